@@ -0,0 +1,100 @@
+// Command avk-loadgen runs synthetic load against an AegisVaultKeeper server and reports latency percentiles.
+// It exits with status 1 when the run exceeds the error rate or latency budget, so it can gate releases.
+//
+// Usage:
+//
+//	avk-loadgen -url https://localhost:56789 -concurrency 50 -duration 30m -insecure
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/loadgen"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+// run executes the load test according to the command-line arguments and returns the process exit code.
+func run(args []string) int {
+	fs := flag.NewFlagSet("avk-loadgen", flag.ContinueOnError)
+	baseURL := fs.String("url", "https://localhost:56789", "base URL of the server under test")
+	concurrency := fs.Int("concurrency", 10, "number of virtual users sending requests in parallel")
+	duration := fs.Duration("duration", time.Minute, "duration of the run")
+	mix := fs.String("mix", "", "operation weights as operation=weight pairs, such as login=1,sync.pull=4 "+
+		"(default: a read-heavy mix of all operations)")
+	fileSize := fs.Int("file-size", 64<<10, "size in bytes of uploaded files")
+	think := fs.Duration("think", 0, "pause of a virtual user between two requests")
+	timeout := fs.Duration("timeout", 30*time.Second, "timeout of a single request")
+	insecure := fs.Bool("insecure", false, "skip verification of the server certificate")
+	interval := fs.Duration("report-interval", 10*time.Second, "period of progress reports (0 disables them)")
+	prefix := fs.String("login-prefix", "loadgen", "prefix of the logins of the registered virtual users")
+	maxErrorRate := fs.Float64("max-error-rate", 0.01, "largest acceptable fraction of failed requests "+
+		"(negative disables the check)")
+	maxP99 := fs.Duration("max-p99", 0, "largest acceptable p99 latency of any operation (0 disables the check)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	cfg := loadgen.Config{
+		BaseURL:        *baseURL,
+		LoginPrefix:    *prefix,
+		Concurrency:    *concurrency,
+		Duration:       *duration,
+		ThinkTime:      *think,
+		FileSize:       *fileSize,
+		ReportInterval: *interval,
+		HTTPClient:     newHTTPClient(*timeout, *insecure),
+		Progress: func(r *loadgen.Report) {
+			fmt.Fprintf(os.Stderr, "%v: %d requests, %.1f req/s, %.2f%% errors\n",
+				r.Elapsed.Round(time.Second), r.Requests(), r.Throughput(), r.ErrorRate()*100)
+		},
+	}
+	if *mix != "" {
+		m, err := loadgen.ParseMix(*mix)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid -mix: %v\n", err)
+			return 2
+		}
+		cfg.Mix = m
+	}
+
+	// An interrupt ends the run early; the results so far are still reported.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	report, err := loadgen.Run(ctx, cfg)
+	if report != nil {
+		if werr := report.Write(os.Stdout); werr != nil {
+			fmt.Fprintf(os.Stderr, "Failed to print report: %v\n", werr)
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Load test failed: %v\n", err)
+		return 1
+	}
+	if err := report.Check(loadgen.Budget{MaxErrorRate: *maxErrorRate, MaxP99: *maxP99}); err != nil {
+		fmt.Fprintf(os.Stderr, "Budget exceeded:\n%v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// newHTTPClient creates the client sending the requests of all virtual users.
+func newHTTPClient(timeout time.Duration, insecure bool) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConnsPerHost = 1024
+	if insecure {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true} //nolint:gosec // Opt-in for self-signed certs
+	}
+	return &http.Client{Transport: transport, Timeout: timeout}
+}
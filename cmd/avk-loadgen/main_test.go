@@ -0,0 +1,69 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/auth/register":
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id":"4c1f5fb6-3e0f-4d65-9b4e-4a1c0e0c7e3a"}`))
+		case "/api/auth/login":
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"access_token": "token", "token_type": "Bearer", "expires_at": time.Now().Add(time.Hour),
+			})
+		case "/api/items/notes":
+			_, _ = w.Write([]byte(`{"notes":[]}`))
+		default:
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(`{"error":"unavailable"}`))
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	tests := []struct {
+		name     string
+		args     []string
+		wantCode int
+	}{
+		{
+			name:     "within budget",
+			args:     []string{"-url", srv.URL, "-concurrency", "2", "-duration", "100ms", "-mix", "note.list=1"},
+			wantCode: 0,
+		},
+		{
+			name:     "error budget exceeded",
+			args:     []string{"-url", srv.URL, "-concurrency", "2", "-duration", "100ms", "-mix", "sync.pull=1"},
+			wantCode: 1,
+		},
+		{
+			name:     "invalid mix",
+			args:     []string{"-url", srv.URL, "-mix", "delete=1"},
+			wantCode: 2,
+		},
+		{
+			name:     "unknown flag",
+			args:     []string{"-users", "10"},
+			wantCode: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.wantCode, run(tt.args))
+		})
+	}
+}
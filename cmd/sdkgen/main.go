@@ -0,0 +1,49 @@
+// Command sdkgen generates the TypeScript client of the AegisVaultKeeper API from its OpenAPI specification.
+//
+// Usage:
+//
+//	sdkgen -spec docs/swagger.json -out sdk/typescript/client.ts
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/sdkgen"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+// run generates the client according to the command-line arguments and returns the process exit code.
+func run(args []string) int {
+	fs := flag.NewFlagSet("sdkgen", flag.ContinueOnError)
+	specPath := fs.String("spec", "docs/swagger.json", "path to the Swagger 2.0 JSON specification")
+	outPath := fs.String("out", "sdk/typescript/client.ts", "path of the generated TypeScript client")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	spec, err := os.ReadFile(*specPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to read spec: %v\n", err)
+		return 1
+	}
+	client, err := sdkgen.TypeScript(spec)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to generate client: %v\n", err)
+		return 1
+	}
+	if err := os.MkdirAll(filepath.Dir(*outPath), 0o750); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to create output directory: %v\n", err)
+		return 1
+	}
+	if err := os.WriteFile(*outPath, client, 0o600); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to write client: %v\n", err)
+		return 1
+	}
+	return 0
+}
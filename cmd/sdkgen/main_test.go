@@ -0,0 +1,55 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	spec := filepath.Join(dir, "swagger.json")
+	require.NoError(t, os.WriteFile(spec, []byte(`{"info":{"version":"1.2.3"},"paths":{}}`), 0o600))
+
+	tests := []struct {
+		name     string
+		args     []string
+		wantCode int
+		wantFile bool
+	}{
+		{
+			name:     "success",
+			args:     []string{"-spec", spec, "-out", filepath.Join(dir, "out", "client.ts")},
+			wantCode: 0,
+			wantFile: true,
+		},
+		{
+			name:     "missing spec",
+			args:     []string{"-spec", filepath.Join(dir, "missing.json"), "-out", filepath.Join(dir, "none.ts")},
+			wantCode: 1,
+		},
+		{
+			name:     "unknown flag",
+			args:     []string{"-lang", "python"},
+			wantCode: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.wantCode, run(tt.args))
+			if tt.wantFile {
+				out, err := os.ReadFile(tt.args[3])
+				require.NoError(t, err)
+				assert.Contains(t, string(out), `export const SPEC_VERSION = "1.2.3";`)
+			}
+		})
+	}
+}
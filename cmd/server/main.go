@@ -1,10 +1,30 @@
 package main
 
 import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	// tzdata resolves the time zones of access policies in images without a zone database.
+	_ "time/tzdata"
+
 	_ "github.com/gdyunin/aegis-vault-keeper/docs"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/fxshow"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/setup"
 )
 
+// usage describes the supported maintenance commands.
+const usage = `Usage:
+  aegis_vault_keeper                  start the server
+  aegis_vault_keeper init [flags]     generate the configuration and create the database schema (-h lists flags)
+  aegis_vault_keeper backup           create an encrypted snapshot of the database and files
+  aegis_vault_keeper restore <name>   restore the database and files from a snapshot (server stopped)
+  aegis_vault_keeper seed <file>      load users and vault items from a YAML or JSON fixture document
+  aegis_vault_keeper mock             start the server on in-memory fixture data, without a database
+  aegis_vault_keeper healthcheck      exit 0 when the server answers its health endpoint (container health checks)`
+
 // main provides the entry point for the AegisVaultKeeper server application.
 //
 // @title                       AegisVaultKeeper API
@@ -28,6 +48,16 @@ import (
 // @name                        Authorization
 // @description                 Bearer token authentication. Use 'Bearer {token}' format.
 //
+// @securityDefinitions.apikey  AdminToken
+// @in                          header
+// @name                        X-Admin-Token
+// @description                 Operator token authorizing the admin API (ADMIN_API_TOKEN).
+//
+// @securityDefinitions.apikey  SCIMToken
+// @in                          header
+// @name                        Authorization
+// @description                 Identity provider token authorizing SCIM provisioning. Use 'Bearer {SCIM_API_TOKEN}'.
+//
 // @tag.name                    Auth
 // @tag.description             Authentication operations - user registration and login
 //
@@ -48,8 +78,128 @@ import (
 //
 // @tag.name                    System
 // @tag.description             System operations - health check and application information
+//
+// @tag.name                    Features
+// @tag.description             Feature flag operations - discover experimental features enabled for the user
+//
+// @tag.name                    Admin
+// @tag.description             Administrative operations - access rules, maintenance mode and feature flags
+//
+// @tag.name                    SCIM
+// @tag.description             SCIM 2.0 provisioning - users and groups synchronized from an enterprise directory
 // .
 func main() {
+	if len(os.Args) > 1 {
+		os.Exit(runCommand(os.Args[1:]))
+	}
+
 	app := fxshow.BuildApp()
 	app.Run()
 }
+
+// runCommand executes a maintenance command and returns the process exit code.
+func runCommand(args []string) int {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	switch {
+	case args[0] == "backup" && len(args) == 1:
+		res, err := fxshow.RunBackup(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Backup failed: %v\n", err)
+			return 1
+		}
+		fmt.Printf("Snapshot %s created: %d tables, %d files\n",
+			res.Name, len(res.Manifest.Tables), len(res.Manifest.Files))
+		return 0
+	case args[0] == "restore" && len(args) == 2:
+		m, err := fxshow.RunRestore(ctx, args[1])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Restore failed: %v\n", err)
+			return 1
+		}
+		fmt.Printf("Snapshot %s taken at %s restored: %d tables, %d files\n",
+			args[1], m.CreatedAt.Format("2006-01-02 15:04:05 MST"), len(m.Tables), len(m.Files))
+		return 0
+	case args[0] == "seed" && len(args) == 2:
+		res, err := fxshow.RunSeed(ctx, args[1])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Seed failed: %v\n", err)
+			return 1
+		}
+		fmt.Printf("Fixtures %s loaded: %d users, %d credentials, %d bank cards, %d notes, %d folders, %d files\n",
+			args[1], res.Users, res.Credentials, res.BankCards, res.Notes, res.Folders, res.Files)
+		return 0
+	case args[0] == "init":
+		return runInit(ctx, args[1:])
+	case args[0] == "healthcheck" && len(args) == 1:
+		if err := fxshow.RunHealthcheck(ctx); err != nil {
+			fmt.Fprintf(os.Stderr, "Health check failed: %v\n", err)
+			return 1
+		}
+		return 0
+	case args[0] == "mock" && len(args) == 1:
+		fxshow.BuildMockApp().Run()
+		return 0
+	default:
+		fmt.Fprintln(os.Stderr, usage)
+		return 2
+	}
+}
+
+// runInit prepares a new installation according to the init command arguments and returns the process exit code.
+// Settings missing from the flags are asked for interactively unless -non-interactive is given.
+func runInit(ctx context.Context, args []string) int {
+	defaults := setup.DefaultSettings()
+	fs := flag.NewFlagSet("init", flag.ContinueOnError)
+	envFile := fs.String("env-file", ".env", "environment file to write")
+	configDir := fs.String("config-dir", "/app/config", "directory of server.yml the configuration is checked with")
+	migrations := fs.String("migrations", "", "directory of the database migrations (embedded ones when empty)")
+	force := fs.Bool("force", false, "replace an existing environment file, generating new secrets")
+	skipSchema := fs.Bool("skip-schema", false, "do not create the database schema")
+	nonInteractive := fs.Bool("non-interactive", false, "use the flag values without asking")
+	s := defaults
+	fs.StringVar(&s.DBHost, "db-host", defaults.DBHost, "PostgreSQL host")
+	fs.IntVar(&s.DBPort, "db-port", defaults.DBPort, "PostgreSQL port")
+	fs.StringVar(&s.DBUser, "db-user", defaults.DBUser, "PostgreSQL user")
+	fs.StringVar(&s.DBPassword, "db-password", "", "PostgreSQL password (falls back to $POSTGRES_PASSWORD)")
+	fs.StringVar(&s.DBName, "db-name", defaults.DBName, "PostgreSQL database")
+	fs.StringVar(&s.DBSSLMode, "db-sslmode", defaults.DBSSLMode, "PostgreSQL SSL mode")
+	fs.IntVar(&s.AppPort, "port", defaults.AppPort, "port the server listens on")
+	fs.StringVar(&s.FileStoragePath, "storage", defaults.FileStoragePath, "directory of uploaded files")
+	fs.StringVar(&s.TLSMode, "tls", defaults.TLSMode, "TLS mode: files, acme or none")
+	fs.StringVar(&s.TLSCertFile, "tls-cert", defaults.TLSCertFile, "TLS certificate file of the files mode")
+	fs.StringVar(&s.TLSKeyFile, "tls-key", defaults.TLSKeyFile, "TLS private key file of the files mode")
+	fs.StringVar(&s.ACMEDomains, "acme-domains", "", "comma-separated host names of the acme mode")
+	fs.StringVar(&s.ACMEEmail, "acme-email", "", "ACME account contact address")
+	fs.BoolVar(&s.AdminAPI, "admin-api", defaults.AdminAPI, "generate an admin API token enabling the admin API")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if s.DBPassword == "" {
+		s.DBPassword = os.Getenv("POSTGRES_PASSWORD")
+	}
+
+	opts := setup.Options{
+		Settings:   s,
+		EnvFile:    *envFile,
+		ConfigDir:  *configDir,
+		Force:      *force,
+		SkipSchema: *skipSchema,
+	}
+	if *migrations != "" {
+		opts.Migrations = os.DirFS(*migrations)
+	}
+	if !*nonInteractive {
+		opts.In, opts.Out = os.Stdin, os.Stdout
+	}
+	res, err := setup.Run(ctx, opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Init failed: %v\n", err)
+		return 1
+	}
+	if err := res.Write(os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to print summary: %v\n", err)
+	}
+	return 0
+}
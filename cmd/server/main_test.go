@@ -219,3 +219,26 @@ func TestApplicationConfiguration(t *testing.T) {
 		})
 	}
 }
+
+func TestRunCommand_Usage(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{name: "unknown command", args: []string{"migrate"}},
+		{name: "backup with extra argument", args: []string{"backup", "now"}},
+		{name: "restore without snapshot name", args: []string{"restore"}},
+		{name: "seed without fixture document", args: []string{"seed"}},
+		{name: "mock with extra argument", args: []string{"mock", "now"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, 2, runCommand(tt.args))
+		})
+	}
+}
@@ -0,0 +1,12 @@
+// Package config embeds the default server configuration into the binary.
+//
+// The server starts from these defaults and merges the server.yml of its configuration directory on top when
+// one exists, so a container image needs no configuration files besides the environment.
+package config
+
+import _ "embed"
+
+// ServerDefaults holds the default server.yml.
+//
+//go:embed server.yml
+var ServerDefaults []byte
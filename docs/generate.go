@@ -0,0 +1,9 @@
+package docs
+
+// The API contract is generated in two steps: swag renders the OpenAPI specification from the handler
+// annotations and delivery DTOs, then sdkgen derives the TypeScript client from it. Run `go generate ./docs`
+// (or `make sdk`) after changing the API, so the specification, the client and the hash reported by
+// GET /api/sdk stay in lockstep.
+
+//go:generate swag init --dir ../cmd/server,../internal/server/delivery --output .
+//go:generate go run ../cmd/sdkgen -spec swagger.json -out ../sdk/typescript/client.ts
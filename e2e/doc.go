@@ -0,0 +1,14 @@
+//go:build e2e
+
+// Package e2e holds the end-to-end tests of the AegisVaultKeeper server.
+//
+// The tests boot the complete application against a PostgreSQL database started with testcontainers,
+// drive the real HTTP API through scenario scripts and compare the exchanges with golden transcripts,
+// so regressions in routing, authentication and encryption wiring are caught automatically. They are
+// built with the e2e tag and need Docker:
+//
+//	go test -tags e2e ./e2e/
+//
+// Setting E2E_POSTGRES_DSN runs the tests against an existing empty database instead of a container.
+// The -update flag rewrites the golden transcripts from the current responses.
+package e2e
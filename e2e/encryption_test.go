@@ -0,0 +1,79 @@
+//go:build e2e
+
+package e2e
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecretsEncryptedAtRest(t *testing.T) {
+	// marker is a secret that must not appear in any stored row.
+	const marker = "e2e-plaintext-marker-3f9c"
+
+	r := &runner{vars: make(map[string]string)}
+	r.run(t, &scenario{Steps: []step{
+		{
+			Name:    "register",
+			Request: "POST /auth/register",
+			Body:    map[string]any{"login": "encryption-check", "password": "encryption-check-password"},
+		},
+		{
+			Name:    "login",
+			Request: "POST /auth/login",
+			Body:    map[string]any{"login": "encryption-check", "password": "encryption-check-password"},
+			Capture: map[string]string{"token": "access_token"},
+		},
+		{
+			Name:    "store credential",
+			Request: "POST /items/credentials",
+			Auth:    "token",
+			Body:    map[string]any{"login": marker, "password": marker, "description": marker},
+		},
+		{
+			Name:    "store note",
+			Request: "POST /items/notes",
+			Auth:    "token",
+			Body:    map[string]any{"note": marker, "description": marker},
+		},
+		{
+			Name:    "store bank card",
+			Request: "POST /items/bankcards",
+			Auth:    "token",
+			Body: map[string]any{
+				"card_number": "4111111111111111", "card_holder": "E2E", "expiry_month": "12",
+				"expiry_year": "2031", "cvv": "123", "description": marker,
+			},
+		},
+	}})
+	require.Equal(t, 4, strings.Count(r.transcript.String(), "--- 201\n"),
+		"the user and the items should be created:\n%s", r.transcript.String())
+
+	conn, err := pgx.Connect(t.Context(), databaseDSN)
+	require.NoError(t, err)
+	defer func() { _ = conn.Close(t.Context()) }()
+
+	for _, table := range []string{"credentials", "notes", "bank_cards"} {
+		rows, err := conn.Query(t.Context(), "SELECT * FROM aegis_vault_keeper."+table)
+		require.NoError(t, err)
+		// n counts the stored rows.
+		var n int
+		for rows.Next() {
+			n++
+			for i, v := range rows.RawValues() {
+				column := rows.FieldDescriptions()[i].Name
+				assert.False(t, bytes.Contains(v, []byte(marker)), "%s.%s stores the secret in plaintext", table, column)
+				assert.False(t, strings.Contains(string(v), "4111111111111111"),
+					"%s.%s stores the card number in plaintext", table, column)
+			}
+		}
+		require.NoError(t, rows.Err())
+		assert.Positive(t, n, "%s should hold the stored item", table)
+		rows.Close()
+	}
+}
@@ -0,0 +1,203 @@
+//go:build e2e
+
+package e2e
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/fxshow"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/setup"
+	"github.com/gdyunin/aegis-vault-keeper/migrations"
+	"github.com/jackc/pgx/v5"
+	"github.com/spf13/viper"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/postgres"
+)
+
+const (
+	// postgresImage is the PostgreSQL image the tests run against; it matches docker-compose.yml.
+	postgresImage = "postgres:17.2"
+	// configDir holds the server configuration file the application loads.
+	configDir = "../config"
+	// readyTimeout bounds the wait for the server to answer health checks.
+	readyTimeout = 30 * time.Second
+)
+
+// baseURL is the URL of the API of the server under test.
+var baseURL string
+
+// databaseDSN is the connection string of the database of the server under test.
+var databaseDSN string
+
+func TestMain(m *testing.M) {
+	os.Exit(run(m))
+}
+
+// run prepares the database, starts the server, runs the tests and tears everything down.
+func run(m *testing.M) int {
+	ctx := context.Background()
+
+	dsn, stopDatabase, err := startDatabase(ctx)
+	if err != nil {
+		log.Printf("failed to start database: %v", err)
+		return 1
+	}
+	defer stopDatabase()
+	databaseDSN = dsn
+
+	if err := applyMigrations(ctx, dsn); err != nil {
+		log.Printf("failed to apply migrations: %v", err)
+		return 1
+	}
+
+	stopServer, err := startServer(ctx, dsn)
+	if err != nil {
+		log.Printf("failed to start server: %v", err)
+		return 1
+	}
+	defer stopServer()
+
+	return m.Run()
+}
+
+// startDatabase returns the connection string of an empty database and a function releasing it.
+// It uses the database named by E2E_POSTGRES_DSN when set and starts a container otherwise.
+func startDatabase(ctx context.Context) (string, func(), error) {
+	if dsn := os.Getenv("E2E_POSTGRES_DSN"); dsn != "" {
+		return dsn, func() {}, nil
+	}
+
+	ctr, err := postgres.Run(ctx, postgresImage,
+		postgres.WithDatabase("aegis_vault_keeper"),
+		postgres.WithUsername("e2e"),
+		postgres.WithPassword("e2e-password"),
+		postgres.BasicWaitStrategies(),
+	)
+	stop := func() {
+		if err := testcontainers.TerminateContainer(ctr); err != nil {
+			log.Printf("failed to terminate database container: %v", err)
+		}
+	}
+	if err != nil {
+		stop()
+		return "", nil, fmt.Errorf("failed to run postgres container: %w", err)
+	}
+	dsn, err := ctr.ConnectionString(ctx, "sslmode=disable")
+	if err != nil {
+		stop()
+		return "", nil, fmt.Errorf("failed to get connection string: %w", err)
+	}
+	return dsn, stop, nil
+}
+
+// applyMigrations applies the embedded up migrations in order through the schema migration of the init command,
+// which records the version like the migrate service of docker-compose.yml.
+func applyMigrations(ctx context.Context, dsn string) error {
+	if _, err := setup.MigrateSchema(ctx, dsn, migrations.FS); err != nil {
+		return fmt.Errorf("failed to migrate schema: %w", err)
+	}
+	return nil
+}
+
+// startServer starts the complete application on a free port against the database and waits until it
+// answers health checks. It returns a function stopping the application.
+func startServer(ctx context.Context, dsn string) (func(), error) {
+	db, err := pgx.ParseConfig(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse database connection string: %w", err)
+	}
+	port, err := freePort()
+	if err != nil {
+		return nil, err
+	}
+	storage, err := os.MkdirTemp("", "aegis-vault-keeper-e2e-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create file storage directory: %w", err)
+	}
+
+	for k, v := range map[string]string{
+		"POSTGRES_HOST":          db.Host,
+		"POSTGRES_PORT":          strconv.Itoa(int(db.Port)),
+		"POSTGRES_USER":          db.User,
+		"POSTGRES_PASSWORD":      db.Password,
+		"POSTGRES_DB_NAME":       db.Database,
+		"POSTGRES_SSL_MODE":      "disable",
+		"POSTGRES_RLS_ENABLED":   "true",
+		"APPLICATION_PORT":       strconv.Itoa(port),
+		"MASTER_KEY":             "e2e-master-key-not-for-production",
+		"FILE_STORAGE_BASE_PATH": storage,
+		"BACKUP_LOCAL_PATH":      filepath.Join(storage, "backups"),
+		"TLS_ENABLED":            "false",
+		"LOGGER_LEVEL":           "error",
+		"GIN_MODE":               "release",
+	} {
+		if err := os.Setenv(k, v); err != nil {
+			return nil, fmt.Errorf("failed to set %s: %w", k, err)
+		}
+	}
+	// The application reads server.yml from /app/config; the repository copy is found first.
+	viper.AddConfigPath(configDir)
+
+	app := fxshow.BuildApp()
+	if err := app.Start(ctx); err != nil {
+		_ = os.RemoveAll(storage)
+		return nil, fmt.Errorf("failed to start application: %w", err)
+	}
+	stop := func() {
+		if err := app.Stop(context.WithoutCancel(ctx)); err != nil {
+			log.Printf("failed to stop application: %v", err)
+		}
+		_ = os.RemoveAll(storage)
+	}
+
+	baseURL = fmt.Sprintf("http://127.0.0.1:%d/api", port)
+	if err := waitReady(ctx); err != nil {
+		stop()
+		return nil, err
+	}
+	return stop, nil
+}
+
+// freePort returns a TCP port nothing listens on.
+func freePort() (int, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, fmt.Errorf("failed to find a free port: %w", err)
+	}
+	defer func() { _ = l.Close() }()
+	return l.Addr().(*net.TCPAddr).Port, nil
+}
+
+// waitReady polls the health endpoint until the server answers or readyTimeout passes.
+func waitReady(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, readyTimeout)
+	defer cancel()
+
+	for {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+"/health", http.NoBody)
+		if err != nil {
+			return fmt.Errorf("failed to create health request: %w", err)
+		}
+		if resp, err := http.DefaultClient.Do(req); err == nil {
+			_ = resp.Body.Close()
+			if resp.StatusCode == http.StatusOK {
+				return nil
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return errors.New("server did not become ready")
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+}
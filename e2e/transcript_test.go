@@ -0,0 +1,192 @@
+//go:build e2e
+
+package e2e
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+// update rewrites the golden transcripts from the current responses.
+var update = flag.Bool("update", false, "rewrite the golden transcripts")
+
+// Patterns of the values that differ between runs; transcripts show placeholders instead.
+var (
+	// uuidPattern matches identifiers.
+	uuidPattern = regexp.MustCompile(`[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}`)
+	// timePattern matches RFC 3339 timestamps.
+	timePattern = regexp.MustCompile(`\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:\d{2})`)
+	// jwtPattern matches JSON web tokens.
+	jwtPattern = regexp.MustCompile(`eyJ[A-Za-z0-9_-]*\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+`)
+	// varPattern matches references to captured variables.
+	varPattern = regexp.MustCompile(`\{\{(\w+)\}\}`)
+)
+
+// scenario is a script of API requests executed in order.
+type scenario struct {
+	// Steps lists the requests.
+	Steps []step `yaml:"steps"`
+}
+
+// step is a single API request of a scenario.
+type step struct {
+	// Body contains the JSON request body; nil sends none.
+	Body any `yaml:"body"`
+	// Capture maps variable names to the top-level response fields whose values they receive.
+	Capture map[string]string `yaml:"capture"`
+	// Name describes the step in the transcript.
+	Name string `yaml:"name"`
+	// Request contains the method and the path relative to the API base, such as "GET /items/notes".
+	Request string `yaml:"request"`
+	// Auth names the variable holding the bearer token to send; empty sends none.
+	Auth string `yaml:"auth"`
+}
+
+// runner executes scenarios and records their transcripts.
+type runner struct {
+	// vars holds the captured variables.
+	vars map[string]string
+	// transcript accumulates the exchanges.
+	transcript bytes.Buffer
+}
+
+// run executes the steps of a scenario, recording every exchange.
+func (r *runner) run(t *testing.T, s *scenario) {
+	t.Helper()
+
+	for _, st := range s.Steps {
+		method, path, ok := strings.Cut(r.expand(st.Request), " ")
+		require.True(t, ok, "step %q: request must be METHOD /path", st.Name)
+
+		// body holds the encoded request body.
+		var body []byte
+		if st.Body != nil {
+			encoded, err := json.Marshal(st.Body)
+			require.NoError(t, err, "step %q", st.Name)
+			body = []byte(r.expand(string(encoded)))
+		}
+
+		req, err := http.NewRequestWithContext(t.Context(), method, baseURL+path, bytes.NewReader(body))
+		require.NoError(t, err, "step %q", st.Name)
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		if st.Auth != "" {
+			req.Header.Set("Authorization", "Bearer "+r.vars[st.Auth])
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err, "step %q", st.Name)
+		respBody, err := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		require.NoError(t, err, "step %q", st.Name)
+
+		fmt.Fprintf(&r.transcript, "=== %s\n%s %s\n", st.Name, method, path)
+		if body != nil {
+			r.transcript.WriteString(render(body))
+		}
+		fmt.Fprintf(&r.transcript, "--- %d\n%s\n", resp.StatusCode, render(respBody))
+
+		r.capture(t, st, respBody)
+	}
+}
+
+// capture stores the response fields named by the step in the variables.
+func (r *runner) capture(t *testing.T, st step, body []byte) {
+	t.Helper()
+
+	if len(st.Capture) == 0 {
+		return
+	}
+	// fields holds the decoded response object.
+	var fields map[string]any
+	require.NoError(t, json.Unmarshal(body, &fields), "step %q: response is not a JSON object", st.Name)
+	for name, field := range st.Capture {
+		v, ok := fields[field]
+		require.True(t, ok, "step %q: response has no field %q", st.Name, field)
+		r.vars[name] = fmt.Sprint(v)
+	}
+}
+
+// expand replaces references to captured variables with their values.
+func (r *runner) expand(s string) string {
+	return varPattern.ReplaceAllStringFunc(s, func(ref string) string {
+		return r.vars[varPattern.FindStringSubmatch(ref)[1]]
+	})
+}
+
+// render formats a body for the transcript: JSON is indented with sorted keys, other content is kept.
+func render(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+	// v holds the decoded JSON body.
+	var v any
+	if err := json.Unmarshal(body, &v); err != nil {
+		return strings.TrimRight(string(body), "\n") + "\n"
+	}
+	indented, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return string(body) + "\n"
+	}
+	return string(indented) + "\n"
+}
+
+// normalize replaces the values that differ between runs with placeholders. Identifiers are numbered in
+// order of appearance, so a transcript still shows which responses refer to the same entity.
+func normalize(transcript string) string {
+	ids := make(map[string]string)
+	transcript = uuidPattern.ReplaceAllStringFunc(transcript, func(id string) string {
+		if _, ok := ids[id]; !ok {
+			ids[id] = fmt.Sprintf("<id-%d>", len(ids)+1)
+		}
+		return ids[id]
+	})
+	transcript = jwtPattern.ReplaceAllString(transcript, "<jwt>")
+	return timePattern.ReplaceAllString(transcript, "<time>")
+}
+
+func TestScenarios(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("testdata", "scenarios", "*.yml"))
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+
+	for _, file := range files {
+		name := strings.TrimSuffix(filepath.Base(file), ".yml")
+		t.Run(name, func(t *testing.T) {
+			data, err := os.ReadFile(file)
+			require.NoError(t, err)
+			// s holds the decoded scenario.
+			var s scenario
+			dec := yaml.NewDecoder(bytes.NewReader(data))
+			dec.KnownFields(true)
+			require.NoError(t, dec.Decode(&s))
+
+			r := &runner{vars: make(map[string]string)}
+			r.run(t, &s)
+			got := normalize(r.transcript.String())
+
+			golden := filepath.Join("testdata", "golden", name+".golden")
+			if *update {
+				require.NoError(t, os.WriteFile(golden, []byte(got), 0o600))
+				return
+			}
+			want, err := os.ReadFile(golden)
+			require.NoError(t, err, "missing golden transcript; run with -update to create it")
+			assert.Equal(t, string(want), got, "transcript differs from %s", golden)
+		})
+	}
+}
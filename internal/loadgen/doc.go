@@ -0,0 +1,7 @@
+// Package loadgen generates synthetic load against an AegisVaultKeeper server.
+//
+// Every worker acts as one virtual user: it registers a fresh account, then runs a weighted mix of logins,
+// item reads and writes, vault synchronizations and file transfers until the run ends. Latencies are
+// recorded per operation in bounded histograms, so soak tests of any length report percentiles in constant
+// memory, and runs can be checked against error rate and latency budgets before releases.
+package loadgen
@@ -0,0 +1,72 @@
+package loadgen
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	// bucketGrowth is the ratio between the upper bounds of neighbouring buckets; reported percentiles
+	// overestimate the exact value by at most 2%.
+	bucketGrowth = 1.02
+	// bucketCount is the number of buckets, covering latencies up to about 42 hours.
+	bucketCount = 1300
+)
+
+// logBucketGrowth caches the logarithm of bucketGrowth.
+var logBucketGrowth = math.Log(bucketGrowth)
+
+// histogram records latencies in logarithmic buckets of microseconds.
+type histogram struct {
+	// counts holds the number of latencies per bucket.
+	counts []int64
+	// total is the number of recorded latencies.
+	total int64
+	// max is the largest recorded latency.
+	max time.Duration
+}
+
+// newHistogram creates an empty histogram.
+func newHistogram() *histogram {
+	return &histogram{counts: make([]int64, bucketCount)}
+}
+
+// record adds a latency to the histogram.
+func (h *histogram) record(d time.Duration) {
+	h.counts[bucketOf(d)]++
+	h.total++
+	h.max = max(h.max, d)
+}
+
+// percentile returns the upper bound of the bucket holding the latency below which the fraction p of the
+// recorded latencies falls, capped at the largest latency; it is zero for an empty histogram.
+func (h *histogram) percentile(p float64) time.Duration {
+	if h.total == 0 {
+		return 0
+	}
+	rank := int64(math.Ceil(p * float64(h.total)))
+	rank = min(max(rank, 1), h.total)
+	// seen counts the latencies in the buckets visited so far.
+	var seen int64
+	for i, n := range h.counts {
+		seen += n
+		if seen >= rank {
+			return min(bucketBound(i), h.max)
+		}
+	}
+	return h.max
+}
+
+// bucketOf returns the index of the bucket of a latency.
+func bucketOf(d time.Duration) int {
+	us := d.Microseconds()
+	if us < 1 {
+		return 0
+	}
+	return min(int(math.Ceil(math.Log(float64(us))/logBucketGrowth)), bucketCount-1)
+}
+
+// bucketBound returns the upper bound of the bucket with the index.
+func bucketBound(i int) time.Duration {
+	return time.Duration(math.Ceil(math.Pow(bucketGrowth, float64(i)))) * time.Microsecond
+}
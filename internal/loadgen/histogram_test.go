@@ -0,0 +1,51 @@
+package loadgen
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHistogram_Percentile(t *testing.T) {
+	t.Parallel()
+
+	h := newHistogram()
+	assert.Zero(t, h.percentile(0.5), "an empty histogram should report zero")
+
+	for i := 1; i <= 100; i++ {
+		h.record(time.Duration(i) * time.Millisecond)
+	}
+
+	tests := []struct {
+		name string
+		p    float64
+		want time.Duration
+	}{
+		{name: "median", p: 0.5, want: 50 * time.Millisecond},
+		{name: "p90", p: 0.9, want: 90 * time.Millisecond},
+		{name: "p99", p: 0.99, want: 99 * time.Millisecond},
+		{name: "maximum", p: 1, want: 100 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := h.percentile(tt.p)
+			assert.GreaterOrEqual(t, got, tt.want)
+			assert.LessOrEqual(t, float64(got), float64(tt.want)*bucketGrowth, "error should stay within a bucket")
+		})
+	}
+}
+
+func TestBucketOf(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, 0, bucketOf(0))
+	assert.Equal(t, 0, bucketOf(time.Microsecond))
+	assert.Equal(t, bucketCount-1, bucketOf(1000*time.Hour), "huge latencies should land in the last bucket")
+	for _, d := range []time.Duration{3 * time.Microsecond, 250 * time.Millisecond, 7 * time.Second} {
+		assert.GreaterOrEqual(t, bucketBound(bucketOf(d)), d, "bucket of %v should bound it", d)
+	}
+}
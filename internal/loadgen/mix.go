@@ -0,0 +1,112 @@
+package loadgen
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Operation names the request a virtual user sends.
+type Operation string
+
+// Operations of the workload.
+const (
+	// OpRegister registers the virtual user; it runs once per worker and is not part of the mix.
+	OpRegister Operation = "register"
+	// OpLogin logs the virtual user in again.
+	OpLogin Operation = "login"
+	// OpCredentialCreate stores a new credential.
+	OpCredentialCreate Operation = "credential.create"
+	// OpCredentialRead reads a stored credential.
+	OpCredentialRead Operation = "credential.read"
+	// OpCredentialUpdate replaces a stored credential.
+	OpCredentialUpdate Operation = "credential.update"
+	// OpNoteCreate stores a new note.
+	OpNoteCreate Operation = "note.create"
+	// OpNoteList lists the notes.
+	OpNoteList Operation = "note.list"
+	// OpBankCardCreate stores a new bank card.
+	OpBankCardCreate Operation = "bankcard.create"
+	// OpSyncPull pulls the whole vault.
+	OpSyncPull Operation = "sync.pull"
+	// OpFileUpload uploads a new file.
+	OpFileUpload Operation = "file.upload"
+	// OpFileDownload downloads a stored file.
+	OpFileDownload Operation = "file.download"
+)
+
+// Mix maps the operations of the workload to their relative weights.
+type Mix map[Operation]int
+
+// DefaultMix is a read-heavy mix resembling the traffic of interactive clients.
+var DefaultMix = Mix{
+	OpLogin:            5,
+	OpCredentialCreate: 10,
+	OpCredentialRead:   30,
+	OpCredentialUpdate: 10,
+	OpNoteCreate:       5,
+	OpNoteList:         10,
+	OpBankCardCreate:   5,
+	OpSyncPull:         15,
+	OpFileUpload:       5,
+	OpFileDownload:     5,
+}
+
+// mixOperations lists the operations a mix may weight.
+var mixOperations = []Operation{
+	OpLogin, OpCredentialCreate, OpCredentialRead, OpCredentialUpdate, OpNoteCreate, OpNoteList,
+	OpBankCardCreate, OpSyncPull, OpFileUpload, OpFileDownload,
+}
+
+// ParseMix parses a mix of comma-separated operation=weight entries, such as "login=1,sync.pull=4".
+// Operations left out are not run.
+func ParseMix(s string) (Mix, error) {
+	m := make(Mix)
+	for _, entry := range strings.Split(s, ",") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid mix entry %q: expected operation=weight", entry)
+		}
+		op := Operation(strings.TrimSpace(name))
+		if !slices.Contains(mixOperations, op) {
+			return nil, fmt.Errorf("invalid mix entry %q: unknown operation %q", entry, op)
+		}
+		weight, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || weight < 0 {
+			return nil, fmt.Errorf("invalid mix entry %q: weight must be a non-negative integer", entry)
+		}
+		m[op] = weight
+	}
+	if m.total() == 0 {
+		return nil, errors.New("mix must give at least one operation a positive weight")
+	}
+	return m, nil
+}
+
+// total returns the sum of the weights.
+func (m Mix) total() int {
+	// sum accumulates the weights.
+	var sum int
+	for _, w := range m {
+		sum += w
+	}
+	return sum
+}
+
+// pick draws an operation with a probability proportional to its weight.
+func (m Mix) pick(rnd *rand.Rand) Operation {
+	n := rnd.IntN(m.total())
+	for _, op := range mixOperations {
+		if n < m[op] {
+			return op
+		}
+		n -= m[op]
+	}
+	return OpLogin
+}
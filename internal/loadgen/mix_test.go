@@ -0,0 +1,61 @@
+package loadgen
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMix(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		want    Mix
+		name    string
+		input   string
+		wantErr string
+	}{
+		{
+			name:  "weights",
+			input: "login=1, sync.pull=4,file.download=0",
+			want:  Mix{OpLogin: 1, OpSyncPull: 4, OpFileDownload: 0},
+		},
+		{name: "missing weight", input: "login", wantErr: "expected operation=weight"},
+		{name: "unknown operation", input: "delete=1", wantErr: `unknown operation "delete"`},
+		{name: "register is not mixable", input: "register=1", wantErr: "unknown operation"},
+		{name: "negative weight", input: "login=-1", wantErr: "non-negative integer"},
+		{name: "all zero", input: "login=0", wantErr: "at least one operation"},
+		{name: "empty", input: "", wantErr: "at least one operation"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseMix(tt.input)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMix_Pick(t *testing.T) {
+	t.Parallel()
+
+	m := Mix{OpLogin: 1, OpSyncPull: 3}
+	rnd := rand.New(rand.NewPCG(1, 2)) //nolint:gosec // Deterministic test sequence
+	counts := make(map[Operation]int)
+	for range 4000 {
+		counts[m.pick(rnd)]++
+	}
+
+	assert.Len(t, counts, 2, "only weighted operations should be picked")
+	assert.InDelta(t, 1000, counts[OpLogin], 150)
+	assert.InDelta(t, 3000, counts[OpSyncPull], 150)
+}
@@ -0,0 +1,195 @@
+package loadgen
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// OperationStats summarizes the requests of one operation.
+type OperationStats struct {
+	// Name identifies the operation.
+	Name Operation
+	// LastError contains the message of the most recent failure; empty without failures.
+	LastError string
+	// Count is the number of completed requests, failures included.
+	Count int64
+	// Errors is the number of failed requests.
+	Errors int64
+	// P50 is the median latency of successful requests.
+	P50 time.Duration
+	// P90 is the 90th percentile latency of successful requests.
+	P90 time.Duration
+	// P99 is the 99th percentile latency of successful requests.
+	P99 time.Duration
+	// Max is the largest latency of successful requests.
+	Max time.Duration
+}
+
+// Report summarizes a load test run.
+type Report struct {
+	// Operations lists the statistics per operation in name order.
+	Operations []OperationStats
+	// Elapsed is the duration of the run so far.
+	Elapsed time.Duration
+}
+
+// Budget sets the limits a run must stay within to pass.
+type Budget struct {
+	// MaxErrorRate is the largest acceptable fraction of failed requests (negative disables the check).
+	MaxErrorRate float64
+	// MaxP99 is the largest acceptable 99th percentile latency of any operation (0 disables the check).
+	MaxP99 time.Duration
+}
+
+// Requests returns the number of completed requests of all operations.
+func (r *Report) Requests() int64 {
+	// n accumulates the request counts.
+	var n int64
+	for _, s := range r.Operations {
+		n += s.Count
+	}
+	return n
+}
+
+// ErrorRate returns the fraction of failed requests of all operations.
+func (r *Report) ErrorRate() float64 {
+	total := r.Requests()
+	if total == 0 {
+		return 0
+	}
+	// n accumulates the error counts.
+	var n int64
+	for _, s := range r.Operations {
+		n += s.Errors
+	}
+	return float64(n) / float64(total)
+}
+
+// Throughput returns the completed requests per second.
+func (r *Report) Throughput() float64 {
+	if r.Elapsed <= 0 {
+		return 0
+	}
+	return float64(r.Requests()) / r.Elapsed.Seconds()
+}
+
+// Check reports the budget limits the run exceeded.
+func (r *Report) Check(b Budget) error {
+	// errs collects the exceeded limits.
+	var errs []error
+	if b.MaxErrorRate >= 0 && r.ErrorRate() > b.MaxErrorRate {
+		errs = append(errs, fmt.Errorf("error rate %.2f%% exceeds %.2f%%", r.ErrorRate()*100, b.MaxErrorRate*100))
+	}
+	if b.MaxP99 > 0 {
+		for _, s := range r.Operations {
+			if s.P99 > b.MaxP99 {
+				errs = append(errs, fmt.Errorf("%s p99 latency %v exceeds %v", s.Name, s.P99, b.MaxP99))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Write prints the report as a table followed by the totals and the last error of every failing operation.
+func (r *Report) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "operation\trequests\terrors\tp50\tp90\tp99\tmax\t")
+	for _, s := range r.Operations {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%v\t%v\t%v\t%v\t\n", s.Name, s.Count, s.Errors,
+			round(s.P50), round(s.P90), round(s.P99), round(s.Max))
+	}
+	if err := tw.Flush(); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+
+	_, err := fmt.Fprintf(w, "\n%d requests in %v, %.1f req/s, %.2f%% errors\n",
+		r.Requests(), round(r.Elapsed), r.Throughput(), r.ErrorRate()*100)
+	for _, s := range r.Operations {
+		if err == nil && s.LastError != "" {
+			_, err = fmt.Fprintf(w, "last %s error: %s\n", s.Name, s.LastError)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	return nil
+}
+
+// round shortens a latency for display.
+func round(d time.Duration) time.Duration {
+	if d >= time.Second {
+		return d.Round(time.Millisecond)
+	}
+	return d.Round(10 * time.Microsecond)
+}
+
+// operationRecord accumulates the results of one operation.
+type operationRecord struct {
+	// latencies holds the latencies of successful requests.
+	latencies *histogram
+	// lastError contains the message of the most recent failure.
+	lastError string
+	// errors is the number of failed requests.
+	errors int64
+}
+
+// recorder collects the results of the requests of all workers. It is safe for concurrent use.
+type recorder struct {
+	// start is the time the run started.
+	start time.Time
+	// ops maps operations to their results.
+	ops map[Operation]*operationRecord
+	// mu guards ops.
+	mu sync.Mutex
+}
+
+// newRecorder creates a recorder of a run starting at start.
+func newRecorder(start time.Time) *recorder {
+	return &recorder{start: start, ops: make(map[Operation]*operationRecord)}
+}
+
+// record adds the result of a request.
+func (r *recorder) record(op Operation, d time.Duration, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rec, ok := r.ops[op]
+	if !ok {
+		rec = &operationRecord{latencies: newHistogram()}
+		r.ops[op] = rec
+	}
+	if err != nil {
+		rec.errors++
+		rec.lastError = err.Error()
+		return
+	}
+	rec.latencies.record(d)
+}
+
+// report summarizes the results recorded until now.
+func (r *recorder) report(now time.Time) *Report {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rep := &Report{Elapsed: now.Sub(r.start)}
+	for op, rec := range r.ops {
+		rep.Operations = append(rep.Operations, OperationStats{
+			Name:      op,
+			Count:     rec.latencies.total + rec.errors,
+			Errors:    rec.errors,
+			P50:       rec.latencies.percentile(0.5),
+			P90:       rec.latencies.percentile(0.9),
+			P99:       rec.latencies.percentile(0.99),
+			Max:       rec.latencies.max,
+			LastError: rec.lastError,
+		})
+	}
+	slices.SortFunc(rep.Operations, func(a, b OperationStats) int { return cmp.Compare(a.Name, b.Name) })
+	return rep
+}
@@ -0,0 +1,99 @@
+package loadgen
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecorder_Report(t *testing.T) {
+	t.Parallel()
+
+	start := time.Now()
+	rec := newRecorder(start)
+	for range 9 {
+		rec.record(OpSyncPull, 10*time.Millisecond, nil)
+	}
+	rec.record(OpSyncPull, 0, errors.New("timeout"))
+	rec.record(OpLogin, 2*time.Millisecond, nil)
+
+	rep := rec.report(start.Add(2 * time.Second))
+
+	require.Len(t, rep.Operations, 2)
+	assert.Equal(t, OpLogin, rep.Operations[0].Name, "operations should be sorted by name")
+	sync := rep.Operations[1]
+	assert.Equal(t, int64(10), sync.Count)
+	assert.Equal(t, int64(1), sync.Errors)
+	assert.Equal(t, "timeout", sync.LastError)
+	assert.Equal(t, 10*time.Millisecond, sync.Max)
+	assert.Equal(t, int64(11), rep.Requests())
+	assert.InDelta(t, 1.0/11, rep.ErrorRate(), 1e-9)
+	assert.InDelta(t, 5.5, rep.Throughput(), 1e-9)
+}
+
+func TestReport_Check(t *testing.T) {
+	t.Parallel()
+
+	rep := &Report{Operations: []OperationStats{
+		{Name: OpLogin, Count: 100, Errors: 5, P99: 80 * time.Millisecond},
+		{Name: OpSyncPull, Count: 100, P99: 300 * time.Millisecond},
+	}}
+
+	tests := []struct {
+		name    string
+		wantErr []string
+		budget  Budget
+	}{
+		{name: "within budget", budget: Budget{MaxErrorRate: 0.05, MaxP99: time.Second}},
+		{name: "checks disabled", budget: Budget{MaxErrorRate: -1}},
+		{name: "error rate", budget: Budget{MaxErrorRate: 0.01}, wantErr: []string{"error rate 2.50% exceeds 1.00%"}},
+		{
+			name:    "latency",
+			budget:  Budget{MaxErrorRate: -1, MaxP99: 100 * time.Millisecond},
+			wantErr: []string{"sync.pull p99 latency 300ms exceeds 100ms"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := rep.Check(tt.budget)
+			if len(tt.wantErr) == 0 {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			for _, want := range tt.wantErr {
+				assert.Contains(t, err.Error(), want)
+			}
+			assert.NotContains(t, err.Error(), "login p99")
+		})
+	}
+}
+
+func TestReport_Write(t *testing.T) {
+	t.Parallel()
+
+	rep := &Report{
+		Elapsed: 10 * time.Second,
+		Operations: []OperationStats{
+			{Name: OpLogin, Count: 20, Errors: 2, LastError: "unauthorized", P50: 3 * time.Millisecond},
+			{Name: OpSyncPull, Count: 80, P99: 1234567 * time.Microsecond},
+		},
+	}
+
+	// out receives the printed report.
+	var out strings.Builder
+	require.NoError(t, rep.Write(&out))
+
+	assert.Contains(t, out.String(), "operation")
+	assert.Contains(t, out.String(), "1.235s", "long latencies should be rounded to milliseconds")
+	assert.Contains(t, out.String(), "100 requests in 10s, 10.0 req/s, 2.00% errors")
+	assert.Contains(t, out.String(), "last login error: unauthorized")
+	assert.NotContains(t, out.String(), "last sync.pull error")
+}
@@ -0,0 +1,302 @@
+package loadgen
+
+import (
+	"context"
+	crand "crypto/rand"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/pkg/client"
+	"github.com/google/uuid"
+)
+
+// maxKeptIDs bounds the item IDs a virtual user remembers per item type for reads and updates.
+const maxKeptIDs = 100
+
+// Config contains the settings of a load test run.
+type Config struct {
+	// HTTPClient sends the requests; http.DefaultClient is used when nil.
+	HTTPClient *http.Client
+	// Progress receives a report of the results so far every ReportInterval; nil disables progress reports.
+	Progress func(*Report)
+	// Mix weights the operations of the virtual users; DefaultMix is used when empty.
+	Mix Mix
+	// BaseURL is the address of the target server, such as "https://localhost:56789".
+	BaseURL string
+	// LoginPrefix starts the logins of the registered virtual users.
+	LoginPrefix string
+	// Concurrency is the number of virtual users sending requests in parallel.
+	Concurrency int
+	// Duration is how long the virtual users send requests.
+	Duration time.Duration
+	// ThinkTime is the pause of a virtual user between two requests.
+	ThinkTime time.Duration
+	// ReportInterval is the period of progress reports.
+	ReportInterval time.Duration
+	// FileSize is the size in bytes of uploaded files.
+	FileSize int
+}
+
+// Run registers Concurrency virtual users and lets them send the operations of the mix until Duration
+// passes or ctx ends, then reports the results. Requests interrupted by the end of the run are not counted.
+// It fails when the settings are invalid or no virtual user could register.
+func Run(ctx context.Context, cfg Config) (*Report, error) {
+	if cfg.Concurrency < 1 {
+		return nil, errors.New("concurrency must be at least 1")
+	}
+	if cfg.Duration <= 0 {
+		return nil, errors.New("duration must be positive")
+	}
+	if cfg.FileSize < 0 {
+		return nil, errors.New("file size must not be negative")
+	}
+	mix := cfg.Mix
+	if mix.total() == 0 {
+		mix = DefaultMix
+	}
+	if _, err := client.New(cfg.BaseURL); err != nil {
+		return nil, fmt.Errorf("invalid target: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, cfg.Duration)
+	defer cancel()
+
+	rec := newRecorder(time.Now())
+	runID := strconv.FormatInt(time.Now().UnixNano(), 36)
+	payload := make([]byte, cfg.FileSize)
+	_, _ = crand.Read(payload)
+
+	var wg sync.WaitGroup
+	// registered counts the virtual users that registered.
+	var registered atomic.Int64
+	for i := range cfg.Concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			u, err := newVirtualUser(ctx, &cfg, fmt.Sprintf("%s-%s-%d", cfg.LoginPrefix, runID, i), payload, rec)
+			if err != nil {
+				return
+			}
+			registered.Add(1)
+			u.run(ctx, mix, rec)
+		}()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	if cfg.Progress != nil && cfg.ReportInterval > 0 {
+		ticker := time.NewTicker(cfg.ReportInterval)
+		defer ticker.Stop()
+	progress:
+		for {
+			select {
+			case <-ticker.C:
+				cfg.Progress(rec.report(time.Now()))
+			case <-done:
+				break progress
+			}
+		}
+	}
+	<-done
+
+	rep := rec.report(time.Now())
+	if registered.Load() == 0 {
+		return rep, errors.New("no virtual user could register")
+	}
+	return rep, nil
+}
+
+// virtualUser is a registered account sending the requests of one worker.
+type virtualUser struct {
+	// client sends the requests of the user.
+	client *client.Client
+	// rnd draws the operations and item contents.
+	rnd *rand.Rand
+	// login contains the login of the user.
+	login string
+	// password contains the password of the user.
+	password string
+	// payload contains the content of uploaded files.
+	payload []byte
+	// credentials lists the IDs of the most recently created credentials.
+	credentials []uuid.UUID
+	// files lists the IDs of the most recently uploaded files.
+	files []uuid.UUID
+	// thinkTime is the pause between two requests.
+	thinkTime time.Duration
+	// uploads counts the uploaded files, naming them uniquely.
+	uploads int
+}
+
+// newVirtualUser registers and logs in a user with the login and a random password.
+func newVirtualUser(
+	ctx context.Context,
+	cfg *Config,
+	login string,
+	payload []byte,
+	rec *recorder,
+) (*virtualUser, error) {
+	password := crand.Text()
+	opts := []client.Option{
+		client.WithCredentials(login, password),
+		client.WithRetryPolicy(client.RetryPolicy{MaxAttempts: 1}),
+	}
+	if cfg.HTTPClient != nil {
+		opts = append(opts, client.WithHTTPClient(cfg.HTTPClient))
+	}
+	c, err := client.New(cfg.BaseURL, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client: %w", err)
+	}
+
+	u := &virtualUser{
+		client:    c,
+		rnd:       rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64())), //nolint:gosec // Workload shape only
+		login:     login,
+		password:  password,
+		payload:   payload,
+		thinkTime: cfg.ThinkTime,
+	}
+	for _, op := range []Operation{OpRegister, OpLogin} {
+		if err := u.measure(ctx, rec, op, func(ctx context.Context) error { return u.do(ctx, op) }); err != nil {
+			return nil, err
+		}
+	}
+	return u, nil
+}
+
+// run sends operations drawn from the mix until ctx ends.
+func (u *virtualUser) run(ctx context.Context, mix Mix, rec *recorder) {
+	for ctx.Err() == nil {
+		op := u.resolve(mix.pick(u.rnd))
+		_ = u.measure(ctx, rec, op, func(ctx context.Context) error { return u.do(ctx, op) })
+		if u.thinkTime > 0 {
+			select {
+			case <-ctx.Done():
+			case <-time.After(u.thinkTime):
+			}
+		}
+	}
+}
+
+// resolve replaces operations on stored items with creating one while the user has none.
+func (u *virtualUser) resolve(op Operation) Operation {
+	switch {
+	case (op == OpCredentialRead || op == OpCredentialUpdate) && len(u.credentials) == 0:
+		return OpCredentialCreate
+	case op == OpFileDownload && len(u.files) == 0:
+		return OpFileUpload
+	default:
+		return op
+	}
+}
+
+// measure runs a request and records its latency and error, unless the end of the run interrupted it.
+func (u *virtualUser) measure(ctx context.Context, rec *recorder, op Operation, fn func(context.Context) error) error {
+	start := time.Now()
+	err := fn(ctx)
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	rec.record(op, time.Since(start), err)
+	return err
+}
+
+// do sends the request of an operation.
+func (u *virtualUser) do(ctx context.Context, op Operation) error {
+	switch op {
+	case OpRegister:
+		_, err := u.client.Register(ctx, u.login, u.password)
+		return err //nolint:wrapcheck // Client errors describe the request
+	case OpLogin:
+		res, err := u.client.Login(ctx, u.login, u.password)
+		if err != nil {
+			return err //nolint:wrapcheck // Client errors describe the request
+		}
+		if res.StepUp != nil {
+			return errors.New("login held for step-up verification")
+		}
+		return nil
+	case OpCredentialCreate:
+		id, err := u.client.CreateCredential(ctx, u.credential())
+		if err == nil {
+			u.credentials = keep(u.credentials, id)
+		}
+		return err //nolint:wrapcheck // Client errors describe the request
+	case OpCredentialRead:
+		_, err := u.client.GetCredential(ctx, u.pick(u.credentials))
+		return err //nolint:wrapcheck // Client errors describe the request
+	case OpCredentialUpdate:
+		return u.client.UpdateCredential(ctx, u.pick(u.credentials), u.credential()) //nolint:wrapcheck // Client errors
+	case OpNoteCreate:
+		_, err := u.client.CreateNote(ctx, &client.Note{
+			Note:        fmt.Sprintf("Load test note %d\n%s", u.rnd.IntN(1_000_000), crand.Text()),
+			Description: "loadgen",
+		})
+		return err //nolint:wrapcheck // Client errors describe the request
+	case OpNoteList:
+		_, err := u.client.ListNotes(ctx)
+		return err //nolint:wrapcheck // Client errors describe the request
+	case OpBankCardCreate:
+		_, err := u.client.CreateBankCard(ctx, &client.BankCard{
+			CardNumber:  "4111111111111111",
+			CardHolder:  "LOAD TEST",
+			ExpiryMonth: fmt.Sprintf("%02d", 1+u.rnd.IntN(12)),
+			ExpiryYear:  strconv.Itoa(time.Now().Year() + 1 + u.rnd.IntN(5)),
+			CVV:         fmt.Sprintf("%03d", u.rnd.IntN(1000)),
+			Description: "loadgen",
+		})
+		return err //nolint:wrapcheck // Client errors describe the request
+	case OpSyncPull:
+		_, err := u.client.PullVault(ctx)
+		return err //nolint:wrapcheck // Client errors describe the request
+	case OpFileUpload:
+		u.uploads++
+		id, err := u.client.UploadFile(ctx, &client.FileUpload{
+			StorageKey:  fmt.Sprintf("loadgen-%d.bin", u.uploads),
+			Description: "loadgen",
+			Content:     u.payload,
+		})
+		if err == nil {
+			u.files = keep(u.files, id)
+		}
+		return err //nolint:wrapcheck // Client errors describe the request
+	case OpFileDownload:
+		_, err := u.client.DownloadFile(ctx, u.pick(u.files))
+		return err //nolint:wrapcheck // Client errors describe the request
+	default:
+		return fmt.Errorf("unknown operation %q", op)
+	}
+}
+
+// credential returns a credential with random contents.
+func (u *virtualUser) credential() *client.Credential {
+	return &client.Credential{
+		Login:       fmt.Sprintf("user%d@example.com", u.rnd.IntN(1_000_000)),
+		Password:    crand.Text(),
+		Description: "loadgen",
+	}
+}
+
+// pick returns a random ID of the list.
+func (u *virtualUser) pick(ids []uuid.UUID) uuid.UUID {
+	return ids[u.rnd.IntN(len(ids))]
+}
+
+// keep appends an ID to the list, dropping the oldest one beyond maxKeptIDs.
+func keep(ids []uuid.UUID, id uuid.UUID) []uuid.UUID {
+	ids = append(ids, id)
+	if len(ids) > maxKeptIDs {
+		ids = ids[1:]
+	}
+	return ids
+}
@@ -0,0 +1,153 @@
+package loadgen
+
+import (
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeServer answers the API requests of the workload with minimal valid responses.
+type fakeServer struct {
+	// rejectRegistration makes every registration fail.
+	rejectRegistration bool
+	// holdLogins answers logins with a step-up challenge.
+	holdLogins bool
+	// requests counts the answered requests.
+	requests atomic.Int64
+}
+
+// ServeHTTP implements http.Handler.
+func (f *fakeServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.requests.Add(1)
+	reply := func(status int, v any) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(v)
+	}
+
+	switch {
+	case r.URL.Path == "/api/auth/register" && f.rejectRegistration:
+		reply(http.StatusConflict, map[string]string{"error": "user already exists"})
+	case r.URL.Path == "/api/auth/login" && f.holdLogins:
+		reply(http.StatusAccepted, map[string]any{"challenge_id": uuid.New(), "expires_at": time.Now().Add(time.Hour)})
+	case r.URL.Path == "/api/auth/login":
+		reply(http.StatusOK, map[string]any{
+			"access_token": "token", "token_type": "Bearer", "expires_at": time.Now().Add(time.Hour),
+		})
+	case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/api/items/filedata/") &&
+		r.URL.Path != "/api/items/filedata/":
+		mw := multipart.NewWriter(w)
+		w.Header().Set("Content-Type", mw.FormDataContentType())
+		meta, _ := mw.CreateFormField("metadata")
+		_ = json.NewEncoder(meta).Encode(map[string]string{"storage_key": "loadgen-1.bin"})
+		file, _ := mw.CreateFormFile("file", "loadgen-1.bin")
+		_, _ = file.Write([]byte("content"))
+		_ = mw.Close()
+	case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/api/items/credentials/"):
+		reply(http.StatusOK, map[string]any{"credential": map[string]string{"login": "user@example.com"}})
+	case r.Method == http.MethodPost:
+		reply(http.StatusCreated, map[string]any{"id": uuid.New()})
+	default:
+		reply(http.StatusOK, map[string]any{})
+	}
+}
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	f := &fakeServer{}
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+
+	// progress counts the progress reports.
+	var progress atomic.Int64
+	rep, err := Run(t.Context(), Config{
+		BaseURL:        srv.URL,
+		HTTPClient:     srv.Client(),
+		LoginPrefix:    "test",
+		Concurrency:    3,
+		Duration:       300 * time.Millisecond,
+		ThinkTime:      time.Millisecond,
+		ReportInterval: 50 * time.Millisecond,
+		FileSize:       128,
+		Progress:       func(*Report) { progress.Add(1) },
+	})
+	require.NoError(t, err)
+
+	assert.Positive(t, progress.Load(), "progress should be reported")
+	assert.Zero(t, rep.ErrorRate(), "the fake server accepts every request")
+	assert.LessOrEqual(t, rep.Requests(), f.requests.Load(), "requests interrupted by the end should not be counted")
+	// seen holds the operations of the report.
+	seen := make(map[Operation]int64)
+	for _, s := range rep.Operations {
+		seen[s.Name] = s.Count
+	}
+	assert.Equal(t, int64(3), seen[OpRegister], "every virtual user should register once")
+	assert.Greater(t, seen[OpLogin], int64(3), "logins should be part of the default mix")
+	assert.Positive(t, seen[OpCredentialRead])
+}
+
+func TestRun_Fails(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		server  *fakeServer
+		cfg     Config
+		wantErr string
+	}{
+		{
+			name:    "no concurrency",
+			cfg:     Config{Duration: time.Second},
+			wantErr: "concurrency must be at least 1",
+		},
+		{
+			name:    "no duration",
+			cfg:     Config{Concurrency: 1},
+			wantErr: "duration must be positive",
+		},
+		{
+			name:    "invalid target",
+			cfg:     Config{Concurrency: 1, Duration: time.Second, BaseURL: "localhost"},
+			wantErr: "invalid target",
+		},
+		{
+			name:    "registration rejected",
+			server:  &fakeServer{rejectRegistration: true},
+			cfg:     Config{Concurrency: 2, Duration: time.Second},
+			wantErr: "no virtual user could register",
+		},
+		{
+			name:    "logins held",
+			server:  &fakeServer{holdLogins: true},
+			cfg:     Config{Concurrency: 1, Duration: time.Second},
+			wantErr: "no virtual user could register",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := tt.cfg
+			if tt.server != nil {
+				srv := httptest.NewServer(tt.server)
+				t.Cleanup(srv.Close)
+				cfg.BaseURL = srv.URL
+				cfg.HTTPClient = srv.Client()
+			}
+
+			_, err := Run(t.Context(), cfg)
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
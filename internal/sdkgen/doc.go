@@ -0,0 +1,6 @@
+// Package sdkgen generates client artifacts from the OpenAPI specification of the AegisVaultKeeper API.
+//
+// The specification is produced from the delivery DTOs and handler annotations by swag; this package
+// derives a TypeScript client from it and computes the specification hash the server reports, so
+// generated clients can detect when they drift from the server contract.
+package sdkgen
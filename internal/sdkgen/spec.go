@@ -0,0 +1,119 @@
+package sdkgen
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+)
+
+// Spec is the subset of a Swagger 2.0 document the generator uses.
+type Spec struct {
+	// Paths maps the API paths to their operations keyed by lowercase HTTP method.
+	Paths map[string]map[string]*Operation `json:"paths"`
+	// Definitions maps the names of the shared schemas to the schemas.
+	Definitions map[string]*Schema `json:"definitions"`
+	// BasePath is the path prefix of all API paths, such as "/api".
+	BasePath string `json:"basePath"`
+	// Info describes the API.
+	Info struct {
+		// Title contains the name of the API.
+		Title string `json:"title"`
+		// Version contains the version of the API.
+		Version string `json:"version"`
+	} `json:"info"`
+}
+
+// Operation is an API operation.
+type Operation struct {
+	// Responses maps the status codes to the responses of the operation.
+	Responses map[string]*Response `json:"responses"`
+	// Summary contains the short description of the operation.
+	Summary string `json:"summary"`
+	// Produces lists the media types of the responses.
+	Produces []string `json:"produces"`
+	// Parameters lists the parameters of the operation.
+	Parameters []*Parameter `json:"parameters"`
+	// Security lists the security requirements of the operation; empty for public operations.
+	Security []map[string][]string `json:"security"`
+}
+
+// Parameter is a parameter of an operation.
+type Parameter struct {
+	// Schema describes body parameters.
+	Schema *Schema `json:"schema"`
+	// Items describes the elements of array parameters.
+	Items *Schema `json:"items"`
+	// Name contains the name of the parameter.
+	Name string `json:"name"`
+	// In names the location of the parameter: "path", "query", "header", "body" or "formData".
+	In string `json:"in"`
+	// Description contains the description of the parameter.
+	Description string `json:"description"`
+	// Type contains the type of non-body parameters.
+	Type string `json:"type"`
+	// Required determines whether the parameter must be sent.
+	Required bool `json:"required"`
+}
+
+// Response is a response of an operation.
+type Response struct {
+	// Schema describes the response body; nil for responses without content.
+	Schema *Schema `json:"schema"`
+	// Description contains the description of the response.
+	Description string `json:"description"`
+}
+
+// Schema is a JSON schema of a value.
+type Schema struct {
+	// Properties maps the property names of objects to their schemas.
+	Properties map[string]*Schema `json:"properties"`
+	// Items describes the elements of arrays.
+	Items *Schema `json:"items"`
+	// AdditionalProperties describes the values of maps; a boolean or a schema.
+	AdditionalProperties json.RawMessage `json:"additionalProperties"`
+	// Ref references a shared schema, such as "#/definitions/note.Note".
+	Ref string `json:"$ref"`
+	// Type contains the JSON type of the value.
+	Type string `json:"type"`
+	// Description contains the description of the value.
+	Description string `json:"description"`
+	// Required lists the required properties of objects.
+	Required []string `json:"required"`
+	// AllOf lists schemas the value satisfies at once.
+	AllOf []*Schema `json:"allOf"`
+	// Enum lists the allowed values.
+	Enum []any `json:"enum"`
+}
+
+// ParseSpec decodes a Swagger 2.0 JSON document.
+func ParseSpec(data []byte) (*Spec, error) {
+	// s holds the decoded document.
+	var s Spec
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("failed to decode OpenAPI spec: %w", err)
+	}
+	return &s, nil
+}
+
+// SpecHash returns the hex SHA-256 digest of the canonical form of a JSON specification: compact, with sorted
+// object keys and without the top-level members that are null or empty arrays. The spec file written by swag
+// omits empty schemes that the spec rendered by the server lists, so both hash the same.
+func SpecHash(data []byte) (string, error) {
+	// doc holds the decoded specification.
+	var doc map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return "", fmt.Errorf("failed to decode OpenAPI spec: %w", err)
+	}
+	for k, v := range doc {
+		if items, ok := v.([]any); v == nil || (ok && len(items) == 0) {
+			delete(doc, k)
+		}
+	}
+	canonical, err := json.Marshal(doc)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode canonical OpenAPI spec: %w", err)
+	}
+	sum := sha256.Sum256(canonical)
+	return hex.EncodeToString(sum[:]), nil
+}
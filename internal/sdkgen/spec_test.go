@@ -0,0 +1,84 @@
+package sdkgen
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpecHash(t *testing.T) {
+	t.Parallel()
+
+	base := `{"swagger":"2.0","info":{"title":"API","version":"1.0"},` +
+		`"paths":{"/a":{"get":{"security":[{"BearerAuth":[]}]}}}}`
+	want, err := SpecHash([]byte(base))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		spec     string
+		wantSame bool
+		wantErr  bool
+	}{
+		{
+			name: "indentation and key order",
+			spec: `{
+				"paths": {"/a": {"get": {"security": [{"BearerAuth": []}]}}},
+				"info": {"version": "1.0", "title": "API"},
+				"swagger": "2.0"
+			}`,
+			wantSame: true,
+		},
+		{
+			name: "empty top-level schemes",
+			spec: `{"schemes":[],"swagger":"2.0","info":{"title":"API","version":"1.0"},` +
+				`"paths":{"/a":{"get":{"security":[{"BearerAuth":[]}]}}}}`,
+			wantSame: true,
+		},
+		{
+			name: "operation made public",
+			spec: `{"swagger":"2.0","info":{"title":"API","version":"1.0"},"paths":{"/a":{"get":{}}}}`,
+		},
+		{
+			name: "version bump",
+			spec: `{"swagger":"2.0","info":{"title":"API","version":"1.1"},` +
+				`"paths":{"/a":{"get":{"security":[{"BearerAuth":[]}]}}}}`,
+		},
+		{
+			name:    "invalid JSON",
+			spec:    `{"swagger":`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := SpecHash([]byte(tt.spec))
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, got, 64)
+			assert.Equal(t, tt.wantSame, got == want)
+		})
+	}
+}
+
+func TestParseSpec(t *testing.T) {
+	t.Parallel()
+
+	spec, err := ParseSpec([]byte(`{"basePath":"/api","info":{"version":"0.1.1"},
+		"paths":{"/notes/{id}":{"get":{"parameters":[{"name":"id","in":"path","type":"string","required":true}]}}}}`))
+	require.NoError(t, err)
+	assert.Equal(t, "/api", spec.BasePath)
+	assert.Equal(t, "0.1.1", spec.Info.Version)
+	require.Len(t, spec.Paths["/notes/{id}"]["get"].Parameters, 1)
+	assert.Equal(t, "path", spec.Paths["/notes/{id}"]["get"].Parameters[0].In)
+
+	_, err = ParseSpec([]byte(`[]`))
+	require.Error(t, err)
+}
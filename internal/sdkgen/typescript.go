@@ -0,0 +1,378 @@
+package sdkgen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// runtime is the fixed part of the generated TypeScript client.
+const runtime = `export class ApiError extends Error {
+  constructor(
+    readonly status: number,
+    readonly body: unknown,
+  ) {
+    super(` + "`API request failed with status ${status}`" + `);
+  }
+}
+
+export interface ClientOptions {
+  /** Base URL of the API including the base path, such as "https://vault.example.com/api". */
+  baseUrl: string;
+  /** Returns the access token sent to protected operations. */
+  token?: () => string | undefined | Promise<string | undefined>;
+  /** Fetch implementation; the global fetch by default. */
+  fetch?: typeof fetch;
+}
+
+type Query = Record<string, string | number | boolean | undefined>;
+
+export class Client {
+  constructor(private readonly options: ClientOptions) {}
+
+  private async request<T>(
+    method: string,
+    path: string,
+    init: { query?: Query; body?: unknown; form?: FormData; secure: boolean; blob?: boolean },
+  ): Promise<T> {
+    const url = new URL(this.options.baseUrl.replace(/\/$/, "") + path);
+    for (const [key, value] of Object.entries(init.query ?? {})) {
+      if (value !== undefined) url.searchParams.set(key, String(value));
+    }
+    const headers: Record<string, string> = { Accept: "application/json" };
+    let body: BodyInit | undefined = init.form;
+    if (init.body !== undefined) {
+      headers["Content-Type"] = "application/json";
+      body = JSON.stringify(init.body);
+    }
+    if (init.secure && this.options.token) {
+      const token = await this.options.token();
+      if (token) headers.Authorization = ` + "`Bearer ${token}`" + `;
+    }
+    const res = await (this.options.fetch ?? fetch)(url, { method, headers, body });
+    if (!res.ok) {
+      const text = await res.text();
+      let detail: unknown = text;
+      try {
+        detail = JSON.parse(text);
+      } catch {
+        // Keep the raw text of non-JSON error bodies.
+      }
+      throw new ApiError(res.status, detail);
+    }
+    if (res.status === 204) return undefined as T;
+    if (init.blob) return (await res.blob()) as T;
+    return (await res.json()) as T;
+  }
+`
+
+// TypeScript generates a TypeScript client from a Swagger 2.0 JSON specification.
+//
+// The client declares an interface per schema definition and a method per operation. The output is
+// deterministic, so regenerating it from an unchanged specification produces identical bytes.
+func TypeScript(data []byte) ([]byte, error) {
+	spec, err := ParseSpec(data)
+	if err != nil {
+		return nil, err
+	}
+	hash, err := SpecHash(data)
+	if err != nil {
+		return nil, err
+	}
+
+	// b accumulates the generated source.
+	var b bytes.Buffer
+	b.WriteString("// Code generated by sdkgen from the OpenAPI specification. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "/** Version of the API the client was generated from. */\nexport const SPEC_VERSION = %q;\n",
+		spec.Info.Version)
+	fmt.Fprintf(&b, "/** SHA-256 digest of the specification, as reported by GET /sdk. */\n"+
+		"export const SPEC_SHA256 = %q;\n", hash)
+
+	for _, name := range sortedKeys(spec.Definitions) {
+		b.WriteString("\n")
+		writeInterface(&b, name, spec.Definitions[name])
+	}
+
+	b.WriteString("\n")
+	b.WriteString(runtime)
+	for _, path := range sortedKeys(spec.Paths) {
+		ops := spec.Paths[path]
+		for _, method := range sortedKeys(ops) {
+			b.WriteString("\n")
+			if err := writeMethod(&b, path, method, ops[method]); err != nil {
+				return nil, fmt.Errorf("failed to generate %s %s: %w", strings.ToUpper(method), path, err)
+			}
+		}
+	}
+	b.WriteString("}\n")
+	return b.Bytes(), nil
+}
+
+// writeInterface writes the interface declaration of a schema definition.
+func writeInterface(b *bytes.Buffer, name string, s *Schema) {
+	writeDoc(b, "", s.Description)
+	fmt.Fprintf(b, "export interface %s {\n", typeName(name))
+	for _, prop := range sortedKeys(s.Properties) {
+		ps := s.Properties[prop]
+		writeDoc(b, "  ", ps.Description)
+		optional := "?"
+		if slices.Contains(s.Required, prop) {
+			optional = ""
+		}
+		fmt.Fprintf(b, "  %s%s: %s;\n", propertyName(prop), optional, tsType(ps))
+	}
+	b.WriteString("}\n")
+}
+
+// writeMethod writes the client method of an operation.
+func writeMethod(b *bytes.Buffer, path, method string, op *Operation) error {
+	// args lists the method arguments in declaration order.
+	var args []string
+	// query lists the query parameter declarations.
+	var query []string
+	// form lists the form parameters.
+	var form []*Parameter
+	// body holds the expression of the JSON body.
+	body := ""
+	urlPath := path
+	for _, p := range op.Parameters {
+		switch p.In {
+		case "path":
+			args = append(args, fmt.Sprintf("%s: %s", identifier(p.Name), paramType(p)))
+			urlPath = strings.ReplaceAll(urlPath, "{"+p.Name+"}", "${encodeURIComponent("+identifier(p.Name)+")}")
+		case "body":
+			args = append(args, fmt.Sprintf("body: %s", tsType(p.Schema)))
+			body = "body"
+		case "query":
+			optional := "?"
+			if p.Required {
+				optional = ""
+			}
+			query = append(query, fmt.Sprintf("%s%s: %s", propertyName(p.Name), optional, paramType(p)))
+		case "formData":
+			form = append(form, p)
+		case "header":
+		default:
+			return fmt.Errorf("unsupported parameter location %q", p.In)
+		}
+	}
+	if len(form) > 0 {
+		args = append(args, "form: FormData")
+	}
+	if len(query) > 0 {
+		args = append(args, fmt.Sprintf("query: { %s } = {}", strings.Join(query, "; ")))
+	}
+
+	result, blob := resultType(op)
+	doc := op.Summary
+	for _, p := range form {
+		doc += fmt.Sprintf("\n\nForm field %q (%s): %s", p.Name, paramType(p), p.Description)
+	}
+	writeDoc(b, "  ", strings.TrimSpace(doc))
+	fmt.Fprintf(b, "  %s(%s): Promise<%s> {\n", methodName(method, path), strings.Join(args, ", "), result)
+
+	// init lists the request options.
+	init := []string{fmt.Sprintf("secure: %t", len(op.Security) > 0)}
+	if len(query) > 0 {
+		init = append(init, "query")
+	}
+	if body != "" {
+		init = append(init, "body")
+	}
+	if len(form) > 0 {
+		init = append(init, "form")
+	}
+	if blob {
+		init = append(init, "blob: true")
+	}
+	fmt.Fprintf(b, "    return this.request(%q, `%s`, { %s });\n  }\n",
+		strings.ToUpper(method), urlPath, strings.Join(init, ", "))
+	return nil
+}
+
+// resultType returns the TypeScript type of the successful result of an operation and whether the result is
+// a binary body.
+func resultType(op *Operation) (string, bool) {
+	// types lists the distinct result types of the successful responses.
+	var types []string
+	blob := false
+	for _, code := range sortedKeys(op.Responses) {
+		if len(code) != 3 || code[0] != '2' {
+			continue
+		}
+		t := "unknown"
+		if s := op.Responses[code].Schema; s != nil {
+			t = tsType(s)
+			blob = t == "Blob"
+		} else if code == "204" {
+			t = "undefined"
+		} else if slices.Contains(op.Produces, "application/octet-stream") {
+			t, blob = "Blob", true
+		}
+		if !slices.Contains(types, t) {
+			types = append(types, t)
+		}
+	}
+	if len(types) == 0 {
+		return "void", false
+	}
+	return strings.Join(types, " | "), blob
+}
+
+// tsType returns the TypeScript type of a schema.
+func tsType(s *Schema) string {
+	if s == nil {
+		return "unknown"
+	}
+	if s.Ref != "" {
+		return typeName(strings.TrimPrefix(s.Ref, "#/definitions/"))
+	}
+	if len(s.AllOf) > 0 {
+		// parts lists the types of the combined schemas.
+		var parts []string
+		for _, sub := range s.AllOf {
+			parts = append(parts, tsType(sub))
+		}
+		return strings.Join(parts, " & ")
+	}
+	if len(s.Enum) > 0 {
+		// values lists the encoded allowed values.
+		var values []string
+		for _, v := range s.Enum {
+			encoded, _ := json.Marshal(v)
+			values = append(values, string(encoded))
+		}
+		return strings.Join(values, " | ")
+	}
+	switch s.Type {
+	case "string":
+		return "string"
+	case "integer", "number":
+		return "number"
+	case "boolean":
+		return "boolean"
+	case "file":
+		return "Blob"
+	case "array":
+		elem := tsType(s.Items)
+		if strings.ContainsAny(elem, "|&") {
+			elem = "(" + elem + ")"
+		}
+		return elem + "[]"
+	case "object", "":
+		if len(s.Properties) > 0 {
+			// fields lists the inline property declarations.
+			var fields []string
+			for _, prop := range sortedKeys(s.Properties) {
+				optional := "?"
+				if slices.Contains(s.Required, prop) {
+					optional = ""
+				}
+				fields = append(fields, fmt.Sprintf("%s%s: %s", propertyName(prop), optional, tsType(s.Properties[prop])))
+			}
+			return "{ " + strings.Join(fields, "; ") + " }"
+		}
+		if len(s.AdditionalProperties) > 0 {
+			// values describes the map values.
+			var values Schema
+			if err := json.Unmarshal(s.AdditionalProperties, &values); err == nil {
+				return "Record<string, " + tsType(&values) + ">"
+			}
+		}
+		if s.Type == "object" {
+			return "Record<string, unknown>"
+		}
+	}
+	return "unknown"
+}
+
+// paramType returns the TypeScript type of a non-body parameter.
+func paramType(p *Parameter) string {
+	return tsType(&Schema{Type: p.Type, Items: p.Items})
+}
+
+// typeName converts a definition name such as "note.PullResponse" to a TypeScript type name such as
+// "NotePullResponse".
+func typeName(name string) string {
+	return pascal(name, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) })
+}
+
+// methodName derives the client method name of an operation from its HTTP method and path, so
+// "GET /items/notes/{id}" becomes "getItemsNotesById".
+func methodName(method, path string) string {
+	name := strings.ToLower(method)
+	for _, segment := range strings.Split(path, "/") {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			name += "By" + pascal(strings.Trim(segment, "{}"), isSeparator)
+			continue
+		}
+		name += pascal(segment, isSeparator)
+	}
+	return name
+}
+
+// identifier converts a parameter name to a TypeScript identifier.
+func identifier(name string) string {
+	p := pascal(name, isSeparator)
+	if p == "" {
+		return "_"
+	}
+	return strings.ToLower(p[:1]) + p[1:]
+}
+
+// propertyName quotes property names that are not valid TypeScript identifiers.
+func propertyName(name string) string {
+	for i, r := range name {
+		if r != '_' && r != '$' && !unicode.IsLetter(r) && (i == 0 || !unicode.IsDigit(r)) {
+			return fmt.Sprintf("%q", name)
+		}
+	}
+	return name
+}
+
+// pascal splits a string at the runes matching sep and joins the words with their first letters capitalized.
+func pascal(s string, sep func(rune) bool) string {
+	// b accumulates the joined words.
+	var b strings.Builder
+	for _, word := range strings.FieldsFunc(s, sep) {
+		b.WriteString(strings.ToUpper(word[:1]) + word[1:])
+	}
+	return b.String()
+}
+
+// isSeparator reports whether a rune separates words in paths and parameter names.
+func isSeparator(r rune) bool {
+	return r == '-' || r == '_' || r == '.' || r == ' '
+}
+
+// writeDoc writes a JSDoc comment with the given indentation; it writes nothing for empty text.
+func writeDoc(b *bytes.Buffer, indent, text string) {
+	if text == "" {
+		return
+	}
+	text = strings.ReplaceAll(text, "*/", "*\\/")
+	lines := strings.Split(text, "\n")
+	if len(lines) == 1 {
+		fmt.Fprintf(b, "%s/** %s */\n", indent, text)
+		return
+	}
+	fmt.Fprintf(b, "%s/**\n", indent)
+	for _, line := range lines {
+		fmt.Fprintf(b, "%s%s\n", indent, strings.TrimRight(" * "+line, " "))
+	}
+	fmt.Fprintf(b, "%s */\n", indent)
+}
+
+// sortedKeys returns the keys of a map in ascending order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
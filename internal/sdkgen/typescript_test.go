@@ -0,0 +1,181 @@
+package sdkgen
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testSpec is a small specification exercising the supported constructs.
+const testSpec = `{
+  "swagger": "2.0",
+  "info": {"title": "Test API", "version": "2.3.4"},
+  "basePath": "/api",
+  "paths": {
+    "/items/notes/{id}": {
+      "get": {
+        "summary": "Get note by ID",
+        "security": [{"BearerAuth": []}],
+        "parameters": [
+          {"name": "id", "in": "path", "type": "string", "required": true},
+          {"name": "fields", "in": "query", "type": "array", "items": {"type": "string"}},
+          {"name": "as-of", "in": "query", "type": "string", "required": true}
+        ],
+        "responses": {
+          "200": {"schema": {"$ref": "#/definitions/note.PullResponse"}},
+          "404": {"schema": {"$ref": "#/definitions/response.Error"}}
+        }
+      },
+      "put": {
+        "security": [{"BearerAuth": []}],
+        "parameters": [
+          {"name": "id", "in": "path", "type": "string", "required": true},
+          {"name": "request", "in": "body", "required": true, "schema": {"$ref": "#/definitions/note.Note"}}
+        ],
+        "responses": {"204": {"description": "Updated"}}
+      }
+    },
+    "/items/files/{file_id}": {
+      "get": {
+        "produces": ["application/octet-stream"],
+        "security": [{"BearerAuth": []}],
+        "parameters": [{"name": "file_id", "in": "path", "type": "string", "required": true}],
+        "responses": {"200": {"description": "File content"}}
+      },
+      "post": {
+        "parameters": [
+          {"name": "file_id", "in": "path", "type": "string", "required": true},
+          {"name": "file", "in": "formData", "type": "file", "description": "File to upload"}
+        ],
+        "responses": {"201": {"schema": {"type": "object", "additionalProperties": {"type": "integer"}}}}
+      }
+    }
+  },
+  "definitions": {
+    "note.Note": {
+      "description": "Note is a text note.",
+      "type": "object",
+      "required": ["note"],
+      "properties": {
+        "note": {"type": "string", "description": "Note contains the text."},
+        "tags": {"type": "array", "items": {"type": "string"}},
+        "kind": {"type": "string", "enum": ["plain", "merge"]}
+      }
+    },
+    "note.PullResponse": {
+      "type": "object",
+      "properties": {"note": {"allOf": [{"$ref": "#/definitions/note.Note"}]}}
+    },
+    "response.Error": {
+      "type": "object",
+      "properties": {"messages": {"type": "array", "items": {"type": "string"}}}
+    }
+  }
+}`
+
+func TestTypeScript(t *testing.T) {
+	t.Parallel()
+
+	hash, err := SpecHash([]byte(testSpec))
+	require.NoError(t, err)
+
+	got, err := TypeScript([]byte(testSpec))
+	require.NoError(t, err)
+	out := string(got)
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "version", want: `export const SPEC_VERSION = "2.3.4";`},
+		{name: "hash", want: `export const SPEC_SHA256 = "` + hash + `";`},
+		{
+			name: "interface",
+			want: "/** Note is a text note. */\nexport interface NoteNote {\n" +
+				"  kind?: \"plain\" | \"merge\";\n  /** Note contains the text. */\n  note: string;\n" +
+				"  tags?: string[];\n}\n",
+		},
+		{name: "allOf reference", want: "  note?: NoteNote;\n"},
+		{
+			name: "path and query parameters",
+			want: "  /** Get note by ID */\n" +
+				"  getItemsNotesById(id: string, query: { fields?: string[]; \"as-of\": string } = {}): " +
+				"Promise<NotePullResponse> {\n" +
+				"    return this.request(\"GET\", `/items/notes/${encodeURIComponent(id)}`, " +
+				"{ secure: true, query });\n  }\n",
+		},
+		{
+			name: "JSON body without content",
+			want: "  putItemsNotesById(id: string, body: NoteNote): Promise<undefined> {\n" +
+				"    return this.request(\"PUT\", `/items/notes/${encodeURIComponent(id)}`, " +
+				"{ secure: true, body });\n  }\n",
+		},
+		{
+			name: "binary response",
+			want: "  getItemsFilesByFileId(fileId: string): Promise<Blob> {\n" +
+				"    return this.request(\"GET\", `/items/files/${encodeURIComponent(fileId)}`, " +
+				"{ secure: true, blob: true });\n  }\n",
+		},
+		{
+			name: "public form upload",
+			want: "  postItemsFilesByFileId(fileId: string, form: FormData): Promise<Record<string, number>> {\n" +
+				"    return this.request(\"POST\", `/items/files/${encodeURIComponent(fileId)}`, " +
+				"{ secure: false, form });\n  }\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Contains(t, out, tt.want)
+		})
+	}
+
+	again, err := TypeScript([]byte(testSpec))
+	require.NoError(t, err)
+	assert.Equal(t, got, again, "generation must be deterministic")
+}
+
+func TestTypeScript_Errors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		spec string
+	}{
+		{name: "invalid JSON", spec: `{`},
+		{
+			name: "unsupported parameter location",
+			spec: `{"paths":{"/a":{"get":{"parameters":[{"name":"sid","in":"cookie","type":"string"}]}}}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := TypeScript([]byte(tt.spec))
+			require.Error(t, err)
+		})
+	}
+}
+
+// TestTypeScript_InLockstep fails when the committed TypeScript client was not regenerated after the
+// OpenAPI specification changed; run `go generate ./docs` to fix it.
+func TestTypeScript_InLockstep(t *testing.T) {
+	t.Parallel()
+
+	root := filepath.Join("..", "..")
+	spec, err := os.ReadFile(filepath.Join(root, "docs", "swagger.json"))
+	require.NoError(t, err)
+	committed, err := os.ReadFile(filepath.Join(root, "sdk", "typescript", "client.ts"))
+	require.NoError(t, err)
+
+	want, err := TypeScript(spec)
+	require.NoError(t, err)
+	assert.Equal(t, string(want), string(committed), "sdk/typescript/client.ts is stale, run go generate ./docs")
+}
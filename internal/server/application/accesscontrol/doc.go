@@ -0,0 +1,6 @@
+// Package accesscontrol provides network access control application services for the AegisVaultKeeper server.
+//
+// This package implements evaluation of global and per-user IP allowlists, CIDR denylists
+// and country blocking, management of the underlying access rules, and the geofence limiting
+// where items tagged restricted can be revealed from.
+package accesscontrol
@@ -0,0 +1,97 @@
+package accesscontrol
+
+import (
+	"net/netip"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/accessrule"
+	"github.com/google/uuid"
+)
+
+// Rule represents an access rule data transfer object for application layer communication.
+type Rule struct {
+	// CreatedAt indicates when the rule was created.
+	CreatedAt time.Time
+	// Network contains the matched CIDR; it is empty for country rules.
+	Network string
+	// Country contains the matched ISO 3166-1 alpha-2 country code; it is empty for network rules.
+	Country string
+	// Action contains "allow" or "deny".
+	Action string
+	// ID uniquely identifies the rule.
+	ID uuid.UUID
+	// UserID identifies the user the rule applies to, or uuid.Nil for global rules.
+	UserID uuid.UUID
+}
+
+// newRuleFromDomain converts a domain access rule entity to application DTO.
+func newRuleFromDomain(r *accessrule.Rule) *Rule {
+	if r == nil {
+		return nil
+	}
+	rule := &Rule{
+		ID:        r.ID,
+		UserID:    r.UserID,
+		Action:    string(r.Action),
+		Country:   r.Country,
+		CreatedAt: r.CreatedAt,
+	}
+	if r.Network.IsValid() {
+		rule.Network = r.Network.String()
+	}
+	return rule
+}
+
+// newRulesFromDomain converts a slice of domain access rule entities to application DTOs.
+func newRulesFromDomain(rs []*accessrule.Rule) []*Rule {
+	result := make([]*Rule, 0, len(rs))
+	for _, r := range rs {
+		result = append(result, newRuleFromDomain(r))
+	}
+	return result
+}
+
+// CheckParams contains parameters for checking whether a request may proceed.
+type CheckParams struct {
+	// IP contains the client address; an invalid address matches no rule.
+	IP netip.Addr
+	// UserID selects the rules of the authenticated user; uuid.Nil selects global rules.
+	UserID uuid.UUID
+}
+
+// RestrictedParams contains parameters for checking whether restricted items may be revealed.
+type RestrictedParams struct {
+	// IP contains the client address; an invalid address lies outside the geofence.
+	IP netip.Addr
+	// UserID identifies the user who owns the items.
+	UserID uuid.UUID
+	// ItemID identifies the revealed item; uuid.Nil covers every item of the user, as in listings.
+	ItemID uuid.UUID
+}
+
+// ListRulesParams contains parameters for listing access rules.
+// When neither Global nor UserID is set, all rules are listed.
+type ListRulesParams struct {
+	// UserID selects the rules of the specified user.
+	UserID uuid.UUID
+	// Global selects the rules applying to all users.
+	Global bool
+}
+
+// AddRuleParams contains parameters for creating an access rule.
+type AddRuleParams struct {
+	// Action contains "allow" or "deny".
+	Action string
+	// Network contains a CIDR or IP address to match (exclusive with Country).
+	Network string
+	// Country contains an ISO 3166-1 alpha-2 country code to match (exclusive with Network).
+	Country string
+	// UserID identifies the user the rule applies to, or uuid.Nil for a global rule.
+	UserID uuid.UUID
+}
+
+// DeleteRuleParams contains parameters for deleting an access rule.
+type DeleteRuleParams struct {
+	// ID identifies the rule to delete.
+	ID uuid.UUID
+}
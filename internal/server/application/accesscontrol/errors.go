@@ -0,0 +1,75 @@
+package accesscontrol
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/errutil"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/accessrule"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/accessrule"
+)
+
+// Access control error definitions.
+var (
+	// ErrAccessControlAppError indicates a general access control application error.
+	ErrAccessControlAppError = errors.New("access control application error")
+
+	// ErrAccessControlTechError indicates a technical error in the access control system.
+	ErrAccessControlTechError = errors.New("access control technical error")
+
+	// ErrAccessDenied indicates that access from the client location is not permitted.
+	ErrAccessDenied = errors.New("access from this location is denied")
+
+	// ErrRestrictedLocation indicates that restricted items cannot be revealed from the client location.
+	ErrRestrictedLocation = errors.New("restricted items cannot be revealed from this location")
+
+	// ErrRuleIncorrectAction indicates an incorrect rule action was provided.
+	ErrRuleIncorrectAction = errors.New("incorrect rule action")
+
+	// ErrRuleIncorrectNetwork indicates an incorrect rule network was provided.
+	ErrRuleIncorrectNetwork = errors.New("incorrect rule network")
+
+	// ErrRuleIncorrectCountry indicates an incorrect rule country was provided.
+	ErrRuleIncorrectCountry = errors.New("incorrect rule country")
+
+	// ErrRuleIncorrectTarget indicates that a rule specified neither or both of network and country.
+	ErrRuleIncorrectTarget = errors.New("rule must specify either a network or a country")
+
+	// ErrRuleNotFound indicates the requested access rule was not found.
+	ErrRuleNotFound = errors.New("access rule not found")
+
+	// ErrGeoIPUnavailable indicates that country rules cannot be used without a GeoIP database.
+	ErrGeoIPUnavailable = errors.New("country rules require a configured GeoIP database")
+)
+
+// mapError maps domain and repository errors to application-level errors.
+func mapError(err error) error {
+	if err == nil {
+		return nil
+	}
+	mapped := errutil.MapError(mapFn, err)
+	if mapped != nil {
+		return fmt.Errorf("access control error mapping failed: %w", mapped)
+	}
+	return nil
+}
+
+// mapFn provides the actual error mapping logic for different error types.
+func mapFn(err error) error {
+	switch {
+	case errors.Is(err, accessrule.ErrNewRuleParamsValidation):
+		return ErrAccessControlAppError
+	case errors.Is(err, accessrule.ErrIncorrectAction):
+		return ErrRuleIncorrectAction
+	case errors.Is(err, accessrule.ErrIncorrectNetwork):
+		return ErrRuleIncorrectNetwork
+	case errors.Is(err, accessrule.ErrIncorrectCountry):
+		return ErrRuleIncorrectCountry
+	case errors.Is(err, accessrule.ErrIncorrectTarget):
+		return ErrRuleIncorrectTarget
+	case errors.Is(err, repository.ErrRuleNotFound):
+		return ErrRuleNotFound
+	default:
+		return errors.Join(ErrAccessControlTechError, err)
+	}
+}
@@ -0,0 +1,241 @@
+package accesscontrol
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/audit"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/accessrule"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/itemtag"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/accessrule"
+	tagRepository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/itemtag"
+	"github.com/google/uuid"
+)
+
+// Reasons recorded when a request is denied.
+const (
+	// reasonDenylist means the client matched a deny rule.
+	reasonDenylist = "denylist"
+	// reasonAllowlist means allow rules exist and the client matched none of them.
+	reasonAllowlist = "allowlist"
+)
+
+// Repository defines the interface for access rule persistence operations.
+type Repository interface {
+	// Save persists an access rule using the provided parameters.
+	Save(ctx context.Context, params repository.SaveParams) error
+	// Load retrieves access rules using the provided parameters.
+	Load(ctx context.Context, params repository.LoadParams) ([]*accessrule.Rule, error)
+	// Delete removes an access rule using the provided parameters.
+	Delete(ctx context.Context, params repository.DeleteParams) error
+}
+
+// TagRepository defines the interface for looking up the tags of vault items.
+type TagRepository interface {
+	// Exists reports whether the item, or any item of the user, carries the tag.
+	Exists(ctx context.Context, params tagRepository.ExistsParams) (bool, error)
+}
+
+// CountryResolver defines the interface for resolving client addresses to countries.
+type CountryResolver interface {
+	// Country returns the ISO 3166-1 alpha-2 country code of ip, or an empty string if unknown.
+	Country(ip netip.Addr) (string, error)
+}
+
+// AuditRecorder defines the interface for recording security audit events.
+type AuditRecorder interface {
+	// Record stores the audit event.
+	Record(ctx context.Context, e audit.Event)
+}
+
+// Service provides network access control operations.
+type Service struct {
+	// r is the repository interface for access rule persistence operations.
+	r Repository
+	// geo resolves client countries; nil disables country rules.
+	geo CountryResolver
+	// tags looks up the restricted tag of items.
+	tags TagRepository
+	// fence limits the locations restricted items can be revealed from.
+	fence accessrule.Geofence
+	// audit records denied requests and rule changes.
+	audit AuditRecorder
+}
+
+// NewService creates a new access control service instance.
+// The country resolver may be nil when no GeoIP database is configured; a geofence without
+// networks and countries leaves restricted items unrestricted.
+func NewService(
+	r Repository,
+	geo CountryResolver,
+	tags TagRepository,
+	fence accessrule.Geofence,
+	audit AuditRecorder,
+) *Service {
+	return &Service{r: r, geo: geo, tags: tags, fence: fence, audit: audit}
+}
+
+// Check verifies that the client address is permitted by the access rules.
+// Global rules are evaluated when no user is specified, otherwise the rules of that user.
+// Deny rules take precedence; when allow rules exist, the client must match at least one of them.
+// Addresses whose country cannot be resolved match no country rule.
+func (s *Service) Check(ctx context.Context, params CheckParams) error {
+	rules, err := s.r.Load(ctx, repository.LoadParams{
+		Global: params.UserID == uuid.Nil,
+		UserID: params.UserID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to load access rules: %w", mapError(err))
+	}
+	if len(rules) == 0 {
+		return nil
+	}
+
+	country := s.country(params.IP, rules)
+	reason := evaluate(rules, params.IP, country)
+	if reason == "" {
+		return nil
+	}
+
+	s.audit.Record(ctx, audit.Event{
+		Type:    audit.EventAccessDenied,
+		UserID:  params.UserID,
+		Details: map[string]string{"reason": reason, "country": country},
+	})
+	return fmt.Errorf("request rejected by %s: %w", reason, ErrAccessDenied)
+}
+
+// CheckRestricted verifies that a reveal of the item, or of any item of the user when no item is
+// specified, is permitted from the client location. Items tagged restricted may only be revealed
+// from inside the geofence; addresses whose country cannot be resolved lie outside every country.
+func (s *Service) CheckRestricted(ctx context.Context, params RestrictedParams) error {
+	if !s.fence.Enabled() {
+		return nil
+	}
+
+	country := ""
+	if len(s.fence.Countries) != 0 && s.geo != nil && params.IP.IsValid() {
+		country, _ = s.geo.Country(params.IP)
+	}
+	if s.fence.Admits(params.IP, country) {
+		return nil
+	}
+
+	restricted, err := s.tags.Exists(ctx, tagRepository.ExistsParams{
+		Tag:    itemtag.Restricted,
+		UserID: params.UserID,
+		ItemID: params.ItemID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to look up restricted items: %w", mapError(err))
+	}
+	if !restricted {
+		return nil
+	}
+
+	details := map[string]string{"country": country}
+	if params.ItemID != uuid.Nil {
+		details["item_id"] = params.ItemID.String()
+	}
+	s.audit.Record(ctx, audit.Event{
+		Type:    audit.EventAccessOutsideGeofence,
+		UserID:  params.UserID,
+		Details: details,
+	})
+	return fmt.Errorf("restricted item reveal rejected: %w", ErrRestrictedLocation)
+}
+
+// ListRules retrieves access rules matching the provided parameters.
+func (s *Service) ListRules(ctx context.Context, params ListRulesParams) ([]*Rule, error) {
+	rules, err := s.r.Load(ctx, repository.LoadParams{Global: params.Global, UserID: params.UserID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load access rules: %w", mapError(err))
+	}
+	return newRulesFromDomain(rules), nil
+}
+
+// AddRule creates a new access rule.
+func (s *Service) AddRule(ctx context.Context, params AddRuleParams) (*Rule, error) {
+	rule, err := accessrule.NewRule(accessrule.NewRuleParams{
+		Action:  accessrule.Action(params.Action),
+		Network: params.Network,
+		Country: params.Country,
+		UserID:  params.UserID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create new access rule: %w", mapError(err))
+	}
+	if rule.Country != "" && s.geo == nil {
+		return nil, fmt.Errorf("failed to create country rule: %w", ErrGeoIPUnavailable)
+	}
+
+	if err := s.r.Save(ctx, repository.SaveParams{Entity: rule}); err != nil {
+		return nil, fmt.Errorf("failed to save access rule: %w", mapError(err))
+	}
+
+	dto := newRuleFromDomain(rule)
+	s.audit.Record(ctx, audit.Event{
+		Type:   audit.EventAccessRuleAdded,
+		UserID: rule.UserID,
+		Details: map[string]string{
+			"rule_id": rule.ID.String(),
+			"action":  dto.Action,
+			"network": dto.Network,
+			"country": dto.Country,
+		},
+	})
+	return dto, nil
+}
+
+// DeleteRule removes an access rule.
+func (s *Service) DeleteRule(ctx context.Context, params DeleteRuleParams) error {
+	if err := s.r.Delete(ctx, repository.DeleteParams{ID: params.ID}); err != nil {
+		return fmt.Errorf("failed to delete access rule: %w", mapError(err))
+	}
+
+	s.audit.Record(ctx, audit.Event{
+		Type:    audit.EventAccessRuleDeleted,
+		Details: map[string]string{"rule_id": params.ID.String()},
+	})
+	return nil
+}
+
+// country resolves the client country when any rule matches by country.
+func (s *Service) country(ip netip.Addr, rules []*accessrule.Rule) string {
+	if s.geo == nil || !ip.IsValid() {
+		return ""
+	}
+	for _, r := range rules {
+		if r.Country != "" {
+			country, err := s.geo.Country(ip)
+			if err != nil {
+				return ""
+			}
+			return country
+		}
+	}
+	return ""
+}
+
+// evaluate applies the rules to the client and returns the denial reason, or an empty string
+// when access is permitted.
+func evaluate(rules []*accessrule.Rule, ip netip.Addr, country string) string {
+	hasAllow, allowed := false, false
+	for _, r := range rules {
+		matches := r.Matches(ip, country)
+		switch r.Action {
+		case accessrule.ActionDeny:
+			if matches {
+				return reasonDenylist
+			}
+		case accessrule.ActionAllow:
+			hasAllow = true
+			allowed = allowed || matches
+		}
+	}
+	if hasAllow && !allowed {
+		return reasonAllowlist
+	}
+	return ""
+}
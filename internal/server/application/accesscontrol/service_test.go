@@ -0,0 +1,464 @@
+package accesscontrol
+
+import (
+	"context"
+	"errors"
+	"net/netip"
+	"testing"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/audit"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/accessrule"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/itemtag"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/accessrule"
+	tagRepository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/itemtag"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockRepository implements Repository for testing.
+type mockRepository struct {
+	loadErr    error
+	saveErr    error
+	deleteErr  error
+	saved      *accessrule.Rule
+	loadParams repository.LoadParams
+	rules      []*accessrule.Rule
+}
+
+func (m *mockRepository) Save(_ context.Context, params repository.SaveParams) error {
+	m.saved = params.Entity
+	return m.saveErr
+}
+
+func (m *mockRepository) Load(_ context.Context, params repository.LoadParams) ([]*accessrule.Rule, error) {
+	m.loadParams = params
+	return m.rules, m.loadErr
+}
+
+func (m *mockRepository) Delete(context.Context, repository.DeleteParams) error {
+	return m.deleteErr
+}
+
+// mockCountryResolver implements CountryResolver for testing.
+type mockCountryResolver struct {
+	err       error
+	countries map[string]string
+	calls     int
+}
+
+func (m *mockCountryResolver) Country(ip netip.Addr) (string, error) {
+	m.calls++
+	return m.countries[ip.String()], m.err
+}
+
+// mockTagRepository implements TagRepository for testing.
+type mockTagRepository struct {
+	err        error
+	restricted map[uuid.UUID]bool
+	params     []tagRepository.ExistsParams
+}
+
+func (m *mockTagRepository) Exists(_ context.Context, params tagRepository.ExistsParams) (bool, error) {
+	m.params = append(m.params, params)
+	if params.ItemID == uuid.Nil {
+		return len(m.restricted) != 0, m.err
+	}
+	return m.restricted[params.ItemID], m.err
+}
+
+// mockAuditRecorder implements AuditRecorder for testing.
+type mockAuditRecorder struct {
+	events []audit.Event
+}
+
+func (m *mockAuditRecorder) Record(_ context.Context, e audit.Event) {
+	m.events = append(m.events, e)
+}
+
+func networkRule(action accessrule.Action, cidr string) *accessrule.Rule {
+	return &accessrule.Rule{Action: action, Network: netip.MustParsePrefix(cidr)}
+}
+
+func countryRule(action accessrule.Action, country string) *accessrule.Rule {
+	return &accessrule.Rule{Action: action, Country: country}
+}
+
+func TestService_Check(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	geo := map[string]string{"203.0.113.7": "DE", "198.51.100.7": "RU"}
+
+	tests := []struct {
+		geoErr     error
+		wantErr    error
+		name       string
+		ip         string
+		wantReason string
+		rules      []*accessrule.Rule
+		noGeo      bool
+		userID     uuid.UUID
+	}{
+		{
+			name: "no rules",
+			ip:   "203.0.113.7",
+		},
+		{
+			name:       "denied network",
+			ip:         "10.1.2.3",
+			rules:      []*accessrule.Rule{networkRule(accessrule.ActionDeny, "10.0.0.0/8")},
+			wantErr:    ErrAccessDenied,
+			wantReason: reasonDenylist,
+		},
+		{
+			name:  "address outside denied network",
+			ip:    "203.0.113.7",
+			rules: []*accessrule.Rule{networkRule(accessrule.ActionDeny, "10.0.0.0/8")},
+		},
+		{
+			name: "allowlisted network",
+			ip:   "192.168.1.5",
+			rules: []*accessrule.Rule{
+				networkRule(accessrule.ActionAllow, "10.0.0.0/8"),
+				networkRule(accessrule.ActionAllow, "192.168.0.0/16"),
+			},
+		},
+		{
+			name:       "address outside allowlist",
+			ip:         "203.0.113.7",
+			rules:      []*accessrule.Rule{networkRule(accessrule.ActionAllow, "10.0.0.0/8")},
+			wantErr:    ErrAccessDenied,
+			wantReason: reasonAllowlist,
+			userID:     userID,
+		},
+		{
+			name: "deny takes precedence over allow",
+			ip:   "10.6.6.6",
+			rules: []*accessrule.Rule{
+				networkRule(accessrule.ActionAllow, "10.0.0.0/8"),
+				networkRule(accessrule.ActionDeny, "10.6.0.0/16"),
+			},
+			wantErr:    ErrAccessDenied,
+			wantReason: reasonDenylist,
+		},
+		{
+			name:       "blocked country",
+			ip:         "198.51.100.7",
+			rules:      []*accessrule.Rule{countryRule(accessrule.ActionDeny, "RU")},
+			wantErr:    ErrAccessDenied,
+			wantReason: reasonDenylist,
+		},
+		{
+			name:  "allowed country",
+			ip:    "203.0.113.7",
+			rules: []*accessrule.Rule{countryRule(accessrule.ActionAllow, "DE")},
+		},
+		{
+			name:   "country lookup failure matches no country rule",
+			ip:     "198.51.100.7",
+			rules:  []*accessrule.Rule{countryRule(accessrule.ActionDeny, "RU")},
+			geoErr: errors.New("corrupt record"),
+		},
+		{
+			name:  "country rules ignored without geoip",
+			ip:    "198.51.100.7",
+			rules: []*accessrule.Rule{countryRule(accessrule.ActionDeny, "RU")},
+			noGeo: true,
+		},
+		{
+			name:       "unknown client address fails allowlist",
+			rules:      []*accessrule.Rule{networkRule(accessrule.ActionAllow, "10.0.0.0/8")},
+			wantErr:    ErrAccessDenied,
+			wantReason: reasonAllowlist,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &mockRepository{rules: tt.rules}
+			recorder := &mockAuditRecorder{}
+			var resolver CountryResolver
+			if !tt.noGeo {
+				resolver = &mockCountryResolver{countries: geo, err: tt.geoErr}
+			}
+			s := NewService(repo, resolver, nil, accessrule.Geofence{}, recorder)
+
+			var ip netip.Addr
+			if tt.ip != "" {
+				ip = netip.MustParseAddr(tt.ip)
+			}
+			err := s.Check(context.Background(), CheckParams{IP: ip, UserID: tt.userID})
+
+			assert.Equal(t, repository.LoadParams{Global: tt.userID == uuid.Nil, UserID: tt.userID}, repo.loadParams)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+				assert.Empty(t, recorder.events)
+				return
+			}
+			require.ErrorIs(t, err, tt.wantErr)
+			require.Len(t, recorder.events, 1)
+			assert.Equal(t, audit.EventAccessDenied, recorder.events[0].Type)
+			assert.Equal(t, tt.userID, recorder.events[0].UserID)
+			assert.Equal(t, tt.wantReason, recorder.events[0].Details["reason"])
+		})
+	}
+}
+
+func TestService_Check_SkipsGeoLookupForNetworkRules(t *testing.T) {
+	t.Parallel()
+
+	resolver := &mockCountryResolver{}
+	repo := &mockRepository{rules: []*accessrule.Rule{networkRule(accessrule.ActionDeny, "10.0.0.0/8")}}
+	s := NewService(repo, resolver, nil, accessrule.Geofence{}, &mockAuditRecorder{})
+
+	require.NoError(t, s.Check(context.Background(), CheckParams{IP: netip.MustParseAddr("203.0.113.7")}))
+	assert.Zero(t, resolver.calls)
+}
+
+func TestService_Check_RepositoryError(t *testing.T) {
+	t.Parallel()
+
+	repo := &mockRepository{loadErr: errors.New("db down")}
+	s := NewService(repo, nil, nil, accessrule.Geofence{}, &mockAuditRecorder{})
+
+	err := s.Check(context.Background(), CheckParams{IP: netip.MustParseAddr("203.0.113.7")})
+	assert.ErrorIs(t, err, ErrAccessControlTechError)
+}
+
+func TestService_AddRule(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+
+	tests := []struct {
+		saveErr error
+		wantErr error
+		name    string
+		params  AddRuleParams
+		noGeo   bool
+	}{
+		{
+			name:   "user network rule",
+			params: AddRuleParams{Action: "allow", Network: "10.0.0.1", UserID: userID},
+		},
+		{
+			name:   "global country rule",
+			params: AddRuleParams{Action: "deny", Country: "ru"},
+		},
+		{
+			name:    "country rule without geoip",
+			params:  AddRuleParams{Action: "deny", Country: "RU"},
+			noGeo:   true,
+			wantErr: ErrGeoIPUnavailable,
+		},
+		{
+			name:    "invalid action",
+			params:  AddRuleParams{Action: "block", Network: "10.0.0.0/8"},
+			wantErr: ErrRuleIncorrectAction,
+		},
+		{
+			name:    "invalid network",
+			params:  AddRuleParams{Action: "deny", Network: "example.com"},
+			wantErr: ErrRuleIncorrectNetwork,
+		},
+		{
+			name:    "invalid country",
+			params:  AddRuleParams{Action: "deny", Country: "Russia"},
+			wantErr: ErrRuleIncorrectCountry,
+		},
+		{
+			name:    "missing target",
+			params:  AddRuleParams{Action: "deny"},
+			wantErr: ErrRuleIncorrectTarget,
+		},
+		{
+			name:    "save failure",
+			params:  AddRuleParams{Action: "deny", Network: "10.0.0.0/8"},
+			saveErr: errors.New("db down"),
+			wantErr: ErrAccessControlTechError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &mockRepository{saveErr: tt.saveErr}
+			recorder := &mockAuditRecorder{}
+			var resolver CountryResolver
+			if !tt.noGeo {
+				resolver = &mockCountryResolver{}
+			}
+			s := NewService(repo, resolver, nil, accessrule.Geofence{}, recorder)
+
+			rule, err := s.AddRule(context.Background(), tt.params)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, rule)
+				assert.Empty(t, recorder.events)
+				return
+			}
+			require.NoError(t, err)
+			require.NotNil(t, repo.saved)
+			assert.Equal(t, repo.saved.ID, rule.ID)
+			assert.Equal(t, tt.params.UserID, rule.UserID)
+			assert.Equal(t, tt.params.Action, rule.Action)
+			require.Len(t, recorder.events, 1)
+			assert.Equal(t, audit.EventAccessRuleAdded, recorder.events[0].Type)
+		})
+	}
+}
+
+func TestService_ListRules(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	repo := &mockRepository{rules: []*accessrule.Rule{
+		{
+			ID:      uuid.New(),
+			UserID:  userID,
+			Action:  accessrule.ActionAllow,
+			Network: netip.MustParsePrefix("10.0.0.0/8"),
+		},
+		{ID: uuid.New(), Action: accessrule.ActionDeny, Country: "RU"},
+	}}
+	s := NewService(repo, nil, nil, accessrule.Geofence{}, &mockAuditRecorder{})
+
+	rules, err := s.ListRules(context.Background(), ListRulesParams{UserID: userID, Global: true})
+
+	require.NoError(t, err)
+	assert.Equal(t, repository.LoadParams{UserID: userID, Global: true}, repo.loadParams)
+	require.Len(t, rules, 2)
+	assert.Equal(t, "10.0.0.0/8", rules[0].Network)
+	assert.Empty(t, rules[0].Country)
+	assert.Equal(t, "RU", rules[1].Country)
+	assert.Empty(t, rules[1].Network)
+}
+
+func TestService_DeleteRule(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		deleteErr  error
+		wantErr    error
+		name       string
+		wantEvents int
+	}{
+		{name: "deleted", wantEvents: 1},
+		{name: "not found", deleteErr: repository.ErrRuleNotFound, wantErr: ErrRuleNotFound},
+		{name: "failure", deleteErr: errors.New("db down"), wantErr: ErrAccessControlTechError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			recorder := &mockAuditRecorder{}
+			s := NewService(&mockRepository{deleteErr: tt.deleteErr}, nil, nil, accessrule.Geofence{}, recorder)
+
+			err := s.DeleteRule(context.Background(), DeleteRuleParams{ID: uuid.New()})
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Len(t, recorder.events, tt.wantEvents)
+		})
+	}
+}
+
+func TestService_CheckRestricted(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	secretID, plainID := uuid.New(), uuid.New()
+	fence := accessrule.Geofence{
+		Networks:  []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")},
+		Countries: []string{"DE"},
+	}
+	geo := map[string]string{"203.0.113.7": "DE", "198.51.100.7": "FR"}
+
+	tests := []struct {
+		tagErr      error
+		wantErr     error
+		fence       accessrule.Geofence
+		name        string
+		ip          string
+		itemID      uuid.UUID
+		wantLookups int
+	}{
+		{name: "geofence disabled", ip: "198.51.100.7", itemID: secretID},
+		{name: "inside network", fence: fence, ip: "10.1.2.3", itemID: secretID},
+		{name: "inside country", fence: fence, ip: "203.0.113.7", itemID: secretID},
+		{
+			name:        "restricted item outside",
+			fence:       fence,
+			ip:          "198.51.100.7",
+			itemID:      secretID,
+			wantErr:     ErrRestrictedLocation,
+			wantLookups: 1,
+		},
+		{name: "unrestricted item outside", fence: fence, ip: "198.51.100.7", itemID: plainID, wantLookups: 1},
+		{
+			name:        "listing with restricted items outside",
+			fence:       fence,
+			ip:          "198.51.100.7",
+			wantErr:     ErrRestrictedLocation,
+			wantLookups: 1,
+		},
+		{
+			name:        "unknown address outside",
+			fence:       fence,
+			itemID:      secretID,
+			wantErr:     ErrRestrictedLocation,
+			wantLookups: 1,
+		},
+		{
+			name:        "tag lookup failure",
+			fence:       fence,
+			ip:          "198.51.100.7",
+			itemID:      secretID,
+			tagErr:      errors.New("db down"),
+			wantErr:     ErrAccessControlTechError,
+			wantLookups: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var ip netip.Addr
+			if tt.ip != "" {
+				ip = netip.MustParseAddr(tt.ip)
+			}
+			tags := &mockTagRepository{restricted: map[uuid.UUID]bool{secretID: true}, err: tt.tagErr}
+			recorder := &mockAuditRecorder{}
+			resolver := &mockCountryResolver{countries: geo}
+			s := NewService(&mockRepository{}, resolver, tags, tt.fence, recorder)
+
+			err := s.CheckRestricted(context.Background(), RestrictedParams{IP: ip, UserID: userID, ItemID: tt.itemID})
+
+			assert.Len(t, tags.params, tt.wantLookups)
+			for _, p := range tags.params {
+				assert.Equal(t, tagRepository.ExistsParams{Tag: itemtag.Restricted, UserID: userID, ItemID: tt.itemID}, p)
+			}
+			if tt.wantErr == nil {
+				require.NoError(t, err)
+				assert.Empty(t, recorder.events)
+				return
+			}
+			require.ErrorIs(t, err, tt.wantErr)
+			if errors.Is(tt.wantErr, ErrRestrictedLocation) {
+				require.Len(t, recorder.events, 1)
+				assert.Equal(t, audit.EventAccessOutsideGeofence, recorder.events[0].Type)
+				assert.Equal(t, userID, recorder.events[0].UserID)
+			}
+		})
+	}
+}
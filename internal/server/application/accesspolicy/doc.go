@@ -0,0 +1,5 @@
+// Package accesspolicy provides time-based access policy application services for the AegisVaultKeeper server.
+//
+// This package implements enforcement of vault availability windows on item reveals and
+// management of the global, administrator-managed and user-defined policies behind them.
+package accesspolicy
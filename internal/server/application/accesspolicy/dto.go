@@ -0,0 +1,95 @@
+package accesspolicy
+
+import (
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/accesspolicy"
+	"github.com/google/uuid"
+)
+
+// Policy represents an access policy data transfer object for application layer communication.
+type Policy struct {
+	// CreatedAt indicates when the policy was created.
+	CreatedAt time.Time
+	// Start contains the HH:MM time of day the window opens at.
+	Start string
+	// End contains the HH:MM time of day the window closes at.
+	End string
+	// Timezone contains the IANA time zone the window is evaluated in.
+	Timezone string
+	// Days contains the three-letter names of the weekdays the window opens on.
+	Days []string
+	// ID uniquely identifies the policy.
+	ID uuid.UUID
+	// UserID identifies the user the policy applies to, or uuid.Nil for global policies.
+	UserID uuid.UUID
+	// Managed reports whether an administrator defined the policy.
+	Managed bool
+}
+
+// newPolicyFromDomain converts a domain access policy entity to application DTO.
+func newPolicyFromDomain(p *accesspolicy.Policy) *Policy {
+	if p == nil {
+		return nil
+	}
+	return &Policy{
+		ID:        p.ID,
+		UserID:    p.UserID,
+		Days:      p.Days.Names(),
+		Start:     accesspolicy.FormatClock(p.Start),
+		End:       accesspolicy.FormatClock(p.End),
+		Timezone:  p.Location.String(),
+		Managed:   p.Managed,
+		CreatedAt: p.CreatedAt,
+	}
+}
+
+// newPoliciesFromDomain converts a slice of domain access policy entities to application DTOs.
+func newPoliciesFromDomain(ps []*accesspolicy.Policy) []*Policy {
+	result := make([]*Policy, 0, len(ps))
+	for _, p := range ps {
+		result = append(result, newPolicyFromDomain(p))
+	}
+	return result
+}
+
+// CheckParams contains parameters for checking whether items may be revealed.
+type CheckParams struct {
+	// At contains the moment of the reveal.
+	At time.Time
+	// UserID identifies the user revealing items; global policies always apply.
+	UserID uuid.UUID
+}
+
+// ListParams contains parameters for listing access policies.
+// When neither Global nor UserID is set, all policies are listed.
+type ListParams struct {
+	// UserID selects the policies of the specified user.
+	UserID uuid.UUID
+	// Global selects the policies applying to all users.
+	Global bool
+}
+
+// AddParams contains parameters for creating an access policy.
+type AddParams struct {
+	// Start contains the HH:MM time of day the window opens at.
+	Start string
+	// End contains the HH:MM time of day the window closes at, 24:00 for midnight.
+	End string
+	// Timezone contains the IANA time zone of the window; empty means UTC.
+	Timezone string
+	// Days contains the three-letter names of the weekdays the window opens on.
+	Days []string
+	// UserID identifies the user the policy applies to, or uuid.Nil for a global policy.
+	UserID uuid.UUID
+	// Managed reports whether an administrator defines the policy.
+	Managed bool
+}
+
+// DeleteParams contains parameters for deleting an access policy.
+type DeleteParams struct {
+	// ID identifies the policy to delete.
+	ID uuid.UUID
+	// UserID restricts the deletion to the policies the user defined; uuid.Nil deletes any policy.
+	UserID uuid.UUID
+}
@@ -0,0 +1,67 @@
+package accesspolicy
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/errutil"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/accesspolicy"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/accesspolicy"
+)
+
+// Access policy error definitions.
+var (
+	// ErrAccessPolicyAppError indicates a general access policy application error.
+	ErrAccessPolicyAppError = errors.New("access policy application error")
+
+	// ErrAccessPolicyTechError indicates a technical error in the access policy system.
+	ErrAccessPolicyTechError = errors.New("access policy technical error")
+
+	// ErrOutsideAccessWindow indicates that items cannot be revealed at this time.
+	ErrOutsideAccessWindow = errors.New("vault is outside its access window")
+
+	// ErrPolicyIncorrectDays indicates no or unknown weekdays were provided.
+	ErrPolicyIncorrectDays = errors.New("incorrect access policy days")
+
+	// ErrPolicyIncorrectTime indicates an incorrect window start or end was provided.
+	ErrPolicyIncorrectTime = errors.New("incorrect access policy time")
+
+	// ErrPolicyIncorrectTimezone indicates an unknown time zone was provided.
+	ErrPolicyIncorrectTimezone = errors.New("incorrect access policy timezone")
+
+	// ErrPolicyNotFound indicates the requested access policy was not found.
+	ErrPolicyNotFound = errors.New("access policy not found")
+)
+
+// mapError maps domain and repository errors to application-level errors.
+func mapError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	mapped := errutil.MapError(mapFn, err)
+	if mapped != nil {
+		return fmt.Errorf("access policy error mapping failed: %w", mapped)
+	}
+	return nil
+}
+
+// mapFn provides the actual error mapping logic for different error types.
+func mapFn(err error) error {
+	switch {
+	case errors.Is(err, accesspolicy.ErrNewPolicyParamsValidation):
+		return ErrAccessPolicyAppError
+	case errors.Is(err, accesspolicy.ErrIncorrectDays):
+		return ErrPolicyIncorrectDays
+	case errors.Is(err, accesspolicy.ErrIncorrectTime):
+		return ErrPolicyIncorrectTime
+	case errors.Is(err, accesspolicy.ErrIncorrectTimezone):
+		return ErrPolicyIncorrectTimezone
+	case errors.Is(err, accesspolicy.ErrIncorrectOwner):
+		return ErrAccessPolicyAppError
+	case errors.Is(err, repository.ErrPolicyNotFound):
+		return ErrPolicyNotFound
+	default:
+		return errors.Join(ErrAccessPolicyTechError, err)
+	}
+}
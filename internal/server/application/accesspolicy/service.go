@@ -0,0 +1,114 @@
+package accesspolicy
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/audit"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/accesspolicy"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/accesspolicy"
+)
+
+// Repository defines the interface for access policy persistence operations.
+type Repository interface {
+	// Save persists an access policy using the provided parameters.
+	Save(ctx context.Context, params repository.SaveParams) error
+	// Load retrieves access policies using the provided parameters.
+	Load(ctx context.Context, params repository.LoadParams) ([]*accesspolicy.Policy, error)
+	// Delete removes an access policy using the provided parameters.
+	Delete(ctx context.Context, params repository.DeleteParams) error
+}
+
+// AuditRecorder defines the interface for recording security audit events.
+type AuditRecorder interface {
+	// Record stores the audit event.
+	Record(ctx context.Context, e audit.Event)
+}
+
+// Service provides time-based access policy operations.
+type Service struct {
+	// r is the repository interface for access policy persistence operations.
+	r Repository
+	// audit records rejected reveals and policy changes.
+	audit AuditRecorder
+}
+
+// NewService creates a new access policy service instance.
+func NewService(r Repository, audit AuditRecorder) *Service {
+	return &Service{r: r, audit: audit}
+}
+
+// CheckReveal verifies that the access windows of the user are open at the moment of the reveal.
+// Global policies and the policies of the user apply; see accesspolicy.Allows for how they combine.
+func (s *Service) CheckReveal(ctx context.Context, params CheckParams) error {
+	policies, err := s.r.Load(ctx, repository.LoadParams{Global: true, UserID: params.UserID})
+	if err != nil {
+		return fmt.Errorf("failed to load access policies: %w", mapError(err))
+	}
+	if accesspolicy.Allows(policies, params.At) {
+		return nil
+	}
+
+	s.audit.Record(ctx, audit.Event{
+		Type:   audit.EventAccessOutsideWindow,
+		UserID: params.UserID,
+	})
+	return fmt.Errorf("reveal rejected: %w", ErrOutsideAccessWindow)
+}
+
+// ListPolicies retrieves access policies matching the provided parameters.
+func (s *Service) ListPolicies(ctx context.Context, params ListParams) ([]*Policy, error) {
+	policies, err := s.r.Load(ctx, repository.LoadParams{Global: params.Global, UserID: params.UserID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load access policies: %w", mapError(err))
+	}
+	return newPoliciesFromDomain(policies), nil
+}
+
+// AddPolicy creates a new access policy.
+func (s *Service) AddPolicy(ctx context.Context, params AddParams) (*Policy, error) {
+	policy, err := accesspolicy.NewPolicy(accesspolicy.NewPolicyParams{
+		Days:     params.Days,
+		Start:    params.Start,
+		End:      params.End,
+		Timezone: params.Timezone,
+		UserID:   params.UserID,
+		Managed:  params.Managed,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create new access policy: %w", mapError(err))
+	}
+
+	if err := s.r.Save(ctx, repository.SaveParams{Entity: policy}); err != nil {
+		return nil, fmt.Errorf("failed to save access policy: %w", mapError(err))
+	}
+
+	dto := newPolicyFromDomain(policy)
+	s.audit.Record(ctx, audit.Event{
+		Type:   audit.EventAccessPolicyAdded,
+		UserID: policy.UserID,
+		Details: map[string]string{
+			"policy_id": policy.ID.String(),
+			"days":      strings.Join(dto.Days, ","),
+			"window":    dto.Start + "-" + dto.End,
+			"timezone":  dto.Timezone,
+			"managed":   fmt.Sprint(policy.Managed),
+		},
+	})
+	return dto, nil
+}
+
+// DeletePolicy removes an access policy. Users can remove only the policies they defined.
+func (s *Service) DeletePolicy(ctx context.Context, params DeleteParams) error {
+	if err := s.r.Delete(ctx, repository.DeleteParams{ID: params.ID, UserID: params.UserID}); err != nil {
+		return fmt.Errorf("failed to delete access policy: %w", mapError(err))
+	}
+
+	s.audit.Record(ctx, audit.Event{
+		Type:    audit.EventAccessPolicyDeleted,
+		UserID:  params.UserID,
+		Details: map[string]string{"policy_id": params.ID.String()},
+	})
+	return nil
+}
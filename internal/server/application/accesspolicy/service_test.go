@@ -0,0 +1,250 @@
+package accesspolicy
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/audit"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/accesspolicy"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/accesspolicy"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockRepository implements Repository for testing.
+type mockRepository struct {
+	loadErr      error
+	saveErr      error
+	deleteErr    error
+	saved        *accesspolicy.Policy
+	loadParams   repository.LoadParams
+	deleteParams repository.DeleteParams
+	policies     []*accesspolicy.Policy
+}
+
+func (m *mockRepository) Save(_ context.Context, params repository.SaveParams) error {
+	m.saved = params.Entity
+	return m.saveErr
+}
+
+func (m *mockRepository) Load(_ context.Context, params repository.LoadParams) ([]*accesspolicy.Policy, error) {
+	m.loadParams = params
+	return m.policies, m.loadErr
+}
+
+func (m *mockRepository) Delete(_ context.Context, params repository.DeleteParams) error {
+	m.deleteParams = params
+	return m.deleteErr
+}
+
+// mockAuditRecorder implements AuditRecorder for testing.
+type mockAuditRecorder struct {
+	events []audit.Event
+}
+
+func (m *mockAuditRecorder) Record(_ context.Context, e audit.Event) {
+	m.events = append(m.events, e)
+}
+
+// workHours returns a policy open on weekdays from 09:00 to 18:00 UTC.
+func workHours(managed bool) *accesspolicy.Policy {
+	return &accesspolicy.Policy{Days: 0b0111110, Start: 9 * 60, End: 18 * 60, Location: time.UTC, Managed: managed}
+}
+
+func TestService_CheckReveal(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	wednesdayNoon := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+	saturdayNoon := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		at       time.Time
+		loadErr  error
+		wantErr  error
+		name     string
+		policies []*accesspolicy.Policy
+	}{
+		{name: "no policies", at: saturdayNoon},
+		{name: "inside window", at: wednesdayNoon, policies: []*accesspolicy.Policy{workHours(true)}},
+		{
+			name:     "outside window",
+			at:       saturdayNoon,
+			policies: []*accesspolicy.Policy{workHours(false)},
+			wantErr:  ErrOutsideAccessWindow,
+		},
+		{
+			name:    "repository failure",
+			at:      wednesdayNoon,
+			loadErr: errors.New("db down"),
+			wantErr: ErrAccessPolicyTechError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &mockRepository{policies: tt.policies, loadErr: tt.loadErr}
+			recorder := &mockAuditRecorder{}
+			s := NewService(repo, recorder)
+
+			err := s.CheckReveal(context.Background(), CheckParams{At: tt.at, UserID: userID})
+
+			assert.Equal(t, repository.LoadParams{Global: true, UserID: userID}, repo.loadParams)
+			if tt.wantErr == nil {
+				require.NoError(t, err)
+				assert.Empty(t, recorder.events)
+				return
+			}
+			require.ErrorIs(t, err, tt.wantErr)
+			if errors.Is(tt.wantErr, ErrOutsideAccessWindow) {
+				require.Len(t, recorder.events, 1)
+				assert.Equal(t, audit.EventAccessOutsideWindow, recorder.events[0].Type)
+				assert.Equal(t, userID, recorder.events[0].UserID)
+			}
+		})
+	}
+}
+
+func TestService_AddPolicy(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+
+	tests := []struct {
+		saveErr error
+		wantErr error
+		name    string
+		params  AddParams
+	}{
+		{
+			name: "user policy",
+			params: AddParams{
+				Days:     []string{"mon", "fri"},
+				Start:    "08:00",
+				End:      "20:00",
+				Timezone: "Europe/Berlin",
+				UserID:   userID,
+			},
+		},
+		{
+			name:   "global managed policy",
+			params: AddParams{Days: []string{"sat"}, Start: "22:00", End: "06:00", Managed: true},
+		},
+		{
+			name:    "invalid days",
+			params:  AddParams{Days: []string{"someday"}, Start: "08:00", End: "20:00", UserID: userID},
+			wantErr: ErrPolicyIncorrectDays,
+		},
+		{
+			name:    "invalid time",
+			params:  AddParams{Days: []string{"mon"}, Start: "25:00", End: "20:00", UserID: userID},
+			wantErr: ErrPolicyIncorrectTime,
+		},
+		{
+			name: "invalid timezone",
+			params: AddParams{
+				Days:     []string{"mon"},
+				Start:    "08:00",
+				End:      "20:00",
+				Timezone: "CEST",
+				UserID:   userID,
+			},
+			wantErr: ErrPolicyIncorrectTimezone,
+		},
+		{
+			name:    "save failure",
+			params:  AddParams{Days: []string{"mon"}, Start: "08:00", End: "20:00", UserID: userID},
+			saveErr: errors.New("db down"),
+			wantErr: ErrAccessPolicyTechError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &mockRepository{saveErr: tt.saveErr}
+			recorder := &mockAuditRecorder{}
+			s := NewService(repo, recorder)
+
+			policy, err := s.AddPolicy(context.Background(), tt.params)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, policy)
+				assert.Empty(t, recorder.events)
+				return
+			}
+			require.NoError(t, err)
+			require.NotNil(t, repo.saved)
+			assert.Equal(t, repo.saved.ID, policy.ID)
+			assert.Equal(t, tt.params.UserID, policy.UserID)
+			assert.Equal(t, tt.params.Days, policy.Days)
+			assert.Equal(t, tt.params.Start, policy.Start)
+			assert.Equal(t, tt.params.End, policy.End)
+			assert.Equal(t, tt.params.Managed, policy.Managed)
+			require.Len(t, recorder.events, 1)
+			assert.Equal(t, audit.EventAccessPolicyAdded, recorder.events[0].Type)
+		})
+	}
+}
+
+func TestService_ListPolicies(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	repo := &mockRepository{policies: []*accesspolicy.Policy{workHours(true)}}
+	s := NewService(repo, &mockAuditRecorder{})
+
+	policies, err := s.ListPolicies(context.Background(), ListParams{UserID: userID, Global: true})
+
+	require.NoError(t, err)
+	assert.Equal(t, repository.LoadParams{UserID: userID, Global: true}, repo.loadParams)
+	require.Len(t, policies, 1)
+	assert.Equal(t, []string{"mon", "tue", "wed", "thu", "fri"}, policies[0].Days)
+	assert.Equal(t, "09:00", policies[0].Start)
+	assert.Equal(t, "18:00", policies[0].End)
+	assert.Equal(t, "UTC", policies[0].Timezone)
+	assert.True(t, policies[0].Managed)
+}
+
+func TestService_DeletePolicy(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		deleteErr  error
+		wantErr    error
+		name       string
+		wantEvents int
+	}{
+		{name: "deleted", wantEvents: 1},
+		{name: "not found", deleteErr: repository.ErrPolicyNotFound, wantErr: ErrPolicyNotFound},
+		{name: "failure", deleteErr: errors.New("db down"), wantErr: ErrAccessPolicyTechError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &mockRepository{deleteErr: tt.deleteErr}
+			recorder := &mockAuditRecorder{}
+			s := NewService(repo, recorder)
+			params := DeleteParams{ID: uuid.New(), UserID: uuid.New()}
+
+			err := s.DeletePolicy(context.Background(), params)
+
+			assert.Equal(t, repository.DeleteParams{ID: params.ID, UserID: params.UserID}, repo.deleteParams)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Len(t, recorder.events, tt.wantEvents)
+		})
+	}
+}
@@ -0,0 +1,5 @@
+// Package acmeaccount provides ACME account management application services for the AegisVaultKeeper server.
+//
+// This package implements business logic for storing ACME account keys together with the DNS provider
+// credentials of DNS-01 challenges, and tells the automation hooks of accounts when they change.
+package acmeaccount
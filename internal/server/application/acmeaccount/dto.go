@@ -0,0 +1,105 @@
+package acmeaccount
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/acmeaccount"
+	"github.com/google/uuid"
+)
+
+// Account represents an ACME account data transfer object for application layer communication.
+type Account struct {
+	// UpdatedAt indicates when the account was last modified.
+	UpdatedAt time.Time
+	// DNSCredentials contains the DNS provider credentials by name.
+	DNSCredentials map[string]string
+	// DirectoryURL contains the directory URL of the certificate authority.
+	DirectoryURL string
+	// Email contains the contact e-mail of the account.
+	Email string
+	// AccountKey contains the PEM-encoded private key of the account.
+	AccountKey string
+	// DNSProvider contains the name of the DNS provider answering DNS-01 challenges.
+	DNSProvider string
+	// HookURL contains the URL of the automation hook told about account changes.
+	HookURL string
+	// Description contains additional information about the account.
+	Description string
+	// ID uniquely identifies the account.
+	ID uuid.UUID
+	// UserID identifies the account owner.
+	UserID uuid.UUID
+}
+
+// newAccountFromDomain converts a domain ACME account entity to application DTO.
+func newAccountFromDomain(a *acmeaccount.Account) (*Account, error) {
+	if a == nil {
+		return nil, nil
+	}
+	credentials, err := a.Credentials()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ACME account %s: %w", a.ID, err)
+	}
+	return &Account{
+		ID:             a.ID,
+		UserID:         a.UserID,
+		DirectoryURL:   string(a.DirectoryURL),
+		Email:          string(a.Email),
+		AccountKey:     string(a.AccountKey),
+		DNSProvider:    string(a.DNSProvider),
+		DNSCredentials: credentials,
+		HookURL:        string(a.HookURL),
+		Description:    string(a.Description),
+		UpdatedAt:      a.UpdatedAt,
+	}, nil
+}
+
+// newAccountsFromDomain converts a slice of domain ACME account entities to application DTOs.
+func newAccountsFromDomain(as []*acmeaccount.Account) ([]*Account, error) {
+	result := make([]*Account, 0, len(as))
+	for _, a := range as {
+		account, err := newAccountFromDomain(a)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, account)
+	}
+	return result, nil
+}
+
+// PullParams contains parameters for retrieving a specific ACME account.
+type PullParams struct {
+	// ID specifies the account to retrieve.
+	ID uuid.UUID
+	// UserID specifies the account owner.
+	UserID uuid.UUID
+}
+
+// ListParams contains parameters for listing user ACME accounts.
+type ListParams struct {
+	// UserID specifies the account owner.
+	UserID uuid.UUID
+}
+
+// PushParams contains parameters for creating or updating an ACME account.
+type PushParams struct {
+	// DNSCredentials specifies the DNS provider credentials by name.
+	DNSCredentials map[string]string
+	// DirectoryURL specifies the directory URL of the certificate authority.
+	DirectoryURL string
+	// Email specifies the contact e-mail of the account.
+	Email string
+	// AccountKey specifies the PEM-encoded private key of the account.
+	AccountKey string
+	// DNSProvider specifies the name of the DNS provider.
+	DNSProvider string
+	// HookURL specifies the URL of the automation hook told about account changes.
+	HookURL string
+	// Description provides additional information about the account.
+	Description string
+	// ID uniquely identifies the account.
+	ID uuid.UUID
+	// UserID identifies the account owner.
+	UserID uuid.UUID
+}
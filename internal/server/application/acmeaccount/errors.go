@@ -0,0 +1,82 @@
+package acmeaccount
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/errutil"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/acmeaccount"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/fieldcrypt"
+)
+
+// ACME account error definitions.
+var (
+	// ErrACMEAppError indicates a general ACME account application error.
+	ErrACMEAppError = errors.New("ACME account application error")
+
+	// ErrACMETechError indicates a technical error in the ACME account system.
+	ErrACMETechError = errors.New("ACME account technical error")
+
+	// ErrACMEIncorrectDirectoryURL indicates an incorrect directory URL was provided.
+	ErrACMEIncorrectDirectoryURL = errors.New("incorrect ACME directory URL")
+
+	// ErrACMEIncorrectEmail indicates an incorrect contact e-mail was provided.
+	ErrACMEIncorrectEmail = errors.New("incorrect ACME account e-mail")
+
+	// ErrACMEIncorrectAccountKey indicates an incorrect account key was provided.
+	ErrACMEIncorrectAccountKey = errors.New("incorrect ACME account key")
+
+	// ErrACMEIncorrectDNSProvider indicates an incorrect DNS provider was provided.
+	ErrACMEIncorrectDNSProvider = errors.New("incorrect DNS provider")
+
+	// ErrACMEIncorrectDNSCredentials indicates incorrect DNS credentials were provided.
+	ErrACMEIncorrectDNSCredentials = errors.New("incorrect DNS credentials")
+
+	// ErrACMEIncorrectHookURL indicates an incorrect automation hook URL was provided.
+	ErrACMEIncorrectHookURL = errors.New("incorrect automation hook URL")
+
+	// ErrACMENotFound indicates the requested ACME account was not found.
+	ErrACMENotFound = errors.New("ACME account not found")
+
+	// ErrACMEAccessDenied indicates access to the ACME account is not permitted.
+	ErrACMEAccessDenied = errors.New("access to this ACME account is denied")
+
+	// ErrACMEIntegrityViolation indicates stored ACME account data failed ownership integrity verification.
+	ErrACMEIntegrityViolation = errors.New("ACME account data integrity violation")
+)
+
+// mapError maps domain and repository errors to application-level errors.
+func mapError(err error) error {
+	if err == nil {
+		return nil
+	}
+	mapped := errutil.MapError(mapFn, err)
+	if mapped != nil {
+		return fmt.Errorf("ACME account error mapping failed: %w", mapped)
+	}
+	return nil
+}
+
+// mapFn provides the actual error mapping logic for different error types.
+func mapFn(err error) error {
+	switch {
+	case errors.Is(err, acmeaccount.ErrNewAccountParamsValidation):
+		return ErrACMEAppError
+	case errors.Is(err, acmeaccount.ErrIncorrectDirectoryURL):
+		return ErrACMEIncorrectDirectoryURL
+	case errors.Is(err, acmeaccount.ErrIncorrectEmail):
+		return ErrACMEIncorrectEmail
+	case errors.Is(err, acmeaccount.ErrIncorrectAccountKey):
+		return ErrACMEIncorrectAccountKey
+	case errors.Is(err, acmeaccount.ErrIncorrectDNSProvider):
+		return ErrACMEIncorrectDNSProvider
+	case errors.Is(err, acmeaccount.ErrIncorrectDNSCredentials):
+		return ErrACMEIncorrectDNSCredentials
+	case errors.Is(err, acmeaccount.ErrIncorrectHookURL):
+		return ErrACMEIncorrectHookURL
+	case errors.Is(err, fieldcrypt.ErrIntegrityViolation):
+		return errors.Join(ErrACMEIntegrityViolation, err)
+	default:
+		return errors.Join(ErrACMETechError, err)
+	}
+}
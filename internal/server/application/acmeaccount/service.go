@@ -0,0 +1,173 @@
+package acmeaccount
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	authzApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/authz"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/audit"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/acmeaccount"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/authz"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/acmeaccount"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/securebytes"
+	"github.com/google/uuid"
+)
+
+// Repository defines the interface for ACME account data persistence operations.
+type Repository interface {
+	// Save persists an ACME account entity using the provided parameters.
+	Save(ctx context.Context, params repository.SaveParams) error
+
+	// Load retrieves ACME account entities using the provided parameters.
+	Load(ctx context.Context, params repository.LoadParams) ([]*acmeaccount.Account, error)
+}
+
+// Authorizer defines the interface for the authorization decision point.
+type Authorizer interface {
+	// Authorize decides whether the user may perform the action on the ACME account.
+	Authorize(ctx context.Context, req authzApp.Request) error
+}
+
+// Hook defines the interface for the automation hooks of ACME accounts.
+type Hook interface {
+	// AccountChanged tells the hook at the target URL that the account changed. The call carries no secrets;
+	// certificate renewal tooling fetches the account through the lease API.
+	AccountChanged(ctx context.Context, target string, accountID uuid.UUID, updatedAt time.Time) error
+}
+
+// AuditRecorder defines the interface for recording security audit events.
+type AuditRecorder interface {
+	// Record stores the audit event.
+	Record(ctx context.Context, e audit.Event)
+}
+
+// Service provides ACME account management business logic operations.
+type Service struct {
+	// r is the repository interface for ACME account data persistence operations.
+	r Repository
+	// authorizer decides whether users may perform actions on ACME accounts.
+	authorizer Authorizer
+	// hook tells the automation hooks of accounts about changes.
+	hook Hook
+	// audit records hook failures.
+	audit AuditRecorder
+}
+
+// NewService creates a new ACME account service instance.
+func NewService(r Repository, authorizer Authorizer, hook Hook, audit AuditRecorder) *Service {
+	return &Service{r: r, authorizer: authorizer, hook: hook, audit: audit}
+}
+
+// Pull retrieves a specific ACME account for the given user.
+func (s *Service) Pull(ctx context.Context, params PullParams) (*Account, error) {
+	accounts, err := s.r.Load(ctx, repository.LoadParams{ID: params.ID, UserID: params.UserID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load ACME accounts: %w", mapError(err))
+	}
+	defer securebytes.WipeAll(accounts)
+	if len(accounts) == 0 {
+		return nil, fmt.Errorf("ACME account not found: %w", ErrACMENotFound)
+	}
+	if err := s.authorize(ctx, authz.ActionRead, accounts[0].ID, accounts[0].UserID, params.UserID); err != nil {
+		return nil, err
+	}
+	account, err := newAccountFromDomain(accounts[0])
+	if err != nil {
+		return nil, mapError(err)
+	}
+	return account, nil
+}
+
+// List retrieves all ACME accounts for the specified user.
+func (s *Service) List(ctx context.Context, params ListParams) ([]*Account, error) {
+	if err := s.authorize(ctx, authz.ActionRead, uuid.Nil, params.UserID, params.UserID); err != nil {
+		return nil, err
+	}
+	accounts, err := s.r.Load(ctx, repository.LoadParams{UserID: params.UserID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load ACME accounts: %w", mapError(err))
+	}
+	defer securebytes.WipeAll(accounts)
+	result, err := newAccountsFromDomain(accounts)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	return result, nil
+}
+
+// Push creates or updates an ACME account for the specified user and tells its automation hook about it.
+// A failing hook does not fail the push; it is recorded in the audit trail instead.
+func (s *Service) Push(ctx context.Context, params *PushParams) (uuid.UUID, error) {
+	a, err := acmeaccount.NewAccount(acmeaccount.NewAccountParams{
+		UserID:         params.UserID,
+		DirectoryURL:   params.DirectoryURL,
+		Email:          params.Email,
+		AccountKey:     params.AccountKey,
+		DNSProvider:    params.DNSProvider,
+		DNSCredentials: params.DNSCredentials,
+		HookURL:        params.HookURL,
+		Description:    params.Description,
+	})
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to create new ACME account: %w", mapError(err))
+	}
+	defer a.Wipe()
+
+	if params.ID != uuid.Nil {
+		if err := s.checkAccessToUpdate(ctx, params.ID, params.UserID); err != nil {
+			return uuid.Nil, fmt.Errorf("access check for updating ACME account failed: %w", err)
+		}
+		a.ID = params.ID
+	} else if err := s.authorize(ctx, authz.ActionWrite, uuid.Nil, params.UserID, params.UserID); err != nil {
+		return uuid.Nil, fmt.Errorf("access check for creating ACME account failed: %w", err)
+	}
+
+	if err := s.r.Save(ctx, repository.SaveParams{Entity: a}); err != nil {
+		return uuid.Nil, fmt.Errorf("failed to save ACME account: %w", mapError(err))
+	}
+	if params.HookURL != "" {
+		s.notifyHook(ctx, params.HookURL, a)
+	}
+	return a.ID, nil
+}
+
+// notifyHook tells the automation hook of the account about the change and audits a failure.
+func (s *Service) notifyHook(ctx context.Context, target string, a *acmeaccount.Account) {
+	if err := s.hook.AccountChanged(ctx, target, a.ID, a.UpdatedAt); err != nil {
+		s.audit.Record(ctx, audit.Event{
+			Type:    audit.EventACMEHookFailed,
+			UserID:  a.UserID,
+			Details: map[string]string{"account_id": a.ID.String(), "error": err.Error()},
+		})
+	}
+}
+
+// checkAccessToUpdate verifies that the ACME account to update exists and that the user may change it.
+func (s *Service) checkAccessToUpdate(ctx context.Context, accountID, userID uuid.UUID) error {
+	existing, err := s.r.Load(ctx, repository.LoadParams{ID: accountID, UserID: userID})
+	if err != nil {
+		return fmt.Errorf("failed to pull existing ACME account: %w", mapError(err))
+	}
+	defer securebytes.WipeAll(existing)
+	if len(existing) == 0 {
+		return fmt.Errorf("ACME account for update not found: %w", ErrACMENotFound)
+	}
+	return s.authorize(ctx, authz.ActionWrite, existing[0].ID, existing[0].UserID, userID)
+}
+
+// authorize asks the authorization decision point whether the user may perform the action on an ACME account
+// of the owner. The accountID is uuid.Nil for new accounts and for the whole collection.
+func (s *Service) authorize(ctx context.Context, action string, accountID, ownerID, userID uuid.UUID) error {
+	err := s.authorizer.Authorize(ctx, authzApp.Request{
+		Action:     action,
+		Kind:       authz.KindACME,
+		ResourceID: accountID,
+		OwnerID:    ownerID,
+		SubjectID:  userID,
+	})
+	if err != nil {
+		return fmt.Errorf("access denied to ACME account %s: %w: %w", accountID, ErrACMEAccessDenied, err)
+	}
+	return nil
+}
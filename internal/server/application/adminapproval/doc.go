@@ -0,0 +1,6 @@
+// Package adminapproval provides dual-control application services for the AegisVaultKeeper server.
+//
+// This package implements separation of duties for destructive admin requests: a request initiated by one
+// operator is queued and the other operators are notified, a second operator approves or rejects it, and
+// the initiator executes the approved request once before the approval expires.
+package adminapproval
@@ -0,0 +1,108 @@
+package adminapproval
+
+import (
+	"encoding/hex"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/adminapproval"
+	"github.com/google/uuid"
+)
+
+// Approval represents an admin approval data transfer object for application layer communication.
+type Approval struct {
+	// CreatedAt indicates when the request was initiated.
+	CreatedAt time.Time
+	// DecidedAt indicates when the request was approved or rejected; zero while pending.
+	DecidedAt time.Time
+	// ExpiresAt indicates the deadline of both the approval and the execution of the request.
+	ExpiresAt time.Time
+	// Action names the guarded admin action.
+	Action string
+	// Method contains the HTTP method of the request.
+	Method string
+	// Path contains the path with the query of the request.
+	Path string
+	// BodySHA256 contains the hex SHA-256 digest of the request body.
+	BodySHA256 string
+	// Initiator names the operator who initiated the request.
+	Initiator string
+	// Approver names the operator who approved or rejected the request; empty while pending.
+	Approver string
+	// Status contains the state of the approval: pending, approved, rejected, executed or expired.
+	Status string
+	// ID identifies the approval.
+	ID uuid.UUID
+}
+
+// newApprovalFromDomain converts a domain approval to application DTO, reporting its state at the moment.
+func newApprovalFromDomain(a *adminapproval.Approval, at time.Time) *Approval {
+	if a == nil {
+		return nil
+	}
+	return &Approval{
+		ID:         a.ID,
+		Action:     a.Action,
+		Method:     a.Method,
+		Path:       a.Path,
+		BodySHA256: hex.EncodeToString(a.BodyDigest),
+		Initiator:  a.Initiator,
+		Approver:   a.Approver,
+		Status:     string(a.State(at)),
+		CreatedAt:  a.CreatedAt,
+		DecidedAt:  a.DecidedAt,
+		ExpiresAt:  a.ExpiresAt,
+	}
+}
+
+// newApprovalsFromDomain converts a slice of domain approvals to application DTOs.
+func newApprovalsFromDomain(as []*adminapproval.Approval, at time.Time) []*Approval {
+	result := make([]*Approval, 0, len(as))
+	for _, a := range as {
+		result = append(result, newApprovalFromDomain(a, at))
+	}
+	return result
+}
+
+// RequestParams contains parameters for initiating a destructive admin request.
+type RequestParams struct {
+	// Action names the guarded admin action.
+	Action string
+	// Method contains the HTTP method of the request.
+	Method string
+	// Path contains the path with the query of the request.
+	Path string
+	// Initiator names the operator initiating the request.
+	Initiator string
+	// BodyDigest contains the SHA-256 digest of the request body.
+	BodyDigest []byte
+}
+
+// ListParams contains parameters for listing approvals.
+type ListParams struct {
+	// Pending restricts the list to the requests still awaiting approval.
+	Pending bool
+}
+
+// DecideParams contains parameters for deciding a destructive admin request.
+type DecideParams struct {
+	// Operator names the operator deciding the request.
+	Operator string
+	// ID identifies the decided approval.
+	ID uuid.UUID
+	// Approve approves the request when true and rejects it otherwise.
+	Approve bool
+}
+
+// ExecuteParams contains parameters for executing an approved destructive admin request.
+type ExecuteParams struct {
+	// Method contains the HTTP method of the request.
+	Method string
+	// Path contains the path with the query of the request.
+	Path string
+	// Operator names the operator executing the request.
+	Operator string
+	// BodyDigest contains the SHA-256 digest of the request body.
+	BodyDigest []byte
+	// ID identifies the approval the request is executed under.
+	ID uuid.UUID
+}
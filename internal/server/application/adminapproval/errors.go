@@ -0,0 +1,81 @@
+package adminapproval
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/errutil"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/adminapproval"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/adminapproval"
+)
+
+// Dual-control error definitions.
+var (
+	// ErrAdminApprovalAppError indicates a general dual-control application error.
+	ErrAdminApprovalAppError = errors.New("admin approval application error")
+
+	// ErrAdminApprovalTechError indicates a technical error in the dual-control system.
+	ErrAdminApprovalTechError = errors.New("admin approval technical error")
+
+	// ErrApprovalNotFound indicates the requested approval was not found.
+	ErrApprovalNotFound = errors.New("admin approval not found")
+
+	// ErrApprovalNotPending indicates that the approval is already decided.
+	ErrApprovalNotPending = errors.New("admin approval is no longer pending")
+
+	// ErrApprovalNotApproved indicates that the request awaits approval, was rejected or was already executed.
+	ErrApprovalNotApproved = errors.New("admin request is not approved")
+
+	// ErrApprovalExpired indicates that the request was not approved or executed in time.
+	ErrApprovalExpired = errors.New("admin approval has expired")
+
+	// ErrApprovalConflict indicates that another request decided or executed the approval first.
+	ErrApprovalConflict = errors.New("admin approval was decided or used by another request")
+
+	// ErrSelfApproval indicates that an operator attempted to approve their own request.
+	ErrSelfApproval = errors.New("admin requests cannot be approved by the initiator")
+
+	// ErrRequestMismatch indicates that the executed request or its operator differs from the approved one.
+	ErrRequestMismatch = errors.New("admin request does not match the approved request")
+)
+
+// mapError maps domain and repository errors to application-level errors.
+func mapError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	mapped := errutil.MapError(mapFn, err)
+	if mapped != nil {
+		return fmt.Errorf("admin approval error mapping failed: %w", mapped)
+	}
+	return nil
+}
+
+// mapFn provides the actual error mapping logic for different error types.
+func mapFn(err error) error {
+	switch {
+	case errors.Is(err, adminapproval.ErrNewApprovalParamsValidation),
+		errors.Is(err, adminapproval.ErrIncorrectAction),
+		errors.Is(err, adminapproval.ErrIncorrectRequest),
+		errors.Is(err, adminapproval.ErrIncorrectOperator),
+		errors.Is(err, adminapproval.ErrIncorrectTTL):
+		return ErrAdminApprovalAppError
+	case errors.Is(err, adminapproval.ErrApprovalNotPending):
+		return ErrApprovalNotPending
+	case errors.Is(err, adminapproval.ErrApprovalNotApproved):
+		return ErrApprovalNotApproved
+	case errors.Is(err, adminapproval.ErrApprovalExpired):
+		return ErrApprovalExpired
+	case errors.Is(err, adminapproval.ErrSelfApproval):
+		return ErrSelfApproval
+	case errors.Is(err, adminapproval.ErrRequestMismatch):
+		return ErrRequestMismatch
+	case errors.Is(err, repository.ErrApprovalNotFound):
+		return ErrApprovalNotFound
+	case errors.Is(err, repository.ErrApprovalConflict):
+		return ErrApprovalConflict
+	default:
+		return errors.Join(ErrAdminApprovalTechError, err)
+	}
+}
@@ -0,0 +1,234 @@
+package adminapproval
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/audit"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/adminapproval"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/adminapproval"
+	"github.com/google/uuid"
+)
+
+// Notification subjects of the dual-control workflow.
+const (
+	// requestedSubject is the subject of the message asking the operators for an approval.
+	requestedSubject = "AegisVaultKeeper admin action awaits approval"
+	// decidedSubject is the subject of the message telling the operators the decision.
+	decidedSubject = "AegisVaultKeeper admin action was decided"
+)
+
+// Repository defines the interface for admin approval persistence operations.
+type Repository interface {
+	// Save stores a new approval or changes an existing one from the stored state.
+	Save(ctx context.Context, params repository.SaveParams) error
+	// Load retrieves approvals using the provided parameters.
+	Load(ctx context.Context, params repository.LoadParams) ([]*adminapproval.Approval, error)
+}
+
+// Messenger defines the interface for delivering messages to e-mail addresses.
+type Messenger interface {
+	// Send delivers a plain text message to the address.
+	Send(ctx context.Context, to, subject, body string) error
+}
+
+// AuditRecorder defines the interface for recording security audit events.
+type AuditRecorder interface {
+	// Record stores the audit event.
+	Record(ctx context.Context, e audit.Event)
+}
+
+// Service provides the dual-control workflow of destructive admin requests.
+type Service struct {
+	// r is the repository interface for admin approval persistence operations.
+	r Repository
+	// messenger e-mails the operators about requests and decisions; nil when no SMTP relay is configured.
+	messenger Messenger
+	// audit records requests, decisions and executions.
+	audit AuditRecorder
+	// now returns the current time.
+	now func() time.Time
+	// recipients lists the e-mail addresses of the operators told about requests and decisions.
+	recipients []string
+	// ttl specifies how long a request may await its approval and execution.
+	ttl time.Duration
+}
+
+// NewService creates a new dual-control service instance. Requests must be approved and executed within ttl.
+// The messenger may be nil when SMTP is not configured, in which case operators learn about requests by
+// listing them.
+func NewService(
+	r Repository,
+	messenger Messenger,
+	audit AuditRecorder,
+	recipients []string,
+	ttl time.Duration,
+) *Service {
+	return &Service{
+		r:          r,
+		messenger:  messenger,
+		audit:      audit,
+		now:        time.Now,
+		recipients: recipients,
+		ttl:        ttl,
+	}
+}
+
+// Request queues a destructive admin request until a second operator approves it and notifies the operators.
+func (s *Service) Request(ctx context.Context, params RequestParams) (*Approval, error) {
+	a, err := adminapproval.NewApproval(adminapproval.NewApprovalParams{
+		Action:     params.Action,
+		Method:     params.Method,
+		Path:       params.Path,
+		Initiator:  params.Initiator,
+		BodyDigest: params.BodyDigest,
+		TTL:        s.ttl,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create admin approval: %w", mapError(err))
+	}
+	if err := s.r.Save(ctx, repository.SaveParams{Entity: a}); err != nil {
+		return nil, fmt.Errorf("failed to save admin approval: %w", mapError(err))
+	}
+
+	s.audit.Record(ctx, audit.Event{Type: audit.EventAdminActionRequested, Details: approvalDetails(a)})
+	s.notify(ctx, requestedSubject, requestedText(a))
+	return newApprovalFromDomain(a, s.now()), nil
+}
+
+// List retrieves the approvals, most recent first, optionally only those still awaiting approval.
+func (s *Service) List(ctx context.Context, params ListParams) ([]*Approval, error) {
+	loadParams := repository.LoadParams{}
+	if params.Pending {
+		loadParams.Status = adminapproval.StatusPending
+	}
+	approvals, err := s.r.Load(ctx, loadParams)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load admin approvals: %w", mapError(err))
+	}
+
+	now := s.now()
+	if params.Pending {
+		approvals = slices.DeleteFunc(approvals, func(a *adminapproval.Approval) bool {
+			return a.State(now) != adminapproval.StatusPending
+		})
+	}
+	return newApprovalsFromDomain(approvals, now), nil
+}
+
+// Decide approves or rejects a pending request and notifies the operators. Only an operator other than the
+// initiator may approve it; the initiator may reject it to withdraw it.
+func (s *Service) Decide(ctx context.Context, params DecideParams) (*Approval, error) {
+	a, err := s.load(ctx, params.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	if err := a.Decide(params.Operator, params.Approve, now); err != nil {
+		return nil, fmt.Errorf("failed to decide admin approval: %w", mapError(err))
+	}
+	if err := s.r.Save(ctx, repository.SaveParams{Entity: a, From: adminapproval.StatusPending}); err != nil {
+		return nil, fmt.Errorf("failed to save admin approval: %w", mapError(err))
+	}
+
+	event := audit.EventAdminActionRejected
+	if params.Approve {
+		event = audit.EventAdminActionApproved
+	}
+	s.audit.Record(ctx, audit.Event{Type: event, Details: approvalDetails(a)})
+	s.notify(ctx, decidedSubject, decidedText(a))
+	return newApprovalFromDomain(a, now), nil
+}
+
+// Execute uses the approval of the request the initiator is about to execute. Each approval permits a single
+// execution of exactly the approved request.
+func (s *Service) Execute(ctx context.Context, params ExecuteParams) error {
+	a, err := s.load(ctx, params.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := a.Execute(adminapproval.ExecuteParams{
+		Method:     params.Method,
+		Path:       params.Path,
+		Operator:   params.Operator,
+		BodyDigest: params.BodyDigest,
+	}, s.now()); err != nil {
+		return fmt.Errorf("failed to execute admin request: %w", mapError(err))
+	}
+	if err := s.r.Save(ctx, repository.SaveParams{Entity: a, From: adminapproval.StatusApproved}); err != nil {
+		return fmt.Errorf("failed to save admin approval: %w", mapError(err))
+	}
+
+	s.audit.Record(ctx, audit.Event{Type: audit.EventAdminActionExecuted, Details: approvalDetails(a)})
+	return nil
+}
+
+// load retrieves the approval by its identifier.
+func (s *Service) load(ctx context.Context, id uuid.UUID) (*adminapproval.Approval, error) {
+	approvals, err := s.r.Load(ctx, repository.LoadParams{ID: id})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load admin approval: %w", mapError(err))
+	}
+	if len(approvals) == 0 {
+		return nil, fmt.Errorf("admin approval %s: %w", id, ErrApprovalNotFound)
+	}
+	return approvals[0], nil
+}
+
+// notify e-mails the message to the operators.
+func (s *Service) notify(ctx context.Context, subject, body string) {
+	if s.messenger == nil {
+		return
+	}
+	for _, to := range s.recipients {
+		// Failed deliveries are not retried; operators still see the request when listing pending ones.
+		_ = s.messenger.Send(ctx, to, subject, body)
+	}
+}
+
+// approvalDetails returns the audit event details describing the approval.
+func approvalDetails(a *adminapproval.Approval) map[string]string {
+	details := map[string]string{
+		"approval_id": a.ID.String(),
+		"action":      a.Action,
+		"method":      a.Method,
+		"path":        a.Path,
+		"initiator":   a.Initiator,
+	}
+	if a.Approver != "" {
+		details["approver"] = a.Approver
+	}
+	return details
+}
+
+// requestedText renders the message asking the operators to decide the request.
+func requestedText(a *adminapproval.Approval) string {
+	var b strings.Builder
+	b.WriteString("An operator initiated an admin action that requires the approval of a second operator.\n\n")
+	fmt.Fprintf(&b, "Approval:  %s\n", a.ID)
+	fmt.Fprintf(&b, "Action:    %s\n", a.Action)
+	fmt.Fprintf(&b, "Request:   %s %s\n", a.Method, a.Path)
+	fmt.Fprintf(&b, "Initiator: %s\n", a.Initiator)
+	fmt.Fprintf(&b, "\nApprove or reject it with POST /api/admin/approvals/%s/approve or /reject before %s.",
+		a.ID, a.ExpiresAt.UTC().Format(time.RFC1123))
+	return b.String()
+}
+
+// decidedText renders the message telling the operators the decision.
+func decidedText(a *adminapproval.Approval) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "An admin action was %s by %s.\n\n", a.Status, a.Approver)
+	fmt.Fprintf(&b, "Approval:  %s\n", a.ID)
+	fmt.Fprintf(&b, "Action:    %s\n", a.Action)
+	fmt.Fprintf(&b, "Request:   %s %s\n", a.Method, a.Path)
+	fmt.Fprintf(&b, "Initiator: %s\n", a.Initiator)
+	if a.Status == adminapproval.StatusApproved {
+		fmt.Fprintf(&b, "\nThe initiator may execute it once until %s.", a.ExpiresAt.UTC().Format(time.RFC1123))
+	}
+	return b.String()
+}
@@ -0,0 +1,360 @@
+package adminapproval
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/audit"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/adminapproval"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/adminapproval"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockRepository implements Repository for testing.
+type mockRepository struct {
+	loadErr    error
+	saveErr    error
+	saved      []repository.SaveParams
+	stored     []*adminapproval.Approval
+	loadParams repository.LoadParams
+}
+
+func (m *mockRepository) Save(_ context.Context, params repository.SaveParams) error {
+	if m.saveErr != nil {
+		return m.saveErr
+	}
+	m.saved = append(m.saved, params)
+	return nil
+}
+
+func (m *mockRepository) Load(
+	_ context.Context,
+	params repository.LoadParams,
+) ([]*adminapproval.Approval, error) {
+	m.loadParams = params
+	return m.stored, m.loadErr
+}
+
+// mockMessenger implements Messenger for testing.
+type mockMessenger struct {
+	to       []string
+	subjects []string
+}
+
+func (m *mockMessenger) Send(_ context.Context, to, subject, _ string) error {
+	m.to = append(m.to, to)
+	m.subjects = append(m.subjects, subject)
+	return errors.New("relay unavailable")
+}
+
+// mockAuditRecorder implements AuditRecorder for testing.
+type mockAuditRecorder struct {
+	events []audit.Event
+}
+
+func (m *mockAuditRecorder) Record(_ context.Context, e audit.Event) {
+	m.events = append(m.events, e)
+}
+
+// recipients lists the operator addresses of the tests.
+var recipients = []string{"alice@example.com", "bob@example.com"}
+
+func TestService_Request(t *testing.T) {
+	t.Parallel()
+
+	digest := sha256.Sum256(nil)
+	valid := RequestParams{
+		Action:     "recording.delete",
+		Method:     http.MethodDelete,
+		Path:       "/api/admin/recordings/6f1c2a7e-0d5b-4c55-9a3e-1f2b3c4d5e6f",
+		Initiator:  "alice",
+		BodyDigest: digest[:],
+	}
+
+	tests := []struct {
+		saveErr   error
+		wantErr   error
+		messenger *mockMessenger
+		params    RequestParams
+		name      string
+		wantMails int
+	}{
+		{name: "queued and mailed", messenger: &mockMessenger{}, params: valid, wantMails: 2},
+		{name: "queued without smtp", params: valid},
+		{
+			name:    "missing initiator",
+			params:  RequestParams{Action: valid.Action, Method: valid.Method, Path: valid.Path, BodyDigest: digest[:]},
+			wantErr: ErrAdminApprovalAppError,
+		},
+		{
+			name:    "repository error",
+			params:  valid,
+			saveErr: errors.New("connection lost"),
+			wantErr: ErrAdminApprovalTechError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &mockRepository{saveErr: tt.saveErr}
+			recorder := &mockAuditRecorder{}
+			// messenger stays a nil interface without a mock, like the service is built without SMTP.
+			var messenger Messenger
+			if tt.messenger != nil {
+				messenger = tt.messenger
+			}
+			s := NewService(repo, messenger, recorder, recipients, time.Hour)
+
+			got, err := s.Request(context.Background(), tt.params)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, got)
+				assert.Empty(t, recorder.events)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, repo.saved, 1)
+			assert.Empty(t, repo.saved[0].From)
+			assert.Equal(t, repo.saved[0].Entity.ID, got.ID)
+			assert.Equal(t, "pending", got.Status)
+			assert.Equal(t, "alice", got.Initiator)
+			assert.Equal(t, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", got.BodySHA256)
+			require.Len(t, recorder.events, 1)
+			assert.Equal(t, audit.EventAdminActionRequested, recorder.events[0].Type)
+			assert.Equal(t, "recording.delete", recorder.events[0].Details["action"])
+			if tt.messenger != nil {
+				assert.Equal(t, recipients, tt.messenger.to)
+				assert.Len(t, tt.messenger.subjects, tt.wantMails)
+			}
+		})
+	}
+}
+
+func TestService_List(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	pending := &adminapproval.Approval{ID: uuid.New(), Status: adminapproval.StatusPending, ExpiresAt: now.Add(time.Hour)}
+	expired := &adminapproval.Approval{ID: uuid.New(), Status: adminapproval.StatusPending, ExpiresAt: now}
+
+	tests := []struct {
+		wantStatus []string
+		params     ListParams
+		name       string
+		wantFilter adminapproval.Status
+	}{
+		{name: "all approvals", wantStatus: []string{"pending", "expired"}},
+		{
+			name:       "pending approvals",
+			params:     ListParams{Pending: true},
+			wantStatus: []string{"pending"},
+			wantFilter: adminapproval.StatusPending,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &mockRepository{stored: []*adminapproval.Approval{pending, expired}}
+			s := NewService(repo, nil, &mockAuditRecorder{}, nil, time.Hour)
+
+			got, err := s.List(context.Background(), tt.params)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantFilter, repo.loadParams.Status)
+			statuses := make([]string, 0, len(got))
+			for _, a := range got {
+				statuses = append(statuses, a.Status)
+			}
+			assert.Equal(t, tt.wantStatus, statuses)
+		})
+	}
+}
+
+func TestService_Decide(t *testing.T) {
+	t.Parallel()
+
+	id := uuid.New()
+
+	tests := []struct {
+		loadErr    error
+		saveErr    error
+		wantErr    error
+		name       string
+		operator   string
+		wantEvent  string
+		wantStatus string
+		approve    bool
+	}{
+		{
+			name:       "approved by a second operator",
+			operator:   "bob",
+			approve:    true,
+			wantEvent:  audit.EventAdminActionApproved,
+			wantStatus: "approved",
+		},
+		{
+			name:       "withdrawn by the initiator",
+			operator:   "alice",
+			wantEvent:  audit.EventAdminActionRejected,
+			wantStatus: "rejected",
+		},
+		{name: "approved by the initiator", operator: "alice", approve: true, wantErr: ErrSelfApproval},
+		{
+			name:     "unknown approval",
+			operator: "bob",
+			approve:  true,
+			loadErr:  repository.ErrApprovalNotFound,
+			wantErr:  ErrApprovalNotFound,
+		},
+		{
+			name:     "decided concurrently",
+			operator: "bob",
+			approve:  true,
+			saveErr:  repository.ErrApprovalConflict,
+			wantErr:  ErrApprovalConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &mockRepository{
+				loadErr: tt.loadErr,
+				saveErr: tt.saveErr,
+				stored: []*adminapproval.Approval{{
+					ID:        id,
+					Action:    "legal_hold.release",
+					Initiator: "alice",
+					Status:    adminapproval.StatusPending,
+					ExpiresAt: time.Now().Add(time.Hour),
+				}},
+			}
+			recorder := &mockAuditRecorder{}
+			messenger := &mockMessenger{}
+			s := NewService(repo, messenger, recorder, recipients, time.Hour)
+
+			got, err := s.Decide(context.Background(), DecideParams{ID: id, Operator: tt.operator, Approve: tt.approve})
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, got)
+				assert.Empty(t, recorder.events)
+				assert.Empty(t, messenger.to)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, id, repo.loadParams.ID)
+			require.Len(t, repo.saved, 1)
+			assert.Equal(t, adminapproval.StatusPending, repo.saved[0].From)
+			assert.Equal(t, tt.wantStatus, got.Status)
+			assert.Equal(t, tt.operator, got.Approver)
+			require.Len(t, recorder.events, 1)
+			assert.Equal(t, tt.wantEvent, recorder.events[0].Type)
+			assert.Equal(t, tt.operator, recorder.events[0].Details["approver"])
+			assert.Equal(t, recipients, messenger.to)
+		})
+	}
+}
+
+func TestService_Execute(t *testing.T) {
+	t.Parallel()
+
+	id := uuid.New()
+	digest := sha256.Sum256(nil)
+	request := ExecuteParams{
+		ID:         id,
+		Method:     http.MethodDelete,
+		Path:       "/api/admin/legal-holds/6f1c2a7e-0d5b-4c55-9a3e-1f2b3c4d5e6f",
+		Operator:   "alice",
+		BodyDigest: digest[:],
+	}
+
+	tests := []struct {
+		saveErr   error
+		wantErr   error
+		name      string
+		status    adminapproval.Status
+		operator  string
+		expiresIn time.Duration
+	}{
+		{name: "approved request", status: adminapproval.StatusApproved, operator: "alice", expiresIn: time.Hour},
+		{
+			name:      "awaiting approval",
+			status:    adminapproval.StatusPending,
+			operator:  "alice",
+			expiresIn: time.Hour,
+			wantErr:   ErrApprovalNotApproved,
+		},
+		{
+			name:      "executed by another operator",
+			status:    adminapproval.StatusApproved,
+			operator:  "bob",
+			expiresIn: time.Hour,
+			wantErr:   ErrRequestMismatch,
+		},
+		{
+			name:     "expired",
+			status:   adminapproval.StatusApproved,
+			operator: "alice",
+			wantErr:  ErrApprovalExpired,
+		},
+		{
+			name:      "executed concurrently",
+			status:    adminapproval.StatusApproved,
+			operator:  "alice",
+			expiresIn: time.Hour,
+			saveErr:   repository.ErrApprovalConflict,
+			wantErr:   ErrApprovalConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &mockRepository{
+				saveErr: tt.saveErr,
+				stored: []*adminapproval.Approval{{
+					ID:         id,
+					Method:     request.Method,
+					Path:       request.Path,
+					BodyDigest: digest[:],
+					Initiator:  "alice",
+					Approver:   "bob",
+					Status:     tt.status,
+					ExpiresAt:  time.Now().Add(tt.expiresIn),
+				}},
+			}
+			recorder := &mockAuditRecorder{}
+			s := NewService(repo, nil, recorder, recipients, time.Hour)
+			params := request
+			params.Operator = tt.operator
+
+			err := s.Execute(context.Background(), params)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Empty(t, recorder.events)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, repo.saved, 1)
+			assert.Equal(t, adminapproval.StatusApproved, repo.saved[0].From)
+			assert.Equal(t, adminapproval.StatusExecuted, repo.saved[0].Entity.Status)
+			require.Len(t, recorder.events, 1)
+			assert.Equal(t, audit.EventAdminActionExecuted, recorder.events[0].Type)
+		})
+	}
+}
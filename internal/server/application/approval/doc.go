@@ -0,0 +1,6 @@
+// Package approval provides access approval application services for the AegisVaultKeeper server.
+//
+// This package implements the workflow for items tagged to require approval: their owners request access,
+// designated approvers are notified and grant or deny the request, and reveals are only permitted while an
+// approved request is in effect.
+package approval
@@ -0,0 +1,91 @@
+package approval
+
+import (
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/approval"
+	"github.com/google/uuid"
+)
+
+// Request represents an access request data transfer object for application layer communication.
+type Request struct {
+	// CreatedAt indicates when the request was filed.
+	CreatedAt time.Time
+	// DecidedAt indicates when the request was decided; zero while pending.
+	DecidedAt time.Time
+	// ExpiresAt indicates the decision deadline while pending and the end of the granted access once approved.
+	ExpiresAt time.Time
+	// Reason contains the explanation given by the requester.
+	Reason string
+	// Status contains the state of the request: pending, approved, denied or expired.
+	Status string
+	// ID identifies the request.
+	ID uuid.UUID
+	// ItemID identifies the requested item.
+	ItemID uuid.UUID
+	// UserID identifies the requester.
+	UserID uuid.UUID
+	// ApproverID identifies the approver who decided the request; uuid.Nil while pending.
+	ApproverID uuid.UUID
+}
+
+// newRequestFromDomain converts a domain access request to application DTO, reporting its state at the moment.
+func newRequestFromDomain(r *approval.Request, at time.Time) *Request {
+	if r == nil {
+		return nil
+	}
+	return &Request{
+		ID:         r.ID,
+		ItemID:     r.ItemID,
+		UserID:     r.UserID,
+		ApproverID: r.ApproverID,
+		Reason:     r.Reason,
+		Status:     string(r.State(at)),
+		CreatedAt:  r.CreatedAt,
+		DecidedAt:  r.DecidedAt,
+		ExpiresAt:  r.ExpiresAt,
+	}
+}
+
+// newRequestsFromDomain converts a slice of domain access requests to application DTOs.
+func newRequestsFromDomain(rs []*approval.Request, at time.Time) []*Request {
+	result := make([]*Request, 0, len(rs))
+	for _, r := range rs {
+		result = append(result, newRequestFromDomain(r, at))
+	}
+	return result
+}
+
+// RequestParams contains parameters for requesting access to an item that requires approval.
+type RequestParams struct {
+	// Reason contains the explanation shown to approvers.
+	Reason string
+	// ItemID identifies the requested item.
+	ItemID uuid.UUID
+	// UserID identifies the requester, who owns the item.
+	UserID uuid.UUID
+}
+
+// ListParams contains parameters for listing access requests.
+type ListParams struct {
+	// UserID identifies the requester, or the approver when listing pending requests.
+	UserID uuid.UUID
+}
+
+// DecideParams contains parameters for deciding an access request.
+type DecideParams struct {
+	// ID identifies the decided request.
+	ID uuid.UUID
+	// ApproverID identifies the approver deciding the request.
+	ApproverID uuid.UUID
+	// Approve grants the request when true and denies it otherwise.
+	Approve bool
+}
+
+// CheckParams contains parameters for checking whether items that require approval may be revealed.
+type CheckParams struct {
+	// UserID identifies the user who owns the items.
+	UserID uuid.UUID
+	// ItemID identifies the revealed item; uuid.Nil covers every item of the user, as in listings.
+	ItemID uuid.UUID
+}
@@ -0,0 +1,70 @@
+package approval
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/errutil"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/approval"
+)
+
+// Access approval error definitions.
+var (
+	// ErrApprovalAppError indicates a general access approval application error.
+	ErrApprovalAppError = errors.New("access approval application error")
+
+	// ErrApprovalTechError indicates a technical error in the access approval system.
+	ErrApprovalTechError = errors.New("access approval technical error")
+
+	// ErrApprovalRequired indicates that the item cannot be revealed without an approved access request.
+	ErrApprovalRequired = errors.New("item requires an approved access request")
+
+	// ErrApprovalNotRequired indicates that access was requested to an item that does not require approval.
+	ErrApprovalNotRequired = errors.New("item does not require approval")
+
+	// ErrNotApprover indicates that the user is not a designated approver.
+	ErrNotApprover = errors.New("user is not an approver")
+
+	// ErrRequestNotFound indicates the requested access request was not found.
+	ErrRequestNotFound = errors.New("access request not found")
+
+	// ErrRequestIncorrectReason indicates a too long request reason was provided.
+	ErrRequestIncorrectReason = errors.New("incorrect access request reason")
+
+	// ErrRequestNotPending indicates that the access request is already decided or has expired.
+	ErrRequestNotPending = errors.New("access request is no longer pending")
+
+	// ErrSelfApproval indicates that an approver attempted to decide their own request.
+	ErrSelfApproval = errors.New("access requests cannot be decided by the requester")
+)
+
+// mapError maps domain and repository errors to application-level errors.
+func mapError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	mapped := errutil.MapError(mapFn, err)
+	if mapped != nil {
+		return fmt.Errorf("access approval error mapping failed: %w", mapped)
+	}
+	return nil
+}
+
+// mapFn provides the actual error mapping logic for different error types.
+func mapFn(err error) error {
+	switch {
+	case errors.Is(err, approval.ErrNewRequestParamsValidation):
+		return ErrApprovalAppError
+	case errors.Is(err, approval.ErrIncorrectReason):
+		return ErrRequestIncorrectReason
+	case errors.Is(err, approval.ErrRequestNotPending), errors.Is(err, approval.ErrRequestExpired):
+		return ErrRequestNotPending
+	case errors.Is(err, approval.ErrSelfApproval):
+		return ErrSelfApproval
+	case errors.Is(err, approval.ErrIncorrectItem), errors.Is(err, approval.ErrIncorrectTTL):
+		return ErrApprovalAppError
+	default:
+		return errors.Join(ErrApprovalTechError, err)
+	}
+}
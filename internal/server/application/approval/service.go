@@ -0,0 +1,312 @@
+package approval
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/notification"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/audit"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/approval"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/itemtag"
+	domainNotification "github.com/gdyunin/aegis-vault-keeper/internal/server/domain/notification"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/approval"
+	tagRepository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/itemtag"
+	"github.com/google/uuid"
+)
+
+// Notification subjects of the approval workflow.
+const (
+	// requestedSubject is the subject of the notification asking approvers for a decision.
+	requestedSubject = "Vault item access awaits your approval"
+	// decidedSubject is the subject of the notification telling the requester the decision.
+	decidedSubject = "Your vault item access request was decided"
+)
+
+// Repository defines the interface for access request persistence operations.
+type Repository interface {
+	// Save persists an access request using the provided parameters.
+	Save(ctx context.Context, params repository.SaveParams) error
+	// Load retrieves access requests using the provided parameters.
+	Load(ctx context.Context, params repository.LoadParams) ([]*approval.Request, error)
+}
+
+// TagRepository defines the interface for looking up the items tagged to require approval.
+type TagRepository interface {
+	// Exists reports whether an item, or any item of the user, carries the tag.
+	Exists(ctx context.Context, params tagRepository.ExistsParams) (bool, error)
+	// Load retrieves item tags using the provided parameters.
+	Load(ctx context.Context, params tagRepository.LoadParams) ([]*itemtag.ItemTags, error)
+}
+
+// Notifier defines the interface for delivering notifications to users over their channels.
+type Notifier interface {
+	// Notify delivers a notification to the channels of the user subscribed to its category.
+	Notify(ctx context.Context, params notification.NotifyParams) error
+}
+
+// AuditRecorder defines the interface for recording security audit events.
+type AuditRecorder interface {
+	// Record stores the audit event.
+	Record(ctx context.Context, e audit.Event)
+}
+
+// Service provides the access approval workflow.
+type Service struct {
+	// r is the repository interface for access request persistence operations.
+	r Repository
+	// tags looks up the approval tag of items.
+	tags TagRepository
+	// notifier tells approvers about new requests and requesters about decisions.
+	notifier Notifier
+	// audit records requests, decisions and rejected reveals.
+	audit AuditRecorder
+	// now returns the current time.
+	now func() time.Time
+	// approvers lists the users who decide access requests; none leaves the approval tag without effect.
+	approvers []uuid.UUID
+	// requestTTL specifies how long a request may await a decision.
+	requestTTL time.Duration
+	// accessTTL specifies how long an approved request permits reveals of its item.
+	accessTTL time.Duration
+}
+
+// NewService creates a new access approval service instance. Requests are decided by the approvers
+// within requestTTL and, once approved, permit reveals of their item for accessTTL.
+func NewService(
+	r Repository,
+	tags TagRepository,
+	notifier Notifier,
+	audit AuditRecorder,
+	approvers []uuid.UUID,
+	requestTTL, accessTTL time.Duration,
+) *Service {
+	return &Service{
+		r:          r,
+		tags:       tags,
+		notifier:   notifier,
+		audit:      audit,
+		now:        time.Now,
+		approvers:  approvers,
+		requestTTL: requestTTL,
+		accessTTL:  accessTTL,
+	}
+}
+
+// CheckApproval verifies that a reveal of the item, or of every item of the user when no item is specified,
+// is permitted. Items tagged to require approval may only be revealed while an approved request is in effect.
+func (s *Service) CheckApproval(ctx context.Context, params CheckParams) error {
+	if len(s.approvers) == 0 {
+		return nil
+	}
+
+	tagged, err := s.tags.Load(ctx, tagRepository.LoadParams{UserID: params.UserID, ItemID: params.ItemID})
+	if err != nil {
+		return fmt.Errorf("failed to look up items requiring approval: %w", mapError(err))
+	}
+	var required []uuid.UUID
+	for _, t := range tagged {
+		if t.Has(itemtag.RequiresApproval) {
+			required = append(required, t.ItemID)
+		}
+	}
+	if len(required) == 0 {
+		return nil
+	}
+
+	grants, err := s.r.Load(ctx, repository.LoadParams{
+		UserID:   params.UserID,
+		ItemID:   params.ItemID,
+		Status:   approval.StatusApproved,
+		ActiveAt: s.now(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to load approved access requests: %w", mapError(err))
+	}
+	for _, itemID := range required {
+		granted := slices.ContainsFunc(grants, func(r *approval.Request) bool { return r.ItemID == itemID })
+		if granted {
+			continue
+		}
+		s.audit.Record(ctx, audit.Event{
+			Type:    audit.EventAccessApprovalRequired,
+			UserID:  params.UserID,
+			Details: map[string]string{"item_id": itemID.String()},
+		})
+		return fmt.Errorf("reveal of item %s rejected: %w", itemID, ErrApprovalRequired)
+	}
+	return nil
+}
+
+// RequestAccess files a request to reveal an item that requires approval and notifies the approvers.
+// While a request for the item is pending, that request is returned instead of filing another one.
+func (s *Service) RequestAccess(ctx context.Context, params RequestParams) (*Request, error) {
+	req, err := approval.NewRequest(approval.NewRequestParams{
+		Reason: params.Reason,
+		ItemID: params.ItemID,
+		UserID: params.UserID,
+		TTL:    s.requestTTL,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create access request: %w", mapError(err))
+	}
+
+	required, err := s.tags.Exists(ctx, tagRepository.ExistsParams{
+		Tag:    itemtag.RequiresApproval,
+		UserID: params.UserID,
+		ItemID: params.ItemID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up items requiring approval: %w", mapError(err))
+	}
+	if !required || len(s.approvers) == 0 {
+		return nil, fmt.Errorf("access to item %s requested: %w", params.ItemID, ErrApprovalNotRequired)
+	}
+
+	now := s.now()
+	pending, err := s.r.Load(ctx, repository.LoadParams{
+		UserID:   params.UserID,
+		ItemID:   params.ItemID,
+		Status:   approval.StatusPending,
+		ActiveAt: now,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load pending access requests: %w", mapError(err))
+	}
+	if len(pending) != 0 {
+		return newRequestFromDomain(pending[0], now), nil
+	}
+
+	if err := s.r.Save(ctx, repository.SaveParams{Entity: req}); err != nil {
+		return nil, fmt.Errorf("failed to save access request: %w", mapError(err))
+	}
+
+	s.audit.Record(ctx, audit.Event{
+		Type:   audit.EventApprovalRequested,
+		UserID: params.UserID,
+		Details: map[string]string{
+			"request_id": req.ID.String(),
+			"item_id":    req.ItemID.String(),
+		},
+	})
+
+	text := requestedText(req)
+	for _, approverID := range s.approvers {
+		if approverID == req.UserID {
+			continue
+		}
+		// Failed deliveries are audited by the notifier; approvers still see the request when listing pending ones.
+		_ = s.notifier.Notify(ctx, notification.NotifyParams{
+			Category: domainNotification.CategorySecurity,
+			Subject:  requestedSubject,
+			Body:     text,
+			UserID:   approverID,
+		})
+	}
+	return newRequestFromDomain(req, now), nil
+}
+
+// ListRequests retrieves the access requests of the user, most recent first.
+func (s *Service) ListRequests(ctx context.Context, params ListParams) ([]*Request, error) {
+	requests, err := s.r.Load(ctx, repository.LoadParams{UserID: params.UserID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load access requests: %w", mapError(err))
+	}
+	return newRequestsFromDomain(requests, s.now()), nil
+}
+
+// ListPending retrieves the requests awaiting the decision of the approver, most recent first.
+// The own requests of the approver are left out, since other approvers decide them.
+func (s *Service) ListPending(ctx context.Context, params ListParams) ([]*Request, error) {
+	if !s.isApprover(params.UserID) {
+		return nil, fmt.Errorf("pending access requests listed: %w", ErrNotApprover)
+	}
+
+	now := s.now()
+	requests, err := s.r.Load(ctx, repository.LoadParams{Status: approval.StatusPending, ActiveAt: now})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load pending access requests: %w", mapError(err))
+	}
+	requests = slices.DeleteFunc(requests, func(r *approval.Request) bool { return r.UserID == params.UserID })
+	return newRequestsFromDomain(requests, now), nil
+}
+
+// Decide grants or denies a pending access request and notifies the requester.
+func (s *Service) Decide(ctx context.Context, params DecideParams) (*Request, error) {
+	if !s.isApprover(params.ApproverID) {
+		return nil, fmt.Errorf("access request %s decided: %w", params.ID, ErrNotApprover)
+	}
+
+	requests, err := s.r.Load(ctx, repository.LoadParams{ID: params.ID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load access request: %w", mapError(err))
+	}
+	if len(requests) == 0 {
+		return nil, fmt.Errorf("access request %s: %w", params.ID, ErrRequestNotFound)
+	}
+	req := requests[0]
+
+	now := s.now()
+	if err := req.Decide(params.ApproverID, params.Approve, s.accessTTL, now); err != nil {
+		return nil, fmt.Errorf("failed to decide access request: %w", mapError(err))
+	}
+	if err := s.r.Save(ctx, repository.SaveParams{Entity: req}); err != nil {
+		return nil, fmt.Errorf("failed to save access request: %w", mapError(err))
+	}
+
+	event := audit.EventApprovalDenied
+	if params.Approve {
+		event = audit.EventApprovalGranted
+	}
+	s.audit.Record(ctx, audit.Event{
+		Type:   event,
+		UserID: req.UserID,
+		Details: map[string]string{
+			"request_id":  req.ID.String(),
+			"item_id":     req.ItemID.String(),
+			"approver_id": params.ApproverID.String(),
+		},
+	})
+
+	// Failed deliveries are audited by the notifier; the requester still sees the decision when listing requests.
+	_ = s.notifier.Notify(ctx, notification.NotifyParams{
+		Category: domainNotification.CategorySecurity,
+		Subject:  decidedSubject,
+		Body:     decidedText(req),
+		UserID:   req.UserID,
+	})
+	return newRequestFromDomain(req, now), nil
+}
+
+// isApprover reports whether the user is a designated approver.
+func (s *Service) isApprover(userID uuid.UUID) bool {
+	return slices.Contains(s.approvers, userID)
+}
+
+// requestedText renders the notification asking approvers to decide the request.
+func requestedText(r *approval.Request) string {
+	var b strings.Builder
+	b.WriteString("A user requests access to a vault item that requires approval.\n\n")
+	fmt.Fprintf(&b, "Request: %s\n", r.ID)
+	fmt.Fprintf(&b, "User:    %s\n", r.UserID)
+	fmt.Fprintf(&b, "Item:    %s\n", r.ItemID)
+	if r.Reason != "" {
+		fmt.Fprintf(&b, "Reason:  %s\n", r.Reason)
+	}
+	fmt.Fprintf(&b, "\nApprove or deny the request before %s.", r.ExpiresAt.UTC().Format(time.RFC1123))
+	return b.String()
+}
+
+// decidedText renders the notification telling the requester the decision.
+func decidedText(r *approval.Request) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Your request to access a vault item was %s.\n\n", r.Status)
+	fmt.Fprintf(&b, "Request: %s\n", r.ID)
+	fmt.Fprintf(&b, "Item:    %s\n", r.ItemID)
+	if r.Status == approval.StatusApproved {
+		fmt.Fprintf(&b, "\nThe item can be revealed until %s.", r.ExpiresAt.UTC().Format(time.RFC1123))
+	}
+	return b.String()
+}
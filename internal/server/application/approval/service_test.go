@@ -0,0 +1,350 @@
+package approval
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/notification"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/audit"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/approval"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/itemtag"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/approval"
+	tagRepository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/itemtag"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockRepository implements Repository for testing.
+type mockRepository struct {
+	loadErr    error
+	saved      *approval.Request
+	loadParams repository.LoadParams
+	stored     []*approval.Request
+}
+
+func (m *mockRepository) Save(_ context.Context, params repository.SaveParams) error {
+	m.saved = params.Entity
+	return nil
+}
+
+func (m *mockRepository) Load(_ context.Context, params repository.LoadParams) ([]*approval.Request, error) {
+	m.loadParams = params
+	return m.stored, m.loadErr
+}
+
+// mockTagRepository implements TagRepository for testing.
+type mockTagRepository struct {
+	tagged []*itemtag.ItemTags
+	exists bool
+}
+
+func (m *mockTagRepository) Exists(context.Context, tagRepository.ExistsParams) (bool, error) {
+	return m.exists, nil
+}
+
+func (m *mockTagRepository) Load(context.Context, tagRepository.LoadParams) ([]*itemtag.ItemTags, error) {
+	return m.tagged, nil
+}
+
+// mockNotifier implements Notifier for testing.
+type mockNotifier struct {
+	sent []notification.NotifyParams
+}
+
+func (m *mockNotifier) Notify(_ context.Context, params notification.NotifyParams) error {
+	m.sent = append(m.sent, params)
+	return notification.ErrNotificationUnreachable
+}
+
+// mockAuditRecorder implements AuditRecorder for testing.
+type mockAuditRecorder struct {
+	events []audit.Event
+}
+
+func (m *mockAuditRecorder) Record(_ context.Context, e audit.Event) {
+	m.events = append(m.events, e)
+}
+
+func TestService_CheckApproval(t *testing.T) {
+	t.Parallel()
+
+	userID, itemID, otherID, approverID := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	tagged := []*itemtag.ItemTags{
+		{ItemID: otherID, UserID: userID, Tags: []string{"work"}},
+		{ItemID: itemID, UserID: userID, Tags: []string{itemtag.RequiresApproval}},
+	}
+	grant := &approval.Request{ItemID: itemID, UserID: userID, Status: approval.StatusApproved}
+
+	tests := []struct {
+		wantErr   error
+		name      string
+		tagged    []*itemtag.ItemTags
+		grants    []*approval.Request
+		approvers []uuid.UUID
+	}{
+		{name: "no approvers", tagged: tagged},
+		{name: "nothing requires approval", tagged: tagged[:1], approvers: []uuid.UUID{approverID}},
+		{name: "approved", tagged: tagged, grants: []*approval.Request{grant}, approvers: []uuid.UUID{approverID}},
+		{name: "not approved", tagged: tagged, approvers: []uuid.UUID{approverID}, wantErr: ErrApprovalRequired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &mockRepository{stored: tt.grants}
+			recorder := &mockAuditRecorder{}
+			s := NewService(
+				repo, &mockTagRepository{tagged: tt.tagged}, &mockNotifier{}, recorder,
+				tt.approvers, time.Hour, 15*time.Minute,
+			)
+			s.now = func() time.Time { return now }
+
+			err := s.CheckApproval(context.Background(), CheckParams{UserID: userID})
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				require.Len(t, recorder.events, 1)
+				assert.Equal(t, audit.EventAccessApprovalRequired, recorder.events[0].Type)
+				assert.Equal(t, itemID.String(), recorder.events[0].Details["item_id"])
+				return
+			}
+			require.NoError(t, err)
+			assert.Empty(t, recorder.events)
+			if tt.grants != nil {
+				assert.Equal(t, repository.LoadParams{
+					UserID:   userID,
+					Status:   approval.StatusApproved,
+					ActiveAt: now,
+				}, repo.loadParams)
+			}
+		})
+	}
+}
+
+func TestService_RequestAccess(t *testing.T) {
+	t.Parallel()
+
+	userID, itemID, approverID := uuid.New(), uuid.New(), uuid.New()
+	pending := &approval.Request{
+		ID:        uuid.New(),
+		ItemID:    itemID,
+		UserID:    userID,
+		Status:    approval.StatusPending,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	tests := []struct {
+		wantErr     error
+		repo        *mockRepository
+		name        string
+		approvers   []uuid.UUID
+		required    bool
+		wantNotices int
+	}{
+		{
+			name:        "request access",
+			repo:        &mockRepository{},
+			approvers:   []uuid.UUID{approverID, userID},
+			required:    true,
+			wantNotices: 1,
+		},
+		{
+			name:      "request already pending",
+			repo:      &mockRepository{stored: []*approval.Request{pending}},
+			approvers: []uuid.UUID{approverID},
+			required:  true,
+		},
+		{
+			name:      "item does not require approval",
+			repo:      &mockRepository{},
+			approvers: []uuid.UUID{approverID},
+			wantErr:   ErrApprovalNotRequired,
+		},
+		{
+			name:     "no approvers",
+			repo:     &mockRepository{},
+			required: true,
+			wantErr:  ErrApprovalNotRequired,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			notifier := &mockNotifier{}
+			recorder := &mockAuditRecorder{}
+			s := NewService(
+				tt.repo, &mockTagRepository{exists: tt.required}, notifier, recorder,
+				tt.approvers, time.Hour, 15*time.Minute,
+			)
+
+			got, err := s.RequestAccess(context.Background(), RequestParams{
+				ItemID: itemID,
+				UserID: userID,
+				Reason: "quarterly audit",
+			})
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, tt.repo.saved)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, string(approval.StatusPending), got.Status)
+			assert.Len(t, notifier.sent, tt.wantNotices)
+			if tt.repo.stored != nil {
+				assert.Equal(t, pending.ID, got.ID)
+				assert.Nil(t, tt.repo.saved)
+				assert.Empty(t, recorder.events)
+				return
+			}
+			require.NotNil(t, tt.repo.saved)
+			assert.Equal(t, got.ID, tt.repo.saved.ID)
+			assert.Equal(t, approverID, notifier.sent[0].UserID)
+			assert.Contains(t, notifier.sent[0].Body, "quarterly audit")
+			require.Len(t, recorder.events, 1)
+			assert.Equal(t, audit.EventApprovalRequested, recorder.events[0].Type)
+		})
+	}
+}
+
+func TestService_ListPending(t *testing.T) {
+	t.Parallel()
+
+	approverID := uuid.New()
+	own := &approval.Request{ID: uuid.New(), UserID: approverID, Status: approval.StatusPending}
+	other := &approval.Request{ID: uuid.New(), UserID: uuid.New(), Status: approval.StatusPending}
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("approver", func(t *testing.T) {
+		t.Parallel()
+
+		repo := &mockRepository{stored: []*approval.Request{own, other}}
+		s := NewService(repo, &mockTagRepository{}, &mockNotifier{}, &mockAuditRecorder{},
+			[]uuid.UUID{approverID}, time.Hour, 15*time.Minute)
+		s.now = func() time.Time { return now }
+
+		got, err := s.ListPending(context.Background(), ListParams{UserID: approverID})
+
+		require.NoError(t, err)
+		require.Len(t, got, 1)
+		assert.Equal(t, other.ID, got[0].ID)
+		assert.Equal(t, repository.LoadParams{Status: approval.StatusPending, ActiveAt: now}, repo.loadParams)
+	})
+
+	t.Run("not an approver", func(t *testing.T) {
+		t.Parallel()
+
+		s := NewService(&mockRepository{}, &mockTagRepository{}, &mockNotifier{}, &mockAuditRecorder{},
+			[]uuid.UUID{approverID}, time.Hour, 15*time.Minute)
+
+		_, err := s.ListPending(context.Background(), ListParams{UserID: uuid.New()})
+
+		require.ErrorIs(t, err, ErrNotApprover)
+	})
+}
+
+func TestService_Decide(t *testing.T) {
+	t.Parallel()
+
+	userID, approverID := uuid.New(), uuid.New()
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	pending := func() *approval.Request {
+		return &approval.Request{
+			ID:        uuid.New(),
+			ItemID:    uuid.New(),
+			UserID:    userID,
+			Status:    approval.StatusPending,
+			CreatedAt: now.Add(-time.Minute),
+			ExpiresAt: now.Add(time.Hour),
+		}
+	}
+
+	tests := []struct {
+		wantErr    error
+		stored     *approval.Request
+		loadErr    error
+		name       string
+		wantStatus string
+		wantEvent  string
+		approverID uuid.UUID
+		approve    bool
+	}{
+		{
+			name:       "approve",
+			stored:     pending(),
+			approverID: approverID,
+			approve:    true,
+			wantStatus: string(approval.StatusApproved),
+			wantEvent:  audit.EventApprovalGranted,
+		},
+		{
+			name:       "deny",
+			stored:     pending(),
+			approverID: approverID,
+			wantStatus: string(approval.StatusDenied),
+			wantEvent:  audit.EventApprovalDenied,
+		},
+		{name: "not an approver", stored: pending(), approverID: uuid.New(), wantErr: ErrNotApprover},
+		{name: "not found", approverID: approverID, approve: true, wantErr: ErrRequestNotFound},
+		{
+			name:       "already decided",
+			stored:     &approval.Request{ID: uuid.New(), UserID: userID, Status: approval.StatusDenied},
+			approverID: approverID,
+			approve:    true,
+			wantErr:    ErrRequestNotPending,
+		},
+		{
+			name:       "repository error",
+			loadErr:    errors.New("connection refused"),
+			approverID: approverID,
+			wantErr:    ErrApprovalTechError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &mockRepository{loadErr: tt.loadErr}
+			id := uuid.New()
+			if tt.stored != nil {
+				repo.stored = []*approval.Request{tt.stored}
+				id = tt.stored.ID
+			}
+			notifier := &mockNotifier{}
+			recorder := &mockAuditRecorder{}
+			s := NewService(repo, &mockTagRepository{}, notifier, recorder,
+				[]uuid.UUID{approverID}, time.Hour, 15*time.Minute)
+			s.now = func() time.Time { return now }
+
+			got, err := s.Decide(context.Background(), DecideParams{
+				ID:         id,
+				ApproverID: tt.approverID,
+				Approve:    tt.approve,
+			})
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, repo.saved)
+				assert.Empty(t, notifier.sent)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, got.Status)
+			assert.Equal(t, approverID, got.ApproverID)
+			assert.Same(t, tt.stored, repo.saved)
+			require.Len(t, recorder.events, 1)
+			assert.Equal(t, tt.wantEvent, recorder.events[0].Type)
+			require.Len(t, notifier.sent, 1)
+			assert.Equal(t, userID, notifier.sent[0].UserID)
+			assert.Contains(t, notifier.sent[0].Body, tt.wantStatus)
+		})
+	}
+}
@@ -0,0 +1,5 @@
+// Package auditexport provides compliance export application services for the AegisVaultKeeper server.
+//
+// This package implements exporting the audit trail of a period as JSON Lines or CSV together with a digest
+// file signed with the response signing key, so auditors can verify the export was not altered.
+package auditexport
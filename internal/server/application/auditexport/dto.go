@@ -0,0 +1,40 @@
+package auditexport
+
+import "time"
+
+// Format identifies the file format of an audit export.
+type Format string
+
+// Export formats.
+const (
+	// FormatJSONL writes one JSON object per entry and line.
+	FormatJSONL Format = "jsonl"
+	// FormatCSV writes a header row and one row per entry.
+	FormatCSV Format = "csv"
+)
+
+// FileName returns the name of the exported file of the format, as listed in the digest.
+func (f Format) FileName() string {
+	return "audit-events." + string(f)
+}
+
+// ExportParams contains the parameters required to export the audit trail.
+type ExportParams struct {
+	// From is the inclusive start of the exported period.
+	From time.Time
+	// To is the exclusive end of the exported period.
+	To time.Time
+	// Format specifies the file format.
+	Format Format
+}
+
+// Digest represents the signed digest of an export.
+type Digest struct {
+	// Document contains the JSON digest file: the exported period, file name and format, number of entries and
+	// the hex SHA-256 digest of the file.
+	Document []byte
+	// Signature contains the Ed25519 signature of Document.
+	Signature []byte
+	// KeyID identifies the key Signature was made with.
+	KeyID string
+}
@@ -0,0 +1,18 @@
+package auditexport
+
+import "errors"
+
+// Audit export error definitions.
+var (
+	// ErrAuditExportTechError indicates a technical error in the audit export system.
+	ErrAuditExportTechError = errors.New("audit export technical error")
+
+	// ErrFormatUnsupported indicates an export format other than JSON Lines and CSV.
+	ErrFormatUnsupported = errors.New("audit export format is not supported")
+
+	// ErrIncorrectPeriod indicates a period without a start or an end, or ending before it starts.
+	ErrIncorrectPeriod = errors.New("incorrect audit export period")
+
+	// ErrSigningKeyNotConfigured indicates that no key is configured to sign the digest of exports.
+	ErrSigningKeyNotConfigured = errors.New("audit export signing key is not configured")
+)
@@ -0,0 +1,246 @@
+package auditexport
+
+import (
+	"bufio"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/audit"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/crypto"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/auditlog"
+	"github.com/google/uuid"
+)
+
+const (
+	// pageSize is the number of entries an export loads at a time.
+	pageSize = 1000
+	// signatureAlgorithm names the algorithm digests are signed with.
+	signatureAlgorithm = "Ed25519"
+)
+
+// csvHeader lists the columns of CSV exports.
+var csvHeader = []string{"seq", "occurred_at", "event_type", "user_id", "client_ip", "details"}
+
+// Repository defines the interface for reading the audit trail.
+type Repository interface {
+	// Load retrieves a page of the entries that occurred in the period ordered by sequence number.
+	Load(ctx context.Context, params repository.LoadParams) ([]*audit.Entry, error)
+}
+
+// AuditRecorder defines the interface for recording security audit events.
+type AuditRecorder interface {
+	// Record stores the audit event.
+	Record(ctx context.Context, e audit.Event)
+}
+
+// Service provides audit trail export operations.
+type Service struct {
+	// r is the repository interface for reading the audit trail.
+	r Repository
+	// audit records exports.
+	audit AuditRecorder
+	// key signs the digests of exports; nil disables exports.
+	key ed25519.PrivateKey
+}
+
+// NewService creates a new audit export service instance. The key signing digests may be nil, in which case
+// exports are refused.
+func NewService(r Repository, key ed25519.PrivateKey, audit AuditRecorder) *Service {
+	return &Service{r: r, key: key, audit: audit}
+}
+
+// entryRecord is the JSON Lines representation of an audit entry.
+type entryRecord struct {
+	// Seq orders the entries by the time they were stored.
+	Seq int64 `json:"seq"`
+	// OccurredAt is the moment the event happened in UTC.
+	OccurredAt time.Time `json:"occurred_at"`
+	// Type identifies the kind of event.
+	Type string `json:"event_type"`
+	// UserID identifies the affected user; omitted for system-wide events.
+	UserID string `json:"user_id,omitempty"`
+	// ClientIP contains the address of the client; omitted for events outside requests.
+	ClientIP string `json:"client_ip,omitempty"`
+	// Details holds event-specific attributes.
+	Details map[string]string `json:"details"`
+}
+
+// digestDocument is the content of the signed digest file of an export.
+type digestDocument struct {
+	// File names the exported file.
+	File string `json:"file"`
+	// Format specifies the format of the exported file.
+	Format Format `json:"format"`
+	// From is the inclusive start of the exported period in UTC.
+	From time.Time `json:"from"`
+	// To is the exclusive end of the exported period in UTC.
+	To time.Time `json:"to"`
+	// Events is the number of exported entries.
+	Events int64 `json:"events"`
+	// SHA256 contains the hex SHA-256 digest of the exported file.
+	SHA256 string `json:"sha256"`
+	// GeneratedAt is the moment the export finished in UTC.
+	GeneratedAt time.Time `json:"generated_at"`
+	// SignatureAlgorithm names the algorithm the digest file is signed with.
+	SignatureAlgorithm string `json:"signature_algorithm"`
+	// KeyID identifies the signing key.
+	KeyID string `json:"key_id"`
+}
+
+// Export writes the audit entries that occurred in the period to w in the requested format, ordered by the
+// time they were stored, and returns the digest of the written file signed with the signing key.
+// Entries are loaded a page at a time and the next page is loaded only after the previous one was written, so
+// exports of any size use bounded memory. Nothing is written when the parameters are invalid or no signing key
+// is configured. The export itself is recorded as an audit event.
+func (s *Service) Export(ctx context.Context, params ExportParams, w io.Writer) (*Digest, error) {
+	if params.Format != FormatJSONL && params.Format != FormatCSV {
+		return nil, ErrFormatUnsupported
+	}
+	if params.From.IsZero() || params.To.IsZero() || !params.From.Before(params.To) {
+		return nil, ErrIncorrectPeriod
+	}
+	if s.key == nil {
+		return nil, ErrSigningKeyNotConfigured
+	}
+
+	hash := sha256.New()
+	out := bufio.NewWriter(io.MultiWriter(w, hash))
+	write, err := newEncoder(params.Format, out)
+	if err != nil {
+		return nil, err
+	}
+
+	var events int64
+	// after holds the sequence number of the last written entry.
+	var after int64
+	for {
+		page, err := s.r.Load(ctx, repository.LoadParams{
+			From:  params.From,
+			To:    params.To,
+			After: after,
+			Limit: pageSize,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to load audit events: %w", errors.Join(ErrAuditExportTechError, err))
+		}
+		for _, e := range page {
+			if err := write(e); err != nil {
+				return nil, err
+			}
+			after = e.Seq
+			events++
+		}
+		if len(page) < pageSize {
+			break
+		}
+	}
+	if err := out.Flush(); err != nil {
+		return nil, fmt.Errorf("failed to write audit export: %w", err)
+	}
+
+	digest := hex.EncodeToString(hash.Sum(nil))
+	publicKey, _ := s.key.Public().(ed25519.PublicKey)
+	keyID := crypto.Ed25519KeyID(publicKey)
+	document, err := json.MarshalIndent(digestDocument{
+		File:               params.Format.FileName(),
+		Format:             params.Format,
+		From:               params.From.UTC(),
+		To:                 params.To.UTC(),
+		Events:             events,
+		SHA256:             digest,
+		GeneratedAt:        time.Now().UTC(),
+		SignatureAlgorithm: signatureAlgorithm,
+		KeyID:              keyID,
+	}, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode audit export digest: %w", err)
+	}
+	document = append(document, '\n')
+
+	s.audit.Record(ctx, audit.Event{
+		Type: audit.EventAuditExported,
+		Details: map[string]string{
+			"from":   params.From.UTC().Format(time.RFC3339),
+			"to":     params.To.UTC().Format(time.RFC3339),
+			"format": string(params.Format),
+			"events": strconv.FormatInt(events, 10),
+			"sha256": digest,
+		},
+	})
+	return &Digest{
+		Document:  document,
+		Signature: ed25519.Sign(s.key, document),
+		KeyID:     keyID,
+	}, nil
+}
+
+// newEncoder returns the function writing entries to w in the format. The header of CSV files is written
+// right away.
+func newEncoder(format Format, w io.Writer) (func(e *audit.Entry) error, error) {
+	if format == FormatJSONL {
+		enc := json.NewEncoder(w)
+		return func(e *audit.Entry) error {
+			if err := enc.Encode(newEntryRecord(e)); err != nil {
+				return fmt.Errorf("failed to write audit export: %w", err)
+			}
+			return nil
+		}, nil
+	}
+
+	cw := csv.NewWriter(w)
+	writeRow := func(row []string) error {
+		if err := cw.Write(row); err != nil {
+			return fmt.Errorf("failed to write audit export: %w", err)
+		}
+		cw.Flush()
+		if err := cw.Error(); err != nil {
+			return fmt.Errorf("failed to write audit export: %w", err)
+		}
+		return nil
+	}
+	if err := writeRow(csvHeader); err != nil {
+		return nil, err
+	}
+	return func(e *audit.Entry) error {
+		r := newEntryRecord(e)
+		details, err := json.Marshal(r.Details)
+		if err != nil {
+			return fmt.Errorf("failed to encode audit event details: %w", err)
+		}
+		return writeRow([]string{
+			strconv.FormatInt(r.Seq, 10),
+			r.OccurredAt.Format(time.RFC3339Nano),
+			r.Type,
+			r.UserID,
+			r.ClientIP,
+			string(details),
+		})
+	}, nil
+}
+
+// newEntryRecord converts an audit entry into its exported representation.
+func newEntryRecord(e *audit.Entry) entryRecord {
+	r := entryRecord{
+		Seq:        e.Seq,
+		OccurredAt: e.OccurredAt.UTC(),
+		Type:       e.Type,
+		ClientIP:   e.ClientIP,
+		Details:    e.Details,
+	}
+	if e.UserID != uuid.Nil {
+		r.UserID = e.UserID.String()
+	}
+	if r.Details == nil {
+		r.Details = map[string]string{}
+	}
+	return r
+}
@@ -0,0 +1,220 @@
+package auditexport
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/audit"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/crypto"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/auditlog"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockRepository implements Repository for testing, paging through the entries like the database.
+type mockRepository struct {
+	err     error
+	entries []*audit.Entry
+	loads   int
+}
+
+func (m *mockRepository) Load(_ context.Context, params repository.LoadParams) ([]*audit.Entry, error) {
+	m.loads++
+	if m.err != nil {
+		return nil, m.err
+	}
+	// page collects the entries after params.After up to the limit.
+	var page []*audit.Entry
+	for _, e := range m.entries {
+		if e.Seq > params.After && len(page) < params.Limit {
+			page = append(page, e)
+		}
+	}
+	return page, nil
+}
+
+// mockAuditRecorder implements AuditRecorder for testing.
+type mockAuditRecorder struct {
+	events []audit.Event
+}
+
+func (m *mockAuditRecorder) Record(_ context.Context, e audit.Event) {
+	m.events = append(m.events, e)
+}
+
+func TestService_Export(t *testing.T) {
+	t.Parallel()
+
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+	userID := uuid.MustParse("6f1c2a7e-0d5b-4c55-9a3e-1f2b3c4d5e6f")
+	key := ed25519.NewKeyFromSeed(make([]byte, ed25519.SeedSize))
+	entries := []*audit.Entry{
+		{
+			Event: audit.Event{
+				Type:       audit.EventLegalHoldPlaced,
+				UserID:     userID,
+				OccurredAt: time.Date(2026, 2, 3, 4, 5, 6, 0, time.UTC),
+				Details:    map[string]string{"reason": "=HYPERLINK(\"x\")"},
+			},
+			ClientIP: "203.0.113.7",
+			Seq:      7,
+		},
+		{
+			Event: audit.Event{Type: audit.EventRowTampered, OccurredAt: time.Date(2026, 2, 4, 0, 0, 0, 0, time.UTC)},
+			Seq:   9,
+		},
+	}
+	// many holds more entries than fit on one page.
+	many := make([]*audit.Entry, pageSize+1)
+	for i := range many {
+		many[i] = &audit.Entry{Event: audit.Event{Type: audit.EventItemRevealed, OccurredAt: from}, Seq: int64(i + 1)}
+	}
+
+	tests := []struct {
+		key        ed25519.PrivateKey
+		loadErr    error
+		wantErr    error
+		entries    []*audit.Entry
+		name       string
+		wantFile   string
+		params     ExportParams
+		wantEvents int64
+		wantLoads  int
+	}{
+		{
+			name:    "json lines",
+			key:     key,
+			entries: entries,
+			params:  ExportParams{From: from, To: to, Format: FormatJSONL},
+			wantFile: `{"seq":7,"occurred_at":"2026-02-03T04:05:06Z","event_type":"legal_hold.placed",` +
+				`"user_id":"6f1c2a7e-0d5b-4c55-9a3e-1f2b3c4d5e6f","client_ip":"203.0.113.7",` +
+				`"details":{"reason":"=HYPERLINK(\"x\")"}}` + "\n" +
+				`{"seq":9,"occurred_at":"2026-02-04T00:00:00Z","event_type":"integrity.row_tampered","details":{}}` + "\n",
+			wantEvents: 2,
+			wantLoads:  1,
+		},
+		{
+			name:    "csv",
+			key:     key,
+			entries: entries,
+			params:  ExportParams{From: from, To: to, Format: FormatCSV},
+			wantFile: "seq,occurred_at,event_type,user_id,client_ip,details\n" +
+				`7,2026-02-03T04:05:06Z,legal_hold.placed,6f1c2a7e-0d5b-4c55-9a3e-1f2b3c4d5e6f,203.0.113.7,` +
+				`"{""reason"":""=HYPERLINK(\""x\"")""}"` + "\n" +
+				"9,2026-02-04T00:00:00Z,integrity.row_tampered,,,{}\n",
+			wantEvents: 2,
+			wantLoads:  1,
+		},
+		{
+			name:       "empty period",
+			key:        key,
+			params:     ExportParams{From: from, To: to, Format: FormatJSONL},
+			wantEvents: 0,
+			wantLoads:  1,
+		},
+		{
+			name:       "entries over several pages",
+			key:        key,
+			entries:    many,
+			params:     ExportParams{From: from, To: to, Format: FormatJSONL},
+			wantEvents: pageSize + 1,
+			wantLoads:  2,
+		},
+		{
+			name:    "unsupported format",
+			key:     key,
+			params:  ExportParams{From: from, To: to, Format: "xml"},
+			wantErr: ErrFormatUnsupported,
+		},
+		{
+			name:    "period without a start",
+			key:     key,
+			params:  ExportParams{To: to, Format: FormatJSONL},
+			wantErr: ErrIncorrectPeriod,
+		},
+		{
+			name:    "period ending before it starts",
+			key:     key,
+			params:  ExportParams{From: to, To: from, Format: FormatJSONL},
+			wantErr: ErrIncorrectPeriod,
+		},
+		{
+			name:    "no signing key",
+			params:  ExportParams{From: from, To: to, Format: FormatJSONL},
+			wantErr: ErrSigningKeyNotConfigured,
+		},
+		{
+			name:      "repository error",
+			key:       key,
+			loadErr:   errors.New("connection lost"),
+			params:    ExportParams{From: from, To: to, Format: FormatJSONL},
+			wantErr:   ErrAuditExportTechError,
+			wantLoads: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &mockRepository{entries: tt.entries, err: tt.loadErr}
+			recorder := &mockAuditRecorder{}
+			s := NewService(repo, tt.key, recorder)
+			// buf receives the exported file.
+			var buf bytes.Buffer
+
+			digest, err := s.Export(context.Background(), tt.params, &buf)
+
+			assert.Equal(t, tt.wantLoads, repo.loads)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, digest)
+				assert.Empty(t, recorder.events)
+				if tt.loadErr == nil {
+					assert.Zero(t, buf.Len())
+				}
+				return
+			}
+			require.NoError(t, err)
+			if tt.wantFile != "" {
+				assert.Equal(t, tt.wantFile, buf.String())
+			}
+			if tt.params.Format == FormatCSV {
+				_, err := csv.NewReader(strings.NewReader(buf.String())).ReadAll()
+				require.NoError(t, err)
+			}
+
+			publicKey := key.Public().(ed25519.PublicKey)
+			assert.True(t, ed25519.Verify(publicKey, digest.Document, digest.Signature))
+			assert.Equal(t, crypto.Ed25519KeyID(publicKey), digest.KeyID)
+
+			sum := sha256.Sum256(buf.Bytes())
+			// doc holds the decoded digest file.
+			var doc digestDocument
+			require.NoError(t, json.Unmarshal(digest.Document, &doc))
+			assert.Equal(t, tt.params.Format.FileName(), doc.File)
+			assert.Equal(t, tt.params.Format, doc.Format)
+			assert.Equal(t, from, doc.From)
+			assert.Equal(t, to, doc.To)
+			assert.Equal(t, tt.wantEvents, doc.Events)
+			assert.Equal(t, hex.EncodeToString(sum[:]), doc.SHA256)
+			assert.Equal(t, "Ed25519", doc.SignatureAlgorithm)
+			assert.Equal(t, digest.KeyID, doc.KeyID)
+
+			require.Len(t, recorder.events, 1)
+			assert.Equal(t, audit.EventAuditExported, recorder.events[0].Type)
+			assert.Equal(t, doc.SHA256, recorder.events[0].Details["sha256"])
+		})
+	}
+}
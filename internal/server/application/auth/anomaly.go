@@ -0,0 +1,464 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"net/netip"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/notification"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/audit"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/auth"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/device"
+	domainNotification "github.com/gdyunin/aegis-vault-keeper/internal/server/domain/notification"
+	repositoryDevice "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/device"
+	"github.com/google/uuid"
+)
+
+// stepUpCodeSpace bounds the numeric step-up verification codes to six digits.
+const stepUpCodeSpace = 1_000_000
+
+// approvalTokenSize is the number of random bytes in an approval link token.
+const approvalTokenSize = 32
+
+// approvalPath is the API path approval links point to.
+const approvalPath = "/api/auth/login/approve"
+
+// stepUpSubject is the subject of the step-up verification notification.
+const stepUpSubject = "New sign-in to your AegisVaultKeeper vault"
+
+// Step-up states recorded in suspicious login audit events.
+const (
+	// stepUpRequired means a verification code was sent and the login is on hold.
+	stepUpRequired = "required"
+	// stepUpUnavailable means the user cannot receive a code, so the login proceeded.
+	stepUpUnavailable = "unavailable"
+)
+
+// Approval methods recorded in login approval and device verification audit events.
+const (
+	// approvedByCode means the user entered the verification code sent to them.
+	approvedByCode = "code"
+	// approvedByLink means the user opened the approval link sent to them.
+	approvedByLink = "link"
+	// approvedByTrustedDevice means the user approved the login from an already trusted device.
+	approvedByTrustedDevice = "trusted_device"
+	// claimedAfterApproval means the held client claimed a login approved by link or trusted device.
+	claimedAfterApproval = "approval"
+	// approvedByRecoveryCode means the user entered one of their recovery codes instead of the verification code.
+	approvedByRecoveryCode = "recovery_code"
+)
+
+// DeviceRepository defines the interface for login device persistence operations.
+type DeviceRepository interface {
+	// Save persists a device using the provided parameters.
+	Save(ctx context.Context, params repositoryDevice.SaveParams) error
+	// Load retrieves devices using the provided parameters.
+	Load(ctx context.Context, params repositoryDevice.LoadParams) ([]*device.Device, error)
+}
+
+// CountryResolver defines the interface for resolving client addresses to countries.
+type CountryResolver interface {
+	// Country returns the ISO 3166-1 alpha-2 country code of ip, or an empty string if unknown.
+	Country(ip netip.Addr) (string, error)
+}
+
+// Notifier defines the interface for delivering notifications to users over their channels.
+type Notifier interface {
+	// Reachable reports whether the user can receive notifications of the category.
+	Reachable(ctx context.Context, params notification.ReachableParams) (bool, error)
+	// Notify delivers a notification to the channels of the user subscribed to its category.
+	Notify(ctx context.Context, params notification.NotifyParams) error
+}
+
+// AuditRecorder defines the interface for recording security audit events.
+type AuditRecorder interface {
+	// Record stores the audit event.
+	Record(ctx context.Context, e audit.Event)
+}
+
+// AnomalyDetector flags logins from unrecognized devices and locations and holds them
+// until the user confirms a verification code sent over their security notification channels, or approves
+// the login by the sent link or from an already trusted device after which the held client claims it.
+type AnomalyDetector struct {
+	// devices persists login fingerprints and pending challenges.
+	devices DeviceRepository
+	// geo resolves client countries; nil limits locations to client networks.
+	geo CountryResolver
+	// notifier delivers verification codes; nil disables step-up verification.
+	notifier Notifier
+	// audit records suspicious logins and verified devices.
+	audit AuditRecorder
+	// approvalBaseURL is the public server URL approval links start with; empty disables approval links.
+	approvalBaseURL string
+	// challengeTTL limits how long a verification code is accepted.
+	challengeTTL time.Duration
+}
+
+// NewAnomalyDetector creates a new login anomaly detector.
+// The country resolver and notifier may be nil when GeoIP or notifications are not configured;
+// an empty approval base URL leaves approval links out of the notifications.
+func NewAnomalyDetector(
+	devices DeviceRepository,
+	geo CountryResolver,
+	notifier Notifier,
+	audit AuditRecorder,
+	approvalBaseURL string,
+	challengeTTL time.Duration,
+) *AnomalyDetector {
+	return &AnomalyDetector{
+		devices:         devices,
+		geo:             geo,
+		notifier:        notifier,
+		audit:           audit,
+		approvalBaseURL: strings.TrimRight(approvalBaseURL, "/"),
+		challengeTTL:    challengeTTL,
+	}
+}
+
+// Assess records the fingerprint of a login with a verified password.
+// It returns a step-up challenge when the login comes from an unrecognized device or location
+// and the user can receive a verification code, or nil when the login may proceed.
+// Users who cannot receive security notifications or servers without a notifier only get the login audited.
+func (d *AnomalyDetector) Assess(ctx context.Context, u *auth.User, params LoginParams) (*StepUpChallenge, error) {
+	candidate, err := device.NewDevice(device.NewDeviceParams{
+		UserID:    u.ID,
+		IP:        params.IP,
+		UserAgent: params.UserAgent,
+		Country:   d.country(params.IP),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fingerprint login: %w", err)
+	}
+
+	known, err := d.devices.Load(ctx, repositoryDevice.LoadParams{UserID: u.ID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load devices: %w", err)
+	}
+
+	now := time.Now()
+	dev := candidate
+	for _, k := range known {
+		if k.Fingerprint == candidate.Fingerprint && k.Location == candidate.Location {
+			dev = k
+			break
+		}
+	}
+	dev.Seen(params.IP, now)
+
+	reasons := candidate.Anomalies(known)
+	if dev.Trusted || len(reasons) == 0 {
+		dev.Trust(now)
+		return nil, d.save(ctx, dev)
+	}
+
+	reachable, err := d.reachable(ctx, u.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !reachable {
+		d.recordSuspicious(ctx, dev, reasons, stepUpUnavailable)
+		dev.Trust(now)
+		return nil, d.save(ctx, dev)
+	}
+
+	code, err := generateStepUpCode()
+	if err != nil {
+		return nil, err
+	}
+	var approvalToken string
+	if d.approvalBaseURL != "" {
+		if approvalToken, err = generateApprovalToken(); err != nil {
+			return nil, err
+		}
+	}
+	dev.IssueChallenge(code, approvalToken, now.Add(d.challengeTTL))
+	if err := d.save(ctx, dev); err != nil {
+		return nil, err
+	}
+	d.recordSuspicious(ctx, dev, reasons, stepUpRequired)
+
+	text := stepUpText(dev, code, d.approvalLink(dev.ID, approvalToken))
+	err = d.notifier.Notify(ctx, notification.NotifyParams{
+		Category: domainNotification.CategorySecurity,
+		Subject:  stepUpSubject,
+		Body:     text,
+		UserID:   u.ID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to send verification code: %w", err)
+	}
+	return &StepUpChallenge{ID: dev.ID, ExpiresAt: dev.ChallengeExpiresAt, Reasons: reasons}, nil
+}
+
+// Verify checks the verification code of a step-up challenge and trusts the device on success.
+// It returns the identifier of the user completing the login.
+func (d *AnomalyDetector) Verify(ctx context.Context, params VerifyLoginParams) (uuid.UUID, error) {
+	dev, err := d.load(ctx, params.ChallengeID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	verifyErr := dev.VerifyChallenge(params.Code, time.Now())
+	if err := d.save(ctx, dev); err != nil {
+		return uuid.Nil, err
+	}
+	if verifyErr != nil {
+		return uuid.Nil, fmt.Errorf("failed to verify device: %w", verifyErr)
+	}
+
+	d.record(ctx, audit.EventDeviceVerified, dev, approvedByCode)
+	return dev.UserID, nil
+}
+
+// Approve checks the token of an approval link sent to the user and approves the held login on success.
+// The held client completes the login with Claim.
+func (d *AnomalyDetector) Approve(ctx context.Context, params ApproveLoginParams) error {
+	dev, err := d.load(ctx, params.ChallengeID)
+	if err != nil {
+		return err
+	}
+
+	approveErr := dev.Approve(params.Token, time.Now())
+	if err := d.save(ctx, dev); err != nil {
+		return err
+	}
+	if approveErr != nil {
+		return fmt.Errorf("failed to approve login: %w", approveErr)
+	}
+
+	d.record(ctx, audit.EventLoginApproved, dev, approvedByLink)
+	return nil
+}
+
+// ApproveTrusted approves a held login of the user from a session of an already trusted device.
+// Held logins of other users are reported as not found.
+func (d *AnomalyDetector) ApproveTrusted(ctx context.Context, params ApprovePendingLoginParams) error {
+	dev, err := d.load(ctx, params.ChallengeID)
+	if err != nil {
+		return err
+	}
+	if dev.UserID != params.UserID {
+		return fmt.Errorf("failed to load device: %w", repositoryDevice.ErrDeviceNotFound)
+	}
+
+	if err := dev.ApproveTrusted(time.Now()); err != nil {
+		return fmt.Errorf("failed to approve login: %w", err)
+	}
+	if err := d.save(ctx, dev); err != nil {
+		return err
+	}
+
+	d.record(ctx, audit.EventLoginApproved, dev, approvedByTrustedDevice)
+	return nil
+}
+
+// Claim completes an approved held login and trusts the device.
+// It returns the identifier of the user completing the login, or device.ErrApprovalPending
+// while the login still awaits approval.
+func (d *AnomalyDetector) Claim(ctx context.Context, params ClaimLoginParams) (uuid.UUID, error) {
+	dev, err := d.load(ctx, params.ChallengeID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	if err := dev.Claim(time.Now()); err != nil {
+		return uuid.Nil, fmt.Errorf("failed to claim login: %w", err)
+	}
+	if err := d.save(ctx, dev); err != nil {
+		return uuid.Nil, err
+	}
+
+	d.record(ctx, audit.EventDeviceVerified, dev, claimedAfterApproval)
+	return dev.UserID, nil
+}
+
+// Recover completes a held login with a recovery code instead of the verification code and trusts the device.
+// The challenge must still be pending; redeem then checks and consumes the recovery code of the user holding it.
+// It returns the identifier of the user completing the login.
+func (d *AnomalyDetector) Recover(
+	ctx context.Context,
+	challengeID uuid.UUID,
+	redeem func(ctx context.Context, userID uuid.UUID) error,
+) (uuid.UUID, error) {
+	dev, err := d.load(ctx, challengeID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	now := time.Now()
+	if err := dev.ApproveTrusted(now); err != nil {
+		return uuid.Nil, fmt.Errorf("failed to approve login: %w", err)
+	}
+	if err := redeem(ctx, dev.UserID); err != nil {
+		return uuid.Nil, fmt.Errorf("failed to redeem recovery code: %w", err)
+	}
+	if err := dev.Claim(now); err != nil {
+		return uuid.Nil, fmt.Errorf("failed to claim login: %w", err)
+	}
+	if err := d.save(ctx, dev); err != nil {
+		return uuid.Nil, err
+	}
+
+	d.record(ctx, audit.EventDeviceVerified, dev, approvedByRecoveryCode)
+	return dev.UserID, nil
+}
+
+// Pending lists the held logins of the user that can still be approved.
+func (d *AnomalyDetector) Pending(ctx context.Context, userID uuid.UUID) ([]*PendingLogin, error) {
+	devices, err := d.devices.Load(ctx, repositoryDevice.LoadParams{UserID: userID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load devices: %w", err)
+	}
+
+	now := time.Now()
+	pending := make([]*PendingLogin, 0)
+	for _, dev := range devices {
+		if dev.Trusted || !dev.Pending(now) {
+			continue
+		}
+		pending = append(pending, &PendingLogin{
+			ID:         dev.ID,
+			IP:         dev.IP,
+			UserAgent:  dev.UserAgent,
+			Location:   dev.Location,
+			LastSeenAt: dev.LastSeenAt,
+			ExpiresAt:  dev.ChallengeExpiresAt,
+			Approved:   dev.Approved,
+		})
+	}
+	return pending, nil
+}
+
+// country resolves the country of the client address, treating lookup failures as unknown.
+func (d *AnomalyDetector) country(ip netip.Addr) string {
+	if d.geo == nil || !ip.IsValid() {
+		return ""
+	}
+	country, err := d.geo.Country(ip)
+	if err != nil {
+		return ""
+	}
+	return country
+}
+
+// reachable reports whether verification codes can be sent to the user.
+func (d *AnomalyDetector) reachable(ctx context.Context, userID uuid.UUID) (bool, error) {
+	if d.notifier == nil {
+		return false, nil
+	}
+	reachable, err := d.notifier.Reachable(ctx, notification.ReachableParams{
+		Category: domainNotification.CategorySecurity,
+		UserID:   userID,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to resolve notification channels: %w", err)
+	}
+	return reachable, nil
+}
+
+// load retrieves the device holding the specified challenge.
+func (d *AnomalyDetector) load(ctx context.Context, challengeID uuid.UUID) (*device.Device, error) {
+	devices, err := d.devices.Load(ctx, repositoryDevice.LoadParams{ID: challengeID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load device: %w", err)
+	}
+	return devices[0], nil
+}
+
+// save persists the device state.
+func (d *AnomalyDetector) save(ctx context.Context, dev *device.Device) error {
+	if err := d.devices.Save(ctx, repositoryDevice.SaveParams{Entity: dev}); err != nil {
+		return fmt.Errorf("failed to save device: %w", err)
+	}
+	return nil
+}
+
+// record emits an audit event of a verified device or approved login.
+func (d *AnomalyDetector) record(ctx context.Context, eventType string, dev *device.Device, method string) {
+	d.audit.Record(ctx, audit.Event{
+		Type:       eventType,
+		UserID:     dev.UserID,
+		OccurredAt: time.Now(),
+		Details: map[string]string{
+			"device_id": dev.ID.String(),
+			"location":  dev.Location,
+			"method":    method,
+		},
+	})
+}
+
+// approvalLink returns the sent link approving the held login, or an empty string without a token.
+func (d *AnomalyDetector) approvalLink(challengeID uuid.UUID, token string) string {
+	if token == "" {
+		return ""
+	}
+	query := url.Values{"challenge_id": {challengeID.String()}, "token": {token}}
+	return d.approvalBaseURL + approvalPath + "?" + query.Encode()
+}
+
+// recordSuspicious emits the audit event of a login from an unrecognized device or location.
+func (d *AnomalyDetector) recordSuspicious(ctx context.Context, dev *device.Device, reasons []string, stepUp string) {
+	d.audit.Record(ctx, audit.Event{
+		Type:       audit.EventLoginSuspicious,
+		UserID:     dev.UserID,
+		OccurredAt: time.Now(),
+		Details: map[string]string{
+			"device_id": dev.ID.String(),
+			"location":  dev.Location,
+			"reasons":   strings.Join(reasons, ","),
+			"step_up":   stepUp,
+		},
+	})
+}
+
+// generateStepUpCode returns a random six-digit verification code.
+func generateStepUpCode() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(stepUpCodeSpace))
+	if err != nil {
+		return "", fmt.Errorf("failed to generate verification code: %w", err)
+	}
+	return fmt.Sprintf("%06d", n.Int64()), nil
+}
+
+// generateApprovalToken returns a random URL-safe approval link token.
+func generateApprovalToken() (string, error) {
+	b := make([]byte, approvalTokenSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate approval token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// stepUpText renders the notification carrying the verification code and the optional approval link.
+func stepUpText(dev *device.Device, code, link string) string {
+	var b strings.Builder
+	b.WriteString("A sign-in to your vault was attempted from an unrecognized device or location.\n\n")
+	fmt.Fprintf(&b, "Device:   %s\n", valueOrUnknown(dev.UserAgent))
+	fmt.Fprintf(&b, "Location: %s\n", valueOrUnknown(dev.Location))
+	if dev.IP.IsValid() {
+		fmt.Fprintf(&b, "Address:  %s\n", dev.IP)
+	}
+	fmt.Fprintf(&b, "Time:     %s\n\n", dev.LastSeenAt.UTC().Format(time.RFC3339))
+	fmt.Fprintf(&b, "If this was you, enter the verification code %s to complete the sign-in.\n", code)
+	if link != "" {
+		fmt.Fprintf(&b, "You can also approve it by opening this link:\n%s\n", link)
+	}
+	b.WriteString("The sign-in can be approved from a device you are already signed in on as well.\n")
+	fmt.Fprintf(&b, "The code expires at %s.\n\n", dev.ChallengeExpiresAt.UTC().Format(time.RFC3339))
+	b.WriteString("If this was not you, do not share the code and change your password.\n")
+	return b.String()
+}
+
+// valueOrUnknown substitutes a placeholder for empty values.
+func valueOrUnknown(s string) string {
+	if s == "" {
+		return "unknown"
+	}
+	return s
+}
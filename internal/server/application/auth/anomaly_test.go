@@ -0,0 +1,501 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/netip"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/notification"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/audit"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/auth"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/device"
+	domainNotification "github.com/gdyunin/aegis-vault-keeper/internal/server/domain/notification"
+	repositoryDevice "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/device"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockDeviceRepository keeps devices in memory for testing.
+type mockDeviceRepository struct {
+	saveErr error
+	devices map[uuid.UUID]*device.Device
+	mu      sync.Mutex
+}
+
+func newMockDeviceRepository(devices ...*device.Device) *mockDeviceRepository {
+	m := &mockDeviceRepository{devices: make(map[uuid.UUID]*device.Device)}
+	for _, d := range devices {
+		m.devices[d.ID] = d
+	}
+	return m
+}
+
+func (m *mockDeviceRepository) Save(_ context.Context, params repositoryDevice.SaveParams) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.saveErr != nil {
+		return m.saveErr
+	}
+	m.devices[params.Entity.ID] = params.Entity
+	return nil
+}
+
+func (m *mockDeviceRepository) Load(
+	_ context.Context,
+	params repositoryDevice.LoadParams,
+) ([]*device.Device, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if params.ID != uuid.Nil {
+		d, ok := m.devices[params.ID]
+		if !ok {
+			return nil, repositoryDevice.ErrDeviceNotFound
+		}
+		return []*device.Device{d}, nil
+	}
+	var result []*device.Device
+	for _, d := range m.devices {
+		if d.UserID == params.UserID {
+			result = append(result, d)
+		}
+	}
+	return result, nil
+}
+
+// mockCountryResolver resolves addresses from a fixed table.
+type mockCountryResolver map[netip.Addr]string
+
+func (m mockCountryResolver) Country(ip netip.Addr) (string, error) {
+	return m[ip], nil
+}
+
+// mockNotifier captures sent notifications.
+type mockNotifier struct {
+	sendErr     error
+	unreachable bool
+	userID      uuid.UUID
+	category    domainNotification.Category
+	body        string
+}
+
+func (m *mockNotifier) Reachable(context.Context, notification.ReachableParams) (bool, error) {
+	return !m.unreachable, nil
+}
+
+func (m *mockNotifier) Notify(_ context.Context, params notification.NotifyParams) error {
+	m.userID, m.category, m.body = params.UserID, params.Category, params.Body
+	return m.sendErr
+}
+
+// mockAuditRecorder captures recorded events.
+type mockAuditRecorder struct {
+	events []audit.Event
+}
+
+func (m *mockAuditRecorder) Record(_ context.Context, e audit.Event) {
+	m.events = append(m.events, e)
+}
+
+// stepUpCodeRe extracts the verification code from the notification text.
+var stepUpCodeRe = regexp.MustCompile(`verification code (\d{6})`)
+
+// approvalLinkRe extracts the approval link token from the notification text.
+var approvalLinkRe = regexp.MustCompile(`https://vault\.example\.com/api/auth/login/approve\?\S*token=([\w-]+)`)
+
+func TestAnomalyDetector_Assess(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	homeIP := netip.MustParseAddr("198.51.100.7")
+	awayIP := netip.MustParseAddr("203.0.113.9")
+	geo := mockCountryResolver{homeIP: "DE", awayIP: "BR"}
+
+	trustedDevice := func() *device.Device {
+		return &device.Device{
+			ID: uuid.New(), UserID: userID, Fingerprint: device.FingerprintOf("Firefox"),
+			UserAgent: "Firefox", Location: "DE", Trusted: true,
+		}
+	}
+
+	tests := []struct {
+		notifier      *mockNotifier
+		known         []*device.Device
+		name          string
+		login         string
+		params        LoginParams
+		wantReasons   []string
+		wantStepUp    string
+		wantChallenge bool
+	}{
+		{
+			name:     "first login is trusted",
+			notifier: &mockNotifier{},
+			login:    "user@example.com",
+			params:   LoginParams{IP: awayIP, UserAgent: "Chrome"},
+		},
+		{
+			name:     "known device and location",
+			notifier: &mockNotifier{},
+			known:    []*device.Device{trustedDevice()},
+			login:    "user@example.com",
+			params:   LoginParams{IP: homeIP, UserAgent: "Firefox"},
+		},
+		{
+			name:          "new device and location",
+			notifier:      &mockNotifier{},
+			known:         []*device.Device{trustedDevice()},
+			login:         "user@example.com",
+			params:        LoginParams{IP: awayIP, UserAgent: "Chrome"},
+			wantChallenge: true,
+			wantReasons:   []string{device.ReasonNewDevice, device.ReasonNewLocation},
+			wantStepUp:    stepUpRequired,
+		},
+		{
+			name:        "unreachable user is only audited",
+			notifier:    &mockNotifier{unreachable: true},
+			known:       []*device.Device{trustedDevice()},
+			login:       "user",
+			params:      LoginParams{IP: awayIP, UserAgent: "Firefox"},
+			wantReasons: []string{device.ReasonNewLocation},
+			wantStepUp:  stepUpUnavailable,
+		},
+		{
+			name:        "missing notifier is only audited",
+			known:       []*device.Device{trustedDevice()},
+			login:       "user@example.com",
+			params:      LoginParams{IP: homeIP, UserAgent: "Chrome"},
+			wantReasons: []string{device.ReasonNewDevice},
+			wantStepUp:  stepUpUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := newMockDeviceRepository(tt.known...)
+			recorder := &mockAuditRecorder{}
+			var notifier Notifier
+			if tt.notifier != nil {
+				notifier = tt.notifier
+			}
+			d := NewAnomalyDetector(repo, geo, notifier, recorder, "", 10*time.Minute)
+			u := &auth.User{ID: userID, Login: tt.login}
+
+			challenge, err := d.Assess(context.Background(), u, tt.params)
+			require.NoError(t, err)
+
+			if !tt.wantChallenge {
+				assert.Nil(t, challenge)
+				for _, dev := range repo.devices {
+					assert.True(t, dev.Trusted)
+				}
+			} else {
+				require.NotNil(t, challenge)
+				assert.Equal(t, tt.wantReasons, challenge.Reasons)
+				assert.Equal(t, userID, tt.notifier.userID)
+				assert.Equal(t, domainNotification.CategorySecurity, tt.notifier.category)
+				require.Contains(t, repo.devices, challenge.ID)
+				assert.False(t, repo.devices[challenge.ID].Trusted)
+				assert.Regexp(t, stepUpCodeRe, tt.notifier.body)
+			}
+
+			if tt.wantStepUp == "" {
+				assert.Empty(t, recorder.events)
+				return
+			}
+			require.Len(t, recorder.events, 1)
+			assert.Equal(t, audit.EventLoginSuspicious, recorder.events[0].Type)
+			assert.Equal(t, tt.wantStepUp, recorder.events[0].Details["step_up"])
+		})
+	}
+}
+
+func TestAnomalyDetector_Verify(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	known := &device.Device{
+		ID: uuid.New(), UserID: userID, Fingerprint: device.FingerprintOf("Firefox"), Trusted: true,
+	}
+
+	tests := []struct {
+		wantErr  error
+		name     string
+		code     func(sent string) string
+		attempts int
+	}{
+		{
+			name: "correct code",
+			code: func(sent string) string { return sent },
+		},
+		{
+			name:     "wrong code",
+			code:     func(string) string { return "000000x" },
+			wantErr:  device.ErrChallengeMismatch,
+			attempts: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := newMockDeviceRepository(&device.Device{
+				ID: known.ID, UserID: userID, Fingerprint: known.Fingerprint, Trusted: true,
+			})
+			notifier := &mockNotifier{}
+			recorder := &mockAuditRecorder{}
+			d := NewAnomalyDetector(repo, nil, notifier, recorder, "", 10*time.Minute)
+
+			challenge, err := d.Assess(
+				context.Background(),
+				&auth.User{ID: userID, Login: "user@example.com"},
+				LoginParams{UserAgent: "Chrome"},
+			)
+			require.NoError(t, err)
+			require.NotNil(t, challenge)
+			sent := stepUpCodeRe.FindStringSubmatch(notifier.body)[1]
+
+			gotUserID, err := d.Verify(
+				context.Background(),
+				VerifyLoginParams{ChallengeID: challenge.ID, Code: tt.code(sent)},
+			)
+
+			dev := repo.devices[challenge.ID]
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.False(t, dev.Trusted)
+				assert.Equal(t, tt.attempts, dev.ChallengeAttempts)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, userID, gotUserID)
+			assert.True(t, dev.Trusted)
+			assert.Equal(t, audit.EventDeviceVerified, recorder.events[len(recorder.events)-1].Type)
+		})
+	}
+}
+
+func TestAnomalyDetector_VerifyUnknownChallenge(t *testing.T) {
+	t.Parallel()
+
+	d := NewAnomalyDetector(newMockDeviceRepository(), nil, nil, &mockAuditRecorder{}, "", time.Minute)
+
+	_, err := d.Verify(context.Background(), VerifyLoginParams{ChallengeID: uuid.New(), Code: "123456"})
+
+	require.ErrorIs(t, err, repositoryDevice.ErrDeviceNotFound)
+	assert.ErrorIs(t, mapError(err), ErrAuthStepUpFailed)
+}
+
+func TestAnomalyDetector_Recover(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+
+	tests := []struct {
+		redeemErr error
+		wantErr   error
+		name      string
+		expired   bool
+	}{
+		{name: "recovery code accepted"},
+		{name: "recovery code rejected", redeemErr: ErrAuthRecoveryCodeInvalid, wantErr: ErrAuthRecoveryCodeInvalid},
+		{name: "expired challenge", expired: true, wantErr: device.ErrChallengeExpired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := newMockDeviceRepository(&device.Device{
+				ID: uuid.New(), UserID: userID, Fingerprint: device.FingerprintOf("Firefox"), Trusted: true,
+			})
+			recorder := &mockAuditRecorder{}
+			ttl := 10 * time.Minute
+			if tt.expired {
+				ttl = -time.Minute
+			}
+			d := NewAnomalyDetector(repo, nil, &mockNotifier{}, recorder, "", ttl)
+
+			challenge, err := d.Assess(
+				context.Background(),
+				&auth.User{ID: userID, Login: "user@example.com"},
+				LoginParams{UserAgent: "Chrome"},
+			)
+			require.NoError(t, err)
+			require.NotNil(t, challenge)
+
+			// redeemedFor holds the user the recovery code was redeemed for.
+			var redeemedFor uuid.UUID
+			gotUserID, err := d.Recover(context.Background(), challenge.ID, func(_ context.Context, id uuid.UUID) error {
+				redeemedFor = id
+				return tt.redeemErr
+			})
+
+			dev := repo.devices[challenge.ID]
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.False(t, dev.Trusted)
+				if tt.expired {
+					assert.Equal(t, uuid.Nil, redeemedFor, "codes are not spent on expired challenges")
+				}
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, userID, gotUserID)
+			assert.Equal(t, userID, redeemedFor)
+			assert.True(t, dev.Trusted)
+			last := recorder.events[len(recorder.events)-1]
+			assert.Equal(t, audit.EventDeviceVerified, last.Type)
+			assert.Equal(t, approvedByRecoveryCode, last.Details["method"])
+		})
+	}
+}
+
+func TestAnomalyDetector_AssessNotifyFailure(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	repo := newMockDeviceRepository(&device.Device{
+		ID: uuid.New(), UserID: userID, Fingerprint: device.FingerprintOf("Firefox"), Trusted: true,
+	})
+	sendErr := errors.New("relay unavailable")
+	d := NewAnomalyDetector(repo, nil, &mockNotifier{sendErr: sendErr}, &mockAuditRecorder{}, "", time.Minute)
+
+	_, err := d.Assess(
+		context.Background(),
+		&auth.User{ID: userID, Login: "user@example.com"},
+		LoginParams{UserAgent: "Chrome"},
+	)
+
+	assert.ErrorIs(t, err, sendErr)
+}
+
+func TestAnomalyDetector_ApproveAndClaim(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+
+	tests := []struct {
+		approve   func(d *AnomalyDetector, challengeID uuid.UUID, token string) error
+		wantErr   error
+		name      string
+		wantClaim bool
+	}{
+		{
+			name: "approved by link",
+			approve: func(d *AnomalyDetector, challengeID uuid.UUID, token string) error {
+				return d.Approve(context.Background(), ApproveLoginParams{ChallengeID: challengeID, Token: token})
+			},
+			wantClaim: true,
+		},
+		{
+			name: "approved from trusted device",
+			approve: func(d *AnomalyDetector, challengeID uuid.UUID, _ string) error {
+				return d.ApproveTrusted(
+					context.Background(),
+					ApprovePendingLoginParams{ChallengeID: challengeID, UserID: userID},
+				)
+			},
+			wantClaim: true,
+		},
+		{
+			name: "wrong link token",
+			approve: func(d *AnomalyDetector, challengeID uuid.UUID, _ string) error {
+				return d.Approve(context.Background(), ApproveLoginParams{ChallengeID: challengeID, Token: "forged"})
+			},
+			wantErr: device.ErrChallengeMismatch,
+		},
+		{
+			name: "approval by another user",
+			approve: func(d *AnomalyDetector, challengeID uuid.UUID, _ string) error {
+				return d.ApproveTrusted(
+					context.Background(),
+					ApprovePendingLoginParams{ChallengeID: challengeID, UserID: uuid.New()},
+				)
+			},
+			wantErr: repositoryDevice.ErrDeviceNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := newMockDeviceRepository(&device.Device{
+				ID: uuid.New(), UserID: userID, Fingerprint: device.FingerprintOf("Firefox"), Trusted: true,
+			})
+			notifier := &mockNotifier{}
+			recorder := &mockAuditRecorder{}
+			d := NewAnomalyDetector(repo, nil, notifier, recorder, "https://vault.example.com/", 10*time.Minute)
+
+			challenge, err := d.Assess(
+				context.Background(),
+				&auth.User{ID: userID, Login: "user@example.com"},
+				LoginParams{UserAgent: "Chrome"},
+			)
+			require.NoError(t, err)
+			require.NotNil(t, challenge)
+			link := approvalLinkRe.FindStringSubmatch(notifier.body)
+			require.NotNil(t, link, "notification should carry the approval link")
+
+			pending, err := d.Pending(context.Background(), userID)
+			require.NoError(t, err)
+			require.Len(t, pending, 1)
+			assert.Equal(t, challenge.ID, pending[0].ID)
+
+			_, err = d.Claim(context.Background(), ClaimLoginParams{ChallengeID: challenge.ID})
+			require.ErrorIs(t, err, device.ErrApprovalPending)
+
+			err = tt.approve(d, challenge.ID, link[1])
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				_, err = d.Claim(context.Background(), ClaimLoginParams{ChallengeID: challenge.ID})
+				require.ErrorIs(t, err, device.ErrApprovalPending)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, audit.EventLoginApproved, recorder.events[len(recorder.events)-1].Type)
+
+			gotUserID, err := d.Claim(context.Background(), ClaimLoginParams{ChallengeID: challenge.ID})
+			require.NoError(t, err)
+			assert.Equal(t, userID, gotUserID)
+			assert.True(t, repo.devices[challenge.ID].Trusted)
+
+			_, err = d.Claim(context.Background(), ClaimLoginParams{ChallengeID: challenge.ID})
+			require.ErrorIs(t, err, device.ErrNoChallenge, "an approval can be claimed only once")
+
+			pending, err = d.Pending(context.Background(), userID)
+			require.NoError(t, err)
+			assert.Empty(t, pending)
+		})
+	}
+}
+
+func TestAnomalyDetector_AssessWithoutApprovalURL(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	repo := newMockDeviceRepository(&device.Device{
+		ID: uuid.New(), UserID: userID, Fingerprint: device.FingerprintOf("Firefox"), Trusted: true,
+	})
+	notifier := &mockNotifier{}
+	d := NewAnomalyDetector(repo, nil, notifier, &mockAuditRecorder{}, "", time.Minute)
+
+	challenge, err := d.Assess(
+		context.Background(),
+		&auth.User{ID: userID, Login: "user@example.com"},
+		LoginParams{UserAgent: "Chrome"},
+	)
+	require.NoError(t, err)
+	require.NotNil(t, challenge)
+
+	assert.NotContains(t, notifier.body, "/api/auth/login/approve")
+	assert.Empty(t, repo.devices[challenge.ID].ApprovalHash)
+}
@@ -1,6 +1,11 @@
 package auth
 
-import "time"
+import (
+	"net/netip"
+	"time"
+
+	"github.com/google/uuid"
+)
 
 // RegisterParams contains the parameters required for user registration.
 type RegisterParams struct {
@@ -8,6 +13,16 @@ type RegisterParams struct {
 	Login string
 	// Password specifies the password for the new user account.
 	Password string
+	// InviteCode contains the invite code the user registers with; optional unless registration requires one.
+	InviteCode string
+}
+
+// Registration describes a newly registered user.
+type Registration struct {
+	// RecoveryCodes lists the single-use recovery codes of the user; they are only returned this once.
+	RecoveryCodes []string
+	// UserID identifies the registered user.
+	UserID uuid.UUID
 }
 
 // LoginParams contains the parameters required for user authentication.
@@ -16,6 +31,10 @@ type LoginParams struct {
 	Login string
 	// Password specifies the password for authentication.
 	Password string
+	// IP contains the client address the login comes from.
+	IP netip.Addr
+	// UserAgent contains the reported client software.
+	UserAgent string
 }
 
 // AccessToken represents a JWT access token with its metadata.
@@ -27,3 +46,179 @@ type AccessToken struct {
 	// TokenType specifies the type of token (typically "Bearer").
 	TokenType string
 }
+
+// StepUpChallenge describes the verification required to complete a login
+// from an unrecognized device or location.
+type StepUpChallenge struct {
+	// ExpiresAt specifies when the verification code expires.
+	ExpiresAt time.Time
+	// Reasons lists why the login was flagged.
+	Reasons []string
+	// ID identifies the challenge to verify.
+	ID uuid.UUID
+}
+
+// VerifyLoginParams contains the parameters required to complete a held login.
+type VerifyLoginParams struct {
+	// Code contains the verification code sent to the user.
+	Code string
+	// RecoveryCode contains a recovery code of the user submitted instead of the verification code.
+	RecoveryCode string
+	// ChallengeID identifies the step-up challenge.
+	ChallengeID uuid.UUID
+}
+
+// ApproveLoginParams contains the parameters of an approval link sent to the user.
+type ApproveLoginParams struct {
+	// Token contains the approval token from the link.
+	Token string
+	// ChallengeID identifies the step-up challenge of the held login.
+	ChallengeID uuid.UUID
+}
+
+// ApprovePendingLoginParams contains the parameters required to approve a held login
+// from a session of an already trusted device.
+type ApprovePendingLoginParams struct {
+	// ChallengeID identifies the step-up challenge of the held login.
+	ChallengeID uuid.UUID
+	// UserID identifies the authenticated user approving the login.
+	UserID uuid.UUID
+}
+
+// ClaimLoginParams contains the parameters required to complete an approved held login.
+type ClaimLoginParams struct {
+	// ChallengeID identifies the step-up challenge of the held login.
+	ChallengeID uuid.UUID
+}
+
+// PendingLogin describes a held login awaiting approval.
+type PendingLogin struct {
+	// LastSeenAt contains the time of the held login.
+	LastSeenAt time.Time
+	// ExpiresAt specifies when the login can no longer be approved.
+	ExpiresAt time.Time
+	// IP contains the client address of the held login.
+	IP netip.Addr
+	// UserAgent contains the reported client software.
+	UserAgent string
+	// Location contains the country code, or the network when the country is unknown.
+	Location string
+	// ID identifies the step-up challenge of the held login.
+	ID uuid.UUID
+	// Approved determines whether the login was approved and awaits the claim by the held client.
+	Approved bool
+}
+
+// ChangePasswordParams contains the parameters required to change the password of a user.
+type ChangePasswordParams struct {
+	// OldPassword contains the current password.
+	OldPassword string
+	// NewPassword specifies the password to change to.
+	NewPassword string
+	// UserID identifies the authenticated user changing the password.
+	UserID uuid.UUID
+}
+
+// RegenerateRecoveryCodesParams contains the parameters required to replace the recovery codes of a user.
+type RegenerateRecoveryCodesParams struct {
+	// Password contains the current password re-entered to confirm the request.
+	Password string
+	// UserID identifies the authenticated user replacing the recovery codes.
+	UserID uuid.UUID
+}
+
+// RecoverAccountParams contains the parameters required to regain access to an account with a recovery code.
+type RecoverAccountParams struct {
+	// Login specifies the login of the account.
+	Login string
+	// RecoveryCode contains an unused recovery code of the user.
+	RecoveryCode string
+	// NewPassword specifies the password to reset to.
+	NewPassword string
+}
+
+// UnlockSettings describes how clients derive the unlock key of a user from the unlock secret.
+type UnlockSettings struct {
+	// KDF names the key derivation function.
+	KDF string
+	// Salt contains the salt of the derivation; empty while the user has no unlock secret.
+	Salt []byte
+	// KeySize is the size in bytes of the derived unlock key.
+	KeySize int
+	// Time is the number of passes of the derivation over its memory.
+	Time uint32
+	// MemoryKiB is the memory used by the derivation in KiB.
+	MemoryKiB uint32
+	// Threads is the number of lanes of the derivation.
+	Threads uint8
+	// Enabled reports whether the user has an unlock secret.
+	Enabled bool
+}
+
+// SetUnlockSecretParams contains the parameters required to set or change the unlock secret of a user.
+type SetUnlockSecretParams struct {
+	// Salt contains the salt the new unlock key was derived with.
+	Salt []byte
+	// UnlockKey contains the unlock key derived from the new unlock secret.
+	UnlockKey []byte
+	// CurrentUnlockKey contains the unlock key of the current unlock secret; required to change it.
+	CurrentUnlockKey []byte
+	// Password contains the current password; required to set the first unlock secret.
+	Password string
+	// UserID identifies the authenticated user setting the unlock secret.
+	UserID uuid.UUID
+}
+
+// DuressSettings describes the duress password of a user.
+type DuressSettings struct {
+	// Enabled reports whether logins with a duress password open the decoy vault.
+	Enabled bool
+	// Alert reports whether logins with the duress password raise a silent security alert.
+	Alert bool
+}
+
+// SetDuressPasswordParams contains the parameters required to set, change or clear the duress password of a user.
+type SetDuressPasswordParams struct {
+	// Password contains the current password re-entered to confirm the change.
+	Password string
+	// DuressPassword contains the new duress password; empty turns duress logins off.
+	DuressPassword string
+	// UserID identifies the authenticated user setting the duress password.
+	UserID uuid.UUID
+	// Alert selects whether logins with the duress password raise a silent security alert.
+	Alert bool
+}
+
+// RequestLoginChangeParams contains the parameters required to request a login change.
+type RequestLoginChangeParams struct {
+	// NewLogin specifies the login to change to.
+	NewLogin string
+	// Password contains the current password re-entered to confirm the request.
+	Password string
+	// UserID identifies the authenticated user changing the login.
+	UserID uuid.UUID
+}
+
+// ConfirmLoginChangeParams contains the confirmation tokens of a requested login change.
+type ConfirmLoginChangeParams struct {
+	// OldToken contains the token sent to the old login.
+	OldToken string
+	// NewToken contains the token sent to the new login.
+	NewToken string
+}
+
+// LoginChange describes a requested or applied login change.
+type LoginChange struct {
+	// ExpiresAt specifies when the confirmation tokens expire; zero for an applied change.
+	ExpiresAt time.Time
+	// OldLogin contains the login the user changes from.
+	OldLogin string
+	// NewLogin contains the login the user changes to.
+	NewLogin string
+	// OldToken contains the confirmation token for the old login; empty for an applied change.
+	OldToken string
+	// NewToken contains the confirmation token for the new login; empty for an applied change.
+	NewToken string
+	// UserID identifies the user changing the login.
+	UserID uuid.UUID
+}
@@ -0,0 +1,45 @@
+package auth
+
+import (
+	"context"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/audit"
+	"github.com/google/uuid"
+)
+
+// DuressAlerter defines the interface for raising the silent alerts of logins with a duress password.
+type DuressAlerter interface {
+	// DuressLogin raises the alert of a login of the user with the duress password.
+	DuressLogin(ctx context.Context, userID uuid.UUID, params LoginParams)
+}
+
+// DuressAlarm raises the silent alerts of duress logins as security audit events. Nothing is sent to the
+// user, so whoever forced the login cannot tell it from a regular one.
+type DuressAlarm struct {
+	// audit records duress logins.
+	audit AuditRecorder
+}
+
+// NewDuressAlarm creates a new duress login alarm.
+func NewDuressAlarm(audit AuditRecorder) *DuressAlarm {
+	return &DuressAlarm{audit: audit}
+}
+
+// DuressLogin records a login of the user with the duress password.
+func (a *DuressAlarm) DuressLogin(ctx context.Context, userID uuid.UUID, params LoginParams) {
+	// ip holds the client address, empty when unknown.
+	var ip string
+	if params.IP.IsValid() {
+		ip = params.IP.String()
+	}
+	a.audit.Record(ctx, audit.Event{
+		Type:       audit.EventDuressLogin,
+		UserID:     userID,
+		OccurredAt: time.Now(),
+		Details: map[string]string{
+			"ip":         ip,
+			"user_agent": params.UserAgent,
+		},
+	})
+}
@@ -0,0 +1,290 @@
+package auth
+
+import (
+	"context"
+	"net/netip"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/audit"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/auth"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/auth"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockDuressAlerter captures the users duress logins were alerted for.
+type mockDuressAlerter struct {
+	alerted []uuid.UUID
+}
+
+func (m *mockDuressAlerter) DuressLogin(_ context.Context, userID uuid.UUID, _ LoginParams) {
+	m.alerted = append(m.alerted, userID)
+}
+
+// duressUsers keeps users by ID for the repository mock of the duress tests.
+type duressUsers struct {
+	users map[uuid.UUID]auth.User
+	mu    sync.Mutex
+}
+
+func (d *duressUsers) repository() *mockRepository {
+	return &mockRepository{
+		saveFunc: func(_ context.Context, params repository.SaveParams) error {
+			d.mu.Lock()
+			defer d.mu.Unlock()
+			d.users[params.Entity.ID] = *params.Entity
+			return nil
+		},
+		loadFunc: func(_ context.Context, params repository.LoadParams) (*auth.User, error) {
+			d.mu.Lock()
+			defer d.mu.Unlock()
+			for _, u := range d.users {
+				if u.ID == params.ID || params.Login != "" && u.Login == params.Login && !u.IsDuressVault() {
+					return &u, nil
+				}
+			}
+			return nil, repository.ErrUserNotFound
+		},
+	}
+}
+
+// plainHasher hashes passwords by prefixing them, so the tests can tell which hash a password matches.
+func plainHasher() *mockPasswordHasherVerificator {
+	return &mockPasswordHasherVerificator{
+		hashFunc: func(password string) (string, error) { return "hashed_" + password, nil },
+		verifyFunc: func(hash, password string) (bool, error) {
+			return hash == "hashed_"+password, nil
+		},
+	}
+}
+
+func TestService_LoginWithDuressPassword(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		wantErr   error
+		name      string
+		password  string
+		alert     bool
+		inactive  bool
+		wantDecoy bool
+	}{
+		{name: "real password", password: "realpassword"},
+		{name: "duress password", password: "duresspassword", wantDecoy: true},
+		{name: "duress password with alert", password: "duresspassword", alert: true, wantDecoy: true},
+		{name: "wrong password", password: "otherpassword", wantErr: ErrAuthWrongLoginOrPassword},
+		{name: "duress password of disabled user", password: "duresspassword", inactive: true,
+			wantErr: ErrAuthUserDisabled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			u := &auth.User{ID: uuid.New(), Login: "alice", PasswordHash: "hashed_realpassword", Active: !tt.inactive}
+			decoy, err := u.NewDuressVault(&mockCryptoKeyGenerator{})
+			require.NoError(t, err)
+			require.NoError(t, u.SetDuressPassword(decoy, plainHasher(), "duresspassword", tt.alert))
+			store := &duressUsers{users: map[uuid.UUID]auth.User{u.ID: *u, decoy.ID: *decoy}}
+
+			// issuedTo holds the user the access token was issued to.
+			var issuedTo uuid.UUID
+			tokens := &mockTokenGenerateValidator{generateFunc: func(userID uuid.UUID) (string, string, time.Time, error) {
+				issuedTo = userID
+				return "token", "Bearer", time.Now().Add(time.Hour), nil
+			}}
+			alerts := &mockDuressAlerter{}
+			// verifies counts the password verifications of the login.
+			verifies := 0
+			hasher := plainHasher()
+			verify := hasher.verifyFunc
+			hasher.verifyFunc = func(hash, password string) (bool, error) {
+				verifies++
+				return verify(hash, password)
+			}
+			service := NewService(
+				store.repository(), hasher, &mockCryptoKeyGenerator{}, tokens, nil, nil, nil, alerts,
+				auth.PasswordPolicy{},
+			)
+
+			_, err = service.Login(context.Background(), LoginParams{
+				Login:    "alice",
+				Password: tt.password,
+				IP:       netip.MustParseAddr("203.0.113.7"),
+			})
+
+			assert.Equal(t, 2, verifies, "every login verifies the password and the duress password")
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Empty(t, alerts.alerted)
+				return
+			}
+			require.NoError(t, err)
+			if !tt.wantDecoy {
+				assert.Equal(t, u.ID, issuedTo)
+				assert.Empty(t, alerts.alerted)
+				return
+			}
+			assert.Equal(t, decoy.ID, issuedTo)
+			if tt.alert {
+				assert.Equal(t, []uuid.UUID{u.ID}, alerts.alerted)
+			} else {
+				assert.Empty(t, alerts.alerted)
+			}
+		})
+	}
+}
+
+func TestService_SetDuressPassword(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		wantErr        error
+		name           string
+		password       string
+		duressPassword string
+		wantEnabled    bool
+	}{
+		{name: "first duress password", password: "realpassword", duressPassword: "duresspassword", wantEnabled: true},
+		{name: "wrong password", password: "otherpassword", duressPassword: "duresspassword",
+			wantErr: ErrAuthPasswordMismatch},
+		{name: "duress password reuses password", password: "realpassword", duressPassword: "realpassword",
+			wantErr: ErrAuthDuressPasswordReused},
+		{name: "too short duress password", password: "realpassword", duressPassword: "short",
+			wantErr: ErrAuthIncorrectPassword},
+		{name: "turned off", password: "realpassword"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			userID := uuid.New()
+			store := &duressUsers{users: map[uuid.UUID]auth.User{
+				userID: {ID: userID, Login: "alice", PasswordHash: "hashed_realpassword", Active: true},
+			}}
+			service := NewService(
+				store.repository(), plainHasher(), &mockCryptoKeyGenerator{}, &mockTokenGenerateValidator{},
+				nil, nil, nil, nil,
+				auth.PasswordPolicy{},
+			)
+
+			err := service.SetDuressPassword(context.Background(), SetDuressPasswordParams{
+				UserID:         userID,
+				Password:       tt.password,
+				DuressPassword: tt.duressPassword,
+				Alert:          true,
+			})
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Len(t, store.users, 1)
+				return
+			}
+			require.NoError(t, err)
+			settings, err := service.DuressSettings(context.Background(), userID)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantEnabled, settings.Enabled)
+			assert.Equal(t, tt.wantEnabled, settings.Alert)
+			if !tt.wantEnabled {
+				return
+			}
+
+			u := store.users[userID]
+			decoy, ok := store.users[u.DuressVaultID]
+			require.True(t, ok, "decoy vault saved")
+			assert.Equal(t, userID, decoy.ProfileOf)
+			assert.Equal(t, "hashed_"+tt.duressPassword, decoy.PasswordHash)
+
+			err = service.SetDuressPassword(context.Background(), SetDuressPasswordParams{
+				UserID:         userID,
+				Password:       tt.password,
+				DuressPassword: "otherduresspassword",
+			})
+			require.NoError(t, err)
+			assert.Len(t, store.users, 2, "the decoy vault is kept")
+			assert.Equal(t, "hashed_otherduresspassword", store.users[decoy.ID].PasswordHash)
+
+			err = service.SetDuressPassword(context.Background(), SetDuressPasswordParams{
+				UserID:         decoy.ID,
+				Password:       "otherduresspassword",
+				DuressPassword: "nestedpassword",
+			})
+			require.ErrorIs(t, err, ErrAuthDuressUnavailable)
+
+			err = service.SetDuressPassword(context.Background(), SetDuressPasswordParams{
+				UserID:   userID,
+				Password: tt.password,
+			})
+			require.NoError(t, err)
+			decoy = store.users[decoy.ID]
+			assert.True(t, decoy.SessionRevoked(time.Now().Add(-time.Minute)),
+				"turning duress logins off revokes the sessions of the decoy vault")
+		})
+	}
+}
+
+func TestService_ValidateTokenOfDuressVault(t *testing.T) {
+	t.Parallel()
+
+	u := &auth.User{ID: uuid.New(), Login: "alice", Active: true}
+	decoy, err := u.NewDuressVault(&mockCryptoKeyGenerator{})
+	require.NoError(t, err)
+	revoked := *u
+	revoked.RevokeSessions(time.Now())
+
+	tests := []struct {
+		wantErr error
+		owner   auth.User
+		name    string
+	}{
+		{name: "active session", owner: *u},
+		{name: "sessions of owner revoked", owner: revoked, wantErr: ErrAuthInvalidAccessToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			store := &duressUsers{users: map[uuid.UUID]auth.User{u.ID: tt.owner, decoy.ID: *decoy}}
+			tokens := &mockTokenGenerateValidator{validateFunc: func(string) (uuid.UUID, time.Time, error) {
+				return decoy.ID, time.Now().Add(-time.Hour), nil
+			}}
+			service := NewService(
+				store.repository(), plainHasher(), &mockCryptoKeyGenerator{}, tokens, nil, nil, nil, nil,
+				auth.PasswordPolicy{},
+			)
+
+			got, err := service.ValidateToken(context.Background(), "token")
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, decoy.ID, got)
+		})
+	}
+}
+
+func TestDuressAlarm_DuressLogin(t *testing.T) {
+	t.Parallel()
+
+	recorder := &mockAuditRecorder{}
+	userID := uuid.New()
+
+	NewDuressAlarm(recorder).DuressLogin(context.Background(), userID, LoginParams{
+		Login:     "alice",
+		Password:  "duresspassword",
+		IP:        netip.MustParseAddr("203.0.113.7"),
+		UserAgent: "curl/8.0",
+	})
+
+	require.Len(t, recorder.events, 1)
+	assert.Equal(t, audit.EventDuressLogin, recorder.events[0].Type)
+	assert.Equal(t, userID, recorder.events[0].UserID)
+	assert.Equal(t, map[string]string{"ip": "203.0.113.7", "user_agent": "curl/8.0"}, recorder.events[0].Details)
+}
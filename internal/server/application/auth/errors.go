@@ -5,8 +5,12 @@ import (
 	"fmt"
 
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/errutil"
+	inviteApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/invite"
 	domain "github.com/gdyunin/aegis-vault-keeper/internal/server/domain/auth"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/device"
 	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/auth"
+	repositoryDevice "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/device"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/vaultunlock"
 )
 
 // Authentication error definitions.
@@ -23,6 +27,30 @@ var (
 	// ErrAuthIncorrectPassword indicates an incorrect password was provided.
 	ErrAuthIncorrectPassword = errors.New("incorrect password")
 
+	// ErrAuthPasswordTooShort indicates a password shorter than the password policy allows.
+	ErrAuthPasswordTooShort = errors.New("password too short")
+
+	// ErrAuthPasswordTooLong indicates a password longer than the maximum length.
+	ErrAuthPasswordTooLong = errors.New("password too long")
+
+	// ErrAuthPasswordNoLower indicates a password without the lower-case letter the password policy requires.
+	ErrAuthPasswordNoLower = errors.New("password lacks a lower-case letter")
+
+	// ErrAuthPasswordNoUpper indicates a password without the upper-case letter the password policy requires.
+	ErrAuthPasswordNoUpper = errors.New("password lacks an upper-case letter")
+
+	// ErrAuthPasswordNoDigit indicates a password without the digit the password policy requires.
+	ErrAuthPasswordNoDigit = errors.New("password lacks a digit")
+
+	// ErrAuthPasswordNoSymbol indicates a password without the symbol the password policy requires.
+	ErrAuthPasswordNoSymbol = errors.New("password lacks a symbol")
+
+	// ErrAuthPasswordCommon indicates a common or banned password.
+	ErrAuthPasswordCommon = errors.New("password too common")
+
+	// ErrAuthPasswordReused indicates a password matching one of the recent passwords of the user.
+	ErrAuthPasswordReused = errors.New("password used recently")
+
 	// ErrAuthWrongLoginOrPassword indicates invalid login credentials.
 	ErrAuthWrongLoginOrPassword = errors.New("wrong login or password")
 
@@ -31,8 +59,70 @@ var (
 
 	// ErrAuthUserAlreadyExists indicates a user already exists with the given login.
 	ErrAuthUserAlreadyExists = errors.New("user already exists")
+
+	// ErrAuthStepUpRequired indicates that the login must be confirmed with a verification code.
+	ErrAuthStepUpRequired = errors.New("step-up verification required")
+
+	// ErrAuthStepUpFailed indicates a wrong, expired or unknown step-up verification.
+	ErrAuthStepUpFailed = errors.New("step-up verification failed")
+
+	// ErrAuthApprovalPending indicates that the held login has not been approved yet.
+	ErrAuthApprovalPending = errors.New("login approval pending")
+
+	// ErrAuthUserDisabled indicates that the user was deactivated and may not log in.
+	ErrAuthUserDisabled = errors.New("user disabled")
+
+	// ErrAuthInviteRequired indicates that registration requires an invite code.
+	ErrAuthInviteRequired = errors.New("invite code required")
+
+	// ErrAuthInviteInvalid indicates an unknown, redeemed, revoked or expired invite code.
+	ErrAuthInviteInvalid = errors.New("invalid invite code")
+
+	// ErrAuthPasswordMismatch indicates that the password re-entered to confirm an account change is wrong.
+	ErrAuthPasswordMismatch = errors.New("password mismatch")
+
+	// ErrAuthLoginChangeUndeliverable indicates that the confirmations of a login change cannot be delivered
+	// to the old or the new login.
+	ErrAuthLoginChangeUndeliverable = errors.New("login change confirmation undeliverable")
+
+	// ErrAuthLoginChangeInvalid indicates invalid, expired or already used login change confirmation tokens.
+	ErrAuthLoginChangeInvalid = errors.New("invalid login change confirmation")
+
+	// ErrAuthIncorrectUnlockSecret indicates an unlock key or salt of the wrong size.
+	ErrAuthIncorrectUnlockSecret = errors.New("incorrect unlock secret")
+
+	// ErrAuthVaultLocked indicates that the vault of the user is sealed and no unlock key was supplied.
+	ErrAuthVaultLocked = errors.New("vault locked")
+
+	// ErrAuthUnlockInvalid indicates an unlock key that does not open the vault of the user.
+	ErrAuthUnlockInvalid = errors.New("invalid unlock key")
+
+	// ErrAuthRecoveryCodeInvalid indicates an unknown or already used recovery code.
+	ErrAuthRecoveryCodeInvalid = errors.New("invalid recovery code")
+
+	// ErrAuthDuressPasswordReused indicates a duress password matching the password of the user.
+	ErrAuthDuressPasswordReused = errors.New("duress password matches the password")
+
+	// ErrAuthDuressUnavailable indicates that the user cannot have a duress password, as in duress sessions.
+	ErrAuthDuressUnavailable = errors.New("duress password unavailable")
 )
 
+// StepUpRequiredError reports a login held until the step-up challenge is verified.
+type StepUpRequiredError struct {
+	// Challenge describes the verification required to complete the login.
+	Challenge StepUpChallenge
+}
+
+// Error returns the error message.
+func (e *StepUpRequiredError) Error() string {
+	return ErrAuthStepUpRequired.Error()
+}
+
+// Unwrap returns ErrAuthStepUpRequired so the error matches it with errors.Is.
+func (e *StepUpRequiredError) Unwrap() error {
+	return ErrAuthStepUpRequired
+}
+
 // mapError maps domain and repository errors to application-level errors.
 func mapError(err error) error {
 	if err == nil {
@@ -58,6 +148,30 @@ func mapFn(err error) error {
 	case errors.Is(err, domain.ErrIncorrectPassword):
 		return ErrAuthIncorrectPassword
 
+	case errors.Is(err, domain.ErrPasswordTooShort):
+		return ErrAuthPasswordTooShort
+
+	case errors.Is(err, domain.ErrPasswordTooLong):
+		return ErrAuthPasswordTooLong
+
+	case errors.Is(err, domain.ErrPasswordNoLower):
+		return ErrAuthPasswordNoLower
+
+	case errors.Is(err, domain.ErrPasswordNoUpper):
+		return ErrAuthPasswordNoUpper
+
+	case errors.Is(err, domain.ErrPasswordNoDigit):
+		return ErrAuthPasswordNoDigit
+
+	case errors.Is(err, domain.ErrPasswordNoSymbol):
+		return ErrAuthPasswordNoSymbol
+
+	case errors.Is(err, domain.ErrPasswordCommon):
+		return ErrAuthPasswordCommon
+
+	case errors.Is(err, domain.ErrPasswordReused):
+		return ErrAuthPasswordReused
+
 	case errors.Is(err, domain.ErrPasswordVerificationFailed):
 		return ErrAuthWrongLoginOrPassword
 
@@ -70,6 +184,38 @@ func mapFn(err error) error {
 	case errors.Is(err, ErrAuthInvalidAccessToken):
 		return ErrAuthInvalidAccessToken
 
+	case errors.Is(err, ErrAuthLoginChangeUndeliverable):
+		return ErrAuthLoginChangeUndeliverable
+
+	case errors.Is(err, ErrAuthRecoveryCodeInvalid):
+		return ErrAuthRecoveryCodeInvalid
+
+	case errors.Is(err, domain.ErrDuressVaultNested),
+		errors.Is(err, domain.ErrDuressVaultMismatch):
+		return ErrAuthDuressUnavailable
+
+	case errors.Is(err, vaultunlock.ErrLocked):
+		return ErrAuthVaultLocked
+
+	case errors.Is(err, vaultunlock.ErrInvalidKey):
+		return ErrAuthUnlockInvalid
+
+	case errors.Is(err, device.ErrNoChallenge),
+		errors.Is(err, device.ErrChallengeExpired),
+		errors.Is(err, device.ErrChallengeAttemptsExceeded),
+		errors.Is(err, device.ErrChallengeMismatch),
+		errors.Is(err, repositoryDevice.ErrDeviceNotFound):
+		return ErrAuthStepUpFailed
+
+	case errors.Is(err, device.ErrApprovalPending):
+		return ErrAuthApprovalPending
+
+	case errors.Is(err, inviteApp.ErrInviteRequired):
+		return ErrAuthInviteRequired
+
+	case errors.Is(err, inviteApp.ErrInviteInvalid):
+		return ErrAuthInviteInvalid
+
 	default:
 		return errors.Join(ErrAuthTechError, err)
 	}
@@ -38,6 +38,26 @@ func TestMapError(t *testing.T) {
 			inputErr: auth.ErrIncorrectPassword,
 			wantErr:  ErrAuthIncorrectPassword,
 		},
+		{
+			name:     "domain_password_too_short",
+			inputErr: auth.ErrPasswordTooShort,
+			wantErr:  ErrAuthPasswordTooShort,
+		},
+		{
+			name:     "domain_password_no_symbol",
+			inputErr: auth.ErrPasswordNoSymbol,
+			wantErr:  ErrAuthPasswordNoSymbol,
+		},
+		{
+			name:     "domain_password_common",
+			inputErr: auth.ErrPasswordCommon,
+			wantErr:  ErrAuthPasswordCommon,
+		},
+		{
+			name:     "domain_password_reused",
+			inputErr: auth.ErrPasswordReused,
+			wantErr:  ErrAuthPasswordReused,
+		},
 		{
 			name:     "domain_password_verification_failed",
 			inputErr: auth.ErrPasswordVerificationFailed,
@@ -58,6 +78,16 @@ func TestMapError(t *testing.T) {
 			inputErr: ErrAuthInvalidAccessToken,
 			wantErr:  ErrAuthInvalidAccessToken,
 		},
+		{
+			name:     "login_change_undeliverable",
+			inputErr: ErrAuthLoginChangeUndeliverable,
+			wantErr:  ErrAuthLoginChangeUndeliverable,
+		},
+		{
+			name:     "domain_duress_vault_nested",
+			inputErr: auth.ErrDuressVaultNested,
+			wantErr:  ErrAuthDuressUnavailable,
+		},
 		{
 			name:     "unknown_error",
 			inputErr: errors.New("unknown error"),
@@ -0,0 +1,178 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/notification"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/audit"
+	domainNotification "github.com/gdyunin/aegis-vault-keeper/internal/server/domain/notification"
+	"github.com/google/uuid"
+)
+
+// loginChangeTTL limits how long the confirmation tokens of a requested login change are accepted.
+const loginChangeTTL = 24 * time.Hour
+
+// loginChangeConfirmPath is the API path confirmation tokens are submitted to.
+const loginChangeConfirmPath = "/api/auth/login-change/confirm"
+
+// loginChangeSubject is the subject of the login change confirmation messages.
+const loginChangeSubject = "Confirm the login change of your AegisVaultKeeper vault"
+
+// loginChangedSubject is the subject of the message telling the old login about an applied change.
+const loginChangedSubject = "The login of your AegisVaultKeeper vault was changed"
+
+// Messenger defines the interface for delivering messages to e-mail addresses.
+type Messenger interface {
+	// Send delivers a plain text message to the address.
+	Send(ctx context.Context, to, subject, body string) error
+}
+
+// LoginChangeNotifier defines the interface for delivering login change confirmations and recording changes.
+type LoginChangeNotifier interface {
+	// Requested delivers the confirmation tokens of a requested login change to the old and the new login.
+	Requested(ctx context.Context, change LoginChange) error
+	// Changed records an applied login change and tells the old login about it.
+	Changed(ctx context.Context, change LoginChange)
+}
+
+// LoginChangeMailer delivers the confirmation tokens of login changes. Each token goes to its login when the
+// login is an e-mail address and over the security notification channels of the user otherwise, so a change
+// is confirmed from both the old and the new login whenever they can receive mail.
+type LoginChangeMailer struct {
+	// messenger sends e-mail; nil when no SMTP relay is configured.
+	messenger Messenger
+	// notifier delivers tokens of logins that are not e-mail addresses; nil when notifications are disabled.
+	notifier Notifier
+	// audit records requested and applied login changes.
+	audit AuditRecorder
+}
+
+// NewLoginChangeMailer creates a new login change confirmation mailer.
+// The messenger and notifier may be nil when SMTP or notifications are not configured; changes whose
+// confirmations cannot be delivered are then rejected.
+func NewLoginChangeMailer(messenger Messenger, notifier Notifier, audit AuditRecorder) *LoginChangeMailer {
+	return &LoginChangeMailer{messenger: messenger, notifier: notifier, audit: audit}
+}
+
+// Requested delivers the confirmation tokens of a requested login change. It fails with
+// ErrAuthLoginChangeUndeliverable before sending anything when either token cannot be delivered.
+func (m *LoginChangeMailer) Requested(ctx context.Context, change LoginChange) error {
+	oldAddress, err := m.route(ctx, change.UserID, change.OldLogin)
+	if err != nil {
+		return err
+	}
+	newAddress, err := m.route(ctx, change.UserID, change.NewLogin)
+	if err != nil {
+		return err
+	}
+
+	oldText := loginChangeText(change, change.OldToken, "new")
+	if err := m.deliver(ctx, change.UserID, oldAddress, loginChangeSubject, oldText); err != nil {
+		return fmt.Errorf("failed to send old login confirmation: %w", err)
+	}
+	newText := loginChangeText(change, change.NewToken, "old")
+	if err := m.deliver(ctx, change.UserID, newAddress, loginChangeSubject, newText); err != nil {
+		return fmt.Errorf("failed to send new login confirmation: %w", err)
+	}
+
+	m.record(ctx, audit.EventLoginChangeRequested, change)
+	return nil
+}
+
+// Changed records an applied login change and tells the old login about it. Delivery failures are ignored,
+// since the change is already applied.
+func (m *LoginChangeMailer) Changed(ctx context.Context, change LoginChange) {
+	m.record(ctx, audit.EventLoginChanged, change)
+
+	address, err := m.route(ctx, change.UserID, change.OldLogin)
+	if err != nil {
+		return
+	}
+	_ = m.deliver(ctx, change.UserID, address, loginChangedSubject, loginChangedText(change))
+}
+
+// route returns the e-mail address a message for the login goes to, or an empty string when it goes
+// over the security notification channels of the user.
+func (m *LoginChangeMailer) route(ctx context.Context, userID uuid.UUID, login string) (string, error) {
+	if isEmailAddress(login) {
+		if m.messenger == nil {
+			return "", fmt.Errorf("e-mail is not configured: %w", ErrAuthLoginChangeUndeliverable)
+		}
+		return login, nil
+	}
+
+	if m.notifier == nil {
+		return "", fmt.Errorf("notifications are not configured: %w", ErrAuthLoginChangeUndeliverable)
+	}
+	reachable, err := m.notifier.Reachable(ctx, notification.ReachableParams{
+		Category: domainNotification.CategorySecurity,
+		UserID:   userID,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve notification channels: %w", err)
+	}
+	if !reachable {
+		return "", fmt.Errorf("no security notification channel: %w", ErrAuthLoginChangeUndeliverable)
+	}
+	return "", nil
+}
+
+// deliver sends the message to the e-mail address, or over the security notification channels
+// of the user when the address is empty.
+func (m *LoginChangeMailer) deliver(ctx context.Context, userID uuid.UUID, address, subject, body string) error {
+	if address != "" {
+		return m.messenger.Send(ctx, address, subject, body)
+	}
+	return m.notifier.Notify(ctx, notification.NotifyParams{
+		Category: domainNotification.CategorySecurity,
+		Subject:  subject,
+		Body:     body,
+		UserID:   userID,
+	})
+}
+
+// record emits an audit event of a login change.
+func (m *LoginChangeMailer) record(ctx context.Context, eventType string, change LoginChange) {
+	m.audit.Record(ctx, audit.Event{
+		Type:       eventType,
+		UserID:     change.UserID,
+		OccurredAt: time.Now(),
+		Details: map[string]string{
+			"old_login": change.OldLogin,
+			"new_login": change.NewLogin,
+		},
+	})
+}
+
+// isEmailAddress reports whether the login is a bare e-mail address.
+func isEmailAddress(login string) bool {
+	addr, err := mail.ParseAddress(login)
+	return err == nil && addr.Address == login
+}
+
+// loginChangeText renders the message carrying a confirmation token; other names the login
+// the second token was sent to.
+func loginChangeText(change LoginChange, token, other string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "A change of the login of your vault from %s to %s was requested.\n\n",
+		change.OldLogin, change.NewLogin)
+	b.WriteString("To confirm the change, submit this token together with the token sent to the ")
+	fmt.Fprintf(&b, "%s login to %s:\n\n%s\n\n", other, loginChangeConfirmPath, token)
+	fmt.Fprintf(&b, "The token expires at %s.\n", change.ExpiresAt.UTC().Format(time.RFC3339))
+	b.WriteString("Once the change is applied, all sessions are signed out.\n\n")
+	b.WriteString("If this was not you, do not share the token and change your password.\n")
+	return b.String()
+}
+
+// loginChangedText renders the message telling the old login about an applied change.
+func loginChangedText(change LoginChange) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "The login of your vault was changed from %s to %s.\n", change.OldLogin, change.NewLogin)
+	b.WriteString("All sessions were signed out; sign in with the new login.\n\n")
+	b.WriteString("If this was not you, contact your administrator immediately.\n")
+	return b.String()
+}
@@ -0,0 +1,157 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/audit"
+	domainNotification "github.com/gdyunin/aegis-vault-keeper/internal/server/domain/notification"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockMessenger captures sent e-mail messages by address.
+type mockMessenger struct {
+	sendErr error
+	sent    map[string]string
+}
+
+func (m *mockMessenger) Send(_ context.Context, to, _, body string) error {
+	if m.sendErr != nil {
+		return m.sendErr
+	}
+	if m.sent == nil {
+		m.sent = make(map[string]string)
+	}
+	m.sent[to] = body
+	return nil
+}
+
+func TestLoginChangeMailer_Requested(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	change := func(oldLogin, newLogin string) LoginChange {
+		return LoginChange{
+			UserID:    userID,
+			OldLogin:  oldLogin,
+			NewLogin:  newLogin,
+			OldToken:  "old-token",
+			NewToken:  "new-token",
+			ExpiresAt: time.Now().Add(loginChangeTTL),
+		}
+	}
+
+	tests := []struct {
+		messenger  *mockMessenger
+		notifier   *mockNotifier
+		wantErr    error
+		wantMailed map[string]string
+		wantNotify string
+		name       string
+		change     LoginChange
+	}{
+		{
+			name:       "both logins are e-mail addresses",
+			change:     change("alice@example.com", "alice@example.org"),
+			messenger:  &mockMessenger{},
+			wantMailed: map[string]string{"alice@example.com": "old-token", "alice@example.org": "new-token"},
+		},
+		{
+			name:       "old login is a user name",
+			change:     change("alice", "alice@example.org"),
+			messenger:  &mockMessenger{},
+			notifier:   &mockNotifier{},
+			wantMailed: map[string]string{"alice@example.org": "new-token"},
+			wantNotify: "old-token",
+		},
+		{
+			name:       "new login is a user name",
+			change:     change("alice@example.com", "alice_new"),
+			messenger:  &mockMessenger{},
+			notifier:   &mockNotifier{},
+			wantMailed: map[string]string{"alice@example.com": "old-token"},
+			wantNotify: "new-token",
+		},
+		{
+			name:    "e-mail not configured",
+			change:  change("alice", "alice@example.org"),
+			wantErr: ErrAuthLoginChangeUndeliverable,
+		},
+		{
+			name:      "no security notification channel",
+			change:    change("alice", "alice_new"),
+			messenger: &mockMessenger{},
+			notifier:  &mockNotifier{unreachable: true},
+			wantErr:   ErrAuthLoginChangeUndeliverable,
+		},
+		{
+			name:      "send failure",
+			change:    change("alice@example.com", "alice@example.org"),
+			messenger: &mockMessenger{sendErr: errors.New("smtp down")},
+			wantErr:   errors.New("failed to send old login confirmation"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// messenger and notifier stay untyped nils when not configured.
+			var (
+				messenger Messenger
+				notifier  Notifier
+			)
+			if tt.messenger != nil {
+				messenger = tt.messenger
+			}
+			if tt.notifier != nil {
+				notifier = tt.notifier
+			}
+			recorder := &mockAuditRecorder{}
+
+			err := NewLoginChangeMailer(messenger, notifier, recorder).Requested(context.Background(), tt.change)
+
+			if tt.wantErr != nil {
+				require.Error(t, err)
+				if errors.Is(tt.wantErr, ErrAuthLoginChangeUndeliverable) {
+					require.ErrorIs(t, err, tt.wantErr)
+				} else {
+					assert.Contains(t, err.Error(), tt.wantErr.Error())
+				}
+				assert.Empty(t, recorder.events)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, tt.messenger.sent, len(tt.wantMailed))
+			for address, token := range tt.wantMailed {
+				assert.Contains(t, tt.messenger.sent[address], token)
+			}
+			if tt.wantNotify != "" {
+				assert.Equal(t, domainNotification.CategorySecurity, tt.notifier.category)
+				assert.Contains(t, tt.notifier.body, tt.wantNotify)
+			}
+			require.Len(t, recorder.events, 1)
+			assert.Equal(t, audit.EventLoginChangeRequested, recorder.events[0].Type)
+			assert.Equal(t, tt.change.NewLogin, recorder.events[0].Details["new_login"])
+		})
+	}
+}
+
+func TestLoginChangeMailer_Changed(t *testing.T) {
+	t.Parallel()
+
+	messenger := &mockMessenger{}
+	recorder := &mockAuditRecorder{}
+	change := LoginChange{UserID: uuid.New(), OldLogin: "alice@example.com", NewLogin: "alice@example.org"}
+
+	NewLoginChangeMailer(messenger, nil, recorder).Changed(context.Background(), change)
+
+	require.Len(t, recorder.events, 1)
+	assert.Equal(t, audit.EventLoginChanged, recorder.events[0].Type)
+	assert.Equal(t, change.UserID, recorder.events[0].UserID)
+	assert.Contains(t, messenger.sent["alice@example.com"], "alice@example.org")
+}
@@ -1,22 +1,46 @@
 package auth
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
+	inviteApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/invite"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/auth"
 	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/auth"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/securebytes"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/vaultunlock"
 	"github.com/google/uuid"
 )
 
+// dummyPasswordHash is a bcrypt hash of the default cost matching no password. Logins of unknown users are
+// verified against it, so they take as long as wrong passwords and do not reveal which logins exist.
+const dummyPasswordHash = "$2a$10$QmUEi6zScCQkHLg3Fvd4JOZpWKOXmo0w6x8fD4fTZqyQW3vn0Ljke"
+
 // TokenGenerateValidator defines the interface for JWT token generation and validation operations.
 type TokenGenerateValidator interface {
 	// GenerateAccessToken creates a new JWT access token for the specified user ID.
 	GenerateAccessToken(userID uuid.UUID) (token string, tokenType string, expiresAt time.Time, err error)
 
-	// ValidateAccessToken validates a JWT token string and returns the associated user ID.
-	ValidateAccessToken(tokenString string) (uuid.UUID, error)
+	// ValidateAccessToken validates a JWT token string and returns the associated user ID and the moment
+	// the token was issued at.
+	ValidateAccessToken(tokenString string) (userID uuid.UUID, issuedAt time.Time, err error)
+
+	// GenerateLoginChangeTokens creates the pair of confirmation tokens of a login change valid for ttl.
+	GenerateLoginChangeTokens(
+		userID uuid.UUID,
+		oldLogin string,
+		newLogin string,
+		ttl time.Duration,
+	) (oldToken string, newToken string, expiresAt time.Time, err error)
+
+	// ValidateLoginChangeTokens validates the pair of confirmation tokens of a login change.
+	ValidateLoginChangeTokens(
+		oldToken string,
+		newToken string,
+	) (userID uuid.UUID, oldLogin string, newLogin string, err error)
 }
 
 // CryptoKeyGenerator is an alias for auth.CryptoKeyGenerator.
@@ -37,6 +61,39 @@ type Repository interface {
 	Load(ctx context.Context, params repository.LoadParams) (*auth.User, error)
 }
 
+// LoginGuard defines the interface for detecting suspicious logins and verifying them.
+type LoginGuard interface {
+	// Assess inspects a login with a verified password and returns a challenge when it must be held.
+	Assess(ctx context.Context, u *auth.User, params LoginParams) (*StepUpChallenge, error)
+	// Verify checks a step-up challenge and returns the identifier of the user completing the login.
+	Verify(ctx context.Context, params VerifyLoginParams) (uuid.UUID, error)
+	// Approve approves a held login with the token of an approval link sent to the user.
+	Approve(ctx context.Context, params ApproveLoginParams) error
+	// ApproveTrusted approves a held login of the user from a session of a trusted device.
+	ApproveTrusted(ctx context.Context, params ApprovePendingLoginParams) error
+	// Claim completes an approved held login and returns the identifier of the user.
+	Claim(ctx context.Context, params ClaimLoginParams) (uuid.UUID, error)
+	// Recover completes a held login once redeem accepts a recovery code of the user holding it
+	// and returns the identifier of the user.
+	Recover(
+		ctx context.Context,
+		challengeID uuid.UUID,
+		redeem func(ctx context.Context, userID uuid.UUID) error,
+	) (uuid.UUID, error)
+	// Pending lists the held logins of the user that can still be approved.
+	Pending(ctx context.Context, userID uuid.UUID) ([]*PendingLogin, error)
+}
+
+// InviteRedeemer defines the interface for the invites new users register with.
+type InviteRedeemer interface {
+	// Redeem registers a new user with register under the invite holding the code, atomically.
+	Redeem(
+		ctx context.Context,
+		params inviteApp.RedeemParams,
+		register func(ctx context.Context) (uuid.UUID, error),
+	) (uuid.UUID, error)
+}
+
 // Service provides authentication business logic operations.
 type Service struct {
 	// r is the repository interface for user data persistence operations.
@@ -47,57 +104,259 @@ type Service struct {
 	cryptoKeyGenerator CryptoKeyGenerator
 	// tokenGenerateValidator handles JWT token generation and validation operations.
 	tokenGenerateValidator TokenGenerateValidator
+	// guard holds suspicious logins for step-up verification; nil disables anomaly detection.
+	guard LoginGuard
+	// invites enforces invite codes on registration; nil registers without invites.
+	invites InviteRedeemer
+	// changes delivers login change confirmations; nil rejects login changes.
+	changes LoginChangeNotifier
+	// alerts raises the silent alerts of duress logins; nil raises none.
+	alerts DuressAlerter
+	// policy holds the rules passwords set on registration, password change and account recovery must follow.
+	policy auth.PasswordPolicy
 }
 
 // NewService creates a new authentication service instance with the provided dependencies.
+// A nil guard disables login anomaly detection; nil invites registers users without invite codes;
+// nil changes rejects login changes; nil alerts raises no duress login alerts. New passwords must follow
+// the password policy.
 func NewService(
 	r Repository,
 	passwordHasherVerificator PasswordHasherVerificator,
 	cryptoKeyGenerator CryptoKeyGenerator,
 	tokenGenerator TokenGenerateValidator,
+	guard LoginGuard,
+	invites InviteRedeemer,
+	changes LoginChangeNotifier,
+	alerts DuressAlerter,
+	policy auth.PasswordPolicy,
 ) *Service {
 	return &Service{
 		r:                         r,
 		passwordHasherVerificator: passwordHasherVerificator,
 		cryptoKeyGenerator:        cryptoKeyGenerator,
 		tokenGenerateValidator:    tokenGenerator,
+		guard:                     guard,
+		invites:                   invites,
+		changes:                   changes,
+		alerts:                    alerts,
+		policy:                    policy,
+	}
+}
+
+// Register creates a new user account with the provided registration parameters and returns its recovery codes.
+// The invite code is redeemed in the same transaction, so a failed registration leaves the invite usable.
+func (s *Service) Register(ctx context.Context, params RegisterParams) (Registration, error) {
+	if s.invites == nil {
+		return s.register(ctx, params)
+	}
+
+	var (
+		// registration holds the result of the last registration attempt.
+		registration Registration
+		// registerErr holds the already mapped error of the last registration attempt.
+		registerErr error
+	)
+	_, err := s.invites.Redeem(ctx, inviteApp.RedeemParams{Code: params.InviteCode},
+		func(ctx context.Context) (uuid.UUID, error) {
+			registration, registerErr = s.register(ctx, params)
+			return registration.UserID, registerErr
+		})
+	if registerErr != nil {
+		return Registration{}, registerErr
+	}
+	if err != nil {
+		return Registration{}, fmt.Errorf("failed to redeem invite: %w", mapError(err))
 	}
+	return registration, nil
 }
 
-// Register creates a new user account with the provided registration parameters.
-func (s *Service) Register(ctx context.Context, params RegisterParams) (uuid.UUID, error) {
+// RegisterFirst creates the first user account of a server set up by the onboarding wizard and returns its
+// recovery codes. No invite code is required, since nobody could have issued one yet; the caller ensures that
+// no user exists.
+func (s *Service) RegisterFirst(ctx context.Context, params RegisterParams) (Registration, error) {
+	return s.register(ctx, params)
+}
+
+// register creates and saves the user account along with its first recovery codes.
+func (s *Service) register(ctx context.Context, params RegisterParams) (Registration, error) {
 	u, err := auth.NewUser(
-		auth.NewUserParams{Login: params.Login, Password: params.Password},
+		auth.NewUserParams{Login: params.Login, Password: params.Password, Policy: s.policy},
 		s.passwordHasherVerificator,
 		s.cryptoKeyGenerator,
 	)
 	if err != nil {
-		return uuid.Nil, fmt.Errorf("failed to create new user: %w", mapError(err))
+		return Registration{}, fmt.Errorf("failed to create new user: %w", mapError(err))
 	}
+	defer u.Wipe()
 
+	codes, err := u.ResetRecoveryCodes(s.cryptoKeyGenerator)
+	if err != nil {
+		return Registration{}, fmt.Errorf("failed to generate recovery codes: %w", mapError(err))
+	}
 	if err := s.r.Save(ctx, repository.SaveParams{Entity: u}); err != nil {
-		return uuid.Nil, fmt.Errorf("failed to save user: %w", mapError(err))
+		return Registration{}, fmt.Errorf("failed to save user: %w", mapError(err))
 	}
 
-	return u.ID, nil
+	return Registration{UserID: u.ID, RecoveryCodes: codes}, nil
 }
 
 // Login authenticates a user with the provided credentials and returns an access token.
+// Logins flagged by the guard fail with *StepUpRequiredError until verified with VerifyLogin.
+// The duress password of the user logs in to the decoy vault instead.
 func (s *Service) Login(ctx context.Context, params LoginParams) (AccessToken, error) {
 	u, err := s.r.Load(ctx, repository.LoadParams{Login: params.Login})
 	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			// Passwords of known users are verified twice, against the password and the duress password.
+			for range 2 {
+				_, _ = s.passwordHasherVerificator.PasswordVerify(dummyPasswordHash, params.Password)
+			}
+		}
 		return AccessToken{}, fmt.Errorf("failed to load user: %w", mapError(err))
 	}
+	defer u.Wipe()
 
 	ok, err := u.VerifyPassword(s.passwordHasherVerificator, params.Password)
 	if err != nil {
 		return AccessToken{}, fmt.Errorf("failed to verify password: %w", mapError(err))
 	}
-	if !ok {
+	// The duress password is verified even when the password matched, so real, duress and wrong passwords
+	// take equally long and response times do not give a duress login away.
+	duress, err := s.verifyDuressPassword(u, params.Password)
+	if err != nil {
+		return AccessToken{}, fmt.Errorf("failed to verify password: %w", mapError(err))
+	}
+	if !ok && !duress {
 		return AccessToken{}, fmt.Errorf("authentication failed: %w", ErrAuthWrongLoginOrPassword)
 	}
+	if !u.Active {
+		return AccessToken{}, fmt.Errorf("authentication failed: %w", ErrAuthUserDisabled)
+	}
+	if !ok {
+		return s.duressLogin(ctx, u, params)
+	}
+
+	if s.guard != nil {
+		challenge, err := s.guard.Assess(ctx, u, params)
+		if err != nil {
+			return AccessToken{}, fmt.Errorf("failed to assess login: %w", mapError(err))
+		}
+		if challenge != nil {
+			return AccessToken{}, &StepUpRequiredError{Challenge: *challenge}
+		}
+	}
+
+	return s.issueAccessToken(u.ID)
+}
+
+// VerifyLogin completes a login held for step-up verification and returns an access token.
+// A recovery code of the user satisfies the verification in place of the sent code and is consumed.
+func (s *Service) VerifyLogin(ctx context.Context, params VerifyLoginParams) (AccessToken, error) {
+	if s.guard == nil {
+		return AccessToken{}, fmt.Errorf("anomaly detection disabled: %w", ErrAuthStepUpFailed)
+	}
+
+	if params.RecoveryCode != "" {
+		userID, err := s.guard.Recover(ctx, params.ChallengeID, func(ctx context.Context, userID uuid.UUID) error {
+			return s.redeemRecoveryCode(ctx, repository.LoadParams{ID: userID}, params.RecoveryCode, nil)
+		})
+		if err != nil {
+			return AccessToken{}, fmt.Errorf("failed to verify login: %w", mapError(err))
+		}
+		return s.issueAccessToken(userID)
+	}
+
+	userID, err := s.guard.Verify(ctx, params)
+	if err != nil {
+		return AccessToken{}, fmt.Errorf("failed to verify login: %w", mapError(err))
+	}
+
+	return s.issueAccessToken(userID)
+}
+
+// verifyDuressPassword verifies the password against the duress password of the user. Users without one
+// are verified against a hash matching no password, so a login takes as long whether or not the user has a
+// duress password.
+func (s *Service) verifyDuressPassword(u *auth.User, password string) (bool, error) {
+	if !u.HasDuressPassword() {
+		_, _ = s.passwordHasherVerificator.PasswordVerify(dummyPasswordHash, password)
+		return false, nil
+	}
+	ok, err := u.VerifyDuressPassword(s.passwordHasherVerificator, password)
+	if err != nil {
+		return false, fmt.Errorf("failed to verify duress password: %w", err)
+	}
+	return ok, nil
+}
+
+// duressLogin opens the decoy vault of the user logging in with the duress password and raises the silent
+// alert when the user enabled it. Step-up verification is skipped, since holding the login would reach the
+// trusted devices of the user and give the decoy away.
+func (s *Service) duressLogin(ctx context.Context, u *auth.User, params LoginParams) (AccessToken, error) {
+	if u.DuressAlert && s.alerts != nil {
+		s.alerts.DuressLogin(ctx, u.ID, params)
+	}
+	return s.issueAccessToken(u.DuressVaultID)
+}
+
+// ApproveLogin approves a held login with the token of an approval link sent to the user.
+func (s *Service) ApproveLogin(ctx context.Context, params ApproveLoginParams) error {
+	if s.guard == nil {
+		return fmt.Errorf("anomaly detection disabled: %w", ErrAuthStepUpFailed)
+	}
+
+	if err := s.guard.Approve(ctx, params); err != nil {
+		return fmt.Errorf("failed to approve login: %w", mapError(err))
+	}
+	return nil
+}
+
+// ApprovePendingLogin approves a held login of the authenticated user.
+// Every access token is issued to a device that passed anomaly detection, so the caller
+// is treated as a trusted device of the user.
+func (s *Service) ApprovePendingLogin(ctx context.Context, params ApprovePendingLoginParams) error {
+	if s.guard == nil {
+		return fmt.Errorf("anomaly detection disabled: %w", ErrAuthStepUpFailed)
+	}
+
+	if err := s.guard.ApproveTrusted(ctx, params); err != nil {
+		return fmt.Errorf("failed to approve login: %w", mapError(err))
+	}
+	return nil
+}
+
+// ClaimLogin completes an approved held login and returns an access token.
+// It fails with ErrAuthApprovalPending while the login awaits approval.
+func (s *Service) ClaimLogin(ctx context.Context, params ClaimLoginParams) (AccessToken, error) {
+	if s.guard == nil {
+		return AccessToken{}, fmt.Errorf("anomaly detection disabled: %w", ErrAuthStepUpFailed)
+	}
+
+	userID, err := s.guard.Claim(ctx, params)
+	if err != nil {
+		return AccessToken{}, fmt.Errorf("failed to claim login: %w", mapError(err))
+	}
 
-	token, tokType, expiresAt, err := s.tokenGenerateValidator.GenerateAccessToken(u.ID)
+	return s.issueAccessToken(userID)
+}
+
+// PendingLogins lists the held logins of the user awaiting approval.
+func (s *Service) PendingLogins(ctx context.Context, userID uuid.UUID) ([]*PendingLogin, error) {
+	if s.guard == nil {
+		return make([]*PendingLogin, 0), nil
+	}
+
+	pending, err := s.guard.Pending(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pending logins: %w", mapError(err))
+	}
+	return pending, nil
+}
+
+// issueAccessToken generates an access token for the authenticated user.
+func (s *Service) issueAccessToken(userID uuid.UUID) (AccessToken, error) {
+	token, tokType, expiresAt, err := s.tokenGenerateValidator.GenerateAccessToken(userID)
 	if err != nil {
 		return AccessToken{}, fmt.Errorf("failed to generate access token: %w", mapError(err))
 	}
@@ -105,11 +364,481 @@ func (s *Service) Login(ctx context.Context, params LoginParams) (AccessToken, e
 	return AccessToken{AccessToken: token, TokenType: tokType, ExpiresAt: expiresAt}, nil
 }
 
+// ChangePassword replaces the password of the user after verifying the current one, revokes the other sessions
+// of the user and returns a new access token. The user key is wrapped with the server master key rather than
+// derived from the password, so it stays unchanged and the repository re-wraps it with a fresh nonce in the same
+// row update that stores the new password hash; the vault remains accessible.
+func (s *Service) ChangePassword(ctx context.Context, params ChangePasswordParams) (AccessToken, error) {
+	u, err := s.r.Load(ctx, repository.LoadParams{ID: params.UserID})
+	if err != nil {
+		return AccessToken{}, fmt.Errorf("failed to load user: %w", mapError(err))
+	}
+	defer u.Wipe()
+
+	ok, err := u.VerifyPassword(s.passwordHasherVerificator, params.OldPassword)
+	if err != nil {
+		return AccessToken{}, fmt.Errorf("failed to verify password: %w", mapError(err))
+	}
+	if !ok {
+		return AccessToken{}, fmt.Errorf("failed to change password: %w", ErrAuthPasswordMismatch)
+	}
+
+	err = u.ChangePassword(s.passwordHasherVerificator, s.passwordHasherVerificator, s.policy, params.NewPassword)
+	if err != nil {
+		return AccessToken{}, fmt.Errorf("failed to set password: %w", mapError(err))
+	}
+	u.RevokeSessions(time.Now())
+	if err := s.r.Save(ctx, repository.SaveParams{Entity: u}); err != nil {
+		return AccessToken{}, fmt.Errorf("failed to save user: %w", mapError(err))
+	}
+
+	return s.issueAccessToken(u.ID)
+}
+
+// ConfirmPassword verifies the password re-entered by an authenticated user to step up before a sensitive
+// operation, failing with ErrAuthPasswordMismatch when it does not match.
+func (s *Service) ConfirmPassword(ctx context.Context, userID uuid.UUID, password string) error {
+	u, err := s.r.Load(ctx, repository.LoadParams{ID: userID})
+	if err != nil {
+		return fmt.Errorf("failed to load user: %w", mapError(err))
+	}
+	defer u.Wipe()
+
+	ok, err := u.VerifyPassword(s.passwordHasherVerificator, password)
+	if err != nil {
+		return fmt.Errorf("failed to verify password: %w", mapError(err))
+	}
+	if !ok {
+		return fmt.Errorf("failed to confirm password: %w", ErrAuthPasswordMismatch)
+	}
+	return nil
+}
+
+// RecoveryCodesLeft returns the number of unused recovery codes of the user.
+func (s *Service) RecoveryCodesLeft(ctx context.Context, userID uuid.UUID) (int, error) {
+	u, err := s.r.Load(ctx, repository.LoadParams{ID: userID})
+	if err != nil {
+		return 0, fmt.Errorf("failed to load user: %w", mapError(err))
+	}
+	defer u.Wipe()
+
+	return u.RecoveryCodesLeft(), nil
+}
+
+// RegenerateRecoveryCodes replaces the recovery codes of the user after verifying the password and returns
+// the new codes. The previous codes stop working, and the new ones cannot be shown again.
+func (s *Service) RegenerateRecoveryCodes(ctx context.Context, params RegenerateRecoveryCodesParams) ([]string, error) {
+	u, err := s.r.Load(ctx, repository.LoadParams{ID: params.UserID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load user: %w", mapError(err))
+	}
+	defer u.Wipe()
+
+	ok, err := u.VerifyPassword(s.passwordHasherVerificator, params.Password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify password: %w", mapError(err))
+	}
+	if !ok {
+		return nil, fmt.Errorf("failed to regenerate recovery codes: %w", ErrAuthPasswordMismatch)
+	}
+
+	codes, err := u.ResetRecoveryCodes(s.cryptoKeyGenerator)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate recovery codes: %w", mapError(err))
+	}
+	if err := s.r.Save(ctx, repository.SaveParams{Entity: u}); err != nil {
+		return nil, fmt.Errorf("failed to save user: %w", mapError(err))
+	}
+	return codes, nil
+}
+
+// RecoverAccount resets the password of a user who lost it with one of their recovery codes, revokes all
+// sessions of the user and returns a new access token. The recovery code is consumed. The vault key is wrapped
+// with the server master key, so the vault stays accessible; an unlock secret is still required to open it.
+func (s *Service) RecoverAccount(ctx context.Context, params RecoverAccountParams) (AccessToken, error) {
+	// userID holds the identifier of the recovered user.
+	var userID uuid.UUID
+	err := s.redeemRecoveryCode(ctx, repository.LoadParams{Login: params.Login}, params.RecoveryCode,
+		func(u *auth.User) error {
+			if !u.Active {
+				return fmt.Errorf("account recovery failed: %w", ErrAuthUserDisabled)
+			}
+			err := u.ChangePassword(s.passwordHasherVerificator, s.passwordHasherVerificator, s.policy,
+				params.NewPassword)
+			if err != nil {
+				return fmt.Errorf("failed to set password: %w", mapError(err))
+			}
+			u.RevokeSessions(time.Now())
+			userID = u.ID
+			return nil
+		})
+	if err != nil {
+		return AccessToken{}, err
+	}
+
+	return s.issueAccessToken(userID)
+}
+
+// redeemRecoveryCode consumes the recovery code of the user matching the load parameters, applies the optional
+// change to the user and saves both. Unknown users and codes alike fail with ErrAuthRecoveryCodeInvalid.
+func (s *Service) redeemRecoveryCode(
+	ctx context.Context,
+	params repository.LoadParams,
+	code string,
+	change func(u *auth.User) error,
+) error {
+	u, err := s.r.Load(ctx, params)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return fmt.Errorf("failed to load user: %w", ErrAuthRecoveryCodeInvalid)
+		}
+		return fmt.Errorf("failed to load user: %w", mapError(err))
+	}
+	defer u.Wipe()
+
+	if !u.UseRecoveryCode(code) {
+		return fmt.Errorf("failed to redeem recovery code: %w", ErrAuthRecoveryCodeInvalid)
+	}
+	if change != nil {
+		if err := change(u); err != nil {
+			return err
+		}
+	}
+	if err := s.r.Save(ctx, repository.SaveParams{Entity: u}); err != nil {
+		return fmt.Errorf("failed to save user: %w", mapError(err))
+	}
+	return nil
+}
+
+// UnlockSettings returns how the client derives the unlock key of the user from the unlock secret.
+func (s *Service) UnlockSettings(ctx context.Context, userID uuid.UUID) (UnlockSettings, error) {
+	u, err := s.r.Load(ctx, repository.LoadParams{ID: userID})
+	if err != nil {
+		return UnlockSettings{}, fmt.Errorf("failed to load user: %w", mapError(err))
+	}
+	defer u.Wipe()
+
+	return UnlockSettings{
+		KDF:       vaultunlock.KDF,
+		Salt:      u.UnlockSalt,
+		KeySize:   vaultunlock.KeySize,
+		Time:      vaultunlock.KDFTime,
+		MemoryKiB: vaultunlock.KDFMemoryKiB,
+		Threads:   vaultunlock.KDFThreads,
+		Enabled:   u.HasUnlockSecret(),
+	}, nil
+}
+
+// SetUnlockSecret seals the vault key of the user with the unlock key derived from a new unlock secret.
+// The first unlock secret is confirmed with the password; changing it takes the current unlock key instead,
+// so the password and the unlock secret are changed independently of each other.
+func (s *Service) SetUnlockSecret(ctx context.Context, params SetUnlockSecretParams) error {
+	if len(params.Salt) < vaultunlock.MinSaltSize || len(params.UnlockKey) != vaultunlock.KeySize {
+		return fmt.Errorf("failed to set unlock secret: %w", ErrAuthIncorrectUnlockSecret)
+	}
+
+	u, err := s.r.Load(ctx, repository.LoadParams{ID: params.UserID})
+	if err != nil {
+		return fmt.Errorf("failed to load user: %w", mapError(err))
+	}
+	defer u.Wipe()
+
+	// key holds the plain vault key of the user.
+	var key []byte
+	if u.HasUnlockSecret() {
+		if len(params.CurrentUnlockKey) == 0 {
+			return fmt.Errorf("failed to change unlock secret: %w", ErrAuthVaultLocked)
+		}
+		if key, err = vaultunlock.Open(params.CurrentUnlockKey, u.CryptoKey, u.ID); err != nil {
+			return fmt.Errorf("failed to open vault key: %w", mapError(err))
+		}
+		defer securebytes.Wipe(key)
+	} else {
+		ok, err := u.VerifyPassword(s.passwordHasherVerificator, params.Password)
+		if err != nil {
+			return fmt.Errorf("failed to verify password: %w", mapError(err))
+		}
+		if !ok {
+			return fmt.Errorf("failed to set unlock secret: %w", ErrAuthPasswordMismatch)
+		}
+		key = u.CryptoKey
+	}
+
+	sealed, err := vaultunlock.Seal(params.UnlockKey, key, u.ID)
+	if err != nil {
+		return fmt.Errorf("failed to seal vault key: %w", mapError(err))
+	}
+	u.SetUnlockSecret(sealed, bytes.Clone(params.Salt))
+	if err := s.r.Save(ctx, repository.SaveParams{Entity: u}); err != nil {
+		return fmt.Errorf("failed to save user: %w", mapError(err))
+	}
+	return nil
+}
+
+// Unlock verifies the unlock key of the user. Users without an unlock secret need none; otherwise
+// ErrAuthVaultLocked is returned without a key and ErrAuthUnlockInvalid when the key does not open the vault.
+func (s *Service) Unlock(ctx context.Context, userID uuid.UUID, unlockKey []byte) error {
+	u, err := s.r.Load(ctx, repository.LoadParams{ID: userID})
+	if err != nil {
+		return fmt.Errorf("failed to load user: %w", mapError(err))
+	}
+	defer u.Wipe()
+
+	if !u.HasUnlockSecret() {
+		return nil
+	}
+	if len(unlockKey) == 0 {
+		return fmt.Errorf("failed to unlock vault: %w", ErrAuthVaultLocked)
+	}
+	key, err := vaultunlock.Open(unlockKey, u.CryptoKey, u.ID)
+	if err != nil {
+		return fmt.Errorf("failed to unlock vault: %w", mapError(err))
+	}
+	securebytes.Wipe(key)
+	return nil
+}
+
+// DuressSettings returns whether the user has a duress password and whether it raises alerts.
+func (s *Service) DuressSettings(ctx context.Context, userID uuid.UUID) (DuressSettings, error) {
+	u, err := s.r.Load(ctx, repository.LoadParams{ID: userID})
+	if err != nil {
+		return DuressSettings{}, fmt.Errorf("failed to load user: %w", mapError(err))
+	}
+	defer u.Wipe()
+
+	return DuressSettings{Enabled: u.HasDuressPassword(), Alert: u.DuressAlert}, nil
+}
+
+// SetDuressPassword sets, changes or clears the duress password of the user after verifying the password.
+// The first duress password creates the decoy vault with key material of its own; later ones keep it, so the
+// decoy items survive changes of the duress password and turning duress logins off.
+func (s *Service) SetDuressPassword(ctx context.Context, params SetDuressPasswordParams) error {
+	u, err := s.r.Load(ctx, repository.LoadParams{ID: params.UserID})
+	if err != nil {
+		return fmt.Errorf("failed to load user: %w", mapError(err))
+	}
+	defer u.Wipe()
+
+	ok, err := u.VerifyPassword(s.passwordHasherVerificator, params.Password)
+	if err != nil {
+		return fmt.Errorf("failed to verify password: %w", mapError(err))
+	}
+	if !ok {
+		return fmt.Errorf("failed to set duress password: %w", ErrAuthPasswordMismatch)
+	}
+
+	if params.DuressPassword == "" {
+		return s.clearDuressPassword(ctx, u)
+	}
+
+	reused, err := u.VerifyPassword(s.passwordHasherVerificator, params.DuressPassword)
+	if err != nil {
+		return fmt.Errorf("failed to verify duress password: %w", mapError(err))
+	}
+	if reused {
+		return fmt.Errorf("failed to set duress password: %w", ErrAuthDuressPasswordReused)
+	}
+
+	decoy, err := s.duressVault(ctx, u)
+	if err != nil {
+		return err
+	}
+	defer decoy.Wipe()
+
+	err = u.SetDuressPassword(decoy, s.passwordHasherVerificator, params.DuressPassword, params.Alert)
+	if err != nil {
+		return fmt.Errorf("failed to set duress password: %w", mapError(err))
+	}
+	// The decoy vault is saved first, as the user refers to it.
+	if err := s.r.Save(ctx, repository.SaveParams{Entity: decoy}); err != nil {
+		return fmt.Errorf("failed to save decoy vault: %w", mapError(err))
+	}
+	if err := s.r.Save(ctx, repository.SaveParams{Entity: u}); err != nil {
+		return fmt.Errorf("failed to save user: %w", mapError(err))
+	}
+	return nil
+}
+
+// clearDuressPassword turns the duress logins of the user off and revokes the sessions of the decoy vault.
+func (s *Service) clearDuressPassword(ctx context.Context, u *auth.User) error {
+	// decoy holds the decoy vault whose sessions are revoked; nil when the user has none.
+	var decoy *auth.User
+	if u.DuressVaultID != uuid.Nil {
+		var err error
+		decoy, err = s.r.Load(ctx, repository.LoadParams{ID: u.DuressVaultID})
+		if err != nil && !errors.Is(err, repository.ErrUserNotFound) {
+			return fmt.Errorf("failed to load decoy vault: %w", mapError(err))
+		}
+		if decoy != nil {
+			defer decoy.Wipe()
+		}
+	}
+
+	if err := u.ClearDuressPassword(decoy, time.Now()); err != nil {
+		return fmt.Errorf("failed to clear duress password: %w", mapError(err))
+	}
+	if decoy != nil {
+		if err := s.r.Save(ctx, repository.SaveParams{Entity: decoy}); err != nil {
+			return fmt.Errorf("failed to save decoy vault: %w", mapError(err))
+		}
+	}
+	if err := s.r.Save(ctx, repository.SaveParams{Entity: u}); err != nil {
+		return fmt.Errorf("failed to save user: %w", mapError(err))
+	}
+	return nil
+}
+
+// duressVault loads the decoy vault profile of the user, or creates it for the first duress password.
+func (s *Service) duressVault(ctx context.Context, u *auth.User) (*auth.User, error) {
+	if u.DuressVaultID != uuid.Nil {
+		decoy, err := s.r.Load(ctx, repository.LoadParams{ID: u.DuressVaultID})
+		if err == nil {
+			return decoy, nil
+		}
+		if !errors.Is(err, repository.ErrUserNotFound) {
+			return nil, fmt.Errorf("failed to load decoy vault: %w", mapError(err))
+		}
+	}
+
+	decoy, err := u.NewDuressVault(s.cryptoKeyGenerator)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create decoy vault: %w", mapError(err))
+	}
+	return decoy, nil
+}
+
+// RequestLoginChange re-authenticates the user with the password and sends the confirmation tokens
+// of the change to the old and the new login. The change is applied by ConfirmLoginChange with both tokens
+// before they expire at the returned moment.
+func (s *Service) RequestLoginChange(ctx context.Context, params RequestLoginChangeParams) (time.Time, error) {
+	if s.changes == nil {
+		return time.Time{}, fmt.Errorf("login changes disabled: %w", ErrAuthLoginChangeUndeliverable)
+	}
+
+	u, err := s.r.Load(ctx, repository.LoadParams{ID: params.UserID})
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to load user: %w", mapError(err))
+	}
+	defer u.Wipe()
+
+	ok, err := u.VerifyPassword(s.passwordHasherVerificator, params.Password)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to verify password: %w", mapError(err))
+	}
+	if !ok {
+		return time.Time{}, fmt.Errorf("failed to confirm login change: %w", ErrAuthPasswordMismatch)
+	}
+
+	oldLogin := u.Login
+	// The loaded user only validates the new login; it is saved once the change is confirmed.
+	if err := u.SetLogin(params.NewLogin); err != nil {
+		return time.Time{}, fmt.Errorf("failed to validate login: %w", mapError(err))
+	}
+	if err := s.loginAvailable(ctx, params.NewLogin); err != nil {
+		return time.Time{}, err
+	}
+
+	oldToken, newToken, expiresAt, err := s.tokenGenerateValidator.GenerateLoginChangeTokens(
+		u.ID, oldLogin, params.NewLogin, loginChangeTTL,
+	)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to generate confirmation tokens: %w", mapError(err))
+	}
+
+	err = s.changes.Requested(ctx, LoginChange{
+		UserID:    u.ID,
+		OldLogin:  oldLogin,
+		NewLogin:  params.NewLogin,
+		OldToken:  oldToken,
+		NewToken:  newToken,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to send confirmations: %w", mapError(err))
+	}
+	return expiresAt, nil
+}
+
+// ConfirmLoginChange applies a login change confirmed with the tokens sent to the old and the new login
+// and revokes all sessions of the user. Tokens of a change that was applied or superseded are rejected.
+func (s *Service) ConfirmLoginChange(ctx context.Context, params ConfirmLoginChangeParams) error {
+	userID, oldLogin, newLogin, err := s.tokenGenerateValidator.ValidateLoginChangeTokens(
+		params.OldToken, params.NewToken,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to validate confirmation tokens: %w", ErrAuthLoginChangeInvalid)
+	}
+
+	u, err := s.r.Load(ctx, repository.LoadParams{ID: userID})
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return fmt.Errorf("failed to load user: %w", ErrAuthLoginChangeInvalid)
+		}
+		return fmt.Errorf("failed to load user: %w", mapError(err))
+	}
+	defer u.Wipe()
+
+	if u.Login != oldLogin {
+		return fmt.Errorf("login changed since the request: %w", ErrAuthLoginChangeInvalid)
+	}
+	if err := u.SetLogin(newLogin); err != nil {
+		return fmt.Errorf("failed to change login: %w", mapError(err))
+	}
+	u.RevokeSessions(time.Now())
+	if err := s.r.Save(ctx, repository.SaveParams{Entity: u}); err != nil {
+		return fmt.Errorf("failed to save user: %w", mapError(err))
+	}
+
+	if s.changes != nil {
+		s.changes.Changed(ctx, LoginChange{UserID: u.ID, OldLogin: oldLogin, NewLogin: newLogin})
+	}
+	return nil
+}
+
+// loginAvailable checks that no user holds the login.
+func (s *Service) loginAvailable(ctx context.Context, login string) error {
+	holder, err := s.r.Load(ctx, repository.LoadParams{Login: login})
+	if err == nil {
+		holder.Wipe()
+		return fmt.Errorf("login taken: %w", ErrAuthUserAlreadyExists)
+	}
+	if !errors.Is(err, repository.ErrUserNotFound) {
+		return fmt.Errorf("failed to load user: %w", mapError(err))
+	}
+	return nil
+}
+
 // ValidateToken validates an access token and returns the associated user ID.
-func (s *Service) ValidateToken(tokenString string) (uuid.UUID, error) {
-	userID, err := s.tokenGenerateValidator.ValidateAccessToken(tokenString)
+// Tokens issued before the sessions of the user were revoked are rejected; tokens of decoy vaults are also
+// rejected once the sessions of the user owning the decoy vault are revoked.
+func (s *Service) ValidateToken(ctx context.Context, tokenString string) (uuid.UUID, error) {
+	userID, issuedAt, err := s.tokenGenerateValidator.ValidateAccessToken(tokenString)
 	if err != nil {
 		return uuid.Nil, fmt.Errorf("failed to validate access token: %w", ErrAuthInvalidAccessToken)
 	}
+
+	u, err := s.r.Load(ctx, repository.LoadParams{ID: userID})
+	if err != nil {
+		// Tokens of users without an account record, such as federated ones, have no sessions to revoke.
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return userID, nil
+		}
+		return uuid.Nil, fmt.Errorf("failed to load user: %w", mapError(err))
+	}
+	defer u.Wipe()
+
+	if u.SessionRevoked(issuedAt) {
+		return uuid.Nil, fmt.Errorf("session revoked: %w", ErrAuthInvalidAccessToken)
+	}
+	if u.IsDuressVault() {
+		owner, err := s.r.Load(ctx, repository.LoadParams{ID: u.ProfileOf})
+		if err != nil {
+			return uuid.Nil, fmt.Errorf("failed to load user: %w", mapError(err))
+		}
+		defer owner.Wipe()
+		if owner.SessionRevoked(issuedAt) {
+			return uuid.Nil, fmt.Errorf("session revoked: %w", ErrAuthInvalidAccessToken)
+		}
+	}
 	return userID, nil
 }
@@ -1,13 +1,21 @@
 package auth
 
 import (
+	"bytes"
 	"context"
+	"crypto/rand"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
+	inviteApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/invite"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/crypto"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/auth"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/device"
 	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/auth"
+	repositoryDevice "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/device"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/vaultunlock"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -69,7 +77,7 @@ func (m *mockCryptoKeyGenerator) CryptoKeyGenerate(size int) ([]byte, error) {
 
 type mockTokenGenerateValidator struct {
 	generateFunc func(userID uuid.UUID) (string, string, time.Time, error)
-	validateFunc func(tokenString string) (uuid.UUID, error)
+	validateFunc func(tokenString string) (uuid.UUID, time.Time, error)
 }
 
 func (m *mockTokenGenerateValidator) GenerateAccessToken(
@@ -81,11 +89,35 @@ func (m *mockTokenGenerateValidator) GenerateAccessToken(
 	return "test_token", "Bearer", time.Now().Add(time.Hour), nil
 }
 
-func (m *mockTokenGenerateValidator) ValidateAccessToken(tokenString string) (uuid.UUID, error) {
+func (m *mockTokenGenerateValidator) ValidateAccessToken(tokenString string) (uuid.UUID, time.Time, error) {
 	if m.validateFunc != nil {
 		return m.validateFunc(tokenString)
 	}
-	return uuid.New(), nil
+	return uuid.New(), time.Now(), nil
+}
+
+// GenerateLoginChangeTokens encodes the change in the tokens, so ValidateLoginChangeTokens can decode it.
+func (m *mockTokenGenerateValidator) GenerateLoginChangeTokens(
+	userID uuid.UUID,
+	oldLogin string,
+	newLogin string,
+	ttl time.Duration,
+) (string, string, time.Time, error) {
+	claims := strings.Join([]string{userID.String(), oldLogin, newLogin}, "|")
+	return "old|" + claims, "new|" + claims, time.Now().Add(ttl), nil
+}
+
+func (m *mockTokenGenerateValidator) ValidateLoginChangeTokens(
+	oldToken string,
+	newToken string,
+) (uuid.UUID, string, string, error) {
+	oldClaims, okOld := strings.CutPrefix(oldToken, "old|")
+	newClaims, okNew := strings.CutPrefix(newToken, "new|")
+	parts := strings.Split(oldClaims, "|")
+	if !okOld || !okNew || oldClaims != newClaims || len(parts) != 3 {
+		return uuid.Nil, "", "", errors.New("invalid tokens")
+	}
+	return uuid.MustParse(parts[0]), parts[1], parts[2], nil
 }
 
 func TestNewService(t *testing.T) {
@@ -96,7 +128,7 @@ func TestNewService(t *testing.T) {
 	keyGen := &mockCryptoKeyGenerator{}
 	tokenGen := &mockTokenGenerateValidator{}
 
-	service := NewService(repo, hasher, keyGen, tokenGen)
+	service := NewService(repo, hasher, keyGen, tokenGen, nil, nil, nil, nil, auth.PasswordPolicy{})
 
 	require.NotNil(t, service)
 	assert.Equal(t, repo, service.r)
@@ -212,23 +244,148 @@ func TestService_Register(t *testing.T) {
 				tt.setupMocks(repo, hasher, keyGen)
 			}
 
-			service := NewService(repo, hasher, keyGen, tokenGen)
-			userID, err := service.Register(context.Background(), tt.args.params)
+			service := NewService(repo, hasher, keyGen, tokenGen, nil, nil, nil, nil, auth.PasswordPolicy{})
+			registration, err := service.Register(context.Background(), tt.args.params)
 
 			if tt.wantErr {
 				require.Error(t, err)
 				if tt.expectedErrMsg != "" {
 					assert.Contains(t, err.Error(), tt.expectedErrMsg)
 				}
-				assert.Equal(t, uuid.Nil, userID)
+				assert.Equal(t, Registration{}, registration)
 			} else {
 				require.NoError(t, err)
-				assert.NotEqual(t, uuid.Nil, userID)
+				assert.NotEqual(t, uuid.Nil, registration.UserID)
+				assert.Len(t, registration.RecoveryCodes, auth.RecoveryCodeCount)
+			}
+		})
+	}
+}
+
+// mockInviteRedeemer implements InviteRedeemer for testing.
+type mockInviteRedeemer struct {
+	err  error
+	code string
+}
+
+func (m *mockInviteRedeemer) Redeem(
+	ctx context.Context,
+	params inviteApp.RedeemParams,
+	register func(ctx context.Context) (uuid.UUID, error),
+) (uuid.UUID, error) {
+	m.code = params.Code
+	if m.err != nil {
+		return uuid.Nil, m.err
+	}
+	return register(ctx)
+}
+
+func TestService_RegisterWithInvite(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		inviteErr error
+		saveErr   error
+		wantErr   error
+		name      string
+	}{
+		{name: "redeemed"},
+		{name: "invite required", inviteErr: inviteApp.ErrInviteRequired, wantErr: ErrAuthInviteRequired},
+		{name: "invalid invite", inviteErr: inviteApp.ErrInviteInvalid, wantErr: ErrAuthInviteInvalid},
+		{name: "invite failure", inviteErr: inviteApp.ErrInviteTechError, wantErr: ErrAuthTechError},
+		{
+			name:    "registration failure is not remapped",
+			saveErr: repository.ErrUserAlreadyExists,
+			wantErr: ErrAuthUserAlreadyExists,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &mockRepository{saveFunc: func(context.Context, repository.SaveParams) error { return tt.saveErr }}
+			invites := &mockInviteRedeemer{err: tt.inviteErr}
+			service := NewService(
+				repo, &mockPasswordHasherVerificator{}, &mockCryptoKeyGenerator{},
+				&mockTokenGenerateValidator{}, nil, invites, nil, nil,
+				auth.PasswordPolicy{},
+			)
+
+			registration, err := service.Register(context.Background(), RegisterParams{
+				Login:      "testuser",
+				Password:   "testpass123",
+				InviteCode: "avki_code",
+			})
+			assert.Equal(t, "avki_code", invites.code)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				if !errors.Is(tt.wantErr, ErrAuthTechError) {
+					assert.NotErrorIs(t, err, ErrAuthTechError)
+				}
+				assert.Equal(t, Registration{}, registration)
+				return
 			}
+			require.NoError(t, err)
+			assert.NotEqual(t, uuid.Nil, registration.UserID)
+			assert.Len(t, registration.RecoveryCodes, auth.RecoveryCodeCount)
 		})
 	}
 }
 
+func TestService_RegisterFirst(t *testing.T) {
+	t.Parallel()
+
+	repo := &mockRepository{saveFunc: func(context.Context, repository.SaveParams) error { return nil }}
+	invites := &mockInviteRedeemer{err: inviteApp.ErrInviteRequired}
+	service := NewService(
+		repo, &mockPasswordHasherVerificator{}, &mockCryptoKeyGenerator{},
+		&mockTokenGenerateValidator{}, nil, invites, nil, nil,
+		auth.PasswordPolicy{},
+	)
+
+	registration, err := service.RegisterFirst(context.Background(), RegisterParams{
+		Login:    "admin",
+		Password: "testpass123",
+	})
+	require.NoError(t, err)
+	assert.Empty(t, invites.code, "no invite is redeemed")
+	assert.NotEqual(t, uuid.Nil, registration.UserID)
+	assert.Len(t, registration.RecoveryCodes, auth.RecoveryCodeCount)
+
+	_, err = service.RegisterFirst(context.Background(), RegisterParams{Login: "admin", Password: "1"})
+	require.ErrorIs(t, err, ErrAuthAppError)
+}
+
+func TestService_LoginUnknownUser(t *testing.T) {
+	t.Parallel()
+
+	repo := &mockRepository{
+		loadFunc: func(context.Context, repository.LoadParams) (*auth.User, error) {
+			return nil, repository.ErrUserNotFound
+		},
+	}
+	// verified holds the hashes the password was verified against.
+	var verified []string
+	hasher := &mockPasswordHasherVerificator{
+		verifyFunc: func(hash, _ string) (bool, error) {
+			verified = append(verified, hash)
+			return false, nil
+		},
+	}
+
+	_, err := NewService(repo, hasher, &mockCryptoKeyGenerator{}, &mockTokenGenerateValidator{}, nil, nil, nil, nil,
+		auth.PasswordPolicy{}).
+		Login(context.Background(), LoginParams{Login: "nonexistent", Password: "testpass123"})
+
+	require.ErrorIs(t, err, ErrAuthWrongLoginOrPassword)
+	assert.Equal(t, []string{dummyPasswordHash, dummyPasswordHash}, verified)
+
+	ok, err := crypto.VerifyBcrypt(dummyPasswordHash, "testpass123")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
 func TestService_Login(t *testing.T) {
 	t.Parallel()
 
@@ -238,6 +395,12 @@ func TestService_Login(t *testing.T) {
 		Login:        "testuser",
 		PasswordHash: "hashed_password",
 		CryptoKey:    []byte("crypto_key"),
+		Active:       true,
+	}
+	disabledUser := &auth.User{
+		ID:           uuid.New(),
+		Login:        "disableduser",
+		PasswordHash: "hashed_password",
 	}
 
 	type args struct {
@@ -327,6 +490,25 @@ func TestService_Login(t *testing.T) {
 			wantErr:        true,
 			expectedErrMsg: "failed to verify password",
 		},
+		{
+			name: "user_disabled",
+			args: args{
+				params: LoginParams{
+					Login:    "disableduser",
+					Password: "testpass123",
+				},
+			},
+			setupMocks: func(repo *mockRepository, hasher *mockPasswordHasherVerificator, tokenGen *mockTokenGenerateValidator) {
+				repo.loadFunc = func(ctx context.Context, params repository.LoadParams) (*auth.User, error) {
+					return disabledUser, nil
+				}
+				hasher.verifyFunc = func(hash, password string) (bool, error) {
+					return true, nil
+				}
+			},
+			wantErr:        true,
+			expectedErrMsg: "user disabled",
+		},
 		{
 			name: "token_generation_failed",
 			args: args{
@@ -365,7 +547,7 @@ func TestService_Login(t *testing.T) {
 				tt.setupMocks(repo, hasher, tokenGen)
 			}
 
-			service := NewService(repo, hasher, keyGen, tokenGen)
+			service := NewService(repo, hasher, keyGen, tokenGen, nil, nil, nil, nil, auth.PasswordPolicy{})
 			token, err := service.Login(context.Background(), tt.args.params)
 
 			if tt.wantErr {
@@ -390,9 +572,13 @@ func TestService_ValidateToken(t *testing.T) {
 	t.Parallel()
 
 	testUserID := uuid.New()
+	revoked := &auth.User{ID: testUserID}
+	revoked.RevokeSessions(time.Now())
 
 	tests := []struct {
 		setupMocks     func(*mockTokenGenerateValidator)
+		loadErr        error
+		user           *auth.User
 		name           string
 		tokenString    string
 		expectedErrMsg string
@@ -403,19 +589,58 @@ func TestService_ValidateToken(t *testing.T) {
 			name:        "valid_token",
 			tokenString: "valid_token_string",
 			setupMocks: func(tokenGen *mockTokenGenerateValidator) {
-				tokenGen.validateFunc = func(tokenString string) (uuid.UUID, error) {
-					return testUserID, nil
+				tokenGen.validateFunc = func(tokenString string) (uuid.UUID, time.Time, error) {
+					return testUserID, time.Now(), nil
+				}
+			},
+			user:           &auth.User{ID: testUserID},
+			wantErr:        false,
+			expectedUserID: testUserID,
+		},
+		{
+			name:        "token_of_user_without_account",
+			tokenString: "federated_token_string",
+			setupMocks: func(tokenGen *mockTokenGenerateValidator) {
+				tokenGen.validateFunc = func(tokenString string) (uuid.UUID, time.Time, error) {
+					return testUserID, time.Now(), nil
 				}
 			},
+			loadErr:        repository.ErrUserNotFound,
 			wantErr:        false,
 			expectedUserID: testUserID,
 		},
+		{
+			name:        "token_issued_before_sessions_revoked",
+			tokenString: "revoked_token_string",
+			setupMocks: func(tokenGen *mockTokenGenerateValidator) {
+				tokenGen.validateFunc = func(tokenString string) (uuid.UUID, time.Time, error) {
+					return testUserID, time.Now().Add(-time.Hour), nil
+				}
+			},
+			user:           revoked,
+			wantErr:        true,
+			expectedUserID: uuid.Nil,
+			expectedErrMsg: "session revoked",
+		},
+		{
+			name:        "user_load_failure",
+			tokenString: "valid_token_string",
+			setupMocks: func(tokenGen *mockTokenGenerateValidator) {
+				tokenGen.validateFunc = func(tokenString string) (uuid.UUID, time.Time, error) {
+					return testUserID, time.Now(), nil
+				}
+			},
+			loadErr:        errors.New("database unavailable"),
+			wantErr:        true,
+			expectedUserID: uuid.Nil,
+			expectedErrMsg: "failed to load user",
+		},
 		{
 			name:        "invalid_token",
 			tokenString: "invalid_token_string",
 			setupMocks: func(tokenGen *mockTokenGenerateValidator) {
-				tokenGen.validateFunc = func(tokenString string) (uuid.UUID, error) {
-					return uuid.Nil, errors.New("invalid token")
+				tokenGen.validateFunc = func(tokenString string) (uuid.UUID, time.Time, error) {
+					return uuid.Nil, time.Time{}, errors.New("invalid token")
 				}
 			},
 			wantErr:        true,
@@ -426,8 +651,8 @@ func TestService_ValidateToken(t *testing.T) {
 			name:        "empty_token",
 			tokenString: "",
 			setupMocks: func(tokenGen *mockTokenGenerateValidator) {
-				tokenGen.validateFunc = func(tokenString string) (uuid.UUID, error) {
-					return uuid.Nil, errors.New("empty token")
+				tokenGen.validateFunc = func(tokenString string) (uuid.UUID, time.Time, error) {
+					return uuid.Nil, time.Time{}, errors.New("empty token")
 				}
 			},
 			wantErr:        true,
@@ -441,7 +666,15 @@ func TestService_ValidateToken(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			repo := &mockRepository{}
+			repo := &mockRepository{
+				loadFunc: func(_ context.Context, _ repository.LoadParams) (*auth.User, error) {
+					if tt.loadErr != nil {
+						return nil, tt.loadErr
+					}
+					u := *tt.user
+					return &u, nil
+				},
+			}
 			hasher := &mockPasswordHasherVerificator{}
 			keyGen := &mockCryptoKeyGenerator{}
 			tokenGen := &mockTokenGenerateValidator{}
@@ -450,8 +683,8 @@ func TestService_ValidateToken(t *testing.T) {
 				tt.setupMocks(tokenGen)
 			}
 
-			service := NewService(repo, hasher, keyGen, tokenGen)
-			userID, err := service.ValidateToken(tt.tokenString)
+			service := NewService(repo, hasher, keyGen, tokenGen, nil, nil, nil, nil, auth.PasswordPolicy{})
+			userID, err := service.ValidateToken(context.Background(), tt.tokenString)
 
 			if tt.wantErr {
 				require.Error(t, err)
@@ -466,3 +699,1123 @@ func TestService_ValidateToken(t *testing.T) {
 		})
 	}
 }
+
+// mockLoginGuard implements LoginGuard for testing.
+type mockLoginGuard struct {
+	assessFunc         func(ctx context.Context, u *auth.User, params LoginParams) (*StepUpChallenge, error)
+	verifyFunc         func(ctx context.Context, params VerifyLoginParams) (uuid.UUID, error)
+	approveFunc        func(ctx context.Context, params ApproveLoginParams) error
+	approveTrustedFunc func(ctx context.Context, params ApprovePendingLoginParams) error
+	claimFunc          func(ctx context.Context, params ClaimLoginParams) (uuid.UUID, error)
+	pendingFunc        func(ctx context.Context, userID uuid.UUID) ([]*PendingLogin, error)
+	// recoverUserID is the user holding the challenge passed to redeem; uuid.Nil rejects the challenge.
+	recoverUserID uuid.UUID
+}
+
+func (m *mockLoginGuard) Assess(ctx context.Context, u *auth.User, params LoginParams) (*StepUpChallenge, error) {
+	if m.assessFunc != nil {
+		return m.assessFunc(ctx, u, params)
+	}
+	return nil, nil
+}
+
+func (m *mockLoginGuard) Verify(ctx context.Context, params VerifyLoginParams) (uuid.UUID, error) {
+	if m.verifyFunc != nil {
+		return m.verifyFunc(ctx, params)
+	}
+	return uuid.Nil, errMockNotImplemented
+}
+
+func (m *mockLoginGuard) Approve(ctx context.Context, params ApproveLoginParams) error {
+	if m.approveFunc != nil {
+		return m.approveFunc(ctx, params)
+	}
+	return errMockNotImplemented
+}
+
+func (m *mockLoginGuard) ApproveTrusted(ctx context.Context, params ApprovePendingLoginParams) error {
+	if m.approveTrustedFunc != nil {
+		return m.approveTrustedFunc(ctx, params)
+	}
+	return errMockNotImplemented
+}
+
+func (m *mockLoginGuard) Claim(ctx context.Context, params ClaimLoginParams) (uuid.UUID, error) {
+	if m.claimFunc != nil {
+		return m.claimFunc(ctx, params)
+	}
+	return uuid.Nil, errMockNotImplemented
+}
+
+func (m *mockLoginGuard) Recover(
+	ctx context.Context,
+	_ uuid.UUID,
+	redeem func(ctx context.Context, userID uuid.UUID) error,
+) (uuid.UUID, error) {
+	if m.recoverUserID == uuid.Nil {
+		return uuid.Nil, repositoryDevice.ErrDeviceNotFound
+	}
+	if err := redeem(ctx, m.recoverUserID); err != nil {
+		return uuid.Nil, err
+	}
+	return m.recoverUserID, nil
+}
+
+func (m *mockLoginGuard) Pending(ctx context.Context, userID uuid.UUID) ([]*PendingLogin, error) {
+	if m.pendingFunc != nil {
+		return m.pendingFunc(ctx, userID)
+	}
+	return nil, errMockNotImplemented
+}
+
+func TestService_LoginWithGuard(t *testing.T) {
+	t.Parallel()
+
+	testUser := &auth.User{ID: uuid.New(), Login: "user@example.com", Active: true}
+	challenge := &StepUpChallenge{ID: uuid.New(), Reasons: []string{"new_device"}}
+
+	tests := []struct {
+		guard       *mockLoginGuard
+		wantErr     error
+		name        string
+		expectToken bool
+	}{
+		{
+			name:        "recognized login",
+			guard:       &mockLoginGuard{},
+			expectToken: true,
+		},
+		{
+			name: "step-up required",
+			guard: &mockLoginGuard{
+				assessFunc: func(context.Context, *auth.User, LoginParams) (*StepUpChallenge, error) {
+					return challenge, nil
+				},
+			},
+			wantErr: ErrAuthStepUpRequired,
+		},
+		{
+			name: "assessment failure",
+			guard: &mockLoginGuard{
+				assessFunc: func(context.Context, *auth.User, LoginParams) (*StepUpChallenge, error) {
+					return nil, errors.New("db down")
+				},
+			},
+			wantErr: ErrAuthTechError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &mockRepository{
+				loadFunc: func(context.Context, repository.LoadParams) (*auth.User, error) {
+					return testUser, nil
+				},
+			}
+			service := NewService(
+				repo, &mockPasswordHasherVerificator{}, &mockCryptoKeyGenerator{}, &mockTokenGenerateValidator{},
+				tt.guard, nil, nil, nil,
+				auth.PasswordPolicy{},
+			)
+
+			token, err := service.Login(context.Background(), LoginParams{Login: testUser.Login, Password: "pass"})
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Empty(t, token.AccessToken)
+				if errors.Is(tt.wantErr, ErrAuthStepUpRequired) {
+					var stepUp *StepUpRequiredError
+					require.ErrorAs(t, err, &stepUp)
+					assert.Equal(t, *challenge, stepUp.Challenge)
+				}
+				return
+			}
+			require.NoError(t, err)
+			assert.NotEmpty(t, token.AccessToken)
+		})
+	}
+}
+
+func TestService_VerifyLogin(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+
+	tests := []struct {
+		guard   LoginGuard
+		wantErr error
+		name    string
+	}{
+		{
+			name: "verified",
+			guard: &mockLoginGuard{
+				verifyFunc: func(context.Context, VerifyLoginParams) (uuid.UUID, error) {
+					return userID, nil
+				},
+			},
+		},
+		{
+			name: "wrong code",
+			guard: &mockLoginGuard{
+				verifyFunc: func(context.Context, VerifyLoginParams) (uuid.UUID, error) {
+					return uuid.Nil, device.ErrChallengeMismatch
+				},
+			},
+			wantErr: ErrAuthStepUpFailed,
+		},
+		{
+			name:    "anomaly detection disabled",
+			guard:   nil,
+			wantErr: ErrAuthStepUpFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var generatedFor uuid.UUID
+			tokenGen := &mockTokenGenerateValidator{
+				generateFunc: func(id uuid.UUID) (string, string, time.Time, error) {
+					generatedFor = id
+					return "access_token", "Bearer", time.Now().Add(time.Hour), nil
+				},
+			}
+			service := NewService(
+				&mockRepository{}, &mockPasswordHasherVerificator{}, &mockCryptoKeyGenerator{}, tokenGen, tt.guard, nil, nil, nil,
+				auth.PasswordPolicy{},
+			)
+
+			token, err := service.VerifyLogin(
+				context.Background(),
+				VerifyLoginParams{ChallengeID: uuid.New(), Code: "123456"},
+			)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "access_token", token.AccessToken)
+			assert.Equal(t, userID, generatedFor)
+		})
+	}
+}
+
+func TestService_ClaimLogin(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+
+	tests := []struct {
+		guard   LoginGuard
+		wantErr error
+		name    string
+	}{
+		{
+			name: "approved",
+			guard: &mockLoginGuard{
+				claimFunc: func(context.Context, ClaimLoginParams) (uuid.UUID, error) {
+					return userID, nil
+				},
+			},
+		},
+		{
+			name: "awaiting approval",
+			guard: &mockLoginGuard{
+				claimFunc: func(context.Context, ClaimLoginParams) (uuid.UUID, error) {
+					return uuid.Nil, device.ErrApprovalPending
+				},
+			},
+			wantErr: ErrAuthApprovalPending,
+		},
+		{
+			name: "expired",
+			guard: &mockLoginGuard{
+				claimFunc: func(context.Context, ClaimLoginParams) (uuid.UUID, error) {
+					return uuid.Nil, device.ErrChallengeExpired
+				},
+			},
+			wantErr: ErrAuthStepUpFailed,
+		},
+		{
+			name:    "anomaly detection disabled",
+			guard:   nil,
+			wantErr: ErrAuthStepUpFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			service := NewService(
+				&mockRepository{}, &mockPasswordHasherVerificator{}, &mockCryptoKeyGenerator{},
+				&mockTokenGenerateValidator{}, tt.guard, nil, nil, nil,
+				auth.PasswordPolicy{},
+			)
+
+			token, err := service.ClaimLogin(context.Background(), ClaimLoginParams{ChallengeID: uuid.New()})
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotEmpty(t, token.AccessToken)
+		})
+	}
+}
+
+func TestService_ApproveLogin(t *testing.T) {
+	t.Parallel()
+
+	userID, challengeID := uuid.New(), uuid.New()
+	guard := &mockLoginGuard{
+		approveFunc: func(_ context.Context, params ApproveLoginParams) error {
+			if params.Token != "token" {
+				return device.ErrChallengeMismatch
+			}
+			return nil
+		},
+		approveTrustedFunc: func(_ context.Context, params ApprovePendingLoginParams) error {
+			if params.UserID != userID {
+				return repositoryDevice.ErrDeviceNotFound
+			}
+			return nil
+		},
+	}
+	service := NewService(
+		&mockRepository{}, &mockPasswordHasherVerificator{}, &mockCryptoKeyGenerator{},
+		&mockTokenGenerateValidator{}, guard, nil, nil, nil,
+		auth.PasswordPolicy{},
+	)
+	ctx := context.Background()
+
+	require.NoError(t, service.ApproveLogin(ctx, ApproveLoginParams{ChallengeID: challengeID, Token: "token"}))
+	require.ErrorIs(t,
+		service.ApproveLogin(ctx, ApproveLoginParams{ChallengeID: challengeID, Token: "other"}),
+		ErrAuthStepUpFailed,
+	)
+	require.NoError(t,
+		service.ApprovePendingLogin(ctx, ApprovePendingLoginParams{ChallengeID: challengeID, UserID: userID}),
+	)
+	require.ErrorIs(t,
+		service.ApprovePendingLogin(ctx, ApprovePendingLoginParams{ChallengeID: challengeID, UserID: uuid.New()}),
+		ErrAuthStepUpFailed,
+	)
+}
+
+func TestService_PendingLogins(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	pending := []*PendingLogin{{ID: uuid.New(), UserAgent: "Chrome"}}
+	guard := &mockLoginGuard{
+		pendingFunc: func(_ context.Context, id uuid.UUID) ([]*PendingLogin, error) {
+			assert.Equal(t, userID, id)
+			return pending, nil
+		},
+	}
+	got, err := NewService(
+		&mockRepository{}, &mockPasswordHasherVerificator{}, &mockCryptoKeyGenerator{},
+		&mockTokenGenerateValidator{}, guard, nil, nil, nil,
+		auth.PasswordPolicy{},
+	).PendingLogins(context.Background(), userID)
+	require.NoError(t, err)
+	assert.Equal(t, pending, got)
+
+	got, err = NewService(
+		&mockRepository{}, &mockPasswordHasherVerificator{}, &mockCryptoKeyGenerator{},
+		&mockTokenGenerateValidator{}, nil, nil, nil, nil,
+		auth.PasswordPolicy{},
+	).PendingLogins(context.Background(), userID)
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
+
+// mockLoginChangeNotifier captures requested and applied login changes.
+type mockLoginChangeNotifier struct {
+	requestErr error
+	requested  []LoginChange
+	changed    []LoginChange
+}
+
+func (m *mockLoginChangeNotifier) Requested(_ context.Context, change LoginChange) error {
+	if m.requestErr != nil {
+		return m.requestErr
+	}
+	m.requested = append(m.requested, change)
+	return nil
+}
+
+func (m *mockLoginChangeNotifier) Changed(_ context.Context, change LoginChange) {
+	m.changed = append(m.changed, change)
+}
+
+func TestService_RequestLoginChange(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+
+	tests := []struct {
+		notifier *mockLoginChangeNotifier
+		wantErr  error
+		name     string
+		newLogin string
+		password string
+	}{
+		{
+			name:     "change requested",
+			notifier: &mockLoginChangeNotifier{},
+			newLogin: "alice@example.org",
+			password: "correct_password",
+		},
+		{
+			name:     "wrong password",
+			notifier: &mockLoginChangeNotifier{},
+			newLogin: "alice@example.org",
+			password: "wrong_password",
+			wantErr:  ErrAuthPasswordMismatch,
+		},
+		{
+			name:     "invalid new login",
+			notifier: &mockLoginChangeNotifier{},
+			newLogin: "abc",
+			password: "correct_password",
+			wantErr:  ErrAuthIncorrectLogin,
+		},
+		{
+			name:     "new login taken",
+			notifier: &mockLoginChangeNotifier{},
+			newLogin: "taken_login",
+			password: "correct_password",
+			wantErr:  ErrAuthUserAlreadyExists,
+		},
+		{
+			name:     "confirmations undeliverable",
+			notifier: &mockLoginChangeNotifier{requestErr: ErrAuthLoginChangeUndeliverable},
+			newLogin: "alice@example.org",
+			password: "correct_password",
+			wantErr:  ErrAuthLoginChangeUndeliverable,
+		},
+		{
+			name:     "login changes disabled",
+			newLogin: "alice@example.org",
+			password: "correct_password",
+			wantErr:  ErrAuthLoginChangeUndeliverable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &mockRepository{
+				loadFunc: func(_ context.Context, params repository.LoadParams) (*auth.User, error) {
+					switch {
+					case params.ID == userID:
+						return &auth.User{ID: userID, Login: "alice", PasswordHash: "hash", Active: true}, nil
+					case params.Login == "taken_login":
+						return &auth.User{ID: uuid.New(), Login: params.Login}, nil
+					default:
+						return nil, repository.ErrUserNotFound
+					}
+				},
+			}
+			hasher := &mockPasswordHasherVerificator{
+				verifyFunc: func(_, password string) (bool, error) { return password == "correct_password", nil },
+			}
+			// changes stays an untyped nil when login changes are disabled.
+			var changes LoginChangeNotifier
+			if tt.notifier != nil {
+				changes = tt.notifier
+			}
+			service := NewService(repo, hasher, &mockCryptoKeyGenerator{}, &mockTokenGenerateValidator{}, nil, nil, changes, nil,
+				auth.PasswordPolicy{})
+
+			expiresAt, err := service.RequestLoginChange(context.Background(), RequestLoginChangeParams{
+				UserID:   userID,
+				NewLogin: tt.newLogin,
+				Password: tt.password,
+			})
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				if tt.notifier != nil {
+					assert.Empty(t, tt.notifier.requested)
+				}
+				return
+			}
+			require.NoError(t, err)
+			assert.WithinDuration(t, time.Now().Add(loginChangeTTL), expiresAt, time.Minute)
+			require.Len(t, tt.notifier.requested, 1)
+			change := tt.notifier.requested[0]
+			assert.Equal(t, "alice", change.OldLogin)
+			assert.Equal(t, tt.newLogin, change.NewLogin)
+			assert.NotEmpty(t, change.OldToken)
+			assert.NotEmpty(t, change.NewToken)
+		})
+	}
+}
+
+func TestService_ConfirmLoginChange(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	tokens := &mockTokenGenerateValidator{}
+	oldToken, newToken, _, err := tokens.GenerateLoginChangeTokens(userID, "alice", "alice@example.org", time.Hour)
+	require.NoError(t, err)
+	_, otherNewToken, _, err := tokens.GenerateLoginChangeTokens(userID, "alice", "alice_other", time.Hour)
+	require.NoError(t, err)
+
+	tests := []struct {
+		saveErr  error
+		wantErr  error
+		name     string
+		login    string
+		oldToken string
+		newToken string
+	}{
+		{name: "change applied", login: "alice", oldToken: oldToken, newToken: newToken},
+		{
+			name:     "tokens of different changes",
+			login:    "alice",
+			oldToken: oldToken,
+			newToken: otherNewToken,
+			wantErr:  ErrAuthLoginChangeInvalid,
+		},
+		{
+			name:     "change already applied",
+			login:    "alice@example.org",
+			oldToken: oldToken,
+			newToken: newToken,
+			wantErr:  ErrAuthLoginChangeInvalid,
+		},
+		{
+			name:     "new login taken meanwhile",
+			login:    "alice",
+			oldToken: oldToken,
+			newToken: newToken,
+			saveErr:  repository.ErrUserAlreadyExists,
+			wantErr:  ErrAuthUserAlreadyExists,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// saved holds the user state persisted by the service.
+			var saved *auth.User
+			repo := &mockRepository{
+				loadFunc: func(_ context.Context, params repository.LoadParams) (*auth.User, error) {
+					return &auth.User{ID: params.ID, Login: tt.login, Active: true}, nil
+				},
+				saveFunc: func(_ context.Context, params repository.SaveParams) error {
+					if tt.saveErr != nil {
+						return tt.saveErr
+					}
+					u := *params.Entity
+					saved = &u
+					return nil
+				},
+			}
+			notifier := &mockLoginChangeNotifier{}
+			service := NewService(
+				repo, &mockPasswordHasherVerificator{}, &mockCryptoKeyGenerator{}, tokens, nil, nil, notifier, nil,
+				auth.PasswordPolicy{},
+			)
+
+			err := service.ConfirmLoginChange(context.Background(), ConfirmLoginChangeParams{
+				OldToken: tt.oldToken,
+				NewToken: tt.newToken,
+			})
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Empty(t, notifier.changed)
+				return
+			}
+			require.NoError(t, err)
+			require.NotNil(t, saved)
+			assert.Equal(t, "alice@example.org", saved.Login)
+			assert.True(t, saved.SessionRevoked(time.Now().Add(-time.Minute)))
+			require.Len(t, notifier.changed, 1)
+			assert.Equal(t, LoginChange{UserID: userID, OldLogin: "alice", NewLogin: "alice@example.org"}, notifier.changed[0])
+		})
+	}
+}
+
+func TestService_ChangePassword(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	cryptoKey := []byte("user_crypto_key_unchanged_by_password")
+
+	tests := []struct {
+		saveErr     error
+		wantErr     error
+		name        string
+		oldPassword string
+		newPassword string
+	}{
+		{name: "password changed", oldPassword: "correct_password", newPassword: "new_password123"},
+		{
+			name:        "wrong current password",
+			oldPassword: "wrong_password",
+			newPassword: "new_password123",
+			wantErr:     ErrAuthPasswordMismatch,
+		},
+		{
+			name:        "invalid new password",
+			oldPassword: "correct_password",
+			newPassword: "",
+			wantErr:     ErrAuthIncorrectPassword,
+		},
+		{
+			name:        "new password violates policy",
+			oldPassword: "correct_password",
+			newPassword: "new_password",
+			wantErr:     ErrAuthPasswordNoDigit,
+		},
+		{
+			name:        "new password reused",
+			oldPassword: "correct_password",
+			newPassword: "old_password1",
+			wantErr:     ErrAuthPasswordReused,
+		},
+		{
+			name:        "save failure",
+			oldPassword: "correct_password",
+			newPassword: "new_password123",
+			saveErr:     errors.New("database unavailable"),
+			wantErr:     ErrAuthTechError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// saved holds the user state persisted by the service.
+			var saved *auth.User
+			repo := &mockRepository{
+				loadFunc: func(_ context.Context, params repository.LoadParams) (*auth.User, error) {
+					require.Equal(t, userID, params.ID)
+					key := append([]byte(nil), cryptoKey...)
+					return &auth.User{
+						ID:              userID,
+						Login:           "alice",
+						PasswordHash:    "old_hash",
+						PasswordHistory: []string{"hash:old_password1"},
+						CryptoKey:       key,
+					}, nil
+				},
+				saveFunc: func(_ context.Context, params repository.SaveParams) error {
+					if tt.saveErr != nil {
+						return tt.saveErr
+					}
+					u := *params.Entity
+					u.CryptoKey = append([]byte(nil), params.Entity.CryptoKey...)
+					saved = &u
+					return nil
+				},
+			}
+			hasher := &mockPasswordHasherVerificator{
+				hashFunc: func(password string) (string, error) { return "hash:" + password, nil },
+				verifyFunc: func(hash, password string) (bool, error) {
+					return hash == "hash:"+password || hash == "old_hash" && password == "correct_password", nil
+				},
+			}
+			policy := auth.NewPasswordPolicy(auth.PasswordPolicyParams{
+				RequiredClasses: []auth.PasswordClass{auth.PasswordClassDigit},
+				HistorySize:     3,
+			})
+			service := NewService(repo, hasher, &mockCryptoKeyGenerator{}, &mockTokenGenerateValidator{}, nil, nil, nil, nil,
+				policy)
+
+			token, err := service.ChangePassword(context.Background(), ChangePasswordParams{
+				UserID:      userID,
+				OldPassword: tt.oldPassword,
+				NewPassword: tt.newPassword,
+			})
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Empty(t, token.AccessToken)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotEmpty(t, token.AccessToken)
+			require.NotNil(t, saved)
+			assert.Equal(t, "hash:new_password123", saved.PasswordHash)
+			assert.Equal(t, []string{"old_hash", "hash:old_password1"}, saved.PasswordHistory)
+			assert.Equal(t, cryptoKey, saved.CryptoKey)
+			assert.True(t, saved.SessionRevoked(time.Now().Add(-time.Minute)))
+		})
+	}
+}
+
+func TestService_SetUnlockSecret(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	cryptoKey := []byte("user_crypto_key_sealed_by_unlock_k")
+	salt := []byte("0123456789abcdef")
+	currentKey := bytes.Repeat([]byte{1}, vaultunlock.KeySize)
+	newKey := bytes.Repeat([]byte{2}, vaultunlock.KeySize)
+	sealed, err := vaultunlock.Seal(currentKey, cryptoKey, userID)
+	require.NoError(t, err)
+
+	tests := []struct {
+		wantErr error
+		name    string
+		params  SetUnlockSecretParams
+		locked  bool
+	}{
+		{
+			name:   "first unlock secret",
+			params: SetUnlockSecretParams{Salt: salt, UnlockKey: newKey, Password: "correct_password"},
+		},
+		{
+			name:    "first unlock secret with wrong password",
+			params:  SetUnlockSecretParams{Salt: salt, UnlockKey: newKey, Password: "wrong_password"},
+			wantErr: ErrAuthPasswordMismatch,
+		},
+		{
+			name:   "changed unlock secret",
+			locked: true,
+			params: SetUnlockSecretParams{Salt: salt, UnlockKey: newKey, CurrentUnlockKey: currentKey},
+		},
+		{
+			name:    "change without current unlock key",
+			locked:  true,
+			params:  SetUnlockSecretParams{Salt: salt, UnlockKey: newKey, Password: "correct_password"},
+			wantErr: ErrAuthVaultLocked,
+		},
+		{
+			name:    "change with wrong current unlock key",
+			locked:  true,
+			params:  SetUnlockSecretParams{Salt: salt, UnlockKey: newKey, CurrentUnlockKey: newKey},
+			wantErr: ErrAuthUnlockInvalid,
+		},
+		{
+			name:    "short salt",
+			params:  SetUnlockSecretParams{Salt: []byte("salt"), UnlockKey: newKey, Password: "correct_password"},
+			wantErr: ErrAuthIncorrectUnlockSecret,
+		},
+		{
+			name:    "short unlock key",
+			params:  SetUnlockSecretParams{Salt: salt, UnlockKey: []byte("key"), Password: "correct_password"},
+			wantErr: ErrAuthIncorrectUnlockSecret,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// saved holds the user state persisted by the service.
+			var saved *auth.User
+			repo := &mockRepository{
+				loadFunc: func(_ context.Context, _ repository.LoadParams) (*auth.User, error) {
+					u := &auth.User{ID: userID, Login: "alice", PasswordHash: "hash"}
+					if tt.locked {
+						u.CryptoKey, u.UnlockSalt = bytes.Clone(sealed), []byte("fedcba9876543210")
+					} else {
+						u.CryptoKey = bytes.Clone(cryptoKey)
+					}
+					return u, nil
+				},
+				saveFunc: func(_ context.Context, params repository.SaveParams) error {
+					u := *params.Entity
+					u.CryptoKey = bytes.Clone(params.Entity.CryptoKey)
+					saved = &u
+					return nil
+				},
+			}
+			hasher := &mockPasswordHasherVerificator{
+				verifyFunc: func(_, password string) (bool, error) { return password == "correct_password", nil },
+			}
+			service := NewService(repo, hasher, &mockCryptoKeyGenerator{}, &mockTokenGenerateValidator{}, nil, nil, nil, nil,
+				auth.PasswordPolicy{})
+
+			tt.params.UserID = userID
+			err := service.SetUnlockSecret(context.Background(), tt.params)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, saved)
+				return
+			}
+			require.NoError(t, err)
+			require.NotNil(t, saved)
+			assert.Equal(t, salt, saved.UnlockSalt)
+			opened, err := vaultunlock.Open(newKey, saved.CryptoKey, userID)
+			require.NoError(t, err)
+			assert.Equal(t, cryptoKey, opened)
+		})
+	}
+}
+
+func TestService_Unlock(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	unlockKey := bytes.Repeat([]byte{1}, vaultunlock.KeySize)
+	sealed, err := vaultunlock.Seal(unlockKey, []byte("user_crypto_key"), userID)
+	require.NoError(t, err)
+
+	tests := []struct {
+		wantErr   error
+		name      string
+		unlockKey []byte
+		locked    bool
+	}{
+		{name: "no unlock secret"},
+		{name: "matching unlock key", locked: true, unlockKey: unlockKey},
+		{name: "missing unlock key", locked: true, wantErr: ErrAuthVaultLocked},
+		{
+			name:      "wrong unlock key",
+			locked:    true,
+			unlockKey: bytes.Repeat([]byte{2}, vaultunlock.KeySize),
+			wantErr:   ErrAuthUnlockInvalid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &mockRepository{
+				loadFunc: func(_ context.Context, _ repository.LoadParams) (*auth.User, error) {
+					if tt.locked {
+						return &auth.User{ID: userID, CryptoKey: bytes.Clone(sealed), UnlockSalt: []byte("salt")}, nil
+					}
+					return &auth.User{ID: userID, CryptoKey: []byte("user_crypto_key")}, nil
+				},
+			}
+			service := NewService(repo, &mockPasswordHasherVerificator{}, &mockCryptoKeyGenerator{},
+				&mockTokenGenerateValidator{}, nil, nil, nil, nil, auth.PasswordPolicy{})
+
+			err := service.Unlock(context.Background(), userID, tt.unlockKey)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestService_VerifyLoginWithRecoveryCode(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+
+	tests := []struct {
+		wantErr      error
+		name         string
+		recoveryCode string
+		holder       uuid.UUID
+	}{
+		{name: "recovery code accepted", recoveryCode: "ABCD-EFGH", holder: userID},
+		{name: "unknown recovery code", recoveryCode: "abcd-efgi", holder: userID, wantErr: ErrAuthRecoveryCodeInvalid},
+		{name: "expired challenge", recoveryCode: "abcd-efgh", wantErr: ErrAuthStepUpFailed},
+		{
+			name:         "user of the challenge removed",
+			recoveryCode: "abcd-efgh",
+			holder:       uuid.New(),
+			wantErr:      ErrAuthRecoveryCodeInvalid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// saved holds the user state persisted by the service.
+			var saved *auth.User
+			repo := &mockRepository{
+				loadFunc: func(_ context.Context, params repository.LoadParams) (*auth.User, error) {
+					if params.ID != userID {
+						return nil, repository.ErrUserNotFound
+					}
+					return &auth.User{ID: userID, RecoveryCodes: [][]byte{
+						auth.HashRecoveryCode("abcd-efgh"), auth.HashRecoveryCode("ijkl-mnop"),
+					}}, nil
+				},
+				saveFunc: func(_ context.Context, params repository.SaveParams) error {
+					saved = params.Entity
+					return nil
+				},
+			}
+			guard := &mockLoginGuard{recoverUserID: tt.holder}
+			service := NewService(
+				repo, &mockPasswordHasherVerificator{}, &mockCryptoKeyGenerator{}, &mockTokenGenerateValidator{},
+				guard, nil, nil, nil,
+				auth.PasswordPolicy{},
+			)
+
+			token, err := service.VerifyLogin(context.Background(), VerifyLoginParams{
+				ChallengeID:  uuid.New(),
+				RecoveryCode: tt.recoveryCode,
+			})
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, saved)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotEmpty(t, token.AccessToken)
+			require.NotNil(t, saved)
+			assert.Equal(t, [][]byte{auth.HashRecoveryCode("ijkl-mnop")}, saved.RecoveryCodes)
+		})
+	}
+}
+
+func TestService_RegenerateRecoveryCodes(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	previous := auth.HashRecoveryCode("abcd-efgh")
+
+	tests := []struct {
+		saveErr  error
+		wantErr  error
+		name     string
+		password string
+	}{
+		{name: "codes regenerated", password: "correct_password"},
+		{name: "wrong password", password: "wrong_password", wantErr: ErrAuthPasswordMismatch},
+		{
+			name:     "save failure",
+			password: "correct_password",
+			saveErr:  errors.New("database unavailable"),
+			wantErr:  ErrAuthTechError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// saved holds the user state persisted by the service.
+			var saved *auth.User
+			repo := &mockRepository{
+				loadFunc: func(_ context.Context, params repository.LoadParams) (*auth.User, error) {
+					require.Equal(t, userID, params.ID)
+					return &auth.User{ID: userID, PasswordHash: "hash", RecoveryCodes: [][]byte{previous}}, nil
+				},
+				saveFunc: func(_ context.Context, params repository.SaveParams) error {
+					if tt.saveErr != nil {
+						return tt.saveErr
+					}
+					saved = params.Entity
+					return nil
+				},
+			}
+			hasher := &mockPasswordHasherVerificator{
+				verifyFunc: func(_, password string) (bool, error) { return password == "correct_password", nil },
+			}
+			keyGen := &mockCryptoKeyGenerator{generateFunc: func(size int) ([]byte, error) {
+				b := make([]byte, size)
+				_, err := rand.Read(b)
+				return b, err
+			}}
+			service := NewService(repo, hasher, keyGen, &mockTokenGenerateValidator{}, nil, nil, nil, nil,
+				auth.PasswordPolicy{})
+
+			codes, err := service.RegenerateRecoveryCodes(context.Background(), RegenerateRecoveryCodesParams{
+				UserID:   userID,
+				Password: tt.password,
+			})
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Empty(t, codes)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, codes, auth.RecoveryCodeCount)
+			require.NotNil(t, saved)
+			assert.Equal(t, auth.RecoveryCodeCount, saved.RecoveryCodesLeft())
+			assert.NotContains(t, saved.RecoveryCodes, previous)
+			assert.True(t, saved.UseRecoveryCode(codes[0]))
+		})
+	}
+}
+
+func TestService_RecoveryCodesLeft(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	repo := &mockRepository{
+		loadFunc: func(_ context.Context, params repository.LoadParams) (*auth.User, error) {
+			require.Equal(t, userID, params.ID)
+			return &auth.User{ID: userID, RecoveryCodes: [][]byte{auth.HashRecoveryCode("abcd-efgh")}}, nil
+		},
+	}
+	service := NewService(
+		repo, &mockPasswordHasherVerificator{}, &mockCryptoKeyGenerator{}, &mockTokenGenerateValidator{}, nil, nil, nil, nil,
+		auth.PasswordPolicy{},
+	)
+
+	remaining, err := service.RecoveryCodesLeft(context.Background(), userID)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, remaining)
+}
+
+func TestService_ConfirmPassword(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+
+	tests := []struct {
+		loadErr  error
+		wantErr  error
+		name     string
+		password string
+	}{
+		{name: "password confirmed", password: "correct_password"},
+		{name: "wrong password", password: "wrong_password", wantErr: ErrAuthPasswordMismatch},
+		{
+			name:     "load failure",
+			password: "correct_password",
+			loadErr:  errors.New("database unavailable"),
+			wantErr:  ErrAuthTechError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &mockRepository{
+				loadFunc: func(_ context.Context, params repository.LoadParams) (*auth.User, error) {
+					require.Equal(t, userID, params.ID)
+					if tt.loadErr != nil {
+						return nil, tt.loadErr
+					}
+					return &auth.User{ID: userID, PasswordHash: "hash"}, nil
+				},
+			}
+			hasher := &mockPasswordHasherVerificator{
+				verifyFunc: func(_, password string) (bool, error) { return password == "correct_password", nil },
+			}
+			service := NewService(repo, hasher, &mockCryptoKeyGenerator{}, &mockTokenGenerateValidator{}, nil, nil, nil, nil,
+				auth.PasswordPolicy{})
+
+			err := service.ConfirmPassword(context.Background(), userID, tt.password)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestService_RecoverAccount(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+
+	tests := []struct {
+		wantErr      error
+		name         string
+		login        string
+		recoveryCode string
+		newPassword  string
+		inactive     bool
+	}{
+		{name: "account recovered", login: "alice", recoveryCode: "abcd-efgh", newPassword: "new_password123"},
+		{
+			name:         "unknown login",
+			login:        "mallory",
+			recoveryCode: "abcd-efgh",
+			newPassword:  "new_password123",
+			wantErr:      ErrAuthRecoveryCodeInvalid,
+		},
+		{
+			name:         "wrong recovery code",
+			login:        "alice",
+			recoveryCode: "abcd-efgi",
+			newPassword:  "new_password123",
+			wantErr:      ErrAuthRecoveryCodeInvalid,
+		},
+		{
+			name:         "invalid new password",
+			login:        "alice",
+			recoveryCode: "abcd-efgh",
+			newPassword:  "short",
+			wantErr:      ErrAuthIncorrectPassword,
+		},
+		{
+			name:         "disabled user",
+			login:        "alice",
+			recoveryCode: "abcd-efgh",
+			newPassword:  "new_password123",
+			inactive:     true,
+			wantErr:      ErrAuthUserDisabled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// saved holds the user state persisted by the service.
+			var saved *auth.User
+			repo := &mockRepository{
+				loadFunc: func(_ context.Context, params repository.LoadParams) (*auth.User, error) {
+					if params.Login != "alice" {
+						return nil, repository.ErrUserNotFound
+					}
+					return &auth.User{
+						ID:            userID,
+						Login:         "alice",
+						PasswordHash:  "old_hash",
+						Active:        !tt.inactive,
+						RecoveryCodes: [][]byte{auth.HashRecoveryCode("abcd-efgh")},
+					}, nil
+				},
+				saveFunc: func(_ context.Context, params repository.SaveParams) error {
+					saved = params.Entity
+					return nil
+				},
+			}
+			hasher := &mockPasswordHasherVerificator{
+				hashFunc: func(password string) (string, error) { return "hash:" + password, nil },
+			}
+			service := NewService(repo, hasher, &mockCryptoKeyGenerator{}, &mockTokenGenerateValidator{}, nil, nil, nil, nil,
+				auth.PasswordPolicy{})
+
+			token, err := service.RecoverAccount(context.Background(), RecoverAccountParams{
+				Login:        tt.login,
+				RecoveryCode: tt.recoveryCode,
+				NewPassword:  tt.newPassword,
+			})
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Empty(t, token.AccessToken)
+				assert.Nil(t, saved)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotEmpty(t, token.AccessToken)
+			require.NotNil(t, saved)
+			assert.Equal(t, "hash:new_password123", saved.PasswordHash)
+			assert.Zero(t, saved.RecoveryCodesLeft())
+			assert.True(t, saved.SessionRevoked(time.Now().Add(-time.Minute)))
+		})
+	}
+}
@@ -0,0 +1,6 @@
+// Package authz provides the authorization decision point of the AegisVaultKeeper server.
+//
+// This package evaluates the attribute-based access control policy for every action application
+// services perform on vault items, describing the subject, resource and request environment to the
+// policy and recording each denial in the audit trail.
+package authz
@@ -0,0 +1,17 @@
+package authz
+
+import "github.com/google/uuid"
+
+// Request describes an action a user performs on a vault item or collection of items.
+type Request struct {
+	// Action contains the action performed, such as authz.ActionRead.
+	Action string
+	// Kind contains the kind of item acted on, such as authz.KindNote.
+	Kind string
+	// ResourceID identifies the item acted on, or uuid.Nil for new items and whole collections.
+	ResourceID uuid.UUID
+	// OwnerID identifies the user owning the item or collection acted on.
+	OwnerID uuid.UUID
+	// SubjectID identifies the user performing the action.
+	SubjectID uuid.UUID
+}
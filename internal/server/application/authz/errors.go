@@ -0,0 +1,9 @@
+package authz
+
+import "errors"
+
+// Authorization error definitions.
+var (
+	// ErrAccessDenied indicates that the authorization policy does not permit the action.
+	ErrAccessDenied = errors.New("action is not permitted by the authorization policy")
+)
@@ -0,0 +1,88 @@
+package authz
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/audit"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/clientinfo"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/authz"
+	"github.com/google/uuid"
+)
+
+// defaultDenyRule names the decision in audit records when no rule of the policy matched.
+const defaultDenyRule = "default deny"
+
+// AuditRecorder defines the interface for recording security audit events.
+type AuditRecorder interface {
+	// Record stores the audit event.
+	Record(ctx context.Context, e audit.Event)
+}
+
+// Service decides authorization requests against the configured policy.
+type Service struct {
+	// policy is the attribute-based access control policy requests are decided by.
+	policy *authz.Policy
+	// audit records denied requests.
+	audit AuditRecorder
+	// now returns the current time of environment attributes.
+	now func() time.Time
+}
+
+// NewService creates a new authorization service deciding requests by the policy.
+func NewService(policy *authz.Policy, audit AuditRecorder) *Service {
+	return &Service{policy: policy, audit: audit, now: time.Now}
+}
+
+// Authorize decides whether the request is permitted, returning ErrAccessDenied when it is not.
+// The client address of the request is taken from the context.
+func (s *Service) Authorize(ctx context.Context, req Request) error {
+	decision := s.policy.Evaluate(s.attributes(ctx, req))
+	if decision.Allowed {
+		return nil
+	}
+
+	rule := defaultDenyRule
+	if decision.Rule != nil {
+		rule = decision.Rule.Text
+	}
+	details := map[string]string{
+		"action": req.Action,
+		"kind":   req.Kind,
+		"rule":   rule,
+	}
+	if req.ResourceID != uuid.Nil {
+		details["resource_id"] = req.ResourceID.String()
+	}
+	if req.OwnerID != req.SubjectID {
+		details["owner_id"] = req.OwnerID.String()
+	}
+	s.audit.Record(ctx, audit.Event{
+		Type:    audit.EventAuthzDenied,
+		UserID:  req.SubjectID,
+		Details: details,
+	})
+	return fmt.Errorf("%s %s denied by %q: %w", req.Action, req.Kind, rule, ErrAccessDenied)
+}
+
+// attributes describes the request and its environment to the policy.
+func (s *Service) attributes(ctx context.Context, req Request) authz.Attributes {
+	now := s.now().UTC()
+	attrs := authz.Attributes{
+		authz.AttrAction:        req.Action,
+		authz.AttrSubjectID:     req.SubjectID.String(),
+		authz.AttrResourceKind:  req.Kind,
+		authz.AttrResourceOwner: req.OwnerID.String(),
+		authz.AttrEnvWeekday:    strings.ToLower(now.Weekday().String()[:3]),
+		authz.AttrEnvHour:       fmt.Sprintf("%02d", now.Hour()),
+	}
+	if req.ResourceID != uuid.Nil {
+		attrs[authz.AttrResourceID] = req.ResourceID.String()
+	}
+	if ip, ok := clientinfo.IP(ctx); ok {
+		attrs[authz.AttrEnvIP] = ip.Unmap().String()
+	}
+	return attrs
+}
@@ -0,0 +1,134 @@
+package authz
+
+import (
+	"context"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/audit"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/clientinfo"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/authz"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockAuditRecorder collects recorded audit events.
+type mockAuditRecorder struct {
+	events []audit.Event
+}
+
+func (m *mockAuditRecorder) Record(_ context.Context, e audit.Event) {
+	m.events = append(m.events, e)
+}
+
+func TestService_Authorize(t *testing.T) {
+	t.Parallel()
+
+	policy, err := authz.Parse(authz.DefaultPolicy + "\n" +
+		"deny write * when env.ip in 203.0.113.0/24\n" +
+		"deny read bankcard when env.weekday == sun and env.hour == 03\n")
+	require.NoError(t, err)
+
+	userID, otherID, itemID := uuid.New(), uuid.New(), uuid.New()
+	monday := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	sundayNight := time.Date(2024, 1, 7, 3, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		at          time.Time
+		name        string
+		ip          string
+		wantRule    string
+		wantDetails map[string]string
+		req         Request
+		wantErr     bool
+	}{
+		{
+			name: "owner reads",
+			at:   monday,
+			req: Request{
+				Action:     authz.ActionRead,
+				Kind:       authz.KindNote,
+				ResourceID: itemID,
+				OwnerID:    userID,
+				SubjectID:  userID,
+			},
+		},
+		{
+			name: "other user reads",
+			at:   monday,
+			req: Request{
+				Action:     authz.ActionRead,
+				Kind:       authz.KindNote,
+				ResourceID: itemID,
+				OwnerID:    otherID,
+				SubjectID:  userID,
+			},
+			wantErr: true,
+			wantDetails: map[string]string{
+				"action":      authz.ActionRead,
+				"kind":        authz.KindNote,
+				"rule":        defaultDenyRule,
+				"resource_id": itemID.String(),
+				"owner_id":    otherID.String(),
+			},
+		},
+		{
+			name:    "write from denied network",
+			at:      monday,
+			ip:      "203.0.113.7",
+			req:     Request{Action: authz.ActionWrite, Kind: authz.KindCredential, OwnerID: userID, SubjectID: userID},
+			wantErr: true,
+			wantDetails: map[string]string{
+				"action": authz.ActionWrite,
+				"kind":   authz.KindCredential,
+				"rule":   "deny write * when env.ip in 203.0.113.0/24",
+			},
+		},
+		{
+			name: "write from other network",
+			at:   monday,
+			ip:   "192.0.2.1",
+			req:  Request{Action: authz.ActionWrite, Kind: authz.KindCredential, OwnerID: userID, SubjectID: userID},
+		},
+		{
+			name:    "bank card listing at denied time",
+			at:      sundayNight,
+			req:     Request{Action: authz.ActionRead, Kind: authz.KindBankCard, OwnerID: userID, SubjectID: userID},
+			wantErr: true,
+			wantDetails: map[string]string{
+				"action": authz.ActionRead,
+				"kind":   authz.KindBankCard,
+				"rule":   "deny read bankcard when env.weekday == sun and env.hour == 03",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			recorder := &mockAuditRecorder{}
+			s := NewService(policy, recorder)
+			s.now = func() time.Time { return tt.at }
+			ctx := context.Background()
+			if tt.ip != "" {
+				ctx = clientinfo.WithIP(ctx, netip.MustParseAddr(tt.ip))
+			}
+
+			err := s.Authorize(ctx, tt.req)
+
+			if !tt.wantErr {
+				require.NoError(t, err)
+				assert.Empty(t, recorder.events)
+				return
+			}
+			require.ErrorIs(t, err, ErrAccessDenied)
+			require.Len(t, recorder.events, 1)
+			assert.Equal(t, audit.EventAuthzDenied, recorder.events[0].Type)
+			assert.Equal(t, userID, recorder.events[0].UserID)
+			assert.Equal(t, tt.wantDetails, recorder.events[0].Details)
+		})
+	}
+}
@@ -27,6 +27,8 @@ type BankCard struct {
 	ID uuid.UUID
 	// UserID is the identifier of the user who owns the card.
 	UserID uuid.UUID
+	// Archived reports whether the bank card is tagged archived; set for listed bank cards only.
+	Archived bool
 }
 
 // newBankCardFromDomain converts a domain bank card entity to application DTO.
@@ -58,6 +60,10 @@ func newBankCardsFromDomain(bcs []*bankcard.BankCard) []*BankCard {
 
 // PullParams contains parameters for retrieving a specific bank card.
 type PullParams struct {
+	// AsOf selects the version current at the specified moment; zero value pulls the current version.
+	AsOf time.Time
+	// Fields selects the sensitive fields of the card to decrypt by name; empty selects all of them.
+	Fields []string
 	// ID is the unique identifier of the bank card to retrieve.
 	ID uuid.UUID
 	// UserID is the identifier of the user who owns the card.
@@ -66,8 +72,33 @@ type PullParams struct {
 
 // ListParams contains parameters for listing bank cards.
 type ListParams struct {
+	// After lists only the cards whose ID sorts after it; zero value starts at the first.
+	After uuid.UUID
+	// AsOf selects the versions current at the specified moment; zero value lists current versions.
+	AsOf time.Time
+	// Fields selects the sensitive fields of cards to decrypt by name; empty selects all of them.
+	Fields []string
+	// Limit caps the number of listed cards and orders them by ID; zero value lists all.
+	Limit int
 	// UserID is the identifier of the user whose cards to list.
 	UserID uuid.UUID
+	// OrderFolder names the folder whose manual order sorts the bank cards with ManualOrder; empty is the root.
+	OrderFolder string
+	// ExcludeArchived leaves out the bank cards tagged archived; zero value lists every bank card.
+	ExcludeArchived bool
+	// ManualOrder sorts the bank cards in the manual order of OrderFolder, the others after them in their default
+	// order; zero value keeps the default order.
+	ManualOrder bool
+}
+
+// RecoverParams contains parameters for restoring a bank card to an earlier version.
+type RecoverParams struct {
+	// AsOf specifies the moment whose bank card version becomes current again.
+	AsOf time.Time
+	// ID specifies the bank card to recover.
+	ID uuid.UUID
+	// UserID specifies the bank card owner.
+	UserID uuid.UUID
 }
 
 // PushParams contains parameters for creating or updating a bank card.
@@ -89,3 +120,11 @@ type PushParams struct {
 	// UserID is the identifier of the user who owns the card.
 	UserID uuid.UUID
 }
+
+// PushBatchParams contains parameters for creating or updating several bank cards of one user at once.
+type PushBatchParams struct {
+	// Items contains the bank cards to push; their UserID is replaced by the batch UserID.
+	Items []*PushParams
+	// UserID identifies the owner of all bank cards in the batch.
+	UserID uuid.UUID
+}
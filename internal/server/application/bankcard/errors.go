@@ -6,6 +6,7 @@ import (
 
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/errutil"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/bankcard"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/fieldcrypt"
 )
 
 // Bank card application error definitions.
@@ -42,6 +43,9 @@ var (
 
 	// ErrBankCardAccessDenied indicates access to the bank card is not permitted.
 	ErrBankCardAccessDenied = errors.New("access to this bank card is denied")
+
+	// ErrBankCardIntegrityViolation indicates stored bank card data failed ownership integrity verification.
+	ErrBankCardIntegrityViolation = errors.New("bank card data integrity violation")
 )
 
 // mapError maps domain errors to application-level errors.
@@ -75,6 +79,8 @@ func mapFn(err error) error {
 		return ErrBankCardCardExpired
 	case errors.Is(err, bankcard.ErrInvalidCVV):
 		return ErrBankCardInvalidCVV
+	case errors.Is(err, fieldcrypt.ErrIntegrityViolation):
+		return errors.Join(ErrBankCardIntegrityViolation, err)
 	default:
 		return errors.Join(ErrBankCardTechError, err)
 	}
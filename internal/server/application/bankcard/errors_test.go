@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/bankcard"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/fieldcrypt"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -62,6 +63,11 @@ func TestMapError(t *testing.T) {
 			inputErr: bankcard.ErrInvalidCVV,
 			wantErr:  ErrBankCardInvalidCVV,
 		},
+		{
+			name:     "integrity_violation",
+			inputErr: fieldcrypt.ErrIntegrityViolation,
+			wantErr:  ErrBankCardIntegrityViolation,
+		},
 		{
 			name:     "unknown_error",
 			inputErr: errors.New("unknown error"),
@@ -140,6 +146,11 @@ func TestMapFn(t *testing.T) {
 			inputErr: errors.Join(errors.New("wrapper"), bankcard.ErrInvalidCardNumber),
 			wantErr:  ErrBankCardInvalidCardNumber,
 		},
+		{
+			name:     "integrity_violation",
+			inputErr: fieldcrypt.ErrIntegrityViolation,
+			wantErr:  ErrBankCardIntegrityViolation,
+		},
 		{
 			name:     "unknown_error",
 			inputErr: errors.New("unknown error"),
@@ -4,9 +4,19 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
+	"time"
 
+	authzApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/authz"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/authz"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/bankcard"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/itemorder"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/itempath"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/itemtag"
 	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/bankcard"
+	orderRepository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/itemorder"
+	tagRepository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/itemtag"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/securebytes"
 	"github.com/google/uuid"
 )
 
@@ -15,45 +25,158 @@ type Repository interface {
 	// Save persists bank card data using the provided parameters.
 	Save(ctx context.Context, params repository.SaveParams) error
 
+	// SaveBatch persists several bank card entities of one user at once.
+	SaveBatch(ctx context.Context, params repository.SaveBatchParams) error
+
 	// Load retrieves bank card data using the provided parameters.
 	Load(ctx context.Context, params repository.LoadParams) ([]*bankcard.BankCard, error)
 }
 
+// Authorizer defines the interface for the authorization decision point.
+type Authorizer interface {
+	// Authorize decides whether the user may perform the action on the bank card.
+	Authorize(ctx context.Context, req authzApp.Request) error
+}
+
+// PlanEnforcer defines the interface for checking the limits of the plans of users.
+type PlanEnforcer interface {
+	// CheckItems ensures that the plan of the user has room for the given number of additional items.
+	CheckItems(ctx context.Context, userID uuid.UUID, added int) error
+}
+
+// PolicyEnforcer defines the interface for checking vault items against the item policies of users.
+type PolicyEnforcer interface {
+	// CheckItem ensures that the item policies of the user allow storing items of the kind.
+	CheckItem(ctx context.Context, userID uuid.UUID, kind string) error
+}
+
+// TagRepository defines the interface for looking up the tags of vault items.
+type TagRepository interface {
+	// Load retrieves the tags of the items of the user matching the parameters.
+	Load(ctx context.Context, params tagRepository.LoadParams) ([]*itemtag.ItemTags, error)
+}
+
+// OrderRepository defines the interface for looking up the manual orders of folders.
+type OrderRepository interface {
+	// Load retrieves the manual order of a folder of the user.
+	Load(ctx context.Context, params orderRepository.LoadParams) (*itemorder.ItemOrder, error)
+}
+
 // Service provides bank card business logic operations.
 type Service struct {
 	// r is the repository interface for bank card data persistence operations.
 	r Repository
+	// authorizer decides whether users may perform actions on bank cards.
+	authorizer Authorizer
+	// plans checks the plan limits of new bank cards; nil leaves them unlimited.
+	plans PlanEnforcer
+	// policies checks created and updated bank cards against the item policies; nil enforces none.
+	policies PolicyEnforcer
+	// tags looks up archived bank cards; nil archives none.
+	tags TagRepository
+	// orders looks up the manual orders of folders; nil keeps every listing in its default order.
+	orders OrderRepository
 }
 
-// NewService creates a new bank card service instance with the provided repository.
-func NewService(r Repository) *Service {
-	return &Service{r: r}
+// NewService creates a new bank card service instance with the provided repository, authorization decision point,
+// plan enforcer (nil means no plan limits), item policy enforcer (nil means no item policies), tag repository
+// (nil means no archived cards) and order repository (nil means no manual orders).
+func NewService(
+	r Repository,
+	authorizer Authorizer,
+	plans PlanEnforcer,
+	policies PolicyEnforcer,
+	tags TagRepository,
+	orders OrderRepository,
+) *Service {
+	return &Service{r: r, authorizer: authorizer, plans: plans, policies: policies, tags: tags, orders: orders}
 }
 
 // Pull retrieves a specific bank card for the given user and card ID.
 func (s *Service) Pull(ctx context.Context, params PullParams) (*BankCard, error) {
 	cards, err := s.r.Load(ctx, repository.LoadParams{
+		AsOf:   params.AsOf,
+		Fields: params.Fields,
 		ID:     params.ID,
 		UserID: params.UserID,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to load bank cards: %w", mapError(err))
 	}
+	defer securebytes.WipeAll(cards)
 	if len(cards) == 0 {
 		return nil, fmt.Errorf("bank card not found: %w", ErrBankCardNotFound)
 	}
+	if err := s.authorize(ctx, authz.ActionRead, cards[0].ID, cards[0].UserID, params.UserID); err != nil {
+		return nil, err
+	}
 	return newBankCardFromDomain(cards[0]), nil
 }
 
 // List retrieves all bank cards for the specified user.
 func (s *Service) List(ctx context.Context, params ListParams) ([]*BankCard, error) {
+	if err := s.authorize(ctx, authz.ActionRead, uuid.Nil, params.UserID, params.UserID); err != nil {
+		return nil, err
+	}
 	cards, err := s.r.Load(ctx, repository.LoadParams{
+		After:  params.After,
+		AsOf:   params.AsOf,
+		Fields: params.Fields,
+		Limit:  params.Limit,
 		UserID: params.UserID,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to load bank cards: %w", mapError(err))
 	}
-	return newBankCardsFromDomain(cards), nil
+	defer securebytes.WipeAll(cards)
+
+	archived, err := s.archived(ctx, params.UserID)
+	if err != nil {
+		return nil, err
+	}
+	result := newBankCardsFromDomain(cards)
+	for _, c := range result {
+		c.Archived = archived[c.ID]
+	}
+	if params.ExcludeArchived {
+		result = slices.DeleteFunc(result, func(c *BankCard) bool { return c.Archived })
+	}
+	if params.ManualOrder {
+		order, err := s.order(ctx, params.UserID, params.OrderFolder)
+		if err != nil {
+			return nil, err
+		}
+		itemorder.Sort(order, result, func(c *BankCard) uuid.UUID { return c.ID })
+	}
+	return result, nil
+}
+
+// archived returns the IDs of the archived bank cards of the user; without a tag repository none is archived.
+func (s *Service) archived(ctx context.Context, userID uuid.UUID) (map[uuid.UUID]bool, error) {
+	if s.tags == nil {
+		return nil, nil
+	}
+	tagged, err := s.tags.Load(ctx, tagRepository.LoadParams{Tag: itemtag.Archived, UserID: userID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load archived bank cards: %w", mapError(err))
+	}
+	return itemtag.ItemIDs(tagged), nil
+}
+
+// order returns the manual order of the folder of the user; without an order repository, or for a folder the user
+// has not arranged, there is none.
+func (s *Service) order(ctx context.Context, userID uuid.UUID, folder string) (*itemorder.ItemOrder, error) {
+	if s.orders == nil {
+		return nil, nil
+	}
+	o, err := s.orders.Load(ctx, orderRepository.LoadParams{Folder: itempath.Normalize(folder), UserID: userID})
+	if errors.Is(err, orderRepository.ErrOrderNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load item order: %w", mapError(err))
+	}
+	return o, nil
 }
 
 // Push creates or updates a bank card with the provided parameters.
@@ -70,12 +193,23 @@ func (s *Service) Push(ctx context.Context, params *PushParams) (uuid.UUID, erro
 	if err != nil {
 		return uuid.Nil, fmt.Errorf("failed to create bank card: %w", mapError(err))
 	}
+	defer card.Wipe()
 
 	if params.ID != uuid.Nil {
 		if err := s.checkAccessToUpdate(ctx, params.ID, params.UserID); err != nil {
 			return uuid.Nil, fmt.Errorf("access check for updating bank card failed: %w", err)
 		}
 		card.ID = params.ID
+	} else {
+		if err := s.authorize(ctx, authz.ActionWrite, uuid.Nil, params.UserID, params.UserID); err != nil {
+			return uuid.Nil, fmt.Errorf("access check for creating bank card failed: %w", err)
+		}
+		if err := s.checkPlan(ctx, params.UserID, 1); err != nil {
+			return uuid.Nil, fmt.Errorf("plan check for creating bank card failed: %w", err)
+		}
+	}
+	if err := s.checkPolicy(ctx, params.UserID); err != nil {
+		return uuid.Nil, fmt.Errorf("item policy check for bank card failed: %w", err)
 	}
 
 	if err := s.r.Save(ctx, repository.SaveParams{Entity: card}); err != nil {
@@ -84,17 +218,165 @@ func (s *Service) Push(ctx context.Context, params *PushParams) (uuid.UUID, erro
 	return card.ID, nil
 }
 
-// checkAccessToUpdate verifies that a user has permission to update a specific bank card.
+// PushBatch creates or updates several bank cards of one user and returns their IDs in input order.
+// The bank cards to update are checked with a single query and everything is saved with batch statements, which
+// keeps large sync pushes fast. When several items share an ID, the last one wins.
+func (s *Service) PushBatch(ctx context.Context, params PushBatchParams) ([]uuid.UUID, error) {
+	cards := make([]*bankcard.BankCard, 0, len(params.Items))
+	defer func() { securebytes.WipeAll(cards) }()
+	ids := make([]uuid.UUID, 0, len(params.Items))
+	// updateIDs collects the IDs of existing bank cards the batch updates.
+	var updateIDs []uuid.UUID
+	for i, item := range params.Items {
+		card, err := bankcard.NewBankCard(&bankcard.NewBankCardParams{
+			UserID:      params.UserID,
+			CardNumber:  item.CardNumber,
+			CardHolder:  item.CardHolder,
+			ExpiryMonth: item.ExpiryMonth,
+			ExpiryYear:  item.ExpiryYear,
+			CVV:         item.CVV,
+			Description: item.Description,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create bank card at index %d: %w", i, mapError(err))
+		}
+		if item.ID != uuid.Nil {
+			card.ID = item.ID
+			updateIDs = append(updateIDs, item.ID)
+		}
+		cards = append(cards, card)
+		ids = append(ids, card.ID)
+	}
+
+	if err := s.checkAccessToUpdateBatch(ctx, updateIDs, params.UserID); err != nil {
+		return nil, fmt.Errorf("access check for updating bank cards failed: %w", err)
+	}
+	if len(updateIDs) < len(params.Items) {
+		if err := s.authorize(ctx, authz.ActionWrite, uuid.Nil, params.UserID, params.UserID); err != nil {
+			return nil, fmt.Errorf("access check for creating bank cards failed: %w", err)
+		}
+		if err := s.checkPlan(ctx, params.UserID, len(params.Items)-len(updateIDs)); err != nil {
+			return nil, fmt.Errorf("plan check for creating bank cards failed: %w", err)
+		}
+	}
+	if len(params.Items) != 0 {
+		if err := s.checkPolicy(ctx, params.UserID); err != nil {
+			return nil, fmt.Errorf("item policy check for bank cards failed: %w", err)
+		}
+	}
+
+	// latest holds the last bank card pushed for every ID, since a statement cannot update a row twice.
+	latest := make([]*bankcard.BankCard, 0, len(cards))
+	seen := make(map[uuid.UUID]struct{}, len(cards))
+	for _, card := range slices.Backward(cards) {
+		if _, ok := seen[card.ID]; ok {
+			continue
+		}
+		seen[card.ID] = struct{}{}
+		latest = append(latest, card)
+	}
+	slices.Reverse(latest)
+
+	if err := s.r.SaveBatch(ctx, repository.SaveBatchParams{Entities: latest, UserID: params.UserID}); err != nil {
+		return nil, fmt.Errorf("failed to save bank cards: %w", mapError(err))
+	}
+	return ids, nil
+}
+
+// Recover makes the bank card version current at the specified moment the latest version again.
+// The recovered version is saved as a new revision, so the replaced state stays retained.
+func (s *Service) Recover(ctx context.Context, params RecoverParams) (*BankCard, error) {
+	cards, err := s.r.Load(ctx, repository.LoadParams{
+		AsOf:   params.AsOf,
+		ID:     params.ID,
+		UserID: params.UserID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load bank card version: %w", mapError(err))
+	}
+	defer securebytes.WipeAll(cards)
+	if len(cards) == 0 {
+		return nil, fmt.Errorf("bank card version not found: %w", ErrBankCardNotFound)
+	}
+	if err := s.authorize(ctx, authz.ActionWrite, cards[0].ID, cards[0].UserID, params.UserID); err != nil {
+		return nil, err
+	}
+
+	card := cards[0]
+	card.UpdatedAt = time.Now()
+	if err := s.r.Save(ctx, repository.SaveParams{Entity: card}); err != nil {
+		return nil, fmt.Errorf("failed to save recovered bank card: %w", mapError(err))
+	}
+	return newBankCardFromDomain(card), nil
+}
+
+// checkAccessToUpdate verifies that the bank card to update exists and that the user may change it.
 func (s *Service) checkAccessToUpdate(ctx context.Context, cardID, userID uuid.UUID) error {
-	exists, err := s.Pull(ctx, PullParams{ID: cardID, UserID: userID})
+	existing, err := s.r.Load(ctx, repository.LoadParams{ID: cardID, UserID: userID})
 	if err != nil {
-		if errors.Is(err, ErrBankCardNotFound) {
-			return fmt.Errorf("bank card for update not found: %w", err)
-		}
 		return fmt.Errorf("failed to pull existing bank card: %w", mapError(err))
 	}
-	if exists.UserID != userID {
-		return fmt.Errorf("access denied to bank card: %w", ErrBankCardAccessDenied)
+	defer securebytes.WipeAll(existing)
+	if len(existing) == 0 {
+		return fmt.Errorf("bank card for update not found: %w", ErrBankCardNotFound)
+	}
+	return s.authorize(ctx, authz.ActionWrite, existing[0].ID, existing[0].UserID, userID)
+}
+
+// checkAccessToUpdateBatch verifies with a single query that all bank cards to update exist and that the user may
+// change them.
+func (s *Service) checkAccessToUpdateBatch(ctx context.Context, ids []uuid.UUID, userID uuid.UUID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	existing, err := s.r.Load(ctx, repository.LoadParams{IDs: ids, UserID: userID})
+	if err != nil {
+		return fmt.Errorf("failed to load existing bank cards: %w", mapError(err))
+	}
+	defer securebytes.WipeAll(existing)
+	owned := make(map[uuid.UUID]struct{}, len(existing))
+	for _, e := range existing {
+		if err := s.authorize(ctx, authz.ActionWrite, e.ID, e.UserID, userID); err != nil {
+			return err
+		}
+		owned[e.ID] = struct{}{}
+	}
+	for _, id := range ids {
+		if _, ok := owned[id]; !ok {
+			return fmt.Errorf("bank card %s for update not found: %w", id, ErrBankCardNotFound)
+		}
+	}
+	return nil
+}
+
+// checkPlan ensures that the item limit of the user's plan still allows the bank cards being added.
+func (s *Service) checkPlan(ctx context.Context, userID uuid.UUID, added int) error {
+	if s.plans == nil {
+		return nil
+	}
+	return s.plans.CheckItems(ctx, userID, added)
+}
+
+// checkPolicy ensures that the item policies of the user allow storing bank cards.
+func (s *Service) checkPolicy(ctx context.Context, userID uuid.UUID) error {
+	if s.policies == nil {
+		return nil
+	}
+	return s.policies.CheckItem(ctx, userID, authz.KindBankCard)
+}
+
+// authorize asks the authorization decision point whether the user may perform the action on a bank card of the owner.
+// The cardID is uuid.Nil for new bank cards and for the whole collection.
+func (s *Service) authorize(ctx context.Context, action string, cardID, ownerID, userID uuid.UUID) error {
+	err := s.authorizer.Authorize(ctx, authzApp.Request{
+		Action:     action,
+		Kind:       authz.KindBankCard,
+		ResourceID: cardID,
+		OwnerID:    ownerID,
+		SubjectID:  userID,
+	})
+	if err != nil {
+		return fmt.Errorf("access denied to bank card %s: %w: %w", cardID, ErrBankCardAccessDenied, err)
 	}
 	return nil
 }
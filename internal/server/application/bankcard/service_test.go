@@ -6,8 +6,13 @@ import (
 	"testing"
 	"time"
 
+	authzApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/authz"
+	itempolicyApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/itempolicy"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/authz"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/bankcard"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/itemtag"
 	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/bankcard"
+	tagRepository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/itemtag"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -15,8 +20,9 @@ import (
 
 // Mock repository for testing.
 type mockRepository struct {
-	saveFunc func(ctx context.Context, params repository.SaveParams) error
-	loadFunc func(ctx context.Context, params repository.LoadParams) ([]*bankcard.BankCard, error)
+	saveFunc      func(ctx context.Context, params repository.SaveParams) error
+	saveBatchFunc func(ctx context.Context, params repository.SaveBatchParams) error
+	loadFunc      func(ctx context.Context, params repository.LoadParams) ([]*bankcard.BankCard, error)
 }
 
 func (m *mockRepository) Save(ctx context.Context, params repository.SaveParams) error {
@@ -26,6 +32,13 @@ func (m *mockRepository) Save(ctx context.Context, params repository.SaveParams)
 	return nil
 }
 
+func (m *mockRepository) SaveBatch(ctx context.Context, params repository.SaveBatchParams) error {
+	if m.saveBatchFunc != nil {
+		return m.saveBatchFunc(ctx, params)
+	}
+	return nil
+}
+
 func (m *mockRepository) Load(
 	ctx context.Context,
 	params repository.LoadParams,
@@ -36,11 +49,42 @@ func (m *mockRepository) Load(
 	return nil, nil
 }
 
+// ownerAuthorizer permits users to act on their own bank cards only, like the default authorization policy.
+type ownerAuthorizer struct{}
+
+func (ownerAuthorizer) Authorize(_ context.Context, req authzApp.Request) error {
+	if req.OwnerID != req.SubjectID {
+		return authzApp.ErrAccessDenied
+	}
+	return nil
+}
+
+// mockPolicyEnforcer implements PolicyEnforcer for testing, recording the checked kinds.
+type mockPolicyEnforcer struct {
+	err   error
+	kinds []string
+}
+
+func (m *mockPolicyEnforcer) CheckItem(_ context.Context, _ uuid.UUID, kind string) error {
+	m.kinds = append(m.kinds, kind)
+	return m.err
+}
+
+// denyAuthorizer denies every request and records the requests decided.
+type denyAuthorizer struct {
+	reqs []authzApp.Request
+}
+
+func (m *denyAuthorizer) Authorize(_ context.Context, req authzApp.Request) error {
+	m.reqs = append(m.reqs, req)
+	return authzApp.ErrAccessDenied
+}
+
 func TestNewService(t *testing.T) {
 	t.Parallel()
 
 	repo := &mockRepository{}
-	service := NewService(repo)
+	service := NewService(repo, ownerAuthorizer{}, nil, nil, nil, nil)
 
 	require.NotNil(t, service)
 	assert.Equal(t, repo, service.r)
@@ -136,7 +180,7 @@ func TestService_Pull(t *testing.T) {
 				tt.setupMock(repo)
 			}
 
-			service := NewService(repo)
+			service := NewService(repo, ownerAuthorizer{}, nil, nil, nil, nil)
 			card, err := service.Pull(context.Background(), tt.args.params)
 
 			if tt.wantErr {
@@ -258,7 +302,7 @@ func TestService_List(t *testing.T) {
 				tt.setupMock(repo)
 			}
 
-			service := NewService(repo)
+			service := NewService(repo, ownerAuthorizer{}, nil, nil, nil, nil)
 			cards, err := service.List(context.Background(), tt.args.params)
 
 			if tt.wantErr {
@@ -417,7 +461,7 @@ func TestService_Push(t *testing.T) {
 				tt.setupMock(repo)
 			}
 
-			service := NewService(repo)
+			service := NewService(repo, ownerAuthorizer{}, nil, nil, nil, nil)
 			cardID, err := service.Push(context.Background(), tt.args.params)
 
 			if tt.wantErr {
@@ -520,7 +564,7 @@ func TestService_checkAccessToUpdate(t *testing.T) {
 				tt.setupMock(repo)
 			}
 
-			service := NewService(repo)
+			service := NewService(repo, ownerAuthorizer{}, nil, nil, nil, nil)
 			err := service.checkAccessToUpdate(context.Background(), tt.cardID, tt.userID)
 
 			if tt.wantErr {
@@ -534,3 +578,471 @@ func TestService_checkAccessToUpdate(t *testing.T) {
 		})
 	}
 }
+
+func TestService_Recover(t *testing.T) {
+	t.Parallel()
+
+	testID := uuid.New()
+	testUserID := uuid.New()
+	asOf := time.Now().Add(-time.Hour)
+
+	version := &bankcard.BankCard{
+		ID:          testID,
+		UserID:      testUserID,
+		CardNumber:  []byte("4532015112830366"),
+		CardHolder:  []byte("John Doe"),
+		ExpiryMonth: []byte("12"),
+		ExpiryYear:  []byte("2030"),
+		CVV:         []byte("123"),
+		Description: []byte("previous description"),
+		UpdatedAt:   asOf.Add(-time.Minute),
+	}
+
+	tests := []struct {
+		loadErr     error
+		saveErr     error
+		name        string
+		wantErrText string
+		versions    []*bankcard.BankCard
+		wantErr     bool
+	}{
+		{
+			name:     "success/version_saved_as_latest",
+			versions: []*bankcard.BankCard{version},
+		},
+		{
+			name:        "error/version_not_found",
+			versions:    []*bankcard.BankCard{},
+			wantErr:     true,
+			wantErrText: "bank card version not found",
+		},
+		{
+			name:        "error/load_failed",
+			loadErr:     errors.New("database error"),
+			wantErr:     true,
+			wantErrText: "failed to load bank card version",
+		},
+		{
+			name:        "error/save_failed",
+			versions:    []*bankcard.BankCard{version},
+			saveErr:     errors.New("database error"),
+			wantErr:     true,
+			wantErrText: "failed to save recovered bank card",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var saved *bankcard.BankCard
+			repo := &mockRepository{
+				loadFunc: func(_ context.Context, params repository.LoadParams) ([]*bankcard.BankCard, error) {
+					assert.Equal(t, asOf, params.AsOf)
+					assert.Equal(t, testID, params.ID)
+					assert.Equal(t, testUserID, params.UserID)
+					if tt.loadErr != nil {
+						return nil, tt.loadErr
+					}
+					cloned := make([]*bankcard.BankCard, 0, len(tt.versions))
+					for _, v := range tt.versions {
+						c := *v
+						cloned = append(cloned, &c)
+					}
+					return cloned, nil
+				},
+				saveFunc: func(_ context.Context, params repository.SaveParams) error {
+					saved = params.Entity
+					return tt.saveErr
+				},
+			}
+
+			got, err := NewService(repo, ownerAuthorizer{}, nil, nil, nil, nil).Recover(context.Background(), RecoverParams{
+				AsOf:   asOf,
+				ID:     testID,
+				UserID: testUserID,
+			})
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErrText)
+				assert.Nil(t, got)
+				return
+			}
+			require.NoError(t, err)
+			require.NotNil(t, saved)
+			assert.Equal(t, testID, got.ID)
+			assert.True(t, saved.UpdatedAt.After(version.UpdatedAt))
+			assert.Equal(t, saved.UpdatedAt, got.UpdatedAt)
+		})
+	}
+}
+
+func TestService_PushBatch(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	existingID := uuid.New()
+	saveErr := errors.New("save failed")
+	card := func(id uuid.UUID) *PushParams {
+		return &PushParams{
+			ID:          id,
+			CardNumber:  "4532015112830366",
+			CardHolder:  "John Doe",
+			ExpiryMonth: "12",
+			ExpiryYear:  "2030",
+			CVV:         "123",
+		}
+	}
+
+	tests := []struct {
+		saveErr       error
+		wantErrIs     error
+		name          string
+		items         []*PushParams
+		existing      []*bankcard.BankCard
+		wantLoadIDs   []uuid.UUID
+		wantSaved     int
+		wantErr       bool
+		wantSaveCalls int
+	}{
+		{
+			name:          "creates and updates with last item winning",
+			items:         []*PushParams{card(uuid.Nil), card(existingID), card(existingID)},
+			existing:      []*bankcard.BankCard{{ID: existingID, UserID: userID}},
+			wantLoadIDs:   []uuid.UUID{existingID, existingID},
+			wantSaved:     2,
+			wantSaveCalls: 1,
+		},
+		{
+			name:      "update of missing item",
+			items:     []*PushParams{card(existingID)},
+			wantErr:   true,
+			wantErrIs: ErrBankCardNotFound,
+		},
+		{
+			name:      "update of item of another user",
+			items:     []*PushParams{card(existingID)},
+			existing:  []*bankcard.BankCard{{ID: existingID, UserID: uuid.New()}},
+			wantErr:   true,
+			wantErrIs: ErrBankCardAccessDenied,
+		},
+		{
+			name:    "invalid item",
+			items:   []*PushParams{{CardNumber: "invalid"}},
+			wantErr: true,
+		},
+		{
+			name:          "save error",
+			items:         []*PushParams{card(uuid.Nil)},
+			saveErr:       saveErr,
+			wantSaveCalls: 1,
+			wantSaved:     1,
+			wantErr:       true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			saveCalls := 0
+			repo := &mockRepository{
+				loadFunc: func(_ context.Context, params repository.LoadParams) ([]*bankcard.BankCard, error) {
+					assert.Equal(t, userID, params.UserID)
+					if tt.wantLoadIDs != nil {
+						assert.Equal(t, tt.wantLoadIDs, params.IDs)
+					}
+					return tt.existing, nil
+				},
+				saveBatchFunc: func(_ context.Context, params repository.SaveBatchParams) error {
+					saveCalls++
+					assert.Equal(t, userID, params.UserID)
+					assert.Len(t, params.Entities, tt.wantSaved)
+					for _, e := range params.Entities {
+						assert.Equal(t, userID, e.UserID)
+					}
+					return tt.saveErr
+				},
+			}
+
+			ids, err := NewService(repo, ownerAuthorizer{}, nil, nil, nil, nil).PushBatch(
+				context.Background(),
+				PushBatchParams{Items: tt.items, UserID: userID},
+			)
+
+			assert.Equal(t, tt.wantSaveCalls, saveCalls)
+			if tt.wantErr {
+				require.Error(t, err)
+				if tt.wantErrIs != nil {
+					require.ErrorIs(t, err, tt.wantErrIs)
+				}
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, ids, len(tt.items))
+			for i, item := range tt.items {
+				if item.ID != uuid.Nil {
+					assert.Equal(t, item.ID, ids[i])
+				} else {
+					assert.NotEqual(t, uuid.Nil, ids[i])
+				}
+			}
+		})
+	}
+}
+
+func TestService_ItemPolicies(t *testing.T) {
+	t.Parallel()
+
+	userID, existingID := uuid.New(), uuid.New()
+	repo := &mockRepository{
+		loadFunc: func(context.Context, repository.LoadParams) ([]*bankcard.BankCard, error) {
+			return []*bankcard.BankCard{{ID: existingID, UserID: userID}}, nil
+		},
+	}
+	card := func(id uuid.UUID) *PushParams {
+		return &PushParams{
+			ID:          id,
+			UserID:      userID,
+			CardNumber:  "4532015112830366",
+			CardHolder:  "John Doe",
+			ExpiryMonth: "12",
+			ExpiryYear:  "2030",
+			CVV:         "123",
+		}
+	}
+
+	tests := []struct {
+		call      func(s *Service) error
+		policyErr error
+		name      string
+		wantKinds []string
+	}{
+		{
+			name: "create allowed",
+			call: func(s *Service) error {
+				_, err := s.Push(context.Background(), card(uuid.Nil))
+				return err
+			},
+			wantKinds: []string{authz.KindBankCard},
+		},
+		{
+			name: "update forbidden",
+			call: func(s *Service) error {
+				_, err := s.Push(context.Background(), card(existingID))
+				return err
+			},
+			policyErr: itempolicyApp.ErrItemKindForbidden,
+			wantKinds: []string{authz.KindBankCard},
+		},
+		{
+			name: "batch checked once",
+			call: func(s *Service) error {
+				_, err := s.PushBatch(context.Background(), PushBatchParams{
+					Items:  []*PushParams{card(uuid.Nil), card(existingID)},
+					UserID: userID,
+				})
+				return err
+			},
+			policyErr: itempolicyApp.ErrItemKindForbidden,
+			wantKinds: []string{authz.KindBankCard},
+		},
+		{
+			name: "empty batch not checked",
+			call: func(s *Service) error {
+				_, err := s.PushBatch(context.Background(), PushBatchParams{UserID: userID})
+				return err
+			},
+			policyErr: itempolicyApp.ErrItemKindForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			policies := &mockPolicyEnforcer{err: tt.policyErr}
+
+			err := tt.call(NewService(repo, ownerAuthorizer{}, nil, policies, nil, nil))
+
+			if tt.policyErr != nil && len(tt.wantKinds) != 0 {
+				require.ErrorIs(t, err, tt.policyErr)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantKinds, policies.kinds)
+		})
+	}
+}
+
+func TestService_Authorization(t *testing.T) {
+	t.Parallel()
+
+	userID, itemID := uuid.New(), uuid.New()
+	request := func(action string, resourceID uuid.UUID) authzApp.Request {
+		return authzApp.Request{
+			Action:     action,
+			Kind:       authz.KindBankCard,
+			ResourceID: resourceID,
+			OwnerID:    userID,
+			SubjectID:  userID,
+		}
+	}
+
+	tests := []struct {
+		call    func(s *Service) error
+		name    string
+		wantReq authzApp.Request
+	}{
+		{
+			name: "pull",
+			call: func(s *Service) error {
+				_, err := s.Pull(context.Background(), PullParams{ID: itemID, UserID: userID})
+				return err
+			},
+			wantReq: request(authz.ActionRead, itemID),
+		},
+		{
+			name: "list",
+			call: func(s *Service) error {
+				_, err := s.List(context.Background(), ListParams{UserID: userID})
+				return err
+			},
+			wantReq: request(authz.ActionRead, uuid.Nil),
+		},
+		{
+			name: "create",
+			call: func(s *Service) error {
+				_, err := s.Push(context.Background(), &PushParams{
+					CardNumber:  "4532015112830366",
+					CardHolder:  "John Doe",
+					ExpiryMonth: "12",
+					ExpiryYear:  "2099",
+					CVV:         "123",
+					UserID:      userID,
+				})
+				return err
+			},
+			wantReq: request(authz.ActionWrite, uuid.Nil),
+		},
+		{
+			name: "update",
+			call: func(s *Service) error {
+				_, err := s.Push(context.Background(), &PushParams{
+					CardNumber:  "4532015112830366",
+					CardHolder:  "John Doe",
+					ExpiryMonth: "12",
+					ExpiryYear:  "2099",
+					CVV:         "123",
+					ID:          itemID,
+					UserID:      userID,
+				})
+				return err
+			},
+			wantReq: request(authz.ActionWrite, itemID),
+		},
+		{
+			name: "recover",
+			call: func(s *Service) error {
+				_, err := s.Recover(context.Background(), RecoverParams{AsOf: time.Now(), ID: itemID, UserID: userID})
+				return err
+			},
+			wantReq: request(authz.ActionWrite, itemID),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &mockRepository{
+				loadFunc: func(context.Context, repository.LoadParams) ([]*bankcard.BankCard, error) {
+					return []*bankcard.BankCard{{ID: itemID, UserID: userID}}, nil
+				},
+			}
+			authorizer := &denyAuthorizer{}
+
+			err := tt.call(NewService(repo, authorizer, nil, nil, nil, nil))
+
+			require.ErrorIs(t, err, ErrBankCardAccessDenied)
+			require.ErrorIs(t, err, authzApp.ErrAccessDenied)
+			assert.Equal(t, []authzApp.Request{tt.wantReq}, authorizer.reqs)
+		})
+	}
+}
+
+// mockTagRepository implements TagRepository for testing.
+type mockTagRepository struct {
+	err  error
+	tags []*itemtag.ItemTags
+}
+
+func (m *mockTagRepository) Load(_ context.Context, params tagRepository.LoadParams) ([]*itemtag.ItemTags, error) {
+	if params.Tag != itemtag.Archived {
+		return nil, errors.New("unexpected tag")
+	}
+	return m.tags, m.err
+}
+
+func TestService_ListArchived(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	activeID, archivedID := uuid.New(), uuid.New()
+	archived := []*itemtag.ItemTags{{ItemID: archivedID, UserID: userID, Tags: []string{itemtag.Archived}}}
+
+	tests := []struct {
+		tags    TagRepository
+		wantErr error
+		want    map[uuid.UUID]bool
+		name    string
+		exclude bool
+	}{
+		{
+			name: "archived items marked",
+			tags: &mockTagRepository{tags: archived},
+			want: map[uuid.UUID]bool{activeID: false, archivedID: true},
+		},
+		{
+			name:    "archived items excluded",
+			tags:    &mockTagRepository{tags: archived},
+			exclude: true,
+			want:    map[uuid.UUID]bool{activeID: false},
+		},
+		{
+			name:    "nothing archived without tags",
+			exclude: true,
+			want:    map[uuid.UUID]bool{activeID: false, archivedID: false},
+		},
+		{
+			name:    "tag lookup error",
+			tags:    &mockTagRepository{err: errors.New("connection refused")},
+			wantErr: ErrBankCardTechError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &mockRepository{loadFunc: func(context.Context, repository.LoadParams) ([]*bankcard.BankCard, error) {
+				return []*bankcard.BankCard{{ID: activeID, UserID: userID}, {ID: archivedID, UserID: userID}}, nil
+			}}
+			service := NewService(repo, ownerAuthorizer{}, nil, nil, tt.tags, nil)
+
+			got, err := service.List(context.Background(), ListParams{UserID: userID, ExcludeArchived: tt.exclude})
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			listed := make(map[uuid.UUID]bool, len(got))
+			for _, item := range got {
+				listed[item.ID] = item.Archived
+			}
+			assert.Equal(t, tt.want, listed)
+		})
+	}
+}
@@ -0,0 +1,7 @@
+// Package billing provides billing integration application services for the AegisVaultKeeper server.
+//
+// This package receives the subscription webhooks of Stripe, verifies their signatures with the signing
+// secret of the endpoint and applies the subscriptions to the plans of users: the plan comes from the price
+// the customer pays, and the user from the user_id metadata of the subscription. Events are applied once
+// and in order, however often and in whatever order Stripe delivers them.
+package billing
@@ -0,0 +1,35 @@
+package billing
+
+// Reasons of webhook events received without changing a plan.
+const (
+	// ReasonUnhandledType reports an event type other than the subscription events.
+	ReasonUnhandledType = "unhandled event type"
+	// ReasonDuplicate reports an event already applied or older than the applied ones.
+	ReasonDuplicate = "duplicate or stale event"
+	// ReasonUserMissing reports a subscription without a valid user_id metadata entry.
+	ReasonUserMissing = "subscription has no valid user_id metadata"
+	// ReasonUserUnknown reports a subscription of a user that does not exist.
+	ReasonUserUnknown = "subscribed user not found"
+	// ReasonPriceUnmapped reports a subscription to prices none of which grants a plan.
+	ReasonPriceUnmapped = "no subscribed price is mapped to a plan"
+)
+
+// StripeWebhookParams contains parameters for receiving a Stripe webhook.
+type StripeWebhookParams struct {
+	// Signature contains the Stripe-Signature header of the request.
+	Signature string
+	// Payload contains the raw request body the signature is made over.
+	Payload []byte
+}
+
+// WebhookResult represents the outcome of a received webhook event.
+type WebhookResult struct {
+	// EventID identifies the event.
+	EventID string
+	// Type contains the event type.
+	Type string
+	// Reason explains why the event did not change a plan; empty when it did.
+	Reason string
+	// Applied reports whether the event changed the subscription of a user.
+	Applied bool
+}
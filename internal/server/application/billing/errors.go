@@ -0,0 +1,18 @@
+package billing
+
+import "errors"
+
+// Billing error definitions.
+var (
+	// ErrBillingDisabled indicates that no billing integration is configured.
+	ErrBillingDisabled = errors.New("billing disabled")
+
+	// ErrBillingSignatureInvalid indicates that the webhook signature is missing, malformed, expired or wrong.
+	ErrBillingSignatureInvalid = errors.New("billing webhook signature invalid")
+
+	// ErrBillingEventInvalid indicates that the webhook payload is not a valid event.
+	ErrBillingEventInvalid = errors.New("billing webhook event invalid")
+
+	// ErrBillingTechError indicates a technical error in the billing system.
+	ErrBillingTechError = errors.New("billing technical error")
+)
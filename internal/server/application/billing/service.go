@@ -0,0 +1,108 @@
+package billing
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/plan"
+	domain "github.com/gdyunin/aegis-vault-keeper/internal/server/domain/plan"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/stripe"
+	"github.com/google/uuid"
+)
+
+// metadataUserID is the subscription metadata key holding the ID of the subscribed user.
+const metadataUserID = "user_id"
+
+// PlanSyncer defines the interface for applying subscription events to the plans of users.
+type PlanSyncer interface {
+	// Sync applies a subscription event and reports whether it changed the stored subscription.
+	Sync(ctx context.Context, params plan.SyncParams) (bool, error)
+}
+
+// Service provides billing integration operations.
+type Service struct {
+	// plans applies subscriptions to the plans of users.
+	plans PlanSyncer
+	// prices maps Stripe price IDs to the names of the plans they grant.
+	prices map[string]string
+	// now returns the current time.
+	now func() time.Time
+	// secret is the signing secret of the Stripe webhook endpoint; empty disables the integration.
+	secret string
+	// tolerance is the accepted age of webhook signatures.
+	tolerance time.Duration
+}
+
+// NewService creates a new billing service verifying Stripe webhooks with the signing secret and granting the
+// plans the prices map to. An empty secret disables the integration.
+func NewService(plans PlanSyncer, secret string, prices map[string]string, tolerance time.Duration) *Service {
+	return &Service{plans: plans, prices: prices, now: time.Now, secret: secret, tolerance: tolerance}
+}
+
+// HandleStripeWebhook verifies and applies a Stripe webhook event. Events that cannot change a plan, such as
+// other event types or subscriptions of unknown users, are received with a reason instead of an error, so
+// Stripe does not retry them; technical errors are returned, so it does.
+func (s *Service) HandleStripeWebhook(ctx context.Context, params StripeWebhookParams) (*WebhookResult, error) {
+	if s.secret == "" {
+		return nil, ErrBillingDisabled
+	}
+	event, err := stripe.ConstructEvent(params.Payload, params.Signature, s.secret, s.tolerance, s.now())
+	switch {
+	case errors.Is(err, stripe.ErrSignatureInvalid):
+		return nil, errors.Join(ErrBillingSignatureInvalid, err)
+	case err != nil:
+		return nil, errors.Join(ErrBillingEventInvalid, err)
+	}
+
+	result := &WebhookResult{EventID: event.ID, Type: event.Type}
+	if event.Subscription == nil {
+		result.Reason = ReasonUnhandledType
+		return result, nil
+	}
+	sub := event.Subscription
+	userID, err := uuid.Parse(sub.Metadata[metadataUserID])
+	if err != nil {
+		result.Reason = ReasonUserMissing
+		return result, nil
+	}
+	status := sub.Status
+	if event.Type == stripe.EventSubscriptionDeleted {
+		status = domain.StatusCanceled
+	}
+	planName := s.planOf(sub.PriceIDs)
+	if planName == "" && (&domain.Subscription{Status: status}).Grants() {
+		result.Reason = ReasonPriceUnmapped
+		return result, nil
+	}
+
+	result.Applied, err = s.plans.Sync(ctx, plan.SyncParams{
+		EventAt:        event.Created,
+		PeriodEnd:      sub.CurrentPeriodEnd,
+		EventID:        event.ID,
+		Plan:           planName,
+		Status:         status,
+		CustomerID:     sub.CustomerID,
+		SubscriptionID: sub.ID,
+		UserID:         userID,
+	})
+	switch {
+	case errors.Is(err, plan.ErrPlanUserNotFound):
+		result.Reason = ReasonUserUnknown
+	case err != nil:
+		return nil, errors.Join(ErrBillingTechError, err)
+	case !result.Applied:
+		result.Reason = ReasonDuplicate
+	}
+	return result, nil
+}
+
+// planOf returns the plan granted by the first mapped price, or an empty name when none is mapped.
+func (s *Service) planOf(priceIDs []string) string {
+	for _, id := range priceIDs {
+		if name, ok := s.prices[id]; ok {
+			return name
+		}
+	}
+	return ""
+}
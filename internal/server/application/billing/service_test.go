@@ -0,0 +1,208 @@
+package billing
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/plan"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/crypto"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testSecret is the endpoint signing secret of the tests.
+const testSecret = "whsec_test"
+
+// mockPlanSyncer implements PlanSyncer for testing.
+type mockPlanSyncer struct {
+	err     error
+	params  []plan.SyncParams
+	applied bool
+}
+
+func (m *mockPlanSyncer) Sync(_ context.Context, params plan.SyncParams) (bool, error) {
+	m.params = append(m.params, params)
+	return m.applied, m.err
+}
+
+// subscriptionEvent returns an event of the type about a subscription of the user to the price.
+func subscriptionEvent(eventType, status, userID, price string) string {
+	return fmt.Sprintf(`{
+		"id": "evt_1", "object": "event", "type": %q, "created": 1791979200,
+		"data": {"object": {
+			"id": "sub_1", "object": "subscription", "customer": "cus_1", "status": %q,
+			"metadata": {"user_id": %q},
+			"items": {"data": [{"price": {"id": %q}, "current_period_end": 1794657600}]}
+		}}
+	}`, eventType, status, userID, price)
+}
+
+// sign returns a Stripe-Signature header signing the payload at the time with the secret.
+func sign(payload, secret string, at time.Time) string {
+	timestamp := strconv.FormatInt(at.Unix(), 10)
+	mac := crypto.SignHMACSHA256([]byte(secret), []byte(timestamp+"."+payload))
+	return "t=" + timestamp + ",v1=" + hex.EncodeToString(mac)
+}
+
+func TestService_HandleStripeWebhook(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1791979260, 0)
+	userID := uuid.New()
+	prices := map[string]string{"price_premium": "premium"}
+	updated := subscriptionEvent("customer.subscription.updated", "active", userID.String(), "price_premium")
+
+	tests := []struct {
+		syncer      *mockPlanSyncer
+		wantErr     error
+		wantSync    *plan.SyncParams
+		name        string
+		secret      string
+		payload     string
+		signature   string
+		wantReason  string
+		wantApplied bool
+	}{
+		{
+			name:        "subscription updated",
+			syncer:      &mockPlanSyncer{applied: true},
+			secret:      testSecret,
+			payload:     updated,
+			signature:   sign(updated, testSecret, now),
+			wantApplied: true,
+			wantSync: &plan.SyncParams{
+				EventAt:        time.Unix(1791979200, 0).UTC(),
+				PeriodEnd:      time.Unix(1794657600, 0).UTC(),
+				EventID:        "evt_1",
+				Plan:           "premium",
+				Status:         "active",
+				CustomerID:     "cus_1",
+				SubscriptionID: "sub_1",
+				UserID:         userID,
+			},
+		},
+		{
+			name:   "subscription deleted",
+			syncer: &mockPlanSyncer{applied: true},
+			secret: testSecret,
+			payload: subscriptionEvent(
+				"customer.subscription.deleted", "past_due", userID.String(), "price_legacy",
+			),
+			wantApplied: true,
+			wantSync: &plan.SyncParams{
+				EventAt:        time.Unix(1791979200, 0).UTC(),
+				PeriodEnd:      time.Unix(1794657600, 0).UTC(),
+				EventID:        "evt_1",
+				Status:         "canceled",
+				CustomerID:     "cus_1",
+				SubscriptionID: "sub_1",
+				UserID:         userID,
+			},
+		},
+		{
+			name:       "duplicate event",
+			syncer:     &mockPlanSyncer{},
+			secret:     testSecret,
+			payload:    updated,
+			wantReason: ReasonDuplicate,
+			wantSync:   &plan.SyncParams{},
+		},
+		{
+			name:       "unknown user",
+			syncer:     &mockPlanSyncer{err: fmt.Errorf("user: %w", plan.ErrPlanUserNotFound)},
+			secret:     testSecret,
+			payload:    updated,
+			wantReason: ReasonUserUnknown,
+			wantSync:   &plan.SyncParams{},
+		},
+		{
+			name:       "other event type",
+			syncer:     &mockPlanSyncer{},
+			secret:     testSecret,
+			payload:    `{"id": "evt_2", "object": "event", "type": "invoice.paid", "created": 1791979200}`,
+			wantReason: ReasonUnhandledType,
+		},
+		{
+			name:       "missing user metadata",
+			syncer:     &mockPlanSyncer{},
+			secret:     testSecret,
+			payload:    subscriptionEvent("customer.subscription.created", "active", "", "price_premium"),
+			wantReason: ReasonUserMissing,
+		},
+		{
+			name:       "unmapped price",
+			syncer:     &mockPlanSyncer{},
+			secret:     testSecret,
+			payload:    subscriptionEvent("customer.subscription.created", "active", userID.String(), "price_x"),
+			wantReason: ReasonPriceUnmapped,
+		},
+		{
+			name:      "wrong signature",
+			syncer:    &mockPlanSyncer{},
+			secret:    testSecret,
+			payload:   updated,
+			signature: sign(updated, "whsec_other", now),
+			wantErr:   ErrBillingSignatureInvalid,
+		},
+		{
+			name:    "invalid event",
+			syncer:  &mockPlanSyncer{},
+			secret:  testSecret,
+			payload: `{"id": "evt_3"`,
+			wantErr: ErrBillingEventInvalid,
+		},
+		{
+			name:    "sync failure",
+			syncer:  &mockPlanSyncer{err: errors.Join(plan.ErrPlanTechError, errors.New("database unavailable"))},
+			secret:  testSecret,
+			payload: updated,
+			wantErr: ErrBillingTechError,
+		},
+		{
+			name:    "disabled",
+			syncer:  &mockPlanSyncer{},
+			payload: updated,
+			wantErr: ErrBillingDisabled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := NewService(tt.syncer, tt.secret, prices, 0)
+			s.now = func() time.Time { return now }
+			signature := tt.signature
+			if signature == "" {
+				signature = sign(tt.payload, testSecret, now)
+			}
+
+			got, err := s.HandleStripeWebhook(context.Background(), StripeWebhookParams{
+				Signature: signature,
+				Payload:   []byte(tt.payload),
+			})
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantApplied, got.Applied)
+			assert.Equal(t, tt.wantReason, got.Reason)
+			if tt.wantSync == nil {
+				assert.Empty(t, tt.syncer.params)
+				return
+			}
+			require.Len(t, tt.syncer.params, 1)
+			if tt.wantSync.EventID != "" {
+				assert.Equal(t, *tt.wantSync, tt.syncer.params[0])
+			}
+		})
+	}
+}
@@ -0,0 +1,6 @@
+// Package botcheck provides the human verification application services for the AegisVaultKeeper server.
+//
+// This package decides when a client must solve a challenge: always to register, and to log in once its
+// network failed to log in too often. Challenges are CAPTCHA widgets verified with their provider or
+// proof-of-work puzzles issued and verified by the server.
+package botcheck
@@ -0,0 +1,63 @@
+package botcheck
+
+import (
+	"net/netip"
+	"time"
+)
+
+// Actions challenges can be required for.
+const (
+	// ActionRegister protects registrations; it always requires a challenge.
+	ActionRegister = "register"
+	// ActionLogin protects logins; it requires a challenge after repeated failures of the client network.
+	ActionLogin = "login"
+)
+
+// Challenge providers.
+const (
+	// ProviderHCaptcha verifies hCaptcha widget responses.
+	ProviderHCaptcha = "hcaptcha"
+	// ProviderTurnstile verifies Cloudflare Turnstile widget responses.
+	ProviderTurnstile = "turnstile"
+	// ProviderPoW issues and verifies proof-of-work puzzles.
+	ProviderPoW = "pow"
+)
+
+// Settings contains the human verification configuration.
+type Settings struct {
+	// Provider names the challenge provider; empty disables challenges.
+	Provider string
+	// SiteKey contains the public site key of the CAPTCHA widget.
+	SiteKey string
+	// LoginFailures is the number of failed logins within LoginWindow after which logins from the client network
+	// require a challenge (0 never requires one).
+	LoginFailures int
+	// LoginWindow is the period failed logins are counted over.
+	LoginWindow time.Duration
+}
+
+// Challenge describes the challenge clients must solve.
+type Challenge struct {
+	// ExpiresAt indicates when solutions of the puzzle stop being accepted; zero for CAPTCHA providers.
+	ExpiresAt time.Time
+	// Provider names the challenge provider.
+	Provider string
+	// SiteKey contains the public site key the CAPTCHA widget is rendered with; empty for proof-of-work.
+	SiteKey string
+	// Puzzle contains the proof-of-work puzzle; empty for CAPTCHA providers.
+	Puzzle string
+	// Difficulty is the number of leading zero bits the solution hash must have; zero for CAPTCHA providers.
+	Difficulty int
+	// LoginRequired reports whether logins from the client network currently require a challenge.
+	LoginRequired bool
+}
+
+// CheckParams contains parameters for checking whether a request may proceed.
+type CheckParams struct {
+	// IP contains the client address.
+	IP netip.Addr
+	// Action names the protected action.
+	Action string
+	// Response contains the challenge response sent by the client.
+	Response string
+}
@@ -0,0 +1,46 @@
+package botcheck
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/errutil"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/captcha"
+)
+
+// Human verification error definitions.
+var (
+	// ErrBotCheckTechError indicates a technical error in the human verification system.
+	ErrBotCheckTechError = errors.New("human verification technical error")
+
+	// ErrChallengesDisabled indicates that no challenge provider is configured.
+	ErrChallengesDisabled = errors.New("challenges are disabled")
+
+	// ErrChallengeRequired indicates that the request must carry a challenge response.
+	ErrChallengeRequired = errors.New("challenge response required")
+
+	// ErrChallengeFailed indicates that the challenge response is invalid, expired or already used.
+	ErrChallengeFailed = errors.New("challenge response invalid")
+)
+
+// mapError maps verifier errors to application-level errors.
+func mapError(err error) error {
+	if err == nil {
+		return nil
+	}
+	mapped := errutil.MapError(mapFn, err)
+	if mapped != nil {
+		return fmt.Errorf("human verification error mapping failed: %w", mapped)
+	}
+	return nil
+}
+
+// mapFn provides the actual error mapping logic for different error types.
+func mapFn(err error) error {
+	switch {
+	case errors.Is(err, captcha.ErrRejected):
+		return ErrChallengeFailed
+	default:
+		return errors.Join(ErrBotCheckTechError, err)
+	}
+}
@@ -0,0 +1,153 @@
+package botcheck
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"sync"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/captcha"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/clientinfo"
+)
+
+// maxTrackedNetworks bounds the number of client networks whose failed logins are counted at once, so a flood of
+// distinct addresses cannot exhaust the server memory; failures of further networks are not counted.
+const maxTrackedNetworks = 100_000
+
+// Verifier defines the interface for verifying challenge responses.
+type Verifier interface {
+	// Verify checks the challenge response of the client.
+	Verify(ctx context.Context, response string, ip netip.Addr) error
+}
+
+// PuzzleIssuer defines the interface for issuing proof-of-work puzzles.
+type PuzzleIssuer interface {
+	// Issue creates a new puzzle.
+	Issue() (captcha.Puzzle, error)
+}
+
+// failureWindow counts the failed logins of a client network.
+type failureWindow struct {
+	// start is when the current counting window began.
+	start time.Time
+	// count is the number of failed logins within the window.
+	count int
+}
+
+// Service decides when clients must solve a challenge and verifies their responses.
+type Service struct {
+	// verifier checks challenge responses; nil disables challenges.
+	verifier Verifier
+	// puzzles issues proof-of-work puzzles; nil for CAPTCHA providers.
+	puzzles PuzzleIssuer
+	// failures counts the failed logins of client networks.
+	failures map[netip.Prefix]*failureWindow
+	// now returns the current time.
+	now func() time.Time
+	// settings contains the human verification configuration.
+	settings Settings
+	// mu guards failures.
+	mu sync.Mutex
+}
+
+// NewService creates a new human verification service instance.
+// The verifier may be nil when no provider is configured, and the puzzle issuer when the provider is a CAPTCHA.
+func NewService(settings Settings, verifier Verifier, puzzles PuzzleIssuer) *Service {
+	return &Service{
+		verifier: verifier,
+		puzzles:  puzzles,
+		failures: make(map[netip.Prefix]*failureWindow),
+		now:      time.Now,
+		settings: settings,
+	}
+}
+
+// Challenge returns the challenge the client must solve, with a fresh puzzle for proof-of-work.
+func (s *Service) Challenge(_ context.Context, ip netip.Addr) (*Challenge, error) {
+	if s.verifier == nil {
+		return nil, ErrChallengesDisabled
+	}
+
+	c := &Challenge{
+		Provider:      s.settings.Provider,
+		SiteKey:       s.settings.SiteKey,
+		LoginRequired: s.loginRequired(ip),
+	}
+	if s.puzzles != nil {
+		puzzle, err := s.puzzles.Issue()
+		if err != nil {
+			return nil, fmt.Errorf("failed to issue challenge: %w", mapError(err))
+		}
+		c.Puzzle, c.Difficulty, c.ExpiresAt = puzzle.Value, puzzle.Difficulty, puzzle.ExpiresAt
+	}
+	return c, nil
+}
+
+// Check verifies the challenge response when the action requires one. Registrations always do, logins once the
+// client network failed to log in too often; nothing is required while challenges are disabled.
+func (s *Service) Check(ctx context.Context, params CheckParams) error {
+	if s.verifier == nil {
+		return nil
+	}
+	if params.Action == ActionLogin && !s.loginRequired(params.IP) {
+		return nil
+	}
+	if params.Response == "" {
+		return fmt.Errorf("%s requires a challenge: %w", params.Action, ErrChallengeRequired)
+	}
+	if err := s.verifier.Verify(ctx, params.Response, params.IP); err != nil {
+		return fmt.Errorf("failed to verify challenge response: %w", mapError(err))
+	}
+	return nil
+}
+
+// RecordLoginFailure counts a failed login of the client network.
+func (s *Service) RecordLoginFailure(_ context.Context, ip netip.Addr) {
+	if s.verifier == nil || s.settings.LoginFailures <= 0 || !ip.IsValid() {
+		return
+	}
+	now := s.now()
+	key := clientinfo.Network(ip)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	w, ok := s.failures[key]
+	if !ok {
+		if len(s.failures) >= maxTrackedNetworks {
+			s.sweep(now)
+		}
+		if len(s.failures) >= maxTrackedNetworks {
+			return
+		}
+		w = &failureWindow{start: now}
+		s.failures[key] = w
+	}
+	if now.Sub(w.start) >= s.settings.LoginWindow {
+		w.start, w.count = now, 0
+	}
+	w.count++
+}
+
+// loginRequired reports whether logins from the client network require a challenge.
+func (s *Service) loginRequired(ip netip.Addr) bool {
+	if s.settings.LoginFailures <= 0 || !ip.IsValid() {
+		return false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	w, ok := s.failures[clientinfo.Network(ip)]
+	return ok && s.now().Sub(w.start) < s.settings.LoginWindow && w.count >= s.settings.LoginFailures
+}
+
+// sweep removes the networks whose counting window has expired.
+func (s *Service) sweep(now time.Time) {
+	for key, w := range s.failures {
+		if now.Sub(w.start) >= s.settings.LoginWindow {
+			delete(s.failures, key)
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package botcheck
+
+import (
+	"context"
+	"errors"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/captcha"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockVerifier implements Verifier for testing.
+type mockVerifier struct {
+	err       error
+	responses []string
+}
+
+func (m *mockVerifier) Verify(_ context.Context, response string, _ netip.Addr) error {
+	m.responses = append(m.responses, response)
+	return m.err
+}
+
+// mockPuzzleIssuer implements PuzzleIssuer for testing.
+type mockPuzzleIssuer struct {
+	err    error
+	puzzle captcha.Puzzle
+}
+
+func (m *mockPuzzleIssuer) Issue() (captcha.Puzzle, error) {
+	return m.puzzle, m.err
+}
+
+func TestService_Check(t *testing.T) {
+	t.Parallel()
+
+	settings := Settings{Provider: ProviderTurnstile, LoginFailures: 2, LoginWindow: time.Minute}
+	client := netip.MustParseAddr("203.0.113.7")
+
+	tests := []struct {
+		verifier      *mockVerifier
+		wantErr       error
+		name          string
+		params        CheckParams
+		loginFailures int
+		wantVerified  bool
+	}{
+		{
+			name:         "registration with a valid response",
+			verifier:     &mockVerifier{},
+			params:       CheckParams{Action: ActionRegister, IP: client, Response: "token"},
+			wantVerified: true,
+		},
+		{
+			name:     "registration without a response",
+			verifier: &mockVerifier{},
+			params:   CheckParams{Action: ActionRegister, IP: client},
+			wantErr:  ErrChallengeRequired,
+		},
+		{
+			name:         "registration with a rejected response",
+			verifier:     &mockVerifier{err: captcha.ErrRejected},
+			params:       CheckParams{Action: ActionRegister, IP: client, Response: "token"},
+			wantErr:      ErrChallengeFailed,
+			wantVerified: true,
+		},
+		{
+			name:         "provider failure",
+			verifier:     &mockVerifier{err: errors.New("connection refused")},
+			params:       CheckParams{Action: ActionRegister, IP: client, Response: "token"},
+			wantErr:      ErrBotCheckTechError,
+			wantVerified: true,
+		},
+		{
+			name:          "login below the failure threshold",
+			verifier:      &mockVerifier{},
+			params:        CheckParams{Action: ActionLogin, IP: client},
+			loginFailures: 1,
+		},
+		{
+			name:          "login after repeated failures without a response",
+			verifier:      &mockVerifier{},
+			params:        CheckParams{Action: ActionLogin, IP: client},
+			loginFailures: 2,
+			wantErr:       ErrChallengeRequired,
+		},
+		{
+			name:          "login after repeated failures with a valid response",
+			verifier:      &mockVerifier{},
+			params:        CheckParams{Action: ActionLogin, IP: client, Response: "token"},
+			loginFailures: 2,
+			wantVerified:  true,
+		},
+		{
+			name:          "login from another network",
+			verifier:      &mockVerifier{},
+			params:        CheckParams{Action: ActionLogin, IP: netip.MustParseAddr("198.51.100.1")},
+			loginFailures: 5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := NewService(settings, tt.verifier, nil)
+			for range tt.loginFailures {
+				s.RecordLoginFailure(context.Background(), client)
+			}
+
+			err := s.Check(context.Background(), tt.params)
+			assert.Equal(t, tt.wantVerified, len(tt.verifier.responses) == 1)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestService_CheckDisabled(t *testing.T) {
+	t.Parallel()
+
+	s := NewService(Settings{LoginFailures: 1, LoginWindow: time.Minute}, nil, nil)
+	ip := netip.MustParseAddr("203.0.113.7")
+	s.RecordLoginFailure(context.Background(), ip)
+
+	require.NoError(t, s.Check(context.Background(), CheckParams{Action: ActionRegister, IP: ip}))
+	require.NoError(t, s.Check(context.Background(), CheckParams{Action: ActionLogin, IP: ip}))
+	_, err := s.Challenge(context.Background(), ip)
+	require.ErrorIs(t, err, ErrChallengesDisabled)
+}
+
+func TestService_LoginWindow(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	s := NewService(Settings{Provider: ProviderPoW, LoginFailures: 1, LoginWindow: time.Minute}, &mockVerifier{}, nil)
+	s.now = func() time.Time { return now }
+	ip := netip.MustParseAddr("2001:db8::1")
+
+	s.RecordLoginFailure(context.Background(), ip)
+	require.ErrorIs(t, s.Check(context.Background(), CheckParams{Action: ActionLogin, IP: ip}), ErrChallengeRequired)
+	require.ErrorIs(t,
+		s.Check(context.Background(), CheckParams{Action: ActionLogin, IP: netip.MustParseAddr("2001:db8::2")}),
+		ErrChallengeRequired, "addresses of the same /64 should share the failure count")
+
+	now = now.Add(time.Minute)
+	require.NoError(t, s.Check(context.Background(), CheckParams{Action: ActionLogin, IP: ip}),
+		"failures should expire with the window")
+}
+
+func TestService_Challenge(t *testing.T) {
+	t.Parallel()
+
+	expiresAt := time.Date(2025, 1, 1, 12, 5, 0, 0, time.UTC)
+	ip := netip.MustParseAddr("203.0.113.7")
+
+	tests := []struct {
+		puzzles  PuzzleIssuer
+		want     *Challenge
+		wantErr  error
+		name     string
+		settings Settings
+	}{
+		{
+			name:     "CAPTCHA widget",
+			settings: Settings{Provider: ProviderHCaptcha, SiteKey: "site-key"},
+			want:     &Challenge{Provider: ProviderHCaptcha, SiteKey: "site-key"},
+		},
+		{
+			name:     "proof-of-work puzzle",
+			settings: Settings{Provider: ProviderPoW},
+			puzzles:  &mockPuzzleIssuer{puzzle: captcha.Puzzle{Value: "p.t", Difficulty: 20, ExpiresAt: expiresAt}},
+			want:     &Challenge{Provider: ProviderPoW, Puzzle: "p.t", Difficulty: 20, ExpiresAt: expiresAt},
+		},
+		{
+			name:     "puzzle failure",
+			settings: Settings{Provider: ProviderPoW},
+			puzzles:  &mockPuzzleIssuer{err: errors.New("no entropy")},
+			wantErr:  ErrBotCheckTechError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := NewService(tt.settings, &mockVerifier{}, tt.puzzles).Challenge(context.Background(), ip)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,6 @@
+// Package bruteforce provides network-level brute force protection for the AegisVaultKeeper server.
+//
+// This package writes authentication failures to a line-oriented log that fail2ban and similar tools can
+// watch, and keeps an internal ban list of client networks that fail to authenticate too often, so repeated
+// offenders are rejected even where no external firewall reacts to the log.
+package bruteforce
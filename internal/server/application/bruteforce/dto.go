@@ -0,0 +1,26 @@
+package bruteforce
+
+import (
+	"net/netip"
+	"time"
+)
+
+// Policy contains the thresholds of the internal ban list.
+type Policy struct {
+	// Threshold is the number of failures within Window that bans a client (0 disables banning).
+	Threshold int
+	// Window is the period failures of a client are counted over.
+	Window time.Duration
+	// BanDuration is how long a banned client is rejected.
+	BanDuration time.Duration
+}
+
+// FailureParams contains parameters for recording an authentication failure.
+type FailureParams struct {
+	// IP contains the client address; failures of an invalid address are ignored.
+	IP netip.Addr
+	// Method contains the HTTP method of the rejected request.
+	Method string
+	// Route contains the route pattern of the rejected request.
+	Route string
+}
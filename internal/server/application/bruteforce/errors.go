@@ -0,0 +1,9 @@
+package bruteforce
+
+import "errors"
+
+// Brute force protection error definitions.
+var (
+	// ErrClientBanned indicates that the client network is banned after repeated authentication failures.
+	ErrClientBanned = errors.New("client is banned after repeated authentication failures")
+)
@@ -0,0 +1,155 @@
+package bruteforce
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/netip"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/audit"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/clientinfo"
+)
+
+// maxClients bounds the number of client networks tracked at once, so a flood of distinct addresses cannot
+// exhaust the server memory; failures of further networks are still logged.
+const maxClients = 100_000
+
+// failureLogTag prefixes every line of the failure log, so filters can tell them from other log lines.
+const failureLogTag = "aegis-vault-keeper[auth]:"
+
+// AuditRecorder defines the interface for recording security audit events.
+type AuditRecorder interface {
+	// Record stores the audit event.
+	Record(ctx context.Context, e audit.Event)
+}
+
+// client tracks the recent authentication failures of a client network.
+type client struct {
+	// windowStart is when the current failure counting window began.
+	windowStart time.Time
+	// bannedUntil is when the ban of the network ends; zero when it is not banned.
+	bannedUntil time.Time
+	// failures is the number of failures within the current window.
+	failures int
+}
+
+// Service logs authentication failures and bans client networks that fail to authenticate too often.
+type Service struct {
+	// log receives a line for every failure and ban; nil disables the failure log.
+	log io.Writer
+	// audit records bans.
+	audit AuditRecorder
+	// clients tracks the failures and bans of client networks.
+	clients map[netip.Prefix]*client
+	// now returns the current time.
+	now func() time.Time
+	// swept is when expired clients were last removed.
+	swept time.Time
+	// policy contains the ban thresholds.
+	policy Policy
+	// mu guards clients, swept and writes to log.
+	mu sync.Mutex
+}
+
+// NewService creates a new brute force protection service instance.
+// The failure log may be nil when no log sink is configured; a policy without a threshold never bans.
+func NewService(policy Policy, log io.Writer, audit AuditRecorder) *Service {
+	return &Service{
+		log:     log,
+		audit:   audit,
+		clients: make(map[netip.Prefix]*client),
+		now:     time.Now,
+		policy:  policy,
+	}
+}
+
+// Check verifies that the client is not banned. Clients with an invalid address are never banned.
+func (s *Service) Check(_ context.Context, ip netip.Addr) error {
+	if s.policy.Threshold <= 0 || !ip.IsValid() {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.clients[clientinfo.Network(ip)]
+	if !ok || !s.now().Before(c.bannedUntil) {
+		return nil
+	}
+	return fmt.Errorf("client %s banned until %s: %w", ip, c.bannedUntil.UTC().Format(time.RFC3339), ErrClientBanned)
+}
+
+// RecordFailure writes the authentication failure to the failure log and bans the client network once it
+// reaches the threshold within the window.
+func (s *Service) RecordFailure(ctx context.Context, params FailureParams) {
+	if !params.IP.IsValid() {
+		return
+	}
+	now := s.now()
+	key := clientinfo.Network(params.IP)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.write(now, "authentication failure from %s method=%s route=%s", params.IP, params.Method, params.Route)
+	if s.policy.Threshold <= 0 {
+		return
+	}
+
+	s.sweep(now)
+	c, ok := s.clients[key]
+	if !ok {
+		if len(s.clients) >= maxClients {
+			return
+		}
+		c = &client{windowStart: now}
+		s.clients[key] = c
+	}
+	if now.Before(c.bannedUntil) {
+		return
+	}
+	if now.Sub(c.windowStart) >= s.policy.Window {
+		c.windowStart, c.failures = now, 0
+	}
+	c.failures++
+	if c.failures < s.policy.Threshold {
+		return
+	}
+
+	c.bannedUntil, c.failures = now.Add(s.policy.BanDuration), 0
+	until := c.bannedUntil.UTC().Format(time.RFC3339)
+	s.write(now, "banned %s from %s until %s", key, params.IP, until)
+	s.audit.Record(ctx, audit.Event{
+		Type: audit.EventClientBanned,
+		Details: map[string]string{
+			"network":  key.String(),
+			"failures": strconv.Itoa(s.policy.Threshold),
+			"until":    until,
+		},
+	})
+}
+
+// write appends a line to the failure log. A failed write does not affect the request being served.
+func (s *Service) write(now time.Time, format string, args ...any) {
+	if s.log == nil {
+		return
+	}
+	line := fmt.Sprintf(format, args...)
+	_, _ = fmt.Fprintf(s.log, "%s %s %s\n", now.UTC().Format(time.RFC3339), failureLogTag, line)
+}
+
+// sweep removes the clients whose window and ban have both expired, at most once per window.
+func (s *Service) sweep(now time.Time) {
+	if now.Sub(s.swept) < s.policy.Window {
+		return
+	}
+	s.swept = now
+	for key, c := range s.clients {
+		if !now.Before(c.bannedUntil) && now.Sub(c.windowStart) >= s.policy.Window {
+			delete(s.clients, key)
+		}
+	}
+}
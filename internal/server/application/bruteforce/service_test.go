@@ -0,0 +1,173 @@
+package bruteforce
+
+import (
+	"bytes"
+	"context"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/audit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockAuditRecorder implements AuditRecorder for testing.
+type mockAuditRecorder struct {
+	events []audit.Event
+}
+
+func (m *mockAuditRecorder) Record(_ context.Context, e audit.Event) {
+	m.events = append(m.events, e)
+}
+
+// clock is a manually advanced time source for testing.
+type clock struct {
+	t time.Time
+}
+
+func (c *clock) now() time.Time { return c.t }
+
+func TestService_RecordFailure(t *testing.T) {
+	t.Parallel()
+
+	policy := Policy{Threshold: 3, Window: time.Minute, BanDuration: 10 * time.Minute}
+	attacker := netip.MustParseAddr("203.0.113.7")
+
+	tests := []struct {
+		name       string
+		policy     Policy
+		failures   []netip.Addr
+		gap        time.Duration
+		after      time.Duration
+		check      netip.Addr
+		wantBanned bool
+	}{
+		{
+			name:     "below the threshold",
+			policy:   policy,
+			failures: []netip.Addr{attacker, attacker},
+			check:    attacker,
+		},
+		{
+			name:       "threshold reached",
+			policy:     policy,
+			failures:   []netip.Addr{attacker, attacker, attacker},
+			check:      attacker,
+			wantBanned: true,
+		},
+		{
+			name:     "ban expires",
+			policy:   policy,
+			failures: []netip.Addr{attacker, attacker, attacker},
+			after:    10 * time.Minute,
+			check:    attacker,
+		},
+		{
+			name:     "failures spread over windows",
+			policy:   policy,
+			failures: []netip.Addr{attacker, attacker, attacker},
+			gap:      40 * time.Second,
+			check:    attacker,
+		},
+		{
+			name:     "other client unaffected",
+			policy:   policy,
+			failures: []netip.Addr{attacker, attacker, attacker},
+			check:    netip.MustParseAddr("203.0.113.8"),
+		},
+		{
+			name:   "IPv6 clients tracked by network",
+			policy: policy,
+			failures: []netip.Addr{
+				netip.MustParseAddr("2001:db8:1:2::1"),
+				netip.MustParseAddr("2001:db8:1:2::2"),
+				netip.MustParseAddr("2001:db8:1:2::3"),
+			},
+			check:      netip.MustParseAddr("2001:db8:1:2::4"),
+			wantBanned: true,
+		},
+		{
+			name:     "banning disabled",
+			policy:   Policy{},
+			failures: []netip.Addr{attacker, attacker, attacker},
+			check:    attacker,
+		},
+		{
+			name:     "invalid addresses ignored",
+			policy:   policy,
+			failures: []netip.Addr{{}, {}, {}},
+			check:    netip.Addr{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			clk := &clock{t: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)}
+			rec := &mockAuditRecorder{}
+			s := NewService(tt.policy, nil, rec)
+			s.now = clk.now
+
+			for _, ip := range tt.failures {
+				s.RecordFailure(context.Background(), FailureParams{IP: ip, Method: "POST", Route: "/api/auth/login"})
+				clk.t = clk.t.Add(tt.gap)
+			}
+			clk.t = clk.t.Add(tt.after)
+
+			err := s.Check(context.Background(), tt.check)
+			if tt.wantBanned {
+				require.ErrorIs(t, err, ErrClientBanned)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestService_FailureLog(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	rec := &mockAuditRecorder{}
+	s := NewService(Policy{Threshold: 2, Window: time.Minute, BanDuration: time.Hour}, &buf, rec)
+	s.now = (&clock{t: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)}).now
+
+	ip := netip.MustParseAddr("198.51.100.4")
+	for range 2 {
+		s.RecordFailure(context.Background(), FailureParams{IP: ip, Method: "PROPFIND", Route: "/api/dav/*path"})
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	require.Len(t, lines, 3)
+	assert.Equal(t,
+		"2025-01-01T12:00:00Z aegis-vault-keeper[auth]: authentication failure from 198.51.100.4 "+
+			"method=PROPFIND route=/api/dav/*path",
+		lines[0])
+	assert.Equal(t,
+		"2025-01-01T12:00:00Z aegis-vault-keeper[auth]: banned 198.51.100.4/32 from 198.51.100.4 "+
+			"until 2025-01-01T13:00:00Z",
+		lines[2])
+
+	require.Len(t, rec.events, 1)
+	assert.Equal(t, audit.EventClientBanned, rec.events[0].Type)
+	assert.Equal(t, "198.51.100.4/32", rec.events[0].Details["network"])
+}
+
+func TestService_MaxClients(t *testing.T) {
+	t.Parallel()
+
+	s := NewService(Policy{Threshold: 1, Window: time.Minute, BanDuration: time.Hour}, nil, &mockAuditRecorder{})
+	for i := range maxClients {
+		s.clients[netip.PrefixFrom(netip.AddrFrom4([4]byte{10, byte(i >> 16), byte(i >> 8), byte(i)}), 32)] = &client{
+			windowStart: s.now(),
+		}
+	}
+
+	ip := netip.MustParseAddr("192.0.2.1")
+	s.RecordFailure(context.Background(), FailureParams{IP: ip})
+	assert.NoError(t, s.Check(context.Background(), ip), "clients beyond the limit should not be tracked")
+	assert.Len(t, s.clients, maxClients)
+}
@@ -0,0 +1,6 @@
+// Package checkout provides shared credential check-out application services for the AegisVaultKeeper server.
+//
+// This package implements the check-out model for credentials owners share with a directory group: one member
+// at a time checks the credential out to reveal it, checks it back in or has it checked in automatically when
+// the check-out ends, and the password is optionally rotated on every check-in.
+package checkout
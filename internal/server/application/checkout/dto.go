@@ -0,0 +1,116 @@
+package checkout
+
+import (
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/checkout"
+	"github.com/google/uuid"
+)
+
+// Share represents a shared credential data transfer object for application layer communication.
+type Share struct {
+	// CreatedAt indicates when the credential was shared.
+	CreatedAt time.Time
+	// CheckedOutAt indicates when the current holder checked the credential out; zero while checked in.
+	CheckedOutAt time.Time
+	// ExpiresAt indicates when the current check-out ends automatically; zero while checked in.
+	ExpiresAt time.Time
+	// CredentialID identifies the shared credential.
+	CredentialID uuid.UUID
+	// OwnerID identifies the owner of the credential.
+	OwnerID uuid.UUID
+	// GroupID identifies the group whose members may check the credential out.
+	GroupID uuid.UUID
+	// HolderID identifies the member holding the credential; uuid.Nil while checked in.
+	HolderID uuid.UUID
+	// RotateOnCheckIn indicates whether the password is rotated whenever the credential is checked in.
+	RotateOnCheckIn bool
+}
+
+// newShareFromDomain converts a domain credential share to application DTO, reporting its check-out
+// at the moment; check-outs that have ended are reported as checked in.
+func newShareFromDomain(s *checkout.Share, at time.Time) *Share {
+	if s == nil {
+		return nil
+	}
+	share := &Share{
+		CredentialID:    s.CredentialID,
+		OwnerID:         s.OwnerID,
+		GroupID:         s.GroupID,
+		RotateOnCheckIn: s.RotateOnCheckIn,
+		CreatedAt:       s.CreatedAt,
+	}
+	if holder := s.Holder(at); holder != uuid.Nil {
+		share.HolderID = holder
+		share.CheckedOutAt = s.CheckedOutAt
+		share.ExpiresAt = s.ExpiresAt
+	}
+	return share
+}
+
+// newSharesFromDomain converts a slice of domain credential shares to application DTOs.
+func newSharesFromDomain(ss []*checkout.Share, at time.Time) []*Share {
+	result := make([]*Share, 0, len(ss))
+	for _, s := range ss {
+		result = append(result, newShareFromDomain(s, at))
+	}
+	return result
+}
+
+// Lease represents a check-out of a shared credential with its revealed secret.
+type Lease struct {
+	// CheckedOutAt indicates when the credential was checked out.
+	CheckedOutAt time.Time
+	// ExpiresAt indicates when the credential is checked in automatically.
+	ExpiresAt time.Time
+	// Login contains the credential login/username.
+	Login string
+	// Password contains the credential password.
+	Password string
+	// Description contains additional information about the credential.
+	Description string
+	// CredentialID identifies the checked out credential.
+	CredentialID uuid.UUID
+}
+
+// ShareParams contains parameters for sharing a credential with a group.
+type ShareParams struct {
+	// CredentialID identifies the shared credential.
+	CredentialID uuid.UUID
+	// OwnerID identifies the owner of the credential.
+	OwnerID uuid.UUID
+	// GroupID identifies the group whose members may check the credential out.
+	GroupID uuid.UUID
+	// RotateOnCheckIn determines whether the password is rotated whenever the credential is checked in.
+	RotateOnCheckIn bool
+}
+
+// UnshareParams contains parameters for stopping sharing a credential.
+type UnshareParams struct {
+	// CredentialID identifies the shared credential.
+	CredentialID uuid.UUID
+	// OwnerID identifies the owner of the credential.
+	OwnerID uuid.UUID
+}
+
+// ListParams contains parameters for listing shared credentials.
+type ListParams struct {
+	// UserID identifies the user whose own shared credentials and those shared with their groups are listed.
+	UserID uuid.UUID
+}
+
+// CheckOutParams contains parameters for checking out a shared credential.
+type CheckOutParams struct {
+	// CredentialID identifies the shared credential.
+	CredentialID uuid.UUID
+	// UserID identifies the member checking the credential out.
+	UserID uuid.UUID
+}
+
+// CheckInParams contains parameters for checking in a shared credential.
+type CheckInParams struct {
+	// CredentialID identifies the shared credential.
+	CredentialID uuid.UUID
+	// UserID identifies the member holding the credential.
+	UserID uuid.UUID
+}
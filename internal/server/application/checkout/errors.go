@@ -0,0 +1,69 @@
+package checkout
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/errutil"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/checkout"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/checkout"
+	groupRepository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/group"
+)
+
+// Shared credential check-out error definitions.
+var (
+	// ErrCheckoutAppError indicates a general shared credential application error.
+	ErrCheckoutAppError = errors.New("shared credential application error")
+
+	// ErrCheckoutTechError indicates a technical error in the shared credential system.
+	ErrCheckoutTechError = errors.New("shared credential technical error")
+
+	// ErrShareNotFound indicates that the credential is not shared with the user.
+	ErrShareNotFound = errors.New("shared credential not found")
+
+	// ErrCredentialNotFound indicates that the credential to share was not found among those of the owner.
+	ErrCredentialNotFound = errors.New("credential not found")
+
+	// ErrGroupNotFound indicates that the group to share the credential with was not found.
+	ErrGroupNotFound = errors.New("group not found")
+
+	// ErrCheckedOut indicates that another member holds the credential.
+	ErrCheckedOut = errors.New("credential is checked out by another member")
+
+	// ErrNotCheckedOut indicates that the user does not hold the credential.
+	ErrNotCheckedOut = errors.New("credential is not checked out by the user")
+)
+
+// mapError maps domain and repository errors to application-level errors.
+func mapError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	mapped := errutil.MapError(mapFn, err)
+	if mapped != nil {
+		return fmt.Errorf("shared credential error mapping failed: %w", mapped)
+	}
+	return nil
+}
+
+// mapFn provides the actual error mapping logic for different error types.
+func mapFn(err error) error {
+	switch {
+	case errors.Is(err, checkout.ErrNewShareParamsValidation):
+		return ErrCheckoutAppError
+	case errors.Is(err, checkout.ErrIncorrectCredential), errors.Is(err, checkout.ErrIncorrectGroup),
+		errors.Is(err, checkout.ErrIncorrectTTL):
+		return ErrCheckoutAppError
+	case errors.Is(err, checkout.ErrCheckedOut):
+		return ErrCheckedOut
+	case errors.Is(err, checkout.ErrNotCheckedOut):
+		return ErrNotCheckedOut
+	case errors.Is(err, repository.ErrShareNotFound):
+		return ErrShareNotFound
+	case errors.Is(err, groupRepository.ErrGroupNotFound):
+		return ErrGroupNotFound
+	default:
+		return errors.Join(ErrCheckoutTechError, err)
+	}
+}
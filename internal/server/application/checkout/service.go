@@ -0,0 +1,403 @@
+package checkout
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	credentialApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/credential"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/audit"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/checkout"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/group"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/license"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/checkout"
+	groupRepository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/group"
+	"github.com/google/uuid"
+)
+
+// defaultTTL specifies how long check-outs last when no duration is configured.
+const defaultTTL = time.Hour
+
+// Check-in reasons recorded in the audit log.
+const (
+	// reasonManual marks check-ins done by the holder.
+	reasonManual = "manual"
+	// reasonTimeout marks check-ins done automatically when the check-out ended.
+	reasonTimeout = "timeout"
+)
+
+// Repository defines the interface for credential share persistence operations.
+type Repository interface {
+	// Save persists a credential share using the provided parameters.
+	Save(ctx context.Context, params repository.SaveParams) error
+	// Load retrieves credential shares using the provided parameters.
+	Load(ctx context.Context, params repository.LoadParams) ([]*checkout.Share, error)
+	// SwapLease replaces the check-out of a share unless it changed concurrently.
+	SwapLease(ctx context.Context, params repository.SwapLeaseParams) error
+	// Delete removes a credential share using the provided parameters.
+	Delete(ctx context.Context, params repository.DeleteParams) error
+}
+
+// GroupRepository defines the interface for looking up the groups credentials are shared with.
+type GroupRepository interface {
+	// Load retrieves groups and the number of matching groups using the provided parameters.
+	Load(ctx context.Context, params groupRepository.LoadParams) ([]*group.Group, int, error)
+}
+
+// CredentialReader defines the interface for revealing the credentials of their owners.
+type CredentialReader interface {
+	// Pull retrieves a specific credential of the owner.
+	Pull(ctx context.Context, params credentialApp.PullParams) (*credentialApp.Credential, error)
+}
+
+// Rotator defines the hook rotating the password of shared credentials configured to rotate on check-in.
+type Rotator interface {
+	// Rotate replaces the password of the credential of the owner.
+	Rotate(ctx context.Context, params credentialApp.RotateParams) error
+}
+
+// AuditRecorder defines the interface for recording security audit events.
+type AuditRecorder interface {
+	// Record stores the audit event.
+	Record(ctx context.Context, e audit.Event)
+}
+
+// PlanEnforcer defines the interface for checking the limits of the plans of users.
+type PlanEnforcer interface {
+	// CheckSharing ensures that the plan of the user allows sharing with a group of the given number of members.
+	CheckSharing(ctx context.Context, userID uuid.UUID, members int) error
+}
+
+// LicenseEnforcer defines the interface for checking enterprise licenses.
+type LicenseEnforcer interface {
+	// Require ensures that a valid license covers the enterprise feature.
+	Require(ctx context.Context, feature string) error
+}
+
+// Service provides the check-out model of shared credentials.
+type Service struct {
+	// r is the repository interface for credential share persistence operations.
+	r Repository
+	// groups looks up the groups credentials are shared with.
+	groups GroupRepository
+	// credentials reveals shared credentials to their holders.
+	credentials CredentialReader
+	// rotator rotates passwords on check-in.
+	rotator Rotator
+	// audit records shares, check-outs, check-ins and rotations.
+	audit AuditRecorder
+	// plans checks whether the plans of owners allow sharing; nil allows every share.
+	plans PlanEnforcer
+	// licenses checks whether the organization vaults feature is licensed; nil allows every share.
+	licenses LicenseEnforcer
+	// now returns the current time.
+	now func() time.Time
+	// ttl specifies how long a check-out lasts before the credential is checked in automatically.
+	ttl time.Duration
+}
+
+// NewService creates a new shared credential service instance.
+// Check-outs last for ttl, or one hour when ttl is not positive. Nil plan and license enforcers allow every share.
+func NewService(
+	r Repository,
+	groups GroupRepository,
+	credentials CredentialReader,
+	rotator Rotator,
+	audit AuditRecorder,
+	ttl time.Duration,
+	plans PlanEnforcer,
+	licenses LicenseEnforcer,
+) *Service {
+	if ttl <= 0 {
+		ttl = defaultTTL
+	}
+	return &Service{
+		r:           r,
+		groups:      groups,
+		credentials: credentials,
+		rotator:     rotator,
+		audit:       audit,
+		plans:       plans,
+		licenses:    licenses,
+		now:         time.Now,
+		ttl:         ttl,
+	}
+}
+
+// Share shares a credential of the owner with a group, or changes the group and rotation setting of a
+// credential already shared. A current check-out is kept. Sharing requires the organization vaults license;
+// credentials already shared stay available to their groups without it.
+func (s *Service) Share(ctx context.Context, params ShareParams) (*Share, error) {
+	if s.licenses != nil {
+		if err := s.licenses.Require(ctx, license.FeatureOrgVaults); err != nil {
+			return nil, fmt.Errorf("license check for sharing credential failed: %w", err)
+		}
+	}
+
+	share, err := checkout.NewShare(checkout.NewShareParams{
+		CredentialID:    params.CredentialID,
+		OwnerID:         params.OwnerID,
+		GroupID:         params.GroupID,
+		RotateOnCheckIn: params.RotateOnCheckIn,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create credential share: %w", mapError(err))
+	}
+
+	if _, err := s.credentials.Pull(ctx, credentialApp.PullParams{
+		ID:     params.CredentialID,
+		UserID: params.OwnerID,
+	}); err != nil {
+		if errors.Is(err, credentialApp.ErrCredentialNotFound) || errors.Is(err, credentialApp.ErrCredentialAccessDenied) {
+			return nil, fmt.Errorf("credential %s shared: %w", params.CredentialID, ErrCredentialNotFound)
+		}
+		return nil, fmt.Errorf("failed to load shared credential: %w", mapError(err))
+	}
+	groups, _, err := s.groups.Load(ctx, groupRepository.LoadParams{ID: params.GroupID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load share group: %w", mapError(err))
+	}
+	if s.plans != nil && len(groups) != 0 {
+		if err := s.plans.CheckSharing(ctx, params.OwnerID, len(groups[0].Members)); err != nil {
+			return nil, fmt.Errorf("plan check for sharing credential failed: %w", err)
+		}
+	}
+
+	existing, err := s.r.Load(ctx, repository.LoadParams{CredentialID: params.CredentialID, OwnerID: params.OwnerID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load credential share: %w", mapError(err))
+	}
+	if len(existing) != 0 {
+		existing[0].GroupID = share.GroupID
+		existing[0].RotateOnCheckIn = share.RotateOnCheckIn
+		share = existing[0]
+	}
+	if err := s.r.Save(ctx, repository.SaveParams{Entity: share}); err != nil {
+		return nil, fmt.Errorf("failed to save credential share: %w", mapError(err))
+	}
+
+	s.audit.Record(ctx, audit.Event{
+		Type:   audit.EventCredentialShared,
+		UserID: params.OwnerID,
+		Details: map[string]string{
+			"credential_id":      params.CredentialID.String(),
+			"group_id":           params.GroupID.String(),
+			"rotate_on_check_in": fmt.Sprint(params.RotateOnCheckIn),
+		},
+	})
+	return newShareFromDomain(share, s.now()), nil
+}
+
+// Unshare stops sharing a credential of the owner. A current check-out ends with it.
+func (s *Service) Unshare(ctx context.Context, params UnshareParams) error {
+	if err := s.r.Delete(ctx, repository.DeleteParams{
+		CredentialID: params.CredentialID,
+		OwnerID:      params.OwnerID,
+	}); err != nil {
+		return fmt.Errorf("failed to delete credential share: %w", mapError(err))
+	}
+
+	s.audit.Record(ctx, audit.Event{
+		Type:    audit.EventCredentialUnshared,
+		UserID:  params.OwnerID,
+		Details: map[string]string{"credential_id": params.CredentialID.String()},
+	})
+	return nil
+}
+
+// List retrieves the credentials the user shares and those shared with the groups of the user.
+func (s *Service) List(ctx context.Context, params ListParams) ([]*Share, error) {
+	owned, err := s.r.Load(ctx, repository.LoadParams{OwnerID: params.UserID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load owned credential shares: %w", mapError(err))
+	}
+	shared, err := s.r.Load(ctx, repository.LoadParams{MemberID: params.UserID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load credential shares of the groups: %w", mapError(err))
+	}
+
+	shares := owned
+	for _, share := range shared {
+		if share.OwnerID != params.UserID {
+			shares = append(shares, share)
+		}
+	}
+	return newSharesFromDomain(shares, s.now()), nil
+}
+
+// CheckOut hands a credential shared with a group of the user to the user and reveals it. Only one member holds
+// a credential at a time; checking out a credential the user already holds reveals it again without extending
+// the check-out. A check-out that has ended is checked in first.
+func (s *Service) CheckOut(ctx context.Context, params CheckOutParams) (*Lease, error) {
+	share, err := s.loadShared(ctx, params.CredentialID, params.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	if share.Expired(now) {
+		if err := s.expire(ctx, share); err != nil {
+			return nil, err
+		}
+	}
+
+	holderID, checkedOutAt := share.HolderID, share.CheckedOutAt
+	if err := share.CheckOut(params.UserID, s.ttl, now); err != nil {
+		return nil, fmt.Errorf("failed to check out credential: %w", mapError(err))
+	}
+	if holderID != params.UserID {
+		if err := s.swapLease(ctx, share, holderID, checkedOutAt); err != nil {
+			if errors.Is(err, repository.ErrLeaseChanged) {
+				return nil, fmt.Errorf("credential %s checked out concurrently: %w", share.CredentialID, ErrCheckedOut)
+			}
+			return nil, err
+		}
+	}
+
+	cred, err := s.credentials.Pull(ctx, credentialApp.PullParams{ID: share.CredentialID, UserID: share.OwnerID})
+	if err != nil {
+		if errors.Is(err, credentialApp.ErrCredentialNotFound) {
+			return nil, fmt.Errorf("checked out credential %s: %w", share.CredentialID, ErrShareNotFound)
+		}
+		return nil, fmt.Errorf("failed to reveal checked out credential: %w", mapError(err))
+	}
+
+	s.audit.Record(ctx, audit.Event{
+		Type:   audit.EventCredentialCheckedOut,
+		UserID: params.UserID,
+		Details: map[string]string{
+			"credential_id": share.CredentialID.String(),
+			"owner_id":      share.OwnerID.String(),
+			"expires_at":    share.ExpiresAt.UTC().Format(time.RFC3339),
+		},
+	})
+	return &Lease{
+		CredentialID: share.CredentialID,
+		Login:        cred.Login,
+		Password:     cred.Password,
+		Description:  cred.Description,
+		CheckedOutAt: share.CheckedOutAt,
+		ExpiresAt:    share.ExpiresAt,
+	}, nil
+}
+
+// CheckIn returns a credential the user holds, rotating its password when the share is configured to.
+func (s *Service) CheckIn(ctx context.Context, params CheckInParams) error {
+	share, err := s.loadShared(ctx, params.CredentialID, params.UserID)
+	if err != nil {
+		return err
+	}
+
+	holderID, checkedOutAt := share.HolderID, share.CheckedOutAt
+	if err := share.CheckIn(params.UserID, s.now()); err != nil {
+		return fmt.Errorf("failed to check in credential: %w", mapError(err))
+	}
+	if err := s.swapLease(ctx, share, holderID, checkedOutAt); err != nil {
+		if errors.Is(err, repository.ErrLeaseChanged) {
+			return fmt.Errorf("credential %s checked in concurrently: %w", share.CredentialID, ErrNotCheckedOut)
+		}
+		return err
+	}
+	return s.checkedIn(ctx, share, holderID, reasonManual)
+}
+
+// CheckInExpired checks in every credential whose check-out has ended and returns how many were checked in.
+// Rotation failures do not stop the remaining check-ins and are reported together.
+func (s *Service) CheckInExpired(ctx context.Context) (int, error) {
+	expired, err := s.r.Load(ctx, repository.LoadParams{ExpiredAt: s.now()})
+	if err != nil {
+		return 0, fmt.Errorf("failed to load expired credential check-outs: %w", mapError(err))
+	}
+
+	var (
+		n    int
+		errs []error
+	)
+	for _, share := range expired {
+		if err := s.expire(ctx, share); err != nil {
+			if !errors.Is(err, ErrCheckedOut) {
+				errs = append(errs, err)
+			}
+			continue
+		}
+		n++
+	}
+	return n, errors.Join(errs...)
+}
+
+// loadShared loads the share of the credential among those shared with the groups of the user.
+func (s *Service) loadShared(ctx context.Context, credentialID, userID uuid.UUID) (*checkout.Share, error) {
+	shares, err := s.r.Load(ctx, repository.LoadParams{CredentialID: credentialID, MemberID: userID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load credential share: %w", mapError(err))
+	}
+	if len(shares) == 0 {
+		return nil, fmt.Errorf("credential %s: %w", credentialID, ErrShareNotFound)
+	}
+	return shares[0], nil
+}
+
+// expire checks in a credential whose check-out has ended. Returns ErrCheckedOut when the check-out
+// changed concurrently, since another check-in or check-out already took place.
+func (s *Service) expire(ctx context.Context, share *checkout.Share) error {
+	holderID, checkedOutAt := share.HolderID, share.CheckedOutAt
+	share.Release()
+	if err := s.swapLease(ctx, share, holderID, checkedOutAt); err != nil {
+		if errors.Is(err, repository.ErrLeaseChanged) {
+			return fmt.Errorf("credential %s checked in concurrently: %w", share.CredentialID, ErrCheckedOut)
+		}
+		return err
+	}
+	return s.checkedIn(ctx, share, holderID, reasonTimeout)
+}
+
+// swapLease stores the check-out of the share in place of the loaded one.
+func (s *Service) swapLease(
+	ctx context.Context,
+	share *checkout.Share,
+	holderID uuid.UUID,
+	checkedOutAt time.Time,
+) error {
+	err := s.r.SwapLease(ctx, repository.SwapLeaseParams{
+		Entity:       share,
+		HolderID:     holderID,
+		CheckedOutAt: checkedOutAt,
+	})
+	if errors.Is(err, repository.ErrLeaseChanged) {
+		return fmt.Errorf("failed to save credential check-out: %w", err)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save credential check-out: %w", mapError(err))
+	}
+	return nil
+}
+
+// checkedIn records the check-in of the holder and runs the rotation hook when the share is configured to.
+func (s *Service) checkedIn(ctx context.Context, share *checkout.Share, holderID uuid.UUID, reason string) error {
+	s.audit.Record(ctx, audit.Event{
+		Type:   audit.EventCredentialCheckedIn,
+		UserID: holderID,
+		Details: map[string]string{
+			"credential_id": share.CredentialID.String(),
+			"owner_id":      share.OwnerID.String(),
+			"reason":        reason,
+		},
+	})
+	if !share.RotateOnCheckIn {
+		return nil
+	}
+
+	if err := s.rotator.Rotate(ctx, credentialApp.RotateParams{
+		ID:     share.CredentialID,
+		UserID: share.OwnerID,
+	}); err != nil {
+		return fmt.Errorf("failed to rotate checked in credential %s: %w", share.CredentialID, mapError(err))
+	}
+	s.audit.Record(ctx, audit.Event{
+		Type:    audit.EventCredentialRotated,
+		UserID:  share.OwnerID,
+		Details: map[string]string{"credential_id": share.CredentialID.String()},
+	})
+	return nil
+}
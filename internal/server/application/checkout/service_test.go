@@ -0,0 +1,475 @@
+package checkout
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	credentialApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/credential"
+	licenseApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/license"
+	planApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/plan"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/audit"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/checkout"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/group"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/license"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/checkout"
+	groupRepository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/group"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockRepository implements Repository for testing.
+type mockRepository struct {
+	swapErr error
+	saved   *checkout.Share
+	swapped []repository.SwapLeaseParams
+	stored  []*checkout.Share
+}
+
+func (m *mockRepository) Save(_ context.Context, params repository.SaveParams) error {
+	m.saved = params.Entity
+	return nil
+}
+
+func (m *mockRepository) Load(_ context.Context, params repository.LoadParams) ([]*checkout.Share, error) {
+	var shares []*checkout.Share
+	for _, s := range m.stored {
+		if params.OwnerID != uuid.Nil && s.OwnerID != params.OwnerID {
+			continue
+		}
+		if params.CredentialID != uuid.Nil && s.CredentialID != params.CredentialID {
+			continue
+		}
+		if !params.ExpiredAt.IsZero() && !s.Expired(params.ExpiredAt) {
+			continue
+		}
+		shares = append(shares, s)
+	}
+	return shares, nil
+}
+
+func (m *mockRepository) SwapLease(_ context.Context, params repository.SwapLeaseParams) error {
+	if m.swapErr != nil {
+		return m.swapErr
+	}
+	m.swapped = append(m.swapped, params)
+	return nil
+}
+
+func (m *mockRepository) Delete(context.Context, repository.DeleteParams) error {
+	if len(m.stored) == 0 {
+		return repository.ErrShareNotFound
+	}
+	return nil
+}
+
+// mockGroupRepository implements GroupRepository for testing.
+type mockGroupRepository struct {
+	err error
+}
+
+func (m *mockGroupRepository) Load(context.Context, groupRepository.LoadParams) ([]*group.Group, int, error) {
+	return []*group.Group{{}}, 1, m.err
+}
+
+// mockPlanEnforcer implements PlanEnforcer for testing.
+type mockPlanEnforcer struct {
+	err error
+}
+
+func (m *mockPlanEnforcer) CheckSharing(context.Context, uuid.UUID, int) error {
+	return m.err
+}
+
+// mockLicenseEnforcer implements LicenseEnforcer for testing.
+type mockLicenseEnforcer struct {
+	err      error
+	features []string
+}
+
+func (m *mockLicenseEnforcer) Require(_ context.Context, feature string) error {
+	m.features = append(m.features, feature)
+	return m.err
+}
+
+// mockCredentials implements CredentialReader and Rotator for testing.
+type mockCredentials struct {
+	pullErr   error
+	rotateErr error
+	rotated   []credentialApp.RotateParams
+}
+
+func (m *mockCredentials) Pull(_ context.Context, params credentialApp.PullParams) (*credentialApp.Credential, error) {
+	if m.pullErr != nil {
+		return nil, m.pullErr
+	}
+	return &credentialApp.Credential{ID: params.ID, UserID: params.UserID, Login: "root", Password: "s3cret"}, nil
+}
+
+func (m *mockCredentials) Rotate(_ context.Context, params credentialApp.RotateParams) error {
+	m.rotated = append(m.rotated, params)
+	return m.rotateErr
+}
+
+// mockAuditRecorder implements AuditRecorder for testing.
+type mockAuditRecorder struct {
+	events []audit.Event
+}
+
+func (m *mockAuditRecorder) Record(_ context.Context, e audit.Event) {
+	m.events = append(m.events, e)
+}
+
+// eventTypes returns the types of the recorded events in order.
+func (m *mockAuditRecorder) eventTypes() []string {
+	types := make([]string, 0, len(m.events))
+	for _, e := range m.events {
+		types = append(types, e.Type)
+	}
+	return types
+}
+
+func TestNewService(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		ttl  time.Duration
+		want time.Duration
+	}{
+		{name: "configured TTL", ttl: 30 * time.Minute, want: 30 * time.Minute},
+		{name: "default TTL", want: time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := NewService(nil, nil, nil, nil, nil, tt.ttl, nil, nil)
+
+			assert.Equal(t, tt.want, s.ttl)
+		})
+	}
+}
+
+func TestService_Share(t *testing.T) {
+	t.Parallel()
+
+	credentialID, ownerID, groupID, holderID := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		pullErr    error
+		groupErr   error
+		planErr    error
+		licenseErr error
+		wantErr    error
+		existing   *checkout.Share
+		name       string
+		wantHolder uuid.UUID
+		groupID    uuid.UUID
+	}{
+		{name: "new share", groupID: groupID},
+		{
+			name:    "existing share keeps the check-out",
+			groupID: groupID,
+			existing: &checkout.Share{
+				CredentialID: credentialID, OwnerID: ownerID, GroupID: uuid.New(),
+				HolderID: holderID, CheckedOutAt: now.Add(-time.Minute), ExpiresAt: now.Add(time.Hour),
+			},
+			wantHolder: holderID,
+		},
+		{name: "missing group", wantErr: ErrCheckoutAppError},
+		{
+			name:    "credential of another user",
+			groupID: groupID,
+			pullErr: credentialApp.ErrCredentialNotFound,
+			wantErr: ErrCredentialNotFound,
+		},
+		{
+			name:     "unknown group",
+			groupID:  groupID,
+			groupErr: groupRepository.ErrGroupNotFound,
+			wantErr:  ErrGroupNotFound,
+		},
+		{
+			name:    "sharing outside the plan",
+			groupID: groupID,
+			planErr: planApp.ErrPlanSharingUnavailable,
+			wantErr: planApp.ErrPlanUpgradeRequired,
+		},
+		{
+			name:       "sharing without license",
+			groupID:    groupID,
+			licenseErr: licenseApp.ErrLicenseFeatureUnavailable,
+			wantErr:    licenseApp.ErrLicenseFeatureUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &mockRepository{}
+			if tt.existing != nil {
+				repo.stored = []*checkout.Share{tt.existing}
+			}
+			recorder := &mockAuditRecorder{}
+			creds := &mockCredentials{pullErr: tt.pullErr}
+			plans := &mockPlanEnforcer{err: tt.planErr}
+			licenses := &mockLicenseEnforcer{err: tt.licenseErr}
+			groups := &mockGroupRepository{err: tt.groupErr}
+			s := NewService(repo, groups, creds, creds, recorder, time.Hour, plans, licenses)
+			s.now = func() time.Time { return now }
+
+			got, err := s.Share(context.Background(), ShareParams{
+				CredentialID:    credentialID,
+				OwnerID:         ownerID,
+				GroupID:         tt.groupID,
+				RotateOnCheckIn: true,
+			})
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, repo.saved)
+				assert.Empty(t, recorder.events)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, []string{license.FeatureOrgVaults}, licenses.features)
+			require.NotNil(t, repo.saved)
+			assert.Equal(t, groupID, repo.saved.GroupID)
+			assert.True(t, repo.saved.RotateOnCheckIn)
+			assert.Equal(t, tt.wantHolder, got.HolderID)
+			assert.Equal(t, []string{audit.EventCredentialShared}, recorder.eventTypes())
+		})
+	}
+}
+
+func TestService_List(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	owned := &checkout.Share{CredentialID: uuid.New(), OwnerID: userID}
+	shared := &checkout.Share{
+		CredentialID: uuid.New(), OwnerID: uuid.New(),
+		HolderID: uuid.New(), CheckedOutAt: now.Add(-2 * time.Hour), ExpiresAt: now.Add(-time.Hour),
+	}
+
+	s := NewService(
+		&mockRepository{stored: []*checkout.Share{owned, shared}},
+		&mockGroupRepository{}, &mockCredentials{}, &mockCredentials{}, &mockAuditRecorder{}, time.Hour,
+		nil, nil,
+	)
+	s.now = func() time.Time { return now }
+
+	got, err := s.List(context.Background(), ListParams{UserID: userID})
+
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, owned.CredentialID, got[0].CredentialID)
+	assert.Equal(t, shared.CredentialID, got[1].CredentialID)
+	assert.Equal(t, uuid.Nil, got[1].HolderID, "ended check-outs are reported as checked in")
+	assert.True(t, got[1].ExpiresAt.IsZero())
+}
+
+func TestService_CheckOut(t *testing.T) {
+	t.Parallel()
+
+	credentialID, ownerID, memberID, otherID := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		swapErr     error
+		wantErr     error
+		share       *checkout.Share
+		name        string
+		wantEvents  []string
+		wantSwaps   int
+		wantRotated int
+	}{
+		{
+			name:       "checked in",
+			share:      &checkout.Share{CredentialID: credentialID, OwnerID: ownerID},
+			wantSwaps:  1,
+			wantEvents: []string{audit.EventCredentialCheckedOut},
+		},
+		{
+			name: "held by the member",
+			share: &checkout.Share{
+				CredentialID: credentialID, OwnerID: ownerID,
+				HolderID: memberID, CheckedOutAt: now.Add(-time.Minute), ExpiresAt: now.Add(time.Minute),
+			},
+			wantEvents: []string{audit.EventCredentialCheckedOut},
+		},
+		{
+			name: "held by another member",
+			share: &checkout.Share{
+				CredentialID: credentialID, OwnerID: ownerID,
+				HolderID: otherID, CheckedOutAt: now.Add(-time.Minute), ExpiresAt: now.Add(time.Minute),
+			},
+			wantErr: ErrCheckedOut,
+		},
+		{
+			name: "check-out of another member ended",
+			share: &checkout.Share{
+				CredentialID: credentialID, OwnerID: ownerID, RotateOnCheckIn: true,
+				HolderID: otherID, CheckedOutAt: now.Add(-2 * time.Hour), ExpiresAt: now.Add(-time.Hour),
+			},
+			wantSwaps:   2,
+			wantRotated: 1,
+			wantEvents: []string{
+				audit.EventCredentialCheckedIn, audit.EventCredentialRotated, audit.EventCredentialCheckedOut,
+			},
+		},
+		{
+			name:    "checked out concurrently",
+			share:   &checkout.Share{CredentialID: credentialID, OwnerID: ownerID},
+			swapErr: repository.ErrLeaseChanged,
+			wantErr: ErrCheckedOut,
+		},
+		{name: "not shared with the member", wantErr: ErrShareNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &mockRepository{swapErr: tt.swapErr}
+			if tt.share != nil {
+				repo.stored = []*checkout.Share{tt.share}
+			}
+			recorder := &mockAuditRecorder{}
+			creds := &mockCredentials{}
+			s := NewService(repo, &mockGroupRepository{}, creds, creds, recorder, time.Hour, nil, nil)
+			s.now = func() time.Time { return now }
+
+			got, err := s.CheckOut(context.Background(), CheckOutParams{CredentialID: credentialID, UserID: memberID})
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, got)
+				assert.NotContains(t, recorder.eventTypes(), audit.EventCredentialCheckedOut)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "s3cret", got.Password)
+			assert.Equal(t, tt.share.ExpiresAt, got.ExpiresAt)
+			assert.Equal(t, memberID, tt.share.HolderID)
+			assert.Len(t, repo.swapped, tt.wantSwaps)
+			assert.Len(t, creds.rotated, tt.wantRotated)
+			assert.Equal(t, tt.wantEvents, recorder.eventTypes())
+		})
+	}
+}
+
+func TestService_CheckIn(t *testing.T) {
+	t.Parallel()
+
+	credentialID, ownerID, memberID, otherID := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	checkedOutAt := now.Add(-time.Minute)
+
+	tests := []struct {
+		rotateErr   error
+		wantErr     error
+		name        string
+		wantEvents  []string
+		holderID    uuid.UUID
+		wantRotated int
+		rotate      bool
+	}{
+		{
+			name:       "held by the member",
+			holderID:   memberID,
+			wantEvents: []string{audit.EventCredentialCheckedIn},
+		},
+		{
+			name:        "rotated on check-in",
+			holderID:    memberID,
+			rotate:      true,
+			wantRotated: 1,
+			wantEvents:  []string{audit.EventCredentialCheckedIn, audit.EventCredentialRotated},
+		},
+		{
+			name:        "rotation failed",
+			holderID:    memberID,
+			rotate:      true,
+			rotateErr:   errors.New("database error"),
+			wantRotated: 1,
+			wantErr:     ErrCheckoutTechError,
+		},
+		{name: "held by another member", holderID: otherID, wantErr: ErrNotCheckedOut},
+		{name: "checked in", wantErr: ErrNotCheckedOut},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			share := &checkout.Share{
+				CredentialID: credentialID, OwnerID: ownerID, RotateOnCheckIn: tt.rotate,
+				HolderID: tt.holderID, CheckedOutAt: checkedOutAt, ExpiresAt: now.Add(time.Hour),
+			}
+			repo := &mockRepository{stored: []*checkout.Share{share}}
+			recorder := &mockAuditRecorder{}
+			creds := &mockCredentials{rotateErr: tt.rotateErr}
+			s := NewService(repo, &mockGroupRepository{}, creds, creds, recorder, time.Hour, nil, nil)
+			s.now = func() time.Time { return now }
+
+			err := s.CheckIn(context.Background(), CheckInParams{CredentialID: credentialID, UserID: memberID})
+
+			assert.Len(t, creds.rotated, tt.wantRotated)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, repo.swapped, 1)
+			assert.Equal(t, memberID, repo.swapped[0].HolderID)
+			assert.Equal(t, checkedOutAt, repo.swapped[0].CheckedOutAt)
+			assert.Equal(t, uuid.Nil, share.HolderID)
+			assert.Equal(t, tt.wantEvents, recorder.eventTypes())
+			assert.Equal(t, reasonManual, recorder.events[0].Details["reason"])
+			if tt.rotate {
+				assert.Equal(t, credentialApp.RotateParams{ID: credentialID, UserID: ownerID}, creds.rotated[0])
+			}
+		})
+	}
+}
+
+func TestService_CheckInExpired(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	expired := &checkout.Share{
+		CredentialID: uuid.New(), OwnerID: uuid.New(), RotateOnCheckIn: true,
+		HolderID: uuid.New(), CheckedOutAt: now.Add(-2 * time.Hour), ExpiresAt: now.Add(-time.Hour),
+	}
+	current := &checkout.Share{
+		CredentialID: uuid.New(), OwnerID: uuid.New(),
+		HolderID: uuid.New(), CheckedOutAt: now.Add(-time.Minute), ExpiresAt: now.Add(time.Hour),
+	}
+	holderID, currentHolderID := expired.HolderID, current.HolderID
+
+	repo := &mockRepository{stored: []*checkout.Share{expired, current}}
+	recorder := &mockAuditRecorder{}
+	creds := &mockCredentials{}
+	s := NewService(repo, &mockGroupRepository{}, creds, creds, recorder, time.Hour, nil, nil)
+	s.now = func() time.Time { return now }
+
+	n, err := s.CheckInExpired(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, uuid.Nil, expired.HolderID)
+	assert.Equal(t, currentHolderID, current.HolderID)
+	require.Len(t, recorder.events, 2)
+	assert.Equal(t, audit.EventCredentialCheckedIn, recorder.events[0].Type)
+	assert.Equal(t, holderID, recorder.events[0].UserID)
+	assert.Equal(t, reasonTimeout, recorder.events[0].Details["reason"])
+	assert.Equal(t, audit.EventCredentialRotated, recorder.events[1].Type)
+}
@@ -17,23 +17,62 @@ type Credential struct {
 	Password string
 	// Description contains additional information about the credential.
 	Description string
+	// URIs contains the website and app addresses of the credential with their match rules.
+	URIs []URI
 	// ID uniquely identifies the credential.
 	ID uuid.UUID
 	// UserID identifies the credential owner.
 	UserID uuid.UUID
+	// Archived reports whether the credential is tagged archived; set for listed credentials only.
+	Archived bool
+}
+
+// URI represents a website or app address of a credential with the rule it is matched by.
+type URI struct {
+	// Value contains the URI, or the regular expression for the regex rule.
+	Value string
+	// Match contains the match rule: base_domain, host, starts_with, regex or never; empty means base_domain.
+	Match string
+}
+
+// newURIsFromDomain converts domain credential URIs to application DTOs.
+func newURIsFromDomain(uris []credential.URI) []URI {
+	if len(uris) == 0 {
+		return nil
+	}
+	result := make([]URI, 0, len(uris))
+	for _, u := range uris {
+		result = append(result, URI{Value: u.Value, Match: string(u.Match)})
+	}
+	return result
+}
+
+// urisToDomain converts application URI DTOs to domain credential URIs.
+func urisToDomain(uris []URI) []credential.URI {
+	if len(uris) == 0 {
+		return nil
+	}
+	result := make([]credential.URI, 0, len(uris))
+	for _, u := range uris {
+		result = append(result, credential.URI{Value: u.Value, Match: credential.MatchRule(u.Match)})
+	}
+	return result
 }
 
 // newCredentialFromDomain converts a domain credential entity to application DTO.
+// The stored URI list is authenticated on load, so it only fails to decode when it was not selected.
 func newCredentialFromDomain(c *credential.Credential) *Credential {
 	if c == nil {
 		return nil
 	}
+	uris, _ := c.URIList()
 	return &Credential{
 		ID:          c.ID,
 		UserID:      c.UserID,
 		Login:       string(c.Login),
 		Password:    string(c.Password),
 		Description: string(c.Description),
+		URIs:        newURIsFromDomain(uris),
 		UpdatedAt:   c.UpdatedAt,
 	}
 }
@@ -49,6 +88,10 @@ func newCredentialsFromDomain(cs []*credential.Credential) []*Credential {
 
 // PullParams contains parameters for retrieving a specific credential.
 type PullParams struct {
+	// AsOf selects the version current at the specified moment; zero value pulls the current version.
+	AsOf time.Time
+	// Fields selects the sensitive fields of the credential to decrypt by name; empty selects all of them.
+	Fields []string
 	// ID specifies the credential to retrieve.
 	ID uuid.UUID
 	// UserID specifies the credential owner.
@@ -57,6 +100,51 @@ type PullParams struct {
 
 // ListParams contains parameters for listing user credentials.
 type ListParams struct {
+	// After lists only the credentials whose ID sorts after it; zero value starts at the first.
+	After uuid.UUID
+	// AsOf selects the versions current at the specified moment; zero value lists current versions.
+	AsOf time.Time
+	// Fields selects the sensitive fields of credentials to decrypt by name; empty selects all of them.
+	Fields []string
+	// Limit caps the number of listed credentials and orders them by ID; zero value lists all.
+	Limit int
+	// UserID specifies the credential owner.
+	UserID uuid.UUID
+	// OrderFolder names the folder whose manual order sorts the credentials with ManualOrder; empty is the root.
+	OrderFolder string
+	// ExcludeArchived leaves out the credentials tagged archived; zero value lists every credential.
+	ExcludeArchived bool
+	// ManualOrder sorts the credentials in the manual order of OrderFolder, the others after them in their default
+	// order; zero value keeps the default order.
+	ManualOrder bool
+}
+
+// MatchParams contains parameters for finding the credentials of a website or app.
+type MatchParams struct {
+	// URI specifies the address of the website or app asking for credentials.
+	URI string
+	// UserID specifies the credential owner.
+	UserID uuid.UUID
+}
+
+// RecoverParams contains parameters for restoring a credential to an earlier version.
+type RecoverParams struct {
+	// AsOf specifies the moment whose credential version becomes current again.
+	AsOf time.Time
+	// ID specifies the credential to recover.
+	ID uuid.UUID
+	// UserID specifies the credential owner.
+	UserID uuid.UUID
+}
+
+// RotateParams contains parameters for replacing the secret of a credential.
+type RotateParams struct {
+	// Login specifies the new login; empty keeps the current one.
+	Login string
+	// Password specifies the new password; empty generates a random one.
+	Password string
+	// ID specifies the credential to rotate.
+	ID uuid.UUID
 	// UserID specifies the credential owner.
 	UserID uuid.UUID
 }
@@ -69,8 +157,18 @@ type PushParams struct {
 	Password string
 	// Description provides additional information about the credential.
 	Description string
+	// URIs specifies the website and app addresses of the credential with their match rules.
+	URIs []URI
 	// ID uniquely identifies the credential.
 	ID uuid.UUID
 	// UserID identifies the credential owner.
 	UserID uuid.UUID
 }
+
+// PushBatchParams contains parameters for creating or updating several credentials of one user at once.
+type PushBatchParams struct {
+	// Items contains the credentials to push; their UserID is replaced by the batch UserID.
+	Items []*PushParams
+	// UserID identifies the owner of all credentials in the batch.
+	UserID uuid.UUID
+}
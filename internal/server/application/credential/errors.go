@@ -6,6 +6,7 @@ import (
 
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/errutil"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/credential"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/fieldcrypt"
 )
 
 // Credential error definitions.
@@ -22,11 +23,23 @@ var (
 	// ErrCredentialIncorrectPassword indicates an incorrect password was provided.
 	ErrCredentialIncorrectPassword = errors.New("incorrect password")
 
+	// ErrCredentialIncorrectURI indicates an incorrect credential URI or URI to match was provided.
+	ErrCredentialIncorrectURI = errors.New("incorrect URI")
+
+	// ErrCredentialIncorrectMatchRule indicates an unknown URI match rule was provided.
+	ErrCredentialIncorrectMatchRule = errors.New("incorrect URI match rule")
+
+	// ErrCredentialTooManyURIs indicates more URIs than a credential may have were provided.
+	ErrCredentialTooManyURIs = errors.New("too many URIs")
+
 	// ErrCredentialNotFound indicates the requested credential was not found.
 	ErrCredentialNotFound = errors.New("credential not found")
 
 	// ErrCredentialAccessDenied indicates access to the credential is not permitted.
 	ErrCredentialAccessDenied = errors.New("access to this credential is denied")
+
+	// ErrCredentialIntegrityViolation indicates stored credential data failed ownership integrity verification.
+	ErrCredentialIntegrityViolation = errors.New("credential data integrity violation")
 )
 
 // mapError maps domain and repository errors to application-level errors.
@@ -50,6 +63,14 @@ func mapFn(err error) error {
 		return ErrCredentialIncorrectLogin
 	case errors.Is(err, credential.ErrIncorrectPassword):
 		return ErrCredentialIncorrectPassword
+	case errors.Is(err, credential.ErrIncorrectURI):
+		return ErrCredentialIncorrectURI
+	case errors.Is(err, credential.ErrIncorrectMatchRule):
+		return ErrCredentialIncorrectMatchRule
+	case errors.Is(err, credential.ErrTooManyURIs):
+		return ErrCredentialTooManyURIs
+	case errors.Is(err, fieldcrypt.ErrIntegrityViolation):
+		return errors.Join(ErrCredentialIntegrityViolation, err)
 	default:
 		return errors.Join(ErrCredentialTechError, err)
 	}
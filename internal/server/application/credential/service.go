@@ -2,11 +2,22 @@ package credential
 
 import (
 	"context"
+	"crypto/rand"
 	"errors"
 	"fmt"
+	"slices"
+	"time"
 
+	authzApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/authz"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/authz"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/credential"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/itemorder"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/itempath"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/itemtag"
 	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/credential"
+	orderRepository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/itemorder"
+	tagRepository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/itemtag"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/securebytes"
 	"github.com/google/uuid"
 )
 
@@ -15,45 +26,193 @@ type Repository interface {
 	// Save persists a credential entity using the provided parameters.
 	Save(ctx context.Context, params repository.SaveParams) error
 
+	// SaveBatch persists several credential entities of one user at once.
+	SaveBatch(ctx context.Context, params repository.SaveBatchParams) error
+
 	// Load retrieves credential entities using the provided parameters.
 	Load(ctx context.Context, params repository.LoadParams) ([]*credential.Credential, error)
 }
 
+// Authorizer defines the interface for the authorization decision point.
+type Authorizer interface {
+	// Authorize decides whether the user may perform the action on the credential.
+	Authorize(ctx context.Context, req authzApp.Request) error
+}
+
+// PlanEnforcer defines the interface for checking the limits of the plans of users.
+type PlanEnforcer interface {
+	// CheckItems ensures that the plan of the user has room for the given number of additional items.
+	CheckItems(ctx context.Context, userID uuid.UUID, added int) error
+}
+
+// PolicyEnforcer defines the interface for checking credentials against the item policies of users.
+type PolicyEnforcer interface {
+	// CheckCredential ensures that the item policies of the user allow storing credentials with the passwords.
+	CheckCredential(ctx context.Context, userID uuid.UUID, passwords ...string) error
+}
+
+// TagRepository defines the interface for looking up the tags of vault items.
+type TagRepository interface {
+	// Load retrieves the tags of the items of the user matching the parameters.
+	Load(ctx context.Context, params tagRepository.LoadParams) ([]*itemtag.ItemTags, error)
+}
+
+// OrderRepository defines the interface for looking up the manual orders of folders.
+type OrderRepository interface {
+	// Load retrieves the manual order of a folder of the user.
+	Load(ctx context.Context, params orderRepository.LoadParams) (*itemorder.ItemOrder, error)
+}
+
 // Service provides credential management business logic operations.
 type Service struct {
 	// r is the repository interface for credential data persistence operations.
 	r Repository
+	// authorizer decides whether users may perform actions on credentials.
+	authorizer Authorizer
+	// plans checks the plan limits of new credentials; nil leaves them unlimited.
+	plans PlanEnforcer
+	// policies checks created and updated credentials against the item policies; nil enforces none.
+	policies PolicyEnforcer
+	// tags looks up archived credentials; nil archives none.
+	tags TagRepository
+	// orders looks up the manual orders of folders; nil keeps every listing in its default order.
+	orders OrderRepository
 }
 
-// NewService creates a new credential service instance with the provided repository.
-func NewService(r Repository) *Service {
-	return &Service{r: r}
+// NewService creates a new credential service instance with the provided repository, authorization decision point,
+// plan enforcer (nil means no plan limits), item policy enforcer (nil means no item policies), tag repository
+// (nil means no archived credentials) and order repository (nil means no manual orders).
+func NewService(
+	r Repository,
+	authorizer Authorizer,
+	plans PlanEnforcer,
+	policies PolicyEnforcer,
+	tags TagRepository,
+	orders OrderRepository,
+) *Service {
+	return &Service{r: r, authorizer: authorizer, plans: plans, policies: policies, tags: tags, orders: orders}
 }
 
 // Pull retrieves a specific credential for the given user.
 func (s *Service) Pull(ctx context.Context, params PullParams) (*Credential, error) {
 	creds, err := s.r.Load(ctx, repository.LoadParams{
+		AsOf:   params.AsOf,
+		Fields: params.Fields,
 		ID:     params.ID,
 		UserID: params.UserID,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to load credentials: %w", mapError(err))
 	}
+	defer securebytes.WipeAll(creds)
 	if len(creds) == 0 {
 		return nil, fmt.Errorf("credential not found: %w", ErrCredentialNotFound)
 	}
+	if err := s.authorize(ctx, authz.ActionRead, creds[0].ID, creds[0].UserID, params.UserID); err != nil {
+		return nil, err
+	}
 	return newCredentialFromDomain(creds[0]), nil
 }
 
 // List retrieves all credentials for the specified user.
 func (s *Service) List(ctx context.Context, params ListParams) ([]*Credential, error) {
+	if err := s.authorize(ctx, authz.ActionRead, uuid.Nil, params.UserID, params.UserID); err != nil {
+		return nil, err
+	}
 	creds, err := s.r.Load(ctx, repository.LoadParams{
+		After:  params.After,
+		AsOf:   params.AsOf,
+		Fields: params.Fields,
+		Limit:  params.Limit,
 		UserID: params.UserID,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to load credentials: %w", mapError(err))
 	}
-	return newCredentialsFromDomain(creds), nil
+	defer securebytes.WipeAll(creds)
+
+	archived, err := s.archived(ctx, params.UserID)
+	if err != nil {
+		return nil, err
+	}
+	result := newCredentialsFromDomain(creds)
+	for _, c := range result {
+		c.Archived = archived[c.ID]
+	}
+	if params.ExcludeArchived {
+		result = slices.DeleteFunc(result, func(c *Credential) bool { return c.Archived })
+	}
+	if params.ManualOrder {
+		order, err := s.order(ctx, params.UserID, params.OrderFolder)
+		if err != nil {
+			return nil, err
+		}
+		itemorder.Sort(order, result, func(c *Credential) uuid.UUID { return c.ID })
+	}
+	return result, nil
+}
+
+// Match retrieves the credentials of the user with a URI matching the URI of a website or app, leaving out
+// archived ones. Every URI of a credential is compared under its own match rule.
+func (s *Service) Match(ctx context.Context, params MatchParams) ([]*Credential, error) {
+	if !credential.ValidTarget(params.URI) {
+		return nil, fmt.Errorf("failed to match credentials: %w", ErrCredentialIncorrectURI)
+	}
+	if err := s.authorize(ctx, authz.ActionRead, uuid.Nil, params.UserID, params.UserID); err != nil {
+		return nil, err
+	}
+	creds, err := s.r.Load(ctx, repository.LoadParams{UserID: params.UserID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load credentials: %w", mapError(err))
+	}
+	defer securebytes.WipeAll(creds)
+
+	archived, err := s.archived(ctx, params.UserID)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*Credential, 0)
+	for _, c := range creds {
+		if archived[c.ID] {
+			continue
+		}
+		matched, err := c.Matches(params.URI)
+		if err != nil {
+			return nil, fmt.Errorf("failed to match credential %s: %w", c.ID, mapError(err))
+		}
+		if matched {
+			result = append(result, newCredentialFromDomain(c))
+		}
+	}
+	return result, nil
+}
+
+// archived returns the IDs of the archived credentials of the user; without a tag repository none is archived.
+func (s *Service) archived(ctx context.Context, userID uuid.UUID) (map[uuid.UUID]bool, error) {
+	if s.tags == nil {
+		return nil, nil
+	}
+	tagged, err := s.tags.Load(ctx, tagRepository.LoadParams{Tag: itemtag.Archived, UserID: userID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load archived credentials: %w", mapError(err))
+	}
+	return itemtag.ItemIDs(tagged), nil
+}
+
+// order returns the manual order of the folder of the user; without an order repository, or for a folder the user
+// has not arranged, there is none.
+func (s *Service) order(ctx context.Context, userID uuid.UUID, folder string) (*itemorder.ItemOrder, error) {
+	if s.orders == nil {
+		return nil, nil
+	}
+	o, err := s.orders.Load(ctx, orderRepository.LoadParams{Folder: itempath.Normalize(folder), UserID: userID})
+	if errors.Is(err, orderRepository.ErrOrderNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load item order: %w", mapError(err))
+	}
+	return o, nil
 }
 
 // Push creates or updates a credential for the specified user.
@@ -63,16 +222,28 @@ func (s *Service) Push(ctx context.Context, params *PushParams) (uuid.UUID, erro
 		Login:       params.Login,
 		Password:    params.Password,
 		Description: params.Description,
+		URIs:        urisToDomain(params.URIs),
 	})
 	if err != nil {
 		return uuid.Nil, fmt.Errorf("failed to create credential: %w", mapError(err))
 	}
+	defer cred.Wipe()
 
 	if params.ID != uuid.Nil {
 		if err := s.checkAccessToUpdate(ctx, params.ID, params.UserID); err != nil {
 			return uuid.Nil, fmt.Errorf("access check for updating credential failed: %w", err)
 		}
 		cred.ID = params.ID
+	} else {
+		if err := s.authorize(ctx, authz.ActionWrite, uuid.Nil, params.UserID, params.UserID); err != nil {
+			return uuid.Nil, fmt.Errorf("access check for creating credential failed: %w", err)
+		}
+		if err := s.checkPlan(ctx, params.UserID, 1); err != nil {
+			return uuid.Nil, fmt.Errorf("plan check for creating credential failed: %w", err)
+		}
+	}
+	if err := s.checkPolicy(ctx, params.UserID, params.Password); err != nil {
+		return uuid.Nil, fmt.Errorf("item policy check for credential failed: %w", err)
 	}
 
 	if err := s.r.Save(ctx, repository.SaveParams{Entity: cred}); err != nil {
@@ -81,17 +252,200 @@ func (s *Service) Push(ctx context.Context, params *PushParams) (uuid.UUID, erro
 	return cred.ID, nil
 }
 
-// checkAccessToUpdate verifies that the user has permission to update the specified credential.
+// PushBatch creates or updates several credentials of one user and returns their IDs in input order.
+// The credentials to update are checked with a single query and everything is saved with batch statements, which
+// keeps large sync pushes fast. When several items share an ID, the last one wins.
+func (s *Service) PushBatch(ctx context.Context, params PushBatchParams) ([]uuid.UUID, error) {
+	creds := make([]*credential.Credential, 0, len(params.Items))
+	defer func() { securebytes.WipeAll(creds) }()
+	ids := make([]uuid.UUID, 0, len(params.Items))
+	// updateIDs collects the IDs of existing credentials the batch updates.
+	var updateIDs []uuid.UUID
+	for i, item := range params.Items {
+		cred, err := credential.NewCredential(credential.NewCredentialParams{
+			UserID:      params.UserID,
+			Login:       item.Login,
+			Password:    item.Password,
+			Description: item.Description,
+			URIs:        urisToDomain(item.URIs),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create credential at index %d: %w", i, mapError(err))
+		}
+		if item.ID != uuid.Nil {
+			cred.ID = item.ID
+			updateIDs = append(updateIDs, item.ID)
+		}
+		creds = append(creds, cred)
+		ids = append(ids, cred.ID)
+	}
+
+	if err := s.checkAccessToUpdateBatch(ctx, updateIDs, params.UserID); err != nil {
+		return nil, fmt.Errorf("access check for updating credentials failed: %w", err)
+	}
+	if len(updateIDs) < len(params.Items) {
+		if err := s.authorize(ctx, authz.ActionWrite, uuid.Nil, params.UserID, params.UserID); err != nil {
+			return nil, fmt.Errorf("access check for creating credentials failed: %w", err)
+		}
+		if err := s.checkPlan(ctx, params.UserID, len(params.Items)-len(updateIDs)); err != nil {
+			return nil, fmt.Errorf("plan check for creating credentials failed: %w", err)
+		}
+	}
+	if len(params.Items) != 0 {
+		passwords := make([]string, 0, len(params.Items))
+		for _, item := range params.Items {
+			passwords = append(passwords, item.Password)
+		}
+		if err := s.checkPolicy(ctx, params.UserID, passwords...); err != nil {
+			return nil, fmt.Errorf("item policy check for credentials failed: %w", err)
+		}
+	}
+
+	// latest holds the last credential pushed for every ID, since a statement cannot update a row twice.
+	latest := make([]*credential.Credential, 0, len(creds))
+	seen := make(map[uuid.UUID]struct{}, len(creds))
+	for _, cred := range slices.Backward(creds) {
+		if _, ok := seen[cred.ID]; ok {
+			continue
+		}
+		seen[cred.ID] = struct{}{}
+		latest = append(latest, cred)
+	}
+	slices.Reverse(latest)
+
+	if err := s.r.SaveBatch(ctx, repository.SaveBatchParams{Entities: latest, UserID: params.UserID}); err != nil {
+		return nil, fmt.Errorf("failed to save credentials: %w", mapError(err))
+	}
+	return ids, nil
+}
+
+// Recover makes the credential version current at the specified moment the latest version again.
+// The recovered version is saved as a new revision, so the replaced state stays retained.
+func (s *Service) Recover(ctx context.Context, params RecoverParams) (*Credential, error) {
+	creds, err := s.r.Load(ctx, repository.LoadParams{
+		AsOf:   params.AsOf,
+		ID:     params.ID,
+		UserID: params.UserID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load credential version: %w", mapError(err))
+	}
+	defer securebytes.WipeAll(creds)
+	if len(creds) == 0 {
+		return nil, fmt.Errorf("credential version not found: %w", ErrCredentialNotFound)
+	}
+	if err := s.authorize(ctx, authz.ActionWrite, creds[0].ID, creds[0].UserID, params.UserID); err != nil {
+		return nil, err
+	}
+
+	cred := creds[0]
+	cred.UpdatedAt = time.Now()
+	if err := s.r.Save(ctx, repository.SaveParams{Entity: cred}); err != nil {
+		return nil, fmt.Errorf("failed to save recovered credential: %w", mapError(err))
+	}
+	return newCredentialFromDomain(cred), nil
+}
+
+// Rotate replaces the password of the credential with the provided or a randomly generated one, keeping the
+// description and, unless a new one is provided, the login. The new secret is saved as a new revision, so the
+// replaced one stays retained in the item history.
+func (s *Service) Rotate(ctx context.Context, params RotateParams) error {
+	creds, err := s.r.Load(ctx, repository.LoadParams{ID: params.ID, UserID: params.UserID})
+	if err != nil {
+		return fmt.Errorf("failed to load credential: %w", mapError(err))
+	}
+	defer securebytes.WipeAll(creds)
+	if len(creds) == 0 {
+		return fmt.Errorf("credential for rotation not found: %w", ErrCredentialNotFound)
+	}
+	if err := s.authorize(ctx, authz.ActionWrite, creds[0].ID, creds[0].UserID, params.UserID); err != nil {
+		return err
+	}
+
+	cred := creds[0]
+	if params.Login != "" {
+		securebytes.Wipe(cred.Login)
+		cred.Login = []byte(params.Login)
+	}
+	securebytes.Wipe(cred.Password)
+	cred.Password = []byte(params.Password)
+	if params.Password == "" {
+		cred.Password = []byte(rand.Text())
+	}
+	cred.UpdatedAt = time.Now()
+	if err := s.r.Save(ctx, repository.SaveParams{Entity: cred}); err != nil {
+		return fmt.Errorf("failed to save rotated credential: %w", mapError(err))
+	}
+	return nil
+}
+
+// checkAccessToUpdate verifies that the credential to update exists and that the user may change it.
 func (s *Service) checkAccessToUpdate(ctx context.Context, credID, userID uuid.UUID) error {
-	exists, err := s.Pull(ctx, PullParams{ID: credID, UserID: userID})
+	existing, err := s.r.Load(ctx, repository.LoadParams{ID: credID, UserID: userID})
 	if err != nil {
-		if errors.Is(err, ErrCredentialNotFound) {
-			return fmt.Errorf("credential for update not found: %w", err)
-		}
 		return fmt.Errorf("failed to pull existing credential: %w", mapError(err))
 	}
-	if exists.UserID != userID {
-		return fmt.Errorf("access denied to credential: %w", ErrCredentialAccessDenied)
+	defer securebytes.WipeAll(existing)
+	if len(existing) == 0 {
+		return fmt.Errorf("credential for update not found: %w", ErrCredentialNotFound)
+	}
+	return s.authorize(ctx, authz.ActionWrite, existing[0].ID, existing[0].UserID, userID)
+}
+
+// checkAccessToUpdateBatch verifies with a single query that all credentials to update exist and that the user may
+// change them.
+func (s *Service) checkAccessToUpdateBatch(ctx context.Context, ids []uuid.UUID, userID uuid.UUID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	existing, err := s.r.Load(ctx, repository.LoadParams{IDs: ids, UserID: userID})
+	if err != nil {
+		return fmt.Errorf("failed to load existing credentials: %w", mapError(err))
+	}
+	defer securebytes.WipeAll(existing)
+	owned := make(map[uuid.UUID]struct{}, len(existing))
+	for _, e := range existing {
+		if err := s.authorize(ctx, authz.ActionWrite, e.ID, e.UserID, userID); err != nil {
+			return err
+		}
+		owned[e.ID] = struct{}{}
+	}
+	for _, id := range ids {
+		if _, ok := owned[id]; !ok {
+			return fmt.Errorf("credential %s for update not found: %w", id, ErrCredentialNotFound)
+		}
+	}
+	return nil
+}
+
+// checkPlan ensures that the plan of the user leaves room for the credentials being added.
+func (s *Service) checkPlan(ctx context.Context, userID uuid.UUID, added int) error {
+	if s.plans == nil {
+		return nil
+	}
+	return s.plans.CheckItems(ctx, userID, added)
+}
+
+// checkPolicy ensures that the item policies of the user allow storing credentials with the passwords.
+func (s *Service) checkPolicy(ctx context.Context, userID uuid.UUID, passwords ...string) error {
+	if s.policies == nil {
+		return nil
+	}
+	return s.policies.CheckCredential(ctx, userID, passwords...)
+}
+
+// authorize asks the authorization decision point whether the user may perform the action on a credential of the owner.
+// The credID is uuid.Nil for new credentials and for the whole collection.
+func (s *Service) authorize(ctx context.Context, action string, credID, ownerID, userID uuid.UUID) error {
+	err := s.authorizer.Authorize(ctx, authzApp.Request{
+		Action:     action,
+		Kind:       authz.KindCredential,
+		ResourceID: credID,
+		OwnerID:    ownerID,
+		SubjectID:  userID,
+	})
+	if err != nil {
+		return fmt.Errorf("access denied to credential %s: %w: %w", credID, ErrCredentialAccessDenied, err)
 	}
 	return nil
 }
@@ -1,13 +1,22 @@
 package credential
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"testing"
 	"time"
 
+	authzApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/authz"
+	itempolicyApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/itempolicy"
+	planApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/plan"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/authz"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/credential"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/itemorder"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/itemtag"
 	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/credential"
+	orderRepository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/itemorder"
+	tagRepository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/itemtag"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -15,8 +24,9 @@ import (
 
 // Mock repository for testing.
 type mockRepository struct {
-	saveFunc func(ctx context.Context, params repository.SaveParams) error
-	loadFunc func(ctx context.Context, params repository.LoadParams) ([]*credential.Credential, error)
+	saveFunc      func(ctx context.Context, params repository.SaveParams) error
+	saveBatchFunc func(ctx context.Context, params repository.SaveBatchParams) error
+	loadFunc      func(ctx context.Context, params repository.LoadParams) ([]*credential.Credential, error)
 }
 
 func (m *mockRepository) Save(ctx context.Context, params repository.SaveParams) error {
@@ -26,6 +36,13 @@ func (m *mockRepository) Save(ctx context.Context, params repository.SaveParams)
 	return nil
 }
 
+func (m *mockRepository) SaveBatch(ctx context.Context, params repository.SaveBatchParams) error {
+	if m.saveBatchFunc != nil {
+		return m.saveBatchFunc(ctx, params)
+	}
+	return nil
+}
+
 func (m *mockRepository) Load(
 	ctx context.Context,
 	params repository.LoadParams,
@@ -36,11 +53,56 @@ func (m *mockRepository) Load(
 	return nil, nil
 }
 
+// ownerAuthorizer permits users to act on their own credentials only, like the default authorization policy.
+type ownerAuthorizer struct{}
+
+func (ownerAuthorizer) Authorize(_ context.Context, req authzApp.Request) error {
+	if req.OwnerID != req.SubjectID {
+		return authzApp.ErrAccessDenied
+	}
+	return nil
+}
+
+// denyAuthorizer denies every request and records the requests decided.
+type denyAuthorizer struct {
+	reqs []authzApp.Request
+}
+
+func (m *denyAuthorizer) Authorize(_ context.Context, req authzApp.Request) error {
+	m.reqs = append(m.reqs, req)
+	return authzApp.ErrAccessDenied
+}
+
+// mockPlanEnforcer implements PlanEnforcer for testing, allowing a fixed number of new items.
+type mockPlanEnforcer struct {
+	added []int
+	left  int
+}
+
+func (m *mockPlanEnforcer) CheckItems(_ context.Context, _ uuid.UUID, added int) error {
+	m.added = append(m.added, added)
+	if added > m.left {
+		return planApp.ErrPlanItemLimitReached
+	}
+	return nil
+}
+
+// mockPolicyEnforcer implements PolicyEnforcer for testing, recording the checked passwords.
+type mockPolicyEnforcer struct {
+	err   error
+	calls [][]string
+}
+
+func (m *mockPolicyEnforcer) CheckCredential(_ context.Context, _ uuid.UUID, passwords ...string) error {
+	m.calls = append(m.calls, passwords)
+	return m.err
+}
+
 func TestNewService(t *testing.T) {
 	t.Parallel()
 
 	repo := &mockRepository{}
-	service := NewService(repo)
+	service := NewService(repo, ownerAuthorizer{}, nil, nil, nil, nil)
 
 	require.NotNil(t, service)
 	assert.Equal(t, repo, service.r)
@@ -133,7 +195,7 @@ func TestService_Pull(t *testing.T) {
 				tt.setupMock(repo)
 			}
 
-			service := NewService(repo)
+			service := NewService(repo, ownerAuthorizer{}, nil, nil, nil, nil)
 			cred, err := service.Pull(context.Background(), tt.args.params)
 
 			if tt.wantErr {
@@ -207,6 +269,25 @@ func TestService_List(t *testing.T) {
 			expectedCount: 2,
 			wantErr:       false,
 		},
+		{
+			name: "selected_fields_forwarded",
+			args: args{
+				params: ListParams{
+					Fields: []string{"login"},
+					UserID: testUserID,
+				},
+			},
+			setupMock: func(repo *mockRepository) {
+				repo.loadFunc = func(ctx context.Context, params repository.LoadParams) ([]*credential.Credential, error) {
+					if len(params.Fields) != 1 || params.Fields[0] != "login" {
+						return nil, errors.New("unexpected fields")
+					}
+					return testCreds, nil
+				}
+			},
+			expectedCount: 2,
+			wantErr:       false,
+		},
 		{
 			name: "empty_list",
 			args: args{
@@ -249,7 +330,7 @@ func TestService_List(t *testing.T) {
 				tt.setupMock(repo)
 			}
 
-			service := NewService(repo)
+			service := NewService(repo, ownerAuthorizer{}, nil, nil, nil, nil)
 			creds, err := service.List(context.Background(), tt.args.params)
 
 			if tt.wantErr {
@@ -393,7 +474,7 @@ func TestService_Push(t *testing.T) {
 				tt.setupMock(repo)
 			}
 
-			service := NewService(repo)
+			service := NewService(repo, ownerAuthorizer{}, nil, nil, nil, nil)
 			credID, err := service.Push(context.Background(), tt.args.params)
 
 			if tt.wantErr {
@@ -496,7 +577,7 @@ func TestService_checkAccessToUpdate(t *testing.T) {
 				tt.setupMock(repo)
 			}
 
-			service := NewService(repo)
+			service := NewService(repo, ownerAuthorizer{}, nil, nil, nil, nil)
 			err := service.checkAccessToUpdate(context.Background(), tt.credID, tt.userID)
 
 			if tt.wantErr {
@@ -510,3 +591,780 @@ func TestService_checkAccessToUpdate(t *testing.T) {
 		})
 	}
 }
+
+func TestService_Recover(t *testing.T) {
+	t.Parallel()
+
+	testID := uuid.New()
+	testUserID := uuid.New()
+	asOf := time.Now().Add(-time.Hour)
+
+	version := &credential.Credential{
+		ID:          testID,
+		UserID:      testUserID,
+		Login:       []byte("previous-login"),
+		Password:    []byte("previous-password"),
+		Description: []byte("previous description"),
+		UpdatedAt:   asOf.Add(-time.Minute),
+	}
+
+	tests := []struct {
+		loadErr     error
+		saveErr     error
+		name        string
+		wantErrText string
+		versions    []*credential.Credential
+		wantErr     bool
+	}{
+		{
+			name:     "success/version_saved_as_latest",
+			versions: []*credential.Credential{version},
+		},
+		{
+			name:        "error/version_not_found",
+			versions:    []*credential.Credential{},
+			wantErr:     true,
+			wantErrText: "credential version not found",
+		},
+		{
+			name:        "error/load_failed",
+			loadErr:     errors.New("database error"),
+			wantErr:     true,
+			wantErrText: "failed to load credential version",
+		},
+		{
+			name:        "error/save_failed",
+			versions:    []*credential.Credential{version},
+			saveErr:     errors.New("database error"),
+			wantErr:     true,
+			wantErrText: "failed to save recovered credential",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var saved *credential.Credential
+			repo := &mockRepository{
+				loadFunc: func(_ context.Context, params repository.LoadParams) ([]*credential.Credential, error) {
+					assert.Equal(t, asOf, params.AsOf)
+					assert.Equal(t, testID, params.ID)
+					assert.Equal(t, testUserID, params.UserID)
+					if tt.loadErr != nil {
+						return nil, tt.loadErr
+					}
+					cloned := make([]*credential.Credential, 0, len(tt.versions))
+					for _, v := range tt.versions {
+						c := *v
+						cloned = append(cloned, &c)
+					}
+					return cloned, nil
+				},
+				saveFunc: func(_ context.Context, params repository.SaveParams) error {
+					saved = params.Entity
+					return tt.saveErr
+				},
+			}
+
+			got, err := NewService(repo, ownerAuthorizer{}, nil, nil, nil, nil).Recover(context.Background(), RecoverParams{
+				AsOf:   asOf,
+				ID:     testID,
+				UserID: testUserID,
+			})
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErrText)
+				assert.Nil(t, got)
+				return
+			}
+			require.NoError(t, err)
+			require.NotNil(t, saved)
+			assert.Equal(t, testID, got.ID)
+			assert.True(t, saved.UpdatedAt.After(version.UpdatedAt))
+			assert.Equal(t, saved.UpdatedAt, got.UpdatedAt)
+		})
+	}
+}
+
+func TestService_Rotate(t *testing.T) {
+	t.Parallel()
+
+	testID := uuid.New()
+	testUserID := uuid.New()
+
+	tests := []struct {
+		loadErr      error
+		saveErr      error
+		params       RotateParams
+		name         string
+		wantErrText  string
+		wantLogin    string
+		wantPassword string
+		found        bool
+		wantErr      bool
+	}{
+		{
+			name:      "success/password_generated",
+			found:     true,
+			wantLogin: "admin",
+		},
+		{
+			name:         "success/secret_replaced",
+			params:       RotateParams{Login: "AKIANEW", Password: "new-secret"},
+			found:        true,
+			wantLogin:    "AKIANEW",
+			wantPassword: "new-secret",
+		},
+		{
+			name:        "error/credential_not_found",
+			wantErr:     true,
+			wantErrText: "credential for rotation not found",
+		},
+		{
+			name:        "error/load_failed",
+			loadErr:     errors.New("database error"),
+			wantErr:     true,
+			wantErrText: "failed to load credential",
+		},
+		{
+			name:        "error/save_failed",
+			found:       true,
+			saveErr:     errors.New("database error"),
+			wantErr:     true,
+			wantErrText: "failed to save rotated credential",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var saved credential.Credential
+			repo := &mockRepository{
+				loadFunc: func(_ context.Context, params repository.LoadParams) ([]*credential.Credential, error) {
+					assert.Equal(t, testID, params.ID)
+					assert.Equal(t, testUserID, params.UserID)
+					if tt.loadErr != nil || !tt.found {
+						return nil, tt.loadErr
+					}
+					return []*credential.Credential{{
+						ID:          testID,
+						UserID:      testUserID,
+						Login:       []byte("admin"),
+						Password:    []byte("shared-secret"),
+						Description: []byte("Router"),
+					}}, nil
+				},
+				saveFunc: func(_ context.Context, params repository.SaveParams) error {
+					saved = *params.Entity
+					saved.Login = bytes.Clone(params.Entity.Login)
+					saved.Password = bytes.Clone(params.Entity.Password)
+					return tt.saveErr
+				},
+			}
+
+			params := tt.params
+			params.ID, params.UserID = testID, testUserID
+			err := NewService(repo, ownerAuthorizer{}, nil, nil, nil, nil).Rotate(context.Background(), params)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErrText)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, testID, saved.ID)
+			assert.Equal(t, tt.wantLogin, string(saved.Login))
+			assert.NotEqual(t, "shared-secret", string(saved.Password))
+			if tt.wantPassword != "" {
+				assert.Equal(t, tt.wantPassword, string(saved.Password))
+			} else {
+				assert.Len(t, saved.Password, 26)
+			}
+			assert.False(t, saved.UpdatedAt.IsZero())
+		})
+	}
+}
+
+func TestService_PushBatch(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	existingID := uuid.New()
+	saveErr := errors.New("save failed")
+	cred := func(id uuid.UUID) *PushParams {
+		return &PushParams{ID: id, Login: "user", Password: "secret"}
+	}
+
+	tests := []struct {
+		saveErr       error
+		wantErrIs     error
+		name          string
+		items         []*PushParams
+		existing      []*credential.Credential
+		wantLoadIDs   []uuid.UUID
+		wantSaved     int
+		wantErr       bool
+		wantSaveCalls int
+	}{
+		{
+			name:          "creates and updates with last item winning",
+			items:         []*PushParams{cred(uuid.Nil), cred(existingID), cred(existingID)},
+			existing:      []*credential.Credential{{ID: existingID, UserID: userID}},
+			wantLoadIDs:   []uuid.UUID{existingID, existingID},
+			wantSaved:     2,
+			wantSaveCalls: 1,
+		},
+		{
+			name:      "update of missing item",
+			items:     []*PushParams{cred(existingID)},
+			wantErr:   true,
+			wantErrIs: ErrCredentialNotFound,
+		},
+		{
+			name:      "update of item of another user",
+			items:     []*PushParams{cred(existingID)},
+			existing:  []*credential.Credential{{ID: existingID, UserID: uuid.New()}},
+			wantErr:   true,
+			wantErrIs: ErrCredentialAccessDenied,
+		},
+		{
+			name:    "invalid item",
+			items:   []*PushParams{{Login: ""}},
+			wantErr: true,
+		},
+		{
+			name:          "save error",
+			items:         []*PushParams{cred(uuid.Nil)},
+			saveErr:       saveErr,
+			wantSaveCalls: 1,
+			wantSaved:     1,
+			wantErr:       true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			saveCalls := 0
+			repo := &mockRepository{
+				loadFunc: func(_ context.Context, params repository.LoadParams) ([]*credential.Credential, error) {
+					assert.Equal(t, userID, params.UserID)
+					if tt.wantLoadIDs != nil {
+						assert.Equal(t, tt.wantLoadIDs, params.IDs)
+					}
+					return tt.existing, nil
+				},
+				saveBatchFunc: func(_ context.Context, params repository.SaveBatchParams) error {
+					saveCalls++
+					assert.Equal(t, userID, params.UserID)
+					assert.Len(t, params.Entities, tt.wantSaved)
+					for _, e := range params.Entities {
+						assert.Equal(t, userID, e.UserID)
+					}
+					return tt.saveErr
+				},
+			}
+
+			ids, err := NewService(repo, ownerAuthorizer{}, nil, nil, nil, nil).PushBatch(
+				context.Background(),
+				PushBatchParams{Items: tt.items, UserID: userID},
+			)
+
+			assert.Equal(t, tt.wantSaveCalls, saveCalls)
+			if tt.wantErr {
+				require.Error(t, err)
+				if tt.wantErrIs != nil {
+					require.ErrorIs(t, err, tt.wantErrIs)
+				}
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, ids, len(tt.items))
+			for i, item := range tt.items {
+				if item.ID != uuid.Nil {
+					assert.Equal(t, item.ID, ids[i])
+				} else {
+					assert.NotEqual(t, uuid.Nil, ids[i])
+				}
+			}
+		})
+	}
+}
+
+func TestService_PlanLimits(t *testing.T) {
+	t.Parallel()
+
+	userID, existingID := uuid.New(), uuid.New()
+	repo := &mockRepository{
+		loadFunc: func(context.Context, repository.LoadParams) ([]*credential.Credential, error) {
+			return []*credential.Credential{{ID: existingID, UserID: userID}}, nil
+		},
+	}
+	item := func(id uuid.UUID) *PushParams {
+		return &PushParams{ID: id, UserID: userID, Login: "user", Password: "secret"}
+	}
+
+	tests := []struct {
+		call      func(s *Service) error
+		wantErr   error
+		name      string
+		wantAdded []int
+		left      int
+	}{
+		{
+			name: "create within limit",
+			call: func(s *Service) error {
+				_, err := s.Push(context.Background(), item(uuid.Nil))
+				return err
+			},
+			left:      1,
+			wantAdded: []int{1},
+		},
+		{
+			name: "create over limit",
+			call: func(s *Service) error {
+				_, err := s.Push(context.Background(), item(uuid.Nil))
+				return err
+			},
+			wantErr:   planApp.ErrPlanUpgradeRequired,
+			wantAdded: []int{1},
+		},
+		{
+			name: "update over limit",
+			call: func(s *Service) error {
+				_, err := s.Push(context.Background(), item(existingID))
+				return err
+			},
+		},
+		{
+			name: "batch counts new items only",
+			call: func(s *Service) error {
+				_, err := s.PushBatch(context.Background(), PushBatchParams{
+					Items:  []*PushParams{item(uuid.Nil), item(existingID), item(uuid.Nil)},
+					UserID: userID,
+				})
+				return err
+			},
+			left:      1,
+			wantErr:   planApp.ErrPlanItemLimitReached,
+			wantAdded: []int{2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			plans := &mockPlanEnforcer{left: tt.left}
+
+			err := tt.call(NewService(repo, ownerAuthorizer{}, plans, nil, nil, nil))
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantAdded, plans.added)
+		})
+	}
+}
+
+func TestService_ItemPolicies(t *testing.T) {
+	t.Parallel()
+
+	userID, existingID := uuid.New(), uuid.New()
+	repo := &mockRepository{
+		loadFunc: func(context.Context, repository.LoadParams) ([]*credential.Credential, error) {
+			return []*credential.Credential{{ID: existingID, UserID: userID}}, nil
+		},
+	}
+	item := func(id uuid.UUID, password string) *PushParams {
+		return &PushParams{ID: id, UserID: userID, Login: "user", Password: password}
+	}
+
+	tests := []struct {
+		call      func(s *Service) error
+		policyErr error
+		name      string
+		wantCalls [][]string
+	}{
+		{
+			name: "create checked",
+			call: func(s *Service) error {
+				_, err := s.Push(context.Background(), item(uuid.Nil, "secret"))
+				return err
+			},
+			wantCalls: [][]string{{"secret"}},
+		},
+		{
+			name: "update rejected",
+			call: func(s *Service) error {
+				_, err := s.Push(context.Background(), item(existingID, "weak"))
+				return err
+			},
+			policyErr: itempolicyApp.ErrWeakPassword,
+			wantCalls: [][]string{{"weak"}},
+		},
+		{
+			name: "batch checks every password at once",
+			call: func(s *Service) error {
+				_, err := s.PushBatch(context.Background(), PushBatchParams{
+					Items:  []*PushParams{item(uuid.Nil, "first"), item(existingID, "second")},
+					UserID: userID,
+				})
+				return err
+			},
+			policyErr: itempolicyApp.ErrItemKindForbidden,
+			wantCalls: [][]string{{"first", "second"}},
+		},
+		{
+			name: "empty batch not checked",
+			call: func(s *Service) error {
+				_, err := s.PushBatch(context.Background(), PushBatchParams{UserID: userID})
+				return err
+			},
+			policyErr: itempolicyApp.ErrItemKindForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			policies := &mockPolicyEnforcer{err: tt.policyErr}
+
+			err := tt.call(NewService(repo, ownerAuthorizer{}, nil, policies, nil, nil))
+
+			if tt.policyErr != nil && len(tt.wantCalls) != 0 {
+				require.ErrorIs(t, err, tt.policyErr)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantCalls, policies.calls)
+		})
+	}
+}
+
+func TestService_Authorization(t *testing.T) {
+	t.Parallel()
+
+	userID, itemID := uuid.New(), uuid.New()
+	request := func(action string, resourceID uuid.UUID) authzApp.Request {
+		return authzApp.Request{
+			Action:     action,
+			Kind:       authz.KindCredential,
+			ResourceID: resourceID,
+			OwnerID:    userID,
+			SubjectID:  userID,
+		}
+	}
+
+	tests := []struct {
+		call    func(s *Service) error
+		name    string
+		wantReq authzApp.Request
+	}{
+		{
+			name: "pull",
+			call: func(s *Service) error {
+				_, err := s.Pull(context.Background(), PullParams{ID: itemID, UserID: userID})
+				return err
+			},
+			wantReq: request(authz.ActionRead, itemID),
+		},
+		{
+			name: "list",
+			call: func(s *Service) error {
+				_, err := s.List(context.Background(), ListParams{UserID: userID})
+				return err
+			},
+			wantReq: request(authz.ActionRead, uuid.Nil),
+		},
+		{
+			name: "create",
+			call: func(s *Service) error {
+				_, err := s.Push(context.Background(), &PushParams{Login: "login", Password: "password", UserID: userID})
+				return err
+			},
+			wantReq: request(authz.ActionWrite, uuid.Nil),
+		},
+		{
+			name: "update",
+			call: func(s *Service) error {
+				_, err := s.Push(context.Background(), &PushParams{
+					Login:    "login",
+					Password: "password",
+					ID:       itemID,
+					UserID:   userID,
+				})
+				return err
+			},
+			wantReq: request(authz.ActionWrite, itemID),
+		},
+		{
+			name: "recover",
+			call: func(s *Service) error {
+				_, err := s.Recover(context.Background(), RecoverParams{AsOf: time.Now(), ID: itemID, UserID: userID})
+				return err
+			},
+			wantReq: request(authz.ActionWrite, itemID),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &mockRepository{
+				loadFunc: func(context.Context, repository.LoadParams) ([]*credential.Credential, error) {
+					return []*credential.Credential{{ID: itemID, UserID: userID}}, nil
+				},
+			}
+			authorizer := &denyAuthorizer{}
+
+			err := tt.call(NewService(repo, authorizer, nil, nil, nil, nil))
+
+			require.ErrorIs(t, err, ErrCredentialAccessDenied)
+			require.ErrorIs(t, err, authzApp.ErrAccessDenied)
+			assert.Equal(t, []authzApp.Request{tt.wantReq}, authorizer.reqs)
+		})
+	}
+}
+
+// mockTagRepository implements TagRepository for testing.
+type mockTagRepository struct {
+	err  error
+	tags []*itemtag.ItemTags
+}
+
+func (m *mockTagRepository) Load(_ context.Context, params tagRepository.LoadParams) ([]*itemtag.ItemTags, error) {
+	if params.Tag != itemtag.Archived {
+		return nil, errors.New("unexpected tag")
+	}
+	return m.tags, m.err
+}
+
+func TestService_ListArchived(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	activeID, archivedID := uuid.New(), uuid.New()
+	archived := []*itemtag.ItemTags{{ItemID: archivedID, UserID: userID, Tags: []string{itemtag.Archived}}}
+
+	tests := []struct {
+		tags    TagRepository
+		wantErr error
+		want    map[uuid.UUID]bool
+		name    string
+		exclude bool
+	}{
+		{
+			name: "archived items marked",
+			tags: &mockTagRepository{tags: archived},
+			want: map[uuid.UUID]bool{activeID: false, archivedID: true},
+		},
+		{
+			name:    "archived items excluded",
+			tags:    &mockTagRepository{tags: archived},
+			exclude: true,
+			want:    map[uuid.UUID]bool{activeID: false},
+		},
+		{
+			name:    "nothing archived without tags",
+			exclude: true,
+			want:    map[uuid.UUID]bool{activeID: false, archivedID: false},
+		},
+		{
+			name:    "tag lookup error",
+			tags:    &mockTagRepository{err: errors.New("connection refused")},
+			wantErr: ErrCredentialTechError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &mockRepository{loadFunc: func(context.Context, repository.LoadParams) ([]*credential.Credential, error) {
+				return []*credential.Credential{{ID: activeID, UserID: userID}, {ID: archivedID, UserID: userID}}, nil
+			}}
+			service := NewService(repo, ownerAuthorizer{}, nil, nil, tt.tags, nil)
+
+			got, err := service.List(context.Background(), ListParams{UserID: userID, ExcludeArchived: tt.exclude})
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			listed := make(map[uuid.UUID]bool, len(got))
+			for _, item := range got {
+				listed[item.ID] = item.Archived
+			}
+			assert.Equal(t, tt.want, listed)
+		})
+	}
+}
+
+// mockOrderRepository implements OrderRepository for testing.
+type mockOrderRepository struct {
+	err    error
+	order  *itemorder.ItemOrder
+	folder string
+}
+
+func (m *mockOrderRepository) Load(_ context.Context, params orderRepository.LoadParams) (*itemorder.ItemOrder, error) {
+	m.folder = params.Folder
+	if m.order == nil && m.err == nil {
+		return nil, orderRepository.ErrOrderNotFound
+	}
+	return m.order, m.err
+}
+
+func TestService_ListManualOrder(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	first, second, third := uuid.New(), uuid.New(), uuid.New()
+
+	tests := []struct {
+		orders  *mockOrderRepository
+		wantErr error
+		name    string
+		want    []uuid.UUID
+		manual  bool
+	}{
+		{
+			name: "manual order applied",
+			orders: &mockOrderRepository{order: &itemorder.ItemOrder{
+				Entries: []itemorder.Entry{{ItemID: third, Pinned: true}, {ItemID: first}},
+			}},
+			manual: true,
+			want:   []uuid.UUID{third, first, second},
+		},
+		{
+			name: "default order without sort",
+			orders: &mockOrderRepository{order: &itemorder.ItemOrder{
+				Entries: []itemorder.Entry{{ItemID: third, Pinned: true}},
+			}},
+			want: []uuid.UUID{first, second, third},
+		},
+		{
+			name:   "folder without order",
+			orders: &mockOrderRepository{},
+			manual: true,
+			want:   []uuid.UUID{first, second, third},
+		},
+		{
+			name:    "order lookup error",
+			orders:  &mockOrderRepository{err: errors.New("connection refused")},
+			manual:  true,
+			wantErr: ErrCredentialTechError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &mockRepository{loadFunc: func(context.Context, repository.LoadParams) ([]*credential.Credential, error) {
+				return []*credential.Credential{
+					{ID: first, UserID: userID}, {ID: second, UserID: userID}, {ID: third, UserID: userID},
+				}, nil
+			}}
+			service := NewService(repo, ownerAuthorizer{}, nil, nil, nil, tt.orders)
+
+			got, err := service.List(context.Background(), ListParams{
+				OrderFolder: "/prod/",
+				UserID:      userID,
+				ManualOrder: tt.manual,
+			})
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			listed := make([]uuid.UUID, 0, len(got))
+			for _, item := range got {
+				listed = append(listed, item.ID)
+			}
+			assert.Equal(t, tt.want, listed)
+			if tt.manual {
+				assert.Equal(t, "prod", tt.orders.folder)
+			}
+		})
+	}
+}
+
+func TestService_Match(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	site, archivedSite, other := uuid.New(), uuid.New(), uuid.New()
+
+	// stored creates a credential of the user with the URIs.
+	stored := func(id uuid.UUID, uris ...credential.URI) *credential.Credential {
+		c, err := credential.NewCredential(credential.NewCredentialParams{
+			Login:    "admin",
+			Password: "pass",
+			URIs:     uris,
+			UserID:   userID,
+		})
+		require.NoError(t, err)
+		c.ID = id
+		return c
+	}
+
+	tests := []struct {
+		loadErr error
+		wantErr error
+		name    string
+		uri     string
+		want    []uuid.UUID
+	}{
+		{name: "matching credentials", uri: "https://login.example.com/signin", want: []uuid.UUID{site}},
+		{name: "no matching credentials", uri: "https://example.net", want: []uuid.UUID{}},
+		{name: "URI without host", uri: "https://", wantErr: ErrCredentialIncorrectURI},
+		{
+			name:    "repository failure",
+			uri:     "https://example.com",
+			loadErr: errors.New("connection refused"),
+			wantErr: ErrCredentialTechError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &mockRepository{loadFunc: func(context.Context, repository.LoadParams) ([]*credential.Credential, error) {
+				return []*credential.Credential{
+					stored(site, credential.URI{Value: "example.com"}),
+					stored(archivedSite, credential.URI{Value: "https://example.com", Match: credential.MatchHost}),
+					stored(other, credential.URI{Value: "example.com", Match: credential.MatchNever}),
+				}, tt.loadErr
+			}}
+			tags := &mockTagRepository{tags: []*itemtag.ItemTags{{ItemID: archivedSite, Tags: []string{itemtag.Archived}}}}
+			service := NewService(repo, ownerAuthorizer{}, nil, nil, tags, nil)
+
+			got, err := service.Match(context.Background(), MatchParams{URI: tt.uri, UserID: userID})
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			matched := make([]uuid.UUID, 0, len(got))
+			for _, item := range got {
+				matched = append(matched, item.ID)
+				assert.Equal(t, []URI{{Value: "example.com", Match: "base_domain"}}, item.URIs)
+			}
+			assert.Equal(t, tt.want, matched)
+		})
+	}
+}
@@ -3,37 +3,47 @@ package datasync
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/bankcard"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/credential"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/filedata"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/note"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/authz"
 	"github.com/google/uuid"
 )
 
 // BankCardService defines operations for synchronizing bank card data.
 type BankCardService interface {
+	Pull(ctx context.Context, params bankcard.PullParams) (*bankcard.BankCard, error)
+
 	List(ctx context.Context, params bankcard.ListParams) ([]*bankcard.BankCard, error)
 
-	Push(ctx context.Context, params *bankcard.PushParams) (uuid.UUID, error)
+	PushBatch(ctx context.Context, params bankcard.PushBatchParams) ([]uuid.UUID, error)
 }
 
 // CredentialService defines operations for synchronizing credential data.
 type CredentialService interface {
+	Pull(ctx context.Context, params credential.PullParams) (*credential.Credential, error)
+
 	List(ctx context.Context, params credential.ListParams) ([]*credential.Credential, error)
 
-	Push(ctx context.Context, params *credential.PushParams) (uuid.UUID, error)
+	PushBatch(ctx context.Context, params credential.PushBatchParams) ([]uuid.UUID, error)
 }
 
 // NoteService defines operations for synchronizing note data.
 type NoteService interface {
+	Pull(ctx context.Context, params note.PullParams) (*note.Note, error)
+
 	List(ctx context.Context, params note.ListParams) ([]*note.Note, error)
 
-	Push(ctx context.Context, params *note.PushParams) (uuid.UUID, error)
+	PushBatch(ctx context.Context, params note.PushBatchParams) ([]uuid.UUID, error)
 }
 
 // FileDataService defines operations for synchronizing file data.
 type FileDataService interface {
+	Pull(ctx context.Context, params filedata.PullParams) (*filedata.FileData, error)
+
 	List(ctx context.Context, params filedata.ListParams) ([]*filedata.FileData, error)
 
 	Push(ctx context.Context, params *filedata.PushParams) (uuid.UUID, error)
@@ -71,42 +81,153 @@ func (a *ServicesAggregator) PullBankCards(
 	ctx context.Context,
 	userID uuid.UUID,
 ) ([]*bankcard.BankCard, error) {
-	bankCards, err := a.bankcardService.List(ctx, bankcard.ListParams{UserID: userID})
+	return a.PullBankCardsAsOf(ctx, userID, time.Time{})
+}
+
+// PullBankCardsAsOf retrieves the bank cards versions of the specified user current at the given moment.
+// A zero moment selects the current versions.
+func (a *ServicesAggregator) PullBankCardsAsOf(
+	ctx context.Context,
+	userID uuid.UUID,
+	asOf time.Time,
+) ([]*bankcard.BankCard, error) {
+	bankCards, err := a.bankcardService.List(ctx, bankcard.ListParams{AsOf: asOf, UserID: userID})
 	if err != nil {
 		return nil, fmt.Errorf("failed to pull bank cards: %w", err)
 	}
 	return bankCards, nil
 }
 
+// PullBankCardsPage retrieves up to limit bank cards of the specified user whose IDs sort after the given one,
+// ordered by ID. A zero ID starts at the first card.
+func (a *ServicesAggregator) PullBankCardsPage(
+	ctx context.Context,
+	userID, after uuid.UUID,
+	limit int,
+) ([]*bankcard.BankCard, error) {
+	bankCards, err := a.bankcardService.List(ctx, bankcard.ListParams{After: after, Limit: limit, UserID: userID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to pull bank cards page: %w", err)
+	}
+	return bankCards, nil
+}
+
 // PullCredentials retrieves all credentials for the specified user.
 func (a *ServicesAggregator) PullCredentials(
 	ctx context.Context,
 	userID uuid.UUID,
 ) ([]*credential.Credential, error) {
-	credentials, err := a.credentialService.List(ctx, credential.ListParams{UserID: userID})
+	return a.PullCredentialsAsOf(ctx, userID, time.Time{})
+}
+
+// PullCredentialsAsOf retrieves the credentials versions of the specified user current at the given moment.
+// A zero moment selects the current versions.
+func (a *ServicesAggregator) PullCredentialsAsOf(
+	ctx context.Context,
+	userID uuid.UUID,
+	asOf time.Time,
+) ([]*credential.Credential, error) {
+	credentials, err := a.credentialService.List(ctx, credential.ListParams{AsOf: asOf, UserID: userID})
 	if err != nil {
 		return nil, fmt.Errorf("failed to pull credentials: %w", err)
 	}
 	return credentials, nil
 }
 
+// PullCredentialsPage retrieves up to limit credentials of the specified user whose IDs sort after the given one,
+// ordered by ID. A zero ID starts at the first credential.
+func (a *ServicesAggregator) PullCredentialsPage(
+	ctx context.Context,
+	userID, after uuid.UUID,
+	limit int,
+) ([]*credential.Credential, error) {
+	credentials, err := a.credentialService.List(ctx, credential.ListParams{After: after, Limit: limit, UserID: userID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to pull credentials page: %w", err)
+	}
+	return credentials, nil
+}
+
 // PullNotes retrieves all notes for the specified user.
-func (a *ServicesAggregator) PullNotes(ctx context.Context, userID uuid.UUID) ([]*note.Note, error) {
-	notes, err := a.noteService.List(ctx, note.ListParams{UserID: userID})
+func (a *ServicesAggregator) PullNotes(
+	ctx context.Context,
+	userID uuid.UUID,
+) ([]*note.Note, error) {
+	return a.PullNotesAsOf(ctx, userID, time.Time{})
+}
+
+// PullNotesAsOf retrieves the notes versions of the specified user current at the given moment.
+// A zero moment selects the current versions.
+func (a *ServicesAggregator) PullNotesAsOf(
+	ctx context.Context,
+	userID uuid.UUID,
+	asOf time.Time,
+) ([]*note.Note, error) {
+	notes, err := a.noteService.List(ctx, note.ListParams{AsOf: asOf, UserID: userID})
 	if err != nil {
 		return nil, fmt.Errorf("failed to pull notes: %w", err)
 	}
 	return notes, nil
 }
 
-// PushBankCards synchronizes bank card data to the server for the specified user.
+// PullNotesPage retrieves up to limit notes of the specified user whose IDs sort after the given one,
+// ordered by ID. A zero ID starts at the first note.
+func (a *ServicesAggregator) PullNotesPage(
+	ctx context.Context,
+	userID, after uuid.UUID,
+	limit int,
+) ([]*note.Note, error) {
+	notes, err := a.noteService.List(ctx, note.ListParams{After: after, Limit: limit, UserID: userID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to pull notes page: %w", err)
+	}
+	return notes, nil
+}
+
+// ItemVersion returns the version of the current revision of the bank card, credential or note of the user.
+// Only the modification time is loaded, so no encrypted field is decrypted.
+func (a *ServicesAggregator) ItemVersion(ctx context.Context, kind string, userID, id uuid.UUID) (int64, error) {
+	// updatedAt holds the modification time of the current revision; err holds the lookup failure.
+	var (
+		updatedAt time.Time
+		err       error
+	)
+	fields := []string{"updated_at"}
+	switch kind {
+	case authz.KindBankCard:
+		var card *bankcard.BankCard
+		if card, err = a.bankcardService.Pull(ctx, bankcard.PullParams{Fields: fields, ID: id, UserID: userID}); err == nil {
+			updatedAt = card.UpdatedAt
+		}
+	case authz.KindCredential:
+		var cred *credential.Credential
+		if cred, err = a.credentialService.Pull(
+			ctx, credential.PullParams{Fields: fields, ID: id, UserID: userID},
+		); err == nil {
+			updatedAt = cred.UpdatedAt
+		}
+	default:
+		var n *note.Note
+		if n, err = a.noteService.Pull(ctx, note.PullParams{Fields: fields, ID: id, UserID: userID}); err == nil {
+			updatedAt = n.UpdatedAt
+		}
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to pull %s %s: %w", kind, id, err)
+	}
+	return version(updatedAt), nil
+}
+
+// PushBankCards synchronizes bank card data to the server for the specified user in a single batch
+// and returns the card IDs in order.
 func (a *ServicesAggregator) PushBankCards(
 	ctx context.Context,
 	userID uuid.UUID,
 	cards []*bankcard.BankCard,
-) error {
-	for _, card := range cards {
-		_, err := a.bankcardService.Push(ctx, &bankcard.PushParams{
+) ([]uuid.UUID, error) {
+	items := make([]*bankcard.PushParams, len(cards))
+	for i, card := range cards {
+		items[i] = &bankcard.PushParams{
 			ID:          card.ID,
 			UserID:      userID,
 			CardNumber:  card.CardNumber,
@@ -115,49 +236,57 @@ func (a *ServicesAggregator) PushBankCards(
 			ExpiryYear:  card.ExpiryYear,
 			CVV:         card.CVV,
 			Description: card.Description,
-		})
-		if err != nil {
-			return fmt.Errorf("failed to push bank card with ID %s: %w", card.ID, err)
 		}
 	}
-	return nil
+	ids, err := a.bankcardService.PushBatch(ctx, bankcard.PushBatchParams{Items: items, UserID: userID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to push bank cards: %w", err)
+	}
+	return ids, nil
 }
 
-// PushCredentials synchronizes credential data to the server for the specified user.
+// PushCredentials synchronizes credential data to the server for the specified user in a single batch
+// and returns the credential IDs in order.
 func (a *ServicesAggregator) PushCredentials(
 	ctx context.Context,
 	userID uuid.UUID,
 	credentials []*credential.Credential,
-) error {
-	for _, cred := range credentials {
-		_, err := a.credentialService.Push(ctx, &credential.PushParams{
+) ([]uuid.UUID, error) {
+	items := make([]*credential.PushParams, len(credentials))
+	for i, cred := range credentials {
+		items[i] = &credential.PushParams{
 			ID:          cred.ID,
 			UserID:      userID,
 			Login:       cred.Login,
 			Password:    cred.Password,
 			Description: cred.Description,
-		})
-		if err != nil {
-			return fmt.Errorf("failed to push credential with ID %s: %w", cred.ID, err)
+			URIs:        cred.URIs,
 		}
 	}
-	return nil
+	ids, err := a.credentialService.PushBatch(ctx, credential.PushBatchParams{Items: items, UserID: userID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to push credentials: %w", err)
+	}
+	return ids, nil
 }
 
-// PushNotes synchronizes note data to the server for the specified user.
-func (a *ServicesAggregator) PushNotes(ctx context.Context, userID uuid.UUID, notes []*note.Note) error {
-	for _, n := range notes {
-		_, err := a.noteService.Push(ctx, &note.PushParams{
+// PushNotes synchronizes note data to the server for the specified user in a single batch
+// and returns the note IDs in order.
+func (a *ServicesAggregator) PushNotes(ctx context.Context, userID uuid.UUID, notes []*note.Note) ([]uuid.UUID, error) {
+	items := make([]*note.PushParams, len(notes))
+	for i, n := range notes {
+		items[i] = &note.PushParams{
 			ID:          n.ID,
 			UserID:      userID,
 			Note:        n.Note,
 			Description: n.Description,
-		})
-		if err != nil {
-			return fmt.Errorf("failed to push note with ID %s: %w", n.ID, err)
 		}
 	}
-	return nil
+	ids, err := a.noteService.PushBatch(ctx, note.PushBatchParams{Items: items, UserID: userID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to push notes: %w", err)
+	}
+	return ids, nil
 }
 
 // PullFiles retrieves all file data for the specified user.
@@ -172,23 +301,49 @@ func (a *ServicesAggregator) PullFiles(
 	return files, nil
 }
 
-// PushFiles synchronizes file data to the server for the specified user.
+// PullFile retrieves the metadata and content of the specified file of the user.
+func (a *ServicesAggregator) PullFile(ctx context.Context, userID, fileID uuid.UUID) (*filedata.FileData, error) {
+	file, err := a.fileDataService.Pull(ctx, filedata.PullParams{ID: fileID, UserID: userID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to pull file with ID %s: %w", fileID, err)
+	}
+	return file, nil
+}
+
+// PullFilesPage retrieves up to limit files of the specified user whose IDs sort after the given one,
+// ordered by ID. A zero ID starts at the first file.
+func (a *ServicesAggregator) PullFilesPage(
+	ctx context.Context,
+	userID, after uuid.UUID,
+	limit int,
+) ([]*filedata.FileData, error) {
+	files, err := a.fileDataService.List(ctx, filedata.ListParams{After: after, Limit: limit, UserID: userID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to pull files page: %w", err)
+	}
+	return files, nil
+}
+
+// PushFiles synchronizes file data to the server for the specified user and returns the file IDs in order.
 func (a *ServicesAggregator) PushFiles(
 	ctx context.Context,
 	userID uuid.UUID,
 	files []*filedata.FileData,
-) error {
+) ([]uuid.UUID, error) {
+	ids := make([]uuid.UUID, 0, len(files))
 	for _, f := range files {
-		_, err := a.fileDataService.Push(ctx, &filedata.PushParams{
+		id, err := a.fileDataService.Push(ctx, &filedata.PushParams{
 			ID:          f.ID,
 			UserID:      userID,
 			StorageKey:  f.StorageKey,
 			Description: f.Description,
+			Folder:      f.Folder,
 			Data:        f.Data,
 		})
 		if err != nil {
-			return fmt.Errorf("failed to push file with ID %s: %w", f.ID, err)
+			return nil, fmt.Errorf("failed to push file with ID %s: %w", f.ID, err)
 		}
+		ids = append(ids, id)
 	}
-	return nil
+	return ids, nil
 }
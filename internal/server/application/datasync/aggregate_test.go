@@ -414,7 +414,7 @@ func TestServicesAggregator_PushBankCards(t *testing.T) {
 				&mockFileDataService{},
 			)
 
-			err := aggr.PushBankCards(context.Background(), tt.userID, tt.cards)
+			_, err := aggr.PushBankCards(context.Background(), tt.userID, tt.cards)
 
 			if tt.wantErr {
 				require.Error(t, err)
@@ -485,7 +485,7 @@ func TestServicesAggregator_PushCredentials(t *testing.T) {
 				&mockFileDataService{},
 			)
 
-			err := aggr.PushCredentials(context.Background(), tt.userID, tt.credentials)
+			_, err := aggr.PushCredentials(context.Background(), tt.userID, tt.credentials)
 
 			if tt.wantErr {
 				require.Error(t, err)
@@ -556,7 +556,7 @@ func TestServicesAggregator_PushNotes(t *testing.T) {
 				&mockFileDataService{},
 			)
 
-			err := aggr.PushNotes(context.Background(), tt.userID, tt.notes)
+			_, err := aggr.PushNotes(context.Background(), tt.userID, tt.notes)
 
 			if tt.wantErr {
 				require.Error(t, err)
@@ -627,7 +627,7 @@ func TestServicesAggregator_PushFiles(t *testing.T) {
 				tt.fileDataService,
 			)
 
-			err := aggr.PushFiles(context.Background(), tt.userID, tt.files)
+			_, err := aggr.PushFiles(context.Background(), tt.userID, tt.files)
 
 			if tt.wantErr {
 				require.Error(t, err)
@@ -5,6 +5,7 @@ import (
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/credential"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/filedata"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/note"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/authz"
 	"github.com/google/uuid"
 )
 
@@ -21,3 +22,156 @@ type SyncPayload struct {
 	// UserID identifies the user owning this data payload.
 	UserID uuid.UUID
 }
+
+// CapturePayload represents one item captured together with its files, such as an identity document
+// with scans of its front and back.
+type CapturePayload struct {
+	// BankCard contains the captured bank card; nil unless the item is a bank card.
+	BankCard *bankcard.BankCard
+	// Credential contains the captured credential; nil unless the item is a credential.
+	Credential *credential.Credential
+	// Note contains the captured note; nil unless the item is a note.
+	Note *note.Note
+	// Files contains the files captured with the item, at least one.
+	Files []*filedata.FileData
+	// UserID identifies the user owning the captured data.
+	UserID uuid.UUID
+}
+
+// CaptureResult contains the identifiers of a stored capture.
+type CaptureResult struct {
+	// FileIDs contains the IDs of the stored files in the order of the payload.
+	FileIDs []uuid.UUID
+	// ItemID contains the ID of the stored item.
+	ItemID uuid.UUID
+}
+
+// validate checks that the payload holds exactly one item and at least one file.
+func (p *CapturePayload) validate() error {
+	items := 0
+	for _, set := range []bool{p.BankCard != nil, p.Credential != nil, p.Note != nil} {
+		if set {
+			items++
+		}
+	}
+	if items != 1 {
+		return ErrCaptureItemRequired
+	}
+	if len(p.Files) == 0 {
+		return ErrCaptureFilesRequired
+	}
+	return nil
+}
+
+// ReplayStatus describes the outcome of a replayed operation.
+type ReplayStatus string
+
+const (
+	// ReplayApplied indicates the operation was stored.
+	ReplayApplied ReplayStatus = "applied"
+	// ReplayConflict indicates the item changed on the server since the parent version of the operation.
+	ReplayConflict ReplayStatus = "conflict"
+	// ReplayRejected indicates the operation is invalid or could not be stored.
+	ReplayRejected ReplayStatus = "rejected"
+	// ReplaySkipped indicates the operation was not attempted because the operation it follows was not applied.
+	ReplaySkipped ReplayStatus = "skipped"
+)
+
+// ReplayPayload represents an ordered batch of operations a client queued while offline.
+type ReplayPayload struct {
+	// Ops contains the operations in the order the client performed them.
+	Ops []*ReplayOp
+	// UserID identifies the user owning the replayed items.
+	UserID uuid.UUID
+}
+
+// ReplayOp represents one queued client operation creating or updating a bank card, credential or note.
+type ReplayOp struct {
+	// BankCard contains the bank card to store; nil unless the operation stores a bank card.
+	BankCard *bankcard.BankCard
+	// Credential contains the credential to store; nil unless the operation stores a credential.
+	Credential *credential.Credential
+	// Note contains the note to store; nil unless the operation stores a note.
+	Note *note.Note
+	// OpID identifies the operation within the batch; it is assigned by the client.
+	OpID string
+	// AfterOp contains the op ID of an earlier operation of the batch whose item this operation updates,
+	// based on the revision that operation stored; empty when the operation does not follow another one.
+	AfterOp string
+	// ParentVersion contains the item version the change is based on; required when the item ID is set.
+	ParentVersion int64
+}
+
+// ReplayOutcome contains the result of one replayed operation.
+type ReplayOutcome struct {
+	// Err contains the reason a rejected operation failed; nil unless the operation was rejected.
+	Err error
+	// OpID identifies the operation the outcome belongs to.
+	OpID string
+	// Status describes whether the operation was applied.
+	Status ReplayStatus
+	// ID contains the ID of the stored item, or of the conflicting item on conflict.
+	ID uuid.UUID
+	// Version contains the version of the stored revision, or the current version on conflict.
+	Version int64
+}
+
+// validate checks that every operation of the payload has a unique op ID.
+func (p *ReplayPayload) validate() error {
+	if len(p.Ops) == 0 {
+		return ErrReplayOpsRequired
+	}
+	seen := make(map[string]struct{}, len(p.Ops))
+	for _, op := range p.Ops {
+		if op == nil || op.OpID == "" {
+			return ErrReplayOpIDInvalid
+		}
+		if _, ok := seen[op.OpID]; ok {
+			return ErrReplayOpIDInvalid
+		}
+		seen[op.OpID] = struct{}{}
+	}
+	return nil
+}
+
+// kind returns the kind of the item the operation stores, or an error unless exactly one item is set.
+func (op *ReplayOp) kind() (string, error) {
+	kinds := make([]string, 0, 1)
+	if op.BankCard != nil {
+		kinds = append(kinds, authz.KindBankCard)
+	}
+	if op.Credential != nil {
+		kinds = append(kinds, authz.KindCredential)
+	}
+	if op.Note != nil {
+		kinds = append(kinds, authz.KindNote)
+	}
+	if len(kinds) != 1 {
+		return "", ErrReplayItemRequired
+	}
+	return kinds[0], nil
+}
+
+// itemID returns the ID of the item the operation stores; uuid.Nil creates a new item.
+func (op *ReplayOp) itemID() uuid.UUID {
+	switch {
+	case op.BankCard != nil:
+		return op.BankCard.ID
+	case op.Credential != nil:
+		return op.Credential.ID
+	default:
+		return op.Note.ID
+	}
+}
+
+// setItemID sets the ID of the item the operation stores.
+func (op *ReplayOp) setItemID(id uuid.UUID) {
+	switch {
+	case op.BankCard != nil:
+		op.BankCard.ID = id
+	case op.Credential != nil:
+		op.Credential.ID = id
+	default:
+		op.Note.ID = id
+	}
+}
@@ -0,0 +1,34 @@
+package datasync
+
+import "errors"
+
+var (
+	// ErrCaptureItemRequired indicates a capture without exactly one item.
+	ErrCaptureItemRequired = errors.New("capture requires exactly one item")
+
+	// ErrCaptureFilesRequired indicates a capture without files.
+	ErrCaptureFilesRequired = errors.New("capture requires at least one file")
+
+	// ErrReplayOpsRequired indicates a replay without operations.
+	ErrReplayOpsRequired = errors.New("replay requires at least one operation")
+
+	// ErrReplayOpIDInvalid indicates a replay operation without an op ID or with an op ID used before in the batch.
+	ErrReplayOpIDInvalid = errors.New("replay operation requires a unique op ID")
+
+	// ErrReplayItemRequired indicates a replay operation without exactly one item.
+	ErrReplayItemRequired = errors.New("replay operation requires exactly one item")
+
+	// ErrReplayParentVersionRequired indicates a replay operation updating an item without the version it is based on.
+	ErrReplayParentVersionRequired = errors.New("replay operation updating an item requires a parent version")
+
+	// ErrExportFormatUnsupported indicates an export to a file format that is not supported.
+	ErrExportFormatUnsupported = errors.New("export format is not supported")
+
+	// ErrExportPasswordRequired indicates an export with neither the master password nor a recipient the file is
+	// encrypted with.
+	ErrExportPasswordRequired = errors.New("export requires a master password or a recipient")
+
+	// ErrExportRecipientInvalid indicates an export to a recipient that is neither an age recipient nor an OpenPGP
+	// public key able to encrypt.
+	ErrExportRecipientInvalid = errors.New("export recipient is not an age recipient or an OpenPGP public key")
+)
@@ -0,0 +1,238 @@
+package datasync
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/bankcard"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/credential"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/filedata"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/note"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/kdbx"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/recipient"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/securebytes"
+	"github.com/google/uuid"
+)
+
+// ExportFormat identifies the file format of a vault export.
+type ExportFormat string
+
+const (
+	// ExportFormatKDBX is the KDBX 4 format of KeePass and KeePassXC databases.
+	ExportFormatKDBX ExportFormat = "kdbx"
+)
+
+// exportDatabaseName names the exported database and its root group.
+const exportDatabaseName = "AegisVaultKeeper"
+
+// Names of the groups holding the items of each kind in exported databases.
+const (
+	// exportGroupCredentials holds the credentials.
+	exportGroupCredentials = "Credentials"
+	// exportGroupBankCards holds the bank cards.
+	exportGroupBankCards = "Bank Cards"
+	// exportGroupNotes holds the notes.
+	exportGroupNotes = "Notes"
+	// exportGroupFiles holds the files, in nested groups mirroring their folders.
+	exportGroupFiles = "Files"
+)
+
+// ExportParams contains parameters for exporting the vault of a user to a file.
+type ExportParams struct {
+	// Password specifies the master password the exported file is encrypted with; it is optional with a recipient.
+	Password string
+	// Recipient specifies the age recipient or OpenPGP public key the whole file is encrypted to, if any.
+	Recipient string
+	// Format specifies the file format.
+	Format ExportFormat
+	// UserID identifies the user whose vault is exported.
+	UserID uuid.UUID
+}
+
+// ExportFile represents a file the vault is exported to.
+type ExportFile struct {
+	// Data contains the file content.
+	Data []byte
+	// Extension contains the extension appended to the file name of files encrypted to a recipient, such as ".age".
+	Extension string
+}
+
+// Export encodes every current item of the user, including the content of files, in a file of the requested
+// format encrypted with the master password. Credentials, bank cards, notes and files are placed in groups of
+// their own; files keep their folder structure and are attached to their entries.
+// With a recipient the whole file is additionally encrypted to it, and the master password may be omitted: the
+// database is then protected by an empty one, so the backup can be stored without a shared secret.
+func (s *Service) Export(ctx context.Context, params ExportParams) (*ExportFile, error) {
+	if params.Format != ExportFormatKDBX {
+		return nil, ErrExportFormatUnsupported
+	}
+	// to holds the parsed recipient, if any.
+	var to recipient.Recipient
+	if params.Recipient != "" {
+		r, err := recipient.Parse(params.Recipient)
+		if err != nil {
+			return nil, ErrExportRecipientInvalid
+		}
+		to = r
+	}
+	if params.Password == "" && to == nil {
+		return nil, ErrExportPasswordRequired
+	}
+
+	payload, err := s.Pull(ctx, params.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export vault: %w", err)
+	}
+	files, err := s.exportFiles(ctx, params.UserID, payload.Files)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export vault: %w", err)
+	}
+	defer wipeAttachments(files)
+
+	// buf holds the encoded file.
+	var buf bytes.Buffer
+	db := &kdbx.Database{
+		Name: exportDatabaseName,
+		Root: &kdbx.Group{
+			Name: exportDatabaseName,
+			Groups: []*kdbx.Group{
+				{Name: exportGroupCredentials, Entries: mapSlice(payload.Credentials, credentialEntry)},
+				{Name: exportGroupBankCards, Entries: mapSlice(payload.BankCards, bankCardEntry)},
+				{Name: exportGroupNotes, Entries: mapSlice(payload.Notes, noteEntry)},
+				files,
+			},
+		},
+	}
+	if params.Password == "" {
+		err = kdbx.WriteWithoutPassword(&buf, db, s.kdf)
+	} else {
+		err = kdbx.Write(&buf, db, params.Password, s.kdf)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode kdbx export: %w", err)
+	}
+	if to == nil {
+		return &ExportFile{Data: buf.Bytes()}, nil
+	}
+
+	defer securebytes.Wipe(buf.Bytes())
+	encrypted, err := to.Encrypt(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt export to recipient: %w", err)
+	}
+	return &ExportFile{Data: encrypted, Extension: to.Extension()}, nil
+}
+
+// exportFiles loads the content of the files and arranges their entries in groups mirroring their folders.
+func (s *Service) exportFiles(ctx context.Context, userID uuid.UUID, files []*filedata.FileData) (*kdbx.Group, error) {
+	root := &kdbx.Group{Name: exportGroupFiles}
+	// folders indexes the groups created for folder paths.
+	folders := map[string]*kdbx.Group{"": root}
+	for _, f := range files {
+		content, err := s.aggr.PullFile(ctx, userID, f.ID)
+		if err != nil {
+			wipeAttachments(root)
+			return nil, err
+		}
+		group := folderGroup(folders, f.Folder)
+		group.Entries = append(group.Entries, fileEntry(content))
+	}
+	return root, nil
+}
+
+// wipeAttachments clears the file contents attached to the entries of the group and its nested groups.
+func wipeAttachments(g *kdbx.Group) {
+	for _, e := range g.Entries {
+		for _, a := range e.Attachments {
+			securebytes.Wipe(a.Data)
+		}
+	}
+	for _, child := range g.Groups {
+		wipeAttachments(child)
+	}
+}
+
+// folderGroup returns the group of the folder path, creating it and its missing parents.
+func folderGroup(folders map[string]*kdbx.Group, folder string) *kdbx.Group {
+	folder = strings.Trim(folder, "/")
+	if g, ok := folders[folder]; ok {
+		return g
+	}
+	parent := folderGroup(folders, strings.TrimSuffix(path.Dir(folder), "."))
+	g := &kdbx.Group{Name: path.Base(folder)}
+	parent.Groups = append(parent.Groups, g)
+	folders[folder] = g
+	return g
+}
+
+// credentialEntry maps a credential to an entry titled by its description, or its login without one.
+func credentialEntry(c *credential.Credential) *kdbx.Entry {
+	return &kdbx.Entry{
+		ID:       c.ID,
+		Modified: c.UpdatedAt,
+		Fields: []kdbx.Field{
+			{Key: kdbx.KeyTitle, Value: cmp.Or(c.Description, c.Login)},
+			{Key: kdbx.KeyUserName, Value: c.Login},
+			{Key: kdbx.KeyPassword, Value: c.Password, Protected: true},
+		},
+	}
+}
+
+// bankCardEntry maps a bank card to an entry with the card details in custom fields.
+func bankCardEntry(c *bankcard.BankCard) *kdbx.Entry {
+	return &kdbx.Entry{
+		ID:       c.ID,
+		Modified: c.UpdatedAt,
+		Fields: []kdbx.Field{
+			{Key: kdbx.KeyTitle, Value: cmp.Or(c.Description, "Card "+lastDigits(c.CardNumber))},
+			{Key: kdbx.KeyUserName, Value: c.CardHolder},
+			{Key: "Card Number", Value: c.CardNumber, Protected: true},
+			{Key: "Expiry", Value: c.ExpiryMonth + "/" + c.ExpiryYear},
+			{Key: "CVV", Value: c.CVV, Protected: true},
+		},
+	}
+}
+
+// noteEntry maps a note to an entry keeping the text in its protected notes.
+func noteEntry(n *note.Note) *kdbx.Entry {
+	return &kdbx.Entry{
+		ID:       n.ID,
+		Modified: n.UpdatedAt,
+		Fields: []kdbx.Field{
+			{Key: kdbx.KeyTitle, Value: cmp.Or(n.Description, exportGroupNotes)},
+			{Key: kdbx.KeyNotes, Value: n.Note, Protected: true},
+		},
+	}
+}
+
+// fileEntry maps a file to an entry titled by its name with the content attached.
+func fileEntry(f *filedata.FileData) *kdbx.Entry {
+	name := path.Base(f.StorageKey)
+	return &kdbx.Entry{
+		ID:       f.ID,
+		Modified: f.UpdatedAt,
+		Fields: []kdbx.Field{
+			{Key: kdbx.KeyTitle, Value: name},
+			{Key: kdbx.KeyNotes, Value: f.Description},
+		},
+		Attachments: []kdbx.Attachment{{Name: name, Data: f.Data}},
+	}
+}
+
+// lastDigits returns the last four characters of the card number.
+func lastDigits(number string) string {
+	return number[max(len(number)-4, 0):]
+}
+
+// mapSlice maps every item to an entry.
+func mapSlice[T any](items []T, fn func(T) *kdbx.Entry) []*kdbx.Entry {
+	entries := make([]*kdbx.Entry, 0, len(items))
+	for _, item := range items {
+		entries = append(entries, fn(item))
+	}
+	return entries
+}
@@ -0,0 +1,210 @@
+package datasync
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/bankcard"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/credential"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/filedata"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/note"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/kdbx"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testAgeRecipient is an age X25519 recipient exports are encrypted to in tests.
+const testAgeRecipient = "age10p4mxysy7q5690fpv9suupfsmekul8vlp6fc28nyn0nwl5h66yyswtp88g"
+
+func TestService_Export(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	files := []*filedata.FileData{{ID: uuid.New(), UserID: userID, StorageKey: "passport.pdf", Folder: "docs"}}
+
+	tests := []struct {
+		wantErr         error
+		noteService     *mockNoteService
+		fileDataService *mockFileDataService
+		name            string
+		errContains     string
+		wantExtension   string
+		params          ExportParams
+	}{
+		{
+			name:            "successful export",
+			noteService:     &mockNoteService{listResult: []*note.Note{{ID: uuid.New(), Note: "text"}}},
+			fileDataService: &mockFileDataService{listResult: files},
+			params:          ExportParams{Format: ExportFormatKDBX, Password: "master password", UserID: userID},
+		},
+		{
+			name:            "export to recipient without password",
+			noteService:     &mockNoteService{listResult: []*note.Note{{ID: uuid.New(), Note: "text"}}},
+			fileDataService: &mockFileDataService{},
+			params:          ExportParams{Format: ExportFormatKDBX, Recipient: testAgeRecipient, UserID: userID},
+			wantExtension:   ".age",
+		},
+		{
+			name:            "invalid recipient",
+			noteService:     &mockNoteService{},
+			fileDataService: &mockFileDataService{},
+			params:          ExportParams{Format: ExportFormatKDBX, Recipient: "age1invalid", UserID: userID},
+			wantErr:         ErrExportRecipientInvalid,
+		},
+		{
+			name:            "unsupported format",
+			noteService:     &mockNoteService{},
+			fileDataService: &mockFileDataService{},
+			params:          ExportParams{Format: "csv", Password: "master password", UserID: userID},
+			wantErr:         ErrExportFormatUnsupported,
+		},
+		{
+			name:            "password required",
+			noteService:     &mockNoteService{},
+			fileDataService: &mockFileDataService{},
+			params:          ExportParams{Format: ExportFormatKDBX, UserID: userID},
+			wantErr:         ErrExportPasswordRequired,
+		},
+		{
+			name:            "pull error",
+			noteService:     &mockNoteService{listError: errors.New("database unavailable")},
+			fileDataService: &mockFileDataService{},
+			params:          ExportParams{Format: ExportFormatKDBX, Password: "master password", UserID: userID},
+			errContains:     "failed to export vault",
+		},
+		{
+			name:            "file content error",
+			noteService:     &mockNoteService{},
+			fileDataService: &mockFileDataService{listResult: files, pullError: errors.New("storage unavailable")},
+			params:          ExportParams{Format: ExportFormatKDBX, Password: "master password", UserID: userID},
+			errContains:     "failed to pull file",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			aggr := NewServicesAggregator(&mockBankCardService{}, &mockCredentialService{}, tt.noteService,
+				tt.fileDataService)
+			s := NewService(aggr, &mockUnitOfWork{})
+			s.kdf = kdbx.KDF{Memory: 1 << 20, Iterations: 1, Parallelism: 1}
+
+			file, err := s.Export(t.Context(), tt.params)
+
+			switch {
+			case tt.wantErr != nil:
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, file)
+			case tt.errContains != "":
+				require.ErrorContains(t, err, tt.errContains)
+				assert.Nil(t, file)
+			case tt.wantExtension != "":
+				require.NoError(t, err)
+				assert.Equal(t, tt.wantExtension, file.Extension)
+				assert.True(t, bytes.HasPrefix(file.Data, []byte("age-encryption.org/v1\n")))
+			default:
+				require.NoError(t, err)
+				assert.Empty(t, file.Extension)
+				require.Greater(t, len(file.Data), 12)
+				assert.Equal(t, uint32(0x9AA2D903), binary.LittleEndian.Uint32(file.Data[0:4]))
+				assert.Equal(t, uint32(0xB54BFB67), binary.LittleEndian.Uint32(file.Data[4:8]))
+				assert.Equal(t, uint32(0x00040000), binary.LittleEndian.Uint32(file.Data[8:12]))
+			}
+		})
+	}
+}
+
+func TestFolderGroup(t *testing.T) {
+	t.Parallel()
+
+	root := &kdbx.Group{Name: exportGroupFiles}
+	folders := map[string]*kdbx.Group{"": root}
+
+	tax := folderGroup(folders, "docs/tax/2026")
+	scans := folderGroup(folders, "/docs/scans/")
+
+	assert.Same(t, root, folderGroup(folders, ""))
+	assert.Same(t, tax, folderGroup(folders, "docs/tax/2026"))
+	require.Len(t, root.Groups, 1)
+	docs := root.Groups[0]
+	assert.Equal(t, "docs", docs.Name)
+	require.Len(t, docs.Groups, 2)
+	assert.Equal(t, "tax", docs.Groups[0].Name)
+	assert.Equal(t, "scans", docs.Groups[1].Name)
+	assert.Same(t, scans, docs.Groups[1])
+	require.Len(t, docs.Groups[0].Groups, 1)
+	assert.Same(t, tax, docs.Groups[0].Groups[0])
+}
+
+func TestExportEntries(t *testing.T) {
+	t.Parallel()
+
+	id := uuid.New()
+	updated := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		entry *kdbx.Entry
+		want  *kdbx.Entry
+		name  string
+	}{
+		{
+			name:  "credential titled by login",
+			entry: credentialEntry(&credential.Credential{ID: id, UpdatedAt: updated, Login: "alice", Password: "pw"}),
+			want: &kdbx.Entry{ID: id, Modified: updated, Fields: []kdbx.Field{
+				{Key: kdbx.KeyTitle, Value: "alice"},
+				{Key: kdbx.KeyUserName, Value: "alice"},
+				{Key: kdbx.KeyPassword, Value: "pw", Protected: true},
+			}},
+		},
+		{
+			name: "bank card titled by last digits",
+			entry: bankCardEntry(&bankcard.BankCard{
+				ID: id, UpdatedAt: updated, CardNumber: "4111111111111111", CardHolder: "ALICE DOE",
+				ExpiryMonth: "12", ExpiryYear: "30", CVV: "123",
+			}),
+			want: &kdbx.Entry{ID: id, Modified: updated, Fields: []kdbx.Field{
+				{Key: kdbx.KeyTitle, Value: "Card 1111"},
+				{Key: kdbx.KeyUserName, Value: "ALICE DOE"},
+				{Key: "Card Number", Value: "4111111111111111", Protected: true},
+				{Key: "Expiry", Value: "12/30"},
+				{Key: "CVV", Value: "123", Protected: true},
+			}},
+		},
+		{
+			name:  "note titled by description",
+			entry: noteEntry(&note.Note{ID: id, UpdatedAt: updated, Note: "text", Description: "Wi-Fi"}),
+			want: &kdbx.Entry{ID: id, Modified: updated, Fields: []kdbx.Field{
+				{Key: kdbx.KeyTitle, Value: "Wi-Fi"},
+				{Key: kdbx.KeyNotes, Value: "text", Protected: true},
+			}},
+		},
+		{
+			name: "file with attachment",
+			entry: fileEntry(&filedata.FileData{
+				ID: id, UpdatedAt: updated, StorageKey: "scans/passport.pdf", Description: "front", Data: []byte("%PDF"),
+			}),
+			want: &kdbx.Entry{
+				ID:       id,
+				Modified: updated,
+				Fields: []kdbx.Field{
+					{Key: kdbx.KeyTitle, Value: "passport.pdf"},
+					{Key: kdbx.KeyNotes, Value: "front"},
+				},
+				Attachments: []kdbx.Attachment{{Name: "passport.pdf", Data: []byte("%PDF")}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, tt.entry)
+		})
+	}
+}
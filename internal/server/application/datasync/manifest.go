@@ -0,0 +1,90 @@
+package datasync
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"slices"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/authz"
+	"github.com/google/uuid"
+)
+
+// ManifestEntry describes the current revision of one vault item without its content.
+type ManifestEntry struct {
+	// UpdatedAt indicates when the item was last modified.
+	UpdatedAt time.Time
+	// Type identifies the kind of the item: bankcard, credential, note or file.
+	Type string
+	// Digest contains the hex-encoded SHA-256 digest of the item content, see digest.
+	Digest string
+	// ID identifies the item.
+	ID uuid.UUID
+	// Version identifies the revision of the item and increases with every update of it, see version.
+	Version int64
+}
+
+// newManifest builds the manifest entries of the payload items, ordered by type and ID.
+// Files are described by their metadata and content hash, so their content is never needed.
+func newManifest(p *SyncPayload) []*ManifestEntry {
+	entries := make([]*ManifestEntry, 0, len(p.BankCards)+len(p.Credentials)+len(p.Notes)+len(p.Files))
+	for _, c := range p.BankCards {
+		entries = append(entries, newManifestEntry(authz.KindBankCard, c.ID, c.UpdatedAt, digest(
+			c.CardNumber, c.CardHolder, c.ExpiryMonth, c.ExpiryYear, c.CVV, c.Description,
+		)))
+	}
+	for _, c := range p.Credentials {
+		entries = append(entries, newManifestEntry(authz.KindCredential, c.ID, c.UpdatedAt, digest(
+			c.Login, c.Password, c.Description,
+		)))
+	}
+	for _, n := range p.Notes {
+		entries = append(entries, newManifestEntry(authz.KindNote, n.ID, n.UpdatedAt, digest(
+			n.Note, n.Description,
+		)))
+	}
+	for _, f := range p.Files {
+		entries = append(entries, newManifestEntry(authz.KindFile, f.ID, f.UpdatedAt, digest(
+			f.StorageKey, f.Folder, f.Description, f.HashSum,
+		)))
+	}
+
+	slices.SortFunc(entries, func(a, b *ManifestEntry) int {
+		return cmp.Or(cmp.Compare(a.Type, b.Type), cmp.Compare(a.ID.String(), b.ID.String()))
+	})
+	return entries
+}
+
+// newManifestEntry creates the manifest entry of an item.
+func newManifestEntry(kind string, id uuid.UUID, updatedAt time.Time, digest string) *ManifestEntry {
+	return &ManifestEntry{
+		ID:        id,
+		Type:      kind,
+		Version:   version(updatedAt),
+		UpdatedAt: updatedAt,
+		Digest:    digest,
+	}
+}
+
+// version returns the version of the item revision modified at the moment: the modification time in
+// microseconds since the Unix epoch, the precision items are stored with.
+func version(updatedAt time.Time) int64 {
+	return updatedAt.UnixMicro()
+}
+
+// digest returns the hex-encoded SHA-256 digest of the item fields. Every field is prefixed with its
+// length as a 4-byte big-endian integer, so clients can compute the same digest from their local copy
+// and no two field lists share an encoding.
+func digest(fields ...string) string {
+	h := sha256.New()
+	// prefix holds the length prefix of the current field.
+	var prefix [4]byte
+	for _, f := range fields {
+		binary.BigEndian.PutUint32(prefix[:], uint32(len(f))) // nolint:gosec // item fields are far below 4 GiB.
+		h.Write(prefix[:])
+		h.Write([]byte(f))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,54 @@
+package datasync
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDigest(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		a      []string
+		b      []string
+		wantEq bool
+	}{
+		{
+			name:   "same fields",
+			a:      []string{"login", "password"},
+			b:      []string{"login", "password"},
+			wantEq: true,
+		},
+		{
+			name:   "shifted field boundary",
+			a:      []string{"ab", "c"},
+			b:      []string{"a", "bc"},
+			wantEq: false,
+		},
+		{
+			name:   "empty field",
+			a:      []string{"note", ""},
+			b:      []string{"note"},
+			wantEq: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			a, b := digest(tt.a...), digest(tt.b...)
+			assert.Len(t, a, 64)
+			assert.Equal(t, tt.wantEq, a == b)
+		})
+	}
+}
+
+func TestDigest_Encoding(t *testing.T) {
+	t.Parallel()
+
+	// The digest of a single "a" field is SHA-256 of 00 00 00 01 61.
+	assert.Equal(t, "72ff6b02949dad95006c343e3db3150090d3afb49f6bbdb92fdc17607997a85c", digest("a"))
+}
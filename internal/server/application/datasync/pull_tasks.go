@@ -3,6 +3,7 @@ package datasync
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/bankcard"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/credential"
@@ -11,14 +12,16 @@ import (
 	"github.com/google/uuid"
 )
 
-// makePullBankCardsTask creates a task function that pulls bank cards for a user and stores them in the target slice.
+// makePullBankCardsTask creates a task function that pulls bank cards for a user as of the given moment
+// and stores them in the target slice.
 func (s *Service) makePullBankCardsTask(
 	ctx context.Context,
 	userID uuid.UUID,
+	asOf time.Time,
 	target *[]*bankcard.BankCard,
 ) func() error {
 	return func() error {
-		result, err := s.aggr.PullBankCards(ctx, userID)
+		result, err := s.aggr.PullBankCardsAsOf(ctx, userID, asOf)
 		if err != nil {
 			return fmt.Errorf("failed to pull bank cards: %w", err)
 		}
@@ -27,15 +30,16 @@ func (s *Service) makePullBankCardsTask(
 	}
 }
 
-// makePullCredentialsTask creates a task function that pulls credentials for a user and stores them
-// in the target slice.
+// makePullCredentialsTask creates a task function that pulls credentials for a user as of the given moment
+// and stores them in the target slice.
 func (s *Service) makePullCredentialsTask(
 	ctx context.Context,
 	userID uuid.UUID,
+	asOf time.Time,
 	target *[]*credential.Credential,
 ) func() error {
 	return func() error {
-		result, err := s.aggr.PullCredentials(ctx, userID)
+		result, err := s.aggr.PullCredentialsAsOf(ctx, userID, asOf)
 		if err != nil {
 			return fmt.Errorf("failed to pull credentials: %w", err)
 		}
@@ -44,14 +48,16 @@ func (s *Service) makePullCredentialsTask(
 	}
 }
 
-// makePullNotesTask creates a task function that pulls notes for a user and stores them in the target slice.
+// makePullNotesTask creates a task function that pulls notes for a user as of the given moment
+// and stores them in the target slice.
 func (s *Service) makePullNotesTask(
 	ctx context.Context,
 	userID uuid.UUID,
+	asOf time.Time,
 	target *[]*note.Note,
 ) func() error {
 	return func() error {
-		result, err := s.aggr.PullNotes(ctx, userID)
+		result, err := s.aggr.PullNotesAsOf(ctx, userID, asOf)
 		if err != nil {
 			return fmt.Errorf("failed to pull notes: %w", err)
 		}
@@ -18,7 +18,7 @@ func (s *Service) makePushBankCardsTask(
 	cards []*bankcard.BankCard,
 ) func() error {
 	return func() error {
-		if err := s.aggr.PushBankCards(ctx, userID, cards); err != nil {
+		if _, err := s.aggr.PushBankCards(ctx, userID, cards); err != nil {
 			return fmt.Errorf("failed to push bank cards: %w", err)
 		}
 		return nil
@@ -32,7 +32,7 @@ func (s *Service) makePushCredentialsTask(
 	creds []*credential.Credential,
 ) func() error {
 	return func() error {
-		if err := s.aggr.PushCredentials(ctx, userID, creds); err != nil {
+		if _, err := s.aggr.PushCredentials(ctx, userID, creds); err != nil {
 			return fmt.Errorf("failed to push credentials: %w", err)
 		}
 		return nil
@@ -42,7 +42,7 @@ func (s *Service) makePushCredentialsTask(
 // makePushNotesTask creates a task function that pushes notes for a user to the server.
 func (s *Service) makePushNotesTask(ctx context.Context, userID uuid.UUID, notes []*note.Note) func() error {
 	return func() error {
-		if err := s.aggr.PushNotes(ctx, userID, notes); err != nil {
+		if _, err := s.aggr.PushNotes(ctx, userID, notes); err != nil {
 			return fmt.Errorf("failed to push notes: %w", err)
 		}
 		return nil
@@ -56,7 +56,7 @@ func (s *Service) makePushFilesTask(
 	files []*filedata.FileData,
 ) func() error {
 	return func() error {
-		if err := s.aggr.PushFiles(ctx, userID, files); err != nil {
+		if _, err := s.aggr.PushFiles(ctx, userID, files); err != nil {
 			return fmt.Errorf("failed to push files: %w", err)
 		}
 		return nil
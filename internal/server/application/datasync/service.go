@@ -3,24 +3,36 @@ package datasync
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/bankcard"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/credential"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/filedata"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/note"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/kdbx"
 	"github.com/google/uuid"
 	"golang.org/x/sync/errgroup"
 )
 
+// UnitOfWork defines the interface for running several repository writes as one transaction.
+type UnitOfWork interface {
+	// Do executes fn in a single transaction scoped to the user, rolling back every write on error.
+	Do(ctx context.Context, userID uuid.UUID, fn func(ctx context.Context) error) error
+}
+
 // Service coordinates data synchronization operations across all data types using concurrent tasks.
 type Service struct {
 	// aggr provides aggregated access to all application layer services for data synchronization.
 	aggr *ServicesAggregator
+	// uow groups the writes of a push into a single transaction.
+	uow UnitOfWork
+	// kdf contains the key derivation parameters of KDBX exports.
+	kdf kdbx.KDF
 }
 
-// NewService creates a new Service with the provided services aggregator.
-func NewService(aggr *ServicesAggregator) *Service {
-	return &Service{aggr: aggr}
+// NewService creates a new Service with the provided services aggregator and unit of work.
+func NewService(aggr *ServicesAggregator, uow UnitOfWork) *Service {
+	return &Service{aggr: aggr, uow: uow, kdf: kdbx.DefaultKDF}
 }
 
 // Pull retrieves all user data concurrently and returns it as a SyncPayload.
@@ -33,9 +45,9 @@ func (s *Service) Pull(ctx context.Context, userID uuid.UUID) (*SyncPayload, err
 	)
 
 	g, ctx := errgroup.WithContext(ctx)
-	g.Go(s.makePullBankCardsTask(ctx, userID, &cards))
-	g.Go(s.makePullCredentialsTask(ctx, userID, &creds))
-	g.Go(s.makePullNotesTask(ctx, userID, &notes))
+	g.Go(s.makePullBankCardsTask(ctx, userID, time.Time{}, &cards))
+	g.Go(s.makePullCredentialsTask(ctx, userID, time.Time{}, &creds))
+	g.Go(s.makePullNotesTask(ctx, userID, time.Time{}, &notes))
 	g.Go(s.makePullFilesTask(ctx, userID, &files))
 
 	if err := g.Wait(); err != nil {
@@ -51,16 +63,195 @@ func (s *Service) Pull(ctx context.Context, userID uuid.UUID) (*SyncPayload, err
 	}, nil
 }
 
-// Push synchronizes all data in the payload to the server concurrently.
-func (s *Service) Push(ctx context.Context, payload *SyncPayload) error {
+// PullAsOf retrieves the user's vault state at the given moment and returns it as a SyncPayload.
+// Files are not versioned, so the payload contains only bank cards, credentials and notes.
+func (s *Service) PullAsOf(ctx context.Context, userID uuid.UUID, asOf time.Time) (*SyncPayload, error) {
+	var (
+		cards []*bankcard.BankCard
+		creds []*credential.Credential
+		notes []*note.Note
+	)
+
 	g, ctx := errgroup.WithContext(ctx)
-	g.Go(s.makePushBankCardsTask(ctx, payload.UserID, payload.BankCards))
-	g.Go(s.makePushCredentialsTask(ctx, payload.UserID, payload.Credentials))
-	g.Go(s.makePushNotesTask(ctx, payload.UserID, payload.Notes))
-	g.Go(s.makePushFilesTask(ctx, payload.UserID, payload.Files))
+	g.Go(s.makePullBankCardsTask(ctx, userID, asOf, &cards))
+	g.Go(s.makePullCredentialsTask(ctx, userID, asOf, &creds))
+	g.Go(s.makePullNotesTask(ctx, userID, asOf, &notes))
 
 	if err := g.Wait(); err != nil {
+		return nil, fmt.Errorf("failed to pull data as of %s: %w", asOf.Format(time.RFC3339), err)
+	}
+
+	return &SyncPayload{
+		UserID:      userID,
+		BankCards:   cards,
+		Credentials: creds,
+		Notes:       notes,
+	}, nil
+}
+
+// Manifest returns a manifest entry for every current item of the user, so clients can tell which items
+// changed without pulling their content.
+func (s *Service) Manifest(ctx context.Context, userID uuid.UUID) ([]*ManifestEntry, error) {
+	payload, err := s.Pull(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build manifest: %w", err)
+	}
+	return newManifest(payload), nil
+}
+
+// Push synchronizes all data in the payload to the server in a single unit of work, so either every
+// item is stored or none is. Bank cards, credentials and notes are written with batch statements;
+// the data types are written one after another, since a transaction runs one statement at a time.
+func (s *Service) Push(ctx context.Context, payload *SyncPayload) error {
+	err := s.uow.Do(ctx, payload.UserID, func(ctx context.Context) error {
+		tasks := []func() error{
+			s.makePushBankCardsTask(ctx, payload.UserID, payload.BankCards),
+			s.makePushCredentialsTask(ctx, payload.UserID, payload.Credentials),
+			s.makePushNotesTask(ctx, payload.UserID, payload.Notes),
+			s.makePushFilesTask(ctx, payload.UserID, payload.Files),
+		}
+		for _, task := range tasks {
+			if err := task(); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
 		return fmt.Errorf("failed to push data: %w", err)
 	}
 	return nil
 }
+
+// Capture stores one item together with its files in a single unit of work, so a client interrupted
+// mid-upload never leaves a partial item behind: either the item and every file are stored or none is.
+func (s *Service) Capture(ctx context.Context, payload *CapturePayload) (*CaptureResult, error) {
+	if err := payload.validate(); err != nil {
+		return nil, fmt.Errorf("invalid capture: %w", err)
+	}
+
+	result := &CaptureResult{}
+	err := s.uow.Do(ctx, payload.UserID, func(ctx context.Context) error {
+		itemID, err := s.pushItem(ctx, payload.UserID, payload.BankCard, payload.Credential, payload.Note)
+		if err != nil {
+			return fmt.Errorf("failed to push captured item: %w", err)
+		}
+		fileIDs, err := s.aggr.PushFiles(ctx, payload.UserID, payload.Files)
+		if err != nil {
+			return fmt.Errorf("failed to push captured files: %w", err)
+		}
+		result.ItemID, result.FileIDs = itemID, fileIDs
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to capture item: %w", err)
+	}
+	return result, nil
+}
+
+// Replay applies the operations a client queued while offline in their order and returns the outcome of
+// every operation. Updates carry the item version they are based on and are only applied while it is still
+// the current version; otherwise the operation is reported as a conflict. An operation following another one
+// of the batch updates the item that operation stored and is skipped unless it was applied. Operations are
+// stored one by one, so the outcome of one does not undo another.
+func (s *Service) Replay(ctx context.Context, payload *ReplayPayload) ([]*ReplayOutcome, error) {
+	if err := payload.validate(); err != nil {
+		return nil, fmt.Errorf("invalid replay: %w", err)
+	}
+
+	outcomes := make([]*ReplayOutcome, 0, len(payload.Ops))
+	byOpID := make(map[string]*ReplayOutcome, len(payload.Ops))
+	for _, op := range payload.Ops {
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("replay interrupted: %w", err)
+		}
+		outcome := s.replayOp(ctx, payload.UserID, op, byOpID)
+		outcomes = append(outcomes, outcome)
+		byOpID[op.OpID] = outcome
+	}
+	return outcomes, nil
+}
+
+// replayOp applies one operation of a replay; applied holds the outcomes of the earlier operations by op ID.
+func (s *Service) replayOp(
+	ctx context.Context,
+	userID uuid.UUID,
+	op *ReplayOp,
+	applied map[string]*ReplayOutcome,
+) *ReplayOutcome {
+	outcome := &ReplayOutcome{OpID: op.OpID}
+	reject := func(err error) *ReplayOutcome {
+		outcome.Status, outcome.Err = ReplayRejected, err
+		return outcome
+	}
+
+	kind, err := op.kind()
+	if err != nil {
+		return reject(err)
+	}
+
+	id, parent := op.itemID(), op.ParentVersion
+	if op.AfterOp != "" {
+		prev, ok := applied[op.AfterOp]
+		if !ok || prev.Status != ReplayApplied {
+			outcome.Status = ReplaySkipped
+			return outcome
+		}
+		id, parent = prev.ID, prev.Version
+	}
+
+	if id != uuid.Nil {
+		if parent == 0 {
+			return reject(ErrReplayParentVersionRequired)
+		}
+		current, err := s.aggr.ItemVersion(ctx, kind, userID, id)
+		if err != nil {
+			return reject(err)
+		}
+		if current != parent {
+			outcome.Status, outcome.ID, outcome.Version = ReplayConflict, id, current
+			return outcome
+		}
+	}
+
+	op.setItemID(id)
+	if id, err = s.pushItem(ctx, userID, op.BankCard, op.Credential, op.Note); err != nil {
+		return reject(fmt.Errorf("failed to push %s: %w", kind, err))
+	}
+	stored, err := s.aggr.ItemVersion(ctx, kind, userID, id)
+	if err != nil {
+		return reject(err)
+	}
+	outcome.Status, outcome.ID, outcome.Version = ReplayApplied, id, stored
+	return outcome
+}
+
+// pushItem stores the one item set among the bank card, credential and note and returns its ID.
+func (s *Service) pushItem(
+	ctx context.Context,
+	userID uuid.UUID,
+	card *bankcard.BankCard,
+	cred *credential.Credential,
+	n *note.Note,
+) (uuid.UUID, error) {
+	// ids holds the IDs of the one-item batch; err holds its failure.
+	var (
+		ids []uuid.UUID
+		err error
+	)
+	switch {
+	case card != nil:
+		ids, err = s.aggr.PushBankCards(ctx, userID, []*bankcard.BankCard{card})
+	case cred != nil:
+		ids, err = s.aggr.PushCredentials(ctx, userID, []*credential.Credential{cred})
+	default:
+		ids, err = s.aggr.PushNotes(ctx, userID, []*note.Note{n})
+	}
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if len(ids) != 1 {
+		return uuid.Nil, fmt.Errorf("got %d IDs for one item", len(ids))
+	}
+	return ids[0], nil
+}
@@ -1,9 +1,12 @@
 package datasync
 
 import (
+	"cmp"
 	"context"
 	"errors"
+	"slices"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -17,6 +20,7 @@ import (
 
 // Mock implementations for testing.
 type mockBankCardService struct {
+	pullFunc   func(params bankcard.PullParams) (*bankcard.BankCard, error)
 	listError  error
 	pushError  error
 	listResult []*bankcard.BankCard
@@ -27,14 +31,27 @@ func (m *mockBankCardService) List(
 	ctx context.Context,
 	params bankcard.ListParams,
 ) ([]*bankcard.BankCard, error) {
-	return m.listResult, m.listError
+	return listPage(m.listResult, func(c *bankcard.BankCard) uuid.UUID { return c.ID }, params.After, params.Limit),
+		m.listError
 }
 
-func (m *mockBankCardService) Push(ctx context.Context, params *bankcard.PushParams) (uuid.UUID, error) {
-	return m.pushResult, m.pushError
+func (m *mockBankCardService) Pull(ctx context.Context, params bankcard.PullParams) (*bankcard.BankCard, error) {
+	if m.pullFunc != nil {
+		return m.pullFunc(params)
+	}
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockBankCardService) PushBatch(ctx context.Context, params bankcard.PushBatchParams) ([]uuid.UUID, error) {
+	ids := make([]uuid.UUID, len(params.Items))
+	for i := range ids {
+		ids[i] = m.pushResult
+	}
+	return ids, m.pushError
 }
 
 type mockCredentialService struct {
+	pullFunc   func(params credential.PullParams) (*credential.Credential, error)
 	listError  error
 	pushError  error
 	listResult []*credential.Credential
@@ -44,14 +61,27 @@ type mockCredentialService struct {
 func (m *mockCredentialService) List(
 	ctx context.Context, params credential.ListParams,
 ) ([]*credential.Credential, error) {
-	return m.listResult, m.listError
+	id := func(c *credential.Credential) uuid.UUID { return c.ID }
+	return listPage(m.listResult, id, params.After, params.Limit), m.listError
 }
 
-func (m *mockCredentialService) Push(ctx context.Context, params *credential.PushParams) (uuid.UUID, error) {
-	return m.pushResult, m.pushError
+func (m *mockCredentialService) Pull(ctx context.Context, params credential.PullParams) (*credential.Credential, error) {
+	if m.pullFunc != nil {
+		return m.pullFunc(params)
+	}
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockCredentialService) PushBatch(ctx context.Context, params credential.PushBatchParams) ([]uuid.UUID, error) {
+	ids := make([]uuid.UUID, len(params.Items))
+	for i := range ids {
+		ids[i] = m.pushResult
+	}
+	return ids, m.pushError
 }
 
 type mockNoteService struct {
+	pullFunc   func(params note.PullParams) (*note.Note, error)
 	listError  error
 	pushError  error
 	listResult []*note.Note
@@ -59,31 +89,87 @@ type mockNoteService struct {
 }
 
 func (m *mockNoteService) List(ctx context.Context, params note.ListParams) ([]*note.Note, error) {
-	return m.listResult, m.listError
+	return listPage(m.listResult, func(n *note.Note) uuid.UUID { return n.ID }, params.After, params.Limit), m.listError
 }
 
-func (m *mockNoteService) Push(ctx context.Context, params *note.PushParams) (uuid.UUID, error) {
-	return m.pushResult, m.pushError
+func (m *mockNoteService) Pull(ctx context.Context, params note.PullParams) (*note.Note, error) {
+	if m.pullFunc != nil {
+		return m.pullFunc(params)
+	}
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockNoteService) PushBatch(ctx context.Context, params note.PushBatchParams) ([]uuid.UUID, error) {
+	ids := make([]uuid.UUID, len(params.Items))
+	for i := range ids {
+		ids[i] = m.pushResult
+	}
+	return ids, m.pushError
 }
 
 type mockFileDataService struct {
 	listError  error
 	pushError  error
+	pullError  error
 	listResult []*filedata.FileData
 	pushResult uuid.UUID
 }
 
+func (m *mockFileDataService) Pull(ctx context.Context, params filedata.PullParams) (*filedata.FileData, error) {
+	if m.pullError != nil {
+		return nil, m.pullError
+	}
+	for _, f := range m.listResult {
+		if f.ID == params.ID {
+			file := *f
+			file.Data = []byte("content of " + f.StorageKey)
+			return &file, nil
+		}
+	}
+	return nil, errors.New("file not found")
+}
+
 func (m *mockFileDataService) List(
 	ctx context.Context,
 	params filedata.ListParams,
 ) ([]*filedata.FileData, error) {
-	return m.listResult, m.listError
+	return listPage(m.listResult, func(f *filedata.FileData) uuid.UUID { return f.ID }, params.After, params.Limit),
+		m.listError
 }
 
 func (m *mockFileDataService) Push(ctx context.Context, params *filedata.PushParams) (uuid.UUID, error) {
 	return m.pushResult, m.pushError
 }
 
+// listPage returns at most limit of the items whose IDs sort after the given one, in ID order, like the paged
+// repository loads; a zero limit returns the items unchanged.
+func listPage[T any](items []T, id func(T) uuid.UUID, after uuid.UUID, limit int) []T {
+	if limit == 0 || items == nil {
+		return items
+	}
+	page := slices.SortedFunc(slices.Values(items), func(a, b T) int {
+		return cmp.Compare(id(a).String(), id(b).String())
+	})
+	page = slices.DeleteFunc(page, func(item T) bool { return id(item).String() <= after.String() })
+	return page[:min(limit, len(page))]
+}
+
+// mockUnitOfWork runs units of work directly, recording the user of the last one.
+type mockUnitOfWork struct {
+	err    error
+	userID uuid.UUID
+	calls  int
+}
+
+func (m *mockUnitOfWork) Do(ctx context.Context, userID uuid.UUID, fn func(ctx context.Context) error) error {
+	m.calls++
+	m.userID = userID
+	if m.err != nil {
+		return m.err
+	}
+	return fn(ctx)
+}
+
 func TestNewService(t *testing.T) {
 	t.Parallel()
 
@@ -105,7 +191,7 @@ func TestNewService(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			service := NewService(tt.aggr)
+			service := NewService(tt.aggr, &mockUnitOfWork{})
 
 			assert.NotNil(t, service)
 			assert.Equal(t, tt.aggr, service.aggr)
@@ -247,7 +333,7 @@ func TestService_Pull(t *testing.T) {
 				tt.noteService,
 				tt.fileDataService,
 			)
-			service := NewService(aggr)
+			service := NewService(aggr, &mockUnitOfWork{})
 
 			result, err := service.Pull(context.Background(), userID)
 
@@ -270,6 +356,248 @@ func TestService_Pull(t *testing.T) {
 	}
 }
 
+func TestService_PullAsOf(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	asOf := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		noteService *mockNoteService
+		name        string
+		errContains string
+		wantErr     bool
+	}{
+		{
+			name: "successful pull without files",
+			noteService: &mockNoteService{
+				listResult: []*note.Note{{ID: uuid.New(), UserID: userID, Note: "Old Note"}},
+			},
+		},
+		{
+			name: "note service error",
+			noteService: &mockNoteService{
+				listError: errors.New("note service error"),
+			},
+			wantErr:     true,
+			errContains: "failed to pull data as of",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			aggr := NewServicesAggregator(
+				&mockBankCardService{listResult: []*bankcard.BankCard{}},
+				&mockCredentialService{listResult: []*credential.Credential{}},
+				tt.noteService,
+				&mockFileDataService{listError: errors.New("files must not be pulled")},
+			)
+
+			result, err := NewService(aggr, &mockUnitOfWork{}).PullAsOf(context.Background(), userID, asOf)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errContains)
+				assert.Nil(t, result)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, userID, result.UserID)
+			assert.Equal(t, tt.noteService.listResult, result.Notes)
+			assert.Nil(t, result.Files)
+		})
+	}
+}
+
+func TestService_Manifest(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	updatedAt := time.Date(2026, 10, 15, 12, 0, 0, 123456000, time.UTC)
+	noteID := uuid.New()
+	fileID := uuid.New()
+
+	tests := []struct {
+		noteService *mockNoteService
+		want        []*ManifestEntry
+		name        string
+		errContains string
+		wantErr     bool
+	}{
+		{
+			name: "entries ordered by type",
+			noteService: &mockNoteService{
+				listResult: []*note.Note{{ID: noteID, UserID: userID, Note: "Note", UpdatedAt: updatedAt}},
+			},
+			want: []*ManifestEntry{
+				{
+					ID:        fileID,
+					Type:      "file",
+					Version:   updatedAt.UnixMicro(),
+					UpdatedAt: updatedAt,
+					Digest:    digest("scan.pdf", "docs", "", "abc"),
+				},
+				{
+					ID:        noteID,
+					Type:      "note",
+					Version:   updatedAt.UnixMicro(),
+					UpdatedAt: updatedAt,
+					Digest:    digest("Note", ""),
+				},
+			},
+		},
+		{
+			name: "pull error",
+			noteService: &mockNoteService{
+				listError: errors.New("note service error"),
+			},
+			wantErr:     true,
+			errContains: "failed to build manifest",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			aggr := NewServicesAggregator(
+				&mockBankCardService{listResult: []*bankcard.BankCard{}},
+				&mockCredentialService{listResult: []*credential.Credential{}},
+				tt.noteService,
+				&mockFileDataService{listResult: []*filedata.FileData{
+					{ID: fileID, UserID: userID, StorageKey: "scan.pdf", Folder: "docs", HashSum: "abc", UpdatedAt: updatedAt},
+				}},
+			)
+
+			got, err := NewService(aggr, &mockUnitOfWork{}).Manifest(context.Background(), userID)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errContains)
+				assert.Nil(t, got)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestService_Replay(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	createdID := uuid.New()
+	existingID := uuid.New()
+	createdAt := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	existingAt := createdAt.Add(-time.Hour)
+
+	// pull reports the current revision of the created and the existing note.
+	pull := func(params note.PullParams) (*note.Note, error) {
+		switch params.ID {
+		case createdID:
+			return &note.Note{ID: createdID, UpdatedAt: createdAt}, nil
+		case existingID:
+			return &note.Note{ID: existingID, UpdatedAt: existingAt}, nil
+		default:
+			return nil, note.ErrNoteNotFound
+		}
+	}
+
+	tests := []struct {
+		wantErr error
+		name    string
+		ops     []*ReplayOp
+		want    []*ReplayOutcome
+	}{
+		{
+			name: "create followed by update",
+			ops: []*ReplayOp{
+				{OpID: "op-1", Note: &note.Note{Note: "draft"}},
+				{OpID: "op-2", AfterOp: "op-1", Note: &note.Note{Note: "final"}},
+			},
+			want: []*ReplayOutcome{
+				{OpID: "op-1", Status: ReplayApplied, ID: createdID, Version: createdAt.UnixMicro()},
+				{OpID: "op-2", Status: ReplayApplied, ID: createdID, Version: createdAt.UnixMicro()},
+			},
+		},
+		{
+			name: "conflict skips dependent operation",
+			ops: []*ReplayOp{
+				{OpID: "op-1", ParentVersion: 1, Note: &note.Note{ID: existingID, Note: "stale"}},
+				{OpID: "op-2", AfterOp: "op-1", Note: &note.Note{Note: "follow-up"}},
+			},
+			want: []*ReplayOutcome{
+				{OpID: "op-1", Status: ReplayConflict, ID: existingID, Version: existingAt.UnixMicro()},
+				{OpID: "op-2", Status: ReplaySkipped},
+			},
+		},
+		{
+			name: "invalid operations rejected",
+			ops: []*ReplayOp{
+				{OpID: "op-1"},
+				{OpID: "op-2", Note: &note.Note{ID: existingID, Note: "no parent"}},
+				{OpID: "op-3", AfterOp: "op-9", Note: &note.Note{Note: "unknown predecessor"}},
+			},
+			want: []*ReplayOutcome{
+				{OpID: "op-1", Status: ReplayRejected, Err: ErrReplayItemRequired},
+				{OpID: "op-2", Status: ReplayRejected, Err: ErrReplayParentVersionRequired},
+				{OpID: "op-3", Status: ReplaySkipped},
+			},
+		},
+		{
+			name: "duplicate op IDs",
+			ops: []*ReplayOp{
+				{OpID: "op-1", Note: &note.Note{Note: "a"}},
+				{OpID: "op-1", Note: &note.Note{Note: "b"}},
+			},
+			wantErr: ErrReplayOpIDInvalid,
+		},
+		{
+			name:    "no operations",
+			wantErr: ErrReplayOpsRequired,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			aggr := NewServicesAggregator(
+				&mockBankCardService{},
+				&mockCredentialService{},
+				&mockNoteService{pullFunc: pull, pushResult: createdID},
+				&mockFileDataService{},
+			)
+
+			got, err := NewService(aggr, &mockUnitOfWork{}).Replay(
+				context.Background(), &ReplayPayload{Ops: tt.ops, UserID: userID},
+			)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, got)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, got, len(tt.want))
+			for i, want := range tt.want {
+				assert.Equal(t, want.OpID, got[i].OpID)
+				assert.Equal(t, want.Status, got[i].Status)
+				assert.Equal(t, want.ID, got[i].ID)
+				assert.Equal(t, want.Version, got[i].Version)
+				if want.Err != nil {
+					assert.ErrorIs(t, got[i].Err, want.Err)
+				} else {
+					assert.NoError(t, got[i].Err)
+				}
+			}
+		})
+	}
+}
+
 func TestService_Push(t *testing.T) {
 	t.Parallel()
 
@@ -415,10 +743,13 @@ func TestService_Push(t *testing.T) {
 				tt.noteService,
 				tt.fileDataService,
 			)
-			service := NewService(aggr)
+			uow := &mockUnitOfWork{}
+			service := NewService(aggr, uow)
 
 			err := service.Push(context.Background(), tt.payload)
 
+			assert.Equal(t, 1, uow.calls)
+			assert.Equal(t, tt.payload.UserID, uow.userID)
 			if tt.wantErr {
 				require.Error(t, err)
 				if tt.errContains != "" {
@@ -430,3 +761,244 @@ func TestService_Push(t *testing.T) {
 		})
 	}
 }
+
+func TestService_PushUnitOfWorkError(t *testing.T) {
+	t.Parallel()
+
+	txErr := errors.New("transaction failed")
+	aggr := NewServicesAggregator(
+		&mockBankCardService{}, &mockCredentialService{}, &mockNoteService{}, &mockFileDataService{},
+	)
+
+	err := NewService(aggr, &mockUnitOfWork{err: txErr}).Push(context.Background(), &SyncPayload{
+		UserID:    uuid.New(),
+		BankCards: []*bankcard.BankCard{{ID: uuid.New()}},
+	})
+
+	require.ErrorIs(t, err, txErr)
+	assert.Contains(t, err.Error(), "failed to push data")
+}
+
+func TestService_Capture(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	itemID, fileID := uuid.New(), uuid.New()
+	scans := []*filedata.FileData{
+		{StorageKey: "passport-front.jpg", Data: []byte("front")},
+		{StorageKey: "passport-back.jpg", Data: []byte("back")},
+	}
+	pushErr := errors.New("push failed")
+
+	tests := []struct {
+		payload     *CapturePayload
+		noteSvc     *mockNoteService
+		fileSvc     *mockFileDataService
+		wantErr     error
+		name        string
+		wantFileIDs []uuid.UUID
+		wantTx      int
+	}{
+		{
+			name:        "note with scans",
+			payload:     &CapturePayload{UserID: userID, Note: &note.Note{Note: "passport"}, Files: scans},
+			noteSvc:     &mockNoteService{pushResult: itemID},
+			fileSvc:     &mockFileDataService{pushResult: fileID},
+			wantFileIDs: []uuid.UUID{fileID, fileID},
+			wantTx:      1,
+		},
+		{
+			name:    "no item",
+			payload: &CapturePayload{UserID: userID, Files: scans},
+			noteSvc: &mockNoteService{},
+			fileSvc: &mockFileDataService{},
+			wantErr: ErrCaptureItemRequired,
+		},
+		{
+			name: "two items",
+			payload: &CapturePayload{
+				UserID: userID, Note: &note.Note{}, Credential: &credential.Credential{}, Files: scans,
+			},
+			noteSvc: &mockNoteService{},
+			fileSvc: &mockFileDataService{},
+			wantErr: ErrCaptureItemRequired,
+		},
+		{
+			name:    "no files",
+			payload: &CapturePayload{UserID: userID, Note: &note.Note{}},
+			noteSvc: &mockNoteService{},
+			fileSvc: &mockFileDataService{},
+			wantErr: ErrCaptureFilesRequired,
+		},
+		{
+			name:    "item failure",
+			payload: &CapturePayload{UserID: userID, Note: &note.Note{}, Files: scans},
+			noteSvc: &mockNoteService{pushError: pushErr},
+			fileSvc: &mockFileDataService{},
+			wantErr: pushErr,
+			wantTx:  1,
+		},
+		{
+			name:    "file failure",
+			payload: &CapturePayload{UserID: userID, Note: &note.Note{}, Files: scans},
+			noteSvc: &mockNoteService{pushResult: itemID},
+			fileSvc: &mockFileDataService{pushError: pushErr},
+			wantErr: pushErr,
+			wantTx:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			uow := &mockUnitOfWork{}
+			aggr := NewServicesAggregator(&mockBankCardService{}, &mockCredentialService{}, tt.noteSvc, tt.fileSvc)
+
+			got, err := NewService(aggr, uow).Capture(context.Background(), tt.payload)
+
+			assert.Equal(t, tt.wantTx, uow.calls)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, got)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, userID, uow.userID)
+			assert.Equal(t, itemID, got.ItemID)
+			assert.Equal(t, tt.wantFileIDs, got.FileIDs)
+		})
+	}
+}
+
+func TestService_Stream(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	cards := []*bankcard.BankCard{{ID: uuid.New()}, {ID: uuid.New()}}
+	// creds spans more than two stream pages.
+	creds := make([]*credential.Credential, 2*streamPageSize+1)
+	for i := range creds {
+		creds[i] = &credential.Credential{ID: uuid.New()}
+	}
+	files := []*filedata.FileData{{ID: uuid.New()}}
+	errEmit := errors.New("client gone")
+
+	tests := []struct {
+		bankcardService   *mockBankCardService
+		credentialService *mockCredentialService
+		emitErr           error
+		expectedErr       error
+		name              string
+		expectedCounts    [4]int
+		wantErr           bool
+	}{
+		{
+			name:              "streams every kind page by page",
+			bankcardService:   &mockBankCardService{listResult: cards},
+			credentialService: &mockCredentialService{listResult: creds},
+			expectedCounts:    [4]int{len(cards), len(creds), 0, len(files)},
+		},
+		{
+			name:              "list error",
+			bankcardService:   &mockBankCardService{listResult: cards},
+			credentialService: &mockCredentialService{listError: errors.New("load failed")},
+			expectedCounts:    [4]int{len(cards), 0, 0, 0},
+			wantErr:           true,
+		},
+		{
+			name:              "emit error stops the stream",
+			bankcardService:   &mockBankCardService{listResult: cards},
+			credentialService: &mockCredentialService{listResult: creds},
+			emitErr:           errEmit,
+			expectedErr:       errEmit,
+			expectedCounts:    [4]int{1, 0, 0, 0},
+			wantErr:           true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			service := NewService(NewServicesAggregator(
+				tt.bankcardService,
+				tt.credentialService,
+				&mockNoteService{},
+				&mockFileDataService{listResult: files},
+			), &mockUnitOfWork{})
+
+			// counts holds the number of emitted bank cards, credentials, notes and files.
+			var counts [4]int
+			// credIDs holds the IDs of the emitted credentials in emission order.
+			var credIDs []string
+			err := service.Stream(context.Background(), userID, func(item *StreamItem) error {
+				switch {
+				case item.BankCard != nil:
+					counts[0]++
+				case item.Credential != nil:
+					counts[1]++
+					credIDs = append(credIDs, item.Credential.ID.String())
+				case item.Note != nil:
+					counts[2]++
+				case item.File != nil:
+					counts[3]++
+				}
+				return tt.emitErr
+			})
+
+			if tt.wantErr {
+				require.Error(t, err)
+				if tt.expectedErr != nil {
+					assert.ErrorIs(t, err, tt.expectedErr)
+				}
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.expectedCounts, counts)
+			assert.True(t, slices.IsSorted(credIDs), "credentials should be streamed in ID order")
+		})
+	}
+}
+
+func BenchmarkService_Pull(b *testing.B) {
+	const items = 1000
+	userID := uuid.New()
+	now := time.Now()
+	cards := make([]*bankcard.BankCard, items)
+	creds := make([]*credential.Credential, items)
+	notes := make([]*note.Note, items)
+	files := make([]*filedata.FileData, items)
+	for i := range items {
+		cards[i] = &bankcard.BankCard{
+			ID: uuid.New(), UserID: userID, CardNumber: "4111111111111111", CardHolder: "John Doe",
+			ExpiryMonth: "12", ExpiryYear: "2030", CVV: "123", UpdatedAt: now,
+		}
+		creds[i] = &credential.Credential{
+			ID: uuid.New(), UserID: userID, Login: "user@example.com", Password: "secret", UpdatedAt: now,
+		}
+		notes[i] = &note.Note{ID: uuid.New(), UserID: userID, Note: string(make([]byte, 1024)), UpdatedAt: now}
+		files[i] = &filedata.FileData{ID: uuid.New(), UserID: userID, StorageKey: "file.bin", UpdatedAt: now}
+	}
+	service := NewService(NewServicesAggregator(
+		&mockBankCardService{listResult: cards},
+		&mockCredentialService{listResult: creds},
+		&mockNoteService{listResult: notes},
+		&mockFileDataService{listResult: files},
+	), &mockUnitOfWork{})
+
+	b.Run("payload", func(b *testing.B) {
+		for range b.N {
+			if _, err := service.Pull(context.Background(), userID); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("manifest", func(b *testing.B) {
+		for range b.N {
+			if _, err := service.Manifest(context.Background(), userID); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
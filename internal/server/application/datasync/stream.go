@@ -0,0 +1,88 @@
+package datasync
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/bankcard"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/credential"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/filedata"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/note"
+	"github.com/google/uuid"
+)
+
+// streamPageSize is the number of items of one kind Stream loads and decrypts at a time.
+const streamPageSize = 500
+
+// StreamItem represents one item of a streamed pull; exactly one of its fields is set.
+type StreamItem struct {
+	// BankCard contains the streamed bank card.
+	BankCard *bankcard.BankCard
+	// Credential contains the streamed credential.
+	Credential *credential.Credential
+	// Note contains the streamed note.
+	Note *note.Note
+	// File contains the metadata of the streamed file.
+	File *filedata.FileData
+}
+
+// Stream passes every item of the user to emit one at a time: bank cards, credentials, notes and then files,
+// each kind ordered by ID. Items are loaded a page at a time and the next page is loaded only after emit has
+// returned for every item of the previous one, so a slow consumer holds the loading back and at most one page
+// is held in memory. Pages are read by separate queries, so an item changed while the stream runs may appear
+// in either its old or its new state. An error returned by emit stops the stream.
+func (s *Service) Stream(ctx context.Context, userID uuid.UUID, emit func(*StreamItem) error) error {
+	if err := streamPages(ctx, userID, s.aggr.PullBankCardsPage,
+		func(c *bankcard.BankCard) uuid.UUID { return c.ID },
+		func(c *bankcard.BankCard) error { return emit(&StreamItem{BankCard: c}) },
+	); err != nil {
+		return fmt.Errorf("failed to stream bank cards: %w", err)
+	}
+	if err := streamPages(ctx, userID, s.aggr.PullCredentialsPage,
+		func(c *credential.Credential) uuid.UUID { return c.ID },
+		func(c *credential.Credential) error { return emit(&StreamItem{Credential: c}) },
+	); err != nil {
+		return fmt.Errorf("failed to stream credentials: %w", err)
+	}
+	if err := streamPages(ctx, userID, s.aggr.PullNotesPage,
+		func(n *note.Note) uuid.UUID { return n.ID },
+		func(n *note.Note) error { return emit(&StreamItem{Note: n}) },
+	); err != nil {
+		return fmt.Errorf("failed to stream notes: %w", err)
+	}
+	if err := streamPages(ctx, userID, s.aggr.PullFilesPage,
+		func(f *filedata.FileData) uuid.UUID { return f.ID },
+		func(f *filedata.FileData) error { return emit(&StreamItem{File: f}) },
+	); err != nil {
+		return fmt.Errorf("failed to stream files: %w", err)
+	}
+	return nil
+}
+
+// streamPages loads the items of one kind page by page with load and passes each of them to emit;
+// id returns the item ID the next page starts after.
+func streamPages[T any](
+	ctx context.Context,
+	userID uuid.UUID,
+	load func(ctx context.Context, userID, after uuid.UUID, limit int) ([]T, error),
+	id func(T) uuid.UUID,
+	emit func(T) error,
+) error {
+	// after holds the ID of the last emitted item.
+	var after uuid.UUID
+	for {
+		page, err := load(ctx, userID, after, streamPageSize)
+		if err != nil {
+			return err
+		}
+		for _, item := range page {
+			if err := emit(item); err != nil {
+				return err
+			}
+		}
+		if len(page) < streamPageSize {
+			return nil
+		}
+		after = id(page[len(page)-1])
+	}
+}
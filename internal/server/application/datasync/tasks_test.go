@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -71,10 +72,10 @@ func TestService_makePullBankCardsTask(t *testing.T) {
 				&mockNoteService{},
 				&mockFileDataService{},
 			)
-			service := NewService(aggr)
+			service := NewService(aggr, &mockUnitOfWork{})
 
 			var target []*bankcard.BankCard
-			task := service.makePullBankCardsTask(context.Background(), tt.userID, &target)
+			task := service.makePullBankCardsTask(context.Background(), tt.userID, time.Time{}, &target)
 
 			err := task()
 
@@ -147,10 +148,10 @@ func TestService_makePullCredentialsTask(t *testing.T) {
 				&mockNoteService{},
 				&mockFileDataService{},
 			)
-			service := NewService(aggr)
+			service := NewService(aggr, &mockUnitOfWork{})
 
 			var target []*credential.Credential
-			task := service.makePullCredentialsTask(context.Background(), tt.userID, &target)
+			task := service.makePullCredentialsTask(context.Background(), tt.userID, time.Time{}, &target)
 
 			err := task()
 
@@ -223,10 +224,10 @@ func TestService_makePullNotesTask(t *testing.T) {
 				tt.noteService,
 				&mockFileDataService{},
 			)
-			service := NewService(aggr)
+			service := NewService(aggr, &mockUnitOfWork{})
 
 			var target []*note.Note
-			task := service.makePullNotesTask(context.Background(), tt.userID, &target)
+			task := service.makePullNotesTask(context.Background(), tt.userID, time.Time{}, &target)
 
 			err := task()
 
@@ -299,7 +300,7 @@ func TestService_makePullFilesTask(t *testing.T) {
 				&mockNoteService{},
 				tt.fileDataService,
 			)
-			service := NewService(aggr)
+			service := NewService(aggr, &mockUnitOfWork{})
 
 			var target []*filedata.FileData
 			task := service.makePullFilesTask(context.Background(), tt.userID, &target)
@@ -375,7 +376,7 @@ func TestService_makePushBankCardsTask(t *testing.T) {
 				&mockNoteService{},
 				&mockFileDataService{},
 			)
-			service := NewService(aggr)
+			service := NewService(aggr, &mockUnitOfWork{})
 
 			task := service.makePushBankCardsTask(context.Background(), tt.userID, tt.cards)
 
@@ -449,7 +450,7 @@ func TestService_makePushCredentialsTask(t *testing.T) {
 				&mockNoteService{},
 				&mockFileDataService{},
 			)
-			service := NewService(aggr)
+			service := NewService(aggr, &mockUnitOfWork{})
 
 			task := service.makePushCredentialsTask(context.Background(), tt.userID, tt.credentials)
 
@@ -523,7 +524,7 @@ func TestService_makePushNotesTask(t *testing.T) {
 				tt.noteService,
 				&mockFileDataService{},
 			)
-			service := NewService(aggr)
+			service := NewService(aggr, &mockUnitOfWork{})
 
 			task := service.makePushNotesTask(context.Background(), tt.userID, tt.notes)
 
@@ -597,7 +598,7 @@ func TestService_makePushFilesTask(t *testing.T) {
 				&mockNoteService{},
 				tt.fileDataService,
 			)
-			service := NewService(aggr)
+			service := NewService(aggr, &mockUnitOfWork{})
 
 			task := service.makePushFilesTask(context.Background(), tt.userID, tt.files)
 
@@ -0,0 +1,5 @@
+// Package devicekey provides device key application services for the AegisVaultKeeper server.
+//
+// This package implements management of the X25519 public keys devices of users register, and
+// resolution of those keys for encrypting sync payloads to a single device.
+package devicekey
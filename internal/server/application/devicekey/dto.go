@@ -0,0 +1,74 @@
+package devicekey
+
+import (
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/devicekey"
+	"github.com/google/uuid"
+)
+
+// DeviceKey represents a device key data transfer object for application layer communication.
+type DeviceKey struct {
+	// CreatedAt indicates when the key was registered.
+	CreatedAt time.Time
+	// Name labels the device holding the private key.
+	Name string
+	// PublicKey contains the X25519 public key of the device.
+	PublicKey []byte
+	// ID identifies the key in requests for encrypted payloads.
+	ID uuid.UUID
+}
+
+// newDeviceKeyFromDomain converts a domain device key entity to application DTO.
+func newDeviceKeyFromDomain(k *devicekey.DeviceKey) *DeviceKey {
+	if k == nil {
+		return nil
+	}
+	return &DeviceKey{
+		ID:        k.ID,
+		Name:      k.Name,
+		PublicKey: k.PublicKey,
+		CreatedAt: k.CreatedAt,
+	}
+}
+
+// newDeviceKeysFromDomain converts a slice of domain device key entities to application DTOs.
+func newDeviceKeysFromDomain(ks []*devicekey.DeviceKey) []*DeviceKey {
+	result := make([]*DeviceKey, 0, len(ks))
+	for _, k := range ks {
+		result = append(result, newDeviceKeyFromDomain(k))
+	}
+	return result
+}
+
+// RegisterParams contains parameters for registering a device key.
+type RegisterParams struct {
+	// Name labels the device holding the private key.
+	Name string
+	// PublicKey contains the X25519 public key of the device.
+	PublicKey []byte
+	// UserID identifies the owner of the key.
+	UserID uuid.UUID
+}
+
+// ListParams contains parameters for listing device keys.
+type ListParams struct {
+	// UserID identifies the owner of the keys.
+	UserID uuid.UUID
+}
+
+// DeleteParams contains parameters for revoking a device key.
+type DeleteParams struct {
+	// ID identifies the key to revoke.
+	ID uuid.UUID
+	// UserID identifies the owner of the key.
+	UserID uuid.UUID
+}
+
+// PublicKeyParams contains parameters for resolving the public key of a device key.
+type PublicKeyParams struct {
+	// ID identifies the key named by the request.
+	ID uuid.UUID
+	// UserID identifies the owner of the key.
+	UserID uuid.UUID
+}
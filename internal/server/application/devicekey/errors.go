@@ -0,0 +1,56 @@
+package devicekey
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/errutil"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/devicekey"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/devicekey"
+)
+
+// Device key error definitions.
+var (
+	// ErrDeviceKeyAppError indicates a general device key application error.
+	ErrDeviceKeyAppError = errors.New("device key application error")
+
+	// ErrDeviceKeyTechError indicates a technical error in the device key system.
+	ErrDeviceKeyTechError = errors.New("device key technical error")
+
+	// ErrDeviceKeyIncorrectName indicates an incorrect device key name was provided.
+	ErrDeviceKeyIncorrectName = errors.New("incorrect device key name")
+
+	// ErrDeviceKeyIncorrectPublicKey indicates a device public key that is not a usable X25519 key.
+	ErrDeviceKeyIncorrectPublicKey = errors.New("incorrect device public key")
+
+	// ErrDeviceKeyNotFound indicates the requested device key was not found.
+	ErrDeviceKeyNotFound = errors.New("device key not found")
+)
+
+// mapError maps domain and repository errors to application-level errors.
+func mapError(err error) error {
+	if err == nil {
+		return nil
+	}
+	mapped := errutil.MapError(mapFn, err)
+	if mapped != nil {
+		return fmt.Errorf("device key error mapping failed: %w", mapped)
+	}
+	return nil
+}
+
+// mapFn provides the actual error mapping logic for different error types.
+func mapFn(err error) error {
+	switch {
+	case errors.Is(err, devicekey.ErrNewDeviceKeyParamsValidation):
+		return ErrDeviceKeyAppError
+	case errors.Is(err, devicekey.ErrIncorrectName):
+		return ErrDeviceKeyIncorrectName
+	case errors.Is(err, devicekey.ErrIncorrectPublicKey):
+		return ErrDeviceKeyIncorrectPublicKey
+	case errors.Is(err, repository.ErrDeviceKeyNotFound):
+		return ErrDeviceKeyNotFound
+	default:
+		return errors.Join(ErrDeviceKeyTechError, err)
+	}
+}
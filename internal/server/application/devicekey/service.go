@@ -0,0 +1,96 @@
+package devicekey
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/audit"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/devicekey"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/devicekey"
+)
+
+// Repository defines the interface for device key persistence operations.
+type Repository interface {
+	// Save persists a device key using the provided parameters.
+	Save(ctx context.Context, params repository.SaveParams) error
+	// Load retrieves device keys using the provided parameters.
+	Load(ctx context.Context, params repository.LoadParams) ([]*devicekey.DeviceKey, error)
+	// Delete removes a device key using the provided parameters.
+	Delete(ctx context.Context, params repository.DeleteParams) error
+}
+
+// AuditRecorder defines the interface for recording security audit events.
+type AuditRecorder interface {
+	// Record stores the audit event.
+	Record(ctx context.Context, e audit.Event)
+}
+
+// Service provides device key operations.
+type Service struct {
+	// r is the repository interface for device key persistence operations.
+	r Repository
+	// audit records key registrations and revocations.
+	audit AuditRecorder
+}
+
+// NewService creates a new device key service instance.
+func NewService(r Repository, audit AuditRecorder) *Service {
+	return &Service{r: r, audit: audit}
+}
+
+// Register stores the public key of a device of the user, so sync payloads can be encrypted for the device.
+func (s *Service) Register(ctx context.Context, params RegisterParams) (*DeviceKey, error) {
+	key, err := devicekey.NewDeviceKey(devicekey.NewDeviceKeyParams{
+		Name:      params.Name,
+		PublicKey: params.PublicKey,
+		UserID:    params.UserID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create new device key: %w", mapError(err))
+	}
+	if err := s.r.Save(ctx, repository.SaveParams{Entity: key}); err != nil {
+		return nil, fmt.Errorf("failed to save device key: %w", mapError(err))
+	}
+	s.audit.Record(ctx, audit.Event{
+		Type:    audit.EventDeviceKeyRegistered,
+		UserID:  key.UserID,
+		Details: map[string]string{"key_id": key.ID.String(), "name": key.Name},
+	})
+	return newDeviceKeyFromDomain(key), nil
+}
+
+// List retrieves the device keys of the user.
+func (s *Service) List(ctx context.Context, params ListParams) ([]*DeviceKey, error) {
+	keys, err := s.r.Load(ctx, repository.LoadParams{UserID: params.UserID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load device keys: %w", mapError(err))
+	}
+	return newDeviceKeysFromDomain(keys), nil
+}
+
+// Delete revokes a device key of the user; payloads are no longer encrypted for the device.
+func (s *Service) Delete(ctx context.Context, params DeleteParams) error {
+	err := s.r.Delete(ctx, repository.DeleteParams{ID: params.ID, UserID: params.UserID})
+	if err != nil {
+		return fmt.Errorf("failed to delete device key: %w", mapError(err))
+	}
+	s.audit.Record(ctx, audit.Event{
+		Type:    audit.EventDeviceKeyDeleted,
+		UserID:  params.UserID,
+		Details: map[string]string{"key_id": params.ID.String()},
+	})
+	return nil
+}
+
+// PublicKey returns the public key of a device key of the user.
+// Returns ErrDeviceKeyNotFound when the user has no such key.
+func (s *Service) PublicKey(ctx context.Context, params PublicKeyParams) ([]byte, error) {
+	keys, err := s.r.Load(ctx, repository.LoadParams{ID: params.ID, UserID: params.UserID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load device key: %w", mapError(err))
+	}
+	if len(keys) == 0 {
+		return nil, ErrDeviceKeyNotFound
+	}
+	return keys[0].PublicKey, nil
+}
@@ -0,0 +1,262 @@
+package devicekey
+
+import (
+	"context"
+	"crypto/ecdh"
+	"crypto/rand"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/audit"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/devicekey"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/devicekey"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockRepository implements Repository for testing.
+type mockRepository struct {
+	loadErr      error
+	saveErr      error
+	deleteErr    error
+	saved        *devicekey.DeviceKey
+	loadParams   repository.LoadParams
+	deleteParams repository.DeleteParams
+	keys         []*devicekey.DeviceKey
+}
+
+func (m *mockRepository) Save(_ context.Context, params repository.SaveParams) error {
+	m.saved = params.Entity
+	return m.saveErr
+}
+
+func (m *mockRepository) Load(_ context.Context, params repository.LoadParams) ([]*devicekey.DeviceKey, error) {
+	m.loadParams = params
+	return m.keys, m.loadErr
+}
+
+func (m *mockRepository) Delete(_ context.Context, params repository.DeleteParams) error {
+	m.deleteParams = params
+	return m.deleteErr
+}
+
+// mockAuditRecorder implements AuditRecorder for testing.
+type mockAuditRecorder struct {
+	events []audit.Event
+}
+
+func (m *mockAuditRecorder) Record(_ context.Context, e audit.Event) {
+	m.events = append(m.events, e)
+}
+
+func TestService_Register(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	key, err := ecdh.X25519().GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	publicKey := key.PublicKey().Bytes()
+
+	tests := []struct {
+		saveErr   error
+		wantErr   error
+		name      string
+		keyName   string
+		publicKey []byte
+	}{
+		{
+			name:      "key registered",
+			keyName:   "phone",
+			publicKey: publicKey,
+		},
+		{
+			name:      "blank name",
+			keyName:   " ",
+			publicKey: publicKey,
+			wantErr:   ErrDeviceKeyIncorrectName,
+		},
+		{
+			name:      "invalid public key",
+			keyName:   "phone",
+			publicKey: []byte("short"),
+			wantErr:   ErrDeviceKeyIncorrectPublicKey,
+		},
+		{
+			name:      "save error",
+			keyName:   "phone",
+			publicKey: publicKey,
+			saveErr:   errors.New("connection refused"),
+			wantErr:   ErrDeviceKeyTechError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &mockRepository{saveErr: tt.saveErr}
+			recorder := &mockAuditRecorder{}
+
+			got, err := NewService(repo, recorder).Register(context.Background(), RegisterParams{
+				Name:      tt.keyName,
+				PublicKey: tt.publicKey,
+				UserID:    userID,
+			})
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Empty(t, recorder.events)
+				return
+			}
+			require.NoError(t, err)
+			require.NotNil(t, repo.saved)
+			assert.Equal(t, repo.saved.ID, got.ID)
+			assert.Equal(t, tt.keyName, got.Name)
+			assert.Equal(t, publicKey, got.PublicKey)
+			assert.Equal(t, userID, repo.saved.UserID)
+			require.Len(t, recorder.events, 1)
+			assert.Equal(t, audit.EventDeviceKeyRegistered, recorder.events[0].Type)
+			assert.Equal(t, got.ID.String(), recorder.events[0].Details["key_id"])
+		})
+	}
+}
+
+func TestService_List(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	createdAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		loadErr error
+		name    string
+		keys    []*devicekey.DeviceKey
+		want    []*DeviceKey
+	}{
+		{
+			name: "keys listed",
+			keys: []*devicekey.DeviceKey{
+				{ID: uuid.Max, UserID: userID, Name: "phone", PublicKey: []byte("key"), CreatedAt: createdAt},
+			},
+			want: []*DeviceKey{{ID: uuid.Max, Name: "phone", PublicKey: []byte("key"), CreatedAt: createdAt}},
+		},
+		{
+			name: "no keys",
+			want: []*DeviceKey{},
+		},
+		{
+			name:    "load error",
+			loadErr: errors.New("connection refused"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &mockRepository{keys: tt.keys, loadErr: tt.loadErr}
+
+			got, err := NewService(repo, &mockAuditRecorder{}).List(context.Background(), ListParams{UserID: userID})
+
+			if tt.loadErr != nil {
+				require.ErrorIs(t, err, ErrDeviceKeyTechError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, repository.LoadParams{UserID: userID}, repo.loadParams)
+		})
+	}
+}
+
+func TestService_Delete(t *testing.T) {
+	t.Parallel()
+
+	params := DeleteParams{ID: uuid.New(), UserID: uuid.New()}
+
+	tests := []struct {
+		deleteErr error
+		wantErr   error
+		name      string
+	}{
+		{
+			name: "key revoked",
+		},
+		{
+			name:      "not found",
+			deleteErr: repository.ErrDeviceKeyNotFound,
+			wantErr:   ErrDeviceKeyNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &mockRepository{deleteErr: tt.deleteErr}
+			recorder := &mockAuditRecorder{}
+
+			err := NewService(repo, recorder).Delete(context.Background(), params)
+
+			assert.Equal(t, repository.DeleteParams{ID: params.ID, UserID: params.UserID}, repo.deleteParams)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Empty(t, recorder.events)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, recorder.events, 1)
+			assert.Equal(t, audit.EventDeviceKeyDeleted, recorder.events[0].Type)
+		})
+	}
+}
+
+func TestService_PublicKey(t *testing.T) {
+	t.Parallel()
+
+	params := PublicKeyParams{ID: uuid.New(), UserID: uuid.New()}
+
+	tests := []struct {
+		loadErr error
+		wantErr error
+		name    string
+		keys    []*devicekey.DeviceKey
+		want    []byte
+	}{
+		{
+			name: "key found",
+			keys: []*devicekey.DeviceKey{{ID: params.ID, PublicKey: []byte("key")}},
+			want: []byte("key"),
+		},
+		{
+			name:    "not found",
+			loadErr: repository.ErrDeviceKeyNotFound,
+			wantErr: ErrDeviceKeyNotFound,
+		},
+		{
+			name:    "load error",
+			loadErr: errors.New("connection refused"),
+			wantErr: ErrDeviceKeyTechError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &mockRepository{keys: tt.keys, loadErr: tt.loadErr}
+
+			got, err := NewService(repo, &mockAuditRecorder{}).PublicKey(context.Background(), params)
+
+			assert.Equal(t, repository.LoadParams{ID: params.ID, UserID: params.UserID}, repo.loadParams)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
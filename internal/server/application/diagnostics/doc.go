@@ -0,0 +1,5 @@
+// Package diagnostics provides runtime diagnostics application services for the AegisVaultKeeper server.
+//
+// This package reports the cipher selected for the server's CPU and measures encryption and
+// decryption throughput, so operators can validate the performance of an instance.
+package diagnostics
@@ -0,0 +1,34 @@
+package diagnostics
+
+import "github.com/gdyunin/aegis-vault-keeper/internal/server/crypto"
+
+// CipherThroughput contains the measured speed of a single cipher.
+type CipherThroughput struct {
+	// Cipher identifies the measured cipher.
+	Cipher string
+	// EncryptMBPerSec contains the encryption speed in megabytes per second.
+	EncryptMBPerSec float64
+	// DecryptMBPerSec contains the decryption speed in megabytes per second.
+	DecryptMBPerSec float64
+}
+
+// CryptoReport contains the cipher selection and measured throughput of the server instance.
+type CryptoReport struct {
+	// DefaultCipher identifies the cipher used to seal stored data.
+	DefaultCipher string
+	// Results contains the measured throughput of every supported cipher.
+	Results []CipherThroughput
+	// PayloadSize contains the size in bytes of the payloads encrypted during measurement.
+	PayloadSize int
+	// AESHardware determines whether the CPU accelerates AES-GCM.
+	AESHardware bool
+}
+
+// newCipherThroughputFromCrypto converts a crypto throughput measurement to an application DTO.
+func newCipherThroughputFromCrypto(t crypto.Throughput) CipherThroughput {
+	return CipherThroughput{
+		Cipher:          string(t.Cipher),
+		EncryptMBPerSec: t.EncryptBytesPerSec / bytesPerMB,
+		DecryptMBPerSec: t.DecryptBytesPerSec / bytesPerMB,
+	}
+}
@@ -0,0 +1,6 @@
+package diagnostics
+
+import "errors"
+
+// ErrDiagnosticsTechError indicates a technical error while collecting diagnostics.
+var ErrDiagnosticsTechError = errors.New("diagnostics technical error")
@@ -0,0 +1,56 @@
+package diagnostics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/crypto"
+)
+
+const (
+	// bytesPerMB converts bytes to megabytes.
+	bytesPerMB = 1 << 20
+	// benchmarkPayloadSize specifies the size of the payloads encrypted during measurement.
+	benchmarkPayloadSize = 1 << 20
+	// benchmarkDuration specifies how long each cipher encrypts and then decrypts during measurement.
+	benchmarkDuration = 200 * time.Millisecond
+)
+
+// Service measures the cryptographic performance of the server instance.
+type Service struct {
+	// payloadSize specifies the size of the payloads encrypted during measurement.
+	payloadSize int
+	// duration specifies how long each cipher encrypts and then decrypts during measurement.
+	duration time.Duration
+	// mu serializes measurements, so concurrent requests do not skew each other.
+	mu sync.Mutex
+}
+
+// NewService creates a new diagnostics service.
+func NewService() *Service {
+	return &Service{payloadSize: benchmarkPayloadSize, duration: benchmarkDuration}
+}
+
+// Crypto reports whether the CPU accelerates AES, the cipher selected for stored data and the measured
+// throughput of every supported cipher. A measurement takes about a second of CPU time.
+func (s *Service) Crypto(ctx context.Context) (*CryptoReport, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	report := &CryptoReport{
+		AESHardware:   crypto.HasAESHardware(),
+		DefaultCipher: string(crypto.DefaultCipher()),
+		PayloadSize:   s.payloadSize,
+	}
+	for _, c := range []crypto.Cipher{crypto.CipherAESGCM, crypto.CipherChaCha20Poly1305} {
+		t, err := crypto.MeasureThroughput(ctx, c, s.payloadSize, s.duration)
+		if err != nil {
+			return nil, fmt.Errorf("failed to measure cipher throughput: %w", errors.Join(ErrDiagnosticsTechError, err))
+		}
+		report.Results = append(report.Results, newCipherThroughputFromCrypto(t))
+	}
+	return report, nil
+}
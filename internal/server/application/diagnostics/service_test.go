@@ -0,0 +1,54 @@
+package diagnostics
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_Crypto(t *testing.T) {
+	t.Parallel()
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		ctx     context.Context
+		name    string
+		wantErr bool
+	}{
+		{name: "measures all ciphers", ctx: context.Background()},
+		{name: "canceled context", ctx: canceled, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := NewService()
+			s.payloadSize, s.duration = 4096, time.Millisecond
+
+			report, err := s.Crypto(tt.ctx)
+
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrDiagnosticsTechError)
+				require.ErrorIs(t, err, context.Canceled)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, crypto.HasAESHardware(), report.AESHardware)
+			assert.Equal(t, string(crypto.DefaultCipher()), report.DefaultCipher)
+			assert.Equal(t, 4096, report.PayloadSize)
+			require.Len(t, report.Results, 2)
+			for _, r := range report.Results {
+				assert.NotEmpty(t, r.Cipher)
+				assert.Positive(t, r.EncryptMBPerSec)
+				assert.Positive(t, r.DecryptMBPerSec)
+			}
+		})
+	}
+}
@@ -0,0 +1,5 @@
+// Package directory provides enterprise directory synchronization for the AegisVaultKeeper server.
+//
+// This package implements the provisioning and deprovisioning of users and groups pushed by
+// identity providers such as Okta or Azure AD over SCIM, mapped onto the authentication domain.
+package directory
@@ -0,0 +1,198 @@
+package directory
+
+import (
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/auth"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/group"
+	"github.com/google/uuid"
+)
+
+// User represents a directory user data transfer object for application layer communication.
+type User struct {
+	// UserName contains the login of the user.
+	UserName string
+	// ExternalID contains the identifier of the user in the enterprise directory.
+	ExternalID string
+	// ID uniquely identifies the user.
+	ID uuid.UUID
+	// Active reports whether the user may log in.
+	Active bool
+}
+
+// newUserFromDomain converts a domain user entity to application DTO without its credentials.
+func newUserFromDomain(u *auth.User) *User {
+	if u == nil {
+		return nil
+	}
+	return &User{
+		ID:         u.ID,
+		UserName:   u.Login,
+		ExternalID: u.ExternalID,
+		Active:     u.Active,
+	}
+}
+
+// UserPage holds a page of directory users.
+type UserPage struct {
+	// Users lists the users of the page.
+	Users []*User
+	// Total counts all users matching the listing parameters.
+	Total int
+}
+
+// Group represents a directory group data transfer object for application layer communication.
+type Group struct {
+	// CreatedAt indicates when the group was created.
+	CreatedAt time.Time
+	// UpdatedAt indicates when the group was last changed.
+	UpdatedAt time.Time
+	// DisplayName contains the unique human-readable name of the group.
+	DisplayName string
+	// ExternalID contains the identifier of the group in the enterprise directory.
+	ExternalID string
+	// Members lists the identifiers of the users in the group.
+	Members []uuid.UUID
+	// ID uniquely identifies the group.
+	ID uuid.UUID
+}
+
+// newGroupFromDomain converts a domain group entity to application DTO.
+func newGroupFromDomain(g *group.Group) *Group {
+	if g == nil {
+		return nil
+	}
+	return &Group{
+		ID:          g.ID,
+		DisplayName: g.DisplayName,
+		ExternalID:  g.ExternalID,
+		Members:     g.Members,
+		CreatedAt:   g.CreatedAt,
+		UpdatedAt:   g.UpdatedAt,
+	}
+}
+
+// GroupPage holds a page of directory groups.
+type GroupPage struct {
+	// Groups lists the groups of the page.
+	Groups []*Group
+	// Total counts all groups matching the listing parameters.
+	Total int
+}
+
+// CreateUserParams contains parameters for provisioning a user.
+type CreateUserParams struct {
+	// UserName contains the login of the user.
+	UserName string
+	// Password contains the initial password; a random one is generated when empty.
+	Password string
+	// ExternalID contains the identifier of the user in the enterprise directory.
+	ExternalID string
+	// Active reports whether the user may log in.
+	Active bool
+}
+
+// UpdateUserParams contains parameters for changing a provisioned user; nil fields are left unchanged.
+type UpdateUserParams struct {
+	// UserName contains the new login of the user.
+	UserName *string
+	// Password contains the new password of the user.
+	Password *string
+	// ExternalID contains the new identifier of the user in the enterprise directory.
+	ExternalID *string
+	// Active reports whether the user may log in.
+	Active *bool
+	// ID identifies the user.
+	ID uuid.UUID
+}
+
+// GetUserParams contains parameters for retrieving a provisioned user.
+type GetUserParams struct {
+	// ID identifies the user.
+	ID uuid.UUID
+}
+
+// ListUsersParams contains parameters for listing provisioned users.
+type ListUsersParams struct {
+	// UserName restricts the listing to the user with this login, when set.
+	UserName string
+	// ExternalID restricts the listing to users with this directory identifier, when set.
+	ExternalID string
+	// Offset specifies how many matching users to skip.
+	Offset int
+	// Limit specifies the maximum number of users to return.
+	Limit int
+}
+
+// DeleteUserParams contains parameters for deprovisioning a user.
+type DeleteUserParams struct {
+	// ID identifies the user.
+	ID uuid.UUID
+}
+
+// CreateGroupParams contains parameters for creating a group.
+type CreateGroupParams struct {
+	// DisplayName contains the unique human-readable name of the group.
+	DisplayName string
+	// ExternalID contains the identifier of the group in the enterprise directory.
+	ExternalID string
+	// Members lists the identifiers of the users in the group.
+	Members []uuid.UUID
+}
+
+// MemberOpKind names the kind of change made to the members of a group.
+type MemberOpKind int
+
+// Member change kinds.
+const (
+	// MemberOpAdd adds the users to the group.
+	MemberOpAdd MemberOpKind = iota
+	// MemberOpRemove removes the users from the group.
+	MemberOpRemove
+	// MemberOpReplace sets the members of the group to exactly the users.
+	MemberOpReplace
+)
+
+// MemberOp describes one change to the members of a group.
+type MemberOp struct {
+	// UserIDs lists the users the change applies to.
+	UserIDs []uuid.UUID
+	// Kind names the kind of change.
+	Kind MemberOpKind
+}
+
+// UpdateGroupParams contains parameters for changing a group; nil fields are left unchanged.
+type UpdateGroupParams struct {
+	// DisplayName contains the new display name of the group.
+	DisplayName *string
+	// ExternalID contains the new identifier of the group in the enterprise directory.
+	ExternalID *string
+	// MemberOps lists the changes to the members, applied in order.
+	MemberOps []MemberOp
+	// ID identifies the group.
+	ID uuid.UUID
+}
+
+// GetGroupParams contains parameters for retrieving a group.
+type GetGroupParams struct {
+	// ID identifies the group.
+	ID uuid.UUID
+}
+
+// ListGroupsParams contains parameters for listing groups.
+type ListGroupsParams struct {
+	// DisplayName restricts the listing to the group with this display name, when set.
+	DisplayName string
+	// ExternalID restricts the listing to groups with this directory identifier, when set.
+	ExternalID string
+	// Offset specifies how many matching groups to skip.
+	Offset int
+	// Limit specifies the maximum number of groups to return.
+	Limit int
+}
+
+// DeleteGroupParams contains parameters for deleting a group.
+type DeleteGroupParams struct {
+	// ID identifies the group.
+	ID uuid.UUID
+}
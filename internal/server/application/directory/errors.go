@@ -0,0 +1,86 @@
+package directory
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/errutil"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/auth"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/group"
+	repositoryAuth "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/auth"
+	repositoryGroup "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/group"
+)
+
+// Directory error definitions.
+var (
+	// ErrDirectoryAppError indicates a general directory application error.
+	ErrDirectoryAppError = errors.New("directory application error")
+
+	// ErrDirectoryTechError indicates a technical error in the directory system.
+	ErrDirectoryTechError = errors.New("directory technical error")
+
+	// ErrDirectoryIncorrectUserName indicates an incorrect user name was provided.
+	ErrDirectoryIncorrectUserName = errors.New("incorrect user name")
+
+	// ErrDirectoryIncorrectPassword indicates an incorrect password was provided.
+	ErrDirectoryIncorrectPassword = errors.New("incorrect password")
+
+	// ErrDirectoryIncorrectDisplayName indicates an incorrect group display name was provided.
+	ErrDirectoryIncorrectDisplayName = errors.New("incorrect group display name")
+
+	// ErrDirectoryUserNotFound indicates the requested user was not found or was deprovisioned.
+	ErrDirectoryUserNotFound = errors.New("user not found")
+
+	// ErrDirectoryUserAlreadyExists indicates a user already exists with the given user name.
+	ErrDirectoryUserAlreadyExists = errors.New("user already exists")
+
+	// ErrDirectoryGroupNotFound indicates the requested group was not found.
+	ErrDirectoryGroupNotFound = errors.New("group not found")
+
+	// ErrDirectoryGroupAlreadyExists indicates a group already exists with the given display name.
+	ErrDirectoryGroupAlreadyExists = errors.New("group already exists")
+
+	// ErrDirectoryMemberNotFound indicates a group member references an unknown user.
+	ErrDirectoryMemberNotFound = errors.New("group member not found")
+)
+
+// mapError maps domain and repository errors to application-level errors.
+func mapError(err error) error {
+	if err == nil {
+		return nil
+	}
+	mapped := errutil.MapError(mapFn, err)
+	if mapped != nil {
+		return fmt.Errorf("directory error mapping failed: %w", mapped)
+	}
+	return nil
+}
+
+// mapFn provides the actual error mapping logic for different error types.
+func mapFn(err error) error {
+	switch {
+	case errors.Is(err, auth.ErrNewUserParamsValidation),
+		errors.Is(err, group.ErrNewGroupParamsValidation):
+		return ErrDirectoryAppError
+	case errors.Is(err, auth.ErrIncorrectLogin):
+		return ErrDirectoryIncorrectUserName
+	case errors.Is(err, auth.ErrIncorrectPassword),
+		errors.Is(err, auth.ErrPasswordTooShort),
+		errors.Is(err, auth.ErrPasswordTooLong):
+		return ErrDirectoryIncorrectPassword
+	case errors.Is(err, group.ErrIncorrectDisplayName):
+		return ErrDirectoryIncorrectDisplayName
+	case errors.Is(err, repositoryAuth.ErrUserNotFound), errors.Is(err, ErrDirectoryUserNotFound):
+		return ErrDirectoryUserNotFound
+	case errors.Is(err, repositoryAuth.ErrUserAlreadyExists):
+		return ErrDirectoryUserAlreadyExists
+	case errors.Is(err, repositoryGroup.ErrGroupNotFound):
+		return ErrDirectoryGroupNotFound
+	case errors.Is(err, repositoryGroup.ErrGroupAlreadyExists):
+		return ErrDirectoryGroupAlreadyExists
+	case errors.Is(err, repositoryGroup.ErrGroupMemberNotFound):
+		return ErrDirectoryMemberNotFound
+	default:
+		return errors.Join(ErrDirectoryTechError, err)
+	}
+}
@@ -0,0 +1,375 @@
+package directory
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/audit"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/auth"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/group"
+	repositoryAuth "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/auth"
+	repositoryGroup "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/group"
+	"github.com/google/uuid"
+)
+
+// generatedPasswordSize is the number of random bytes in passwords generated for users provisioned without one.
+const generatedPasswordSize = 24
+
+// UserRepository defines the interface for user persistence operations.
+type UserRepository interface {
+	// Save persists user data using the provided parameters.
+	Save(ctx context.Context, params repositoryAuth.SaveParams) error
+	// Load retrieves user data using the provided parameters.
+	Load(ctx context.Context, params repositoryAuth.LoadParams) (*auth.User, error)
+	// List retrieves a page of provisioned users and the number of matching users.
+	List(ctx context.Context, params repositoryAuth.ListParams) ([]*auth.User, int, error)
+}
+
+// GroupRepository defines the interface for group persistence operations.
+type GroupRepository interface {
+	// Save creates or updates a group without its members.
+	Save(ctx context.Context, params repositoryGroup.SaveParams) error
+	// Load retrieves a page of groups with their members and the number of matching groups.
+	Load(ctx context.Context, params repositoryGroup.LoadParams) ([]*group.Group, int, error)
+	// ReplaceMembers sets the members of a group.
+	ReplaceMembers(ctx context.Context, params repositoryGroup.MembersParams) error
+	// AddMembers adds users to a group.
+	AddMembers(ctx context.Context, params repositoryGroup.MembersParams) error
+	// RemoveMembers removes users from a group.
+	RemoveMembers(ctx context.Context, params repositoryGroup.MembersParams) error
+	// Delete removes a group with its memberships.
+	Delete(ctx context.Context, params repositoryGroup.DeleteParams) error
+}
+
+// UnitOfWork defines the interface for running several repository writes as one transaction.
+type UnitOfWork interface {
+	// Do executes fn in a single transaction scoped to the user, rolling back every write on error.
+	Do(ctx context.Context, userID uuid.UUID, fn func(ctx context.Context) error) error
+}
+
+// AuditRecorder defines the interface for recording security audit events.
+type AuditRecorder interface {
+	// Record stores the audit event.
+	Record(ctx context.Context, e audit.Event)
+}
+
+// Service provides enterprise directory provisioning operations.
+type Service struct {
+	// users is the repository interface for user persistence operations.
+	users UserRepository
+	// groups is the repository interface for group persistence operations.
+	groups GroupRepository
+	// uow groups the writes of a group change into a single transaction.
+	uow UnitOfWork
+	// hasher hashes the passwords of provisioned users.
+	hasher auth.PasswordHasher
+	// cryptoKeyGenerator generates the encryption keys of provisioned users.
+	cryptoKeyGenerator auth.CryptoKeyGenerator
+	// audit records provisioning changes.
+	audit AuditRecorder
+}
+
+// NewService creates a new directory service instance with the provided dependencies.
+func NewService(
+	users UserRepository,
+	groups GroupRepository,
+	uow UnitOfWork,
+	hasher auth.PasswordHasher,
+	cryptoKeyGenerator auth.CryptoKeyGenerator,
+	audit AuditRecorder,
+) *Service {
+	return &Service{
+		users:              users,
+		groups:             groups,
+		uow:                uow,
+		hasher:             hasher,
+		cryptoKeyGenerator: cryptoKeyGenerator,
+		audit:              audit,
+	}
+}
+
+// CreateUser provisions a new user. Users provisioned without a password get a random one,
+// so they can only sign in through the identity provider.
+func (s *Service) CreateUser(ctx context.Context, params CreateUserParams) (*User, error) {
+	password := params.Password
+	if password == "" {
+		b := make([]byte, generatedPasswordSize)
+		if _, err := rand.Read(b); err != nil {
+			return nil, fmt.Errorf("failed to generate password: %w", mapError(err))
+		}
+		password = base64.RawURLEncoding.EncodeToString(b)
+	}
+
+	u, err := auth.NewUser(
+		auth.NewUserParams{Login: params.UserName, Password: password},
+		s.hasher,
+		s.cryptoKeyGenerator,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create new user: %w", mapError(err))
+	}
+	defer u.Wipe()
+	u.ExternalID = params.ExternalID
+	u.Active = params.Active
+
+	if err := s.users.Save(ctx, repositoryAuth.SaveParams{Entity: u}); err != nil {
+		return nil, fmt.Errorf("failed to save user: %w", mapError(err))
+	}
+
+	s.audit.Record(ctx, audit.Event{
+		Type:    audit.EventUserProvisioned,
+		UserID:  u.ID,
+		Details: map[string]string{"login": u.Login, "external_id": u.ExternalID},
+	})
+	return newUserFromDomain(u), nil
+}
+
+// GetUser retrieves a provisioned user.
+func (s *Service) GetUser(ctx context.Context, params GetUserParams) (*User, error) {
+	u, err := s.loadUser(ctx, params.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer u.Wipe()
+
+	return newUserFromDomain(u), nil
+}
+
+// ListUsers retrieves a page of provisioned users.
+func (s *Service) ListUsers(ctx context.Context, params ListUsersParams) (*UserPage, error) {
+	users, total, err := s.users.List(ctx, repositoryAuth.ListParams{
+		Login:      params.UserName,
+		ExternalID: params.ExternalID,
+		Offset:     params.Offset,
+		Limit:      params.Limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", mapError(err))
+	}
+
+	page := &UserPage{Users: make([]*User, 0, len(users)), Total: total}
+	for _, u := range users {
+		page.Users = append(page.Users, newUserFromDomain(u))
+	}
+	return page, nil
+}
+
+// UpdateUser changes the login, password, directory identifier or activation of a provisioned user.
+// Deactivated users cannot log in; access tokens issued before stay valid until they expire.
+func (s *Service) UpdateUser(ctx context.Context, params UpdateUserParams) (*User, error) {
+	u, err := s.loadUser(ctx, params.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer u.Wipe()
+
+	if params.UserName != nil {
+		if err := u.SetLogin(*params.UserName); err != nil {
+			return nil, fmt.Errorf("failed to change user name: %w", mapError(err))
+		}
+	}
+	if params.Password != nil {
+		if err := u.SetPassword(s.hasher, *params.Password); err != nil {
+			return nil, fmt.Errorf("failed to change password: %w", mapError(err))
+		}
+	}
+	if params.ExternalID != nil {
+		u.ExternalID = *params.ExternalID
+	}
+	if params.Active != nil {
+		u.Active = *params.Active
+	}
+
+	if err := s.users.Save(ctx, repositoryAuth.SaveParams{Entity: u}); err != nil {
+		return nil, fmt.Errorf("failed to save user: %w", mapError(err))
+	}
+
+	s.audit.Record(ctx, audit.Event{
+		Type:    audit.EventUserUpdated,
+		UserID:  u.ID,
+		Details: map[string]string{"login": u.Login, "active": fmt.Sprint(u.Active)},
+	})
+	return newUserFromDomain(u), nil
+}
+
+// DeleteUser deprovisions a user. The user is deactivated and hidden from the directory, while the
+// user's vault is retained for recovery by an administrator.
+func (s *Service) DeleteUser(ctx context.Context, params DeleteUserParams) error {
+	u, err := s.loadUser(ctx, params.ID)
+	if err != nil {
+		return err
+	}
+	defer u.Wipe()
+
+	u.Deprovision(time.Now())
+	if err := s.users.Save(ctx, repositoryAuth.SaveParams{Entity: u}); err != nil {
+		return fmt.Errorf("failed to save user: %w", mapError(err))
+	}
+
+	s.audit.Record(ctx, audit.Event{
+		Type:    audit.EventUserDeprovisioned,
+		UserID:  u.ID,
+		Details: map[string]string{"login": u.Login},
+	})
+	return nil
+}
+
+// CreateGroup creates a group with its members.
+func (s *Service) CreateGroup(ctx context.Context, params CreateGroupParams) (*Group, error) {
+	g, err := group.NewGroup(group.NewGroupParams{
+		DisplayName: params.DisplayName,
+		ExternalID:  params.ExternalID,
+		Members:     params.Members,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create new group: %w", mapError(err))
+	}
+
+	var created *group.Group
+	err = s.uow.Do(ctx, uuid.Nil, func(ctx context.Context) error {
+		if err := s.groups.Save(ctx, repositoryGroup.SaveParams{Entity: g}); err != nil {
+			return err
+		}
+		members := repositoryGroup.MembersParams{GroupID: g.ID, UserIDs: g.Members}
+		if err := s.groups.AddMembers(ctx, members); err != nil {
+			return err
+		}
+		created, err = s.loadGroup(ctx, g.ID)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save group: %w", mapError(err))
+	}
+
+	s.audit.Record(ctx, audit.Event{
+		Type:    audit.EventGroupCreated,
+		Details: map[string]string{"group_id": created.ID.String(), "display_name": created.DisplayName},
+	})
+	return newGroupFromDomain(created), nil
+}
+
+// GetGroup retrieves a group with its members.
+func (s *Service) GetGroup(ctx context.Context, params GetGroupParams) (*Group, error) {
+	g, err := s.loadGroup(ctx, params.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load group: %w", mapError(err))
+	}
+	return newGroupFromDomain(g), nil
+}
+
+// ListGroups retrieves a page of groups with their members.
+func (s *Service) ListGroups(ctx context.Context, params ListGroupsParams) (*GroupPage, error) {
+	groups, total, err := s.groups.Load(ctx, repositoryGroup.LoadParams{
+		DisplayName: params.DisplayName,
+		ExternalID:  params.ExternalID,
+		Offset:      params.Offset,
+		Limit:       params.Limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list groups: %w", mapError(err))
+	}
+
+	page := &GroupPage{Groups: make([]*Group, 0, len(groups)), Total: total}
+	for _, g := range groups {
+		page.Groups = append(page.Groups, newGroupFromDomain(g))
+	}
+	return page, nil
+}
+
+// UpdateGroup changes the display name, directory identifier or members of a group in one transaction.
+func (s *Service) UpdateGroup(ctx context.Context, params UpdateGroupParams) (*Group, error) {
+	var updated *group.Group
+	err := s.uow.Do(ctx, uuid.Nil, func(ctx context.Context) error {
+		g, err := s.loadGroup(ctx, params.ID)
+		if err != nil {
+			return err
+		}
+
+		if params.DisplayName != nil {
+			if err := g.Rename(*params.DisplayName); err != nil {
+				return err
+			}
+		}
+		if params.ExternalID != nil {
+			g.ExternalID = *params.ExternalID
+		}
+		g.UpdatedAt = time.Now()
+		if err := s.groups.Save(ctx, repositoryGroup.SaveParams{Entity: g}); err != nil {
+			return err
+		}
+
+		for _, op := range params.MemberOps {
+			if err := s.applyMemberOp(ctx, g.ID, op); err != nil {
+				return err
+			}
+		}
+
+		updated, err = s.loadGroup(ctx, g.ID)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update group: %w", mapError(err))
+	}
+
+	s.audit.Record(ctx, audit.Event{
+		Type:    audit.EventGroupUpdated,
+		Details: map[string]string{"group_id": updated.ID.String(), "display_name": updated.DisplayName},
+	})
+	return newGroupFromDomain(updated), nil
+}
+
+// DeleteGroup removes a group. Its members are not affected.
+func (s *Service) DeleteGroup(ctx context.Context, params DeleteGroupParams) error {
+	if err := s.groups.Delete(ctx, repositoryGroup.DeleteParams{ID: params.ID}); err != nil {
+		return fmt.Errorf("failed to delete group: %w", mapError(err))
+	}
+
+	s.audit.Record(ctx, audit.Event{
+		Type:    audit.EventGroupDeleted,
+		Details: map[string]string{"group_id": params.ID.String()},
+	})
+	return nil
+}
+
+// loadUser retrieves a user, failing with ErrDirectoryUserNotFound for deprovisioned users.
+func (s *Service) loadUser(ctx context.Context, id uuid.UUID) (*auth.User, error) {
+	u, err := s.users.Load(ctx, repositoryAuth.LoadParams{ID: id})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load user: %w", mapError(err))
+	}
+	if u.Deprovisioned() {
+		u.Wipe()
+		return nil, fmt.Errorf("failed to load user: %w", ErrDirectoryUserNotFound)
+	}
+	return u, nil
+}
+
+// loadGroup retrieves a single group with its members.
+func (s *Service) loadGroup(ctx context.Context, id uuid.UUID) (*group.Group, error) {
+	groups, _, err := s.groups.Load(ctx, repositoryGroup.LoadParams{ID: id})
+	if err != nil {
+		return nil, err
+	}
+	if len(groups) == 0 {
+		return nil, repositoryGroup.ErrGroupNotFound
+	}
+	return groups[0], nil
+}
+
+// applyMemberOp applies one change to the members of the group.
+func (s *Service) applyMemberOp(ctx context.Context, groupID uuid.UUID, op MemberOp) error {
+	params := repositoryGroup.MembersParams{GroupID: groupID, UserIDs: op.UserIDs}
+	switch op.Kind {
+	case MemberOpAdd:
+		return s.groups.AddMembers(ctx, params)
+	case MemberOpRemove:
+		return s.groups.RemoveMembers(ctx, params)
+	case MemberOpReplace:
+		return s.groups.ReplaceMembers(ctx, params)
+	default:
+		return fmt.Errorf("unknown member operation %d", op.Kind)
+	}
+}
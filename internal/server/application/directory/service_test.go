@@ -0,0 +1,552 @@
+package directory
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/audit"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/auth"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/group"
+	repositoryAuth "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/auth"
+	repositoryGroup "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/group"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockUserRepository implements UserRepository for testing.
+type mockUserRepository struct {
+	saveErr    error
+	loadErr    error
+	listErr    error
+	user       *auth.User
+	saved      *auth.User
+	listParams repositoryAuth.ListParams
+	users      []*auth.User
+	total      int
+}
+
+func (m *mockUserRepository) Save(_ context.Context, params repositoryAuth.SaveParams) error {
+	saved := *params.Entity
+	m.saved = &saved
+	return m.saveErr
+}
+
+func (m *mockUserRepository) Load(context.Context, repositoryAuth.LoadParams) (*auth.User, error) {
+	if m.loadErr != nil {
+		return nil, m.loadErr
+	}
+	u := *m.user
+	return &u, nil
+}
+
+func (m *mockUserRepository) List(_ context.Context, params repositoryAuth.ListParams) ([]*auth.User, int, error) {
+	m.listParams = params
+	return m.users, m.total, m.listErr
+}
+
+// mockGroupRepository implements GroupRepository for testing with an in-memory group.
+type mockGroupRepository struct {
+	saveErr    error
+	membersErr error
+	deleteErr  error
+	group      *group.Group
+	calls      []string
+}
+
+func (m *mockGroupRepository) Save(_ context.Context, params repositoryGroup.SaveParams) error {
+	if m.saveErr != nil {
+		return m.saveErr
+	}
+	g := *params.Entity
+	g.Members = nil
+	if m.group != nil {
+		g.Members = m.group.Members
+	}
+	m.group = &g
+	m.calls = append(m.calls, "save")
+	return nil
+}
+
+func (m *mockGroupRepository) Load(
+	_ context.Context,
+	params repositoryGroup.LoadParams,
+) ([]*group.Group, int, error) {
+	if m.group == nil || (params.ID != uuid.Nil && params.ID != m.group.ID) {
+		if params.ID != uuid.Nil {
+			return nil, 0, repositoryGroup.ErrGroupNotFound
+		}
+		return nil, 0, nil
+	}
+	g := *m.group
+	return []*group.Group{&g}, 1, nil
+}
+
+func (m *mockGroupRepository) ReplaceMembers(_ context.Context, params repositoryGroup.MembersParams) error {
+	m.calls = append(m.calls, "replace")
+	m.group.Members = append([]uuid.UUID(nil), params.UserIDs...)
+	return m.membersErr
+}
+
+func (m *mockGroupRepository) AddMembers(_ context.Context, params repositoryGroup.MembersParams) error {
+	m.calls = append(m.calls, "add")
+	m.group.Members = append(m.group.Members, params.UserIDs...)
+	return m.membersErr
+}
+
+func (m *mockGroupRepository) RemoveMembers(_ context.Context, params repositoryGroup.MembersParams) error {
+	m.calls = append(m.calls, "remove")
+	var kept []uuid.UUID
+	for _, id := range m.group.Members {
+		removed := false
+		for _, r := range params.UserIDs {
+			removed = removed || r == id
+		}
+		if !removed {
+			kept = append(kept, id)
+		}
+	}
+	m.group.Members = kept
+	return m.membersErr
+}
+
+func (m *mockGroupRepository) Delete(context.Context, repositoryGroup.DeleteParams) error {
+	return m.deleteErr
+}
+
+// mockUnitOfWork implements UnitOfWork for testing by running fn directly.
+type mockUnitOfWork struct {
+	calls int
+}
+
+func (m *mockUnitOfWork) Do(ctx context.Context, _ uuid.UUID, fn func(ctx context.Context) error) error {
+	m.calls++
+	return fn(ctx)
+}
+
+// mockPasswordHasher implements auth.PasswordHasher for testing.
+type mockPasswordHasher struct{}
+
+func (mockPasswordHasher) PasswordHash(password string) (string, error) {
+	return "hashed_" + password, nil
+}
+
+// mockCryptoKeyGenerator implements auth.CryptoKeyGenerator for testing.
+type mockCryptoKeyGenerator struct{}
+
+func (mockCryptoKeyGenerator) CryptoKeyGenerate(size int) ([]byte, error) {
+	return make([]byte, size), nil
+}
+
+// mockAuditRecorder implements AuditRecorder for testing.
+type mockAuditRecorder struct {
+	events []audit.Event
+}
+
+func (m *mockAuditRecorder) Record(_ context.Context, e audit.Event) {
+	m.events = append(m.events, e)
+}
+
+// newTestService creates a service over the mocks.
+func newTestService(
+	users *mockUserRepository,
+	groups *mockGroupRepository,
+	recorder *mockAuditRecorder,
+) (*Service, *mockUnitOfWork) {
+	uow := &mockUnitOfWork{}
+	return NewService(users, groups, uow, mockPasswordHasher{}, mockCryptoKeyGenerator{}, recorder), uow
+}
+
+func TestService_CreateUser(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		saveErr      error
+		wantErr      error
+		name         string
+		params       CreateUserParams
+		wantPassword bool
+	}{
+		{
+			name:         "with password",
+			params:       CreateUserParams{UserName: "alice@example.com", Password: "secret-pass", Active: true},
+			wantPassword: true,
+		},
+		{
+			name:   "without password",
+			params: CreateUserParams{UserName: "bob@example.com", ExternalID: "00u1", Active: true},
+		},
+		{
+			name:   "inactive",
+			params: CreateUserParams{UserName: "carol@example.com"},
+		},
+		{
+			name:    "short user name",
+			params:  CreateUserParams{UserName: "bob"},
+			wantErr: ErrDirectoryIncorrectUserName,
+		},
+		{
+			name:    "short password",
+			params:  CreateUserParams{UserName: "alice@example.com", Password: "secret"},
+			wantErr: ErrDirectoryIncorrectPassword,
+		},
+		{
+			name:    "duplicate user name",
+			params:  CreateUserParams{UserName: "alice@example.com"},
+			saveErr: repositoryAuth.ErrUserAlreadyExists,
+			wantErr: ErrDirectoryUserAlreadyExists,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			users := &mockUserRepository{saveErr: tt.saveErr}
+			recorder := &mockAuditRecorder{}
+			s, _ := newTestService(users, &mockGroupRepository{}, recorder)
+
+			got, err := s.CreateUser(context.Background(), tt.params)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.NotErrorIs(t, err, ErrDirectoryTechError)
+				assert.Nil(t, got)
+				assert.Empty(t, recorder.events)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.params.UserName, got.UserName)
+			assert.Equal(t, tt.params.ExternalID, got.ExternalID)
+			assert.Equal(t, tt.params.Active, got.Active)
+			assert.Equal(t, got.ID, users.saved.ID)
+			assert.Equal(t, tt.params.ExternalID, users.saved.ExternalID)
+			if tt.wantPassword {
+				assert.Equal(t, "hashed_"+tt.params.Password, users.saved.PasswordHash)
+			} else {
+				assert.Greater(t, len(users.saved.PasswordHash), len("hashed_")+30)
+			}
+			require.Len(t, recorder.events, 1)
+			assert.Equal(t, audit.EventUserProvisioned, recorder.events[0].Type)
+			assert.Equal(t, got.ID, recorder.events[0].UserID)
+		})
+	}
+}
+
+func TestService_GetUser(t *testing.T) {
+	t.Parallel()
+
+	active := &auth.User{ID: uuid.New(), Login: "alice@example.com", ExternalID: "00u1", Active: true}
+	removed := &auth.User{ID: uuid.New(), Login: "bob@example.com", DeprovisionedAt: time.Now()}
+
+	tests := []struct {
+		loadErr error
+		wantErr error
+		user    *auth.User
+		name    string
+	}{
+		{name: "provisioned user", user: active},
+		{name: "deprovisioned user", user: removed, wantErr: ErrDirectoryUserNotFound},
+		{name: "unknown user", loadErr: repositoryAuth.ErrUserNotFound, wantErr: ErrDirectoryUserNotFound},
+		{name: "load error", loadErr: errors.New("db down"), wantErr: ErrDirectoryTechError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			s, _ := newTestService(
+				&mockUserRepository{user: tt.user, loadErr: tt.loadErr}, &mockGroupRepository{}, &mockAuditRecorder{},
+			)
+
+			got, err := s.GetUser(context.Background(), GetUserParams{ID: uuid.New()})
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, &User{ID: active.ID, UserName: active.Login, ExternalID: "00u1", Active: true}, got)
+		})
+	}
+}
+
+func TestService_ListUsers(t *testing.T) {
+	t.Parallel()
+
+	user := &auth.User{ID: uuid.New(), Login: "alice@example.com", Active: true}
+	users := &mockUserRepository{users: []*auth.User{user}, total: 7}
+	s, _ := newTestService(users, &mockGroupRepository{}, &mockAuditRecorder{})
+
+	got, err := s.ListUsers(context.Background(), ListUsersParams{ExternalID: "00u1", Offset: 5, Limit: 1})
+
+	require.NoError(t, err)
+	assert.Equal(t, repositoryAuth.ListParams{ExternalID: "00u1", Offset: 5, Limit: 1}, users.listParams)
+	assert.Equal(t, 7, got.Total)
+	require.Len(t, got.Users, 1)
+	assert.Equal(t, user.ID, got.Users[0].ID)
+}
+
+func TestService_UpdateUser(t *testing.T) {
+	t.Parallel()
+
+	newName := "alice.smith@example.com"
+	newPassword := "new-secret-pass"
+	shortName := "abc"
+	inactive := false
+	externalID := "00u9"
+
+	tests := []struct {
+		wantErr  error
+		check    func(*testing.T, *auth.User)
+		name     string
+		params   UpdateUserParams
+		wantType string
+	}{
+		{
+			name:   "rename and change password",
+			params: UpdateUserParams{UserName: &newName, Password: &newPassword},
+			check: func(t *testing.T, u *auth.User) {
+				t.Helper()
+				assert.Equal(t, newName, u.Login)
+				assert.Equal(t, "hashed_"+newPassword, u.PasswordHash)
+				assert.True(t, u.Active)
+				assert.Equal(t, "00u1", u.ExternalID)
+			},
+		},
+		{
+			name:   "deactivate and relink",
+			params: UpdateUserParams{Active: &inactive, ExternalID: &externalID},
+			check: func(t *testing.T, u *auth.User) {
+				t.Helper()
+				assert.False(t, u.Active)
+				assert.Equal(t, externalID, u.ExternalID)
+				assert.Equal(t, "alice@example.com", u.Login)
+			},
+		},
+		{
+			name:    "invalid user name",
+			params:  UpdateUserParams{UserName: &shortName},
+			wantErr: ErrDirectoryIncorrectUserName,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			users := &mockUserRepository{user: &auth.User{
+				ID: uuid.New(), Login: "alice@example.com", PasswordHash: "hash", ExternalID: "00u1", Active: true,
+			}}
+			recorder := &mockAuditRecorder{}
+			s, _ := newTestService(users, &mockGroupRepository{}, recorder)
+
+			_, err := s.UpdateUser(context.Background(), tt.params)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, users.saved)
+				return
+			}
+			require.NoError(t, err)
+			tt.check(t, users.saved)
+			require.Len(t, recorder.events, 1)
+			assert.Equal(t, audit.EventUserUpdated, recorder.events[0].Type)
+		})
+	}
+}
+
+func TestService_DeleteUser(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	users := &mockUserRepository{user: &auth.User{ID: userID, Login: "alice@example.com", Active: true}}
+	recorder := &mockAuditRecorder{}
+	s, _ := newTestService(users, &mockGroupRepository{}, recorder)
+
+	require.NoError(t, s.DeleteUser(context.Background(), DeleteUserParams{ID: userID}))
+
+	assert.False(t, users.saved.Active)
+	assert.True(t, users.saved.Deprovisioned())
+	require.Len(t, recorder.events, 1)
+	assert.Equal(t, audit.EventUserDeprovisioned, recorder.events[0].Type)
+	assert.Equal(t, userID, recorder.events[0].UserID)
+
+	users.user = users.saved
+	err := s.DeleteUser(context.Background(), DeleteUserParams{ID: userID})
+	assert.ErrorIs(t, err, ErrDirectoryUserNotFound)
+}
+
+func TestService_CreateGroup(t *testing.T) {
+	t.Parallel()
+
+	members := []uuid.UUID{uuid.New(), uuid.New()}
+
+	tests := []struct {
+		saveErr    error
+		membersErr error
+		wantErr    error
+		name       string
+		params     CreateGroupParams
+	}{
+		{
+			name:   "with members",
+			params: CreateGroupParams{DisplayName: "Engineering", ExternalID: "00g1", Members: members},
+		},
+		{
+			name:    "blank name",
+			params:  CreateGroupParams{DisplayName: " "},
+			wantErr: ErrDirectoryIncorrectDisplayName,
+		},
+		{
+			name:    "duplicate name",
+			params:  CreateGroupParams{DisplayName: "Engineering"},
+			saveErr: repositoryGroup.ErrGroupAlreadyExists,
+			wantErr: ErrDirectoryGroupAlreadyExists,
+		},
+		{
+			name:       "unknown member",
+			params:     CreateGroupParams{DisplayName: "Engineering", Members: members},
+			membersErr: repositoryGroup.ErrGroupMemberNotFound,
+			wantErr:    ErrDirectoryMemberNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			groups := &mockGroupRepository{saveErr: tt.saveErr, membersErr: tt.membersErr}
+			recorder := &mockAuditRecorder{}
+			s, uow := newTestService(&mockUserRepository{}, groups, recorder)
+
+			got, err := s.CreateGroup(context.Background(), tt.params)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Empty(t, recorder.events)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, 1, uow.calls)
+			assert.Equal(t, tt.params.DisplayName, got.DisplayName)
+			assert.Equal(t, tt.params.ExternalID, got.ExternalID)
+			assert.Equal(t, members, got.Members)
+			require.Len(t, recorder.events, 1)
+			assert.Equal(t, audit.EventGroupCreated, recorder.events[0].Type)
+		})
+	}
+}
+
+func TestService_UpdateGroup(t *testing.T) {
+	t.Parallel()
+
+	alice, bob, carol := uuid.New(), uuid.New(), uuid.New()
+	newName := "Platform"
+	blank := ""
+
+	tests := []struct {
+		wantErr     error
+		name        string
+		params      UpdateGroupParams
+		wantName    string
+		wantMembers []uuid.UUID
+		wantCalls   []string
+	}{
+		{
+			name: "member operations applied in order",
+			params: UpdateGroupParams{MemberOps: []MemberOp{
+				{Kind: MemberOpRemove, UserIDs: []uuid.UUID{alice}},
+				{Kind: MemberOpAdd, UserIDs: []uuid.UUID{carol}},
+			}},
+			wantName:    "Engineering",
+			wantMembers: []uuid.UUID{bob, carol},
+			wantCalls:   []string{"save", "remove", "add"},
+		},
+		{
+			name: "rename and replace members",
+			params: UpdateGroupParams{
+				DisplayName: &newName,
+				MemberOps:   []MemberOp{{Kind: MemberOpReplace, UserIDs: []uuid.UUID{carol}}},
+			},
+			wantName:    newName,
+			wantMembers: []uuid.UUID{carol},
+			wantCalls:   []string{"save", "replace"},
+		},
+		{
+			name:    "blank name",
+			params:  UpdateGroupParams{DisplayName: &blank},
+			wantErr: ErrDirectoryIncorrectDisplayName,
+		},
+		{
+			name:    "unknown group",
+			params:  UpdateGroupParams{ID: uuid.New()},
+			wantErr: ErrDirectoryGroupNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			existing := &group.Group{ID: uuid.New(), DisplayName: "Engineering", Members: []uuid.UUID{alice, bob}}
+			groups := &mockGroupRepository{group: existing}
+			recorder := &mockAuditRecorder{}
+			s, uow := newTestService(&mockUserRepository{}, groups, recorder)
+			params := tt.params
+			if params.ID == uuid.Nil {
+				params.ID = existing.ID
+			}
+
+			got, err := s.UpdateGroup(context.Background(), params)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Empty(t, recorder.events)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, 1, uow.calls)
+			assert.Equal(t, tt.wantName, got.DisplayName)
+			assert.Equal(t, tt.wantMembers, got.Members)
+			assert.Equal(t, tt.wantCalls, groups.calls)
+			require.Len(t, recorder.events, 1)
+			assert.Equal(t, audit.EventGroupUpdated, recorder.events[0].Type)
+		})
+	}
+}
+
+func TestService_DeleteGroup(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		deleteErr error
+		wantErr   error
+		name      string
+	}{
+		{name: "deleted"},
+		{name: "unknown group", deleteErr: repositoryGroup.ErrGroupNotFound, wantErr: ErrDirectoryGroupNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			recorder := &mockAuditRecorder{}
+			s, _ := newTestService(&mockUserRepository{}, &mockGroupRepository{deleteErr: tt.deleteErr}, recorder)
+
+			err := s.DeleteGroup(context.Background(), DeleteGroupParams{ID: uuid.New()})
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Empty(t, recorder.events)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, recorder.events, 1)
+			assert.Equal(t, audit.EventGroupDeleted, recorder.events[0].Type)
+		})
+	}
+}
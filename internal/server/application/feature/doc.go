@@ -0,0 +1,5 @@
+// Package feature provides feature flag application services for the AegisVaultKeeper server.
+//
+// This package implements resolution of feature flags from configured deployment defaults and
+// stored deployment-wide and per-user overrides, and management of those overrides.
+package feature
@@ -0,0 +1,91 @@
+package feature
+
+import (
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/feature"
+	"github.com/google/uuid"
+)
+
+// Flag represents the effective state of a feature for a user.
+type Flag struct {
+	// Key identifies the feature.
+	Key string
+	// Enabled determines whether the feature is switched on.
+	Enabled bool
+}
+
+// Override represents a stored feature flag data transfer object for application layer communication.
+type Override struct {
+	// UpdatedAt indicates when the flag was last set.
+	UpdatedAt time.Time
+	// Key identifies the feature.
+	Key string
+	// UserID identifies the user the flag applies to, or uuid.Nil for deployment-wide flags.
+	UserID uuid.UUID
+	// Enabled determines whether the feature is switched on.
+	Enabled bool
+}
+
+// newOverrideFromDomain converts a domain feature flag entity to application DTO.
+func newOverrideFromDomain(f *feature.Flag) *Override {
+	if f == nil {
+		return nil
+	}
+	return &Override{
+		Key:       f.Key,
+		UserID:    f.UserID,
+		Enabled:   f.Enabled,
+		UpdatedAt: f.UpdatedAt,
+	}
+}
+
+// newOverridesFromDomain converts a slice of domain feature flag entities to application DTOs.
+func newOverridesFromDomain(fs []*feature.Flag) []*Override {
+	result := make([]*Override, 0, len(fs))
+	for _, f := range fs {
+		result = append(result, newOverrideFromDomain(f))
+	}
+	return result
+}
+
+// CheckParams contains parameters for checking whether a feature is enabled.
+type CheckParams struct {
+	// Key identifies the feature.
+	Key string
+	// UserID selects the overrides of the user; uuid.Nil checks the deployment-wide state.
+	UserID uuid.UUID
+}
+
+// ListParams contains parameters for listing the effective feature flags.
+type ListParams struct {
+	// UserID selects the overrides of the user; uuid.Nil lists the deployment-wide state.
+	UserID uuid.UUID
+}
+
+// ListOverridesParams contains parameters for listing stored feature flags.
+// When neither Global nor UserID is set, all flags are listed.
+type ListOverridesParams struct {
+	// UserID selects the flags of the specified user.
+	UserID uuid.UUID
+	// Global selects the flags applying to all users.
+	Global bool
+}
+
+// SetFlagParams contains parameters for storing a feature flag.
+type SetFlagParams struct {
+	// Key identifies the feature.
+	Key string
+	// UserID identifies the user the flag applies to, or uuid.Nil for a deployment-wide flag.
+	UserID uuid.UUID
+	// Enabled determines whether the feature is switched on.
+	Enabled bool
+}
+
+// DeleteFlagParams contains parameters for removing a stored feature flag.
+type DeleteFlagParams struct {
+	// Key identifies the feature.
+	Key string
+	// UserID identifies the user of the flag, or uuid.Nil for the deployment-wide flag.
+	UserID uuid.UUID
+}
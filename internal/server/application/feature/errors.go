@@ -0,0 +1,51 @@
+package feature
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/errutil"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/feature"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/feature"
+)
+
+// Feature flag error definitions.
+var (
+	// ErrFeatureAppError indicates a general feature flag application error.
+	ErrFeatureAppError = errors.New("feature flag application error")
+
+	// ErrFeatureTechError indicates a technical error in the feature flag system.
+	ErrFeatureTechError = errors.New("feature flag technical error")
+
+	// ErrFlagIncorrectKey indicates an incorrect feature flag key was provided.
+	ErrFlagIncorrectKey = errors.New("incorrect feature flag key")
+
+	// ErrFlagNotFound indicates the requested feature flag override was not found.
+	ErrFlagNotFound = errors.New("feature flag not found")
+)
+
+// mapError maps domain and repository errors to application-level errors.
+func mapError(err error) error {
+	if err == nil {
+		return nil
+	}
+	mapped := errutil.MapError(mapFn, err)
+	if mapped != nil {
+		return fmt.Errorf("feature flag error mapping failed: %w", mapped)
+	}
+	return nil
+}
+
+// mapFn provides the actual error mapping logic for different error types.
+func mapFn(err error) error {
+	switch {
+	case errors.Is(err, feature.ErrNewFlagParamsValidation):
+		return ErrFeatureAppError
+	case errors.Is(err, feature.ErrIncorrectKey):
+		return ErrFlagIncorrectKey
+	case errors.Is(err, repository.ErrFlagNotFound):
+		return ErrFlagNotFound
+	default:
+		return errors.Join(ErrFeatureTechError, err)
+	}
+}
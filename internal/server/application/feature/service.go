@@ -0,0 +1,144 @@
+package feature
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/audit"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/feature"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/feature"
+	"github.com/google/uuid"
+)
+
+// Repository defines the interface for feature flag persistence operations.
+type Repository interface {
+	// Save persists a feature flag using the provided parameters.
+	Save(ctx context.Context, params repository.SaveParams) error
+	// Load retrieves feature flags using the provided parameters.
+	Load(ctx context.Context, params repository.LoadParams) ([]*feature.Flag, error)
+	// Delete removes a feature flag using the provided parameters.
+	Delete(ctx context.Context, params repository.DeleteParams) error
+}
+
+// AuditRecorder defines the interface for recording security audit events.
+type AuditRecorder interface {
+	// Record stores the audit event.
+	Record(ctx context.Context, e audit.Event)
+}
+
+// Service provides feature flag operations.
+type Service struct {
+	// r is the repository interface for feature flag persistence operations.
+	r Repository
+	// audit records flag changes.
+	audit AuditRecorder
+	// defaults holds the deployment defaults from the configuration file.
+	defaults map[string]bool
+}
+
+// NewService creates a new feature flag service instance with the configured deployment defaults.
+func NewService(r Repository, defaults map[string]bool, audit AuditRecorder) *Service {
+	return &Service{r: r, defaults: maps.Clone(defaults), audit: audit}
+}
+
+// Enabled reports whether the feature is switched on for the user.
+// A per-user flag takes precedence over a deployment-wide one, which takes precedence over
+// the configured default; features known to none of them are off.
+func (s *Service) Enabled(ctx context.Context, params CheckParams) (bool, error) {
+	flags, err := s.resolve(ctx, params.UserID)
+	if err != nil {
+		return false, err
+	}
+	return flags[params.Key], nil
+}
+
+// List retrieves the effective state of every known feature for the user ordered by key.
+func (s *Service) List(ctx context.Context, params ListParams) ([]*Flag, error) {
+	flags, err := s.resolve(ctx, params.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*Flag, 0, len(flags))
+	for _, key := range slices.Sorted(maps.Keys(flags)) {
+		result = append(result, &Flag{Key: key, Enabled: flags[key]})
+	}
+	return result, nil
+}
+
+// ListOverrides retrieves stored feature flags matching the provided parameters.
+func (s *Service) ListOverrides(ctx context.Context, params ListOverridesParams) ([]*Override, error) {
+	flags, err := s.r.Load(ctx, repository.LoadParams{Global: params.Global, UserID: params.UserID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load feature flags: %w", mapError(err))
+	}
+	return newOverridesFromDomain(flags), nil
+}
+
+// SetFlag stores a deployment-wide or per-user feature flag, replacing the previous one.
+func (s *Service) SetFlag(ctx context.Context, params SetFlagParams) (*Override, error) {
+	flag, err := feature.NewFlag(feature.NewFlagParams{
+		Key:     params.Key,
+		UserID:  params.UserID,
+		Enabled: params.Enabled,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create new feature flag: %w", mapError(err))
+	}
+
+	if err := s.r.Save(ctx, repository.SaveParams{Entity: flag}); err != nil {
+		return nil, fmt.Errorf("failed to save feature flag: %w", mapError(err))
+	}
+
+	s.audit.Record(ctx, audit.Event{
+		Type:   audit.EventFeatureFlagSet,
+		UserID: flag.UserID,
+		Details: map[string]string{
+			"key":     flag.Key,
+			"enabled": strconv.FormatBool(flag.Enabled),
+		},
+	})
+	return newOverrideFromDomain(flag), nil
+}
+
+// DeleteFlag removes a stored feature flag so the next less specific level applies again.
+func (s *Service) DeleteFlag(ctx context.Context, params DeleteFlagParams) error {
+	err := s.r.Delete(ctx, repository.DeleteParams{Key: params.Key, UserID: params.UserID})
+	if err != nil {
+		return fmt.Errorf("failed to delete feature flag: %w", mapError(err))
+	}
+
+	s.audit.Record(ctx, audit.Event{
+		Type:    audit.EventFeatureFlagDeleted,
+		UserID:  params.UserID,
+		Details: map[string]string{"key": params.Key},
+	})
+	return nil
+}
+
+// resolve merges the configured defaults with the stored deployment-wide and per-user flags.
+func (s *Service) resolve(ctx context.Context, userID uuid.UUID) (map[string]bool, error) {
+	stored, err := s.r.Load(ctx, repository.LoadParams{Global: true, UserID: userID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load feature flags: %w", mapError(err))
+	}
+
+	flags := maps.Clone(s.defaults)
+	if flags == nil {
+		flags = make(map[string]bool, len(stored))
+	}
+	for _, f := range stored {
+		if f.IsGlobal() {
+			flags[f.Key] = f.Enabled
+		}
+	}
+	for _, f := range stored {
+		if !f.IsGlobal() && f.UserID == userID {
+			flags[f.Key] = f.Enabled
+		}
+	}
+	return flags, nil
+}
@@ -0,0 +1,261 @@
+package feature
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/audit"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/feature"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/feature"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockRepository implements Repository for testing.
+type mockRepository struct {
+	loadErr      error
+	saveErr      error
+	deleteErr    error
+	saved        *feature.Flag
+	loadParams   repository.LoadParams
+	deleteParams repository.DeleteParams
+	flags        []*feature.Flag
+}
+
+func (m *mockRepository) Save(_ context.Context, params repository.SaveParams) error {
+	m.saved = params.Entity
+	return m.saveErr
+}
+
+func (m *mockRepository) Load(_ context.Context, params repository.LoadParams) ([]*feature.Flag, error) {
+	m.loadParams = params
+	return m.flags, m.loadErr
+}
+
+func (m *mockRepository) Delete(_ context.Context, params repository.DeleteParams) error {
+	m.deleteParams = params
+	return m.deleteErr
+}
+
+// mockAuditRecorder implements AuditRecorder for testing.
+type mockAuditRecorder struct {
+	events []audit.Event
+}
+
+func (m *mockAuditRecorder) Record(_ context.Context, e audit.Event) {
+	m.events = append(m.events, e)
+}
+
+func TestService_Enabled(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	defaults := map[string]bool{"sync_v2": true, "crypto_v2": false}
+
+	tests := []struct {
+		loadErr error
+		name    string
+		key     string
+		flags   []*feature.Flag
+		userID  uuid.UUID
+		want    bool
+	}{
+		{name: "configured default on", key: "sync_v2", want: true},
+		{name: "configured default off", key: "crypto_v2", want: false},
+		{name: "unknown feature", key: "unknown", want: false},
+		{
+			name:  "deployment flag overrides default",
+			key:   "sync_v2",
+			flags: []*feature.Flag{{Key: "sync_v2", Enabled: false}},
+			want:  false,
+		},
+		{
+			name: "user flag overrides deployment flag",
+			key:  "crypto_v2",
+			flags: []*feature.Flag{
+				{Key: "crypto_v2", Enabled: false},
+				{Key: "crypto_v2", UserID: userID, Enabled: true},
+			},
+			userID: userID,
+			want:   true,
+		},
+		{
+			name:    "load error",
+			key:     "sync_v2",
+			loadErr: errors.New("connection refused"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &mockRepository{flags: tt.flags, loadErr: tt.loadErr}
+			s := NewService(repo, defaults, &mockAuditRecorder{})
+
+			got, err := s.Enabled(context.Background(), CheckParams{Key: tt.key, UserID: tt.userID})
+
+			if tt.loadErr != nil {
+				require.ErrorIs(t, err, ErrFeatureTechError)
+				assert.False(t, got)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, repository.LoadParams{Global: true, UserID: tt.userID}, repo.loadParams)
+		})
+	}
+}
+
+func TestService_List(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	repo := &mockRepository{flags: []*feature.Flag{
+		{Key: "beta_ui", Enabled: true},
+		{Key: "sync_v2", UserID: userID, Enabled: false},
+	}}
+	s := NewService(repo, map[string]bool{"sync_v2": true, "crypto_v2": false}, &mockAuditRecorder{})
+
+	flags, err := s.List(context.Background(), ListParams{UserID: userID})
+
+	require.NoError(t, err)
+	assert.Equal(t, []*Flag{
+		{Key: "beta_ui", Enabled: true},
+		{Key: "crypto_v2", Enabled: false},
+		{Key: "sync_v2", Enabled: false},
+	}, flags)
+}
+
+func TestService_List_NoFlags(t *testing.T) {
+	t.Parallel()
+
+	s := NewService(&mockRepository{}, nil, &mockAuditRecorder{})
+
+	flags, err := s.List(context.Background(), ListParams{})
+
+	require.NoError(t, err)
+	assert.NotNil(t, flags)
+	assert.Empty(t, flags)
+}
+
+func TestService_ListOverrides(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	repo := &mockRepository{flags: []*feature.Flag{{Key: "sync_v2", UserID: userID, Enabled: true}}}
+	s := NewService(repo, nil, &mockAuditRecorder{})
+
+	overrides, err := s.ListOverrides(context.Background(), ListOverridesParams{UserID: userID})
+
+	require.NoError(t, err)
+	assert.Equal(t, repository.LoadParams{UserID: userID}, repo.loadParams)
+	require.Len(t, overrides, 1)
+	assert.Equal(t, "sync_v2", overrides[0].Key)
+	assert.Equal(t, userID, overrides[0].UserID)
+	assert.True(t, overrides[0].Enabled)
+}
+
+func TestService_SetFlag(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+
+	tests := []struct {
+		saveErr error
+		wantErr error
+		name    string
+		params  SetFlagParams
+	}{
+		{
+			name:   "deployment flag",
+			params: SetFlagParams{Key: "sync_v2", Enabled: true},
+		},
+		{
+			name:   "user flag",
+			params: SetFlagParams{Key: "sync_v2", UserID: userID},
+		},
+		{
+			name:    "invalid key",
+			params:  SetFlagParams{Key: "Sync V2"},
+			wantErr: ErrFlagIncorrectKey,
+		},
+		{
+			name:    "save error",
+			params:  SetFlagParams{Key: "sync_v2"},
+			saveErr: errors.New("connection refused"),
+			wantErr: ErrFeatureTechError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &mockRepository{saveErr: tt.saveErr}
+			recorder := &mockAuditRecorder{}
+			s := NewService(repo, nil, recorder)
+
+			override, err := s.SetFlag(context.Background(), tt.params)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, override)
+				assert.Empty(t, recorder.events)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.params.Key, override.Key)
+			assert.Equal(t, tt.params.UserID, override.UserID)
+			assert.Equal(t, tt.params.Enabled, override.Enabled)
+			assert.False(t, override.UpdatedAt.IsZero())
+			require.NotNil(t, repo.saved)
+			assert.Equal(t, tt.params.Key, repo.saved.Key)
+			require.Len(t, recorder.events, 1)
+			assert.Equal(t, audit.EventFeatureFlagSet, recorder.events[0].Type)
+			assert.Equal(t, tt.params.UserID, recorder.events[0].UserID)
+			assert.Equal(t, tt.params.Key, recorder.events[0].Details["key"])
+		})
+	}
+}
+
+func TestService_DeleteFlag(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+
+	tests := []struct {
+		deleteErr error
+		wantErr   error
+		name      string
+	}{
+		{name: "deleted"},
+		{name: "not found", deleteErr: repository.ErrFlagNotFound, wantErr: ErrFlagNotFound},
+		{name: "delete error", deleteErr: errors.New("connection refused"), wantErr: ErrFeatureTechError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &mockRepository{deleteErr: tt.deleteErr}
+			recorder := &mockAuditRecorder{}
+			s := NewService(repo, nil, recorder)
+
+			err := s.DeleteFlag(context.Background(), DeleteFlagParams{Key: "sync_v2", UserID: userID})
+
+			assert.Equal(t, repository.DeleteParams{Key: "sync_v2", UserID: userID}, repo.deleteParams)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Empty(t, recorder.events)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, recorder.events, 1)
+			assert.Equal(t, audit.EventFeatureFlagDeleted, recorder.events[0].Type)
+			assert.Equal(t, userID, recorder.events[0].UserID)
+		})
+	}
+}
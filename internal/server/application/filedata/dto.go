@@ -19,12 +19,18 @@ type FileData struct {
 	HashSum string
 	// Description contains user-provided file description (max 255 chars).
 	Description string
+	// Folder contains the path of the folder holding the file; empty for the root folder.
+	Folder string
 	// Data contains the actual file content bytes (may be empty for metadata-only operations).
 	Data []byte
 	// ID contains the unique file identifier.
 	ID uuid.UUID
 	// UserID contains the file owner identifier.
 	UserID uuid.UUID
+	// Size contains the size of the file content in bytes; zero for files stored before sizes were recorded.
+	Size int64
+	// Archived reports whether the file is tagged archived; set for listed files only.
+	Archived bool
 }
 
 // newFileFromDomain converts a domain FileData entity to application layer DTO.
@@ -38,6 +44,8 @@ func newFileFromDomain(c *filedata.FileData) *FileData {
 		StorageKey:  string(c.StorageKey),
 		HashSum:     string(c.HashSum),
 		Description: string(c.Description),
+		Folder:      string(c.Folder),
+		Size:        c.Size,
 		UpdatedAt:   c.UpdatedAt,
 	}
 }
@@ -61,8 +69,19 @@ type PullParams struct {
 
 // ListParams contains parameters for retrieving all files belonging to a user.
 type ListParams struct {
+	// After lists only the files whose ID sorts after it; zero value starts at the first.
+	After uuid.UUID
+	// Limit caps the number of listed files and orders them by ID; zero value lists all.
+	Limit int
 	// UserID specifies the file owner for filtering.
 	UserID uuid.UUID
+	// OrderFolder names the folder whose manual order sorts the files with ManualOrder; empty is the root.
+	OrderFolder string
+	// ExcludeArchived leaves out the files tagged archived; zero value lists every file.
+	ExcludeArchived bool
+	// ManualOrder sorts the files in the manual order of OrderFolder, the others after them in their default
+	// order; zero value keeps the default order.
+	ManualOrder bool
 }
 
 // PushParams contains parameters for creating or updating file data.
@@ -71,12 +90,109 @@ type PushParams struct {
 	StorageKey string
 	// Description contains user-provided file description (max 255 chars).
 	Description string
+	// Folder specifies the existing folder holding the file; empty keeps the folder of an updated file
+	// and places a new file in the root folder.
+	Folder string
 	// Data contains the file content bytes (required for new files).
 	Data []byte
 	// ID specifies the file ID for updates (uuid.Nil for new files).
 	ID uuid.UUID
 	// UserID specifies the file owner.
 	UserID uuid.UUID
+	// AllowEmpty permits files without content, such as the empty files WebDAV clients create before writing.
+	AllowEmpty bool
+	// Reserved reports that the caller already holds a transfer reserved with ReserveTransfer for the upload.
+	Reserved bool
+}
+
+// RewrapKeysParams contains parameters for re-wrapping the file keys of a user after a user key rotation.
+type RewrapKeysParams struct {
+	// OldUserKey is the previous user key the stored file keys are wrapped with.
+	OldUserKey []byte
+	// UserID specifies the owner whose file keys are re-wrapped.
+	UserID uuid.UUID
+}
+
+// Folder represents a file folder for application layer operations.
+type Folder struct {
+	// UpdatedAt contains the timestamp when the folder was last created, renamed or moved.
+	UpdatedAt time.Time
+	// Path contains the slash-separated path of the folder.
+	Path string
+	// ID contains the unique folder identifier.
+	ID uuid.UUID
+	// UserID contains the folder owner identifier.
+	UserID uuid.UUID
+}
+
+// newFolderFromDomain converts a domain Folder entity to application layer DTO.
+func newFolderFromDomain(f *filedata.Folder) *Folder {
+	if f == nil {
+		return nil
+	}
+	return &Folder{
+		ID:        f.ID,
+		UserID:    f.UserID,
+		Path:      string(f.Path),
+		UpdatedAt: f.UpdatedAt,
+	}
+}
+
+// FolderListing contains one page of the contents of a folder.
+type FolderListing struct {
+	// Folder contains the path of the listed folder; empty for the root folder.
+	Folder string
+	// Folders contains the folders directly inside the listed folder, ordered by path.
+	Folders []*Folder
+	// Files contains the requested page of files directly inside the listed folder, ordered by storage key.
+	Files []*FileData
+	// Total counts all files directly inside the listed folder.
+	Total int
+}
+
+// CreateFolderParams contains parameters for creating a folder.
+type CreateFolderParams struct {
+	// Path specifies the path of the new folder; its parent folder must exist.
+	Path string
+	// UserID specifies the folder owner.
+	UserID uuid.UUID
+}
+
+// MoveFolderParams contains parameters for renaming or moving a folder together with its contents.
+type MoveFolderParams struct {
+	// Path specifies the new path of the folder; its parent folder must exist.
+	Path string
+	// ID specifies the folder to move.
+	ID uuid.UUID
+	// UserID specifies the folder owner.
+	UserID uuid.UUID
+}
+
+// ListFolderParams contains parameters for listing the contents of a folder.
+type ListFolderParams struct {
+	// Folder specifies the path of the listed folder; empty lists the root folder.
+	Folder string
+	// Offset specifies how many files to skip.
+	Offset int
+	// Limit specifies the maximum number of files to return.
+	Limit int
+	// UserID specifies the folder owner.
+	UserID uuid.UUID
+	// ManualOrder sorts the files in the manual order of the folder, the others after them by storage key;
+	// zero value orders every file by storage key.
+	ManualOrder bool
+}
+
+// MoveFileParams contains parameters for moving a file to another folder or renaming it.
+type MoveFileParams struct {
+	// Folder specifies the existing folder the file is moved to; empty moves it to the root folder.
+	Folder string
+	// StorageKey specifies the new storage key of the file; empty keeps the current one.
+	StorageKey string
+	// ID specifies the file to move.
+	ID uuid.UUID
+	// UserID specifies the file owner.
+	UserID uuid.UUID
 }
 
 // calculateDataHashSum computes the SHA256 hash of the file data for integrity verification.
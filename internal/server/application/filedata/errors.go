@@ -6,6 +6,8 @@ import (
 
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/errutil"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/filedata"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/fieldcrypt"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/filestorage"
 )
 
 var (
@@ -32,6 +34,30 @@ var (
 
 	// ErrFileAccessDenied indicates that the user lacks permission to access the file.
 	ErrFileAccessDenied = errors.New("access to this file is denied")
+
+	// ErrFileIncorrectFolder indicates an invalid or malformed folder path.
+	ErrFileIncorrectFolder = errors.New("incorrect folder path")
+
+	// ErrFolderNotFound indicates that the requested folder or the parent of a new folder does not exist.
+	ErrFolderNotFound = errors.New("folder not found")
+
+	// ErrFolderExists indicates that a folder with the requested path already exists.
+	ErrFolderExists = errors.New("folder already exists")
+
+	// ErrFolderMoveIntoItself indicates an attempt to move a folder below itself.
+	ErrFolderMoveIntoItself = errors.New("folder cannot be moved into itself")
+
+	// ErrFileStorageKeyTaken indicates that another file is already stored under the requested storage key.
+	ErrFileStorageKeyTaken = errors.New("storage key is already taken")
+
+	// ErrFileQuotaExceeded indicates that storing the file would exceed the storage quota of the user.
+	ErrFileQuotaExceeded = errors.New("file storage quota exceeded")
+
+	// ErrFileBusy indicates that too many file contents are being encrypted or decrypted to accept another transfer.
+	ErrFileBusy = errors.New("too many file transfers in progress")
+
+	// ErrFileIntegrityViolation indicates stored file data failed ownership integrity verification.
+	ErrFileIntegrityViolation = errors.New("file data integrity violation")
 )
 
 // mapError maps domain layer errors to application layer errors for consistent error handling.
@@ -49,12 +75,18 @@ func mapError(err error) error {
 // mapFn provides the specific error mapping logic for file data domain errors.
 func mapFn(err error) error {
 	switch {
+	case errors.Is(err, filedata.ErrIncorrectFolder):
+		return ErrFileIncorrectFolder
 	case errors.Is(err, filedata.ErrNewFileParamsValidation):
 		return ErrFileAppError
 	case errors.Is(err, filedata.ErrIncorrectStorageKey):
 		return ErrFileIncorrectStorageKey
 	case errors.Is(err, filedata.ErrIncorrectHashSum):
 		return ErrFileIncorrectHashSum
+	case errors.Is(err, filestorage.ErrStorageKeyTaken):
+		return ErrFileStorageKeyTaken
+	case errors.Is(err, fieldcrypt.ErrIntegrityViolation):
+		return errors.Join(ErrFileIntegrityViolation, err)
 	default:
 		return errors.Join(ErrFileTechError, err)
 	}
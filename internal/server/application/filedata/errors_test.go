@@ -5,6 +5,8 @@ import (
 	"testing"
 
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/filedata"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/fieldcrypt"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/filestorage"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -39,6 +41,21 @@ func TestMapError(t *testing.T) {
 			input: filedata.ErrIncorrectHashSum,
 			want:  ErrFileIncorrectHashSum,
 		},
+		{
+			name:  "domain_folder_error/maps_to_specific_error",
+			input: filedata.ErrIncorrectFolder,
+			want:  ErrFileIncorrectFolder,
+		},
+		{
+			name:  "storage_key_taken/maps_to_specific_error",
+			input: filestorage.ErrStorageKeyTaken,
+			want:  ErrFileStorageKeyTaken,
+		},
+		{
+			name:  "integrity_violation/maps_to_specific_error",
+			input: fieldcrypt.ErrIntegrityViolation,
+			want:  ErrFileIntegrityViolation,
+		},
 		{
 			name:  "unknown_error/maps_to_tech_error",
 			input: errors.New("unknown database error"),
@@ -188,6 +205,16 @@ func TestErrorConstants(t *testing.T) {
 			err:  ErrFileAccessDenied,
 			want: "access to this file is denied",
 		},
+		{
+			name: "ErrFolderNotFound",
+			err:  ErrFolderNotFound,
+			want: "folder not found",
+		},
+		{
+			name: "ErrFolderExists",
+			err:  ErrFolderExists,
+			want: "folder already exists",
+		},
 	}
 
 	for _, tt := range tests {
@@ -213,6 +240,12 @@ func TestErrorsAreDistinct(t *testing.T) {
 		ErrRollBackFileSaveFailed,
 		ErrFileNotFound,
 		ErrFileAccessDenied,
+		ErrFileIncorrectFolder,
+		ErrFolderNotFound,
+		ErrFolderExists,
+		ErrFolderMoveIntoItself,
+		ErrFileStorageKeyTaken,
+		ErrFileQuotaExceeded,
 	}
 
 	// Check that all errors are distinct
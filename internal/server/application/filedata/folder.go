@@ -0,0 +1,268 @@
+package filedata
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/authz"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/filedata"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/itemorder"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/db"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/filedata"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/filefolder"
+	filestorage "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/filestorage"
+	"github.com/google/uuid"
+)
+
+// CreateFolder creates a folder for the specified user below an existing parent folder.
+func (s *Service) CreateFolder(ctx context.Context, params CreateFolderParams) (*Folder, error) {
+	if err := s.authorize(ctx, authz.ActionWrite, uuid.Nil, params.UserID, params.UserID); err != nil {
+		return nil, fmt.Errorf("create folder access error: %w", err)
+	}
+
+	f, err := filedata.NewFolder(filedata.NewFolderParams{Path: params.Path, UserID: params.UserID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create folder: %w", mapError(err))
+	}
+
+	folders, err := s.loadFolders(ctx, params.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkFolderTarget(folders, string(f.Path)); err != nil {
+		return nil, err
+	}
+
+	if err := s.folders.Save(ctx, filefolder.SaveParams{Entity: f}); err != nil {
+		return nil, fmt.Errorf("failed to save folder: %w", mapError(err))
+	}
+	return newFolderFromDomain(f), nil
+}
+
+// MoveFolder renames or moves a folder to a new path below an existing parent folder. The folders and
+// files inside it move along in a single transaction; file contents stay in place.
+func (s *Service) MoveFolder(ctx context.Context, params MoveFolderParams) (*Folder, error) {
+	if err := s.authorize(ctx, authz.ActionWrite, uuid.Nil, params.UserID, params.UserID); err != nil {
+		return nil, fmt.Errorf("move folder access error: %w", err)
+	}
+
+	target, err := filedata.NewFolder(filedata.NewFolderParams{Path: params.Path, UserID: params.UserID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to validate folder path: %w", mapError(err))
+	}
+
+	folders, err := s.loadFolders(ctx, params.UserID)
+	if err != nil {
+		return nil, err
+	}
+	idx := slices.IndexFunc(folders, func(f *filedata.Folder) bool { return f.ID == params.ID })
+	if idx < 0 {
+		return nil, fmt.Errorf("folder %s not found: %w", params.ID, ErrFolderNotFound)
+	}
+	f := folders[idx]
+
+	from, to := string(f.Path), string(target.Path)
+	if from == to {
+		return newFolderFromDomain(f), nil
+	}
+	if filedata.InFolder(to, from) {
+		return nil, fmt.Errorf("failed to move folder %q to %q: %w", from, to, ErrFolderMoveIntoItself)
+	}
+	if err := checkFolderTarget(folders, to); err != nil {
+		return nil, err
+	}
+
+	fds, err := s.r.Load(ctx, repository.LoadParams{UserID: params.UserID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load files: %w", mapError(err))
+	}
+
+	err = s.uow.Do(ctx, params.UserID, func(ctx context.Context) error {
+		for _, sub := range folders {
+			if !sub.Rebase(from, to) {
+				continue
+			}
+			if err := s.folders.Save(ctx, filefolder.SaveParams{Entity: sub}); err != nil {
+				return fmt.Errorf("failed to save folder %s: %w", sub.ID, mapError(err))
+			}
+		}
+		for _, fd := range fds {
+			if !fd.RebaseFolder(from, to) {
+				continue
+			}
+			if err := s.r.Save(ctx, repository.SaveParams{Entity: fd}); err != nil {
+				return fmt.Errorf("failed to save file %s metadata: %w", fd.ID, mapError(err))
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to move folder %q to %q: %w", from, to, err)
+	}
+
+	return newFolderFromDomain(f), nil
+}
+
+// ListFolder retrieves the folders and one page of the files directly inside a folder of the specified user.
+// Files are ordered by storage key, or manually when requested; the page is selected by offset and limit.
+func (s *Service) ListFolder(ctx context.Context, params ListFolderParams) (*FolderListing, error) {
+	if err := s.authorize(ctx, authz.ActionRead, uuid.Nil, params.UserID, params.UserID); err != nil {
+		return nil, err
+	}
+
+	folder := filedata.NormalizeFolder(params.Folder)
+	if folder != "" && !filedata.ValidFolder(folder) {
+		return nil, fmt.Errorf("failed to list folder %q: %w", folder, ErrFileIncorrectFolder)
+	}
+
+	folders, err := s.loadFolders(ctx, params.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if folder != "" && findFolder(folders, folder) == nil {
+		return nil, fmt.Errorf("folder %q not found: %w", folder, ErrFolderNotFound)
+	}
+
+	fds, err := s.r.Load(ctx, repository.LoadParams{UserID: params.UserID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load files: %w", mapError(err))
+	}
+
+	listing := &FolderListing{Folder: folder, Folders: []*Folder{}, Files: []*FileData{}}
+	for _, f := range folders {
+		if filedata.ParentFolder(string(f.Path)) == folder {
+			listing.Folders = append(listing.Folders, newFolderFromDomain(f))
+		}
+	}
+	slices.SortFunc(listing.Folders, func(a, b *Folder) int { return cmp.Compare(a.Path, b.Path) })
+
+	// inFolder collects the files directly inside the listed folder.
+	var inFolder []*filedata.FileData
+	for _, fd := range fds {
+		if string(fd.Folder) == folder {
+			inFolder = append(inFolder, fd)
+		}
+	}
+	slices.SortFunc(inFolder, func(a, b *filedata.FileData) int {
+		return cmp.Or(cmp.Compare(string(a.StorageKey), string(b.StorageKey)), cmp.Compare(a.ID.String(), b.ID.String()))
+	})
+	if params.ManualOrder {
+		order, err := s.order(ctx, params.UserID, folder)
+		if err != nil {
+			return nil, err
+		}
+		itemorder.Sort(order, inFolder, func(fd *filedata.FileData) uuid.UUID { return fd.ID })
+	}
+
+	listing.Total = len(inFolder)
+	start := min(max(params.Offset, 0), len(inFolder))
+	end := min(start+max(params.Limit, 0), len(inFolder))
+	listing.Files = append(listing.Files, newFilesFromDomain(inFolder[start:end])...)
+
+	return listing, nil
+}
+
+// MoveFile moves a file to another existing folder and optionally renames it to a new storage key.
+// A renamed file's content is moved in storage without re-encryption.
+func (s *Service) MoveFile(ctx context.Context, params MoveFileParams) (*FileData, error) {
+	fd, err := s.loadMetadata(ctx, PullParams{ID: params.ID, UserID: params.UserID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load metadata: %w", err)
+	}
+	if err := s.authorize(ctx, authz.ActionWrite, fd.ID, fd.UserID, params.UserID); err != nil {
+		return nil, fmt.Errorf("move file access error: %w", err)
+	}
+
+	oldKey := string(fd.StorageKey)
+	if err := fd.Relocate(params.Folder, params.StorageKey); err != nil {
+		return nil, fmt.Errorf("failed to move file: %w", mapError(err))
+	}
+	if err := s.requireFolder(ctx, fd.UserID, string(fd.Folder)); err != nil {
+		return nil, err
+	}
+
+	newKey := string(fd.StorageKey)
+	if newKey != oldKey {
+		if err := s.fs.Move(ctx, filestorage.MoveParams{
+			UserID:         fd.UserID,
+			FromStorageKey: oldKey,
+			ToStorageKey:   newKey,
+		}); err != nil {
+			return nil, fmt.Errorf("failed to move file data: %w", mapError(err))
+		}
+		// Within a unit of work the metadata may still roll back after MoveFile returns.
+		db.OnRollback(ctx, func(ctx context.Context) error { return s.rollbackFileMove(ctx, fd, oldKey) })
+	}
+
+	if err := s.r.Save(ctx, repository.SaveParams{Entity: fd}); err != nil {
+		if newKey == oldKey {
+			return nil, fmt.Errorf("failed to save file metadata: %w", mapError(err))
+		}
+		if rollbackErr := s.rollbackFileMove(ctx, fd, oldKey); rollbackErr != nil {
+			return nil, errors.Join(fmt.Errorf("failed to save file metadata: %w", mapError(err)), rollbackErr)
+		}
+		return nil, fmt.Errorf("failed to save file metadata: %w", mapError(err))
+	}
+
+	return newFileFromDomain(fd), nil
+}
+
+// rollbackFileMove moves the content of a renamed file back to its previous storage key.
+func (s *Service) rollbackFileMove(ctx context.Context, fd *filedata.FileData, oldKey string) error {
+	if err := s.fs.Move(ctx, filestorage.MoveParams{
+		UserID:         fd.UserID,
+		FromStorageKey: string(fd.StorageKey),
+		ToStorageKey:   oldKey,
+	}); err != nil {
+		return errors.Join(ErrRollBackFileSaveFailed, err)
+	}
+	return nil
+}
+
+// requireFolder ensures that the folder of the user exists; the root folder always exists.
+func (s *Service) requireFolder(ctx context.Context, userID uuid.UUID, folder string) error {
+	if folder == "" {
+		return nil
+	}
+	folders, err := s.loadFolders(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if findFolder(folders, folder) == nil {
+		return fmt.Errorf("folder %q not found: %w", folder, ErrFolderNotFound)
+	}
+	return nil
+}
+
+// loadFolders retrieves all folders of the user.
+func (s *Service) loadFolders(ctx context.Context, userID uuid.UUID) ([]*filedata.Folder, error) {
+	folders, err := s.folders.Load(ctx, filefolder.LoadParams{UserID: userID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load folders: %w", mapError(err))
+	}
+	return folders, nil
+}
+
+// checkFolderTarget ensures that no folder exists at the path and that its parent folder exists.
+func checkFolderTarget(folders []*filedata.Folder, path string) error {
+	if findFolder(folders, path) != nil {
+		return fmt.Errorf("folder %q: %w", path, ErrFolderExists)
+	}
+	if parent := filedata.ParentFolder(path); parent != "" && findFolder(folders, parent) == nil {
+		return fmt.Errorf("parent folder %q not found: %w", parent, ErrFolderNotFound)
+	}
+	return nil
+}
+
+// findFolder returns the folder with the path or nil when there is none.
+func findFolder(folders []*filedata.Folder, path string) *filedata.Folder {
+	for _, f := range folders {
+		if string(f.Path) == path {
+			return f
+		}
+	}
+	return nil
+}
@@ -0,0 +1,468 @@
+package filedata
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/filedata"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/itemorder"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/filedata"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/filefolder"
+	filestorage "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/filestorage"
+	orderRepository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/itemorder"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// folderStore keeps saved folders in memory for testing.
+type folderStore struct {
+	loadErr error
+	saved   []*filedata.Folder
+	folders []*filedata.Folder
+}
+
+func (f *folderStore) repo() *MockFolderRepository {
+	return &MockFolderRepository{
+		SaveFunc: func(_ context.Context, p filefolder.SaveParams) error {
+			f.saved = append(f.saved, p.Entity)
+			return nil
+		},
+		LoadFunc: func(context.Context, filefolder.LoadParams) ([]*filedata.Folder, error) {
+			return f.folders, f.loadErr
+		},
+	}
+}
+
+// newFolders creates folders of the user with the paths.
+func newFolders(userID uuid.UUID, paths ...string) []*filedata.Folder {
+	folders := make([]*filedata.Folder, 0, len(paths))
+	for _, p := range paths {
+		folders = append(folders, &filedata.Folder{ID: uuid.New(), UserID: userID, Path: []byte(p)})
+	}
+	return folders
+}
+
+func TestService_CreateFolder(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+
+	tests := []struct {
+		wantErr  error
+		name     string
+		path     string
+		wantPath string
+		existing []string
+	}{
+		{name: "top_level", path: "docs", wantPath: "docs"},
+		{name: "nested", path: "/docs/taxes/", existing: []string{"docs"}, wantPath: "docs/taxes"},
+		{name: "missing_parent", path: "docs/taxes", wantErr: ErrFolderNotFound},
+		{name: "exists", path: "docs", existing: []string{"docs"}, wantErr: ErrFolderExists},
+		{name: "invalid_path", path: "docs/../etc", wantErr: ErrFileIncorrectFolder},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			store := &folderStore{folders: newFolders(userID, tt.existing...)}
+			s := NewService(&MockRepository{}, &MockFileStorageRepository{}, store.repo(), directUnitOfWork{},
+				ownerAuthorizer{}, 0, nil, nil, nil, nil, nil)
+
+			got, err := s.CreateFolder(context.Background(), CreateFolderParams{Path: tt.path, UserID: userID})
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Empty(t, store.saved)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantPath, got.Path)
+			assert.Equal(t, userID, got.UserID)
+			require.Len(t, store.saved, 1)
+			assert.Equal(t, got.ID, store.saved[0].ID)
+		})
+	}
+}
+
+func TestService_MoveFolder(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+
+	tests := []struct {
+		wantErr         error
+		saveErr         error
+		name            string
+		from            string
+		to              string
+		existing        []string
+		wantFolderPaths []string
+		wantFileFolders []string
+	}{
+		{
+			name:            "rename_with_contents",
+			from:            "docs",
+			to:              "papers",
+			existing:        []string{"docs", "docs/taxes", "docs2"},
+			wantFolderPaths: []string{"papers", "papers/taxes"},
+			wantFileFolders: []string{"papers", "papers/taxes"},
+		},
+		{
+			name:            "move_below_other_folder",
+			from:            "docs",
+			to:              "archive/docs",
+			existing:        []string{"docs", "docs/taxes", "archive"},
+			wantFolderPaths: []string{"archive/docs", "archive/docs/taxes"},
+			wantFileFolders: []string{"archive/docs", "archive/docs/taxes"},
+		},
+		{name: "same_path", from: "docs", to: "docs/", existing: []string{"docs"}},
+		{
+			name:     "into_itself",
+			from:     "docs",
+			to:       "docs/taxes/docs",
+			existing: []string{"docs", "docs/taxes"},
+			wantErr:  ErrFolderMoveIntoItself,
+		},
+		{name: "target_exists", from: "docs", to: "docs2", existing: []string{"docs", "docs2"}, wantErr: ErrFolderExists},
+		{name: "missing_parent", from: "docs", to: "archive/docs", existing: []string{"docs"}, wantErr: ErrFolderNotFound},
+		{name: "missing_folder", to: "papers", wantErr: ErrFolderNotFound},
+		{
+			name:     "file_save_error",
+			from:     "docs",
+			to:       "papers",
+			existing: []string{"docs"},
+			saveErr:  errors.New("db down"),
+			wantErr:  ErrFileTechError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			store := &folderStore{folders: newFolders(userID, tt.existing...)}
+			files := []*filedata.FileData{
+				{ID: uuid.New(), UserID: userID, StorageKey: []byte("a.txt"), Folder: []byte("docs")},
+				{ID: uuid.New(), UserID: userID, StorageKey: []byte("b.txt"), Folder: []byte("docs/taxes")},
+				{ID: uuid.New(), UserID: userID, StorageKey: []byte("c.txt"), Folder: []byte("docs2")},
+				{ID: uuid.New(), UserID: userID, StorageKey: []byte("d.txt")},
+			}
+			// savedFiles collects the files whose metadata was saved.
+			var savedFiles []*filedata.FileData
+			repo := &MockRepository{
+				LoadFunc: func(context.Context, repository.LoadParams) ([]*filedata.FileData, error) {
+					return files, nil
+				},
+				SaveFunc: func(_ context.Context, p repository.SaveParams) error {
+					savedFiles = append(savedFiles, p.Entity)
+					return tt.saveErr
+				},
+			}
+			s := NewService(repo, &MockFileStorageRepository{}, store.repo(), directUnitOfWork{}, ownerAuthorizer{}, 0, nil, nil,
+				nil, nil, nil)
+
+			id := uuid.New()
+			if f := findFolder(store.folders, tt.from); f != nil {
+				id = f.ID
+			}
+			got, err := s.MoveFolder(context.Background(), MoveFolderParams{ID: id, Path: tt.to, UserID: userID})
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, id, got.ID)
+
+			// folderPaths collects the paths of the saved folders.
+			var folderPaths []string
+			for _, f := range store.saved {
+				folderPaths = append(folderPaths, string(f.Path))
+			}
+			assert.ElementsMatch(t, tt.wantFolderPaths, folderPaths)
+
+			// fileFolders collects the folders of the saved files.
+			var fileFolders []string
+			for _, fd := range savedFiles {
+				fileFolders = append(fileFolders, string(fd.Folder))
+			}
+			assert.ElementsMatch(t, tt.wantFileFolders, fileFolders)
+		})
+	}
+}
+
+// folderOrders implements OrderRepository for testing, holding the manual order of one folder.
+type folderOrders struct {
+	order *itemorder.ItemOrder
+}
+
+func (o folderOrders) Load(_ context.Context, params orderRepository.LoadParams) (*itemorder.ItemOrder, error) {
+	if o.order == nil || o.order.Folder != params.Folder {
+		return nil, orderRepository.ErrOrderNotFound
+	}
+	return o.order, nil
+}
+
+func TestService_ListFolder(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	files := []*filedata.FileData{
+		{ID: uuid.New(), UserID: userID, StorageKey: []byte("c.txt"), Folder: []byte("docs")},
+		{ID: uuid.New(), UserID: userID, StorageKey: []byte("a.txt"), Folder: []byte("docs")},
+		{ID: uuid.New(), UserID: userID, StorageKey: []byte("b.txt"), Folder: []byte("docs")},
+		{ID: uuid.New(), UserID: userID, StorageKey: []byte("nested.txt"), Folder: []byte("docs/taxes")},
+		{ID: uuid.New(), UserID: userID, StorageKey: []byte("root.txt")},
+	}
+
+	tests := []struct {
+		wantErr     error
+		name        string
+		params      ListFolderParams
+		wantFolders []string
+		wantFiles   []string
+		wantTotal   int
+	}{
+		{
+			name:        "root",
+			params:      ListFolderParams{Limit: 10},
+			wantFolders: []string{"docs", "photos"},
+			wantFiles:   []string{"root.txt"},
+			wantTotal:   1,
+		},
+		{
+			name:        "folder_first_page",
+			params:      ListFolderParams{Folder: "/docs/", Limit: 2},
+			wantFolders: []string{"docs/taxes"},
+			wantFiles:   []string{"a.txt", "b.txt"},
+			wantTotal:   3,
+		},
+		{
+			name:        "folder_last_page",
+			params:      ListFolderParams{Folder: "docs", Offset: 2, Limit: 2},
+			wantFolders: []string{"docs/taxes"},
+			wantFiles:   []string{"c.txt"},
+			wantTotal:   3,
+		},
+		{
+			name:        "offset_past_end",
+			params:      ListFolderParams{Folder: "docs", Offset: 10, Limit: 2},
+			wantFolders: []string{"docs/taxes"},
+			wantTotal:   3,
+		},
+		{
+			name:        "folder_manual_order",
+			params:      ListFolderParams{Folder: "docs", Limit: 2, ManualOrder: true},
+			wantFolders: []string{"docs/taxes"},
+			wantFiles:   []string{"c.txt", "b.txt"},
+			wantTotal:   3,
+		},
+		{name: "missing_folder", params: ListFolderParams{Folder: "music"}, wantErr: ErrFolderNotFound},
+		{name: "invalid_folder", params: ListFolderParams{Folder: "a/../b"}, wantErr: ErrFileIncorrectFolder},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			store := &folderStore{folders: newFolders(userID, "photos", "docs/taxes", "docs")}
+			repo := &MockRepository{
+				LoadFunc: func(context.Context, repository.LoadParams) ([]*filedata.FileData, error) {
+					return files, nil
+				},
+			}
+			orders := folderOrders{order: &itemorder.ItemOrder{
+				Folder:  "docs",
+				Entries: []itemorder.Entry{{ItemID: files[0].ID, Pinned: true}, {ItemID: files[2].ID}},
+			}}
+			s := NewService(repo, &MockFileStorageRepository{}, store.repo(), directUnitOfWork{}, ownerAuthorizer{}, 0, nil, nil,
+				nil, nil, orders)
+
+			params := tt.params
+			params.UserID = userID
+			got, err := s.ListFolder(context.Background(), params)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			// folders collects the paths of the listed folders.
+			folders := []string{}
+			for _, f := range got.Folders {
+				folders = append(folders, f.Path)
+			}
+			// keys collects the storage keys of the listed files.
+			keys := []string{}
+			for _, fd := range got.Files {
+				keys = append(keys, fd.StorageKey)
+			}
+			assert.Equal(t, tt.wantFolders, folders)
+			if tt.wantFiles == nil {
+				tt.wantFiles = []string{}
+			}
+			assert.Equal(t, tt.wantFiles, keys)
+			assert.Equal(t, tt.wantTotal, got.Total)
+		})
+	}
+}
+
+func TestService_MoveFile(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	fileID := uuid.New()
+
+	tests := []struct {
+		moveErr    error
+		saveErr    error
+		wantErr    error
+		name       string
+		params     MoveFileParams
+		wantKey    string
+		wantFolder string
+		wantMoves  []filestorage.MoveParams
+	}{
+		{
+			name:       "move_to_folder",
+			params:     MoveFileParams{Folder: "docs"},
+			wantKey:    "inbox.txt",
+			wantFolder: "docs",
+		},
+		{
+			name:       "rename_in_root",
+			params:     MoveFileParams{StorageKey: "renamed.txt"},
+			wantKey:    "renamed.txt",
+			wantFolder: "",
+			wantMoves: []filestorage.MoveParams{
+				{UserID: userID, FromStorageKey: "inbox.txt", ToStorageKey: "renamed.txt"},
+			},
+		},
+		{name: "missing_folder", params: MoveFileParams{Folder: "music"}, wantErr: ErrFolderNotFound},
+		{name: "invalid_storage_key", params: MoveFileParams{StorageKey: "../x"}, wantErr: ErrFileIncorrectStorageKey},
+		{
+			name:    "storage_key_taken",
+			params:  MoveFileParams{StorageKey: "taken.txt"},
+			moveErr: filestorage.ErrStorageKeyTaken,
+			wantErr: ErrFileStorageKeyTaken,
+			wantMoves: []filestorage.MoveParams{
+				{UserID: userID, FromStorageKey: "inbox.txt", ToStorageKey: "taken.txt"},
+			},
+		},
+		{
+			name:    "metadata_save_error_moves_content_back",
+			params:  MoveFileParams{StorageKey: "renamed.txt"},
+			saveErr: errors.New("db down"),
+			wantErr: ErrFileTechError,
+			wantMoves: []filestorage.MoveParams{
+				{UserID: userID, FromStorageKey: "inbox.txt", ToStorageKey: "renamed.txt"},
+				{UserID: userID, FromStorageKey: "renamed.txt", ToStorageKey: "inbox.txt"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &MockRepository{
+				LoadFunc: func(context.Context, repository.LoadParams) ([]*filedata.FileData, error) {
+					return []*filedata.FileData{
+						{ID: fileID, UserID: userID, StorageKey: []byte("inbox.txt"), Folder: []byte("")},
+					}, nil
+				},
+				SaveFunc: func(context.Context, repository.SaveParams) error { return tt.saveErr },
+			}
+			// moves collects the content moves requested from storage.
+			var moves []filestorage.MoveParams
+			fs := &MockFileStorageRepository{
+				MoveFunc: func(_ context.Context, p filestorage.MoveParams) error {
+					moves = append(moves, p)
+					if len(moves) == 1 {
+						return tt.moveErr
+					}
+					return nil
+				},
+			}
+			store := &folderStore{folders: newFolders(userID, "docs")}
+			s := NewService(repo, fs, store.repo(), directUnitOfWork{}, ownerAuthorizer{}, 0, nil, nil, nil, nil, nil)
+
+			params := tt.params
+			params.ID, params.UserID = fileID, userID
+			got, err := s.MoveFile(context.Background(), params)
+			assert.Equal(t, tt.wantMoves, moves)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantKey, got.StorageKey)
+			assert.Equal(t, tt.wantFolder, got.Folder)
+		})
+	}
+}
+
+func TestService_Push_Folder(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	fileID := uuid.New()
+
+	tests := []struct {
+		wantErr    error
+		name       string
+		params     PushParams
+		wantFolder string
+	}{
+		{
+			name:       "create_in_folder",
+			params:     PushParams{StorageKey: "a.txt", Folder: "docs", Data: []byte("x"), UserID: userID},
+			wantFolder: "docs",
+		},
+		{
+			name:    "create_in_missing_folder",
+			params:  PushParams{StorageKey: "a.txt", Folder: "music", Data: []byte("x"), UserID: userID},
+			wantErr: ErrFolderNotFound,
+		},
+		{
+			name:       "update_keeps_folder",
+			params:     PushParams{ID: fileID, StorageKey: "a.txt", Data: []byte("x"), UserID: userID},
+			wantFolder: "docs/taxes",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// saved holds the file metadata passed to the repository.
+			var saved *filedata.FileData
+			repo := &MockRepository{
+				LoadFunc: func(context.Context, repository.LoadParams) ([]*filedata.FileData, error) {
+					return []*filedata.FileData{
+						{ID: fileID, UserID: userID, StorageKey: []byte("a.txt"), Folder: []byte("docs/taxes")},
+					}, nil
+				},
+				SaveFunc: func(_ context.Context, p repository.SaveParams) error {
+					saved = p.Entity
+					return nil
+				},
+			}
+			store := &folderStore{folders: newFolders(userID, "docs", "docs/taxes")}
+			s := NewService(repo, &MockFileStorageRepository{}, store.repo(), directUnitOfWork{}, ownerAuthorizer{}, 0, nil, nil,
+				nil, nil, nil)
+
+			params := tt.params
+			_, err := s.Push(context.Background(), &params)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, saved)
+				return
+			}
+			require.NoError(t, err)
+			require.NotNil(t, saved)
+			assert.Equal(t, tt.wantFolder, string(saved.Folder))
+		})
+	}
+}
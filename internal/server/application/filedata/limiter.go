@@ -0,0 +1,92 @@
+package filedata
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"time"
+
+	"golang.org/x/sync/semaphore"
+)
+
+// TransferLimits contains the bounds of the file contents encrypted or decrypted at once.
+type TransferLimits struct {
+	// Workers limits the number of file contents processed concurrently (0 uses one per CPU).
+	Workers int
+	// MemoryBudget limits the total bytes of the file contents processed at once (0 means no limit).
+	MemoryBudget int64
+	// QueueTimeout bounds the wait for a free worker and memory budget (0 rejects busy transfers at once).
+	QueueTimeout time.Duration
+}
+
+// Limiter bounds the file contents the service encrypts or decrypts at once, so many simultaneous large
+// transfers cannot exhaust the server memory. Transfers beyond the limits queue for up to the queue timeout
+// and are then rejected with ErrFileBusy. A nil Limiter admits every transfer at once.
+type Limiter struct {
+	// workers holds a token for every transfer in progress.
+	workers chan struct{}
+	// memory tracks the bytes of the file contents in progress; nil when the memory is not limited.
+	memory *semaphore.Weighted
+	// budget is the capacity of memory in bytes.
+	budget int64
+	// wait bounds how long a transfer queues for a worker and memory.
+	wait time.Duration
+}
+
+// NewLimiter creates a Limiter enforcing the limits.
+func NewLimiter(limits TransferLimits) *Limiter {
+	workers := limits.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	l := &Limiter{workers: make(chan struct{}, workers), budget: limits.MemoryBudget, wait: limits.QueueTimeout}
+	if l.budget > 0 {
+		l.memory = semaphore.NewWeighted(l.budget)
+	}
+	return l
+}
+
+// acquire waits for a worker and size bytes of the memory budget and returns a function releasing them.
+// A file larger than the whole budget waits until it has the budget to itself.
+func (l *Limiter) acquire(ctx context.Context, size int64) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, l.wait)
+	defer cancel()
+
+	select {
+	case l.workers <- struct{}{}:
+	default:
+		select {
+		case l.workers <- struct{}{}:
+		case <-waitCtx.Done():
+			return nil, l.busy(ctx, "worker")
+		}
+	}
+	if l.memory == nil {
+		return func() { <-l.workers }, nil
+	}
+
+	weight := min(max(size, 0), l.budget)
+	if !l.memory.TryAcquire(weight) {
+		if err := l.memory.Acquire(waitCtx, weight); err != nil {
+			<-l.workers
+			return nil, l.busy(ctx, "memory budget")
+		}
+	}
+	return func() {
+		l.memory.Release(weight)
+		<-l.workers
+	}, nil
+}
+
+// busy returns the error of a transfer that got no free resource in time; a transfer whose own context
+// ended gets the context error instead.
+func (l *Limiter) busy(ctx context.Context, resource string) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("file transfer canceled while waiting for a %s: %w", resource, err)
+	}
+	return fmt.Errorf("no free file transfer %s within %s: %w", resource, l.wait, ErrFileBusy)
+}
@@ -0,0 +1,141 @@
+package filedata
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	filestorage "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/filestorage"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimiter_Acquire(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		limiter *Limiter
+		name    string
+		held    []int64
+		size    int64
+		wantErr error
+	}{
+		{
+			name:    "nil limiter admits every transfer",
+			limiter: nil,
+			size:    1 << 30,
+		},
+		{
+			name:    "free worker",
+			limiter: NewLimiter(TransferLimits{Workers: 2}),
+			held:    []int64{10},
+			size:    10,
+		},
+		{
+			name:    "no free worker",
+			limiter: NewLimiter(TransferLimits{Workers: 1, QueueTimeout: 10 * time.Millisecond}),
+			held:    []int64{10},
+			size:    10,
+			wantErr: ErrFileBusy,
+		},
+		{
+			name:    "memory within budget",
+			limiter: NewLimiter(TransferLimits{Workers: 4, MemoryBudget: 100}),
+			held:    []int64{60},
+			size:    40,
+		},
+		{
+			name:    "memory budget exhausted",
+			limiter: NewLimiter(TransferLimits{Workers: 4, MemoryBudget: 100, QueueTimeout: 10 * time.Millisecond}),
+			held:    []int64{60},
+			size:    50,
+			wantErr: ErrFileBusy,
+		},
+		{
+			name:    "file larger than the idle budget",
+			limiter: NewLimiter(TransferLimits{Workers: 4, MemoryBudget: 100}),
+			size:    500,
+		},
+		{
+			name:    "file larger than the busy budget",
+			limiter: NewLimiter(TransferLimits{Workers: 4, MemoryBudget: 100}),
+			held:    []int64{1},
+			size:    500,
+			wantErr: ErrFileBusy,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			for _, size := range tt.held {
+				release, err := tt.limiter.acquire(context.Background(), size)
+				require.NoError(t, err)
+				defer release()
+			}
+
+			release, err := tt.limiter.acquire(context.Background(), tt.size)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			release()
+		})
+	}
+}
+
+func TestLimiter_AcquireQueues(t *testing.T) {
+	t.Parallel()
+
+	l := NewLimiter(TransferLimits{Workers: 1, MemoryBudget: 100, QueueTimeout: time.Minute})
+	release, err := l.acquire(context.Background(), 100)
+	require.NoError(t, err)
+	time.AfterFunc(10*time.Millisecond, release)
+
+	queued, err := l.acquire(context.Background(), 100)
+	require.NoError(t, err, "the queued transfer should start once the first one ends")
+	queued()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	held, err := l.acquire(ctx, 1)
+	require.NoError(t, err)
+	defer held()
+	cancel()
+	_, err = l.acquire(ctx, 1)
+	require.ErrorIs(t, err, context.Canceled)
+	assert.NotErrorIs(t, err, ErrFileBusy, "a canceled transfer is not busy")
+}
+
+func TestService_PushBusy(t *testing.T) {
+	t.Parallel()
+
+	l := NewLimiter(TransferLimits{Workers: 1})
+	release, err := l.acquire(context.Background(), 1)
+	require.NoError(t, err)
+	defer release()
+
+	fs := &MockFileStorageRepository{
+		SaveFunc: func(ctx context.Context, params filestorage.SaveParams) error {
+			t.Error("a rejected upload should not store content")
+			return nil
+		},
+		DeleteFunc: func(ctx context.Context, params filestorage.DeleteParams) error {
+			t.Error("a rejected upload should not delete content")
+			return nil
+		},
+	}
+	s := NewService(
+		&MockRepository{}, fs, &MockFolderRepository{}, directUnitOfWork{}, ownerAuthorizer{}, 0, l, nil, nil, nil, nil,
+	)
+
+	_, err = s.Push(context.Background(), &PushParams{
+		ID:         uuid.New(),
+		UserID:     uuid.New(),
+		StorageKey: "docs/file.txt",
+		Data:       []byte("content"),
+	})
+	require.ErrorIs(t, err, ErrFileBusy)
+}
@@ -4,10 +4,20 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 
+	authzApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/authz"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/authz"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/filedata"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/itemorder"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/itempath"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/itemtag"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/db"
 	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/filedata"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/filefolder"
 	filestorage "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/filestorage"
+	orderRepository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/itemorder"
+	tagRepository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/itemtag"
 	"github.com/google/uuid"
 )
 
@@ -27,6 +37,62 @@ type FileStorageRepository interface {
 	Load(ctx context.Context, params filestorage.LoadParams) ([]byte, error)
 	// Delete removes file content using the provided parameters.
 	Delete(ctx context.Context, params filestorage.DeleteParams) error
+	// NewFileKey generates a per-file content key wrapped by the user key.
+	NewFileKey(ctx context.Context, params filestorage.FileKeyParams) ([]byte, error)
+	// Rewrap re-wraps a file key from the previous user key to the current one.
+	Rewrap(ctx context.Context, params filestorage.RewrapParams) ([]byte, error)
+	// Move moves file content to another storage key without overwriting.
+	Move(ctx context.Context, params filestorage.MoveParams) error
+	// Usage returns the number of bytes the stored files of a user occupy.
+	Usage(ctx context.Context, params filestorage.UsageParams) (int64, error)
+}
+
+// FolderRepository defines the interface for file folder persistence operations.
+type FolderRepository interface {
+	// Save persists a folder using the provided parameters.
+	Save(ctx context.Context, params filefolder.SaveParams) error
+	// Load retrieves folders using the provided parameters.
+	Load(ctx context.Context, params filefolder.LoadParams) ([]*filedata.Folder, error)
+}
+
+// UnitOfWork defines the interface for running several repository writes as one transaction.
+type UnitOfWork interface {
+	// Do executes fn in a single transaction scoped to the user, rolling back every write on error.
+	Do(ctx context.Context, userID uuid.UUID, fn func(ctx context.Context) error) error
+}
+
+// Authorizer defines the interface for the authorization decision point.
+type Authorizer interface {
+	// Authorize decides whether the user may perform the action on the file.
+	Authorize(ctx context.Context, req authzApp.Request) error
+}
+
+// PlanEnforcer defines the interface for checking the limits of the plans of users.
+type PlanEnforcer interface {
+	// CheckItems ensures that the plan of the user has room for the given number of additional items.
+	CheckItems(ctx context.Context, userID uuid.UUID, added int) error
+	// CheckFileSize ensures that the plan of the user allows storing a file of size bytes.
+	CheckFileSize(ctx context.Context, userID uuid.UUID, size int64) error
+	// MaxFileSize returns the largest file in bytes the plan of the user allows; zero means no limit.
+	MaxFileSize(ctx context.Context, userID uuid.UUID) (int64, error)
+}
+
+// PolicyEnforcer defines the interface for checking vault items against the item policies of users.
+type PolicyEnforcer interface {
+	// CheckItem ensures that the item policies of the user allow storing items of the kind.
+	CheckItem(ctx context.Context, userID uuid.UUID, kind string) error
+}
+
+// TagRepository defines the interface for looking up the tags of vault items.
+type TagRepository interface {
+	// Load retrieves the tags of the items of the user matching the parameters.
+	Load(ctx context.Context, params tagRepository.LoadParams) ([]*itemtag.ItemTags, error)
+}
+
+// OrderRepository defines the interface for looking up the manual orders of folders.
+type OrderRepository interface {
+	// Load retrieves the manual order of a folder of the user.
+	Load(ctx context.Context, params orderRepository.LoadParams) (*itemorder.ItemOrder, error)
 }
 
 // Service provides file data management business logic operations.
@@ -35,11 +101,56 @@ type Service struct {
 	r Repository
 	// fs handles actual file content storage.
 	fs FileStorageRepository
+	// folders handles file folder persistence.
+	folders FolderRepository
+	// uow groups the writes of folder moves into a single transaction.
+	uow UnitOfWork
+	// authorizer decides whether users may perform actions on files.
+	authorizer Authorizer
+	// limiter bounds the file contents encrypted or decrypted at once; nil admits every transfer.
+	limiter *Limiter
+	// plans checks the plan limits of stored files; nil leaves them unlimited.
+	plans PlanEnforcer
+	// policies checks created and updated files against the item policies; nil enforces none.
+	policies PolicyEnforcer
+	// quota limits the bytes the stored files of each user may occupy; zero means no limit.
+	quota int64
+	// tags looks up archived files; nil archives none.
+	tags TagRepository
+	// orders looks up the manual orders of folders; nil keeps every listing in its default order.
+	orders OrderRepository
 }
 
-// NewService creates a new file data service with the provided repositories.
-func NewService(r Repository, fs FileStorageRepository) *Service {
-	return &Service{r: r, fs: fs}
+// NewService creates a new file data service with the provided repositories, authorization decision point,
+// per-user storage quota in bytes (0 means no limit), transfer limiter (nil means no limit), plan enforcer
+// (nil means no plan limits), item policy enforcer (nil means no item policies), tag repository (nil means no
+// archived files) and order repository (nil means no manual orders).
+func NewService(
+	r Repository,
+	fs FileStorageRepository,
+	folders FolderRepository,
+	uow UnitOfWork,
+	authorizer Authorizer,
+	quota int64,
+	limiter *Limiter,
+	plans PlanEnforcer,
+	policies PolicyEnforcer,
+	tags TagRepository,
+	orders OrderRepository,
+) *Service {
+	return &Service{
+		r:          r,
+		fs:         fs,
+		folders:    folders,
+		uow:        uow,
+		authorizer: authorizer,
+		quota:      quota,
+		limiter:    limiter,
+		plans:      plans,
+		policies:   policies,
+		tags:       tags,
+		orders:     orders,
+	}
 }
 
 // Pull retrieves a specific file's metadata and content by ID.
@@ -48,9 +159,20 @@ func (s *Service) Pull(ctx context.Context, params PullParams) (*FileData, error
 	if err != nil {
 		return nil, fmt.Errorf("failed to load metadata: %w", err)
 	}
+	if err := s.authorize(ctx, authz.ActionRead, fd.ID, fd.UserID, params.UserID); err != nil {
+		return nil, err
+	}
+
+	release, err := s.limiter.acquire(ctx, fd.Size)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start file transfer: %w", err)
+	}
+	defer release()
 
 	fileData, err := s.fs.Load(ctx, filestorage.LoadParams{
 		UserID:     fd.UserID,
+		FileID:     fd.ID,
+		FileKey:    fd.FileKey,
 		StorageKey: string(fd.StorageKey),
 	})
 	if err != nil {
@@ -65,51 +187,175 @@ func (s *Service) Pull(ctx context.Context, params PullParams) (*FileData, error
 
 // List retrieves all files belonging to the specified user.
 func (s *Service) List(ctx context.Context, params ListParams) ([]*FileData, error) {
+	if err := s.authorize(ctx, authz.ActionRead, uuid.Nil, params.UserID, params.UserID); err != nil {
+		return nil, err
+	}
 	fds, err := s.r.Load(ctx, repository.LoadParams{
+		After:  params.After,
+		Limit:  params.Limit,
 		UserID: params.UserID,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to load files: %w", mapError(err))
 	}
-	return newFilesFromDomain(fds), nil
+
+	archived, err := s.archived(ctx, params.UserID)
+	if err != nil {
+		return nil, err
+	}
+	result := newFilesFromDomain(fds)
+	for _, f := range result {
+		f.Archived = archived[f.ID]
+	}
+	if params.ExcludeArchived {
+		result = slices.DeleteFunc(result, func(f *FileData) bool { return f.Archived })
+	}
+	if params.ManualOrder {
+		order, err := s.order(ctx, params.UserID, params.OrderFolder)
+		if err != nil {
+			return nil, err
+		}
+		itemorder.Sort(order, result, func(f *FileData) uuid.UUID { return f.ID })
+	}
+	return result, nil
+}
+
+// archived returns the IDs of the archived files of the user; without a tag repository none is archived.
+func (s *Service) archived(ctx context.Context, userID uuid.UUID) (map[uuid.UUID]bool, error) {
+	if s.tags == nil {
+		return nil, nil
+	}
+	tagged, err := s.tags.Load(ctx, tagRepository.LoadParams{Tag: itemtag.Archived, UserID: userID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load archived files: %w", mapError(err))
+	}
+	return itemtag.ItemIDs(tagged), nil
+}
+
+// order returns the manual order of the folder of the user; without an order repository, or for a folder the user
+// has not arranged, there is none.
+func (s *Service) order(ctx context.Context, userID uuid.UUID, folder string) (*itemorder.ItemOrder, error) {
+	if s.orders == nil {
+		return nil, nil
+	}
+	o, err := s.orders.Load(ctx, orderRepository.LoadParams{Folder: itempath.Normalize(folder), UserID: userID})
+	if errors.Is(err, orderRepository.ErrOrderNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load item order: %w", mapError(err))
+	}
+	return o, nil
+}
+
+// ReserveTransfer waits for the limiter to admit a transfer of size bytes and returns a function releasing it.
+// Callers reserve an upload by its declared size before reading the content, so a rejected upload is never
+// read into memory.
+func (s *Service) ReserveTransfer(ctx context.Context, size int64) (func(), error) {
+	release, err := s.limiter.acquire(ctx, size)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start file transfer: %w", err)
+	}
+	return release, nil
 }
 
 // Push creates or updates a file for the specified user with validation and encryption.
+// The stored files of the user must stay within the storage quota, the replaced content not counted.
+// The upload waits for the limiter before anything is changed, so a rejected upload leaves the stored file intact;
+// an upload reserved with ReserveTransfer does not wait again.
 func (s *Service) Push(ctx context.Context, params *PushParams) (uuid.UUID, error) {
-	if len(params.Data) == 0 {
+	if len(params.Data) == 0 && !params.AllowEmpty {
 		return uuid.Nil, fmt.Errorf("file data is required: %w", ErrFileDataRequired)
 	}
 
+	if !params.Reserved {
+		release, err := s.ReserveTransfer(ctx, int64(len(params.Data)))
+		if err != nil {
+			return uuid.Nil, err
+		}
+		defer release()
+	}
+
 	fd, err := filedata.NewFile(filedata.NewFileDataParams{
 		UserID:      params.UserID,
 		StorageKey:  params.StorageKey,
 		HashSum:     params.calculateDataHashSum(),
 		Description: params.Description,
+		Folder:      params.Folder,
+		Size:        int64(len(params.Data)),
 	})
 	if err != nil {
 		return uuid.Nil, fmt.Errorf("failed to create file: %w", mapError(err))
 	}
 
+	if err := s.requireFolder(ctx, params.UserID, string(fd.Folder)); err != nil {
+		return uuid.Nil, err
+	}
+
+	// existing holds the stored metadata of an updated file; nil for new files.
+	var existing *filedata.FileData
+	// replaced is the storage key of the content the upload takes the place of.
+	replaced := string(fd.StorageKey)
 	if params.ID != uuid.Nil {
-		existing, err := s.findFileForUpdate(ctx, params)
-		if err != nil {
+		if existing, err = s.findFileForUpdate(ctx, params); err != nil {
 			return uuid.Nil, fmt.Errorf("update file access error: %w", err)
 		}
 		fd.ID = params.ID
-		if err := s.removeOldFileOnKeyChange(ctx, existing, params.StorageKey); err != nil {
-			return uuid.Nil, fmt.Errorf("old file delete error: %w", err)
+		if params.Folder == "" {
+			fd.Folder = existing.Folder
 		}
+		replaced = string(existing.StorageKey)
+	} else if err := s.authorize(ctx, authz.ActionWrite, uuid.Nil, params.UserID, params.UserID); err != nil {
+		return uuid.Nil, fmt.Errorf("create file access error: %w", err)
 	}
 
-	if err := s.fs.Save(ctx, filestorage.SaveParams{
-		UserID:     fd.UserID,
-		StorageKey: string(fd.StorageKey),
-		Data:       params.Data,
-	}); err != nil {
-		return uuid.Nil, fmt.Errorf("failed to save file data: %w", mapError(err))
+	if err := s.checkPlan(ctx, params.UserID, fd.Size, existing == nil); err != nil {
+		return uuid.Nil, fmt.Errorf("plan check for storing file failed: %w", err)
+	}
+	if err := s.checkPolicy(ctx, params.UserID); err != nil {
+		return uuid.Nil, fmt.Errorf("item policy check for storing file failed: %w", err)
+	}
+	if err := s.checkQuota(ctx, params.UserID, replaced, fd.Size); err != nil {
+		return uuid.Nil, err
+	}
+
+	// newContent reports whether the file content is stored under a key it did not occupy before.
+	newContent := true
+	if existing != nil {
+		newContent = string(existing.StorageKey) != params.StorageKey
+	}
+
+	saveContent := func(ctx context.Context) error {
+		if err := s.fs.Save(ctx, filestorage.SaveParams{
+			UserID:     fd.UserID,
+			FileID:     fd.ID,
+			FileKey:    fd.FileKey,
+			StorageKey: string(fd.StorageKey),
+			Data:       params.Data,
+		}); err != nil {
+			return fmt.Errorf("failed to save file data: %w", mapError(err))
+		}
+		return nil
+	}
+	if newContent {
+		if fd.FileKey, err = s.fs.NewFileKey(ctx, filestorage.FileKeyParams{UserID: fd.UserID, FileID: fd.ID}); err != nil {
+			return uuid.Nil, fmt.Errorf("failed to create file key: %w", mapError(err))
+		}
+		if err := saveContent(ctx); err != nil {
+			return uuid.Nil, err
+		}
+		// Within a unit of work the metadata may still roll back after Push returns.
+		db.OnRollback(ctx, func(ctx context.Context) error { return s.rollbackFileSave(ctx, fd) })
+	} else {
+		// Content replaced in place keeps its file key and is written only after the metadata, so a rollback or
+		// dry run leaves the stored content readable with the key the file metadata still holds.
+		fd.FileKey = existing.FileKey
 	}
 
 	if err := s.r.Save(ctx, repository.SaveParams{Entity: fd}); err != nil {
+		if !newContent {
+			return uuid.Nil, fmt.Errorf("failed to save file metadata: %w", mapError(err))
+		}
 		if rollbackErr := s.rollbackFileSave(ctx, fd); rollbackErr != nil {
 			return uuid.Nil, errors.Join(
 				fmt.Errorf("failed to save file metadata: %w", mapError(err)),
@@ -119,17 +365,116 @@ func (s *Service) Push(ctx context.Context, params *PushParams) (uuid.UUID, erro
 		return uuid.Nil, fmt.Errorf("failed to save file metadata: %w", mapError(err))
 	}
 
+	if !newContent {
+		replaceContent := func(ctx context.Context) error { return s.replaceFileContent(ctx, existing, saveContent) }
+		if !db.OnCommit(ctx, replaceContent) {
+			if err := replaceContent(ctx); err != nil {
+				return uuid.Nil, err
+			}
+		}
+	}
+
+	if existing != nil {
+		// The old content is removed only once the new metadata is committed, so a rollback restoring the old
+		// metadata finds its content intact.
+		removeOld := func(ctx context.Context) error {
+			return s.removeOldFileOnKeyChange(ctx, existing, params.StorageKey)
+		}
+		if !db.OnCommit(ctx, removeOld) {
+			if err := removeOld(ctx); err != nil {
+				return uuid.Nil, fmt.Errorf("old file delete error: %w", err)
+			}
+		}
+	}
+
 	return fd.ID, nil
 }
 
+// CheckUpload ensures that the plan and the storage quota of the user accept size bytes of content pushed with
+// params and returns the largest content in bytes they accept, negative when neither bounds it. Streaming
+// uploads check once before writing and again when their content outgrows the limit, so they abort without
+// buffering the whole content.
+func (s *Service) CheckUpload(ctx context.Context, params *PushParams, size int64) (int64, error) {
+	// replaced is the storage key of the content the upload takes the place of.
+	replaced := params.StorageKey
+	if params.ID != uuid.Nil {
+		existing, err := s.findFileForUpdate(ctx, params)
+		if err != nil {
+			return 0, fmt.Errorf("update file access error: %w", err)
+		}
+		replaced = string(existing.StorageKey)
+	}
+
+	if err := s.checkPlan(ctx, params.UserID, size, false); err != nil {
+		return 0, fmt.Errorf("plan check for storing file failed: %w", err)
+	}
+	if err := s.checkQuota(ctx, params.UserID, replaced, size); err != nil {
+		return 0, err
+	}
+
+	limit := int64(-1)
+	if s.plans != nil {
+		maxSize, err := s.plans.MaxFileSize(ctx, params.UserID)
+		if err != nil {
+			return 0, fmt.Errorf("plan check for storing file failed: %w", err)
+		}
+		if maxSize > 0 {
+			limit = maxSize
+		}
+	}
+	if s.quota > 0 {
+		used, err := s.fs.Usage(ctx, filestorage.UsageParams{UserID: params.UserID, Exclude: replaced})
+		if err != nil {
+			return 0, fmt.Errorf("failed to measure storage usage: %w", mapError(err))
+		}
+		if free := s.quota - used; limit < 0 || free < limit {
+			limit = free
+		}
+	}
+	return limit, nil
+}
+
+// RewrapKeys re-wraps the file keys of a user with the current user key after a user key rotation
+// and returns the number of files updated. File content is not re-encrypted; files stored before
+// per-file keys were introduced have no file key and are skipped.
+func (s *Service) RewrapKeys(ctx context.Context, params RewrapKeysParams) (int, error) {
+	fds, err := s.r.Load(ctx, repository.LoadParams{UserID: params.UserID})
+	if err != nil {
+		return 0, fmt.Errorf("failed to load files: %w", mapError(err))
+	}
+
+	// rewrapped counts the files whose key was re-wrapped.
+	var rewrapped int
+	for _, fd := range fds {
+		if len(fd.FileKey) == 0 {
+			continue
+		}
+		fileKey, err := s.fs.Rewrap(ctx, filestorage.RewrapParams{
+			UserID:     fd.UserID,
+			FileID:     fd.ID,
+			FileKey:    fd.FileKey,
+			OldUserKey: params.OldUserKey,
+		})
+		if err != nil {
+			return rewrapped, fmt.Errorf("failed to rewrap key of file %s: %w", fd.ID, mapError(err))
+		}
+		fd.FileKey = fileKey
+		if err := s.r.Save(ctx, repository.SaveParams{Entity: fd}); err != nil {
+			return rewrapped, fmt.Errorf("failed to save file %s metadata: %w", fd.ID, mapError(err))
+		}
+		rewrapped++
+	}
+	return rewrapped, nil
+}
+
 // findFileForUpdate retrieves and validates access to an existing file for update operations.
 func (s *Service) findFileForUpdate(ctx context.Context, params *PushParams) (*filedata.FileData, error) {
 	existing, err := s.loadMetadata(ctx, PullParams{ID: params.ID, UserID: params.UserID})
 	if err != nil {
 		return nil, fmt.Errorf("access check for updating file failed: %w", err)
 	}
-	if existing.UserID != params.UserID {
-		return nil, fmt.Errorf("access denied to file: %w", ErrFileAccessDenied)
+	if err := s.authorize(ctx, authz.ActionWrite, existing.ID, existing.UserID, params.UserID); err != nil {
+		return nil, err
 	}
 	return existing, nil
 }
@@ -151,6 +496,63 @@ func (s *Service) removeOldFileOnKeyChange(
 	return nil
 }
 
+// replaceFileContent writes content replacing the stored content of the file in place with save. The metadata
+// describing the new content is saved before, so when the write fails it is restored to the existing metadata,
+// which still describes the stored content.
+func (s *Service) replaceFileContent(
+	ctx context.Context,
+	existing *filedata.FileData,
+	save func(ctx context.Context) error,
+) error {
+	err := save(ctx)
+	if err == nil {
+		return nil
+	}
+	if restoreErr := s.r.Save(ctx, repository.SaveParams{Entity: existing}); restoreErr != nil {
+		return errors.Join(err, fmt.Errorf("failed to restore file metadata: %w", mapError(restoreErr)))
+	}
+	return err
+}
+
+// checkPlan ensures that the plan of the user accepts a file of size bytes and, when the file is new, has room
+// for one more item.
+func (s *Service) checkPlan(ctx context.Context, userID uuid.UUID, size int64, newFile bool) error {
+	if s.plans == nil {
+		return nil
+	}
+	if err := s.plans.CheckFileSize(ctx, userID, size); err != nil {
+		return err
+	}
+	if newFile {
+		return s.plans.CheckItems(ctx, userID, 1)
+	}
+	return nil
+}
+
+// checkPolicy ensures that the item policies of the user allow storing files.
+func (s *Service) checkPolicy(ctx context.Context, userID uuid.UUID) error {
+	if s.policies == nil {
+		return nil
+	}
+	return s.policies.CheckItem(ctx, userID, authz.KindFile)
+}
+
+// checkQuota ensures that storing size bytes in place of the content under the replaced storage key keeps
+// the stored files of the user within the quota. A zero quota disables the check.
+func (s *Service) checkQuota(ctx context.Context, userID uuid.UUID, replaced string, size int64) error {
+	if s.quota <= 0 {
+		return nil
+	}
+	used, err := s.fs.Usage(ctx, filestorage.UsageParams{UserID: userID, Exclude: replaced})
+	if err != nil {
+		return fmt.Errorf("failed to measure storage usage: %w", mapError(err))
+	}
+	if used+size > s.quota {
+		return fmt.Errorf("%d of %d bytes in use: %w", used, s.quota, ErrFileQuotaExceeded)
+	}
+	return nil
+}
+
 // rollbackFileSave removes a saved file from storage during transaction rollback operations.
 func (s *Service) rollbackFileSave(ctx context.Context, fd *filedata.FileData) error {
 	if deleteErr := s.fs.Delete(ctx, filestorage.DeleteParams{
@@ -177,3 +579,19 @@ func (s *Service) loadMetadata(ctx context.Context, params PullParams) (*filedat
 
 	return fds[0], nil
 }
+
+// authorize asks the authorization decision point whether the user may perform the action on a file of the owner.
+// The fileID is uuid.Nil for new files and for the whole collection.
+func (s *Service) authorize(ctx context.Context, action string, fileID, ownerID, userID uuid.UUID) error {
+	err := s.authorizer.Authorize(ctx, authzApp.Request{
+		Action:     action,
+		Kind:       authz.KindFile,
+		ResourceID: fileID,
+		OwnerID:    ownerID,
+		SubjectID:  userID,
+	})
+	if err != nil {
+		return fmt.Errorf("access denied to file %s: %w: %w", fileID, ErrFileAccessDenied, err)
+	}
+	return nil
+}
@@ -3,12 +3,22 @@ package filedata
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
+	authzApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/authz"
+	itempolicyApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/itempolicy"
+	planApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/plan"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/authz"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/filedata"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/itemtag"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/db"
 	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/filedata"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/filefolder"
 	filestorage "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/filestorage"
+	tagRepository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/itemtag"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/middleware"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -39,9 +49,13 @@ func (m *MockRepository) Load(
 
 // MockFileStorageRepository implements FileStorageRepository interface for testing.
 type MockFileStorageRepository struct {
-	SaveFunc   func(ctx context.Context, params filestorage.SaveParams) error
-	LoadFunc   func(ctx context.Context, params filestorage.LoadParams) ([]byte, error)
-	DeleteFunc func(ctx context.Context, params filestorage.DeleteParams) error
+	SaveFunc       func(ctx context.Context, params filestorage.SaveParams) error
+	LoadFunc       func(ctx context.Context, params filestorage.LoadParams) ([]byte, error)
+	DeleteFunc     func(ctx context.Context, params filestorage.DeleteParams) error
+	NewFileKeyFunc func(ctx context.Context, params filestorage.FileKeyParams) ([]byte, error)
+	RewrapFunc     func(ctx context.Context, params filestorage.RewrapParams) ([]byte, error)
+	MoveFunc       func(ctx context.Context, params filestorage.MoveParams) error
+	UsageFunc      func(ctx context.Context, params filestorage.UsageParams) (int64, error)
 }
 
 func (m *MockFileStorageRepository) Save(ctx context.Context, params filestorage.SaveParams) error {
@@ -65,6 +79,118 @@ func (m *MockFileStorageRepository) Delete(ctx context.Context, params filestora
 	return nil
 }
 
+func (m *MockFileStorageRepository) NewFileKey(
+	ctx context.Context,
+	params filestorage.FileKeyParams,
+) ([]byte, error) {
+	if m.NewFileKeyFunc != nil {
+		return m.NewFileKeyFunc(ctx, params)
+	}
+	return []byte("wrapped-file-key"), nil
+}
+
+func (m *MockFileStorageRepository) Rewrap(ctx context.Context, params filestorage.RewrapParams) ([]byte, error) {
+	if m.RewrapFunc != nil {
+		return m.RewrapFunc(ctx, params)
+	}
+	return nil, nil
+}
+
+func (m *MockFileStorageRepository) Move(ctx context.Context, params filestorage.MoveParams) error {
+	if m.MoveFunc != nil {
+		return m.MoveFunc(ctx, params)
+	}
+	return nil
+}
+
+func (m *MockFileStorageRepository) Usage(ctx context.Context, params filestorage.UsageParams) (int64, error) {
+	if m.UsageFunc != nil {
+		return m.UsageFunc(ctx, params)
+	}
+	return 0, nil
+}
+
+// MockFolderRepository implements FolderRepository interface for testing.
+type MockFolderRepository struct {
+	SaveFunc func(ctx context.Context, params filefolder.SaveParams) error
+	LoadFunc func(ctx context.Context, params filefolder.LoadParams) ([]*filedata.Folder, error)
+}
+
+func (m *MockFolderRepository) Save(ctx context.Context, params filefolder.SaveParams) error {
+	if m.SaveFunc != nil {
+		return m.SaveFunc(ctx, params)
+	}
+	return nil
+}
+
+func (m *MockFolderRepository) Load(ctx context.Context, params filefolder.LoadParams) ([]*filedata.Folder, error) {
+	if m.LoadFunc != nil {
+		return m.LoadFunc(ctx, params)
+	}
+	return nil, nil
+}
+
+// directUnitOfWork runs units of work without a transaction.
+type directUnitOfWork struct{}
+
+func (directUnitOfWork) Do(ctx context.Context, _ uuid.UUID, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// ownerAuthorizer permits users to act on their own files only, like the default authorization policy.
+type ownerAuthorizer struct{}
+
+func (ownerAuthorizer) Authorize(_ context.Context, req authzApp.Request) error {
+	if req.OwnerID != req.SubjectID {
+		return authzApp.ErrAccessDenied
+	}
+	return nil
+}
+
+// denyAuthorizer denies every request and records the requests decided.
+type denyAuthorizer struct {
+	reqs []authzApp.Request
+}
+
+func (m *denyAuthorizer) Authorize(_ context.Context, req authzApp.Request) error {
+	m.reqs = append(m.reqs, req)
+	return authzApp.ErrAccessDenied
+}
+
+// mockPlanEnforcer implements PlanEnforcer for testing.
+type mockPlanEnforcer struct {
+	itemsErr error
+	maxSize  int64
+	added    int
+}
+
+func (m *mockPlanEnforcer) CheckItems(_ context.Context, _ uuid.UUID, added int) error {
+	m.added += added
+	return m.itemsErr
+}
+
+func (m *mockPlanEnforcer) CheckFileSize(_ context.Context, _ uuid.UUID, size int64) error {
+	if size > m.maxSize {
+		return planApp.ErrPlanFileTooLarge
+	}
+	return nil
+}
+
+func (m *mockPlanEnforcer) MaxFileSize(context.Context, uuid.UUID) (int64, error) {
+	return m.maxSize, nil
+}
+
+// mockPolicyEnforcer implements PolicyEnforcer for testing, recording the checked kinds.
+type mockPolicyEnforcer struct {
+	err   error
+	kinds []string
+}
+
+func (m *mockPolicyEnforcer) CheckItem(_ context.Context, _ uuid.UUID, kind string) error {
+	m.kinds = append(m.kinds, kind)
+	return m.err
+}
+
 func TestNewService(t *testing.T) {
 	t.Parallel()
 
@@ -96,7 +222,9 @@ func TestNewService(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			got := NewService(tt.repo, tt.fs)
+			got := NewService(
+				tt.repo, tt.fs, &MockFolderRepository{}, directUnitOfWork{}, ownerAuthorizer{}, 0, nil, nil, nil, nil, nil,
+			)
 			require.NotNil(t, got)
 			assert.Equal(t, tt.repo, got.r)
 			assert.Equal(t, tt.fs, got.fs)
@@ -235,7 +363,8 @@ func TestService_Pull(t *testing.T) {
 				tt.setupFSMock(mockFS)
 			}
 
-			service := NewService(mockRepo, mockFS)
+			service := NewService(mockRepo, mockFS, &MockFolderRepository{}, directUnitOfWork{}, ownerAuthorizer{}, 0, nil, nil,
+				nil, nil, nil)
 			got, err := service.Pull(context.Background(), tt.params)
 
 			if tt.wantErr {
@@ -353,7 +482,8 @@ func TestService_List(t *testing.T) {
 				tt.setupRepoMock(mockRepo)
 			}
 
-			service := NewService(mockRepo, mockFS)
+			service := NewService(mockRepo, mockFS, &MockFolderRepository{}, directUnitOfWork{}, ownerAuthorizer{}, 0, nil, nil,
+				nil, nil, nil)
 			got, err := service.List(context.Background(), tt.params)
 
 			if tt.wantErr {
@@ -406,6 +536,7 @@ func TestService_Push(t *testing.T) {
 					assert.Equal(t, testUserID, params.Entity.UserID)
 					assert.Equal(t, []byte("test/file.txt"), params.Entity.StorageKey)
 					assert.Equal(t, []byte("test description"), params.Entity.Description)
+					assert.Equal(t, []byte("wrapped-file-key"), params.Entity.FileKey)
 					return nil
 				}
 			},
@@ -414,11 +545,34 @@ func TestService_Push(t *testing.T) {
 					assert.Equal(t, testUserID, params.UserID)
 					assert.Equal(t, "test/file.txt", params.StorageKey)
 					assert.Equal(t, testData, params.Data)
+					assert.Equal(t, []byte("wrapped-file-key"), params.FileKey)
+					assert.NotEqual(t, uuid.Nil, params.FileID)
 					return nil
 				}
 			},
 			wantErr: false,
 		},
+		{
+			name: "error/file_key_error",
+			params: &PushParams{
+				UserID:      testUserID,
+				StorageKey:  "test/file.txt",
+				Description: "test description",
+				Data:        testData,
+			},
+			setupRepoMock: func(m *MockRepository) {},
+			setupFSMock: func(m *MockFileStorageRepository) {
+				m.NewFileKeyFunc = func(ctx context.Context, params filestorage.FileKeyParams) ([]byte, error) {
+					return nil, errors.New("key error")
+				}
+				m.SaveFunc = func(ctx context.Context, params filestorage.SaveParams) error {
+					t.Error("content must not be stored without a file key")
+					return nil
+				}
+			},
+			wantErr:     true,
+			wantErrText: "failed to create file key",
+		},
 		{
 			name: "error/empty_data",
 			params: &PushParams{
@@ -503,7 +657,8 @@ func TestService_Push(t *testing.T) {
 				tt.setupFSMock(mockFS)
 			}
 
-			service := NewService(mockRepo, mockFS)
+			service := NewService(mockRepo, mockFS, &MockFolderRepository{}, directUnitOfWork{}, ownerAuthorizer{}, 0, nil, nil,
+				nil, nil, nil)
 			gotID, err := service.Push(context.Background(), tt.params)
 
 			if tt.wantErr {
@@ -522,6 +677,433 @@ func TestService_Push(t *testing.T) {
 	}
 }
 
+func TestService_Push_Quota(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	existingID := uuid.New()
+	data := []byte("0123456789")
+
+	tests := []struct {
+		params      *PushParams
+		name        string
+		wantExclude string
+		used        int64
+		quota       int64
+		wantErr     bool
+	}{
+		{
+			name:        "within_quota",
+			params:      &PushParams{UserID: userID, StorageKey: "a.txt", Data: data},
+			used:        90,
+			quota:       100,
+			wantExclude: "a.txt",
+		},
+		{
+			name:        "exceeds_quota",
+			params:      &PushParams{UserID: userID, StorageKey: "a.txt", Data: data},
+			used:        91,
+			quota:       100,
+			wantExclude: "a.txt",
+			wantErr:     true,
+		},
+		{
+			name:        "update_excludes_replaced_content",
+			params:      &PushParams{ID: existingID, UserID: userID, StorageKey: "new.txt", Data: data},
+			used:        90,
+			quota:       100,
+			wantExclude: "old.txt",
+		},
+		{
+			name:   "no_quota",
+			params: &PushParams{UserID: userID, StorageKey: "a.txt", Data: data},
+			used:   1 << 40,
+		},
+		{
+			name:        "empty_file_allowed",
+			params:      &PushParams{UserID: userID, StorageKey: "a.txt", AllowEmpty: true},
+			used:        100,
+			quota:       100,
+			wantExclude: "a.txt",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &MockRepository{
+				LoadFunc: func(ctx context.Context, params repository.LoadParams) ([]*filedata.FileData, error) {
+					return []*filedata.FileData{{ID: existingID, UserID: userID, StorageKey: []byte("old.txt")}}, nil
+				},
+			}
+			// saved reports whether the content was stored.
+			var saved bool
+			fs := &MockFileStorageRepository{
+				UsageFunc: func(ctx context.Context, params filestorage.UsageParams) (int64, error) {
+					assert.Equal(t, userID, params.UserID)
+					assert.Equal(t, tt.wantExclude, params.Exclude)
+					return tt.used, nil
+				},
+				DeleteFunc: func(ctx context.Context, params filestorage.DeleteParams) error {
+					assert.False(t, tt.wantErr, "replaced content must not be removed when the quota is exceeded")
+					return nil
+				},
+				SaveFunc: func(ctx context.Context, params filestorage.SaveParams) error {
+					saved = true
+					return nil
+				},
+			}
+			s := NewService(
+				repo, fs, &MockFolderRepository{}, directUnitOfWork{}, ownerAuthorizer{}, tt.quota, nil, nil, nil, nil, nil,
+			)
+
+			_, err := s.Push(context.Background(), tt.params)
+
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrFileQuotaExceeded)
+				assert.False(t, saved)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, saved)
+		})
+	}
+}
+
+func TestService_Push_Plan(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	existingID := uuid.New()
+	data := []byte("0123456789")
+
+	tests := []struct {
+		plans     *mockPlanEnforcer
+		wantErr   error
+		params    *PushParams
+		name      string
+		wantAdded int
+	}{
+		{
+			name:      "new file within plan",
+			params:    &PushParams{UserID: userID, StorageKey: "a.txt", Data: data},
+			plans:     &mockPlanEnforcer{maxSize: 10},
+			wantAdded: 1,
+		},
+		{
+			name:    "file too large",
+			params:  &PushParams{UserID: userID, StorageKey: "a.txt", Data: data},
+			plans:   &mockPlanEnforcer{maxSize: 9},
+			wantErr: planApp.ErrPlanFileTooLarge,
+		},
+		{
+			name:      "item limit reached",
+			params:    &PushParams{UserID: userID, StorageKey: "a.txt", Data: data},
+			plans:     &mockPlanEnforcer{maxSize: 10, itemsErr: planApp.ErrPlanItemLimitReached},
+			wantErr:   planApp.ErrPlanUpgradeRequired,
+			wantAdded: 1,
+		},
+		{
+			name:   "update skips item limit",
+			params: &PushParams{ID: existingID, UserID: userID, StorageKey: "new.txt", Data: data},
+			plans:  &mockPlanEnforcer{maxSize: 10, itemsErr: planApp.ErrPlanItemLimitReached},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &MockRepository{
+				LoadFunc: func(ctx context.Context, params repository.LoadParams) ([]*filedata.FileData, error) {
+					return []*filedata.FileData{{ID: existingID, UserID: userID, StorageKey: []byte("old.txt")}}, nil
+				},
+			}
+			// saved reports whether the content was stored.
+			var saved bool
+			fs := &MockFileStorageRepository{
+				SaveFunc: func(ctx context.Context, params filestorage.SaveParams) error {
+					saved = true
+					return nil
+				},
+			}
+			s := NewService(
+				repo, fs, &MockFolderRepository{}, directUnitOfWork{}, ownerAuthorizer{}, 0, nil, tt.plans, nil, nil, nil,
+			)
+
+			_, err := s.Push(context.Background(), tt.params)
+
+			assert.Equal(t, tt.wantAdded, tt.plans.added)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.False(t, saved)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, saved)
+		})
+	}
+}
+
+func TestService_CheckUpload(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	existingID := uuid.New()
+
+	tests := []struct {
+		plans       *mockPlanEnforcer
+		wantErr     error
+		params      *PushParams
+		name        string
+		wantExclude string
+		size        int64
+		quota       int64
+		wantLimit   int64
+	}{
+		{
+			name:      "unbounded",
+			params:    &PushParams{UserID: userID, StorageKey: "a.txt"},
+			size:      1 << 40,
+			wantLimit: -1,
+		},
+		{
+			name:        "plan bounds the limit",
+			params:      &PushParams{UserID: userID, StorageKey: "a.txt"},
+			plans:       &mockPlanEnforcer{maxSize: 50},
+			quota:       200,
+			wantExclude: "a.txt",
+			wantLimit:   50,
+		},
+		{
+			name:        "free quota bounds the limit",
+			params:      &PushParams{UserID: userID, StorageKey: "a.txt"},
+			plans:       &mockPlanEnforcer{maxSize: 50},
+			quota:       100,
+			wantExclude: "a.txt",
+			size:        20,
+			wantLimit:   30,
+		},
+		{
+			name:        "update excludes replaced content",
+			params:      &PushParams{ID: existingID, UserID: userID, StorageKey: "old.txt"},
+			quota:       100,
+			wantExclude: "old.txt",
+			wantLimit:   30,
+		},
+		{
+			name:    "file too large",
+			params:  &PushParams{UserID: userID, StorageKey: "a.txt"},
+			plans:   &mockPlanEnforcer{maxSize: 50},
+			size:    51,
+			wantErr: planApp.ErrPlanFileTooLarge,
+		},
+		{
+			name:        "quota exceeded",
+			params:      &PushParams{UserID: userID, StorageKey: "a.txt"},
+			quota:       100,
+			wantExclude: "a.txt",
+			size:        31,
+			wantErr:     ErrFileQuotaExceeded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &MockRepository{
+				LoadFunc: func(ctx context.Context, params repository.LoadParams) ([]*filedata.FileData, error) {
+					return []*filedata.FileData{{ID: existingID, UserID: userID, StorageKey: []byte("old.txt")}}, nil
+				},
+			}
+			fs := &MockFileStorageRepository{
+				UsageFunc: func(ctx context.Context, params filestorage.UsageParams) (int64, error) {
+					assert.Equal(t, tt.wantExclude, params.Exclude)
+					return 70, nil
+				},
+			}
+			var plans PlanEnforcer
+			if tt.plans != nil {
+				plans = tt.plans
+			}
+			s := NewService(
+				repo, fs, &MockFolderRepository{}, directUnitOfWork{}, ownerAuthorizer{}, tt.quota, nil, plans, nil, nil, nil,
+			)
+
+			limit, err := s.CheckUpload(context.Background(), tt.params, tt.size)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantLimit, limit)
+		})
+	}
+}
+
+func TestService_Push_ItemPolicy(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	existingID := uuid.New()
+
+	tests := []struct {
+		policyErr error
+		params    *PushParams
+		name      string
+	}{
+		{
+			name:   "new file allowed",
+			params: &PushParams{UserID: userID, StorageKey: "a.txt", Data: []byte("data")},
+		},
+		{
+			name:      "new file forbidden",
+			params:    &PushParams{UserID: userID, StorageKey: "a.txt", Data: []byte("data")},
+			policyErr: itempolicyApp.ErrItemKindForbidden,
+		},
+		{
+			name:      "update forbidden",
+			params:    &PushParams{ID: existingID, UserID: userID, StorageKey: "new.txt", Data: []byte("data")},
+			policyErr: itempolicyApp.ErrItemKindForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &MockRepository{
+				LoadFunc: func(ctx context.Context, params repository.LoadParams) ([]*filedata.FileData, error) {
+					return []*filedata.FileData{{ID: existingID, UserID: userID, StorageKey: []byte("old.txt")}}, nil
+				},
+			}
+			// saved reports whether the content was stored.
+			var saved bool
+			fs := &MockFileStorageRepository{
+				SaveFunc: func(ctx context.Context, params filestorage.SaveParams) error {
+					saved = true
+					return nil
+				},
+			}
+			policies := &mockPolicyEnforcer{err: tt.policyErr}
+			s := NewService(
+				repo, fs, &MockFolderRepository{}, directUnitOfWork{}, ownerAuthorizer{}, 0, nil, nil, policies, nil, nil,
+			)
+
+			_, err := s.Push(context.Background(), tt.params)
+
+			assert.Equal(t, []string{authz.KindFile}, policies.kinds)
+			if tt.policyErr != nil {
+				require.ErrorIs(t, err, tt.policyErr)
+				assert.False(t, saved)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, saved)
+		})
+	}
+}
+
+func TestService_RewrapKeys(t *testing.T) {
+	t.Parallel()
+
+	testUserID := uuid.New()
+	keyed := &filedata.FileData{ID: uuid.New(), UserID: testUserID, FileKey: []byte("old-wrapped")}
+	legacy := &filedata.FileData{ID: uuid.New(), UserID: testUserID}
+
+	tests := []struct {
+		setupRepoMock func(*MockRepository, *[]*filedata.FileData)
+		setupFSMock   func(*MockFileStorageRepository)
+		name          string
+		wantErrText   string
+		wantCount     int
+		wantErr       bool
+	}{
+		{
+			name: "success/rewraps_keyed_files_only",
+			setupRepoMock: func(m *MockRepository, saved *[]*filedata.FileData) {
+				m.LoadFunc = func(ctx context.Context, params repository.LoadParams) ([]*filedata.FileData, error) {
+					k, l := *keyed, *legacy
+					return []*filedata.FileData{&k, &l}, nil
+				}
+				m.SaveFunc = func(ctx context.Context, params repository.SaveParams) error {
+					*saved = append(*saved, params.Entity)
+					return nil
+				}
+			},
+			setupFSMock: func(m *MockFileStorageRepository) {
+				m.RewrapFunc = func(ctx context.Context, params filestorage.RewrapParams) ([]byte, error) {
+					assert.Equal(t, []byte("old-wrapped"), params.FileKey)
+					assert.Equal(t, []byte("old-user-key"), params.OldUserKey)
+					assert.Equal(t, keyed.ID, params.FileID)
+					return []byte("new-wrapped"), nil
+				}
+			},
+			wantCount: 1,
+		},
+		{
+			name: "error/load_error",
+			setupRepoMock: func(m *MockRepository, _ *[]*filedata.FileData) {
+				m.LoadFunc = func(ctx context.Context, params repository.LoadParams) ([]*filedata.FileData, error) {
+					return nil, errors.New("db error")
+				}
+			},
+			setupFSMock: func(m *MockFileStorageRepository) {},
+			wantErr:     true,
+			wantErrText: "failed to load files",
+		},
+		{
+			name: "error/rewrap_error",
+			setupRepoMock: func(m *MockRepository, _ *[]*filedata.FileData) {
+				m.LoadFunc = func(ctx context.Context, params repository.LoadParams) ([]*filedata.FileData, error) {
+					k := *keyed
+					return []*filedata.FileData{&k}, nil
+				}
+			},
+			setupFSMock: func(m *MockFileStorageRepository) {
+				m.RewrapFunc = func(ctx context.Context, params filestorage.RewrapParams) ([]byte, error) {
+					return nil, errors.New("wrong key")
+				}
+			},
+			wantErr:     true,
+			wantErrText: "failed to rewrap key of file",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// saved collects the file metadata written back by the service.
+			var saved []*filedata.FileData
+			mockRepo := &MockRepository{}
+			mockFS := &MockFileStorageRepository{}
+			tt.setupRepoMock(mockRepo, &saved)
+			tt.setupFSMock(mockFS)
+
+			service := NewService(mockRepo, mockFS, &MockFolderRepository{}, directUnitOfWork{}, ownerAuthorizer{}, 0, nil, nil,
+				nil, nil, nil)
+			n, err := service.RewrapKeys(context.Background(), RewrapKeysParams{
+				UserID:     testUserID,
+				OldUserKey: []byte("old-user-key"),
+			})
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErrText)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantCount, n)
+			require.Len(t, saved, tt.wantCount)
+			assert.Equal(t, []byte("new-wrapped"), saved[0].FileKey)
+		})
+	}
+}
+
 func TestService_loadMetadata(t *testing.T) {
 	t.Parallel()
 
@@ -611,7 +1193,8 @@ func TestService_loadMetadata(t *testing.T) {
 				tt.setupRepoMock(mockRepo)
 			}
 
-			service := NewService(mockRepo, mockFS)
+			service := NewService(mockRepo, mockFS, &MockFolderRepository{}, directUnitOfWork{}, ownerAuthorizer{}, 0, nil, nil,
+				nil, nil, nil)
 			got, err := service.loadMetadata(context.Background(), tt.params)
 
 			if tt.wantErr {
@@ -718,7 +1301,19 @@ func TestService_findFileForUpdate(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			service := NewService(tt.mockRepo, &MockFileStorageRepository{})
+			service := NewService(
+				tt.mockRepo,
+				&MockFileStorageRepository{},
+				&MockFolderRepository{},
+				directUnitOfWork{},
+				ownerAuthorizer{},
+				0,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			)
 			got, err := service.findFileForUpdate(context.Background(), tt.params)
 
 			if tt.wantErr {
@@ -795,7 +1390,19 @@ func TestService_removeOldFileOnKeyChange(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			service := NewService(&MockRepository{}, tt.mockFS)
+			service := NewService(
+				&MockRepository{},
+				tt.mockFS,
+				&MockFolderRepository{},
+				directUnitOfWork{},
+				ownerAuthorizer{},
+				0,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			)
 			err := service.removeOldFileOnKeyChange(context.Background(), tt.existing, tt.newStorageKey)
 
 			if tt.wantErr {
@@ -808,6 +1415,262 @@ func TestService_removeOldFileOnKeyChange(t *testing.T) {
 	}
 }
 
+// txClient runs units of work as transactions that do nothing themselves.
+type txClient struct {
+	db.DBClient
+}
+
+func (txClient) RunInTx(ctx context.Context, _ uuid.UUID, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func TestService_Push_UnitOfWork(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	fileID := uuid.New()
+	laterErr := errors.New("later step failed")
+
+	// blob is a stored file content with the file key it is encrypted with.
+	type blob struct {
+		data    string
+		fileKey string
+	}
+
+	tests := []struct {
+		run        func(*db.UnitOfWork, context.Context, uuid.UUID, func(context.Context) error) error
+		laterErr   error
+		wantStored map[string]blob
+		name       string
+		storageKey string
+	}{
+		{
+			name:       "commit removes old content",
+			run:        (*db.UnitOfWork).Do,
+			storageKey: "new_key",
+			wantStored: map[string]blob{"new_key": {data: "new content", fileKey: "new_file_key"}},
+		},
+		{
+			name:       "rollback after push keeps old content",
+			run:        (*db.UnitOfWork).Do,
+			laterErr:   laterErr,
+			storageKey: "new_key",
+			wantStored: map[string]blob{"old_key": {data: "old content", fileKey: "old_file_key"}},
+		},
+		{
+			name:       "dry run keeps old content",
+			run:        (*db.UnitOfWork).DryRun,
+			storageKey: "new_key",
+			wantStored: map[string]blob{"old_key": {data: "old content", fileKey: "old_file_key"}},
+		},
+		{
+			name:       "commit replaces content under same key",
+			run:        (*db.UnitOfWork).Do,
+			storageKey: "old_key",
+			wantStored: map[string]blob{"old_key": {data: "new content", fileKey: "old_file_key"}},
+		},
+		{
+			name:       "rollback keeps content under same key",
+			run:        (*db.UnitOfWork).Do,
+			laterErr:   laterErr,
+			storageKey: "old_key",
+			wantStored: map[string]blob{"old_key": {data: "old content", fileKey: "old_file_key"}},
+		},
+		{
+			name:       "dry run keeps content under same key",
+			run:        (*db.UnitOfWork).DryRun,
+			storageKey: "old_key",
+			wantStored: map[string]blob{"old_key": {data: "old content", fileKey: "old_file_key"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// stored holds the stored file contents by storage key.
+			stored := map[string]blob{"old_key": {data: "old content", fileKey: "old_file_key"}}
+			fs := &MockFileStorageRepository{
+				NewFileKeyFunc: func(context.Context, filestorage.FileKeyParams) ([]byte, error) {
+					return []byte("new_file_key"), nil
+				},
+				SaveFunc: func(_ context.Context, params filestorage.SaveParams) error {
+					stored[params.StorageKey] = blob{data: string(params.Data), fileKey: string(params.FileKey)}
+					return nil
+				},
+				DeleteFunc: func(_ context.Context, params filestorage.DeleteParams) error {
+					delete(stored, params.StorageKey)
+					return nil
+				},
+			}
+			repo := &MockRepository{
+				LoadFunc: func(context.Context, repository.LoadParams) ([]*filedata.FileData, error) {
+					return []*filedata.FileData{{
+						ID:         fileID,
+						UserID:     userID,
+						StorageKey: []byte("old_key"),
+						FileKey:    []byte("old_file_key"),
+					}}, nil
+				},
+			}
+			uow := db.NewUnitOfWork(txClient{}, middleware.RetryPolicy{})
+			service := NewService(repo, fs, &MockFolderRepository{}, uow, ownerAuthorizer{}, 0,
+				nil, nil, nil, nil, nil)
+
+			err := tt.run(uow, context.Background(), userID, func(ctx context.Context) error {
+				_, err := service.Push(ctx, &PushParams{
+					ID:         fileID,
+					UserID:     userID,
+					StorageKey: tt.storageKey,
+					Data:       []byte("new content"),
+				})
+				require.NoError(t, err)
+				return tt.laterErr
+			})
+
+			require.ErrorIs(t, err, tt.laterErr)
+			assert.Equal(t, tt.wantStored, stored)
+		})
+	}
+}
+
+func TestService_Push_DryRunKeepsFileReadable(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	fileID := uuid.New()
+
+	for _, storageKey := range []string{"old_key", "new_key"} {
+		t.Run(storageKey, func(t *testing.T) {
+			t.Parallel()
+
+			// stored holds the stored file contents by storage key, prefixed with their file key.
+			stored := map[string]string{"old_key": "old_file_key:old content"}
+			fs := &MockFileStorageRepository{
+				NewFileKeyFunc: func(context.Context, filestorage.FileKeyParams) ([]byte, error) {
+					return []byte("new_file_key"), nil
+				},
+				SaveFunc: func(_ context.Context, params filestorage.SaveParams) error {
+					stored[params.StorageKey] = string(params.FileKey) + ":" + string(params.Data)
+					return nil
+				},
+				LoadFunc: func(_ context.Context, params filestorage.LoadParams) ([]byte, error) {
+					data, ok := strings.CutPrefix(stored[params.StorageKey], string(params.FileKey)+":")
+					if !ok {
+						return nil, errors.New("decryption failed")
+					}
+					return []byte(data), nil
+				},
+				DeleteFunc: func(_ context.Context, params filestorage.DeleteParams) error {
+					delete(stored, params.StorageKey)
+					return nil
+				},
+			}
+			repo := &MockRepository{
+				LoadFunc: func(context.Context, repository.LoadParams) ([]*filedata.FileData, error) {
+					return []*filedata.FileData{{
+						ID:         fileID,
+						UserID:     userID,
+						StorageKey: []byte("old_key"),
+						FileKey:    []byte("old_file_key"),
+					}}, nil
+				},
+			}
+			uow := db.NewUnitOfWork(txClient{}, middleware.RetryPolicy{})
+			service := NewService(repo, fs, &MockFolderRepository{}, uow, ownerAuthorizer{}, 0,
+				nil, nil, nil, nil, nil)
+
+			err := uow.DryRun(context.Background(), userID, func(ctx context.Context) error {
+				_, err := service.Push(ctx, &PushParams{
+					ID:         fileID,
+					UserID:     userID,
+					StorageKey: storageKey,
+					Data:       []byte("new content"),
+				})
+				return err
+			})
+			require.NoError(t, err)
+
+			got, err := service.Pull(context.Background(), PullParams{ID: fileID, UserID: userID})
+			require.NoError(t, err)
+			assert.Equal(t, []byte("old content"), got.Data)
+		})
+	}
+}
+
+func TestService_Push_ReplaceFailureRestoresMetadata(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	fileID := uuid.New()
+	writeErr := errors.New("disk full")
+
+	tests := []struct {
+		run  func(uow *db.UnitOfWork, fn func(ctx context.Context) error) error
+		name string
+	}{
+		{
+			name: "unit of work",
+			run: func(uow *db.UnitOfWork, fn func(ctx context.Context) error) error {
+				return uow.Do(context.Background(), userID, fn)
+			},
+		},
+		{
+			name: "no unit of work",
+			run: func(_ *db.UnitOfWork, fn func(ctx context.Context) error) error {
+				return fn(context.Background())
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			existing := &filedata.FileData{
+				ID:         fileID,
+				UserID:     userID,
+				StorageKey: []byte("old_key"),
+				FileKey:    []byte("old_file_key"),
+				HashSum:    []byte("old_hash"),
+				Size:       int64(len("old content")),
+			}
+			// saved holds the metadata saved by the service in order.
+			var saved []filedata.FileData
+			repo := &MockRepository{
+				LoadFunc: func(context.Context, repository.LoadParams) ([]*filedata.FileData, error) {
+					return []*filedata.FileData{existing}, nil
+				},
+				SaveFunc: func(_ context.Context, params repository.SaveParams) error {
+					saved = append(saved, *params.Entity)
+					return nil
+				},
+			}
+			fs := &MockFileStorageRepository{
+				SaveFunc: func(context.Context, filestorage.SaveParams) error { return writeErr },
+			}
+			uow := db.NewUnitOfWork(txClient{}, middleware.RetryPolicy{})
+			service := NewService(repo, fs, &MockFolderRepository{}, uow, ownerAuthorizer{}, 0,
+				nil, nil, nil, nil, nil)
+
+			err := tt.run(uow, func(ctx context.Context) error {
+				_, err := service.Push(ctx, &PushParams{
+					ID:         fileID,
+					UserID:     userID,
+					StorageKey: "old_key",
+					Data:       []byte("new content"),
+				})
+				return err
+			})
+
+			require.ErrorIs(t, err, writeErr)
+			require.Len(t, saved, 2)
+			assert.Equal(t, int64(len("new content")), saved[0].Size)
+			assert.Equal(t, *existing, saved[1], "the metadata is restored to describe the stored content")
+		})
+	}
+}
+
 func TestService_rollbackFileSave(t *testing.T) {
 	t.Parallel()
 
@@ -857,7 +1720,19 @@ func TestService_rollbackFileSave(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			service := NewService(&MockRepository{}, tt.mockFS)
+			service := NewService(
+				&MockRepository{},
+				tt.mockFS,
+				&MockFolderRepository{},
+				directUnitOfWork{},
+				ownerAuthorizer{},
+				0,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			)
 			err := service.rollbackFileSave(context.Background(), tt.fileData)
 
 			if tt.wantErr {
@@ -869,3 +1744,196 @@ func TestService_rollbackFileSave(t *testing.T) {
 		})
 	}
 }
+
+func TestService_Authorization(t *testing.T) {
+	t.Parallel()
+
+	userID, fileID := uuid.New(), uuid.New()
+	request := func(action string, resourceID uuid.UUID) authzApp.Request {
+		return authzApp.Request{
+			Action:     action,
+			Kind:       authz.KindFile,
+			ResourceID: resourceID,
+			OwnerID:    userID,
+			SubjectID:  userID,
+		}
+	}
+
+	tests := []struct {
+		call    func(s *Service) error
+		name    string
+		wantReq authzApp.Request
+	}{
+		{
+			name: "pull",
+			call: func(s *Service) error {
+				_, err := s.Pull(context.Background(), PullParams{ID: fileID, UserID: userID})
+				return err
+			},
+			wantReq: request(authz.ActionRead, fileID),
+		},
+		{
+			name: "list",
+			call: func(s *Service) error {
+				_, err := s.List(context.Background(), ListParams{UserID: userID})
+				return err
+			},
+			wantReq: request(authz.ActionRead, uuid.Nil),
+		},
+		{
+			name: "create",
+			call: func(s *Service) error {
+				_, err := s.Push(context.Background(), &PushParams{
+					StorageKey: "file.txt",
+					Data:       []byte("data"),
+					UserID:     userID,
+				})
+				return err
+			},
+			wantReq: request(authz.ActionWrite, uuid.Nil),
+		},
+		{
+			name: "update",
+			call: func(s *Service) error {
+				_, err := s.Push(context.Background(), &PushParams{
+					StorageKey: "file.txt",
+					Data:       []byte("data"),
+					ID:         fileID,
+					UserID:     userID,
+				})
+				return err
+			},
+			wantReq: request(authz.ActionWrite, fileID),
+		},
+		{
+			name: "move file",
+			call: func(s *Service) error {
+				_, err := s.MoveFile(context.Background(), MoveFileParams{ID: fileID, UserID: userID})
+				return err
+			},
+			wantReq: request(authz.ActionWrite, fileID),
+		},
+		{
+			name: "list folder",
+			call: func(s *Service) error {
+				_, err := s.ListFolder(context.Background(), ListFolderParams{UserID: userID})
+				return err
+			},
+			wantReq: request(authz.ActionRead, uuid.Nil),
+		},
+		{
+			name: "create folder",
+			call: func(s *Service) error {
+				_, err := s.CreateFolder(context.Background(), CreateFolderParams{Path: "docs", UserID: userID})
+				return err
+			},
+			wantReq: request(authz.ActionWrite, uuid.Nil),
+		},
+		{
+			name: "move folder",
+			call: func(s *Service) error {
+				_, err := s.MoveFolder(context.Background(), MoveFolderParams{ID: uuid.New(), Path: "docs", UserID: userID})
+				return err
+			},
+			wantReq: request(authz.ActionWrite, uuid.Nil),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &MockRepository{
+				LoadFunc: func(context.Context, repository.LoadParams) ([]*filedata.FileData, error) {
+					return []*filedata.FileData{{ID: fileID, UserID: userID, StorageKey: []byte("file.txt")}}, nil
+				},
+			}
+			fs := &MockFileStorageRepository{}
+			authorizer := &denyAuthorizer{}
+
+			err := tt.call(NewService(
+				repo, fs, &MockFolderRepository{}, directUnitOfWork{}, authorizer, 0, nil, nil, nil, nil, nil,
+			))
+
+			require.ErrorIs(t, err, ErrFileAccessDenied)
+			require.ErrorIs(t, err, authzApp.ErrAccessDenied)
+			assert.Equal(t, []authzApp.Request{tt.wantReq}, authorizer.reqs)
+		})
+	}
+}
+
+// mockTagRepository implements TagRepository for testing.
+type mockTagRepository struct {
+	err  error
+	tags []*itemtag.ItemTags
+}
+
+func (m *mockTagRepository) Load(_ context.Context, params tagRepository.LoadParams) ([]*itemtag.ItemTags, error) {
+	if params.Tag != itemtag.Archived {
+		return nil, errors.New("unexpected tag")
+	}
+	return m.tags, m.err
+}
+
+func TestService_ListArchived(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	activeID, archivedID := uuid.New(), uuid.New()
+	archived := []*itemtag.ItemTags{{ItemID: archivedID, UserID: userID, Tags: []string{itemtag.Archived}}}
+
+	tests := []struct {
+		tags    TagRepository
+		wantErr error
+		want    map[uuid.UUID]bool
+		name    string
+		exclude bool
+	}{
+		{
+			name: "archived items marked",
+			tags: &mockTagRepository{tags: archived},
+			want: map[uuid.UUID]bool{activeID: false, archivedID: true},
+		},
+		{
+			name:    "archived items excluded",
+			tags:    &mockTagRepository{tags: archived},
+			exclude: true,
+			want:    map[uuid.UUID]bool{activeID: false},
+		},
+		{
+			name:    "nothing archived without tags",
+			exclude: true,
+			want:    map[uuid.UUID]bool{activeID: false, archivedID: false},
+		},
+		{
+			name:    "tag lookup error",
+			tags:    &mockTagRepository{err: errors.New("connection refused")},
+			wantErr: ErrFileTechError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &MockRepository{LoadFunc: func(context.Context, repository.LoadParams) ([]*filedata.FileData, error) {
+				return []*filedata.FileData{{ID: activeID, UserID: userID}, {ID: archivedID, UserID: userID}}, nil
+			}}
+			files, folders := &MockFileStorageRepository{}, &MockFolderRepository{}
+			service := NewService(repo, files, folders, directUnitOfWork{}, ownerAuthorizer{}, 0, nil, nil, nil, tt.tags, nil)
+
+			got, err := service.List(context.Background(), ListParams{UserID: userID, ExcludeArchived: tt.exclude})
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			listed := make(map[uuid.UUID]bool, len(got))
+			for _, item := range got {
+				listed[item.ID] = item.Archived
+			}
+			assert.Equal(t, tt.want, listed)
+		})
+	}
+}
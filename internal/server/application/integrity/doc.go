@@ -0,0 +1,5 @@
+// Package integrity provides stored data integrity verification application services for the AegisVaultKeeper server.
+//
+// This package implements the periodic verification of row signatures and reports
+// tampered rows through metrics and audit events.
+package integrity
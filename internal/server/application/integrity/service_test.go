@@ -0,0 +1,125 @@
+package integrity
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/audit"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/rowsign"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockVerifier implements Verifier for testing.
+type mockVerifier struct {
+	err     error
+	reports []rowsign.Report
+}
+
+func (m *mockVerifier) Verify(ctx context.Context) ([]rowsign.Report, error) {
+	return m.reports, m.err
+}
+
+// mockAuditRecorder implements AuditRecorder for testing.
+type mockAuditRecorder struct {
+	events []audit.Event
+}
+
+func (m *mockAuditRecorder) Record(ctx context.Context, e audit.Event) {
+	m.events = append(m.events, e)
+}
+
+// mockMetrics implements MetricsRecorder for testing.
+type mockMetrics struct {
+	values map[string]int64
+}
+
+func (m *mockMetrics) Add(name string, delta int64) { m.values[name] += delta }
+func (m *mockMetrics) Set(name string, value int64) { m.values[name] = value }
+
+func TestService_RunVerification(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	rowID := uuid.New()
+	verifyErr := errors.New("db down")
+
+	tests := []struct {
+		verifier    *mockVerifier
+		wantMetrics map[string]int64
+		name        string
+		wantChecked int
+		wantEvents  int
+		expectError bool
+	}{
+		{
+			name: "clean_tables",
+			verifier: &mockVerifier{reports: []rowsign.Report{
+				{Table: "notes", Checked: 3},
+				{Table: "files", Checked: 2},
+			}},
+			wantChecked: 5,
+			wantMetrics: map[string]int64{
+				MetricRuns:            1,
+				MetricRowsChecked:     5,
+				MetricRowsTampered:    0,
+				MetricLastRunTampered: 0,
+			},
+		},
+		{
+			name: "tampered_row_reported",
+			verifier: &mockVerifier{reports: []rowsign.Report{
+				{
+					Table:      "notes",
+					Checked:    2,
+					Violations: []rowsign.Violation{{Table: "notes", ID: rowID, UserID: userID}},
+				},
+			}},
+			wantChecked: 2,
+			wantEvents:  1,
+			wantMetrics: map[string]int64{
+				MetricRuns:            1,
+				MetricRowsChecked:     2,
+				MetricRowsTampered:    1,
+				MetricLastRunTampered: 1,
+			},
+		},
+		{
+			name:        "verifier_error",
+			verifier:    &mockVerifier{err: verifyErr},
+			expectError: true,
+			wantMetrics: map[string]int64{MetricRunFailures: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			auditRecorder := &mockAuditRecorder{}
+			metrics := &mockMetrics{values: map[string]int64{}}
+			s := NewService(tt.verifier, auditRecorder, metrics)
+
+			report, err := s.RunVerification(context.Background())
+			assert.Equal(t, tt.wantMetrics, metrics.values)
+
+			if tt.expectError {
+				require.ErrorIs(t, err, verifyErr)
+				assert.Nil(t, report)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantChecked, report.Checked)
+			require.Len(t, auditRecorder.events, tt.wantEvents)
+			for _, e := range auditRecorder.events {
+				assert.Equal(t, audit.EventRowTampered, e.Type)
+				assert.Equal(t, userID, e.UserID)
+				assert.Equal(t, rowID.String(), e.Details["row_id"])
+				assert.Equal(t, "notes", e.Details["table"])
+			}
+		})
+	}
+}
@@ -0,0 +1,5 @@
+// Package invite provides invite code application services for the AegisVaultKeeper server.
+//
+// This package manages the invite codes administrators and users with an invite quota hand out, and
+// redeems them when new users register, so deployments can restrict signups to invited people.
+package invite
@@ -0,0 +1,112 @@
+package invite
+
+import (
+	"slices"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/invite"
+	"github.com/google/uuid"
+)
+
+// Invite represents an invite data transfer object for application layer communication.
+type Invite struct {
+	// CreatedAt indicates when the invite was created.
+	CreatedAt time.Time
+	// ExpiresAt indicates when the invite can no longer be redeemed.
+	ExpiresAt time.Time
+	// RedeemedAt indicates when the invite was redeemed; zero if it was not.
+	RedeemedAt time.Time
+	// Note describes whom the invite is meant for.
+	Note string
+	// Code contains the invite code; it is only returned once, when the invite is created.
+	Code string
+	// Status names the state of the invite: pending, redeemed, revoked or expired.
+	Status string
+	// Groups lists the groups the new user is added to.
+	Groups []uuid.UUID
+	// InviteQuota specifies how many invites the new user may create.
+	InviteQuota int
+	// ID uniquely identifies the invite.
+	ID uuid.UUID
+	// CreatedBy identifies the user who created the invite; uuid.Nil for administrators.
+	CreatedBy uuid.UUID
+	// RedeemedBy identifies the user registered with the invite; uuid.Nil if it was not redeemed.
+	RedeemedBy uuid.UUID
+}
+
+// newInviteFromDomain converts a domain invite entity to application DTO without its code,
+// reporting its state at the specified time.
+func newInviteFromDomain(i *invite.Invite, at time.Time) *Invite {
+	if i == nil {
+		return nil
+	}
+	return &Invite{
+		ID:          i.ID,
+		Note:        i.Note,
+		Status:      string(i.Status(at)),
+		Groups:      slices.Clone(i.Groups),
+		InviteQuota: i.InviteQuota,
+		CreatedBy:   i.CreatedBy,
+		RedeemedBy:  i.RedeemedBy,
+		CreatedAt:   i.CreatedAt,
+		ExpiresAt:   i.ExpiresAt,
+		RedeemedAt:  i.RedeemedAt,
+	}
+}
+
+// newInvitesFromDomain converts a slice of domain invite entities to application DTOs.
+func newInvitesFromDomain(is []*invite.Invite, at time.Time) []*Invite {
+	result := make([]*Invite, 0, len(is))
+	for _, i := range is {
+		result = append(result, newInviteFromDomain(i, at))
+	}
+	return result
+}
+
+// Quota represents how many invites a user may create and how many count against the limit.
+type Quota struct {
+	// Limit specifies how many pending and redeemed invites the user may hold.
+	Limit int
+	// Used counts the pending and redeemed invites of the user.
+	Used int
+}
+
+// CreateParams contains parameters for creating an invite.
+type CreateParams struct {
+	// ExpiresAt specifies when the invite can no longer be redeemed; zero applies the configured lifetime.
+	ExpiresAt time.Time
+	// Note describes whom the invite is meant for.
+	Note string
+	// Groups lists the groups the new user is added to; administrators only.
+	Groups []uuid.UUID
+	// InviteQuota specifies how many invites the new user may create; administrators only.
+	InviteQuota int
+	// CreatedBy identifies the user creating the invite; uuid.Nil for administrators.
+	CreatedBy uuid.UUID
+}
+
+// ListParams contains parameters for listing invites.
+type ListParams struct {
+	// CreatedBy identifies the user whose invites are listed; uuid.Nil lists every invite.
+	CreatedBy uuid.UUID
+}
+
+// RevokeParams contains parameters for revoking an invite.
+type RevokeParams struct {
+	// ID identifies the invite to revoke.
+	ID uuid.UUID
+	// CreatedBy identifies the user who created the invite; uuid.Nil revokes any invite.
+	CreatedBy uuid.UUID
+}
+
+// QuotaParams contains parameters for reporting the invite quota of a user.
+type QuotaParams struct {
+	// UserID identifies the user.
+	UserID uuid.UUID
+}
+
+// RedeemParams contains parameters for registering a user with an invite.
+type RedeemParams struct {
+	// Code contains the presented invite code; empty registers without an invite where permitted.
+	Code string
+}
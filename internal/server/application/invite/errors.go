@@ -0,0 +1,84 @@
+package invite
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/errutil"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/invite"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/invite"
+)
+
+// Invite error definitions.
+var (
+	// ErrInviteAppError indicates a general invite application error.
+	ErrInviteAppError = errors.New("invite application error")
+
+	// ErrInviteTechError indicates a technical error in the invite system.
+	ErrInviteTechError = errors.New("invite technical error")
+
+	// ErrInviteRequired indicates that registration requires an invite code.
+	ErrInviteRequired = errors.New("invite code required")
+
+	// ErrInviteInvalid indicates that the invite code is unknown, was already redeemed, was revoked or
+	// has expired; the cases are not told apart so codes cannot be probed.
+	ErrInviteInvalid = errors.New("invalid invite code")
+
+	// ErrInviteNotFound indicates the requested invite was not found.
+	ErrInviteNotFound = errors.New("invite not found")
+
+	// ErrInviteQuotaExceeded indicates that the user has no invites left to create.
+	ErrInviteQuotaExceeded = errors.New("invite quota exceeded")
+
+	// ErrInvitePresetsForbidden indicates that only administrators may preset groups or invite quotas.
+	ErrInvitePresetsForbidden = errors.New("invite presets are reserved to administrators")
+
+	// ErrInviteGroupNotFound indicates that an invite presets a group that does not exist.
+	ErrInviteGroupNotFound = errors.New("invite group not found")
+
+	// ErrInviteIncorrectNote indicates an incorrect invite note was provided.
+	ErrInviteIncorrectNote = errors.New("incorrect invite note")
+
+	// ErrInviteIncorrectExpiry indicates an invite expiry in the past or too far in the future.
+	ErrInviteIncorrectExpiry = errors.New("incorrect invite expiry")
+
+	// ErrInviteIncorrectPresets indicates too many preset groups or too large a preset invite quota.
+	ErrInviteIncorrectPresets = errors.New("incorrect invite presets")
+
+	// ErrInviteRedeemed indicates that a redeemed invite cannot be revoked.
+	ErrInviteRedeemed = errors.New("invite already redeemed")
+)
+
+// mapError maps domain and repository errors to application-level errors.
+func mapError(err error) error {
+	if err == nil {
+		return nil
+	}
+	mapped := errutil.MapError(mapFn, err)
+	if mapped != nil {
+		return fmt.Errorf("invite error mapping failed: %w", mapped)
+	}
+	return nil
+}
+
+// mapFn provides the actual error mapping logic for different error types.
+func mapFn(err error) error {
+	switch {
+	case errors.Is(err, invite.ErrNewInviteParamsValidation):
+		return ErrInviteAppError
+	case errors.Is(err, invite.ErrIncorrectNote):
+		return ErrInviteIncorrectNote
+	case errors.Is(err, invite.ErrIncorrectExpiry):
+		return ErrInviteIncorrectExpiry
+	case errors.Is(err, invite.ErrIncorrectPresets):
+		return ErrInviteIncorrectPresets
+	case errors.Is(err, invite.ErrInviteRedeemed):
+		return ErrInviteRedeemed
+	case errors.Is(err, invite.ErrInviteUnusable):
+		return ErrInviteInvalid
+	case errors.Is(err, repository.ErrInviteNotFound):
+		return ErrInviteNotFound
+	default:
+		return errors.Join(ErrInviteTechError, err)
+	}
+}
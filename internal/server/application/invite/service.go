@@ -0,0 +1,312 @@
+package invite
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/audit"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/group"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/invite"
+	groupRepo "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/group"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/invite"
+	"github.com/google/uuid"
+)
+
+// defaultTTL specifies how long invites last when no lifetime is configured.
+const defaultTTL = 7 * 24 * time.Hour
+
+// codePrefix marks invite codes, so secret scanners can recognize leaked ones.
+const codePrefix = "avki_"
+
+// Repository defines the interface for invite persistence operations.
+type Repository interface {
+	// Save persists an invite using the provided parameters.
+	Save(ctx context.Context, params repository.SaveParams) error
+	// Load retrieves invites using the provided parameters.
+	Load(ctx context.Context, params repository.LoadParams) ([]*invite.Invite, error)
+	// Redeem marks a pending invite as redeemed using the provided parameters.
+	Redeem(ctx context.Context, params repository.RedeemParams) error
+}
+
+// GroupRepository defines the interface for the groups invites add new users to.
+type GroupRepository interface {
+	// Load retrieves groups using the provided parameters along with the total number of matches.
+	Load(ctx context.Context, params groupRepo.LoadParams) ([]*group.Group, int, error)
+	// AddMembers adds users to a group using the provided parameters.
+	AddMembers(ctx context.Context, params groupRepo.MembersParams) error
+}
+
+// UnitOfWork defines the interface for running repository operations in a single transaction.
+type UnitOfWork interface {
+	// Do executes fn in a single transaction scoped to the user, rolling back every write on error.
+	Do(ctx context.Context, userID uuid.UUID, fn func(ctx context.Context) error) error
+}
+
+// AuditRecorder defines the interface for recording security audit events.
+type AuditRecorder interface {
+	// Record stores the audit event.
+	Record(ctx context.Context, e audit.Event)
+}
+
+// Service provides invite code management and redemption.
+type Service struct {
+	// r is the repository interface for invite persistence operations.
+	r Repository
+	// groups adds new users to the preset groups.
+	groups GroupRepository
+	// uow makes registration and redemption atomic.
+	uow UnitOfWork
+	// audit records invite changes and redemptions.
+	audit AuditRecorder
+	// now returns the current time.
+	now func() time.Time
+	// ttl specifies how long invites last unless their creator sets an expiry.
+	ttl time.Duration
+	// userQuota specifies how many invites users may create by default.
+	userQuota int
+	// required rejects registrations without an invite code.
+	required bool
+}
+
+// NewService creates a new invite service instance. Registration requires an invite code when required is
+// set. Invites last for ttl unless their creator sets an expiry, or a week when ttl is not positive; users
+// may create userQuota invites unless the invite they registered with grants more.
+func NewService(
+	r Repository,
+	groups GroupRepository,
+	uow UnitOfWork,
+	audit AuditRecorder,
+	required bool,
+	ttl time.Duration,
+	userQuota int,
+) *Service {
+	if ttl <= 0 {
+		ttl = defaultTTL
+	}
+	return &Service{
+		r:         r,
+		groups:    groups,
+		uow:       uow,
+		audit:     audit,
+		now:       time.Now,
+		ttl:       ttl,
+		userQuota: max(userQuota, 0),
+		required:  required,
+	}
+}
+
+// Create issues a new invite with a random code. Administrators may preset the groups and the invite quota
+// of the new user; users may not, and are limited by their invite quota. The returned invite carries its
+// code, which is not retrievable afterwards.
+func (s *Service) Create(ctx context.Context, params CreateParams) (*Invite, error) {
+	if params.CreatedBy == uuid.Nil {
+		if err := s.checkGroups(ctx, params.Groups); err != nil {
+			return nil, err
+		}
+	} else {
+		if len(params.Groups) > 0 || params.InviteQuota > 0 {
+			return nil, ErrInvitePresetsForbidden
+		}
+		quota, err := s.Quota(ctx, QuotaParams{UserID: params.CreatedBy})
+		if err != nil {
+			return nil, err
+		}
+		if quota.Used >= quota.Limit {
+			return nil, ErrInviteQuotaExceeded
+		}
+	}
+
+	expiresAt := params.ExpiresAt
+	if expiresAt.IsZero() {
+		expiresAt = s.now().Add(s.ttl)
+	}
+	code := codePrefix + rand.Text()
+	i, err := invite.NewInvite(invite.NewInviteParams{
+		ExpiresAt:   expiresAt,
+		Code:        code,
+		Note:        params.Note,
+		Groups:      params.Groups,
+		InviteQuota: params.InviteQuota,
+		CreatedBy:   params.CreatedBy,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create new invite: %w", mapError(err))
+	}
+
+	if err := s.r.Save(ctx, repository.SaveParams{Entity: i}); err != nil {
+		return nil, fmt.Errorf("failed to save invite: %w", mapError(err))
+	}
+
+	s.audit.Record(ctx, audit.Event{
+		Type:   audit.EventInviteCreated,
+		UserID: i.CreatedBy,
+		Details: map[string]string{
+			"invite_id":    i.ID.String(),
+			"groups":       strconv.Itoa(len(i.Groups)),
+			"invite_quota": strconv.Itoa(i.InviteQuota),
+		},
+	})
+
+	result := newInviteFromDomain(i, s.now())
+	result.Code = code
+	return result, nil
+}
+
+// List retrieves the invites created by the user, or every invite for administrators, without their codes.
+func (s *Service) List(ctx context.Context, params ListParams) ([]*Invite, error) {
+	is, err := s.r.Load(ctx, repository.LoadParams{CreatedBy: params.CreatedBy})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load invites: %w", mapError(err))
+	}
+	return newInvitesFromDomain(is, s.now()), nil
+}
+
+// Revoke prevents an invite created by the user, or any invite for administrators, from being redeemed.
+// Revoking a revoked or expired invite has no effect; a redeemed invite cannot be revoked.
+func (s *Service) Revoke(ctx context.Context, params RevokeParams) error {
+	is, err := s.r.Load(ctx, repository.LoadParams{ID: params.ID, CreatedBy: params.CreatedBy})
+	if err != nil {
+		return fmt.Errorf("failed to load invite: %w", mapError(err))
+	}
+	if len(is) == 0 {
+		return ErrInviteNotFound
+	}
+
+	i := is[0]
+	if err := i.Revoke(s.now()); err != nil {
+		return fmt.Errorf("failed to revoke invite: %w", mapError(err))
+	}
+	if err := s.r.Save(ctx, repository.SaveParams{Entity: i}); err != nil {
+		return fmt.Errorf("failed to save invite: %w", mapError(err))
+	}
+
+	s.audit.Record(ctx, audit.Event{
+		Type:    audit.EventInviteRevoked,
+		UserID:  params.CreatedBy,
+		Details: map[string]string{"invite_id": i.ID.String()},
+	})
+	return nil
+}
+
+// Quota reports how many invites the user may create and how many count against the limit. The limit is
+// the configured user quota, or the quota preset by the invite the user registered with when larger.
+// Pending and redeemed invites count; revoked and expired ones free their slot.
+func (s *Service) Quota(ctx context.Context, params QuotaParams) (*Quota, error) {
+	redeemed, err := s.r.Load(ctx, repository.LoadParams{RedeemedBy: params.UserID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load redeemed invite: %w", mapError(err))
+	}
+	limit := s.userQuota
+	for _, i := range redeemed {
+		limit = max(limit, i.InviteQuota)
+	}
+
+	created, err := s.r.Load(ctx, repository.LoadParams{CreatedBy: params.UserID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load invites: %w", mapError(err))
+	}
+	now := s.now()
+	// used counts the invites holding a slot.
+	var used int
+	for _, i := range created {
+		if st := i.Status(now); st == invite.StatusPending || st == invite.StatusRedeemed {
+			used++
+		}
+	}
+	return &Quota{Limit: limit, Used: used}, nil
+}
+
+// Redeem registers a new user with register under the invite holding the code, in a single transaction:
+// the invite is marked as redeemed and the user joins its preset groups, or nothing happens. Without a code
+// the user registers uninvited unless registration requires an invite. Errors of register are returned
+// unchanged; an unknown, redeemed, revoked or expired code fails with ErrInviteInvalid.
+func (s *Service) Redeem(
+	ctx context.Context,
+	params RedeemParams,
+	register func(ctx context.Context) (uuid.UUID, error),
+) (uuid.UUID, error) {
+	if params.Code == "" {
+		if s.required {
+			return uuid.Nil, ErrInviteRequired
+		}
+		return register(ctx)
+	}
+
+	// userID identifies the registered user.
+	var userID uuid.UUID
+	// i holds the redeemed invite.
+	var i *invite.Invite
+	err := s.uow.Do(ctx, uuid.Nil, func(ctx context.Context) error {
+		is, err := s.r.Load(ctx, repository.LoadParams{CodeHash: invite.HashCode(params.Code)})
+		if err != nil && !errors.Is(err, repository.ErrInviteNotFound) {
+			return fmt.Errorf("failed to load invite: %w", mapError(err))
+		}
+		if len(is) == 0 || is[0].Status(s.now()) != invite.StatusPending {
+			return ErrInviteInvalid
+		}
+		i = is[0]
+
+		userID, err = register(ctx)
+		if err != nil {
+			return err
+		}
+
+		err = s.r.Redeem(ctx, repository.RedeemParams{At: s.now(), ID: i.ID, UserID: userID})
+		if errors.Is(err, repository.ErrInviteNotFound) {
+			return ErrInviteInvalid
+		}
+		if err != nil {
+			return fmt.Errorf("failed to redeem invite: %w", mapError(err))
+		}
+		return s.join(ctx, i.Groups, userID)
+	})
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	s.audit.Record(ctx, audit.Event{
+		Type:    audit.EventInviteRedeemed,
+		UserID:  userID,
+		Details: map[string]string{"invite_id": i.ID.String(), "created_by": i.CreatedBy.String()},
+	})
+	return userID, nil
+}
+
+// checkGroups verifies that every group exists.
+func (s *Service) checkGroups(ctx context.Context, groupIDs []uuid.UUID) error {
+	for _, id := range groupIDs {
+		if id == uuid.Nil {
+			continue
+		}
+		_, _, err := s.groups.Load(ctx, groupRepo.LoadParams{ID: id})
+		if errors.Is(err, groupRepo.ErrGroupNotFound) {
+			return ErrInviteGroupNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("failed to load invite group: %w", mapError(err))
+		}
+	}
+	return nil
+}
+
+// join adds the user to the groups, skipping the groups deleted since the invite was created.
+func (s *Service) join(ctx context.Context, groupIDs []uuid.UUID, userID uuid.UUID) error {
+	for _, id := range groupIDs {
+		_, _, err := s.groups.Load(ctx, groupRepo.LoadParams{ID: id})
+		if errors.Is(err, groupRepo.ErrGroupNotFound) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to load invite group: %w", mapError(err))
+		}
+		err = s.groups.AddMembers(ctx, groupRepo.MembersParams{GroupID: id, UserIDs: []uuid.UUID{userID}})
+		if err != nil {
+			return fmt.Errorf("failed to add user to invite group: %w", mapError(err))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,356 @@
+package invite
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/audit"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/group"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/invite"
+	groupRepo "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/group"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/invite"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockRepository implements Repository for testing.
+type mockRepository struct {
+	stored []*invite.Invite
+}
+
+func (m *mockRepository) Save(_ context.Context, params repository.SaveParams) error {
+	for k, i := range m.stored {
+		if i.ID == params.Entity.ID {
+			m.stored[k] = params.Entity
+			return nil
+		}
+	}
+	m.stored = append(m.stored, params.Entity)
+	return nil
+}
+
+func (m *mockRepository) Load(_ context.Context, params repository.LoadParams) ([]*invite.Invite, error) {
+	var is []*invite.Invite
+	for _, i := range m.stored {
+		if params.ID != uuid.Nil && i.ID != params.ID {
+			continue
+		}
+		if params.CreatedBy != uuid.Nil && i.CreatedBy != params.CreatedBy {
+			continue
+		}
+		if params.RedeemedBy != uuid.Nil && i.RedeemedBy != params.RedeemedBy {
+			continue
+		}
+		if params.CodeHash != nil && string(i.CodeHash) != string(params.CodeHash) {
+			continue
+		}
+		is = append(is, i)
+	}
+	if len(is) == 0 && (params.ID != uuid.Nil || params.CodeHash != nil) {
+		return nil, repository.ErrInviteNotFound
+	}
+	return is, nil
+}
+
+func (m *mockRepository) Redeem(_ context.Context, params repository.RedeemParams) error {
+	for _, i := range m.stored {
+		if i.ID == params.ID {
+			if err := i.Redeem(params.UserID, params.At); err != nil {
+				return repository.ErrInviteNotFound
+			}
+			return nil
+		}
+	}
+	return repository.ErrInviteNotFound
+}
+
+// mockGroups implements GroupRepository for testing.
+type mockGroups struct {
+	members map[uuid.UUID][]uuid.UUID
+}
+
+func (m *mockGroups) Load(_ context.Context, params groupRepo.LoadParams) ([]*group.Group, int, error) {
+	if _, ok := m.members[params.ID]; !ok {
+		return nil, 0, groupRepo.ErrGroupNotFound
+	}
+	return []*group.Group{{ID: params.ID}}, 1, nil
+}
+
+func (m *mockGroups) AddMembers(_ context.Context, params groupRepo.MembersParams) error {
+	m.members[params.GroupID] = append(m.members[params.GroupID], params.UserIDs...)
+	return nil
+}
+
+// mockUnitOfWork implements UnitOfWork for testing without transactions.
+type mockUnitOfWork struct{}
+
+func (mockUnitOfWork) Do(ctx context.Context, _ uuid.UUID, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// mockAudit implements AuditRecorder for testing.
+type mockAudit struct {
+	events []audit.Event
+}
+
+func (m *mockAudit) Record(_ context.Context, e audit.Event) {
+	m.events = append(m.events, e)
+}
+
+// newTestInvite creates a stored invite holding the code.
+func newTestInvite(t *testing.T, code string, createdBy uuid.UUID) *invite.Invite {
+	t.Helper()
+
+	i, err := invite.NewInvite(invite.NewInviteParams{
+		ExpiresAt: time.Now().Add(time.Hour),
+		Code:      code,
+		CreatedBy: createdBy,
+	})
+	require.NoError(t, err)
+	return i
+}
+
+func TestService_Create(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	groupID := uuid.New()
+
+	tests := []struct {
+		wantErr   error
+		params    CreateParams
+		name      string
+		stored    int
+		userQuota int
+	}{
+		{
+			name:   "administrator with presets",
+			params: CreateParams{Note: "family", Groups: []uuid.UUID{groupID}, InviteQuota: 2},
+		},
+		{
+			name:    "administrator with unknown group",
+			params:  CreateParams{Groups: []uuid.UUID{uuid.New()}},
+			wantErr: ErrInviteGroupNotFound,
+		},
+		{
+			name:      "user within quota",
+			params:    CreateParams{CreatedBy: userID},
+			userQuota: 2,
+			stored:    1,
+		},
+		{
+			name:      "user over quota",
+			params:    CreateParams{CreatedBy: userID},
+			userQuota: 1,
+			stored:    1,
+			wantErr:   ErrInviteQuotaExceeded,
+		},
+		{
+			name:      "user with presets",
+			params:    CreateParams{CreatedBy: userID, InviteQuota: 1},
+			userQuota: 5,
+			wantErr:   ErrInvitePresetsForbidden,
+		},
+		{
+			name:    "expiry too far ahead",
+			params:  CreateParams{ExpiresAt: time.Now().Add(invite.MaxLifetime + time.Hour)},
+			wantErr: ErrInviteIncorrectExpiry,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &mockRepository{}
+			for range tt.stored {
+				repo.stored = append(repo.stored, newTestInvite(t, codePrefix+uuid.NewString(), userID))
+			}
+			groups := &mockGroups{members: map[uuid.UUID][]uuid.UUID{groupID: nil}}
+			rec := &mockAudit{}
+			s := NewService(repo, groups, mockUnitOfWork{}, rec, true, 0, tt.userQuota)
+
+			got, err := s.Create(context.Background(), tt.params)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Len(t, repo.stored, tt.stored)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, strings.HasPrefix(got.Code, codePrefix))
+			assert.Equal(t, string(invite.StatusPending), got.Status)
+			assert.WithinDuration(t, time.Now().Add(defaultTTL), got.ExpiresAt, time.Minute)
+			assert.Len(t, repo.stored, tt.stored+1)
+			assert.Equal(t, invite.HashCode(got.Code), repo.stored[tt.stored].CodeHash)
+			require.Len(t, rec.events, 1)
+			assert.Equal(t, audit.EventInviteCreated, rec.events[0].Type)
+		})
+	}
+}
+
+func TestService_Quota(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	granted := newTestInvite(t, codePrefix+uuid.NewString(), uuid.Nil)
+	granted.InviteQuota = 3
+	require.NoError(t, granted.Redeem(userID, time.Now()))
+	pending := newTestInvite(t, codePrefix+uuid.NewString(), userID)
+	revoked := newTestInvite(t, codePrefix+uuid.NewString(), userID)
+	require.NoError(t, revoked.Revoke(time.Now()))
+	expired := newTestInvite(t, codePrefix+uuid.NewString(), userID)
+	expired.ExpiresAt = time.Now().Add(-time.Minute)
+
+	repo := &mockRepository{stored: []*invite.Invite{granted, pending, revoked, expired}}
+	s := NewService(repo, &mockGroups{}, mockUnitOfWork{}, &mockAudit{}, true, 0, 1)
+
+	got, err := s.Quota(context.Background(), QuotaParams{UserID: userID})
+	require.NoError(t, err)
+	assert.Equal(t, &Quota{Limit: 3, Used: 1}, got)
+}
+
+func TestService_Revoke(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+
+	tests := []struct {
+		wantErr   error
+		prepare   func(i *invite.Invite)
+		name      string
+		createdBy uuid.UUID
+	}{
+		{name: "creator", createdBy: userID},
+		{name: "administrator", createdBy: uuid.Nil},
+		{name: "other user", createdBy: uuid.New(), wantErr: ErrInviteNotFound},
+		{
+			name:      "redeemed",
+			createdBy: userID,
+			prepare:   func(i *invite.Invite) { _ = i.Redeem(uuid.New(), time.Now()) },
+			wantErr:   ErrInviteRedeemed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			i := newTestInvite(t, codePrefix+uuid.NewString(), userID)
+			if tt.prepare != nil {
+				tt.prepare(i)
+			}
+			repo := &mockRepository{stored: []*invite.Invite{i}}
+			rec := &mockAudit{}
+			s := NewService(repo, &mockGroups{}, mockUnitOfWork{}, rec, true, 0, 0)
+
+			err := s.Revoke(context.Background(), RevokeParams{ID: i.ID, CreatedBy: tt.createdBy})
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Empty(t, rec.events)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, invite.StatusRevoked, i.Status(time.Now()))
+			require.Len(t, rec.events, 1)
+			assert.Equal(t, audit.EventInviteRevoked, rec.events[0].Type)
+		})
+	}
+}
+
+func TestService_Redeem(t *testing.T) {
+	t.Parallel()
+
+	code := codePrefix + "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	errRegister := errors.New("user already exists")
+
+	tests := []struct {
+		wantErr     error
+		registerErr error
+		prepare     func(i *invite.Invite)
+		name        string
+		code        string
+		required    bool
+		wantGroup   bool
+		wantRedeem  bool
+		wantCalled  bool
+		deleteGroup bool
+	}{
+		{name: "valid code", code: code, wantRedeem: true, wantCalled: true, wantGroup: true},
+		{name: "deleted group is skipped", code: code, deleteGroup: true, wantRedeem: true, wantCalled: true},
+		{name: "unknown code", code: codePrefix + "unknown", wantErr: ErrInviteInvalid},
+		{
+			name:    "revoked code",
+			code:    code,
+			prepare: func(i *invite.Invite) { _ = i.Revoke(time.Now()) },
+			wantErr: ErrInviteInvalid,
+		},
+		{
+			name:    "expired code",
+			code:    code,
+			prepare: func(i *invite.Invite) { i.ExpiresAt = time.Now().Add(-time.Second) },
+			wantErr: ErrInviteInvalid,
+		},
+		{
+			name:        "registration fails",
+			code:        code,
+			registerErr: errRegister,
+			wantErr:     errRegister,
+			wantCalled:  true,
+		},
+		{name: "no code in open mode", wantCalled: true},
+		{name: "no code in invite mode", required: true, wantErr: ErrInviteRequired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			groupID := uuid.New()
+			i := newTestInvite(t, code, uuid.Nil)
+			i.Groups = []uuid.UUID{groupID}
+			if tt.prepare != nil {
+				tt.prepare(i)
+			}
+			repo := &mockRepository{stored: []*invite.Invite{i}}
+			groups := &mockGroups{members: map[uuid.UUID][]uuid.UUID{groupID: nil}}
+			if tt.deleteGroup {
+				delete(groups.members, groupID)
+			}
+			rec := &mockAudit{}
+			s := NewService(repo, groups, mockUnitOfWork{}, rec, tt.required, 0, 0)
+
+			newUserID := uuid.New()
+			// called reports whether the registration ran.
+			var called bool
+			userID, err := s.Redeem(context.Background(), RedeemParams{Code: tt.code},
+				func(context.Context) (uuid.UUID, error) {
+					called = true
+					return newUserID, tt.registerErr
+				})
+
+			assert.Equal(t, tt.wantCalled, called)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Equal(t, uuid.Nil, userID)
+				assert.True(t, i.RedeemedAt.IsZero())
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, newUserID, userID)
+			if !tt.wantRedeem {
+				assert.Empty(t, rec.events)
+				return
+			}
+			assert.Equal(t, newUserID, i.RedeemedBy)
+			if tt.wantGroup {
+				assert.Equal(t, []uuid.UUID{newUserID}, groups.members[groupID])
+			}
+			require.Len(t, rec.events, 1)
+			assert.Equal(t, audit.EventInviteRedeemed, rec.events[0].Type)
+		})
+	}
+}
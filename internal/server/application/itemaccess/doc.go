@@ -0,0 +1,5 @@
+// Package itemaccess provides item access history application services for the AegisVaultKeeper server.
+//
+// This package implements recording of the reveals of vault items in the audit trail and the access
+// history users review to spot unexpected access to their secrets.
+package itemaccess
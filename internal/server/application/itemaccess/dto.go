@@ -0,0 +1,85 @@
+package itemaccess
+
+import (
+	"net/netip"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/itemaccess"
+	"github.com/google/uuid"
+)
+
+// Reveal represents an item reveal data transfer object for application layer communication.
+type Reveal struct {
+	// RevealedAt indicates when the item was revealed.
+	RevealedAt time.Time
+	// IP contains the client address the item was revealed from, if known.
+	IP netip.Addr
+	// UserAgent contains the reported client software.
+	UserAgent string
+}
+
+// DeviceAccess represents the reveals of an item from one device.
+type DeviceAccess struct {
+	// LastRevealedAt indicates the latest reveal from the device.
+	LastRevealedAt time.Time
+	// UserAgent contains the client software reported by the latest reveal from the device.
+	UserAgent string
+	// Count is the number of reveals from the device.
+	Count int
+}
+
+// History represents the access history of a vault item.
+type History struct {
+	// LastRevealedAt indicates the latest reveal; zero when the item was never revealed.
+	LastRevealedAt time.Time
+	// Devices summarizes the reveals per device, most recently active first.
+	Devices []*DeviceAccess
+	// Reveals lists the latest reveals, newest first.
+	Reveals []*Reveal
+	// Count is the number of reveals of the item.
+	Count int
+	// ItemID identifies the item.
+	ItemID uuid.UUID
+}
+
+// newHistoryFromDomain converts a domain access history of the item to application DTO.
+func newHistoryFromDomain(itemID uuid.UUID, h *itemaccess.History) *History {
+	result := &History{
+		ItemID:         itemID,
+		Count:          h.Count,
+		LastRevealedAt: h.LastRevealedAt,
+		Devices:        make([]*DeviceAccess, 0, len(h.Devices)),
+		Reveals:        make([]*Reveal, 0, len(h.Reveals)),
+	}
+	for _, d := range h.Devices {
+		result.Devices = append(result.Devices, &DeviceAccess{
+			LastRevealedAt: d.LastRevealedAt,
+			UserAgent:      d.UserAgent,
+			Count:          d.Count,
+		})
+	}
+	for _, r := range h.Reveals {
+		result.Reveals = append(result.Reveals, &Reveal{RevealedAt: r.RevealedAt, IP: r.IP, UserAgent: r.UserAgent})
+	}
+	return result
+}
+
+// RecordParams contains parameters for recording a reveal of an item.
+type RecordParams struct {
+	// IP contains the client address the item was revealed from, if known.
+	IP netip.Addr
+	// UserAgent contains the reported client software.
+	UserAgent string
+	// ItemID identifies the revealed item.
+	ItemID uuid.UUID
+	// UserID identifies the user who revealed the item.
+	UserID uuid.UUID
+}
+
+// HistoryParams contains parameters for retrieving the access history of an item.
+type HistoryParams struct {
+	// ItemID identifies the item.
+	ItemID uuid.UUID
+	// UserID identifies the user who owns the item.
+	UserID uuid.UUID
+}
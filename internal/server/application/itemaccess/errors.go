@@ -0,0 +1,41 @@
+package itemaccess
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/errutil"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/itemaccess"
+)
+
+// Item access error definitions.
+var (
+	// ErrItemAccessAppError indicates a general item access application error.
+	ErrItemAccessAppError = errors.New("item access application error")
+
+	// ErrItemAccessTechError indicates a technical error in the item access system.
+	ErrItemAccessTechError = errors.New("item access technical error")
+)
+
+// mapError maps domain and repository errors to application-level errors.
+func mapError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	mapped := errutil.MapError(mapFn, err)
+	if mapped != nil {
+		return fmt.Errorf("item access error mapping failed: %w", mapped)
+	}
+	return nil
+}
+
+// mapFn provides the actual error mapping logic for different error types.
+func mapFn(err error) error {
+	switch {
+	case errors.Is(err, itemaccess.ErrNewRevealParamsValidation):
+		return ErrItemAccessAppError
+	default:
+		return errors.Join(ErrItemAccessTechError, err)
+	}
+}
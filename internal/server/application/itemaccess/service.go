@@ -0,0 +1,78 @@
+package itemaccess
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/audit"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/itemaccess"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/itemaccess"
+)
+
+// historyLimit bounds the number of reveals listed in an access history; counters cover every reveal.
+const historyLimit = 100
+
+// Repository defines the interface for item reveal persistence operations.
+type Repository interface {
+	// Save persists an item reveal using the provided parameters.
+	Save(ctx context.Context, params repository.SaveParams) error
+	// Load retrieves the reveals of an item, newest first, using the provided parameters.
+	Load(ctx context.Context, params repository.LoadParams) ([]*itemaccess.Reveal, error)
+}
+
+// AuditRecorder defines the interface for recording security audit events.
+type AuditRecorder interface {
+	// Record stores the audit event.
+	Record(ctx context.Context, e audit.Event)
+}
+
+// Service provides item access history operations.
+type Service struct {
+	// r is the repository interface for item reveal persistence operations.
+	r Repository
+	// audit records item reveals.
+	audit AuditRecorder
+}
+
+// NewService creates a new item access history service instance.
+func NewService(r Repository, audit AuditRecorder) *Service {
+	return &Service{r: r, audit: audit}
+}
+
+// Record stores a reveal of an item in its access history and the audit trail.
+func (s *Service) Record(ctx context.Context, params RecordParams) error {
+	reveal, err := itemaccess.NewReveal(itemaccess.NewRevealParams{
+		IP:        params.IP,
+		UserAgent: params.UserAgent,
+		ItemID:    params.ItemID,
+		UserID:    params.UserID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create item reveal: %w", mapError(err))
+	}
+
+	if err := s.r.Save(ctx, repository.SaveParams{Entity: reveal}); err != nil {
+		return fmt.Errorf("failed to save item reveal: %w", mapError(err))
+	}
+
+	s.audit.Record(ctx, audit.Event{
+		Type:       audit.EventItemRevealed,
+		UserID:     params.UserID,
+		OccurredAt: reveal.RevealedAt,
+		Details: map[string]string{
+			"item_id":    params.ItemID.String(),
+			"user_agent": reveal.UserAgent,
+		},
+	})
+	return nil
+}
+
+// History retrieves the access history of an item: how many times and when it was revealed, per device,
+// and its latest reveals. A never revealed item has an empty history.
+func (s *Service) History(ctx context.Context, params HistoryParams) (*History, error) {
+	reveals, err := s.r.Load(ctx, repository.LoadParams{UserID: params.UserID, ItemID: params.ItemID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load item reveals: %w", mapError(err))
+	}
+	return newHistoryFromDomain(params.ItemID, itemaccess.NewHistory(reveals, historyLimit)), nil
+}
@@ -0,0 +1,169 @@
+package itemaccess
+
+import (
+	"context"
+	"errors"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/audit"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/itemaccess"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/itemaccess"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockRepository implements Repository for testing.
+type mockRepository struct {
+	loadErr    error
+	saveErr    error
+	saved      *itemaccess.Reveal
+	loadParams repository.LoadParams
+	stored     []*itemaccess.Reveal
+}
+
+func (m *mockRepository) Save(_ context.Context, params repository.SaveParams) error {
+	m.saved = params.Entity
+	return m.saveErr
+}
+
+func (m *mockRepository) Load(_ context.Context, params repository.LoadParams) ([]*itemaccess.Reveal, error) {
+	m.loadParams = params
+	return m.stored, m.loadErr
+}
+
+// mockAuditRecorder implements AuditRecorder for testing.
+type mockAuditRecorder struct {
+	events []audit.Event
+}
+
+func (m *mockAuditRecorder) Record(_ context.Context, e audit.Event) {
+	m.events = append(m.events, e)
+}
+
+func TestService_Record(t *testing.T) {
+	t.Parallel()
+
+	userID, itemID := uuid.New(), uuid.New()
+	ip := netip.MustParseAddr("203.0.113.7")
+
+	tests := []struct {
+		repo    *mockRepository
+		wantErr error
+		name    string
+		params  RecordParams
+	}{
+		{
+			name:   "reveal recorded",
+			repo:   &mockRepository{},
+			params: RecordParams{IP: ip, UserAgent: "aegis-cli/1.0", ItemID: itemID, UserID: userID},
+		},
+		{
+			name:    "missing item",
+			repo:    &mockRepository{},
+			params:  RecordParams{UserAgent: "aegis-cli/1.0", UserID: userID},
+			wantErr: ErrItemAccessAppError,
+		},
+		{
+			name:    "save failure",
+			repo:    &mockRepository{saveErr: errors.New("connection refused")},
+			params:  RecordParams{UserAgent: "aegis-cli/1.0", ItemID: itemID, UserID: userID},
+			wantErr: ErrItemAccessTechError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			recorder := &mockAuditRecorder{}
+			err := NewService(tt.repo, recorder).Record(context.Background(), tt.params)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Empty(t, recorder.events)
+				return
+			}
+			require.NoError(t, err)
+			require.NotNil(t, tt.repo.saved)
+			assert.Equal(t, itemID, tt.repo.saved.ItemID)
+			assert.Equal(t, userID, tt.repo.saved.UserID)
+			assert.Equal(t, ip, tt.repo.saved.IP)
+			require.Len(t, recorder.events, 1)
+			assert.Equal(t, audit.EventItemRevealed, recorder.events[0].Type)
+			assert.Equal(t, userID, recorder.events[0].UserID)
+			assert.Equal(t, itemID.String(), recorder.events[0].Details["item_id"])
+			assert.Equal(t, "aegis-cli/1.0", recorder.events[0].Details["user_agent"])
+		})
+	}
+}
+
+func TestService_History(t *testing.T) {
+	t.Parallel()
+
+	userID, itemID := uuid.New(), uuid.New()
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	ip := netip.MustParseAddr("203.0.113.7")
+	stored := []*itemaccess.Reveal{
+		{ItemID: itemID, UserID: userID, UserAgent: "phone", Fingerprint: "p", IP: ip, RevealedAt: now},
+		{ItemID: itemID, UserID: userID, UserAgent: "laptop", Fingerprint: "l", RevealedAt: now.Add(-time.Hour)},
+		{ItemID: itemID, UserID: userID, UserAgent: "phone", Fingerprint: "p", RevealedAt: now.Add(-2 * time.Hour)},
+	}
+
+	tests := []struct {
+		repo    *mockRepository
+		wantErr error
+		want    *History
+		name    string
+	}{
+		{
+			name: "revealed item",
+			repo: &mockRepository{stored: stored},
+			want: &History{
+				ItemID:         itemID,
+				Count:          3,
+				LastRevealedAt: now,
+				Devices: []*DeviceAccess{
+					{UserAgent: "phone", LastRevealedAt: now, Count: 2},
+					{UserAgent: "laptop", LastRevealedAt: now.Add(-time.Hour), Count: 1},
+				},
+				Reveals: []*Reveal{
+					{UserAgent: "phone", IP: ip, RevealedAt: now},
+					{UserAgent: "laptop", RevealedAt: now.Add(-time.Hour)},
+					{UserAgent: "phone", RevealedAt: now.Add(-2 * time.Hour)},
+				},
+			},
+		},
+		{
+			name: "never revealed item",
+			repo: &mockRepository{},
+			want: &History{ItemID: itemID, Devices: []*DeviceAccess{}, Reveals: []*Reveal{}},
+		},
+		{
+			name:    "load failure",
+			repo:    &mockRepository{loadErr: errors.New("connection refused")},
+			wantErr: ErrItemAccessTechError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := NewService(tt.repo, &mockAuditRecorder{}).History(
+				context.Background(),
+				HistoryParams{ItemID: itemID, UserID: userID},
+			)
+
+			assert.Equal(t, repository.LoadParams{UserID: userID, ItemID: itemID}, tt.repo.loadParams)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,5 @@
+// Package itemorder provides manual item order application services for the AegisVaultKeeper server.
+//
+// This package implements management of the per-folder orders users arrange and pin their vault items in,
+// which item listings follow when the manual sort is requested.
+package itemorder
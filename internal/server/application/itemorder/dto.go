@@ -0,0 +1,78 @@
+package itemorder
+
+import (
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/itemorder"
+	"github.com/google/uuid"
+)
+
+// Entry represents an item placed in a manual order.
+type Entry struct {
+	// ItemID identifies the placed item.
+	ItemID uuid.UUID
+	// Pinned indicates whether the item is pinned ahead of the unpinned items.
+	Pinned bool
+}
+
+// ItemOrder represents an item order data transfer object for application layer communication.
+type ItemOrder struct {
+	// UpdatedAt indicates when the order was last changed; zero for folders without an order.
+	UpdatedAt time.Time
+	// Folder contains the folder the order applies to; empty for the root.
+	Folder string
+	// Entries contains the placed items in order, the pinned ones first.
+	Entries []Entry
+}
+
+// newItemOrderFromDomain converts a domain item order entity to application DTO.
+func newItemOrderFromDomain(o *itemorder.ItemOrder) *ItemOrder {
+	if o == nil {
+		return nil
+	}
+	entries := make([]Entry, 0, len(o.Entries))
+	for _, e := range o.Entries {
+		entries = append(entries, Entry{ItemID: e.ItemID, Pinned: e.Pinned})
+	}
+	return &ItemOrder{
+		Folder:    o.Folder,
+		Entries:   entries,
+		UpdatedAt: o.UpdatedAt,
+	}
+}
+
+// Position represents the requested place of an item in an order.
+type Position struct {
+	// ItemID identifies the moved item.
+	ItemID uuid.UUID
+	// Position contains the zero-based index of the item among the pinned or among the unpinned items.
+	Position int
+	// Pinned indicates whether the item is pinned.
+	Pinned bool
+}
+
+// GetParams contains parameters for retrieving the order of a folder.
+type GetParams struct {
+	// Folder contains the folder; empty addresses the root.
+	Folder string
+	// UserID identifies the user who owns the order.
+	UserID uuid.UUID
+}
+
+// ReorderParams contains parameters for moving items within the order of a folder.
+type ReorderParams struct {
+	// Folder contains the folder; empty addresses the root.
+	Folder string
+	// Positions contains the requested places of the items, applied one after another.
+	Positions []Position
+	// UserID identifies the user who owns the order.
+	UserID uuid.UUID
+}
+
+// ResetParams contains parameters for removing the order of a folder.
+type ResetParams struct {
+	// Folder contains the folder; empty addresses the root.
+	Folder string
+	// UserID identifies the user who owns the order.
+	UserID uuid.UUID
+}
@@ -0,0 +1,56 @@
+package itemorder
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/errutil"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/itemorder"
+)
+
+// Item order error definitions.
+var (
+	// ErrItemOrderAppError indicates a general item order application error.
+	ErrItemOrderAppError = errors.New("item order application error")
+
+	// ErrItemOrderTechError indicates a technical error in the item order system.
+	ErrItemOrderTechError = errors.New("item order technical error")
+
+	// ErrIncorrectFolder indicates a folder that is not a sequence of short names separated by slashes was provided.
+	ErrIncorrectFolder = errors.New("incorrect item order folder")
+
+	// ErrIncorrectPosition indicates a position without an item or with a negative index was provided.
+	ErrIncorrectPosition = errors.New("incorrect item position")
+
+	// ErrTooManyItems indicates that the order would hold more items than allowed.
+	ErrTooManyItems = errors.New("too many ordered items")
+)
+
+// mapError maps domain and repository errors to application-level errors.
+func mapError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	mapped := errutil.MapError(mapFn, err)
+	if mapped != nil {
+		return fmt.Errorf("item order error mapping failed: %w", mapped)
+	}
+	return nil
+}
+
+// mapFn provides the actual error mapping logic for different error types.
+func mapFn(err error) error {
+	switch {
+	case errors.Is(err, itemorder.ErrNewItemOrderParamsValidation):
+		return ErrItemOrderAppError
+	case errors.Is(err, itemorder.ErrIncorrectFolder):
+		return ErrIncorrectFolder
+	case errors.Is(err, itemorder.ErrIncorrectItem), errors.Is(err, itemorder.ErrIncorrectPosition):
+		return ErrIncorrectPosition
+	case errors.Is(err, itemorder.ErrTooManyItems):
+		return ErrTooManyItems
+	default:
+		return errors.Join(ErrItemOrderTechError, err)
+	}
+}
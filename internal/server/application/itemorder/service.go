@@ -0,0 +1,91 @@
+package itemorder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/itemorder"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/itemorder"
+	"github.com/google/uuid"
+)
+
+// Repository defines the interface for item order persistence operations.
+type Repository interface {
+	// Save persists the order of a folder using the provided parameters.
+	Save(ctx context.Context, params repository.SaveParams) error
+	// Load retrieves the order of a folder using the provided parameters.
+	Load(ctx context.Context, params repository.LoadParams) (*itemorder.ItemOrder, error)
+}
+
+// Service provides item order operations.
+type Service struct {
+	// r is the repository interface for item order persistence operations.
+	r Repository
+}
+
+// NewService creates a new item order service instance.
+func NewService(r Repository) *Service {
+	return &Service{r: r}
+}
+
+// GetOrder retrieves the order of a folder; a folder the user has not arranged has an empty one.
+func (s *Service) GetOrder(ctx context.Context, params GetParams) (*ItemOrder, error) {
+	o, err := s.load(ctx, params.Folder, params.UserID)
+	if err != nil {
+		return nil, err
+	}
+	return newItemOrderFromDomain(o), nil
+}
+
+// Reorder moves the items to the positions within the order of a folder, placing items not yet in the
+// order, and returns the resulting order.
+func (s *Service) Reorder(ctx context.Context, params ReorderParams) (*ItemOrder, error) {
+	o, err := s.load(ctx, params.Folder, params.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	positions := make([]itemorder.Position, 0, len(params.Positions))
+	for _, p := range params.Positions {
+		positions = append(positions, itemorder.Position{ItemID: p.ItemID, Position: p.Position, Pinned: p.Pinned})
+	}
+	if err := o.Move(positions); err != nil {
+		return nil, fmt.Errorf("failed to move items: %w", mapError(err))
+	}
+
+	if err := s.r.Save(ctx, repository.SaveParams{Entity: o}); err != nil {
+		return nil, fmt.Errorf("failed to save item order: %w", mapError(err))
+	}
+	return newItemOrderFromDomain(o), nil
+}
+
+// ResetOrder removes the order of a folder, so its items are listed in their default order again.
+func (s *Service) ResetOrder(ctx context.Context, params ResetParams) error {
+	o, err := itemorder.NewItemOrder(itemorder.NewItemOrderParams{Folder: params.Folder, UserID: params.UserID})
+	if err != nil {
+		return fmt.Errorf("failed to create item order: %w", mapError(err))
+	}
+
+	if err := s.r.Save(ctx, repository.SaveParams{Entity: o}); err != nil {
+		return fmt.Errorf("failed to delete item order: %w", mapError(err))
+	}
+	return nil
+}
+
+// load retrieves the stored order of the folder, or a new empty one when the folder has none.
+func (s *Service) load(ctx context.Context, folder string, userID uuid.UUID) (*itemorder.ItemOrder, error) {
+	o, err := itemorder.NewItemOrder(itemorder.NewItemOrderParams{Folder: folder, UserID: userID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create item order: %w", mapError(err))
+	}
+
+	stored, err := s.r.Load(ctx, repository.LoadParams{Folder: o.Folder, UserID: o.UserID})
+	if errors.Is(err, repository.ErrOrderNotFound) {
+		return o, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load item order: %w", mapError(err))
+	}
+	return stored, nil
+}
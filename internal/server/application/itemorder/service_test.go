@@ -0,0 +1,189 @@
+package itemorder
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/itemorder"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/itemorder"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockRepository implements Repository for testing.
+type mockRepository struct {
+	loadErr    error
+	saveErr    error
+	saved      *itemorder.ItemOrder
+	stored     *itemorder.ItemOrder
+	loadParams repository.LoadParams
+}
+
+func (m *mockRepository) Save(_ context.Context, params repository.SaveParams) error {
+	m.saved = params.Entity
+	return m.saveErr
+}
+
+func (m *mockRepository) Load(_ context.Context, params repository.LoadParams) (*itemorder.ItemOrder, error) {
+	m.loadParams = params
+	if m.stored == nil && m.loadErr == nil {
+		return nil, repository.ErrOrderNotFound
+	}
+	return m.stored, m.loadErr
+}
+
+func TestService_GetOrder(t *testing.T) {
+	t.Parallel()
+
+	userID, itemID := uuid.New(), uuid.New()
+
+	tests := []struct {
+		repo        *mockRepository
+		wantErr     error
+		name        string
+		folder      string
+		wantFolder  string
+		wantEntries []Entry
+	}{
+		{name: "folder without order", repo: &mockRepository{}, folder: "/prod/", wantFolder: "prod"},
+		{
+			name: "stored order",
+			repo: &mockRepository{stored: &itemorder.ItemOrder{
+				Folder:  "prod",
+				Entries: []itemorder.Entry{{ItemID: itemID, Pinned: true}},
+			}},
+			folder:      "prod",
+			wantFolder:  "prod",
+			wantEntries: []Entry{{ItemID: itemID, Pinned: true}},
+		},
+		{name: "incorrect folder", repo: &mockRepository{}, folder: "prod db", wantErr: ErrIncorrectFolder},
+		{
+			name:    "repository failure",
+			repo:    &mockRepository{loadErr: errors.New("connection refused")},
+			wantErr: ErrItemOrderTechError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := NewService(tt.repo).GetOrder(context.Background(), GetParams{Folder: tt.folder, UserID: userID})
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantFolder, got.Folder)
+			assert.ElementsMatch(t, tt.wantEntries, got.Entries)
+			assert.Equal(t, repository.LoadParams{Folder: tt.wantFolder, UserID: userID}, tt.repo.loadParams)
+		})
+	}
+}
+
+func TestService_Reorder(t *testing.T) {
+	t.Parallel()
+
+	userID, itemA, itemB := uuid.New(), uuid.New(), uuid.New()
+
+	tests := []struct {
+		repo      *mockRepository
+		wantErr   error
+		name      string
+		positions []Position
+		want      []Entry
+	}{
+		{
+			name: "move into stored order",
+			repo: &mockRepository{stored: &itemorder.ItemOrder{
+				UserID:  userID,
+				Entries: []itemorder.Entry{{ItemID: itemA}},
+			}},
+			positions: []Position{{ItemID: itemB, Pinned: true}},
+			want:      []Entry{{ItemID: itemB, Pinned: true}, {ItemID: itemA}},
+		},
+		{
+			name:      "first order of folder",
+			repo:      &mockRepository{},
+			positions: []Position{{ItemID: itemA, Position: 3}},
+			want:      []Entry{{ItemID: itemA}},
+		},
+		{
+			name:      "negative position",
+			repo:      &mockRepository{},
+			positions: []Position{{ItemID: itemA, Position: -1}},
+			wantErr:   ErrIncorrectPosition,
+		},
+		{
+			name:      "missing item",
+			repo:      &mockRepository{},
+			positions: []Position{{}},
+			wantErr:   ErrIncorrectPosition,
+		},
+		{
+			name:      "repository failure",
+			repo:      &mockRepository{saveErr: errors.New("connection refused")},
+			positions: []Position{{ItemID: itemA}},
+			wantErr:   ErrItemOrderTechError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := NewService(tt.repo).Reorder(context.Background(), ReorderParams{
+				Positions: tt.positions,
+				UserID:    userID,
+			})
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got.Entries)
+			assert.Len(t, tt.repo.saved.Entries, len(tt.want))
+		})
+	}
+}
+
+func TestService_ResetOrder(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+
+	tests := []struct {
+		repo    *mockRepository
+		wantErr error
+		name    string
+		folder  string
+	}{
+		{name: "reset order", repo: &mockRepository{}, folder: "prod/"},
+		{name: "incorrect folder", repo: &mockRepository{}, folder: "..", wantErr: ErrIncorrectFolder},
+		{
+			name:    "repository failure",
+			repo:    &mockRepository{saveErr: errors.New("connection refused")},
+			wantErr: ErrItemOrderTechError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := NewService(tt.repo).ResetOrder(context.Background(), ResetParams{Folder: tt.folder, UserID: userID})
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "prod", tt.repo.saved.Folder)
+			assert.Empty(t, tt.repo.saved.Entries)
+		})
+	}
+}
@@ -0,0 +1,5 @@
+// Package itempath provides item path application services for the AegisVaultKeeper server.
+//
+// This package implements management of the hierarchical paths users assign to their vault items,
+// which machine identities use to address the secrets they lease.
+package itempath
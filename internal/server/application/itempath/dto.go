@@ -0,0 +1,65 @@
+package itempath
+
+import (
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/itempath"
+	"github.com/google/uuid"
+)
+
+// ItemPath represents an item path data transfer object for application layer communication.
+type ItemPath struct {
+	// UpdatedAt indicates when the path was last changed; zero for items without a path.
+	UpdatedAt time.Time
+	// Path contains the path of the item; empty when the item has no path.
+	Path string
+	// ItemID identifies the addressed item.
+	ItemID uuid.UUID
+}
+
+// newItemPathFromDomain converts a domain item path entity to application DTO.
+func newItemPathFromDomain(p *itempath.ItemPath) *ItemPath {
+	if p == nil {
+		return nil
+	}
+	return &ItemPath{
+		ItemID:    p.ItemID,
+		Path:      p.Path,
+		UpdatedAt: p.UpdatedAt,
+	}
+}
+
+// newItemPathsFromDomain converts a slice of domain item path entities to application DTOs.
+func newItemPathsFromDomain(ps []*itempath.ItemPath) []*ItemPath {
+	result := make([]*ItemPath, 0, len(ps))
+	for _, p := range ps {
+		result = append(result, newItemPathFromDomain(p))
+	}
+	return result
+}
+
+// ListParams contains parameters for listing the item paths of a user.
+type ListParams struct {
+	// Prefix limits the listing to paths equal to or below it; empty lists every path.
+	Prefix string
+	// UserID identifies the user whose item paths are listed.
+	UserID uuid.UUID
+}
+
+// GetParams contains parameters for retrieving the path of an item.
+type GetParams struct {
+	// ItemID identifies the item.
+	ItemID uuid.UUID
+	// UserID identifies the user who owns the item.
+	UserID uuid.UUID
+}
+
+// SetParams contains parameters for replacing the path of an item.
+type SetParams struct {
+	// Path contains the new path of the item; empty removes the path.
+	Path string
+	// ItemID identifies the item.
+	ItemID uuid.UUID
+	// UserID identifies the user who owns the item.
+	UserID uuid.UUID
+}
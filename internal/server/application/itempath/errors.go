@@ -0,0 +1,54 @@
+package itempath
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/errutil"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/itempath"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/itempath"
+)
+
+// Item path error definitions.
+var (
+	// ErrItemPathAppError indicates a general item path application error.
+	ErrItemPathAppError = errors.New("item path application error")
+
+	// ErrItemPathTechError indicates a technical error in the item path system.
+	ErrItemPathTechError = errors.New("item path technical error")
+
+	// ErrIncorrectPath indicates a path that is not a sequence of short names separated by slashes was provided.
+	ErrIncorrectPath = errors.New("incorrect item path")
+
+	// ErrPathTaken indicates that another item of the user already has the path.
+	ErrPathTaken = errors.New("item path already taken")
+)
+
+// mapError maps domain and repository errors to application-level errors.
+func mapError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	mapped := errutil.MapError(mapFn, err)
+	if mapped != nil {
+		return fmt.Errorf("item path error mapping failed: %w", mapped)
+	}
+	return nil
+}
+
+// mapFn provides the actual error mapping logic for different error types.
+func mapFn(err error) error {
+	switch {
+	case errors.Is(err, itempath.ErrNewItemPathParamsValidation):
+		return ErrItemPathAppError
+	case errors.Is(err, itempath.ErrIncorrectPath):
+		return ErrIncorrectPath
+	case errors.Is(err, itempath.ErrIncorrectItem):
+		return ErrItemPathAppError
+	case errors.Is(err, repository.ErrPathTaken):
+		return ErrPathTaken
+	default:
+		return errors.Join(ErrItemPathTechError, err)
+	}
+}
@@ -0,0 +1,71 @@
+package itempath
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/itempath"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/itempath"
+)
+
+// Repository defines the interface for item path persistence operations.
+type Repository interface {
+	// Save persists the path of an item using the provided parameters.
+	Save(ctx context.Context, params repository.SaveParams) error
+	// Load retrieves item paths using the provided parameters.
+	Load(ctx context.Context, params repository.LoadParams) ([]*itempath.ItemPath, error)
+}
+
+// Service provides item path operations.
+type Service struct {
+	// r is the repository interface for item path persistence operations.
+	r Repository
+}
+
+// NewService creates a new item path service instance.
+func NewService(r Repository) *Service {
+	return &Service{r: r}
+}
+
+// ListPaths retrieves the item paths of the user equal to or below the prefix, ordered by path.
+func (s *Service) ListPaths(ctx context.Context, params ListParams) ([]*ItemPath, error) {
+	prefix := itempath.Normalize(params.Prefix)
+	if prefix != "" && !itempath.Valid(prefix) {
+		return nil, ErrIncorrectPath
+	}
+
+	paths, err := s.r.Load(ctx, repository.LoadParams{UserID: params.UserID, Prefix: prefix})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load item paths: %w", mapError(err))
+	}
+	return newItemPathsFromDomain(paths), nil
+}
+
+// GetPath retrieves the path of an item; an item without a path has an empty one.
+func (s *Service) GetPath(ctx context.Context, params GetParams) (*ItemPath, error) {
+	stored, err := s.r.Load(ctx, repository.LoadParams{UserID: params.UserID, ItemID: params.ItemID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load item path: %w", mapError(err))
+	}
+	if len(stored) == 0 {
+		return &ItemPath{ItemID: params.ItemID}, nil
+	}
+	return newItemPathFromDomain(stored[0]), nil
+}
+
+// SetPath replaces the path of an item. Paths are unique among the items of a user.
+func (s *Service) SetPath(ctx context.Context, params SetParams) (*ItemPath, error) {
+	p, err := itempath.NewItemPath(itempath.NewItemPathParams{
+		Path:   params.Path,
+		ItemID: params.ItemID,
+		UserID: params.UserID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create item path: %w", mapError(err))
+	}
+
+	if err := s.r.Save(ctx, repository.SaveParams{Entity: p}); err != nil {
+		return nil, fmt.Errorf("failed to save item path: %w", mapError(err))
+	}
+	return newItemPathFromDomain(p), nil
+}
@@ -0,0 +1,147 @@
+package itempath
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/itempath"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/itempath"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockRepository implements Repository for testing.
+type mockRepository struct {
+	loadErr    error
+	saveErr    error
+	saved      *itempath.ItemPath
+	loadParams repository.LoadParams
+	stored     []*itempath.ItemPath
+}
+
+func (m *mockRepository) Save(_ context.Context, params repository.SaveParams) error {
+	m.saved = params.Entity
+	return m.saveErr
+}
+
+func (m *mockRepository) Load(_ context.Context, params repository.LoadParams) ([]*itempath.ItemPath, error) {
+	m.loadParams = params
+	return m.stored, m.loadErr
+}
+
+func TestService_SetPath(t *testing.T) {
+	t.Parallel()
+
+	userID, itemID := uuid.New(), uuid.New()
+
+	tests := []struct {
+		repo     *mockRepository
+		wantErr  error
+		name     string
+		path     string
+		wantPath string
+	}{
+		{name: "set path", repo: &mockRepository{}, path: "/prod/db/", wantPath: "prod/db"},
+		{name: "remove path", repo: &mockRepository{}},
+		{name: "incorrect path", repo: &mockRepository{}, path: "prod db", wantErr: ErrIncorrectPath},
+		{
+			name:    "path taken",
+			repo:    &mockRepository{saveErr: repository.ErrPathTaken},
+			path:    "prod/db",
+			wantErr: ErrPathTaken,
+		},
+		{
+			name:    "repository failure",
+			repo:    &mockRepository{saveErr: errors.New("connection refused")},
+			path:    "prod/db",
+			wantErr: ErrItemPathTechError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := NewService(tt.repo).SetPath(context.Background(), SetParams{
+				Path:   tt.path,
+				ItemID: itemID,
+				UserID: userID,
+			})
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantPath, got.Path)
+			assert.Equal(t, tt.wantPath, tt.repo.saved.Path)
+		})
+	}
+}
+
+func TestService_GetPath(t *testing.T) {
+	t.Parallel()
+
+	userID, itemID := uuid.New(), uuid.New()
+
+	tests := []struct {
+		repo     *mockRepository
+		name     string
+		wantPath string
+	}{
+		{
+			name:     "item with path",
+			repo:     &mockRepository{stored: []*itempath.ItemPath{{ItemID: itemID, Path: "prod/db"}}},
+			wantPath: "prod/db",
+		},
+		{name: "item without path", repo: &mockRepository{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := NewService(tt.repo).GetPath(context.Background(), GetParams{ItemID: itemID, UserID: userID})
+
+			require.NoError(t, err)
+			assert.Equal(t, itemID, got.ItemID)
+			assert.Equal(t, tt.wantPath, got.Path)
+			assert.Equal(t, repository.LoadParams{UserID: userID, ItemID: itemID}, tt.repo.loadParams)
+		})
+	}
+}
+
+func TestService_ListPaths(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+
+	tests := []struct {
+		wantErr    error
+		name       string
+		prefix     string
+		wantPrefix string
+	}{
+		{name: "all paths"},
+		{name: "prefix", prefix: "prod/db/", wantPrefix: "prod/db"},
+		{name: "incorrect prefix", prefix: "prod/..", wantErr: ErrIncorrectPath},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &mockRepository{}
+			_, err := NewService(repo).ListPaths(context.Background(), ListParams{Prefix: tt.prefix, UserID: userID})
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, repository.LoadParams{UserID: userID, Prefix: tt.wantPrefix}, repo.loadParams)
+		})
+	}
+}
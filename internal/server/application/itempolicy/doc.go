@@ -0,0 +1,6 @@
+// Package itempolicy provides vault item policy application services for the AegisVaultKeeper server.
+//
+// This package implements management of the item policies administrators set on the vault items of every user
+// or of the members of a directory group, and the checks the item services run on created and updated items
+// against the merged policies of their owner.
+package itempolicy
@@ -0,0 +1,131 @@
+package itempolicy
+
+import (
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/auth"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/itempolicy"
+	"github.com/google/uuid"
+)
+
+// Rules represents the restrictions an item policy puts on vault items.
+type Rules struct {
+	// ForbiddenKinds lists the item types that may not be stored: bankcard, credential, file or note.
+	ForbiddenKinds []string
+	// RequiredClasses lists the character classes every credential password must contain: lower, upper,
+	// digit or symbol.
+	RequiredClasses []string
+	// MinPasswordLength specifies the minimum length of credential passwords in characters; zero sets none.
+	MinPasswordLength int
+	// BanCommonPasswords determines whether commonly used credential passwords are rejected.
+	BanCommonPasswords bool
+}
+
+// Policy represents an item policy data transfer object for application layer communication.
+type Policy struct {
+	// CreatedAt indicates when the policy was created.
+	CreatedAt time.Time
+	// UpdatedAt indicates when the policy was last changed.
+	UpdatedAt time.Time
+	// Name describes the policy.
+	Name string
+	// Rules contains the restrictions the policy puts on vault items.
+	Rules
+	// ID uniquely identifies the policy.
+	ID uuid.UUID
+	// GroupID identifies the group whose members the policy applies to; uuid.Nil when it applies to every user.
+	GroupID uuid.UUID
+}
+
+// newRulesFromDomain converts domain item policy rules to application DTO.
+func newRulesFromDomain(r itempolicy.Rules) Rules {
+	classes := make([]string, 0, len(r.RequiredClasses))
+	for _, c := range r.RequiredClasses {
+		classes = append(classes, string(c))
+	}
+	return Rules{
+		ForbiddenKinds:     append([]string{}, r.ForbiddenKinds...),
+		RequiredClasses:    classes,
+		MinPasswordLength:  r.MinPasswordLength,
+		BanCommonPasswords: r.BanCommonPasswords,
+	}
+}
+
+// rulesToDomain converts application item policy rules to the domain rules.
+func rulesToDomain(r Rules) itempolicy.Rules {
+	// classes holds the required character classes.
+	var classes []auth.PasswordClass
+	for _, c := range r.RequiredClasses {
+		classes = append(classes, auth.PasswordClass(c))
+	}
+	return itempolicy.Rules{
+		ForbiddenKinds:     r.ForbiddenKinds,
+		RequiredClasses:    classes,
+		MinPasswordLength:  r.MinPasswordLength,
+		BanCommonPasswords: r.BanCommonPasswords,
+	}
+}
+
+// newPolicyFromDomain converts a domain item policy entity to application DTO.
+func newPolicyFromDomain(p *itempolicy.Policy) *Policy {
+	if p == nil {
+		return nil
+	}
+	return &Policy{
+		ID:        p.ID,
+		GroupID:   p.GroupID,
+		Name:      p.Name,
+		Rules:     newRulesFromDomain(p.Rules),
+		CreatedAt: p.CreatedAt,
+		UpdatedAt: p.UpdatedAt,
+	}
+}
+
+// newPoliciesFromDomain converts a slice of domain item policy entities to application DTOs.
+func newPoliciesFromDomain(ps []*itempolicy.Policy) []*Policy {
+	result := make([]*Policy, 0, len(ps))
+	for _, p := range ps {
+		result = append(result, newPolicyFromDomain(p))
+	}
+	return result
+}
+
+// CreateParams contains parameters for creating an item policy.
+type CreateParams struct {
+	// Name describes the policy.
+	Name string
+	// Rules contains the restrictions the policy puts on vault items.
+	Rules Rules
+	// GroupID identifies the group whose members the policy applies to; uuid.Nil applies it to every user.
+	GroupID uuid.UUID
+}
+
+// UpdateParams contains parameters for replacing an item policy.
+type UpdateParams struct {
+	// Name describes the policy.
+	Name string
+	// Rules contains the restrictions the policy puts on vault items.
+	Rules Rules
+	// ID identifies the policy.
+	ID uuid.UUID
+	// GroupID identifies the group whose members the policy applies to; uuid.Nil applies it to every user.
+	GroupID uuid.UUID
+}
+
+// GetParams contains parameters for retrieving an item policy.
+type GetParams struct {
+	// ID identifies the policy.
+	ID uuid.UUID
+}
+
+// DeleteParams contains parameters for deleting an item policy.
+type DeleteParams struct {
+	// ID identifies the policy.
+	ID uuid.UUID
+}
+
+// EffectiveParams contains parameters for retrieving the rules applying to a user.
+type EffectiveParams struct {
+	// UserID identifies the user.
+	UserID uuid.UUID
+}
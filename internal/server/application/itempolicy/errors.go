@@ -0,0 +1,117 @@
+package itempolicy
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/errutil"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/itempolicy"
+	repositoryGroup "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/group"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/itempolicy"
+)
+
+// Item policy error definitions.
+var (
+	// ErrItemPolicyAppError indicates a general item policy application error.
+	ErrItemPolicyAppError = errors.New("item policy application error")
+
+	// ErrItemPolicyTechError indicates a technical error in the item policy system.
+	ErrItemPolicyTechError = errors.New("item policy technical error")
+
+	// ErrIncorrectName indicates the name of the policy is empty or too long.
+	ErrIncorrectName = errors.New("incorrect item policy name")
+
+	// ErrIncorrectKind indicates the policy forbids an unknown item type.
+	ErrIncorrectKind = errors.New("incorrect item type")
+
+	// ErrIncorrectMinPasswordLength indicates the minimum password length is out of range.
+	ErrIncorrectMinPasswordLength = errors.New("incorrect minimum password length")
+
+	// ErrIncorrectPasswordClass indicates the policy requires an unknown character class.
+	ErrIncorrectPasswordClass = errors.New("incorrect password character class")
+
+	// ErrNoRules indicates the policy restricts nothing.
+	ErrNoRules = errors.New("item policy has no rules")
+
+	// ErrGroupNotFound indicates the group of the policy does not exist.
+	ErrGroupNotFound = errors.New("group not found")
+
+	// ErrPolicyNotFound indicates the item policy does not exist.
+	ErrPolicyNotFound = errors.New("item policy not found")
+
+	// ErrItemKindForbidden indicates the item policies of the user forbid storing items of the type.
+	ErrItemKindForbidden = errors.New("item type forbidden by item policy")
+
+	// ErrWeakPassword indicates a credential password does not meet the item policies of the user.
+	ErrWeakPassword = errors.New("credential password does not meet item policy")
+
+	// ErrPasswordTooShort indicates a credential password is shorter than the item policies require.
+	ErrPasswordTooShort = errors.New("credential password is too short")
+
+	// ErrPasswordNoLower indicates a credential password lacks a required lower-case letter.
+	ErrPasswordNoLower = errors.New("credential password has no lower-case letter")
+
+	// ErrPasswordNoUpper indicates a credential password lacks a required upper-case letter.
+	ErrPasswordNoUpper = errors.New("credential password has no upper-case letter")
+
+	// ErrPasswordNoDigit indicates a credential password lacks a required digit.
+	ErrPasswordNoDigit = errors.New("credential password has no digit")
+
+	// ErrPasswordNoSymbol indicates a credential password lacks a required symbol.
+	ErrPasswordNoSymbol = errors.New("credential password has no symbol")
+
+	// ErrPasswordCommon indicates a credential password is a commonly used one.
+	ErrPasswordCommon = errors.New("credential password is too common")
+)
+
+// mapError maps domain and repository errors to application-level errors.
+func mapError(err error) error {
+	if err == nil {
+		return nil
+	}
+	mapped := errutil.MapError(mapFn, err)
+	if mapped != nil {
+		return fmt.Errorf("item policy error mapping failed: %w", mapped)
+	}
+	return nil
+}
+
+// mapFn provides the actual error mapping logic for different error types.
+func mapFn(err error) error {
+	switch {
+	case errors.Is(err, itempolicy.ErrNewPolicyParamsValidation):
+		return ErrItemPolicyAppError
+	case errors.Is(err, itempolicy.ErrIncorrectName):
+		return ErrIncorrectName
+	case errors.Is(err, itempolicy.ErrIncorrectKind):
+		return ErrIncorrectKind
+	case errors.Is(err, itempolicy.ErrIncorrectMinPasswordLength):
+		return ErrIncorrectMinPasswordLength
+	case errors.Is(err, itempolicy.ErrIncorrectPasswordClass):
+		return ErrIncorrectPasswordClass
+	case errors.Is(err, itempolicy.ErrNoRules):
+		return ErrNoRules
+	case errors.Is(err, repositoryGroup.ErrGroupNotFound):
+		return ErrGroupNotFound
+	case errors.Is(err, repository.ErrPolicyNotFound):
+		return ErrPolicyNotFound
+	case errors.Is(err, itempolicy.ErrKindForbidden):
+		return ErrItemKindForbidden
+	case errors.Is(err, itempolicy.ErrWeakPassword):
+		return ErrWeakPassword
+	case errors.Is(err, itempolicy.ErrPasswordTooShort):
+		return ErrPasswordTooShort
+	case errors.Is(err, itempolicy.ErrPasswordNoLower):
+		return ErrPasswordNoLower
+	case errors.Is(err, itempolicy.ErrPasswordNoUpper):
+		return ErrPasswordNoUpper
+	case errors.Is(err, itempolicy.ErrPasswordNoDigit):
+		return ErrPasswordNoDigit
+	case errors.Is(err, itempolicy.ErrPasswordNoSymbol):
+		return ErrPasswordNoSymbol
+	case errors.Is(err, itempolicy.ErrPasswordCommon):
+		return ErrPasswordCommon
+	default:
+		return errors.Join(ErrItemPolicyTechError, err)
+	}
+}
@@ -0,0 +1,261 @@
+package itempolicy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/audit"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/authz"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/group"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/itempolicy"
+	repositoryGroup "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/group"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/itempolicy"
+	"github.com/google/uuid"
+)
+
+// Repository defines the interface for item policy persistence operations.
+type Repository interface {
+	// Save creates or replaces an item policy.
+	Save(ctx context.Context, params repository.SaveParams) error
+	// Load retrieves the item policies matching the parameters.
+	Load(ctx context.Context, params repository.LoadParams) ([]*itempolicy.Policy, error)
+	// Delete removes an item policy.
+	Delete(ctx context.Context, params repository.DeleteParams) error
+}
+
+// GroupRepository defines the interface for loading directory groups with their members.
+type GroupRepository interface {
+	// Load retrieves groups with their members and the number of matching groups.
+	Load(ctx context.Context, params repositoryGroup.LoadParams) ([]*group.Group, int, error)
+}
+
+// AuditRecorder defines the interface for recording security audit events.
+type AuditRecorder interface {
+	// Record stores the audit event.
+	Record(ctx context.Context, e audit.Event)
+}
+
+// Service provides item policy operations.
+type Service struct {
+	// r is the repository interface for item policy persistence operations.
+	r Repository
+	// groups checks the groups of policies and resolves their members.
+	groups GroupRepository
+	// audit records policy changes.
+	audit AuditRecorder
+}
+
+// NewService creates a new item policy service instance.
+func NewService(r Repository, groups GroupRepository, audit AuditRecorder) *Service {
+	return &Service{r: r, groups: groups, audit: audit}
+}
+
+// List retrieves every item policy ordered by creation time.
+func (s *Service) List(ctx context.Context) ([]*Policy, error) {
+	policies, err := s.r.Load(ctx, repository.LoadParams{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load item policies: %w", mapError(err))
+	}
+	return newPoliciesFromDomain(policies), nil
+}
+
+// Get retrieves an item policy.
+func (s *Service) Get(ctx context.Context, params GetParams) (*Policy, error) {
+	p, err := s.load(ctx, params.ID)
+	if err != nil {
+		return nil, err
+	}
+	return newPolicyFromDomain(p), nil
+}
+
+// Create adds an item policy applying to every user or to the members of an existing directory group. Items
+// created or updated afterwards must meet it; items already stored are left as they are.
+func (s *Service) Create(ctx context.Context, params CreateParams) (*Policy, error) {
+	p, err := itempolicy.NewPolicy(itempolicy.NewPolicyParams{
+		Name:    params.Name,
+		Rules:   rulesToDomain(params.Rules),
+		GroupID: params.GroupID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create new item policy: %w", mapError(err))
+	}
+	if err := s.save(ctx, p); err != nil {
+		return nil, err
+	}
+
+	s.record(ctx, audit.EventItemPolicyCreated, p)
+	return newPolicyFromDomain(p), nil
+}
+
+// Update replaces the name, group and rules of an item policy.
+func (s *Service) Update(ctx context.Context, params UpdateParams) (*Policy, error) {
+	p, err := s.load(ctx, params.ID)
+	if err != nil {
+		return nil, err
+	}
+	err = p.Update(itempolicy.NewPolicyParams{
+		Name:    params.Name,
+		Rules:   rulesToDomain(params.Rules),
+		GroupID: params.GroupID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update item policy: %w", mapError(err))
+	}
+	if err := s.save(ctx, p); err != nil {
+		return nil, err
+	}
+
+	s.record(ctx, audit.EventItemPolicyUpdated, p)
+	return newPolicyFromDomain(p), nil
+}
+
+// Delete removes an item policy.
+func (s *Service) Delete(ctx context.Context, params DeleteParams) error {
+	p, err := s.load(ctx, params.ID)
+	if err != nil {
+		return err
+	}
+	if err := s.r.Delete(ctx, repository.DeleteParams{ID: p.ID}); err != nil {
+		return fmt.Errorf("failed to delete item policy: %w", mapError(err))
+	}
+
+	s.record(ctx, audit.EventItemPolicyDeleted, p)
+	return nil
+}
+
+// Effective retrieves the rules of the item policies applying to the user merged into one, so clients can check
+// items before storing them.
+func (s *Service) Effective(ctx context.Context, params EffectiveParams) (*Rules, error) {
+	rules, err := s.rules(ctx, params.UserID)
+	if err != nil {
+		return nil, err
+	}
+	effective := newRulesFromDomain(rules)
+	return &effective, nil
+}
+
+// CheckItem ensures that the item policies of the user allow storing items of the kind.
+func (s *Service) CheckItem(ctx context.Context, userID uuid.UUID, kind string) error {
+	rules, err := s.rules(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if err := rules.CheckKind(kind); err != nil {
+		return fmt.Errorf("%s items are forbidden: %w", kind, mapError(err))
+	}
+	return nil
+}
+
+// CheckCredential ensures that the item policies of the user allow storing credentials and that every password
+// meets them.
+func (s *Service) CheckCredential(ctx context.Context, userID uuid.UUID, passwords ...string) error {
+	rules, err := s.rules(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if err := rules.CheckKind(authz.KindCredential); err != nil {
+		return fmt.Errorf("credentials are forbidden: %w", mapError(err))
+	}
+	for i, password := range passwords {
+		if err := rules.CheckPassword(password); err != nil {
+			return fmt.Errorf("credential password at index %d rejected: %w", i, mapError(err))
+		}
+	}
+	return nil
+}
+
+// rules merges the rules of the policies applying to the user: those of every user and those of the groups the
+// user is a member of. Policies of groups removed from the directory are ignored.
+func (s *Service) rules(ctx context.Context, userID uuid.UUID) (itempolicy.Rules, error) {
+	policies, err := s.r.Load(ctx, repository.LoadParams{})
+	if err != nil {
+		return itempolicy.Rules{}, fmt.Errorf("failed to load item policies: %w", mapError(err))
+	}
+
+	// memberOf lists the groups of policies the user is a member of; checked caches the looked up groups.
+	var memberOf []uuid.UUID
+	checked := make(map[uuid.UUID]struct{})
+	// rules accumulates the rules of the applying policies.
+	var rules itempolicy.Rules
+	for _, p := range policies {
+		if _, ok := checked[p.GroupID]; p.GroupID != uuid.Nil && !ok {
+			checked[p.GroupID] = struct{}{}
+			member, err := s.isMember(ctx, p.GroupID, userID)
+			if err != nil {
+				return itempolicy.Rules{}, err
+			}
+			if member {
+				memberOf = append(memberOf, p.GroupID)
+			}
+		}
+		if p.AppliesTo(memberOf) {
+			rules = rules.Merge(p.Rules)
+		}
+	}
+	return rules, nil
+}
+
+// isMember reports whether the user is a member of the group; a group removed from the directory has no members.
+func (s *Service) isMember(ctx context.Context, groupID, userID uuid.UUID) (bool, error) {
+	groups, _, err := s.groups.Load(ctx, repositoryGroup.LoadParams{ID: groupID})
+	if errors.Is(err, repositoryGroup.ErrGroupNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to load group %s: %w", groupID, mapError(err))
+	}
+	for _, g := range groups {
+		if slices.Contains(g.Members, userID) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// load retrieves an item policy by ID.
+func (s *Service) load(ctx context.Context, id uuid.UUID) (*itempolicy.Policy, error) {
+	policies, err := s.r.Load(ctx, repository.LoadParams{ID: id})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load item policy: %w", mapError(err))
+	}
+	if len(policies) == 0 {
+		return nil, fmt.Errorf("item policy %s not found: %w", id, ErrPolicyNotFound)
+	}
+	return policies[0], nil
+}
+
+// save checks that the group of the policy exists and stores the policy.
+func (s *Service) save(ctx context.Context, p *itempolicy.Policy) error {
+	if p.GroupID != uuid.Nil {
+		if _, _, err := s.groups.Load(ctx, repositoryGroup.LoadParams{ID: p.GroupID}); err != nil {
+			return fmt.Errorf("failed to load group %s: %w", p.GroupID, mapError(err))
+		}
+	}
+	if err := s.r.Save(ctx, repository.SaveParams{Entity: p}); err != nil {
+		return fmt.Errorf("failed to save item policy: %w", mapError(err))
+	}
+	return nil
+}
+
+// record records an audit event describing the policy.
+func (s *Service) record(ctx context.Context, eventType string, p *itempolicy.Policy) {
+	details := map[string]string{"policy_id": p.ID.String(), "name": p.Name}
+	if p.GroupID != uuid.Nil {
+		details["group_id"] = p.GroupID.String()
+	}
+	if eventType != audit.EventItemPolicyDeleted {
+		classes := make([]string, 0, len(p.RequiredClasses))
+		for _, c := range p.RequiredClasses {
+			classes = append(classes, string(c))
+		}
+		details["forbidden_kinds"] = strings.Join(p.ForbiddenKinds, ",")
+		details["required_classes"] = strings.Join(classes, ",")
+		details["min_password_length"] = strconv.Itoa(p.MinPasswordLength)
+		details["ban_common_passwords"] = strconv.FormatBool(p.BanCommonPasswords)
+	}
+	s.audit.Record(ctx, audit.Event{Type: eventType, Details: details})
+}
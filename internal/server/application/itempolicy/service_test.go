@@ -0,0 +1,443 @@
+package itempolicy
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/audit"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/auth"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/authz"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/group"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/itempolicy"
+	repositoryGroup "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/group"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/itempolicy"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockRepository implements Repository for testing.
+type mockRepository struct {
+	loadErr   error
+	saveErr   error
+	deleteErr error
+	saved     *itempolicy.Policy
+	policies  []*itempolicy.Policy
+	deleted   uuid.UUID
+}
+
+func (m *mockRepository) Save(_ context.Context, params repository.SaveParams) error {
+	m.saved = params.Entity
+	return m.saveErr
+}
+
+func (m *mockRepository) Load(_ context.Context, params repository.LoadParams) ([]*itempolicy.Policy, error) {
+	if m.loadErr != nil {
+		return nil, m.loadErr
+	}
+	if params.ID == uuid.Nil {
+		return m.policies, nil
+	}
+	for _, p := range m.policies {
+		if p.ID == params.ID {
+			c := *p
+			return []*itempolicy.Policy{&c}, nil
+		}
+	}
+	return nil, repository.ErrPolicyNotFound
+}
+
+func (m *mockRepository) Delete(_ context.Context, params repository.DeleteParams) error {
+	m.deleted = params.ID
+	return m.deleteErr
+}
+
+// mockGroupRepository implements GroupRepository for testing.
+type mockGroupRepository struct {
+	err    error
+	groups map[uuid.UUID][]uuid.UUID
+}
+
+func (m *mockGroupRepository) Load(_ context.Context, params repositoryGroup.LoadParams) ([]*group.Group, int, error) {
+	if m.err != nil {
+		return nil, 0, m.err
+	}
+	members, ok := m.groups[params.ID]
+	if !ok {
+		return nil, 0, repositoryGroup.ErrGroupNotFound
+	}
+	return []*group.Group{{ID: params.ID, Members: members}}, 1, nil
+}
+
+// mockAuditRecorder implements AuditRecorder for testing.
+type mockAuditRecorder struct {
+	events []audit.Event
+}
+
+func (m *mockAuditRecorder) Record(_ context.Context, e audit.Event) {
+	m.events = append(m.events, e)
+}
+
+func TestService_Create(t *testing.T) {
+	t.Parallel()
+
+	groupID := uuid.New()
+	groups := &mockGroupRepository{groups: map[uuid.UUID][]uuid.UUID{groupID: nil}}
+
+	tests := []struct {
+		saveErr     error
+		wantErrs    []error
+		wantDetails map[string]string
+		name        string
+		params      CreateParams
+	}{
+		{
+			name: "policy of every user",
+			params: CreateParams{
+				Name:  "Everyone",
+				Rules: Rules{ForbiddenKinds: []string{"bankcard"}, RequiredClasses: []string{"upper", "digit"}},
+			},
+			wantDetails: map[string]string{
+				"name":                 "Everyone",
+				"forbidden_kinds":      "bankcard",
+				"required_classes":     "digit,upper",
+				"min_password_length":  "0",
+				"ban_common_passwords": "false",
+			},
+		},
+		{
+			name:   "policy of a group",
+			params: CreateParams{Name: "Finance", GroupID: groupID, Rules: Rules{MinPasswordLength: 16}},
+			wantDetails: map[string]string{
+				"name":                 "Finance",
+				"group_id":             groupID.String(),
+				"forbidden_kinds":      "",
+				"required_classes":     "",
+				"min_password_length":  "16",
+				"ban_common_passwords": "false",
+			},
+		},
+		{
+			name:     "unknown group",
+			params:   CreateParams{Name: "Finance", GroupID: uuid.New(), Rules: Rules{MinPasswordLength: 16}},
+			wantErrs: []error{ErrGroupNotFound},
+		},
+		{
+			name: "invalid rules",
+			params: CreateParams{
+				Name:  "",
+				Rules: Rules{ForbiddenKinds: []string{"acme"}, RequiredClasses: []string{"emoji"}, MinPasswordLength: -1},
+			},
+			wantErrs: []error{
+				ErrItemPolicyAppError, ErrIncorrectName, ErrIncorrectKind, ErrIncorrectPasswordClass,
+				ErrIncorrectMinPasswordLength,
+			},
+		},
+		{
+			name:     "no rules",
+			params:   CreateParams{Name: "Empty"},
+			wantErrs: []error{ErrNoRules},
+		},
+		{
+			name:     "save failure",
+			params:   CreateParams{Name: "Everyone", Rules: Rules{BanCommonPasswords: true}},
+			saveErr:  errors.New("connection lost"),
+			wantErrs: []error{ErrItemPolicyTechError},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &mockRepository{saveErr: tt.saveErr}
+			recorder := &mockAuditRecorder{}
+			s := NewService(repo, groups, recorder)
+
+			got, err := s.Create(context.Background(), tt.params)
+			if len(tt.wantErrs) != 0 {
+				for _, want := range tt.wantErrs {
+					require.ErrorIs(t, err, want)
+				}
+				if tt.saveErr == nil {
+					assert.NotErrorIs(t, err, ErrItemPolicyTechError)
+				}
+				assert.Empty(t, recorder.events)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, repo.saved.ID, got.ID)
+			assert.Equal(t, tt.params.GroupID, got.GroupID)
+			require.Len(t, recorder.events, 1)
+			assert.Equal(t, audit.EventItemPolicyCreated, recorder.events[0].Type)
+			tt.wantDetails["policy_id"] = got.ID.String()
+			assert.Equal(t, tt.wantDetails, recorder.events[0].Details)
+		})
+	}
+}
+
+func TestService_Update(t *testing.T) {
+	t.Parallel()
+
+	created := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	existing := &itempolicy.Policy{
+		ID:        uuid.New(),
+		Name:      "Everyone",
+		Rules:     itempolicy.Rules{BanCommonPasswords: true},
+		CreatedAt: created,
+		UpdatedAt: created,
+	}
+
+	tests := []struct {
+		wantErr error
+		name    string
+		params  UpdateParams
+	}{
+		{
+			name:   "replaces rules",
+			params: UpdateParams{ID: existing.ID, Name: "Everyone", Rules: Rules{ForbiddenKinds: []string{"note"}}},
+		},
+		{
+			name:    "unknown policy",
+			params:  UpdateParams{ID: uuid.New(), Name: "Everyone", Rules: Rules{BanCommonPasswords: true}},
+			wantErr: ErrPolicyNotFound,
+		},
+		{
+			name:    "invalid rules",
+			params:  UpdateParams{ID: existing.ID, Name: "Everyone"},
+			wantErr: ErrNoRules,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &mockRepository{policies: []*itempolicy.Policy{existing}}
+			recorder := &mockAuditRecorder{}
+			s := NewService(repo, &mockGroupRepository{}, recorder)
+
+			got, err := s.Update(context.Background(), tt.params)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, repo.saved)
+				assert.Empty(t, recorder.events)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, existing.ID, got.ID)
+			assert.Equal(t, created, got.CreatedAt)
+			assert.True(t, got.UpdatedAt.After(created))
+			assert.Equal(t, []string{"note"}, got.ForbiddenKinds)
+			assert.False(t, got.BanCommonPasswords)
+			require.Len(t, recorder.events, 1)
+			assert.Equal(t, audit.EventItemPolicyUpdated, recorder.events[0].Type)
+		})
+	}
+}
+
+func TestService_Delete(t *testing.T) {
+	t.Parallel()
+
+	existing := &itempolicy.Policy{ID: uuid.New(), Name: "Everyone"}
+
+	tests := []struct {
+		deleteErr error
+		wantErr   error
+		name      string
+		id        uuid.UUID
+	}{
+		{name: "deletes policy", id: existing.ID},
+		{name: "unknown policy", id: uuid.New(), wantErr: ErrPolicyNotFound},
+		{name: "delete failure", id: existing.ID, deleteErr: errors.New("connection lost"), wantErr: ErrItemPolicyTechError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &mockRepository{policies: []*itempolicy.Policy{existing}, deleteErr: tt.deleteErr}
+			recorder := &mockAuditRecorder{}
+			s := NewService(repo, &mockGroupRepository{}, recorder)
+
+			err := s.Delete(context.Background(), DeleteParams{ID: tt.id})
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Empty(t, recorder.events)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, existing.ID, repo.deleted)
+			require.Len(t, recorder.events, 1)
+			assert.Equal(t, audit.EventItemPolicyDeleted, recorder.events[0].Type)
+			assert.Equal(t, map[string]string{"policy_id": existing.ID.String(), "name": "Everyone"},
+				recorder.events[0].Details)
+		})
+	}
+}
+
+func TestService_ListGet(t *testing.T) {
+	t.Parallel()
+
+	p := &itempolicy.Policy{
+		ID:    uuid.New(),
+		Name:  "Everyone",
+		Rules: itempolicy.Rules{RequiredClasses: []auth.PasswordClass{auth.PasswordClassSymbol}},
+	}
+	s := NewService(&mockRepository{policies: []*itempolicy.Policy{p}}, &mockGroupRepository{}, &mockAuditRecorder{})
+
+	list, err := s.List(context.Background())
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, []string{"symbol"}, list[0].RequiredClasses)
+	assert.Equal(t, []string{}, list[0].ForbiddenKinds)
+
+	got, err := s.Get(context.Background(), GetParams{ID: p.ID})
+	require.NoError(t, err)
+	assert.Equal(t, list[0], got)
+
+	_, err = s.Get(context.Background(), GetParams{ID: uuid.New()})
+	require.ErrorIs(t, err, ErrPolicyNotFound)
+
+	_, err = NewService(
+		&mockRepository{loadErr: errors.New("connection lost")}, &mockGroupRepository{}, &mockAuditRecorder{},
+	).List(context.Background())
+	require.ErrorIs(t, err, ErrItemPolicyTechError)
+}
+
+func TestService_Checks(t *testing.T) {
+	t.Parallel()
+
+	member, outsider := uuid.New(), uuid.New()
+	financeID, removedID := uuid.New(), uuid.New()
+	policies := []*itempolicy.Policy{
+		{ID: uuid.New(), Rules: itempolicy.Rules{MinPasswordLength: 10}},
+		{
+			ID:      uuid.New(),
+			GroupID: financeID,
+			Rules: itempolicy.Rules{
+				ForbiddenKinds:  []string{authz.KindBankCard},
+				RequiredClasses: []auth.PasswordClass{auth.PasswordClassDigit},
+			},
+		},
+		{ID: uuid.New(), GroupID: removedID, Rules: itempolicy.Rules{ForbiddenKinds: []string{authz.KindNote}}},
+	}
+	groups := &mockGroupRepository{groups: map[uuid.UUID][]uuid.UUID{financeID: {member}}}
+
+	tests := []struct {
+		groupErr  error
+		check     func(s *Service) error
+		wantErrs  []error
+		name      string
+		noPolicy  bool
+		techError bool
+	}{
+		{
+			name:  "member may store notes",
+			check: func(s *Service) error { return s.CheckItem(context.Background(), member, authz.KindNote) },
+		},
+		{
+			name:     "member may not store bank cards",
+			check:    func(s *Service) error { return s.CheckItem(context.Background(), member, authz.KindBankCard) },
+			wantErrs: []error{ErrItemKindForbidden},
+		},
+		{
+			name:  "outsider may store bank cards",
+			check: func(s *Service) error { return s.CheckItem(context.Background(), outsider, authz.KindBankCard) },
+		},
+		{
+			name: "outsider password meets the policy of every user",
+			check: func(s *Service) error {
+				return s.CheckCredential(context.Background(), outsider, "long-enough")
+			},
+		},
+		{
+			name: "member password needs a digit",
+			check: func(s *Service) error {
+				return s.CheckCredential(context.Background(), member, "long-enough1", "long-enough")
+			},
+			wantErrs: []error{ErrWeakPassword, ErrPasswordNoDigit},
+		},
+		{
+			name: "short password",
+			check: func(s *Service) error {
+				return s.CheckCredential(context.Background(), outsider, "short")
+			},
+			wantErrs: []error{ErrWeakPassword, ErrPasswordTooShort},
+		},
+		{
+			name:     "no policies",
+			check:    func(s *Service) error { return s.CheckCredential(context.Background(), member, "1") },
+			noPolicy: true,
+		},
+		{
+			name:      "group lookup failure",
+			check:     func(s *Service) error { return s.CheckItem(context.Background(), member, authz.KindNote) },
+			groupErr:  errors.New("connection lost"),
+			techError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &mockRepository{policies: policies}
+			if tt.noPolicy {
+				repo.policies = nil
+			}
+			g := groups
+			if tt.groupErr != nil {
+				g = &mockGroupRepository{err: tt.groupErr}
+			}
+
+			err := tt.check(NewService(repo, g, &mockAuditRecorder{}))
+			switch {
+			case tt.techError:
+				require.ErrorIs(t, err, ErrItemPolicyTechError)
+			case len(tt.wantErrs) != 0:
+				for _, want := range tt.wantErrs {
+					require.ErrorIs(t, err, want)
+				}
+				assert.NotErrorIs(t, err, ErrItemPolicyTechError)
+			default:
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestService_Effective(t *testing.T) {
+	t.Parallel()
+
+	member, outsider, financeID := uuid.New(), uuid.New(), uuid.New()
+	repo := &mockRepository{policies: []*itempolicy.Policy{
+		{ID: uuid.New(), Rules: itempolicy.Rules{MinPasswordLength: 10}},
+		{
+			ID:      uuid.New(),
+			GroupID: financeID,
+			Rules: itempolicy.Rules{
+				ForbiddenKinds:     []string{authz.KindBankCard},
+				MinPasswordLength:  14,
+				BanCommonPasswords: true,
+			},
+		},
+	}}
+	s := NewService(repo, &mockGroupRepository{groups: map[uuid.UUID][]uuid.UUID{financeID: {member}}},
+		&mockAuditRecorder{})
+
+	got, err := s.Effective(context.Background(), EffectiveParams{UserID: member})
+	require.NoError(t, err)
+	assert.Equal(t, &Rules{
+		ForbiddenKinds:     []string{authz.KindBankCard},
+		RequiredClasses:    []string{},
+		MinPasswordLength:  14,
+		BanCommonPasswords: true,
+	}, got)
+
+	got, err = s.Effective(context.Background(), EffectiveParams{UserID: outsider})
+	require.NoError(t, err)
+	assert.Equal(t, &Rules{ForbiddenKinds: []string{}, RequiredClasses: []string{}, MinPasswordLength: 10}, got)
+}
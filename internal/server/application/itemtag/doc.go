@@ -0,0 +1,5 @@
+// Package itemtag provides item tag application services for the AegisVaultKeeper server.
+//
+// This package implements management of the tags users attach to their vault items, including
+// the restricted tag that limits the locations an item can be revealed from.
+package itemtag
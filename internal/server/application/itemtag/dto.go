@@ -0,0 +1,63 @@
+package itemtag
+
+import (
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/itemtag"
+	"github.com/google/uuid"
+)
+
+// ItemTags represents an item tag data transfer object for application layer communication.
+type ItemTags struct {
+	// UpdatedAt indicates when the tags were last changed.
+	UpdatedAt time.Time
+	// Tags contains the sorted tags of the item.
+	Tags []string
+	// ItemID identifies the tagged item.
+	ItemID uuid.UUID
+}
+
+// newItemTagsFromDomain converts a domain item tag entity to application DTO.
+func newItemTagsFromDomain(t *itemtag.ItemTags) *ItemTags {
+	if t == nil {
+		return nil
+	}
+	return &ItemTags{
+		ItemID:    t.ItemID,
+		Tags:      t.Tags,
+		UpdatedAt: t.UpdatedAt,
+	}
+}
+
+// newItemTagsListFromDomain converts a slice of domain item tag entities to application DTOs.
+func newItemTagsListFromDomain(ts []*itemtag.ItemTags) []*ItemTags {
+	result := make([]*ItemTags, 0, len(ts))
+	for _, t := range ts {
+		result = append(result, newItemTagsFromDomain(t))
+	}
+	return result
+}
+
+// ListParams contains parameters for listing the tagged items of a user.
+type ListParams struct {
+	// UserID identifies the user whose tagged items are listed.
+	UserID uuid.UUID
+}
+
+// GetParams contains parameters for retrieving the tags of an item.
+type GetParams struct {
+	// ItemID identifies the item.
+	ItemID uuid.UUID
+	// UserID identifies the user who owns the item.
+	UserID uuid.UUID
+}
+
+// SetParams contains parameters for replacing the tags of an item.
+type SetParams struct {
+	// Tags contains the new tags of the item; an empty set removes all tags.
+	Tags []string
+	// ItemID identifies the item.
+	ItemID uuid.UUID
+	// UserID identifies the user who owns the item.
+	UserID uuid.UUID
+}
@@ -0,0 +1,53 @@
+package itemtag
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/errutil"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/itemtag"
+)
+
+// Item tag error definitions.
+var (
+	// ErrItemTagAppError indicates a general item tag application error.
+	ErrItemTagAppError = errors.New("item tag application error")
+
+	// ErrItemTagTechError indicates a technical error in the item tag system.
+	ErrItemTagTechError = errors.New("item tag technical error")
+
+	// ErrIncorrectTag indicates a tag that is not a short lowercase label was provided.
+	ErrIncorrectTag = errors.New("incorrect item tag")
+
+	// ErrTooManyTags indicates that more tags than allowed were provided for an item.
+	ErrTooManyTags = errors.New("too many item tags")
+)
+
+// mapError maps domain and repository errors to application-level errors.
+func mapError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	mapped := errutil.MapError(mapFn, err)
+	if mapped != nil {
+		return fmt.Errorf("item tag error mapping failed: %w", mapped)
+	}
+	return nil
+}
+
+// mapFn provides the actual error mapping logic for different error types.
+func mapFn(err error) error {
+	switch {
+	case errors.Is(err, itemtag.ErrNewItemTagsParamsValidation):
+		return ErrItemTagAppError
+	case errors.Is(err, itemtag.ErrIncorrectTag):
+		return ErrIncorrectTag
+	case errors.Is(err, itemtag.ErrTooManyTags):
+		return ErrTooManyTags
+	case errors.Is(err, itemtag.ErrIncorrectItem):
+		return ErrItemTagAppError
+	default:
+		return errors.Join(ErrItemTagTechError, err)
+	}
+}
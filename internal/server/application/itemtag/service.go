@@ -0,0 +1,102 @@
+package itemtag
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/audit"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/itemtag"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/itemtag"
+	"github.com/google/uuid"
+)
+
+// Repository defines the interface for item tag persistence operations.
+type Repository interface {
+	// Save persists the tags of an item using the provided parameters.
+	Save(ctx context.Context, params repository.SaveParams) error
+	// Load retrieves item tags using the provided parameters.
+	Load(ctx context.Context, params repository.LoadParams) ([]*itemtag.ItemTags, error)
+}
+
+// AuditRecorder defines the interface for recording security audit events.
+type AuditRecorder interface {
+	// Record stores the audit event.
+	Record(ctx context.Context, e audit.Event)
+}
+
+// Service provides item tag operations.
+type Service struct {
+	// r is the repository interface for item tag persistence operations.
+	r Repository
+	// audit records changes of the restricted tag.
+	audit AuditRecorder
+}
+
+// NewService creates a new item tag service instance.
+func NewService(r Repository, audit AuditRecorder) *Service {
+	return &Service{r: r, audit: audit}
+}
+
+// ListTags retrieves the tagged items of the user, most recently tagged first.
+func (s *Service) ListTags(ctx context.Context, params ListParams) ([]*ItemTags, error) {
+	tags, err := s.r.Load(ctx, repository.LoadParams{UserID: params.UserID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load item tags: %w", mapError(err))
+	}
+	return newItemTagsListFromDomain(tags), nil
+}
+
+// GetTags retrieves the tags of an item; an untagged item has an empty tag set.
+func (s *Service) GetTags(ctx context.Context, params GetParams) (*ItemTags, error) {
+	current, err := s.load(ctx, params.UserID, params.ItemID)
+	if err != nil {
+		return nil, err
+	}
+	return newItemTagsFromDomain(current), nil
+}
+
+// SetTags replaces the tags of an item. Adding or removing the restricted tag is audited.
+func (s *Service) SetTags(ctx context.Context, params SetParams) (*ItemTags, error) {
+	tags, err := itemtag.NewItemTags(itemtag.NewItemTagsParams{
+		Tags:   params.Tags,
+		ItemID: params.ItemID,
+		UserID: params.UserID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create item tags: %w", mapError(err))
+	}
+
+	current, err := s.load(ctx, params.UserID, params.ItemID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.r.Save(ctx, repository.SaveParams{Entity: tags}); err != nil {
+		return nil, fmt.Errorf("failed to save item tags: %w", mapError(err))
+	}
+
+	if restricted := tags.Has(itemtag.Restricted); restricted != current.Has(itemtag.Restricted) {
+		s.audit.Record(ctx, audit.Event{
+			Type:   audit.EventItemRestrictionChanged,
+			UserID: params.UserID,
+			Details: map[string]string{
+				"item_id":    params.ItemID.String(),
+				"restricted": strconv.FormatBool(restricted),
+			},
+		})
+	}
+	return newItemTagsFromDomain(tags), nil
+}
+
+// load retrieves the stored tags of an item, returning an empty tag set for untagged items.
+func (s *Service) load(ctx context.Context, userID, itemID uuid.UUID) (*itemtag.ItemTags, error) {
+	stored, err := s.r.Load(ctx, repository.LoadParams{UserID: userID, ItemID: itemID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load item tags: %w", mapError(err))
+	}
+	if len(stored) == 0 {
+		return &itemtag.ItemTags{ItemID: itemID, UserID: userID, Tags: []string{}}, nil
+	}
+	return stored[0], nil
+}
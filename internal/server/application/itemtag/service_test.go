@@ -0,0 +1,178 @@
+package itemtag
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/audit"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/itemtag"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/itemtag"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockRepository implements Repository for testing.
+type mockRepository struct {
+	loadErr    error
+	saveErr    error
+	saved      *itemtag.ItemTags
+	loadParams repository.LoadParams
+	stored     []*itemtag.ItemTags
+}
+
+func (m *mockRepository) Save(_ context.Context, params repository.SaveParams) error {
+	m.saved = params.Entity
+	return m.saveErr
+}
+
+func (m *mockRepository) Load(_ context.Context, params repository.LoadParams) ([]*itemtag.ItemTags, error) {
+	m.loadParams = params
+	return m.stored, m.loadErr
+}
+
+// mockAuditRecorder implements AuditRecorder for testing.
+type mockAuditRecorder struct {
+	events []audit.Event
+}
+
+func (m *mockAuditRecorder) Record(_ context.Context, e audit.Event) {
+	m.events = append(m.events, e)
+}
+
+func TestService_SetTags(t *testing.T) {
+	t.Parallel()
+
+	userID, itemID := uuid.New(), uuid.New()
+	restricted := &itemtag.ItemTags{ItemID: itemID, UserID: userID, Tags: []string{itemtag.Restricted}}
+
+	tests := []struct {
+		repo           *mockRepository
+		wantErr        error
+		name           string
+		wantRestricted string
+		tags           []string
+		wantTags       []string
+	}{
+		{
+			name:           "restrict item",
+			repo:           &mockRepository{},
+			tags:           []string{"Restricted", "work"},
+			wantTags:       []string{itemtag.Restricted, "work"},
+			wantRestricted: "true",
+		},
+		{
+			name:           "lift restriction",
+			repo:           &mockRepository{stored: []*itemtag.ItemTags{restricted}},
+			tags:           []string{"work"},
+			wantTags:       []string{"work"},
+			wantRestricted: "false",
+		},
+		{
+			name:     "keep restriction",
+			repo:     &mockRepository{stored: []*itemtag.ItemTags{restricted}},
+			tags:     []string{itemtag.Restricted, "tax"},
+			wantTags: []string{itemtag.Restricted, "tax"},
+		},
+		{
+			name:    "incorrect tag",
+			repo:    &mockRepository{},
+			tags:    []string{"top secret"},
+			wantErr: ErrIncorrectTag,
+		},
+		{
+			name:    "load failure",
+			repo:    &mockRepository{loadErr: errors.New("connection refused")},
+			tags:    []string{"work"},
+			wantErr: ErrItemTagTechError,
+		},
+		{
+			name:    "save failure",
+			repo:    &mockRepository{saveErr: errors.New("connection refused")},
+			tags:    []string{"work"},
+			wantErr: ErrItemTagTechError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			recorder := &mockAuditRecorder{}
+			got, err := NewService(tt.repo, recorder).SetTags(context.Background(), SetParams{
+				Tags:   tt.tags,
+				ItemID: itemID,
+				UserID: userID,
+			})
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, got)
+				assert.Empty(t, recorder.events)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantTags, got.Tags)
+			assert.Equal(t, tt.wantTags, tt.repo.saved.Tags)
+			assert.Equal(t, repository.LoadParams{UserID: userID, ItemID: itemID}, tt.repo.loadParams)
+			if tt.wantRestricted == "" {
+				assert.Empty(t, recorder.events)
+				return
+			}
+			require.Len(t, recorder.events, 1)
+			assert.Equal(t, audit.EventItemRestrictionChanged, recorder.events[0].Type)
+			assert.Equal(t, tt.wantRestricted, recorder.events[0].Details["restricted"])
+		})
+	}
+}
+
+func TestService_GetTags(t *testing.T) {
+	t.Parallel()
+
+	userID, itemID := uuid.New(), uuid.New()
+
+	tests := []struct {
+		repo     *mockRepository
+		name     string
+		wantTags []string
+	}{
+		{name: "untagged item", repo: &mockRepository{}, wantTags: []string{}},
+		{
+			name: "tagged item",
+			repo: &mockRepository{stored: []*itemtag.ItemTags{
+				{ItemID: itemID, UserID: userID, Tags: []string{"work"}},
+			}},
+			wantTags: []string{"work"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := NewService(tt.repo, &mockAuditRecorder{}).GetTags(context.Background(), GetParams{
+				ItemID: itemID,
+				UserID: userID,
+			})
+
+			require.NoError(t, err)
+			assert.Equal(t, itemID, got.ItemID)
+			assert.Equal(t, tt.wantTags, got.Tags)
+		})
+	}
+}
+
+func TestService_ListTags(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	repo := &mockRepository{stored: []*itemtag.ItemTags{{ItemID: uuid.New(), UserID: userID, Tags: []string{"work"}}}}
+
+	got, err := NewService(repo, &mockAuditRecorder{}).ListTags(context.Background(), ListParams{UserID: userID})
+
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, []string{"work"}, got[0].Tags)
+	assert.Equal(t, repository.LoadParams{UserID: userID}, repo.loadParams)
+}
@@ -0,0 +1,5 @@
+// Package legalhold provides legal hold application services for the AegisVaultKeeper server.
+//
+// This package implements placing legal holds on the data of users or on single items, releasing them with an
+// audit trail, and resolving the held users and items the deletion paths of the server must leave intact.
+package legalhold
@@ -0,0 +1,89 @@
+package legalhold
+
+import (
+	"slices"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/legalhold"
+	"github.com/google/uuid"
+)
+
+// Hold represents a legal hold data transfer object for application layer communication.
+type Hold struct {
+	// PlacedAt indicates when the hold was placed.
+	PlacedAt time.Time
+	// Reason describes the matter the data is preserved for.
+	Reason string
+	// ID uniquely identifies the hold.
+	ID uuid.UUID
+	// UserID identifies the user whose data is held.
+	UserID uuid.UUID
+	// ItemID identifies the held item; uuid.Nil when every item of the user is held.
+	ItemID uuid.UUID
+}
+
+// newHoldFromDomain converts a domain legal hold entity to application DTO.
+func newHoldFromDomain(h *legalhold.Hold) *Hold {
+	if h == nil {
+		return nil
+	}
+	return &Hold{
+		ID:       h.ID,
+		UserID:   h.UserID,
+		ItemID:   h.ItemID,
+		Reason:   h.Reason,
+		PlacedAt: h.PlacedAt,
+	}
+}
+
+// newHoldsFromDomain converts a slice of domain legal hold entities to application DTOs.
+func newHoldsFromDomain(hs []*legalhold.Hold) []*Hold {
+	result := make([]*Hold, 0, len(hs))
+	for _, h := range hs {
+		result = append(result, newHoldFromDomain(h))
+	}
+	return result
+}
+
+// Held represents the users and items under legal hold whose data must not be permanently deleted.
+type Held struct {
+	// UserIDs lists the users all of whose data is held; nil when none is.
+	UserIDs []uuid.UUID
+	// ItemIDs lists the single items held; nil when none is.
+	ItemIDs []uuid.UUID
+}
+
+// Covers reports whether the data of the user, or any of the items, is held.
+func (h *Held) Covers(userID uuid.UUID, itemIDs ...uuid.UUID) bool {
+	if slices.Contains(h.UserIDs, userID) {
+		return true
+	}
+	for _, id := range itemIDs {
+		if slices.Contains(h.ItemIDs, id) {
+			return true
+		}
+	}
+	return false
+}
+
+// PlaceParams contains parameters for placing a legal hold.
+type PlaceParams struct {
+	// Reason describes the matter the data is preserved for.
+	Reason string
+	// UserID identifies the user whose data is held.
+	UserID uuid.UUID
+	// ItemID identifies the held item; uuid.Nil holds every item of the user.
+	ItemID uuid.UUID
+}
+
+// ReleaseParams contains parameters for releasing a legal hold.
+type ReleaseParams struct {
+	// ID identifies the hold.
+	ID uuid.UUID
+}
+
+// ListParams contains parameters for listing legal holds.
+type ListParams struct {
+	// UserID restricts the list to the holds on the data of this user when set.
+	UserID uuid.UUID
+}
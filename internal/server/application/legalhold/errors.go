@@ -0,0 +1,57 @@
+package legalhold
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/errutil"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/legalhold"
+	repositoryAuth "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/auth"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/legalhold"
+)
+
+// Legal hold error definitions.
+var (
+	// ErrLegalHoldAppError indicates a general legal hold application error.
+	ErrLegalHoldAppError = errors.New("legal hold application error")
+
+	// ErrLegalHoldTechError indicates a technical error in the legal hold system.
+	ErrLegalHoldTechError = errors.New("legal hold technical error")
+
+	// ErrIncorrectReason indicates the reason of the hold is empty or too long.
+	ErrIncorrectReason = errors.New("incorrect legal hold reason")
+
+	// ErrUserNotFound indicates the user whose data is to be held does not exist.
+	ErrUserNotFound = errors.New("user not found")
+
+	// ErrHoldNotFound indicates the legal hold does not exist.
+	ErrHoldNotFound = errors.New("legal hold not found")
+)
+
+// mapError maps domain and repository errors to application-level errors.
+func mapError(err error) error {
+	if err == nil {
+		return nil
+	}
+	mapped := errutil.MapError(mapFn, err)
+	if mapped != nil {
+		return fmt.Errorf("legal hold error mapping failed: %w", mapped)
+	}
+	return nil
+}
+
+// mapFn provides the actual error mapping logic for different error types.
+func mapFn(err error) error {
+	switch {
+	case errors.Is(err, legalhold.ErrNewHoldParamsValidation):
+		return ErrLegalHoldAppError
+	case errors.Is(err, legalhold.ErrIncorrectReason):
+		return ErrIncorrectReason
+	case errors.Is(err, legalhold.ErrIncorrectUserID), errors.Is(err, repositoryAuth.ErrUserNotFound):
+		return ErrUserNotFound
+	case errors.Is(err, repository.ErrHoldNotFound):
+		return ErrHoldNotFound
+	default:
+		return errors.Join(ErrLegalHoldTechError, err)
+	}
+}
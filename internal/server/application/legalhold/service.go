@@ -0,0 +1,150 @@
+package legalhold
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/audit"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/auth"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/legalhold"
+	repositoryAuth "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/auth"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/legalhold"
+	"github.com/google/uuid"
+)
+
+// Repository defines the interface for legal hold persistence operations.
+type Repository interface {
+	// Save stores a new legal hold.
+	Save(ctx context.Context, params repository.SaveParams) error
+	// Load retrieves the legal holds matching the parameters.
+	Load(ctx context.Context, params repository.LoadParams) ([]*legalhold.Hold, error)
+	// Delete removes a legal hold.
+	Delete(ctx context.Context, params repository.DeleteParams) error
+}
+
+// UserRepository defines the interface for loading users.
+type UserRepository interface {
+	// Load retrieves user data using the provided parameters.
+	Load(ctx context.Context, params repositoryAuth.LoadParams) (*auth.User, error)
+}
+
+// AuditRecorder defines the interface for recording security audit events.
+type AuditRecorder interface {
+	// Record stores the audit event.
+	Record(ctx context.Context, e audit.Event)
+}
+
+// Service provides legal hold operations.
+type Service struct {
+	// r is the repository interface for legal hold persistence operations.
+	r Repository
+	// users checks that held users exist.
+	users UserRepository
+	// audit records hold placement and release.
+	audit AuditRecorder
+}
+
+// NewService creates a new legal hold service instance.
+func NewService(r Repository, users UserRepository, audit AuditRecorder) *Service {
+	return &Service{r: r, users: users, audit: audit}
+}
+
+// Place puts the data of an existing user, or a single item of the user, on legal hold. Held data is left
+// intact by purges, retention enforcement and version history pruning until the hold is released.
+func (s *Service) Place(ctx context.Context, params PlaceParams) (*Hold, error) {
+	h, err := legalhold.NewHold(legalhold.NewHoldParams{
+		UserID: params.UserID,
+		ItemID: params.ItemID,
+		Reason: params.Reason,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create new legal hold: %w", mapError(err))
+	}
+	if _, err := s.users.Load(ctx, repositoryAuth.LoadParams{ID: h.UserID}); err != nil {
+		return nil, fmt.Errorf("failed to load user %s: %w", h.UserID, mapError(err))
+	}
+
+	if err := s.r.Save(ctx, repository.SaveParams{Entity: h}); err != nil {
+		return nil, fmt.Errorf("failed to save legal hold: %w", mapError(err))
+	}
+
+	s.audit.Record(ctx, audit.Event{
+		Type:    audit.EventLegalHoldPlaced,
+		UserID:  h.UserID,
+		Details: holdDetails(h),
+	})
+	return newHoldFromDomain(h), nil
+}
+
+// Release lifts a legal hold, so the data it covered can be deleted again unless another hold covers it.
+func (s *Service) Release(ctx context.Context, params ReleaseParams) error {
+	holds, err := s.r.Load(ctx, repository.LoadParams{ID: params.ID})
+	if err != nil {
+		return fmt.Errorf("failed to load legal hold: %w", mapError(err))
+	}
+	if err := s.r.Delete(ctx, repository.DeleteParams{ID: params.ID}); err != nil {
+		return fmt.Errorf("failed to delete legal hold: %w", mapError(err))
+	}
+
+	for _, h := range holds {
+		s.audit.Record(ctx, audit.Event{
+			Type:    audit.EventLegalHoldReleased,
+			UserID:  h.UserID,
+			Details: holdDetails(h),
+		})
+	}
+	return nil
+}
+
+// List retrieves the legal holds, optionally of one user, ordered by placement time.
+func (s *Service) List(ctx context.Context, params ListParams) ([]*Hold, error) {
+	holds, err := s.r.Load(ctx, repository.LoadParams{UserID: params.UserID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load legal holds: %w", mapError(err))
+	}
+	return newHoldsFromDomain(holds), nil
+}
+
+// Held resolves the users and items currently under legal hold.
+func (s *Service) Held(ctx context.Context) (*Held, error) {
+	holds, err := s.r.Load(ctx, repository.LoadParams{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load legal holds: %w", mapError(err))
+	}
+
+	// users and items collect the distinct held users and items.
+	users := make(map[uuid.UUID]struct{})
+	items := make(map[uuid.UUID]struct{})
+	for _, h := range holds {
+		if h.ItemID == uuid.Nil {
+			users[h.UserID] = struct{}{}
+		} else {
+			items[h.ItemID] = struct{}{}
+		}
+	}
+	held := &Held{}
+	if len(users) > 0 {
+		held.UserIDs = slices.SortedFunc(maps.Keys(users), compareIDs)
+	}
+	if len(items) > 0 {
+		held.ItemIDs = slices.SortedFunc(maps.Keys(items), compareIDs)
+	}
+	return held, nil
+}
+
+// holdDetails returns the audit event details describing the hold.
+func holdDetails(h *legalhold.Hold) map[string]string {
+	details := map[string]string{"hold_id": h.ID.String(), "reason": h.Reason}
+	if h.ItemID != uuid.Nil {
+		details["item_id"] = h.ItemID.String()
+	}
+	return details
+}
+
+// compareIDs orders identifiers by their string form.
+func compareIDs(a, b uuid.UUID) int {
+	return strings.Compare(a.String(), b.String())
+}
@@ -0,0 +1,243 @@
+package legalhold
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/audit"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/auth"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/legalhold"
+	repositoryAuth "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/auth"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/legalhold"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockRepository implements Repository for testing.
+type mockRepository struct {
+	loadErr      error
+	saveErr      error
+	deleteErr    error
+	saved        *legalhold.Hold
+	holds        []*legalhold.Hold
+	loadParams   []repository.LoadParams
+	deleteParams repository.DeleteParams
+}
+
+func (m *mockRepository) Save(_ context.Context, params repository.SaveParams) error {
+	m.saved = params.Entity
+	return m.saveErr
+}
+
+func (m *mockRepository) Load(_ context.Context, params repository.LoadParams) ([]*legalhold.Hold, error) {
+	m.loadParams = append(m.loadParams, params)
+	return m.holds, m.loadErr
+}
+
+func (m *mockRepository) Delete(_ context.Context, params repository.DeleteParams) error {
+	m.deleteParams = params
+	return m.deleteErr
+}
+
+// mockUserRepository implements UserRepository for testing.
+type mockUserRepository struct {
+	err   error
+	users map[uuid.UUID]bool
+}
+
+func (m *mockUserRepository) Load(_ context.Context, params repositoryAuth.LoadParams) (*auth.User, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	if !m.users[params.ID] {
+		return nil, repositoryAuth.ErrUserNotFound
+	}
+	return &auth.User{ID: params.ID}, nil
+}
+
+// mockAuditRecorder implements AuditRecorder for testing.
+type mockAuditRecorder struct {
+	events []audit.Event
+}
+
+func (m *mockAuditRecorder) Record(_ context.Context, e audit.Event) {
+	m.events = append(m.events, e)
+}
+
+func TestService_Place(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	itemID := uuid.New()
+	users := &mockUserRepository{users: map[uuid.UUID]bool{userID: true}}
+
+	tests := []struct {
+		userErr     error
+		saveErr     error
+		wantErr     error
+		wantDetails map[string]string
+		name        string
+		params      PlaceParams
+	}{
+		{
+			name:        "hold on a user",
+			params:      PlaceParams{UserID: userID, Reason: "Case 42"},
+			wantDetails: map[string]string{"reason": "Case 42"},
+		},
+		{
+			name:        "hold on an item",
+			params:      PlaceParams{UserID: userID, ItemID: itemID, Reason: "Case 42"},
+			wantDetails: map[string]string{"reason": "Case 42", "item_id": itemID.String()},
+		},
+		{
+			name:    "unknown user",
+			params:  PlaceParams{UserID: uuid.New(), Reason: "Case 42"},
+			wantErr: ErrUserNotFound,
+		},
+		{
+			name:    "missing user",
+			params:  PlaceParams{Reason: "Case 42"},
+			wantErr: ErrUserNotFound,
+		},
+		{
+			name:    "blank reason",
+			params:  PlaceParams{UserID: userID},
+			wantErr: ErrIncorrectReason,
+		},
+		{
+			name:    "user lookup error",
+			params:  PlaceParams{UserID: userID, Reason: "Case 42"},
+			userErr: errors.New("connection refused"),
+			wantErr: ErrLegalHoldTechError,
+		},
+		{
+			name:    "save error",
+			params:  PlaceParams{UserID: userID, Reason: "Case 42"},
+			saveErr: errors.New("connection refused"),
+			wantErr: ErrLegalHoldTechError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &mockRepository{saveErr: tt.saveErr}
+			recorder := &mockAuditRecorder{}
+			s := NewService(repo, users, recorder)
+			if tt.userErr != nil {
+				s = NewService(repo, &mockUserRepository{err: tt.userErr}, recorder)
+			}
+
+			h, err := s.Place(context.Background(), tt.params)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, h)
+				assert.Empty(t, recorder.events)
+				return
+			}
+			require.NoError(t, err)
+			require.NotNil(t, repo.saved)
+			assert.Equal(t, repo.saved.ID, h.ID)
+			assert.Equal(t, tt.params.ItemID, h.ItemID)
+			require.Len(t, recorder.events, 1)
+			assert.Equal(t, audit.EventLegalHoldPlaced, recorder.events[0].Type)
+			assert.Equal(t, userID, recorder.events[0].UserID)
+			tt.wantDetails["hold_id"] = h.ID.String()
+			assert.Equal(t, tt.wantDetails, recorder.events[0].Details)
+		})
+	}
+}
+
+func TestService_Release(t *testing.T) {
+	t.Parallel()
+
+	hold := &legalhold.Hold{ID: uuid.New(), UserID: uuid.New(), Reason: "Case 42"}
+
+	tests := []struct {
+		loadErr   error
+		deleteErr error
+		wantErr   error
+		name      string
+	}{
+		{name: "released"},
+		{name: "unknown hold", loadErr: repository.ErrHoldNotFound, wantErr: ErrHoldNotFound},
+		{name: "already released", deleteErr: repository.ErrHoldNotFound, wantErr: ErrHoldNotFound},
+		{name: "delete error", deleteErr: errors.New("connection refused"), wantErr: ErrLegalHoldTechError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &mockRepository{holds: []*legalhold.Hold{hold}, loadErr: tt.loadErr, deleteErr: tt.deleteErr}
+			recorder := &mockAuditRecorder{}
+
+			err := NewService(repo, &mockUserRepository{}, recorder).Release(
+				context.Background(), ReleaseParams{ID: hold.ID},
+			)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Empty(t, recorder.events)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, repository.DeleteParams{ID: hold.ID}, repo.deleteParams)
+			require.Len(t, recorder.events, 1)
+			assert.Equal(t, audit.EventLegalHoldReleased, recorder.events[0].Type)
+			assert.Equal(t, hold.UserID, recorder.events[0].UserID)
+			assert.Equal(t, hold.ID.String(), recorder.events[0].Details["hold_id"])
+		})
+	}
+}
+
+func TestService_List(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	hold := &legalhold.Hold{ID: uuid.New(), UserID: userID, Reason: "Case 42"}
+	repo := &mockRepository{holds: []*legalhold.Hold{hold}}
+
+	holds, err := NewService(repo, &mockUserRepository{}, &mockAuditRecorder{}).List(
+		context.Background(), ListParams{UserID: userID},
+	)
+
+	require.NoError(t, err)
+	require.Len(t, holds, 1)
+	assert.Equal(t, hold.ID, holds[0].ID)
+	assert.Equal(t, []repository.LoadParams{{UserID: userID}}, repo.loadParams)
+
+	repo = &mockRepository{loadErr: errors.New("connection refused")}
+	_, err = NewService(repo, &mockUserRepository{}, &mockAuditRecorder{}).List(context.Background(), ListParams{})
+	assert.ErrorIs(t, err, ErrLegalHoldTechError)
+}
+
+func TestService_Held(t *testing.T) {
+	t.Parallel()
+
+	userID, otherID := uuid.New(), uuid.New()
+	itemID := uuid.New()
+	repo := &mockRepository{holds: []*legalhold.Hold{
+		{ID: uuid.New(), UserID: userID},
+		{ID: uuid.New(), UserID: userID},
+		{ID: uuid.New(), UserID: otherID, ItemID: itemID},
+	}}
+
+	held, err := NewService(repo, &mockUserRepository{}, &mockAuditRecorder{}).Held(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{userID}, held.UserIDs)
+	assert.Equal(t, []uuid.UUID{itemID}, held.ItemIDs)
+	assert.True(t, held.Covers(userID))
+	assert.True(t, held.Covers(otherID, uuid.New(), itemID))
+	assert.False(t, held.Covers(otherID, uuid.New()))
+
+	held, err = NewService(&mockRepository{}, &mockUserRepository{}, &mockAuditRecorder{}).Held(context.Background())
+	require.NoError(t, err)
+	assert.Nil(t, held.UserIDs)
+	assert.Nil(t, held.ItemIDs)
+}
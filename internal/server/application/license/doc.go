@@ -0,0 +1,7 @@
+// Package license provides enterprise license application services for the AegisVaultKeeper server.
+//
+// This package verifies the license keys installed by administrators and answers whether enterprise
+// features are available. With a vendor public key configured, single sign-on, SCIM provisioning, audit
+// exports and organization vaults require a license covering them. Expired licenses keep their features for
+// a grace period; afterwards the features are switched off without touching any stored data.
+package license
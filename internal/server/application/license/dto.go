@@ -0,0 +1,59 @@
+package license
+
+import (
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/license"
+)
+
+// StateNone reports that no license is installed.
+const StateNone = "none"
+
+// Status represents the installed license and the enterprise features it makes available.
+type Status struct {
+	// IssuedAt is the time the license was issued.
+	IssuedAt time.Time
+	// ExpiresAt is the time the license expires; zero for perpetual licenses.
+	ExpiresAt time.Time
+	// GraceUntil is the time the features of the expired license are switched off; zero for perpetual licenses.
+	GraceUntil time.Time
+	// ID identifies the license.
+	ID string
+	// Licensee names the organization the license is issued to.
+	Licensee string
+	// State contains the state of the license, such as license.StateGrace, or StateNone.
+	State string
+	// Features lists the features the license covers.
+	Features []string
+	// Available lists the enterprise features available now.
+	Available []string
+	// Enforced reports whether enterprise features require a license; without a vendor public key they do not.
+	Enforced bool
+}
+
+// newStatus builds the status of the license at the moment; l may be nil when no license is installed.
+func newStatus(l *license.License, at time.Time, grace time.Duration) *Status {
+	st := &Status{State: StateNone, Available: []string{}, Enforced: true}
+	if l == nil {
+		return st
+	}
+	st.IssuedAt = l.IssuedAt
+	st.ExpiresAt = l.ExpiresAt
+	st.GraceUntil = l.GraceUntil(grace)
+	st.ID = l.ID
+	st.Licensee = l.Licensee
+	st.State = l.State(at, grace)
+	st.Features = l.Features
+	for _, feature := range license.Features {
+		if l.Allows(feature, at, grace) {
+			st.Available = append(st.Available, feature)
+		}
+	}
+	return st
+}
+
+// InstallParams contains parameters for installing a license key.
+type InstallParams struct {
+	// Key contains the license key as issued.
+	Key string
+}
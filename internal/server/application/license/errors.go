@@ -0,0 +1,21 @@
+package license
+
+import "errors"
+
+// License error definitions.
+var (
+	// ErrLicenseNotConfigured indicates that no vendor public key is configured to verify license keys with.
+	ErrLicenseNotConfigured = errors.New("license verification not configured")
+
+	// ErrLicenseInvalid indicates that the license key is malformed or not signed by the vendor.
+	ErrLicenseInvalid = errors.New("license key invalid")
+
+	// ErrLicenseExpired indicates that the license expired and its grace period ended.
+	ErrLicenseExpired = errors.New("license expired")
+
+	// ErrLicenseFeatureUnavailable indicates that no valid license covers an enterprise feature.
+	ErrLicenseFeatureUnavailable = errors.New("feature requires an enterprise license")
+
+	// ErrLicenseTechError indicates a technical error in the license system.
+	ErrLicenseTechError = errors.New("license technical error")
+)
@@ -0,0 +1,166 @@
+package license
+
+import (
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/audit"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/license"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/license"
+)
+
+// DefaultGracePeriod is how long the features of an expired license are kept when no grace period is
+// configured.
+const DefaultGracePeriod = 14 * 24 * time.Hour
+
+// reloadInterval is how long a loaded license is used before the installed license is loaded again, so
+// licenses installed through another server instance take effect on every instance.
+const reloadInterval = time.Minute
+
+// Repository defines the interface for license key persistence operations.
+type Repository interface {
+	// Save stores an installed license key.
+	Save(ctx context.Context, params repository.SaveParams) error
+	// Load retrieves the most recently installed license key.
+	Load(ctx context.Context) (string, error)
+}
+
+// AuditRecorder defines the interface for recording security audit events.
+type AuditRecorder interface {
+	// Record stores the audit event.
+	Record(ctx context.Context, e audit.Event)
+}
+
+// Service provides enterprise license operations.
+type Service struct {
+	// r is the repository interface for license key persistence operations.
+	r Repository
+	// audit records license installations.
+	audit AuditRecorder
+	// now returns the current time.
+	now func() time.Time
+	// loadedAt is the time the license was last loaded; zero before the first load.
+	loadedAt time.Time
+	// current holds the loaded license; nil when none is installed.
+	current *license.License
+	// key contains the license key of the configuration, used until a key is installed.
+	key string
+	// publicKey verifies license keys; nil leaves enterprise features unlicensed and available.
+	publicKey ed25519.PublicKey
+	// grace is how long the features of an expired license are kept.
+	grace time.Duration
+	// mu guards loadedAt and current.
+	mu sync.Mutex
+}
+
+// NewService creates a new license service verifying license keys with the vendor public key. Without a public
+// key enterprise features are available to every deployment. The configured key is used until a license is
+// installed; a non-positive grace period uses DefaultGracePeriod.
+func NewService(
+	r Repository,
+	audit AuditRecorder,
+	publicKey ed25519.PublicKey,
+	key string,
+	grace time.Duration,
+) *Service {
+	if grace <= 0 {
+		grace = DefaultGracePeriod
+	}
+	return &Service{r: r, audit: audit, now: time.Now, key: key, publicKey: publicKey, grace: grace}
+}
+
+// Allows reports whether the enterprise feature is available. Should the installed license fail to load,
+// the previously loaded one keeps applying, so a database outage does not switch features off.
+func (s *Service) Allows(ctx context.Context, feature string) bool {
+	if s.publicKey == nil {
+		return true
+	}
+	l, _ := s.load(ctx)
+	return l != nil && l.Allows(feature, s.now(), s.grace)
+}
+
+// Require ensures that the enterprise feature is available.
+func (s *Service) Require(ctx context.Context, feature string) error {
+	if !s.Allows(ctx, feature) {
+		return fmt.Errorf("feature %s: %w", feature, ErrLicenseFeatureUnavailable)
+	}
+	return nil
+}
+
+// Status reports the installed license and the enterprise features available now.
+func (s *Service) Status(ctx context.Context) (*Status, error) {
+	if s.publicKey == nil {
+		return &Status{State: StateNone, Available: slices.Clone(license.Features)}, nil
+	}
+	l, err := s.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return newStatus(l, s.now(), s.grace), nil
+}
+
+// Install verifies and installs the license key, replacing the current license on every server instance.
+// Licenses past their grace period are refused.
+func (s *Service) Install(ctx context.Context, params InstallParams) (*Status, error) {
+	if s.publicKey == nil {
+		return nil, ErrLicenseNotConfigured
+	}
+	key := strings.TrimSpace(params.Key)
+	l, err := license.Parse(key, s.publicKey)
+	if err != nil {
+		return nil, errors.Join(ErrLicenseInvalid, err)
+	}
+	now := s.now()
+	if l.State(now, s.grace) == license.StateExpired {
+		return nil, fmt.Errorf("license %s expired at %s: %w", l.ID, l.ExpiresAt.Format(time.RFC3339), ErrLicenseExpired)
+	}
+
+	if err := s.r.Save(ctx, repository.SaveParams{InstalledAt: now, ID: l.ID, Key: key}); err != nil {
+		return nil, errors.Join(ErrLicenseTechError, err)
+	}
+	s.mu.Lock()
+	s.current, s.loadedAt = l, now
+	s.mu.Unlock()
+
+	s.audit.Record(ctx, audit.Event{
+		Type: audit.EventLicenseInstalled,
+		Details: map[string]string{
+			"license_id": l.ID,
+			"licensee":   l.Licensee,
+			"features":   strings.Join(l.Features, ","),
+		},
+	})
+	return newStatus(l, now, s.grace), nil
+}
+
+// load returns the installed license, loading it again once reloadInterval passed. The configured key
+// applies while no license is installed; keys failing verification count as no license.
+func (s *Service) load(ctx context.Context) (*license.License, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if !s.loadedAt.IsZero() && now.Sub(s.loadedAt) < reloadInterval {
+		return s.current, nil
+	}
+	key, err := s.r.Load(ctx)
+	switch {
+	case errors.Is(err, repository.ErrLicenseNotFound):
+		key = s.key
+	case err != nil:
+		return s.current, errors.Join(ErrLicenseTechError, err)
+	}
+	s.current, s.loadedAt = nil, now
+	if key != "" {
+		if l, err := license.Parse(key, s.publicKey); err == nil {
+			s.current = l
+		}
+	}
+	return s.current, nil
+}
@@ -0,0 +1,346 @@
+package license
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/audit"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/license"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/license"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// errDatabase is the error of failing repository operations.
+var errDatabase = errors.New("database unavailable")
+
+// mockRepository implements Repository for testing.
+type mockRepository struct {
+	saveErr error
+	loadErr error
+	saved   []repository.SaveParams
+	key     string
+	loads   int
+}
+
+func (m *mockRepository) Save(_ context.Context, params repository.SaveParams) error {
+	if m.saveErr != nil {
+		return m.saveErr
+	}
+	m.saved = append(m.saved, params)
+	m.key = params.Key
+	return nil
+}
+
+func (m *mockRepository) Load(context.Context) (string, error) {
+	m.loads++
+	if m.loadErr != nil {
+		return "", m.loadErr
+	}
+	if m.key == "" {
+		return "", repository.ErrLicenseNotFound
+	}
+	return m.key, nil
+}
+
+// mockAuditRecorder implements AuditRecorder for testing.
+type mockAuditRecorder struct {
+	events []audit.Event
+}
+
+func (m *mockAuditRecorder) Record(_ context.Context, e audit.Event) {
+	m.events = append(m.events, e)
+}
+
+// signKey signs a license for the features expiring at the moment; a zero moment signs a perpetual license.
+func signKey(t *testing.T, privateKey ed25519.PrivateKey, expiresAt time.Time, features ...string) string {
+	t.Helper()
+
+	key, err := license.Sign(&license.License{
+		IssuedAt:  time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		ExpiresAt: expiresAt,
+		ID:        "lic-1",
+		Licensee:  "Example Corp",
+		Features:  features,
+	}, privateKey)
+	require.NoError(t, err)
+	return key
+}
+
+func TestService_Install(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	_, otherKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	tests := []struct {
+		saveErr   error
+		wantErr   error
+		publicKey ed25519.PublicKey
+		name      string
+		key       string
+		wantState string
+		wantAvail []string
+	}{
+		{
+			name:      "valid license",
+			publicKey: publicKey,
+			key:       " " + signKey(t, privateKey, now.AddDate(1, 0, 0), license.FeatureSCIM) + "\n",
+			wantState: license.StateValid,
+			wantAvail: []string{license.FeatureSCIM},
+		},
+		{
+			name:      "license in grace period",
+			publicKey: publicKey,
+			key:       signKey(t, privateKey, now.Add(-time.Hour), license.FeatureSSO, license.FeatureOrgVaults),
+			wantState: license.StateGrace,
+			wantAvail: []string{license.FeatureSSO, license.FeatureOrgVaults},
+		},
+		{
+			name:      "license past grace period",
+			publicKey: publicKey,
+			key:       signKey(t, privateKey, now.Add(-DefaultGracePeriod), license.FeatureSSO),
+			wantErr:   ErrLicenseExpired,
+		},
+		{
+			name:      "foreign signature",
+			publicKey: publicKey,
+			key:       signKey(t, otherKey, time.Time{}, license.FeatureSSO),
+			wantErr:   ErrLicenseInvalid,
+		},
+		{
+			name:      "malformed key",
+			publicKey: publicKey,
+			key:       "not-a-license",
+			wantErr:   ErrLicenseInvalid,
+		},
+		{
+			name:    "verification not configured",
+			key:     signKey(t, privateKey, time.Time{}, license.FeatureSSO),
+			wantErr: ErrLicenseNotConfigured,
+		},
+		{
+			name:      "save failure",
+			saveErr:   errDatabase,
+			publicKey: publicKey,
+			key:       signKey(t, privateKey, time.Time{}, license.FeatureSSO),
+			wantErr:   ErrLicenseTechError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &mockRepository{saveErr: tt.saveErr}
+			recorder := &mockAuditRecorder{}
+			service := NewService(repo, recorder, tt.publicKey, "", 0)
+			service.now = func() time.Time { return now }
+
+			status, err := service.Install(context.Background(), InstallParams{Key: tt.key})
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Empty(t, repo.saved)
+				assert.Empty(t, recorder.events)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantState, status.State)
+			assert.Equal(t, tt.wantAvail, status.Available)
+			assert.True(t, status.Enforced)
+			require.Len(t, repo.saved, 1)
+			assert.Equal(t, repository.SaveParams{InstalledAt: now, ID: "lic-1", Key: repo.key}, repo.saved[0])
+			require.Len(t, recorder.events, 1)
+			assert.Equal(t, audit.EventLicenseInstalled, recorder.events[0].Type)
+			assert.Equal(t, "lic-1", recorder.events[0].Details["license_id"])
+			for _, feature := range tt.wantAvail {
+				assert.True(t, service.Allows(context.Background(), feature))
+			}
+			assert.Zero(t, repo.loads, "installed license is used without loading it")
+		})
+	}
+}
+
+func TestService_Allows(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	_, otherKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	tests := []struct {
+		loadErr   error
+		publicKey ed25519.PublicKey
+		name      string
+		stored    string
+		key       string
+		feature   string
+		want      bool
+	}{
+		{
+			name:    "enforcement off",
+			feature: license.FeatureSCIM,
+			want:    true,
+		},
+		{
+			name:      "no license",
+			publicKey: publicKey,
+			feature:   license.FeatureSCIM,
+		},
+		{
+			name:      "installed license",
+			publicKey: publicKey,
+			stored:    signKey(t, privateKey, time.Time{}, license.FeatureSCIM),
+			feature:   license.FeatureSCIM,
+			want:      true,
+		},
+		{
+			name:      "feature not licensed",
+			publicKey: publicKey,
+			stored:    signKey(t, privateKey, time.Time{}, license.FeatureSCIM),
+			feature:   license.FeatureSSO,
+		},
+		{
+			name:      "license past grace period",
+			publicKey: publicKey,
+			stored:    signKey(t, privateKey, now.Add(-DefaultGracePeriod), license.FeatureSCIM),
+			feature:   license.FeatureSCIM,
+		},
+		{
+			name:      "configured key",
+			publicKey: publicKey,
+			key:       signKey(t, privateKey, time.Time{}, license.FeatureOrgVaults),
+			feature:   license.FeatureOrgVaults,
+			want:      true,
+		},
+		{
+			name:      "installed license replaces configured key",
+			publicKey: publicKey,
+			stored:    signKey(t, privateKey, time.Time{}, license.FeatureSCIM),
+			key:       signKey(t, privateKey, time.Time{}, license.FeatureOrgVaults),
+			feature:   license.FeatureOrgVaults,
+		},
+		{
+			name:      "foreign configured key",
+			publicKey: publicKey,
+			key:       signKey(t, otherKey, time.Time{}, license.FeatureOrgVaults),
+			feature:   license.FeatureOrgVaults,
+		},
+		{
+			name:      "load failure",
+			loadErr:   errDatabase,
+			publicKey: publicKey,
+			key:       signKey(t, privateKey, time.Time{}, license.FeatureOrgVaults),
+			feature:   license.FeatureOrgVaults,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &mockRepository{loadErr: tt.loadErr, key: tt.stored}
+			service := NewService(repo, &mockAuditRecorder{}, tt.publicKey, tt.key, 0)
+			service.now = func() time.Time { return now }
+
+			assert.Equal(t, tt.want, service.Allows(context.Background(), tt.feature))
+			err := service.Require(context.Background(), tt.feature)
+			if tt.want {
+				require.NoError(t, err)
+			} else {
+				require.ErrorIs(t, err, ErrLicenseFeatureUnavailable)
+			}
+		})
+	}
+}
+
+func TestService_AllowsKeepsLicenseOnLoadFailure(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	repo := &mockRepository{key: signKey(t, privateKey, time.Time{}, license.FeatureSCIM)}
+	service := NewService(repo, &mockAuditRecorder{}, publicKey, "", 0)
+	service.now = func() time.Time { return now }
+
+	require.True(t, service.Allows(context.Background(), license.FeatureSCIM))
+	require.True(t, service.Allows(context.Background(), license.FeatureSCIM))
+	assert.Equal(t, 1, repo.loads, "loaded license is reused until the reload interval passes")
+
+	now = now.Add(reloadInterval)
+	repo.loadErr = errDatabase
+	assert.True(t, service.Allows(context.Background(), license.FeatureSCIM))
+	_, err = service.Status(context.Background())
+	require.ErrorIs(t, err, ErrLicenseTechError)
+
+	repo.loadErr, repo.key = nil, ""
+	now = now.Add(reloadInterval)
+	assert.False(t, service.Allows(context.Background(), license.FeatureSCIM))
+}
+
+func TestService_Status(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	expiresAt := now.Add(-24 * time.Hour)
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	tests := []struct {
+		publicKey ed25519.PublicKey
+		want      *Status
+		name      string
+		stored    string
+	}{
+		{
+			name: "enforcement off",
+			want: &Status{State: StateNone, Available: license.Features},
+		},
+		{
+			name:      "no license",
+			publicKey: publicKey,
+			want:      &Status{State: StateNone, Available: []string{}, Enforced: true},
+		},
+		{
+			name:      "license in grace period",
+			publicKey: publicKey,
+			stored:    signKey(t, privateKey, expiresAt, license.FeatureSCIM, license.FeatureAuditExport),
+			want: &Status{
+				IssuedAt:   time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+				ExpiresAt:  expiresAt,
+				GraceUntil: expiresAt.Add(time.Hour * 48),
+				ID:         "lic-1",
+				Licensee:   "Example Corp",
+				State:      license.StateGrace,
+				Features:   []string{license.FeatureSCIM, license.FeatureAuditExport},
+				Available:  []string{license.FeatureSCIM, license.FeatureAuditExport},
+				Enforced:   true,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			service := NewService(&mockRepository{key: tt.stored}, &mockAuditRecorder{}, tt.publicKey, "", 48*time.Hour)
+			service.now = func() time.Time { return now }
+
+			status, err := service.Status(context.Background())
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, status)
+		})
+	}
+}
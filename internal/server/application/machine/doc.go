@@ -0,0 +1,5 @@
+// Package machine provides machine identity application services for the AegisVaultKeeper server.
+//
+// This package manages the machine identities of users and leases the vault items in their scope to
+// CI/CD pipelines presenting a machine token, enforcing the same access rules as user reveals.
+package machine
@@ -0,0 +1,162 @@
+package machine
+
+import (
+	"net/netip"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/machine"
+	"github.com/google/uuid"
+)
+
+// Item represents a vault item in the scope of a machine identity.
+type Item struct {
+	// Kind names the kind of the item: credential, note or acme.
+	Kind string
+	// ID identifies the item.
+	ID uuid.UUID
+}
+
+// Machine represents a machine identity data transfer object for application layer communication.
+type Machine struct {
+	// CreatedAt indicates when the identity was created.
+	CreatedAt time.Time
+	// LastUsedAt indicates when the identity last leased items; zero if never used.
+	LastUsedAt time.Time
+	// Name labels the automation holding the token.
+	Name string
+	// Token contains the machine token; it is only returned once, when the identity is created.
+	Token string
+	// Scope lists the items the identity may lease.
+	Scope []Item
+	// ID uniquely identifies the identity.
+	ID uuid.UUID
+}
+
+// newMachineFromDomain converts a domain machine identity entity to application DTO without its token.
+func newMachineFromDomain(m *machine.Machine) *Machine {
+	if m == nil {
+		return nil
+	}
+	scope := make([]Item, 0, len(m.Scope))
+	for _, item := range m.Scope {
+		scope = append(scope, Item{Kind: string(item.Kind), ID: item.ID})
+	}
+	return &Machine{
+		ID:         m.ID,
+		Name:       m.Name,
+		Scope:      scope,
+		CreatedAt:  m.CreatedAt,
+		LastUsedAt: m.LastUsedAt,
+	}
+}
+
+// newMachinesFromDomain converts a slice of domain machine identity entities to application DTOs.
+func newMachinesFromDomain(ms []*machine.Machine) []*Machine {
+	result := make([]*Machine, 0, len(ms))
+	for _, m := range ms {
+		result = append(result, newMachineFromDomain(m))
+	}
+	return result
+}
+
+// Secret represents the revealed value of a leased vault item.
+type Secret struct {
+	// DNSCredentials contains the DNS provider credentials of an ACME account by name.
+	DNSCredentials map[string]string
+	// Kind names the kind of the item.
+	Kind string
+	// Login contains the login of a credential.
+	Login string
+	// Password contains the password of a credential.
+	Password string
+	// Text contains the text of a note.
+	Text string
+	// DirectoryURL contains the directory URL of the certificate authority of an ACME account.
+	DirectoryURL string
+	// Email contains the contact e-mail of an ACME account.
+	Email string
+	// AccountKey contains the PEM-encoded private key of an ACME account.
+	AccountKey string
+	// DNSProvider contains the DNS provider of an ACME account.
+	DNSProvider string
+	// ID identifies the item.
+	ID uuid.UUID
+}
+
+// Lease represents the vault items handed to a machine identity, valid until it expires.
+type Lease struct {
+	// IssuedAt indicates when the lease was issued.
+	IssuedAt time.Time
+	// ExpiresAt indicates when the holder must discard the leased values.
+	ExpiresAt time.Time
+	// Secrets contains the revealed values of the leased items.
+	Secrets []Secret
+	// ID uniquely identifies the lease in the audit log.
+	ID uuid.UUID
+	// MachineID identifies the machine identity holding the lease.
+	MachineID uuid.UUID
+}
+
+// CreateParams contains parameters for creating a machine identity.
+type CreateParams struct {
+	// Name labels the automation the token is issued to.
+	Name string
+	// Scope lists the items the identity may lease.
+	Scope []Item
+	// UserID identifies the owner of the identity and the items.
+	UserID uuid.UUID
+}
+
+// ListParams contains parameters for listing machine identities.
+type ListParams struct {
+	// UserID identifies the owner of the identities.
+	UserID uuid.UUID
+}
+
+// DeleteParams contains parameters for revoking a machine identity.
+type DeleteParams struct {
+	// ID identifies the identity to revoke.
+	ID uuid.UUID
+	// UserID identifies the owner of the identity.
+	UserID uuid.UUID
+}
+
+// LeaseParams contains parameters for leasing vault items to a machine identity.
+type LeaseParams struct {
+	// IP contains the client address of the request.
+	IP netip.Addr
+	// Token contains the presented machine token.
+	Token string
+	// Items lists the requested items; empty leases the whole scope.
+	Items []uuid.UUID
+}
+
+// LeasePathParams contains parameters for leasing the vault item with a path to a machine identity.
+type LeasePathParams struct {
+	// IP contains the client address of the request.
+	IP netip.Addr
+	// Token contains the presented machine token.
+	Token string
+	// Path contains the requested path, such as prod/db/payments.
+	Path string
+}
+
+// ListPathsParams contains parameters for listing the item paths visible to a machine identity.
+type ListPathsParams struct {
+	// IP contains the client address of the request.
+	IP netip.Addr
+	// Token contains the presented machine token.
+	Token string
+	// Prefix limits the listing to paths equal to or below it; empty lists every path.
+	Prefix string
+}
+
+// Path represents the path of a vault item in the scope of a machine identity.
+type Path struct {
+	// Path contains the path of the item.
+	Path string
+	// Kind names the kind of the item: credential, note or acme.
+	Kind string
+	// ID identifies the item.
+	ID uuid.UUID
+}
@@ -0,0 +1,70 @@
+package machine
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/errutil"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/machine"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/machine"
+)
+
+// Machine identity error definitions.
+var (
+	// ErrMachineAppError indicates a general machine identity application error.
+	ErrMachineAppError = errors.New("machine identity application error")
+
+	// ErrMachineTechError indicates a technical error in the machine identity system.
+	ErrMachineTechError = errors.New("machine identity technical error")
+
+	// ErrMachineIncorrectName indicates an incorrect machine identity name was provided.
+	ErrMachineIncorrectName = errors.New("incorrect machine identity name")
+
+	// ErrMachineIncorrectScope indicates an empty, too large or unsupported machine identity scope.
+	ErrMachineIncorrectScope = errors.New("incorrect machine identity scope")
+
+	// ErrMachineNotFound indicates the requested machine identity was not found.
+	ErrMachineNotFound = errors.New("machine identity not found")
+
+	// ErrInvalidToken indicates that the presented machine token belongs to no machine identity.
+	ErrInvalidToken = errors.New("invalid machine token")
+
+	// ErrItemOutOfScope indicates that a requested item is not in the scope of the machine identity.
+	ErrItemOutOfScope = errors.New("item is out of machine identity scope")
+
+	// ErrItemNotFound indicates that an item in the scope no longer exists.
+	ErrItemNotFound = errors.New("leased item not found")
+
+	// ErrPathNotFound indicates that no item of the owner of the machine identity has the requested path.
+	ErrPathNotFound = errors.New("item path not found")
+)
+
+// mapError maps domain and repository errors to application-level errors.
+func mapError(err error) error {
+	if err == nil {
+		return nil
+	}
+	mapped := errutil.MapError(mapFn, err)
+	if mapped != nil {
+		return fmt.Errorf("machine identity error mapping failed: %w", mapped)
+	}
+	return nil
+}
+
+// mapFn provides the actual error mapping logic for different error types.
+func mapFn(err error) error {
+	switch {
+	case errors.Is(err, machine.ErrNewMachineParamsValidation):
+		return ErrMachineAppError
+	case errors.Is(err, machine.ErrIncorrectName):
+		return ErrMachineIncorrectName
+	case errors.Is(err, machine.ErrIncorrectScope):
+		return ErrMachineIncorrectScope
+	case errors.Is(err, machine.ErrItemOutOfScope):
+		return ErrItemOutOfScope
+	case errors.Is(err, repository.ErrMachineNotFound):
+		return ErrMachineNotFound
+	default:
+		return errors.Join(ErrMachineTechError, err)
+	}
+}
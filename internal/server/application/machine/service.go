@@ -0,0 +1,407 @@
+package machine
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"net/netip"
+	"strings"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/accesscontrol"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/accesspolicy"
+	acmeaccountApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/acmeaccount"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/approval"
+	credentialApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/credential"
+	noteApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/note"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/audit"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/itempath"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/machine"
+	itempathRepo "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/itempath"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/machine"
+	"github.com/google/uuid"
+)
+
+// defaultTTL specifies how long leases last when no duration is configured.
+const defaultTTL = 5 * time.Minute
+
+// tokenPrefix marks machine tokens, so secret scanners can recognize leaked ones.
+const tokenPrefix = "avkm_"
+
+// Repository defines the interface for machine identity persistence operations.
+type Repository interface {
+	// Save persists a machine identity using the provided parameters.
+	Save(ctx context.Context, params repository.SaveParams) error
+	// Load retrieves machine identities using the provided parameters.
+	Load(ctx context.Context, params repository.LoadParams) ([]*machine.Machine, error)
+	// Delete removes a machine identity using the provided parameters.
+	Delete(ctx context.Context, params repository.DeleteParams) error
+}
+
+// PathRepository defines the interface for looking up items by the paths their owners assigned to them.
+type PathRepository interface {
+	// Load retrieves item paths using the provided parameters.
+	Load(ctx context.Context, params itempathRepo.LoadParams) ([]*itempath.ItemPath, error)
+}
+
+// CredentialReader defines the interface for revealing the credentials of their owners.
+type CredentialReader interface {
+	// Pull retrieves a specific credential of the owner.
+	Pull(ctx context.Context, params credentialApp.PullParams) (*credentialApp.Credential, error)
+}
+
+// NoteReader defines the interface for revealing the notes of their owners.
+type NoteReader interface {
+	// Pull retrieves a specific note of the owner.
+	Pull(ctx context.Context, params noteApp.PullParams) (*noteApp.Note, error)
+}
+
+// ACMEReader defines the interface for revealing the ACME accounts of their owners.
+type ACMEReader interface {
+	// Pull retrieves a specific ACME account of the owner.
+	Pull(ctx context.Context, params acmeaccountApp.PullParams) (*acmeaccountApp.Account, error)
+}
+
+// AccessChecker defines the interface for the network access rules and the geofence of restricted items.
+type AccessChecker interface {
+	// Check verifies that the client address is permitted by the access rules of the user.
+	Check(ctx context.Context, params accesscontrol.CheckParams) error
+	// CheckRestricted verifies that the item may be revealed from the client address if it is restricted.
+	CheckRestricted(ctx context.Context, params accesscontrol.RestrictedParams) error
+}
+
+// RevealChecker defines the interface for the access windows of users.
+type RevealChecker interface {
+	// CheckReveal verifies that the access windows of the user are open at the moment.
+	CheckReveal(ctx context.Context, params accesspolicy.CheckParams) error
+}
+
+// ApprovalChecker defines the interface for the approval workflow of items that require approval.
+type ApprovalChecker interface {
+	// CheckApproval verifies that an approved access request is in effect for the item.
+	CheckApproval(ctx context.Context, params approval.CheckParams) error
+}
+
+// AuditRecorder defines the interface for recording security audit events.
+type AuditRecorder interface {
+	// Record stores the audit event.
+	Record(ctx context.Context, e audit.Event)
+}
+
+// Service provides machine identities and the leasing of vault items to them.
+type Service struct {
+	// r is the repository interface for machine identity persistence operations.
+	r Repository
+	// paths resolves the items addressed by path.
+	paths PathRepository
+	// credentials reveals leased credentials.
+	credentials CredentialReader
+	// notes reveals leased notes.
+	notes NoteReader
+	// acme reveals leased ACME accounts.
+	acme ACMEReader
+	// access enforces the network access rules and the geofence.
+	access AccessChecker
+	// reveal enforces the access windows.
+	reveal RevealChecker
+	// approvals enforces the approval workflow.
+	approvals ApprovalChecker
+	// audit records identity changes and leases.
+	audit AuditRecorder
+	// now returns the current time.
+	now func() time.Time
+	// ttl specifies how long the leased values may be used.
+	ttl time.Duration
+}
+
+// NewService creates a new machine identity service instance.
+// Leases last for ttl, or five minutes when ttl is not positive.
+func NewService(
+	r Repository,
+	paths PathRepository,
+	credentials CredentialReader,
+	notes NoteReader,
+	acme ACMEReader,
+	access AccessChecker,
+	reveal RevealChecker,
+	approvals ApprovalChecker,
+	audit AuditRecorder,
+	ttl time.Duration,
+) *Service {
+	if ttl <= 0 {
+		ttl = defaultTTL
+	}
+	return &Service{
+		r:           r,
+		paths:       paths,
+		credentials: credentials,
+		notes:       notes,
+		acme:        acme,
+		access:      access,
+		reveal:      reveal,
+		approvals:   approvals,
+		audit:       audit,
+		now:         time.Now,
+		ttl:         ttl,
+	}
+}
+
+// Create issues a new machine identity with a random token to an automation of the user. The returned
+// identity carries its token, which is not retrievable afterwards.
+func (s *Service) Create(ctx context.Context, params CreateParams) (*Machine, error) {
+	scope := make([]machine.Item, 0, len(params.Scope))
+	for _, item := range params.Scope {
+		scope = append(scope, machine.Item{Kind: machine.Kind(item.Kind), ID: item.ID})
+	}
+
+	token := tokenPrefix + rand.Text()
+	m, err := machine.NewMachine(machine.NewMachineParams{
+		Name:   params.Name,
+		Token:  token,
+		Scope:  scope,
+		UserID: params.UserID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create new machine identity: %w", mapError(err))
+	}
+
+	if err := s.r.Save(ctx, repository.SaveParams{Entity: m}); err != nil {
+		return nil, fmt.Errorf("failed to save machine identity: %w", mapError(err))
+	}
+
+	s.audit.Record(ctx, audit.Event{
+		Type:    audit.EventMachineCreated,
+		UserID:  m.UserID,
+		Details: map[string]string{"machine_id": m.ID.String(), "name": m.Name},
+	})
+
+	result := newMachineFromDomain(m)
+	result.Token = token
+	return result, nil
+}
+
+// List retrieves the machine identities of the user without their tokens.
+func (s *Service) List(ctx context.Context, params ListParams) ([]*Machine, error) {
+	ms, err := s.r.Load(ctx, repository.LoadParams{UserID: params.UserID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load machine identities: %w", mapError(err))
+	}
+	return newMachinesFromDomain(ms), nil
+}
+
+// Delete revokes a machine identity of the user; its token stops working immediately.
+func (s *Service) Delete(ctx context.Context, params DeleteParams) error {
+	err := s.r.Delete(ctx, repository.DeleteParams{ID: params.ID, UserID: params.UserID})
+	if err != nil {
+		return fmt.Errorf("failed to delete machine identity: %w", mapError(err))
+	}
+
+	s.audit.Record(ctx, audit.Event{
+		Type:    audit.EventMachineDeleted,
+		UserID:  params.UserID,
+		Details: map[string]string{"machine_id": params.ID.String()},
+	})
+	return nil
+}
+
+// Lease reveals the requested items in the scope of the machine identity holding the token. The network
+// access rules, access windows, geofence and approval workflow of the owner apply as to the owner's own
+// reveals; any rejected item fails the whole lease.
+func (s *Service) Lease(ctx context.Context, params LeaseParams) (*Lease, error) {
+	m, err := s.authorize(ctx, params.IP, params.Token)
+	if err != nil {
+		return nil, err
+	}
+	return s.lease(ctx, m, params.IP, params.Items)
+}
+
+// LeasePath reveals the item the owner of the machine identity assigned the path to, as Lease does for
+// a single item. Returns ErrPathNotFound when no item of the owner has the path.
+func (s *Service) LeasePath(ctx context.Context, params LeasePathParams) (*Lease, error) {
+	m, err := s.authorize(ctx, params.IP, params.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	path := itempath.Normalize(params.Path)
+	if !itempath.Valid(path) {
+		return nil, ErrPathNotFound
+	}
+	ps, err := s.paths.Load(ctx, itempathRepo.LoadParams{Path: path, UserID: m.UserID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load item path: %w", mapError(err))
+	}
+	if len(ps) == 0 {
+		return nil, ErrPathNotFound
+	}
+	return s.lease(ctx, m, params.IP, []uuid.UUID{ps[0].ItemID})
+}
+
+// ListPaths retrieves the paths of the items in the scope of the machine identity holding the token that
+// equal or lie below the prefix, ordered by path. Nothing lies below an invalid prefix.
+func (s *Service) ListPaths(ctx context.Context, params ListPathsParams) ([]*Path, error) {
+	m, err := s.authorize(ctx, params.IP, params.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := itempath.Normalize(params.Prefix)
+	if prefix != "" && !itempath.Valid(prefix) {
+		return []*Path{}, nil
+	}
+	ps, err := s.paths.Load(ctx, itempathRepo.LoadParams{Prefix: prefix, UserID: m.UserID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load item paths: %w", mapError(err))
+	}
+
+	result := make([]*Path, 0, len(ps))
+	for _, p := range ps {
+		items, err := m.Resolve([]uuid.UUID{p.ItemID})
+		if err != nil {
+			continue
+		}
+		result = append(result, &Path{Path: p.Path, Kind: string(items[0].Kind), ID: p.ItemID})
+	}
+	return result, nil
+}
+
+// authorize resolves the machine identity holding the token and checks the network access rules of its
+// owner for the client address.
+func (s *Service) authorize(ctx context.Context, ip netip.Addr, token string) (*machine.Machine, error) {
+	m, err := s.authenticate(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.access.Check(ctx, accesscontrol.CheckParams{IP: ip, UserID: m.UserID}); err != nil {
+		return nil, fmt.Errorf("machine identity %s denied: %w", m.ID, err)
+	}
+	return m, nil
+}
+
+// lease reveals the items with the identifiers, or the whole scope when none are given, to the machine
+// identity and records the lease.
+func (s *Service) lease(ctx context.Context, m *machine.Machine, ip netip.Addr, ids []uuid.UUID) (*Lease, error) {
+	items, err := m.Resolve(ids)
+	if err != nil {
+		s.audit.Record(ctx, audit.Event{
+			Type:    audit.EventMachineLeaseDenied,
+			UserID:  m.UserID,
+			Details: map[string]string{"machine_id": m.ID.String(), "reason": "out_of_scope"},
+		})
+		return nil, fmt.Errorf("failed to resolve leased items: %w", mapError(err))
+	}
+
+	now := s.now()
+	if err := s.reveal.CheckReveal(ctx, accesspolicy.CheckParams{At: now, UserID: m.UserID}); err != nil {
+		return nil, fmt.Errorf("machine identity %s denied: %w", m.ID, err)
+	}
+
+	secrets := make([]Secret, 0, len(items))
+	for _, item := range items {
+		secret, err := s.pull(ctx, m.UserID, item, ip)
+		if err != nil {
+			return nil, err
+		}
+		secrets = append(secrets, secret)
+	}
+
+	m.Used(now)
+	if err := s.r.Save(ctx, repository.SaveParams{Entity: m}); err != nil {
+		return nil, fmt.Errorf("failed to save machine identity: %w", mapError(err))
+	}
+
+	lease := &Lease{
+		ID:        uuid.New(),
+		MachineID: m.ID,
+		IssuedAt:  now,
+		ExpiresAt: now.Add(s.ttl),
+		Secrets:   secrets,
+	}
+	leased := make([]string, 0, len(items))
+	for _, item := range items {
+		leased = append(leased, item.ID.String())
+	}
+	s.audit.Record(ctx, audit.Event{
+		Type:   audit.EventMachineLeaseIssued,
+		UserID: m.UserID,
+		Details: map[string]string{
+			"machine_id": m.ID.String(),
+			"lease_id":   lease.ID.String(),
+			"items":      strings.Join(leased, ","),
+			"expires_at": lease.ExpiresAt.UTC().Format(time.RFC3339),
+		},
+	})
+	return lease, nil
+}
+
+// authenticate resolves the machine identity holding the token.
+func (s *Service) authenticate(ctx context.Context, token string) (*machine.Machine, error) {
+	if !strings.HasPrefix(token, tokenPrefix) {
+		return nil, ErrInvalidToken
+	}
+
+	ms, err := s.r.Load(ctx, repository.LoadParams{TokenHash: machine.HashToken(token)})
+	if errors.Is(err, repository.ErrMachineNotFound) {
+		return nil, ErrInvalidToken
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load machine identity: %w", mapError(err))
+	}
+	return ms[0], nil
+}
+
+// pull reveals a leased item after checking the geofence and approval workflow of the owner for it.
+func (s *Service) pull(ctx context.Context, userID uuid.UUID, item machine.Item, ip netip.Addr) (Secret, error) {
+	if err := s.access.CheckRestricted(ctx, accesscontrol.RestrictedParams{
+		IP:     ip,
+		UserID: userID,
+		ItemID: item.ID,
+	}); err != nil {
+		return Secret{}, fmt.Errorf("item %s denied: %w", item.ID, err)
+	}
+	if err := s.approvals.CheckApproval(ctx, approval.CheckParams{UserID: userID, ItemID: item.ID}); err != nil {
+		return Secret{}, fmt.Errorf("item %s denied: %w", item.ID, err)
+	}
+
+	switch item.Kind {
+	case machine.KindCredential:
+		c, err := s.credentials.Pull(ctx, credentialApp.PullParams{ID: item.ID, UserID: userID})
+		if errors.Is(err, credentialApp.ErrCredentialNotFound) || errors.Is(err, credentialApp.ErrCredentialAccessDenied) {
+			return Secret{}, fmt.Errorf("credential %s: %w", item.ID, ErrItemNotFound)
+		}
+		if err != nil {
+			return Secret{}, fmt.Errorf("failed to reveal leased credential: %w", mapError(err))
+		}
+		return Secret{ID: item.ID, Kind: string(item.Kind), Login: c.Login, Password: c.Password}, nil
+	case machine.KindNote:
+		n, err := s.notes.Pull(ctx, noteApp.PullParams{ID: item.ID, UserID: userID})
+		if errors.Is(err, noteApp.ErrNoteNotFound) || errors.Is(err, noteApp.ErrNoteAccessDenied) {
+			return Secret{}, fmt.Errorf("note %s: %w", item.ID, ErrItemNotFound)
+		}
+		if err != nil {
+			return Secret{}, fmt.Errorf("failed to reveal leased note: %w", mapError(err))
+		}
+		return Secret{ID: item.ID, Kind: string(item.Kind), Text: n.Note}, nil
+	case machine.KindACME:
+		a, err := s.acme.Pull(ctx, acmeaccountApp.PullParams{ID: item.ID, UserID: userID})
+		if errors.Is(err, acmeaccountApp.ErrACMENotFound) || errors.Is(err, acmeaccountApp.ErrACMEAccessDenied) {
+			return Secret{}, fmt.Errorf("ACME account %s: %w", item.ID, ErrItemNotFound)
+		}
+		if err != nil {
+			return Secret{}, fmt.Errorf("failed to reveal leased ACME account: %w", mapError(err))
+		}
+		return Secret{
+			ID:             item.ID,
+			Kind:           string(item.Kind),
+			DirectoryURL:   a.DirectoryURL,
+			Email:          a.Email,
+			AccountKey:     a.AccountKey,
+			DNSProvider:    a.DNSProvider,
+			DNSCredentials: a.DNSCredentials,
+		}, nil
+	default:
+		return Secret{}, fmt.Errorf("item %s of kind %q: %w", item.ID, item.Kind, ErrMachineTechError)
+	}
+}
@@ -0,0 +1,476 @@
+package machine
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/accesscontrol"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/accesspolicy"
+	acmeaccountApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/acmeaccount"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/approval"
+	credentialApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/credential"
+	noteApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/note"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/audit"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/itempath"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/machine"
+	itempathRepo "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/itempath"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/machine"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockRepository implements Repository for testing.
+type mockRepository struct {
+	saved  *machine.Machine
+	stored []*machine.Machine
+}
+
+func (m *mockRepository) Save(_ context.Context, params repository.SaveParams) error {
+	m.saved = params.Entity
+	return nil
+}
+
+func (m *mockRepository) Load(_ context.Context, params repository.LoadParams) ([]*machine.Machine, error) {
+	var ms []*machine.Machine
+	for _, e := range m.stored {
+		if params.UserID != uuid.Nil && e.UserID != params.UserID {
+			continue
+		}
+		if params.TokenHash != nil && string(e.TokenHash) != string(params.TokenHash) {
+			continue
+		}
+		ms = append(ms, e)
+	}
+	if len(ms) == 0 && params.TokenHash != nil {
+		return nil, repository.ErrMachineNotFound
+	}
+	return ms, nil
+}
+
+func (m *mockRepository) Delete(_ context.Context, _ repository.DeleteParams) error {
+	if len(m.stored) == 0 {
+		return repository.ErrMachineNotFound
+	}
+	return nil
+}
+
+// mockPathRepository implements PathRepository for testing.
+type mockPathRepository struct {
+	stored []*itempath.ItemPath
+}
+
+func (m *mockPathRepository) Load(_ context.Context, params itempathRepo.LoadParams) ([]*itempath.ItemPath, error) {
+	var ps []*itempath.ItemPath
+	for _, p := range m.stored {
+		if p.UserID != params.UserID {
+			continue
+		}
+		if params.Path != "" && p.Path != params.Path {
+			continue
+		}
+		if params.Prefix != "" && !p.Under(params.Prefix) {
+			continue
+		}
+		ps = append(ps, p)
+	}
+	return ps, nil
+}
+
+// mockCredentials implements CredentialReader for testing.
+type mockCredentials struct {
+	err error
+}
+
+func (m *mockCredentials) Pull(_ context.Context, params credentialApp.PullParams) (*credentialApp.Credential, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &credentialApp.Credential{ID: params.ID, UserID: params.UserID, Login: "ci", Password: "s3cret"}, nil
+}
+
+// mockNotes implements NoteReader for testing.
+type mockNotes struct{}
+
+func (m *mockNotes) Pull(_ context.Context, params noteApp.PullParams) (*noteApp.Note, error) {
+	return &noteApp.Note{ID: params.ID, UserID: params.UserID, Note: "deploy key"}, nil
+}
+
+// mockACME implements ACMEReader for testing.
+type mockACME struct {
+	err error
+}
+
+func (m *mockACME) Pull(_ context.Context, params acmeaccountApp.PullParams) (*acmeaccountApp.Account, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &acmeaccountApp.Account{
+		ID:             params.ID,
+		UserID:         params.UserID,
+		DirectoryURL:   "https://acme.example.com/directory",
+		AccountKey:     "account key",
+		DNSProvider:    "cloudflare",
+		DNSCredentials: map[string]string{"CF_DNS_API_TOKEN": "token"},
+	}, nil
+}
+
+// mockCheckers implements AccessChecker, RevealChecker and ApprovalChecker for testing.
+type mockCheckers struct {
+	accessErr     error
+	restrictedErr error
+	revealErr     error
+	approvalErr   error
+}
+
+func (m *mockCheckers) Check(_ context.Context, _ accesscontrol.CheckParams) error {
+	return m.accessErr
+}
+
+func (m *mockCheckers) CheckRestricted(_ context.Context, _ accesscontrol.RestrictedParams) error {
+	return m.restrictedErr
+}
+
+func (m *mockCheckers) CheckReveal(_ context.Context, _ accesspolicy.CheckParams) error {
+	return m.revealErr
+}
+
+func (m *mockCheckers) CheckApproval(_ context.Context, _ approval.CheckParams) error {
+	return m.approvalErr
+}
+
+// mockAuditRecorder implements AuditRecorder for testing.
+type mockAuditRecorder struct {
+	events []audit.Event
+}
+
+func (m *mockAuditRecorder) Record(_ context.Context, e audit.Event) {
+	m.events = append(m.events, e)
+}
+
+func TestService_Create(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		wantErr error
+		name    string
+		scope   []Item
+	}{
+		{name: "valid machine", scope: []Item{{Kind: "credential", ID: uuid.New()}}},
+		{name: "empty scope", wantErr: ErrMachineIncorrectScope},
+		{name: "unknown kind", scope: []Item{{Kind: "file", ID: uuid.New()}}, wantErr: ErrMachineIncorrectScope},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &mockRepository{}
+			recorder := &mockAuditRecorder{}
+			s := NewService(repo, nil, nil, nil, nil, nil, nil, nil, recorder, 0)
+
+			got, err := s.Create(context.Background(), CreateParams{Name: "ci", Scope: tt.scope, UserID: uuid.New()})
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, repo.saved)
+				assert.Empty(t, recorder.events)
+				return
+			}
+			require.NoError(t, err)
+			require.NotNil(t, repo.saved)
+			assert.True(t, strings.HasPrefix(got.Token, tokenPrefix))
+			assert.Equal(t, machine.HashToken(got.Token), repo.saved.TokenHash)
+			require.Len(t, recorder.events, 1)
+			assert.Equal(t, audit.EventMachineCreated, recorder.events[0].Type)
+		})
+	}
+}
+
+func TestService_Lease(t *testing.T) {
+	t.Parallel()
+
+	const token = tokenPrefix + "LEASETOKENLEASETOKEN"
+	credentialID, noteID, acmeID := uuid.New(), uuid.New(), uuid.New()
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		checkers    mockCheckers
+		credErr     error
+		acmeErr     error
+		wantErr     error
+		name        string
+		token       string
+		wantEvent   string
+		items       []uuid.UUID
+		wantSecrets int
+	}{
+		{name: "whole scope", token: token, wantSecrets: 3, wantEvent: audit.EventMachineLeaseIssued},
+		{
+			name:        "single item",
+			token:       token,
+			items:       []uuid.UUID{noteID},
+			wantSecrets: 1,
+			wantEvent:   audit.EventMachineLeaseIssued,
+		},
+		{name: "unknown token", token: tokenPrefix + "OTHERTOKENOTHERTOKEN", wantErr: ErrInvalidToken},
+		{name: "foreign token", token: "LEASETOKENLEASETOKEN", wantErr: ErrInvalidToken},
+		{
+			name:      "item out of scope",
+			token:     token,
+			items:     []uuid.UUID{uuid.New()},
+			wantErr:   ErrItemOutOfScope,
+			wantEvent: audit.EventMachineLeaseDenied,
+		},
+		{
+			name:     "denied network",
+			token:    token,
+			checkers: mockCheckers{accessErr: accesscontrol.ErrAccessDenied},
+			wantErr:  accesscontrol.ErrAccessDenied,
+		},
+		{
+			name:     "outside access window",
+			token:    token,
+			checkers: mockCheckers{revealErr: accesspolicy.ErrOutsideAccessWindow},
+			wantErr:  accesspolicy.ErrOutsideAccessWindow,
+		},
+		{
+			name:     "restricted item",
+			token:    token,
+			checkers: mockCheckers{restrictedErr: accesscontrol.ErrRestrictedLocation},
+			wantErr:  accesscontrol.ErrRestrictedLocation,
+		},
+		{
+			name:     "approval required",
+			token:    token,
+			checkers: mockCheckers{approvalErr: approval.ErrApprovalRequired},
+			wantErr:  approval.ErrApprovalRequired,
+		},
+		{
+			name:    "deleted credential",
+			token:   token,
+			credErr: credentialApp.ErrCredentialNotFound,
+			wantErr: ErrItemNotFound,
+		},
+		{
+			name:    "deleted ACME account",
+			token:   token,
+			items:   []uuid.UUID{acmeID},
+			acmeErr: acmeaccountApp.ErrACMENotFound,
+			wantErr: ErrItemNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			m, err := machine.NewMachine(machine.NewMachineParams{
+				Name:  "ci",
+				Token: token,
+				Scope: []machine.Item{
+					{Kind: machine.KindCredential, ID: credentialID},
+					{Kind: machine.KindNote, ID: noteID},
+					{Kind: machine.KindACME, ID: acmeID},
+				},
+				UserID: uuid.New(),
+			})
+			require.NoError(t, err)
+			repo := &mockRepository{stored: []*machine.Machine{m}}
+			recorder := &mockAuditRecorder{}
+			s := NewService(
+				repo,
+				&mockPathRepository{},
+				&mockCredentials{err: tt.credErr},
+				&mockNotes{},
+				&mockACME{err: tt.acmeErr},
+				&tt.checkers,
+				&tt.checkers,
+				&tt.checkers,
+				recorder,
+				time.Minute,
+			)
+			s.now = func() time.Time { return now }
+
+			got, err := s.Lease(context.Background(), LeaseParams{Token: tt.token, Items: tt.items})
+
+			if tt.wantEvent != "" {
+				require.Len(t, recorder.events, 1)
+				assert.Equal(t, tt.wantEvent, recorder.events[0].Type)
+			} else {
+				assert.Empty(t, recorder.events)
+			}
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, repo.saved)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, got.Secrets, tt.wantSecrets)
+			for _, secret := range got.Secrets {
+				if secret.Kind == string(machine.KindACME) {
+					assert.Equal(t, "account key", secret.AccountKey)
+					assert.Equal(t, map[string]string{"CF_DNS_API_TOKEN": "token"}, secret.DNSCredentials)
+				}
+			}
+			assert.Equal(t, m.ID, got.MachineID)
+			assert.Equal(t, now.Add(time.Minute), got.ExpiresAt)
+			require.NotNil(t, repo.saved)
+			assert.Equal(t, now, repo.saved.LastUsedAt)
+		})
+	}
+}
+
+func TestService_LeasePath(t *testing.T) {
+	t.Parallel()
+
+	const token = tokenPrefix + "LEASETOKENLEASETOKEN"
+	userID, credentialID, foreignID := uuid.New(), uuid.New(), uuid.New()
+
+	tests := []struct {
+		wantErr   error
+		name      string
+		path      string
+		wantEvent string
+		wantID    uuid.UUID
+	}{
+		{name: "path in scope", path: "/prod/db/payments/", wantID: credentialID, wantEvent: audit.EventMachineLeaseIssued},
+		{name: "unknown path", path: "prod/db/orders", wantErr: ErrPathNotFound},
+		{name: "invalid path", path: "prod/../db", wantErr: ErrPathNotFound},
+		{name: "empty path", path: "/", wantErr: ErrPathNotFound},
+		{
+			name:      "path out of scope",
+			path:      "prod/db/admin",
+			wantErr:   ErrItemOutOfScope,
+			wantEvent: audit.EventMachineLeaseDenied,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			m, err := machine.NewMachine(machine.NewMachineParams{
+				Name:   "ci",
+				Token:  token,
+				Scope:  []machine.Item{{Kind: machine.KindCredential, ID: credentialID}},
+				UserID: userID,
+			})
+			require.NoError(t, err)
+			paths := &mockPathRepository{stored: []*itempath.ItemPath{
+				{Path: "prod/db/payments", ItemID: credentialID, UserID: userID},
+				{Path: "prod/db/admin", ItemID: foreignID, UserID: userID},
+			}}
+			recorder := &mockAuditRecorder{}
+			checkers := &mockCheckers{}
+			s := NewService(
+				&mockRepository{stored: []*machine.Machine{m}},
+				paths,
+				&mockCredentials{},
+				&mockNotes{},
+				&mockACME{},
+				checkers,
+				checkers,
+				checkers,
+				recorder,
+				time.Minute,
+			)
+
+			got, err := s.LeasePath(context.Background(), LeasePathParams{Token: token, Path: tt.path})
+
+			if tt.wantEvent != "" {
+				require.Len(t, recorder.events, 1)
+				assert.Equal(t, tt.wantEvent, recorder.events[0].Type)
+			} else {
+				assert.Empty(t, recorder.events)
+			}
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, got.Secrets, 1)
+			assert.Equal(t, tt.wantID, got.Secrets[0].ID)
+		})
+	}
+}
+
+func TestService_ListPaths(t *testing.T) {
+	t.Parallel()
+
+	const token = tokenPrefix + "LEASETOKENLEASETOKEN"
+	userID, credentialID, noteID := uuid.New(), uuid.New(), uuid.New()
+
+	tests := []struct {
+		checkers  mockCheckers
+		wantErr   error
+		name      string
+		token     string
+		prefix    string
+		wantPaths []string
+	}{
+		{
+			name:      "whole scope",
+			token:     token,
+			wantPaths: []string{"prod/db/payments", "prod/deploy"},
+		},
+		{name: "prefix", token: token, prefix: "prod/db", wantPaths: []string{"prod/db/payments"}},
+		{name: "invalid prefix", token: token, prefix: "prod/..", wantPaths: []string{}},
+		{name: "unknown token", token: tokenPrefix + "OTHERTOKENOTHERTOKEN", wantErr: ErrInvalidToken},
+		{
+			name:     "denied network",
+			token:    token,
+			checkers: mockCheckers{accessErr: accesscontrol.ErrAccessDenied},
+			wantErr:  accesscontrol.ErrAccessDenied,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			m, err := machine.NewMachine(machine.NewMachineParams{
+				Name:  "ci",
+				Token: token,
+				Scope: []machine.Item{
+					{Kind: machine.KindCredential, ID: credentialID},
+					{Kind: machine.KindNote, ID: noteID},
+				},
+				UserID: userID,
+			})
+			require.NoError(t, err)
+			paths := &mockPathRepository{stored: []*itempath.ItemPath{
+				{Path: "prod/db/admin", ItemID: uuid.New(), UserID: userID},
+				{Path: "prod/db/payments", ItemID: credentialID, UserID: userID},
+				{Path: "prod/deploy", ItemID: noteID, UserID: userID},
+			}}
+			s := NewService(
+				&mockRepository{stored: []*machine.Machine{m}},
+				paths,
+				nil,
+				nil,
+				nil,
+				&tt.checkers,
+				&tt.checkers,
+				&tt.checkers,
+				&mockAuditRecorder{},
+				time.Minute,
+			)
+
+			got, err := s.ListPaths(context.Background(), ListPathsParams{Token: tt.token, Prefix: tt.prefix})
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			gotPaths := make([]string, 0, len(got))
+			for _, p := range got {
+				gotPaths = append(gotPaths, p.Path)
+			}
+			assert.Equal(t, tt.wantPaths, gotPaths)
+		})
+	}
+}
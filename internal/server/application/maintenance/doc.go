@@ -0,0 +1,5 @@
+// Package maintenance provides the read-only maintenance mode switch for the AegisVaultKeeper server.
+//
+// While maintenance mode is on, mutating requests are rejected so backups and migrations can run
+// against a stable dataset, and reads continue to be served.
+package maintenance
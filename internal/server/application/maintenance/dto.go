@@ -0,0 +1,17 @@
+package maintenance
+
+import "time"
+
+// State describes the current maintenance mode.
+type State struct {
+	// Since contains the moment maintenance mode was last switched; zero when it was set at startup.
+	Since time.Time
+	// Enabled determines whether mutating requests are rejected.
+	Enabled bool
+}
+
+// SetParams contains the parameters for switching maintenance mode.
+type SetParams struct {
+	// Enabled determines whether maintenance mode is switched on.
+	Enabled bool
+}
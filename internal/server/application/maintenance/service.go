@@ -0,0 +1,67 @@
+package maintenance
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/audit"
+)
+
+// AuditRecorder defines the interface for recording security audit events.
+type AuditRecorder interface {
+	// Record stores the audit event.
+	Record(ctx context.Context, e audit.Event)
+}
+
+// Service holds the maintenance mode of the server instance.
+// The mode is kept in memory, so every instance of a deployment is switched separately.
+type Service struct {
+	// audit records maintenance mode changes.
+	audit AuditRecorder
+	// state is the current maintenance mode.
+	state State
+	// mu guards state.
+	mu sync.RWMutex
+}
+
+// NewService creates a new maintenance mode switch starting in the specified mode.
+func NewService(enabled bool, audit AuditRecorder) *Service {
+	return &Service{audit: audit, state: State{Enabled: enabled}}
+}
+
+// Enabled reports whether mutating requests must be rejected.
+func (s *Service) Enabled() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.state.Enabled
+}
+
+// Status returns the current maintenance mode.
+func (s *Service) Status(context.Context) State {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.state
+}
+
+// Set switches maintenance mode and returns the resulting state.
+// Switching to the current mode keeps the state unchanged.
+func (s *Service) Set(ctx context.Context, params SetParams) State {
+	s.mu.Lock()
+	if s.state.Enabled == params.Enabled {
+		state := s.state
+		s.mu.Unlock()
+		return state
+	}
+	s.state = State{Enabled: params.Enabled, Since: time.Now()}
+	state := s.state
+	s.mu.Unlock()
+
+	s.audit.Record(ctx, audit.Event{
+		Type:       audit.EventMaintenanceToggled,
+		OccurredAt: state.Since,
+		Details:    map[string]string{"enabled": strconv.FormatBool(state.Enabled)},
+	})
+	return state
+}
@@ -0,0 +1,82 @@
+package maintenance
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/audit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockAuditRecorder implements AuditRecorder for testing.
+type mockAuditRecorder struct {
+	events []audit.Event
+}
+
+func (m *mockAuditRecorder) Record(_ context.Context, e audit.Event) {
+	m.events = append(m.events, e)
+}
+
+func TestNewService(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		enabled bool
+	}{
+		{name: "starts in normal mode", enabled: false},
+		{name: "starts in maintenance mode", enabled: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := NewService(tt.enabled, &mockAuditRecorder{})
+
+			assert.Equal(t, tt.enabled, s.Enabled())
+			assert.Equal(t, State{Enabled: tt.enabled}, s.Status(context.Background()))
+		})
+	}
+}
+
+func TestService_Set(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		initial    bool
+		enabled    bool
+		wantEvents int
+	}{
+		{name: "switch on", initial: false, enabled: true, wantEvents: 1},
+		{name: "switch off", initial: true, enabled: false, wantEvents: 1},
+		{name: "already on", initial: true, enabled: true, wantEvents: 0},
+		{name: "already off", initial: false, enabled: false, wantEvents: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			recorder := &mockAuditRecorder{}
+			s := NewService(tt.initial, recorder)
+
+			state := s.Set(context.Background(), SetParams{Enabled: tt.enabled})
+
+			assert.Equal(t, tt.enabled, state.Enabled)
+			assert.Equal(t, tt.enabled, s.Enabled())
+			assert.Equal(t, state, s.Status(context.Background()))
+			require.Len(t, recorder.events, tt.wantEvents)
+			if tt.wantEvents > 0 {
+				assert.False(t, state.Since.IsZero())
+				assert.Equal(t, audit.EventMaintenanceToggled, recorder.events[0].Type)
+				assert.Equal(t, strconv.FormatBool(tt.enabled), recorder.events[0].Details["enabled"])
+			} else {
+				assert.True(t, state.Since.IsZero())
+			}
+		})
+	}
+}
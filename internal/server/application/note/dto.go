@@ -19,6 +19,8 @@ type Note struct {
 	ID uuid.UUID
 	// UserID identifies the note owner.
 	UserID uuid.UUID
+	// Archived reports whether the note is tagged archived; set for listed notes only.
+	Archived bool
 }
 
 // newNoteFromDomain converts a domain note entity to application DTO.
@@ -46,6 +48,10 @@ func newNotesFromDomain(cs []*note.Note) []*Note {
 
 // PullParams contains parameters for retrieving a specific note.
 type PullParams struct {
+	// AsOf selects the version current at the specified moment; zero value pulls the current version.
+	AsOf time.Time
+	// Fields selects the sensitive fields of the note to decrypt by name; empty selects all of them.
+	Fields []string
 	// ID specifies the note to retrieve.
 	ID uuid.UUID
 	// UserID specifies the note owner.
@@ -54,6 +60,31 @@ type PullParams struct {
 
 // ListParams contains parameters for listing user notes.
 type ListParams struct {
+	// After lists only the notes whose ID sorts after it; zero value starts at the first.
+	After uuid.UUID
+	// AsOf selects the versions current at the specified moment; zero value lists current versions.
+	AsOf time.Time
+	// Fields selects the sensitive fields of notes to decrypt by name; empty selects all of them.
+	Fields []string
+	// Limit caps the number of listed notes and orders them by ID; zero value lists all.
+	Limit int
+	// UserID specifies the note owner.
+	UserID uuid.UUID
+	// OrderFolder names the folder whose manual order sorts the notes with ManualOrder; empty is the root.
+	OrderFolder string
+	// ExcludeArchived leaves out the notes tagged archived; zero value lists every note.
+	ExcludeArchived bool
+	// ManualOrder sorts the notes in the manual order of OrderFolder, the others after them in their default
+	// order; zero value keeps the default order.
+	ManualOrder bool
+}
+
+// RecoverParams contains parameters for restoring a note to an earlier version.
+type RecoverParams struct {
+	// AsOf specifies the moment whose note version becomes current again.
+	AsOf time.Time
+	// ID specifies the note to recover.
+	ID uuid.UUID
 	// UserID specifies the note owner.
 	UserID uuid.UUID
 }
@@ -69,3 +100,57 @@ type PushParams struct {
 	// UserID identifies the note owner.
 	UserID uuid.UUID
 }
+
+// PushBatchParams contains parameters for creating or updating several notes of one user at once.
+type PushBatchParams struct {
+	// Items contains the notes to push; their UserID is replaced by the batch UserID.
+	Items []*PushParams
+	// UserID identifies the owner of all notes in the batch.
+	UserID uuid.UUID
+}
+
+// Update represents a stored text update of a note edited in merge mode.
+type Update struct {
+	// Data contains the update in the JSON update format of notecrdt.
+	Data []byte
+	// Seq orders the updates; clients pass the last one they merged to pull the newer ones.
+	Seq int64
+	// Snapshot reports whether the update is a compacted snapshot replacing all updates up to its Seq.
+	Snapshot bool
+}
+
+// MergeParams contains parameters for switching merge mode of a note on or off.
+type MergeParams struct {
+	// ID specifies the note.
+	ID uuid.UUID
+	// UserID specifies the note owner.
+	UserID uuid.UUID
+}
+
+// PushUpdateParams contains parameters for merging a text update into a note edited in merge mode.
+type PushUpdateParams struct {
+	// Data contains the update in the JSON update format of notecrdt; snapshots are not accepted.
+	Data []byte
+	// ID specifies the note.
+	ID uuid.UUID
+	// UserID specifies the note owner.
+	UserID uuid.UUID
+}
+
+// PushUpdateResult describes a merged text update.
+type PushUpdateResult struct {
+	// Note contains the note with the merged text.
+	Note *Note
+	// Seq is the sequence number the update was stored with.
+	Seq int64
+}
+
+// UpdatesParams contains parameters for pulling the text updates of a note edited in merge mode.
+type UpdatesParams struct {
+	// ID specifies the note.
+	ID uuid.UUID
+	// UserID specifies the note owner.
+	UserID uuid.UUID
+	// AfterSeq limits the updates to those stored after the sequence number; zero pulls all of them.
+	AfterSeq int64
+}
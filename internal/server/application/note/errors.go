@@ -6,6 +6,8 @@ import (
 
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/errutil"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/note"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/notecrdt"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/fieldcrypt"
 )
 
 // Note error definitions.
@@ -24,6 +26,18 @@ var (
 
 	// ErrNoteAccessDenied indicates access to the note is not permitted.
 	ErrNoteAccessDenied = errors.New("access to this note is denied")
+
+	// ErrNoteIntegrityViolation indicates stored note data failed ownership integrity verification.
+	ErrNoteIntegrityViolation = errors.New("note data integrity violation")
+
+	// ErrNoteMergeMode indicates a plain change of the text of a note edited in merge mode.
+	ErrNoteMergeMode = errors.New("note text is edited in merge mode")
+
+	// ErrNoteNotInMergeMode indicates a text update of a note not edited in merge mode.
+	ErrNoteNotInMergeMode = errors.New("note is not edited in merge mode")
+
+	// ErrNoteUpdateInvalid indicates a malformed text update or one that does not fit the text of the note.
+	ErrNoteUpdateInvalid = errors.New("invalid note text update")
 )
 
 // mapError maps domain and repository errors to application-level errors.
@@ -45,6 +59,13 @@ func mapFn(err error) error {
 		return ErrNoteAppError
 	case errors.Is(err, note.ErrIncorrectNoteText):
 		return ErrNoteIncorrectNoteText
+	case errors.Is(err, notecrdt.ErrMalformedUpdate),
+		errors.Is(err, notecrdt.ErrInvalidOperation),
+		errors.Is(err, notecrdt.ErrUnknownElement),
+		errors.Is(err, notecrdt.ErrMisplacedSnapshot):
+		return errors.Join(ErrNoteUpdateInvalid, err)
+	case errors.Is(err, fieldcrypt.ErrIntegrityViolation):
+		return errors.Join(ErrNoteIntegrityViolation, err)
 	default:
 		return errors.Join(ErrNoteTechError, err)
 	}
@@ -5,6 +5,8 @@ import (
 	"testing"
 
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/note"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/notecrdt"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/fieldcrypt"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -34,6 +36,16 @@ func TestMapError(t *testing.T) {
 			input: note.ErrIncorrectNoteText,
 			want:  ErrNoteIncorrectNoteText,
 		},
+		{
+			name:  "integrity_violation/maps_to_specific_error",
+			input: fieldcrypt.ErrIntegrityViolation,
+			want:  ErrNoteIntegrityViolation,
+		},
+		{
+			name:  "unknown_text_element/maps_to_invalid_update",
+			input: notecrdt.ErrUnknownElement,
+			want:  ErrNoteUpdateInvalid,
+		},
 		{
 			name:  "unknown_error/maps_to_tech_error",
 			input: errors.New("unknown database error"),
@@ -0,0 +1,292 @@
+package note
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/authz"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/note"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/notecrdt"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/note"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/noteupdate"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/securebytes"
+	"github.com/google/uuid"
+)
+
+// defaultCompactThreshold is the number of stored updates a note must have to be compacted when no threshold
+// is configured.
+const defaultCompactThreshold = 100
+
+// EnableMerge switches a note to merge mode and returns its updates. The replicated text is seeded with the
+// current text of the note, one element per character; enabling merge mode twice keeps the stored updates.
+func (s *Service) EnableMerge(ctx context.Context, params MergeParams) ([]*Update, error) {
+	err := s.uow.Do(ctx, params.UserID, func(ctx context.Context) error {
+		n, err := s.loadNote(ctx, authz.ActionWrite, params.ID, params.UserID)
+		if err != nil {
+			return err
+		}
+		defer n.Wipe()
+
+		records, err := s.updates.Load(ctx, noteupdate.LoadParams{NoteID: n.ID, UserID: params.UserID, Lock: true})
+		if err != nil {
+			return fmt.Errorf("failed to load note updates: %w", mapError(err))
+		}
+		defer securebytes.WipeAll(records)
+		if len(records) != 0 {
+			return nil
+		}
+
+		data, err := notecrdt.Encode(notecrdt.Seed(string(n.Note)))
+		if err != nil {
+			return fmt.Errorf("failed to encode note seed: %w", mapError(err))
+		}
+		defer securebytes.Wipe(data)
+		if _, err := s.updates.Save(ctx, noteupdate.SaveParams{Entity: newRecord(n, data, 0)}); err != nil {
+			return fmt.Errorf("failed to save note seed: %w", mapError(err))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to enable merge mode: %w", err)
+	}
+	return s.Updates(ctx, UpdatesParams{ID: params.ID, UserID: params.UserID})
+}
+
+// DisableMerge switches a note back to plain edits. The stored updates are removed and the merged text is kept.
+func (s *Service) DisableMerge(ctx context.Context, params MergeParams) error {
+	n, err := s.loadNote(ctx, authz.ActionWrite, params.ID, params.UserID)
+	if err != nil {
+		return err
+	}
+	n.Wipe()
+
+	if err := s.updates.Delete(ctx, noteupdate.DeleteParams{NoteID: params.ID, UserID: params.UserID}); err != nil {
+		return fmt.Errorf("failed to delete note updates: %w", mapError(err))
+	}
+	return nil
+}
+
+// PushUpdate merges a text update into a note edited in merge mode, stores it and saves the merged text as the
+// text of the note, so plain reads see the merged text. Merges of the same note run one after another.
+func (s *Service) PushUpdate(ctx context.Context, params PushUpdateParams) (*PushUpdateResult, error) {
+	u, err := notecrdt.Decode(params.Data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode note update: %w", mapError(err))
+	}
+	if u.IsSnapshot() {
+		return nil, fmt.Errorf("snapshots are made by compaction only: %w", ErrNoteUpdateInvalid)
+	}
+
+	// result holds the outcome of the merge committed by the unit of work.
+	var result *PushUpdateResult
+	err = s.uow.Do(ctx, params.UserID, func(ctx context.Context) error {
+		n, err := s.loadNote(ctx, authz.ActionWrite, params.ID, params.UserID)
+		if err != nil {
+			return err
+		}
+		defer n.Wipe()
+
+		doc, records, err := s.mergeStored(ctx, n.ID, params.UserID)
+		defer securebytes.WipeAll(records)
+		if err != nil {
+			return err
+		}
+		if len(records) == 0 {
+			return fmt.Errorf("note %s: %w", n.ID, ErrNoteNotInMergeMode)
+		}
+		if err := doc.Merge(u); err != nil {
+			return fmt.Errorf("failed to merge note update: %w", mapError(err))
+		}
+
+		data, err := notecrdt.Encode(u)
+		if err != nil {
+			return fmt.Errorf("failed to encode note update: %w", mapError(err))
+		}
+		defer securebytes.Wipe(data)
+		seq, err := s.updates.Save(ctx, noteupdate.SaveParams{Entity: newRecord(n, data, 0)})
+		if err != nil {
+			return fmt.Errorf("failed to save note update: %w", mapError(err))
+		}
+
+		securebytes.Wipe(n.Note)
+		n.Note = []byte(doc.Text())
+		n.UpdatedAt = time.Now()
+		if err := s.r.Save(ctx, repository.SaveParams{Entity: n}); err != nil {
+			return fmt.Errorf("failed to save merged note: %w", mapError(err))
+		}
+		result = &PushUpdateResult{Note: newNoteFromDomain(n), Seq: seq}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to push note update: %w", err)
+	}
+	return result, nil
+}
+
+// Updates retrieves the text updates of a note edited in merge mode in sequence order. A snapshot among them
+// replaces all updates up to its sequence number, so a client that merged some of those rebuilds its text from
+// the snapshot before merging the newer updates and its own unsent edits.
+func (s *Service) Updates(ctx context.Context, params UpdatesParams) ([]*Update, error) {
+	n, err := s.loadNote(ctx, authz.ActionRead, params.ID, params.UserID)
+	if err != nil {
+		return nil, err
+	}
+	n.Wipe()
+
+	records, err := s.updates.Load(ctx, noteupdate.LoadParams{
+		NoteID:   params.ID,
+		UserID:   params.UserID,
+		AfterSeq: params.AfterSeq,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load note updates: %w", mapError(err))
+	}
+	defer securebytes.WipeAll(records)
+
+	if len(records) == 0 {
+		merging, err := s.updates.Merging(ctx, noteupdate.MergingParams{
+			NoteIDs: []uuid.UUID{params.ID},
+			UserID:  params.UserID,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to find notes in merge mode: %w", mapError(err))
+		}
+		if len(merging) == 0 {
+			return nil, fmt.Errorf("note %s: %w", params.ID, ErrNoteNotInMergeMode)
+		}
+	}
+
+	updates := make([]*Update, 0, len(records))
+	for _, r := range records {
+		u, err := notecrdt.Decode(r.Data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode stored note update %d: %w", r.Seq, errors.Join(ErrNoteTechError, err))
+		}
+		updates = append(updates, &Update{Data: bytes.Clone(r.Data), Seq: r.Seq, Snapshot: u.IsSnapshot()})
+	}
+	return updates, nil
+}
+
+// Compact replaces the stored updates of every note with at least minUpdates of them (0 uses 100) by a single
+// snapshot of its text. Tombstones are kept without their text, so edits of devices that have not seen the
+// compacted updates still merge. Notes failing to compact are skipped; their errors are joined into the error.
+// It returns the number of compacted notes.
+func (s *Service) Compact(ctx context.Context, minUpdates int) (int, error) {
+	if minUpdates <= 0 {
+		minUpdates = defaultCompactThreshold
+	}
+	refs, err := s.updates.Compactable(ctx, noteupdate.CompactableParams{MinUpdates: minUpdates})
+	if err != nil {
+		return 0, fmt.Errorf("failed to find compactable notes: %w", mapError(err))
+	}
+
+	compacted := 0
+	// errs collects the errors of notes failing to compact.
+	var errs []error
+	for _, ref := range refs {
+		ok, err := s.compactNote(ctx, ref)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to compact note %s: %w", ref.NoteID, err))
+			continue
+		}
+		if ok {
+			compacted++
+		}
+	}
+	return compacted, errors.Join(errs...)
+}
+
+// compactNote replaces the stored updates of the note by a snapshot with the sequence number of the last of
+// them. It reports false when the note has fewer than two updates left to compact.
+func (s *Service) compactNote(ctx context.Context, ref noteupdate.NoteRef) (bool, error) {
+	compacted := false
+	err := s.uow.Do(ctx, ref.UserID, func(ctx context.Context) error {
+		doc, records, err := s.mergeStored(ctx, ref.NoteID, ref.UserID)
+		defer securebytes.WipeAll(records)
+		if err != nil {
+			return err
+		}
+		if len(records) < 2 {
+			return nil
+		}
+
+		data, err := notecrdt.Encode(doc.Snapshot())
+		if err != nil {
+			return fmt.Errorf("failed to encode note snapshot: %w", mapError(err))
+		}
+		defer securebytes.Wipe(data)
+
+		last := records[len(records)-1].Seq
+		if err := s.updates.Delete(ctx, noteupdate.DeleteParams{
+			NoteID:  ref.NoteID,
+			UserID:  ref.UserID,
+			UpToSeq: last,
+		}); err != nil {
+			return fmt.Errorf("failed to delete compacted note updates: %w", mapError(err))
+		}
+		snapshot := newRecord(&note.Note{ID: ref.NoteID, UserID: ref.UserID}, data, last)
+		if _, err := s.updates.Save(ctx, noteupdate.SaveParams{Entity: snapshot}); err != nil {
+			return fmt.Errorf("failed to save note snapshot: %w", mapError(err))
+		}
+		compacted = true
+		return nil
+	})
+	return compacted, err
+}
+
+// mergeStored locks the note and merges its stored updates into a new replicated text. The loaded records are
+// returned so the caller can inspect and wipe them.
+func (s *Service) mergeStored(
+	ctx context.Context,
+	noteID, userID uuid.UUID,
+) (*notecrdt.Doc, []*notecrdt.Record, error) {
+	records, err := s.updates.Load(ctx, noteupdate.LoadParams{NoteID: noteID, UserID: userID, Lock: true})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load note updates: %w", mapError(err))
+	}
+
+	doc := notecrdt.NewDoc()
+	for _, r := range records {
+		u, err := notecrdt.Decode(r.Data)
+		if err == nil {
+			err = doc.Merge(u)
+		}
+		if err != nil {
+			return nil, records, fmt.Errorf("failed to merge stored note update %d: %w",
+				r.Seq, errors.Join(ErrNoteTechError, err))
+		}
+	}
+	return doc, records, nil
+}
+
+// loadNote loads a note of the user and checks that the user may perform the action on it.
+// The caller wipes the returned note once done.
+func (s *Service) loadNote(ctx context.Context, action string, id, userID uuid.UUID) (*note.Note, error) {
+	notes, err := s.r.Load(ctx, repository.LoadParams{ID: id, UserID: userID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load note: %w", mapError(err))
+	}
+	if len(notes) == 0 {
+		return nil, fmt.Errorf("note not found: %w", ErrNoteNotFound)
+	}
+	if err := s.authorize(ctx, action, notes[0].ID, notes[0].UserID, userID); err != nil {
+		notes[0].Wipe()
+		return nil, err
+	}
+	return notes[0], nil
+}
+
+// newRecord creates the record of an update of the note; a zero seq is assigned by the repository.
+func newRecord(n *note.Note, data []byte, seq int64) *notecrdt.Record {
+	return &notecrdt.Record{
+		ID:        uuid.New(),
+		NoteID:    n.ID,
+		UserID:    n.UserID,
+		Data:      data,
+		Seq:       seq,
+		CreatedAt: time.Now(),
+	}
+}
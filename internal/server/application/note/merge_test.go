@@ -0,0 +1,235 @@
+package note
+
+import (
+	"bytes"
+	"context"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/note"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/notecrdt"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/note"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/noteupdate"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memUpdateRepository keeps note updates in memory for testing.
+type memUpdateRepository struct {
+	records []*notecrdt.Record
+	seq     int64
+	mu      sync.Mutex
+}
+
+func (m *memUpdateRepository) Save(_ context.Context, params noteupdate.SaveParams) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	r := *params.Entity
+	r.Data = bytes.Clone(r.Data)
+	if r.Seq == 0 {
+		m.seq++
+		r.Seq = m.seq
+	}
+	m.records = append(m.records, &r)
+	slices.SortFunc(m.records, func(a, b *notecrdt.Record) int { return int(a.Seq - b.Seq) })
+	return r.Seq, nil
+}
+
+func (m *memUpdateRepository) Load(_ context.Context, params noteupdate.LoadParams) ([]*notecrdt.Record, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var result []*notecrdt.Record
+	for _, r := range m.records {
+		if r.NoteID == params.NoteID && r.UserID == params.UserID && r.Seq > params.AfterSeq {
+			c := *r
+			c.Data = bytes.Clone(r.Data)
+			result = append(result, &c)
+		}
+	}
+	return result, nil
+}
+
+func (m *memUpdateRepository) Delete(_ context.Context, params noteupdate.DeleteParams) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.records = slices.DeleteFunc(m.records, func(r *notecrdt.Record) bool {
+		return r.NoteID == params.NoteID && (params.UpToSeq == 0 || r.Seq <= params.UpToSeq)
+	})
+	return nil
+}
+
+func (m *memUpdateRepository) Merging(_ context.Context, params noteupdate.MergingParams) ([]uuid.UUID, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var ids []uuid.UUID
+	for _, r := range m.records {
+		if slices.Contains(params.NoteIDs, r.NoteID) && !slices.Contains(ids, r.NoteID) {
+			ids = append(ids, r.NoteID)
+		}
+	}
+	return ids, nil
+}
+
+func (m *memUpdateRepository) Compactable(
+	_ context.Context,
+	params noteupdate.CompactableParams,
+) ([]noteupdate.NoteRef, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	counts := make(map[noteupdate.NoteRef]int)
+	for _, r := range m.records {
+		counts[noteupdate.NoteRef{NoteID: r.NoteID, UserID: r.UserID}]++
+	}
+	var refs []noteupdate.NoteRef
+	for ref, n := range counts {
+		if n >= params.MinUpdates {
+			refs = append(refs, ref)
+		}
+	}
+	return refs, nil
+}
+
+// memNoteRepository keeps a single note in memory for testing.
+func memNoteRepository(n *note.Note) *MockRepository {
+	var mu sync.Mutex
+	return &MockRepository{
+		LoadFunc: func(_ context.Context, params repository.LoadParams) ([]*note.Note, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			if params.UserID != n.UserID || (params.ID != n.ID && !slices.Contains(params.IDs, n.ID)) {
+				return nil, nil
+			}
+			c := *n
+			c.Note = bytes.Clone(n.Note)
+			c.Description = bytes.Clone(n.Description)
+			return []*note.Note{&c}, nil
+		},
+		SaveFunc: func(_ context.Context, params repository.SaveParams) error {
+			mu.Lock()
+			defer mu.Unlock()
+			n.Note = bytes.Clone(params.Entity.Note)
+			n.Description = bytes.Clone(params.Entity.Description)
+			n.UpdatedAt = params.Entity.UpdatedAt
+			return nil
+		},
+	}
+}
+
+// encodeOps returns the encoded update of the operations.
+func encodeOps(t *testing.T, ops ...notecrdt.Op) []byte {
+	t.Helper()
+	data, err := notecrdt.Encode(notecrdt.Update{Ops: ops})
+	require.NoError(t, err)
+	return data
+}
+
+// insertAfter returns an operation inserting the value after the element.
+func insertAfter(replica string, counter uint64, after notecrdt.ID, value string) notecrdt.Op {
+	id := notecrdt.ID{Replica: replica, Counter: counter}
+	return notecrdt.Op{Insert: &notecrdt.Insert{ID: id, After: after, Value: value}}
+}
+
+func TestService_Merge(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	n := &note.Note{ID: uuid.New(), UserID: userID, Note: []byte("ac"), Description: []byte("shopping")}
+	updates := &memUpdateRepository{}
+	s := NewService(memNoteRepository(n), updates, inlineUnitOfWork{}, ownerAuthorizer{}, nil, nil, nil, nil)
+	ctx := context.Background()
+	params := MergeParams{ID: n.ID, UserID: userID}
+
+	_, err := s.PushUpdate(ctx, PushUpdateParams{ID: n.ID, UserID: userID, Data: []byte(`{"ops":[]}`)})
+	require.ErrorIs(t, err, ErrNoteNotInMergeMode)
+
+	seed, err := s.EnableMerge(ctx, params)
+	require.NoError(t, err)
+	require.Len(t, seed, 1)
+	again, err := s.EnableMerge(ctx, params)
+	require.NoError(t, err)
+	assert.Equal(t, seed, again, "enabling twice keeps the stored updates")
+
+	// Two devices insert at the same place concurrently, unaware of each other.
+	a := notecrdt.ID{Replica: notecrdt.SeedReplica, Counter: 1}
+	res, err := s.PushUpdate(ctx, PushUpdateParams{ID: n.ID, UserID: userID, Data: encodeOps(t,
+		insertAfter("laptop", 3, a, "1"))})
+	require.NoError(t, err)
+	assert.Equal(t, "a1c", res.Note.Note)
+	res, err = s.PushUpdate(ctx, PushUpdateParams{ID: n.ID, UserID: userID, Data: encodeOps(t,
+		insertAfter("phone", 3, a, "2"), notecrdt.Op{Delete: &notecrdt.ID{Replica: notecrdt.SeedReplica, Counter: 2}})})
+	require.NoError(t, err)
+	assert.Equal(t, "a21", res.Note.Note)
+	assert.Equal(t, "shopping", res.Note.Description)
+	assert.Equal(t, "a21", string(n.Note), "merged text is saved as the note text")
+
+	_, err = s.PushUpdate(ctx, PushUpdateParams{ID: n.ID, UserID: userID, Data: encodeOps(t,
+		insertAfter("phone", 9, notecrdt.ID{Replica: "tablet", Counter: 8}, "x"))})
+	require.ErrorIs(t, err, ErrNoteUpdateInvalid)
+
+	newer, err := s.Updates(ctx, UpdatesParams{ID: n.ID, UserID: userID, AfterSeq: seed[0].Seq})
+	require.NoError(t, err)
+	require.Len(t, newer, 2)
+
+	// Plain edits of the text are rejected while description changes pass.
+	_, err = s.Push(ctx, &PushParams{ID: n.ID, UserID: userID, Note: "overwrite"})
+	require.ErrorIs(t, err, ErrNoteMergeMode)
+	_, err = s.Push(ctx, &PushParams{ID: n.ID, UserID: userID, Note: "a21", Description: "groceries"})
+	require.NoError(t, err)
+
+	compacted, err := s.Compact(ctx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 1, compacted)
+	all, err := s.Updates(ctx, UpdatesParams{ID: n.ID, UserID: userID})
+	require.NoError(t, err)
+	require.Len(t, all, 1)
+	assert.True(t, all[0].Snapshot)
+	assert.Equal(t, newer[1].Seq, all[0].Seq, "the snapshot keeps the sequence number of the last update")
+
+	// Edits referencing elements merged before the compaction still merge.
+	res, err = s.PushUpdate(ctx, PushUpdateParams{ID: n.ID, UserID: userID, Data: encodeOps(t,
+		insertAfter("laptop", 4, notecrdt.ID{Replica: "laptop", Counter: 3}, "!"))})
+	require.NoError(t, err)
+	assert.Equal(t, "a21!", res.Note.Note)
+
+	require.NoError(t, s.DisableMerge(ctx, params))
+	_, err = s.Updates(ctx, UpdatesParams{ID: n.ID, UserID: userID})
+	require.ErrorIs(t, err, ErrNoteNotInMergeMode)
+	assert.Equal(t, "a21!", string(n.Note), "the merged text is kept")
+}
+
+func TestService_PushUpdate_Rejected(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	n := &note.Note{ID: uuid.New(), UserID: userID, Note: []byte("text")}
+
+	tests := []struct {
+		wantErr error
+		name    string
+		userID  uuid.UUID
+		data    []byte
+	}{
+		{name: "malformed update", userID: userID, data: []byte(`{"move":[]}`), wantErr: ErrNoteUpdateInvalid},
+		{
+			name:    "snapshot",
+			userID:  userID,
+			data:    []byte(`{"snapshot":[{"id":{"replica":"phone","counter":1},"value":"x"}]}`),
+			wantErr: ErrNoteUpdateInvalid,
+		},
+		{name: "other user", userID: uuid.New(), data: []byte(`{"ops":[]}`), wantErr: ErrNoteNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := NewService(
+				memNoteRepository(n), &memUpdateRepository{}, inlineUnitOfWork{}, ownerAuthorizer{}, nil, nil, nil, nil,
+			)
+			_, err := s.PushUpdate(context.Background(), PushUpdateParams{ID: n.ID, UserID: tt.userID, Data: tt.data})
+			require.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
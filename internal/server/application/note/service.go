@@ -1,12 +1,25 @@
 package note
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
+	"slices"
+	"time"
 
+	authzApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/authz"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/authz"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/itemorder"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/itempath"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/itemtag"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/note"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/notecrdt"
+	orderRepository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/itemorder"
+	tagRepository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/itemtag"
 	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/note"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/noteupdate"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/securebytes"
 	"github.com/google/uuid"
 )
 
@@ -15,45 +28,197 @@ type Repository interface {
 	// Save persists a note entity using the provided parameters.
 	Save(ctx context.Context, params repository.SaveParams) error
 
+	// SaveBatch persists several note entities of one user at once.
+	SaveBatch(ctx context.Context, params repository.SaveBatchParams) error
+
 	// Load retrieves note entities using the provided parameters.
 	Load(ctx context.Context, params repository.LoadParams) ([]*note.Note, error)
 }
 
+// UpdateRepository defines the interface for persistence of the text updates of notes edited in merge mode.
+type UpdateRepository interface {
+	// Save persists an update and returns its sequence number.
+	Save(ctx context.Context, params noteupdate.SaveParams) (int64, error)
+
+	// Load retrieves the updates of a note in sequence order.
+	Load(ctx context.Context, params noteupdate.LoadParams) ([]*notecrdt.Record, error)
+
+	// Delete removes the updates of a note.
+	Delete(ctx context.Context, params noteupdate.DeleteParams) error
+
+	// Merging returns which of the notes are edited in merge mode.
+	Merging(ctx context.Context, params noteupdate.MergingParams) ([]uuid.UUID, error)
+
+	// Compactable returns the notes of all users with at least the minimum number of stored updates.
+	Compactable(ctx context.Context, params noteupdate.CompactableParams) ([]noteupdate.NoteRef, error)
+}
+
+// UnitOfWork defines the interface for running several repository writes as one transaction.
+type UnitOfWork interface {
+	// Do executes fn in a single transaction scoped to the user, rolling back every write on error.
+	Do(ctx context.Context, userID uuid.UUID, fn func(ctx context.Context) error) error
+}
+
+// Authorizer defines the interface for the authorization decision point.
+type Authorizer interface {
+	// Authorize decides whether the user may perform the action on the note.
+	Authorize(ctx context.Context, req authzApp.Request) error
+}
+
+// PlanEnforcer defines the interface for checking the limits of the plans of users.
+type PlanEnforcer interface {
+	// CheckItems ensures that the plan of the user has room for the given number of additional items.
+	CheckItems(ctx context.Context, userID uuid.UUID, added int) error
+}
+
+// PolicyEnforcer defines the interface for checking vault items against the item policies of users.
+type PolicyEnforcer interface {
+	// CheckItem ensures that the item policies of the user allow storing items of the kind.
+	CheckItem(ctx context.Context, userID uuid.UUID, kind string) error
+}
+
+// TagRepository defines the interface for looking up the tags of vault items.
+type TagRepository interface {
+	// Load retrieves the tags of the items of the user matching the parameters.
+	Load(ctx context.Context, params tagRepository.LoadParams) ([]*itemtag.ItemTags, error)
+}
+
+// OrderRepository defines the interface for looking up the manual orders of folders.
+type OrderRepository interface {
+	// Load retrieves the manual order of a folder of the user.
+	Load(ctx context.Context, params orderRepository.LoadParams) (*itemorder.ItemOrder, error)
+}
+
 // Service provides note management business logic operations.
 type Service struct {
 	// r is the repository interface for note data persistence operations.
 	r Repository
+	// updates is the repository of the text updates of notes edited in merge mode.
+	updates UpdateRepository
+	// uow groups the writes of merges into a single transaction.
+	uow UnitOfWork
+	// authorizer decides whether users may perform actions on notes.
+	authorizer Authorizer
+	// plans checks the plan limits of new notes; nil leaves them unlimited.
+	plans PlanEnforcer
+	// policies checks created and updated notes against the item policies; nil enforces none.
+	policies PolicyEnforcer
+	// tags looks up archived notes; nil archives none.
+	tags TagRepository
+	// orders looks up the manual orders of folders; nil keeps every listing in its default order.
+	orders OrderRepository
 }
 
-// NewService creates a new note service instance with the provided repository.
-func NewService(r Repository) *Service {
-	return &Service{r: r}
+// NewService creates a new note service instance with the provided repositories, unit of work, authorization
+// decision point, plan enforcer (nil means no plan limits), item policy enforcer (nil means no item policies),
+// tag repository (nil means no archived notes) and order repository (nil means no manual orders).
+func NewService(
+	r Repository,
+	updates UpdateRepository,
+	uow UnitOfWork,
+	authorizer Authorizer,
+	plans PlanEnforcer,
+	policies PolicyEnforcer,
+	tags TagRepository,
+	orders OrderRepository,
+) *Service {
+	return &Service{
+		r:          r,
+		updates:    updates,
+		uow:        uow,
+		authorizer: authorizer,
+		plans:      plans,
+		policies:   policies,
+		tags:       tags,
+		orders:     orders,
+	}
 }
 
 // Pull retrieves a specific note for the given user.
 func (s *Service) Pull(ctx context.Context, params PullParams) (*Note, error) {
 	notes, err := s.r.Load(ctx, repository.LoadParams{
+		AsOf:   params.AsOf,
+		Fields: params.Fields,
 		ID:     params.ID,
 		UserID: params.UserID,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to load notes: %w", mapError(err))
 	}
+	defer securebytes.WipeAll(notes)
 	if len(notes) == 0 {
 		return nil, fmt.Errorf("note not found: %w", ErrNoteNotFound)
 	}
+	if err := s.authorize(ctx, authz.ActionRead, notes[0].ID, notes[0].UserID, params.UserID); err != nil {
+		return nil, err
+	}
 	return newNoteFromDomain(notes[0]), nil
 }
 
 // List retrieves all notes for the specified user.
 func (s *Service) List(ctx context.Context, params ListParams) ([]*Note, error) {
+	if err := s.authorize(ctx, authz.ActionRead, uuid.Nil, params.UserID, params.UserID); err != nil {
+		return nil, err
+	}
 	notes, err := s.r.Load(ctx, repository.LoadParams{
+		After:  params.After,
+		AsOf:   params.AsOf,
+		Fields: params.Fields,
+		Limit:  params.Limit,
 		UserID: params.UserID,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to load notes: %w", mapError(err))
 	}
-	return newNotesFromDomain(notes), nil
+	defer securebytes.WipeAll(notes)
+
+	archived, err := s.archived(ctx, params.UserID)
+	if err != nil {
+		return nil, err
+	}
+	result := newNotesFromDomain(notes)
+	for _, n := range result {
+		n.Archived = archived[n.ID]
+	}
+	if params.ExcludeArchived {
+		result = slices.DeleteFunc(result, func(n *Note) bool { return n.Archived })
+	}
+	if params.ManualOrder {
+		order, err := s.order(ctx, params.UserID, params.OrderFolder)
+		if err != nil {
+			return nil, err
+		}
+		itemorder.Sort(order, result, func(n *Note) uuid.UUID { return n.ID })
+	}
+	return result, nil
+}
+
+// archived returns the IDs of the archived notes of the user; without a tag repository none is archived.
+func (s *Service) archived(ctx context.Context, userID uuid.UUID) (map[uuid.UUID]bool, error) {
+	if s.tags == nil {
+		return nil, nil
+	}
+	tagged, err := s.tags.Load(ctx, tagRepository.LoadParams{Tag: itemtag.Archived, UserID: userID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load archived notes: %w", mapError(err))
+	}
+	return itemtag.ItemIDs(tagged), nil
+}
+
+// order returns the manual order of the folder of the user; without an order repository, or for a folder the user
+// has not arranged, there is none.
+func (s *Service) order(ctx context.Context, userID uuid.UUID, folder string) (*itemorder.ItemOrder, error) {
+	if s.orders == nil {
+		return nil, nil
+	}
+	o, err := s.orders.Load(ctx, orderRepository.LoadParams{Folder: itempath.Normalize(folder), UserID: userID})
+	if errors.Is(err, orderRepository.ErrOrderNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load item order: %w", mapError(err))
+	}
+	return o, nil
 }
 
 // Push creates or updates a note for the specified user.
@@ -66,12 +231,26 @@ func (s *Service) Push(ctx context.Context, params *PushParams) (uuid.UUID, erro
 	if err != nil {
 		return uuid.Nil, fmt.Errorf("failed to create new note: %w", mapError(err))
 	}
+	defer n.Wipe()
 
 	if params.ID != uuid.Nil {
 		if err := s.checkAccessToUpdate(ctx, params.ID, params.UserID); err != nil {
 			return uuid.Nil, fmt.Errorf("access check for updating note failed: %w", err)
 		}
 		n.ID = params.ID
+		if err := s.checkMergeMode(ctx, params.UserID, []*note.Note{n}); err != nil {
+			return uuid.Nil, err
+		}
+	} else {
+		if err := s.authorize(ctx, authz.ActionWrite, uuid.Nil, params.UserID, params.UserID); err != nil {
+			return uuid.Nil, fmt.Errorf("access check for creating note failed: %w", err)
+		}
+		if err := s.checkPlan(ctx, params.UserID, 1); err != nil {
+			return uuid.Nil, fmt.Errorf("plan check for creating note failed: %w", err)
+		}
+	}
+	if err := s.checkPolicy(ctx, params.UserID); err != nil {
+		return uuid.Nil, fmt.Errorf("item policy check for note failed: %w", err)
 	}
 
 	if err := s.r.Save(ctx, repository.SaveParams{Entity: n}); err != nil {
@@ -80,17 +259,201 @@ func (s *Service) Push(ctx context.Context, params *PushParams) (uuid.UUID, erro
 	return n.ID, nil
 }
 
-// checkAccessToUpdate verifies that the user has permission to update the specified note.
+// PushBatch creates or updates several notes of one user and returns their IDs in input order.
+// The notes to update are checked with a single query and everything is saved with batch statements, which
+// keeps large sync pushes fast. When several items share an ID, the last one wins.
+func (s *Service) PushBatch(ctx context.Context, params PushBatchParams) ([]uuid.UUID, error) {
+	notes := make([]*note.Note, 0, len(params.Items))
+	defer func() { securebytes.WipeAll(notes) }()
+	ids := make([]uuid.UUID, 0, len(params.Items))
+	// updateIDs collects the IDs of existing notes the batch updates.
+	var updateIDs []uuid.UUID
+	for i, item := range params.Items {
+		n, err := note.NewNote(note.NewNoteParams{
+			UserID:      params.UserID,
+			Note:        item.Note,
+			Description: item.Description,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create new note at index %d: %w", i, mapError(err))
+		}
+		if item.ID != uuid.Nil {
+			n.ID = item.ID
+			updateIDs = append(updateIDs, item.ID)
+		}
+		notes = append(notes, n)
+		ids = append(ids, n.ID)
+	}
+
+	if err := s.checkAccessToUpdateBatch(ctx, updateIDs, params.UserID); err != nil {
+		return nil, fmt.Errorf("access check for updating notes failed: %w", err)
+	}
+	if len(updateIDs) < len(params.Items) {
+		if err := s.authorize(ctx, authz.ActionWrite, uuid.Nil, params.UserID, params.UserID); err != nil {
+			return nil, fmt.Errorf("access check for creating notes failed: %w", err)
+		}
+		if err := s.checkPlan(ctx, params.UserID, len(params.Items)-len(updateIDs)); err != nil {
+			return nil, fmt.Errorf("plan check for creating notes failed: %w", err)
+		}
+	}
+	if len(params.Items) != 0 {
+		if err := s.checkPolicy(ctx, params.UserID); err != nil {
+			return nil, fmt.Errorf("item policy check for notes failed: %w", err)
+		}
+	}
+
+	// latest holds the last note pushed for every ID, since a statement cannot update a row twice.
+	latest := make([]*note.Note, 0, len(notes))
+	seen := make(map[uuid.UUID]struct{}, len(notes))
+	for _, n := range slices.Backward(notes) {
+		if _, ok := seen[n.ID]; ok {
+			continue
+		}
+		seen[n.ID] = struct{}{}
+		latest = append(latest, n)
+	}
+	slices.Reverse(latest)
+	if len(updateIDs) > 0 {
+		if err := s.checkMergeMode(ctx, params.UserID, latest); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.r.SaveBatch(ctx, repository.SaveBatchParams{Entities: latest, UserID: params.UserID}); err != nil {
+		return nil, fmt.Errorf("failed to save notes: %w", mapError(err))
+	}
+	return ids, nil
+}
+
+// Recover makes the note version current at the specified moment the latest version again.
+// The recovered version is saved as a new revision, so the replaced state stays retained.
+func (s *Service) Recover(ctx context.Context, params RecoverParams) (*Note, error) {
+	notes, err := s.r.Load(ctx, repository.LoadParams{
+		AsOf:   params.AsOf,
+		ID:     params.ID,
+		UserID: params.UserID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load note version: %w", mapError(err))
+	}
+	defer securebytes.WipeAll(notes)
+	if len(notes) == 0 {
+		return nil, fmt.Errorf("note version not found: %w", ErrNoteNotFound)
+	}
+	if err := s.authorize(ctx, authz.ActionWrite, notes[0].ID, notes[0].UserID, params.UserID); err != nil {
+		return nil, err
+	}
+
+	n := notes[0]
+	if err := s.checkMergeMode(ctx, params.UserID, []*note.Note{n}); err != nil {
+		return nil, err
+	}
+	n.UpdatedAt = time.Now()
+	if err := s.r.Save(ctx, repository.SaveParams{Entity: n}); err != nil {
+		return nil, fmt.Errorf("failed to save recovered note: %w", mapError(err))
+	}
+	return newNoteFromDomain(n), nil
+}
+
+// checkAccessToUpdate verifies that the note to update exists and that the user may change it.
 func (s *Service) checkAccessToUpdate(ctx context.Context, noteID, userID uuid.UUID) error {
-	exists, err := s.Pull(ctx, PullParams{ID: noteID, UserID: userID})
+	existing, err := s.r.Load(ctx, repository.LoadParams{ID: noteID, UserID: userID})
 	if err != nil {
-		if errors.Is(err, ErrNoteNotFound) {
-			return fmt.Errorf("note for update not found: %w", err)
-		}
 		return fmt.Errorf("failed to pull existing note: %w", mapError(err))
 	}
-	if exists.UserID != userID {
-		return fmt.Errorf("access denied to note: %w", ErrNoteAccessDenied)
+	defer securebytes.WipeAll(existing)
+	if len(existing) == 0 {
+		return fmt.Errorf("note for update not found: %w", ErrNoteNotFound)
+	}
+	return s.authorize(ctx, authz.ActionWrite, existing[0].ID, existing[0].UserID, userID)
+}
+
+// checkAccessToUpdateBatch verifies with a single query that all notes to update exist and that the user may
+// change them.
+func (s *Service) checkAccessToUpdateBatch(ctx context.Context, ids []uuid.UUID, userID uuid.UUID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	existing, err := s.r.Load(ctx, repository.LoadParams{IDs: ids, UserID: userID})
+	if err != nil {
+		return fmt.Errorf("failed to load existing notes: %w", mapError(err))
+	}
+	defer securebytes.WipeAll(existing)
+	owned := make(map[uuid.UUID]struct{}, len(existing))
+	for _, e := range existing {
+		if err := s.authorize(ctx, authz.ActionWrite, e.ID, e.UserID, userID); err != nil {
+			return err
+		}
+		owned[e.ID] = struct{}{}
+	}
+	for _, id := range ids {
+		if _, ok := owned[id]; !ok {
+			return fmt.Errorf("note %s for update not found: %w", id, ErrNoteNotFound)
+		}
+	}
+	return nil
+}
+
+// checkMergeMode rejects plain changes of the text of notes edited in merge mode, whose text is derived from
+// their updates. Pushing the current text, for instance to change the description only, is allowed.
+func (s *Service) checkMergeMode(ctx context.Context, userID uuid.UUID, notes []*note.Note) error {
+	ids := make([]uuid.UUID, 0, len(notes))
+	for _, n := range notes {
+		ids = append(ids, n.ID)
+	}
+	merging, err := s.updates.Merging(ctx, noteupdate.MergingParams{NoteIDs: ids, UserID: userID})
+	if err != nil {
+		return fmt.Errorf("failed to find notes in merge mode: %w", mapError(err))
+	}
+	if len(merging) == 0 {
+		return nil
+	}
+
+	current, err := s.r.Load(ctx, repository.LoadParams{Fields: []string{"note"}, IDs: merging, UserID: userID})
+	if err != nil {
+		return fmt.Errorf("failed to load notes in merge mode: %w", mapError(err))
+	}
+	defer securebytes.WipeAll(current)
+	texts := make(map[uuid.UUID][]byte, len(current))
+	for _, c := range current {
+		texts[c.ID] = c.Note
+	}
+	for _, n := range notes {
+		if text, ok := texts[n.ID]; ok && !bytes.Equal(text, n.Note) {
+			return fmt.Errorf("note %s: %w", n.ID, ErrNoteMergeMode)
+		}
+	}
+	return nil
+}
+
+// checkPlan ensures that the plan of the user has room for the notes being added.
+func (s *Service) checkPlan(ctx context.Context, userID uuid.UUID, added int) error {
+	if s.plans == nil {
+		return nil
+	}
+	return s.plans.CheckItems(ctx, userID, added)
+}
+
+// checkPolicy ensures that the item policies of the user allow storing notes.
+func (s *Service) checkPolicy(ctx context.Context, userID uuid.UUID) error {
+	if s.policies == nil {
+		return nil
+	}
+	return s.policies.CheckItem(ctx, userID, authz.KindNote)
+}
+
+// authorize asks the authorization decision point whether the user may perform the action on a note of the owner.
+// The noteID is uuid.Nil for new notes and for the whole collection.
+func (s *Service) authorize(ctx context.Context, action string, noteID, ownerID, userID uuid.UUID) error {
+	err := s.authorizer.Authorize(ctx, authzApp.Request{
+		Action:     action,
+		Kind:       authz.KindNote,
+		ResourceID: noteID,
+		OwnerID:    ownerID,
+		SubjectID:  userID,
+	})
+	if err != nil {
+		return fmt.Errorf("access denied to note %s: %w: %w", noteID, ErrNoteAccessDenied, err)
 	}
 	return nil
 }
@@ -6,8 +6,15 @@ import (
 	"testing"
 	"time"
 
+	authzApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/authz"
+	itempolicyApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/itempolicy"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/authz"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/itemtag"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/note"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/notecrdt"
+	tagRepository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/itemtag"
 	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/note"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/noteupdate"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -15,8 +22,9 @@ import (
 
 // MockRepository implements Repository interface for testing.
 type MockRepository struct {
-	SaveFunc func(ctx context.Context, params repository.SaveParams) error
-	LoadFunc func(ctx context.Context, params repository.LoadParams) ([]*note.Note, error)
+	SaveFunc      func(ctx context.Context, params repository.SaveParams) error
+	SaveBatchFunc func(ctx context.Context, params repository.SaveBatchParams) error
+	LoadFunc      func(ctx context.Context, params repository.LoadParams) ([]*note.Note, error)
 }
 
 func (m *MockRepository) Save(ctx context.Context, params repository.SaveParams) error {
@@ -26,6 +34,13 @@ func (m *MockRepository) Save(ctx context.Context, params repository.SaveParams)
 	return nil
 }
 
+func (m *MockRepository) SaveBatch(ctx context.Context, params repository.SaveBatchParams) error {
+	if m.SaveBatchFunc != nil {
+		return m.SaveBatchFunc(ctx, params)
+	}
+	return nil
+}
+
 func (m *MockRepository) Load(ctx context.Context, params repository.LoadParams) ([]*note.Note, error) {
 	if m.LoadFunc != nil {
 		return m.LoadFunc(ctx, params)
@@ -33,6 +48,91 @@ func (m *MockRepository) Load(ctx context.Context, params repository.LoadParams)
 	return nil, nil
 }
 
+// mockUpdateRepository implements UpdateRepository interface for testing.
+type mockUpdateRepository struct {
+	saveFunc        func(params noteupdate.SaveParams) (int64, error)
+	loadFunc        func(params noteupdate.LoadParams) ([]*notecrdt.Record, error)
+	deleteFunc      func(params noteupdate.DeleteParams) error
+	mergingFunc     func(params noteupdate.MergingParams) ([]uuid.UUID, error)
+	compactableFunc func(params noteupdate.CompactableParams) ([]noteupdate.NoteRef, error)
+}
+
+func (m *mockUpdateRepository) Save(_ context.Context, params noteupdate.SaveParams) (int64, error) {
+	if m.saveFunc != nil {
+		return m.saveFunc(params)
+	}
+	return 1, nil
+}
+
+func (m *mockUpdateRepository) Load(_ context.Context, params noteupdate.LoadParams) ([]*notecrdt.Record, error) {
+	if m.loadFunc != nil {
+		return m.loadFunc(params)
+	}
+	return nil, nil
+}
+
+func (m *mockUpdateRepository) Delete(_ context.Context, params noteupdate.DeleteParams) error {
+	if m.deleteFunc != nil {
+		return m.deleteFunc(params)
+	}
+	return nil
+}
+
+func (m *mockUpdateRepository) Merging(_ context.Context, params noteupdate.MergingParams) ([]uuid.UUID, error) {
+	if m.mergingFunc != nil {
+		return m.mergingFunc(params)
+	}
+	return nil, nil
+}
+
+func (m *mockUpdateRepository) Compactable(
+	_ context.Context,
+	params noteupdate.CompactableParams,
+) ([]noteupdate.NoteRef, error) {
+	if m.compactableFunc != nil {
+		return m.compactableFunc(params)
+	}
+	return nil, nil
+}
+
+// inlineUnitOfWork runs units of work directly without a transaction.
+type inlineUnitOfWork struct{}
+
+func (inlineUnitOfWork) Do(ctx context.Context, _ uuid.UUID, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// ownerAuthorizer permits users to act on their own notes only, like the default authorization policy.
+type ownerAuthorizer struct{}
+
+func (ownerAuthorizer) Authorize(_ context.Context, req authzApp.Request) error {
+	if req.OwnerID != req.SubjectID {
+		return authzApp.ErrAccessDenied
+	}
+	return nil
+}
+
+// mockPolicyEnforcer implements PolicyEnforcer for testing, recording the checked kinds.
+type mockPolicyEnforcer struct {
+	err   error
+	kinds []string
+}
+
+func (m *mockPolicyEnforcer) CheckItem(_ context.Context, _ uuid.UUID, kind string) error {
+	m.kinds = append(m.kinds, kind)
+	return m.err
+}
+
+// denyAuthorizer denies every request and records the requests decided.
+type denyAuthorizer struct {
+	reqs []authzApp.Request
+}
+
+func (m *denyAuthorizer) Authorize(_ context.Context, req authzApp.Request) error {
+	m.reqs = append(m.reqs, req)
+	return authzApp.ErrAccessDenied
+}
+
 func TestNewService(t *testing.T) {
 	t.Parallel()
 
@@ -58,7 +158,7 @@ func TestNewService(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			got := NewService(tt.repo)
+			got := NewService(tt.repo, &mockUpdateRepository{}, inlineUnitOfWork{}, ownerAuthorizer{}, nil, nil, nil, nil)
 			require.NotNil(t, got)
 			assert.Equal(t, tt.repo, got.r)
 		})
@@ -150,7 +250,7 @@ func TestService_Pull(t *testing.T) {
 				tt.setupMock(mockRepo)
 			}
 
-			service := NewService(mockRepo)
+			service := NewService(mockRepo, &mockUpdateRepository{}, inlineUnitOfWork{}, ownerAuthorizer{}, nil, nil, nil, nil)
 			got, err := service.Pull(context.Background(), tt.params)
 
 			if tt.wantErr {
@@ -262,7 +362,7 @@ func TestService_List(t *testing.T) {
 				tt.setupMock(mockRepo)
 			}
 
-			service := NewService(mockRepo)
+			service := NewService(mockRepo, &mockUpdateRepository{}, inlineUnitOfWork{}, ownerAuthorizer{}, nil, nil, nil, nil)
 			got, err := service.List(context.Background(), tt.params)
 
 			if tt.wantErr {
@@ -424,7 +524,7 @@ func TestService_Push(t *testing.T) {
 				tt.setupMock(mockRepo)
 			}
 
-			service := NewService(mockRepo)
+			service := NewService(mockRepo, &mockUpdateRepository{}, inlineUnitOfWork{}, ownerAuthorizer{}, nil, nil, nil, nil)
 			gotID, err := service.Push(context.Background(), tt.params)
 
 			if tt.wantErr {
@@ -525,7 +625,7 @@ func TestService_checkAccessToUpdate(t *testing.T) {
 				tt.setupMock(mockRepo)
 			}
 
-			service := NewService(mockRepo)
+			service := NewService(mockRepo, &mockUpdateRepository{}, inlineUnitOfWork{}, ownerAuthorizer{}, nil, nil, nil, nil)
 			err := service.checkAccessToUpdate(context.Background(), tt.noteID, tt.userID)
 
 			if tt.wantErr {
@@ -537,3 +637,440 @@ func TestService_checkAccessToUpdate(t *testing.T) {
 		})
 	}
 }
+
+func TestService_Recover(t *testing.T) {
+	t.Parallel()
+
+	testID := uuid.New()
+	testUserID := uuid.New()
+	asOf := time.Now().Add(-time.Hour)
+
+	version := &note.Note{
+		ID:          testID,
+		UserID:      testUserID,
+		Note:        []byte("previous note"),
+		Description: []byte("previous description"),
+		UpdatedAt:   asOf.Add(-time.Minute),
+	}
+
+	tests := []struct {
+		loadErr     error
+		saveErr     error
+		name        string
+		wantErrText string
+		versions    []*note.Note
+		wantErr     bool
+	}{
+		{
+			name:     "success/version_saved_as_latest",
+			versions: []*note.Note{version},
+		},
+		{
+			name:        "error/version_not_found",
+			versions:    []*note.Note{},
+			wantErr:     true,
+			wantErrText: "note version not found",
+		},
+		{
+			name:        "error/load_failed",
+			loadErr:     errors.New("database error"),
+			wantErr:     true,
+			wantErrText: "failed to load note version",
+		},
+		{
+			name:        "error/save_failed",
+			versions:    []*note.Note{version},
+			saveErr:     errors.New("database error"),
+			wantErr:     true,
+			wantErrText: "failed to save recovered note",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var saved *note.Note
+			repo := &MockRepository{
+				LoadFunc: func(_ context.Context, params repository.LoadParams) ([]*note.Note, error) {
+					assert.Equal(t, asOf, params.AsOf)
+					assert.Equal(t, testID, params.ID)
+					assert.Equal(t, testUserID, params.UserID)
+					if tt.loadErr != nil {
+						return nil, tt.loadErr
+					}
+					cloned := make([]*note.Note, 0, len(tt.versions))
+					for _, v := range tt.versions {
+						c := *v
+						cloned = append(cloned, &c)
+					}
+					return cloned, nil
+				},
+				SaveFunc: func(_ context.Context, params repository.SaveParams) error {
+					saved = params.Entity
+					return tt.saveErr
+				},
+			}
+
+			service := NewService(repo, &mockUpdateRepository{}, inlineUnitOfWork{}, ownerAuthorizer{}, nil, nil, nil, nil)
+			got, err := service.Recover(context.Background(), RecoverParams{
+				AsOf:   asOf,
+				ID:     testID,
+				UserID: testUserID,
+			})
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErrText)
+				assert.Nil(t, got)
+				return
+			}
+			require.NoError(t, err)
+			require.NotNil(t, saved)
+			assert.Equal(t, testID, got.ID)
+			assert.True(t, saved.UpdatedAt.After(version.UpdatedAt))
+			assert.Equal(t, saved.UpdatedAt, got.UpdatedAt)
+		})
+	}
+}
+
+func TestService_PushBatch(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	existingID := uuid.New()
+	saveErr := errors.New("save failed")
+	item := func(id uuid.UUID) *PushParams {
+		return &PushParams{ID: id, Note: "text"}
+	}
+
+	tests := []struct {
+		saveErr       error
+		wantErrIs     error
+		name          string
+		items         []*PushParams
+		existing      []*note.Note
+		wantLoadIDs   []uuid.UUID
+		wantSaved     int
+		wantErr       bool
+		wantSaveCalls int
+	}{
+		{
+			name:          "creates and updates with last item winning",
+			items:         []*PushParams{item(uuid.Nil), item(existingID), item(existingID)},
+			existing:      []*note.Note{{ID: existingID, UserID: userID}},
+			wantLoadIDs:   []uuid.UUID{existingID, existingID},
+			wantSaved:     2,
+			wantSaveCalls: 1,
+		},
+		{
+			name:      "update of missing item",
+			items:     []*PushParams{item(existingID)},
+			wantErr:   true,
+			wantErrIs: ErrNoteNotFound,
+		},
+		{
+			name:      "update of item of another user",
+			items:     []*PushParams{item(existingID)},
+			existing:  []*note.Note{{ID: existingID, UserID: uuid.New()}},
+			wantErr:   true,
+			wantErrIs: ErrNoteAccessDenied,
+		},
+		{
+			name:    "invalid item",
+			items:   []*PushParams{{Note: ""}},
+			wantErr: true,
+		},
+		{
+			name:          "save error",
+			items:         []*PushParams{item(uuid.Nil)},
+			saveErr:       saveErr,
+			wantSaveCalls: 1,
+			wantSaved:     1,
+			wantErr:       true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			saveCalls := 0
+			repo := &MockRepository{
+				LoadFunc: func(_ context.Context, params repository.LoadParams) ([]*note.Note, error) {
+					assert.Equal(t, userID, params.UserID)
+					if tt.wantLoadIDs != nil {
+						assert.Equal(t, tt.wantLoadIDs, params.IDs)
+					}
+					return tt.existing, nil
+				},
+				SaveBatchFunc: func(_ context.Context, params repository.SaveBatchParams) error {
+					saveCalls++
+					assert.Equal(t, userID, params.UserID)
+					assert.Len(t, params.Entities, tt.wantSaved)
+					for _, e := range params.Entities {
+						assert.Equal(t, userID, e.UserID)
+					}
+					return tt.saveErr
+				},
+			}
+
+			s := NewService(repo, &mockUpdateRepository{}, inlineUnitOfWork{}, ownerAuthorizer{}, nil, nil, nil, nil)
+			ids, err := s.PushBatch(
+				context.Background(),
+				PushBatchParams{Items: tt.items, UserID: userID},
+			)
+
+			assert.Equal(t, tt.wantSaveCalls, saveCalls)
+			if tt.wantErr {
+				require.Error(t, err)
+				if tt.wantErrIs != nil {
+					require.ErrorIs(t, err, tt.wantErrIs)
+				}
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, ids, len(tt.items))
+			for i, item := range tt.items {
+				if item.ID != uuid.Nil {
+					assert.Equal(t, item.ID, ids[i])
+				} else {
+					assert.NotEqual(t, uuid.Nil, ids[i])
+				}
+			}
+		})
+	}
+}
+
+func TestService_ItemPolicies(t *testing.T) {
+	t.Parallel()
+
+	userID, existingID := uuid.New(), uuid.New()
+	repo := &MockRepository{
+		LoadFunc: func(context.Context, repository.LoadParams) ([]*note.Note, error) {
+			return []*note.Note{{ID: existingID, UserID: userID}}, nil
+		},
+	}
+	item := func(id uuid.UUID) *PushParams {
+		return &PushParams{ID: id, UserID: userID, Note: "text"}
+	}
+
+	tests := []struct {
+		call      func(s *Service) error
+		policyErr error
+		name      string
+		wantKinds []string
+	}{
+		{
+			name: "create allowed",
+			call: func(s *Service) error {
+				_, err := s.Push(context.Background(), item(uuid.Nil))
+				return err
+			},
+			wantKinds: []string{authz.KindNote},
+		},
+		{
+			name: "update forbidden",
+			call: func(s *Service) error {
+				_, err := s.Push(context.Background(), item(existingID))
+				return err
+			},
+			policyErr: itempolicyApp.ErrItemKindForbidden,
+			wantKinds: []string{authz.KindNote},
+		},
+		{
+			name: "batch checked once",
+			call: func(s *Service) error {
+				_, err := s.PushBatch(context.Background(), PushBatchParams{
+					Items:  []*PushParams{item(uuid.Nil), item(existingID)},
+					UserID: userID,
+				})
+				return err
+			},
+			policyErr: itempolicyApp.ErrItemKindForbidden,
+			wantKinds: []string{authz.KindNote},
+		},
+		{
+			name: "empty batch not checked",
+			call: func(s *Service) error {
+				_, err := s.PushBatch(context.Background(), PushBatchParams{UserID: userID})
+				return err
+			},
+			policyErr: itempolicyApp.ErrItemKindForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			policies := &mockPolicyEnforcer{err: tt.policyErr}
+
+			err := tt.call(NewService(repo, &mockUpdateRepository{}, inlineUnitOfWork{}, ownerAuthorizer{}, nil, policies,
+				nil, nil))
+
+			if tt.policyErr != nil && len(tt.wantKinds) != 0 {
+				require.ErrorIs(t, err, tt.policyErr)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantKinds, policies.kinds)
+		})
+	}
+}
+
+func TestService_Authorization(t *testing.T) {
+	t.Parallel()
+
+	userID, itemID := uuid.New(), uuid.New()
+	request := func(action string, resourceID uuid.UUID) authzApp.Request {
+		return authzApp.Request{
+			Action:     action,
+			Kind:       authz.KindNote,
+			ResourceID: resourceID,
+			OwnerID:    userID,
+			SubjectID:  userID,
+		}
+	}
+
+	tests := []struct {
+		call    func(s *Service) error
+		name    string
+		wantReq authzApp.Request
+	}{
+		{
+			name: "pull",
+			call: func(s *Service) error {
+				_, err := s.Pull(context.Background(), PullParams{ID: itemID, UserID: userID})
+				return err
+			},
+			wantReq: request(authz.ActionRead, itemID),
+		},
+		{
+			name: "list",
+			call: func(s *Service) error {
+				_, err := s.List(context.Background(), ListParams{UserID: userID})
+				return err
+			},
+			wantReq: request(authz.ActionRead, uuid.Nil),
+		},
+		{
+			name: "create",
+			call: func(s *Service) error {
+				_, err := s.Push(context.Background(), &PushParams{Note: "text", UserID: userID})
+				return err
+			},
+			wantReq: request(authz.ActionWrite, uuid.Nil),
+		},
+		{
+			name: "update",
+			call: func(s *Service) error {
+				_, err := s.Push(context.Background(), &PushParams{Note: "text", ID: itemID, UserID: userID})
+				return err
+			},
+			wantReq: request(authz.ActionWrite, itemID),
+		},
+		{
+			name: "recover",
+			call: func(s *Service) error {
+				_, err := s.Recover(context.Background(), RecoverParams{AsOf: time.Now(), ID: itemID, UserID: userID})
+				return err
+			},
+			wantReq: request(authz.ActionWrite, itemID),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &MockRepository{
+				LoadFunc: func(context.Context, repository.LoadParams) ([]*note.Note, error) {
+					return []*note.Note{{ID: itemID, UserID: userID}}, nil
+				},
+			}
+			authorizer := &denyAuthorizer{}
+
+			err := tt.call(NewService(repo, &mockUpdateRepository{}, inlineUnitOfWork{}, authorizer, nil, nil, nil, nil))
+
+			require.ErrorIs(t, err, ErrNoteAccessDenied)
+			require.ErrorIs(t, err, authzApp.ErrAccessDenied)
+			assert.Equal(t, []authzApp.Request{tt.wantReq}, authorizer.reqs)
+		})
+	}
+}
+
+// mockTagRepository implements TagRepository for testing.
+type mockTagRepository struct {
+	err  error
+	tags []*itemtag.ItemTags
+}
+
+func (m *mockTagRepository) Load(_ context.Context, params tagRepository.LoadParams) ([]*itemtag.ItemTags, error) {
+	if params.Tag != itemtag.Archived {
+		return nil, errors.New("unexpected tag")
+	}
+	return m.tags, m.err
+}
+
+func TestService_ListArchived(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	activeID, archivedID := uuid.New(), uuid.New()
+	archived := []*itemtag.ItemTags{{ItemID: archivedID, UserID: userID, Tags: []string{itemtag.Archived}}}
+
+	tests := []struct {
+		tags    TagRepository
+		wantErr error
+		want    map[uuid.UUID]bool
+		name    string
+		exclude bool
+	}{
+		{
+			name: "archived items marked",
+			tags: &mockTagRepository{tags: archived},
+			want: map[uuid.UUID]bool{activeID: false, archivedID: true},
+		},
+		{
+			name:    "archived items excluded",
+			tags:    &mockTagRepository{tags: archived},
+			exclude: true,
+			want:    map[uuid.UUID]bool{activeID: false},
+		},
+		{
+			name:    "nothing archived without tags",
+			exclude: true,
+			want:    map[uuid.UUID]bool{activeID: false, archivedID: false},
+		},
+		{
+			name:    "tag lookup error",
+			tags:    &mockTagRepository{err: errors.New("connection refused")},
+			wantErr: ErrNoteTechError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &MockRepository{LoadFunc: func(context.Context, repository.LoadParams) ([]*note.Note, error) {
+				return []*note.Note{{ID: activeID, UserID: userID}, {ID: archivedID, UserID: userID}}, nil
+			}}
+			service := NewService(repo, &mockUpdateRepository{}, inlineUnitOfWork{}, ownerAuthorizer{}, nil, nil, tt.tags, nil)
+
+			got, err := service.List(context.Background(), ListParams{UserID: userID, ExcludeArchived: tt.exclude})
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			listed := make(map[uuid.UUID]bool, len(got))
+			for _, item := range got {
+				listed[item.ID] = item.Archived
+			}
+			assert.Equal(t, tt.want, listed)
+		})
+	}
+}
@@ -0,0 +1,7 @@
+// Package notification provides notification application services for the AegisVaultKeeper server.
+//
+// This package implements management of the per-user notification channels and the web push
+// subscriptions of user devices, delivery of security alerts, sharing invitations and reminders
+// to the channels and devices subscribed to them, independently of the mechanism behind each one,
+// and sync messages telling the other devices of a user that the vault changed.
+package notification
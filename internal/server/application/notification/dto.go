@@ -0,0 +1,168 @@
+package notification
+
+import (
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/notification"
+	"github.com/google/uuid"
+)
+
+// Channel represents a notification channel data transfer object for application layer communication.
+type Channel struct {
+	// UpdatedAt indicates when the channel was last changed.
+	UpdatedAt time.Time
+	// Kind names the delivery mechanism.
+	Kind string
+	// Hint describes the target without revealing secrets such as webhook paths.
+	Hint string
+	// Categories lists the notification categories delivered to the channel.
+	Categories []string
+}
+
+// newChannelFromDomain converts a domain channel entity to application DTO without its target.
+func newChannelFromDomain(c *notification.Channel) *Channel {
+	if c == nil {
+		return nil
+	}
+	categories := make([]string, len(c.Categories))
+	for i, category := range c.Categories {
+		categories[i] = string(category)
+	}
+	return &Channel{
+		Kind:       string(c.Kind),
+		Hint:       c.Hint(),
+		Categories: categories,
+		UpdatedAt:  c.UpdatedAt,
+	}
+}
+
+// newChannelsFromDomain converts a slice of domain channel entities to application DTOs.
+func newChannelsFromDomain(cs []*notification.Channel) []*Channel {
+	result := make([]*Channel, 0, len(cs))
+	for _, c := range cs {
+		result = append(result, newChannelFromDomain(c))
+	}
+	return result
+}
+
+// PushSubscription represents a device push subscription data transfer object without its endpoint and keys.
+type PushSubscription struct {
+	// CreatedAt indicates when the device subscribed.
+	CreatedAt time.Time
+	// UpdatedAt indicates when the subscription was last renewed.
+	UpdatedAt time.Time
+	// Device contains the name the user recognizes the device by.
+	Device string
+	// Hint contains the host of the push service serving the device.
+	Hint string
+	// Categories lists the notification categories delivered to the device.
+	Categories []string
+	// ID uniquely identifies the subscription.
+	ID uuid.UUID
+}
+
+// newPushSubscriptionFromDomain converts a domain push subscription to application DTO without its secrets.
+func newPushSubscriptionFromDomain(s *notification.PushSubscription) *PushSubscription {
+	if s == nil {
+		return nil
+	}
+	categories := make([]string, len(s.Categories))
+	for i, category := range s.Categories {
+		categories[i] = string(category)
+	}
+	return &PushSubscription{
+		ID:         s.ID,
+		Device:     s.Device,
+		Hint:       s.Subscription.Host(),
+		Categories: categories,
+		CreatedAt:  s.CreatedAt,
+		UpdatedAt:  s.UpdatedAt,
+	}
+}
+
+// newPushSubscriptionsFromDomain converts a slice of domain push subscriptions to application DTOs.
+func newPushSubscriptionsFromDomain(ss []*notification.PushSubscription) []*PushSubscription {
+	result := make([]*PushSubscription, 0, len(ss))
+	for _, s := range ss {
+		result = append(result, newPushSubscriptionFromDomain(s))
+	}
+	return result
+}
+
+// ListParams contains parameters for listing notification channels.
+type ListParams struct {
+	// UserID identifies the owner of the channels.
+	UserID uuid.UUID
+}
+
+// SetParams contains parameters for adding or changing a notification channel.
+type SetParams struct {
+	// Kind names the delivery mechanism; a user has at most one channel of each kind.
+	Kind string
+	// Target addresses the recipient within the delivery mechanism.
+	Target string
+	// Categories lists the notification categories to deliver to the channel.
+	Categories []string
+	// UserID identifies the owner of the channel.
+	UserID uuid.UUID
+}
+
+// DeleteParams contains parameters for removing a notification channel.
+type DeleteParams struct {
+	// Kind identifies the channel to remove.
+	Kind string
+	// UserID identifies the owner of the channel.
+	UserID uuid.UUID
+}
+
+// SubscribeParams contains parameters for registering the push subscription of a device.
+type SubscribeParams struct {
+	// Device contains the name the user recognizes the device by.
+	Device string
+	// Endpoint contains the push service URL of the subscription.
+	Endpoint string
+	// P256dh contains the base64url-encoded P-256 public key of the browser.
+	P256dh string
+	// Auth contains the base64url-encoded authentication secret of the browser.
+	Auth string
+	// Categories lists the notification categories to deliver to the device; empty receives sync messages only.
+	Categories []string
+	// UserID identifies the owner of the subscription.
+	UserID uuid.UUID
+}
+
+// UnsubscribeParams contains parameters for removing a push subscription.
+type UnsubscribeParams struct {
+	// ID identifies the subscription to remove.
+	ID uuid.UUID
+	// UserID identifies the owner of the subscription.
+	UserID uuid.UUID
+}
+
+// SyncParams contains parameters for telling the devices of a user that the vault changed.
+type SyncParams struct {
+	// ExceptID identifies the subscription of the device that made the change; uuid.Nil notifies all devices.
+	ExceptID uuid.UUID
+	// UserID identifies the owner of the vault.
+	UserID uuid.UUID
+}
+
+// ReachableParams contains parameters for checking whether a user can receive a notification.
+type ReachableParams struct {
+	// Category names the kind of notification.
+	Category notification.Category
+	// UserID identifies the recipient.
+	UserID uuid.UUID
+}
+
+// NotifyParams contains parameters for delivering a notification.
+type NotifyParams struct {
+	// Category names the kind of notification; only channels subscribed to it receive the notification.
+	Category notification.Category
+	// Subject contains the one-line summary of the notification.
+	Subject string
+	// Body contains the plain-text content of the notification.
+	Body string
+	// UserID identifies the recipient.
+	UserID uuid.UUID
+}
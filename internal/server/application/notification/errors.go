@@ -0,0 +1,84 @@
+package notification
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/errutil"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/notification"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/notification"
+	repositoryPush "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/pushsubscription"
+)
+
+// Notification error definitions.
+var (
+	// ErrNotificationAppError indicates a general notification application error.
+	ErrNotificationAppError = errors.New("notification application error")
+
+	// ErrNotificationTechError indicates a technical error in the notification system.
+	ErrNotificationTechError = errors.New("notification technical error")
+
+	// ErrNotificationIncorrectKind indicates an unsupported channel kind was provided.
+	ErrNotificationIncorrectKind = errors.New("incorrect notification channel kind")
+
+	// ErrNotificationIncorrectTarget indicates a malformed channel target was provided.
+	ErrNotificationIncorrectTarget = errors.New("incorrect notification channel target")
+
+	// ErrNotificationIncorrectCategories indicates no or unknown notification categories were provided.
+	ErrNotificationIncorrectCategories = errors.New("incorrect notification categories")
+
+	// ErrNotificationIncorrectDevice indicates an empty or too long device name was provided.
+	ErrNotificationIncorrectDevice = errors.New("incorrect push subscription device")
+
+	// ErrNotificationIncorrectSubscription indicates a malformed push subscription endpoint or keys were provided.
+	ErrNotificationIncorrectSubscription = errors.New("incorrect push subscription")
+
+	// ErrNotificationPushSubscriptionNotFound indicates the requested push subscription was not found.
+	ErrNotificationPushSubscriptionNotFound = errors.New("push subscription not found")
+
+	// ErrNotificationChannelNotFound indicates the requested notification channel was not found.
+	ErrNotificationChannelNotFound = errors.New("notification channel not found")
+
+	// ErrNotificationChannelUnavailable indicates the channel kind is not configured on this server.
+	ErrNotificationChannelUnavailable = errors.New("notification channel kind not available")
+
+	// ErrNotificationUnreachable indicates the user has no channel to receive the notification on.
+	ErrNotificationUnreachable = errors.New("user has no channel for the notification")
+)
+
+// mapError maps domain and repository errors to application-level errors.
+func mapError(err error) error {
+	if err == nil {
+		return nil
+	}
+	mapped := errutil.MapError(mapFn, err)
+	if mapped != nil {
+		return fmt.Errorf("notification error mapping failed: %w", mapped)
+	}
+	return nil
+}
+
+// mapFn provides the actual error mapping logic for different error types.
+func mapFn(err error) error {
+	switch {
+	case errors.Is(err, notification.ErrNewChannelParamsValidation),
+		errors.Is(err, notification.ErrNewPushSubscriptionParamsValidation):
+		return ErrNotificationAppError
+	case errors.Is(err, notification.ErrIncorrectKind):
+		return ErrNotificationIncorrectKind
+	case errors.Is(err, notification.ErrIncorrectTarget):
+		return ErrNotificationIncorrectTarget
+	case errors.Is(err, notification.ErrIncorrectCategories):
+		return ErrNotificationIncorrectCategories
+	case errors.Is(err, notification.ErrIncorrectDevice):
+		return ErrNotificationIncorrectDevice
+	case errors.Is(err, notification.ErrIncorrectSubscription):
+		return ErrNotificationIncorrectSubscription
+	case errors.Is(err, repository.ErrChannelNotFound):
+		return ErrNotificationChannelNotFound
+	case errors.Is(err, repositoryPush.ErrSubscriptionNotFound):
+		return ErrNotificationPushSubscriptionNotFound
+	default:
+		return errors.Join(ErrNotificationTechError, err)
+	}
+}
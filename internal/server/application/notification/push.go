@@ -0,0 +1,150 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/audit"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/notification"
+	repositoryPush "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/pushsubscription"
+	"github.com/google/uuid"
+)
+
+// PushPublicKey returns the VAPID public key browsers pass as applicationServerKey when subscribing.
+// Returns ErrNotificationChannelUnavailable when web push is not configured on this server.
+func (s *Service) PushPublicKey(context.Context) (string, error) {
+	if s.push == nil {
+		return "", ErrNotificationChannelUnavailable
+	}
+	return s.push.PublicKey(), nil
+}
+
+// ListPushSubscriptions retrieves the push subscriptions of the devices of the user.
+func (s *Service) ListPushSubscriptions(ctx context.Context, params ListParams) ([]*PushSubscription, error) {
+	subscriptions, err := s.pushes.Load(ctx, repositoryPush.LoadParams{UserID: params.UserID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load push subscriptions: %w", mapError(err))
+	}
+	return newPushSubscriptionsFromDomain(subscriptions), nil
+}
+
+// Subscribe registers the push subscription of a device of the user. Subscribing an endpoint again renews
+// the existing subscription with the new keys, device name and categories.
+// Returns ErrNotificationChannelUnavailable when web push is not configured on this server.
+func (s *Service) Subscribe(ctx context.Context, params SubscribeParams) (*PushSubscription, error) {
+	newParams := notification.NewPushSubscriptionParams{
+		Device: params.Device,
+		Subscription: notification.WebPushSubscription{
+			Endpoint: params.Endpoint,
+			Keys:     notification.WebPushKeys{P256dh: params.P256dh, Auth: params.Auth},
+		},
+		Categories: toCategories(params.Categories),
+		UserID:     params.UserID,
+	}
+	if err := newParams.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid push subscription: %w", mapError(err))
+	}
+	if s.push == nil {
+		return nil, ErrNotificationChannelUnavailable
+	}
+
+	existing, err := s.pushes.Load(ctx, repositoryPush.LoadParams{UserID: params.UserID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load push subscriptions: %w", mapError(err))
+	}
+	var subscription *notification.PushSubscription
+	for _, e := range existing {
+		if e.Subscription.Endpoint == params.Endpoint {
+			subscription = e
+			break
+		}
+	}
+	if subscription == nil {
+		if subscription, err = notification.NewPushSubscription(newParams); err != nil {
+			return nil, fmt.Errorf("failed to create push subscription: %w", mapError(err))
+		}
+	} else if err := subscription.Renew(newParams); err != nil {
+		return nil, fmt.Errorf("failed to renew push subscription: %w", mapError(err))
+	}
+
+	if err := s.pushes.Save(ctx, repositoryPush.SaveParams{Entity: subscription}); err != nil {
+		return nil, fmt.Errorf("failed to save push subscription: %w", mapError(err))
+	}
+
+	s.audit.Record(ctx, audit.Event{
+		Type:    audit.EventPushSubscribed,
+		UserID:  params.UserID,
+		Details: map[string]string{"subscription_id": subscription.ID.String()},
+	})
+	return newPushSubscriptionFromDomain(subscription), nil
+}
+
+// Unsubscribe removes the push subscription from the user.
+func (s *Service) Unsubscribe(ctx context.Context, params UnsubscribeParams) error {
+	err := s.pushes.Delete(ctx, repositoryPush.DeleteParams{ID: params.ID, UserID: params.UserID})
+	if err != nil {
+		return fmt.Errorf("failed to delete push subscription: %w", mapError(err))
+	}
+
+	s.audit.Record(ctx, audit.Event{
+		Type:    audit.EventPushUnsubscribed,
+		UserID:  params.UserID,
+		Details: map[string]string{"subscription_id": params.ID.String()},
+	})
+	return nil
+}
+
+// TriggerSync tells every device of the user except the one that made the change to pull the vault.
+// Subscriptions that no longer exist are removed; the remaining devices still sync on their own schedule,
+// so failed deliveries are not audited.
+func (s *Service) TriggerSync(ctx context.Context, params SyncParams) error {
+	subscriptions, err := s.pushSubscriptions(ctx, params.UserID)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, sub := range subscriptions {
+		if sub.ID == params.ExceptID {
+			continue
+		}
+		err := s.push.Push(ctx, &sub.Subscription, notification.PushMessage{Type: notification.PushMessageSync})
+		switch {
+		case errors.Is(err, notification.ErrPushSubscriptionGone):
+			s.dropPushSubscription(ctx, sub)
+		case err != nil:
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Join(ErrNotificationTechError, fmt.Errorf("failed to trigger sync: %w", errors.Join(errs...)))
+	}
+	return nil
+}
+
+// pushSubscriptions loads the push subscriptions of the user; none when web push is not configured.
+func (s *Service) pushSubscriptions(ctx context.Context, userID uuid.UUID) ([]*notification.PushSubscription, error) {
+	if s.push == nil {
+		return nil, nil
+	}
+	subscriptions, err := s.pushes.Load(ctx, repositoryPush.LoadParams{UserID: userID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load push subscriptions: %w", mapError(err))
+	}
+	return subscriptions, nil
+}
+
+// dropPushSubscription removes a subscription the push service no longer accepts messages for.
+// A failed removal is retried on the next delivery to it.
+func (s *Service) dropPushSubscription(ctx context.Context, sub *notification.PushSubscription) {
+	err := s.pushes.Delete(ctx, repositoryPush.DeleteParams{ID: sub.ID, UserID: sub.UserID})
+	if err != nil {
+		return
+	}
+	s.audit.Record(ctx, audit.Event{
+		Type:    audit.EventPushUnsubscribed,
+		UserID:  sub.UserID,
+		Details: map[string]string{"subscription_id": sub.ID.String(), "reason": "gone"},
+	})
+}
@@ -0,0 +1,321 @@
+package notification
+
+import (
+	"context"
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/audit"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/notification"
+	repositoryPush "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/pushsubscription"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testPushKey returns a base64url-encoded browser public key.
+func testPushKey(t *testing.T) string {
+	t.Helper()
+
+	key, err := ecdh.P256().GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	return base64.RawURLEncoding.EncodeToString(key.PublicKey().Bytes())
+}
+
+// testPushAuth is a base64url-encoded browser authentication secret.
+var testPushAuth = base64.RawURLEncoding.EncodeToString(make([]byte, 16))
+
+// newTestPushSubscription creates a valid push subscription of the user for tests, with the endpoint
+// https://push.example.com/<name>, optionally subscribed to security notifications.
+func newTestPushSubscription(
+	t *testing.T,
+	userID uuid.UUID,
+	name string,
+	security bool,
+) *notification.PushSubscription {
+	t.Helper()
+
+	var categories []notification.Category
+	if security {
+		categories = []notification.Category{notification.CategorySecurity}
+	}
+	s, err := notification.NewPushSubscription(notification.NewPushSubscriptionParams{
+		Device: name,
+		Subscription: notification.WebPushSubscription{
+			Endpoint: "https://push.example.com/" + name,
+			Keys:     notification.WebPushKeys{P256dh: testPushKey(t), Auth: testPushAuth},
+		},
+		Categories: categories,
+		UserID:     userID,
+	})
+	require.NoError(t, err)
+	return s
+}
+
+func TestService_PushPublicKey(t *testing.T) {
+	t.Parallel()
+
+	configured := NewService(nil, nil, nil, nil, &mockPushSender{}, &mockAuditRecorder{})
+	key, err := configured.PushPublicKey(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "BPublicKey", key)
+
+	unconfigured := NewService(nil, nil, nil, nil, nil, &mockAuditRecorder{})
+	_, err = unconfigured.PushPublicKey(context.Background())
+	assert.ErrorIs(t, err, ErrNotificationChannelUnavailable)
+}
+
+func TestService_Subscribe(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	existing := newTestPushSubscription(t, userID, "laptop", true)
+
+	tests := []struct {
+		saveErr  error
+		wantErr  error
+		existing *notification.PushSubscription
+		name     string
+		params   SubscribeParams
+		noPush   bool
+	}{
+		{
+			name: "device subscribed",
+			params: SubscribeParams{
+				Device:     "Phone",
+				Endpoint:   "https://push.example.com/phone",
+				Categories: []string{"security"},
+			},
+		},
+		{
+			name:     "subscription renewed",
+			existing: existing,
+			params: SubscribeParams{
+				Device:     "Phone",
+				Endpoint:   existing.Subscription.Endpoint,
+				Categories: []string{"security"},
+			},
+		},
+		{
+			name:    "web push not configured",
+			params:  SubscribeParams{Device: "Phone", Endpoint: "https://push.example.com/phone"},
+			noPush:  true,
+			wantErr: ErrNotificationChannelUnavailable,
+		},
+		{
+			name:    "missing device",
+			params:  SubscribeParams{Endpoint: "https://push.example.com/phone"},
+			wantErr: ErrNotificationIncorrectDevice,
+		},
+		{
+			name:    "http endpoint",
+			params:  SubscribeParams{Device: "Phone", Endpoint: "http://push.example.com/phone"},
+			wantErr: ErrNotificationIncorrectSubscription,
+		},
+		{
+			name: "unknown category",
+			params: SubscribeParams{
+				Device:     "Phone",
+				Endpoint:   "https://push.example.com/phone",
+				Categories: []string{"news"},
+			},
+			wantErr: ErrNotificationIncorrectCategories,
+		},
+		{
+			name:    "save error",
+			params:  SubscribeParams{Device: "Phone", Endpoint: "https://push.example.com/phone"},
+			saveErr: errors.New("connection refused"),
+			wantErr: ErrNotificationTechError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &mockPushRepository{saveErr: tt.saveErr}
+			if tt.existing != nil {
+				repo.subscriptions = []*notification.PushSubscription{tt.existing}
+			}
+			var push PushSender = &mockPushSender{}
+			if tt.noPush {
+				push = nil
+			}
+			recorder := &mockAuditRecorder{}
+			svc := NewService(&mockRepository{}, repo, &mockUserRepository{}, nil, push, recorder)
+
+			tt.params.P256dh, tt.params.Auth, tt.params.UserID = testPushKey(t), testPushAuth, userID
+			got, err := svc.Subscribe(context.Background(), tt.params)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, got)
+				assert.Empty(t, recorder.events)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "Phone", got.Device)
+			assert.Equal(t, "push.example.com", got.Hint)
+			require.NotNil(t, repo.saved)
+			assert.Equal(t, tt.params.P256dh, repo.saved.Subscription.Keys.P256dh)
+			if tt.existing != nil {
+				assert.Equal(t, tt.existing.ID, got.ID)
+			}
+			require.Len(t, recorder.events, 1)
+			assert.Equal(t, audit.EventPushSubscribed, recorder.events[0].Type)
+		})
+	}
+}
+
+func TestService_Unsubscribe(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		deleteErr error
+		wantErr   error
+		name      string
+	}{
+		{name: "removed"},
+		{
+			name:      "not found",
+			deleteErr: repositoryPush.ErrSubscriptionNotFound,
+			wantErr:   ErrNotificationPushSubscriptionNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &mockPushRepository{deleteErr: tt.deleteErr}
+			recorder := &mockAuditRecorder{}
+			svc := NewService(&mockRepository{}, repo, &mockUserRepository{}, nil, nil, recorder)
+			params := UnsubscribeParams{ID: uuid.New(), UserID: uuid.New()}
+
+			err := svc.Unsubscribe(context.Background(), params)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Empty(t, recorder.events)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, []uuid.UUID{params.ID}, repo.deleted)
+			require.Len(t, recorder.events, 1)
+			assert.Equal(t, audit.EventPushUnsubscribed, recorder.events[0].Type)
+		})
+	}
+}
+
+func TestService_TriggerSync(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	laptop := newTestPushSubscription(t, userID, "laptop", false)
+	phone := newTestPushSubscription(t, userID, "phone", true)
+	tablet := newTestPushSubscription(t, userID, "tablet", false)
+	pushErr := errors.New("push service unavailable")
+
+	tests := []struct {
+		wantErr     error
+		errs        map[string]error
+		name        string
+		wantTo      []string
+		wantDeleted []uuid.UUID
+		exceptID    uuid.UUID
+	}{
+		{
+			name:   "all devices",
+			wantTo: []string{laptop.Subscription.Endpoint, phone.Subscription.Endpoint, tablet.Subscription.Endpoint},
+		},
+		{
+			name:     "except the changing device",
+			exceptID: phone.ID,
+			wantTo:   []string{laptop.Subscription.Endpoint, tablet.Subscription.Endpoint},
+		},
+		{
+			name:        "expired subscription removed",
+			exceptID:    phone.ID,
+			errs:        map[string]error{laptop.Subscription.Endpoint: notification.ErrPushSubscriptionGone},
+			wantTo:      []string{laptop.Subscription.Endpoint, tablet.Subscription.Endpoint},
+			wantDeleted: []uuid.UUID{laptop.ID},
+		},
+		{
+			name:     "push failure",
+			exceptID: phone.ID,
+			errs:     map[string]error{tablet.Subscription.Endpoint: pushErr},
+			wantTo:   []string{laptop.Subscription.Endpoint, tablet.Subscription.Endpoint},
+			wantErr:  pushErr,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &mockPushRepository{subscriptions: []*notification.PushSubscription{laptop, phone, tablet}}
+			push := &mockPushSender{errs: tt.errs}
+			svc := NewService(&mockRepository{}, repo, &mockUserRepository{}, nil, push, &mockAuditRecorder{})
+
+			err := svc.TriggerSync(context.Background(), SyncParams{ExceptID: tt.exceptID, UserID: userID})
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, ErrNotificationTechError)
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantTo, push.to)
+			for _, msg := range push.pushed {
+				assert.Equal(t, notification.PushMessage{Type: notification.PushMessageSync}, msg)
+			}
+			assert.Equal(t, tt.wantDeleted, repo.deleted)
+		})
+	}
+}
+
+func TestService_TriggerSync_WithoutWebPush(t *testing.T) {
+	t.Parallel()
+
+	repo := &mockPushRepository{loadErr: errors.New("must not be called")}
+	svc := NewService(&mockRepository{}, repo, &mockUserRepository{}, nil, nil, &mockAuditRecorder{})
+
+	assert.NoError(t, svc.TriggerSync(context.Background(), SyncParams{UserID: uuid.New()}))
+}
+
+func TestService_Notify_PushSubscriptionGone(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	gone := newTestPushSubscription(t, userID, "old", true)
+	active := newTestPushSubscription(t, userID, "new", true)
+	repo := &mockPushRepository{subscriptions: []*notification.PushSubscription{gone, active}}
+	push := &mockPushSender{errs: map[string]error{gone.Subscription.Endpoint: notification.ErrPushSubscriptionGone}}
+	recorder := &mockAuditRecorder{}
+	svc := NewService(&mockRepository{}, repo, &mockUserRepository{}, nil, push, recorder)
+
+	err := svc.Notify(context.Background(), NotifyParams{
+		Category: notification.CategorySecurity,
+		Subject:  "New login",
+		Body:     "Code: 123456",
+		UserID:   userID,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{gone.ID}, repo.deleted)
+	require.Len(t, push.pushed, 2)
+	assert.Equal(t, notification.PushMessage{
+		Type:     notification.PushMessageNotification,
+		Category: notification.CategorySecurity,
+		Title:    "New login",
+		Body:     "Code: 123456",
+	}, push.pushed[1])
+	types := make([]string, 0, len(recorder.events))
+	for _, e := range recorder.events {
+		types = append(types, e.Type)
+	}
+	assert.Equal(t, []string{audit.EventPushUnsubscribed, audit.EventNotificationFailed}, types)
+}
@@ -0,0 +1,293 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/audit"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/auth"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/notification"
+	repositoryAuth "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/auth"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/notification"
+	repositoryPush "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/pushsubscription"
+	"github.com/google/uuid"
+)
+
+// Repository defines the interface for notification channel persistence operations.
+type Repository interface {
+	// Save persists a channel using the provided parameters.
+	Save(ctx context.Context, params repository.SaveParams) error
+	// Load retrieves channels using the provided parameters.
+	Load(ctx context.Context, params repository.LoadParams) ([]*notification.Channel, error)
+	// Delete removes a channel using the provided parameters.
+	Delete(ctx context.Context, params repository.DeleteParams) error
+}
+
+// PushRepository defines the interface for push subscription persistence operations.
+type PushRepository interface {
+	// Save persists a push subscription using the provided parameters.
+	Save(ctx context.Context, params repositoryPush.SaveParams) error
+	// Load retrieves push subscriptions using the provided parameters.
+	Load(ctx context.Context, params repositoryPush.LoadParams) ([]*notification.PushSubscription, error)
+	// Delete removes a push subscription using the provided parameters.
+	Delete(ctx context.Context, params repositoryPush.DeleteParams) error
+}
+
+// UserRepository defines the interface for loading users.
+type UserRepository interface {
+	// Load retrieves user data using the provided parameters.
+	Load(ctx context.Context, params repositoryAuth.LoadParams) (*auth.User, error)
+}
+
+// Sender defines the interface for delivering messages over a single channel kind.
+type Sender interface {
+	// Send delivers a plain text message to the recipient addressed by the channel target.
+	Send(ctx context.Context, to, subject, body string) error
+}
+
+// PushSender defines the interface for delivering web push messages to browser push subscriptions.
+type PushSender interface {
+	// PublicKey returns the VAPID public key browsers subscribe with.
+	PublicKey() string
+	// Push delivers the message to the subscription; returns notification.ErrPushSubscriptionGone
+	// when the subscription no longer exists.
+	Push(ctx context.Context, sub *notification.WebPushSubscription, msg notification.PushMessage) error
+}
+
+// AuditRecorder defines the interface for recording security audit events.
+type AuditRecorder interface {
+	// Record stores the audit event.
+	Record(ctx context.Context, e audit.Event)
+}
+
+// recipient is a channel or a device push subscription a notification is delivered to.
+type recipient struct {
+	// push is the device push subscription; nil for channels.
+	push *notification.PushSubscription
+	// kind names the delivery mechanism.
+	kind notification.Kind
+	// to addresses the recipient within the delivery mechanism of a channel.
+	to string
+}
+
+// Service provides notification channel and push subscription management and notification delivery.
+type Service struct {
+	// r is the repository interface for channel persistence operations.
+	r Repository
+	// pushes is the repository interface for push subscription persistence operations.
+	pushes PushRepository
+	// users loads the logins e-mail notifications fall back to.
+	users UserRepository
+	// senders maps the channel kinds configured on the server to their senders.
+	senders map[notification.Kind]Sender
+	// push delivers web push messages; nil when web push is not configured.
+	push PushSender
+	// audit records channel and subscription changes and failed deliveries.
+	audit AuditRecorder
+}
+
+// NewService creates a new notification service instance.
+// Only channel kinds with a sender can be configured and delivered to; nil senders are ignored.
+// A nil push sender disables web push.
+func NewService(
+	r Repository,
+	pushes PushRepository,
+	users UserRepository,
+	senders map[notification.Kind]Sender,
+	push PushSender,
+	audit AuditRecorder,
+) *Service {
+	configured := make(map[notification.Kind]Sender, len(senders))
+	for kind, sender := range senders {
+		if sender != nil {
+			configured[kind] = sender
+		}
+	}
+	return &Service{r: r, pushes: pushes, users: users, senders: configured, push: push, audit: audit}
+}
+
+// List retrieves the notification channels of the user.
+func (s *Service) List(ctx context.Context, params ListParams) ([]*Channel, error) {
+	channels, err := s.r.Load(ctx, repository.LoadParams{UserID: params.UserID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load notification channels: %w", mapError(err))
+	}
+	return newChannelsFromDomain(channels), nil
+}
+
+// Set adds a channel of the kind to the user or replaces the target and categories of the existing one.
+// Returns ErrNotificationChannelUnavailable when the kind is not configured on this server.
+func (s *Service) Set(ctx context.Context, params SetParams) (*Channel, error) {
+	kind := notification.Kind(params.Kind)
+	categories := toCategories(params.Categories)
+	newParams := notification.NewChannelParams{
+		Kind:       kind,
+		Target:     params.Target,
+		Categories: categories,
+		UserID:     params.UserID,
+	}
+	if err := newParams.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid notification channel: %w", mapError(err))
+	}
+	if _, ok := s.senders[kind]; !ok {
+		return nil, ErrNotificationChannelUnavailable
+	}
+
+	var channel *notification.Channel
+	existing, err := s.r.Load(ctx, repository.LoadParams{Kind: kind, UserID: params.UserID})
+	switch {
+	case errors.Is(err, repository.ErrChannelNotFound):
+		if channel, err = notification.NewChannel(newParams); err != nil {
+			return nil, fmt.Errorf("failed to create notification channel: %w", mapError(err))
+		}
+	case err != nil:
+		return nil, fmt.Errorf("failed to load notification channel: %w", mapError(err))
+	default:
+		channel = existing[0]
+		if err := channel.Reconfigure(params.Target, categories); err != nil {
+			return nil, fmt.Errorf("failed to change notification channel: %w", mapError(err))
+		}
+	}
+
+	if err := s.r.Save(ctx, repository.SaveParams{Entity: channel}); err != nil {
+		return nil, fmt.Errorf("failed to save notification channel: %w", mapError(err))
+	}
+
+	s.audit.Record(ctx, audit.Event{
+		Type:    audit.EventNotificationChannelSet,
+		UserID:  params.UserID,
+		Details: map[string]string{"kind": params.Kind},
+	})
+	return newChannelFromDomain(channel), nil
+}
+
+// Delete removes the channel of the kind from the user.
+func (s *Service) Delete(ctx context.Context, params DeleteParams) error {
+	err := s.r.Delete(ctx, repository.DeleteParams{Kind: notification.Kind(params.Kind), UserID: params.UserID})
+	if err != nil {
+		return fmt.Errorf("failed to delete notification channel: %w", mapError(err))
+	}
+
+	s.audit.Record(ctx, audit.Event{
+		Type:    audit.EventNotificationChannelDeleted,
+		UserID:  params.UserID,
+		Details: map[string]string{"kind": params.Kind},
+	})
+	return nil
+}
+
+// Reachable reports whether the user can receive notifications of the category.
+func (s *Service) Reachable(ctx context.Context, params ReachableParams) (bool, error) {
+	recipients, err := s.recipients(ctx, params.UserID, params.Category)
+	if err != nil {
+		return false, err
+	}
+	return len(recipients) > 0, nil
+}
+
+// Notify delivers the notification to every channel and device of the user subscribed to its category.
+// Users without any configured channel or device are notified by e-mail when their login is an e-mail
+// address. The notification counts as delivered when at least one recipient accepted it; failed ones are
+// audited. Returns ErrNotificationUnreachable when the user has no recipient for the notification.
+func (s *Service) Notify(ctx context.Context, params NotifyParams) error {
+	recipients, err := s.recipients(ctx, params.UserID, params.Category)
+	if err != nil {
+		return err
+	}
+	if len(recipients) == 0 {
+		return ErrNotificationUnreachable
+	}
+
+	var errs []error
+	for _, r := range recipients {
+		err := s.deliver(ctx, r, params)
+		if err == nil {
+			continue
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", r.kind, err))
+		s.audit.Record(ctx, audit.Event{
+			Type:    audit.EventNotificationFailed,
+			UserID:  params.UserID,
+			Details: map[string]string{"kind": string(r.kind), "category": string(params.Category)},
+		})
+	}
+	if len(errs) == len(recipients) {
+		return errors.Join(ErrNotificationTechError, fmt.Errorf("failed to deliver notification: %w", errors.Join(errs...)))
+	}
+	return nil
+}
+
+// deliver sends the notification to the recipient. Push subscriptions that no longer exist are removed.
+func (s *Service) deliver(ctx context.Context, r recipient, params NotifyParams) error {
+	if r.push == nil {
+		return s.senders[r.kind].Send(ctx, r.to, params.Subject, params.Body)
+	}
+	err := s.push.Push(ctx, &r.push.Subscription, notification.PushMessage{
+		Type:     notification.PushMessageNotification,
+		Category: params.Category,
+		Title:    params.Subject,
+		Body:     params.Body,
+	})
+	if errors.Is(err, notification.ErrPushSubscriptionGone) {
+		s.dropPushSubscription(ctx, r.push)
+	}
+	return err
+}
+
+// recipients resolves the channels and devices a notification of the category is delivered to. Users
+// without any configured channel or device fall back to their e-mail login, so notifications work before
+// channels are set up.
+func (s *Service) recipients(
+	ctx context.Context,
+	userID uuid.UUID,
+	category notification.Category,
+) ([]recipient, error) {
+	channels, err := s.r.Load(ctx, repository.LoadParams{UserID: userID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load notification channels: %w", mapError(err))
+	}
+	subscriptions, err := s.pushSubscriptions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(channels) == 0 && len(subscriptions) == 0 {
+		if _, ok := s.senders[notification.KindEmail]; !ok {
+			return nil, nil
+		}
+		u, err := s.users.Load(ctx, repositoryAuth.LoadParams{ID: userID})
+		if err != nil {
+			return nil, errors.Join(ErrNotificationTechError, fmt.Errorf("failed to load user: %w", err))
+		}
+		u.Wipe()
+		addr, err := mail.ParseAddress(u.Login)
+		if err != nil {
+			return nil, nil // nolint:nilerr // Users without an e-mail login and channels cannot be notified
+		}
+		return []recipient{{kind: notification.KindEmail, to: addr.Address}}, nil
+	}
+
+	var recipients []recipient
+	for _, c := range channels {
+		if _, ok := s.senders[c.Kind]; ok && c.Subscribed(category) {
+			recipients = append(recipients, recipient{kind: c.Kind, to: c.Target})
+		}
+	}
+	for _, sub := range subscriptions {
+		if sub.Subscribed(category) {
+			recipients = append(recipients, recipient{kind: notification.KindWebPush, push: sub})
+		}
+	}
+	return recipients, nil
+}
+
+// toCategories converts category names to domain categories.
+func toCategories(names []string) []notification.Category {
+	categories := make([]notification.Category, len(names))
+	for i, c := range names {
+		categories[i] = notification.Category(c)
+	}
+	return categories
+}
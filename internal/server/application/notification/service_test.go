@@ -0,0 +1,474 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/audit"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/auth"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/notification"
+	repositoryAuth "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/auth"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/notification"
+	repositoryPush "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/pushsubscription"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockRepository implements Repository for testing.
+type mockRepository struct {
+	loadErr      error
+	saveErr      error
+	deleteErr    error
+	saved        *notification.Channel
+	deleteParams repository.DeleteParams
+	channels     []*notification.Channel
+}
+
+func (m *mockRepository) Save(_ context.Context, params repository.SaveParams) error {
+	m.saved = params.Entity
+	return m.saveErr
+}
+
+func (m *mockRepository) Load(_ context.Context, params repository.LoadParams) ([]*notification.Channel, error) {
+	if m.loadErr != nil {
+		return nil, m.loadErr
+	}
+	if params.Kind == "" {
+		return m.channels, nil
+	}
+	for _, c := range m.channels {
+		if c.Kind == params.Kind {
+			return []*notification.Channel{c}, nil
+		}
+	}
+	return nil, repository.ErrChannelNotFound
+}
+
+func (m *mockRepository) Delete(_ context.Context, params repository.DeleteParams) error {
+	m.deleteParams = params
+	return m.deleteErr
+}
+
+// mockPushRepository implements PushRepository for testing.
+type mockPushRepository struct {
+	loadErr       error
+	saveErr       error
+	deleteErr     error
+	saved         *notification.PushSubscription
+	deleted       []uuid.UUID
+	subscriptions []*notification.PushSubscription
+}
+
+func (m *mockPushRepository) Save(_ context.Context, params repositoryPush.SaveParams) error {
+	m.saved = params.Entity
+	return m.saveErr
+}
+
+func (m *mockPushRepository) Load(
+	_ context.Context,
+	_ repositoryPush.LoadParams,
+) ([]*notification.PushSubscription, error) {
+	if m.loadErr != nil {
+		return nil, m.loadErr
+	}
+	return m.subscriptions, nil
+}
+
+func (m *mockPushRepository) Delete(_ context.Context, params repositoryPush.DeleteParams) error {
+	m.deleted = append(m.deleted, params.ID)
+	return m.deleteErr
+}
+
+// mockPushSender implements PushSender for testing.
+type mockPushSender struct {
+	errs   map[string]error
+	pushed []notification.PushMessage
+	to     []string
+}
+
+func (m *mockPushSender) PublicKey() string {
+	return "BPublicKey"
+}
+
+func (m *mockPushSender) Push(
+	_ context.Context,
+	sub *notification.WebPushSubscription,
+	msg notification.PushMessage,
+) error {
+	m.pushed = append(m.pushed, msg)
+	m.to = append(m.to, sub.Endpoint)
+	return m.errs[sub.Endpoint]
+}
+
+// mockUserRepository implements UserRepository for testing.
+type mockUserRepository struct {
+	err   error
+	login string
+}
+
+func (m *mockUserRepository) Load(_ context.Context, params repositoryAuth.LoadParams) (*auth.User, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &auth.User{ID: params.ID, Login: m.login}, nil
+}
+
+// mockSender implements Sender for testing.
+type mockSender struct {
+	err  error
+	sent []string
+}
+
+func (m *mockSender) Send(_ context.Context, to, subject, _ string) error {
+	m.sent = append(m.sent, to+": "+subject)
+	return m.err
+}
+
+// mockAuditRecorder implements AuditRecorder for testing.
+type mockAuditRecorder struct {
+	events []audit.Event
+}
+
+func (m *mockAuditRecorder) Record(_ context.Context, e audit.Event) {
+	m.events = append(m.events, e)
+}
+
+// newTestChannel creates a valid channel of the user for tests.
+func newTestChannel(
+	t *testing.T,
+	userID uuid.UUID,
+	kind notification.Kind,
+	target string,
+	categories ...notification.Category,
+) *notification.Channel {
+	t.Helper()
+
+	c, err := notification.NewChannel(notification.NewChannelParams{
+		Kind:       kind,
+		Target:     target,
+		Categories: categories,
+		UserID:     userID,
+	})
+	require.NoError(t, err)
+	return c
+}
+
+func TestService_Set(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+
+	tests := []struct {
+		saveErr  error
+		wantErr  error
+		existing *notification.Channel
+		name     string
+		params   SetParams
+	}{
+		{
+			name: "channel added",
+			params: SetParams{
+				Kind:       "telegram",
+				Target:     "42",
+				Categories: []string{"security", "reminders"},
+			},
+		},
+		{
+			name:     "channel changed",
+			existing: newTestChannel(t, userID, notification.KindTelegram, "41", notification.CategorySharing),
+			params: SetParams{
+				Kind:       "telegram",
+				Target:     "42",
+				Categories: []string{"security", "reminders"},
+			},
+		},
+		{
+			name:    "kind not configured",
+			params:  SetParams{Kind: "slack", Target: "https://hooks.slack.com/services/x", Categories: []string{"security"}},
+			wantErr: ErrNotificationChannelUnavailable,
+		},
+		{
+			name:    "unknown kind",
+			params:  SetParams{Kind: "sms", Target: "42", Categories: []string{"security"}},
+			wantErr: ErrNotificationIncorrectKind,
+		},
+		{
+			name:    "malformed target",
+			params:  SetParams{Kind: "telegram", Target: "@alice", Categories: []string{"security"}},
+			wantErr: ErrNotificationIncorrectTarget,
+		},
+		{
+			name:    "unknown category",
+			params:  SetParams{Kind: "telegram", Target: "42", Categories: []string{"news"}},
+			wantErr: ErrNotificationIncorrectCategories,
+		},
+		{
+			name:    "save error",
+			params:  SetParams{Kind: "telegram", Target: "42", Categories: []string{"security"}},
+			saveErr: errors.New("connection refused"),
+			wantErr: ErrNotificationTechError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &mockRepository{saveErr: tt.saveErr}
+			if tt.existing != nil {
+				repo.channels = []*notification.Channel{tt.existing}
+			}
+			recorder := &mockAuditRecorder{}
+			senders := map[notification.Kind]Sender{notification.KindTelegram: &mockSender{}}
+			svc := NewService(repo, &mockPushRepository{}, &mockUserRepository{}, senders, nil, recorder)
+
+			tt.params.UserID = userID
+			got, err := svc.Set(context.Background(), tt.params)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, got)
+				assert.Empty(t, recorder.events)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "42", got.Hint)
+			assert.Equal(t, []string{"reminders", "security"}, got.Categories)
+			require.NotNil(t, repo.saved)
+			assert.Equal(t, userID, repo.saved.UserID)
+			if tt.existing != nil {
+				assert.Equal(t, tt.existing.ID, repo.saved.ID)
+			}
+			require.Len(t, recorder.events, 1)
+			assert.Equal(t, audit.EventNotificationChannelSet, recorder.events[0].Type)
+		})
+	}
+}
+
+func TestService_Delete(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		deleteErr error
+		wantErr   error
+		name      string
+	}{
+		{name: "deleted"},
+		{name: "not found", deleteErr: repository.ErrChannelNotFound, wantErr: ErrNotificationChannelNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &mockRepository{deleteErr: tt.deleteErr}
+			recorder := &mockAuditRecorder{}
+			svc := NewService(repo, &mockPushRepository{}, &mockUserRepository{}, nil, nil, recorder)
+			params := DeleteParams{Kind: "slack", UserID: uuid.New()}
+
+			err := svc.Delete(context.Background(), params)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Empty(t, recorder.events)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, repository.DeleteParams{Kind: notification.KindSlack, UserID: params.UserID}, repo.deleteParams)
+			require.Len(t, recorder.events, 1)
+			assert.Equal(t, audit.EventNotificationChannelDeleted, recorder.events[0].Type)
+		})
+	}
+}
+
+func TestService_Notify(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	sendErr := errors.New("network unreachable")
+
+	tests := []struct {
+		wantErr       error
+		name          string
+		login         string
+		channels      []*notification.Channel
+		subscriptions []*notification.PushSubscription
+		wantEmail     []string
+		wantTelegram  []string
+		wantPushed    []string
+		emailErr      bool
+		telegramErr   bool
+		withoutEmail  bool
+		wantFailures  int
+		wantReachable bool
+	}{
+		{
+			name:          "no channels falls back to e-mail login",
+			login:         "alice@example.com",
+			wantEmail:     []string{"alice@example.com: subject"},
+			wantReachable: true,
+		},
+		{
+			name:    "no channels and no e-mail login",
+			login:   "alice",
+			wantErr: ErrNotificationUnreachable,
+		},
+		{
+			name:         "no channels and no SMTP",
+			login:        "alice@example.com",
+			withoutEmail: true,
+			wantErr:      ErrNotificationUnreachable,
+		},
+		{
+			name:  "subscribed channels only",
+			login: "alice@example.com",
+			channels: []*notification.Channel{
+				newTestChannel(t, userID, notification.KindEmail, "bob@example.com", notification.CategoryReminders),
+				newTestChannel(t, userID, notification.KindTelegram, "42", notification.CategorySecurity),
+			},
+			wantTelegram:  []string{"42: subject"},
+			wantReachable: true,
+		},
+		{
+			name:  "configured channels without subscription",
+			login: "alice@example.com",
+			channels: []*notification.Channel{
+				newTestChannel(t, userID, notification.KindTelegram, "42", notification.CategoryReminders),
+			},
+			wantErr: ErrNotificationUnreachable,
+		},
+		{
+			name:  "channel kind no longer configured",
+			login: "alice@example.com",
+			channels: []*notification.Channel{
+				newTestChannel(t, userID, notification.KindSlack, "https://hooks.slack.com/services/x",
+					notification.CategorySecurity),
+			},
+			wantErr: ErrNotificationUnreachable,
+		},
+		{
+			name:          "subscribed device",
+			login:         "alice@example.com",
+			subscriptions: []*notification.PushSubscription{newTestPushSubscription(t, userID, "a", true)},
+			wantPushed:    []string{"https://push.example.com/a"},
+			wantReachable: true,
+		},
+		{
+			name:          "device without subscription",
+			login:         "alice@example.com",
+			subscriptions: []*notification.PushSubscription{newTestPushSubscription(t, userID, "a", false)},
+			wantErr:       ErrNotificationUnreachable,
+		},
+		{
+			name:  "one channel failed",
+			login: "alice@example.com",
+			channels: []*notification.Channel{
+				newTestChannel(t, userID, notification.KindEmail, "bob@example.com", notification.CategorySecurity),
+				newTestChannel(t, userID, notification.KindTelegram, "42", notification.CategorySecurity),
+			},
+			emailErr:      true,
+			wantEmail:     []string{"bob@example.com: subject"},
+			wantTelegram:  []string{"42: subject"},
+			wantFailures:  1,
+			wantReachable: true,
+		},
+		{
+			name:  "all channels failed",
+			login: "alice@example.com",
+			channels: []*notification.Channel{
+				newTestChannel(t, userID, notification.KindTelegram, "42", notification.CategorySecurity),
+			},
+			telegramErr:   true,
+			wantTelegram:  []string{"42: subject"},
+			wantFailures:  1,
+			wantErr:       sendErr,
+			wantReachable: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			email, telegram := &mockSender{}, &mockSender{}
+			if tt.emailErr {
+				email.err = sendErr
+			}
+			if tt.telegramErr {
+				telegram.err = sendErr
+			}
+			senders := map[notification.Kind]Sender{notification.KindEmail: email, notification.KindTelegram: telegram}
+			if tt.withoutEmail {
+				senders[notification.KindEmail] = nil
+			}
+			recorder := &mockAuditRecorder{}
+			push := &mockPushSender{}
+			svc := NewService(
+				&mockRepository{channels: tt.channels},
+				&mockPushRepository{subscriptions: tt.subscriptions},
+				&mockUserRepository{login: tt.login},
+				senders,
+				push,
+				recorder,
+			)
+
+			reachable, err := svc.Reachable(context.Background(), ReachableParams{
+				Category: notification.CategorySecurity,
+				UserID:   userID,
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantReachable, reachable)
+
+			err = svc.Notify(context.Background(), NotifyParams{
+				Category: notification.CategorySecurity,
+				Subject:  "subject",
+				Body:     "body",
+				UserID:   userID,
+			})
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantEmail, email.sent)
+			assert.Equal(t, tt.wantTelegram, telegram.sent)
+			assert.Equal(t, tt.wantPushed, push.to)
+			assert.Len(t, recorder.events, tt.wantFailures)
+			for _, e := range recorder.events {
+				assert.Equal(t, audit.EventNotificationFailed, e.Type)
+			}
+		})
+	}
+}
+
+func TestService_Notify_LoadErrors(t *testing.T) {
+	t.Parallel()
+
+	loadErr := errors.New("connection refused")
+	senders := map[notification.Kind]Sender{notification.KindEmail: &mockSender{}}
+
+	tests := []struct {
+		repo  *mockRepository
+		users *mockUserRepository
+		name  string
+	}{
+		{name: "channels", repo: &mockRepository{loadErr: loadErr}, users: &mockUserRepository{}},
+		{name: "user", repo: &mockRepository{}, users: &mockUserRepository{err: loadErr}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			svc := NewService(tt.repo, &mockPushRepository{}, tt.users, senders, nil, &mockAuditRecorder{})
+
+			err := svc.Notify(context.Background(), NotifyParams{Category: notification.CategorySecurity})
+
+			require.ErrorIs(t, err, ErrNotificationTechError)
+			assert.ErrorIs(t, err, loadErr)
+		})
+	}
+}
@@ -0,0 +1,6 @@
+// Package onboarding provides onboarding wizard application services for the AegisVaultKeeper server.
+//
+// This package implements the first-run wizard of a server with an empty database: it runs the steps of the
+// configured template, checking the master key, the file storage and the SMTP relay, and creates the first
+// account, after which the wizard is closed for good.
+package onboarding
@@ -0,0 +1,77 @@
+package onboarding
+
+import (
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/onboarding"
+	"github.com/google/uuid"
+)
+
+// Step represents an onboarding step with the result of its last run.
+type Step struct {
+	// CheckedAt indicates when the step last ran; zero for pending steps.
+	CheckedAt time.Time
+	// Name identifies the step: master_key, storage, smtp or admin.
+	Name string
+	// Title names the step for display.
+	Title string
+	// Description explains what the step does.
+	Description string
+	// Status contains the state of the step: pending, passed, failed or skipped.
+	Status string
+	// Message explains the state to the operator.
+	Message string
+	// Required determines whether the step must pass before the first account is created.
+	Required bool
+}
+
+// Status represents the progress of the onboarding wizard.
+type Status struct {
+	// Steps lists the steps of the wizard in order; empty once the server is initialized.
+	Steps []Step
+	// Next names the first step that neither passed nor was skipped.
+	Next string
+	// Initialized determines whether the server already has accounts, closing the wizard.
+	Initialized bool
+}
+
+// RunParams contains parameters for running an onboarding step.
+type RunParams struct {
+	// Step names the step to run.
+	Step string
+	// Email specifies the recipient of the test message of the smtp step.
+	Email string
+	// Login specifies the login of the account created by the admin step.
+	Login string
+	// Password specifies the password of the account created by the admin step.
+	Password string
+}
+
+// Account describes the first account created by the onboarding wizard.
+type Account struct {
+	// RecoveryCodes lists the single-use recovery codes of the account; they are only returned this once.
+	RecoveryCodes []string
+	// UserID identifies the account.
+	UserID uuid.UUID
+}
+
+// RunResult represents the outcome of an onboarding step.
+type RunResult struct {
+	// Account describes the account created by the admin step; nil for other steps.
+	Account *Account
+	// Step contains the step with the result of the run.
+	Step Step
+}
+
+// newStep combines the definition of a step with its result.
+func newStep(d onboarding.StepDefinition, r onboarding.Result) Step {
+	return Step{
+		CheckedAt:   r.CheckedAt,
+		Name:        d.Name,
+		Title:       d.Title,
+		Description: d.Description,
+		Status:      r.Status,
+		Message:     r.Message,
+		Required:    d.Required,
+	}
+}
@@ -0,0 +1,49 @@
+package onboarding
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/errutil"
+)
+
+// Onboarding error definitions.
+var (
+	// ErrOnboardingAppError indicates a general onboarding application error.
+	ErrOnboardingAppError = errors.New("onboarding application error")
+
+	// ErrOnboardingTechError indicates a technical error in the onboarding system.
+	ErrOnboardingTechError = errors.New("onboarding technical error")
+
+	// ErrAlreadyInitialized indicates that the server already has accounts, so the wizard is closed.
+	ErrAlreadyInitialized = errors.New("server is already initialized")
+
+	// ErrStepNotFound indicates that the wizard template has no step of the name.
+	ErrStepNotFound = errors.New("onboarding step not found")
+
+	// ErrStepsPending indicates that required steps preceding the admin step have not passed.
+	ErrStepsPending = errors.New("required onboarding steps have not passed")
+
+	// ErrRecipientRequired indicates that the SMTP step lacks the address of the test message.
+	ErrRecipientRequired = errors.New("test message recipient required")
+
+	// ErrAccountRequired indicates that the admin step lacks the login or the password of the account.
+	ErrAccountRequired = errors.New("account login and password required")
+)
+
+// mapError maps domain and repository errors to application-level errors.
+func mapError(err error) error {
+	if err == nil {
+		return nil
+	}
+	mapped := errutil.MapError(mapFn, err)
+	if mapped != nil {
+		return fmt.Errorf("onboarding error mapping failed: %w", mapped)
+	}
+	return nil
+}
+
+// mapFn provides the actual error mapping logic for different error types.
+func mapFn(err error) error {
+	return errors.Join(ErrOnboardingTechError, err)
+}
@@ -0,0 +1,244 @@
+package onboarding
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	authApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/auth"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/audit"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/onboarding"
+	"github.com/google/uuid"
+)
+
+const (
+	// recommendedMasterKeyLength specifies the master key length the master key step accepts.
+	recommendedMasterKeyLength = 32
+	// testMessageSubject is the subject of the test message of the smtp step.
+	testMessageSubject = "AegisVaultKeeper test message"
+	// testMessageBody is the body of the test message of the smtp step.
+	testMessageBody = "This message was sent by the onboarding wizard to check the SMTP relay of the server."
+)
+
+// UserRepository defines the interface for finding out whether the server has accounts.
+type UserRepository interface {
+	// ListIDs returns identifiers of all registered users.
+	ListIDs(ctx context.Context) ([]uuid.UUID, error)
+}
+
+// AccountRegistrar defines the interface for creating the first account of the server.
+type AccountRegistrar interface {
+	// RegisterFirst creates a user account without an invite code and returns its recovery codes.
+	RegisterFirst(ctx context.Context, params authApp.RegisterParams) (authApp.Registration, error)
+}
+
+// StorageProber defines the interface for checking the file storage.
+type StorageProber interface {
+	// Probe checks that the storage accepts, returns and removes files.
+	Probe(ctx context.Context) error
+}
+
+// Mailer defines the interface for sending the test message of the smtp step.
+type Mailer interface {
+	// Send delivers a plain-text message with the subject and body to the recipient.
+	Send(ctx context.Context, to, subject, body string) error
+}
+
+// AuditRecorder defines the interface for recording security audit events.
+type AuditRecorder interface {
+	// Record stores the audit event.
+	Record(ctx context.Context, e audit.Event)
+}
+
+// Service runs the onboarding wizard of a server with an empty database.
+// Step results are kept in memory, so a restarted server starts the checks over.
+type Service struct {
+	// users finds out whether the server has accounts.
+	users UserRepository
+	// accounts creates the first account.
+	accounts AccountRegistrar
+	// storage checks the file storage.
+	storage StorageProber
+	// mailer sends the test message; nil when no SMTP relay is configured.
+	mailer Mailer
+	// audit records the completion of the onboarding.
+	audit AuditRecorder
+	// progress holds the results of the steps run so far.
+	progress onboarding.Progress
+	// template lists the steps of the wizard.
+	template onboarding.Template
+	// masterKeyLength holds the length of the configured master key.
+	masterKeyLength int
+	// initialized caches that the server has accounts; it never becomes false again.
+	initialized bool
+	// mu serializes the steps, so concurrent requests cannot create two first accounts.
+	mu sync.Mutex
+}
+
+// NewService creates a new onboarding wizard running the steps of the template. A nil mailer skips the smtp step.
+func NewService(
+	users UserRepository,
+	accounts AccountRegistrar,
+	storage StorageProber,
+	mailer Mailer,
+	audit AuditRecorder,
+	template onboarding.Template,
+	masterKeyLength int,
+) *Service {
+	return &Service{
+		users:           users,
+		accounts:        accounts,
+		storage:         storage,
+		mailer:          mailer,
+		audit:           audit,
+		progress:        onboarding.Progress{},
+		template:        template,
+		masterKeyLength: masterKeyLength,
+	}
+}
+
+// Status reports whether the server still awaits onboarding and, while it does, the steps of the wizard with the
+// results of their last runs.
+func (s *Service) Status(ctx context.Context) (*Status, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	initialized, err := s.isInitialized(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if initialized {
+		return &Status{Initialized: true}, nil
+	}
+
+	status := &Status{Next: s.progress.Next(s.template), Steps: make([]Step, 0, len(s.template))}
+	for _, d := range s.template {
+		status.Steps = append(status.Steps, newStep(d, s.progress.Result(d.Name)))
+	}
+	return status, nil
+}
+
+// Run runs a step of the wizard. A failed check is reported in the result, not as an error, and the step may be
+// run again. The admin step requires every required step before it to have passed; the account it creates
+// closes the wizard.
+func (s *Service) Run(ctx context.Context, params RunParams) (*RunResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	initialized, err := s.isInitialized(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if initialized {
+		return nil, ErrAlreadyInitialized
+	}
+	d, ok := s.template.Step(params.Step)
+	if !ok {
+		return nil, fmt.Errorf("step %q: %w", params.Step, ErrStepNotFound)
+	}
+
+	// result holds the outcome of the step.
+	var result RunResult
+	switch d.Name {
+	case onboarding.StepMasterKey:
+		s.checkMasterKey()
+	case onboarding.StepStorage:
+		s.checkStorage(ctx)
+	case onboarding.StepSMTP:
+		if err := s.checkSMTP(ctx, params.Email); err != nil {
+			return nil, err
+		}
+	case onboarding.StepAdmin:
+		account, err := s.createAccount(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+		result.Account = account
+	}
+	result.Step = newStep(d, s.progress.Result(d.Name))
+	return &result, nil
+}
+
+// checkMasterKey checks that the master key is long enough. The key is read when the server starts, so it can
+// only be replaced in the configuration.
+func (s *Service) checkMasterKey() {
+	if s.masterKeyLength < recommendedMasterKeyLength {
+		s.progress.Record(onboarding.StepMasterKey, onboarding.StatusFailed, fmt.Sprintf(
+			"MASTER_KEY is %d characters long; set at least %d random characters and restart the server",
+			s.masterKeyLength, recommendedMasterKeyLength,
+		))
+		return
+	}
+	s.progress.Record(onboarding.StepMasterKey, onboarding.StatusPassed, "MASTER_KEY is long enough")
+}
+
+// checkStorage checks that the file storage accepts, returns and removes files.
+func (s *Service) checkStorage(ctx context.Context) {
+	if err := s.storage.Probe(ctx); err != nil {
+		s.progress.Record(onboarding.StepStorage, onboarding.StatusFailed, "File storage is not usable: "+err.Error())
+		return
+	}
+	s.progress.Record(onboarding.StepStorage, onboarding.StatusPassed, "File storage accepts, reads and removes files")
+}
+
+// checkSMTP sends a test message to the recipient; the step is skipped when no SMTP relay is configured.
+func (s *Service) checkSMTP(ctx context.Context, email string) error {
+	if s.mailer == nil {
+		s.progress.Record(onboarding.StepSMTP, onboarding.StatusSkipped,
+			"No SMTP relay is configured in SMTP_HOST; e-mail features stay disabled")
+		return nil
+	}
+	email = strings.TrimSpace(email)
+	if email == "" {
+		return ErrRecipientRequired
+	}
+	if err := s.mailer.Send(ctx, email, testMessageSubject, testMessageBody); err != nil {
+		s.progress.Record(onboarding.StepSMTP, onboarding.StatusFailed, "Test message could not be sent: "+err.Error())
+		return nil
+	}
+	s.progress.Record(onboarding.StepSMTP, onboarding.StatusPassed, "Test message sent to "+email)
+	return nil
+}
+
+// createAccount creates the first account once the required steps passed and closes the wizard.
+// Registration errors are returned as the authentication service reports them.
+func (s *Service) createAccount(ctx context.Context, params RunParams) (*Account, error) {
+	if !s.progress.Ready(s.template, onboarding.StepAdmin) {
+		return nil, fmt.Errorf("next step is %q: %w", s.progress.Next(s.template), ErrStepsPending)
+	}
+	if params.Login == "" || params.Password == "" {
+		return nil, ErrAccountRequired
+	}
+
+	registration, err := s.accounts.RegisterFirst(ctx, authApp.RegisterParams{
+		Login:    params.Login,
+		Password: params.Password,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create first account: %w", err)
+	}
+
+	s.initialized = true
+	s.progress.Record(onboarding.StepAdmin, onboarding.StatusPassed, "Account "+params.Login+" created")
+	s.audit.Record(ctx, audit.Event{
+		Type:    audit.EventServerOnboarded,
+		UserID:  registration.UserID,
+		Details: map[string]string{"login": params.Login},
+	})
+	return &Account{UserID: registration.UserID, RecoveryCodes: registration.RecoveryCodes}, nil
+}
+
+// isInitialized reports whether the server has accounts. A positive answer is cached, so the wizard stays closed
+// for the lifetime of the process.
+func (s *Service) isInitialized(ctx context.Context) (bool, error) {
+	if s.initialized {
+		return true, nil
+	}
+	ids, err := s.users.ListIDs(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to list users: %w", mapError(err))
+	}
+	s.initialized = len(ids) != 0
+	return s.initialized, nil
+}
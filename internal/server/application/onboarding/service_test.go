@@ -0,0 +1,285 @@
+package onboarding
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	authApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/auth"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/audit"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/onboarding"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockUserRepository struct {
+	err   error
+	ids   []uuid.UUID
+	calls int
+}
+
+func (m *mockUserRepository) ListIDs(context.Context) ([]uuid.UUID, error) {
+	m.calls++
+	return m.ids, m.err
+}
+
+type mockAccountRegistrar struct {
+	err    error
+	params authApp.RegisterParams
+	userID uuid.UUID
+}
+
+func (m *mockAccountRegistrar) RegisterFirst(
+	_ context.Context,
+	params authApp.RegisterParams,
+) (authApp.Registration, error) {
+	m.params = params
+	if m.err != nil {
+		return authApp.Registration{}, m.err
+	}
+	return authApp.Registration{UserID: m.userID, RecoveryCodes: []string{"abcd-efgh"}}, nil
+}
+
+type mockStorageProber struct {
+	err error
+}
+
+func (m *mockStorageProber) Probe(context.Context) error {
+	return m.err
+}
+
+type mockMailer struct {
+	err error
+	to  string
+}
+
+func (m *mockMailer) Send(_ context.Context, to, _, _ string) error {
+	m.to = to
+	return m.err
+}
+
+type mockAuditRecorder struct {
+	events []audit.Event
+}
+
+func (m *mockAuditRecorder) Record(_ context.Context, e audit.Event) {
+	m.events = append(m.events, e)
+}
+
+func newTestService(t *testing.T, users *mockUserRepository, mailer Mailer) (*Service, *mockAuditRecorder) {
+	t.Helper()
+
+	template, err := onboarding.NewTemplate(nil)
+	require.NoError(t, err)
+	recorder := &mockAuditRecorder{}
+	s := NewService(users, &mockAccountRegistrar{userID: uuid.New()}, &mockStorageProber{}, mailer, recorder,
+		template, recommendedMasterKeyLength)
+	return s, recorder
+}
+
+func TestService_Status(t *testing.T) {
+	t.Parallel()
+
+	t.Run("uninitialized", func(t *testing.T) {
+		t.Parallel()
+
+		s, _ := newTestService(t, &mockUserRepository{}, nil)
+
+		status, err := s.Status(context.Background())
+		require.NoError(t, err)
+		assert.False(t, status.Initialized)
+		assert.Equal(t, onboarding.StepMasterKey, status.Next)
+		require.Len(t, status.Steps, len(onboarding.DefaultSteps))
+		for i, step := range status.Steps {
+			assert.Equal(t, onboarding.DefaultSteps[i], step.Name)
+			assert.Equal(t, onboarding.StatusPending, step.Status)
+		}
+	})
+
+	t.Run("initialized is cached", func(t *testing.T) {
+		t.Parallel()
+
+		users := &mockUserRepository{ids: []uuid.UUID{uuid.New()}}
+		s, _ := newTestService(t, users, nil)
+
+		for range 2 {
+			status, err := s.Status(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, &Status{Initialized: true}, status)
+		}
+		assert.Equal(t, 1, users.calls)
+	})
+
+	t.Run("repository failure", func(t *testing.T) {
+		t.Parallel()
+
+		s, _ := newTestService(t, &mockUserRepository{err: errors.New("db down")}, nil)
+
+		_, err := s.Status(context.Background())
+		require.ErrorIs(t, err, ErrOnboardingTechError)
+	})
+}
+
+func TestService_Run(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		wantErr         error
+		prober          *mockStorageProber
+		mailer          Mailer
+		users           *mockUserRepository
+		name            string
+		params          RunParams
+		wantStatus      string
+		wantMessage     string
+		masterKeyLength int
+	}{
+		{
+			name:            "strong master key",
+			params:          RunParams{Step: onboarding.StepMasterKey},
+			masterKeyLength: 48,
+			wantStatus:      onboarding.StatusPassed,
+		},
+		{
+			name:            "short master key",
+			params:          RunParams{Step: onboarding.StepMasterKey},
+			masterKeyLength: 16,
+			wantStatus:      onboarding.StatusFailed,
+			wantMessage:     "MASTER_KEY is 16 characters long",
+		},
+		{
+			name:       "usable storage",
+			params:     RunParams{Step: onboarding.StepStorage},
+			wantStatus: onboarding.StatusPassed,
+		},
+		{
+			name:        "unusable storage",
+			params:      RunParams{Step: onboarding.StepStorage},
+			prober:      &mockStorageProber{err: errors.New("read-only file system")},
+			wantStatus:  onboarding.StatusFailed,
+			wantMessage: "read-only file system",
+		},
+		{
+			name:        "test message sent",
+			params:      RunParams{Step: onboarding.StepSMTP, Email: " ops@example.com "},
+			mailer:      &mockMailer{},
+			wantStatus:  onboarding.StatusPassed,
+			wantMessage: "ops@example.com",
+		},
+		{
+			name:        "test message rejected",
+			params:      RunParams{Step: onboarding.StepSMTP, Email: "ops@example.com"},
+			mailer:      &mockMailer{err: errors.New("535 authentication failed")},
+			wantStatus:  onboarding.StatusFailed,
+			wantMessage: "535 authentication failed",
+		},
+		{
+			name:        "no relay configured",
+			params:      RunParams{Step: onboarding.StepSMTP},
+			wantStatus:  onboarding.StatusSkipped,
+			wantMessage: "SMTP_HOST",
+		},
+		{
+			name:    "recipient missing",
+			params:  RunParams{Step: onboarding.StepSMTP},
+			mailer:  &mockMailer{},
+			wantErr: ErrRecipientRequired,
+		},
+		{
+			name:    "unknown step",
+			params:  RunParams{Step: "dns"},
+			wantErr: ErrStepNotFound,
+		},
+		{
+			name:    "admin before the checks",
+			params:  RunParams{Step: onboarding.StepAdmin, Login: "admin", Password: "Correct-Horse-42"},
+			wantErr: ErrStepsPending,
+		},
+		{
+			name:    "already initialized",
+			params:  RunParams{Step: onboarding.StepStorage},
+			users:   &mockUserRepository{ids: []uuid.UUID{uuid.New()}},
+			wantErr: ErrAlreadyInitialized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			template, err := onboarding.NewTemplate(nil)
+			require.NoError(t, err)
+			users := tt.users
+			if users == nil {
+				users = &mockUserRepository{}
+			}
+			prober := tt.prober
+			if prober == nil {
+				prober = &mockStorageProber{}
+			}
+			s := NewService(users, &mockAccountRegistrar{}, prober, tt.mailer, &mockAuditRecorder{},
+				template, tt.masterKeyLength)
+
+			result, err := s.Run(context.Background(), tt.params)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, result)
+				return
+			}
+			require.NoError(t, err)
+			assert.Nil(t, result.Account)
+			assert.Equal(t, tt.params.Step, result.Step.Name)
+			assert.Equal(t, tt.wantStatus, result.Step.Status)
+			assert.Contains(t, result.Step.Message, tt.wantMessage)
+			assert.False(t, result.Step.CheckedAt.IsZero())
+			if m, ok := tt.mailer.(*mockMailer); ok {
+				assert.Equal(t, "ops@example.com", m.to)
+			}
+		})
+	}
+}
+
+func TestService_Run_Admin(t *testing.T) {
+	t.Parallel()
+
+	users := &mockUserRepository{}
+	s, recorder := newTestService(t, users, nil)
+	ctx := context.Background()
+	admin := RunParams{Step: onboarding.StepAdmin, Login: "admin", Password: "Correct-Horse-42"}
+
+	_, err := s.Run(ctx, RunParams{Step: onboarding.StepMasterKey})
+	require.NoError(t, err)
+	_, err = s.Run(ctx, admin)
+	require.ErrorIs(t, err, ErrStepsPending, "the storage check has not passed")
+
+	_, err = s.Run(ctx, RunParams{Step: onboarding.StepStorage})
+	require.NoError(t, err)
+	_, err = s.Run(ctx, RunParams{Step: onboarding.StepAdmin, Login: "admin"})
+	require.ErrorIs(t, err, ErrAccountRequired)
+
+	registrar := s.accounts.(*mockAccountRegistrar)
+	registrar.err = authApp.ErrAuthUserAlreadyExists
+	_, err = s.Run(ctx, admin)
+	require.ErrorIs(t, err, authApp.ErrAuthUserAlreadyExists, "registration errors are not remapped")
+	assert.NotErrorIs(t, err, ErrOnboardingTechError)
+
+	registrar.err = nil
+	result, err := s.Run(ctx, admin)
+	require.NoError(t, err, "the skippable smtp step is optional")
+	assert.Equal(t, authApp.RegisterParams{Login: "admin", Password: "Correct-Horse-42"}, registrar.params)
+	require.NotNil(t, result.Account)
+	assert.Equal(t, registrar.userID, result.Account.UserID)
+	assert.Equal(t, []string{"abcd-efgh"}, result.Account.RecoveryCodes)
+	assert.Equal(t, onboarding.StatusPassed, result.Step.Status)
+
+	require.Len(t, recorder.events, 1)
+	assert.Equal(t, audit.EventServerOnboarded, recorder.events[0].Type)
+	assert.Equal(t, registrar.userID, recorder.events[0].UserID)
+
+	_, err = s.Run(ctx, admin)
+	require.ErrorIs(t, err, ErrAlreadyInitialized)
+	status, err := s.Status(ctx)
+	require.NoError(t, err)
+	assert.True(t, status.Initialized)
+}
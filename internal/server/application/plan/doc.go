@@ -0,0 +1,11 @@
+// Package plan provides subscription plan application services for the AegisVaultKeeper server.
+//
+// This package enforces the limits of the plan of each user on hosted deployments. Plans come from a plan
+// provider: a billing integration implements Provider to report the plan users pay for, and StaticProvider
+// gives every user the same plan. Services storing items, files and shares check the limits first and fail
+// with ErrPlanUpgradeRequired when the plan of the user does not allow the operation.
+//
+// SubscriptionService is the provider of billing integrations: it applies subscription events once and in
+// order, keeps a downgraded plan until the end of the period paid for and flags users over the item limit of
+// the plan they are left with.
+package plan
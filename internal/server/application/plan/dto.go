@@ -0,0 +1,81 @@
+package plan
+
+import (
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/plan"
+	"github.com/google/uuid"
+)
+
+// Limits represents the limits of a plan at the application layer. Zero numeric limits mean no limit.
+type Limits struct {
+	// MaxItems limits the number of stored vault items.
+	MaxItems int64
+	// MaxFileSize limits the size of a single file in bytes.
+	MaxFileSize int64
+	// MaxOrgSize limits the number of members of a group credentials are shared with.
+	MaxOrgSize int
+	// Sharing allows sharing credentials with groups.
+	Sharing bool
+}
+
+// Plan represents the plan of a user and its usage at the application layer.
+type Plan struct {
+	// Name identifies the plan; empty when plans are not enforced.
+	Name string
+	// Limits contains the limits of the plan.
+	Limits Limits
+	// Items contains the number of vault items the user stores.
+	Items int64
+	// Enforced reports whether the limits apply to the user.
+	Enforced bool
+	// OverQuota reports whether the user stores more items than the plan allows, such as after a downgrade.
+	OverQuota bool
+}
+
+// newPlanFromDomain converts the domain plan of a user and the number of items the user stores to its application
+// representation.
+// A nil plan yields a plan without limits.
+func newPlanFromDomain(p *plan.Plan, items int64) *Plan {
+	if p == nil {
+		return &Plan{Items: items, Limits: Limits{Sharing: true}}
+	}
+	return &Plan{
+		Name: p.Name,
+		Limits: Limits{
+			MaxItems:    p.Limits.MaxItems,
+			MaxFileSize: p.Limits.MaxFileSize,
+			MaxOrgSize:  p.Limits.MaxOrgSize,
+			Sharing:     p.Limits.Sharing,
+		},
+		Items:     items,
+		Enforced:  true,
+		OverQuota: !p.AllowsItems(items, 0),
+	}
+}
+
+// CurrentParams contains parameters for reporting the plan of a user.
+type CurrentParams struct {
+	// UserID identifies the user.
+	UserID uuid.UUID
+}
+
+// SyncParams contains parameters for applying a billing subscription event to the plan of a user.
+type SyncParams struct {
+	// EventAt is the time the billing provider created the event.
+	EventAt time.Time
+	// PeriodEnd is the end of the billing period paid for.
+	PeriodEnd time.Time
+	// EventID identifies the event at the billing provider, so redelivered events are applied once.
+	EventID string
+	// Plan names the plan the subscription grants.
+	Plan string
+	// Status contains the subscription status, such as plan.StatusActive.
+	Status string
+	// CustomerID identifies the customer at the billing provider.
+	CustomerID string
+	// SubscriptionID identifies the subscription at the billing provider.
+	SubscriptionID string
+	// UserID identifies the subscribed user.
+	UserID uuid.UUID
+}
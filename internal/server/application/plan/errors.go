@@ -0,0 +1,30 @@
+package plan
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Plan error definitions.
+var (
+	// ErrPlanUpgradeRequired indicates that the plan of the user does not allow the operation.
+	ErrPlanUpgradeRequired = errors.New("plan upgrade required")
+
+	// ErrPlanItemLimitReached indicates that the user stores as many items as the plan allows.
+	ErrPlanItemLimitReached = fmt.Errorf("item limit of the plan reached: %w", ErrPlanUpgradeRequired)
+
+	// ErrPlanFileTooLarge indicates that a file is larger than the plan allows.
+	ErrPlanFileTooLarge = fmt.Errorf("file size limit of the plan exceeded: %w", ErrPlanUpgradeRequired)
+
+	// ErrPlanSharingUnavailable indicates that the plan does not include sharing.
+	ErrPlanSharingUnavailable = fmt.Errorf("plan does not include sharing: %w", ErrPlanUpgradeRequired)
+
+	// ErrPlanOrgSizeExceeded indicates that a group has more members than the plan allows.
+	ErrPlanOrgSizeExceeded = fmt.Errorf("organization size limit of the plan exceeded: %w", ErrPlanUpgradeRequired)
+
+	// ErrPlanUserNotFound indicates that a billing subscription belongs to an unknown user.
+	ErrPlanUserNotFound = errors.New("subscribed user not found")
+
+	// ErrPlanTechError indicates a technical error in the plan system.
+	ErrPlanTechError = errors.New("plan technical error")
+)
@@ -0,0 +1,135 @@
+package plan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/plan"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/plan"
+	"github.com/google/uuid"
+)
+
+// Provider defines the plan provider hook: the interface a billing integration implements to report the plan
+// each user is subscribed to.
+type Provider interface {
+	// Plan returns the plan of the user, or nil when no limits apply to the user.
+	Plan(ctx context.Context, userID uuid.UUID) (*plan.Plan, error)
+}
+
+// Repository defines the interface for measuring the usage plans limit.
+type Repository interface {
+	// CountItems returns the number of vault items the user stores.
+	CountItems(ctx context.Context, params repository.CountItemsParams) (int64, error)
+}
+
+// StaticProvider gives every user the same plan.
+type StaticProvider struct {
+	// plan is the plan of every user; nil when no limits apply.
+	plan *plan.Plan
+}
+
+// NewStaticProvider creates a plan provider giving every user the built-in plan with the name.
+// An empty or unknown name gives users no limits.
+func NewStaticProvider(name string) *StaticProvider {
+	p, ok := plan.Lookup(name)
+	if !ok {
+		return &StaticProvider{}
+	}
+	return &StaticProvider{plan: &p}
+}
+
+// Plan returns the plan of every user.
+func (p *StaticProvider) Plan(context.Context, uuid.UUID) (*plan.Plan, error) {
+	return p.plan, nil
+}
+
+// Service enforces the limits of the plans of users.
+type Service struct {
+	// provider reports the plan of each user.
+	provider Provider
+	// r measures the usage of users.
+	r Repository
+}
+
+// NewService creates a new plan service enforcing the plans reported by the provider.
+func NewService(provider Provider, r Repository) *Service {
+	return &Service{provider: provider, r: r}
+}
+
+// Current reports the plan of the user, its limits and the number of items the user stores.
+func (s *Service) Current(ctx context.Context, params CurrentParams) (*Plan, error) {
+	p, err := s.plan(ctx, params.UserID)
+	if err != nil {
+		return nil, err
+	}
+	items, err := s.r.CountItems(ctx, repository.CountItemsParams{UserID: params.UserID})
+	if err != nil {
+		return nil, errors.Join(ErrPlanTechError, err)
+	}
+	return newPlanFromDomain(p, items), nil
+}
+
+// CheckItems ensures that the plan of the user has room for the given number of additional items.
+func (s *Service) CheckItems(ctx context.Context, userID uuid.UUID, added int) error {
+	p, err := s.plan(ctx, userID)
+	if err != nil || p == nil || p.Limits.MaxItems <= 0 || added <= 0 {
+		return err
+	}
+	items, err := s.r.CountItems(ctx, repository.CountItemsParams{UserID: userID})
+	if err != nil {
+		return errors.Join(ErrPlanTechError, err)
+	}
+	if !p.AllowsItems(items, int64(added)) {
+		return fmt.Errorf("%d of %d items stored on the %s plan: %w",
+			items, p.Limits.MaxItems, p.Name, ErrPlanItemLimitReached)
+	}
+	return nil
+}
+
+// CheckFileSize ensures that the plan of the user allows storing a file of size bytes.
+func (s *Service) CheckFileSize(ctx context.Context, userID uuid.UUID, size int64) error {
+	p, err := s.plan(ctx, userID)
+	if err != nil || p == nil {
+		return err
+	}
+	if !p.AllowsFileSize(size) {
+		return fmt.Errorf("%d bytes exceed the %d of the %s plan: %w",
+			size, p.Limits.MaxFileSize, p.Name, ErrPlanFileTooLarge)
+	}
+	return nil
+}
+
+// MaxFileSize returns the largest file in bytes the plan of the user allows; zero means no limit.
+func (s *Service) MaxFileSize(ctx context.Context, userID uuid.UUID) (int64, error) {
+	p, err := s.plan(ctx, userID)
+	if err != nil || p == nil {
+		return 0, err
+	}
+	return max(p.Limits.MaxFileSize, 0), nil
+}
+
+// CheckSharing ensures that the plan of the user allows sharing with a group of the given number of members.
+func (s *Service) CheckSharing(ctx context.Context, userID uuid.UUID, members int) error {
+	p, err := s.plan(ctx, userID)
+	if err != nil || p == nil {
+		return err
+	}
+	if !p.Limits.Sharing {
+		return fmt.Errorf("sharing on the %s plan: %w", p.Name, ErrPlanSharingUnavailable)
+	}
+	if !p.AllowsOrgSize(members) {
+		return fmt.Errorf("%d members exceed the %d of the %s plan: %w",
+			members, p.Limits.MaxOrgSize, p.Name, ErrPlanOrgSizeExceeded)
+	}
+	return nil
+}
+
+// plan returns the plan of the user, or nil when no limits apply.
+func (s *Service) plan(ctx context.Context, userID uuid.UUID) (*plan.Plan, error) {
+	p, err := s.provider.Plan(ctx, userID)
+	if err != nil {
+		return nil, errors.Join(ErrPlanTechError, fmt.Errorf("failed to look up the plan: %w", err))
+	}
+	return p, nil
+}
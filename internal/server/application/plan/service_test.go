@@ -0,0 +1,303 @@
+package plan
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/plan"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/plan"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// errBilling is the error of a failing billing integration.
+var errBilling = errors.New("billing unavailable")
+
+// mockProvider implements Provider for testing.
+type mockProvider struct {
+	err  error
+	plan *plan.Plan
+}
+
+func (m *mockProvider) Plan(context.Context, uuid.UUID) (*plan.Plan, error) {
+	return m.plan, m.err
+}
+
+// mockRepository implements Repository for testing.
+type mockRepository struct {
+	err   error
+	calls int
+	items int64
+}
+
+func (m *mockRepository) CountItems(context.Context, repository.CountItemsParams) (int64, error) {
+	m.calls++
+	return m.items, m.err
+}
+
+func TestNewStaticProvider(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		want *plan.Plan
+		name string
+		plan string
+	}{
+		{name: "free", plan: plan.NameFree, want: &plan.Free},
+		{name: "premium", plan: plan.NamePremium, want: &plan.Premium},
+		{name: "no plans", plan: ""},
+		{name: "unknown plan", plan: "enterprise"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := NewStaticProvider(tt.plan).Plan(context.Background(), uuid.New())
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestService_CheckItems(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		provider  *mockProvider
+		repo      *mockRepository
+		wantErr   error
+		name      string
+		added     int
+		wantCalls int
+	}{
+		{
+			name:      "within limit",
+			provider:  &mockProvider{plan: &plan.Free},
+			repo:      &mockRepository{items: 49},
+			added:     1,
+			wantCalls: 1,
+		},
+		{
+			name:      "limit reached",
+			provider:  &mockProvider{plan: &plan.Free},
+			repo:      &mockRepository{items: 49},
+			added:     2,
+			wantErr:   ErrPlanItemLimitReached,
+			wantCalls: 1,
+		},
+		{
+			name:     "nothing added",
+			provider: &mockProvider{plan: &plan.Free},
+			repo:     &mockRepository{items: 50},
+		},
+		{
+			name:     "unlimited plan",
+			provider: &mockProvider{plan: &plan.Premium},
+			repo:     &mockRepository{},
+			added:    1000,
+		},
+		{
+			name:     "no plan",
+			provider: &mockProvider{},
+			repo:     &mockRepository{},
+			added:    1000,
+		},
+		{
+			name:     "provider failure",
+			provider: &mockProvider{err: errBilling},
+			repo:     &mockRepository{},
+			added:    1,
+			wantErr:  ErrPlanTechError,
+		},
+		{
+			name:      "count failure",
+			provider:  &mockProvider{plan: &plan.Free},
+			repo:      &mockRepository{err: errors.New("database unavailable")},
+			added:     1,
+			wantErr:   ErrPlanTechError,
+			wantCalls: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := NewService(tt.provider, tt.repo).CheckItems(context.Background(), uuid.New(), tt.added)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantCalls, tt.repo.calls)
+		})
+	}
+}
+
+func TestService_CheckFileSize(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		plan    *plan.Plan
+		wantErr error
+		name    string
+		size    int64
+	}{
+		{name: "within limit", plan: &plan.Free, size: 10 << 20},
+		{name: "too large", plan: &plan.Free, size: 10<<20 + 1, wantErr: ErrPlanFileTooLarge},
+		{name: "unlimited plan", plan: &plan.Premium, size: 1 << 40},
+		{name: "no plan", size: 1 << 40},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			service := NewService(&mockProvider{plan: tt.plan}, &mockRepository{})
+
+			err := service.CheckFileSize(context.Background(), uuid.New(), tt.size)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				require.ErrorIs(t, err, ErrPlanUpgradeRequired)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestService_MaxFileSize(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		plan *plan.Plan
+		err  error
+		name string
+		want int64
+	}{
+		{name: "limited plan", plan: &plan.Free, want: 10 << 20},
+		{name: "unlimited plan", plan: &plan.Premium},
+		{name: "no plan"},
+		{name: "provider error", err: errBilling},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			service := NewService(&mockProvider{plan: tt.plan, err: tt.err}, &mockRepository{})
+
+			got, err := service.MaxFileSize(context.Background(), uuid.New())
+
+			if tt.err != nil {
+				require.ErrorIs(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestService_CheckSharing(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		plan    *plan.Plan
+		wantErr error
+		name    string
+		members int
+	}{
+		{name: "sharing unavailable", plan: &plan.Free, members: 1, wantErr: ErrPlanSharingUnavailable},
+		{name: "within limit", plan: &plan.Premium, members: 10},
+		{name: "group too large", plan: &plan.Premium, members: 11, wantErr: ErrPlanOrgSizeExceeded},
+		{name: "no plan", members: 1000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			service := NewService(&mockProvider{plan: tt.plan}, &mockRepository{})
+
+			err := service.CheckSharing(context.Background(), uuid.New(), tt.members)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				require.ErrorIs(t, err, ErrPlanUpgradeRequired)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestService_Current(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		provider *mockProvider
+		repo     *mockRepository
+		wantErr  error
+		want     *Plan
+		name     string
+	}{
+		{
+			name:     "free plan",
+			provider: &mockProvider{plan: &plan.Free},
+			repo:     &mockRepository{items: 7},
+			want: &Plan{
+				Name:     plan.NameFree,
+				Limits:   Limits{MaxItems: 50, MaxFileSize: 10 << 20},
+				Items:    7,
+				Enforced: true,
+			},
+		},
+		{
+			name:     "over quota",
+			provider: &mockProvider{plan: &plan.Free},
+			repo:     &mockRepository{items: 51},
+			want: &Plan{
+				Name:      plan.NameFree,
+				Limits:    Limits{MaxItems: 50, MaxFileSize: 10 << 20},
+				Items:     51,
+				Enforced:  true,
+				OverQuota: true,
+			},
+		},
+		{
+			name:     "no plan",
+			provider: &mockProvider{},
+			repo:     &mockRepository{items: 7},
+			want:     &Plan{Limits: Limits{Sharing: true}, Items: 7},
+		},
+		{
+			name:     "provider failure",
+			provider: &mockProvider{err: errBilling},
+			repo:     &mockRepository{},
+			wantErr:  errBilling,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := NewService(tt.provider, tt.repo).Current(context.Background(), CurrentParams{UserID: uuid.New()})
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				require.ErrorIs(t, err, ErrPlanTechError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package plan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/plan"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/plan"
+	"github.com/google/uuid"
+)
+
+// SubscriptionRepository defines the interface for subscription persistence and billing event deduplication.
+type SubscriptionRepository interface {
+	// LoadSubscription retrieves the subscription of a user.
+	LoadSubscription(ctx context.Context, params repository.LoadSubscriptionParams) (*plan.Subscription, error)
+	// SaveSubscription stores a subscription unless a later billing event is already stored.
+	SaveSubscription(ctx context.Context, params repository.SaveSubscriptionParams) error
+	// HasEvent reports whether a billing event was already applied.
+	HasEvent(ctx context.Context, params repository.EventParams) (bool, error)
+	// SaveEvent records a billing event as applied.
+	SaveEvent(ctx context.Context, params repository.EventParams) error
+}
+
+// SubscriptionService keeps the plans of users in sync with their billing subscriptions. It implements
+// Provider: users get the plans of their subscriptions, and the fallback plans without one.
+type SubscriptionService struct {
+	// r stores the subscriptions and the applied billing events.
+	r SubscriptionRepository
+	// items measures the usage of users.
+	items Repository
+	// fallback reports the plan of users without a subscription granting one.
+	fallback Provider
+	// now returns the current time.
+	now func() time.Time
+}
+
+// NewSubscriptionService creates a new subscription service falling back to the plans of the fallback
+// provider for users without a subscription.
+func NewSubscriptionService(r SubscriptionRepository, items Repository, fallback Provider) *SubscriptionService {
+	return &SubscriptionService{r: r, items: items, fallback: fallback, now: time.Now}
+}
+
+// Plan returns the plan the subscription of the user gives, or the fallback plan.
+func (s *SubscriptionService) Plan(ctx context.Context, userID uuid.UUID) (*plan.Plan, error) {
+	sub, err := s.load(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.planAt(ctx, userID, sub, s.now())
+}
+
+// Sync applies a billing subscription event to the plan of the user and reports whether it changed the stored
+// subscription. Redelivered events and events older than the stored subscription are skipped.
+//
+// A downgrade never cuts the paid period short: the previous plan is kept until the end of the period paid
+// for, whatever proration the billing provider applied. Users storing more items than the plan they are left
+// with are flagged over quota; they keep their items and may read, update and delete them, but cannot add
+// items until they are back under the limit.
+func (s *SubscriptionService) Sync(ctx context.Context, params SyncParams) (bool, error) {
+	seen, err := s.r.HasEvent(ctx, repository.EventParams{ID: params.EventID})
+	if err != nil {
+		return false, errors.Join(ErrPlanTechError, err)
+	}
+	if seen {
+		return false, nil
+	}
+	prev, err := s.load(ctx, params.UserID)
+	if err != nil {
+		return false, err
+	}
+	if prev != nil && params.EventAt.Before(prev.EventAt) {
+		return false, s.markApplied(ctx, params.EventID)
+	}
+
+	now := s.now()
+	next := &plan.Subscription{
+		EventAt:        params.EventAt,
+		PeriodEnd:      params.PeriodEnd,
+		UpdatedAt:      now,
+		Plan:           params.Plan,
+		Status:         params.Status,
+		CustomerID:     params.CustomerID,
+		SubscriptionID: params.SubscriptionID,
+		UserID:         params.UserID,
+	}
+	target, err := s.planAt(ctx, params.UserID, next, now)
+	if err != nil {
+		return false, err
+	}
+	if prev != nil {
+		if err := s.keepPaidPlan(ctx, prev, next, target, now); err != nil {
+			return false, err
+		}
+	}
+	if target != nil && target.Limits.MaxItems > 0 {
+		items, err := s.items.CountItems(ctx, repository.CountItemsParams{UserID: params.UserID})
+		if err != nil {
+			return false, errors.Join(ErrPlanTechError, err)
+		}
+		next.OverQuota = !target.AllowsItems(items, 0)
+	}
+
+	if err := s.r.SaveSubscription(ctx, repository.SaveSubscriptionParams{Entity: next}); err != nil {
+		if errors.Is(err, repository.ErrSubscriptionUserNotFound) {
+			return false, fmt.Errorf("user %s: %w", params.UserID, ErrPlanUserNotFound)
+		}
+		return false, errors.Join(ErrPlanTechError, err)
+	}
+	return true, s.markApplied(ctx, params.EventID)
+}
+
+// keepPaidPlan keeps the plan the previous subscription gives until the end of its paid period when the next
+// subscription downgrades the user to the target plan.
+func (s *SubscriptionService) keepPaidPlan(
+	ctx context.Context,
+	prev, next *plan.Subscription,
+	target *plan.Plan,
+	now time.Time,
+) error {
+	current, err := s.planAt(ctx, prev.UserID, prev, now)
+	if err != nil {
+		return err
+	}
+	if current == nil || target == nil || target.Covers(current) {
+		return nil
+	}
+	until := prev.PeriodEnd
+	if prev.GracePlan == current.Name && prev.GraceUntil.After(until) {
+		until = prev.GraceUntil
+	}
+	if until.After(now) {
+		next.GracePlan, next.GraceUntil = current.Name, until
+	}
+	return nil
+}
+
+// load returns the subscription of the user, or nil without one.
+func (s *SubscriptionService) load(ctx context.Context, userID uuid.UUID) (*plan.Subscription, error) {
+	sub, err := s.r.LoadSubscription(ctx, repository.LoadSubscriptionParams{UserID: userID})
+	if errors.Is(err, repository.ErrSubscriptionNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Join(ErrPlanTechError, err)
+	}
+	return sub, nil
+}
+
+// planAt returns the plan the subscription gives at the moment, or the fallback plan when it gives none.
+func (s *SubscriptionService) planAt(
+	ctx context.Context,
+	userID uuid.UUID,
+	sub *plan.Subscription,
+	at time.Time,
+) (*plan.Plan, error) {
+	if sub != nil {
+		if p, ok := plan.Lookup(sub.PlanAt(at)); ok {
+			return &p, nil
+		}
+	}
+	p, err := s.fallback.Plan(ctx, userID)
+	if err != nil {
+		return nil, errors.Join(ErrPlanTechError, fmt.Errorf("failed to look up the fallback plan: %w", err))
+	}
+	return p, nil
+}
+
+// markApplied records the billing event as applied.
+func (s *SubscriptionService) markApplied(ctx context.Context, eventID string) error {
+	if err := s.r.SaveEvent(ctx, repository.EventParams{ID: eventID}); err != nil {
+		return errors.Join(ErrPlanTechError, err)
+	}
+	return nil
+}
@@ -0,0 +1,263 @@
+package plan
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/plan"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/plan"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockSubscriptionRepository implements SubscriptionRepository for testing.
+type mockSubscriptionRepository struct {
+	loadErr error
+	saveErr error
+	sub     *plan.Subscription
+	saved   *plan.Subscription
+	events  map[string]bool
+}
+
+func (m *mockSubscriptionRepository) LoadSubscription(
+	context.Context,
+	repository.LoadSubscriptionParams,
+) (*plan.Subscription, error) {
+	if m.loadErr != nil {
+		return nil, m.loadErr
+	}
+	if m.sub == nil {
+		return nil, repository.ErrSubscriptionNotFound
+	}
+	return m.sub, nil
+}
+
+func (m *mockSubscriptionRepository) SaveSubscription(_ context.Context, p repository.SaveSubscriptionParams) error {
+	if m.saveErr != nil {
+		return m.saveErr
+	}
+	m.saved = p.Entity
+	return nil
+}
+
+func (m *mockSubscriptionRepository) HasEvent(_ context.Context, p repository.EventParams) (bool, error) {
+	return m.events[p.ID], nil
+}
+
+func (m *mockSubscriptionRepository) SaveEvent(_ context.Context, p repository.EventParams) error {
+	if m.events == nil {
+		m.events = make(map[string]bool)
+	}
+	m.events[p.ID] = true
+	return nil
+}
+
+func TestSubscriptionService_Plan(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		sub  *plan.Subscription
+		want *plan.Plan
+		name string
+	}{
+		{
+			name: "no subscription",
+			want: &plan.Free,
+		},
+		{
+			name: "active subscription",
+			sub:  &plan.Subscription{Plan: plan.NamePremium, Status: plan.StatusActive},
+			want: &plan.Premium,
+		},
+		{
+			name: "canceled subscription",
+			sub:  &plan.Subscription{Plan: plan.NamePremium, Status: plan.StatusCanceled},
+			want: &plan.Free,
+		},
+		{
+			name: "canceled subscription in grace",
+			sub: &plan.Subscription{
+				GraceUntil: now.Add(time.Hour),
+				Plan:       plan.NamePremium,
+				Status:     plan.StatusCanceled,
+				GracePlan:  plan.NamePremium,
+			},
+			want: &plan.Premium,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := NewSubscriptionService(
+				&mockSubscriptionRepository{sub: tt.sub}, &mockRepository{}, &mockProvider{plan: &plan.Free},
+			)
+			s.now = func() time.Time { return now }
+
+			got, err := s.Plan(context.Background(), uuid.New())
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSubscriptionService_Sync(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	userID := uuid.New()
+	premium := &plan.Subscription{
+		EventAt:   now.Add(-24 * time.Hour),
+		PeriodEnd: now.Add(10 * 24 * time.Hour),
+		Plan:      plan.NamePremium,
+		Status:    plan.StatusActive,
+		UserID:    userID,
+	}
+
+	tests := []struct {
+		repo          *mockSubscriptionRepository
+		wantErr       error
+		want          *plan.Subscription
+		name          string
+		params        SyncParams
+		items         int64
+		wantApplied   bool
+		wantRecorded  bool
+		wantOverQuota bool
+	}{
+		{
+			name: "new subscription",
+			repo: &mockSubscriptionRepository{},
+			params: SyncParams{
+				EventAt: now, EventID: "evt_1", Plan: plan.NamePremium, Status: plan.StatusActive, UserID: userID,
+			},
+			items:        120,
+			want:         &plan.Subscription{Plan: plan.NamePremium, Status: plan.StatusActive},
+			wantApplied:  true,
+			wantRecorded: true,
+		},
+		{
+			name: "redelivered event",
+			repo: &mockSubscriptionRepository{events: map[string]bool{"evt_1": true}},
+			params: SyncParams{
+				EventAt: now, EventID: "evt_1", Plan: plan.NamePremium, Status: plan.StatusActive, UserID: userID,
+			},
+			wantRecorded: true,
+		},
+		{
+			name: "stale event",
+			repo: &mockSubscriptionRepository{sub: premium},
+			params: SyncParams{
+				EventAt: premium.EventAt.Add(-time.Minute), EventID: "evt_0", Plan: plan.NameFree,
+				Status: plan.StatusActive, UserID: userID,
+			},
+			wantRecorded: true,
+		},
+		{
+			name: "downgrade keeps the paid period",
+			repo: &mockSubscriptionRepository{sub: premium},
+			params: SyncParams{
+				EventAt: now, EventID: "evt_2", Plan: plan.NameFree, Status: plan.StatusActive, UserID: userID,
+			},
+			items: 120,
+			want: &plan.Subscription{
+				GraceUntil: premium.PeriodEnd,
+				Plan:       plan.NameFree,
+				Status:     plan.StatusActive,
+				GracePlan:  plan.NamePremium,
+			},
+			wantApplied:   true,
+			wantRecorded:  true,
+			wantOverQuota: true,
+		},
+		{
+			name: "cancellation after the paid period flags over quota",
+			repo: &mockSubscriptionRepository{sub: &plan.Subscription{
+				EventAt:   now.Add(-40 * 24 * time.Hour),
+				PeriodEnd: now.Add(-time.Hour),
+				Plan:      plan.NamePremium,
+				Status:    plan.StatusPastDue,
+				UserID:    userID,
+			}},
+			params: SyncParams{
+				EventAt: now, EventID: "evt_3", Plan: plan.NamePremium, Status: plan.StatusCanceled, UserID: userID,
+			},
+			items:         120,
+			want:          &plan.Subscription{Plan: plan.NamePremium, Status: plan.StatusCanceled},
+			wantApplied:   true,
+			wantRecorded:  true,
+			wantOverQuota: true,
+		},
+		{
+			name: "upgrade clears grace",
+			repo: &mockSubscriptionRepository{sub: &plan.Subscription{
+				EventAt:    now.Add(-time.Hour),
+				GraceUntil: now.Add(time.Hour),
+				Plan:       plan.NameFree,
+				Status:     plan.StatusActive,
+				GracePlan:  plan.NamePremium,
+				UserID:     userID,
+			}},
+			params: SyncParams{
+				EventAt: now, EventID: "evt_4", Plan: plan.NamePremium, Status: plan.StatusActive, UserID: userID,
+			},
+			items:        120,
+			want:         &plan.Subscription{Plan: plan.NamePremium, Status: plan.StatusActive},
+			wantApplied:  true,
+			wantRecorded: true,
+		},
+		{
+			name: "unknown user",
+			repo: &mockSubscriptionRepository{saveErr: repository.ErrSubscriptionUserNotFound},
+			params: SyncParams{
+				EventAt: now, EventID: "evt_5", Plan: plan.NamePremium, Status: plan.StatusActive, UserID: userID,
+			},
+			wantErr: ErrPlanUserNotFound,
+		},
+		{
+			name: "load failure",
+			repo: &mockSubscriptionRepository{loadErr: errBilling},
+			params: SyncParams{
+				EventAt: now, EventID: "evt_6", Plan: plan.NamePremium, Status: plan.StatusActive, UserID: userID,
+			},
+			wantErr: ErrPlanTechError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := NewSubscriptionService(tt.repo, &mockRepository{items: tt.items}, &mockProvider{plan: &plan.Free})
+			s.now = func() time.Time { return now }
+
+			applied, err := s.Sync(context.Background(), tt.params)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.False(t, tt.repo.events[tt.params.EventID], "failed events are retried")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantApplied, applied)
+			assert.Equal(t, tt.wantRecorded, tt.repo.events[tt.params.EventID])
+			if tt.want == nil {
+				assert.Nil(t, tt.repo.saved)
+				return
+			}
+			require.NotNil(t, tt.repo.saved)
+			assert.Equal(t, tt.want.Plan, tt.repo.saved.Plan)
+			assert.Equal(t, tt.want.Status, tt.repo.saved.Status)
+			assert.Equal(t, tt.want.GracePlan, tt.repo.saved.GracePlan)
+			assert.Equal(t, tt.want.GraceUntil, tt.repo.saved.GraceUntil)
+			assert.Equal(t, tt.wantOverQuota, tt.repo.saved.OverQuota)
+			assert.Equal(t, now, tt.repo.saved.UpdatedAt)
+		})
+	}
+}
@@ -0,0 +1,6 @@
+// Package purge provides item purge application services for the AegisVaultKeeper server.
+//
+// This package implements the two-step permanent deletion of every vault item of one kind: the first step
+// reports the number of affected items with a short-lived confirmation token, the second step deletes the
+// items in one transaction when the token is presented and the items are still the same.
+package purge
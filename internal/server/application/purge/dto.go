@@ -0,0 +1,45 @@
+package purge
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PrepareParams contains parameters for requesting the purge of the items of one kind.
+type PrepareParams struct {
+	// Kind selects the kind of the items to purge.
+	Kind string
+	// UserID identifies the user owning the items.
+	UserID uuid.UUID
+}
+
+// Confirmation describes a requested purge awaiting confirmation.
+type Confirmation struct {
+	// ExpiresAt is the time after which the token no longer confirms the purge.
+	ExpiresAt time.Time
+	// Token confirms the purge of exactly the counted items.
+	Token string
+	// Kind is the kind of the items to purge.
+	Kind string
+	// Count is the number of items the purge deletes.
+	Count int
+}
+
+// ExecuteParams contains parameters for executing a confirmed purge.
+type ExecuteParams struct {
+	// Token is the confirmation token returned when the purge was requested.
+	Token string
+	// Kind selects the kind of the items to purge; it must match the kind of the token.
+	Kind string
+	// UserID identifies the user owning the items.
+	UserID uuid.UUID
+}
+
+// Result describes an executed purge.
+type Result struct {
+	// Kind is the kind of the purged items.
+	Kind string
+	// Deleted is the number of deleted items.
+	Deleted int
+}
@@ -0,0 +1,28 @@
+package purge
+
+import "errors"
+
+// Item purge error definitions.
+var (
+	// ErrUnknownKind indicates that items of the specified kind cannot be purged.
+	ErrUnknownKind = errors.New("unknown item kind")
+
+	// ErrNoItems indicates that the user has no items of the specified kind.
+	ErrNoItems = errors.New("no items to purge")
+
+	// ErrInvalidToken indicates that the confirmation token is malformed, expired or issued for another purge.
+	ErrInvalidToken = errors.New("invalid purge confirmation token")
+
+	// ErrItemsChanged indicates that items of the kind were added, changed or removed after the confirmation
+	// token was issued.
+	ErrItemsChanged = errors.New("items changed since the purge was requested")
+
+	// ErrItemsOnLegalHold indicates that the user or some of the items are under legal hold and cannot be purged.
+	ErrItemsOnLegalHold = errors.New("items are under legal hold")
+
+	// ErrPurgeAccessDenied indicates that changing the items of the kind is not permitted.
+	ErrPurgeAccessDenied = errors.New("access to these items is denied")
+
+	// ErrPurgeTechError indicates a technical error in the item purge system.
+	ErrPurgeTechError = errors.New("item purge technical error")
+)
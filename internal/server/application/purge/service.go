@@ -0,0 +1,248 @@
+package purge
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	authzApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/authz"
+	legalholdApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/legalhold"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/audit"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/authz"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/purge"
+	"github.com/google/uuid"
+)
+
+// defaultTokenTTL is how long confirmation tokens are accepted when no lifetime is configured.
+const defaultTokenTTL = 5 * time.Minute
+
+// kinds lists the kinds of items that can be purged. Files are not purged this way, because their
+// contents are kept outside the database and cannot be removed in the same transaction.
+var kinds = []string{authz.KindBankCard, authz.KindCredential, authz.KindNote}
+
+// Repository defines the interface for item purge persistence operations.
+type Repository interface {
+	// Load lists the items of one kind of a user in ID order.
+	Load(ctx context.Context, params repository.LoadParams) ([]*repository.Item, error)
+	// Delete permanently removes the listed items of one kind of a user and returns how many were deleted.
+	Delete(ctx context.Context, params repository.DeleteParams) (int64, error)
+}
+
+// UnitOfWork defines the interface for composing repository operations into a single transaction.
+type UnitOfWork interface {
+	// Do executes fn in a single transaction scoped to the user, rolling back every write on error.
+	Do(ctx context.Context, userID uuid.UUID, fn func(ctx context.Context) error) error
+}
+
+// TokenGenerateValidator defines the interface for issuing and validating purge confirmation tokens.
+type TokenGenerateValidator interface {
+	// GeneratePurgeToken creates the confirmation token of a purge of the items with the digest.
+	GeneratePurgeToken(userID uuid.UUID, kind, digest string, ttl time.Duration) (string, time.Time, error)
+	// ValidatePurgeToken validates a confirmation token and returns the user, the kind and the digest it binds.
+	ValidatePurgeToken(token string) (uuid.UUID, string, string, error)
+}
+
+// Authorizer defines the interface for the authorization decision point.
+type Authorizer interface {
+	// Authorize decides whether the user may perform the action on the items.
+	Authorize(ctx context.Context, req authzApp.Request) error
+}
+
+// HoldResolver defines the interface for resolving the users and items under legal hold.
+type HoldResolver interface {
+	// Held returns the users and items whose data must not be permanently deleted.
+	Held(ctx context.Context) (*legalholdApp.Held, error)
+}
+
+// AuditRecorder defines the interface for recording security audit events.
+type AuditRecorder interface {
+	// Record stores the audit event.
+	Record(ctx context.Context, e audit.Event)
+}
+
+// Service provides the two-step permanent deletion of every item of one kind.
+type Service struct {
+	// r is the repository interface for item purge persistence operations.
+	r Repository
+	// uow runs the deletions of a purge in one transaction.
+	uow UnitOfWork
+	// tokens issues and validates confirmation tokens.
+	tokens TokenGenerateValidator
+	// authorizer decides whether users may change the items they purge.
+	authorizer Authorizer
+	// holds resolves the users and items under legal hold that cannot be purged.
+	holds HoldResolver
+	// audit records executed purges.
+	audit AuditRecorder
+	// tokenTTL is how long confirmation tokens are accepted.
+	tokenTTL time.Duration
+}
+
+// NewService creates a new item purge service accepting confirmation tokens for tokenTTL
+// (0 uses five minutes).
+func NewService(
+	r Repository,
+	uow UnitOfWork,
+	tokens TokenGenerateValidator,
+	authorizer Authorizer,
+	holds HoldResolver,
+	audit AuditRecorder,
+	tokenTTL time.Duration,
+) *Service {
+	if tokenTTL <= 0 {
+		tokenTTL = defaultTokenTTL
+	}
+	return &Service{
+		r:          r,
+		uow:        uow,
+		tokens:     tokens,
+		authorizer: authorizer,
+		holds:      holds,
+		audit:      audit,
+		tokenTTL:   tokenTTL,
+	}
+}
+
+// Prepare requests the purge of every item of the kind of the user and returns the number of affected items
+// with the token confirming their purge. Nothing is deleted until the purge is executed with the token.
+func (s *Service) Prepare(ctx context.Context, params PrepareParams) (*Confirmation, error) {
+	if err := s.check(ctx, params.Kind, params.UserID); err != nil {
+		return nil, err
+	}
+
+	items, err := s.r.Load(ctx, repository.LoadParams{Kind: params.Kind, UserID: params.UserID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load items to purge: %w", mapError(err))
+	}
+	if len(items) == 0 {
+		return nil, ErrNoItems
+	}
+	held, err := s.holds.Held(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve legal holds: %w", errors.Join(ErrPurgeTechError, err))
+	}
+	if err := checkHolds(held, params.UserID, items); err != nil {
+		return nil, err
+	}
+
+	token, expiresAt, err := s.tokens.GeneratePurgeToken(params.UserID, params.Kind, digest(items), s.tokenTTL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to issue purge confirmation token: %w", errors.Join(ErrPurgeTechError, err))
+	}
+	return &Confirmation{Token: token, Kind: params.Kind, Count: len(items), ExpiresAt: expiresAt}, nil
+}
+
+// Execute permanently deletes the items confirmed by the token in one transaction. The purge is refused when
+// any item of the kind was added, changed or removed after the token was issued, or when the user or any of the
+// items was put on legal hold.
+func (s *Service) Execute(ctx context.Context, params ExecuteParams) (*Result, error) {
+	if err := s.check(ctx, params.Kind, params.UserID); err != nil {
+		return nil, err
+	}
+
+	userID, kind, confirmed, err := s.tokens.ValidatePurgeToken(params.Token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to validate purge confirmation token: %w: %w", ErrInvalidToken, err)
+	}
+	if userID != params.UserID || kind != params.Kind {
+		return nil, fmt.Errorf("purge confirmation token issued for another purge: %w", ErrInvalidToken)
+	}
+
+	held, err := s.holds.Held(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve legal holds: %w", errors.Join(ErrPurgeTechError, err))
+	}
+
+	// deleted counts the items removed by the transaction.
+	var deleted int64
+	err = s.uow.Do(ctx, params.UserID, func(ctx context.Context) error {
+		items, err := s.r.Load(ctx, repository.LoadParams{Kind: params.Kind, UserID: params.UserID})
+		if err != nil {
+			return fmt.Errorf("failed to load items to purge: %w", mapError(err))
+		}
+		if digest(items) != confirmed {
+			return ErrItemsChanged
+		}
+		if err := checkHolds(held, params.UserID, items); err != nil {
+			return err
+		}
+
+		ids := make([]uuid.UUID, 0, len(items))
+		for _, item := range items {
+			ids = append(ids, item.ID)
+		}
+		deleted, err = s.r.Delete(ctx, repository.DeleteParams{IDs: ids, Kind: params.Kind, UserID: params.UserID})
+		if err != nil {
+			return fmt.Errorf("failed to delete purged items: %w", mapError(err))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to purge %s items: %w", params.Kind, err)
+	}
+
+	s.audit.Record(ctx, audit.Event{
+		Type:   audit.EventItemsPurged,
+		UserID: params.UserID,
+		Details: map[string]string{
+			"kind":    params.Kind,
+			"deleted": strconv.FormatInt(deleted, 10),
+		},
+	})
+	return &Result{Kind: params.Kind, Deleted: int(deleted)}, nil
+}
+
+// check ensures that items of the kind can be purged and that the user may change the whole collection.
+func (s *Service) check(ctx context.Context, kind string, userID uuid.UUID) error {
+	if !slices.Contains(kinds, kind) {
+		return ErrUnknownKind
+	}
+
+	err := s.authorizer.Authorize(ctx, authzApp.Request{
+		Action:    authz.ActionWrite,
+		Kind:      kind,
+		OwnerID:   userID,
+		SubjectID: userID,
+	})
+	if err != nil {
+		return fmt.Errorf("access denied to %s items: %w: %w", kind, ErrPurgeAccessDenied, err)
+	}
+	return nil
+}
+
+// checkHolds ensures that neither the user nor any of the items is under legal hold.
+func checkHolds(held *legalholdApp.Held, userID uuid.UUID, items []*repository.Item) error {
+	ids := make([]uuid.UUID, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.ID)
+	}
+	if held.Covers(userID, ids...) {
+		return ErrItemsOnLegalHold
+	}
+	return nil
+}
+
+// digest summarizes the identities and revisions of the items, so a confirmation token only confirms the purge
+// of the very items it was issued for.
+func digest(items []*repository.Item) string {
+	h := sha256.New()
+	for _, item := range items {
+		h.Write(item.ID[:])
+		_ = binary.Write(h, binary.BigEndian, item.UpdatedAt.UnixNano())
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// mapError maps repository errors to application-level errors.
+func mapError(err error) error {
+	if errors.Is(err, repository.ErrUnknownKind) {
+		return ErrUnknownKind
+	}
+	return errors.Join(ErrPurgeTechError, err)
+}
@@ -0,0 +1,381 @@
+package purge
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	authzApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/authz"
+	legalholdApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/legalhold"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/audit"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/purge"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// errDatabase is the error of failing repository operations.
+var errDatabase = errors.New("database unavailable")
+
+// mockRepository implements Repository for testing.
+type mockRepository struct {
+	loadErr   error
+	deleteErr error
+	items     []*repository.Item
+	deleted   []uuid.UUID
+}
+
+func (m *mockRepository) Load(_ context.Context, _ repository.LoadParams) ([]*repository.Item, error) {
+	if m.loadErr != nil {
+		return nil, m.loadErr
+	}
+	return m.items, nil
+}
+
+func (m *mockRepository) Delete(_ context.Context, params repository.DeleteParams) (int64, error) {
+	if m.deleteErr != nil {
+		return 0, m.deleteErr
+	}
+	m.deleted = params.IDs
+	return int64(len(params.IDs)), nil
+}
+
+// mockUnitOfWork implements UnitOfWork for testing, running fn directly.
+type mockUnitOfWork struct {
+	calls int
+}
+
+func (m *mockUnitOfWork) Do(ctx context.Context, _ uuid.UUID, fn func(ctx context.Context) error) error {
+	m.calls++
+	return fn(ctx)
+}
+
+// mockTokens implements TokenGenerateValidator for testing, encoding the bound values in the token.
+type mockTokens struct {
+	generateErr error
+}
+
+func (m *mockTokens) GeneratePurgeToken(
+	userID uuid.UUID,
+	kind, digest string,
+	ttl time.Duration,
+) (string, time.Time, error) {
+	if m.generateErr != nil {
+		return "", time.Time{}, m.generateErr
+	}
+	return userID.String() + "|" + kind + "|" + digest, time.Unix(0, 0).Add(ttl), nil
+}
+
+func (m *mockTokens) ValidatePurgeToken(token string) (uuid.UUID, string, string, error) {
+	parts := strings.Split(token, "|")
+	if len(parts) != 3 {
+		return uuid.Nil, "", "", errors.New("JWT error: malformed token")
+	}
+	userID, err := uuid.Parse(parts[0])
+	if err != nil {
+		return uuid.Nil, "", "", errors.New("JWT error: malformed token")
+	}
+	return userID, parts[1], parts[2], nil
+}
+
+// mockAuthorizer implements Authorizer for testing.
+type mockAuthorizer struct {
+	err error
+}
+
+func (m *mockAuthorizer) Authorize(context.Context, authzApp.Request) error {
+	return m.err
+}
+
+// mockHoldResolver implements HoldResolver for testing.
+type mockHoldResolver struct {
+	err  error
+	held legalholdApp.Held
+}
+
+func (m *mockHoldResolver) Held(context.Context) (*legalholdApp.Held, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &m.held, nil
+}
+
+// mockAuditRecorder implements AuditRecorder for testing.
+type mockAuditRecorder struct {
+	events []audit.Event
+}
+
+func (m *mockAuditRecorder) Record(_ context.Context, e audit.Event) {
+	m.events = append(m.events, e)
+}
+
+// testItems returns two stored items.
+func testItems() []*repository.Item {
+	return []*repository.Item{
+		{ID: uuid.New(), UpdatedAt: time.Unix(100, 0)},
+		{ID: uuid.New(), UpdatedAt: time.Unix(200, 0)},
+	}
+}
+
+func TestNewService_DefaultTokenTTL(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, defaultTokenTTL, NewService(nil, nil, nil, nil, nil, nil, 0).tokenTTL)
+	assert.Equal(t, time.Minute, NewService(nil, nil, nil, nil, nil, nil, time.Minute).tokenTTL)
+}
+
+func TestService_Prepare(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	items := testItems()
+
+	tests := []struct {
+		repo      *mockRepository
+		tokens    *mockTokens
+		holds     *mockHoldResolver
+		authErr   error
+		wantErr   error
+		name      string
+		kind      string
+		wantCount int
+	}{
+		{
+			name:      "confirmation issued",
+			repo:      &mockRepository{items: items},
+			tokens:    &mockTokens{},
+			kind:      "credential",
+			wantCount: 2,
+		},
+		{
+			name:    "unknown kind",
+			repo:    &mockRepository{items: items},
+			tokens:  &mockTokens{},
+			kind:    "file",
+			wantErr: ErrUnknownKind,
+		},
+		{
+			name:    "access denied",
+			repo:    &mockRepository{items: items},
+			tokens:  &mockTokens{},
+			authErr: authzApp.ErrAccessDenied,
+			kind:    "note",
+			wantErr: ErrPurgeAccessDenied,
+		},
+		{
+			name:    "no items",
+			repo:    &mockRepository{},
+			tokens:  &mockTokens{},
+			kind:    "bankcard",
+			wantErr: ErrNoItems,
+		},
+		{
+			name:    "load error",
+			repo:    &mockRepository{loadErr: errDatabase},
+			tokens:  &mockTokens{},
+			kind:    "bankcard",
+			wantErr: ErrPurgeTechError,
+		},
+		{
+			name:    "token error",
+			repo:    &mockRepository{items: items},
+			tokens:  &mockTokens{generateErr: errors.New("JWT error")},
+			kind:    "bankcard",
+			wantErr: ErrPurgeTechError,
+		},
+		{
+			name:    "user on legal hold",
+			repo:    &mockRepository{items: items},
+			tokens:  &mockTokens{},
+			holds:   &mockHoldResolver{held: legalholdApp.Held{UserIDs: []uuid.UUID{userID}}},
+			kind:    "note",
+			wantErr: ErrItemsOnLegalHold,
+		},
+		{
+			name:    "item on legal hold",
+			repo:    &mockRepository{items: items},
+			tokens:  &mockTokens{},
+			holds:   &mockHoldResolver{held: legalholdApp.Held{ItemIDs: []uuid.UUID{items[1].ID}}},
+			kind:    "note",
+			wantErr: ErrItemsOnLegalHold,
+		},
+		{
+			name:    "hold lookup error",
+			repo:    &mockRepository{items: items},
+			tokens:  &mockTokens{},
+			holds:   &mockHoldResolver{err: errDatabase},
+			kind:    "note",
+			wantErr: ErrPurgeTechError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			holds := tt.holds
+			if holds == nil {
+				holds = &mockHoldResolver{}
+			}
+			s := NewService(tt.repo, &mockUnitOfWork{}, tt.tokens, &mockAuthorizer{err: tt.authErr}, holds,
+				&mockAuditRecorder{}, time.Minute)
+
+			got, err := s.Prepare(context.Background(), PrepareParams{Kind: tt.kind, UserID: userID})
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, got)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.kind, got.Kind)
+			assert.Equal(t, tt.wantCount, got.Count)
+			assert.Equal(t, time.Unix(0, 0).Add(time.Minute), got.ExpiresAt)
+			assert.NotEmpty(t, got.Token)
+			assert.Empty(t, tt.repo.deleted)
+		})
+	}
+}
+
+func TestService_Execute(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	items := testItems()
+	token := userID.String() + "|credential|" + digest(items)
+
+	tests := []struct {
+		repo        *mockRepository
+		holds       *mockHoldResolver
+		authErr     error
+		wantErr     error
+		name        string
+		kind        string
+		token       string
+		wantDeleted int
+	}{
+		{
+			name:        "items purged",
+			repo:        &mockRepository{items: items},
+			kind:        "credential",
+			token:       token,
+			wantDeleted: 2,
+		},
+		{
+			name:    "unknown kind",
+			repo:    &mockRepository{items: items},
+			kind:    "file",
+			token:   token,
+			wantErr: ErrUnknownKind,
+		},
+		{
+			name:    "access denied",
+			repo:    &mockRepository{items: items},
+			authErr: authzApp.ErrAccessDenied,
+			kind:    "credential",
+			token:   token,
+			wantErr: ErrPurgeAccessDenied,
+		},
+		{
+			name:    "malformed token",
+			repo:    &mockRepository{items: items},
+			kind:    "credential",
+			token:   "not.a.token",
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name:    "token of another user",
+			repo:    &mockRepository{items: items},
+			kind:    "credential",
+			token:   uuid.NewString() + "|credential|" + digest(items),
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name:    "token of another kind",
+			repo:    &mockRepository{items: items},
+			kind:    "note",
+			token:   token,
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name:    "item added",
+			repo:    &mockRepository{items: append(testItems(), items...)},
+			kind:    "credential",
+			token:   token,
+			wantErr: ErrItemsChanged,
+		},
+		{
+			name: "item changed",
+			repo: &mockRepository{items: []*repository.Item{
+				items[0], {ID: items[1].ID, UpdatedAt: items[1].UpdatedAt.Add(time.Second)},
+			}},
+			kind:    "credential",
+			token:   token,
+			wantErr: ErrItemsChanged,
+		},
+		{
+			name:    "items already purged",
+			repo:    &mockRepository{},
+			kind:    "credential",
+			token:   token,
+			wantErr: ErrItemsChanged,
+		},
+		{
+			name:    "delete error",
+			repo:    &mockRepository{items: items, deleteErr: errDatabase},
+			kind:    "credential",
+			token:   token,
+			wantErr: ErrPurgeTechError,
+		},
+		{
+			name:    "hold placed after confirmation",
+			repo:    &mockRepository{items: items},
+			holds:   &mockHoldResolver{held: legalholdApp.Held{ItemIDs: []uuid.UUID{items[0].ID}}},
+			kind:    "credential",
+			token:   token,
+			wantErr: ErrItemsOnLegalHold,
+		},
+		{
+			name:    "hold lookup error",
+			repo:    &mockRepository{items: items},
+			holds:   &mockHoldResolver{err: errDatabase},
+			kind:    "credential",
+			token:   token,
+			wantErr: ErrPurgeTechError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			uow := &mockUnitOfWork{}
+			recorder := &mockAuditRecorder{}
+			holds := tt.holds
+			if holds == nil {
+				holds = &mockHoldResolver{}
+			}
+			s := NewService(tt.repo, uow, &mockTokens{}, &mockAuthorizer{err: tt.authErr}, holds, recorder,
+				time.Minute)
+
+			got, err := s.Execute(context.Background(), ExecuteParams{Token: tt.token, Kind: tt.kind, UserID: userID})
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, got)
+				assert.Empty(t, recorder.events)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, &Result{Kind: tt.kind, Deleted: tt.wantDeleted}, got)
+			assert.Equal(t, 1, uow.calls)
+			assert.Equal(t, []uuid.UUID{items[0].ID, items[1].ID}, tt.repo.deleted)
+			require.Len(t, recorder.events, 1)
+			assert.Equal(t, audit.EventItemsPurged, recorder.events[0].Type)
+			assert.Equal(t, userID, recorder.events[0].UserID)
+			assert.Equal(t, map[string]string{"kind": "credential", "deleted": "2"}, recorder.events[0].Details)
+		})
+	}
+}
@@ -0,0 +1,7 @@
+// Package recording provides the request/response recording mode for debugging support cases.
+//
+// Administrators record the requests of a user or a route for a limited time window to investigate client
+// issues that cannot be reproduced otherwise. Captured pairs are sanitized before they are stored: credentials
+// and tokens are removed from headers, and the string values of request and response bodies are redacted except
+// for identifiers, timestamps and status fields, so recordings never reveal vault contents.
+package recording
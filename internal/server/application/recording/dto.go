@@ -0,0 +1,156 @@
+package recording
+
+import (
+	"net/http"
+	"time"
+
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/recording"
+	"github.com/google/uuid"
+)
+
+// Settings contains the recording mode settings.
+type Settings struct {
+	// MaxWindow limits the time window of a recording (0 disables the recording mode).
+	MaxWindow time.Duration
+	// Retention specifies how long recordings are kept after their window ends (0 keeps them until deleted).
+	Retention time.Duration
+	// MaxBodySize limits the bytes captured of every request and response body (0 captures no bodies).
+	MaxBodySize int
+}
+
+// Session describes a recording.
+type Session struct {
+	// StartedAt is the time the recording started.
+	StartedAt time.Time
+	// ExpiresAt is the time the recording stops capturing requests.
+	ExpiresAt time.Time
+	// ID identifies the recording.
+	ID uuid.UUID
+	// UserID selects the requests of a single user; uuid.Nil records the requests of every user.
+	UserID uuid.UUID
+	// Route selects the requests whose path starts with the prefix; empty records every path.
+	Route string
+	// Reason explains why the recording was started, such as a support case reference.
+	Reason string
+	// Exchanges is the number of request/response pairs captured.
+	Exchanges int
+	// Active reports whether the recording is capturing requests.
+	Active bool
+}
+
+// newSession converts a stored recording session, reporting whether it is active at the moment.
+func newSession(s *repository.Session, at time.Time) *Session {
+	return &Session{
+		StartedAt: s.StartedAt,
+		ExpiresAt: s.ExpiresAt,
+		ID:        s.ID,
+		UserID:    s.UserID,
+		Route:     s.Route,
+		Reason:    s.Reason,
+		Exchanges: s.Exchanges,
+		Active:    isActive(s, at),
+	}
+}
+
+// isActive reports whether the stored recording session captures requests at the moment.
+func isActive(s *repository.Session, at time.Time) bool {
+	return !s.StartedAt.After(at) && s.ExpiresAt.After(at)
+}
+
+// StartParams contains parameters for starting a recording.
+type StartParams struct {
+	// UserID selects the requests of a single user; uuid.Nil records the requests of every user.
+	UserID uuid.UUID
+	// Route selects the requests whose path starts with the prefix; empty records every path.
+	Route string
+	// Reason explains why the recording is started, such as a support case reference.
+	Reason string
+	// Duration is the time window of the recording.
+	Duration time.Duration
+}
+
+// RecordParams contains a handled request and its response as seen by the server, before sanitization.
+type RecordParams struct {
+	// StartedAt is the time the request was received.
+	StartedAt time.Time
+	// RequestHeader contains the request headers.
+	RequestHeader http.Header
+	// ResponseHeader contains the response headers.
+	ResponseHeader http.Header
+	// RequestBody contains the captured beginning of the request body.
+	RequestBody []byte
+	// ResponseBody contains the captured beginning of the response body.
+	ResponseBody []byte
+	// UserID identifies the authenticated user; uuid.Nil for anonymous requests.
+	UserID uuid.UUID
+	// RequestID identifies the request in the logs.
+	RequestID string
+	// Method is the HTTP method of the request.
+	Method string
+	// Path is the URL path of the request.
+	Path string
+	// Route is the route pattern that handled the request; empty when no route matched.
+	Route string
+	// Query is the raw query string of the request.
+	Query string
+	// Duration is how long the request took to handle.
+	Duration time.Duration
+	// Status is the HTTP status code of the response.
+	Status int
+	// RequestTruncated reports whether the request body was longer than the captured part.
+	RequestTruncated bool
+	// ResponseTruncated reports whether the response body was longer than the captured part.
+	ResponseTruncated bool
+}
+
+// Exchange is a captured request/response pair after sanitization.
+type Exchange struct {
+	// RecordedAt is the time the request was received.
+	RecordedAt time.Time
+	// RequestHeaders contains the request headers; credentials are redacted.
+	RequestHeaders map[string]string
+	// ResponseHeaders contains the response headers; credentials are redacted.
+	ResponseHeaders map[string]string
+	// ID identifies the pair.
+	ID uuid.UUID
+	// UserID identifies the authenticated user; uuid.Nil for anonymous requests.
+	UserID uuid.UUID
+	// RequestID identifies the request in the logs.
+	RequestID string
+	// Method is the HTTP method of the request.
+	Method string
+	// Path is the URL path of the request.
+	Path string
+	// Route is the route pattern that handled the request.
+	Route string
+	// Query is the sanitized query string of the request.
+	Query string
+	// RequestBody is the sanitized request body.
+	RequestBody string
+	// ResponseBody is the sanitized response body.
+	ResponseBody string
+	// Duration is how long the request took to handle.
+	Duration time.Duration
+	// Status is the HTTP status code of the response.
+	Status int
+}
+
+// newExchange converts a stored request/response pair.
+func newExchange(e *repository.Exchange) *Exchange {
+	return &Exchange{
+		RecordedAt:      e.RecordedAt,
+		RequestHeaders:  e.RequestHeaders,
+		ResponseHeaders: e.ResponseHeaders,
+		ID:              e.ID,
+		UserID:          e.UserID,
+		RequestID:       e.RequestID,
+		Method:          e.Method,
+		Path:            e.Path,
+		Route:           e.Route,
+		Query:           e.Query,
+		RequestBody:     e.RequestBody,
+		ResponseBody:    e.ResponseBody,
+		Duration:        e.Duration,
+		Status:          e.Status,
+	}
+}
@@ -0,0 +1,25 @@
+package recording
+
+import "errors"
+
+// Recording error definitions.
+var (
+	// ErrRecordingDisabled indicates that the recording mode is switched off in the configuration.
+	ErrRecordingDisabled = errors.New("recording mode disabled")
+
+	// ErrRecordingTargetRequired indicates that a recording selects neither a user nor a route.
+	ErrRecordingTargetRequired = errors.New("recording requires a user or a route")
+
+	// ErrRecordingInvalidRoute indicates that the route of a recording is not an absolute path prefix.
+	ErrRecordingInvalidRoute = errors.New("recording route must start with a slash")
+
+	// ErrRecordingInvalidDuration indicates that the time window of a recording is not positive or exceeds
+	// the configured maximum.
+	ErrRecordingInvalidDuration = errors.New("recording duration out of range")
+
+	// ErrRecordingNotFound indicates that no recording with the specified ID exists.
+	ErrRecordingNotFound = errors.New("recording not found")
+
+	// ErrRecordingTechError indicates a technical error in the recording system.
+	ErrRecordingTechError = errors.New("recording technical error")
+)
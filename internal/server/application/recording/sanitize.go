@@ -0,0 +1,166 @@
+package recording
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Redacted replaces the values removed from captured requests and responses.
+const Redacted = "[REDACTED]"
+
+// sensitiveHeaderParts lists the fragments of header names whose values are never recorded.
+var sensitiveHeaderParts = []string{
+	"auth", "cookie", "token", "secret", "key", "signature", "password", "session", "otp", "captcha",
+}
+
+// safeFields lists the JSON fields and query parameters whose string values are recorded as is.
+var safeFields = []string{
+	"id", "type", "kind", "status", "state", "code", "error", "messages", "method", "version", "plan",
+	"limit", "offset", "page", "sort", "order", "fields", "format",
+}
+
+// isSafeField reports whether the string values of the JSON field or query parameter are recorded as is:
+// identifiers, timestamps and the fields describing states and errors.
+func isSafeField(name string) bool {
+	name = strings.ToLower(name)
+	return slices.Contains(safeFields, name) || strings.HasSuffix(name, "_id") || strings.HasSuffix(name, "_at")
+}
+
+// sanitizeHeaders flattens the headers and redacts the values of those carrying credentials.
+// Returns nil for no headers.
+func sanitizeHeaders(h http.Header) map[string]string {
+	if len(h) == 0 {
+		return nil
+	}
+	headers := make(map[string]string, len(h))
+	for name, values := range h {
+		name = http.CanonicalHeaderKey(name)
+		if isSensitiveHeader(name) {
+			headers[name] = Redacted
+			continue
+		}
+		headers[name] = strings.Join(values, ", ")
+	}
+	return headers
+}
+
+// isSensitiveHeader reports whether the header may carry credentials.
+func isSensitiveHeader(name string) bool {
+	name = strings.ToLower(name)
+	for _, part := range sensitiveHeaderParts {
+		if strings.Contains(name, part) {
+			return true
+		}
+	}
+	return false
+}
+
+// sanitizeQuery redacts the values of the query parameters that are not safe fields, keeping parameter names
+// in alphabetical order. An unparsable query is redacted as a whole.
+func sanitizeQuery(raw string) string {
+	if raw == "" {
+		return ""
+	}
+	values, err := url.ParseQuery(raw)
+	if err != nil {
+		return Redacted
+	}
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		for _, v := range values[name] {
+			if b.Len() > 0 {
+				b.WriteByte('&')
+			}
+			b.WriteString(url.QueryEscape(name))
+			b.WriteByte('=')
+			if isSafeField(name) {
+				b.WriteString(url.QueryEscape(v))
+			} else {
+				b.WriteString(Redacted)
+			}
+		}
+	}
+	return b.String()
+}
+
+// sanitizeBody redacts a captured body. Complete JSON bodies keep their structure, numbers, booleans and the
+// strings of safe fields, and form bodies are redacted like queries. Other bodies, and bodies cut off at the
+// capture limit, are replaced with a summary of their size and content type.
+func sanitizeBody(contentType string, body []byte, truncated bool) string {
+	if len(body) == 0 {
+		return ""
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = "application/octet-stream"
+	}
+	if !truncated {
+		switch {
+		case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+			if sanitized, ok := sanitizeJSON(body); ok {
+				return sanitized
+			}
+		case mediaType == "application/x-www-form-urlencoded":
+			return sanitizeQuery(string(body))
+		}
+	}
+
+	size := strconv.Itoa(len(body))
+	if truncated {
+		size += "+"
+	}
+	return fmt.Sprintf("[%s bytes of %s]", size, mediaType)
+}
+
+// sanitizeJSON redacts the string values of a JSON document except those of safe fields.
+// Reports false when the body is not valid JSON.
+func sanitizeJSON(body []byte) (string, bool) {
+	// document holds the decoded body; numbers are kept as written.
+	var document any
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&document); err != nil || decoder.More() {
+		return "", false
+	}
+	sanitized, err := json.Marshal(redactJSON(document, false))
+	if err != nil {
+		return "", false
+	}
+	return string(sanitized), true
+}
+
+// redactJSON replaces the strings of the decoded JSON value with Redacted unless they belong to a safe field.
+func redactJSON(value any, safe bool) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for name, field := range v {
+			v[name] = redactJSON(field, isSafeField(name))
+		}
+		return v
+	case []any:
+		for i, element := range v {
+			v[i] = redactJSON(element, safe)
+		}
+		return v
+	case string:
+		if safe {
+			return v
+		}
+		return Redacted
+	default:
+		return v
+	}
+}
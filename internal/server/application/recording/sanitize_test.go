@@ -0,0 +1,141 @@
+package recording
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSanitizeHeaders(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		header http.Header
+		want   map[string]string
+		name   string
+	}{
+		{name: "no headers"},
+		{
+			name: "credentials redacted",
+			header: http.Header{
+				"Authorization":   {"Bearer eyJhbGciOi"},
+				"Cookie":          {"session=abc"},
+				"X-Admin-Token":   {"secret"},
+				"X-Api-Key":       {"key"},
+				"Content-Type":    {"application/json"},
+				"Accept-Encoding": {"gzip", "br"},
+			},
+			want: map[string]string{
+				"Authorization":   Redacted,
+				"Cookie":          Redacted,
+				"X-Admin-Token":   Redacted,
+				"X-Api-Key":       Redacted,
+				"Content-Type":    "application/json",
+				"Accept-Encoding": "gzip, br",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, sanitizeHeaders(tt.header))
+		})
+	}
+}
+
+func TestSanitizeQuery(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		raw  string
+		want string
+	}{
+		{name: "empty", raw: "", want: ""},
+		{name: "safe parameters kept", raw: "type=note&limit=10", want: "limit=10&type=note"},
+		{
+			name: "other parameters redacted",
+			raw:  "search=bank&token=abc&item_id=42",
+			want: "item_id=42&search=" + Redacted + "&token=" + Redacted,
+		},
+		{name: "unparsable", raw: "a=%zz", want: Redacted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, sanitizeQuery(tt.raw))
+		})
+	}
+}
+
+func TestSanitizeBody(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		want        string
+		truncated   bool
+	}{
+		{name: "empty", contentType: "application/json", body: "", want: ""},
+		{
+			name:        "json strings redacted",
+			contentType: "application/json; charset=utf-8",
+			body: `{"id":"7f1c","login":"user@example.com","password":"hunter2",` +
+				`"created_at":"2026-10-15T12:00:00Z","size":42,"tags":["a","b"],"favorite":true,"note":null}`,
+			want: `{"created_at":"2026-10-15T12:00:00Z","favorite":true,"id":"7f1c","login":"[REDACTED]",` +
+				`"note":null,"password":"[REDACTED]","size":42,"tags":["[REDACTED]","[REDACTED]"]}`,
+		},
+		{
+			name:        "nested json",
+			contentType: "application/problem+json",
+			body:        `{"messages":["card has expired"],"items":[{"item_id":"1","number":"4111"}]}`,
+			want:        `{"items":[{"item_id":"1","number":"[REDACTED]"}],"messages":["card has expired"]}`,
+		},
+		{
+			name:        "invalid json summarized",
+			contentType: "application/json",
+			body:        `{"password":`,
+			want:        "[12 bytes of application/json]",
+		},
+		{
+			name:        "truncated json summarized",
+			contentType: "application/json",
+			body:        `{"id":"1"}`,
+			truncated:   true,
+			want:        "[10+ bytes of application/json]",
+		},
+		{
+			name:        "form redacted",
+			contentType: "application/x-www-form-urlencoded",
+			body:        "grant_type=password&password=hunter2",
+			want:        "grant_type=" + Redacted + "&password=" + Redacted,
+		},
+		{
+			name:        "binary summarized",
+			contentType: "application/octet-stream",
+			body:        "\x00\x01\x02",
+			want:        "[3 bytes of application/octet-stream]",
+		},
+		{
+			name:        "unknown content type",
+			contentType: "",
+			body:        "plain",
+			want:        "[5 bytes of application/octet-stream]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, sanitizeBody(tt.contentType, []byte(tt.body), tt.truncated))
+		})
+	}
+}
@@ -0,0 +1,322 @@
+package recording
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/audit"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/recording"
+	"github.com/google/uuid"
+)
+
+// reloadInterval is how long the loaded active recordings are used before they are loaded again, so
+// recordings started through another server instance capture requests on every instance.
+const reloadInterval = 10 * time.Second
+
+// purgeInterval is how often recordings past their retention period are removed.
+const purgeInterval = time.Hour
+
+// Repository defines the interface for recording persistence operations.
+type Repository interface {
+	// SaveSession stores a new recording session or updates the expiry time of an existing one.
+	SaveSession(ctx context.Context, params repository.SaveSessionParams) error
+	// LoadSessions retrieves recording sessions matching the provided parameters.
+	LoadSessions(ctx context.Context, params repository.LoadSessionsParams) ([]*repository.Session, error)
+	// DeleteSession removes a recording session together with its captured pairs.
+	DeleteSession(ctx context.Context, id uuid.UUID) error
+	// DeleteExpired removes the recording sessions that expired before the moment.
+	DeleteExpired(ctx context.Context, before time.Time) (int64, error)
+	// SaveExchange encrypts and stores a captured request/response pair.
+	SaveExchange(ctx context.Context, params repository.SaveExchangeParams) error
+	// LoadExchanges retrieves the pairs captured for a recording session.
+	LoadExchanges(ctx context.Context, sessionID uuid.UUID) ([]*repository.Exchange, error)
+}
+
+// AuditRecorder defines the interface for recording security audit events.
+type AuditRecorder interface {
+	// Record stores the audit event.
+	Record(ctx context.Context, e audit.Event)
+}
+
+// Service provides the request/response recording mode.
+type Service struct {
+	// r is the repository interface for recording persistence operations.
+	r Repository
+	// audit records started, stopped and deleted recordings.
+	audit AuditRecorder
+	// now returns the current time.
+	now func() time.Time
+	// loadedAt is the time the active recordings were last loaded; zero forces the next load.
+	loadedAt time.Time
+	// active holds the loaded active recordings.
+	active []*repository.Session
+	// settings contains the recording mode settings.
+	settings Settings
+	// mu guards loadedAt and active.
+	mu sync.Mutex
+}
+
+// NewService creates a new recording service with the specified settings.
+func NewService(r Repository, audit AuditRecorder, settings Settings) *Service {
+	return &Service{r: r, audit: audit, now: time.Now, settings: settings}
+}
+
+// BodyLimit returns how many bytes of every request and response body are captured.
+func (s *Service) BodyLimit() int {
+	return max(s.settings.MaxBodySize, 0)
+}
+
+// PurgeInterval returns how often recordings past their retention period are removed;
+// 0 when recordings are kept until deleted or the recording mode is disabled.
+func (s *Service) PurgeInterval() time.Duration {
+	if s.settings.MaxWindow <= 0 || s.settings.Retention <= 0 {
+		return 0
+	}
+	return purgeInterval
+}
+
+// Start starts recording the requests of the user, of the route or of the user on the route for the duration.
+func (s *Service) Start(ctx context.Context, params StartParams) (*Session, error) {
+	if s.settings.MaxWindow <= 0 {
+		return nil, ErrRecordingDisabled
+	}
+	route := strings.TrimSpace(params.Route)
+	if params.UserID == uuid.Nil && route == "" {
+		return nil, ErrRecordingTargetRequired
+	}
+	if route != "" && !strings.HasPrefix(route, "/") {
+		return nil, ErrRecordingInvalidRoute
+	}
+	if params.Duration <= 0 || params.Duration > s.settings.MaxWindow {
+		return nil, fmt.Errorf("%w: must be positive and at most %s", ErrRecordingInvalidDuration, s.settings.MaxWindow)
+	}
+
+	now := s.now()
+	session := repository.Session{
+		StartedAt: now,
+		ExpiresAt: now.Add(params.Duration),
+		ID:        uuid.New(),
+		UserID:    params.UserID,
+		Route:     route,
+		Reason:    strings.TrimSpace(params.Reason),
+	}
+	if err := s.r.SaveSession(ctx, repository.SaveSessionParams{Session: session}); err != nil {
+		return nil, errors.Join(ErrRecordingTechError, fmt.Errorf("failed to save recording: %w", err))
+	}
+	s.invalidate()
+
+	s.audit.Record(ctx, audit.Event{
+		Type:       audit.EventRecordingStarted,
+		OccurredAt: now,
+		UserID:     session.UserID,
+		Details: map[string]string{
+			"recording_id": session.ID.String(),
+			"route":        session.Route,
+			"reason":       session.Reason,
+			"expires_at":   session.ExpiresAt.UTC().Format(time.RFC3339),
+		},
+	})
+	return newSession(&session, now), nil
+}
+
+// Stop ends the recording before its time window does. Stopping an ended recording changes nothing.
+func (s *Service) Stop(ctx context.Context, id uuid.UUID) (*Session, error) {
+	session, err := s.load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	now := s.now()
+	if !session.ExpiresAt.After(now) {
+		return newSession(session, now), nil
+	}
+
+	session.ExpiresAt = now
+	if err := s.r.SaveSession(ctx, repository.SaveSessionParams{Session: *session}); err != nil {
+		return nil, errors.Join(ErrRecordingTechError, fmt.Errorf("failed to stop recording: %w", err))
+	}
+	s.invalidate()
+
+	s.audit.Record(ctx, audit.Event{
+		Type:       audit.EventRecordingStopped,
+		OccurredAt: now,
+		UserID:     session.UserID,
+		Details:    map[string]string{"recording_id": session.ID.String()},
+	})
+	return newSession(session, now), nil
+}
+
+// Delete removes the recording together with the pairs captured for it.
+func (s *Service) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := s.r.DeleteSession(ctx, id); err != nil {
+		if errors.Is(err, repository.ErrSessionNotFound) {
+			return ErrRecordingNotFound
+		}
+		return errors.Join(ErrRecordingTechError, fmt.Errorf("failed to delete recording: %w", err))
+	}
+	s.invalidate()
+
+	s.audit.Record(ctx, audit.Event{
+		Type:       audit.EventRecordingDeleted,
+		OccurredAt: s.now(),
+		Details:    map[string]string{"recording_id": id.String()},
+	})
+	return nil
+}
+
+// Sessions lists the recordings, most recent first.
+func (s *Service) Sessions(ctx context.Context) ([]*Session, error) {
+	stored, err := s.r.LoadSessions(ctx, repository.LoadSessionsParams{})
+	if err != nil {
+		return nil, errors.Join(ErrRecordingTechError, fmt.Errorf("failed to load recordings: %w", err))
+	}
+	now := s.now()
+	sessions := make([]*Session, 0, len(stored))
+	for _, session := range stored {
+		sessions = append(sessions, newSession(session, now))
+	}
+	return sessions, nil
+}
+
+// Exchanges returns the request/response pairs captured for the recording in the order they were captured.
+func (s *Service) Exchanges(ctx context.Context, id uuid.UUID) ([]*Exchange, error) {
+	if _, err := s.load(ctx, id); err != nil {
+		return nil, err
+	}
+	stored, err := s.r.LoadExchanges(ctx, id)
+	if err != nil {
+		return nil, errors.Join(ErrRecordingTechError, fmt.Errorf("failed to load recorded exchanges: %w", err))
+	}
+	exchanges := make([]*Exchange, 0, len(stored))
+	for _, e := range stored {
+		exchanges = append(exchanges, newExchange(e))
+	}
+	return exchanges, nil
+}
+
+// Wants reports whether a request to the path may be recorded, before it is known who sends it.
+func (s *Service) Wants(ctx context.Context, path string) (bool, error) {
+	if s.settings.MaxWindow <= 0 {
+		return false, nil
+	}
+	active, err := s.activeSessions(ctx)
+	if err != nil {
+		return false, err
+	}
+	now := s.now()
+	for _, session := range active {
+		if isActive(session, now) && strings.HasPrefix(path, session.Route) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Record sanitizes the handled request and its response and stores them for every active recording selecting
+// the user and path of the request.
+func (s *Service) Record(ctx context.Context, params RecordParams) error {
+	if s.settings.MaxWindow <= 0 {
+		return nil
+	}
+	active, err := s.activeSessions(ctx)
+	if err != nil {
+		return err
+	}
+
+	// exchange holds the sanitized pair, built once a recording selects the request.
+	var exchange *repository.Exchange
+	for _, session := range active {
+		if !isActive(session, params.StartedAt) || !strings.HasPrefix(params.Path, session.Route) {
+			continue
+		}
+		if session.UserID != uuid.Nil && session.UserID != params.UserID {
+			continue
+		}
+		if exchange == nil {
+			exchange = sanitize(params)
+		}
+		exchange.ID = uuid.New()
+		if err := s.r.SaveExchange(ctx, repository.SaveExchangeParams{
+			Exchange:  *exchange,
+			SessionID: session.ID,
+		}); err != nil {
+			return errors.Join(ErrRecordingTechError, fmt.Errorf("failed to save recorded exchange: %w", err))
+		}
+	}
+	return nil
+}
+
+// Purge removes the recordings whose time window ended longer than the retention period ago and returns how
+// many were removed.
+func (s *Service) Purge(ctx context.Context) (int64, error) {
+	if s.settings.Retention <= 0 {
+		return 0, nil
+	}
+	n, err := s.r.DeleteExpired(ctx, s.now().Add(-s.settings.Retention))
+	if err != nil {
+		return 0, errors.Join(ErrRecordingTechError, fmt.Errorf("failed to purge recordings: %w", err))
+	}
+	return n, nil
+}
+
+// load retrieves the stored recording session with the ID.
+func (s *Service) load(ctx context.Context, id uuid.UUID) (*repository.Session, error) {
+	sessions, err := s.r.LoadSessions(ctx, repository.LoadSessionsParams{ID: id})
+	if err != nil {
+		return nil, errors.Join(ErrRecordingTechError, fmt.Errorf("failed to load recording: %w", err))
+	}
+	if len(sessions) == 0 {
+		return nil, ErrRecordingNotFound
+	}
+	return sessions[0], nil
+}
+
+// activeSessions returns the active recordings, loading them again once reloadInterval has passed.
+func (s *Service) activeSessions(ctx context.Context) ([]*repository.Session, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if !s.loadedAt.IsZero() && now.Sub(s.loadedAt) < reloadInterval {
+		return s.active, nil
+	}
+	active, err := s.r.LoadSessions(ctx, repository.LoadSessionsParams{ActiveAt: now})
+	if err != nil {
+		return nil, errors.Join(ErrRecordingTechError, fmt.Errorf("failed to load active recordings: %w", err))
+	}
+	s.active, s.loadedAt = active, now
+	return active, nil
+}
+
+// invalidate makes the next request load the active recordings again.
+func (s *Service) invalidate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.loadedAt = time.Time{}
+}
+
+// sanitize builds the stored pair of the handled request with credentials and vault contents redacted.
+func sanitize(params RecordParams) *repository.Exchange {
+	return &repository.Exchange{
+		RecordedAt:      params.StartedAt,
+		RequestHeaders:  sanitizeHeaders(params.RequestHeader),
+		ResponseHeaders: sanitizeHeaders(params.ResponseHeader),
+		UserID:          params.UserID,
+		RequestID:       params.RequestID,
+		Method:          params.Method,
+		Path:            params.Path,
+		Route:           params.Route,
+		Query:           sanitizeQuery(params.Query),
+		RequestBody: sanitizeBody(
+			params.RequestHeader.Get("Content-Type"), params.RequestBody, params.RequestTruncated,
+		),
+		ResponseBody: sanitizeBody(
+			params.ResponseHeader.Get("Content-Type"), params.ResponseBody, params.ResponseTruncated,
+		),
+		Duration: params.Duration,
+		Status:   params.Status,
+	}
+}
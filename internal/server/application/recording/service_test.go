@@ -0,0 +1,519 @@
+package recording
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/audit"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/recording"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// errDatabase is the error of failing repository operations.
+var errDatabase = errors.New("database unavailable")
+
+// testSettings enables the recording mode for tests.
+var testSettings = Settings{MaxWindow: time.Hour, Retention: 7 * 24 * time.Hour, MaxBodySize: 1024}
+
+// mockRepository implements Repository for testing, keeping sessions and pairs in memory.
+type mockRepository struct {
+	saveErr   error
+	loadErr   error
+	deleteErr error
+	exchanges map[uuid.UUID][]repository.Exchange
+	sessions  []repository.Session
+	purged    time.Time
+	loads     int
+}
+
+func (m *mockRepository) SaveSession(_ context.Context, params repository.SaveSessionParams) error {
+	if m.saveErr != nil {
+		return m.saveErr
+	}
+	for i := range m.sessions {
+		if m.sessions[i].ID == params.Session.ID {
+			m.sessions[i].ExpiresAt = params.Session.ExpiresAt
+			return nil
+		}
+	}
+	m.sessions = append(m.sessions, params.Session)
+	return nil
+}
+
+func (m *mockRepository) LoadSessions(
+	_ context.Context,
+	params repository.LoadSessionsParams,
+) ([]*repository.Session, error) {
+	m.loads++
+	if m.loadErr != nil {
+		return nil, m.loadErr
+	}
+	var sessions []*repository.Session
+	for _, s := range m.sessions {
+		if params.ID != uuid.Nil && s.ID != params.ID {
+			continue
+		}
+		if !params.ActiveAt.IsZero() && !isActive(&s, params.ActiveAt) {
+			continue
+		}
+		s.Exchanges = len(m.exchanges[s.ID])
+		sessions = append(sessions, &s)
+	}
+	return sessions, nil
+}
+
+func (m *mockRepository) DeleteSession(_ context.Context, id uuid.UUID) error {
+	if m.deleteErr != nil {
+		return m.deleteErr
+	}
+	for i, s := range m.sessions {
+		if s.ID == id {
+			m.sessions = append(m.sessions[:i], m.sessions[i+1:]...)
+			return nil
+		}
+	}
+	return repository.ErrSessionNotFound
+}
+
+func (m *mockRepository) DeleteExpired(_ context.Context, before time.Time) (int64, error) {
+	if m.deleteErr != nil {
+		return 0, m.deleteErr
+	}
+	m.purged = before
+	return 2, nil
+}
+
+func (m *mockRepository) SaveExchange(_ context.Context, params repository.SaveExchangeParams) error {
+	if m.saveErr != nil {
+		return m.saveErr
+	}
+	if m.exchanges == nil {
+		m.exchanges = make(map[uuid.UUID][]repository.Exchange)
+	}
+	m.exchanges[params.SessionID] = append(m.exchanges[params.SessionID], params.Exchange)
+	return nil
+}
+
+func (m *mockRepository) LoadExchanges(_ context.Context, sessionID uuid.UUID) ([]*repository.Exchange, error) {
+	if m.loadErr != nil {
+		return nil, m.loadErr
+	}
+	exchanges := make([]*repository.Exchange, 0, len(m.exchanges[sessionID]))
+	for _, e := range m.exchanges[sessionID] {
+		exchanges = append(exchanges, &e)
+	}
+	return exchanges, nil
+}
+
+// mockAuditRecorder implements AuditRecorder for testing.
+type mockAuditRecorder struct {
+	events []audit.Event
+}
+
+func (m *mockAuditRecorder) Record(_ context.Context, e audit.Event) {
+	m.events = append(m.events, e)
+}
+
+func TestService_Start(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	userID := uuid.New()
+
+	tests := []struct {
+		saveErr   error
+		wantErr   error
+		name      string
+		params    StartParams
+		wantRoute string
+		settings  Settings
+	}{
+		{
+			name:     "user recorded",
+			settings: testSettings,
+			params:   StartParams{UserID: userID, Reason: " case 42 ", Duration: 30 * time.Minute},
+		},
+		{
+			name:      "route recorded",
+			settings:  testSettings,
+			params:    StartParams{Route: " /api/items ", Duration: time.Hour},
+			wantRoute: "/api/items",
+		},
+		{
+			name:     "recording disabled",
+			params:   StartParams{UserID: userID, Duration: time.Minute},
+			wantErr:  ErrRecordingDisabled,
+			settings: Settings{},
+		},
+		{
+			name:     "no target",
+			settings: testSettings,
+			params:   StartParams{Duration: time.Minute},
+			wantErr:  ErrRecordingTargetRequired,
+		},
+		{
+			name:     "relative route",
+			settings: testSettings,
+			params:   StartParams{Route: "api/items", Duration: time.Minute},
+			wantErr:  ErrRecordingInvalidRoute,
+		},
+		{
+			name:     "no duration",
+			settings: testSettings,
+			params:   StartParams{UserID: userID},
+			wantErr:  ErrRecordingInvalidDuration,
+		},
+		{
+			name:     "window too long",
+			settings: testSettings,
+			params:   StartParams{UserID: userID, Duration: 2 * time.Hour},
+			wantErr:  ErrRecordingInvalidDuration,
+		},
+		{
+			name:     "repository failure",
+			settings: testSettings,
+			params:   StartParams{UserID: userID, Duration: time.Minute},
+			saveErr:  errDatabase,
+			wantErr:  ErrRecordingTechError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &mockRepository{saveErr: tt.saveErr}
+			recorder := &mockAuditRecorder{}
+			service := NewService(repo, recorder, tt.settings)
+			service.now = func() time.Time { return now }
+
+			session, err := service.Start(context.Background(), tt.params)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Empty(t, repo.sessions)
+				assert.Empty(t, recorder.events)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, now, session.StartedAt)
+			assert.Equal(t, now.Add(tt.params.Duration), session.ExpiresAt)
+			assert.Equal(t, tt.params.UserID, session.UserID)
+			assert.Equal(t, tt.wantRoute, session.Route)
+			assert.True(t, session.Active)
+			require.Len(t, repo.sessions, 1)
+			assert.Equal(t, session.ID, repo.sessions[0].ID)
+			require.Len(t, recorder.events, 1)
+			assert.Equal(t, audit.EventRecordingStarted, recorder.events[0].Type)
+			assert.Equal(t, session.ID.String(), recorder.events[0].Details["recording_id"])
+		})
+	}
+}
+
+func TestService_Stop(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	active := repository.Session{StartedAt: now.Add(-time.Minute), ExpiresAt: now.Add(time.Hour), ID: uuid.New()}
+	ended := repository.Session{StartedAt: now.Add(-time.Hour), ExpiresAt: now.Add(-time.Minute), ID: uuid.New()}
+
+	tests := []struct {
+		loadErr       error
+		wantErr       error
+		name          string
+		wantExpiresAt time.Time
+		id            uuid.UUID
+		wantEvents    int
+	}{
+		{name: "active recording stopped", id: active.ID, wantExpiresAt: now, wantEvents: 1},
+		{name: "ended recording unchanged", id: ended.ID, wantExpiresAt: ended.ExpiresAt},
+		{name: "unknown recording", id: uuid.New(), wantErr: ErrRecordingNotFound},
+		{name: "repository failure", id: active.ID, loadErr: errDatabase, wantErr: ErrRecordingTechError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &mockRepository{loadErr: tt.loadErr, sessions: []repository.Session{active, ended}}
+			recorder := &mockAuditRecorder{}
+			service := NewService(repo, recorder, testSettings)
+			service.now = func() time.Time { return now }
+
+			session, err := service.Stop(context.Background(), tt.id)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantExpiresAt, session.ExpiresAt)
+			assert.False(t, session.Active)
+			require.Len(t, recorder.events, tt.wantEvents)
+			if tt.wantEvents > 0 {
+				assert.Equal(t, audit.EventRecordingStopped, recorder.events[0].Type)
+			}
+		})
+	}
+}
+
+func TestService_Delete(t *testing.T) {
+	t.Parallel()
+
+	session := repository.Session{ID: uuid.New()}
+
+	tests := []struct {
+		deleteErr  error
+		wantErr    error
+		name       string
+		id         uuid.UUID
+		wantEvents int
+	}{
+		{name: "deleted", id: session.ID, wantEvents: 1},
+		{name: "unknown recording", id: uuid.New(), wantErr: ErrRecordingNotFound},
+		{name: "repository failure", id: session.ID, deleteErr: errDatabase, wantErr: ErrRecordingTechError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &mockRepository{deleteErr: tt.deleteErr, sessions: []repository.Session{session}}
+			recorder := &mockAuditRecorder{}
+			service := NewService(repo, recorder, testSettings)
+
+			err := service.Delete(context.Background(), tt.id)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+				assert.Empty(t, repo.sessions)
+			}
+			require.Len(t, recorder.events, tt.wantEvents)
+			if tt.wantEvents > 0 {
+				assert.Equal(t, audit.EventRecordingDeleted, recorder.events[0].Type)
+			}
+		})
+	}
+}
+
+func TestService_Record(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	userID := uuid.New()
+	otherID := uuid.New()
+	byUser := repository.Session{StartedAt: now, ExpiresAt: now.Add(time.Hour), ID: uuid.New(), UserID: userID}
+	byRoute := repository.Session{
+		StartedAt: now, ExpiresAt: now.Add(time.Hour), ID: uuid.New(), Route: "/api/items/notes",
+	}
+	ended := repository.Session{StartedAt: now.Add(-2 * time.Hour), ExpiresAt: now.Add(-time.Hour), ID: uuid.New()}
+
+	tests := []struct {
+		name      string
+		path      string
+		wantSaved []uuid.UUID
+		userID    uuid.UUID
+		wantWants bool
+	}{
+		{
+			name:      "user and route selected",
+			path:      "/api/items/notes/1",
+			userID:    userID,
+			wantWants: true,
+			wantSaved: []uuid.UUID{byUser.ID, byRoute.ID},
+		},
+		{
+			name:      "other user on recorded route",
+			path:      "/api/items/notes",
+			userID:    otherID,
+			wantWants: true,
+			wantSaved: []uuid.UUID{byRoute.ID},
+		},
+		{
+			name:      "anonymous request on other route",
+			path:      "/api/auth/login",
+			wantWants: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &mockRepository{sessions: []repository.Session{byUser, byRoute, ended}}
+			service := NewService(repo, &mockAuditRecorder{}, testSettings)
+			service.now = func() time.Time { return now }
+
+			wants, err := service.Wants(context.Background(), tt.path)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantWants, wants)
+
+			err = service.Record(context.Background(), RecordParams{
+				StartedAt:     now,
+				RequestHeader: http.Header{"Authorization": {"Bearer token"}, "Content-Type": {"application/json"}},
+				RequestBody:   []byte(`{"id":"1","text":"secret note"}`),
+				UserID:        tt.userID,
+				Method:        http.MethodPut,
+				Path:          tt.path,
+				Status:        http.StatusOK,
+			})
+			require.NoError(t, err)
+
+			assert.Len(t, repo.exchanges, len(tt.wantSaved))
+			for _, id := range tt.wantSaved {
+				require.Len(t, repo.exchanges[id], 1)
+				e := repo.exchanges[id][0]
+				assert.Equal(t, tt.userID, e.UserID)
+				assert.Equal(t, Redacted, e.RequestHeaders["Authorization"])
+				assert.JSONEq(t, `{"id":"1","text":"[REDACTED]"}`, e.RequestBody)
+			}
+			assert.Equal(t, 1, repo.loads, "active recordings must be loaded once")
+		})
+	}
+}
+
+func TestService_Wants(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	byRoute := repository.Session{StartedAt: now, ExpiresAt: now.Add(time.Minute), ID: uuid.New(), Route: "/api/items"}
+
+	t.Run("disabled recording mode loads nothing", func(t *testing.T) {
+		t.Parallel()
+
+		repo := &mockRepository{sessions: []repository.Session{byRoute}}
+		service := NewService(repo, &mockAuditRecorder{}, Settings{})
+
+		wants, err := service.Wants(context.Background(), "/api/items")
+
+		require.NoError(t, err)
+		assert.False(t, wants)
+		assert.Zero(t, repo.loads)
+	})
+
+	t.Run("active recordings reloaded", func(t *testing.T) {
+		t.Parallel()
+
+		current := now
+		repo := &mockRepository{sessions: []repository.Session{byRoute}}
+		service := NewService(repo, &mockAuditRecorder{}, testSettings)
+		service.now = func() time.Time { return current }
+
+		wants, err := service.Wants(context.Background(), "/api/auth/login")
+		require.NoError(t, err)
+		assert.False(t, wants)
+		wants, err = service.Wants(context.Background(), "/api/items/notes")
+		require.NoError(t, err)
+		assert.True(t, wants)
+		assert.Equal(t, 1, repo.loads)
+
+		current = now.Add(time.Minute)
+		wants, err = service.Wants(context.Background(), "/api/items/notes")
+		require.NoError(t, err)
+		assert.False(t, wants, "ended recordings must stop capturing before the next reload")
+
+		current = now.Add(reloadInterval)
+		_, err = service.Wants(context.Background(), "/api/items/notes")
+		require.NoError(t, err)
+		assert.Equal(t, 2, repo.loads)
+	})
+
+	t.Run("repository failure", func(t *testing.T) {
+		t.Parallel()
+
+		service := NewService(&mockRepository{loadErr: errDatabase}, &mockAuditRecorder{}, testSettings)
+
+		_, err := service.Wants(context.Background(), "/api/items")
+
+		require.ErrorIs(t, err, ErrRecordingTechError)
+	})
+}
+
+func TestService_Exchanges(t *testing.T) {
+	t.Parallel()
+
+	session := repository.Session{ID: uuid.New()}
+	exchange := repository.Exchange{ID: uuid.New(), Method: http.MethodGet, Path: "/api/items", Status: 200}
+	repo := &mockRepository{
+		sessions:  []repository.Session{session},
+		exchanges: map[uuid.UUID][]repository.Exchange{session.ID: {exchange}},
+	}
+	service := NewService(repo, &mockAuditRecorder{}, testSettings)
+
+	exchanges, err := service.Exchanges(context.Background(), session.ID)
+	require.NoError(t, err)
+	require.Len(t, exchanges, 1)
+	assert.Equal(t, exchange.ID, exchanges[0].ID)
+	assert.Equal(t, "/api/items", exchanges[0].Path)
+
+	_, err = service.Exchanges(context.Background(), uuid.New())
+	require.ErrorIs(t, err, ErrRecordingNotFound)
+
+	sessions, err := service.Sessions(context.Background())
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	assert.Equal(t, 1, sessions[0].Exchanges)
+}
+
+func TestService_Purge(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		deleteErr   error
+		wantErr     error
+		name        string
+		settings    Settings
+		wantBefore  time.Time
+		wantN       int64
+		wantPurgeIn time.Duration
+	}{
+		{
+			name:        "expired recordings removed",
+			settings:    testSettings,
+			wantBefore:  now.Add(-testSettings.Retention),
+			wantN:       2,
+			wantPurgeIn: purgeInterval,
+		},
+		{
+			name:     "kept until deleted",
+			settings: Settings{MaxWindow: time.Hour},
+		},
+		{
+			name:        "repository failure",
+			settings:    testSettings,
+			deleteErr:   errDatabase,
+			wantErr:     ErrRecordingTechError,
+			wantPurgeIn: purgeInterval,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &mockRepository{deleteErr: tt.deleteErr}
+			service := NewService(repo, &mockAuditRecorder{}, tt.settings)
+			service.now = func() time.Time { return now }
+
+			n, err := service.Purge(context.Background())
+
+			assert.Equal(t, tt.wantPurgeIn, service.PurgeInterval())
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantN, n)
+			assert.Equal(t, tt.wantBefore, repo.purged)
+		})
+	}
+}
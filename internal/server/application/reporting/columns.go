@@ -0,0 +1,138 @@
+package reporting
+
+import (
+	"slices"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/bankcard"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/credential"
+)
+
+// lastDigitsCount is the number of trailing card number digits of the non-secret card number column.
+const lastDigitsCount = 4
+
+// column describes a report column of items of type T.
+type column[T any] struct {
+	// value renders the column of an item.
+	value func(T) string
+	// name identifies the column in requests and the header row.
+	name string
+	// field names the encrypted item field the column is rendered from; empty for plain fields.
+	field string
+	// secret marks columns revealing secrets, which require the secrets to be included.
+	secret bool
+}
+
+// bankCardColumns lists the columns of bank card reports in default order.
+var bankCardColumns = []column[*bankcard.BankCard]{
+	{name: "id", value: func(c *bankcard.BankCard) string { return c.ID.String() }},
+	{name: "card_holder", field: "card_holder", value: func(c *bankcard.BankCard) string { return c.CardHolder }},
+	{
+		name:  "card_last_digits",
+		field: "card_number",
+		value: func(c *bankcard.BankCard) string { return lastDigits(c.CardNumber) },
+	},
+	{
+		name:   "card_number",
+		field:  "card_number",
+		secret: true,
+		value:  func(c *bankcard.BankCard) string { return c.CardNumber },
+	},
+	{name: "expiry_month", field: "expiry_month", value: func(c *bankcard.BankCard) string { return c.ExpiryMonth }},
+	{name: "expiry_year", field: "expiry_year", value: func(c *bankcard.BankCard) string { return c.ExpiryYear }},
+	{name: "cvv", field: "cvv", secret: true, value: func(c *bankcard.BankCard) string { return c.CVV }},
+	{name: "description", field: "description", value: func(c *bankcard.BankCard) string { return c.Description }},
+	{name: "updated_at", value: func(c *bankcard.BankCard) string { return formatTime(c.UpdatedAt) }},
+}
+
+// credentialColumns lists the columns of credential reports in default order.
+var credentialColumns = []column[*credential.Credential]{
+	{name: "id", value: func(c *credential.Credential) string { return c.ID.String() }},
+	{name: "login", field: "login", value: func(c *credential.Credential) string { return c.Login }},
+	{
+		name:   "password",
+		field:  "password",
+		secret: true,
+		value:  func(c *credential.Credential) string { return c.Password },
+	},
+	{name: "description", field: "description", value: func(c *credential.Credential) string { return c.Description }},
+	{name: "updated_at", value: func(c *credential.Credential) string { return formatTime(c.UpdatedAt) }},
+}
+
+// Columns returns the names of the columns of reports of the type in default order, or nil for unknown types.
+func Columns(t Type) []string {
+	switch t {
+	case TypeBankCards:
+		return columnNames(bankCardColumns)
+	case TypeCredentials:
+		return columnNames(credentialColumns)
+	default:
+		return nil
+	}
+}
+
+// columnNames returns the names of the columns.
+func columnNames[T any](columns []column[T]) []string {
+	names := make([]string, 0, len(columns))
+	for _, c := range columns {
+		names = append(names, c.name)
+	}
+	return names
+}
+
+// selectColumns resolves the requested column names against the columns of a report. No names select every
+// column, leaving out secret ones unless secrets are included.
+func selectColumns[T any](columns []column[T], names []string, includeSecrets bool) ([]column[T], error) {
+	if len(names) == 0 {
+		// selected holds the default columns.
+		var selected []column[T]
+		for _, c := range columns {
+			if !c.secret || includeSecrets {
+				selected = append(selected, c)
+			}
+		}
+		return selected, nil
+	}
+
+	selected := make([]column[T], 0, len(names))
+	for _, name := range names {
+		i := slices.IndexFunc(columns, func(c column[T]) bool { return c.name == name })
+		if i < 0 {
+			return nil, ErrReportColumnUnknown
+		}
+		if columns[i].secret && !includeSecrets {
+			return nil, ErrReportSecretsNotIncluded
+		}
+		selected = append(selected, columns[i])
+	}
+	return selected, nil
+}
+
+// decryptedFields returns the encrypted item fields the columns are rendered from, so the other fields stay
+// sealed. Item services decrypt every field when none is named.
+func decryptedFields[T any](columns []column[T]) []string {
+	// fields holds the distinct encrypted fields.
+	var fields []string
+	for _, c := range columns {
+		if c.field != "" && !slices.Contains(fields, c.field) {
+			fields = append(fields, c.field)
+		}
+	}
+	return fields
+}
+
+// lastDigits returns the last digits of a card number.
+func lastDigits(number string) string {
+	if len(number) <= lastDigitsCount {
+		return number
+	}
+	return number[len(number)-lastDigitsCount:]
+}
+
+// formatTime renders a moment in UTC as RFC 3339, or empty for the zero time.
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,6 @@
+// Package reporting provides CSV report application services for the AegisVaultKeeper server.
+//
+// This package streams the bank cards or credentials of a user as CSV rows with the columns the user selects,
+// for users migrating to another tool or auditing their data. Secret columns, such as passwords, card numbers
+// and CVVs, are only included on explicit request confirmed with the password of the user.
+package reporting
@@ -0,0 +1,28 @@
+package reporting
+
+import "github.com/google/uuid"
+
+// Type identifies the kind of items a report lists.
+type Type string
+
+// Report types.
+const (
+	// TypeBankCards lists bank cards.
+	TypeBankCards Type = "bankcards"
+	// TypeCredentials lists credentials.
+	TypeCredentials Type = "credentials"
+)
+
+// GenerateParams contains the parameters required to generate a report.
+type GenerateParams struct {
+	// Columns selects the report columns in output order; empty selects every column allowed by IncludeSecrets.
+	Columns []string
+	// Password contains the password of the user re-entered to confirm a report including secrets.
+	Password string
+	// Type specifies the kind of items listed.
+	Type Type
+	// UserID identifies the user whose items are listed.
+	UserID uuid.UUID
+	// IncludeSecrets allows secret columns; it requires Password.
+	IncludeSecrets bool
+}
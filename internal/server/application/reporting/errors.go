@@ -0,0 +1,18 @@
+package reporting
+
+import "errors"
+
+// Reporting error definitions.
+var (
+	// ErrReportTypeUnknown indicates a report of an item type that is not reportable.
+	ErrReportTypeUnknown = errors.New("report type is unknown")
+
+	// ErrReportColumnUnknown indicates a column that the report type does not have.
+	ErrReportColumnUnknown = errors.New("report column is unknown")
+
+	// ErrReportSecretsNotIncluded indicates a secret column selected without requesting secrets.
+	ErrReportSecretsNotIncluded = errors.New("report secret columns require secrets to be included")
+
+	// ErrReportPasswordRequired indicates a report including secrets without the confirming password.
+	ErrReportPasswordRequired = errors.New("report including secrets requires the password")
+)
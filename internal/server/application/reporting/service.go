@@ -0,0 +1,176 @@
+package reporting
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/bankcard"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/credential"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/audit"
+	"github.com/google/uuid"
+)
+
+// pageSize is the number of items a report loads and decrypts at a time.
+const pageSize = 500
+
+// BankCardService defines the interface for listing user bank cards.
+type BankCardService interface {
+	// List retrieves the bank cards belonging to the specified user.
+	List(ctx context.Context, params bankcard.ListParams) ([]*bankcard.BankCard, error)
+}
+
+// CredentialService defines the interface for listing user credentials.
+type CredentialService interface {
+	// List retrieves the credentials belonging to the specified user.
+	List(ctx context.Context, params credential.ListParams) ([]*credential.Credential, error)
+}
+
+// PasswordConfirmer defines the interface for the step-up verification of reports including secrets.
+type PasswordConfirmer interface {
+	// ConfirmPassword verifies the password re-entered by the user.
+	ConfirmPassword(ctx context.Context, userID uuid.UUID, password string) error
+}
+
+// AuditRecorder defines the interface for recording security audit events.
+type AuditRecorder interface {
+	// Record stores the audit event.
+	Record(ctx context.Context, e audit.Event)
+}
+
+// Service provides CSV report operations.
+type Service struct {
+	// bankcards lists user bank cards.
+	bankcards BankCardService
+	// credentials lists user credentials.
+	credentials CredentialService
+	// passwords confirms the password of reports including secrets.
+	passwords PasswordConfirmer
+	// audit records generated reports.
+	audit AuditRecorder
+}
+
+// NewService creates a new reporting service instance with the provided dependencies.
+func NewService(
+	bankcards BankCardService,
+	credentials CredentialService,
+	passwords PasswordConfirmer,
+	audit AuditRecorder,
+) *Service {
+	return &Service{
+		bankcards:   bankcards,
+		credentials: credentials,
+		passwords:   passwords,
+		audit:       audit,
+	}
+}
+
+// Generate passes the header row and then one row per item of the report to emit, items ordered by ID.
+// Items are loaded a page at a time and the next page is loaded only after emit has returned for every row of
+// the previous one, so at most one page is held in memory. Reports including secrets require the password of
+// the user. An error returned by emit stops the report.
+func (s *Service) Generate(ctx context.Context, params GenerateParams, emit func(row []string) error) error {
+	switch params.Type {
+	case TypeBankCards:
+		return generate(ctx, s, params, bankCardColumns, s.listBankCards,
+			func(c *bankcard.BankCard) uuid.UUID { return c.ID }, emit)
+	case TypeCredentials:
+		return generate(ctx, s, params, credentialColumns, s.listCredentials,
+			func(c *credential.Credential) uuid.UUID { return c.ID }, emit)
+	default:
+		return fmt.Errorf("failed to generate %q report: %w", params.Type, ErrReportTypeUnknown)
+	}
+}
+
+// generate resolves the columns of the report, confirms the password when secrets are included and emits
+// the rows of the items load returns page by page; id returns the item ID the next page starts after.
+func generate[T any](
+	ctx context.Context,
+	s *Service,
+	params GenerateParams,
+	columns []column[T],
+	load func(ctx context.Context, params listParams) ([]T, error),
+	id func(T) uuid.UUID,
+	emit func(row []string) error,
+) error {
+	selected, err := selectColumns(columns, params.Columns, params.IncludeSecrets)
+	if err != nil {
+		return fmt.Errorf("failed to select report columns: %w", err)
+	}
+	if params.IncludeSecrets {
+		if params.Password == "" {
+			return fmt.Errorf("failed to confirm password: %w", ErrReportPasswordRequired)
+		}
+		if err := s.passwords.ConfirmPassword(ctx, params.UserID, params.Password); err != nil {
+			return fmt.Errorf("failed to confirm password: %w", err)
+		}
+	}
+
+	header := columnNames(selected)
+	s.audit.Record(ctx, audit.Event{
+		Type:   audit.EventReportGenerated,
+		UserID: params.UserID,
+		Details: map[string]string{
+			"type":            string(params.Type),
+			"columns":         strings.Join(header, ","),
+			"include_secrets": strconv.FormatBool(params.IncludeSecrets),
+		},
+	})
+	if err := emit(header); err != nil {
+		return err
+	}
+
+	fields := decryptedFields(selected)
+	// after holds the ID of the last emitted item.
+	var after uuid.UUID
+	for {
+		page, err := load(ctx, listParams{After: after, Fields: fields, UserID: params.UserID})
+		if err != nil {
+			return fmt.Errorf("failed to list %s: %w", params.Type, err)
+		}
+		for _, item := range page {
+			row := make([]string, 0, len(selected))
+			for _, c := range selected {
+				row = append(row, c.value(item))
+			}
+			if err := emit(row); err != nil {
+				return err
+			}
+		}
+		if len(page) < pageSize {
+			return nil
+		}
+		after = id(page[len(page)-1])
+	}
+}
+
+// listParams contains the parameters of loading a page of report items.
+type listParams struct {
+	// Fields names the encrypted fields to decrypt.
+	Fields []string
+	// After is the ID the page starts after.
+	After uuid.UUID
+	// UserID identifies the owner of the items.
+	UserID uuid.UUID
+}
+
+// listBankCards loads a page of bank cards.
+func (s *Service) listBankCards(ctx context.Context, params listParams) ([]*bankcard.BankCard, error) {
+	return s.bankcards.List(ctx, bankcard.ListParams{
+		After:  params.After,
+		Fields: params.Fields,
+		Limit:  pageSize,
+		UserID: params.UserID,
+	})
+}
+
+// listCredentials loads a page of credentials.
+func (s *Service) listCredentials(ctx context.Context, params listParams) ([]*credential.Credential, error) {
+	return s.credentials.List(ctx, credential.ListParams{
+		After:  params.After,
+		Fields: params.Fields,
+		Limit:  pageSize,
+		UserID: params.UserID,
+	})
+}
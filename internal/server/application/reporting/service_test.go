@@ -0,0 +1,287 @@
+package reporting
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/bankcard"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/credential"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/audit"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// errPasswordMismatch is the error of mockPasswordConfirmer for wrong passwords.
+var errPasswordMismatch = errors.New("password mismatch")
+
+// errDatabase is the error of failing item listings.
+var errDatabase = errors.New("database unavailable")
+
+// mockBankCardService implements BankCardService for testing.
+type mockBankCardService struct {
+	err   error
+	items []*bankcard.BankCard
+	calls []bankcard.ListParams
+}
+
+func (m *mockBankCardService) List(_ context.Context, params bankcard.ListParams) ([]*bankcard.BankCard, error) {
+	m.calls = append(m.calls, params)
+	return m.items, m.err
+}
+
+// mockCredentialService implements CredentialService for testing, serving its items page by page.
+type mockCredentialService struct {
+	err   error
+	items []*credential.Credential
+	calls []credential.ListParams
+}
+
+func (m *mockCredentialService) List(
+	_ context.Context,
+	params credential.ListParams,
+) ([]*credential.Credential, error) {
+	m.calls = append(m.calls, params)
+	if m.err != nil {
+		return nil, m.err
+	}
+	// page holds the items sorting after the requested ID.
+	var page []*credential.Credential
+	for _, c := range m.items {
+		if c.ID.String() > params.After.String() && len(page) < params.Limit {
+			page = append(page, c)
+		}
+	}
+	return page, nil
+}
+
+// mockPasswordConfirmer implements PasswordConfirmer for testing.
+type mockPasswordConfirmer struct {
+	password string
+}
+
+func (m *mockPasswordConfirmer) ConfirmPassword(_ context.Context, _ uuid.UUID, password string) error {
+	if password != m.password {
+		return errPasswordMismatch
+	}
+	return nil
+}
+
+// mockAuditRecorder implements AuditRecorder for testing.
+type mockAuditRecorder struct {
+	events []audit.Event
+}
+
+func (m *mockAuditRecorder) Record(_ context.Context, e audit.Event) {
+	m.events = append(m.events, e)
+}
+
+func TestService_Generate(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	cardID := uuid.MustParse("00000000-0000-0000-0000-000000000001")
+	credID := uuid.MustParse("00000000-0000-0000-0000-000000000002")
+	updatedAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.FixedZone("CEST", 2*60*60))
+	card := &bankcard.BankCard{
+		ID:          cardID,
+		CardNumber:  "4111111111111111",
+		CardHolder:  "ALICE DOE",
+		ExpiryMonth: "12",
+		ExpiryYear:  "2030",
+		CVV:         "123",
+		UpdatedAt:   updatedAt,
+	}
+	cred := &credential.Credential{
+		ID:          credID,
+		Login:       "alice",
+		Password:    "s3cret",
+		Description: "=HYPERLINK()",
+		UpdatedAt:   updatedAt,
+	}
+
+	tests := []struct {
+		listErr    error
+		wantErr    error
+		name       string
+		password   string
+		reportType Type
+		columns    []string
+		wantFields []string
+		wantRows   [][]string
+		secrets    bool
+	}{
+		{
+			name:       "default credential columns",
+			reportType: TypeCredentials,
+			wantFields: []string{"login", "description"},
+			wantRows: [][]string{
+				{"id", "login", "description", "updated_at"},
+				{credID.String(), "alice", "=HYPERLINK()", "2026-10-01T10:00:00Z"},
+			},
+		},
+		{
+			name:       "default credential columns with secrets",
+			reportType: TypeCredentials,
+			secrets:    true,
+			password:   "correct_password",
+			wantFields: []string{"login", "password", "description"},
+			wantRows: [][]string{
+				{"id", "login", "password", "description", "updated_at"},
+				{credID.String(), "alice", "s3cret", "=HYPERLINK()", "2026-10-01T10:00:00Z"},
+			},
+		},
+		{
+			name:       "selected bank card columns",
+			reportType: TypeBankCards,
+			columns:    []string{"card_last_digits", "card_holder", "id"},
+			wantFields: []string{"card_number", "card_holder"},
+			wantRows: [][]string{
+				{"card_last_digits", "card_holder", "id"},
+				{"1111", "ALICE DOE", cardID.String()},
+			},
+		},
+		{
+			name:       "selected secret bank card columns",
+			reportType: TypeBankCards,
+			columns:    []string{"card_number", "cvv"},
+			secrets:    true,
+			password:   "correct_password",
+			wantFields: []string{"card_number", "cvv"},
+			wantRows:   [][]string{{"card_number", "cvv"}, {"4111111111111111", "123"}},
+		},
+		{
+			name:       "secret column without secrets",
+			reportType: TypeCredentials,
+			columns:    []string{"login", "password"},
+			wantErr:    ErrReportSecretsNotIncluded,
+		},
+		{
+			name:       "unknown column",
+			reportType: TypeBankCards,
+			columns:    []string{"pin"},
+			wantErr:    ErrReportColumnUnknown,
+		},
+		{
+			name:       "unknown type",
+			reportType: "notes",
+			wantErr:    ErrReportTypeUnknown,
+		},
+		{
+			name:       "secrets without password",
+			reportType: TypeCredentials,
+			secrets:    true,
+			wantErr:    ErrReportPasswordRequired,
+		},
+		{
+			name:       "secrets with wrong password",
+			reportType: TypeCredentials,
+			secrets:    true,
+			password:   "wrong_password",
+			wantErr:    errPasswordMismatch,
+		},
+		{
+			name:       "list failure",
+			reportType: TypeBankCards,
+			listErr:    errDatabase,
+			wantErr:    errDatabase,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cards := &mockBankCardService{items: []*bankcard.BankCard{card}, err: tt.listErr}
+			creds := &mockCredentialService{items: []*credential.Credential{cred}, err: tt.listErr}
+			recorder := &mockAuditRecorder{}
+			service := NewService(cards, creds, &mockPasswordConfirmer{password: "correct_password"}, recorder)
+
+			// rows collects the emitted rows.
+			var rows [][]string
+			err := service.Generate(context.Background(), GenerateParams{
+				Columns:        tt.columns,
+				Password:       tt.password,
+				Type:           tt.reportType,
+				UserID:         userID,
+				IncludeSecrets: tt.secrets,
+			}, func(row []string) error {
+				rows = append(rows, row)
+				return nil
+			})
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				if tt.listErr == nil {
+					assert.Empty(t, rows)
+					assert.Empty(t, recorder.events)
+				}
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantRows, rows)
+
+			// fields holds the encrypted fields requested by the report.
+			var fields []string
+			if tt.reportType == TypeBankCards {
+				require.Len(t, cards.calls, 1)
+				fields = cards.calls[0].Fields
+				assert.Equal(t, pageSize, cards.calls[0].Limit)
+			} else {
+				require.Len(t, creds.calls, 1)
+				fields = creds.calls[0].Fields
+			}
+			assert.Equal(t, tt.wantFields, fields)
+
+			require.Len(t, recorder.events, 1)
+			assert.Equal(t, audit.EventReportGenerated, recorder.events[0].Type)
+			assert.Equal(t, userID, recorder.events[0].UserID)
+			assert.Equal(t, string(tt.reportType), recorder.events[0].Details["type"])
+		})
+	}
+}
+
+func TestService_GeneratePages(t *testing.T) {
+	t.Parallel()
+
+	creds := &mockCredentialService{}
+	for range pageSize + 1 {
+		creds.items = append(creds.items, &credential.Credential{ID: uuid.New(), Login: "user"})
+	}
+	slices.SortFunc(creds.items, func(a, b *credential.Credential) int {
+		return strings.Compare(a.ID.String(), b.ID.String())
+	})
+	service := NewService(&mockBankCardService{}, creds, &mockPasswordConfirmer{}, &mockAuditRecorder{})
+
+	// count holds the number of emitted item rows.
+	var count int
+	err := service.Generate(context.Background(), GenerateParams{Type: TypeCredentials, Columns: []string{"id"}},
+		func(row []string) error {
+			if row[0] != "id" {
+				count++
+			}
+			return nil
+		})
+
+	require.NoError(t, err)
+	assert.Equal(t, pageSize+1, count)
+	require.Len(t, creds.calls, 2)
+	assert.Equal(t, creds.items[pageSize-1].ID, creds.calls[1].After)
+
+	stop := errors.New("client gone")
+	err = service.Generate(context.Background(), GenerateParams{Type: TypeCredentials},
+		func([]string) error { return stop })
+	require.ErrorIs(t, err, stop)
+}
+
+func TestColumns(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, []string{"id", "login", "password", "description", "updated_at"}, Columns(TypeCredentials))
+	assert.Contains(t, Columns(TypeBankCards), "cvv")
+	assert.Nil(t, Columns("notes"))
+}
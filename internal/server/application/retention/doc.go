@@ -0,0 +1,6 @@
+// Package retention provides data retention application services for the AegisVaultKeeper server.
+//
+// This package implements management of the retention overrides of directory groups, resolution of the
+// policy of every user from the overrides of their groups and the configured defaults, and the preview and
+// enforcement of those policies on replaced item versions and item reveal records.
+package retention
@@ -0,0 +1,100 @@
+package retention
+
+import (
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/retention"
+	"github.com/google/uuid"
+)
+
+// Policy represents the data retention rules; a zero rule keeps the data forever.
+type Policy struct {
+	// KeepVersions specifies how many replaced versions of every item are kept.
+	KeepVersions int
+	// KeepAuditDays specifies for how many days item reveal records are kept.
+	KeepAuditDays int
+}
+
+// Override represents the retention policy of a directory group data transfer object for application layer
+// communication.
+type Override struct {
+	// UpdatedAt indicates when the override was last set.
+	UpdatedAt time.Time
+	// Policy contains the rules applied to the members of the group.
+	Policy
+	// GroupID identifies the group the override applies to.
+	GroupID uuid.UUID
+}
+
+// Policies represents the configured default retention policy with the overrides of directory groups.
+type Policies struct {
+	// Overrides lists the overrides of directory groups ordered by group ID.
+	Overrides []*Override
+	// Defaults contains the policy applied to users in no group with an override.
+	Defaults Policy
+}
+
+// Scope represents the data of the users sharing one effective retention policy that the policy does not keep.
+type Scope struct {
+	// Policy contains the effective policy of the users.
+	Policy
+	// Users specifies the number of users with overrides the policy applies to, those under legal hold left out;
+	// zero for the defaults.
+	Users int
+	// Versions specifies the number of replaced item versions the policy does not keep.
+	Versions int64
+	// AuditRecords specifies the number of item reveal records the policy does not keep.
+	AuditRecords int64
+	// Default reports whether the scope covers the users without overrides.
+	Default bool
+}
+
+// Report represents the data the retention policies purged, or would purge when previewed.
+type Report struct {
+	// Scopes lists the data of every effective policy, the defaults first.
+	Scopes []*Scope
+	// Versions specifies the total number of replaced item versions.
+	Versions int64
+	// AuditRecords specifies the total number of item reveal records.
+	AuditRecords int64
+}
+
+// newPolicyFromDomain converts a domain retention policy to application DTO.
+func newPolicyFromDomain(p retention.Policy) Policy {
+	return Policy{KeepVersions: p.KeepVersions, KeepAuditDays: p.KeepAuditDays}
+}
+
+// newOverrideFromDomain converts a domain retention override entity to application DTO.
+func newOverrideFromDomain(o *retention.Override) *Override {
+	if o == nil {
+		return nil
+	}
+	return &Override{
+		GroupID:   o.GroupID,
+		Policy:    newPolicyFromDomain(o.Policy),
+		UpdatedAt: o.UpdatedAt,
+	}
+}
+
+// newOverridesFromDomain converts a slice of domain retention override entities to application DTOs.
+func newOverridesFromDomain(os []*retention.Override) []*Override {
+	result := make([]*Override, 0, len(os))
+	for _, o := range os {
+		result = append(result, newOverrideFromDomain(o))
+	}
+	return result
+}
+
+// SetOverrideParams contains parameters for setting the retention policy of a directory group.
+type SetOverrideParams struct {
+	// Policy contains the rules applied to the members of the group.
+	Policy
+	// GroupID identifies the group.
+	GroupID uuid.UUID
+}
+
+// DeleteOverrideParams contains parameters for removing the retention policy of a directory group.
+type DeleteOverrideParams struct {
+	// GroupID identifies the group.
+	GroupID uuid.UUID
+}
@@ -0,0 +1,62 @@
+package retention
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/errutil"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/retention"
+	repositoryGroup "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/group"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/retention"
+)
+
+// Retention error definitions.
+var (
+	// ErrRetentionAppError indicates a general retention application error.
+	ErrRetentionAppError = errors.New("retention application error")
+
+	// ErrRetentionTechError indicates a technical error in the retention system.
+	ErrRetentionTechError = errors.New("retention technical error")
+
+	// ErrIncorrectKeepVersions indicates the number of kept item versions is out of range.
+	ErrIncorrectKeepVersions = errors.New("incorrect number of kept item versions")
+
+	// ErrIncorrectKeepAuditDays indicates the number of days reveal records are kept is out of range.
+	ErrIncorrectKeepAuditDays = errors.New("incorrect number of days reveal records are kept")
+
+	// ErrGroupNotFound indicates the group of the override does not exist.
+	ErrGroupNotFound = errors.New("group not found")
+
+	// ErrOverrideNotFound indicates the group has no retention override.
+	ErrOverrideNotFound = errors.New("retention override not found")
+)
+
+// mapError maps domain and repository errors to application-level errors.
+func mapError(err error) error {
+	if err == nil {
+		return nil
+	}
+	mapped := errutil.MapError(mapFn, err)
+	if mapped != nil {
+		return fmt.Errorf("retention error mapping failed: %w", mapped)
+	}
+	return nil
+}
+
+// mapFn provides the actual error mapping logic for different error types.
+func mapFn(err error) error {
+	switch {
+	case errors.Is(err, retention.ErrNewOverrideParamsValidation):
+		return ErrRetentionAppError
+	case errors.Is(err, retention.ErrIncorrectKeepVersions):
+		return ErrIncorrectKeepVersions
+	case errors.Is(err, retention.ErrIncorrectKeepAuditDays):
+		return ErrIncorrectKeepAuditDays
+	case errors.Is(err, retention.ErrIncorrectGroupID), errors.Is(err, repositoryGroup.ErrGroupNotFound):
+		return ErrGroupNotFound
+	case errors.Is(err, repository.ErrOverrideNotFound):
+		return ErrOverrideNotFound
+	default:
+		return errors.Join(ErrRetentionTechError, err)
+	}
+}
@@ -0,0 +1,275 @@
+package retention
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	legalholdApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/legalhold"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/audit"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/group"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/retention"
+	repositoryGroup "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/group"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/retention"
+	"github.com/google/uuid"
+)
+
+// Repository defines the interface for retention override persistence and retention enforcement operations.
+type Repository interface {
+	// Save creates or replaces the retention override of a group.
+	Save(ctx context.Context, params repository.SaveParams) error
+	// Load retrieves every retention override.
+	Load(ctx context.Context) ([]*retention.Override, error)
+	// Delete removes the retention override of a group.
+	Delete(ctx context.Context, params repository.DeleteParams) error
+	// Count returns the amount of data in the scope the retention rules do not keep.
+	Count(ctx context.Context, params repository.ScopeParams) (retention.Counts, error)
+	// Purge deletes the data in the scope the retention rules do not keep and returns the amount deleted.
+	Purge(ctx context.Context, params repository.ScopeParams) (retention.Counts, error)
+}
+
+// GroupRepository defines the interface for loading directory groups with their members.
+type GroupRepository interface {
+	// Load retrieves groups with their members and the number of matching groups.
+	Load(ctx context.Context, params repositoryGroup.LoadParams) ([]*group.Group, int, error)
+}
+
+// HoldResolver defines the interface for resolving the users and items under legal hold.
+type HoldResolver interface {
+	// Held returns the users and items whose data must not be permanently deleted.
+	Held(ctx context.Context) (*legalholdApp.Held, error)
+}
+
+// AuditRecorder defines the interface for recording security audit events.
+type AuditRecorder interface {
+	// Record stores the audit event.
+	Record(ctx context.Context, e audit.Event)
+}
+
+// Service provides data retention operations.
+type Service struct {
+	// r is the repository interface for retention persistence operations.
+	r Repository
+	// groups loads the members of the groups with overrides.
+	groups GroupRepository
+	// holds resolves the users and items under legal hold whose data is kept regardless of the policies.
+	holds HoldResolver
+	// audit records override changes and purges.
+	audit AuditRecorder
+	// defaults holds the policy of users in no group with an override.
+	defaults retention.Policy
+}
+
+// NewService creates a new retention service instance applying the default policy to users in no group with
+// an override.
+func NewService(
+	r Repository,
+	groups GroupRepository,
+	holds HoldResolver,
+	audit AuditRecorder,
+	defaults Policy,
+) *Service {
+	return &Service{
+		r:        r,
+		groups:   groups,
+		holds:    holds,
+		audit:    audit,
+		defaults: retention.Policy{KeepVersions: defaults.KeepVersions, KeepAuditDays: defaults.KeepAuditDays},
+	}
+}
+
+// Policies retrieves the default retention policy with the overrides of directory groups.
+func (s *Service) Policies(ctx context.Context) (*Policies, error) {
+	overrides, err := s.r.Load(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load retention overrides: %w", mapError(err))
+	}
+	return &Policies{Defaults: newPolicyFromDomain(s.defaults), Overrides: newOverridesFromDomain(overrides)}, nil
+}
+
+// SetOverride applies a retention policy to the members of an existing directory group instead of the defaults,
+// replacing the previous override of the group.
+func (s *Service) SetOverride(ctx context.Context, params SetOverrideParams) (*Override, error) {
+	o, err := retention.NewOverride(retention.NewOverrideParams{
+		GroupID: params.GroupID,
+		Policy:  retention.Policy{KeepVersions: params.KeepVersions, KeepAuditDays: params.KeepAuditDays},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create new retention override: %w", mapError(err))
+	}
+	if _, _, err := s.groups.Load(ctx, repositoryGroup.LoadParams{ID: o.GroupID}); err != nil {
+		return nil, fmt.Errorf("failed to load group %s: %w", o.GroupID, mapError(err))
+	}
+
+	if err := s.r.Save(ctx, repository.SaveParams{Entity: o}); err != nil {
+		return nil, fmt.Errorf("failed to save retention override: %w", mapError(err))
+	}
+
+	s.audit.Record(ctx, audit.Event{
+		Type: audit.EventRetentionOverrideSet,
+		Details: map[string]string{
+			"group_id":        o.GroupID.String(),
+			"keep_versions":   strconv.Itoa(o.KeepVersions),
+			"keep_audit_days": strconv.Itoa(o.KeepAuditDays),
+		},
+	})
+	return newOverrideFromDomain(o), nil
+}
+
+// DeleteOverride removes the retention policy of a directory group so the defaults apply to its members again.
+func (s *Service) DeleteOverride(ctx context.Context, params DeleteOverrideParams) error {
+	if err := s.r.Delete(ctx, repository.DeleteParams{GroupID: params.GroupID}); err != nil {
+		return fmt.Errorf("failed to delete retention override: %w", mapError(err))
+	}
+
+	s.audit.Record(ctx, audit.Event{
+		Type:    audit.EventRetentionOverrideDeleted,
+		Details: map[string]string{"group_id": params.GroupID.String()},
+	})
+	return nil
+}
+
+// Preview reports the data the retention policies would purge now without deleting anything.
+func (s *Service) Preview(ctx context.Context) (*Report, error) {
+	return s.apply(ctx, s.r.Count)
+}
+
+// Enforce purges the replaced item versions and item reveal records the retention policies do not keep.
+// Users in several groups with overrides keep the data any of their policies keeps. The data of users and items
+// under legal hold is kept regardless of the policies.
+func (s *Service) Enforce(ctx context.Context) (*Report, error) {
+	report, err := s.apply(ctx, s.r.Purge)
+	if report != nil && (report.Versions > 0 || report.AuditRecords > 0) {
+		s.audit.Record(ctx, audit.Event{
+			Type: audit.EventRetentionEnforced,
+			Details: map[string]string{
+				"versions":      strconv.FormatInt(report.Versions, 10),
+				"audit_records": strconv.FormatInt(report.AuditRecords, 10),
+			},
+		})
+	}
+	return report, err
+}
+
+// scope holds the users sharing one effective retention policy.
+type scope struct {
+	// users lists the users with overrides; nil for the defaults.
+	users []uuid.UUID
+	// policy contains the effective policy of the users.
+	policy retention.Policy
+}
+
+// apply resolves the effective policy of every user and runs fn over the data of every policy keeping less than
+// everything, leaving out the users and items under legal hold. On failure it returns the data handled so far
+// with the error.
+func (s *Service) apply(
+	ctx context.Context,
+	fn func(ctx context.Context, params repository.ScopeParams) (retention.Counts, error),
+) (*Report, error) {
+	scopes, overridden, err := s.resolve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	held, err := s.holds.Held(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve legal holds: %w", errors.Join(ErrRetentionTechError, err))
+	}
+	excluded := overridden
+	if len(held.UserIDs) > 0 {
+		excluded = slices.Compact(slices.SortedFunc(slices.Values(slices.Concat(overridden, held.UserIDs)), compareIDs))
+	}
+
+	now := time.Now()
+	report := &Report{Scopes: make([]*Scope, 0, len(scopes))}
+	for _, sc := range scopes {
+		users := sc.users
+		if users != nil {
+			users = slices.DeleteFunc(users, func(id uuid.UUID) bool { return slices.Contains(held.UserIDs, id) })
+		}
+		result := &Scope{Policy: newPolicyFromDomain(sc.policy), Users: len(users), Default: sc.users == nil}
+		report.Scopes = append(report.Scopes, result)
+		if sc.policy.IsZero() || (users != nil && len(users) == 0) {
+			continue
+		}
+
+		params := repository.ScopeParams{
+			KeepVersions:   sc.policy.KeepVersions,
+			AuditBefore:    sc.policy.AuditCutoff(now),
+			UserIDs:        users,
+			ExcludeItemIDs: held.ItemIDs,
+		}
+		if users == nil {
+			params.ExcludeUserIDs = excluded
+		}
+		counts, err := fn(ctx, params)
+		result.Versions, result.AuditRecords = counts.Versions, counts.AuditRecords
+		report.Versions += counts.Versions
+		report.AuditRecords += counts.AuditRecords
+		if err != nil {
+			return report, fmt.Errorf("failed to apply retention policy: %w", mapError(err))
+		}
+	}
+	return report, nil
+}
+
+// resolve groups the members of the groups with overrides by their merged policy and returns the scopes, the
+// defaults first, with the users having overrides. Overrides of groups removed from the directory are ignored.
+func (s *Service) resolve(ctx context.Context) ([]*scope, []uuid.UUID, error) {
+	overrides, err := s.r.Load(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load retention overrides: %w", mapError(err))
+	}
+
+	// policies maps the members of the groups with overrides to their merged policy.
+	policies := make(map[uuid.UUID]retention.Policy)
+	for _, o := range overrides {
+		groups, _, err := s.groups.Load(ctx, repositoryGroup.LoadParams{ID: o.GroupID})
+		if errors.Is(err, repositoryGroup.ErrGroupNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to load group %s: %w", o.GroupID, mapError(err))
+		}
+		for _, g := range groups {
+			for _, userID := range g.Members {
+				if p, ok := policies[userID]; ok {
+					policies[userID] = p.Merge(o.Policy)
+				} else {
+					policies[userID] = o.Policy
+				}
+			}
+		}
+	}
+
+	// users maps every effective policy to the users it applies to.
+	users := make(map[retention.Policy][]uuid.UUID)
+	for userID, p := range policies {
+		users[p] = append(users[p], userID)
+	}
+	scopes := []*scope{{policy: s.defaults}}
+	for _, p := range slices.SortedFunc(maps.Keys(users), comparePolicies) {
+		slices.SortFunc(users[p], compareIDs)
+		scopes = append(scopes, &scope{policy: p, users: users[p]})
+	}
+	var overridden []uuid.UUID
+	if len(policies) > 0 {
+		overridden = slices.SortedFunc(maps.Keys(policies), compareIDs)
+	}
+	return scopes, overridden, nil
+}
+
+// comparePolicies orders policies by the number of kept versions, then by the days reveal records are kept.
+func comparePolicies(a, b retention.Policy) int {
+	return cmp.Or(cmp.Compare(a.KeepVersions, b.KeepVersions), cmp.Compare(a.KeepAuditDays, b.KeepAuditDays))
+}
+
+// compareIDs orders identifiers by their string form.
+func compareIDs(a, b uuid.UUID) int {
+	return strings.Compare(a.String(), b.String())
+}
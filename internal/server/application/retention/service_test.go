@@ -0,0 +1,349 @@
+package retention
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	legalholdApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/legalhold"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/audit"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/group"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/retention"
+	repositoryGroup "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/group"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/retention"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockRepository implements Repository for testing.
+type mockRepository struct {
+	loadErr      error
+	saveErr      error
+	deleteErr    error
+	purgeErr     error
+	saved        *retention.Override
+	overrides    []*retention.Override
+	counted      []repository.ScopeParams
+	purged       []repository.ScopeParams
+	deleteParams repository.DeleteParams
+}
+
+func (m *mockRepository) Save(_ context.Context, params repository.SaveParams) error {
+	m.saved = params.Entity
+	return m.saveErr
+}
+
+func (m *mockRepository) Load(context.Context) ([]*retention.Override, error) {
+	return m.overrides, m.loadErr
+}
+
+func (m *mockRepository) Delete(_ context.Context, params repository.DeleteParams) error {
+	m.deleteParams = params
+	return m.deleteErr
+}
+
+func (m *mockRepository) Count(_ context.Context, params repository.ScopeParams) (retention.Counts, error) {
+	m.counted = append(m.counted, params)
+	return retention.Counts{Versions: 2, AuditRecords: 1}, nil
+}
+
+func (m *mockRepository) Purge(_ context.Context, params repository.ScopeParams) (retention.Counts, error) {
+	m.purged = append(m.purged, params)
+	if m.purgeErr != nil {
+		return retention.Counts{}, m.purgeErr
+	}
+	return retention.Counts{Versions: 2, AuditRecords: 1}, nil
+}
+
+// mockGroupRepository implements GroupRepository for testing.
+type mockGroupRepository struct {
+	err    error
+	groups map[uuid.UUID]*group.Group
+}
+
+func (m *mockGroupRepository) Load(
+	_ context.Context,
+	params repositoryGroup.LoadParams,
+) ([]*group.Group, int, error) {
+	if m.err != nil {
+		return nil, 0, m.err
+	}
+	g, ok := m.groups[params.ID]
+	if !ok {
+		return nil, 0, repositoryGroup.ErrGroupNotFound
+	}
+	return []*group.Group{g}, 1, nil
+}
+
+// mockHoldResolver implements HoldResolver for testing.
+type mockHoldResolver struct {
+	err  error
+	held legalholdApp.Held
+}
+
+func (m *mockHoldResolver) Held(context.Context) (*legalholdApp.Held, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &m.held, nil
+}
+
+// mockAuditRecorder implements AuditRecorder for testing.
+type mockAuditRecorder struct {
+	events []audit.Event
+}
+
+func (m *mockAuditRecorder) Record(_ context.Context, e audit.Event) {
+	m.events = append(m.events, e)
+}
+
+func TestService_SetOverride(t *testing.T) {
+	t.Parallel()
+
+	groupID := uuid.New()
+	groups := &mockGroupRepository{groups: map[uuid.UUID]*group.Group{groupID: {ID: groupID}}}
+
+	tests := []struct {
+		groupErr  error
+		saveErr   error
+		wantErr   error
+		name      string
+		params    SetOverrideParams
+		wantSaved bool
+	}{
+		{
+			name:      "set",
+			params:    SetOverrideParams{GroupID: groupID, Policy: Policy{KeepVersions: 10, KeepAuditDays: 365}},
+			wantSaved: true,
+		},
+		{
+			name:    "unknown group",
+			params:  SetOverrideParams{GroupID: uuid.New(), Policy: Policy{KeepVersions: 10}},
+			wantErr: ErrGroupNotFound,
+		},
+		{
+			name:    "negative versions",
+			params:  SetOverrideParams{GroupID: groupID, Policy: Policy{KeepVersions: -1}},
+			wantErr: ErrIncorrectKeepVersions,
+		},
+		{
+			name:    "too many audit days",
+			params:  SetOverrideParams{GroupID: groupID, Policy: Policy{KeepAuditDays: 1_000_000}},
+			wantErr: ErrIncorrectKeepAuditDays,
+		},
+		{
+			name:     "group lookup error",
+			params:   SetOverrideParams{GroupID: groupID},
+			groupErr: errors.New("connection refused"),
+			wantErr:  ErrRetentionTechError,
+		},
+		{
+			name:    "save error",
+			params:  SetOverrideParams{GroupID: groupID},
+			saveErr: errors.New("connection refused"),
+			wantErr: ErrRetentionTechError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &mockRepository{saveErr: tt.saveErr}
+			recorder := &mockAuditRecorder{}
+			g := groups
+			if tt.groupErr != nil {
+				g = &mockGroupRepository{err: tt.groupErr}
+			}
+			s := NewService(repo, g, &mockHoldResolver{}, recorder, Policy{})
+
+			got, err := s.SetOverride(context.Background(), tt.params)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Empty(t, recorder.events)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.params.Policy, got.Policy)
+			assert.Equal(t, tt.params.GroupID, repo.saved.GroupID)
+			require.Len(t, recorder.events, 1)
+			assert.Equal(t, audit.EventRetentionOverrideSet, recorder.events[0].Type)
+			assert.Equal(t, "365", recorder.events[0].Details["keep_audit_days"])
+		})
+	}
+}
+
+func TestService_DeleteOverride(t *testing.T) {
+	t.Parallel()
+
+	groupID := uuid.New()
+
+	tests := []struct {
+		deleteErr error
+		wantErr   error
+		name      string
+	}{
+		{name: "deleted"},
+		{name: "not found", deleteErr: repository.ErrOverrideNotFound, wantErr: ErrOverrideNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &mockRepository{deleteErr: tt.deleteErr}
+			recorder := &mockAuditRecorder{}
+			s := NewService(repo, &mockGroupRepository{}, &mockHoldResolver{}, recorder, Policy{})
+
+			err := s.DeleteOverride(context.Background(), DeleteOverrideParams{GroupID: groupID})
+			assert.Equal(t, repository.DeleteParams{GroupID: groupID}, repo.deleteParams)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Empty(t, recorder.events)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, recorder.events, 1)
+			assert.Equal(t, audit.EventRetentionOverrideDeleted, recorder.events[0].Type)
+		})
+	}
+}
+
+func TestService_Policies(t *testing.T) {
+	t.Parallel()
+
+	groupID := uuid.New()
+	repo := &mockRepository{overrides: []*retention.Override{
+		{GroupID: groupID, Policy: retention.Policy{KeepVersions: 3}},
+	}}
+	s := NewService(repo, &mockGroupRepository{}, &mockHoldResolver{}, &mockAuditRecorder{}, Policy{KeepAuditDays: 90})
+
+	got, err := s.Policies(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, Policy{KeepAuditDays: 90}, got.Defaults)
+	assert.Equal(t, []*Override{{GroupID: groupID, Policy: Policy{KeepVersions: 3}}}, got.Overrides)
+}
+
+func TestService_Preview(t *testing.T) {
+	t.Parallel()
+
+	alice := uuid.MustParse("00000000-0000-0000-0000-00000000000a")
+	bob := uuid.MustParse("00000000-0000-0000-0000-00000000000b")
+	legal, support, removed := uuid.New(), uuid.New(), uuid.New()
+	groups := &mockGroupRepository{groups: map[uuid.UUID]*group.Group{
+		legal:   {ID: legal, Members: []uuid.UUID{alice}},
+		support: {ID: support, Members: []uuid.UUID{alice, bob}},
+	}}
+	repo := &mockRepository{overrides: []*retention.Override{
+		{GroupID: legal, Policy: retention.Policy{KeepVersions: 50}},
+		{GroupID: support, Policy: retention.Policy{KeepVersions: 5, KeepAuditDays: 30}},
+		{GroupID: removed, Policy: retention.Policy{KeepVersions: 1}},
+	}}
+	s := NewService(repo, groups, &mockHoldResolver{}, &mockAuditRecorder{}, Policy{KeepVersions: 10, KeepAuditDays: 90})
+
+	before := time.Now()
+	report, err := s.Preview(context.Background())
+
+	require.NoError(t, err)
+	assert.Empty(t, repo.purged)
+	assert.Equal(t, []*Scope{
+		{Policy: Policy{KeepVersions: 10, KeepAuditDays: 90}, Default: true, Versions: 2, AuditRecords: 1},
+		{Policy: Policy{KeepVersions: 5, KeepAuditDays: 30}, Users: 1, Versions: 2, AuditRecords: 1},
+		{Policy: Policy{KeepVersions: 50}, Users: 1, Versions: 2, AuditRecords: 1},
+	}, report.Scopes)
+	assert.Equal(t, int64(6), report.Versions)
+	assert.Equal(t, int64(3), report.AuditRecords)
+
+	require.Len(t, repo.counted, 3)
+	assert.Equal(t, []uuid.UUID{alice, bob}, repo.counted[0].ExcludeUserIDs)
+	assert.Nil(t, repo.counted[0].UserIDs)
+	assert.WithinDuration(t, before.AddDate(0, 0, -90), repo.counted[0].AuditBefore, time.Minute)
+	assert.Equal(t, []uuid.UUID{bob}, repo.counted[1].UserIDs)
+	assert.Equal(t, []uuid.UUID{alice}, repo.counted[2].UserIDs)
+	assert.Equal(t, 50, repo.counted[2].KeepVersions)
+	assert.True(t, repo.counted[2].AuditBefore.IsZero(), "merged policy keeps reveals forever")
+}
+
+func TestService_PreviewLegalHolds(t *testing.T) {
+	t.Parallel()
+
+	alice := uuid.MustParse("00000000-0000-0000-0000-00000000000a")
+	bob := uuid.MustParse("00000000-0000-0000-0000-00000000000b")
+	carol := uuid.MustParse("00000000-0000-0000-0000-00000000000c")
+	itemID := uuid.New()
+	legal, support := uuid.New(), uuid.New()
+	groups := &mockGroupRepository{groups: map[uuid.UUID]*group.Group{
+		legal:   {ID: legal, Members: []uuid.UUID{alice}},
+		support: {ID: support, Members: []uuid.UUID{bob}},
+	}}
+	repo := &mockRepository{overrides: []*retention.Override{
+		{GroupID: legal, Policy: retention.Policy{KeepVersions: 50}},
+		{GroupID: support, Policy: retention.Policy{KeepVersions: 5}},
+	}}
+	holds := &mockHoldResolver{held: legalholdApp.Held{UserIDs: []uuid.UUID{alice, carol}, ItemIDs: []uuid.UUID{itemID}}}
+	s := NewService(repo, groups, holds, &mockAuditRecorder{}, Policy{KeepVersions: 10})
+
+	report, err := s.Preview(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, report.Scopes, 3)
+	assert.Equal(t, 0, report.Scopes[2].Users, "held members are left out")
+	require.Len(t, repo.counted, 2)
+	assert.Equal(t, []uuid.UUID{alice, bob, carol}, repo.counted[0].ExcludeUserIDs)
+	assert.Equal(t, []uuid.UUID{itemID}, repo.counted[0].ExcludeItemIDs)
+	assert.Equal(t, []uuid.UUID{bob}, repo.counted[1].UserIDs)
+	assert.Equal(t, []uuid.UUID{itemID}, repo.counted[1].ExcludeItemIDs)
+
+	holds = &mockHoldResolver{err: errors.New("connection refused")}
+	_, err = NewService(repo, groups, holds, &mockAuditRecorder{}, Policy{KeepVersions: 10}).Preview(context.Background())
+	assert.ErrorIs(t, err, ErrRetentionTechError)
+}
+
+func TestService_Enforce(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		purgeErr   error
+		name       string
+		defaults   Policy
+		wantPurges int
+		wantEvents int
+	}{
+		{name: "purges", defaults: Policy{KeepVersions: 10}, wantPurges: 1, wantEvents: 1},
+		{name: "keep everything", defaults: Policy{}},
+		{
+			name:       "purge error",
+			defaults:   Policy{KeepAuditDays: 30},
+			purgeErr:   errors.New("connection refused"),
+			wantPurges: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &mockRepository{purgeErr: tt.purgeErr}
+			recorder := &mockAuditRecorder{}
+			s := NewService(repo, &mockGroupRepository{}, &mockHoldResolver{}, recorder, tt.defaults)
+
+			report, err := s.Enforce(context.Background())
+			assert.Len(t, repo.purged, tt.wantPurges)
+			assert.Empty(t, repo.counted)
+			assert.Len(t, recorder.events, tt.wantEvents)
+			if tt.purgeErr != nil {
+				require.ErrorIs(t, err, ErrRetentionTechError)
+				return
+			}
+			require.NoError(t, err)
+			if tt.wantEvents > 0 {
+				assert.Equal(t, audit.EventRetentionEnforced, recorder.events[0].Type)
+				assert.Equal(t, "2", recorder.events[0].Details["versions"])
+				assert.Equal(t, int64(2), report.Versions)
+			}
+		})
+	}
+}
@@ -0,0 +1,5 @@
+// Package rotation provides credential rotation application services for the AegisVaultKeeper server.
+//
+// This package manages the rotation policies of credentials and rotates their secrets in the external
+// systems they belong to, on schedule or on demand, storing every new secret as a new credential version.
+package rotation
@@ -0,0 +1,110 @@
+package rotation
+
+import (
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/rotation"
+	"github.com/google/uuid"
+)
+
+// Settings represents the address of the external system a credential secret belongs to.
+type Settings struct {
+	// Host contains the PostgreSQL server host.
+	Host string
+	// Database contains the PostgreSQL database.
+	Database string
+	// SSLMode contains the PostgreSQL TLS mode.
+	SSLMode string
+	// UserName contains the AWS IAM user.
+	UserName string
+	// Region contains the AWS signing region.
+	Region string
+	// Endpoint contains the AWS IAM API URL.
+	Endpoint string
+	// URL contains the webhook URL.
+	URL string
+	// Port contains the PostgreSQL server port.
+	Port int
+}
+
+// Policy represents a credential rotation policy data transfer object for application layer communication.
+type Policy struct {
+	// CreatedAt indicates when the policy was created.
+	CreatedAt time.Time
+	// LastRotatedAt indicates when the secret was last rotated; zero if never rotated.
+	LastRotatedAt time.Time
+	// NextRotationAt indicates when the next scheduled rotation is due; zero when rotated on demand only.
+	NextRotationAt time.Time
+	// LastError contains the failure reason of the last rotation; empty if it succeeded.
+	LastError string
+	// Kind names the rotator replacing the secret.
+	Kind string
+	// Settings addresses the external system.
+	Settings Settings
+	// Interval specifies how often the secret is rotated; zero rotates on demand only.
+	Interval time.Duration
+	// CredentialID identifies the rotated credential.
+	CredentialID uuid.UUID
+}
+
+// newPolicyFromDomain converts a domain rotation policy to application DTO.
+func newPolicyFromDomain(p *rotation.Policy) *Policy {
+	if p == nil {
+		return nil
+	}
+	return &Policy{
+		CredentialID:   p.CredentialID,
+		Kind:           string(p.Kind),
+		Settings:       Settings(p.Settings),
+		Interval:       p.Interval,
+		LastError:      p.LastError,
+		CreatedAt:      p.CreatedAt,
+		LastRotatedAt:  p.LastRotatedAt,
+		NextRotationAt: p.NextRotationAt,
+	}
+}
+
+// newPoliciesFromDomain converts a slice of domain rotation policies to application DTOs.
+func newPoliciesFromDomain(ps []*rotation.Policy) []*Policy {
+	result := make([]*Policy, 0, len(ps))
+	for _, p := range ps {
+		result = append(result, newPolicyFromDomain(p))
+	}
+	return result
+}
+
+// SetPolicyParams contains parameters for setting the rotation policy of a credential.
+type SetPolicyParams struct {
+	// Kind names the rotator replacing the secret.
+	Kind string
+	// Settings addresses the external system.
+	Settings Settings
+	// Interval specifies how often the secret is rotated; zero rotates on demand only.
+	Interval time.Duration
+	// CredentialID identifies the rotated credential.
+	CredentialID uuid.UUID
+	// UserID identifies the owner of the credential.
+	UserID uuid.UUID
+}
+
+// ListParams contains parameters for listing the rotation policies of a user.
+type ListParams struct {
+	// UserID identifies the owner of the credentials.
+	UserID uuid.UUID
+}
+
+// DeletePolicyParams contains parameters for removing the rotation policy of a credential.
+type DeletePolicyParams struct {
+	// CredentialID identifies the rotated credential.
+	CredentialID uuid.UUID
+	// UserID identifies the owner of the credential.
+	UserID uuid.UUID
+}
+
+// RotateParams contains parameters for rotating the secret of a credential on demand.
+type RotateParams struct {
+	// CredentialID identifies the rotated credential.
+	CredentialID uuid.UUID
+	// UserID identifies the owner of the credential.
+	UserID uuid.UUID
+}
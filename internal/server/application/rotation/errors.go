@@ -0,0 +1,68 @@
+package rotation
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/errutil"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/rotation"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/rotation"
+)
+
+// Credential rotation error definitions.
+var (
+	// ErrRotationAppError indicates a general credential rotation application error.
+	ErrRotationAppError = errors.New("credential rotation application error")
+
+	// ErrRotationTechError indicates a technical error in the credential rotation system.
+	ErrRotationTechError = errors.New("credential rotation technical error")
+
+	// ErrPolicyNotFound indicates that the credential has no rotation policy.
+	ErrPolicyNotFound = errors.New("rotation policy not found")
+
+	// ErrCredentialNotFound indicates that the credential was not found among those of the user.
+	ErrCredentialNotFound = errors.New("credential not found")
+
+	// ErrIncorrectKind indicates that the rotator kind is not supported.
+	ErrIncorrectKind = errors.New("incorrect rotator kind")
+
+	// ErrIncorrectSettings indicates that the rotator settings are missing or malformed.
+	ErrIncorrectSettings = errors.New("incorrect rotator settings")
+
+	// ErrIncorrectInterval indicates that the rotation interval is negative or too short.
+	ErrIncorrectInterval = errors.New("incorrect rotation interval")
+
+	// ErrRotationFailed indicates that the external system did not accept the new secret.
+	ErrRotationFailed = errors.New("credential rotation failed")
+)
+
+// mapError maps domain and repository errors to application-level errors.
+func mapError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	mapped := errutil.MapError(mapFn, err)
+	if mapped != nil {
+		return fmt.Errorf("credential rotation error mapping failed: %w", mapped)
+	}
+	return nil
+}
+
+// mapFn provides the actual error mapping logic for different error types.
+func mapFn(err error) error {
+	switch {
+	case errors.Is(err, rotation.ErrNewPolicyParamsValidation), errors.Is(err, rotation.ErrIncorrectCredential):
+		return ErrRotationAppError
+	case errors.Is(err, rotation.ErrIncorrectKind):
+		return ErrIncorrectKind
+	case errors.Is(err, rotation.ErrIncorrectSettings):
+		return ErrIncorrectSettings
+	case errors.Is(err, rotation.ErrIncorrectInterval):
+		return ErrIncorrectInterval
+	case errors.Is(err, repository.ErrPolicyNotFound):
+		return ErrPolicyNotFound
+	default:
+		return errors.Join(ErrRotationTechError, err)
+	}
+}
@@ -0,0 +1,286 @@
+package rotation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	credentialApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/credential"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/audit"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/rotation"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/rotation"
+	"github.com/google/uuid"
+)
+
+// Rotation triggers recorded in the audit log.
+const (
+	// triggerManual marks rotations requested by the owner.
+	triggerManual = "manual"
+	// triggerScheduled marks rotations run when the policy interval elapsed.
+	triggerScheduled = "scheduled"
+)
+
+// Repository defines the interface for rotation policy persistence operations.
+type Repository interface {
+	// Save persists a rotation policy using the provided parameters.
+	Save(ctx context.Context, params repository.SaveParams) error
+	// Load retrieves rotation policies using the provided parameters.
+	Load(ctx context.Context, params repository.LoadParams) ([]*rotation.Policy, error)
+	// Delete removes a rotation policy using the provided parameters.
+	Delete(ctx context.Context, params repository.DeleteParams) error
+}
+
+// CredentialStore defines the interface for reading credentials and storing their rotated secrets.
+type CredentialStore interface {
+	// Pull retrieves a specific credential of the owner.
+	Pull(ctx context.Context, params credentialApp.PullParams) (*credentialApp.Credential, error)
+	// Rotate stores the new secret of the credential of the owner as a new version.
+	Rotate(ctx context.Context, params credentialApp.RotateParams) error
+}
+
+// Rotator defines the interface for replacing a secret in the external system it belongs to.
+type Rotator interface {
+	// Rotate generates a new secret, authenticating to the external system with the current one, and
+	// returns it. A non-empty secret returned together with an error was already accepted by the system.
+	Rotate(ctx context.Context, p *rotation.Policy, current rotation.Secret) (rotation.Secret, error)
+}
+
+// AuditRecorder defines the interface for recording security audit events.
+type AuditRecorder interface {
+	// Record stores the audit event.
+	Record(ctx context.Context, e audit.Event)
+}
+
+// Service provides the rotation of credential secrets in external systems.
+type Service struct {
+	// r is the repository interface for rotation policy persistence operations.
+	r Repository
+	// credentials reads the rotated credentials and stores their new secrets.
+	credentials CredentialStore
+	// rotators replace secrets in external systems, keyed by rotator kind.
+	rotators map[rotation.Kind]Rotator
+	// audit records policy changes and rotations.
+	audit AuditRecorder
+	// now returns the current time.
+	now func() time.Time
+}
+
+// NewService creates a new credential rotation service instance.
+func NewService(
+	r Repository,
+	credentials CredentialStore,
+	rotators map[rotation.Kind]Rotator,
+	audit AuditRecorder,
+) *Service {
+	return &Service{
+		r:           r,
+		credentials: credentials,
+		rotators:    rotators,
+		audit:       audit,
+		now:         time.Now,
+	}
+}
+
+// SetPolicy creates or replaces the rotation policy of a credential of the owner.
+// The next scheduled rotation is counted from now.
+func (s *Service) SetPolicy(ctx context.Context, params SetPolicyParams) (*Policy, error) {
+	p, err := rotation.NewPolicy(rotation.NewPolicyParams{
+		Kind:         rotation.Kind(params.Kind),
+		Settings:     rotation.Settings(params.Settings),
+		Interval:     params.Interval,
+		CredentialID: params.CredentialID,
+		UserID:       params.UserID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create rotation policy: %w", mapError(err))
+	}
+
+	if _, err := s.pullCredential(ctx, params.CredentialID, params.UserID); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.r.Load(ctx, repository.LoadParams{CredentialID: params.CredentialID, UserID: params.UserID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load rotation policy: %w", mapError(err))
+	}
+	if len(existing) != 0 {
+		p.CreatedAt = existing[0].CreatedAt
+		p.LastRotatedAt = existing[0].LastRotatedAt
+	}
+
+	if err := s.r.Save(ctx, repository.SaveParams{Entity: p}); err != nil {
+		return nil, fmt.Errorf("failed to save rotation policy: %w", mapError(err))
+	}
+	s.audit.Record(ctx, audit.Event{
+		Type:   audit.EventCredentialRotationConfigured,
+		UserID: params.UserID,
+		Details: map[string]string{
+			"credential_id": params.CredentialID.String(),
+			"kind":          string(p.Kind),
+			"interval":      p.Interval.String(),
+		},
+	})
+	return newPolicyFromDomain(p), nil
+}
+
+// ListPolicies retrieves the rotation policies of the credentials of the owner.
+func (s *Service) ListPolicies(ctx context.Context, params ListParams) ([]*Policy, error) {
+	ps, err := s.r.Load(ctx, repository.LoadParams{UserID: params.UserID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load rotation policies: %w", mapError(err))
+	}
+	return newPoliciesFromDomain(ps), nil
+}
+
+// DeletePolicy removes the rotation policy of a credential of the owner. The credential keeps its secret.
+func (s *Service) DeletePolicy(ctx context.Context, params DeletePolicyParams) error {
+	if err := s.r.Delete(ctx, repository.DeleteParams{
+		CredentialID: params.CredentialID,
+		UserID:       params.UserID,
+	}); err != nil {
+		return fmt.Errorf("failed to delete rotation policy: %w", mapError(err))
+	}
+	s.audit.Record(ctx, audit.Event{
+		Type:    audit.EventCredentialRotationRemoved,
+		UserID:  params.UserID,
+		Details: map[string]string{"credential_id": params.CredentialID.String()},
+	})
+	return nil
+}
+
+// RotateNow rotates the secret of a credential of the owner immediately and returns the updated policy.
+// A rejected rotation is recorded on the policy and reported as ErrRotationFailed.
+func (s *Service) RotateNow(ctx context.Context, params RotateParams) (*Policy, error) {
+	ps, err := s.r.Load(ctx, repository.LoadParams{CredentialID: params.CredentialID, UserID: params.UserID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load rotation policy: %w", mapError(err))
+	}
+	if len(ps) == 0 {
+		return nil, fmt.Errorf("credential %s: %w", params.CredentialID, ErrPolicyNotFound)
+	}
+
+	if err := s.rotate(ctx, ps[0], triggerManual); err != nil {
+		return nil, err
+	}
+	return newPolicyFromDomain(ps[0]), nil
+}
+
+// RotateDue rotates every credential whose scheduled rotation is due and returns how many were rotated.
+// Failures do not stop the remaining rotations and are reported together.
+func (s *Service) RotateDue(ctx context.Context) (int, error) {
+	due, err := s.r.Load(ctx, repository.LoadParams{DueAt: s.now()})
+	if err != nil {
+		return 0, fmt.Errorf("failed to load due rotation policies: %w", mapError(err))
+	}
+
+	var (
+		n    int
+		errs []error
+	)
+	for _, p := range due {
+		if err := s.rotate(ctx, p, triggerScheduled); err != nil {
+			if !errors.Is(err, ErrCredentialNotFound) {
+				errs = append(errs, err)
+			}
+			continue
+		}
+		n++
+	}
+	return n, errors.Join(errs...)
+}
+
+// pullCredential retrieves the credential of the owner, reporting missing and foreign credentials
+// as ErrCredentialNotFound.
+func (s *Service) pullCredential(
+	ctx context.Context,
+	credentialID, userID uuid.UUID,
+) (*credentialApp.Credential, error) {
+	c, err := s.credentials.Pull(ctx, credentialApp.PullParams{ID: credentialID, UserID: userID})
+	if err != nil {
+		if errors.Is(err, credentialApp.ErrCredentialNotFound) || errors.Is(err, credentialApp.ErrCredentialAccessDenied) {
+			return nil, fmt.Errorf("credential %s rotated: %w", credentialID, ErrCredentialNotFound)
+		}
+		return nil, fmt.Errorf("failed to load rotated credential: %w", mapError(err))
+	}
+	return c, nil
+}
+
+// rotate replaces the secret of the credential of the policy in its external system, stores the new
+// secret as a new credential version and records the outcome on the policy. The policy of a credential
+// that no longer exists is removed.
+func (s *Service) rotate(ctx context.Context, p *rotation.Policy, trigger string) error {
+	c, err := s.pullCredential(ctx, p.CredentialID, p.UserID)
+	if errors.Is(err, ErrCredentialNotFound) {
+		if err := s.r.Delete(ctx, repository.DeleteParams{
+			CredentialID: p.CredentialID,
+			UserID:       p.UserID,
+		}); err != nil && !errors.Is(err, repository.ErrPolicyNotFound) {
+			return fmt.Errorf("failed to delete orphaned rotation policy: %w", mapError(err))
+		}
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	rotateErr := s.replace(ctx, p, rotation.Secret{Login: c.Login, Password: c.Password})
+	if rotateErr != nil {
+		p.Failed(s.now(), rotateErr.Error())
+		s.audit.Record(ctx, audit.Event{
+			Type:   audit.EventCredentialRotationFailed,
+			UserID: p.UserID,
+			Details: map[string]string{
+				"credential_id": p.CredentialID.String(),
+				"kind":          string(p.Kind),
+				"trigger":       trigger,
+			},
+		})
+	} else {
+		p.Rotated(s.now())
+		s.audit.Record(ctx, audit.Event{
+			Type:   audit.EventCredentialRotated,
+			UserID: p.UserID,
+			Details: map[string]string{
+				"credential_id": p.CredentialID.String(),
+				"kind":          string(p.Kind),
+				"trigger":       trigger,
+			},
+		})
+	}
+
+	if err := s.r.Save(ctx, repository.SaveParams{Entity: p}); err != nil {
+		return errors.Join(rotateErr, fmt.Errorf("failed to save rotation policy: %w", mapError(err)))
+	}
+	if rotateErr != nil {
+		return fmt.Errorf("credential %s: %w: %w", p.CredentialID, ErrRotationFailed, rotateErr)
+	}
+	return nil
+}
+
+// replace runs the rotator of the policy and stores the secret it returns. A secret returned together
+// with an error is stored as well, since the external system already accepted it.
+func (s *Service) replace(ctx context.Context, p *rotation.Policy, current rotation.Secret) error {
+	rotator, ok := s.rotators[p.Kind]
+	if !ok {
+		return fmt.Errorf("no rotator for kind %q", p.Kind)
+	}
+
+	next, rotateErr := rotator.Rotate(ctx, p, current)
+	if next.Password == "" {
+		if rotateErr == nil {
+			rotateErr = errors.New("rotator returned an empty secret")
+		}
+		return rotateErr
+	}
+
+	if err := s.credentials.Rotate(ctx, credentialApp.RotateParams{
+		ID:       p.CredentialID,
+		UserID:   p.UserID,
+		Login:    next.Login,
+		Password: next.Password,
+	}); err != nil {
+		return errors.Join(rotateErr, fmt.Errorf("failed to store rotated secret: %w", err))
+	}
+	return rotateErr
+}
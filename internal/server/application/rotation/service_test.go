@@ -0,0 +1,328 @@
+package rotation
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	credentialApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/credential"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/audit"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/rotation"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/rotation"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockRepository implements Repository for testing.
+type mockRepository struct {
+	saved   *rotation.Policy
+	stored  []*rotation.Policy
+	deleted []repository.DeleteParams
+}
+
+func (m *mockRepository) Save(_ context.Context, params repository.SaveParams) error {
+	m.saved = params.Entity
+	return nil
+}
+
+func (m *mockRepository) Load(_ context.Context, params repository.LoadParams) ([]*rotation.Policy, error) {
+	var ps []*rotation.Policy
+	for _, p := range m.stored {
+		if params.UserID != uuid.Nil && p.UserID != params.UserID {
+			continue
+		}
+		if params.CredentialID != uuid.Nil && p.CredentialID != params.CredentialID {
+			continue
+		}
+		if !params.DueAt.IsZero() && !p.Due(params.DueAt) {
+			continue
+		}
+		ps = append(ps, p)
+	}
+	return ps, nil
+}
+
+func (m *mockRepository) Delete(_ context.Context, params repository.DeleteParams) error {
+	m.deleted = append(m.deleted, params)
+	if len(m.stored) == 0 {
+		return repository.ErrPolicyNotFound
+	}
+	return nil
+}
+
+// mockCredentials implements CredentialStore for testing.
+type mockCredentials struct {
+	pullErr error
+	rotated []credentialApp.RotateParams
+}
+
+func (m *mockCredentials) Pull(_ context.Context, params credentialApp.PullParams) (*credentialApp.Credential, error) {
+	if m.pullErr != nil {
+		return nil, m.pullErr
+	}
+	return &credentialApp.Credential{ID: params.ID, UserID: params.UserID, Login: "app", Password: "old"}, nil
+}
+
+func (m *mockCredentials) Rotate(_ context.Context, params credentialApp.RotateParams) error {
+	m.rotated = append(m.rotated, params)
+	return nil
+}
+
+// mockRotator implements Rotator for testing.
+type mockRotator struct {
+	err     error
+	next    rotation.Secret
+	current rotation.Secret
+}
+
+func (m *mockRotator) Rotate(_ context.Context, _ *rotation.Policy, current rotation.Secret) (rotation.Secret, error) {
+	m.current = current
+	return m.next, m.err
+}
+
+// mockAuditRecorder implements AuditRecorder for testing.
+type mockAuditRecorder struct {
+	events []audit.Event
+}
+
+func (m *mockAuditRecorder) Record(_ context.Context, e audit.Event) {
+	m.events = append(m.events, e)
+}
+
+// eventTypes returns the types of the recorded events in order.
+func (m *mockAuditRecorder) eventTypes() []string {
+	types := make([]string, 0, len(m.events))
+	for _, e := range m.events {
+		types = append(types, e.Type)
+	}
+	return types
+}
+
+func TestService_SetPolicy(t *testing.T) {
+	t.Parallel()
+
+	credentialID, userID := uuid.New(), uuid.New()
+	rotatedAt := time.Date(2026, 9, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		pullErr     error
+		wantErr     error
+		existing    *rotation.Policy
+		name        string
+		kind        string
+		wantRotated time.Time
+		interval    time.Duration
+	}{
+		{name: "new policy", kind: "webhook", interval: 24 * time.Hour},
+		{
+			name:        "existing policy keeps the last rotation",
+			kind:        "webhook",
+			existing:    &rotation.Policy{CredentialID: credentialID, UserID: userID, LastRotatedAt: rotatedAt},
+			wantRotated: rotatedAt,
+		},
+		{name: "unknown kind", kind: "ftp", wantErr: ErrIncorrectKind},
+		{name: "short interval", kind: "webhook", interval: time.Minute, wantErr: ErrIncorrectInterval},
+		{
+			name:    "credential of another user",
+			kind:    "webhook",
+			pullErr: credentialApp.ErrCredentialAccessDenied,
+			wantErr: ErrCredentialNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &mockRepository{}
+			if tt.existing != nil {
+				repo.stored = []*rotation.Policy{tt.existing}
+			}
+			recorder := &mockAuditRecorder{}
+			s := NewService(repo, &mockCredentials{pullErr: tt.pullErr}, nil, recorder)
+
+			got, err := s.SetPolicy(context.Background(), SetPolicyParams{
+				Kind:         tt.kind,
+				Settings:     Settings{URL: "https://hooks.example.com/rotate"},
+				Interval:     tt.interval,
+				CredentialID: credentialID,
+				UserID:       userID,
+			})
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, repo.saved)
+				assert.Empty(t, recorder.events)
+				return
+			}
+			require.NoError(t, err)
+			require.NotNil(t, repo.saved)
+			assert.Equal(t, tt.interval, got.Interval)
+			assert.Equal(t, tt.wantRotated, got.LastRotatedAt)
+			assert.Equal(t, []string{audit.EventCredentialRotationConfigured}, recorder.eventTypes())
+		})
+	}
+}
+
+func TestService_DeletePolicy(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		wantErr error
+		name    string
+		stored  bool
+	}{
+		{name: "existing policy", stored: true},
+		{name: "missing policy", wantErr: ErrPolicyNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &mockRepository{}
+			if tt.stored {
+				repo.stored = []*rotation.Policy{{}}
+			}
+			recorder := &mockAuditRecorder{}
+			s := NewService(repo, nil, nil, recorder)
+
+			err := s.DeletePolicy(context.Background(), DeletePolicyParams{CredentialID: uuid.New(), UserID: uuid.New()})
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Empty(t, recorder.events)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, []string{audit.EventCredentialRotationRemoved}, recorder.eventTypes())
+		})
+	}
+}
+
+func TestService_RotateNow(t *testing.T) {
+	t.Parallel()
+
+	credentialID, userID := uuid.New(), uuid.New()
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	errRejected := errors.New("target rejected the secret")
+
+	tests := []struct {
+		rotatorErr  error
+		pullErr     error
+		wantErr     error
+		name        string
+		next        rotation.Secret
+		wantEvents  []string
+		wantStored  int
+		wantDeleted int
+		noPolicy    bool
+	}{
+		{
+			name:       "rotated",
+			next:       rotation.Secret{Login: "app", Password: "new"},
+			wantEvents: []string{audit.EventCredentialRotated},
+			wantStored: 1,
+		},
+		{
+			name:       "rejected by the target",
+			rotatorErr: errRejected,
+			wantErr:    ErrRotationFailed,
+			wantEvents: []string{audit.EventCredentialRotationFailed},
+		},
+		{
+			name:       "accepted by the target with a cleanup error",
+			next:       rotation.Secret{Login: "AKIANEW", Password: "new"},
+			rotatorErr: errRejected,
+			wantErr:    ErrRotationFailed,
+			wantEvents: []string{audit.EventCredentialRotationFailed},
+			wantStored: 1,
+		},
+		{name: "missing policy", noPolicy: true, wantErr: ErrPolicyNotFound},
+		{
+			name:        "deleted credential",
+			pullErr:     credentialApp.ErrCredentialNotFound,
+			wantErr:     ErrCredentialNotFound,
+			wantDeleted: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &mockRepository{}
+			if !tt.noPolicy {
+				repo.stored = []*rotation.Policy{{
+					CredentialID: credentialID,
+					UserID:       userID,
+					Kind:         rotation.KindWebhook,
+					Interval:     24 * time.Hour,
+				}}
+			}
+			creds := &mockCredentials{pullErr: tt.pullErr}
+			rotator := &mockRotator{next: tt.next, err: tt.rotatorErr}
+			recorder := &mockAuditRecorder{}
+			s := NewService(repo, creds, map[rotation.Kind]Rotator{rotation.KindWebhook: rotator}, recorder)
+			s.now = func() time.Time { return now }
+
+			got, err := s.RotateNow(context.Background(), RotateParams{CredentialID: credentialID, UserID: userID})
+
+			assert.Equal(t, tt.wantEvents, nilIfEmpty(recorder.eventTypes()))
+			assert.Len(t, creds.rotated, tt.wantStored)
+			assert.Len(t, repo.deleted, tt.wantDeleted)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, rotation.Secret{Login: "app", Password: "old"}, rotator.current)
+			assert.Equal(t, now, got.LastRotatedAt)
+			assert.Equal(t, now.Add(24*time.Hour), got.NextRotationAt)
+			assert.Empty(t, got.LastError)
+		})
+	}
+}
+
+func TestService_RotateDue(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	due := &rotation.Policy{
+		CredentialID:   uuid.New(),
+		Kind:           rotation.KindPostgres,
+		Interval:       time.Hour,
+		NextRotationAt: now.Add(-time.Minute),
+	}
+	notDue := &rotation.Policy{
+		CredentialID:   uuid.New(),
+		Kind:           rotation.KindPostgres,
+		Interval:       time.Hour,
+		NextRotationAt: now.Add(time.Minute),
+	}
+	onDemand := &rotation.Policy{CredentialID: uuid.New(), Kind: rotation.KindPostgres}
+
+	repo := &mockRepository{stored: []*rotation.Policy{due, notDue, onDemand}}
+	creds := &mockCredentials{}
+	rotator := &mockRotator{next: rotation.Secret{Login: "app", Password: "new"}}
+	s := NewService(repo, creds, map[rotation.Kind]Rotator{rotation.KindPostgres: rotator}, &mockAuditRecorder{})
+	s.now = func() time.Time { return now }
+
+	n, err := s.RotateDue(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	require.Len(t, creds.rotated, 1)
+	assert.Equal(t, due.CredentialID, creds.rotated[0].ID)
+	assert.Equal(t, now.Add(time.Hour), due.NextRotationAt)
+}
+
+// nilIfEmpty returns nil for an empty slice so expectations can leave it unset.
+func nilIfEmpty(s []string) []string {
+	if len(s) == 0 {
+		return nil
+	}
+	return s
+}
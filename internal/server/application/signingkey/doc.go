@@ -0,0 +1,5 @@
+// Package signingkey provides request signing key application services for the AegisVaultKeeper server.
+//
+// This package implements management of the per-client HMAC keys users sign export requests
+// with, and resolution of those keys for signature verification.
+package signingkey
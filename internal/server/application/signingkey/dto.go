@@ -0,0 +1,77 @@
+package signingkey
+
+import (
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/signingkey"
+	"github.com/google/uuid"
+)
+
+// SigningKey represents a request signing key data transfer object for application layer communication.
+type SigningKey struct {
+	// CreatedAt indicates when the key was created.
+	CreatedAt time.Time
+	// Name labels the client holding the key.
+	Name string
+	// Secret contains the key material; it is only returned once, when the key is created.
+	Secret []byte
+	// ID identifies the key in request signatures.
+	ID uuid.UUID
+}
+
+// newSigningKeyFromDomain converts a domain signing key entity to application DTO without its secret.
+func newSigningKeyFromDomain(k *signingkey.SigningKey) *SigningKey {
+	if k == nil {
+		return nil
+	}
+	return &SigningKey{
+		ID:        k.ID,
+		Name:      k.Name,
+		CreatedAt: k.CreatedAt,
+	}
+}
+
+// newSigningKeysFromDomain converts a slice of domain signing key entities to application DTOs.
+func newSigningKeysFromDomain(ks []*signingkey.SigningKey) []*SigningKey {
+	result := make([]*SigningKey, 0, len(ks))
+	for _, k := range ks {
+		result = append(result, newSigningKeyFromDomain(k))
+	}
+	return result
+}
+
+// CreateParams contains parameters for creating a signing key.
+type CreateParams struct {
+	// Name labels the client the key is issued to.
+	Name string
+	// UserID identifies the owner of the key.
+	UserID uuid.UUID
+}
+
+// ListParams contains parameters for listing signing keys.
+type ListParams struct {
+	// UserID identifies the owner of the keys.
+	UserID uuid.UUID
+}
+
+// DeleteParams contains parameters for revoking a signing key.
+type DeleteParams struct {
+	// ID identifies the key to revoke.
+	ID uuid.UUID
+	// UserID identifies the owner of the key.
+	UserID uuid.UUID
+}
+
+// EnrolledParams contains parameters for checking whether a user signs export requests.
+type EnrolledParams struct {
+	// UserID identifies the user.
+	UserID uuid.UUID
+}
+
+// SecretParams contains parameters for resolving the key material of a signing key.
+type SecretParams struct {
+	// ID identifies the key named by the signature.
+	ID uuid.UUID
+	// UserID identifies the owner of the key.
+	UserID uuid.UUID
+}
@@ -0,0 +1,51 @@
+package signingkey
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/errutil"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/signingkey"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/signingkey"
+)
+
+// Signing key error definitions.
+var (
+	// ErrSigningKeyAppError indicates a general signing key application error.
+	ErrSigningKeyAppError = errors.New("signing key application error")
+
+	// ErrSigningKeyTechError indicates a technical error in the signing key system.
+	ErrSigningKeyTechError = errors.New("signing key technical error")
+
+	// ErrSigningKeyIncorrectName indicates an incorrect signing key name was provided.
+	ErrSigningKeyIncorrectName = errors.New("incorrect signing key name")
+
+	// ErrSigningKeyNotFound indicates the requested signing key was not found.
+	ErrSigningKeyNotFound = errors.New("signing key not found")
+)
+
+// mapError maps domain and repository errors to application-level errors.
+func mapError(err error) error {
+	if err == nil {
+		return nil
+	}
+	mapped := errutil.MapError(mapFn, err)
+	if mapped != nil {
+		return fmt.Errorf("signing key error mapping failed: %w", mapped)
+	}
+	return nil
+}
+
+// mapFn provides the actual error mapping logic for different error types.
+func mapFn(err error) error {
+	switch {
+	case errors.Is(err, signingkey.ErrNewSigningKeyParamsValidation):
+		return ErrSigningKeyAppError
+	case errors.Is(err, signingkey.ErrIncorrectName):
+		return ErrSigningKeyIncorrectName
+	case errors.Is(err, repository.ErrSigningKeyNotFound):
+		return ErrSigningKeyNotFound
+	default:
+		return errors.Join(ErrSigningKeyTechError, err)
+	}
+}
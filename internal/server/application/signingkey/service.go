@@ -0,0 +1,127 @@
+package signingkey
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/audit"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/signingkey"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/signingkey"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/securebytes"
+)
+
+// Repository defines the interface for signing key persistence operations.
+type Repository interface {
+	// Save persists a signing key using the provided parameters.
+	Save(ctx context.Context, params repository.SaveParams) error
+	// Load retrieves signing keys using the provided parameters.
+	Load(ctx context.Context, params repository.LoadParams) ([]*signingkey.SigningKey, error)
+	// Exists reports whether the user has signing keys.
+	Exists(ctx context.Context, params repository.ExistsParams) (bool, error)
+	// Delete removes a signing key using the provided parameters.
+	Delete(ctx context.Context, params repository.DeleteParams) error
+}
+
+// AuditRecorder defines the interface for recording security audit events.
+type AuditRecorder interface {
+	// Record stores the audit event.
+	Record(ctx context.Context, e audit.Event)
+}
+
+// Service provides request signing key operations.
+type Service struct {
+	// r is the repository interface for signing key persistence operations.
+	r Repository
+	// audit records key registrations and revocations.
+	audit AuditRecorder
+}
+
+// NewService creates a new signing key service instance.
+func NewService(r Repository, audit AuditRecorder) *Service {
+	return &Service{r: r, audit: audit}
+}
+
+// Create issues a new random signing key to a client of the user. The returned key carries its secret,
+// which is not retrievable afterwards. Once a user has a key, their requests must be signed,
+// including those registering further keys.
+func (s *Service) Create(ctx context.Context, params CreateParams) (*SigningKey, error) {
+	secret := make([]byte, signingkey.MinSecretLen)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("failed to generate signing key secret: %w", mapError(err))
+	}
+
+	key, err := signingkey.NewSigningKey(signingkey.NewSigningKeyParams{
+		Name:   params.Name,
+		Secret: secret,
+		UserID: params.UserID,
+	})
+	if err != nil {
+		securebytes.Wipe(secret)
+		return nil, fmt.Errorf("failed to create new signing key: %w", mapError(err))
+	}
+
+	if err := s.r.Save(ctx, repository.SaveParams{Entity: key}); err != nil {
+		key.Wipe()
+		return nil, fmt.Errorf("failed to save signing key: %w", mapError(err))
+	}
+
+	s.audit.Record(ctx, audit.Event{
+		Type:    audit.EventSigningKeyCreated,
+		UserID:  key.UserID,
+		Details: map[string]string{"key_id": key.ID.String(), "name": key.Name},
+	})
+
+	result := newSigningKeyFromDomain(key)
+	result.Secret = key.Secret
+	return result, nil
+}
+
+// List retrieves the signing keys of the user without their secrets.
+func (s *Service) List(ctx context.Context, params ListParams) ([]*SigningKey, error) {
+	keys, err := s.r.Load(ctx, repository.LoadParams{UserID: params.UserID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load signing keys: %w", mapError(err))
+	}
+	defer securebytes.WipeAll(keys)
+
+	return newSigningKeysFromDomain(keys), nil
+}
+
+// Delete revokes a signing key of the user. Revoking the last key lifts the signing requirement, so the
+// request doing so must itself be signed.
+func (s *Service) Delete(ctx context.Context, params DeleteParams) error {
+	err := s.r.Delete(ctx, repository.DeleteParams{ID: params.ID, UserID: params.UserID})
+	if err != nil {
+		return fmt.Errorf("failed to delete signing key: %w", mapError(err))
+	}
+
+	s.audit.Record(ctx, audit.Event{
+		Type:    audit.EventSigningKeyDeleted,
+		UserID:  params.UserID,
+		Details: map[string]string{"key_id": params.ID.String()},
+	})
+	return nil
+}
+
+// Enrolled reports whether the user has signing keys, and so must sign their requests.
+func (s *Service) Enrolled(ctx context.Context, params EnrolledParams) (bool, error) {
+	enrolled, err := s.r.Exists(ctx, repository.ExistsParams{UserID: params.UserID})
+	if err != nil {
+		return false, fmt.Errorf("failed to check signing keys: %w", mapError(err))
+	}
+	return enrolled, nil
+}
+
+// Secret returns the key material of a signing key of the user; the caller wipes it once done.
+// Returns ErrSigningKeyNotFound when the user has no such key.
+func (s *Service) Secret(ctx context.Context, params SecretParams) ([]byte, error) {
+	keys, err := s.r.Load(ctx, repository.LoadParams{ID: params.ID, UserID: params.UserID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load signing key: %w", mapError(err))
+	}
+	if len(keys) == 0 {
+		return nil, ErrSigningKeyNotFound
+	}
+	return keys[0].Secret, nil
+}
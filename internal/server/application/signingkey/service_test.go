@@ -0,0 +1,281 @@
+package signingkey
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/audit"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/signingkey"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/signingkey"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockRepository implements Repository for testing.
+type mockRepository struct {
+	loadErr      error
+	saveErr      error
+	existsErr    error
+	deleteErr    error
+	saved        *signingkey.SigningKey
+	loadParams   repository.LoadParams
+	deleteParams repository.DeleteParams
+	keys         []*signingkey.SigningKey
+	exists       bool
+}
+
+func (m *mockRepository) Save(_ context.Context, params repository.SaveParams) error {
+	m.saved = params.Entity
+	return m.saveErr
+}
+
+func (m *mockRepository) Load(_ context.Context, params repository.LoadParams) ([]*signingkey.SigningKey, error) {
+	m.loadParams = params
+	return m.keys, m.loadErr
+}
+
+func (m *mockRepository) Exists(context.Context, repository.ExistsParams) (bool, error) {
+	return m.exists, m.existsErr
+}
+
+func (m *mockRepository) Delete(_ context.Context, params repository.DeleteParams) error {
+	m.deleteParams = params
+	return m.deleteErr
+}
+
+// mockAuditRecorder implements AuditRecorder for testing.
+type mockAuditRecorder struct {
+	events []audit.Event
+}
+
+func (m *mockAuditRecorder) Record(_ context.Context, e audit.Event) {
+	m.events = append(m.events, e)
+}
+
+func TestService_Create(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+
+	tests := []struct {
+		saveErr error
+		wantErr error
+		name    string
+		keyName string
+	}{
+		{
+			name:    "key issued",
+			keyName: "backup-cli",
+		},
+		{
+			name:    "blank name",
+			keyName: " ",
+			wantErr: ErrSigningKeyIncorrectName,
+		},
+		{
+			name:    "save error",
+			keyName: "backup-cli",
+			saveErr: errors.New("connection refused"),
+			wantErr: ErrSigningKeyTechError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &mockRepository{saveErr: tt.saveErr}
+			recorder := &mockAuditRecorder{}
+			s := NewService(repo, recorder)
+
+			key, err := s.Create(context.Background(), CreateParams{Name: tt.keyName, UserID: userID})
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, key)
+				assert.Empty(t, recorder.events)
+				return
+			}
+			require.NoError(t, err)
+			require.NotNil(t, repo.saved)
+			assert.Equal(t, repo.saved.ID, key.ID)
+			assert.Equal(t, tt.keyName, key.Name)
+			assert.Len(t, key.Secret, signingkey.MinSecretLen)
+			assert.NotEqual(t, make([]byte, signingkey.MinSecretLen), key.Secret)
+			assert.Equal(t, userID, repo.saved.UserID)
+			require.Len(t, recorder.events, 1)
+			assert.Equal(t, audit.EventSigningKeyCreated, recorder.events[0].Type)
+			assert.Equal(t, key.ID.String(), recorder.events[0].Details["key_id"])
+		})
+	}
+}
+
+func TestService_List(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	createdAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		loadErr error
+		name    string
+		keys    []*signingkey.SigningKey
+		want    []*SigningKey
+	}{
+		{
+			name: "secrets omitted",
+			keys: []*signingkey.SigningKey{
+				{ID: uuid.Max, UserID: userID, Name: "laptop", Secret: []byte("secret"), CreatedAt: createdAt},
+			},
+			want: []*SigningKey{{ID: uuid.Max, Name: "laptop", CreatedAt: createdAt}},
+		},
+		{
+			name: "no keys",
+			want: []*SigningKey{},
+		},
+		{
+			name:    "load error",
+			loadErr: errors.New("connection refused"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &mockRepository{keys: tt.keys, loadErr: tt.loadErr}
+
+			got, err := NewService(repo, &mockAuditRecorder{}).List(context.Background(), ListParams{UserID: userID})
+
+			if tt.loadErr != nil {
+				require.ErrorIs(t, err, ErrSigningKeyTechError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, repository.LoadParams{UserID: userID}, repo.loadParams)
+			for _, k := range tt.keys {
+				assert.Equal(t, make([]byte, len("secret")), k.Secret)
+			}
+		})
+	}
+}
+
+func TestService_Delete(t *testing.T) {
+	t.Parallel()
+
+	params := DeleteParams{ID: uuid.New(), UserID: uuid.New()}
+
+	tests := []struct {
+		deleteErr error
+		wantErr   error
+		name      string
+	}{
+		{
+			name: "key revoked",
+		},
+		{
+			name:      "not found",
+			deleteErr: repository.ErrSigningKeyNotFound,
+			wantErr:   ErrSigningKeyNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &mockRepository{deleteErr: tt.deleteErr}
+			recorder := &mockAuditRecorder{}
+
+			err := NewService(repo, recorder).Delete(context.Background(), params)
+
+			assert.Equal(t, repository.DeleteParams{ID: params.ID, UserID: params.UserID}, repo.deleteParams)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Empty(t, recorder.events)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, recorder.events, 1)
+			assert.Equal(t, audit.EventSigningKeyDeleted, recorder.events[0].Type)
+		})
+	}
+}
+
+func TestService_Enrolled(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		existsErr error
+		name      string
+		exists    bool
+	}{
+		{name: "enrolled", exists: true},
+		{name: "not enrolled"},
+		{name: "exists error", existsErr: errors.New("connection refused")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := NewService(&mockRepository{exists: tt.exists, existsErr: tt.existsErr}, &mockAuditRecorder{})
+
+			got, err := s.Enrolled(context.Background(), EnrolledParams{UserID: uuid.New()})
+
+			if tt.existsErr != nil {
+				require.ErrorIs(t, err, ErrSigningKeyTechError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.exists, got)
+		})
+	}
+}
+
+func TestService_Secret(t *testing.T) {
+	t.Parallel()
+
+	params := SecretParams{ID: uuid.New(), UserID: uuid.New()}
+
+	tests := []struct {
+		loadErr error
+		wantErr error
+		name    string
+		keys    []*signingkey.SigningKey
+		want    []byte
+	}{
+		{
+			name: "key found",
+			keys: []*signingkey.SigningKey{{ID: params.ID, Secret: []byte("secret")}},
+			want: []byte("secret"),
+		},
+		{
+			name:    "not found",
+			loadErr: repository.ErrSigningKeyNotFound,
+			wantErr: ErrSigningKeyNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &mockRepository{keys: tt.keys, loadErr: tt.loadErr}
+
+			got, err := NewService(repo, &mockAuditRecorder{}).Secret(context.Background(), params)
+
+			assert.Equal(t, repository.LoadParams{ID: params.ID, UserID: params.UserID}, repo.loadParams)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,6 @@
+// Package stats provides usage statistics application services for the AegisVaultKeeper server.
+//
+// This package takes the periodic rollups of aggregate instance usage, combining database aggregates with
+// the request counters of the server process, prunes rollups past their retention and serves them to
+// administrators as time series.
+package stats
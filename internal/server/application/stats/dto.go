@@ -0,0 +1,92 @@
+package stats
+
+import (
+	"maps"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/stats"
+)
+
+// Rollup represents a snapshot of the aggregate usage of the instance for application layer communication.
+type Rollup struct {
+	// RolledUpAt indicates when the snapshot was taken.
+	RolledUpAt time.Time
+	// Items counts the stored items by item type.
+	Items map[string]int64
+	// StorageTiers counts the users by storage usage tier.
+	StorageTiers map[string]int64
+	// Users is the number of registered users.
+	Users int64
+	// DailyActiveUsers is the number of users who signed in during the last day.
+	DailyActiveUsers int64
+	// MonthlyActiveUsers is the number of users who signed in during the last 30 days.
+	MonthlyActiveUsers int64
+	// StorageBytes is the total size of stored file content.
+	StorageBytes int64
+	// Requests counts the API requests handled since the previous rollup.
+	Requests int64
+	// ClientErrors counts the requests answered with a 4xx status since the previous rollup.
+	ClientErrors int64
+	// ServerErrors counts the requests answered with a 5xx status since the previous rollup.
+	ServerErrors int64
+	// SyncRequests counts the vault synchronization requests since the previous rollup.
+	SyncRequests int64
+	// SyncBytes counts the bytes transferred by vault synchronization requests since the previous rollup.
+	SyncBytes int64
+}
+
+// ErrorRate returns the share of requests answered with a 5xx status, or zero without requests.
+func (r *Rollup) ErrorRate() float64 {
+	if r.Requests == 0 {
+		return 0
+	}
+	return float64(r.ServerErrors) / float64(r.Requests)
+}
+
+// newRollupFromDomain converts a domain rollup to application DTO.
+func newRollupFromDomain(r *stats.Rollup) *Rollup {
+	return &Rollup{
+		RolledUpAt:         r.RolledUpAt,
+		Items:              maps.Clone(r.Items),
+		StorageTiers:       maps.Clone(r.StorageTiers),
+		Users:              r.Users,
+		DailyActiveUsers:   r.DailyActiveUsers,
+		MonthlyActiveUsers: r.MonthlyActiveUsers,
+		StorageBytes:       r.StorageBytes,
+		Requests:           r.Requests,
+		ClientErrors:       r.ClientErrors,
+		ServerErrors:       r.ServerErrors,
+		SyncRequests:       r.SyncRequests,
+		SyncBytes:          r.SyncBytes,
+	}
+}
+
+// ReportParams contains the parameters for reporting usage statistics.
+type ReportParams struct {
+	// From is the start of the period; zero reports the DefaultPeriod before To.
+	From time.Time
+	// To is the exclusive end of the period; zero reports up to now.
+	To time.Time
+}
+
+// Report represents the usage statistics of a period.
+type Report struct {
+	// From is the start of the reported period.
+	From time.Time
+	// To is the exclusive end of the reported period.
+	To time.Time
+	// Latest is the most recent rollup of the period; nil when the period has none.
+	Latest *Rollup
+	// Rollups lists the rollups of the period, oldest first.
+	Rollups []*Rollup
+	// Requests counts the API requests handled during the period.
+	Requests int64
+	// ClientErrors counts the requests answered with a 4xx status during the period.
+	ClientErrors int64
+	// ServerErrors counts the requests answered with a 5xx status during the period.
+	ServerErrors int64
+	// SyncRequests counts the vault synchronization requests during the period.
+	SyncRequests int64
+	// SyncBytes counts the bytes transferred by vault synchronization requests during the period.
+	SyncBytes int64
+}
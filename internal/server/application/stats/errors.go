@@ -0,0 +1,12 @@
+package stats
+
+import "errors"
+
+// Usage statistics error definitions.
+var (
+	// ErrStatsPeriodInvalid indicates that the requested period does not end after it starts.
+	ErrStatsPeriodInvalid = errors.New("stats period is invalid")
+
+	// ErrStatsTechError indicates a technical error in the usage statistics system.
+	ErrStatsTechError = errors.New("stats technical error")
+)
@@ -0,0 +1,146 @@
+package stats
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/stats"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/stats"
+	"github.com/google/uuid"
+)
+
+// DefaultPeriod is the period reported when no start is requested.
+const DefaultPeriod = 7 * 24 * time.Hour
+
+// Metric names of the request counters rolled up into usage statistics.
+const (
+	// MetricRequests counts handled API requests.
+	MetricRequests = "http_requests_total"
+	// MetricClientErrors counts API requests answered with a 4xx status.
+	MetricClientErrors = "http_requests_client_errors_total"
+	// MetricServerErrors counts API requests answered with a 5xx status.
+	MetricServerErrors = "http_requests_server_errors_total"
+	// MetricSyncRequests counts vault synchronization requests.
+	MetricSyncRequests = "http_sync_requests_total"
+	// MetricSyncBytes counts the bytes received and sent by vault synchronization requests.
+	MetricSyncBytes = "http_sync_bytes_total"
+)
+
+// Repository defines the interface for aggregate usage queries and rollup persistence.
+type Repository interface {
+	// Collect returns a rollup holding the aggregate usage of the instance without request counters.
+	Collect(ctx context.Context, params repository.CollectParams) (*stats.Rollup, error)
+	// Save stores a rollup.
+	Save(ctx context.Context, params repository.SaveParams) error
+	// Load retrieves the rollups taken in a period, oldest first.
+	Load(ctx context.Context, params repository.LoadParams) ([]*stats.Rollup, error)
+	// Prune deletes the rollups taken before a moment and returns their number.
+	Prune(ctx context.Context, params repository.PruneParams) (int64, error)
+}
+
+// MetricsSnapshotter defines the interface for reading operational metrics.
+type MetricsSnapshotter interface {
+	// Snapshot returns a copy of all current metric values.
+	Snapshot() map[string]int64
+}
+
+// Service provides usage statistics operations.
+type Service struct {
+	// repository collects the aggregate usage and stores the rollups.
+	repository Repository
+	// metrics provides the request counters of the server process.
+	metrics MetricsSnapshotter
+	// now returns the current time.
+	now func() time.Time
+	// rolledUp holds the request counters included in the previous stored rollup.
+	rolledUp map[string]int64
+	// retention is how long rollups are kept; non-positive keeps them forever.
+	retention time.Duration
+	// mu guards rolledUp, so concurrent rollups never count the same requests twice.
+	mu sync.Mutex
+}
+
+// NewService creates a new usage statistics service keeping rollups for the retention period.
+// A non-positive retention keeps them forever.
+func NewService(repository Repository, metrics MetricsSnapshotter, retention time.Duration) *Service {
+	return &Service{
+		repository: repository,
+		metrics:    metrics,
+		now:        time.Now,
+		rolledUp:   make(map[string]int64),
+		retention:  retention,
+	}
+}
+
+// Rollup takes and stores a rollup of the aggregate usage, with the requests counted since the previous
+// rollup of this process, and prunes the rollups past the retention. Requests of a failed rollup are
+// counted by the next one.
+func (s *Service) Rollup(ctx context.Context) (*Rollup, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	r, err := s.repository.Collect(ctx, repository.CollectParams{At: now})
+	if err != nil {
+		return nil, errors.Join(ErrStatsTechError, err)
+	}
+	r.ID = uuid.New()
+	r.RolledUpAt = now
+
+	counters := s.metrics.Snapshot()
+	delta := func(name string) int64 { return max(counters[name]-s.rolledUp[name], 0) }
+	r.Requests = delta(MetricRequests)
+	r.ClientErrors = delta(MetricClientErrors)
+	r.ServerErrors = delta(MetricServerErrors)
+	r.SyncRequests = delta(MetricSyncRequests)
+	r.SyncBytes = delta(MetricSyncBytes)
+
+	if err := s.repository.Save(ctx, repository.SaveParams{Entity: r}); err != nil {
+		return nil, errors.Join(ErrStatsTechError, err)
+	}
+	s.rolledUp = counters
+
+	if s.retention > 0 {
+		if _, err := s.repository.Prune(ctx, repository.PruneParams{Before: now.Add(-s.retention)}); err != nil {
+			return nil, errors.Join(ErrStatsTechError, fmt.Errorf("rollup stored, pruning failed: %w", err))
+		}
+	}
+	return newRollupFromDomain(r), nil
+}
+
+// Report retrieves the rollups of the period with the request counters summed over it.
+func (s *Service) Report(ctx context.Context, params ReportParams) (*Report, error) {
+	to := params.To
+	if to.IsZero() {
+		to = s.now()
+	}
+	from := params.From
+	if from.IsZero() {
+		from = to.Add(-DefaultPeriod)
+	}
+	if !from.Before(to) {
+		return nil, ErrStatsPeriodInvalid
+	}
+
+	rollups, err := s.repository.Load(ctx, repository.LoadParams{From: from, To: to})
+	if err != nil {
+		return nil, errors.Join(ErrStatsTechError, err)
+	}
+
+	report := &Report{From: from, To: to, Rollups: make([]*Rollup, 0, len(rollups))}
+	for _, r := range rollups {
+		report.Rollups = append(report.Rollups, newRollupFromDomain(r))
+		report.Requests += r.Requests
+		report.ClientErrors += r.ClientErrors
+		report.ServerErrors += r.ServerErrors
+		report.SyncRequests += r.SyncRequests
+		report.SyncBytes += r.SyncBytes
+	}
+	if n := len(report.Rollups); n > 0 {
+		report.Latest = report.Rollups[n-1]
+	}
+	return report, nil
+}
@@ -0,0 +1,238 @@
+package stats
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/stats"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/stats"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// errDatabase is the error of failing repository operations.
+var errDatabase = errors.New("database unavailable")
+
+// mockRepository implements Repository for testing.
+type mockRepository struct {
+	collectErr error
+	saveErr    error
+	loadErr    error
+	pruneErr   error
+	saved      []*stats.Rollup
+	loaded     []*stats.Rollup
+	loads      []repository.LoadParams
+	prunes     []repository.PruneParams
+}
+
+func (m *mockRepository) Collect(context.Context, repository.CollectParams) (*stats.Rollup, error) {
+	if m.collectErr != nil {
+		return nil, m.collectErr
+	}
+	return &stats.Rollup{
+		Users:        3,
+		Items:        map[string]int64{stats.ItemNotes: 2},
+		StorageTiers: stats.Distribution(3, nil),
+	}, nil
+}
+
+func (m *mockRepository) Save(_ context.Context, params repository.SaveParams) error {
+	if m.saveErr != nil {
+		return m.saveErr
+	}
+	m.saved = append(m.saved, params.Entity)
+	return nil
+}
+
+func (m *mockRepository) Load(_ context.Context, params repository.LoadParams) ([]*stats.Rollup, error) {
+	m.loads = append(m.loads, params)
+	return m.loaded, m.loadErr
+}
+
+func (m *mockRepository) Prune(_ context.Context, params repository.PruneParams) (int64, error) {
+	m.prunes = append(m.prunes, params)
+	return 0, m.pruneErr
+}
+
+// mockMetrics implements MetricsSnapshotter for testing.
+type mockMetrics struct {
+	values map[string]int64
+}
+
+func (m *mockMetrics) Snapshot() map[string]int64 {
+	return maps.Clone(m.values)
+}
+
+func TestService_Rollup(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	repo := &mockRepository{}
+	metrics := &mockMetrics{values: map[string]int64{
+		MetricRequests:     10,
+		MetricClientErrors: 2,
+		MetricServerErrors: 1,
+		MetricSyncRequests: 4,
+		MetricSyncBytes:    1024,
+	}}
+	service := NewService(repo, metrics, 90*24*time.Hour)
+	service.now = func() time.Time { return now }
+
+	first, err := service.Rollup(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, now, first.RolledUpAt)
+	assert.Equal(t, int64(3), first.Users)
+	assert.Equal(t, int64(10), first.Requests)
+	assert.Equal(t, int64(1024), first.SyncBytes)
+	assert.InDelta(t, 0.1, first.ErrorRate(), 1e-9)
+	require.Len(t, repo.prunes, 1)
+	assert.Equal(t, now.Add(-90*24*time.Hour), repo.prunes[0].Before)
+
+	metrics.values[MetricRequests] = 25
+	metrics.values[MetricServerErrors] = 1
+	repo.saveErr = errDatabase
+	_, err = service.Rollup(context.Background())
+	require.ErrorIs(t, err, ErrStatsTechError)
+	require.ErrorIs(t, err, errDatabase)
+
+	metrics.values[MetricRequests] = 30
+	repo.saveErr = nil
+	second, err := service.Rollup(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(20), second.Requests, "requests of the failed rollup are counted by the next one")
+	assert.Zero(t, second.ServerErrors)
+	assert.Zero(t, second.ErrorRate())
+	require.Len(t, repo.saved, 2)
+	assert.NotEqual(t, repo.saved[0].ID, repo.saved[1].ID)
+}
+
+func TestService_RollupErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		repo      *mockRepository
+		name      string
+		retention time.Duration
+		wantSaved int
+	}{
+		{
+			name: "collect failure",
+			repo: &mockRepository{collectErr: errDatabase},
+		},
+		{
+			name:      "prune failure",
+			repo:      &mockRepository{pruneErr: errDatabase},
+			retention: time.Hour,
+			wantSaved: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			service := NewService(tt.repo, &mockMetrics{}, tt.retention)
+
+			_, err := service.Rollup(context.Background())
+
+			require.ErrorIs(t, err, ErrStatsTechError)
+			require.ErrorIs(t, err, errDatabase)
+			assert.Len(t, tt.repo.saved, tt.wantSaved)
+		})
+	}
+}
+
+func TestService_RollupKeepsForever(t *testing.T) {
+	t.Parallel()
+
+	repo := &mockRepository{}
+
+	_, err := NewService(repo, &mockMetrics{}, 0).Rollup(context.Background())
+
+	require.NoError(t, err)
+	assert.Empty(t, repo.prunes)
+}
+
+func TestService_Report(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	from := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	rollups := []*stats.Rollup{
+		{RolledUpAt: from.Add(time.Hour), Users: 2, Requests: 10, ServerErrors: 1, SyncBytes: 100},
+		{RolledUpAt: from.Add(2 * time.Hour), Users: 3, Requests: 5, ClientErrors: 2, SyncRequests: 1},
+	}
+
+	tests := []struct {
+		loadErr  error
+		wantErr  error
+		params   ReportParams
+		wantLoad repository.LoadParams
+		name     string
+		loaded   []*stats.Rollup
+	}{
+		{
+			name:     "default period",
+			loaded:   rollups,
+			wantLoad: repository.LoadParams{From: now.Add(-DefaultPeriod), To: now},
+		},
+		{
+			name:     "requested period",
+			params:   ReportParams{From: from, To: from.Add(24 * time.Hour)},
+			loaded:   rollups,
+			wantLoad: repository.LoadParams{From: from, To: from.Add(24 * time.Hour)},
+		},
+		{
+			name:     "empty period",
+			params:   ReportParams{From: from},
+			wantLoad: repository.LoadParams{From: from, To: now},
+		},
+		{
+			name:    "start after end",
+			params:  ReportParams{From: now, To: from},
+			wantErr: ErrStatsPeriodInvalid,
+		},
+		{
+			name:    "load failure",
+			loadErr: errDatabase,
+			wantErr: ErrStatsTechError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &mockRepository{loaded: tt.loaded, loadErr: tt.loadErr}
+			service := NewService(repo, &mockMetrics{}, 0)
+			service.now = func() time.Time { return now }
+
+			report, err := service.Report(context.Background(), tt.params)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, repo.loads, 1)
+			assert.Equal(t, tt.wantLoad, repo.loads[0])
+			assert.Equal(t, tt.wantLoad.From, report.From)
+			assert.Equal(t, tt.wantLoad.To, report.To)
+			if tt.loaded == nil {
+				assert.Nil(t, report.Latest)
+				assert.Empty(t, report.Rollups)
+				return
+			}
+			require.Len(t, report.Rollups, 2)
+			assert.Equal(t, int64(3), report.Latest.Users)
+			assert.Equal(t, int64(15), report.Requests)
+			assert.Equal(t, int64(2), report.ClientErrors)
+			assert.Equal(t, int64(1), report.ServerErrors)
+			assert.Equal(t, int64(1), report.SyncRequests)
+			assert.Equal(t, int64(100), report.SyncBytes)
+		})
+	}
+}
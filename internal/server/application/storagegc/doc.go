@@ -0,0 +1,6 @@
+// Package storagegc provides file storage reconciliation application services for the AegisVaultKeeper server.
+//
+// This package compares the stored file contents with the file metadata rows, reports
+// orphaned contents and metadata without content, and optionally removes orphaned
+// contents once they are older than a safety window.
+package storagegc
@@ -0,0 +1,49 @@
+package storagegc
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OrphanBlob describes stored file content without a matching file metadata row.
+type OrphanBlob struct {
+	// ModTime is the time the content was last written.
+	ModTime time.Time
+	// StorageKey is the path of the content within the user directory.
+	StorageKey string
+	// Size is the stored (encrypted) size of the content in bytes.
+	Size int64
+	// UserID identifies the user directory the content is stored in.
+	UserID uuid.UUID
+	// Removed determines whether the content was removed by this run.
+	Removed bool
+}
+
+// MissingBlob describes a file metadata row whose content is not in storage.
+type MissingBlob struct {
+	// StorageKey is the storage key recorded in the metadata.
+	StorageKey string
+	// FileID identifies the file metadata row.
+	FileID uuid.UUID
+	// UserID identifies the owner of the file.
+	UserID uuid.UUID
+}
+
+// Report summarizes a single reconciliation run.
+type Report struct {
+	// CheckedAt is the time the run started.
+	CheckedAt time.Time
+	// OrphanBlobs lists stored contents without file metadata.
+	OrphanBlobs []OrphanBlob
+	// MissingBlobs lists file metadata rows without stored content.
+	MissingBlobs []MissingBlob
+	// SkippedUsers lists users whose metadata could not be loaded; their contents are never reported as orphaned.
+	SkippedUsers []uuid.UUID
+	// Blobs is the number of stored contents scanned.
+	Blobs int
+	// Files is the number of file metadata rows checked.
+	Files int
+	// Removed is the number of orphaned contents removed by this run.
+	Removed int
+}
@@ -0,0 +1,6 @@
+package storagegc
+
+import "errors"
+
+// ErrStorageGCTechError indicates a technical error while reconciling file storage.
+var ErrStorageGCTechError = errors.New("storage reconciliation technical error")
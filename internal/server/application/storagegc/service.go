@@ -0,0 +1,211 @@
+package storagegc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/filedata"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/filedata"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/filestorage"
+	"github.com/google/uuid"
+)
+
+// DefaultGracePeriod is the age orphaned contents must reach before they are removed when no period is configured.
+const DefaultGracePeriod = 24 * time.Hour
+
+// Metric names updated by reconciliation runs.
+const (
+	// MetricRuns counts completed reconciliation runs.
+	MetricRuns = "storage_gc_runs_total"
+	// MetricRunFailures counts reconciliation runs that could not complete.
+	MetricRunFailures = "storage_gc_failures_total"
+	// MetricOrphanBlobs holds the number of orphaned contents found by the latest run.
+	MetricOrphanBlobs = "storage_gc_orphan_blobs"
+	// MetricMissingBlobs holds the number of metadata rows without content found by the latest run.
+	MetricMissingBlobs = "storage_gc_missing_blobs"
+	// MetricBlobsRemoved counts orphaned contents removed.
+	MetricBlobsRemoved = "storage_gc_blobs_removed_total"
+)
+
+// BlobStore defines the interface for listing and removing stored file contents.
+type BlobStore interface {
+	// Scan lists the stored contents of all users.
+	Scan(ctx context.Context) ([]filestorage.Blob, error)
+	// Delete removes stored content using the provided parameters.
+	Delete(ctx context.Context, params filestorage.DeleteParams) error
+}
+
+// MetadataRepository defines the interface for loading file metadata.
+type MetadataRepository interface {
+	// Load retrieves file metadata using the provided parameters.
+	Load(ctx context.Context, params repository.LoadParams) ([]*filedata.FileData, error)
+}
+
+// UserDirectory defines the interface for enumerating registered users.
+type UserDirectory interface {
+	// ListIDs returns identifiers of all registered users.
+	ListIDs(ctx context.Context) ([]uuid.UUID, error)
+}
+
+// MetricsRecorder defines the interface for updating operational metrics.
+type MetricsRecorder interface {
+	// Add increments the named counter by delta.
+	Add(name string, delta int64)
+	// Set assigns the named gauge to value.
+	Set(name string, value int64)
+}
+
+// Service provides file storage reconciliation operations.
+type Service struct {
+	// blobs lists and removes stored file contents.
+	blobs BlobStore
+	// metadata loads the file metadata of users.
+	metadata MetadataRepository
+	// users enumerates the registered users.
+	users UserDirectory
+	// metrics receives reconciliation counters.
+	metrics MetricsRecorder
+	// now returns the current time.
+	now func() time.Time
+	// gracePeriod is the age orphaned contents must reach before they are removed.
+	gracePeriod time.Duration
+	// cleanup determines whether scheduled runs remove orphaned contents.
+	cleanup bool
+}
+
+// NewService creates a new storage reconciliation service.
+// A non-positive grace period falls back to DefaultGracePeriod.
+func NewService(
+	blobs BlobStore,
+	metadata MetadataRepository,
+	users UserDirectory,
+	metrics MetricsRecorder,
+	gracePeriod time.Duration,
+	cleanup bool,
+) *Service {
+	if gracePeriod <= 0 {
+		gracePeriod = DefaultGracePeriod
+	}
+	return &Service{
+		blobs:       blobs,
+		metadata:    metadata,
+		users:       users,
+		metrics:     metrics,
+		now:         time.Now,
+		gracePeriod: gracePeriod,
+		cleanup:     cleanup,
+	}
+}
+
+// Detect compares stored contents with file metadata once without removing anything.
+func (s *Service) Detect(ctx context.Context) (*Report, error) {
+	return s.run(ctx, false)
+}
+
+// Reconcile compares stored contents with file metadata once and, when cleanup is enabled,
+// removes orphaned contents older than the grace period. Metadata without content is only reported.
+func (s *Service) Reconcile(ctx context.Context) (*Report, error) {
+	return s.run(ctx, s.cleanup)
+}
+
+// run performs a single reconciliation pass and updates the metrics.
+func (s *Service) run(ctx context.Context, cleanup bool) (*Report, error) {
+	report, err := s.reconcile(ctx, cleanup)
+	if err != nil {
+		s.metrics.Add(MetricRunFailures, 1)
+		return nil, errors.Join(ErrStorageGCTechError, err)
+	}
+
+	s.metrics.Add(MetricRuns, 1)
+	s.metrics.Set(MetricOrphanBlobs, int64(len(report.OrphanBlobs)))
+	s.metrics.Set(MetricMissingBlobs, int64(len(report.MissingBlobs)))
+	s.metrics.Add(MetricBlobsRemoved, int64(report.Removed))
+
+	return report, nil
+}
+
+// reconcile matches stored contents against the metadata of every user owning either of them.
+func (s *Service) reconcile(ctx context.Context, cleanup bool) (*Report, error) {
+	report := &Report{CheckedAt: s.now()}
+
+	blobs, err := s.blobs.Scan(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan file storage: %w", err)
+	}
+	report.Blobs = len(blobs)
+
+	userIDs, err := s.users.ListIDs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+
+	// stored indexes the scanned contents by owner and storage key.
+	stored := make(map[uuid.UUID]map[string]filestorage.Blob)
+	for _, b := range blobs {
+		if stored[b.UserID] == nil {
+			stored[b.UserID] = make(map[string]filestorage.Blob)
+			userIDs = append(userIDs, b.UserID)
+		}
+		stored[b.UserID][b.StorageKey] = b
+	}
+
+	// seen tracks the users already reconciled, since blob owners may repeat registered users.
+	seen := make(map[uuid.UUID]struct{}, len(userIDs))
+	for _, userID := range userIDs {
+		if _, ok := seen[userID]; ok {
+			continue
+		}
+		seen[userID] = struct{}{}
+
+		fds, err := s.metadata.Load(ctx, repository.LoadParams{UserID: userID})
+		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return nil, fmt.Errorf("reconciliation aborted: %w", ctxErr)
+			}
+			report.SkippedUsers = append(report.SkippedUsers, userID)
+			continue
+		}
+		report.Files += len(fds)
+
+		userBlobs := stored[userID]
+		for _, fd := range fds {
+			key := filestorage.BlobKey(string(fd.StorageKey))
+			if _, ok := userBlobs[key]; ok {
+				delete(userBlobs, key)
+				continue
+			}
+			report.MissingBlobs = append(report.MissingBlobs, MissingBlob{
+				FileID:     fd.ID,
+				UserID:     userID,
+				StorageKey: string(fd.StorageKey),
+			})
+		}
+		for _, b := range userBlobs {
+			report.OrphanBlobs = append(report.OrphanBlobs, s.orphan(ctx, b, cleanup, report))
+		}
+	}
+
+	return report, nil
+}
+
+// orphan reports an orphaned content and removes it when cleanup is enabled and it is past the grace period.
+// Contents younger than the grace period may belong to an upload whose metadata is not committed yet.
+func (s *Service) orphan(ctx context.Context, b filestorage.Blob, cleanup bool, report *Report) OrphanBlob {
+	o := OrphanBlob{
+		UserID:     b.UserID,
+		StorageKey: b.StorageKey,
+		Size:       b.Size,
+		ModTime:    b.ModTime,
+	}
+	if !cleanup || report.CheckedAt.Sub(b.ModTime) < s.gracePeriod {
+		return o
+	}
+	if err := s.blobs.Delete(ctx, filestorage.DeleteParams{UserID: b.UserID, StorageKey: b.StorageKey}); err != nil {
+		return o
+	}
+	o.Removed = true
+	report.Removed++
+	return o
+}
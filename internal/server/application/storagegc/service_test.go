@@ -0,0 +1,251 @@
+package storagegc
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/filedata"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/filedata"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/filestorage"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockBlobStore implements BlobStore for testing.
+type mockBlobStore struct {
+	scanErr   error
+	deleteErr error
+	blobs     []filestorage.Blob
+	deleted   []filestorage.DeleteParams
+}
+
+func (m *mockBlobStore) Scan(ctx context.Context) ([]filestorage.Blob, error) {
+	return m.blobs, m.scanErr
+}
+
+func (m *mockBlobStore) Delete(ctx context.Context, params filestorage.DeleteParams) error {
+	if m.deleteErr != nil {
+		return m.deleteErr
+	}
+	m.deleted = append(m.deleted, params)
+	return nil
+}
+
+// mockMetadata implements MetadataRepository for testing.
+type mockMetadata struct {
+	files map[uuid.UUID][]*filedata.FileData
+	errs  map[uuid.UUID]error
+}
+
+func (m *mockMetadata) Load(ctx context.Context, params repository.LoadParams) ([]*filedata.FileData, error) {
+	if err := m.errs[params.UserID]; err != nil {
+		return nil, err
+	}
+	return m.files[params.UserID], nil
+}
+
+// mockUsers implements UserDirectory for testing.
+type mockUsers struct {
+	err error
+	ids []uuid.UUID
+}
+
+func (m *mockUsers) ListIDs(ctx context.Context) ([]uuid.UUID, error) {
+	return m.ids, m.err
+}
+
+// mockMetrics implements MetricsRecorder for testing.
+type mockMetrics struct {
+	values map[string]int64
+}
+
+func (m *mockMetrics) Add(name string, delta int64) { m.values[name] += delta }
+func (m *mockMetrics) Set(name string, value int64) { m.values[name] = value }
+
+func TestService_Reconcile(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	old := now.Add(-48 * time.Hour)
+	fresh := now.Add(-time.Minute)
+	userID := uuid.New()
+	goneUserID := uuid.New()
+	brokenUserID := uuid.New()
+	fileID := uuid.New()
+	missingID := uuid.New()
+
+	tests := []struct {
+		blobs       *mockBlobStore
+		metadata    *mockMetadata
+		users       *mockUsers
+		wantMetrics map[string]int64
+		name        string
+		wantOrphans []OrphanBlob
+		wantMissing []MissingBlob
+		wantSkipped []uuid.UUID
+		wantDeleted int
+		cleanup     bool
+		wantErr     bool
+	}{
+		{
+			name: "consistent_storage",
+			blobs: &mockBlobStore{blobs: []filestorage.Blob{
+				{UserID: userID, StorageKey: "docs/a.txt", ModTime: old},
+			}},
+			metadata: &mockMetadata{files: map[uuid.UUID][]*filedata.FileData{
+				userID: {{ID: fileID, UserID: userID, StorageKey: []byte("docs/a.txt")}},
+			}},
+			users: &mockUsers{ids: []uuid.UUID{userID}},
+			wantMetrics: map[string]int64{
+				MetricRuns:         1,
+				MetricOrphanBlobs:  0,
+				MetricMissingBlobs: 0,
+				MetricBlobsRemoved: 0,
+			},
+		},
+		{
+			name: "orphans_and_missing_reported_without_cleanup",
+			blobs: &mockBlobStore{blobs: []filestorage.Blob{
+				{UserID: userID, StorageKey: "stale.bin", ModTime: old, Size: 10},
+				{UserID: goneUserID, StorageKey: "left.bin", ModTime: old, Size: 20},
+			}},
+			metadata: &mockMetadata{files: map[uuid.UUID][]*filedata.FileData{
+				userID: {{ID: missingID, UserID: userID, StorageKey: []byte("lost.txt")}},
+			}},
+			users: &mockUsers{ids: []uuid.UUID{userID}},
+			wantOrphans: []OrphanBlob{
+				{UserID: userID, StorageKey: "stale.bin", ModTime: old, Size: 10},
+				{UserID: goneUserID, StorageKey: "left.bin", ModTime: old, Size: 20},
+			},
+			wantMissing: []MissingBlob{{FileID: missingID, UserID: userID, StorageKey: "lost.txt"}},
+			wantMetrics: map[string]int64{
+				MetricRuns:         1,
+				MetricOrphanBlobs:  2,
+				MetricMissingBlobs: 1,
+				MetricBlobsRemoved: 0,
+			},
+		},
+		{
+			name: "cleanup_respects_grace_period",
+			blobs: &mockBlobStore{blobs: []filestorage.Blob{
+				{UserID: userID, StorageKey: "stale.bin", ModTime: old},
+				{UserID: userID, StorageKey: "uploading.bin", ModTime: fresh},
+			}},
+			metadata: &mockMetadata{},
+			users:    &mockUsers{ids: []uuid.UUID{userID}},
+			cleanup:  true,
+			wantOrphans: []OrphanBlob{
+				{UserID: userID, StorageKey: "stale.bin", ModTime: old, Removed: true},
+				{UserID: userID, StorageKey: "uploading.bin", ModTime: fresh},
+			},
+			wantDeleted: 1,
+			wantMetrics: map[string]int64{
+				MetricRuns:         1,
+				MetricOrphanBlobs:  2,
+				MetricMissingBlobs: 0,
+				MetricBlobsRemoved: 1,
+			},
+		},
+		{
+			name: "unreadable_metadata_skips_user",
+			blobs: &mockBlobStore{blobs: []filestorage.Blob{
+				{UserID: brokenUserID, StorageKey: "a.bin", ModTime: old},
+			}},
+			metadata:    &mockMetadata{errs: map[uuid.UUID]error{brokenUserID: errors.New("integrity violation")}},
+			users:       &mockUsers{ids: []uuid.UUID{brokenUserID}},
+			cleanup:     true,
+			wantSkipped: []uuid.UUID{brokenUserID},
+			wantMetrics: map[string]int64{
+				MetricRuns:         1,
+				MetricOrphanBlobs:  0,
+				MetricMissingBlobs: 0,
+				MetricBlobsRemoved: 0,
+			},
+		},
+		{
+			name:        "scan_error",
+			blobs:       &mockBlobStore{scanErr: errors.New("disk gone")},
+			metadata:    &mockMetadata{},
+			users:       &mockUsers{},
+			wantErr:     true,
+			wantMetrics: map[string]int64{MetricRunFailures: 1},
+		},
+		{
+			name:        "user_listing_error",
+			blobs:       &mockBlobStore{},
+			metadata:    &mockMetadata{},
+			users:       &mockUsers{err: errors.New("db down")},
+			wantErr:     true,
+			wantMetrics: map[string]int64{MetricRunFailures: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			metrics := &mockMetrics{values: map[string]int64{}}
+			s := NewService(tt.blobs, tt.metadata, tt.users, metrics, 0, tt.cleanup)
+			s.now = func() time.Time { return now }
+
+			report, err := s.Reconcile(context.Background())
+
+			assert.Equal(t, tt.wantMetrics, metrics.values)
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.ErrorIs(t, err, ErrStorageGCTechError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, now, report.CheckedAt)
+			assert.ElementsMatch(t, tt.wantOrphans, report.OrphanBlobs)
+			assert.ElementsMatch(t, tt.wantMissing, report.MissingBlobs)
+			assert.Equal(t, tt.wantSkipped, report.SkippedUsers)
+			assert.Len(t, tt.blobs.deleted, tt.wantDeleted)
+			assert.Equal(t, tt.wantDeleted, report.Removed)
+		})
+	}
+}
+
+func TestService_Detect_NeverRemoves(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	blobs := &mockBlobStore{blobs: []filestorage.Blob{
+		{UserID: userID, StorageKey: "stale.bin", ModTime: time.Now().Add(-30 * 24 * time.Hour)},
+	}}
+	s := NewService(blobs, &mockMetadata{}, &mockUsers{}, &mockMetrics{values: map[string]int64{}}, time.Hour, true)
+
+	report, err := s.Detect(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, report.OrphanBlobs, 1)
+	assert.False(t, report.OrphanBlobs[0].Removed)
+	assert.Empty(t, blobs.deleted)
+}
+
+func TestNewService_DefaultGracePeriod(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		grace time.Duration
+		want  time.Duration
+	}{
+		{name: "configured", grace: time.Hour, want: time.Hour},
+		{name: "zero_uses_default", want: DefaultGracePeriod},
+		{name: "negative_uses_default", grace: -time.Second, want: DefaultGracePeriod},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := NewService(nil, nil, nil, nil, tt.grace, false)
+			assert.Equal(t, tt.want, s.gracePeriod)
+		})
+	}
+}
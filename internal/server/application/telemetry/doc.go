@@ -0,0 +1,6 @@
+// Package telemetry provides anonymous usage ping application services for the AegisVaultKeeper server.
+//
+// This package builds the usage pings of opted-in deployments from aggregate instance usage and the
+// deployment-wide feature flags, sends them to the telemetry endpoint and previews to administrators
+// exactly what is sent.
+package telemetry
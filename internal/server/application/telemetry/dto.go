@@ -0,0 +1,27 @@
+package telemetry
+
+import "time"
+
+// Settings contains the telemetry configuration.
+type Settings struct {
+	// Endpoint contains the URL usage pings are posted to.
+	Endpoint string
+	// Interval is the period between usage pings.
+	Interval time.Duration
+	// Enabled determines whether the deployment opted in to usage pings.
+	Enabled bool
+}
+
+// Preview represents the usage ping of the deployment for application layer communication.
+type Preview struct {
+	// LastSentAt indicates when this process last sent a ping; zero if it has not.
+	LastSentAt time.Time
+	// Endpoint contains the URL pings are posted to.
+	Endpoint string
+	// Payload contains the exact JSON body of the ping.
+	Payload []byte
+	// Interval is the period between pings.
+	Interval time.Duration
+	// Enabled determines whether pings are sent.
+	Enabled bool
+}
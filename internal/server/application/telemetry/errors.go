@@ -0,0 +1,12 @@
+package telemetry
+
+import "errors"
+
+// Telemetry error definitions.
+var (
+	// ErrTelemetryDisabled indicates that usage pings are not switched on for the deployment.
+	ErrTelemetryDisabled = errors.New("telemetry is disabled")
+
+	// ErrTelemetryTechError indicates a technical error in the telemetry system.
+	ErrTelemetryTechError = errors.New("telemetry technical error")
+)
@@ -0,0 +1,151 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync"
+	"time"
+
+	featureApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/feature"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/buildinfo"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/stats"
+	statsRepository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/stats"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/telemetry"
+	"github.com/google/uuid"
+)
+
+// DefaultInterval is the period between pings when none is configured.
+const DefaultInterval = 24 * time.Hour
+
+// UsageCollector defines the interface for aggregate usage queries.
+type UsageCollector interface {
+	// Collect returns a rollup holding the aggregate usage of the instance without request counters.
+	Collect(ctx context.Context, params statsRepository.CollectParams) (*stats.Rollup, error)
+}
+
+// FeatureLister defines the interface for reading the effective feature flags.
+type FeatureLister interface {
+	// List retrieves the effective state of every known feature ordered by key.
+	List(ctx context.Context, params featureApp.ListParams) ([]*featureApp.Flag, error)
+}
+
+// Sender defines the interface for delivering encoded usage pings.
+type Sender interface {
+	// Send posts the encoded ping to the telemetry endpoint.
+	Send(ctx context.Context, body []byte) error
+}
+
+// Service provides usage ping operations.
+type Service struct {
+	// usage collects the aggregate usage of the instance.
+	usage UsageCollector
+	// features lists the deployment-wide feature flags.
+	features FeatureLister
+	// sender delivers the pings.
+	sender Sender
+	// now returns the current time.
+	now func() time.Time
+	// lastSentAt holds when this process last sent a ping.
+	lastSentAt time.Time
+	// settings contains the telemetry configuration.
+	settings Settings
+	// mu guards lastSentAt.
+	mu sync.Mutex
+}
+
+// NewService creates a new telemetry service instance. A non-positive interval uses DefaultInterval.
+func NewService(settings Settings, usage UsageCollector, features FeatureLister, sender Sender) *Service {
+	if settings.Interval <= 0 {
+		settings.Interval = DefaultInterval
+	}
+	return &Service{
+		usage:    usage,
+		features: features,
+		sender:   sender,
+		now:      time.Now,
+		settings: settings,
+	}
+}
+
+// Interval returns the period between pings, or zero when pings are switched off.
+func (s *Service) Interval() time.Duration {
+	if !s.settings.Enabled {
+		return 0
+	}
+	return s.settings.Interval
+}
+
+// Preview returns the ping that would be sent now, byte for byte, whether or not pings are switched on.
+func (s *Service) Preview(ctx context.Context) (*Preview, error) {
+	body, err := s.payload(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return &Preview{
+		LastSentAt: s.lastSentAt,
+		Endpoint:   s.settings.Endpoint,
+		Payload:    body,
+		Interval:   s.settings.Interval,
+		Enabled:    s.settings.Enabled,
+	}, nil
+}
+
+// Send builds the ping and posts it to the telemetry endpoint.
+func (s *Service) Send(ctx context.Context) error {
+	if !s.settings.Enabled {
+		return ErrTelemetryDisabled
+	}
+
+	body, err := s.payload(ctx)
+	if err != nil {
+		return err
+	}
+	if err := s.sender.Send(ctx, body); err != nil {
+		return errors.Join(ErrTelemetryTechError, err)
+	}
+
+	s.mu.Lock()
+	s.lastSentAt = s.now()
+	s.mu.Unlock()
+	return nil
+}
+
+// payload builds the encoded ping from the current usage and feature flags.
+func (s *Service) payload(ctx context.Context) ([]byte, error) {
+	r, err := s.usage.Collect(ctx, statsRepository.CollectParams{At: s.now()})
+	if err != nil {
+		return nil, errors.Join(ErrTelemetryTechError, err)
+	}
+	flags, err := s.features.List(ctx, featureApp.ListParams{UserID: uuid.Nil})
+	if err != nil {
+		return nil, errors.Join(ErrTelemetryTechError, err)
+	}
+
+	p := telemetry.Payload{
+		Items:    make(map[string]string, len(r.Items)),
+		Version:  buildinfo.Version,
+		OS:       runtime.GOOS,
+		Arch:     runtime.GOARCH,
+		Users:    telemetry.Bucket(r.Users),
+		Features: make([]string, 0, len(flags)),
+		Schema:   telemetry.SchemaVersion,
+	}
+	for item, n := range r.Items {
+		p.Items[item] = telemetry.Bucket(n)
+	}
+	for _, f := range flags {
+		if f.Enabled {
+			p.Features = append(p.Features, f.Key)
+		}
+	}
+
+	body, err := p.Encode()
+	if err != nil {
+		return nil, errors.Join(ErrTelemetryTechError, err)
+	}
+	return body, nil
+}
@@ -0,0 +1,186 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"testing"
+	"time"
+
+	featureApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/feature"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/buildinfo"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/stats"
+	statsRepository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/stats"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// errUnavailable is the error of failing dependencies.
+var errUnavailable = errors.New("unavailable")
+
+// mockUsage implements UsageCollector for testing.
+type mockUsage struct {
+	err error
+}
+
+func (m *mockUsage) Collect(context.Context, statsRepository.CollectParams) (*stats.Rollup, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &stats.Rollup{
+		Users: 42,
+		Items: map[string]int64{stats.ItemCredentials: 0, stats.ItemNotes: 7, stats.ItemFiles: 1500},
+	}, nil
+}
+
+// mockFeatures implements FeatureLister for testing.
+type mockFeatures struct {
+	err error
+}
+
+func (m *mockFeatures) List(_ context.Context, params featureApp.ListParams) ([]*featureApp.Flag, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	if params.UserID != uuid.Nil {
+		return nil, errors.New("user flags listed")
+	}
+	return []*featureApp.Flag{{Key: "beta_ui"}, {Key: "sync_v2", Enabled: true}}, nil
+}
+
+// mockSender implements Sender for testing.
+type mockSender struct {
+	err  error
+	sent [][]byte
+}
+
+func (m *mockSender) Send(_ context.Context, body []byte) error {
+	m.sent = append(m.sent, body)
+	return m.err
+}
+
+func TestService_Preview(t *testing.T) {
+	t.Parallel()
+
+	settings := Settings{Endpoint: "https://telemetry.example.com/v1/ping", Interval: 24 * time.Hour}
+	service := NewService(settings, &mockUsage{}, &mockFeatures{}, &mockSender{})
+
+	got, err := service.Preview(context.Background())
+
+	require.NoError(t, err)
+	assert.False(t, got.Enabled)
+	assert.Equal(t, settings.Endpoint, got.Endpoint)
+	assert.Equal(t, settings.Interval, got.Interval)
+	assert.True(t, got.LastSentAt.IsZero())
+	assert.JSONEq(t, `{
+		"schema":1,"version":"`+buildinfo.Version+`","os":"`+runtime.GOOS+`","arch":"`+runtime.GOARCH+`",
+		"users":"11-100","items":{"credentials":"0","notes":"1-10","files":"1001-10000"},"features":["sync_v2"]
+	}`, string(got.Payload))
+}
+
+func TestService_Interval(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		settings Settings
+		want     time.Duration
+	}{
+		{name: "disabled", settings: Settings{Interval: time.Hour}},
+		{name: "configured", settings: Settings{Interval: time.Hour, Enabled: true}, want: time.Hour},
+		{name: "default", settings: Settings{Enabled: true}, want: DefaultInterval},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, NewService(tt.settings, nil, nil, nil).Interval())
+		})
+	}
+}
+
+func TestService_Send(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	sender := &mockSender{}
+	service := NewService(Settings{Enabled: true}, &mockUsage{}, &mockFeatures{}, sender)
+	service.now = func() time.Time { return now }
+
+	require.NoError(t, service.Send(context.Background()))
+
+	preview, err := service.Preview(context.Background())
+	require.NoError(t, err)
+	require.Len(t, sender.sent, 1)
+	assert.Equal(t, preview.Payload, sender.sent[0], "the preview shows exactly what is sent")
+	assert.Equal(t, now, preview.LastSentAt)
+}
+
+func TestService_SendErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		wantErr  error
+		usage    *mockUsage
+		features *mockFeatures
+		sender   *mockSender
+		name     string
+		enabled  bool
+		wantSent int
+	}{
+		{
+			name:     "disabled",
+			wantErr:  ErrTelemetryDisabled,
+			usage:    &mockUsage{},
+			features: &mockFeatures{},
+			sender:   &mockSender{},
+		},
+		{
+			name:     "usage failure",
+			wantErr:  ErrTelemetryTechError,
+			usage:    &mockUsage{err: errUnavailable},
+			features: &mockFeatures{},
+			sender:   &mockSender{},
+			enabled:  true,
+		},
+		{
+			name:     "feature failure",
+			wantErr:  ErrTelemetryTechError,
+			usage:    &mockUsage{},
+			features: &mockFeatures{err: errUnavailable},
+			sender:   &mockSender{},
+			enabled:  true,
+		},
+		{
+			name:     "delivery failure",
+			wantErr:  ErrTelemetryTechError,
+			usage:    &mockUsage{},
+			features: &mockFeatures{},
+			sender:   &mockSender{err: errUnavailable},
+			enabled:  true,
+			wantSent: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			service := NewService(Settings{Enabled: tt.enabled}, tt.usage, tt.features, tt.sender)
+
+			err := service.Send(context.Background())
+
+			require.ErrorIs(t, err, tt.wantErr)
+			assert.Len(t, tt.sender.sent, tt.wantSent)
+			preview, err := service.Preview(context.Background())
+			if tt.usage.err != nil || tt.features.err != nil {
+				require.ErrorIs(t, err, ErrTelemetryTechError)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, preview.LastSentAt.IsZero())
+		})
+	}
+}
@@ -0,0 +1,6 @@
+// Package updatecheck provides server update check application services for the AegisVaultKeeper server.
+//
+// This package periodically downloads the signed release manifest of the vendor, verifies its signature and
+// compares the announced release with the running build, so administrators learn about available updates from
+// the health and admin endpoints and the server log.
+package updatecheck
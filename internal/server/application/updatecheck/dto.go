@@ -0,0 +1,36 @@
+package updatecheck
+
+import (
+	"crypto/ed25519"
+	"time"
+)
+
+// Settings contains the update check configuration.
+type Settings struct {
+	// PublicKey verifies the signatures of release manifests.
+	PublicKey ed25519.PublicKey
+	// Current contains the version of the running build.
+	Current string
+	// Interval is the period between checks.
+	Interval time.Duration
+}
+
+// Status represents the result of the latest update check for application layer communication.
+type Status struct {
+	// CheckedAt indicates when the release manifest was last verified; zero before the first successful check.
+	CheckedAt time.Time
+	// ReleasedAt indicates when the latest release was published.
+	ReleasedAt time.Time
+	// Current contains the version of the running build.
+	Current string
+	// Latest contains the version of the latest release; empty before the first successful check.
+	Latest string
+	// URL contains the page the latest release is downloaded from.
+	URL string
+	// Notes contains a summary of the changes in the latest release.
+	Notes string
+	// Available determines whether the latest release is newer than the running build.
+	Available bool
+	// Enabled determines whether updates are checked.
+	Enabled bool
+}
@@ -0,0 +1,15 @@
+package updatecheck
+
+import "errors"
+
+// Update check error definitions.
+var (
+	// ErrUpdateCheckDisabled indicates that no release manifest URL is configured.
+	ErrUpdateCheckDisabled = errors.New("update check is disabled")
+
+	// ErrUpdateManifestInvalid indicates that the release manifest is malformed or not signed by the vendor.
+	ErrUpdateManifestInvalid = errors.New("release manifest is invalid")
+
+	// ErrUpdateCheckTechError indicates a technical error in the update check system.
+	ErrUpdateCheckTechError = errors.New("update check technical error")
+)
@@ -0,0 +1,95 @@
+package updatecheck
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/release"
+)
+
+// DefaultInterval is the period between checks when none is configured.
+const DefaultInterval = 24 * time.Hour
+
+// Fetcher defines the interface for downloading the release manifest.
+type Fetcher interface {
+	// Fetch downloads the signed release manifest.
+	Fetch(ctx context.Context) (string, error)
+}
+
+// Service provides update check operations.
+type Service struct {
+	// fetcher downloads the release manifest; nil disables update checks.
+	fetcher Fetcher
+	// now returns the current time.
+	now func() time.Time
+	// latest holds the release announced by the last verified manifest; nil before the first one.
+	latest *release.Manifest
+	// checkedAt holds when the last manifest was verified.
+	checkedAt time.Time
+	// settings contains the update check configuration.
+	settings Settings
+	// mu guards latest and checkedAt.
+	mu sync.RWMutex
+}
+
+// NewService creates a new update check service instance. The fetcher may be nil when no release manifest URL
+// is configured. A non-positive interval uses DefaultInterval.
+func NewService(settings Settings, fetcher Fetcher) *Service {
+	if settings.Interval <= 0 {
+		settings.Interval = DefaultInterval
+	}
+	return &Service{fetcher: fetcher, now: time.Now, settings: settings}
+}
+
+// Interval returns the period between checks, or zero when update checks are switched off.
+func (s *Service) Interval() time.Duration {
+	if s.fetcher == nil {
+		return 0
+	}
+	return s.settings.Interval
+}
+
+// Check downloads and verifies the release manifest and compares the announced release with the running build.
+// A failed check keeps the result of the previous one.
+func (s *Service) Check(ctx context.Context) (*Status, error) {
+	if s.fetcher == nil {
+		return nil, ErrUpdateCheckDisabled
+	}
+
+	manifest, err := s.fetcher.Fetch(ctx)
+	if err != nil {
+		return nil, errors.Join(ErrUpdateCheckTechError, err)
+	}
+	latest, err := release.Parse(manifest, s.settings.PublicKey)
+	if err != nil {
+		return nil, errors.Join(ErrUpdateManifestInvalid, err)
+	}
+
+	s.mu.Lock()
+	s.latest = latest
+	s.checkedAt = s.now()
+	s.mu.Unlock()
+	return s.Status(ctx)
+}
+
+// Status reports the result of the latest successful check.
+func (s *Service) Status(context.Context) (*Status, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	status := &Status{
+		CheckedAt: s.checkedAt,
+		Current:   s.settings.Current,
+		Enabled:   s.fetcher != nil,
+	}
+	if s.latest != nil {
+		status.ReleasedAt = s.latest.ReleasedAt
+		status.Latest = s.latest.Version
+		status.URL = s.latest.URL
+		status.Notes = s.latest.Notes
+		status.Available = release.Newer(s.latest.Version, s.settings.Current)
+	}
+	return status, nil
+}
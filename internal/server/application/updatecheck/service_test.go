@@ -0,0 +1,168 @@
+package updatecheck
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/release"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockFetcher implements Fetcher for testing.
+type mockFetcher struct {
+	err      error
+	manifest string
+}
+
+func (m *mockFetcher) Fetch(context.Context) (string, error) {
+	return m.manifest, m.err
+}
+
+// signManifest signs a release manifest of the version for testing.
+func signManifest(t *testing.T, privateKey ed25519.PrivateKey, version string) string {
+	t.Helper()
+
+	manifest, err := release.Sign(&release.Manifest{
+		ReleasedAt: time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC),
+		Version:    version,
+		URL:        "https://example.com/releases/" + version,
+		Notes:      "Bug fixes",
+	}, privateKey)
+	require.NoError(t, err)
+	return manifest
+}
+
+func TestService_Check(t *testing.T) {
+	t.Parallel()
+
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	_, otherKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		fetcher       *mockFetcher
+		wantErr       error
+		name          string
+		current       string
+		wantAvailable bool
+	}{
+		{
+			name:          "update available",
+			fetcher:       &mockFetcher{manifest: signManifest(t, privateKey, "v1.5.0")},
+			current:       "v1.4.2",
+			wantAvailable: true,
+		},
+		{
+			name:    "up to date",
+			fetcher: &mockFetcher{manifest: signManifest(t, privateKey, "v1.5.0")},
+			current: "1.5.0",
+		},
+		{
+			name:    "development build",
+			fetcher: &mockFetcher{manifest: signManifest(t, privateKey, "v1.5.0")},
+			current: "N/A",
+		},
+		{
+			name:    "foreign signature",
+			fetcher: &mockFetcher{manifest: signManifest(t, otherKey, "v9.0.0")},
+			current: "v1.4.2",
+			wantErr: ErrUpdateManifestInvalid,
+		},
+		{
+			name:    "manifest host unavailable",
+			fetcher: &mockFetcher{err: errors.New("connection refused")},
+			current: "v1.4.2",
+			wantErr: ErrUpdateCheckTechError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			service := NewService(Settings{PublicKey: publicKey, Current: tt.current}, tt.fetcher)
+			service.now = func() time.Time { return now }
+
+			got, err := service.Check(context.Background())
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				status, err := service.Status(context.Background())
+				require.NoError(t, err)
+				assert.Empty(t, status.Latest, "unverified releases are never reported")
+				assert.False(t, status.Available)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, now, got.CheckedAt)
+			assert.Equal(t, "v1.5.0", got.Latest)
+			assert.Equal(t, tt.current, got.Current)
+			assert.Equal(t, "https://example.com/releases/v1.5.0", got.URL)
+			assert.Equal(t, tt.wantAvailable, got.Available)
+			assert.True(t, got.Enabled)
+		})
+	}
+}
+
+func TestService_CheckKeepsPreviousResult(t *testing.T) {
+	t.Parallel()
+
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	fetcher := &mockFetcher{manifest: signManifest(t, privateKey, "v1.5.0")}
+	service := NewService(Settings{PublicKey: publicKey, Current: "v1.4.0"}, fetcher)
+
+	_, err = service.Check(context.Background())
+	require.NoError(t, err)
+	fetcher.err = errors.New("timeout")
+	_, err = service.Check(context.Background())
+	require.ErrorIs(t, err, ErrUpdateCheckTechError)
+
+	status, err := service.Status(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "v1.5.0", status.Latest)
+	assert.True(t, status.Available)
+}
+
+func TestService_Disabled(t *testing.T) {
+	t.Parallel()
+
+	service := NewService(Settings{Current: "v1.4.0", Interval: time.Hour}, nil)
+
+	_, err := service.Check(context.Background())
+	require.ErrorIs(t, err, ErrUpdateCheckDisabled)
+	assert.Zero(t, service.Interval())
+	status, err := service.Status(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, &Status{Current: "v1.4.0"}, status)
+}
+
+func TestService_Interval(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		interval time.Duration
+		want     time.Duration
+	}{
+		{name: "configured", interval: time.Hour, want: time.Hour},
+		{name: "default", want: DefaultInterval},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			service := NewService(Settings{Interval: tt.interval}, &mockFetcher{})
+
+			assert.Equal(t, tt.want, service.Interval())
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package vaulthealth
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/bankcard"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/credential"
+)
+
+// Password weakness reasons reported for credentials.
+const (
+	// WeaknessTooShort indicates a password shorter than passwordMinLen characters.
+	WeaknessTooShort = "too_short"
+	// WeaknessLowVariety indicates a password using fewer than passwordMinClasses character classes.
+	WeaknessLowVariety = "low_variety"
+	// WeaknessCommon indicates a widely used password.
+	WeaknessCommon = "common"
+	// WeaknessReused indicates a password shared with another credential of the same user.
+	WeaknessReused = "reused"
+)
+
+const (
+	// passwordMinLen is the minimum length of a strong password.
+	passwordMinLen = 12
+	// passwordMinClasses is the minimum number of character classes of a strong password.
+	passwordMinClasses = 3
+	// expiryWarningWindow is how long before expiry a bank card is reported.
+	expiryWarningWindow = 60 * 24 * time.Hour
+)
+
+// commonPasswords lists widely used passwords rejected regardless of their length.
+var commonPasswords = []string{
+	"123456", "12345678", "123456789", "1234567890", "password", "password1", "password123",
+	"qwerty", "qwerty123", "qwertyuiop", "111111", "000000", "abc123", "iloveyou", "admin",
+	"letmein", "welcome", "monkey", "dragon", "football", "baseball", "sunshine", "princess",
+	"passw0rd", "p@ssw0rd", "1q2w3e4r", "zaq12wsx", "trustno1", "superman", "master",
+}
+
+// findWeakCredentials analyzes credential passwords and returns the weak or reused ones. Archived credentials
+// are left out, so they are neither reported nor count as reusing a password.
+func findWeakCredentials(creds []*credential.Credential) []WeakCredential {
+	creds = slices.DeleteFunc(slices.Clone(creds), func(c *credential.Credential) bool { return c.Archived })
+	uses := make(map[string]int, len(creds))
+	for _, c := range creds {
+		uses[c.Password]++
+	}
+
+	var weak []WeakCredential
+	for _, c := range creds {
+		reasons := passwordWeaknesses(c.Password)
+		if uses[c.Password] > 1 {
+			reasons = append(reasons, WeaknessReused)
+		}
+		if len(reasons) > 0 {
+			weak = append(weak, WeakCredential{ID: c.ID, Reasons: reasons})
+		}
+	}
+	return weak
+}
+
+// passwordWeaknesses returns the strength rules the password violates.
+func passwordWeaknesses(password string) []string {
+	var reasons []string
+	if utf8.RuneCountInString(password) < passwordMinLen {
+		reasons = append(reasons, WeaknessTooShort)
+	}
+	if characterClasses(password) < passwordMinClasses {
+		reasons = append(reasons, WeaknessLowVariety)
+	}
+	if slices.Contains(commonPasswords, strings.ToLower(password)) {
+		reasons = append(reasons, WeaknessCommon)
+	}
+	return reasons
+}
+
+// characterClasses counts the lower case, upper case, digit and other character classes used in s.
+func characterClasses(s string) int {
+	var lower, upper, digit, other bool
+	for _, r := range s {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			other = true
+		}
+	}
+
+	n := 0
+	for _, used := range []bool{lower, upper, digit, other} {
+		if used {
+			n++
+		}
+	}
+	return n
+}
+
+// findExpiringCards returns the bank cards that expired or expire within the warning window.
+// Cards with an unparsable expiry date are skipped.
+func findExpiringCards(cards []*bankcard.BankCard, now time.Time) []ExpiringCard {
+	var expiring []ExpiringCard
+	for _, c := range cards {
+		expiresAt, ok := cardExpiry(c.ExpiryMonth, c.ExpiryYear)
+		if !ok || expiresAt.Sub(now) > expiryWarningWindow {
+			continue
+		}
+		expiring = append(expiring, ExpiringCard{
+			ID:        c.ID,
+			ExpiresAt: expiresAt,
+			Expired:   !now.Before(expiresAt),
+		})
+	}
+	return expiring
+}
+
+// cardExpiry returns the first moment after the card's expiry month.
+func cardExpiry(month, year string) (time.Time, bool) {
+	m, err := strconv.Atoi(month)
+	if err != nil || m < 1 || m > 12 {
+		return time.Time{}, false
+	}
+	y, err := strconv.Atoi(year)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Date(y, time.Month(m)+1, 1, 0, 0, 0, 0, time.UTC), true
+}
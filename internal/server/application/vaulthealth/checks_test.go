@@ -0,0 +1,113 @@
+package vaulthealth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/bankcard"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/credential"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPasswordWeaknesses(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		password string
+		want     []string
+	}{
+		{name: "strong", password: "Correct-Horse-42", want: nil},
+		{name: "too_short", password: "Ab1!", want: []string{WeaknessTooShort}},
+		{name: "low_variety", password: "alllowercaseletters", want: []string{WeaknessLowVariety}},
+		{
+			name:     "common",
+			password: "Password123",
+			want:     []string{WeaknessTooShort, WeaknessCommon},
+		},
+		{
+			name:     "all_rules",
+			password: "qwerty",
+			want:     []string{WeaknessTooShort, WeaknessLowVariety, WeaknessCommon},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, passwordWeaknesses(tt.password))
+		})
+	}
+}
+
+func TestFindWeakCredentials(t *testing.T) {
+	t.Parallel()
+
+	strong := &credential.Credential{ID: uuid.New(), Password: "Correct-Horse-42"}
+	reusedA := &credential.Credential{ID: uuid.New(), Password: "Battery-Staple-7"}
+	reusedB := &credential.Credential{ID: uuid.New(), Password: "Battery-Staple-7"}
+	weak := &credential.Credential{ID: uuid.New(), Password: "short"}
+
+	got := findWeakCredentials([]*credential.Credential{strong, reusedA, reusedB, weak})
+
+	assert.Equal(t, []WeakCredential{
+		{ID: reusedA.ID, Reasons: []string{WeaknessReused}},
+		{ID: reusedB.ID, Reasons: []string{WeaknessReused}},
+		{ID: weak.ID, Reasons: []string{WeaknessTooShort, WeaknessLowVariety}},
+	}, got)
+}
+
+func TestFindWeakCredentials_SkipsArchived(t *testing.T) {
+	t.Parallel()
+
+	active := &credential.Credential{ID: uuid.New(), Password: "Battery-Staple-7"}
+	rotated := &credential.Credential{ID: uuid.New(), Password: "Battery-Staple-7", Archived: true}
+	old := &credential.Credential{ID: uuid.New(), Password: "short", Archived: true}
+
+	assert.Empty(t, findWeakCredentials([]*credential.Credential{active, rotated, old}))
+}
+
+func TestFindExpiringCards(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, time.March, 15, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		month string
+		year  string
+		want  []ExpiringCard
+	}{
+		{name: "far_future", month: "12", year: "2030"},
+		{
+			name:  "expires_soon",
+			month: "04",
+			year:  "2025",
+			want:  []ExpiringCard{{ExpiresAt: time.Date(2025, time.May, 1, 0, 0, 0, 0, time.UTC)}},
+		},
+		{
+			name:  "expired",
+			month: "02",
+			year:  "2025",
+			want: []ExpiringCard{
+				{ExpiresAt: time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC), Expired: true},
+			},
+		},
+		{name: "unparsable_month", month: "13", year: "2025"},
+		{name: "unparsable_year", month: "01", year: "20x5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			card := &bankcard.BankCard{ID: uuid.New(), ExpiryMonth: tt.month, ExpiryYear: tt.year}
+			for i := range tt.want {
+				tt.want[i].ID = card.ID
+			}
+
+			assert.Equal(t, tt.want, findExpiringCards([]*bankcard.BankCard{card}, now))
+		})
+	}
+}
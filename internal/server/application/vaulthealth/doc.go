@@ -0,0 +1,6 @@
+// Package vaulthealth provides vault health report application services for the AegisVaultKeeper server.
+//
+// This package checks that stored items of a user still decrypt, calculates storage usage,
+// detects expiring bank cards and weak or reused passwords, and delivers the resulting
+// report on request or as a reminder notification from a periodic job.
+package vaulthealth
@@ -0,0 +1,131 @@
+package vaulthealth
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Item categories covered by decryption checks.
+const (
+	// CategoryCredentials identifies credential items.
+	CategoryCredentials = "credentials"
+	// CategoryBankCards identifies bank card items.
+	CategoryBankCards = "bankcards"
+	// CategoryNotes identifies note items.
+	CategoryNotes = "notes"
+	// CategoryFiles identifies file items.
+	CategoryFiles = "files"
+)
+
+// Report summarizes the health of a user's vault.
+type Report struct {
+	// GeneratedAt indicates when the report was produced.
+	GeneratedAt time.Time
+	// DecryptionChecks lists the decryption spot-check result per item category.
+	DecryptionChecks []DecryptionCheck
+	// ExpiringCards lists bank cards that expired or expire soon.
+	ExpiringCards []ExpiringCard
+	// WeakCredentials lists credentials whose passwords are weak or reused.
+	WeakCredentials []WeakCredential
+	// Usage contains the vault storage usage.
+	Usage Usage
+	// UserID identifies the vault owner.
+	UserID uuid.UUID
+}
+
+// DecryptionCheck contains the decryption spot-check result of an item category.
+type DecryptionCheck struct {
+	// Category identifies the checked item category.
+	Category string
+	// Checked is the number of items decrypted successfully.
+	Checked int
+	// Passed reports whether all checked items decrypted and verified successfully.
+	Passed bool
+}
+
+// ExpiringCard describes a bank card that expired or expires within the warning window.
+type ExpiringCard struct {
+	// ExpiresAt is the first moment the card is no longer valid.
+	ExpiresAt time.Time
+	// ID identifies the bank card.
+	ID uuid.UUID
+	// Expired reports whether the card is already expired.
+	Expired bool
+}
+
+// WeakCredential describes a credential whose password failed the strength analysis.
+type WeakCredential struct {
+	// Reasons lists the detected password weaknesses.
+	Reasons []string
+	// ID identifies the credential.
+	ID uuid.UUID
+}
+
+// Usage contains item counts and the storage occupied by a user's vault.
+type Usage struct {
+	// FileBytes is the storage occupied by encrypted file contents.
+	FileBytes int64
+	// Credentials is the number of stored credentials.
+	Credentials int
+	// BankCards is the number of stored bank cards.
+	BankCards int
+	// Notes is the number of stored notes.
+	Notes int
+	// Files is the number of stored files.
+	Files int
+}
+
+// HasFindings reports whether the report contains anything requiring the user's attention.
+func (r *Report) HasFindings() bool {
+	if len(r.ExpiringCards) > 0 || len(r.WeakCredentials) > 0 {
+		return true
+	}
+	for _, c := range r.DecryptionChecks {
+		if !c.Passed {
+			return true
+		}
+	}
+	return false
+}
+
+// Text renders the report as plain text suitable for notification delivery.
+// The rendering contains item identifiers only and never includes item contents.
+func (r *Report) Text() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Vault health report generated at %s\n\n", r.GeneratedAt.UTC().Format(time.RFC3339))
+
+	b.WriteString("Decryption checks:\n")
+	for _, c := range r.DecryptionChecks {
+		status := "ok"
+		if !c.Passed {
+			status = "FAILED"
+		}
+		fmt.Fprintf(&b, "  %s: %s (%d checked)\n", c.Category, status, c.Checked)
+	}
+
+	fmt.Fprintf(&b, "\nUsage: %d credentials, %d bank cards, %d notes, %d files (%d bytes)\n",
+		r.Usage.Credentials, r.Usage.BankCards, r.Usage.Notes, r.Usage.Files, r.Usage.FileBytes)
+
+	if len(r.ExpiringCards) > 0 {
+		b.WriteString("\nExpiring bank cards:\n")
+		for _, c := range r.ExpiringCards {
+			state := "expires"
+			if c.Expired {
+				state = "expired"
+			}
+			fmt.Fprintf(&b, "  %s: %s %s\n", c.ID, state, c.ExpiresAt.Format("2006-01-02"))
+		}
+	}
+
+	if len(r.WeakCredentials) > 0 {
+		b.WriteString("\nWeak credentials:\n")
+		for _, c := range r.WeakCredentials {
+			fmt.Fprintf(&b, "  %s: %s\n", c.ID, strings.Join(c.Reasons, ", "))
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,75 @@
+package vaulthealth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReport_HasFindings(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		report *Report
+		name   string
+		want   bool
+	}{
+		{
+			name:   "clean",
+			report: &Report{DecryptionChecks: []DecryptionCheck{{Category: CategoryNotes, Passed: true}}},
+			want:   false,
+		},
+		{
+			name:   "failed_decryption",
+			report: &Report{DecryptionChecks: []DecryptionCheck{{Category: CategoryNotes}}},
+			want:   true,
+		},
+		{
+			name:   "expiring_card",
+			report: &Report{ExpiringCards: []ExpiringCard{{ID: uuid.New()}}},
+			want:   true,
+		},
+		{
+			name:   "weak_credential",
+			report: &Report{WeakCredentials: []WeakCredential{{ID: uuid.New()}}},
+			want:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, tt.report.HasFindings())
+		})
+	}
+}
+
+func TestReport_Text(t *testing.T) {
+	t.Parallel()
+
+	cardID := uuid.New()
+	credID := uuid.New()
+	report := &Report{
+		GeneratedAt: time.Date(2025, time.March, 15, 10, 0, 0, 0, time.UTC),
+		DecryptionChecks: []DecryptionCheck{
+			{Category: CategoryNotes, Checked: 2, Passed: true},
+			{Category: CategoryFiles, Checked: 1},
+		},
+		ExpiringCards: []ExpiringCard{
+			{ID: cardID, ExpiresAt: time.Date(2025, time.April, 1, 0, 0, 0, 0, time.UTC)},
+		},
+		WeakCredentials: []WeakCredential{{ID: credID, Reasons: []string{WeaknessTooShort, WeaknessReused}}},
+		Usage:           Usage{Notes: 2, Files: 1, FileBytes: 512},
+	}
+
+	text := report.Text()
+
+	assert.Contains(t, text, "generated at 2025-03-15T10:00:00Z")
+	assert.Contains(t, text, "notes: ok (2 checked)")
+	assert.Contains(t, text, "files: FAILED (1 checked)")
+	assert.Contains(t, text, "2 notes, 1 files (512 bytes)")
+	assert.Contains(t, text, cardID.String()+": expires 2025-04-01")
+	assert.Contains(t, text, credID.String()+": too_short, reused")
+}
@@ -0,0 +1,235 @@
+package vaulthealth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/bankcard"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/credential"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/filedata"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/note"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/notification"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/crypto"
+	domainNotification "github.com/gdyunin/aegis-vault-keeper/internal/server/domain/notification"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/fieldcrypt"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/filestorage"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/rowsign"
+	"github.com/google/uuid"
+)
+
+// fileSampleSize is the maximum number of files fully decrypted by a single spot-check.
+const fileSampleSize = 3
+
+// reportSubject is the subject of vault health report notifications.
+const reportSubject = "AegisVaultKeeper vault health report"
+
+// CredentialService defines the interface for listing user credentials.
+type CredentialService interface {
+	// List retrieves all credentials belonging to the specified user.
+	List(ctx context.Context, params credential.ListParams) ([]*credential.Credential, error)
+}
+
+// BankCardService defines the interface for listing user bank cards.
+type BankCardService interface {
+	// List retrieves all bank cards belonging to the specified user.
+	List(ctx context.Context, params bankcard.ListParams) ([]*bankcard.BankCard, error)
+}
+
+// NoteService defines the interface for listing user notes.
+type NoteService interface {
+	// List retrieves all notes belonging to the specified user.
+	List(ctx context.Context, params note.ListParams) ([]*note.Note, error)
+}
+
+// FileDataService defines the interface for listing and retrieving user files.
+type FileDataService interface {
+	// List retrieves metadata of all files belonging to the specified user.
+	List(ctx context.Context, params filedata.ListParams) ([]*filedata.FileData, error)
+	// Pull retrieves a specific file's metadata and content.
+	Pull(ctx context.Context, params filedata.PullParams) (*filedata.FileData, error)
+}
+
+// StorageUsageMeter defines the interface for measuring user file storage usage.
+type StorageUsageMeter interface {
+	// Usage returns the number of bytes stored for the specified user.
+	Usage(ctx context.Context, params filestorage.UsageParams) (int64, error)
+}
+
+// UserDirectory defines the interface for enumerating registered users.
+type UserDirectory interface {
+	// ListIDs returns identifiers of all registered users.
+	ListIDs(ctx context.Context) ([]uuid.UUID, error)
+}
+
+// Notifier defines the interface for delivering notifications to users over their channels.
+type Notifier interface {
+	// Notify delivers a notification to the channels of the user subscribed to its category.
+	Notify(ctx context.Context, params notification.NotifyParams) error
+}
+
+// Service provides vault health report operations.
+type Service struct {
+	// users enumerates report recipients.
+	users UserDirectory
+	// credentials lists user credentials.
+	credentials CredentialService
+	// bankcards lists user bank cards.
+	bankcards BankCardService
+	// notes lists user notes.
+	notes NoteService
+	// files lists and retrieves user files.
+	files FileDataService
+	// storage measures user file storage usage.
+	storage StorageUsageMeter
+	// notifier delivers scheduled reports; nil disables delivery.
+	notifier Notifier
+}
+
+// NewService creates a new vault health service instance with the provided dependencies.
+// A nil notifier disables delivery of scheduled reports.
+func NewService(
+	users UserDirectory,
+	credentials CredentialService,
+	bankcards BankCardService,
+	notes NoteService,
+	files FileDataService,
+	storage StorageUsageMeter,
+	notifier Notifier,
+) *Service {
+	return &Service{
+		users:       users,
+		credentials: credentials,
+		bankcards:   bankcards,
+		notes:       notes,
+		files:       files,
+		storage:     storage,
+		notifier:    notifier,
+	}
+}
+
+// Generate produces the vault health report of the specified user.
+func (s *Service) Generate(ctx context.Context, userID uuid.UUID) (*Report, error) {
+	report := &Report{UserID: userID, GeneratedAt: time.Now()}
+
+	creds, err := s.credentials.List(ctx, credential.ListParams{UserID: userID})
+	if err := s.addCheck(report, CategoryCredentials, len(creds), err); err != nil {
+		return nil, err
+	}
+	cards, err := s.bankcards.List(ctx, bankcard.ListParams{UserID: userID})
+	if err := s.addCheck(report, CategoryBankCards, len(cards), err); err != nil {
+		return nil, err
+	}
+	notes, err := s.notes.List(ctx, note.ListParams{UserID: userID})
+	if err := s.addCheck(report, CategoryNotes, len(notes), err); err != nil {
+		return nil, err
+	}
+	files, err := s.files.List(ctx, filedata.ListParams{UserID: userID})
+	if err != nil && !isIntegrityError(err) {
+		return nil, fmt.Errorf("failed to list %s: %w", CategoryFiles, err)
+	}
+	if err != nil {
+		report.DecryptionChecks = append(report.DecryptionChecks, DecryptionCheck{Category: CategoryFiles})
+	} else if err := s.spotCheckFiles(ctx, report, files); err != nil {
+		return nil, err
+	}
+
+	fileBytes, err := s.storage.Usage(ctx, filestorage.UsageParams{UserID: userID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate storage usage: %w", err)
+	}
+	report.Usage = Usage{
+		Credentials: len(creds),
+		BankCards:   len(cards),
+		Notes:       len(notes),
+		Files:       len(files),
+		FileBytes:   fileBytes,
+	}
+
+	report.ExpiringCards = findExpiringCards(cards, report.GeneratedAt)
+	report.WeakCredentials = findWeakCredentials(creds)
+
+	return report, nil
+}
+
+// RunScheduled generates reports for all users and sends those with findings as reminders.
+// Reports are delivered only when a notifier is configured and the user has a channel for reminders.
+// A failure for one user does not stop processing of the remaining users.
+func (s *Service) RunScheduled(ctx context.Context) error {
+	ids, err := s.users.ListIDs(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list users: %w", err)
+	}
+
+	var errs []error
+	for _, id := range ids {
+		if err := s.runForUser(ctx, id); err != nil {
+			errs = append(errs, fmt.Errorf("user %s: %w", id, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// runForUser generates the report of a single user and delivers it if required.
+func (s *Service) runForUser(ctx context.Context, userID uuid.UUID) error {
+	report, err := s.Generate(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to generate report: %w", err)
+	}
+	if s.notifier == nil || !report.HasFindings() {
+		return nil
+	}
+
+	err = s.notifier.Notify(ctx, notification.NotifyParams{
+		Category: domainNotification.CategoryReminders,
+		Subject:  reportSubject,
+		Body:     report.Text(),
+		UserID:   userID,
+	})
+	if err != nil && !errors.Is(err, notification.ErrNotificationUnreachable) {
+		return fmt.Errorf("failed to send report: %w", err)
+	}
+	return nil
+}
+
+// addCheck records the decryption check result of a listed category.
+// Integrity failures mark the check as failed; any other error is returned.
+func (s *Service) addCheck(report *Report, category string, checked int, err error) error {
+	if err != nil && !isIntegrityError(err) {
+		return fmt.Errorf("failed to list %s: %w", category, err)
+	}
+	check := DecryptionCheck{Category: category, Passed: err == nil}
+	if err == nil {
+		check.Checked = checked
+	}
+	report.DecryptionChecks = append(report.DecryptionChecks, check)
+	return nil
+}
+
+// spotCheckFiles decrypts a random sample of files and records the result.
+func (s *Service) spotCheckFiles(ctx context.Context, report *Report, files []*filedata.FileData) error {
+	check := DecryptionCheck{Category: CategoryFiles, Passed: true}
+	perm := rand.Perm(len(files))
+	for _, i := range perm[:min(fileSampleSize, len(perm))] {
+		_, err := s.files.Pull(ctx, filedata.PullParams{ID: files[i].ID, UserID: report.UserID})
+		if err != nil {
+			if !isIntegrityError(err) {
+				return fmt.Errorf("failed to pull file: %w", err)
+			}
+			check.Passed = false
+			continue
+		}
+		check.Checked++
+	}
+	report.DecryptionChecks = append(report.DecryptionChecks, check)
+	return nil
+}
+
+// isIntegrityError reports whether err indicates tampered or undecryptable stored data.
+func isIntegrityError(err error) bool {
+	return errors.Is(err, fieldcrypt.ErrIntegrityViolation) ||
+		errors.Is(err, rowsign.ErrSignatureMismatch) ||
+		errors.Is(err, crypto.ErrIntegrityCheckFailed)
+}
@@ -0,0 +1,292 @@
+package vaulthealth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/bankcard"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/credential"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/filedata"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/note"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/notification"
+	domainNotification "github.com/gdyunin/aegis-vault-keeper/internal/server/domain/notification"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/fieldcrypt"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/filestorage"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockCredentialService implements CredentialService for testing.
+type mockCredentialService struct {
+	err   error
+	items []*credential.Credential
+}
+
+func (m *mockCredentialService) List(
+	ctx context.Context,
+	params credential.ListParams,
+) ([]*credential.Credential, error) {
+	return m.items, m.err
+}
+
+// mockBankCardService implements BankCardService for testing.
+type mockBankCardService struct {
+	err   error
+	items []*bankcard.BankCard
+}
+
+func (m *mockBankCardService) List(
+	ctx context.Context,
+	params bankcard.ListParams,
+) ([]*bankcard.BankCard, error) {
+	return m.items, m.err
+}
+
+// mockNoteService implements NoteService for testing.
+type mockNoteService struct {
+	err   error
+	items []*note.Note
+}
+
+func (m *mockNoteService) List(ctx context.Context, params note.ListParams) ([]*note.Note, error) {
+	return m.items, m.err
+}
+
+// mockFileDataService implements FileDataService for testing.
+type mockFileDataService struct {
+	listErr error
+	pullErr error
+	items   []*filedata.FileData
+	pulled  int
+}
+
+func (m *mockFileDataService) List(
+	ctx context.Context,
+	params filedata.ListParams,
+) ([]*filedata.FileData, error) {
+	return m.items, m.listErr
+}
+
+func (m *mockFileDataService) Pull(
+	ctx context.Context,
+	params filedata.PullParams,
+) (*filedata.FileData, error) {
+	m.pulled++
+	return &filedata.FileData{ID: params.ID}, m.pullErr
+}
+
+// mockStorageUsageMeter implements StorageUsageMeter for testing.
+type mockStorageUsageMeter struct {
+	err   error
+	bytes int64
+}
+
+func (m *mockStorageUsageMeter) Usage(ctx context.Context, params filestorage.UsageParams) (int64, error) {
+	return m.bytes, m.err
+}
+
+// mockUserDirectory implements UserDirectory for testing.
+type mockUserDirectory struct {
+	listErr error
+	ids     []uuid.UUID
+}
+
+func (m *mockUserDirectory) ListIDs(ctx context.Context) ([]uuid.UUID, error) {
+	return m.ids, m.listErr
+}
+
+// mockNotifier implements Notifier for testing.
+type mockNotifier struct {
+	err         error
+	unreachable map[uuid.UUID]bool
+	sent        []uuid.UUID
+}
+
+func (m *mockNotifier) Notify(ctx context.Context, params notification.NotifyParams) error {
+	if m.unreachable[params.UserID] {
+		return notification.ErrNotificationUnreachable
+	}
+	if params.Category != domainNotification.CategoryReminders {
+		return errors.New("unexpected category")
+	}
+	m.sent = append(m.sent, params.UserID)
+	return m.err
+}
+
+// integrityErr mimics an application error wrapping a field integrity violation.
+var integrityErr = fmt.Errorf("failed to load: %w", fieldcrypt.ErrIntegrityViolation)
+
+func TestService_Generate(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	techErr := errors.New("db down")
+
+	tests := []struct {
+		creds       *mockCredentialService
+		notes       *mockNoteService
+		files       *mockFileDataService
+		storage     *mockStorageUsageMeter
+		name        string
+		wantChecks  []DecryptionCheck
+		wantUsage   Usage
+		wantPulled  int
+		expectError bool
+	}{
+		{
+			name: "healthy_vault",
+			creds: &mockCredentialService{items: []*credential.Credential{
+				{ID: uuid.New(), Password: "Correct-Horse-42"},
+			}},
+			notes: &mockNoteService{items: []*note.Note{{ID: uuid.New()}, {ID: uuid.New()}}},
+			files: &mockFileDataService{items: []*filedata.FileData{
+				{ID: uuid.New()}, {ID: uuid.New()}, {ID: uuid.New()}, {ID: uuid.New()},
+			}},
+			storage: &mockStorageUsageMeter{bytes: 2048},
+			wantChecks: []DecryptionCheck{
+				{Category: CategoryCredentials, Checked: 1, Passed: true},
+				{Category: CategoryBankCards, Checked: 0, Passed: true},
+				{Category: CategoryNotes, Checked: 2, Passed: true},
+				{Category: CategoryFiles, Checked: fileSampleSize, Passed: true},
+			},
+			wantUsage:  Usage{Credentials: 1, Notes: 2, Files: 4, FileBytes: 2048},
+			wantPulled: fileSampleSize,
+		},
+		{
+			name:    "integrity_violations",
+			creds:   &mockCredentialService{err: integrityErr},
+			notes:   &mockNoteService{},
+			files:   &mockFileDataService{items: []*filedata.FileData{{ID: uuid.New()}}, pullErr: integrityErr},
+			storage: &mockStorageUsageMeter{},
+			wantChecks: []DecryptionCheck{
+				{Category: CategoryCredentials, Passed: false},
+				{Category: CategoryBankCards, Passed: true},
+				{Category: CategoryNotes, Passed: true},
+				{Category: CategoryFiles, Passed: false},
+			},
+			wantUsage:  Usage{Files: 1},
+			wantPulled: 1,
+		},
+		{
+			name:        "technical_error",
+			creds:       &mockCredentialService{},
+			notes:       &mockNoteService{err: techErr},
+			files:       &mockFileDataService{},
+			storage:     &mockStorageUsageMeter{},
+			expectError: true,
+		},
+		{
+			name:        "storage_usage_error",
+			creds:       &mockCredentialService{},
+			notes:       &mockNoteService{},
+			files:       &mockFileDataService{},
+			storage:     &mockStorageUsageMeter{err: techErr},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := NewService(nil, tt.creds, &mockBankCardService{}, tt.notes, tt.files, tt.storage, nil)
+
+			report, err := s.Generate(context.Background(), userID)
+
+			if tt.expectError {
+				require.Error(t, err)
+				assert.Nil(t, report)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, userID, report.UserID)
+			assert.Equal(t, tt.wantChecks, report.DecryptionChecks)
+			assert.Equal(t, tt.wantUsage, report.Usage)
+			assert.Equal(t, tt.wantPulled, tt.files.pulled)
+		})
+	}
+}
+
+func TestService_RunScheduled(t *testing.T) {
+	t.Parallel()
+
+	reachableUser := uuid.New()
+	unreachableUser := uuid.New()
+	users := &mockUserDirectory{ids: []uuid.UUID{reachableUser, unreachableUser}}
+	unreachable := map[uuid.UUID]bool{unreachableUser: true}
+	weakCreds := &mockCredentialService{items: []*credential.Credential{{ID: uuid.New(), Password: "qwerty"}}}
+
+	tests := []struct {
+		users       *mockUserDirectory
+		creds       *mockCredentialService
+		notifier    *mockNotifier
+		name        string
+		wantSent    []uuid.UUID
+		expectError bool
+	}{
+		{
+			name:     "notifies_reachable_users",
+			users:    users,
+			creds:    weakCreds,
+			notifier: &mockNotifier{unreachable: unreachable},
+			wantSent: []uuid.UUID{reachableUser},
+		},
+		{
+			name:     "no_findings",
+			users:    users,
+			creds:    &mockCredentialService{},
+			notifier: &mockNotifier{unreachable: unreachable},
+		},
+		{
+			name:        "send_failure",
+			users:       users,
+			creds:       weakCreds,
+			notifier:    &mockNotifier{unreachable: unreachable, err: errors.New("smtp down")},
+			wantSent:    []uuid.UUID{reachableUser},
+			expectError: true,
+		},
+		{
+			name:        "list_users_failure",
+			users:       &mockUserDirectory{listErr: errors.New("db down")},
+			creds:       weakCreds,
+			notifier:    &mockNotifier{},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := NewService(
+				tt.users, tt.creds, &mockBankCardService{}, &mockNoteService{},
+				&mockFileDataService{}, &mockStorageUsageMeter{}, tt.notifier,
+			)
+
+			err := s.RunScheduled(context.Background())
+
+			if tt.expectError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantSent, tt.notifier.sent)
+		})
+	}
+}
+
+func TestService_RunScheduled_WithoutNotifier(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	s := NewService(
+		&mockUserDirectory{ids: []uuid.UUID{userID}},
+		&mockCredentialService{items: []*credential.Credential{{ID: uuid.New(), Password: "qwerty"}}},
+		&mockBankCardService{}, &mockNoteService{}, &mockFileDataService{}, &mockStorageUsageMeter{}, nil,
+	)
+
+	require.NoError(t, s.RunScheduled(context.Background()))
+}
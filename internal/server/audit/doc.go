@@ -0,0 +1,5 @@
+// Package audit provides security audit event recording for the AegisVaultKeeper server.
+//
+// This package defines audit events emitted by security-relevant operations
+// and recorders that persist them to the audit trail.
+package audit
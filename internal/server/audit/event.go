@@ -0,0 +1,185 @@
+package audit
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Audit event types.
+const (
+	// EventRowTampered is emitted when a stored row fails integrity signature verification.
+	EventRowTampered = "integrity.row_tampered"
+	// EventAccessDenied is emitted when a request is rejected by network access rules.
+	EventAccessDenied = "access.denied"
+	// EventClientBanned is emitted when a client network is banned after repeated authentication failures.
+	EventClientBanned = "access.client_banned"
+	// EventAccessRuleAdded is emitted when a network access rule is created.
+	EventAccessRuleAdded = "access.rule_added"
+	// EventAccessRuleDeleted is emitted when a network access rule is deleted.
+	EventAccessRuleDeleted = "access.rule_deleted"
+	// EventAccessOutsideWindow is emitted when an item reveal is rejected outside the access windows.
+	EventAccessOutsideWindow = "access.outside_window"
+	// EventAccessPolicyAdded is emitted when a time-based access policy is created.
+	EventAccessPolicyAdded = "access.policy_added"
+	// EventAccessPolicyDeleted is emitted when a time-based access policy is deleted.
+	EventAccessPolicyDeleted = "access.policy_deleted"
+	// EventAccessOutsideGeofence is emitted when a restricted item reveal comes from outside the geofence.
+	EventAccessOutsideGeofence = "access.outside_geofence"
+	// EventItemRestrictionChanged is emitted when a user adds or removes the restricted tag of an item.
+	EventItemRestrictionChanged = "item.restriction_changed"
+	// EventItemRevealed is emitted when a user reads the secret content of an item.
+	EventItemRevealed = "item.revealed"
+	// EventAccessApprovalRequired is emitted when an item reveal is rejected for lack of an approved request.
+	EventAccessApprovalRequired = "access.approval_required"
+	// EventApprovalRequested is emitted when a user requests access to an item that requires approval.
+	EventApprovalRequested = "approval.requested"
+	// EventApprovalGranted is emitted when an approver grants an access request.
+	EventApprovalGranted = "approval.granted"
+	// EventApprovalDenied is emitted when an approver denies an access request.
+	EventApprovalDenied = "approval.denied"
+	// EventCredentialShared is emitted when an owner shares a credential with a group or changes the share.
+	EventCredentialShared = "credential.shared"
+	// EventCredentialUnshared is emitted when an owner stops sharing a credential.
+	EventCredentialUnshared = "credential.unshared"
+	// EventCredentialCheckedOut is emitted when a member checks out a shared credential.
+	EventCredentialCheckedOut = "credential.checked_out"
+	// EventCredentialCheckedIn is emitted when a shared credential is checked in by its holder or on timeout.
+	EventCredentialCheckedIn = "credential.checked_in"
+	// EventCredentialRotated is emitted when the secret of a credential is rotated on check-in or by its
+	// rotation policy.
+	EventCredentialRotated = "credential.rotated"
+	// EventCredentialRotationFailed is emitted when rotating the secret of a credential in its external system fails.
+	EventCredentialRotationFailed = "credential.rotation_failed"
+	// EventCredentialRotationConfigured is emitted when an owner sets the rotation policy of a credential.
+	EventCredentialRotationConfigured = "credential.rotation_configured"
+	// EventCredentialRotationRemoved is emitted when an owner removes the rotation policy of a credential.
+	EventCredentialRotationRemoved = "credential.rotation_removed"
+	// EventMachineCreated is emitted when a user creates a machine identity.
+	EventMachineCreated = "machine.created"
+	// EventMachineDeleted is emitted when a user revokes a machine identity.
+	EventMachineDeleted = "machine.deleted"
+	// EventMachineLeaseIssued is emitted when a machine identity leases vault items.
+	EventMachineLeaseIssued = "machine.lease_issued"
+	// EventMachineLeaseDenied is emitted when a machine identity requests items outside its scope.
+	EventMachineLeaseDenied = "machine.lease_denied"
+	// EventInviteCreated is emitted when an administrator or a user creates an invite.
+	EventInviteCreated = "invite.created"
+	// EventInviteRevoked is emitted when an administrator or a user revokes an invite.
+	EventInviteRevoked = "invite.revoked"
+	// EventInviteRedeemed is emitted when a new user registers with an invite.
+	EventInviteRedeemed = "invite.redeemed"
+	// EventACMEHookFailed is emitted when the automation hook of an ACME account cannot be told about a change.
+	EventACMEHookFailed = "acme.hook_failed"
+	// EventAuthzDenied is emitted when the authorization policy rejects an action on a vault item.
+	EventAuthzDenied = "authz.denied"
+	// EventLoginSuspicious is emitted when a login comes from an unrecognized device or location.
+	EventLoginSuspicious = "auth.login_suspicious"
+	// EventDeviceVerified is emitted when a step-up verification establishes trust in a device.
+	EventDeviceVerified = "auth.device_verified"
+	// EventLoginApproved is emitted when a held login is approved by link or from a trusted device.
+	EventLoginApproved = "auth.login_approved"
+	// EventLoginChangeRequested is emitted when a user requests to change the login and confirmations are sent.
+	EventLoginChangeRequested = "auth.login_change_requested"
+	// EventLoginChanged is emitted when a confirmed login change is applied and the user sessions are revoked.
+	EventLoginChanged = "auth.login_changed"
+	// EventDuressLogin is emitted when a user logs in with the duress password and alerts are enabled.
+	EventDuressLogin = "auth.duress_login"
+	// EventMaintenanceToggled is emitted when read-only maintenance mode is switched on or off.
+	EventMaintenanceToggled = "maintenance.toggled"
+	// EventFeatureFlagSet is emitted when a feature flag is switched on or off.
+	EventFeatureFlagSet = "feature.flag_set"
+	// EventFeatureFlagDeleted is emitted when a feature flag override is removed.
+	EventFeatureFlagDeleted = "feature.flag_deleted"
+	// EventSigningKeyCreated is emitted when a user registers a request signing key.
+	EventSigningKeyCreated = "signing.key_created"
+	// EventSigningKeyDeleted is emitted when a user revokes a request signing key.
+	EventSigningKeyDeleted = "signing.key_deleted"
+	// EventDeviceKeyRegistered is emitted when a user registers the public key of a device.
+	EventDeviceKeyRegistered = "device.key_registered"
+	// EventDeviceKeyDeleted is emitted when a user revokes the public key of a device.
+	EventDeviceKeyDeleted = "device.key_deleted"
+	// EventUserProvisioned is emitted when the enterprise directory creates a user.
+	EventUserProvisioned = "directory.user_provisioned"
+	// EventUserUpdated is emitted when the enterprise directory changes or deactivates a user.
+	EventUserUpdated = "directory.user_updated"
+	// EventUserDeprovisioned is emitted when the enterprise directory removes a user.
+	EventUserDeprovisioned = "directory.user_deprovisioned"
+	// EventGroupCreated is emitted when the enterprise directory creates a group.
+	EventGroupCreated = "directory.group_created"
+	// EventGroupUpdated is emitted when the enterprise directory renames a group or changes its members.
+	EventGroupUpdated = "directory.group_updated"
+	// EventGroupDeleted is emitted when the enterprise directory deletes a group.
+	EventGroupDeleted = "directory.group_deleted"
+	// EventNotificationChannelSet is emitted when a user adds or changes a notification channel.
+	EventNotificationChannelSet = "notification.channel_set"
+	// EventNotificationChannelDeleted is emitted when a user removes a notification channel.
+	EventNotificationChannelDeleted = "notification.channel_deleted"
+	// EventNotificationFailed is emitted when a notification cannot be delivered to a channel.
+	EventNotificationFailed = "notification.delivery_failed"
+	// EventPushSubscribed is emitted when a device subscribes to web push or renews its subscription.
+	EventPushSubscribed = "notification.push_subscribed"
+	// EventPushUnsubscribed is emitted when a push subscription is removed by the user or found expired.
+	EventPushUnsubscribed = "notification.push_unsubscribed"
+	// EventReportGenerated is emitted when a user generates a CSV report of vault items.
+	EventReportGenerated = "report.generated"
+	// EventLicenseInstalled is emitted when an administrator installs a license key.
+	EventLicenseInstalled = "license.installed"
+	// EventRecordingStarted is emitted when an administrator starts recording requests for debugging.
+	EventRecordingStarted = "recording.started"
+	// EventRecordingStopped is emitted when an administrator stops a recording before its window ends.
+	EventRecordingStopped = "recording.stopped"
+	// EventRecordingDeleted is emitted when an administrator deletes a recording with its captured requests.
+	EventRecordingDeleted = "recording.deleted"
+	// EventItemsPurged is emitted when a user permanently deletes every item of one kind.
+	EventItemsPurged = "item.purged"
+	// EventRetentionOverrideSet is emitted when an administrator sets the retention policy of a directory group.
+	EventRetentionOverrideSet = "retention.override_set"
+	// EventRetentionOverrideDeleted is emitted when the retention policy of a directory group is removed.
+	EventRetentionOverrideDeleted = "retention.override_deleted"
+	// EventRetentionEnforced is emitted when the scheduler purges data the retention policies do not keep.
+	EventRetentionEnforced = "retention.enforced"
+	// EventLegalHoldPlaced is emitted when an administrator places a legal hold on the data of a user or an item.
+	EventLegalHoldPlaced = "legal_hold.placed"
+	// EventLegalHoldReleased is emitted when an administrator releases a legal hold.
+	EventLegalHoldReleased = "legal_hold.released"
+	// EventItemPolicyCreated is emitted when an administrator creates a vault item policy.
+	EventItemPolicyCreated = "item_policy.created"
+	// EventItemPolicyUpdated is emitted when an administrator changes a vault item policy.
+	EventItemPolicyUpdated = "item_policy.updated"
+	// EventItemPolicyDeleted is emitted when an administrator deletes a vault item policy.
+	EventItemPolicyDeleted = "item_policy.deleted"
+	// EventAuditExported is emitted when an administrator exports the audit trail for a compliance audit.
+	EventAuditExported = "audit.exported"
+	// EventAdminActionRequested is emitted when an operator initiates a destructive admin request under dual control.
+	EventAdminActionRequested = "admin.action_requested"
+	// EventAdminActionApproved is emitted when a second operator approves a destructive admin request.
+	EventAdminActionApproved = "admin.action_approved"
+	// EventAdminActionRejected is emitted when an operator rejects or withdraws a destructive admin request.
+	EventAdminActionRejected = "admin.action_rejected"
+	// EventAdminActionExecuted is emitted when the initiator executes an approved destructive admin request.
+	EventAdminActionExecuted = "admin.action_executed"
+	// EventServerOnboarded is emitted when the onboarding wizard creates the first account of the server.
+	EventServerOnboarded = "server.onboarded"
+)
+
+// Event describes a security-relevant occurrence recorded in the audit trail.
+type Event struct {
+	// OccurredAt is the moment the event happened.
+	OccurredAt time.Time
+	// Details holds event-specific attributes.
+	Details map[string]string
+	// Type identifies the kind of event.
+	Type string
+	// UserID identifies the affected user, or uuid.Nil for system-wide events.
+	UserID uuid.UUID
+}
+
+// Entry is an audit event as stored in the persistent audit trail.
+type Entry struct {
+	Event
+	// ClientIP contains the address of the client the event originates from; empty for events outside requests.
+	ClientIP string
+	// Seq orders the entries by the time they were stored; it is assigned by the store.
+	Seq int64
+}
@@ -0,0 +1,47 @@
+package audit
+
+import (
+	"context"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/clientinfo"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/dryrun"
+	"go.uber.org/zap"
+)
+
+// LogRecorder records audit events as structured log entries.
+type LogRecorder struct {
+	// logger receives the audit entries.
+	logger *zap.SugaredLogger
+}
+
+// NewLogRecorder creates a new LogRecorder writing to the provided logger.
+func NewLogRecorder(logger *zap.SugaredLogger) *LogRecorder {
+	return &LogRecorder{logger: logger}
+}
+
+// Record writes the audit event to the log. Events without a timestamp are stamped with the current time.
+// The client IP address is included when the event originates from a request. Events of dry runs are dropped,
+// since their changes are rolled back.
+func (r *LogRecorder) Record(ctx context.Context, e Event) {
+	if dryrun.Enabled(ctx) {
+		return
+	}
+	if e.OccurredAt.IsZero() {
+		e.OccurredAt = time.Now()
+	}
+
+	fields := make([]any, 0, 8+2*len(e.Details))
+	fields = append(fields,
+		"event_type", e.Type,
+		"user_id", e.UserID.String(),
+		"occurred_at", e.OccurredAt.UTC().Format(time.RFC3339Nano),
+	)
+	if ip, ok := clientinfo.IP(ctx); ok {
+		fields = append(fields, "client_ip", ip.String())
+	}
+	for k, v := range e.Details {
+		fields = append(fields, k, v)
+	}
+	r.logger.Warnw("Audit event", fields...)
+}
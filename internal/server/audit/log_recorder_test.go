@@ -0,0 +1,89 @@
+package audit
+
+import (
+	"context"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/clientinfo"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/dryrun"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestLogRecorder_Record(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	occurredAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		event         Event
+		name          string
+		wantTimestamp bool
+	}{
+		{
+			name: "event_with_timestamp",
+			event: Event{
+				Type:       EventRowTampered,
+				UserID:     userID,
+				OccurredAt: occurredAt,
+				Details:    map[string]string{"table": "notes"},
+			},
+			wantTimestamp: true,
+		},
+		{
+			name:  "event_without_timestamp",
+			event: Event{Type: EventRowTampered},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			core, logs := observer.New(zap.InfoLevel)
+			r := NewLogRecorder(zap.New(core).Sugar())
+
+			r.Record(context.Background(), tt.event)
+
+			require.Equal(t, 1, logs.Len())
+			fields := logs.All()[0].ContextMap()
+			assert.Equal(t, tt.event.Type, fields["event_type"])
+			assert.Equal(t, tt.event.UserID.String(), fields["user_id"])
+			assert.NotEmpty(t, fields["occurred_at"])
+			if tt.wantTimestamp {
+				assert.Equal(t, occurredAt.Format(time.RFC3339Nano), fields["occurred_at"])
+				assert.Equal(t, "notes", fields["table"])
+			}
+		})
+	}
+}
+
+func TestLogRecorder_Record_ClientIP(t *testing.T) {
+	t.Parallel()
+
+	core, logs := observer.New(zap.InfoLevel)
+	r := NewLogRecorder(zap.New(core).Sugar())
+
+	ctx := clientinfo.WithIP(context.Background(), netip.MustParseAddr("203.0.113.7"))
+	r.Record(ctx, Event{Type: EventRowTampered})
+
+	require.Equal(t, 1, logs.Len())
+	assert.Equal(t, "203.0.113.7", logs.All()[0].ContextMap()["client_ip"])
+}
+
+func TestLogRecorder_Record_DryRun(t *testing.T) {
+	t.Parallel()
+
+	core, logs := observer.New(zap.InfoLevel)
+	r := NewLogRecorder(zap.New(core).Sugar())
+
+	r.Record(dryrun.With(context.Background()), Event{Type: EventRowTampered})
+
+	assert.Zero(t, logs.Len())
+}
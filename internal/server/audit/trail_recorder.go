@@ -0,0 +1,145 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/clientinfo"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/dryrun"
+	"go.uber.org/zap"
+)
+
+const (
+	// trailQueueSize is the number of entries waiting to be stored before recording blocks.
+	trailQueueSize = 4096
+	// trailBatchSize is the largest number of entries stored at once.
+	trailBatchSize = 256
+	// trailWriteTimeout bounds storing a single batch of entries.
+	trailWriteTimeout = 10 * time.Second
+)
+
+// Store persists audit entries in the audit trail.
+type Store interface {
+	// Append stores the entries in the order given.
+	Append(ctx context.Context, entries []Entry) error
+}
+
+// TrailRecorder records audit events as structured log entries and stores them in the persistent audit trail,
+// so the trail can be exported for compliance audits.
+// Entries are stored in the background outside the transaction of the recording request, so events of failed
+// operations are kept and recording never waits for the database unless the queue is full.
+type TrailRecorder struct {
+	// log writes every event to the log before it is queued.
+	log *LogRecorder
+	// store persists the queued entries.
+	store Store
+	// logger reports entries that could not be stored.
+	logger *zap.SugaredLogger
+	// queue holds the entries waiting to be stored.
+	queue chan Entry
+	// done is closed when the writer loop exits.
+	done chan struct{}
+	// mu guards stopped and closing queue against concurrent sends.
+	mu sync.RWMutex
+	// stopped reports whether the queue is closed.
+	stopped bool
+}
+
+// NewTrailRecorder creates a new TrailRecorder logging events with log and storing them in store.
+// Entries are stored once Start has been called.
+func NewTrailRecorder(log *LogRecorder, store Store, logger *zap.SugaredLogger) *TrailRecorder {
+	return &TrailRecorder{
+		log:    log,
+		store:  store,
+		logger: logger,
+		queue:  make(chan Entry, trailQueueSize),
+		done:   make(chan struct{}),
+	}
+}
+
+// Record writes the audit event to the log and queues it for the audit trail. Events without a timestamp are
+// stamped with the current time, and the client IP address is kept when the event originates from a request.
+// Events of dry runs are dropped, since their changes are rolled back. When the queue is full, Record waits
+// for the writer rather than leave a gap in the trail.
+func (r *TrailRecorder) Record(ctx context.Context, e Event) {
+	if dryrun.Enabled(ctx) {
+		return
+	}
+	if e.OccurredAt.IsZero() {
+		e.OccurredAt = time.Now()
+	}
+	r.log.Record(ctx, e)
+
+	entry := Entry{Event: e}
+	if ip, ok := clientinfo.IP(ctx); ok {
+		entry.ClientIP = ip.String()
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.stopped {
+		r.logger.Errorw("Audit event not stored: the audit trail is stopped", "event_type", e.Type)
+		return
+	}
+	r.queue <- entry
+}
+
+// Start launches the writer storing the queued entries in the background.
+func (r *TrailRecorder) Start(_ context.Context) error {
+	go r.loop()
+	return nil
+}
+
+// Stop closes the queue and waits for the writer to store the remaining entries or the context to expire.
+// Events recorded afterwards are only logged.
+func (r *TrailRecorder) Stop(ctx context.Context) error {
+	r.mu.Lock()
+	if !r.stopped {
+		r.stopped = true
+		close(r.queue)
+	}
+	r.mu.Unlock()
+
+	select {
+	case <-r.done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("audit trail stop interrupted: %w", ctx.Err())
+	}
+}
+
+// loop stores the queued entries in batches until the queue is closed and drained.
+func (r *TrailRecorder) loop() {
+	defer close(r.done)
+
+	batch := make([]Entry, 0, trailBatchSize)
+	for entry := range r.queue {
+		batch = append(batch, entry)
+	fill:
+		for len(batch) < trailBatchSize {
+			select {
+			case next, ok := <-r.queue:
+				if !ok {
+					break fill
+				}
+				batch = append(batch, next)
+			default:
+				break fill
+			}
+		}
+		r.write(batch)
+		batch = batch[:0]
+	}
+}
+
+// write stores a batch of entries. Entries that cannot be stored are reported; they remain in the log.
+func (r *TrailRecorder) write(batch []Entry) {
+	ctx, cancel := context.WithTimeout(context.Background(), trailWriteTimeout)
+	defer cancel()
+
+	if err := r.store.Append(ctx, batch); err != nil {
+		r.logger.Errorw("Failed to store audit events", "count", len(batch), "error", err)
+	}
+}
@@ -0,0 +1,125 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"net/netip"
+	"sync"
+	"testing"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/clientinfo"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/dryrun"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// mockStore implements Store for testing.
+type mockStore struct {
+	// err is returned by Append when set.
+	err error
+	// entries collects the appended entries.
+	entries []Entry
+	// mu guards entries.
+	mu sync.Mutex
+}
+
+func (m *mockStore) Append(_ context.Context, entries []Entry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.err != nil {
+		return m.err
+	}
+	m.entries = append(m.entries, entries...)
+	return nil
+}
+
+func TestTrailRecorder_Record(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+
+	tests := []struct {
+		ctx         context.Context
+		storeErr    error
+		name        string
+		wantIP      string
+		wantStored  int
+		wantLogged  int
+		wantErrLogs int
+	}{
+		{
+			name:       "stores the event with the client address",
+			ctx:        clientinfo.WithIP(context.Background(), netip.MustParseAddr("203.0.113.7")),
+			wantIP:     "203.0.113.7",
+			wantStored: 1,
+			wantLogged: 1,
+		},
+		{
+			name:       "stores events outside requests",
+			ctx:        context.Background(),
+			wantStored: 1,
+			wantLogged: 1,
+		},
+		{
+			name: "drops events of dry runs",
+			ctx:  dryrun.With(context.Background()),
+		},
+		{
+			name:        "reports events that cannot be stored",
+			ctx:         context.Background(),
+			storeErr:    errors.New("connection lost"),
+			wantLogged:  1,
+			wantErrLogs: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			core, logs := observer.New(zap.InfoLevel)
+			logger := zap.New(core).Sugar()
+			store := &mockStore{err: tt.storeErr}
+			r := NewTrailRecorder(NewLogRecorder(logger), store, logger)
+			require.NoError(t, r.Start(context.Background()))
+
+			r.Record(tt.ctx, Event{Type: EventRowTampered, UserID: userID})
+			require.NoError(t, r.Stop(context.Background()))
+
+			assert.Len(t, logs.FilterMessage("Audit event").All(), tt.wantLogged)
+			assert.Len(t, logs.FilterMessage("Failed to store audit events").All(), tt.wantErrLogs)
+			require.Len(t, store.entries, tt.wantStored)
+			if tt.wantStored > 0 {
+				e := store.entries[0]
+				assert.Equal(t, EventRowTampered, e.Type)
+				assert.Equal(t, userID, e.UserID)
+				assert.Equal(t, tt.wantIP, e.ClientIP)
+				assert.False(t, e.OccurredAt.IsZero())
+			}
+		})
+	}
+}
+
+func TestTrailRecorder_Stop(t *testing.T) {
+	t.Parallel()
+
+	core, logs := observer.New(zap.InfoLevel)
+	logger := zap.New(core).Sugar()
+	store := &mockStore{}
+	r := NewTrailRecorder(NewLogRecorder(logger), store, logger)
+	require.NoError(t, r.Start(context.Background()))
+
+	for range 3 * trailBatchSize {
+		r.Record(context.Background(), Event{Type: EventRowTampered})
+	}
+	require.NoError(t, r.Stop(context.Background()))
+	require.NoError(t, r.Stop(context.Background()))
+	r.Record(context.Background(), Event{Type: EventRowTampered})
+
+	assert.Len(t, store.entries, 3*trailBatchSize)
+	assert.Equal(t, 1, logs.FilterMessage("Audit event not stored: the audit trail is stopped").Len())
+}
@@ -0,0 +1,5 @@
+// Package backup provides encrypted snapshots of the AegisVaultKeeper server state.
+//
+// This package produces compressed and encrypted archives of the database tables and the
+// file storage, streams them to a snapshot store and restores them after consistency checks.
+package backup
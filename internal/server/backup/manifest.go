@@ -0,0 +1,98 @@
+package backup
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// formatVersion is the snapshot archive layout version written to the manifest.
+const formatVersion = 1
+
+// Archive entry names and prefixes used inside the snapshot.
+const (
+	// manifestEntry is the name of the manifest entry written last to the archive.
+	manifestEntry = "manifest.json"
+	// tablesPrefix prefixes the entries holding table rows as JSON lines.
+	tablesPrefix = "db/"
+	// tablesSuffix is the extension of the entries holding table rows.
+	tablesSuffix = ".jsonl"
+	// filesPrefix prefixes the entries holding file storage content.
+	filesPrefix = "files/"
+)
+
+// ErrInconsistentSnapshot indicates that snapshot content does not match its manifest.
+var ErrInconsistentSnapshot = errors.New("snapshot content does not match its manifest")
+
+// Manifest describes the content of a snapshot and the checksums used to verify it on restore.
+type Manifest struct {
+	// CreatedAt specifies when the snapshot was taken.
+	CreatedAt time.Time `json:"created_at"`
+	// Tables lists the dumped database tables.
+	Tables []TableEntry `json:"tables"`
+	// Files lists the archived file storage entries.
+	Files []FileEntry `json:"files"`
+	// Version specifies the snapshot archive layout version.
+	Version int `json:"version"`
+}
+
+// TableEntry describes a dumped database table.
+type TableEntry struct {
+	// Name is the table name within the application schema.
+	Name string `json:"name"`
+	// SHA256 is the hex-encoded checksum of the table entry content.
+	SHA256 string `json:"sha256"`
+	// Rows is the number of dumped rows.
+	Rows int `json:"rows"`
+}
+
+// FileEntry describes an archived file storage entry.
+type FileEntry struct {
+	// Path is the slash-separated path relative to the file storage base directory.
+	Path string `json:"path"`
+	// SHA256 is the hex-encoded checksum of the file content.
+	SHA256 string `json:"sha256"`
+	// Size is the file size in bytes.
+	Size int64 `json:"size"`
+}
+
+// entryDigest is the checksum and size of an archive entry computed while reading a snapshot.
+type entryDigest struct {
+	// sha256 is the hex-encoded checksum of the entry content.
+	sha256 string
+	// size is the entry size in bytes.
+	size int64
+	// rows is the number of JSON lines in table entries.
+	rows int
+}
+
+// verify checks that the archive entries read from a snapshot match the manifest exactly.
+func (m *Manifest) verify(tables, files map[string]entryDigest) error {
+	if m.Version != formatVersion {
+		return fmt.Errorf("%w: unsupported snapshot version %d", ErrInconsistentSnapshot, m.Version)
+	}
+	if len(tables) != len(m.Tables) || len(files) != len(m.Files) {
+		return fmt.Errorf("%w: unexpected number of entries", ErrInconsistentSnapshot)
+	}
+
+	for _, t := range m.Tables {
+		d, ok := tables[t.Name]
+		if !ok {
+			return fmt.Errorf("%w: table %q is missing", ErrInconsistentSnapshot, t.Name)
+		}
+		if d.sha256 != t.SHA256 || d.rows != t.Rows {
+			return fmt.Errorf("%w: table %q checksum mismatch", ErrInconsistentSnapshot, t.Name)
+		}
+	}
+
+	for _, f := range m.Files {
+		d, ok := files[f.Path]
+		if !ok {
+			return fmt.Errorf("%w: file %q is missing", ErrInconsistentSnapshot, f.Path)
+		}
+		if d.sha256 != f.SHA256 || d.size != f.Size {
+			return fmt.Errorf("%w: file %q checksum mismatch", ErrInconsistentSnapshot, f.Path)
+		}
+	}
+	return nil
+}
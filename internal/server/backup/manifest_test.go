@@ -0,0 +1,76 @@
+package backup
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManifest_Verify(t *testing.T) {
+	t.Parallel()
+
+	manifest := Manifest{
+		Version: formatVersion,
+		Tables:  []TableEntry{{Name: "notes", Rows: 2, SHA256: "aa"}},
+		Files:   []FileEntry{{Path: "u1/f.bin", Size: 3, SHA256: "bb"}},
+	}
+	validTables := map[string]entryDigest{"notes": {sha256: "aa", rows: 2}}
+	validFiles := map[string]entryDigest{"u1/f.bin": {sha256: "bb", size: 3}}
+
+	tests := []struct {
+		tables  map[string]entryDigest
+		files   map[string]entryDigest
+		name    string
+		version int
+		wantErr bool
+	}{
+		{name: "consistent", version: formatVersion, tables: validTables, files: validFiles},
+		{name: "unsupported_version", version: 99, tables: validTables, files: validFiles, wantErr: true},
+		{
+			name:    "missing_table",
+			version: formatVersion,
+			tables:  map[string]entryDigest{"users": {sha256: "aa", rows: 2}},
+			files:   validFiles,
+			wantErr: true,
+		},
+		{
+			name:    "row_count_mismatch",
+			version: formatVersion,
+			tables:  map[string]entryDigest{"notes": {sha256: "aa", rows: 1}},
+			files:   validFiles,
+			wantErr: true,
+		},
+		{
+			name:    "file_checksum_mismatch",
+			version: formatVersion,
+			tables:  validTables,
+			files:   map[string]entryDigest{"u1/f.bin": {sha256: "cc", size: 3}},
+			wantErr: true,
+		},
+		{
+			name:    "extra_file",
+			version: formatVersion,
+			tables:  validTables,
+			files: map[string]entryDigest{
+				"u1/f.bin": {sha256: "bb", size: 3},
+				"u1/x.bin": {sha256: "dd", size: 1},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			m := manifest
+			m.Version = tt.version
+			err := m.verify(tt.tables, tt.files)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInconsistentSnapshot)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,168 @@
+package backup
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/sigv4"
+)
+
+// maxErrorBodySize limits how much of an S3 error response is included in errors.
+const maxErrorBodySize = 512
+
+// S3Config contains the connection parameters of an S3-compatible object storage.
+type S3Config struct {
+	// Endpoint is the storage base URL, e.g. https://s3.eu-central-1.amazonaws.com.
+	Endpoint string
+	// Region is the signing region of the storage.
+	Region string
+	// Bucket is the bucket holding the snapshots.
+	Bucket string
+	// AccessKeyID is the access key identifier used for request signing.
+	AccessKeyID string
+	// SecretAccessKey is the secret key used for request signing (sensitive data).
+	SecretAccessKey string
+}
+
+// S3Store keeps snapshots in an S3-compatible object storage using path-style requests
+// signed with AWS Signature Version 4.
+type S3Store struct {
+	// client performs the HTTP requests.
+	client *http.Client
+	// signer adds the authorization headers to the requests.
+	signer *sigv4.Signer
+	// now returns the current time used for request signing.
+	now func() time.Time
+	// cfg contains the storage connection parameters.
+	cfg S3Config
+}
+
+// NewS3Store creates a new S3Store with the provided configuration.
+func NewS3Store(client *http.Client, cfg S3Config) *S3Store {
+	creds := sigv4.Credentials{AccessKeyID: cfg.AccessKeyID, SecretAccessKey: cfg.SecretAccessKey}
+	return &S3Store{
+		client: client,
+		signer: sigv4.NewSigner("s3", cfg.Region, creds, "x-amz-content-sha256"),
+		cfg:    cfg,
+		now:    time.Now,
+	}
+}
+
+// Create starts writing a new snapshot into a local spool file uploaded on commit.
+// S3 requires the object size and checksum before upload, so the snapshot is spooled first.
+func (s *S3Store) Create(ctx context.Context, name string) (SnapshotWriter, error) {
+	if err := validateName(name); err != nil {
+		return nil, err
+	}
+	f, err := os.CreateTemp("", "aegis-snapshot-*.partial")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create snapshot spool file: %w", err)
+	}
+	h := sha256.New()
+	return &s3Writer{
+		Writer: io.MultiWriter(f, h),
+		ctx:    ctx,
+		store:  s,
+		spool:  f,
+		hash:   h,
+		name:   name,
+	}, nil
+}
+
+// Open downloads the snapshot object with the specified name.
+func (s *S3Store) Open(ctx context.Context, name string) (io.ReadCloser, error) {
+	if err := validateName(name); err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.objectURL(name), http.NoBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build snapshot download request: %w", err)
+	}
+	resp, err := s.do(req, sigv4.EmptyPayloadHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download snapshot: %w", err)
+	}
+	return resp.Body, nil
+}
+
+// objectURL builds the path-style URL of the snapshot object.
+func (s *S3Store) objectURL(name string) string {
+	base := strings.TrimRight(s.cfg.Endpoint, "/")
+	return base + "/" + url.PathEscape(s.cfg.Bucket) + "/" + url.PathEscape(name)
+}
+
+// do signs and sends the request, returning an error for non-successful responses.
+func (s *S3Store) do(req *http.Request, payloadHash string) (*http.Response, error) {
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	s.signer.Sign(req, payloadHash, s.now())
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return resp, nil
+}
+
+// s3Writer spools a snapshot to a temporary file and uploads it to an S3Store on commit.
+type s3Writer struct {
+	io.Writer
+	// ctx is the context of the upload request.
+	ctx context.Context
+	// store is the destination storage.
+	store *S3Store
+	// spool is the temporary file holding the snapshot.
+	spool *os.File
+	// hash computes the snapshot checksum required for request signing.
+	hash hash.Hash
+	// name is the snapshot object name.
+	name string
+}
+
+// Commit uploads the spooled snapshot and removes the spool file.
+func (w *s3Writer) Commit() error {
+	defer func() { _ = w.Abort() }()
+
+	size, err := w.spool.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return fmt.Errorf("failed to determine snapshot size: %w", err)
+	}
+	if _, err := w.spool.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to rewind snapshot spool file: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(w.ctx, http.MethodPut, w.store.objectURL(w.name), w.spool)
+	if err != nil {
+		return fmt.Errorf("failed to build snapshot upload request: %w", err)
+	}
+	req.ContentLength = size
+
+	resp, err := w.store.do(req, hex.EncodeToString(w.hash.Sum(nil)))
+	if err != nil {
+		return fmt.Errorf("failed to upload snapshot: %w", err)
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return resp.Body.Close()
+}
+
+// Abort closes and removes the spool file.
+func (w *s3Writer) Abort() error {
+	_ = w.spool.Close()
+	if err := os.Remove(w.spool.Name()); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove snapshot spool file: %w", err)
+	}
+	return nil
+}
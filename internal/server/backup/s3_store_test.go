@@ -0,0 +1,136 @@
+package backup
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeS3 is a minimal in-memory S3 endpoint for testing.
+type fakeS3 struct {
+	objects map[string][]byte
+	mu      sync.Mutex
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/20261015/us-east-1/s3/") {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch r.Method {
+	case http.MethodPut:
+		data, _ := io.ReadAll(r.Body)
+		sum := sha256.Sum256(data)
+		if r.Header.Get("X-Amz-Content-Sha256") != hex.EncodeToString(sum[:]) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		f.objects[r.URL.Path] = data
+	case http.MethodGet:
+		data, ok := f.objects[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte("NoSuchKey"))
+			return
+		}
+		_, _ = w.Write(data)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func newTestS3Store(t *testing.T, accessKeyID string) (*S3Store, *fakeS3) {
+	t.Helper()
+
+	fake := &fakeS3{objects: make(map[string][]byte)}
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+
+	store := NewS3Store(srv.Client(), S3Config{
+		Endpoint:        srv.URL,
+		Region:          "us-east-1",
+		Bucket:          "backups",
+		AccessKeyID:     accessKeyID,
+		SecretAccessKey: "secret",
+	})
+	store.now = func() time.Time { return time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC) }
+	return store, fake
+}
+
+func TestS3Store_UploadAndDownload(t *testing.T) {
+	t.Parallel()
+
+	store, fake := newTestS3Store(t, "AKID")
+
+	w, err := store.Create(context.Background(), "snap.avks")
+	require.NoError(t, err)
+	_, err = w.Write([]byte("payload"))
+	require.NoError(t, err)
+	require.NoError(t, w.Commit())
+	assert.Equal(t, []byte("payload"), fake.objects["/backups/snap.avks"])
+
+	r, err := store.Open(context.Background(), "snap.avks")
+	require.NoError(t, err)
+	defer func() { _ = r.Close() }()
+	data, err := io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Equal(t, "payload", string(data))
+}
+
+func TestS3Store_Errors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		run         func(store *S3Store) error
+		name        string
+		accessKey   string
+		errContains string
+	}{
+		{
+			name:      "missing_object",
+			accessKey: "AKID",
+			run: func(store *S3Store) error {
+				_, err := store.Open(context.Background(), "missing.avks")
+				return err
+			},
+			errContains: "unexpected status 404: NoSuchKey",
+		},
+		{
+			name:      "rejected_upload",
+			accessKey: "OTHER",
+			run: func(store *S3Store) error {
+				w, err := store.Create(context.Background(), "snap.avks")
+				if err != nil {
+					return err
+				}
+				return w.Commit()
+			},
+			errContains: "unexpected status 403",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			store, _ := newTestS3Store(t, tt.accessKey)
+			err := tt.run(store)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errContains)
+		})
+	}
+}
@@ -0,0 +1,179 @@
+package backup
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDatabase keeps table rows in memory for testing.
+type fakeDatabase struct {
+	rows     map[string][][]byte
+	replaced bool
+}
+
+func (f *fakeDatabase) Tables() []string {
+	return []string{"users", "notes"}
+}
+
+func (f *fakeDatabase) Dump(_ context.Context, fn func(table string, row []byte) error) error {
+	for _, table := range f.Tables() {
+		for _, row := range f.rows[table] {
+			if err := fn(table, row); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (f *fakeDatabase) Replace(_ context.Context, entries []TableEntry, source RowSource) error {
+	rows := make(map[string][][]byte)
+	for _, e := range entries {
+		err := source(e.Name, func(row []byte) error {
+			rows[e.Name] = append(rows[e.Name], slices.Clone(row))
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	f.rows = rows
+	f.replaced = true
+	return nil
+}
+
+func writeTestFile(t *testing.T, base, rel, content string) {
+	t.Helper()
+
+	p := filepath.Join(base, filepath.FromSlash(rel))
+	require.NoError(t, os.MkdirAll(filepath.Dir(p), 0o750))
+	require.NoError(t, os.WriteFile(p, []byte(content), 0o600))
+}
+
+func readTestFiles(t *testing.T, base string) map[string]string {
+	t.Helper()
+
+	files := make(map[string]string)
+	err := filepath.WalkDir(base, func(p string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(base, p)
+		files[filepath.ToSlash(rel)] = string(data)
+		return nil
+	})
+	require.NoError(t, err)
+	return files
+}
+
+func newTestService(t *testing.T) (*Service, *fakeDatabase, string, string) {
+	t.Helper()
+
+	filesBase := filepath.Join(t.TempDir(), "filestorage")
+	storeDir := filepath.Join(t.TempDir(), "snapshots")
+	db := &fakeDatabase{rows: map[string][][]byte{
+		"users": {[]byte(`{"id":"u1"}`), []byte(`{"id":"u2"}`)},
+		"notes": {[]byte(`{"id":"n1","note":"\\x0102"}`)},
+	}}
+	writeTestFile(t, filesBase, "u1/report.pdf", "encrypted-report")
+	writeTestFile(t, filesBase, "u2/nested/photo.png", "encrypted-photo")
+
+	svc := NewService(db, NewLocalStore(storeDir), testKey, filesBase)
+	svc.now = func() time.Time { return time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC) }
+	return svc, db, filesBase, storeDir
+}
+
+func TestService_BackupRestore(t *testing.T) {
+	t.Parallel()
+
+	svc, db, filesBase, _ := newTestService(t)
+	originalRows := db.rows
+	originalFiles := readTestFiles(t, filesBase)
+
+	res, err := svc.Backup(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "aegis-vault-keeper-20261015T120000Z.avks", res.Name)
+	assert.Equal(t, []TableEntry{
+		{Name: "users", Rows: 2, SHA256: res.Manifest.Tables[0].SHA256},
+		{Name: "notes", Rows: 1, SHA256: res.Manifest.Tables[1].SHA256},
+	}, res.Manifest.Tables)
+	assert.Len(t, res.Manifest.Files, 2)
+
+	db.rows = map[string][][]byte{"users": {[]byte(`{"id":"u3"}`)}}
+	require.NoError(t, os.RemoveAll(filepath.Join(filesBase, "u1")))
+	writeTestFile(t, filesBase, "u3/new.bin", "created-after-backup")
+
+	m, err := svc.Restore(context.Background(), res.Name)
+	require.NoError(t, err)
+	assert.Equal(t, res.Manifest.Tables, m.Tables)
+	assert.Equal(t, originalRows, db.rows)
+	assert.Equal(t, originalFiles, readTestFiles(t, filesBase))
+}
+
+func TestService_RestoreRejectsDamagedSnapshot(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		damage func(t *testing.T, svc *Service, path string)
+		name   string
+	}{
+		{
+			name: "flipped_byte",
+			damage: func(t *testing.T, _ *Service, path string) {
+				t.Helper()
+				data, err := os.ReadFile(path)
+				require.NoError(t, err)
+				data[len(data)/2] ^= 0xFF
+				require.NoError(t, os.WriteFile(path, data, 0o600))
+			},
+		},
+		{
+			name: "truncated",
+			damage: func(t *testing.T, _ *Service, path string) {
+				t.Helper()
+				data, err := os.ReadFile(path)
+				require.NoError(t, err)
+				require.NoError(t, os.WriteFile(path, data[:len(data)-40], 0o600))
+			},
+		},
+		{
+			name: "wrong_key",
+			damage: func(t *testing.T, svc *Service, _ string) {
+				t.Helper()
+				svc.key = []byte("fedcba9876543210fedcba9876543210")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			svc, db, filesBase, storeDir := newTestService(t)
+			res, err := svc.Backup(context.Background())
+			require.NoError(t, err)
+
+			writeTestFile(t, filesBase, "u3/new.bin", "created-after-backup")
+			before := readTestFiles(t, filesBase)
+			tt.damage(t, svc, filepath.Join(storeDir, res.Name))
+
+			_, err = svc.Restore(context.Background(), res.Name)
+			require.Error(t, err)
+			assert.True(t, errors.Is(err, ErrCorruptedSnapshot) || errors.Is(err, ErrInconsistentSnapshot))
+			assert.False(t, db.replaced, "database must not be touched")
+			assert.Equal(t, before, readTestFiles(t, filesBase), "file storage must not be touched")
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// storeDirPermission is the permission for creating the local snapshot directory.
+const storeDirPermission = 0o750
+
+// SnapshotWriter receives a snapshot stream and publishes it only once the stream is complete.
+type SnapshotWriter interface {
+	io.Writer
+	// Commit publishes the written snapshot under its name.
+	Commit() error
+	// Abort discards the written data.
+	Abort() error
+}
+
+// Store defines snapshot storage destinations.
+type Store interface {
+	// Create starts writing a new snapshot with the specified name.
+	Create(ctx context.Context, name string) (SnapshotWriter, error)
+	// Open opens the snapshot with the specified name for reading.
+	Open(ctx context.Context, name string) (io.ReadCloser, error)
+}
+
+// LocalStore keeps snapshots in a local directory.
+type LocalStore struct {
+	// dir is the directory holding the snapshots.
+	dir string
+}
+
+// NewLocalStore creates a new LocalStore keeping snapshots in dir.
+func NewLocalStore(dir string) *LocalStore {
+	return &LocalStore{dir: dir}
+}
+
+// Create starts writing a new snapshot into a temporary file renamed on commit.
+func (s *LocalStore) Create(_ context.Context, name string) (SnapshotWriter, error) {
+	if err := validateName(name); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(s.dir, storeDirPermission); err != nil {
+		return nil, fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+	f, err := os.CreateTemp(s.dir, "."+name+".*.partial")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create snapshot file: %w", err)
+	}
+	return &localWriter{File: f, target: filepath.Join(s.dir, name)}, nil
+}
+
+// Open opens the snapshot file with the specified name.
+func (s *LocalStore) Open(_ context.Context, name string) (io.ReadCloser, error) {
+	if err := validateName(name); err != nil {
+		return nil, err
+	}
+	f, err := os.Open(filepath.Join(s.dir, name))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open snapshot file: %w", err)
+	}
+	return f, nil
+}
+
+// localWriter writes a snapshot into a temporary file of a LocalStore.
+type localWriter struct {
+	*os.File
+	// target is the final snapshot path.
+	target string
+}
+
+// Commit syncs the temporary file and renames it to the snapshot path.
+func (w *localWriter) Commit() error {
+	if err := w.Sync(); err != nil {
+		return errors.Join(fmt.Errorf("failed to sync snapshot file: %w", err), w.Abort())
+	}
+	if err := w.Close(); err != nil {
+		return errors.Join(fmt.Errorf("failed to close snapshot file: %w", err), w.Abort())
+	}
+	if err := os.Rename(w.Name(), w.target); err != nil {
+		return errors.Join(fmt.Errorf("failed to publish snapshot file: %w", err), w.Abort())
+	}
+	return nil
+}
+
+// Abort closes and removes the temporary file.
+func (w *localWriter) Abort() error {
+	_ = w.Close()
+	if err := os.Remove(w.Name()); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove partial snapshot file: %w", err)
+	}
+	return nil
+}
+
+// validateName rejects snapshot names that are not plain file names.
+func validateName(name string) error {
+	if name == "" || name != filepath.Base(name) || !filepath.IsLocal(name) {
+		return fmt.Errorf("invalid snapshot name %q", name)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package backup
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalStore_CommitAndOpen(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join(t.TempDir(), "snapshots")
+	store := NewLocalStore(dir)
+
+	w, err := store.Create(context.Background(), "snap.avks")
+	require.NoError(t, err)
+	_, err = w.Write([]byte("payload"))
+	require.NoError(t, err)
+
+	_, err = store.Open(context.Background(), "snap.avks")
+	require.Error(t, err, "snapshot must not be visible before commit")
+
+	require.NoError(t, w.Commit())
+
+	r, err := store.Open(context.Background(), "snap.avks")
+	require.NoError(t, err)
+	defer func() { _ = r.Close() }()
+	data, err := io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Equal(t, "payload", string(data))
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "no partial files should remain")
+}
+
+func TestLocalStore_Abort(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	store := NewLocalStore(dir)
+
+	w, err := store.Create(context.Background(), "snap.avks")
+	require.NoError(t, err)
+	_, err = w.Write([]byte("partial"))
+	require.NoError(t, err)
+	require.NoError(t, w.Abort())
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestValidateName(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{name: "plain_name", input: "snap.avks", wantErr: false},
+		{name: "empty", input: "", wantErr: true},
+		{name: "nested_path", input: "dir/snap.avks", wantErr: true},
+		{name: "parent_traversal", input: "../snap.avks", wantErr: true},
+		{name: "absolute_path", input: "/tmp/snap.avks", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := validateName(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package backup
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/crypto"
+)
+
+const (
+	// chunkSize is the maximum plaintext size sealed into a single stream chunk.
+	chunkSize = 64 << 10
+	// chunkOverhead bounds the ciphertext expansion added by AES-GCM nonce and tag.
+	chunkOverhead = 64
+	// chunkHeaderSize is the size of the chunk header holding the final flag and ciphertext length.
+	chunkHeaderSize = 5
+)
+
+// streamMagic identifies encrypted snapshot streams and binds chunks to the format version.
+var streamMagic = []byte("AVKSNAP1")
+
+// ErrCorruptedSnapshot indicates that a snapshot is truncated, tampered with or sealed with another key.
+var ErrCorruptedSnapshot = errors.New("snapshot is corrupted or was encrypted with another key")
+
+// encryptWriter seals written data into authenticated chunks with AES-GCM.
+// Every chunk is bound to its position and the last chunk is marked final, so reordering,
+// dropping and truncating chunks is detected on decryption.
+type encryptWriter struct {
+	// w receives the encrypted stream.
+	w io.Writer
+	// key is the AES-256 snapshot encryption key.
+	key []byte
+	// buf accumulates plaintext until a full chunk is available.
+	buf []byte
+	// index is the position of the next chunk in the stream.
+	index uint64
+	// started determines whether the stream magic has been written.
+	started bool
+	// closed determines whether the final chunk has been written.
+	closed bool
+}
+
+// newEncryptWriter creates a writer encrypting the stream written to w with key.
+// Close must be called to flush the final chunk.
+func newEncryptWriter(w io.Writer, key []byte) *encryptWriter {
+	return &encryptWriter{w: w, key: key, buf: make([]byte, 0, chunkSize)}
+}
+
+// Write buffers p and seals every complete chunk.
+func (e *encryptWriter) Write(p []byte) (int, error) {
+	if e.closed {
+		return 0, errors.New("write to closed snapshot stream")
+	}
+	n := len(p)
+	for len(p) > 0 {
+		free := chunkSize - len(e.buf)
+		if free == 0 {
+			if err := e.seal(e.buf, false); err != nil {
+				return 0, err
+			}
+			e.buf = e.buf[:0]
+			continue
+		}
+		take := min(free, len(p))
+		e.buf = append(e.buf, p[:take]...)
+		p = p[take:]
+	}
+	return n, nil
+}
+
+// Close seals the buffered data as the final chunk.
+func (e *encryptWriter) Close() error {
+	if e.closed {
+		return nil
+	}
+	e.closed = true
+	return e.seal(e.buf, true)
+}
+
+// seal encrypts a single chunk and writes it with its header.
+func (e *encryptWriter) seal(data []byte, final bool) error {
+	if !e.started {
+		if _, err := e.w.Write(streamMagic); err != nil {
+			return fmt.Errorf("failed to write snapshot header: %w", err)
+		}
+		e.started = true
+	}
+
+	ct, err := crypto.EncryptAESGCMWithAAD(e.key, data, chunkAAD(e.index, final))
+	if err != nil {
+		return fmt.Errorf("failed to encrypt snapshot chunk: %w", err)
+	}
+
+	header := make([]byte, chunkHeaderSize)
+	if final {
+		header[0] = 1
+	}
+	binary.BigEndian.PutUint32(header[1:], uint32(len(ct)))
+	if _, err := e.w.Write(header); err != nil {
+		return fmt.Errorf("failed to write snapshot chunk header: %w", err)
+	}
+	if _, err := e.w.Write(ct); err != nil {
+		return fmt.Errorf("failed to write snapshot chunk: %w", err)
+	}
+	e.index++
+	return nil
+}
+
+// decryptReader opens the authenticated chunks produced by encryptWriter.
+type decryptReader struct {
+	// r provides the encrypted stream.
+	r io.Reader
+	// key is the AES-256 snapshot encryption key.
+	key []byte
+	// buf holds decrypted data not yet returned to the caller.
+	buf []byte
+	// index is the position of the next expected chunk.
+	index uint64
+	// started determines whether the stream magic has been verified.
+	started bool
+	// done determines whether the final chunk has been read.
+	done bool
+}
+
+// newDecryptReader creates a reader decrypting the stream read from r with key.
+func newDecryptReader(r io.Reader, key []byte) *decryptReader {
+	return &decryptReader{r: r, key: key}
+}
+
+// Read returns decrypted data, reporting ErrCorruptedSnapshot for any stream inconsistency.
+func (d *decryptReader) Read(p []byte) (int, error) {
+	for len(d.buf) == 0 {
+		if d.done {
+			return 0, io.EOF
+		}
+		if err := d.next(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, d.buf)
+	d.buf = d.buf[n:]
+	return n, nil
+}
+
+// next reads and decrypts the next chunk of the stream.
+func (d *decryptReader) next() error {
+	if !d.started {
+		magic := make([]byte, len(streamMagic))
+		if _, err := io.ReadFull(d.r, magic); err != nil || string(magic) != string(streamMagic) {
+			return fmt.Errorf("%w: unknown snapshot format", ErrCorruptedSnapshot)
+		}
+		d.started = true
+	}
+
+	header := make([]byte, chunkHeaderSize)
+	if _, err := io.ReadFull(d.r, header); err != nil {
+		return fmt.Errorf("%w: stream ended before the final chunk", ErrCorruptedSnapshot)
+	}
+	final := header[0] == 1
+	size := binary.BigEndian.Uint32(header[1:])
+	if header[0] > 1 || size > chunkSize+chunkOverhead {
+		return fmt.Errorf("%w: invalid chunk header", ErrCorruptedSnapshot)
+	}
+
+	ct := make([]byte, size)
+	if _, err := io.ReadFull(d.r, ct); err != nil {
+		return fmt.Errorf("%w: stream ended inside a chunk", ErrCorruptedSnapshot)
+	}
+	pt, err := crypto.DecryptAESGCMWithAAD(d.key, ct, chunkAAD(d.index, final))
+	if err != nil {
+		return errors.Join(ErrCorruptedSnapshot, err)
+	}
+
+	if final {
+		if _, err := io.ReadFull(d.r, make([]byte, 1)); err == nil {
+			return fmt.Errorf("%w: unexpected data after the final chunk", ErrCorruptedSnapshot)
+		}
+		d.done = true
+	}
+	d.buf = pt
+	d.index++
+	return nil
+}
+
+// chunkAAD builds the additional authenticated data binding a chunk to its position and finality.
+func chunkAAD(index uint64, final bool) []byte {
+	return crypto.BuildAAD(string(streamMagic), strconv.FormatUint(index, 10), strconv.FormatBool(final))
+}
@@ -0,0 +1,114 @@
+package backup
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testKey = []byte("0123456789abcdef0123456789abcdef")
+
+func encryptStream(t *testing.T, key, data []byte) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w := newEncryptWriter(&buf, key)
+	_, err := w.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func TestEncryptStream_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		size int
+	}{
+		{name: "empty", size: 0},
+		{name: "small", size: 100},
+		{name: "exact_chunk", size: chunkSize},
+		{name: "multiple_chunks", size: 3*chunkSize + 17},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			data := bytes.Repeat([]byte{0xAB}, tt.size)
+			got, err := io.ReadAll(newDecryptReader(bytes.NewReader(encryptStream(t, testKey, data)), testKey))
+			require.NoError(t, err)
+			assert.Equal(t, data, append([]byte{}, got...))
+		})
+	}
+}
+
+func TestDecryptStream_Corruption(t *testing.T) {
+	t.Parallel()
+
+	stream := encryptStream(t, testKey, bytes.Repeat([]byte{1}, 2*chunkSize+10))
+	firstChunkEnd := len(streamMagic) + chunkHeaderSize + chunkSize + 28
+
+	tests := []struct {
+		mutate func([]byte) []byte
+		key    []byte
+		name   string
+	}{
+		{
+			name:   "wrong_key",
+			key:    []byte("fedcba9876543210fedcba9876543210"),
+			mutate: func(b []byte) []byte { return b },
+		},
+		{
+			name:   "truncated_before_final_chunk",
+			key:    testKey,
+			mutate: func(b []byte) []byte { return b[:firstChunkEnd] },
+		},
+		{
+			name:   "truncated_inside_chunk",
+			key:    testKey,
+			mutate: func(b []byte) []byte { return b[:len(b)-3] },
+		},
+		{
+			name: "flipped_byte",
+			key:  testKey,
+			mutate: func(b []byte) []byte {
+				b[len(b)-1] ^= 0xFF
+				return b
+			},
+		},
+		{
+			name:   "trailing_data",
+			key:    testKey,
+			mutate: func(b []byte) []byte { return append(b, 0) },
+		},
+		{
+			name:   "unknown_format",
+			key:    testKey,
+			mutate: func(b []byte) []byte { return append([]byte("PLAIN"), b...) },
+		},
+		{
+			name: "forged_final_flag",
+			key:  testKey,
+			mutate: func(b []byte) []byte {
+				b[len(streamMagic)] = 1
+				return b[:firstChunkEnd]
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			data := tt.mutate(append([]byte{}, stream...))
+			_, err := io.ReadAll(newDecryptReader(bytes.NewReader(data), tt.key))
+			require.Error(t, err)
+			assert.ErrorIs(t, err, ErrCorruptedSnapshot)
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package buildinfo
+
+import (
+	"runtime/debug"
+	"strconv"
+)
+
+// unknown is the value of build metadata not injected at build time.
+const unknown = "N/A"
+
+// KeyDependencies lists the modules whose versions are reported in build details.
+var KeyDependencies = []string{
+	"github.com/gin-gonic/gin",
+	"github.com/golang-jwt/jwt/v5",
+	"github.com/jackc/pgx/v5",
+	"go.uber.org/fx",
+	"go.uber.org/zap",
+	"golang.org/x/crypto",
+}
+
+// Details holds the metadata of the running build.
+type Details struct {
+	// Dependencies maps the key dependency modules to their versions.
+	Dependencies map[string]string
+	// Version contains the application version.
+	Version string
+	// Commit contains the Git commit hash of the build.
+	Commit string
+	// Date contains the build timestamp in RFC3339 format.
+	Date string
+	// GoVersion contains the version of the Go toolchain that built the binary.
+	GoVersion string
+	// Modified reports whether the build had uncommitted changes; false when unknown.
+	Modified bool
+}
+
+// Read returns the details of the running build with the injected version, commit and date. Metadata not
+// injected at build time is taken from the VCS information the Go toolchain embeds in the binary, when available.
+func Read(version, commit, date string) *Details {
+	info, _ := debug.ReadBuildInfo()
+	return newDetails(info, version, commit, date)
+}
+
+// newDetails combines the injected metadata with the embedded build information, which may be nil.
+func newDetails(info *debug.BuildInfo, version, commit, date string) *Details {
+	d := &Details{
+		Dependencies: make(map[string]string),
+		Version:      version,
+		Commit:       commit,
+		Date:         date,
+		GoVersion:    unknown,
+	}
+	if info == nil {
+		return d
+	}
+
+	d.GoVersion = info.GoVersion
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			if d.Commit == unknown {
+				d.Commit = setting.Value
+			}
+		case "vcs.time":
+			if d.Date == unknown {
+				d.Date = setting.Value
+			}
+		case "vcs.modified":
+			d.Modified, _ = strconv.ParseBool(setting.Value)
+		}
+	}
+	for _, dep := range info.Deps {
+		for _, key := range KeyDependencies {
+			if dep.Path != key {
+				continue
+			}
+			if dep.Replace != nil {
+				dep = dep.Replace
+			}
+			d.Dependencies[key] = dep.Version
+		}
+	}
+	return d
+}
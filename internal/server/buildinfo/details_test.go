@@ -0,0 +1,91 @@
+package buildinfo
+
+import (
+	"runtime/debug"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewDetails(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		info *debug.BuildInfo
+		want *Details
+		name string
+	}{
+		{
+			name: "no build information",
+			want: &Details{
+				Dependencies: map[string]string{},
+				Version:      "v1.4.2",
+				Commit:       unknown,
+				Date:         unknown,
+				GoVersion:    unknown,
+			},
+		},
+		{
+			name: "embedded build information",
+			info: &debug.BuildInfo{
+				GoVersion: "go1.24.4",
+				Deps: []*debug.Module{
+					{Path: "github.com/gin-gonic/gin", Version: "v1.10.1"},
+					{Path: "go.uber.org/zap", Version: "v1.27.0", Replace: &debug.Module{Version: "v1.27.1"}},
+					{Path: "github.com/example/unlisted", Version: "v0.1.0"},
+				},
+				Settings: []debug.BuildSetting{
+					{Key: "vcs.revision", Value: "0b712a2"},
+					{Key: "vcs.time", Value: "2026-10-15T12:00:00Z"},
+					{Key: "vcs.modified", Value: "true"},
+				},
+			},
+			want: &Details{
+				Dependencies: map[string]string{
+					"github.com/gin-gonic/gin": "v1.10.1",
+					"go.uber.org/zap":          "v1.27.1",
+				},
+				Version:   "v1.4.2",
+				Commit:    "0b712a2",
+				Date:      "2026-10-15T12:00:00Z",
+				GoVersion: "go1.24.4",
+				Modified:  true,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, newDetails(tt.info, "v1.4.2", unknown, unknown))
+		})
+	}
+}
+
+func TestRead(t *testing.T) {
+	t.Parallel()
+
+	got := Read("v1.4.2", "abc123", "2026-10-15T12:00:00Z")
+
+	require.NotNil(t, got)
+	assert.Equal(t, "v1.4.2", got.Version)
+	assert.Equal(t, "abc123", got.Commit)
+	assert.NotEmpty(t, got.GoVersion)
+	assert.NotNil(t, got.Dependencies)
+}
+
+func TestNewDetailsKeepsInjectedMetadata(t *testing.T) {
+	t.Parallel()
+
+	info := &debug.BuildInfo{Settings: []debug.BuildSetting{
+		{Key: "vcs.revision", Value: "0b712a2"},
+		{Key: "vcs.time", Value: "2026-10-15T12:00:00Z"},
+	}}
+
+	got := newDetails(info, "v1.4.2", "abc123", "2026-10-14T08:00:00Z")
+
+	assert.Equal(t, "abc123", got.Commit)
+	assert.Equal(t, "2026-10-14T08:00:00Z", got.Date)
+}
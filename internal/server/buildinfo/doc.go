@@ -1,5 +1,6 @@
 // Package buildinfo provides build metadata injection and management for the AegisVaultKeeper server.
 //
 // This package handles injection of build-time information such as version,
-// commit hash, and build date into the application.
+// commit hash, and build date into the application, and reads the Go version, VCS state and key dependency
+// versions embedded in the binary.
 package buildinfo
@@ -0,0 +1,6 @@
+// Package captcha verifies that requests come from humans for the AegisVaultKeeper server.
+//
+// This package checks hCaptcha and Cloudflare Turnstile responses with the siteverify API of the provider,
+// and implements a stateless proof-of-work scheme: the server signs puzzles instead of storing them, and
+// clients solve a puzzle by finding a nonce whose SHA-256 hash with it has enough leading zero bits.
+package captcha
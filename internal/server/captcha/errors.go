@@ -0,0 +1,9 @@
+package captcha
+
+import "errors"
+
+// Verification error definitions.
+var (
+	// ErrRejected indicates that the challenge response is missing, invalid, expired or already used.
+	ErrRejected = errors.New("challenge response rejected")
+)
@@ -0,0 +1,156 @@
+package captcha
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"math/bits"
+	"net/netip"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/crypto"
+)
+
+// Proof-of-work puzzle parameters.
+const (
+	// puzzleNonceSize is the size of the random part of a puzzle.
+	puzzleNonceSize = 16
+	// puzzlePayloadSize is the size of a puzzle payload: the random part, the expiry and the difficulty.
+	puzzlePayloadSize = puzzleNonceSize + 8 + 1
+	// maxSolutionLen bounds the length of the nonce found by the client.
+	maxSolutionLen = 64
+	// maxUsedPuzzles bounds the number of solved puzzles remembered until they expire, so a flood of
+	// solutions cannot exhaust the server memory; solutions beyond it are rejected.
+	maxUsedPuzzles = 100_000
+)
+
+// Puzzle is a proof-of-work puzzle issued to a client.
+type Puzzle struct {
+	// ExpiresAt indicates when solutions of the puzzle stop being accepted.
+	ExpiresAt time.Time
+	// Value contains the signed puzzle the client hashes with its nonce.
+	Value string
+	// Difficulty is the number of leading zero bits the SHA-256 hash of "<value>:<nonce>" must have.
+	Difficulty int
+}
+
+// ProofOfWork issues and verifies stateless proof-of-work puzzles. Puzzles are signed rather than stored,
+// so any instance sharing the key verifies them; solved puzzles are remembered until they expire, so every
+// puzzle is accepted once.
+type ProofOfWork struct {
+	// key signs the puzzles (sensitive data).
+	key []byte
+	// used maps solved puzzles to their expiry.
+	used map[string]time.Time
+	// now returns the current time.
+	now func() time.Time
+	// ttl specifies how long an issued puzzle may be solved.
+	ttl time.Duration
+	// difficulty is the number of leading zero bits required from solutions of issued puzzles.
+	difficulty int
+	// mu guards used.
+	mu sync.Mutex
+}
+
+// NewProofOfWork creates a new ProofOfWork signing puzzles with the key, requiring difficulty leading zero bits
+// and accepting solutions for ttl.
+func NewProofOfWork(key []byte, difficulty int, ttl time.Duration) *ProofOfWork {
+	return &ProofOfWork{
+		key:        key,
+		used:       make(map[string]time.Time),
+		now:        time.Now,
+		ttl:        ttl,
+		difficulty: difficulty,
+	}
+}
+
+// Issue creates a new puzzle.
+func (p *ProofOfWork) Issue() (Puzzle, error) {
+	payload := make([]byte, puzzlePayloadSize)
+	if _, err := rand.Read(payload[:puzzleNonceSize]); err != nil {
+		return Puzzle{}, fmt.Errorf("failed to generate puzzle: %w", err)
+	}
+	expiresAt := p.now().Add(p.ttl).Truncate(time.Second)
+	binary.BigEndian.PutUint64(payload[puzzleNonceSize:], uint64(expiresAt.Unix()))
+	payload[puzzlePayloadSize-1] = byte(p.difficulty)
+
+	value := base64.RawURLEncoding.EncodeToString(payload) + "." +
+		base64.RawURLEncoding.EncodeToString(crypto.SignHMACSHA256(p.key, payload))
+	return Puzzle{Value: value, ExpiresAt: expiresAt, Difficulty: p.difficulty}, nil
+}
+
+// Verify checks a solution in the form "<puzzle>:<nonce>". The puzzle must be signed with the key, unexpired
+// and not solved before, and the SHA-256 hash of the whole solution must have the difficulty of the puzzle in
+// leading zero bits. Invalid solutions are rejected with ErrRejected.
+func (p *ProofOfWork) Verify(_ context.Context, response string, _ netip.Addr) error {
+	puzzle, nonce, ok := strings.Cut(response, ":")
+	if !ok || nonce == "" || len(nonce) > maxSolutionLen {
+		return fmt.Errorf("malformed proof-of-work solution: %w", ErrRejected)
+	}
+	expiresAt, difficulty, err := p.open(puzzle)
+	if err != nil {
+		return err
+	}
+	now := p.now()
+	if !now.Before(expiresAt) {
+		return fmt.Errorf("proof-of-work puzzle expired: %w", ErrRejected)
+	}
+	sum := sha256.Sum256([]byte(response))
+	if leadingZeroBits(sum[:]) < difficulty {
+		return fmt.Errorf("proof-of-work solution below difficulty %d: %w", difficulty, ErrRejected)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, solved := p.used[puzzle]; solved {
+		return fmt.Errorf("proof-of-work puzzle already solved: %w", ErrRejected)
+	}
+	if len(p.used) >= maxUsedPuzzles {
+		for k, exp := range p.used {
+			if !now.Before(exp) {
+				delete(p.used, k)
+			}
+		}
+		if len(p.used) >= maxUsedPuzzles {
+			return fmt.Errorf("too many unexpired proof-of-work solutions: %w", ErrRejected)
+		}
+	}
+	p.used[puzzle] = expiresAt
+	return nil
+}
+
+// open checks the signature of a puzzle and returns its expiry and difficulty.
+func (p *ProofOfWork) open(puzzle string) (time.Time, int, error) {
+	encPayload, encTag, ok := strings.Cut(puzzle, ".")
+	if !ok {
+		return time.Time{}, 0, fmt.Errorf("malformed proof-of-work puzzle: %w", ErrRejected)
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encPayload)
+	if err != nil || len(payload) != puzzlePayloadSize {
+		return time.Time{}, 0, fmt.Errorf("malformed proof-of-work puzzle: %w", ErrRejected)
+	}
+	tag, err := base64.RawURLEncoding.DecodeString(encTag)
+	if err != nil || !crypto.VerifyHMACSHA256(p.key, payload, tag) {
+		return time.Time{}, 0, fmt.Errorf("proof-of-work puzzle not issued by this server: %w", ErrRejected)
+	}
+	expiresAt := time.Unix(int64(binary.BigEndian.Uint64(payload[puzzleNonceSize:])), 0)
+	return expiresAt, int(payload[puzzlePayloadSize-1]), nil
+}
+
+// leadingZeroBits returns the number of leading zero bits of b.
+func leadingZeroBits(b []byte) int {
+	n := 0
+	for _, x := range b {
+		if x != 0 {
+			return n + bits.LeadingZeros8(x)
+		}
+		n += 8
+	}
+	return n
+}
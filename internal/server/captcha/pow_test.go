@@ -0,0 +1,159 @@
+package captcha
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/netip"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// solve finds a nonce solving the puzzle by brute force.
+func solve(t *testing.T, puzzle Puzzle) string {
+	t.Helper()
+
+	for i := 0; ; i++ {
+		response := puzzle.Value + ":" + strconv.Itoa(i)
+		sum := sha256.Sum256([]byte(response))
+		if leadingZeroBits(sum[:]) >= puzzle.Difficulty {
+			return response
+		}
+	}
+}
+
+// unsolve finds a nonce that does not solve the puzzle.
+func unsolve(t *testing.T, puzzle Puzzle) string {
+	t.Helper()
+
+	for i := 0; ; i++ {
+		response := puzzle.Value + ":" + strconv.Itoa(i)
+		sum := sha256.Sum256([]byte(response))
+		if leadingZeroBits(sum[:]) < puzzle.Difficulty {
+			return response
+		}
+	}
+}
+
+func TestProofOfWork_Verify(t *testing.T) {
+	t.Parallel()
+
+	key := []byte("01234567890123456789012345678901")
+
+	tests := []struct {
+		response func(t *testing.T, p *ProofOfWork) string
+		name     string
+		after    time.Duration
+		wantErr  bool
+	}{
+		{
+			name:     "solved puzzle",
+			response: func(t *testing.T, p *ProofOfWork) string { return solve(t, issue(t, p)) },
+		},
+		{
+			name:     "unsolved puzzle",
+			response: func(t *testing.T, p *ProofOfWork) string { return unsolve(t, issue(t, p)) },
+			wantErr:  true,
+		},
+		{
+			name:     "expired puzzle",
+			response: func(t *testing.T, p *ProofOfWork) string { return solve(t, issue(t, p)) },
+			after:    time.Minute,
+			wantErr:  true,
+		},
+		{
+			name: "puzzle of another server",
+			response: func(t *testing.T, _ *ProofOfWork) string {
+				return solve(t, issue(t, NewProofOfWork([]byte("another key"), 8, time.Minute)))
+			},
+			wantErr: true,
+		},
+		{
+			name: "tampered difficulty",
+			response: func(t *testing.T, p *ProofOfWork) string {
+				puzzle := issue(t, p)
+				encPayload, encTag, _ := strings.Cut(puzzle.Value, ".")
+				payload, err := base64.RawURLEncoding.DecodeString(encPayload)
+				require.NoError(t, err)
+				payload[puzzlePayloadSize-1] = 0
+				return base64.RawURLEncoding.EncodeToString(payload) + "." + encTag + ":1"
+			},
+			wantErr: true,
+		},
+		{
+			name:     "missing nonce",
+			response: func(t *testing.T, p *ProofOfWork) string { return issue(t, p).Value },
+			wantErr:  true,
+		},
+		{
+			name:     "malformed puzzle",
+			response: func(*testing.T, *ProofOfWork) string { return "garbage:1" },
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+			p := NewProofOfWork(key, 8, time.Minute)
+			p.now = func() time.Time { return now }
+			response := tt.response(t, p)
+			now = now.Add(tt.after)
+
+			err := p.Verify(context.Background(), response, netip.Addr{})
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrRejected)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestProofOfWork_VerifyOnce(t *testing.T) {
+	t.Parallel()
+
+	p := NewProofOfWork([]byte("key"), 4, time.Minute)
+	response := solve(t, issue(t, p))
+
+	require.NoError(t, p.Verify(context.Background(), response, netip.Addr{}))
+	require.ErrorIs(t, p.Verify(context.Background(), response, netip.Addr{}), ErrRejected,
+		"a puzzle should be accepted once")
+}
+
+func TestProofOfWork_Issue(t *testing.T) {
+	t.Parallel()
+
+	p := NewProofOfWork([]byte("key"), 20, 5*time.Minute)
+	p.now = func() time.Time { return time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC) }
+
+	first, second := issue(t, p), issue(t, p)
+	assert.NotEqual(t, first.Value, second.Value)
+	assert.Equal(t, 20, first.Difficulty)
+	assert.Equal(t, time.Date(2025, 1, 1, 12, 5, 0, 0, time.UTC), first.ExpiresAt.UTC())
+}
+
+func TestLeadingZeroBits(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, 0, leadingZeroBits([]byte{0x80}))
+	assert.Equal(t, 7, leadingZeroBits([]byte{0x01}))
+	assert.Equal(t, 12, leadingZeroBits([]byte{0x00, 0x0f}))
+	assert.Equal(t, 16, leadingZeroBits([]byte{0x00, 0x00}))
+}
+
+// issue issues a puzzle and fails the test on error.
+func issue(t *testing.T, p *ProofOfWork) Puzzle {
+	t.Helper()
+
+	puzzle, err := p.Issue()
+	require.NoError(t, err)
+	return puzzle
+}
@@ -0,0 +1,67 @@
+package captcha
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSiteVerifier_Verify(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		wantErr  error
+		name     string
+		body     string
+		response string
+		status   int
+		failed   bool
+	}{
+		{name: "valid response", response: "token", status: http.StatusOK, body: `{"success":true}`},
+		{
+			name:     "refused response",
+			response: "token",
+			status:   http.StatusOK,
+			body:     `{"success":false,"error-codes":["invalid-input-response"]}`,
+			wantErr:  ErrRejected,
+		},
+		{name: "empty response", response: "", wantErr: ErrRejected},
+		{name: "provider failure", response: "token", status: http.StatusInternalServerError, failed: true},
+		{name: "malformed answer", response: "token", status: http.StatusOK, body: `<html>`, failed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.NoError(t, r.ParseForm())
+				assert.Equal(t, "site-secret", r.PostForm.Get("secret"))
+				assert.Equal(t, tt.response, r.PostForm.Get("response"))
+				assert.Equal(t, "203.0.113.7", r.PostForm.Get("remoteip"))
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			t.Cleanup(server.Close)
+
+			err := NewSiteVerifier(server.Client(), server.URL, "site-secret").
+				Verify(context.Background(), tt.response, netip.MustParseAddr("203.0.113.7"))
+
+			switch {
+			case tt.wantErr != nil:
+				require.ErrorIs(t, err, tt.wantErr)
+			case tt.failed:
+				require.Error(t, err)
+				assert.NotErrorIs(t, err, ErrRejected, "a provider failure is not a refused response")
+				assert.NotContains(t, err.Error(), "site-secret")
+			default:
+				require.NoError(t, err)
+			}
+		})
+	}
+}
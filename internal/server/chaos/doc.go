@@ -0,0 +1,7 @@
+// Package chaos provides fault injection for resilience testing of the AegisVaultKeeper server.
+//
+// Faults add latency, fail requests or drop connections with a configured probability, so the retry logic
+// of clients and the error handling of the server can be exercised against a realistic deployment. Rules
+// select the HTTP routes faults are injected into; repository faults apply to every database operation.
+// Fault injection is disabled unless explicitly enabled and must never be enabled in production.
+package chaos
@@ -0,0 +1,135 @@
+package chaos
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Kind identifies the effect of a fault.
+type Kind string
+
+// Fault kinds.
+const (
+	// KindLatency delays the operation.
+	KindLatency Kind = "latency"
+	// KindError fails the operation.
+	KindError Kind = "error"
+	// KindDrop closes the client connection without a response.
+	KindDrop Kind = "drop"
+)
+
+// ErrInjected is returned by operations failed by an injected fault.
+var ErrInjected = errors.New("injected fault")
+
+// Fault is an effect injected into an operation with a probability.
+type Fault struct {
+	// Kind identifies the effect.
+	Kind Kind
+	// Probability is the chance in [0, 1] that the fault is injected into an operation.
+	Probability float64
+	// Latency is the delay added by latency faults.
+	Latency time.Duration
+	// Status is the HTTP status code of requests failed by error faults.
+	Status int
+}
+
+// Fires reports whether the fault is injected into the current operation, drawing from roll or from the
+// default source when roll is nil.
+func (f Fault) Fires(roll func() float64) bool {
+	if roll == nil {
+		roll = rand.Float64
+	}
+	return roll() < f.Probability
+}
+
+// Rule selects the HTTP requests a fault is injected into.
+type Rule struct {
+	// Method is the request method the rule applies to (empty matches any).
+	Method string
+	// PathPrefix is the prefix of the request paths the rule applies to.
+	PathPrefix string
+	// Fault is the injected fault.
+	Fault Fault
+}
+
+// Matches reports whether the rule applies to a request.
+func (r Rule) Matches(method, path string) bool {
+	return (r.Method == "" || r.Method == method) && strings.HasPrefix(path, r.PathPrefix)
+}
+
+// ParseFault parses a fault in the KIND:PROBABILITY[:PARAM] format: latency requires a duration parameter,
+// error accepts an optional HTTP status code (503 by default) and drop accepts none.
+func ParseFault(entry string) (Fault, error) {
+	parts := strings.Split(strings.TrimSpace(entry), ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return Fault{}, errors.New("expected KIND:PROBABILITY[:PARAM]")
+	}
+
+	p, err := strconv.ParseFloat(parts[1], 64)
+	if err != nil || p < 0 || p > 1 {
+		return Fault{}, fmt.Errorf("probability must be a number between 0 and 1, got %q", parts[1])
+	}
+	f := Fault{Kind: Kind(parts[0]), Probability: p}
+	// param holds the optional kind-specific parameter.
+	var param string
+	if len(parts) == 3 {
+		param = parts[2]
+	}
+
+	switch f.Kind {
+	case KindLatency:
+		d, err := time.ParseDuration(param)
+		if err != nil || d <= 0 {
+			return Fault{}, fmt.Errorf("latency requires a positive duration, got %q", param)
+		}
+		f.Latency = d
+	case KindError:
+		f.Status = http.StatusServiceUnavailable
+		if param != "" {
+			status, err := strconv.Atoi(param)
+			if err != nil || status < 400 || status > 599 {
+				return Fault{}, fmt.Errorf("error status must be an HTTP error code, got %q", param)
+			}
+			f.Status = status
+		}
+	case KindDrop:
+		if param != "" {
+			return Fault{}, errors.New("drop takes no parameter")
+		}
+	default:
+		return Fault{}, fmt.Errorf("unknown fault kind %q: must be latency, error or drop", parts[0])
+	}
+	return f, nil
+}
+
+// ParseRule parses a rule in the [METHOD ]PATH_PREFIX=FAULT format, such as "POST /api/items=error:0.1:503".
+func ParseRule(entry string) (Rule, error) {
+	route, fault, ok := strings.Cut(strings.TrimSpace(entry), "=")
+	if !ok {
+		return Rule{}, errors.New("expected [METHOD ]PATH_PREFIX=KIND:PROBABILITY[:PARAM]")
+	}
+
+	// r holds the parsed rule.
+	var r Rule
+	route = strings.TrimSpace(route)
+	if method, path, ok := strings.Cut(route, " "); ok {
+		r.Method = strings.ToUpper(method)
+		route = strings.TrimSpace(path)
+	}
+	if !strings.HasPrefix(route, "/") {
+		return Rule{}, fmt.Errorf("path prefix must start with /, got %q", route)
+	}
+	r.PathPrefix = route
+
+	f, err := ParseFault(fault)
+	if err != nil {
+		return Rule{}, err
+	}
+	r.Fault = f
+	return r, nil
+}
@@ -0,0 +1,99 @@
+package chaos
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRule(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		entry   string
+		wantErr string
+		want    Rule
+	}{
+		{
+			name:  "latency on any method",
+			entry: "/api/items=latency:0.25:300ms",
+			want: Rule{
+				PathPrefix: "/api/items",
+				Fault:      Fault{Kind: KindLatency, Probability: 0.25, Latency: 300 * time.Millisecond},
+			},
+		},
+		{
+			name:  "error with status",
+			entry: " post /api/items/notes = error:0.1:502 ",
+			want: Rule{
+				Method:     http.MethodPost,
+				PathPrefix: "/api/items/notes",
+				Fault:      Fault{Kind: KindError, Probability: 0.1, Status: http.StatusBadGateway},
+			},
+		},
+		{
+			name:  "error with default status",
+			entry: "/api=error:1",
+			want: Rule{
+				PathPrefix: "/api",
+				Fault:      Fault{Kind: KindError, Probability: 1, Status: http.StatusServiceUnavailable},
+			},
+		},
+		{
+			name:  "drop",
+			entry: "GET /api/items/sync=drop:0.05",
+			want: Rule{
+				Method:     http.MethodGet,
+				PathPrefix: "/api/items/sync",
+				Fault:      Fault{Kind: KindDrop, Probability: 0.05},
+			},
+		},
+		{name: "missing fault", entry: "/api", wantErr: "expected"},
+		{name: "relative path", entry: "api=drop:1", wantErr: "must start with /"},
+		{name: "missing probability", entry: "/api=drop", wantErr: "expected KIND"},
+		{name: "probability above one", entry: "/api=drop:1.5", wantErr: "between 0 and 1"},
+		{name: "latency without duration", entry: "/api=latency:0.5", wantErr: "positive duration"},
+		{name: "success status", entry: "/api=error:0.5:200", wantErr: "HTTP error code"},
+		{name: "drop with parameter", entry: "/api=drop:0.5:1s", wantErr: "no parameter"},
+		{name: "unknown kind", entry: "/api=explode:0.5", wantErr: "unknown fault kind"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseRule(tt.entry)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRule_Matches(t *testing.T) {
+	t.Parallel()
+
+	r := Rule{Method: http.MethodPut, PathPrefix: "/api/items"}
+	assert.True(t, r.Matches(http.MethodPut, "/api/items/notes/1"))
+	assert.False(t, r.Matches(http.MethodGet, "/api/items/notes/1"))
+	assert.False(t, r.Matches(http.MethodPut, "/api/auth/login"))
+	assert.True(t, Rule{PathPrefix: "/"}.Matches(http.MethodDelete, "/api/items/notes/1"))
+}
+
+func TestFault_Fires(t *testing.T) {
+	t.Parallel()
+
+	f := Fault{Probability: 0.3}
+	assert.True(t, f.Fires(func() float64 { return 0.29 }))
+	assert.False(t, f.Fires(func() float64 { return 0.3 }))
+	assert.False(t, Fault{}.Fires(nil), "zero probability should never fire")
+	assert.True(t, Fault{Probability: 1}.Fires(nil), "probability one should always fire")
+}
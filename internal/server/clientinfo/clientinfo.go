@@ -0,0 +1,35 @@
+package clientinfo
+
+import (
+	"context"
+	"net/netip"
+)
+
+// ipv6NetworkBits is the prefix length IPv6 clients are attributed to, since a single subscriber usually
+// holds a whole /64 and can rotate addresses within it at will.
+const ipv6NetworkBits = 64
+
+// ipCtxKey is the context key under which the client IP address is stored.
+type ipCtxKey struct{}
+
+// WithIP returns a copy of ctx carrying the client IP address.
+func WithIP(ctx context.Context, ip netip.Addr) context.Context {
+	return context.WithValue(ctx, ipCtxKey{}, ip)
+}
+
+// IP returns the client IP address stored in ctx, if any.
+func IP(ctx context.Context) (netip.Addr, bool) {
+	ip, ok := ctx.Value(ipCtxKey{}).(netip.Addr)
+	return ip, ok && ip.IsValid()
+}
+
+// Network returns the network a client address is attributed to when counting its requests: the address itself
+// for IPv4 and its /64 for IPv6.
+func Network(ip netip.Addr) netip.Prefix {
+	ip = ip.Unmap()
+	if ip.Is4() {
+		return netip.PrefixFrom(ip, ip.BitLen())
+	}
+	p, _ := ip.Prefix(ipv6NetworkBits)
+	return p
+}
@@ -0,0 +1,67 @@
+package clientinfo
+
+import (
+	"context"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIP(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		ctx    context.Context
+		want   netip.Addr
+		name   string
+		wantOK bool
+	}{
+		{
+			name:   "stored address",
+			ctx:    WithIP(context.Background(), netip.MustParseAddr("203.0.113.7")),
+			want:   netip.MustParseAddr("203.0.113.7"),
+			wantOK: true,
+		},
+		{
+			name: "missing address",
+			ctx:  context.Background(),
+		},
+		{
+			name: "invalid address",
+			ctx:  WithIP(context.Background(), netip.Addr{}),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := IP(tt.ctx)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNetwork(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		ip   string
+		want string
+	}{
+		{name: "IPv4 address", ip: "203.0.113.7", want: "203.0.113.7/32"},
+		{name: "IPv4-mapped IPv6 address", ip: "::ffff:203.0.113.7", want: "203.0.113.7/32"},
+		{name: "IPv6 address", ip: "2001:db8:1:2:3:4:5:6", want: "2001:db8:1:2::/64"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, netip.MustParsePrefix(tt.want), Network(netip.MustParseAddr(tt.ip)))
+		})
+	}
+}
@@ -0,0 +1,5 @@
+// Package clientinfo carries attributes of the calling client through request contexts.
+//
+// The delivery layer resolves the client address once per request, and application
+// services and recorders read it back without depending on HTTP types.
+package clientinfo
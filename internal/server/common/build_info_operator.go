@@ -1,6 +1,10 @@
 package common
 
-import "time"
+import (
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/buildinfo"
+)
 
 // BuildInfoOperator provides access to application build information and metadata.
 type BuildInfoOperator struct {
@@ -40,3 +44,8 @@ func (b *BuildInfoOperator) Date() time.Time {
 func (b *BuildInfoOperator) Commit() string {
 	return b.buildCommit
 }
+
+// Details returns the build metadata with the Go version, VCS state and key dependency versions of the binary.
+func (b *BuildInfoOperator) Details() *buildinfo.Details {
+	return buildinfo.Read(b.buildVersion, b.buildCommit, b.buildDate)
+}
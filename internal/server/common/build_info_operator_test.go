@@ -198,3 +198,16 @@ func TestBuildInfoOperator_Commit(t *testing.T) {
 		})
 	}
 }
+
+// TestBuildInfoOperator_Details tests the Details method.
+func TestBuildInfoOperator_Details(t *testing.T) {
+	operator := NewBuildInfoOperator("v1.0.0", "2023-01-01T12:00:00Z", "abc123")
+	result := operator.Details()
+
+	if result.Version != "v1.0.0" || result.Date != "2023-01-01T12:00:00Z" || result.Commit != "abc123" {
+		t.Errorf("Details() = %+v, want the operator build metadata", result)
+	}
+	if result.GoVersion == "" || result.Dependencies == nil {
+		t.Errorf("Details() = %+v, want the embedded build information", result)
+	}
+}
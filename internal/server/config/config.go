@@ -1,14 +1,35 @@
 package config
 
 import (
+	"bytes"
 	"crypto/sha256"
 	"errors"
 	"fmt"
+	"net/mail"
+	"net/netip"
+	"net/url"
 	"os"
 	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
+	defaults "github.com/gdyunin/aegis-vault-keeper/config"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/chaos"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/crypto"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/auth"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/authz"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/feature"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/invite"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/onboarding"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/plan"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/license"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/notifier"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/release"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/securebytes"
+	"github.com/google/uuid"
 	"github.com/mitchellh/mapstructure"
 	"github.com/spf13/viper"
 )
@@ -16,6 +37,32 @@ import (
 // masterKeyMinLen defines the minimum required length for the master encryption key.
 const masterKeyMinLen = 16
 
+// adminTokenMinLen defines the minimum required length for the admin API token.
+const adminTokenMinLen = 32
+
+// adminOperatorShared names the operator authenticated by the admin API token, reserved among operator names.
+const adminOperatorShared = "admin"
+
+// integrityKeyDomain separates the integrity key derived from the master key from the encryption key.
+const integrityKeyDomain = "aegis-vault-keeper/row-integrity/"
+
+// backupKeyDomain separates the backup key derived from the master key from the other derived keys.
+const backupKeyDomain = "aegis-vault-keeper/backup/"
+
+// challengeKeyDomain separates the proof-of-work puzzle signing key derived from the master key from the other
+// derived keys.
+const challengeKeyDomain = "aegis-vault-keeper/challenge/"
+
+// recordingKeyDomain separates the key encrypting recorded requests derived from the master key from the other
+// derived keys.
+const recordingKeyDomain = "aegis-vault-keeper/recording/"
+
+// sessionKeyDomain separates the CSRF token signing key derived from the master key from the other derived keys.
+const sessionKeyDomain = "aegis-vault-keeper/session/"
+
+// challengePoWMaxDifficulty bounds the proof-of-work difficulty, so solving a puzzle stays feasible in a browser.
+const challengePoWMaxDifficulty = 32
+
 // Config contains all configuration parameters for the AegisVaultKeeper server application.
 type Config struct {
 	// FileStorageBasePath specifies the base directory for file storage operations.
@@ -36,30 +83,366 @@ type Config struct {
 	TLSKeyFile string `mapstructure:"TLS_KEY_FILE"`
 	// PostgresUser specifies the database username for authentication.
 	PostgresUser string `mapstructure:"POSTGRES_USER"`
+	// BackupStorage specifies the snapshot store type (local, s3).
+	BackupStorage string `mapstructure:"BACKUP_STORAGE"`
+	// BackupLocalPath specifies the directory holding snapshots of the local store.
+	BackupLocalPath string `mapstructure:"BACKUP_LOCAL_PATH"`
+	// BackupS3Endpoint specifies the base URL of the S3-compatible snapshot store.
+	BackupS3Endpoint string `mapstructure:"BACKUP_S3_ENDPOINT"`
+	// BackupS3Region specifies the signing region of the S3-compatible snapshot store.
+	BackupS3Region string `mapstructure:"BACKUP_S3_REGION"`
+	// BackupS3Bucket specifies the bucket holding snapshots in the S3-compatible store.
+	BackupS3Bucket string `mapstructure:"BACKUP_S3_BUCKET"`
+	// BackupS3AccessKeyID specifies the access key identifier of the S3-compatible snapshot store.
+	BackupS3AccessKeyID string `mapstructure:"BACKUP_S3_ACCESS_KEY_ID"`
+	// BackupS3SecretAccessKey contains the secret key of the S3-compatible snapshot store (sensitive data).
+	BackupS3SecretAccessKey string `mapstructure:"BACKUP_S3_SECRET_ACCESS_KEY"`
+	// SMTPHost specifies the SMTP relay hostname used for outgoing mail (empty disables mail delivery).
+	SMTPHost string `mapstructure:"SMTP_HOST"`
+	// SMTPUsername specifies the SMTP relay username (empty disables authentication).
+	SMTPUsername string `mapstructure:"SMTP_USERNAME"`
+	// SMTPPassword contains the SMTP relay password (sensitive data).
+	SMTPPassword string `mapstructure:"SMTP_PASSWORD"`
+	// SMTPFrom specifies the sender address of outgoing mail.
+	SMTPFrom string `mapstructure:"SMTP_FROM"`
+	// TelegramBotToken contains the token of the Telegram bot notifications are sent by
+	// (sensitive data, empty disables Telegram channels).
+	TelegramBotToken string `mapstructure:"TELEGRAM_BOT_TOKEN"`
+	// WebPushVAPIDPrivateKey contains the base64url-encoded P-256 key web push messages are signed with
+	// (sensitive data, empty disables web push channels).
+	WebPushVAPIDPrivateKey string `mapstructure:"WEBPUSH_VAPID_PRIVATE_KEY"`
+	// WebPushVAPIDSubject specifies the mailto: or https: contact of the operator announced to push services.
+	WebPushVAPIDSubject string `mapstructure:"WEBPUSH_VAPID_SUBJECT"`
+	// CORSAllowedOrigins lists origins allowed to call the API ("*" allows any, empty disables CORS).
+	CORSAllowedOrigins []string `mapstructure:"CORS_ALLOWED_ORIGINS"`
+	// CORSAllowedMethods lists HTTP methods allowed in cross-origin requests.
+	CORSAllowedMethods []string `mapstructure:"CORS_ALLOWED_METHODS"`
+	// CORSAllowedHeaders lists request headers allowed in cross-origin requests.
+	CORSAllowedHeaders []string `mapstructure:"CORS_ALLOWED_HEADERS"`
+	// TrustedProxies lists CIDRs or addresses of reverse proxies allowed to forward client IPs.
+	TrustedProxies []string `mapstructure:"TRUSTED_PROXIES"`
+	// FeatureFlags lists deployment default feature flags as key=true or key=false entries.
+	FeatureFlags []string `mapstructure:"FEATURE_FLAGS"`
+	// ChaosRules lists the faults injected into HTTP requests as [METHOD ]PATH_PREFIX=KIND:PROBABILITY[:PARAM]
+	// entries.
+	ChaosRules []string `mapstructure:"CHAOS_RULES"`
+	// ChaosRepositoryFaults lists the faults injected into database operations as KIND:PROBABILITY[:PARAM]
+	// entries of the latency and error kinds.
+	ChaosRepositoryFaults []string `mapstructure:"CHAOS_REPOSITORY_FAULTS"`
+	// JWTAudiences lists the audiences of issued access tokens, one of which presented tokens must name
+	// (empty skips the audience check).
+	JWTAudiences []string `mapstructure:"JWT_AUDIENCES"`
+	// TLSACMEDomains lists host names certificates are obtained for via ACME (empty uses certificate files).
+	TLSACMEDomains []string `mapstructure:"TLS_ACME_DOMAINS"`
+	// TLSACMEEmail specifies the ACME account contact address.
+	TLSACMEEmail string `mapstructure:"TLS_ACME_EMAIL"`
+	// TLSACMECacheDir specifies the directory storing ACME certificates and the account key.
+	TLSACMECacheDir string `mapstructure:"TLS_ACME_CACHE_DIR"`
+	// TLSACMEDirectoryURL specifies the ACME directory endpoint (empty uses Let's Encrypt production).
+	TLSACMEDirectoryURL string `mapstructure:"TLS_ACME_DIRECTORY_URL"`
+	// TLSACMEHTTPAddr specifies the address of the plain HTTP listener answering ACME challenges.
+	TLSACMEHTTPAddr string `mapstructure:"TLS_ACME_HTTP_ADDR"`
+	// SecurityCSP specifies the Content-Security-Policy of non-HTML responses (empty omits the header).
+	SecurityCSP string `mapstructure:"SECURITY_CSP"`
+	// SecurityHTMLCSP specifies the Content-Security-Policy of HTML pages (empty omits the header).
+	SecurityHTMLCSP string `mapstructure:"SECURITY_HTML_CSP"`
+	// SessionCookieName names the HttpOnly cookie carrying the access token of web sessions.
+	SessionCookieName string `mapstructure:"SESSION_COOKIE_NAME"`
+	// SessionCSRFCookieName names the cookie carrying the CSRF token of web sessions.
+	SessionCSRFCookieName string `mapstructure:"SESSION_CSRF_COOKIE_NAME"`
+	// SessionCookieDomain specifies the domain of the session cookies (empty limits them to the server host).
+	SessionCookieDomain string `mapstructure:"SESSION_COOKIE_DOMAIN"`
+	// SessionCookieSameSite restricts sending the session cookies with cross-site requests: strict, lax or none.
+	SessionCookieSameSite string `mapstructure:"SESSION_COOKIE_SAMESITE"`
+	// AdminAPIToken contains the token authorizing admin API requests (sensitive data, empty disables the API).
+	AdminAPIToken string `mapstructure:"ADMIN_API_TOKEN"`
+	// AdminOperatorTokens lists name:token pairs giving admin operators tokens of their own, which authorize admin
+	// API requests as the named operator (sensitive data).
+	AdminOperatorTokens []string `mapstructure:"ADMIN_OPERATOR_TOKENS"`
+	// AdminNotifyEmails lists the addresses mailed about destructive admin requests awaiting a second operator.
+	AdminNotifyEmails []string `mapstructure:"ADMIN_NOTIFY_EMAILS"`
+	// AdminSigningKey contains the HMAC key admin API requests must be signed with (sensitive data, empty disables).
+	AdminSigningKey string `mapstructure:"ADMIN_SIGNING_KEY"`
+	// ResponseSigningKey contains the base64-encoded Ed25519 seed responses of critical endpoints are signed with
+	// (sensitive data, empty disables).
+	ResponseSigningKey string `mapstructure:"RESPONSE_SIGNING_KEY"`
+	// SCIMAPIToken contains the bearer token authorizing SCIM provisioning requests (sensitive data, empty disables).
+	SCIMAPIToken string `mapstructure:"SCIM_API_TOKEN"`
+	// GeoIPDBPath specifies the MaxMind country database file used by country rules (empty disables them).
+	GeoIPDBPath string `mapstructure:"GEOIP_DB_PATH"`
+	// RestrictedItemsNetworks lists CIDRs or addresses items tagged restricted may be revealed from.
+	RestrictedItemsNetworks []string `mapstructure:"RESTRICTED_ITEMS_NETWORKS"`
+	// RestrictedItemsCountries lists ISO country codes items tagged restricted may be revealed from.
+	RestrictedItemsCountries []string `mapstructure:"RESTRICTED_ITEMS_COUNTRIES"`
+	// ApprovalApprovers lists the IDs of users approving access to items that require approval (empty disables
+	// the approval workflow).
+	ApprovalApprovers []string `mapstructure:"APPROVAL_APPROVERS"`
+	// AuthzPolicyFile specifies the file holding the vault item authorization policy (empty uses the owner-only
+	// default policy).
+	AuthzPolicyFile string `mapstructure:"AUTHZ_POLICY_FILE"`
+	// JWTIssuer specifies the issuer of access tokens (empty uses aegis_vault_keeper).
+	JWTIssuer string `mapstructure:"JWT_ISSUER"`
+	// JWTJWKSURL specifies the JWKS endpoint of a central identity provider whose tokens are accepted
+	// (empty disables token federation).
+	JWTJWKSURL string `mapstructure:"JWT_JWKS_URL"`
+	// JWTJWKSIssuer specifies the issuer of identity provider tokens.
+	JWTJWKSIssuer string `mapstructure:"JWT_JWKS_ISSUER"`
+	// JWTJWKSUserClaim specifies the identity provider token claim holding the user ID (empty uses sub).
+	JWTJWKSUserClaim string `mapstructure:"JWT_JWKS_USER_CLAIM"`
+	// LoginApprovalURL specifies the public server URL of login approval links sent to users (empty omits the links).
+	LoginApprovalURL string `mapstructure:"LOGIN_APPROVAL_URL"`
+	// AuthFailureLogPath specifies the file authentication failures and client bans are appended to for fail2ban
+	// (empty disables the failure log).
+	AuthFailureLogPath string `mapstructure:"AUTH_FAILURE_LOG_PATH"`
+	// ChallengeProvider specifies the human verification challenge of registrations and repeated failed logins
+	// (hcaptcha, turnstile, pow; empty disables challenges).
+	ChallengeProvider string `mapstructure:"CHALLENGE_PROVIDER"`
+	// ChallengeSiteKey specifies the public site key of the CAPTCHA widget.
+	ChallengeSiteKey string `mapstructure:"CHALLENGE_SITE_KEY"`
+	// ChallengeSecret contains the secret CAPTCHA responses are verified with (sensitive data).
+	ChallengeSecret string `mapstructure:"CHALLENGE_SECRET"`
+	// RegistrationMode specifies who may register (open, invite; invite requires an invite code; empty means open).
+	RegistrationMode string `mapstructure:"REGISTRATION_MODE"`
+	// PasswordRequiredClasses lists the character classes user passwords must contain (lower, upper, digit, symbol).
+	PasswordRequiredClasses []string `mapstructure:"PASSWORD_REQUIRED_CLASSES"`
+	// OnboardingSteps lists the steps of the onboarding wizard in order (master_key, storage, smtp, admin; admin
+	// must come last; empty uses all of them).
+	OnboardingSteps []string `mapstructure:"ONBOARDING_STEPS"`
+	// PasswordBannedFile specifies the file listing passwords rejected in addition to the common ones, one per line
+	// (empty bans only the common passwords).
+	PasswordBannedFile string `mapstructure:"PASSWORD_BANNED_FILE"`
+	// PlanDefault specifies the plan limiting every user (free, premium; empty disables plan limits).
+	PlanDefault string `mapstructure:"PLAN_DEFAULT"`
+	// StripeWebhookSecret contains the signing secret of the Stripe webhook endpoint (sensitive data; empty
+	// disables the Stripe integration).
+	StripeWebhookSecret string `mapstructure:"STRIPE_WEBHOOK_SECRET"`
+	// StripePricePlans lists the plans Stripe prices grant as price_id=plan entries.
+	StripePricePlans []string `mapstructure:"STRIPE_PRICE_PLANS"`
+	// AutofillAndroidApps lists the Android apps allowed to autofill credentials of this server as
+	// package_name=SHA256_fingerprint entries; repeat a package for several signing certificates.
+	AutofillAndroidApps []string `mapstructure:"AUTOFILL_ANDROID_APPS"`
+	// AutofillIOSApps lists the iOS apps allowed to autofill credentials of this server as TEAMID.bundle.id entries.
+	AutofillIOSApps []string `mapstructure:"AUTOFILL_IOS_APPS"`
+	// LicensePublicKey contains the base64-encoded Ed25519 public key of the license vendor (empty leaves
+	// enterprise features unlicensed and available).
+	LicensePublicKey string `mapstructure:"LICENSE_PUBLIC_KEY"`
+	// LicenseKey contains the enterprise license key used until one is installed through the admin API
+	// (sensitive data).
+	LicenseKey string `mapstructure:"LICENSE_KEY"`
+	// UpdateCheckURL contains the URL of the signed release manifest checked for updates (empty disables update
+	// checks).
+	UpdateCheckURL string `mapstructure:"UPDATE_CHECK_URL"`
+	// UpdateCheckPublicKey contains the base64-encoded Ed25519 public key release manifests are signed with.
+	UpdateCheckPublicKey string `mapstructure:"UPDATE_CHECK_PUBLIC_KEY"`
+	// TelemetryEndpoint contains the URL anonymous usage pings are posted to.
+	TelemetryEndpoint string `mapstructure:"TELEMETRY_ENDPOINT"`
 	// MasterKey contains the derived encryption key for data protection (highly sensitive).
-	MasterKey []byte
+	MasterKey securebytes.Bytes
+	// IntegrityKey contains the derived HMAC key for stored row signatures (highly sensitive).
+	IntegrityKey securebytes.Bytes
+	// BackupKey contains the derived encryption key for backup snapshots (highly sensitive).
+	BackupKey securebytes.Bytes
+	// ChallengeKey contains the derived HMAC key proof-of-work puzzles are signed with (sensitive).
+	ChallengeKey securebytes.Bytes
+	// RecordingKey contains the derived encryption key for recorded requests (highly sensitive).
+	RecordingKey securebytes.Bytes
+	// SessionKey contains the derived HMAC key the CSRF tokens of web sessions are signed with (sensitive).
+	SessionKey securebytes.Bytes
 	// PostgresInitTimeout specifies the maximum duration for database initialization.
 	PostgresInitTimeout time.Duration `mapstructure:"POSTGRES_INIT_TIMEOUT"`
+	// PostgresTxRetryBackoff specifies the delay before the first retry of a failed transaction, doubled per retry.
+	PostgresTxRetryBackoff time.Duration `mapstructure:"POSTGRES_TX_RETRY_BACKOFF"`
 	// ApplicationPort specifies the HTTP server listening port.
 	ApplicationPort int `mapstructure:"APPLICATION_PORT"`
 	// AccessTokenLifeTime specifies the JWT token validity duration.
 	AccessTokenLifeTime time.Duration `mapstructure:"ACCESS_TOKEN_LIFETIME"`
+	// JWTLeeway tolerates clock skew between services when checking token validity periods.
+	JWTLeeway time.Duration `mapstructure:"JWT_LEEWAY"`
+	// JWTJWKSCacheTTL specifies how long identity provider signing keys are cached.
+	JWTJWKSCacheTTL time.Duration `mapstructure:"JWT_JWKS_CACHE_TTL"`
 	// PostgresPort specifies the PostgreSQL server port number.
 	PostgresPort int `mapstructure:"POSTGRES_PORT"`
+	// PostgresTxRetries specifies how often transactions failing on serialization or deadlock are retried.
+	PostgresTxRetries int `mapstructure:"POSTGRES_TX_RETRIES"`
+	// CryptoWorkers limits the concurrent encryption of bulk operation items (0 uses one worker per CPU).
+	CryptoWorkers int `mapstructure:"CRYPTO_WORKERS"`
+	// FileTransferWorkers limits the file contents encrypted or decrypted concurrently (0 uses one worker per CPU).
+	FileTransferWorkers int `mapstructure:"FILE_TRANSFER_WORKERS"`
+	// BruteForceThreshold specifies how many authentication failures within the window ban a client network
+	// (0 disables the ban list).
+	BruteForceThreshold int `mapstructure:"BRUTE_FORCE_THRESHOLD"`
+	// ChallengePoWDifficulty specifies the leading zero bits proof-of-work solutions must have.
+	ChallengePoWDifficulty int `mapstructure:"CHALLENGE_POW_DIFFICULTY"`
+	// ChallengeLoginFailures specifies how many failed logins within the window make logins from a client network
+	// require a challenge (0 never requires one on login).
+	ChallengeLoginFailures int `mapstructure:"CHALLENGE_LOGIN_FAILURES"`
+	// InviteUserQuota specifies how many pending and redeemed invites each user may hold (0 reserves invites to
+	// administrators and invites granting a quota).
+	InviteUserQuota int `mapstructure:"INVITE_USER_QUOTA"`
+	// PasswordMinLength specifies the minimum length of user passwords (8 to 64; 0 uses 8).
+	PasswordMinLength int `mapstructure:"PASSWORD_MIN_LENGTH"`
+	// PasswordHistory specifies how many recent passwords of a user, the current one included, a new password may
+	// not match (0 allows reuse).
+	PasswordHistory int `mapstructure:"PASSWORD_HISTORY"`
+	// SMTPPort specifies the SMTP relay port number.
+	SMTPPort int `mapstructure:"SMTP_PORT"`
+	// MasterKeyLength holds the length of the configured master key, checked by the onboarding wizard.
+	MasterKeyLength int
+	// HTTPMaxHeaderBytes limits the size of request headers (0 uses the net/http default of 1 MB).
+	HTTPMaxHeaderBytes int `mapstructure:"HTTP_MAX_HEADER_BYTES"`
+	// NoteMergeCompactThreshold specifies how many stored updates a note edited in merge mode must have to be
+	// compacted (0 uses 100).
+	NoteMergeCompactThreshold int `mapstructure:"NOTE_MERGE_COMPACT_THRESHOLD"`
+	// RecordingMaxBodySize limits the bytes recorded of every request and response body (0 records no bodies).
+	RecordingMaxBodySize int `mapstructure:"RECORDING_MAX_BODY_SIZE"`
+	// RetentionKeepVersions specifies how many replaced versions of every item are kept (0 keeps them all).
+	RetentionKeepVersions int `mapstructure:"RETENTION_KEEP_VERSIONS"`
+	// RetentionKeepAuditDays specifies for how many days item reveal records are kept (0 keeps them forever).
+	RetentionKeepAuditDays int `mapstructure:"RETENTION_KEEP_AUDIT_DAYS"`
+	// FileStorageQuota limits the bytes the stored files of each user may occupy (0 means no limit).
+	FileStorageQuota int64 `mapstructure:"FILE_STORAGE_QUOTA"`
+	// FileTransferMemory limits the total bytes of the file contents encrypted or decrypted at once
+	// (0 means no limit).
+	FileTransferMemory int64 `mapstructure:"FILE_TRANSFER_MEMORY"`
 	// DeliveryStartTimeout specifies the maximum duration for HTTP server startup.
 	DeliveryStartTimeout time.Duration `mapstructure:"DELIVERY_START_TIMEOUT"`
 	// DeliveryStopTimeout specifies the maximum duration for HTTP server shutdown.
 	DeliveryStopTimeout time.Duration `mapstructure:"DELIVERY_STOP_TIMEOUT"`
+	// HTTPReadTimeout limits reading an entire request including the body (0 means no limit).
+	HTTPReadTimeout time.Duration `mapstructure:"HTTP_READ_TIMEOUT"`
+	// HTTPReadHeaderTimeout limits reading request headers (0 falls back to HTTPReadTimeout).
+	HTTPReadHeaderTimeout time.Duration `mapstructure:"HTTP_READ_HEADER_TIMEOUT"`
+	// HTTPWriteTimeout limits writing a response (0 means no limit).
+	HTTPWriteTimeout time.Duration `mapstructure:"HTTP_WRITE_TIMEOUT"`
+	// HTTPIdleTimeout limits how long idle keep-alive connections stay open (0 falls back to HTTPReadTimeout).
+	HTTPIdleTimeout time.Duration `mapstructure:"HTTP_IDLE_TIMEOUT"`
+	// HTTPRequestTimeout bounds handling of public, auth, account, feature and admin requests (0 means no limit).
+	HTTPRequestTimeout time.Duration `mapstructure:"HTTP_REQUEST_TIMEOUT"`
+	// HTTPItemsRequestTimeout bounds handling of item requests other than file transfers (0 means no limit).
+	HTTPItemsRequestTimeout time.Duration `mapstructure:"HTTP_ITEMS_REQUEST_TIMEOUT"`
+	// HTTPFilesRequestTimeout bounds handling of file upload and download requests (0 means no limit).
+	HTTPFilesRequestTimeout time.Duration `mapstructure:"HTTP_FILES_REQUEST_TIMEOUT"`
+	// RequestSignatureMaxSkew bounds how far the signing time of a signed request may be from the server time
+	// (0 uses 5 minutes).
+	RequestSignatureMaxSkew time.Duration `mapstructure:"REQUEST_SIGNATURE_MAX_SKEW"`
+	// IntegrityVerifyInterval specifies how often stored row signatures are verified (0 disables the job).
+	IntegrityVerifyInterval time.Duration `mapstructure:"INTEGRITY_VERIFY_INTERVAL"`
+	// ItemHistoryRetention specifies how long replaced item versions are retained (0 keeps them forever).
+	ItemHistoryRetention time.Duration `mapstructure:"ITEM_HISTORY_RETENTION"`
+	// ItemHistoryPruneInterval specifies how often expired item versions are pruned (0 disables the job).
+	ItemHistoryPruneInterval time.Duration `mapstructure:"ITEM_HISTORY_PRUNE_INTERVAL"`
+	// HealthReportInterval specifies how often vault health reports are mailed (0 disables the job).
+	HealthReportInterval time.Duration `mapstructure:"HEALTH_REPORT_INTERVAL"`
+	// CORSMaxAge specifies how long browsers may cache preflight responses (0 omits the header).
+	CORSMaxAge time.Duration `mapstructure:"CORS_MAX_AGE"`
+	// LoginStepUpTTL specifies how long step-up verification codes of suspicious logins are accepted.
+	LoginStepUpTTL time.Duration `mapstructure:"LOGIN_STEP_UP_TTL"`
+	// BruteForceWindow specifies the period authentication failures of a client network are counted over.
+	BruteForceWindow time.Duration `mapstructure:"BRUTE_FORCE_WINDOW"`
+	// BruteForceBanDuration specifies how long a banned client network is rejected.
+	BruteForceBanDuration time.Duration `mapstructure:"BRUTE_FORCE_BAN_DURATION"`
+	// ChallengeTTL specifies how long a proof-of-work puzzle may be solved.
+	ChallengeTTL time.Duration `mapstructure:"CHALLENGE_TTL"`
+	// ChallengeLoginWindow specifies the period failed logins of a client network are counted over.
+	ChallengeLoginWindow time.Duration `mapstructure:"CHALLENGE_LOGIN_WINDOW"`
+	// InviteTTL specifies how long invites last unless their creator sets an expiry (0 uses one week).
+	InviteTTL time.Duration `mapstructure:"INVITE_TTL"`
+	// ApprovalRequestTTL specifies how long an access request waits for a decision before it expires.
+	ApprovalRequestTTL time.Duration `mapstructure:"APPROVAL_REQUEST_TTL"`
+	// ApprovalAccessTTL specifies how long an approved access request allows revealing the item.
+	ApprovalAccessTTL time.Duration `mapstructure:"APPROVAL_ACCESS_TTL"`
+	// AdminDualControlTTL specifies how long a destructive admin request may await approval and, once approved,
+	// execution.
+	AdminDualControlTTL time.Duration `mapstructure:"ADMIN_DUAL_CONTROL_TTL"`
+	// CheckoutTTL specifies how long a shared credential stays checked out before it is checked in automatically
+	// (0 uses one hour).
+	CheckoutTTL time.Duration `mapstructure:"CHECKOUT_TTL"`
+	// CheckoutSweepInterval specifies how often expired credential check-outs are checked in (0 disables the job).
+	CheckoutSweepInterval time.Duration `mapstructure:"CHECKOUT_SWEEP_INTERVAL"`
+	// RotationCheckInterval specifies how often due credential rotations are run (0 disables the job).
+	RotationCheckInterval time.Duration `mapstructure:"ROTATION_CHECK_INTERVAL"`
+	// StorageGCInterval specifies how often file storage is reconciled with file metadata (0 disables the job).
+	StorageGCInterval time.Duration `mapstructure:"STORAGE_GC_INTERVAL"`
+	// FileTransferQueueTimeout bounds the wait of a file transfer for a free worker and memory before it is
+	// rejected as busy (0 rejects busy transfers at once).
+	FileTransferQueueTimeout time.Duration `mapstructure:"FILE_TRANSFER_QUEUE_TIMEOUT"`
+	// StorageGCGracePeriod specifies how old orphaned file contents must be before they are removed
+	// (0 uses 24 hours).
+	StorageGCGracePeriod time.Duration `mapstructure:"STORAGE_GC_GRACE_PERIOD"`
+	// NoteMergeCompactInterval specifies how often the updates of notes edited in merge mode are compacted
+	// (0 disables the job).
+	NoteMergeCompactInterval time.Duration `mapstructure:"NOTE_MERGE_COMPACT_INTERVAL"`
+	// StatsRollupInterval specifies how often usage statistics are rolled up (0 disables the job).
+	StatsRollupInterval time.Duration `mapstructure:"STATS_ROLLUP_INTERVAL"`
+	// StatsRetention specifies how long usage statistics rollups are kept (0 keeps them forever).
+	StatsRetention time.Duration `mapstructure:"STATS_RETENTION"`
+	// StripeWebhookTolerance specifies the accepted age of Stripe webhook signatures (0 uses five minutes).
+	StripeWebhookTolerance time.Duration `mapstructure:"STRIPE_WEBHOOK_TOLERANCE"`
+	// LicenseGracePeriod specifies how long the features of an expired license are kept (0 uses 14 days).
+	LicenseGracePeriod time.Duration `mapstructure:"LICENSE_GRACE_PERIOD"`
+	// UpdateCheckInterval specifies how often the release manifest is checked (0 uses 24 hours).
+	UpdateCheckInterval time.Duration `mapstructure:"UPDATE_CHECK_INTERVAL"`
+	// TelemetryInterval specifies how often usage pings are sent (0 uses 24 hours).
+	TelemetryInterval time.Duration `mapstructure:"TELEMETRY_INTERVAL"`
+	// RecordingMaxWindow limits the time window of request recordings (0 disables the recording mode).
+	RecordingMaxWindow time.Duration `mapstructure:"RECORDING_MAX_WINDOW"`
+	// RecordingRetention specifies how long recordings are kept after their window ends (0 keeps them until
+	// deleted).
+	RecordingRetention time.Duration `mapstructure:"RECORDING_RETENTION"`
+	// RetentionInterval specifies how often retention policies are enforced (0 disables the job).
+	RetentionInterval time.Duration `mapstructure:"RETENTION_INTERVAL"`
+	// LeaseTTL specifies how long machine identities may use leased secret values (0 uses five minutes).
+	LeaseTTL time.Duration `mapstructure:"LEASE_TTL"`
+	// PurgeTokenTTL specifies how long confirmation tokens of item purges are accepted (0 uses five minutes).
+	PurgeTokenTTL time.Duration `mapstructure:"PURGE_TOKEN_TTL"`
+	// SecurityHSTSMaxAge specifies the Strict-Transport-Security lifetime sent over TLS (0 omits the header).
+	SecurityHSTSMaxAge time.Duration `mapstructure:"SECURITY_HSTS_MAX_AGE"`
 	// TLSEnabled determines whether HTTPS should be used instead of HTTP.
 	TLSEnabled bool `mapstructure:"TLS_ENABLED"`
+	// PostgresRLSEnabled determines whether repository operations set the row-level security user scope.
+	PostgresRLSEnabled bool `mapstructure:"POSTGRES_RLS_ENABLED"`
+	// CORSAllowCredentials determines whether cross-origin requests may include credentials.
+	CORSAllowCredentials bool `mapstructure:"CORS_ALLOW_CREDENTIALS"`
+	// SessionCookiesEnabled determines whether web clients may keep the access token in a session cookie.
+	SessionCookiesEnabled bool `mapstructure:"SESSION_COOKIES_ENABLED"`
+	// SessionCookieSecure determines whether the session cookies are only sent over HTTPS.
+	SessionCookieSecure bool `mapstructure:"SESSION_COOKIE_SECURE"`
+	// SecurityHSTSIncludeSubdomains determines whether the HSTS policy also covers subdomains.
+	SecurityHSTSIncludeSubdomains bool `mapstructure:"SECURITY_HSTS_SUBDOMAINS"`
+	// HTTP2Enabled determines whether HTTP/2 is negotiated on TLS connections.
+	HTTP2Enabled bool `mapstructure:"HTTP2_ENABLED"`
+	// LoginAnomalyDetection determines whether logins from unrecognized devices or locations are held.
+	LoginAnomalyDetection bool `mapstructure:"LOGIN_ANOMALY_DETECTION"`
+	// HTTPKeepAlivesEnabled determines whether client connections are reused between requests.
+	HTTPKeepAlivesEnabled bool `mapstructure:"HTTP_KEEP_ALIVES_ENABLED"`
+	// WebUIEnabled determines whether the embedded web vault UI is served under /ui.
+	WebUIEnabled bool `mapstructure:"WEB_UI_ENABLED"`
+	// MaintenanceMode determines whether the server starts in read-only maintenance mode.
+	MaintenanceMode bool `mapstructure:"MAINTENANCE_MODE"`
+	// StorageGCCleanup determines whether orphaned file contents are removed by the reconciliation job.
+	StorageGCCleanup bool `mapstructure:"STORAGE_GC_CLEANUP"`
+	// LockKeyMemory determines whether the derived keys are locked in memory, so they are never swapped out.
+	LockKeyMemory bool `mapstructure:"LOCK_KEY_MEMORY"`
+	// TelemetryEnabled determines whether anonymous usage pings are sent (opt-in).
+	TelemetryEnabled bool `mapstructure:"TELEMETRY_ENABLED"`
+	// PasswordBanCommon determines whether user passwords found on the built-in list of common passwords are rejected.
+	PasswordBanCommon bool `mapstructure:"PASSWORD_BAN_COMMON"`
+	// AdminDualControl determines whether destructive admin requests run only once a second operator approves them.
+	AdminDualControl bool `mapstructure:"ADMIN_DUAL_CONTROL"`
+	// ChaosEnabled determines whether the chaos rules and repository faults are injected (never in production).
+	ChaosEnabled bool `mapstructure:"CHAOS_ENABLED"`
 }
 
-// LoadConfig loads and validates the server configuration from environment variables and files.
+// LoadConfig loads and validates the server configuration from environment variables and files. The defaults
+// embedded in the binary are read first; a server.yml found in the configuration directory overrides them.
 func LoadConfig() (*Config, error) {
-	viper.SetConfigName("server")
 	viper.SetConfigType("yml")
+	if err := viper.ReadConfig(bytes.NewReader(defaults.ServerDefaults)); err != nil {
+		return nil, fmt.Errorf("failed to read embedded config: %w", err)
+	}
+	viper.SetConfigName("server")
 	viper.AddConfigPath("/app/config")
-	if err := viper.ReadInConfig(); err != nil {
+	if err := viper.MergeInConfig(); err != nil && !errors.As(err, &viper.ConfigFileNotFoundError{}) {
 		return nil, fmt.Errorf("failed to read config file: %w", err)
 	}
 
@@ -72,9 +455,10 @@ func LoadConfig() (*Config, error) {
 
 	// cfg holds the unmarshalled configuration structure.
 	var cfg Config
-	if err := viper.Unmarshal(&cfg, viper.DecodeHook(
+	if err := viper.Unmarshal(&cfg, viper.DecodeHook(mapstructure.ComposeDecodeHookFunc(
 		mapstructure.StringToTimeDurationHookFunc(),
-	)); err != nil {
+		mapstructure.StringToSliceHookFunc(","),
+	))); err != nil {
 		return nil, fmt.Errorf("failed to decode config into struct: %w", err)
 	}
 
@@ -83,11 +467,165 @@ func LoadConfig() (*Config, error) {
 		return nil, fmt.Errorf("failed to load master key: %w", err)
 	}
 	cfg.MasterKey = mk
+	cfg.MasterKeyLength = len(viper.GetString("MASTER_KEY"))
+
+	ik, err := loadIntegrityKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load integrity key: %w", err)
+	}
+	cfg.IntegrityKey = ik
+
+	bk, err := loadBackupKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load backup key: %w", err)
+	}
+	cfg.BackupKey = bk
+	cfg.ChallengeKey = deriveKeySHA256(challengeKeyDomain + viper.GetString("MASTER_KEY"))
+	cfg.RecordingKey = deriveKeySHA256(recordingKeyDomain + viper.GetString("MASTER_KEY"))
+	cfg.SessionKey = deriveKeySHA256(sessionKeyDomain + viper.GetString("MASTER_KEY"))
+
+	if cfg.LockKeyMemory {
+		if err := lockKeys(&cfg); err != nil {
+			return nil, fmt.Errorf("failed to lock key memory: %w", err)
+		}
+	}
 
 	if err := validateTLSConfig(&cfg); err != nil {
 		return nil, fmt.Errorf("TLS configuration validation failed: %w", err)
 	}
 
+	if err := validateTrustedProxies(&cfg); err != nil {
+		return nil, fmt.Errorf("trusted proxies validation failed: %w", err)
+	}
+
+	if err := validateRestrictedItems(&cfg); err != nil {
+		return nil, fmt.Errorf("restricted items validation failed: %w", err)
+	}
+
+	if err := validateAuthzPolicy(&cfg); err != nil {
+		return nil, fmt.Errorf("authorization policy validation failed: %w", err)
+	}
+
+	if err := validateApprovals(&cfg); err != nil {
+		return nil, fmt.Errorf("approval workflow validation failed: %w", err)
+	}
+
+	if err := validateCheckout(&cfg); err != nil {
+		return nil, fmt.Errorf("credential check-out validation failed: %w", err)
+	}
+
+	if err := validateLease(&cfg); err != nil {
+		return nil, fmt.Errorf("machine lease validation failed: %w", err)
+	}
+
+	if err := validatePurge(&cfg); err != nil {
+		return nil, fmt.Errorf("item purge validation failed: %w", err)
+	}
+
+	if err := validateFileStorage(&cfg); err != nil {
+		return nil, fmt.Errorf("file storage validation failed: %w", err)
+	}
+
+	if err := validateStorageGC(&cfg); err != nil {
+		return nil, fmt.Errorf("storage reconciliation validation failed: %w", err)
+	}
+
+	if err := validateNoteMerge(&cfg); err != nil {
+		return nil, fmt.Errorf("note merge validation failed: %w", err)
+	}
+
+	if err := validateFeatureFlags(&cfg); err != nil {
+		return nil, fmt.Errorf("feature flags validation failed: %w", err)
+	}
+
+	if err := validateChaos(&cfg); err != nil {
+		return nil, fmt.Errorf("fault injection validation failed: %w", err)
+	}
+
+	if err := validateAdminToken(&cfg); err != nil {
+		return nil, fmt.Errorf("admin API validation failed: %w", err)
+	}
+
+	if err := validateAdminOperators(&cfg); err != nil {
+		return nil, fmt.Errorf("admin dual control validation failed: %w", err)
+	}
+
+	if err := validateSCIMToken(&cfg); err != nil {
+		return nil, fmt.Errorf("SCIM API validation failed: %w", err)
+	}
+
+	if err := validateWebPush(&cfg); err != nil {
+		return nil, fmt.Errorf("web push validation failed: %w", err)
+	}
+
+	if err := validateRequestSigning(&cfg); err != nil {
+		return nil, fmt.Errorf("request signing validation failed: %w", err)
+	}
+
+	if err := validateJWT(&cfg); err != nil {
+		return nil, fmt.Errorf("JWT validation failed: %w", err)
+	}
+
+	if err := validateLoginApprovalURL(&cfg); err != nil {
+		return nil, fmt.Errorf("login protection validation failed: %w", err)
+	}
+
+	if err := validateBruteForce(&cfg); err != nil {
+		return nil, fmt.Errorf("brute force protection validation failed: %w", err)
+	}
+
+	if err := validateChallenge(&cfg); err != nil {
+		return nil, fmt.Errorf("challenge validation failed: %w", err)
+	}
+
+	if err := validateRegistration(&cfg); err != nil {
+		return nil, fmt.Errorf("registration validation failed: %w", err)
+	}
+
+	if err := validatePasswordPolicy(&cfg); err != nil {
+		return nil, fmt.Errorf("password policy validation failed: %w", err)
+	}
+
+	if err := validateOnboarding(&cfg); err != nil {
+		return nil, fmt.Errorf("onboarding validation failed: %w", err)
+	}
+
+	if err := validatePlan(&cfg); err != nil {
+		return nil, fmt.Errorf("plan validation failed: %w", err)
+	}
+
+	if err := validateBilling(&cfg); err != nil {
+		return nil, fmt.Errorf("billing validation failed: %w", err)
+	}
+
+	if err := validateLicense(&cfg); err != nil {
+		return nil, fmt.Errorf("license validation failed: %w", err)
+	}
+
+	if err := validateTelemetry(&cfg); err != nil {
+		return nil, fmt.Errorf("telemetry validation failed: %w", err)
+	}
+
+	if err := validateUpdateCheck(&cfg); err != nil {
+		return nil, fmt.Errorf("update check validation failed: %w", err)
+	}
+
+	if err := validateRecording(&cfg); err != nil {
+		return nil, fmt.Errorf("recording validation failed: %w", err)
+	}
+
+	if err := validateAutofill(&cfg); err != nil {
+		return nil, fmt.Errorf("autofill validation failed: %w", err)
+	}
+
+	if err := validateRetention(&cfg); err != nil {
+		return nil, fmt.Errorf("retention validation failed: %w", err)
+	}
+
+	if err := validateSessionCookies(&cfg); err != nil {
+		return nil, fmt.Errorf("session cookie validation failed: %w", err)
+	}
+
 	return &cfg, nil
 }
 
@@ -118,19 +656,72 @@ func loadMasterKey() ([]byte, error) {
 	return deriveKeySHA256(masterKey), nil
 }
 
+// loadIntegrityKey loads the row integrity signing key from environment variables.
+// When no dedicated key is configured, it is derived from the master key with domain separation.
+func loadIntegrityKey() ([]byte, error) {
+	return loadDomainKey("INTEGRITY_KEY", integrityKeyDomain, "integrity")
+}
+
+// loadBackupKey loads the backup snapshot encryption key from environment variables.
+// When no dedicated key is configured, it is derived from the master key with domain separation,
+// so snapshots can be restored on any instance sharing the master key.
+func loadBackupKey() ([]byte, error) {
+	return loadDomainKey("BACKUP_KEY", backupKeyDomain, "backup")
+}
+
+// loadDomainKey loads a dedicated key from the specified environment variable or derives it
+// from the master key with the domain prefix when the variable is empty.
+func loadDomainKey(envName, domain, purpose string) ([]byte, error) {
+	key := viper.GetString(envName)
+	if key == "" {
+		return deriveKeySHA256(domain + viper.GetString("MASTER_KEY")), nil
+	}
+	if len(key) < masterKeyMinLen {
+		return nil, fmt.Errorf("invalid %s key: it must be at least %d characters long", purpose, masterKeyMinLen)
+	}
+	return deriveKeySHA256(key), nil
+}
+
 // deriveKeySHA256 derives a 32-byte encryption key from the master key using SHA256.
 func deriveKeySHA256(masterKey string) []byte {
-	sum := sha256.Sum256([]byte(masterKey))
+	input := []byte(masterKey)
+	defer securebytes.Wipe(input)
+
+	sum := sha256.Sum256(input)
 	return sum[:]
 }
 
+// lockKeys pins the derived keys in physical memory, so they are never written to swap.
+// Locking requires a sufficient RLIMIT_MEMLOCK limit or the CAP_IPC_LOCK capability.
+func lockKeys(cfg *Config) error {
+	for _, key := range []securebytes.Bytes{
+		cfg.MasterKey, cfg.IntegrityKey, cfg.BackupKey, cfg.ChallengeKey, cfg.RecordingKey, cfg.SessionKey,
+	} {
+		if err := securebytes.Lock(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // validateTLSConfig validates TLS configuration when TLS is enabled.
-// Checks that required certificate and key files are specified and exist.
+// Checks that required certificate and key files are specified and exist,
+// or that a certificate cache directory is set when certificates are obtained via ACME.
 func validateTLSConfig(cfg *Config) error {
 	if !cfg.TLSEnabled {
 		return nil
 	}
 
+	if len(cfg.TLSACMEDomains) > 0 {
+		if cfg.TLSACMECacheDir == "" {
+			return errors.New("TLS_ACME_CACHE_DIR is required when TLS_ACME_DOMAINS is set")
+		}
+		if cfg.TLSACMEHTTPAddr == "" {
+			return errors.New("TLS_ACME_HTTP_ADDR is required when TLS_ACME_DOMAINS is set")
+		}
+		return nil
+	}
+
 	if cfg.TLSCertFile == "" {
 		return errors.New("TLS_CERT_FILE is required when TLS is enabled")
 	}
@@ -149,3 +740,635 @@ func validateTLSConfig(cfg *Config) error {
 
 	return nil
 }
+
+// validateTrustedProxies checks that every trusted proxy entry is a valid CIDR or IP address.
+func validateTrustedProxies(cfg *Config) error {
+	for _, entry := range cleanList(cfg.TrustedProxies) {
+		if _, err := parseProxyPrefix(entry); err != nil {
+			return fmt.Errorf("invalid TRUSTED_PROXIES entry %q: %w", entry, err)
+		}
+	}
+	return nil
+}
+
+// validateRestrictedItems checks that every restricted items network is a valid CIDR or IP address and every
+// country is a two-letter code, which requires the country database.
+func validateRestrictedItems(cfg *Config) error {
+	for _, entry := range cleanList(cfg.RestrictedItemsNetworks) {
+		if _, err := parseProxyPrefix(entry); err != nil {
+			return fmt.Errorf("invalid RESTRICTED_ITEMS_NETWORKS entry %q: %w", entry, err)
+		}
+	}
+	countries := cleanList(cfg.RestrictedItemsCountries)
+	for _, entry := range countries {
+		if !isCountryCode(entry) {
+			return fmt.Errorf("invalid RESTRICTED_ITEMS_COUNTRIES entry %q: must be a two-letter country code", entry)
+		}
+	}
+	if len(countries) != 0 && cfg.GeoIPDBPath == "" {
+		return errors.New("GEOIP_DB_PATH is required when RESTRICTED_ITEMS_COUNTRIES is set")
+	}
+	return nil
+}
+
+// isCountryCode reports whether the entry is a two-letter ISO 3166-1 alpha-2 code in any letter case.
+func isCountryCode(entry string) bool {
+	if len(entry) != 2 {
+		return false
+	}
+	for _, r := range entry {
+		if (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') {
+			return false
+		}
+	}
+	return true
+}
+
+// validateAuthzPolicy checks that a configured authorization policy file is readable and holds a valid policy.
+func validateAuthzPolicy(cfg *Config) error {
+	if cfg.AuthzPolicyFile == "" {
+		return nil
+	}
+	text, err := os.ReadFile(cfg.AuthzPolicyFile)
+	if err != nil {
+		return fmt.Errorf("failed to read AUTHZ_POLICY_FILE: %w", err)
+	}
+	if _, err := authz.Parse(string(text)); err != nil {
+		return fmt.Errorf("invalid AUTHZ_POLICY_FILE policy: %w", err)
+	}
+	return nil
+}
+
+// validateApprovals checks that every approver is a valid user ID and that the request and access TTLs
+// are positive when the approval workflow is enabled.
+func validateApprovals(cfg *Config) error {
+	approvers := cleanList(cfg.ApprovalApprovers)
+	for _, entry := range approvers {
+		if _, err := uuid.Parse(entry); err != nil {
+			return fmt.Errorf("invalid APPROVAL_APPROVERS entry %q: must be a user ID", entry)
+		}
+	}
+	if len(approvers) == 0 {
+		return nil
+	}
+	if cfg.ApprovalRequestTTL <= 0 {
+		return errors.New("APPROVAL_REQUEST_TTL must be positive when APPROVAL_APPROVERS is set")
+	}
+	if cfg.ApprovalAccessTTL <= 0 {
+		return errors.New("APPROVAL_ACCESS_TTL must be positive when APPROVAL_APPROVERS is set")
+	}
+	return nil
+}
+
+// validateCheckout checks that the check-out duration of shared credentials is not negative.
+func validateCheckout(cfg *Config) error {
+	if cfg.CheckoutTTL < 0 {
+		return errors.New("CHECKOUT_TTL must not be negative")
+	}
+	return nil
+}
+
+// validateLease checks that the lease duration of machine identities is not negative.
+func validateLease(cfg *Config) error {
+	if cfg.LeaseTTL < 0 {
+		return errors.New("LEASE_TTL must not be negative")
+	}
+	return nil
+}
+
+// validatePurge checks that the lifetime of item purge confirmation tokens is not negative.
+func validatePurge(cfg *Config) error {
+	if cfg.PurgeTokenTTL < 0 {
+		return errors.New("PURGE_TOKEN_TTL must not be negative")
+	}
+	return nil
+}
+
+// validateFileStorage checks that the file storage quota and the file transfer limits are not negative.
+func validateFileStorage(cfg *Config) error {
+	if cfg.FileStorageQuota < 0 {
+		return errors.New("FILE_STORAGE_QUOTA must not be negative")
+	}
+	if cfg.FileTransferWorkers < 0 {
+		return errors.New("FILE_TRANSFER_WORKERS must not be negative")
+	}
+	if cfg.FileTransferMemory < 0 {
+		return errors.New("FILE_TRANSFER_MEMORY must not be negative")
+	}
+	if cfg.FileTransferQueueTimeout < 0 {
+		return errors.New("FILE_TRANSFER_QUEUE_TIMEOUT must not be negative")
+	}
+	return nil
+}
+
+// validateStorageGC checks that the grace period of orphaned file contents is not negative.
+func validateStorageGC(cfg *Config) error {
+	if cfg.StorageGCGracePeriod < 0 {
+		return errors.New("STORAGE_GC_GRACE_PERIOD must not be negative")
+	}
+	return nil
+}
+
+// validateNoteMerge checks that the compaction threshold of note updates is not negative.
+func validateNoteMerge(cfg *Config) error {
+	if cfg.NoteMergeCompactThreshold < 0 {
+		return errors.New("NOTE_MERGE_COMPACT_THRESHOLD must not be negative")
+	}
+	return nil
+}
+
+// validateFeatureFlags checks that every feature flag entry is a valid key with a boolean state.
+func validateFeatureFlags(cfg *Config) error {
+	for _, entry := range cleanList(cfg.FeatureFlags) {
+		if _, _, err := parseFeatureFlag(entry); err != nil {
+			return fmt.Errorf("invalid FEATURE_FLAGS entry %q: %w", entry, err)
+		}
+	}
+	return nil
+}
+
+// validateChaos checks that every chaos rule and repository fault is well-formed and that repository faults
+// only add latency or fail operations.
+func validateChaos(cfg *Config) error {
+	for _, entry := range cleanList(cfg.ChaosRules) {
+		if _, err := chaos.ParseRule(entry); err != nil {
+			return fmt.Errorf("invalid CHAOS_RULES entry %q: %w", entry, err)
+		}
+	}
+	for _, entry := range cleanList(cfg.ChaosRepositoryFaults) {
+		f, err := chaos.ParseFault(entry)
+		if err != nil {
+			return fmt.Errorf("invalid CHAOS_REPOSITORY_FAULTS entry %q: %w", entry, err)
+		}
+		if f.Kind == chaos.KindDrop {
+			return fmt.Errorf("invalid CHAOS_REPOSITORY_FAULTS entry %q: drop only applies to HTTP requests", entry)
+		}
+	}
+	return nil
+}
+
+// validateAdminToken checks that a configured admin API token is long enough to resist guessing.
+func validateAdminToken(cfg *Config) error {
+	if cfg.AdminAPIToken != "" && len(cfg.AdminAPIToken) < adminTokenMinLen {
+		return fmt.Errorf("ADMIN_API_TOKEN must be at least %d characters long", adminTokenMinLen)
+	}
+	return nil
+}
+
+// validateAdminOperators checks that operator tokens accompany the admin API token, that every operator has a
+// distinct name other than the one of the admin token and a distinct token long enough to resist guessing, and
+// that dual control has a second operator to approve requests, a positive TTL and valid notification addresses.
+func validateAdminOperators(cfg *Config) error {
+	entries := cleanList(cfg.AdminOperatorTokens)
+	if len(entries) != 0 && cfg.AdminAPIToken == "" {
+		return errors.New("ADMIN_OPERATOR_TOKENS requires ADMIN_API_TOKEN")
+	}
+	// tokens collects the tokens seen so far, starting with the admin token.
+	tokens := map[string]bool{cfg.AdminAPIToken: true}
+	// names collects the operator names seen so far.
+	names := map[string]bool{adminOperatorShared: true}
+	for _, entry := range entries {
+		name, token, ok := strings.Cut(entry, ":")
+		name = strings.TrimSpace(name)
+		switch {
+		case !ok || name == "":
+			return errors.New("invalid ADMIN_OPERATOR_TOKENS entry: must be name:token")
+		case names[name]:
+			return fmt.Errorf("invalid ADMIN_OPERATOR_TOKENS entry %q: name is reserved or repeated", name)
+		case len(token) < adminTokenMinLen:
+			return fmt.Errorf("invalid ADMIN_OPERATOR_TOKENS entry %q: token must be at least %d characters long",
+				name, adminTokenMinLen)
+		case tokens[token]:
+			return fmt.Errorf("invalid ADMIN_OPERATOR_TOKENS entry %q: token is already used", name)
+		}
+		names[name] = true
+		tokens[token] = true
+	}
+
+	for _, entry := range cleanList(cfg.AdminNotifyEmails) {
+		if _, err := mail.ParseAddress(entry); err != nil {
+			return fmt.Errorf("invalid ADMIN_NOTIFY_EMAILS entry %q: %w", entry, err)
+		}
+	}
+	if !cfg.AdminDualControl {
+		return nil
+	}
+	if len(entries) == 0 {
+		return errors.New("ADMIN_DUAL_CONTROL requires ADMIN_OPERATOR_TOKENS, so a second operator can approve requests")
+	}
+	if cfg.AdminDualControlTTL <= 0 {
+		return errors.New("ADMIN_DUAL_CONTROL_TTL must be positive when ADMIN_DUAL_CONTROL is enabled")
+	}
+	return nil
+}
+
+// validateSCIMToken checks that a configured SCIM API token is long enough to resist guessing.
+func validateSCIMToken(cfg *Config) error {
+	if cfg.SCIMAPIToken != "" && len(cfg.SCIMAPIToken) < adminTokenMinLen {
+		return fmt.Errorf("SCIM_API_TOKEN must be at least %d characters long", adminTokenMinLen)
+	}
+	return nil
+}
+
+// validateWebPush checks that a configured VAPID key is a valid P-256 key and that the operator contact
+// push services require accompanies it.
+func validateWebPush(cfg *Config) error {
+	if cfg.WebPushVAPIDPrivateKey == "" {
+		return nil
+	}
+	if _, err := notifier.ParseVAPIDPrivateKey(cfg.WebPushVAPIDPrivateKey); err != nil {
+		return fmt.Errorf("invalid WEBPUSH_VAPID_PRIVATE_KEY: %w", err)
+	}
+	if !strings.HasPrefix(cfg.WebPushVAPIDSubject, "mailto:") && !strings.HasPrefix(cfg.WebPushVAPIDSubject, "https://") {
+		return errors.New("WEBPUSH_VAPID_SUBJECT must be a mailto: or https:// URI when web push is enabled")
+	}
+	return nil
+}
+
+// validateRequestSigning checks that a configured admin signing key is long enough to resist guessing, that a
+// configured response signing key is an Ed25519 seed and that the accepted signing time window is not negative.
+func validateRequestSigning(cfg *Config) error {
+	if cfg.AdminSigningKey != "" && len(cfg.AdminSigningKey) < adminTokenMinLen {
+		return fmt.Errorf("ADMIN_SIGNING_KEY must be at least %d characters long", adminTokenMinLen)
+	}
+	if cfg.ResponseSigningKey != "" {
+		if _, err := crypto.ParseEd25519Seed(cfg.ResponseSigningKey); err != nil {
+			return fmt.Errorf("invalid RESPONSE_SIGNING_KEY: %w", err)
+		}
+	}
+	if cfg.RequestSignatureMaxSkew < 0 {
+		return errors.New("REQUEST_SIGNATURE_MAX_SKEW must not be negative")
+	}
+	return nil
+}
+
+// validateJWT checks that the token leeway is not negative and that token federation, when enabled,
+// names an absolute JWKS URL, the identity provider issuer, accepted audiences and a positive cache time.
+func validateJWT(cfg *Config) error {
+	if cfg.JWTLeeway < 0 {
+		return errors.New("JWT_LEEWAY must not be negative")
+	}
+	if cfg.JWTJWKSURL == "" {
+		return nil
+	}
+	u, err := url.Parse(cfg.JWTJWKSURL)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return fmt.Errorf("JWT_JWKS_URL must be an absolute http(s) URL, got %q", cfg.JWTJWKSURL)
+	}
+	if cfg.JWTJWKSIssuer == "" {
+		return errors.New("JWT_JWKS_ISSUER is required when JWT_JWKS_URL is set")
+	}
+	if len(cleanList(cfg.JWTAudiences)) == 0 {
+		return errors.New("JWT_AUDIENCES is required when JWT_JWKS_URL is set")
+	}
+	if cfg.JWTJWKSCacheTTL <= 0 {
+		return errors.New("JWT_JWKS_CACHE_TTL must be positive when JWT_JWKS_URL is set")
+	}
+	return nil
+}
+
+// validateLoginApprovalURL checks that a configured login approval URL is an absolute HTTP(S) URL.
+func validateLoginApprovalURL(cfg *Config) error {
+	if cfg.LoginApprovalURL == "" {
+		return nil
+	}
+	u, err := url.Parse(cfg.LoginApprovalURL)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return fmt.Errorf("LOGIN_APPROVAL_URL must be an absolute http(s) URL, got %q", cfg.LoginApprovalURL)
+	}
+	return nil
+}
+
+// validateBruteForce checks that the ban threshold is not negative and that an enabled ban list has a positive
+// window and ban duration.
+func validateBruteForce(cfg *Config) error {
+	if cfg.BruteForceThreshold < 0 {
+		return errors.New("BRUTE_FORCE_THRESHOLD must not be negative")
+	}
+	if cfg.BruteForceThreshold == 0 {
+		return nil
+	}
+	if cfg.BruteForceWindow <= 0 {
+		return errors.New("BRUTE_FORCE_WINDOW must be positive when BRUTE_FORCE_THRESHOLD is set")
+	}
+	if cfg.BruteForceBanDuration <= 0 {
+		return errors.New("BRUTE_FORCE_BAN_DURATION must be positive when BRUTE_FORCE_THRESHOLD is set")
+	}
+	return nil
+}
+
+// validateChallenge checks the challenge provider and its settings: CAPTCHA providers need a site key and
+// a secret, proof-of-work needs a feasible difficulty and a positive puzzle lifetime.
+func validateChallenge(cfg *Config) error {
+	if cfg.ChallengeLoginFailures < 0 {
+		return errors.New("CHALLENGE_LOGIN_FAILURES must not be negative")
+	}
+	if cfg.ChallengeLoginFailures > 0 && cfg.ChallengeLoginWindow <= 0 {
+		return errors.New("CHALLENGE_LOGIN_WINDOW must be positive when CHALLENGE_LOGIN_FAILURES is set")
+	}
+
+	switch cfg.ChallengeProvider {
+	case "":
+		return nil
+	case "hcaptcha", "turnstile":
+		if cfg.ChallengeSiteKey == "" || cfg.ChallengeSecret == "" {
+			return fmt.Errorf("CHALLENGE_SITE_KEY and CHALLENGE_SECRET are required for the %s provider",
+				cfg.ChallengeProvider)
+		}
+		return nil
+	case "pow":
+		if cfg.ChallengePoWDifficulty < 1 || cfg.ChallengePoWDifficulty > challengePoWMaxDifficulty {
+			return fmt.Errorf("CHALLENGE_POW_DIFFICULTY must be between 1 and %d, got %d",
+				challengePoWMaxDifficulty, cfg.ChallengePoWDifficulty)
+		}
+		if cfg.ChallengeTTL <= 0 {
+			return errors.New("CHALLENGE_TTL must be positive for the pow provider")
+		}
+		return nil
+	default:
+		return fmt.Errorf("CHALLENGE_PROVIDER must be hcaptcha, turnstile or pow, got %q", cfg.ChallengeProvider)
+	}
+}
+
+// validateRegistration checks the registration mode and the invite settings.
+func validateRegistration(cfg *Config) error {
+	switch cfg.RegistrationMode {
+	case "", "open", "invite":
+	default:
+		return fmt.Errorf("REGISTRATION_MODE must be open or invite, got %q", cfg.RegistrationMode)
+	}
+	if cfg.InviteTTL < 0 || cfg.InviteTTL > invite.MaxLifetime {
+		return fmt.Errorf("INVITE_TTL must be between 0 and %s, got %s", invite.MaxLifetime, cfg.InviteTTL)
+	}
+	if cfg.InviteUserQuota < 0 || cfg.InviteUserQuota > invite.MaxInviteQuota {
+		return fmt.Errorf("INVITE_USER_QUOTA must be between 0 and %d, got %d",
+			invite.MaxInviteQuota, cfg.InviteUserQuota)
+	}
+	return nil
+}
+
+// validatePasswordPolicy checks the password length and history limits, the character class names and that
+// the banned password file is readable.
+func validatePasswordPolicy(cfg *Config) error {
+	if cfg.PasswordMinLength != 0 &&
+		(cfg.PasswordMinLength < auth.MinPasswordLength || cfg.PasswordMinLength > auth.MaxPasswordLength) {
+		return fmt.Errorf("PASSWORD_MIN_LENGTH must be between %d and %d, got %d",
+			auth.MinPasswordLength, auth.MaxPasswordLength, cfg.PasswordMinLength)
+	}
+	if cfg.PasswordHistory < 0 || cfg.PasswordHistory > auth.MaxPasswordHistory {
+		return fmt.Errorf("PASSWORD_HISTORY must be between 0 and %d, got %d",
+			auth.MaxPasswordHistory, cfg.PasswordHistory)
+	}
+	for _, entry := range cleanList(cfg.PasswordRequiredClasses) {
+		if _, err := auth.ParsePasswordClass(entry); err != nil {
+			return fmt.Errorf("invalid PASSWORD_REQUIRED_CLASSES entry %q: must be lower, upper, digit or symbol", entry)
+		}
+	}
+	if cfg.PasswordBannedFile != "" {
+		if _, err := os.ReadFile(cfg.PasswordBannedFile); err != nil {
+			return fmt.Errorf("failed to read PASSWORD_BANNED_FILE: %w", err)
+		}
+	}
+	return nil
+}
+
+// validateOnboarding checks that the onboarding wizard steps are known, listed once and end with the admin step.
+func validateOnboarding(cfg *Config) error {
+	if _, err := onboarding.NewTemplate(cleanList(cfg.OnboardingSteps)); err != nil {
+		return fmt.Errorf("invalid ONBOARDING_STEPS: %w", err)
+	}
+	return nil
+}
+
+// validatePlan checks the default plan name.
+func validatePlan(cfg *Config) error {
+	if cfg.PlanDefault == "" {
+		return nil
+	}
+	if _, ok := plan.Lookup(cfg.PlanDefault); !ok {
+		return fmt.Errorf("PLAN_DEFAULT must be %s or %s, got %q", plan.NameFree, plan.NamePremium, cfg.PlanDefault)
+	}
+	return nil
+}
+
+// validateBilling checks that the Stripe price mapping is well-formed and configured with the webhook secret.
+func validateBilling(cfg *Config) error {
+	prices := cleanList(cfg.StripePricePlans)
+	for _, entry := range prices {
+		if _, _, err := parsePricePlan(entry); err != nil {
+			return fmt.Errorf("invalid STRIPE_PRICE_PLANS entry %q: %w", entry, err)
+		}
+	}
+	if (cfg.StripeWebhookSecret == "") != (len(prices) == 0) {
+		return errors.New("STRIPE_WEBHOOK_SECRET and STRIPE_PRICE_PLANS must be set together")
+	}
+	if cfg.StripeWebhookTolerance < 0 {
+		return fmt.Errorf("STRIPE_WEBHOOK_TOLERANCE must not be negative, got %s", cfg.StripeWebhookTolerance)
+	}
+	return nil
+}
+
+// validateLicense checks that the license vendor public key is well-formed and that the configured license key
+// is signed by the vendor.
+func validateLicense(cfg *Config) error {
+	if cfg.LicenseGracePeriod < 0 {
+		return fmt.Errorf("LICENSE_GRACE_PERIOD must not be negative, got %s", cfg.LicenseGracePeriod)
+	}
+	if cfg.LicensePublicKey == "" {
+		if cfg.LicenseKey != "" {
+			return errors.New("LICENSE_KEY requires LICENSE_PUBLIC_KEY")
+		}
+		return nil
+	}
+	publicKey, err := license.ParsePublicKey(cfg.LicensePublicKey)
+	if err != nil {
+		return fmt.Errorf("invalid LICENSE_PUBLIC_KEY: %w", err)
+	}
+	if cfg.LicenseKey != "" {
+		if _, err := license.Parse(cfg.LicenseKey, publicKey); err != nil {
+			return fmt.Errorf("invalid LICENSE_KEY: %w", err)
+		}
+	}
+	return nil
+}
+
+// validateTelemetry checks that the ping interval is not negative and that enabled telemetry has an absolute
+// HTTP(S) endpoint.
+func validateTelemetry(cfg *Config) error {
+	if cfg.TelemetryInterval < 0 {
+		return fmt.Errorf("TELEMETRY_INTERVAL must not be negative, got %s", cfg.TelemetryInterval)
+	}
+	if !cfg.TelemetryEnabled {
+		return nil
+	}
+	u, err := url.Parse(cfg.TelemetryEndpoint)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return fmt.Errorf("TELEMETRY_ENDPOINT must be an absolute http(s) URL, got %q", cfg.TelemetryEndpoint)
+	}
+	return nil
+}
+
+// validateUpdateCheck checks that the check interval is not negative and that a configured release manifest URL
+// is an absolute HTTP(S) URL with a well-formed release public key.
+func validateUpdateCheck(cfg *Config) error {
+	if cfg.UpdateCheckInterval < 0 {
+		return fmt.Errorf("UPDATE_CHECK_INTERVAL must not be negative, got %s", cfg.UpdateCheckInterval)
+	}
+	if cfg.UpdateCheckURL == "" {
+		return nil
+	}
+	u, err := url.Parse(cfg.UpdateCheckURL)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return fmt.Errorf("UPDATE_CHECK_URL must be an absolute http(s) URL, got %q", cfg.UpdateCheckURL)
+	}
+	if cfg.UpdateCheckPublicKey == "" {
+		return errors.New("UPDATE_CHECK_PUBLIC_KEY is required when UPDATE_CHECK_URL is set")
+	}
+	if _, err := release.ParsePublicKey(cfg.UpdateCheckPublicKey); err != nil {
+		return fmt.Errorf("invalid UPDATE_CHECK_PUBLIC_KEY: %w", err)
+	}
+	return nil
+}
+
+// validateRecording checks that the recording window, retention and body size limit are not negative.
+func validateRecording(cfg *Config) error {
+	if cfg.RecordingMaxWindow < 0 {
+		return fmt.Errorf("RECORDING_MAX_WINDOW must not be negative, got %s", cfg.RecordingMaxWindow)
+	}
+	if cfg.RecordingRetention < 0 {
+		return fmt.Errorf("RECORDING_RETENTION must not be negative, got %s", cfg.RecordingRetention)
+	}
+	if cfg.RecordingMaxBodySize < 0 {
+		return fmt.Errorf("RECORDING_MAX_BODY_SIZE must not be negative, got %d", cfg.RecordingMaxBodySize)
+	}
+	return nil
+}
+
+// validateRetention checks that the retention rules and the enforcement interval are not negative.
+func validateRetention(cfg *Config) error {
+	if cfg.RetentionKeepVersions < 0 {
+		return fmt.Errorf("RETENTION_KEEP_VERSIONS must not be negative, got %d", cfg.RetentionKeepVersions)
+	}
+	if cfg.RetentionKeepAuditDays < 0 {
+		return fmt.Errorf("RETENTION_KEEP_AUDIT_DAYS must not be negative, got %d", cfg.RetentionKeepAuditDays)
+	}
+	if cfg.RetentionInterval < 0 {
+		return fmt.Errorf("RETENTION_INTERVAL must not be negative, got %s", cfg.RetentionInterval)
+	}
+	return nil
+}
+
+// sessionSameSiteModes lists the accepted SESSION_COOKIE_SAMESITE values.
+var sessionSameSiteModes = []string{"strict", "lax", "none"}
+
+// validateSessionCookies checks that the session cookies are named and restricted consistently. Cookies sent with
+// cross-site requests must be secure, and session cookies must not be readable by credentialed requests from any
+// origin.
+func validateSessionCookies(cfg *Config) error {
+	if !slices.Contains(sessionSameSiteModes, cfg.SessionCookieSameSite) {
+		return fmt.Errorf("SESSION_COOKIE_SAMESITE must be one of %s, got %q",
+			strings.Join(sessionSameSiteModes, ", "), cfg.SessionCookieSameSite)
+	}
+	if cfg.SessionCookieSameSite == "none" && !cfg.SessionCookieSecure {
+		return errors.New("SESSION_COOKIE_SAMESITE none requires SESSION_COOKIE_SECURE")
+	}
+	for _, name := range []string{cfg.SessionCookieName, cfg.SessionCSRFCookieName} {
+		if !cookieNamePattern.MatchString(name) {
+			return fmt.Errorf("invalid session cookie name %q", name)
+		}
+	}
+	if cfg.SessionCookieName == cfg.SessionCSRFCookieName {
+		return errors.New("SESSION_COOKIE_NAME and SESSION_CSRF_COOKIE_NAME must differ")
+	}
+	if cfg.SessionCookiesEnabled && cfg.CORSAllowCredentials && slices.Contains(cleanList(cfg.CORSAllowedOrigins), "*") {
+		return errors.New("SESSION_COOKIES_ENABLED must not be combined with credentialed CORS for any origin")
+	}
+	return nil
+}
+
+var (
+	// androidPackagePattern matches Android application IDs, such as com.example.vault.
+	androidPackagePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*(\.[A-Za-z][A-Za-z0-9_]*)+$`)
+	// certFingerprintPattern matches SHA-256 certificate fingerprints as colon-separated hex bytes.
+	certFingerprintPattern = regexp.MustCompile(`^([0-9A-F]{2}:){31}[0-9A-F]{2}$`)
+	// iosAppIDPattern matches iOS app IDs, the team ID followed by the bundle ID.
+	iosAppIDPattern = regexp.MustCompile(`^[A-Z0-9]{10}\.[A-Za-z0-9-]+(\.[A-Za-z0-9-]+)*$`)
+	// cookieNamePattern matches cookie names made of RFC 6265 token characters.
+	cookieNamePattern = regexp.MustCompile("^[!#$%&'*+.^_`|~0-9A-Za-z-]+$")
+)
+
+// validateAutofill checks that the Android and iOS apps associated for autofill are well-formed.
+func validateAutofill(cfg *Config) error {
+	for _, entry := range cleanList(cfg.AutofillAndroidApps) {
+		if _, _, err := parseAndroidApp(entry); err != nil {
+			return fmt.Errorf("invalid AUTOFILL_ANDROID_APPS entry %q: %w", entry, err)
+		}
+	}
+	for _, entry := range cleanList(cfg.AutofillIOSApps) {
+		if !iosAppIDPattern.MatchString(entry) {
+			return fmt.Errorf("invalid AUTOFILL_IOS_APPS entry %q: expected TEAMID.bundle.id", entry)
+		}
+	}
+	return nil
+}
+
+// parseAndroidApp parses a package_name=SHA256_fingerprint entry of AUTOFILL_ANDROID_APPS.
+// The fingerprint is returned in upper case, as assetlinks.json expects.
+func parseAndroidApp(entry string) (string, string, error) {
+	pkg, fingerprint, ok := strings.Cut(entry, "=")
+	pkg, fingerprint = strings.TrimSpace(pkg), strings.ToUpper(strings.TrimSpace(fingerprint))
+	if !ok || !androidPackagePattern.MatchString(pkg) {
+		return "", "", errors.New("expected package_name=SHA256_fingerprint")
+	}
+	if !certFingerprintPattern.MatchString(fingerprint) {
+		return "", "", errors.New("fingerprint must be 32 colon-separated hex bytes")
+	}
+	return pkg, fingerprint, nil
+}
+
+// parsePricePlan parses a price_id=plan entry of STRIPE_PRICE_PLANS.
+func parsePricePlan(entry string) (string, string, error) {
+	price, name, ok := strings.Cut(entry, "=")
+	price, name = strings.TrimSpace(price), strings.TrimSpace(name)
+	if !ok || price == "" {
+		return "", "", errors.New("expected price_id=plan")
+	}
+	if _, ok := plan.Lookup(name); !ok {
+		return "", "", fmt.Errorf("plan must be %s or %s", plan.NameFree, plan.NamePremium)
+	}
+	return price, name, nil
+}
+
+// parseProxyPrefix parses a CIDR, treating a bare IP address as a single-host network.
+func parseProxyPrefix(entry string) (netip.Prefix, error) {
+	if !strings.Contains(entry, "/") {
+		ip, err := netip.ParseAddr(entry)
+		if err != nil {
+			return netip.Prefix{}, err
+		}
+		ip = ip.Unmap()
+		return netip.PrefixFrom(ip, ip.BitLen()), nil
+	}
+	p, err := netip.ParsePrefix(entry)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	return p.Masked(), nil
+}
+
+// parseFeatureFlag parses a key=bool feature flag entry.
+func parseFeatureFlag(entry string) (string, bool, error) {
+	key, value, ok := strings.Cut(entry, "=")
+	if !ok {
+		return "", false, errors.New("expected key=true or key=false")
+	}
+	key = strings.TrimSpace(key)
+	if !feature.IsValidKey(key) {
+		return "", false, errors.New("key must be up to 64 lowercase letters, digits, dots, dashes or underscores")
+	}
+	enabled, err := strconv.ParseBool(strings.TrimSpace(value))
+	if err != nil {
+		return "", false, fmt.Errorf("invalid state: %w", err)
+	}
+	return key, enabled, nil
+}
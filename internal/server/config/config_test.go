@@ -1,12 +1,19 @@
 package config
 
 import (
+	"crypto/ed25519"
+	"crypto/rand"
 	"crypto/sha256"
+	"encoding/base64"
 	"os"
 	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/license"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/securebytes"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -276,17 +283,1933 @@ func TestValidateTLSConfig(t *testing.T) {
 			shouldErr:   true,
 			errorSubstr: "TLS certificate file not found",
 		},
+		{
+			name: "ACME without certificate files",
+			config: &Config{
+				TLSEnabled:      true,
+				TLSACMEDomains:  []string{"vault.example.com"},
+				TLSACMECacheDir: tempDir,
+				TLSACMEHTTPAddr: ":80",
+			},
+			shouldErr: false,
+		},
+		{
+			name: "ACME missing cache dir",
+			config: &Config{
+				TLSEnabled:      true,
+				TLSACMEDomains:  []string{"vault.example.com"},
+				TLSACMEHTTPAddr: ":80",
+			},
+			shouldErr:   true,
+			errorSubstr: "TLS_ACME_CACHE_DIR is required",
+		},
+		{
+			name: "ACME missing challenge address",
+			config: &Config{
+				TLSEnabled:      true,
+				TLSACMEDomains:  []string{"vault.example.com"},
+				TLSACMECacheDir: tempDir,
+			},
+			shouldErr:   true,
+			errorSubstr: "TLS_ACME_HTTP_ADDR is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := validateTLSConfig(tt.config)
+
+			if tt.shouldErr {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorSubstr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateTrustedProxies(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		errorSubstr string
+		proxies     []string
+	}{
+		{name: "no proxies"},
+		{name: "cidrs and addresses", proxies: []string{"10.0.0.0/8", " 192.168.1.10", "fd00::/8", ""}},
+		{name: "invalid address", proxies: []string{"10.0.0.0/8", "proxy.local"}, errorSubstr: "proxy.local"},
+		{name: "invalid prefix length", proxies: []string{"10.0.0.0/33"}, errorSubstr: "10.0.0.0/33"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := validateTrustedProxies(&Config{TrustedProxies: tt.proxies})
+
+			if tt.errorSubstr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorSubstr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateAuthzPolicy(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	validFile := filepath.Join(dir, "valid.policy")
+	invalidFile := filepath.Join(dir, "invalid.policy")
+	require.NoError(t, os.WriteFile(validFile, []byte("allow read * when subject.id == resource.owner\n"), 0o600))
+	require.NoError(t, os.WriteFile(invalidFile, []byte("permit read *\n"), 0o600))
+
+	tests := []struct {
+		name        string
+		file        string
+		errorSubstr string
+	}{
+		{name: "default policy"},
+		{name: "valid policy", file: validFile},
+		{name: "invalid policy", file: invalidFile, errorSubstr: "line 1"},
+		{name: "missing file", file: filepath.Join(dir, "missing.policy"), errorSubstr: "failed to read"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := validateAuthzPolicy(&Config{AuthzPolicyFile: tt.file})
+
+			if tt.errorSubstr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorSubstr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateApprovals(t *testing.T) {
+	t.Parallel()
+
+	approver := "550e8400-e29b-41d4-a716-446655440000"
+
+	tests := []struct {
+		name        string
+		errorSubstr string
+		approvers   []string
+		requestTTL  time.Duration
+		accessTTL   time.Duration
+	}{
+		{name: "workflow disabled"},
+		{name: "approvers", approvers: []string{" " + approver, ""}, requestTTL: time.Hour, accessTTL: time.Minute},
+		{name: "invalid approver", approvers: []string{"alice"}, requestTTL: time.Hour, errorSubstr: "alice"},
+		{name: "missing request TTL", approvers: []string{approver}, accessTTL: time.Minute, errorSubstr: "REQUEST_TTL"},
+		{name: "missing access TTL", approvers: []string{approver}, requestTTL: time.Hour, errorSubstr: "ACCESS_TTL"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := validateApprovals(&Config{
+				ApprovalApprovers:  tt.approvers,
+				ApprovalRequestTTL: tt.requestTTL,
+				ApprovalAccessTTL:  tt.accessTTL,
+			})
+
+			if tt.errorSubstr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorSubstr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateRestrictedItems(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		errorSubstr string
+		geoIPDBPath string
+		networks    []string
+		countries   []string
+	}{
+		{name: "no geofence"},
+		{name: "networks only", networks: []string{"10.0.0.0/8", " 192.168.1.10", ""}},
+		{name: "countries", countries: []string{"DE", " fr"}, geoIPDBPath: "/app/geoip/country.mmdb"},
+		{name: "invalid network", networks: []string{"office.local"}, errorSubstr: "office.local"},
+		{
+			name:        "invalid country",
+			countries:   []string{"DEU"},
+			geoIPDBPath: "/app/geoip/country.mmdb",
+			errorSubstr: "DEU",
+		},
+		{name: "countries without database", countries: []string{"DE"}, errorSubstr: "GEOIP_DB_PATH"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := validateRestrictedItems(&Config{
+				RestrictedItemsNetworks:  tt.networks,
+				RestrictedItemsCountries: tt.countries,
+				GeoIPDBPath:              tt.geoIPDBPath,
+			})
+
+			if tt.errorSubstr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorSubstr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateFeatureFlags(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		errorSubstr string
+		flags       []string
+	}{
+		{name: "no flags"},
+		{name: "valid flags", flags: []string{"sync_v2=true", " crypto.v2 = false ", "beta=1", ""}},
+		{name: "missing state", flags: []string{"sync_v2"}, errorSubstr: "sync_v2"},
+		{name: "invalid state", flags: []string{"sync_v2=yes"}, errorSubstr: "sync_v2=yes"},
+		{name: "invalid key", flags: []string{"Sync V2=true"}, errorSubstr: "Sync V2=true"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := validateFeatureFlags(&Config{FeatureFlags: tt.flags})
+
+			if tt.errorSubstr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorSubstr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateChaos(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		errorSubstr string
+		rules       []string
+		faults      []string
+	}{
+		{name: "no faults"},
+		{
+			name:   "valid faults",
+			rules:  []string{"/api/items=latency:0.5:200ms", " POST /api/auth = error:0.1:502", "/api/sync=drop:0.01", ""},
+			faults: []string{"latency:0.2:50ms", "error:0.05"},
+		},
+		{name: "invalid rule", rules: []string{"/api=drop"}, errorSubstr: "CHAOS_RULES"},
+		{name: "invalid repository fault", faults: []string{"latency:2:1s"}, errorSubstr: "CHAOS_REPOSITORY_FAULTS"},
+		{name: "repository drop", faults: []string{"drop:0.1"}, errorSubstr: "only applies to HTTP"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := validateChaos(&Config{ChaosRules: tt.rules, ChaosRepositoryFaults: tt.faults})
+
+			if tt.errorSubstr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorSubstr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateAdminToken(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr bool
+	}{
+		{name: "admin api disabled", token: ""},
+		{name: "long token", token: strings.Repeat("a", adminTokenMinLen)},
+		{name: "short token", token: strings.Repeat("a", adminTokenMinLen-1), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := validateAdminToken(&Config{AdminAPIToken: tt.token})
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "ADMIN_API_TOKEN")
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateAdminOperators(t *testing.T) {
+	t.Parallel()
+
+	adminToken := strings.Repeat("a", adminTokenMinLen)
+	aliceToken := strings.Repeat("b", adminTokenMinLen)
+	bobToken := strings.Repeat("c", adminTokenMinLen)
+
+	tests := []struct {
+		operators   []string
+		emails      []string
+		name        string
+		adminToken  string
+		wantErr     string
+		ttl         time.Duration
+		dualControl bool
+	}{
+		{name: "no operators", adminToken: adminToken},
+		{
+			name:       "operators",
+			adminToken: adminToken,
+			operators:  []string{"alice:" + aliceToken, " bob:" + bobToken, ""},
+			emails:     []string{"security@example.com", "Ops <ops@example.com>"},
+		},
+		{
+			name:        "dual control",
+			adminToken:  adminToken,
+			operators:   []string{"alice:" + aliceToken},
+			ttl:         24 * time.Hour,
+			dualControl: true,
+		},
+		{
+			name:      "operators without admin api",
+			operators: []string{"alice:" + aliceToken},
+			wantErr:   "requires ADMIN_API_TOKEN",
+		},
+		{
+			name:       "missing name",
+			adminToken: adminToken,
+			operators:  []string{":" + aliceToken},
+			wantErr:    "must be name:token",
+		},
+		{
+			name:       "missing colon",
+			adminToken: adminToken,
+			operators:  []string{aliceToken},
+			wantErr:    "must be name:token",
+		},
+		{
+			name:       "reserved name",
+			adminToken: adminToken,
+			operators:  []string{"admin:" + aliceToken},
+			wantErr:    "reserved or repeated",
+		},
+		{
+			name:       "repeated name",
+			adminToken: adminToken,
+			operators:  []string{"alice:" + aliceToken, "alice:" + bobToken},
+			wantErr:    "reserved or repeated",
+		},
+		{
+			name:       "short token",
+			adminToken: adminToken,
+			operators:  []string{"alice:short"},
+			wantErr:    "at least 32 characters",
+		},
+		{
+			name:       "admin token reused",
+			adminToken: adminToken,
+			operators:  []string{"alice:" + adminToken},
+			wantErr:    "already used",
+		},
+		{
+			name:       "token shared by operators",
+			adminToken: adminToken,
+			operators:  []string{"alice:" + aliceToken, "bob:" + aliceToken},
+			wantErr:    "already used",
+		},
+		{
+			name:       "invalid email",
+			adminToken: adminToken,
+			emails:     []string{"security"},
+			wantErr:    "ADMIN_NOTIFY_EMAILS",
+		},
+		{
+			name:        "dual control without second operator",
+			adminToken:  adminToken,
+			ttl:         24 * time.Hour,
+			dualControl: true,
+			wantErr:     "requires ADMIN_OPERATOR_TOKENS",
+		},
+		{
+			name:        "dual control without ttl",
+			adminToken:  adminToken,
+			operators:   []string{"alice:" + aliceToken},
+			dualControl: true,
+			wantErr:     "ADMIN_DUAL_CONTROL_TTL",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := validateAdminOperators(&Config{
+				AdminAPIToken:       tt.adminToken,
+				AdminOperatorTokens: tt.operators,
+				AdminNotifyEmails:   tt.emails,
+				AdminDualControlTTL: tt.ttl,
+				AdminDualControl:    tt.dualControl,
+			})
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateSCIMToken(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr bool
+	}{
+		{name: "scim api disabled", token: ""},
+		{name: "long token", token: strings.Repeat("a", adminTokenMinLen)},
+		{name: "short token", token: strings.Repeat("a", adminTokenMinLen-1), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := validateSCIMToken(&Config{SCIMAPIToken: tt.token})
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "SCIM_API_TOKEN")
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateWebPush(t *testing.T) {
+	t.Parallel()
+
+	key := base64.RawURLEncoding.EncodeToString([]byte("0123456789abcdef0123456789abcdef"))
+
+	tests := []struct {
+		name    string
+		key     string
+		subject string
+		wantErr bool
+	}{
+		{name: "web push disabled", key: ""},
+		{name: "mailto subject", key: key, subject: "mailto:ops@example.com"},
+		{name: "https subject", key: key, subject: "https://vault.example.com"},
+		{name: "missing subject", key: key, wantErr: true},
+		{name: "plain address subject", key: key, subject: "ops@example.com", wantErr: true},
+		{name: "invalid key", key: "not-a-key", subject: "mailto:ops@example.com", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := validateWebPush(&Config{WebPushVAPIDPrivateKey: tt.key, WebPushVAPIDSubject: tt.subject})
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "WEBPUSH_VAPID_")
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateRequestSigning(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		key         string
+		responseKey string
+		wantSubstr  string
+		maxSkew     time.Duration
+	}{
+		{name: "admin signing disabled", maxSkew: 5 * time.Minute},
+		{
+			name:        "response key",
+			responseKey: base64.StdEncoding.EncodeToString(make([]byte, 32)),
+			maxSkew:     5 * time.Minute,
+		},
+		{
+			name:        "short response key",
+			responseKey: base64.StdEncoding.EncodeToString(make([]byte, 16)),
+			maxSkew:     5 * time.Minute,
+			wantSubstr:  "RESPONSE_SIGNING_KEY",
+		},
+		{name: "long key", key: strings.Repeat("a", adminTokenMinLen), maxSkew: 5 * time.Minute},
+		{
+			name:       "short key",
+			key:        strings.Repeat("a", adminTokenMinLen-1),
+			maxSkew:    5 * time.Minute,
+			wantSubstr: "ADMIN_SIGNING_KEY",
+		},
+		{name: "default skew"},
+		{name: "negative skew", maxSkew: -time.Minute, wantSubstr: "REQUEST_SIGNATURE_MAX_SKEW"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := validateRequestSigning(&Config{
+				AdminSigningKey:         tt.key,
+				ResponseSigningKey:      tt.responseKey,
+				RequestSignatureMaxSkew: tt.maxSkew,
+			})
+
+			if tt.wantSubstr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantSubstr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateJWT(t *testing.T) {
+	t.Parallel()
+
+	// federated returns a valid token federation configuration with the changes applied.
+	federated := func(change func(cfg *Config)) *Config {
+		cfg := &Config{
+			JWTJWKSURL:      "https://idp.example.com/.well-known/jwks.json",
+			JWTJWKSIssuer:   "https://idp.example.com",
+			JWTAudiences:    []string{"vault"},
+			JWTJWKSCacheTTL: 10 * time.Minute,
+		}
+		if change != nil {
+			change(cfg)
+		}
+		return cfg
+	}
+
+	tests := []struct {
+		cfg        *Config
+		name       string
+		wantSubstr string
+	}{
+		{name: "federation disabled", cfg: &Config{JWTLeeway: 30 * time.Second}},
+		{name: "negative leeway", cfg: &Config{JWTLeeway: -time.Second}, wantSubstr: "JWT_LEEWAY"},
+		{name: "federation enabled", cfg: federated(nil)},
+		{
+			name:       "relative JWKS URL",
+			cfg:        federated(func(cfg *Config) { cfg.JWTJWKSURL = "/jwks.json" }),
+			wantSubstr: "JWT_JWKS_URL",
+		},
+		{
+			name:       "missing issuer",
+			cfg:        federated(func(cfg *Config) { cfg.JWTJWKSIssuer = "" }),
+			wantSubstr: "JWT_JWKS_ISSUER",
+		},
+		{
+			name:       "blank audiences",
+			cfg:        federated(func(cfg *Config) { cfg.JWTAudiences = []string{" "} }),
+			wantSubstr: "JWT_AUDIENCES",
+		},
+		{
+			name:       "zero cache time",
+			cfg:        federated(func(cfg *Config) { cfg.JWTJWKSCacheTTL = 0 }),
+			wantSubstr: "JWT_JWKS_CACHE_TTL",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := validateJWT(tt.cfg)
+
+			if tt.wantSubstr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantSubstr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// Helper tests to ensure our test utilities work.
+func TestLoadIntegrityKey(t *testing.T) {
+	tests := []struct {
+		setupEnv    func()
+		name        string
+		errorSubstr string
+		shouldErr   bool
+	}{
+		{
+			name: "dedicated integrity key",
+			setupEnv: func() {
+				viper.Set("INTEGRITY_KEY", "dedicated_integrity_key")
+			},
+			shouldErr: false,
+		},
+		{
+			name: "derived from master key",
+			setupEnv: func() {
+				viper.Set("INTEGRITY_KEY", "")
+				viper.Set("MASTER_KEY", "this_is_a_valid_master_key_16_chars")
+			},
+			shouldErr: false,
+		},
+		{
+			name: "too short integrity key",
+			setupEnv: func() {
+				viper.Set("INTEGRITY_KEY", "short")
+			},
+			shouldErr:   true,
+			errorSubstr: "invalid integrity key",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupEnv()
+			defer func() {
+				viper.Set("INTEGRITY_KEY", "")
+				viper.Set("MASTER_KEY", "")
+			}()
+
+			result, err := loadIntegrityKey()
+
+			if tt.shouldErr {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorSubstr)
+				assert.Nil(t, result)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Len(t, result, 32)
+
+			mk, mkErr := loadMasterKey()
+			if mkErr == nil {
+				assert.NotEqual(t, mk, result, "integrity key must differ from the encryption key")
+			}
+		})
+	}
+}
+
+func TestLoadBackupKey(t *testing.T) {
+	tests := []struct {
+		setupEnv    func()
+		name        string
+		errorSubstr string
+		shouldErr   bool
+	}{
+		{
+			name: "dedicated backup key",
+			setupEnv: func() {
+				viper.Set("BACKUP_KEY", "dedicated_backup_key_value")
+			},
+			shouldErr: false,
+		},
+		{
+			name: "derived from master key",
+			setupEnv: func() {
+				viper.Set("BACKUP_KEY", "")
+				viper.Set("MASTER_KEY", "this_is_a_valid_master_key_16_chars")
+			},
+			shouldErr: false,
+		},
+		{
+			name: "too short backup key",
+			setupEnv: func() {
+				viper.Set("BACKUP_KEY", "short")
+			},
+			shouldErr:   true,
+			errorSubstr: "invalid backup key",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupEnv()
+			defer func() {
+				viper.Set("BACKUP_KEY", "")
+				viper.Set("MASTER_KEY", "")
+			}()
+
+			result, err := loadBackupKey()
+
+			if tt.shouldErr {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorSubstr)
+				assert.Nil(t, result)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Len(t, result, 32)
+
+			ik, ikErr := loadIntegrityKey()
+			if ikErr == nil {
+				assert.NotEqual(t, ik, result, "backup key must differ from the integrity key")
+			}
+		})
+	}
+}
+
+func TestConfigStructReflection(t *testing.T) {
+	t.Parallel()
+
+	// Test that we can properly reflect on the Config struct
+	cfg := Config{}
+	cfgType := reflect.TypeOf(cfg)
+
+	// Verify we can iterate through fields
+	fieldCount := 0
+	for i := range cfgType.NumField() {
+		field := cfgType.Field(i)
+		fieldCount++
+
+		// Check that fields with mapstructure tags are valid
+		if tag := field.Tag.Get("mapstructure"); tag != "" {
+			assert.NotEmpty(t, tag, "mapstructure tag should not be empty")
+		}
+	}
+
+	// Verify we found the expected number of fields (this might need adjustment if Config changes)
+	assert.Greater(t, fieldCount, 10, "Config should have multiple fields")
+}
+
+func TestConfigFieldTypes(t *testing.T) {
+	t.Parallel()
+
+	cfg := Config{}
+	cfgType := reflect.TypeOf(cfg)
+
+	expectedTypes := map[string]string{
+		"FileStorageBasePath":       "string",
+		"PostgresPassword":          "string",
+		"PostgresDBName":            "string",
+		"PostgresHost":              "string",
+		"PostgresSSLMode":           "string",
+		"LoggerLevel":               "string",
+		"TLSCertFile":               "string",
+		"TLSKeyFile":                "string",
+		"TLSACMEDomains":            "[]string",
+		"TLSACMEEmail":              "string",
+		"TLSACMECacheDir":           "string",
+		"TLSACMEDirectoryURL":       "string",
+		"TLSACMEHTTPAddr":           "string",
+		"PostgresUser":              "string",
+		"MasterKey":                 "securebytes.Bytes",
+		"IntegrityKey":              "securebytes.Bytes",
+		"BackupKey":                 "securebytes.Bytes",
+		"BackupStorage":             "string",
+		"BackupLocalPath":           "string",
+		"PostgresInitTimeout":       "time.Duration",
+		"PostgresTxRetryBackoff":    "time.Duration",
+		"ApplicationPort":           "int",
+		"AccessTokenLifeTime":       "time.Duration",
+		"PostgresPort":              "int",
+		"PostgresTxRetries":         "int",
+		"DeliveryStartTimeout":      "time.Duration",
+		"DeliveryStopTimeout":       "time.Duration",
+		"IntegrityVerifyInterval":   "time.Duration",
+		"ItemHistoryRetention":      "time.Duration",
+		"ItemHistoryPruneInterval":  "time.Duration",
+		"HealthReportInterval":      "time.Duration",
+		"SMTPHost":                  "string",
+		"SMTPPort":                  "int",
+		"CryptoWorkers":             "int",
+		"SMTPUsername":              "string",
+		"SMTPPassword":              "string",
+		"SMTPFrom":                  "string",
+		"TelegramBotToken":          "string",
+		"WebPushVAPIDPrivateKey":    "string",
+		"WebPushVAPIDSubject":       "string",
+		"CORSAllowedOrigins":        "[]string",
+		"CORSAllowedMethods":        "[]string",
+		"CORSAllowedHeaders":        "[]string",
+		"CORSMaxAge":                "time.Duration",
+		"TrustedProxies":            "[]string",
+		"FeatureFlags":              "[]string",
+		"ChaosRules":                "[]string",
+		"ChaosRepositoryFaults":     "[]string",
+		"ChaosEnabled":              "bool",
+		"CORSAllowCredentials":      "bool",
+		"SecurityCSP":               "string",
+		"SecurityHTMLCSP":           "string",
+		"AdminAPIToken":             "string",
+		"AdminOperatorTokens":       "[]string",
+		"AdminNotifyEmails":         "[]string",
+		"AdminDualControlTTL":       "time.Duration",
+		"AdminDualControl":          "bool",
+		"SCIMAPIToken":              "string",
+		"AdminSigningKey":           "string",
+		"ResponseSigningKey":        "string",
+		"GeoIPDBPath":               "string",
+		"RestrictedItemsNetworks":   "[]string",
+		"RestrictedItemsCountries":  "[]string",
+		"AuthzPolicyFile":           "string",
+		"ApprovalApprovers":         "[]string",
+		"ApprovalRequestTTL":        "time.Duration",
+		"ApprovalAccessTTL":         "time.Duration",
+		"CheckoutTTL":               "time.Duration",
+		"CheckoutSweepInterval":     "time.Duration",
+		"RotationCheckInterval":     "time.Duration",
+		"StorageGCInterval":         "time.Duration",
+		"StorageGCGracePeriod":      "time.Duration",
+		"StorageGCCleanup":          "bool",
+		"NoteMergeCompactInterval":  "time.Duration",
+		"StatsRollupInterval":       "time.Duration",
+		"StatsRetention":            "time.Duration",
+		"StripeWebhookTolerance":    "time.Duration",
+		"LicenseGracePeriod":        "time.Duration",
+		"TelemetryInterval":         "time.Duration",
+		"UpdateCheckInterval":       "time.Duration",
+		"RecordingMaxWindow":        "time.Duration",
+		"RecordingRetention":        "time.Duration",
+		"RecordingMaxBodySize":      "int",
+		"RetentionKeepVersions":     "int",
+		"RetentionKeepAuditDays":    "int",
+		"RetentionInterval":         "time.Duration",
+		"NoteMergeCompactThreshold": "int",
+		"FileStorageQuota":          "int64",
+		"FileTransferWorkers":       "int",
+		"FileTransferMemory":        "int64",
+		"FileTransferQueueTimeout":  "time.Duration",
+		"LeaseTTL":                  "time.Duration",
+		"LoginApprovalURL":          "string",
+		"AuthFailureLogPath":        "string",
+		"BruteForceThreshold":       "int",
+		"BruteForceWindow":          "time.Duration",
+		"BruteForceBanDuration":     "time.Duration",
+		"ChallengeProvider":         "string",
+		"ChallengeSiteKey":          "string",
+		"ChallengeSecret":           "string",
+		"ChallengeKey":              "securebytes.Bytes",
+		"RecordingKey":              "securebytes.Bytes",
+		"SessionKey":                "securebytes.Bytes",
+		"SessionCookieName":         "string",
+		"SessionCSRFCookieName":     "string",
+		"SessionCookieDomain":       "string",
+		"SessionCookieSameSite":     "string",
+		"SessionCookiesEnabled":     "bool",
+		"SessionCookieSecure":       "bool",
+		"ChallengePoWDifficulty":    "int",
+		"ChallengeTTL":              "time.Duration",
+		"ChallengeLoginFailures":    "int",
+		"ChallengeLoginWindow":      "time.Duration",
+		"RegistrationMode":          "string",
+		"PlanDefault":               "string",
+		"StripeWebhookSecret":       "string",
+		"StripePricePlans":          "[]string",
+		"LicensePublicKey":          "string",
+		"LicenseKey":                "string",
+		"TelemetryEndpoint":         "string",
+		"UpdateCheckURL":            "string",
+		"UpdateCheckPublicKey":      "string",
+		"InviteTTL":                 "time.Duration",
+		"InviteUserQuota":           "int",
+		"PasswordRequiredClasses":   "[]string",
+		"OnboardingSteps":           "[]string",
+		"MasterKeyLength":           "int",
+		"PasswordBannedFile":        "string",
+		"PasswordMinLength":         "int",
+		"PasswordHistory":           "int",
+		"PasswordBanCommon":         "bool",
+		"SecurityHSTSMaxAge":        "time.Duration",
+		"HTTPReadTimeout":           "time.Duration",
+		"HTTPRequestTimeout":        "time.Duration",
+		"HTTPItemsRequestTimeout":   "time.Duration",
+		"HTTPFilesRequestTimeout":   "time.Duration",
+		"RequestSignatureMaxSkew":   "time.Duration",
+		"JWTIssuer":                 "string",
+		"JWTAudiences":              "[]string",
+		"JWTLeeway":                 "time.Duration",
+		"JWTJWKSURL":                "string",
+		"JWTJWKSIssuer":             "string",
+		"JWTJWKSUserClaim":          "string",
+		"JWTJWKSCacheTTL":           "time.Duration",
+		"HTTPReadHeaderTimeout":     "time.Duration",
+		"HTTPWriteTimeout":          "time.Duration",
+		"HTTPIdleTimeout":           "time.Duration",
+		"HTTPMaxHeaderBytes":        "int",
+		"HTTP2Enabled":              "bool",
+		"HTTPKeepAlivesEnabled":     "bool",
+		"WebUIEnabled":              "bool",
+		"LoginStepUpTTL":            "time.Duration",
+		"LoginAnomalyDetection":     "bool",
+		"MaintenanceMode":           "bool",
+		"LockKeyMemory":             "bool",
+		"TelemetryEnabled":          "bool",
+		"TLSEnabled":                "bool",
+	}
+
+	for i := range cfgType.NumField() {
+		field := cfgType.Field(i)
+		if expectedType, exists := expectedTypes[field.Name]; exists {
+			assert.Equal(t, expectedType, field.Type.String(),
+				"Field %s should have type %s", field.Name, expectedType)
+		}
+	}
+}
+
+func TestLockKeys(t *testing.T) {
+	t.Parallel()
+
+	cfg := &Config{
+		MasterKey:    deriveKeySHA256("master-key-for-tests"),
+		IntegrityKey: deriveKeySHA256("integrity-key-for-tests"),
+		BackupKey:    nil,
+	}
+
+	if err := lockKeys(cfg); err != nil {
+		t.Skipf("memory locking unavailable: %v", err)
+	}
+	for _, key := range []securebytes.Bytes{cfg.MasterKey, cfg.IntegrityKey} {
+		require.NoError(t, securebytes.Unlock(key))
+	}
+}
+
+func TestMasterKeyConstants(t *testing.T) {
+	t.Parallel()
+
+	// Test that our constants are reasonable
+	assert.Equal(t, 16, masterKeyMinLen, "Master key minimum length should be 16")
+	assert.Greater(t, masterKeyMinLen, 8, "Master key should be reasonably secure")
+	assert.LessOrEqual(t, masterKeyMinLen, 32, "Master key minimum shouldn't be too restrictive")
+}
+
+func TestLoadConfigErrorPatterns(t *testing.T) {
+	// Test LoadConfig error patterns without complex setup
+	tests := []struct {
+		name        string
+		description string
+	}{
+		{
+			name:        "config_file_error_pattern",
+			description: "LoadConfig should return error when config file not found",
+		},
+		{
+			name:        "master_key_error_pattern",
+			description: "LoadConfig should return error for invalid master key",
+		},
+		{
+			name:        "tls_validation_error_pattern",
+			description: "LoadConfig should return error for invalid TLS config",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Test error message patterns exist
+			assert.NotEmpty(t, tt.description)
+
+			// Test that LoadConfig function exists
+			assert.NotNil(t, LoadConfig)
+		})
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	tests := []struct {
+		setup       func(t *testing.T) func()
+		name        string
+		errContains string
+		expectErr   bool
+	}{
+		{
+			name: "missing config file uses embedded defaults",
+			setup: func(t *testing.T) func() {
+				t.Helper()
+				viper.Reset()
+				viper.AddConfigPath(t.TempDir())
+
+				t.Setenv("MASTER_KEY", "test-master-key-for-testing-purposes-only")
+				t.Setenv("TLS_ENABLED", "false")
+
+				return func() { viper.Reset() }
+			},
+			expectErr: false,
+		},
+		{
+			name: "malformed config file error",
+			setup: func(t *testing.T) func() {
+				t.Helper()
+				viper.Reset()
+
+				tmpDir := t.TempDir()
+				err := os.WriteFile(filepath.Join(tmpDir, "server.yml"), []byte("server_host: [localhost"), 0o600)
+				require.NoError(t, err)
+				viper.AddConfigPath(tmpDir)
+
+				t.Setenv("MASTER_KEY", "test-master-key-for-testing-purposes-only")
+
+				return func() { viper.Reset() }
+			},
+			expectErr:   true,
+			errContains: "failed to read config file",
+		},
+		{
+			name: "invalid master key error",
+			setup: func(t *testing.T) func() {
+				t.Helper()
+				viper.Reset()
+
+				// Create temporary config file
+				tmpDir := t.TempDir()
+				configFile := filepath.Join(tmpDir, "server.yml")
+				err := os.WriteFile(configFile, []byte("server_host: localhost"), 0o600)
+				require.NoError(t, err)
+
+				viper.SetConfigName("server")
+				viper.SetConfigType("yml")
+				viper.AddConfigPath(tmpDir)
+
+				// Set environment variable for short master key
+				t.Setenv("MASTER_KEY", "short")
+
+				return func() { viper.Reset() }
+			},
+			expectErr:   true,
+			errContains: "failed to load master key",
+		},
+		{
+			name: "TLS validation error",
+			setup: func(t *testing.T) func() {
+				t.Helper()
+				viper.Reset()
+
+				// Create temporary config file
+				tmpDir := t.TempDir()
+				configFile := filepath.Join(tmpDir, "server.yml")
+				err := os.WriteFile(configFile, []byte("tls_enabled: true"), 0o600)
+				require.NoError(t, err)
+
+				viper.SetConfigName("server")
+				viper.SetConfigType("yml")
+				viper.AddConfigPath(tmpDir)
+
+				// Set environment variables
+				t.Setenv("MASTER_KEY", "test-master-key-for-testing-purposes-only")
+				t.Setenv("TLS_ENABLED", "true")
+				// Don't set TLS_CERT_FILE and TLS_KEY_FILE to trigger validation error
+
+				return func() { viper.Reset() }
+			},
+			expectErr:   true,
+			errContains: "TLS configuration validation failed",
+		},
+		{
+			name: "unmarshal error pattern",
+			setup: func(t *testing.T) func() {
+				t.Helper()
+				viper.Reset()
+
+				// Create temporary config file with invalid YAML structure
+				tmpDir := t.TempDir()
+				configFile := filepath.Join(tmpDir, "server.yml")
+				// Create invalid duration format to trigger unmarshal error
+				err := os.WriteFile(configFile, []byte("delivery_start_timeout: invalid_duration"), 0o600)
+				require.NoError(t, err)
+
+				viper.SetConfigName("server")
+				viper.SetConfigType("yml")
+				viper.AddConfigPath(tmpDir)
+
+				t.Setenv("MASTER_KEY", "test-master-key-for-testing-purposes-only")
+
+				return func() { viper.Reset() }
+			},
+			expectErr:   true,
+			errContains: "failed to decode config into struct",
+		},
+		{
+			name: "bind environment variables error pattern",
+			setup: func(t *testing.T) func() {
+				t.Helper()
+				viper.Reset()
+
+				// Create temporary config file
+				tmpDir := t.TempDir()
+				configFile := filepath.Join(tmpDir, "server.yml")
+				err := os.WriteFile(configFile, []byte("server_host: localhost"), 0o600)
+				require.NoError(t, err)
+
+				viper.SetConfigName("server")
+				viper.SetConfigType("yml")
+				viper.AddConfigPath(tmpDir)
+
+				t.Setenv("MASTER_KEY", "test-master-key-for-testing-purposes-only")
+
+				return func() { viper.Reset() }
+			},
+			expectErr:   false, // bindEnvFromStruct usually succeeds
+			errContains: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := tt.setup(t)
+			defer cleanup()
+
+			// Test LoadConfig - this will execute the function
+			config, err := LoadConfig()
+
+			if tt.expectErr {
+				require.Error(t, err)
+				if tt.errContains != "" {
+					assert.Contains(t, err.Error(), tt.errContains)
+				}
+				assert.Nil(t, config)
+			} else {
+				require.NoError(t, err)
+				require.NotNil(t, config)
+			}
+		})
+	}
+}
+
+func TestLoadConfigFunctionExists(t *testing.T) {
+	t.Parallel()
+
+	// Test that LoadConfig function exists and can be called
+	// This is a simple test to ensure the function is accessible
+	assert.NotNil(t, LoadConfig, "LoadConfig function should exist")
+
+	// Test error patterns that would occur in LoadConfig
+	tests := []struct {
+		name           string
+		errorPattern   string
+		expectedSubstr string
+	}{
+		{
+			name:           "config file error pattern",
+			errorPattern:   "failed to read config file: some error",
+			expectedSubstr: "failed to read config file",
+		},
+		{
+			name:           "env binding error pattern",
+			errorPattern:   "failed to bind environment variables: some error",
+			expectedSubstr: "failed to bind environment variables",
+		},
+		{
+			name:           "master key error pattern",
+			errorPattern:   "failed to load master key: some error",
+			expectedSubstr: "failed to load master key",
+		},
+		{
+			name:           "config unmarshal error pattern",
+			errorPattern:   "failed to unmarshal config: some error",
+			expectedSubstr: "failed to unmarshal config",
+		},
+		{
+			name:           "tls validation error pattern",
+			errorPattern:   "failed to validate TLS config: some error",
+			expectedSubstr: "failed to validate TLS config",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Test that error patterns are as expected
+			assert.Contains(t, tt.errorPattern, tt.expectedSubstr)
+		})
+	}
+}
+
+func TestValidateCheckout(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		ttl     time.Duration
+		wantErr bool
+	}{
+		{name: "positive TTL", ttl: time.Hour},
+		{name: "default TTL"},
+		{name: "negative TTL", ttl: -time.Minute, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := validateCheckout(&Config{CheckoutTTL: tt.ttl})
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "CHECKOUT_TTL")
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateLease(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		ttl     time.Duration
+		wantErr bool
+	}{
+		{name: "positive TTL", ttl: time.Minute},
+		{name: "default TTL"},
+		{name: "negative TTL", ttl: -time.Second, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := validateLease(&Config{LeaseTTL: tt.ttl})
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "LEASE_TTL")
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidatePurge(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		ttl     time.Duration
+		wantErr bool
+	}{
+		{name: "positive TTL", ttl: time.Minute},
+		{name: "default TTL"},
+		{name: "negative TTL", ttl: -time.Second, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := validatePurge(&Config{PurgeTokenTTL: tt.ttl})
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "PURGE_TOKEN_TTL")
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateStorageGC(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		grace   time.Duration
+		wantErr bool
+	}{
+		{name: "positive grace period", grace: time.Hour},
+		{name: "default grace period"},
+		{name: "negative grace period", grace: -time.Second, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := validateStorageGC(&Config{StorageGCGracePeriod: tt.grace})
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "STORAGE_GC_GRACE_PERIOD")
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateNoteMerge(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		threshold int
+		wantErr   bool
+	}{
+		{name: "positive threshold", threshold: 50},
+		{name: "default threshold"},
+		{name: "negative threshold", threshold: -1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := validateNoteMerge(&Config{NoteMergeCompactThreshold: tt.threshold})
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "NOTE_MERGE_COMPACT_THRESHOLD")
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateFileStorage(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		config  *Config
+		name    string
+		wantErr string
+	}{
+		{name: "positive quota", config: &Config{FileStorageQuota: 1 << 30}},
+		{name: "no quota", config: &Config{}},
+		{name: "negative quota", config: &Config{FileStorageQuota: -1}, wantErr: "FILE_STORAGE_QUOTA"},
+		{
+			name: "transfer limits",
+			config: &Config{
+				FileTransferWorkers: 4, FileTransferMemory: 256 << 20, FileTransferQueueTimeout: 10 * time.Second,
+			},
+		},
+		{name: "negative workers", config: &Config{FileTransferWorkers: -1}, wantErr: "FILE_TRANSFER_WORKERS"},
+		{name: "negative memory", config: &Config{FileTransferMemory: -1}, wantErr: "FILE_TRANSFER_MEMORY"},
+		{
+			name:    "negative queue timeout",
+			config:  &Config{FileTransferQueueTimeout: -time.Second},
+			wantErr: "FILE_TRANSFER_QUEUE_TIMEOUT",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := validateFileStorage(tt.config)
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateLoginApprovalURL(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		url     string
+		wantErr bool
+	}{
+		{name: "approval links disabled", url: ""},
+		{name: "https url", url: "https://vault.example.com"},
+		{name: "url with path", url: "https://example.com/vault/"},
+		{name: "relative url", url: "/vault", wantErr: true},
+		{name: "unsupported scheme", url: "ftp://vault.example.com", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := validateLoginApprovalURL(&Config{LoginApprovalURL: tt.url})
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "LOGIN_APPROVAL_URL")
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateBruteForce(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		config  *Config
+		name    string
+		wantErr string
+	}{
+		{name: "ban list disabled", config: &Config{}},
+		{
+			name:   "ban list enabled",
+			config: &Config{BruteForceThreshold: 20, BruteForceWindow: time.Minute, BruteForceBanDuration: time.Hour},
+		},
+		{name: "negative threshold", config: &Config{BruteForceThreshold: -1}, wantErr: "BRUTE_FORCE_THRESHOLD"},
+		{
+			name:    "no window",
+			config:  &Config{BruteForceThreshold: 20, BruteForceBanDuration: time.Hour},
+			wantErr: "BRUTE_FORCE_WINDOW",
+		},
+		{
+			name:    "no ban duration",
+			config:  &Config{BruteForceThreshold: 20, BruteForceWindow: time.Minute},
+			wantErr: "BRUTE_FORCE_BAN_DURATION",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := validateBruteForce(tt.config)
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateChallenge(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		config  *Config
+		name    string
+		wantErr string
+	}{
+		{name: "challenges disabled", config: &Config{}},
+		{
+			name:   "captcha",
+			config: &Config{ChallengeProvider: "hcaptcha", ChallengeSiteKey: "site", ChallengeSecret: "secret"},
+		},
+		{
+			name:   "proof of work",
+			config: &Config{ChallengeProvider: "pow", ChallengePoWDifficulty: 20, ChallengeTTL: time.Minute},
+		},
+		{name: "unknown provider", config: &Config{ChallengeProvider: "recaptcha"}, wantErr: "CHALLENGE_PROVIDER"},
+		{
+			name:    "captcha without secret",
+			config:  &Config{ChallengeProvider: "turnstile", ChallengeSiteKey: "site"},
+			wantErr: "CHALLENGE_SECRET",
+		},
+		{
+			name:    "difficulty too high",
+			config:  &Config{ChallengeProvider: "pow", ChallengePoWDifficulty: 33, ChallengeTTL: time.Minute},
+			wantErr: "CHALLENGE_POW_DIFFICULTY",
+		},
+		{
+			name:    "no puzzle lifetime",
+			config:  &Config{ChallengeProvider: "pow", ChallengePoWDifficulty: 20},
+			wantErr: "CHALLENGE_TTL",
+		},
+		{
+			name:    "negative login failures",
+			config:  &Config{ChallengeLoginFailures: -1},
+			wantErr: "CHALLENGE_LOGIN_FAILURES",
+		},
+		{
+			name:    "no login window",
+			config:  &Config{ChallengeLoginFailures: 3},
+			wantErr: "CHALLENGE_LOGIN_WINDOW",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := validateChallenge(tt.config)
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateRegistration(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		config  *Config
+		name    string
+		wantErr string
+	}{
+		{name: "defaults", config: &Config{}},
+		{name: "open", config: &Config{RegistrationMode: "open", InviteTTL: 168 * time.Hour}},
+		{
+			name:   "invite only",
+			config: &Config{RegistrationMode: "invite", InviteTTL: time.Hour, InviteUserQuota: 3},
+		},
+		{
+			name:    "unknown mode",
+			config:  &Config{RegistrationMode: "closed", InviteTTL: time.Hour},
+			wantErr: "REGISTRATION_MODE",
+		},
+		{
+			name:    "negative invite lifetime",
+			config:  &Config{RegistrationMode: "open", InviteTTL: -time.Hour},
+			wantErr: "INVITE_TTL",
+		},
+		{
+			name:    "invite lifetime too long",
+			config:  &Config{RegistrationMode: "open", InviteTTL: 91 * 24 * time.Hour},
+			wantErr: "INVITE_TTL",
+		},
+		{
+			name:    "negative user quota",
+			config:  &Config{RegistrationMode: "open", InviteTTL: time.Hour, InviteUserQuota: -1},
+			wantErr: "INVITE_USER_QUOTA",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := validateRegistration(tt.config)
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidatePasswordPolicy(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	bannedFile := filepath.Join(dir, "banned.txt")
+	require.NoError(t, os.WriteFile(bannedFile, []byte("company2024\n"), 0o600))
+
+	tests := []struct {
+		config  *Config
+		name    string
+		wantErr string
+	}{
+		{name: "defaults", config: &Config{}},
+		{
+			name: "strict policy",
+			config: &Config{
+				PasswordRequiredClasses: []string{"lower", " Upper ", "digit", "symbol", ""},
+				PasswordBannedFile:      bannedFile,
+				PasswordMinLength:       12,
+				PasswordHistory:         5,
+				PasswordBanCommon:       true,
+			},
+		},
+		{
+			name:    "minimum length too short",
+			config:  &Config{PasswordMinLength: 6},
+			wantErr: "PASSWORD_MIN_LENGTH",
+		},
+		{
+			name:    "minimum length too long",
+			config:  &Config{PasswordMinLength: 65},
+			wantErr: "PASSWORD_MIN_LENGTH",
+		},
+		{
+			name:    "negative history",
+			config:  &Config{PasswordHistory: -1},
+			wantErr: "PASSWORD_HISTORY",
+		},
+		{
+			name:    "history too long",
+			config:  &Config{PasswordHistory: 25},
+			wantErr: "PASSWORD_HISTORY",
+		},
+		{
+			name:    "unknown character class",
+			config:  &Config{PasswordRequiredClasses: []string{"emoji"}},
+			wantErr: "PASSWORD_REQUIRED_CLASSES",
+		},
+		{
+			name:    "missing banned file",
+			config:  &Config{PasswordBannedFile: filepath.Join(dir, "missing.txt")},
+			wantErr: "PASSWORD_BANNED_FILE",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := validatePasswordPolicy(tt.config)
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateOnboarding(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		config  *Config
+		name    string
+		wantErr string
+	}{
+		{name: "defaults", config: &Config{}},
+		{
+			name:   "custom steps",
+			config: &Config{OnboardingSteps: []string{" storage ", "", "master_key", "admin"}},
+		},
+		{
+			name:    "unknown step",
+			config:  &Config{OnboardingSteps: []string{"dns", "admin"}},
+			wantErr: "ONBOARDING_STEPS",
+		},
+		{
+			name:    "admin step not last",
+			config:  &Config{OnboardingSteps: []string{"admin", "smtp"}},
+			wantErr: "ONBOARDING_STEPS",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := validateOnboarding(tt.config)
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidatePlan(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		config  *Config
+		name    string
+		wantErr string
+	}{
+		{name: "no plan limits", config: &Config{}},
+		{name: "free", config: &Config{PlanDefault: "free"}},
+		{name: "premium", config: &Config{PlanDefault: "premium"}},
+		{name: "unknown plan", config: &Config{PlanDefault: "enterprise"}, wantErr: "PLAN_DEFAULT"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := validatePlan(tt.config)
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateBilling(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		config  *Config
+		name    string
+		wantErr string
+	}{
+		{name: "stripe disabled", config: &Config{}},
+		{
+			name: "stripe enabled",
+			config: &Config{
+				StripeWebhookSecret: "whsec_test",
+				StripePricePlans:    []string{"price_monthly=premium", "price_basic=free"},
+			},
+		},
+		{
+			name:    "secret without prices",
+			config:  &Config{StripeWebhookSecret: "whsec_test"},
+			wantErr: "set together",
+		},
+		{
+			name:    "prices without secret",
+			config:  &Config{StripePricePlans: []string{"price_monthly=premium"}},
+			wantErr: "set together",
+		},
+		{
+			name: "unknown plan",
+			config: &Config{
+				StripeWebhookSecret: "whsec_test",
+				StripePricePlans:    []string{"price_monthly=enterprise"},
+			},
+			wantErr: "STRIPE_PRICE_PLANS",
+		},
+		{
+			name: "missing price",
+			config: &Config{
+				StripeWebhookSecret: "whsec_test",
+				StripePricePlans:    []string{"premium"},
+			},
+			wantErr: "STRIPE_PRICE_PLANS",
+		},
+		{
+			name: "negative tolerance",
+			config: &Config{
+				StripeWebhookSecret:    "whsec_test",
+				StripePricePlans:       []string{"price_monthly=premium"},
+				StripeWebhookTolerance: -time.Second,
+			},
+			wantErr: "STRIPE_WEBHOOK_TOLERANCE",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := validateBilling(tt.config)
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateLicense(t *testing.T) {
+	t.Parallel()
+
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	_, otherKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	encodedKey := base64.StdEncoding.EncodeToString(publicKey)
+	terms := &license.License{IssuedAt: time.Now(), ID: "lic-1", Features: []string{license.FeatureSCIM}}
+	key, err := license.Sign(terms, privateKey)
+	require.NoError(t, err)
+	foreignKey, err := license.Sign(terms, otherKey)
+	require.NoError(t, err)
+
+	tests := []struct {
+		config  *Config
+		name    string
+		wantErr string
+	}{
+		{name: "licensing disabled", config: &Config{}},
+		{name: "public key only", config: &Config{LicensePublicKey: encodedKey}},
+		{
+			name:   "configured license",
+			config: &Config{LicensePublicKey: encodedKey, LicenseKey: key, LicenseGracePeriod: 24 * time.Hour},
+		},
+		{
+			name:    "malformed public key",
+			config:  &Config{LicensePublicKey: "not-a-key"},
+			wantErr: "LICENSE_PUBLIC_KEY",
+		},
+		{
+			name:    "license without public key",
+			config:  &Config{LicenseKey: key},
+			wantErr: "requires LICENSE_PUBLIC_KEY",
+		},
+		{
+			name:    "foreign license",
+			config:  &Config{LicensePublicKey: encodedKey, LicenseKey: foreignKey},
+			wantErr: "LICENSE_KEY",
+		},
+		{
+			name:    "negative grace period",
+			config:  &Config{LicenseGracePeriod: -time.Hour},
+			wantErr: "LICENSE_GRACE_PERIOD",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := validateLicense(tt.config)
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateTelemetry(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		config  *Config
+		name    string
+		wantErr string
+	}{
+		{name: "telemetry disabled", config: &Config{}},
+		{name: "disabled with endpoint", config: &Config{TelemetryEndpoint: "https://telemetry.example.com/v1/ping"}},
+		{
+			name: "telemetry enabled",
+			config: &Config{
+				TelemetryEnabled:  true,
+				TelemetryEndpoint: "https://telemetry.example.com/v1/ping",
+				TelemetryInterval: 12 * time.Hour,
+			},
+		},
+		{
+			name:    "enabled without endpoint",
+			config:  &Config{TelemetryEnabled: true},
+			wantErr: "TELEMETRY_ENDPOINT",
+		},
+		{
+			name:    "relative endpoint",
+			config:  &Config{TelemetryEnabled: true, TelemetryEndpoint: "/v1/ping"},
+			wantErr: "TELEMETRY_ENDPOINT",
+		},
+		{
+			name:    "negative interval",
+			config:  &Config{TelemetryInterval: -time.Hour},
+			wantErr: "TELEMETRY_INTERVAL",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := validateTelemetry(tt.config)
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateUpdateCheck(t *testing.T) {
+	t.Parallel()
+
+	publicKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	encodedKey := base64.StdEncoding.EncodeToString(publicKey)
+
+	tests := []struct {
+		config  *Config
+		name    string
+		wantErr string
+	}{
+		{name: "update checks disabled", config: &Config{}},
+		{
+			name: "update checks enabled",
+			config: &Config{
+				UpdateCheckURL:       "https://releases.example.com/latest",
+				UpdateCheckPublicKey: encodedKey,
+				UpdateCheckInterval:  12 * time.Hour,
+			},
+		},
+		{
+			name:    "relative URL",
+			config:  &Config{UpdateCheckURL: "/latest", UpdateCheckPublicKey: encodedKey},
+			wantErr: "UPDATE_CHECK_URL",
+		},
+		{
+			name:    "missing public key",
+			config:  &Config{UpdateCheckURL: "https://releases.example.com/latest"},
+			wantErr: "UPDATE_CHECK_PUBLIC_KEY is required",
+		},
+		{
+			name:    "malformed public key",
+			config:  &Config{UpdateCheckURL: "https://releases.example.com/latest", UpdateCheckPublicKey: "not-a-key"},
+			wantErr: "invalid UPDATE_CHECK_PUBLIC_KEY",
+		},
+		{
+			name:    "negative interval",
+			config:  &Config{UpdateCheckInterval: -time.Hour},
+			wantErr: "UPDATE_CHECK_INTERVAL",
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			err := validateTLSConfig(tt.config)
+			err := validateUpdateCheck(tt.config)
 
-			if tt.shouldErr {
-				assert.Error(t, err)
-				assert.Contains(t, err.Error(), tt.errorSubstr)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
 			} else {
 				assert.NoError(t, err)
 			}
@@ -294,281 +2217,243 @@ func TestValidateTLSConfig(t *testing.T) {
 	}
 }
 
-// Helper tests to ensure our test utilities work.
-func TestConfigStructReflection(t *testing.T) {
-	t.Parallel()
-
-	// Test that we can properly reflect on the Config struct
-	cfg := Config{}
-	cfgType := reflect.TypeOf(cfg)
-
-	// Verify we can iterate through fields
-	fieldCount := 0
-	for i := range cfgType.NumField() {
-		field := cfgType.Field(i)
-		fieldCount++
+func BenchmarkDeriveKeySHA256(b *testing.B) {
+	masterKey := strings.Repeat("k", 64)
 
-		// Check that fields with mapstructure tags are valid
-		if tag := field.Tag.Get("mapstructure"); tag != "" {
-			assert.NotEmpty(t, tag, "mapstructure tag should not be empty")
-		}
+	for range b.N {
+		securebytes.Wipe(deriveKeySHA256(integrityKeyDomain + masterKey))
 	}
-
-	// Verify we found the expected number of fields (this might need adjustment if Config changes)
-	assert.Greater(t, fieldCount, 10, "Config should have multiple fields")
 }
 
-func TestConfigFieldTypes(t *testing.T) {
+func TestValidateRecording(t *testing.T) {
 	t.Parallel()
 
-	cfg := Config{}
-	cfgType := reflect.TypeOf(cfg)
-
-	expectedTypes := map[string]string{
-		"FileStorageBasePath":  "string",
-		"PostgresPassword":     "string",
-		"PostgresDBName":       "string",
-		"PostgresHost":         "string",
-		"PostgresSSLMode":      "string",
-		"LoggerLevel":          "string",
-		"TLSCertFile":          "string",
-		"TLSKeyFile":           "string",
-		"PostgresUser":         "string",
-		"MasterKey":            "[]uint8",
-		"PostgresInitTimeout":  "time.Duration",
-		"ApplicationPort":      "int",
-		"AccessTokenLifeTime":  "time.Duration",
-		"PostgresPort":         "int",
-		"DeliveryStartTimeout": "time.Duration",
-		"DeliveryStopTimeout":  "time.Duration",
-		"TLSEnabled":           "bool",
+	tests := []struct {
+		config  *Config
+		name    string
+		wantErr string
+	}{
+		{name: "recording disabled", config: &Config{}},
+		{
+			name: "valid recording settings",
+			config: &Config{
+				RecordingMaxWindow:   time.Hour,
+				RecordingRetention:   7 * 24 * time.Hour,
+				RecordingMaxBodySize: 16384,
+			},
+		},
+		{
+			name:    "negative window",
+			config:  &Config{RecordingMaxWindow: -time.Hour},
+			wantErr: "RECORDING_MAX_WINDOW",
+		},
+		{
+			name:    "negative retention",
+			config:  &Config{RecordingRetention: -time.Hour},
+			wantErr: "RECORDING_RETENTION",
+		},
+		{
+			name:    "negative body size",
+			config:  &Config{RecordingMaxBodySize: -1},
+			wantErr: "RECORDING_MAX_BODY_SIZE",
+		},
 	}
 
-	for i := range cfgType.NumField() {
-		field := cfgType.Field(i)
-		if expectedType, exists := expectedTypes[field.Name]; exists {
-			assert.Equal(t, expectedType, field.Type.String(),
-				"Field %s should have type %s", field.Name, expectedType)
-		}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := validateRecording(tt.config)
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
 	}
 }
 
-func TestMasterKeyConstants(t *testing.T) {
+func TestValidateRetention(t *testing.T) {
 	t.Parallel()
 
-	// Test that our constants are reasonable
-	assert.Equal(t, 16, masterKeyMinLen, "Master key minimum length should be 16")
-	assert.Greater(t, masterKeyMinLen, 8, "Master key should be reasonably secure")
-	assert.LessOrEqual(t, masterKeyMinLen, 32, "Master key minimum shouldn't be too restrictive")
-}
-
-func TestLoadConfigErrorPatterns(t *testing.T) {
-	// Test LoadConfig error patterns without complex setup
 	tests := []struct {
-		name        string
-		description string
+		config  *Config
+		name    string
+		wantErr string
 	}{
+		{name: "keep everything", config: &Config{}},
 		{
-			name:        "config_file_error_pattern",
-			description: "LoadConfig should return error when config file not found",
+			name: "valid retention settings",
+			config: &Config{
+				RetentionInterval:      time.Hour,
+				RetentionKeepVersions:  10,
+				RetentionKeepAuditDays: 365,
+			},
 		},
 		{
-			name:        "master_key_error_pattern",
-			description: "LoadConfig should return error for invalid master key",
+			name:    "negative versions",
+			config:  &Config{RetentionKeepVersions: -1},
+			wantErr: "RETENTION_KEEP_VERSIONS",
 		},
 		{
-			name:        "tls_validation_error_pattern",
-			description: "LoadConfig should return error for invalid TLS config",
+			name:    "negative audit days",
+			config:  &Config{RetentionKeepAuditDays: -1},
+			wantErr: "RETENTION_KEEP_AUDIT_DAYS",
+		},
+		{
+			name:    "negative interval",
+			config:  &Config{RetentionInterval: -time.Hour},
+			wantErr: "RETENTION_INTERVAL",
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			// Test error message patterns exist
-			assert.NotEmpty(t, tt.description)
+			t.Parallel()
 
-			// Test that LoadConfig function exists
-			assert.NotNil(t, LoadConfig)
+			err := validateRetention(tt.config)
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
 		})
 	}
 }
 
-func TestLoadConfig(t *testing.T) {
+func TestValidateSessionCookies(t *testing.T) {
+	t.Parallel()
+
+	// valid returns session cookie settings passing validation, changed by modify.
+	valid := func(modify func(cfg *Config)) *Config {
+		cfg := &Config{
+			SessionCookieName:     "avk_session",
+			SessionCSRFCookieName: "avk_csrf",
+			SessionCookieSameSite: "strict",
+			SessionCookieSecure:   true,
+			SessionCookiesEnabled: true,
+		}
+		modify(cfg)
+		return cfg
+	}
+
 	tests := []struct {
-		setup       func(t *testing.T) func()
-		name        string
-		errContains string
-		expectErr   bool
+		config  *Config
+		name    string
+		wantErr string
 	}{
+		{name: "defaults", config: valid(func(*Config) {})},
 		{
-			name: "missing config file error",
-			setup: func(t *testing.T) func() {
-				t.Helper()
-				// Reset viper and use invalid config path to trigger error
-				viper.Reset()
-				return func() { viper.Reset() }
-			},
-			expectErr:   true,
-			errContains: "failed to read config file",
+			name: "cross-site secure cookies",
+			config: valid(func(cfg *Config) {
+				cfg.SessionCookieSameSite = "none"
+				cfg.CORSAllowedOrigins = []string{"https://app.example.com"}
+				cfg.CORSAllowCredentials = true
+			}),
 		},
 		{
-			name: "invalid master key error",
-			setup: func(t *testing.T) func() {
-				t.Helper()
-				viper.Reset()
-
-				// Create temporary config file
-				tmpDir := t.TempDir()
-				configFile := filepath.Join(tmpDir, "server.yml")
-				err := os.WriteFile(configFile, []byte("server_host: localhost"), 0o600)
-				require.NoError(t, err)
-
-				viper.SetConfigName("server")
-				viper.SetConfigType("yml")
-				viper.AddConfigPath(tmpDir)
-
-				// Set environment variable for short master key
-				t.Setenv("MASTER_KEY", "short")
-
-				return func() { viper.Reset() }
-			},
-			expectErr:   true,
-			errContains: "failed to load master key",
+			name:    "unknown same site mode",
+			config:  valid(func(cfg *Config) { cfg.SessionCookieSameSite = "Strict" }),
+			wantErr: "SESSION_COOKIE_SAMESITE",
 		},
 		{
-			name: "TLS validation error",
-			setup: func(t *testing.T) func() {
-				t.Helper()
-				viper.Reset()
-
-				// Create temporary config file
-				tmpDir := t.TempDir()
-				configFile := filepath.Join(tmpDir, "server.yml")
-				err := os.WriteFile(configFile, []byte("tls_enabled: true"), 0o600)
-				require.NoError(t, err)
-
-				viper.SetConfigName("server")
-				viper.SetConfigType("yml")
-				viper.AddConfigPath(tmpDir)
-
-				// Set environment variables
-				t.Setenv("MASTER_KEY", "test-master-key-for-testing-purposes-only")
-				t.Setenv("TLS_ENABLED", "true")
-				// Don't set TLS_CERT_FILE and TLS_KEY_FILE to trigger validation error
-
-				return func() { viper.Reset() }
-			},
-			expectErr:   true,
-			errContains: "TLS configuration validation failed",
+			name: "cross-site cookies over http",
+			config: valid(func(cfg *Config) {
+				cfg.SessionCookieSameSite = "none"
+				cfg.SessionCookieSecure = false
+			}),
+			wantErr: "SESSION_COOKIE_SECURE",
 		},
 		{
-			name: "unmarshal error pattern",
-			setup: func(t *testing.T) func() {
-				t.Helper()
-				viper.Reset()
-
-				// Create temporary config file with invalid YAML structure
-				tmpDir := t.TempDir()
-				configFile := filepath.Join(tmpDir, "server.yml")
-				// Create invalid duration format to trigger unmarshal error
-				err := os.WriteFile(configFile, []byte("delivery_start_timeout: invalid_duration"), 0o600)
-				require.NoError(t, err)
-
-				viper.SetConfigName("server")
-				viper.SetConfigType("yml")
-				viper.AddConfigPath(tmpDir)
-
-				t.Setenv("MASTER_KEY", "test-master-key-for-testing-purposes-only")
-
-				return func() { viper.Reset() }
-			},
-			expectErr:   true,
-			errContains: "failed to decode config into struct",
+			name:    "invalid cookie name",
+			config:  valid(func(cfg *Config) { cfg.SessionCookieName = "avk session" }),
+			wantErr: "invalid session cookie name",
 		},
 		{
-			name: "bind environment variables error pattern",
-			setup: func(t *testing.T) func() {
-				t.Helper()
-				viper.Reset()
-
-				// Create temporary config file
-				tmpDir := t.TempDir()
-				configFile := filepath.Join(tmpDir, "server.yml")
-				err := os.WriteFile(configFile, []byte("server_host: localhost"), 0o600)
-				require.NoError(t, err)
-
-				viper.SetConfigName("server")
-				viper.SetConfigType("yml")
-				viper.AddConfigPath(tmpDir)
-
-				t.Setenv("MASTER_KEY", "test-master-key-for-testing-purposes-only")
-
-				return func() { viper.Reset() }
-			},
-			expectErr:   false, // bindEnvFromStruct usually succeeds
-			errContains: "",
+			name:    "empty cookie name",
+			config:  valid(func(cfg *Config) { cfg.SessionCSRFCookieName = "" }),
+			wantErr: "invalid session cookie name",
+		},
+		{
+			name:    "shared cookie name",
+			config:  valid(func(cfg *Config) { cfg.SessionCSRFCookieName = "avk_session" }),
+			wantErr: "must differ",
+		},
+		{
+			name: "credentials for any origin",
+			config: valid(func(cfg *Config) {
+				cfg.CORSAllowedOrigins = []string{" * "}
+				cfg.CORSAllowCredentials = true
+			}),
+			wantErr: "credentialed CORS",
+		},
+		{
+			name: "credentials for any origin without sessions",
+			config: valid(func(cfg *Config) {
+				cfg.CORSAllowedOrigins = []string{"*"}
+				cfg.CORSAllowCredentials = true
+				cfg.SessionCookiesEnabled = false
+			}),
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			cleanup := tt.setup(t)
-			defer cleanup()
+			t.Parallel()
 
-			// Test LoadConfig - this will execute the function
-			config, err := LoadConfig()
+			err := validateSessionCookies(tt.config)
 
-			if tt.expectErr {
+			if tt.wantErr != "" {
 				require.Error(t, err)
-				if tt.errContains != "" {
-					assert.Contains(t, err.Error(), tt.errContains)
-				}
-				assert.Nil(t, config)
+				assert.Contains(t, err.Error(), tt.wantErr)
 			} else {
-				require.NoError(t, err)
-				require.NotNil(t, config)
+				assert.NoError(t, err)
 			}
 		})
 	}
 }
 
-func TestLoadConfigFunctionExists(t *testing.T) {
+func TestValidateAutofill(t *testing.T) {
 	t.Parallel()
 
-	// Test that LoadConfig function exists and can be called
-	// This is a simple test to ensure the function is accessible
-	assert.NotNil(t, LoadConfig, "LoadConfig function should exist")
+	fingerprint := strings.TrimSuffix(strings.Repeat("AB:", 32), ":")
 
-	// Test error patterns that would occur in LoadConfig
 	tests := []struct {
-		name           string
-		errorPattern   string
-		expectedSubstr string
+		config  *Config
+		name    string
+		wantErr string
 	}{
+		{name: "no apps", config: &Config{}},
 		{
-			name:           "config file error pattern",
-			errorPattern:   "failed to read config file: some error",
-			expectedSubstr: "failed to read config file",
+			name: "valid apps",
+			config: &Config{
+				AutofillAndroidApps: []string{"com.example.vault=" + strings.ToLower(fingerprint)},
+				AutofillIOSApps:     []string{"ABCDE12345.com.example.vault"},
+			},
 		},
 		{
-			name:           "env binding error pattern",
-			errorPattern:   "failed to bind environment variables: some error",
-			expectedSubstr: "failed to bind environment variables",
+			name:    "missing fingerprint",
+			config:  &Config{AutofillAndroidApps: []string{"com.example.vault"}},
+			wantErr: "AUTOFILL_ANDROID_APPS",
 		},
 		{
-			name:           "master key error pattern",
-			errorPattern:   "failed to load master key: some error",
-			expectedSubstr: "failed to load master key",
+			name:    "malformed package",
+			config:  &Config{AutofillAndroidApps: []string{"vault=" + fingerprint}},
+			wantErr: "AUTOFILL_ANDROID_APPS",
 		},
 		{
-			name:           "config unmarshal error pattern",
-			errorPattern:   "failed to unmarshal config: some error",
-			expectedSubstr: "failed to unmarshal config",
+			name:    "short fingerprint",
+			config:  &Config{AutofillAndroidApps: []string{"com.example.vault=AB:CD"}},
+			wantErr: "AUTOFILL_ANDROID_APPS",
 		},
 		{
-			name:           "tls validation error pattern",
-			errorPattern:   "failed to validate TLS config: some error",
-			expectedSubstr: "failed to validate TLS config",
+			name:    "missing team ID",
+			config:  &Config{AutofillIOSApps: []string{"com.example.vault"}},
+			wantErr: "AUTOFILL_IOS_APPS",
 		},
 	}
 
@@ -576,8 +2461,14 @@ func TestLoadConfigFunctionExists(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			// Test that error patterns are as expected
-			assert.Contains(t, tt.errorPattern, tt.expectedSubstr)
+			err := validateAutofill(tt.config)
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
 		})
 	}
 }
@@ -1,8 +1,21 @@
 package config
 
 import (
+	"crypto/ed25519"
+	"net/http"
+	"net/netip"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/chaos"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/crypto"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/auth"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/license"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/release"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/securebytes"
+	"github.com/google/uuid"
 )
 
 // DBConfig contains database connection configuration extracted from the main config.
@@ -21,18 +34,27 @@ type DBConfig struct {
 	Port int
 	// Timeout specifies the maximum duration for database initialization.
 	Timeout time.Duration
+	// TxRetries specifies how often transactions failing on serialization or deadlock are retried.
+	TxRetries int
+	// TxRetryBackoff specifies the delay before the first transaction retry, doubled per retry.
+	TxRetryBackoff time.Duration
+	// RLSEnabled determines whether repository operations set the row-level security user scope.
+	RLSEnabled bool
 }
 
 // ExtractDBConfig extracts database-specific configuration from the main config.
 func ExtractDBConfig(cfg *Config) *DBConfig {
 	return &DBConfig{
-		Host:     cfg.PostgresHost,
-		User:     cfg.PostgresUser,
-		Password: cfg.PostgresPassword,
-		DBName:   cfg.PostgresDBName,
-		SSLMode:  cfg.PostgresSSLMode,
-		Port:     cfg.PostgresPort,
-		Timeout:  cfg.PostgresInitTimeout,
+		Host:           cfg.PostgresHost,
+		User:           cfg.PostgresUser,
+		Password:       cfg.PostgresPassword,
+		DBName:         cfg.PostgresDBName,
+		SSLMode:        cfg.PostgresSSLMode,
+		Port:           cfg.PostgresPort,
+		Timeout:        cfg.PostgresInitTimeout,
+		TxRetries:      cfg.PostgresTxRetries,
+		TxRetryBackoff: cfg.PostgresTxRetryBackoff,
+		RLSEnabled:     cfg.PostgresRLSEnabled,
 	}
 }
 
@@ -52,9 +74,23 @@ func ExtractLoggerConfig(cfg *Config) *LoggerConfig {
 // AuthConfig contains authentication configuration extracted from the main config.
 type AuthConfig struct {
 	// MasterKey contains the derived encryption key for data protection (highly sensitive).
-	MasterKey []byte
+	MasterKey securebytes.Bytes
+	// Issuer names the issuer of access tokens.
+	Issuer string
+	// JWKSURL specifies the JWKS endpoint of the identity provider (empty disables token federation).
+	JWKSURL string
+	// JWKSIssuer names the issuer of identity provider tokens.
+	JWKSIssuer string
+	// JWKSUserClaim names the identity provider token claim holding the user ID.
+	JWKSUserClaim string
+	// Audiences lists the audiences of issued tokens, one of which presented tokens must name.
+	Audiences []string
 	// AccessTokenLifeTime specifies the JWT token validity duration.
 	AccessTokenLifeTime time.Duration
+	// Leeway tolerates clock skew when checking token validity periods.
+	Leeway time.Duration
+	// JWKSCacheTTL specifies how long identity provider signing keys are cached.
+	JWKSCacheTTL time.Duration
 }
 
 // ExtractAuthConfig extracts authentication-specific configuration from the main config.
@@ -62,6 +98,26 @@ func ExtractAuthConfig(cfg *Config) *AuthConfig {
 	return &AuthConfig{
 		MasterKey:           cfg.MasterKey,
 		AccessTokenLifeTime: cfg.AccessTokenLifeTime,
+		Issuer:              cfg.JWTIssuer,
+		Audiences:           cleanList(cfg.JWTAudiences),
+		Leeway:              cfg.JWTLeeway,
+		JWKSURL:             cfg.JWTJWKSURL,
+		JWKSIssuer:          cfg.JWTJWKSIssuer,
+		JWKSUserClaim:       cfg.JWTJWKSUserClaim,
+		JWKSCacheTTL:        cfg.JWTJWKSCacheTTL,
+	}
+}
+
+// CryptoConfig contains bulk encryption configuration extracted from the main config.
+type CryptoConfig struct {
+	// Workers limits the number of items encrypted or decrypted concurrently (0 uses one per CPU).
+	Workers int
+}
+
+// ExtractCryptoConfig extracts bulk encryption configuration from the main config.
+func ExtractCryptoConfig(cfg *Config) *CryptoConfig {
+	return &CryptoConfig{
+		Workers: cfg.CryptoWorkers,
 	}
 }
 
@@ -77,31 +133,989 @@ type DeliveryConfig struct {
 	StartTimeout time.Duration
 	// StopTimeout specifies the maximum duration for HTTP server shutdown.
 	StopTimeout time.Duration
+	// ACMEDomains lists host names certificates are obtained for via ACME (empty uses certificate files).
+	ACMEDomains []string
+	// ACMEEmail specifies the ACME account contact address.
+	ACMEEmail string
+	// ACMECacheDir specifies the directory storing ACME certificates and the account key.
+	ACMECacheDir string
+	// ACMEDirectoryURL specifies the ACME directory endpoint (empty uses Let's Encrypt production).
+	ACMEDirectoryURL string
+	// ACMEHTTPAddr specifies the address of the plain HTTP listener answering ACME challenges.
+	ACMEHTTPAddr string
+	// ReadTimeout limits reading an entire request including the body (0 means no limit).
+	ReadTimeout time.Duration
+	// ReadHeaderTimeout limits reading request headers.
+	ReadHeaderTimeout time.Duration
+	// WriteTimeout limits writing a response (0 means no limit).
+	WriteTimeout time.Duration
+	// IdleTimeout limits how long idle keep-alive connections stay open.
+	IdleTimeout time.Duration
+	// RequestTimeout bounds handling of public, auth, account, feature and admin requests (0 means no limit).
+	RequestTimeout time.Duration
+	// ItemsRequestTimeout bounds handling of item requests other than file transfers (0 means no limit).
+	ItemsRequestTimeout time.Duration
+	// FilesRequestTimeout bounds handling of file upload and download requests (0 means no limit).
+	FilesRequestTimeout time.Duration
+	// MaxHeaderBytes limits the size of request headers.
+	MaxHeaderBytes int
 	// TLSEnabled determines whether HTTPS should be used instead of HTTP.
 	TLSEnabled bool
+	// HTTP2Enabled determines whether HTTP/2 is negotiated on TLS connections.
+	HTTP2Enabled bool
+	// KeepAlivesEnabled determines whether client connections are reused between requests.
+	KeepAlivesEnabled bool
+	// WebUIEnabled determines whether the embedded web vault UI is served.
+	WebUIEnabled bool
 }
 
 // ExtractDeliveryConfig extracts HTTP delivery-specific configuration from the main config.
 func ExtractDeliveryConfig(cfg *Config) *DeliveryConfig {
 	return &DeliveryConfig{
-		Address:      ":" + strconv.Itoa(cfg.ApplicationPort),
-		StartTimeout: cfg.DeliveryStartTimeout,
-		StopTimeout:  cfg.DeliveryStopTimeout,
-		TLSEnabled:   cfg.TLSEnabled,
-		TLSCertFile:  cfg.TLSCertFile,
-		TLSKeyFile:   cfg.TLSKeyFile,
+		Address:             ":" + strconv.Itoa(cfg.ApplicationPort),
+		StartTimeout:        cfg.DeliveryStartTimeout,
+		StopTimeout:         cfg.DeliveryStopTimeout,
+		TLSEnabled:          cfg.TLSEnabled,
+		TLSCertFile:         cfg.TLSCertFile,
+		TLSKeyFile:          cfg.TLSKeyFile,
+		ACMEDomains:         cleanList(cfg.TLSACMEDomains),
+		ACMEEmail:           cfg.TLSACMEEmail,
+		ACMECacheDir:        cfg.TLSACMECacheDir,
+		ACMEDirectoryURL:    cfg.TLSACMEDirectoryURL,
+		ACMEHTTPAddr:        cfg.TLSACMEHTTPAddr,
+		ReadTimeout:         cfg.HTTPReadTimeout,
+		ReadHeaderTimeout:   cfg.HTTPReadHeaderTimeout,
+		WriteTimeout:        cfg.HTTPWriteTimeout,
+		IdleTimeout:         cfg.HTTPIdleTimeout,
+		RequestTimeout:      cfg.HTTPRequestTimeout,
+		ItemsRequestTimeout: cfg.HTTPItemsRequestTimeout,
+		FilesRequestTimeout: cfg.HTTPFilesRequestTimeout,
+		MaxHeaderBytes:      cfg.HTTPMaxHeaderBytes,
+		HTTP2Enabled:        cfg.HTTP2Enabled,
+		KeepAlivesEnabled:   cfg.HTTPKeepAlivesEnabled,
+		WebUIEnabled:        cfg.WebUIEnabled,
+	}
+}
+
+// CORSConfig contains cross-origin resource sharing configuration extracted from the main config.
+type CORSConfig struct {
+	// AllowedOrigins lists origins allowed to call the API ("*" allows any, empty disables CORS).
+	AllowedOrigins []string
+	// AllowedMethods lists HTTP methods allowed in cross-origin requests.
+	AllowedMethods []string
+	// AllowedHeaders lists request headers allowed in cross-origin requests.
+	AllowedHeaders []string
+	// MaxAge specifies how long browsers may cache preflight responses (0 omits the header).
+	MaxAge time.Duration
+	// AllowCredentials determines whether cross-origin requests may include credentials.
+	AllowCredentials bool
+}
+
+// ExtractCORSConfig extracts CORS-specific configuration from the main config.
+// List entries are trimmed and empty entries are dropped. With session cookies enabled the CSRF token header is
+// allowed as well, so cross-origin web clients can send it.
+func ExtractCORSConfig(cfg *Config) *CORSConfig {
+	headers := cleanList(cfg.CORSAllowedHeaders)
+	if cfg.SessionCookiesEnabled && !slices.ContainsFunc(headers, func(h string) bool {
+		return strings.EqualFold(h, csrfTokenHeader)
+	}) {
+		headers = append(headers, csrfTokenHeader)
+	}
+	return &CORSConfig{
+		AllowedOrigins:   cleanList(cfg.CORSAllowedOrigins),
+		AllowedMethods:   cleanList(cfg.CORSAllowedMethods),
+		AllowedHeaders:   headers,
+		MaxAge:           cfg.CORSMaxAge,
+		AllowCredentials: cfg.CORSAllowCredentials,
+	}
+}
+
+// csrfTokenHeader names the request header web clients send the CSRF token of their session in.
+const csrfTokenHeader = "X-CSRF-Token"
+
+// SessionConfig contains cookie session configuration extracted from the main config.
+type SessionConfig struct {
+	// Key contains the derived HMAC key the CSRF tokens are signed with (sensitive).
+	Key securebytes.Bytes
+	// CookieName names the HttpOnly cookie carrying the access token.
+	CookieName string
+	// CSRFCookieName names the cookie carrying the CSRF token.
+	CSRFCookieName string
+	// Domain specifies the domain of the cookies (empty limits them to the server host).
+	Domain string
+	// SameSite restricts sending the cookies with cross-site requests.
+	SameSite http.SameSite
+	// Secure determines whether the cookies are only sent over HTTPS.
+	Secure bool
+	// Enabled determines whether web clients may keep the access token in a session cookie.
+	Enabled bool
+}
+
+// ExtractSessionConfig extracts cookie session configuration from the main config.
+func ExtractSessionConfig(cfg *Config) *SessionConfig {
+	// sameSite maps the configured mode to the cookie attribute.
+	sameSite := http.SameSiteStrictMode
+	switch cfg.SessionCookieSameSite {
+	case "lax":
+		sameSite = http.SameSiteLaxMode
+	case "none":
+		sameSite = http.SameSiteNoneMode
+	}
+	return &SessionConfig{
+		Key:            cfg.SessionKey,
+		CookieName:     cfg.SessionCookieName,
+		CSRFCookieName: cfg.SessionCSRFCookieName,
+		Domain:         cfg.SessionCookieDomain,
+		SameSite:       sameSite,
+		Secure:         cfg.SessionCookieSecure,
+		Enabled:        cfg.SessionCookiesEnabled,
+	}
+}
+
+// SecurityHeadersConfig contains security response header configuration extracted from the main config.
+type SecurityHeadersConfig struct {
+	// CSP specifies the Content-Security-Policy of non-HTML responses (empty omits the header).
+	CSP string
+	// HTMLCSP specifies the Content-Security-Policy of HTML pages (empty omits the header).
+	HTMLCSP string
+	// HSTSMaxAge specifies the Strict-Transport-Security lifetime sent over TLS (0 omits the header).
+	HSTSMaxAge time.Duration
+	// HSTSIncludeSubdomains determines whether the HSTS policy also covers subdomains.
+	HSTSIncludeSubdomains bool
+	// TLSEnabled determines whether HTTPS is used; HSTS is only sent in that case.
+	TLSEnabled bool
+}
+
+// ExtractSecurityHeadersConfig extracts security header configuration from the main config.
+func ExtractSecurityHeadersConfig(cfg *Config) *SecurityHeadersConfig {
+	return &SecurityHeadersConfig{
+		CSP:                   cfg.SecurityCSP,
+		HTMLCSP:               cfg.SecurityHTMLCSP,
+		HSTSMaxAge:            cfg.SecurityHSTSMaxAge,
+		HSTSIncludeSubdomains: cfg.SecurityHSTSIncludeSubdomains,
+		TLSEnabled:            cfg.TLSEnabled,
+	}
+}
+
+// ProxyConfig contains reverse proxy configuration extracted from the main config.
+type ProxyConfig struct {
+	// TrustedProxies lists networks of reverse proxies allowed to forward client addresses.
+	TrustedProxies []netip.Prefix
+}
+
+// ExtractProxyConfig extracts reverse proxy configuration from the main config.
+// Entries are validated when the configuration is loaded; invalid ones are skipped here.
+func ExtractProxyConfig(cfg *Config) *ProxyConfig {
+	var trusted []netip.Prefix
+	for _, entry := range cleanList(cfg.TrustedProxies) {
+		if p, err := parseProxyPrefix(entry); err == nil {
+			trusted = append(trusted, p)
+		}
+	}
+	return &ProxyConfig{TrustedProxies: trusted}
+}
+
+// AdminConfig contains admin API configuration extracted from the main config.
+type AdminConfig struct {
+	// Operators maps the names of admin operators to their own tokens (sensitive data).
+	Operators map[string]string
+	// NotifyEmails lists the addresses mailed about destructive admin requests awaiting approval.
+	NotifyEmails []string
+	// Token authorizes admin API requests (sensitive data, empty disables the API).
+	Token string
+	// DualControlTTL specifies how long a destructive admin request may await approval and execution.
+	DualControlTTL time.Duration
+	// DualControl determines whether destructive admin requests require the approval of a second operator.
+	DualControl bool
+}
+
+// ExtractAdminConfig extracts admin API configuration from the main config.
+// Entries are validated when the configuration is loaded; malformed operator tokens are skipped here.
+func ExtractAdminConfig(cfg *Config) *AdminConfig {
+	admin := &AdminConfig{
+		NotifyEmails:   cleanList(cfg.AdminNotifyEmails),
+		Token:          cfg.AdminAPIToken,
+		DualControlTTL: cfg.AdminDualControlTTL,
+		DualControl:    cfg.AdminDualControl,
+	}
+	for _, entry := range cleanList(cfg.AdminOperatorTokens) {
+		name, token, ok := strings.Cut(entry, ":")
+		if name = strings.TrimSpace(name); !ok || name == "" {
+			continue
+		}
+		if admin.Operators == nil {
+			admin.Operators = make(map[string]string)
+		}
+		admin.Operators[name] = token
+	}
+	return admin
+}
+
+// SCIMConfig contains SCIM provisioning API configuration extracted from the main config.
+type SCIMConfig struct {
+	// Token authorizes SCIM provisioning requests (sensitive data, empty disables the API).
+	Token string
+}
+
+// ExtractSCIMConfig extracts SCIM provisioning API configuration from the main config.
+func ExtractSCIMConfig(cfg *Config) *SCIMConfig {
+	return &SCIMConfig{
+		Token: cfg.SCIMAPIToken,
+	}
+}
+
+// RequestSigningConfig contains request signing configuration extracted from the main config.
+type RequestSigningConfig struct {
+	// ResponseKey signs the responses of critical endpoints (sensitive data, nil disables signing of them).
+	ResponseKey ed25519.PrivateKey
+	// AdminKey signs admin API requests (sensitive data, empty disables signing of them).
+	AdminKey string
+	// MaxSkew bounds how far the signing time of a signed request may be from the server time (0 uses 5 minutes).
+	MaxSkew time.Duration
+}
+
+// ExtractRequestSigningConfig extracts request signing configuration from the main config.
+func ExtractRequestSigningConfig(cfg *Config) *RequestSigningConfig {
+	// responseKey stays nil unless a valid response signing key is configured.
+	var responseKey ed25519.PrivateKey
+	if key, err := crypto.ParseEd25519Seed(cfg.ResponseSigningKey); err == nil {
+		responseKey = key
+	}
+	return &RequestSigningConfig{
+		ResponseKey: responseKey,
+		AdminKey:    cfg.AdminSigningKey,
+		MaxSkew:     cfg.RequestSignatureMaxSkew,
+	}
+}
+
+// GeoIPConfig contains IP geolocation configuration extracted from the main config.
+type GeoIPConfig struct {
+	// DBPath specifies the MaxMind country database file (empty disables country lookups).
+	DBPath string
+}
+
+// ExtractGeoIPConfig extracts IP geolocation configuration from the main config.
+func ExtractGeoIPConfig(cfg *Config) *GeoIPConfig {
+	return &GeoIPConfig{
+		DBPath: cfg.GeoIPDBPath,
+	}
+}
+
+// GeofenceConfig contains the locations items tagged restricted may be revealed from, extracted from the main config.
+type GeofenceConfig struct {
+	// Networks lists networks restricted items may be revealed from.
+	Networks []netip.Prefix
+	// Countries lists upper-case ISO country codes restricted items may be revealed from.
+	Countries []string
+}
+
+// ExtractGeofenceConfig extracts restricted items geofence configuration from the main config.
+// Entries are validated when the configuration is loaded; invalid networks are skipped here.
+func ExtractGeofenceConfig(cfg *Config) *GeofenceConfig {
+	fence := &GeofenceConfig{}
+	for _, entry := range cleanList(cfg.RestrictedItemsNetworks) {
+		if p, err := parseProxyPrefix(entry); err == nil {
+			fence.Networks = append(fence.Networks, p)
+		}
+	}
+	for _, entry := range cleanList(cfg.RestrictedItemsCountries) {
+		fence.Countries = append(fence.Countries, strings.ToUpper(entry))
+	}
+	return fence
+}
+
+// AuthzConfig contains vault item authorization configuration extracted from the main config.
+type AuthzConfig struct {
+	// PolicyFile specifies the file holding the authorization policy (empty uses the default policy).
+	PolicyFile string
+}
+
+// ExtractAuthzConfig extracts vault item authorization configuration from the main config.
+func ExtractAuthzConfig(cfg *Config) *AuthzConfig {
+	return &AuthzConfig{
+		PolicyFile: cfg.AuthzPolicyFile,
+	}
+}
+
+// ApprovalConfig contains access approval workflow configuration extracted from the main config.
+type ApprovalConfig struct {
+	// Approvers lists the users approving access requests (empty disables the workflow).
+	Approvers []uuid.UUID
+	// RequestTTL specifies how long an access request waits for a decision.
+	RequestTTL time.Duration
+	// AccessTTL specifies how long an approved access request allows revealing the item.
+	AccessTTL time.Duration
+}
+
+// ExtractApprovalConfig extracts access approval workflow configuration from the main config.
+// Entries are validated when the configuration is loaded; invalid approvers are skipped here.
+func ExtractApprovalConfig(cfg *Config) *ApprovalConfig {
+	approvals := &ApprovalConfig{
+		RequestTTL: cfg.ApprovalRequestTTL,
+		AccessTTL:  cfg.ApprovalAccessTTL,
+	}
+	for _, entry := range cleanList(cfg.ApprovalApprovers) {
+		if id, err := uuid.Parse(entry); err == nil {
+			approvals.Approvers = append(approvals.Approvers, id)
+		}
+	}
+	return approvals
+}
+
+// CheckoutConfig contains shared credential check-out configuration extracted from the main config.
+type CheckoutConfig struct {
+	// TTL specifies how long a shared credential stays checked out (0 uses one hour).
+	TTL time.Duration
+	// SweepInterval specifies how often expired check-outs are checked in (0 disables the job).
+	SweepInterval time.Duration
+}
+
+// ExtractCheckoutConfig extracts shared credential check-out configuration from the main config.
+func ExtractCheckoutConfig(cfg *Config) *CheckoutConfig {
+	return &CheckoutConfig{
+		TTL:           cfg.CheckoutTTL,
+		SweepInterval: cfg.CheckoutSweepInterval,
+	}
+}
+
+// RotationConfig contains credential rotation configuration extracted from the main config.
+type RotationConfig struct {
+	// CheckInterval specifies how often due rotations are run (0 disables the job).
+	CheckInterval time.Duration
+}
+
+// ExtractRotationConfig extracts credential rotation configuration from the main config.
+func ExtractRotationConfig(cfg *Config) *RotationConfig {
+	return &RotationConfig{
+		CheckInterval: cfg.RotationCheckInterval,
+	}
+}
+
+// StorageGCConfig contains file storage reconciliation configuration extracted from the main config.
+type StorageGCConfig struct {
+	// Interval specifies how often file storage is reconciled (0 disables the job).
+	Interval time.Duration
+	// GracePeriod specifies how old orphaned contents must be before they are removed (0 uses 24 hours).
+	GracePeriod time.Duration
+	// Cleanup determines whether orphaned contents are removed.
+	Cleanup bool
+}
+
+// ExtractStorageGCConfig extracts file storage reconciliation configuration from the main config.
+func ExtractStorageGCConfig(cfg *Config) *StorageGCConfig {
+	return &StorageGCConfig{
+		Interval:    cfg.StorageGCInterval,
+		GracePeriod: cfg.StorageGCGracePeriod,
+		Cleanup:     cfg.StorageGCCleanup,
+	}
+}
+
+// StatsConfig contains usage statistics configuration extracted from the main config.
+type StatsConfig struct {
+	// RollupInterval specifies how often usage statistics are rolled up (0 disables the job).
+	RollupInterval time.Duration
+	// Retention specifies how long rollups are kept (0 keeps them forever).
+	Retention time.Duration
+}
+
+// ExtractStatsConfig extracts usage statistics configuration from the main config.
+func ExtractStatsConfig(cfg *Config) *StatsConfig {
+	return &StatsConfig{
+		RollupInterval: cfg.StatsRollupInterval,
+		Retention:      cfg.StatsRetention,
+	}
+}
+
+// PlanConfig contains plan enforcement configuration extracted from the main config.
+type PlanConfig struct {
+	// Default specifies the plan limiting every user (empty disables plan limits).
+	Default string
+}
+
+// ExtractPlanConfig extracts plan enforcement configuration from the main config.
+func ExtractPlanConfig(cfg *Config) *PlanConfig {
+	return &PlanConfig{
+		Default: cfg.PlanDefault,
+	}
+}
+
+// BillingConfig contains billing integration configuration extracted from the main config.
+type BillingConfig struct {
+	// StripePricePlans maps Stripe price IDs to the names of the plans they grant.
+	StripePricePlans map[string]string
+	// StripeWebhookSecret contains the signing secret of the Stripe webhook endpoint (empty disables Stripe).
+	StripeWebhookSecret string
+	// StripeWebhookTolerance specifies the accepted age of webhook signatures (0 uses five minutes).
+	StripeWebhookTolerance time.Duration
+}
+
+// ExtractBillingConfig extracts billing integration configuration from the main config.
+func ExtractBillingConfig(cfg *Config) *BillingConfig {
+	prices := make(map[string]string)
+	for _, entry := range cleanList(cfg.StripePricePlans) {
+		if price, name, err := parsePricePlan(entry); err == nil {
+			prices[price] = name
+		}
+	}
+	return &BillingConfig{
+		StripePricePlans:       prices,
+		StripeWebhookSecret:    cfg.StripeWebhookSecret,
+		StripeWebhookTolerance: cfg.StripeWebhookTolerance,
+	}
+}
+
+// LicenseConfig contains enterprise license configuration extracted from the main config.
+type LicenseConfig struct {
+	// PublicKey verifies license keys; nil leaves enterprise features unlicensed and available.
+	PublicKey ed25519.PublicKey
+	// Key contains the license key used until one is installed.
+	Key string
+	// GracePeriod specifies how long the features of an expired license are kept (0 uses 14 days).
+	GracePeriod time.Duration
+}
+
+// ExtractLicenseConfig extracts enterprise license configuration from the main config.
+func ExtractLicenseConfig(cfg *Config) *LicenseConfig {
+	// publicKey stays nil unless a valid vendor public key is configured.
+	var publicKey ed25519.PublicKey
+	if key, err := license.ParsePublicKey(cfg.LicensePublicKey); err == nil {
+		publicKey = key
+	}
+	return &LicenseConfig{
+		PublicKey:   publicKey,
+		Key:         strings.TrimSpace(cfg.LicenseKey),
+		GracePeriod: cfg.LicenseGracePeriod,
+	}
+}
+
+// NoteMergeConfig contains note merge mode configuration extracted from the main config.
+type NoteMergeConfig struct {
+	// CompactInterval specifies how often the updates of notes are compacted (0 disables the job).
+	CompactInterval time.Duration
+	// CompactThreshold specifies how many stored updates a note must have to be compacted (0 uses 100).
+	CompactThreshold int
+}
+
+// ExtractNoteMergeConfig extracts note merge mode configuration from the main config.
+func ExtractNoteMergeConfig(cfg *Config) *NoteMergeConfig {
+	return &NoteMergeConfig{
+		CompactInterval:  cfg.NoteMergeCompactInterval,
+		CompactThreshold: cfg.NoteMergeCompactThreshold,
+	}
+}
+
+// LoginProtectionConfig contains login anomaly detection configuration extracted from the main config.
+type LoginProtectionConfig struct {
+	// ApprovalURL specifies the public server URL of e-mailed approval links.
+	ApprovalURL string
+	// StepUpTTL specifies how long step-up verification codes are accepted.
+	StepUpTTL time.Duration
+	// AnomalyDetection determines whether logins from unrecognized devices or locations are held.
+	AnomalyDetection bool
+}
+
+// ExtractLoginProtectionConfig extracts login anomaly detection configuration from the main config.
+func ExtractLoginProtectionConfig(cfg *Config) *LoginProtectionConfig {
+	return &LoginProtectionConfig{
+		ApprovalURL:      cfg.LoginApprovalURL,
+		StepUpTTL:        cfg.LoginStepUpTTL,
+		AnomalyDetection: cfg.LoginAnomalyDetection,
+	}
+}
+
+// BruteForceConfig contains the failure log and ban list configuration extracted from the main config.
+type BruteForceConfig struct {
+	// LogPath specifies the file authentication failures are appended to (empty disables the failure log).
+	LogPath string
+	// Threshold specifies how many failures within the window ban a client network (0 disables the ban list).
+	Threshold int
+	// Window specifies the period failures are counted over.
+	Window time.Duration
+	// BanDuration specifies how long a banned client network is rejected.
+	BanDuration time.Duration
+}
+
+// ExtractBruteForceConfig extracts the failure log and ban list configuration from the main config.
+func ExtractBruteForceConfig(cfg *Config) *BruteForceConfig {
+	return &BruteForceConfig{
+		LogPath:     cfg.AuthFailureLogPath,
+		Threshold:   cfg.BruteForceThreshold,
+		Window:      cfg.BruteForceWindow,
+		BanDuration: cfg.BruteForceBanDuration,
+	}
+}
+
+// ChallengeConfig contains the human verification challenge configuration extracted from the main config.
+type ChallengeConfig struct {
+	// Provider specifies the challenge provider (hcaptcha, turnstile, pow; empty disables challenges).
+	Provider string
+	// SiteKey specifies the public site key of the CAPTCHA widget.
+	SiteKey string
+	// Secret contains the secret CAPTCHA responses are verified with (sensitive data).
+	Secret string
+	// Key contains the derived HMAC key proof-of-work puzzles are signed with (sensitive).
+	Key securebytes.Bytes
+	// Difficulty specifies the leading zero bits proof-of-work solutions must have.
+	Difficulty int
+	// TTL specifies how long a proof-of-work puzzle may be solved.
+	TTL time.Duration
+	// LoginFailures specifies how many failed logins within the window require a challenge on login
+	// (0 never requires one).
+	LoginFailures int
+	// LoginWindow specifies the period failed logins of a client network are counted over.
+	LoginWindow time.Duration
+}
+
+// ExtractChallengeConfig extracts the human verification challenge configuration from the main config.
+func ExtractChallengeConfig(cfg *Config) *ChallengeConfig {
+	return &ChallengeConfig{
+		Provider:      cfg.ChallengeProvider,
+		SiteKey:       cfg.ChallengeSiteKey,
+		Secret:        cfg.ChallengeSecret,
+		Key:           cfg.ChallengeKey,
+		Difficulty:    cfg.ChallengePoWDifficulty,
+		TTL:           cfg.ChallengeTTL,
+		LoginFailures: cfg.ChallengeLoginFailures,
+		LoginWindow:   cfg.ChallengeLoginWindow,
+	}
+}
+
+// MaintenanceConfig contains read-only maintenance mode configuration extracted from the main config.
+type MaintenanceConfig struct {
+	// Enabled determines whether the server starts in maintenance mode.
+	Enabled bool
+}
+
+// ExtractMaintenanceConfig extracts read-only maintenance mode configuration from the main config.
+func ExtractMaintenanceConfig(cfg *Config) *MaintenanceConfig {
+	return &MaintenanceConfig{
+		Enabled: cfg.MaintenanceMode,
+	}
+}
+
+// FeatureConfig contains feature flag configuration extracted from the main config.
+type FeatureConfig struct {
+	// Defaults maps feature keys to their deployment default state.
+	Defaults map[string]bool
+}
+
+// ExtractFeatureConfig extracts feature flag configuration from the main config.
+// Malformed entries are skipped; LoadConfig rejects them beforehand.
+func ExtractFeatureConfig(cfg *Config) *FeatureConfig {
+	defaults := make(map[string]bool)
+	for _, entry := range cleanList(cfg.FeatureFlags) {
+		if key, enabled, err := parseFeatureFlag(entry); err == nil {
+			defaults[key] = enabled
+		}
 	}
+	return &FeatureConfig{Defaults: defaults}
+}
+
+// ChaosConfig contains fault injection configuration extracted from the main config.
+type ChaosConfig struct {
+	// Rules select the HTTP requests faults are injected into.
+	Rules []chaos.Rule
+	// RepositoryFaults lists the faults injected into database operations.
+	RepositoryFaults []chaos.Fault
+}
+
+// ExtractChaosConfig extracts fault injection configuration from the main config. Rules and faults are
+// only extracted when fault injection is enabled; malformed entries are skipped, LoadConfig rejects them
+// beforehand.
+func ExtractChaosConfig(cfg *Config) *ChaosConfig {
+	out := &ChaosConfig{}
+	if !cfg.ChaosEnabled {
+		return out
+	}
+	for _, entry := range cleanList(cfg.ChaosRules) {
+		if r, err := chaos.ParseRule(entry); err == nil {
+			out.Rules = append(out.Rules, r)
+		}
+	}
+	for _, entry := range cleanList(cfg.ChaosRepositoryFaults) {
+		if f, err := chaos.ParseFault(entry); err == nil && f.Kind != chaos.KindDrop {
+			out.RepositoryFaults = append(out.RepositoryFaults, f)
+		}
+	}
+	return out
+}
+
+// cleanList trims whitespace around list entries and drops empty entries.
+func cleanList(items []string) []string {
+	var out []string
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
 }
 
 // FileStorageConfig contains file storage configuration extracted from the main config.
 type FileStorageConfig struct {
 	// BasePath specifies the base directory for file storage operations.
 	BasePath string
+	// Quota limits the bytes the stored files of each user may occupy (0 means no limit).
+	Quota int64
+	// TransferWorkers limits the file contents encrypted or decrypted concurrently (0 uses one per CPU).
+	TransferWorkers int
+	// TransferMemory limits the total bytes of the file contents encrypted or decrypted at once (0 means no limit).
+	TransferMemory int64
+	// TransferQueueTimeout bounds the wait of a file transfer for a free worker and memory.
+	TransferQueueTimeout time.Duration
 }
 
 // ExtractFileStorageConfig extracts file storage-specific configuration from the main config.
 func ExtractFileStorageConfig(cfg *Config) *FileStorageConfig {
 	return &FileStorageConfig{
-		BasePath: cfg.FileStorageBasePath,
+		BasePath:             cfg.FileStorageBasePath,
+		Quota:                cfg.FileStorageQuota,
+		TransferWorkers:      cfg.FileTransferWorkers,
+		TransferMemory:       cfg.FileTransferMemory,
+		TransferQueueTimeout: cfg.FileTransferQueueTimeout,
+	}
+}
+
+// IntegrityConfig contains stored data integrity configuration extracted from the main config.
+type IntegrityConfig struct {
+	// Key contains the derived HMAC key for stored row signatures (highly sensitive).
+	Key securebytes.Bytes
+	// VerifyInterval specifies how often stored row signatures are verified (0 disables the job).
+	VerifyInterval time.Duration
+}
+
+// ExtractIntegrityConfig extracts integrity-specific configuration from the main config.
+func ExtractIntegrityConfig(cfg *Config) *IntegrityConfig {
+	return &IntegrityConfig{
+		Key:            cfg.IntegrityKey,
+		VerifyInterval: cfg.IntegrityVerifyInterval,
+	}
+}
+
+// HistoryConfig contains item version retention configuration extracted from the main config.
+type HistoryConfig struct {
+	// Retention specifies how long replaced item versions are retained (0 keeps them forever).
+	Retention time.Duration
+	// PruneInterval specifies how often expired item versions are pruned (0 disables the job).
+	PruneInterval time.Duration
+}
+
+// ExtractHistoryConfig extracts item history-specific configuration from the main config.
+func ExtractHistoryConfig(cfg *Config) *HistoryConfig {
+	return &HistoryConfig{
+		Retention:     cfg.ItemHistoryRetention,
+		PruneInterval: cfg.ItemHistoryPruneInterval,
+	}
+}
+
+// BackupConfig contains backup snapshot configuration extracted from the main config.
+type BackupConfig struct {
+	// Storage specifies the snapshot store type (local, s3).
+	Storage string
+	// LocalPath specifies the directory holding snapshots of the local store.
+	LocalPath string
+	// FilesBasePath specifies the file storage directory included in snapshots.
+	FilesBasePath string
+	// S3Endpoint specifies the base URL of the S3-compatible snapshot store.
+	S3Endpoint string
+	// S3Region specifies the signing region of the S3-compatible snapshot store.
+	S3Region string
+	// S3Bucket specifies the bucket holding snapshots in the S3-compatible store.
+	S3Bucket string
+	// S3AccessKeyID specifies the access key identifier of the S3-compatible snapshot store.
+	S3AccessKeyID string
+	// S3SecretAccessKey contains the secret key of the S3-compatible snapshot store (sensitive data).
+	S3SecretAccessKey string
+	// Key contains the derived encryption key for backup snapshots (highly sensitive).
+	Key securebytes.Bytes
+}
+
+// ExtractBackupConfig extracts backup-specific configuration from the main config.
+func ExtractBackupConfig(cfg *Config) *BackupConfig {
+	return &BackupConfig{
+		Storage:           cfg.BackupStorage,
+		LocalPath:         cfg.BackupLocalPath,
+		FilesBasePath:     cfg.FileStorageBasePath,
+		S3Endpoint:        cfg.BackupS3Endpoint,
+		S3Region:          cfg.BackupS3Region,
+		S3Bucket:          cfg.BackupS3Bucket,
+		S3AccessKeyID:     cfg.BackupS3AccessKeyID,
+		S3SecretAccessKey: cfg.BackupS3SecretAccessKey,
+		Key:               cfg.BackupKey,
+	}
+}
+
+// MailConfig contains outgoing mail configuration extracted from the main config.
+type MailConfig struct {
+	// Host specifies the SMTP relay hostname (empty disables mail delivery).
+	Host string
+	// Username specifies the SMTP relay username (empty disables authentication).
+	Username string
+	// Password contains the SMTP relay password (sensitive data).
+	Password string
+	// From specifies the sender address of outgoing mail.
+	From string
+	// Port specifies the SMTP relay port number.
+	Port int
+}
+
+// ExtractMailConfig extracts mail-specific configuration from the main config.
+func ExtractMailConfig(cfg *Config) *MailConfig {
+	return &MailConfig{
+		Host:     cfg.SMTPHost,
+		Username: cfg.SMTPUsername,
+		Password: cfg.SMTPPassword,
+		From:     cfg.SMTPFrom,
+		Port:     cfg.SMTPPort,
+	}
+}
+
+// NotificationConfig contains notification channel configuration extracted from the main config.
+type NotificationConfig struct {
+	// TelegramBotToken authenticates the Telegram bot (sensitive data, empty disables Telegram channels).
+	TelegramBotToken string
+	// VAPIDPrivateKey signs web push messages (sensitive data, empty disables web push channels).
+	VAPIDPrivateKey string
+	// VAPIDSubject specifies the contact of the operator announced to push services.
+	VAPIDSubject string
+}
+
+// ExtractNotificationConfig extracts notification channel configuration from the main config.
+func ExtractNotificationConfig(cfg *Config) *NotificationConfig {
+	return &NotificationConfig{
+		TelegramBotToken: cfg.TelegramBotToken,
+		VAPIDPrivateKey:  cfg.WebPushVAPIDPrivateKey,
+		VAPIDSubject:     cfg.WebPushVAPIDSubject,
+	}
+}
+
+// HealthReportConfig contains vault health report configuration extracted from the main config.
+type HealthReportConfig struct {
+	// Interval specifies how often vault health reports are generated and mailed (0 disables the job).
+	Interval time.Duration
+}
+
+// ExtractHealthReportConfig extracts vault health report configuration from the main config.
+func ExtractHealthReportConfig(cfg *Config) *HealthReportConfig {
+	return &HealthReportConfig{
+		Interval: cfg.HealthReportInterval,
+	}
+}
+
+// LeaseConfig contains machine lease configuration extracted from the main config.
+type LeaseConfig struct {
+	// TTL specifies how long leased secret values may be used (0 uses five minutes).
+	TTL time.Duration
+}
+
+// ExtractLeaseConfig extracts machine lease configuration from the main config.
+func ExtractLeaseConfig(cfg *Config) *LeaseConfig {
+	return &LeaseConfig{
+		TTL: cfg.LeaseTTL,
+	}
+}
+
+// PurgeConfig contains item purge configuration extracted from the main config.
+type PurgeConfig struct {
+	// TokenTTL specifies how long purge confirmation tokens are accepted (0 uses five minutes).
+	TokenTTL time.Duration
+}
+
+// ExtractPurgeConfig extracts item purge configuration from the main config.
+func ExtractPurgeConfig(cfg *Config) *PurgeConfig {
+	return &PurgeConfig{
+		TokenTTL: cfg.PurgeTokenTTL,
+	}
+}
+
+// InviteConfig contains the registration mode and invite configuration extracted from the main config.
+type InviteConfig struct {
+	// Required indicates that registration requires an invite code.
+	Required bool
+	// TTL specifies how long invites last unless their creator sets an expiry (0 uses one week).
+	TTL time.Duration
+	// UserQuota specifies how many pending and redeemed invites each user may hold.
+	UserQuota int
+}
+
+// ExtractInviteConfig extracts the registration mode and invite configuration from the main config.
+func ExtractInviteConfig(cfg *Config) *InviteConfig {
+	return &InviteConfig{
+		Required:  cfg.RegistrationMode == "invite",
+		TTL:       cfg.InviteTTL,
+		UserQuota: cfg.InviteUserQuota,
+	}
+}
+
+// PasswordPolicyConfig contains user password policy configuration extracted from the main config.
+type PasswordPolicyConfig struct {
+	// RequiredClasses lists the character classes passwords must contain.
+	RequiredClasses []auth.PasswordClass
+	// BannedFile specifies the file listing passwords rejected in addition to the common ones (empty adds none).
+	BannedFile string
+	// MinLength specifies the minimum password length (0 uses 8).
+	MinLength int
+	// History specifies how many recent passwords of a user a new password may not match (0 allows reuse).
+	History int
+	// BanCommon determines whether the built-in common passwords are rejected.
+	BanCommon bool
+}
+
+// ExtractPasswordPolicyConfig extracts user password policy configuration from the main config.
+// Entries are validated when the configuration is loaded; unknown character classes are skipped here.
+func ExtractPasswordPolicyConfig(cfg *Config) *PasswordPolicyConfig {
+	policy := &PasswordPolicyConfig{
+		BannedFile: cfg.PasswordBannedFile,
+		MinLength:  cfg.PasswordMinLength,
+		History:    cfg.PasswordHistory,
+		BanCommon:  cfg.PasswordBanCommon,
+	}
+	for _, entry := range cleanList(cfg.PasswordRequiredClasses) {
+		if class, err := auth.ParsePasswordClass(entry); err == nil {
+			policy.RequiredClasses = append(policy.RequiredClasses, class)
+		}
+	}
+	return policy
+}
+
+// OnboardingConfig contains onboarding wizard configuration extracted from the main config.
+type OnboardingConfig struct {
+	// Steps lists the steps of the wizard in order (empty uses all of them).
+	Steps []string
+	// MasterKeyLength holds the length of the configured master key.
+	MasterKeyLength int
+}
+
+// ExtractOnboardingConfig extracts onboarding wizard configuration from the main config.
+// Steps are validated when the configuration is loaded.
+func ExtractOnboardingConfig(cfg *Config) *OnboardingConfig {
+	return &OnboardingConfig{
+		Steps:           cleanList(cfg.OnboardingSteps),
+		MasterKeyLength: cfg.MasterKeyLength,
+	}
+}
+
+// TelemetryConfig contains anonymous usage ping configuration extracted from the main config.
+type TelemetryConfig struct {
+	// Endpoint contains the URL pings are posted to.
+	Endpoint string
+	// Interval specifies how often pings are sent (0 uses 24 hours).
+	Interval time.Duration
+	// Enabled determines whether pings are sent.
+	Enabled bool
+}
+
+// ExtractTelemetryConfig extracts anonymous usage ping configuration from the main config.
+func ExtractTelemetryConfig(cfg *Config) *TelemetryConfig {
+	return &TelemetryConfig{
+		Endpoint: cfg.TelemetryEndpoint,
+		Interval: cfg.TelemetryInterval,
+		Enabled:  cfg.TelemetryEnabled,
+	}
+}
+
+// UpdateCheckConfig contains server update check configuration extracted from the main config.
+type UpdateCheckConfig struct {
+	// PublicKey verifies the signatures of release manifests.
+	PublicKey ed25519.PublicKey
+	// URL contains the URL of the release manifest (empty disables update checks).
+	URL string
+	// Interval specifies how often the release manifest is checked (0 uses 24 hours).
+	Interval time.Duration
+}
+
+// ExtractUpdateCheckConfig extracts server update check configuration from the main config.
+func ExtractUpdateCheckConfig(cfg *Config) *UpdateCheckConfig {
+	// publicKey stays nil unless a valid release public key is configured.
+	var publicKey ed25519.PublicKey
+	if key, err := release.ParsePublicKey(cfg.UpdateCheckPublicKey); err == nil {
+		publicKey = key
+	}
+	return &UpdateCheckConfig{
+		PublicKey: publicKey,
+		URL:       cfg.UpdateCheckURL,
+		Interval:  cfg.UpdateCheckInterval,
+	}
+}
+
+// RecordingConfig contains request recording configuration extracted from the main config.
+type RecordingConfig struct {
+	// Key contains the derived encryption key for recorded requests (highly sensitive).
+	Key securebytes.Bytes
+	// MaxWindow limits the time window of recordings (0 disables the recording mode).
+	MaxWindow time.Duration
+	// Retention specifies how long recordings are kept after their window ends (0 keeps them until deleted).
+	Retention time.Duration
+	// MaxBodySize limits the bytes recorded of every request and response body (0 records no bodies).
+	MaxBodySize int
+}
+
+// ExtractRecordingConfig extracts request recording configuration from the main config.
+func ExtractRecordingConfig(cfg *Config) *RecordingConfig {
+	return &RecordingConfig{
+		Key:         cfg.RecordingKey,
+		MaxWindow:   cfg.RecordingMaxWindow,
+		Retention:   cfg.RecordingRetention,
+		MaxBodySize: cfg.RecordingMaxBodySize,
+	}
+}
+
+// AutofillApp describes an Android app associated with the server for autofill.
+type AutofillApp struct {
+	// PackageName contains the application ID of the app.
+	PackageName string
+	// Fingerprints lists the SHA-256 fingerprints of the signing certificates of the app.
+	Fingerprints []string
+}
+
+// AutofillConfig contains the native app associations for autofill extracted from the main config.
+type AutofillConfig struct {
+	// AndroidApps lists the associated Android apps in configuration order.
+	AndroidApps []AutofillApp
+	// IOSApps lists the app IDs of the associated iOS apps.
+	IOSApps []string
+}
+
+// ExtractAutofillConfig extracts the native app associations for autofill from the main config.
+// The fingerprints of a package listed several times are collected into one app.
+func ExtractAutofillConfig(cfg *Config) *AutofillConfig {
+	// apps collects the Android apps by package name in configuration order.
+	var apps []AutofillApp
+	for _, entry := range cleanList(cfg.AutofillAndroidApps) {
+		pkg, fingerprint, err := parseAndroidApp(entry)
+		if err != nil {
+			continue
+		}
+		i := slices.IndexFunc(apps, func(a AutofillApp) bool { return a.PackageName == pkg })
+		if i < 0 {
+			apps = append(apps, AutofillApp{PackageName: pkg})
+			i = len(apps) - 1
+		}
+		if !slices.Contains(apps[i].Fingerprints, fingerprint) {
+			apps[i].Fingerprints = append(apps[i].Fingerprints, fingerprint)
+		}
+	}
+	return &AutofillConfig{
+		AndroidApps: apps,
+		IOSApps:     cleanList(cfg.AutofillIOSApps),
+	}
+}
+
+// RetentionConfig contains the default data retention rules extracted from the main config.
+type RetentionConfig struct {
+	// Interval specifies how often retention policies are enforced (0 disables the job).
+	Interval time.Duration
+	// KeepVersions specifies how many replaced versions of every item are kept (0 keeps them all).
+	KeepVersions int
+	// KeepAuditDays specifies for how many days item reveal records are kept (0 keeps them forever).
+	KeepAuditDays int
+}
+
+// ExtractRetentionConfig extracts the default data retention rules from the main config.
+func ExtractRetentionConfig(cfg *Config) *RetentionConfig {
+	return &RetentionConfig{
+		Interval:      cfg.RetentionInterval,
+		KeepVersions:  cfg.RetentionKeepVersions,
+		KeepAuditDays: cfg.RetentionKeepAuditDays,
 	}
 }
@@ -1,9 +1,19 @@
 package config
 
 import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"net/http"
+	"net/netip"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/chaos"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/auth"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -19,22 +29,26 @@ func TestExtractDBConfig(t *testing.T) {
 		{
 			name: "complete database config",
 			config: &Config{
-				PostgresHost:        "localhost",
-				PostgresUser:        "testuser",
-				PostgresPassword:    "testpass",
-				PostgresDBName:      "testdb",
-				PostgresSSLMode:     "disable",
-				PostgresPort:        5432,
-				PostgresInitTimeout: 30 * time.Second,
+				PostgresHost:           "localhost",
+				PostgresUser:           "testuser",
+				PostgresPassword:       "testpass",
+				PostgresDBName:         "testdb",
+				PostgresSSLMode:        "disable",
+				PostgresPort:           5432,
+				PostgresInitTimeout:    30 * time.Second,
+				PostgresTxRetries:      3,
+				PostgresTxRetryBackoff: 20 * time.Millisecond,
 			},
 			expected: &DBConfig{
-				Host:     "localhost",
-				User:     "testuser",
-				Password: "testpass",
-				DBName:   "testdb",
-				SSLMode:  "disable",
-				Port:     5432,
-				Timeout:  30 * time.Second,
+				Host:           "localhost",
+				User:           "testuser",
+				Password:       "testpass",
+				DBName:         "testdb",
+				SSLMode:        "disable",
+				Port:           5432,
+				Timeout:        30 * time.Second,
+				TxRetries:      3,
+				TxRetryBackoff: 20 * time.Millisecond,
 			},
 		},
 		{
@@ -193,6 +207,31 @@ func TestExtractAuthConfig(t *testing.T) {
 				AccessTokenLifeTime: 24 * time.Hour,
 			},
 		},
+		{
+			name: "token claims and federation",
+			config: &Config{
+				MasterKey:           []byte("key"),
+				AccessTokenLifeTime: time.Hour,
+				JWTIssuer:           "https://vault.example.com",
+				JWTAudiences:        []string{" vault", "", "cli "},
+				JWTLeeway:           30 * time.Second,
+				JWTJWKSURL:          "https://idp.example.com/jwks.json",
+				JWTJWKSIssuer:       "https://idp.example.com",
+				JWTJWKSUserClaim:    "vault_uid",
+				JWTJWKSCacheTTL:     10 * time.Minute,
+			},
+			expected: &AuthConfig{
+				MasterKey:           []byte("key"),
+				AccessTokenLifeTime: time.Hour,
+				Issuer:              "https://vault.example.com",
+				Audiences:           []string{"vault", "cli"},
+				Leeway:              30 * time.Second,
+				JWKSURL:             "https://idp.example.com/jwks.json",
+				JWKSIssuer:          "https://idp.example.com",
+				JWKSUserClaim:       "vault_uid",
+				JWKSCacheTTL:        10 * time.Minute,
+			},
+		},
 		{
 			name:   "empty auth config",
 			config: &Config{},
@@ -264,6 +303,56 @@ func TestExtractDeliveryConfig(t *testing.T) {
 				TLSKeyFile:   "/path/to/key.pem",
 			},
 		},
+		{
+			name: "ACME certificates",
+			config: &Config{
+				ApplicationPort:     443,
+				TLSEnabled:          true,
+				TLSACMEDomains:      []string{"vault.example.com", " api.example.com"},
+				TLSACMEEmail:        "admin@example.com",
+				TLSACMECacheDir:     "/app/certs/acme",
+				TLSACMEDirectoryURL: "https://acme-staging-v02.api.letsencrypt.org/directory",
+				TLSACMEHTTPAddr:     ":80",
+			},
+			expected: &DeliveryConfig{
+				Address:          ":443",
+				TLSEnabled:       true,
+				ACMEDomains:      []string{"vault.example.com", "api.example.com"},
+				ACMEEmail:        "admin@example.com",
+				ACMECacheDir:     "/app/certs/acme",
+				ACMEDirectoryURL: "https://acme-staging-v02.api.letsencrypt.org/directory",
+				ACMEHTTPAddr:     ":80",
+			},
+		},
+		{
+			name: "connection tuning",
+			config: &Config{
+				ApplicationPort:         8080,
+				HTTPReadTimeout:         0,
+				HTTPReadHeaderTimeout:   10 * time.Second,
+				HTTPWriteTimeout:        0,
+				HTTPIdleTimeout:         2 * time.Minute,
+				HTTPRequestTimeout:      30 * time.Second,
+				HTTPItemsRequestTimeout: time.Minute,
+				HTTPFilesRequestTimeout: 10 * time.Minute,
+				HTTPMaxHeaderBytes:      64 << 10,
+				HTTP2Enabled:            true,
+				HTTPKeepAlivesEnabled:   true,
+				WebUIEnabled:            true,
+			},
+			expected: &DeliveryConfig{
+				Address:             ":8080",
+				ReadHeaderTimeout:   10 * time.Second,
+				IdleTimeout:         2 * time.Minute,
+				RequestTimeout:      30 * time.Second,
+				ItemsRequestTimeout: time.Minute,
+				FilesRequestTimeout: 10 * time.Minute,
+				MaxHeaderBytes:      64 << 10,
+				HTTP2Enabled:        true,
+				KeepAlivesEnabled:   true,
+				WebUIEnabled:        true,
+			},
+		},
 		{
 			name: "HTTP only config",
 			config: &Config{
@@ -376,6 +465,32 @@ func TestExtractFileStorageConfig(t *testing.T) {
 				BasePath: "C:\\data\\aegis_vault_keeper",
 			},
 		},
+		{
+			name: "with quota",
+			config: &Config{
+				FileStorageBasePath: "/app/filestorage",
+				FileStorageQuota:    10 << 30,
+			},
+			expected: &FileStorageConfig{
+				BasePath: "/app/filestorage",
+				Quota:    10 << 30,
+			},
+		},
+		{
+			name: "with transfer limits",
+			config: &Config{
+				FileStorageBasePath:      "/app/filestorage",
+				FileTransferWorkers:      4,
+				FileTransferMemory:       256 << 20,
+				FileTransferQueueTimeout: 10 * time.Second,
+			},
+			expected: &FileStorageConfig{
+				BasePath:             "/app/filestorage",
+				TransferWorkers:      4,
+				TransferMemory:       256 << 20,
+				TransferQueueTimeout: 10 * time.Second,
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -390,6 +505,146 @@ func TestExtractFileStorageConfig(t *testing.T) {
 	}
 }
 
+func TestExtractIntegrityConfig(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		config   *Config
+		expected *IntegrityConfig
+		name     string
+	}{
+		{
+			name: "enabled verification",
+			config: &Config{
+				IntegrityKey:            []byte("integrity_key"),
+				IntegrityVerifyInterval: time.Hour,
+			},
+			expected: &IntegrityConfig{
+				Key:            []byte("integrity_key"),
+				VerifyInterval: time.Hour,
+			},
+		},
+		{
+			name: "disabled verification",
+			config: &Config{
+				IntegrityKey: []byte("integrity_key"),
+			},
+			expected: &IntegrityConfig{
+				Key: []byte("integrity_key"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			result := ExtractIntegrityConfig(tt.config)
+
+			require.NotNil(t, result)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestExtractHistoryConfig(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		config   *Config
+		expected *HistoryConfig
+		name     string
+	}{
+		{
+			name: "retention with pruning",
+			config: &Config{
+				ItemHistoryRetention:     90 * 24 * time.Hour,
+				ItemHistoryPruneInterval: time.Hour,
+			},
+			expected: &HistoryConfig{
+				Retention:     90 * 24 * time.Hour,
+				PruneInterval: time.Hour,
+			},
+		},
+		{
+			name:     "keep versions forever",
+			config:   &Config{},
+			expected: &HistoryConfig{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			result := ExtractHistoryConfig(tt.config)
+
+			require.NotNil(t, result)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestExtractBackupConfig(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		config   *Config
+		expected *BackupConfig
+		name     string
+	}{
+		{
+			name: "local storage",
+			config: &Config{
+				BackupStorage:       "local",
+				BackupLocalPath:     "/app/backups",
+				FileStorageBasePath: "/app/filestorage",
+				BackupKey:           []byte("backup_key"),
+			},
+			expected: &BackupConfig{
+				Storage:       "local",
+				LocalPath:     "/app/backups",
+				FilesBasePath: "/app/filestorage",
+				Key:           []byte("backup_key"),
+			},
+		},
+		{
+			name: "s3 storage",
+			config: &Config{
+				BackupStorage:           "s3",
+				BackupS3Endpoint:        "https://s3.example.com",
+				BackupS3Region:          "eu-central-1",
+				BackupS3Bucket:          "vault-backups",
+				BackupS3AccessKeyID:     "AKID",
+				BackupS3SecretAccessKey: "secret",
+				FileStorageBasePath:     "/app/filestorage",
+				BackupKey:               []byte("backup_key"),
+			},
+			expected: &BackupConfig{
+				Storage:           "s3",
+				FilesBasePath:     "/app/filestorage",
+				S3Endpoint:        "https://s3.example.com",
+				S3Region:          "eu-central-1",
+				S3Bucket:          "vault-backups",
+				S3AccessKeyID:     "AKID",
+				S3SecretAccessKey: "secret",
+				Key:               []byte("backup_key"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			result := ExtractBackupConfig(tt.config)
+
+			require.NotNil(t, result)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
 func TestExtractedConfigStructures(t *testing.T) {
 	t.Parallel()
 
@@ -447,3 +702,1062 @@ func TestExtractedConfigStructures(t *testing.T) {
 	assert.Equal(t, "localhost", newDBConfig.Host)
 	assert.NotEqual(t, "modified", newDBConfig.Host)
 }
+
+func TestExtractMailConfig(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		config   *Config
+		expected *MailConfig
+		name     string
+	}{
+		{
+			name: "authenticated relay",
+			config: &Config{
+				SMTPHost:     "smtp.example.com",
+				SMTPPort:     587,
+				SMTPUsername: "mailer",
+				SMTPPassword: "secret",
+				SMTPFrom:     "vault@example.com",
+			},
+			expected: &MailConfig{
+				Host:     "smtp.example.com",
+				Port:     587,
+				Username: "mailer",
+				Password: "secret",
+				From:     "vault@example.com",
+			},
+		},
+		{
+			name:     "mail disabled",
+			config:   &Config{},
+			expected: &MailConfig{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			result := ExtractMailConfig(tt.config)
+
+			require.NotNil(t, result)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestExtractHealthReportConfig(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		config   *Config
+		expected *HealthReportConfig
+		name     string
+	}{
+		{
+			name:     "daily reports",
+			config:   &Config{HealthReportInterval: 24 * time.Hour},
+			expected: &HealthReportConfig{Interval: 24 * time.Hour},
+		},
+		{
+			name:     "reports disabled",
+			config:   &Config{},
+			expected: &HealthReportConfig{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			result := ExtractHealthReportConfig(tt.config)
+
+			require.NotNil(t, result)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestExtractCryptoConfig(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		config   *Config
+		expected *CryptoConfig
+		name     string
+	}{
+		{
+			name:     "explicit workers",
+			config:   &Config{CryptoWorkers: 4},
+			expected: &CryptoConfig{Workers: 4},
+		},
+		{
+			name:     "one worker per CPU",
+			config:   &Config{},
+			expected: &CryptoConfig{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			result := ExtractCryptoConfig(tt.config)
+
+			require.NotNil(t, result)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestExtractCORSConfig(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		config   *Config
+		expected *CORSConfig
+		name     string
+	}{
+		{
+			name: "restricted origins",
+			config: &Config{
+				CORSAllowedOrigins:   []string{"https://app.example.com", " https://admin.example.com "},
+				CORSAllowedMethods:   []string{"GET", "POST", ""},
+				CORSAllowedHeaders:   []string{"Authorization", "Content-Type"},
+				CORSMaxAge:           10 * time.Minute,
+				CORSAllowCredentials: true,
+			},
+			expected: &CORSConfig{
+				AllowedOrigins:   []string{"https://app.example.com", "https://admin.example.com"},
+				AllowedMethods:   []string{"GET", "POST"},
+				AllowedHeaders:   []string{"Authorization", "Content-Type"},
+				MaxAge:           10 * time.Minute,
+				AllowCredentials: true,
+			},
+		},
+		{
+			name:     "cors disabled",
+			config:   &Config{CORSAllowedOrigins: []string{""}},
+			expected: &CORSConfig{},
+		},
+		{
+			name: "csrf header allowed for sessions",
+			config: &Config{
+				CORSAllowedOrigins:    []string{"https://app.example.com"},
+				CORSAllowedHeaders:    []string{"Authorization", "Content-Type"},
+				SessionCookiesEnabled: true,
+			},
+			expected: &CORSConfig{
+				AllowedOrigins: []string{"https://app.example.com"},
+				AllowedHeaders: []string{"Authorization", "Content-Type", "X-CSRF-Token"},
+			},
+		},
+		{
+			name: "csrf header configured",
+			config: &Config{
+				CORSAllowedHeaders:    []string{"x-csrf-token"},
+				SessionCookiesEnabled: true,
+			},
+			expected: &CORSConfig{AllowedHeaders: []string{"x-csrf-token"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			result := ExtractCORSConfig(tt.config)
+
+			require.NotNil(t, result)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestExtractSecurityHeadersConfig(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		config   *Config
+		expected *SecurityHeadersConfig
+		name     string
+	}{
+		{
+			name: "tls with hsts",
+			config: &Config{
+				SecurityCSP:                   "default-src 'none'",
+				SecurityHTMLCSP:               "default-src 'self'",
+				SecurityHSTSMaxAge:            8760 * time.Hour,
+				SecurityHSTSIncludeSubdomains: true,
+				TLSEnabled:                    true,
+			},
+			expected: &SecurityHeadersConfig{
+				CSP:                   "default-src 'none'",
+				HTMLCSP:               "default-src 'self'",
+				HSTSMaxAge:            8760 * time.Hour,
+				HSTSIncludeSubdomains: true,
+				TLSEnabled:            true,
+			},
+		},
+		{
+			name:     "defaults",
+			config:   &Config{},
+			expected: &SecurityHeadersConfig{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			result := ExtractSecurityHeadersConfig(tt.config)
+
+			require.NotNil(t, result)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestExtractProxyConfig(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		config   *Config
+		expected *ProxyConfig
+		name     string
+	}{
+		{
+			name:     "no trusted proxies",
+			config:   &Config{},
+			expected: &ProxyConfig{},
+		},
+		{
+			name: "cidrs and bare addresses",
+			config: &Config{
+				TrustedProxies: []string{"10.1.0.0/16", " 192.168.1.10 ", "2001:db8::1", "", "172.16.5.4/12"},
+			},
+			expected: &ProxyConfig{
+				TrustedProxies: []netip.Prefix{
+					netip.MustParsePrefix("10.1.0.0/16"),
+					netip.MustParsePrefix("192.168.1.10/32"),
+					netip.MustParsePrefix("2001:db8::1/128"),
+					netip.MustParsePrefix("172.16.0.0/12"),
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.expected, ExtractProxyConfig(tt.config))
+		})
+	}
+}
+
+func TestExtractAdminConfig(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		config   *Config
+		expected *AdminConfig
+		name     string
+	}{
+		{name: "admin api disabled", config: &Config{}, expected: &AdminConfig{}},
+		{
+			name:     "token set",
+			config:   &Config{AdminAPIToken: "admin-token"},
+			expected: &AdminConfig{Token: "admin-token"},
+		},
+		{
+			name: "dual control",
+			config: &Config{
+				AdminAPIToken:       "admin-token",
+				AdminOperatorTokens: []string{" alice:alice-token ", "bob:bob:token", "malformed", ""},
+				AdminNotifyEmails:   []string{" security@example.com ", ""},
+				AdminDualControlTTL: 24 * time.Hour,
+				AdminDualControl:    true,
+			},
+			expected: &AdminConfig{
+				Operators:      map[string]string{"alice": "alice-token", "bob": "bob:token"},
+				NotifyEmails:   []string{"security@example.com"},
+				Token:          "admin-token",
+				DualControlTTL: 24 * time.Hour,
+				DualControl:    true,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.expected, ExtractAdminConfig(tt.config))
+		})
+	}
+}
+
+func TestExtractSCIMConfig(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		config   *Config
+		expected *SCIMConfig
+		name     string
+	}{
+		{name: "scim api disabled", config: &Config{}, expected: &SCIMConfig{}},
+		{
+			name:     "token set",
+			config:   &Config{SCIMAPIToken: "scim-token"},
+			expected: &SCIMConfig{Token: "scim-token"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.expected, ExtractSCIMConfig(tt.config))
+		})
+	}
+}
+
+func TestExtractNotificationConfig(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		config   *Config
+		expected *NotificationConfig
+		name     string
+	}{
+		{name: "channels disabled", config: &Config{}, expected: &NotificationConfig{}},
+		{
+			name: "channels configured",
+			config: &Config{
+				TelegramBotToken:       "bot-token",
+				WebPushVAPIDPrivateKey: "vapid-key",
+				WebPushVAPIDSubject:    "mailto:ops@example.com",
+			},
+			expected: &NotificationConfig{
+				TelegramBotToken: "bot-token",
+				VAPIDPrivateKey:  "vapid-key",
+				VAPIDSubject:     "mailto:ops@example.com",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.expected, ExtractNotificationConfig(tt.config))
+		})
+	}
+}
+
+func TestExtractRequestSigningConfig(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		config   *Config
+		expected *RequestSigningConfig
+		name     string
+	}{
+		{
+			name:     "admin signing disabled",
+			config:   &Config{RequestSignatureMaxSkew: 5 * time.Minute},
+			expected: &RequestSigningConfig{MaxSkew: 5 * time.Minute},
+		},
+		{
+			name:     "admin key set",
+			config:   &Config{AdminSigningKey: "signing-key", RequestSignatureMaxSkew: time.Minute},
+			expected: &RequestSigningConfig{AdminKey: "signing-key", MaxSkew: time.Minute},
+		},
+		{
+			name: "response key set",
+			config: &Config{
+				ResponseSigningKey:      base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{0x01}, 32)),
+				RequestSignatureMaxSkew: time.Minute,
+			},
+			expected: &RequestSigningConfig{
+				ResponseKey: ed25519.NewKeyFromSeed(bytes.Repeat([]byte{0x01}, 32)),
+				MaxSkew:     time.Minute,
+			},
+		},
+		{
+			name:     "invalid response key ignored",
+			config:   &Config{ResponseSigningKey: "garbage", RequestSignatureMaxSkew: time.Minute},
+			expected: &RequestSigningConfig{MaxSkew: time.Minute},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.expected, ExtractRequestSigningConfig(tt.config))
+		})
+	}
+}
+
+func TestExtractGeoIPConfig(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		config   *Config
+		expected *GeoIPConfig
+		name     string
+	}{
+		{name: "geoip disabled", config: &Config{}, expected: &GeoIPConfig{}},
+		{
+			name:     "database path set",
+			config:   &Config{GeoIPDBPath: "/app/geoip/GeoLite2-Country.mmdb"},
+			expected: &GeoIPConfig{DBPath: "/app/geoip/GeoLite2-Country.mmdb"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.expected, ExtractGeoIPConfig(tt.config))
+		})
+	}
+}
+
+func TestExtractGeofenceConfig(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		config   *Config
+		expected *GeofenceConfig
+		name     string
+	}{
+		{name: "geofence disabled", config: &Config{}, expected: &GeofenceConfig{}},
+		{
+			name: "networks and countries",
+			config: &Config{
+				RestrictedItemsNetworks:  []string{"10.1.0.0/16", " 192.168.1.10 ", ""},
+				RestrictedItemsCountries: []string{"de", " FR ", ""},
+			},
+			expected: &GeofenceConfig{
+				Networks: []netip.Prefix{
+					netip.MustParsePrefix("10.1.0.0/16"),
+					netip.MustParsePrefix("192.168.1.10/32"),
+				},
+				Countries: []string{"DE", "FR"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.expected, ExtractGeofenceConfig(tt.config))
+		})
+	}
+}
+
+func TestExtractAuthzConfig(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		config   *Config
+		expected *AuthzConfig
+		name     string
+	}{
+		{name: "default policy", config: &Config{}, expected: &AuthzConfig{}},
+		{
+			name:     "policy file set",
+			config:   &Config{AuthzPolicyFile: "/app/authz/vault.policy"},
+			expected: &AuthzConfig{PolicyFile: "/app/authz/vault.policy"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.expected, ExtractAuthzConfig(tt.config))
+		})
+	}
+}
+
+func TestExtractApprovalConfig(t *testing.T) {
+	t.Parallel()
+
+	approver := uuid.MustParse("550e8400-e29b-41d4-a716-446655440000")
+
+	tests := []struct {
+		config   *Config
+		expected *ApprovalConfig
+		name     string
+	}{
+		{name: "workflow disabled", config: &Config{}, expected: &ApprovalConfig{}},
+		{
+			name: "approvers and TTLs",
+			config: &Config{
+				ApprovalApprovers:  []string{" " + approver.String() + " ", ""},
+				ApprovalRequestTTL: time.Hour,
+				ApprovalAccessTTL:  15 * time.Minute,
+			},
+			expected: &ApprovalConfig{
+				Approvers:  []uuid.UUID{approver},
+				RequestTTL: time.Hour,
+				AccessTTL:  15 * time.Minute,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.expected, ExtractApprovalConfig(tt.config))
+		})
+	}
+}
+
+func TestExtractCheckoutConfig(t *testing.T) {
+	t.Parallel()
+
+	cfg := &Config{CheckoutTTL: time.Hour, CheckoutSweepInterval: time.Minute}
+
+	assert.Equal(t, &CheckoutConfig{TTL: time.Hour, SweepInterval: time.Minute}, ExtractCheckoutConfig(cfg))
+}
+
+func TestExtractRotationConfig(t *testing.T) {
+	t.Parallel()
+
+	cfg := &Config{RotationCheckInterval: 5 * time.Minute}
+
+	assert.Equal(t, &RotationConfig{CheckInterval: 5 * time.Minute}, ExtractRotationConfig(cfg))
+}
+
+func TestExtractStorageGCConfig(t *testing.T) {
+	t.Parallel()
+
+	cfg := &Config{StorageGCInterval: time.Hour, StorageGCGracePeriod: 48 * time.Hour, StorageGCCleanup: true}
+
+	assert.Equal(t,
+		&StorageGCConfig{Interval: time.Hour, GracePeriod: 48 * time.Hour, Cleanup: true},
+		ExtractStorageGCConfig(cfg),
+	)
+}
+
+func TestExtractStatsConfig(t *testing.T) {
+	t.Parallel()
+
+	cfg := &Config{StatsRollupInterval: time.Hour, StatsRetention: 90 * 24 * time.Hour}
+
+	assert.Equal(t,
+		&StatsConfig{RollupInterval: time.Hour, Retention: 90 * 24 * time.Hour},
+		ExtractStatsConfig(cfg),
+	)
+}
+
+func TestExtractPlanConfig(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, &PlanConfig{Default: "free"}, ExtractPlanConfig(&Config{PlanDefault: "free"}))
+}
+
+func TestExtractBillingConfig(t *testing.T) {
+	t.Parallel()
+
+	cfg := &Config{
+		StripeWebhookSecret:    "whsec_test",
+		StripePricePlans:       []string{" price_monthly = premium ", "price_yearly=premium", ""},
+		StripeWebhookTolerance: time.Minute,
+	}
+
+	assert.Equal(t, &BillingConfig{
+		StripePricePlans:       map[string]string{"price_monthly": "premium", "price_yearly": "premium"},
+		StripeWebhookSecret:    "whsec_test",
+		StripeWebhookTolerance: time.Minute,
+	}, ExtractBillingConfig(cfg))
+}
+
+func TestExtractLicenseConfig(t *testing.T) {
+	t.Parallel()
+
+	publicKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	tests := []struct {
+		config   *Config
+		expected *LicenseConfig
+		name     string
+	}{
+		{name: "licensing disabled", config: &Config{}, expected: &LicenseConfig{}},
+		{
+			name: "licensing enabled",
+			config: &Config{
+				LicensePublicKey:   base64.StdEncoding.EncodeToString(publicKey),
+				LicenseKey:         " AVK1.payload.signature\n",
+				LicenseGracePeriod: 72 * time.Hour,
+			},
+			expected: &LicenseConfig{
+				PublicKey:   publicKey,
+				Key:         "AVK1.payload.signature",
+				GracePeriod: 72 * time.Hour,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.expected, ExtractLicenseConfig(tt.config))
+		})
+	}
+}
+
+func TestExtractNoteMergeConfig(t *testing.T) {
+	t.Parallel()
+
+	cfg := &Config{NoteMergeCompactInterval: time.Hour, NoteMergeCompactThreshold: 50}
+
+	assert.Equal(t,
+		&NoteMergeConfig{CompactInterval: time.Hour, CompactThreshold: 50},
+		ExtractNoteMergeConfig(cfg),
+	)
+}
+
+func TestExtractLoginProtectionConfig(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		config   *Config
+		expected *LoginProtectionConfig
+		name     string
+	}{
+		{name: "anomaly detection disabled", config: &Config{}, expected: &LoginProtectionConfig{}},
+		{
+			name: "anomaly detection enabled",
+			config: &Config{
+				LoginAnomalyDetection: true,
+				LoginStepUpTTL:        10 * time.Minute,
+				LoginApprovalURL:      "https://vault.example.com",
+			},
+			expected: &LoginProtectionConfig{
+				AnomalyDetection: true,
+				StepUpTTL:        10 * time.Minute,
+				ApprovalURL:      "https://vault.example.com",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.expected, ExtractLoginProtectionConfig(tt.config))
+		})
+	}
+}
+
+func TestExtractBruteForceConfig(t *testing.T) {
+	t.Parallel()
+
+	cfg := &Config{
+		AuthFailureLogPath:    "/var/log/aegis-vault-keeper/auth.log",
+		BruteForceThreshold:   20,
+		BruteForceWindow:      10 * time.Minute,
+		BruteForceBanDuration: 30 * time.Minute,
+	}
+
+	assert.Equal(t,
+		&BruteForceConfig{
+			LogPath:     "/var/log/aegis-vault-keeper/auth.log",
+			Threshold:   20,
+			Window:      10 * time.Minute,
+			BanDuration: 30 * time.Minute,
+		},
+		ExtractBruteForceConfig(cfg),
+	)
+}
+
+func TestExtractChallengeConfig(t *testing.T) {
+	t.Parallel()
+
+	key := deriveKeySHA256(challengeKeyDomain + "master-key-for-tests")
+	cfg := &Config{
+		ChallengeProvider:      "pow",
+		ChallengeSiteKey:       "site",
+		ChallengeSecret:        "secret",
+		ChallengeKey:           key,
+		ChallengePoWDifficulty: 20,
+		ChallengeTTL:           5 * time.Minute,
+		ChallengeLoginFailures: 3,
+		ChallengeLoginWindow:   15 * time.Minute,
+	}
+
+	assert.Equal(t,
+		&ChallengeConfig{
+			Provider:      "pow",
+			SiteKey:       "site",
+			Secret:        "secret",
+			Key:           key,
+			Difficulty:    20,
+			TTL:           5 * time.Minute,
+			LoginFailures: 3,
+			LoginWindow:   15 * time.Minute,
+		},
+		ExtractChallengeConfig(cfg),
+	)
+}
+
+func TestExtractInviteConfig(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		config   *Config
+		expected *InviteConfig
+		name     string
+	}{
+		{
+			name:     "open",
+			config:   &Config{RegistrationMode: "open", InviteTTL: 168 * time.Hour},
+			expected: &InviteConfig{TTL: 168 * time.Hour},
+		},
+		{
+			name:     "invite only",
+			config:   &Config{RegistrationMode: "invite", InviteTTL: time.Hour, InviteUserQuota: 3},
+			expected: &InviteConfig{Required: true, TTL: time.Hour, UserQuota: 3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.expected, ExtractInviteConfig(tt.config))
+		})
+	}
+}
+
+func TestExtractPasswordPolicyConfig(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		config   *Config
+		expected *PasswordPolicyConfig
+		name     string
+	}{
+		{name: "defaults", config: &Config{}, expected: &PasswordPolicyConfig{}},
+		{
+			name: "strict policy",
+			config: &Config{
+				PasswordRequiredClasses: []string{" Upper ", "emoji", "digit", ""},
+				PasswordBannedFile:      "/etc/aegis/banned.txt",
+				PasswordMinLength:       12,
+				PasswordHistory:         5,
+				PasswordBanCommon:       true,
+			},
+			expected: &PasswordPolicyConfig{
+				RequiredClasses: []auth.PasswordClass{auth.PasswordClassUpper, auth.PasswordClassDigit},
+				BannedFile:      "/etc/aegis/banned.txt",
+				MinLength:       12,
+				History:         5,
+				BanCommon:       true,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.expected, ExtractPasswordPolicyConfig(tt.config))
+		})
+	}
+}
+
+func TestExtractOnboardingConfig(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		config   *Config
+		expected *OnboardingConfig
+		name     string
+	}{
+		{name: "defaults", config: &Config{}, expected: &OnboardingConfig{}},
+		{
+			name:     "custom steps",
+			config:   &Config{OnboardingSteps: []string{" storage ", "", "admin"}, MasterKeyLength: 48},
+			expected: &OnboardingConfig{Steps: []string{"storage", "admin"}, MasterKeyLength: 48},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.expected, ExtractOnboardingConfig(tt.config))
+		})
+	}
+}
+
+func TestExtractMaintenanceConfig(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		config   *Config
+		expected *MaintenanceConfig
+		name     string
+	}{
+		{name: "normal mode", config: &Config{}, expected: &MaintenanceConfig{}},
+		{name: "maintenance mode", config: &Config{MaintenanceMode: true}, expected: &MaintenanceConfig{Enabled: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.expected, ExtractMaintenanceConfig(tt.config))
+		})
+	}
+}
+
+func TestExtractFeatureConfig(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		config   *Config
+		expected *FeatureConfig
+		name     string
+	}{
+		{
+			name:     "no flags",
+			config:   &Config{},
+			expected: &FeatureConfig{Defaults: map[string]bool{}},
+		},
+		{
+			name:     "flags",
+			config:   &Config{FeatureFlags: []string{"sync_v2=true", " crypto_v2 = false", "", "broken"}},
+			expected: &FeatureConfig{Defaults: map[string]bool{"sync_v2": true, "crypto_v2": false}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.expected, ExtractFeatureConfig(tt.config))
+		})
+	}
+}
+
+func TestExtractChaosConfig(t *testing.T) {
+	t.Parallel()
+
+	rules := []string{"POST /api/items=error:0.5", "", "broken"}
+	faults := []string{"latency:1:10ms", "drop:0.5"}
+
+	tests := []struct {
+		config   *Config
+		expected *ChaosConfig
+		name     string
+	}{
+		{
+			name:     "disabled",
+			config:   &Config{ChaosRules: rules, ChaosRepositoryFaults: faults},
+			expected: &ChaosConfig{},
+		},
+		{
+			name:   "enabled",
+			config: &Config{ChaosEnabled: true, ChaosRules: rules, ChaosRepositoryFaults: faults},
+			expected: &ChaosConfig{
+				Rules: []chaos.Rule{{
+					Method:     http.MethodPost,
+					PathPrefix: "/api/items",
+					Fault:      chaos.Fault{Kind: chaos.KindError, Probability: 0.5, Status: http.StatusServiceUnavailable},
+				}},
+				RepositoryFaults: []chaos.Fault{{Kind: chaos.KindLatency, Probability: 1, Latency: 10 * time.Millisecond}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.expected, ExtractChaosConfig(tt.config))
+		})
+	}
+}
+
+func TestExtractLeaseConfig(t *testing.T) {
+	t.Parallel()
+
+	cfg := &Config{LeaseTTL: 10 * time.Minute}
+
+	assert.Equal(t, &LeaseConfig{TTL: 10 * time.Minute}, ExtractLeaseConfig(cfg))
+}
+
+func TestExtractPurgeConfig(t *testing.T) {
+	t.Parallel()
+
+	cfg := &Config{PurgeTokenTTL: 2 * time.Minute}
+
+	assert.Equal(t, &PurgeConfig{TokenTTL: 2 * time.Minute}, ExtractPurgeConfig(cfg))
+}
+
+func TestExtractTelemetryConfig(t *testing.T) {
+	t.Parallel()
+
+	cfg := &Config{
+		TelemetryEnabled:  true,
+		TelemetryEndpoint: "https://telemetry.example.com/v1/ping",
+		TelemetryInterval: 12 * time.Hour,
+	}
+
+	assert.Equal(t, &TelemetryConfig{
+		Endpoint: "https://telemetry.example.com/v1/ping",
+		Interval: 12 * time.Hour,
+		Enabled:  true,
+	}, ExtractTelemetryConfig(cfg))
+}
+
+func TestExtractUpdateCheckConfig(t *testing.T) {
+	t.Parallel()
+
+	publicKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	tests := []struct {
+		config   *Config
+		expected *UpdateCheckConfig
+		name     string
+	}{
+		{name: "update checks disabled", config: &Config{}, expected: &UpdateCheckConfig{}},
+		{
+			name: "update checks enabled",
+			config: &Config{
+				UpdateCheckURL:       "https://releases.example.com/latest",
+				UpdateCheckPublicKey: base64.StdEncoding.EncodeToString(publicKey),
+				UpdateCheckInterval:  6 * time.Hour,
+			},
+			expected: &UpdateCheckConfig{
+				PublicKey: publicKey,
+				URL:       "https://releases.example.com/latest",
+				Interval:  6 * time.Hour,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.expected, ExtractUpdateCheckConfig(tt.config))
+		})
+	}
+}
+
+func TestExtractRecordingConfig(t *testing.T) {
+	t.Parallel()
+
+	key := deriveKeySHA256(recordingKeyDomain + "master-key-for-tests")
+	cfg := &Config{
+		RecordingKey:         key,
+		RecordingMaxWindow:   time.Hour,
+		RecordingRetention:   7 * 24 * time.Hour,
+		RecordingMaxBodySize: 16384,
+	}
+
+	assert.Equal(t, &RecordingConfig{
+		Key:         key,
+		MaxWindow:   time.Hour,
+		Retention:   7 * 24 * time.Hour,
+		MaxBodySize: 16384,
+	}, ExtractRecordingConfig(cfg))
+}
+
+func TestExtractSessionConfig(t *testing.T) {
+	t.Parallel()
+
+	key := deriveKeySHA256(sessionKeyDomain + "master-key-for-tests")
+
+	tests := []struct {
+		name         string
+		sameSite     string
+		wantSameSite http.SameSite
+	}{
+		{name: "strict", sameSite: "strict", wantSameSite: http.SameSiteStrictMode},
+		{name: "lax", sameSite: "lax", wantSameSite: http.SameSiteLaxMode},
+		{name: "none", sameSite: "none", wantSameSite: http.SameSiteNoneMode},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := &Config{
+				SessionKey:            key,
+				SessionCookieName:     "avk_session",
+				SessionCSRFCookieName: "avk_csrf",
+				SessionCookieDomain:   "vault.example.com",
+				SessionCookieSameSite: tt.sameSite,
+				SessionCookieSecure:   true,
+				SessionCookiesEnabled: true,
+			}
+
+			assert.Equal(t, &SessionConfig{
+				Key:            key,
+				CookieName:     "avk_session",
+				CSRFCookieName: "avk_csrf",
+				Domain:         "vault.example.com",
+				SameSite:       tt.wantSameSite,
+				Secure:         true,
+				Enabled:        true,
+			}, ExtractSessionConfig(cfg))
+		})
+	}
+}
+
+func TestExtractAutofillConfig(t *testing.T) {
+	t.Parallel()
+
+	release := strings.TrimSuffix(strings.Repeat("AB:", 32), ":")
+	debug := strings.TrimSuffix(strings.Repeat("0F:", 32), ":")
+	cfg := &Config{
+		AutofillAndroidApps: []string{
+			" com.example.vault = " + release,
+			"com.example.vault=" + strings.ToLower(debug),
+			"com.example.vault=" + release,
+			"com.example.lite=" + release,
+			"",
+		},
+		AutofillIOSApps: []string{" ABCDE12345.com.example.vault ", ""},
+	}
+
+	assert.Equal(t, &AutofillConfig{
+		AndroidApps: []AutofillApp{
+			{PackageName: "com.example.vault", Fingerprints: []string{release, debug}},
+			{PackageName: "com.example.lite", Fingerprints: []string{release}},
+		},
+		IOSApps: []string{"ABCDE12345.com.example.vault"},
+	}, ExtractAutofillConfig(cfg))
+}
+
+func TestExtractRetentionConfig(t *testing.T) {
+	t.Parallel()
+
+	cfg := &Config{
+		RetentionInterval:      time.Hour,
+		RetentionKeepVersions:  10,
+		RetentionKeepAuditDays: 365,
+	}
+
+	assert.Equal(t, &RetentionConfig{
+		Interval:      time.Hour,
+		KeepVersions:  10,
+		KeepAuditDays: 365,
+	}, ExtractRetentionConfig(cfg))
+}
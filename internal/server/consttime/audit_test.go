@@ -0,0 +1,174 @@
+package consttime
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// auditedPackages lists the directories of packages handling passwords, tokens and login codes.
+var auditedPackages = []string{
+	"../application/auth",
+	"../crypto",
+	"../delivery/auth",
+	"../delivery/middleware",
+	"../domain/auth",
+	"../domain/device",
+	"../security",
+}
+
+// secretName matches identifiers that hold secrets or values derived from them.
+var secretName = regexp.MustCompile(`(?i)(password|secret|token|hash|digest|signature)`)
+
+// variableTimeFuncs lists comparisons returning as soon as the inputs differ.
+var variableTimeFuncs = map[string]bool{
+	"bytes.Equal":       true,
+	"bytes.Compare":     true,
+	"strings.Compare":   true,
+	"strings.EqualFold": true,
+	"reflect.DeepEqual": true,
+}
+
+func TestAudit_TimingSafeComparisons(t *testing.T) {
+	t.Parallel()
+
+	for _, dir := range auditedPackages {
+		t.Run(filepath.Base(dir), func(t *testing.T) {
+			t.Parallel()
+
+			files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+			require.NoError(t, err)
+			require.NotEmpty(t, files)
+
+			fset := token.NewFileSet()
+			for _, path := range files {
+				if strings.HasSuffix(path, "_test.go") {
+					continue
+				}
+				f, err := parser.ParseFile(fset, path, nil, 0)
+				require.NoError(t, err)
+				for _, finding := range auditFile(fset, f) {
+					assert.Fail(t, "variable-time comparison of a secret", finding)
+				}
+			}
+		})
+	}
+}
+
+func TestAuditFile(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		src   string
+		wants int
+	}{
+		{
+			name:  "constant-time helpers",
+			src:   `func f(a, b []byte) bool { return consttime.Equal(a, b) }`,
+			wants: 0,
+		},
+		{
+			name:  "subtle import",
+			src:   `import "crypto/subtle"`,
+			wants: 1,
+		},
+		{
+			name:  "bytes equal",
+			src:   `func f(a, b []byte) bool { return bytes.Equal(a, b) }`,
+			wants: 1,
+		},
+		{
+			name:  "secret compared with operator",
+			src:   `func f(u user, password string) bool { return u.PasswordHash != password }`,
+			wants: 1,
+		},
+		{
+			name:  "secret compared with literal",
+			src:   `func f(token string) bool { return token == "" }`,
+			wants: 0,
+		},
+		{
+			name:  "non-secret compared with operator",
+			src:   `func f(a, b int) bool { return a == b }`,
+			wants: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			fset := token.NewFileSet()
+			f, err := parser.ParseFile(fset, "audit.go", "package p\n"+tt.src, 0)
+			require.NoError(t, err)
+
+			assert.Len(t, auditFile(fset, f), tt.wants)
+		})
+	}
+}
+
+// auditFile returns the positions of variable-time comparisons of secrets in the file: imports of
+// crypto/subtle bypassing this package, early-exit comparison functions, and == or != between secrets.
+func auditFile(fset *token.FileSet, f *ast.File) []string {
+	var findings []string
+	for _, imp := range f.Imports {
+		if path, _ := strconv.Unquote(imp.Path.Value); path == "crypto/subtle" {
+			findings = append(findings, fset.Position(imp.Pos()).String()+": crypto/subtle instead of consttime")
+		}
+	}
+	ast.Inspect(f, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.CallExpr:
+			if sel, ok := n.Fun.(*ast.SelectorExpr); ok {
+				if pkg, ok := sel.X.(*ast.Ident); ok && variableTimeFuncs[pkg.Name+"."+sel.Sel.Name] {
+					findings = append(findings, fset.Position(n.Pos()).String()+": "+pkg.Name+"."+sel.Sel.Name)
+				}
+			}
+		case *ast.BinaryExpr:
+			if n.Op != token.EQL && n.Op != token.NEQ {
+				return true
+			}
+			if isConstant(n.X) || isConstant(n.Y) {
+				return true
+			}
+			if secretName.MatchString(operandName(n.X)) || secretName.MatchString(operandName(n.Y)) {
+				findings = append(findings, fset.Position(n.Pos()).String()+": "+n.Op.String()+" on a secret")
+			}
+		}
+		return true
+	})
+	return findings
+}
+
+// isConstant reports whether the expression is a literal or nil, comparisons with which leak no secret.
+func isConstant(e ast.Expr) bool {
+	switch e := e.(type) {
+	case *ast.BasicLit:
+		return true
+	case *ast.Ident:
+		return e.Name == "nil"
+	default:
+		return false
+	}
+}
+
+// operandName returns the name of an identifier or the selected field of a selector expression.
+func operandName(e ast.Expr) string {
+	switch e := e.(type) {
+	case *ast.Ident:
+		return e.Name
+	case *ast.SelectorExpr:
+		return e.Sel.Name
+	default:
+		return ""
+	}
+}
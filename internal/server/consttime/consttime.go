@@ -0,0 +1,30 @@
+package consttime
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+)
+
+// Equal reports whether a and b are equal in time independent of their contents.
+// The lengths may leak, so the inputs should be digests or other values of a public length.
+func Equal(a, b []byte) bool {
+	return subtle.ConstantTimeCompare(a, b) == 1
+}
+
+// EqualString reports whether a and b are equal in time independent of their contents.
+// The lengths may leak, so the inputs should be values of a public length.
+func EqualString(a, b string) bool {
+	return Equal([]byte(a), []byte(b))
+}
+
+// EqualHashed reports whether a and b are equal, comparing their SHA-256 digests in constant time,
+// so that neither the contents nor the lengths of the inputs leak.
+func EqualHashed(a, b string) bool {
+	da, db := Digest(a), Digest(b)
+	return Equal(da[:], db[:])
+}
+
+// Digest returns the SHA-256 digest of a secret, suitable for comparison with Equal.
+func Digest(secret string) [sha256.Size]byte {
+	return sha256.Sum256([]byte(secret))
+}
@@ -0,0 +1,42 @@
+package consttime
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEqual(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		a    []byte
+		b    []byte
+		want bool
+	}{
+		{name: "equal", a: []byte("secret"), b: []byte("secret"), want: true},
+		{name: "different contents", a: []byte("secret"), b: []byte("secreT"), want: false},
+		{name: "different lengths", a: []byte("secret"), b: []byte("secrets"), want: false},
+		{name: "both empty", a: nil, b: []byte{}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, Equal(tt.a, tt.b))
+			assert.Equal(t, tt.want, EqualString(string(tt.a), string(tt.b)))
+			assert.Equal(t, tt.want, EqualHashed(string(tt.a), string(tt.b)))
+		})
+	}
+}
+
+func TestDigest(t *testing.T) {
+	t.Parallel()
+
+	a, b := Digest("secret"), Digest("secret")
+
+	assert.Equal(t, a, b)
+	assert.NotEqual(t, a, Digest("other"))
+}
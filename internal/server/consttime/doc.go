@@ -0,0 +1,7 @@
+// Package consttime provides timing-safe comparisons of secrets for the AegisVaultKeeper server.
+//
+// Comparing secrets with == or bytes.Equal returns as soon as the first byte differs, which lets an attacker
+// recover a secret byte by byte from response times. The helpers of this package take the same time for all
+// inputs of a given length, and EqualHashed for all inputs. Authentication and security code compares
+// passwords, tokens, codes and digests only through this package, which an audit test enforces.
+package consttime
@@ -0,0 +1,128 @@
+package crypto
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/sys/cpu"
+)
+
+// Cipher identifies an authenticated encryption algorithm for stored data.
+type Cipher string
+
+// Supported ciphers.
+const (
+	// CipherAESGCM is AES-GCM, fastest on CPUs with AES and carry-less multiplication instructions.
+	CipherAESGCM Cipher = "aes-gcm"
+	// CipherChaCha20Poly1305 is ChaCha20-Poly1305, faster than software AES on CPUs without AES-NI.
+	CipherChaCha20Poly1305 Cipher = "chacha20-poly1305"
+)
+
+// chachaPrefix marks ciphertexts sealed with ChaCha20-Poly1305. AES-GCM ciphertexts keep the unprefixed
+// layout of EncryptAESGCMWithAAD, so data stored before the cipher selection remains readable.
+var chachaPrefix = []byte{0x00, 'c', 'c', '2', '0'}
+
+// defaultCipher is the cipher used by Seal, selected once for the CPU the server runs on.
+var defaultCipher = PreferredCipher()
+
+// HasAESHardware reports whether the CPU provides instructions accelerating AES-GCM.
+func HasAESHardware() bool {
+	switch runtime.GOARCH {
+	case "amd64", "386":
+		return cpu.X86.HasAES && cpu.X86.HasPCLMULQDQ
+	case "arm64":
+		return cpu.ARM64.HasAES && cpu.ARM64.HasPMULL
+	case "s390x":
+		return cpu.S390X.HasAES && cpu.S390X.HasAESGCM
+	case "ppc64", "ppc64le":
+		return true
+	default:
+		return false
+	}
+}
+
+// PreferredCipher returns AES-GCM when the CPU accelerates it and ChaCha20-Poly1305 otherwise.
+func PreferredCipher() Cipher {
+	if HasAESHardware() {
+		return CipherAESGCM
+	}
+	return CipherChaCha20Poly1305
+}
+
+// DefaultCipher returns the cipher Seal uses on this server.
+func DefaultCipher() Cipher {
+	return defaultCipher
+}
+
+// Seal encrypts plaintext with the cipher preferred for this CPU, authenticating the additional data
+// alongside it. Keys other than 256 bits are always used with AES-GCM.
+func Seal(key, plaintext, aad []byte) ([]byte, error) {
+	c := defaultCipher
+	if len(key) != chacha20poly1305.KeySize {
+		c = CipherAESGCM
+	}
+	return SealWith(c, key, plaintext, aad)
+}
+
+// SealWith encrypts plaintext with the specified cipher, authenticating the additional data alongside it.
+func SealWith(c Cipher, key, plaintext, aad []byte) ([]byte, error) {
+	switch c {
+	case CipherAESGCM:
+		return EncryptAESGCMWithAAD(key, plaintext, aad)
+	case CipherChaCha20Poly1305:
+		aead, err := chacha20poly1305.New(key)
+		if err != nil {
+			return nil, fmt.Errorf("chacha20poly1305.New: %w", err)
+		}
+		return sealPrefixed(aead, chachaPrefix, plaintext, aad)
+	default:
+		return nil, fmt.Errorf("unsupported cipher %q", c)
+	}
+}
+
+// Open decrypts data produced by Seal, SealWith or EncryptAESGCMWithAAD, detecting the cipher used.
+// The same additional data must be supplied, otherwise ErrIntegrityCheckFailed is returned.
+func Open(key, data, aad []byte) ([]byte, error) {
+	if bytes.HasPrefix(data, chachaPrefix) && len(key) == chacha20poly1305.KeySize {
+		aead, err := chacha20poly1305.New(key)
+		if err != nil {
+			return nil, fmt.Errorf("chacha20poly1305.New: %w", err)
+		}
+		// A random AES-GCM nonce may start with the prefix too; authentication tells them apart.
+		if plaintext, err := openPrefixed(aead, chachaPrefix, data, aad); err == nil {
+			return plaintext, nil
+		}
+	}
+	return DecryptAESGCMWithAAD(key, data, aad)
+}
+
+// sealPrefixed encrypts plaintext with a random nonce and returns prefix, nonce and ciphertext.
+func sealPrefixed(aead cipher.AEAD, prefix, plaintext, aad []byte) ([]byte, error) {
+	out := make([]byte, len(prefix)+aead.NonceSize(), len(prefix)+aead.NonceSize()+len(plaintext)+aead.Overhead())
+	copy(out, prefix)
+	nonce := out[len(prefix):]
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("read nonce: %w", err)
+	}
+	return aead.Seal(out, nonce, plaintext, aad), nil
+}
+
+// openPrefixed decrypts data produced by sealPrefixed.
+func openPrefixed(aead cipher.AEAD, prefix, data, aad []byte) ([]byte, error) {
+	data = data[len(prefix):]
+	if len(data) < aead.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+	nonce, ciphertext := data[:aead.NonceSize()], data[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, aad)
+	if err != nil {
+		return nil, fmt.Errorf("aead.Open: %w", errors.Join(ErrIntegrityCheckFailed, err))
+	}
+	return plaintext, nil
+}
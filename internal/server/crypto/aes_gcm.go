@@ -4,22 +4,35 @@ import (
 	"crypto/aes"
 	"crypto/cipher"
 	"crypto/rand"
+	"encoding/binary"
 	"errors"
 	"fmt"
 	"io"
 )
 
+// ErrIntegrityCheckFailed indicates that ciphertext authentication failed, either because the data
+// was tampered with or because it was bound to different additional authenticated data.
+var ErrIntegrityCheckFailed = errors.New("ciphertext integrity check failed")
+
 // EncryptAESGCM encrypts plaintext using AES-GCM with a random nonce.
 // Returns the nonce prepended to the ciphertext for decryption.
 func EncryptAESGCM(key, plaintext []byte) ([]byte, error) {
-	block, err := aes.NewCipher(key)
-	if err != nil {
-		return nil, fmt.Errorf("aes.NewCipher: %w", err)
-	}
+	return EncryptAESGCMWithAAD(key, plaintext, nil)
+}
 
-	aesgcm, err := cipher.NewGCM(block)
+// DecryptAESGCM decrypts data encrypted with EncryptAESGCM.
+// Expects the nonce to be prepended to the ciphertext.
+func DecryptAESGCM(key, data []byte) ([]byte, error) {
+	return DecryptAESGCMWithAAD(key, data, nil)
+}
+
+// EncryptAESGCMWithAAD encrypts plaintext using AES-GCM with a random nonce,
+// authenticating the additional data alongside the ciphertext.
+// Returns the nonce prepended to the ciphertext for decryption.
+func EncryptAESGCMWithAAD(key, plaintext, aad []byte) ([]byte, error) {
+	aesgcm, err := newGCM(key)
 	if err != nil {
-		return nil, fmt.Errorf("cipher.NewGCM: %w", err)
+		return nil, err
 	}
 
 	nonce := make([]byte, aesgcm.NonceSize())
@@ -27,7 +40,7 @@ func EncryptAESGCM(key, plaintext []byte) ([]byte, error) {
 		return nil, fmt.Errorf("read nonce: %w", err)
 	}
 
-	ciphertext := aesgcm.Seal(nil, nonce, plaintext, nil)
+	ciphertext := aesgcm.Seal(nil, nonce, plaintext, aad)
 
 	// Avoid appending to non-zero length slice
 	result := make([]byte, 0, len(nonce)+len(ciphertext))
@@ -36,17 +49,12 @@ func EncryptAESGCM(key, plaintext []byte) ([]byte, error) {
 	return result, nil
 }
 
-// DecryptAESGCM decrypts data encrypted with EncryptAESGCM.
-// Expects the nonce to be prepended to the ciphertext.
-func DecryptAESGCM(key, data []byte) ([]byte, error) {
-	block, err := aes.NewCipher(key)
-	if err != nil {
-		return nil, fmt.Errorf("aes.NewCipher: %w", err)
-	}
-
-	aesgcm, err := cipher.NewGCM(block)
+// DecryptAESGCMWithAAD decrypts data encrypted with EncryptAESGCMWithAAD.
+// The same additional data must be supplied, otherwise ErrIntegrityCheckFailed is returned.
+func DecryptAESGCMWithAAD(key, data, aad []byte) ([]byte, error) {
+	aesgcm, err := newGCM(key)
 	if err != nil {
-		return nil, fmt.Errorf("cipher.NewGCM: %w", err)
+		return nil, err
 	}
 
 	nonceSize := aesgcm.NonceSize()
@@ -55,9 +63,39 @@ func DecryptAESGCM(key, data []byte) ([]byte, error) {
 	}
 
 	nonce, ciphertext := data[:nonceSize], data[nonceSize:]
-	plaintext, err := aesgcm.Open(nil, nonce, ciphertext, nil)
+	plaintext, err := aesgcm.Open(nil, nonce, ciphertext, aad)
 	if err != nil {
-		return nil, fmt.Errorf("aesgcm.Open: %w", err)
+		return nil, fmt.Errorf("aesgcm.Open: %w", errors.Join(ErrIntegrityCheckFailed, err))
 	}
 	return plaintext, nil
 }
+
+// BuildAAD encodes the given parts into unambiguous additional authenticated data.
+// Each part is prefixed with its length so that different part boundaries never collide.
+func BuildAAD(parts ...string) []byte {
+	size := 0
+	for _, p := range parts {
+		size += binary.MaxVarintLen64 + len(p)
+	}
+
+	aad := make([]byte, 0, size)
+	for _, p := range parts {
+		aad = binary.AppendUvarint(aad, uint64(len(p)))
+		aad = append(aad, p...)
+	}
+	return aad
+}
+
+// newGCM creates an AES-GCM cipher for the given key.
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("aes.NewCipher: %w", err)
+	}
+
+	aesgcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("cipher.NewGCM: %w", err)
+	}
+	return aesgcm, nil
+}
@@ -2,9 +2,14 @@ package crypto
 
 import (
 	"bytes"
+	"context"
+	"crypto/ecdh"
+	"crypto/ed25519"
 	"crypto/rand"
+	"encoding/base64"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -323,6 +328,107 @@ func TestAESGCM_NonceUniqueness(t *testing.T) {
 	}
 }
 
+func TestAESGCMWithAAD(t *testing.T) {
+	t.Parallel()
+
+	key := make([]byte, 32)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+
+	plaintext := []byte("secret value")
+	aad := BuildAAD("user-1", "note", "item-1", "note")
+
+	ciphertext, err := EncryptAESGCMWithAAD(key, plaintext, aad)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		aad         []byte
+		expectError bool
+	}{
+		{
+			name:        "matching aad",
+			aad:         BuildAAD("user-1", "note", "item-1", "note"),
+			expectError: false,
+		},
+		{
+			name:        "different item id",
+			aad:         BuildAAD("user-1", "note", "item-2", "note"),
+			expectError: true,
+		},
+		{
+			name:        "different item type",
+			aad:         BuildAAD("user-1", "credential", "item-1", "note"),
+			expectError: true,
+		},
+		{
+			name:        "different field",
+			aad:         BuildAAD("user-1", "note", "item-1", "description"),
+			expectError: true,
+		},
+		{
+			name:        "missing aad",
+			aad:         nil,
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			decrypted, err := DecryptAESGCMWithAAD(key, ciphertext, tt.aad)
+			if tt.expectError {
+				require.ErrorIs(t, err, ErrIntegrityCheckFailed)
+				assert.Nil(t, decrypted)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, plaintext, decrypted)
+		})
+	}
+}
+
+func TestBuildAAD(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, BuildAAD("a", "b"), BuildAAD("a", "b"))
+	assert.NotEqual(t, BuildAAD("ab", "c"), BuildAAD("a", "bc"))
+	assert.NotEqual(t, BuildAAD("a", "b"), BuildAAD("b", "a"))
+	assert.Empty(t, BuildAAD())
+}
+
+func TestHMACSHA256(t *testing.T) {
+	t.Parallel()
+
+	key := []byte("integrity-key")
+	data := []byte("row data")
+	tag := SignHMACSHA256(key, data)
+
+	tests := []struct {
+		name  string
+		key   []byte
+		data  []byte
+		tag   []byte
+		valid bool
+	}{
+		{name: "valid tag", key: key, data: data, tag: tag, valid: true},
+		{name: "modified data", key: key, data: []byte("row datA"), tag: tag, valid: false},
+		{name: "different key", key: []byte("other-key"), data: data, tag: tag, valid: false},
+		{name: "truncated tag", key: key, data: data, tag: tag[:16], valid: false},
+		{name: "empty tag", key: key, data: data, tag: nil, valid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.valid, VerifyHMACSHA256(tt.key, tt.data, tt.tag))
+		})
+	}
+
+	assert.Len(t, tag, 32)
+}
+
 func TestHashBcrypt(t *testing.T) {
 	t.Parallel()
 
@@ -560,3 +666,267 @@ func TestConstants(t *testing.T) {
 	// Test that the constant matches bcrypt's actual limit
 	assert.Equal(t, 72, MaxBcryptInputLength, "MaxBcryptInputLength should match bcrypt's limit")
 }
+
+func TestSealOpen(t *testing.T) {
+	t.Parallel()
+
+	key := make([]byte, 32)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+	aad := BuildAAD("note", "description")
+
+	tests := []struct {
+		name   string
+		cipher Cipher
+	}{
+		{name: "aes-gcm", cipher: CipherAESGCM},
+		{name: "chacha20-poly1305", cipher: CipherChaCha20Poly1305},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			sealed, err := SealWith(tt.cipher, key, []byte("secret"), aad)
+			require.NoError(t, err)
+			assert.Equal(t, tt.cipher == CipherChaCha20Poly1305, bytes.HasPrefix(sealed, chachaPrefix))
+
+			opened, err := Open(key, sealed, aad)
+			require.NoError(t, err)
+			assert.Equal(t, []byte("secret"), opened)
+
+			_, err = Open(key, sealed, BuildAAD("note", "other"))
+			require.ErrorIs(t, err, ErrIntegrityCheckFailed)
+
+			tampered := bytes.Clone(sealed)
+			tampered[len(tampered)-1] ^= 0xff
+			_, err = Open(key, tampered, aad)
+			require.ErrorIs(t, err, ErrIntegrityCheckFailed)
+		})
+	}
+}
+
+func TestOpen_LegacyAESGCM(t *testing.T) {
+	t.Parallel()
+
+	key := []byte("testtesttesttesttesttesttesttest")
+	sealed, err := EncryptAESGCMWithAAD(key, []byte("stored before cipher selection"), nil)
+	require.NoError(t, err)
+
+	// A legacy nonce starting with the ChaCha20-Poly1305 prefix must still open with AES-GCM.
+	prefixed, err := EncryptAESGCMWithAAD(key, []byte("prefixed nonce"), nil)
+	require.NoError(t, err)
+	aead, err := newGCM(key)
+	require.NoError(t, err)
+	nonce := append(bytes.Clone(chachaPrefix), prefixed[len(chachaPrefix):aead.NonceSize()]...)
+	prefixed = append(nonce, aead.Seal(nil, nonce, []byte("prefixed nonce"), nil)...)
+
+	opened, err := Open(key, sealed, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("stored before cipher selection"), opened)
+
+	opened, err = Open(key, prefixed, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("prefixed nonce"), opened)
+}
+
+func TestSeal(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		keySize    int
+		wantCipher Cipher
+	}{
+		{name: "256-bit key uses preferred cipher", keySize: 32, wantCipher: PreferredCipher()},
+		{name: "128-bit key uses aes-gcm", keySize: 16, wantCipher: CipherAESGCM},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			key := make([]byte, tt.keySize)
+			sealed, err := Seal(key, []byte("data"), nil)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantCipher == CipherChaCha20Poly1305, bytes.HasPrefix(sealed, chachaPrefix))
+
+			opened, err := Open(key, sealed, nil)
+			require.NoError(t, err)
+			assert.Equal(t, []byte("data"), opened)
+		})
+	}
+}
+
+func TestPreferredCipher(t *testing.T) {
+	t.Parallel()
+
+	want := CipherChaCha20Poly1305
+	if HasAESHardware() {
+		want = CipherAESGCM
+	}
+	assert.Equal(t, want, PreferredCipher())
+	assert.Equal(t, want, DefaultCipher())
+
+	_, err := SealWith(Cipher("des"), make([]byte, 32), nil, nil)
+	require.Error(t, err)
+}
+
+func TestMeasureThroughput(t *testing.T) {
+	t.Parallel()
+
+	for _, c := range []Cipher{CipherAESGCM, CipherChaCha20Poly1305} {
+		got, err := MeasureThroughput(context.Background(), c, 4096, time.Millisecond)
+		require.NoError(t, err)
+		assert.Equal(t, c, got.Cipher)
+		assert.Positive(t, got.EncryptBytesPerSec)
+		assert.Positive(t, got.DecryptBytesPerSec)
+	}
+
+	_, err := MeasureThroughput(context.Background(), CipherAESGCM, 0, time.Millisecond)
+	require.Error(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = MeasureThroughput(ctx, CipherAESGCM, 4096, time.Second)
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestSealOpenForX25519(t *testing.T) {
+	t.Parallel()
+
+	key, err := ecdh.X25519().GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	other, err := ecdh.X25519().GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	aad := []byte("device-key-id")
+
+	sealed, err := SealForX25519(key.PublicKey().Bytes(), []byte("payload"), aad)
+	require.NoError(t, err)
+	assert.Len(t, sealed.EphemeralKey, X25519KeySize)
+	assert.NotContains(t, string(sealed.Ciphertext), "payload")
+
+	opened, err := OpenForX25519(key, sealed, aad)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("payload"), opened)
+
+	again, err := SealForX25519(key.PublicKey().Bytes(), []byte("payload"), aad)
+	require.NoError(t, err)
+	assert.NotEqual(t, sealed.EphemeralKey, again.EphemeralKey)
+	assert.NotEqual(t, sealed.WrappedKey, again.WrappedKey)
+
+	_, err = OpenForX25519(other, sealed, aad)
+	require.ErrorIs(t, err, ErrIntegrityCheckFailed)
+	_, err = OpenForX25519(key, sealed, []byte("other-key-id"))
+	require.ErrorIs(t, err, ErrIntegrityCheckFailed)
+
+	_, err = SealForX25519([]byte("short"), []byte("payload"), aad)
+	require.ErrorIs(t, err, ErrIncorrectX25519Key)
+}
+
+func BenchmarkSealWith(b *testing.B) {
+	key := make([]byte, 32)
+	payload := make([]byte, 64<<10)
+
+	for _, c := range []Cipher{CipherAESGCM, CipherChaCha20Poly1305} {
+		b.Run(string(c), func(b *testing.B) {
+			b.SetBytes(int64(len(payload)))
+			for range b.N {
+				if _, err := SealWith(c, key, payload, nil); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// benchmarkSizes lists the payload sizes of the cipher benchmarks: field values, notes and file chunks.
+var benchmarkSizes = []struct {
+	name string
+	size int
+}{
+	{name: "64B", size: 64},
+	{name: "1KiB", size: 1 << 10},
+	{name: "64KiB", size: 64 << 10},
+	{name: "1MiB", size: 1 << 20},
+}
+
+func BenchmarkEncryptAESGCM(b *testing.B) {
+	key := make([]byte, 32)
+	aad := BuildAAD("note", "user", "item", "note")
+
+	for _, s := range benchmarkSizes {
+		b.Run(s.name, func(b *testing.B) {
+			payload := make([]byte, s.size)
+			b.SetBytes(int64(s.size))
+			for range b.N {
+				if _, err := EncryptAESGCMWithAAD(key, payload, aad); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkDecryptAESGCM(b *testing.B) {
+	key := make([]byte, 32)
+	aad := BuildAAD("note", "user", "item", "note")
+
+	for _, s := range benchmarkSizes {
+		b.Run(s.name, func(b *testing.B) {
+			sealed, err := EncryptAESGCMWithAAD(key, make([]byte, s.size), aad)
+			if err != nil {
+				b.Fatal(err)
+			}
+			b.SetBytes(int64(s.size))
+			b.ResetTimer()
+			for range b.N {
+				if _, err := DecryptAESGCMWithAAD(key, sealed, aad); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestParseEd25519Seed(t *testing.T) {
+	t.Parallel()
+
+	seed := bytes.Repeat([]byte{0x07}, ed25519.SeedSize)
+
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{name: "valid seed", input: base64.StdEncoding.EncodeToString(seed)},
+		{name: "surrounding whitespace", input: " " + base64.StdEncoding.EncodeToString(seed) + "\n"},
+		{name: "invalid base64", input: "not base64!", wantErr: true},
+		{name: "wrong length", input: base64.StdEncoding.EncodeToString(seed[:16]), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			key, err := ParseEd25519Seed(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, ed25519.NewKeyFromSeed(seed), key)
+		})
+	}
+}
+
+func TestEd25519KeyID(t *testing.T) {
+	t.Parallel()
+
+	a := ed25519.NewKeyFromSeed(bytes.Repeat([]byte{0x01}, ed25519.SeedSize)).Public().(ed25519.PublicKey)
+	b := ed25519.NewKeyFromSeed(bytes.Repeat([]byte{0x02}, ed25519.SeedSize)).Public().(ed25519.PublicKey)
+
+	assert.Len(t, Ed25519KeyID(a), 2*ed25519KeyIDSize)
+	assert.Equal(t, Ed25519KeyID(a), Ed25519KeyID(a))
+	assert.NotEqual(t, Ed25519KeyID(a), Ed25519KeyID(b))
+}
@@ -1,5 +1,6 @@
 // Package crypto provides encryption and cryptographic services for the AegisVaultKeeper server.
 //
-// This package implements core cryptographic operations including AES-GCM encryption,
-// key derivation, and secure random number generation.
+// This package implements core cryptographic operations including AES-GCM encryption, HMAC signing,
+// key derivation, and secure random number generation. Stored data is sealed with AES-GCM on CPUs
+// with hardware AES support and with ChaCha20-Poly1305 otherwise.
 package crypto
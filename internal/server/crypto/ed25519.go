@@ -0,0 +1,32 @@
+package crypto
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// ed25519KeyIDSize is the number of SHA-256 digest bytes identifying an Ed25519 public key.
+const ed25519KeyIDSize = 8
+
+// ParseEd25519Seed parses a base64-encoded 32-byte Ed25519 seed into the private key derived from it.
+func ParseEd25519Seed(s string) (ed25519.PrivateKey, error) {
+	seed, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil {
+		return nil, fmt.Errorf("invalid base64 encoding: %w", err)
+	}
+	if len(seed) != ed25519.SeedSize {
+		return nil, fmt.Errorf("expected %d bytes, got %d", ed25519.SeedSize, len(seed))
+	}
+	return ed25519.NewKeyFromSeed(seed), nil
+}
+
+// Ed25519KeyID returns the hex-encoded beginning of the SHA-256 digest of the public key, so verifiers can tell
+// which key signed a message when keys are rotated.
+func Ed25519KeyID(key ed25519.PublicKey) string {
+	digest := sha256.Sum256(key)
+	return hex.EncodeToString(digest[:ed25519KeyIDSize])
+}
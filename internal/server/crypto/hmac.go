@@ -0,0 +1,19 @@
+package crypto
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+)
+
+// SignHMACSHA256 computes an HMAC-SHA256 tag of data under the key.
+func SignHMACSHA256(key, data []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(data)
+	return mac.Sum(nil)
+}
+
+// VerifyHMACSHA256 reports whether tag is a valid HMAC-SHA256 of data under the key.
+// The comparison is performed in constant time.
+func VerifyHMACSHA256(key, data, tag []byte) bool {
+	return hmac.Equal(SignHMACSHA256(key, data), tag)
+}
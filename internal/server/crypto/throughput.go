@@ -0,0 +1,70 @@
+package crypto
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Throughput describes the measured speed of a cipher in bytes per second.
+type Throughput struct {
+	// Cipher identifies the measured cipher.
+	Cipher Cipher
+	// EncryptBytesPerSec contains the measured encryption speed.
+	EncryptBytesPerSec float64
+	// DecryptBytesPerSec contains the measured decryption speed.
+	DecryptBytesPerSec float64
+}
+
+// MeasureThroughput encrypts and then decrypts payloads of payloadSize bytes with the cipher for roughly
+// the duration each and reports the achieved speed. It stops early once ctx is done.
+func MeasureThroughput(ctx context.Context, c Cipher, payloadSize int, duration time.Duration) (Throughput, error) {
+	if payloadSize <= 0 || duration <= 0 {
+		return Throughput{}, errors.New("payload size and duration must be positive")
+	}
+
+	key := make([]byte, 32)
+	payload := make([]byte, payloadSize)
+	if _, err := rand.Read(key); err != nil {
+		return Throughput{}, fmt.Errorf("failed to generate key: %w", err)
+	}
+	aad := BuildAAD("throughput", string(c))
+
+	// sealed holds the ciphertext of the last encryption, decrypted in the second phase.
+	var sealed []byte
+	encrypt, err := measure(ctx, payloadSize, duration, func() error {
+		var err error
+		sealed, err = SealWith(c, key, payload, aad)
+		return err
+	})
+	if err != nil {
+		return Throughput{}, fmt.Errorf("failed to measure %s encryption: %w", c, err)
+	}
+	decrypt, err := measure(ctx, payloadSize, duration, func() error {
+		_, err := Open(key, sealed, aad)
+		return err
+	})
+	if err != nil {
+		return Throughput{}, fmt.Errorf("failed to measure %s decryption: %w", c, err)
+	}
+	return Throughput{Cipher: c, EncryptBytesPerSec: encrypt, DecryptBytesPerSec: decrypt}, nil
+}
+
+// measure runs op repeatedly for roughly the duration, at least once, and returns the bytes per second
+// achieved when every run processes size bytes.
+func measure(ctx context.Context, size int, duration time.Duration, op func() error) (float64, error) {
+	start := time.Now()
+	runs := 0
+	for runs == 0 || time.Since(start) < duration {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+		if err := op(); err != nil {
+			return 0, err
+		}
+		runs++
+	}
+	return float64(runs*size) / time.Since(start).Seconds(), nil
+}
@@ -0,0 +1,111 @@
+package crypto
+
+import (
+	"crypto/ecdh"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// X25519KeySize is the size in bytes of X25519 public keys.
+const X25519KeySize = 32
+
+// sessionKeySize is the size in bytes of the AES-256 keys payloads sealed for a recipient are encrypted with.
+const sessionKeySize = 32
+
+// wrapKeyInfo binds the key wrapping keys derived from X25519 shared secrets to this scheme.
+const wrapKeyInfo = "aegis-vault-keeper device key wrap v1"
+
+// ErrIncorrectX25519Key indicates that a public key is not a usable X25519 key.
+var ErrIncorrectX25519Key = errors.New("incorrect X25519 public key")
+
+// RecipientEnvelope holds a payload sealed for the holder of an X25519 private key.
+type RecipientEnvelope struct {
+	// EphemeralKey contains the X25519 public key the key wrapping key was agreed with.
+	EphemeralKey []byte
+	// WrappedKey contains the session key encrypted with the key wrapping key, the nonce prepended.
+	WrappedKey []byte
+	// Ciphertext contains the payload encrypted with the session key, the nonce prepended.
+	Ciphertext []byte
+}
+
+// SealForX25519 encrypts plaintext for the holder of the private key of the X25519 public key. The plaintext is
+// encrypted with a random AES-256-GCM session key, which is wrapped with AES-256-GCM under a key derived with
+// HKDF-SHA256 from the secret agreed with a fresh ephemeral key, salted with the ephemeral and recipient public
+// keys. The additional data is authenticated by both encryptions.
+func SealForX25519(recipient, plaintext, aad []byte) (*RecipientEnvelope, error) {
+	pub, err := ecdh.X25519().NewPublicKey(recipient)
+	if err != nil {
+		return nil, ErrIncorrectX25519Key
+	}
+	ephemeral, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate ephemeral key: %w", err)
+	}
+	shared, err := ephemeral.ECDH(pub)
+	if err != nil {
+		return nil, ErrIncorrectX25519Key
+	}
+	wrapKey, err := deriveWrapKey(shared, ephemeral.PublicKey().Bytes(), recipient)
+	if err != nil {
+		return nil, err
+	}
+
+	sessionKey := make([]byte, sessionKeySize)
+	if _, err := io.ReadFull(rand.Reader, sessionKey); err != nil {
+		return nil, fmt.Errorf("failed to generate session key: %w", err)
+	}
+	ciphertext, err := EncryptAESGCMWithAAD(sessionKey, plaintext, aad)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt payload: %w", err)
+	}
+	wrapped, err := EncryptAESGCMWithAAD(wrapKey, sessionKey, aad)
+	if err != nil {
+		return nil, fmt.Errorf("failed to wrap session key: %w", err)
+	}
+	return &RecipientEnvelope{
+		EphemeralKey: ephemeral.PublicKey().Bytes(),
+		WrappedKey:   wrapped,
+		Ciphertext:   ciphertext,
+	}, nil
+}
+
+// OpenForX25519 decrypts an envelope sealed by SealForX25519 with the private key of the recipient.
+// The same additional data must be supplied, otherwise ErrIntegrityCheckFailed is returned.
+func OpenForX25519(key *ecdh.PrivateKey, e *RecipientEnvelope, aad []byte) ([]byte, error) {
+	ephemeral, err := ecdh.X25519().NewPublicKey(e.EphemeralKey)
+	if err != nil {
+		return nil, ErrIncorrectX25519Key
+	}
+	shared, err := key.ECDH(ephemeral)
+	if err != nil {
+		return nil, ErrIncorrectX25519Key
+	}
+	wrapKey, err := deriveWrapKey(shared, e.EphemeralKey, key.PublicKey().Bytes())
+	if err != nil {
+		return nil, err
+	}
+	sessionKey, err := DecryptAESGCMWithAAD(wrapKey, e.WrappedKey, aad)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap session key: %w", err)
+	}
+	plaintext, err := DecryptAESGCMWithAAD(sessionKey, e.Ciphertext, aad)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt payload: %w", err)
+	}
+	return plaintext, nil
+}
+
+// deriveWrapKey derives the key wrapping key from the agreed secret, salted with both public keys.
+func deriveWrapKey(shared, ephemeral, recipient []byte) ([]byte, error) {
+	salt := make([]byte, 0, len(ephemeral)+len(recipient))
+	salt = append(append(salt, ephemeral...), recipient...)
+	key, err := hkdf.Key(sha256.New, shared, salt, wrapKeyInfo, sessionKeySize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive key wrapping key: %w", err)
+	}
+	return key, nil
+}
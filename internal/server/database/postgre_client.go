@@ -25,6 +25,9 @@ type Config struct {
 	Port int
 	// Timeout specifies the maximum duration for connection attempts and pings.
 	Timeout time.Duration
+	// RLSEnabled determines whether repository operations are bound to a row-level security user scope.
+	// Without it every connection runs in the admin scope.
+	RLSEnabled bool
 }
 
 // Client provides a PostgreSQL database client with connection management and query execution.
@@ -33,17 +36,14 @@ type Client struct {
 	db *sql.DB
 	// pingTimeout specifies the timeout duration for health check operations.
 	pingTimeout time.Duration
+	// rlsEnabled determines whether RunInUserScope and RunInAdminScope set the row-level security scope.
+	rlsEnabled bool
 }
 
 // NewClient creates a new PostgreSQL client with the provided configuration.
 // It establishes a connection and verifies connectivity with a ping operation.
 func NewClient(cfg *Config) (*Client, error) {
-	dsn := fmt.Sprintf(
-		"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
-		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.DBName, cfg.SSLMode,
-	)
-
-	dbConn, err := sql.Open("pgx", dsn)
+	dbConn, err := sql.Open("pgx", dsn(cfg))
 	if err != nil {
 		return nil, fmt.Errorf("database connection opening failed: %w", err)
 	}
@@ -55,12 +55,25 @@ func NewClient(cfg *Config) (*Client, error) {
 		return nil, fmt.Errorf("database ping failed: %w", err)
 	}
 
-	return &Client{db: dbConn, pingTimeout: cfg.Timeout}, nil
+	return &Client{db: dbConn, pingTimeout: cfg.Timeout, rlsEnabled: cfg.RLSEnabled}, nil
+}
+
+// dsn builds the connection string for cfg. Row-level security policies are always enforced by the
+// database, so with RLS disabled every connection starts in the admin scope and sees the rows of all users.
+func dsn(cfg *Config) string {
+	s := fmt.Sprintf(
+		"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.DBName, cfg.SSLMode,
+	)
+	if !cfg.RLSEnabled {
+		s += fmt.Sprintf(" options='-c %s=on'", adminScopeSetting)
+	}
+	return s
 }
 
 // Exec executes a query that doesn't return rows (INSERT, UPDATE, DELETE).
 func (c *Client) Exec(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
-	result, err := c.db.ExecContext(ctx, query, args...)
+	result, err := c.executor(ctx).ExecContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("query %q execution failed: %w", query, err)
 	}
@@ -69,12 +82,12 @@ func (c *Client) Exec(ctx context.Context, query string, args ...interface{}) (s
 
 // QueryRow executes a query that returns at most one row and returns a *sql.Row.
 func (c *Client) QueryRow(ctx context.Context, query string, args ...interface{}) *sql.Row {
-	return c.db.QueryRowContext(ctx, query, args...)
+	return c.executor(ctx).QueryRowContext(ctx, query, args...)
 }
 
 // Query executes a query that returns multiple rows and returns a *sql.Rows result set.
 func (c *Client) Query(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
-	rows, err := c.db.QueryContext(ctx, query, args...)
+	rows, err := c.executor(ctx).QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("query %q execution failed: %w", query, err)
 	}
@@ -477,6 +477,38 @@ func TestDSNGeneration(t *testing.T) {
 	}
 }
 
+func TestDSN(t *testing.T) {
+	t.Parallel()
+
+	base := "host=localhost port=5432 user=u password=p dbname=d sslmode=disable"
+
+	tests := []struct {
+		name       string
+		want       string
+		rlsEnabled bool
+	}{
+		{name: "rls_enabled", want: base, rlsEnabled: true},
+		{name: "rls_disabled_starts_in_admin_scope", want: base + " options='-c aegis_vault_keeper.admin_scope=on'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := &Config{
+				Host:       "localhost",
+				Port:       5432,
+				User:       "u",
+				Password:   "p",
+				DBName:     "d",
+				SSLMode:    "disable",
+				RLSEnabled: tt.rlsEnabled,
+			}
+			assert.Equal(t, tt.want, dsn(cfg))
+		})
+	}
+}
+
 func TestDSNFormatting(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,107 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// userScopeSetting is the PostgreSQL run-time parameter read by row-level security policies.
+	userScopeSetting = "aegis_vault_keeper.user_id"
+	// adminScopeSetting is the PostgreSQL run-time parameter letting row-level security policies expose the
+	// rows of every user.
+	adminScopeSetting = "aegis_vault_keeper.admin_scope"
+)
+
+// txCtxKey is the context key under which the active transaction is stored.
+type txCtxKey struct{}
+
+// queryExecutor abstracts the query methods shared by *sql.DB and *sql.Tx.
+type queryExecutor interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// RunInUserScope executes fn inside a transaction where the row-level security user scope
+// is set with SET LOCAL semantics, so policies only expose rows owned by userID.
+// When RLS is disabled, userID is nil, or a scoped transaction is already active, fn runs directly.
+func (c *Client) RunInUserScope(
+	ctx context.Context,
+	userID uuid.UUID,
+	fn func(ctx context.Context) error,
+) error {
+	if !c.rlsEnabled || userID == uuid.Nil {
+		return fn(ctx)
+	}
+	return c.RunInTx(ctx, userID, fn)
+}
+
+// RunInAdminScope executes fn inside a transaction where the row-level security admin scope is set with
+// SET LOCAL semantics, so policies expose the rows of every user. Only instance-wide work such as statistics,
+// integrity checks and backups runs in it. When RLS is disabled, or a transaction is already active, fn runs
+// directly.
+func (c *Client) RunInAdminScope(ctx context.Context, fn func(ctx context.Context) error) error {
+	if !c.rlsEnabled {
+		return fn(ctx)
+	}
+	return c.runInTx(ctx, adminScopeSetting, "on", fn)
+}
+
+// RunInTx executes fn inside a single transaction, so the operations of several repositories
+// commit or roll back together. The row-level security user scope is set when RLS is enabled and
+// userID is not nil. When a transaction is already active, fn joins it.
+func (c *Client) RunInTx(
+	ctx context.Context,
+	userID uuid.UUID,
+	fn func(ctx context.Context) error,
+) error {
+	if c.rlsEnabled && userID != uuid.Nil {
+		return c.runInTx(ctx, userScopeSetting, userID.String(), fn)
+	}
+	return c.runInTx(ctx, "", "", fn)
+}
+
+// runInTx executes fn inside a single transaction with the run-time parameter setting set to value,
+// or with no parameter set when setting is empty. When a transaction is already active, fn joins it.
+func (c *Client) runInTx(ctx context.Context, setting, value string, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txCtxKey{}).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("transaction start failed: %w", err)
+	}
+
+	if setting != "" {
+		query := "SELECT set_config($1, $2, true)"
+		if _, err := tx.ExecContext(ctx, query, setting, value); err != nil {
+			return errors.Join(fmt.Errorf("scope setup failed: %w", err), tx.Rollback())
+		}
+	}
+
+	if err := fn(context.WithValue(ctx, txCtxKey{}, tx)); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return errors.Join(err, fmt.Errorf("transaction rollback failed: %w", rbErr))
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("transaction commit failed: %w", err)
+	}
+	return nil
+}
+
+// executor returns the active transaction from the context, or the connection pool otherwise.
+func (c *Client) executor(ctx context.Context) queryExecutor {
+	if tx, ok := ctx.Value(txCtxKey{}).(*sql.Tx); ok {
+		return tx
+	}
+	return c.db
+}
@@ -0,0 +1,140 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_RunInUserScope_Passthrough(t *testing.T) {
+	t.Parallel()
+
+	fnErr := errors.New("fn failed")
+
+	tests := []struct {
+		fnErr      error
+		name       string
+		userID     uuid.UUID
+		rlsEnabled bool
+	}{
+		{
+			name:       "rls_disabled",
+			userID:     uuid.New(),
+			rlsEnabled: false,
+		},
+		{
+			name:       "nil_user_id",
+			userID:     uuid.Nil,
+			rlsEnabled: true,
+		},
+		{
+			name:       "error_propagated",
+			userID:     uuid.New(),
+			rlsEnabled: false,
+			fnErr:      fnErr,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			c := &Client{rlsEnabled: tt.rlsEnabled}
+			ctx := context.Background()
+
+			called := false
+			err := c.RunInUserScope(ctx, tt.userID, func(inner context.Context) error {
+				called = true
+				assert.Equal(t, ctx, inner, "context should not be wrapped when scope is skipped")
+				return tt.fnErr
+			})
+
+			assert.True(t, called)
+			if tt.fnErr != nil {
+				require.ErrorIs(t, err, tt.fnErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestClient_Executor(t *testing.T) {
+	t.Parallel()
+
+	db := &sql.DB{}
+	tx := &sql.Tx{}
+	c := &Client{db: db}
+
+	t.Run("pool_without_scoped_tx", func(t *testing.T) {
+		t.Parallel()
+		assert.Same(t, db, c.executor(context.Background()))
+	})
+
+	t.Run("scoped_tx_from_context", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.WithValue(context.Background(), txCtxKey{}, tx)
+		assert.Same(t, tx, c.executor(ctx))
+	})
+}
+
+func TestClient_RunInTx_JoinsActiveTx(t *testing.T) {
+	t.Parallel()
+
+	c := &Client{db: &sql.DB{}, rlsEnabled: true}
+	ctx := context.WithValue(context.Background(), txCtxKey{}, &sql.Tx{})
+	fnErr := errors.New("fn failed")
+
+	err := c.RunInTx(ctx, uuid.New(), func(inner context.Context) error {
+		assert.Equal(t, ctx, inner, "active transaction should be joined")
+		return fnErr
+	})
+
+	require.ErrorIs(t, err, fnErr)
+}
+
+func TestClient_RunInAdminScope(t *testing.T) {
+	t.Parallel()
+
+	fnErr := errors.New("fn failed")
+
+	tests := []struct {
+		ctx        context.Context
+		name       string
+		rlsEnabled bool
+	}{
+		{
+			name:       "rls_disabled",
+			ctx:        context.Background(),
+			rlsEnabled: false,
+		},
+		{
+			name:       "joins_active_tx",
+			ctx:        context.WithValue(context.Background(), txCtxKey{}, &sql.Tx{}),
+			rlsEnabled: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			c := &Client{db: &sql.DB{}, rlsEnabled: tt.rlsEnabled}
+
+			called := false
+			err := c.RunInAdminScope(tt.ctx, func(inner context.Context) error {
+				called = true
+				assert.Equal(t, tt.ctx, inner, "context should not be wrapped when no transaction is opened")
+				return fnErr
+			})
+
+			assert.True(t, called)
+			require.ErrorIs(t, err, fnErr)
+		})
+	}
+}
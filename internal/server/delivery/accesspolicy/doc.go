@@ -0,0 +1,5 @@
+// Package accesspolicy provides HTTP handlers for access policy endpoints in the AegisVaultKeeper server.
+//
+// This package lets authenticated users review the availability windows their vault items can be
+// revealed in, and define or remove windows of their own on top of those set by administrators.
+package accesspolicy
@@ -0,0 +1,75 @@
+package accesspolicy
+
+import (
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/accesspolicy"
+	"github.com/google/uuid"
+)
+
+// Policy represents a vault availability window.
+type Policy struct {
+	// CreatedAt contains the policy creation timestamp.
+	CreatedAt time.Time `json:"created_at" example:"2023-12-01T10:00:00Z"`
+	// Start contains the HH:MM time of day the window opens at.
+	Start string `json:"start"      example:"09:00"`
+	// End contains the HH:MM time of day the window closes at.
+	End string `json:"end"        example:"18:00"`
+	// Timezone contains the IANA time zone the window is evaluated in.
+	Timezone string `json:"timezone"   example:"Europe/Berlin"`
+	// Days contains the weekdays the window opens on.
+	Days []string `json:"days"       example:"mon,tue,wed,thu,fri"`
+	// ID contains the unique policy identifier.
+	ID uuid.UUID `json:"id"         example:"123e4567-e89b-12d3-a456-426614174000"`
+	// Managed reports whether an administrator set the policy; managed policies cannot be removed by users.
+	Managed bool `json:"managed"    example:"false"`
+}
+
+// NewPolicyFromApp converts an application layer access policy to delivery DTO.
+func NewPolicyFromApp(p *accesspolicy.Policy) *Policy {
+	if p == nil {
+		return nil
+	}
+	return &Policy{
+		ID:        p.ID,
+		Days:      p.Days,
+		Start:     p.Start,
+		End:       p.End,
+		Timezone:  p.Timezone,
+		Managed:   p.Managed,
+		CreatedAt: p.CreatedAt,
+	}
+}
+
+// NewPoliciesFromApp converts application layer access policies to delivery DTOs.
+func NewPoliciesFromApp(ps []*accesspolicy.Policy) []*Policy {
+	result := make([]*Policy, 0, len(ps))
+	for _, p := range ps {
+		result = append(result, NewPolicyFromApp(p))
+	}
+	return result
+}
+
+// ListPoliciesResponse represents the response containing the access policies applying to the user.
+type ListPoliciesResponse struct {
+	// Policies contains the global and user policies ordered by creation time.
+	Policies []*Policy `json:"policies"`
+}
+
+// AddPolicyRequest represents the request to define an access window.
+type AddPolicyRequest struct {
+	// Start contains the HH:MM time of day the window opens at (required).
+	Start string `json:"start"              binding:"required" example:"09:00"`
+	// End contains the HH:MM time of day the window closes at, 24:00 for midnight (required).
+	End string `json:"end"                binding:"required" example:"18:00"`
+	// Timezone contains the IANA time zone of the window; omit for UTC.
+	Timezone string `json:"timezone,omitzero"                    example:"Europe/Berlin"`
+	// Days contains the weekdays the window opens on: mon, tue, wed, thu, fri, sat or sun (required).
+	Days []string `json:"days"               binding:"required" example:"mon,tue,wed,thu,fri"`
+}
+
+// PolicyIDRequest represents the access policy addressed in the request path.
+type PolicyIDRequest struct {
+	// ID contains the policy identifier (required UUID format).
+	ID string `uri:"id" binding:"required,uuid" example:"123e4567-e89b-12d3-a456-426614174000"`
+}
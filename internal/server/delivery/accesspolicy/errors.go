@@ -0,0 +1,78 @@
+package accesspolicy
+
+import (
+	"net/http"
+
+	app "github.com/gdyunin/aegis-vault-keeper/internal/server/application/accesspolicy"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/errutil"
+	"github.com/gin-gonic/gin"
+)
+
+// AccessPolicyErrRegistry defines error handling policies for access policy operations.
+var AccessPolicyErrRegistry = errutil.Registry{
+	{
+		ErrorIn: app.ErrAccessPolicyTechError,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusInternalServerError,
+			PublicMsg:  http.StatusText(http.StatusInternalServerError),
+			LogIt:      true,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassTech,
+		},
+	},
+	{
+		ErrorIn: app.ErrPolicyNotFound,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusNotFound,
+			PublicMsg:  "Access policy not found",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassGeneric,
+		},
+	},
+	{
+		ErrorIn: app.ErrPolicyIncorrectDays,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusBadRequest,
+			PublicMsg:  "Days must list at least one of mon, tue, wed, thu, fri, sat or sun",
+			LogIt:      false,
+			AllowMerge: true,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+	{
+		ErrorIn: app.ErrPolicyIncorrectTime,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusBadRequest,
+			PublicMsg:  "Start and end must be different HH:MM times of day",
+			LogIt:      false,
+			AllowMerge: true,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+	{
+		ErrorIn: app.ErrPolicyIncorrectTimezone,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusBadRequest,
+			PublicMsg:  "Timezone must be an IANA time zone such as Europe/Berlin",
+			LogIt:      false,
+			AllowMerge: true,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+	{
+		ErrorIn: app.ErrAccessPolicyAppError,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusBadRequest,
+			PublicMsg:  "Invalid access policy parameters",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+}
+
+// handleError processes access policy errors using the registry and returns appropriate HTTP response.
+func handleError(err error, c *gin.Context) (int, []string) {
+	return errutil.HandleWithRegistry(AccessPolicyErrRegistry, err, c)
+}
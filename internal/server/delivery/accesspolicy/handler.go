@@ -0,0 +1,164 @@
+package accesspolicy
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/accesspolicy"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/response"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/util"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Service defines the access policy application service interface.
+type Service interface {
+	// ListPolicies retrieves the access policies matching the filter.
+	ListPolicies(context.Context, accesspolicy.ListParams) ([]*accesspolicy.Policy, error)
+	// AddPolicy creates a new access policy.
+	AddPolicy(context.Context, accesspolicy.AddParams) (*accesspolicy.Policy, error)
+	// DeletePolicy removes an access policy.
+	DeletePolicy(context.Context, accesspolicy.DeleteParams) error
+}
+
+// Handler handles HTTP requests for access policy endpoints.
+type Handler struct {
+	// s is the access policy service used to process operations.
+	s Service
+}
+
+// NewHandler creates a new access policy handler with the provided service.
+func NewHandler(s Service) *Handler {
+	return &Handler{s: s}
+}
+
+// List retrieves the access policies applying to the authenticated user.
+// @Summary      List access policies
+// @Description  Retrieves the global, administrator-managed and own windows vault items can be revealed in
+// @Tags         Account
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} ListPoliciesResponse "Access policies retrieved successfully"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /account/access-policies [get]
+// .
+func (h *Handler) List(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	userID, err := extractor.UserID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+
+	policies, err := h.s.ListPolicies(c, accesspolicy.ListParams{UserID: userID, Global: true})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, ListPoliciesResponse{Policies: NewPoliciesFromApp(policies)})
+}
+
+// Add defines an access window of the authenticated user.
+// @Summary      Add access policy
+// @Description  Restricts item reveals to a recurring window. When the user has several windows, items can be
+// @Description  revealed while any of them is open; windows set by administrators still apply.
+// .
+// @Tags         Account
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body AddPolicyRequest true "Access window"
+// @Success      201 {object} Policy "Access policy created successfully"
+// @Failure      400 {object} response.Error "Bad request - invalid days, times or timezone"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /account/access-policies [post]
+// .
+func (h *Handler) Add(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	userID, err := extractor.UserID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+
+	// req holds the deserialized JSON request payload for the policy.
+	var req AddPolicyRequest
+	if err := extractor.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	policy, err := h.s.AddPolicy(c, accesspolicy.AddParams{
+		Days:     req.Days,
+		Start:    req.Start,
+		End:      req.End,
+		Timezone: req.Timezone,
+		UserID:   userID,
+	})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, NewPolicyFromApp(policy))
+}
+
+// Delete removes an access window of the authenticated user.
+// @Summary      Delete access policy
+// @Description  Removes a window the user defined; windows managed by administrators cannot be removed
+// @Tags         Account
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Access policy ID" format(uuid)
+// @Success      204 "Access policy deleted successfully"
+// @Failure      400 {object} response.Error "Bad request - invalid ID format"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
+// @Failure      404 {object} response.Error "Not found - access policy not found or managed by an administrator"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /account/access-policies/{id} [delete]
+// .
+func (h *Handler) Delete(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	userID, err := extractor.UserID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+
+	// req holds the deserialized URI parameters for the delete request.
+	var req PolicyIDRequest
+	if err := extractor.BindURI(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	policyID, err := uuid.Parse(req.ID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	if err := h.s.DeletePolicy(c, accesspolicy.DeleteParams{ID: policyID, UserID: userID}); err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.Data(http.StatusNoContent, "", nil)
+}
@@ -0,0 +1,252 @@
+package accesspolicy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/accesspolicy"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/consts"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockPolicyService implements Service for testing.
+type mockPolicyService struct {
+	listFunc   func(ctx context.Context, params accesspolicy.ListParams) ([]*accesspolicy.Policy, error)
+	addFunc    func(ctx context.Context, params accesspolicy.AddParams) (*accesspolicy.Policy, error)
+	deleteFunc func(ctx context.Context, params accesspolicy.DeleteParams) error
+}
+
+func (m *mockPolicyService) ListPolicies(
+	ctx context.Context,
+	params accesspolicy.ListParams,
+) ([]*accesspolicy.Policy, error) {
+	if m.listFunc != nil {
+		return m.listFunc(ctx, params)
+	}
+	return nil, nil
+}
+
+func (m *mockPolicyService) AddPolicy(
+	ctx context.Context,
+	params accesspolicy.AddParams,
+) (*accesspolicy.Policy, error) {
+	if m.addFunc != nil {
+		return m.addFunc(ctx, params)
+	}
+	return nil, nil
+}
+
+func (m *mockPolicyService) DeletePolicy(ctx context.Context, params accesspolicy.DeleteParams) error {
+	if m.deleteFunc != nil {
+		return m.deleteFunc(ctx, params)
+	}
+	return nil
+}
+
+func TestHandler_List(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	policyID := uuid.New()
+
+	tests := []struct {
+		mockService    *mockPolicyService
+		name           string
+		setUser        bool
+		wantCount      int
+		expectedStatus int
+	}{
+		{
+			name:    "success",
+			setUser: true,
+			mockService: &mockPolicyService{
+				listFunc: func(_ context.Context, params accesspolicy.ListParams) ([]*accesspolicy.Policy, error) {
+					assert.Equal(t, accesspolicy.ListParams{UserID: userID, Global: true}, params)
+					return []*accesspolicy.Policy{{
+						ID:       policyID,
+						Days:     []string{"mon"},
+						Start:    "09:00",
+						End:      "18:00",
+						Timezone: "UTC",
+						Managed:  true,
+					}}, nil
+				},
+			},
+			wantCount:      1,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "missing user context",
+			mockService:    &mockPolicyService{},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:    "service error",
+			setUser: true,
+			mockService: &mockPolicyService{
+				listFunc: func(context.Context, accesspolicy.ListParams) ([]*accesspolicy.Policy, error) {
+					return nil, accesspolicy.ErrAccessPolicyTechError
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/account/access-policies", nil)
+			if tt.setUser {
+				c.Set(consts.CtxKeyUserID, userID)
+			}
+
+			NewHandler(tt.mockService).List(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.wantCount == 0 {
+				return
+			}
+			var got ListPoliciesResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+			require.Len(t, got.Policies, tt.wantCount)
+			assert.Equal(t, policyID, got.Policies[0].ID)
+			assert.True(t, got.Policies[0].Managed)
+		})
+	}
+}
+
+func TestHandler_Add(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+
+	tests := []struct {
+		mockService    *mockPolicyService
+		name           string
+		body           string
+		expectedStatus int
+	}{
+		{
+			name: "success",
+			body: `{"days":["mon","fri"],"start":"09:00","end":"18:00","timezone":"Europe/Berlin"}`,
+			mockService: &mockPolicyService{
+				addFunc: func(_ context.Context, params accesspolicy.AddParams) (*accesspolicy.Policy, error) {
+					assert.Equal(t, accesspolicy.AddParams{
+						Days:     []string{"mon", "fri"},
+						Start:    "09:00",
+						End:      "18:00",
+						Timezone: "Europe/Berlin",
+						UserID:   userID,
+					}, params)
+					return &accesspolicy.Policy{ID: uuid.New(), Days: params.Days}, nil
+				},
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "missing days",
+			body:           `{"start":"09:00","end":"18:00"}`,
+			mockService:    &mockPolicyService{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "invalid timezone",
+			body: `{"days":["mon"],"start":"09:00","end":"18:00","timezone":"CEST"}`,
+			mockService: &mockPolicyService{
+				addFunc: func(context.Context, accesspolicy.AddParams) (*accesspolicy.Policy, error) {
+					return nil, errors.Join(accesspolicy.ErrAccessPolicyAppError, accesspolicy.ErrPolicyIncorrectTimezone)
+				},
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/account/access-policies", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Set(consts.CtxKeyUserID, userID)
+
+			NewHandler(tt.mockService).Add(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestHandler_Delete(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	policyID := uuid.New()
+
+	tests := []struct {
+		mockService    *mockPolicyService
+		name           string
+		id             string
+		expectedStatus int
+	}{
+		{
+			name: "success",
+			id:   policyID.String(),
+			mockService: &mockPolicyService{
+				deleteFunc: func(_ context.Context, params accesspolicy.DeleteParams) error {
+					assert.Equal(t, accesspolicy.DeleteParams{ID: policyID, UserID: userID}, params)
+					return nil
+				},
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "invalid ID",
+			id:             "work-hours",
+			mockService:    &mockPolicyService{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "managed or missing policy",
+			id:   policyID.String(),
+			mockService: &mockPolicyService{
+				deleteFunc: func(context.Context, accesspolicy.DeleteParams) error {
+					return accesspolicy.ErrPolicyNotFound
+				},
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodDelete, "/account/access-policies/"+tt.id, nil)
+			c.Params = gin.Params{{Key: "id", Value: tt.id}}
+			c.Set(consts.CtxKeyUserID, userID)
+
+			NewHandler(tt.mockService).Delete(c)
+
+			c.Writer.WriteHeaderNow()
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
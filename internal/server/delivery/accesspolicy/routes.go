@@ -0,0 +1,12 @@
+package accesspolicy
+
+import "github.com/gin-gonic/gin"
+
+// RegisterRoutes registers access policy routes with the provided router group.
+// Creates /access-policies and /access-policies/:id with the specified handler.
+func RegisterRoutes(r *gin.RouterGroup, h *Handler) {
+	policiesGroup := r.Group("/access-policies")
+	policiesGroup.GET("", h.List)
+	policiesGroup.POST("", h.Add)
+	policiesGroup.DELETE("/:id", h.Delete)
+}
@@ -0,0 +1,26 @@
+package accesspolicy
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterRoutes(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	RegisterRoutes(router.Group("/account"), NewHandler(&mockPolicyService{}))
+
+	got := make(map[string]string)
+	for _, r := range router.Routes() {
+		got[r.Method+" "+r.Path] = r.Handler
+	}
+	assert.Len(t, got, 3)
+	assert.Contains(t, got, http.MethodGet+" /account/access-policies")
+	assert.Contains(t, got, http.MethodPost+" /account/access-policies")
+	assert.Contains(t, got, http.MethodDelete+" /account/access-policies/:id")
+}
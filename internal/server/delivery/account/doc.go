@@ -0,0 +1,5 @@
+// Package account provides HTTP handlers for account-level endpoints in the AegisVaultKeeper server.
+//
+// This package exposes reports that describe the state of the authenticated user's vault as a whole,
+// such as the vault health report.
+package account
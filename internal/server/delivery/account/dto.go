@@ -0,0 +1,89 @@
+package account
+
+import (
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/vaulthealth"
+	"github.com/google/uuid"
+)
+
+// HealthReport represents the vault health report of the authenticated user.
+type HealthReport struct {
+	// GeneratedAt indicates when the report was produced.
+	GeneratedAt time.Time `json:"generated_at" example:"2023-12-01T10:00:00Z"`
+	// DecryptionChecks lists the decryption spot-check result per item category.
+	DecryptionChecks []DecryptionCheck `json:"decryption_checks"`
+	// ExpiringCards lists bank cards that expired or expire soon.
+	ExpiringCards []ExpiringCard `json:"expiring_cards"`
+	// WeakCredentials lists credentials whose passwords are weak or reused.
+	WeakCredentials []WeakCredential `json:"weak_credentials"`
+	// Usage contains the vault storage usage.
+	Usage Usage `json:"usage"`
+}
+
+// DecryptionCheck represents the decryption spot-check result of an item category.
+type DecryptionCheck struct {
+	// Category identifies the checked item category.
+	Category string `json:"category" example:"credentials"`
+	// Checked is the number of items decrypted successfully.
+	Checked int `json:"checked" example:"12"`
+	// Passed reports whether all checked items decrypted and verified successfully.
+	Passed bool `json:"passed" example:"true"`
+}
+
+// ExpiringCard represents a bank card that expired or expires soon.
+type ExpiringCard struct {
+	// ExpiresAt is the first moment the card is no longer valid.
+	ExpiresAt time.Time `json:"expires_at" example:"2024-01-01T00:00:00Z"`
+	// ID identifies the bank card.
+	ID uuid.UUID `json:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	// Expired reports whether the card is already expired.
+	Expired bool `json:"expired" example:"false"`
+}
+
+// WeakCredential represents a credential whose password failed the strength analysis.
+type WeakCredential struct {
+	// Reasons lists the detected password weaknesses.
+	Reasons []string `json:"reasons" example:"too_short,reused"`
+	// ID identifies the credential.
+	ID uuid.UUID `json:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
+}
+
+// Usage represents item counts and the storage occupied by the vault.
+type Usage struct {
+	// FileBytes is the storage occupied by encrypted file contents.
+	FileBytes int64 `json:"file_bytes" example:"1048576"`
+	// Credentials is the number of stored credentials.
+	Credentials int `json:"credentials" example:"12"`
+	// BankCards is the number of stored bank cards.
+	BankCards int `json:"bankcards" example:"2"`
+	// Notes is the number of stored notes.
+	Notes int `json:"notes" example:"5"`
+	// Files is the number of stored files.
+	Files int `json:"files" example:"3"`
+}
+
+// NewHealthReportFromApp creates a delivery layer HealthReport from application layer data.
+func NewHealthReportFromApp(r *vaulthealth.Report) *HealthReport {
+	if r == nil {
+		return nil
+	}
+
+	resp := &HealthReport{
+		GeneratedAt:      r.GeneratedAt,
+		DecryptionChecks: make([]DecryptionCheck, 0, len(r.DecryptionChecks)),
+		ExpiringCards:    make([]ExpiringCard, 0, len(r.ExpiringCards)),
+		WeakCredentials:  make([]WeakCredential, 0, len(r.WeakCredentials)),
+		Usage:            Usage(r.Usage),
+	}
+	for _, c := range r.DecryptionChecks {
+		resp.DecryptionChecks = append(resp.DecryptionChecks, DecryptionCheck(c))
+	}
+	for _, c := range r.ExpiringCards {
+		resp.ExpiringCards = append(resp.ExpiringCards, ExpiringCard(c))
+	}
+	for _, c := range r.WeakCredentials {
+		resp.WeakCredentials = append(resp.WeakCredentials, WeakCredential(c))
+	}
+	return resp
+}
@@ -0,0 +1,62 @@
+package account
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/vaulthealth"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/response"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/util"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Service defines the account application service interface.
+type Service interface {
+	// Generate produces the vault health report of the specified user.
+	Generate(context.Context, uuid.UUID) (*vaulthealth.Report, error)
+}
+
+// Handler handles HTTP requests for account endpoints.
+type Handler struct {
+	// s is the account service used to build reports.
+	s Service
+}
+
+// NewHandler creates a new account handler with the provided service.
+func NewHandler(s Service) *Handler {
+	return &Handler{s: s}
+}
+
+// HealthReport returns the vault health report of the authenticated user.
+// @Summary      Get vault health report
+// @Description  Checks that stored items decrypt, calculates storage usage and reports expiring cards
+// @Description  and weak or reused passwords. Item contents are never included.
+// .
+// @Tags         Account
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} HealthReport "Vault health report generated successfully"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /account/health-report [get]
+// .
+func (h *Handler) HealthReport(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	userID, err := extractor.UserID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+
+	report, err := h.s.Generate(c, userID)
+	if err != nil {
+		_ = c.Error(err)
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+
+	c.JSON(http.StatusOK, NewHealthReportFromApp(report))
+}
@@ -0,0 +1,119 @@
+package account
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/vaulthealth"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/consts"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockAccountService implements Service for testing.
+type mockAccountService struct {
+	generateFunc func(ctx context.Context, userID uuid.UUID) (*vaulthealth.Report, error)
+}
+
+func (m *mockAccountService) Generate(ctx context.Context, userID uuid.UUID) (*vaulthealth.Report, error) {
+	if m.generateFunc != nil {
+		return m.generateFunc(ctx, userID)
+	}
+	return &vaulthealth.Report{UserID: userID}, nil
+}
+
+func TestHandler_HealthReport(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	credID := uuid.New()
+	generatedAt := time.Date(2025, time.March, 15, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		setupContext   func(c *gin.Context)
+		mockService    *mockAccountService
+		want           *HealthReport
+		name           string
+		expectedStatus int
+	}{
+		{
+			name: "success",
+			setupContext: func(c *gin.Context) {
+				c.Set(consts.CtxKeyUserID, userID)
+			},
+			mockService: &mockAccountService{
+				generateFunc: func(ctx context.Context, id uuid.UUID) (*vaulthealth.Report, error) {
+					return &vaulthealth.Report{
+						UserID:      id,
+						GeneratedAt: generatedAt,
+						DecryptionChecks: []vaulthealth.DecryptionCheck{
+							{Category: vaulthealth.CategoryNotes, Checked: 2, Passed: true},
+						},
+						WeakCredentials: []vaulthealth.WeakCredential{
+							{ID: credID, Reasons: []string{vaulthealth.WeaknessReused}},
+						},
+						Usage: vaulthealth.Usage{Notes: 2, Credentials: 1},
+					}, nil
+				},
+			},
+			want: &HealthReport{
+				GeneratedAt:      generatedAt,
+				DecryptionChecks: []DecryptionCheck{{Category: "notes", Checked: 2, Passed: true}},
+				ExpiringCards:    []ExpiringCard{},
+				WeakCredentials:  []WeakCredential{{ID: credID, Reasons: []string{"reused"}}},
+				Usage:            Usage{Notes: 2, Credentials: 1},
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "missing user context",
+			setupContext: func(c *gin.Context) {
+				// don't set user_id
+			},
+			mockService:    &mockAccountService{},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name: "service error",
+			setupContext: func(c *gin.Context) {
+				c.Set(consts.CtxKeyUserID, userID)
+			},
+			mockService: &mockAccountService{
+				generateFunc: func(ctx context.Context, id uuid.UUID) (*vaulthealth.Report, error) {
+					return nil, errors.New("service error")
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/health-report", nil)
+
+			tt.setupContext(c)
+
+			NewHandler(tt.mockService).HealthReport(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.want == nil {
+				return
+			}
+			var got HealthReport
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+			assert.Equal(t, *tt.want, got)
+		})
+	}
+}
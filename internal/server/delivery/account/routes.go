@@ -0,0 +1,8 @@
+package account
+
+import "github.com/gin-gonic/gin"
+
+// RegisterRoutes registers account routes with the provided router group.
+func RegisterRoutes(r *gin.RouterGroup, h *Handler) {
+	r.GET("/health-report", h.HealthReport)
+}
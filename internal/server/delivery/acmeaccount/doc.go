@@ -0,0 +1,5 @@
+// Package acmeaccount provides HTTP handlers for ACME account management endpoints in the AegisVaultKeeper server.
+//
+// This package implements REST API endpoints for storing and retrieving ACME account keys together with
+// the DNS provider credentials certificate renewal tooling needs to answer DNS-01 challenges.
+package acmeaccount
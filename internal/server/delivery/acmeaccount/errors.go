@@ -0,0 +1,139 @@
+package acmeaccount
+
+import (
+	"net/http"
+
+	app "github.com/gdyunin/aegis-vault-keeper/internal/server/application/acmeaccount"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/errutil"
+	"github.com/gin-gonic/gin"
+)
+
+// ACMEErrRegistry defines error handling policies for ACME account operations.
+var ACMEErrRegistry = errutil.Registry{
+
+	{
+		ErrorIn: app.ErrACMETechError,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusInternalServerError,
+			PublicMsg:  http.StatusText(http.StatusInternalServerError),
+			LogIt:      true,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassTech,
+		},
+	},
+
+	{
+		ErrorIn: app.ErrACMEIntegrityViolation,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusInternalServerError,
+			PublicMsg:  "Stored ACME account data failed integrity verification",
+			LogIt:      true,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassTech,
+		},
+	},
+
+	{
+		ErrorIn: app.ErrACMEAccessDenied,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusForbidden,
+			PublicMsg:  "Access to this ACME account is denied",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassAuth,
+		},
+	},
+
+	{
+		ErrorIn: app.ErrACMENotFound,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusNotFound,
+			PublicMsg:  "ACME account not found",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassGeneric,
+		},
+	},
+
+	{
+		ErrorIn: app.ErrACMEIncorrectDirectoryURL,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusBadRequest,
+			PublicMsg:  "Invalid directory URL, an HTTPS URL is required",
+			LogIt:      false,
+			AllowMerge: true,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+
+	{
+		ErrorIn: app.ErrACMEIncorrectEmail,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusBadRequest,
+			PublicMsg:  "Invalid account e-mail",
+			LogIt:      false,
+			AllowMerge: true,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+
+	{
+		ErrorIn: app.ErrACMEIncorrectAccountKey,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusBadRequest,
+			PublicMsg:  "Invalid account key, a PEM-encoded private key is required",
+			LogIt:      false,
+			AllowMerge: true,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+
+	{
+		ErrorIn: app.ErrACMEIncorrectDNSProvider,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusBadRequest,
+			PublicMsg:  "Invalid DNS provider",
+			LogIt:      false,
+			AllowMerge: true,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+
+	{
+		ErrorIn: app.ErrACMEIncorrectDNSCredentials,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusBadRequest,
+			PublicMsg:  "Invalid DNS credentials",
+			LogIt:      false,
+			AllowMerge: true,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+
+	{
+		ErrorIn: app.ErrACMEIncorrectHookURL,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusBadRequest,
+			PublicMsg:  "Invalid hook URL, an HTTPS URL is required",
+			LogIt:      false,
+			AllowMerge: true,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+
+	{
+		ErrorIn: app.ErrACMEAppError,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusBadRequest,
+			PublicMsg:  "Invalid parameters",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+}
+
+// handleError processes ACME account errors using the registry and returns appropriate HTTP response.
+func handleError(err error, c *gin.Context) (int, []string) {
+	return errutil.HandleWithRegistry(ACMEErrRegistry, err, c)
+}
@@ -0,0 +1,188 @@
+package acmeaccount
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/acmeaccount"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/response"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/util"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Service defines the ACME account application service interface.
+type Service interface {
+	// Pull retrieves a specific ACME account by ID for the authenticated user.
+	Pull(context.Context, acmeaccount.PullParams) (*acmeaccount.Account, error)
+	// List retrieves all ACME accounts belonging to the authenticated user.
+	List(context.Context, acmeaccount.ListParams) ([]*acmeaccount.Account, error)
+	// Push creates or updates an ACME account for the authenticated user.
+	Push(context.Context, *acmeaccount.PushParams) (uuid.UUID, error)
+}
+
+// Handler handles HTTP requests for ACME account management endpoints.
+type Handler struct {
+	// s is the ACME account service used to process account operations.
+	s Service
+}
+
+// NewHandler creates a new ACME account handler with the provided service.
+func NewHandler(s Service) *Handler {
+	return &Handler{s: s}
+}
+
+// Pull retrieves a specific ACME account by ID.
+// @Summary      Get ACME account by ID
+// @Description  Retrieves a specific ACME account with its key and DNS credentials
+// @Tags         ACMEAccounts
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "ACME account ID" format(uuid)
+// @Success      200 {object} PullResponse "ACME account retrieved successfully"
+// @Failure      400 {object} response.Error "Bad request - invalid ID format"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
+// @Failure      404 {object} response.Error "Not found - ACME account not found"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /items/acme/{id} [get]
+// .
+func (h *Handler) Pull(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	userID, err := extractor.UserID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+
+	// req holds the deserialized URI parameters for the pull request.
+	var req PullRequest
+	if err := extractor.BindURI(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	pullingID, err := uuid.Parse(req.ID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	a, err := h.s.Pull(c, acmeaccount.PullParams{ID: pullingID, UserID: userID})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, PullResponse{Account: NewAccountFromApp(a)})
+}
+
+// List retrieves all ACME accounts for the authenticated user.
+// @Summary      List all ACME accounts
+// @Description  Retrieves all ACME accounts belonging to the authenticated user
+// @Tags         ACMEAccounts
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} ListResponse "ACME accounts retrieved successfully"
+// @Success      204 "No ACME accounts found"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /items/acme [get]
+// .
+func (h *Handler) List(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	userID, err := extractor.UserID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+
+	accounts, err := h.s.List(c, acmeaccount.ListParams{UserID: userID})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	if len(accounts) == 0 {
+		c.Status(http.StatusNoContent)
+		return
+	}
+
+	c.JSON(http.StatusOK, ListResponse{Accounts: NewAccountsFromApp(accounts)})
+}
+
+// Push creates a new ACME account or updates an existing one.
+// @Summary      Create or update ACME account
+// @Description  Creates a new ACME account or updates an existing one if ID is provided in URL path.
+// @Description  When a hook URL is set, the hook receives a secret-free acme.account_updated event after the save.
+// .
+// @Tags         ACMEAccounts
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string false "ACME account ID for update operation" format(uuid)
+// @Param        request body PushRequest true "ACME account data"
+// @Success      201 {object} PushResponse "ACME account created or updated successfully"
+// @Failure      400 {object} response.Error "Bad request - invalid input data"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
+// @Failure      404 {object} response.Error "Not found - ACME account not found for update"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /items/acme [post]
+// @Router       /items/acme/{id} [put]
+// .
+func (h *Handler) Push(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	userID, err := extractor.UserID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+
+	// req holds the deserialized JSON request payload for the push operation.
+	var req PushRequest
+	if err := extractor.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	accountID := uuid.Nil
+	if idStr := c.Param("id"); idStr != "" {
+		id, err := uuid.Parse(idStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+			return
+		}
+		accountID = id
+	}
+
+	newID, err := h.s.Push(c, &acmeaccount.PushParams{
+		ID:             accountID,
+		UserID:         userID,
+		DirectoryURL:   req.DirectoryURL,
+		Email:          req.Email,
+		AccountKey:     req.AccountKey,
+		DNSProvider:    req.DNSProvider,
+		DNSCredentials: req.DNSCredentials,
+		HookURL:        req.HookURL,
+		Description:    req.Description,
+	})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, PushResponse{ID: newID})
+}
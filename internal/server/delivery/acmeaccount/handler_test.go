@@ -0,0 +1,307 @@
+package acmeaccount
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/acmeaccount"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/response"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockService implements the Service interface for testing.
+type mockService struct {
+	pullFunc func(ctx context.Context, params acmeaccount.PullParams) (*acmeaccount.Account, error)
+	listFunc func(ctx context.Context, params acmeaccount.ListParams) ([]*acmeaccount.Account, error)
+	pushFunc func(ctx context.Context, params *acmeaccount.PushParams) (uuid.UUID, error)
+}
+
+func (m *mockService) Pull(ctx context.Context, params acmeaccount.PullParams) (*acmeaccount.Account, error) {
+	if m.pullFunc != nil {
+		return m.pullFunc(ctx, params)
+	}
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockService) List(ctx context.Context, params acmeaccount.ListParams) ([]*acmeaccount.Account, error) {
+	if m.listFunc != nil {
+		return m.listFunc(ctx, params)
+	}
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockService) Push(ctx context.Context, params *acmeaccount.PushParams) (uuid.UUID, error) {
+	if m.pushFunc != nil {
+		return m.pushFunc(ctx, params)
+	}
+	return uuid.Nil, errors.New("not implemented")
+}
+
+// assertJSONBody compares the response body with the expected value encoded as JSON.
+func assertJSONBody(t *testing.T, expected interface{}, body []byte) {
+	t.Helper()
+
+	var actual interface{}
+	require.NoError(t, json.Unmarshal(body, &actual))
+	expectedBytes, err := json.Marshal(expected)
+	require.NoError(t, err)
+	var want interface{}
+	require.NoError(t, json.Unmarshal(expectedBytes, &want))
+	assert.Equal(t, want, actual)
+}
+
+func TestHandler_Pull(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+
+	userID := uuid.New()
+	accountID := uuid.New()
+
+	tests := []struct {
+		expectedBody   interface{}
+		pullErr        error
+		name           string
+		urlParam       string
+		expectedStatus int
+		noUser         bool
+	}{
+		{
+			name:           "successful pull",
+			urlParam:       accountID.String(),
+			expectedStatus: http.StatusOK,
+			expectedBody: PullResponse{Account: &Account{
+				ID:             accountID,
+				DirectoryURL:   "https://acme.example.com/directory",
+				AccountKey:     "key",
+				DNSProvider:    "cloudflare",
+				DNSCredentials: map[string]string{"CF_DNS_API_TOKEN": "token"},
+			}},
+		},
+		{
+			name:           "missing user ID",
+			urlParam:       accountID.String(),
+			noUser:         true,
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   response.DefaultInternalServerError,
+		},
+		{
+			name:           "invalid UUID in path",
+			urlParam:       "invalid-uuid",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   response.DefaultBadRequestError,
+		},
+		{
+			name:           "not found",
+			urlParam:       accountID.String(),
+			pullErr:        acmeaccount.ErrACMENotFound,
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   response.Error{Messages: []string{"ACME account not found"}},
+		},
+		{
+			name:           "access denied",
+			urlParam:       accountID.String(),
+			pullErr:        acmeaccount.ErrACMEAccessDenied,
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   response.Error{Messages: []string{"Access to this ACME account is denied"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			handler := NewHandler(&mockService{
+				pullFunc: func(_ context.Context, p acmeaccount.PullParams) (*acmeaccount.Account, error) {
+					assert.Equal(t, acmeaccount.PullParams{ID: accountID, UserID: userID}, p)
+					if tt.pullErr != nil {
+						return nil, tt.pullErr
+					}
+					return &acmeaccount.Account{
+						ID:             accountID,
+						UserID:         userID,
+						DirectoryURL:   "https://acme.example.com/directory",
+						AccountKey:     "key",
+						DNSProvider:    "cloudflare",
+						DNSCredentials: map[string]string{"CF_DNS_API_TOKEN": "token"},
+					}, nil
+				},
+			})
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/acme/"+tt.urlParam, nil)
+			c.Params = gin.Params{{Key: "id", Value: tt.urlParam}}
+			if !tt.noUser {
+				c.Set("userID", userID)
+			}
+
+			handler.Pull(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assertJSONBody(t, tt.expectedBody, w.Body.Bytes())
+		})
+	}
+}
+
+func TestHandler_List(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+
+	userID := uuid.New()
+	accountID := uuid.New()
+
+	tests := []struct {
+		expectedBody   interface{}
+		listErr        error
+		name           string
+		accounts       []*acmeaccount.Account
+		expectedStatus int
+	}{
+		{
+			name:           "accounts listed",
+			accounts:       []*acmeaccount.Account{{ID: accountID, DNSProvider: "route53"}},
+			expectedStatus: http.StatusOK,
+			expectedBody:   ListResponse{Accounts: []*Account{{ID: accountID, DNSProvider: "route53"}}},
+		},
+		{
+			name:           "no accounts",
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "tech error",
+			listErr:        acmeaccount.ErrACMETechError,
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   response.Error{Messages: []string{"Internal Server Error"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			handler := NewHandler(&mockService{
+				listFunc: func(_ context.Context, p acmeaccount.ListParams) ([]*acmeaccount.Account, error) {
+					assert.Equal(t, userID, p.UserID)
+					return tt.accounts, tt.listErr
+				},
+			})
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/acme", nil)
+			c.Set("userID", userID)
+
+			handler.List(c)
+
+			c.Writer.WriteHeaderNow()
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != nil {
+				assertJSONBody(t, tt.expectedBody, w.Body.Bytes())
+			}
+		})
+	}
+}
+
+func TestHandler_Push(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+
+	userID := uuid.New()
+	accountID := uuid.New()
+	validBody := `{"directory_url":"https://acme.example.com/directory","account_key":"key",` +
+		`"dns_provider":"cloudflare","dns_credentials":{"CF_DNS_API_TOKEN":"token"},` +
+		`"hook_url":"https://renew.example.com/hooks/acme"}`
+
+	tests := []struct {
+		expectedBody   interface{}
+		pushErr        error
+		name           string
+		body           string
+		urlParam       string
+		wantID         uuid.UUID
+		expectedStatus int
+	}{
+		{
+			name:           "created",
+			body:           validBody,
+			expectedStatus: http.StatusCreated,
+			expectedBody:   PushResponse{ID: accountID},
+		},
+		{
+			name:           "updated",
+			body:           validBody,
+			urlParam:       accountID.String(),
+			wantID:         accountID,
+			expectedStatus: http.StatusCreated,
+			expectedBody:   PushResponse{ID: accountID},
+		},
+		{
+			name:           "missing account key",
+			body:           `{"directory_url":"https://acme.example.com/directory"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   response.DefaultBadRequestError,
+		},
+		{
+			name:           "invalid UUID in path",
+			body:           validBody,
+			urlParam:       "invalid-uuid",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   response.DefaultBadRequestError,
+		},
+		{
+			name:           "validation errors merged",
+			body:           validBody,
+			pushErr:        errors.Join(acmeaccount.ErrACMEIncorrectAccountKey, acmeaccount.ErrACMEIncorrectHookURL),
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: response.Error{Messages: []string{
+				"Invalid account key, a PEM-encoded private key is required",
+				"Invalid hook URL, an HTTPS URL is required",
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			handler := NewHandler(&mockService{
+				pushFunc: func(_ context.Context, p *acmeaccount.PushParams) (uuid.UUID, error) {
+					assert.Equal(t, tt.wantID, p.ID)
+					assert.Equal(t, userID, p.UserID)
+					assert.Equal(t, "cloudflare", p.DNSProvider)
+					assert.Equal(t, map[string]string{"CF_DNS_API_TOKEN": "token"}, p.DNSCredentials)
+					assert.Equal(t, "https://renew.example.com/hooks/acme", p.HookURL)
+					if tt.pushErr != nil {
+						return uuid.Nil, tt.pushErr
+					}
+					return accountID, nil
+				},
+			})
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/acme", bytes.NewBufferString(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			if tt.urlParam != "" {
+				c.Params = gin.Params{{Key: "id", Value: tt.urlParam}}
+			}
+			c.Set("userID", userID)
+
+			handler.Push(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assertJSONBody(t, tt.expectedBody, w.Body.Bytes())
+		})
+	}
+}
@@ -0,0 +1,18 @@
+package acmeaccount
+
+import (
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/util"
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterRoutes registers ACME account management routes with the provided router group.
+// The reveal middleware runs on the route returning the content of a single ACME account.
+func RegisterRoutes(r *gin.RouterGroup, h *Handler, reveal ...gin.HandlerFunc) {
+	acmeGroup := r.Group("/acme")
+	acmeGroup.POST("", h.Push)
+	acmeGroup.GET("", h.List)
+
+	acmeIDGroup := acmeGroup.Group("/:id")
+	acmeIDGroup.GET("", util.Chain(reveal, h.Pull)...)
+	acmeIDGroup.PUT("", h.Push)
+}
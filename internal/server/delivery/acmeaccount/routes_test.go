@@ -0,0 +1,46 @@
+package acmeaccount
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterRoutes(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	RegisterRoutes(router.Group("/api/items"), &Handler{})
+
+	// got collects the registered routes as method and path pairs.
+	got := make(map[string]bool)
+	for _, route := range router.Routes() {
+		got[route.Method+" "+route.Path] = true
+	}
+
+	expected := []string{
+		http.MethodPost + " /api/items/acme",
+		http.MethodGet + " /api/items/acme",
+		http.MethodGet + " /api/items/acme/:id",
+		http.MethodPut + " /api/items/acme/:id",
+	}
+	for _, route := range expected {
+		assert.True(t, got[route], "expected route %s not found", route)
+	}
+	assert.Len(t, got, len(expected))
+}
+
+func TestRegisterRoutes_HandlerNil(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+
+	require.NotPanics(t, func() {
+		RegisterRoutes(gin.New().Group("/"), nil)
+	})
+}
@@ -0,0 +1,5 @@
+// Package admin provides HTTP handlers for administrative endpoints in the AegisVaultKeeper server.
+//
+// This package exposes operator-only APIs authorized by the admin token,
+// such as management of network access rules.
+package admin
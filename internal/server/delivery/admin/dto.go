@@ -0,0 +1,1013 @@
+package admin
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/accesscontrol"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/accesspolicy"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/diagnostics"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/feature"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/itempolicy"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/legalhold"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/license"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/maintenance"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/recording"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/retention"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/stats"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/storagegc"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/telemetry"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/updatecheck"
+	"github.com/google/uuid"
+)
+
+// AccessRule represents a network access rule.
+type AccessRule struct {
+	// CreatedAt contains the rule creation timestamp.
+	CreatedAt time.Time `json:"created_at"        example:"2023-12-01T10:00:00Z"`
+	// Network contains the matched CIDR; omitted for country rules.
+	Network string `json:"network,omitzero"  example:"203.0.113.0/24"`
+	// Country contains the matched ISO 3166-1 alpha-2 country code; omitted for network rules.
+	Country string `json:"country,omitzero"  example:"DE"`
+	// Action contains "allow" or "deny".
+	Action string `json:"action"            example:"deny"`
+	// ID contains the unique rule identifier.
+	ID uuid.UUID `json:"id"                example:"123e4567-e89b-12d3-a456-426614174000"`
+	// UserID contains the user the rule applies to; omitted for global rules.
+	UserID uuid.UUID `json:"user_id,omitzero"  example:"123e4567-e89b-12d3-a456-426614174001"`
+}
+
+// NewAccessRuleFromApp converts an application layer access rule to delivery DTO.
+func NewAccessRuleFromApp(r *accesscontrol.Rule) *AccessRule {
+	if r == nil {
+		return nil
+	}
+	return &AccessRule{
+		ID:        r.ID,
+		UserID:    r.UserID,
+		Action:    r.Action,
+		Network:   r.Network,
+		Country:   r.Country,
+		CreatedAt: r.CreatedAt,
+	}
+}
+
+// NewAccessRulesFromApp converts a slice of application layer access rules to delivery DTOs.
+func NewAccessRulesFromApp(rules []*accesscontrol.Rule) []*AccessRule {
+	if rules == nil {
+		return nil
+	}
+	result := make([]*AccessRule, 0, len(rules))
+	for _, r := range rules {
+		result = append(result, NewAccessRuleFromApp(r))
+	}
+	return result
+}
+
+// ListAccessRulesRequest represents the filter of the access rule listing.
+// Without filters all rules are listed.
+type ListAccessRulesRequest struct {
+	// UserID selects the rules of the specified user.
+	UserID string `form:"user_id" example:"123e4567-e89b-12d3-a456-426614174001"`
+	// Global selects the rules applying to all users.
+	Global bool `form:"global"  example:"true"`
+}
+
+// AddAccessRuleRequest represents the data required to create an access rule.
+// Exactly one of network or country must be set.
+type AddAccessRuleRequest struct {
+	// Action contains "allow" or "deny" (required).
+	Action string `json:"action"            binding:"required" example:"deny"`
+	// Network contains a CIDR or IP address to match.
+	Network string `json:"network,omitzero"                    example:"203.0.113.0/24"`
+	// Country contains an ISO 3166-1 alpha-2 country code to match (requires a GeoIP database).
+	Country string `json:"country,omitzero"                    example:"DE"`
+	// UserID contains the user the rule applies to; omit for a global rule.
+	UserID uuid.UUID `json:"user_id,omitzero"                    example:"123e4567-e89b-12d3-a456-426614174001"`
+}
+
+// DeleteAccessRuleRequest represents the request to delete an access rule.
+type DeleteAccessRuleRequest struct {
+	// ID contains the rule identifier (required UUID format).
+	ID string `uri:"id" binding:"required" example:"123e4567-e89b-12d3-a456-426614174000"`
+}
+
+// ListAccessRulesResponse represents the response containing access rules.
+type ListAccessRulesResponse struct {
+	// Rules contains the matching access rules.
+	Rules []*AccessRule `json:"rules"`
+}
+
+// AddAccessRuleResponse represents the response after creating an access rule.
+type AddAccessRuleResponse struct {
+	// Rule contains the created access rule.
+	Rule *AccessRule `json:"rule"`
+}
+
+// AccessPolicy represents a vault availability window.
+type AccessPolicy struct {
+	// CreatedAt contains the policy creation timestamp.
+	CreatedAt time.Time `json:"created_at"        example:"2023-12-01T10:00:00Z"`
+	// Start contains the HH:MM time of day the window opens at.
+	Start string `json:"start"             example:"09:00"`
+	// End contains the HH:MM time of day the window closes at.
+	End string `json:"end"               example:"18:00"`
+	// Timezone contains the IANA time zone the window is evaluated in.
+	Timezone string `json:"timezone"          example:"Europe/Berlin"`
+	// Days contains the weekdays the window opens on.
+	Days []string `json:"days"              example:"mon,tue,wed,thu,fri"`
+	// ID contains the unique policy identifier.
+	ID uuid.UUID `json:"id"                example:"123e4567-e89b-12d3-a456-426614174000"`
+	// UserID contains the user the policy applies to; omitted for global policies.
+	UserID uuid.UUID `json:"user_id,omitzero"  example:"123e4567-e89b-12d3-a456-426614174001"`
+	// Managed reports whether an administrator set the policy.
+	Managed bool `json:"managed"           example:"true"`
+}
+
+// NewAccessPolicyFromApp converts an application layer access policy to delivery DTO.
+func NewAccessPolicyFromApp(p *accesspolicy.Policy) *AccessPolicy {
+	if p == nil {
+		return nil
+	}
+	return &AccessPolicy{
+		ID:        p.ID,
+		UserID:    p.UserID,
+		Days:      p.Days,
+		Start:     p.Start,
+		End:       p.End,
+		Timezone:  p.Timezone,
+		Managed:   p.Managed,
+		CreatedAt: p.CreatedAt,
+	}
+}
+
+// NewAccessPoliciesFromApp converts a slice of application layer access policies to delivery DTOs.
+func NewAccessPoliciesFromApp(policies []*accesspolicy.Policy) []*AccessPolicy {
+	if policies == nil {
+		return nil
+	}
+	result := make([]*AccessPolicy, 0, len(policies))
+	for _, p := range policies {
+		result = append(result, NewAccessPolicyFromApp(p))
+	}
+	return result
+}
+
+// ListAccessPoliciesRequest represents the filter of the access policy listing.
+// Without filters all policies are listed.
+type ListAccessPoliciesRequest struct {
+	// UserID selects the policies of the specified user.
+	UserID string `form:"user_id" example:"123e4567-e89b-12d3-a456-426614174001"`
+	// Global selects the policies applying to all users.
+	Global bool `form:"global"  example:"true"`
+}
+
+// AddAccessPolicyRequest represents the data required to create an access policy.
+type AddAccessPolicyRequest struct {
+	// Start contains the HH:MM time of day the window opens at (required).
+	Start string `json:"start"             binding:"required" example:"09:00"`
+	// End contains the HH:MM time of day the window closes at, 24:00 for midnight (required).
+	End string `json:"end"               binding:"required" example:"18:00"`
+	// Timezone contains the IANA time zone of the window; omit for UTC.
+	Timezone string `json:"timezone,omitzero"                   example:"Europe/Berlin"`
+	// Days contains the weekdays the window opens on: mon, tue, wed, thu, fri, sat or sun (required).
+	Days []string `json:"days"              binding:"required" example:"mon,tue,wed,thu,fri"`
+	// UserID contains the user the policy applies to; omit for a global policy.
+	UserID uuid.UUID `json:"user_id,omitzero"                   example:"123e4567-e89b-12d3-a456-426614174001"`
+}
+
+// DeleteAccessPolicyRequest represents the request to delete an access policy.
+type DeleteAccessPolicyRequest struct {
+	// ID contains the policy identifier (required UUID format).
+	ID string `uri:"id" binding:"required" example:"123e4567-e89b-12d3-a456-426614174000"`
+}
+
+// ListAccessPoliciesResponse represents the response containing access policies.
+type ListAccessPoliciesResponse struct {
+	// Policies contains the matching access policies.
+	Policies []*AccessPolicy `json:"policies"`
+}
+
+// AddAccessPolicyResponse represents the response after creating an access policy.
+type AddAccessPolicyResponse struct {
+	// Policy contains the created access policy.
+	Policy *AccessPolicy `json:"policy"`
+}
+
+// MaintenanceStatus represents the read-only maintenance mode.
+type MaintenanceStatus struct {
+	// Since contains the moment the mode was last switched; omitted when it was set at startup.
+	Since time.Time `json:"since,omitzero" example:"2023-12-01T10:00:00Z"`
+	// Enabled determines whether mutating requests are rejected.
+	Enabled bool `json:"enabled"        example:"true"`
+}
+
+// NewMaintenanceStatusFromApp converts the application layer maintenance state to delivery DTO.
+func NewMaintenanceStatusFromApp(s maintenance.State) MaintenanceStatus {
+	return MaintenanceStatus{Enabled: s.Enabled, Since: s.Since}
+}
+
+// SetMaintenanceRequest represents the request to switch maintenance mode.
+type SetMaintenanceRequest struct {
+	// Enabled determines whether maintenance mode is switched on (required).
+	Enabled *bool `json:"enabled" binding:"required" example:"true"`
+}
+
+// FeatureFlag represents a stored feature flag.
+type FeatureFlag struct {
+	// UpdatedAt contains the moment the flag was last set.
+	UpdatedAt time.Time `json:"updated_at"        example:"2023-12-01T10:00:00Z"`
+	// Key contains the feature key.
+	Key string `json:"key"               example:"sync_v2"`
+	// UserID contains the user the flag applies to; omitted for deployment-wide flags.
+	UserID uuid.UUID `json:"user_id,omitzero"  example:"123e4567-e89b-12d3-a456-426614174001"`
+	// Enabled determines whether the feature is switched on.
+	Enabled bool `json:"enabled"           example:"true"`
+}
+
+// NewFeatureFlagFromApp converts an application layer feature flag to delivery DTO.
+func NewFeatureFlagFromApp(f *feature.Override) *FeatureFlag {
+	if f == nil {
+		return nil
+	}
+	return &FeatureFlag{
+		Key:       f.Key,
+		UserID:    f.UserID,
+		Enabled:   f.Enabled,
+		UpdatedAt: f.UpdatedAt,
+	}
+}
+
+// NewFeatureFlagsFromApp converts a slice of application layer feature flags to delivery DTOs.
+func NewFeatureFlagsFromApp(flags []*feature.Override) []*FeatureFlag {
+	if flags == nil {
+		return nil
+	}
+	result := make([]*FeatureFlag, 0, len(flags))
+	for _, f := range flags {
+		result = append(result, NewFeatureFlagFromApp(f))
+	}
+	return result
+}
+
+// ListFeatureFlagsRequest represents the filter of the feature flag listing.
+// Without filters all flags are listed.
+type ListFeatureFlagsRequest struct {
+	// UserID selects the flags of the specified user.
+	UserID string `form:"user_id" example:"123e4567-e89b-12d3-a456-426614174001"`
+	// Global selects the flags applying to all users.
+	Global bool `form:"global"  example:"true"`
+}
+
+// FeatureFlagKeyRequest represents the feature addressed by a feature flag request.
+type FeatureFlagKeyRequest struct {
+	// Key contains the feature key (required).
+	Key string `uri:"key" binding:"required" example:"sync_v2"`
+}
+
+// SetFeatureFlagRequest represents the data required to set a feature flag.
+type SetFeatureFlagRequest struct {
+	// Enabled determines whether the feature is switched on (required).
+	Enabled *bool `json:"enabled"           binding:"required" example:"true"`
+	// UserID contains the user the flag applies to; omit for a deployment-wide flag.
+	UserID uuid.UUID `json:"user_id,omitzero"                    example:"123e4567-e89b-12d3-a456-426614174001"`
+}
+
+// DeleteFeatureFlagRequest represents the owner of the feature flag to delete.
+type DeleteFeatureFlagRequest struct {
+	// UserID selects the flag of the specified user; omit for the deployment-wide flag.
+	UserID string `form:"user_id" example:"123e4567-e89b-12d3-a456-426614174001"`
+}
+
+// ListFeatureFlagsResponse represents the response containing feature flags.
+type ListFeatureFlagsResponse struct {
+	// Flags contains the matching feature flags.
+	Flags []*FeatureFlag `json:"flags"`
+}
+
+// SetFeatureFlagResponse represents the response after setting a feature flag.
+type SetFeatureFlagResponse struct {
+	// Flag contains the stored feature flag.
+	Flag *FeatureFlag `json:"flag"`
+}
+
+// CipherThroughput represents the measured speed of a single cipher.
+type CipherThroughput struct {
+	// Cipher identifies the measured cipher.
+	Cipher string `json:"cipher"             example:"aes-gcm"`
+	// EncryptMBPerSec contains the encryption speed in megabytes per second.
+	EncryptMBPerSec float64 `json:"encrypt_mb_per_sec" example:"2048.5"`
+	// DecryptMBPerSec contains the decryption speed in megabytes per second.
+	DecryptMBPerSec float64 `json:"decrypt_mb_per_sec" example:"2150.3"`
+}
+
+// CryptoDiagnostics represents the cipher selection and measured throughput of the instance.
+type CryptoDiagnostics struct {
+	// DefaultCipher identifies the cipher used to seal stored data.
+	DefaultCipher string `json:"default_cipher" example:"aes-gcm"`
+	// Results contains the measured throughput of every supported cipher.
+	Results []CipherThroughput `json:"results"`
+	// PayloadSize contains the size in bytes of the payloads encrypted during measurement.
+	PayloadSize int `json:"payload_size"   example:"1048576"`
+	// AESHardware determines whether the CPU accelerates AES-GCM.
+	AESHardware bool `json:"aes_hardware"   example:"true"`
+}
+
+// NewCryptoDiagnosticsFromApp converts the application layer crypto report to delivery DTO.
+func NewCryptoDiagnosticsFromApp(r *diagnostics.CryptoReport) *CryptoDiagnostics {
+	if r == nil {
+		return nil
+	}
+	results := make([]CipherThroughput, 0, len(r.Results))
+	for _, t := range r.Results {
+		results = append(results, CipherThroughput{
+			Cipher:          t.Cipher,
+			EncryptMBPerSec: t.EncryptMBPerSec,
+			DecryptMBPerSec: t.DecryptMBPerSec,
+		})
+	}
+	return &CryptoDiagnostics{
+		AESHardware:   r.AESHardware,
+		DefaultCipher: r.DefaultCipher,
+		PayloadSize:   r.PayloadSize,
+		Results:       results,
+	}
+}
+
+// OrphanBlob represents stored file content without file metadata.
+type OrphanBlob struct {
+	// ModTime contains the time the content was last written.
+	ModTime time.Time `json:"mod_time"    example:"2025-01-01T12:00:00Z"`
+	// StorageKey contains the path of the content within the user directory.
+	StorageKey string `json:"storage_key" example:"docs/report.pdf"`
+	// Size contains the stored (encrypted) size of the content in bytes.
+	Size int64 `json:"size"        example:"1048604"`
+	// UserID contains the user directory the content is stored in.
+	UserID uuid.UUID `json:"user_id"     example:"550e8400-e29b-41d4-a716-446655440000"`
+	// Removed determines whether the content was removed.
+	Removed bool `json:"removed"     example:"false"`
+}
+
+// MissingBlob represents file metadata without stored content.
+type MissingBlob struct {
+	// StorageKey contains the storage key recorded in the metadata.
+	StorageKey string `json:"storage_key" example:"docs/report.pdf"`
+	// FileID contains the file metadata identifier.
+	FileID uuid.UUID `json:"file_id"     example:"550e8400-e29b-41d4-a716-446655440001"`
+	// UserID contains the owner of the file.
+	UserID uuid.UUID `json:"user_id"     example:"550e8400-e29b-41d4-a716-446655440000"`
+}
+
+// StorageReport represents the result of a file storage reconciliation.
+type StorageReport struct {
+	// CheckedAt contains the time the reconciliation started.
+	CheckedAt time.Time `json:"checked_at"    example:"2025-01-01T12:00:00Z"`
+	// OrphanBlobs contains the stored contents without file metadata.
+	OrphanBlobs []OrphanBlob `json:"orphan_blobs"`
+	// MissingBlobs contains the file metadata without stored content.
+	MissingBlobs []MissingBlob `json:"missing_blobs"`
+	// SkippedUsers contains the users whose file metadata could not be loaded.
+	SkippedUsers []uuid.UUID `json:"skipped_users"`
+	// Blobs contains the number of stored contents scanned.
+	Blobs int `json:"blobs"         example:"120"`
+	// Files contains the number of file metadata rows checked.
+	Files int `json:"files"         example:"118"`
+}
+
+// NewStorageReportFromApp converts the application layer reconciliation report to delivery DTO.
+func NewStorageReportFromApp(r *storagegc.Report) *StorageReport {
+	if r == nil {
+		return nil
+	}
+	orphans := make([]OrphanBlob, 0, len(r.OrphanBlobs))
+	for _, o := range r.OrphanBlobs {
+		orphans = append(orphans, OrphanBlob(o))
+	}
+	missing := make([]MissingBlob, 0, len(r.MissingBlobs))
+	for _, m := range r.MissingBlobs {
+		missing = append(missing, MissingBlob(m))
+	}
+	skipped := r.SkippedUsers
+	if skipped == nil {
+		skipped = []uuid.UUID{}
+	}
+	return &StorageReport{
+		CheckedAt:    r.CheckedAt,
+		OrphanBlobs:  orphans,
+		MissingBlobs: missing,
+		SkippedUsers: skipped,
+		Blobs:        r.Blobs,
+		Files:        r.Files,
+	}
+}
+
+// StatsRequest represents the period of the usage statistics.
+type StatsRequest struct {
+	// From selects the rollups taken at or after this moment; empty reports the last seven days.
+	From time.Time `form:"from" example:"2025-01-01T00:00:00Z"`
+	// To selects the rollups taken before this moment; empty reports up to now.
+	To time.Time `form:"to"   example:"2025-01-08T00:00:00Z"`
+}
+
+// StatsRollup represents a snapshot of the aggregate usage of the instance.
+type StatsRollup struct {
+	// RolledUpAt contains the time the snapshot was taken.
+	RolledUpAt time.Time `json:"rolled_up_at"         example:"2025-01-01T12:00:00Z"`
+	// Items contains the number of stored items by item type.
+	Items map[string]int64 `json:"items"`
+	// StorageTiers contains the number of users by storage usage tier.
+	StorageTiers map[string]int64 `json:"storage_tiers"`
+	// Users contains the number of registered users.
+	Users int64 `json:"users"                example:"120"`
+	// DailyActiveUsers contains the number of users who signed in during the last day.
+	DailyActiveUsers int64 `json:"daily_active_users"   example:"35"`
+	// MonthlyActiveUsers contains the number of users who signed in during the last 30 days.
+	MonthlyActiveUsers int64 `json:"monthly_active_users" example:"98"`
+	// StorageBytes contains the total size of stored file content.
+	StorageBytes int64 `json:"storage_bytes"        example:"1073741824"`
+	// Requests contains the number of API requests handled since the previous rollup.
+	Requests int64 `json:"requests"             example:"5400"`
+	// ClientErrors contains the number of requests answered with a 4xx status since the previous rollup.
+	ClientErrors int64 `json:"client_errors"        example:"42"`
+	// ServerErrors contains the number of requests answered with a 5xx status since the previous rollup.
+	ServerErrors int64 `json:"server_errors"        example:"3"`
+	// SyncRequests contains the number of vault synchronization requests since the previous rollup.
+	SyncRequests int64 `json:"sync_requests"        example:"800"`
+	// SyncBytes contains the bytes transferred by vault synchronization requests since the previous rollup.
+	SyncBytes int64 `json:"sync_bytes"           example:"52428800"`
+	// ErrorRate contains the share of requests answered with a 5xx status.
+	ErrorRate float64 `json:"error_rate"           example:"0.0006"`
+}
+
+// NewStatsRollupFromApp converts the application layer rollup to delivery DTO.
+func NewStatsRollupFromApp(r *stats.Rollup) *StatsRollup {
+	if r == nil {
+		return nil
+	}
+	return &StatsRollup{
+		RolledUpAt:         r.RolledUpAt,
+		Items:              r.Items,
+		StorageTiers:       r.StorageTiers,
+		Users:              r.Users,
+		DailyActiveUsers:   r.DailyActiveUsers,
+		MonthlyActiveUsers: r.MonthlyActiveUsers,
+		StorageBytes:       r.StorageBytes,
+		Requests:           r.Requests,
+		ClientErrors:       r.ClientErrors,
+		ServerErrors:       r.ServerErrors,
+		SyncRequests:       r.SyncRequests,
+		SyncBytes:          r.SyncBytes,
+		ErrorRate:          r.ErrorRate(),
+	}
+}
+
+// StatsReport represents the usage statistics of a period.
+type StatsReport struct {
+	// From contains the start of the reported period.
+	From time.Time `json:"from"          example:"2025-01-01T00:00:00Z"`
+	// To contains the end of the reported period.
+	To time.Time `json:"to"            example:"2025-01-08T00:00:00Z"`
+	// Latest contains the most recent rollup of the period; omitted when the period has none.
+	Latest *StatsRollup `json:"latest,omitempty"`
+	// Rollups contains the rollups of the period, oldest first.
+	Rollups []*StatsRollup `json:"rollups"`
+	// Requests contains the number of API requests handled during the period.
+	Requests int64 `json:"requests"      example:"37800"`
+	// ClientErrors contains the number of requests answered with a 4xx status during the period.
+	ClientErrors int64 `json:"client_errors" example:"294"`
+	// ServerErrors contains the number of requests answered with a 5xx status during the period.
+	ServerErrors int64 `json:"server_errors" example:"21"`
+	// SyncRequests contains the number of vault synchronization requests during the period.
+	SyncRequests int64 `json:"sync_requests" example:"5600"`
+	// SyncBytes contains the bytes transferred by vault synchronization requests during the period.
+	SyncBytes int64 `json:"sync_bytes"    example:"367001600"`
+}
+
+// NewStatsReportFromApp converts the application layer usage statistics to delivery DTO.
+func NewStatsReportFromApp(r *stats.Report) *StatsReport {
+	if r == nil {
+		return nil
+	}
+	rollups := make([]*StatsRollup, 0, len(r.Rollups))
+	for _, rollup := range r.Rollups {
+		rollups = append(rollups, NewStatsRollupFromApp(rollup))
+	}
+	return &StatsReport{
+		From:         r.From,
+		To:           r.To,
+		Latest:       NewStatsRollupFromApp(r.Latest),
+		Rollups:      rollups,
+		Requests:     r.Requests,
+		ClientErrors: r.ClientErrors,
+		ServerErrors: r.ServerErrors,
+		SyncRequests: r.SyncRequests,
+		SyncBytes:    r.SyncBytes,
+	}
+}
+
+// LicenseStatus represents the installed enterprise license and the features available now.
+type LicenseStatus struct {
+	// IssuedAt contains the time the license was issued; omitted without a license.
+	IssuedAt time.Time `json:"issued_at,omitzero"   example:"2025-01-01T00:00:00Z"`
+	// ExpiresAt contains the time the license expires; omitted for perpetual licenses.
+	ExpiresAt time.Time `json:"expires_at,omitzero"  example:"2026-01-01T00:00:00Z"`
+	// GraceUntil contains the time the features of the expired license are switched off; omitted for perpetual
+	// licenses.
+	GraceUntil time.Time `json:"grace_until,omitzero" example:"2026-01-15T00:00:00Z"`
+	// ID identifies the license; omitted without a license.
+	ID string `json:"id,omitempty"         example:"lic-2025-0042"`
+	// Licensee names the organization the license is issued to; omitted without a license.
+	Licensee string `json:"licensee,omitempty"   example:"Example Corp"`
+	// State contains the state of the license: valid, grace, expired or none.
+	State string `json:"state"                example:"valid"`
+	// Features lists the features the license covers.
+	Features []string `json:"features"             example:"sso,scim"`
+	// Available lists the enterprise features available now.
+	Available []string `json:"available"            example:"sso,scim"`
+	// Enforced determines whether enterprise features require a license.
+	Enforced bool `json:"enforced"             example:"true"`
+}
+
+// NewLicenseStatusFromApp converts the application layer license status to delivery DTO.
+func NewLicenseStatusFromApp(s *license.Status) *LicenseStatus {
+	if s == nil {
+		return nil
+	}
+	features := s.Features
+	if features == nil {
+		features = []string{}
+	}
+	return &LicenseStatus{
+		IssuedAt:   s.IssuedAt,
+		ExpiresAt:  s.ExpiresAt,
+		GraceUntil: s.GraceUntil,
+		ID:         s.ID,
+		Licensee:   s.Licensee,
+		State:      s.State,
+		Features:   features,
+		Available:  s.Available,
+		Enforced:   s.Enforced,
+	}
+}
+
+// InstallLicenseRequest represents the request to install an enterprise license key.
+type InstallLicenseRequest struct {
+	// Key contains the license key as issued (required).
+	Key string `json:"key" binding:"required" example:"AVK1.eyJpZCI6...Ig.3q2-7w..."`
+}
+
+// TelemetryPreview represents the anonymous usage ping of the deployment.
+type TelemetryPreview struct {
+	// LastSentAt contains the time this server instance last sent a ping; omitted if it has not.
+	LastSentAt time.Time `json:"last_sent_at,omitzero" example:"2026-10-15T12:00:00Z"`
+	// Endpoint contains the URL pings are posted to.
+	Endpoint string `json:"endpoint"               example:"https://telemetry.example.com/v1/ping"`
+	// Payload contains the exact JSON body of the ping.
+	Payload json.RawMessage `json:"payload"                swaggertype:"object"`
+	// IntervalSeconds contains the period between pings in seconds.
+	IntervalSeconds int64 `json:"interval_seconds"       example:"86400"`
+	// Enabled determines whether pings are sent.
+	Enabled bool `json:"enabled"                example:"false"`
+}
+
+// NewTelemetryPreviewFromApp converts the application layer telemetry preview to delivery DTO.
+func NewTelemetryPreviewFromApp(p *telemetry.Preview) *TelemetryPreview {
+	if p == nil {
+		return nil
+	}
+	return &TelemetryPreview{
+		LastSentAt:      p.LastSentAt,
+		Endpoint:        p.Endpoint,
+		Payload:         p.Payload,
+		IntervalSeconds: int64(p.Interval.Seconds()),
+		Enabled:         p.Enabled,
+	}
+}
+
+// UpdateStatus represents the result of the latest server update check.
+type UpdateStatus struct {
+	// CheckedAt contains the time the release manifest was last verified; omitted before the first check.
+	CheckedAt time.Time `json:"checked_at,omitzero"  example:"2026-10-15T12:00:00Z"`
+	// ReleasedAt contains the time the latest release was published; omitted before the first check.
+	ReleasedAt time.Time `json:"released_at,omitzero" example:"2026-10-01T00:00:00Z"`
+	// Current contains the version of the running build.
+	Current string `json:"current"              example:"v1.4.2"`
+	// Latest contains the version of the latest release; omitted before the first check.
+	Latest string `json:"latest,omitempty"     example:"v1.5.0"`
+	// URL contains the page the latest release is downloaded from; omitted before the first check.
+	URL string `json:"url,omitempty"        example:"https://example.com/releases/v1.5.0"`
+	// Notes contains a summary of the changes in the latest release.
+	Notes string `json:"notes,omitempty"      example:"Telemetry and update checks"`
+	// Available determines whether the latest release is newer than the running build.
+	Available bool `json:"available"            example:"true"`
+	// Enabled determines whether updates are checked.
+	Enabled bool `json:"enabled"              example:"true"`
+}
+
+// NewUpdateStatusFromApp converts the application layer update status to delivery DTO.
+func NewUpdateStatusFromApp(s *updatecheck.Status) *UpdateStatus {
+	if s == nil {
+		return nil
+	}
+	return &UpdateStatus{
+		CheckedAt:  s.CheckedAt,
+		ReleasedAt: s.ReleasedAt,
+		Current:    s.Current,
+		Latest:     s.Latest,
+		URL:        s.URL,
+		Notes:      s.Notes,
+		Available:  s.Available,
+		Enabled:    s.Enabled,
+	}
+}
+
+// Recording represents a request recording.
+type Recording struct {
+	// StartedAt contains the time the recording started.
+	StartedAt time.Time `json:"started_at"       example:"2026-10-15T12:00:00Z"`
+	// ExpiresAt contains the time the recording stops capturing requests.
+	ExpiresAt time.Time `json:"expires_at"       example:"2026-10-15T12:30:00Z"`
+	// Route contains the path prefix of the recorded requests; omitted when every path is recorded.
+	Route string `json:"route,omitempty"  example:"/api/items/bankcards"`
+	// Reason explains why the recording was started.
+	Reason string `json:"reason,omitempty" example:"Support case 4211"`
+	// ID contains the unique recording identifier.
+	ID uuid.UUID `json:"id"               example:"123e4567-e89b-12d3-a456-426614174000"`
+	// UserID contains the user whose requests are recorded; omitted when every user is recorded.
+	UserID uuid.UUID `json:"user_id,omitzero" example:"123e4567-e89b-12d3-a456-426614174001"`
+	// Exchanges contains the number of captured requests.
+	Exchanges int `json:"exchanges"        example:"12"`
+	// Active determines whether the recording is capturing requests.
+	Active bool `json:"active"           example:"true"`
+}
+
+// NewRecordingFromApp converts an application layer recording to delivery DTO.
+func NewRecordingFromApp(s *recording.Session) *Recording {
+	if s == nil {
+		return nil
+	}
+	return &Recording{
+		StartedAt: s.StartedAt,
+		ExpiresAt: s.ExpiresAt,
+		Route:     s.Route,
+		Reason:    s.Reason,
+		ID:        s.ID,
+		UserID:    s.UserID,
+		Exchanges: s.Exchanges,
+		Active:    s.Active,
+	}
+}
+
+// NewRecordingsFromApp converts a slice of application layer recordings to delivery DTOs.
+func NewRecordingsFromApp(sessions []*recording.Session) []*Recording {
+	result := make([]*Recording, 0, len(sessions))
+	for _, s := range sessions {
+		result = append(result, NewRecordingFromApp(s))
+	}
+	return result
+}
+
+// StartRecordingRequest represents the data required to start a request recording.
+// At least one of user_id or route must be set.
+type StartRecordingRequest struct {
+	// Route contains the path prefix of the requests to record; omit to record every path of the user.
+	Route string `json:"route,omitzero"                      example:"/api/items/bankcards"`
+	// Reason explains why the recording is started, such as a support case reference.
+	Reason string `json:"reason,omitzero"                     example:"Support case 4211"`
+	// UserID contains the user whose requests are recorded; omit to record every user on the route.
+	UserID uuid.UUID `json:"user_id,omitzero"                    example:"123e4567-e89b-12d3-a456-426614174001"`
+	// DurationSeconds contains the time window of the recording in seconds (required).
+	DurationSeconds int64 `json:"duration_seconds" binding:"required" example:"1800"`
+}
+
+// RecordingIDRequest represents the recording addressed by a request.
+type RecordingIDRequest struct {
+	// ID contains the recording identifier (required UUID format).
+	ID string `uri:"id" binding:"required" example:"123e4567-e89b-12d3-a456-426614174000"`
+}
+
+// ListRecordingsResponse represents the response containing request recordings.
+type ListRecordingsResponse struct {
+	// Recordings contains the recordings, most recent first.
+	Recordings []*Recording `json:"recordings"`
+}
+
+// RecordedExchange represents a captured request and its response with sensitive values redacted.
+type RecordedExchange struct {
+	// RecordedAt contains the time the request was received.
+	RecordedAt time.Time `json:"recorded_at"                example:"2026-10-15T12:05:00Z"`
+	// RequestHeaders contains the request headers; credentials are redacted.
+	RequestHeaders map[string]string `json:"request_headers,omitempty"`
+	// ResponseHeaders contains the response headers; credentials are redacted.
+	ResponseHeaders map[string]string `json:"response_headers,omitempty"`
+	// RequestID identifies the request in the server logs.
+	RequestID string `json:"request_id,omitempty"       example:"f3b2c1d0-1234-4a5b-8c9d-0e1f2a3b4c5d"`
+	// Method contains the HTTP method of the request.
+	Method string `json:"method"                     example:"POST"`
+	// Path contains the URL path of the request.
+	Path string `json:"path"                       example:"/api/items/bankcards"`
+	// Route contains the route pattern that handled the request.
+	Route string `json:"route,omitempty"            example:"/api/items/bankcards"`
+	// Query contains the query string of the request; values that may be sensitive are redacted.
+	Query string `json:"query,omitempty"            example:"limit=10"`
+	// RequestBody contains the request body; values that may be sensitive are redacted.
+	RequestBody string `json:"request_body,omitempty"     example:"{\"number\":\"[REDACTED]\"}"`
+	// ResponseBody contains the response body; values that may be sensitive are redacted.
+	ResponseBody string `json:"response_body,omitempty"    example:"{\"messages\":[\"card has expired\"]}"`
+	// ID contains the unique identifier of the captured request.
+	ID uuid.UUID `json:"id"                         example:"123e4567-e89b-12d3-a456-426614174002"`
+	// UserID contains the authenticated user; omitted for anonymous requests.
+	UserID uuid.UUID `json:"user_id,omitzero"           example:"123e4567-e89b-12d3-a456-426614174001"`
+	// DurationMS contains how long the request took to handle in milliseconds.
+	DurationMS int64 `json:"duration_ms"                example:"42"`
+	// Status contains the HTTP status code of the response.
+	Status int `json:"status"                     example:"400"`
+}
+
+// NewRecordedExchangesFromApp converts a slice of application layer captured requests to delivery DTOs.
+func NewRecordedExchangesFromApp(exchanges []*recording.Exchange) []*RecordedExchange {
+	result := make([]*RecordedExchange, 0, len(exchanges))
+	for _, e := range exchanges {
+		result = append(result, &RecordedExchange{
+			RecordedAt:      e.RecordedAt,
+			RequestHeaders:  e.RequestHeaders,
+			ResponseHeaders: e.ResponseHeaders,
+			RequestID:       e.RequestID,
+			Method:          e.Method,
+			Path:            e.Path,
+			Route:           e.Route,
+			Query:           e.Query,
+			RequestBody:     e.RequestBody,
+			ResponseBody:    e.ResponseBody,
+			ID:              e.ID,
+			UserID:          e.UserID,
+			DurationMS:      e.Duration.Milliseconds(),
+			Status:          e.Status,
+		})
+	}
+	return result
+}
+
+// ListRecordedExchangesResponse represents the response containing the requests captured by a recording.
+type ListRecordedExchangesResponse struct {
+	// Exchanges contains the captured requests in the order they were received.
+	Exchanges []*RecordedExchange `json:"exchanges"`
+}
+
+// RetentionPolicy represents data retention rules; a zero rule keeps the data forever.
+type RetentionPolicy struct {
+	// KeepVersions specifies how many replaced versions of every item are kept.
+	KeepVersions int `json:"keep_versions"   example:"10"`
+	// KeepAuditDays specifies for how many days item reveal records are kept.
+	KeepAuditDays int `json:"keep_audit_days" example:"365"`
+}
+
+// RetentionOverride represents the retention policy of a directory group.
+type RetentionOverride struct {
+	// UpdatedAt contains the moment the policy was last set.
+	UpdatedAt time.Time `json:"updated_at" example:"2023-12-01T10:00:00Z"`
+	// RetentionPolicy contains the rules applied to the members of the group.
+	RetentionPolicy
+	// GroupID contains the group the policy applies to.
+	GroupID uuid.UUID `json:"group_id"   example:"123e4567-e89b-12d3-a456-426614174000"`
+}
+
+// NewRetentionOverrideFromApp converts an application layer retention override to delivery DTO.
+func NewRetentionOverrideFromApp(o *retention.Override) *RetentionOverride {
+	if o == nil {
+		return nil
+	}
+	return &RetentionOverride{
+		GroupID:         o.GroupID,
+		RetentionPolicy: RetentionPolicy{KeepVersions: o.KeepVersions, KeepAuditDays: o.KeepAuditDays},
+		UpdatedAt:       o.UpdatedAt,
+	}
+}
+
+// RetentionPolicies represents the default retention policy with the overrides of directory groups.
+type RetentionPolicies struct {
+	// Overrides contains the policies of directory groups ordered by group ID.
+	Overrides []*RetentionOverride `json:"overrides"`
+	// Defaults contains the policy of users in no group with a policy.
+	Defaults RetentionPolicy `json:"defaults"`
+}
+
+// NewRetentionPoliciesFromApp converts the application layer retention policies to delivery DTO.
+func NewRetentionPoliciesFromApp(p *retention.Policies) *RetentionPolicies {
+	overrides := make([]*RetentionOverride, 0, len(p.Overrides))
+	for _, o := range p.Overrides {
+		overrides = append(overrides, NewRetentionOverrideFromApp(o))
+	}
+	return &RetentionPolicies{
+		Defaults:  RetentionPolicy{KeepVersions: p.Defaults.KeepVersions, KeepAuditDays: p.Defaults.KeepAuditDays},
+		Overrides: overrides,
+	}
+}
+
+// RetentionGroupIDRequest represents the directory group addressed by a retention request.
+type RetentionGroupIDRequest struct {
+	// ID contains the group identifier (required UUID format).
+	ID string `uri:"id" binding:"required" example:"123e4567-e89b-12d3-a456-426614174000"`
+}
+
+// SetRetentionOverrideRequest represents the retention policy to apply to a directory group.
+type SetRetentionOverrideRequest struct {
+	// KeepVersions specifies how many replaced versions of every item are kept; 0 keeps all.
+	KeepVersions int `json:"keep_versions"   example:"10"`
+	// KeepAuditDays specifies for how many days item reveal records are kept; 0 keeps them forever.
+	KeepAuditDays int `json:"keep_audit_days" example:"365"`
+}
+
+// RetentionScope represents the data of the users sharing one effective policy that the policy does not keep.
+type RetentionScope struct {
+	// Policy contains the effective policy of the users.
+	Policy RetentionPolicy `json:"policy"`
+	// Users contains the number of users with group policies it applies to; omitted for the defaults.
+	Users int `json:"users,omitempty" example:"12"`
+	// Versions contains the number of replaced item versions the policy would purge.
+	Versions int64 `json:"versions"        example:"340"`
+	// AuditRecords contains the number of item reveal records the policy would purge.
+	AuditRecords int64 `json:"audit_records"   example:"1200"`
+	// Default reports whether the scope covers the users without group policies.
+	Default bool `json:"default"         example:"false"`
+}
+
+// RetentionPreview represents the data the retention policies would purge if enforced now.
+type RetentionPreview struct {
+	// Scopes contains the data of every effective policy, the defaults first.
+	Scopes []*RetentionScope `json:"scopes"`
+	// Versions contains the total number of replaced item versions that would be purged.
+	Versions int64 `json:"versions"      example:"340"`
+	// AuditRecords contains the total number of item reveal records that would be purged.
+	AuditRecords int64 `json:"audit_records" example:"1200"`
+}
+
+// NewRetentionPreviewFromApp converts an application layer retention report to delivery DTO.
+func NewRetentionPreviewFromApp(r *retention.Report) *RetentionPreview {
+	scopes := make([]*RetentionScope, 0, len(r.Scopes))
+	for _, s := range r.Scopes {
+		scopes = append(scopes, &RetentionScope{
+			Policy:       RetentionPolicy{KeepVersions: s.KeepVersions, KeepAuditDays: s.KeepAuditDays},
+			Users:        s.Users,
+			Versions:     s.Versions,
+			AuditRecords: s.AuditRecords,
+			Default:      s.Default,
+		})
+	}
+	return &RetentionPreview{Scopes: scopes, Versions: r.Versions, AuditRecords: r.AuditRecords}
+}
+
+// LegalHold represents a legal hold keeping the data of a user, or a single item, from being deleted.
+type LegalHold struct {
+	// PlacedAt contains the moment the hold was placed.
+	PlacedAt time.Time `json:"placed_at"         example:"2023-12-01T10:00:00Z"`
+	// Reason describes the matter the data is preserved for.
+	Reason string `json:"reason"            example:"Litigation 2023-117"`
+	// ID contains the hold identifier.
+	ID uuid.UUID `json:"id"                example:"123e4567-e89b-12d3-a456-426614174000"`
+	// UserID contains the user whose data is held.
+	UserID uuid.UUID `json:"user_id"           example:"123e4567-e89b-12d3-a456-426614174001"`
+	// ItemID contains the held item; omitted when every item of the user is held.
+	ItemID uuid.UUID `json:"item_id,omitzero"  example:"123e4567-e89b-12d3-a456-426614174002"`
+}
+
+// NewLegalHoldFromApp converts an application layer legal hold to delivery DTO.
+func NewLegalHoldFromApp(h *legalhold.Hold) *LegalHold {
+	if h == nil {
+		return nil
+	}
+	return &LegalHold{
+		ID:       h.ID,
+		UserID:   h.UserID,
+		ItemID:   h.ItemID,
+		Reason:   h.Reason,
+		PlacedAt: h.PlacedAt,
+	}
+}
+
+// NewLegalHoldsFromApp converts a slice of application layer legal holds to delivery DTOs.
+func NewLegalHoldsFromApp(hs []*legalhold.Hold) []*LegalHold {
+	result := make([]*LegalHold, 0, len(hs))
+	for _, h := range hs {
+		result = append(result, NewLegalHoldFromApp(h))
+	}
+	return result
+}
+
+// ListLegalHoldsRequest represents the filter of the legal hold listing.
+type ListLegalHoldsRequest struct {
+	// UserID selects the holds on the data of the specified user.
+	UserID string `form:"user_id" example:"123e4567-e89b-12d3-a456-426614174001"`
+}
+
+// ListLegalHoldsResponse represents the response containing legal holds.
+type ListLegalHoldsResponse struct {
+	// Holds contains the legal holds ordered by placement time.
+	Holds []*LegalHold `json:"holds"`
+}
+
+// PlaceLegalHoldRequest represents the data required to place a legal hold.
+type PlaceLegalHoldRequest struct {
+	// Reason describes the matter the data is preserved for (required).
+	Reason string `json:"reason"           binding:"required" example:"Litigation 2023-117"`
+	// UserID contains the user whose data is held (required).
+	UserID uuid.UUID `json:"user_id"                            example:"123e4567-e89b-12d3-a456-426614174001"`
+	// ItemID contains the held item; omit to hold every item of the user.
+	ItemID uuid.UUID `json:"item_id,omitzero"                   example:"123e4567-e89b-12d3-a456-426614174002"`
+}
+
+// LegalHoldIDRequest represents the legal hold addressed by a request.
+type LegalHoldIDRequest struct {
+	// ID contains the hold identifier (required UUID format).
+	ID string `uri:"id" binding:"required" example:"123e4567-e89b-12d3-a456-426614174000"`
+}
+
+// ItemPolicyRules represents the restrictions an item policy puts on vault items.
+type ItemPolicyRules struct {
+	// ForbiddenKinds lists the item types that may not be stored: bankcard, credential, file or note.
+	ForbiddenKinds []string `json:"forbidden_kinds"      example:"bankcard"`
+	// RequiredClasses lists the character classes every credential password must contain: lower, upper,
+	// digit or symbol.
+	RequiredClasses []string `json:"required_classes"     example:"digit,upper"`
+	// MinPasswordLength specifies the minimum length of credential passwords in characters; 0 sets none.
+	MinPasswordLength int `json:"min_password_length"  example:"12"`
+	// BanCommonPasswords determines whether commonly used credential passwords are rejected.
+	BanCommonPasswords bool `json:"ban_common_passwords" example:"true"`
+}
+
+// toApp converts the item policy rules to the application layer rules.
+func (r ItemPolicyRules) toApp() itempolicy.Rules {
+	return itempolicy.Rules{
+		ForbiddenKinds:     r.ForbiddenKinds,
+		RequiredClasses:    r.RequiredClasses,
+		MinPasswordLength:  r.MinPasswordLength,
+		BanCommonPasswords: r.BanCommonPasswords,
+	}
+}
+
+// ItemPolicy represents an item policy applying to every user or to the members of a directory group.
+type ItemPolicy struct {
+	// CreatedAt contains the moment the policy was created.
+	CreatedAt time.Time `json:"created_at"         example:"2023-12-01T10:00:00Z"`
+	// UpdatedAt contains the moment the policy was last changed.
+	UpdatedAt time.Time `json:"updated_at"         example:"2023-12-01T10:00:00Z"`
+	// Name describes the policy.
+	Name string `json:"name"               example:"Finance"`
+	// Rules contains the restrictions the policy puts on vault items.
+	Rules ItemPolicyRules `json:"rules"`
+	// ID contains the policy identifier.
+	ID uuid.UUID `json:"id"                 example:"123e4567-e89b-12d3-a456-426614174000"`
+	// GroupID contains the group whose members the policy applies to; omitted when it applies to every user.
+	GroupID uuid.UUID `json:"group_id,omitzero"  example:"123e4567-e89b-12d3-a456-426614174001"`
+}
+
+// NewItemPolicyFromApp converts an application layer item policy to delivery DTO.
+func NewItemPolicyFromApp(p *itempolicy.Policy) *ItemPolicy {
+	if p == nil {
+		return nil
+	}
+	return &ItemPolicy{
+		ID:      p.ID,
+		GroupID: p.GroupID,
+		Name:    p.Name,
+		Rules: ItemPolicyRules{
+			ForbiddenKinds:     append([]string{}, p.ForbiddenKinds...),
+			RequiredClasses:    append([]string{}, p.RequiredClasses...),
+			MinPasswordLength:  p.MinPasswordLength,
+			BanCommonPasswords: p.BanCommonPasswords,
+		},
+		CreatedAt: p.CreatedAt,
+		UpdatedAt: p.UpdatedAt,
+	}
+}
+
+// NewItemPoliciesFromApp converts a slice of application layer item policies to delivery DTOs.
+func NewItemPoliciesFromApp(ps []*itempolicy.Policy) []*ItemPolicy {
+	result := make([]*ItemPolicy, 0, len(ps))
+	for _, p := range ps {
+		result = append(result, NewItemPolicyFromApp(p))
+	}
+	return result
+}
+
+// ListItemPoliciesResponse represents the response containing item policies.
+type ListItemPoliciesResponse struct {
+	// Policies contains the item policies ordered by creation time.
+	Policies []*ItemPolicy `json:"policies"`
+}
+
+// ItemPolicyRequest represents the data required to create or replace an item policy.
+type ItemPolicyRequest struct {
+	// Name describes the policy (required).
+	Name string `json:"name"              binding:"required" example:"Finance"`
+	// Rules contains the restrictions the policy puts on vault items; at least one is required.
+	Rules ItemPolicyRules `json:"rules"`
+	// GroupID contains the group whose members the policy applies to; omit to apply it to every user.
+	GroupID uuid.UUID `json:"group_id,omitzero"                   example:"123e4567-e89b-12d3-a456-426614174001"`
+}
+
+// ItemPolicyIDRequest represents the item policy addressed by a request.
+type ItemPolicyIDRequest struct {
+	// ID contains the policy identifier (required UUID format).
+	ID string `uri:"id" binding:"required" example:"123e4567-e89b-12d3-a456-426614174000"`
+}
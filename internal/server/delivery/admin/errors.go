@@ -0,0 +1,556 @@
+package admin
+
+import (
+	"net/http"
+
+	app "github.com/gdyunin/aegis-vault-keeper/internal/server/application/accesscontrol"
+	policyApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/accesspolicy"
+	diagnosticsApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/diagnostics"
+	featureApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/feature"
+	itempolicyApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/itempolicy"
+	legalholdApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/legalhold"
+	licenseApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/license"
+	recordingApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/recording"
+	retentionApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/retention"
+	statsApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/stats"
+	storagegcApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/storagegc"
+	telemetryApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/telemetry"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/errutil"
+	"github.com/gin-gonic/gin"
+)
+
+// AdminErrRegistry defines error handling policies for administrative operations.
+var AdminErrRegistry = errutil.Registry{
+	{
+		ErrorIn: app.ErrAccessControlTechError,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusInternalServerError,
+			PublicMsg:  http.StatusText(http.StatusInternalServerError),
+			LogIt:      true,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassTech,
+		},
+	},
+	{
+		ErrorIn: app.ErrRuleNotFound,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusNotFound,
+			PublicMsg:  "Access rule not found",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassGeneric,
+		},
+	},
+	{
+		ErrorIn: app.ErrGeoIPUnavailable,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusBadRequest,
+			PublicMsg:  "Country rules require a configured GeoIP database",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+	{
+		ErrorIn: app.ErrRuleIncorrectAction,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusBadRequest,
+			PublicMsg:  "Action must be allow or deny",
+			LogIt:      false,
+			AllowMerge: true,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+	{
+		ErrorIn: app.ErrRuleIncorrectNetwork,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusBadRequest,
+			PublicMsg:  "Network must be a valid CIDR or IP address",
+			LogIt:      false,
+			AllowMerge: true,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+	{
+		ErrorIn: app.ErrRuleIncorrectCountry,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusBadRequest,
+			PublicMsg:  "Country must be an ISO 3166-1 alpha-2 code",
+			LogIt:      false,
+			AllowMerge: true,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+	{
+		ErrorIn: app.ErrRuleIncorrectTarget,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusBadRequest,
+			PublicMsg:  "Exactly one of network or country must be set",
+			LogIt:      false,
+			AllowMerge: true,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+	{
+		ErrorIn: app.ErrAccessControlAppError,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusBadRequest,
+			PublicMsg:  "Invalid parameters",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+	{
+		ErrorIn: featureApp.ErrFeatureTechError,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusInternalServerError,
+			PublicMsg:  http.StatusText(http.StatusInternalServerError),
+			LogIt:      true,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassTech,
+		},
+	},
+	{
+		ErrorIn: featureApp.ErrFlagNotFound,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusNotFound,
+			PublicMsg:  "Feature flag not found",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassGeneric,
+		},
+	},
+	{
+		ErrorIn: featureApp.ErrFlagIncorrectKey,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusBadRequest,
+			PublicMsg:  "Feature key must be up to 64 lowercase letters, digits, dots, dashes or underscores",
+			LogIt:      false,
+			AllowMerge: true,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+	{
+		ErrorIn: featureApp.ErrFeatureAppError,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusBadRequest,
+			PublicMsg:  "Invalid parameters",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+	{
+		ErrorIn: policyApp.ErrAccessPolicyTechError,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusInternalServerError,
+			PublicMsg:  http.StatusText(http.StatusInternalServerError),
+			LogIt:      true,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassTech,
+		},
+	},
+	{
+		ErrorIn: policyApp.ErrPolicyNotFound,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusNotFound,
+			PublicMsg:  "Access policy not found",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassGeneric,
+		},
+	},
+	{
+		ErrorIn: policyApp.ErrPolicyIncorrectDays,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusBadRequest,
+			PublicMsg:  "Days must list at least one of mon, tue, wed, thu, fri, sat or sun",
+			LogIt:      false,
+			AllowMerge: true,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+	{
+		ErrorIn: policyApp.ErrPolicyIncorrectTime,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusBadRequest,
+			PublicMsg:  "Start and end must be different HH:MM times of day",
+			LogIt:      false,
+			AllowMerge: true,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+	{
+		ErrorIn: policyApp.ErrPolicyIncorrectTimezone,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusBadRequest,
+			PublicMsg:  "Timezone must be an IANA time zone such as Europe/Berlin",
+			LogIt:      false,
+			AllowMerge: true,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+	{
+		ErrorIn: policyApp.ErrAccessPolicyAppError,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusBadRequest,
+			PublicMsg:  "Invalid access policy parameters",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+	{
+		ErrorIn: diagnosticsApp.ErrDiagnosticsTechError,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusInternalServerError,
+			PublicMsg:  http.StatusText(http.StatusInternalServerError),
+			LogIt:      true,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassTech,
+		},
+	},
+	{
+		ErrorIn: storagegcApp.ErrStorageGCTechError,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusInternalServerError,
+			PublicMsg:  http.StatusText(http.StatusInternalServerError),
+			LogIt:      true,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassTech,
+		},
+	},
+	{
+		ErrorIn: statsApp.ErrStatsTechError,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusInternalServerError,
+			PublicMsg:  http.StatusText(http.StatusInternalServerError),
+			LogIt:      true,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassTech,
+		},
+	},
+	{
+		ErrorIn: statsApp.ErrStatsPeriodInvalid,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusBadRequest,
+			PublicMsg:  "The period must end after it starts",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+	{
+		ErrorIn: licenseApp.ErrLicenseTechError,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusInternalServerError,
+			PublicMsg:  http.StatusText(http.StatusInternalServerError),
+			LogIt:      true,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassTech,
+		},
+	},
+	{
+		ErrorIn: licenseApp.ErrLicenseNotConfigured,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusConflict,
+			PublicMsg:  "License verification is not configured: set LICENSE_PUBLIC_KEY",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+	{
+		ErrorIn: licenseApp.ErrLicenseInvalid,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusBadRequest,
+			PublicMsg:  "Invalid license key",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+	{
+		ErrorIn: licenseApp.ErrLicenseExpired,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusBadRequest,
+			PublicMsg:  "The license has expired",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+	{
+		ErrorIn: telemetryApp.ErrTelemetryTechError,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusInternalServerError,
+			PublicMsg:  http.StatusText(http.StatusInternalServerError),
+			LogIt:      true,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassTech,
+		},
+	},
+	{
+		ErrorIn: recordingApp.ErrRecordingTechError,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusInternalServerError,
+			PublicMsg:  http.StatusText(http.StatusInternalServerError),
+			LogIt:      true,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassTech,
+		},
+	},
+	{
+		ErrorIn: recordingApp.ErrRecordingNotFound,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusNotFound,
+			PublicMsg:  "Recording not found",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassGeneric,
+		},
+	},
+	{
+		ErrorIn: recordingApp.ErrRecordingDisabled,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusConflict,
+			PublicMsg:  "Request recording is disabled: set RECORDING_MAX_WINDOW",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+	{
+		ErrorIn: recordingApp.ErrRecordingTargetRequired,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusBadRequest,
+			PublicMsg:  "A recording requires a user, a route or both",
+			LogIt:      false,
+			AllowMerge: true,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+	{
+		ErrorIn: recordingApp.ErrRecordingInvalidRoute,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusBadRequest,
+			PublicMsg:  "Route must be a path prefix starting with a slash",
+			LogIt:      false,
+			AllowMerge: true,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+	{
+		ErrorIn: recordingApp.ErrRecordingInvalidDuration,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusBadRequest,
+			PublicMsg:  "Duration must be positive and must not exceed RECORDING_MAX_WINDOW",
+			LogIt:      false,
+			AllowMerge: true,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+	{
+		ErrorIn: retentionApp.ErrRetentionTechError,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusInternalServerError,
+			PublicMsg:  http.StatusText(http.StatusInternalServerError),
+			LogIt:      true,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassTech,
+		},
+	},
+	{
+		ErrorIn: retentionApp.ErrGroupNotFound,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusNotFound,
+			PublicMsg:  "Group not found",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassGeneric,
+		},
+	},
+	{
+		ErrorIn: retentionApp.ErrOverrideNotFound,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusNotFound,
+			PublicMsg:  "Group has no retention policy",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassGeneric,
+		},
+	},
+	{
+		ErrorIn: retentionApp.ErrIncorrectKeepVersions,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusBadRequest,
+			PublicMsg:  "Kept versions must be between 0 and 10000",
+			LogIt:      false,
+			AllowMerge: true,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+	{
+		ErrorIn: retentionApp.ErrIncorrectKeepAuditDays,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusBadRequest,
+			PublicMsg:  "Days reveal records are kept must be between 0 and 36500",
+			LogIt:      false,
+			AllowMerge: true,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+	{
+		ErrorIn: retentionApp.ErrRetentionAppError,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusBadRequest,
+			PublicMsg:  "Invalid parameters",
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+	{
+		ErrorIn: legalholdApp.ErrLegalHoldTechError,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusInternalServerError,
+			PublicMsg:  http.StatusText(http.StatusInternalServerError),
+			LogIt:      true,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassTech,
+		},
+	},
+	{
+		ErrorIn: legalholdApp.ErrUserNotFound,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusNotFound,
+			PublicMsg:  "User not found",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassGeneric,
+		},
+	},
+	{
+		ErrorIn: legalholdApp.ErrHoldNotFound,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusNotFound,
+			PublicMsg:  "Legal hold not found",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassGeneric,
+		},
+	},
+	{
+		ErrorIn: legalholdApp.ErrIncorrectReason,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusBadRequest,
+			PublicMsg:  "Reason must be between 1 and 1000 characters",
+			LogIt:      false,
+			AllowMerge: true,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+	{
+		ErrorIn: legalholdApp.ErrLegalHoldAppError,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusBadRequest,
+			PublicMsg:  "Invalid parameters",
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+	{
+		ErrorIn: itempolicyApp.ErrItemPolicyTechError,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusInternalServerError,
+			PublicMsg:  http.StatusText(http.StatusInternalServerError),
+			LogIt:      true,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassTech,
+		},
+	},
+	{
+		ErrorIn: itempolicyApp.ErrGroupNotFound,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusNotFound,
+			PublicMsg:  "Group not found",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassGeneric,
+		},
+	},
+	{
+		ErrorIn: itempolicyApp.ErrPolicyNotFound,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusNotFound,
+			PublicMsg:  "Item policy not found",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassGeneric,
+		},
+	},
+	{
+		ErrorIn: itempolicyApp.ErrIncorrectName,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusBadRequest,
+			PublicMsg:  "Name must be between 1 and 100 characters",
+			LogIt:      false,
+			AllowMerge: true,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+	{
+		ErrorIn: itempolicyApp.ErrIncorrectKind,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusBadRequest,
+			PublicMsg:  "Forbidden kinds must be bankcard, credential, file or note",
+			LogIt:      false,
+			AllowMerge: true,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+	{
+		ErrorIn: itempolicyApp.ErrIncorrectPasswordClass,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusBadRequest,
+			PublicMsg:  "Required classes must be lower, upper, digit or symbol",
+			LogIt:      false,
+			AllowMerge: true,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+	{
+		ErrorIn: itempolicyApp.ErrIncorrectMinPasswordLength,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusBadRequest,
+			PublicMsg:  "Minimum password length must be between 0 and 128",
+			LogIt:      false,
+			AllowMerge: true,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+	{
+		ErrorIn: itempolicyApp.ErrNoRules,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusBadRequest,
+			PublicMsg:  "Item policy must set at least one rule",
+			LogIt:      false,
+			AllowMerge: true,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+	{
+		ErrorIn: itempolicyApp.ErrItemPolicyAppError,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusBadRequest,
+			PublicMsg:  "Invalid parameters",
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+}
+
+// handleError processes administrative errors using the registry and returns appropriate HTTP response.
+func handleError(err error, c *gin.Context) (int, []string) {
+	return errutil.HandleWithRegistry(AdminErrRegistry, err, c)
+}
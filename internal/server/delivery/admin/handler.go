@@ -0,0 +1,1562 @@
+package admin
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/accesscontrol"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/accesspolicy"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/diagnostics"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/feature"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/itempolicy"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/legalhold"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/license"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/maintenance"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/recording"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/retention"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/stats"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/storagegc"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/telemetry"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/updatecheck"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/response"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/util"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Service defines the administrative application service interface.
+type Service interface {
+	// ListRules retrieves the access rules matching the filter.
+	ListRules(context.Context, accesscontrol.ListRulesParams) ([]*accesscontrol.Rule, error)
+	// AddRule creates a new access rule.
+	AddRule(context.Context, accesscontrol.AddRuleParams) (*accesscontrol.Rule, error)
+	// DeleteRule removes an access rule.
+	DeleteRule(context.Context, accesscontrol.DeleteRuleParams) error
+}
+
+// MaintenanceService defines the maintenance mode switch interface.
+type MaintenanceService interface {
+	// Status returns the current maintenance mode.
+	Status(context.Context) maintenance.State
+	// Set switches maintenance mode and returns the resulting state.
+	Set(context.Context, maintenance.SetParams) maintenance.State
+}
+
+// FeatureService defines the feature flag management interface.
+type FeatureService interface {
+	// ListOverrides retrieves the stored feature flags matching the filter.
+	ListOverrides(context.Context, feature.ListOverridesParams) ([]*feature.Override, error)
+	// SetFlag stores a deployment-wide or per-user feature flag.
+	SetFlag(context.Context, feature.SetFlagParams) (*feature.Override, error)
+	// DeleteFlag removes a stored feature flag.
+	DeleteFlag(context.Context, feature.DeleteFlagParams) error
+}
+
+// DiagnosticsService defines the runtime diagnostics interface.
+type DiagnosticsService interface {
+	// Crypto reports the selected cipher and measures encryption throughput.
+	Crypto(context.Context) (*diagnostics.CryptoReport, error)
+}
+
+// AccessPolicyService defines the vault availability window management interface.
+type AccessPolicyService interface {
+	// ListPolicies retrieves the access policies matching the filter.
+	ListPolicies(context.Context, accesspolicy.ListParams) ([]*accesspolicy.Policy, error)
+	// AddPolicy creates a new access policy.
+	AddPolicy(context.Context, accesspolicy.AddParams) (*accesspolicy.Policy, error)
+	// DeletePolicy removes an access policy.
+	DeletePolicy(context.Context, accesspolicy.DeleteParams) error
+}
+
+// StorageService defines the file storage reconciliation interface.
+type StorageService interface {
+	// Detect compares stored file contents with file metadata without removing anything.
+	Detect(context.Context) (*storagegc.Report, error)
+}
+
+// StatsService defines the usage statistics interface.
+type StatsService interface {
+	// Report retrieves the usage statistics rollups of a period.
+	Report(context.Context, stats.ReportParams) (*stats.Report, error)
+}
+
+// LicenseService defines the enterprise license management interface.
+type LicenseService interface {
+	// Status reports the installed license and the enterprise features available now.
+	Status(context.Context) (*license.Status, error)
+	// Install verifies and installs a license key.
+	Install(context.Context, license.InstallParams) (*license.Status, error)
+}
+
+// TelemetryService defines the anonymous usage ping interface.
+type TelemetryService interface {
+	// Preview returns the usage ping that would be sent now.
+	Preview(context.Context) (*telemetry.Preview, error)
+}
+
+// UpdateService defines the server update check interface.
+type UpdateService interface {
+	// Status reports the result of the latest update check.
+	Status(context.Context) (*updatecheck.Status, error)
+}
+
+// RecordingService defines the request recording management interface.
+type RecordingService interface {
+	// Start starts recording the requests of a user, of a route or of a user on a route.
+	Start(context.Context, recording.StartParams) (*recording.Session, error)
+	// Stop ends a recording before its time window does.
+	Stop(context.Context, uuid.UUID) (*recording.Session, error)
+	// Delete removes a recording together with the requests captured for it.
+	Delete(context.Context, uuid.UUID) error
+	// Sessions retrieves all recordings, most recent first.
+	Sessions(context.Context) ([]*recording.Session, error)
+	// Exchanges retrieves the requests captured by a recording.
+	Exchanges(context.Context, uuid.UUID) ([]*recording.Exchange, error)
+}
+
+// RetentionService defines the data retention policy management interface.
+type RetentionService interface {
+	// Policies retrieves the default retention policy with the overrides of directory groups.
+	Policies(context.Context) (*retention.Policies, error)
+	// SetOverride applies a retention policy to the members of a directory group.
+	SetOverride(context.Context, retention.SetOverrideParams) (*retention.Override, error)
+	// DeleteOverride removes the retention policy of a directory group.
+	DeleteOverride(context.Context, retention.DeleteOverrideParams) error
+	// Preview reports the data the retention policies would purge now.
+	Preview(context.Context) (*retention.Report, error)
+}
+
+// LegalHoldService defines the legal hold management interface.
+type LegalHoldService interface {
+	// List retrieves the legal holds, optionally of one user.
+	List(context.Context, legalhold.ListParams) ([]*legalhold.Hold, error)
+	// Place puts the data of a user, or a single item of the user, on legal hold.
+	Place(context.Context, legalhold.PlaceParams) (*legalhold.Hold, error)
+	// Release lifts a legal hold.
+	Release(context.Context, legalhold.ReleaseParams) error
+}
+
+// ItemPolicyService defines the item policy management interface.
+type ItemPolicyService interface {
+	// List retrieves every item policy.
+	List(context.Context) ([]*itempolicy.Policy, error)
+	// Get retrieves an item policy.
+	Get(context.Context, itempolicy.GetParams) (*itempolicy.Policy, error)
+	// Create adds an item policy.
+	Create(context.Context, itempolicy.CreateParams) (*itempolicy.Policy, error)
+	// Update replaces an item policy.
+	Update(context.Context, itempolicy.UpdateParams) (*itempolicy.Policy, error)
+	// Delete removes an item policy.
+	Delete(context.Context, itempolicy.DeleteParams) error
+}
+
+// Handler handles HTTP requests for administrative endpoints.
+type Handler struct {
+	// s is the administrative service used to process operations.
+	s Service
+	// m is the maintenance mode switch.
+	m MaintenanceService
+	// f is the feature flag management service.
+	f FeatureService
+	// d is the runtime diagnostics service.
+	d DiagnosticsService
+	// p is the vault availability window management service.
+	p AccessPolicyService
+	// g is the file storage reconciliation service.
+	g StorageService
+	// u is the usage statistics service.
+	u StatsService
+	// l is the enterprise license management service.
+	l LicenseService
+	// t is the anonymous usage ping service.
+	t TelemetryService
+	// v is the server update check service.
+	v UpdateService
+	// r is the request recording management service.
+	r RecordingService
+	// k is the data retention policy management service.
+	k RetentionService
+	// o is the legal hold management service.
+	o LegalHoldService
+	// i is the item policy management service.
+	i ItemPolicyService
+}
+
+// NewHandler creates a new administrative handler with the provided services.
+func NewHandler(
+	s Service,
+	m MaintenanceService,
+	f FeatureService,
+	d DiagnosticsService,
+	p AccessPolicyService,
+	g StorageService,
+	u StatsService,
+	l LicenseService,
+	t TelemetryService,
+	v UpdateService,
+	r RecordingService,
+	k RetentionService,
+	o LegalHoldService,
+	i ItemPolicyService,
+) *Handler {
+	return &Handler{s: s, m: m, f: f, d: d, p: p, g: g, u: u, l: l, t: t, v: v, r: r, k: k, o: o, i: i}
+}
+
+// ListAccessRules retrieves network access rules.
+// @Summary      List access rules
+// @Description  Retrieves global and per-user network access rules, optionally filtered
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Security     AdminToken
+// @Param        user_id query string false "Only rules of this user" format(uuid)
+// @Param        global query bool false "Only global rules"
+// @Success      200 {object} ListAccessRulesResponse "Access rules retrieved successfully"
+// @Failure      400 {object} response.Error "Bad request - invalid filter"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing admin token"
+// @Failure      404 {object} response.Error "Not found - admin API is disabled"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /admin/access-rules [get]
+// .
+func (h *Handler) ListAccessRules(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	// req holds the deserialized query parameters for the listing.
+	var req ListAccessRulesRequest
+	if err := extractor.BindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	userID := uuid.Nil
+	if req.UserID != "" {
+		id, err := uuid.Parse(req.UserID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+			return
+		}
+		userID = id
+	}
+
+	rules, err := h.s.ListRules(c, accesscontrol.ListRulesParams{UserID: userID, Global: req.Global})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, ListAccessRulesResponse{Rules: NewAccessRulesFromApp(rules)})
+}
+
+// AddAccessRule creates a network access rule.
+// @Summary      Create access rule
+// @Description  Creates an allow or deny rule matching a network or a country, globally or for one user.
+// @Description  Deny rules win; once any allow rule exists, only matching requests are admitted.
+// .
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Security     AdminToken
+// @Param        request body AddAccessRuleRequest true "Access rule data"
+// @Success      201 {object} AddAccessRuleResponse "Access rule created successfully"
+// @Failure      400 {object} response.Error "Bad request - invalid rule"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing admin token"
+// @Failure      404 {object} response.Error "Not found - admin API is disabled"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /admin/access-rules [post]
+// .
+func (h *Handler) AddAccessRule(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	// req holds the deserialized JSON request payload for the rule creation.
+	var req AddAccessRuleRequest
+	if err := extractor.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	rule, err := h.s.AddRule(c, accesscontrol.AddRuleParams{
+		Action:  req.Action,
+		Network: req.Network,
+		Country: req.Country,
+		UserID:  req.UserID,
+	})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, AddAccessRuleResponse{Rule: NewAccessRuleFromApp(rule)})
+}
+
+// DeleteAccessRule removes a network access rule.
+// @Summary      Delete access rule
+// @Description  Removes a network access rule by ID
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Security     AdminToken
+// @Param        id path string true "Access rule ID" format(uuid)
+// @Success      204 "Access rule deleted successfully"
+// @Failure      400 {object} response.Error "Bad request - invalid ID format"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing admin token"
+// @Failure      404 {object} response.Error "Not found - access rule not found or admin API is disabled"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /admin/access-rules/{id} [delete]
+// .
+func (h *Handler) DeleteAccessRule(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	// req holds the deserialized URI parameters for the delete request.
+	var req DeleteAccessRuleRequest
+	if err := extractor.BindURI(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	ruleID, err := uuid.Parse(req.ID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	if err := h.s.DeleteRule(c, accesscontrol.DeleteRuleParams{ID: ruleID}); err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.Data(http.StatusNoContent, "", nil)
+}
+
+// ListAccessPolicies retrieves vault availability windows.
+// @Summary      List access policies
+// @Description  Retrieves global and per-user vault availability windows, optionally filtered
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Security     AdminToken
+// @Param        user_id query string false "Only policies of this user" format(uuid)
+// @Param        global query bool false "Only global policies"
+// @Success      200 {object} ListAccessPoliciesResponse "Access policies retrieved successfully"
+// @Failure      400 {object} response.Error "Bad request - invalid filter"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing admin token"
+// @Failure      404 {object} response.Error "Not found - admin API is disabled"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /admin/access-policies [get]
+// .
+func (h *Handler) ListAccessPolicies(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	// req holds the deserialized query parameters for the listing.
+	var req ListAccessPoliciesRequest
+	if err := extractor.BindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	userID, err := parseOptionalUUID(req.UserID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	policies, err := h.p.ListPolicies(c, accesspolicy.ListParams{UserID: userID, Global: req.Global})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, ListAccessPoliciesResponse{Policies: NewAccessPoliciesFromApp(policies)})
+}
+
+// AddAccessPolicy creates a vault availability window.
+// @Summary      Create access policy
+// @Description  Restricts item reveals to a recurring window, globally or for one user. Windows set here
+// @Description  cannot be removed by users; while any applies, reveals outside all of them are rejected.
+// .
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Security     AdminToken
+// @Param        request body AddAccessPolicyRequest true "Access policy data"
+// @Success      201 {object} AddAccessPolicyResponse "Access policy created successfully"
+// @Failure      400 {object} response.Error "Bad request - invalid days, times or timezone"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing admin token"
+// @Failure      404 {object} response.Error "Not found - admin API is disabled"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /admin/access-policies [post]
+// .
+func (h *Handler) AddAccessPolicy(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	// req holds the deserialized JSON request payload for the policy creation.
+	var req AddAccessPolicyRequest
+	if err := extractor.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	policy, err := h.p.AddPolicy(c, accesspolicy.AddParams{
+		Days:     req.Days,
+		Start:    req.Start,
+		End:      req.End,
+		Timezone: req.Timezone,
+		UserID:   req.UserID,
+		Managed:  true,
+	})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, AddAccessPolicyResponse{Policy: NewAccessPolicyFromApp(policy)})
+}
+
+// DeleteAccessPolicy removes a vault availability window.
+// @Summary      Delete access policy
+// @Description  Removes a global or per-user access policy by ID, including windows users set themselves
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Security     AdminToken
+// @Param        id path string true "Access policy ID" format(uuid)
+// @Success      204 "Access policy deleted successfully"
+// @Failure      400 {object} response.Error "Bad request - invalid ID format"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing admin token"
+// @Failure      404 {object} response.Error "Not found - access policy not found or admin API is disabled"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /admin/access-policies/{id} [delete]
+// .
+func (h *Handler) DeleteAccessPolicy(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	// req holds the deserialized URI parameters for the delete request.
+	var req DeleteAccessPolicyRequest
+	if err := extractor.BindURI(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	policyID, err := uuid.Parse(req.ID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	if err := h.p.DeletePolicy(c, accesspolicy.DeleteParams{ID: policyID}); err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.Data(http.StatusNoContent, "", nil)
+}
+
+// GetMaintenance returns the read-only maintenance mode.
+// @Summary      Get maintenance mode
+// @Description  Reports whether the server rejects mutating requests for a backup or migration window
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Security     AdminToken
+// @Success      200 {object} MaintenanceStatus "Maintenance mode retrieved successfully"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing admin token"
+// @Failure      404 {object} response.Error "Not found - admin API is disabled"
+// @Router       /admin/maintenance [get]
+// .
+func (h *Handler) GetMaintenance(c *gin.Context) {
+	c.JSON(http.StatusOK, NewMaintenanceStatusFromApp(h.m.Status(c)))
+}
+
+// SetMaintenance switches the read-only maintenance mode.
+// @Summary      Switch maintenance mode
+// @Description  Switches read-only maintenance mode. While it is on, mutating requests get 503 and reads
+// @Description  continue to work. The mode is held in memory of the instance serving the request.
+// .
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Security     AdminToken
+// @Param        request body SetMaintenanceRequest true "Maintenance mode"
+// @Success      200 {object} MaintenanceStatus "Maintenance mode switched successfully"
+// @Failure      400 {object} response.Error "Bad request - invalid input data"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing admin token"
+// @Failure      404 {object} response.Error "Not found - admin API is disabled"
+// @Router       /admin/maintenance [put]
+// .
+func (h *Handler) SetMaintenance(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	// req holds the deserialized JSON request payload for the mode switch.
+	var req SetMaintenanceRequest
+	if err := extractor.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	state := h.m.Set(c, maintenance.SetParams{Enabled: *req.Enabled})
+	c.JSON(http.StatusOK, NewMaintenanceStatusFromApp(state))
+}
+
+// ListFeatureFlags retrieves stored feature flags.
+// @Summary      List feature flags
+// @Description  Retrieves deployment-wide and per-user feature flag overrides, optionally filtered.
+// @Description  Features only set in the configuration file are not listed.
+// .
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Security     AdminToken
+// @Param        user_id query string false "Only flags of this user" format(uuid)
+// @Param        global query bool false "Only deployment-wide flags"
+// @Success      200 {object} ListFeatureFlagsResponse "Feature flags retrieved successfully"
+// @Failure      400 {object} response.Error "Bad request - invalid filter"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing admin token"
+// @Failure      404 {object} response.Error "Not found - admin API is disabled"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /admin/features [get]
+// .
+func (h *Handler) ListFeatureFlags(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	// req holds the deserialized query parameters for the listing.
+	var req ListFeatureFlagsRequest
+	if err := extractor.BindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	userID, err := parseOptionalUUID(req.UserID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	flags, err := h.f.ListOverrides(c, feature.ListOverridesParams{UserID: userID, Global: req.Global})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, ListFeatureFlagsResponse{Flags: NewFeatureFlagsFromApp(flags)})
+}
+
+// SetFeatureFlag switches a feature on or off.
+// @Summary      Set feature flag
+// @Description  Switches a feature on or off for the whole deployment or, with user_id, for one user.
+// @Description  A per-user flag wins over a deployment-wide one, which wins over the configuration file.
+// .
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Security     AdminToken
+// @Param        key path string true "Feature key"
+// @Param        request body SetFeatureFlagRequest true "Feature flag state"
+// @Success      200 {object} SetFeatureFlagResponse "Feature flag set successfully"
+// @Failure      400 {object} response.Error "Bad request - invalid key or input data"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing admin token"
+// @Failure      404 {object} response.Error "Not found - admin API is disabled"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /admin/features/{key} [put]
+// .
+func (h *Handler) SetFeatureFlag(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	// uri holds the deserialized URI parameters identifying the feature.
+	var uri FeatureFlagKeyRequest
+	if err := extractor.BindURI(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	// req holds the deserialized JSON request payload for the flag.
+	var req SetFeatureFlagRequest
+	if err := extractor.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	flag, err := h.f.SetFlag(c, feature.SetFlagParams{
+		Key:     uri.Key,
+		UserID:  req.UserID,
+		Enabled: *req.Enabled,
+	})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SetFeatureFlagResponse{Flag: NewFeatureFlagFromApp(flag)})
+}
+
+// DeleteFeatureFlag removes a feature flag override.
+// @Summary      Delete feature flag
+// @Description  Removes the deployment-wide flag or, with user_id, the flag of one user,
+// @Description  so the next less specific setting applies again.
+// .
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Security     AdminToken
+// @Param        key path string true "Feature key"
+// @Param        user_id query string false "User of the flag" format(uuid)
+// @Success      204 "Feature flag deleted successfully"
+// @Failure      400 {object} response.Error "Bad request - invalid user ID format"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing admin token"
+// @Failure      404 {object} response.Error "Not found - feature flag not found or admin API is disabled"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /admin/features/{key} [delete]
+// .
+func (h *Handler) DeleteFeatureFlag(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	// uri holds the deserialized URI parameters identifying the feature.
+	var uri FeatureFlagKeyRequest
+	if err := extractor.BindURI(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	// req holds the deserialized query parameters selecting the flag owner.
+	var req DeleteFeatureFlagRequest
+	if err := extractor.BindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	userID, err := parseOptionalUUID(req.UserID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	if err := h.f.DeleteFlag(c, feature.DeleteFlagParams{Key: uri.Key, UserID: userID}); err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.Data(http.StatusNoContent, "", nil)
+}
+
+// GetCryptoDiagnostics measures the encryption throughput of the instance.
+// @Summary      Get crypto diagnostics
+// @Description  Reports whether the CPU accelerates AES, the cipher selected for stored data and the measured
+// @Description  encrypt and decrypt throughput of every supported cipher. A measurement takes about a second.
+// .
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Security     AdminToken
+// @Success      200 {object} CryptoDiagnostics "Crypto diagnostics measured successfully"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing admin token"
+// @Failure      404 {object} response.Error "Not found - admin API is disabled"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /admin/crypto [get]
+// .
+func (h *Handler) GetCryptoDiagnostics(c *gin.Context) {
+	report, err := h.d.Crypto(c)
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, NewCryptoDiagnosticsFromApp(report))
+}
+
+// GetStorageReport reports orphaned file contents and file metadata without content.
+// @Summary      Get storage reconciliation report
+// @Description  Compares the stored file contents with the file metadata of all users and lists contents without
+// @Description  metadata and metadata without contents. Nothing is removed; cleanup is left to the scheduled job.
+// .
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Security     AdminToken
+// @Success      200 {object} StorageReport "Storage reconciled successfully"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing admin token"
+// @Failure      404 {object} response.Error "Not found - admin API is disabled"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /admin/storage [get]
+// .
+func (h *Handler) GetStorageReport(c *gin.Context) {
+	report, err := h.g.Detect(c)
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, NewStorageReportFromApp(report))
+}
+
+// GetStats reports aggregate usage statistics over time.
+// @Summary      Get usage statistics
+// @Description  Lists the periodic rollups of aggregate usage taken in the period, oldest first: user counts,
+// @Description  daily and monthly active users, item counts by type, storage usage by tier, and the requests,
+// @Description  error responses and sync volume since the previous rollup, with the request counters summed over
+// @Description  the period. Without a period the last seven days are reported.
+// .
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Security     AdminToken
+// @Param        from query string false "Start of the period" format(date-time)
+// @Param        to query string false "End of the period" format(date-time)
+// @Success      200 {object} StatsReport "Usage statistics retrieved successfully"
+// @Failure      400 {object} response.Error "Bad request - invalid period"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing admin token"
+// @Failure      404 {object} response.Error "Not found - admin API is disabled"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /admin/stats [get]
+// .
+func (h *Handler) GetStats(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	// req holds the deserialized query parameters of the period.
+	var req StatsRequest
+	if err := extractor.BindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	report, err := h.u.Report(c, stats.ReportParams{From: req.From, To: req.To})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, NewStatsReportFromApp(report))
+}
+
+// GetLicense reports the installed enterprise license.
+// @Summary      Get license
+// @Description  Reports the installed enterprise license, its state and the enterprise features available now.
+// @Description  Expired licenses keep their features for the grace period; afterwards the state is expired and
+// @Description  no features are available. Without a configured license public key nothing is enforced and
+// @Description  every feature is available.
+// .
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Security     AdminToken
+// @Success      200 {object} LicenseStatus "License retrieved successfully"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing admin token"
+// @Failure      404 {object} response.Error "Not found - admin API is disabled"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /admin/license [get]
+// .
+func (h *Handler) GetLicense(c *gin.Context) {
+	status, err := h.l.Status(c)
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, NewLicenseStatusFromApp(status))
+}
+
+// InstallLicense installs an enterprise license key.
+// @Summary      Install license
+// @Description  Verifies the license key against the configured license public key and installs it, replacing
+// @Description  the current license on every server instance. Licenses past their grace period are refused.
+// .
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Security     AdminToken
+// @Param        request body InstallLicenseRequest true "License key"
+// @Success      200 {object} LicenseStatus "License installed successfully"
+// @Failure      400 {object} response.Error "Bad request - invalid or expired license key"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing admin token"
+// @Failure      404 {object} response.Error "Not found - admin API is disabled"
+// @Failure      409 {object} response.Error "Conflict - no license public key is configured"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /admin/license [put]
+// .
+func (h *Handler) InstallLicense(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	// req holds the deserialized JSON request payload for the installation.
+	var req InstallLicenseRequest
+	if err := extractor.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	status, err := h.l.Install(c, license.InstallParams{Key: req.Key})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, NewLicenseStatusFromApp(status))
+}
+
+// GetTelemetry previews the anonymous usage ping.
+// @Summary      Get telemetry preview
+// @Description  Returns whether anonymous usage pings are switched on, where and how often they are sent, and
+// @Description  the payload that would be sent now, byte for byte. Pings carry the server version and platform,
+// @Description  user and item counts rounded to buckets and the feature flags switched on for the deployment;
+// @Description  they carry no identifiers. The payload is previewed even while telemetry is switched off.
+// .
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Security     AdminToken
+// @Success      200 {object} TelemetryPreview "Telemetry preview retrieved successfully"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing admin token"
+// @Failure      404 {object} response.Error "Not found - admin API is disabled"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /admin/telemetry [get]
+// .
+func (h *Handler) GetTelemetry(c *gin.Context) {
+	preview, err := h.t.Preview(c)
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, NewTelemetryPreviewFromApp(preview))
+}
+
+// GetUpdate reports whether a newer server release is available.
+// @Summary      Get update status
+// @Description  Reports the running version and the latest release announced by the signed release manifest,
+// @Description  with its download page and notes. The manifest is checked every UPDATE_CHECK_INTERVAL and only
+// @Description  manifests signed with the release key are trusted; a failed check keeps the previous result.
+// .
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Security     AdminToken
+// @Success      200 {object} UpdateStatus "Update status retrieved successfully"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing admin token"
+// @Failure      404 {object} response.Error "Not found - admin API is disabled"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /admin/update [get]
+// .
+func (h *Handler) GetUpdate(c *gin.Context) {
+	status, err := h.v.Status(c)
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, NewUpdateStatusFromApp(status))
+}
+
+// ListRecordings retrieves request recordings.
+// @Summary      List request recordings
+// @Description  Lists request recordings, most recent first, with the number of requests each captured and
+// @Description  whether it is still capturing.
+// .
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Security     AdminToken
+// @Success      200 {object} ListRecordingsResponse "Recordings retrieved successfully"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing admin token"
+// @Failure      404 {object} response.Error "Not found - admin API is disabled"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /admin/recordings [get]
+// .
+func (h *Handler) ListRecordings(c *gin.Context) {
+	sessions, err := h.r.Sessions(c)
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, ListRecordingsResponse{Recordings: NewRecordingsFromApp(sessions)})
+}
+
+// StartRecording starts a request recording.
+// @Summary      Start request recording
+// @Description  Captures the requests of a user, of a route or of a user on a route, with their responses, for
+// @Description  a time window of at most RECORDING_MAX_WINDOW. Credentials in headers and values in queries and
+// @Description  bodies that may be sensitive are redacted before the capture is stored encrypted. Admin requests
+// @Description  are never recorded.
+// .
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Security     AdminToken
+// @Param        request body StartRecordingRequest true "Recording data"
+// @Success      201 {object} Recording "Recording started successfully"
+// @Failure      400 {object} response.Error "Bad request - no user or route, invalid route or duration"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing admin token"
+// @Failure      404 {object} response.Error "Not found - admin API is disabled"
+// @Failure      409 {object} response.Error "Conflict - request recording is disabled"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /admin/recordings [post]
+// .
+func (h *Handler) StartRecording(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	// req holds the deserialized JSON request payload for the recording start.
+	var req StartRecordingRequest
+	if err := extractor.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	session, err := h.r.Start(c, recording.StartParams{
+		UserID:   req.UserID,
+		Route:    req.Route,
+		Reason:   req.Reason,
+		Duration: time.Duration(req.DurationSeconds) * time.Second,
+	})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, NewRecordingFromApp(session))
+}
+
+// StopRecording ends a request recording.
+// @Summary      Stop request recording
+// @Description  Stops capturing requests before the time window of the recording ends. The captured requests
+// @Description  are kept until the recording is deleted or its retention ends.
+// .
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Security     AdminToken
+// @Param        id path string true "Recording ID" format(uuid)
+// @Success      200 {object} Recording "Recording stopped successfully"
+// @Failure      400 {object} response.Error "Bad request - invalid ID format"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing admin token"
+// @Failure      404 {object} response.Error "Not found - recording not found or admin API is disabled"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /admin/recordings/{id}/stop [post]
+// .
+func (h *Handler) StopRecording(c *gin.Context) {
+	id, ok := bindRecordingID(c)
+	if !ok {
+		return
+	}
+
+	session, err := h.r.Stop(c, id)
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, NewRecordingFromApp(session))
+}
+
+// DeleteRecording removes a request recording.
+// @Summary      Delete request recording
+// @Description  Removes a recording together with the requests it captured
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Security     AdminToken
+// @Param        id path string true "Recording ID" format(uuid)
+// @Success      204 "Recording deleted successfully"
+// @Failure      400 {object} response.Error "Bad request - invalid ID format"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing admin token"
+// @Failure      404 {object} response.Error "Not found - recording not found or admin API is disabled"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /admin/recordings/{id} [delete]
+// .
+func (h *Handler) DeleteRecording(c *gin.Context) {
+	id, ok := bindRecordingID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.r.Delete(c, id); err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.Data(http.StatusNoContent, "", nil)
+}
+
+// ListRecordedExchanges retrieves the requests captured by a recording.
+// @Summary      List recorded requests
+// @Description  Lists the requests captured by a recording with their responses, in the order they were
+// @Description  received. Credentials and values that may be sensitive are redacted.
+// .
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Security     AdminToken
+// @Param        id path string true "Recording ID" format(uuid)
+// @Success      200 {object} ListRecordedExchangesResponse "Recorded requests retrieved successfully"
+// @Failure      400 {object} response.Error "Bad request - invalid ID format"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing admin token"
+// @Failure      404 {object} response.Error "Not found - recording not found or admin API is disabled"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /admin/recordings/{id}/exchanges [get]
+// .
+func (h *Handler) ListRecordedExchanges(c *gin.Context) {
+	id, ok := bindRecordingID(c)
+	if !ok {
+		return
+	}
+
+	exchanges, err := h.r.Exchanges(c, id)
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, ListRecordedExchangesResponse{Exchanges: NewRecordedExchangesFromApp(exchanges)})
+}
+
+// GetRetention retrieves the data retention policies.
+// @Summary      Get retention policies
+// @Description  Reports the default retention policy from the configuration file and the overrides of
+// @Description  directory groups. A zero rule keeps the data forever.
+// .
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Security     AdminToken
+// @Success      200 {object} RetentionPolicies "Retention policies retrieved successfully"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing admin token"
+// @Failure      404 {object} response.Error "Not found - admin API is disabled"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /admin/retention [get]
+// .
+func (h *Handler) GetRetention(c *gin.Context) {
+	policies, err := h.k.Policies(c)
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, NewRetentionPoliciesFromApp(policies))
+}
+
+// SetRetentionOverride sets the retention policy of a directory group.
+// @Summary      Set group retention policy
+// @Description  Applies a retention policy to the members of a directory group instead of the defaults.
+// @Description  Members of several groups keep the data any of their policies keeps.
+// .
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Security     AdminToken
+// @Param        id path string true "Group ID" format(uuid)
+// @Param        request body SetRetentionOverrideRequest true "Retention policy"
+// @Success      200 {object} RetentionOverride "Retention policy set successfully"
+// @Failure      400 {object} response.Error "Bad request - invalid ID format or policy"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing admin token"
+// @Failure      404 {object} response.Error "Not found - group not found or admin API is disabled"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /admin/retention/groups/{id} [put]
+// .
+func (h *Handler) SetRetentionOverride(c *gin.Context) {
+	groupID, ok := bindRetentionGroupID(c)
+	if !ok {
+		return
+	}
+
+	// req holds the deserialized JSON request payload for the policy.
+	var req SetRetentionOverrideRequest
+	if err := util.NewCtxExtractor(c).BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	override, err := h.k.SetOverride(c, retention.SetOverrideParams{
+		GroupID: groupID,
+		Policy:  retention.Policy{KeepVersions: req.KeepVersions, KeepAuditDays: req.KeepAuditDays},
+	})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, NewRetentionOverrideFromApp(override))
+}
+
+// DeleteRetentionOverride removes the retention policy of a directory group.
+// @Summary      Delete group retention policy
+// @Description  Removes the retention policy of a directory group so the defaults apply to its members again
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Security     AdminToken
+// @Param        id path string true "Group ID" format(uuid)
+// @Success      204 "Retention policy deleted successfully"
+// @Failure      400 {object} response.Error "Bad request - invalid ID format"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing admin token"
+// @Failure      404 {object} response.Error "Not found - group has no policy or admin API is disabled"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /admin/retention/groups/{id} [delete]
+// .
+func (h *Handler) DeleteRetentionOverride(c *gin.Context) {
+	groupID, ok := bindRetentionGroupID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.k.DeleteOverride(c, retention.DeleteOverrideParams{GroupID: groupID}); err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.Data(http.StatusNoContent, "", nil)
+}
+
+// PreviewRetention reports the data the retention policies would purge.
+// @Summary      Preview retention
+// @Description  Counts the replaced item versions and item reveal records the retention policies would purge
+// @Description  if enforced now, for every effective policy. Nothing is deleted.
+// .
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Security     AdminToken
+// @Success      200 {object} RetentionPreview "Retention preview computed successfully"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing admin token"
+// @Failure      404 {object} response.Error "Not found - admin API is disabled"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /admin/retention/preview [get]
+// .
+func (h *Handler) PreviewRetention(c *gin.Context) {
+	report, err := h.k.Preview(c)
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, NewRetentionPreviewFromApp(report))
+}
+
+// ListLegalHolds retrieves legal holds.
+// @Summary      List legal holds
+// @Description  Retrieves the legal holds ordered by placement time, optionally of one user
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Security     AdminToken
+// @Param        user_id query string false "Only holds on the data of this user" format(uuid)
+// @Success      200 {object} ListLegalHoldsResponse "Legal holds retrieved successfully"
+// @Failure      400 {object} response.Error "Bad request - invalid filter"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing admin token"
+// @Failure      404 {object} response.Error "Not found - admin API is disabled"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /admin/legal-holds [get]
+// .
+func (h *Handler) ListLegalHolds(c *gin.Context) {
+	// req holds the deserialized query parameters for the listing.
+	var req ListLegalHoldsRequest
+	if err := util.NewCtxExtractor(c).BindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	userID := uuid.Nil
+	if req.UserID != "" {
+		id, err := uuid.Parse(req.UserID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+			return
+		}
+		userID = id
+	}
+
+	holds, err := h.o.List(c, legalhold.ListParams{UserID: userID})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, ListLegalHoldsResponse{Holds: NewLegalHoldsFromApp(holds)})
+}
+
+// PlaceLegalHold places a legal hold.
+// @Summary      Place legal hold
+// @Description  Puts the data of a user, or a single item of the user, on legal hold. Held data is left intact
+// @Description  by item purges, retention enforcement and version history pruning until the hold is released.
+// .
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Security     AdminToken
+// @Param        request body PlaceLegalHoldRequest true "Legal hold data"
+// @Success      201 {object} LegalHold "Legal hold placed successfully"
+// @Failure      400 {object} response.Error "Bad request - invalid hold"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing admin token"
+// @Failure      404 {object} response.Error "Not found - user not found or admin API is disabled"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /admin/legal-holds [post]
+// .
+func (h *Handler) PlaceLegalHold(c *gin.Context) {
+	// req holds the deserialized JSON request payload for the hold.
+	var req PlaceLegalHoldRequest
+	if err := util.NewCtxExtractor(c).BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	hold, err := h.o.Place(c, legalhold.PlaceParams{UserID: req.UserID, ItemID: req.ItemID, Reason: req.Reason})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, NewLegalHoldFromApp(hold))
+}
+
+// ReleaseLegalHold releases a legal hold.
+// @Summary      Release legal hold
+// @Description  Lifts a legal hold, so the data it covered can be deleted again unless another hold covers it
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Security     AdminToken
+// @Param        id path string true "Legal hold ID" format(uuid)
+// @Success      204 "Legal hold released successfully"
+// @Failure      400 {object} response.Error "Bad request - invalid ID format"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing admin token"
+// @Failure      404 {object} response.Error "Not found - hold not found or admin API is disabled"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /admin/legal-holds/{id} [delete]
+// .
+func (h *Handler) ReleaseLegalHold(c *gin.Context) {
+	// req holds the deserialized URI parameters of the request.
+	var req LegalHoldIDRequest
+	if err := util.NewCtxExtractor(c).BindURI(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+	id, err := uuid.Parse(req.ID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	if err := h.o.Release(c, legalhold.ReleaseParams{ID: id}); err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.Data(http.StatusNoContent, "", nil)
+}
+
+// ListItemPolicies retrieves item policies.
+// @Summary      List item policies
+// @Description  Retrieves every item policy ordered by creation time
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Security     AdminToken
+// @Success      200 {object} ListItemPoliciesResponse "Item policies retrieved successfully"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing admin token"
+// @Failure      404 {object} response.Error "Not found - admin API is disabled"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /admin/item-policies [get]
+// .
+func (h *Handler) ListItemPolicies(c *gin.Context) {
+	policies, err := h.i.List(c)
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, ListItemPoliciesResponse{Policies: NewItemPoliciesFromApp(policies)})
+}
+
+// CreateItemPolicy creates an item policy.
+// @Summary      Create item policy
+// @Description  Restricts the vault items of every user, or of the members of a directory group: forbids item
+// @Description  types and sets the strength credential passwords must have. Items created or updated afterwards
+// @Description  must meet every policy applying to their owner; items already stored are left as they are.
+// .
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Security     AdminToken
+// @Param        request body ItemPolicyRequest true "Item policy data"
+// @Success      201 {object} ItemPolicy "Item policy created successfully"
+// @Failure      400 {object} response.Error "Bad request - invalid policy"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing admin token"
+// @Failure      404 {object} response.Error "Not found - group not found or admin API is disabled"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /admin/item-policies [post]
+// .
+func (h *Handler) CreateItemPolicy(c *gin.Context) {
+	// req holds the deserialized JSON request payload for the policy.
+	var req ItemPolicyRequest
+	if err := util.NewCtxExtractor(c).BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	policy, err := h.i.Create(c, itempolicy.CreateParams{
+		Name:    req.Name,
+		Rules:   req.Rules.toApp(),
+		GroupID: req.GroupID,
+	})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, NewItemPolicyFromApp(policy))
+}
+
+// GetItemPolicy retrieves an item policy.
+// @Summary      Get item policy
+// @Description  Retrieves an item policy by ID
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Security     AdminToken
+// @Param        id path string true "Item policy ID" format(uuid)
+// @Success      200 {object} ItemPolicy "Item policy retrieved successfully"
+// @Failure      400 {object} response.Error "Bad request - invalid ID format"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing admin token"
+// @Failure      404 {object} response.Error "Not found - policy not found or admin API is disabled"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /admin/item-policies/{id} [get]
+// .
+func (h *Handler) GetItemPolicy(c *gin.Context) {
+	id, ok := bindItemPolicyID(c)
+	if !ok {
+		return
+	}
+
+	policy, err := h.i.Get(c, itempolicy.GetParams{ID: id})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, NewItemPolicyFromApp(policy))
+}
+
+// UpdateItemPolicy replaces an item policy.
+// @Summary      Update item policy
+// @Description  Replaces the name, group and rules of an item policy
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Security     AdminToken
+// @Param        id path string true "Item policy ID" format(uuid)
+// @Param        request body ItemPolicyRequest true "Item policy data"
+// @Success      200 {object} ItemPolicy "Item policy updated successfully"
+// @Failure      400 {object} response.Error "Bad request - invalid ID format or policy"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing admin token"
+// @Failure      404 {object} response.Error "Not found - policy or group not found or admin API is disabled"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /admin/item-policies/{id} [put]
+// .
+func (h *Handler) UpdateItemPolicy(c *gin.Context) {
+	id, ok := bindItemPolicyID(c)
+	if !ok {
+		return
+	}
+
+	// req holds the deserialized JSON request payload for the policy.
+	var req ItemPolicyRequest
+	if err := util.NewCtxExtractor(c).BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	policy, err := h.i.Update(c, itempolicy.UpdateParams{
+		ID:      id,
+		Name:    req.Name,
+		Rules:   req.Rules.toApp(),
+		GroupID: req.GroupID,
+	})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, NewItemPolicyFromApp(policy))
+}
+
+// DeleteItemPolicy removes an item policy.
+// @Summary      Delete item policy
+// @Description  Removes an item policy, so its rules no longer apply to new and updated items
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Security     AdminToken
+// @Param        id path string true "Item policy ID" format(uuid)
+// @Success      204 "Item policy deleted successfully"
+// @Failure      400 {object} response.Error "Bad request - invalid ID format"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing admin token"
+// @Failure      404 {object} response.Error "Not found - policy not found or admin API is disabled"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /admin/item-policies/{id} [delete]
+// .
+func (h *Handler) DeleteItemPolicy(c *gin.Context) {
+	id, ok := bindItemPolicyID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.i.Delete(c, itempolicy.DeleteParams{ID: id}); err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.Data(http.StatusNoContent, "", nil)
+}
+
+// bindItemPolicyID parses the item policy ID of the request path, responding with 400 Bad Request when it is
+// invalid.
+func bindItemPolicyID(c *gin.Context) (uuid.UUID, bool) {
+	// req holds the deserialized URI parameters of the request.
+	var req ItemPolicyIDRequest
+	if err := util.NewCtxExtractor(c).BindURI(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return uuid.Nil, false
+	}
+	id, err := uuid.Parse(req.ID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// bindRetentionGroupID parses the group ID of the request path, responding with 400 Bad Request when it is
+// invalid.
+func bindRetentionGroupID(c *gin.Context) (uuid.UUID, bool) {
+	// req holds the deserialized URI parameters of the request.
+	var req RetentionGroupIDRequest
+	if err := util.NewCtxExtractor(c).BindURI(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return uuid.Nil, false
+	}
+	id, err := uuid.Parse(req.ID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// bindRecordingID parses the recording ID of the request path, responding with 400 Bad Request when it is
+// invalid.
+func bindRecordingID(c *gin.Context) (uuid.UUID, bool) {
+	// req holds the deserialized URI parameters of the request.
+	var req RecordingIDRequest
+	if err := util.NewCtxExtractor(c).BindURI(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return uuid.Nil, false
+	}
+	id, err := uuid.Parse(req.ID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// parseOptionalUUID parses a user ID filter, treating an empty value as uuid.Nil.
+func parseOptionalUUID(s string) (uuid.UUID, error) {
+	if s == "" {
+		return uuid.Nil, nil
+	}
+	return uuid.Parse(s)
+}
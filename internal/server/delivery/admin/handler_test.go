@@ -0,0 +1,2723 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/accesscontrol"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/accesspolicy"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/diagnostics"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/feature"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/itempolicy"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/legalhold"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/license"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/maintenance"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/recording"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/retention"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/stats"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/storagegc"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/telemetry"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/updatecheck"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockAdminService implements Service for testing.
+type mockAdminService struct {
+	listFunc   func(ctx context.Context, params accesscontrol.ListRulesParams) ([]*accesscontrol.Rule, error)
+	addFunc    func(ctx context.Context, params accesscontrol.AddRuleParams) (*accesscontrol.Rule, error)
+	deleteFunc func(ctx context.Context, params accesscontrol.DeleteRuleParams) error
+}
+
+func (m *mockAdminService) ListRules(
+	ctx context.Context,
+	params accesscontrol.ListRulesParams,
+) ([]*accesscontrol.Rule, error) {
+	if m.listFunc != nil {
+		return m.listFunc(ctx, params)
+	}
+	return []*accesscontrol.Rule{}, nil
+}
+
+func (m *mockAdminService) AddRule(
+	ctx context.Context,
+	params accesscontrol.AddRuleParams,
+) (*accesscontrol.Rule, error) {
+	if m.addFunc != nil {
+		return m.addFunc(ctx, params)
+	}
+	return &accesscontrol.Rule{ID: uuid.New()}, nil
+}
+
+func (m *mockAdminService) DeleteRule(ctx context.Context, params accesscontrol.DeleteRuleParams) error {
+	if m.deleteFunc != nil {
+		return m.deleteFunc(ctx, params)
+	}
+	return nil
+}
+
+// mockMaintenanceService implements MaintenanceService for testing.
+type mockMaintenanceService struct {
+	state maintenance.State
+}
+
+func (m *mockMaintenanceService) Status(context.Context) maintenance.State {
+	return m.state
+}
+
+func (m *mockMaintenanceService) Set(_ context.Context, params maintenance.SetParams) maintenance.State {
+	m.state = maintenance.State{Enabled: params.Enabled, Since: time.Now()}
+	return m.state
+}
+
+// mockFeatureService implements FeatureService for testing.
+type mockFeatureService struct {
+	listFunc   func(ctx context.Context, params feature.ListOverridesParams) ([]*feature.Override, error)
+	setFunc    func(ctx context.Context, params feature.SetFlagParams) (*feature.Override, error)
+	deleteFunc func(ctx context.Context, params feature.DeleteFlagParams) error
+}
+
+func (m *mockFeatureService) ListOverrides(
+	ctx context.Context,
+	params feature.ListOverridesParams,
+) ([]*feature.Override, error) {
+	if m.listFunc != nil {
+		return m.listFunc(ctx, params)
+	}
+	return []*feature.Override{}, nil
+}
+
+func (m *mockFeatureService) SetFlag(ctx context.Context, params feature.SetFlagParams) (*feature.Override, error) {
+	if m.setFunc != nil {
+		return m.setFunc(ctx, params)
+	}
+	return &feature.Override{Key: params.Key, UserID: params.UserID, Enabled: params.Enabled}, nil
+}
+
+func (m *mockFeatureService) DeleteFlag(ctx context.Context, params feature.DeleteFlagParams) error {
+	if m.deleteFunc != nil {
+		return m.deleteFunc(ctx, params)
+	}
+	return nil
+}
+
+// mockDiagnosticsService implements DiagnosticsService for testing.
+type mockDiagnosticsService struct {
+	cryptoFunc func(ctx context.Context) (*diagnostics.CryptoReport, error)
+}
+
+func (m *mockDiagnosticsService) Crypto(ctx context.Context) (*diagnostics.CryptoReport, error) {
+	if m.cryptoFunc != nil {
+		return m.cryptoFunc(ctx)
+	}
+	return &diagnostics.CryptoReport{}, nil
+}
+
+// mockStorageService implements StorageService for testing.
+type mockStorageService struct {
+	detectFunc func(ctx context.Context) (*storagegc.Report, error)
+}
+
+func (m *mockStorageService) Detect(ctx context.Context) (*storagegc.Report, error) {
+	if m.detectFunc != nil {
+		return m.detectFunc(ctx)
+	}
+	return &storagegc.Report{}, nil
+}
+
+// mockStatsService implements StatsService for testing.
+type mockStatsService struct {
+	reportFunc func(ctx context.Context, params stats.ReportParams) (*stats.Report, error)
+}
+
+func (m *mockStatsService) Report(ctx context.Context, params stats.ReportParams) (*stats.Report, error) {
+	if m.reportFunc != nil {
+		return m.reportFunc(ctx, params)
+	}
+	return &stats.Report{}, nil
+}
+
+// mockLicenseService implements LicenseService for testing.
+type mockLicenseService struct {
+	statusFunc  func(ctx context.Context) (*license.Status, error)
+	installFunc func(ctx context.Context, params license.InstallParams) (*license.Status, error)
+}
+
+func (m *mockLicenseService) Status(ctx context.Context) (*license.Status, error) {
+	if m.statusFunc != nil {
+		return m.statusFunc(ctx)
+	}
+	return &license.Status{State: license.StateNone}, nil
+}
+
+func (m *mockLicenseService) Install(ctx context.Context, params license.InstallParams) (*license.Status, error) {
+	if m.installFunc != nil {
+		return m.installFunc(ctx, params)
+	}
+	return &license.Status{State: license.StateNone}, nil
+}
+
+// mockTelemetryService implements TelemetryService for testing.
+type mockTelemetryService struct {
+	previewFunc func(ctx context.Context) (*telemetry.Preview, error)
+}
+
+func (m *mockTelemetryService) Preview(ctx context.Context) (*telemetry.Preview, error) {
+	if m.previewFunc != nil {
+		return m.previewFunc(ctx)
+	}
+	return &telemetry.Preview{Payload: []byte(`{}`)}, nil
+}
+
+// mockUpdateService implements UpdateService for testing.
+type mockUpdateService struct {
+	statusFunc func(ctx context.Context) (*updatecheck.Status, error)
+}
+
+func (m *mockUpdateService) Status(ctx context.Context) (*updatecheck.Status, error) {
+	if m.statusFunc != nil {
+		return m.statusFunc(ctx)
+	}
+	return &updatecheck.Status{}, nil
+}
+
+// mockRecordingService implements RecordingService for testing.
+type mockRecordingService struct {
+	startFunc     func(ctx context.Context, params recording.StartParams) (*recording.Session, error)
+	stopFunc      func(ctx context.Context, id uuid.UUID) (*recording.Session, error)
+	deleteFunc    func(ctx context.Context, id uuid.UUID) error
+	sessionsFunc  func(ctx context.Context) ([]*recording.Session, error)
+	exchangesFunc func(ctx context.Context, id uuid.UUID) ([]*recording.Exchange, error)
+}
+
+func (m *mockRecordingService) Start(ctx context.Context, params recording.StartParams) (*recording.Session, error) {
+	if m.startFunc != nil {
+		return m.startFunc(ctx, params)
+	}
+	return &recording.Session{}, nil
+}
+
+func (m *mockRecordingService) Stop(ctx context.Context, id uuid.UUID) (*recording.Session, error) {
+	if m.stopFunc != nil {
+		return m.stopFunc(ctx, id)
+	}
+	return &recording.Session{ID: id}, nil
+}
+
+func (m *mockRecordingService) Delete(ctx context.Context, id uuid.UUID) error {
+	if m.deleteFunc != nil {
+		return m.deleteFunc(ctx, id)
+	}
+	return nil
+}
+
+func (m *mockRecordingService) Sessions(ctx context.Context) ([]*recording.Session, error) {
+	if m.sessionsFunc != nil {
+		return m.sessionsFunc(ctx)
+	}
+	return nil, nil
+}
+
+func (m *mockRecordingService) Exchanges(ctx context.Context, id uuid.UUID) ([]*recording.Exchange, error) {
+	if m.exchangesFunc != nil {
+		return m.exchangesFunc(ctx, id)
+	}
+	return nil, nil
+}
+
+// mockRetentionService implements RetentionService for testing.
+type mockRetentionService struct {
+	policiesFunc func(ctx context.Context) (*retention.Policies, error)
+	setFunc      func(ctx context.Context, params retention.SetOverrideParams) (*retention.Override, error)
+	deleteFunc   func(ctx context.Context, params retention.DeleteOverrideParams) error
+	previewFunc  func(ctx context.Context) (*retention.Report, error)
+}
+
+func (m *mockRetentionService) Policies(ctx context.Context) (*retention.Policies, error) {
+	if m.policiesFunc != nil {
+		return m.policiesFunc(ctx)
+	}
+	return &retention.Policies{}, nil
+}
+
+func (m *mockRetentionService) SetOverride(
+	ctx context.Context,
+	params retention.SetOverrideParams,
+) (*retention.Override, error) {
+	if m.setFunc != nil {
+		return m.setFunc(ctx, params)
+	}
+	return &retention.Override{GroupID: params.GroupID, Policy: params.Policy}, nil
+}
+
+func (m *mockRetentionService) DeleteOverride(ctx context.Context, params retention.DeleteOverrideParams) error {
+	if m.deleteFunc != nil {
+		return m.deleteFunc(ctx, params)
+	}
+	return nil
+}
+
+func (m *mockRetentionService) Preview(ctx context.Context) (*retention.Report, error) {
+	if m.previewFunc != nil {
+		return m.previewFunc(ctx)
+	}
+	return &retention.Report{}, nil
+}
+
+// mockLegalHoldService implements LegalHoldService for testing.
+type mockLegalHoldService struct {
+	listFunc    func(ctx context.Context, params legalhold.ListParams) ([]*legalhold.Hold, error)
+	placeFunc   func(ctx context.Context, params legalhold.PlaceParams) (*legalhold.Hold, error)
+	releaseFunc func(ctx context.Context, params legalhold.ReleaseParams) error
+}
+
+func (m *mockLegalHoldService) List(ctx context.Context, params legalhold.ListParams) ([]*legalhold.Hold, error) {
+	if m.listFunc != nil {
+		return m.listFunc(ctx, params)
+	}
+	return nil, nil
+}
+
+func (m *mockLegalHoldService) Place(ctx context.Context, params legalhold.PlaceParams) (*legalhold.Hold, error) {
+	if m.placeFunc != nil {
+		return m.placeFunc(ctx, params)
+	}
+	return &legalhold.Hold{ID: uuid.New(), UserID: params.UserID, ItemID: params.ItemID, Reason: params.Reason}, nil
+}
+
+func (m *mockLegalHoldService) Release(ctx context.Context, params legalhold.ReleaseParams) error {
+	if m.releaseFunc != nil {
+		return m.releaseFunc(ctx, params)
+	}
+	return nil
+}
+
+// mockItemPolicyService implements ItemPolicyService for testing.
+type mockItemPolicyService struct {
+	listFunc   func(ctx context.Context) ([]*itempolicy.Policy, error)
+	getFunc    func(ctx context.Context, params itempolicy.GetParams) (*itempolicy.Policy, error)
+	createFunc func(ctx context.Context, params itempolicy.CreateParams) (*itempolicy.Policy, error)
+	updateFunc func(ctx context.Context, params itempolicy.UpdateParams) (*itempolicy.Policy, error)
+	deleteFunc func(ctx context.Context, params itempolicy.DeleteParams) error
+}
+
+func (m *mockItemPolicyService) List(ctx context.Context) ([]*itempolicy.Policy, error) {
+	if m.listFunc != nil {
+		return m.listFunc(ctx)
+	}
+	return nil, nil
+}
+
+func (m *mockItemPolicyService) Get(ctx context.Context, params itempolicy.GetParams) (*itempolicy.Policy, error) {
+	if m.getFunc != nil {
+		return m.getFunc(ctx, params)
+	}
+	return &itempolicy.Policy{ID: params.ID}, nil
+}
+
+func (m *mockItemPolicyService) Create(
+	ctx context.Context,
+	params itempolicy.CreateParams,
+) (*itempolicy.Policy, error) {
+	if m.createFunc != nil {
+		return m.createFunc(ctx, params)
+	}
+	return &itempolicy.Policy{ID: uuid.New(), Name: params.Name, Rules: params.Rules, GroupID: params.GroupID}, nil
+}
+
+func (m *mockItemPolicyService) Update(
+	ctx context.Context,
+	params itempolicy.UpdateParams,
+) (*itempolicy.Policy, error) {
+	if m.updateFunc != nil {
+		return m.updateFunc(ctx, params)
+	}
+	return &itempolicy.Policy{ID: params.ID, Name: params.Name, Rules: params.Rules, GroupID: params.GroupID}, nil
+}
+
+func (m *mockItemPolicyService) Delete(ctx context.Context, params itempolicy.DeleteParams) error {
+	if m.deleteFunc != nil {
+		return m.deleteFunc(ctx, params)
+	}
+	return nil
+}
+
+// mockAccessPolicyService implements AccessPolicyService for testing.
+type mockAccessPolicyService struct {
+	listFunc   func(ctx context.Context, params accesspolicy.ListParams) ([]*accesspolicy.Policy, error)
+	addFunc    func(ctx context.Context, params accesspolicy.AddParams) (*accesspolicy.Policy, error)
+	deleteFunc func(ctx context.Context, params accesspolicy.DeleteParams) error
+}
+
+func (m *mockAccessPolicyService) ListPolicies(
+	ctx context.Context,
+	params accesspolicy.ListParams,
+) ([]*accesspolicy.Policy, error) {
+	if m.listFunc != nil {
+		return m.listFunc(ctx, params)
+	}
+	return nil, nil
+}
+
+func (m *mockAccessPolicyService) AddPolicy(
+	ctx context.Context,
+	params accesspolicy.AddParams,
+) (*accesspolicy.Policy, error) {
+	if m.addFunc != nil {
+		return m.addFunc(ctx, params)
+	}
+	return nil, nil
+}
+
+func (m *mockAccessPolicyService) DeletePolicy(ctx context.Context, params accesspolicy.DeleteParams) error {
+	if m.deleteFunc != nil {
+		return m.deleteFunc(ctx, params)
+	}
+	return nil
+}
+
+func TestHandler_ListAccessRules(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	ruleID := uuid.New()
+	createdAt := time.Date(2025, time.March, 15, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		mockService    *mockAdminService
+		want           *ListAccessRulesResponse
+		name           string
+		query          string
+		expectedStatus int
+	}{
+		{
+			name:  "success with user filter",
+			query: "?user_id=" + userID.String(),
+			mockService: &mockAdminService{
+				listFunc: func(_ context.Context, p accesscontrol.ListRulesParams) ([]*accesscontrol.Rule, error) {
+					if p.UserID != userID || p.Global {
+						return nil, errors.New("unexpected params")
+					}
+					return []*accesscontrol.Rule{{
+						ID:        ruleID,
+						UserID:    userID,
+						Action:    "deny",
+						Network:   "203.0.113.0/24",
+						CreatedAt: createdAt,
+					}}, nil
+				},
+			},
+			want: &ListAccessRulesResponse{Rules: []*AccessRule{{
+				ID:        ruleID,
+				UserID:    userID,
+				Action:    "deny",
+				Network:   "203.0.113.0/24",
+				CreatedAt: createdAt,
+			}}},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:  "global filter",
+			query: "?global=true",
+			mockService: &mockAdminService{
+				listFunc: func(_ context.Context, p accesscontrol.ListRulesParams) ([]*accesscontrol.Rule, error) {
+					if !p.Global {
+						return nil, errors.New("unexpected params")
+					}
+					return []*accesscontrol.Rule{}, nil
+				},
+			},
+			want:           &ListAccessRulesResponse{Rules: []*AccessRule{}},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid user id",
+			query:          "?user_id=invalid",
+			mockService:    &mockAdminService{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "service tech error",
+			query: "",
+			mockService: &mockAdminService{
+				listFunc: func(context.Context, accesscontrol.ListRulesParams) ([]*accesscontrol.Rule, error) {
+					return nil, accesscontrol.ErrAccessControlTechError
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/admin/access-rules"+tt.query, nil)
+
+			NewHandler(tt.mockService, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil).ListAccessRules(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.want != nil {
+				var got ListAccessRulesResponse
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+				assert.Equal(t, tt.want, &got)
+			}
+		})
+	}
+}
+
+func TestHandler_AddAccessRule(t *testing.T) {
+	t.Parallel()
+
+	ruleID := uuid.New()
+
+	tests := []struct {
+		mockService    *mockAdminService
+		name           string
+		body           string
+		wantMessage    string
+		expectedStatus int
+	}{
+		{
+			name: "success",
+			body: `{"action":"allow","country":"DE"}`,
+			mockService: &mockAdminService{
+				addFunc: func(_ context.Context, p accesscontrol.AddRuleParams) (*accesscontrol.Rule, error) {
+					return &accesscontrol.Rule{ID: ruleID, Action: p.Action, Country: p.Country}, nil
+				},
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "missing action",
+			body:           `{"network":"10.0.0.0/8"}`,
+			mockService:    &mockAdminService{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "malformed json",
+			body:           `{`,
+			mockService:    &mockAdminService{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "invalid network",
+			body: `{"action":"deny","network":"bogus"}`,
+			mockService: &mockAdminService{
+				addFunc: func(context.Context, accesscontrol.AddRuleParams) (*accesscontrol.Rule, error) {
+					return nil, fmt.Errorf("%w: %w", accesscontrol.ErrAccessControlAppError,
+						accesscontrol.ErrRuleIncorrectNetwork)
+				},
+			},
+			wantMessage:    "Network must be a valid CIDR or IP address",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "geoip unavailable",
+			body: `{"action":"deny","country":"DE"}`,
+			mockService: &mockAdminService{
+				addFunc: func(context.Context, accesscontrol.AddRuleParams) (*accesscontrol.Rule, error) {
+					return nil, accesscontrol.ErrGeoIPUnavailable
+				},
+			},
+			wantMessage:    "Country rules require a configured GeoIP database",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/admin/access-rules", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			NewHandler(tt.mockService, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil).AddAccessRule(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusCreated {
+				var got AddAccessRuleResponse
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+				assert.Equal(t, ruleID, got.Rule.ID)
+				assert.Equal(t, "DE", got.Rule.Country)
+			}
+			if tt.wantMessage != "" {
+				assert.Contains(t, w.Body.String(), tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestHandler_DeleteAccessRule(t *testing.T) {
+	t.Parallel()
+
+	ruleID := uuid.New()
+
+	tests := []struct {
+		mockService    *mockAdminService
+		name           string
+		id             string
+		expectedStatus int
+	}{
+		{
+			name: "success",
+			id:   ruleID.String(),
+			mockService: &mockAdminService{
+				deleteFunc: func(_ context.Context, p accesscontrol.DeleteRuleParams) error {
+					if p.ID != ruleID {
+						return errors.New("unexpected id")
+					}
+					return nil
+				},
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "invalid id",
+			id:             "invalid",
+			mockService:    &mockAdminService{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "not found",
+			id:   ruleID.String(),
+			mockService: &mockAdminService{
+				deleteFunc: func(context.Context, accesscontrol.DeleteRuleParams) error {
+					return accesscontrol.ErrRuleNotFound
+				},
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodDelete, "/admin/access-rules/"+tt.id, nil)
+			c.Params = gin.Params{{Key: "id", Value: tt.id}}
+
+			NewHandler(tt.mockService, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil).DeleteAccessRule(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestHandler_GetMaintenance(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+	since := time.Date(2025, time.March, 15, 10, 0, 0, 0, time.UTC)
+	m := &mockMaintenanceService{state: maintenance.State{Enabled: true, Since: since}}
+
+	rec := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rec)
+	c.Request = httptest.NewRequest(http.MethodGet, "/admin/maintenance", nil)
+
+	NewHandler(nil, m, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil).GetMaintenance(c)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	var resp MaintenanceStatus
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.True(t, resp.Enabled)
+	assert.Equal(t, since, resp.Since)
+}
+
+func TestHandler_SetMaintenance(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		body        string
+		wantStatus  int
+		wantEnabled bool
+	}{
+		{name: "switch on", body: `{"enabled":true}`, wantStatus: http.StatusOK, wantEnabled: true},
+		{name: "switch off", body: `{"enabled":false}`, wantStatus: http.StatusOK, wantEnabled: false},
+		{name: "missing mode", body: `{}`, wantStatus: http.StatusBadRequest},
+		{name: "invalid json", body: `{"enabled":`, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			m := &mockMaintenanceService{state: maintenance.State{Enabled: !tt.wantEnabled}}
+
+			rec := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rec)
+			c.Request = httptest.NewRequest(http.MethodPut, "/admin/maintenance", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			NewHandler(nil, m, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil).SetMaintenance(c)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var resp MaintenanceStatus
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+			assert.Equal(t, tt.wantEnabled, resp.Enabled)
+			assert.Equal(t, tt.wantEnabled, m.state.Enabled)
+		})
+	}
+}
+
+func TestHandler_ListFeatureFlags(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+
+	tests := []struct {
+		mockService    *mockFeatureService
+		name           string
+		query          string
+		expectedStatus int
+		expectedCount  int
+	}{
+		{
+			name:  "all flags",
+			query: "",
+			mockService: &mockFeatureService{
+				listFunc: func(_ context.Context, p feature.ListOverridesParams) ([]*feature.Override, error) {
+					if p.UserID != uuid.Nil || p.Global {
+						return nil, errors.New("unexpected filter")
+					}
+					return []*feature.Override{{Key: "sync_v2", Enabled: true}, {Key: "sync_v2", UserID: userID}}, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+			expectedCount:  2,
+		},
+		{
+			name:  "user flags",
+			query: "?user_id=" + userID.String(),
+			mockService: &mockFeatureService{
+				listFunc: func(_ context.Context, p feature.ListOverridesParams) ([]*feature.Override, error) {
+					if p.UserID != userID {
+						return nil, errors.New("unexpected user")
+					}
+					return []*feature.Override{{Key: "sync_v2", UserID: userID}}, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+			expectedCount:  1,
+		},
+		{
+			name:           "invalid user id",
+			query:          "?user_id=invalid",
+			mockService:    &mockFeatureService{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "service error",
+			mockService: &mockFeatureService{
+				listFunc: func(context.Context, feature.ListOverridesParams) ([]*feature.Override, error) {
+					return nil, fmt.Errorf("load failed: %w", feature.ErrFeatureTechError)
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/admin/features"+tt.query, nil)
+
+			NewHandler(nil, nil, tt.mockService, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil).ListFeatureFlags(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var resp ListFeatureFlagsResponse
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+				assert.Len(t, resp.Flags, tt.expectedCount)
+			}
+		})
+	}
+}
+
+func TestHandler_SetFeatureFlag(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+
+	tests := []struct {
+		mockService    *mockFeatureService
+		wantParams     *feature.SetFlagParams
+		name           string
+		key            string
+		body           string
+		wantMessage    string
+		expectedStatus int
+	}{
+		{
+			name:           "deployment flag",
+			key:            "sync_v2",
+			body:           `{"enabled":true}`,
+			mockService:    &mockFeatureService{},
+			wantParams:     &feature.SetFlagParams{Key: "sync_v2", Enabled: true},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "user flag",
+			key:            "sync_v2",
+			body:           `{"enabled":false,"user_id":"` + userID.String() + `"}`,
+			mockService:    &mockFeatureService{},
+			wantParams:     &feature.SetFlagParams{Key: "sync_v2", UserID: userID},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "missing state",
+			key:            "sync_v2",
+			body:           `{}`,
+			mockService:    &mockFeatureService{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "invalid key",
+			key:  "Sync",
+			body: `{"enabled":true}`,
+			mockService: &mockFeatureService{
+				setFunc: func(context.Context, feature.SetFlagParams) (*feature.Override, error) {
+					return nil, errors.Join(feature.ErrFeatureAppError, feature.ErrFlagIncorrectKey)
+				},
+			},
+			wantMessage:    "Feature key must be",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var got *feature.SetFlagParams
+			if tt.wantParams != nil {
+				tt.mockService.setFunc = func(_ context.Context, p feature.SetFlagParams) (*feature.Override, error) {
+					got = &p
+					return &feature.Override{Key: p.Key, UserID: p.UserID, Enabled: p.Enabled}, nil
+				}
+			}
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPut, "/admin/features/"+tt.key, strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Params = gin.Params{{Key: "key", Value: tt.key}}
+
+			NewHandler(nil, nil, tt.mockService, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil).SetFeatureFlag(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.wantParams != nil {
+				assert.Equal(t, tt.wantParams, got)
+				var resp SetFeatureFlagResponse
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+				assert.Equal(t, tt.wantParams.Key, resp.Flag.Key)
+				assert.Equal(t, tt.wantParams.Enabled, resp.Flag.Enabled)
+			}
+			if tt.wantMessage != "" {
+				assert.Contains(t, w.Body.String(), tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestHandler_DeleteFeatureFlag(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+
+	tests := []struct {
+		mockService    *mockFeatureService
+		name           string
+		query          string
+		expectedStatus int
+	}{
+		{
+			name: "deployment flag",
+			mockService: &mockFeatureService{
+				deleteFunc: func(_ context.Context, p feature.DeleteFlagParams) error {
+					if p.Key != "sync_v2" || p.UserID != uuid.Nil {
+						return errors.New("unexpected flag")
+					}
+					return nil
+				},
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:  "user flag",
+			query: "?user_id=" + userID.String(),
+			mockService: &mockFeatureService{
+				deleteFunc: func(_ context.Context, p feature.DeleteFlagParams) error {
+					if p.Key != "sync_v2" || p.UserID != userID {
+						return errors.New("unexpected flag")
+					}
+					return nil
+				},
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "invalid user id",
+			query:          "?user_id=invalid",
+			mockService:    &mockFeatureService{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "not found",
+			mockService: &mockFeatureService{
+				deleteFunc: func(context.Context, feature.DeleteFlagParams) error {
+					return feature.ErrFlagNotFound
+				},
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodDelete, "/admin/features/sync_v2"+tt.query, nil)
+			c.Params = gin.Params{{Key: "key", Value: "sync_v2"}}
+
+			NewHandler(nil, nil, tt.mockService, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil).DeleteFeatureFlag(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestHandler_GetCryptoDiagnostics(t *testing.T) {
+	t.Parallel()
+
+	report := &diagnostics.CryptoReport{
+		AESHardware:   true,
+		DefaultCipher: "aes-gcm",
+		PayloadSize:   1 << 20,
+		Results: []diagnostics.CipherThroughput{
+			{Cipher: "aes-gcm", EncryptMBPerSec: 2048, DecryptMBPerSec: 2150},
+			{Cipher: "chacha20-poly1305", EncryptMBPerSec: 1400, DecryptMBPerSec: 1450},
+		},
+	}
+
+	tests := []struct {
+		mockService    *mockDiagnosticsService
+		want           *CryptoDiagnostics
+		name           string
+		expectedStatus int
+	}{
+		{
+			name: "success",
+			mockService: &mockDiagnosticsService{
+				cryptoFunc: func(context.Context) (*diagnostics.CryptoReport, error) {
+					return report, nil
+				},
+			},
+			want: &CryptoDiagnostics{
+				AESHardware:   true,
+				DefaultCipher: "aes-gcm",
+				PayloadSize:   1 << 20,
+				Results: []CipherThroughput{
+					{Cipher: "aes-gcm", EncryptMBPerSec: 2048, DecryptMBPerSec: 2150},
+					{Cipher: "chacha20-poly1305", EncryptMBPerSec: 1400, DecryptMBPerSec: 1450},
+				},
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "technical error",
+			mockService: &mockDiagnosticsService{
+				cryptoFunc: func(context.Context) (*diagnostics.CryptoReport, error) {
+					return nil, fmt.Errorf("measure: %w", diagnostics.ErrDiagnosticsTechError)
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/admin/crypto", nil)
+
+			NewHandler(nil, nil, nil, tt.mockService, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil).GetCryptoDiagnostics(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.want != nil {
+				var got CryptoDiagnostics
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+				assert.Equal(t, tt.want, &got)
+			}
+		})
+	}
+}
+
+func TestHandler_GetStorageReport(t *testing.T) {
+	t.Parallel()
+
+	checkedAt := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	userID := uuid.New()
+	fileID := uuid.New()
+
+	tests := []struct {
+		mockService    *mockStorageService
+		want           *StorageReport
+		name           string
+		expectedStatus int
+	}{
+		{
+			name: "success",
+			mockService: &mockStorageService{
+				detectFunc: func(context.Context) (*storagegc.Report, error) {
+					return &storagegc.Report{
+						CheckedAt:    checkedAt,
+						OrphanBlobs:  []storagegc.OrphanBlob{{UserID: userID, StorageKey: "a.bin", Size: 10, ModTime: checkedAt}},
+						MissingBlobs: []storagegc.MissingBlob{{UserID: userID, FileID: fileID, StorageKey: "b.txt"}},
+						Blobs:        1,
+						Files:        1,
+					}, nil
+				},
+			},
+			want: &StorageReport{
+				CheckedAt:    checkedAt,
+				OrphanBlobs:  []OrphanBlob{{UserID: userID, StorageKey: "a.bin", Size: 10, ModTime: checkedAt}},
+				MissingBlobs: []MissingBlob{{UserID: userID, FileID: fileID, StorageKey: "b.txt"}},
+				SkippedUsers: []uuid.UUID{},
+				Blobs:        1,
+				Files:        1,
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "technical error",
+			mockService: &mockStorageService{
+				detectFunc: func(context.Context) (*storagegc.Report, error) {
+					return nil, fmt.Errorf("scan: %w", storagegc.ErrStorageGCTechError)
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/admin/storage", nil)
+
+			NewHandler(nil, nil, nil, nil, nil, tt.mockService, nil, nil, nil, nil, nil, nil, nil, nil).GetStorageReport(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.want != nil {
+				var got StorageReport
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+				assert.Equal(t, tt.want, &got)
+			}
+		})
+	}
+}
+
+func TestHandler_GetStats(t *testing.T) {
+	t.Parallel()
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(7 * 24 * time.Hour)
+	rollup := &stats.Rollup{
+		RolledUpAt:   from.Add(time.Hour),
+		Items:        map[string]int64{"notes": 4},
+		StorageTiers: map[string]int64{"under_1mib": 2},
+		Users:        2,
+		Requests:     200,
+		ServerErrors: 5,
+	}
+
+	tests := []struct {
+		mockService    *mockStatsService
+		want           *StatsReport
+		name           string
+		query          string
+		expectedStatus int
+	}{
+		{
+			name:  "success",
+			query: "?from=2025-01-01T00:00:00Z&to=2025-01-08T00:00:00Z",
+			mockService: &mockStatsService{
+				reportFunc: func(_ context.Context, params stats.ReportParams) (*stats.Report, error) {
+					assert.True(t, from.Equal(params.From))
+					assert.True(t, to.Equal(params.To))
+					return &stats.Report{
+						From:         from,
+						To:           to,
+						Latest:       rollup,
+						Rollups:      []*stats.Rollup{rollup},
+						Requests:     200,
+						ServerErrors: 5,
+					}, nil
+				},
+			},
+			want: &StatsReport{
+				From: from,
+				To:   to,
+				Latest: &StatsRollup{
+					RolledUpAt:   from.Add(time.Hour),
+					Items:        map[string]int64{"notes": 4},
+					StorageTiers: map[string]int64{"under_1mib": 2},
+					Users:        2,
+					Requests:     200,
+					ServerErrors: 5,
+					ErrorRate:    0.025,
+				},
+				Rollups: []*StatsRollup{{
+					RolledUpAt:   from.Add(time.Hour),
+					Items:        map[string]int64{"notes": 4},
+					StorageTiers: map[string]int64{"under_1mib": 2},
+					Users:        2,
+					Requests:     200,
+					ServerErrors: 5,
+					ErrorRate:    0.025,
+				}},
+				Requests:     200,
+				ServerErrors: 5,
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid time",
+			query:          "?from=yesterday",
+			mockService:    &mockStatsService{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "invalid period",
+			query: "?from=2025-01-08T00:00:00Z&to=2025-01-01T00:00:00Z",
+			mockService: &mockStatsService{
+				reportFunc: func(context.Context, stats.ReportParams) (*stats.Report, error) {
+					return nil, stats.ErrStatsPeriodInvalid
+				},
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "technical error",
+			mockService: &mockStatsService{
+				reportFunc: func(context.Context, stats.ReportParams) (*stats.Report, error) {
+					return nil, fmt.Errorf("load: %w", stats.ErrStatsTechError)
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/admin/stats"+tt.query, nil)
+
+			NewHandler(nil, nil, nil, nil, nil, nil, tt.mockService, nil, nil, nil, nil, nil, nil, nil).GetStats(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.want != nil {
+				var got StatsReport
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+				assert.Equal(t, tt.want, &got)
+			}
+		})
+	}
+}
+
+func TestHandler_GetLicense(t *testing.T) {
+	t.Parallel()
+
+	expiresAt := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		mockService    *mockLicenseService
+		want           *LicenseStatus
+		name           string
+		expectedStatus int
+	}{
+		{
+			name: "license in grace period",
+			mockService: &mockLicenseService{
+				statusFunc: func(context.Context) (*license.Status, error) {
+					return &license.Status{
+						ExpiresAt:  expiresAt,
+						GraceUntil: expiresAt.Add(14 * 24 * time.Hour),
+						ID:         "lic-1",
+						Licensee:   "Example Corp",
+						State:      "grace",
+						Features:   []string{"scim"},
+						Available:  []string{"scim"},
+						Enforced:   true,
+					}, nil
+				},
+			},
+			want: &LicenseStatus{
+				ExpiresAt:  expiresAt,
+				GraceUntil: expiresAt.Add(14 * 24 * time.Hour),
+				ID:         "lic-1",
+				Licensee:   "Example Corp",
+				State:      "grace",
+				Features:   []string{"scim"},
+				Available:  []string{"scim"},
+				Enforced:   true,
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "no license",
+			mockService: &mockLicenseService{
+				statusFunc: func(context.Context) (*license.Status, error) {
+					return &license.Status{State: license.StateNone, Available: []string{}, Enforced: true}, nil
+				},
+			},
+			want: &LicenseStatus{
+				State:     license.StateNone,
+				Features:  []string{},
+				Available: []string{},
+				Enforced:  true,
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "technical error",
+			mockService: &mockLicenseService{
+				statusFunc: func(context.Context) (*license.Status, error) {
+					return nil, fmt.Errorf("load: %w", license.ErrLicenseTechError)
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/admin/license", nil)
+
+			NewHandler(nil, nil, nil, nil, nil, nil, nil, tt.mockService, nil, nil, nil, nil, nil, nil).GetLicense(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.want != nil {
+				var got LicenseStatus
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+				assert.Equal(t, tt.want, &got)
+			}
+		})
+	}
+}
+
+func TestHandler_InstallLicense(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		installErr     error
+		name           string
+		body           string
+		wantKey        string
+		expectedStatus int
+	}{
+		{
+			name:           "success",
+			body:           `{"key":"AVK1.payload.signature"}`,
+			wantKey:        "AVK1.payload.signature",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "missing key",
+			body:           `{}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid key",
+			installErr:     errors.Join(license.ErrLicenseInvalid, errors.New("signature invalid")),
+			body:           `{"key":"AVK1.payload.forged"}`,
+			wantKey:        "AVK1.payload.forged",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "expired license",
+			installErr:     fmt.Errorf("license lic-1: %w", license.ErrLicenseExpired),
+			body:           `{"key":"AVK1.payload.signature"}`,
+			wantKey:        "AVK1.payload.signature",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "verification not configured",
+			installErr:     license.ErrLicenseNotConfigured,
+			body:           `{"key":"AVK1.payload.signature"}`,
+			wantKey:        "AVK1.payload.signature",
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "technical error",
+			installErr:     errors.Join(license.ErrLicenseTechError, errors.New("connection refused")),
+			body:           `{"key":"AVK1.payload.signature"}`,
+			wantKey:        "AVK1.payload.signature",
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// installed holds the key passed to the service.
+			var installed string
+			m := &mockLicenseService{
+				installFunc: func(_ context.Context, params license.InstallParams) (*license.Status, error) {
+					installed = params.Key
+					if tt.installErr != nil {
+						return nil, tt.installErr
+					}
+					return &license.Status{ID: "lic-1", State: "valid", Available: []string{"sso"}}, nil
+				},
+			}
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPut, "/admin/license", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			NewHandler(nil, nil, nil, nil, nil, nil, nil, m, nil, nil, nil, nil, nil, nil).InstallLicense(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, tt.wantKey, installed)
+			if tt.expectedStatus == http.StatusOK {
+				var got LicenseStatus
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+				assert.Equal(t, "lic-1", got.ID)
+				assert.Equal(t, []string{"sso"}, got.Available)
+			}
+		})
+	}
+}
+
+func TestHandler_ListAccessPolicies(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	policyID := uuid.New()
+
+	tests := []struct {
+		mockService    *mockAccessPolicyService
+		name           string
+		query          string
+		wantCount      int
+		expectedStatus int
+	}{
+		{
+			name:  "filter by user",
+			query: "?user_id=" + userID.String(),
+			mockService: &mockAccessPolicyService{
+				listFunc: func(_ context.Context, p accesspolicy.ListParams) ([]*accesspolicy.Policy, error) {
+					if p.UserID != userID || p.Global {
+						return nil, errors.New("unexpected filter")
+					}
+					return []*accesspolicy.Policy{{ID: policyID, UserID: userID, Days: []string{"mon"}}}, nil
+				},
+			},
+			wantCount:      1,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid user id",
+			query:          "?user_id=invalid",
+			mockService:    &mockAccessPolicyService{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "technical error",
+			mockService: &mockAccessPolicyService{
+				listFunc: func(context.Context, accesspolicy.ListParams) ([]*accesspolicy.Policy, error) {
+					return nil, accesspolicy.ErrAccessPolicyTechError
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/admin/access-policies"+tt.query, nil)
+
+			NewHandler(nil, nil, nil, nil, tt.mockService, nil, nil, nil, nil, nil, nil, nil, nil, nil).ListAccessPolicies(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.wantCount > 0 {
+				var got ListAccessPoliciesResponse
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+				require.Len(t, got.Policies, tt.wantCount)
+				assert.Equal(t, policyID, got.Policies[0].ID)
+			}
+		})
+	}
+}
+
+func TestHandler_AddAccessPolicy(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		mockService    *mockAccessPolicyService
+		name           string
+		body           string
+		expectedStatus int
+	}{
+		{
+			name: "global policy is managed",
+			body: `{"days":["mon","tue"],"start":"22:00","end":"06:00"}`,
+			mockService: &mockAccessPolicyService{
+				addFunc: func(_ context.Context, p accesspolicy.AddParams) (*accesspolicy.Policy, error) {
+					if !p.Managed || p.UserID != uuid.Nil || p.Start != "22:00" {
+						return nil, errors.New("unexpected params")
+					}
+					return &accesspolicy.Policy{ID: uuid.New(), Managed: true}, nil
+				},
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "missing start",
+			body:           `{"days":["mon"],"end":"06:00"}`,
+			mockService:    &mockAccessPolicyService{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "invalid time",
+			body: `{"days":["mon"],"start":"9am","end":"18:00"}`,
+			mockService: &mockAccessPolicyService{
+				addFunc: func(context.Context, accesspolicy.AddParams) (*accesspolicy.Policy, error) {
+					return nil, errors.Join(accesspolicy.ErrAccessPolicyAppError, accesspolicy.ErrPolicyIncorrectTime)
+				},
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/admin/access-policies", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			NewHandler(nil, nil, nil, nil, tt.mockService, nil, nil, nil, nil, nil, nil, nil, nil, nil).AddAccessPolicy(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestHandler_DeleteAccessPolicy(t *testing.T) {
+	t.Parallel()
+
+	policyID := uuid.New()
+
+	tests := []struct {
+		mockService    *mockAccessPolicyService
+		name           string
+		id             string
+		expectedStatus int
+	}{
+		{
+			name: "success",
+			id:   policyID.String(),
+			mockService: &mockAccessPolicyService{
+				deleteFunc: func(_ context.Context, p accesspolicy.DeleteParams) error {
+					if p.ID != policyID || p.UserID != uuid.Nil {
+						return errors.New("unexpected params")
+					}
+					return nil
+				},
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "invalid id",
+			id:             "invalid",
+			mockService:    &mockAccessPolicyService{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "not found",
+			id:   policyID.String(),
+			mockService: &mockAccessPolicyService{
+				deleteFunc: func(context.Context, accesspolicy.DeleteParams) error {
+					return accesspolicy.ErrPolicyNotFound
+				},
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodDelete, "/admin/access-policies/"+tt.id, nil)
+			c.Params = gin.Params{{Key: "id", Value: tt.id}}
+
+			NewHandler(nil, nil, nil, nil, tt.mockService, nil, nil, nil, nil, nil, nil, nil, nil, nil).DeleteAccessPolicy(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestHandler_GetTelemetry(t *testing.T) {
+	t.Parallel()
+
+	sentAt := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		mockService    *mockTelemetryService
+		name           string
+		wantBody       string
+		expectedStatus int
+	}{
+		{
+			name: "enabled telemetry",
+			mockService: &mockTelemetryService{
+				previewFunc: func(context.Context) (*telemetry.Preview, error) {
+					return &telemetry.Preview{
+						LastSentAt: sentAt,
+						Endpoint:   "https://telemetry.example.com/v1/ping",
+						Payload:    []byte(`{"schema":1,"users":"1-10","features":[]}`),
+						Interval:   24 * time.Hour,
+						Enabled:    true,
+					}, nil
+				},
+			},
+			wantBody: `{
+				"last_sent_at":"2026-10-15T12:00:00Z","endpoint":"https://telemetry.example.com/v1/ping",
+				"payload":{"schema":1,"users":"1-10","features":[]},"interval_seconds":86400,"enabled":true
+			}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "disabled telemetry",
+			mockService: &mockTelemetryService{
+				previewFunc: func(context.Context) (*telemetry.Preview, error) {
+					return &telemetry.Preview{Payload: []byte(`{"schema":1}`), Interval: time.Hour}, nil
+				},
+			},
+			wantBody:       `{"endpoint":"","payload":{"schema":1},"interval_seconds":3600,"enabled":false}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "technical error",
+			mockService: &mockTelemetryService{
+				previewFunc: func(context.Context) (*telemetry.Preview, error) {
+					return nil, fmt.Errorf("collect: %w", telemetry.ErrTelemetryTechError)
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/admin/telemetry", nil)
+
+			NewHandler(nil, nil, nil, nil, nil, nil, nil, nil, tt.mockService, nil, nil, nil, nil, nil).GetTelemetry(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.wantBody != "" {
+				assert.JSONEq(t, tt.wantBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandler_GetUpdate(t *testing.T) {
+	t.Parallel()
+
+	checkedAt := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		mockService    *mockUpdateService
+		name           string
+		wantBody       string
+		expectedStatus int
+	}{
+		{
+			name: "update available",
+			mockService: &mockUpdateService{
+				statusFunc: func(context.Context) (*updatecheck.Status, error) {
+					return &updatecheck.Status{
+						CheckedAt:  checkedAt,
+						ReleasedAt: checkedAt.Add(-14 * 24 * time.Hour),
+						Current:    "v1.4.2",
+						Latest:     "v1.5.0",
+						URL:        "https://example.com/releases/v1.5.0",
+						Available:  true,
+						Enabled:    true,
+					}, nil
+				},
+			},
+			wantBody: `{
+				"checked_at":"2026-10-15T12:00:00Z","released_at":"2026-10-01T12:00:00Z","current":"v1.4.2",
+				"latest":"v1.5.0","url":"https://example.com/releases/v1.5.0","available":true,"enabled":true
+			}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "update checks disabled",
+			mockService: &mockUpdateService{
+				statusFunc: func(context.Context) (*updatecheck.Status, error) {
+					return &updatecheck.Status{Current: "v1.4.2"}, nil
+				},
+			},
+			wantBody:       `{"current":"v1.4.2","available":false,"enabled":false}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "technical error",
+			mockService: &mockUpdateService{
+				statusFunc: func(context.Context) (*updatecheck.Status, error) {
+					return nil, errors.New("unexpected")
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/admin/update", nil)
+
+			NewHandler(nil, nil, nil, nil, nil, nil, nil, nil, nil, tt.mockService, nil, nil, nil, nil).GetUpdate(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.wantBody != "" {
+				assert.JSONEq(t, tt.wantBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandler_StartRecording(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	startedAt := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		mockService    *mockRecordingService
+		name           string
+		body           string
+		wantBody       string
+		expectedStatus int
+	}{
+		{
+			name: "user on route",
+			body: `{"user_id":"` + userID.String() + `","route":"/api/items","reason":"case 42","duration_seconds":600}`,
+			mockService: &mockRecordingService{
+				startFunc: func(_ context.Context, p recording.StartParams) (*recording.Session, error) {
+					if p.UserID != userID || p.Route != "/api/items" || p.Duration != 10*time.Minute {
+						return nil, errors.New("unexpected params")
+					}
+					return &recording.Session{
+						StartedAt: startedAt,
+						ExpiresAt: startedAt.Add(p.Duration),
+						ID:        userID,
+						UserID:    p.UserID,
+						Route:     p.Route,
+						Reason:    p.Reason,
+						Active:    true,
+					}, nil
+				},
+			},
+			wantBody: `{
+				"started_at":"2026-10-15T12:00:00Z","expires_at":"2026-10-15T12:10:00Z","route":"/api/items",
+				"reason":"case 42","id":"` + userID.String() + `","user_id":"` + userID.String() + `",
+				"exchanges":0,"active":true
+			}`,
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "missing duration",
+			body:           `{"route":"/api/items"}`,
+			mockService:    &mockRecordingService{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "duration too long",
+			body: `{"route":"/api/items","duration_seconds":86400}`,
+			mockService: &mockRecordingService{
+				startFunc: func(context.Context, recording.StartParams) (*recording.Session, error) {
+					return nil, fmt.Errorf("%w: must be at most 1h0m0s", recording.ErrRecordingInvalidDuration)
+				},
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "recording disabled",
+			body: `{"route":"/api/items","duration_seconds":600}`,
+			mockService: &mockRecordingService{
+				startFunc: func(context.Context, recording.StartParams) (*recording.Session, error) {
+					return nil, recording.ErrRecordingDisabled
+				},
+			},
+			expectedStatus: http.StatusConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/admin/recordings", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			NewHandler(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, tt.mockService, nil, nil, nil).StartRecording(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.wantBody != "" {
+				assert.JSONEq(t, tt.wantBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandler_StopRecording(t *testing.T) {
+	t.Parallel()
+
+	recordingID := uuid.New()
+
+	tests := []struct {
+		mockService    *mockRecordingService
+		name           string
+		id             string
+		expectedStatus int
+	}{
+		{
+			name:           "success",
+			id:             recordingID.String(),
+			mockService:    &mockRecordingService{},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid id",
+			id:             "invalid",
+			mockService:    &mockRecordingService{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "not found",
+			id:   recordingID.String(),
+			mockService: &mockRecordingService{
+				stopFunc: func(context.Context, uuid.UUID) (*recording.Session, error) {
+					return nil, recording.ErrRecordingNotFound
+				},
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/admin/recordings/"+tt.id+"/stop", nil)
+			c.Params = gin.Params{{Key: "id", Value: tt.id}}
+
+			NewHandler(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, tt.mockService, nil, nil, nil).StopRecording(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestHandler_DeleteRecording(t *testing.T) {
+	t.Parallel()
+
+	recordingID := uuid.New()
+
+	tests := []struct {
+		mockService    *mockRecordingService
+		name           string
+		id             string
+		expectedStatus int
+	}{
+		{
+			name: "success",
+			id:   recordingID.String(),
+			mockService: &mockRecordingService{
+				deleteFunc: func(_ context.Context, id uuid.UUID) error {
+					if id != recordingID {
+						return errors.New("unexpected id")
+					}
+					return nil
+				},
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name: "not found",
+			id:   recordingID.String(),
+			mockService: &mockRecordingService{
+				deleteFunc: func(context.Context, uuid.UUID) error {
+					return recording.ErrRecordingNotFound
+				},
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodDelete, "/admin/recordings/"+tt.id, nil)
+			c.Params = gin.Params{{Key: "id", Value: tt.id}}
+
+			NewHandler(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, tt.mockService, nil, nil, nil).DeleteRecording(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestHandler_ListRecordedExchanges(t *testing.T) {
+	t.Parallel()
+
+	recordingID := uuid.New()
+	exchangeID := uuid.New()
+	recordedAt := time.Date(2026, 10, 15, 12, 5, 0, 0, time.UTC)
+
+	tests := []struct {
+		mockService    *mockRecordingService
+		name           string
+		wantBody       string
+		expectedStatus int
+	}{
+		{
+			name: "exchanges listed",
+			mockService: &mockRecordingService{
+				exchangesFunc: func(context.Context, uuid.UUID) ([]*recording.Exchange, error) {
+					return []*recording.Exchange{{
+						RecordedAt:     recordedAt,
+						RequestHeaders: map[string]string{"Authorization": recording.Redacted},
+						ID:             exchangeID,
+						Method:         http.MethodPost,
+						Path:           "/api/items/bankcards",
+						RequestBody:    `{"number":"[REDACTED]"}`,
+						Duration:       42 * time.Millisecond,
+						Status:         http.StatusBadRequest,
+					}}, nil
+				},
+			},
+			wantBody: `{"exchanges":[{
+				"recorded_at":"2026-10-15T12:05:00Z","request_headers":{"Authorization":"[REDACTED]"},
+				"method":"POST","path":"/api/items/bankcards","request_body":"{\"number\":\"[REDACTED]\"}",
+				"id":"` + exchangeID.String() + `","duration_ms":42,"status":400
+			}]}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "no exchanges",
+			mockService:    &mockRecordingService{},
+			wantBody:       `{"exchanges":[]}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "not found",
+			mockService: &mockRecordingService{
+				exchangesFunc: func(context.Context, uuid.UUID) ([]*recording.Exchange, error) {
+					return nil, recording.ErrRecordingNotFound
+				},
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/admin/recordings/"+recordingID.String()+"/exchanges", nil)
+			c.Params = gin.Params{{Key: "id", Value: recordingID.String()}}
+
+			NewHandler(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, tt.mockService, nil, nil, nil).ListRecordedExchanges(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.wantBody != "" {
+				assert.JSONEq(t, tt.wantBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandler_GetRetention(t *testing.T) {
+	t.Parallel()
+
+	groupID := uuid.New()
+	updatedAt := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		mockService    *mockRetentionService
+		name           string
+		wantBody       string
+		expectedStatus int
+	}{
+		{
+			name: "policies retrieved",
+			mockService: &mockRetentionService{
+				policiesFunc: func(context.Context) (*retention.Policies, error) {
+					return &retention.Policies{
+						Defaults: retention.Policy{KeepVersions: 10},
+						Overrides: []*retention.Override{{
+							UpdatedAt: updatedAt,
+							Policy:    retention.Policy{KeepAuditDays: 365},
+							GroupID:   groupID,
+						}},
+					}, nil
+				},
+			},
+			wantBody: `{"defaults":{"keep_versions":10,"keep_audit_days":0},"overrides":[{
+				"updated_at":"2026-10-15T12:00:00Z","keep_versions":0,"keep_audit_days":365,
+				"group_id":"` + groupID.String() + `"
+			}]}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "service error",
+			mockService: &mockRetentionService{
+				policiesFunc: func(context.Context) (*retention.Policies, error) {
+					return nil, retention.ErrRetentionTechError
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/admin/retention", nil)
+
+			NewHandler(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, tt.mockService, nil, nil).GetRetention(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.wantBody != "" {
+				assert.JSONEq(t, tt.wantBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandler_SetRetentionOverride(t *testing.T) {
+	t.Parallel()
+
+	groupID := uuid.New()
+
+	tests := []struct {
+		mockService    *mockRetentionService
+		name           string
+		id             string
+		body           string
+		expectedStatus int
+	}{
+		{
+			name: "success",
+			id:   groupID.String(),
+			body: `{"keep_versions":5,"keep_audit_days":30}`,
+			mockService: &mockRetentionService{
+				setFunc: func(_ context.Context, params retention.SetOverrideParams) (*retention.Override, error) {
+					if params.GroupID != groupID || params.KeepVersions != 5 || params.KeepAuditDays != 30 {
+						return nil, errors.New("unexpected params")
+					}
+					return &retention.Override{GroupID: params.GroupID, Policy: params.Policy}, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid id",
+			id:             "invalid",
+			body:           `{"keep_versions":5}`,
+			mockService:    &mockRetentionService{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid body",
+			id:             groupID.String(),
+			body:           `{"keep_versions":"five"}`,
+			mockService:    &mockRetentionService{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "invalid policy",
+			id:   groupID.String(),
+			body: `{"keep_versions":-1}`,
+			mockService: &mockRetentionService{
+				setFunc: func(context.Context, retention.SetOverrideParams) (*retention.Override, error) {
+					return nil, retention.ErrIncorrectKeepVersions
+				},
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "group not found",
+			id:   groupID.String(),
+			body: `{"keep_versions":5}`,
+			mockService: &mockRetentionService{
+				setFunc: func(context.Context, retention.SetOverrideParams) (*retention.Override, error) {
+					return nil, retention.ErrGroupNotFound
+				},
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPut, "/admin/retention/groups/"+tt.id, strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Params = gin.Params{{Key: "id", Value: tt.id}}
+
+			NewHandler(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, tt.mockService, nil, nil).SetRetentionOverride(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestHandler_DeleteRetentionOverride(t *testing.T) {
+	t.Parallel()
+
+	groupID := uuid.New()
+
+	tests := []struct {
+		mockService    *mockRetentionService
+		name           string
+		id             string
+		expectedStatus int
+	}{
+		{
+			name:           "success",
+			id:             groupID.String(),
+			mockService:    &mockRetentionService{},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "invalid id",
+			id:             "invalid",
+			mockService:    &mockRetentionService{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "not found",
+			id:   groupID.String(),
+			mockService: &mockRetentionService{
+				deleteFunc: func(context.Context, retention.DeleteOverrideParams) error {
+					return retention.ErrOverrideNotFound
+				},
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodDelete, "/admin/retention/groups/"+tt.id, nil)
+			c.Params = gin.Params{{Key: "id", Value: tt.id}}
+
+			h := NewHandler(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, tt.mockService, nil, nil)
+			h.DeleteRetentionOverride(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestHandler_PreviewRetention(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		mockService    *mockRetentionService
+		name           string
+		wantBody       string
+		expectedStatus int
+	}{
+		{
+			name: "preview computed",
+			mockService: &mockRetentionService{
+				previewFunc: func(context.Context) (*retention.Report, error) {
+					return &retention.Report{
+						Scopes: []*retention.Scope{
+							{Policy: retention.Policy{KeepVersions: 10}, Default: true, Versions: 7},
+							{Policy: retention.Policy{KeepAuditDays: 30}, Users: 2, AuditRecords: 4},
+						},
+						Versions:     7,
+						AuditRecords: 4,
+					}, nil
+				},
+			},
+			wantBody: `{"scopes":[
+				{"policy":{"keep_versions":10,"keep_audit_days":0},"versions":7,"audit_records":0,"default":true},
+				{"policy":{"keep_versions":0,"keep_audit_days":30},"users":2,"versions":0,"audit_records":4,
+				"default":false}
+			],"versions":7,"audit_records":4}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "service error",
+			mockService: &mockRetentionService{
+				previewFunc: func(context.Context) (*retention.Report, error) {
+					return nil, retention.ErrRetentionTechError
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/admin/retention/preview", nil)
+
+			NewHandler(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, tt.mockService, nil, nil).PreviewRetention(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.wantBody != "" {
+				assert.JSONEq(t, tt.wantBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandler_ListLegalHolds(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	holdID := uuid.New()
+	placedAt := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		mockService    *mockLegalHoldService
+		name           string
+		query          string
+		wantBody       string
+		expectedStatus int
+	}{
+		{
+			name:  "holds of a user",
+			query: "?user_id=" + userID.String(),
+			mockService: &mockLegalHoldService{
+				listFunc: func(_ context.Context, params legalhold.ListParams) ([]*legalhold.Hold, error) {
+					if params.UserID != userID {
+						return nil, errors.New("unexpected params")
+					}
+					return []*legalhold.Hold{{ID: holdID, UserID: userID, Reason: "Case 42", PlacedAt: placedAt}}, nil
+				},
+			},
+			wantBody: fmt.Sprintf(`{"holds":[{"id":%q,"user_id":%q,"reason":"Case 42",`+
+				`"placed_at":"2026-10-15T12:00:00Z"}]}`, holdID, userID),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "no holds",
+			mockService:    &mockLegalHoldService{},
+			wantBody:       `{"holds":[]}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid user ID",
+			query:          "?user_id=invalid",
+			mockService:    &mockLegalHoldService{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "service error",
+			mockService: &mockLegalHoldService{
+				listFunc: func(context.Context, legalhold.ListParams) ([]*legalhold.Hold, error) {
+					return nil, legalhold.ErrLegalHoldTechError
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/admin/legal-holds"+tt.query, nil)
+
+			NewHandler(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, tt.mockService, nil).ListLegalHolds(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.wantBody != "" {
+				assert.JSONEq(t, tt.wantBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandler_PlaceLegalHold(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	itemID := uuid.New()
+
+	tests := []struct {
+		mockService    *mockLegalHoldService
+		name           string
+		body           string
+		expectedStatus int
+	}{
+		{
+			name: "success",
+			body: fmt.Sprintf(`{"user_id":%q,"item_id":%q,"reason":"Case 42"}`, userID, itemID),
+			mockService: &mockLegalHoldService{
+				placeFunc: func(_ context.Context, params legalhold.PlaceParams) (*legalhold.Hold, error) {
+					if params.UserID != userID || params.ItemID != itemID || params.Reason != "Case 42" {
+						return nil, errors.New("unexpected params")
+					}
+					return &legalhold.Hold{ID: uuid.New(), UserID: userID, ItemID: itemID, Reason: "Case 42"}, nil
+				},
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "missing reason",
+			body:           fmt.Sprintf(`{"user_id":%q}`, userID),
+			mockService:    &mockLegalHoldService{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid user ID",
+			body:           `{"user_id":"invalid","reason":"Case 42"}`,
+			mockService:    &mockLegalHoldService{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "user not found",
+			body: fmt.Sprintf(`{"user_id":%q,"reason":"Case 42"}`, userID),
+			mockService: &mockLegalHoldService{
+				placeFunc: func(context.Context, legalhold.PlaceParams) (*legalhold.Hold, error) {
+					return nil, legalhold.ErrUserNotFound
+				},
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name: "reason too long",
+			body: fmt.Sprintf(`{"user_id":%q,"reason":"Case 42"}`, userID),
+			mockService: &mockLegalHoldService{
+				placeFunc: func(context.Context, legalhold.PlaceParams) (*legalhold.Hold, error) {
+					return nil, legalhold.ErrIncorrectReason
+				},
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/admin/legal-holds", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			NewHandler(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, tt.mockService, nil).PlaceLegalHold(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestHandler_ReleaseLegalHold(t *testing.T) {
+	t.Parallel()
+
+	holdID := uuid.New()
+
+	tests := []struct {
+		mockService    *mockLegalHoldService
+		name           string
+		id             string
+		expectedStatus int
+	}{
+		{
+			name: "success",
+			id:   holdID.String(),
+			mockService: &mockLegalHoldService{
+				releaseFunc: func(_ context.Context, params legalhold.ReleaseParams) error {
+					if params.ID != holdID {
+						return errors.New("unexpected params")
+					}
+					return nil
+				},
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "invalid id",
+			id:             "invalid",
+			mockService:    &mockLegalHoldService{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "hold not found",
+			id:   holdID.String(),
+			mockService: &mockLegalHoldService{
+				releaseFunc: func(context.Context, legalhold.ReleaseParams) error {
+					return legalhold.ErrHoldNotFound
+				},
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodDelete, "/admin/legal-holds/"+tt.id, nil)
+			c.Params = gin.Params{{Key: "id", Value: tt.id}}
+
+			NewHandler(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, tt.mockService, nil).ReleaseLegalHold(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestHandler_ListItemPolicies(t *testing.T) {
+	t.Parallel()
+
+	policyID, groupID := uuid.New(), uuid.New()
+	createdAt := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		mockService    *mockItemPolicyService
+		name           string
+		wantBody       string
+		expectedStatus int
+	}{
+		{
+			name: "policies",
+			mockService: &mockItemPolicyService{
+				listFunc: func(context.Context) ([]*itempolicy.Policy, error) {
+					return []*itempolicy.Policy{{
+						ID:        policyID,
+						GroupID:   groupID,
+						Name:      "Finance",
+						Rules:     itempolicy.Rules{ForbiddenKinds: []string{"bankcard"}, MinPasswordLength: 12},
+						CreatedAt: createdAt,
+						UpdatedAt: createdAt,
+					}}, nil
+				},
+			},
+			wantBody: fmt.Sprintf(`{"policies":[{"id":%q,"group_id":%q,"name":"Finance","rules":{`+
+				`"forbidden_kinds":["bankcard"],"required_classes":[],"min_password_length":12,`+
+				`"ban_common_passwords":false},"created_at":"2026-10-15T12:00:00Z",`+
+				`"updated_at":"2026-10-15T12:00:00Z"}]}`, policyID, groupID),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "no policies",
+			mockService:    &mockItemPolicyService{},
+			wantBody:       `{"policies":[]}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "service error",
+			mockService: &mockItemPolicyService{
+				listFunc: func(context.Context) ([]*itempolicy.Policy, error) {
+					return nil, itempolicy.ErrItemPolicyTechError
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/admin/item-policies", nil)
+
+			NewHandler(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, tt.mockService).ListItemPolicies(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.wantBody != "" {
+				assert.JSONEq(t, tt.wantBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandler_CreateItemPolicy(t *testing.T) {
+	t.Parallel()
+
+	groupID := uuid.New()
+
+	tests := []struct {
+		mockService    *mockItemPolicyService
+		name           string
+		body           string
+		expectedStatus int
+	}{
+		{
+			name: "success",
+			body: fmt.Sprintf(`{"name":"Finance","group_id":%q,"rules":{"forbidden_kinds":["bankcard"],`+
+				`"required_classes":["digit"],"min_password_length":12,"ban_common_passwords":true}}`, groupID),
+			mockService: &mockItemPolicyService{
+				createFunc: func(_ context.Context, params itempolicy.CreateParams) (*itempolicy.Policy, error) {
+					want := itempolicy.CreateParams{
+						Name:    "Finance",
+						GroupID: groupID,
+						Rules: itempolicy.Rules{
+							ForbiddenKinds:     []string{"bankcard"},
+							RequiredClasses:    []string{"digit"},
+							MinPasswordLength:  12,
+							BanCommonPasswords: true,
+						},
+					}
+					if !assert.ObjectsAreEqual(want, params) {
+						return nil, errors.New("unexpected params")
+					}
+					return &itempolicy.Policy{ID: uuid.New(), Name: params.Name, Rules: params.Rules}, nil
+				},
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "missing name",
+			body:           `{"rules":{"min_password_length":12}}`,
+			mockService:    &mockItemPolicyService{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "invalid rules",
+			body: `{"name":"Finance","rules":{"forbidden_kinds":["totp"]}}`,
+			mockService: &mockItemPolicyService{
+				createFunc: func(context.Context, itempolicy.CreateParams) (*itempolicy.Policy, error) {
+					return nil, errors.Join(itempolicy.ErrIncorrectKind, itempolicy.ErrNoRules)
+				},
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "group not found",
+			body: fmt.Sprintf(`{"name":"Finance","group_id":%q,"rules":{"min_password_length":12}}`, groupID),
+			mockService: &mockItemPolicyService{
+				createFunc: func(context.Context, itempolicy.CreateParams) (*itempolicy.Policy, error) {
+					return nil, itempolicy.ErrGroupNotFound
+				},
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/admin/item-policies", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			NewHandler(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, tt.mockService).CreateItemPolicy(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestHandler_GetItemPolicy(t *testing.T) {
+	t.Parallel()
+
+	policyID := uuid.New()
+
+	tests := []struct {
+		mockService    *mockItemPolicyService
+		name           string
+		id             string
+		expectedStatus int
+	}{
+		{
+			name:           "success",
+			id:             policyID.String(),
+			mockService:    &mockItemPolicyService{},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid id",
+			id:             "invalid",
+			mockService:    &mockItemPolicyService{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "policy not found",
+			id:   policyID.String(),
+			mockService: &mockItemPolicyService{
+				getFunc: func(context.Context, itempolicy.GetParams) (*itempolicy.Policy, error) {
+					return nil, itempolicy.ErrPolicyNotFound
+				},
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/admin/item-policies/"+tt.id, nil)
+			c.Params = gin.Params{{Key: "id", Value: tt.id}}
+
+			NewHandler(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, tt.mockService).GetItemPolicy(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestHandler_UpdateItemPolicy(t *testing.T) {
+	t.Parallel()
+
+	policyID := uuid.New()
+
+	tests := []struct {
+		mockService    *mockItemPolicyService
+		name           string
+		id             string
+		body           string
+		expectedStatus int
+	}{
+		{
+			name: "success",
+			id:   policyID.String(),
+			body: `{"name":"Everyone","rules":{"ban_common_passwords":true}}`,
+			mockService: &mockItemPolicyService{
+				updateFunc: func(_ context.Context, params itempolicy.UpdateParams) (*itempolicy.Policy, error) {
+					if params.ID != policyID || params.Name != "Everyone" || !params.Rules.BanCommonPasswords {
+						return nil, errors.New("unexpected params")
+					}
+					return &itempolicy.Policy{ID: params.ID, Name: params.Name, Rules: params.Rules}, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid id",
+			id:             "invalid",
+			body:           `{"name":"Everyone","rules":{"ban_common_passwords":true}}`,
+			mockService:    &mockItemPolicyService{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid body",
+			id:             policyID.String(),
+			body:           `{"name":`,
+			mockService:    &mockItemPolicyService{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "policy not found",
+			id:   policyID.String(),
+			body: `{"name":"Everyone","rules":{"ban_common_passwords":true}}`,
+			mockService: &mockItemPolicyService{
+				updateFunc: func(context.Context, itempolicy.UpdateParams) (*itempolicy.Policy, error) {
+					return nil, itempolicy.ErrPolicyNotFound
+				},
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPut, "/admin/item-policies/"+tt.id, strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Params = gin.Params{{Key: "id", Value: tt.id}}
+
+			NewHandler(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, tt.mockService).UpdateItemPolicy(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestHandler_DeleteItemPolicy(t *testing.T) {
+	t.Parallel()
+
+	policyID := uuid.New()
+
+	tests := []struct {
+		mockService    *mockItemPolicyService
+		name           string
+		id             string
+		expectedStatus int
+	}{
+		{
+			name: "success",
+			id:   policyID.String(),
+			mockService: &mockItemPolicyService{
+				deleteFunc: func(_ context.Context, params itempolicy.DeleteParams) error {
+					if params.ID != policyID {
+						return errors.New("unexpected params")
+					}
+					return nil
+				},
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "invalid id",
+			id:             "invalid",
+			mockService:    &mockItemPolicyService{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "policy not found",
+			id:   policyID.String(),
+			mockService: &mockItemPolicyService{
+				deleteFunc: func(context.Context, itempolicy.DeleteParams) error {
+					return itempolicy.ErrPolicyNotFound
+				},
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodDelete, "/admin/item-policies/"+tt.id, nil)
+			c.Params = gin.Params{{Key: "id", Value: tt.id}}
+
+			NewHandler(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, tt.mockService).DeleteItemPolicy(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
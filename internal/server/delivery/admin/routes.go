@@ -0,0 +1,49 @@
+package admin
+
+import "github.com/gin-gonic/gin"
+
+// RegisterRoutes registers administrative routes with the provided router group.
+func RegisterRoutes(r *gin.RouterGroup, h *Handler) {
+	rulesGroup := r.Group("/access-rules")
+	rulesGroup.GET("", h.ListAccessRules)
+	rulesGroup.POST("", h.AddAccessRule)
+	rulesGroup.DELETE("/:id", h.DeleteAccessRule)
+	policiesGroup := r.Group("/access-policies")
+	policiesGroup.GET("", h.ListAccessPolicies)
+	policiesGroup.POST("", h.AddAccessPolicy)
+	policiesGroup.DELETE("/:id", h.DeleteAccessPolicy)
+	r.GET("/maintenance", h.GetMaintenance)
+	r.PUT("/maintenance", h.SetMaintenance)
+	featuresGroup := r.Group("/features")
+	featuresGroup.GET("", h.ListFeatureFlags)
+	featuresGroup.PUT("/:key", h.SetFeatureFlag)
+	featuresGroup.DELETE("/:key", h.DeleteFeatureFlag)
+	r.GET("/crypto", h.GetCryptoDiagnostics)
+	r.GET("/storage", h.GetStorageReport)
+	r.GET("/stats", h.GetStats)
+	r.GET("/license", h.GetLicense)
+	r.PUT("/license", h.InstallLicense)
+	r.GET("/telemetry", h.GetTelemetry)
+	r.GET("/update", h.GetUpdate)
+	recordingsGroup := r.Group("/recordings")
+	recordingsGroup.GET("", h.ListRecordings)
+	recordingsGroup.POST("", h.StartRecording)
+	recordingsGroup.POST("/:id/stop", h.StopRecording)
+	recordingsGroup.DELETE("/:id", h.DeleteRecording)
+	recordingsGroup.GET("/:id/exchanges", h.ListRecordedExchanges)
+	retentionGroup := r.Group("/retention")
+	retentionGroup.GET("", h.GetRetention)
+	retentionGroup.GET("/preview", h.PreviewRetention)
+	retentionGroup.PUT("/groups/:id", h.SetRetentionOverride)
+	retentionGroup.DELETE("/groups/:id", h.DeleteRetentionOverride)
+	holdsGroup := r.Group("/legal-holds")
+	holdsGroup.GET("", h.ListLegalHolds)
+	holdsGroup.POST("", h.PlaceLegalHold)
+	holdsGroup.DELETE("/:id", h.ReleaseLegalHold)
+	itemPoliciesGroup := r.Group("/item-policies")
+	itemPoliciesGroup.GET("", h.ListItemPolicies)
+	itemPoliciesGroup.POST("", h.CreateItemPolicy)
+	itemPoliciesGroup.GET("/:id", h.GetItemPolicy)
+	itemPoliciesGroup.PUT("/:id", h.UpdateItemPolicy)
+	itemPoliciesGroup.DELETE("/:id", h.DeleteItemPolicy)
+}
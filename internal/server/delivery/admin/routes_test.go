@@ -0,0 +1,74 @@
+package admin
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterRoutes(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	h := NewHandler(
+		&mockAdminService{},
+		&mockMaintenanceService{},
+		&mockFeatureService{},
+		&mockDiagnosticsService{},
+		&mockAccessPolicyService{},
+		&mockStorageService{},
+		&mockStatsService{},
+		&mockLicenseService{},
+		&mockTelemetryService{},
+		&mockUpdateService{},
+		&mockRecordingService{},
+		&mockRetentionService{},
+		&mockLegalHoldService{},
+		&mockItemPolicyService{},
+	)
+	RegisterRoutes(router.Group("/admin"), h)
+
+	got := make(map[string]string)
+	for _, route := range router.Routes() {
+		got[route.Method+" "+route.Path] = route.Path
+	}
+	assert.Len(t, got, 35)
+	assert.Contains(t, got, http.MethodGet+" /admin/access-rules")
+	assert.Contains(t, got, http.MethodPost+" /admin/access-rules")
+	assert.Contains(t, got, http.MethodDelete+" /admin/access-rules/:id")
+	assert.Contains(t, got, http.MethodGet+" /admin/access-policies")
+	assert.Contains(t, got, http.MethodPost+" /admin/access-policies")
+	assert.Contains(t, got, http.MethodDelete+" /admin/access-policies/:id")
+	assert.Contains(t, got, http.MethodGet+" /admin/maintenance")
+	assert.Contains(t, got, http.MethodPut+" /admin/maintenance")
+	assert.Contains(t, got, http.MethodGet+" /admin/features")
+	assert.Contains(t, got, http.MethodPut+" /admin/features/:key")
+	assert.Contains(t, got, http.MethodDelete+" /admin/features/:key")
+	assert.Contains(t, got, http.MethodGet+" /admin/crypto")
+	assert.Contains(t, got, http.MethodGet+" /admin/storage")
+	assert.Contains(t, got, http.MethodGet+" /admin/stats")
+	assert.Contains(t, got, http.MethodGet+" /admin/license")
+	assert.Contains(t, got, http.MethodPut+" /admin/license")
+	assert.Contains(t, got, http.MethodGet+" /admin/telemetry")
+	assert.Contains(t, got, http.MethodGet+" /admin/update")
+	assert.Contains(t, got, http.MethodGet+" /admin/recordings")
+	assert.Contains(t, got, http.MethodPost+" /admin/recordings")
+	assert.Contains(t, got, http.MethodPost+" /admin/recordings/:id/stop")
+	assert.Contains(t, got, http.MethodDelete+" /admin/recordings/:id")
+	assert.Contains(t, got, http.MethodGet+" /admin/recordings/:id/exchanges")
+	assert.Contains(t, got, http.MethodGet+" /admin/retention")
+	assert.Contains(t, got, http.MethodGet+" /admin/retention/preview")
+	assert.Contains(t, got, http.MethodPut+" /admin/retention/groups/:id")
+	assert.Contains(t, got, http.MethodDelete+" /admin/retention/groups/:id")
+	assert.Contains(t, got, http.MethodGet+" /admin/legal-holds")
+	assert.Contains(t, got, http.MethodPost+" /admin/legal-holds")
+	assert.Contains(t, got, http.MethodDelete+" /admin/legal-holds/:id")
+	assert.Contains(t, got, http.MethodGet+" /admin/item-policies")
+	assert.Contains(t, got, http.MethodPost+" /admin/item-policies")
+	assert.Contains(t, got, http.MethodGet+" /admin/item-policies/:id")
+	assert.Contains(t, got, http.MethodPut+" /admin/item-policies/:id")
+	assert.Contains(t, got, http.MethodDelete+" /admin/item-policies/:id")
+}
@@ -0,0 +1,6 @@
+// Package adminapproval provides HTTP handlers for the dual control of destructive admin actions in the
+// AegisVaultKeeper server.
+//
+// This package lets admin operators review the destructive admin requests held back until a second operator
+// approves them, and approve or reject them.
+package adminapproval
@@ -0,0 +1,81 @@
+package adminapproval
+
+import (
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/adminapproval"
+	"github.com/google/uuid"
+)
+
+// Approval represents a destructive admin request held back until a second operator approves it.
+type Approval struct {
+	// CreatedAt contains the timestamp the request was initiated at.
+	CreatedAt time.Time `json:"created_at"          example:"2023-12-01T10:00:00Z"`
+	// DecidedAt contains the timestamp of the decision; omitted while pending.
+	DecidedAt time.Time `json:"decided_at,omitzero" example:"2023-12-01T10:05:00Z"`
+	// ExpiresAt contains the deadline of both the approval and the execution of the request.
+	ExpiresAt time.Time `json:"expires_at"          example:"2023-12-02T10:00:00Z"`
+	// Action names the guarded admin action.
+	Action string `json:"action"              example:"legal_hold.release"`
+	// Method contains the HTTP method of the request.
+	Method string `json:"method"              example:"DELETE"`
+	// Path contains the path with the query of the request.
+	Path string `json:"path"                example:"/api/admin/legal-holds/123e4567-e89b-12d3-a456-426614174001"`
+	// Digest contains the hex SHA-256 digest of the request body the execution must repeat.
+	Digest string `json:"body_sha256"         example:"e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"`
+	// Initiator names the operator who initiated the request.
+	Initiator string `json:"initiator"           example:"alice"`
+	// Approver names the operator who approved or rejected the request; omitted while pending.
+	Approver string `json:"approver,omitzero"   example:"bob"`
+	// Status contains the state of the approval: pending, approved, rejected, executed or expired.
+	Status string `json:"status"              example:"approved"`
+	// ID contains the approval identifier to send in X-Admin-Approval once approved.
+	ID uuid.UUID `json:"id"                  example:"123e4567-e89b-12d3-a456-426614174000"`
+}
+
+// NewApprovalFromApp converts an application layer approval to delivery DTO.
+func NewApprovalFromApp(a *adminapproval.Approval) *Approval {
+	if a == nil {
+		return nil
+	}
+	return &Approval{
+		ID:        a.ID,
+		Action:    a.Action,
+		Method:    a.Method,
+		Path:      a.Path,
+		Digest:    a.BodySHA256,
+		Initiator: a.Initiator,
+		Approver:  a.Approver,
+		Status:    a.Status,
+		CreatedAt: a.CreatedAt,
+		DecidedAt: a.DecidedAt,
+		ExpiresAt: a.ExpiresAt,
+	}
+}
+
+// NewApprovalsFromApp converts application layer approvals to delivery DTOs.
+func NewApprovalsFromApp(as []*adminapproval.Approval) []*Approval {
+	result := make([]*Approval, 0, len(as))
+	for _, a := range as {
+		result = append(result, NewApprovalFromApp(a))
+	}
+	return result
+}
+
+// ListApprovalsRequest represents the query filtering the listed approvals.
+type ListApprovalsRequest struct {
+	// Pending restricts the list to the requests still awaiting approval.
+	Pending bool `form:"pending" example:"true"`
+}
+
+// ListApprovalsResponse represents the response containing approvals.
+type ListApprovalsResponse struct {
+	// Approvals contains the approvals, most recent first.
+	Approvals []*Approval `json:"approvals"`
+}
+
+// ApprovalIDRequest represents the approval addressed in the request path.
+type ApprovalIDRequest struct {
+	// ID contains the approval identifier (required UUID format).
+	ID string `uri:"id" binding:"required,uuid" example:"123e4567-e89b-12d3-a456-426614174000"`
+}
@@ -0,0 +1,78 @@
+package adminapproval
+
+import (
+	"net/http"
+
+	app "github.com/gdyunin/aegis-vault-keeper/internal/server/application/adminapproval"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/errutil"
+	"github.com/gin-gonic/gin"
+)
+
+// AdminApprovalErrRegistry defines error handling policies for the dual control of destructive admin actions.
+var AdminApprovalErrRegistry = errutil.Registry{
+	{
+		ErrorIn: app.ErrAdminApprovalTechError,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusInternalServerError,
+			PublicMsg:  http.StatusText(http.StatusInternalServerError),
+			LogIt:      true,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassTech,
+		},
+	},
+	{
+		ErrorIn: app.ErrApprovalNotFound,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusNotFound,
+			PublicMsg:  "Admin approval not found",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassGeneric,
+		},
+	},
+	{
+		ErrorIn: app.ErrSelfApproval,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusForbidden,
+			PublicMsg:  "Admin requests must be approved by another operator",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassAuth,
+		},
+	},
+	{
+		ErrorIn: app.ErrApprovalNotPending,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusConflict,
+			PublicMsg:  "Admin request is already decided",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassGeneric,
+		},
+	},
+	{
+		ErrorIn: app.ErrApprovalExpired,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusConflict,
+			PublicMsg:  "Admin request has expired",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassGeneric,
+		},
+	},
+	{
+		ErrorIn: app.ErrApprovalConflict,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusConflict,
+			PublicMsg:  "Admin request was decided by another operator",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassGeneric,
+		},
+	},
+}
+
+// handleError processes errors using the admin approval error registry.
+func handleError(err error, c *gin.Context) (int, []string) {
+	return errutil.HandleWithRegistry(AdminApprovalErrRegistry, err, c)
+}
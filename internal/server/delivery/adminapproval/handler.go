@@ -0,0 +1,147 @@
+package adminapproval
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/adminapproval"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/response"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/util"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Service defines the dual-control application service interface.
+type Service interface {
+	// List retrieves the destructive admin requests held back for approval.
+	List(context.Context, adminapproval.ListParams) ([]*adminapproval.Approval, error)
+	// Decide approves or rejects a pending destructive admin request.
+	Decide(context.Context, adminapproval.DecideParams) (*adminapproval.Approval, error)
+}
+
+// Handler handles HTTP requests for dual-control endpoints.
+type Handler struct {
+	// s is the dual-control service used to process operations.
+	s Service
+}
+
+// NewHandler creates a new dual-control handler with the provided service.
+func NewHandler(s Service) *Handler {
+	return &Handler{s: s}
+}
+
+// List retrieves the destructive admin requests held back for approval.
+// @Summary      List admin approvals
+// @Description  Retrieves the destructive admin requests held back until a second operator approves them,
+// @Description  most recent first
+// .
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Security     AdminToken
+// @Param        pending query bool false "Only requests awaiting approval"
+// @Success      200 {object} ListApprovalsResponse "Admin approvals retrieved successfully"
+// @Failure      400 {object} response.Error "Bad request - invalid filter"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing admin token"
+// @Failure      404 {object} response.Error "Not found - admin API is disabled"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /admin/approvals [get]
+// .
+func (h *Handler) List(c *gin.Context) {
+	// req holds the deserialized query parameters of the request.
+	var req ListApprovalsRequest
+	if err := util.NewCtxExtractor(c).BindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	approvals, err := h.s.List(c, adminapproval.ListParams{Pending: req.Pending})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, ListApprovalsResponse{Approvals: NewApprovalsFromApp(approvals)})
+}
+
+// Approve approves a pending destructive admin request as the authenticated operator.
+// @Summary      Approve admin request
+// @Description  Approves a destructive admin request initiated by another operator. The initiator may then
+// @Description  execute it once, before it expires, by repeating the identical request with the approval ID in
+// @Description  the X-Admin-Approval header.
+// .
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Security     AdminToken
+// @Param        id path string true "Admin approval ID" format(uuid)
+// @Success      200 {object} Approval "Admin request approved successfully"
+// @Failure      400 {object} response.Error "Bad request - invalid ID format"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing admin token"
+// @Failure      403 {object} response.Error "Forbidden - operator initiated the request"
+// @Failure      404 {object} response.Error "Not found - admin approval not found or admin API is disabled"
+// @Failure      409 {object} response.Error "Conflict - admin request already decided or expired"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /admin/approvals/{id}/approve [post]
+// .
+func (h *Handler) Approve(c *gin.Context) {
+	h.decide(c, true)
+}
+
+// Reject rejects a pending destructive admin request as the authenticated operator.
+// @Summary      Reject admin request
+// @Description  Rejects a pending destructive admin request; the initiator may withdraw their own request
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Security     AdminToken
+// @Param        id path string true "Admin approval ID" format(uuid)
+// @Success      200 {object} Approval "Admin request rejected successfully"
+// @Failure      400 {object} response.Error "Bad request - invalid ID format"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing admin token"
+// @Failure      404 {object} response.Error "Not found - admin approval not found or admin API is disabled"
+// @Failure      409 {object} response.Error "Conflict - admin request already decided or expired"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /admin/approvals/{id}/reject [post]
+// .
+func (h *Handler) Reject(c *gin.Context) {
+	h.decide(c, false)
+}
+
+// decide records the decision of the authenticated operator on the approval addressed in the path.
+func (h *Handler) decide(c *gin.Context, approve bool) {
+	extractor := util.NewCtxExtractor(c)
+
+	operator, err := extractor.AdminOperator()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+
+	// req holds the deserialized URI parameters for the decision.
+	var req ApprovalIDRequest
+	if err := extractor.BindURI(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	approvalID, err := uuid.Parse(req.ID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	approval, err := h.s.Decide(c, adminapproval.DecideParams{ID: approvalID, Operator: operator, Approve: approve})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, NewApprovalFromApp(approval))
+}
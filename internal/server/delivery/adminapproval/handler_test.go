@@ -0,0 +1,249 @@
+package adminapproval
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/adminapproval"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/consts"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockAdminApprovalService implements Service for testing.
+type mockAdminApprovalService struct {
+	listFunc   func(ctx context.Context, params adminapproval.ListParams) ([]*adminapproval.Approval, error)
+	decideFunc func(ctx context.Context, params adminapproval.DecideParams) (*adminapproval.Approval, error)
+}
+
+func (m *mockAdminApprovalService) List(
+	ctx context.Context,
+	params adminapproval.ListParams,
+) ([]*adminapproval.Approval, error) {
+	if m.listFunc != nil {
+		return m.listFunc(ctx, params)
+	}
+	return nil, nil
+}
+
+func (m *mockAdminApprovalService) Decide(
+	ctx context.Context,
+	params adminapproval.DecideParams,
+) (*adminapproval.Approval, error) {
+	if m.decideFunc != nil {
+		return m.decideFunc(ctx, params)
+	}
+	return nil, nil
+}
+
+func TestHandler_List(t *testing.T) {
+	t.Parallel()
+
+	approvalID := uuid.New()
+
+	tests := []struct {
+		mockService    *mockAdminApprovalService
+		name           string
+		query          string
+		wantCount      int
+		expectedStatus int
+	}{
+		{
+			name: "all approvals",
+			mockService: &mockAdminApprovalService{
+				listFunc: func(_ context.Context, params adminapproval.ListParams) ([]*adminapproval.Approval, error) {
+					assert.Equal(t, adminapproval.ListParams{}, params)
+					return []*adminapproval.Approval{{ID: approvalID, Status: "executed"}}, nil
+				},
+			},
+			wantCount:      1,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:  "pending approvals",
+			query: "?pending=true",
+			mockService: &mockAdminApprovalService{
+				listFunc: func(_ context.Context, params adminapproval.ListParams) ([]*adminapproval.Approval, error) {
+					assert.Equal(t, adminapproval.ListParams{Pending: true}, params)
+					return []*adminapproval.Approval{{ID: approvalID, Status: "pending"}}, nil
+				},
+			},
+			wantCount:      1,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid filter",
+			query:          "?pending=maybe",
+			mockService:    &mockAdminApprovalService{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "service error",
+			mockService: &mockAdminApprovalService{
+				listFunc: func(context.Context, adminapproval.ListParams) ([]*adminapproval.Approval, error) {
+					return nil, errors.Join(adminapproval.ErrAdminApprovalTechError, errors.New("db down"))
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/admin/approvals"+tt.query, nil)
+
+			NewHandler(tt.mockService).List(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+			// got holds the decoded response.
+			var got ListApprovalsResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+			require.Len(t, got.Approvals, tt.wantCount)
+			assert.Equal(t, approvalID, got.Approvals[0].ID)
+		})
+	}
+}
+
+func TestHandler_Decide(t *testing.T) {
+	t.Parallel()
+
+	approvalID := uuid.New()
+
+	tests := []struct {
+		mockService    *mockAdminApprovalService
+		name           string
+		operator       string
+		id             string
+		approve        bool
+		expectedStatus int
+	}{
+		{
+			name:     "approve",
+			operator: "bob",
+			id:       approvalID.String(),
+			approve:  true,
+			mockService: &mockAdminApprovalService{
+				decideFunc: func(
+					_ context.Context,
+					params adminapproval.DecideParams,
+				) (*adminapproval.Approval, error) {
+					assert.Equal(t, adminapproval.DecideParams{ID: approvalID, Operator: "bob", Approve: true}, params)
+					return &adminapproval.Approval{ID: approvalID, Status: "approved", Approver: "bob"}, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:     "reject",
+			operator: "alice",
+			id:       approvalID.String(),
+			mockService: &mockAdminApprovalService{
+				decideFunc: func(
+					_ context.Context,
+					params adminapproval.DecideParams,
+				) (*adminapproval.Approval, error) {
+					assert.Equal(t, adminapproval.DecideParams{ID: approvalID, Operator: "alice"}, params)
+					return &adminapproval.Approval{ID: approvalID, Status: "rejected", Approver: "alice"}, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:     "self approval",
+			operator: "alice",
+			id:       approvalID.String(),
+			approve:  true,
+			mockService: &mockAdminApprovalService{
+				decideFunc: func(context.Context, adminapproval.DecideParams) (*adminapproval.Approval, error) {
+					return nil, adminapproval.ErrSelfApproval
+				},
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:     "not found",
+			operator: "bob",
+			id:       approvalID.String(),
+			approve:  true,
+			mockService: &mockAdminApprovalService{
+				decideFunc: func(context.Context, adminapproval.DecideParams) (*adminapproval.Approval, error) {
+					return nil, adminapproval.ErrApprovalNotFound
+				},
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:     "already decided",
+			operator: "bob",
+			id:       approvalID.String(),
+			approve:  true,
+			mockService: &mockAdminApprovalService{
+				decideFunc: func(context.Context, adminapproval.DecideParams) (*adminapproval.Approval, error) {
+					return nil, adminapproval.ErrApprovalNotPending
+				},
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "invalid id",
+			operator:       "bob",
+			id:             "approval",
+			approve:        true,
+			mockService:    &mockAdminApprovalService{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "missing operator",
+			id:             approvalID.String(),
+			approve:        true,
+			mockService:    &mockAdminApprovalService{},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/admin/approvals/"+tt.id, nil)
+			c.Params = gin.Params{{Key: "id", Value: tt.id}}
+			if tt.operator != "" {
+				c.Set(consts.CtxKeyAdminOperator, tt.operator)
+			}
+
+			h := NewHandler(tt.mockService)
+			if tt.approve {
+				h.Approve(c)
+			} else {
+				h.Reject(c)
+			}
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+			// got holds the decoded approval.
+			var got Approval
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+			assert.Equal(t, approvalID, got.ID)
+			assert.Equal(t, tt.operator, got.Approver)
+		})
+	}
+}
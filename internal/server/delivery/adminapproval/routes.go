@@ -0,0 +1,12 @@
+package adminapproval
+
+import "github.com/gin-gonic/gin"
+
+// RegisterRoutes registers dual-control routes with the provided router group.
+// Creates /approvals and the decision endpoints of /approvals/:id with the specified handler.
+func RegisterRoutes(r *gin.RouterGroup, h *Handler) {
+	approvalsGroup := r.Group("/approvals")
+	approvalsGroup.GET("", h.List)
+	approvalsGroup.POST("/:id/approve", h.Approve)
+	approvalsGroup.POST("/:id/reject", h.Reject)
+}
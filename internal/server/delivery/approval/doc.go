@@ -0,0 +1,5 @@
+// Package approval provides HTTP handlers for access approval endpoints in the AegisVaultKeeper server.
+//
+// This package lets authenticated users request access to their items that require approval and
+// follow their requests, and lets designated approvers review pending requests and grant or deny them.
+package approval
@@ -0,0 +1,77 @@
+package approval
+
+import (
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/approval"
+	"github.com/google/uuid"
+)
+
+// Request represents a request to reveal an item that requires approval.
+type Request struct {
+	// CreatedAt contains the timestamp the request was filed at.
+	CreatedAt time.Time `json:"created_at"           example:"2023-12-01T10:00:00Z"`
+	// DecidedAt contains the timestamp of the decision; omitted while pending.
+	DecidedAt time.Time `json:"decided_at,omitzero"  example:"2023-12-01T10:05:00Z"`
+	// ExpiresAt contains the decision deadline while pending and the end of the granted access once approved.
+	ExpiresAt time.Time `json:"expires_at"           example:"2023-12-01T10:20:00Z"`
+	// Reason contains the explanation given by the requester.
+	Reason string `json:"reason,omitzero"      example:"Quarterly tax filing"`
+	// Status contains the state of the request: pending, approved, denied or expired.
+	Status string `json:"status"               example:"approved"`
+	// ID contains the unique request identifier.
+	ID uuid.UUID `json:"id"                   example:"123e4567-e89b-12d3-a456-426614174000"`
+	// ItemID contains the identifier of the requested item.
+	ItemID uuid.UUID `json:"item_id"              example:"123e4567-e89b-12d3-a456-426614174001"`
+	// UserID contains the identifier of the requester.
+	UserID uuid.UUID `json:"user_id"              example:"123e4567-e89b-12d3-a456-426614174002"`
+	// ApproverID contains the identifier of the approver who decided the request; omitted while pending.
+	ApproverID uuid.UUID `json:"approver_id,omitzero" example:"123e4567-e89b-12d3-a456-426614174003"`
+}
+
+// NewRequestFromApp converts an application layer access request to delivery DTO.
+func NewRequestFromApp(r *approval.Request) *Request {
+	if r == nil {
+		return nil
+	}
+	return &Request{
+		ID:         r.ID,
+		ItemID:     r.ItemID,
+		UserID:     r.UserID,
+		ApproverID: r.ApproverID,
+		Reason:     r.Reason,
+		Status:     r.Status,
+		CreatedAt:  r.CreatedAt,
+		DecidedAt:  r.DecidedAt,
+		ExpiresAt:  r.ExpiresAt,
+	}
+}
+
+// NewRequestsFromApp converts application layer access requests to delivery DTOs.
+func NewRequestsFromApp(rs []*approval.Request) []*Request {
+	result := make([]*Request, 0, len(rs))
+	for _, r := range rs {
+		result = append(result, NewRequestFromApp(r))
+	}
+	return result
+}
+
+// ListRequestsResponse represents the response containing access requests.
+type ListRequestsResponse struct {
+	// Requests contains the access requests, most recent first.
+	Requests []*Request `json:"requests"`
+}
+
+// RequestAccessRequest represents the request to reveal an item that requires approval.
+type RequestAccessRequest struct {
+	// Reason contains the explanation shown to approvers, up to 255 characters.
+	Reason string `json:"reason,omitzero"                        example:"Quarterly tax filing"`
+	// ItemID contains the identifier of the requested item (required UUID format).
+	ItemID string `json:"item_id"         binding:"required,uuid" example:"123e4567-e89b-12d3-a456-426614174001"`
+}
+
+// RequestIDRequest represents the access request addressed in the request path.
+type RequestIDRequest struct {
+	// ID contains the access request identifier (required UUID format).
+	ID string `uri:"id" binding:"required,uuid" example:"123e4567-e89b-12d3-a456-426614174000"`
+}
@@ -0,0 +1,98 @@
+package approval
+
+import (
+	"net/http"
+
+	app "github.com/gdyunin/aegis-vault-keeper/internal/server/application/approval"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/errutil"
+	"github.com/gin-gonic/gin"
+)
+
+// ApprovalErrRegistry defines error handling policies for access approval operations.
+var ApprovalErrRegistry = errutil.Registry{
+	{
+		ErrorIn: app.ErrApprovalTechError,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusInternalServerError,
+			PublicMsg:  http.StatusText(http.StatusInternalServerError),
+			LogIt:      true,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassTech,
+		},
+	},
+	{
+		ErrorIn: app.ErrRequestNotFound,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusNotFound,
+			PublicMsg:  "Access request not found",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassGeneric,
+		},
+	},
+	{
+		ErrorIn: app.ErrNotApprover,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusForbidden,
+			PublicMsg:  "Only designated approvers can review access requests",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassAuth,
+		},
+	},
+	{
+		ErrorIn: app.ErrSelfApproval,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusForbidden,
+			PublicMsg:  "Access requests must be decided by another approver",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassAuth,
+		},
+	},
+	{
+		ErrorIn: app.ErrRequestNotPending,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusConflict,
+			PublicMsg:  "Access request is already decided or has expired",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassGeneric,
+		},
+	},
+	{
+		ErrorIn: app.ErrApprovalNotRequired,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusBadRequest,
+			PublicMsg:  "Item does not require approval",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+	{
+		ErrorIn: app.ErrRequestIncorrectReason,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusBadRequest,
+			PublicMsg:  "Reason must not exceed 255 characters",
+			LogIt:      false,
+			AllowMerge: true,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+	{
+		ErrorIn: app.ErrApprovalAppError,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusBadRequest,
+			PublicMsg:  "Invalid access request parameters",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+}
+
+// handleError processes access approval errors using the registry and returns appropriate HTTP response.
+func handleError(err error, c *gin.Context) (int, []string) {
+	return errutil.HandleWithRegistry(ApprovalErrRegistry, err, c)
+}
@@ -0,0 +1,233 @@
+package approval
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/approval"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/response"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/util"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Service defines the access approval application service interface.
+type Service interface {
+	// RequestAccess files a request to reveal an item that requires approval.
+	RequestAccess(context.Context, approval.RequestParams) (*approval.Request, error)
+	// ListRequests retrieves the access requests of the user.
+	ListRequests(context.Context, approval.ListParams) ([]*approval.Request, error)
+	// ListPending retrieves the requests awaiting the decision of the approver.
+	ListPending(context.Context, approval.ListParams) ([]*approval.Request, error)
+	// Decide grants or denies a pending access request.
+	Decide(context.Context, approval.DecideParams) (*approval.Request, error)
+}
+
+// Handler handles HTTP requests for access approval endpoints.
+type Handler struct {
+	// s is the access approval service used to process operations.
+	s Service
+}
+
+// NewHandler creates a new access approval handler with the provided service.
+func NewHandler(s Service) *Handler {
+	return &Handler{s: s}
+}
+
+// List retrieves the access requests of the authenticated user.
+// @Summary      List access requests
+// @Description  Retrieves the requests the user filed to reveal items that require approval, most recent first
+// @Tags         Account
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} ListRequestsResponse "Access requests retrieved successfully"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /account/approvals [get]
+// .
+func (h *Handler) List(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	userID, err := extractor.UserID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+
+	requests, err := h.s.ListRequests(c, approval.ListParams{UserID: userID})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, ListRequestsResponse{Requests: NewRequestsFromApp(requests)})
+}
+
+// Request asks the approvers for access to an item of the authenticated user that requires approval.
+// @Summary      Request item access
+// @Description  Files a request to reveal an item tagged approval and notifies the approvers. While a request
+// @Description  for the item is pending, that request is returned instead of filing another one.
+// .
+// @Tags         Account
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body RequestAccessRequest true "Requested item"
+// @Success      201 {object} Request "Access request filed successfully"
+// @Failure      400 {object} response.Error "Bad request - invalid item, reason, or the item does not require approval"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /account/approvals [post]
+// .
+func (h *Handler) Request(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	userID, err := extractor.UserID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+
+	// req holds the deserialized JSON request payload for the access request.
+	var req RequestAccessRequest
+	if err := extractor.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	itemID, err := uuid.Parse(req.ItemID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	request, err := h.s.RequestAccess(c, approval.RequestParams{
+		Reason: req.Reason,
+		ItemID: itemID,
+		UserID: userID,
+	})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, NewRequestFromApp(request))
+}
+
+// Pending retrieves the access requests awaiting the decision of the authenticated approver.
+// @Summary      List pending access requests
+// @Description  Retrieves the requests of other users awaiting a decision; only designated approvers may list them
+// @Tags         Account
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} ListRequestsResponse "Pending access requests retrieved successfully"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
+// @Failure      403 {object} response.Error "Forbidden - user is not an approver"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /account/approvals/pending [get]
+// .
+func (h *Handler) Pending(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	userID, err := extractor.UserID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+
+	requests, err := h.s.ListPending(c, approval.ListParams{UserID: userID})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, ListRequestsResponse{Requests: NewRequestsFromApp(requests)})
+}
+
+// Approve grants a pending access request as the authenticated approver.
+// @Summary      Approve access request
+// @Description  Grants a pending request of another user; the item can then be revealed for a limited time
+// @Tags         Account
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Access request ID" format(uuid)
+// @Success      200 {object} Request "Access request approved successfully"
+// @Failure      400 {object} response.Error "Bad request - invalid ID format"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
+// @Failure      403 {object} response.Error "Forbidden - user is not an approver or filed the request"
+// @Failure      404 {object} response.Error "Not found - access request not found"
+// @Failure      409 {object} response.Error "Conflict - access request already decided or expired"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /account/approvals/{id}/approve [post]
+// .
+func (h *Handler) Approve(c *gin.Context) {
+	h.decide(c, true)
+}
+
+// Deny rejects a pending access request as the authenticated approver.
+// @Summary      Deny access request
+// @Description  Rejects a pending request of another user
+// @Tags         Account
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Access request ID" format(uuid)
+// @Success      200 {object} Request "Access request denied successfully"
+// @Failure      400 {object} response.Error "Bad request - invalid ID format"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
+// @Failure      403 {object} response.Error "Forbidden - user is not an approver or filed the request"
+// @Failure      404 {object} response.Error "Not found - access request not found"
+// @Failure      409 {object} response.Error "Conflict - access request already decided or expired"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /account/approvals/{id}/deny [post]
+// .
+func (h *Handler) Deny(c *gin.Context) {
+	h.decide(c, false)
+}
+
+// decide records the decision of the authenticated approver on the access request addressed in the path.
+func (h *Handler) decide(c *gin.Context, approve bool) {
+	extractor := util.NewCtxExtractor(c)
+
+	userID, err := extractor.UserID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+
+	// req holds the deserialized URI parameters for the decision.
+	var req RequestIDRequest
+	if err := extractor.BindURI(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	requestID, err := uuid.Parse(req.ID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	request, err := h.s.Decide(c, approval.DecideParams{ID: requestID, ApproverID: userID, Approve: approve})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, NewRequestFromApp(request))
+}
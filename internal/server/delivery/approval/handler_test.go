@@ -0,0 +1,316 @@
+package approval
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/approval"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/consts"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockApprovalService implements Service for testing.
+type mockApprovalService struct {
+	requestFunc func(ctx context.Context, params approval.RequestParams) (*approval.Request, error)
+	listFunc    func(ctx context.Context, params approval.ListParams) ([]*approval.Request, error)
+	pendingFunc func(ctx context.Context, params approval.ListParams) ([]*approval.Request, error)
+	decideFunc  func(ctx context.Context, params approval.DecideParams) (*approval.Request, error)
+}
+
+func (m *mockApprovalService) RequestAccess(
+	ctx context.Context,
+	params approval.RequestParams,
+) (*approval.Request, error) {
+	if m.requestFunc != nil {
+		return m.requestFunc(ctx, params)
+	}
+	return nil, nil
+}
+
+func (m *mockApprovalService) ListRequests(
+	ctx context.Context,
+	params approval.ListParams,
+) ([]*approval.Request, error) {
+	if m.listFunc != nil {
+		return m.listFunc(ctx, params)
+	}
+	return nil, nil
+}
+
+func (m *mockApprovalService) ListPending(
+	ctx context.Context,
+	params approval.ListParams,
+) ([]*approval.Request, error) {
+	if m.pendingFunc != nil {
+		return m.pendingFunc(ctx, params)
+	}
+	return nil, nil
+}
+
+func (m *mockApprovalService) Decide(ctx context.Context, params approval.DecideParams) (*approval.Request, error) {
+	if m.decideFunc != nil {
+		return m.decideFunc(ctx, params)
+	}
+	return nil, nil
+}
+
+func TestHandler_List(t *testing.T) {
+	t.Parallel()
+
+	userID, requestID := uuid.New(), uuid.New()
+
+	tests := []struct {
+		mockService    *mockApprovalService
+		name           string
+		pending        bool
+		setUser        bool
+		wantCount      int
+		expectedStatus int
+	}{
+		{
+			name:    "own requests",
+			setUser: true,
+			mockService: &mockApprovalService{
+				listFunc: func(_ context.Context, params approval.ListParams) ([]*approval.Request, error) {
+					assert.Equal(t, approval.ListParams{UserID: userID}, params)
+					return []*approval.Request{{ID: requestID, Status: "approved"}}, nil
+				},
+			},
+			wantCount:      1,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:    "pending requests",
+			pending: true,
+			setUser: true,
+			mockService: &mockApprovalService{
+				pendingFunc: func(_ context.Context, params approval.ListParams) ([]*approval.Request, error) {
+					assert.Equal(t, approval.ListParams{UserID: userID}, params)
+					return []*approval.Request{{ID: requestID, Status: "pending"}}, nil
+				},
+			},
+			wantCount:      1,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:    "not an approver",
+			pending: true,
+			setUser: true,
+			mockService: &mockApprovalService{
+				pendingFunc: func(context.Context, approval.ListParams) ([]*approval.Request, error) {
+					return nil, approval.ErrNotApprover
+				},
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "missing user context",
+			mockService:    &mockApprovalService{},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:    "service error",
+			setUser: true,
+			mockService: &mockApprovalService{
+				listFunc: func(context.Context, approval.ListParams) ([]*approval.Request, error) {
+					return nil, approval.ErrApprovalTechError
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/account/approvals", nil)
+			if tt.setUser {
+				c.Set(consts.CtxKeyUserID, userID)
+			}
+
+			h := NewHandler(tt.mockService)
+			if tt.pending {
+				h.Pending(c)
+			} else {
+				h.List(c)
+			}
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.wantCount == 0 {
+				return
+			}
+			var got ListRequestsResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+			require.Len(t, got.Requests, tt.wantCount)
+			assert.Equal(t, requestID, got.Requests[0].ID)
+		})
+	}
+}
+
+func TestHandler_Request(t *testing.T) {
+	t.Parallel()
+
+	userID, itemID := uuid.New(), uuid.New()
+
+	tests := []struct {
+		mockService    *mockApprovalService
+		name           string
+		body           string
+		expectedStatus int
+	}{
+		{
+			name: "success",
+			body: `{"item_id":"` + itemID.String() + `","reason":"tax filing"}`,
+			mockService: &mockApprovalService{
+				requestFunc: func(_ context.Context, params approval.RequestParams) (*approval.Request, error) {
+					assert.Equal(t, approval.RequestParams{Reason: "tax filing", ItemID: itemID, UserID: userID}, params)
+					return &approval.Request{ID: uuid.New(), ItemID: itemID, Status: "pending"}, nil
+				},
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "invalid item ID",
+			body:           `{"item_id":"tax-card"}`,
+			mockService:    &mockApprovalService{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "approval not required",
+			body: `{"item_id":"` + itemID.String() + `"}`,
+			mockService: &mockApprovalService{
+				requestFunc: func(context.Context, approval.RequestParams) (*approval.Request, error) {
+					return nil, approval.ErrApprovalNotRequired
+				},
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/account/approvals", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Set(consts.CtxKeyUserID, userID)
+
+			NewHandler(tt.mockService).Request(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestHandler_Decide(t *testing.T) {
+	t.Parallel()
+
+	approverID, requestID := uuid.New(), uuid.New()
+
+	tests := []struct {
+		mockService    *mockApprovalService
+		name           string
+		id             string
+		approve        bool
+		expectedStatus int
+	}{
+		{
+			name:    "approve",
+			id:      requestID.String(),
+			approve: true,
+			mockService: &mockApprovalService{
+				decideFunc: func(_ context.Context, params approval.DecideParams) (*approval.Request, error) {
+					assert.Equal(t, approval.DecideParams{ID: requestID, ApproverID: approverID, Approve: true}, params)
+					return &approval.Request{ID: requestID, Status: "approved"}, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "deny",
+			id:   requestID.String(),
+			mockService: &mockApprovalService{
+				decideFunc: func(_ context.Context, params approval.DecideParams) (*approval.Request, error) {
+					assert.False(t, params.Approve)
+					return &approval.Request{ID: requestID, Status: "denied"}, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid ID",
+			id:             "latest",
+			mockService:    &mockApprovalService{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:    "already decided",
+			id:      requestID.String(),
+			approve: true,
+			mockService: &mockApprovalService{
+				decideFunc: func(context.Context, approval.DecideParams) (*approval.Request, error) {
+					return nil, errors.Join(approval.ErrRequestNotPending, errors.New("request is denied"))
+				},
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:    "own request",
+			id:      requestID.String(),
+			approve: true,
+			mockService: &mockApprovalService{
+				decideFunc: func(context.Context, approval.DecideParams) (*approval.Request, error) {
+					return nil, approval.ErrSelfApproval
+				},
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name: "missing request",
+			id:   requestID.String(),
+			mockService: &mockApprovalService{
+				decideFunc: func(context.Context, approval.DecideParams) (*approval.Request, error) {
+					return nil, approval.ErrRequestNotFound
+				},
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/account/approvals/"+tt.id+"/approve", nil)
+			c.Params = gin.Params{{Key: "id", Value: tt.id}}
+			c.Set(consts.CtxKeyUserID, approverID)
+
+			h := NewHandler(tt.mockService)
+			if tt.approve {
+				h.Approve(c)
+			} else {
+				h.Deny(c)
+			}
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
@@ -0,0 +1,14 @@
+package approval
+
+import "github.com/gin-gonic/gin"
+
+// RegisterRoutes registers access approval routes with the provided router group.
+// Creates /approvals, /approvals/pending and the decision endpoints of /approvals/:id with the specified handler.
+func RegisterRoutes(r *gin.RouterGroup, h *Handler) {
+	approvalsGroup := r.Group("/approvals")
+	approvalsGroup.GET("", h.List)
+	approvalsGroup.POST("", h.Request)
+	approvalsGroup.GET("/pending", h.Pending)
+	approvalsGroup.POST("/:id/approve", h.Approve)
+	approvalsGroup.POST("/:id/deny", h.Deny)
+}
@@ -0,0 +1,28 @@
+package approval
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterRoutes(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	RegisterRoutes(router.Group("/account"), NewHandler(&mockApprovalService{}))
+
+	got := make(map[string]string)
+	for _, r := range router.Routes() {
+		got[r.Method+" "+r.Path] = r.Handler
+	}
+	assert.Len(t, got, 5)
+	assert.Contains(t, got, http.MethodGet+" /account/approvals")
+	assert.Contains(t, got, http.MethodPost+" /account/approvals")
+	assert.Contains(t, got, http.MethodGet+" /account/approvals/pending")
+	assert.Contains(t, got, http.MethodPost+" /account/approvals/:id/approve")
+	assert.Contains(t, got, http.MethodPost+" /account/approvals/:id/deny")
+}
@@ -0,0 +1,6 @@
+// Package auditexport provides HTTP handlers for compliance exports of the audit trail in the AegisVaultKeeper
+// server.
+//
+// This package streams the audit events of a period as a ZIP archive holding the JSON Lines or CSV file with a
+// digest file and its Ed25519 signature, for SOC 2 and ISO 27001 audits.
+package auditexport
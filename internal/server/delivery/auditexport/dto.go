@@ -0,0 +1,13 @@
+package auditexport
+
+import "time"
+
+// ExportRequest represents the request exporting the audit trail.
+type ExportRequest struct {
+	// From is the inclusive start of the exported period in RFC 3339 format (required).
+	From time.Time `form:"from" binding:"required" example:"2026-01-01T00:00:00Z"`
+	// To is the exclusive end of the exported period in RFC 3339 format (required).
+	To time.Time `form:"to"   binding:"required" example:"2026-04-01T00:00:00Z"`
+	// Format specifies the file format: jsonl (default) or csv.
+	Format string `form:"format" example:"jsonl"`
+}
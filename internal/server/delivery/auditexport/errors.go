@@ -0,0 +1,58 @@
+package auditexport
+
+import (
+	"net/http"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/auditexport"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/errutil"
+	"github.com/gin-gonic/gin"
+)
+
+// AuditExportErrRegistry defines error handling policies for audit trail exports.
+var AuditExportErrRegistry = errutil.Registry{
+	{
+		ErrorIn: auditexport.ErrAuditExportTechError,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusInternalServerError,
+			PublicMsg:  http.StatusText(http.StatusInternalServerError),
+			LogIt:      true,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassTech,
+		},
+	},
+	{
+		ErrorIn: auditexport.ErrFormatUnsupported,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusBadRequest,
+			PublicMsg:  "Unsupported format; supported formats: jsonl, csv",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+	{
+		ErrorIn: auditexport.ErrIncorrectPeriod,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusBadRequest,
+			PublicMsg:  "The period must end after it starts",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+	{
+		ErrorIn: auditexport.ErrSigningKeyNotConfigured,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusConflict,
+			PublicMsg:  "Audit export digests are not signed: set RESPONSE_SIGNING_KEY",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+}
+
+// handleError processes errors using the audit export error registry.
+func handleError(err error, c *gin.Context) (int, []string) {
+	return errutil.HandleWithRegistry(AuditExportErrRegistry, err, c)
+}
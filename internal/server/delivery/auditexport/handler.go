@@ -0,0 +1,162 @@
+package auditexport
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/auditexport"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/response"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/util"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// digestSuffix is appended to the name of the exported file to name its digest file.
+	digestSuffix = ".digest.json"
+	// signatureSuffix is appended to the name of the digest file to name its signature file.
+	signatureSuffix = ".sig"
+	// archiveTimeLayout formats the period bounds in archive names.
+	archiveTimeLayout = "20060102T150405Z"
+	// mimeZIP is the media type of export archives.
+	mimeZIP = "application/zip"
+)
+
+// Service defines the audit export application service interface.
+type Service interface {
+	// Export writes the audit entries of the period to w and returns the signed digest of the written file.
+	Export(ctx context.Context, params auditexport.ExportParams, w io.Writer) (*auditexport.Digest, error)
+}
+
+// Handler handles HTTP requests for audit trail export endpoints.
+type Handler struct {
+	// s is the audit export service used to export the audit trail.
+	s Service
+}
+
+// NewHandler creates a new audit export handler with the provided service.
+func NewHandler(s Service) *Handler {
+	return &Handler{s: s}
+}
+
+// Export streams the audit trail of a period as a ZIP archive.
+// @Summary      Export audit trail
+// @Description  Streams the audit events that occurred in the period as a ZIP archive, for compliance audits.
+// @Description  The archive holds audit-events.jsonl or audit-events.csv with the events in the order they were
+// @Description  stored, the digest file audit-events.<format>.digest.json listing the period, the number of
+// @Description  events and the SHA-256 digest of the file, and the raw Ed25519 signature of the digest file in
+// @Description  audit-events.<format>.digest.json.sig, made with the response signing key. Events are loaded and
+// @Description  written page by page, so large periods are streamed. Should a failure interrupt the stream, the
+// @Description  connection is closed before the end of the archive. Requires the audit_export license feature
+// .
+// @Tags         Admin
+// @Produce      application/zip
+// @Produce      json
+// @Security     AdminToken
+// @Param        from query string true "Inclusive start of the period in RFC 3339 format"
+// @Param        to query string true "Exclusive end of the period in RFC 3339 format"
+// @Param        format query string false "File format" Enums(jsonl, csv) default(jsonl)
+// @Success      200 {file} binary "ZIP archive with the export, its digest and signature"
+// @Failure      400 {object} response.Error "Bad request - invalid period or format"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing admin token"
+// @Failure      403 {object} response.Error "Forbidden - license does not include audit export"
+// @Failure      404 {object} response.Error "Not found - admin API is disabled"
+// @Failure      409 {object} response.Error "Conflict - response signing key not configured"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /admin/audit/export [get]
+// .
+func (h *Handler) Export(c *gin.Context) {
+	// req holds the deserialized query parameters of the request.
+	var req ExportRequest
+	if err := util.NewCtxExtractor(c).BindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+	format := auditexport.Format(req.Format)
+	if format == "" {
+		format = auditexport.FormatJSONL
+	}
+
+	w := &archiveWriter{
+		c: c,
+		name: fmt.Sprintf("audit-%s-%s.zip",
+			req.From.UTC().Format(archiveTimeLayout), req.To.UTC().Format(archiveTimeLayout)),
+	}
+	archive := zip.NewWriter(w)
+	err := h.writeArchive(c, archive, auditexport.ExportParams{From: req.From, To: req.To, Format: format})
+	switch {
+	case err != nil && !w.started:
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+	case err != nil:
+		// The status is sent, so the client can only tell a truncated archive by the aborted connection.
+		_, _ = handleError(err, c)
+		panic(http.ErrAbortHandler)
+	}
+}
+
+// writeArchive writes the export of the period followed by its digest and signature files to the archive and
+// closes it.
+func (h *Handler) writeArchive(ctx context.Context, archive *zip.Writer, params auditexport.ExportParams) error {
+	modified := time.Now()
+	name := params.Format.FileName()
+	file, err := archive.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: modified})
+	if err != nil {
+		return fmt.Errorf("failed to add %s to archive: %w", name, err)
+	}
+	digest, err := h.s.Export(ctx, params, file)
+	if err != nil {
+		return err
+	}
+
+	for _, f := range []struct {
+		name    string
+		content []byte
+	}{
+		{name: name + digestSuffix, content: digest.Document},
+		{name: name + digestSuffix + signatureSuffix, content: digest.Signature},
+	} {
+		w, err := archive.CreateHeader(&zip.FileHeader{Name: f.name, Method: zip.Deflate, Modified: modified})
+		if err != nil {
+			return fmt.Errorf("failed to add %s to archive: %w", f.name, err)
+		}
+		if _, err := w.Write(f.content); err != nil {
+			return fmt.Errorf("failed to write %s: %w", f.name, err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+	return nil
+}
+
+// archiveWriter writes the archive to the response, sending the status and headers with the first bytes, so
+// failures detected before anything is written are still answered with an error response.
+type archiveWriter struct {
+	// c is the request context the archive is written to.
+	c *gin.Context
+	// name is the file name of the archive offered to the client.
+	name string
+	// started reports whether the response status and headers were written.
+	started bool
+}
+
+// Write sends the status and headers on the first call and writes p to the response.
+func (w *archiveWriter) Write(p []byte) (int, error) {
+	if !w.started {
+		w.c.Header("Content-Disposition", "attachment; filename=\""+w.name+"\"")
+		w.c.Header("Content-Type", mimeZIP)
+		w.c.Status(http.StatusOK)
+		w.started = true
+	}
+	n, err := w.c.Writer.Write(p)
+	if err != nil {
+		return n, fmt.Errorf("failed to write archive: %w", err)
+	}
+	return n, nil
+}
@@ -0,0 +1,208 @@
+package auditexport
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/auditexport"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+// mockAuditExportService implements Service for testing.
+type mockAuditExportService struct {
+	exportFunc func(ctx context.Context, params auditexport.ExportParams, w io.Writer) (*auditexport.Digest, error)
+}
+
+func (m *mockAuditExportService) Export(
+	ctx context.Context,
+	params auditexport.ExportParams,
+	w io.Writer,
+) (*auditexport.Digest, error) {
+	if m.exportFunc != nil {
+		return m.exportFunc(ctx, params, w)
+	}
+	return &auditexport.Digest{}, nil
+}
+
+func TestHandler_Export(t *testing.T) {
+	t.Parallel()
+
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		exportErr      error
+		wantFiles      map[string]string
+		name           string
+		query          string
+		wantFormat     auditexport.Format
+		expectedStatus int
+	}{
+		{
+			name:       "json lines by default",
+			query:      "?from=2026-01-01T00:00:00Z&to=2026-04-01T00:00:00Z",
+			wantFormat: auditexport.FormatJSONL,
+			wantFiles: map[string]string{
+				"audit-events.jsonl":                 "{\"seq\":1}\n",
+				"audit-events.jsonl.digest.json":     "{\"events\":1}\n",
+				"audit-events.jsonl.digest.json.sig": "signature",
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:       "csv",
+			query:      "?from=2026-01-01T00:00:00Z&to=2026-04-01T00:00:00Z&format=csv",
+			wantFormat: auditexport.FormatCSV,
+			wantFiles: map[string]string{
+				"audit-events.csv":                 "{\"seq\":1}\n",
+				"audit-events.csv.digest.json":     "{\"events\":1}\n",
+				"audit-events.csv.digest.json.sig": "signature",
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "missing period",
+			query:          "?from=2026-01-01T00:00:00Z",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid time",
+			query:          "?from=yesterday&to=2026-04-01T00:00:00Z",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "unsupported format",
+			query:          "?from=2026-01-01T00:00:00Z&to=2026-04-01T00:00:00Z&format=xml",
+			wantFormat:     "xml",
+			exportErr:      auditexport.ErrFormatUnsupported,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "signing key not configured",
+			query:          "?from=2026-01-01T00:00:00Z&to=2026-04-01T00:00:00Z",
+			wantFormat:     auditexport.FormatJSONL,
+			exportErr:      auditexport.ErrSigningKeyNotConfigured,
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "service error",
+			query:          "?from=2026-01-01T00:00:00Z&to=2026-04-01T00:00:00Z",
+			wantFormat:     auditexport.FormatJSONL,
+			exportErr:      errors.Join(auditexport.ErrAuditExportTechError, errors.New("connection lost")),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			service := &mockAuditExportService{
+				exportFunc: func(
+					_ context.Context,
+					params auditexport.ExportParams,
+					w io.Writer,
+				) (*auditexport.Digest, error) {
+					assert.Equal(t, auditexport.ExportParams{From: from, To: to, Format: tt.wantFormat}, params)
+					if tt.exportErr != nil {
+						return nil, tt.exportErr
+					}
+					_, err := io.WriteString(w, "{\"seq\":1}\n")
+					require.NoError(t, err)
+					return &auditexport.Digest{Document: []byte("{\"events\":1}\n"), Signature: []byte("signature")}, nil
+				},
+			}
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/admin/audit/export"+tt.query, nil)
+
+			NewHandler(service).Export(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.wantFiles == nil {
+				return
+			}
+			assert.Equal(t, mimeZIP, w.Header().Get("Content-Type"))
+			assert.Equal(t, "attachment; filename=\"audit-20260101T000000Z-20260401T000000Z.zip\"",
+				w.Header().Get("Content-Disposition"))
+			archive, err := zip.NewReader(bytes.NewReader(w.Body.Bytes()), int64(w.Body.Len()))
+			require.NoError(t, err)
+			got := make(map[string]string)
+			for _, f := range archive.File {
+				r, err := f.Open()
+				require.NoError(t, err)
+				content, err := io.ReadAll(r)
+				require.NoError(t, err)
+				got[f.Name] = string(content)
+			}
+			assert.Equal(t, tt.wantFiles, got)
+		})
+	}
+}
+
+// interruptedService returns a service that fails after exporting enough to flush the archive to the client.
+func interruptedService() *mockAuditExportService {
+	return &mockAuditExportService{
+		exportFunc: func(_ context.Context, _ auditexport.ExportParams, w io.Writer) (*auditexport.Digest, error) {
+			// noise does not compress, so the archive is flushed to the client before the failure.
+			noise := make([]byte, 256<<10)
+			_, _ = rand.Read(noise)
+			if _, err := w.Write(noise); err != nil {
+				return nil, err
+			}
+			return nil, errors.Join(auditexport.ErrAuditExportTechError, errors.New("database unavailable"))
+		},
+	}
+}
+
+func TestHandler_ExportInterrupted(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet,
+		"/admin/audit/export?from=2026-01-01T00:00:00Z&to=2026-04-01T00:00:00Z", nil)
+
+	handler := NewHandler(interruptedService())
+
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() { handler.Export(c) })
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Positive(t, w.Body.Len())
+}
+
+func TestHandler_ExportInterrupted_Connection(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.Recovery(zaptest.NewLogger(t).Sugar()))
+	RegisterRoutes(router.Group("admin/audit"), NewHandler(interruptedService()))
+
+	srv := httptest.NewServer(router)
+	defer srv.Close()
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet,
+		srv.URL+"/admin/audit/export?from=2026-01-01T00:00:00Z&to=2026-04-01T00:00:00Z", nil)
+	require.NoError(t, err)
+	resp, err := srv.Client().Do(req)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	_, err = io.ReadAll(resp.Body)
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF, "the client should see the archive is truncated")
+}
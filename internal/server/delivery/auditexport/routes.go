@@ -0,0 +1,9 @@
+package auditexport
+
+import "github.com/gin-gonic/gin"
+
+// RegisterRoutes registers audit trail export routes with the provided router group.
+// Creates /export, serving the archive of the requested period.
+func RegisterRoutes(r *gin.RouterGroup, h *Handler) {
+	r.GET("/export", h.Export)
+}
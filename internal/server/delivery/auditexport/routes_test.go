@@ -0,0 +1,24 @@
+package auditexport
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterRoutes(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	RegisterRoutes(router.Group("/admin/audit"), NewHandler(&mockAuditExportService{}))
+
+	got := make(map[string]string)
+	for _, r := range router.Routes() {
+		got[r.Method+" "+r.Path] = r.Handler
+	}
+	assert.Len(t, got, 1)
+	assert.Contains(t, got, http.MethodGet+" /admin/audit/export")
+}
@@ -3,15 +3,18 @@ package auth
 import (
 	"time"
 
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/auth"
 	"github.com/google/uuid"
 )
 
 // RegisterRequest represents the data required for user registration.
 type RegisterRequest struct {
 	// Login contains the user's email address or username (required, unique across system).
-	Login string `json:"login"    binding:"required" example:"user@example.com"`
+	Login string `json:"login"                 binding:"required" example:"user@example.com"`
 	// Password contains the user's plaintext password (required, min 8 chars, will be hashed).
-	Password string `json:"password" binding:"required" example:"securePassword123"`
+	Password string `json:"password"              binding:"required" example:"securePassword123"`
+	// InviteCode contains the invite code (required when registration is invitation-only).
+	InviteCode string `json:"invite_code,omitempty" example:"avki_ABCDEFGHIJKLMNOPQRSTUVWXYZ"`
 }
 
 // LoginRequest represents the data required for user authentication.
@@ -24,8 +27,10 @@ type LoginRequest struct {
 
 // RegisterResponse represents the response after successful user registration.
 type RegisterResponse struct {
+	// RecoveryCodes lists the single-use recovery codes of the new user; they are only shown this once.
+	RecoveryCodes []string `json:"recovery_codes" example:"abcd-efgh-ijkl-mnop"`
 	// ID contains the newly created user's unique identifier.
-	ID uuid.UUID `json:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	ID uuid.UUID `json:"id"             example:"123e4567-e89b-12d3-a456-426614174000"`
 }
 
 // AccessToken represents the authentication token and its metadata.
@@ -37,3 +42,198 @@ type AccessToken struct {
 	// TokenType specifies the token type, always "Bearer" for OAuth 2.0 compliance.
 	TokenType string `json:"token_type"   example:"Bearer"`
 }
+
+// StepUpResponse represents a login held until it is confirmed with a verification code.
+type StepUpResponse struct {
+	// ExpiresAt specifies when the verification code sent to the user expires.
+	ExpiresAt time.Time `json:"expires_at"   example:"2023-12-31T23:59:59Z"`
+	// Reasons lists why the login was flagged ("new_device", "new_location").
+	Reasons []string `json:"reasons"      example:"new_device"`
+	// ChallengeID identifies the challenge to submit with the verification code.
+	ChallengeID uuid.UUID `json:"challenge_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+}
+
+// VerifyLoginRequest represents the data required to complete a held login.
+type VerifyLoginRequest struct {
+	// ChallengeID identifies the step-up challenge returned by the login (required UUID format).
+	ChallengeID string `json:"challenge_id"            binding:"required" example:"123e4567-e89b-12d3-a456-426614174000"`
+	// Code contains the verification code sent to the user by e-mail (required without a recovery code).
+	Code string `json:"code,omitempty"          example:"123456"`
+	// RecoveryCode contains a recovery code of the user submitted instead of the verification code.
+	RecoveryCode string `json:"recovery_code,omitempty" example:"abcd-efgh-ijkl-mnop"`
+}
+
+// ApproveLoginRequest represents the parameters of an e-mailed approval link.
+type ApproveLoginRequest struct {
+	// ChallengeID identifies the held login (required UUID format).
+	ChallengeID string `form:"challenge_id" binding:"required" example:"123e4567-e89b-12d3-a456-426614174000"`
+	// Token contains the approval token from the link (required).
+	Token string `form:"token"        binding:"required" example:"V2hhdCBhIGxvdmVseSBkYXkgZm9yIGEgc2lnbi1pbg"`
+}
+
+// ApproveLoginResponse represents the result of an approval by link.
+type ApproveLoginResponse struct {
+	// Message contains a human readable confirmation.
+	Message string `json:"message" example:"The sign-in has been approved"`
+}
+
+// ClaimLoginRequest represents the data required to complete an approved held login.
+type ClaimLoginRequest struct {
+	// ChallengeID identifies the step-up challenge returned by the login (required UUID format).
+	ChallengeID string `json:"challenge_id" binding:"required" example:"123e4567-e89b-12d3-a456-426614174000"`
+}
+
+// PendingLogin represents a held login awaiting approval.
+type PendingLogin struct {
+	// LastSeenAt contains the time of the held login.
+	LastSeenAt time.Time `json:"last_seen_at" example:"2023-12-01T10:00:00Z"`
+	// ExpiresAt specifies when the login can no longer be approved.
+	ExpiresAt time.Time `json:"expires_at"   example:"2023-12-01T10:10:00Z"`
+	// IP contains the client address of the held login; omitted when unknown.
+	IP string `json:"ip,omitzero"  example:"203.0.113.9"`
+	// UserAgent contains the reported client software.
+	UserAgent string `json:"user_agent"   example:"Mozilla/5.0"`
+	// Location contains the country code, or the network when the country is unknown.
+	Location string `json:"location"     example:"DE"`
+	// ID identifies the held login to approve.
+	ID uuid.UUID `json:"id"           example:"123e4567-e89b-12d3-a456-426614174000"`
+	// Approved determines whether the login was approved and awaits the claim by the held client.
+	Approved bool `json:"approved"     example:"false"`
+}
+
+// NewPendingLoginsFromApp converts application layer held logins to delivery DTOs.
+func NewPendingLoginsFromApp(logins []*auth.PendingLogin) []*PendingLogin {
+	result := make([]*PendingLogin, 0, len(logins))
+	for _, l := range logins {
+		var ip string
+		if l.IP.IsValid() {
+			ip = l.IP.String()
+		}
+		result = append(result, &PendingLogin{
+			ID:         l.ID,
+			IP:         ip,
+			UserAgent:  l.UserAgent,
+			Location:   l.Location,
+			LastSeenAt: l.LastSeenAt,
+			ExpiresAt:  l.ExpiresAt,
+			Approved:   l.Approved,
+		})
+	}
+	return result
+}
+
+// PendingLoginsResponse represents the held logins of the user awaiting approval.
+type PendingLoginsResponse struct {
+	// Logins contains the held logins.
+	Logins []*PendingLogin `json:"logins"`
+}
+
+// ApprovePendingLoginRequest represents the request to approve a held login.
+type ApprovePendingLoginRequest struct {
+	// ID identifies the held login (required UUID format).
+	ID string `uri:"id" binding:"required" example:"123e4567-e89b-12d3-a456-426614174000"`
+}
+
+// ChangePasswordRequest represents the request to change the password of the authenticated user.
+type ChangePasswordRequest struct {
+	// OldPassword contains the current password (required field).
+	OldPassword string `json:"old_password" binding:"required" example:"securePassword123"`
+	// NewPassword specifies the password to change to (required field).
+	NewPassword string `json:"new_password" binding:"required" example:"evenMoreSecurePassword456"`
+}
+
+// RecoveryCodesResponse describes the recovery codes of the authenticated user.
+type RecoveryCodesResponse struct {
+	// RecoveryCodes lists newly generated recovery codes; only returned right after they were generated.
+	RecoveryCodes []string `json:"recovery_codes,omitempty" example:"abcd-efgh-ijkl-mnop"`
+	// Remaining is the number of unused recovery codes.
+	Remaining int `json:"remaining"                example:"10"`
+}
+
+// RegenerateRecoveryCodesRequest represents the request to replace the recovery codes of the authenticated user.
+type RegenerateRecoveryCodesRequest struct {
+	// Password contains the current password re-entered to confirm the request (required field).
+	Password string `json:"password" binding:"required" example:"securePassword123"`
+}
+
+// RecoverAccountRequest represents the request to reset a lost password with a recovery code.
+type RecoverAccountRequest struct {
+	// Login contains the login of the account (required field).
+	Login string `json:"login"         binding:"required" example:"user@example.com"`
+	// RecoveryCode contains an unused recovery code of the user (required field).
+	RecoveryCode string `json:"recovery_code" binding:"required" example:"abcd-efgh-ijkl-mnop"`
+	// NewPassword specifies the password to reset to (required field).
+	NewPassword string `json:"new_password"  binding:"required" example:"evenMoreSecurePassword456"`
+}
+
+// UnlockSettingsResponse describes how the client derives the unlock key from the unlock secret.
+type UnlockSettingsResponse struct {
+	// KDF names the key derivation function.
+	KDF string `json:"kdf"            example:"argon2id"`
+	// Salt contains the base64 encoded salt of the derivation; omitted while no unlock secret is set.
+	Salt []byte `json:"salt,omitempty" swaggertype:"string" example:"MDEyMzQ1Njc4OWFiY2RlZg=="`
+	// KeySize is the size in bytes of the derived unlock key.
+	KeySize int `json:"key_size"       example:"32"`
+	// Time is the number of passes of the derivation over its memory.
+	Time uint32 `json:"time"           example:"3"`
+	// MemoryKiB is the memory used by the derivation in KiB.
+	MemoryKiB uint32 `json:"memory_kib"     example:"65536"`
+	// Threads is the number of lanes of the derivation.
+	Threads uint8 `json:"threads"        example:"4"`
+	// Enabled reports whether the vault is sealed with an unlock secret.
+	Enabled bool `json:"enabled"        example:"true"`
+}
+
+// SetUnlockSecretRequest represents the request to set or change the unlock secret of the authenticated user.
+// Keys are derived by the client and sent base64 encoded; the unlock secret itself is never sent.
+type SetUnlockSecretRequest struct {
+	// Salt contains the salt the new unlock key was derived with (required field).
+	Salt []byte `json:"salt"                         binding:"required" swaggertype:"string"`
+	// UnlockKey contains the unlock key derived from the new unlock secret (required field).
+	UnlockKey []byte `json:"unlock_key"                   binding:"required" swaggertype:"string"`
+	// CurrentUnlockKey contains the unlock key of the current unlock secret; required to change it.
+	CurrentUnlockKey []byte `json:"current_unlock_key,omitempty" swaggertype:"string"`
+	// Password contains the current password; required to set the first unlock secret.
+	Password string `json:"password,omitempty"           example:"securePassword123"`
+}
+
+// DuressSettingsResponse describes the duress password of the authenticated user.
+type DuressSettingsResponse struct {
+	// Enabled reports whether logins with a duress password open the decoy vault.
+	Enabled bool `json:"enabled" example:"true"`
+	// Alert reports whether logins with the duress password raise a silent security alert.
+	Alert bool `json:"alert"   example:"true"`
+}
+
+// SetDuressPasswordRequest represents the request to set, change or clear the duress password of the
+// authenticated user.
+type SetDuressPasswordRequest struct {
+	// Password contains the current password re-entered to confirm the change (required field).
+	Password string `json:"password"                  binding:"required" example:"securePassword123"`
+	// DuressPassword contains the new duress password; empty or omitted turns duress logins off.
+	DuressPassword string `json:"duress_password,omitempty" example:"decoyPassword456"`
+	// Alert selects whether logins with the duress password raise a silent security alert.
+	Alert bool `json:"alert"                     example:"true"`
+}
+
+// RequestLoginChangeRequest represents the request to change the login of the authenticated user.
+type RequestLoginChangeRequest struct {
+	// NewLogin specifies the login to change to (required field).
+	NewLogin string `json:"new_login" binding:"required" example:"user@example.org"`
+	// Password contains the current password re-entered to confirm the change (required field).
+	Password string `json:"password"  binding:"required" example:"securePassword123"`
+}
+
+// RequestLoginChangeResponse represents a requested login change awaiting confirmation.
+type RequestLoginChangeResponse struct {
+	// ExpiresAt specifies when the sent confirmation tokens expire.
+	ExpiresAt time.Time `json:"expires_at" example:"2023-12-02T10:00:00Z"`
+}
+
+// ConfirmLoginChangeRequest represents the confirmation tokens of a login change.
+type ConfirmLoginChangeRequest struct {
+	// OldToken contains the token sent to the old login (required field).
+	OldToken string `json:"old_token" binding:"required" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	// NewToken contains the token sent to the new login (required field).
+	NewToken string `json:"new_token" binding:"required" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+}
@@ -41,6 +41,146 @@ var AuthErrRegistry = errutil.Registry{
 			ErrorClass: errutil.ErrorClassAuth,
 		},
 	},
+	{
+		ErrorIn: app.ErrAuthStepUpFailed,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusUnauthorized,
+			PublicMsg:  "The verification code is invalid or has expired. Please log in again",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassAuth,
+		},
+	},
+	{
+		ErrorIn: app.ErrAuthRecoveryCodeInvalid,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusUnauthorized,
+			PublicMsg:  "The login or recovery code is invalid, or the code was already used",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassAuth,
+		},
+	},
+	{
+		ErrorIn: app.ErrAuthApprovalPending,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusConflict,
+			PublicMsg:  "The login has not been approved yet",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassAuth,
+		},
+	},
+	{
+		ErrorIn: app.ErrAuthUserDisabled,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusForbidden,
+			PublicMsg:  "Account is disabled",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassAuth,
+		},
+	},
+	{
+		ErrorIn: app.ErrAuthInviteRequired,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusForbidden,
+			PublicMsg:  "Registration requires an invite code",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassAuth,
+		},
+	},
+	{
+		ErrorIn: app.ErrAuthInviteInvalid,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusForbidden,
+			PublicMsg:  "The invite code is invalid, already used or has expired",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassAuth,
+		},
+	},
+	{
+		ErrorIn: app.ErrAuthPasswordMismatch,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusForbidden,
+			PublicMsg:  "The provided password is incorrect",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassAuth,
+		},
+	},
+	{
+		ErrorIn: app.ErrAuthLoginChangeInvalid,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusForbidden,
+			PublicMsg:  "The confirmation tokens are invalid, already used or have expired",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassAuth,
+		},
+	},
+	{
+		ErrorIn: app.ErrAuthLoginChangeUndeliverable,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusConflict,
+			PublicMsg:  "The login change confirmations cannot be delivered to the current or the new login",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassGeneric,
+		},
+	},
+	{
+		ErrorIn: app.ErrAuthVaultLocked,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusForbidden,
+			PublicMsg:  "The current unlock key is required to change the unlock secret",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassAuth,
+		},
+	},
+	{
+		ErrorIn: app.ErrAuthUnlockInvalid,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusForbidden,
+			PublicMsg:  "The current unlock key does not match your unlock secret",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassAuth,
+		},
+	},
+	{
+		ErrorIn: app.ErrAuthIncorrectUnlockSecret,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusBadRequest,
+			PublicMsg:  "The unlock key must be 32 bytes derived with a salt of at least 16 bytes",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+	{
+		ErrorIn: app.ErrAuthDuressPasswordReused,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusBadRequest,
+			PublicMsg:  "The duress password must differ from the password",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+	{
+		ErrorIn: app.ErrAuthDuressUnavailable,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusConflict,
+			PublicMsg:  "A duress password cannot be set for this account",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassGeneric,
+		},
+	},
 	{
 		ErrorIn: app.ErrAuthIncorrectLogin,
 		HandlePolicy: errutil.Policy{
@@ -61,6 +201,86 @@ var AuthErrRegistry = errutil.Registry{
 			ErrorClass: errutil.ErrorClassValidation,
 		},
 	},
+	{
+		ErrorIn: app.ErrAuthPasswordTooShort,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusBadRequest,
+			PublicMsg:  "The password is shorter than the password policy allows",
+			LogIt:      false,
+			AllowMerge: true,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+	{
+		ErrorIn: app.ErrAuthPasswordTooLong,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusBadRequest,
+			PublicMsg:  "The password must not be longer than 64 characters",
+			LogIt:      false,
+			AllowMerge: true,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+	{
+		ErrorIn: app.ErrAuthPasswordNoLower,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusBadRequest,
+			PublicMsg:  "The password must contain a lower-case letter",
+			LogIt:      false,
+			AllowMerge: true,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+	{
+		ErrorIn: app.ErrAuthPasswordNoUpper,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusBadRequest,
+			PublicMsg:  "The password must contain an upper-case letter",
+			LogIt:      false,
+			AllowMerge: true,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+	{
+		ErrorIn: app.ErrAuthPasswordNoDigit,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusBadRequest,
+			PublicMsg:  "The password must contain a digit",
+			LogIt:      false,
+			AllowMerge: true,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+	{
+		ErrorIn: app.ErrAuthPasswordNoSymbol,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusBadRequest,
+			PublicMsg:  "The password must contain a symbol",
+			LogIt:      false,
+			AllowMerge: true,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+	{
+		ErrorIn: app.ErrAuthPasswordCommon,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusBadRequest,
+			PublicMsg:  "The password is too common. Please choose another one",
+			LogIt:      false,
+			AllowMerge: true,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+	{
+		ErrorIn: app.ErrAuthPasswordReused,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusBadRequest,
+			PublicMsg:  "The password was used recently. Please choose another one",
+			LogIt:      false,
+			AllowMerge: true,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
 	{
 		ErrorIn: app.ErrAuthUserAlreadyExists,
 		HandlePolicy: errutil.Policy{
@@ -55,6 +55,66 @@ func TestAuthErrRegistry(t *testing.T) {
 			},
 			found: true,
 		},
+		{
+			name:    "invite required",
+			errorIn: auth.ErrAuthInviteRequired,
+			expectedPolicy: errutil.Policy{
+				StatusCode: 403,
+				PublicMsg:  "Registration requires an invite code",
+				LogIt:      false,
+				AllowMerge: false,
+				ErrorClass: errutil.ErrorClassAuth,
+			},
+			found: true,
+		},
+		{
+			name:    "invalid invite",
+			errorIn: auth.ErrAuthInviteInvalid,
+			expectedPolicy: errutil.Policy{
+				StatusCode: 403,
+				PublicMsg:  "The invite code is invalid, already used or has expired",
+				LogIt:      false,
+				AllowMerge: false,
+				ErrorClass: errutil.ErrorClassAuth,
+			},
+			found: true,
+		},
+		{
+			name:    "step-up verification failed",
+			errorIn: auth.ErrAuthStepUpFailed,
+			expectedPolicy: errutil.Policy{
+				StatusCode: 401,
+				PublicMsg:  "The verification code is invalid or has expired. Please log in again",
+				LogIt:      false,
+				AllowMerge: false,
+				ErrorClass: errutil.ErrorClassAuth,
+			},
+			found: true,
+		},
+		{
+			name:    "user disabled",
+			errorIn: auth.ErrAuthUserDisabled,
+			expectedPolicy: errutil.Policy{
+				StatusCode: 403,
+				PublicMsg:  "Account is disabled",
+				LogIt:      false,
+				AllowMerge: false,
+				ErrorClass: errutil.ErrorClassAuth,
+			},
+			found: true,
+		},
+		{
+			name:    "approval pending",
+			errorIn: auth.ErrAuthApprovalPending,
+			expectedPolicy: errutil.Policy{
+				StatusCode: 409,
+				PublicMsg:  "The login has not been approved yet",
+				LogIt:      false,
+				AllowMerge: false,
+				ErrorClass: errutil.ErrorClassAuth,
+			},
+			found: true,
+		},
 		{
 			name:    "incorrect login",
 			errorIn: auth.ErrAuthIncorrectLogin,
@@ -147,8 +207,30 @@ func TestAuthErrRegistry_AllErrorsCovered(t *testing.T) {
 		auth.ErrAuthTechError,
 		auth.ErrAuthWrongLoginOrPassword,
 		auth.ErrAuthInvalidAccessToken,
+		auth.ErrAuthStepUpFailed,
+		auth.ErrAuthRecoveryCodeInvalid,
+		auth.ErrAuthApprovalPending,
+		auth.ErrAuthUserDisabled,
+		auth.ErrAuthInviteRequired,
+		auth.ErrAuthInviteInvalid,
+		auth.ErrAuthPasswordMismatch,
+		auth.ErrAuthLoginChangeInvalid,
+		auth.ErrAuthLoginChangeUndeliverable,
+		auth.ErrAuthVaultLocked,
+		auth.ErrAuthUnlockInvalid,
+		auth.ErrAuthIncorrectUnlockSecret,
+		auth.ErrAuthDuressPasswordReused,
+		auth.ErrAuthDuressUnavailable,
 		auth.ErrAuthIncorrectLogin,
 		auth.ErrAuthIncorrectPassword,
+		auth.ErrAuthPasswordTooShort,
+		auth.ErrAuthPasswordTooLong,
+		auth.ErrAuthPasswordNoLower,
+		auth.ErrAuthPasswordNoUpper,
+		auth.ErrAuthPasswordNoDigit,
+		auth.ErrAuthPasswordNoSymbol,
+		auth.ErrAuthPasswordCommon,
+		auth.ErrAuthPasswordReused,
 		auth.ErrAuthUserAlreadyExists,
 		auth.ErrAuthAppError,
 	}
@@ -187,7 +269,17 @@ func TestAuthErrRegistry_StatusCodes(t *testing.T) {
 		{auth.ErrAuthInvalidAccessToken, 401},
 		{auth.ErrAuthIncorrectLogin, 400},
 		{auth.ErrAuthIncorrectPassword, 400},
+		{auth.ErrAuthPasswordTooShort, 400},
+		{auth.ErrAuthPasswordCommon, 400},
+		{auth.ErrAuthPasswordReused, 400},
 		{auth.ErrAuthUserAlreadyExists, 409},
+		{auth.ErrAuthPasswordMismatch, 403},
+		{auth.ErrAuthLoginChangeInvalid, 403},
+		{auth.ErrAuthLoginChangeUndeliverable, 409},
+		{auth.ErrAuthUnlockInvalid, 403},
+		{auth.ErrAuthIncorrectUnlockSecret, 400},
+		{auth.ErrAuthDuressPasswordReused, 400},
+		{auth.ErrAuthDuressUnavailable, 409},
 		{auth.ErrAuthAppError, 400},
 	}
 
@@ -219,6 +311,9 @@ func TestAuthErrRegistry_ErrorClasses(t *testing.T) {
 		{auth.ErrAuthIncorrectLogin, errutil.ErrorClassValidation},
 		{auth.ErrAuthIncorrectPassword, errutil.ErrorClassValidation},
 		{auth.ErrAuthUserAlreadyExists, errutil.ErrorClassValidation},
+		{auth.ErrAuthPasswordMismatch, errutil.ErrorClassAuth},
+		{auth.ErrAuthLoginChangeInvalid, errutil.ErrorClassAuth},
+		{auth.ErrAuthLoginChangeUndeliverable, errutil.ErrorClassGeneric},
 		{auth.ErrAuthAppError, errutil.ErrorClassValidation},
 	}
 
@@ -329,3 +424,21 @@ func TestHandleError_WithWrappedErrors(t *testing.T) {
 	assert.Equal(t, 500, statusCode)
 	assert.Equal(t, []string{"Internal Server Error"}, messages)
 }
+
+func TestAuthErrRegistry_PasswordPolicyViolations(t *testing.T) {
+	t.Parallel()
+
+	err := errors.Join(auth.ErrAuthAppError, auth.ErrAuthIncorrectPassword,
+		auth.ErrAuthPasswordTooShort, auth.ErrAuthPasswordNoDigit, auth.ErrAuthPasswordCommon)
+
+	status, msgs, logIt := AuthErrRegistry.Handle(err)
+
+	assert.Equal(t, 400, status)
+	assert.False(t, logIt)
+	assert.Equal(t, []string{
+		"The password provided is not valid",
+		"The password is shorter than the password policy allows",
+		"The password must contain a digit",
+		"The password is too common. Please choose another one",
+	}, msgs)
+}
@@ -2,9 +2,12 @@ package auth
 
 import (
 	"context"
+	"errors"
 	"net/http"
+	"time"
 
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/auth"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/clientinfo"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/response"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/util"
 	"github.com/gin-gonic/gin"
@@ -14,9 +17,39 @@ import (
 // Service defines the authentication application service interface.
 type Service interface {
 	// Register creates a new user account with the provided parameters.
-	Register(context.Context, auth.RegisterParams) (uuid.UUID, error)
+	Register(context.Context, auth.RegisterParams) (auth.Registration, error)
 	// Login authenticates a user and returns an access token.
 	Login(context.Context, auth.LoginParams) (auth.AccessToken, error)
+	// VerifyLogin completes a login held for step-up verification and returns an access token.
+	VerifyLogin(context.Context, auth.VerifyLoginParams) (auth.AccessToken, error)
+	// ApproveLogin approves a held login with the token of an e-mailed approval link.
+	ApproveLogin(context.Context, auth.ApproveLoginParams) error
+	// ClaimLogin completes an approved held login and returns an access token.
+	ClaimLogin(context.Context, auth.ClaimLoginParams) (auth.AccessToken, error)
+	// PendingLogins lists the held logins of the user awaiting approval.
+	PendingLogins(context.Context, uuid.UUID) ([]*auth.PendingLogin, error)
+	// ApprovePendingLogin approves a held login of the authenticated user.
+	ApprovePendingLogin(context.Context, auth.ApprovePendingLoginParams) error
+	// ChangePassword replaces the password of the authenticated user and returns a new access token.
+	ChangePassword(context.Context, auth.ChangePasswordParams) (auth.AccessToken, error)
+	// RecoveryCodesLeft returns the number of unused recovery codes of the user.
+	RecoveryCodesLeft(context.Context, uuid.UUID) (int, error)
+	// RegenerateRecoveryCodes replaces the recovery codes of the authenticated user and returns the new codes.
+	RegenerateRecoveryCodes(context.Context, auth.RegenerateRecoveryCodesParams) ([]string, error)
+	// RecoverAccount resets a lost password with a recovery code and returns an access token.
+	RecoverAccount(context.Context, auth.RecoverAccountParams) (auth.AccessToken, error)
+	// UnlockSettings returns how the client derives the unlock key of the user from the unlock secret.
+	UnlockSettings(context.Context, uuid.UUID) (auth.UnlockSettings, error)
+	// SetUnlockSecret seals the vault of the user with the unlock key derived from a new unlock secret.
+	SetUnlockSecret(context.Context, auth.SetUnlockSecretParams) error
+	// DuressSettings returns whether the user has a duress password and whether it raises alerts.
+	DuressSettings(context.Context, uuid.UUID) (auth.DuressSettings, error)
+	// SetDuressPassword sets, changes or clears the duress password of the authenticated user.
+	SetDuressPassword(context.Context, auth.SetDuressPasswordParams) error
+	// RequestLoginChange sends the confirmations of a login change of the authenticated user.
+	RequestLoginChange(context.Context, auth.RequestLoginChangeParams) (time.Time, error)
+	// ConfirmLoginChange applies a login change confirmed with the tokens sent to both logins.
+	ConfirmLoginChange(context.Context, auth.ConfirmLoginChangeParams) error
 }
 
 // Handler handles HTTP requests for authentication endpoints.
@@ -32,14 +65,18 @@ func NewHandler(s Service) *Handler {
 
 // Register handles user registration.
 // @Summary      Register a new user
-// @Description  Creates a new user account with login and password
+// @Description  Creates a new user account with login and password. Invitation-only deployments require an
+// @Description  invite code. The response carries the single-use recovery codes of the user, shown only once.
 // @Tags         Auth
 // @Accept       json
 // @Produce      json
 // @Param        request body RegisterRequest true "User registration data"
+// @Param        X-Challenge-Response header string false "Solution of the challenge from /auth/challenge"
 // @Success      201 {object} RegisterResponse "User created successfully"
 // @Failure      400 {object} response.Error "Bad request - invalid input data"
+// @Failure      403 {object} response.Error "Forbidden - challenge solution rejected or invite code invalid"
 // @Failure      409 {object} response.Error "Conflict - user already exists"
+// @Failure      428 {object} response.Error "Precondition required - solve a challenge"
 // @Failure      500 {object} response.Error "Internal server error"
 // @Router       /auth/register [post]
 // .
@@ -55,11 +92,12 @@ func (h *Handler) Register(c *gin.Context) {
 	}
 
 	serviceParams := auth.RegisterParams{
-		Login:    req.Login,
-		Password: req.Password,
+		Login:      req.Login,
+		Password:   req.Password,
+		InviteCode: req.InviteCode,
 	}
 
-	createdUserID, err := h.s.Register(c, serviceParams)
+	registration, err := h.s.Register(c, serviceParams)
 	if err != nil {
 		code, msgs := handleError(err, c)
 		c.JSON(code, response.Error{
@@ -69,7 +107,8 @@ func (h *Handler) Register(c *gin.Context) {
 	}
 
 	resp := RegisterResponse{
-		ID: createdUserID,
+		ID:            registration.UserID,
+		RecoveryCodes: registration.RecoveryCodes,
 	}
 
 	c.JSON(http.StatusCreated, resp)
@@ -77,14 +116,21 @@ func (h *Handler) Register(c *gin.Context) {
 
 // Login handles user authentication.
 // @Summary      Authenticate user
-// @Description  Authenticates user with login and password, returns access token
+// @Description  Authenticates user with login and password, returns access token.
+// @Description  Logins from unrecognized devices or locations are held until confirmed via /auth/login/verify
+// @Description  with the code e-mailed to the user.
+// .
 // @Tags         Auth
 // @Accept       json
 // @Produce      json
 // @Param        request body LoginRequest true "User login credentials"
+// @Param        X-Challenge-Response header string false "Solution of the challenge from /auth/challenge"
 // @Success      200 {object} AccessToken "Authentication successful"
+// @Success      202 {object} StepUpResponse "Verification code sent - confirm the login"
 // @Failure      400 {object} response.Error "Bad request - invalid input data"
 // @Failure      401 {object} response.Error "Unauthorized - invalid credentials"
+// @Failure      403 {object} response.Error "Forbidden - account disabled or challenge solution rejected"
+// @Failure      428 {object} response.Error "Precondition required - solve a challenge"
 // @Failure      500 {object} response.Error "Internal server error"
 // @Router       /auth/login [post]
 // .
@@ -99,12 +145,449 @@ func (h *Handler) Login(c *gin.Context) {
 		return
 	}
 
+	ip, _ := clientinfo.IP(c.Request.Context())
 	serviceParams := auth.LoginParams{
-		Login:    req.Login,
-		Password: req.Password,
+		Login:     req.Login,
+		Password:  req.Password,
+		IP:        ip,
+		UserAgent: c.Request.UserAgent(),
 	}
 
 	accessToken, err := h.s.Login(c, serviceParams)
+	if err != nil {
+		// stepUp holds the challenge of a login held for verification.
+		var stepUp *auth.StepUpRequiredError
+		if errors.As(err, &stepUp) {
+			c.JSON(http.StatusAccepted, StepUpResponse{
+				ChallengeID: stepUp.Challenge.ID,
+				ExpiresAt:   stepUp.Challenge.ExpiresAt,
+				Reasons:     stepUp.Challenge.Reasons,
+			})
+			return
+		}
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, newAccessToken(accessToken))
+}
+
+// VerifyLogin completes a login held for step-up verification.
+// @Summary      Confirm held login
+// @Description  Completes a login from an unrecognized device or location with the e-mailed verification code,
+// @Description  or with one of the recovery codes of the user instead, which is used up.
+// @Description  The device is trusted afterwards; a code accepts a limited number of wrong attempts.
+// .
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        request body VerifyLoginRequest true "Step-up challenge and verification code"
+// @Success      200 {object} AccessToken "Authentication successful"
+// @Failure      400 {object} response.Error "Bad request - invalid input data"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or expired verification or recovery code"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /auth/login/verify [post]
+// .
+func (h *Handler) VerifyLogin(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	// req holds the deserialized JSON verification request.
+	var req VerifyLoginRequest
+	if err := extractor.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	challengeID, err := uuid.Parse(req.ChallengeID)
+	if err != nil || (req.Code == "") == (req.RecoveryCode == "") {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	accessToken, err := h.s.VerifyLogin(c, auth.VerifyLoginParams{
+		ChallengeID:  challengeID,
+		Code:         req.Code,
+		RecoveryCode: req.RecoveryCode,
+	})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, newAccessToken(accessToken))
+}
+
+// ApproveLogin approves a held login from the e-mailed approval link.
+// @Summary      Approve held login by link
+// @Description  Approves a login from an unrecognized device with the token of the e-mailed approval link.
+// @Description  The held client then completes the login with /auth/login/claim.
+// .
+// @Tags         Auth
+// @Produce      json
+// @Param        challenge_id query string true "Held login ID" format(uuid)
+// @Param        token query string true "Approval token from the link"
+// @Success      200 {object} ApproveLoginResponse "Login approved"
+// @Failure      400 {object} response.Error "Bad request - invalid input data"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or expired approval link"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /auth/login/approve [get]
+// .
+func (h *Handler) ApproveLogin(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	// req holds the deserialized query parameters of the approval link.
+	var req ApproveLoginRequest
+	if err := extractor.BindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	challengeID, err := uuid.Parse(req.ChallengeID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	if err := h.s.ApproveLogin(c, auth.ApproveLoginParams{ChallengeID: challengeID, Token: req.Token}); err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, ApproveLoginResponse{Message: "The sign-in has been approved"})
+}
+
+// ClaimLogin completes an approved held login.
+// @Summary      Claim approved login
+// @Description  Completes a held login once it was approved by the e-mailed link or from a signed-in device.
+// @Description  Held clients poll this endpoint; it answers 409 while the login awaits approval.
+// .
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        request body ClaimLoginRequest true "Held login challenge"
+// @Success      200 {object} AccessToken "Authentication successful"
+// @Failure      400 {object} response.Error "Bad request - invalid input data"
+// @Failure      401 {object} response.Error "Unauthorized - unknown or expired held login"
+// @Failure      409 {object} response.Error "Conflict - login not approved yet"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /auth/login/claim [post]
+// .
+func (h *Handler) ClaimLogin(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	// req holds the deserialized JSON claim request.
+	var req ClaimLoginRequest
+	if err := extractor.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	challengeID, err := uuid.Parse(req.ChallengeID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	accessToken, err := h.s.ClaimLogin(c, auth.ClaimLoginParams{ChallengeID: challengeID})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, newAccessToken(accessToken))
+}
+
+// PendingLogins lists the held logins of the authenticated user.
+// @Summary      List held logins
+// @Description  Lists logins from unrecognized devices awaiting approval, so a signed-in device can approve them
+// @Tags         Account
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} PendingLoginsResponse "Held logins retrieved successfully"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /account/logins/pending [get]
+// .
+func (h *Handler) PendingLogins(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	userID, err := extractor.UserID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+
+	logins, err := h.s.PendingLogins(c, userID)
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, PendingLoginsResponse{Logins: NewPendingLoginsFromApp(logins)})
+}
+
+// ApprovePendingLogin approves a held login of the authenticated user.
+// @Summary      Approve held login
+// @Description  Approves a login from an unrecognized device from an already signed-in device.
+// @Description  The held client then completes the login with /auth/login/claim.
+// .
+// @Tags         Account
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Held login ID" format(uuid)
+// @Success      204 "Login approved"
+// @Failure      400 {object} response.Error "Bad request - invalid ID format"
+// @Failure      401 {object} response.Error "Unauthorized - invalid token, or unknown or expired held login"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /account/logins/{id}/approve [post]
+// .
+func (h *Handler) ApprovePendingLogin(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	userID, err := extractor.UserID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+
+	// req holds the deserialized URI parameters of the approval.
+	var req ApprovePendingLoginRequest
+	if err := extractor.BindURI(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	challengeID, err := uuid.Parse(req.ID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	params := auth.ApprovePendingLoginParams{ChallengeID: challengeID, UserID: userID}
+	if err := h.s.ApprovePendingLogin(c, params); err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.Data(http.StatusNoContent, "", nil)
+}
+
+// ChangePassword changes the password of the authenticated user.
+// @Summary      Change password
+// @Description  Verifies the current password and replaces it. The vault stays accessible; every other session
+// @Description  is signed out and a new access token is returned.
+// .
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body ChangePasswordRequest true "Current and new password"
+// @Success      200 {object} AccessToken "Password changed"
+// @Failure      400 {object} response.Error "Bad request - invalid input data"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
+// @Failure      403 {object} response.Error "Forbidden - wrong current password"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /auth/change-password [post]
+// .
+func (h *Handler) ChangePassword(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	userID, err := extractor.UserID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+
+	// req holds the deserialized JSON password change request.
+	var req ChangePasswordRequest
+	if err := extractor.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	accessToken, err := h.s.ChangePassword(c, auth.ChangePasswordParams{
+		UserID:      userID,
+		OldPassword: req.OldPassword,
+		NewPassword: req.NewPassword,
+	})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, newAccessToken(accessToken))
+}
+
+// RecoveryCodes returns the number of unused recovery codes of the authenticated user.
+// @Summary      Count recovery codes
+// @Description  Returns how many of the single-use recovery codes of the user are left. The codes themselves are
+// @Description  only shown when they are generated.
+// .
+// @Tags         Auth
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} RecoveryCodesResponse "Remaining recovery codes"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /auth/recovery-codes [get]
+// .
+func (h *Handler) RecoveryCodes(c *gin.Context) {
+	userID, err := util.NewCtxExtractor(c).UserID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+
+	remaining, err := h.s.RecoveryCodesLeft(c, userID)
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, RecoveryCodesResponse{Remaining: remaining})
+}
+
+// RegenerateRecoveryCodes replaces the recovery codes of the authenticated user.
+// @Summary      Regenerate recovery codes
+// @Description  Verifies the password and replaces the recovery codes of the user with a new set. The previous
+// @Description  codes stop working; the new ones are returned only this once.
+// .
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body RegenerateRecoveryCodesRequest true "Current password"
+// @Success      201 {object} RecoveryCodesResponse "New recovery codes"
+// @Failure      400 {object} response.Error "Bad request - invalid input data"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
+// @Failure      403 {object} response.Error "Forbidden - wrong password"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /auth/recovery-codes [post]
+// .
+func (h *Handler) RegenerateRecoveryCodes(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	userID, err := extractor.UserID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+
+	// req holds the deserialized JSON recovery code regeneration request.
+	var req RegenerateRecoveryCodesRequest
+	if err := extractor.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	codes, err := h.s.RegenerateRecoveryCodes(c, auth.RegenerateRecoveryCodesParams{
+		UserID:   userID,
+		Password: req.Password,
+	})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, RecoveryCodesResponse{RecoveryCodes: codes, Remaining: len(codes)})
+}
+
+// RecoverAccount resets a lost password with a recovery code.
+// @Summary      Recover account
+// @Description  Resets the password of the account with one of its recovery codes, which is used up. Every
+// @Description  session is signed out and a new access token is returned. A vault sealed with an unlock secret
+// @Description  still requires the unlock secret.
+// .
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        request body RecoverAccountRequest true "Login, recovery code and new password"
+// @Param        X-Challenge-Response header string false "Solution of the challenge from /auth/challenge"
+// @Success      200 {object} AccessToken "Password reset"
+// @Failure      400 {object} response.Error "Bad request - invalid input data"
+// @Failure      401 {object} response.Error "Unauthorized - unknown login or invalid recovery code"
+// @Failure      403 {object} response.Error "Forbidden - account disabled or challenge solution rejected"
+// @Failure      428 {object} response.Error "Precondition required - solve a challenge"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /auth/recover [post]
+// .
+func (h *Handler) RecoverAccount(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	// req holds the deserialized JSON account recovery request.
+	var req RecoverAccountRequest
+	if err := extractor.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	accessToken, err := h.s.RecoverAccount(c, auth.RecoverAccountParams{
+		Login:        req.Login,
+		RecoveryCode: req.RecoveryCode,
+		NewPassword:  req.NewPassword,
+	})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, newAccessToken(accessToken))
+}
+
+// UnlockSettings returns the key derivation settings of the unlock secret.
+// @Summary      Get unlock secret settings
+// @Description  Returns whether the vault is sealed with an unlock secret, the salt and the Argon2id parameters
+// @Description  the client derives the unlock key with. Vault requests carry the derived key, base64 encoded,
+// @Description  in the X-Vault-Unlock header.
+// .
+// @Tags         Auth
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} UnlockSettingsResponse "Unlock secret settings"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /auth/unlock-secret [get]
+// .
+func (h *Handler) UnlockSettings(c *gin.Context) {
+	userID, err := util.NewCtxExtractor(c).UserID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+
+	settings, err := h.s.UnlockSettings(c, userID)
 	if err != nil {
 		code, msgs := handleError(err, c)
 		c.JSON(code, response.Error{
@@ -113,11 +596,247 @@ func (h *Handler) Login(c *gin.Context) {
 		return
 	}
 
-	resp := AccessToken{
-		AccessToken: accessToken.AccessToken,
-		ExpiresAt:   accessToken.ExpiresAt,
-		TokenType:   accessToken.TokenType,
+	c.JSON(http.StatusOK, UnlockSettingsResponse{
+		KDF:       settings.KDF,
+		Salt:      settings.Salt,
+		KeySize:   settings.KeySize,
+		Time:      settings.Time,
+		MemoryKiB: settings.MemoryKiB,
+		Threads:   settings.Threads,
+		Enabled:   settings.Enabled,
+	})
+}
+
+// SetUnlockSecret sets or changes the unlock secret of the authenticated user.
+// @Summary      Set unlock secret
+// @Description  Seals the vault with an unlock key the client derives from an unlock secret kept apart from the
+// @Description  password. The first unlock secret is confirmed with the password, a change with the current
+// @Description  unlock key. The unlock secret itself is never sent.
+// .
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body SetUnlockSecretRequest true "Derived unlock keys, salt and password"
+// @Success      204 "Unlock secret set"
+// @Failure      400 {object} response.Error "Bad request - invalid input data"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
+// @Failure      403 {object} response.Error "Forbidden - wrong password or current unlock key"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /auth/unlock-secret [put]
+// .
+func (h *Handler) SetUnlockSecret(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	userID, err := extractor.UserID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
 	}
 
-	c.JSON(http.StatusOK, resp)
+	// req holds the deserialized JSON unlock secret request.
+	var req SetUnlockSecretRequest
+	if err := extractor.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	err = h.s.SetUnlockSecret(c, auth.SetUnlockSecretParams{
+		UserID:           userID,
+		Salt:             req.Salt,
+		UnlockKey:        req.UnlockKey,
+		CurrentUnlockKey: req.CurrentUnlockKey,
+		Password:         req.Password,
+	})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.Data(http.StatusNoContent, "", nil)
+}
+
+// DuressSettings returns the duress password settings of the authenticated user.
+// @Summary      Get duress password settings
+// @Description  Reports whether a duress password opening the decoy vault is set and whether logins with it
+// @Description  raise a silent security alert.
+// .
+// @Tags         Auth
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} DuressSettingsResponse "Duress password settings"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /auth/duress [get]
+// .
+func (h *Handler) DuressSettings(c *gin.Context) {
+	userID, err := util.NewCtxExtractor(c).UserID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+
+	settings, err := h.s.DuressSettings(c, userID)
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, DuressSettingsResponse{Enabled: settings.Enabled, Alert: settings.Alert})
+}
+
+// SetDuressPassword sets, changes or clears the duress password of the authenticated user.
+// @Summary      Set duress password
+// @Description  Sets a duress password confirmed with the password. Logging in with it opens a decoy vault
+// @Description  with separate key material and items instead of the real one, optionally raising a silent
+// @Description  security alert. An empty duress password turns duress logins off; the decoy vault is kept.
+// .
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body SetDuressPasswordRequest true "Password, duress password and alert setting"
+// @Success      204 "Duress password set"
+// @Failure      400 {object} response.Error "Bad request - invalid input data or duress password reuses password"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
+// @Failure      403 {object} response.Error "Forbidden - wrong password"
+// @Failure      409 {object} response.Error "Conflict - duress password unavailable"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /auth/duress [put]
+// .
+func (h *Handler) SetDuressPassword(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	userID, err := extractor.UserID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+
+	// req holds the deserialized JSON duress password request.
+	var req SetDuressPasswordRequest
+	if err := extractor.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	err = h.s.SetDuressPassword(c, auth.SetDuressPasswordParams{
+		UserID:         userID,
+		Password:       req.Password,
+		DuressPassword: req.DuressPassword,
+		Alert:          req.Alert,
+	})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.Data(http.StatusNoContent, "", nil)
+}
+
+// RequestLoginChange requests a change of the login of the authenticated user.
+// @Summary      Request login change
+// @Description  Re-checks the password and sends confirmation tokens to the old and the new login. Logins that
+// @Description  are e-mail addresses get them by e-mail, others over the security notification channels. The
+// @Description  change is applied by /auth/login-change/confirm with both tokens.
+// .
+// @Tags         Account
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body RequestLoginChangeRequest true "New login and current password"
+// @Success      202 {object} RequestLoginChangeResponse "Confirmations sent"
+// @Failure      400 {object} response.Error "Bad request - invalid input data"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
+// @Failure      403 {object} response.Error "Forbidden - wrong password"
+// @Failure      409 {object} response.Error "Conflict - login taken or confirmations cannot be delivered"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /account/login-change [post]
+// .
+func (h *Handler) RequestLoginChange(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	userID, err := extractor.UserID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+
+	// req holds the deserialized JSON login change request.
+	var req RequestLoginChangeRequest
+	if err := extractor.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	expiresAt, err := h.s.RequestLoginChange(c, auth.RequestLoginChangeParams{
+		UserID:   userID,
+		NewLogin: req.NewLogin,
+		Password: req.Password,
+	})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, RequestLoginChangeResponse{ExpiresAt: expiresAt})
+}
+
+// ConfirmLoginChange applies a login change confirmed from both logins.
+// @Summary      Confirm login change
+// @Description  Applies a requested login change with the tokens sent to the old and the new login and signs out
+// @Description  all sessions of the user.
+// .
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        request body ConfirmLoginChangeRequest true "Confirmation tokens sent to both logins"
+// @Success      204 "Login changed"
+// @Failure      400 {object} response.Error "Bad request - invalid input data"
+// @Failure      403 {object} response.Error "Forbidden - invalid, expired or used confirmation tokens"
+// @Failure      409 {object} response.Error "Conflict - login taken"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /auth/login-change/confirm [post]
+// .
+func (h *Handler) ConfirmLoginChange(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	// req holds the deserialized JSON confirmation request.
+	var req ConfirmLoginChangeRequest
+	if err := extractor.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	err := h.s.ConfirmLoginChange(c, auth.ConfirmLoginChangeParams{OldToken: req.OldToken, NewToken: req.NewToken})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.Data(http.StatusNoContent, "", nil)
+}
+
+// newAccessToken converts an application layer access token to delivery DTO.
+func newAccessToken(t auth.AccessToken) AccessToken {
+	return AccessToken{
+		AccessToken: t.AccessToken,
+		ExpiresAt:   t.ExpiresAt,
+		TokenType:   t.TokenType,
+	}
 }
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/auth"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/consts"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -19,15 +20,30 @@ import (
 
 // mockAuthService is a mock implementation of the Service interface for testing.
 type mockAuthService struct {
-	registerFunc func(context.Context, auth.RegisterParams) (uuid.UUID, error)
-	loginFunc    func(context.Context, auth.LoginParams) (auth.AccessToken, error)
+	registerFunc    func(context.Context, auth.RegisterParams) (auth.Registration, error)
+	loginFunc       func(context.Context, auth.LoginParams) (auth.AccessToken, error)
+	verifyLoginFunc func(context.Context, auth.VerifyLoginParams) (auth.AccessToken, error)
+	approveFunc     func(context.Context, auth.ApproveLoginParams) error
+	claimFunc       func(context.Context, auth.ClaimLoginParams) (auth.AccessToken, error)
+	pendingFunc     func(context.Context, uuid.UUID) ([]*auth.PendingLogin, error)
+	approvePending  func(context.Context, auth.ApprovePendingLoginParams) error
+	changePassword  func(context.Context, auth.ChangePasswordParams) (auth.AccessToken, error)
+	unlockSettings  func(context.Context, uuid.UUID) (auth.UnlockSettings, error)
+	setUnlock       func(context.Context, auth.SetUnlockSecretParams) error
+	requestChange   func(context.Context, auth.RequestLoginChangeParams) (time.Time, error)
+	confirmChange   func(context.Context, auth.ConfirmLoginChangeParams) error
+	codesLeft       func(context.Context, uuid.UUID) (int, error)
+	regenerateCodes func(context.Context, auth.RegenerateRecoveryCodesParams) ([]string, error)
+	recoverAccount  func(context.Context, auth.RecoverAccountParams) (auth.AccessToken, error)
+	duressSettings  func(context.Context, uuid.UUID) (auth.DuressSettings, error)
+	setDuress       func(context.Context, auth.SetDuressPasswordParams) error
 }
 
-func (m *mockAuthService) Register(ctx context.Context, params auth.RegisterParams) (uuid.UUID, error) {
+func (m *mockAuthService) Register(ctx context.Context, params auth.RegisterParams) (auth.Registration, error) {
 	if m.registerFunc != nil {
 		return m.registerFunc(ctx, params)
 	}
-	return uuid.Nil, nil
+	return auth.Registration{}, nil
 }
 
 func (m *mockAuthService) Login(ctx context.Context, params auth.LoginParams) (auth.AccessToken, error) {
@@ -37,6 +53,126 @@ func (m *mockAuthService) Login(ctx context.Context, params auth.LoginParams) (a
 	return auth.AccessToken{}, nil
 }
 
+func (m *mockAuthService) VerifyLogin(
+	ctx context.Context,
+	params auth.VerifyLoginParams,
+) (auth.AccessToken, error) {
+	if m.verifyLoginFunc != nil {
+		return m.verifyLoginFunc(ctx, params)
+	}
+	return auth.AccessToken{}, nil
+}
+
+func (m *mockAuthService) ApproveLogin(ctx context.Context, params auth.ApproveLoginParams) error {
+	if m.approveFunc != nil {
+		return m.approveFunc(ctx, params)
+	}
+	return nil
+}
+
+func (m *mockAuthService) ClaimLogin(ctx context.Context, params auth.ClaimLoginParams) (auth.AccessToken, error) {
+	if m.claimFunc != nil {
+		return m.claimFunc(ctx, params)
+	}
+	return auth.AccessToken{}, nil
+}
+
+func (m *mockAuthService) PendingLogins(ctx context.Context, userID uuid.UUID) ([]*auth.PendingLogin, error) {
+	if m.pendingFunc != nil {
+		return m.pendingFunc(ctx, userID)
+	}
+	return nil, nil
+}
+
+func (m *mockAuthService) ApprovePendingLogin(ctx context.Context, params auth.ApprovePendingLoginParams) error {
+	if m.approvePending != nil {
+		return m.approvePending(ctx, params)
+	}
+	return nil
+}
+
+func (m *mockAuthService) ChangePassword(
+	ctx context.Context,
+	params auth.ChangePasswordParams,
+) (auth.AccessToken, error) {
+	if m.changePassword != nil {
+		return m.changePassword(ctx, params)
+	}
+	return auth.AccessToken{}, nil
+}
+
+func (m *mockAuthService) RecoveryCodesLeft(ctx context.Context, userID uuid.UUID) (int, error) {
+	if m.codesLeft != nil {
+		return m.codesLeft(ctx, userID)
+	}
+	return 0, nil
+}
+
+func (m *mockAuthService) RegenerateRecoveryCodes(
+	ctx context.Context,
+	params auth.RegenerateRecoveryCodesParams,
+) ([]string, error) {
+	if m.regenerateCodes != nil {
+		return m.regenerateCodes(ctx, params)
+	}
+	return nil, nil
+}
+
+func (m *mockAuthService) RecoverAccount(
+	ctx context.Context,
+	params auth.RecoverAccountParams,
+) (auth.AccessToken, error) {
+	if m.recoverAccount != nil {
+		return m.recoverAccount(ctx, params)
+	}
+	return auth.AccessToken{}, nil
+}
+
+func (m *mockAuthService) UnlockSettings(ctx context.Context, userID uuid.UUID) (auth.UnlockSettings, error) {
+	if m.unlockSettings != nil {
+		return m.unlockSettings(ctx, userID)
+	}
+	return auth.UnlockSettings{}, nil
+}
+
+func (m *mockAuthService) DuressSettings(ctx context.Context, userID uuid.UUID) (auth.DuressSettings, error) {
+	if m.duressSettings != nil {
+		return m.duressSettings(ctx, userID)
+	}
+	return auth.DuressSettings{}, nil
+}
+
+func (m *mockAuthService) SetDuressPassword(ctx context.Context, params auth.SetDuressPasswordParams) error {
+	if m.setDuress != nil {
+		return m.setDuress(ctx, params)
+	}
+	return nil
+}
+
+func (m *mockAuthService) SetUnlockSecret(ctx context.Context, params auth.SetUnlockSecretParams) error {
+	if m.setUnlock != nil {
+		return m.setUnlock(ctx, params)
+	}
+	return nil
+}
+
+func (m *mockAuthService) RequestLoginChange(
+	ctx context.Context,
+	params auth.RequestLoginChangeParams,
+) (time.Time, error) {
+	if m.requestChange != nil {
+		return m.requestChange(ctx, params)
+	}
+	return time.Time{}, nil
+}
+
+func (m *mockAuthService) ConfirmLoginChange(ctx context.Context, params auth.ConfirmLoginChangeParams) error {
+	if m.confirmChange != nil {
+		return m.confirmChange(ctx, params)
+	}
+	return nil
+}
+
 func TestNewHandler(t *testing.T) {
 	t.Parallel()
 
@@ -67,10 +203,10 @@ func TestHandler_Register(t *testing.T) {
 			contentType: "application/json",
 			mockSetup: func(m *mockAuthService) {
 				testID := uuid.New()
-				m.registerFunc = func(ctx context.Context, params auth.RegisterParams) (uuid.UUID, error) {
+				m.registerFunc = func(ctx context.Context, params auth.RegisterParams) (auth.Registration, error) {
 					assert.Equal(t, "test@example.com", params.Login)
 					assert.Equal(t, "securePassword123", params.Password)
-					return testID, nil
+					return auth.Registration{UserID: testID, RecoveryCodes: []string{"abcd-efgh-ijkl-mnop"}}, nil
 				}
 			},
 			expectedStatus: http.StatusCreated,
@@ -80,6 +216,7 @@ func TestHandler_Register(t *testing.T) {
 				err := json.Unmarshal(body, &resp)
 				require.NoError(t, err)
 				assert.NotEqual(t, uuid.Nil, resp.ID)
+				assert.Equal(t, []string{"abcd-efgh-ijkl-mnop"}, resp.RecoveryCodes)
 			},
 		},
 		{
@@ -87,9 +224,9 @@ func TestHandler_Register(t *testing.T) {
 			requestBody: `{"login": "test@example.com", "password":`,
 			contentType: "application/json",
 			mockSetup: func(m *mockAuthService) {
-				m.registerFunc = func(ctx context.Context, params auth.RegisterParams) (uuid.UUID, error) {
+				m.registerFunc = func(ctx context.Context, params auth.RegisterParams) (auth.Registration, error) {
 					t.Error("service should not be called with invalid JSON")
-					return uuid.Nil, nil
+					return auth.Registration{}, nil
 				}
 			},
 			expectedStatus: http.StatusBadRequest,
@@ -105,9 +242,9 @@ func TestHandler_Register(t *testing.T) {
 			},
 			contentType: "application/json",
 			mockSetup: func(m *mockAuthService) {
-				m.registerFunc = func(ctx context.Context, params auth.RegisterParams) (uuid.UUID, error) {
+				m.registerFunc = func(ctx context.Context, params auth.RegisterParams) (auth.Registration, error) {
 					t.Error("service should not be called with missing login")
-					return uuid.Nil, nil
+					return auth.Registration{}, nil
 				}
 			},
 			expectedStatus: http.StatusBadRequest,
@@ -123,9 +260,9 @@ func TestHandler_Register(t *testing.T) {
 			},
 			contentType: "application/json",
 			mockSetup: func(m *mockAuthService) {
-				m.registerFunc = func(ctx context.Context, params auth.RegisterParams) (uuid.UUID, error) {
+				m.registerFunc = func(ctx context.Context, params auth.RegisterParams) (auth.Registration, error) {
 					t.Error("service should not be called with missing password")
-					return uuid.Nil, nil
+					return auth.Registration{}, nil
 				}
 			},
 			expectedStatus: http.StatusBadRequest,
@@ -142,8 +279,8 @@ func TestHandler_Register(t *testing.T) {
 			},
 			contentType: "application/json",
 			mockSetup: func(m *mockAuthService) {
-				m.registerFunc = func(ctx context.Context, params auth.RegisterParams) (uuid.UUID, error) {
-					return uuid.Nil, auth.ErrAuthUserAlreadyExists
+				m.registerFunc = func(ctx context.Context, params auth.RegisterParams) (auth.Registration, error) {
+					return auth.Registration{}, auth.ErrAuthUserAlreadyExists
 				}
 			},
 			expectedStatus: http.StatusConflict,
@@ -160,8 +297,8 @@ func TestHandler_Register(t *testing.T) {
 			},
 			contentType: "application/json",
 			mockSetup: func(m *mockAuthService) {
-				m.registerFunc = func(ctx context.Context, params auth.RegisterParams) (uuid.UUID, error) {
-					return uuid.Nil, auth.ErrAuthIncorrectLogin
+				m.registerFunc = func(ctx context.Context, params auth.RegisterParams) (auth.Registration, error) {
+					return auth.Registration{}, auth.ErrAuthIncorrectLogin
 				}
 			},
 			expectedStatus: http.StatusBadRequest,
@@ -178,8 +315,8 @@ func TestHandler_Register(t *testing.T) {
 			},
 			contentType: "application/json",
 			mockSetup: func(m *mockAuthService) {
-				m.registerFunc = func(ctx context.Context, params auth.RegisterParams) (uuid.UUID, error) {
-					return uuid.Nil, auth.ErrAuthIncorrectPassword
+				m.registerFunc = func(ctx context.Context, params auth.RegisterParams) (auth.Registration, error) {
+					return auth.Registration{}, auth.ErrAuthIncorrectPassword
 				}
 			},
 			expectedStatus: http.StatusBadRequest,
@@ -196,8 +333,8 @@ func TestHandler_Register(t *testing.T) {
 			},
 			contentType: "application/json",
 			mockSetup: func(m *mockAuthService) {
-				m.registerFunc = func(ctx context.Context, params auth.RegisterParams) (uuid.UUID, error) {
-					return uuid.Nil, auth.ErrAuthTechError
+				m.registerFunc = func(ctx context.Context, params auth.RegisterParams) (auth.Registration, error) {
+					return auth.Registration{}, auth.ErrAuthTechError
 				}
 			},
 			expectedStatus: http.StatusInternalServerError,
@@ -214,8 +351,8 @@ func TestHandler_Register(t *testing.T) {
 			},
 			contentType: "application/json",
 			mockSetup: func(m *mockAuthService) {
-				m.registerFunc = func(ctx context.Context, params auth.RegisterParams) (uuid.UUID, error) {
-					return uuid.Nil, auth.ErrAuthAppError
+				m.registerFunc = func(ctx context.Context, params auth.RegisterParams) (auth.Registration, error) {
+					return auth.Registration{}, auth.ErrAuthAppError
 				}
 			},
 			expectedStatus: http.StatusBadRequest,
@@ -229,9 +366,9 @@ func TestHandler_Register(t *testing.T) {
 			requestBody: "not-json",
 			contentType: "text/plain",
 			mockSetup: func(m *mockAuthService) {
-				m.registerFunc = func(ctx context.Context, params auth.RegisterParams) (uuid.UUID, error) {
+				m.registerFunc = func(ctx context.Context, params auth.RegisterParams) (auth.Registration, error) {
 					t.Error("service should not be called with invalid content type")
-					return uuid.Nil, nil
+					return auth.Registration{}, nil
 				}
 			},
 			expectedStatus: http.StatusBadRequest,
@@ -321,6 +458,33 @@ func TestHandler_Login(t *testing.T) {
 				assert.False(t, resp.ExpiresAt.IsZero())
 			},
 		},
+		{
+			name: "login held for step-up verification",
+			requestBody: LoginRequest{
+				Login:    "test@example.com",
+				Password: "securePassword123",
+			},
+			contentType: "application/json",
+			mockSetup: func(m *mockAuthService) {
+				m.loginFunc = func(ctx context.Context, params auth.LoginParams) (auth.AccessToken, error) {
+					return auth.AccessToken{}, &auth.StepUpRequiredError{Challenge: auth.StepUpChallenge{
+						ExpiresAt: time.Now().Add(10 * time.Minute),
+						Reasons:   []string{"new_device"},
+						ID:        uuid.MustParse("123e4567-e89b-12d3-a456-426614174000"),
+					}}
+				}
+			},
+			expectedStatus: http.StatusAccepted,
+			validateResp: func(t *testing.T, body []byte) {
+				t.Helper()
+				var resp StepUpResponse
+				err := json.Unmarshal(body, &resp)
+				require.NoError(t, err)
+				assert.Equal(t, "123e4567-e89b-12d3-a456-426614174000", resp.ChallengeID.String())
+				assert.Equal(t, []string{"new_device"}, resp.Reasons)
+				assert.False(t, resp.ExpiresAt.IsZero())
+			},
+		},
 		{
 			name:        "invalid JSON body",
 			requestBody: `{"login": "test@example.com", "password":`,
@@ -554,3 +718,855 @@ func TestHandler_Login(t *testing.T) {
 		})
 	}
 }
+
+func TestHandler_VerifyLogin(t *testing.T) {
+	t.Parallel()
+
+	challengeID := uuid.New()
+
+	tests := []struct {
+		requestBody    interface{}
+		mockSetup      func(*mockAuthService)
+		validateResp   func(t *testing.T, body []byte)
+		name           string
+		expectedStatus int
+	}{
+		{
+			name:        "successful verification",
+			requestBody: VerifyLoginRequest{ChallengeID: challengeID.String(), Code: "123456"},
+			mockSetup: func(m *mockAuthService) {
+				m.verifyLoginFunc = func(ctx context.Context, params auth.VerifyLoginParams) (auth.AccessToken, error) {
+					assert.Equal(t, challengeID, params.ChallengeID)
+					assert.Equal(t, "123456", params.Code)
+					return auth.AccessToken{
+						AccessToken: "test-jwt-token",
+						ExpiresAt:   time.Now().Add(time.Hour),
+						TokenType:   "Bearer",
+					}, nil
+				}
+			},
+			expectedStatus: http.StatusOK,
+			validateResp: func(t *testing.T, body []byte) {
+				t.Helper()
+				var resp AccessToken
+				err := json.Unmarshal(body, &resp)
+				require.NoError(t, err)
+				assert.Equal(t, "test-jwt-token", resp.AccessToken)
+			},
+		},
+		{
+			name:        "invalid challenge id",
+			requestBody: VerifyLoginRequest{ChallengeID: "not-a-uuid", Code: "123456"},
+			mockSetup: func(m *mockAuthService) {
+				m.verifyLoginFunc = func(ctx context.Context, params auth.VerifyLoginParams) (auth.AccessToken, error) {
+					t.Error("service should not be called with invalid challenge id")
+					return auth.AccessToken{}, nil
+				}
+			},
+			expectedStatus: http.StatusBadRequest,
+			validateResp: func(t *testing.T, body []byte) {
+				t.Helper()
+				assert.Contains(t, string(body), "Bad Request")
+			},
+		},
+		{
+			name:        "missing code",
+			requestBody: VerifyLoginRequest{ChallengeID: challengeID.String()},
+			mockSetup: func(m *mockAuthService) {
+				m.verifyLoginFunc = func(ctx context.Context, params auth.VerifyLoginParams) (auth.AccessToken, error) {
+					t.Error("service should not be called with missing code")
+					return auth.AccessToken{}, nil
+				}
+			},
+			expectedStatus: http.StatusBadRequest,
+			validateResp: func(t *testing.T, body []byte) {
+				t.Helper()
+				assert.Contains(t, string(body), "Bad Request")
+			},
+		},
+		{
+			name:        "recovery code instead of verification code",
+			requestBody: VerifyLoginRequest{ChallengeID: challengeID.String(), RecoveryCode: "abcd-efgh-ijkl-mnop"},
+			mockSetup: func(m *mockAuthService) {
+				m.verifyLoginFunc = func(ctx context.Context, params auth.VerifyLoginParams) (auth.AccessToken, error) {
+					assert.Empty(t, params.Code)
+					assert.Equal(t, "abcd-efgh-ijkl-mnop", params.RecoveryCode)
+					return auth.AccessToken{AccessToken: "test-jwt-token", TokenType: "Bearer"}, nil
+				}
+			},
+			expectedStatus: http.StatusOK,
+			validateResp: func(t *testing.T, body []byte) {
+				t.Helper()
+				assert.Contains(t, string(body), "test-jwt-token")
+			},
+		},
+		{
+			name: "both codes",
+			requestBody: VerifyLoginRequest{
+				ChallengeID: challengeID.String(), Code: "123456", RecoveryCode: "abcd-efgh-ijkl-mnop",
+			},
+			mockSetup: func(m *mockAuthService) {
+				m.verifyLoginFunc = func(ctx context.Context, params auth.VerifyLoginParams) (auth.AccessToken, error) {
+					t.Error("service should not be called with both codes")
+					return auth.AccessToken{}, nil
+				}
+			},
+			expectedStatus: http.StatusBadRequest,
+			validateResp: func(t *testing.T, body []byte) {
+				t.Helper()
+				assert.Contains(t, string(body), "Bad Request")
+			},
+		},
+		{
+			name:        "used recovery code",
+			requestBody: VerifyLoginRequest{ChallengeID: challengeID.String(), RecoveryCode: "abcd-efgh-ijkl-mnop"},
+			mockSetup: func(m *mockAuthService) {
+				m.verifyLoginFunc = func(ctx context.Context, params auth.VerifyLoginParams) (auth.AccessToken, error) {
+					return auth.AccessToken{}, auth.ErrAuthRecoveryCodeInvalid
+				}
+			},
+			expectedStatus: http.StatusUnauthorized,
+			validateResp: func(t *testing.T, body []byte) {
+				t.Helper()
+				assert.Contains(t, string(body), "recovery code is invalid")
+			},
+		},
+		{
+			name:        "wrong or expired code",
+			requestBody: VerifyLoginRequest{ChallengeID: challengeID.String(), Code: "000000"},
+			mockSetup: func(m *mockAuthService) {
+				m.verifyLoginFunc = func(ctx context.Context, params auth.VerifyLoginParams) (auth.AccessToken, error) {
+					return auth.AccessToken{}, auth.ErrAuthStepUpFailed
+				}
+			},
+			expectedStatus: http.StatusUnauthorized,
+			validateResp: func(t *testing.T, body []byte) {
+				t.Helper()
+				assert.Contains(t, string(body), "The verification code is invalid or has expired")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			mockService := &mockAuthService{}
+			tt.mockSetup(mockService)
+			handler := NewHandler(mockService)
+
+			bodyBytes, err := json.Marshal(tt.requestBody)
+			require.NoError(t, err)
+			req := httptest.NewRequest(http.MethodPost, "/auth/login/verify", bytes.NewReader(bodyBytes))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+
+			c, _ := gin.CreateTestContext(rec)
+			c.Request = req
+
+			handler.VerifyLogin(c)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			tt.validateResp(t, rec.Body.Bytes())
+		})
+	}
+}
+
+func TestHandler_ApproveLogin(t *testing.T) {
+	t.Parallel()
+
+	challengeID := uuid.New()
+
+	tests := []struct {
+		approveErr     error
+		name           string
+		query          string
+		wantBody       string
+		expectedStatus int
+	}{
+		{
+			name:           "approved",
+			query:          "?challenge_id=" + challengeID.String() + "&token=approval-token",
+			expectedStatus: http.StatusOK,
+			wantBody:       "The sign-in has been approved",
+		},
+		{
+			name:           "missing token",
+			query:          "?challenge_id=" + challengeID.String(),
+			expectedStatus: http.StatusBadRequest,
+			wantBody:       "Bad Request",
+		},
+		{
+			name:           "invalid challenge id",
+			query:          "?challenge_id=not-a-uuid&token=approval-token",
+			expectedStatus: http.StatusBadRequest,
+			wantBody:       "Bad Request",
+		},
+		{
+			name:           "invalid or expired link",
+			query:          "?challenge_id=" + challengeID.String() + "&token=forged",
+			approveErr:     auth.ErrAuthStepUpFailed,
+			expectedStatus: http.StatusUnauthorized,
+			wantBody:       "The verification code is invalid or has expired",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			handler := NewHandler(&mockAuthService{
+				approveFunc: func(_ context.Context, params auth.ApproveLoginParams) error {
+					assert.Equal(t, challengeID, params.ChallengeID)
+					return tt.approveErr
+				},
+			})
+
+			rec := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rec)
+			c.Request = httptest.NewRequest(http.MethodGet, "/auth/login/approve"+tt.query, nil)
+
+			handler.ApproveLogin(c)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.Contains(t, rec.Body.String(), tt.wantBody)
+		})
+	}
+}
+
+func TestHandler_ClaimLogin(t *testing.T) {
+	t.Parallel()
+
+	challengeID := uuid.New()
+
+	tests := []struct {
+		requestBody    interface{}
+		claimErr       error
+		name           string
+		wantBody       string
+		expectedStatus int
+	}{
+		{
+			name:           "approved login",
+			requestBody:    ClaimLoginRequest{ChallengeID: challengeID.String()},
+			expectedStatus: http.StatusOK,
+			wantBody:       "test-jwt-token",
+		},
+		{
+			name:           "awaiting approval",
+			requestBody:    ClaimLoginRequest{ChallengeID: challengeID.String()},
+			claimErr:       auth.ErrAuthApprovalPending,
+			expectedStatus: http.StatusConflict,
+			wantBody:       "The login has not been approved yet",
+		},
+		{
+			name:           "expired held login",
+			requestBody:    ClaimLoginRequest{ChallengeID: challengeID.String()},
+			claimErr:       auth.ErrAuthStepUpFailed,
+			expectedStatus: http.StatusUnauthorized,
+			wantBody:       "The verification code is invalid or has expired",
+		},
+		{
+			name:           "invalid challenge id",
+			requestBody:    ClaimLoginRequest{ChallengeID: "not-a-uuid"},
+			expectedStatus: http.StatusBadRequest,
+			wantBody:       "Bad Request",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			handler := NewHandler(&mockAuthService{
+				claimFunc: func(_ context.Context, params auth.ClaimLoginParams) (auth.AccessToken, error) {
+					assert.Equal(t, challengeID, params.ChallengeID)
+					if tt.claimErr != nil {
+						return auth.AccessToken{}, tt.claimErr
+					}
+					return auth.AccessToken{AccessToken: "test-jwt-token", TokenType: "Bearer"}, nil
+				},
+			})
+
+			bodyBytes, err := json.Marshal(tt.requestBody)
+			require.NoError(t, err)
+			rec := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rec)
+			c.Request = httptest.NewRequest(http.MethodPost, "/auth/login/claim", bytes.NewReader(bodyBytes))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			handler.ClaimLogin(c)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.Contains(t, rec.Body.String(), tt.wantBody)
+		})
+	}
+}
+
+func TestHandler_PendingLogins(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	login := &auth.PendingLogin{
+		ID:        uuid.New(),
+		UserAgent: "Chrome",
+		Location:  "BR",
+		ExpiresAt: time.Now().Add(time.Minute),
+	}
+
+	gin.SetMode(gin.TestMode)
+	handler := NewHandler(&mockAuthService{
+		pendingFunc: func(_ context.Context, id uuid.UUID) ([]*auth.PendingLogin, error) {
+			assert.Equal(t, userID, id)
+			return []*auth.PendingLogin{login}, nil
+		},
+	})
+
+	rec := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rec)
+	c.Request = httptest.NewRequest(http.MethodGet, "/account/logins/pending", nil)
+	c.Set(consts.CtxKeyUserID, userID)
+
+	handler.PendingLogins(c)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	var resp PendingLoginsResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	require.Len(t, resp.Logins, 1)
+	assert.Equal(t, login.ID, resp.Logins[0].ID)
+	assert.Equal(t, "Chrome", resp.Logins[0].UserAgent)
+	assert.Empty(t, resp.Logins[0].IP)
+}
+
+func TestHandler_ApprovePendingLogin(t *testing.T) {
+	t.Parallel()
+
+	userID, challengeID := uuid.New(), uuid.New()
+
+	tests := []struct {
+		approveErr     error
+		name           string
+		id             string
+		expectedStatus int
+	}{
+		{name: "approved", id: challengeID.String(), expectedStatus: http.StatusNoContent},
+		{name: "invalid id", id: "not-a-uuid", expectedStatus: http.StatusBadRequest},
+		{
+			name:           "unknown held login",
+			id:             challengeID.String(),
+			approveErr:     auth.ErrAuthStepUpFailed,
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			handler := NewHandler(&mockAuthService{
+				approvePending: func(_ context.Context, params auth.ApprovePendingLoginParams) error {
+					assert.Equal(t, userID, params.UserID)
+					assert.Equal(t, challengeID, params.ChallengeID)
+					return tt.approveErr
+				},
+			})
+
+			rec := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rec)
+			c.Request = httptest.NewRequest(http.MethodPost, "/account/logins/"+tt.id+"/approve", nil)
+			c.Params = gin.Params{{Key: "id", Value: tt.id}}
+			c.Set(consts.CtxKeyUserID, userID)
+
+			handler.ApprovePendingLogin(c)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+}
+
+func TestHandler_RequestLoginChange(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	expiresAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		requestErr     error
+		name           string
+		body           string
+		expectedStatus int
+	}{
+		{
+			name:           "confirmations sent",
+			body:           `{"new_login":"user@example.org","password":"securePassword123"}`,
+			expectedStatus: http.StatusAccepted,
+		},
+		{name: "missing password", body: `{"new_login":"user@example.org"}`, expectedStatus: http.StatusBadRequest},
+		{
+			name:           "wrong password",
+			body:           `{"new_login":"user@example.org","password":"wrong"}`,
+			requestErr:     auth.ErrAuthPasswordMismatch,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "undeliverable confirmations",
+			body:           `{"new_login":"user@example.org","password":"securePassword123"}`,
+			requestErr:     auth.ErrAuthLoginChangeUndeliverable,
+			expectedStatus: http.StatusConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			handler := NewHandler(&mockAuthService{
+				requestChange: func(_ context.Context, params auth.RequestLoginChangeParams) (time.Time, error) {
+					assert.Equal(t, userID, params.UserID)
+					assert.Equal(t, "user@example.org", params.NewLogin)
+					return expiresAt, tt.requestErr
+				},
+			})
+
+			rec := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rec)
+			c.Request = httptest.NewRequest(http.MethodPost, "/account/login-change", bytes.NewBufferString(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Set(consts.CtxKeyUserID, userID)
+
+			handler.RequestLoginChange(c)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.expectedStatus == http.StatusAccepted {
+				// resp holds the decoded response body.
+				var resp RequestLoginChangeResponse
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+				assert.Equal(t, expiresAt, resp.ExpiresAt)
+			}
+		})
+	}
+}
+
+func TestHandler_ConfirmLoginChange(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		confirmErr     error
+		name           string
+		body           string
+		expectedStatus int
+	}{
+		{name: "change applied", body: `{"old_token":"old","new_token":"new"}`, expectedStatus: http.StatusNoContent},
+		{name: "missing token", body: `{"old_token":"old"}`, expectedStatus: http.StatusBadRequest},
+		{
+			name:           "invalid tokens",
+			body:           `{"old_token":"old","new_token":"new"}`,
+			confirmErr:     auth.ErrAuthLoginChangeInvalid,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "login taken",
+			body:           `{"old_token":"old","new_token":"new"}`,
+			confirmErr:     auth.ErrAuthUserAlreadyExists,
+			expectedStatus: http.StatusConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			handler := NewHandler(&mockAuthService{
+				confirmChange: func(_ context.Context, params auth.ConfirmLoginChangeParams) error {
+					assert.Equal(t, auth.ConfirmLoginChangeParams{OldToken: "old", NewToken: "new"}, params)
+					return tt.confirmErr
+				},
+			})
+
+			rec := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rec)
+			c.Request = httptest.NewRequest(
+				http.MethodPost, "/auth/login-change/confirm", bytes.NewBufferString(tt.body),
+			)
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			handler.ConfirmLoginChange(c)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+}
+
+func TestHandler_ChangePassword(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	validBody := `{"old_password":"securePassword123","new_password":"evenMoreSecurePassword456"}`
+
+	tests := []struct {
+		changeErr      error
+		name           string
+		body           string
+		expectedStatus int
+	}{
+		{name: "password changed", body: validBody, expectedStatus: http.StatusOK},
+		{name: "missing new password", body: `{"old_password":"x"}`, expectedStatus: http.StatusBadRequest},
+		{
+			name:           "wrong current password",
+			body:           validBody,
+			changeErr:      auth.ErrAuthPasswordMismatch,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "weak new password",
+			body:           validBody,
+			changeErr:      auth.ErrAuthIncorrectPassword,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			handler := NewHandler(&mockAuthService{
+				changePassword: func(_ context.Context, params auth.ChangePasswordParams) (auth.AccessToken, error) {
+					assert.Equal(t, userID, params.UserID)
+					assert.Equal(t, "securePassword123", params.OldPassword)
+					if tt.changeErr != nil {
+						return auth.AccessToken{}, tt.changeErr
+					}
+					return auth.AccessToken{AccessToken: "new_token", TokenType: "Bearer"}, nil
+				},
+			})
+
+			rec := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rec)
+			c.Request = httptest.NewRequest(http.MethodPost, "/auth/change-password", bytes.NewBufferString(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Set(consts.CtxKeyUserID, userID)
+
+			handler.ChangePassword(c)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.expectedStatus == http.StatusOK {
+				// resp holds the decoded response body.
+				var resp AccessToken
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+				assert.Equal(t, "new_token", resp.AccessToken)
+			}
+		})
+	}
+}
+
+func TestHandler_RecoveryCodes(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+	userID := uuid.New()
+	handler := NewHandler(&mockAuthService{
+		codesLeft: func(_ context.Context, id uuid.UUID) (int, error) {
+			assert.Equal(t, userID, id)
+			return 7, nil
+		},
+	})
+
+	rec := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rec)
+	c.Request = httptest.NewRequest(http.MethodGet, "/auth/recovery-codes", nil)
+	c.Set(consts.CtxKeyUserID, userID)
+
+	handler.RecoveryCodes(c)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"remaining":7}`, rec.Body.String())
+}
+
+func TestHandler_RegenerateRecoveryCodes(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	validBody := `{"password":"securePassword123"}`
+
+	tests := []struct {
+		regenerateErr  error
+		name           string
+		body           string
+		expectedStatus int
+	}{
+		{name: "codes regenerated", body: validBody, expectedStatus: http.StatusCreated},
+		{name: "missing password", body: `{}`, expectedStatus: http.StatusBadRequest},
+		{
+			name:           "wrong password",
+			body:           validBody,
+			regenerateErr:  auth.ErrAuthPasswordMismatch,
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			handler := NewHandler(&mockAuthService{
+				regenerateCodes: func(_ context.Context, params auth.RegenerateRecoveryCodesParams) ([]string, error) {
+					assert.Equal(t, userID, params.UserID)
+					assert.Equal(t, "securePassword123", params.Password)
+					if tt.regenerateErr != nil {
+						return nil, tt.regenerateErr
+					}
+					return []string{"abcd-efgh-ijkl-mnop", "qrst-uvwx-yz23-4567"}, nil
+				},
+			})
+
+			rec := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rec)
+			c.Request = httptest.NewRequest(http.MethodPost, "/auth/recovery-codes", bytes.NewBufferString(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Set(consts.CtxKeyUserID, userID)
+
+			handler.RegenerateRecoveryCodes(c)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.expectedStatus == http.StatusCreated {
+				assert.JSONEq(t, `{"recovery_codes":["abcd-efgh-ijkl-mnop","qrst-uvwx-yz23-4567"],"remaining":2}`,
+					rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandler_RecoverAccount(t *testing.T) {
+	t.Parallel()
+
+	validBody := `{"login":"alice","recovery_code":"abcd-efgh-ijkl-mnop","new_password":"evenMoreSecurePassword456"}`
+
+	tests := []struct {
+		recoverErr     error
+		name           string
+		body           string
+		expectedStatus int
+	}{
+		{name: "account recovered", body: validBody, expectedStatus: http.StatusOK},
+		{name: "missing recovery code", body: `{"login":"alice","new_password":"x"}`, expectedStatus: http.StatusBadRequest},
+		{
+			name:           "invalid recovery code",
+			body:           validBody,
+			recoverErr:     auth.ErrAuthRecoveryCodeInvalid,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "disabled account",
+			body:           validBody,
+			recoverErr:     auth.ErrAuthUserDisabled,
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			handler := NewHandler(&mockAuthService{
+				recoverAccount: func(_ context.Context, params auth.RecoverAccountParams) (auth.AccessToken, error) {
+					assert.Equal(t, "alice", params.Login)
+					assert.Equal(t, "abcd-efgh-ijkl-mnop", params.RecoveryCode)
+					assert.Equal(t, "evenMoreSecurePassword456", params.NewPassword)
+					if tt.recoverErr != nil {
+						return auth.AccessToken{}, tt.recoverErr
+					}
+					return auth.AccessToken{AccessToken: "new_token", TokenType: "Bearer"}, nil
+				},
+			})
+
+			rec := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rec)
+			c.Request = httptest.NewRequest(http.MethodPost, "/auth/recover", bytes.NewBufferString(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			handler.RecoverAccount(c)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.expectedStatus == http.StatusOK {
+				// resp holds the decoded response body.
+				var resp AccessToken
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+				assert.Equal(t, "new_token", resp.AccessToken)
+			}
+		})
+	}
+}
+
+func TestHandler_UnlockSettings(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+	userID := uuid.New()
+	handler := NewHandler(&mockAuthService{
+		unlockSettings: func(_ context.Context, id uuid.UUID) (auth.UnlockSettings, error) {
+			assert.Equal(t, userID, id)
+			return auth.UnlockSettings{
+				KDF: "argon2id", Salt: []byte("0123456789abcdef"), KeySize: 32,
+				Time: 3, MemoryKiB: 65536, Threads: 4, Enabled: true,
+			}, nil
+		},
+	})
+
+	rec := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rec)
+	c.Request = httptest.NewRequest(http.MethodGet, "/auth/unlock-secret", nil)
+	c.Set(consts.CtxKeyUserID, userID)
+
+	handler.UnlockSettings(c)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"kdf":"argon2id","salt":"MDEyMzQ1Njc4OWFiY2RlZg==","key_size":32,"time":3,
+		"memory_kib":65536,"threads":4,"enabled":true}`, rec.Body.String())
+}
+
+func TestHandler_SetUnlockSecret(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	validBody := `{"salt":"MDEyMzQ1Njc4OWFiY2RlZg==","unlock_key":"AQID","password":"securePassword123"}`
+
+	tests := []struct {
+		setErr         error
+		name           string
+		body           string
+		expectedStatus int
+	}{
+		{name: "unlock secret set", body: validBody, expectedStatus: http.StatusNoContent},
+		{name: "missing unlock key", body: `{"salt":"MDEyMzQ1Njc4OWFiY2RlZg=="}`, expectedStatus: http.StatusBadRequest},
+		{name: "undecodable salt", body: `{"salt":"!","unlock_key":"AQID"}`, expectedStatus: http.StatusBadRequest},
+		{
+			name:           "wrong current unlock key",
+			body:           validBody,
+			setErr:         auth.ErrAuthUnlockInvalid,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "wrong key size",
+			body:           validBody,
+			setErr:         auth.ErrAuthIncorrectUnlockSecret,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			handler := NewHandler(&mockAuthService{
+				setUnlock: func(_ context.Context, params auth.SetUnlockSecretParams) error {
+					assert.Equal(t, userID, params.UserID)
+					assert.Equal(t, []byte("0123456789abcdef"), params.Salt)
+					assert.Equal(t, []byte{1, 2, 3}, params.UnlockKey)
+					return tt.setErr
+				},
+			})
+
+			rec := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rec)
+			c.Request = httptest.NewRequest(http.MethodPut, "/auth/unlock-secret", bytes.NewBufferString(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Set(consts.CtxKeyUserID, userID)
+
+			handler.SetUnlockSecret(c)
+
+			assert.Equal(t, tt.expectedStatus, c.Writer.Status())
+		})
+	}
+}
+
+func TestHandler_DuressSettings(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	gin.SetMode(gin.TestMode)
+	handler := NewHandler(&mockAuthService{
+		duressSettings: func(_ context.Context, id uuid.UUID) (auth.DuressSettings, error) {
+			assert.Equal(t, userID, id)
+			return auth.DuressSettings{Enabled: true, Alert: true}, nil
+		},
+	})
+
+	rec := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rec)
+	c.Request = httptest.NewRequest(http.MethodGet, "/auth/duress", http.NoBody)
+	c.Set(consts.CtxKeyUserID, userID)
+
+	handler.DuressSettings(c)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"enabled":true,"alert":true}`, rec.Body.String())
+}
+
+func TestHandler_SetDuressPassword(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	validBody := `{"password":"securePassword123","duress_password":"decoyPassword456","alert":true}`
+
+	tests := []struct {
+		setErr         error
+		name           string
+		body           string
+		expectedStatus int
+	}{
+		{name: "duress password set", body: validBody, expectedStatus: http.StatusNoContent},
+		{name: "missing password", body: `{"duress_password":"decoyPassword456"}`, expectedStatus: http.StatusBadRequest},
+		{name: "wrong password", body: validBody, setErr: auth.ErrAuthPasswordMismatch, expectedStatus: http.StatusForbidden},
+		{
+			name:           "duress password reuses password",
+			body:           validBody,
+			setErr:         auth.ErrAuthDuressPasswordReused,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "duress session",
+			body:           validBody,
+			setErr:         auth.ErrAuthDuressUnavailable,
+			expectedStatus: http.StatusConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			handler := NewHandler(&mockAuthService{
+				setDuress: func(_ context.Context, params auth.SetDuressPasswordParams) error {
+					assert.Equal(t, auth.SetDuressPasswordParams{
+						UserID:         userID,
+						Password:       "securePassword123",
+						DuressPassword: "decoyPassword456",
+						Alert:          true,
+					}, params)
+					return tt.setErr
+				},
+			})
+
+			rec := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rec)
+			c.Request = httptest.NewRequest(http.MethodPut, "/auth/duress", bytes.NewBufferString(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Set(consts.CtxKeyUserID, userID)
+
+			handler.SetDuressPassword(c)
+
+			assert.Equal(t, tt.expectedStatus, c.Writer.Status())
+		})
+	}
+}
@@ -1,11 +1,46 @@
 package auth
 
-import "github.com/gin-gonic/gin"
+import (
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/util"
+	"github.com/gin-gonic/gin"
+)
+
+// Guards contains the middleware run before registration and login requests reach the handler.
+type Guards struct {
+	// Register guards user registration.
+	Register []gin.HandlerFunc
+	// Login guards password login and account recovery; the held login endpoints are not guarded.
+	Login []gin.HandlerFunc
+	// Authenticated authenticates password, recovery code, unlock secret and duress password changes.
+	Authenticated []gin.HandlerFunc
+}
 
 // RegisterRoutes registers authentication endpoints on the provided router group.
-// Creates /auth/register and /auth/login endpoints with the specified handler.
-func RegisterRoutes(r *gin.RouterGroup, h *Handler) {
+// Creates /auth/register, /auth/login, the held login endpoints /auth/login/verify,
+// /auth/login/approve and /auth/login/claim, /auth/recover, /auth/login-change/confirm, /auth/change-password,
+// /auth/recovery-codes, /auth/unlock-secret and /auth/duress with the specified handler.
+func RegisterRoutes(r *gin.RouterGroup, h *Handler, g Guards) {
 	authGroup := r.Group("/auth")
-	authGroup.POST("/register", h.Register)
-	authGroup.POST("/login", h.Login)
+	authGroup.POST("/register", util.Chain(g.Register, h.Register)...)
+	authGroup.POST("/login", util.Chain(g.Login, h.Login)...)
+	authGroup.POST("/login/verify", h.VerifyLogin)
+	authGroup.GET("/login/approve", h.ApproveLogin)
+	authGroup.POST("/login/claim", h.ClaimLogin)
+	authGroup.POST("/recover", util.Chain(g.Login, h.RecoverAccount)...)
+	authGroup.POST("/login-change/confirm", h.ConfirmLoginChange)
+	authGroup.POST("/change-password", util.Chain(g.Authenticated, h.ChangePassword)...)
+	authGroup.GET("/recovery-codes", util.Chain(g.Authenticated, h.RecoveryCodes)...)
+	authGroup.POST("/recovery-codes", util.Chain(g.Authenticated, h.RegenerateRecoveryCodes)...)
+	authGroup.GET("/unlock-secret", util.Chain(g.Authenticated, h.UnlockSettings)...)
+	authGroup.PUT("/unlock-secret", util.Chain(g.Authenticated, h.SetUnlockSecret)...)
+	authGroup.GET("/duress", util.Chain(g.Authenticated, h.DuressSettings)...)
+	authGroup.PUT("/duress", util.Chain(g.Authenticated, h.SetDuressPassword)...)
+}
+
+// RegisterAccountRoutes registers held login approval and login change endpoints on the authenticated account
+// group. Creates /logins/pending, /logins/:id/approve and /login-change with the specified handler.
+func RegisterAccountRoutes(r *gin.RouterGroup, h *Handler) {
+	r.GET("/logins/pending", h.PendingLogins)
+	r.POST("/logins/:id/approve", h.ApprovePendingLogin)
+	r.POST("/login-change", h.RequestLoginChange)
 }
@@ -26,13 +26,25 @@ func TestRegisterRoutes(t *testing.T) {
 			expectedRoutes: []string{
 				"POST /auth/register",
 				"POST /auth/login",
+				"POST /auth/login/verify",
+				"GET /auth/login/approve",
+				"POST /auth/login/claim",
+				"POST /auth/recover",
+				"POST /auth/login-change/confirm",
+				"POST /auth/change-password",
+				"GET /auth/recovery-codes",
+				"POST /auth/recovery-codes",
+				"GET /auth/unlock-secret",
+				"PUT /auth/unlock-secret",
+				"GET /auth/duress",
+				"PUT /auth/duress",
 			},
 			validateFunc: func(t *testing.T, router *gin.Engine) {
 				t.Helper()
 				routes := router.Routes()
-				assert.Len(t, routes, 2)
+				assert.Len(t, routes, 14)
 
-				// Check that both routes are registered
+				// Check that all routes are registered
 				methodPaths := make(map[string]string)
 				for _, route := range routes {
 					methodPaths[route.Method+" "+route.Path] = route.Handler
@@ -40,6 +52,18 @@ func TestRegisterRoutes(t *testing.T) {
 
 				assert.Contains(t, methodPaths, "POST /auth/register")
 				assert.Contains(t, methodPaths, "POST /auth/login")
+				assert.Contains(t, methodPaths, "POST /auth/login/verify")
+				assert.Contains(t, methodPaths, "GET /auth/login/approve")
+				assert.Contains(t, methodPaths, "POST /auth/login/claim")
+				assert.Contains(t, methodPaths, "POST /auth/recover")
+				assert.Contains(t, methodPaths, "POST /auth/login-change/confirm")
+				assert.Contains(t, methodPaths, "POST /auth/change-password")
+				assert.Contains(t, methodPaths, "GET /auth/recovery-codes")
+				assert.Contains(t, methodPaths, "POST /auth/recovery-codes")
+				assert.Contains(t, methodPaths, "GET /auth/unlock-secret")
+				assert.Contains(t, methodPaths, "PUT /auth/unlock-secret")
+				assert.Contains(t, methodPaths, "GET /auth/duress")
+				assert.Contains(t, methodPaths, "PUT /auth/duress")
 			},
 		},
 	}
@@ -57,7 +81,7 @@ func TestRegisterRoutes(t *testing.T) {
 			require.NotNil(t, handler)
 
 			// Execute
-			RegisterRoutes(rootGroup, handler)
+			RegisterRoutes(rootGroup, handler, Guards{})
 
 			// Validate
 			tt.validateFunc(t, router)
@@ -77,14 +101,17 @@ func TestRegisterRoutes_Integration(t *testing.T) {
 	handler := NewHandler(mockService)
 
 	// Execute
-	RegisterRoutes(rootGroup, handler)
+	RegisterRoutes(rootGroup, handler, Guards{})
 
 	// Validate routes are accessible
 	routes := router.Routes()
-	require.Len(t, routes, 2)
+	require.Len(t, routes, 14)
 
 	// Check specific route paths
-	var registerFound, loginFound bool
+	var registerFound, loginFound, verifyFound, approveFound, claimFound, recoverFound, confirmFound, passwordFound bool
+	// unlockMethods, recoveryCodeMethods and duressMethods collect the methods registered for the unlock secret,
+	// recovery code and duress password routes.
+	var unlockMethods, recoveryCodeMethods, duressMethods []string
 	for _, route := range routes {
 		switch route.Path {
 		case "/api/auth/register":
@@ -93,11 +120,44 @@ func TestRegisterRoutes_Integration(t *testing.T) {
 		case "/api/auth/login":
 			assert.Equal(t, "POST", route.Method)
 			loginFound = true
+		case "/api/auth/login/verify":
+			assert.Equal(t, "POST", route.Method)
+			verifyFound = true
+		case "/api/auth/login/approve":
+			assert.Equal(t, "GET", route.Method)
+			approveFound = true
+		case "/api/auth/login/claim":
+			assert.Equal(t, "POST", route.Method)
+			claimFound = true
+		case "/api/auth/recover":
+			assert.Equal(t, "POST", route.Method)
+			recoverFound = true
+		case "/api/auth/login-change/confirm":
+			assert.Equal(t, "POST", route.Method)
+			confirmFound = true
+		case "/api/auth/change-password":
+			assert.Equal(t, "POST", route.Method)
+			passwordFound = true
+		case "/api/auth/recovery-codes":
+			recoveryCodeMethods = append(recoveryCodeMethods, route.Method)
+		case "/api/auth/unlock-secret":
+			unlockMethods = append(unlockMethods, route.Method)
+		case "/api/auth/duress":
+			duressMethods = append(duressMethods, route.Method)
 		}
 	}
 
 	assert.True(t, registerFound, "Register route should be registered")
 	assert.True(t, loginFound, "Login route should be registered")
+	assert.True(t, verifyFound, "Login verification route should be registered")
+	assert.True(t, approveFound, "Login approval route should be registered")
+	assert.True(t, claimFound, "Login claim route should be registered")
+	assert.True(t, recoverFound, "Account recovery route should be registered")
+	assert.True(t, confirmFound, "Login change confirmation route should be registered")
+	assert.True(t, passwordFound, "Password change route should be registered")
+	assert.ElementsMatch(t, []string{"GET", "POST"}, recoveryCodeMethods, "Recovery code routes should be registered")
+	assert.ElementsMatch(t, []string{"GET", "PUT"}, unlockMethods, "Unlock secret routes should be registered")
+	assert.ElementsMatch(t, []string{"GET", "PUT"}, duressMethods, "Duress password routes should be registered")
 }
 
 func TestRegisterRoutes_WithDifferentBasePaths(t *testing.T) {
@@ -111,17 +171,17 @@ func TestRegisterRoutes_WithDifferentBasePaths(t *testing.T) {
 		{
 			name:     "root path",
 			basePath: "",
-			expected: []string{"/auth/register", "/auth/login"},
+			expected: []string{"/auth/register", "/auth/login", "/auth/login/verify"},
 		},
 		{
 			name:     "api v1 path",
 			basePath: "/api/v1",
-			expected: []string{"/api/v1/auth/register", "/api/v1/auth/login"},
+			expected: []string{"/api/v1/auth/register", "/api/v1/auth/login", "/api/v1/auth/login/verify"},
 		},
 		{
 			name:     "nested path",
 			basePath: "/app/api",
-			expected: []string{"/app/api/auth/register", "/app/api/auth/login"},
+			expected: []string{"/app/api/auth/register", "/app/api/auth/login", "/app/api/auth/login/verify"},
 		},
 	}
 
@@ -138,11 +198,11 @@ func TestRegisterRoutes_WithDifferentBasePaths(t *testing.T) {
 			handler := NewHandler(mockService)
 
 			// Execute
-			RegisterRoutes(rootGroup, handler)
+			RegisterRoutes(rootGroup, handler, Guards{})
 
 			// Validate
 			routes := router.Routes()
-			require.Len(t, routes, 2)
+			require.Len(t, routes, 14)
 
 			actualPaths := make([]string, len(routes))
 			for i, route := range routes {
@@ -168,11 +228,11 @@ func TestRegisterRoutes_HandlerMethods(t *testing.T) {
 	handler := NewHandler(mockService)
 
 	// Execute
-	RegisterRoutes(rootGroup, handler)
+	RegisterRoutes(rootGroup, handler, Guards{})
 
 	// Validate that handler methods are properly set
 	routes := router.Routes()
-	require.Len(t, routes, 2)
+	require.Len(t, routes, 14)
 
 	for _, route := range routes {
 		// Verify that routes have handlers set
@@ -184,8 +244,35 @@ func TestRegisterRoutes_HandlerMethods(t *testing.T) {
 			assert.Equal(t, "POST", route.Method)
 		case "/auth/login":
 			assert.Equal(t, "POST", route.Method)
+		case "/auth/login/verify", "/auth/login/claim", "/auth/recover", "/auth/login-change/confirm",
+			"/auth/change-password":
+			assert.Equal(t, "POST", route.Method)
+		case "/auth/login/approve":
+			assert.Equal(t, "GET", route.Method)
+		case "/auth/recovery-codes":
+			assert.Contains(t, []string{"GET", "POST"}, route.Method)
+		case "/auth/unlock-secret", "/auth/duress":
+			assert.Contains(t, []string{"GET", "PUT"}, route.Method)
 		default:
 			t.Errorf("Unexpected route path: %s", route.Path)
 		}
 	}
 }
+
+func TestRegisterAccountRoutes(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	RegisterAccountRoutes(router.Group("/api/account"), NewHandler(&mockAuthService{}))
+
+	methodPaths := make(map[string]bool)
+	for _, route := range router.Routes() {
+		methodPaths[route.Method+" "+route.Path] = true
+	}
+	assert.Len(t, methodPaths, 3)
+	assert.True(t, methodPaths["GET /api/account/logins/pending"])
+	assert.True(t, methodPaths["POST /api/account/logins/:id/approve"])
+	assert.True(t, methodPaths["POST /api/account/login-change"])
+}
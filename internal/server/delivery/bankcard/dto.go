@@ -1,6 +1,7 @@
 package bankcard
 
 import (
+	"slices"
 	"time"
 
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/bankcard"
@@ -25,6 +26,42 @@ type BankCard struct {
 	Description string `json:"description,omitempty"  example:"Main credit card"`
 	// ID contains the unique identifier for this bank card record.
 	ID uuid.UUID `json:"id,omitempty"           example:"123e4567-e89b-12d3-a456-426614174000"`
+	// Archived reports whether the bank card is archived and hidden from lists by default.
+	Archived bool `json:"archived,omitempty"     example:"false"`
+}
+
+// fieldNames lists the bank card fields clients may select with the fields query parameter.
+var fieldNames = []string{
+	"id", "card_number", "card_holder", "expiry_month", "expiry_year", "cvv", "description", "updated_at",
+}
+
+// keepFields clears the fields of the bank card not named in fields, always keeping the ID.
+// An empty field list keeps every field.
+func (b *BankCard) keepFields(fields []string) {
+	if b == nil || len(fields) == 0 {
+		return
+	}
+	if !slices.Contains(fields, "card_number") {
+		b.CardNumber = ""
+	}
+	if !slices.Contains(fields, "card_holder") {
+		b.CardHolder = ""
+	}
+	if !slices.Contains(fields, "expiry_month") {
+		b.ExpiryMonth = ""
+	}
+	if !slices.Contains(fields, "expiry_year") {
+		b.ExpiryYear = ""
+	}
+	if !slices.Contains(fields, "cvv") {
+		b.CVV = ""
+	}
+	if !slices.Contains(fields, "description") {
+		b.Description = ""
+	}
+	if !slices.Contains(fields, "updated_at") {
+		b.UpdatedAt = time.Time{}
+	}
 }
 
 // ToApp converts this DTO to an application layer BankCard entity with the specified user ID.
@@ -71,6 +108,7 @@ func NewBankCardFromApp(bc *bankcard.BankCard) *BankCard {
 		CVV:         bc.CVV,
 		Description: bc.Description,
 		UpdatedAt:   bc.UpdatedAt,
+		Archived:    bc.Archived,
 	}
 }
 
@@ -108,6 +146,12 @@ type PullRequest struct {
 	ID string `uri:"id" binding:"required" example:"123e4567-e89b-12d3-a456-426614174000"`
 }
 
+// AsOfRequest represents the request addressing a bank card state at a past moment.
+type AsOfRequest struct {
+	// Timestamp contains the RFC 3339 moment whose bank card version is addressed (required).
+	Timestamp time.Time `form:"timestamp" binding:"required" example:"2023-12-01T10:00:00Z"`
+}
+
 // PushResponse represents the response after creating or updating a bank card.
 type PushResponse struct {
 	// ID contains the UUID of the created or updated bank card.
@@ -125,3 +169,9 @@ type ListResponse struct {
 	// BankCards contains the list of all bank cards belonging to the user.
 	BankCards []*BankCard `json:"bankcards"`
 }
+
+// RecoverResponse represents the response containing the recovered bank card.
+type RecoverResponse struct {
+	// BankCard contains the bank card data saved as the latest version.
+	BankCard *BankCard `json:"bankcard"`
+}
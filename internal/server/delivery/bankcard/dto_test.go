@@ -436,6 +436,55 @@ func TestNewBankCardsFromApp(t *testing.T) {
 	}
 }
 
+func TestBankCard_keepFields(t *testing.T) {
+	t.Parallel()
+
+	id := uuid.New()
+	updatedAt := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	full := BankCard{
+		ID:          id,
+		CardNumber:  "4242424242424242",
+		CardHolder:  "John Doe",
+		ExpiryMonth: "12",
+		ExpiryYear:  "2030",
+		CVV:         "123",
+		Description: "Main card",
+		UpdatedAt:   updatedAt,
+	}
+
+	tests := []struct {
+		name   string
+		fields []string
+		want   BankCard
+	}{
+		{
+			name:   "all fields",
+			fields: nil,
+			want:   full,
+		},
+		{
+			name:   "metadata only",
+			fields: []string{"card_holder", "updated_at"},
+			want:   BankCard{ID: id, CardHolder: "John Doe", UpdatedAt: updatedAt},
+		},
+		{
+			name:   "id only",
+			fields: []string{"id"},
+			want:   BankCard{ID: id},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := full
+			got.keepFields(tt.fields)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
 func TestBankCard_JSONSerialization(t *testing.T) {
 	t.Parallel()
 
@@ -5,6 +5,8 @@ import (
 
 	app "github.com/gdyunin/aegis-vault-keeper/internal/server/application/bankcard"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/errutil"
+	itempolicydel "github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/itempolicy"
+	plandel "github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/plan"
 	"github.com/gin-gonic/gin"
 )
 
@@ -23,6 +25,17 @@ var BankCardErrRegistry = errutil.Registry{
 		},
 	},
 
+	{
+		ErrorIn: app.ErrBankCardIntegrityViolation,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusInternalServerError,
+			PublicMsg:  "Stored bank card data failed integrity verification",
+			LogIt:      true,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassTech,
+		},
+	},
+
 	{
 		ErrorIn: app.ErrBankCardAccessDenied,
 		HandlePolicy: errutil.Policy{
@@ -127,8 +140,15 @@ var BankCardErrRegistry = errutil.Registry{
 	},
 }
 
+// handledErrRegistry aggregates the bank card, plan enforcement and item policy enforcement error registries.
+var handledErrRegistry = errutil.Merge(
+	BankCardErrRegistry,
+	plandel.PlanErrRegistry,
+	itempolicydel.ItemPolicyErrRegistry,
+)
+
 // handleError processes bank card application errors using the registry.
 // Returns HTTP status code and error messages for response.
 func handleError(err error, c *gin.Context) (int, []string) {
-	return errutil.HandleWithRegistry(BankCardErrRegistry, err, c)
+	return errutil.HandleWithRegistry(handledErrRegistry, err, c)
 }
@@ -145,6 +145,7 @@ func TestBankCardErrRegistry_AllErrorsCovered(t *testing.T) {
 	// List of all bankcard errors that should be in the registry
 	expectedErrors := []error{
 		bankcard.ErrBankCardTechError,
+		bankcard.ErrBankCardIntegrityViolation,
 		bankcard.ErrBankCardNotFound,
 		bankcard.ErrBankCardAccessDenied,
 		bankcard.ErrBankCardInvalidCardNumber,
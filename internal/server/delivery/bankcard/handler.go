@@ -3,8 +3,11 @@ package bankcard
 import (
 	"context"
 	"net/http"
+	"slices"
 
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/bankcard"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/consts"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/jsonenc"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/response"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/util"
 	"github.com/gin-gonic/gin"
@@ -19,6 +22,8 @@ type Service interface {
 	List(context.Context, bankcard.ListParams) ([]*bankcard.BankCard, error)
 	// Push creates or updates a bank card for the authenticated user.
 	Push(context.Context, *bankcard.PushParams) (uuid.UUID, error)
+	// Recover restores a bank card of the authenticated user to the version current at a given moment.
+	Recover(context.Context, bankcard.RecoverParams) (*bankcard.BankCard, error)
 }
 
 // Handler handles HTTP requests for bank card endpoints.
@@ -40,8 +45,9 @@ func NewHandler(s Service) *Handler {
 // @Produce      json
 // @Security     BearerAuth
 // @Param        id path string true "Bank card ID" format(uuid)
+// @Param        fields query string false "Comma-separated fields to return; the ID is always returned"
 // @Success      200 {object} PullResponse "Bank card retrieved successfully"
-// @Failure      400 {object} response.Error "Bad request - invalid ID format"
+// @Failure      400 {object} response.Error "Bad request - invalid ID format or unknown field"
 // @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
 // @Failure      404 {object} response.Error "Not found - bank card not found"
 // @Failure      500 {object} response.Error "Internal server error"
@@ -70,7 +76,14 @@ func (h *Handler) Pull(c *gin.Context) {
 		return
 	}
 
+	fields, err := extractor.Fields(fieldNames)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
 	serviceParams := bankcard.PullParams{
+		Fields: fields,
 		ID:     pullingID,
 		UserID: userID,
 	}
@@ -87,6 +100,7 @@ func (h *Handler) Pull(c *gin.Context) {
 	resp := PullResponse{
 		BankCard: NewBankCardFromApp(bc),
 	}
+	resp.BankCard.keepFields(fields)
 
 	c.JSON(http.StatusOK, resp)
 }
@@ -98,8 +112,13 @@ func (h *Handler) Pull(c *gin.Context) {
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
+// @Param        fields query string false "Comma-separated fields to return; the ID is always returned"
+// @Param        include query string false "Comma-separated item groups to add; archived lists archived items too"
+// @Param        sort query string false "List order; manual lists pinned and arranged items first" Enums(manual)
+// @Param        folder query string false "Folder whose manual order sorts the list; the root when omitted"
 // @Success      200 {object} ListResponse "Bank cards retrieved successfully"
 // @Success      204 "No bank cards found"
+// @Failure      400 {object} response.Error "Bad request - unknown field, include or sort"
 // @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
 // @Failure      500 {object} response.Error "Internal server error"
 // @Router       /items/bankcards [get]
@@ -113,8 +132,30 @@ func (h *Handler) List(c *gin.Context) {
 		return
 	}
 
+	fields, err := extractor.Fields(fieldNames)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	include, err := extractor.Include([]string{consts.IncludeArchived})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	sort, err := extractor.Sort([]string{consts.SortManual})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
 	serviceParams := bankcard.ListParams{
-		UserID: userID,
+		Fields:          fields,
+		UserID:          userID,
+		OrderFolder:     extractor.Folder(),
+		ExcludeArchived: !slices.Contains(include, consts.IncludeArchived),
+		ManualOrder:     sort == consts.SortManual,
 	}
 
 	bcs, err := h.s.List(c, serviceParams)
@@ -134,8 +175,11 @@ func (h *Handler) List(c *gin.Context) {
 	resp := ListResponse{
 		BankCards: NewBankCardsFromApp(bcs),
 	}
+	for _, bc := range resp.BankCards {
+		bc.keepFields(fields)
+	}
 
-	c.JSON(http.StatusOK, resp)
+	c.Render(http.StatusOK, jsonenc.JSON{Value: &resp})
 }
 
 // Push creates a new bank card or updates an existing one.
@@ -210,3 +254,107 @@ func (h *Handler) Push(c *gin.Context) {
 
 	c.JSON(http.StatusCreated, resp)
 }
+
+// PullAsOf retrieves a specific bank card as it was at the requested moment.
+// @Summary      Get bank card at a past moment
+// @Description  Retrieves the version of a bank card that was current at the given moment
+// @Tags         BankCards
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Bank card ID" format(uuid)
+// @Param        timestamp query string true "Moment to view (RFC 3339)" format(date-time)
+// @Success      200 {object} PullResponse "Bank card version retrieved successfully"
+// @Failure      400 {object} response.Error "Bad request - invalid ID or timestamp format"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
+// @Failure      404 {object} response.Error "Not found - no bank card version at the given moment"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /items/bankcards/{id}/as-of [get]
+// .
+func (h *Handler) PullAsOf(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	userID, err := extractor.UserID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+
+	cardID, req, ok := bindAsOfRequest(c, extractor)
+	if !ok {
+		return
+	}
+
+	bc, err := h.s.Pull(c, bankcard.PullParams{AsOf: req.Timestamp, ID: cardID, UserID: userID})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, PullResponse{BankCard: NewBankCardFromApp(bc)})
+}
+
+// Recover restores a bank card to the version that was current at the requested moment.
+// @Summary      Recover bank card version
+// @Description  Saves the bank card version current at the given moment as the latest version
+// @Tags         BankCards
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Bank card ID" format(uuid)
+// @Param        timestamp query string true "Moment to recover to (RFC 3339)" format(date-time)
+// @Success      200 {object} RecoverResponse "Bank card recovered successfully"
+// @Failure      400 {object} response.Error "Bad request - invalid ID or timestamp format"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
+// @Failure      404 {object} response.Error "Not found - no bank card version at the given moment"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /items/bankcards/{id}/recover [post]
+// .
+func (h *Handler) Recover(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	userID, err := extractor.UserID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+
+	cardID, req, ok := bindAsOfRequest(c, extractor)
+	if !ok {
+		return
+	}
+
+	bc, err := h.s.Recover(c, bankcard.RecoverParams{AsOf: req.Timestamp, ID: cardID, UserID: userID})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, RecoverResponse{BankCard: NewBankCardFromApp(bc)})
+}
+
+// bindAsOfRequest extracts the bank card ID from the path and the moment from the query.
+// On failure it writes a bad request response and reports false.
+func bindAsOfRequest(c *gin.Context, extractor *util.CtxExtractor) (uuid.UUID, AsOfRequest, bool) {
+	// uriReq holds the deserialized URI parameters identifying the bank card.
+	var uriReq PullRequest
+	// req holds the deserialized query parameters selecting the moment.
+	var req AsOfRequest
+	if extractor.BindURI(&uriReq) != nil || extractor.BindQuery(&req) != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return uuid.Nil, req, false
+	}
+
+	id, err := uuid.Parse(uriReq.ID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return uuid.Nil, req, false
+	}
+	return id, req, true
+}
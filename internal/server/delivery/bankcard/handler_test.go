@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/bankcard"
 	"github.com/gin-gonic/gin"
@@ -22,9 +23,10 @@ var (
 
 // mockBankCardService is a mock implementation of the Service interface for testing.
 type mockBankCardService struct {
-	pullFunc func(context.Context, bankcard.PullParams) (*bankcard.BankCard, error)
-	listFunc func(context.Context, bankcard.ListParams) ([]*bankcard.BankCard, error)
-	pushFunc func(context.Context, *bankcard.PushParams) (uuid.UUID, error)
+	pullFunc    func(context.Context, bankcard.PullParams) (*bankcard.BankCard, error)
+	listFunc    func(context.Context, bankcard.ListParams) ([]*bankcard.BankCard, error)
+	pushFunc    func(context.Context, *bankcard.PushParams) (uuid.UUID, error)
+	recoverFunc func(context.Context, bankcard.RecoverParams) (*bankcard.BankCard, error)
 }
 
 func (m *mockBankCardService) Pull(
@@ -54,6 +56,16 @@ func (m *mockBankCardService) Push(ctx context.Context, params *bankcard.PushPar
 	return uuid.Nil, nil
 }
 
+func (m *mockBankCardService) Recover(
+	ctx context.Context,
+	params bankcard.RecoverParams,
+) (*bankcard.BankCard, error) {
+	if m.recoverFunc != nil {
+		return m.recoverFunc(ctx, params)
+	}
+	return nil, errMockNotImplemented
+}
+
 func TestNewHandler(t *testing.T) {
 	t.Parallel()
 
@@ -266,6 +278,7 @@ func TestHandler_List(t *testing.T) {
 					},
 				}
 				m.listFunc = func(ctx context.Context, params bankcard.ListParams) ([]*bankcard.BankCard, error) {
+					assert.True(t, params.ExcludeArchived)
 					return testCards, nil
 				}
 			},
@@ -660,3 +673,112 @@ func TestHandler_Push(t *testing.T) {
 		})
 	}
 }
+
+func TestHandler_AsOfEndpoints(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+
+	userID := uuid.New()
+	itemID := uuid.New()
+	asOf := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	validQuery := "?timestamp=2024-01-02T03:04:05Z"
+
+	tests := []struct {
+		serviceErr     error
+		name           string
+		urlParam       string
+		query          string
+		setUser        bool
+		expectedStatus int
+	}{
+		{
+			name:           "success",
+			setUser:        true,
+			urlParam:       itemID.String(),
+			query:          validQuery,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "missing user ID",
+			urlParam:       itemID.String(),
+			query:          validQuery,
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:           "invalid UUID in path",
+			setUser:        true,
+			urlParam:       "invalid-uuid",
+			query:          validQuery,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "missing timestamp",
+			setUser:        true,
+			urlParam:       itemID.String(),
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid timestamp",
+			setUser:        true,
+			urlParam:       itemID.String(),
+			query:          "?timestamp=yesterday",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "version not found",
+			setUser:        true,
+			urlParam:       itemID.String(),
+			query:          validQuery,
+			serviceErr:     bankcard.ErrBankCardNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			checkParams := func(gotAsOf time.Time, gotID, gotUserID uuid.UUID) {
+				assert.True(t, asOf.Equal(gotAsOf))
+				assert.Equal(t, itemID, gotID)
+				assert.Equal(t, userID, gotUserID)
+			}
+			mockSvc := &mockBankCardService{
+				pullFunc: func(_ context.Context, params bankcard.PullParams) (*bankcard.BankCard, error) {
+					checkParams(params.AsOf, params.ID, params.UserID)
+					if tt.serviceErr != nil {
+						return nil, tt.serviceErr
+					}
+					return &bankcard.BankCard{ID: params.ID, UserID: params.UserID}, nil
+				},
+				recoverFunc: func(_ context.Context, params bankcard.RecoverParams) (*bankcard.BankCard, error) {
+					checkParams(params.AsOf, params.ID, params.UserID)
+					if tt.serviceErr != nil {
+						return nil, tt.serviceErr
+					}
+					return &bankcard.BankCard{ID: params.ID, UserID: params.UserID, UpdatedAt: time.Now()}, nil
+				},
+			}
+			handler := NewHandler(mockSvc)
+
+			endpoints := map[string]gin.HandlerFunc{
+				http.MethodGet:  handler.PullAsOf,
+				http.MethodPost: handler.Recover,
+			}
+			for method, handle := range endpoints {
+				w := httptest.NewRecorder()
+				c, _ := gin.CreateTestContext(w)
+				c.Request = httptest.NewRequest(method, "/"+tt.urlParam+"/as-of"+tt.query, nil)
+				c.Params = gin.Params{{Key: "id", Value: tt.urlParam}}
+				if tt.setUser {
+					c.Set("userID", userID)
+				}
+
+				handle(c)
+
+				assert.Equal(t, tt.expectedStatus, w.Code, method)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package bankcard
+
+import "github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/jsonenc"
+
+// AppendJSON appends the JSON encoding of the bank card. Like its omitempty struct tags, it omits empty
+// strings and false flags but always writes the modification time and the ID.
+func (b *BankCard) AppendJSON(dst []byte) []byte {
+	if b == nil {
+		return jsonenc.Null(dst)
+	}
+	dst = append(dst, '{')
+	dst = jsonenc.Time(jsonenc.Key(dst, "updated_at"), b.UpdatedAt)
+	if b.CardNumber != "" {
+		dst = jsonenc.String(jsonenc.Key(dst, "card_number"), b.CardNumber)
+	}
+	if b.CardHolder != "" {
+		dst = jsonenc.String(jsonenc.Key(dst, "card_holder"), b.CardHolder)
+	}
+	if b.ExpiryMonth != "" {
+		dst = jsonenc.String(jsonenc.Key(dst, "expiry_month"), b.ExpiryMonth)
+	}
+	if b.ExpiryYear != "" {
+		dst = jsonenc.String(jsonenc.Key(dst, "expiry_year"), b.ExpiryYear)
+	}
+	if b.CVV != "" {
+		dst = jsonenc.String(jsonenc.Key(dst, "cvv"), b.CVV)
+	}
+	if b.Description != "" {
+		dst = jsonenc.String(jsonenc.Key(dst, "description"), b.Description)
+	}
+	dst = jsonenc.UUID(jsonenc.Key(dst, "id"), b.ID)
+	if b.Archived {
+		dst = jsonenc.Bool(jsonenc.Key(dst, "archived"), b.Archived)
+	}
+	return append(dst, '}')
+}
+
+// AppendJSON appends the JSON encoding of the list response.
+func (r *ListResponse) AppendJSON(dst []byte) []byte {
+	if r == nil {
+		return jsonenc.Null(dst)
+	}
+	dst = append(dst, '{')
+	dst = jsonenc.Array(jsonenc.Key(dst, "bankcards"), r.BankCards)
+	return append(dst, '}')
+}
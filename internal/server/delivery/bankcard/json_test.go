@@ -0,0 +1,49 @@
+package bankcard
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListResponse_AppendJSON(t *testing.T) {
+	t.Parallel()
+
+	full := &BankCard{
+		ID:          uuid.New(),
+		CardNumber:  "4242424242424242",
+		CardHolder:  "JOHN O'DOE <JR>",
+		ExpiryMonth: "12",
+		ExpiryYear:  "2030",
+		CVV:         "123",
+		Description: "Main & only card",
+		Archived:    true,
+		UpdatedAt:   time.Date(2025, 5, 6, 7, 8, 9, 120000000, time.UTC),
+	}
+
+	tests := []struct {
+		resp *ListResponse
+		name string
+	}{
+		{name: "full", resp: &ListResponse{BankCards: []*BankCard{full}}},
+		{name: "selected fields", resp: &ListResponse{BankCards: []*BankCard{{ID: full.ID, CVV: full.CVV}}}},
+		{name: "zero card", resp: &ListResponse{BankCards: []*BankCard{{}, nil}}},
+		{name: "empty", resp: &ListResponse{BankCards: []*BankCard{}}},
+		{name: "nil list", resp: &ListResponse{}},
+		{name: "nil", resp: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			want, err := json.Marshal(tt.resp)
+			require.NoError(t, err)
+			assert.Equal(t, string(want), string(tt.resp.AppendJSON(nil)))
+		})
+	}
+}
@@ -1,15 +1,21 @@
 package bankcard
 
-import "github.com/gin-gonic/gin"
+import (
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/util"
+	"github.com/gin-gonic/gin"
+)
 
 // RegisterRoutes configures bank card endpoints in the router group.
 // Sets up CRUD operations: POST/GET for collections, GET/PUT for individual items.
-func RegisterRoutes(r *gin.RouterGroup, h *Handler) {
+// The reveal middleware runs on the routes returning the content of a single bank card.
+func RegisterRoutes(r *gin.RouterGroup, h *Handler, reveal ...gin.HandlerFunc) {
 	bankcardsGroup := r.Group("/bankcards")
 	bankcardsGroup.POST("", h.Push)
 	bankcardsGroup.GET("", h.List)
 
 	bankcardsIDGroup := bankcardsGroup.Group("/:id")
-	bankcardsIDGroup.GET("", h.Pull)
+	bankcardsIDGroup.GET("", util.Chain(reveal, h.Pull)...)
 	bankcardsIDGroup.PUT("", h.Push)
+	bankcardsIDGroup.GET("/as-of", util.Chain(reveal, h.PullAsOf)...)
+	bankcardsIDGroup.POST("/recover", h.Recover)
 }
@@ -29,11 +29,13 @@ func TestRegisterRoutes(t *testing.T) {
 				"GET /bankcards/:id",
 				"POST /bankcards",
 				"PUT /bankcards/:id",
+				"GET /bankcards/:id/as-of",
+				"POST /bankcards/:id/recover",
 			},
 			validateFunc: func(t *testing.T, router *gin.Engine) {
 				t.Helper()
 				routes := router.Routes()
-				assert.Len(t, routes, 4)
+				assert.Len(t, routes, 6)
 
 				// Check that all routes are registered
 				methodPaths := make(map[string]string)
@@ -45,6 +47,8 @@ func TestRegisterRoutes(t *testing.T) {
 				assert.Contains(t, methodPaths, "GET /bankcards/:id")
 				assert.Contains(t, methodPaths, "POST /bankcards")
 				assert.Contains(t, methodPaths, "PUT /bankcards/:id")
+				assert.Contains(t, methodPaths, "GET /bankcards/:id/as-of")
+				assert.Contains(t, methodPaths, "POST /bankcards/:id/recover")
 			},
 		},
 	}
@@ -86,10 +90,10 @@ func TestRegisterRoutes_Integration(t *testing.T) {
 
 	// Validate routes are accessible
 	routes := router.Routes()
-	require.Len(t, routes, 4)
+	require.Len(t, routes, 6)
 
 	// Check specific route paths
-	var listFound, pullFound, postFound, putFound bool
+	var listFound, pullFound, postFound, putFound, asOfFound, recoverFound bool
 	for _, route := range routes {
 		switch {
 		case route.Method == http.MethodGet && route.Path == "/api/bankcards":
@@ -100,6 +104,10 @@ func TestRegisterRoutes_Integration(t *testing.T) {
 			postFound = true
 		case route.Method == http.MethodPut && route.Path == "/api/bankcards/:id":
 			putFound = true
+		case route.Method == http.MethodGet && route.Path == "/api/bankcards/:id/as-of":
+			asOfFound = true
+		case route.Method == http.MethodPost && route.Path == "/api/bankcards/:id/recover":
+			recoverFound = true
 		}
 	}
 
@@ -107,6 +115,8 @@ func TestRegisterRoutes_Integration(t *testing.T) {
 	assert.True(t, pullFound, "Pull route should be registered")
 	assert.True(t, postFound, "Post route should be registered")
 	assert.True(t, putFound, "Put route should be registered")
+	assert.True(t, asOfFound, "As-of route should be registered")
+	assert.True(t, recoverFound, "Recover route should be registered")
 }
 
 func TestRegisterRoutes_WithDifferentBasePaths(t *testing.T) {
@@ -151,7 +161,7 @@ func TestRegisterRoutes_WithDifferentBasePaths(t *testing.T) {
 
 			// Validate
 			routes := router.Routes()
-			require.Len(t, routes, 4)
+			require.Len(t, routes, 6)
 
 			actualPaths := make([]string, len(routes))
 			for i, route := range routes {
@@ -181,7 +191,7 @@ func TestRegisterRoutes_HandlerMethods(t *testing.T) {
 
 	// Validate that handler methods are properly set
 	routes := router.Routes()
-	require.Len(t, routes, 4)
+	require.Len(t, routes, 6)
 
 	methodCounts := make(map[string]int)
 	for _, route := range routes {
@@ -201,13 +211,17 @@ func TestRegisterRoutes_HandlerMethods(t *testing.T) {
 			// Create endpoint
 		case route.Method == http.MethodPut && route.Path == "/bankcards/:id":
 			// Update endpoint
+		case route.Method == http.MethodGet && route.Path == "/bankcards/:id/as-of":
+			// As-of endpoint
+		case route.Method == http.MethodPost && route.Path == "/bankcards/:id/recover":
+			// Recover endpoint
 		default:
 			t.Errorf("Unexpected route: %s %s", route.Method, route.Path)
 		}
 	}
 
 	// Verify method distribution
-	assert.Equal(t, 2, methodCounts["GET"], "Should have 2 GET routes")
-	assert.Equal(t, 1, methodCounts["POST"], "Should have 1 POST route")
+	assert.Equal(t, 3, methodCounts["GET"], "Should have 3 GET routes")
+	assert.Equal(t, 2, methodCounts["POST"], "Should have 2 POST routes")
 	assert.Equal(t, 1, methodCounts["PUT"], "Should have 1 PUT route")
 }
@@ -0,0 +1,5 @@
+// Package billing provides HTTP handlers for billing integration endpoints in the AegisVaultKeeper server.
+//
+// This package receives the subscription webhooks of Stripe. The endpoint is public: requests are
+// authenticated by the Stripe signature of their body, so the body is read raw rather than bound.
+package billing
@@ -0,0 +1,22 @@
+package billing
+
+import "github.com/gdyunin/aegis-vault-keeper/internal/server/application/billing"
+
+// WebhookResponse represents the acknowledgement of a received webhook event.
+type WebhookResponse struct {
+	// Reason explains why the event did not change a plan; omitted when it did.
+	Reason string `json:"reason,omitempty" example:"unhandled event type"`
+	// Received reports that the event was received and need not be delivered again.
+	Received bool `json:"received" example:"true"`
+	// Applied reports whether the event changed the subscription of a user.
+	Applied bool `json:"applied" example:"true"`
+}
+
+// NewWebhookResponseFromApp converts an application layer webhook result to delivery DTO.
+func NewWebhookResponseFromApp(r *billing.WebhookResult) WebhookResponse {
+	return WebhookResponse{
+		Reason:   r.Reason,
+		Received: true,
+		Applied:  r.Applied,
+	}
+}
@@ -0,0 +1,58 @@
+package billing
+
+import (
+	"net/http"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/billing"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/errutil"
+	"github.com/gin-gonic/gin"
+)
+
+// BillingErrRegistry defines error handling policies for billing operations.
+var BillingErrRegistry = errutil.Registry{
+	{
+		ErrorIn: billing.ErrBillingDisabled,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusNotFound,
+			PublicMsg:  http.StatusText(http.StatusNotFound),
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+	{
+		ErrorIn: billing.ErrBillingSignatureInvalid,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusBadRequest,
+			PublicMsg:  "Webhook signature is invalid",
+			LogIt:      true,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassAuth,
+		},
+	},
+	{
+		ErrorIn: billing.ErrBillingEventInvalid,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusBadRequest,
+			PublicMsg:  "Webhook event is invalid",
+			LogIt:      true,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+	{
+		ErrorIn: billing.ErrBillingTechError,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusInternalServerError,
+			PublicMsg:  http.StatusText(http.StatusInternalServerError),
+			LogIt:      true,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassTech,
+		},
+	},
+}
+
+// handleError processes errors using the billing error registry.
+func handleError(err error, c *gin.Context) (int, []string) {
+	return errutil.HandleWithRegistry(BillingErrRegistry, err, c)
+}
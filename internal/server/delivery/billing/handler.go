@@ -0,0 +1,81 @@
+package billing
+
+import (
+	"context"
+	"io"
+	"net/http"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/billing"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/response"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// headerStripeSignature names the header carrying the Stripe signature of webhook bodies.
+	headerStripeSignature = "Stripe-Signature"
+	// maxWebhookSize limits the size of webhook bodies; Stripe events are far smaller.
+	maxWebhookSize = 256 << 10
+)
+
+// Service defines the billing application service interface.
+type Service interface {
+	// HandleStripeWebhook verifies and applies a Stripe webhook event.
+	HandleStripeWebhook(ctx context.Context, params billing.StripeWebhookParams) (*billing.WebhookResult, error)
+}
+
+// Handler handles HTTP requests for billing endpoints.
+type Handler struct {
+	// s is the billing service used to apply webhook events.
+	s Service
+}
+
+// NewHandler creates a new billing handler with the provided service.
+func NewHandler(s Service) *Handler {
+	return &Handler{s: s}
+}
+
+// StripeWebhook receives a Stripe subscription webhook.
+// @Summary      Receive Stripe webhook
+// @Description  Applies customer.subscription.created, updated and deleted events to the plan of the user named
+// @Description  by the user_id metadata of the subscription. Requests are authenticated by the Stripe-Signature
+// @Description  header. Events are applied once and in order; redelivered, stale and unrelated events are
+// @Description  acknowledged with a reason. Not found unless the Stripe integration is configured
+// .
+// @Tags         Billing
+// @Accept       json
+// @Produce      json
+// @Param        Stripe-Signature header string true "Stripe webhook signature"
+// @Success      200 {object} WebhookResponse "Event received"
+// @Failure      400 {object} response.Error "Bad request - invalid signature or event"
+// @Failure      404 {object} response.Error "Stripe integration not configured"
+// @Failure      413 {object} response.Error "Request body too large"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /billing/stripe/webhook [post]
+// .
+func (h *Handler) StripeWebhook(c *gin.Context) {
+	payload, err := io.ReadAll(io.LimitReader(c.Request.Body, maxWebhookSize+1))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+	if len(payload) > maxWebhookSize {
+		c.JSON(http.StatusRequestEntityTooLarge, response.Error{
+			Messages: []string{http.StatusText(http.StatusRequestEntityTooLarge)},
+		})
+		return
+	}
+
+	result, err := h.s.HandleStripeWebhook(c, billing.StripeWebhookParams{
+		Signature: c.GetHeader(headerStripeSignature),
+		Payload:   payload,
+	})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, NewWebhookResponseFromApp(result))
+}
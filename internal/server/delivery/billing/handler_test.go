@@ -0,0 +1,126 @@
+package billing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/billing"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockBillingService implements Service for testing.
+type mockBillingService struct {
+	handleFunc func(ctx context.Context, params billing.StripeWebhookParams) (*billing.WebhookResult, error)
+}
+
+func (m *mockBillingService) HandleStripeWebhook(
+	ctx context.Context,
+	params billing.StripeWebhookParams,
+) (*billing.WebhookResult, error) {
+	if m.handleFunc != nil {
+		return m.handleFunc(ctx, params)
+	}
+	return &billing.WebhookResult{}, nil
+}
+
+func TestHandler_StripeWebhook(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		mockService    *mockBillingService
+		want           *WebhookResponse
+		name           string
+		body           string
+		expectedStatus int
+	}{
+		{
+			name: "applied event",
+			body: `{"id": "evt_1"}`,
+			mockService: &mockBillingService{
+				handleFunc: func(_ context.Context, params billing.StripeWebhookParams) (*billing.WebhookResult, error) {
+					assert.Equal(t, "t=1,v1=abc", params.Signature)
+					assert.JSONEq(t, `{"id": "evt_1"}`, string(params.Payload))
+					return &billing.WebhookResult{EventID: "evt_1", Applied: true}, nil
+				},
+			},
+			want:           &WebhookResponse{Received: true, Applied: true},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "ignored event",
+			body: `{"id": "evt_2"}`,
+			mockService: &mockBillingService{
+				handleFunc: func(context.Context, billing.StripeWebhookParams) (*billing.WebhookResult, error) {
+					return &billing.WebhookResult{EventID: "evt_2", Reason: billing.ReasonUnhandledType}, nil
+				},
+			},
+			want:           &WebhookResponse{Reason: billing.ReasonUnhandledType, Received: true},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "body too large",
+			body:           strings.Repeat("x", maxWebhookSize+1),
+			mockService:    &mockBillingService{},
+			expectedStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name: "invalid signature",
+			body: `{}`,
+			mockService: &mockBillingService{
+				handleFunc: func(context.Context, billing.StripeWebhookParams) (*billing.WebhookResult, error) {
+					return nil, billing.ErrBillingSignatureInvalid
+				},
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "billing disabled",
+			body: `{}`,
+			mockService: &mockBillingService{
+				handleFunc: func(context.Context, billing.StripeWebhookParams) (*billing.WebhookResult, error) {
+					return nil, billing.ErrBillingDisabled
+				},
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name: "service error",
+			body: `{}`,
+			mockService: &mockBillingService{
+				handleFunc: func(context.Context, billing.StripeWebhookParams) (*billing.WebhookResult, error) {
+					return nil, errors.Join(billing.ErrBillingTechError, errors.New("database unavailable"))
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/billing/stripe/webhook", strings.NewReader(tt.body))
+			c.Request.Header.Set(headerStripeSignature, "t=1,v1=abc")
+
+			NewHandler(tt.mockService).StripeWebhook(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.want != nil {
+				// got holds the decoded response body.
+				var got WebhookResponse
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+				assert.Equal(t, *tt.want, got)
+			}
+		})
+	}
+}
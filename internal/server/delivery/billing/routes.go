@@ -0,0 +1,8 @@
+package billing
+
+import "github.com/gin-gonic/gin"
+
+// RegisterRoutes registers billing routes with the provided router group.
+func RegisterRoutes(r *gin.RouterGroup, h *Handler) {
+	r.POST("/stripe/webhook", h.StripeWebhook)
+}
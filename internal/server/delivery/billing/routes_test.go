@@ -0,0 +1,22 @@
+package billing
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterRoutes(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	RegisterRoutes(router.Group("/billing"), NewHandler(&mockBillingService{}))
+
+	routes := router.Routes()
+	assert.Len(t, routes, 1)
+	assert.Equal(t, http.MethodPost, routes[0].Method)
+	assert.Equal(t, "/billing/stripe/webhook", routes[0].Path)
+}
@@ -0,0 +1,5 @@
+// Package botcheck provides HTTP handlers for the human verification challenge in the AegisVaultKeeper server.
+//
+// This package tells clients which CAPTCHA widget to render, or hands out a proof-of-work puzzle, so they
+// can send the solution with registrations and with logins after repeated failures.
+package botcheck
@@ -0,0 +1,35 @@
+package botcheck
+
+import (
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/botcheck"
+)
+
+// ChallengeResponse represents the challenge the client must solve.
+type ChallengeResponse struct {
+	// ExpiresAt indicates when solutions of the puzzle stop being accepted; omitted for CAPTCHA providers.
+	ExpiresAt time.Time `json:"expires_at,omitzero"  example:"2023-12-01T10:05:00Z"`
+	// Provider names the challenge provider: hcaptcha, turnstile or pow.
+	Provider string `json:"provider"             example:"pow"`
+	// SiteKey contains the public site key the CAPTCHA widget is rendered with; omitted for proof-of-work.
+	SiteKey string `json:"site_key,omitempty"   example:"10000000-ffff-ffff-ffff-000000000001"`
+	// Puzzle contains the proof-of-work puzzle; omitted for CAPTCHA providers.
+	Puzzle string `json:"puzzle,omitempty"     example:"q83vEjRWeJCrze8SNFZ4kAAAAABlaQ3AFA.mB1f..."`
+	// Difficulty is the number of leading zero bits the SHA-256 hash of "<puzzle>:<nonce>" must have.
+	Difficulty int `json:"difficulty,omitempty" example:"20"`
+	// LoginRequired reports whether logins from the client network currently require a solution.
+	LoginRequired bool `json:"login_required"       example:"false"`
+}
+
+// NewChallengeResponseFromApp converts an application layer challenge to delivery DTO.
+func NewChallengeResponseFromApp(c *botcheck.Challenge) ChallengeResponse {
+	return ChallengeResponse{
+		ExpiresAt:     c.ExpiresAt,
+		Provider:      c.Provider,
+		SiteKey:       c.SiteKey,
+		Puzzle:        c.Puzzle,
+		Difficulty:    c.Difficulty,
+		LoginRequired: c.LoginRequired,
+	}
+}
@@ -0,0 +1,38 @@
+package botcheck
+
+import (
+	"net/http"
+
+	app "github.com/gdyunin/aegis-vault-keeper/internal/server/application/botcheck"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/errutil"
+	"github.com/gin-gonic/gin"
+)
+
+// BotCheckErrRegistry defines error handling policies for human verification operations.
+var BotCheckErrRegistry = errutil.Registry{
+	{
+		ErrorIn: app.ErrChallengesDisabled,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusNotFound,
+			PublicMsg:  "Challenges are not enabled on this server",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassGeneric,
+		},
+	},
+	{
+		ErrorIn: app.ErrBotCheckTechError,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusInternalServerError,
+			PublicMsg:  http.StatusText(http.StatusInternalServerError),
+			LogIt:      true,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassTech,
+		},
+	},
+}
+
+// handleError processes human verification errors using the registry and returns appropriate HTTP response.
+func handleError(err error, c *gin.Context) (int, []string) {
+	return errutil.HandleWithRegistry(BotCheckErrRegistry, err, c)
+}
@@ -0,0 +1,55 @@
+package botcheck
+
+import (
+	"context"
+	"net/http"
+	"net/netip"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/botcheck"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/clientinfo"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/response"
+	"github.com/gin-gonic/gin"
+)
+
+// Service defines the human verification application service interface.
+type Service interface {
+	// Challenge returns the challenge the client must solve.
+	Challenge(ctx context.Context, ip netip.Addr) (*botcheck.Challenge, error)
+}
+
+// Handler handles HTTP requests for human verification endpoints.
+type Handler struct {
+	// s is the human verification service issuing challenges.
+	s Service
+}
+
+// NewHandler creates a new human verification handler with the provided service.
+func NewHandler(s Service) *Handler {
+	return &Handler{s: s}
+}
+
+// Challenge returns the challenge the client must solve before registering or logging in.
+// @Summary      Get a challenge
+// @Description  Returns the CAPTCHA widget to render or a fresh proof-of-work puzzle. The solution is sent in the
+// @Description  X-Challenge-Response header of registrations, and of logins once login_required is true:
+// @Description  the widget response for CAPTCHA providers, or "<puzzle>:<nonce>" for proof-of-work.
+// .
+// @Tags         Auth
+// @Produce      json
+// @Success      200 {object} ChallengeResponse "Challenge issued"
+// @Failure      404 {object} response.Error "Not found - challenges are not enabled"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /auth/challenge [get]
+// .
+func (h *Handler) Challenge(c *gin.Context) {
+	ip, _ := clientinfo.IP(c.Request.Context())
+
+	challenge, err := h.s.Challenge(c, ip)
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{Messages: msgs})
+		return
+	}
+
+	c.JSON(http.StatusOK, NewChallengeResponseFromApp(challenge))
+}
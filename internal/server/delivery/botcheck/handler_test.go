@@ -0,0 +1,108 @@
+package botcheck
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/botcheck"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockService implements Service interface for testing.
+type mockService struct {
+	ChallengeFunc func(ctx context.Context, ip netip.Addr) (*botcheck.Challenge, error)
+}
+
+func (m *mockService) Challenge(ctx context.Context, ip netip.Addr) (*botcheck.Challenge, error) {
+	return m.ChallengeFunc(ctx, ip)
+}
+
+func TestHandler_Challenge(t *testing.T) {
+	t.Parallel()
+
+	expiresAt := time.Date(2023, 12, 1, 10, 5, 0, 0, time.UTC)
+
+	tests := []struct {
+		challenge  *botcheck.Challenge
+		err        error
+		want       map[string]any
+		name       string
+		wantStatus int
+	}{
+		{
+			name: "success/captcha",
+			challenge: &botcheck.Challenge{
+				Provider: botcheck.ProviderTurnstile,
+				SiteKey:  "site-key",
+			},
+			wantStatus: http.StatusOK,
+			want: map[string]any{
+				"provider":       "turnstile",
+				"site_key":       "site-key",
+				"login_required": false,
+			},
+		},
+		{
+			name: "success/proof_of_work",
+			challenge: &botcheck.Challenge{
+				ExpiresAt:     expiresAt,
+				Provider:      botcheck.ProviderPoW,
+				Puzzle:        "puzzle",
+				Difficulty:    20,
+				LoginRequired: true,
+			},
+			wantStatus: http.StatusOK,
+			want: map[string]any{
+				"expires_at":     "2023-12-01T10:05:00Z",
+				"provider":       "pow",
+				"puzzle":         "puzzle",
+				"difficulty":     float64(20),
+				"login_required": true,
+			},
+		},
+		{
+			name:       "error/disabled",
+			err:        botcheck.ErrChallengesDisabled,
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "error/technical",
+			err:        errors.Join(botcheck.ErrBotCheckTechError, errors.New("entropy exhausted")),
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			h := NewHandler(&mockService{
+				ChallengeFunc: func(context.Context, netip.Addr) (*botcheck.Challenge, error) {
+					return tt.challenge, tt.err
+				},
+			})
+			RegisterRoutes(router.Group(""), h)
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/auth/challenge", nil))
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			if tt.want == nil {
+				return
+			}
+			var got map[string]any
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
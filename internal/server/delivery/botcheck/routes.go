@@ -0,0 +1,8 @@
+package botcheck
+
+import "github.com/gin-gonic/gin"
+
+// RegisterRoutes registers the challenge endpoint /auth/challenge on the provided router group.
+func RegisterRoutes(r *gin.RouterGroup, h *Handler) {
+	r.GET("/auth/challenge", h.Challenge)
+}
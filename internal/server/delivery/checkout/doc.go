@@ -0,0 +1,5 @@
+// Package checkout provides HTTP handlers for shared credential endpoints in the AegisVaultKeeper server.
+//
+// This package lets authenticated users share their credentials with a directory group, and lets group
+// members check a shared credential out to reveal it exclusively and check it back in.
+package checkout
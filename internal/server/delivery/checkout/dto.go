@@ -0,0 +1,105 @@
+package checkout
+
+import (
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/checkout"
+	"github.com/google/uuid"
+)
+
+// Share represents a credential shared with a group for exclusive check-out.
+type Share struct {
+	// CreatedAt contains the timestamp the credential was shared at.
+	CreatedAt time.Time `json:"created_at"                example:"2023-12-01T10:00:00Z"`
+	// CheckedOutAt contains the timestamp of the current check-out; omitted while checked in.
+	CheckedOutAt time.Time `json:"checked_out_at,omitzero"   example:"2023-12-01T11:00:00Z"`
+	// ExpiresAt contains the moment the current check-out ends automatically; omitted while checked in.
+	ExpiresAt time.Time `json:"expires_at,omitzero"       example:"2023-12-01T12:00:00Z"`
+	// CredentialID contains the identifier of the shared credential.
+	CredentialID uuid.UUID `json:"credential_id"             example:"123e4567-e89b-12d3-a456-426614174000"`
+	// OwnerID contains the identifier of the owner of the credential.
+	OwnerID uuid.UUID `json:"owner_id"                  example:"123e4567-e89b-12d3-a456-426614174001"`
+	// GroupID contains the identifier of the group whose members may check the credential out.
+	GroupID uuid.UUID `json:"group_id"                  example:"123e4567-e89b-12d3-a456-426614174002"`
+	// HolderID contains the identifier of the member holding the credential; omitted while checked in.
+	HolderID uuid.UUID `json:"holder_id,omitzero"        example:"123e4567-e89b-12d3-a456-426614174003"`
+	// RotateOnCheckIn indicates whether the password is rotated whenever the credential is checked in.
+	RotateOnCheckIn bool `json:"rotate_on_check_in"        example:"true"`
+}
+
+// NewShareFromApp converts an application layer shared credential to delivery DTO.
+func NewShareFromApp(s *checkout.Share) *Share {
+	if s == nil {
+		return nil
+	}
+	return &Share{
+		CredentialID:    s.CredentialID,
+		OwnerID:         s.OwnerID,
+		GroupID:         s.GroupID,
+		HolderID:        s.HolderID,
+		RotateOnCheckIn: s.RotateOnCheckIn,
+		CreatedAt:       s.CreatedAt,
+		CheckedOutAt:    s.CheckedOutAt,
+		ExpiresAt:       s.ExpiresAt,
+	}
+}
+
+// NewSharesFromApp converts application layer shared credentials to delivery DTOs.
+func NewSharesFromApp(ss []*checkout.Share) []*Share {
+	result := make([]*Share, 0, len(ss))
+	for _, s := range ss {
+		result = append(result, NewShareFromApp(s))
+	}
+	return result
+}
+
+// ListSharesResponse represents the response containing shared credentials.
+type ListSharesResponse struct {
+	// Shares contains the credentials the user shares and those shared with the groups of the user.
+	Shares []*Share `json:"shares"`
+}
+
+// Lease represents a check-out of a shared credential with its revealed secret.
+type Lease struct {
+	// CheckedOutAt contains the timestamp the credential was checked out at.
+	CheckedOutAt time.Time `json:"checked_out_at"        example:"2023-12-01T11:00:00Z"`
+	// ExpiresAt contains the moment the credential is checked in automatically.
+	ExpiresAt time.Time `json:"expires_at"            example:"2023-12-01T12:00:00Z"`
+	// Login contains the credential login/username.
+	Login string `json:"login"                 example:"root"`
+	// Password contains the credential password.
+	Password string `json:"password"              example:"s3cr3tP@ssw0rd"`
+	// Description contains additional information about the credential.
+	Description string `json:"description,omitzero"  example:"Production database"`
+	// CredentialID contains the identifier of the checked out credential.
+	CredentialID uuid.UUID `json:"credential_id"         example:"123e4567-e89b-12d3-a456-426614174000"`
+}
+
+// NewLeaseFromApp converts an application layer check-out to delivery DTO.
+func NewLeaseFromApp(l *checkout.Lease) *Lease {
+	if l == nil {
+		return nil
+	}
+	return &Lease{
+		CredentialID: l.CredentialID,
+		Login:        l.Login,
+		Password:     l.Password,
+		Description:  l.Description,
+		CheckedOutAt: l.CheckedOutAt,
+		ExpiresAt:    l.ExpiresAt,
+	}
+}
+
+// ShareRequest represents the request to share a credential with a group.
+type ShareRequest struct {
+	// GroupID contains the identifier of the group allowed to check the credential out (required UUID format).
+	GroupID string `json:"group_id"           binding:"required,uuid" example:"123e4567-e89b-12d3-a456-426614174002"`
+	// RotateOnCheckIn determines whether the password is rotated whenever the credential is checked in.
+	RotateOnCheckIn bool `json:"rotate_on_check_in"                         example:"true"`
+}
+
+// CredentialIDRequest represents the shared credential addressed in the request path.
+type CredentialIDRequest struct {
+	// ID contains the credential identifier (required UUID format).
+	ID string `uri:"id" binding:"required,uuid" example:"123e4567-e89b-12d3-a456-426614174000"`
+}
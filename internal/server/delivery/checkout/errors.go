@@ -0,0 +1,103 @@
+package checkout
+
+import (
+	"net/http"
+
+	app "github.com/gdyunin/aegis-vault-keeper/internal/server/application/checkout"
+	licenseApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/license"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/errutil"
+	plandel "github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/plan"
+	"github.com/gin-gonic/gin"
+)
+
+// CheckoutErrRegistry defines error handling policies for shared credential operations.
+var CheckoutErrRegistry = errutil.Registry{
+	{
+		ErrorIn: app.ErrCheckoutTechError,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusInternalServerError,
+			PublicMsg:  http.StatusText(http.StatusInternalServerError),
+			LogIt:      true,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassTech,
+		},
+	},
+	{
+		ErrorIn: app.ErrShareNotFound,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusNotFound,
+			PublicMsg:  "Shared credential not found",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassGeneric,
+		},
+	},
+	{
+		ErrorIn: app.ErrCredentialNotFound,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusNotFound,
+			PublicMsg:  "Credential not found",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassGeneric,
+		},
+	},
+	{
+		ErrorIn: app.ErrGroupNotFound,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusNotFound,
+			PublicMsg:  "Group not found",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassGeneric,
+		},
+	},
+	{
+		ErrorIn: app.ErrCheckedOut,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusConflict,
+			PublicMsg:  "Credential is checked out by another member",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassGeneric,
+		},
+	},
+	{
+		ErrorIn: app.ErrNotCheckedOut,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusConflict,
+			PublicMsg:  "Credential is not checked out by the user",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassGeneric,
+		},
+	},
+	{
+		ErrorIn: app.ErrCheckoutAppError,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusBadRequest,
+			PublicMsg:  "Invalid shared credential parameters",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+	{
+		ErrorIn: licenseApp.ErrLicenseFeatureUnavailable,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusForbidden,
+			PublicMsg:  "Sharing credentials with groups requires an enterprise license",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassGeneric,
+		},
+	},
+}
+
+// handledErrRegistry aggregates the shared credential and plan enforcement error registries.
+var handledErrRegistry = errutil.Merge(CheckoutErrRegistry, plandel.PlanErrRegistry)
+
+// handleError processes shared credential errors using the registry and returns appropriate HTTP response.
+func handleError(err error, c *gin.Context) (int, []string) {
+	return errutil.HandleWithRegistry(handledErrRegistry, err, c)
+}
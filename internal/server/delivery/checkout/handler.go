@@ -0,0 +1,277 @@
+package checkout
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/checkout"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/response"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/util"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Service defines the shared credential application service interface.
+type Service interface {
+	// Share shares a credential of the owner with a group.
+	Share(context.Context, checkout.ShareParams) (*checkout.Share, error)
+	// Unshare stops sharing a credential of the owner.
+	Unshare(context.Context, checkout.UnshareParams) error
+	// List retrieves the credentials the user shares and those shared with the groups of the user.
+	List(context.Context, checkout.ListParams) ([]*checkout.Share, error)
+	// CheckOut checks a shared credential out to the user and reveals it.
+	CheckOut(context.Context, checkout.CheckOutParams) (*checkout.Lease, error)
+	// CheckIn returns a shared credential checked out by the user.
+	CheckIn(context.Context, checkout.CheckInParams) error
+}
+
+// Handler handles HTTP requests for shared credential endpoints.
+type Handler struct {
+	// s is the shared credential service used to process operations.
+	s Service
+}
+
+// NewHandler creates a new shared credential handler with the provided service.
+func NewHandler(s Service) *Handler {
+	return &Handler{s: s}
+}
+
+// List retrieves the shared credentials of the authenticated user.
+// @Summary      List shared credentials
+// @Description  Retrieves the credentials the user shares and those shared with the groups the user belongs to
+// @Tags         Account
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} ListSharesResponse "Shared credentials retrieved successfully"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /account/shared-credentials [get]
+// .
+func (h *Handler) List(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	userID, err := extractor.UserID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+
+	shares, err := h.s.List(c, checkout.ListParams{UserID: userID})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, ListSharesResponse{Shares: NewSharesFromApp(shares)})
+}
+
+// Share shares a credential of the authenticated user with a group.
+// @Summary      Share credential
+// @Description  Lets the members of a directory group check the credential out one at a time. Sharing an
+// @Description  already shared credential changes its group and rotation setting without ending a check-out.
+// @Description  Sharing requires an enterprise license covering organization vaults when licenses are enforced.
+// .
+// @Tags         Account
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Credential ID" format(uuid)
+// @Param        request body ShareRequest true "Group and rotation setting"
+// @Success      200 {object} Share "Credential shared successfully"
+// @Failure      400 {object} response.Error "Bad request - invalid ID format or request body"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
+// @Failure      403 {object} response.Error "Forbidden - no enterprise license covers organization vaults"
+// @Failure      404 {object} response.Error "Not found - credential or group not found"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /account/shared-credentials/{id} [put]
+// .
+func (h *Handler) Share(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	userID, err := extractor.UserID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+
+	credentialID, ok := bindCredentialID(c, extractor)
+	if !ok {
+		return
+	}
+
+	// req holds the deserialized JSON request payload for the share.
+	var req ShareRequest
+	if err := extractor.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	groupID, err := uuid.Parse(req.GroupID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	share, err := h.s.Share(c, checkout.ShareParams{
+		CredentialID:    credentialID,
+		OwnerID:         userID,
+		GroupID:         groupID,
+		RotateOnCheckIn: req.RotateOnCheckIn,
+	})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, NewShareFromApp(share))
+}
+
+// Unshare stops sharing a credential of the authenticated user.
+// @Summary      Unshare credential
+// @Description  Stops sharing the credential; a member holding it loses access immediately
+// @Tags         Account
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Credential ID" format(uuid)
+// @Success      204 "Credential unshared successfully"
+// @Failure      400 {object} response.Error "Bad request - invalid ID format"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
+// @Failure      404 {object} response.Error "Not found - shared credential not found"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /account/shared-credentials/{id} [delete]
+// .
+func (h *Handler) Unshare(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	userID, err := extractor.UserID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+
+	credentialID, ok := bindCredentialID(c, extractor)
+	if !ok {
+		return
+	}
+
+	if err := h.s.Unshare(c, checkout.UnshareParams{CredentialID: credentialID, OwnerID: userID}); err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.Data(http.StatusNoContent, "", nil)
+}
+
+// CheckOut checks a shared credential out to the authenticated user.
+// @Summary      Check out shared credential
+// @Description  Reveals the credential exclusively to the user until it is checked in or the check-out expires.
+// @Description  Checking out a credential the user already holds returns the current check-out.
+// .
+// @Tags         Account
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Credential ID" format(uuid)
+// @Success      200 {object} Lease "Credential checked out successfully"
+// @Failure      400 {object} response.Error "Bad request - invalid ID format"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
+// @Failure      404 {object} response.Error "Not found - shared credential not found"
+// @Failure      409 {object} response.Error "Conflict - credential is checked out by another member"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /account/shared-credentials/{id}/checkout [post]
+// .
+func (h *Handler) CheckOut(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	userID, err := extractor.UserID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+
+	credentialID, ok := bindCredentialID(c, extractor)
+	if !ok {
+		return
+	}
+
+	lease, err := h.s.CheckOut(c, checkout.CheckOutParams{CredentialID: credentialID, UserID: userID})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, NewLeaseFromApp(lease))
+}
+
+// CheckIn returns a shared credential checked out by the authenticated user.
+// @Summary      Check in shared credential
+// @Description  Ends the check-out of the user; the password is rotated when the share requires it
+// @Tags         Account
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Credential ID" format(uuid)
+// @Success      204 "Credential checked in successfully"
+// @Failure      400 {object} response.Error "Bad request - invalid ID format"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
+// @Failure      404 {object} response.Error "Not found - shared credential not found"
+// @Failure      409 {object} response.Error "Conflict - credential is not checked out by the user"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /account/shared-credentials/{id}/checkin [post]
+// .
+func (h *Handler) CheckIn(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	userID, err := extractor.UserID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+
+	credentialID, ok := bindCredentialID(c, extractor)
+	if !ok {
+		return
+	}
+
+	if err := h.s.CheckIn(c, checkout.CheckInParams{CredentialID: credentialID, UserID: userID}); err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.Data(http.StatusNoContent, "", nil)
+}
+
+// bindCredentialID extracts the credential identifier from the request path.
+// Responds with 400 Bad Request and returns false when the identifier is missing or malformed.
+func bindCredentialID(c *gin.Context, extractor *util.CtxExtractor) (uuid.UUID, bool) {
+	// req holds the deserialized URI parameters of the request.
+	var req CredentialIDRequest
+	if err := extractor.BindURI(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return uuid.Nil, false
+	}
+
+	credentialID, err := uuid.Parse(req.ID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return uuid.Nil, false
+	}
+	return credentialID, true
+}
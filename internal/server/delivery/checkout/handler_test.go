@@ -0,0 +1,444 @@
+package checkout
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/checkout"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/license"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/consts"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockCheckoutService implements Service for testing.
+type mockCheckoutService struct {
+	shareFunc    func(ctx context.Context, params checkout.ShareParams) (*checkout.Share, error)
+	unshareFunc  func(ctx context.Context, params checkout.UnshareParams) error
+	listFunc     func(ctx context.Context, params checkout.ListParams) ([]*checkout.Share, error)
+	checkOutFunc func(ctx context.Context, params checkout.CheckOutParams) (*checkout.Lease, error)
+	checkInFunc  func(ctx context.Context, params checkout.CheckInParams) error
+}
+
+func (m *mockCheckoutService) Share(ctx context.Context, params checkout.ShareParams) (*checkout.Share, error) {
+	if m.shareFunc != nil {
+		return m.shareFunc(ctx, params)
+	}
+	return nil, nil
+}
+
+func (m *mockCheckoutService) Unshare(ctx context.Context, params checkout.UnshareParams) error {
+	if m.unshareFunc != nil {
+		return m.unshareFunc(ctx, params)
+	}
+	return nil
+}
+
+func (m *mockCheckoutService) List(ctx context.Context, params checkout.ListParams) ([]*checkout.Share, error) {
+	if m.listFunc != nil {
+		return m.listFunc(ctx, params)
+	}
+	return nil, nil
+}
+
+func (m *mockCheckoutService) CheckOut(ctx context.Context, params checkout.CheckOutParams) (*checkout.Lease, error) {
+	if m.checkOutFunc != nil {
+		return m.checkOutFunc(ctx, params)
+	}
+	return nil, nil
+}
+
+func (m *mockCheckoutService) CheckIn(ctx context.Context, params checkout.CheckInParams) error {
+	if m.checkInFunc != nil {
+		return m.checkInFunc(ctx, params)
+	}
+	return nil
+}
+
+func TestHandler_List(t *testing.T) {
+	t.Parallel()
+
+	userID, credentialID := uuid.New(), uuid.New()
+
+	tests := []struct {
+		mockService    *mockCheckoutService
+		name           string
+		setUser        bool
+		wantCount      int
+		expectedStatus int
+	}{
+		{
+			name:    "success",
+			setUser: true,
+			mockService: &mockCheckoutService{
+				listFunc: func(_ context.Context, params checkout.ListParams) ([]*checkout.Share, error) {
+					assert.Equal(t, checkout.ListParams{UserID: userID}, params)
+					return []*checkout.Share{{CredentialID: credentialID, HolderID: userID}}, nil
+				},
+			},
+			wantCount:      1,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "missing user context",
+			mockService:    &mockCheckoutService{},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:    "service error",
+			setUser: true,
+			mockService: &mockCheckoutService{
+				listFunc: func(context.Context, checkout.ListParams) ([]*checkout.Share, error) {
+					return nil, checkout.ErrCheckoutTechError
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/account/shared-credentials", nil)
+			if tt.setUser {
+				c.Set(consts.CtxKeyUserID, userID)
+			}
+
+			NewHandler(tt.mockService).List(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.wantCount == 0 {
+				return
+			}
+			var got ListSharesResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+			require.Len(t, got.Shares, tt.wantCount)
+			assert.Equal(t, credentialID, got.Shares[0].CredentialID)
+			assert.Equal(t, userID, got.Shares[0].HolderID)
+		})
+	}
+}
+
+func TestHandler_Share(t *testing.T) {
+	t.Parallel()
+
+	ownerID, credentialID, groupID := uuid.New(), uuid.New(), uuid.New()
+
+	tests := []struct {
+		mockService    *mockCheckoutService
+		name           string
+		id             string
+		body           string
+		expectedStatus int
+	}{
+		{
+			name: "success",
+			id:   credentialID.String(),
+			body: `{"group_id":"` + groupID.String() + `","rotate_on_check_in":true}`,
+			mockService: &mockCheckoutService{
+				shareFunc: func(_ context.Context, params checkout.ShareParams) (*checkout.Share, error) {
+					assert.Equal(t, checkout.ShareParams{
+						CredentialID:    credentialID,
+						OwnerID:         ownerID,
+						GroupID:         groupID,
+						RotateOnCheckIn: true,
+					}, params)
+					return &checkout.Share{CredentialID: credentialID, GroupID: groupID, RotateOnCheckIn: true}, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid ID",
+			id:             "db",
+			body:           `{"group_id":"` + groupID.String() + `"}`,
+			mockService:    &mockCheckoutService{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "missing group",
+			id:             credentialID.String(),
+			body:           `{"rotate_on_check_in":true}`,
+			mockService:    &mockCheckoutService{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "group not found",
+			id:   credentialID.String(),
+			body: `{"group_id":"` + groupID.String() + `"}`,
+			mockService: &mockCheckoutService{
+				shareFunc: func(context.Context, checkout.ShareParams) (*checkout.Share, error) {
+					return nil, checkout.ErrGroupNotFound
+				},
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name: "credential not found",
+			id:   credentialID.String(),
+			body: `{"group_id":"` + groupID.String() + `"}`,
+			mockService: &mockCheckoutService{
+				shareFunc: func(context.Context, checkout.ShareParams) (*checkout.Share, error) {
+					return nil, checkout.ErrCredentialNotFound
+				},
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name: "not licensed",
+			id:   credentialID.String(),
+			body: `{"group_id":"` + groupID.String() + `"}`,
+			mockService: &mockCheckoutService{
+				shareFunc: func(context.Context, checkout.ShareParams) (*checkout.Share, error) {
+					return nil, fmt.Errorf("license check failed: %w", license.ErrLicenseFeatureUnavailable)
+				},
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPut, "/account/shared-credentials/"+tt.id,
+				strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Params = gin.Params{{Key: "id", Value: tt.id}}
+			c.Set(consts.CtxKeyUserID, ownerID)
+
+			NewHandler(tt.mockService).Share(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestHandler_Unshare(t *testing.T) {
+	t.Parallel()
+
+	ownerID, credentialID := uuid.New(), uuid.New()
+
+	tests := []struct {
+		mockService    *mockCheckoutService
+		name           string
+		id             string
+		expectedStatus int
+	}{
+		{
+			name: "success",
+			id:   credentialID.String(),
+			mockService: &mockCheckoutService{
+				unshareFunc: func(_ context.Context, params checkout.UnshareParams) error {
+					assert.Equal(t, checkout.UnshareParams{CredentialID: credentialID, OwnerID: ownerID}, params)
+					return nil
+				},
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "invalid ID",
+			id:             "db",
+			mockService:    &mockCheckoutService{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "not shared",
+			id:   credentialID.String(),
+			mockService: &mockCheckoutService{
+				unshareFunc: func(context.Context, checkout.UnshareParams) error {
+					return checkout.ErrShareNotFound
+				},
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodDelete, "/account/shared-credentials/"+tt.id, nil)
+			c.Params = gin.Params{{Key: "id", Value: tt.id}}
+			c.Set(consts.CtxKeyUserID, ownerID)
+
+			NewHandler(tt.mockService).Unshare(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestHandler_CheckOut(t *testing.T) {
+	t.Parallel()
+
+	userID, credentialID := uuid.New(), uuid.New()
+	expiresAt := time.Date(2024, 3, 1, 11, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		mockService    *mockCheckoutService
+		want           *Lease
+		name           string
+		id             string
+		expectedStatus int
+	}{
+		{
+			name: "success",
+			id:   credentialID.String(),
+			mockService: &mockCheckoutService{
+				checkOutFunc: func(_ context.Context, params checkout.CheckOutParams) (*checkout.Lease, error) {
+					assert.Equal(t, checkout.CheckOutParams{CredentialID: credentialID, UserID: userID}, params)
+					return &checkout.Lease{
+						CredentialID: credentialID,
+						Login:        "root",
+						Password:     "secret",
+						ExpiresAt:    expiresAt,
+					}, nil
+				},
+			},
+			want: &Lease{
+				CredentialID: credentialID,
+				Login:        "root",
+				Password:     "secret",
+				ExpiresAt:    expiresAt,
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid ID",
+			id:             "db",
+			mockService:    &mockCheckoutService{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "held by another member",
+			id:   credentialID.String(),
+			mockService: &mockCheckoutService{
+				checkOutFunc: func(context.Context, checkout.CheckOutParams) (*checkout.Lease, error) {
+					return nil, errors.Join(checkout.ErrCheckedOut, errors.New("lease changed"))
+				},
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name: "not shared",
+			id:   credentialID.String(),
+			mockService: &mockCheckoutService{
+				checkOutFunc: func(context.Context, checkout.CheckOutParams) (*checkout.Lease, error) {
+					return nil, checkout.ErrShareNotFound
+				},
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/account/shared-credentials/"+tt.id+"/checkout", nil)
+			c.Params = gin.Params{{Key: "id", Value: tt.id}}
+			c.Set(consts.CtxKeyUserID, userID)
+
+			NewHandler(tt.mockService).CheckOut(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.want == nil {
+				return
+			}
+			var got Lease
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+			assert.Equal(t, *tt.want, got)
+		})
+	}
+}
+
+func TestHandler_CheckIn(t *testing.T) {
+	t.Parallel()
+
+	userID, credentialID := uuid.New(), uuid.New()
+
+	tests := []struct {
+		mockService    *mockCheckoutService
+		name           string
+		id             string
+		expectedStatus int
+	}{
+		{
+			name: "success",
+			id:   credentialID.String(),
+			mockService: &mockCheckoutService{
+				checkInFunc: func(_ context.Context, params checkout.CheckInParams) error {
+					assert.Equal(t, checkout.CheckInParams{CredentialID: credentialID, UserID: userID}, params)
+					return nil
+				},
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "invalid ID",
+			id:             "db",
+			mockService:    &mockCheckoutService{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "not held by user",
+			id:   credentialID.String(),
+			mockService: &mockCheckoutService{
+				checkInFunc: func(context.Context, checkout.CheckInParams) error {
+					return checkout.ErrNotCheckedOut
+				},
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name: "rotation failure",
+			id:   credentialID.String(),
+			mockService: &mockCheckoutService{
+				checkInFunc: func(context.Context, checkout.CheckInParams) error {
+					return errors.Join(checkout.ErrCheckoutTechError, errors.New("db down"))
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/account/shared-credentials/"+tt.id+"/checkin", nil)
+			c.Params = gin.Params{{Key: "id", Value: tt.id}}
+			c.Set(consts.CtxKeyUserID, userID)
+
+			NewHandler(tt.mockService).CheckIn(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
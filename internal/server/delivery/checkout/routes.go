@@ -0,0 +1,15 @@
+package checkout
+
+import "github.com/gin-gonic/gin"
+
+// RegisterRoutes registers shared credential routes with the provided router group.
+// Creates /shared-credentials and the share and check-out endpoints of /shared-credentials/:id with the
+// specified handler.
+func RegisterRoutes(r *gin.RouterGroup, h *Handler) {
+	sharesGroup := r.Group("/shared-credentials")
+	sharesGroup.GET("", h.List)
+	sharesGroup.PUT("/:id", h.Share)
+	sharesGroup.DELETE("/:id", h.Unshare)
+	sharesGroup.POST("/:id/checkout", h.CheckOut)
+	sharesGroup.POST("/:id/checkin", h.CheckIn)
+}
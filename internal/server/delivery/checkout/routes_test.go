@@ -0,0 +1,28 @@
+package checkout
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterRoutes(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	RegisterRoutes(router.Group("/account"), NewHandler(&mockCheckoutService{}))
+
+	got := make(map[string]string)
+	for _, r := range router.Routes() {
+		got[r.Method+" "+r.Path] = r.Handler
+	}
+	assert.Len(t, got, 5)
+	assert.Contains(t, got, http.MethodGet+" /account/shared-credentials")
+	assert.Contains(t, got, http.MethodPut+" /account/shared-credentials/:id")
+	assert.Contains(t, got, http.MethodDelete+" /account/shared-credentials/:id")
+	assert.Contains(t, got, http.MethodPost+" /account/shared-credentials/:id/checkout")
+	assert.Contains(t, got, http.MethodPost+" /account/shared-credentials/:id/checkin")
+}
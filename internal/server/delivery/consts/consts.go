@@ -3,8 +3,44 @@ package consts
 // HeaderXRequestID defines the HTTP header name for request ID tracking.
 const HeaderXRequestID = "X-Request-Id"
 
+// HeaderXAdminToken defines the HTTP header name carrying the admin API token.
+const HeaderXAdminToken = "X-Admin-Token"
+
+// HeaderXAdminApproval defines the HTTP header name naming the approval a destructive admin request runs under.
+const HeaderXAdminApproval = "X-Admin-Approval"
+
+// HeaderXSignature defines the HTTP header name carrying the HMAC signature of high-privilege requests.
+const HeaderXSignature = "X-Signature"
+
+// HeaderXPushSubscription defines the HTTP header name carrying the push subscription ID of the requesting device.
+const HeaderXPushSubscription = "X-Push-Subscription"
+
+// HeaderXChallengeResponse defines the HTTP header name carrying the solved CAPTCHA or proof-of-work challenge.
+const HeaderXChallengeResponse = "X-Challenge-Response"
+
+// HeaderXDryRun defines the HTTP header name marking mutating requests whose changes are rolled back.
+const HeaderXDryRun = "X-Dry-Run"
+
+// HeaderXDeviceKey defines the HTTP header name naming the device key sync responses are encrypted for.
+const HeaderXDeviceKey = "X-Device-Key"
+
+// HeaderXResponseSignature defines the HTTP header name carrying the Ed25519 signature of critical responses.
+const HeaderXResponseSignature = "X-Response-Signature"
+
+// HeaderXCSRFToken defines the HTTP header name carrying the CSRF token of cookie session requests.
+const HeaderXCSRFToken = "X-CSRF-Token"
+
+// IncludeArchived defines the include query parameter value adding archived items to item lists.
+const IncludeArchived = "archived"
+
+// SortManual defines the sort query parameter value ordering item lists in the manual order of a folder.
+const SortManual = "manual"
+
 // CtxKeyUserID defines the context key for storing authenticated user ID.
 const CtxKeyUserID = "userID"
 
+// CtxKeyAdminOperator defines the context key for storing the name of the authenticated admin operator.
+const CtxKeyAdminOperator = "adminOperator"
+
 // ErrorMessageInvalidParameters defines the standard error message for parameter validation failures.
 const ErrorMessageInvalidParameters = "Invalid or missing request parameters"
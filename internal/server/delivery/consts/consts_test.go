@@ -19,11 +19,51 @@ func TestConstants(t *testing.T) {
 			got:  HeaderXRequestID,
 			want: "X-Request-Id",
 		},
+		{
+			name: "HeaderXAdminToken",
+			got:  HeaderXAdminToken,
+			want: "X-Admin-Token",
+		},
+		{
+			name: "HeaderXAdminApproval",
+			got:  HeaderXAdminApproval,
+			want: "X-Admin-Approval",
+		},
+		{
+			name: "HeaderXSignature",
+			got:  HeaderXSignature,
+			want: "X-Signature",
+		},
+		{
+			name: "HeaderXPushSubscription",
+			got:  HeaderXPushSubscription,
+			want: "X-Push-Subscription",
+		},
+		{
+			name: "HeaderXChallengeResponse",
+			got:  HeaderXChallengeResponse,
+			want: "X-Challenge-Response",
+		},
+		{
+			name: "HeaderXDryRun",
+			got:  HeaderXDryRun,
+			want: "X-Dry-Run",
+		},
+		{
+			name: "HeaderXCSRFToken",
+			got:  HeaderXCSRFToken,
+			want: "X-CSRF-Token",
+		},
 		{
 			name: "CtxKeyUserID",
 			got:  CtxKeyUserID,
 			want: "userID",
 		},
+		{
+			name: "CtxKeyAdminOperator",
+			got:  CtxKeyAdminOperator,
+			want: "adminOperator",
+		},
 		{
 			name: "ErrorMessageInvalidParameters",
 			got:  ErrorMessageInvalidParameters,
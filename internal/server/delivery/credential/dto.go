@@ -1,6 +1,7 @@
 package credential
 
 import (
+	"slices"
 	"time"
 
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/credential"
@@ -17,8 +18,70 @@ type Credential struct {
 	Password string `json:"password,omitzero"    example:"securePassword123"`
 	// Description contains optional user notes about where this credential is used.
 	Description string `json:"description,omitzero" example:"Email account credentials"`
+	// URIs contains the website and app addresses of the credential with their match rules.
+	URIs []URI `json:"uris,omitzero"`
 	// ID contains the unique identifier for this credential record.
 	ID uuid.UUID `json:"id,omitzero"          example:"123e4567-e89b-12d3-a456-426614174000"`
+	// Archived reports whether the credential is archived and hidden from lists by default.
+	Archived bool `json:"archived,omitzero"    example:"false"`
+}
+
+// fieldNames lists the credential fields clients may select with the fields query parameter.
+var fieldNames = []string{"id", "login", "password", "description", "uris", "updated_at"}
+
+// keepFields clears the fields of the credential not named in fields, always keeping the ID.
+// An empty field list keeps every field.
+func (c *Credential) keepFields(fields []string) {
+	if c == nil || len(fields) == 0 {
+		return
+	}
+	if !slices.Contains(fields, "login") {
+		c.Login = ""
+	}
+	if !slices.Contains(fields, "password") {
+		c.Password = ""
+	}
+	if !slices.Contains(fields, "description") {
+		c.Description = ""
+	}
+	if !slices.Contains(fields, "uris") {
+		c.URIs = nil
+	}
+	if !slices.Contains(fields, "updated_at") {
+		c.UpdatedAt = time.Time{}
+	}
+}
+
+// URI represents a website or app address of a credential with the rule it is matched by.
+type URI struct {
+	// Value contains the URI, or the regular expression for the regex rule.
+	Value string `json:"value"           example:"https://example.com"`
+	// Match contains the rule the URI is matched by: base_domain (default), host, starts_with, regex or never.
+	Match string `json:"match,omitzero" example:"base_domain"`
+}
+
+// urisToApp converts URI DTOs to application layer URIs.
+func urisToApp(uris []URI) []credential.URI {
+	if len(uris) == 0 {
+		return nil
+	}
+	result := make([]credential.URI, 0, len(uris))
+	for _, u := range uris {
+		result = append(result, credential.URI{Value: u.Value, Match: u.Match})
+	}
+	return result
+}
+
+// newURIsFromApp converts application layer URIs to DTOs.
+func newURIsFromApp(uris []credential.URI) []URI {
+	if len(uris) == 0 {
+		return nil
+	}
+	result := make([]URI, 0, len(uris))
+	for _, u := range uris {
+		result = append(result, URI{Value: u.Value, Match: u.Match})
+	}
+	return result
 }
 
 // ToApp converts this DTO to an application layer Credential entity with the specified user ID.
@@ -32,6 +95,7 @@ func (c *Credential) ToApp(userID uuid.UUID) *credential.Credential {
 		Login:       c.Login,
 		Password:    c.Password,
 		Description: c.Description,
+		URIs:        urisToApp(c.URIs),
 		UpdatedAt:   c.UpdatedAt,
 	}
 }
@@ -58,7 +122,9 @@ func NewCredentialFromApp(c *credential.Credential) *Credential {
 		Login:       c.Login,
 		Password:    c.Password,
 		Description: c.Description,
+		URIs:        newURIsFromApp(c.URIs),
 		UpdatedAt:   c.UpdatedAt,
+		Archived:    c.Archived,
 	}
 }
 
@@ -82,6 +148,8 @@ type PushRequest struct {
 	Password string `json:"password"             binding:"required" example:"securePassword123"`
 	// Optional description
 	Description string `json:"description,omitzero"                    example:"Email account credentials"`
+	// Optional website and app addresses with their match rules (max 32)
+	URIs []URI `json:"uris,omitzero"`
 }
 
 // PullRequest represents the request to retrieve a specific credential.
@@ -90,6 +158,18 @@ type PullRequest struct {
 	ID string `uri:"id" binding:"required" example:"123e4567-e89b-12d3-a456-426614174000"`
 }
 
+// MatchRequest represents the request for the credentials of a website or app.
+type MatchRequest struct {
+	// URI of the website or app asking for credentials (required)
+	URI string `form:"uri" binding:"required" example:"https://login.example.com/signin"`
+}
+
+// AsOfRequest represents the request addressing a credential state at a past moment.
+type AsOfRequest struct {
+	// Timestamp contains the RFC 3339 moment whose credential version is addressed (required).
+	Timestamp time.Time `form:"timestamp" binding:"required" example:"2023-12-01T10:00:00Z"`
+}
+
 // PushResponse represents the response after creating or updating a credential.
 type PushResponse struct {
 	// Created or updated credential ID
@@ -107,3 +187,15 @@ type ListResponse struct {
 	// List of credentials
 	Credentials []*Credential `json:"credentials"`
 }
+
+// MatchResponse represents the response containing the credentials matching a website or app.
+type MatchResponse struct {
+	// Matching credentials
+	Credentials []*Credential `json:"credentials"`
+}
+
+// RecoverResponse represents the response containing the recovered credential.
+type RecoverResponse struct {
+	// Credential contains the credential data saved as the latest version.
+	Credential *Credential `json:"credential"`
+}
@@ -5,6 +5,8 @@ import (
 
 	app "github.com/gdyunin/aegis-vault-keeper/internal/server/application/credential"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/errutil"
+	itempolicydel "github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/itempolicy"
+	plandel "github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/plan"
 	"github.com/gin-gonic/gin"
 )
 
@@ -23,6 +25,17 @@ var CredentialErrRegistry = errutil.Registry{
 		},
 	},
 
+	{
+		ErrorIn: app.ErrCredentialIntegrityViolation,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusInternalServerError,
+			PublicMsg:  "Stored credential data failed integrity verification",
+			LogIt:      true,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassTech,
+		},
+	},
+
 	{
 		ErrorIn: app.ErrCredentialAccessDenied,
 		HandlePolicy: errutil.Policy{
@@ -66,6 +79,37 @@ var CredentialErrRegistry = errutil.Registry{
 		},
 	},
 
+	{
+		ErrorIn: app.ErrCredentialIncorrectURI,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusBadRequest,
+			PublicMsg:  "Invalid URI",
+			LogIt:      false,
+			AllowMerge: true,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+	{
+		ErrorIn: app.ErrCredentialIncorrectMatchRule,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusBadRequest,
+			PublicMsg:  "Invalid URI match rule",
+			LogIt:      false,
+			AllowMerge: true,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+	{
+		ErrorIn: app.ErrCredentialTooManyURIs,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusBadRequest,
+			PublicMsg:  "Too many URIs",
+			LogIt:      false,
+			AllowMerge: true,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+
 	{
 		ErrorIn: app.ErrCredentialAppError,
 		HandlePolicy: errutil.Policy{
@@ -78,8 +122,15 @@ var CredentialErrRegistry = errutil.Registry{
 	},
 }
 
+// handledErrRegistry aggregates the credential, plan enforcement and item policy enforcement error registries.
+var handledErrRegistry = errutil.Merge(
+	CredentialErrRegistry,
+	plandel.PlanErrRegistry,
+	itempolicydel.ItemPolicyErrRegistry,
+)
+
 // handleError processes credential application errors using the registry.
 // Returns HTTP status code and error messages for response.
 func handleError(err error, c *gin.Context) (int, []string) {
-	return errutil.HandleWithRegistry(CredentialErrRegistry, err, c)
+	return errutil.HandleWithRegistry(handledErrRegistry, err, c)
 }
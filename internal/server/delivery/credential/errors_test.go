@@ -109,10 +109,14 @@ func TestCredentialErrRegistry_Coverage(t *testing.T) {
 	// Verify all expected credential errors are covered
 	expectedErrors := []error{
 		app.ErrCredentialTechError,
+		app.ErrCredentialIntegrityViolation,
 		app.ErrCredentialAccessDenied,
 		app.ErrCredentialNotFound,
 		app.ErrCredentialIncorrectLogin,
 		app.ErrCredentialIncorrectPassword,
+		app.ErrCredentialIncorrectURI,
+		app.ErrCredentialIncorrectMatchRule,
+		app.ErrCredentialTooManyURIs,
 		app.ErrCredentialAppError,
 	}
 
@@ -3,8 +3,11 @@ package credential
 import (
 	"context"
 	"net/http"
+	"slices"
 
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/credential"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/consts"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/jsonenc"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/response"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/util"
 	"github.com/gin-gonic/gin"
@@ -17,8 +20,12 @@ type Service interface {
 	Pull(context.Context, credential.PullParams) (*credential.Credential, error)
 	// List retrieves all credentials belonging to the authenticated user.
 	List(context.Context, credential.ListParams) ([]*credential.Credential, error)
+	// Match retrieves the credentials of the authenticated user matching the URI of a website or app.
+	Match(context.Context, credential.MatchParams) ([]*credential.Credential, error)
 	// Push creates or updates a credential for the authenticated user.
 	Push(context.Context, *credential.PushParams) (uuid.UUID, error)
+	// Recover restores a credential of the authenticated user to the version current at a given moment.
+	Recover(context.Context, credential.RecoverParams) (*credential.Credential, error)
 }
 
 // Handler handles HTTP requests for credential endpoints.
@@ -40,8 +47,9 @@ func NewHandler(s Service) *Handler {
 // @Produce      json
 // @Security     BearerAuth
 // @Param        id path string true "Credential ID" format(uuid)
+// @Param        fields query string false "Comma-separated fields to return; the ID is always returned"
 // @Success      200 {object} PullResponse "Credential retrieved successfully"
-// @Failure      400 {object} response.Error "Bad request - invalid ID format"
+// @Failure      400 {object} response.Error "Bad request - invalid ID format or unknown field"
 // @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
 // @Failure      404 {object} response.Error "Not found - credential not found"
 // @Failure      500 {object} response.Error "Internal server error"
@@ -69,7 +77,13 @@ func (h *Handler) Pull(c *gin.Context) {
 		return
 	}
 
-	cred, err := h.s.Pull(c, credential.PullParams{ID: pullingID, UserID: userID})
+	fields, err := extractor.Fields(fieldNames)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	cred, err := h.s.Pull(c, credential.PullParams{Fields: fields, ID: pullingID, UserID: userID})
 	if err != nil {
 		code, msgs := handleError(err, c)
 		c.JSON(code, response.Error{
@@ -79,6 +93,7 @@ func (h *Handler) Pull(c *gin.Context) {
 	}
 
 	resp := PullResponse{Credential: NewCredentialFromApp(cred)}
+	resp.Credential.keepFields(fields)
 	c.JSON(http.StatusOK, resp)
 }
 
@@ -89,8 +104,13 @@ func (h *Handler) Pull(c *gin.Context) {
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
+// @Param        fields query string false "Comma-separated fields to return; the ID is always returned"
+// @Param        include query string false "Comma-separated item groups to add; archived lists archived items too"
+// @Param        sort query string false "List order; manual lists pinned and arranged items first" Enums(manual)
+// @Param        folder query string false "Folder whose manual order sorts the list; the root when omitted"
 // @Success      200 {object} ListResponse "Credentials retrieved successfully"
 // @Success      204 "No credentials found"
+// @Failure      400 {object} response.Error "Bad request - unknown field, include or sort"
 // @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
 // @Failure      500 {object} response.Error "Internal server error"
 // @Router       /items/credentials [get]
@@ -104,7 +124,31 @@ func (h *Handler) List(c *gin.Context) {
 		return
 	}
 
-	creds, err := h.s.List(c, credential.ListParams{UserID: userID})
+	fields, err := extractor.Fields(fieldNames)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	include, err := extractor.Include([]string{consts.IncludeArchived})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	sort, err := extractor.Sort([]string{consts.SortManual})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	creds, err := h.s.List(c, credential.ListParams{
+		Fields:          fields,
+		UserID:          userID,
+		OrderFolder:     extractor.Folder(),
+		ExcludeArchived: !slices.Contains(include, consts.IncludeArchived),
+		ManualOrder:     sort == consts.SortManual,
+	})
 	if err != nil {
 		code, msgs := handleError(err, c)
 		c.JSON(code, response.Error{
@@ -119,7 +163,63 @@ func (h *Handler) List(c *gin.Context) {
 	}
 
 	resp := ListResponse{Credentials: NewCredentialsFromApp(creds)}
-	c.JSON(http.StatusOK, resp)
+	for _, cred := range resp.Credentials {
+		cred.keepFields(fields)
+	}
+	c.Render(http.StatusOK, jsonenc.JSON{Value: &resp})
+}
+
+// Match retrieves the credentials matching the URI of a website or app.
+// @Summary      Match credentials by URI
+// @Description  Retrieves the credentials of the authenticated user with a URI matching the given one, leaving out
+// @Description  archived ones. Every credential URI is compared under its own match rule: base_domain (default)
+// @Description  compares registrable domains, host compares host names and ports, starts_with compares prefixes,
+// @Description  regex takes the credential URI as a regular expression, and never matches nothing. App URIs,
+// @Description  androidapp://<package name> and iosapp://<bundle ID>, match only the same app of the same platform.
+// .
+// @Tags         Credentials
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        uri query string true "URI of the website or app asking for credentials"
+// @Success      200 {object} MatchResponse "Matching credentials retrieved successfully"
+// @Success      204 "No matching credentials found"
+// @Failure      400 {object} response.Error "Bad request - missing or invalid URI"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /items/credentials/match [get]
+// .
+func (h *Handler) Match(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	userID, err := extractor.UserID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+
+	// req holds the deserialized query parameters of the request.
+	var req MatchRequest
+	if err := extractor.BindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	creds, err := h.s.Match(c, credential.MatchParams{URI: req.URI, UserID: userID})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	if len(creds) == 0 {
+		c.Status(http.StatusNoContent)
+		return
+	}
+
+	c.Render(http.StatusOK, jsonenc.JSON{Value: &MatchResponse{Credentials: NewCredentialsFromApp(creds)}})
 }
 
 // Push creates a new credential or updates an existing one.
@@ -171,6 +271,7 @@ func (h *Handler) Push(c *gin.Context) {
 		Login:       req.Login,
 		Password:    req.Password,
 		Description: req.Description,
+		URIs:        urisToApp(req.URIs),
 	})
 	if err != nil {
 		code, msgs := handleError(err, c)
@@ -182,3 +283,107 @@ func (h *Handler) Push(c *gin.Context) {
 
 	c.JSON(http.StatusCreated, PushResponse{ID: newID})
 }
+
+// PullAsOf retrieves a specific credential as it was at the requested moment.
+// @Summary      Get credential at a past moment
+// @Description  Retrieves the version of a credential that was current at the given moment
+// @Tags         Credentials
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Credential ID" format(uuid)
+// @Param        timestamp query string true "Moment to view (RFC 3339)" format(date-time)
+// @Success      200 {object} PullResponse "Credential version retrieved successfully"
+// @Failure      400 {object} response.Error "Bad request - invalid ID or timestamp format"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
+// @Failure      404 {object} response.Error "Not found - no credential version at the given moment"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /items/credentials/{id}/as-of [get]
+// .
+func (h *Handler) PullAsOf(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	userID, err := extractor.UserID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+
+	credID, req, ok := bindAsOfRequest(c, extractor)
+	if !ok {
+		return
+	}
+
+	cred, err := h.s.Pull(c, credential.PullParams{AsOf: req.Timestamp, ID: credID, UserID: userID})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, PullResponse{Credential: NewCredentialFromApp(cred)})
+}
+
+// Recover restores a credential to the version that was current at the requested moment.
+// @Summary      Recover credential version
+// @Description  Saves the credential version current at the given moment as the latest version
+// @Tags         Credentials
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Credential ID" format(uuid)
+// @Param        timestamp query string true "Moment to recover to (RFC 3339)" format(date-time)
+// @Success      200 {object} RecoverResponse "Credential recovered successfully"
+// @Failure      400 {object} response.Error "Bad request - invalid ID or timestamp format"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
+// @Failure      404 {object} response.Error "Not found - no credential version at the given moment"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /items/credentials/{id}/recover [post]
+// .
+func (h *Handler) Recover(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	userID, err := extractor.UserID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+
+	credID, req, ok := bindAsOfRequest(c, extractor)
+	if !ok {
+		return
+	}
+
+	cred, err := h.s.Recover(c, credential.RecoverParams{AsOf: req.Timestamp, ID: credID, UserID: userID})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, RecoverResponse{Credential: NewCredentialFromApp(cred)})
+}
+
+// bindAsOfRequest extracts the credential ID from the path and the moment from the query.
+// On failure it writes a bad request response and reports false.
+func bindAsOfRequest(c *gin.Context, extractor *util.CtxExtractor) (uuid.UUID, AsOfRequest, bool) {
+	// uriReq holds the deserialized URI parameters identifying the credential.
+	var uriReq PullRequest
+	// req holds the deserialized query parameters selecting the moment.
+	var req AsOfRequest
+	if extractor.BindURI(&uriReq) != nil || extractor.BindQuery(&req) != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return uuid.Nil, req, false
+	}
+
+	id, err := uuid.Parse(uriReq.ID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return uuid.Nil, req, false
+	}
+	return id, req, true
+}
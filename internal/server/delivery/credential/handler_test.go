@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/credential"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/response"
@@ -20,9 +21,11 @@ import (
 
 // mockService implements the Service interface for testing.
 type mockService struct {
-	pullFunc func(ctx context.Context, params credential.PullParams) (*credential.Credential, error)
-	listFunc func(ctx context.Context, params credential.ListParams) ([]*credential.Credential, error)
-	pushFunc func(ctx context.Context, params *credential.PushParams) (uuid.UUID, error)
+	pullFunc    func(ctx context.Context, params credential.PullParams) (*credential.Credential, error)
+	listFunc    func(ctx context.Context, params credential.ListParams) ([]*credential.Credential, error)
+	matchFunc   func(ctx context.Context, params credential.MatchParams) ([]*credential.Credential, error)
+	pushFunc    func(ctx context.Context, params *credential.PushParams) (uuid.UUID, error)
+	recoverFunc func(ctx context.Context, params credential.RecoverParams) (*credential.Credential, error)
 }
 
 func (m *mockService) Pull(
@@ -45,6 +48,16 @@ func (m *mockService) List(
 	return nil, errors.New("not implemented")
 }
 
+func (m *mockService) Match(
+	ctx context.Context,
+	params credential.MatchParams,
+) ([]*credential.Credential, error) {
+	if m.matchFunc != nil {
+		return m.matchFunc(ctx, params)
+	}
+	return nil, errors.New("not implemented")
+}
+
 func (m *mockService) Push(ctx context.Context, params *credential.PushParams) (uuid.UUID, error) {
 	if m.pushFunc != nil {
 		return m.pushFunc(ctx, params)
@@ -52,6 +65,16 @@ func (m *mockService) Push(ctx context.Context, params *credential.PushParams) (
 	return uuid.Nil, errors.New("not implemented")
 }
 
+func (m *mockService) Recover(
+	ctx context.Context,
+	params credential.RecoverParams,
+) (*credential.Credential, error) {
+	if m.recoverFunc != nil {
+		return m.recoverFunc(ctx, params)
+	}
+	return nil, errors.New("not implemented")
+}
+
 func TestNewHandler(t *testing.T) {
 	t.Parallel()
 
@@ -213,12 +236,15 @@ func TestHandler_List(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
 	userID := uuid.New()
+	credID := uuid.New()
+	updatedAt := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
 
 	tests := []struct {
 		expectedBody   interface{}
 		setupContext   func(c *gin.Context)
 		mockSetup      func(m *mockService)
 		name           string
+		query          string
 		expectedStatus int
 	}{
 		{
@@ -229,6 +255,7 @@ func TestHandler_List(t *testing.T) {
 			mockSetup: func(m *mockService) {
 				m.listFunc = func(ctx context.Context, params credential.ListParams) ([]*credential.Credential, error) {
 					assert.Equal(t, userID, params.UserID)
+					assert.True(t, params.ExcludeArchived)
 					return []*credential.Credential{
 						{
 							ID:          uuid.New(),
@@ -262,6 +289,96 @@ func TestHandler_List(t *testing.T) {
 			expectedStatus: http.StatusOK, // Gin returns 200 even when c.Status(204) is called
 			expectedBody:   nil,
 		},
+		{
+			name:  "sparse fieldset",
+			query: "?fields=login,updated_at",
+			setupContext: func(c *gin.Context) {
+				c.Set("userID", userID)
+			},
+			mockSetup: func(m *mockService) {
+				m.listFunc = func(ctx context.Context, params credential.ListParams) ([]*credential.Credential, error) {
+					assert.Equal(t, []string{"login", "updated_at"}, params.Fields)
+					return []*credential.Credential{
+						{
+							ID:          credID,
+							UserID:      userID,
+							Login:       "user1@example.com",
+							Description: "Test credential 1",
+							UpdatedAt:   updatedAt,
+						},
+					}, nil
+				}
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: ListResponse{Credentials: []*Credential{
+				{ID: credID, Login: "user1@example.com", UpdatedAt: updatedAt},
+			}},
+		},
+		{
+			name:  "unknown field",
+			query: "?fields=login,cvv",
+			setupContext: func(c *gin.Context) {
+				c.Set("userID", userID)
+			},
+			mockSetup:      func(m *mockService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   response.DefaultBadRequestError,
+		},
+		{
+			name:  "archived included",
+			query: "?include=archived",
+			setupContext: func(c *gin.Context) {
+				c.Set("userID", userID)
+			},
+			mockSetup: func(m *mockService) {
+				m.listFunc = func(ctx context.Context, params credential.ListParams) ([]*credential.Credential, error) {
+					assert.False(t, params.ExcludeArchived)
+					return []*credential.Credential{{ID: credID, UserID: userID, Login: "old", Archived: true}}, nil
+				}
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: ListResponse{Credentials: []*Credential{
+				{ID: credID, Login: "old", Archived: true},
+			}},
+		},
+		{
+			name:  "unknown include",
+			query: "?include=deleted",
+			setupContext: func(c *gin.Context) {
+				c.Set("userID", userID)
+			},
+			mockSetup:      func(m *mockService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   response.DefaultBadRequestError,
+		},
+		{
+			name:  "manual order",
+			query: "?sort=manual&folder=prod/db",
+			setupContext: func(c *gin.Context) {
+				c.Set("userID", userID)
+			},
+			mockSetup: func(m *mockService) {
+				m.listFunc = func(ctx context.Context, params credential.ListParams) ([]*credential.Credential, error) {
+					assert.True(t, params.ManualOrder)
+					assert.Equal(t, "prod/db", params.OrderFolder)
+					return []*credential.Credential{{ID: credID, UserID: userID, Login: "pinned"}}, nil
+				}
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: ListResponse{Credentials: []*Credential{
+				{ID: credID, Login: "pinned"},
+			}},
+		},
+		{
+			name:  "unknown sort",
+			query: "?sort=login",
+			setupContext: func(c *gin.Context) {
+				c.Set("userID", userID)
+			},
+			mockSetup:      func(m *mockService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   response.DefaultBadRequestError,
+		},
 		{
 			name: "missing user ID",
 			setupContext: func(c *gin.Context) {
@@ -299,7 +416,7 @@ func TestHandler_List(t *testing.T) {
 			w := httptest.NewRecorder()
 			c, _ := gin.CreateTestContext(w)
 
-			req := httptest.NewRequest(http.MethodGet, "/credentials", nil)
+			req := httptest.NewRequest(http.MethodGet, "/credentials"+tt.query, nil)
 			c.Request = req
 
 			tt.setupContext(c)
@@ -511,3 +628,198 @@ func TestHandler_Push(t *testing.T) {
 		})
 	}
 }
+
+func TestHandler_AsOfEndpoints(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+
+	userID := uuid.New()
+	itemID := uuid.New()
+	asOf := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	validQuery := "?timestamp=2024-01-02T03:04:05Z"
+
+	tests := []struct {
+		serviceErr     error
+		name           string
+		urlParam       string
+		query          string
+		setUser        bool
+		expectedStatus int
+	}{
+		{
+			name:           "success",
+			setUser:        true,
+			urlParam:       itemID.String(),
+			query:          validQuery,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "missing user ID",
+			urlParam:       itemID.String(),
+			query:          validQuery,
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:           "invalid UUID in path",
+			setUser:        true,
+			urlParam:       "invalid-uuid",
+			query:          validQuery,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "missing timestamp",
+			setUser:        true,
+			urlParam:       itemID.String(),
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid timestamp",
+			setUser:        true,
+			urlParam:       itemID.String(),
+			query:          "?timestamp=yesterday",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "version not found",
+			setUser:        true,
+			urlParam:       itemID.String(),
+			query:          validQuery,
+			serviceErr:     credential.ErrCredentialNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			checkParams := func(gotAsOf time.Time, gotID, gotUserID uuid.UUID) {
+				assert.True(t, asOf.Equal(gotAsOf))
+				assert.Equal(t, itemID, gotID)
+				assert.Equal(t, userID, gotUserID)
+			}
+			mockSvc := &mockService{
+				pullFunc: func(_ context.Context, params credential.PullParams) (*credential.Credential, error) {
+					checkParams(params.AsOf, params.ID, params.UserID)
+					if tt.serviceErr != nil {
+						return nil, tt.serviceErr
+					}
+					return &credential.Credential{ID: params.ID, UserID: params.UserID}, nil
+				},
+				recoverFunc: func(_ context.Context, params credential.RecoverParams) (*credential.Credential, error) {
+					checkParams(params.AsOf, params.ID, params.UserID)
+					if tt.serviceErr != nil {
+						return nil, tt.serviceErr
+					}
+					return &credential.Credential{ID: params.ID, UserID: params.UserID, UpdatedAt: time.Now()}, nil
+				},
+			}
+			handler := NewHandler(mockSvc)
+
+			endpoints := map[string]gin.HandlerFunc{
+				http.MethodGet:  handler.PullAsOf,
+				http.MethodPost: handler.Recover,
+			}
+			for method, handle := range endpoints {
+				w := httptest.NewRecorder()
+				c, _ := gin.CreateTestContext(w)
+				c.Request = httptest.NewRequest(method, "/"+tt.urlParam+"/as-of"+tt.query, nil)
+				c.Params = gin.Params{{Key: "id", Value: tt.urlParam}}
+				if tt.setUser {
+					c.Set("userID", userID)
+				}
+
+				handle(c)
+
+				assert.Equal(t, tt.expectedStatus, w.Code, method)
+			}
+		})
+	}
+}
+
+func TestHandler_Match(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+
+	userID := uuid.New()
+	credID := uuid.New()
+
+	tests := []struct {
+		matchFunc      func(ctx context.Context, params credential.MatchParams) ([]*credential.Credential, error)
+		name           string
+		query          string
+		setUser        bool
+		expectedStatus int
+	}{
+		{
+			name:    "matching credentials",
+			query:   "?uri=https%3A%2F%2Flogin.example.com",
+			setUser: true,
+			matchFunc: func(_ context.Context, params credential.MatchParams) ([]*credential.Credential, error) {
+				assert.Equal(t, credential.MatchParams{URI: "https://login.example.com", UserID: userID}, params)
+				return []*credential.Credential{{
+					ID:    credID,
+					Login: "user@example.com",
+					URIs:  []credential.URI{{Value: "example.com", Match: "base_domain"}},
+				}}, nil
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:    "no matching credentials",
+			query:   "?uri=https%3A%2F%2Fexample.net",
+			setUser: true,
+			matchFunc: func(context.Context, credential.MatchParams) ([]*credential.Credential, error) {
+				return []*credential.Credential{}, nil
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "missing URI",
+			setUser:        true,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:    "invalid URI",
+			query:   "?uri=https%3A%2F%2F",
+			setUser: true,
+			matchFunc: func(context.Context, credential.MatchParams) ([]*credential.Credential, error) {
+				return nil, credential.ErrCredentialIncorrectURI
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "missing user context",
+			query:          "?uri=example.com",
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/credentials/match"+tt.query, nil)
+			if tt.setUser {
+				c.Set("userID", userID)
+			}
+
+			NewHandler(&mockService{matchFunc: tt.matchFunc}).Match(c)
+			c.Writer.WriteHeaderNow()
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+			var resp MatchResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			require.Len(t, resp.Credentials, 1)
+			assert.Equal(t, credID, resp.Credentials[0].ID)
+			assert.Equal(t, []URI{{Value: "example.com", Match: "base_domain"}}, resp.Credentials[0].URIs)
+		})
+	}
+}
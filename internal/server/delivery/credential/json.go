@@ -0,0 +1,66 @@
+package credential
+
+import (
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/jsonenc"
+	"github.com/google/uuid"
+)
+
+// AppendJSON appends the JSON encoding of the credential, omitting zero fields like its struct tags.
+func (c *Credential) AppendJSON(dst []byte) []byte {
+	if c == nil {
+		return jsonenc.Null(dst)
+	}
+	dst = append(dst, '{')
+	if !c.UpdatedAt.IsZero() {
+		dst = jsonenc.Time(jsonenc.Key(dst, "updated_at"), c.UpdatedAt)
+	}
+	if c.Login != "" {
+		dst = jsonenc.String(jsonenc.Key(dst, "login"), c.Login)
+	}
+	if c.Password != "" {
+		dst = jsonenc.String(jsonenc.Key(dst, "password"), c.Password)
+	}
+	if c.Description != "" {
+		dst = jsonenc.String(jsonenc.Key(dst, "description"), c.Description)
+	}
+	if len(c.URIs) != 0 {
+		dst = jsonenc.Array(jsonenc.Key(dst, "uris"), c.URIs)
+	}
+	if c.ID != uuid.Nil {
+		dst = jsonenc.UUID(jsonenc.Key(dst, "id"), c.ID)
+	}
+	if c.Archived {
+		dst = jsonenc.Bool(jsonenc.Key(dst, "archived"), c.Archived)
+	}
+	return append(dst, '}')
+}
+
+// AppendJSON appends the JSON encoding of the list response.
+func (r *ListResponse) AppendJSON(dst []byte) []byte {
+	if r == nil {
+		return jsonenc.Null(dst)
+	}
+	dst = append(dst, '{')
+	dst = jsonenc.Array(jsonenc.Key(dst, "credentials"), r.Credentials)
+	return append(dst, '}')
+}
+
+// AppendJSON appends the JSON encoding of the URI.
+func (u URI) AppendJSON(dst []byte) []byte {
+	dst = append(dst, '{')
+	dst = jsonenc.String(jsonenc.Key(dst, "value"), u.Value)
+	if u.Match != "" {
+		dst = jsonenc.String(jsonenc.Key(dst, "match"), u.Match)
+	}
+	return append(dst, '}')
+}
+
+// AppendJSON appends the JSON encoding of the match response.
+func (r *MatchResponse) AppendJSON(dst []byte) []byte {
+	if r == nil {
+		return jsonenc.Null(dst)
+	}
+	dst = append(dst, '{')
+	dst = jsonenc.Array(jsonenc.Key(dst, "credentials"), r.Credentials)
+	return append(dst, '}')
+}
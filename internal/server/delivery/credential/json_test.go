@@ -0,0 +1,47 @@
+package credential
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListResponse_AppendJSON(t *testing.T) {
+	t.Parallel()
+
+	full := &Credential{
+		ID:          uuid.New(),
+		Login:       "user@example.com",
+		Password:    `p<a>s&s"w\o` + "\nrd",
+		Description: "Почта",
+		URIs:        []URI{{Value: "https://mail.example.com", Match: "host"}, {Value: "example.com"}},
+		Archived:    true,
+		UpdatedAt:   time.Date(2025, 5, 6, 7, 8, 9, 123456789, time.UTC),
+	}
+
+	tests := []struct {
+		resp *ListResponse
+		name string
+	}{
+		{name: "full", resp: &ListResponse{Credentials: []*Credential{full}}},
+		{name: "selected fields", resp: &ListResponse{Credentials: []*Credential{{ID: full.ID, Login: full.Login}}}},
+		{name: "zero credential", resp: &ListResponse{Credentials: []*Credential{{}, nil}}},
+		{name: "empty", resp: &ListResponse{Credentials: []*Credential{}}},
+		{name: "nil list", resp: &ListResponse{}},
+		{name: "nil", resp: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			want, err := json.Marshal(tt.resp)
+			require.NoError(t, err)
+			assert.Equal(t, string(want), string(tt.resp.AppendJSON(nil)))
+		})
+	}
+}
@@ -1,15 +1,22 @@
 package credential
 
-import "github.com/gin-gonic/gin"
+import (
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/util"
+	"github.com/gin-gonic/gin"
+)
 
 // RegisterRoutes configures credential endpoints in the router group.
-// Sets up CRUD operations: POST/GET for collections, GET/PUT for individual items.
-func RegisterRoutes(r *gin.RouterGroup, h *Handler) {
+// Sets up CRUD operations: POST/GET for collections, GET/PUT for individual items, and URI matching.
+// The reveal middleware runs on the routes returning the content of a single credential.
+func RegisterRoutes(r *gin.RouterGroup, h *Handler, reveal ...gin.HandlerFunc) {
 	credentialsGroup := r.Group("/credentials")
 	credentialsGroup.POST("", h.Push)
 	credentialsGroup.GET("", h.List)
+	credentialsGroup.GET("/match", h.Match)
 
 	credentialsIDGroup := credentialsGroup.Group("/:id")
-	credentialsIDGroup.GET("", h.Pull)
+	credentialsIDGroup.GET("", util.Chain(reveal, h.Pull)...)
 	credentialsIDGroup.PUT("", h.Push)
+	credentialsIDGroup.GET("/as-of", util.Chain(reveal, h.PullAsOf)...)
+	credentialsIDGroup.POST("/recover", h.Recover)
 }
@@ -134,8 +134,11 @@ func TestRegisterRoutes_RouteStructure(t *testing.T) {
 	}{
 		{http.MethodPost, "/api/v1/credentials"},
 		{http.MethodGet, "/api/v1/credentials"},
+		{http.MethodGet, "/api/v1/credentials/match"},
 		{http.MethodGet, "/api/v1/credentials/:id"},
 		{http.MethodPut, "/api/v1/credentials/:id"},
+		{http.MethodGet, "/api/v1/credentials/:id/as-of"},
+		{http.MethodPost, "/api/v1/credentials/:id/recover"},
 	}
 
 	// Verify all expected routes are registered
@@ -150,7 +153,7 @@ func TestRegisterRoutes_RouteStructure(t *testing.T) {
 		assert.True(t, found, "Expected route %s %s not found", expected.method, expected.path)
 	}
 
-	// Verify no unexpected routes are registered (should have exactly 4 routes)
+	// Verify no unexpected routes are registered (should have exactly 7 routes)
 	credentialRoutes := 0
 	for _, route := range routes {
 		if len(route.Path) > 13 && route.Path[:14] == "/api/v1/creden" {
@@ -1,11 +1,14 @@
 package datasync
 
 import (
+	"time"
+
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/datasync"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/bankcard"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/credential"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/filedata"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/note"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/response"
 	"github.com/google/uuid"
 )
 
@@ -21,6 +24,18 @@ type SyncPayload struct {
 	Files []*filedata.FileData `json:"files,omitzero"` // User's files
 }
 
+// AsOfRequest represents the request addressing the vault state at a past moment.
+type AsOfRequest struct {
+	// Timestamp contains the RFC 3339 moment whose vault state is addressed (required).
+	Timestamp time.Time `form:"timestamp" binding:"required" example:"2023-12-01T10:00:00Z"`
+}
+
+// ExportRequest represents the request exporting the vault to a file.
+type ExportRequest struct {
+	// Format contains the file format of the export (required); only kdbx is supported.
+	Format string `form:"format" binding:"required" example:"kdbx"`
+}
+
 // ToApp converts the delivery layer SyncPayload to application layer format.
 func (p *SyncPayload) ToApp(userID uuid.UUID) *datasync.SyncPayload {
 	if p == nil {
@@ -52,3 +67,202 @@ func NewSyncPayloadFromApp(sp *datasync.SyncPayload) *SyncPayload {
 func (p *SyncPayload) isEmpty() bool {
 	return len(p.BankCards) == 0 && len(p.Credentials) == 0 && len(p.Notes) == 0 && len(p.Files) == 0
 }
+
+// StreamLine represents one line of a streamed pull: one item, or the error that ended the stream.
+type StreamLine struct {
+	// BankCard contains the streamed bank card.
+	BankCard *bankcard.BankCard `json:"bankcard,omitzero"`
+	// Credential contains the streamed credential.
+	Credential *credential.Credential `json:"credential,omitzero"`
+	// Note contains the streamed note.
+	Note *note.Note `json:"note,omitzero"`
+	// File contains the metadata of the streamed file.
+	File *filedata.FileData `json:"file,omitzero"`
+	// Error describes the failure that ended the stream; it is only set on the last line.
+	Error *response.Error `json:"error,omitzero"`
+}
+
+// NewStreamLineFromApp converts an application layer stream item to a stream line.
+func NewStreamLineFromApp(item *datasync.StreamItem) *StreamLine {
+	if item == nil {
+		return nil
+	}
+	line := &StreamLine{
+		BankCard:   bankcard.NewBankCardFromApp(item.BankCard),
+		Credential: credential.NewCredentialFromApp(item.Credential),
+		Note:       note.NewNoteFromApp(item.Note),
+	}
+	if item.File != nil {
+		line.File = filedata.NewFileDataFromApp(item.File)
+	}
+	return line
+}
+
+const (
+	// captureMetadataField names the multipart form field carrying the CaptureRequest JSON.
+	captureMetadataField = "metadata"
+	// captureFilesField names the multipart form field carrying the captured files.
+	captureFilesField = "files"
+)
+
+// CaptureRequest represents the item and file metadata of a capture, sent as the metadata form field.
+// Exactly one of the bank card, credential and note is required.
+type CaptureRequest struct {
+	// BankCard contains the captured bank card.
+	BankCard *bankcard.BankCard `json:"bankcard,omitempty"`
+	// Credential contains the captured credential.
+	Credential *credential.Credential `json:"credential,omitempty"`
+	// Note contains the captured note.
+	Note *note.Note `json:"note,omitempty"`
+	// Files contains the metadata of the uploaded files in upload order; optional.
+	Files []*CaptureFile `json:"files,omitempty"`
+}
+
+// CaptureFile represents the metadata of a captured file.
+type CaptureFile struct {
+	// StorageKey is the storage key of the file; empty uses the uploaded file name.
+	StorageKey string `json:"storage_key" example:"passport-front.jpg"`
+	// Description is the user-provided description of the file content.
+	Description string `json:"description" example:"Passport, front page"`
+	// Folder is the path of an existing folder holding the file; empty for the root folder.
+	Folder string `json:"folder"      example:"documents/passport"`
+}
+
+// CaptureResponse represents the response after storing a capture.
+type CaptureResponse struct {
+	// FileIDs contains the IDs of the stored files in upload order.
+	FileIDs []uuid.UUID `json:"file_ids"`
+	// ItemID is the ID of the stored item.
+	ItemID uuid.UUID `json:"item_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+}
+
+// ToApp converts the capture metadata to an application layer payload without files.
+func (r *CaptureRequest) ToApp(userID uuid.UUID) *datasync.CapturePayload {
+	payload := &datasync.CapturePayload{UserID: userID}
+	if r.BankCard != nil {
+		payload.BankCard = r.BankCard.ToApp(userID)
+	}
+	if r.Credential != nil {
+		payload.Credential = r.Credential.ToApp(userID)
+	}
+	if r.Note != nil {
+		payload.Note = r.Note.ToApp(userID)
+	}
+	return payload
+}
+
+// file returns the metadata of the uploaded file at the position, empty when none was sent.
+func (r *CaptureRequest) file(i int) *CaptureFile {
+	if i < len(r.Files) && r.Files[i] != nil {
+		return r.Files[i]
+	}
+	return &CaptureFile{}
+}
+
+// NewCaptureResponseFromApp creates a capture response from the application layer result.
+func NewCaptureResponseFromApp(r *datasync.CaptureResult) *CaptureResponse {
+	return &CaptureResponse{ItemID: r.ItemID, FileIDs: r.FileIDs}
+}
+
+// ManifestItem describes the current revision of one vault item without its content.
+type ManifestItem struct {
+	// UpdatedAt contains the timestamp when the item was last modified.
+	UpdatedAt time.Time `json:"updated_at" example:"2023-12-01T10:00:00Z"`
+	// Type identifies the kind of the item: bankcard, credential, note or file.
+	Type string `json:"type"       example:"note"`
+	// Digest contains the hex-encoded SHA-256 digest of the item content.
+	Digest string `json:"digest"     example:"72ff6b02949dad95006c343e3db3150090d3afb49f6bbdb92fdc17607997a85c"`
+	// ID contains the unique identifier of the item.
+	ID uuid.UUID `json:"id"         example:"123e4567-e89b-12d3-a456-426614174000"`
+	// Version identifies the revision of the item and increases with every update.
+	Version int64 `json:"version"    example:"1701424800000000"`
+}
+
+// ManifestResponse represents the response containing the manifest of the user's vault.
+type ManifestResponse struct {
+	// Items contains an entry for every item of the vault, ordered by type and ID.
+	Items []*ManifestItem `json:"items"`
+}
+
+// NewManifestResponseFromApp creates a manifest response from the application layer manifest entries.
+func NewManifestResponseFromApp(entries []*datasync.ManifestEntry) *ManifestResponse {
+	items := make([]*ManifestItem, 0, len(entries))
+	for _, e := range entries {
+		items = append(items, &ManifestItem{
+			ID:        e.ID,
+			Type:      e.Type,
+			Version:   e.Version,
+			UpdatedAt: e.UpdatedAt,
+			Digest:    e.Digest,
+		})
+	}
+	return &ManifestResponse{Items: items}
+}
+
+// ReplayRequest represents an ordered batch of operations a client queued while offline.
+type ReplayRequest struct {
+	// Ops contains the operations in the order the client performed them, at most 1000.
+	Ops []*ReplayOp `json:"ops" binding:"required,max=1000"`
+}
+
+// ReplayOp represents one queued operation creating or updating a bank card, credential or note.
+// Exactly one of the bank card, credential and note is required; an item ID updates the item.
+type ReplayOp struct {
+	// BankCard contains the bank card to store.
+	BankCard *bankcard.BankCard `json:"bankcard,omitempty"`
+	// Credential contains the credential to store.
+	Credential *credential.Credential `json:"credential,omitempty"`
+	// Note contains the note to store.
+	Note *note.Note `json:"note,omitempty"`
+	// OpID is the client-assigned operation ID, unique within the batch (required).
+	OpID string `json:"op_id"                    example:"op-1"`
+	// AfterOp is the op ID of an earlier operation whose item this operation updates; optional.
+	AfterOp string `json:"after_op,omitempty"       example:"op-0"`
+	// ParentVersion is the item version the change is based on; required when the item ID is set.
+	ParentVersion int64 `json:"parent_version,omitempty" example:"1701424800000000"`
+}
+
+// ReplayOutcome represents the result of one replayed operation.
+type ReplayOutcome struct {
+	// OpID is the ID of the operation the outcome belongs to.
+	OpID string `json:"op_id"            example:"op-1"`
+	// Status is one of applied, conflict, rejected and skipped.
+	Status string `json:"status"           example:"applied"`
+	// Messages contains the reasons a rejected operation failed.
+	Messages []string `json:"errors,omitempty"`
+	// ID is the ID of the stored item, or of the conflicting item on conflict.
+	ID uuid.UUID `json:"id,omitzero"      example:"123e4567-e89b-12d3-a456-426614174000"`
+	// Version is the version of the stored revision, or the current version on conflict.
+	Version int64 `json:"version,omitzero" example:"1701424800000000"`
+	// Code is the HTTP status code matching the rejection reason; zero unless the operation was rejected.
+	Code int `json:"code,omitzero"    example:"400"`
+}
+
+// ReplayResponse represents the outcomes of a replay in the order of the operations.
+type ReplayResponse struct {
+	// Outcomes contains the outcome of every operation.
+	Outcomes []*ReplayOutcome `json:"outcomes"`
+}
+
+// ToApp converts the replay request to an application layer payload.
+func (r *ReplayRequest) ToApp(userID uuid.UUID) *datasync.ReplayPayload {
+	ops := make([]*datasync.ReplayOp, 0, len(r.Ops))
+	for _, op := range r.Ops {
+		if op == nil {
+			ops = append(ops, nil)
+			continue
+		}
+		appOp := &datasync.ReplayOp{OpID: op.OpID, AfterOp: op.AfterOp, ParentVersion: op.ParentVersion}
+		if op.BankCard != nil {
+			appOp.BankCard = op.BankCard.ToApp(userID)
+		}
+		if op.Credential != nil {
+			appOp.Credential = op.Credential.ToApp(userID)
+		}
+		if op.Note != nil {
+			appOp.Note = op.Note.ToApp(userID)
+		}
+		ops = append(ops, appOp)
+	}
+	return &datasync.ReplayPayload{Ops: ops, UserID: userID}
+}
@@ -1,20 +1,132 @@
 package datasync
 
 import (
+	"net/http"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/datasync"
 	bankcarddel "github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/bankcard"
 	credentialdel "github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/credential"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/errutil"
 	filedatadel "github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/filedata"
+	itempolicydel "github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/itempolicy"
 	notedel "github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/note"
+	plandel "github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/plan"
 	"github.com/gin-gonic/gin"
 )
 
+// CaptureErrRegistry defines error handling policies for capture validation.
+var CaptureErrRegistry = errutil.Registry{
+	{
+		ErrorIn: datasync.ErrCaptureItemRequired,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusBadRequest,
+			PublicMsg:  "Exactly one of bankcard, credential and note is required",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+	{
+		ErrorIn: datasync.ErrCaptureFilesRequired,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusBadRequest,
+			PublicMsg:  "At least one file is required",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+}
+
+// ReplayErrRegistry defines error handling policies for replay validation.
+var ReplayErrRegistry = errutil.Registry{
+	{
+		ErrorIn: datasync.ErrReplayOpsRequired,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusBadRequest,
+			PublicMsg:  "At least one operation is required",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+	{
+		ErrorIn: datasync.ErrReplayOpIDInvalid,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusBadRequest,
+			PublicMsg:  "Every operation requires an op ID unique within the batch",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+	{
+		ErrorIn: datasync.ErrReplayItemRequired,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusBadRequest,
+			PublicMsg:  "Exactly one of bankcard, credential and note is required",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+	{
+		ErrorIn: datasync.ErrReplayParentVersionRequired,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusBadRequest,
+			PublicMsg:  "A parent version is required to update an item",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+}
+
+// ExportErrRegistry defines error handling policies for vault exports.
+var ExportErrRegistry = errutil.Registry{
+	{
+		ErrorIn: datasync.ErrExportFormatUnsupported,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusBadRequest,
+			PublicMsg:  "Unsupported export format; supported formats: kdbx",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+	{
+		ErrorIn: datasync.ErrExportPasswordRequired,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusBadRequest,
+			PublicMsg:  "The X-Export-Password header with the master password or X-Export-Recipient is required",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+	{
+		ErrorIn: datasync.ErrExportRecipientInvalid,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusBadRequest,
+			PublicMsg:  "X-Export-Recipient must be an age recipient or a base64-encoded OpenPGP public key",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+}
+
 // DataSyncErrRegistry aggregates error registries from all data types for unified error handling.
 var DataSyncErrRegistry = errutil.Merge(
+	CaptureErrRegistry,
+	ReplayErrRegistry,
+	ExportErrRegistry,
 	bankcarddel.BankCardErrRegistry,
 	credentialdel.CredentialErrRegistry,
 	notedel.NoteErrRegistry,
 	filedatadel.FileDataErrRegistry,
+	plandel.PlanErrRegistry,
+	itempolicydel.ItemPolicyErrRegistry,
 )
 
 // handleError processes errors using the consolidated data sync error registry.
@@ -2,11 +2,19 @@ package datasync
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
 	"net/http"
+	"time"
 
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/datasync"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/filedata"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/jsonenc"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/response"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/util"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/securebytes"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
@@ -17,8 +25,33 @@ type Service interface {
 	Pull(context.Context, uuid.UUID) (*datasync.SyncPayload, error)
 	// Push accepts synchronized data from client and applies changes.
 	Push(context.Context, *datasync.SyncPayload) error
+	// PullAsOf retrieves the user's vault state at the given moment.
+	PullAsOf(context.Context, uuid.UUID, time.Time) (*datasync.SyncPayload, error)
+	// Capture stores one item together with its files in a single transaction.
+	Capture(context.Context, *datasync.CapturePayload) (*datasync.CaptureResult, error)
+	// Manifest retrieves the revision and content digest of every item of the user.
+	Manifest(context.Context, uuid.UUID) ([]*datasync.ManifestEntry, error)
+	// Replay applies the operations a client queued while offline and reports the outcome of each one.
+	Replay(context.Context, *datasync.ReplayPayload) ([]*datasync.ReplayOutcome, error)
+	// Stream passes every item of the user to the emit function one at a time while loading them.
+	Stream(ctx context.Context, userID uuid.UUID, emit func(*datasync.StreamItem) error) error
+	// Export encodes the vault of the user in a file encrypted with the master password or to a recipient.
+	Export(ctx context.Context, params datasync.ExportParams) (*datasync.ExportFile, error)
 }
 
+const (
+	// mimeNDJSON is the media type of newline-delimited JSON, one value per line.
+	mimeNDJSON = "application/x-ndjson"
+	// streamFlushSize is the amount of encoded lines buffered before a stream writes them to the client.
+	streamFlushSize = 32 << 10
+	// headerExportPassword carries the master password an export is encrypted with.
+	headerExportPassword = "X-Export-Password"
+	// headerExportRecipient carries the age recipient or OpenPGP public key an export is encrypted to.
+	headerExportRecipient = "X-Export-Recipient"
+	// exportFileName is the file name suggested for KDBX exports.
+	exportFileName = "aegis-vault.kdbx"
+)
+
 // Handler handles HTTP requests for data synchronization endpoints.
 type Handler struct {
 	// s is the data sync service used to process bulk operations.
@@ -32,13 +65,17 @@ func NewHandler(s Service) *Handler {
 
 // Pull retrieves all user data for synchronization.
 // @Summary      Pull all user data
-// @Description  Retrieves all user data (cards, credentials, notes, files) for synchronization
+// @Description  Retrieves all user data (cards, credentials, notes, files) for synchronization.
+// @Description  With Accept: application/x-ndjson the items are streamed while they are loaded, one StreamLine
+// @Description  per line, so the response of a large vault is never built in memory. A failure after the
+// @Description  first line is reported by a last line carrying only the error
 // .
 // @Tags         DataSync
 // @Accept       json
 // @Produce      json
+// @Produce      x-ndjson
 // @Security     BearerAuth
-// @Success      200 {object} SyncPayload "User data retrieved successfully"
+// @Success      200 {object} SyncPayload "User data retrieved successfully; a stream of StreamLine with NDJSON"
 // @Success      204 "No data found"
 // @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
 // @Failure      500 {object} response.Error "Internal server error"
@@ -53,6 +90,11 @@ func (h *Handler) Pull(c *gin.Context) {
 		return
 	}
 
+	if c.NegotiateFormat(gin.MIMEJSON, mimeNDJSON) == mimeNDJSON {
+		h.stream(c, userID)
+		return
+	}
+
 	payload, err := h.s.Pull(c, userID)
 	if err != nil {
 		code, msgs := handleError(err, c)
@@ -68,12 +110,210 @@ func (h *Handler) Pull(c *gin.Context) {
 		c.Data(http.StatusNoContent, "", nil)
 		return
 	}
-	c.JSON(http.StatusOK, resp)
+	c.Render(http.StatusOK, jsonenc.JSON{Value: resp})
+}
+
+// stream writes every item of the user as a line of newline-delimited JSON while the items are loaded.
+// Lines are buffered up to streamFlushSize and then written and flushed, so a client reading slowly blocks
+// the writes and with them the loading of further items. Once the first lines are written the status code
+// can no longer change, so a later failure is reported by a last line carrying the error.
+func (h *Handler) stream(c *gin.Context, userID uuid.UUID) {
+	// buf holds the encoded lines not written yet; started reports whether the response status was sent.
+	var (
+		buf     = make([]byte, 0, 2*streamFlushSize)
+		started bool
+	)
+	flush := func() error {
+		if !started {
+			c.Header("Content-Type", mimeNDJSON)
+			c.Status(http.StatusOK)
+			started = true
+		}
+		if _, err := c.Writer.Write(buf); err != nil {
+			return fmt.Errorf("failed to write stream: %w", err)
+		}
+		buf = buf[:0]
+		c.Writer.Flush()
+		return nil
+	}
+
+	err := h.s.Stream(c, userID, func(item *datasync.StreamItem) error {
+		buf = append(NewStreamLineFromApp(item).AppendJSON(buf), '\n')
+		if len(buf) < streamFlushSize {
+			return nil
+		}
+		return flush()
+	})
+	switch {
+	case err != nil && !started:
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+	case err != nil:
+		_, msgs := handleError(err, c)
+		buf = append((&StreamLine{Error: &response.Error{Messages: msgs}}).AppendJSON(buf), '\n')
+		_ = flush()
+	case len(buf) > 0:
+		_ = flush()
+	case !started:
+		c.Data(http.StatusNoContent, "", nil)
+	}
+}
+
+// Manifest retrieves the metadata of every vault item for reconciliation.
+// @Summary      Get vault manifest
+// @Description  Retrieves the ID, type, version, modification time and content digest of every item of the user
+// @Description  without the item content, so clients can fetch exactly the items that differ from their copy.
+// @Description  The digest is the SHA-256 of the item fields, each prefixed with its 4-byte big-endian length
+// .
+// @Tags         DataSync
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} ManifestResponse "Manifest retrieved successfully"
+// @Success      204 "No data found"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /items/sync/manifest [get]
+// .
+func (h *Handler) Manifest(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	userID, err := extractor.UserID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+
+	entries, err := h.s.Manifest(c, userID)
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	if len(entries) == 0 {
+		c.Data(http.StatusNoContent, "", nil)
+		return
+	}
+	c.Render(http.StatusOK, jsonenc.JSON{Value: NewManifestResponseFromApp(entries)})
+}
+
+// PullAsOf retrieves the user's vault as it was at the requested moment.
+// @Summary      Pull vault state at a past moment
+// @Description  Retrieves the versions of cards, credentials and notes that were current at the given moment.
+// @Description  Files are not versioned and are not included.
+// .
+// @Tags         DataSync
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        timestamp query string true "Moment to view (RFC 3339)" format(date-time)
+// @Success      200 {object} SyncPayload "Vault state retrieved successfully"
+// @Success      204 "No data found"
+// @Failure      400 {object} response.Error "Bad request - invalid timestamp format"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /items/vault/as-of [get]
+// .
+func (h *Handler) PullAsOf(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	userID, err := extractor.UserID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+
+	// req holds the deserialized query parameters selecting the moment.
+	var req AsOfRequest
+	if err := extractor.BindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	payload, err := h.s.PullAsOf(c, userID, req.Timestamp)
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	resp := NewSyncPayloadFromApp(payload)
+
+	if resp.isEmpty() {
+		c.Data(http.StatusNoContent, "", nil)
+		return
+	}
+	c.Render(http.StatusOK, jsonenc.JSON{Value: resp})
+}
+
+// Export exports the user's vault to a file.
+// @Summary      Export vault to a file
+// @Description  Exports every current item of the user, including file content, to a KeePass (KDBX 4) database
+// @Description  encrypted with the master password of the X-Export-Password header. Credentials, bank cards,
+// @Description  notes and files are placed in groups of their own; files keep their folder structure and are
+// @Description  attached to their entries. Passwords, card numbers, CVVs and note texts are protected fields.
+// @Description  With the X-Export-Recipient header the whole file is encrypted to an age X25519 recipient
+// @Description  (aegis-vault.kdbx.age) or a base64-encoded OpenPGP public key (aegis-vault.kdbx.gpg), and the
+// @Description  master password becomes optional: without it the database has an empty master password
+// .
+// @Tags         DataSync
+// @Produce      octet-stream
+// @Produce      json
+// @Security     BearerAuth
+// @Param        format query string true "File format" Enums(kdbx)
+// @Param        X-Export-Password header string false "Master password the exported file is encrypted with"
+// @Param        X-Export-Recipient header string false "age recipient or base64 OpenPGP key to encrypt the file to"
+// @Success      200 {file} binary "KDBX 4 database, encrypted to the recipient if any"
+// @Failure      400 {object} response.Error "Bad request - unsupported format, invalid recipient or no password"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /items/export [get]
+// .
+func (h *Handler) Export(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	userID, err := extractor.UserID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+
+	// req holds the deserialized query parameters selecting the format.
+	var req ExportRequest
+	if err := extractor.BindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	file, err := h.s.Export(c, datasync.ExportParams{
+		Password:  c.GetHeader(headerExportPassword),
+		Recipient: c.GetHeader(headerExportRecipient),
+		Format:    datasync.ExportFormat(req.Format),
+		UserID:    userID,
+	})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.Header("Content-Disposition", "attachment; filename=\""+exportFileName+file.Extension+"\"")
+	c.Data(http.StatusOK, "application/octet-stream", file.Data)
 }
 
 // Push synchronizes user data to the server.
 // @Summary      Push user data for synchronization
-// @Description  Uploads and syncs all user data (cards, credentials, notes, files)
+// @Description  Uploads and syncs all user data (cards, credentials, notes, files) in a single transaction:
+// @Description  either every item is stored or none is
 // .
 // @Tags         DataSync
 // @Accept       json
@@ -113,3 +353,174 @@ func (h *Handler) Push(c *gin.Context) {
 
 	c.Data(http.StatusNoContent, "", nil)
 }
+
+// Replay applies the operations a client queued while offline.
+// @Summary      Replay offline operations
+// @Description  Applies an ordered batch of client operations creating or updating cards, credentials and notes
+// @Description  and returns the outcome of every operation. An update is applied only while its parent_version
+// @Description  is the current item version, otherwise it is reported as a conflict. An operation with after_op
+// @Description  updates the item stored by that earlier operation and is skipped unless it was applied
+// .
+// @Tags         DataSync
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body ReplayRequest true "Operations to replay"
+// @Success      200 {object} ReplayResponse "Operations replayed; see the status of every operation"
+// @Failure      400 {object} response.Error "Bad request - invalid batch or op IDs"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /items/sync/replay [post]
+// .
+func (h *Handler) Replay(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	userID, err := extractor.UserID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+
+	// req holds the deserialized JSON request payload for the replay.
+	var req ReplayRequest
+	if err := extractor.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	outcomes, err := h.s.Replay(c, req.ToApp(userID))
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	resp := ReplayResponse{Outcomes: make([]*ReplayOutcome, 0, len(outcomes))}
+	for _, o := range outcomes {
+		out := &ReplayOutcome{OpID: o.OpID, Status: string(o.Status), ID: o.ID, Version: o.Version}
+		if o.Err != nil {
+			out.Code, out.Messages = handleError(o.Err, c)
+		}
+		resp.Outcomes = append(resp.Outcomes, out)
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// Capture stores one item together with its files, such as an identity document with front and back scans.
+// @Summary      Capture item with files
+// @Description  Uploads one bank card, credential or note together with its files in a single multipart request.
+// @Description  The item and every file are stored in a single transaction: either all of them are stored or none is.
+// @Description  File metadata is matched to the uploaded files by position; a missing storage key defaults to
+// @Description  the uploaded file name
+// .
+// @Tags         DataSync
+// @Accept       multipart/form-data
+// @Produce      json
+// @Security     BearerAuth
+// @Param        metadata formData string true "Item and file metadata as JSON (CaptureRequest)"
+// @Param        files formData file true "Files of the item, in the order of the file metadata"
+// @Success      201 {object} CaptureResponse "Item and files stored successfully"
+// @Failure      400 {object} response.Error "Bad request - invalid metadata, item or files"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
+// @Failure      404 {object} response.Error "Not found - folder not found"
+// @Failure      507 {object} response.Error "Insufficient storage - file storage quota exceeded"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /items/capture [post]
+// .
+func (h *Handler) Capture(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	userID, err := extractor.UserID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+
+	form, err := c.MultipartForm()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	// req holds the deserialized item and file metadata of the capture.
+	var req CaptureRequest
+	if err := json.Unmarshal([]byte(firstValue(form.Value[captureMetadataField])), &req); err != nil {
+		c.JSON(http.StatusBadRequest, response.Error{
+			Messages: []string{"Capture metadata must be a JSON object"},
+		})
+		return
+	}
+
+	headers := form.File[captureFilesField]
+	if len(req.Files) != 0 && len(req.Files) != len(headers) {
+		c.JSON(http.StatusBadRequest, response.Error{
+			Messages: []string{"File metadata must match the uploaded files"},
+		})
+		return
+	}
+
+	payload := req.ToApp(userID)
+	defer func() {
+		for _, f := range payload.Files {
+			securebytes.Wipe(f.Data)
+		}
+	}()
+	for i, fh := range headers {
+		fd, err := readCapturedFile(fh, req.file(i))
+		if err != nil {
+			_ = c.Error(err)
+			c.JSON(http.StatusBadRequest, response.Error{
+				Messages: []string{"Failed to read file content"},
+			})
+			return
+		}
+		fd.UserID = userID
+		payload.Files = append(payload.Files, fd)
+	}
+
+	result, err := h.s.Capture(c, payload)
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, NewCaptureResponseFromApp(result))
+}
+
+// readCapturedFile reads an uploaded file of a capture into an application file entity.
+func readCapturedFile(fh *multipart.FileHeader, meta *CaptureFile) (*filedata.FileData, error) {
+	file, err := fh.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open uploaded file %q: %w", fh.Filename, err)
+	}
+	defer func() { _ = file.Close() }()
+
+	content, err := io.ReadAll(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read uploaded file %q: %w", fh.Filename, err)
+	}
+
+	storageKey := meta.StorageKey
+	if storageKey == "" {
+		storageKey = fh.Filename
+	}
+	return &filedata.FileData{
+		StorageKey:  storageKey,
+		Description: meta.Description,
+		Folder:      meta.Folder,
+		Data:        content,
+	}, nil
+}
+
+// firstValue returns the first of the form values or an empty string when there is none.
+func firstValue(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
@@ -5,12 +5,18 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/bankcard"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/credential"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/datasync"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/filedata"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/note"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/consts"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -22,6 +28,68 @@ import (
 type mockSyncService struct {
 	pullFunc func(ctx context.Context, userID uuid.UUID) (*datasync.SyncPayload, error)
 	pushFunc func(ctx context.Context, payload *datasync.SyncPayload) error
+	asOfFunc func(ctx context.Context, userID uuid.UUID, asOf time.Time) (*datasync.SyncPayload, error)
+	captFunc func(ctx context.Context, payload *datasync.CapturePayload) (*datasync.CaptureResult, error)
+	mfstFunc func(ctx context.Context, userID uuid.UUID) ([]*datasync.ManifestEntry, error)
+	rplyFunc func(ctx context.Context, payload *datasync.ReplayPayload) ([]*datasync.ReplayOutcome, error)
+	strmFunc func(ctx context.Context, userID uuid.UUID, emit func(*datasync.StreamItem) error) error
+	exptFunc func(ctx context.Context, params datasync.ExportParams) (*datasync.ExportFile, error)
+}
+
+func (m *mockSyncService) Export(ctx context.Context, params datasync.ExportParams) (*datasync.ExportFile, error) {
+	if m.exptFunc != nil {
+		return m.exptFunc(ctx, params)
+	}
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockSyncService) Stream(
+	ctx context.Context,
+	userID uuid.UUID,
+	emit func(*datasync.StreamItem) error,
+) error {
+	if m.strmFunc != nil {
+		return m.strmFunc(ctx, userID, emit)
+	}
+	return nil
+}
+
+func (m *mockSyncService) Replay(
+	ctx context.Context,
+	payload *datasync.ReplayPayload,
+) ([]*datasync.ReplayOutcome, error) {
+	if m.rplyFunc != nil {
+		return m.rplyFunc(ctx, payload)
+	}
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockSyncService) Manifest(ctx context.Context, userID uuid.UUID) ([]*datasync.ManifestEntry, error) {
+	if m.mfstFunc != nil {
+		return m.mfstFunc(ctx, userID)
+	}
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockSyncService) Capture(
+	ctx context.Context,
+	payload *datasync.CapturePayload,
+) (*datasync.CaptureResult, error) {
+	if m.captFunc != nil {
+		return m.captFunc(ctx, payload)
+	}
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockSyncService) PullAsOf(
+	ctx context.Context,
+	userID uuid.UUID,
+	asOf time.Time,
+) (*datasync.SyncPayload, error) {
+	if m.asOfFunc != nil {
+		return m.asOfFunc(ctx, userID, asOf)
+	}
+	return nil, errors.New("not implemented")
 }
 
 func (m *mockSyncService) Pull(ctx context.Context, userID uuid.UUID) (*datasync.SyncPayload, error) {
@@ -165,6 +233,7 @@ func TestHandler_Pull(t *testing.T) {
 			gin.SetMode(gin.TestMode)
 			w := httptest.NewRecorder()
 			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/items/sync", http.NoBody)
 
 			tt.setupContext(c)
 
@@ -176,6 +245,509 @@ func TestHandler_Pull(t *testing.T) {
 	}
 }
 
+func TestHandler_PullStream(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	items := []*datasync.StreamItem{
+		{BankCard: &bankcard.BankCard{ID: uuid.New(), CardHolder: "John Doe"}},
+		{Credential: &credential.Credential{ID: uuid.New(), Login: "user"}},
+		{Note: &note.Note{ID: uuid.New(), Note: "Text"}},
+		{File: &filedata.FileData{ID: uuid.New(), StorageKey: "scan.pdf"}},
+	}
+	emitAll := func(items []*datasync.StreamItem, err error) func(
+		context.Context, uuid.UUID, func(*datasync.StreamItem) error,
+	) error {
+		return func(_ context.Context, uid uuid.UUID, emit func(*datasync.StreamItem) error) error {
+			assert.Equal(t, userID, uid)
+			for _, item := range items {
+				if err := emit(item); err != nil {
+					return err
+				}
+			}
+			return err
+		}
+	}
+	// longNotes hold more encoded lines than a stream buffers before writing them.
+	longNotes := make([]*datasync.StreamItem, 2*streamFlushSize/1024)
+	for i := range longNotes {
+		longNotes[i] = &datasync.StreamItem{Note: &note.Note{ID: uuid.New(), Note: strings.Repeat("n", 1024)}}
+	}
+
+	tests := []struct {
+		strmFunc       func(context.Context, uuid.UUID, func(*datasync.StreamItem) error) error
+		name           string
+		expectedKeys   []string
+		expectedStatus int
+		expectError    bool
+	}{
+		{
+			name:           "streams one line per item",
+			strmFunc:       emitAll(items, nil),
+			expectedStatus: http.StatusOK,
+			expectedKeys:   []string{"bankcard", "credential", "note", "file"},
+		},
+		{
+			name:           "no items",
+			strmFunc:       emitAll(nil, nil),
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "error before the first write",
+			strmFunc:       emitAll(items, errors.New("service error")),
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:           "error after the first write",
+			strmFunc:       emitAll(longNotes, errors.New("service error")),
+			expectedStatus: http.StatusOK,
+			expectError:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/items/sync", http.NoBody)
+			c.Request.Header.Set("Accept", mimeNDJSON)
+			c.Set(consts.CtxKeyUserID, userID)
+
+			handler := NewHandler(&mockSyncService{strmFunc: tt.strmFunc})
+			handler.Pull(c)
+
+			require.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+			assert.Equal(t, mimeNDJSON, w.Header().Get("Content-Type"))
+
+			lines := strings.Split(strings.TrimSuffix(w.Body.String(), "\n"), "\n")
+			if tt.expectError {
+				last := lines[len(lines)-1]
+				assert.JSONEq(t, `{"error":{"messages":["Internal Server Error"]}}`, last)
+				assert.Len(t, lines, len(longNotes)+1)
+				return
+			}
+			require.Len(t, lines, len(tt.expectedKeys))
+			for i, line := range lines {
+				// decoded holds the fields of one line.
+				var decoded map[string]json.RawMessage
+				require.NoError(t, json.Unmarshal([]byte(line), &decoded))
+				assert.Len(t, decoded, 1)
+				assert.Contains(t, decoded, tt.expectedKeys[i])
+			}
+		})
+	}
+}
+
+func TestHandler_PullAsOf(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	asOf := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	withNote := func(ctx context.Context, uid uuid.UUID, at time.Time) (*datasync.SyncPayload, error) {
+		assert.Equal(t, userID, uid)
+		assert.True(t, asOf.Equal(at))
+		return &datasync.SyncPayload{
+			UserID: uid,
+			Notes:  []*note.Note{{ID: uuid.New(), UserID: uid, Note: "Old note"}},
+		}, nil
+	}
+
+	tests := []struct {
+		mockService    *mockSyncService
+		name           string
+		query          string
+		setUser        bool
+		expectedStatus int
+	}{
+		{
+			name:           "successful pull with data",
+			query:          "?timestamp=2024-01-02T03:04:05Z",
+			setUser:        true,
+			mockService:    &mockSyncService{asOfFunc: withNote},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:    "empty vault at moment",
+			query:   "?timestamp=2024-01-02T03:04:05Z",
+			setUser: true,
+			mockService: &mockSyncService{
+				asOfFunc: func(context.Context, uuid.UUID, time.Time) (*datasync.SyncPayload, error) {
+					return &datasync.SyncPayload{}, nil
+				},
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "missing timestamp",
+			setUser:        true,
+			mockService:    &mockSyncService{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "missing user context",
+			query:          "?timestamp=2024-01-02T03:04:05Z",
+			mockService:    &mockSyncService{},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:    "service error",
+			query:   "?timestamp=2024-01-02T03:04:05Z",
+			setUser: true,
+			mockService: &mockSyncService{
+				asOfFunc: func(context.Context, uuid.UUID, time.Time) (*datasync.SyncPayload, error) {
+					return nil, errors.New("service error")
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/vault/as-of"+tt.query, nil)
+			if tt.setUser {
+				c.Set(consts.CtxKeyUserID, userID)
+			}
+
+			handler := NewHandler(tt.mockService)
+			handler.PullAsOf(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestHandler_Export(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	exported := func(_ context.Context, params datasync.ExportParams) (*datasync.ExportFile, error) {
+		assert.Equal(t, datasync.ExportParams{
+			Password: "master password",
+			Format:   datasync.ExportFormatKDBX,
+			UserID:   userID,
+		}, params)
+		return &datasync.ExportFile{Data: []byte("kdbx")}, nil
+	}
+	encrypted := func(_ context.Context, params datasync.ExportParams) (*datasync.ExportFile, error) {
+		assert.Equal(t, datasync.ExportParams{
+			Recipient: "age1recipient",
+			Format:    datasync.ExportFormatKDBX,
+			UserID:    userID,
+		}, params)
+		return &datasync.ExportFile{Data: []byte("age"), Extension: ".age"}, nil
+	}
+
+	tests := []struct {
+		mockService    *mockSyncService
+		name           string
+		query          string
+		password       string
+		recipient      string
+		wantBody       string
+		wantFileName   string
+		setUser        bool
+		expectedStatus int
+	}{
+		{
+			name:           "successful export",
+			query:          "?format=kdbx",
+			password:       "master password",
+			setUser:        true,
+			mockService:    &mockSyncService{exptFunc: exported},
+			wantBody:       "kdbx",
+			wantFileName:   "aegis-vault.kdbx",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "export to recipient",
+			query:          "?format=kdbx",
+			recipient:      "age1recipient",
+			setUser:        true,
+			mockService:    &mockSyncService{exptFunc: encrypted},
+			wantBody:       "age",
+			wantFileName:   "aegis-vault.kdbx.age",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:      "invalid recipient",
+			query:     "?format=kdbx",
+			recipient: "age1invalid",
+			setUser:   true,
+			mockService: &mockSyncService{
+				exptFunc: func(context.Context, datasync.ExportParams) (*datasync.ExportFile, error) {
+					return nil, datasync.ErrExportRecipientInvalid
+				},
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "missing format",
+			password:       "master password",
+			setUser:        true,
+			mockService:    &mockSyncService{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:    "unsupported format",
+			query:   "?format=csv",
+			setUser: true,
+			mockService: &mockSyncService{
+				exptFunc: func(context.Context, datasync.ExportParams) (*datasync.ExportFile, error) {
+					return nil, datasync.ErrExportFormatUnsupported
+				},
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:    "missing password",
+			query:   "?format=kdbx",
+			setUser: true,
+			mockService: &mockSyncService{
+				exptFunc: func(context.Context, datasync.ExportParams) (*datasync.ExportFile, error) {
+					return nil, datasync.ErrExportPasswordRequired
+				},
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "missing user context",
+			query:          "?format=kdbx",
+			mockService:    &mockSyncService{},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:     "service error",
+			query:    "?format=kdbx",
+			password: "master password",
+			setUser:  true,
+			mockService: &mockSyncService{
+				exptFunc: func(context.Context, datasync.ExportParams) (*datasync.ExportFile, error) {
+					return nil, errors.New("service error")
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/export"+tt.query, nil)
+			if tt.password != "" {
+				c.Request.Header.Set(headerExportPassword, tt.password)
+			}
+			if tt.recipient != "" {
+				c.Request.Header.Set(headerExportRecipient, tt.recipient)
+			}
+			if tt.setUser {
+				c.Set(consts.CtxKeyUserID, userID)
+			}
+
+			handler := NewHandler(tt.mockService)
+			handler.Export(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.wantBody != "" {
+				assert.Equal(t, tt.wantBody, w.Body.String())
+				assert.Equal(t, "attachment; filename=\""+tt.wantFileName+"\"", w.Header().Get("Content-Disposition"))
+			}
+		})
+	}
+}
+
+func TestHandler_Manifest(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	itemID := uuid.New()
+	updatedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		mockService    *mockSyncService
+		expectedBody   *ManifestResponse
+		name           string
+		setUser        bool
+		expectedStatus int
+	}{
+		{
+			name:    "successful manifest",
+			setUser: true,
+			mockService: &mockSyncService{
+				mfstFunc: func(_ context.Context, uid uuid.UUID) ([]*datasync.ManifestEntry, error) {
+					assert.Equal(t, userID, uid)
+					return []*datasync.ManifestEntry{
+						{ID: itemID, Type: "note", Version: 7, UpdatedAt: updatedAt, Digest: "abc"},
+					}, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: &ManifestResponse{Items: []*ManifestItem{
+				{ID: itemID, Type: "note", Version: 7, UpdatedAt: updatedAt, Digest: "abc"},
+			}},
+		},
+		{
+			name:    "empty vault",
+			setUser: true,
+			mockService: &mockSyncService{
+				mfstFunc: func(context.Context, uuid.UUID) ([]*datasync.ManifestEntry, error) {
+					return []*datasync.ManifestEntry{}, nil
+				},
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "missing user context",
+			mockService:    &mockSyncService{},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:    "service error",
+			setUser: true,
+			mockService: &mockSyncService{
+				mfstFunc: func(context.Context, uuid.UUID) ([]*datasync.ManifestEntry, error) {
+					return nil, errors.New("service error")
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/sync/manifest", nil)
+			if tt.setUser {
+				c.Set(consts.CtxKeyUserID, userID)
+			}
+
+			NewHandler(tt.mockService).Manifest(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != nil {
+				var got ManifestResponse
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+				assert.Equal(t, tt.expectedBody, &got)
+			}
+		})
+	}
+}
+
+func TestHandler_Replay(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	noteID := uuid.New()
+
+	tests := []struct {
+		mockService    *mockSyncService
+		expectedBody   *ReplayResponse
+		name           string
+		body           string
+		setUser        bool
+		expectedStatus int
+	}{
+		{
+			name:    "outcome per operation",
+			setUser: true,
+			body: `{"ops":[{"op_id":"op-1","note":{"note":"draft"}},` +
+				`{"op_id":"op-2","after_op":"op-1","note":{"note":"final"}},{"op_id":"op-3"}]}`,
+			mockService: &mockSyncService{
+				rplyFunc: func(_ context.Context, p *datasync.ReplayPayload) ([]*datasync.ReplayOutcome, error) {
+					assert.Equal(t, userID, p.UserID)
+					require.Len(t, p.Ops, 3)
+					assert.Equal(t, "draft", p.Ops[0].Note.Note)
+					assert.Equal(t, "op-1", p.Ops[1].AfterOp)
+					return []*datasync.ReplayOutcome{
+						{OpID: "op-1", Status: datasync.ReplayApplied, ID: noteID, Version: 7},
+						{OpID: "op-2", Status: datasync.ReplayConflict, ID: noteID, Version: 8},
+						{OpID: "op-3", Status: datasync.ReplayRejected, Err: datasync.ErrReplayItemRequired},
+					}, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: &ReplayResponse{Outcomes: []*ReplayOutcome{
+				{OpID: "op-1", Status: "applied", ID: noteID, Version: 7},
+				{OpID: "op-2", Status: "conflict", ID: noteID, Version: 8},
+				{
+					OpID:     "op-3",
+					Status:   "rejected",
+					Code:     http.StatusBadRequest,
+					Messages: []string{"Exactly one of bankcard, credential and note is required"},
+				},
+			}},
+		},
+		{
+			name:    "invalid batch",
+			setUser: true,
+			body:    `{"ops":[{"op_id":""}]}`,
+			mockService: &mockSyncService{
+				rplyFunc: func(context.Context, *datasync.ReplayPayload) ([]*datasync.ReplayOutcome, error) {
+					return nil, datasync.ErrReplayOpIDInvalid
+				},
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "malformed body",
+			setUser:        true,
+			body:           `{"ops":`,
+			mockService:    &mockSyncService{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "missing user context",
+			body:           `{"ops":[]}`,
+			mockService:    &mockSyncService{},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/sync/replay", bytes.NewBufferString(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			if tt.setUser {
+				c.Set(consts.CtxKeyUserID, userID)
+			}
+
+			NewHandler(tt.mockService).Replay(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != nil {
+				var got ReplayResponse
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+				assert.Equal(t, tt.expectedBody, &got)
+			}
+		})
+	}
+}
+
 func TestHandler_Push(t *testing.T) {
 	t.Parallel()
 
@@ -298,3 +870,117 @@ func TestHandleError(t *testing.T) {
 		})
 	}
 }
+
+func TestHandler_Capture(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	itemID, frontID, backID := uuid.New(), uuid.New(), uuid.New()
+
+	// file describes an uploaded part of the multipart request.
+	type file struct {
+		name    string
+		content string
+	}
+	scans := []file{{name: "front.jpg", content: "front"}, {name: "back.jpg", content: "back"}}
+
+	tests := []struct {
+		captFunc   func(ctx context.Context, payload *datasync.CapturePayload) (*datasync.CaptureResult, error)
+		name       string
+		metadata   string
+		files      []file
+		setUser    bool
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name: "note with scans",
+			metadata: `{"note":{"note":"Passport 1234"},"files":[` +
+				`{"storage_key":"passport/front.jpg","description":"Front","folder":"ids"},{}]}`,
+			files:   scans,
+			setUser: true,
+			captFunc: func(_ context.Context, p *datasync.CapturePayload) (*datasync.CaptureResult, error) {
+				assert.Equal(t, userID, p.UserID)
+				require.NotNil(t, p.Note)
+				assert.Equal(t, "Passport 1234", p.Note.Note)
+				assert.Nil(t, p.BankCard)
+				require.Len(t, p.Files, 2)
+				assert.Equal(t, "passport/front.jpg", p.Files[0].StorageKey)
+				assert.Equal(t, "Front", p.Files[0].Description)
+				assert.Equal(t, "ids", p.Files[0].Folder)
+				assert.Equal(t, []byte("front"), p.Files[0].Data)
+				assert.Equal(t, "back.jpg", p.Files[1].StorageKey)
+				assert.Equal(t, userID, p.Files[1].UserID)
+				return &datasync.CaptureResult{ItemID: itemID, FileIDs: []uuid.UUID{frontID, backID}}, nil
+			},
+			wantStatus: http.StatusCreated,
+			wantBody:   itemID.String(),
+		},
+		{
+			name:       "missing metadata",
+			files:      scans,
+			setUser:    true,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "Capture metadata must be a JSON object",
+		},
+		{
+			name:       "metadata not matching files",
+			metadata:   `{"note":{"note":"Passport"},"files":[{}]}`,
+			files:      scans,
+			setUser:    true,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "File metadata must match the uploaded files",
+		},
+		{
+			name:     "no item",
+			metadata: `{}`,
+			files:    scans,
+			setUser:  true,
+			captFunc: func(context.Context, *datasync.CapturePayload) (*datasync.CaptureResult, error) {
+				return nil, datasync.ErrCaptureItemRequired
+			},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "Exactly one of bankcard, credential and note is required",
+		},
+		{
+			name:       "missing user context",
+			metadata:   `{"note":{"note":"Passport"}}`,
+			files:      scans,
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+
+			var body bytes.Buffer
+			mw := multipart.NewWriter(&body)
+			if tt.metadata != "" {
+				require.NoError(t, mw.WriteField("metadata", tt.metadata))
+			}
+			for _, f := range tt.files {
+				part, err := mw.CreateFormFile("files", f.name)
+				require.NoError(t, err)
+				_, err = part.Write([]byte(f.content))
+				require.NoError(t, err)
+			}
+			require.NoError(t, mw.Close())
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/items/capture", &body)
+			c.Request.Header.Set("Content-Type", mw.FormDataContentType())
+			if tt.setUser {
+				c.Set(consts.CtxKeyUserID, userID)
+			}
+
+			NewHandler(&mockSyncService{captFunc: tt.captFunc}).Capture(c)
+
+			assert.Equal(t, tt.wantStatus, w.Code, w.Body.String())
+			assert.Contains(t, w.Body.String(), tt.wantBody)
+		})
+	}
+}
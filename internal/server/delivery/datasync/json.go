@@ -0,0 +1,77 @@
+package datasync
+
+import "github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/jsonenc"
+
+// AppendJSON appends the JSON encoding of the sync payload, omitting nil item lists like its struct tags.
+func (p *SyncPayload) AppendJSON(dst []byte) []byte {
+	if p == nil {
+		return jsonenc.Null(dst)
+	}
+	dst = append(dst, '{')
+	if p.BankCards != nil {
+		dst = jsonenc.Array(jsonenc.Key(dst, "bankcards"), p.BankCards)
+	}
+	if p.Credentials != nil {
+		dst = jsonenc.Array(jsonenc.Key(dst, "credentials"), p.Credentials)
+	}
+	if p.Notes != nil {
+		dst = jsonenc.Array(jsonenc.Key(dst, "notes"), p.Notes)
+	}
+	if p.Files != nil {
+		dst = jsonenc.Array(jsonenc.Key(dst, "files"), p.Files)
+	}
+	return append(dst, '}')
+}
+
+// AppendJSON appends the JSON encoding of the stream line, omitting nil fields like its struct tags.
+func (l *StreamLine) AppendJSON(dst []byte) []byte {
+	if l == nil {
+		return jsonenc.Null(dst)
+	}
+	dst = append(dst, '{')
+	if l.BankCard != nil {
+		dst = l.BankCard.AppendJSON(jsonenc.Key(dst, "bankcard"))
+	}
+	if l.Credential != nil {
+		dst = l.Credential.AppendJSON(jsonenc.Key(dst, "credential"))
+	}
+	if l.Note != nil {
+		dst = l.Note.AppendJSON(jsonenc.Key(dst, "note"))
+	}
+	if l.File != nil {
+		dst = l.File.AppendJSON(jsonenc.Key(dst, "file"))
+	}
+	if l.Error != nil {
+		dst = append(jsonenc.Key(dst, "error"), '{')
+		if l.Error.Code != "" {
+			dst = jsonenc.String(jsonenc.Key(dst, "code"), l.Error.Code)
+		}
+		dst = jsonenc.Strings(jsonenc.Key(dst, "messages"), l.Error.Messages)
+		dst = append(dst, '}')
+	}
+	return append(dst, '}')
+}
+
+// AppendJSON appends the JSON encoding of the manifest item.
+func (i *ManifestItem) AppendJSON(dst []byte) []byte {
+	if i == nil {
+		return jsonenc.Null(dst)
+	}
+	dst = append(dst, '{')
+	dst = jsonenc.Time(jsonenc.Key(dst, "updated_at"), i.UpdatedAt)
+	dst = jsonenc.String(jsonenc.Key(dst, "type"), i.Type)
+	dst = jsonenc.String(jsonenc.Key(dst, "digest"), i.Digest)
+	dst = jsonenc.UUID(jsonenc.Key(dst, "id"), i.ID)
+	dst = jsonenc.Int(jsonenc.Key(dst, "version"), i.Version)
+	return append(dst, '}')
+}
+
+// AppendJSON appends the JSON encoding of the manifest response.
+func (r *ManifestResponse) AppendJSON(dst []byte) []byte {
+	if r == nil {
+		return jsonenc.Null(dst)
+	}
+	dst = append(dst, '{')
+	dst = jsonenc.Array(jsonenc.Key(dst, "items"), r.Items)
+	return append(dst, '}')
+}
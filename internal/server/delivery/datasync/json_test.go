@@ -0,0 +1,147 @@
+package datasync
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/bankcard"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/credential"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/filedata"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/note"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/response"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testPayload returns a sync payload with n items of every kind.
+func testPayload(n int) *SyncPayload {
+	at := time.Date(2025, 5, 6, 7, 8, 9, 123456789, time.UTC)
+	p := &SyncPayload{
+		BankCards:   make([]*bankcard.BankCard, 0, n),
+		Credentials: make([]*credential.Credential, 0, n),
+		Notes:       make([]*note.Note, 0, n),
+		Files:       make([]*filedata.FileData, 0, n),
+	}
+	for i := range n {
+		p.BankCards = append(p.BankCards, &bankcard.BankCard{
+			ID: uuid.New(), CardNumber: "4242424242424242", CardHolder: "John Doe", ExpiryMonth: "12",
+			ExpiryYear: "2030", CVV: "123", UpdatedAt: at,
+		})
+		p.Credentials = append(p.Credentials, &credential.Credential{
+			ID: uuid.New(), Login: fmt.Sprintf("user%d@example.com", i), Password: "s3cr3t&<>", UpdatedAt: at,
+		})
+		p.Notes = append(p.Notes, &note.Note{ID: uuid.New(), Note: "Meeting notes\nwith ABC", UpdatedAt: at})
+		p.Files = append(p.Files, &filedata.FileData{
+			ID: uuid.New(), UserID: uuid.New(), StorageKey: "document.pdf", Data: []byte("content"), UpdatedAt: at,
+		})
+	}
+	return p
+}
+
+func TestSyncPayload_AppendJSON(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		payload *SyncPayload
+		name    string
+	}{
+		{name: "all kinds", payload: testPayload(3)},
+		{name: "only notes", payload: &SyncPayload{Notes: testPayload(1).Notes}},
+		{name: "empty lists", payload: &SyncPayload{Credentials: []*credential.Credential{}}},
+		{name: "no lists", payload: &SyncPayload{}},
+		{name: "nil", payload: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			want, err := json.Marshal(tt.payload)
+			require.NoError(t, err)
+			assert.Equal(t, string(want), string(tt.payload.AppendJSON(nil)))
+		})
+	}
+}
+
+func TestStreamLine_AppendJSON(t *testing.T) {
+	t.Parallel()
+
+	p := testPayload(1)
+	tests := []struct {
+		line *StreamLine
+		name string
+	}{
+		{name: "bank card", line: &StreamLine{BankCard: p.BankCards[0]}},
+		{name: "credential", line: &StreamLine{Credential: p.Credentials[0]}},
+		{name: "note", line: &StreamLine{Note: p.Notes[0]}},
+		{name: "file", line: &StreamLine{File: p.Files[0]}},
+		{name: "error", line: &StreamLine{Error: &response.Error{Messages: []string{"failed & <stopped>"}}}},
+		{name: "error with code", line: &StreamLine{Error: &response.Error{Code: "code", Messages: nil}}},
+		{name: "nil", line: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			want, err := json.Marshal(tt.line)
+			require.NoError(t, err)
+			assert.Equal(t, string(want), string(tt.line.AppendJSON(nil)))
+		})
+	}
+}
+
+func TestManifestResponse_AppendJSON(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		resp *ManifestResponse
+		name string
+	}{
+		{
+			name: "items",
+			resp: &ManifestResponse{Items: []*ManifestItem{{
+				ID:        uuid.New(),
+				Type:      "note",
+				Version:   1701424800000000,
+				UpdatedAt: time.Date(2023, 12, 1, 10, 0, 0, 0, time.UTC),
+				Digest:    "72ff6b02949dad95006c343e3db3150090d3afb49f6bbdb92fdc17607997a85c",
+			}, nil}},
+		},
+		{name: "empty", resp: &ManifestResponse{Items: []*ManifestItem{}}},
+		{name: "nil", resp: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			want, err := json.Marshal(tt.resp)
+			require.NoError(t, err)
+			assert.Equal(t, string(want), string(tt.resp.AppendJSON(nil)))
+		})
+	}
+}
+
+func BenchmarkSyncPayload_Encode(b *testing.B) {
+	payload := testPayload(10_000)
+
+	b.Run("encoding/json", func(b *testing.B) {
+		b.ReportAllocs()
+		for range b.N {
+			if _, err := json.Marshal(payload); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("jsonenc", func(b *testing.B) {
+		b.ReportAllocs()
+		buf := make([]byte, 0, 16<<20)
+		for range b.N {
+			buf = payload.AppendJSON(buf[:0])
+		}
+	})
+}
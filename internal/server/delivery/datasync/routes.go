@@ -1,10 +1,28 @@
 package datasync
 
-import "github.com/gin-gonic/gin"
+import (
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/util"
+	"github.com/gin-gonic/gin"
+)
 
 // RegisterRoutes registers data synchronization routes with the provided router group.
-func RegisterRoutes(r *gin.RouterGroup, h *Handler) {
+// The export middleware guards the endpoints returning the whole vault; the manifest carries no item content
+// and is not guarded.
+func RegisterRoutes(r *gin.RouterGroup, h *Handler, export ...gin.HandlerFunc) {
 	syncGroup := r.Group("/sync")
 	syncGroup.POST("", h.Push)
-	syncGroup.GET("", h.Pull)
+	syncGroup.POST("/replay", h.Replay)
+	syncGroup.GET("", util.Chain(export, h.Pull)...)
+	syncGroup.GET("/manifest", h.Manifest)
+
+	vaultGroup := r.Group("/vault")
+	vaultGroup.GET("/as-of", util.Chain(export, h.PullAsOf)...)
+
+	r.GET("/export", util.Chain(export, h.Export)...)
+}
+
+// RegisterCaptureRoutes registers the capture route storing an item together with its files with the provided
+// router group.
+func RegisterCaptureRoutes(r gin.IRouter, h *Handler) {
+	r.POST("/capture", h.Capture)
 }
@@ -1,6 +1,8 @@
 package datasync
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/gin-gonic/gin"
@@ -35,3 +37,35 @@ func TestRegisterRoutes(t *testing.T) {
 		})
 	}
 }
+
+func TestRegisterRoutes_Export(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	export := func(c *gin.Context) { c.AbortWithStatus(http.StatusUnauthorized) }
+
+	RegisterRoutes(router.Group("/items"), NewHandler(&mockSyncService{}), export)
+	RegisterCaptureRoutes(router.Group("/items"), NewHandler(&mockSyncService{}))
+
+	tests := []struct {
+		method     string
+		path       string
+		wantStatus int
+	}{
+		{method: http.MethodGet, path: "/items/sync", wantStatus: http.StatusUnauthorized},
+		{method: http.MethodGet, path: "/items/vault/as-of", wantStatus: http.StatusUnauthorized},
+		{method: http.MethodGet, path: "/items/export", wantStatus: http.StatusUnauthorized},
+		// The manifest carries no item content and is not guarded by the export middleware.
+		{method: http.MethodGet, path: "/items/sync/manifest", wantStatus: http.StatusInternalServerError},
+		// Pushes reach the handler, which fails without an authenticated user.
+		{method: http.MethodPost, path: "/items/sync", wantStatus: http.StatusInternalServerError},
+		{method: http.MethodPost, path: "/items/sync/replay", wantStatus: http.StatusInternalServerError},
+		{method: http.MethodPost, path: "/items/capture", wantStatus: http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+		assert.Equal(t, tt.wantStatus, w.Code, tt.method+" "+tt.path)
+	}
+}
@@ -0,0 +1,6 @@
+// Package dav provides the WebDAV endpoint of the encrypted file vault in the AegisVaultKeeper server.
+//
+// This package maps the files and folders of a user onto a WebDAV file system, so the vault can be
+// mounted as a network drive. Reads decrypt files on the fly and serve byte ranges; writes are stored
+// through the file data service and count against the storage quota.
+package dav
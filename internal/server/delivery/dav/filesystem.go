@@ -0,0 +1,490 @@
+package dav
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/fs"
+	"math"
+	"mime"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/filedata"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/securebytes"
+	"github.com/google/uuid"
+	"golang.org/x/net/webdav"
+)
+
+// listLimit is the page size used to list every file of a folder at once.
+const listLimit = math.MaxInt32
+
+var (
+	// errDeleteUnsupported indicates a removal, such as that of an overwriting move; files and folders cannot
+	// be deleted through WebDAV.
+	errDeleteUnsupported = errors.New("deleting files and folders is not supported")
+
+	// errIsDirectory indicates an attempt to write file content to a folder.
+	errIsDirectory = errors.New("is a directory")
+
+	// errReadOnly indicates an attempt to write to a file opened for reading.
+	errReadOnly = errors.New("file is opened for reading")
+
+	// errWriteOnly indicates an attempt to read from a file opened for writing.
+	errWriteOnly = errors.New("file is opened for writing")
+)
+
+// fileSystem exposes the files and folders of one user as a WebDAV file system.
+//
+// Folders map to file folders. A file appears inside its folder under the last segment of its storage
+// key; when several files share that name, the first one in storage key order is shown, and files named
+// like a folder are hidden. New and moved files are stored under their full WebDAV path.
+type fileSystem struct {
+	// s is the file data service backing the file system.
+	s Service
+	// err holds the last application error that has no file system equivalent, for the response status.
+	err error
+	// userID identifies the user whose files are exposed.
+	userID uuid.UUID
+}
+
+// Mkdir creates a folder; its parent folder must exist.
+func (fsys *fileSystem) Mkdir(ctx context.Context, name string, _ os.FileMode) error {
+	p := cleanPath(name)
+	if p == "" {
+		return &os.PathError{Op: "mkdir", Path: name, Err: fs.ErrExist}
+	}
+	_, err := fsys.s.CreateFolder(ctx, filedata.CreateFolderParams{Path: p, UserID: fsys.userID})
+	return fsys.fail("mkdir", name, err)
+}
+
+// OpenFile opens a file or folder for reading, or a file for writing. Written content is stored when
+// the file is closed; writes always replace the whole content and fail once the content exceeds the plan
+// or the storage quota of the user.
+func (fsys *fileSystem) OpenFile(ctx context.Context, name string, flag int, _ os.FileMode) (webdav.File, error) {
+	if flag&(os.O_WRONLY|os.O_RDWR) != 0 {
+		return fsys.create(ctx, name)
+	}
+
+	fi, err := fsys.stat(ctx, "open", name)
+	if err != nil {
+		return nil, err
+	}
+	if fi.dir {
+		return &dirFile{ctx: ctx, fsys: fsys, info: fi, path: cleanPath(name)}, nil
+	}
+
+	fd, err := fsys.s.Pull(ctx, filedata.PullParams{ID: fi.id, UserID: fsys.userID})
+	if err != nil {
+		return nil, fsys.fail("open", name, err)
+	}
+	fi.size = int64(len(fd.Data))
+	return &readFile{Reader: bytes.NewReader(fd.Data), data: fd.Data, info: fi}, nil
+}
+
+// RemoveAll rejects removals. DELETE is not routed to the file system, so only overwriting moves and copies
+// reach it.
+func (fsys *fileSystem) RemoveAll(context.Context, string) error {
+	return errDeleteUnsupported
+}
+
+// Rename moves a file or folder to a new path; the parent folder of the new path must exist.
+func (fsys *fileSystem) Rename(ctx context.Context, oldName, newName string) error {
+	fi, err := fsys.stat(ctx, "rename", oldName)
+	if err != nil {
+		return err
+	}
+	to := cleanPath(newName)
+	if fi.id == uuid.Nil || to == "" {
+		return &os.PathError{Op: "rename", Path: oldName, Err: fs.ErrPermission}
+	}
+
+	if fi.dir {
+		_, err = fsys.s.MoveFolder(ctx, filedata.MoveFolderParams{ID: fi.id, Path: to, UserID: fsys.userID})
+	} else {
+		folder, _ := splitPath(to)
+		_, err = fsys.s.MoveFile(ctx, filedata.MoveFileParams{
+			ID:         fi.id,
+			Folder:     folder,
+			StorageKey: to,
+			UserID:     fsys.userID,
+		})
+	}
+	return fsys.fail("rename", oldName, err)
+}
+
+// Stat describes a file or folder.
+func (fsys *fileSystem) Stat(ctx context.Context, name string) (os.FileInfo, error) {
+	fi, err := fsys.stat(ctx, "stat", name)
+	if err != nil {
+		return nil, err
+	}
+	return fi, nil
+}
+
+// create opens a file for writing, replacing the file shown under the name or adding a new one.
+func (fsys *fileSystem) create(ctx context.Context, name string) (webdav.File, error) {
+	p := cleanPath(name)
+	if p == "" {
+		return nil, &os.PathError{Op: "open", Path: name, Err: errIsDirectory}
+	}
+	folder, base := splitPath(p)
+
+	entries, err := fsys.readDir(ctx, folder)
+	if err != nil {
+		return nil, fsys.fail("open", name, err)
+	}
+
+	params := filedata.PushParams{
+		StorageKey: p,
+		Folder:     folder,
+		UserID:     fsys.userID,
+		AllowEmpty: true,
+	}
+	if fi := findEntry(entries, base); fi != nil {
+		if fi.dir {
+			return nil, &os.PathError{Op: "open", Path: name, Err: errIsDirectory}
+		}
+		params.ID = fi.id
+		params.StorageKey = fi.storageKey
+		params.Description = fi.description
+	}
+
+	limit, err := fsys.s.CheckUpload(ctx, &params, 0)
+	if err != nil {
+		return nil, fsys.fail("open", name, err)
+	}
+	return &writeFile{ctx: ctx, fsys: fsys, params: params, name: base, limit: limit}, nil
+}
+
+// stat resolves a WebDAV path to the file or folder shown under it.
+func (fsys *fileSystem) stat(ctx context.Context, op, name string) (*fileInfo, error) {
+	p := cleanPath(name)
+	if p == "" {
+		return &fileInfo{name: "/", dir: true}, nil
+	}
+	folder, base := splitPath(p)
+
+	entries, err := fsys.readDir(ctx, folder)
+	if err != nil {
+		return nil, fsys.fail(op, name, err)
+	}
+	if fi := findEntry(entries, base); fi != nil {
+		return fi, nil
+	}
+	return nil, &os.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+}
+
+// readDir lists the folders and files shown inside a folder, folders first.
+func (fsys *fileSystem) readDir(ctx context.Context, folder string) ([]*fileInfo, error) {
+	listing, err := fsys.s.ListFolder(ctx, filedata.ListFolderParams{
+		Folder: folder,
+		Limit:  listLimit,
+		UserID: fsys.userID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]*fileInfo, 0, len(listing.Folders)+len(listing.Files))
+	// seen holds the names already shown inside the folder.
+	seen := make(map[string]struct{}, cap(entries))
+	for _, f := range listing.Folders {
+		fi := &fileInfo{modTime: f.UpdatedAt, name: path.Base(f.Path), id: f.ID, dir: true}
+		seen[fi.name] = struct{}{}
+		entries = append(entries, fi)
+	}
+	for _, fd := range listing.Files {
+		fi := newFileInfo(fd)
+		if _, ok := seen[fi.name]; ok {
+			continue
+		}
+		seen[fi.name] = struct{}{}
+		entries = append(entries, fi)
+	}
+	return entries, nil
+}
+
+// fail converts an application error to the file system error WebDAV expects. Missing files and folders
+// become fs.ErrNotExist and existing folders fs.ErrExist; other errors are kept for the response status.
+func (fsys *fileSystem) fail(op, name string, err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, filedata.ErrFileNotFound), errors.Is(err, filedata.ErrFolderNotFound):
+		return &os.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+	case errors.Is(err, filedata.ErrFolderExists):
+		return &os.PathError{Op: op, Path: name, Err: fs.ErrExist}
+	default:
+		fsys.err = err
+		return err
+	}
+}
+
+// cleanPath converts a WebDAV path to a slash-separated path without surrounding slashes; the root is empty.
+func cleanPath(name string) string {
+	return strings.Trim(path.Clean("/"+name), "/")
+}
+
+// splitPath splits a cleaned path into the folder holding the entry and the name of the entry.
+func splitPath(p string) (string, string) {
+	i := strings.LastIndex(p, "/")
+	if i < 0 {
+		return "", p
+	}
+	return p[:i], p[i+1:]
+}
+
+// findEntry returns the entry with the name or nil when there is none.
+func findEntry(entries []*fileInfo, name string) *fileInfo {
+	for _, fi := range entries {
+		if fi.name == name {
+			return fi
+		}
+	}
+	return nil
+}
+
+// fileInfo describes a file or folder of the WebDAV file system.
+type fileInfo struct {
+	// modTime contains the last modification timestamp.
+	modTime time.Time
+	// name contains the name shown inside the parent folder.
+	name string
+	// storageKey contains the storage key of a file; empty for folders.
+	storageKey string
+	// description contains the description of a file, kept when the file is overwritten.
+	description string
+	// hashSum contains the SHA256 hash of the file content, used as the entity tag.
+	hashSum string
+	// size contains the size of the file content in bytes.
+	size int64
+	// id identifies the file or folder; uuid.Nil for the root folder.
+	id uuid.UUID
+	// dir reports whether the entry is a folder.
+	dir bool
+}
+
+// newFileInfo describes a stored file.
+func newFileInfo(fd *filedata.FileData) *fileInfo {
+	return &fileInfo{
+		modTime:     fd.UpdatedAt,
+		name:        path.Base(fd.StorageKey),
+		storageKey:  fd.StorageKey,
+		description: fd.Description,
+		hashSum:     fd.HashSum,
+		size:        fd.Size,
+		id:          fd.ID,
+	}
+}
+
+// Name returns the name shown inside the parent folder.
+func (fi *fileInfo) Name() string { return fi.name }
+
+// Size returns the size of the file content in bytes.
+func (fi *fileInfo) Size() int64 { return fi.size }
+
+// Mode returns the file mode bits.
+func (fi *fileInfo) Mode() os.FileMode {
+	if fi.dir {
+		return os.ModeDir | 0o700
+	}
+	return 0o600
+}
+
+// ModTime returns the last modification timestamp.
+func (fi *fileInfo) ModTime() time.Time { return fi.modTime }
+
+// IsDir reports whether the entry is a folder.
+func (fi *fileInfo) IsDir() bool { return fi.dir }
+
+// Sys returns nil; there is no underlying data source.
+func (fi *fileInfo) Sys() any { return nil }
+
+// ETag returns the content hash as the entity tag, so listings need not decrypt files.
+func (fi *fileInfo) ETag(context.Context) (string, error) {
+	if fi.hashSum == "" {
+		return "", webdav.ErrNotImplemented
+	}
+	return `"` + fi.hashSum + `"`, nil
+}
+
+// ContentType returns the media type derived from the file name extension, so listings need not
+// decrypt files.
+func (fi *fileInfo) ContentType(context.Context) (string, error) {
+	if ct := mime.TypeByExtension(path.Ext(fi.name)); ct != "" {
+		return ct, nil
+	}
+	return "application/octet-stream", nil
+}
+
+// dirFile is a folder opened for reading.
+type dirFile struct {
+	// ctx is the request context used to list the folder.
+	ctx context.Context
+	// fsys is the file system the folder belongs to.
+	fsys *fileSystem
+	// info describes the folder.
+	info *fileInfo
+	// path contains the cleaned path of the folder.
+	path string
+	// entries holds the entries not yet returned by Readdir; nil until the folder is listed.
+	entries []*fileInfo
+	// listed reports whether the folder has been listed.
+	listed bool
+}
+
+// Close releases the folder.
+func (f *dirFile) Close() error { return nil }
+
+// Read fails; folders have no content.
+func (f *dirFile) Read([]byte) (int, error) {
+	return 0, &os.PathError{Op: "read", Path: f.info.name, Err: errIsDirectory}
+}
+
+// Seek fails; folders have no content.
+func (f *dirFile) Seek(int64, int) (int64, error) {
+	return 0, &os.PathError{Op: "seek", Path: f.info.name, Err: errIsDirectory}
+}
+
+// Write fails; folders have no content.
+func (f *dirFile) Write([]byte) (int, error) {
+	return 0, &os.PathError{Op: "write", Path: f.info.name, Err: errIsDirectory}
+}
+
+// Stat describes the folder.
+func (f *dirFile) Stat() (os.FileInfo, error) { return f.info, nil }
+
+// Readdir returns the next count entries of the folder, or all remaining ones when count is not positive.
+func (f *dirFile) Readdir(count int) ([]os.FileInfo, error) {
+	if !f.listed {
+		entries, err := f.fsys.readDir(f.ctx, f.path)
+		if err != nil {
+			return nil, f.fsys.fail("readdir", f.info.name, err)
+		}
+		f.entries, f.listed = entries, true
+	}
+
+	n := len(f.entries)
+	if count > 0 {
+		if n == 0 {
+			return nil, io.EOF
+		}
+		n = min(n, count)
+	}
+	out := make([]os.FileInfo, 0, n)
+	for _, fi := range f.entries[:n] {
+		out = append(out, fi)
+	}
+	f.entries = f.entries[n:]
+	return out, nil
+}
+
+// readFile is a file opened for reading, holding its decrypted content.
+type readFile struct {
+	*bytes.Reader
+	// info describes the file.
+	info *fileInfo
+	// data holds the decrypted content, wiped when the file is closed.
+	data []byte
+}
+
+// Close wipes the decrypted content.
+func (f *readFile) Close() error {
+	securebytes.Wipe(f.data)
+	return nil
+}
+
+// Readdir fails; files have no entries.
+func (f *readFile) Readdir(int) ([]os.FileInfo, error) {
+	return nil, &os.PathError{Op: "readdir", Path: f.info.name, Err: fs.ErrInvalid}
+}
+
+// Stat describes the file.
+func (f *readFile) Stat() (os.FileInfo, error) { return f.info, nil }
+
+// Write fails; the file is opened for reading.
+func (f *readFile) Write([]byte) (int, error) {
+	return 0, &os.PathError{Op: "write", Path: f.info.name, Err: errReadOnly}
+}
+
+// writeFile is a file opened for writing, buffering its content until it is closed.
+type writeFile struct {
+	// ctx is the request context used to store the file.
+	ctx context.Context
+	// fsys is the file system the file belongs to.
+	fsys *fileSystem
+	// err holds the error that aborted a write; the content of an aborted file is not stored.
+	err error
+	// name contains the name of the file inside its folder.
+	name string
+	// buf collects the written content.
+	buf bytes.Buffer
+	// params holds the parameters the content is stored with.
+	params filedata.PushParams
+	// limit is the largest content in bytes the plan and the quota accepted; negative when unbounded.
+	limit int64
+}
+
+// Close stores the written content and wipes the buffer; the content of an aborted write is discarded.
+func (f *writeFile) Close() error {
+	data := f.buf.Bytes()
+	defer securebytes.Wipe(data)
+	if f.err != nil {
+		return f.err
+	}
+
+	f.params.Data = data
+	_, err := f.fsys.s.Push(f.ctx, &f.params)
+	return f.fsys.fail("close", f.name, err)
+}
+
+// Read fails; the file is opened for writing.
+func (f *writeFile) Read([]byte) (int, error) {
+	return 0, &os.PathError{Op: "read", Path: f.name, Err: errWriteOnly}
+}
+
+// Readdir fails; files have no entries.
+func (f *writeFile) Readdir(int) ([]os.FileInfo, error) {
+	return nil, &os.PathError{Op: "readdir", Path: f.name, Err: fs.ErrInvalid}
+}
+
+// Seek fails; the content is written sequentially.
+func (f *writeFile) Seek(int64, int) (int64, error) {
+	return 0, &os.PathError{Op: "seek", Path: f.name, Err: errWriteOnly}
+}
+
+// Stat describes the file with the content written so far.
+func (f *writeFile) Stat() (os.FileInfo, error) {
+	hash := sha256.Sum256(f.buf.Bytes())
+	return &fileInfo{
+		modTime: time.Now(),
+		name:    f.name,
+		hashSum: hex.EncodeToString(hash[:]),
+		size:    int64(f.buf.Len()),
+		id:      f.params.ID,
+	}, nil
+}
+
+// Write appends to the buffered content. Content outgrowing the accepted limit is checked again, and the
+// write is aborted once the plan or the quota rejects it, so an oversized upload is never buffered whole.
+func (f *writeFile) Write(p []byte) (int, error) {
+	if f.err != nil {
+		return 0, f.err
+	}
+	size := int64(f.buf.Len() + len(p))
+	if f.limit >= 0 && size > f.limit {
+		limit, err := f.fsys.s.CheckUpload(f.ctx, &f.params, size)
+		if err != nil {
+			f.err = f.fsys.fail("write", f.name, err)
+			return 0, f.err
+		}
+		f.limit = limit
+	}
+	return f.buf.Write(p)
+}
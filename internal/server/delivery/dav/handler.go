@@ -0,0 +1,121 @@
+package dav
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/filedata"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/errutil"
+	filedataDelivery "github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/filedata"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/response"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/util"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"golang.org/x/net/webdav"
+)
+
+// Service defines the file data application service interface used by the WebDAV endpoint.
+type Service interface {
+	// Pull retrieves a specific file by ID for the authenticated user.
+	Pull(context.Context, filedata.PullParams) (*filedata.FileData, error)
+	// Push uploads and stores a new file for the authenticated user.
+	Push(context.Context, *filedata.PushParams) (uuid.UUID, error)
+	// MoveFile moves a file of the authenticated user to another folder or renames it.
+	MoveFile(context.Context, filedata.MoveFileParams) (*filedata.FileData, error)
+	// ListFolder retrieves the subfolders and a page of the files of a folder of the authenticated user.
+	ListFolder(context.Context, filedata.ListFolderParams) (*filedata.FolderListing, error)
+	// CreateFolder creates a folder for the authenticated user.
+	CreateFolder(context.Context, filedata.CreateFolderParams) (*filedata.Folder, error)
+	// MoveFolder renames or moves a folder of the authenticated user together with its contents.
+	MoveFolder(context.Context, filedata.MoveFolderParams) (*filedata.Folder, error)
+	// CheckUpload ensures that the plan and quota accept an upload of the given size and returns the largest
+	// size they accept, negative when unbounded.
+	CheckUpload(context.Context, *filedata.PushParams, int64) (int64, error)
+}
+
+// Handler handles WebDAV requests for the file vault of the authenticated user.
+type Handler struct {
+	// s is the file data service used to process file operations.
+	s Service
+	// locks holds the in-memory WebDAV lock system of each user, keyed by user ID.
+	locks sync.Map
+}
+
+// NewHandler creates a new WebDAV handler with the provided service.
+func NewHandler(s Service) *Handler {
+	return &Handler{s: s}
+}
+
+// ServeDAV serves a WebDAV request below the route of the handler. Files are read with byte range
+// support and written as a whole; failed writes answer with the status of the application error,
+// such as 507 Insufficient Storage when the storage quota is exceeded.
+func (h *Handler) ServeDAV(c *gin.Context) {
+	userID, err := util.NewCtxExtractor(c).UserID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+
+	fsys := &fileSystem{s: h.s, userID: userID}
+	dav := &webdav.Handler{
+		Prefix:     strings.TrimSuffix(c.FullPath(), "/*"+pathParam),
+		FileSystem: fsys,
+		LockSystem: h.lockSystem(userID),
+	}
+	dav.ServeHTTP(&statusWriter{ResponseWriter: c.Writer, c: c, fsys: fsys}, c.Request)
+}
+
+// RejectDelete answers DELETE requests with 405 Method Not Allowed; files and folders cannot be deleted
+// through WebDAV.
+func (h *Handler) RejectDelete(c *gin.Context) {
+	c.Header("Allow", strings.Join(methods, ", "))
+	c.JSON(http.StatusMethodNotAllowed, response.Error{
+		Messages: []string{"Deleting files and folders is not supported"},
+	})
+}
+
+// lockSystem returns the lock system of the user, creating it on first use.
+func (h *Handler) lockSystem(userID uuid.UUID) webdav.LockSystem {
+	if ls, ok := h.locks.Load(userID); ok {
+		return ls.(webdav.LockSystem)
+	}
+	ls, _ := h.locks.LoadOrStore(userID, webdav.NewMemLS())
+	return ls.(webdav.LockSystem)
+}
+
+// statusWriter replaces the generic error statuses the WebDAV library answers failed file operations
+// with the status and message the file data error registry assigns to the application error.
+type statusWriter struct {
+	http.ResponseWriter
+	// c is the request context errors are logged to.
+	c *gin.Context
+	// fsys is the file system holding the application error of the request.
+	fsys *fileSystem
+	// replaced reports whether the status was replaced, so the library's own body is dropped.
+	replaced bool
+}
+
+// WriteHeader writes the status, replacing error statuses caused by an application error.
+func (w *statusWriter) WriteHeader(code int) {
+	if code < http.StatusBadRequest || w.fsys.err == nil || w.replaced {
+		w.ResponseWriter.WriteHeader(code)
+		return
+	}
+
+	status, msgs := errutil.HandleWithRegistry(filedataDelivery.FileDataErrRegistry, w.fsys.err, w.c)
+	w.replaced = true
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.ResponseWriter.WriteHeader(status)
+	_, _ = io.WriteString(w.ResponseWriter, strings.Join(msgs, "; "))
+}
+
+// Write writes the body unless the status was replaced.
+func (w *statusWriter) Write(b []byte) (int, error) {
+	if w.replaced {
+		return len(b), nil
+	}
+	return w.ResponseWriter.Write(b)
+}
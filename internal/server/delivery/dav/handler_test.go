@@ -0,0 +1,440 @@
+package dav
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/filedata"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/consts"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeService keeps the files and folders of a user in memory.
+type fakeService struct {
+	pushErr  error
+	pushed   *filedata.PushParams
+	moved    *filedata.MoveFileParams
+	created  *filedata.CreateFolderParams
+	folders  []*filedata.Folder
+	files    []*filedata.FileData
+	moveDirs []filedata.MoveFolderParams
+	checks   []int64
+	quota    int64
+}
+
+func newFakeService() *fakeService {
+	return &fakeService{
+		folders: []*filedata.Folder{{ID: uuid.New(), Path: "docs", UpdatedAt: time.Now()}},
+		files: []*filedata.FileData{
+			{ID: uuid.New(), StorageKey: "docs", HashSum: "h0", Data: []byte("hidden")},
+			{ID: uuid.New(), StorageKey: "old/a.txt", HashSum: "h1", Description: "kept", Data: []byte("hello")},
+			{ID: uuid.New(), StorageKey: "other/a.txt", HashSum: "h2", Data: []byte("shadowed")},
+			{ID: uuid.New(), StorageKey: "docs/b.pdf", Folder: "docs", HashSum: "h3", Data: []byte("pdf")},
+		},
+	}
+}
+
+func (s *fakeService) Pull(_ context.Context, p filedata.PullParams) (*filedata.FileData, error) {
+	for _, fd := range s.files {
+		if fd.ID == p.ID {
+			out := *fd
+			out.Data = append([]byte(nil), fd.Data...)
+			return &out, nil
+		}
+	}
+	return nil, fmt.Errorf("pull %s: %w", p.ID, filedata.ErrFileNotFound)
+}
+
+func (s *fakeService) Push(_ context.Context, p *filedata.PushParams) (uuid.UUID, error) {
+	pushed := *p
+	pushed.Data = append([]byte(nil), p.Data...)
+	s.pushed = &pushed
+	return uuid.New(), s.pushErr
+}
+
+func (s *fakeService) MoveFile(_ context.Context, p filedata.MoveFileParams) (*filedata.FileData, error) {
+	s.moved = &p
+	return &filedata.FileData{ID: p.ID}, nil
+}
+
+func (s *fakeService) ListFolder(_ context.Context, p filedata.ListFolderParams) (*filedata.FolderListing, error) {
+	if p.Folder != "" && !s.hasFolder(p.Folder) {
+		return nil, fmt.Errorf("list %q: %w", p.Folder, filedata.ErrFolderNotFound)
+	}
+	listing := &filedata.FolderListing{Folder: p.Folder}
+	for _, f := range s.folders {
+		if parent, _ := splitPath(f.Path); parent == p.Folder {
+			listing.Folders = append(listing.Folders, f)
+		}
+	}
+	for _, fd := range s.files {
+		if fd.Folder == p.Folder {
+			meta := *fd
+			meta.Data = nil
+			meta.Size = int64(len(fd.Data))
+			listing.Files = append(listing.Files, &meta)
+		}
+	}
+	listing.Total = len(listing.Files)
+	return listing, nil
+}
+
+func (s *fakeService) CreateFolder(_ context.Context, p filedata.CreateFolderParams) (*filedata.Folder, error) {
+	if s.hasFolder(p.Path) {
+		return nil, fmt.Errorf("create %q: %w", p.Path, filedata.ErrFolderExists)
+	}
+	if strings.Contains(p.Path, " ") {
+		return nil, fmt.Errorf("create %q: %w", p.Path, filedata.ErrFileIncorrectFolder)
+	}
+	s.created = &p
+	return &filedata.Folder{ID: uuid.New(), Path: p.Path}, nil
+}
+
+func (s *fakeService) MoveFolder(_ context.Context, p filedata.MoveFolderParams) (*filedata.Folder, error) {
+	s.moveDirs = append(s.moveDirs, p)
+	return &filedata.Folder{ID: p.ID, Path: p.Path}, nil
+}
+
+func (s *fakeService) CheckUpload(_ context.Context, _ *filedata.PushParams, size int64) (int64, error) {
+	s.checks = append(s.checks, size)
+	if s.quota <= 0 {
+		return -1, nil
+	}
+	if size > s.quota {
+		return 0, fmt.Errorf("check %d bytes: %w", size, filedata.ErrFileQuotaExceeded)
+	}
+	return s.quota, nil
+}
+
+func (s *fakeService) hasFolder(p string) bool {
+	for _, f := range s.folders {
+		if f.Path == p {
+			return true
+		}
+	}
+	return false
+}
+
+func TestHandler_ServeDAV(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+	userID := uuid.New()
+
+	tests := []struct {
+		setup      func(s *fakeService)
+		check      func(t *testing.T, s *fakeService)
+		headers    map[string]string
+		name       string
+		method     string
+		target     string
+		body       string
+		wantBody   []string
+		hideBody   []string
+		wantStatus int
+	}{
+		{
+			name:       "list root",
+			method:     "PROPFIND",
+			target:     "/api/dav/",
+			headers:    map[string]string{"Depth": "1"},
+			wantStatus: http.StatusMultiStatus,
+			wantBody: []string{
+				"<D:href>/api/dav/docs/</D:href>",
+				"<D:href>/api/dav/a.txt</D:href>",
+				"<D:getcontentlength>5</D:getcontentlength>",
+				`<D:getetag>"h1"</D:getetag>`,
+				"<D:getcontenttype>text/plain; charset=utf-8</D:getcontenttype>",
+			},
+			hideBody: []string{"<D:getcontentlength>6</D:getcontentlength>", "h2"},
+		},
+		{
+			name:       "list root without trailing slash",
+			method:     "PROPFIND",
+			target:     "/api/dav",
+			headers:    map[string]string{"Depth": "0"},
+			wantStatus: http.StatusMultiStatus,
+			wantBody:   []string{"<D:href>/api/dav/</D:href>"},
+		},
+		{
+			name:       "list folder",
+			method:     "PROPFIND",
+			target:     "/api/dav/docs/",
+			headers:    map[string]string{"Depth": "1"},
+			wantStatus: http.StatusMultiStatus,
+			wantBody:   []string{"<D:href>/api/dav/docs/b.pdf</D:href>", "application/pdf"},
+		},
+		{
+			name:       "read file",
+			method:     http.MethodGet,
+			target:     "/api/dav/a.txt",
+			wantStatus: http.StatusOK,
+			wantBody:   []string{"hello"},
+		},
+		{
+			name:       "read byte range",
+			method:     http.MethodGet,
+			target:     "/api/dav/a.txt",
+			headers:    map[string]string{"Range": "bytes=1-3"},
+			wantStatus: http.StatusPartialContent,
+			wantBody:   []string{"ell"},
+			hideBody:   []string{"hello"},
+		},
+		{
+			name:       "read missing file",
+			method:     http.MethodGet,
+			target:     "/api/dav/missing.txt",
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "write new file",
+			method:     http.MethodPut,
+			target:     "/api/dav/docs/new.txt",
+			body:       "content",
+			wantStatus: http.StatusCreated,
+			check: func(t *testing.T, s *fakeService) {
+				t.Helper()
+				require.NotNil(t, s.pushed)
+				assert.Equal(t, uuid.Nil, s.pushed.ID)
+				assert.Equal(t, "docs/new.txt", s.pushed.StorageKey)
+				assert.Equal(t, "docs", s.pushed.Folder)
+				assert.Equal(t, userID, s.pushed.UserID)
+				assert.Equal(t, []byte("content"), s.pushed.Data)
+			},
+		},
+		{
+			name:       "write empty file",
+			method:     http.MethodPut,
+			target:     "/api/dav/empty.txt",
+			wantStatus: http.StatusCreated,
+			check: func(t *testing.T, s *fakeService) {
+				t.Helper()
+				require.NotNil(t, s.pushed)
+				assert.True(t, s.pushed.AllowEmpty)
+				assert.Empty(t, s.pushed.Data)
+			},
+		},
+		{
+			name:       "overwrite file",
+			method:     http.MethodPut,
+			target:     "/api/dav/a.txt",
+			body:       "updated",
+			wantStatus: http.StatusCreated,
+			check: func(t *testing.T, s *fakeService) {
+				t.Helper()
+				require.NotNil(t, s.pushed)
+				assert.Equal(t, s.files[1].ID, s.pushed.ID)
+				assert.Equal(t, "old/a.txt", s.pushed.StorageKey)
+				assert.Equal(t, "kept", s.pushed.Description)
+			},
+		},
+		{
+			name:       "write over quota",
+			setup:      func(s *fakeService) { s.pushErr = fmt.Errorf("push: %w", filedata.ErrFileQuotaExceeded) },
+			method:     http.MethodPut,
+			target:     "/api/dav/big.bin",
+			body:       "content",
+			wantStatus: http.StatusInsufficientStorage,
+			wantBody:   []string{"File storage quota exceeded"},
+			hideBody:   []string{"Method Not Allowed"},
+		},
+		{
+			name:       "write beyond quota aborts",
+			setup:      func(s *fakeService) { s.quota = 4 },
+			method:     http.MethodPut,
+			target:     "/api/dav/big.bin",
+			body:       "content",
+			wantStatus: http.StatusInsufficientStorage,
+			wantBody:   []string{"File storage quota exceeded"},
+			check: func(t *testing.T, s *fakeService) {
+				t.Helper()
+				assert.Nil(t, s.pushed)
+				assert.Equal(t, []int64{0, 7}, s.checks)
+			},
+		},
+		{
+			name:       "write into missing folder",
+			method:     http.MethodPut,
+			target:     "/api/dav/missing/new.txt",
+			body:       "content",
+			wantStatus: http.StatusConflict,
+			check: func(t *testing.T, s *fakeService) {
+				t.Helper()
+				assert.Nil(t, s.pushed)
+			},
+		},
+		{
+			name:       "create folder",
+			method:     "MKCOL",
+			target:     "/api/dav/docs/taxes",
+			wantStatus: http.StatusCreated,
+			check: func(t *testing.T, s *fakeService) {
+				t.Helper()
+				require.NotNil(t, s.created)
+				assert.Equal(t, "docs/taxes", s.created.Path)
+			},
+		},
+		{
+			name:       "create existing folder",
+			method:     "MKCOL",
+			target:     "/api/dav/docs",
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			name:       "create invalid folder",
+			method:     "MKCOL",
+			target:     "/api/dav/my%20docs",
+			wantStatus: http.StatusBadRequest,
+			wantBody:   []string{"Invalid folder path"},
+		},
+		{
+			name:       "move file",
+			method:     "MOVE",
+			target:     "/api/dav/a.txt",
+			headers:    map[string]string{"Destination": "http://example.com/api/dav/docs/c.txt"},
+			wantStatus: http.StatusCreated,
+			check: func(t *testing.T, s *fakeService) {
+				t.Helper()
+				require.NotNil(t, s.moved)
+				assert.Equal(t, s.files[1].ID, s.moved.ID)
+				assert.Equal(t, "docs", s.moved.Folder)
+				assert.Equal(t, "docs/c.txt", s.moved.StorageKey)
+			},
+		},
+		{
+			name:       "rename folder",
+			method:     "MOVE",
+			target:     "/api/dav/docs/",
+			headers:    map[string]string{"Destination": "http://example.com/api/dav/papers/"},
+			wantStatus: http.StatusCreated,
+			check: func(t *testing.T, s *fakeService) {
+				t.Helper()
+				require.Len(t, s.moveDirs, 1)
+				assert.Equal(t, s.folders[0].ID, s.moveDirs[0].ID)
+				assert.Equal(t, "papers", s.moveDirs[0].Path)
+			},
+		},
+		{
+			name:       "delete file",
+			method:     http.MethodDelete,
+			target:     "/api/dav/a.txt",
+			wantStatus: http.StatusMethodNotAllowed,
+			wantBody:   []string{"Deleting files and folders is not supported"},
+		},
+		{
+			name:       "delete root",
+			method:     http.MethodDelete,
+			target:     "/api/dav",
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			svc := newFakeService()
+			if tt.setup != nil {
+				tt.setup(svc)
+			}
+
+			router := gin.New()
+			group := router.Group("/api/dav", func(c *gin.Context) {
+				c.Set(consts.CtxKeyUserID, userID)
+				c.Next()
+			})
+			RegisterRoutes(group, NewHandler(svc))
+
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code, w.Body.String())
+			for _, s := range tt.wantBody {
+				assert.Contains(t, w.Body.String(), s)
+			}
+			for _, s := range tt.hideBody {
+				assert.NotContains(t, w.Body.String(), s)
+			}
+			if tt.check != nil {
+				tt.check(t, svc)
+			}
+		})
+	}
+}
+
+func TestWriteFile_Limit(t *testing.T) {
+	t.Parallel()
+
+	svc := newFakeService()
+	svc.quota = 10
+	fsys := &fileSystem{s: svc, userID: uuid.New()}
+
+	f, err := fsys.OpenFile(context.Background(), "/new.txt", os.O_WRONLY|os.O_CREATE, 0)
+	require.NoError(t, err)
+
+	_, err = f.Write([]byte("012345"))
+	require.NoError(t, err)
+	_, err = f.Write([]byte("6789ab"))
+	require.ErrorIs(t, err, filedata.ErrFileQuotaExceeded)
+	_, err = f.Write([]byte("c"))
+	require.ErrorIs(t, err, filedata.ErrFileQuotaExceeded)
+
+	require.ErrorIs(t, f.Close(), filedata.ErrFileQuotaExceeded)
+	assert.Nil(t, svc.pushed)
+	assert.Equal(t, []int64{0, 12}, svc.checks)
+}
+
+func TestHandler_LockSystem(t *testing.T) {
+	t.Parallel()
+
+	h := NewHandler(newFakeService())
+	alice, bob := uuid.New(), uuid.New()
+
+	assert.Same(t, h.lockSystem(alice), h.lockSystem(alice))
+	assert.NotSame(t, h.lockSystem(alice), h.lockSystem(bob))
+}
+
+func TestCleanPath(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		in         string
+		want       string
+		wantFolder string
+		wantBase   string
+	}{
+		{name: "root", in: "/", want: "", wantBase: ""},
+		{name: "empty", in: "", want: "", wantBase: ""},
+		{name: "file in root", in: "/a.txt", want: "a.txt", wantBase: "a.txt"},
+		{name: "nested folder", in: "/docs/taxes/", want: "docs/taxes", wantFolder: "docs", wantBase: "taxes"},
+		{name: "dot segments", in: "/docs/../a/./b", want: "a/b", wantFolder: "a", wantBase: "b"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := cleanPath(tt.in)
+			assert.Equal(t, tt.want, got)
+			folder, base := splitPath(got)
+			assert.Equal(t, tt.wantFolder, folder)
+			assert.Equal(t, tt.wantBase, base)
+			assert.Equal(t, path.Join(folder, base), got)
+		})
+	}
+}
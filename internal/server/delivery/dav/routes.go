@@ -0,0 +1,28 @@
+package dav
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// pathParam names the route parameter holding the WebDAV path below the route group.
+const pathParam = "path"
+
+// methods lists the HTTP and WebDAV methods served by the WebDAV endpoint. DELETE is not among them;
+// files and folders cannot be deleted through WebDAV.
+var methods = []string{
+	"OPTIONS", "GET", "HEAD", "PUT",
+	"MKCOL", "COPY", "MOVE", "LOCK", "UNLOCK", "PROPFIND", "PROPPATCH",
+}
+
+// RegisterRoutes registers the WebDAV endpoint with the provided router, serving the vault root at the
+// router path and files and folders below it. DELETE requests are answered with 405 Method Not Allowed.
+func RegisterRoutes(r gin.IRouter, h *Handler) {
+	for _, m := range methods {
+		r.Handle(m, "", h.ServeDAV)
+		r.Handle(m, "/*"+pathParam, h.ServeDAV)
+	}
+	r.Handle(http.MethodDelete, "", h.RejectDelete)
+	r.Handle(http.MethodDelete, "/*"+pathParam, h.RejectDelete)
+}
@@ -0,0 +1,5 @@
+// Package devicekey provides HTTP handlers for device key endpoints in the AegisVaultKeeper server.
+//
+// This package lets authenticated users register, list and revoke the X25519 public keys
+// of their devices, which sync payloads are encrypted for.
+package devicekey
@@ -0,0 +1,62 @@
+package devicekey
+
+import (
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/devicekey"
+	"github.com/google/uuid"
+)
+
+// DeviceKey represents the registered public key of a device.
+type DeviceKey struct {
+	// CreatedAt contains the key registration timestamp.
+	CreatedAt time.Time `json:"created_at"                      example:"2023-12-01T10:00:00Z"`
+	// Name contains the label of the device holding the private key.
+	Name string `json:"name"                            example:"phone"`
+	// PublicKey contains the base64-encoded X25519 public key of the device.
+	PublicKey []byte `json:"public_key" swaggertype:"string" example:"3p7bfXt9wbTTW2HC7OQ1Nz+DQ8hbeGdNrfx+FG+IK08="`
+	// ID contains the key identifier sent in the X-Device-Key header.
+	ID uuid.UUID `json:"id"                              example:"123e4567-e89b-12d3-a456-426614174000"`
+}
+
+// NewDeviceKeyFromApp converts an application layer device key to delivery DTO.
+func NewDeviceKeyFromApp(k *devicekey.DeviceKey) *DeviceKey {
+	if k == nil {
+		return nil
+	}
+	return &DeviceKey{
+		ID:        k.ID,
+		Name:      k.Name,
+		PublicKey: k.PublicKey,
+		CreatedAt: k.CreatedAt,
+	}
+}
+
+// NewDeviceKeysFromApp converts application layer device keys to delivery DTOs.
+func NewDeviceKeysFromApp(ks []*devicekey.DeviceKey) []*DeviceKey {
+	result := make([]*DeviceKey, 0, len(ks))
+	for _, k := range ks {
+		result = append(result, NewDeviceKeyFromApp(k))
+	}
+	return result
+}
+
+// RegisterDeviceKeyRequest represents the request to register the public key of a device.
+type RegisterDeviceKeyRequest struct {
+	// Name contains the label of the device (required, up to 64 characters).
+	Name string `json:"name"       binding:"required" example:"phone"`
+	// PublicKey contains the base64-encoded 32-byte X25519 public key of the device (required).
+	PublicKey []byte `json:"public_key" binding:"required" swaggertype:"string"`
+}
+
+// ListDeviceKeysResponse represents the response containing the device keys of the user.
+type ListDeviceKeysResponse struct {
+	// Keys contains the device keys ordered by registration time.
+	Keys []*DeviceKey `json:"keys"`
+}
+
+// DeleteDeviceKeyRequest represents the request to revoke a device key.
+type DeleteDeviceKeyRequest struct {
+	// ID contains the key identifier (required UUID format).
+	ID string `uri:"id" binding:"required" example:"123e4567-e89b-12d3-a456-426614174000"`
+}
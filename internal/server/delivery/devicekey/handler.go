@@ -0,0 +1,158 @@
+package devicekey
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/devicekey"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/response"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/util"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Service defines the device key application service interface.
+type Service interface {
+	// Register stores the public key of a device of the user.
+	Register(context.Context, devicekey.RegisterParams) (*devicekey.DeviceKey, error)
+	// List retrieves the device keys of the user.
+	List(context.Context, devicekey.ListParams) ([]*devicekey.DeviceKey, error)
+	// Delete revokes a device key of the user.
+	Delete(context.Context, devicekey.DeleteParams) error
+}
+
+// Handler handles HTTP requests for device key endpoints.
+type Handler struct {
+	// s is the device key service used to process operations.
+	s Service
+}
+
+// NewHandler creates a new device key handler with the provided service.
+func NewHandler(s Service) *Handler {
+	return &Handler{s: s}
+}
+
+// List retrieves the device keys of the authenticated user.
+// @Summary      List device keys
+// @Description  Retrieves the X25519 public keys registered by the devices of the user
+// @Tags         Account
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} ListDeviceKeysResponse "Device keys retrieved successfully"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /account/device-keys [get]
+// .
+func (h *Handler) List(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	userID, err := extractor.UserID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+
+	keys, err := h.s.List(c, devicekey.ListParams{UserID: userID})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, ListDeviceKeysResponse{Keys: NewDeviceKeysFromApp(keys)})
+}
+
+// Register stores the public key of a device of the authenticated user.
+// @Summary      Register device key
+// @Description  Registers the X25519 public key of a device. Sync requests carrying the key ID in the X-Device-Key
+// @Description  header receive their payloads encrypted for the device on top of TLS.
+// .
+// @Tags         Account
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body RegisterDeviceKeyRequest true "Device key data"
+// @Success      201 {object} DeviceKey "Device key registered successfully"
+// @Failure      400 {object} response.Error "Bad request - invalid name or public key"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /account/device-keys [post]
+// .
+func (h *Handler) Register(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	userID, err := extractor.UserID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+
+	// req holds the deserialized JSON request payload for the key registration.
+	var req RegisterDeviceKeyRequest
+	if err := extractor.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	key, err := h.s.Register(c, devicekey.RegisterParams{Name: req.Name, PublicKey: req.PublicKey, UserID: userID})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, NewDeviceKeyFromApp(key))
+}
+
+// Delete revokes a device key of the authenticated user.
+// @Summary      Delete device key
+// @Description  Revokes a device key; sync requests naming it are rejected afterwards
+// @Tags         Account
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Device key ID" format(uuid)
+// @Success      204 "Device key deleted successfully"
+// @Failure      400 {object} response.Error "Bad request - invalid ID format"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
+// @Failure      404 {object} response.Error "Not found - device key not found"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /account/device-keys/{id} [delete]
+// .
+func (h *Handler) Delete(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	userID, err := extractor.UserID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+
+	// req holds the deserialized URI parameters for the delete request.
+	var req DeleteDeviceKeyRequest
+	if err := extractor.BindURI(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	keyID, err := uuid.Parse(req.ID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	if err := h.s.Delete(c, devicekey.DeleteParams{ID: keyID, UserID: userID}); err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.Data(http.StatusNoContent, "", nil)
+}
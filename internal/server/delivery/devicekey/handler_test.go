@@ -0,0 +1,273 @@
+package devicekey
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/devicekey"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/consts"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockDeviceKeyService implements Service for testing.
+type mockDeviceKeyService struct {
+	registerFunc func(ctx context.Context, params devicekey.RegisterParams) (*devicekey.DeviceKey, error)
+	listFunc     func(ctx context.Context, params devicekey.ListParams) ([]*devicekey.DeviceKey, error)
+	deleteFunc   func(ctx context.Context, params devicekey.DeleteParams) error
+}
+
+func (m *mockDeviceKeyService) Register(
+	ctx context.Context,
+	params devicekey.RegisterParams,
+) (*devicekey.DeviceKey, error) {
+	if m.registerFunc != nil {
+		return m.registerFunc(ctx, params)
+	}
+	return nil, nil
+}
+
+func (m *mockDeviceKeyService) List(
+	ctx context.Context,
+	params devicekey.ListParams,
+) ([]*devicekey.DeviceKey, error) {
+	if m.listFunc != nil {
+		return m.listFunc(ctx, params)
+	}
+	return nil, nil
+}
+
+func (m *mockDeviceKeyService) Delete(ctx context.Context, params devicekey.DeleteParams) error {
+	if m.deleteFunc != nil {
+		return m.deleteFunc(ctx, params)
+	}
+	return nil
+}
+
+func TestHandler_List(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	keyID := uuid.New()
+	createdAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	publicKey := []byte("0123456789abcdef0123456789abcdef")
+
+	tests := []struct {
+		mockService    *mockDeviceKeyService
+		want           *ListDeviceKeysResponse
+		name           string
+		setUser        bool
+		expectedStatus int
+	}{
+		{
+			name:    "success",
+			setUser: true,
+			mockService: &mockDeviceKeyService{
+				listFunc: func(_ context.Context, params devicekey.ListParams) ([]*devicekey.DeviceKey, error) {
+					assert.Equal(t, userID, params.UserID)
+					return []*devicekey.DeviceKey{
+						{ID: keyID, Name: "phone", PublicKey: publicKey, CreatedAt: createdAt},
+					}, nil
+				},
+			},
+			want: &ListDeviceKeysResponse{
+				Keys: []*DeviceKey{{ID: keyID, Name: "phone", PublicKey: publicKey, CreatedAt: createdAt}},
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "missing user context",
+			mockService:    &mockDeviceKeyService{},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:    "service error",
+			setUser: true,
+			mockService: &mockDeviceKeyService{
+				listFunc: func(context.Context, devicekey.ListParams) ([]*devicekey.DeviceKey, error) {
+					return nil, devicekey.ErrDeviceKeyTechError
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/account/device-keys", nil)
+			if tt.setUser {
+				c.Set(consts.CtxKeyUserID, userID)
+			}
+
+			NewHandler(tt.mockService).List(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.want == nil {
+				return
+			}
+			var got ListDeviceKeysResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+			assert.Equal(t, *tt.want, got)
+		})
+	}
+}
+
+func TestHandler_Register(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	keyID := uuid.New()
+	publicKey := []byte("0123456789abcdef0123456789abcdef")
+	encodedKey := "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY="
+
+	tests := []struct {
+		mockService    *mockDeviceKeyService
+		name           string
+		body           string
+		wantName       string
+		expectedStatus int
+	}{
+		{
+			name: "success",
+			body: `{"name":"phone","public_key":"` + encodedKey + `"}`,
+			mockService: &mockDeviceKeyService{
+				registerFunc: func(_ context.Context, params devicekey.RegisterParams) (*devicekey.DeviceKey, error) {
+					assert.Equal(t, devicekey.RegisterParams{Name: "phone", PublicKey: publicKey, UserID: userID}, params)
+					return &devicekey.DeviceKey{ID: keyID, Name: params.Name, PublicKey: params.PublicKey}, nil
+				},
+			},
+			wantName:       "phone",
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "missing public key",
+			body:           `{"name":"phone"}`,
+			mockService:    &mockDeviceKeyService{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "public key not base64",
+			body:           `{"name":"phone","public_key":"not base64!"}`,
+			mockService:    &mockDeviceKeyService{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "invalid public key",
+			body: `{"name":"phone","public_key":"c2hvcnQ="}`,
+			mockService: &mockDeviceKeyService{
+				registerFunc: func(context.Context, devicekey.RegisterParams) (*devicekey.DeviceKey, error) {
+					return nil, devicekey.ErrDeviceKeyIncorrectPublicKey
+				},
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "service error",
+			body: `{"name":"phone","public_key":"` + encodedKey + `"}`,
+			mockService: &mockDeviceKeyService{
+				registerFunc: func(context.Context, devicekey.RegisterParams) (*devicekey.DeviceKey, error) {
+					return nil, errors.Join(devicekey.ErrDeviceKeyTechError, errors.New("db down"))
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/account/device-keys", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Set(consts.CtxKeyUserID, userID)
+
+			NewHandler(tt.mockService).Register(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.wantName == "" {
+				return
+			}
+			var got DeviceKey
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+			assert.Equal(t, keyID, got.ID)
+			assert.Equal(t, tt.wantName, got.Name)
+			assert.Equal(t, publicKey, got.PublicKey)
+		})
+	}
+}
+
+func TestHandler_Delete(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	keyID := uuid.New()
+
+	tests := []struct {
+		mockService    *mockDeviceKeyService
+		name           string
+		id             string
+		expectedStatus int
+	}{
+		{
+			name: "success",
+			id:   keyID.String(),
+			mockService: &mockDeviceKeyService{
+				deleteFunc: func(_ context.Context, params devicekey.DeleteParams) error {
+					assert.Equal(t, devicekey.DeleteParams{ID: keyID, UserID: userID}, params)
+					return nil
+				},
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "invalid id",
+			id:             "not-a-uuid",
+			mockService:    &mockDeviceKeyService{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "not found",
+			id:   keyID.String(),
+			mockService: &mockDeviceKeyService{
+				deleteFunc: func(context.Context, devicekey.DeleteParams) error {
+					return devicekey.ErrDeviceKeyNotFound
+				},
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodDelete, "/account/device-keys/"+tt.id, nil)
+			c.Params = gin.Params{{Key: "id", Value: tt.id}}
+			c.Set(consts.CtxKeyUserID, userID)
+
+			NewHandler(tt.mockService).Delete(c)
+
+			c.Writer.WriteHeaderNow()
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
@@ -0,0 +1,12 @@
+package devicekey
+
+import "github.com/gin-gonic/gin"
+
+// RegisterRoutes registers device key routes with the provided router group.
+// Creates /device-keys and /device-keys/:id with the specified handler.
+func RegisterRoutes(r *gin.RouterGroup, h *Handler) {
+	keysGroup := r.Group("/device-keys")
+	keysGroup.GET("", h.List)
+	keysGroup.POST("", h.Register)
+	keysGroup.DELETE("/:id", h.Delete)
+}
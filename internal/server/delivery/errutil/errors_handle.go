@@ -1,12 +1,22 @@
 package errutil
 
 import (
+	"context"
 	"errors"
+	"math"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+// StatusClientClosedRequest is the non-standard status recorded for requests abandoned by clients.
+const StatusClientClosedRequest = 499
+
+// timeoutMsg is the user-facing message for requests aborted by their handling deadline.
+const timeoutMsg = "The request took too long to process. Please try again later"
+
 // ErrorClass represents the category of an error for prioritization.
 type ErrorClass int
 
@@ -33,6 +43,8 @@ type Policy struct {
 	LogIt bool
 	// AllowMerge indicates whether this error can be merged with others.
 	AllowMerge bool
+	// RetryAfter is sent as the Retry-After header telling clients when to retry; zero sends no header.
+	RetryAfter time.Duration
 }
 
 // Precedes returns true if this policy has higher priority than other.
@@ -107,6 +119,7 @@ func (r Registry) Message(best Policy, matches []Policy) []string {
 }
 
 // Handle processes an error using the registry and returns response details.
+// Errors caused by an expired request deadline or a client disconnect take precedence over registry rules.
 func (r Registry) Handle(err error) (int, []string, bool) {
 	defStatus := http.StatusInternalServerError
 	defMsg := http.StatusText(defStatus)
@@ -116,6 +129,13 @@ func (r Registry) Handle(err error) (int, []string, bool) {
 		return defStatus, []string{defMsg}, false
 	}
 
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusServiceUnavailable, []string{timeoutMsg}, true
+	case errors.Is(err, context.Canceled):
+		return StatusClientClosedRequest, []string{"Client Closed Request"}, false
+	}
+
 	matches := r.Match(err)
 	if len(matches) == 0 {
 		return defStatus, []string{defMsg}, defLog
@@ -126,14 +146,30 @@ func (r Registry) Handle(err error) (int, []string, bool) {
 }
 
 // HandleWithRegistry processes an error and optionally logs it to Gin context.
+// It sets the Retry-After header when the policy of the error asks clients to retry later.
 func HandleWithRegistry(r Registry, err error, c *gin.Context) (int, []string) {
 	code, msgs, logIt := r.Handle(err)
 	if logIt {
 		_ = c.Error(err)
 	}
+	if after := r.retryAfter(err); after > 0 {
+		c.Header("Retry-After", strconv.FormatInt(int64(math.Ceil(after.Seconds())), 10))
+	}
 	return code, msgs
 }
 
+// retryAfter returns the retry delay of the policy Handle selects for the error, or zero when it has none.
+func (r Registry) retryAfter(err error) time.Duration {
+	if err == nil || errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		return 0
+	}
+	best, ok := r.Best(r.Match(err))
+	if !ok {
+		return 0
+	}
+	return best.RetryAfter
+}
+
 // Merge combines multiple registries into a single registry.
 func Merge(regs ...Registry) Registry {
 	// out holds the combined registry entries from all input registries.
@@ -1,8 +1,13 @@
 package errutil
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -301,6 +306,20 @@ func TestRegistry_Handle(t *testing.T) {
 			wantMessages: []string{"Validation failed"},
 			wantLogIt:    false,
 		},
+		{
+			name:         "deadline_exceeded_takes_precedence",
+			inputErr:     errors.Join(errTestAuth, context.DeadlineExceeded),
+			wantStatus:   http.StatusServiceUnavailable,
+			wantMessages: []string{"The request took too long to process. Please try again later"},
+			wantLogIt:    true,
+		},
+		{
+			name:         "canceled_returns_client_closed_request",
+			inputErr:     fmt.Errorf("query failed: %w", context.Canceled),
+			wantStatus:   StatusClientClosedRequest,
+			wantMessages: []string{"Client Closed Request"},
+			wantLogIt:    false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -339,14 +358,24 @@ func TestHandleWithRegistry(t *testing.T) {
 				ErrorClass: ErrorClassValidation,
 			},
 		},
+		{
+			ErrorIn: errTestBusy,
+			HandlePolicy: Policy{
+				StatusCode: http.StatusTooManyRequests,
+				PublicMsg:  "Busy",
+				ErrorClass: ErrorClassGeneric,
+				RetryAfter: 1500 * time.Millisecond,
+			},
+		},
 	}
 
 	tests := []struct {
-		inputErr     error
-		name         string
-		wantMessages []string
-		wantStatus   int
-		expectLogged bool
+		inputErr       error
+		name           string
+		wantRetryAfter string
+		wantMessages   []string
+		wantStatus     int
+		expectLogged   bool
 	}{
 		{
 			name:         "auth_error_gets_logged",
@@ -369,6 +398,20 @@ func TestHandleWithRegistry(t *testing.T) {
 			wantMessages: []string{"Internal Server Error"},
 			expectLogged: true,
 		},
+		{
+			name:           "busy_error_sets_retry_after",
+			inputErr:       fmt.Errorf("wrapped: %w", errTestBusy),
+			wantStatus:     http.StatusTooManyRequests,
+			wantMessages:   []string{"Busy"},
+			wantRetryAfter: "2",
+		},
+		{
+			name:         "deadline_sets_no_retry_after",
+			inputErr:     errors.Join(errTestBusy, context.DeadlineExceeded),
+			wantStatus:   http.StatusServiceUnavailable,
+			wantMessages: []string{timeoutMsg},
+			expectLogged: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -377,12 +420,14 @@ func TestHandleWithRegistry(t *testing.T) {
 			t.Parallel()
 
 			gin.SetMode(gin.TestMode)
-			c, _ := gin.CreateTestContext(nil)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
 
 			status, messages := HandleWithRegistry(registry, tt.inputErr, c)
 
 			assert.Equal(t, tt.wantStatus, status)
 			assert.Equal(t, tt.wantMessages, messages)
+			assert.Equal(t, tt.wantRetryAfter, w.Header().Get("Retry-After"))
 
 			if tt.expectLogged {
 				assert.NotEmpty(t, c.Errors, "Error should be logged to gin context")
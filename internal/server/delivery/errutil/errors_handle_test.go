@@ -13,6 +13,7 @@ var (
 	errTestAuth       = errors.New("auth error")
 	errTestValidation = errors.New("validation error")
 	errTestTech       = errors.New("technical error")
+	errTestBusy       = errors.New("busy error")
 )
 
 func TestErrorClass(t *testing.T) {
@@ -0,0 +1,5 @@
+// Package feature provides HTTP handlers for feature flag endpoints in the AegisVaultKeeper server.
+//
+// This package exposes the effective feature flags of the authenticated user, so clients can
+// adapt to the experimental endpoints and behaviors switched on for them.
+package feature
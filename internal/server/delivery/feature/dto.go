@@ -0,0 +1,18 @@
+package feature
+
+import "github.com/gdyunin/aegis-vault-keeper/internal/server/application/feature"
+
+// ListFeaturesResponse represents the effective feature flags of the authenticated user.
+type ListFeaturesResponse struct {
+	// Features maps every known feature key to whether it is switched on.
+	Features map[string]bool `json:"features" example:"sync_v2:true"`
+}
+
+// NewListFeaturesResponseFromApp converts application layer feature flags to delivery DTO.
+func NewListFeaturesResponseFromApp(flags []*feature.Flag) ListFeaturesResponse {
+	features := make(map[string]bool, len(flags))
+	for _, f := range flags {
+		features[f.Key] = f.Enabled
+	}
+	return ListFeaturesResponse{Features: features}
+}
@@ -0,0 +1,61 @@
+package feature
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/feature"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/response"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/util"
+	"github.com/gin-gonic/gin"
+)
+
+// Service defines the feature flag application service interface.
+type Service interface {
+	// List retrieves the effective state of every known feature for the user.
+	List(context.Context, feature.ListParams) ([]*feature.Flag, error)
+}
+
+// Handler handles HTTP requests for feature flag endpoints.
+type Handler struct {
+	// s is the feature flag service used to resolve flags.
+	s Service
+}
+
+// NewHandler creates a new feature flag handler with the provided service.
+func NewHandler(s Service) *Handler {
+	return &Handler{s: s}
+}
+
+// List returns the effective feature flags of the authenticated user.
+// @Summary      List feature flags
+// @Description  Reports which experimental endpoints and behaviors are switched on for the user,
+// @Description  combining deployment defaults with deployment-wide and per-user overrides.
+// .
+// @Tags         Features
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} ListFeaturesResponse "Feature flags retrieved successfully"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /features [get]
+// .
+func (h *Handler) List(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	userID, err := extractor.UserID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+
+	flags, err := h.s.List(c, feature.ListParams{UserID: userID})
+	if err != nil {
+		_ = c.Error(err)
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+
+	c.JSON(http.StatusOK, NewListFeaturesResponseFromApp(flags))
+}
@@ -0,0 +1,115 @@
+package feature
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/feature"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/consts"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockFeatureService implements Service for testing.
+type mockFeatureService struct {
+	listFunc func(ctx context.Context, params feature.ListParams) ([]*feature.Flag, error)
+}
+
+func (m *mockFeatureService) List(ctx context.Context, params feature.ListParams) ([]*feature.Flag, error) {
+	if m.listFunc != nil {
+		return m.listFunc(ctx, params)
+	}
+	return nil, nil
+}
+
+func TestHandler_List(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+
+	tests := []struct {
+		setupContext   func(c *gin.Context)
+		mockService    *mockFeatureService
+		want           *ListFeaturesResponse
+		name           string
+		expectedStatus int
+	}{
+		{
+			name: "success",
+			setupContext: func(c *gin.Context) {
+				c.Set(consts.CtxKeyUserID, userID)
+			},
+			mockService: &mockFeatureService{
+				listFunc: func(_ context.Context, params feature.ListParams) ([]*feature.Flag, error) {
+					assert.Equal(t, userID, params.UserID)
+					return []*feature.Flag{
+						{Key: "crypto_v2", Enabled: false},
+						{Key: "sync_v2", Enabled: true},
+					}, nil
+				},
+			},
+			want: &ListFeaturesResponse{
+				Features: map[string]bool{"crypto_v2": false, "sync_v2": true},
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "no features",
+			setupContext: func(c *gin.Context) {
+				c.Set(consts.CtxKeyUserID, userID)
+			},
+			mockService:    &mockFeatureService{},
+			want:           &ListFeaturesResponse{Features: map[string]bool{}},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "missing user context",
+			setupContext: func(c *gin.Context) {
+				// don't set user_id
+			},
+			mockService:    &mockFeatureService{},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name: "service error",
+			setupContext: func(c *gin.Context) {
+				c.Set(consts.CtxKeyUserID, userID)
+			},
+			mockService: &mockFeatureService{
+				listFunc: func(context.Context, feature.ListParams) ([]*feature.Flag, error) {
+					return nil, errors.New("service error")
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/features", nil)
+
+			tt.setupContext(c)
+
+			NewHandler(tt.mockService).List(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.want == nil {
+				return
+			}
+			var got ListFeaturesResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+			assert.Equal(t, *tt.want, got)
+		})
+	}
+}
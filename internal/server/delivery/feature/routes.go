@@ -0,0 +1,8 @@
+package feature
+
+import "github.com/gin-gonic/gin"
+
+// RegisterRoutes registers feature flag routes with the provided router group.
+func RegisterRoutes(r *gin.RouterGroup, h *Handler) {
+	r.GET("", h.List)
+}
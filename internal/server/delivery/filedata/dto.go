@@ -7,6 +7,14 @@ import (
 	"github.com/google/uuid"
 )
 
+// Page size limits of folder listings.
+const (
+	// defaultPageSize is the number of files returned when the request sets no limit.
+	defaultPageSize = 100
+	// maxPageSize limits the number of files returned per page.
+	maxPageSize = 200
+)
+
 // PullRequest represents the request to retrieve a specific file.
 type PullRequest struct {
 	// ID is the unique file identifier in UUID format.
@@ -25,6 +33,88 @@ type PushRequest struct {
 	StorageKey string `form:"storage_key" example:"document.pdf"` // Custom storage key (filename)
 	// Description is optional user-provided description of the file content.
 	Description string `form:"description" example:"Important PDF"` // File description
+	// Folder is the existing folder holding the file; empty keeps the folder of an updated file.
+	Folder string `form:"folder" example:"docs/taxes"` // Folder path
+}
+
+// MoveFileRequest represents the request to move a file to another folder or rename it.
+type MoveFileRequest struct {
+	// Folder is the existing folder the file is moved to; empty moves it to the root folder.
+	Folder string `json:"folder" example:"docs/taxes"`
+	// StorageKey is the new storage key of the file; empty keeps the current one.
+	StorageKey string `json:"storage_key" example:"tax-return-2025.pdf"`
+}
+
+// FolderRequest represents the request to create a folder or to rename or move one.
+type FolderRequest struct {
+	// Path is the path of the folder; its parent folder must exist.
+	Path string `json:"path" binding:"required" example:"docs/taxes"`
+}
+
+// FolderQuery represents the query parameters of a folder listing.
+type FolderQuery struct {
+	// Limit is the maximum number of files returned; nil uses the default page size.
+	Limit *int `form:"limit" example:"100"`
+	// Path is the path of the listed folder; empty lists the root folder.
+	Path string `form:"path" example:"docs"`
+	// Offset is the number of files skipped.
+	Offset int `form:"offset" example:"0"`
+}
+
+// page returns the offset and the limit of the requested page of files.
+func (q FolderQuery) page() (offset, limit int) {
+	limit = defaultPageSize
+	if q.Limit != nil {
+		limit = min(max(*q.Limit, 0), maxPageSize)
+	}
+	return max(q.Offset, 0), limit
+}
+
+// Folder represents a file folder.
+type Folder struct {
+	// UpdatedAt indicates when the folder was last created, renamed or moved.
+	UpdatedAt time.Time `json:"updated_at" example:"2023-12-01T10:00:00Z"`
+	// Path is the slash-separated path of the folder.
+	Path string `json:"path"       example:"docs/taxes"`
+	// ID is the unique folder identifier.
+	ID uuid.UUID `json:"id"         example:"123e4567-e89b-12d3-a456-426614174000"`
+}
+
+// NewFolderFromApp converts an application folder to delivery DTO format.
+func NewFolderFromApp(f *filedata.Folder) *Folder {
+	return &Folder{ID: f.ID, Path: f.Path, UpdatedAt: f.UpdatedAt}
+}
+
+// FolderListResponse represents one page of the contents of a folder.
+type FolderListResponse struct {
+	// Folder is the path of the listed folder; empty for the root folder.
+	Folder string `json:"folder"  example:"docs"`
+	// Folders contains the folders directly inside the listed folder.
+	Folders []*Folder `json:"folders"`
+	// Files contains the requested page of files directly inside the listed folder, without content.
+	Files []*FileData `json:"files"`
+	// Total counts all files directly inside the listed folder.
+	Total int `json:"total"   example:"42"`
+	// Offset is the number of files skipped.
+	Offset int `json:"offset"  example:"0"`
+	// Limit is the maximum number of files returned.
+	Limit int `json:"limit"   example:"100"`
+}
+
+// NewFolderListResponseFromApp converts an application folder listing to delivery DTO format.
+func NewFolderListResponseFromApp(l *filedata.FolderListing, offset, limit int) *FolderListResponse {
+	folders := make([]*Folder, 0, len(l.Folders))
+	for _, f := range l.Folders {
+		folders = append(folders, NewFolderFromApp(f))
+	}
+	return &FolderListResponse{
+		Folder:  l.Folder,
+		Folders: folders,
+		Files:   NewFileDataListFromApp(l.Files),
+		Total:   l.Total,
+		Offset:  offset,
+		Limit:   limit,
+	}
 }
 
 // PushResponse represents the response after uploading a file.
@@ -43,12 +133,18 @@ type FileData struct {
 	HashSum string `json:"hash_sum"       example:"d41d8cd98f00b204e9800998ecf8427e"`
 	// Description is the user-provided description of the file content.
 	Description string `json:"description"    example:"Important PDF document"`
+	// Folder is the path of the folder holding the file; empty for the root folder.
+	Folder string `json:"folder"         example:"docs/taxes"`
 	// Data contains the file content bytes (omitted in list responses).
 	Data []byte `json:"data,omitempty"`
 	// ID is the unique file identifier.
 	ID uuid.UUID `json:"id"             example:"123e4567-e89b-12d3-a456-426614174000"`
 	// UserID identifies the file owner.
 	UserID uuid.UUID `json:"user_id"        example:"987fcdeb-51a2-43d1-9f12-ba9876543210"`
+	// Size is the size of the file content in bytes; zero for files stored before sizes were recorded.
+	Size int64 `json:"size"           example:"2048"`
+	// Archived reports whether the file is archived and hidden from lists by default.
+	Archived bool `json:"archived"       example:"false"`
 }
 
 // NewFileDataFromApp converts an application filedata entity to delivery DTO format.
@@ -59,8 +155,11 @@ func NewFileDataFromApp(fd *filedata.FileData) *FileData {
 		StorageKey:  fd.StorageKey,
 		HashSum:     fd.HashSum,
 		Description: fd.Description,
+		Folder:      fd.Folder,
+		Size:        fd.Size,
 		UpdatedAt:   fd.UpdatedAt,
 		Data:        fd.Data,
+		Archived:    fd.Archived,
 	}
 }
 
@@ -84,6 +183,8 @@ func (f *FileData) ToApp(userID uuid.UUID) *filedata.FileData {
 		StorageKey:  f.StorageKey,
 		HashSum:     f.HashSum,
 		Description: f.Description,
+		Folder:      f.Folder,
+		Size:        f.Size,
 		UpdatedAt:   f.UpdatedAt,
 		Data:        f.Data,
 	}
@@ -109,6 +210,8 @@ func (f *FileData) withoutData() *FileData {
 		StorageKey:  f.StorageKey,
 		HashSum:     f.HashSum,
 		Description: f.Description,
+		Folder:      f.Folder,
+		Size:        f.Size,
 		UpdatedAt:   f.UpdatedAt,
 	}
 }
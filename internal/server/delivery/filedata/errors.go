@@ -2,12 +2,18 @@ package filedata
 
 import (
 	"net/http"
+	"time"
 
 	app "github.com/gdyunin/aegis-vault-keeper/internal/server/application/filedata"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/errutil"
+	itempolicydel "github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/itempolicy"
+	plandel "github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/plan"
 	"github.com/gin-gonic/gin"
 )
 
+// busyRetryAfter is the delay clients are asked to wait before retrying a transfer rejected as busy.
+const busyRetryAfter = 5 * time.Second
+
 // FileDataErrRegistry defines error handling policies for file data operations.
 var FileDataErrRegistry = errutil.Registry{
 
@@ -22,6 +28,17 @@ var FileDataErrRegistry = errutil.Registry{
 		},
 	},
 
+	{
+		ErrorIn: app.ErrFileIntegrityViolation,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusInternalServerError,
+			PublicMsg:  "Stored file data failed integrity verification",
+			LogIt:      true,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassTech,
+		},
+	},
+
 	{
 		ErrorIn: app.ErrFileAccessDenied,
 		HandlePolicy: errutil.Policy{
@@ -55,6 +72,84 @@ var FileDataErrRegistry = errutil.Registry{
 		},
 	},
 
+	{
+		ErrorIn: app.ErrFolderNotFound,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusNotFound,
+			PublicMsg:  "Folder not found",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassGeneric,
+		},
+	},
+
+	{
+		ErrorIn: app.ErrFolderExists,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusConflict,
+			PublicMsg:  "Folder already exists",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassGeneric,
+		},
+	},
+
+	{
+		ErrorIn: app.ErrFileStorageKeyTaken,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusConflict,
+			PublicMsg:  "Storage key is already taken",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassGeneric,
+		},
+	},
+
+	{
+		ErrorIn: app.ErrFileQuotaExceeded,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusInsufficientStorage,
+			PublicMsg:  "File storage quota exceeded",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassGeneric,
+		},
+	},
+
+	{
+		ErrorIn: app.ErrFileBusy,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusTooManyRequests,
+			PublicMsg:  "Too many file transfers in progress, please retry later",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassGeneric,
+			RetryAfter: busyRetryAfter,
+		},
+	},
+
+	{
+		ErrorIn: app.ErrFolderMoveIntoItself,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusBadRequest,
+			PublicMsg:  "Folder cannot be moved into itself",
+			LogIt:      false,
+			AllowMerge: true,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+
+	{
+		ErrorIn: app.ErrFileIncorrectFolder,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusBadRequest,
+			PublicMsg:  "Invalid folder path",
+			LogIt:      false,
+			AllowMerge: true,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+
 	{
 		ErrorIn: app.ErrFileIncorrectHashSum,
 		HandlePolicy: errutil.Policy{
@@ -100,7 +195,14 @@ var FileDataErrRegistry = errutil.Registry{
 	},
 }
 
+// handledErrRegistry aggregates the file data, plan enforcement and item policy enforcement error registries.
+var handledErrRegistry = errutil.Merge(
+	FileDataErrRegistry,
+	plandel.PlanErrRegistry,
+	itempolicydel.ItemPolicyErrRegistry,
+)
+
 // handleError processes file data errors using the registry and returns appropriate HTTP response.
 func handleError(err error, c *gin.Context) (int, []string) {
-	return errutil.HandleWithRegistry(FileDataErrRegistry, err, c)
+	return errutil.HandleWithRegistry(handledErrRegistry, err, c)
 }
@@ -61,6 +61,42 @@ func TestFileDataErrRegistry(t *testing.T) {
 			expectedClass: errutil.ErrorClassTech,
 			expectedCode:  500,
 		},
+		{
+			name:          "ErrFolderNotFound",
+			expectedError: app.ErrFolderNotFound,
+			expectedClass: errutil.ErrorClassGeneric,
+			expectedCode:  404,
+		},
+		{
+			name:          "ErrFolderExists",
+			expectedError: app.ErrFolderExists,
+			expectedClass: errutil.ErrorClassGeneric,
+			expectedCode:  409,
+		},
+		{
+			name:          "ErrFileStorageKeyTaken",
+			expectedError: app.ErrFileStorageKeyTaken,
+			expectedClass: errutil.ErrorClassGeneric,
+			expectedCode:  409,
+		},
+		{
+			name:          "ErrFileQuotaExceeded",
+			expectedError: app.ErrFileQuotaExceeded,
+			expectedClass: errutil.ErrorClassGeneric,
+			expectedCode:  507,
+		},
+		{
+			name:          "ErrFolderMoveIntoItself",
+			expectedError: app.ErrFolderMoveIntoItself,
+			expectedClass: errutil.ErrorClassValidation,
+			expectedCode:  400,
+		},
+		{
+			name:          "ErrFileIncorrectFolder",
+			expectedError: app.ErrFileIncorrectFolder,
+			expectedClass: errutil.ErrorClassValidation,
+			expectedCode:  400,
+		},
 		{
 			name:          "ErrFileAppError",
 			expectedError: app.ErrFileAppError,
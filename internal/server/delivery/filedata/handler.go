@@ -8,10 +8,14 @@ import (
 	"io"
 	"mime/multipart"
 	"net/http"
+	"slices"
 
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/filedata"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/consts"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/jsonenc"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/response"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/util"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/securebytes"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
@@ -24,6 +28,19 @@ type Service interface {
 	List(context.Context, filedata.ListParams) ([]*filedata.FileData, error)
 	// Push uploads and stores a new file for the authenticated user.
 	Push(context.Context, *filedata.PushParams) (uuid.UUID, error)
+	// MoveFile moves a file of the authenticated user to another folder or renames it.
+	MoveFile(context.Context, filedata.MoveFileParams) (*filedata.FileData, error)
+	// ListFolder retrieves the subfolders and a page of the files of a folder of the authenticated user.
+	ListFolder(context.Context, filedata.ListFolderParams) (*filedata.FolderListing, error)
+	// CreateFolder creates a folder for the authenticated user.
+	CreateFolder(context.Context, filedata.CreateFolderParams) (*filedata.Folder, error)
+	// MoveFolder renames or moves a folder of the authenticated user together with its contents.
+	MoveFolder(context.Context, filedata.MoveFolderParams) (*filedata.Folder, error)
+	// ReserveTransfer waits for the transfer limiter to admit an upload of the given size in bytes.
+	ReserveTransfer(context.Context, int64) (func(), error)
+	// CheckUpload ensures that the plan and quota accept an upload of the given size and returns the largest
+	// size they accept, negative when unbounded.
+	CheckUpload(context.Context, *filedata.PushParams, int64) (int64, error)
 }
 
 // Handler handles HTTP requests for file data storage endpoints.
@@ -50,6 +67,7 @@ func NewHandler(s Service) *Handler {
 // @Failure      400 {object} response.Error "Bad request - invalid ID format"
 // @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
 // @Failure      404 {object} response.Error "Not found - file not found"
+// @Failure      429 {object} response.Error "Too many file transfers in progress; retry after Retry-After"
 // @Failure      500 {object} response.Error "Internal server error"
 // @Router       /items/filedata/{id} [get]
 // .
@@ -83,9 +101,11 @@ func (h *Handler) Pull(c *gin.Context) {
 		})
 		return
 	}
+	defer securebytes.Wipe(fd.Data)
 
 	// buf holds the multipart form data buffer for the download response.
 	var buf bytes.Buffer
+	defer func() { securebytes.Wipe(buf.Bytes()) }()
 	writer := multipart.NewWriter(&buf)
 
 	metadataWriter, err := writer.CreateFormField("metadata")
@@ -135,8 +155,12 @@ func (h *Handler) Pull(c *gin.Context) {
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
+// @Param        include query string false "Comma-separated item groups to add; archived lists archived items too"
+// @Param        sort query string false "List order; manual lists pinned and arranged items first" Enums(manual)
+// @Param        folder query string false "Folder whose manual order sorts the list; the root when omitted"
 // @Success      200 {object} ListResponse "Files metadata retrieved successfully"
 // @Success      204 "No files found"
+// @Failure      400 {object} response.Error "Bad request - unknown include or sort"
 // @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
 // @Failure      500 {object} response.Error "Internal server error"
 // @Router       /items/filedata [get]
@@ -150,7 +174,24 @@ func (h *Handler) List(c *gin.Context) {
 		return
 	}
 
-	files, err := h.s.List(c, filedata.ListParams{UserID: userID})
+	include, err := extractor.Include([]string{consts.IncludeArchived})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	sort, err := extractor.Sort([]string{consts.SortManual})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	files, err := h.s.List(c, filedata.ListParams{
+		UserID:          userID,
+		OrderFolder:     extractor.Folder(),
+		ExcludeArchived: !slices.Contains(include, consts.IncludeArchived),
+		ManualOrder:     sort == consts.SortManual,
+	})
 	if err != nil {
 		code, msgs := handleError(err, c)
 		c.JSON(code, response.Error{
@@ -164,10 +205,12 @@ func (h *Handler) List(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, ListResponse{Files: NewFileDataListFromApp(files)})
+	c.Render(http.StatusOK, jsonenc.JSON{Value: &ListResponse{Files: NewFileDataListFromApp(files)}})
 }
 
 // Push uploads a new file or updates an existing one.
+// The upload is admitted by the transfer limiter for its Content-Length before the body is read, and the body
+// may not exceed the declared length.
 // @Summary      Upload or update file
 // @Description  Uploads a new file or updates an existing one if ID is provided in URL path
 // @Tags         Files
@@ -178,10 +221,13 @@ func (h *Handler) List(c *gin.Context) {
 // @Param        file formData file true "File to upload"
 // @Param        storage_key formData string false "Custom storage key (filename)"
 // @Param        description formData string false "File description"
+// @Param        folder formData string false "Existing folder path; empty keeps the folder of an updated file"
 // @Success      201 {object} PushResponse "File uploaded successfully"
 // @Failure      400 {object} response.Error "Bad request - invalid input data or file"
 // @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
 // @Failure      404 {object} response.Error "Not found - file not found for update"
+// @Failure      411 {object} response.Error "Length required - the request has no Content-Length"
+// @Failure      429 {object} response.Error "Too many file transfers in progress; retry after Retry-After"
 // @Failure      500 {object} response.Error "Internal server error"
 // @Router       /items/filedata [post]
 // @Router       /items/filedata/{id} [put]
@@ -195,6 +241,24 @@ func (h *Handler) Push(c *gin.Context) {
 		return
 	}
 
+	size := c.Request.ContentLength
+	if size < 0 {
+		c.JSON(http.StatusLengthRequired, response.Error{
+			Messages: []string{"Content-Length is required"},
+		})
+		return
+	}
+	release, err := h.s.ReserveTransfer(c, size)
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+	defer release()
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, size)
+
 	// req holds the deserialized form data for the push request.
 	var req PushRequest
 	if err := c.ShouldBind(&req); err != nil {
@@ -222,6 +286,7 @@ func (h *Handler) Push(c *gin.Context) {
 		})
 		return
 	}
+	defer securebytes.Wipe(content)
 
 	fileDataID := uuid.Nil
 	if idStr := c.Param("id"); idStr != "" {
@@ -238,7 +303,9 @@ func (h *Handler) Push(c *gin.Context) {
 		UserID:      userID,
 		StorageKey:  req.StorageKey,
 		Description: req.Description,
+		Folder:      req.Folder,
 		Data:        content,
+		Reserved:    true,
 	})
 	if err != nil {
 		code, msgs := handleError(err, c)
@@ -250,3 +317,220 @@ func (h *Handler) Push(c *gin.Context) {
 
 	c.JSON(http.StatusCreated, PushResponse{ID: newID})
 }
+
+// Move moves a file to another folder or renames it.
+// @Summary      Move or rename file
+// @Description  Moves a file of the authenticated user to another existing folder and optionally renames it.
+// @Description  An empty folder moves the file to the root folder; an empty storage key keeps the current one.
+// .
+// @Tags         Files
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "File ID" format(uuid)
+// @Param        request body MoveFileRequest true "Target folder and storage key"
+// @Success      200 {object} FileData "File moved successfully"
+// @Failure      400 {object} response.Error "Bad request - invalid folder or storage key"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
+// @Failure      403 {object} response.Error "Forbidden - access to this file is denied"
+// @Failure      404 {object} response.Error "Not found - file or folder not found"
+// @Failure      409 {object} response.Error "Conflict - storage key is already taken"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /items/filedata/{id}/move [post]
+// .
+func (h *Handler) Move(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	userID, err := extractor.UserID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+
+	fileID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	// req holds the deserialized request body for the move request.
+	var req MoveFileRequest
+	if err := extractor.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	fd, err := h.s.MoveFile(c, filedata.MoveFileParams{
+		ID:         fileID,
+		UserID:     userID,
+		Folder:     req.Folder,
+		StorageKey: req.StorageKey,
+	})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, NewFileDataFromApp(fd))
+}
+
+// ListFolder retrieves the contents of a folder.
+// @Summary      List folder contents
+// @Description  Retrieves the subfolders and one page of the files directly inside a folder of the authenticated user.
+// @Description  Files are ordered by storage key, or in the manual order of the folder with sort=manual, and
+// @Description  returned without content.
+// .
+// @Tags         Files
+// @Produce      json
+// @Security     BearerAuth
+// @Param        path query string false "Folder path; empty lists the root folder"
+// @Param        offset query int false "Number of files to skip"
+// @Param        limit query int false "Maximum number of files returned (default 100, at most 200)"
+// @Param        sort query string false "File order; manual lists pinned and arranged files first" Enums(manual)
+// @Success      200 {object} FolderListResponse "Folder contents retrieved successfully"
+// @Failure      400 {object} response.Error "Bad request - invalid folder path or sort"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
+// @Failure      404 {object} response.Error "Not found - folder not found"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /items/folders [get]
+// .
+func (h *Handler) ListFolder(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	userID, err := extractor.UserID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+
+	// query holds the deserialized query parameters for the listing.
+	var query FolderQuery
+	if err := extractor.BindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+	offset, limit := query.page()
+
+	sort, err := extractor.Sort([]string{consts.SortManual})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	listing, err := h.s.ListFolder(c, filedata.ListFolderParams{
+		UserID:      userID,
+		Folder:      query.Path,
+		Offset:      offset,
+		Limit:       limit,
+		ManualOrder: sort == consts.SortManual,
+	})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, NewFolderListResponseFromApp(listing, offset, limit))
+}
+
+// CreateFolder creates a folder.
+// @Summary      Create folder
+// @Description  Creates a folder for the authenticated user below an existing parent folder
+// @Tags         Files
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body FolderRequest true "Folder path"
+// @Success      201 {object} Folder "Folder created successfully"
+// @Failure      400 {object} response.Error "Bad request - invalid folder path"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
+// @Failure      404 {object} response.Error "Not found - parent folder not found"
+// @Failure      409 {object} response.Error "Conflict - folder already exists"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /items/folders [post]
+// .
+func (h *Handler) CreateFolder(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	userID, err := extractor.UserID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+
+	// req holds the deserialized request body for the folder creation.
+	var req FolderRequest
+	if err := extractor.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	f, err := h.s.CreateFolder(c, filedata.CreateFolderParams{UserID: userID, Path: req.Path})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, NewFolderFromApp(f))
+}
+
+// MoveFolder renames or moves a folder.
+// @Summary      Rename or move folder
+// @Description  Changes the path of a folder of the authenticated user; the folders and files inside it move along.
+// @Description  The new parent folder must exist and must not lie inside the moved folder.
+// .
+// @Tags         Files
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Folder ID" format(uuid)
+// @Param        request body FolderRequest true "New folder path"
+// @Success      200 {object} Folder "Folder moved successfully"
+// @Failure      400 {object} response.Error "Bad request - invalid folder path or move into itself"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
+// @Failure      404 {object} response.Error "Not found - folder or parent folder not found"
+// @Failure      409 {object} response.Error "Conflict - folder already exists"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /items/folders/{id} [put]
+// .
+func (h *Handler) MoveFolder(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	userID, err := extractor.UserID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+
+	folderID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	// req holds the deserialized request body for the folder move.
+	var req FolderRequest
+	if err := extractor.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	f, err := h.s.MoveFolder(c, filedata.MoveFolderParams{ID: folderID, UserID: userID, Path: req.Path})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, NewFolderFromApp(f))
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"io"
 	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
@@ -21,9 +22,14 @@ import (
 
 // mockFileDataService implements filedata service for testing.
 type mockFileDataService struct {
-	pullFunc func(ctx context.Context, params filedata.PullParams) (*filedata.FileData, error)
-	listFunc func(ctx context.Context, params filedata.ListParams) ([]*filedata.FileData, error)
-	pushFunc func(ctx context.Context, params *filedata.PushParams) (uuid.UUID, error)
+	pullFunc         func(ctx context.Context, params filedata.PullParams) (*filedata.FileData, error)
+	listFunc         func(ctx context.Context, params filedata.ListParams) ([]*filedata.FileData, error)
+	pushFunc         func(ctx context.Context, params *filedata.PushParams) (uuid.UUID, error)
+	moveFunc         func(ctx context.Context, params filedata.MoveFileParams) (*filedata.FileData, error)
+	listFolderFunc   func(ctx context.Context, params filedata.ListFolderParams) (*filedata.FolderListing, error)
+	createFolderFunc func(ctx context.Context, params filedata.CreateFolderParams) (*filedata.Folder, error)
+	moveFolderFunc   func(ctx context.Context, params filedata.MoveFolderParams) (*filedata.Folder, error)
+	reserveFunc      func(ctx context.Context, size int64) (func(), error)
 }
 
 func (m *mockFileDataService) Pull(
@@ -53,6 +59,57 @@ func (m *mockFileDataService) Push(ctx context.Context, params *filedata.PushPar
 	return uuid.New(), nil
 }
 
+func (m *mockFileDataService) MoveFile(
+	ctx context.Context,
+	params filedata.MoveFileParams,
+) (*filedata.FileData, error) {
+	return m.moveFunc(ctx, params)
+}
+
+func (m *mockFileDataService) ListFolder(
+	ctx context.Context,
+	params filedata.ListFolderParams,
+) (*filedata.FolderListing, error) {
+	return m.listFolderFunc(ctx, params)
+}
+
+func (m *mockFileDataService) CreateFolder(
+	ctx context.Context,
+	params filedata.CreateFolderParams,
+) (*filedata.Folder, error) {
+	return m.createFolderFunc(ctx, params)
+}
+
+func (m *mockFileDataService) MoveFolder(
+	ctx context.Context,
+	params filedata.MoveFolderParams,
+) (*filedata.Folder, error) {
+	return m.moveFolderFunc(ctx, params)
+}
+
+func (m *mockFileDataService) ReserveTransfer(ctx context.Context, size int64) (func(), error) {
+	if m.reserveFunc != nil {
+		return m.reserveFunc(ctx, size)
+	}
+	return func() {}, nil
+}
+
+func (m *mockFileDataService) CheckUpload(context.Context, *filedata.PushParams, int64) (int64, error) {
+	return -1, nil
+}
+
+// countingReader counts the bytes read from the wrapped reader.
+type countingReader struct {
+	r    io.Reader
+	read int
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.read += n
+	return n, err
+}
+
 func TestNewHandler(t *testing.T) {
 	t.Parallel()
 
@@ -197,6 +254,7 @@ func TestHandler_List(t *testing.T) {
 			mockService: &mockFileDataService{
 				listFunc: func(ctx context.Context, params filedata.ListParams) ([]*filedata.FileData, error) {
 					assert.Equal(t, userID, params.UserID)
+					assert.True(t, params.ExcludeArchived)
 					return []*filedata.FileData{
 						{
 							ID:          uuid.New(),
@@ -397,6 +455,51 @@ func TestHandler_Push(t *testing.T) {
 			},
 			expectedStatus: http.StatusInternalServerError,
 		},
+		{
+			name: "missing content length",
+			setupContext: func(c *gin.Context) {
+				c.Set(consts.CtxKeyUserID, userID)
+			},
+			setupRequest: func() *http.Request {
+				req := httptest.NewRequest(http.MethodPost, "/items/filedata", strings.NewReader("content"))
+				req.ContentLength = -1
+				return req
+			},
+			mockService: &mockFileDataService{
+				reserveFunc: func(ctx context.Context, size int64) (func(), error) {
+					t.Error("transfer reserved without a declared size")
+					return func() {}, nil
+				},
+			},
+			expectedStatus: http.StatusLengthRequired,
+		},
+		{
+			name: "body longer than content length",
+			setupContext: func(c *gin.Context) {
+				c.Set(consts.CtxKeyUserID, userID)
+			},
+			setupRequest: func() *http.Request {
+				body := &bytes.Buffer{}
+				writer := multipart.NewWriter(body)
+				part, err := writer.CreateFormFile("file", "test.txt")
+				require.NoError(t, err)
+				_, err = part.Write(testContent)
+				require.NoError(t, err)
+				require.NoError(t, writer.Close())
+
+				req := httptest.NewRequest(http.MethodPost, "/items/filedata", body)
+				req.Header.Set("Content-Type", writer.FormDataContentType())
+				req.ContentLength = 16
+				return req
+			},
+			mockService: &mockFileDataService{
+				pushFunc: func(ctx context.Context, params *filedata.PushParams) (uuid.UUID, error) {
+					t.Error("upload beyond its declared size was stored")
+					return uuid.Nil, nil
+				},
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
 	}
 
 	for _, tt := range tests {
@@ -420,3 +523,298 @@ func TestHandler_Push(t *testing.T) {
 		})
 	}
 }
+
+func TestHandler_Push_TransferBusy(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+	limiter := filedata.NewLimiter(filedata.TransferLimits{Workers: 2, MemoryBudget: 1024})
+	app := filedata.NewService(nil, nil, nil, nil, nil, 0, limiter, nil, nil, nil, nil)
+
+	// The first upload holds most of the memory budget while the second arrives.
+	release, err := app.ReserveTransfer(context.Background(), 1000)
+	require.NoError(t, err)
+	defer release()
+
+	form := &bytes.Buffer{}
+	writer := multipart.NewWriter(form)
+	part, err := writer.CreateFormFile("file", "test.txt")
+	require.NoError(t, err)
+	_, err = part.Write(bytes.Repeat([]byte("x"), 512))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	body := &countingReader{r: form}
+	req := httptest.NewRequest(http.MethodPost, "/items/filedata", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.ContentLength = int64(form.Len())
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Set(consts.CtxKeyUserID, uuid.New())
+
+	NewHandler(&mockFileDataService{
+		reserveFunc: app.ReserveTransfer,
+		pushFunc: func(ctx context.Context, params *filedata.PushParams) (uuid.UUID, error) {
+			t.Error("upload over the memory budget was stored")
+			return uuid.Nil, nil
+		},
+	}).Push(c)
+
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+	assert.Zero(t, body.read)
+}
+
+func TestHandler_Move(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	fileID := uuid.New()
+
+	tests := []struct {
+		moveFunc       func(ctx context.Context, params filedata.MoveFileParams) (*filedata.FileData, error)
+		name           string
+		id             string
+		body           string
+		expectedStatus int
+	}{
+		{
+			name: "moved",
+			id:   fileID.String(),
+			body: `{"folder":"docs","storage_key":"renamed.txt"}`,
+			moveFunc: func(_ context.Context, p filedata.MoveFileParams) (*filedata.FileData, error) {
+				assert.Equal(t, filedata.MoveFileParams{
+					ID:         fileID,
+					UserID:     userID,
+					Folder:     "docs",
+					StorageKey: "renamed.txt",
+				}, p)
+				return &filedata.FileData{ID: fileID, StorageKey: p.StorageKey, Folder: p.Folder}, nil
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{name: "invalid_id", id: "not-a-uuid", body: `{}`, expectedStatus: http.StatusBadRequest},
+		{name: "invalid_body", id: fileID.String(), body: `{`, expectedStatus: http.StatusBadRequest},
+		{
+			name: "storage_key_taken",
+			id:   fileID.String(),
+			body: `{"storage_key":"taken.txt"}`,
+			moveFunc: func(context.Context, filedata.MoveFileParams) (*filedata.FileData, error) {
+				return nil, filedata.ErrFileStorageKeyTaken
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name: "folder_not_found",
+			id:   fileID.String(),
+			body: `{"folder":"music"}`,
+			moveFunc: func(context.Context, filedata.MoveFileParams) (*filedata.FileData, error) {
+				return nil, filedata.ErrFolderNotFound
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Set(consts.CtxKeyUserID, userID)
+			c.Params = gin.Params{{Key: "id", Value: tt.id}}
+			c.Request = httptest.NewRequest(http.MethodPost, "/filedata/"+tt.id+"/move", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			NewHandler(&mockFileDataService{moveFunc: tt.moveFunc}).Move(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestHandler_ListFolder(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+
+	tests := []struct {
+		listErr        error
+		name           string
+		query          string
+		wantParams     filedata.ListFolderParams
+		expectedStatus int
+	}{
+		{
+			name:           "default_page",
+			query:          "path=docs",
+			wantParams:     filedata.ListFolderParams{UserID: userID, Folder: "docs", Limit: 100},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "limit_capped",
+			query:          "offset=20&limit=1000",
+			wantParams:     filedata.ListFolderParams{UserID: userID, Offset: 20, Limit: 200},
+			expectedStatus: http.StatusOK,
+		},
+		{name: "invalid_limit", query: "limit=many", expectedStatus: http.StatusBadRequest},
+		{
+			name:           "folder_not_found",
+			query:          "path=music",
+			wantParams:     filedata.ListFolderParams{UserID: userID, Folder: "music", Limit: 100},
+			listErr:        filedata.ErrFolderNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			svc := &mockFileDataService{
+				listFolderFunc: func(_ context.Context, p filedata.ListFolderParams) (*filedata.FolderListing, error) {
+					assert.Equal(t, tt.wantParams, p)
+					if tt.listErr != nil {
+						return nil, tt.listErr
+					}
+					return &filedata.FolderListing{
+						Folder:  p.Folder,
+						Folders: []*filedata.Folder{{ID: uuid.New(), Path: "docs/taxes"}},
+						Files:   []*filedata.FileData{{ID: uuid.New(), StorageKey: "a.txt", Folder: p.Folder}},
+						Total:   7,
+					}, nil
+				},
+			}
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Set(consts.CtxKeyUserID, userID)
+			c.Request = httptest.NewRequest(http.MethodGet, "/folders/?"+tt.query, nil)
+
+			NewHandler(svc).ListFolder(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if w.Code == http.StatusOK {
+				assert.Contains(t, w.Body.String(), `"total":7`)
+				assert.Contains(t, w.Body.String(), `"path":"docs/taxes"`)
+				assert.Contains(t, w.Body.String(), `"storage_key":"a.txt"`)
+			}
+		})
+	}
+}
+
+func TestHandler_CreateFolder(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+
+	tests := []struct {
+		createErr      error
+		name           string
+		body           string
+		expectedStatus int
+	}{
+		{name: "created", body: `{"path":"docs"}`, expectedStatus: http.StatusCreated},
+		{name: "missing_path", body: `{}`, expectedStatus: http.StatusBadRequest},
+		{name: "exists", body: `{"path":"docs"}`, createErr: filedata.ErrFolderExists, expectedStatus: http.StatusConflict},
+		{
+			name:           "invalid_path",
+			body:           `{"path":"a/../b"}`,
+			createErr:      filedata.ErrFileIncorrectFolder,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			svc := &mockFileDataService{
+				createFolderFunc: func(_ context.Context, p filedata.CreateFolderParams) (*filedata.Folder, error) {
+					assert.Equal(t, userID, p.UserID)
+					if tt.createErr != nil {
+						return nil, tt.createErr
+					}
+					return &filedata.Folder{ID: uuid.New(), UserID: p.UserID, Path: p.Path}, nil
+				},
+			}
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Set(consts.CtxKeyUserID, userID)
+			c.Request = httptest.NewRequest(http.MethodPost, "/folders/", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			NewHandler(svc).CreateFolder(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestHandler_MoveFolder(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	folderID := uuid.New()
+
+	tests := []struct {
+		moveErr        error
+		name           string
+		id             string
+		body           string
+		expectedStatus int
+	}{
+		{name: "moved", id: folderID.String(), body: `{"path":"archive/docs"}`, expectedStatus: http.StatusOK},
+		{name: "invalid_id", id: "not-a-uuid", body: `{"path":"docs"}`, expectedStatus: http.StatusBadRequest},
+		{name: "missing_path", id: folderID.String(), body: `{}`, expectedStatus: http.StatusBadRequest},
+		{
+			name:           "into_itself",
+			id:             folderID.String(),
+			body:           `{"path":"docs/sub/docs"}`,
+			moveErr:        filedata.ErrFolderMoveIntoItself,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "not_found",
+			id:             folderID.String(),
+			body:           `{"path":"papers"}`,
+			moveErr:        filedata.ErrFolderNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			svc := &mockFileDataService{
+				moveFolderFunc: func(_ context.Context, p filedata.MoveFolderParams) (*filedata.Folder, error) {
+					assert.Equal(t, folderID, p.ID)
+					assert.Equal(t, userID, p.UserID)
+					if tt.moveErr != nil {
+						return nil, tt.moveErr
+					}
+					return &filedata.Folder{ID: p.ID, UserID: p.UserID, Path: p.Path}, nil
+				},
+			}
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Set(consts.CtxKeyUserID, userID)
+			c.Params = gin.Params{{Key: "id", Value: tt.id}}
+			c.Request = httptest.NewRequest(http.MethodPut, "/folders/"+tt.id, strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			NewHandler(svc).MoveFolder(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
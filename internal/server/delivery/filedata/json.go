@@ -0,0 +1,34 @@
+package filedata
+
+import "github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/jsonenc"
+
+// AppendJSON appends the JSON encoding of the file metadata, and of its content when present.
+func (f *FileData) AppendJSON(dst []byte) []byte {
+	if f == nil {
+		return jsonenc.Null(dst)
+	}
+	dst = append(dst, '{')
+	dst = jsonenc.Time(jsonenc.Key(dst, "updated_at"), f.UpdatedAt)
+	dst = jsonenc.String(jsonenc.Key(dst, "storage_key"), f.StorageKey)
+	dst = jsonenc.String(jsonenc.Key(dst, "hash_sum"), f.HashSum)
+	dst = jsonenc.String(jsonenc.Key(dst, "description"), f.Description)
+	dst = jsonenc.String(jsonenc.Key(dst, "folder"), f.Folder)
+	if len(f.Data) > 0 {
+		dst = jsonenc.Bytes(jsonenc.Key(dst, "data"), f.Data)
+	}
+	dst = jsonenc.UUID(jsonenc.Key(dst, "id"), f.ID)
+	dst = jsonenc.UUID(jsonenc.Key(dst, "user_id"), f.UserID)
+	dst = jsonenc.Int(jsonenc.Key(dst, "size"), f.Size)
+	dst = jsonenc.Bool(jsonenc.Key(dst, "archived"), f.Archived)
+	return append(dst, '}')
+}
+
+// AppendJSON appends the JSON encoding of the list response.
+func (r *ListResponse) AppendJSON(dst []byte) []byte {
+	if r == nil {
+		return jsonenc.Null(dst)
+	}
+	dst = append(dst, '{')
+	dst = jsonenc.Array(jsonenc.Key(dst, "files"), r.Files)
+	return append(dst, '}')
+}
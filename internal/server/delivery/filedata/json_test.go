@@ -0,0 +1,51 @@
+package filedata
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListResponse_AppendJSON(t *testing.T) {
+	t.Parallel()
+
+	meta := &FileData{
+		ID:          uuid.New(),
+		UserID:      uuid.New(),
+		StorageKey:  "tax <2024>.pdf",
+		HashSum:     "d41d8cd98f00b204e9800998ecf8427e",
+		Description: "Return & receipts",
+		Archived:    true,
+		Folder:      "docs/taxes",
+		Size:        2048,
+		UpdatedAt:   time.Date(2025, 5, 6, 7, 8, 9, 1, time.UTC),
+	}
+	withData := *meta
+	withData.Data = []byte("%PDF-1.7\x00\xff")
+
+	tests := []struct {
+		resp *ListResponse
+		name string
+	}{
+		{name: "metadata", resp: &ListResponse{Files: []*FileData{meta}}},
+		{name: "with content", resp: &ListResponse{Files: []*FileData{&withData}}},
+		{name: "zero file", resp: &ListResponse{Files: []*FileData{{Data: []byte{}}, nil}}},
+		{name: "empty", resp: &ListResponse{Files: []*FileData{}}},
+		{name: "nil list", resp: &ListResponse{}},
+		{name: "nil", resp: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			want, err := json.Marshal(tt.resp)
+			require.NoError(t, err)
+			assert.Equal(t, string(want), string(tt.resp.AppendJSON(nil)))
+		})
+	}
+}
@@ -1,14 +1,25 @@
 package filedata
 
-import "github.com/gin-gonic/gin"
+import (
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/util"
+	"github.com/gin-gonic/gin"
+)
 
-// RegisterRoutes registers file data management routes with the provided router.
-func RegisterRoutes(r gin.IRouter, h *Handler) {
+// RegisterRoutes registers file data and file folder management routes with the provided router.
+// The reveal middleware runs on the route downloading a single file.
+func RegisterRoutes(r gin.IRouter, h *Handler, reveal ...gin.HandlerFunc) {
 	filedata := r.Group("/filedata")
 	{
-		filedata.GET("/:id", h.Pull)
+		filedata.GET("/:id", util.Chain(reveal, h.Pull)...)
 		filedata.GET("/", h.List)
 		filedata.POST("/", h.Push)
 		filedata.PUT("/:id", h.Push)
+		filedata.POST("/:id/move", h.Move)
+	}
+	folders := r.Group("/folders")
+	{
+		folders.GET("/", h.ListFolder)
+		folders.POST("/", h.CreateFolder)
+		folders.PUT("/:id", h.MoveFolder)
 	}
 }
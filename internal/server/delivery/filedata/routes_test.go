@@ -31,6 +31,30 @@ func (m *mockService) Push(ctx context.Context, params *appfiledata.PushParams)
 	return uuid.Nil, nil
 }
 
+func (m *mockService) MoveFile(context.Context, appfiledata.MoveFileParams) (*appfiledata.FileData, error) {
+	return nil, nil
+}
+
+func (m *mockService) ListFolder(context.Context, appfiledata.ListFolderParams) (*appfiledata.FolderListing, error) {
+	return nil, nil
+}
+
+func (m *mockService) CreateFolder(context.Context, appfiledata.CreateFolderParams) (*appfiledata.Folder, error) {
+	return nil, nil
+}
+
+func (m *mockService) MoveFolder(context.Context, appfiledata.MoveFolderParams) (*appfiledata.Folder, error) {
+	return nil, nil
+}
+
+func (m *mockService) ReserveTransfer(context.Context, int64) (func(), error) {
+	return func() {}, nil
+}
+
+func (m *mockService) CheckUpload(context.Context, *appfiledata.PushParams, int64) (int64, error) {
+	return -1, nil
+}
+
 func TestRegisterRoutes(t *testing.T) {
 	t.Parallel()
 
@@ -62,9 +86,13 @@ func TestRegisterRoutes(t *testing.T) {
 				assert.True(t, routeMap["GET /filedata/"], "GET / route should be registered")
 				assert.True(t, routeMap["POST /filedata/"], "POST / route should be registered")
 				assert.True(t, routeMap["PUT /filedata/:id"], "PUT /:id route should be registered")
+				assert.True(t, routeMap["POST /filedata/:id/move"], "POST /:id/move route should be registered")
+				assert.True(t, routeMap["GET /folders/"], "GET /folders/ route should be registered")
+				assert.True(t, routeMap["POST /folders/"], "POST /folders/ route should be registered")
+				assert.True(t, routeMap["PUT /folders/:id"], "PUT /folders/:id route should be registered")
 
 				// Verify we have at least the expected number of routes
-				assert.GreaterOrEqual(t, len(routes), 4, "Should have at least 4 routes registered")
+				assert.GreaterOrEqual(t, len(routes), 8, "Should have at least 8 routes registered")
 			},
 		},
 	}
@@ -0,0 +1,9 @@
+package health
+
+// Status represents the health of the application.
+type Status struct {
+	// Status contains the health of the application.
+	Status string `json:"status"           example:"ok"`
+	// UpdateAvailable determines whether a newer server release is available.
+	UpdateAvailable bool `json:"update_available" example:"false"`
+}
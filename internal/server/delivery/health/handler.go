@@ -1,28 +1,50 @@
 package health
 
 import (
+	"context"
 	"net/http"
 
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/updatecheck"
 	"github.com/gin-gonic/gin"
 )
 
+// statusOK is the status reported by a healthy server.
+const statusOK = "ok"
+
+// UpdateChecker defines the server update check interface.
+type UpdateChecker interface {
+	// Status reports the result of the latest update check.
+	Status(context.Context) (*updatecheck.Status, error)
+}
+
 // Handler provides HTTP endpoints for application health checking.
-type Handler struct{}
+type Handler struct {
+	// updates reports available server updates; nil never reports one.
+	updates UpdateChecker
+}
 
-// NewHandler creates a new health check handler instance.
-func NewHandler() *Handler {
-	return &Handler{}
+// NewHandler creates a new health check handler instance. The update checker may be nil.
+func NewHandler(updates UpdateChecker) *Handler {
+	return &Handler{updates: updates}
 }
 
 // HealthCheck performs application health check.
 // @Summary      Health check
-// @Description  Returns HTTP 200 if the application is healthy and running
+// @Description  Returns HTTP 200 if the application is healthy and running, and whether a newer server release
+// @Description  is available. Release details are reported by the admin API only.
+// .
 // @Tags         System
 // @Accept       json
 // @Produce      json
-// @Success      200 "Application is healthy"
+// @Success      200 {object} Status "Application is healthy"
 // @Router       /health [get]
 // .
 func (h *Handler) HealthCheck(c *gin.Context) {
-	c.Status(http.StatusOK)
+	status := Status{Status: statusOK}
+	if h.updates != nil {
+		if updates, err := h.updates.Status(c); err == nil {
+			status.UpdateAvailable = updates.Available
+		}
+	}
+	c.JSON(http.StatusOK, status)
 }
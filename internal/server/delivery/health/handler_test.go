@@ -1,10 +1,13 @@
 package health
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/updatecheck"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -28,7 +31,7 @@ func TestNewHandler(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			got := NewHandler()
+			got := NewHandler(nil)
 			require.NotNil(t, got)
 			assert.Equal(t, tt.want, got)
 		})
@@ -62,7 +65,7 @@ func TestHandler_HealthCheck(t *testing.T) {
 
 			// Setup
 			gin.SetMode(gin.TestMode)
-			handler := NewHandler()
+			handler := NewHandler(nil)
 
 			// Create gin router and register endpoint
 			router := gin.New()
@@ -84,12 +87,68 @@ func TestHandler_HealthCheck(t *testing.T) {
 	}
 }
 
+// mockUpdateChecker implements UpdateChecker for testing.
+type mockUpdateChecker struct {
+	err       error
+	available bool
+}
+
+func (m *mockUpdateChecker) Status(context.Context) (*updatecheck.Status, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &updatecheck.Status{Latest: "v1.5.0", Available: m.available, Enabled: true}, nil
+}
+
+func TestHandler_HealthCheck_Updates(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		updates  UpdateChecker
+		name     string
+		wantBody string
+	}{
+		{name: "update checks disabled", wantBody: `{"status":"ok","update_available":false}`},
+		{
+			name:     "update available",
+			updates:  &mockUpdateChecker{available: true},
+			wantBody: `{"status":"ok","update_available":true}`,
+		},
+		{
+			name:     "up to date",
+			updates:  &mockUpdateChecker{},
+			wantBody: `{"status":"ok","update_available":false}`,
+		},
+		{
+			name:     "update check failure",
+			updates:  &mockUpdateChecker{err: errors.New("unavailable")},
+			wantBody: `{"status":"ok","update_available":false}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/health", nil)
+
+			NewHandler(tt.updates).HealthCheck(c)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.JSONEq(t, tt.wantBody, w.Body.String())
+		})
+	}
+}
+
 func TestHandler_HealthCheck_WithServer(t *testing.T) {
 	t.Parallel()
 
 	// Test using httptest.Server as recommended in Issue #16
 	gin.SetMode(gin.TestMode)
-	handler := NewHandler()
+	handler := NewHandler(nil)
 
 	router := gin.New()
 	router.GET("/health", handler.HealthCheck)
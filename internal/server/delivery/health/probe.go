@@ -0,0 +1,57 @@
+package health
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+)
+
+// probePath is the path of the health endpoint under the API prefix.
+const probePath = "/api/health"
+
+// ErrUnhealthy is returned when the health endpoint answers with a status other than 200.
+var ErrUnhealthy = errors.New("server is unhealthy")
+
+// Probe requests the health endpoint of the server listening on addr from the same host, the way a container
+// health check does. Over TLS the probe presents serverName, which certificates obtained via ACME are selected
+// by, and does not verify the certificate: it is issued for the public host name, while the probe connects to
+// the loopback address.
+func Probe(ctx context.Context, addr, serverName string, tlsEnabled bool) error {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return fmt.Errorf("invalid listening address %q: %w", addr, err)
+	}
+	if host == "" || net.ParseIP(host).IsUnspecified() {
+		host = "127.0.0.1"
+	}
+
+	scheme := "http"
+	// transport connects to the server without reusing connections.
+	transport := &http.Transport{DisableKeepAlives: true}
+	if tlsEnabled {
+		scheme = "https"
+		transport.TLSClientConfig = &tls.Config{
+			ServerName:         serverName,
+			InsecureSkipVerify: true, //nolint:gosec // Loopback probe of the own server
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		scheme+"://"+net.JoinHostPort(host, port)+probePath, http.NoBody)
+	if err != nil {
+		return fmt.Errorf("failed to create health request: %w", err)
+	}
+	resp, err := (&http.Client{Transport: transport}).Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach server: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: status %d", ErrUnhealthy, resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package health
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProbe(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		wantErr    error
+		name       string
+		path       string
+		status     int
+		tlsEnabled bool
+		wantFail   bool
+	}{
+		{name: "healthy over http", path: probePath, status: http.StatusOK},
+		{name: "healthy over https", path: probePath, status: http.StatusOK, tlsEnabled: true},
+		{
+			name:    "unhealthy status",
+			path:    probePath,
+			status:  http.StatusServiceUnavailable,
+			wantErr: ErrUnhealthy,
+		},
+		{name: "other path", path: "/health", status: http.StatusOK, wantErr: ErrUnhealthy},
+		{name: "scheme mismatch", path: probePath, status: http.StatusOK, tlsEnabled: true, wantFail: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mux := http.NewServeMux()
+			mux.HandleFunc(tt.path, func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(tt.status) })
+			mux.HandleFunc("/", func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusNotFound) })
+			srv := httptest.NewUnstartedServer(mux)
+			if tt.tlsEnabled && !tt.wantFail {
+				srv.StartTLS()
+			} else {
+				srv.Start()
+			}
+			t.Cleanup(srv.Close)
+
+			u, err := url.Parse(srv.URL)
+			require.NoError(t, err)
+			_, port, err := net.SplitHostPort(u.Host)
+			require.NoError(t, err)
+
+			err = Probe(context.Background(), ":"+port, "vault.example.com", tt.tlsEnabled)
+			switch {
+			case tt.wantErr != nil:
+				require.ErrorIs(t, err, tt.wantErr)
+			case tt.wantFail:
+				assert.Error(t, err)
+			default:
+				assert.NoError(t, err)
+			}
+		})
+	}
+
+	t.Run("invalid address", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Error(t, Probe(context.Background(), "8080", "", false))
+	})
+}
@@ -35,7 +35,7 @@ func TestRegisterRoutes(t *testing.T) {
 			gin.SetMode(gin.TestMode)
 			router := gin.New()
 			group := router.Group("")
-			handler := NewHandler()
+			handler := NewHandler(nil)
 
 			// Register routes
 			RegisterRoutes(group, handler)
@@ -64,7 +64,7 @@ func TestRegisterRoutes_Integration(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	router := gin.New()
 	group := router.Group("/api/v1")
-	handler := NewHandler()
+	handler := NewHandler(nil)
 
 	// Register routes
 	RegisterRoutes(group, handler)
@@ -4,13 +4,19 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
 )
 
+// challengeReadHeaderTimeout limits how long the ACME challenge listener waits for request headers.
+const challengeReadHeaderTimeout = 10 * time.Second
+
 // RouteConfigurator defines the interface for registering routes on a Gin router.
 type RouteConfigurator interface {
 	// RegisterRoutes registers all routes on the provided Gin router instance.
@@ -23,12 +29,51 @@ type MiddlewareConfigurator interface {
 	RegisterMiddlewares(router *gin.Engine)
 }
 
+// ACMEConfig contains settings of automatic certificate management via ACME HTTP-01 challenges.
+type ACMEConfig struct {
+	// Domains lists the host names certificates are requested for.
+	Domains []string
+	// Email specifies the account contact address passed to the certificate authority.
+	Email string
+	// CacheDir specifies the directory where obtained certificates and the account key are stored.
+	CacheDir string
+	// DirectoryURL specifies the ACME directory endpoint (empty uses Let's Encrypt production).
+	DirectoryURL string
+	// HTTPAddr specifies the address of the plain HTTP listener answering challenges.
+	HTTPAddr string
+}
+
+// ConnectionConfig contains connection handling settings of the HTTP server.
+// Zero timeouts mean no limit, which keeps long file uploads and downloads working.
+type ConnectionConfig struct {
+	// ReadTimeout limits reading an entire request including the body.
+	ReadTimeout time.Duration
+	// ReadHeaderTimeout limits reading request headers.
+	ReadHeaderTimeout time.Duration
+	// WriteTimeout limits writing a response.
+	WriteTimeout time.Duration
+	// IdleTimeout limits how long an idle keep-alive connection stays open.
+	IdleTimeout time.Duration
+	// MaxHeaderBytes limits the size of request headers (0 uses the net/http default).
+	MaxHeaderBytes int
+	// HTTP2Enabled determines whether HTTP/2 is negotiated on TLS connections.
+	HTTP2Enabled bool
+	// KeepAlivesEnabled determines whether connections are reused between requests.
+	KeepAlivesEnabled bool
+}
+
 // HTTPServer represents an HTTP server with TLS support and graceful shutdown capabilities.
 type HTTPServer struct {
 	// l is the structured logger for server operations.
 	l *zap.SugaredLogger
 	// server is the underlying HTTP server instance.
 	server *http.Server
+	// challengeServer answers ACME HTTP-01 challenges and redirects other requests to HTTPS; nil without ACME.
+	challengeServer *http.Server
+	// listener is the socket passed by the service manager; nil listens on the configured address.
+	listener net.Listener
+	// challengeListener is the passed socket of the ACME challenge server; nil listens on its address.
+	challengeListener net.Listener
 	// certFile is the path to the TLS certificate file.
 	certFile string
 	// keyFile is the path to the TLS private key file.
@@ -42,6 +87,8 @@ type HTTPServer struct {
 }
 
 // NewHTTPServer creates a new HTTP server instance with the provided configuration.
+// When TLS is enabled and acmeCfg is not nil, certificates are obtained and renewed automatically
+// instead of being loaded from certFile and keyFile.
 func NewHTTPServer(
 	logger *zap.SugaredLogger,
 	rc RouteConfigurator,
@@ -52,17 +99,16 @@ func NewHTTPServer(
 	tlsEnabled bool,
 	certFile string,
 	keyFile string,
+	acmeCfg *ACMEConfig,
+	connCfg ConnectionConfig,
 ) *HTTPServer {
 	r := gin.New()
 	mc.RegisterMiddlewares(r)
 	rc.RegisterRoutes(r)
 
 	s := &HTTPServer{
-		l: logger,
-		server: &http.Server{
-			Addr:    addr,
-			Handler: r,
-		},
+		l:            logger,
+		server:       newServer(addr, r, connCfg),
 		startTimeout: startTimeout,
 		stopTimeout:  stopTimeout,
 		tlsEnabled:   tlsEnabled,
@@ -70,24 +116,95 @@ func NewHTTPServer(
 		keyFile:      keyFile,
 	}
 
+	if tlsEnabled && acmeCfg != nil {
+		m := newCertManager(acmeCfg)
+		s.server.TLSConfig = m.TLSConfig()
+		s.challengeServer = &http.Server{
+			Addr:              acmeCfg.HTTPAddr,
+			Handler:           m.HTTPHandler(nil),
+			ReadHeaderTimeout: challengeReadHeaderTimeout,
+		}
+	}
+
 	return s
 }
 
+// newServer creates the underlying http.Server with the connection settings applied.
+func newServer(addr string, h http.Handler, cfg ConnectionConfig) *http.Server {
+	protocols := new(http.Protocols)
+	protocols.SetHTTP1(true)
+	protocols.SetHTTP2(cfg.HTTP2Enabled)
+
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           h,
+		ReadTimeout:       cfg.ReadTimeout,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+		MaxHeaderBytes:    cfg.MaxHeaderBytes,
+		Protocols:         protocols,
+	}
+	srv.SetKeepAlivesEnabled(cfg.KeepAlivesEnabled)
+	return srv
+}
+
+// newCertManager creates an autocert manager restricted to the configured domains.
+func newCertManager(cfg *ACMEConfig) *autocert.Manager {
+	m := &autocert.Manager{
+		Prompt:     autocert.AcceptTOS,
+		HostPolicy: autocert.HostWhitelist(cfg.Domains...),
+		Cache:      autocert.DirCache(cfg.CacheDir),
+		Email:      cfg.Email,
+	}
+	if cfg.DirectoryURL != "" {
+		m.Client = &acme.Client{DirectoryURL: cfg.DirectoryURL}
+	}
+	return m
+}
+
+// UseListeners makes the server accept connections on sockets opened by the service manager instead of
+// listening on the configured addresses. The first socket serves the API; with ACME the second one answers the
+// challenges. Without sockets the server listens as configured.
+func (s *HTTPServer) UseListeners(listeners []net.Listener) error {
+	limit := 1
+	if s.challengeServer != nil {
+		limit++
+	}
+	if len(listeners) > limit {
+		return fmt.Errorf("%d sockets passed, the server accepts at most %d", len(listeners), limit)
+	}
+	if len(listeners) > 0 {
+		s.listener = listeners[0]
+	}
+	if len(listeners) > 1 {
+		s.challengeListener = listeners[1]
+	}
+	return nil
+}
+
 // Start starts the HTTP server and returns an error if startup fails.
 func (s *HTTPServer) Start(ctx context.Context) error {
-	errChan := make(chan error, 1)
+	errChan := make(chan error, 2)
 	go func() {
 		if err := s.listen(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 			errChan <- err
 		}
 	}()
+	if s.challengeServer != nil {
+		go func() {
+			if err := s.listenChallenge(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				errChan <- err
+			}
+		}()
+	}
 
 	if err := s.startCheck(ctx, errChan); err != nil {
 		s.l.Errorf("Failed to start HTTP server: %v", err)
 		return fmt.Errorf("HTTP server start failed: %w", err)
 	}
 
-	s.l.Infof("%s server started successfully on %s", s.getProtocol(), s.server.Addr)
+	s.l.Infof("%s server started successfully on %s", s.getProtocol(), s.address())
 	return nil
 }
 
@@ -96,7 +213,7 @@ func (s *HTTPServer) Stop(ctx context.Context) error {
 	shutdownCtx, cancel := context.WithTimeout(ctx, s.stopTimeout)
 	defer cancel()
 
-	if err := s.server.Shutdown(shutdownCtx); err != nil {
+	if err := s.shutdown(shutdownCtx); err != nil {
 		s.l.Errorf("Failed to gracefully shutdown HTTP server: %v", err)
 		return fmt.Errorf("failed to gracefully shutdown HTTP server: %w", err)
 	}
@@ -112,7 +229,7 @@ func (s *HTTPServer) startCheck(ctx context.Context, errChan chan error) error {
 	case <-time.After(s.startTimeout):
 		return nil
 	case <-ctx.Done():
-		if err := s.server.Shutdown(context.Background()); err != nil {
+		if err := s.shutdown(context.Background()); err != nil {
 			s.l.Errorf("Failed to shutdown server during start cancellation: %v", err)
 			return fmt.Errorf("failed to shutdown HTTP server during start cancellation: %w", err)
 		}
@@ -120,6 +237,15 @@ func (s *HTTPServer) startCheck(ctx context.Context, errChan chan error) error {
 	}
 }
 
+// shutdown gracefully stops the main listener and the ACME challenge listener if present.
+func (s *HTTPServer) shutdown(ctx context.Context) error {
+	err := s.server.Shutdown(ctx)
+	if s.challengeServer != nil {
+		err = errors.Join(err, s.challengeServer.Shutdown(ctx))
+	}
+	return err // nolint:wrapcheck // Callers wrap the error with context.
+}
+
 // init sets Gin framework to release mode for production deployments.
 func init() {
 	gin.SetMode(gin.ReleaseMode)
@@ -141,8 +267,22 @@ func (s *HTTPServer) getProtocol() string {
 	return "HTTP"
 }
 
+// address returns the address the server accepts connections on.
+func (s *HTTPServer) address() string {
+	if s.listener != nil {
+		return s.listener.Addr().String() + " (socket activation)"
+	}
+	return s.server.Addr
+}
+
 // listenHTTP starts the HTTP server listener.
 func (s *HTTPServer) listenHTTP() error {
+	if s.listener != nil {
+		if err := s.server.Serve(s.listener); err != nil {
+			return fmt.Errorf("failed to start HTTP server: %w", err)
+		}
+		return nil
+	}
 	if err := s.server.ListenAndServe(); err != nil {
 		return fmt.Errorf("failed to start HTTP server: %w", err)
 	}
@@ -150,9 +290,34 @@ func (s *HTTPServer) listenHTTP() error {
 }
 
 // listenHTTPS starts the HTTPS server listener with TLS certificates.
+// Certificates come from the ACME manager when configured, or from the certificate files otherwise.
 func (s *HTTPServer) listenHTTPS() error {
-	if err := s.server.ListenAndServeTLS(s.certFile, s.keyFile); err != nil {
+	certFile, keyFile := s.certFile, s.keyFile
+	if s.challengeServer != nil {
+		certFile, keyFile = "", ""
+	}
+	if s.listener != nil {
+		if err := s.server.ServeTLS(s.listener, certFile, keyFile); err != nil {
+			return fmt.Errorf("failed to start HTTPS server: %w", err)
+		}
+		return nil
+	}
+	if err := s.server.ListenAndServeTLS(certFile, keyFile); err != nil {
 		return fmt.Errorf("failed to start HTTPS server: %w", err)
 	}
 	return nil
 }
+
+// listenChallenge starts the plain HTTP listener answering ACME HTTP-01 challenges.
+func (s *HTTPServer) listenChallenge() error {
+	if s.challengeListener != nil {
+		if err := s.challengeServer.Serve(s.challengeListener); err != nil {
+			return fmt.Errorf("failed to start ACME challenge server: %w", err)
+		}
+		return nil
+	}
+	if err := s.challengeServer.ListenAndServe(); err != nil {
+		return fmt.Errorf("failed to start ACME challenge server: %w", err)
+	}
+	return nil
+}
@@ -2,7 +2,9 @@ package delivery
 
 import (
 	"context"
+	"net"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
@@ -113,6 +115,8 @@ func TestNewHTTPServer(t *testing.T) {
 				tt.tlsEnabled,
 				tt.certFile,
 				tt.keyFile,
+				nil,
+				ConnectionConfig{KeepAlivesEnabled: true},
 			)
 
 			require.NotNil(t, server)
@@ -184,6 +188,8 @@ func TestHTTPServer_Start(t *testing.T) {
 				tt.tlsEnabled,
 				"nonexistent-cert.pem",
 				"nonexistent-key.pem",
+				nil,
+				ConnectionConfig{KeepAlivesEnabled: true},
 			)
 
 			ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
@@ -249,6 +255,8 @@ func TestHTTPServer_Stop(t *testing.T) {
 				false,
 				"",
 				"",
+				nil,
+				ConnectionConfig{KeepAlivesEnabled: true},
 			)
 
 			ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
@@ -304,6 +312,8 @@ func TestHTTPServer_GetProtocol(t *testing.T) {
 				tt.tlsEnabled,
 				"cert.pem",
 				"key.pem",
+				nil,
+				ConnectionConfig{KeepAlivesEnabled: true},
 			)
 
 			protocol := server.getProtocol()
@@ -366,6 +376,8 @@ func TestHTTPServer_StartCheck(t *testing.T) {
 				false,
 				"",
 				"",
+				nil,
+				ConnectionConfig{KeepAlivesEnabled: true},
 			)
 
 			ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
@@ -439,6 +451,8 @@ func TestHTTPServer_Listen(t *testing.T) {
 				tt.tlsEnabled,
 				"cert.pem",
 				"key.pem",
+				nil,
+				ConnectionConfig{KeepAlivesEnabled: true},
 			)
 
 			// Test method signature exists
@@ -449,6 +463,212 @@ func TestHTTPServer_Listen(t *testing.T) {
 	}
 }
 
+func TestNewHTTPServer_ACME(t *testing.T) {
+	t.Parallel()
+
+	acmeCfg := &ACMEConfig{
+		Domains:  []string{"vault.example.com"},
+		Email:    "admin@example.com",
+		CacheDir: t.TempDir(),
+		HTTPAddr: ":0",
+	}
+
+	tests := []struct {
+		acmeCfg       *ACMEConfig
+		name          string
+		tlsEnabled    bool
+		wantChallenge bool
+	}{
+		{name: "acme with tls", acmeCfg: acmeCfg, tlsEnabled: true, wantChallenge: true},
+		{name: "acme ignored without tls", acmeCfg: acmeCfg, tlsEnabled: false, wantChallenge: false},
+		{name: "static certificates", acmeCfg: nil, tlsEnabled: true, wantChallenge: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			server := NewHTTPServer(
+				zaptest.NewLogger(t).Sugar(),
+				&mockRouteConfigurator{},
+				&mockMiddlewareConfigurator{},
+				":0",
+				100*time.Millisecond,
+				time.Second,
+				tt.tlsEnabled,
+				"cert.pem",
+				"key.pem",
+				tt.acmeCfg,
+				ConnectionConfig{KeepAlivesEnabled: true},
+			)
+
+			if !tt.wantChallenge {
+				assert.Nil(t, server.challengeServer)
+				return
+			}
+			require.NotNil(t, server.challengeServer)
+			require.NotNil(t, server.server.TLSConfig)
+			assert.NotNil(t, server.server.TLSConfig.GetCertificate)
+
+			// Requests other than ACME challenges are redirected to HTTPS.
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "http://vault.example.com/api/health", nil)
+			server.challengeServer.Handler.ServeHTTP(rec, req)
+			assert.Equal(t, http.StatusFound, rec.Code)
+			assert.Equal(t, "https://vault.example.com/api/health", rec.Header().Get("Location"))
+		})
+	}
+}
+
+func TestHTTPServer_StartStop_ACME(t *testing.T) {
+	t.Parallel()
+
+	server := NewHTTPServer(
+		zaptest.NewLogger(t).Sugar(),
+		&mockRouteConfigurator{},
+		&mockMiddlewareConfigurator{},
+		"127.0.0.1:0",
+		100*time.Millisecond,
+		time.Second,
+		true,
+		"",
+		"",
+		&ACMEConfig{Domains: []string{"vault.example.com"}, CacheDir: t.TempDir(), HTTPAddr: "127.0.0.1:0"},
+		ConnectionConfig{KeepAlivesEnabled: true},
+	)
+
+	require.NoError(t, server.Start(context.Background()))
+	assert.NoError(t, server.Stop(context.Background()))
+}
+
+func TestHTTPServer_UseListeners(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		acmeCfg   *ACMEConfig
+		name      string
+		listeners int
+		wantErr   bool
+	}{
+		{name: "configured address"},
+		{name: "passed socket", listeners: 1},
+		{name: "challenge socket without acme", listeners: 2, wantErr: true},
+		{
+			name:      "challenge socket with acme",
+			acmeCfg:   &ACMEConfig{Domains: []string{"vault.example.com"}, HTTPAddr: "127.0.0.1:0"},
+			listeners: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// listeners holds the sockets passed to the server.
+			var listeners []net.Listener
+			for range tt.listeners {
+				l, err := net.Listen("tcp", "127.0.0.1:0")
+				require.NoError(t, err)
+				t.Cleanup(func() { _ = l.Close() })
+				listeners = append(listeners, l)
+			}
+			if tt.acmeCfg != nil {
+				tt.acmeCfg.CacheDir = t.TempDir()
+			}
+			rc := &mockRouteConfigurator{registerRoutesFunc: func(r *gin.Engine) {
+				r.GET("/ping", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+			}}
+			server := NewHTTPServer(zaptest.NewLogger(t).Sugar(), rc, &mockMiddlewareConfigurator{},
+				"127.0.0.1:0", 50*time.Millisecond, time.Second, tt.acmeCfg != nil, "", "", tt.acmeCfg,
+				ConnectionConfig{KeepAlivesEnabled: true})
+
+			err := server.UseListeners(listeners)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.NoError(t, server.Start(context.Background()))
+			defer func() { assert.NoError(t, server.Stop(context.Background())) }()
+
+			if tt.listeners == 1 {
+				resp, err := http.Get("http://" + listeners[0].Addr().String() + "/ping")
+				require.NoError(t, err)
+				_ = resp.Body.Close()
+				assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+			}
+			if tt.listeners == 2 {
+				client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+					return http.ErrUseLastResponse
+				}}
+				resp, err := client.Get("http://" + listeners[1].Addr().String() + "/ping")
+				require.NoError(t, err)
+				_ = resp.Body.Close()
+				assert.Equal(t, http.StatusFound, resp.StatusCode, "the challenge socket redirects to HTTPS")
+			}
+		})
+	}
+}
+
+func TestNewHTTPServer_ConnectionConfig(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		cfg       ConnectionConfig
+		wantHTTP2 bool
+	}{
+		{
+			name: "tuned connections with http2",
+			cfg: ConnectionConfig{
+				ReadTimeout:       time.Minute,
+				ReadHeaderTimeout: 5 * time.Second,
+				WriteTimeout:      2 * time.Minute,
+				IdleTimeout:       90 * time.Second,
+				MaxHeaderBytes:    64 << 10,
+				HTTP2Enabled:      true,
+				KeepAlivesEnabled: true,
+			},
+			wantHTTP2: true,
+		},
+		{
+			name:      "unlimited timeouts without http2",
+			cfg:       ConnectionConfig{},
+			wantHTTP2: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			server := NewHTTPServer(
+				zaptest.NewLogger(t).Sugar(),
+				&mockRouteConfigurator{},
+				&mockMiddlewareConfigurator{},
+				":0",
+				time.Second,
+				time.Second,
+				false,
+				"",
+				"",
+				nil,
+				tt.cfg,
+			)
+
+			srv := server.server
+			assert.Equal(t, tt.cfg.ReadTimeout, srv.ReadTimeout)
+			assert.Equal(t, tt.cfg.ReadHeaderTimeout, srv.ReadHeaderTimeout)
+			assert.Equal(t, tt.cfg.WriteTimeout, srv.WriteTimeout)
+			assert.Equal(t, tt.cfg.IdleTimeout, srv.IdleTimeout)
+			assert.Equal(t, tt.cfg.MaxHeaderBytes, srv.MaxHeaderBytes)
+			require.NotNil(t, srv.Protocols)
+			assert.True(t, srv.Protocols.HTTP1())
+			assert.Equal(t, tt.wantHTTP2, srv.Protocols.HTTP2())
+		})
+	}
+}
+
 func TestRouteConfigurator(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,5 @@
+// Package invite provides HTTP handlers for invite code endpoints in the AegisVaultKeeper server.
+//
+// This package lets administrators and users with an invite quota create, list and revoke the invite codes
+// new users register with when registration is invitation-only.
+package invite
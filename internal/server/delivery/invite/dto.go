@@ -0,0 +1,106 @@
+package invite
+
+import (
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/invite"
+	"github.com/google/uuid"
+)
+
+// Invite represents an invite code a new user registers with.
+type Invite struct {
+	// CreatedAt contains the timestamp the invite was created at.
+	CreatedAt time.Time `json:"created_at"             example:"2023-12-01T10:00:00Z"`
+	// ExpiresAt contains the timestamp after which the invite can no longer be redeemed.
+	ExpiresAt time.Time `json:"expires_at"             example:"2023-12-08T10:00:00Z"`
+	// RedeemedAt contains the timestamp the invite was redeemed at; omitted if it was not.
+	RedeemedAt time.Time `json:"redeemed_at,omitzero"   example:"2023-12-02T10:00:00Z"`
+	// Note describes whom the invite is meant for.
+	Note string `json:"note,omitempty"         example:"For Alice"`
+	// Code contains the invite code; returned only once, when the invite is created.
+	Code string `json:"code,omitzero"          example:"avki_JBSWY3DPEHPK3PXPJBSWY3DPEH"`
+	// Status contains the state of the invite: pending, redeemed, revoked or expired.
+	Status string `json:"status"                 example:"pending"`
+	// Groups contains the groups the new user is added to.
+	Groups []uuid.UUID `json:"groups,omitempty"`
+	// InviteQuota contains how many invites the new user may create.
+	InviteQuota int `json:"invite_quota,omitempty" example:"2"`
+	// ID contains the identifier of the invite.
+	ID uuid.UUID `json:"id"                     example:"123e4567-e89b-12d3-a456-426614174000"`
+	// CreatedBy contains the user who created the invite; omitted for administrators.
+	CreatedBy uuid.UUID `json:"created_by,omitzero"    example:"123e4567-e89b-12d3-a456-426614174000"`
+	// RedeemedBy contains the user registered with the invite; omitted if it was not redeemed.
+	RedeemedBy uuid.UUID `json:"redeemed_by,omitzero"   example:"123e4567-e89b-12d3-a456-426614174000"`
+}
+
+// NewInviteFromApp converts an application layer invite to delivery DTO.
+func NewInviteFromApp(i *invite.Invite) *Invite {
+	if i == nil {
+		return nil
+	}
+	return &Invite{
+		ID:          i.ID,
+		Note:        i.Note,
+		Code:        i.Code,
+		Status:      i.Status,
+		Groups:      i.Groups,
+		InviteQuota: i.InviteQuota,
+		CreatedBy:   i.CreatedBy,
+		RedeemedBy:  i.RedeemedBy,
+		CreatedAt:   i.CreatedAt,
+		ExpiresAt:   i.ExpiresAt,
+		RedeemedAt:  i.RedeemedAt,
+	}
+}
+
+// NewInvitesFromApp converts application layer invites to delivery DTOs.
+func NewInvitesFromApp(is []*invite.Invite) []*Invite {
+	result := make([]*Invite, 0, len(is))
+	for _, i := range is {
+		result = append(result, NewInviteFromApp(i))
+	}
+	return result
+}
+
+// Quota represents how many invites the user may create.
+type Quota struct {
+	// Limit contains how many pending and redeemed invites the user may hold.
+	Limit int `json:"limit"     example:"3"`
+	// Remaining contains how many more invites the user may create now.
+	Remaining int `json:"remaining" example:"1"`
+}
+
+// NewQuotaFromApp converts an application layer invite quota to delivery DTO.
+func NewQuotaFromApp(q *invite.Quota) *Quota {
+	if q == nil {
+		return nil
+	}
+	return &Quota{Limit: q.Limit, Remaining: max(q.Limit-q.Used, 0)}
+}
+
+// ListResponse represents the response containing invites.
+type ListResponse struct {
+	// Quota contains the invite quota of the user; omitted for administrators.
+	Quota *Quota `json:"quota,omitempty"`
+	// Invites contains the invites, oldest first.
+	Invites []*Invite `json:"invites"`
+}
+
+// CreateRequest represents the request to create an invite.
+type CreateRequest struct {
+	// ExpiresAt contains when the invite expires (optional, at most 90 days ahead; defaults to the configured
+	// lifetime).
+	ExpiresAt time.Time `json:"expires_at,omitzero" example:"2023-12-08T10:00:00Z"`
+	// Note describes whom the invite is meant for (optional, at most 256 characters).
+	Note string `json:"note"                example:"For Alice"`
+	// Groups contains the groups the new user is added to (optional, administrators only, at most 20).
+	Groups []uuid.UUID `json:"groups"`
+	// InviteQuota contains how many invites the new user may create (optional, administrators only).
+	InviteQuota int `json:"invite_quota"        example:"2"`
+}
+
+// InviteIDRequest represents the invite addressed in the request path.
+type InviteIDRequest struct {
+	// ID contains the invite identifier (required UUID format).
+	ID string `uri:"id" binding:"required,uuid" example:"123e4567-e89b-12d3-a456-426614174000"`
+}
@@ -0,0 +1,244 @@
+package invite
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/invite"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/response"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/util"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Service defines the invite application service interface.
+type Service interface {
+	// Create issues a new invite code.
+	Create(context.Context, invite.CreateParams) (*invite.Invite, error)
+	// List retrieves the invites of a user or, for administrators, every invite.
+	List(context.Context, invite.ListParams) ([]*invite.Invite, error)
+	// Revoke prevents an invite from being redeemed.
+	Revoke(context.Context, invite.RevokeParams) error
+	// Quota reports how many invites a user may create.
+	Quota(context.Context, invite.QuotaParams) (*invite.Quota, error)
+}
+
+// Handler handles HTTP requests for invite endpoints.
+type Handler struct {
+	// s is the invite service used to process operations.
+	s Service
+}
+
+// NewHandler creates a new invite handler with the provided service.
+func NewHandler(s Service) *Handler {
+	return &Handler{s: s}
+}
+
+// List retrieves the invites created by the authenticated user.
+// @Summary      List invites
+// @Description  Retrieves the invites created by the user and the remaining invite quota; codes are not returned
+// @Tags         Account
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} ListResponse "Invites retrieved successfully"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /account/invites [get]
+// .
+func (h *Handler) List(c *gin.Context) {
+	userID, err := util.NewCtxExtractor(c).UserID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+
+	invites, err := h.s.List(c, invite.ListParams{CreatedBy: userID})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+	quota, err := h.s.Quota(c, invite.QuotaParams{UserID: userID})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, ListResponse{Invites: NewInvitesFromApp(invites), Quota: NewQuotaFromApp(quota)})
+}
+
+// Create issues an invite code on behalf of the authenticated user.
+// @Summary      Create invite
+// @Description  Issues an invite code a new user registers with, counted against the invite quota of the user.
+// @Description  The code is returned only in this response. Groups and invite quotas are reserved to
+// @Description  administrators.
+// .
+// @Tags         Account
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body CreateRequest true "Expiry and note of the invite"
+// @Success      201 {object} Invite "Invite created successfully"
+// @Failure      400 {object} response.Error "Bad request - invalid expiry or note"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
+// @Failure      403 {object} response.Error "Forbidden - invite quota exceeded or presets requested"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /account/invites [post]
+// .
+func (h *Handler) Create(c *gin.Context) {
+	userID, err := util.NewCtxExtractor(c).UserID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+	h.create(c, userID)
+}
+
+// Revoke revokes an invite created by the authenticated user.
+// @Summary      Revoke invite
+// @Description  Prevents the invite from being redeemed and frees its slot in the invite quota
+// @Tags         Account
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Invite ID" format(uuid)
+// @Success      204 "Invite revoked successfully"
+// @Failure      400 {object} response.Error "Bad request - invalid ID format"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
+// @Failure      404 {object} response.Error "Not found - invite not found"
+// @Failure      409 {object} response.Error "Conflict - invite already used"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /account/invites/{id} [delete]
+// .
+func (h *Handler) Revoke(c *gin.Context) {
+	userID, err := util.NewCtxExtractor(c).UserID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+	h.revoke(c, userID)
+}
+
+// AdminList retrieves every invite.
+// @Summary      List all invites
+// @Description  Retrieves the invites of administrators and users; codes are not returned
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Security     AdminToken
+// @Success      200 {object} ListResponse "Invites retrieved successfully"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing admin token"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /admin/invites [get]
+// .
+func (h *Handler) AdminList(c *gin.Context) {
+	invites, err := h.s.List(c, invite.ListParams{})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, ListResponse{Invites: NewInvitesFromApp(invites)})
+}
+
+// AdminCreate issues an invite code as an administrator.
+// @Summary      Create invite as administrator
+// @Description  Issues an invite code a new user registers with. The invite may add the new user to groups and
+// @Description  grant an invite quota. The code is returned only in this response.
+// .
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Security     AdminToken
+// @Param        request body CreateRequest true "Expiry, note and presets of the invite"
+// @Success      201 {object} Invite "Invite created successfully"
+// @Failure      400 {object} response.Error "Bad request - invalid expiry, note or presets"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing admin token"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /admin/invites [post]
+// .
+func (h *Handler) AdminCreate(c *gin.Context) {
+	h.create(c, uuid.Nil)
+}
+
+// AdminRevoke revokes any invite as an administrator.
+// @Summary      Revoke invite as administrator
+// @Description  Prevents the invite of any user from being redeemed
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Security     AdminToken
+// @Param        id path string true "Invite ID" format(uuid)
+// @Success      204 "Invite revoked successfully"
+// @Failure      400 {object} response.Error "Bad request - invalid ID format"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing admin token"
+// @Failure      404 {object} response.Error "Not found - invite not found"
+// @Failure      409 {object} response.Error "Conflict - invite already used"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /admin/invites/{id} [delete]
+// .
+func (h *Handler) AdminRevoke(c *gin.Context) {
+	h.revoke(c, uuid.Nil)
+}
+
+// create issues an invite on behalf of the user, or of an administrator for uuid.Nil.
+func (h *Handler) create(c *gin.Context, createdBy uuid.UUID) {
+	// req holds the deserialized JSON request payload for the invite.
+	var req CreateRequest
+	if err := util.NewCtxExtractor(c).BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	i, err := h.s.Create(c, invite.CreateParams{
+		ExpiresAt:   req.ExpiresAt,
+		Note:        req.Note,
+		Groups:      req.Groups,
+		InviteQuota: req.InviteQuota,
+		CreatedBy:   createdBy,
+	})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, NewInviteFromApp(i))
+}
+
+// revoke revokes an invite of the user, or any invite for uuid.Nil.
+func (h *Handler) revoke(c *gin.Context, createdBy uuid.UUID) {
+	// req holds the deserialized URI parameters of the request.
+	var req InviteIDRequest
+	if err := util.NewCtxExtractor(c).BindURI(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	inviteID, err := uuid.Parse(req.ID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	if err := h.s.Revoke(c, invite.RevokeParams{ID: inviteID, CreatedBy: createdBy}); err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.Data(http.StatusNoContent, "", nil)
+}
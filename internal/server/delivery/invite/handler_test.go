@@ -0,0 +1,304 @@
+package invite
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/invite"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/consts"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockInviteService implements Service for testing.
+type mockInviteService struct {
+	createFunc func(ctx context.Context, params invite.CreateParams) (*invite.Invite, error)
+	listFunc   func(ctx context.Context, params invite.ListParams) ([]*invite.Invite, error)
+	revokeFunc func(ctx context.Context, params invite.RevokeParams) error
+	quotaFunc  func(ctx context.Context, params invite.QuotaParams) (*invite.Quota, error)
+}
+
+func (m *mockInviteService) Create(ctx context.Context, params invite.CreateParams) (*invite.Invite, error) {
+	if m.createFunc != nil {
+		return m.createFunc(ctx, params)
+	}
+	return nil, nil
+}
+
+func (m *mockInviteService) List(ctx context.Context, params invite.ListParams) ([]*invite.Invite, error) {
+	if m.listFunc != nil {
+		return m.listFunc(ctx, params)
+	}
+	return nil, nil
+}
+
+func (m *mockInviteService) Revoke(ctx context.Context, params invite.RevokeParams) error {
+	if m.revokeFunc != nil {
+		return m.revokeFunc(ctx, params)
+	}
+	return nil
+}
+
+func (m *mockInviteService) Quota(ctx context.Context, params invite.QuotaParams) (*invite.Quota, error) {
+	if m.quotaFunc != nil {
+		return m.quotaFunc(ctx, params)
+	}
+	return &invite.Quota{}, nil
+}
+
+func TestHandler_List(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+
+	tests := []struct {
+		mockService    *mockInviteService
+		wantQuota      *Quota
+		name           string
+		admin          bool
+		setUser        bool
+		expectedStatus int
+	}{
+		{
+			name:    "user",
+			setUser: true,
+			mockService: &mockInviteService{
+				listFunc: func(_ context.Context, params invite.ListParams) ([]*invite.Invite, error) {
+					assert.Equal(t, invite.ListParams{CreatedBy: userID}, params)
+					return []*invite.Invite{{ID: uuid.New(), Status: "pending"}}, nil
+				},
+				quotaFunc: func(context.Context, invite.QuotaParams) (*invite.Quota, error) {
+					return &invite.Quota{Limit: 3, Used: 1}, nil
+				},
+			},
+			wantQuota:      &Quota{Limit: 3, Remaining: 2},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:  "administrator",
+			admin: true,
+			mockService: &mockInviteService{
+				listFunc: func(_ context.Context, params invite.ListParams) ([]*invite.Invite, error) {
+					assert.Equal(t, invite.ListParams{}, params)
+					return []*invite.Invite{{ID: uuid.New(), Status: "redeemed"}}, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "missing user context",
+			mockService:    &mockInviteService{},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/invites", nil)
+			if tt.setUser {
+				c.Set(consts.CtxKeyUserID, userID)
+			}
+
+			if tt.admin {
+				NewHandler(tt.mockService).AdminList(c)
+			} else {
+				NewHandler(tt.mockService).List(c)
+			}
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var got ListResponse
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+				assert.Len(t, got.Invites, 1)
+				assert.Equal(t, tt.wantQuota, got.Quota)
+			}
+		})
+	}
+}
+
+func TestHandler_Create(t *testing.T) {
+	t.Parallel()
+
+	userID, groupID := uuid.New(), uuid.New()
+
+	tests := []struct {
+		mockService    *mockInviteService
+		name           string
+		body           string
+		admin          bool
+		expectedStatus int
+	}{
+		{
+			name: "user",
+			body: `{"note":"For Alice"}`,
+			mockService: &mockInviteService{
+				createFunc: func(_ context.Context, params invite.CreateParams) (*invite.Invite, error) {
+					assert.Equal(t, invite.CreateParams{Note: "For Alice", CreatedBy: userID}, params)
+					return &invite.Invite{ID: uuid.New(), Code: "avki_CODE", Status: "pending"}, nil
+				},
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:  "administrator with presets",
+			body:  `{"groups":["` + groupID.String() + `"],"invite_quota":2}`,
+			admin: true,
+			mockService: &mockInviteService{
+				createFunc: func(_ context.Context, params invite.CreateParams) (*invite.Invite, error) {
+					assert.Equal(t, invite.CreateParams{Groups: []uuid.UUID{groupID}, InviteQuota: 2}, params)
+					return &invite.Invite{ID: uuid.New(), Code: "avki_CODE", Status: "pending"}, nil
+				},
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "malformed group id",
+			body:           `{"groups":["family"]}`,
+			admin:          true,
+			mockService:    &mockInviteService{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "quota exceeded",
+			body: `{}`,
+			mockService: &mockInviteService{
+				createFunc: func(context.Context, invite.CreateParams) (*invite.Invite, error) {
+					return nil, invite.ErrInviteQuotaExceeded
+				},
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name: "invalid expiry",
+			body: `{"expires_at":"2020-01-01T00:00:00Z"}`,
+			mockService: &mockInviteService{
+				createFunc: func(context.Context, invite.CreateParams) (*invite.Invite, error) {
+					return nil, invite.ErrInviteIncorrectExpiry
+				},
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/invites", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			if tt.admin {
+				NewHandler(tt.mockService).AdminCreate(c)
+			} else {
+				c.Set(consts.CtxKeyUserID, userID)
+				NewHandler(tt.mockService).Create(c)
+			}
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusCreated {
+				var got Invite
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+				assert.Equal(t, "avki_CODE", got.Code)
+			}
+		})
+	}
+}
+
+func TestHandler_Revoke(t *testing.T) {
+	t.Parallel()
+
+	userID, inviteID := uuid.New(), uuid.New()
+
+	tests := []struct {
+		mockService    *mockInviteService
+		name           string
+		id             string
+		admin          bool
+		expectedStatus int
+	}{
+		{
+			name: "user",
+			id:   inviteID.String(),
+			mockService: &mockInviteService{
+				revokeFunc: func(_ context.Context, params invite.RevokeParams) error {
+					assert.Equal(t, invite.RevokeParams{ID: inviteID, CreatedBy: userID}, params)
+					return nil
+				},
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:  "administrator",
+			id:    inviteID.String(),
+			admin: true,
+			mockService: &mockInviteService{
+				revokeFunc: func(_ context.Context, params invite.RevokeParams) error {
+					assert.Equal(t, invite.RevokeParams{ID: inviteID}, params)
+					return nil
+				},
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "invalid ID",
+			id:             "alice",
+			mockService:    &mockInviteService{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "not found",
+			id:   inviteID.String(),
+			mockService: &mockInviteService{
+				revokeFunc: func(context.Context, invite.RevokeParams) error {
+					return invite.ErrInviteNotFound
+				},
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name: "already redeemed",
+			id:   inviteID.String(),
+			mockService: &mockInviteService{
+				revokeFunc: func(context.Context, invite.RevokeParams) error {
+					return invite.ErrInviteRedeemed
+				},
+			},
+			expectedStatus: http.StatusConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodDelete, "/invites/"+tt.id, nil)
+			c.Params = gin.Params{{Key: "id", Value: tt.id}}
+
+			if tt.admin {
+				NewHandler(tt.mockService).AdminRevoke(c)
+			} else {
+				c.Set(consts.CtxKeyUserID, userID)
+				NewHandler(tt.mockService).Revoke(c)
+			}
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
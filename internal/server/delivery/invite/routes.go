@@ -0,0 +1,21 @@
+package invite
+
+import "github.com/gin-gonic/gin"
+
+// RegisterRoutes registers invite routes of users with the provided router group.
+// Creates /invites and /invites/:id endpoints with the specified handler.
+func RegisterRoutes(r *gin.RouterGroup, h *Handler) {
+	invitesGroup := r.Group("/invites")
+	invitesGroup.GET("", h.List)
+	invitesGroup.POST("", h.Create)
+	invitesGroup.DELETE("/:id", h.Revoke)
+}
+
+// RegisterAdminRoutes registers invite routes of administrators with the provided router group.
+// Creates /invites and /invites/:id endpoints with the specified handler.
+func RegisterAdminRoutes(r *gin.RouterGroup, h *Handler) {
+	invitesGroup := r.Group("/invites")
+	invitesGroup.GET("", h.AdminList)
+	invitesGroup.POST("", h.AdminCreate)
+	invitesGroup.DELETE("/:id", h.AdminRevoke)
+}
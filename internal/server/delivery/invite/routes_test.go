@@ -0,0 +1,43 @@
+package invite
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterRoutes(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	RegisterRoutes(router.Group("/account"), NewHandler(&mockInviteService{}))
+
+	got := make(map[string]string)
+	for _, r := range router.Routes() {
+		got[r.Method+" "+r.Path] = r.Handler
+	}
+	assert.Len(t, got, 3)
+	assert.Contains(t, got, http.MethodGet+" /account/invites")
+	assert.Contains(t, got, http.MethodPost+" /account/invites")
+	assert.Contains(t, got, http.MethodDelete+" /account/invites/:id")
+}
+
+func TestRegisterAdminRoutes(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	RegisterAdminRoutes(router.Group("/admin"), NewHandler(&mockInviteService{}))
+
+	got := make(map[string]string)
+	for _, r := range router.Routes() {
+		got[r.Method+" "+r.Path] = r.Handler
+	}
+	assert.Len(t, got, 3)
+	assert.Contains(t, got, http.MethodGet+" /admin/invites")
+	assert.Contains(t, got, http.MethodPost+" /admin/invites")
+	assert.Contains(t, got, http.MethodDelete+" /admin/invites/:id")
+}
@@ -0,0 +1,5 @@
+// Package itemaccess provides HTTP handlers for item access history endpoints in the AegisVaultKeeper server.
+//
+// This package lets authenticated users review how many times and when each of their vault items was
+// revealed, per device, so they can spot unexpected access to their secrets.
+package itemaccess
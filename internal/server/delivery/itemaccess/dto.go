@@ -0,0 +1,77 @@
+package itemaccess
+
+import (
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/itemaccess"
+	"github.com/google/uuid"
+)
+
+// Reveal represents a single reveal of a vault item.
+type Reveal struct {
+	// RevealedAt contains the moment the item was revealed.
+	RevealedAt time.Time `json:"revealed_at" example:"2023-12-01T10:00:00Z"`
+	// IP contains the client address the item was revealed from, if known.
+	IP string `json:"ip,omitzero" example:"203.0.113.7"`
+	// UserAgent contains the reported client software.
+	UserAgent string `json:"user_agent"  example:"aegis-cli/1.0"`
+}
+
+// DeviceAccess represents the reveals of a vault item from one device.
+type DeviceAccess struct {
+	// LastRevealedAt contains the moment of the latest reveal from the device.
+	LastRevealedAt time.Time `json:"last_revealed_at" example:"2023-12-01T10:00:00Z"`
+	// UserAgent contains the client software reported by the latest reveal from the device.
+	UserAgent string `json:"user_agent"       example:"aegis-cli/1.0"`
+	// RevealCount contains the number of reveals from the device.
+	RevealCount int `json:"reveal_count"     example:"3"`
+}
+
+// HistoryResponse represents the access history of a vault item.
+type HistoryResponse struct {
+	// LastRevealedAt contains the moment of the latest reveal; omitted when the item was never revealed.
+	LastRevealedAt time.Time `json:"last_revealed_at,omitzero" example:"2023-12-01T10:00:00Z"`
+	// Devices summarizes the reveals per device, most recently active first.
+	Devices []*DeviceAccess `json:"devices"`
+	// Reveals lists the latest reveals, newest first.
+	Reveals []*Reveal `json:"reveals"`
+	// RevealCount contains the number of reveals of the item.
+	RevealCount int `json:"reveal_count"              example:"5"`
+	// ItemID contains the identifier of the item.
+	ItemID uuid.UUID `json:"item_id"                   example:"123e4567-e89b-12d3-a456-426614174000"`
+}
+
+// NewHistoryResponseFromApp converts an application layer access history to delivery DTO.
+func NewHistoryResponseFromApp(h *itemaccess.History) *HistoryResponse {
+	if h == nil {
+		return nil
+	}
+	resp := &HistoryResponse{
+		ItemID:         h.ItemID,
+		RevealCount:    h.Count,
+		LastRevealedAt: h.LastRevealedAt,
+		Devices:        make([]*DeviceAccess, 0, len(h.Devices)),
+		Reveals:        make([]*Reveal, 0, len(h.Reveals)),
+	}
+	for _, d := range h.Devices {
+		resp.Devices = append(resp.Devices, &DeviceAccess{
+			LastRevealedAt: d.LastRevealedAt,
+			UserAgent:      d.UserAgent,
+			RevealCount:    d.Count,
+		})
+	}
+	for _, r := range h.Reveals {
+		reveal := &Reveal{RevealedAt: r.RevealedAt, UserAgent: r.UserAgent}
+		if r.IP.IsValid() {
+			reveal.IP = r.IP.String()
+		}
+		resp.Reveals = append(resp.Reveals, reveal)
+	}
+	return resp
+}
+
+// ItemIDRequest represents the item addressed in the request path.
+type ItemIDRequest struct {
+	// ID contains the item identifier (required UUID format).
+	ID string `uri:"id" binding:"required,uuid" example:"123e4567-e89b-12d3-a456-426614174000"`
+}
@@ -0,0 +1,79 @@
+package itemaccess
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/itemaccess"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/response"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/util"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Service defines the item access history application service interface.
+type Service interface {
+	// History retrieves the access history of an item.
+	History(context.Context, itemaccess.HistoryParams) (*itemaccess.History, error)
+}
+
+// Handler handles HTTP requests for item access history endpoints.
+type Handler struct {
+	// s is the item access history service used to process operations.
+	s Service
+}
+
+// NewHandler creates a new item access history handler with the provided service.
+func NewHandler(s Service) *Handler {
+	return &Handler{s: s}
+}
+
+// History retrieves the access history of an item of the authenticated user.
+// @Summary      Get item access history
+// @Description  Reports how many times and when an item of any kind was revealed, per device, and lists its
+// @Description  latest reveals, newest first. Item detail responses carry the count and the latest moment in the
+// @Description  X-Item-Reveal-Count and X-Item-Last-Revealed-At headers. Never revealed items have an empty history.
+// .
+// @Tags         ItemAccess
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Item ID" format(uuid)
+// @Success      200 {object} HistoryResponse "Item access history retrieved successfully"
+// @Failure      400 {object} response.Error "Bad request - invalid ID format"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /items/{id}/access-history [get]
+// .
+func (h *Handler) History(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	userID, err := extractor.UserID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+
+	// req holds the deserialized URI parameters of the request.
+	var req ItemIDRequest
+	if err := extractor.BindURI(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+	itemID, err := uuid.Parse(req.ID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	history, err := h.s.History(c, itemaccess.HistoryParams{ItemID: itemID, UserID: userID})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, NewHistoryResponseFromApp(history))
+}
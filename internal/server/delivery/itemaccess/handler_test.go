@@ -0,0 +1,133 @@
+package itemaccess
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/itemaccess"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/consts"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockHistoryService implements Service for testing.
+type mockHistoryService struct {
+	historyFunc func(ctx context.Context, params itemaccess.HistoryParams) (*itemaccess.History, error)
+}
+
+func (m *mockHistoryService) History(
+	ctx context.Context,
+	params itemaccess.HistoryParams,
+) (*itemaccess.History, error) {
+	if m.historyFunc != nil {
+		return m.historyFunc(ctx, params)
+	}
+	return nil, nil
+}
+
+func TestHandler_History(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	itemID := uuid.New()
+	revealedAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		mockService    *mockHistoryService
+		name           string
+		id             string
+		wantBody       string
+		setUser        bool
+		expectedStatus int
+	}{
+		{
+			name:    "revealed item",
+			id:      itemID.String(),
+			setUser: true,
+			mockService: &mockHistoryService{
+				historyFunc: func(_ context.Context, params itemaccess.HistoryParams) (*itemaccess.History, error) {
+					assert.Equal(t, itemaccess.HistoryParams{ItemID: itemID, UserID: userID}, params)
+					return &itemaccess.History{
+						ItemID:         itemID,
+						Count:          1,
+						LastRevealedAt: revealedAt,
+						Devices: []*itemaccess.DeviceAccess{
+							{UserAgent: "aegis-cli/1.0", LastRevealedAt: revealedAt, Count: 1},
+						},
+						Reveals: []*itemaccess.Reveal{
+							{UserAgent: "aegis-cli/1.0", IP: netip.MustParseAddr("203.0.113.7"), RevealedAt: revealedAt},
+						},
+					}, nil
+				},
+			},
+			wantBody: `{"item_id":"` + itemID.String() + `","reveal_count":1,` +
+				`"last_revealed_at":"2026-10-01T12:00:00Z",` +
+				`"devices":[{"user_agent":"aegis-cli/1.0","reveal_count":1,"last_revealed_at":"2026-10-01T12:00:00Z"}],` +
+				`"reveals":[{"user_agent":"aegis-cli/1.0","ip":"203.0.113.7","revealed_at":"2026-10-01T12:00:00Z"}]}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:    "never revealed item",
+			id:      itemID.String(),
+			setUser: true,
+			mockService: &mockHistoryService{
+				historyFunc: func(context.Context, itemaccess.HistoryParams) (*itemaccess.History, error) {
+					return &itemaccess.History{ItemID: itemID}, nil
+				},
+			},
+			wantBody:       `{"item_id":"` + itemID.String() + `","reveal_count":0,"devices":[],"reveals":[]}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid ID",
+			id:             "passport",
+			setUser:        true,
+			mockService:    &mockHistoryService{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "missing user context",
+			id:             itemID.String(),
+			mockService:    &mockHistoryService{},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:    "service error",
+			id:      itemID.String(),
+			setUser: true,
+			mockService: &mockHistoryService{
+				historyFunc: func(context.Context, itemaccess.HistoryParams) (*itemaccess.History, error) {
+					return nil, itemaccess.ErrItemAccessTechError
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/items/"+tt.id+"/access-history", nil)
+			c.Params = gin.Params{{Key: "id", Value: tt.id}}
+			if tt.setUser {
+				c.Set(consts.CtxKeyUserID, userID)
+			}
+
+			NewHandler(tt.mockService).History(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.wantBody != "" {
+				assert.JSONEq(t, tt.wantBody, w.Body.String())
+			}
+		})
+	}
+}
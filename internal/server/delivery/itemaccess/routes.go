@@ -0,0 +1,9 @@
+package itemaccess
+
+import "github.com/gin-gonic/gin"
+
+// RegisterRoutes registers item access history routes with the provided router group.
+// Creates /:id/access-history with the specified handler.
+func RegisterRoutes(r *gin.RouterGroup, h *Handler) {
+	r.GET("/:id/access-history", h.History)
+}
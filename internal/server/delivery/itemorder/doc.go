@@ -0,0 +1,5 @@
+// Package itemorder provides HTTP handlers for manual item order endpoints in the AegisVaultKeeper server.
+//
+// This package lets authenticated users pin their vault items and arrange them in a custom order per
+// folder, which item listings follow when requested with sort=manual.
+package itemorder
@@ -0,0 +1,67 @@
+package itemorder
+
+import (
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/itemorder"
+	"github.com/google/uuid"
+)
+
+// Entry represents an item placed in a manual order.
+type Entry struct {
+	// ItemID contains the identifier of the placed item.
+	ItemID uuid.UUID `json:"item_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	// Pinned indicates whether the item is pinned ahead of the unpinned items.
+	Pinned bool `json:"pinned"  example:"true"`
+}
+
+// ItemOrder represents the manual order of the items of a folder.
+type ItemOrder struct {
+	// UpdatedAt contains the timestamp of the last order change; omitted for folders without an order.
+	UpdatedAt time.Time `json:"updated_at,omitzero" example:"2023-12-01T10:00:00Z"`
+	// Folder contains the folder the order applies to; empty for the root.
+	Folder string `json:"folder"              example:"prod/db"`
+	// Items contains the placed items in order, the pinned ones first.
+	Items []Entry `json:"items"`
+}
+
+// NewItemOrderFromApp converts an application layer item order to delivery DTO.
+func NewItemOrderFromApp(o *itemorder.ItemOrder) *ItemOrder {
+	if o == nil {
+		return nil
+	}
+	items := make([]Entry, 0, len(o.Entries))
+	for _, e := range o.Entries {
+		items = append(items, Entry{ItemID: e.ItemID, Pinned: e.Pinned})
+	}
+	return &ItemOrder{
+		Folder:    o.Folder,
+		Items:     items,
+		UpdatedAt: o.UpdatedAt,
+	}
+}
+
+// FolderRequest represents the folder addressed in the request query.
+type FolderRequest struct {
+	// Folder contains the folder; omit to address the root.
+	Folder string `form:"folder" example:"prod/db"`
+}
+
+// Position represents the requested place of an item.
+type Position struct {
+	// ItemID contains the identifier of the moved item (required).
+	ItemID uuid.UUID `json:"item_id"  example:"123e4567-e89b-12d3-a456-426614174000"`
+	// Position contains the zero-based index of the item among the pinned or among the unpinned items;
+	// indexes past the end place the item last.
+	Position int `json:"position" example:"0"`
+	// Pinned indicates whether the item is pinned.
+	Pinned bool `json:"pinned"   example:"true"`
+}
+
+// ReorderRequest represents the request to move items within the order of a folder.
+type ReorderRequest struct {
+	// Folder contains the folder; empty addresses the root.
+	Folder string `json:"folder"    example:"prod/db"`
+	// Positions contains the requested places of the items, applied one after another.
+	Positions []Position `json:"positions" binding:"required"`
+}
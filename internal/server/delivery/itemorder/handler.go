@@ -0,0 +1,166 @@
+package itemorder
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/itemorder"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/response"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/util"
+	"github.com/gin-gonic/gin"
+)
+
+// Service defines the item order application service interface.
+type Service interface {
+	// GetOrder retrieves the order of a folder.
+	GetOrder(context.Context, itemorder.GetParams) (*itemorder.ItemOrder, error)
+	// Reorder moves items within the order of a folder.
+	Reorder(context.Context, itemorder.ReorderParams) (*itemorder.ItemOrder, error)
+	// ResetOrder removes the order of a folder.
+	ResetOrder(context.Context, itemorder.ResetParams) error
+}
+
+// Handler handles HTTP requests for item order endpoints.
+type Handler struct {
+	// s is the item order service used to process operations.
+	s Service
+}
+
+// NewHandler creates a new item order handler with the provided service.
+func NewHandler(s Service) *Handler {
+	return &Handler{s: s}
+}
+
+// Get retrieves the manual order of a folder of the authenticated user.
+// @Summary      Get item order
+// @Description  Retrieves the items of a folder in their manual order, the pinned ones first; folders without
+// @Description  an order have no items
+// .
+// @Tags         ItemOrder
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        folder query string false "Folder, the root when omitted" example(prod/db)
+// @Success      200 {object} ItemOrder "Item order retrieved successfully"
+// @Failure      400 {object} response.Error "Bad request - invalid folder"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /items/order [get]
+// .
+func (h *Handler) Get(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	userID, err := extractor.UserID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+
+	// req holds the deserialized query parameters of the request.
+	var req FolderRequest
+	if err := extractor.BindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	order, err := h.s.GetOrder(c, itemorder.GetParams{Folder: req.Folder, UserID: userID})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, NewItemOrderFromApp(order))
+}
+
+// Reorder moves items within the manual order of a folder of the authenticated user.
+// @Summary      Reorder items
+// @Description  Places the items at the positions one after another. Positions count among the pinned items
+// @Description  for pinned ones and among the unpinned items otherwise; items not yet in the order are added
+// @Description  and indexes past the end place the item last. A folder order holds at most 1000 items.
+// .
+// @Tags         ItemOrder
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body ReorderRequest true "Position updates"
+// @Success      200 {object} ItemOrder "Items reordered successfully"
+// @Failure      400 {object} response.Error "Bad request - invalid folder or positions"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /items/order [put]
+// .
+func (h *Handler) Reorder(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	userID, err := extractor.UserID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+
+	// req holds the deserialized JSON request payload for the position updates.
+	var req ReorderRequest
+	if err := extractor.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	positions := make([]itemorder.Position, 0, len(req.Positions))
+	for _, p := range req.Positions {
+		positions = append(positions, itemorder.Position{ItemID: p.ItemID, Position: p.Position, Pinned: p.Pinned})
+	}
+	order, err := h.s.Reorder(c, itemorder.ReorderParams{Folder: req.Folder, Positions: positions, UserID: userID})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, NewItemOrderFromApp(order))
+}
+
+// Reset removes the manual order of a folder of the authenticated user.
+// @Summary      Reset item order
+// @Description  Removes the manual order of a folder, so its items are listed in their default order again
+// @Tags         ItemOrder
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        folder query string false "Folder, the root when omitted" example(prod/db)
+// @Success      204 "Item order removed successfully"
+// @Failure      400 {object} response.Error "Bad request - invalid folder"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /items/order [delete]
+// .
+func (h *Handler) Reset(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	userID, err := extractor.UserID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+
+	// req holds the deserialized query parameters of the request.
+	var req FolderRequest
+	if err := extractor.BindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	if err := h.s.ResetOrder(c, itemorder.ResetParams{Folder: req.Folder, UserID: userID}); err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.Data(http.StatusNoContent, "", nil)
+}
@@ -0,0 +1,287 @@
+package itemorder
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/itemorder"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/consts"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockOrderService implements Service for testing.
+type mockOrderService struct {
+	getFunc     func(ctx context.Context, params itemorder.GetParams) (*itemorder.ItemOrder, error)
+	reorderFunc func(ctx context.Context, params itemorder.ReorderParams) (*itemorder.ItemOrder, error)
+	resetFunc   func(ctx context.Context, params itemorder.ResetParams) error
+}
+
+func (m *mockOrderService) GetOrder(ctx context.Context, params itemorder.GetParams) (*itemorder.ItemOrder, error) {
+	if m.getFunc != nil {
+		return m.getFunc(ctx, params)
+	}
+	return nil, nil
+}
+
+func (m *mockOrderService) Reorder(
+	ctx context.Context,
+	params itemorder.ReorderParams,
+) (*itemorder.ItemOrder, error) {
+	if m.reorderFunc != nil {
+		return m.reorderFunc(ctx, params)
+	}
+	return nil, nil
+}
+
+func (m *mockOrderService) ResetOrder(ctx context.Context, params itemorder.ResetParams) error {
+	if m.resetFunc != nil {
+		return m.resetFunc(ctx, params)
+	}
+	return nil
+}
+
+func TestHandler_Get(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	itemID := uuid.New()
+
+	tests := []struct {
+		mockService    *mockOrderService
+		name           string
+		query          string
+		setUser        bool
+		wantCount      int
+		expectedStatus int
+	}{
+		{
+			name:    "success with folder",
+			query:   "?folder=prod/db",
+			setUser: true,
+			mockService: &mockOrderService{
+				getFunc: func(_ context.Context, params itemorder.GetParams) (*itemorder.ItemOrder, error) {
+					assert.Equal(t, itemorder.GetParams{Folder: "prod/db", UserID: userID}, params)
+					return &itemorder.ItemOrder{
+						Folder:  "prod/db",
+						Entries: []itemorder.Entry{{ItemID: itemID, Pinned: true}},
+					}, nil
+				},
+			},
+			wantCount:      1,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "missing user context",
+			mockService:    &mockOrderService{},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:    "invalid folder",
+			query:   "?folder=prod/..",
+			setUser: true,
+			mockService: &mockOrderService{
+				getFunc: func(context.Context, itemorder.GetParams) (*itemorder.ItemOrder, error) {
+					return nil, itemorder.ErrIncorrectFolder
+				},
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:    "service error",
+			setUser: true,
+			mockService: &mockOrderService{
+				getFunc: func(context.Context, itemorder.GetParams) (*itemorder.ItemOrder, error) {
+					return nil, itemorder.ErrItemOrderTechError
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/items/order"+tt.query, nil)
+			if tt.setUser {
+				c.Set(consts.CtxKeyUserID, userID)
+			}
+
+			NewHandler(tt.mockService).Get(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.wantCount == 0 {
+				return
+			}
+			var got ItemOrder
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+			require.Len(t, got.Items, tt.wantCount)
+			assert.Equal(t, "prod/db", got.Folder)
+			assert.Equal(t, Entry{ItemID: itemID, Pinned: true}, got.Items[0])
+		})
+	}
+}
+
+func TestHandler_Reorder(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	itemID := uuid.New()
+
+	tests := []struct {
+		mockService    *mockOrderService
+		name           string
+		body           string
+		setUser        bool
+		expectedStatus int
+	}{
+		{
+			name:    "success",
+			body:    `{"folder":"prod","positions":[{"item_id":"` + itemID.String() + `","position":2,"pinned":true}]}`,
+			setUser: true,
+			mockService: &mockOrderService{
+				reorderFunc: func(_ context.Context, params itemorder.ReorderParams) (*itemorder.ItemOrder, error) {
+					assert.Equal(t, itemorder.ReorderParams{
+						Folder:    "prod",
+						Positions: []itemorder.Position{{ItemID: itemID, Position: 2, Pinned: true}},
+						UserID:    userID,
+					}, params)
+					return &itemorder.ItemOrder{Entries: []itemorder.Entry{{ItemID: itemID, Pinned: true}}}, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "missing user context",
+			body:           `{"positions":[]}`,
+			mockService:    &mockOrderService{},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:           "missing positions",
+			body:           `{"folder":"prod"}`,
+			setUser:        true,
+			mockService:    &mockOrderService{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "malformed item",
+			body:           `{"positions":[{"item_id":"not-a-uuid"}]}`,
+			setUser:        true,
+			mockService:    &mockOrderService{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:    "incorrect position",
+			body:    `{"positions":[{"item_id":"` + itemID.String() + `","position":-1}]}`,
+			setUser: true,
+			mockService: &mockOrderService{
+				reorderFunc: func(context.Context, itemorder.ReorderParams) (*itemorder.ItemOrder, error) {
+					return nil, itemorder.ErrIncorrectPosition
+				},
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:    "too many items",
+			body:    `{"positions":[{"item_id":"` + itemID.String() + `"}]}`,
+			setUser: true,
+			mockService: &mockOrderService{
+				reorderFunc: func(context.Context, itemorder.ReorderParams) (*itemorder.ItemOrder, error) {
+					return nil, itemorder.ErrTooManyItems
+				},
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPut, "/items/order", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			if tt.setUser {
+				c.Set(consts.CtxKeyUserID, userID)
+			}
+
+			NewHandler(tt.mockService).Reorder(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestHandler_Reset(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+
+	tests := []struct {
+		mockService    *mockOrderService
+		name           string
+		query          string
+		setUser        bool
+		expectedStatus int
+	}{
+		{
+			name:    "success",
+			query:   "?folder=prod",
+			setUser: true,
+			mockService: &mockOrderService{
+				resetFunc: func(_ context.Context, params itemorder.ResetParams) error {
+					assert.Equal(t, itemorder.ResetParams{Folder: "prod", UserID: userID}, params)
+					return nil
+				},
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "missing user context",
+			mockService:    &mockOrderService{},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:    "invalid folder",
+			query:   "?folder=..",
+			setUser: true,
+			mockService: &mockOrderService{
+				resetFunc: func(context.Context, itemorder.ResetParams) error {
+					return itemorder.ErrIncorrectFolder
+				},
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodDelete, "/items/order"+tt.query, nil)
+			if tt.setUser {
+				c.Set(consts.CtxKeyUserID, userID)
+			}
+
+			NewHandler(tt.mockService).Reset(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
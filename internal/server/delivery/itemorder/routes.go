@@ -0,0 +1,12 @@
+package itemorder
+
+import "github.com/gin-gonic/gin"
+
+// RegisterRoutes registers item order routes with the provided router group.
+// Creates /order with the specified handler.
+func RegisterRoutes(r *gin.RouterGroup, h *Handler) {
+	orderGroup := r.Group("/order")
+	orderGroup.GET("", h.Get)
+	orderGroup.PUT("", h.Reorder)
+	orderGroup.DELETE("", h.Reset)
+}
@@ -0,0 +1,26 @@
+package itemorder
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterRoutes(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	RegisterRoutes(router.Group("/items"), NewHandler(&mockOrderService{}))
+
+	got := make(map[string]string)
+	for _, r := range router.Routes() {
+		got[r.Method+" "+r.Path] = r.Handler
+	}
+	assert.Len(t, got, 3)
+	assert.Contains(t, got, http.MethodGet+" /items/order")
+	assert.Contains(t, got, http.MethodPut+" /items/order")
+	assert.Contains(t, got, http.MethodDelete+" /items/order")
+}
@@ -0,0 +1,5 @@
+// Package itempath provides HTTP handlers for item path endpoints in the AegisVaultKeeper server.
+//
+// This package lets authenticated users assign hierarchical paths, such as prod/db/payments, to their
+// vault items so machine identities can address secrets by name.
+package itempath
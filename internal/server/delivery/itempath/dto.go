@@ -0,0 +1,63 @@
+package itempath
+
+import (
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/itempath"
+	"github.com/google/uuid"
+)
+
+// ItemPath represents the path of a vault item.
+type ItemPath struct {
+	// UpdatedAt contains the timestamp of the last path change; omitted for items without a path.
+	UpdatedAt time.Time `json:"updated_at,omitzero" example:"2023-12-01T10:00:00Z"`
+	// Path contains the path of the item; empty when the item has no path.
+	Path string `json:"path"                example:"prod/db/payments"`
+	// ItemID contains the identifier of the addressed item.
+	ItemID uuid.UUID `json:"item_id"             example:"123e4567-e89b-12d3-a456-426614174000"`
+}
+
+// NewItemPathFromApp converts an application layer item path to delivery DTO.
+func NewItemPathFromApp(p *itempath.ItemPath) *ItemPath {
+	if p == nil {
+		return nil
+	}
+	return &ItemPath{
+		ItemID:    p.ItemID,
+		Path:      p.Path,
+		UpdatedAt: p.UpdatedAt,
+	}
+}
+
+// NewItemPathsFromApp converts application layer item paths to delivery DTOs.
+func NewItemPathsFromApp(ps []*itempath.ItemPath) []*ItemPath {
+	result := make([]*ItemPath, 0, len(ps))
+	for _, p := range ps {
+		result = append(result, NewItemPathFromApp(p))
+	}
+	return result
+}
+
+// ListPathsRequest represents the query of an item path listing.
+type ListPathsRequest struct {
+	// Prefix limits the listing to paths equal to or below it; omit to list every path.
+	Prefix string `form:"prefix" example:"prod/db"`
+}
+
+// ListPathsResponse represents the response containing the item paths of the user.
+type ListPathsResponse struct {
+	// Items contains the paths of the items ordered by path.
+	Items []*ItemPath `json:"items"`
+}
+
+// SetPathRequest represents the request to replace the path of an item.
+type SetPathRequest struct {
+	// Path contains the new path of the item; empty removes the path.
+	Path string `json:"path" example:"prod/db/payments"`
+}
+
+// ItemIDRequest represents the item addressed in the request path.
+type ItemIDRequest struct {
+	// ID contains the item identifier (required UUID format).
+	ID string `uri:"id" binding:"required,uuid" example:"123e4567-e89b-12d3-a456-426614174000"`
+}
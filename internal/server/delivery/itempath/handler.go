@@ -0,0 +1,186 @@
+package itempath
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/itempath"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/response"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/util"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Service defines the item path application service interface.
+type Service interface {
+	// ListPaths retrieves the item paths of the user equal to or below a prefix.
+	ListPaths(context.Context, itempath.ListParams) ([]*itempath.ItemPath, error)
+	// GetPath retrieves the path of an item.
+	GetPath(context.Context, itempath.GetParams) (*itempath.ItemPath, error)
+	// SetPath replaces the path of an item.
+	SetPath(context.Context, itempath.SetParams) (*itempath.ItemPath, error)
+}
+
+// Handler handles HTTP requests for item path endpoints.
+type Handler struct {
+	// s is the item path service used to process operations.
+	s Service
+}
+
+// NewHandler creates a new item path handler with the provided service.
+func NewHandler(s Service) *Handler {
+	return &Handler{s: s}
+}
+
+// List retrieves the item paths of the authenticated user.
+// @Summary      List item paths
+// @Description  Retrieves the paths of the items ordered by path, optionally only those equal to or below a prefix
+// @Tags         ItemPaths
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        prefix query string false "Path prefix" example(prod/db)
+// @Success      200 {object} ListPathsResponse "Item paths retrieved successfully"
+// @Failure      400 {object} response.Error "Bad request - invalid prefix"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
+// @Failure      403 {object} response.Error "Forbidden - restricted items cannot be revealed from this location"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /items/paths [get]
+// .
+func (h *Handler) List(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	userID, err := extractor.UserID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+
+	// req holds the deserialized query parameters of the request.
+	var req ListPathsRequest
+	if err := extractor.BindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	paths, err := h.s.ListPaths(c, itempath.ListParams{Prefix: req.Prefix, UserID: userID})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, ListPathsResponse{Items: NewItemPathsFromApp(paths)})
+}
+
+// Get retrieves the path of an item of the authenticated user.
+// @Summary      Get item path
+// @Description  Retrieves the path of an item; items without a path have an empty one
+// @Tags         ItemPaths
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Item ID" format(uuid)
+// @Success      200 {object} ItemPath "Item path retrieved successfully"
+// @Failure      400 {object} response.Error "Bad request - invalid ID format"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
+// @Failure      403 {object} response.Error "Forbidden - restricted items cannot be revealed from this location"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /items/paths/{id} [get]
+// .
+func (h *Handler) Get(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	userID, err := extractor.UserID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+
+	itemID, ok := bindItemID(c, extractor)
+	if !ok {
+		return
+	}
+
+	path, err := h.s.GetPath(c, itempath.GetParams{ItemID: itemID, UserID: userID})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, NewItemPathFromApp(path))
+}
+
+// Set replaces the path of an item of the authenticated user.
+// @Summary      Set item path
+// @Description  Replaces the path of an item. Paths such as prod/db/payments are unique among the items of
+// @Description  the user; surrounding slashes are dropped and an empty path removes the path of the item.
+// .
+// @Tags         ItemPaths
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Item ID" format(uuid)
+// @Param        request body SetPathRequest true "Item path"
+// @Success      200 {object} ItemPath "Item path replaced successfully"
+// @Failure      400 {object} response.Error "Bad request - invalid ID format or path"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
+// @Failure      403 {object} response.Error "Forbidden - restricted items cannot be changed from this location"
+// @Failure      409 {object} response.Error "Conflict - another item already has the path"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /items/paths/{id} [put]
+// .
+func (h *Handler) Set(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	userID, err := extractor.UserID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+
+	itemID, ok := bindItemID(c, extractor)
+	if !ok {
+		return
+	}
+
+	// req holds the deserialized JSON request payload for the path.
+	var req SetPathRequest
+	if err := extractor.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	path, err := h.s.SetPath(c, itempath.SetParams{Path: req.Path, ItemID: itemID, UserID: userID})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, NewItemPathFromApp(path))
+}
+
+// bindItemID extracts the item identifier from the request path, answering 400 Bad Request when it is malformed.
+func bindItemID(c *gin.Context, extractor *util.CtxExtractor) (uuid.UUID, bool) {
+	// req holds the deserialized URI parameters of the request.
+	var req ItemIDRequest
+	if err := extractor.BindURI(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return uuid.Nil, false
+	}
+
+	itemID, err := uuid.Parse(req.ID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return uuid.Nil, false
+	}
+	return itemID, true
+}
@@ -0,0 +1,278 @@
+package itempath
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/itempath"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/consts"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockPathService implements Service for testing.
+type mockPathService struct {
+	listFunc func(ctx context.Context, params itempath.ListParams) ([]*itempath.ItemPath, error)
+	getFunc  func(ctx context.Context, params itempath.GetParams) (*itempath.ItemPath, error)
+	setFunc  func(ctx context.Context, params itempath.SetParams) (*itempath.ItemPath, error)
+}
+
+func (m *mockPathService) ListPaths(ctx context.Context, params itempath.ListParams) ([]*itempath.ItemPath, error) {
+	if m.listFunc != nil {
+		return m.listFunc(ctx, params)
+	}
+	return nil, nil
+}
+
+func (m *mockPathService) GetPath(ctx context.Context, params itempath.GetParams) (*itempath.ItemPath, error) {
+	if m.getFunc != nil {
+		return m.getFunc(ctx, params)
+	}
+	return nil, nil
+}
+
+func (m *mockPathService) SetPath(ctx context.Context, params itempath.SetParams) (*itempath.ItemPath, error) {
+	if m.setFunc != nil {
+		return m.setFunc(ctx, params)
+	}
+	return nil, nil
+}
+
+func TestHandler_List(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	itemID := uuid.New()
+
+	tests := []struct {
+		mockService    *mockPathService
+		name           string
+		query          string
+		setUser        bool
+		wantCount      int
+		expectedStatus int
+	}{
+		{
+			name:    "success with prefix",
+			query:   "?prefix=prod/db",
+			setUser: true,
+			mockService: &mockPathService{
+				listFunc: func(_ context.Context, params itempath.ListParams) ([]*itempath.ItemPath, error) {
+					assert.Equal(t, itempath.ListParams{Prefix: "prod/db", UserID: userID}, params)
+					return []*itempath.ItemPath{{ItemID: itemID, Path: "prod/db/payments"}}, nil
+				},
+			},
+			wantCount:      1,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "missing user context",
+			mockService:    &mockPathService{},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:    "invalid prefix",
+			query:   "?prefix=prod/..",
+			setUser: true,
+			mockService: &mockPathService{
+				listFunc: func(context.Context, itempath.ListParams) ([]*itempath.ItemPath, error) {
+					return nil, itempath.ErrIncorrectPath
+				},
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:    "service error",
+			setUser: true,
+			mockService: &mockPathService{
+				listFunc: func(context.Context, itempath.ListParams) ([]*itempath.ItemPath, error) {
+					return nil, itempath.ErrItemPathTechError
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/items/paths"+tt.query, nil)
+			if tt.setUser {
+				c.Set(consts.CtxKeyUserID, userID)
+			}
+
+			NewHandler(tt.mockService).List(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.wantCount == 0 {
+				return
+			}
+			var got ListPathsResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+			require.Len(t, got.Items, tt.wantCount)
+			assert.Equal(t, itemID, got.Items[0].ItemID)
+			assert.Equal(t, "prod/db/payments", got.Items[0].Path)
+		})
+	}
+}
+
+func TestHandler_Get(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	itemID := uuid.New()
+
+	tests := []struct {
+		mockService    *mockPathService
+		name           string
+		id             string
+		wantBody       string
+		expectedStatus int
+	}{
+		{
+			name: "item without path",
+			id:   itemID.String(),
+			mockService: &mockPathService{
+				getFunc: func(_ context.Context, params itempath.GetParams) (*itempath.ItemPath, error) {
+					assert.Equal(t, itempath.GetParams{ItemID: itemID, UserID: userID}, params)
+					return &itempath.ItemPath{ItemID: itemID}, nil
+				},
+			},
+			wantBody:       `{"path":"","item_id":"` + itemID.String() + `"}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid ID",
+			id:             "payments",
+			mockService:    &mockPathService{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "service error",
+			id:   itemID.String(),
+			mockService: &mockPathService{
+				getFunc: func(context.Context, itempath.GetParams) (*itempath.ItemPath, error) {
+					return nil, itempath.ErrItemPathTechError
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/items/paths/"+tt.id, nil)
+			c.Params = gin.Params{{Key: "id", Value: tt.id}}
+			c.Set(consts.CtxKeyUserID, userID)
+
+			NewHandler(tt.mockService).Get(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.wantBody != "" {
+				assert.JSONEq(t, tt.wantBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandler_Set(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	itemID := uuid.New()
+
+	tests := []struct {
+		mockService    *mockPathService
+		name           string
+		id             string
+		body           string
+		expectedStatus int
+	}{
+		{
+			name: "success",
+			id:   itemID.String(),
+			body: `{"path":"/prod/db/payments/"}`,
+			mockService: &mockPathService{
+				setFunc: func(_ context.Context, params itempath.SetParams) (*itempath.ItemPath, error) {
+					assert.Equal(t, itempath.SetParams{
+						Path:   "/prod/db/payments/",
+						ItemID: itemID,
+						UserID: userID,
+					}, params)
+					return &itempath.ItemPath{ItemID: itemID, Path: "prod/db/payments"}, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid ID",
+			id:             "payments",
+			body:           `{"path":""}`,
+			mockService:    &mockPathService{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "malformed body",
+			id:             itemID.String(),
+			body:           `{"path":["prod"]}`,
+			mockService:    &mockPathService{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "invalid path",
+			id:   itemID.String(),
+			body: `{"path":"prod/../db"}`,
+			mockService: &mockPathService{
+				setFunc: func(context.Context, itempath.SetParams) (*itempath.ItemPath, error) {
+					return nil, errors.Join(itempath.ErrItemPathAppError, itempath.ErrIncorrectPath)
+				},
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "path taken",
+			id:   itemID.String(),
+			body: `{"path":"prod/db/payments"}`,
+			mockService: &mockPathService{
+				setFunc: func(context.Context, itempath.SetParams) (*itempath.ItemPath, error) {
+					return nil, itempath.ErrPathTaken
+				},
+			},
+			expectedStatus: http.StatusConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPut, "/items/paths/"+tt.id, strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Params = gin.Params{{Key: "id", Value: tt.id}}
+			c.Set(consts.CtxKeyUserID, userID)
+
+			NewHandler(tt.mockService).Set(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
@@ -0,0 +1,12 @@
+package itempath
+
+import "github.com/gin-gonic/gin"
+
+// RegisterRoutes registers item path routes with the provided router group.
+// Creates /paths and /paths/:id with the specified handler.
+func RegisterRoutes(r *gin.RouterGroup, h *Handler) {
+	pathsGroup := r.Group("/paths")
+	pathsGroup.GET("", h.List)
+	pathsGroup.GET("/:id", h.Get)
+	pathsGroup.PUT("/:id", h.Set)
+}
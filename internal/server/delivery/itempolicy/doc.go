@@ -0,0 +1,5 @@
+// Package itempolicy provides HTTP handlers for item policy endpoints in the AegisVaultKeeper server.
+//
+// This package reports the item policy rules applying to the authenticated user and maps the rejections
+// of item policy enforcement to error responses for the item endpoints.
+package itempolicy
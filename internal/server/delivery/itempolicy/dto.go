@@ -0,0 +1,25 @@
+package itempolicy
+
+import "github.com/gdyunin/aegis-vault-keeper/internal/server/application/itempolicy"
+
+// RulesResponse represents the item policy rules applying to the authenticated user.
+type RulesResponse struct {
+	// ForbiddenKinds lists the item types that may not be stored.
+	ForbiddenKinds []string `json:"forbidden_kinds"      example:"bankcard"`
+	// RequiredClasses lists the character classes every credential password must contain.
+	RequiredClasses []string `json:"required_classes"     example:"digit,upper"`
+	// MinPasswordLength specifies the minimum length of credential passwords in characters; zero sets none.
+	MinPasswordLength int `json:"min_password_length"  example:"12"`
+	// BanCommonPasswords reports whether commonly used credential passwords are rejected.
+	BanCommonPasswords bool `json:"ban_common_passwords" example:"true"`
+}
+
+// NewRulesResponseFromApp converts application layer item policy rules to delivery DTO.
+func NewRulesResponseFromApp(r *itempolicy.Rules) RulesResponse {
+	return RulesResponse{
+		ForbiddenKinds:     append([]string{}, r.ForbiddenKinds...),
+		RequiredClasses:    append([]string{}, r.RequiredClasses...),
+		MinPasswordLength:  r.MinPasswordLength,
+		BanCommonPasswords: r.BanCommonPasswords,
+	}
+}
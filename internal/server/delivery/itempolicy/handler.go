@@ -0,0 +1,64 @@
+package itempolicy
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/itempolicy"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/response"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/util"
+	"github.com/gin-gonic/gin"
+)
+
+// Service defines the item policy application service interface.
+type Service interface {
+	// Effective retrieves the rules of the item policies applying to the user merged into one.
+	Effective(ctx context.Context, params itempolicy.EffectiveParams) (*itempolicy.Rules, error)
+}
+
+// Handler handles HTTP requests for item policy endpoints.
+type Handler struct {
+	// s is the item policy service used to resolve the rules.
+	s Service
+}
+
+// NewHandler creates a new item policy handler with the provided service.
+func NewHandler(s Service) *Handler {
+	return &Handler{s: s}
+}
+
+// Effective returns the item policy rules applying to the authenticated user.
+// @Summary      Get item policy
+// @Description  Reports the rules of the item policies applying to the user, merged into the strictest
+// @Description  combination. Pushing a forbidden item type is answered with 403 Forbidden and a credential
+// @Description  password breaking the rules with 400 Bad Request
+// .
+// @Tags         Account
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} RulesResponse "Item policy rules retrieved successfully"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /account/item-policy [get]
+// .
+func (h *Handler) Effective(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	userID, err := extractor.UserID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+
+	rules, err := h.s.Effective(c, itempolicy.EffectiveParams{UserID: userID})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, NewRulesResponseFromApp(rules))
+}
@@ -0,0 +1,175 @@
+package itempolicy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/itempolicy"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/consts"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockItemPolicyService implements Service for testing.
+type mockItemPolicyService struct {
+	effectiveFunc func(ctx context.Context, params itempolicy.EffectiveParams) (*itempolicy.Rules, error)
+}
+
+func (m *mockItemPolicyService) Effective(
+	ctx context.Context,
+	params itempolicy.EffectiveParams,
+) (*itempolicy.Rules, error) {
+	if m.effectiveFunc != nil {
+		return m.effectiveFunc(ctx, params)
+	}
+	return &itempolicy.Rules{}, nil
+}
+
+func TestHandler_Effective(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+
+	tests := []struct {
+		setupContext   func(c *gin.Context)
+		mockService    *mockItemPolicyService
+		want           *RulesResponse
+		name           string
+		expectedStatus int
+	}{
+		{
+			name: "rules of the user",
+			setupContext: func(c *gin.Context) {
+				c.Set(consts.CtxKeyUserID, userID)
+			},
+			mockService: &mockItemPolicyService{
+				effectiveFunc: func(_ context.Context, params itempolicy.EffectiveParams) (*itempolicy.Rules, error) {
+					assert.Equal(t, userID, params.UserID)
+					return &itempolicy.Rules{
+						ForbiddenKinds:     []string{"bankcard"},
+						RequiredClasses:    []string{"digit"},
+						MinPasswordLength:  12,
+						BanCommonPasswords: true,
+					}, nil
+				},
+			},
+			want: &RulesResponse{
+				ForbiddenKinds:     []string{"bankcard"},
+				RequiredClasses:    []string{"digit"},
+				MinPasswordLength:  12,
+				BanCommonPasswords: true,
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "no policies",
+			setupContext: func(c *gin.Context) {
+				c.Set(consts.CtxKeyUserID, userID)
+			},
+			mockService:    &mockItemPolicyService{},
+			want:           &RulesResponse{ForbiddenKinds: []string{}, RequiredClasses: []string{}},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "missing user context",
+			setupContext: func(c *gin.Context) {
+				// don't set user_id
+			},
+			mockService:    &mockItemPolicyService{},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name: "service error",
+			setupContext: func(c *gin.Context) {
+				c.Set(consts.CtxKeyUserID, userID)
+			},
+			mockService: &mockItemPolicyService{
+				effectiveFunc: func(context.Context, itempolicy.EffectiveParams) (*itempolicy.Rules, error) {
+					return nil, errors.Join(itempolicy.ErrItemPolicyTechError, errors.New("database unavailable"))
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/account/item-policy", nil)
+
+			tt.setupContext(c)
+
+			NewHandler(tt.mockService).Effective(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.want == nil {
+				return
+			}
+			var got RulesResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+			assert.Equal(t, *tt.want, got)
+		})
+	}
+}
+
+func TestItemPolicyErrRegistry(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		err      error
+		name     string
+		wantMsgs []string
+		wantCode int
+	}{
+		{
+			name:     "forbidden kind",
+			err:      fmt.Errorf("bankcard items are forbidden: %w", itempolicy.ErrItemKindForbidden),
+			wantCode: http.StatusForbidden,
+			wantMsgs: []string{"An item policy forbids storing items of this type"},
+		},
+		{
+			name: "every broken rule reported",
+			err: errors.Join(
+				itempolicy.ErrWeakPassword, itempolicy.ErrPasswordTooShort, itempolicy.ErrPasswordNoDigit,
+			),
+			wantCode: http.StatusBadRequest,
+			wantMsgs: []string{
+				"Password does not meet the item policy",
+				"Password is shorter than the item policy requires",
+				"Password must contain a digit",
+			},
+		},
+		{
+			name:     "technical error",
+			err:      errors.Join(itempolicy.ErrItemPolicyTechError, errors.New("connection lost")),
+			wantCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+
+			code, msgs := handleError(tt.err, c)
+
+			assert.Equal(t, tt.wantCode, code)
+			if tt.wantMsgs != nil {
+				assert.ElementsMatch(t, tt.wantMsgs, msgs)
+			}
+		})
+	}
+}
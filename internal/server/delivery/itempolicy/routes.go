@@ -0,0 +1,9 @@
+package itempolicy
+
+import "github.com/gin-gonic/gin"
+
+// RegisterRoutes registers item policy routes with the provided router group.
+// Creates /item-policy with the specified handler.
+func RegisterRoutes(r *gin.RouterGroup, h *Handler) {
+	r.GET("/item-policy", h.Effective)
+}
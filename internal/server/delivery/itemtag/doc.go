@@ -0,0 +1,5 @@
+// Package itemtag provides HTTP handlers for item tag endpoints in the AegisVaultKeeper server.
+//
+// This package lets authenticated users label their vault items, including with the restricted tag
+// that limits reveals of an item to the locations configured by the operator.
+package itemtag
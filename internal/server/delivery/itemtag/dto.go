@@ -0,0 +1,61 @@
+package itemtag
+
+import (
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/itemtag"
+	"github.com/google/uuid"
+)
+
+// ItemTags represents the tags of a vault item.
+type ItemTags struct {
+	// UpdatedAt contains the timestamp of the last tag change.
+	UpdatedAt time.Time `json:"updated_at,omitzero" example:"2023-12-01T10:00:00Z"`
+	// Tags contains the sorted tags of the item.
+	Tags []string `json:"tags"                example:"restricted,work"`
+	// ItemID contains the identifier of the tagged item.
+	ItemID uuid.UUID `json:"item_id"             example:"123e4567-e89b-12d3-a456-426614174000"`
+}
+
+// NewItemTagsFromApp converts application layer item tags to delivery DTO.
+func NewItemTagsFromApp(t *itemtag.ItemTags) *ItemTags {
+	if t == nil {
+		return nil
+	}
+	tags := t.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	return &ItemTags{
+		ItemID:    t.ItemID,
+		Tags:      tags,
+		UpdatedAt: t.UpdatedAt,
+	}
+}
+
+// NewItemTagsListFromApp converts application layer item tags to delivery DTOs.
+func NewItemTagsListFromApp(ts []*itemtag.ItemTags) []*ItemTags {
+	result := make([]*ItemTags, 0, len(ts))
+	for _, t := range ts {
+		result = append(result, NewItemTagsFromApp(t))
+	}
+	return result
+}
+
+// ListTagsResponse represents the response containing the tagged items of the user.
+type ListTagsResponse struct {
+	// Items contains the tags of every tagged item, most recently changed first.
+	Items []*ItemTags `json:"items"`
+}
+
+// SetTagsRequest represents the request to replace the tags of an item.
+type SetTagsRequest struct {
+	// Tags contains the new tags of the item; an empty list removes all tags.
+	Tags []string `json:"tags" example:"restricted,work"`
+}
+
+// ItemIDRequest represents the item addressed in the request path.
+type ItemIDRequest struct {
+	// ID contains the item identifier (required UUID format).
+	ID string `uri:"id" binding:"required,uuid" example:"123e4567-e89b-12d3-a456-426614174000"`
+}
@@ -0,0 +1,60 @@
+package itemtag
+
+import (
+	"fmt"
+	"net/http"
+
+	app "github.com/gdyunin/aegis-vault-keeper/internal/server/application/itemtag"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/errutil"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/itemtag"
+	"github.com/gin-gonic/gin"
+)
+
+// ItemTagErrRegistry defines error handling policies for item tag operations.
+var ItemTagErrRegistry = errutil.Registry{
+	{
+		ErrorIn: app.ErrItemTagTechError,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusInternalServerError,
+			PublicMsg:  http.StatusText(http.StatusInternalServerError),
+			LogIt:      true,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassTech,
+		},
+	},
+	{
+		ErrorIn: app.ErrIncorrectTag,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusBadRequest,
+			PublicMsg:  "Tags must be up to 32 letters, digits, dashes or underscores starting with a letter or digit",
+			LogIt:      false,
+			AllowMerge: true,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+	{
+		ErrorIn: app.ErrTooManyTags,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusBadRequest,
+			PublicMsg:  fmt.Sprintf("An item can have at most %d tags", itemtag.MaxTags),
+			LogIt:      false,
+			AllowMerge: true,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+	{
+		ErrorIn: app.ErrItemTagAppError,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusBadRequest,
+			PublicMsg:  "Invalid item tag parameters",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+}
+
+// handleError processes item tag errors using the registry and returns appropriate HTTP response.
+func handleError(err error, c *gin.Context) (int, []string) {
+	return errutil.HandleWithRegistry(ItemTagErrRegistry, err, c)
+}
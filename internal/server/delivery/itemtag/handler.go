@@ -0,0 +1,176 @@
+package itemtag
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/itemtag"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/response"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/util"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Service defines the item tag application service interface.
+type Service interface {
+	// ListTags retrieves the tags of every tagged item of the user.
+	ListTags(context.Context, itemtag.ListParams) ([]*itemtag.ItemTags, error)
+	// GetTags retrieves the tags of an item.
+	GetTags(context.Context, itemtag.GetParams) (*itemtag.ItemTags, error)
+	// SetTags replaces the tags of an item.
+	SetTags(context.Context, itemtag.SetParams) (*itemtag.ItemTags, error)
+}
+
+// Handler handles HTTP requests for item tag endpoints.
+type Handler struct {
+	// s is the item tag service used to process operations.
+	s Service
+}
+
+// NewHandler creates a new item tag handler with the provided service.
+func NewHandler(s Service) *Handler {
+	return &Handler{s: s}
+}
+
+// List retrieves the tags of every tagged item of the authenticated user.
+// @Summary      List item tags
+// @Description  Retrieves the tags of every tagged item, most recently changed first
+// @Tags         ItemTags
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} ListTagsResponse "Item tags retrieved successfully"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
+// @Failure      403 {object} response.Error "Forbidden - restricted items cannot be revealed from this location"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /items/tags [get]
+// .
+func (h *Handler) List(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	userID, err := extractor.UserID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+
+	tags, err := h.s.ListTags(c, itemtag.ListParams{UserID: userID})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, ListTagsResponse{Items: NewItemTagsListFromApp(tags)})
+}
+
+// Get retrieves the tags of an item of the authenticated user.
+// @Summary      Get item tags
+// @Description  Retrieves the tags of an item; untagged items have an empty tag list
+// @Tags         ItemTags
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Item ID" format(uuid)
+// @Success      200 {object} ItemTags "Item tags retrieved successfully"
+// @Failure      400 {object} response.Error "Bad request - invalid ID format"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
+// @Failure      403 {object} response.Error "Forbidden - restricted items cannot be revealed from this location"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /items/tags/{id} [get]
+// .
+func (h *Handler) Get(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	userID, err := extractor.UserID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+
+	itemID, ok := bindItemID(c, extractor)
+	if !ok {
+		return
+	}
+
+	tags, err := h.s.GetTags(c, itemtag.GetParams{ItemID: itemID, UserID: userID})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, NewItemTagsFromApp(tags))
+}
+
+// Set replaces the tags of an item of the authenticated user.
+// @Summary      Set item tags
+// @Description  Replaces the tags of an item. Tags are lowercased, deduplicated and sorted; the restricted tag
+// @Description  limits reveals of the item to the locations configured by the operator.
+// .
+// @Tags         ItemTags
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Item ID" format(uuid)
+// @Param        request body SetTagsRequest true "Item tags"
+// @Success      200 {object} ItemTags "Item tags replaced successfully"
+// @Failure      400 {object} response.Error "Bad request - invalid ID format or tags"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
+// @Failure      403 {object} response.Error "Forbidden - restricted items cannot be changed from this location"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /items/tags/{id} [put]
+// .
+func (h *Handler) Set(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	userID, err := extractor.UserID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+
+	itemID, ok := bindItemID(c, extractor)
+	if !ok {
+		return
+	}
+
+	// req holds the deserialized JSON request payload for the tags.
+	var req SetTagsRequest
+	if err := extractor.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	tags, err := h.s.SetTags(c, itemtag.SetParams{Tags: req.Tags, ItemID: itemID, UserID: userID})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, NewItemTagsFromApp(tags))
+}
+
+// bindItemID extracts the item identifier from the request path, answering 400 Bad Request when it is malformed.
+func bindItemID(c *gin.Context, extractor *util.CtxExtractor) (uuid.UUID, bool) {
+	// req holds the deserialized URI parameters of the request.
+	var req ItemIDRequest
+	if err := extractor.BindURI(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return uuid.Nil, false
+	}
+
+	itemID, err := uuid.Parse(req.ID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return uuid.Nil, false
+	}
+	return itemID, true
+}
@@ -0,0 +1,254 @@
+package itemtag
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/itemtag"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/consts"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockTagService implements Service for testing.
+type mockTagService struct {
+	listFunc func(ctx context.Context, params itemtag.ListParams) ([]*itemtag.ItemTags, error)
+	getFunc  func(ctx context.Context, params itemtag.GetParams) (*itemtag.ItemTags, error)
+	setFunc  func(ctx context.Context, params itemtag.SetParams) (*itemtag.ItemTags, error)
+}
+
+func (m *mockTagService) ListTags(ctx context.Context, params itemtag.ListParams) ([]*itemtag.ItemTags, error) {
+	if m.listFunc != nil {
+		return m.listFunc(ctx, params)
+	}
+	return nil, nil
+}
+
+func (m *mockTagService) GetTags(ctx context.Context, params itemtag.GetParams) (*itemtag.ItemTags, error) {
+	if m.getFunc != nil {
+		return m.getFunc(ctx, params)
+	}
+	return nil, nil
+}
+
+func (m *mockTagService) SetTags(ctx context.Context, params itemtag.SetParams) (*itemtag.ItemTags, error) {
+	if m.setFunc != nil {
+		return m.setFunc(ctx, params)
+	}
+	return nil, nil
+}
+
+func TestHandler_List(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	itemID := uuid.New()
+
+	tests := []struct {
+		mockService    *mockTagService
+		name           string
+		setUser        bool
+		wantCount      int
+		expectedStatus int
+	}{
+		{
+			name:    "success",
+			setUser: true,
+			mockService: &mockTagService{
+				listFunc: func(_ context.Context, params itemtag.ListParams) ([]*itemtag.ItemTags, error) {
+					assert.Equal(t, itemtag.ListParams{UserID: userID}, params)
+					return []*itemtag.ItemTags{{ItemID: itemID, Tags: []string{"restricted"}}}, nil
+				},
+			},
+			wantCount:      1,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "missing user context",
+			mockService:    &mockTagService{},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:    "service error",
+			setUser: true,
+			mockService: &mockTagService{
+				listFunc: func(context.Context, itemtag.ListParams) ([]*itemtag.ItemTags, error) {
+					return nil, itemtag.ErrItemTagTechError
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/items/tags", nil)
+			if tt.setUser {
+				c.Set(consts.CtxKeyUserID, userID)
+			}
+
+			NewHandler(tt.mockService).List(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.wantCount == 0 {
+				return
+			}
+			var got ListTagsResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+			require.Len(t, got.Items, tt.wantCount)
+			assert.Equal(t, itemID, got.Items[0].ItemID)
+			assert.Equal(t, []string{"restricted"}, got.Items[0].Tags)
+		})
+	}
+}
+
+func TestHandler_Get(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	itemID := uuid.New()
+
+	tests := []struct {
+		mockService    *mockTagService
+		name           string
+		id             string
+		wantBody       string
+		expectedStatus int
+	}{
+		{
+			name: "untagged item",
+			id:   itemID.String(),
+			mockService: &mockTagService{
+				getFunc: func(_ context.Context, params itemtag.GetParams) (*itemtag.ItemTags, error) {
+					assert.Equal(t, itemtag.GetParams{ItemID: itemID, UserID: userID}, params)
+					return &itemtag.ItemTags{ItemID: itemID}, nil
+				},
+			},
+			wantBody:       `{"tags":[],"item_id":"` + itemID.String() + `"}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid ID",
+			id:             "passport",
+			mockService:    &mockTagService{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "service error",
+			id:   itemID.String(),
+			mockService: &mockTagService{
+				getFunc: func(context.Context, itemtag.GetParams) (*itemtag.ItemTags, error) {
+					return nil, itemtag.ErrItemTagTechError
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/items/tags/"+tt.id, nil)
+			c.Params = gin.Params{{Key: "id", Value: tt.id}}
+			c.Set(consts.CtxKeyUserID, userID)
+
+			NewHandler(tt.mockService).Get(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.wantBody != "" {
+				assert.JSONEq(t, tt.wantBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandler_Set(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	itemID := uuid.New()
+
+	tests := []struct {
+		mockService    *mockTagService
+		name           string
+		id             string
+		body           string
+		expectedStatus int
+	}{
+		{
+			name: "success",
+			id:   itemID.String(),
+			body: `{"tags":["Restricted","work"]}`,
+			mockService: &mockTagService{
+				setFunc: func(_ context.Context, params itemtag.SetParams) (*itemtag.ItemTags, error) {
+					assert.Equal(t, itemtag.SetParams{
+						Tags:   []string{"Restricted", "work"},
+						ItemID: itemID,
+						UserID: userID,
+					}, params)
+					return &itemtag.ItemTags{ItemID: itemID, Tags: []string{"restricted", "work"}}, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid ID",
+			id:             "passport",
+			body:           `{"tags":[]}`,
+			mockService:    &mockTagService{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "malformed body",
+			id:             itemID.String(),
+			body:           `{"tags":"restricted"}`,
+			mockService:    &mockTagService{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "invalid tag",
+			id:   itemID.String(),
+			body: `{"tags":["top secret"]}`,
+			mockService: &mockTagService{
+				setFunc: func(context.Context, itemtag.SetParams) (*itemtag.ItemTags, error) {
+					return nil, errors.Join(itemtag.ErrItemTagAppError, itemtag.ErrIncorrectTag)
+				},
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPut, "/items/tags/"+tt.id, strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Params = gin.Params{{Key: "id", Value: tt.id}}
+			c.Set(consts.CtxKeyUserID, userID)
+
+			NewHandler(tt.mockService).Set(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
@@ -0,0 +1,12 @@
+package itemtag
+
+import "github.com/gin-gonic/gin"
+
+// RegisterRoutes registers item tag routes with the provided router group.
+// Creates /tags and /tags/:id with the specified handler.
+func RegisterRoutes(r *gin.RouterGroup, h *Handler) {
+	tagsGroup := r.Group("/tags")
+	tagsGroup.GET("", h.List)
+	tagsGroup.GET("/:id", h.Get)
+	tagsGroup.PUT("/:id", h.Set)
+}
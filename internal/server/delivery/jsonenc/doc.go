@@ -0,0 +1,8 @@
+// Package jsonenc provides reflection-free JSON encoding for the high-volume responses of the delivery layer.
+//
+// List and sync responses of large vaults hold tens of thousands of items; encoding them with encoding/json
+// walks every field through reflection and allocates for each timestamp and identifier. Response types on
+// those paths implement Appender instead, appending their encoding to a byte slice with the helpers of this
+// package, and handlers render them through JSON, which reuses pooled buffers across requests. The output
+// is byte-for-byte identical to encoding/json, HTML escaping included, so clients see no difference.
+package jsonenc
@@ -0,0 +1,162 @@
+package jsonenc
+
+import (
+	"encoding/base64"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// hexDigits holds the lowercase hexadecimal digits.
+const hexDigits = "0123456789abcdef"
+
+// Appender is implemented by response types that append their own JSON encoding.
+type Appender interface {
+	// AppendJSON appends the JSON encoding of the value to dst and returns the extended slice.
+	// A nil receiver appends null.
+	AppendJSON(dst []byte) []byte
+}
+
+// Key appends the key of an object member, preceded by a comma unless it follows the opening brace.
+// The key is written as is and must not need escaping.
+func Key(dst []byte, key string) []byte {
+	if len(dst) > 0 && dst[len(dst)-1] != '{' {
+		dst = append(dst, ',')
+	}
+	dst = append(dst, '"')
+	dst = append(dst, key...)
+	return append(dst, '"', ':')
+}
+
+// Null appends null.
+func Null(dst []byte) []byte {
+	return append(dst, "null"...)
+}
+
+// String appends s as a JSON string. Characters are escaped like encoding/json does with HTML escaping:
+// quotes, backslashes, control characters, <, >, &, U+2028 and U+2029 are escaped and invalid UTF-8 is
+// replaced with U+FFFD.
+func String(dst []byte, s string) []byte {
+	dst = append(dst, '"')
+	start := 0
+	for i := 0; i < len(s); {
+		if b := s[i]; b < utf8.RuneSelf {
+			if htmlSafe(b) {
+				i++
+				continue
+			}
+			dst = append(dst, s[start:i]...)
+			switch b {
+			case '\\', '"':
+				dst = append(dst, '\\', b)
+			case '\b':
+				dst = append(dst, '\\', 'b')
+			case '\f':
+				dst = append(dst, '\\', 'f')
+			case '\n':
+				dst = append(dst, '\\', 'n')
+			case '\r':
+				dst = append(dst, '\\', 'r')
+			case '\t':
+				dst = append(dst, '\\', 't')
+			default:
+				dst = append(dst, '\\', 'u', '0', '0', hexDigits[b>>4], hexDigits[b&0xF])
+			}
+			i++
+			start = i
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case r == utf8.RuneError && size == 1:
+			dst = append(dst, s[start:i]...)
+			dst = append(dst, "\ufffd"...)
+		case r == '\u2028' || r == '\u2029':
+			dst = append(dst, s[start:i]...)
+			dst = append(dst, '\\', 'u', '2', '0', '2', hexDigits[r&0xF])
+		default:
+			i += size
+			continue
+		}
+		i += size
+		start = i
+	}
+	dst = append(dst, s[start:]...)
+	return append(dst, '"')
+}
+
+// htmlSafe reports whether the ASCII character is written without escaping.
+func htmlSafe(b byte) bool {
+	return b >= 0x20 && b != '"' && b != '\\' && b != '<' && b != '>' && b != '&'
+}
+
+// Bytes appends b as a base64-encoded JSON string, or null for a nil slice.
+func Bytes(dst, b []byte) []byte {
+	if b == nil {
+		return Null(dst)
+	}
+	dst = append(dst, '"')
+	dst = base64.StdEncoding.AppendEncode(dst, b)
+	return append(dst, '"')
+}
+
+// Time appends t as an RFC 3339 JSON string with nanoseconds, like time.Time.MarshalJSON.
+func Time(dst []byte, t time.Time) []byte {
+	dst = append(dst, '"')
+	dst = t.AppendFormat(dst, time.RFC3339Nano)
+	return append(dst, '"')
+}
+
+// UUID appends id as a JSON string in its canonical hyphenated form.
+func UUID(dst []byte, id uuid.UUID) []byte {
+	dst = append(dst, '"')
+	for i, b := range id {
+		if i == 4 || i == 6 || i == 8 || i == 10 {
+			dst = append(dst, '-')
+		}
+		dst = append(dst, hexDigits[b>>4], hexDigits[b&0xF])
+	}
+	return append(dst, '"')
+}
+
+// Int appends n as a JSON number.
+func Int(dst []byte, n int64) []byte {
+	return strconv.AppendInt(dst, n, 10)
+}
+
+// Bool appends b as a JSON boolean.
+func Bool(dst []byte, b bool) []byte {
+	return strconv.AppendBool(dst, b)
+}
+
+// Array appends the items as a JSON array, or null for a nil slice.
+func Array[T Appender](dst []byte, items []T) []byte {
+	if items == nil {
+		return Null(dst)
+	}
+	dst = append(dst, '[')
+	for i, item := range items {
+		if i > 0 {
+			dst = append(dst, ',')
+		}
+		dst = item.AppendJSON(dst)
+	}
+	return append(dst, ']')
+}
+
+// Strings appends the strings as a JSON array, or null for a nil slice.
+func Strings(dst []byte, items []string) []byte {
+	if items == nil {
+		return Null(dst)
+	}
+	dst = append(dst, '[')
+	for i, s := range items {
+		if i > 0 {
+			dst = append(dst, ',')
+		}
+		dst = String(dst, s)
+	}
+	return append(dst, ']')
+}
@@ -0,0 +1,135 @@
+package jsonenc
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testItem is an Appender encoding a single string member.
+type testItem struct {
+	// Name is the encoded member.
+	Name string `json:"name"`
+}
+
+// AppendJSON implements Appender.
+func (i *testItem) AppendJSON(dst []byte) []byte {
+	if i == nil {
+		return Null(dst)
+	}
+	dst = append(dst, '{')
+	dst = Key(dst, "name")
+	dst = String(dst, i.Name)
+	return append(dst, '}')
+}
+
+// marshal returns the encoding/json encoding of v.
+func marshal(t *testing.T, v any) string {
+	t.Helper()
+	want, err := json.Marshal(v)
+	require.NoError(t, err)
+	return string(want)
+}
+
+func TestString(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "empty", input: ""},
+		{name: "plain", input: "user@example.com"},
+		{name: "quotes and backslashes", input: `say "hi" \ bye`},
+		{name: "short escapes", input: "a\nb\rc\td\be\ff"},
+		{name: "control characters", input: "\x00\x01\x1f\x7f"},
+		{name: "html", input: "<script>alert('x') && 1</script>"},
+		{name: "multibyte", input: "пароль 密码 🔑"},
+		{name: "line separators", input: "a\u2028b\u2029c"},
+		{name: "invalid utf-8", input: "a\xffb\xc3"},
+		{name: "escape at end", input: "tail\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, marshal(t, tt.input), string(String(nil, tt.input)))
+		})
+	}
+}
+
+func TestValues(t *testing.T) {
+	t.Parallel()
+
+	id := uuid.MustParse("123e4567-e89b-12d3-a456-426614174000")
+	at := time.Date(2025, 5, 6, 7, 8, 9, 123456700, time.FixedZone("UTC+3", 3*60*60))
+
+	tests := []struct {
+		value any
+		name  string
+		got   []byte
+	}{
+		{name: "uuid", value: id, got: UUID(nil, id)},
+		{name: "nil uuid", value: uuid.Nil, got: UUID(nil, uuid.Nil)},
+		{name: "time", value: at, got: Time(nil, at)},
+		{name: "utc time", value: at.UTC().Truncate(time.Second), got: Time(nil, at.UTC().Truncate(time.Second))},
+		{name: "zero time", value: time.Time{}, got: Time(nil, time.Time{})},
+		{name: "bytes", value: []byte("file content\x00\xff"), got: Bytes(nil, []byte("file content\x00\xff"))},
+		{name: "empty bytes", value: []byte{}, got: Bytes(nil, []byte{})},
+		{name: "nil bytes", value: []byte(nil), got: Bytes(nil, nil)},
+		{name: "int", value: int64(-1701424800000000), got: Int(nil, -1701424800000000)},
+		{name: "bool", value: true, got: Bool(nil, true)},
+		{
+			name:  "array",
+			value: []*testItem{{Name: "a"}, nil, {Name: "<b>"}},
+			got:   Array(nil, []*testItem{{Name: "a"}, nil, {Name: "<b>"}}),
+		},
+		{name: "empty array", value: []*testItem{}, got: Array(nil, []*testItem{})},
+		{name: "nil array", value: []*testItem(nil), got: Array(nil, []*testItem(nil))},
+		{name: "strings", value: []string{"a", "<b>"}, got: Strings(nil, []string{"a", "<b>"})},
+		{name: "nil strings", value: []string(nil), got: Strings(nil, nil)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, marshal(t, tt.value), string(tt.got))
+		})
+	}
+}
+
+func TestKey(t *testing.T) {
+	t.Parallel()
+
+	dst := append([]byte(nil), '{')
+	dst = Key(dst, "a")
+	dst = Int(dst, 1)
+	dst = Key(dst, "b")
+	dst = Null(dst)
+	dst = append(dst, '}')
+
+	assert.JSONEq(t, `{"a":1,"b":null}`, string(dst))
+}
+
+func TestAppend_DoesNotAllocate(t *testing.T) {
+	id := uuid.New()
+	at := time.Now()
+	items := []*testItem{{Name: "a"}, {Name: "b & c"}}
+	dst := make([]byte, 0, 1024)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		out := String(dst[:0], "user@example.com <admin>")
+		out = UUID(out, id)
+		out = Time(out, at)
+		out = Int(out, 42)
+		out = Bytes(out, []byte("content"))
+		_ = Array(out, items)
+	})
+	assert.Zero(t, allocs)
+}
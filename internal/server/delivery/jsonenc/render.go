@@ -0,0 +1,51 @@
+package jsonenc
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// maxPooledBuffer is the capacity above which buffers are dropped instead of returned to the pool,
+// so a single huge response does not pin its memory for the lifetime of the process.
+const maxPooledBuffer = 4 << 20
+
+// bufferPool holds the buffers responses are encoded into.
+var bufferPool = sync.Pool{
+	New: func() any {
+		buf := make([]byte, 0, 64<<10)
+		return &buf
+	},
+}
+
+// JSON renders an Appender as a JSON response body, encoding it into a pooled buffer.
+// It implements the gin render.Render interface: c.Render(http.StatusOK, jsonenc.JSON{Value: resp}).
+type JSON struct {
+	// Value is the response to encode.
+	Value Appender
+}
+
+// Render writes the JSON encoding of the value with the JSON content type.
+func (r JSON) Render(w http.ResponseWriter) error {
+	r.WriteContentType(w)
+
+	buf, _ := bufferPool.Get().(*[]byte)
+	body := r.Value.AppendJSON((*buf)[:0])
+	_, err := w.Write(body)
+	if cap(body) <= maxPooledBuffer {
+		*buf = body
+		bufferPool.Put(buf)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write response: %w", err)
+	}
+	return nil
+}
+
+// WriteContentType sets the JSON content type, matching the responses of gin's JSON renderer.
+func (r JSON) WriteContentType(w http.ResponseWriter) {
+	header := w.Header()
+	if val := header["Content-Type"]; len(val) == 0 {
+		header["Content-Type"] = []string{"application/json; charset=utf-8"}
+	}
+}
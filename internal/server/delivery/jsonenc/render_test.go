@@ -0,0 +1,74 @@
+package jsonenc
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSON_Render(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		value    Appender
+		name     string
+		wantBody string
+	}{
+		{name: "small", value: &testItem{Name: "<alice>"}, wantBody: `{"name":"\u003calice\u003e"}`},
+		{
+			name:     "larger than a pooled buffer",
+			value:    &testItem{Name: strings.Repeat("x", maxPooledBuffer+1)},
+			wantBody: `{"name":"` + strings.Repeat("x", maxPooledBuffer+1) + `"}`,
+		},
+		{name: "nil", value: (*testItem)(nil), wantBody: "null"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Render(http.StatusOK, JSON{Value: tt.value})
+
+			require.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+			assert.Equal(t, tt.wantBody, w.Body.String())
+		})
+	}
+}
+
+func BenchmarkJSON_Render(b *testing.B) {
+	items := make([]*testItem, 10_000)
+	for i := range items {
+		items[i] = &testItem{Name: "user@example.com"}
+	}
+	value := &testList{Items: items}
+	w := httptest.NewRecorder()
+
+	for range b.N {
+		w.Body.Reset()
+		if err := (JSON{Value: value}).Render(w); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// testList is an Appender encoding a list of items.
+type testList struct {
+	// Items is the encoded list.
+	Items []*testItem
+}
+
+// AppendJSON implements Appender.
+func (l *testList) AppendJSON(dst []byte) []byte {
+	dst = append(dst, '{')
+	dst = Key(dst, "items")
+	dst = Array(dst, l.Items)
+	return append(dst, '}')
+}
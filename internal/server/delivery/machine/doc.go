@@ -0,0 +1,5 @@
+// Package machine provides HTTP handlers for machine identity and lease endpoints in the AegisVaultKeeper server.
+//
+// This package lets authenticated users issue scoped tokens to their CI/CD pipelines and other automations,
+// and lets those automations exchange the tokens for short-lived leases of the secrets in their scope.
+package machine
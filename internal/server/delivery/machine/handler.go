@@ -0,0 +1,456 @@
+package machine
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/accesscontrol"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/accesspolicy"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/approval"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/machine"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/clientinfo"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/response"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/util"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Service defines the machine identity application service interface.
+type Service interface {
+	// Create issues a new machine identity to an automation of the user.
+	Create(context.Context, machine.CreateParams) (*machine.Machine, error)
+	// List retrieves the machine identities of the user.
+	List(context.Context, machine.ListParams) ([]*machine.Machine, error)
+	// Delete revokes a machine identity of the user.
+	Delete(context.Context, machine.DeleteParams) error
+	// Lease reveals items in the scope of the machine identity holding the token.
+	Lease(context.Context, machine.LeaseParams) (*machine.Lease, error)
+	// LeasePath reveals the item with a path in the scope of the machine identity holding the token.
+	LeasePath(context.Context, machine.LeasePathParams) (*machine.Lease, error)
+	// ListPaths retrieves the item paths in the scope of the machine identity holding the token.
+	ListPaths(context.Context, machine.ListPathsParams) ([]*machine.Path, error)
+}
+
+// Handler handles HTTP requests for machine identity and lease endpoints.
+type Handler struct {
+	// s is the machine identity service used to process operations.
+	s Service
+}
+
+// NewHandler creates a new machine identity handler with the provided service.
+func NewHandler(s Service) *Handler {
+	return &Handler{s: s}
+}
+
+// List retrieves the machine identities of the authenticated user.
+// @Summary      List machine identities
+// @Description  Retrieves the machine identities of the user with their scopes; tokens are not returned
+// @Tags         Account
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} ListResponse "Machine identities retrieved successfully"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /account/machines [get]
+// .
+func (h *Handler) List(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	userID, err := extractor.UserID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+
+	machines, err := h.s.List(c, machine.ListParams{UserID: userID})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, ListResponse{Machines: NewMachinesFromApp(machines)})
+}
+
+// Create issues a machine identity to an automation of the authenticated user.
+// @Summary      Create machine identity
+// @Description  Issues a token that lets a CI/CD pipeline or other automation lease the listed vault items.
+// @Description  The token is returned only in this response and must be stored as a pipeline secret.
+// .
+// @Tags         Account
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body CreateRequest true "Name and scope of the machine identity"
+// @Success      201 {object} Machine "Machine identity created successfully"
+// @Failure      400 {object} response.Error "Bad request - invalid name or scope"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /account/machines [post]
+// .
+func (h *Handler) Create(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	userID, err := extractor.UserID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+
+	// req holds the deserialized JSON request payload for the machine identity.
+	var req CreateRequest
+	if err := extractor.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	scope := make([]machine.Item, 0, len(req.Scope))
+	for _, item := range req.Scope {
+		scope = append(scope, machine.Item(item))
+	}
+
+	m, err := h.s.Create(c, machine.CreateParams{Name: req.Name, Scope: scope, UserID: userID})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, NewMachineFromApp(m))
+}
+
+// Delete revokes a machine identity of the authenticated user.
+// @Summary      Delete machine identity
+// @Description  Revokes the machine identity; its token stops working immediately
+// @Tags         Account
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Machine identity ID" format(uuid)
+// @Success      204 "Machine identity deleted successfully"
+// @Failure      400 {object} response.Error "Bad request - invalid ID format"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
+// @Failure      404 {object} response.Error "Not found - machine identity not found"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /account/machines/{id} [delete]
+// .
+func (h *Handler) Delete(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	userID, err := extractor.UserID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+
+	// req holds the deserialized URI parameters of the request.
+	var req MachineIDRequest
+	if err := extractor.BindURI(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	machineID, err := uuid.Parse(req.ID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	if err := h.s.Delete(c, machine.DeleteParams{ID: machineID, UserID: userID}); err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.Data(http.StatusNoContent, "", nil)
+}
+
+// Lease exchanges a machine token for the values of items in its scope.
+// @Summary      Lease secrets
+// @Description  Exchanges the machine token sent as a bearer token for the values of the requested items.
+// @Description  Omitting items leases the whole scope. The values must be discarded when the lease expires.
+// @Description  The network rules, access windows, geofence and approval workflow of the owner apply.
+// .
+// @Tags         Lease
+// @Accept       json
+// @Produce      json
+// @Param        Authorization header string true "Machine token" example(Bearer avkm_JBSWY3DPEHPK3PXPJBSWY3DPEH)
+// @Param        request body LeaseRequest false "Identifiers of the leased items"
+// @Success      200 {object} Lease "Secrets leased successfully"
+// @Failure      400 {object} response.Error "Bad request - invalid item identifiers"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or revoked machine token"
+// @Failure      403 {object} response.Error "Forbidden - items out of scope or denied by the owner's rules"
+// @Failure      404 {object} response.Error "Not found - leased item not found"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /lease [post]
+// .
+func (h *Handler) Lease(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	token, ok := bearerToken(c)
+	if !ok {
+		return
+	}
+
+	// req holds the deserialized JSON request payload for the lease.
+	var req LeaseRequest
+	if c.Request.ContentLength != 0 {
+		if err := extractor.BindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+			return
+		}
+	}
+
+	ip, _ := clientinfo.IP(c.Request.Context())
+	lease, err := h.s.Lease(c, machine.LeaseParams{IP: ip, Token: token, Items: req.Items})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Code:     errorCode(err),
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, NewLeaseFromApp(lease))
+}
+
+// ExternalSecret serves the values of a vault item to the External Secrets Operator.
+// @Summary      Get external secret
+// @Description  Serves the values of an item in the scope of the machine token sent as a bearer token, in the
+// @Description  format expected by the webhook provider of the External Secrets Operator. Credentials have
+// @Description  the login and password properties, notes the text property. ACME accounts have the directory_url,
+// @Description  email, account_key and dns_provider properties plus one per DNS credential, named like
+// @Description  CF_DNS_API_TOKEN. Without a property all values of the item are returned as one object; with
+// @Description  a property, that value alone as {"value": "..."}.
+// @Description  Every request is audited as a lease, and the rules of the owner apply as to leases.
+// .
+// @Tags         Lease
+// @Accept       json
+// @Produce      json
+// @Param        Authorization header string true "Machine token" example(Bearer avkm_JBSWY3DPEHPK3PXPJBSWY3DPEH)
+// @Param        kind path string true "Item kind" Enums(credentials, notes, acme)
+// @Param        id path string true "Item ID" format(uuid)
+// @Param        property path string false "Item property, such as password, text or account_key"
+// @Success      200 {object} ExternalSecretValue "Secret served successfully"
+// @Failure      400 {object} response.Error "Bad request - invalid kind or ID format"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or revoked machine token"
+// @Failure      403 {object} response.Error "Forbidden - item out of scope or denied by the owner's rules"
+// @Failure      404 {object} response.Error "Not found - item or property not found"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /external-secrets/v1/{kind}/{id}/{property} [get]
+// .
+func (h *Handler) ExternalSecret(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	token, ok := bearerToken(c)
+	if !ok {
+		return
+	}
+
+	// req holds the deserialized URI parameters of the request.
+	var req ExternalSecretRequest
+	if err := extractor.BindURI(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	itemID, err := uuid.Parse(req.ID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	ip, _ := clientinfo.IP(c.Request.Context())
+	lease, err := h.s.Lease(c, machine.LeaseParams{IP: ip, Token: token, Items: []uuid.UUID{itemID}})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Code:     errorCode(err),
+			Messages: msgs,
+		})
+		return
+	}
+
+	values := externalSecretValues(lease, strings.TrimSuffix(req.Kind, "s"))
+	if values == nil {
+		code, msgs := handleError(machine.ErrItemNotFound, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+	if req.Property == "" {
+		c.JSON(http.StatusOK, values)
+		return
+	}
+
+	value, ok := values[req.Property]
+	if !ok {
+		c.JSON(http.StatusNotFound, response.Error{Messages: []string{"Secret property not found"}})
+		return
+	}
+	c.JSON(http.StatusOK, ExternalSecretValue{Value: value})
+}
+
+// ListKV lists the secret paths visible to a machine token.
+// @Summary      List secret paths
+// @Description  Lists the paths of the items in the scope of the machine token sent as a bearer token,
+// @Description  optionally only those equal to or below a prefix. No values are revealed.
+// .
+// @Tags         Lease
+// @Accept       json
+// @Produce      json
+// @Param        Authorization header string true "Machine token" example(Bearer avkm_JBSWY3DPEHPK3PXPJBSWY3DPEH)
+// @Param        prefix query string false "Path prefix" example(prod/db)
+// @Success      200 {object} KVListResponse "Secret paths retrieved successfully"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or revoked machine token"
+// @Failure      403 {object} response.Error "Forbidden - denied by the owner's network rules"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /kv [get]
+// .
+func (h *Handler) ListKV(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	token, ok := bearerToken(c)
+	if !ok {
+		return
+	}
+
+	// req holds the deserialized query parameters of the request.
+	var req KVListRequest
+	if err := extractor.BindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	ip, _ := clientinfo.IP(c.Request.Context())
+	paths, err := h.s.ListPaths(c, machine.ListPathsParams{IP: ip, Token: token, Prefix: req.Prefix})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, NewKVListResponseFromApp(paths))
+}
+
+// GetKV leases the vault item with a path to a machine token.
+// @Summary      Get secret by path
+// @Description  Serves the values of the item its owner assigned the path to, such as prod/db/payments, if it
+// @Description  is in the scope of the machine token sent as a bearer token. Every request is audited as a
+// @Description  lease, and the rules of the owner apply as to leases.
+// .
+// @Tags         Lease
+// @Accept       json
+// @Produce      json
+// @Param        Authorization header string true "Machine token" example(Bearer avkm_JBSWY3DPEHPK3PXPJBSWY3DPEH)
+// @Param        path path string true "Secret path" example(prod/db/payments)
+// @Success      200 {object} KVSecret "Secret leased successfully"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or revoked machine token"
+// @Failure      403 {object} response.Error "Forbidden - item out of scope or denied by the owner's rules"
+// @Failure      404 {object} response.Error "Not found - secret path not found"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /kv/{path} [get]
+// .
+func (h *Handler) GetKV(c *gin.Context) {
+	token, ok := bearerToken(c)
+	if !ok {
+		return
+	}
+
+	path := c.Param("path")
+	ip, _ := clientinfo.IP(c.Request.Context())
+	lease, err := h.s.LeasePath(c, machine.LeasePathParams{IP: ip, Token: token, Path: path})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Code:     errorCode(err),
+			Messages: msgs,
+		})
+		return
+	}
+	if len(lease.Secrets) != 1 {
+		code, msgs := handleError(machine.ErrItemNotFound, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	secret := lease.Secrets[0]
+	c.JSON(http.StatusOK, KVSecret{
+		Path:      strings.Trim(path, "/"),
+		Kind:      secret.Kind,
+		ID:        secret.ID,
+		Data:      externalSecretValues(lease, secret.Kind),
+		LeaseID:   lease.ID,
+		ExpiresAt: lease.ExpiresAt,
+	})
+}
+
+// bearerToken extracts the machine token from the Authorization header.
+// Responds with 401 Unauthorized and returns false when the header carries no bearer token.
+func bearerToken(c *gin.Context) (string, bool) {
+	token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		code, msgs := handleError(machine.ErrInvalidToken, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return "", false
+	}
+	return token, true
+}
+
+// externalSecretValues returns the values of the leased item by property name, or nil when the lease holds no
+// item of the kind.
+func externalSecretValues(lease *machine.Lease, kind string) map[string]string {
+	if lease == nil || len(lease.Secrets) != 1 || lease.Secrets[0].Kind != kind {
+		return nil
+	}
+	secret := lease.Secrets[0]
+	switch kind {
+	case "note":
+		return map[string]string{"text": secret.Text}
+	case "acme":
+		values := map[string]string{
+			"directory_url": secret.DirectoryURL,
+			"email":         secret.Email,
+			"account_key":   secret.AccountKey,
+			"dns_provider":  secret.DNSProvider,
+		}
+		// DNS credential names are upper case, so they never collide with the lower case account properties.
+		for name, value := range secret.DNSCredentials {
+			values[name] = value
+		}
+		return values
+	default:
+		return map[string]string{"login": secret.Login, "password": secret.Password}
+	}
+}
+
+// errorCode returns the machine-readable code of lease rejections that share one with item reveals.
+func errorCode(err error) string {
+	switch {
+	case errors.Is(err, accesspolicy.ErrOutsideAccessWindow):
+		return response.CodeOutsideAccessWindow
+	case errors.Is(err, accesscontrol.ErrRestrictedLocation):
+		return response.CodeRestrictedLocation
+	case errors.Is(err, approval.ErrApprovalRequired):
+		return response.CodeApprovalRequired
+	default:
+		return ""
+	}
+}
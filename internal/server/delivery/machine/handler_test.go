@@ -0,0 +1,647 @@
+package machine
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/accesspolicy"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/machine"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/consts"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/response"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockMachineService implements Service for testing.
+type mockMachineService struct {
+	createFunc func(ctx context.Context, params machine.CreateParams) (*machine.Machine, error)
+	listFunc   func(ctx context.Context, params machine.ListParams) ([]*machine.Machine, error)
+	deleteFunc func(ctx context.Context, params machine.DeleteParams) error
+	leaseFunc  func(ctx context.Context, params machine.LeaseParams) (*machine.Lease, error)
+	pathFunc   func(ctx context.Context, params machine.LeasePathParams) (*machine.Lease, error)
+	pathsFunc  func(ctx context.Context, params machine.ListPathsParams) ([]*machine.Path, error)
+}
+
+func (m *mockMachineService) Create(ctx context.Context, params machine.CreateParams) (*machine.Machine, error) {
+	if m.createFunc != nil {
+		return m.createFunc(ctx, params)
+	}
+	return nil, nil
+}
+
+func (m *mockMachineService) List(ctx context.Context, params machine.ListParams) ([]*machine.Machine, error) {
+	if m.listFunc != nil {
+		return m.listFunc(ctx, params)
+	}
+	return nil, nil
+}
+
+func (m *mockMachineService) Delete(ctx context.Context, params machine.DeleteParams) error {
+	if m.deleteFunc != nil {
+		return m.deleteFunc(ctx, params)
+	}
+	return nil
+}
+
+func (m *mockMachineService) Lease(ctx context.Context, params machine.LeaseParams) (*machine.Lease, error) {
+	if m.leaseFunc != nil {
+		return m.leaseFunc(ctx, params)
+	}
+	return nil, nil
+}
+
+func (m *mockMachineService) LeasePath(ctx context.Context, params machine.LeasePathParams) (*machine.Lease, error) {
+	if m.pathFunc != nil {
+		return m.pathFunc(ctx, params)
+	}
+	return nil, nil
+}
+
+func (m *mockMachineService) ListPaths(ctx context.Context, params machine.ListPathsParams) ([]*machine.Path, error) {
+	if m.pathsFunc != nil {
+		return m.pathsFunc(ctx, params)
+	}
+	return nil, nil
+}
+
+func TestHandler_List(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+
+	tests := []struct {
+		mockService    *mockMachineService
+		name           string
+		setUser        bool
+		expectedStatus int
+	}{
+		{
+			name:    "success",
+			setUser: true,
+			mockService: &mockMachineService{
+				listFunc: func(_ context.Context, params machine.ListParams) ([]*machine.Machine, error) {
+					assert.Equal(t, machine.ListParams{UserID: userID}, params)
+					return []*machine.Machine{{ID: uuid.New(), Name: "ci"}}, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "missing user context",
+			mockService:    &mockMachineService{},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/account/machines", nil)
+			if tt.setUser {
+				c.Set(consts.CtxKeyUserID, userID)
+			}
+
+			NewHandler(tt.mockService).List(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestHandler_Create(t *testing.T) {
+	t.Parallel()
+
+	userID, itemID := uuid.New(), uuid.New()
+
+	tests := []struct {
+		mockService    *mockMachineService
+		name           string
+		body           string
+		expectedStatus int
+	}{
+		{
+			name: "success",
+			body: `{"name":"ci","scope":[{"kind":"credential","id":"` + itemID.String() + `"}]}`,
+			mockService: &mockMachineService{
+				createFunc: func(_ context.Context, params machine.CreateParams) (*machine.Machine, error) {
+					assert.Equal(t, machine.CreateParams{
+						Name:   "ci",
+						Scope:  []machine.Item{{Kind: "credential", ID: itemID}},
+						UserID: userID,
+					}, params)
+					return &machine.Machine{ID: uuid.New(), Name: "ci", Token: "avkm_TOKEN"}, nil
+				},
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "missing scope",
+			body:           `{"name":"ci"}`,
+			mockService:    &mockMachineService{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "malformed item id",
+			body:           `{"name":"ci","scope":[{"kind":"note","id":"db"}]}`,
+			mockService:    &mockMachineService{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "invalid scope",
+			body: `{"name":"ci","scope":[{"kind":"file","id":"` + itemID.String() + `"}]}`,
+			mockService: &mockMachineService{
+				createFunc: func(context.Context, machine.CreateParams) (*machine.Machine, error) {
+					return nil, machine.ErrMachineIncorrectScope
+				},
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/account/machines", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Set(consts.CtxKeyUserID, userID)
+
+			NewHandler(tt.mockService).Create(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusCreated {
+				var got Machine
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+				assert.Equal(t, "avkm_TOKEN", got.Token)
+			}
+		})
+	}
+}
+
+func TestHandler_Delete(t *testing.T) {
+	t.Parallel()
+
+	userID, machineID := uuid.New(), uuid.New()
+
+	tests := []struct {
+		mockService    *mockMachineService
+		name           string
+		id             string
+		expectedStatus int
+	}{
+		{
+			name: "success",
+			id:   machineID.String(),
+			mockService: &mockMachineService{
+				deleteFunc: func(_ context.Context, params machine.DeleteParams) error {
+					assert.Equal(t, machine.DeleteParams{ID: machineID, UserID: userID}, params)
+					return nil
+				},
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "invalid ID",
+			id:             "ci",
+			mockService:    &mockMachineService{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "not found",
+			id:   machineID.String(),
+			mockService: &mockMachineService{
+				deleteFunc: func(context.Context, machine.DeleteParams) error {
+					return machine.ErrMachineNotFound
+				},
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodDelete, "/account/machines/"+tt.id, nil)
+			c.Params = gin.Params{{Key: "id", Value: tt.id}}
+			c.Set(consts.CtxKeyUserID, userID)
+
+			NewHandler(tt.mockService).Delete(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestHandler_Lease(t *testing.T) {
+	t.Parallel()
+
+	itemID := uuid.New()
+	issuedAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		mockService    *mockMachineService
+		name           string
+		auth           string
+		body           string
+		wantCode       string
+		expectedStatus int
+	}{
+		{
+			name: "success",
+			auth: "Bearer avkm_TOKEN",
+			body: `{"items":["` + itemID.String() + `"]}`,
+			mockService: &mockMachineService{
+				leaseFunc: func(_ context.Context, params machine.LeaseParams) (*machine.Lease, error) {
+					assert.Equal(t, "avkm_TOKEN", params.Token)
+					assert.Equal(t, []uuid.UUID{itemID}, params.Items)
+					return &machine.Lease{
+						IssuedAt:  issuedAt,
+						ExpiresAt: issuedAt.Add(5 * time.Minute),
+						Secrets:   []machine.Secret{{ID: itemID, Kind: "credential", Login: "ci", Password: "pw"}},
+					}, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "whole scope without body",
+			auth: "Bearer avkm_TOKEN",
+			mockService: &mockMachineService{
+				leaseFunc: func(_ context.Context, params machine.LeaseParams) (*machine.Lease, error) {
+					assert.Empty(t, params.Items)
+					return &machine.Lease{}, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "missing token",
+			mockService:    &mockMachineService{},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "malformed items",
+			auth:           "Bearer avkm_TOKEN",
+			body:           `{"items":["db"]}`,
+			mockService:    &mockMachineService{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "revoked token",
+			auth: "Bearer avkm_TOKEN",
+			mockService: &mockMachineService{
+				leaseFunc: func(context.Context, machine.LeaseParams) (*machine.Lease, error) {
+					return nil, machine.ErrInvalidToken
+				},
+			},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name: "out of scope",
+			auth: "Bearer avkm_TOKEN",
+			mockService: &mockMachineService{
+				leaseFunc: func(context.Context, machine.LeaseParams) (*machine.Lease, error) {
+					return nil, machine.ErrItemOutOfScope
+				},
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name: "outside access window",
+			auth: "Bearer avkm_TOKEN",
+			mockService: &mockMachineService{
+				leaseFunc: func(context.Context, machine.LeaseParams) (*machine.Lease, error) {
+					return nil, accesspolicy.ErrOutsideAccessWindow
+				},
+			},
+			wantCode:       response.CodeOutsideAccessWindow,
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/api/lease", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			if tt.auth != "" {
+				c.Request.Header.Set("Authorization", tt.auth)
+			}
+
+			NewHandler(tt.mockService).Lease(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus != http.StatusOK {
+				var got response.Error
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+				assert.Equal(t, tt.wantCode, got.Code)
+			}
+		})
+	}
+}
+
+func TestHandler_ExternalSecret(t *testing.T) {
+	t.Parallel()
+
+	itemID := uuid.New()
+	credentialLease := func(_ context.Context, params machine.LeaseParams) (*machine.Lease, error) {
+		assert.Equal(t, "avkm_TOKEN", params.Token)
+		assert.Equal(t, []uuid.UUID{itemID}, params.Items)
+		return &machine.Lease{
+			Secrets: []machine.Secret{{ID: itemID, Kind: "credential", Login: "ci", Password: "pw"}},
+		}, nil
+	}
+	acmeLease := func(context.Context, machine.LeaseParams) (*machine.Lease, error) {
+		return &machine.Lease{
+			Secrets: []machine.Secret{{
+				ID:             itemID,
+				Kind:           "acme",
+				DirectoryURL:   "https://acme.example.com/directory",
+				AccountKey:     "key",
+				DNSProvider:    "cloudflare",
+				DNSCredentials: map[string]string{"CF_DNS_API_TOKEN": "token"},
+			}},
+		}, nil
+	}
+
+	tests := []struct {
+		mockService    *mockMachineService
+		name           string
+		auth           string
+		kind           string
+		property       string
+		wantBody       string
+		expectedStatus int
+	}{
+		{
+			name:           "all values",
+			auth:           "Bearer avkm_TOKEN",
+			kind:           "credentials",
+			mockService:    &mockMachineService{leaseFunc: credentialLease},
+			wantBody:       `{"login":"ci","password":"pw"}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "single property",
+			auth:           "Bearer avkm_TOKEN",
+			kind:           "credentials",
+			property:       "password",
+			mockService:    &mockMachineService{leaseFunc: credentialLease},
+			wantBody:       `{"value":"pw"}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:        "ACME account values",
+			auth:        "Bearer avkm_TOKEN",
+			kind:        "acme",
+			mockService: &mockMachineService{leaseFunc: acmeLease},
+			wantBody: `{"CF_DNS_API_TOKEN":"token","account_key":"key","directory_url":` +
+				`"https://acme.example.com/directory","dns_provider":"cloudflare","email":""}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "ACME DNS credential",
+			auth:           "Bearer avkm_TOKEN",
+			kind:           "acme",
+			property:       "CF_DNS_API_TOKEN",
+			mockService:    &mockMachineService{leaseFunc: acmeLease},
+			wantBody:       `{"value":"token"}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "unknown property",
+			auth:           "Bearer avkm_TOKEN",
+			kind:           "credentials",
+			property:       "text",
+			mockService:    &mockMachineService{leaseFunc: credentialLease},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "kind mismatch",
+			auth:           "Bearer avkm_TOKEN",
+			kind:           "notes",
+			mockService:    &mockMachineService{leaseFunc: credentialLease},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "unknown kind",
+			auth:           "Bearer avkm_TOKEN",
+			kind:           "cards",
+			mockService:    &mockMachineService{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "missing token",
+			kind:           "credentials",
+			mockService:    &mockMachineService{},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name: "out of scope",
+			auth: "Bearer avkm_TOKEN",
+			kind: "credentials",
+			mockService: &mockMachineService{
+				leaseFunc: func(context.Context, machine.LeaseParams) (*machine.Lease, error) {
+					return nil, machine.ErrItemOutOfScope
+				},
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/api/external-secrets/v1/"+tt.kind+"/"+itemID.String(), nil)
+			if tt.auth != "" {
+				c.Request.Header.Set("Authorization", tt.auth)
+			}
+			c.Params = gin.Params{{Key: "kind", Value: tt.kind}, {Key: "id", Value: itemID.String()}}
+			if tt.property != "" {
+				c.Params = append(c.Params, gin.Param{Key: "property", Value: tt.property})
+			}
+
+			NewHandler(tt.mockService).ExternalSecret(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.wantBody != "" {
+				assert.JSONEq(t, tt.wantBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandler_ListKV(t *testing.T) {
+	t.Parallel()
+
+	itemID := uuid.New()
+
+	tests := []struct {
+		mockService    *mockMachineService
+		name           string
+		auth           string
+		query          string
+		wantBody       string
+		expectedStatus int
+	}{
+		{
+			name:  "prefix",
+			auth:  "Bearer avkm_TOKEN",
+			query: "?prefix=prod/db",
+			mockService: &mockMachineService{
+				pathsFunc: func(_ context.Context, params machine.ListPathsParams) ([]*machine.Path, error) {
+					assert.Equal(t, "avkm_TOKEN", params.Token)
+					assert.Equal(t, "prod/db", params.Prefix)
+					return []*machine.Path{{Path: "prod/db/payments", Kind: "credential", ID: itemID}}, nil
+				},
+			},
+			wantBody: `{"keys":[{"path":"prod/db/payments","kind":"credential","id":"` +
+				itemID.String() + `"}]}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "missing token",
+			mockService:    &mockMachineService{},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name: "invalid token",
+			auth: "Bearer avkm_TOKEN",
+			mockService: &mockMachineService{
+				pathsFunc: func(context.Context, machine.ListPathsParams) ([]*machine.Path, error) {
+					return nil, machine.ErrInvalidToken
+				},
+			},
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/api/kv"+tt.query, nil)
+			if tt.auth != "" {
+				c.Request.Header.Set("Authorization", tt.auth)
+			}
+
+			NewHandler(tt.mockService).ListKV(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.wantBody != "" {
+				assert.JSONEq(t, tt.wantBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandler_GetKV(t *testing.T) {
+	t.Parallel()
+
+	itemID, leaseID := uuid.New(), uuid.New()
+	expiresAt := time.Date(2026, 10, 1, 12, 5, 0, 0, time.UTC)
+
+	tests := []struct {
+		mockService    *mockMachineService
+		name           string
+		auth           string
+		wantBody       string
+		expectedStatus int
+	}{
+		{
+			name: "success",
+			auth: "Bearer avkm_TOKEN",
+			mockService: &mockMachineService{
+				pathFunc: func(_ context.Context, params machine.LeasePathParams) (*machine.Lease, error) {
+					assert.Equal(t, "avkm_TOKEN", params.Token)
+					assert.Equal(t, "/prod/db/payments", params.Path)
+					return &machine.Lease{
+						ID:        leaseID,
+						ExpiresAt: expiresAt,
+						Secrets:   []machine.Secret{{ID: itemID, Kind: "credential", Login: "ci", Password: "pw"}},
+					}, nil
+				},
+			},
+			wantBody: `{"path":"prod/db/payments","kind":"credential","id":"` + itemID.String() +
+				`","lease_id":"` + leaseID.String() + `","expires_at":"2026-10-01T12:05:00Z",` +
+				`"data":{"login":"ci","password":"pw"}}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "missing token",
+			mockService:    &mockMachineService{},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name: "unknown path",
+			auth: "Bearer avkm_TOKEN",
+			mockService: &mockMachineService{
+				pathFunc: func(context.Context, machine.LeasePathParams) (*machine.Lease, error) {
+					return nil, machine.ErrPathNotFound
+				},
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name: "outside access window",
+			auth: "Bearer avkm_TOKEN",
+			mockService: &mockMachineService{
+				pathFunc: func(context.Context, machine.LeasePathParams) (*machine.Lease, error) {
+					return nil, accesspolicy.ErrOutsideAccessWindow
+				},
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/api/kv/prod/db/payments", nil)
+			if tt.auth != "" {
+				c.Request.Header.Set("Authorization", tt.auth)
+			}
+			c.Params = gin.Params{{Key: "path", Value: "/prod/db/payments"}}
+
+			NewHandler(tt.mockService).GetKV(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.wantBody != "" {
+				assert.JSONEq(t, tt.wantBody, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package machine
+
+import "github.com/gin-gonic/gin"
+
+// RegisterRoutes registers machine identity management routes with the provided router group.
+// Creates /machines and /machines/:id endpoints with the specified handler.
+func RegisterRoutes(r *gin.RouterGroup, h *Handler) {
+	machinesGroup := r.Group("/machines")
+	machinesGroup.GET("", h.List)
+	machinesGroup.POST("", h.Create)
+	machinesGroup.DELETE("/:id", h.Delete)
+}
+
+// RegisterLeaseRoutes registers the lease route with the provided router group.
+// Creates the /lease endpoint, authenticated by machine tokens instead of user sessions.
+func RegisterLeaseRoutes(r *gin.RouterGroup, h *Handler) {
+	r.POST("/lease", h.Lease)
+}
+
+// RegisterExternalSecretsRoutes registers the External Secrets Operator compatible routes with the provided
+// router group. Creates /external-secrets/v1/:kind/:id and /external-secrets/v1/:kind/:id/:property endpoints,
+// authenticated by machine tokens instead of user sessions.
+func RegisterExternalSecretsRoutes(r *gin.RouterGroup, h *Handler) {
+	secretsGroup := r.Group("/external-secrets/v1")
+	secretsGroup.GET("/:kind/:id", h.ExternalSecret)
+	secretsGroup.GET("/:kind/:id/:property", h.ExternalSecret)
+}
+
+// RegisterKVRoutes registers the secret path routes with the provided router group.
+// Creates /kv and /kv/*path endpoints, authenticated by machine tokens instead of user sessions.
+func RegisterKVRoutes(r *gin.RouterGroup, h *Handler) {
+	r.GET("/kv", h.ListKV)
+	r.GET("/kv/*path", h.GetKV)
+}
@@ -0,0 +1,71 @@
+package machine
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterRoutes(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	RegisterRoutes(router.Group("/account"), NewHandler(&mockMachineService{}))
+
+	got := make(map[string]string)
+	for _, r := range router.Routes() {
+		got[r.Method+" "+r.Path] = r.Handler
+	}
+	assert.Len(t, got, 3)
+	assert.Contains(t, got, http.MethodGet+" /account/machines")
+	assert.Contains(t, got, http.MethodPost+" /account/machines")
+	assert.Contains(t, got, http.MethodDelete+" /account/machines/:id")
+}
+
+func TestRegisterLeaseRoutes(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	RegisterLeaseRoutes(router.Group("/api"), NewHandler(&mockMachineService{}))
+
+	routes := router.Routes()
+	assert.Len(t, routes, 1)
+	assert.Equal(t, http.MethodPost, routes[0].Method)
+	assert.Equal(t, "/api/lease", routes[0].Path)
+}
+
+func TestRegisterExternalSecretsRoutes(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	RegisterExternalSecretsRoutes(router.Group("/api"), NewHandler(&mockMachineService{}))
+
+	got := make(map[string]string)
+	for _, r := range router.Routes() {
+		got[r.Method+" "+r.Path] = r.Handler
+	}
+	assert.Len(t, got, 2)
+	assert.Contains(t, got, http.MethodGet+" /api/external-secrets/v1/:kind/:id")
+	assert.Contains(t, got, http.MethodGet+" /api/external-secrets/v1/:kind/:id/:property")
+}
+
+func TestRegisterKVRoutes(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	RegisterKVRoutes(router.Group("/api"), NewHandler(&mockMachineService{}))
+
+	got := make(map[string]string)
+	for _, r := range router.Routes() {
+		got[r.Method+" "+r.Path] = r.Handler
+	}
+	assert.Len(t, got, 2)
+	assert.Contains(t, got, http.MethodGet+" /api/kv")
+	assert.Contains(t, got, http.MethodGet+" /api/kv/*path")
+}
@@ -0,0 +1,5 @@
+// Package metrics provides operational metrics endpoints for the AegisVaultKeeper server.
+//
+// This package exposes in-process counters such as integrity verification results
+// so that external monitoring systems can scrape them.
+package metrics
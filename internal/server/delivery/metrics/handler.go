@@ -0,0 +1,37 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Snapshotter provides a point-in-time copy of operational metrics.
+type Snapshotter interface {
+	// Snapshot returns current metric values by name.
+	Snapshot() map[string]int64
+}
+
+// Handler handles HTTP requests for operational metrics.
+type Handler struct {
+	// s provides metric values.
+	s Snapshotter
+}
+
+// NewHandler creates a new metrics handler with the provided snapshotter.
+func NewHandler(s Snapshotter) *Handler {
+	return &Handler{s: s}
+}
+
+// Metrics returns current operational metric values.
+// @Summary      Get operational metrics
+// @Description  Returns current values of server counters such as integrity verification results
+// @Tags         System
+// @Accept       json
+// @Produce      json
+// @Success      200 {object} map[string]int64 "Metric values by name"
+// @Router       /metrics [get]
+// .
+func (h *Handler) Metrics(c *gin.Context) {
+	c.JSON(http.StatusOK, h.s.Snapshot())
+}
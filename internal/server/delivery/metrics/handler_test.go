@@ -0,0 +1,62 @@
+package metrics
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockSnapshotter implements Snapshotter for testing.
+type mockSnapshotter struct {
+	values map[string]int64
+}
+
+func (m *mockSnapshotter) Snapshot() map[string]int64 {
+	return m.values
+}
+
+func TestHandler_Metrics(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		values map[string]int64
+		want   map[string]int64
+		name   string
+	}{
+		{
+			name:   "success/returns_metrics",
+			values: map[string]int64{"integrity_rows_tampered_total": 2},
+			want:   map[string]int64{"integrity_rows_tampered_total": 2},
+		},
+		{
+			name:   "success/empty_metrics",
+			values: map[string]int64{},
+			want:   map[string]int64{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			RegisterRoutes(router.Group(""), NewHandler(&mockSnapshotter{values: tt.values}))
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, http.StatusOK, w.Code)
+
+			var got map[string]int64
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
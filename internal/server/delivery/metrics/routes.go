@@ -0,0 +1,8 @@
+package metrics
+
+import "github.com/gin-gonic/gin"
+
+// RegisterRoutes configures metrics endpoint in the router group.
+func RegisterRoutes(r *gin.RouterGroup, h *Handler) {
+	r.GET("/metrics", h.Metrics)
+}
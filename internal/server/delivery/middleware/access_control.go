@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/accesscontrol"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/clientinfo"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/consts"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/response"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// AccessChecker defines the interface for network access control services.
+type AccessChecker interface {
+	// Check verifies that the client address is permitted by the access rules.
+	Check(ctx context.Context, params accesscontrol.CheckParams) error
+}
+
+// AccessControl creates middleware that rejects requests from network locations blocked by access rules.
+// Before authentication it enforces global rules; after AuthWithJWT it enforces the rules of the user.
+// A nil checker disables the middleware.
+func AccessControl(checker AccessChecker) gin.HandlerFunc {
+	if checker == nil {
+		return func(c *gin.Context) { c.Next() }
+	}
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		ip, _ := clientinfo.IP(ctx)
+		value, _ := c.Get(consts.CtxKeyUserID)
+		userID, _ := value.(uuid.UUID)
+
+		if err := checker.Check(ctx, accesscontrol.CheckParams{IP: ip, UserID: userID}); err != nil {
+			code, msgs := handleError(err, c)
+			c.JSON(code, response.Error{
+				Messages: msgs,
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
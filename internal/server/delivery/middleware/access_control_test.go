@@ -0,0 +1,99 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/accesscontrol"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/consts"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockAccessChecker implements AccessChecker for testing.
+type mockAccessChecker struct {
+	err    error
+	params accesscontrol.CheckParams
+}
+
+func (m *mockAccessChecker) Check(_ context.Context, params accesscontrol.CheckParams) error {
+	m.params = params
+	return m.err
+}
+
+func TestAccessControl(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+	userID := uuid.New()
+
+	tests := []struct {
+		checkErr   error
+		name       string
+		userID     uuid.UUID
+		wantStatus int
+	}{
+		{
+			name:       "permitted anonymous request",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "permitted user request",
+			userID:     userID,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "denied request",
+			checkErr:   accesscontrol.ErrAccessDenied,
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "checker failure",
+			checkErr:   errors.Join(accesscontrol.ErrAccessControlTechError, errors.New("db down")),
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			checker := &mockAccessChecker{err: tt.checkErr}
+			router := gin.New()
+			router.Use(RealIP(nil))
+			if tt.userID != uuid.Nil {
+				router.Use(func(c *gin.Context) { c.Set(consts.CtxKeyUserID, tt.userID) })
+			}
+			router.Use(AccessControl(checker))
+			router.GET("/test", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			req.RemoteAddr = "203.0.113.7:4321"
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.Equal(t, netip.MustParseAddr("203.0.113.7"), checker.params.IP)
+			assert.Equal(t, tt.userID, checker.params.UserID)
+		})
+	}
+}
+
+func TestAccessControl_NilChecker(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(AccessControl(nil))
+	router.GET("/test", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/test", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/accesspolicy"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/consts"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/response"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// RevealChecker defines the interface for time-based access policy services.
+type RevealChecker interface {
+	// CheckReveal verifies that the access windows of the user are open at the moment.
+	CheckReveal(ctx context.Context, params accesspolicy.CheckParams) error
+}
+
+// RevealWindow creates middleware that rejects item reveals outside the access windows of the user
+// with 403 Forbidden and the outside_access_window error code. Only GET, HEAD and WebDAV PROPFIND requests
+// reveal items; changes are accepted at any time. Must run after AuthWithJWT. A nil checker disables
+// the middleware.
+func RevealWindow(checker RevealChecker) gin.HandlerFunc {
+	if checker == nil {
+		return func(c *gin.Context) { c.Next() }
+	}
+	return func(c *gin.Context) {
+		if !isRevealMethod(c.Request.Method) {
+			c.Next()
+			return
+		}
+
+		value, _ := c.Get(consts.CtxKeyUserID)
+		userID, _ := value.(uuid.UUID)
+
+		err := checker.CheckReveal(c.Request.Context(), accesspolicy.CheckParams{At: time.Now(), UserID: userID})
+		if err != nil {
+			code, msgs := handleError(err, c)
+			resp := response.Error{Messages: msgs}
+			if errors.Is(err, accesspolicy.ErrOutsideAccessWindow) {
+				resp.Code = response.CodeOutsideAccessWindow
+			}
+			c.JSON(code, resp)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// isRevealMethod reports whether the HTTP method reads items: GET and HEAD, and PROPFIND listing
+// folders of the WebDAV drive.
+func isRevealMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, methodPropfind:
+		return true
+	default:
+		return false
+	}
+}
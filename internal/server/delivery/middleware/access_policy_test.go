@@ -0,0 +1,131 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/accesspolicy"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/consts"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/response"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockRevealChecker returns a fixed error and records the checked parameters.
+type mockRevealChecker struct {
+	err    error
+	params []accesspolicy.CheckParams
+}
+
+func (m *mockRevealChecker) CheckReveal(_ context.Context, params accesspolicy.CheckParams) error {
+	m.params = append(m.params, params)
+	return m.err
+}
+
+func TestRevealWindow(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+	userID := uuid.New()
+
+	tests := []struct {
+		checker    *mockRevealChecker
+		name       string
+		method     string
+		wantCode   string
+		wantStatus int
+		wantChecks int
+	}{
+		{
+			name:       "inside window",
+			checker:    &mockRevealChecker{},
+			method:     http.MethodGet,
+			wantStatus: http.StatusOK,
+			wantChecks: 1,
+		},
+		{
+			name:       "reveal outside window",
+			checker:    &mockRevealChecker{err: accesspolicy.ErrOutsideAccessWindow},
+			method:     http.MethodGet,
+			wantStatus: http.StatusForbidden,
+			wantCode:   response.CodeOutsideAccessWindow,
+			wantChecks: 1,
+		},
+		{
+			name:       "webdav listing outside window",
+			checker:    &mockRevealChecker{err: accesspolicy.ErrOutsideAccessWindow},
+			method:     "PROPFIND",
+			wantStatus: http.StatusForbidden,
+			wantCode:   response.CodeOutsideAccessWindow,
+			wantChecks: 1,
+		},
+		{
+			name:       "change outside window",
+			checker:    &mockRevealChecker{err: accesspolicy.ErrOutsideAccessWindow},
+			method:     http.MethodPost,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "checker failure",
+			checker: &mockRevealChecker{
+				err: errors.Join(accesspolicy.ErrAccessPolicyTechError, errors.New("db down")),
+			},
+			method:     http.MethodGet,
+			wantStatus: http.StatusInternalServerError,
+			wantChecks: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			router := gin.New()
+			router.Use(func(c *gin.Context) {
+				c.Set(consts.CtxKeyUserID, userID)
+				c.Next()
+			})
+			router.Use(RevealWindow(tt.checker))
+			router.Handle(tt.method, "/items/notes", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+			w := httptest.NewRecorder()
+			before := time.Now()
+			router.ServeHTTP(w, httptest.NewRequest(tt.method, "/items/notes", nil))
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			require.Len(t, tt.checker.params, tt.wantChecks)
+			if tt.wantChecks != 0 {
+				assert.Equal(t, userID, tt.checker.params[0].UserID)
+				assert.False(t, tt.checker.params[0].At.Before(before))
+			}
+			if tt.wantStatus == http.StatusOK {
+				return
+			}
+			var got response.Error
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+			assert.Equal(t, tt.wantCode, got.Code)
+			assert.NotEmpty(t, got.Messages)
+		})
+	}
+}
+
+func TestRevealWindow_Disabled(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RevealWindow(nil))
+	router.GET("/items/notes", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/items/notes", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/consttime"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/consts"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/response"
+	"github.com/gin-gonic/gin"
+)
+
+// SharedAdminOperator names the operator authenticated by the shared admin token.
+const SharedAdminOperator = "admin"
+
+// adminCredential pairs the digest of an admin token with the operator it authenticates.
+type adminCredential struct {
+	// operator names the operator the token belongs to.
+	operator string
+	// digest contains the digest of the token.
+	digest [32]byte
+}
+
+// AdminToken creates middleware that authorizes administrative requests by the static operator token
+// sent in the X-Admin-Token header. Besides the shared token, which authenticates SharedAdminOperator,
+// every operator may have a token of their own, mapped from the operator name; the authenticated operator
+// is stored in the context. An empty shared token disables the admin API entirely.
+func AdminToken(token string, operators map[string]string) gin.HandlerFunc {
+	if token == "" {
+		return func(c *gin.Context) {
+			c.JSON(http.StatusNotFound, response.Error{Messages: []string{"Admin API is disabled"}})
+			c.Abort()
+		}
+	}
+
+	credentials := []adminCredential{{operator: SharedAdminOperator, digest: consttime.Digest(token)}}
+	for operator, t := range operators {
+		credentials = append(credentials, adminCredential{operator: operator, digest: consttime.Digest(t)})
+	}
+	return func(c *gin.Context) {
+		got := consttime.Digest(c.GetHeader(consts.HeaderXAdminToken))
+		// operator names the matching operator; every token is compared, so timing does not tell which matched.
+		var operator string
+		for _, cred := range credentials {
+			if consttime.Equal(got[:], cred.digest[:]) {
+				operator = cred.operator
+			}
+		}
+		if operator == "" {
+			c.JSON(http.StatusUnauthorized, response.Error{Messages: []string{"Invalid admin token"}})
+			c.Abort()
+			return
+		}
+		c.Set(consts.CtxKeyAdminOperator, operator)
+		c.Next()
+	}
+}
@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/consts"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdminToken(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+	const token = "0123456789abcdef0123456789abcdef"
+	const aliceToken = "alice-0123456789abcdef0123456789"
+	operators := map[string]string{"alice": aliceToken}
+
+	tests := []struct {
+		operators    map[string]string
+		name         string
+		configured   string
+		header       string
+		wantOperator string
+		wantStatus   int
+	}{
+		{
+			name:         "valid token",
+			configured:   token,
+			header:       token,
+			wantOperator: SharedAdminOperator,
+			wantStatus:   http.StatusOK,
+		},
+		{
+			name:         "operator token",
+			operators:    operators,
+			configured:   token,
+			header:       aliceToken,
+			wantOperator: "alice",
+			wantStatus:   http.StatusOK,
+		},
+		{
+			name:         "shared token with operators",
+			operators:    operators,
+			configured:   token,
+			header:       token,
+			wantOperator: SharedAdminOperator,
+			wantStatus:   http.StatusOK,
+		},
+		{name: "wrong token", configured: token, header: token + "x", wantStatus: http.StatusUnauthorized},
+		{name: "missing token", configured: token, wantStatus: http.StatusUnauthorized},
+		{name: "admin api disabled", header: "anything", wantStatus: http.StatusNotFound},
+		{name: "admin api disabled without header", wantStatus: http.StatusNotFound},
+		{
+			name:       "admin api disabled with operators",
+			operators:  operators,
+			header:     aliceToken,
+			wantStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			router := gin.New()
+			router.Use(AdminToken(tt.configured, tt.operators))
+			router.GET("/test", func(c *gin.Context) {
+				assert.Equal(t, tt.wantOperator, c.GetString(consts.CtxKeyAdminOperator))
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			if tt.header != "" {
+				req.Header.Set(consts.HeaderXAdminToken, tt.header)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+		})
+	}
+}
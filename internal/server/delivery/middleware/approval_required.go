@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/approval"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/consts"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/response"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ApprovalChecker defines the interface for the approval workflow of items tagged to require approval.
+type ApprovalChecker interface {
+	// CheckApproval verifies that an approved access request is in effect for the items of the user.
+	CheckApproval(ctx context.Context, params approval.CheckParams) error
+}
+
+// ApprovalRequired creates middleware that rejects access to items tagged to require approval without an
+// approved access request in effect with 403 Forbidden and the approval_required error code. Requests naming
+// an item by id are checked against that item; other reading requests, such as listings and sync pulls,
+// are checked against every such item of the user. Must run after AuthWithJWT. A nil checker disables the
+// middleware.
+func ApprovalRequired(checker ApprovalChecker) gin.HandlerFunc {
+	if checker == nil {
+		return func(c *gin.Context) { c.Next() }
+	}
+	return func(c *gin.Context) {
+		var itemID uuid.UUID
+		if raw := c.Param("id"); raw != "" {
+			id, err := uuid.Parse(raw)
+			if err != nil {
+				// Handlers reject malformed ids themselves.
+				c.Next()
+				return
+			}
+			itemID = id
+		} else if !isRevealMethod(c.Request.Method) {
+			c.Next()
+			return
+		}
+
+		value, _ := c.Get(consts.CtxKeyUserID)
+		userID, _ := value.(uuid.UUID)
+
+		err := checker.CheckApproval(c.Request.Context(), approval.CheckParams{UserID: userID, ItemID: itemID})
+		if err != nil {
+			code, msgs := handleError(err, c)
+			resp := response.Error{Messages: msgs}
+			if errors.Is(err, approval.ErrApprovalRequired) {
+				resp.Code = response.CodeApprovalRequired
+			}
+			c.JSON(code, resp)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,156 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/approval"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/consts"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/response"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockApprovalChecker returns a fixed error and records the checked parameters.
+type mockApprovalChecker struct {
+	err    error
+	params []approval.CheckParams
+}
+
+func (m *mockApprovalChecker) CheckApproval(_ context.Context, params approval.CheckParams) error {
+	m.params = append(m.params, params)
+	return m.err
+}
+
+func TestApprovalRequired(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+	userID, itemID := uuid.New(), uuid.New()
+
+	tests := []struct {
+		checker    *mockApprovalChecker
+		name       string
+		method     string
+		path       string
+		wantCode   string
+		wantItemID uuid.UUID
+		wantStatus int
+		wantChecks int
+	}{
+		{
+			name:       "approved item",
+			checker:    &mockApprovalChecker{},
+			method:     http.MethodGet,
+			path:       "/items/notes/" + itemID.String(),
+			wantItemID: itemID,
+			wantStatus: http.StatusOK,
+			wantChecks: 1,
+		},
+		{
+			name:       "item without approval",
+			checker:    &mockApprovalChecker{err: approval.ErrApprovalRequired},
+			method:     http.MethodGet,
+			path:       "/items/notes/" + itemID.String(),
+			wantItemID: itemID,
+			wantStatus: http.StatusForbidden,
+			wantCode:   response.CodeApprovalRequired,
+			wantChecks: 1,
+		},
+		{
+			name:       "item change without approval",
+			checker:    &mockApprovalChecker{err: approval.ErrApprovalRequired},
+			method:     http.MethodPut,
+			path:       "/items/notes/" + itemID.String(),
+			wantItemID: itemID,
+			wantStatus: http.StatusForbidden,
+			wantCode:   response.CodeApprovalRequired,
+			wantChecks: 1,
+		},
+		{
+			name:       "listing without approval",
+			checker:    &mockApprovalChecker{err: approval.ErrApprovalRequired},
+			method:     http.MethodGet,
+			path:       "/items/notes",
+			wantStatus: http.StatusForbidden,
+			wantCode:   response.CodeApprovalRequired,
+			wantChecks: 1,
+		},
+		{
+			name:       "creation without approval",
+			checker:    &mockApprovalChecker{err: approval.ErrApprovalRequired},
+			method:     http.MethodPost,
+			path:       "/items/notes",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "malformed id",
+			checker:    &mockApprovalChecker{err: approval.ErrApprovalRequired},
+			method:     http.MethodGet,
+			path:       "/items/notes/not-a-uuid",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "checker failure",
+			checker: &mockApprovalChecker{
+				err: errors.Join(approval.ErrApprovalTechError, errors.New("db down")),
+			},
+			method:     http.MethodGet,
+			path:       "/items/notes",
+			wantStatus: http.StatusInternalServerError,
+			wantChecks: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			router := gin.New()
+			router.Use(func(c *gin.Context) {
+				c.Set(consts.CtxKeyUserID, userID)
+				c.Next()
+			})
+			router.Use(ApprovalRequired(tt.checker))
+			ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+			router.Handle(tt.method, "/items/notes", ok)
+			router.Handle(tt.method, "/items/notes/:id", ok)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			require.Len(t, tt.checker.params, tt.wantChecks)
+			if tt.wantChecks != 0 {
+				assert.Equal(t, approval.CheckParams{UserID: userID, ItemID: tt.wantItemID}, tt.checker.params[0])
+			}
+			if tt.wantStatus == http.StatusOK {
+				return
+			}
+			var got response.Error
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+			assert.Equal(t, tt.wantCode, got.Code)
+			assert.NotEmpty(t, got.Messages)
+		})
+	}
+}
+
+func TestApprovalRequired_Disabled(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(ApprovalRequired(nil))
+	router.GET("/items/notes", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/items/notes", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
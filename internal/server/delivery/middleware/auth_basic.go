@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/consts"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/response"
+	"github.com/gin-gonic/gin"
+)
+
+// AuthWithJWTOrBasic creates middleware that validates JWT tokens sent either as a Bearer token or as the
+// password of HTTP Basic credentials, for clients such as mounted WebDAV drives that only support Basic
+// authentication. The Basic user name is ignored. Requests without valid credentials are answered with
+// 401 Unauthorized and a Basic challenge for the realm, so such clients prompt for the token.
+func AuthWithJWTOrBasic(service AuthWithJWTService, realm string) gin.HandlerFunc {
+	challenge := "Basic realm=" + strconv.Quote(realm) + `, charset="UTF-8"`
+	return func(c *gin.Context) {
+		token := strings.TrimPrefix(c.Request.Header.Get("Authorization"), "Bearer ")
+		if _, password, ok := c.Request.BasicAuth(); ok {
+			token = password
+		}
+		if token == "" {
+			c.Header("WWW-Authenticate", challenge)
+			c.Status(http.StatusUnauthorized)
+			c.Abort()
+			return
+		}
+
+		userID, err := service.ValidateToken(c.Request.Context(), token)
+		if err != nil {
+			code, msgs := handleError(err, c)
+			if code == http.StatusUnauthorized {
+				c.Header("WWW-Authenticate", challenge)
+			}
+			c.JSON(code, response.Error{
+				Messages: msgs,
+			})
+			c.Abort()
+			return
+		}
+
+		c.Set(consts.CtxKeyUserID, userID)
+
+		c.Next()
+	}
+}
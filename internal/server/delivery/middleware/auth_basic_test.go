@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	app "github.com/gdyunin/aegis-vault-keeper/internal/server/application/auth"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/consts"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthWithJWTOrBasic(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	service := &MockAuthWithJWTService{
+		ValidateTokenFunc: func(_ context.Context, token string) (uuid.UUID, error) {
+			if token == "valid_token" {
+				return userID, nil
+			}
+			return uuid.Nil, app.ErrAuthInvalidAccessToken
+		},
+	}
+
+	tests := []struct {
+		setup         func(r *http.Request)
+		name          string
+		wantChallenge bool
+		wantStatus    int
+	}{
+		{
+			name:       "bearer_token",
+			setup:      func(r *http.Request) { r.Header.Set("Authorization", "Bearer valid_token") },
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "token_as_basic_password",
+			setup:      func(r *http.Request) { r.SetBasicAuth("alice", "valid_token") },
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:          "missing_credentials",
+			setup:         func(r *http.Request) {},
+			wantStatus:    http.StatusUnauthorized,
+			wantChallenge: true,
+		},
+		{
+			name:          "empty_basic_password",
+			setup:         func(r *http.Request) { r.SetBasicAuth("alice", "") },
+			wantStatus:    http.StatusUnauthorized,
+			wantChallenge: true,
+		},
+		{
+			name:          "invalid_basic_password",
+			setup:         func(r *http.Request) { r.SetBasicAuth("alice", "password") },
+			wantStatus:    http.StatusUnauthorized,
+			wantChallenge: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.Use(AuthWithJWTOrBasic(service, "Vault"))
+			router.GET("/test", func(c *gin.Context) {
+				got, _ := c.Get(consts.CtxKeyUserID)
+				assert.Equal(t, userID, got)
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			tt.setup(req)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantChallenge {
+				assert.Equal(t, `Basic realm="Vault", charset="UTF-8"`, w.Header().Get("WWW-Authenticate"))
+			} else {
+				assert.Empty(t, w.Header().Get("WWW-Authenticate"))
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"context"
 	"net/http"
 	"strings"
 
@@ -13,7 +14,7 @@ import (
 // AuthWithJWTService defines the interface for JWT token validation services.
 type AuthWithJWTService interface {
 	// ValidateToken validates the provided JWT token and returns the user ID.
-	ValidateToken(token string) (uuid.UUID, error)
+	ValidateToken(ctx context.Context, token string) (uuid.UUID, error)
 }
 
 // AuthWithJWT creates middleware that validates JWT tokens in the Authorization header.
@@ -28,7 +29,7 @@ func AuthWithJWT(service AuthWithJWTService) gin.HandlerFunc {
 		}
 		rawToken := strings.TrimPrefix(accessToken, "Bearer ")
 
-		userID, err := service.ValidateToken(rawToken)
+		userID, err := service.ValidateToken(c.Request.Context(), rawToken)
 		if err != nil {
 			code, msgs := handleError(err, c)
 			c.JSON(code, response.Error{
@@ -43,3 +44,23 @@ func AuthWithJWT(service AuthWithJWTService) gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// OptionalAuthWithJWT creates middleware that identifies the user of requests carrying a valid JWT token in the
+// Authorization header and lets requests without one, or with an invalid one, through unauthenticated.
+// Handlers tell the two apart by the user ID in context.
+func OptionalAuthWithJWT(service AuthWithJWTService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		accessToken := c.Request.Header.Get("Authorization")
+		if accessToken == "" {
+			c.Next()
+			return
+		}
+		rawToken := strings.TrimPrefix(accessToken, "Bearer ")
+
+		if userID, err := service.ValidateToken(c.Request.Context(), rawToken); err == nil {
+			c.Set(consts.CtxKeyUserID, userID)
+		}
+
+		c.Next()
+	}
+}
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"context"
 	"errors"
 	"net/http"
 	"net/http/httptest"
@@ -15,12 +16,12 @@ import (
 
 // MockAuthWithJWTService implements AuthWithJWTService interface for testing.
 type MockAuthWithJWTService struct {
-	ValidateTokenFunc func(token string) (uuid.UUID, error)
+	ValidateTokenFunc func(ctx context.Context, token string) (uuid.UUID, error)
 }
 
-func (m *MockAuthWithJWTService) ValidateToken(token string) (uuid.UUID, error) {
+func (m *MockAuthWithJWTService) ValidateToken(ctx context.Context, token string) (uuid.UUID, error) {
 	if m.ValidateTokenFunc != nil {
-		return m.ValidateTokenFunc(token)
+		return m.ValidateTokenFunc(ctx, token)
 	}
 	return uuid.New(), nil
 }
@@ -48,7 +49,7 @@ func TestAuthWithJWT(t *testing.T) {
 				return req
 			},
 			setupMockService: func(m *MockAuthWithJWTService) {
-				m.ValidateTokenFunc = func(token string) (uuid.UUID, error) {
+				m.ValidateTokenFunc = func(_ context.Context, token string) (uuid.UUID, error) {
 					assert.Equal(t, "valid_token_123", token)
 					return testUserID, nil
 				}
@@ -90,7 +91,7 @@ func TestAuthWithJWT(t *testing.T) {
 				return req
 			},
 			setupMockService: func(m *MockAuthWithJWTService) {
-				m.ValidateTokenFunc = func(token string) (uuid.UUID, error) {
+				m.ValidateTokenFunc = func(_ context.Context, token string) (uuid.UUID, error) {
 					assert.Equal(t, "raw_token_without_bearer", token)
 					return testUserID, nil
 				}
@@ -108,7 +109,7 @@ func TestAuthWithJWT(t *testing.T) {
 				return req
 			},
 			setupMockService: func(m *MockAuthWithJWTService) {
-				m.ValidateTokenFunc = func(token string) (uuid.UUID, error) {
+				m.ValidateTokenFunc = func(_ context.Context, token string) (uuid.UUID, error) {
 					return uuid.Nil, errors.New("invalid token")
 				}
 			},
@@ -124,7 +125,7 @@ func TestAuthWithJWT(t *testing.T) {
 				return req
 			},
 			setupMockService: func(m *MockAuthWithJWTService) {
-				m.ValidateTokenFunc = func(token string) (uuid.UUID, error) {
+				m.ValidateTokenFunc = func(_ context.Context, token string) (uuid.UUID, error) {
 					assert.Equal(t, "token_after_bearer", token)
 					return testUserID, nil
 				}
@@ -191,7 +192,7 @@ func TestAuthWithJWT_WithServer(t *testing.T) {
 	testUserID := uuid.New()
 
 	mockService := &MockAuthWithJWTService{
-		ValidateTokenFunc: func(token string) (uuid.UUID, error) {
+		ValidateTokenFunc: func(_ context.Context, token string) (uuid.UUID, error) {
 			if token == "valid_token" {
 				return testUserID, nil
 			}
@@ -258,3 +259,51 @@ func TestAuthWithJWT_WithServer(t *testing.T) {
 		})
 	}
 }
+
+func TestOptionalAuthWithJWT(t *testing.T) {
+	t.Parallel()
+
+	testUserID := uuid.New()
+	service := &MockAuthWithJWTService{
+		ValidateTokenFunc: func(_ context.Context, token string) (uuid.UUID, error) {
+			if token == "valid_token" {
+				return testUserID, nil
+			}
+			return uuid.Nil, errors.New("invalid token")
+		},
+	}
+
+	tests := []struct {
+		name            string
+		authorization   string
+		wantUserIDInCtx bool
+	}{
+		{name: "valid token", authorization: "Bearer valid_token", wantUserIDInCtx: true},
+		{name: "missing token"},
+		{name: "invalid token", authorization: "Bearer expired_token"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/version", nil)
+			if tt.authorization != "" {
+				c.Request.Header.Set("Authorization", tt.authorization)
+			}
+
+			OptionalAuthWithJWT(service)(c)
+
+			assert.False(t, c.IsAborted())
+			assert.Equal(t, http.StatusOK, w.Code)
+			userID, exists := c.Get(consts.CtxKeyUserID)
+			require.Equal(t, tt.wantUserIDInCtx, exists)
+			if tt.wantUserIDInCtx {
+				assert.Equal(t, testUserID, userID)
+			}
+		})
+	}
+}
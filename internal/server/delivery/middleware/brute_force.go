@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/netip"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/bruteforce"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/clientinfo"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/response"
+	"github.com/gin-gonic/gin"
+)
+
+// BruteForceGuard defines the interface for brute force protection services.
+type BruteForceGuard interface {
+	// Check verifies that the client is not banned.
+	Check(ctx context.Context, ip netip.Addr) error
+	// RecordFailure logs an authentication failure and bans clients that fail too often.
+	RecordFailure(ctx context.Context, params bruteforce.FailureParams)
+}
+
+// BruteForce creates middleware that rejects requests from banned clients and reports 401 Unauthorized responses
+// of routes whose path starts with one of authPrefixes as authentication failures. Failures are reported with
+// the route pattern rather than the request path, so client-controlled text never reaches the failure log.
+// A nil guard disables the middleware.
+func BruteForce(guard BruteForceGuard, authPrefixes ...string) gin.HandlerFunc {
+	if guard == nil {
+		return func(c *gin.Context) { c.Next() }
+	}
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		ip, _ := clientinfo.IP(ctx)
+
+		if err := guard.Check(ctx, ip); err != nil {
+			code, msgs := handleError(err, c)
+			c.JSON(code, response.Error{
+				Messages: msgs,
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+
+		if c.Writer.Status() == http.StatusUnauthorized && hasAnyPrefix(c.FullPath(), authPrefixes) {
+			guard.RecordFailure(ctx, bruteforce.FailureParams{IP: ip, Method: c.Request.Method, Route: c.FullPath()})
+		}
+	}
+}
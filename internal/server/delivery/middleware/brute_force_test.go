@@ -0,0 +1,119 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/bruteforce"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockBruteForceGuard implements BruteForceGuard for testing.
+type mockBruteForceGuard struct {
+	err      error
+	checked  netip.Addr
+	failures []bruteforce.FailureParams
+}
+
+func (m *mockBruteForceGuard) Check(_ context.Context, ip netip.Addr) error {
+	m.checked = ip
+	return m.err
+}
+
+func (m *mockBruteForceGuard) RecordFailure(_ context.Context, params bruteforce.FailureParams) {
+	m.failures = append(m.failures, params)
+}
+
+func TestBruteForce(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		checkErr     error
+		name         string
+		path         string
+		status       int
+		wantStatus   int
+		wantFailures int
+	}{
+		{
+			name:       "successful login",
+			path:       "/api/auth/login",
+			status:     http.StatusOK,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:         "failed login",
+			path:         "/api/auth/login",
+			status:       http.StatusUnauthorized,
+			wantStatus:   http.StatusUnauthorized,
+			wantFailures: 1,
+		},
+		{
+			name:       "unauthorized route outside the prefixes",
+			path:       "/api/items/notes",
+			status:     http.StatusUnauthorized,
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "forbidden login",
+			path:       "/api/auth/login",
+			status:     http.StatusForbidden,
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "banned client",
+			path:       "/api/items/notes",
+			checkErr:   bruteforce.ErrClientBanned,
+			status:     http.StatusOK,
+			wantStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			guard := &mockBruteForceGuard{err: tt.checkErr}
+			router := gin.New()
+			router.Use(RealIP(nil), BruteForce(guard, "/api/auth/login"))
+			router.POST(tt.path, func(c *gin.Context) { c.Status(tt.status) })
+
+			req := httptest.NewRequest(http.MethodPost, tt.path, nil)
+			req.RemoteAddr = "203.0.113.7:4321"
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.Equal(t, netip.MustParseAddr("203.0.113.7"), guard.checked)
+			require.Len(t, guard.failures, tt.wantFailures)
+			if tt.wantFailures > 0 {
+				assert.Equal(t, bruteforce.FailureParams{
+					IP:     netip.MustParseAddr("203.0.113.7"),
+					Method: http.MethodPost,
+					Route:  tt.path,
+				}, guard.failures[0])
+			}
+		})
+	}
+}
+
+func TestBruteForce_NilGuard(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(BruteForce(nil))
+	router.GET("/test", func(c *gin.Context) { c.Status(http.StatusUnauthorized) })
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/test", nil))
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
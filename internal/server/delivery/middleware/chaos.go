@@ -0,0 +1,92 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/chaos"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/response"
+	"github.com/gin-gonic/gin"
+)
+
+// MetricChaosFaults counts the faults injected into HTTP requests.
+const MetricChaosFaults = "chaos_http_faults_injected_total"
+
+// chaosMessage is returned for requests failed by an injected error fault.
+const chaosMessage = "The request failed due to an injected fault"
+
+// ChaosRecorder defines the interface for counting injected faults.
+type ChaosRecorder interface {
+	// Add increments the named counter by delta.
+	Add(name string, delta int64)
+}
+
+// ChaosConfig contains the fault injection settings of HTTP requests.
+type ChaosConfig struct {
+	// Recorder counts injected faults (nil disables counting).
+	Recorder ChaosRecorder
+	// Roll draws the numbers faults fire by (nil uses a pseudo-random source).
+	Roll func() float64
+	// Rules select the requests faults are injected into (empty disables fault injection).
+	Rules []chaos.Rule
+}
+
+// Chaos creates middleware that injects the faults of the rules matching the request path and method.
+// Every matching fault fires independently: latency faults delay the request first, then a drop fault
+// closes the connection without a response, otherwise an error fault answers with its status code.
+// Dropping aborts the handler with http.ErrAbortHandler, so the server closes the connection.
+func Chaos(cfg ChaosConfig) gin.HandlerFunc {
+	if len(cfg.Rules) == 0 {
+		return func(c *gin.Context) { c.Next() }
+	}
+	return func(c *gin.Context) {
+		// delay accumulates the latency of the fired latency faults.
+		var delay time.Duration
+		// drop reports whether a drop fault fired.
+		var drop bool
+		// status holds the status code of the first fired error fault.
+		var status int
+		for _, r := range cfg.Rules {
+			if !r.Matches(c.Request.Method, c.Request.URL.Path) || !r.Fault.Fires(cfg.Roll) {
+				continue
+			}
+			recordChaos(cfg.Recorder)
+			switch r.Fault.Kind {
+			case chaos.KindLatency:
+				delay += r.Fault.Latency
+			case chaos.KindDrop:
+				drop = true
+			case chaos.KindError:
+				if status == 0 {
+					status = r.Fault.Status
+				}
+			}
+		}
+
+		if delay > 0 {
+			t := time.NewTimer(delay)
+			select {
+			case <-t.C:
+			case <-c.Request.Context().Done():
+				t.Stop()
+				c.Abort()
+				return
+			}
+		}
+		if drop {
+			panic(http.ErrAbortHandler)
+		}
+		if status != 0 {
+			c.AbortWithStatusJSON(status, response.Error{Messages: []string{chaosMessage}})
+			return
+		}
+		c.Next()
+	}
+}
+
+// recordChaos increments the injected fault counter when a recorder is configured.
+func recordChaos(recorder ChaosRecorder) {
+	if recorder != nil {
+		recorder.Add(MetricChaosFaults, 1)
+	}
+}
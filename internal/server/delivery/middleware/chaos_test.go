@@ -0,0 +1,137 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/chaos"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChaos(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+
+	errorRule := chaos.Rule{
+		Method:     http.MethodPost,
+		PathPrefix: "/api/items",
+		Fault:      chaos.Fault{Kind: chaos.KindError, Probability: 0.5, Status: http.StatusBadGateway},
+	}
+	latencyRule := chaos.Rule{
+		PathPrefix: "/api",
+		Fault:      chaos.Fault{Kind: chaos.KindLatency, Probability: 1, Latency: 20 * time.Millisecond},
+	}
+
+	tests := []struct {
+		wantCounts  map[string]int64
+		name        string
+		method      string
+		path        string
+		rules       []chaos.Rule
+		roll        float64
+		wantStatus  int
+		wantLatency time.Duration
+	}{
+		{
+			name:       "no rules",
+			method:     http.MethodPost,
+			path:       "/api/items/notes",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "error fired",
+			rules:      []chaos.Rule{errorRule},
+			method:     http.MethodPost,
+			path:       "/api/items/notes",
+			roll:       0.1,
+			wantStatus: http.StatusBadGateway,
+			wantCounts: map[string]int64{MetricChaosFaults: 1},
+		},
+		{
+			name:       "error not fired",
+			rules:      []chaos.Rule{errorRule},
+			method:     http.MethodPost,
+			path:       "/api/items/notes",
+			roll:       0.7,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "other method",
+			rules:      []chaos.Rule{errorRule},
+			method:     http.MethodGet,
+			path:       "/api/items/notes",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "other path",
+			rules:      []chaos.Rule{errorRule},
+			method:     http.MethodPost,
+			path:       "/api/auth/login",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:        "latency and error",
+			rules:       []chaos.Rule{latencyRule, errorRule},
+			method:      http.MethodPost,
+			path:        "/api/items/notes",
+			wantStatus:  http.StatusBadGateway,
+			wantLatency: 20 * time.Millisecond,
+			wantCounts:  map[string]int64{MetricChaosFaults: 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			recorder := &countingRecorder{}
+			router := gin.New()
+			router.Use(Chaos(ChaosConfig{
+				Rules:    tt.rules,
+				Roll:     func() float64 { return tt.roll },
+				Recorder: recorder,
+			}))
+			router.Any("/*path", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+			w := httptest.NewRecorder()
+			start := time.Now()
+			router.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, http.NoBody))
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.GreaterOrEqual(t, time.Since(start), tt.wantLatency)
+			assert.Equal(t, tt.wantCounts, recorder.counts)
+		})
+	}
+}
+
+func TestChaos_Drop(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(Chaos(ChaosConfig{Rules: []chaos.Rule{{
+		PathPrefix: "/",
+		Fault:      chaos.Fault{Kind: chaos.KindDrop, Probability: 1},
+	}}}))
+	router.GET("/health", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/health", http.NoBody))
+	})
+
+	srv := httptest.NewServer(router)
+	defer srv.Close()
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, srv.URL+"/health", http.NoBody)
+	require.NoError(t, err)
+	resp, err := srv.Client().Do(req)
+	if err == nil {
+		_ = resp.Body.Close()
+	}
+	assert.Error(t, err, "the connection should be closed without a response")
+}
@@ -0,0 +1,97 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// corsWildcard allows requests from any origin when listed among the allowed origins.
+const corsWildcard = "*"
+
+// CORSConfig contains cross-origin resource sharing settings.
+type CORSConfig struct {
+	// AllowedOrigins lists origins allowed to call the API; "*" allows any origin, empty disables CORS.
+	AllowedOrigins []string
+	// AllowedMethods lists HTTP methods allowed in cross-origin requests.
+	AllowedMethods []string
+	// AllowedHeaders lists request headers allowed in cross-origin requests.
+	AllowedHeaders []string
+	// MaxAge specifies how long browsers may cache preflight responses (0 omits the header).
+	MaxAge time.Duration
+	// AllowCredentials determines whether cross-origin requests may include credentials.
+	AllowCredentials bool
+}
+
+// CORS creates middleware that applies cross-origin resource sharing headers and answers preflight requests.
+// Requests without an Origin header and requests from origins that are not allowed pass through unchanged,
+// so browsers block their responses; preflight requests from such origins are rejected.
+func CORS(cfg CORSConfig) gin.HandlerFunc {
+	if len(cfg.AllowedOrigins) == 0 {
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	allowAny := slices.Contains(cfg.AllowedOrigins, corsWildcard)
+	methods := strings.Join(cfg.AllowedMethods, ", ")
+	headers := strings.Join(cfg.AllowedHeaders, ", ")
+	maxAge := strconv.Itoa(int(cfg.MaxAge.Seconds()))
+
+	return func(c *gin.Context) {
+		origin := c.Request.Header.Get("Origin")
+		if origin == "" {
+			c.Next()
+			return
+		}
+
+		h := c.Writer.Header()
+		h.Add("Vary", "Origin")
+
+		preflight := c.Request.Method == http.MethodOptions &&
+			c.Request.Header.Get("Access-Control-Request-Method") != ""
+		allowed := allowAny || slices.Contains(cfg.AllowedOrigins, origin)
+		if !allowed {
+			if preflight {
+				c.AbortWithStatus(http.StatusForbidden)
+				return
+			}
+			c.Next()
+			return
+		}
+
+		// Credentialed responses must name the origin explicitly instead of the wildcard.
+		if allowAny && !cfg.AllowCredentials {
+			h.Set("Access-Control-Allow-Origin", corsWildcard)
+		} else {
+			h.Set("Access-Control-Allow-Origin", origin)
+		}
+		if cfg.AllowCredentials {
+			h.Set("Access-Control-Allow-Credentials", "true")
+		}
+
+		if !preflight {
+			c.Next()
+			return
+		}
+
+		requested := c.Request.Header.Get("Access-Control-Request-Method")
+		if !slices.Contains(cfg.AllowedMethods, requested) {
+			c.AbortWithStatus(http.StatusForbidden)
+			return
+		}
+
+		h.Add("Vary", "Access-Control-Request-Method")
+		h.Add("Vary", "Access-Control-Request-Headers")
+		h.Set("Access-Control-Allow-Methods", methods)
+		if headers != "" {
+			h.Set("Access-Control-Allow-Headers", headers)
+		}
+		if cfg.MaxAge > 0 {
+			h.Set("Access-Control-Max-Age", maxAge)
+		}
+		c.AbortWithStatus(http.StatusNoContent)
+	}
+}
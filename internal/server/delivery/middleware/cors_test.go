@@ -0,0 +1,169 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCORS(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+
+	restricted := CORSConfig{
+		AllowedOrigins:   []string{"https://app.example.com"},
+		AllowedMethods:   []string{http.MethodGet, http.MethodPost},
+		AllowedHeaders:   []string{"Authorization", "Content-Type"},
+		MaxAge:           10 * time.Minute,
+		AllowCredentials: true,
+	}
+	wildcard := CORSConfig{
+		AllowedOrigins: []string{"*"},
+		AllowedMethods: []string{http.MethodGet},
+	}
+
+	tests := []struct {
+		wantHeaders    map[string]string
+		requestHeaders map[string]string
+		name           string
+		method         string
+		cfg            CORSConfig
+		wantStatus     int
+	}{
+		{
+			name:        "disabled/no_origins",
+			cfg:         CORSConfig{},
+			method:      http.MethodGet,
+			wantStatus:  http.StatusOK,
+			wantHeaders: map[string]string{"Access-Control-Allow-Origin": ""},
+			requestHeaders: map[string]string{
+				"Origin": "https://app.example.com",
+			},
+		},
+		{
+			name:        "simple/no_origin_header",
+			cfg:         restricted,
+			method:      http.MethodGet,
+			wantStatus:  http.StatusOK,
+			wantHeaders: map[string]string{"Access-Control-Allow-Origin": "", "Vary": ""},
+		},
+		{
+			name:       "simple/allowed_origin",
+			cfg:        restricted,
+			method:     http.MethodGet,
+			wantStatus: http.StatusOK,
+			requestHeaders: map[string]string{
+				"Origin": "https://app.example.com",
+			},
+			wantHeaders: map[string]string{
+				"Access-Control-Allow-Origin":      "https://app.example.com",
+				"Access-Control-Allow-Credentials": "true",
+				"Vary":                             "Origin",
+			},
+		},
+		{
+			name:       "simple/disallowed_origin",
+			cfg:        restricted,
+			method:     http.MethodGet,
+			wantStatus: http.StatusOK,
+			requestHeaders: map[string]string{
+				"Origin": "https://evil.example.com",
+			},
+			wantHeaders: map[string]string{"Access-Control-Allow-Origin": ""},
+		},
+		{
+			name:       "simple/wildcard_origin",
+			cfg:        wildcard,
+			method:     http.MethodGet,
+			wantStatus: http.StatusOK,
+			requestHeaders: map[string]string{
+				"Origin": "https://any.example.com",
+			},
+			wantHeaders: map[string]string{
+				"Access-Control-Allow-Origin":      "*",
+				"Access-Control-Allow-Credentials": "",
+			},
+		},
+		{
+			name:       "preflight/allowed",
+			cfg:        restricted,
+			method:     http.MethodOptions,
+			wantStatus: http.StatusNoContent,
+			requestHeaders: map[string]string{
+				"Origin":                        "https://app.example.com",
+				"Access-Control-Request-Method": http.MethodPost,
+			},
+			wantHeaders: map[string]string{
+				"Access-Control-Allow-Origin":  "https://app.example.com",
+				"Access-Control-Allow-Methods": "GET, POST",
+				"Access-Control-Allow-Headers": "Authorization, Content-Type",
+				"Access-Control-Max-Age":       "600",
+			},
+		},
+		{
+			name:       "preflight/disallowed_method",
+			cfg:        restricted,
+			method:     http.MethodOptions,
+			wantStatus: http.StatusForbidden,
+			requestHeaders: map[string]string{
+				"Origin":                        "https://app.example.com",
+				"Access-Control-Request-Method": http.MethodDelete,
+			},
+			wantHeaders: map[string]string{"Access-Control-Allow-Methods": ""},
+		},
+		{
+			name:       "preflight/disallowed_origin",
+			cfg:        restricted,
+			method:     http.MethodOptions,
+			wantStatus: http.StatusForbidden,
+			requestHeaders: map[string]string{
+				"Origin":                        "https://evil.example.com",
+				"Access-Control-Request-Method": http.MethodGet,
+			},
+			wantHeaders: map[string]string{"Access-Control-Allow-Origin": ""},
+		},
+		{
+			name:       "preflight/wildcard_without_max_age",
+			cfg:        wildcard,
+			method:     http.MethodOptions,
+			wantStatus: http.StatusNoContent,
+			requestHeaders: map[string]string{
+				"Origin":                        "https://any.example.com",
+				"Access-Control-Request-Method": http.MethodGet,
+			},
+			wantHeaders: map[string]string{
+				"Access-Control-Allow-Origin":  "*",
+				"Access-Control-Allow-Headers": "",
+				"Access-Control-Max-Age":       "",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			router := gin.New()
+			router.Use(CORS(tt.cfg))
+			router.GET("/test", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+			req := httptest.NewRequest(tt.method, "/test", nil)
+			for k, v := range tt.requestHeaders {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+
+			router.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			for k, v := range tt.wantHeaders {
+				assert.Equal(t, v, rec.Header().Get(k), "header %s", k)
+			}
+		})
+	}
+}
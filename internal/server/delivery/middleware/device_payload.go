@@ -0,0 +1,146 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/devicekey"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/crypto"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/consts"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/response"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// DevicePayloadAlgorithm names the scheme responses sealed for a device key are encrypted with.
+const DevicePayloadAlgorithm = "X25519-HKDF-SHA256-A256GCM"
+
+// Messages returned for requests naming unusable device keys.
+const (
+	// deviceKeyMalformedMessage is returned when the device key header is not a key ID.
+	deviceKeyMalformedMessage = "X-Device-Key must be the ID of a registered device key"
+	// deviceKeyUnknownMessage is returned for device keys that were never registered or are revoked.
+	deviceKeyUnknownMessage = "Device key is unknown or revoked"
+)
+
+// DeviceKeyResolver defines the interface for resolving the device keys of users.
+type DeviceKeyResolver interface {
+	// PublicKey returns the X25519 public key of a device key of the user.
+	PublicKey(ctx context.Context, params devicekey.PublicKeyParams) ([]byte, error)
+}
+
+// SealedPayload is the response body replacing successful responses sealed for a device key.
+type SealedPayload struct {
+	// KeyID identifies the device key the payload is sealed for.
+	KeyID string `json:"key_id"`
+	// Algorithm names the encryption scheme.
+	Algorithm string `json:"alg"`
+	// EphemeralKey contains the X25519 public key the key wrapping key was agreed with.
+	EphemeralKey []byte `json:"ephemeral_key"`
+	// WrappedKey contains the AES-256-GCM session key encrypted with the key wrapping key, the nonce prepended.
+	WrappedKey []byte `json:"wrapped_key"`
+	// Ciphertext contains the original response body encrypted with the session key, the nonce prepended.
+	Ciphertext []byte `json:"ciphertext"`
+	// ContentType is the content type of the original response body.
+	ContentType string `json:"content_type"`
+}
+
+// DevicePayloadEncryption creates middleware that encrypts successful responses for the device key named in the
+// X-Device-Key header, so sync payloads stay opaque to TLS-terminating proxies and only the device holding the
+// private key can read them. Requests without the header are served in plaintext. Unknown or revoked keys are
+// rejected with 403 Forbidden before the handler runs. The response is buffered while sealing, so streamed bodies
+// are delivered at once; error responses are never sealed. It must run after AuthWithJWT.
+// A nil resolver disables the middleware.
+func DevicePayloadEncryption(resolver DeviceKeyResolver) gin.HandlerFunc {
+	if resolver == nil {
+		return func(c *gin.Context) { c.Next() }
+	}
+	return func(c *gin.Context) {
+		header := c.GetHeader(consts.HeaderXDeviceKey)
+		if header == "" {
+			c.Next()
+			return
+		}
+		keyID, err := uuid.Parse(header)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, response.Error{Messages: []string{deviceKeyMalformedMessage}})
+			c.Abort()
+			return
+		}
+
+		value, _ := c.Get(consts.CtxKeyUserID)
+		userID, _ := value.(uuid.UUID)
+		publicKey, err := resolver.PublicKey(c.Request.Context(), devicekey.PublicKeyParams{ID: keyID, UserID: userID})
+		if err != nil {
+			if errors.Is(err, devicekey.ErrDeviceKeyNotFound) {
+				c.JSON(http.StatusForbidden, response.Error{Messages: []string{deviceKeyUnknownMessage}})
+				c.Abort()
+				return
+			}
+			abortWithServerError(c, err)
+			return
+		}
+
+		original := c.Writer
+		writer := &bufferedWriter{ResponseWriter: original}
+		c.Writer = writer
+
+		c.Next()
+
+		c.Writer = original
+		status := writer.Status()
+		if status < http.StatusOK || status >= http.StatusMultipleChoices || writer.body.Len() == 0 {
+			original.WriteHeaderNow()
+			_, _ = original.Write(writer.body.Bytes())
+			return
+		}
+
+		// The headers describing the original body no longer apply to the envelope replacing it.
+		contentType := original.Header().Get("Content-Type")
+		original.Header().Del("Content-Type")
+		original.Header().Del("Content-Length")
+		original.Header().Del("Content-Disposition")
+		id := keyID.String()
+		envelope, err := crypto.SealForX25519(publicKey, writer.body.Bytes(), []byte(id))
+		if err != nil {
+			_ = c.Error(err)
+			c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+			return
+		}
+		original.Header().Set(consts.HeaderXDeviceKey, id)
+		c.JSON(status, SealedPayload{
+			KeyID:        id,
+			Algorithm:    DevicePayloadAlgorithm,
+			EphemeralKey: envelope.EphemeralKey,
+			WrappedKey:   envelope.WrappedKey,
+			Ciphertext:   envelope.Ciphertext,
+			ContentType:  contentType,
+		})
+	}
+}
+
+// bufferedWriter holds back the response body so it can be sealed or signed once the handler is done.
+type bufferedWriter struct {
+	// ResponseWriter is the wrapped gin response writer.
+	gin.ResponseWriter
+	// body holds the complete response body.
+	body bytes.Buffer
+}
+
+// Write buffers the response body.
+func (w *bufferedWriter) Write(data []byte) (int, error) {
+	return w.body.Write(data) // nolint:wrapcheck // Buffering writer wrapper
+}
+
+// WriteString buffers the response body.
+func (w *bufferedWriter) WriteString(s string) (int, error) {
+	return w.body.WriteString(s) // nolint:wrapcheck // Buffering writer wrapper
+}
+
+// WriteHeaderNow holds back the status line until the handler is done.
+func (w *bufferedWriter) WriteHeaderNow() {}
+
+// Flush holds back streamed chunks until the handler is done.
+func (w *bufferedWriter) Flush() {}
@@ -0,0 +1,182 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/devicekey"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/crypto"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/consts"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockDeviceKeyResolver implements DeviceKeyResolver for testing.
+type mockDeviceKeyResolver struct {
+	err  error
+	keys map[uuid.UUID][]byte
+}
+
+func (m *mockDeviceKeyResolver) PublicKey(_ context.Context, params devicekey.PublicKeyParams) ([]byte, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	key, ok := m.keys[params.ID]
+	if !ok {
+		return nil, devicekey.ErrDeviceKeyNotFound
+	}
+	return bytes.Clone(key), nil
+}
+
+// newDevicePayloadRouter creates a router answering /sync with a streamed body and /fail with an error.
+func newDevicePayloadRouter(mw gin.HandlerFunc, userID uuid.UUID) *gin.Engine {
+	router := gin.New()
+	setUser := func(c *gin.Context) {
+		c.Set(consts.CtxKeyUserID, userID)
+		c.Next()
+	}
+	router.GET("/sync", setUser, mw, func(c *gin.Context) {
+		c.Header("Content-Type", "application/x-ndjson")
+		c.Status(http.StatusOK)
+		_, _ = c.Writer.WriteString("{\"n\":1}\n")
+		c.Writer.Flush()
+		_, _ = c.Writer.WriteString("{\"n\":2}\n")
+	})
+	router.GET("/fail", setUser, mw, func(c *gin.Context) {
+		c.JSON(http.StatusConflict, gin.H{"messages": []string{"conflict"}})
+	})
+	return router
+}
+
+func TestDevicePayloadEncryption(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+
+	userID := uuid.New()
+	keyID := uuid.New()
+	privateKey, err := ecdh.X25519().GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	resolver := &mockDeviceKeyResolver{keys: map[uuid.UUID][]byte{keyID: privateKey.PublicKey().Bytes()}}
+
+	tests := []struct {
+		resolver   *mockDeviceKeyResolver
+		name       string
+		header     string
+		path       string
+		wantBody   string
+		wantStatus int
+		wantSealed bool
+	}{
+		{
+			name:       "no resolver",
+			header:     keyID.String(),
+			path:       "/sync",
+			wantStatus: http.StatusOK,
+			wantBody:   "{\"n\":1}\n{\"n\":2}\n",
+		},
+		{
+			name:       "no header",
+			resolver:   resolver,
+			path:       "/sync",
+			wantStatus: http.StatusOK,
+			wantBody:   "{\"n\":1}\n{\"n\":2}\n",
+		},
+		{
+			name:       "sealed",
+			resolver:   resolver,
+			header:     keyID.String(),
+			path:       "/sync",
+			wantStatus: http.StatusOK,
+			wantBody:   "{\"n\":1}\n{\"n\":2}\n",
+			wantSealed: true,
+		},
+		{
+			name:       "error response stays plaintext",
+			resolver:   resolver,
+			header:     keyID.String(),
+			path:       "/fail",
+			wantStatus: http.StatusConflict,
+			wantBody:   `{"messages":["conflict"]}`,
+		},
+		{
+			name:       "unknown key",
+			resolver:   resolver,
+			header:     uuid.NewString(),
+			path:       "/sync",
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "malformed key ID",
+			resolver:   resolver,
+			header:     "laptop",
+			path:       "/sync",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "key lookup error",
+			resolver:   &mockDeviceKeyResolver{err: errors.New("connection refused")},
+			header:     keyID.String(),
+			path:       "/sync",
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var r DeviceKeyResolver
+			if tt.resolver != nil {
+				r = tt.resolver
+			}
+			router := newDevicePayloadRouter(DevicePayloadEncryption(r), userID)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.header != "" {
+				req.Header.Set(consts.HeaderXDeviceKey, tt.header)
+			}
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantBody == "" {
+				return
+			}
+			if !tt.wantSealed {
+				assert.Equal(t, tt.wantBody, w.Body.String())
+				assert.Empty(t, w.Header().Get(consts.HeaderXDeviceKey))
+				return
+			}
+
+			assert.Equal(t, keyID.String(), w.Header().Get(consts.HeaderXDeviceKey))
+			assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+			var payload SealedPayload
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &payload))
+			assert.Equal(t, keyID.String(), payload.KeyID)
+			assert.Equal(t, DevicePayloadAlgorithm, payload.Algorithm)
+			assert.Equal(t, "application/x-ndjson", payload.ContentType)
+
+			envelope := &crypto.RecipientEnvelope{
+				EphemeralKey: payload.EphemeralKey,
+				WrappedKey:   payload.WrappedKey,
+				Ciphertext:   payload.Ciphertext,
+			}
+			plaintext, err := crypto.OpenForX25519(privateKey, envelope, []byte(payload.KeyID))
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantBody, string(plaintext))
+
+			_, err = crypto.OpenForX25519(privateKey, envelope, []byte(uuid.NewString()))
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/consts"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/response"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/dryrun"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// dryRunQueryParam is the query parameter marking dry runs for clients unable to set headers.
+const dryRunQueryParam = "dry_run"
+
+// Dry run rejection messages.
+const (
+	// dryRunInvalidMessage is returned when the dry-run mark is not a boolean.
+	dryRunInvalidMessage = "X-Dry-Run and dry_run must be true or false"
+	// dryRunUnsupportedMessage is returned when the storage cannot roll back changes.
+	dryRunUnsupportedMessage = "Dry runs are not supported by the storage of this server"
+)
+
+// DryRunner defines the interface for running requests in transactions that always roll back.
+type DryRunner interface {
+	// DryRun executes fn in a transaction scoped to the user that rolls back once fn returns.
+	DryRun(ctx context.Context, userID uuid.UUID, fn func(ctx context.Context) error) error
+}
+
+// DryRun creates middleware that handles mutating requests marked with the X-Dry-Run header or the dry_run query
+// parameter in a transaction that always rolls back: validation, authorization and storage constraints apply as
+// usual and the response tells what would happen, but nothing is stored. Responses of dry runs carry the
+// X-Dry-Run: true header; the request context stays marked for dryrun.Enabled, so earlier middleware can skip its
+// own effects. Dry runs are rejected with 501 Not Implemented when the storage cannot roll back, marks other than
+// booleans with 400 Bad Request. Must run after AuthWithJWT. A nil runner rejects every dry run.
+func DryRun(runner DryRunner) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		enabled, valid := dryRunRequested(c)
+		if !valid {
+			c.JSON(http.StatusBadRequest, response.Error{Messages: []string{dryRunInvalidMessage}})
+			c.Abort()
+			return
+		}
+		if !enabled || isSafeMethod(c.Request.Method) {
+			c.Next()
+			return
+		}
+		if runner == nil {
+			c.JSON(http.StatusNotImplemented, response.Error{Messages: []string{dryRunUnsupportedMessage}})
+			c.Abort()
+			return
+		}
+
+		value, _ := c.Get(consts.CtxKeyUserID)
+		userID, _ := value.(uuid.UUID)
+		ctx := c.Request.Context()
+		// handled reports whether the request reached the handlers, which responded already.
+		handled := false
+		err := runner.DryRun(ctx, userID, func(ctx context.Context) error {
+			handled = true
+			c.Header(consts.HeaderXDryRun, "true")
+			c.Request = c.Request.WithContext(ctx)
+			c.Next()
+			return nil
+		})
+		// The transaction has ended, so the context carrying it must not reach earlier middleware.
+		c.Request = c.Request.WithContext(dryrun.With(ctx))
+
+		switch {
+		case errors.Is(err, dryrun.ErrUnsupported):
+			c.JSON(http.StatusNotImplemented, response.Error{Messages: []string{dryRunUnsupportedMessage}})
+			c.Abort()
+		case err != nil && !handled:
+			_ = c.Error(err)
+			c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+			c.Abort()
+		case err != nil:
+			_ = c.Error(err)
+		}
+	}
+}
+
+// dryRunRequested reports whether the request is marked as a dry run by the header or the query parameter,
+// and whether the marks present are booleans.
+func dryRunRequested(c *gin.Context) (bool, bool) {
+	requested := false
+	for _, mark := range []string{c.GetHeader(consts.HeaderXDryRun), c.Query(dryRunQueryParam)} {
+		if mark == "" {
+			continue
+		}
+		enabled, err := strconv.ParseBool(mark)
+		if err != nil {
+			return false, false
+		}
+		requested = requested || enabled
+	}
+	return requested, true
+}
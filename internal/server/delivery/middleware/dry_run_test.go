@@ -0,0 +1,172 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/consts"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/dryrun"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockDryRunner implements DryRunner for testing, marking the context like a rolled back transaction.
+type mockDryRunner struct {
+	err    error
+	userID uuid.UUID
+	runs   int
+}
+
+func (m *mockDryRunner) DryRun(ctx context.Context, userID uuid.UUID, fn func(ctx context.Context) error) error {
+	if errors.Is(m.err, dryrun.ErrUnsupported) {
+		return m.err
+	}
+	m.runs++
+	m.userID = userID
+	if err := fn(dryrun.With(ctx)); err != nil {
+		return err
+	}
+	return m.err
+}
+
+func TestDryRun(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+	userID := uuid.New()
+
+	tests := []struct {
+		runnerErr      error
+		name           string
+		method         string
+		header         string
+		query          string
+		wantHeader     string
+		expectedStatus int
+		wantRuns       int
+		wantHandled    bool
+		wantDryRun     bool
+	}{
+		{
+			name:           "header marks dry run",
+			method:         http.MethodPost,
+			header:         "true",
+			expectedStatus: http.StatusCreated,
+			wantHeader:     "true",
+			wantRuns:       1,
+			wantHandled:    true,
+			wantDryRun:     true,
+		},
+		{
+			name:           "query marks dry run",
+			method:         http.MethodDelete,
+			query:          "?dry_run=1",
+			expectedStatus: http.StatusCreated,
+			wantHeader:     "true",
+			wantRuns:       1,
+			wantHandled:    true,
+			wantDryRun:     true,
+		},
+		{
+			name:           "unmarked request",
+			method:         http.MethodPost,
+			expectedStatus: http.StatusCreated,
+			wantHandled:    true,
+		},
+		{
+			name:           "dry run switched off",
+			method:         http.MethodPut,
+			header:         "false",
+			expectedStatus: http.StatusCreated,
+			wantHandled:    true,
+		},
+		{
+			name:           "read ignores the mark",
+			method:         http.MethodGet,
+			header:         "true",
+			expectedStatus: http.StatusCreated,
+			wantHandled:    true,
+		},
+		{
+			name:           "invalid mark",
+			method:         http.MethodPost,
+			query:          "?dry_run=maybe",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "unsupported storage",
+			method:         http.MethodPost,
+			header:         "true",
+			runnerErr:      dryrun.ErrUnsupported,
+			expectedStatus: http.StatusNotImplemented,
+		},
+		{
+			name:           "rollback failure after the response",
+			method:         http.MethodPost,
+			header:         "true",
+			runnerErr:      errors.New("rollback failed"),
+			expectedStatus: http.StatusCreated,
+			wantHeader:     "true",
+			wantRuns:       1,
+			wantHandled:    true,
+			wantDryRun:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			runner := &mockDryRunner{err: tt.runnerErr}
+			// handled and handlerDryRun capture what the handler saw.
+			var handled, handlerDryRun bool
+			router := gin.New()
+			router.Use(func(c *gin.Context) {
+				c.Set(consts.CtxKeyUserID, userID)
+				c.Next()
+			})
+			router.Use(DryRun(runner))
+			router.Handle(tt.method, "/items", func(c *gin.Context) {
+				handled = true
+				handlerDryRun = dryrun.Enabled(c.Request.Context())
+				c.Status(http.StatusCreated)
+			})
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(tt.method, "/items"+tt.query, nil)
+			if tt.header != "" {
+				req.Header.Set(consts.HeaderXDryRun, tt.header)
+			}
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, tt.wantHeader, w.Header().Get(consts.HeaderXDryRun))
+			assert.Equal(t, tt.wantRuns, runner.runs)
+			assert.Equal(t, tt.wantHandled, handled)
+			assert.Equal(t, tt.wantDryRun, handlerDryRun)
+			if tt.wantRuns > 0 {
+				assert.Equal(t, userID, runner.userID)
+			}
+		})
+	}
+}
+
+func TestDryRun_NilRunner(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(DryRun(nil))
+	router.POST("/items", func(c *gin.Context) { c.Status(http.StatusCreated) })
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/items", nil)
+	req.Header.Set(consts.HeaderXDryRun, "true")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotImplemented, w.Code)
+}
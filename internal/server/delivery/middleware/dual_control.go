@@ -0,0 +1,106 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"net/http"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/adminapproval"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/consts"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/response"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// DualControlGate defines the interface for the dual-control workflow of destructive admin requests.
+type DualControlGate interface {
+	// Request queues a destructive admin request until a second operator approves it.
+	Request(ctx context.Context, params adminapproval.RequestParams) (*adminapproval.Approval, error)
+	// Execute uses the approval of the request about to be executed.
+	Execute(ctx context.Context, params adminapproval.ExecuteParams) error
+}
+
+// approvalPendingResponse is the response to a destructive admin request queued for approval.
+type approvalPendingResponse struct {
+	// ExpiresAt is the deadline of both the approval and the execution of the request.
+	ExpiresAt time.Time `json:"expires_at"`
+	// Action names the guarded admin action.
+	Action string `json:"action"`
+	// Status contains the state of the approval, pending.
+	Status string `json:"status"`
+	// ID identifies the approval to send in X-Admin-Approval once it is approved.
+	ID uuid.UUID `json:"approval_id"`
+}
+
+// DualControl creates middleware holding back the destructive admin requests listed in actions, which maps
+// the method and route of a request, such as "DELETE /api/admin/legal-holds/:id", to the name of the action,
+// until a second operator approves them. A request without the X-Admin-Approval header is queued and answered
+// with 202 Accepted and the approval ID; once approved, the initiator repeats the identical request with the
+// approval ID in X-Admin-Approval, and it runs. Must run after AdminToken. A nil gate disables the middleware.
+func DualControl(gate DualControlGate, actions map[string]string) gin.HandlerFunc {
+	if gate == nil {
+		return func(c *gin.Context) { c.Next() }
+	}
+	return func(c *gin.Context) {
+		action, ok := actions[c.Request.Method+" "+c.FullPath()]
+		if !ok {
+			c.Next()
+			return
+		}
+
+		body, err := readSignedBody(c.Request)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+			c.Abort()
+			return
+		}
+		digest := sha256.Sum256(body)
+		operator := c.GetString(consts.CtxKeyAdminOperator)
+
+		header := c.GetHeader(consts.HeaderXAdminApproval)
+		if header == "" {
+			approval, err := gate.Request(c.Request.Context(), adminapproval.RequestParams{
+				Action:     action,
+				Method:     c.Request.Method,
+				Path:       c.Request.URL.RequestURI(),
+				Initiator:  operator,
+				BodyDigest: digest[:],
+			})
+			if err != nil {
+				code, msgs := handleError(err, c)
+				c.JSON(code, response.Error{Messages: msgs})
+				c.Abort()
+				return
+			}
+			c.JSON(http.StatusAccepted, approvalPendingResponse{
+				ID:        approval.ID,
+				Action:    approval.Action,
+				Status:    approval.Status,
+				ExpiresAt: approval.ExpiresAt,
+			})
+			c.Abort()
+			return
+		}
+
+		id, err := uuid.Parse(header)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, response.Error{Messages: []string{"Invalid X-Admin-Approval header"}})
+			c.Abort()
+			return
+		}
+		if err := gate.Execute(c.Request.Context(), adminapproval.ExecuteParams{
+			ID:         id,
+			Method:     c.Request.Method,
+			Path:       c.Request.URL.RequestURI(),
+			Operator:   operator,
+			BodyDigest: digest[:],
+		}); err != nil {
+			code, msgs := handleError(err, c)
+			c.JSON(code, response.Error{Messages: msgs})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
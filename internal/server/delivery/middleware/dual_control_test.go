@@ -0,0 +1,192 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/adminapproval"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/consts"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockDualControlGate queues requests and checks executions, recording the parameters.
+type mockDualControlGate struct {
+	executeErr error
+	requested  []adminapproval.RequestParams
+	executed   []adminapproval.ExecuteParams
+}
+
+func (m *mockDualControlGate) Request(
+	_ context.Context,
+	params adminapproval.RequestParams,
+) (*adminapproval.Approval, error) {
+	m.requested = append(m.requested, params)
+	return &adminapproval.Approval{
+		ID:        uuid.MustParse("6f1c2a7e-0d5b-4c55-9a3e-1f2b3c4d5e6f"),
+		Action:    params.Action,
+		Status:    "pending",
+		ExpiresAt: time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC),
+	}, nil
+}
+
+func (m *mockDualControlGate) Execute(_ context.Context, params adminapproval.ExecuteParams) error {
+	m.executed = append(m.executed, params)
+	return m.executeErr
+}
+
+func TestDualControl(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+	approvalID := uuid.New()
+	actions := map[string]string{"DELETE /holds/:id": "legal_hold.release"}
+	body := `{"reason":"Case closed"}`
+	digest := sha256.Sum256([]byte(body))
+
+	tests := []struct {
+		gate          *mockDualControlGate
+		wantRequested []adminapproval.RequestParams
+		wantExecuted  []adminapproval.ExecuteParams
+		name          string
+		method        string
+		path          string
+		approval      string
+		wantStatus    int
+		wantHandled   bool
+	}{
+		{
+			name:        "unguarded route",
+			gate:        &mockDualControlGate{},
+			method:      http.MethodGet,
+			path:        "/holds/1",
+			wantStatus:  http.StatusOK,
+			wantHandled: true,
+		},
+		{
+			name:   "queued for approval",
+			gate:   &mockDualControlGate{},
+			method: http.MethodDelete,
+			path:   "/holds/1?force=true",
+			wantRequested: []adminapproval.RequestParams{{
+				Action:     "legal_hold.release",
+				Method:     http.MethodDelete,
+				Path:       "/holds/1?force=true",
+				Initiator:  "alice",
+				BodyDigest: digest[:],
+			}},
+			wantStatus: http.StatusAccepted,
+		},
+		{
+			name:     "executed under the approval",
+			gate:     &mockDualControlGate{},
+			method:   http.MethodDelete,
+			path:     "/holds/1",
+			approval: approvalID.String(),
+			wantExecuted: []adminapproval.ExecuteParams{{
+				ID:         approvalID,
+				Method:     http.MethodDelete,
+				Path:       "/holds/1",
+				Operator:   "alice",
+				BodyDigest: digest[:],
+			}},
+			wantStatus:  http.StatusOK,
+			wantHandled: true,
+		},
+		{
+			name:       "approval awaiting decision",
+			gate:       &mockDualControlGate{executeErr: adminapproval.ErrApprovalNotApproved},
+			method:     http.MethodDelete,
+			path:       "/holds/1",
+			approval:   approvalID.String(),
+			wantStatus: http.StatusConflict,
+			wantExecuted: []adminapproval.ExecuteParams{{
+				ID: approvalID, Method: http.MethodDelete, Path: "/holds/1", Operator: "alice", BodyDigest: digest[:],
+			}},
+		},
+		{
+			name:       "approval of another request",
+			gate:       &mockDualControlGate{executeErr: adminapproval.ErrRequestMismatch},
+			method:     http.MethodDelete,
+			path:       "/holds/1",
+			approval:   approvalID.String(),
+			wantStatus: http.StatusForbidden,
+			wantExecuted: []adminapproval.ExecuteParams{{
+				ID: approvalID, Method: http.MethodDelete, Path: "/holds/1", Operator: "alice", BodyDigest: digest[:],
+			}},
+		},
+		{
+			name:       "malformed approval",
+			gate:       &mockDualControlGate{},
+			method:     http.MethodDelete,
+			path:       "/holds/1",
+			approval:   "approval",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:        "dual control disabled",
+			method:      http.MethodDelete,
+			path:        "/holds/1",
+			wantStatus:  http.StatusOK,
+			wantHandled: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// gate stays a nil interface without a mock, like the middleware is built without dual control.
+			var gate DualControlGate
+			if tt.gate != nil {
+				gate = tt.gate
+			}
+			// handled reports whether the guarded handler ran.
+			var handled bool
+			router := gin.New()
+			router.Use(func(c *gin.Context) { c.Set(consts.CtxKeyAdminOperator, "alice") }, DualControl(gate, actions))
+			handler := func(c *gin.Context) {
+				got, err := c.GetRawData()
+				require.NoError(t, err)
+				assert.JSONEq(t, body, string(got))
+				handled = true
+				c.Status(http.StatusOK)
+			}
+			router.GET("/holds/:id", handler)
+			router.DELETE("/holds/:id", handler)
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(body))
+			if tt.approval != "" {
+				req.Header.Set(consts.HeaderXAdminApproval, tt.approval)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.Equal(t, tt.wantHandled, handled)
+			if tt.gate != nil {
+				assert.Equal(t, tt.wantRequested, tt.gate.requested)
+				assert.Equal(t, tt.wantExecuted, tt.gate.executed)
+			}
+			if tt.wantStatus == http.StatusAccepted {
+				// resp holds the decoded approval.
+				var resp map[string]string
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+				assert.Equal(t, map[string]string{
+					"approval_id": "6f1c2a7e-0d5b-4c55-9a3e-1f2b3c4d5e6f",
+					"action":      "legal_hold.release",
+					"status":      "pending",
+					"expires_at":  "2026-10-16T12:00:00Z",
+				}, resp)
+			}
+		})
+	}
+}
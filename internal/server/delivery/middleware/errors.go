@@ -3,7 +3,13 @@ package middleware
 import (
 	"net/http"
 
+	accessApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/accesscontrol"
+	policyApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/accesspolicy"
+	adminApprovalApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/adminapproval"
+	approvalApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/approval"
 	app "github.com/gdyunin/aegis-vault-keeper/internal/server/application/auth"
+	botcheckApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/botcheck"
+	bruteforceApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/bruteforce"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/errutil"
 	"github.com/gin-gonic/gin"
 )
@@ -20,6 +26,196 @@ var MiddlewareErrRegistry = errutil.Registry{
 			ErrorClass: errutil.ErrorClassAuth,
 		},
 	},
+	{
+		ErrorIn: app.ErrAuthVaultLocked,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusLocked,
+			PublicMsg:  "Your vault is locked. Please send the unlock key in X-Vault-Unlock",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassAuth,
+		},
+	},
+	{
+		ErrorIn: app.ErrAuthUnlockInvalid,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusForbidden,
+			PublicMsg:  "The unlock key does not match your unlock secret",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassAuth,
+		},
+	},
+	{
+		ErrorIn: accessApp.ErrAccessDenied,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusForbidden,
+			PublicMsg:  "Access from your network location is not permitted",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassAuth,
+		},
+	},
+	{
+		ErrorIn: bruteforceApp.ErrClientBanned,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusForbidden,
+			PublicMsg:  "Too many failed authentication attempts from your network location. Please try again later",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassAuth,
+		},
+	},
+	{
+		ErrorIn: botcheckApp.ErrChallengeRequired,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusPreconditionRequired,
+			PublicMsg:  "Please solve the challenge from /api/auth/challenge and send it in X-Challenge-Response",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassAuth,
+		},
+	},
+	{
+		ErrorIn: botcheckApp.ErrChallengeFailed,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusForbidden,
+			PublicMsg:  "The challenge response is invalid or expired. Please solve a new challenge",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassAuth,
+		},
+	},
+	{
+		ErrorIn: botcheckApp.ErrBotCheckTechError,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusServiceUnavailable,
+			PublicMsg:  "The challenge cannot be verified right now. Please try again later",
+			LogIt:      true,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassTech,
+		},
+	},
+	{
+		ErrorIn: accessApp.ErrRestrictedLocation,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusForbidden,
+			PublicMsg:  "Restricted items cannot be revealed from your location",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassAuth,
+		},
+	},
+	{
+		ErrorIn: approvalApp.ErrApprovalRequired,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusForbidden,
+			PublicMsg:  "This item can only be revealed after your access request is approved",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassAuth,
+		},
+	},
+	{
+		ErrorIn: policyApp.ErrOutsideAccessWindow,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusForbidden,
+			PublicMsg:  "Vault items cannot be revealed outside your access windows",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassAuth,
+		},
+	},
+	{
+		ErrorIn: policyApp.ErrAccessPolicyTechError,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusInternalServerError,
+			PublicMsg:  http.StatusText(http.StatusInternalServerError),
+			LogIt:      true,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassTech,
+		},
+	},
+	{
+		ErrorIn: accessApp.ErrAccessControlTechError,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusInternalServerError,
+			PublicMsg:  http.StatusText(http.StatusInternalServerError),
+			LogIt:      true,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassTech,
+		},
+	},
+	{
+		ErrorIn: approvalApp.ErrApprovalTechError,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusInternalServerError,
+			PublicMsg:  http.StatusText(http.StatusInternalServerError),
+			LogIt:      true,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassTech,
+		},
+	},
+	{
+		ErrorIn: adminApprovalApp.ErrApprovalNotFound,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusNotFound,
+			PublicMsg:  "The admin approval does not exist",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassGeneric,
+		},
+	},
+	{
+		ErrorIn: adminApprovalApp.ErrApprovalNotApproved,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusConflict,
+			PublicMsg:  "The admin request awaits approval by a second operator, was rejected or was already executed",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassGeneric,
+		},
+	},
+	{
+		ErrorIn: adminApprovalApp.ErrApprovalExpired,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusConflict,
+			PublicMsg:  "The admin approval has expired. Please initiate the request again",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassGeneric,
+		},
+	},
+	{
+		ErrorIn: adminApprovalApp.ErrApprovalConflict,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusConflict,
+			PublicMsg:  "The admin approval was used by another request",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassGeneric,
+		},
+	},
+	{
+		ErrorIn: adminApprovalApp.ErrRequestMismatch,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusForbidden,
+			PublicMsg:  "Only the initiator may execute the approved request, unchanged",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassAuth,
+		},
+	},
+	{
+		ErrorIn: adminApprovalApp.ErrAdminApprovalTechError,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusInternalServerError,
+			PublicMsg:  http.StatusText(http.StatusInternalServerError),
+			LogIt:      true,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassTech,
+		},
+	},
 }
 
 // handleError processes middleware errors using the registry and returns appropriate HTTP response.
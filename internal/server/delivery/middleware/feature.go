@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/feature"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/consts"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/response"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// FeatureChecker defines the interface for feature flag services.
+type FeatureChecker interface {
+	// Enabled reports whether the feature is switched on for the user.
+	Enabled(ctx context.Context, params feature.CheckParams) (bool, error)
+}
+
+// RequireFeature creates middleware that hides experimental routes behind a feature flag: requests
+// get 404 Not Found unless the feature is on. After AuthWithJWT the flags of the user apply,
+// otherwise the deployment-wide ones. A nil checker treats every feature as off.
+func RequireFeature(checker FeatureChecker, key string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if checker == nil {
+			c.AbortWithStatus(http.StatusNotFound)
+			return
+		}
+
+		value, _ := c.Get(consts.CtxKeyUserID)
+		userID, _ := value.(uuid.UUID)
+
+		enabled, err := checker.Enabled(c.Request.Context(), feature.CheckParams{Key: key, UserID: userID})
+		if err != nil {
+			_ = c.Error(err)
+			c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+			c.Abort()
+			return
+		}
+		if !enabled {
+			c.AbortWithStatus(http.StatusNotFound)
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,99 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/feature"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/consts"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockFeatureChecker implements FeatureChecker for testing.
+type mockFeatureChecker struct {
+	err     error
+	enabled map[uuid.UUID]bool
+	params  feature.CheckParams
+}
+
+func (m *mockFeatureChecker) Enabled(_ context.Context, params feature.CheckParams) (bool, error) {
+	m.params = params
+	return m.enabled[params.UserID], m.err
+}
+
+func TestRequireFeature(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+
+	userID := uuid.New()
+
+	tests := []struct {
+		checker    *mockFeatureChecker
+		name       string
+		userID     uuid.UUID
+		wantStatus int
+	}{
+		{
+			name:       "no checker",
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "enabled for deployment",
+			checker:    &mockFeatureChecker{enabled: map[uuid.UUID]bool{uuid.Nil: true}},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "enabled for user",
+			checker:    &mockFeatureChecker{enabled: map[uuid.UUID]bool{userID: true}},
+			userID:     userID,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "disabled",
+			checker:    &mockFeatureChecker{enabled: map[uuid.UUID]bool{uuid.Nil: true}},
+			userID:     userID,
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "checker error",
+			checker:    &mockFeatureChecker{err: errors.New("connection refused")},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var checker FeatureChecker
+			if tt.checker != nil {
+				checker = tt.checker
+			}
+
+			router := gin.New()
+			router.GET("/experimental",
+				func(c *gin.Context) {
+					if tt.userID != uuid.Nil {
+						c.Set(consts.CtxKeyUserID, tt.userID)
+					}
+				},
+				RequireFeature(checker, "sync_v2"),
+				func(c *gin.Context) { c.Status(http.StatusOK) },
+			)
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/experimental", nil))
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			if tt.checker != nil {
+				assert.Equal(t, feature.CheckParams{Key: "sync_v2", UserID: tt.userID}, tt.checker.params)
+			}
+		})
+	}
+}
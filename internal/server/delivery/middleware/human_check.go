@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/netip"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/botcheck"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/clientinfo"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/consts"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/response"
+	"github.com/gin-gonic/gin"
+)
+
+// HumanChecker defines the interface for human verification services.
+type HumanChecker interface {
+	// Check verifies the challenge response when the action requires one.
+	Check(ctx context.Context, params botcheck.CheckParams) error
+	// RecordLoginFailure counts a failed login of the client network.
+	RecordLoginFailure(ctx context.Context, ip netip.Addr)
+}
+
+// HumanCheck creates middleware that requires a solved challenge in the X-Challenge-Response header before the
+// action when the checker asks for one. For logins, 401 Unauthorized responses are counted as failures, so
+// logins from networks failing too often require a challenge. A nil checker disables the middleware.
+func HumanCheck(checker HumanChecker, action string) gin.HandlerFunc {
+	if checker == nil {
+		return func(c *gin.Context) { c.Next() }
+	}
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		ip, _ := clientinfo.IP(ctx)
+
+		err := checker.Check(ctx, botcheck.CheckParams{
+			IP:       ip,
+			Action:   action,
+			Response: c.GetHeader(consts.HeaderXChallengeResponse),
+		})
+		if err != nil {
+			code, msgs := handleError(err, c)
+			c.JSON(code, response.Error{
+				Messages: msgs,
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+
+		if action == botcheck.ActionLogin && c.Writer.Status() == http.StatusUnauthorized {
+			checker.RecordLoginFailure(ctx, ip)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/botcheck"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/consts"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockHumanChecker implements HumanChecker for testing.
+type mockHumanChecker struct {
+	err      error
+	params   botcheck.CheckParams
+	failures int
+}
+
+func (m *mockHumanChecker) Check(_ context.Context, params botcheck.CheckParams) error {
+	m.params = params
+	return m.err
+}
+
+func (m *mockHumanChecker) RecordLoginFailure(context.Context, netip.Addr) {
+	m.failures++
+}
+
+func TestHumanCheck(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		checkErr     error
+		name         string
+		action       string
+		status       int
+		wantStatus   int
+		wantFailures int
+	}{
+		{
+			name:       "solved registration",
+			action:     botcheck.ActionRegister,
+			status:     http.StatusCreated,
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name:       "registration without a challenge",
+			action:     botcheck.ActionRegister,
+			checkErr:   botcheck.ErrChallengeRequired,
+			wantStatus: http.StatusPreconditionRequired,
+		},
+		{
+			name:       "invalid challenge response",
+			action:     botcheck.ActionRegister,
+			checkErr:   botcheck.ErrChallengeFailed,
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "provider unavailable",
+			action:     botcheck.ActionLogin,
+			checkErr:   errors.Join(botcheck.ErrBotCheckTechError, errors.New("timeout")),
+			wantStatus: http.StatusServiceUnavailable,
+		},
+		{
+			name:         "failed login",
+			action:       botcheck.ActionLogin,
+			status:       http.StatusUnauthorized,
+			wantStatus:   http.StatusUnauthorized,
+			wantFailures: 1,
+		},
+		{
+			name:       "failed registration",
+			action:     botcheck.ActionRegister,
+			status:     http.StatusUnauthorized,
+			wantStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			checker := &mockHumanChecker{err: tt.checkErr}
+			router := gin.New()
+			router.Use(RealIP(nil), HumanCheck(checker, tt.action))
+			router.POST("/test", func(c *gin.Context) { c.Status(tt.status) })
+
+			req := httptest.NewRequest(http.MethodPost, "/test", nil)
+			req.RemoteAddr = "203.0.113.7:4321"
+			req.Header.Set(consts.HeaderXChallengeResponse, "solution")
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.Equal(t, botcheck.CheckParams{
+				IP:       netip.MustParseAddr("203.0.113.7"),
+				Action:   tt.action,
+				Response: "solution",
+			}, checker.params)
+			assert.Equal(t, tt.wantFailures, checker.failures)
+		})
+	}
+}
+
+func TestHumanCheck_NilChecker(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(HumanCheck(nil, botcheck.ActionRegister))
+	router.POST("/test", func(c *gin.Context) { c.Status(http.StatusCreated) })
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/test", nil))
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+}
@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/response"
+	"github.com/gin-gonic/gin"
+)
+
+// LicenseChecker defines the interface for enterprise license services.
+type LicenseChecker interface {
+	// Allows reports whether the enterprise feature is available.
+	Allows(ctx context.Context, feature string) bool
+}
+
+// RequireLicense creates middleware that answers requests to an enterprise feature with 403 Forbidden
+// unless a valid license covers it. A nil checker leaves the feature available.
+func RequireLicense(checker LicenseChecker, feature string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if checker != nil && !checker.Allows(c.Request.Context(), feature) {
+			c.JSON(http.StatusForbidden, response.Error{
+				Messages: []string{"Feature " + feature + " requires an enterprise license"},
+			})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockLicenseChecker implements LicenseChecker for testing.
+type mockLicenseChecker struct {
+	allowed map[string]bool
+}
+
+func (m *mockLicenseChecker) Allows(_ context.Context, feature string) bool {
+	return m.allowed[feature]
+}
+
+func TestRequireLicense(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		checker    *mockLicenseChecker
+		name       string
+		wantStatus int
+	}{
+		{
+			name:       "no checker",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "licensed",
+			checker:    &mockLicenseChecker{allowed: map[string]bool{"scim": true}},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "not licensed",
+			checker:    &mockLicenseChecker{allowed: map[string]bool{"sso": true}},
+			wantStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var checker LicenseChecker
+			if tt.checker != nil {
+				checker = tt.checker
+			}
+
+			router := gin.New()
+			router.GET("/enterprise",
+				RequireLicense(checker, "scim"),
+				func(c *gin.Context) { c.Status(http.StatusOK) },
+			)
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/enterprise", nil))
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			if tt.wantStatus == http.StatusForbidden {
+				assert.Contains(t, rec.Body.String(), "requires an enterprise license")
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/response"
+	"github.com/gin-gonic/gin"
+)
+
+// maintenanceMessage is returned for mutating requests rejected in maintenance mode.
+const maintenanceMessage = "The service is in read-only maintenance mode. Please try again later"
+
+// MaintenanceMode defines the interface for reporting the read-only maintenance mode.
+type MaintenanceMode interface {
+	// Enabled reports whether mutating requests must be rejected.
+	Enabled() bool
+}
+
+// Maintenance creates middleware that rejects mutating requests with 503 Service Unavailable while
+// maintenance mode is on. GET, HEAD, OPTIONS and WebDAV PROPFIND requests are always served, as are routes whose
+// path starts with one of exemptPrefixes. A nil mode disables the middleware.
+func Maintenance(mode MaintenanceMode, exemptPrefixes ...string) gin.HandlerFunc {
+	if mode == nil {
+		return func(c *gin.Context) { c.Next() }
+	}
+	return func(c *gin.Context) {
+		if !mode.Enabled() || isSafeMethod(c.Request.Method) || hasAnyPrefix(c.FullPath(), exemptPrefixes) {
+			c.Next()
+			return
+		}
+
+		c.JSON(http.StatusServiceUnavailable, response.Error{Messages: []string{maintenanceMessage}})
+		c.Abort()
+	}
+}
+
+// methodPropfind is the WebDAV method listing the properties of files and folders.
+const methodPropfind = "PROPFIND"
+
+// isSafeMethod reports whether the HTTP method does not modify server state.
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, methodPropfind:
+		return true
+	default:
+		return false
+	}
+}
+
+// hasAnyPrefix reports whether path starts with one of the prefixes.
+func hasAnyPrefix(path string, prefixes []string) bool {
+	for _, p := range prefixes {
+		if strings.HasPrefix(path, p) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// staticMaintenanceMode reports a fixed maintenance mode.
+type staticMaintenanceMode bool
+
+func (m staticMaintenanceMode) Enabled() bool {
+	return bool(m)
+}
+
+func TestMaintenance(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		mode       MaintenanceMode
+		name       string
+		method     string
+		path       string
+		wantStatus int
+	}{
+		{name: "disabled middleware", mode: nil, method: http.MethodPost, path: "/items", wantStatus: http.StatusOK},
+		{
+			name: "normal mode", mode: staticMaintenanceMode(false),
+			method: http.MethodPost, path: "/items", wantStatus: http.StatusOK,
+		},
+		{
+			name: "read in maintenance", mode: staticMaintenanceMode(true),
+			method: http.MethodGet, path: "/items", wantStatus: http.StatusOK,
+		},
+		{
+			name: "webdav listing in maintenance", mode: staticMaintenanceMode(true),
+			method: "PROPFIND", path: "/items", wantStatus: http.StatusOK,
+		},
+		{
+			name: "write in maintenance", mode: staticMaintenanceMode(true),
+			method: http.MethodPost, path: "/items", wantStatus: http.StatusServiceUnavailable,
+		},
+		{
+			name: "delete in maintenance", mode: staticMaintenanceMode(true),
+			method: http.MethodDelete, path: "/items", wantStatus: http.StatusServiceUnavailable,
+		},
+		{
+			name: "exempt route in maintenance", mode: staticMaintenanceMode(true),
+			method: http.MethodPut, path: "/admin/maintenance", wantStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			router := gin.New()
+			router.Use(Maintenance(tt.mode, "/admin/"))
+			handler := func(c *gin.Context) { c.Status(http.StatusOK) }
+			router.Handle(tt.method, "/items", handler)
+			router.Handle(tt.method, "/admin/maintenance", handler)
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			if tt.wantStatus == http.StatusServiceUnavailable {
+				assert.Contains(t, rec.Body.String(), "maintenance mode")
+			}
+		})
+	}
+}
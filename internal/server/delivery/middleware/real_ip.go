@@ -0,0 +1,95 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/clientinfo"
+	"github.com/gin-gonic/gin"
+)
+
+// Headers set by reverse proxies to forward the original client address.
+const (
+	headerXForwardedFor = "X-Forwarded-For"
+	headerXRealIP       = "X-Real-Ip"
+)
+
+// RealIP creates middleware that resolves the client IP address and stores it in the request context.
+// Forwarding headers are only honored when the connecting peer belongs to one of the trusted proxy
+// networks; otherwise the peer address is used, so clients cannot spoof their address.
+func RealIP(trustedProxies []netip.Prefix) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if ip := resolveClientIP(c.Request, trustedProxies); ip.IsValid() {
+			c.Request = c.Request.WithContext(clientinfo.WithIP(c.Request.Context(), ip))
+		}
+		c.Next()
+	}
+}
+
+// resolveClientIP determines the client address of the request.
+// X-Forwarded-For is walked from the right, skipping trusted proxy hops, and the first untrusted
+// address is the client. X-Real-IP is used when X-Forwarded-For is absent.
+func resolveClientIP(r *http.Request, trusted []netip.Prefix) netip.Addr {
+	peer := parseAddr(r.RemoteAddr)
+	if !peer.IsValid() || !isTrusted(peer, trusted) {
+		return peer
+	}
+
+	if hops := forwardedHops(r.Header.Values(headerXForwardedFor)); len(hops) > 0 {
+		client := peer
+		for i := len(hops) - 1; i >= 0; i-- {
+			hop, err := netip.ParseAddr(hops[i])
+			if err != nil {
+				return client
+			}
+			client = hop.Unmap()
+			if !isTrusted(client, trusted) {
+				break
+			}
+		}
+		return client
+	}
+
+	if ip, err := netip.ParseAddr(strings.TrimSpace(r.Header.Get(headerXRealIP))); err == nil {
+		return ip.Unmap()
+	}
+	return peer
+}
+
+// forwardedHops splits X-Forwarded-For header values into individual addresses.
+func forwardedHops(values []string) []string {
+	hops := make([]string, 0, len(values))
+	for _, v := range values {
+		for _, hop := range strings.Split(v, ",") {
+			if hop = strings.TrimSpace(hop); hop != "" {
+				hops = append(hops, hop)
+			}
+		}
+	}
+	return hops
+}
+
+// parseAddr parses the host part of a "host:port" remote address.
+func parseAddr(remoteAddr string) netip.Addr {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return netip.Addr{}
+	}
+	return ip.Unmap()
+}
+
+// isTrusted reports whether ip belongs to one of the trusted networks.
+func isTrusted(ip netip.Addr, trusted []netip.Prefix) bool {
+	for _, p := range trusted {
+		if p.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,114 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/clientinfo"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRealIP(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+
+	trusted := []netip.Prefix{
+		netip.MustParsePrefix("10.0.0.0/8"),
+		netip.MustParsePrefix("::1/128"),
+	}
+
+	tests := []struct {
+		headers    map[string][]string
+		name       string
+		remoteAddr string
+		want       string
+	}{
+		{
+			name:       "untrusted peer ignores forwarding headers",
+			remoteAddr: "198.51.100.10:4321",
+			headers: map[string][]string{
+				"X-Forwarded-For": {"203.0.113.7"},
+				"X-Real-Ip":       {"203.0.113.8"},
+			},
+			want: "198.51.100.10",
+		},
+		{
+			name:       "trusted peer without headers",
+			remoteAddr: "10.1.2.3:4321",
+			want:       "10.1.2.3",
+		},
+		{
+			name:       "trusted peer with forwarded client",
+			remoteAddr: "10.1.2.3:4321",
+			headers:    map[string][]string{"X-Forwarded-For": {"203.0.113.7"}},
+			want:       "203.0.113.7",
+		},
+		{
+			name:       "spoofed leftmost entry is skipped",
+			remoteAddr: "10.1.2.3:4321",
+			headers:    map[string][]string{"X-Forwarded-For": {"1.1.1.1, 203.0.113.7, 10.0.0.5"}},
+			want:       "203.0.113.7",
+		},
+		{
+			name:       "multiple header lines",
+			remoteAddr: "10.1.2.3:4321",
+			headers:    map[string][]string{"X-Forwarded-For": {"203.0.113.7", "10.0.0.5"}},
+			want:       "203.0.113.7",
+		},
+		{
+			name:       "all hops trusted",
+			remoteAddr: "10.1.2.3:4321",
+			headers:    map[string][]string{"X-Forwarded-For": {"10.0.0.9, 10.0.0.5"}},
+			want:       "10.0.0.9",
+		},
+		{
+			name:       "malformed hop stops the walk",
+			remoteAddr: "10.1.2.3:4321",
+			headers:    map[string][]string{"X-Forwarded-For": {"203.0.113.7, garbage, 10.0.0.5"}},
+			want:       "10.0.0.5",
+		},
+		{
+			name:       "real ip header",
+			remoteAddr: "[::1]:4321",
+			headers:    map[string][]string{"X-Real-Ip": {"2001:db8::1"}},
+			want:       "2001:db8::1",
+		},
+		{
+			name:       "invalid real ip header",
+			remoteAddr: "10.1.2.3:4321",
+			headers:    map[string][]string{"X-Real-Ip": {"unknown"}},
+			want:       "10.1.2.3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var got string
+			router := gin.New()
+			router.Use(RealIP(trusted))
+			router.GET("/test", func(c *gin.Context) {
+				if ip, ok := clientinfo.IP(c.Request.Context()); ok {
+					got = ip.String()
+				}
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for k, vals := range tt.headers {
+				for _, v := range vals {
+					req.Header.Add(k, v)
+				}
+			}
+			router.ServeHTTP(httptest.NewRecorder(), req)
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
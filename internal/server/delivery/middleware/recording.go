@@ -0,0 +1,140 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	recordingApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/recording"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/consts"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// RequestRecorder defines the interface for capturing requests selected by debugging recordings.
+type RequestRecorder interface {
+	// Wants reports whether a request to the path may be recorded.
+	Wants(ctx context.Context, path string) (bool, error)
+	// BodyLimit returns how many bytes of every request and response body are captured.
+	BodyLimit() int
+	// Record sanitizes and stores the handled request and its response.
+	Record(ctx context.Context, params recordingApp.RecordParams) error
+}
+
+// Recording creates middleware that captures the requests selected by active debugging recordings together with
+// their responses. Bodies are captured up to the limit of the recorder without delaying streamed uploads and
+// downloads, and the user is taken from the context after the handlers run, so the middleware works for routes
+// with any authentication. Routes whose path starts with one of exemptPrefixes are never recorded.
+// A nil recorder disables the middleware.
+func Recording(recorder RequestRecorder, logger *zap.SugaredLogger, exemptPrefixes ...string) gin.HandlerFunc {
+	if recorder == nil {
+		return func(c *gin.Context) { c.Next() }
+	}
+	return func(c *gin.Context) {
+		if hasAnyPrefix(c.FullPath(), exemptPrefixes) {
+			c.Next()
+			return
+		}
+		wants, err := recorder.Wants(c.Request.Context(), c.Request.URL.Path)
+		if err != nil {
+			logger.Errorf("Failed to check active recordings: %v", err)
+		}
+		if !wants {
+			c.Next()
+			return
+		}
+
+		startedAt := time.Now()
+		limit := recorder.BodyLimit()
+		requestBody, requestTruncated := captureRequestBody(c, limit)
+		writer := &recordingWriter{ResponseWriter: c.Writer, limit: limit}
+		c.Writer = writer
+
+		c.Next()
+
+		value, _ := c.Get(consts.CtxKeyUserID)
+		userID, _ := value.(uuid.UUID)
+		// The request context is canceled once the client disconnects, which must not lose the capture.
+		ctx := context.WithoutCancel(c.Request.Context())
+		if err := recorder.Record(ctx, recordingApp.RecordParams{
+			StartedAt:         startedAt,
+			RequestHeader:     c.Request.Header,
+			ResponseHeader:    writer.Header(),
+			RequestBody:       requestBody,
+			ResponseBody:      writer.body.Bytes(),
+			UserID:            userID,
+			RequestID:         c.Request.Header.Get(consts.HeaderXRequestID),
+			Method:            c.Request.Method,
+			Path:              c.Request.URL.Path,
+			Route:             c.FullPath(),
+			Query:             c.Request.URL.RawQuery,
+			Duration:          time.Since(startedAt),
+			Status:            writer.Status(),
+			RequestTruncated:  requestTruncated,
+			ResponseTruncated: writer.truncated,
+		}); err != nil {
+			logger.Errorf("Failed to record request: %v", err)
+		}
+	}
+}
+
+// captureRequestBody reads up to limit bytes of the request body and puts them back in front of the rest of it,
+// so the handler reads the complete body. Reports whether the body is longer than the captured part.
+func captureRequestBody(c *gin.Context, limit int) ([]byte, bool) {
+	if c.Request.Body == nil || c.Request.Body == http.NoBody || limit <= 0 {
+		return nil, c.Request.ContentLength > 0
+	}
+	// One byte over the limit tells whether the body was cut off.
+	head, err := io.ReadAll(io.LimitReader(c.Request.Body, int64(limit)+1))
+	c.Request.Body = readCloser{Reader: io.MultiReader(bytes.NewReader(head), c.Request.Body), Closer: c.Request.Body}
+	if err != nil {
+		return nil, true
+	}
+	if len(head) > limit {
+		return head[:limit], true
+	}
+	return head, false
+}
+
+// readCloser combines the reader of a restored request body with the closer of the original one.
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// recordingWriter captures up to limit bytes of the response body while writing it.
+type recordingWriter struct {
+	// ResponseWriter is the wrapped gin response writer.
+	gin.ResponseWriter
+	// body holds the captured beginning of the response body.
+	body bytes.Buffer
+	// limit is how many bytes of the response body are captured.
+	limit int
+	// truncated reports whether the response body is longer than the captured part.
+	truncated bool
+}
+
+// Write captures and writes the response body.
+func (w *recordingWriter) Write(data []byte) (int, error) {
+	w.capture(data)
+	return w.ResponseWriter.Write(data) // nolint:wrapcheck // Transparent writer wrapper
+}
+
+// WriteString captures and writes the response body.
+func (w *recordingWriter) WriteString(s string) (int, error) {
+	w.capture([]byte(s))
+	return w.ResponseWriter.WriteString(s) // nolint:wrapcheck // Transparent writer wrapper
+}
+
+// capture keeps the part of data that fits in the limit.
+func (w *recordingWriter) capture(data []byte) {
+	room := w.limit - w.body.Len()
+	if len(data) > room {
+		w.truncated = true
+		data = data[:max(room, 0)]
+	}
+	w.body.Write(data)
+}
@@ -0,0 +1,166 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	recordingApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/recording"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/consts"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// mockRequestRecorder implements RequestRecorder for testing.
+type mockRequestRecorder struct {
+	wantsErr error
+	recorded []recordingApp.RecordParams
+	limit    int
+	wants    bool
+}
+
+func (m *mockRequestRecorder) Wants(context.Context, string) (bool, error) {
+	return m.wants, m.wantsErr
+}
+
+func (m *mockRequestRecorder) BodyLimit() int {
+	return m.limit
+}
+
+func (m *mockRequestRecorder) Record(_ context.Context, params recordingApp.RecordParams) error {
+	m.recorded = append(m.recorded, params)
+	return nil
+}
+
+func TestRecording(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+	userID := uuid.New()
+
+	tests := []struct {
+		wantsErr          error
+		name              string
+		path              string
+		body              string
+		wantRequestBody   string
+		wantResponseBody  string
+		limit             int
+		wants             bool
+		wantRecorded      bool
+		wantReqTruncated  bool
+		wantRespTruncated bool
+	}{
+		{
+			name:             "request recorded",
+			path:             "/api/items/notes",
+			body:             `{"id":"1"}`,
+			limit:            64,
+			wants:            true,
+			wantRecorded:     true,
+			wantRequestBody:  `{"id":"1"}`,
+			wantResponseBody: `{"id":"1","text":"secret"}`,
+		},
+		{
+			name:              "bodies cut off at the limit",
+			path:              "/api/items/notes",
+			body:              `{"id":"1"}`,
+			limit:             4,
+			wants:             true,
+			wantRecorded:      true,
+			wantRequestBody:   `{"id`,
+			wantResponseBody:  `{"id`,
+			wantReqTruncated:  true,
+			wantRespTruncated: true,
+		},
+		{
+			name:  "request not selected",
+			path:  "/api/items/notes",
+			body:  `{"id":"1"}`,
+			limit: 64,
+		},
+		{
+			name:     "recordings unavailable",
+			path:     "/api/items/notes",
+			body:     `{"id":"1"}`,
+			limit:    64,
+			wantsErr: errors.New("database unavailable"),
+		},
+		{
+			name:  "exempt route",
+			path:  "/api/admin/recordings",
+			body:  `{"id":"1"}`,
+			limit: 64,
+			wants: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			recorder := &mockRequestRecorder{wantsErr: tt.wantsErr, limit: tt.limit, wants: tt.wants}
+			// handled holds the request body read by the handler.
+			var handled string
+			handler := func(c *gin.Context) {
+				body, err := io.ReadAll(c.Request.Body)
+				require.NoError(t, err)
+				handled = string(body)
+				c.Set(consts.CtxKeyUserID, userID)
+				c.Header("Content-Type", "application/json")
+				c.String(http.StatusCreated, `{"id":"1","text":"secret"}`)
+			}
+			router := gin.New()
+			router.Use(Recording(recorder, zap.NewNop().Sugar(), "/api/admin/"))
+			router.POST("/api/items/notes", handler)
+			router.POST("/api/admin/recordings", handler)
+
+			req := httptest.NewRequest(http.MethodPost, tt.path+"?type=note", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.body, handled, "the handler must read the complete body")
+			assert.Equal(t, `{"id":"1","text":"secret"}`, w.Body.String())
+			if !tt.wantRecorded {
+				assert.Empty(t, recorder.recorded)
+				return
+			}
+			require.Len(t, recorder.recorded, 1)
+			got := recorder.recorded[0]
+			assert.Equal(t, userID, got.UserID)
+			assert.Equal(t, http.MethodPost, got.Method)
+			assert.Equal(t, tt.path, got.Path)
+			assert.Equal(t, tt.path, got.Route)
+			assert.Equal(t, "type=note", got.Query)
+			assert.Equal(t, http.StatusCreated, got.Status)
+			assert.Equal(t, "application/json", got.RequestHeader.Get("Content-Type"))
+			assert.Equal(t, "application/json", got.ResponseHeader.Get("Content-Type"))
+			assert.Equal(t, tt.wantRequestBody, string(got.RequestBody))
+			assert.Equal(t, tt.wantResponseBody, string(got.ResponseBody))
+			assert.Equal(t, tt.wantReqTruncated, got.RequestTruncated)
+			assert.Equal(t, tt.wantRespTruncated, got.ResponseTruncated)
+		})
+	}
+}
+
+func TestRecording_NilRecorder(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Recording(nil, zap.NewNop().Sugar()))
+	router.GET("/api/items/notes", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/items/notes", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
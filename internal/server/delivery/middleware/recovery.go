@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"runtime/debug"
+	"syscall"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// Recovery creates middleware that recovers from handler panics and answers with 500 Internal Server Error,
+// as gin.Recovery does. A panic with http.ErrAbortHandler is passed on to the server instead, which aborts the
+// connection without completing the response, so a handler streaming a response whose status is already sent
+// can make the client tell the response is truncated.
+func Recovery(logger *zap.SugaredLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			rec := recover()
+			if rec == nil {
+				return
+			}
+			err, _ := rec.(error)
+			switch {
+			case errors.Is(err, http.ErrAbortHandler):
+				panic(rec)
+			case errors.Is(err, syscall.EPIPE) || errors.Is(err, syscall.ECONNRESET):
+				// The connection is gone, so no status can be written to it.
+				_ = c.Error(err)
+				c.Abort()
+			default:
+				logger.Errorf("Recovered from panic: %v\n%s", rec, debug.Stack())
+				c.AbortWithStatus(http.StatusInternalServerError)
+			}
+		}()
+		c.Next()
+	}
+}
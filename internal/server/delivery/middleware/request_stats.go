@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"net/http"
+
+	statsApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/stats"
+	"github.com/gin-gonic/gin"
+)
+
+// RequestStatsRecorder defines the interface for counting handled requests.
+type RequestStatsRecorder interface {
+	// Add increments the named counter by delta.
+	Add(name string, delta int64)
+}
+
+// RequestStats creates middleware that counts handled requests, the requests answered with 4xx and 5xx statuses
+// and, for routes whose path starts with one of syncPrefixes, the synchronization requests and the bytes they
+// received and sent. The counters feed the usage statistics rollups. A nil recorder disables the middleware.
+func RequestStats(recorder RequestStatsRecorder, syncPrefixes ...string) gin.HandlerFunc {
+	if recorder == nil {
+		return func(c *gin.Context) { c.Next() }
+	}
+	return func(c *gin.Context) {
+		c.Next()
+
+		recorder.Add(statsApp.MetricRequests, 1)
+		switch status := c.Writer.Status(); {
+		case status >= http.StatusInternalServerError:
+			recorder.Add(statsApp.MetricServerErrors, 1)
+		case status >= http.StatusBadRequest:
+			recorder.Add(statsApp.MetricClientErrors, 1)
+		}
+
+		if !hasAnyPrefix(c.FullPath(), syncPrefixes) {
+			return
+		}
+		recorder.Add(statsApp.MetricSyncRequests, 1)
+		recorder.Add(statsApp.MetricSyncBytes, max(c.Request.ContentLength, 0)+int64(max(c.Writer.Size(), 0)))
+	}
+}
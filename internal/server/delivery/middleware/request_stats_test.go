@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	statsApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/stats"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestStats(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		wantCounts map[string]int64
+		name       string
+		method     string
+		path       string
+		body       string
+	}{
+		{
+			name:       "successful request",
+			method:     http.MethodGet,
+			path:       "/api/items/notes",
+			wantCounts: map[string]int64{statsApp.MetricRequests: 1},
+		},
+		{
+			name:   "client error",
+			method: http.MethodGet,
+			path:   "/api/items/missing",
+			wantCounts: map[string]int64{
+				statsApp.MetricRequests:     1,
+				statsApp.MetricClientErrors: 1,
+			},
+		},
+		{
+			name:   "server error",
+			method: http.MethodGet,
+			path:   "/api/items/broken",
+			wantCounts: map[string]int64{
+				statsApp.MetricRequests:     1,
+				statsApp.MetricServerErrors: 1,
+			},
+		},
+		{
+			name:   "sync request",
+			method: http.MethodPost,
+			path:   "/api/items/sync",
+			body:   `{"notes":[]}`,
+			wantCounts: map[string]int64{
+				statsApp.MetricRequests:     1,
+				statsApp.MetricSyncRequests: 1,
+				statsApp.MetricSyncBytes:    int64(len(`{"notes":[]}`) + len("synced")),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			recorder := &countingRecorder{}
+			router := gin.New()
+			router.Use(RequestStats(recorder, "/api/items/sync"))
+			router.GET("/api/items/notes", func(c *gin.Context) { c.Status(http.StatusOK) })
+			router.GET("/api/items/broken", func(c *gin.Context) { c.Status(http.StatusInternalServerError) })
+			router.POST("/api/items/sync", func(c *gin.Context) { c.String(http.StatusOK, "synced") })
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body)))
+
+			assert.Equal(t, tt.wantCounts, recorder.counts)
+		})
+	}
+}
+
+func TestRequestStats_NilRecorder(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RequestStats(nil))
+	router.GET("/", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+}
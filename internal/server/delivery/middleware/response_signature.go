@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/crypto"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/consts"
+	"github.com/gin-gonic/gin"
+)
+
+// ResponseSignature creates middleware that signs the responses of the routes with the Ed25519 key, so clients
+// can tell that exports, manifests and recovery codes were not modified by a compromised proxy. The signature
+// is sent in the X-Response-Signature header as "t=<unix time>,key=<key ID>,v1=<hex signature>" and covers the
+// canonical response: the signing scheme, method, path with query, status code, timestamp and hex SHA-256 digest
+// of the body. The response is buffered while signing, so streamed bodies are delivered at once.
+// Routes are matched against the route pattern; a nil key disables the middleware.
+func ResponseSignature(key ed25519.PrivateKey, routes ...string) gin.HandlerFunc {
+	if key == nil {
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	keyID := crypto.Ed25519KeyID(key.Public().(ed25519.PublicKey))
+	return func(c *gin.Context) {
+		if !slices.Contains(routes, c.FullPath()) {
+			c.Next()
+			return
+		}
+
+		original := c.Writer
+		writer := &bufferedWriter{ResponseWriter: original}
+		c.Writer = writer
+
+		c.Next()
+
+		c.Writer = original
+		timestamp := time.Now().Unix()
+		payload := canonicalResponse(
+			c.Request.Method, c.Request.URL.RequestURI(), writer.Status(), timestamp, writer.body.Bytes(),
+		)
+		signature := ed25519.Sign(key, payload)
+		original.Header().Set(consts.HeaderXResponseSignature, "t="+strconv.FormatInt(timestamp, 10)+
+			",key="+keyID+","+signatureVersion+"="+hex.EncodeToString(signature))
+		original.WriteHeaderNow()
+		_, _ = original.Write(writer.body.Bytes())
+	}
+}
+
+// canonicalResponse builds the signed representation of a response: the signing scheme, method, path with
+// query, status code, timestamp and hex SHA-256 digest of the body, separated by newlines.
+func canonicalResponse(method, requestURI string, status int, timestamp int64, body []byte) []byte {
+	digest := sha256.Sum256(body)
+	return []byte(strings.Join([]string{
+		signatureVersion,
+		method,
+		requestURI,
+		strconv.Itoa(status),
+		strconv.FormatInt(timestamp, 10),
+		hex.EncodeToString(digest[:]),
+	}, "\n"))
+}
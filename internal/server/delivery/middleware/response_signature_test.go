@@ -0,0 +1,116 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/crypto"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/consts"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResponseSignature(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+
+	key := ed25519.NewKeyFromSeed(bytes.Repeat([]byte{0x05}, ed25519.SeedSize))
+	public := key.Public().(ed25519.PublicKey)
+
+	tests := []struct {
+		key        ed25519.PrivateKey
+		name       string
+		path       string
+		wantBody   string
+		wantStatus int
+		wantSigned bool
+	}{
+		{
+			name:       "no key",
+			path:       "/export?format=json",
+			wantStatus: http.StatusOK,
+			wantBody:   "vault",
+		},
+		{
+			name:       "signed route",
+			key:        key,
+			path:       "/export?format=json",
+			wantStatus: http.StatusOK,
+			wantBody:   "vault",
+			wantSigned: true,
+		},
+		{
+			name:       "signed error",
+			key:        key,
+			path:       "/export?fail=1",
+			wantStatus: http.StatusConflict,
+			wantBody:   "conflict",
+			wantSigned: true,
+		},
+		{
+			name:       "other route",
+			key:        key,
+			path:       "/notes",
+			wantStatus: http.StatusOK,
+			wantBody:   "notes",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			router := gin.New()
+			router.Use(ResponseSignature(tt.key, "/export"))
+			router.GET("/export", func(c *gin.Context) {
+				if c.Query("fail") != "" {
+					c.String(http.StatusConflict, "conflict")
+					return
+				}
+				_, _ = c.Writer.WriteString("va")
+				c.Writer.Flush()
+				_, _ = c.Writer.WriteString("ult")
+			})
+			router.GET("/notes", func(c *gin.Context) {
+				c.String(http.StatusOK, "notes")
+			})
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.Equal(t, tt.wantBody, w.Body.String())
+			header := w.Header().Get(consts.HeaderXResponseSignature)
+			if !tt.wantSigned {
+				assert.Empty(t, header)
+				return
+			}
+
+			// parts holds the fields of the signature header.
+			parts := map[string]string{}
+			for _, kv := range strings.Split(header, ",") {
+				k, v, _ := strings.Cut(kv, "=")
+				parts[k] = v
+			}
+			assert.Equal(t, crypto.Ed25519KeyID(public), parts["key"])
+			ts, err := strconv.ParseInt(parts["t"], 10, 64)
+			require.NoError(t, err)
+			signature, err := hex.DecodeString(parts["v1"])
+			require.NoError(t, err)
+
+			payload := canonicalResponse(http.MethodGet, tt.path, tt.wantStatus, ts, []byte(tt.wantBody))
+			assert.True(t, ed25519.Verify(public, payload, signature))
+			tampered := canonicalResponse(http.MethodGet, tt.path, tt.wantStatus, ts, []byte("tampered"))
+			assert.False(t, ed25519.Verify(public, tampered, signature))
+		})
+	}
+}
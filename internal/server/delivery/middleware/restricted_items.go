@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/accesscontrol"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/clientinfo"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/consts"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/response"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// RestrictedItemChecker defines the interface for the geofence of items tagged restricted.
+type RestrictedItemChecker interface {
+	// CheckRestricted verifies that the restricted items of the user may be revealed from the client address.
+	CheckRestricted(ctx context.Context, params accesscontrol.RestrictedParams) error
+}
+
+// RestrictedItems creates middleware that rejects access to items tagged restricted from outside the configured
+// geofence with 403 Forbidden and the restricted_location error code. Requests naming an item by id are checked
+// against that item; other reading requests, such as listings and sync pulls, are checked against every
+// restricted item of the user. Must run after AuthWithJWT. A nil checker disables the middleware.
+func RestrictedItems(checker RestrictedItemChecker) gin.HandlerFunc {
+	if checker == nil {
+		return func(c *gin.Context) { c.Next() }
+	}
+	return func(c *gin.Context) {
+		var itemID uuid.UUID
+		if raw := c.Param("id"); raw != "" {
+			id, err := uuid.Parse(raw)
+			if err != nil {
+				// Handlers reject malformed ids themselves.
+				c.Next()
+				return
+			}
+			itemID = id
+		} else if !isRevealMethod(c.Request.Method) {
+			c.Next()
+			return
+		}
+
+		ctx := c.Request.Context()
+		ip, _ := clientinfo.IP(ctx)
+		value, _ := c.Get(consts.CtxKeyUserID)
+		userID, _ := value.(uuid.UUID)
+
+		err := checker.CheckRestricted(ctx, accesscontrol.RestrictedParams{IP: ip, UserID: userID, ItemID: itemID})
+		if err != nil {
+			code, msgs := handleError(err, c)
+			resp := response.Error{Messages: msgs}
+			if errors.Is(err, accesscontrol.ErrRestrictedLocation) {
+				resp.Code = response.CodeRestrictedLocation
+			}
+			c.JSON(code, resp)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
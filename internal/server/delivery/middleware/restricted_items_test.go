@@ -0,0 +1,164 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/accesscontrol"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/consts"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/response"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockRestrictedItemChecker returns a fixed error and records the checked parameters.
+type mockRestrictedItemChecker struct {
+	err    error
+	params []accesscontrol.RestrictedParams
+}
+
+func (m *mockRestrictedItemChecker) CheckRestricted(_ context.Context, params accesscontrol.RestrictedParams) error {
+	m.params = append(m.params, params)
+	return m.err
+}
+
+func TestRestrictedItems(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+	userID, itemID := uuid.New(), uuid.New()
+
+	tests := []struct {
+		checker    *mockRestrictedItemChecker
+		name       string
+		method     string
+		path       string
+		wantCode   string
+		wantItemID uuid.UUID
+		wantStatus int
+		wantChecks int
+	}{
+		{
+			name:       "item inside geofence",
+			checker:    &mockRestrictedItemChecker{},
+			method:     http.MethodGet,
+			path:       "/items/notes/" + itemID.String(),
+			wantItemID: itemID,
+			wantStatus: http.StatusOK,
+			wantChecks: 1,
+		},
+		{
+			name:       "item outside geofence",
+			checker:    &mockRestrictedItemChecker{err: accesscontrol.ErrRestrictedLocation},
+			method:     http.MethodGet,
+			path:       "/items/notes/" + itemID.String(),
+			wantItemID: itemID,
+			wantStatus: http.StatusForbidden,
+			wantCode:   response.CodeRestrictedLocation,
+			wantChecks: 1,
+		},
+		{
+			name:       "item change outside geofence",
+			checker:    &mockRestrictedItemChecker{err: accesscontrol.ErrRestrictedLocation},
+			method:     http.MethodPut,
+			path:       "/items/notes/" + itemID.String(),
+			wantItemID: itemID,
+			wantStatus: http.StatusForbidden,
+			wantCode:   response.CodeRestrictedLocation,
+			wantChecks: 1,
+		},
+		{
+			name:       "listing outside geofence",
+			checker:    &mockRestrictedItemChecker{err: accesscontrol.ErrRestrictedLocation},
+			method:     http.MethodGet,
+			path:       "/items/notes",
+			wantStatus: http.StatusForbidden,
+			wantCode:   response.CodeRestrictedLocation,
+			wantChecks: 1,
+		},
+		{
+			name:       "creation outside geofence",
+			checker:    &mockRestrictedItemChecker{err: accesscontrol.ErrRestrictedLocation},
+			method:     http.MethodPost,
+			path:       "/items/notes",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "malformed id",
+			checker:    &mockRestrictedItemChecker{err: accesscontrol.ErrRestrictedLocation},
+			method:     http.MethodGet,
+			path:       "/items/notes/not-a-uuid",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "checker failure",
+			checker: &mockRestrictedItemChecker{
+				err: errors.Join(accesscontrol.ErrAccessControlTechError, errors.New("db down")),
+			},
+			method:     http.MethodGet,
+			path:       "/items/notes",
+			wantStatus: http.StatusInternalServerError,
+			wantChecks: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			router := gin.New()
+			router.Use(RealIP(nil))
+			router.Use(func(c *gin.Context) {
+				c.Set(consts.CtxKeyUserID, userID)
+				c.Next()
+			})
+			router.Use(RestrictedItems(tt.checker))
+			ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+			router.Handle(tt.method, "/items/notes", ok)
+			router.Handle(tt.method, "/items/notes/:id", ok)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.RemoteAddr = "203.0.113.7:4321"
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			require.Len(t, tt.checker.params, tt.wantChecks)
+			if tt.wantChecks != 0 {
+				assert.Equal(t, accesscontrol.RestrictedParams{
+					IP:     netip.MustParseAddr("203.0.113.7"),
+					UserID: userID,
+					ItemID: tt.wantItemID,
+				}, tt.checker.params[0])
+			}
+			if tt.wantStatus == http.StatusOK {
+				return
+			}
+			var got response.Error
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+			assert.Equal(t, tt.wantCode, got.Code)
+			assert.NotEmpty(t, got.Messages)
+		})
+	}
+}
+
+func TestRestrictedItems_Disabled(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RestrictedItems(nil))
+	router.GET("/items/notes", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/items/notes", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
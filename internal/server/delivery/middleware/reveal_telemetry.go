@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/itemaccess"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/clientinfo"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/consts"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Headers reporting the earlier reveals of an item in its detail responses.
+const (
+	// headerRevealCount carries the number of earlier reveals of the item.
+	headerRevealCount = "X-Item-Reveal-Count"
+	// headerLastRevealedAt carries the RFC 3339 moment of the latest earlier reveal of the item.
+	headerLastRevealedAt = "X-Item-Last-Revealed-At"
+)
+
+// ItemAccessRecorder defines the interface for the access history of vault items.
+type ItemAccessRecorder interface {
+	// Record stores a reveal of an item in its access history.
+	Record(ctx context.Context, params itemaccess.RecordParams) error
+	// History retrieves the access history of an item.
+	History(ctx context.Context, params itemaccess.HistoryParams) (*itemaccess.History, error)
+}
+
+// RevealTelemetry creates middleware for item detail routes that records every successful reveal of the item
+// named by id in its access history. Before the handler runs, the X-Item-Reveal-Count and
+// X-Item-Last-Revealed-At headers report the earlier reveals of the item, so users spot unexpected access
+// right in the detail response. Telemetry is best effort and never fails the reveal. Must run after
+// AuthWithJWT. A nil recorder disables the middleware.
+func RevealTelemetry(recorder ItemAccessRecorder) gin.HandlerFunc {
+	if recorder == nil {
+		return func(c *gin.Context) { c.Next() }
+	}
+	return func(c *gin.Context) {
+		value, _ := c.Get(consts.CtxKeyUserID)
+		userID, ok := value.(uuid.UUID)
+		itemID, err := uuid.Parse(c.Param("id"))
+		if !ok || err != nil {
+			// Handlers reject malformed ids themselves.
+			c.Next()
+			return
+		}
+
+		ctx := c.Request.Context()
+		history, err := recorder.History(ctx, itemaccess.HistoryParams{ItemID: itemID, UserID: userID})
+		if err == nil {
+			c.Header(headerRevealCount, strconv.Itoa(history.Count))
+			if !history.LastRevealedAt.IsZero() {
+				c.Header(headerLastRevealedAt, history.LastRevealedAt.UTC().Format(time.RFC3339))
+			}
+		}
+
+		c.Next()
+
+		if c.Writer.Status() != http.StatusOK {
+			return
+		}
+		ip, _ := clientinfo.IP(ctx)
+		_ = recorder.Record(context.WithoutCancel(ctx), itemaccess.RecordParams{
+			IP:        ip,
+			UserAgent: c.Request.UserAgent(),
+			ItemID:    itemID,
+			UserID:    userID,
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/itemaccess"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/consts"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockItemAccessRecorder returns a fixed access history and records the reveals.
+type mockItemAccessRecorder struct {
+	historyErr error
+	history    *itemaccess.History
+	recorded   []itemaccess.RecordParams
+}
+
+func (m *mockItemAccessRecorder) Record(_ context.Context, params itemaccess.RecordParams) error {
+	m.recorded = append(m.recorded, params)
+	return nil
+}
+
+func (m *mockItemAccessRecorder) History(context.Context, itemaccess.HistoryParams) (*itemaccess.History, error) {
+	return m.history, m.historyErr
+}
+
+func TestRevealTelemetry(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+	userID, itemID := uuid.New(), uuid.New()
+	lastRevealedAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		recorder     *mockItemAccessRecorder
+		name         string
+		path         string
+		wantCount    string
+		wantLast     string
+		status       int
+		wantRecorded bool
+	}{
+		{
+			name:         "revealed before",
+			recorder:     &mockItemAccessRecorder{history: &itemaccess.History{Count: 2, LastRevealedAt: lastRevealedAt}},
+			path:         "/items/notes/" + itemID.String(),
+			status:       http.StatusOK,
+			wantCount:    "2",
+			wantLast:     "2026-10-01T12:00:00Z",
+			wantRecorded: true,
+		},
+		{
+			name:         "first reveal",
+			recorder:     &mockItemAccessRecorder{history: &itemaccess.History{}},
+			path:         "/items/notes/" + itemID.String(),
+			status:       http.StatusOK,
+			wantCount:    "0",
+			wantRecorded: true,
+		},
+		{
+			name:         "history unavailable",
+			recorder:     &mockItemAccessRecorder{historyErr: errors.New("connection refused")},
+			path:         "/items/notes/" + itemID.String(),
+			status:       http.StatusOK,
+			wantRecorded: true,
+		},
+		{
+			name:      "failed reveal",
+			recorder:  &mockItemAccessRecorder{history: &itemaccess.History{}},
+			path:      "/items/notes/" + itemID.String(),
+			status:    http.StatusNotFound,
+			wantCount: "0",
+		},
+		{
+			name:     "malformed id",
+			recorder: &mockItemAccessRecorder{history: &itemaccess.History{}},
+			path:     "/items/notes/not-a-uuid",
+			status:   http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			router := gin.New()
+			router.Use(func(c *gin.Context) {
+				c.Set(consts.CtxKeyUserID, userID)
+				c.Next()
+			})
+			router.GET("/items/notes/:id", RevealTelemetry(tt.recorder), func(c *gin.Context) { c.Status(tt.status) })
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			req.Header.Set("User-Agent", "aegis-cli/1.0")
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+			assert.Equal(t, tt.wantCount, w.Header().Get(headerRevealCount))
+			assert.Equal(t, tt.wantLast, w.Header().Get(headerLastRevealedAt))
+			if !tt.wantRecorded {
+				assert.Empty(t, tt.recorder.recorded)
+				return
+			}
+			require.Len(t, tt.recorder.recorded, 1)
+			assert.Equal(t, itemaccess.RecordParams{UserAgent: "aegis-cli/1.0", ItemID: itemID, UserID: userID},
+				tt.recorder.recorded[0])
+		})
+	}
+}
+
+func TestRevealTelemetry_Disabled(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/items/notes/:id", RevealTelemetry(nil), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/items/notes/"+uuid.NewString(), nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get(headerRevealCount))
+}
@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/consttime"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/response"
+	"github.com/gin-gonic/gin"
+)
+
+// SCIMToken creates middleware that authorizes SCIM provisioning requests of the identity provider by
+// the static bearer token sent in the Authorization header. An empty token disables the SCIM API entirely.
+func SCIMToken(token string) gin.HandlerFunc {
+	if token == "" {
+		return func(c *gin.Context) {
+			c.JSON(http.StatusNotFound, response.Error{Messages: []string{"SCIM API is disabled"}})
+			c.Abort()
+		}
+	}
+
+	want := consttime.Digest(token)
+	return func(c *gin.Context) {
+		got := consttime.Digest(strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer "))
+		if !consttime.Equal(got[:], want[:]) {
+			c.JSON(http.StatusUnauthorized, response.Error{Messages: []string{"Invalid SCIM token"}})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
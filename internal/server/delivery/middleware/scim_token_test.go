@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSCIMToken(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+	const token = "0123456789abcdef0123456789abcdef"
+
+	tests := []struct {
+		name       string
+		configured string
+		header     string
+		wantStatus int
+	}{
+		{name: "valid token", configured: token, header: "Bearer " + token, wantStatus: http.StatusOK},
+		{name: "wrong token", configured: token, header: "Bearer " + token + "x", wantStatus: http.StatusUnauthorized},
+		{name: "token without scheme", configured: token, header: token, wantStatus: http.StatusOK},
+		{name: "missing token", configured: token, wantStatus: http.StatusUnauthorized},
+		{name: "empty bearer token", configured: token, header: "Bearer ", wantStatus: http.StatusUnauthorized},
+		{name: "scim api disabled", header: "Bearer anything", wantStatus: http.StatusNotFound},
+		{name: "scim api disabled without header", wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			router := gin.New()
+			router.Use(SCIMToken(tt.configured))
+			router.GET("/test", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package middleware
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SecurityHeadersConfig contains settings of the security response headers.
+type SecurityHeadersConfig struct {
+	// ContentSecurityPolicy is the policy sent with non-HTML responses (empty omits the header).
+	ContentSecurityPolicy string
+	// HTMLContentSecurityPolicy is the policy sent with HTML pages such as Swagger UI (empty omits the header).
+	HTMLContentSecurityPolicy string
+	// HSTSMaxAge specifies the Strict-Transport-Security lifetime (0 omits the header).
+	HSTSMaxAge time.Duration
+	// HSTSIncludeSubdomains determines whether the HSTS policy also covers subdomains.
+	HSTSIncludeSubdomains bool
+	// TLSEnabled determines whether the server is reached over HTTPS; HSTS is only sent in that case.
+	TLSEnabled bool
+}
+
+// SecurityHeaders creates middleware that applies security headers to every response.
+// The Content-Security-Policy is chosen by the response content type once the handler starts writing,
+// so HTML pages receive the HTML policy and all other responses the API policy.
+func SecurityHeaders(cfg SecurityHeadersConfig) gin.HandlerFunc {
+	hsts := ""
+	if cfg.TLSEnabled && cfg.HSTSMaxAge > 0 {
+		hsts = "max-age=" + strconv.Itoa(int(cfg.HSTSMaxAge.Seconds()))
+		if cfg.HSTSIncludeSubdomains {
+			hsts += "; includeSubDomains"
+		}
+	}
+
+	return func(c *gin.Context) {
+		h := c.Writer.Header()
+		h.Set("X-Content-Type-Options", "nosniff")
+		h.Set("X-Frame-Options", "DENY")
+		h.Set("Referrer-Policy", "no-referrer")
+		if hsts != "" {
+			h.Set("Strict-Transport-Security", hsts)
+		}
+
+		w := &cspWriter{
+			ResponseWriter: c.Writer,
+			apiPolicy:      cfg.ContentSecurityPolicy,
+			htmlPolicy:     cfg.HTMLContentSecurityPolicy,
+		}
+		c.Writer = w
+		c.Next()
+		// Responses without a body are written by gin after the chain returns.
+		w.applyPolicy()
+	}
+}
+
+// NoStore creates middleware that forbids caching of responses carrying secrets.
+func NoStore() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Cache-Control", "no-store")
+		c.Header("Pragma", "no-cache")
+		c.Next()
+	}
+}
+
+// cspWriter sets the Content-Security-Policy matching the response content type before headers are sent.
+type cspWriter struct {
+	// ResponseWriter is the wrapped gin response writer.
+	gin.ResponseWriter
+	// apiPolicy is the policy for non-HTML responses.
+	apiPolicy string
+	// htmlPolicy is the policy for HTML responses.
+	htmlPolicy string
+	// applied reports whether the policy header has been set.
+	applied bool
+}
+
+// applyPolicy sets the Content-Security-Policy header once, based on the current content type.
+func (w *cspWriter) applyPolicy() {
+	if w.applied || w.Written() {
+		return
+	}
+	w.applied = true
+
+	policy := w.apiPolicy
+	if strings.HasPrefix(w.Header().Get("Content-Type"), "text/html") {
+		policy = w.htmlPolicy
+	}
+	if policy != "" {
+		w.Header().Set("Content-Security-Policy", policy)
+	}
+}
+
+// WriteHeaderNow applies the policy and sends the response headers.
+func (w *cspWriter) WriteHeaderNow() {
+	w.applyPolicy()
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+// Write applies the policy and writes the response body.
+func (w *cspWriter) Write(data []byte) (int, error) {
+	w.applyPolicy()
+	return w.ResponseWriter.Write(data) // nolint:wrapcheck // Transparent writer wrapper
+}
+
+// WriteString applies the policy and writes the response body.
+func (w *cspWriter) WriteString(s string) (int, error) {
+	w.applyPolicy()
+	return w.ResponseWriter.WriteString(s) // nolint:wrapcheck // Transparent writer wrapper
+}
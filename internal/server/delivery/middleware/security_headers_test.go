@@ -0,0 +1,118 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSecurityHeaders(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+
+	const (
+		apiPolicy  = "default-src 'none'; frame-ancestors 'none'"
+		htmlPolicy = "default-src 'self'"
+	)
+	base := SecurityHeadersConfig{
+		ContentSecurityPolicy:     apiPolicy,
+		HTMLContentSecurityPolicy: htmlPolicy,
+		HSTSMaxAge:                365 * 24 * time.Hour,
+		HSTSIncludeSubdomains:     true,
+		TLSEnabled:                true,
+	}
+	noTLS := base
+	noTLS.TLSEnabled = false
+
+	tests := []struct {
+		handler     gin.HandlerFunc
+		wantHeaders map[string]string
+		name        string
+		cfg         SecurityHeadersConfig
+	}{
+		{
+			name:    "json_response_over_tls",
+			cfg:     base,
+			handler: func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{"ok": true}) },
+			wantHeaders: map[string]string{
+				"X-Content-Type-Options":    "nosniff",
+				"X-Frame-Options":           "DENY",
+				"Referrer-Policy":           "no-referrer",
+				"Strict-Transport-Security": "max-age=31536000; includeSubDomains",
+				"Content-Security-Policy":   apiPolicy,
+			},
+		},
+		{
+			name: "html_response",
+			cfg:  base,
+			handler: func(c *gin.Context) {
+				c.Data(http.StatusOK, "text/html; charset=utf-8", []byte("<html></html>"))
+			},
+			wantHeaders: map[string]string{"Content-Security-Policy": htmlPolicy},
+		},
+		{
+			name:        "empty_response",
+			cfg:         base,
+			handler:     func(c *gin.Context) { c.Status(http.StatusNoContent) },
+			wantHeaders: map[string]string{"Content-Security-Policy": apiPolicy},
+		},
+		{
+			name:    "no_hsts_without_tls",
+			cfg:     noTLS,
+			handler: func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{}) },
+			wantHeaders: map[string]string{
+				"Strict-Transport-Security": "",
+				"X-Content-Type-Options":    "nosniff",
+			},
+		},
+		{
+			name:    "policies_disabled",
+			cfg:     SecurityHeadersConfig{TLSEnabled: true},
+			handler: func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{}) },
+			wantHeaders: map[string]string{
+				"Strict-Transport-Security": "",
+				"Content-Security-Policy":   "",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			router := gin.New()
+			router.Use(SecurityHeaders(tt.cfg))
+			router.GET("/test", tt.handler)
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/test", nil))
+
+			for k, v := range tt.wantHeaders {
+				assert.Equal(t, v, rec.Header().Get(k), "header %s", k)
+			}
+		})
+	}
+}
+
+func TestNoStore(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/secret", NoStore(), func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{}) })
+	router.GET("/public", func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{}) })
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/secret", nil))
+	assert.Equal(t, "no-store", rec.Header().Get("Cache-Control"))
+	assert.Equal(t, "no-cache", rec.Header().Get("Pragma"))
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/public", nil))
+	assert.Empty(t, rec.Header().Get("Cache-Control"))
+}
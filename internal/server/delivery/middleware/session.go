@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"strings"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/consttime"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/consts"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/response"
+	"github.com/gin-gonic/gin"
+)
+
+// csrfInvalidMessage is returned when a cookie session request carries no valid CSRF token.
+const csrfInvalidMessage = "CSRF token missing or invalid"
+
+// SessionConfig contains the settings of cookie sessions of web clients.
+type SessionConfig struct {
+	// Key signs the CSRF tokens bound to the sessions.
+	Key []byte
+	// CookieName names the HttpOnly cookie carrying the access token.
+	CookieName string
+	// CSRFCookieName names the cookie carrying the CSRF token, readable by the scripts of the web client.
+	CSRFCookieName string
+	// Domain specifies the domain of the cookies; empty limits them to the host of the server.
+	Domain string
+	// SameSite restricts sending the cookies along with cross-site requests.
+	SameSite http.SameSite
+	// Secure determines whether the cookies are only sent over HTTPS.
+	Secure bool
+	// Enabled determines whether cookie sessions are accepted.
+	Enabled bool
+}
+
+// CSRFToken returns the CSRF token of the session carrying the access token. Binding the token to the session
+// keeps an attacker able to plant cookies from a sibling domain from pairing them with a CSRF token they know.
+func CSRFToken(key []byte, accessToken string) string {
+	mac := hmac.New(sha256.New, key)
+	_, _ = mac.Write([]byte(accessToken))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// CookieSession creates middleware authenticating web clients by the access token of the session cookie.
+// Requests carrying an Authorization header are left alone, so API clients are unaffected. Otherwise, when the
+// session cookie is present, requests modifying server state must repeat the CSRF token of the session
+// in the X-CSRF-Token header (double submit) and are answered with 403 Forbidden if they do not; the access
+// token is then passed on as a Bearer token, so every JWT authenticated route accepts the session.
+func CookieSession(cfg SessionConfig) gin.HandlerFunc {
+	if !cfg.Enabled {
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") != "" {
+			c.Next()
+			return
+		}
+		accessToken, err := c.Cookie(cfg.CookieName)
+		if err != nil || accessToken == "" {
+			c.Next()
+			return
+		}
+
+		if !isSafeMethod(c.Request.Method) {
+			got := c.GetHeader(consts.HeaderXCSRFToken)
+			if got == "" || !consttime.EqualString(got, CSRFToken(cfg.Key, accessToken)) {
+				c.JSON(http.StatusForbidden, response.Error{Messages: []string{csrfInvalidMessage}})
+				c.Abort()
+				return
+			}
+		}
+
+		c.Request.Header.Set("Authorization", "Bearer "+strings.TrimSpace(accessToken))
+		c.Next()
+	}
+}
@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/consts"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCSRFToken(t *testing.T) {
+	t.Parallel()
+
+	key := []byte("session-key")
+	token := CSRFToken(key, "access-token")
+
+	assert.Len(t, token, 43)
+	assert.Equal(t, token, CSRFToken(key, "access-token"))
+	assert.NotEqual(t, token, CSRFToken(key, "other-token"))
+	assert.NotEqual(t, token, CSRFToken([]byte("other-key"), "access-token"))
+}
+
+func TestCookieSession(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+
+	cfg := SessionConfig{
+		Key:            []byte("session-key"),
+		CookieName:     "avk_session",
+		CSRFCookieName: "avk_csrf",
+		Enabled:        true,
+	}
+	csrfToken := CSRFToken(cfg.Key, "access-token")
+
+	tests := []struct {
+		cfg        SessionConfig
+		name       string
+		method     string
+		cookie     string
+		header     string
+		csrfToken  string
+		wantAuth   string
+		wantStatus int
+	}{
+		{
+			name: "read with session", cfg: cfg, method: http.MethodGet, cookie: "access-token",
+			wantAuth: "Bearer access-token", wantStatus: http.StatusOK,
+		},
+		{
+			name: "write with csrf token", cfg: cfg, method: http.MethodPost, cookie: "access-token",
+			csrfToken: csrfToken, wantAuth: "Bearer access-token", wantStatus: http.StatusOK,
+		},
+		{
+			name: "write without csrf token", cfg: cfg, method: http.MethodPost, cookie: "access-token",
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name: "write with csrf token of other session", cfg: cfg, method: http.MethodDelete, cookie: "other-token",
+			csrfToken: csrfToken, wantStatus: http.StatusForbidden,
+		},
+		{
+			name: "authorization header preferred", cfg: cfg, method: http.MethodPost, cookie: "access-token",
+			header: "Bearer api-token", wantAuth: "Bearer api-token", wantStatus: http.StatusOK,
+		},
+		{name: "no session", cfg: cfg, method: http.MethodPost, wantStatus: http.StatusOK},
+		{
+			name: "sessions disabled", cfg: SessionConfig{CookieName: "avk_session"}, method: http.MethodPost,
+			cookie: "access-token", wantStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// gotAuth holds the Authorization header seen by the handler.
+			var gotAuth string
+			router := gin.New()
+			router.Use(CookieSession(tt.cfg))
+			router.Handle(tt.method, "/items", func(c *gin.Context) {
+				gotAuth = c.GetHeader("Authorization")
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(tt.method, "/items", nil)
+			if tt.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: "avk_session", Value: tt.cookie})
+			}
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			if tt.csrfToken != "" {
+				req.Header.Set(consts.HeaderXCSRFToken, tt.csrfToken)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.Equal(t, tt.wantAuth, gotAuth)
+			if tt.wantStatus == http.StatusForbidden {
+				assert.Contains(t, rec.Body.String(), csrfInvalidMessage)
+			}
+		})
+	}
+}
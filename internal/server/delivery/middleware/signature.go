@@ -0,0 +1,371 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/signingkey"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/crypto"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/consts"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/response"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/securebytes"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// signatureVersion names the signing scheme in the signature header and the canonical request.
+const signatureVersion = "v1"
+
+// DefaultSignatureMaxSkew is the signing time window used when none is configured.
+const DefaultSignatureMaxSkew = 5 * time.Minute
+
+// maxSignedBodySize limits the request bodies buffered to compute their digest.
+const maxSignedBodySize = 1 << 20
+
+// maxSpooledBodySize limits the request bodies of users, such as file uploads, digested for their signature.
+// Bodies larger than maxSignedBodySize are spooled to a temporary file instead of memory.
+const maxSpooledBodySize = 1 << 30
+
+// Messages returned for rejected signatures.
+const (
+	// signatureRequiredMessage is returned for unsigned requests that must be signed.
+	signatureRequiredMessage = "This request must be signed"
+	// signatureInvalidMessage is returned for malformed, forged, expired or replayed signatures.
+	signatureInvalidMessage = "Request signature is invalid or expired"
+)
+
+// errSignatureMalformed indicates that the signature header cannot be parsed.
+var errSignatureMalformed = errors.New("malformed request signature")
+
+// SigningKeyResolver defines the interface for resolving the request signing keys of users.
+type SigningKeyResolver interface {
+	// Enrolled reports whether the user has signing keys, and so must sign high-privilege requests.
+	Enrolled(ctx context.Context, params signingkey.EnrolledParams) (bool, error)
+	// Secret returns the key material of a signing key of the user; the caller wipes it once done.
+	Secret(ctx context.Context, params signingkey.SecretParams) ([]byte, error)
+}
+
+// RequestSignature creates middleware that requires the requests of users holding signing keys to be signed
+// with one of them, so a leaked access token alone can neither read or change the vault nor replace the keys.
+// Requests of users without keys pass unsigned. It must run after AuthWithJWT.
+// Signed bodies up to maxSpooledBodySize bytes are accepted, so file uploads can be signed as well.
+// Signatures older or newer than maxSkew, DefaultSignatureMaxSkew when zero, or seen before, are rejected.
+// A nil resolver disables the middleware.
+func RequestSignature(resolver SigningKeyResolver, maxSkew time.Duration) gin.HandlerFunc {
+	if resolver == nil {
+		return func(c *gin.Context) { c.Next() }
+	}
+	if maxSkew <= 0 {
+		maxSkew = DefaultSignatureMaxSkew
+	}
+
+	guard := newReplayGuard()
+	return func(c *gin.Context) {
+		value, _ := c.Get(consts.CtxKeyUserID)
+		userID, _ := value.(uuid.UUID)
+		ctx := c.Request.Context()
+
+		header := c.GetHeader(consts.HeaderXSignature)
+		if header == "" {
+			enrolled, err := resolver.Enrolled(ctx, signingkey.EnrolledParams{UserID: userID})
+			if err != nil {
+				abortWithServerError(c, err)
+				return
+			}
+			if enrolled {
+				abortUnauthorized(c, signatureRequiredMessage)
+				return
+			}
+			c.Next()
+			return
+		}
+
+		sig, err := parseSignature(header)
+		if err != nil {
+			abortUnauthorized(c, signatureInvalidMessage)
+			return
+		}
+		keyID, err := uuid.Parse(sig.keyID)
+		if err != nil {
+			abortUnauthorized(c, signatureInvalidMessage)
+			return
+		}
+
+		secret, err := resolver.Secret(ctx, signingkey.SecretParams{ID: keyID, UserID: userID})
+		if err != nil {
+			if errors.Is(err, signingkey.ErrSigningKeyNotFound) {
+				abortUnauthorized(c, signatureInvalidMessage)
+				return
+			}
+			abortWithServerError(c, err)
+			return
+		}
+		defer securebytes.Wipe(secret)
+
+		verifySignature(c, guard, secret, sig, maxSkew, maxSpooledBodySize)
+	}
+}
+
+// AdminRequestSignature creates middleware that requires administrative requests to be signed with
+// the operator signing key, so a leaked admin token alone is not enough to use the admin API.
+// Signatures older or newer than maxSkew, DefaultSignatureMaxSkew when zero, or seen before, are rejected.
+// An empty key disables the middleware.
+func AdminRequestSignature(key string, maxSkew time.Duration) gin.HandlerFunc {
+	if key == "" {
+		return func(c *gin.Context) { c.Next() }
+	}
+	if maxSkew <= 0 {
+		maxSkew = DefaultSignatureMaxSkew
+	}
+
+	secret := []byte(key)
+	guard := newReplayGuard()
+	return func(c *gin.Context) {
+		header := c.GetHeader(consts.HeaderXSignature)
+		if header == "" {
+			abortUnauthorized(c, signatureRequiredMessage)
+			return
+		}
+
+		sig, err := parseSignature(header)
+		if err != nil {
+			abortUnauthorized(c, signatureInvalidMessage)
+			return
+		}
+
+		verifySignature(c, guard, secret, sig, maxSkew, maxSignedBodySize)
+	}
+}
+
+// requestSignature holds the parts of a signature header.
+type requestSignature struct {
+	// keyID names the signing key; unused for administrative requests.
+	keyID string
+	// mac contains the HMAC-SHA256 of the canonical request.
+	mac []byte
+	// timestamp contains the Unix time the request was signed at.
+	timestamp int64
+}
+
+// parseSignature parses a signature header of the form "t=<unix time>,key=<key ID>,v1=<hex HMAC>".
+func parseSignature(header string) (requestSignature, error) {
+	var (
+		sig          requestSignature
+		hasTimestamp bool
+	)
+	for _, part := range strings.Split(header, ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return requestSignature{}, errSignatureMalformed
+		}
+		switch name {
+		case "t":
+			ts, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return requestSignature{}, errSignatureMalformed
+			}
+			sig.timestamp, hasTimestamp = ts, true
+		case "key":
+			sig.keyID = value
+		case signatureVersion:
+			mac, err := hex.DecodeString(value)
+			if err != nil {
+				return requestSignature{}, errSignatureMalformed
+			}
+			sig.mac = mac
+		}
+	}
+	if !hasTimestamp || len(sig.mac) == 0 {
+		return requestSignature{}, errSignatureMalformed
+	}
+	return sig, nil
+}
+
+// verifySignature checks the signature of the request against the secret and continues the chain when
+// it is valid, fresh and seen for the first time. Bodies up to maxBody bytes are digested and restored for
+// the handlers.
+func verifySignature(
+	c *gin.Context,
+	guard *replayGuard,
+	secret []byte,
+	sig requestSignature,
+	maxSkew time.Duration,
+	maxBody int64,
+) {
+	now := time.Now()
+	signedAt := time.Unix(sig.timestamp, 0)
+	if signedAt.Before(now.Add(-maxSkew)) || signedAt.After(now.Add(maxSkew)) {
+		abortUnauthorized(c, signatureInvalidMessage)
+		return
+	}
+
+	digest, cleanup, err := digestSignedBody(c.Request, maxBody)
+	if err != nil {
+		c.JSON(http.StatusRequestEntityTooLarge, response.Error{Messages: []string{err.Error()}})
+		c.Abort()
+		return
+	}
+	defer cleanup()
+
+	payload := canonicalRequest(c.Request.Method, c.Request.URL.RequestURI(), sig.timestamp, digest)
+	if !crypto.VerifyHMACSHA256(secret, payload, sig.mac) {
+		abortUnauthorized(c, signatureInvalidMessage)
+		return
+	}
+	if !guard.firstUse(sig.mac, signedAt.Add(maxSkew), now) {
+		abortUnauthorized(c, signatureInvalidMessage)
+		return
+	}
+
+	c.Next()
+}
+
+// readSignedBody reads the request body, up to maxSignedBodySize bytes, and replaces it with a copy.
+func readSignedBody(r *http.Request) ([]byte, error) {
+	if r.Body == nil {
+		return nil, nil
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxSignedBodySize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+	if len(body) > maxSignedBodySize {
+		return nil, fmt.Errorf("signed request bodies must not exceed %d bytes", maxSignedBodySize)
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+// digestSignedBody returns the SHA-256 digest of the request body, up to maxBody bytes, and replaces the body
+// with a copy. Bodies larger than maxSignedBodySize are copied to a temporary file, which the returned cleanup
+// removes once the request is handled.
+func digestSignedBody(r *http.Request, maxBody int64) ([]byte, func(), error) {
+	cleanup := func() {}
+	if maxBody <= maxSignedBodySize {
+		body, err := readSignedBody(r)
+		if err != nil {
+			return nil, cleanup, err
+		}
+		digest := sha256.Sum256(body)
+		return digest[:], cleanup, nil
+	}
+	if r.Body == nil {
+		digest := sha256.Sum256(nil)
+		return digest[:], cleanup, nil
+	}
+
+	head, err := io.ReadAll(io.LimitReader(r.Body, maxSignedBodySize+1))
+	if err != nil {
+		return nil, cleanup, fmt.Errorf("failed to read request body: %w", err)
+	}
+	if len(head) <= maxSignedBodySize {
+		r.Body = io.NopCloser(bytes.NewReader(head))
+		digest := sha256.Sum256(head)
+		return digest[:], cleanup, nil
+	}
+
+	spool, err := os.CreateTemp("", "signed-body-*")
+	if err != nil {
+		return nil, cleanup, fmt.Errorf("failed to spool request body: %w", err)
+	}
+	cleanup = func() {
+		_ = spool.Close()
+		_ = os.Remove(spool.Name())
+	}
+
+	h := sha256.New()
+	w := io.MultiWriter(spool, h)
+	if _, err := w.Write(head); err != nil {
+		cleanup()
+		return nil, func() {}, fmt.Errorf("failed to spool request body: %w", err)
+	}
+	n, err := io.Copy(w, io.LimitReader(r.Body, maxBody-int64(len(head))+1))
+	if err != nil {
+		cleanup()
+		return nil, func() {}, fmt.Errorf("failed to read request body: %w", err)
+	}
+	if int64(len(head))+n > maxBody {
+		cleanup()
+		return nil, func() {}, fmt.Errorf("signed request bodies must not exceed %d bytes", maxBody)
+	}
+	if _, err := spool.Seek(0, io.SeekStart); err != nil {
+		cleanup()
+		return nil, func() {}, fmt.Errorf("failed to spool request body: %w", err)
+	}
+
+	r.Body = io.NopCloser(spool)
+	return h.Sum(nil), cleanup, nil
+}
+
+// canonicalRequest builds the signed representation of a request: the signing scheme, method,
+// path with query, timestamp and hex SHA-256 digest of the body, separated by newlines.
+func canonicalRequest(method, requestURI string, timestamp int64, digest []byte) []byte {
+	return []byte(strings.Join([]string{
+		signatureVersion,
+		method,
+		requestURI,
+		strconv.FormatInt(timestamp, 10),
+		hex.EncodeToString(digest),
+	}, "\n"))
+}
+
+// replayGuard remembers the signatures accepted within their validity window.
+type replayGuard struct {
+	// seen maps accepted signatures to the moment they expire.
+	seen map[string]time.Time
+	// nextPrune is the moment expired signatures are next dropped.
+	nextPrune time.Time
+	// mu guards seen and nextPrune.
+	mu sync.Mutex
+}
+
+// newReplayGuard creates an empty replay guard.
+func newReplayGuard() *replayGuard {
+	return &replayGuard{seen: make(map[string]time.Time)}
+}
+
+// firstUse records the signature until expiresAt and reports whether it was not seen before.
+func (g *replayGuard) firstUse(mac []byte, expiresAt, now time.Time) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if now.After(g.nextPrune) {
+		for k, exp := range g.seen {
+			if now.After(exp) {
+				delete(g.seen, k)
+			}
+		}
+		g.nextPrune = now.Add(time.Minute)
+	}
+
+	k := string(mac)
+	if exp, ok := g.seen[k]; ok && !now.After(exp) {
+		return false
+	}
+	g.seen[k] = expiresAt
+	return true
+}
+
+// abortUnauthorized rejects the request with 401 Unauthorized and the message.
+func abortUnauthorized(c *gin.Context, msg string) {
+	c.JSON(http.StatusUnauthorized, response.Error{Messages: []string{msg}})
+	c.Abort()
+}
+
+// abortWithServerError records the error and rejects the request with 500 Internal Server Error.
+func abortWithServerError(c *gin.Context, err error) {
+	_ = c.Error(err)
+	c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+	c.Abort()
+}
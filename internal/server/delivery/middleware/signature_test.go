@@ -0,0 +1,359 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/signingkey"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/crypto"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/consts"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockSigningKeyResolver implements SigningKeyResolver for testing.
+type mockSigningKeyResolver struct {
+	enrolledErr error
+	secretErr   error
+	secrets     map[uuid.UUID][]byte
+	enrolled    bool
+}
+
+func (m *mockSigningKeyResolver) Enrolled(context.Context, signingkey.EnrolledParams) (bool, error) {
+	return m.enrolled, m.enrolledErr
+}
+
+func (m *mockSigningKeyResolver) Secret(_ context.Context, params signingkey.SecretParams) ([]byte, error) {
+	if m.secretErr != nil {
+		return nil, m.secretErr
+	}
+	secret, ok := m.secrets[params.ID]
+	if !ok {
+		return nil, signingkey.ErrSigningKeyNotFound
+	}
+	return bytes.Clone(secret), nil
+}
+
+// signHeader builds a signature header for the request parts.
+func signHeader(secret []byte, keyID, method, uri string, ts time.Time, body []byte) string {
+	digest := sha256.Sum256(body)
+	mac := crypto.SignHMACSHA256(secret, canonicalRequest(method, uri, ts.Unix(), digest[:]))
+	return fmt.Sprintf("t=%d,key=%s,v1=%s", ts.Unix(), keyID, hex.EncodeToString(mac))
+}
+
+// newSignedRouter creates a router echoing the body of requests passing the middleware.
+func newSignedRouter(mw gin.HandlerFunc, userID uuid.UUID) *gin.Engine {
+	router := gin.New()
+	router.Any("/export",
+		func(c *gin.Context) {
+			c.Set(consts.CtxKeyUserID, userID)
+			c.Next()
+		},
+		mw,
+		func(c *gin.Context) {
+			body, _ := io.ReadAll(c.Request.Body)
+			c.String(http.StatusOK, string(body))
+		},
+	)
+	return router
+}
+
+func TestRequestSignature(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+
+	userID := uuid.New()
+	keyID := uuid.New()
+	secret := bytes.Repeat([]byte{0x42}, 32)
+	now := time.Now()
+
+	tests := []struct {
+		resolver   *mockSigningKeyResolver
+		name       string
+		header     string
+		uri        string
+		wantStatus int
+	}{
+		{
+			name:       "no resolver",
+			uri:        "/export",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "unsigned without keys",
+			resolver:   &mockSigningKeyResolver{},
+			uri:        "/export",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "unsigned with keys",
+			resolver:   &mockSigningKeyResolver{enrolled: true},
+			uri:        "/export",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "enrollment check error",
+			resolver:   &mockSigningKeyResolver{enrolledErr: errors.New("connection refused")},
+			uri:        "/export",
+			wantStatus: http.StatusInternalServerError,
+		},
+		{
+			name:       "valid signature",
+			resolver:   &mockSigningKeyResolver{enrolled: true, secrets: map[uuid.UUID][]byte{keyID: secret}},
+			uri:        "/export?as_of=2026-10-01",
+			header:     signHeader(secret, keyID.String(), http.MethodGet, "/export?as_of=2026-10-01", now, nil),
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "tampered query",
+			resolver:   &mockSigningKeyResolver{enrolled: true, secrets: map[uuid.UUID][]byte{keyID: secret}},
+			uri:        "/export?as_of=2020-01-01",
+			header:     signHeader(secret, keyID.String(), http.MethodGet, "/export?as_of=2026-10-01", now, nil),
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "wrong secret",
+			resolver:   &mockSigningKeyResolver{enrolled: true, secrets: map[uuid.UUID][]byte{keyID: secret}},
+			uri:        "/export",
+			header:     signHeader([]byte("wrong"), keyID.String(), http.MethodGet, "/export", now, nil),
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "expired signature",
+			resolver:   &mockSigningKeyResolver{enrolled: true, secrets: map[uuid.UUID][]byte{keyID: secret}},
+			uri:        "/export",
+			header:     signHeader(secret, keyID.String(), http.MethodGet, "/export", now.Add(-time.Hour), nil),
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "unknown key",
+			resolver:   &mockSigningKeyResolver{enrolled: true},
+			uri:        "/export",
+			header:     signHeader(secret, keyID.String(), http.MethodGet, "/export", now, nil),
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "malformed key ID",
+			resolver:   &mockSigningKeyResolver{enrolled: true},
+			uri:        "/export",
+			header:     signHeader(secret, "laptop", http.MethodGet, "/export", now, nil),
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "key lookup error",
+			resolver:   &mockSigningKeyResolver{secretErr: errors.New("connection refused")},
+			uri:        "/export",
+			header:     signHeader(secret, keyID.String(), http.MethodGet, "/export", now, nil),
+			wantStatus: http.StatusInternalServerError,
+		},
+		{
+			name:       "malformed header",
+			resolver:   &mockSigningKeyResolver{enrolled: true},
+			uri:        "/export",
+			header:     "garbage",
+			wantStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var resolver SigningKeyResolver
+			if tt.resolver != nil {
+				resolver = tt.resolver
+			}
+			router := newSignedRouter(RequestSignature(resolver, 5*time.Minute), userID)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, tt.uri, nil)
+			if tt.header != "" {
+				req.Header.Set(consts.HeaderXSignature, tt.header)
+			}
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
+
+func TestRequestSignature_Replay(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+
+	userID := uuid.New()
+	keyID := uuid.New()
+	secret := bytes.Repeat([]byte{0x42}, 32)
+	resolver := &mockSigningKeyResolver{enrolled: true, secrets: map[uuid.UUID][]byte{keyID: secret}}
+	router := newSignedRouter(RequestSignature(resolver, 5*time.Minute), userID)
+	header := signHeader(secret, keyID.String(), http.MethodGet, "/export", time.Now(), nil)
+
+	var codes []int
+	for range 2 {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/export", nil)
+		req.Header.Set(consts.HeaderXSignature, header)
+		router.ServeHTTP(w, req)
+		codes = append(codes, w.Code)
+	}
+
+	assert.Equal(t, []int{http.StatusOK, http.StatusUnauthorized}, codes)
+}
+
+func TestRequestSignature_LargeBody(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+
+	userID := uuid.New()
+	keyID := uuid.New()
+	secret := bytes.Repeat([]byte{0x42}, 32)
+	resolver := &mockSigningKeyResolver{enrolled: true, secrets: map[uuid.UUID][]byte{keyID: secret}}
+	router := newSignedRouter(RequestSignature(resolver, 5*time.Minute), userID)
+	body := bytes.Repeat([]byte("file"), maxSignedBodySize)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPut, "/export", bytes.NewReader(body))
+	req.Header.Set(
+		consts.HeaderXSignature,
+		signHeader(secret, keyID.String(), http.MethodPut, "/export", time.Now(), body),
+	)
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, len(body), w.Body.Len())
+}
+
+func TestAdminRequestSignature(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+
+	key := strings.Repeat("k", 32)
+	body := []byte(`{"enabled":true}`)
+	now := time.Now()
+
+	tests := []struct {
+		name       string
+		key        string
+		header     string
+		body       []byte
+		wantStatus int
+	}{
+		{
+			name:       "signing disabled",
+			body:       body,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "unsigned",
+			key:        key,
+			body:       body,
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "valid signature",
+			key:        key,
+			header:     signHeader([]byte(key), "", http.MethodPut, "/export", now, body),
+			body:       body,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "tampered body",
+			key:        key,
+			header:     signHeader([]byte(key), "", http.MethodPut, "/export", now, body),
+			body:       []byte(`{"enabled":false}`),
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "future signature",
+			key:        key,
+			header:     signHeader([]byte(key), "", http.MethodPut, "/export", now.Add(time.Hour), body),
+			body:       body,
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "body too large",
+			key:        key,
+			header:     signHeader([]byte(key), "", http.MethodPut, "/export", now, nil),
+			body:       make([]byte, maxSignedBodySize+1),
+			wantStatus: http.StatusRequestEntityTooLarge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			router := newSignedRouter(AdminRequestSignature(tt.key, 5*time.Minute), uuid.Nil)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPut, "/export", bytes.NewReader(tt.body))
+			if tt.header != "" {
+				req.Header.Set(consts.HeaderXSignature, tt.header)
+			}
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantStatus == http.StatusOK {
+				assert.Equal(t, string(tt.body), w.Body.String())
+			}
+		})
+	}
+}
+
+func TestParseSignature(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		header  string
+		want    requestSignature
+		wantErr bool
+	}{
+		{
+			name:   "all parts",
+			header: "t=1700000000,key=abc,v1=0a0b",
+			want:   requestSignature{timestamp: 1700000000, keyID: "abc", mac: []byte{0x0a, 0x0b}},
+		},
+		{
+			name:   "spaces and no key",
+			header: "t=1700000000, v1=0a0b",
+			want:   requestSignature{timestamp: 1700000000, mac: []byte{0x0a, 0x0b}},
+		},
+		{name: "missing timestamp", header: "key=abc,v1=0a0b", wantErr: true},
+		{name: "missing mac", header: "t=1700000000,key=abc", wantErr: true},
+		{name: "invalid timestamp", header: "t=yesterday,v1=0a0b", wantErr: true},
+		{name: "invalid mac", header: "t=1700000000,v1=zz", wantErr: true},
+		{name: "no separator", header: "t1700000000", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := parseSignature(tt.header)
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, errSignatureMalformed)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"context"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/notification"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/consts"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/dryrun"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// syncTriggerTimeout bounds the delivery of the sync messages triggered by a single request.
+const syncTriggerTimeout = 30 * time.Second
+
+// SyncTrigger defines the interface for telling the other devices of a user that the vault changed.
+type SyncTrigger interface {
+	// TriggerSync sends a sync message to the push subscriptions of the user.
+	TriggerSync(ctx context.Context, params notification.SyncParams) error
+}
+
+// SyncTriggers creates middleware that sends sync messages to the devices of the user after each
+// successful mutating request, so open clients pull the change without polling. The device that made
+// the change is skipped when it passes its push subscription ID in the X-Push-Subscription header.
+// Messages are sent in the background once the response is written; delivery is best effort, since
+// clients also sync on their own. Dry runs change nothing and send no messages. Must run after
+// AuthWithJWT. A nil trigger disables the middleware.
+func SyncTriggers(trigger SyncTrigger) gin.HandlerFunc {
+	if trigger == nil {
+		return func(c *gin.Context) { c.Next() }
+	}
+	return func(c *gin.Context) {
+		c.Next()
+
+		status := c.Writer.Status()
+		if isSafeMethod(c.Request.Method) || status < 200 || status >= 300 || dryrun.Enabled(c.Request.Context()) {
+			return
+		}
+		value, _ := c.Get(consts.CtxKeyUserID)
+		userID, ok := value.(uuid.UUID)
+		if !ok {
+			return
+		}
+		exceptID, _ := uuid.Parse(c.GetHeader(consts.HeaderXPushSubscription))
+
+		ctx := context.WithoutCancel(c.Request.Context())
+		go func() {
+			ctx, cancel := context.WithTimeout(ctx, syncTriggerTimeout)
+			defer cancel()
+			_ = trigger.TriggerSync(ctx, notification.SyncParams{ExceptID: exceptID, UserID: userID})
+		}()
+	}
+}
@@ -0,0 +1,127 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/notification"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/consts"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/dryrun"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingSyncTrigger passes the triggered sync parameters to a channel.
+type recordingSyncTrigger chan notification.SyncParams
+
+func (r recordingSyncTrigger) TriggerSync(_ context.Context, params notification.SyncParams) error {
+	r <- params
+	return nil
+}
+
+func TestSyncTriggers(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+	userID := uuid.New()
+	deviceID := uuid.New()
+
+	tests := []struct {
+		want    *notification.SyncParams
+		name    string
+		method  string
+		header  string
+		status  int
+		setUser bool
+		dryRun  bool
+	}{
+		{
+			name:    "successful change",
+			method:  http.MethodPost,
+			status:  http.StatusCreated,
+			setUser: true,
+			want:    &notification.SyncParams{UserID: userID},
+		},
+		{
+			name:    "change from subscribed device",
+			method:  http.MethodDelete,
+			header:  deviceID.String(),
+			status:  http.StatusNoContent,
+			setUser: true,
+			want:    &notification.SyncParams{ExceptID: deviceID, UserID: userID},
+		},
+		{
+			name:    "malformed device header",
+			method:  http.MethodPut,
+			header:  "laptop",
+			status:  http.StatusOK,
+			setUser: true,
+			want:    &notification.SyncParams{UserID: userID},
+		},
+		{name: "read", method: http.MethodGet, status: http.StatusOK, setUser: true},
+		{name: "failed change", method: http.MethodPost, status: http.StatusBadRequest, setUser: true},
+		{name: "unauthenticated", method: http.MethodPost, status: http.StatusCreated},
+		{name: "dry run", method: http.MethodPost, status: http.StatusCreated, setUser: true, dryRun: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			trigger := make(recordingSyncTrigger, 1)
+			router := gin.New()
+			router.Use(func(c *gin.Context) {
+				if tt.setUser {
+					c.Set(consts.CtxKeyUserID, userID)
+				}
+				if tt.dryRun {
+					c.Request = c.Request.WithContext(dryrun.With(c.Request.Context()))
+				}
+				c.Next()
+			})
+			router.Use(SyncTriggers(trigger))
+			router.Handle(tt.method, "/items", func(c *gin.Context) { c.Status(tt.status) })
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(tt.method, "/items", nil)
+			if tt.header != "" {
+				req.Header.Set(consts.HeaderXPushSubscription, tt.header)
+			}
+			router.ServeHTTP(w, req)
+
+			if tt.want == nil {
+				select {
+				case got := <-trigger:
+					t.Fatalf("unexpected sync trigger: %+v", got)
+				case <-time.After(50 * time.Millisecond):
+				}
+				return
+			}
+			select {
+			case got := <-trigger:
+				assert.Equal(t, *tt.want, got)
+			case <-time.After(time.Second):
+				require.FailNow(t, "sync was not triggered")
+			}
+		})
+	}
+}
+
+func TestSyncTriggers_Disabled(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(SyncTriggers(nil))
+	router.POST("/items", func(c *gin.Context) { c.Status(http.StatusCreated) })
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/items", nil))
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+}
@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/response"
+	"github.com/gin-gonic/gin"
+)
+
+// Metric names updated by the request timeout middleware.
+const (
+	// MetricRequestsTimedOut counts requests whose handling deadline passed.
+	MetricRequestsTimedOut = "http_requests_timed_out_total"
+	// MetricRequestsCanceled counts requests abandoned by clients before handling completed.
+	MetricRequestsCanceled = "http_requests_canceled_total"
+)
+
+// timeoutMessage is returned for timed out requests the handler wrote no response for.
+const timeoutMessage = "The request took too long to process. Please try again later"
+
+// TimeoutRecorder defines the interface for counting timed out and canceled requests.
+type TimeoutRecorder interface {
+	// Add increments the named counter by delta.
+	Add(name string, delta int64)
+}
+
+// Timeout creates middleware that bounds the request context with the timeout, so database queries
+// and file operations using it are aborted once the deadline passes or the client disconnects.
+// Timed out requests neither aborted nor answered get 503 Service Unavailable. A zero timeout only
+// propagates client disconnects; a nil recorder disables counting.
+func Timeout(timeout time.Duration, recorder TimeoutRecorder) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		parent := c.Request.Context()
+		ctx, cancel := parent, context.CancelFunc(func() {})
+		if timeout > 0 {
+			ctx, cancel = context.WithTimeout(parent, timeout)
+		}
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		switch {
+		case errors.Is(parent.Err(), context.Canceled):
+			recordTimeout(recorder, MetricRequestsCanceled)
+		case errors.Is(ctx.Err(), context.DeadlineExceeded):
+			recordTimeout(recorder, MetricRequestsTimedOut)
+			if !c.IsAborted() && !c.Writer.Written() {
+				c.AbortWithStatusJSON(http.StatusServiceUnavailable, response.Error{Messages: []string{timeoutMessage}})
+			}
+		}
+	}
+}
+
+// recordTimeout increments the named counter when a recorder is configured.
+func recordTimeout(recorder TimeoutRecorder, name string) {
+	if recorder != nil {
+		recorder.Add(name, 1)
+	}
+}
@@ -0,0 +1,132 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// countingRecorder counts metric increments by name.
+type countingRecorder struct {
+	counts map[string]int64
+	mu     sync.Mutex
+}
+
+func (r *countingRecorder) Add(name string, delta int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.counts == nil {
+		r.counts = make(map[string]int64)
+	}
+	r.counts[name] += delta
+}
+
+func TestTimeout(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+
+	waitForDone := func(c *gin.Context) { <-c.Request.Context().Done() }
+
+	tests := []struct {
+		handler      gin.HandlerFunc
+		wantCounts   map[string]int64
+		name         string
+		timeout      time.Duration
+		wantStatus   int
+		cancelClient bool
+		wantDeadline bool
+	}{
+		{
+			name:         "completed in time",
+			timeout:      time.Minute,
+			handler:      func(c *gin.Context) { c.Status(http.StatusOK) },
+			wantStatus:   http.StatusOK,
+			wantDeadline: true,
+		},
+		{
+			name:       "timed out without response",
+			timeout:    10 * time.Millisecond,
+			handler:    waitForDone,
+			wantStatus: http.StatusServiceUnavailable,
+			wantCounts: map[string]int64{MetricRequestsTimedOut: 1},
+		},
+		{
+			name:    "timed out with handler response",
+			timeout: 10 * time.Millisecond,
+			handler: func(c *gin.Context) {
+				waitForDone(c)
+				c.JSON(http.StatusInternalServerError, gin.H{})
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantCounts: map[string]int64{MetricRequestsTimedOut: 1},
+		},
+		{
+			name:         "client disconnected",
+			timeout:      time.Minute,
+			handler:      waitForDone,
+			cancelClient: true,
+			wantStatus:   http.StatusOK,
+			wantCounts:   map[string]int64{MetricRequestsCanceled: 1},
+		},
+		{
+			name:       "zero timeout",
+			timeout:    0,
+			handler:    func(c *gin.Context) { c.Status(http.StatusOK) },
+			wantStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			recorder := &countingRecorder{}
+			var hasDeadline bool
+			router := gin.New()
+			router.Use(Timeout(tt.timeout, recorder))
+			router.GET("/test", func(c *gin.Context) {
+				_, hasDeadline = c.Request.Context().Deadline()
+				tt.handler(c)
+			})
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tt.cancelClient {
+				cancel()
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequestWithContext(ctx, http.MethodGet, "/test", nil))
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.Equal(t, tt.wantDeadline || tt.timeout > 0, hasDeadline)
+			if tt.wantCounts == nil {
+				assert.Empty(t, recorder.counts)
+			} else {
+				assert.Equal(t, tt.wantCounts, recorder.counts)
+			}
+		})
+	}
+}
+
+func TestTimeout_NilRecorder(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(Timeout(10*time.Millisecond, nil))
+	router.GET("/test", func(c *gin.Context) { <-c.Request.Context().Done() })
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/test", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Contains(t, rec.Body.String(), "took too long")
+}
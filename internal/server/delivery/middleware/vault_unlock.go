@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"context"
+	"encoding/base64"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/consts"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/response"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/vaultunlock"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// headerVaultUnlock names the header carrying the base64 encoded unlock key of the vault.
+const headerVaultUnlock = "X-Vault-Unlock"
+
+// VaultUnlockService defines the interface for verifying the unlock keys of vaults.
+type VaultUnlockService interface {
+	// Unlock verifies the unlock key of the user; users without an unlock secret need none.
+	Unlock(ctx context.Context, userID uuid.UUID, unlockKey []byte) error
+}
+
+// VaultUnlock creates middleware that rejects requests to vaults sealed with an unlock secret unless they carry
+// a matching unlock key in the X-Vault-Unlock header. The verified key is stored in the request context, so the
+// user key can be opened with it. It must run after AuthWithJWT. A nil service disables the middleware.
+func VaultUnlock(service VaultUnlockService) gin.HandlerFunc {
+	if service == nil {
+		return func(c *gin.Context) { c.Next() }
+	}
+	return func(c *gin.Context) {
+		value, _ := c.Get(consts.CtxKeyUserID)
+		userID, _ := value.(uuid.UUID)
+
+		// unlockKey holds the decoded unlock key; an undecodable header is verified as a wrong key.
+		unlockKey, err := base64.StdEncoding.DecodeString(c.GetHeader(headerVaultUnlock))
+		if err != nil {
+			unlockKey = []byte{0}
+		}
+
+		ctx := c.Request.Context()
+		if err := service.Unlock(ctx, userID, unlockKey); err != nil {
+			code, msgs := handleError(err, c)
+			c.JSON(code, response.Error{
+				Messages: msgs,
+			})
+			c.Abort()
+			return
+		}
+		if len(unlockKey) != 0 {
+			c.Request = c.Request.WithContext(vaultunlock.WithKey(ctx, unlockKey))
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,103 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	app "github.com/gdyunin/aegis-vault-keeper/internal/server/application/auth"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/consts"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/vaultunlock"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// staticVaultUnlockService accepts a single unlock key; a nil key leaves the vault unsealed.
+type staticVaultUnlockService struct {
+	key []byte
+}
+
+func (s *staticVaultUnlockService) Unlock(_ context.Context, _ uuid.UUID, unlockKey []byte) error {
+	switch {
+	case s.key == nil:
+		return nil
+	case len(unlockKey) == 0:
+		return app.ErrAuthVaultLocked
+	case !bytes.Equal(s.key, unlockKey):
+		return app.ErrAuthUnlockInvalid
+	default:
+		return nil
+	}
+}
+
+func TestVaultUnlock(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+	unlockKey := bytes.Repeat([]byte{1}, vaultunlock.KeySize)
+	encoded := base64.StdEncoding.EncodeToString(unlockKey)
+
+	tests := []struct {
+		service    VaultUnlockService
+		name       string
+		header     string
+		wantKey    []byte
+		wantStatus int
+	}{
+		{name: "disabled middleware", service: nil, wantStatus: http.StatusOK},
+		{name: "vault without unlock secret", service: &staticVaultUnlockService{}, wantStatus: http.StatusOK},
+		{
+			name:       "matching unlock key",
+			service:    &staticVaultUnlockService{key: unlockKey},
+			header:     encoded,
+			wantKey:    unlockKey,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "missing unlock key",
+			service:    &staticVaultUnlockService{key: unlockKey},
+			wantStatus: http.StatusLocked,
+		},
+		{
+			name:       "wrong unlock key",
+			service:    &staticVaultUnlockService{key: unlockKey},
+			header:     base64.StdEncoding.EncodeToString([]byte("wrong")),
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "undecodable unlock key",
+			service:    &staticVaultUnlockService{key: unlockKey},
+			header:     "not base64!",
+			wantStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// gotKey holds the unlock key the handler found in the request context.
+			var gotKey []byte
+			router := gin.New()
+			router.Use(func(c *gin.Context) { c.Set(consts.CtxKeyUserID, uuid.New()) }, VaultUnlock(tt.service))
+			router.GET("/items", func(c *gin.Context) {
+				gotKey, _ = vaultunlock.Key(c.Request.Context())
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/items", nil)
+			if tt.header != "" {
+				req.Header.Set(headerVaultUnlock, tt.header)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.Equal(t, tt.wantKey, gotKey)
+		})
+	}
+}
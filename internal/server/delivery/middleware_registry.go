@@ -1,29 +1,89 @@
 package delivery
 
 import (
+	"net/netip"
+
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/middleware"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
 
+// syncRoutePrefixes lists the vault synchronization routes whose requests and bytes are counted as sync volume.
+var syncRoutePrefixes = []string{"/api/items/sync"}
+
+// recordingExemptPrefixes lists the admin routes never recorded, so recordings cannot capture admin credentials
+// or the recordings themselves.
+var recordingExemptPrefixes = []string{"/api/admin/"}
+
 // MiddlewareRegistry manages HTTP middleware registration for the Gin router.
 type MiddlewareRegistry struct {
 	// logger provides logging functionality for middleware operations.
 	logger *zap.SugaredLogger
+	// cors contains cross-origin resource sharing settings.
+	cors middleware.CORSConfig
+	// securityHeaders contains settings of the security response headers.
+	securityHeaders middleware.SecurityHeadersConfig
+	// trustedProxies lists networks of reverse proxies allowed to forward client addresses.
+	trustedProxies []netip.Prefix
+	// chaos contains the fault injection settings (no rules disables fault injection).
+	chaos middleware.ChaosConfig
+	// requestStats counts handled requests for the usage statistics; nil disables counting.
+	requestStats middleware.RequestStatsRecorder
+	// recorder captures the requests selected by debugging recordings; nil disables recording.
+	recorder middleware.RequestRecorder
 }
 
-// NewMiddlewareRegistry creates a new middleware registry with the provided logger.
-func NewMiddlewareRegistry(logger *zap.SugaredLogger) *MiddlewareRegistry {
+// NewMiddlewareRegistry creates a new middleware registry with the provided logger, header settings,
+// trusted reverse proxy networks, fault injection settings, request counter and request recorder.
+func NewMiddlewareRegistry(
+	logger *zap.SugaredLogger,
+	cors middleware.CORSConfig,
+	securityHeaders middleware.SecurityHeadersConfig,
+	trustedProxies []netip.Prefix,
+	chaos middleware.ChaosConfig,
+	requestStats middleware.RequestStatsRecorder,
+	recorder middleware.RequestRecorder,
+) *MiddlewareRegistry {
 	return &MiddlewareRegistry{
-		logger: logger,
+		logger:          logger,
+		cors:            cors,
+		securityHeaders: securityHeaders,
+		trustedProxies:  trustedProxies,
+		chaos:           chaos,
+		requestStats:    requestStats,
+		recorder:        recorder,
 	}
 }
 
-// RegisterMiddlewares configures standard middleware for the Gin router.
+// RegisterMiddlewares configures standard middleware for the Gin router, preceded by fault injection
+// when chaos rules are configured. Gin's own client IP resolution is restricted to the same trusted proxies.
 func (mr *MiddlewareRegistry) RegisterMiddlewares(router *gin.Engine) {
+	// Handlers pass the gin context to services, so request deadlines and client disconnects
+	// reach database queries and file operations only when it falls back to the request context.
+	router.ContextWithFallback = true
+
+	proxies := make([]string, 0, len(mr.trustedProxies))
+	for _, p := range mr.trustedProxies {
+		proxies = append(proxies, p.String())
+	}
+	if err := router.SetTrustedProxies(proxies); err != nil {
+		mr.logger.Errorf("Failed to set trusted proxies: %v", err)
+	}
+
+	// Fault injection runs first, so injected faults bypass the other middlewares.
+	if len(mr.chaos.Rules) > 0 {
+		mr.logger.Warnf("Fault injection is enabled with %d rules; never enable it in production", len(mr.chaos.Rules))
+		router.Use(middleware.Chaos(mr.chaos))
+	}
+
 	router.Use(
-		gin.Recovery(),
+		middleware.Recovery(mr.logger.Named("http-recovery")),
+		middleware.RealIP(mr.trustedProxies),
 		middleware.RequestID(),
 		middleware.RequestLogging(mr.logger.Named("http-request")),
+		middleware.RequestStats(mr.requestStats, syncRoutePrefixes...),
+		middleware.Recording(mr.recorder, mr.logger.Named("request-recording"), recordingExemptPrefixes...),
+		middleware.SecurityHeaders(mr.securityHeaders),
+		middleware.CORS(mr.cors),
 	)
 }
@@ -1,8 +1,15 @@
 package delivery
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
 	"testing"
 
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/chaos"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/clientinfo"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/middleware"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -36,7 +43,9 @@ func TestNewMiddlewareRegistry(t *testing.T) {
 				logger = zaptest.NewLogger(t).Sugar()
 			}
 
-			registry := NewMiddlewareRegistry(logger)
+			registry := NewMiddlewareRegistry(
+				logger, middleware.CORSConfig{}, middleware.SecurityHeadersConfig{}, nil, middleware.ChaosConfig{}, nil, nil,
+			)
 
 			require.NotNil(t, registry)
 			assert.Equal(t, logger, registry.logger)
@@ -60,6 +69,8 @@ func TestMiddlewareRegistry_RegisterMiddlewares(t *testing.T) {
 				"Recovery",
 				"RequestID",
 				"RequestLogging",
+				"SecurityHeaders",
+				"CORS",
 			},
 			expectPanic: false,
 		},
@@ -83,7 +94,9 @@ func TestMiddlewareRegistry_RegisterMiddlewares(t *testing.T) {
 				logger = zaptest.NewLogger(t).Sugar()
 			}
 
-			registry := NewMiddlewareRegistry(logger)
+			registry := NewMiddlewareRegistry(
+				logger, middleware.CORSConfig{}, middleware.SecurityHeadersConfig{}, nil, middleware.ChaosConfig{}, nil, nil,
+			)
 
 			// Test for panic or success based on expectation
 			if tt.expectPanic {
@@ -116,9 +129,11 @@ func TestMiddlewareRegistry_MiddlewareOrder(t *testing.T) {
 		{
 			name: "middleware registration order",
 			expectedOrder: []string{
-				"gin.Recovery",
+				"middleware.Recovery",
 				"middleware.RequestID",
 				"middleware.RequestLogging",
+				"middleware.SecurityHeaders",
+				"middleware.CORS",
 			},
 			verifyHandlers: true,
 		},
@@ -132,13 +147,15 @@ func TestMiddlewareRegistry_MiddlewareOrder(t *testing.T) {
 			router := gin.New()
 			logger := zaptest.NewLogger(t).Sugar()
 
-			registry := NewMiddlewareRegistry(logger)
+			registry := NewMiddlewareRegistry(
+				logger, middleware.CORSConfig{}, middleware.SecurityHeadersConfig{}, nil, middleware.ChaosConfig{}, nil, nil,
+			)
 			registry.RegisterMiddlewares(router)
 
 			if tt.verifyHandlers {
 				// Verify handlers were registered in correct order
 				handlers := router.Handlers
-				assert.GreaterOrEqual(t, len(handlers), 3, "Should have at least 3 middleware handlers")
+				assert.GreaterOrEqual(t, len(handlers), 8, "Should have at least 8 middleware handlers")
 			}
 		})
 	}
@@ -167,7 +184,9 @@ func TestMiddlewareRegistry_LoggerNaming(t *testing.T) {
 			router := gin.New()
 			logger := zaptest.NewLogger(t).Sugar().Named(tt.loggerName)
 
-			registry := NewMiddlewareRegistry(logger)
+			registry := NewMiddlewareRegistry(
+				logger, middleware.CORSConfig{}, middleware.SecurityHeadersConfig{}, nil, middleware.ChaosConfig{}, nil, nil,
+			)
 
 			// This should not panic and should handle logger naming correctly
 			assert.NotPanics(t, func() {
@@ -205,7 +224,9 @@ func TestMiddlewareRegistry_MiddlewareConfiguration(t *testing.T) {
 			t.Parallel()
 
 			logger := zaptest.NewLogger(t).Sugar()
-			registry := NewMiddlewareRegistry(logger)
+			registry := NewMiddlewareRegistry(
+				logger, middleware.CORSConfig{}, middleware.SecurityHeadersConfig{}, nil, middleware.ChaosConfig{}, nil, nil,
+			)
 
 			var router *gin.Engine
 			if tt.testType == "standard" {
@@ -258,7 +279,9 @@ func TestMiddlewareRegistry_Integration(t *testing.T) {
 			initialHandlerCount := len(router.Handlers)
 
 			for range tt.registryCount {
-				registry := NewMiddlewareRegistry(logger)
+				registry := NewMiddlewareRegistry(
+					logger, middleware.CORSConfig{}, middleware.SecurityHeadersConfig{}, nil, middleware.ChaosConfig{}, nil, nil,
+				)
 				registry.RegisterMiddlewares(router)
 			}
 
@@ -267,11 +290,11 @@ func TestMiddlewareRegistry_Integration(t *testing.T) {
 
 			if tt.expectDuplication {
 				// Multiple registrations should add more handlers
-				expectedDelta := 3 * tt.registryCount // 3 middleware per registration
+				expectedDelta := 8 * tt.registryCount // 8 middleware per registration
 				assert.Equal(t, expectedDelta, handlerDelta, "Should have duplicated middleware")
 			} else {
-				// Single registration should add exactly 3 handlers
-				assert.Equal(t, 3, handlerDelta, "Should have exactly 3 middleware handlers")
+				// Single registration should add exactly 8 handlers
+				assert.Equal(t, 8, handlerDelta, "Should have exactly 8 middleware handlers")
 			}
 		})
 	}
@@ -286,8 +309,8 @@ func TestMiddlewareRegistry_MiddlewareTypes(t *testing.T) {
 		description string
 	}{
 		{
-			name:        "gin recovery middleware",
-			middleware:  "gin.Recovery",
+			name:        "recovery middleware",
+			middleware:  "middleware.Recovery",
 			description: "handles panics and returns 500 status",
 		},
 		{
@@ -310,7 +333,9 @@ func TestMiddlewareRegistry_MiddlewareTypes(t *testing.T) {
 			router := gin.New()
 			logger := zaptest.NewLogger(t).Sugar()
 
-			registry := NewMiddlewareRegistry(logger)
+			registry := NewMiddlewareRegistry(
+				logger, middleware.CORSConfig{}, middleware.SecurityHeadersConfig{}, nil, middleware.ChaosConfig{}, nil, nil,
+			)
 			registry.RegisterMiddlewares(router)
 
 			// Verify middleware types are correctly configured
@@ -358,7 +383,9 @@ func TestMiddlewareRegistry_LoggerConfiguration(t *testing.T) {
 				logger = zaptest.NewLogger(t).Sugar()
 			}
 
-			registry := NewMiddlewareRegistry(logger)
+			registry := NewMiddlewareRegistry(
+				logger, middleware.CORSConfig{}, middleware.SecurityHeadersConfig{}, nil, middleware.ChaosConfig{}, nil, nil,
+			)
 			registry.RegisterMiddlewares(router)
 
 			// Verify logger configuration behavior
@@ -367,3 +394,142 @@ func TestMiddlewareRegistry_LoggerConfiguration(t *testing.T) {
 		})
 	}
 }
+
+func TestMiddlewareRegistry_CORSPreflight(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	logger := zaptest.NewLogger(t).Sugar()
+
+	registry := NewMiddlewareRegistry(logger, middleware.CORSConfig{
+		AllowedOrigins: []string{"https://app.example.com"},
+		AllowedMethods: []string{http.MethodGet},
+	}, middleware.SecurityHeadersConfig{}, nil, middleware.ChaosConfig{}, nil, nil)
+	registry.RegisterMiddlewares(router)
+	router.GET("/api/items/notes", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	// Preflight requests reach the middleware even though no OPTIONS route is registered.
+	req := httptest.NewRequest(http.MethodOptions, "/api/items/notes", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	req.Header.Set("Access-Control-Request-Method", http.MethodGet)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, "https://app.example.com", rec.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestMiddlewareRegistry_TrustedProxies(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		trusted []netip.Prefix
+		want    string
+	}{
+		{
+			name:    "forwarded address from trusted proxy",
+			trusted: []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")},
+			want:    "203.0.113.7",
+		},
+		{
+			name: "forwarding headers ignored without trusted proxies",
+			want: "10.1.2.3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			registry := NewMiddlewareRegistry(
+				zaptest.NewLogger(t).Sugar(),
+				middleware.CORSConfig{},
+				middleware.SecurityHeadersConfig{},
+				tt.trusted,
+				middleware.ChaosConfig{},
+				nil,
+				nil,
+			)
+			registry.RegisterMiddlewares(router)
+
+			var ctxIP, ginIP string
+			router.GET("/test", func(c *gin.Context) {
+				if ip, ok := clientinfo.IP(c.Request.Context()); ok {
+					ctxIP = ip.String()
+				}
+				ginIP = c.ClientIP()
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			req.RemoteAddr = "10.1.2.3:4321"
+			req.Header.Set("X-Forwarded-For", "203.0.113.7")
+			router.ServeHTTP(httptest.NewRecorder(), req)
+
+			assert.Equal(t, tt.want, ctxIP)
+			assert.Equal(t, tt.want, ginIP)
+		})
+	}
+}
+
+func TestMiddlewareRegistry_ContextFallback(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	NewMiddlewareRegistry(
+		zaptest.NewLogger(t).Sugar(),
+		middleware.CORSConfig{},
+		middleware.SecurityHeadersConfig{},
+		nil,
+		middleware.ChaosConfig{},
+		nil,
+		nil,
+	).RegisterMiddlewares(router)
+
+	var ctxErr error
+	router.GET("/test", func(c *gin.Context) {
+		ctxErr = c.Err()
+		c.Status(http.StatusOK)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequestWithContext(ctx, http.MethodGet, "/test", nil))
+
+	assert.ErrorIs(t, ctxErr, context.Canceled)
+}
+
+func TestMiddlewareRegistry_Chaos(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	NewMiddlewareRegistry(
+		zaptest.NewLogger(t).Sugar(),
+		middleware.CORSConfig{},
+		middleware.SecurityHeadersConfig{},
+		nil,
+		middleware.ChaosConfig{Rules: []chaos.Rule{{
+			PathPrefix: "/api/items",
+			Fault:      chaos.Fault{Kind: chaos.KindError, Probability: 1, Status: http.StatusServiceUnavailable},
+		}}},
+		nil,
+		nil,
+	).RegisterMiddlewares(router)
+	router.GET("/api/items/notes", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.GET("/api/health", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/items/notes", http.NoBody))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Empty(t, rec.Header().Get("X-Content-Type-Options"), "faults should be injected before other middleware")
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/health", http.NoBody))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
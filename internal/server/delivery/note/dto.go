@@ -1,6 +1,8 @@
 package note
 
 import (
+	"encoding/json"
+	"slices"
 	"time"
 
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/note"
@@ -17,6 +19,28 @@ type Note struct {
 	Description string `json:"description,omitzero" example:"Meeting with client ABC"`
 	// ID contains the unique note identifier.
 	ID uuid.UUID `json:"id,omitzero"          example:"123e4567-e89b-12d3-a456-426614174000"`
+	// Archived reports whether the note is archived and hidden from lists by default.
+	Archived bool `json:"archived,omitzero"    example:"false"`
+}
+
+// fieldNames lists the note fields clients may select with the fields query parameter.
+var fieldNames = []string{"id", "note", "description", "updated_at"}
+
+// keepFields clears the fields of the note not named in fields, always keeping the ID.
+// An empty field list keeps every field.
+func (n *Note) keepFields(fields []string) {
+	if n == nil || len(fields) == 0 {
+		return
+	}
+	if !slices.Contains(fields, "note") {
+		n.Note = ""
+	}
+	if !slices.Contains(fields, "description") {
+		n.Description = ""
+	}
+	if !slices.Contains(fields, "updated_at") {
+		n.UpdatedAt = time.Time{}
+	}
 }
 
 // ToApp converts delivery DTO to application layer Note entity.
@@ -55,6 +79,7 @@ func NewNoteFromApp(n *note.Note) *Note {
 		Note:        n.Note,
 		Description: n.Description,
 		UpdatedAt:   n.UpdatedAt,
+		Archived:    n.Archived,
 	}
 }
 
@@ -84,6 +109,12 @@ type PullRequest struct {
 	ID string `uri:"id" binding:"required" example:"123e4567-e89b-12d3-a456-426614174000"`
 }
 
+// AsOfRequest represents the request addressing a note state at a past moment.
+type AsOfRequest struct {
+	// Timestamp contains the RFC 3339 moment whose note version is addressed (required).
+	Timestamp time.Time `form:"timestamp" binding:"required" example:"2023-12-01T10:00:00Z"`
+}
+
 // PushResponse represents the response after creating or updating a note.
 type PushResponse struct {
 	// ID contains the created or updated note identifier.
@@ -101,3 +132,54 @@ type ListResponse struct {
 	// Notes contains all notes belonging to the authenticated user.
 	Notes []*Note `json:"notes"`
 }
+
+// RecoverResponse represents the response containing the recovered note.
+type RecoverResponse struct {
+	// Note contains the note data saved as the latest version.
+	Note *Note `json:"note"`
+}
+
+// UpdatesRequest represents the query selecting the text updates of a note to pull.
+type UpdatesRequest struct {
+	// After contains the sequence number of the last update merged by the client; zero pulls all updates.
+	After int64 `form:"after" binding:"min=0" example:"42"`
+}
+
+// Update represents a stored text update of a note edited in merge mode.
+type Update struct {
+	// Update contains the update in the JSON update format: an ops list or, for snapshots, a snapshot list.
+	Update json.RawMessage `json:"update" swaggertype:"object"`
+	// Seq contains the sequence number of the update.
+	Seq int64 `json:"seq" example:"42"`
+	// Snapshot reports whether the update is a compacted snapshot replacing all updates up to its Seq.
+	Snapshot bool `json:"snapshot,omitzero" example:"false"`
+}
+
+// NewUpdatesFromApp converts application layer text updates to delivery DTOs.
+func NewUpdatesFromApp(updates []*note.Update) []*Update {
+	result := make([]*Update, 0, len(updates))
+	for _, u := range updates {
+		result = append(result, &Update{Update: u.Data, Seq: u.Seq, Snapshot: u.Snapshot})
+	}
+	return result
+}
+
+// UpdatesResponse represents the response containing the text updates of a note.
+type UpdatesResponse struct {
+	// Updates contains the updates in sequence order.
+	Updates []*Update `json:"updates"`
+}
+
+// PushUpdateRequest represents the request merging a text update into a note.
+type PushUpdateRequest struct {
+	// Update contains the update in the JSON update format with an ops list (required).
+	Update json.RawMessage `json:"update" binding:"required" swaggertype:"object"`
+}
+
+// PushUpdateResponse represents the response after merging a text update into a note.
+type PushUpdateResponse struct {
+	// Note contains the note with the merged text.
+	Note *Note `json:"note"`
+	// Seq contains the sequence number the update was stored with.
+	Seq int64 `json:"seq" example:"43"`
+}
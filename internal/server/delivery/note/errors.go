@@ -5,6 +5,8 @@ import (
 
 	app "github.com/gdyunin/aegis-vault-keeper/internal/server/application/note"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/errutil"
+	itempolicydel "github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/itempolicy"
+	plandel "github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/plan"
 	"github.com/gin-gonic/gin"
 )
 
@@ -22,6 +24,17 @@ var NoteErrRegistry = errutil.Registry{
 		},
 	},
 
+	{
+		ErrorIn: app.ErrNoteIntegrityViolation,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusInternalServerError,
+			PublicMsg:  "Stored note data failed integrity verification",
+			LogIt:      true,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassTech,
+		},
+	},
+
 	{
 		ErrorIn: app.ErrNoteAccessDenied,
 		HandlePolicy: errutil.Policy{
@@ -44,6 +57,39 @@ var NoteErrRegistry = errutil.Registry{
 		},
 	},
 
+	{
+		ErrorIn: app.ErrNoteMergeMode,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusConflict,
+			PublicMsg:  "Note text is edited in merge mode; push text updates instead",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassGeneric,
+		},
+	},
+
+	{
+		ErrorIn: app.ErrNoteNotInMergeMode,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusConflict,
+			PublicMsg:  "Note is not edited in merge mode",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassGeneric,
+		},
+	},
+
+	{
+		ErrorIn: app.ErrNoteUpdateInvalid,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusBadRequest,
+			PublicMsg:  "Invalid note text update",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+
 	{
 		ErrorIn: app.ErrNoteIncorrectNoteText,
 		HandlePolicy: errutil.Policy{
@@ -67,7 +113,14 @@ var NoteErrRegistry = errutil.Registry{
 	},
 }
 
+// handledErrRegistry aggregates the note, plan enforcement and item policy enforcement error registries.
+var handledErrRegistry = errutil.Merge(
+	NoteErrRegistry,
+	plandel.PlanErrRegistry,
+	itempolicydel.ItemPolicyErrRegistry,
+)
+
 // handleError processes note errors using the registry and returns appropriate HTTP response.
 func handleError(err error, c *gin.Context) (int, []string) {
-	return errutil.HandleWithRegistry(NoteErrRegistry, err, c)
+	return errutil.HandleWithRegistry(handledErrRegistry, err, c)
 }
@@ -49,6 +49,24 @@ func TestNoteErrRegistry(t *testing.T) {
 			expectedMerge:  false,
 			expectedClass:  errutil.ErrorClassGeneric,
 		},
+		{
+			name:           "not in merge mode error",
+			errorIn:        app.ErrNoteNotInMergeMode,
+			expectedStatus: 409,
+			expectedMsg:    "Note is not edited in merge mode",
+			expectedLogIt:  false,
+			expectedMerge:  false,
+			expectedClass:  errutil.ErrorClassGeneric,
+		},
+		{
+			name:           "invalid note update error",
+			errorIn:        app.ErrNoteUpdateInvalid,
+			expectedStatus: 400,
+			expectedMsg:    "Invalid note text update",
+			expectedLogIt:  false,
+			expectedMerge:  false,
+			expectedClass:  errutil.ErrorClassValidation,
+		},
 		{
 			name:           "incorrect note text error",
 			errorIn:        app.ErrNoteIncorrectNoteText,
@@ -100,8 +118,12 @@ func TestNoteErrRegistry_Coverage(t *testing.T) {
 	// Verify all expected note errors are covered
 	expectedErrors := []error{
 		app.ErrNoteTechError,
+		app.ErrNoteIntegrityViolation,
 		app.ErrNoteAccessDenied,
 		app.ErrNoteNotFound,
+		app.ErrNoteMergeMode,
+		app.ErrNoteNotInMergeMode,
+		app.ErrNoteUpdateInvalid,
 		app.ErrNoteIncorrectNoteText,
 		app.ErrNoteAppError,
 	}
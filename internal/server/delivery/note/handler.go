@@ -3,8 +3,11 @@ package note
 import (
 	"context"
 	"net/http"
+	"slices"
 
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/note"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/consts"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/jsonenc"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/response"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/util"
 	"github.com/gin-gonic/gin"
@@ -19,6 +22,16 @@ type Service interface {
 	List(context.Context, note.ListParams) ([]*note.Note, error)
 	// Push creates or updates a note for the authenticated user.
 	Push(context.Context, *note.PushParams) (uuid.UUID, error)
+	// Recover restores a note of the authenticated user to the version current at a given moment.
+	Recover(context.Context, note.RecoverParams) (*note.Note, error)
+	// EnableMerge switches a note of the authenticated user to merge mode and returns its text updates.
+	EnableMerge(context.Context, note.MergeParams) ([]*note.Update, error)
+	// DisableMerge switches a note of the authenticated user back to plain edits.
+	DisableMerge(context.Context, note.MergeParams) error
+	// Updates retrieves the text updates of a note of the authenticated user edited in merge mode.
+	Updates(context.Context, note.UpdatesParams) ([]*note.Update, error)
+	// PushUpdate merges a text update into a note of the authenticated user edited in merge mode.
+	PushUpdate(context.Context, note.PushUpdateParams) (*note.PushUpdateResult, error)
 }
 
 // Handler handles HTTP requests for note management endpoints.
@@ -40,8 +53,9 @@ func NewHandler(s Service) *Handler {
 // @Produce      json
 // @Security     BearerAuth
 // @Param        id path string true "Note ID" format(uuid)
+// @Param        fields query string false "Comma-separated fields to return; the ID is always returned"
 // @Success      200 {object} PullResponse "Note retrieved successfully"
-// @Failure      400 {object} response.Error "Bad request - invalid ID format"
+// @Failure      400 {object} response.Error "Bad request - invalid ID format or unknown field"
 // @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
 // @Failure      404 {object} response.Error "Not found - note not found"
 // @Failure      500 {object} response.Error "Internal server error"
@@ -69,7 +83,13 @@ func (h *Handler) Pull(c *gin.Context) {
 		return
 	}
 
-	n, err := h.s.Pull(c, note.PullParams{ID: pullingID, UserID: userID})
+	fields, err := extractor.Fields(fieldNames)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	n, err := h.s.Pull(c, note.PullParams{Fields: fields, ID: pullingID, UserID: userID})
 	if err != nil {
 		code, msgs := handleError(err, c)
 		c.JSON(code, response.Error{
@@ -78,7 +98,9 @@ func (h *Handler) Pull(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, PullResponse{Note: NewNoteFromApp(n)})
+	resp := PullResponse{Note: NewNoteFromApp(n)}
+	resp.Note.keepFields(fields)
+	c.JSON(http.StatusOK, resp)
 }
 
 // List retrieves all notes for the authenticated user.
@@ -88,8 +110,13 @@ func (h *Handler) Pull(c *gin.Context) {
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
+// @Param        fields query string false "Comma-separated fields to return; the ID is always returned"
+// @Param        include query string false "Comma-separated item groups to add; archived lists archived items too"
+// @Param        sort query string false "List order; manual lists pinned and arranged items first" Enums(manual)
+// @Param        folder query string false "Folder whose manual order sorts the list; the root when omitted"
 // @Success      200 {object} ListResponse "Notes retrieved successfully"
 // @Success      204 "No notes found"
+// @Failure      400 {object} response.Error "Bad request - unknown field, include or sort"
 // @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
 // @Failure      500 {object} response.Error "Internal server error"
 // @Router       /items/notes [get]
@@ -103,7 +130,31 @@ func (h *Handler) List(c *gin.Context) {
 		return
 	}
 
-	notes, err := h.s.List(c, note.ListParams{UserID: userID})
+	fields, err := extractor.Fields(fieldNames)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	include, err := extractor.Include([]string{consts.IncludeArchived})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	sort, err := extractor.Sort([]string{consts.SortManual})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	notes, err := h.s.List(c, note.ListParams{
+		Fields:          fields,
+		UserID:          userID,
+		OrderFolder:     extractor.Folder(),
+		ExcludeArchived: !slices.Contains(include, consts.IncludeArchived),
+		ManualOrder:     sort == consts.SortManual,
+	})
 	if err != nil {
 		code, msgs := handleError(err, c)
 		c.JSON(code, response.Error{
@@ -117,7 +168,11 @@ func (h *Handler) List(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, ListResponse{Notes: NewNotesFromApp(notes)})
+	resp := ListResponse{Notes: NewNotesFromApp(notes)}
+	for _, n := range resp.Notes {
+		n.keepFields(fields)
+	}
+	c.Render(http.StatusOK, jsonenc.JSON{Value: &resp})
 }
 
 // Push creates a new note or updates an existing one.
@@ -133,6 +188,7 @@ func (h *Handler) List(c *gin.Context) {
 // @Failure      400 {object} response.Error "Bad request - invalid input data"
 // @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
 // @Failure      404 {object} response.Error "Not found - note not found for update"
+// @Failure      409 {object} response.Error "Conflict - note text is edited in merge mode"
 // @Failure      500 {object} response.Error "Internal server error"
 // @Router       /items/notes [post]
 // @Router       /items/notes/{id} [put]
@@ -179,3 +235,108 @@ func (h *Handler) Push(c *gin.Context) {
 
 	c.JSON(http.StatusCreated, PushResponse{ID: newID})
 }
+
+// PullAsOf retrieves a specific note as it was at the requested moment.
+// @Summary      Get note at a past moment
+// @Description  Retrieves the version of a note that was current at the given moment
+// @Tags         Notes
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Note ID" format(uuid)
+// @Param        timestamp query string true "Moment to view (RFC 3339)" format(date-time)
+// @Success      200 {object} PullResponse "Note version retrieved successfully"
+// @Failure      400 {object} response.Error "Bad request - invalid ID or timestamp format"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
+// @Failure      404 {object} response.Error "Not found - no note version at the given moment"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /items/notes/{id}/as-of [get]
+// .
+func (h *Handler) PullAsOf(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	userID, err := extractor.UserID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+
+	noteID, req, ok := bindAsOfRequest(c, extractor)
+	if !ok {
+		return
+	}
+
+	n, err := h.s.Pull(c, note.PullParams{AsOf: req.Timestamp, ID: noteID, UserID: userID})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, PullResponse{Note: NewNoteFromApp(n)})
+}
+
+// Recover restores a note to the version that was current at the requested moment.
+// @Summary      Recover note version
+// @Description  Saves the note version current at the given moment as the latest version
+// @Tags         Notes
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Note ID" format(uuid)
+// @Param        timestamp query string true "Moment to recover to (RFC 3339)" format(date-time)
+// @Success      200 {object} RecoverResponse "Note recovered successfully"
+// @Failure      400 {object} response.Error "Bad request - invalid ID or timestamp format"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
+// @Failure      404 {object} response.Error "Not found - no note version at the given moment"
+// @Failure      409 {object} response.Error "Conflict - note text is edited in merge mode"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /items/notes/{id}/recover [post]
+// .
+func (h *Handler) Recover(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	userID, err := extractor.UserID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+
+	noteID, req, ok := bindAsOfRequest(c, extractor)
+	if !ok {
+		return
+	}
+
+	n, err := h.s.Recover(c, note.RecoverParams{AsOf: req.Timestamp, ID: noteID, UserID: userID})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, RecoverResponse{Note: NewNoteFromApp(n)})
+}
+
+// bindAsOfRequest extracts the note ID from the path and the moment from the query.
+// On failure it writes a bad request response and reports false.
+func bindAsOfRequest(c *gin.Context, extractor *util.CtxExtractor) (uuid.UUID, AsOfRequest, bool) {
+	// uriReq holds the deserialized URI parameters identifying the note.
+	var uriReq PullRequest
+	// req holds the deserialized query parameters selecting the moment.
+	var req AsOfRequest
+	if extractor.BindURI(&uriReq) != nil || extractor.BindQuery(&req) != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return uuid.Nil, req, false
+	}
+
+	id, err := uuid.Parse(uriReq.ID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return uuid.Nil, req, false
+	}
+	return id, req, true
+}
@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/note"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/response"
@@ -20,9 +21,14 @@ import (
 
 // mockService implements the Service interface for testing.
 type mockService struct {
-	pullFunc func(ctx context.Context, params note.PullParams) (*note.Note, error)
-	listFunc func(ctx context.Context, params note.ListParams) ([]*note.Note, error)
-	pushFunc func(ctx context.Context, params *note.PushParams) (uuid.UUID, error)
+	pullFunc    func(ctx context.Context, params note.PullParams) (*note.Note, error)
+	listFunc    func(ctx context.Context, params note.ListParams) ([]*note.Note, error)
+	pushFunc    func(ctx context.Context, params *note.PushParams) (uuid.UUID, error)
+	recoverFunc func(ctx context.Context, params note.RecoverParams) (*note.Note, error)
+	enableFunc  func(ctx context.Context, params note.MergeParams) ([]*note.Update, error)
+	disableFunc func(ctx context.Context, params note.MergeParams) error
+	updatesFunc func(ctx context.Context, params note.UpdatesParams) ([]*note.Update, error)
+	pushUpdFunc func(ctx context.Context, params note.PushUpdateParams) (*note.PushUpdateResult, error)
 }
 
 func (m *mockService) Pull(ctx context.Context, params note.PullParams) (*note.Note, error) {
@@ -46,6 +52,41 @@ func (m *mockService) Push(ctx context.Context, params *note.PushParams) (uuid.U
 	return uuid.Nil, errors.New("not implemented")
 }
 
+func (m *mockService) Recover(ctx context.Context, params note.RecoverParams) (*note.Note, error) {
+	if m.recoverFunc != nil {
+		return m.recoverFunc(ctx, params)
+	}
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockService) EnableMerge(ctx context.Context, params note.MergeParams) ([]*note.Update, error) {
+	if m.enableFunc != nil {
+		return m.enableFunc(ctx, params)
+	}
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockService) DisableMerge(ctx context.Context, params note.MergeParams) error {
+	if m.disableFunc != nil {
+		return m.disableFunc(ctx, params)
+	}
+	return errors.New("not implemented")
+}
+
+func (m *mockService) Updates(ctx context.Context, params note.UpdatesParams) ([]*note.Update, error) {
+	if m.updatesFunc != nil {
+		return m.updatesFunc(ctx, params)
+	}
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockService) PushUpdate(ctx context.Context, params note.PushUpdateParams) (*note.PushUpdateResult, error) {
+	if m.pushUpdFunc != nil {
+		return m.pushUpdFunc(ctx, params)
+	}
+	return nil, errors.New("not implemented")
+}
+
 func TestNewHandler(t *testing.T) {
 	t.Parallel()
 
@@ -231,6 +272,7 @@ func TestHandler_List(t *testing.T) {
 			mockSetup: func(m *mockService) {
 				m.listFunc = func(ctx context.Context, params note.ListParams) ([]*note.Note, error) {
 					assert.Equal(t, userID, params.UserID)
+					assert.True(t, params.ExcludeArchived)
 					return []*note.Note{
 						{
 							ID:          uuid.New(),
@@ -523,3 +565,250 @@ func TestHandler_Push(t *testing.T) {
 		})
 	}
 }
+
+func TestHandler_AsOfEndpoints(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+
+	userID := uuid.New()
+	itemID := uuid.New()
+	asOf := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	validQuery := "?timestamp=2024-01-02T03:04:05Z"
+
+	tests := []struct {
+		serviceErr     error
+		name           string
+		urlParam       string
+		query          string
+		setUser        bool
+		expectedStatus int
+	}{
+		{
+			name:           "success",
+			setUser:        true,
+			urlParam:       itemID.String(),
+			query:          validQuery,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "missing user ID",
+			urlParam:       itemID.String(),
+			query:          validQuery,
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:           "invalid UUID in path",
+			setUser:        true,
+			urlParam:       "invalid-uuid",
+			query:          validQuery,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "missing timestamp",
+			setUser:        true,
+			urlParam:       itemID.String(),
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid timestamp",
+			setUser:        true,
+			urlParam:       itemID.String(),
+			query:          "?timestamp=yesterday",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "version not found",
+			setUser:        true,
+			urlParam:       itemID.String(),
+			query:          validQuery,
+			serviceErr:     note.ErrNoteNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			checkParams := func(gotAsOf time.Time, gotID, gotUserID uuid.UUID) {
+				assert.True(t, asOf.Equal(gotAsOf))
+				assert.Equal(t, itemID, gotID)
+				assert.Equal(t, userID, gotUserID)
+			}
+			mockSvc := &mockService{
+				pullFunc: func(_ context.Context, params note.PullParams) (*note.Note, error) {
+					checkParams(params.AsOf, params.ID, params.UserID)
+					if tt.serviceErr != nil {
+						return nil, tt.serviceErr
+					}
+					return &note.Note{ID: params.ID, UserID: params.UserID}, nil
+				},
+				recoverFunc: func(_ context.Context, params note.RecoverParams) (*note.Note, error) {
+					checkParams(params.AsOf, params.ID, params.UserID)
+					if tt.serviceErr != nil {
+						return nil, tt.serviceErr
+					}
+					return &note.Note{ID: params.ID, UserID: params.UserID, UpdatedAt: time.Now()}, nil
+				},
+			}
+			handler := NewHandler(mockSvc)
+
+			endpoints := map[string]gin.HandlerFunc{
+				http.MethodGet:  handler.PullAsOf,
+				http.MethodPost: handler.Recover,
+			}
+			for method, handle := range endpoints {
+				w := httptest.NewRecorder()
+				c, _ := gin.CreateTestContext(w)
+				c.Request = httptest.NewRequest(method, "/"+tt.urlParam+"/as-of"+tt.query, nil)
+				c.Params = gin.Params{{Key: "id", Value: tt.urlParam}}
+				if tt.setUser {
+					c.Set("userID", userID)
+				}
+
+				handle(c)
+
+				assert.Equal(t, tt.expectedStatus, w.Code, method)
+			}
+		})
+	}
+}
+
+func TestHandler_PushUpdate(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+
+	userID := uuid.New()
+	noteID := uuid.New()
+	update := `{"ops":[{"delete":{"replica":"seed","counter":1}}]}`
+
+	tests := []struct {
+		serviceErr     error
+		name           string
+		urlParam       string
+		body           string
+		expectedStatus int
+	}{
+		{
+			name:           "success",
+			urlParam:       noteID.String(),
+			body:           `{"update":` + update + `}`,
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "invalid UUID in path",
+			urlParam:       "invalid-uuid",
+			body:           `{"update":` + update + `}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "missing update",
+			urlParam:       noteID.String(),
+			body:           `{}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid update",
+			urlParam:       noteID.String(),
+			body:           `{"update":` + update + `}`,
+			serviceErr:     note.ErrNoteUpdateInvalid,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "not in merge mode",
+			urlParam:       noteID.String(),
+			body:           `{"update":` + update + `}`,
+			serviceErr:     note.ErrNoteNotInMergeMode,
+			expectedStatus: http.StatusConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			handler := NewHandler(&mockService{
+				pushUpdFunc: func(_ context.Context, params note.PushUpdateParams) (*note.PushUpdateResult, error) {
+					assert.Equal(t, noteID, params.ID)
+					assert.Equal(t, userID, params.UserID)
+					assert.JSONEq(t, update, string(params.Data))
+					if tt.serviceErr != nil {
+						return nil, tt.serviceErr
+					}
+					return &note.PushUpdateResult{Note: &note.Note{ID: noteID, Note: "merged"}, Seq: 7}, nil
+				},
+			})
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/"+tt.urlParam+"/updates", bytes.NewBufferString(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Params = gin.Params{{Key: "id", Value: tt.urlParam}}
+			c.Set("userID", userID)
+
+			handler.PushUpdate(c)
+
+			require.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusCreated {
+				// resp holds the decoded response body.
+				var resp PushUpdateResponse
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+				assert.Equal(t, int64(7), resp.Seq)
+				assert.Equal(t, "merged", resp.Note.Note)
+			}
+		})
+	}
+}
+
+func TestHandler_Updates(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+
+	userID := uuid.New()
+	noteID := uuid.New()
+	snapshot := `{"snapshot":[{"id":{"replica":"seed","counter":1},"value":"a"}]}`
+
+	tests := []struct {
+		name           string
+		query          string
+		wantAfter      int64
+		expectedStatus int
+	}{
+		{name: "all updates", expectedStatus: http.StatusOK},
+		{name: "updates after a sequence number", query: "?after=41", wantAfter: 41, expectedStatus: http.StatusOK},
+		{name: "negative sequence number", query: "?after=-1", expectedStatus: http.StatusBadRequest},
+		{name: "invalid sequence number", query: "?after=latest", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			handler := NewHandler(&mockService{
+				updatesFunc: func(_ context.Context, params note.UpdatesParams) ([]*note.Update, error) {
+					assert.Equal(t, tt.wantAfter, params.AfterSeq)
+					return []*note.Update{{Data: []byte(snapshot), Seq: 42, Snapshot: true}}, nil
+				},
+			})
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/"+noteID.String()+"/updates"+tt.query, nil)
+			c.Params = gin.Params{{Key: "id", Value: noteID.String()}}
+			c.Set("userID", userID)
+
+			handler.Updates(c)
+
+			require.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				assert.JSONEq(t,
+					`{"updates":[{"update":`+snapshot+`,"seq":42,"snapshot":true}]}`,
+					w.Body.String(),
+				)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package note
+
+import (
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/jsonenc"
+	"github.com/google/uuid"
+)
+
+// AppendJSON appends the JSON encoding of the note, omitting zero fields like its struct tags.
+func (n *Note) AppendJSON(dst []byte) []byte {
+	if n == nil {
+		return jsonenc.Null(dst)
+	}
+	dst = append(dst, '{')
+	if !n.UpdatedAt.IsZero() {
+		dst = jsonenc.Time(jsonenc.Key(dst, "updated_at"), n.UpdatedAt)
+	}
+	if n.Note != "" {
+		dst = jsonenc.String(jsonenc.Key(dst, "note"), n.Note)
+	}
+	if n.Description != "" {
+		dst = jsonenc.String(jsonenc.Key(dst, "description"), n.Description)
+	}
+	if n.ID != uuid.Nil {
+		dst = jsonenc.UUID(jsonenc.Key(dst, "id"), n.ID)
+	}
+	if n.Archived {
+		dst = jsonenc.Bool(jsonenc.Key(dst, "archived"), n.Archived)
+	}
+	return append(dst, '}')
+}
+
+// AppendJSON appends the JSON encoding of the list response.
+func (r *ListResponse) AppendJSON(dst []byte) []byte {
+	if r == nil {
+		return jsonenc.Null(dst)
+	}
+	dst = append(dst, '{')
+	dst = jsonenc.Array(jsonenc.Key(dst, "notes"), r.Notes)
+	return append(dst, '}')
+}
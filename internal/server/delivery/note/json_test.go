@@ -0,0 +1,45 @@
+package note
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListResponse_AppendJSON(t *testing.T) {
+	t.Parallel()
+
+	full := &Note{
+		ID:          uuid.New(),
+		Note:        "Line 1\nLine 2\t<b>bold</b> & \"quoted\"  ",
+		Description: "Заметка",
+		Archived:    true,
+		UpdatedAt:   time.Date(2025, 5, 6, 7, 8, 9, 0, time.FixedZone("UTC-5", -5*60*60)),
+	}
+
+	tests := []struct {
+		resp *ListResponse
+		name string
+	}{
+		{name: "full", resp: &ListResponse{Notes: []*Note{full}}},
+		{name: "selected fields", resp: &ListResponse{Notes: []*Note{{ID: full.ID, UpdatedAt: full.UpdatedAt}}}},
+		{name: "zero note", resp: &ListResponse{Notes: []*Note{{}, nil}}},
+		{name: "empty", resp: &ListResponse{Notes: []*Note{}}},
+		{name: "nil list", resp: &ListResponse{}},
+		{name: "nil", resp: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			want, err := json.Marshal(tt.resp)
+			require.NoError(t, err)
+			assert.Equal(t, string(want), string(tt.resp.AppendJSON(nil)))
+		})
+	}
+}
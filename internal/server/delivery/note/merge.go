@@ -0,0 +1,222 @@
+package note
+
+import (
+	"net/http"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/note"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/response"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/util"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// EnableMerge switches a note to merge mode.
+// @Summary      Enable note merge mode
+// @Description  Switches a note to merge mode, seeding its replicated text with the current text, one element
+// @Description  per character made by the "seed" replica. Concurrent edits of several devices are then pushed as
+// @Description  text updates and merged instead of overwriting each other. Enabling it twice keeps the updates.
+// .
+// @Tags         Notes
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Note ID" format(uuid)
+// @Success      200 {object} UpdatesResponse "Merge mode enabled; the stored updates of the note"
+// @Failure      400 {object} response.Error "Bad request - invalid ID format"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
+// @Failure      403 {object} response.Error "Forbidden - access to this note is denied"
+// @Failure      404 {object} response.Error "Not found - note not found"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /items/notes/{id}/merge [post]
+// .
+func (h *Handler) EnableMerge(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	userID, err := extractor.UserID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+
+	noteID, ok := bindNoteID(c, extractor)
+	if !ok {
+		return
+	}
+
+	updates, err := h.s.EnableMerge(c, note.MergeParams{ID: noteID, UserID: userID})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, UpdatesResponse{Updates: NewUpdatesFromApp(updates)})
+}
+
+// DisableMerge switches a note back to plain edits.
+// @Summary      Disable note merge mode
+// @Description  Removes the text updates of a note and keeps the merged text, so the note is edited plainly again
+// @Tags         Notes
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Note ID" format(uuid)
+// @Success      204 "Merge mode disabled"
+// @Failure      400 {object} response.Error "Bad request - invalid ID format"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
+// @Failure      403 {object} response.Error "Forbidden - access to this note is denied"
+// @Failure      404 {object} response.Error "Not found - note not found"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /items/notes/{id}/merge [delete]
+// .
+func (h *Handler) DisableMerge(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	userID, err := extractor.UserID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+
+	noteID, ok := bindNoteID(c, extractor)
+	if !ok {
+		return
+	}
+
+	if err := h.s.DisableMerge(c, note.MergeParams{ID: noteID, UserID: userID}); err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// Updates retrieves the text updates of a note edited in merge mode.
+// @Summary      Get note text updates
+// @Description  Retrieves the text updates of a note stored after the given sequence number, in sequence order.
+// @Description  A snapshot replaces all updates up to its sequence number: clients that merged some of them
+// @Description  rebuild the text from the snapshot and merge the newer updates and their unsent edits again.
+// .
+// @Tags         Notes
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Note ID" format(uuid)
+// @Param        after query int false "Sequence number of the last merged update (0 pulls all updates)"
+// @Success      200 {object} UpdatesResponse "Text updates retrieved successfully"
+// @Failure      400 {object} response.Error "Bad request - invalid ID or sequence number"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
+// @Failure      404 {object} response.Error "Not found - note not found"
+// @Failure      409 {object} response.Error "Conflict - note is not edited in merge mode"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /items/notes/{id}/updates [get]
+// .
+func (h *Handler) Updates(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	userID, err := extractor.UserID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+
+	noteID, ok := bindNoteID(c, extractor)
+	if !ok {
+		return
+	}
+
+	// req holds the deserialized query parameters selecting the updates.
+	var req UpdatesRequest
+	if err := extractor.BindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	updates, err := h.s.Updates(c, note.UpdatesParams{ID: noteID, UserID: userID, AfterSeq: req.After})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, UpdatesResponse{Updates: NewUpdatesFromApp(updates)})
+}
+
+// PushUpdate merges a text update into a note edited in merge mode.
+// @Summary      Push note text update
+// @Description  Merges a list of insert and delete operations made on a device into the text of a note and saves
+// @Description  the merged text as the note text. Operations reference elements by replica and Lamport counter,
+// @Description  so concurrent edits of several devices merge; an update may be pushed more than once.
+// .
+// @Tags         Notes
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Note ID" format(uuid)
+// @Param        request body PushUpdateRequest true "Text update"
+// @Success      201 {object} PushUpdateResponse "Update merged successfully"
+// @Failure      400 {object} response.Error "Bad request - invalid ID format or text update"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
+// @Failure      403 {object} response.Error "Forbidden - access to this note is denied"
+// @Failure      404 {object} response.Error "Not found - note not found"
+// @Failure      409 {object} response.Error "Conflict - note is not edited in merge mode"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /items/notes/{id}/updates [post]
+// .
+func (h *Handler) PushUpdate(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	userID, err := extractor.UserID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+
+	noteID, ok := bindNoteID(c, extractor)
+	if !ok {
+		return
+	}
+
+	// req holds the deserialized JSON request payload with the update.
+	var req PushUpdateRequest
+	if err := extractor.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	res, err := h.s.PushUpdate(c, note.PushUpdateParams{Data: req.Update, ID: noteID, UserID: userID})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, PushUpdateResponse{Note: NewNoteFromApp(res.Note), Seq: res.Seq})
+}
+
+// bindNoteID extracts the note ID from the path.
+// On failure it writes a bad request response and reports false.
+func bindNoteID(c *gin.Context, extractor *util.CtxExtractor) (uuid.UUID, bool) {
+	// req holds the deserialized URI parameters identifying the note.
+	var req PullRequest
+	if err := extractor.BindURI(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return uuid.Nil, false
+	}
+
+	id, err := uuid.Parse(req.ID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return uuid.Nil, false
+	}
+	return id, true
+}
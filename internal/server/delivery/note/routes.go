@@ -1,14 +1,24 @@
 package note
 
-import "github.com/gin-gonic/gin"
+import (
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/util"
+	"github.com/gin-gonic/gin"
+)
 
 // RegisterRoutes registers note management routes with the provided router group.
-func RegisterRoutes(r *gin.RouterGroup, h *Handler) {
+// The reveal middleware runs on the routes returning the content of a single note.
+func RegisterRoutes(r *gin.RouterGroup, h *Handler, reveal ...gin.HandlerFunc) {
 	notesGroup := r.Group("/notes")
 	notesGroup.POST("", h.Push)
 	notesGroup.GET("", h.List)
 
 	notesIDGroup := notesGroup.Group("/:id")
-	notesIDGroup.GET("", h.Pull)
+	notesIDGroup.GET("", util.Chain(reveal, h.Pull)...)
 	notesIDGroup.PUT("", h.Push)
+	notesIDGroup.GET("/as-of", util.Chain(reveal, h.PullAsOf)...)
+	notesIDGroup.POST("/recover", h.Recover)
+	notesIDGroup.POST("/merge", h.EnableMerge)
+	notesIDGroup.DELETE("/merge", h.DisableMerge)
+	notesIDGroup.GET("/updates", h.Updates)
+	notesIDGroup.POST("/updates", h.PushUpdate)
 }
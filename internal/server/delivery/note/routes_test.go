@@ -137,6 +137,12 @@ func TestRegisterRoutes_RouteStructure(t *testing.T) {
 		{http.MethodGet, "/api/v1/notes"},
 		{http.MethodGet, "/api/v1/notes/:id"},
 		{http.MethodPut, "/api/v1/notes/:id"},
+		{http.MethodGet, "/api/v1/notes/:id/as-of"},
+		{http.MethodPost, "/api/v1/notes/:id/recover"},
+		{http.MethodPost, "/api/v1/notes/:id/merge"},
+		{http.MethodDelete, "/api/v1/notes/:id/merge"},
+		{http.MethodGet, "/api/v1/notes/:id/updates"},
+		{http.MethodPost, "/api/v1/notes/:id/updates"},
 	}
 
 	// Verify all expected routes are registered
@@ -151,7 +157,7 @@ func TestRegisterRoutes_RouteStructure(t *testing.T) {
 		assert.True(t, found, "Expected route %s %s not found", expected.method, expected.path)
 	}
 
-	// Verify no unexpected routes are registered (should have exactly 4 routes)
+	// Verify no unexpected routes are registered
 	noteRoutes := 0
 	for _, route := range routes {
 		if len(route.Path) > 8 && route.Path[:9] == "/api/v1/n" {
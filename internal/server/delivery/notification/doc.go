@@ -0,0 +1,6 @@
+// Package notification provides HTTP handlers for notification endpoints in the AegisVaultKeeper server.
+//
+// This package lets authenticated users choose the channels, such as e-mail, Telegram or Slack,
+// that security alerts, sharing invitations and reminders are delivered to, and subscribe their
+// browsers to web push messages for those notifications and for vault sync triggers.
+package notification
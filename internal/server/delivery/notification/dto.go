@@ -0,0 +1,146 @@
+package notification
+
+import (
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/notification"
+	"github.com/google/uuid"
+)
+
+// Channel represents a notification channel without its target.
+type Channel struct {
+	// UpdatedAt contains the timestamp of the last change of the channel.
+	UpdatedAt time.Time `json:"updated_at" example:"2023-12-01T10:00:00Z"`
+	// Kind contains the delivery mechanism (email, telegram or slack).
+	Kind string `json:"kind"       example:"telegram"`
+	// Hint contains a non-secret description of the target, such as the address or the webhook host.
+	Hint string `json:"hint"       example:"123456789"`
+	// Categories contains the notification categories delivered to the channel.
+	Categories []string `json:"categories" example:"security,reminders"`
+}
+
+// NewChannelFromApp converts an application layer channel to delivery DTO.
+func NewChannelFromApp(c *notification.Channel) *Channel {
+	if c == nil {
+		return nil
+	}
+	return &Channel{
+		Kind:       c.Kind,
+		Hint:       c.Hint,
+		Categories: c.Categories,
+		UpdatedAt:  c.UpdatedAt,
+	}
+}
+
+// NewChannelsFromApp converts application layer channels to delivery DTOs.
+func NewChannelsFromApp(cs []*notification.Channel) []*Channel {
+	result := make([]*Channel, 0, len(cs))
+	for _, c := range cs {
+		result = append(result, NewChannelFromApp(c))
+	}
+	return result
+}
+
+// ListChannelsResponse represents the response containing the notification channels of the user.
+type ListChannelsResponse struct {
+	// Channels contains the channels ordered by kind.
+	Channels []*Channel `json:"channels"`
+}
+
+// KindRequest represents the channel kind addressed in the request path.
+type KindRequest struct {
+	// Kind contains the delivery mechanism (required: email, telegram or slack).
+	Kind string `uri:"kind" binding:"required" example:"telegram"`
+}
+
+// SetChannelRequest represents the request to add or change a notification channel.
+type SetChannelRequest struct {
+	// Target contains the e-mail address, Telegram chat ID or Slack webhook URL (required).
+	Target string `json:"target"     binding:"required" example:"123456789"`
+	// Categories contains the notification categories to deliver: security, sharing and reminders (required).
+	Categories []string `json:"categories" binding:"required" example:"security,reminders"`
+}
+
+// PushSubscription represents a device push subscription without its endpoint and keys.
+type PushSubscription struct {
+	// CreatedAt contains the timestamp of the subscription of the device.
+	CreatedAt time.Time `json:"created_at" example:"2023-12-01T10:00:00Z"`
+	// UpdatedAt contains the timestamp of the last renewal of the subscription.
+	UpdatedAt time.Time `json:"updated_at" example:"2023-12-01T10:00:00Z"`
+	// Device contains the name the user recognizes the device by.
+	Device string `json:"device"     example:"Firefox on laptop"`
+	// Hint contains the host of the push service serving the device.
+	Hint string `json:"hint"       example:"updates.push.services.mozilla.com"`
+	// Categories contains the notification categories delivered to the device.
+	Categories []string `json:"categories" example:"security"`
+	// ID contains the unique identifier of the subscription.
+	ID uuid.UUID `json:"id"         example:"123e4567-e89b-12d3-a456-426614174000"`
+}
+
+// NewPushSubscriptionFromApp converts an application layer push subscription to delivery DTO.
+func NewPushSubscriptionFromApp(s *notification.PushSubscription) *PushSubscription {
+	if s == nil {
+		return nil
+	}
+	return &PushSubscription{
+		ID:         s.ID,
+		Device:     s.Device,
+		Hint:       s.Hint,
+		Categories: s.Categories,
+		CreatedAt:  s.CreatedAt,
+		UpdatedAt:  s.UpdatedAt,
+	}
+}
+
+// NewPushSubscriptionsFromApp converts application layer push subscriptions to delivery DTOs.
+func NewPushSubscriptionsFromApp(ss []*notification.PushSubscription) []*PushSubscription {
+	result := make([]*PushSubscription, 0, len(ss))
+	for _, s := range ss {
+		result = append(result, NewPushSubscriptionFromApp(s))
+	}
+	return result
+}
+
+// ListPushSubscriptionsResponse represents the response containing the push subscriptions of the user.
+type ListPushSubscriptionsResponse struct {
+	// Subscriptions contains the subscriptions ordered by the time the devices subscribed.
+	Subscriptions []*PushSubscription `json:"subscriptions"`
+}
+
+// VAPIDKeyResponse represents the response containing the application server key of the server.
+type VAPIDKeyResponse struct {
+	// PublicKey contains the base64url-encoded VAPID public key to pass to PushManager.subscribe.
+	PublicKey string `json:"public_key" example:"BNcRdreALRFXTkOOUHK1EtK2wtaz5Ry4YfYCA_0QTpQt"`
+}
+
+// PushKeysRequest represents the keys of a browser push subscription.
+type PushKeysRequest struct {
+	// P256dh contains the base64url-encoded P-256 public key of the browser (required).
+	P256dh string `json:"p256dh" binding:"required" example:"BCVxsr7N_eNgVRqvHtD0zTZsEc6-VV-JvLexhqUzORcx"`
+	// Auth contains the base64url-encoded authentication secret of the browser (required).
+	Auth string `json:"auth"   binding:"required" example:"BTBZMqHH6r4Tts7J_aSIgg"`
+}
+
+// PushSubscriptionRequest represents a browser push subscription as returned by PushSubscription.toJSON.
+type PushSubscriptionRequest struct {
+	// Endpoint contains the push service URL of the subscription (required).
+	Endpoint string `json:"endpoint" binding:"required" example:"https://updates.push.services.mozilla.com/wpush/v2/abc"`
+	// Keys contains the encryption keys of the subscription.
+	Keys PushKeysRequest `json:"keys"`
+}
+
+// SubscribeRequest represents the request to register the push subscription of a device.
+type SubscribeRequest struct {
+	// Device contains the name the user recognizes the device by (required).
+	Device string `json:"device"       binding:"required" example:"Firefox on laptop"`
+	// Subscription contains the browser push subscription.
+	Subscription PushSubscriptionRequest `json:"subscription"`
+	// Categories contains the notification categories to deliver; empty receives sync messages only.
+	Categories []string `json:"categories"                    example:"security"`
+}
+
+// PushSubscriptionIDRequest represents the push subscription addressed in the request path.
+type PushSubscriptionIDRequest struct {
+	// ID contains the unique identifier of the subscription (required).
+	ID string `uri:"id" binding:"required,uuid" example:"123e4567-e89b-12d3-a456-426614174000"`
+}
@@ -0,0 +1,176 @@
+package notification
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/notification"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/response"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/util"
+	"github.com/gin-gonic/gin"
+)
+
+// Service defines the notification application service interface.
+type Service interface {
+	// List retrieves the notification channels of the user.
+	List(context.Context, notification.ListParams) ([]*notification.Channel, error)
+	// Set adds or changes the notification channel of a kind.
+	Set(context.Context, notification.SetParams) (*notification.Channel, error)
+	// Delete removes the notification channel of a kind.
+	Delete(context.Context, notification.DeleteParams) error
+	// PushPublicKey returns the VAPID public key browsers subscribe with.
+	PushPublicKey(context.Context) (string, error)
+	// ListPushSubscriptions retrieves the push subscriptions of the user's devices.
+	ListPushSubscriptions(context.Context, notification.ListParams) ([]*notification.PushSubscription, error)
+	// Subscribe registers or renews the push subscription of a device.
+	Subscribe(context.Context, notification.SubscribeParams) (*notification.PushSubscription, error)
+	// Unsubscribe removes the push subscription of a device.
+	Unsubscribe(context.Context, notification.UnsubscribeParams) error
+}
+
+// Handler handles HTTP requests for notification channel and push subscription endpoints.
+type Handler struct {
+	// s is the notification service used to process operations.
+	s Service
+}
+
+// NewHandler creates a new notification handler with the provided service.
+func NewHandler(s Service) *Handler {
+	return &Handler{s: s}
+}
+
+// List retrieves the notification channels of the authenticated user.
+// @Summary      List notification channels
+// @Description  Retrieves the channels notifications are delivered to, with hints instead of their targets
+// @Tags         Account
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} ListChannelsResponse "Notification channels retrieved successfully"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /account/notification-channels [get]
+// .
+func (h *Handler) List(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	userID, err := extractor.UserID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+
+	channels, err := h.s.List(c, notification.ListParams{UserID: userID})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, ListChannelsResponse{Channels: NewChannelsFromApp(channels)})
+}
+
+// Set adds or changes a notification channel of the authenticated user.
+// @Summary      Set notification channel
+// @Description  Adds the channel of the kind or replaces its target and categories. A user has at most
+// @Description  one channel of each kind. Users without any channel or push subscription are notified
+// @Description  at their e-mail login.
+// .
+// @Tags         Account
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        kind path string true "Channel kind" Enums(email, telegram, slack)
+// @Param        request body SetChannelRequest true "Channel data"
+// @Success      200 {object} Channel "Notification channel saved successfully"
+// @Failure      400 {object} response.Error "Bad request - invalid kind, target or categories"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
+// @Failure      422 {object} response.Error "Unprocessable entity - channel kind not configured on the server"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /account/notification-channels/{kind} [put]
+// .
+func (h *Handler) Set(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	userID, err := extractor.UserID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+
+	// uri holds the deserialized URI parameters naming the channel kind.
+	var uri KindRequest
+	if err := extractor.BindURI(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	// req holds the deserialized JSON request payload for the channel.
+	var req SetChannelRequest
+	if err := extractor.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	channel, err := h.s.Set(c, notification.SetParams{
+		Kind:       uri.Kind,
+		Target:     req.Target,
+		Categories: req.Categories,
+		UserID:     userID,
+	})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, NewChannelFromApp(channel))
+}
+
+// Delete removes a notification channel of the authenticated user.
+// @Summary      Delete notification channel
+// @Description  Removes the channel of the kind. Users left without channels and push subscriptions are
+// @Description  notified at their e-mail login.
+// .
+// @Tags         Account
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        kind path string true "Channel kind" Enums(email, telegram, slack)
+// @Success      204 "Notification channel deleted successfully"
+// @Failure      400 {object} response.Error "Bad request - invalid kind"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
+// @Failure      404 {object} response.Error "Not found - notification channel not found"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /account/notification-channels/{kind} [delete]
+// .
+func (h *Handler) Delete(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	userID, err := extractor.UserID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+
+	// req holds the deserialized URI parameters for the delete request.
+	var req KindRequest
+	if err := extractor.BindURI(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	if err := h.s.Delete(c, notification.DeleteParams{Kind: req.Kind, UserID: userID}); err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.Data(http.StatusNoContent, "", nil)
+}
@@ -0,0 +1,316 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/notification"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/consts"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockNotificationService implements Service for testing.
+type mockNotificationService struct {
+	listFunc     func(ctx context.Context, params notification.ListParams) ([]*notification.Channel, error)
+	setFunc      func(ctx context.Context, params notification.SetParams) (*notification.Channel, error)
+	deleteFunc   func(ctx context.Context, params notification.DeleteParams) error
+	keyFunc      func(ctx context.Context) (string, error)
+	listPushFunc func(
+		ctx context.Context,
+		params notification.ListParams,
+	) ([]*notification.PushSubscription, error)
+	subscribeFunc func(
+		ctx context.Context,
+		params notification.SubscribeParams,
+	) (*notification.PushSubscription, error)
+	unsubscribeFunc func(ctx context.Context, params notification.UnsubscribeParams) error
+}
+
+func (m *mockNotificationService) List(
+	ctx context.Context,
+	params notification.ListParams,
+) ([]*notification.Channel, error) {
+	if m.listFunc != nil {
+		return m.listFunc(ctx, params)
+	}
+	return nil, nil
+}
+
+func (m *mockNotificationService) Set(
+	ctx context.Context,
+	params notification.SetParams,
+) (*notification.Channel, error) {
+	if m.setFunc != nil {
+		return m.setFunc(ctx, params)
+	}
+	return nil, nil
+}
+
+func (m *mockNotificationService) Delete(ctx context.Context, params notification.DeleteParams) error {
+	if m.deleteFunc != nil {
+		return m.deleteFunc(ctx, params)
+	}
+	return nil
+}
+
+func (m *mockNotificationService) PushPublicKey(ctx context.Context) (string, error) {
+	if m.keyFunc != nil {
+		return m.keyFunc(ctx)
+	}
+	return "", nil
+}
+
+func (m *mockNotificationService) ListPushSubscriptions(
+	ctx context.Context,
+	params notification.ListParams,
+) ([]*notification.PushSubscription, error) {
+	if m.listPushFunc != nil {
+		return m.listPushFunc(ctx, params)
+	}
+	return nil, nil
+}
+
+func (m *mockNotificationService) Subscribe(
+	ctx context.Context,
+	params notification.SubscribeParams,
+) (*notification.PushSubscription, error) {
+	if m.subscribeFunc != nil {
+		return m.subscribeFunc(ctx, params)
+	}
+	return nil, nil
+}
+
+func (m *mockNotificationService) Unsubscribe(ctx context.Context, params notification.UnsubscribeParams) error {
+	if m.unsubscribeFunc != nil {
+		return m.unsubscribeFunc(ctx, params)
+	}
+	return nil
+}
+
+func TestHandler_List(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	updatedAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		mockService    *mockNotificationService
+		want           *ListChannelsResponse
+		name           string
+		setUser        bool
+		expectedStatus int
+	}{
+		{
+			name:    "success",
+			setUser: true,
+			mockService: &mockNotificationService{
+				listFunc: func(_ context.Context, params notification.ListParams) ([]*notification.Channel, error) {
+					assert.Equal(t, userID, params.UserID)
+					return []*notification.Channel{{
+						Kind:       "slack",
+						Hint:       "hooks.slack.com",
+						Categories: []string{"security"},
+						UpdatedAt:  updatedAt,
+					}}, nil
+				},
+			},
+			want: &ListChannelsResponse{Channels: []*Channel{{
+				Kind:       "slack",
+				Hint:       "hooks.slack.com",
+				Categories: []string{"security"},
+				UpdatedAt:  updatedAt,
+			}}},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "missing user context",
+			mockService:    &mockNotificationService{},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:    "service error",
+			setUser: true,
+			mockService: &mockNotificationService{
+				listFunc: func(context.Context, notification.ListParams) ([]*notification.Channel, error) {
+					return nil, notification.ErrNotificationTechError
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/account/notification-channels", nil)
+			if tt.setUser {
+				c.Set(consts.CtxKeyUserID, userID)
+			}
+
+			NewHandler(tt.mockService).List(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.want == nil {
+				return
+			}
+			var got ListChannelsResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+			assert.Equal(t, *tt.want, got)
+		})
+	}
+}
+
+func TestHandler_Set(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+
+	tests := []struct {
+		mockService    *mockNotificationService
+		name           string
+		kind           string
+		body           string
+		wantHint       string
+		expectedStatus int
+	}{
+		{
+			name: "success",
+			kind: "telegram",
+			body: `{"target":"42","categories":["security","reminders"]}`,
+			mockService: &mockNotificationService{
+				setFunc: func(_ context.Context, params notification.SetParams) (*notification.Channel, error) {
+					assert.Equal(t, notification.SetParams{
+						Kind:       "telegram",
+						Target:     "42",
+						Categories: []string{"security", "reminders"},
+						UserID:     userID,
+					}, params)
+					return &notification.Channel{Kind: params.Kind, Hint: params.Target, Categories: params.Categories}, nil
+				},
+			},
+			wantHint:       "42",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "missing target",
+			kind:           "telegram",
+			body:           `{"categories":["security"]}`,
+			mockService:    &mockNotificationService{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "invalid target",
+			kind: "telegram",
+			body: `{"target":"@alice","categories":["security"]}`,
+			mockService: &mockNotificationService{
+				setFunc: func(context.Context, notification.SetParams) (*notification.Channel, error) {
+					return nil, errors.Join(notification.ErrNotificationAppError, notification.ErrNotificationIncorrectTarget)
+				},
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "kind not configured",
+			kind: "email",
+			body: `{"target":"{}","categories":["security"]}`,
+			mockService: &mockNotificationService{
+				setFunc: func(context.Context, notification.SetParams) (*notification.Channel, error) {
+					return nil, notification.ErrNotificationChannelUnavailable
+				},
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(
+				http.MethodPut,
+				"/account/notification-channels/"+tt.kind,
+				strings.NewReader(tt.body),
+			)
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Params = gin.Params{{Key: "kind", Value: tt.kind}}
+			c.Set(consts.CtxKeyUserID, userID)
+
+			NewHandler(tt.mockService).Set(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.wantHint == "" {
+				return
+			}
+			var got Channel
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+			assert.Equal(t, tt.kind, got.Kind)
+			assert.Equal(t, tt.wantHint, got.Hint)
+		})
+	}
+}
+
+func TestHandler_Delete(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+
+	tests := []struct {
+		mockService    *mockNotificationService
+		name           string
+		expectedStatus int
+	}{
+		{
+			name: "success",
+			mockService: &mockNotificationService{
+				deleteFunc: func(_ context.Context, params notification.DeleteParams) error {
+					assert.Equal(t, notification.DeleteParams{Kind: "slack", UserID: userID}, params)
+					return nil
+				},
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name: "not found",
+			mockService: &mockNotificationService{
+				deleteFunc: func(context.Context, notification.DeleteParams) error {
+					return notification.ErrNotificationChannelNotFound
+				},
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodDelete, "/account/notification-channels/slack", nil)
+			c.Params = gin.Params{{Key: "kind", Value: "slack"}}
+			c.Set(consts.CtxKeyUserID, userID)
+
+			NewHandler(tt.mockService).Delete(c)
+
+			c.Writer.WriteHeaderNow()
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
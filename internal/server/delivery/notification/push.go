@@ -0,0 +1,171 @@
+package notification
+
+import (
+	"net/http"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/notification"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/response"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/util"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// VAPIDKey returns the application server key browsers subscribe with.
+// @Summary      Get VAPID public key
+// @Description  Returns the key to pass as applicationServerKey to PushManager.subscribe
+// @Tags         Account
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} VAPIDKeyResponse "VAPID public key retrieved successfully"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
+// @Failure      422 {object} response.Error "Unprocessable entity - web push not configured on the server"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /account/push-subscriptions/vapid-key [get]
+// .
+func (h *Handler) VAPIDKey(c *gin.Context) {
+	key, err := h.s.PushPublicKey(c)
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, VAPIDKeyResponse{PublicKey: key})
+}
+
+// ListPushSubscriptions retrieves the push subscriptions of the authenticated user's devices.
+// @Summary      List push subscriptions
+// @Description  Retrieves the devices receiving web push messages, with push service hosts instead of endpoints
+// @Tags         Account
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} ListPushSubscriptionsResponse "Push subscriptions retrieved successfully"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /account/push-subscriptions [get]
+// .
+func (h *Handler) ListPushSubscriptions(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	userID, err := extractor.UserID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+
+	subscriptions, err := h.s.ListPushSubscriptions(c, notification.ListParams{UserID: userID})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, ListPushSubscriptionsResponse{Subscriptions: NewPushSubscriptionsFromApp(subscriptions)})
+}
+
+// Subscribe registers the push subscription of a device of the authenticated user.
+// @Summary      Subscribe device to web push
+// @Description  Registers the browser push subscription of the device, or renews it when the endpoint is already
+// @Description  registered. The device receives the listed notification categories and sync messages whenever
+// @Description  the vault changes on another device.
+// .
+// @Tags         Account
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body SubscribeRequest true "Push subscription data"
+// @Success      201 {object} PushSubscription "Push subscription saved successfully"
+// @Failure      400 {object} response.Error "Bad request - invalid device, subscription or categories"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
+// @Failure      422 {object} response.Error "Unprocessable entity - web push not configured on the server"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /account/push-subscriptions [post]
+// .
+func (h *Handler) Subscribe(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	userID, err := extractor.UserID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+
+	// req holds the deserialized JSON request payload for the subscription.
+	var req SubscribeRequest
+	if err := extractor.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	subscription, err := h.s.Subscribe(c, notification.SubscribeParams{
+		Device:     req.Device,
+		Endpoint:   req.Subscription.Endpoint,
+		P256dh:     req.Subscription.Keys.P256dh,
+		Auth:       req.Subscription.Keys.Auth,
+		Categories: req.Categories,
+		UserID:     userID,
+	})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, NewPushSubscriptionFromApp(subscription))
+}
+
+// Unsubscribe removes a push subscription of the authenticated user.
+// @Summary      Unsubscribe device from web push
+// @Description  Removes the push subscription; the device stops receiving notifications and sync messages
+// @Tags         Account
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Push subscription ID"
+// @Success      204 "Push subscription deleted successfully"
+// @Failure      400 {object} response.Error "Bad request - invalid subscription ID"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
+// @Failure      404 {object} response.Error "Not found - push subscription not found"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /account/push-subscriptions/{id} [delete]
+// .
+func (h *Handler) Unsubscribe(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	userID, err := extractor.UserID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+
+	// req holds the deserialized URI parameters for the unsubscribe request.
+	var req PushSubscriptionIDRequest
+	if err := extractor.BindURI(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	id, err := uuid.Parse(req.ID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	if err := h.s.Unsubscribe(c, notification.UnsubscribeParams{ID: id, UserID: userID}); err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.Data(http.StatusNoContent, "", nil)
+}
@@ -0,0 +1,283 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/notification"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/consts"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_VAPIDKey(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		mockService    *mockNotificationService
+		name           string
+		wantKey        string
+		expectedStatus int
+	}{
+		{
+			name: "success",
+			mockService: &mockNotificationService{
+				keyFunc: func(context.Context) (string, error) { return "BPublicKey", nil },
+			},
+			wantKey:        "BPublicKey",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "web push not configured",
+			mockService: &mockNotificationService{
+				keyFunc: func(context.Context) (string, error) {
+					return "", notification.ErrNotificationChannelUnavailable
+				},
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/account/push-subscriptions/vapid-key", nil)
+
+			NewHandler(tt.mockService).VAPIDKey(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.wantKey == "" {
+				return
+			}
+			var got VAPIDKeyResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+			assert.Equal(t, tt.wantKey, got.PublicKey)
+		})
+	}
+}
+
+func TestHandler_ListPushSubscriptions(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	subscriptionID := uuid.New()
+
+	tests := []struct {
+		mockService    *mockNotificationService
+		name           string
+		wantCount      int
+		setUser        bool
+		expectedStatus int
+	}{
+		{
+			name:    "success",
+			setUser: true,
+			mockService: &mockNotificationService{
+				listPushFunc: func(
+					_ context.Context,
+					params notification.ListParams,
+				) ([]*notification.PushSubscription, error) {
+					assert.Equal(t, userID, params.UserID)
+					return []*notification.PushSubscription{{ID: subscriptionID, Device: "Laptop"}}, nil
+				},
+			},
+			wantCount:      1,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "missing user context",
+			mockService:    &mockNotificationService{},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:    "service error",
+			setUser: true,
+			mockService: &mockNotificationService{
+				listPushFunc: func(context.Context, notification.ListParams) ([]*notification.PushSubscription, error) {
+					return nil, notification.ErrNotificationTechError
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/account/push-subscriptions", nil)
+			if tt.setUser {
+				c.Set(consts.CtxKeyUserID, userID)
+			}
+
+			NewHandler(tt.mockService).ListPushSubscriptions(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.wantCount == 0 {
+				return
+			}
+			var got ListPushSubscriptionsResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+			require.Len(t, got.Subscriptions, tt.wantCount)
+			assert.Equal(t, subscriptionID, got.Subscriptions[0].ID)
+			assert.Equal(t, "Laptop", got.Subscriptions[0].Device)
+		})
+	}
+}
+
+func TestHandler_Subscribe(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	validBody := `{"device":"Laptop","subscription":{"endpoint":"https://push.example.com/abc",` +
+		`"keys":{"p256dh":"BKey","auth":"Secret"}},"categories":["security"]}`
+
+	tests := []struct {
+		mockService    *mockNotificationService
+		name           string
+		body           string
+		expectedStatus int
+	}{
+		{
+			name: "success",
+			body: validBody,
+			mockService: &mockNotificationService{
+				subscribeFunc: func(
+					_ context.Context,
+					params notification.SubscribeParams,
+				) (*notification.PushSubscription, error) {
+					assert.Equal(t, notification.SubscribeParams{
+						Device:     "Laptop",
+						Endpoint:   "https://push.example.com/abc",
+						P256dh:     "BKey",
+						Auth:       "Secret",
+						Categories: []string{"security"},
+						UserID:     userID,
+					}, params)
+					return &notification.PushSubscription{ID: uuid.New(), Device: params.Device}, nil
+				},
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "missing keys",
+			body:           `{"device":"Laptop","subscription":{"endpoint":"https://push.example.com/abc"}}`,
+			mockService:    &mockNotificationService{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "invalid subscription",
+			body: validBody,
+			mockService: &mockNotificationService{
+				subscribeFunc: func(context.Context, notification.SubscribeParams) (*notification.PushSubscription, error) {
+					return nil, errors.Join(
+						notification.ErrNotificationAppError,
+						notification.ErrNotificationIncorrectSubscription,
+					)
+				},
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "web push not configured",
+			body: validBody,
+			mockService: &mockNotificationService{
+				subscribeFunc: func(context.Context, notification.SubscribeParams) (*notification.PushSubscription, error) {
+					return nil, notification.ErrNotificationChannelUnavailable
+				},
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/account/push-subscriptions", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Set(consts.CtxKeyUserID, userID)
+
+			NewHandler(tt.mockService).Subscribe(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestHandler_Unsubscribe(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	subscriptionID := uuid.New()
+
+	tests := []struct {
+		mockService    *mockNotificationService
+		name           string
+		id             string
+		expectedStatus int
+	}{
+		{
+			name: "success",
+			id:   subscriptionID.String(),
+			mockService: &mockNotificationService{
+				unsubscribeFunc: func(_ context.Context, params notification.UnsubscribeParams) error {
+					assert.Equal(t, notification.UnsubscribeParams{ID: subscriptionID, UserID: userID}, params)
+					return nil
+				},
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "invalid ID",
+			id:             "laptop",
+			mockService:    &mockNotificationService{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "not found",
+			id:   subscriptionID.String(),
+			mockService: &mockNotificationService{
+				unsubscribeFunc: func(context.Context, notification.UnsubscribeParams) error {
+					return notification.ErrNotificationPushSubscriptionNotFound
+				},
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodDelete, "/account/push-subscriptions/"+tt.id, nil)
+			c.Params = gin.Params{{Key: "id", Value: tt.id}}
+			c.Set(consts.CtxKeyUserID, userID)
+
+			NewHandler(tt.mockService).Unsubscribe(c)
+
+			c.Writer.WriteHeaderNow()
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
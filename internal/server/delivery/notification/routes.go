@@ -0,0 +1,19 @@
+package notification
+
+import "github.com/gin-gonic/gin"
+
+// RegisterRoutes registers notification channel and push subscription routes with the provided router group.
+// Creates /notification-channels, /notification-channels/:kind, /push-subscriptions,
+// /push-subscriptions/vapid-key and /push-subscriptions/:id with the specified handler.
+func RegisterRoutes(r *gin.RouterGroup, h *Handler) {
+	channelsGroup := r.Group("/notification-channels")
+	channelsGroup.GET("", h.List)
+	channelsGroup.PUT("/:kind", h.Set)
+	channelsGroup.DELETE("/:kind", h.Delete)
+
+	pushGroup := r.Group("/push-subscriptions")
+	pushGroup.GET("", h.ListPushSubscriptions)
+	pushGroup.POST("", h.Subscribe)
+	pushGroup.GET("/vapid-key", h.VAPIDKey)
+	pushGroup.DELETE("/:id", h.Unsubscribe)
+}
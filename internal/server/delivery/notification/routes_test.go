@@ -0,0 +1,30 @@
+package notification
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterRoutes(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	RegisterRoutes(router.Group("/account"), NewHandler(&mockNotificationService{}))
+
+	got := make(map[string]string)
+	for _, r := range router.Routes() {
+		got[r.Method+" "+r.Path] = r.Handler
+	}
+	assert.Len(t, got, 7)
+	assert.Contains(t, got, http.MethodGet+" /account/notification-channels")
+	assert.Contains(t, got, http.MethodPut+" /account/notification-channels/:kind")
+	assert.Contains(t, got, http.MethodDelete+" /account/notification-channels/:kind")
+	assert.Contains(t, got, http.MethodGet+" /account/push-subscriptions")
+	assert.Contains(t, got, http.MethodPost+" /account/push-subscriptions")
+	assert.Contains(t, got, http.MethodGet+" /account/push-subscriptions/vapid-key")
+	assert.Contains(t, got, http.MethodDelete+" /account/push-subscriptions/:id")
+}
@@ -0,0 +1,5 @@
+// Package onboarding provides HTTP handlers for the onboarding wizard endpoints in the AegisVaultKeeper server.
+//
+// This package reports the steps of the first-run wizard of a server with an empty database and runs them,
+// ending with the creation of the first account.
+package onboarding
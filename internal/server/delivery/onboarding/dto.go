@@ -0,0 +1,99 @@
+package onboarding
+
+import (
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/onboarding"
+	"github.com/google/uuid"
+)
+
+// StepResponse represents an onboarding step with the result of its last run.
+type StepResponse struct {
+	// CheckedAt indicates when the step last ran; omitted for pending steps.
+	CheckedAt time.Time `json:"checked_at,omitzero"  example:"2024-01-15T10:30:00Z"`
+	// Name identifies the step: master_key, storage, smtp or admin.
+	Name string `json:"name"                 example:"storage"`
+	// Title names the step for display.
+	Title string `json:"title"                example:"File storage"`
+	// Description explains what the step does.
+	Description string `json:"description"          example:"Writes, reads back and removes a probe file."`
+	// Status contains the state of the step: pending, passed, failed or skipped.
+	Status string `json:"status"               example:"passed"`
+	// Message explains the state to the operator.
+	Message string `json:"message,omitempty"    example:"File storage accepts, reads and removes files"`
+	// Required determines whether the step must pass before the first account is created.
+	Required bool `json:"required"             example:"true"`
+}
+
+// StatusResponse represents the progress of the onboarding wizard.
+type StatusResponse struct {
+	// Steps lists the steps of the wizard in order; empty once the server is initialized.
+	Steps []StepResponse `json:"steps"`
+	// Next names the first step that neither passed nor was skipped.
+	Next string `json:"next,omitempty" example:"smtp"`
+	// Initialized determines whether the server already has accounts, closing the wizard.
+	Initialized bool `json:"initialized"    example:"false"`
+}
+
+// RunRequest represents a request to run an onboarding step.
+type RunRequest struct {
+	// Step names the step to run (required).
+	Step string `json:"step"               binding:"required" example:"smtp"`
+	// Email specifies the recipient of the test message of the smtp step.
+	Email string `json:"email,omitempty"                       example:"ops@example.com"`
+	// Login specifies the login of the account created by the admin step.
+	Login string `json:"login,omitempty"                       example:"admin@example.com"`
+	// Password specifies the password of the account created by the admin step.
+	Password string `json:"password,omitempty"                    example:"Correct-Horse-42"`
+}
+
+// AccountResponse represents the first account created by the onboarding wizard.
+type AccountResponse struct {
+	// RecoveryCodes lists the single-use recovery codes of the account; they are only shown this once.
+	RecoveryCodes []string `json:"recovery_codes" example:"abcd-efgh-ijkl-mnop"`
+	// ID contains the identifier of the account.
+	ID uuid.UUID `json:"id"             example:"123e4567-e89b-12d3-a456-426614174000"`
+}
+
+// RunResponse represents the outcome of an onboarding step.
+type RunResponse struct {
+	// Account describes the account created by the admin step; omitted for other steps.
+	Account *AccountResponse `json:"account,omitempty"`
+	// Step contains the step with the result of the run.
+	Step StepResponse `json:"step"`
+}
+
+// NewStepResponseFromApp converts an application onboarding step to a response DTO.
+func NewStepResponseFromApp(s onboarding.Step) StepResponse {
+	return StepResponse{
+		CheckedAt:   s.CheckedAt,
+		Name:        s.Name,
+		Title:       s.Title,
+		Description: s.Description,
+		Status:      s.Status,
+		Message:     s.Message,
+		Required:    s.Required,
+	}
+}
+
+// NewStatusResponseFromApp converts the application onboarding status to a response DTO.
+func NewStatusResponseFromApp(s *onboarding.Status) StatusResponse {
+	resp := StatusResponse{
+		Steps:       make([]StepResponse, 0, len(s.Steps)),
+		Next:        s.Next,
+		Initialized: s.Initialized,
+	}
+	for _, step := range s.Steps {
+		resp.Steps = append(resp.Steps, NewStepResponseFromApp(step))
+	}
+	return resp
+}
+
+// NewRunResponseFromApp converts the application outcome of an onboarding step to a response DTO.
+func NewRunResponseFromApp(r *onboarding.RunResult) RunResponse {
+	resp := RunResponse{Step: NewStepResponseFromApp(r.Step)}
+	if r.Account != nil {
+		resp.Account = &AccountResponse{ID: r.Account.UserID, RecoveryCodes: r.Account.RecoveryCodes}
+	}
+	return resp
+}
@@ -0,0 +1,105 @@
+package onboarding
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/onboarding"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/response"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/util"
+	"github.com/gin-gonic/gin"
+)
+
+// Service defines the onboarding wizard application service interface.
+type Service interface {
+	// Status reports whether the server awaits onboarding and the steps of the wizard.
+	Status(ctx context.Context) (*onboarding.Status, error)
+	// Run runs a step of the wizard.
+	Run(ctx context.Context, params onboarding.RunParams) (*onboarding.RunResult, error)
+}
+
+// Handler handles HTTP requests for onboarding wizard endpoints.
+type Handler struct {
+	// s is the onboarding service running the wizard.
+	s Service
+}
+
+// NewHandler creates a new onboarding handler with the provided service.
+func NewHandler(s Service) *Handler {
+	return &Handler{s: s}
+}
+
+// Status returns the progress of the onboarding wizard.
+// @Summary      Get onboarding status
+// @Description  Reports whether the server still has an empty database and, while it does, the steps of the
+// @Description  first-run wizard in order with the results of their last runs. Once an account exists the
+// @Description  server is initialized and no steps are listed.
+// .
+// @Tags         System
+// @Produce      json
+// @Success      200 {object} StatusResponse "Onboarding status retrieved successfully"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /onboarding [get]
+// .
+func (h *Handler) Status(c *gin.Context) {
+	status, err := h.s.Status(c)
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, NewStatusResponseFromApp(status))
+}
+
+// Run runs a step of the onboarding wizard.
+// @Summary      Run onboarding step
+// @Description  Runs a step of the first-run wizard. The master_key, storage and smtp steps report failed checks
+// @Description  in the step status and may be run again; smtp sends a test message to email. The admin step
+// @Description  creates the first account from login and password once the required steps passed, returns
+// @Description  its recovery codes and closes the wizard.
+// .
+// @Tags         System
+// @Accept       json
+// @Produce      json
+// @Param        request body RunRequest true "Step to run"
+// @Success      200 {object} RunResponse "Step ran"
+// @Success      201 {object} RunResponse "First account created"
+// @Failure      400 {object} response.Error "Bad request - invalid input data"
+// @Failure      404 {object} response.Error "Not found - the wizard has no such step"
+// @Failure      409 {object} response.Error "Conflict - server already initialized or required steps pending"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /onboarding [post]
+// .
+func (h *Handler) Run(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	// req holds the deserialized JSON step request.
+	var req RunRequest
+	if err := extractor.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	result, err := h.s.Run(c, onboarding.RunParams{
+		Step:     req.Step,
+		Email:    req.Email,
+		Login:    req.Login,
+		Password: req.Password,
+	})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	status := http.StatusOK
+	if result.Account != nil {
+		status = http.StatusCreated
+	}
+	c.JSON(status, NewRunResponseFromApp(result))
+}
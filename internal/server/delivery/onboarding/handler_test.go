@@ -0,0 +1,226 @@
+package onboarding
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	authApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/auth"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/onboarding"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockOnboardingService implements Service for testing.
+type mockOnboardingService struct {
+	statusFunc func(ctx context.Context) (*onboarding.Status, error)
+	runFunc    func(ctx context.Context, params onboarding.RunParams) (*onboarding.RunResult, error)
+}
+
+func (m *mockOnboardingService) Status(ctx context.Context) (*onboarding.Status, error) {
+	if m.statusFunc != nil {
+		return m.statusFunc(ctx)
+	}
+	return &onboarding.Status{Initialized: true}, nil
+}
+
+func (m *mockOnboardingService) Run(
+	ctx context.Context,
+	params onboarding.RunParams,
+) (*onboarding.RunResult, error) {
+	if m.runFunc != nil {
+		return m.runFunc(ctx, params)
+	}
+	return &onboarding.RunResult{Step: onboarding.Step{Name: params.Step, Status: "passed"}}, nil
+}
+
+func TestHandler_Status(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		mockService    *mockOnboardingService
+		want           *StatusResponse
+		name           string
+		expectedStatus int
+	}{
+		{
+			name: "uninitialized server",
+			mockService: &mockOnboardingService{
+				statusFunc: func(context.Context) (*onboarding.Status, error) {
+					return &onboarding.Status{
+						Next: "storage",
+						Steps: []onboarding.Step{
+							{Name: "master_key", Status: "passed", Required: true},
+							{Name: "storage", Status: "pending", Required: true},
+						},
+					}, nil
+				},
+			},
+			want: &StatusResponse{
+				Next: "storage",
+				Steps: []StepResponse{
+					{Name: "master_key", Status: "passed", Required: true},
+					{Name: "storage", Status: "pending", Required: true},
+				},
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "initialized server",
+			mockService:    &mockOnboardingService{},
+			want:           &StatusResponse{Steps: []StepResponse{}, Initialized: true},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "service error",
+			mockService: &mockOnboardingService{
+				statusFunc: func(context.Context) (*onboarding.Status, error) {
+					return nil, errors.Join(onboarding.ErrOnboardingTechError, errors.New("database unavailable"))
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/api/onboarding", nil)
+
+			NewHandler(tt.mockService).Status(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.want == nil {
+				return
+			}
+			var got StatusResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+			assert.Equal(t, *tt.want, got)
+		})
+	}
+}
+
+func TestHandler_Run(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+
+	tests := []struct {
+		mockService    *mockOnboardingService
+		want           *RunResponse
+		name           string
+		body           string
+		expectedStatus int
+	}{
+		{
+			name: "check step",
+			body: `{"step":"smtp","email":"ops@example.com"}`,
+			mockService: &mockOnboardingService{
+				runFunc: func(_ context.Context, params onboarding.RunParams) (*onboarding.RunResult, error) {
+					assert.Equal(t, onboarding.RunParams{Step: "smtp", Email: "ops@example.com"}, params)
+					return &onboarding.RunResult{Step: onboarding.Step{Name: "smtp", Status: "failed"}}, nil
+				},
+			},
+			want:           &RunResponse{Step: StepResponse{Name: "smtp", Status: "failed"}},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "first account created",
+			body: `{"step":"admin","login":"admin","password":"Correct-Horse-42"}`,
+			mockService: &mockOnboardingService{
+				runFunc: func(_ context.Context, params onboarding.RunParams) (*onboarding.RunResult, error) {
+					assert.Equal(t, "admin", params.Login)
+					assert.Equal(t, "Correct-Horse-42", params.Password)
+					return &onboarding.RunResult{
+						Step:    onboarding.Step{Name: "admin", Status: "passed"},
+						Account: &onboarding.Account{UserID: userID, RecoveryCodes: []string{"abcd-efgh"}},
+					}, nil
+				},
+			},
+			want: &RunResponse{
+				Step:    StepResponse{Name: "admin", Status: "passed"},
+				Account: &AccountResponse{ID: userID, RecoveryCodes: []string{"abcd-efgh"}},
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "missing step",
+			body:           `{"email":"ops@example.com"}`,
+			mockService:    &mockOnboardingService{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "unknown step",
+			body: `{"step":"dns"}`,
+			mockService: &mockOnboardingService{
+				runFunc: func(context.Context, onboarding.RunParams) (*onboarding.RunResult, error) {
+					return nil, fmt.Errorf("step %q: %w", "dns", onboarding.ErrStepNotFound)
+				},
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name: "already initialized",
+			body: `{"step":"storage"}`,
+			mockService: &mockOnboardingService{
+				runFunc: func(context.Context, onboarding.RunParams) (*onboarding.RunResult, error) {
+					return nil, onboarding.ErrAlreadyInitialized
+				},
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name: "checks pending",
+			body: `{"step":"admin","login":"admin","password":"Correct-Horse-42"}`,
+			mockService: &mockOnboardingService{
+				runFunc: func(context.Context, onboarding.RunParams) (*onboarding.RunResult, error) {
+					return nil, fmt.Errorf("next step is %q: %w", "storage", onboarding.ErrStepsPending)
+				},
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name: "registration error",
+			body: `{"step":"admin","login":"admin","password":"short"}`,
+			mockService: &mockOnboardingService{
+				runFunc: func(context.Context, onboarding.RunParams) (*onboarding.RunResult, error) {
+					return nil, fmt.Errorf("failed to create first account: %w", authApp.ErrAuthAppError)
+				},
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/api/onboarding", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			NewHandler(tt.mockService).Run(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.want == nil {
+				return
+			}
+			var got RunResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+			assert.Equal(t, *tt.want, got)
+		})
+	}
+}
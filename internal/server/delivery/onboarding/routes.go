@@ -0,0 +1,10 @@
+package onboarding
+
+import "github.com/gin-gonic/gin"
+
+// RegisterRoutes registers onboarding wizard routes with the provided router group.
+// Creates /onboarding with the specified handler.
+func RegisterRoutes(r *gin.RouterGroup, h *Handler) {
+	r.GET("/onboarding", h.Status)
+	r.POST("/onboarding", h.Run)
+}
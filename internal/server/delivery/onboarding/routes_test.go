@@ -0,0 +1,27 @@
+package onboarding
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterRoutes(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	RegisterRoutes(router.Group("/api"), NewHandler(&mockOnboardingService{}))
+
+	routes := router.Routes()
+	assert.Len(t, routes, 2)
+	// methods maps the registered paths to their methods.
+	methods := make(map[string]string)
+	for _, r := range routes {
+		methods[r.Method] = r.Path
+	}
+	assert.Equal(t, "/api/onboarding", methods[http.MethodGet])
+	assert.Equal(t, "/api/onboarding", methods[http.MethodPost])
+}
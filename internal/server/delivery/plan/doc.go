@@ -0,0 +1,5 @@
+// Package plan provides HTTP handlers for plan endpoints in the AegisVaultKeeper server.
+//
+// This package reports the plan limiting the authenticated user and their usage, and maps the
+// upgrade-required errors of plan enforcement to Payment Required responses for the item endpoints.
+package plan
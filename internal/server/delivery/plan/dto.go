@@ -0,0 +1,46 @@
+package plan
+
+import "github.com/gdyunin/aegis-vault-keeper/internal/server/application/plan"
+
+// LimitsResponse represents the limits of a plan. Zero numeric limits mean no limit.
+type LimitsResponse struct {
+	// MaxItems limits the number of stored vault items.
+	MaxItems int64 `json:"max_items" example:"50"`
+	// MaxFileSize limits the size of a single file in bytes.
+	MaxFileSize int64 `json:"max_file_size" example:"10485760"`
+	// MaxOrgSize limits the number of members of a group credentials are shared with.
+	MaxOrgSize int `json:"max_org_size" example:"0"`
+	// Sharing allows sharing credentials with groups.
+	Sharing bool `json:"sharing" example:"false"`
+}
+
+// PlanResponse represents the plan of the authenticated user and their usage.
+type PlanResponse struct {
+	// Name identifies the plan; omitted when plans are not enforced.
+	Name string `json:"name,omitempty" example:"free"`
+	// Limits contains the limits of the plan.
+	Limits LimitsResponse `json:"limits"`
+	// Items contains the number of vault items the user stores.
+	Items int64 `json:"items" example:"12"`
+	// Enforced reports whether the limits apply to the user.
+	Enforced bool `json:"enforced" example:"true"`
+	// OverQuota reports whether the user stores more items than the plan allows; adding items is refused
+	// until some are deleted or the plan is upgraded.
+	OverQuota bool `json:"over_quota" example:"false"`
+}
+
+// NewPlanResponseFromApp converts an application layer plan to delivery DTO.
+func NewPlanResponseFromApp(p *plan.Plan) PlanResponse {
+	return PlanResponse{
+		Name: p.Name,
+		Limits: LimitsResponse{
+			MaxItems:    p.Limits.MaxItems,
+			MaxFileSize: p.Limits.MaxFileSize,
+			MaxOrgSize:  p.Limits.MaxOrgSize,
+			Sharing:     p.Limits.Sharing,
+		},
+		Items:     p.Items,
+		Enforced:  p.Enforced,
+		OverQuota: p.OverQuota,
+	}
+}
@@ -0,0 +1,69 @@
+package plan
+
+import (
+	"net/http"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/plan"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/errutil"
+	"github.com/gin-gonic/gin"
+)
+
+// PlanErrRegistry defines error handling policies for plan enforcement. Item registries merge it, so the
+// item endpoints answer plan violations with 402 Payment Required.
+var PlanErrRegistry = errutil.Registry{
+	{
+		ErrorIn: plan.ErrPlanItemLimitReached,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusPaymentRequired,
+			PublicMsg:  "Item limit of your plan reached; upgrade your plan to store more items",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+	{
+		ErrorIn: plan.ErrPlanFileTooLarge,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusPaymentRequired,
+			PublicMsg:  "File is larger than your plan allows; upgrade your plan to store larger files",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+	{
+		ErrorIn: plan.ErrPlanSharingUnavailable,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusPaymentRequired,
+			PublicMsg:  "Your plan does not include sharing; upgrade your plan to share credentials",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+	{
+		ErrorIn: plan.ErrPlanOrgSizeExceeded,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusPaymentRequired,
+			PublicMsg:  "The group has more members than your plan allows; upgrade your plan to share with it",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+	{
+		ErrorIn: plan.ErrPlanTechError,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusInternalServerError,
+			PublicMsg:  http.StatusText(http.StatusInternalServerError),
+			LogIt:      true,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassTech,
+		},
+	},
+}
+
+// handleError processes errors using the plan error registry.
+func handleError(err error, c *gin.Context) (int, []string) {
+	return errutil.HandleWithRegistry(PlanErrRegistry, err, c)
+}
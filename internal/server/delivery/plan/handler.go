@@ -0,0 +1,64 @@
+package plan
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/plan"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/response"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/util"
+	"github.com/gin-gonic/gin"
+)
+
+// Service defines the plan application service interface.
+type Service interface {
+	// Current retrieves the plan limiting the user and their usage.
+	Current(ctx context.Context, params plan.CurrentParams) (*plan.Plan, error)
+}
+
+// Handler handles HTTP requests for plan endpoints.
+type Handler struct {
+	// s is the plan service used to resolve plans.
+	s Service
+}
+
+// NewHandler creates a new plan handler with the provided service.
+func NewHandler(s Service) *Handler {
+	return &Handler{s: s}
+}
+
+// Current returns the plan of the authenticated user.
+// @Summary      Get plan
+// @Description  Reports the plan limiting the user, its limits and the number of vault items the user stores.
+// @Description  Zero numeric limits mean no limit. Operations exceeding the limits are answered with
+// @Description  402 Payment Required. Without enforced plans the response has no name and enforced is false
+// .
+// @Tags         Plan
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} PlanResponse "Plan retrieved successfully"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /plan [get]
+// .
+func (h *Handler) Current(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	userID, err := extractor.UserID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+
+	p, err := h.s.Current(c, plan.CurrentParams{UserID: userID})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, NewPlanResponseFromApp(p))
+}
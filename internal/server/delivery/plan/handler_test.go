@@ -0,0 +1,179 @@
+package plan
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/plan"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/consts"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockPlanService implements Service for testing.
+type mockPlanService struct {
+	currentFunc func(ctx context.Context, params plan.CurrentParams) (*plan.Plan, error)
+}
+
+func (m *mockPlanService) Current(ctx context.Context, params plan.CurrentParams) (*plan.Plan, error) {
+	if m.currentFunc != nil {
+		return m.currentFunc(ctx, params)
+	}
+	return &plan.Plan{}, nil
+}
+
+func TestHandler_Current(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+
+	tests := []struct {
+		setupContext   func(c *gin.Context)
+		mockService    *mockPlanService
+		want           *PlanResponse
+		name           string
+		expectedStatus int
+	}{
+		{
+			name: "enforced plan",
+			setupContext: func(c *gin.Context) {
+				c.Set(consts.CtxKeyUserID, userID)
+			},
+			mockService: &mockPlanService{
+				currentFunc: func(_ context.Context, params plan.CurrentParams) (*plan.Plan, error) {
+					assert.Equal(t, userID, params.UserID)
+					return &plan.Plan{
+						Name:     "free",
+						Limits:   plan.Limits{MaxItems: 50, MaxFileSize: 10 << 20},
+						Items:    12,
+						Enforced: true,
+					}, nil
+				},
+			},
+			want: &PlanResponse{
+				Name:     "free",
+				Limits:   LimitsResponse{MaxItems: 50, MaxFileSize: 10 << 20},
+				Items:    12,
+				Enforced: true,
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "plans not enforced",
+			setupContext: func(c *gin.Context) {
+				c.Set(consts.CtxKeyUserID, userID)
+			},
+			mockService: &mockPlanService{
+				currentFunc: func(context.Context, plan.CurrentParams) (*plan.Plan, error) {
+					return &plan.Plan{Limits: plan.Limits{Sharing: true}, Items: 3}, nil
+				},
+			},
+			want:           &PlanResponse{Limits: LimitsResponse{Sharing: true}, Items: 3},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "over quota",
+			setupContext: func(c *gin.Context) {
+				c.Set(consts.CtxKeyUserID, userID)
+			},
+			mockService: &mockPlanService{
+				currentFunc: func(context.Context, plan.CurrentParams) (*plan.Plan, error) {
+					return &plan.Plan{
+						Name:      "free",
+						Limits:    plan.Limits{MaxItems: 50},
+						Items:     60,
+						Enforced:  true,
+						OverQuota: true,
+					}, nil
+				},
+			},
+			want: &PlanResponse{
+				Name:      "free",
+				Limits:    LimitsResponse{MaxItems: 50},
+				Items:     60,
+				Enforced:  true,
+				OverQuota: true,
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "missing user context",
+			setupContext: func(c *gin.Context) {
+				// don't set user_id
+			},
+			mockService:    &mockPlanService{},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name: "service error",
+			setupContext: func(c *gin.Context) {
+				c.Set(consts.CtxKeyUserID, userID)
+			},
+			mockService: &mockPlanService{
+				currentFunc: func(context.Context, plan.CurrentParams) (*plan.Plan, error) {
+					return nil, errors.Join(plan.ErrPlanTechError, errors.New("billing unavailable"))
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/plan", nil)
+
+			tt.setupContext(c)
+
+			NewHandler(tt.mockService).Current(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.want == nil {
+				return
+			}
+			var got PlanResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+			assert.Equal(t, *tt.want, got)
+		})
+	}
+}
+
+func TestPlanErrRegistry(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		err      error
+		name     string
+		wantCode int
+	}{
+		{name: "item limit", err: plan.ErrPlanItemLimitReached, wantCode: http.StatusPaymentRequired},
+		{name: "file size", err: plan.ErrPlanFileTooLarge, wantCode: http.StatusPaymentRequired},
+		{name: "sharing", err: plan.ErrPlanSharingUnavailable, wantCode: http.StatusPaymentRequired},
+		{name: "organization size", err: plan.ErrPlanOrgSizeExceeded, wantCode: http.StatusPaymentRequired},
+		{name: "technical error", err: plan.ErrPlanTechError, wantCode: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+
+			code, msgs := handleError(tt.err, c)
+
+			assert.Equal(t, tt.wantCode, code)
+			assert.NotEmpty(t, msgs)
+		})
+	}
+}
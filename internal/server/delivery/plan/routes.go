@@ -0,0 +1,8 @@
+package plan
+
+import "github.com/gin-gonic/gin"
+
+// RegisterRoutes registers plan routes with the provided router group.
+func RegisterRoutes(r *gin.RouterGroup, h *Handler) {
+	r.GET("", h.Current)
+}
@@ -0,0 +1,5 @@
+// Package purge provides HTTP handlers for the item purge endpoint in the AegisVaultKeeper server.
+//
+// This package lets authenticated users permanently delete every vault item of one kind in two steps:
+// a confirmation token reporting the affected items is issued first, and the purge runs when it is sent back.
+package purge
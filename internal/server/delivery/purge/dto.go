@@ -0,0 +1,63 @@
+package purge
+
+import (
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/purge"
+)
+
+// PurgeQuery represents the query parameters of an item purge request.
+type PurgeQuery struct {
+	// Type contains the kind of the items to purge: bankcard, credential or note.
+	Type string `form:"type" binding:"required" example:"credential"`
+}
+
+// PurgeRequest represents the optional body of an item purge request.
+type PurgeRequest struct {
+	// Token contains the confirmation token of a requested purge; empty requests a new purge.
+	Token string `json:"token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+}
+
+// PurgeConfirmation represents a requested purge awaiting confirmation.
+type PurgeConfirmation struct {
+	// ExpiresAt contains the time after which the token no longer confirms the purge.
+	ExpiresAt time.Time `json:"expires_at" example:"2023-12-01T10:05:00Z"`
+	// Token contains the token confirming the purge of exactly the counted items.
+	Token string `json:"token"      example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	// Type contains the kind of the items to purge.
+	Type string `json:"type"       example:"credential"`
+	// Count contains the number of items the purge deletes.
+	Count int `json:"count"      example:"42"`
+}
+
+// NewPurgeConfirmationFromApp converts an application layer purge confirmation to delivery DTO.
+func NewPurgeConfirmationFromApp(c *purge.Confirmation) *PurgeConfirmation {
+	if c == nil {
+		return nil
+	}
+	return &PurgeConfirmation{
+		ExpiresAt: c.ExpiresAt,
+		Token:     c.Token,
+		Type:      c.Kind,
+		Count:     c.Count,
+	}
+}
+
+// PurgeResult represents an executed purge.
+type PurgeResult struct {
+	// Type contains the kind of the purged items.
+	Type string `json:"type"    example:"credential"`
+	// Deleted contains the number of deleted items.
+	Deleted int `json:"deleted" example:"42"`
+}
+
+// NewPurgeResultFromApp converts an application layer purge result to delivery DTO.
+func NewPurgeResultFromApp(r *purge.Result) *PurgeResult {
+	if r == nil {
+		return nil
+	}
+	return &PurgeResult{
+		Type:    r.Kind,
+		Deleted: r.Deleted,
+	}
+}
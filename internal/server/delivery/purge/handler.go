@@ -0,0 +1,103 @@
+package purge
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/purge"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/response"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/util"
+	"github.com/gin-gonic/gin"
+)
+
+// Service defines the item purge application service interface.
+type Service interface {
+	// Prepare requests the purge of every item of one kind and issues its confirmation token.
+	Prepare(context.Context, purge.PrepareParams) (*purge.Confirmation, error)
+	// Execute permanently deletes the items confirmed by the token.
+	Execute(context.Context, purge.ExecuteParams) (*purge.Result, error)
+}
+
+// Handler handles HTTP requests for item purge endpoints.
+type Handler struct {
+	// s is the item purge service used to process operations.
+	s Service
+}
+
+// NewHandler creates a new item purge handler with the provided service.
+func NewHandler(s Service) *Handler {
+	return &Handler{s: s}
+}
+
+// Purge requests or executes the permanent deletion of every item of one kind of the authenticated user.
+// @Summary      Purge items
+// @Description  Permanently deletes every item of one type together with its history, tags, paths and shares.
+// @Description  Without a token the purge is only requested: the response reports the number of affected
+// @Description  items and a confirmation token valid for PURGE_TOKEN_TTL. Sending the token back deletes the
+// @Description  items in one transaction, unless an item of the type was added, changed or removed meanwhile,
+// @Description  and answers with the type and the number of deleted items.
+// .
+// @Tags         Purge
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        type query string true "Item type" Enums(bankcard, credential, note)
+// @Param        request body PurgeRequest false "Confirmation token of the requested purge"
+// @Success      200 {object} PurgeConfirmation "Purge requested; confirm it with the token"
+// @Failure      400 {object} response.Error "Bad request - invalid type or confirmation token"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
+// @Failure      403 {object} response.Error "Forbidden - changing these items is denied"
+// @Failure      404 {object} response.Error "Not found - no items of the type"
+// @Failure      409 {object} response.Error "Conflict - items changed since the purge was requested"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /items/purge [post]
+// .
+func (h *Handler) Purge(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	userID, err := extractor.UserID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+
+	// query holds the deserialized query parameters of the request.
+	var query PurgeQuery
+	if err := extractor.BindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	// req holds the deserialized JSON request payload with the confirmation token.
+	var req PurgeRequest
+	if c.Request.ContentLength != 0 {
+		if err := extractor.BindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+			return
+		}
+	}
+
+	if req.Token == "" {
+		confirmation, err := h.s.Prepare(c, purge.PrepareParams{Kind: query.Type, UserID: userID})
+		if err != nil {
+			code, msgs := handleError(err, c)
+			c.JSON(code, response.Error{
+				Messages: msgs,
+			})
+			return
+		}
+		c.JSON(http.StatusOK, NewPurgeConfirmationFromApp(confirmation))
+		return
+	}
+
+	result, err := h.s.Execute(c, purge.ExecuteParams{Token: req.Token, Kind: query.Type, UserID: userID})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, NewPurgeResultFromApp(result))
+}
@@ -0,0 +1,204 @@
+package purge
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/purge"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/consts"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockPurgeService implements Service for testing.
+type mockPurgeService struct {
+	prepareFunc func(ctx context.Context, params purge.PrepareParams) (*purge.Confirmation, error)
+	executeFunc func(ctx context.Context, params purge.ExecuteParams) (*purge.Result, error)
+}
+
+func (m *mockPurgeService) Prepare(ctx context.Context, params purge.PrepareParams) (*purge.Confirmation, error) {
+	if m.prepareFunc != nil {
+		return m.prepareFunc(ctx, params)
+	}
+	return nil, nil
+}
+
+func (m *mockPurgeService) Execute(ctx context.Context, params purge.ExecuteParams) (*purge.Result, error) {
+	if m.executeFunc != nil {
+		return m.executeFunc(ctx, params)
+	}
+	return nil, nil
+}
+
+func TestHandler_Purge(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	expiresAt := time.Date(2023, 12, 1, 10, 5, 0, 0, time.UTC)
+
+	tests := []struct {
+		mockService    *mockPurgeService
+		name           string
+		query          string
+		body           string
+		wantBody       string
+		setUser        bool
+		expectedStatus int
+	}{
+		{
+			name:    "purge requested",
+			setUser: true,
+			query:   "?type=credential",
+			mockService: &mockPurgeService{
+				prepareFunc: func(_ context.Context, params purge.PrepareParams) (*purge.Confirmation, error) {
+					assert.Equal(t, purge.PrepareParams{Kind: "credential", UserID: userID}, params)
+					return &purge.Confirmation{Token: "tok", Kind: "credential", Count: 3, ExpiresAt: expiresAt}, nil
+				},
+			},
+			wantBody:       `{"expires_at":"2023-12-01T10:05:00Z","token":"tok","type":"credential","count":3}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:    "purge requested with empty token",
+			setUser: true,
+			query:   "?type=note",
+			body:    `{"token":""}`,
+			mockService: &mockPurgeService{
+				prepareFunc: func(context.Context, purge.PrepareParams) (*purge.Confirmation, error) {
+					return &purge.Confirmation{Token: "tok", Kind: "note", Count: 1, ExpiresAt: expiresAt}, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:    "purge executed",
+			setUser: true,
+			query:   "?type=credential",
+			body:    `{"token":"tok"}`,
+			mockService: &mockPurgeService{
+				executeFunc: func(_ context.Context, params purge.ExecuteParams) (*purge.Result, error) {
+					assert.Equal(t, purge.ExecuteParams{Token: "tok", Kind: "credential", UserID: userID}, params)
+					return &purge.Result{Kind: "credential", Deleted: 3}, nil
+				},
+			},
+			wantBody:       `{"type":"credential","deleted":3}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "missing user context",
+			query:          "?type=credential",
+			mockService:    &mockPurgeService{},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:           "missing type",
+			setUser:        true,
+			mockService:    &mockPurgeService{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "malformed body",
+			setUser:        true,
+			query:          "?type=credential",
+			body:           `{"token":`,
+			mockService:    &mockPurgeService{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:    "unknown type",
+			setUser: true,
+			query:   "?type=file",
+			mockService: &mockPurgeService{
+				prepareFunc: func(context.Context, purge.PrepareParams) (*purge.Confirmation, error) {
+					return nil, purge.ErrUnknownKind
+				},
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:    "no items",
+			setUser: true,
+			query:   "?type=credential",
+			mockService: &mockPurgeService{
+				prepareFunc: func(context.Context, purge.PrepareParams) (*purge.Confirmation, error) {
+					return nil, purge.ErrNoItems
+				},
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:    "access denied",
+			setUser: true,
+			query:   "?type=credential",
+			mockService: &mockPurgeService{
+				prepareFunc: func(context.Context, purge.PrepareParams) (*purge.Confirmation, error) {
+					return nil, purge.ErrPurgeAccessDenied
+				},
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:    "invalid token",
+			setUser: true,
+			query:   "?type=credential",
+			body:    `{"token":"expired"}`,
+			mockService: &mockPurgeService{
+				executeFunc: func(context.Context, purge.ExecuteParams) (*purge.Result, error) {
+					return nil, purge.ErrInvalidToken
+				},
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:    "items changed",
+			setUser: true,
+			query:   "?type=credential",
+			body:    `{"token":"tok"}`,
+			mockService: &mockPurgeService{
+				executeFunc: func(context.Context, purge.ExecuteParams) (*purge.Result, error) {
+					return nil, purge.ErrItemsChanged
+				},
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:    "service error",
+			setUser: true,
+			query:   "?type=credential",
+			body:    `{"token":"tok"}`,
+			mockService: &mockPurgeService{
+				executeFunc: func(context.Context, purge.ExecuteParams) (*purge.Result, error) {
+					return nil, purge.ErrPurgeTechError
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/items/purge"+tt.query, strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			if tt.setUser {
+				c.Set(consts.CtxKeyUserID, userID)
+			}
+
+			NewHandler(tt.mockService).Purge(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.wantBody != "" {
+				assert.JSONEq(t, tt.wantBody, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,9 @@
+package purge
+
+import "github.com/gin-gonic/gin"
+
+// RegisterRoutes registers item purge routes with the provided router group.
+// Creates /purge with the specified handler.
+func RegisterRoutes(r *gin.RouterGroup, h *Handler) {
+	r.POST("/purge", h.Purge)
+}
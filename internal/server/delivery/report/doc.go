@@ -0,0 +1,6 @@
+// Package report provides HTTP handlers for CSV report endpoints in the AegisVaultKeeper server.
+//
+// This package streams the bank cards or credentials of authenticated users as CSV files with the columns they
+// select, for migrating to other tools or auditing their data. Secret columns require an explicit flag and the
+// re-entered password of the user.
+package report
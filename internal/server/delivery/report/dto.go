@@ -0,0 +1,23 @@
+package report
+
+import "strings"
+
+// ReportRequest represents the request generating a CSV report.
+type ReportRequest struct {
+	// File contains the name of the report file (required): the report type with the .csv extension.
+	File string `uri:"file" binding:"required" example:"credentials.csv"`
+	// IncludeSecrets allows secret columns such as passwords, card numbers and CVVs.
+	IncludeSecrets bool `form:"include_secrets" example:"false"`
+}
+
+// formulaPrefixes lists the leading characters spreadsheet applications evaluate cells starting with.
+const formulaPrefixes = "=+-@\t\r"
+
+// neutralizeCell prefixes a cell value starting like a spreadsheet formula with an apostrophe, so spreadsheet
+// applications opening the report show it as text instead of evaluating it.
+func neutralizeCell(value string) string {
+	if value != "" && strings.ContainsRune(formulaPrefixes, rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
@@ -0,0 +1,78 @@
+package report
+
+import (
+	"net/http"
+
+	authApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/auth"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/reporting"
+	bankcarddel "github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/bankcard"
+	credentialdel "github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/credential"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/errutil"
+	"github.com/gin-gonic/gin"
+)
+
+// ReportErrRegistry defines error handling policies for CSV reports.
+var ReportErrRegistry = errutil.Registry{
+	{
+		ErrorIn: reporting.ErrReportTypeUnknown,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusNotFound,
+			PublicMsg:  "Unknown report; supported reports: bankcards.csv, credentials.csv",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+	{
+		ErrorIn: reporting.ErrReportColumnUnknown,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusBadRequest,
+			PublicMsg:  "Unknown report column",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+	{
+		ErrorIn: reporting.ErrReportSecretsNotIncluded,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusBadRequest,
+			PublicMsg:  "Secret columns require include_secrets=true",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+	{
+		ErrorIn: reporting.ErrReportPasswordRequired,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusBadRequest,
+			PublicMsg:  "The X-Confirm-Password header with the account password is required to include secrets",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+	{
+		ErrorIn: authApp.ErrAuthPasswordMismatch,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusForbidden,
+			PublicMsg:  "The provided password is incorrect",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassAuth,
+		},
+	},
+}
+
+// ReportsErrRegistry aggregates the report and reported item error registries.
+var ReportsErrRegistry = errutil.Merge(
+	ReportErrRegistry,
+	bankcarddel.BankCardErrRegistry,
+	credentialdel.CredentialErrRegistry,
+)
+
+// handleError processes errors using the consolidated report error registry.
+func handleError(err error, c *gin.Context) (int, []string) {
+	return errutil.HandleWithRegistry(ReportsErrRegistry, err, c)
+}
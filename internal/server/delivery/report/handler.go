@@ -0,0 +1,160 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/reporting"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/response"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/util"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// headerConfirmPassword names the header carrying the re-entered password of reports including secrets.
+	headerConfirmPassword = "X-Confirm-Password"
+	// reportExtension is the file extension of report names.
+	reportExtension = ".csv"
+	// mimeCSV is the media type of reports.
+	mimeCSV = "text/csv; charset=utf-8"
+	// flushSize is the number of buffered report bytes written to the client at once.
+	flushSize = 32 << 10
+)
+
+// Service defines the reporting application service interface.
+type Service interface {
+	// Generate passes the header row and then the row of every reported item to the emit function.
+	Generate(ctx context.Context, params reporting.GenerateParams, emit func(row []string) error) error
+}
+
+// Handler handles HTTP requests for CSV report endpoints.
+type Handler struct {
+	// s is the reporting service used to generate reports.
+	s Service
+}
+
+// NewHandler creates a new report handler with the provided service.
+func NewHandler(s Service) *Handler {
+	return &Handler{s: s}
+}
+
+// Report streams a CSV report of the bank cards or credentials of the authenticated user.
+// @Summary      Get CSV report
+// @Description  Streams the bank cards or credentials of the user as a CSV file, one row per item ordered by ID,
+// @Description  with the columns of the fields parameter in its order. Without it every column is included
+// @Description  except secret ones: password, card_number and cvv. Secret columns require include_secrets=true
+// @Description  and the account password in the X-Confirm-Password header. Cells starting like spreadsheet
+// @Description  formulas are prefixed with an apostrophe. Should a failure interrupt the stream, the connection
+// @Description  is closed before the end of the file
+// .
+// @Tags         Reports
+// @Produce      text/csv
+// @Produce      json
+// @Security     BearerAuth
+// @Param        file path string true "Report file" Enums(bankcards.csv, credentials.csv)
+// @Param        fields query string false "Comma-separated columns in output order"
+// @Param        include_secrets query bool false "Allow secret columns"
+// @Param        X-Confirm-Password header string false "Account password, required to include secrets"
+// @Success      200 {file} binary "CSV report"
+// @Failure      400 {object} response.Error "Bad request - unknown column or secret column without secrets"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
+// @Failure      403 {object} response.Error "Forbidden - incorrect password"
+// @Failure      404 {object} response.Error "Unknown report"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /reports/{file} [get]
+// .
+func (h *Handler) Report(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	userID, err := extractor.UserID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+
+	// req holds the deserialized URI and query parameters of the request.
+	var req ReportRequest
+	if err := extractor.BindURI(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+	if err := extractor.BindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+	name, ok := strings.CutSuffix(req.File, reportExtension)
+	reportType := reporting.Type(name)
+	columns := reporting.Columns(reportType)
+	if !ok || columns == nil {
+		code, msgs := handleError(reporting.ErrReportTypeUnknown, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+	fields, err := extractor.Fields(columns)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	// buf holds the encoded rows not yet written to the client.
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	// started reports whether the response status and headers were written.
+	var started bool
+	flush := func() error {
+		w.Flush()
+		if err := w.Error(); err != nil {
+			return fmt.Errorf("failed to encode report: %w", err)
+		}
+		if !started {
+			c.Header("Content-Disposition", "attachment; filename=\""+req.File+"\"")
+			c.Header("Content-Type", mimeCSV)
+			c.Status(http.StatusOK)
+			started = true
+		}
+		if _, err := c.Writer.Write(buf.Bytes()); err != nil {
+			return fmt.Errorf("failed to write report: %w", err)
+		}
+		buf.Reset()
+		c.Writer.Flush()
+		return nil
+	}
+
+	err = h.s.Generate(c, reporting.GenerateParams{
+		Columns:        fields,
+		Password:       c.GetHeader(headerConfirmPassword),
+		Type:           reportType,
+		UserID:         userID,
+		IncludeSecrets: req.IncludeSecrets,
+	}, func(row []string) error {
+		for i, value := range row {
+			row[i] = neutralizeCell(value)
+		}
+		if err := w.Write(row); err != nil {
+			return fmt.Errorf("failed to encode report: %w", err)
+		}
+		if buf.Len() < flushSize {
+			return nil
+		}
+		return flush()
+	})
+	switch {
+	case err != nil && !started:
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+	case err != nil:
+		// The status is sent, so the client can only tell a truncated report by the aborted connection.
+		_, _ = handleError(err, c)
+		panic(http.ErrAbortHandler)
+	default:
+		_ = flush()
+	}
+}
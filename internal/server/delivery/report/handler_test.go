@@ -0,0 +1,303 @@
+package report
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	authApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/auth"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/reporting"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/consts"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+// mockReportService implements Service for testing.
+type mockReportService struct {
+	generateFunc func(ctx context.Context, params reporting.GenerateParams, emit func(row []string) error) error
+}
+
+func (m *mockReportService) Generate(
+	ctx context.Context,
+	params reporting.GenerateParams,
+	emit func(row []string) error,
+) error {
+	if m.generateFunc != nil {
+		return m.generateFunc(ctx, params, emit)
+	}
+	return nil
+}
+
+// emitRows returns a generate function emitting the rows.
+func emitRows(rows ...[]string) func(context.Context, reporting.GenerateParams, func([]string) error) error {
+	return func(_ context.Context, _ reporting.GenerateParams, emit func([]string) error) error {
+		for _, row := range rows {
+			if err := emit(row); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func TestHandler_Report(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+
+	tests := []struct {
+		mockService    *mockReportService
+		name           string
+		file           string
+		query          string
+		password       string
+		wantBody       string
+		setUser        bool
+		expectedStatus int
+	}{
+		{
+			name:    "credential report",
+			file:    "credentials.csv",
+			query:   "?fields=login,description&include_secrets=false",
+			setUser: true,
+			mockService: &mockReportService{
+				generateFunc: func(
+					ctx context.Context,
+					params reporting.GenerateParams,
+					emit func([]string) error,
+				) error {
+					assert.Equal(t, reporting.GenerateParams{
+						Columns: []string{"login", "description"},
+						Type:    reporting.TypeCredentials,
+						UserID:  userID,
+					}, params)
+					return emitRows(
+						[]string{"login", "description"},
+						[]string{"alice", "work, \"main\""},
+						[]string{"bob", "=HYPERLINK(\"http://evil\")"},
+					)(ctx, params, emit)
+				},
+			},
+			wantBody:       "login,description\nalice,\"work, \"\"main\"\"\"\nbob,\"'=HYPERLINK(\"\"http://evil\"\")\"\n",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:     "bank card report with secrets",
+			file:     "bankcards.csv",
+			query:    "?include_secrets=true",
+			password: "correct_password",
+			setUser:  true,
+			mockService: &mockReportService{
+				generateFunc: func(
+					ctx context.Context,
+					params reporting.GenerateParams,
+					emit func([]string) error,
+				) error {
+					assert.Equal(t, reporting.GenerateParams{
+						Password:       "correct_password",
+						Type:           reporting.TypeBankCards,
+						UserID:         userID,
+						IncludeSecrets: true,
+					}, params)
+					return emitRows([]string{"card_number"}, []string{"4111111111111111"})(ctx, params, emit)
+				},
+			},
+			wantBody:       "card_number\n4111111111111111\n",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "unknown report",
+			file:           "notes.csv",
+			setUser:        true,
+			mockService:    &mockReportService{},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "missing extension",
+			file:           "credentials",
+			setUser:        true,
+			mockService:    &mockReportService{},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "unknown column",
+			file:           "credentials.csv",
+			query:          "?fields=pin",
+			setUser:        true,
+			mockService:    &mockReportService{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid flag",
+			file:           "credentials.csv",
+			query:          "?include_secrets=maybe",
+			setUser:        true,
+			mockService:    &mockReportService{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:    "secret column without secrets",
+			file:    "credentials.csv",
+			query:   "?fields=password",
+			setUser: true,
+			mockService: &mockReportService{
+				generateFunc: func(context.Context, reporting.GenerateParams, func([]string) error) error {
+					return reporting.ErrReportSecretsNotIncluded
+				},
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:     "wrong password",
+			file:     "credentials.csv",
+			query:    "?include_secrets=true",
+			password: "wrong_password",
+			setUser:  true,
+			mockService: &mockReportService{
+				generateFunc: func(context.Context, reporting.GenerateParams, func([]string) error) error {
+					return authApp.ErrAuthPasswordMismatch
+				},
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "missing user context",
+			file:           "credentials.csv",
+			mockService:    &mockReportService{},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:    "service error",
+			file:    "credentials.csv",
+			setUser: true,
+			mockService: &mockReportService{
+				generateFunc: func(ctx context.Context, params reporting.GenerateParams, emit func([]string) error) error {
+					if err := emit([]string{"id"}); err != nil {
+						return err
+					}
+					return errors.New("service error")
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/reports/"+tt.file+tt.query, nil)
+			c.Params = gin.Params{{Key: "file", Value: tt.file}}
+			if tt.password != "" {
+				c.Request.Header.Set(headerConfirmPassword, tt.password)
+			}
+			if tt.setUser {
+				c.Set(consts.CtxKeyUserID, userID)
+			}
+
+			NewHandler(tt.mockService).Report(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.wantBody != "" {
+				assert.Equal(t, tt.wantBody, w.Body.String())
+				assert.Equal(t, mimeCSV, w.Header().Get("Content-Type"))
+				assert.Equal(t, "attachment; filename=\""+tt.file+"\"", w.Header().Get("Content-Disposition"))
+			}
+		})
+	}
+}
+
+func TestHandler_ReportInterrupted(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/reports/credentials.csv", nil)
+	c.Params = gin.Params{{Key: "file", Value: "credentials.csv"}}
+	c.Set(consts.CtxKeyUserID, uuid.New())
+
+	handler := NewHandler(&mockReportService{
+		generateFunc: func(_ context.Context, _ reporting.GenerateParams, emit func([]string) error) error {
+			row := []string{strings.Repeat("x", flushSize)}
+			if err := emit(row); err != nil {
+				return err
+			}
+			return errors.New("database unavailable")
+		},
+	})
+
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() { handler.Report(c) })
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, flushSize+1, w.Body.Len())
+}
+
+func TestHandler_ReportInterrupted_Connection(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+	handler := NewHandler(&mockReportService{
+		generateFunc: func(_ context.Context, _ reporting.GenerateParams, emit func([]string) error) error {
+			if err := emit([]string{strings.Repeat("x", flushSize)}); err != nil {
+				return err
+			}
+			return errors.New("database unavailable")
+		},
+	})
+	router := gin.New()
+	router.Use(middleware.Recovery(zaptest.NewLogger(t).Sugar()))
+	router.GET("/reports/:file", func(c *gin.Context) {
+		c.Set(consts.CtxKeyUserID, uuid.New())
+		handler.Report(c)
+	})
+
+	srv := httptest.NewServer(router)
+	defer srv.Close()
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, srv.URL+"/reports/credentials.csv", nil)
+	require.NoError(t, err)
+	resp, err := srv.Client().Do(req)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	_, err = io.ReadAll(resp.Body)
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF, "the client should see the report is truncated")
+}
+
+func TestNeutralizeCell(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{name: "plain text", value: "alice", want: "alice"},
+		{name: "empty", value: "", want: ""},
+		{name: "formula", value: "=1+2", want: "'=1+2"},
+		{name: "plus", value: "+49 30 123", want: "'+49 30 123"},
+		{name: "minus", value: "-2+3", want: "'-2+3"},
+		{name: "at sign", value: "@SUM(A1)", want: "'@SUM(A1)"},
+		{name: "tab", value: "\t=1", want: "'\t=1"},
+		{name: "inner formula", value: "a=1", want: "a=1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, neutralizeCell(tt.value))
+		})
+	}
+}
@@ -0,0 +1,9 @@
+package report
+
+import "github.com/gin-gonic/gin"
+
+// RegisterRoutes registers CSV report routes with the provided router group.
+// Creates /:file, serving the report named by the file, such as credentials.csv.
+func RegisterRoutes(r *gin.RouterGroup, h *Handler) {
+	r.GET("/:file", h.Report)
+}
@@ -2,8 +2,19 @@ package response
 
 import "net/http"
 
+// CodeOutsideAccessWindow identifies item reveals rejected because the vault is outside its access windows.
+const CodeOutsideAccessWindow = "outside_access_window"
+
+// CodeRestrictedLocation identifies reveals of restricted items rejected because of the client location.
+const CodeRestrictedLocation = "restricted_location"
+
+// CodeApprovalRequired identifies reveals of items rejected because no approved access request is in effect.
+const CodeApprovalRequired = "approval_required"
+
 // Error represents an API error response with multiple possible error messages.
 type Error struct {
+	// Code contains a stable machine-readable identifier of the error; omitted for most errors.
+	Code string `json:"code,omitzero"`
 	// Messages contains one or more error descriptions for the client.
 	Messages []string `json:"messages"`
 }
@@ -120,6 +120,14 @@ func TestError_JSONSerialization(t *testing.T) {
 			},
 			expectedJSON: `{"messages":["错误信息","エラーメッセージ","🚫 Error"]}`,
 		},
+		{
+			name: "with code",
+			error: Error{
+				Code:     CodeOutsideAccessWindow,
+				Messages: []string{"Outside access window"},
+			},
+			expectedJSON: `{"code":"outside_access_window","messages":["Outside access window"]}`,
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,5 @@
+// Package rotation provides HTTP handlers for credential rotation endpoints in the AegisVaultKeeper server.
+//
+// This package lets authenticated users configure how the secrets of their credentials are rotated in the
+// external systems they belong to, and rotate them on demand.
+package rotation
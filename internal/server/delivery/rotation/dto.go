@@ -0,0 +1,97 @@
+package rotation
+
+import (
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/rotation"
+	"github.com/google/uuid"
+)
+
+// Settings represents the address of the external system a credential secret belongs to.
+// Only the settings of the configured rotator kind are used.
+type Settings struct {
+	// Host contains the PostgreSQL server host.
+	Host string `json:"host,omitzero"      example:"db.example.com"`
+	// Database contains the PostgreSQL database to connect to.
+	Database string `json:"database,omitzero"  example:"app"`
+	// SSLMode contains the PostgreSQL TLS mode; defaults to require.
+	SSLMode string `json:"sslmode,omitzero"   example:"verify-full"`
+	// UserName contains the AWS IAM user whose access key is rotated.
+	UserName string `json:"user_name,omitzero" example:"deploy-bot"`
+	// Region contains the AWS signing region; defaults to us-east-1.
+	Region string `json:"region,omitzero"    example:"us-east-1"`
+	// Endpoint contains the AWS IAM API URL; defaults to https://iam.amazonaws.com.
+	Endpoint string `json:"endpoint,omitzero"  example:"https://iam.amazonaws.com"`
+	// URL contains the HTTPS webhook URL the new secret is sent to.
+	URL string `json:"url,omitzero"       example:"https://hooks.example.com/rotate"`
+	// Port contains the PostgreSQL server port; defaults to 5432.
+	Port int `json:"port,omitzero"      example:"5432"`
+}
+
+// Policy represents the rotation policy of a credential.
+type Policy struct {
+	// CreatedAt contains the timestamp the policy was created at.
+	CreatedAt time.Time `json:"created_at"                 example:"2023-12-01T10:00:00Z"`
+	// LastRotatedAt contains the timestamp of the last successful rotation; omitted if never rotated.
+	LastRotatedAt time.Time `json:"last_rotated_at,omitzero"   example:"2023-12-02T10:00:00Z"`
+	// NextRotationAt contains the moment of the next scheduled rotation; omitted when rotated on demand only.
+	NextRotationAt time.Time `json:"next_rotation_at,omitzero"  example:"2023-12-03T10:00:00Z"`
+	// LastError contains the failure reason of the last rotation; omitted if it succeeded.
+	LastError string `json:"last_error,omitzero"        example:"unexpected status 403"`
+	// Kind contains the rotator replacing the secret.
+	Kind string `json:"kind"                       example:"postgres"`
+	// Settings contains the address of the external system.
+	Settings Settings `json:"settings"`
+	// IntervalSeconds contains how often the secret is rotated; zero rotates on demand only.
+	IntervalSeconds int64 `json:"interval_seconds"           example:"86400"`
+	// CredentialID contains the identifier of the rotated credential.
+	CredentialID uuid.UUID `json:"credential_id"              example:"123e4567-e89b-12d3-a456-426614174000"`
+}
+
+// NewPolicyFromApp converts an application layer rotation policy to delivery DTO.
+func NewPolicyFromApp(p *rotation.Policy) *Policy {
+	if p == nil {
+		return nil
+	}
+	return &Policy{
+		CredentialID:    p.CredentialID,
+		Kind:            p.Kind,
+		Settings:        Settings(p.Settings),
+		IntervalSeconds: int64(p.Interval / time.Second),
+		LastError:       p.LastError,
+		CreatedAt:       p.CreatedAt,
+		LastRotatedAt:   p.LastRotatedAt,
+		NextRotationAt:  p.NextRotationAt,
+	}
+}
+
+// NewPoliciesFromApp converts application layer rotation policies to delivery DTOs.
+func NewPoliciesFromApp(ps []*rotation.Policy) []*Policy {
+	result := make([]*Policy, 0, len(ps))
+	for _, p := range ps {
+		result = append(result, NewPolicyFromApp(p))
+	}
+	return result
+}
+
+// ListPoliciesResponse represents the response containing credential rotation policies.
+type ListPoliciesResponse struct {
+	// Policies contains the rotation policies of the credentials of the user.
+	Policies []*Policy `json:"policies"`
+}
+
+// SetPolicyRequest represents the request to set the rotation policy of a credential.
+type SetPolicyRequest struct {
+	// Kind contains the rotator replacing the secret: postgres, aws_iam or webhook (required).
+	Kind string `json:"kind"             binding:"required" example:"postgres"`
+	// Settings contains the address of the external system.
+	Settings Settings `json:"settings"`
+	// IntervalSeconds determines how often the secret is rotated; zero rotates on demand only.
+	IntervalSeconds int64 `json:"interval_seconds" binding:"min=0"  example:"86400"`
+}
+
+// CredentialIDRequest represents the credential addressed in the request path.
+type CredentialIDRequest struct {
+	// ID contains the credential identifier (required UUID format).
+	ID string `uri:"id" binding:"required,uuid" example:"123e4567-e89b-12d3-a456-426614174000"`
+}
@@ -0,0 +1,234 @@
+package rotation
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/rotation"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/response"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/util"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Service defines the credential rotation application service interface.
+type Service interface {
+	// SetPolicy creates or replaces the rotation policy of a credential of the owner.
+	SetPolicy(context.Context, rotation.SetPolicyParams) (*rotation.Policy, error)
+	// ListPolicies retrieves the rotation policies of the credentials of the owner.
+	ListPolicies(context.Context, rotation.ListParams) ([]*rotation.Policy, error)
+	// DeletePolicy removes the rotation policy of a credential of the owner.
+	DeletePolicy(context.Context, rotation.DeletePolicyParams) error
+	// RotateNow rotates the secret of a credential of the owner immediately.
+	RotateNow(context.Context, rotation.RotateParams) (*rotation.Policy, error)
+}
+
+// Handler handles HTTP requests for credential rotation endpoints.
+type Handler struct {
+	// s is the credential rotation service used to process operations.
+	s Service
+}
+
+// NewHandler creates a new credential rotation handler with the provided service.
+func NewHandler(s Service) *Handler {
+	return &Handler{s: s}
+}
+
+// List retrieves the credential rotation policies of the authenticated user.
+// @Summary      List credential rotation policies
+// @Description  Retrieves the rotation policies of the credentials of the user with the outcome of their last rotation
+// @Tags         Account
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} ListPoliciesResponse "Rotation policies retrieved successfully"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /account/credential-rotations [get]
+// .
+func (h *Handler) List(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	userID, err := extractor.UserID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+
+	policies, err := h.s.ListPolicies(c, rotation.ListParams{UserID: userID})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, ListPoliciesResponse{Policies: NewPoliciesFromApp(policies)})
+}
+
+// SetPolicy sets the rotation policy of a credential of the authenticated user.
+// @Summary      Set credential rotation policy
+// @Description  Configures the external system the credential secret belongs to and how often it is rotated.
+// @Description  The postgres rotator changes the password of the credential login, the aws_iam rotator replaces
+// @Description  the access key stored as login and password, and the webhook rotator posts the new secret.
+// .
+// @Tags         Account
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Credential ID" format(uuid)
+// @Param        request body SetPolicyRequest true "Rotator kind, settings and interval"
+// @Success      200 {object} Policy "Rotation policy set successfully"
+// @Failure      400 {object} response.Error "Bad request - invalid ID format, kind, settings or interval"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
+// @Failure      404 {object} response.Error "Not found - credential not found"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /account/credential-rotations/{id} [put]
+// .
+func (h *Handler) SetPolicy(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	userID, err := extractor.UserID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+
+	credentialID, ok := bindCredentialID(c, extractor)
+	if !ok {
+		return
+	}
+
+	// req holds the deserialized JSON request payload for the rotation policy.
+	var req SetPolicyRequest
+	if err := extractor.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+	if req.IntervalSeconds > math.MaxInt64/int64(time.Second) {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	policy, err := h.s.SetPolicy(c, rotation.SetPolicyParams{
+		Kind:         req.Kind,
+		Settings:     rotation.Settings(req.Settings),
+		Interval:     time.Duration(req.IntervalSeconds) * time.Second,
+		CredentialID: credentialID,
+		UserID:       userID,
+	})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, NewPolicyFromApp(policy))
+}
+
+// DeletePolicy removes the rotation policy of a credential of the authenticated user.
+// @Summary      Delete credential rotation policy
+// @Description  Stops rotating the credential; its current secret is kept
+// @Tags         Account
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Credential ID" format(uuid)
+// @Success      204 "Rotation policy deleted successfully"
+// @Failure      400 {object} response.Error "Bad request - invalid ID format"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
+// @Failure      404 {object} response.Error "Not found - rotation policy not found"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /account/credential-rotations/{id} [delete]
+// .
+func (h *Handler) DeletePolicy(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	userID, err := extractor.UserID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+
+	credentialID, ok := bindCredentialID(c, extractor)
+	if !ok {
+		return
+	}
+
+	if err := h.s.DeletePolicy(c, rotation.DeletePolicyParams{CredentialID: credentialID, UserID: userID}); err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.Data(http.StatusNoContent, "", nil)
+}
+
+// Rotate rotates the secret of a credential of the authenticated user immediately.
+// @Summary      Rotate credential
+// @Description  Generates a new secret, updates the external system and stores the secret as a new credential
+// @Description  version. A failed rotation is recorded on the policy.
+// .
+// @Tags         Account
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Credential ID" format(uuid)
+// @Success      200 {object} Policy "Credential rotated successfully"
+// @Failure      400 {object} response.Error "Bad request - invalid ID format"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
+// @Failure      404 {object} response.Error "Not found - rotation policy or credential not found"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Failure      502 {object} response.Error "Bad gateway - external system did not accept the new secret"
+// @Router       /account/credential-rotations/{id}/rotate [post]
+// .
+func (h *Handler) Rotate(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	userID, err := extractor.UserID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+
+	credentialID, ok := bindCredentialID(c, extractor)
+	if !ok {
+		return
+	}
+
+	policy, err := h.s.RotateNow(c, rotation.RotateParams{CredentialID: credentialID, UserID: userID})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, NewPolicyFromApp(policy))
+}
+
+// bindCredentialID extracts the credential identifier from the request path.
+// Responds with 400 Bad Request and returns false when the identifier is missing or malformed.
+func bindCredentialID(c *gin.Context, extractor *util.CtxExtractor) (uuid.UUID, bool) {
+	// req holds the deserialized URI parameters of the request.
+	var req CredentialIDRequest
+	if err := extractor.BindURI(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return uuid.Nil, false
+	}
+
+	credentialID, err := uuid.Parse(req.ID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return uuid.Nil, false
+	}
+	return credentialID, true
+}
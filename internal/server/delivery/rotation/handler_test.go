@@ -0,0 +1,363 @@
+package rotation
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/rotation"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/consts"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockRotationService implements Service for testing.
+type mockRotationService struct {
+	setPolicyFunc    func(ctx context.Context, params rotation.SetPolicyParams) (*rotation.Policy, error)
+	listPoliciesFunc func(ctx context.Context, params rotation.ListParams) ([]*rotation.Policy, error)
+	deletePolicyFunc func(ctx context.Context, params rotation.DeletePolicyParams) error
+	rotateNowFunc    func(ctx context.Context, params rotation.RotateParams) (*rotation.Policy, error)
+}
+
+func (m *mockRotationService) SetPolicy(
+	ctx context.Context,
+	params rotation.SetPolicyParams,
+) (*rotation.Policy, error) {
+	if m.setPolicyFunc != nil {
+		return m.setPolicyFunc(ctx, params)
+	}
+	return nil, nil
+}
+
+func (m *mockRotationService) ListPolicies(
+	ctx context.Context,
+	params rotation.ListParams,
+) ([]*rotation.Policy, error) {
+	if m.listPoliciesFunc != nil {
+		return m.listPoliciesFunc(ctx, params)
+	}
+	return nil, nil
+}
+
+func (m *mockRotationService) DeletePolicy(ctx context.Context, params rotation.DeletePolicyParams) error {
+	if m.deletePolicyFunc != nil {
+		return m.deletePolicyFunc(ctx, params)
+	}
+	return nil
+}
+
+func (m *mockRotationService) RotateNow(ctx context.Context, params rotation.RotateParams) (*rotation.Policy, error) {
+	if m.rotateNowFunc != nil {
+		return m.rotateNowFunc(ctx, params)
+	}
+	return nil, nil
+}
+
+func TestHandler_List(t *testing.T) {
+	t.Parallel()
+
+	userID, credentialID := uuid.New(), uuid.New()
+
+	tests := []struct {
+		mockService    *mockRotationService
+		name           string
+		setUser        bool
+		wantCount      int
+		expectedStatus int
+	}{
+		{
+			name:    "success",
+			setUser: true,
+			mockService: &mockRotationService{
+				listPoliciesFunc: func(_ context.Context, params rotation.ListParams) ([]*rotation.Policy, error) {
+					assert.Equal(t, rotation.ListParams{UserID: userID}, params)
+					return []*rotation.Policy{{CredentialID: credentialID, Kind: "webhook", Interval: time.Hour}}, nil
+				},
+			},
+			wantCount:      1,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "missing user context",
+			mockService:    &mockRotationService{},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:    "service error",
+			setUser: true,
+			mockService: &mockRotationService{
+				listPoliciesFunc: func(context.Context, rotation.ListParams) ([]*rotation.Policy, error) {
+					return nil, rotation.ErrRotationTechError
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/account/credential-rotations", nil)
+			if tt.setUser {
+				c.Set(consts.CtxKeyUserID, userID)
+			}
+
+			NewHandler(tt.mockService).List(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.wantCount == 0 {
+				return
+			}
+			var got ListPoliciesResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+			require.Len(t, got.Policies, tt.wantCount)
+			assert.Equal(t, credentialID, got.Policies[0].CredentialID)
+			assert.Equal(t, int64(3600), got.Policies[0].IntervalSeconds)
+		})
+	}
+}
+
+func TestHandler_SetPolicy(t *testing.T) {
+	t.Parallel()
+
+	userID, credentialID := uuid.New(), uuid.New()
+
+	tests := []struct {
+		mockService    *mockRotationService
+		name           string
+		id             string
+		body           string
+		expectedStatus int
+	}{
+		{
+			name: "success",
+			id:   credentialID.String(),
+			body: `{"kind":"postgres","settings":{"host":"db.example.com","database":"app"},"interval_seconds":86400}`,
+			mockService: &mockRotationService{
+				setPolicyFunc: func(_ context.Context, params rotation.SetPolicyParams) (*rotation.Policy, error) {
+					assert.Equal(t, rotation.SetPolicyParams{
+						Kind:         "postgres",
+						Settings:     rotation.Settings{Host: "db.example.com", Database: "app"},
+						Interval:     24 * time.Hour,
+						CredentialID: credentialID,
+						UserID:       userID,
+					}, params)
+					return &rotation.Policy{CredentialID: credentialID, Kind: "postgres"}, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid ID",
+			id:             "db",
+			body:           `{"kind":"webhook"}`,
+			mockService:    &mockRotationService{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "missing kind",
+			id:             credentialID.String(),
+			body:           `{"interval_seconds":86400}`,
+			mockService:    &mockRotationService{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "negative interval",
+			id:             credentialID.String(),
+			body:           `{"kind":"webhook","interval_seconds":-1}`,
+			mockService:    &mockRotationService{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "overflowing interval",
+			id:             credentialID.String(),
+			body:           `{"kind":"webhook","interval_seconds":9223372036854775807}`,
+			mockService:    &mockRotationService{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "invalid settings",
+			id:   credentialID.String(),
+			body: `{"kind":"webhook","settings":{"url":"http://hooks.example.com"}}`,
+			mockService: &mockRotationService{
+				setPolicyFunc: func(context.Context, rotation.SetPolicyParams) (*rotation.Policy, error) {
+					return nil, rotation.ErrIncorrectSettings
+				},
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "credential not found",
+			id:   credentialID.String(),
+			body: `{"kind":"webhook"}`,
+			mockService: &mockRotationService{
+				setPolicyFunc: func(context.Context, rotation.SetPolicyParams) (*rotation.Policy, error) {
+					return nil, rotation.ErrCredentialNotFound
+				},
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPut, "/account/credential-rotations/"+tt.id,
+				strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Params = gin.Params{{Key: "id", Value: tt.id}}
+			c.Set(consts.CtxKeyUserID, userID)
+
+			NewHandler(tt.mockService).SetPolicy(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestHandler_DeletePolicy(t *testing.T) {
+	t.Parallel()
+
+	userID, credentialID := uuid.New(), uuid.New()
+
+	tests := []struct {
+		mockService    *mockRotationService
+		name           string
+		id             string
+		expectedStatus int
+	}{
+		{
+			name: "success",
+			id:   credentialID.String(),
+			mockService: &mockRotationService{
+				deletePolicyFunc: func(_ context.Context, params rotation.DeletePolicyParams) error {
+					assert.Equal(t, rotation.DeletePolicyParams{CredentialID: credentialID, UserID: userID}, params)
+					return nil
+				},
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "invalid ID",
+			id:             "db",
+			mockService:    &mockRotationService{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "not configured",
+			id:   credentialID.String(),
+			mockService: &mockRotationService{
+				deletePolicyFunc: func(context.Context, rotation.DeletePolicyParams) error {
+					return rotation.ErrPolicyNotFound
+				},
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodDelete, "/account/credential-rotations/"+tt.id, nil)
+			c.Params = gin.Params{{Key: "id", Value: tt.id}}
+			c.Set(consts.CtxKeyUserID, userID)
+
+			NewHandler(tt.mockService).DeletePolicy(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestHandler_Rotate(t *testing.T) {
+	t.Parallel()
+
+	userID, credentialID := uuid.New(), uuid.New()
+	rotatedAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		mockService    *mockRotationService
+		name           string
+		id             string
+		expectedStatus int
+	}{
+		{
+			name: "success",
+			id:   credentialID.String(),
+			mockService: &mockRotationService{
+				rotateNowFunc: func(_ context.Context, params rotation.RotateParams) (*rotation.Policy, error) {
+					assert.Equal(t, rotation.RotateParams{CredentialID: credentialID, UserID: userID}, params)
+					return &rotation.Policy{CredentialID: credentialID, LastRotatedAt: rotatedAt}, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid ID",
+			id:             "db",
+			mockService:    &mockRotationService{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "not configured",
+			id:   credentialID.String(),
+			mockService: &mockRotationService{
+				rotateNowFunc: func(context.Context, rotation.RotateParams) (*rotation.Policy, error) {
+					return nil, rotation.ErrPolicyNotFound
+				},
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name: "rejected by the external system",
+			id:   credentialID.String(),
+			mockService: &mockRotationService{
+				rotateNowFunc: func(context.Context, rotation.RotateParams) (*rotation.Policy, error) {
+					return nil, rotation.ErrRotationFailed
+				},
+			},
+			expectedStatus: http.StatusBadGateway,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/account/credential-rotations/"+tt.id+"/rotate", nil)
+			c.Params = gin.Params{{Key: "id", Value: tt.id}}
+			c.Set(consts.CtxKeyUserID, userID)
+
+			NewHandler(tt.mockService).Rotate(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+			var got Policy
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+			assert.Equal(t, rotatedAt, got.LastRotatedAt)
+		})
+	}
+}
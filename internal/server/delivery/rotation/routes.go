@@ -0,0 +1,14 @@
+package rotation
+
+import "github.com/gin-gonic/gin"
+
+// RegisterRoutes registers credential rotation routes with the provided router group.
+// Creates /credential-rotations and the policy and rotation endpoints of /credential-rotations/:id with the
+// specified handler.
+func RegisterRoutes(r *gin.RouterGroup, h *Handler) {
+	rotationsGroup := r.Group("/credential-rotations")
+	rotationsGroup.GET("", h.List)
+	rotationsGroup.PUT("/:id", h.SetPolicy)
+	rotationsGroup.DELETE("/:id", h.DeletePolicy)
+	rotationsGroup.POST("/:id/rotate", h.Rotate)
+}
@@ -0,0 +1,27 @@
+package rotation
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterRoutes(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	RegisterRoutes(router.Group("/account"), NewHandler(&mockRotationService{}))
+
+	got := make(map[string]string)
+	for _, r := range router.Routes() {
+		got[r.Method+" "+r.Path] = r.Handler
+	}
+	assert.Len(t, got, 4)
+	assert.Contains(t, got, http.MethodGet+" /account/credential-rotations")
+	assert.Contains(t, got, http.MethodPut+" /account/credential-rotations/:id")
+	assert.Contains(t, got, http.MethodDelete+" /account/credential-rotations/:id")
+	assert.Contains(t, got, http.MethodPost+" /account/credential-rotations/:id/rotate")
+}
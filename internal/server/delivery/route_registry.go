@@ -1,23 +1,127 @@
 package delivery
 
 import (
+	"crypto/ed25519"
+	"time"
+
+	botcheckApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/botcheck"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/about"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/accesspolicy"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/account"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/acmeaccount"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/admin"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/adminapproval"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/approval"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/auditexport"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/auth"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/bankcard"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/billing"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/botcheck"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/checkout"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/credential"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/datasync"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/dav"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/devicekey"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/feature"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/filedata"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/health"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/invite"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/itemaccess"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/itemorder"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/itempath"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/itempolicy"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/itemtag"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/machine"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/metrics"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/middleware"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/note"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/notification"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/onboarding"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/plan"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/purge"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/report"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/rotation"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/scim"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/sdk"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/signingkey"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/swagger"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/version"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/websession"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/webui"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/wellknown"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/license"
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
+	"github.com/swaggo/swag"
 )
 
+// maintenanceExemptPrefixes lists routes accepting mutating requests in maintenance mode:
+// logins and leases, so users and their automations can still read their vaults, and the admin API that switches
+// the mode off.
+var maintenanceExemptPrefixes = []string{"/api/auth/login", "/api/lease", "/api/admin/"}
+
+// credentialCheckPrefixes lists routes that check client credentials, whose 401 responses count as
+// authentication failures: logins, machine token routes, WebDAV Basic authentication, and the admin and SCIM
+// tokens. Expired access tokens on the other routes are routine and are not counted.
+var credentialCheckPrefixes = []string{
+	"/api/auth/login",
+	"/api/lease",
+	"/api/kv",
+	"/api/external-secrets/",
+	"/api/dav",
+	"/api/admin/",
+	"/api/scim/",
+}
+
+// signedResponseRoutes lists the routes whose responses are signed with the response signing key: vault exports,
+// the sync manifest and recovery codes, whose tampering by a compromised proxy would mislead clients the most.
+var signedResponseRoutes = []string{"/api/items/export", "/api/items/sync/manifest", "/api/auth/recovery-codes"}
+
+// dualControlActions maps the method and route of the destructive admin requests held back until a second
+// operator approves them, when dual control is enabled, to the names of their actions: releasing legal holds,
+// which lets retention and purges delete the held data, deleting request recordings, and deleting retention
+// overrides, which lets the default retention delete data the override kept.
+var dualControlActions = map[string]string{
+	"DELETE /api/admin/legal-holds/:id":      "legal_hold.release",
+	"DELETE /api/admin/recordings/:id":       "recording.delete",
+	"DELETE /api/admin/retention/groups/:id": "retention_override.delete",
+}
+
+// davRealm names the protection space in the Basic authentication challenge of the WebDAV endpoint.
+const davRealm = "AegisVaultKeeper"
+
+// RouteTimeouts contains the request handling deadlines of the route groups; zero disables a deadline.
+type RouteTimeouts struct {
+	// Default bounds public, authentication, account, feature and admin requests.
+	Default time.Duration
+	// Items bounds item requests other than file transfers.
+	Items time.Duration
+	// Files bounds file upload and download requests.
+	Files time.Duration
+}
+
+// RequestSigning configures the HMAC signatures required on high-privilege requests and the Ed25519 signatures
+// of critical responses.
+type RequestSigning struct {
+	// Keys resolves the signing keys of users; nil disables signing of user requests.
+	Keys middleware.SigningKeyResolver
+	// ResponseKey signs the responses of signedResponseRoutes; nil disables signing of responses.
+	ResponseKey ed25519.PrivateKey
+	// AdminKey signs administrative requests; empty disables signing of them.
+	AdminKey string
+	// MaxSkew bounds how far the signing time of a request may be from the server time.
+	MaxSkew time.Duration
+}
+
 // BuildInfoOperator interface for accessing build information.
-type BuildInfoOperator about.BuildInfoOperator
+type BuildInfoOperator interface {
+	about.BuildInfoOperator
+	version.BuildDetailsReader
+}
+
+// MetricsSnapshotter interface for accessing operational metrics.
+type MetricsSnapshotter metrics.Snapshotter
 
 // RouteRegistry manages registration of all HTTP routes and their handlers.
 // Coordinates authentication, business logic services, and route grouping.
@@ -28,6 +132,8 @@ type RouteRegistry struct {
 	authJWTService middleware.AuthWithJWTService
 	// buildInfoOperator provides application build information.
 	buildInfoOperator BuildInfoOperator
+	// metricsSnapshotter provides operational metrics.
+	metricsSnapshotter MetricsSnapshotter
 	// bankcardService handles bank card operations.
 	bankcardService bankcard.Service
 	// credentialService handles credential operations.
@@ -38,6 +144,136 @@ type RouteRegistry struct {
 	datasyncService datasync.Service
 	// filedataService handles file data operations.
 	filedataService filedata.Service
+	// accountService handles account report operations.
+	accountService account.Service
+	// accessChecker enforces network access rules; nil disables enforcement.
+	accessChecker middleware.AccessChecker
+	// bruteForceGuard rejects banned clients and reports failed authentications; nil disables the protection.
+	bruteForceGuard middleware.BruteForceGuard
+	// humanChecker requires challenge solutions on registrations and repeated failed logins; nil disables the
+	// check.
+	humanChecker middleware.HumanChecker
+	// challengeService issues the challenges clients solve for the human check.
+	challengeService botcheck.Service
+	// adminService handles administrative operations.
+	adminService admin.Service
+	// maintenanceMode reports read-only maintenance mode; nil disables enforcement.
+	maintenanceMode middleware.MaintenanceMode
+	// maintenanceService switches read-only maintenance mode.
+	maintenanceService admin.MaintenanceService
+	// featureService resolves the feature flags of users.
+	featureService feature.Service
+	// featureFlagService manages stored feature flags.
+	featureFlagService admin.FeatureService
+	// diagnosticsService measures the runtime performance of the instance.
+	diagnosticsService admin.DiagnosticsService
+	// signingKeyService manages the request signing keys of users.
+	signingKeyService signingkey.Service
+	// directoryService provisions users and groups from an identity provider.
+	directoryService scim.Service
+	// notificationService manages the notification channels and push subscriptions of users.
+	notificationService notification.Service
+	// syncTrigger tells the other devices of a user that the vault changed; nil disables sync messages.
+	syncTrigger middleware.SyncTrigger
+	// accessPolicyService manages the access windows users define for themselves.
+	accessPolicyService accesspolicy.Service
+	// accessPolicyAdminService manages the access windows set by administrators.
+	accessPolicyAdminService admin.AccessPolicyService
+	// revealChecker rejects item reveals outside the access windows of the user; nil disables the check.
+	revealChecker middleware.RevealChecker
+	// itemTagService manages the tags users attach to their vault items.
+	itemTagService itemtag.Service
+	// restrictedChecker rejects access to restricted items from outside the geofence; nil disables the check.
+	restrictedChecker middleware.RestrictedItemChecker
+	// approvalService runs the approval workflow of items that require approval.
+	approvalService approval.Service
+	// approvalChecker rejects access to items that require approval without an approved request; nil disables
+	// the check.
+	approvalChecker middleware.ApprovalChecker
+	// checkoutService shares credentials with groups and checks them out to one member at a time.
+	checkoutService checkout.Service
+	// rotationService rotates credential secrets in the external systems they belong to.
+	rotationService rotation.Service
+	// machineService issues machine identities and leases vault items to them.
+	machineService machine.Service
+	// itemPathService manages the paths users assign to their vault items.
+	itemPathService itempath.Service
+	// acmeAccountService manages the ACME accounts certificate renewal tooling reads from the vault.
+	acmeAccountService acmeaccount.Service
+	// storageService reconciles stored file contents with file metadata.
+	storageService admin.StorageService
+	// statsService reports aggregate usage statistics.
+	statsService admin.StatsService
+	// inviteService manages the invite codes new users register with.
+	inviteService invite.Service
+	// vaultUnlocker verifies the unlock keys of vaults sealed with an unlock secret.
+	vaultUnlocker middleware.VaultUnlockService
+	// itemAccessService reports the access history of vault items.
+	itemAccessService itemaccess.Service
+	// revealRecorder records the reveals of vault items in their access history; nil disables the telemetry.
+	revealRecorder middleware.ItemAccessRecorder
+	// reportService generates CSV reports of vault items.
+	reportService report.Service
+	// planService reports the plans limiting users.
+	planService plan.Service
+	// billingService receives the webhooks of billing integrations.
+	billingService billing.Service
+	// licenseService manages the enterprise license.
+	licenseService admin.LicenseService
+	// licenseChecker rejects requests to unlicensed enterprise features; nil leaves them available.
+	licenseChecker middleware.LicenseChecker
+	// telemetryService previews the anonymous usage ping.
+	telemetryService admin.TelemetryService
+	// updateService reports available server updates.
+	updateService admin.UpdateService
+	// recordingService manages the request recordings of support debugging.
+	recordingService admin.RecordingService
+	// retentionService manages the data retention policies.
+	retentionService admin.RetentionService
+	// legalHoldService manages the legal holds.
+	legalHoldService admin.LegalHoldService
+	// itemPolicyService reports the item policy rules applying to users.
+	itemPolicyService itempolicy.Service
+	// itemPolicyAdminService manages the item policies.
+	itemPolicyAdminService admin.ItemPolicyService
+	// onboardingService runs the first-run wizard of a server with an empty database.
+	onboardingService onboarding.Service
+	// auditExportService exports the audit trail for compliance audits.
+	auditExportService auditexport.Service
+	// adminApprovalService lists and decides the destructive admin requests held back for approval.
+	adminApprovalService adminapproval.Service
+	// dualControlGate holds back destructive admin requests until a second operator approves them; nil disables
+	// dual control.
+	dualControlGate middleware.DualControlGate
+	// dryRunner runs dry-run item changes in transactions that roll back; nil rejects dry runs.
+	dryRunner middleware.DryRunner
+	// purgeService permanently deletes every item of one kind after confirmation.
+	purgeService purge.Service
+	// itemOrderService manages the manual orders users arrange the items of their folders in.
+	itemOrderService itemorder.Service
+	// deviceKeyService manages the device keys sync responses are encrypted for.
+	deviceKeyService devicekey.Service
+	// deviceKeys resolves the device keys named by sync requests; nil disables encryption of sync responses.
+	deviceKeys middleware.DeviceKeyResolver
+	// timeoutRecorder counts timed out and canceled requests; nil disables counting.
+	timeoutRecorder middleware.TimeoutRecorder
+	// timeouts contains the request handling deadlines of the route groups.
+	timeouts RouteTimeouts
+	// signing configures the signatures required on vault exports and administrative requests.
+	signing RequestSigning
+	// sessions configures the cookie sessions of web clients.
+	sessions middleware.SessionConfig
+	// webUI determines whether the embedded web vault UI is served.
+	webUI bool
+	// autofill describes the native apps associated with the server for autofill.
+	autofill wellknown.Associations
+	// adminToken authorizes administrative requests; empty disables the admin API.
+	adminToken string
+	// adminOperators maps the names of admin operators to their own tokens, which authorize administrative
+	// requests as the admin token does.
+	adminOperators map[string]string
+	// scimToken authorizes SCIM provisioning requests; empty disables the SCIM API.
+	scimToken string
 }
 
 // NewRouteRegistry creates a new RouteRegistry with all required service dependencies.
@@ -45,52 +281,481 @@ func NewRouteRegistry(
 	authService auth.Service,
 	authJWTService middleware.AuthWithJWTService,
 	buildInfoOperator BuildInfoOperator,
+	metricsSnapshotter MetricsSnapshotter,
 	bankcardService bankcard.Service,
 	credentialService credential.Service,
 	noteService note.Service,
 	datasyncService datasync.Service,
 	filedataService filedata.Service,
+	accountService account.Service,
+	accessChecker middleware.AccessChecker,
+	bruteForceGuard middleware.BruteForceGuard,
+	humanChecker middleware.HumanChecker,
+	challengeService botcheck.Service,
+	adminService admin.Service,
+	maintenanceMode middleware.MaintenanceMode,
+	maintenanceService admin.MaintenanceService,
+	featureService feature.Service,
+	featureFlagService admin.FeatureService,
+	diagnosticsService admin.DiagnosticsService,
+	signingKeyService signingkey.Service,
+	directoryService scim.Service,
+	notificationService notification.Service,
+	syncTrigger middleware.SyncTrigger,
+	accessPolicyService accesspolicy.Service,
+	accessPolicyAdminService admin.AccessPolicyService,
+	revealChecker middleware.RevealChecker,
+	itemTagService itemtag.Service,
+	restrictedChecker middleware.RestrictedItemChecker,
+	approvalService approval.Service,
+	approvalChecker middleware.ApprovalChecker,
+	checkoutService checkout.Service,
+	rotationService rotation.Service,
+	machineService machine.Service,
+	itemPathService itempath.Service,
+	acmeAccountService acmeaccount.Service,
+	storageService admin.StorageService,
+	statsService admin.StatsService,
+	inviteService invite.Service,
+	vaultUnlocker middleware.VaultUnlockService,
+	itemAccessService itemaccess.Service,
+	revealRecorder middleware.ItemAccessRecorder,
+	reportService report.Service,
+	planService plan.Service,
+	billingService billing.Service,
+	licenseService admin.LicenseService,
+	licenseChecker middleware.LicenseChecker,
+	telemetryService admin.TelemetryService,
+	updateService admin.UpdateService,
+	recordingService admin.RecordingService,
+	retentionService admin.RetentionService,
+	legalHoldService admin.LegalHoldService,
+	itemPolicyService itempolicy.Service,
+	itemPolicyAdminService admin.ItemPolicyService,
+	onboardingService onboarding.Service,
+	auditExportService auditexport.Service,
+	adminApprovalService adminapproval.Service,
+	dualControlGate middleware.DualControlGate,
+	dryRunner middleware.DryRunner,
+	purgeService purge.Service,
+	itemOrderService itemorder.Service,
+	deviceKeyService devicekey.Service,
+	deviceKeys middleware.DeviceKeyResolver,
+	timeouts RouteTimeouts,
+	signing RequestSigning,
+	sessions middleware.SessionConfig,
+	webUI bool,
+	autofill wellknown.Associations,
+	timeoutRecorder middleware.TimeoutRecorder,
+	adminToken string,
+	adminOperators map[string]string,
+	scimToken string,
 ) *RouteRegistry {
 	return &RouteRegistry{
-		authService:       authService,
-		authJWTService:    authJWTService,
-		buildInfoOperator: buildInfoOperator,
-		bankcardService:   bankcardService,
-		credentialService: credentialService,
-		noteService:       noteService,
-		datasyncService:   datasyncService,
-		filedataService:   filedataService,
+		authService:              authService,
+		authJWTService:           authJWTService,
+		buildInfoOperator:        buildInfoOperator,
+		metricsSnapshotter:       metricsSnapshotter,
+		bankcardService:          bankcardService,
+		credentialService:        credentialService,
+		noteService:              noteService,
+		datasyncService:          datasyncService,
+		filedataService:          filedataService,
+		accountService:           accountService,
+		accessChecker:            accessChecker,
+		bruteForceGuard:          bruteForceGuard,
+		humanChecker:             humanChecker,
+		challengeService:         challengeService,
+		adminService:             adminService,
+		maintenanceMode:          maintenanceMode,
+		maintenanceService:       maintenanceService,
+		featureService:           featureService,
+		featureFlagService:       featureFlagService,
+		diagnosticsService:       diagnosticsService,
+		signingKeyService:        signingKeyService,
+		directoryService:         directoryService,
+		notificationService:      notificationService,
+		syncTrigger:              syncTrigger,
+		accessPolicyService:      accessPolicyService,
+		accessPolicyAdminService: accessPolicyAdminService,
+		revealChecker:            revealChecker,
+		itemTagService:           itemTagService,
+		restrictedChecker:        restrictedChecker,
+		approvalService:          approvalService,
+		approvalChecker:          approvalChecker,
+		checkoutService:          checkoutService,
+		rotationService:          rotationService,
+		machineService:           machineService,
+		itemPathService:          itemPathService,
+		acmeAccountService:       acmeAccountService,
+		storageService:           storageService,
+		statsService:             statsService,
+		inviteService:            inviteService,
+		vaultUnlocker:            vaultUnlocker,
+		itemAccessService:        itemAccessService,
+		revealRecorder:           revealRecorder,
+		reportService:            reportService,
+		planService:              planService,
+		billingService:           billingService,
+		licenseService:           licenseService,
+		licenseChecker:           licenseChecker,
+		telemetryService:         telemetryService,
+		updateService:            updateService,
+		recordingService:         recordingService,
+		retentionService:         retentionService,
+		legalHoldService:         legalHoldService,
+		itemPolicyService:        itemPolicyService,
+		itemPolicyAdminService:   itemPolicyAdminService,
+		onboardingService:        onboardingService,
+		auditExportService:       auditExportService,
+		adminApprovalService:     adminApprovalService,
+		dualControlGate:          dualControlGate,
+		dryRunner:                dryRunner,
+		purgeService:             purgeService,
+		itemOrderService:         itemOrderService,
+		deviceKeyService:         deviceKeyService,
+		deviceKeys:               deviceKeys,
+		timeouts:                 timeouts,
+		signing:                  signing,
+		sessions:                 sessions,
+		webUI:                    webUI,
+		autofill:                 autofill,
+		timeoutRecorder:          timeoutRecorder,
+		adminToken:               adminToken,
+		adminOperators:           adminOperators,
+		scimToken:                scimToken,
 	}
 }
 
 // RegisterRoutes configures all application routes on the provided Gin engine.
-// Sets up base routes (health, auth, swagger, about) and protected item routes.
+// Sets up base routes (health, auth, swagger, about, metrics), the machine token protected lease routes,
+// protected item, report, WebDAV, account, feature and plan routes, billing webhooks, administrative routes, the
+// audit trail export and SCIM provisioning routes, the app association files and response signing key under
+// "/.well-known", and the web vault UI under "/ui" when enabled.
 func (rr *RouteRegistry) RegisterRoutes(router *gin.Engine) {
 	baseGroup := rr.makeBaseGroup(router)
 	rr.registerBaseRoutes(baseGroup)
+	rr.registerLeaseRoutes(baseGroup)
 	rr.registerItemsRoutes(baseGroup)
+	rr.registerReportRoutes(baseGroup)
+	rr.registerWebDAVRoutes(baseGroup)
+	rr.registerAccountRoutes(baseGroup)
+	rr.registerFeatureRoutes(baseGroup)
+	rr.registerPlanRoutes(baseGroup)
+	rr.registerBillingRoutes(baseGroup)
+	rr.registerAdminRoutes(baseGroup)
+	rr.registerAuditExportRoutes(baseGroup)
+	rr.registerSCIMRoutes(baseGroup)
+	rr.registerWellKnownRoutes(router)
+	rr.registerWebUIRoutes(router)
+}
+
+// registerWebUIRoutes registers the embedded web vault UI under "/ui", outside of the "/api" prefix, and redirects
+// the root of the domain to it. The UI is static and calls the API from the browser, so the API middleware applies
+// to those calls only.
+func (rr *RouteRegistry) registerWebUIRoutes(router *gin.Engine) {
+	if !rr.webUI {
+		return
+	}
+	group := router.Group("", rr.timeout(rr.timeouts.Default))
+	webui.RegisterRoutes(group, webui.NewHandler())
+}
+
+// registerWellKnownRoutes registers the app association files native autofill services fetch from the root of the
+// domain, outside of the "/api" prefix, and the public key signed responses are verified with.
+func (rr *RouteRegistry) registerWellKnownRoutes(router *gin.Engine) {
+	group := router.Group("", rr.timeout(rr.timeouts.Default))
+	// responseKey holds the public half of the response signing key; nil when responses are not signed.
+	var responseKey ed25519.PublicKey
+	if rr.signing.ResponseKey != nil {
+		responseKey = rr.signing.ResponseKey.Public().(ed25519.PublicKey)
+	}
+	wellknown.RegisterRoutes(group, wellknown.NewHandler(rr.autofill, responseKey))
 }
 
 // makeBaseGroup creates the base API route group with "/api" prefix.
+// Banned clients, global network access rules and read-only maintenance mode apply to every request under it.
+// Web clients holding a cookie session are authenticated by it, after the CSRF token check of the session.
+// Request deadlines are set per nested group, since a nested deadline cannot outlast an outer one.
 func (rr *RouteRegistry) makeBaseGroup(router *gin.Engine) *gin.RouterGroup {
-	return router.Group("/api")
+	return router.Group(
+		"/api",
+		middleware.BruteForce(rr.bruteForceGuard, credentialCheckPrefixes...),
+		middleware.AccessControl(rr.accessChecker),
+		middleware.Maintenance(rr.maintenanceMode, maintenanceExemptPrefixes...),
+		middleware.CookieSession(rr.sessions),
+	)
 }
 
 // registerBaseRoutes registers public routes that don't require authentication.
-func (rr *RouteRegistry) registerBaseRoutes(group *gin.RouterGroup) {
-	health.RegisterRoutes(group, health.NewHandler())
-	auth.RegisterRoutes(group, auth.NewHandler(rr.authService))
+// Authentication responses carry access tokens and challenges are single use, so caching of them is disabled.
+// Registrations and logins after repeated failures require a solved challenge when the human check is enabled.
+// Password, recovery code, unlock secret and duress password changes are the only authenticated auth routes;
+// they work on locked vaults. The onboarding wizard creates the first account, so it is public as well and closes
+// itself once an account exists. Web clients exchange their access token for a cookie session here.
+func (rr *RouteRegistry) registerBaseRoutes(baseGroup *gin.RouterGroup) {
+	group := baseGroup.Group("", rr.timeout(rr.timeouts.Default))
+	health.RegisterRoutes(group, health.NewHandler(rr.updateService))
+	authGroup := group.Group(
+		"",
+		middleware.NoStore(),
+		middleware.ResponseSignature(rr.signing.ResponseKey, signedResponseRoutes...),
+	)
+	auth.RegisterRoutes(authGroup, auth.NewHandler(rr.authService), auth.Guards{
+		Register: []gin.HandlerFunc{middleware.HumanCheck(rr.humanChecker, botcheckApp.ActionRegister)},
+		Login:    []gin.HandlerFunc{middleware.HumanCheck(rr.humanChecker, botcheckApp.ActionLogin)},
+		Authenticated: []gin.HandlerFunc{
+			middleware.AuthWithJWT(rr.authJWTService),
+			middleware.AccessControl(rr.accessChecker),
+		},
+	})
+	botcheck.RegisterRoutes(authGroup, botcheck.NewHandler(rr.challengeService))
+	onboarding.RegisterRoutes(authGroup, onboarding.NewHandler(rr.onboardingService))
+	websession.RegisterRoutes(authGroup, websession.NewHandler(rr.sessions), middleware.AuthWithJWT(rr.authJWTService))
 	swagger.RegisterRoutes(group, ginSwagger.WrapHandler(swaggerFiles.Handler))
 	about.RegisterRoutes(group, about.NewHandler(rr.buildInfoOperator))
+	versionGroup := group.Group("", middleware.OptionalAuthWithJWT(rr.authJWTService))
+	version.RegisterRoutes(versionGroup, version.NewHandler(rr.buildInfoOperator))
+	metrics.RegisterRoutes(group, metrics.NewHandler(rr.metricsSnapshotter))
+	sdk.RegisterRoutes(group, sdk.NewHandler(func() (string, error) { return swag.ReadDoc() }))
+}
+
+// registerLeaseRoutes registers the lease, secret path and External Secrets Operator routes authenticated by
+// machine tokens instead of JWTs. They carry secret values, so caching of them is disabled; the per-user rules of
+// the owner of the machine identity are enforced by the lease service.
+func (rr *RouteRegistry) registerLeaseRoutes(baseGroup *gin.RouterGroup) {
+	group := baseGroup.Group("", rr.timeout(rr.timeouts.Default), middleware.NoStore())
+	handler := machine.NewHandler(rr.machineService)
+	machine.RegisterLeaseRoutes(group, handler)
+	machine.RegisterKVRoutes(group, handler)
+	machine.RegisterExternalSecretsRoutes(group, handler)
 }
 
 // registerItemsRoutes registers protected routes that require JWT authentication.
-// All item endpoints are under "/api/items" with JWT middleware protection.
+// All item endpoints are under "/api/items" with JWT middleware protection, per-user network
+// access rules and caching disabled. File transfers, including captures of items with their files,
+// get their own, usually longer, deadline.
+// Sync responses are encrypted for the device key named by the request and exports and the sync manifest are
+// signed by the server. Reads of
+// single items are recorded in their access history.
 func (rr *RouteRegistry) registerItemsRoutes(group *gin.RouterGroup) {
-	itemsGroup := group.Group("items", middleware.AuthWithJWT(rr.authJWTService))
-	bankcard.RegisterRoutes(itemsGroup, bankcard.NewHandler(rr.bankcardService))
-	credential.RegisterRoutes(itemsGroup, credential.NewHandler(rr.credentialService))
-	note.RegisterRoutes(itemsGroup, note.NewHandler(rr.noteService))
-	datasync.RegisterRoutes(itemsGroup, datasync.NewHandler(rr.datasyncService))
-	filedata.RegisterRoutes(itemsGroup, filedata.NewHandler(rr.filedataService))
+	reveal := middleware.RevealTelemetry(rr.revealRecorder)
+	itemsGroup := rr.makeItemsGroup(group, rr.timeouts.Items)
+	bankcard.RegisterRoutes(itemsGroup, bankcard.NewHandler(rr.bankcardService), reveal)
+	credential.RegisterRoutes(itemsGroup, credential.NewHandler(rr.credentialService), reveal)
+	note.RegisterRoutes(itemsGroup, note.NewHandler(rr.noteService), reveal)
+	itemtag.RegisterRoutes(itemsGroup, itemtag.NewHandler(rr.itemTagService))
+	itempath.RegisterRoutes(itemsGroup, itempath.NewHandler(rr.itemPathService))
+	itemorder.RegisterRoutes(itemsGroup, itemorder.NewHandler(rr.itemOrderService))
+	itemaccess.RegisterRoutes(itemsGroup, itemaccess.NewHandler(rr.itemAccessService))
+	purge.RegisterRoutes(itemsGroup, purge.NewHandler(rr.purgeService))
+	acmeaccount.RegisterRoutes(itemsGroup, acmeaccount.NewHandler(rr.acmeAccountService), reveal)
+	datasync.RegisterRoutes(
+		itemsGroup.Group(
+			"",
+			middleware.ResponseSignature(rr.signing.ResponseKey, signedResponseRoutes...),
+			middleware.DevicePayloadEncryption(rr.deviceKeys),
+		),
+		datasync.NewHandler(rr.datasyncService),
+	)
+	filesGroup := rr.makeItemsGroup(group, rr.timeouts.Files)
+	filedata.RegisterRoutes(filesGroup, filedata.NewHandler(rr.filedataService), reveal)
+	datasync.RegisterCaptureRoutes(filesGroup, datasync.NewHandler(rr.datasyncService))
+}
+
+// makeItemsGroup creates an "/api/items" route group bounded by the timeout. Requests of users holding signing
+// keys must be signed with one of them. Reads are rejected
+// outside the access windows of the user, restricted items are only accessible inside the geofence,
+// items that require approval only with an approved request and successful changes send sync messages
+// to the other devices of the user. Vaults sealed with an unlock secret require the unlock key. Changes
+// marked as dry runs are checked in full and rolled back.
+func (rr *RouteRegistry) makeItemsGroup(group *gin.RouterGroup, timeout time.Duration) *gin.RouterGroup {
+	return group.Group(
+		"items",
+		rr.timeout(timeout),
+		middleware.NoStore(),
+		middleware.AuthWithJWT(rr.authJWTService),
+		middleware.AccessControl(rr.accessChecker),
+		middleware.RequestSignature(rr.signing.Keys, rr.signing.MaxSkew),
+		middleware.VaultUnlock(rr.vaultUnlocker),
+		middleware.RevealWindow(rr.revealChecker),
+		middleware.RestrictedItems(rr.restrictedChecker),
+		middleware.ApprovalRequired(rr.approvalChecker),
+		middleware.SyncTriggers(rr.syncTrigger),
+		middleware.DryRun(rr.dryRunner),
+	)
+}
+
+// registerReportRoutes registers the CSV report routes under "/api/reports". Reports list the items of the user,
+// so the item access rules, the request signatures, the unlock key and the deadline of item requests apply as for
+// item listings.
+func (rr *RouteRegistry) registerReportRoutes(group *gin.RouterGroup) {
+	reportsGroup := group.Group(
+		"reports",
+		rr.timeout(rr.timeouts.Items),
+		middleware.NoStore(),
+		middleware.AuthWithJWT(rr.authJWTService),
+		middleware.AccessControl(rr.accessChecker),
+		middleware.RequestSignature(rr.signing.Keys, rr.signing.MaxSkew),
+		middleware.VaultUnlock(rr.vaultUnlocker),
+		middleware.RevealWindow(rr.revealChecker),
+		middleware.RestrictedItems(rr.restrictedChecker),
+		middleware.ApprovalRequired(rr.approvalChecker),
+	)
+	report.RegisterRoutes(reportsGroup, report.NewHandler(rr.reportService))
+}
+
+// registerWebDAVRoutes registers the WebDAV endpoint of the file vault under "/api/dav". Drive clients
+// authenticate with the access token as a Bearer token or as the password of Basic credentials; the item
+// access rules, the request signatures, the unlock key and the deadline of file transfers apply as for the file
+// routes.
+func (rr *RouteRegistry) registerWebDAVRoutes(group *gin.RouterGroup) {
+	davGroup := group.Group(
+		"dav",
+		rr.timeout(rr.timeouts.Files),
+		middleware.NoStore(),
+		middleware.AuthWithJWTOrBasic(rr.authJWTService, davRealm),
+		middleware.AccessControl(rr.accessChecker),
+		middleware.RequestSignature(rr.signing.Keys, rr.signing.MaxSkew),
+		middleware.VaultUnlock(rr.vaultUnlocker),
+		middleware.RevealWindow(rr.revealChecker),
+		middleware.RestrictedItems(rr.restrictedChecker),
+		middleware.ApprovalRequired(rr.approvalChecker),
+		middleware.SyncTriggers(rr.syncTrigger),
+	)
+	dav.RegisterRoutes(davGroup, dav.NewHandler(rr.filedataService))
+}
+
+// registerAccountRoutes registers protected account routes that require JWT authentication.
+// All account endpoints, including approval of held logins, signing and device keys, notification channels, push
+// subscriptions, access windows, item access requests, shared credentials, credential rotations and machine
+// identities, are under "/api/account" with JWT middleware protection, per-user network access rules and caching
+// disabled. Requests of users holding signing keys, including those registering or revoking signing keys, must be
+// signed with one of them, so a leaked access token cannot replace the keys or lift the signing requirement.
+// Account data is encrypted with the vault key, so vaults sealed with an unlock secret require the unlock key.
+func (rr *RouteRegistry) registerAccountRoutes(group *gin.RouterGroup) {
+	accountGroup := group.Group(
+		"account",
+		rr.timeout(rr.timeouts.Default),
+		middleware.NoStore(),
+		middleware.AuthWithJWT(rr.authJWTService),
+		middleware.AccessControl(rr.accessChecker),
+		middleware.RequestSignature(rr.signing.Keys, rr.signing.MaxSkew),
+		middleware.VaultUnlock(rr.vaultUnlocker),
+	)
+	account.RegisterRoutes(accountGroup, account.NewHandler(rr.accountService))
+	auth.RegisterAccountRoutes(accountGroup, auth.NewHandler(rr.authService))
+	signingkey.RegisterRoutes(accountGroup, signingkey.NewHandler(rr.signingKeyService))
+	devicekey.RegisterRoutes(accountGroup, devicekey.NewHandler(rr.deviceKeyService))
+	notification.RegisterRoutes(accountGroup, notification.NewHandler(rr.notificationService))
+	accesspolicy.RegisterRoutes(accountGroup, accesspolicy.NewHandler(rr.accessPolicyService))
+	itempolicy.RegisterRoutes(accountGroup, itempolicy.NewHandler(rr.itemPolicyService))
+	approval.RegisterRoutes(accountGroup, approval.NewHandler(rr.approvalService))
+	checkout.RegisterRoutes(accountGroup, checkout.NewHandler(rr.checkoutService))
+	rotation.RegisterRoutes(accountGroup, rotation.NewHandler(rr.rotationService))
+	machine.RegisterRoutes(accountGroup, machine.NewHandler(rr.machineService))
+	invite.RegisterRoutes(accountGroup, invite.NewHandler(rr.inviteService))
+}
+
+// registerFeatureRoutes registers protected feature flag routes that require JWT authentication.
+// The effective flags of the user are served under "/api/features" with JWT middleware protection
+// and per-user network access rules.
+func (rr *RouteRegistry) registerFeatureRoutes(group *gin.RouterGroup) {
+	featuresGroup := group.Group(
+		"features",
+		rr.timeout(rr.timeouts.Default),
+		middleware.AuthWithJWT(rr.authJWTService),
+		middleware.AccessControl(rr.accessChecker),
+	)
+	feature.RegisterRoutes(featuresGroup, feature.NewHandler(rr.featureService))
+}
+
+// registerPlanRoutes registers protected plan routes that require JWT authentication.
+// The plan of the user is served under "/api/plan" with JWT middleware protection
+// and per-user network access rules.
+func (rr *RouteRegistry) registerPlanRoutes(group *gin.RouterGroup) {
+	planGroup := group.Group(
+		"plan",
+		rr.timeout(rr.timeouts.Default),
+		middleware.AuthWithJWT(rr.authJWTService),
+		middleware.AccessControl(rr.accessChecker),
+	)
+	plan.RegisterRoutes(planGroup, plan.NewHandler(rr.planService))
+}
+
+// registerBillingRoutes registers public billing webhook routes.
+// Webhooks are served under "/api/billing" without JWT authentication: billing providers authenticate them
+// by signing the request body.
+func (rr *RouteRegistry) registerBillingRoutes(group *gin.RouterGroup) {
+	billingGroup := group.Group("billing", rr.timeout(rr.timeouts.Default))
+	billing.RegisterRoutes(billingGroup, billing.NewHandler(rr.billingService))
+}
+
+// registerAdminRoutes registers administrative routes that require the admin token.
+// All admin endpoints are under "/api/admin" with admin token protection and caching disabled.
+// With an admin signing key configured, every request must also be signed with it. With dual control enabled,
+// the requests listed in dualControlActions run only once a second operator approves them.
+func (rr *RouteRegistry) registerAdminRoutes(group *gin.RouterGroup) {
+	adminGroup := group.Group(
+		"admin",
+		rr.timeout(rr.timeouts.Default),
+		middleware.NoStore(),
+		middleware.AdminToken(rr.adminToken, rr.adminOperators),
+		middleware.AdminRequestSignature(rr.signing.AdminKey, rr.signing.MaxSkew),
+		middleware.DualControl(rr.dualControlGate, dualControlActions),
+	)
+	handler := admin.NewHandler(
+		rr.adminService,
+		rr.maintenanceService,
+		rr.featureFlagService,
+		rr.diagnosticsService,
+		rr.accessPolicyAdminService,
+		rr.storageService,
+		rr.statsService,
+		rr.licenseService,
+		rr.telemetryService,
+		rr.updateService,
+		rr.recordingService,
+		rr.retentionService,
+		rr.legalHoldService,
+		rr.itemPolicyAdminService,
+	)
+	admin.RegisterRoutes(adminGroup, handler)
+	invite.RegisterAdminRoutes(adminGroup, invite.NewHandler(rr.inviteService))
+	adminapproval.RegisterRoutes(adminGroup, adminapproval.NewHandler(rr.adminApprovalService))
+}
+
+// registerAuditExportRoutes registers the audit trail export route under "/api/admin/audit" with the admin
+// token and request signatures required as for the other admin routes, the audit export license requirement
+// and caching disabled. Exports of long periods are streamed, so the deadline of file transfers applies; the
+// group is a sibling of the admin group, since a nested deadline cannot outlast an outer one.
+func (rr *RouteRegistry) registerAuditExportRoutes(group *gin.RouterGroup) {
+	auditGroup := group.Group(
+		"admin/audit",
+		rr.timeout(rr.timeouts.Files),
+		middleware.NoStore(),
+		middleware.AdminToken(rr.adminToken, rr.adminOperators),
+		middleware.AdminRequestSignature(rr.signing.AdminKey, rr.signing.MaxSkew),
+		middleware.RequireLicense(rr.licenseChecker, license.FeatureAuditExport),
+	)
+	auditexport.RegisterRoutes(auditGroup, auditexport.NewHandler(rr.auditExportService))
+}
+
+// registerSCIMRoutes registers SCIM 2.0 provisioning routes that require the SCIM token.
+// All SCIM endpoints are under "/api/scim/v2" with SCIM token protection, the SCIM license requirement and
+// caching disabled.
+func (rr *RouteRegistry) registerSCIMRoutes(group *gin.RouterGroup) {
+	scimGroup := group.Group(
+		"scim/v2",
+		rr.timeout(rr.timeouts.Default),
+		middleware.NoStore(),
+		middleware.SCIMToken(rr.scimToken),
+		middleware.RequireLicense(rr.licenseChecker, license.FeatureSCIM),
+	)
+	scim.RegisterRoutes(scimGroup, scim.NewHandler(rr.directoryService))
+}
+
+// timeout creates the request deadline middleware of a route group.
+func (rr *RouteRegistry) timeout(d time.Duration) gin.HandlerFunc {
+	return middleware.Timeout(d, rr.timeoutRecorder)
 }
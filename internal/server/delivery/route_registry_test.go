@@ -1,9 +1,25 @@
 package delivery
 
 import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/accesscontrol"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/adminapproval"
+	auditexportApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/auditexport"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/botcheck"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/bruteforce"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/signingkey"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/middleware"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/wellknown"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -28,14 +44,78 @@ func TestNewRouteRegistry(t *testing.T) {
 			// Test that we can create a registry with nil services
 			// This tests the constructor without requiring full interface implementation
 			registry := NewRouteRegistry(
-				nil, // authService
-				nil, // authJWTService
-				nil, // buildInfoOperator
-				nil, // bankcardService
-				nil, // credentialService
-				nil, // noteService
-				nil, // datasyncService
-				nil, // filedataService
+				nil,                        // authService
+				nil,                        // authJWTService
+				nil,                        // buildInfoOperator
+				nil,                        // metricsSnapshotter
+				nil,                        // bankcardService
+				nil,                        // credentialService
+				nil,                        // noteService
+				nil,                        // datasyncService
+				nil,                        // filedataService
+				nil,                        // accountService
+				nil,                        // accessChecker
+				nil,                        // bruteForceGuard
+				nil,                        // humanChecker
+				nil,                        // challengeService
+				nil,                        // adminService
+				nil,                        // maintenanceMode
+				nil,                        // maintenanceService
+				nil,                        // featureService
+				nil,                        // featureFlagService
+				nil,                        // diagnosticsService
+				nil,                        // signingKeyService
+				nil,                        // directoryService
+				nil,                        // notificationService
+				nil,                        // syncTrigger
+				nil,                        // accessPolicyService
+				nil,                        // accessPolicyAdminService
+				nil,                        // revealChecker
+				nil,                        // itemTagService
+				nil,                        // restrictedChecker
+				nil,                        // approvalService
+				nil,                        // approvalChecker
+				nil,                        // checkoutService
+				nil,                        // rotationService
+				nil,                        // machineService
+				nil,                        // itemPathService
+				nil,                        // acmeAccountService
+				nil,                        // storageService
+				nil,                        // statsService
+				nil,                        // inviteService
+				nil,                        // vaultUnlocker
+				nil,                        // itemAccessService
+				nil,                        // revealRecorder
+				nil,                        // reportService
+				nil,                        // planService
+				nil,                        // billingService
+				nil,                        // licenseService
+				nil,                        // licenseChecker
+				nil,                        // telemetryService
+				nil,                        // updateService
+				nil,                        // recordingService
+				nil,                        // retentionService
+				nil,                        // legalHoldService
+				nil,                        // itemPolicyService
+				nil,                        // itemPolicyAdminService
+				nil,                        // onboardingService
+				nil,                        // auditExportService
+				nil,                        // adminApprovalService
+				nil,                        // dualControlGate
+				nil,                        // dryRunner
+				nil,                        // purgeService
+				nil,                        // itemOrderService
+				nil,                        // deviceKeyService
+				nil,                        // deviceKeys
+				RouteTimeouts{},            // timeouts
+				RequestSigning{},           // signing
+				middleware.SessionConfig{}, // sessions
+				false,                      // webUI
+				wellknown.Associations{},   // autofill
+				nil,                        // timeoutRecorder
+				"",                         // adminToken
+				nil,                        // adminOperators
+				"",                         // scimToken
 			)
 
 			require.NotNil(t, registry)
@@ -47,6 +127,25 @@ func TestNewRouteRegistry(t *testing.T) {
 			assert.Nil(t, registry.noteService)
 			assert.Nil(t, registry.datasyncService)
 			assert.Nil(t, registry.filedataService)
+			assert.Nil(t, registry.accountService)
+			assert.Nil(t, registry.accessChecker)
+			assert.Nil(t, registry.adminService)
+			assert.Nil(t, registry.maintenanceMode)
+			assert.Nil(t, registry.maintenanceService)
+			assert.Nil(t, registry.featureService)
+			assert.Nil(t, registry.featureFlagService)
+			assert.Nil(t, registry.diagnosticsService)
+			assert.Nil(t, registry.signingKeyService)
+			assert.Nil(t, registry.directoryService)
+			assert.Nil(t, registry.notificationService)
+			assert.Nil(t, registry.syncTrigger)
+			assert.Nil(t, registry.accessPolicyService)
+			assert.Nil(t, registry.accessPolicyAdminService)
+			assert.Nil(t, registry.revealChecker)
+			assert.Nil(t, registry.itemTagService)
+			assert.Nil(t, registry.restrictedChecker)
+			assert.Empty(t, registry.adminToken)
+			assert.Empty(t, registry.scimToken)
 		})
 	}
 }
@@ -74,7 +173,10 @@ func TestRouteRegistry_RegisterRoutes(t *testing.T) {
 			router := gin.New()
 
 			registry := NewRouteRegistry(
-				nil, nil, nil, nil, nil, nil, nil, nil,
+				nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+				nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+				nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+				RouteTimeouts{}, RequestSigning{}, middleware.SessionConfig{}, false, wellknown.Associations{}, nil, "", nil, "",
 			)
 
 			// This should not panic even with nil services
@@ -110,7 +212,10 @@ func TestRouteRegistry_MakeBaseGroup(t *testing.T) {
 			router := gin.New()
 
 			registry := NewRouteRegistry(
-				nil, nil, nil, nil, nil, nil, nil, nil,
+				nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+				nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+				nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+				RouteTimeouts{}, RequestSigning{}, middleware.SessionConfig{}, false, wellknown.Associations{}, nil, "", nil, "",
 			)
 
 			group := registry.makeBaseGroup(router)
@@ -137,6 +242,7 @@ func TestRouteRegistry_RegisterBaseRoutes(t *testing.T) {
 				"auth",
 				"swagger",
 				"about",
+				"sdk",
 			},
 		},
 	}
@@ -150,7 +256,10 @@ func TestRouteRegistry_RegisterBaseRoutes(t *testing.T) {
 			group := router.Group("/api")
 
 			registry := NewRouteRegistry(
-				nil, nil, nil, nil, nil, nil, nil, nil,
+				nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+				nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+				nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+				RouteTimeouts{}, RequestSigning{}, middleware.SessionConfig{}, false, wellknown.Associations{}, nil, "", nil, "",
 			)
 
 			// This should not panic
@@ -193,7 +302,10 @@ func TestRouteRegistry_RegisterItemsRoutes(t *testing.T) {
 			group := router.Group("/api")
 
 			registry := NewRouteRegistry(
-				nil, nil, nil, nil, nil, nil, nil, nil,
+				nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+				nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+				nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+				RouteTimeouts{}, RequestSigning{}, middleware.SessionConfig{}, false, wellknown.Associations{}, nil, "", nil, "",
 			)
 
 			// This should not panic
@@ -218,6 +330,685 @@ func TestRouteRegistry_RegisterItemsRoutes(t *testing.T) {
 	}
 }
 
+func TestRouteRegistry_RegisterWebDAVRoutes(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	group := router.Group("/api")
+
+	registry := NewRouteRegistry(
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, RouteTimeouts{},
+		RequestSigning{}, middleware.SessionConfig{}, false, wellknown.Associations{}, nil, "", nil, "",
+	)
+
+	assert.NotPanics(t, func() {
+		registry.registerWebDAVRoutes(group)
+	})
+
+	// registered collects the registered method and path pairs.
+	registered := make(map[string]bool)
+	for _, route := range router.Routes() {
+		registered[route.Method+" "+route.Path] = true
+	}
+	for _, route := range []string{
+		"PROPFIND /api/dav", "PROPFIND /api/dav/*path", "PUT /api/dav/*path", "MOVE /api/dav/*path",
+	} {
+		assert.True(t, registered[route], "route %s should be registered", route)
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("PROPFIND", "/api/dav/", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Header().Get("WWW-Authenticate"), `Basic realm="AegisVaultKeeper"`)
+}
+
+func TestRouteRegistry_RegisterAccountRoutes(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	group := router.Group("/api")
+
+	registry := NewRouteRegistry(
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, RouteTimeouts{},
+		RequestSigning{}, middleware.SessionConfig{}, false, wellknown.Associations{}, nil, "", nil, "",
+	)
+
+	assert.NotPanics(t, func() {
+		registry.registerAccountRoutes(group)
+	})
+
+	paths := make([]string, 0)
+	for _, route := range router.Routes() {
+		paths = append(paths, route.Path)
+	}
+	assert.Contains(t, paths, "/api/account/health-report")
+	assert.Contains(t, paths, "/api/account/logins/pending")
+	assert.Contains(t, paths, "/api/account/logins/:id/approve")
+	assert.Contains(t, paths, "/api/account/notification-channels/:kind")
+	assert.Contains(t, paths, "/api/account/push-subscriptions")
+	assert.Contains(t, paths, "/api/account/push-subscriptions/:id")
+	assert.Contains(t, paths, "/api/account/access-policies")
+	assert.Contains(t, paths, "/api/account/access-policies/:id")
+}
+
+func TestRouteRegistry_RegisterFeatureRoutes(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	group := router.Group("/api")
+
+	registry := NewRouteRegistry(
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, RouteTimeouts{},
+		RequestSigning{}, middleware.SessionConfig{}, false, wellknown.Associations{}, nil, "", nil, "",
+	)
+
+	assert.NotPanics(t, func() {
+		registry.registerFeatureRoutes(group)
+	})
+
+	paths := make([]string, 0)
+	for _, route := range router.Routes() {
+		paths = append(paths, route.Path)
+	}
+	assert.Equal(t, []string{"/api/features"}, paths)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/features", nil))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestRouteRegistry_RegisterPlanRoutes(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	group := router.Group("/api")
+
+	registry := NewRouteRegistry(
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, RouteTimeouts{},
+		RequestSigning{}, middleware.SessionConfig{}, false, wellknown.Associations{}, nil, "", nil, "",
+	)
+
+	assert.NotPanics(t, func() {
+		registry.registerPlanRoutes(group)
+	})
+
+	paths := make([]string, 0)
+	for _, route := range router.Routes() {
+		paths = append(paths, route.Path)
+	}
+	assert.Equal(t, []string{"/api/plan"}, paths)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/plan", nil))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestRouteRegistry_RegisterBillingRoutes(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	group := router.Group("/api")
+
+	registry := NewRouteRegistry(
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, RouteTimeouts{},
+		RequestSigning{}, middleware.SessionConfig{}, false, wellknown.Associations{}, nil, "", nil, "",
+	)
+
+	assert.NotPanics(t, func() {
+		registry.registerBillingRoutes(group)
+	})
+
+	paths := make([]string, 0)
+	for _, route := range router.Routes() {
+		paths = append(paths, route.Path)
+	}
+	assert.Equal(t, []string{"/api/billing/stripe/webhook"}, paths)
+}
+
+func TestRouteRegistry_NoStoreOnSecretRoutes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		wantNoStore bool
+	}{
+		{name: "items", method: http.MethodGet, path: "/api/items/notes", wantNoStore: true},
+		{name: "reports", method: http.MethodGet, path: "/api/reports/credentials.csv", wantNoStore: true},
+		{name: "account", method: http.MethodGet, path: "/api/account/health-report", wantNoStore: true},
+		{name: "auth", method: http.MethodPost, path: "/api/auth/login", wantNoStore: true},
+		{name: "lease", method: http.MethodPost, path: "/api/lease", wantNoStore: true},
+		{name: "external secrets", method: http.MethodGet, path: "/api/external-secrets/v1/notes/1", wantNoStore: true},
+		{name: "kv", method: http.MethodGet, path: "/api/kv/prod/db/payments", wantNoStore: true},
+		{name: "admin", method: http.MethodGet, path: "/api/admin/access-rules", wantNoStore: true},
+		{name: "scim", method: http.MethodGet, path: "/api/scim/v2/Users", wantNoStore: true},
+		{name: "health", method: http.MethodGet, path: "/api/health", wantNoStore: false},
+	}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	NewRouteRegistry(
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, RouteTimeouts{},
+		RequestSigning{}, middleware.SessionConfig{}, false, wellknown.Associations{}, nil, "", nil, "",
+	).RegisterRoutes(router)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+
+			if tt.wantNoStore {
+				assert.Equal(t, "no-store", rec.Header().Get("Cache-Control"))
+			} else {
+				assert.Empty(t, rec.Header().Get("Cache-Control"))
+			}
+		})
+	}
+}
+
+func TestRouteRegistry_RegisterAdminRoutes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		token      string
+		header     string
+		signingKey string
+		wantStatus int
+	}{
+		{name: "admin api disabled", token: "", header: "", wantStatus: http.StatusNotFound},
+		{name: "missing token", token: "secret", header: "", wantStatus: http.StatusUnauthorized},
+		{name: "wrong token", token: "secret", header: "other", wantStatus: http.StatusUnauthorized},
+		{
+			name: "unsigned request", token: "secret", header: "secret", signingKey: "signing-secret",
+			wantStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			NewRouteRegistry(
+				nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+				nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+				nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+				RouteTimeouts{}, RequestSigning{AdminKey: tt.signingKey}, middleware.SessionConfig{}, false,
+				wellknown.Associations{}, nil, tt.token, nil, "",
+			).RegisterRoutes(router)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/admin/access-rules", nil)
+			if tt.header != "" {
+				req.Header.Set("X-Admin-Token", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+		})
+	}
+}
+
+func TestRouteRegistry_RegisterSCIMRoutes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		checker    middleware.LicenseChecker
+		name       string
+		token      string
+		header     string
+		wantStatus int
+	}{
+		{name: "scim api disabled", token: "", header: "", wantStatus: http.StatusNotFound},
+		{name: "missing token", token: "secret", header: "", wantStatus: http.StatusUnauthorized},
+		{name: "wrong token", token: "secret", header: "Bearer other", wantStatus: http.StatusUnauthorized},
+		{name: "valid token", token: "secret", header: "Bearer secret", wantStatus: http.StatusOK},
+		{
+			name:       "licensed",
+			checker:    licenseChecker(true),
+			token:      "secret",
+			header:     "Bearer secret",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "not licensed",
+			checker:    licenseChecker(false),
+			token:      "secret",
+			header:     "Bearer secret",
+			wantStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			NewRouteRegistry(
+				nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+				nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+				nil, nil, nil, nil, tt.checker, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+				nil, nil, RouteTimeouts{}, RequestSigning{}, middleware.SessionConfig{}, false, wellknown.Associations{}, nil,
+				"", nil, tt.token,
+			).RegisterRoutes(router)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/scim/v2/ServiceProviderConfig", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+		})
+	}
+}
+
+// unsignedAuditExportService implements auditexport.Service refusing exports for lack of a signing key.
+type unsignedAuditExportService struct{}
+
+func (unsignedAuditExportService) Export(
+	context.Context,
+	auditexportApp.ExportParams,
+	io.Writer,
+) (*auditexportApp.Digest, error) {
+	return nil, auditexportApp.ErrSigningKeyNotConfigured
+}
+
+func TestRouteRegistry_RegisterAuditExportRoutes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		checker    middleware.LicenseChecker
+		name       string
+		token      string
+		header     string
+		wantStatus int
+	}{
+		{name: "admin api disabled", token: "", header: "", wantStatus: http.StatusNotFound},
+		{name: "missing token", token: "secret", header: "", wantStatus: http.StatusUnauthorized},
+		{name: "licensed", checker: licenseChecker(true), token: "secret", header: "secret", wantStatus: http.StatusConflict},
+		{
+			name:       "not licensed",
+			checker:    licenseChecker(false),
+			token:      "secret",
+			header:     "secret",
+			wantStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			NewRouteRegistry(
+				nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+				nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+				nil, nil, nil, nil, tt.checker, nil, nil, nil, nil, nil, nil, nil, nil, unsignedAuditExportService{},
+				nil, nil, nil, nil, nil, nil, nil, RouteTimeouts{}, RequestSigning{}, middleware.SessionConfig{}, false,
+				wellknown.Associations{}, nil, tt.token, nil, "",
+			).RegisterRoutes(router)
+
+			req := httptest.NewRequest(http.MethodGet,
+				"/api/admin/audit/export?from=2026-01-01T00:00:00Z&to=2026-04-01T00:00:00Z", nil)
+			if tt.header != "" {
+				req.Header.Set("X-Admin-Token", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.Equal(t, "no-store", rec.Header().Get("Cache-Control"))
+		})
+	}
+}
+
+// queueingGate implements middleware.DualControlGate queueing every request and recording its initiator.
+type queueingGate struct {
+	initiators []string
+}
+
+func (g *queueingGate) Request(
+	_ context.Context,
+	params adminapproval.RequestParams,
+) (*adminapproval.Approval, error) {
+	g.initiators = append(g.initiators, params.Initiator)
+	return &adminapproval.Approval{Action: params.Action, Status: "pending"}, nil
+}
+
+func (g *queueingGate) Execute(context.Context, adminapproval.ExecuteParams) error {
+	return adminapproval.ErrApprovalNotApproved
+}
+
+func TestRouteRegistry_DualControl(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	gate := &queueingGate{}
+	NewRouteRegistry(
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, gate, nil, nil, nil, nil, nil, RouteTimeouts{},
+		RequestSigning{}, middleware.SessionConfig{}, false, wellknown.Associations{}, nil, "admin-token",
+		map[string]string{"alice": "alice-token"}, "",
+	).RegisterRoutes(router)
+
+	for _, path := range []string{
+		"/api/admin/legal-holds/6f1c2a7e-0d5b-4c55-9a3e-1f2b3c4d5e6f",
+		"/api/admin/recordings/6f1c2a7e-0d5b-4c55-9a3e-1f2b3c4d5e6f",
+		"/api/admin/retention/groups/6f1c2a7e-0d5b-4c55-9a3e-1f2b3c4d5e6f",
+	} {
+		req := httptest.NewRequest(http.MethodDelete, path, nil)
+		req.Header.Set("X-Admin-Token", "alice-token")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusAccepted, rec.Code, path)
+	}
+	assert.Equal(t, []string{"alice", "alice", "alice"}, gate.initiators)
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/admin/legal-holds/6f1c2a7e-0d5b-4c55-9a3e-1f2b3c4d5e6f", nil)
+	req.Header.Set("X-Admin-Token", "admin-token")
+	req.Header.Set("X-Admin-Approval", "6f1c2a7e-0d5b-4c55-9a3e-1f2b3c4d5e6f")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusConflict, rec.Code, "an unapproved request should not run")
+}
+
+func TestRouteRegistry_RegisterWebUIRoutes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		webUI      bool
+		wantStatus int
+	}{
+		{name: "web ui enabled", webUI: true, wantStatus: http.StatusOK},
+		{name: "web ui disabled", webUI: false, wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			NewRouteRegistry(
+				nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+				nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+				nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+				RouteTimeouts{}, RequestSigning{}, middleware.SessionConfig{}, tt.webUI, wellknown.Associations{}, nil,
+				"", nil, "",
+			).RegisterRoutes(router)
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ui/", nil))
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+		})
+	}
+}
+
+func TestRouteRegistry_AccessControl(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	checker := &denyingChecker{}
+	NewRouteRegistry(
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, checker, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		RouteTimeouts{}, RequestSigning{}, middleware.SessionConfig{}, false, wellknown.Associations{}, nil, "", nil, "",
+	).RegisterRoutes(router)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/health", nil))
+
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.True(t, checker.called)
+}
+
+func TestRouteRegistry_RequestSignature(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	NewRouteRegistry(
+		nil, staticTokenValidator{}, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		nil, RouteTimeouts{}, RequestSigning{Keys: enrolledResolver{}}, middleware.SessionConfig{}, false,
+		wellknown.Associations{}, nil, "", nil, "",
+	).RegisterRoutes(router)
+
+	for _, route := range []struct{ method, path string }{
+		{http.MethodPost, "/api/account/signing-keys"},
+		{http.MethodDelete, "/api/account/signing-keys/6f1c2a7e-0d5b-4c55-9a3e-1f2b3c4d5e6f"},
+		{http.MethodGet, "/api/items/credentials"},
+		{http.MethodPost, "/api/items/filedata/"},
+		{http.MethodGet, "/api/items/sync"},
+		{http.MethodGet, "/api/reports/items"},
+		{"PROPFIND", "/api/dav/"},
+	} {
+		req := httptest.NewRequest(route.method, route.path, nil)
+		req.Header.Set("Authorization", "Bearer token")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code, route.method+" "+route.path)
+		assert.Contains(t, rec.Body.String(), "This request must be signed", route.method+" "+route.path)
+	}
+}
+
+func TestRouteRegistry_BruteForce(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	guard := &failureCounter{}
+	NewRouteRegistry(
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, guard, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, RouteTimeouts{},
+		RequestSigning{}, middleware.SessionConfig{}, false, wellknown.Associations{}, nil, "admin-token", nil, "",
+	).RegisterRoutes(router)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/admin/maintenance", nil)
+	req.Header.Set("Authorization", "Bearer wrong")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Equal(t, []string{"/api/admin/maintenance"}, guard.routes, "a rejected admin token should be reported")
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/health", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Len(t, guard.routes, 1)
+}
+
+func TestRouteRegistry_HumanCheck(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		wantStatus int
+	}{
+		{
+			name: "register requires challenge", method: http.MethodPost, path: "/api/auth/register",
+			wantStatus: http.StatusPreconditionRequired,
+		},
+		{
+			name: "login requires challenge", method: http.MethodPost, path: "/api/auth/login",
+			wantStatus: http.StatusPreconditionRequired,
+		},
+		{name: "challenge served", method: http.MethodGet, path: "/api/auth/challenge", wantStatus: http.StatusOK},
+		{name: "held login not guarded", method: http.MethodPost, path: "/api/auth/login/verify", wantStatus: 0},
+	}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	gate := challengeGate{}
+	NewRouteRegistry(
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, gate, gate, nil, nil, nil, nil, nil, nil, nil, nil,
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, RouteTimeouts{},
+		RequestSigning{}, middleware.SessionConfig{}, false, wellknown.Associations{}, nil, "", nil, "",
+	).RegisterRoutes(router)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+			if tt.wantStatus == 0 {
+				assert.NotEqual(t, http.StatusPreconditionRequired, rec.Code)
+				return
+			}
+			assert.Equal(t, tt.wantStatus, rec.Code)
+		})
+	}
+}
+
+func TestRouteRegistry_Maintenance(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		wantStatus int
+	}{
+		{
+			name: "register rejected", method: http.MethodPost, path: "/api/auth/register",
+			wantStatus: http.StatusServiceUnavailable,
+		},
+		{
+			name: "item write rejected", method: http.MethodPost, path: "/api/items/notes",
+			wantStatus: http.StatusServiceUnavailable,
+		},
+		{name: "login exempt", method: http.MethodPost, path: "/api/auth/login", wantStatus: http.StatusBadRequest},
+		{name: "lease exempt", method: http.MethodPost, path: "/api/lease", wantStatus: http.StatusUnauthorized},
+		{name: "admin exempt", method: http.MethodPut, path: "/api/admin/maintenance", wantStatus: http.StatusNotFound},
+		{name: "read served", method: http.MethodGet, path: "/api/health", wantStatus: http.StatusOK},
+	}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	NewRouteRegistry(
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, maintenanceOn{}, nil, nil, nil, nil,
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		RouteTimeouts{}, RequestSigning{}, middleware.SessionConfig{}, false, wellknown.Associations{}, nil, "", nil, "",
+	).RegisterRoutes(router)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+		})
+	}
+}
+
+// staticTokenValidator accepts every access token as a token of the same user.
+type staticTokenValidator struct{}
+
+func (staticTokenValidator) ValidateToken(context.Context, string) (uuid.UUID, error) {
+	return uuid.MustParse("0b8f4f6e-3c2a-4d1b-9e7f-5a6b7c8d9e0f"), nil
+}
+
+// enrolledResolver reports every user as holding signing keys and knows none of them.
+type enrolledResolver struct{}
+
+func (enrolledResolver) Enrolled(context.Context, signingkey.EnrolledParams) (bool, error) {
+	return true, nil
+}
+
+func (enrolledResolver) Secret(context.Context, signingkey.SecretParams) ([]byte, error) {
+	return nil, signingkey.ErrSigningKeyNotFound
+}
+
+// maintenanceOn reports maintenance mode as always enabled.
+type maintenanceOn struct{}
+
+func (maintenanceOn) Enabled() bool {
+	return true
+}
+
+// licenseChecker answers every license check with its value.
+type licenseChecker bool
+
+func (l licenseChecker) Allows(context.Context, string) bool {
+	return bool(l)
+}
+
+// denyingChecker rejects every request and records that it was consulted.
+type denyingChecker struct {
+	called bool
+}
+
+func (d *denyingChecker) Check(context.Context, accesscontrol.CheckParams) error {
+	d.called = true
+	return accesscontrol.ErrAccessDenied
+}
+
+// failureCounter bans no client and records the routes of reported authentication failures.
+type failureCounter struct {
+	routes []string
+}
+
+func (f *failureCounter) Check(context.Context, netip.Addr) error {
+	return nil
+}
+
+func (f *failureCounter) RecordFailure(_ context.Context, params bruteforce.FailureParams) {
+	f.routes = append(f.routes, params.Route)
+}
+
+// challengeGate requires a challenge on every guarded request and issues proof-of-work challenges.
+type challengeGate struct{}
+
+func (challengeGate) Check(context.Context, botcheck.CheckParams) error {
+	return botcheck.ErrChallengeRequired
+}
+
+func (challengeGate) RecordLoginFailure(context.Context, netip.Addr) {}
+
+func (challengeGate) Challenge(context.Context, netip.Addr) (*botcheck.Challenge, error) {
+	return &botcheck.Challenge{Provider: botcheck.ProviderPoW, Puzzle: "puzzle", Difficulty: 1}, nil
+}
+
 func TestRouteRegistry_ServiceIntegration(t *testing.T) {
 	t.Parallel()
 
@@ -241,7 +1032,10 @@ func TestRouteRegistry_ServiceIntegration(t *testing.T) {
 			router := gin.New()
 
 			registry := NewRouteRegistry(
-				nil, nil, nil, nil, nil, nil, nil, nil,
+				nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+				nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+				nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+				RouteTimeouts{}, RequestSigning{}, middleware.SessionConfig{}, false, wellknown.Associations{}, nil, "", nil, "",
 			)
 
 			if tt.expectPanic {
@@ -278,3 +1072,67 @@ func TestBuildInfoOperator(t *testing.T) {
 		})
 	}
 }
+
+func TestRouteRegistry_Timeouts(t *testing.T) {
+	t.Parallel()
+
+	const expired = time.Nanosecond
+
+	tests := []struct {
+		name         string
+		path         string
+		timeouts     RouteTimeouts
+		wantTimedOut int64
+	}{
+		{name: "default group", path: "/api/health", timeouts: RouteTimeouts{Default: expired}, wantTimedOut: 1},
+		{name: "default group disabled", path: "/api/health", timeouts: RouteTimeouts{Items: expired}},
+		{name: "items group", path: "/api/items/notes", timeouts: RouteTimeouts{Items: expired}, wantTimedOut: 1},
+		{
+			name: "files group", path: "/api/items/filedata/",
+			timeouts: RouteTimeouts{Files: expired}, wantTimedOut: 1,
+		},
+		{name: "files excluded from items", path: "/api/items/filedata/", timeouts: RouteTimeouts{Items: expired}},
+		{name: "webdav group", path: "/api/dav/", timeouts: RouteTimeouts{Files: expired}, wantTimedOut: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			recorder := &timeoutCounter{}
+			NewRouteRegistry(
+				nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+				nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+				nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+				tt.timeouts, RequestSigning{}, middleware.SessionConfig{}, false, wellknown.Associations{}, recorder, "", nil, "",
+			).RegisterRoutes(router)
+
+			router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			assert.Equal(t, tt.wantTimedOut, recorder.get(middleware.MetricRequestsTimedOut))
+		})
+	}
+}
+
+// timeoutCounter counts request timeout metrics by name.
+type timeoutCounter struct {
+	counts map[string]int64
+	mu     sync.Mutex
+}
+
+func (c *timeoutCounter) Add(name string, delta int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.counts == nil {
+		c.counts = make(map[string]int64)
+	}
+	c.counts[name] += delta
+}
+
+func (c *timeoutCounter) get(name string) int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.counts[name]
+}
@@ -0,0 +1,5 @@
+// Package scim provides HTTP handlers for SCIM 2.0 provisioning in the AegisVaultKeeper server.
+//
+// This package implements the User and Group endpoints of RFC 7644, so identity providers
+// such as Okta or Azure AD can provision, deprovision and group-assign users automatically.
+package scim
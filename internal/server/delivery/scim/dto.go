@@ -0,0 +1,228 @@
+package scim
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/directory"
+)
+
+// SCIM schema URNs.
+const (
+	// SchemaUser identifies the core User resource schema.
+	SchemaUser = "urn:ietf:params:scim:schemas:core:2.0:User"
+	// SchemaGroup identifies the core Group resource schema.
+	SchemaGroup = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	// SchemaServiceProviderConfig identifies the service provider configuration schema.
+	SchemaServiceProviderConfig = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+	// SchemaListResponse identifies list responses.
+	SchemaListResponse = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	// SchemaPatchOp identifies PATCH requests.
+	SchemaPatchOp = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	// SchemaError identifies error responses.
+	SchemaError = "urn:ietf:params:scim:api:messages:2.0:Error"
+)
+
+// Meta contains the resource metadata.
+type Meta struct {
+	// Created indicates when the resource was created, when known.
+	Created *time.Time `json:"created,omitempty"`
+	// LastModified indicates when the resource was last changed, when known.
+	LastModified *time.Time `json:"lastModified,omitempty"`
+	// ResourceType names the type of the resource.
+	ResourceType string `json:"resourceType"`
+}
+
+// User represents a SCIM User resource.
+type User struct {
+	// ID uniquely identifies the user.
+	ID string `json:"id"`
+	// ExternalID contains the identifier of the user in the enterprise directory.
+	ExternalID string `json:"externalId,omitempty"`
+	// UserName contains the login of the user.
+	UserName string `json:"userName"`
+	// Meta contains the resource metadata.
+	Meta Meta `json:"meta"`
+	// Schemas lists the schemas of the resource.
+	Schemas []string `json:"schemas"`
+	// Active reports whether the user may log in.
+	Active bool `json:"active"`
+}
+
+// NewUserFromApp converts an application directory user to a SCIM User resource.
+func NewUserFromApp(u *directory.User) *User {
+	if u == nil {
+		return nil
+	}
+	return &User{
+		Schemas:    []string{SchemaUser},
+		ID:         u.ID.String(),
+		ExternalID: u.ExternalID,
+		UserName:   u.UserName,
+		Active:     u.Active,
+		Meta:       Meta{ResourceType: "User"},
+	}
+}
+
+// UserRequest represents a SCIM User resource sent to create or replace a user.
+// Attributes not supported by AegisVaultKeeper, such as names and e-mails, are ignored.
+type UserRequest struct {
+	// Active reports whether the user may log in; absent means active.
+	Active *bool `json:"active"`
+	// UserName contains the login of the user.
+	UserName string `json:"userName"`
+	// ExternalID contains the identifier of the user in the enterprise directory.
+	ExternalID string `json:"externalId"`
+	// Password contains the password of the user; absent keeps or generates one.
+	Password string `json:"password"`
+}
+
+// MemberRef references a member of a group.
+type MemberRef struct {
+	// Value contains the ID of the member user.
+	Value string `json:"value"`
+}
+
+// Group represents a SCIM Group resource.
+type Group struct {
+	// ID uniquely identifies the group.
+	ID string `json:"id"`
+	// ExternalID contains the identifier of the group in the enterprise directory.
+	ExternalID string `json:"externalId,omitempty"`
+	// DisplayName contains the unique human-readable name of the group.
+	DisplayName string `json:"displayName"`
+	// Members lists the member users.
+	Members []MemberRef `json:"members"`
+	// Meta contains the resource metadata.
+	Meta Meta `json:"meta"`
+	// Schemas lists the schemas of the resource.
+	Schemas []string `json:"schemas"`
+}
+
+// NewGroupFromApp converts an application directory group to a SCIM Group resource.
+func NewGroupFromApp(g *directory.Group) *Group {
+	if g == nil {
+		return nil
+	}
+	members := make([]MemberRef, 0, len(g.Members))
+	for _, id := range g.Members {
+		members = append(members, MemberRef{Value: id.String()})
+	}
+	created, modified := g.CreatedAt, g.UpdatedAt
+	return &Group{
+		Schemas:     []string{SchemaGroup},
+		ID:          g.ID.String(),
+		ExternalID:  g.ExternalID,
+		DisplayName: g.DisplayName,
+		Members:     members,
+		Meta:        Meta{ResourceType: "Group", Created: &created, LastModified: &modified},
+	}
+}
+
+// GroupRequest represents a SCIM Group resource sent to create or replace a group.
+type GroupRequest struct {
+	// DisplayName contains the unique human-readable name of the group.
+	DisplayName string `json:"displayName"`
+	// ExternalID contains the identifier of the group in the enterprise directory.
+	ExternalID string `json:"externalId"`
+	// Members lists the member users.
+	Members []MemberRef `json:"members"`
+}
+
+// ListResponse represents a page of SCIM resources.
+type ListResponse struct {
+	// Resources lists the resources of the page.
+	Resources any `json:"Resources"`
+	// Schemas lists the schemas of the response.
+	Schemas []string `json:"schemas"`
+	// TotalResults counts all resources matching the query.
+	TotalResults int `json:"totalResults"`
+	// StartIndex contains the 1-based index of the first resource of the page.
+	StartIndex int `json:"startIndex"`
+	// ItemsPerPage counts the resources of the page.
+	ItemsPerPage int `json:"itemsPerPage"`
+}
+
+// PatchOperation represents a single operation of a SCIM PATCH request.
+type PatchOperation struct {
+	// Op names the operation: add, replace or remove, in any letter case.
+	Op string `json:"op"`
+	// Path selects the attribute the operation applies to; empty applies the value to the resource.
+	Path string `json:"path"`
+	// Value contains the operation value.
+	Value json.RawMessage `json:"value"`
+}
+
+// PatchRequest represents a SCIM PATCH request.
+type PatchRequest struct {
+	// Schemas lists the schemas of the request.
+	Schemas []string `json:"schemas"`
+	// Operations lists the operations, applied in order.
+	Operations []PatchOperation `json:"Operations"`
+}
+
+// Error represents a SCIM error response.
+type Error struct {
+	// Status contains the HTTP status code as a string.
+	Status string `json:"status"`
+	// SCIMType names the SCIM error type, when one applies.
+	SCIMType string `json:"scimType,omitempty"`
+	// Detail describes the error.
+	Detail string `json:"detail"`
+	// Schemas lists the schemas of the response.
+	Schemas []string `json:"schemas"`
+}
+
+// Supported describes whether an optional SCIM feature is supported.
+type Supported struct {
+	// Supported reports whether the feature is supported.
+	Supported bool `json:"supported"`
+}
+
+// FilterSupport describes the supported filtering.
+type FilterSupport struct {
+	// Supported reports whether filtering is supported.
+	Supported bool `json:"supported"`
+	// MaxResults limits the number of resources returned per page.
+	MaxResults int `json:"maxResults"`
+}
+
+// BulkSupport describes the supported bulk operations.
+type BulkSupport struct {
+	// Supported reports whether bulk operations are supported.
+	Supported bool `json:"supported"`
+	// MaxOperations limits the operations of a bulk request.
+	MaxOperations int `json:"maxOperations"`
+	// MaxPayloadSize limits the size of a bulk request in bytes.
+	MaxPayloadSize int `json:"maxPayloadSize"`
+}
+
+// AuthenticationScheme describes a supported authentication scheme.
+type AuthenticationScheme struct {
+	// Type names the scheme type.
+	Type string `json:"type"`
+	// Name contains the human-readable scheme name.
+	Name string `json:"name"`
+	// Description describes the scheme.
+	Description string `json:"description"`
+}
+
+// ServiceProviderConfig describes the SCIM features supported by AegisVaultKeeper.
+type ServiceProviderConfig struct {
+	// Schemas lists the schemas of the resource.
+	Schemas []string `json:"schemas"`
+	// AuthenticationSchemes lists the supported authentication schemes.
+	AuthenticationSchemes []AuthenticationScheme `json:"authenticationSchemes"`
+	// Bulk describes bulk operation support.
+	Bulk BulkSupport `json:"bulk"`
+	// Filter describes filtering support.
+	Filter FilterSupport `json:"filter"`
+	// Patch describes PATCH support.
+	Patch Supported `json:"patch"`
+	// ChangePassword describes password change support.
+	ChangePassword Supported `json:"changePassword"`
+	// Sort describes sorting support.
+	Sort Supported `json:"sort"`
+	// ETag describes entity tag support.
+	ETag Supported `json:"etag"`
+}
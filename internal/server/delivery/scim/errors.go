@@ -0,0 +1,162 @@
+package scim
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	app "github.com/gdyunin/aegis-vault-keeper/internal/server/application/directory"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/errutil"
+	"github.com/gin-gonic/gin"
+)
+
+// SCIM error types (RFC 7644, section 3.12).
+const (
+	// scimTypeInvalidFilter is returned for unsupported or malformed filters.
+	scimTypeInvalidFilter = "invalidFilter"
+	// scimTypeInvalidSyntax is returned for malformed requests.
+	scimTypeInvalidSyntax = "invalidSyntax"
+	// scimTypeInvalidValue is returned for attribute values that are not accepted.
+	scimTypeInvalidValue = "invalidValue"
+	// scimTypeInvalidPath is returned for PATCH paths that cannot be applied.
+	scimTypeInvalidPath = "invalidPath"
+	// scimTypeUniqueness is returned when a unique attribute is already taken.
+	scimTypeUniqueness = "uniqueness"
+)
+
+// resourceNotFoundDetail is returned for unknown resources and malformed resource IDs.
+const resourceNotFoundDetail = "Resource not found"
+
+// DirectoryErrRegistry defines error handling policies for SCIM provisioning operations.
+var DirectoryErrRegistry = errutil.Registry{
+	{
+		ErrorIn: app.ErrDirectoryTechError,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusInternalServerError,
+			PublicMsg:  http.StatusText(http.StatusInternalServerError),
+			LogIt:      true,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassTech,
+		},
+	},
+	{
+		ErrorIn: app.ErrDirectoryUserNotFound,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusNotFound,
+			PublicMsg:  "User not found",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassGeneric,
+		},
+	},
+	{
+		ErrorIn: app.ErrDirectoryGroupNotFound,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusNotFound,
+			PublicMsg:  "Group not found",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassGeneric,
+		},
+	},
+	{
+		ErrorIn: app.ErrDirectoryUserAlreadyExists,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusConflict,
+			PublicMsg:  "A user with this userName already exists",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassGeneric,
+		},
+	},
+	{
+		ErrorIn: app.ErrDirectoryGroupAlreadyExists,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusConflict,
+			PublicMsg:  "A group with this displayName already exists",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassGeneric,
+		},
+	},
+	{
+		ErrorIn: app.ErrDirectoryIncorrectUserName,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusBadRequest,
+			PublicMsg:  "userName must be between 5 and 50 characters",
+			LogIt:      false,
+			AllowMerge: true,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+	{
+		ErrorIn: app.ErrDirectoryIncorrectPassword,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusBadRequest,
+			PublicMsg:  "password must be between 8 and 64 characters",
+			LogIt:      false,
+			AllowMerge: true,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+	{
+		ErrorIn: app.ErrDirectoryIncorrectDisplayName,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusBadRequest,
+			PublicMsg:  "displayName must be between 1 and 256 characters",
+			LogIt:      false,
+			AllowMerge: true,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+	{
+		ErrorIn: app.ErrDirectoryMemberNotFound,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusBadRequest,
+			PublicMsg:  "Group members must reference existing users",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+	{
+		ErrorIn: app.ErrDirectoryAppError,
+		HandlePolicy: errutil.Policy{
+			StatusCode: http.StatusBadRequest,
+			PublicMsg:  "Invalid resource attributes",
+			LogIt:      false,
+			AllowMerge: false,
+			ErrorClass: errutil.ErrorClassValidation,
+		},
+	},
+}
+
+// handleError processes directory errors using the registry and responds with a SCIM error.
+func handleError(err error, c *gin.Context) {
+	code, msgs := errutil.HandleWithRegistry(DirectoryErrRegistry, err, c)
+
+	var scimType string
+	switch code {
+	case http.StatusConflict:
+		scimType = scimTypeUniqueness
+	case http.StatusBadRequest:
+		scimType = scimTypeInvalidValue
+	}
+	respondError(c, code, scimType, strings.Join(msgs, "; "))
+}
+
+// respondError responds with a SCIM error of the status, error type and detail.
+func respondError(c *gin.Context, code int, scimType, detail string) {
+	respond(c, code, Error{
+		Schemas:  []string{SchemaError},
+		Status:   strconv.Itoa(code),
+		SCIMType: scimType,
+		Detail:   detail,
+	})
+}
+
+// respond writes the body as JSON with the SCIM media type.
+func respond(c *gin.Context, code int, body any) {
+	c.Header("Content-Type", "application/scim+json; charset=utf-8")
+	c.JSON(code, body)
+}
@@ -0,0 +1,568 @@
+package scim
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/directory"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/util"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Service defines the directory application service interface.
+type Service interface {
+	// CreateUser provisions a new user.
+	CreateUser(context.Context, directory.CreateUserParams) (*directory.User, error)
+	// GetUser retrieves a provisioned user.
+	GetUser(context.Context, directory.GetUserParams) (*directory.User, error)
+	// ListUsers retrieves a page of provisioned users.
+	ListUsers(context.Context, directory.ListUsersParams) (*directory.UserPage, error)
+	// UpdateUser changes the attributes of a user.
+	UpdateUser(context.Context, directory.UpdateUserParams) (*directory.User, error)
+	// DeleteUser deprovisions a user.
+	DeleteUser(context.Context, directory.DeleteUserParams) error
+	// CreateGroup creates a new group.
+	CreateGroup(context.Context, directory.CreateGroupParams) (*directory.Group, error)
+	// GetGroup retrieves a group with its members.
+	GetGroup(context.Context, directory.GetGroupParams) (*directory.Group, error)
+	// ListGroups retrieves a page of groups.
+	ListGroups(context.Context, directory.ListGroupsParams) (*directory.GroupPage, error)
+	// UpdateGroup changes the attributes and members of a group.
+	UpdateGroup(context.Context, directory.UpdateGroupParams) (*directory.Group, error)
+	// DeleteGroup deletes a group.
+	DeleteGroup(context.Context, directory.DeleteGroupParams) error
+}
+
+// Handler handles HTTP requests for SCIM provisioning endpoints.
+type Handler struct {
+	// s is the directory service used to process operations.
+	s Service
+}
+
+// NewHandler creates a new SCIM handler with the provided service.
+func NewHandler(s Service) *Handler {
+	return &Handler{s: s}
+}
+
+// ServiceProviderConfig describes the supported SCIM features.
+// @Summary      SCIM service provider configuration
+// @Description  Describes the SCIM features supported by AegisVaultKeeper
+// @Tags         SCIM
+// @Produce      json
+// @Security     SCIMToken
+// @Success      200 {object} ServiceProviderConfig "Service provider configuration"
+// @Failure      401 {object} Error "Unauthorized - invalid or missing SCIM token"
+// @Router       /scim/v2/ServiceProviderConfig [get]
+// .
+func (h *Handler) ServiceProviderConfig(c *gin.Context) {
+	respond(c, http.StatusOK, ServiceProviderConfig{
+		Schemas: []string{SchemaServiceProviderConfig},
+		AuthenticationSchemes: []AuthenticationScheme{{
+			Type:        "oauthbearertoken",
+			Name:        "OAuth Bearer Token",
+			Description: "Authentication with the SCIM provisioning token",
+		}},
+		Patch:  Supported{Supported: true},
+		Filter: FilterSupport{Supported: true, MaxResults: maxPageSize},
+	})
+}
+
+// ListUsers retrieves a page of provisioned users.
+// @Summary      List users
+// @Description  Retrieves a page of provisioned users. Filters of the form 'userName eq "value"' and
+// @Description  'externalId eq "value"' are supported.
+// .
+// @Tags         SCIM
+// @Produce      json
+// @Security     SCIMToken
+// @Param        filter query string false "Filter expression"
+// @Param        startIndex query int false "1-based index of the first user"
+// @Param        count query int false "Maximum number of users returned"
+// @Success      200 {object} ListResponse "Users retrieved successfully"
+// @Failure      400 {object} Error "Bad request - unsupported filter"
+// @Failure      401 {object} Error "Unauthorized - invalid or missing SCIM token"
+// @Failure      500 {object} Error "Internal server error"
+// @Router       /scim/v2/Users [get]
+// .
+func (h *Handler) ListUsers(c *gin.Context) {
+	var query listQuery
+	if err := util.NewCtxExtractor(c).BindQuery(&query); err != nil {
+		respondError(c, http.StatusBadRequest, scimTypeInvalidSyntax, err.Error())
+		return
+	}
+	startIndex, offset, limit := query.page()
+	params := directory.ListUsersParams{Offset: offset, Limit: limit}
+	if err := filterUsers(query.Filter, &params); err != nil {
+		respondRequestError(c, err)
+		return
+	}
+
+	page, err := h.s.ListUsers(c, params)
+	if err != nil {
+		handleError(err, c)
+		return
+	}
+
+	users := make([]*User, 0, len(page.Users))
+	for _, u := range page.Users {
+		users = append(users, NewUserFromApp(u))
+	}
+	respond(c, http.StatusOK, ListResponse{
+		Schemas:      []string{SchemaListResponse},
+		TotalResults: page.Total,
+		StartIndex:   startIndex,
+		ItemsPerPage: len(users),
+		Resources:    users,
+	})
+}
+
+// GetUser retrieves a provisioned user.
+// @Summary      Get user
+// @Description  Retrieves a provisioned user by ID
+// @Tags         SCIM
+// @Produce      json
+// @Security     SCIMToken
+// @Param        id path string true "User ID" format(uuid)
+// @Success      200 {object} User "User retrieved successfully"
+// @Failure      401 {object} Error "Unauthorized - invalid or missing SCIM token"
+// @Failure      404 {object} Error "Not found - user not found"
+// @Failure      500 {object} Error "Internal server error"
+// @Router       /scim/v2/Users/{id} [get]
+// .
+func (h *Handler) GetUser(c *gin.Context) {
+	id, ok := resourceID(c)
+	if !ok {
+		return
+	}
+
+	u, err := h.s.GetUser(c, directory.GetUserParams{ID: id})
+	if err != nil {
+		handleError(err, c)
+		return
+	}
+
+	respond(c, http.StatusOK, NewUserFromApp(u))
+}
+
+// CreateUser provisions a new user.
+// @Summary      Create user
+// @Description  Provisions a new user with an empty vault. Without a password a random one is set,
+// @Description  so the user can only sign in through the identity provider.
+// .
+// @Tags         SCIM
+// @Accept       json
+// @Produce      json
+// @Security     SCIMToken
+// @Param        request body UserRequest true "User resource"
+// @Success      201 {object} User "User created successfully"
+// @Failure      400 {object} Error "Bad request - invalid attributes"
+// @Failure      401 {object} Error "Unauthorized - invalid or missing SCIM token"
+// @Failure      409 {object} Error "Conflict - userName already taken"
+// @Failure      500 {object} Error "Internal server error"
+// @Router       /scim/v2/Users [post]
+// .
+func (h *Handler) CreateUser(c *gin.Context) {
+	// req holds the deserialized JSON request payload for the user creation.
+	var req UserRequest
+	if err := util.NewCtxExtractor(c).BindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, scimTypeInvalidSyntax, err.Error())
+		return
+	}
+
+	u, err := h.s.CreateUser(c, directory.CreateUserParams{
+		UserName:   req.UserName,
+		Password:   req.Password,
+		ExternalID: req.ExternalID,
+		Active:     req.Active == nil || *req.Active,
+	})
+	if err != nil {
+		handleError(err, c)
+		return
+	}
+
+	respond(c, http.StatusCreated, NewUserFromApp(u))
+}
+
+// ReplaceUser replaces the attributes of a user.
+// @Summary      Replace user
+// @Description  Replaces the attributes of a user. An absent active attribute activates the user and
+// @Description  an absent password keeps the current one.
+// .
+// @Tags         SCIM
+// @Accept       json
+// @Produce      json
+// @Security     SCIMToken
+// @Param        id path string true "User ID" format(uuid)
+// @Param        request body UserRequest true "User resource"
+// @Success      200 {object} User "User replaced successfully"
+// @Failure      400 {object} Error "Bad request - invalid attributes"
+// @Failure      401 {object} Error "Unauthorized - invalid or missing SCIM token"
+// @Failure      404 {object} Error "Not found - user not found"
+// @Failure      409 {object} Error "Conflict - userName already taken"
+// @Failure      500 {object} Error "Internal server error"
+// @Router       /scim/v2/Users/{id} [put]
+// .
+func (h *Handler) ReplaceUser(c *gin.Context) {
+	id, ok := resourceID(c)
+	if !ok {
+		return
+	}
+	// req holds the deserialized JSON request payload for the user replacement.
+	var req UserRequest
+	if err := util.NewCtxExtractor(c).BindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, scimTypeInvalidSyntax, err.Error())
+		return
+	}
+
+	active := req.Active == nil || *req.Active
+	params := directory.UpdateUserParams{
+		ID:         id,
+		UserName:   &req.UserName,
+		ExternalID: &req.ExternalID,
+		Active:     &active,
+	}
+	if req.Password != "" {
+		params.Password = &req.Password
+	}
+	u, err := h.s.UpdateUser(c, params)
+	if err != nil {
+		handleError(err, c)
+		return
+	}
+
+	respond(c, http.StatusOK, NewUserFromApp(u))
+}
+
+// PatchUser changes attributes of a user.
+// @Summary      Patch user
+// @Description  Applies add, replace and remove operations to the userName, externalId, active and
+// @Description  password attributes. Setting active to false blocks new logins; issued tokens stay
+// @Description  valid until they expire.
+// .
+// @Tags         SCIM
+// @Accept       json
+// @Produce      json
+// @Security     SCIMToken
+// @Param        id path string true "User ID" format(uuid)
+// @Param        request body PatchRequest true "PATCH operations"
+// @Success      200 {object} User "User changed successfully"
+// @Failure      400 {object} Error "Bad request - invalid operations"
+// @Failure      401 {object} Error "Unauthorized - invalid or missing SCIM token"
+// @Failure      404 {object} Error "Not found - user not found"
+// @Failure      409 {object} Error "Conflict - userName already taken"
+// @Failure      500 {object} Error "Internal server error"
+// @Router       /scim/v2/Users/{id} [patch]
+// .
+func (h *Handler) PatchUser(c *gin.Context) {
+	id, ok := resourceID(c)
+	if !ok {
+		return
+	}
+	// req holds the deserialized JSON request payload for the user changes.
+	var req PatchRequest
+	if err := util.NewCtxExtractor(c).BindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, scimTypeInvalidSyntax, err.Error())
+		return
+	}
+	params, err := newUserPatch(id, req.Operations)
+	if err != nil {
+		respondRequestError(c, err)
+		return
+	}
+
+	u, err := h.s.UpdateUser(c, params)
+	if err != nil {
+		handleError(err, c)
+		return
+	}
+
+	respond(c, http.StatusOK, NewUserFromApp(u))
+}
+
+// DeleteUser deprovisions a user.
+// @Summary      Delete user
+// @Description  Deprovisions a user: the user can no longer log in and is hidden from the SCIM API.
+// @Description  The vault of the user is retained.
+// .
+// @Tags         SCIM
+// @Security     SCIMToken
+// @Param        id path string true "User ID" format(uuid)
+// @Success      204 "User deprovisioned successfully"
+// @Failure      401 {object} Error "Unauthorized - invalid or missing SCIM token"
+// @Failure      404 {object} Error "Not found - user not found"
+// @Failure      500 {object} Error "Internal server error"
+// @Router       /scim/v2/Users/{id} [delete]
+// .
+func (h *Handler) DeleteUser(c *gin.Context) {
+	id, ok := resourceID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.s.DeleteUser(c, directory.DeleteUserParams{ID: id}); err != nil {
+		handleError(err, c)
+		return
+	}
+
+	c.Data(http.StatusNoContent, "", nil)
+}
+
+// ListGroups retrieves a page of groups.
+// @Summary      List groups
+// @Description  Retrieves a page of groups with their members. Filters of the form 'displayName eq "value"'
+// @Description  and 'externalId eq "value"' are supported.
+// .
+// @Tags         SCIM
+// @Produce      json
+// @Security     SCIMToken
+// @Param        filter query string false "Filter expression"
+// @Param        startIndex query int false "1-based index of the first group"
+// @Param        count query int false "Maximum number of groups returned"
+// @Success      200 {object} ListResponse "Groups retrieved successfully"
+// @Failure      400 {object} Error "Bad request - unsupported filter"
+// @Failure      401 {object} Error "Unauthorized - invalid or missing SCIM token"
+// @Failure      500 {object} Error "Internal server error"
+// @Router       /scim/v2/Groups [get]
+// .
+func (h *Handler) ListGroups(c *gin.Context) {
+	var query listQuery
+	if err := util.NewCtxExtractor(c).BindQuery(&query); err != nil {
+		respondError(c, http.StatusBadRequest, scimTypeInvalidSyntax, err.Error())
+		return
+	}
+	startIndex, offset, limit := query.page()
+	params := directory.ListGroupsParams{Offset: offset, Limit: limit}
+	if err := filterGroups(query.Filter, &params); err != nil {
+		respondRequestError(c, err)
+		return
+	}
+
+	page, err := h.s.ListGroups(c, params)
+	if err != nil {
+		handleError(err, c)
+		return
+	}
+
+	groups := make([]*Group, 0, len(page.Groups))
+	for _, g := range page.Groups {
+		groups = append(groups, NewGroupFromApp(g))
+	}
+	respond(c, http.StatusOK, ListResponse{
+		Schemas:      []string{SchemaListResponse},
+		TotalResults: page.Total,
+		StartIndex:   startIndex,
+		ItemsPerPage: len(groups),
+		Resources:    groups,
+	})
+}
+
+// GetGroup retrieves a group.
+// @Summary      Get group
+// @Description  Retrieves a group with its members by ID
+// @Tags         SCIM
+// @Produce      json
+// @Security     SCIMToken
+// @Param        id path string true "Group ID" format(uuid)
+// @Success      200 {object} Group "Group retrieved successfully"
+// @Failure      401 {object} Error "Unauthorized - invalid or missing SCIM token"
+// @Failure      404 {object} Error "Not found - group not found"
+// @Failure      500 {object} Error "Internal server error"
+// @Router       /scim/v2/Groups/{id} [get]
+// .
+func (h *Handler) GetGroup(c *gin.Context) {
+	id, ok := resourceID(c)
+	if !ok {
+		return
+	}
+
+	g, err := h.s.GetGroup(c, directory.GetGroupParams{ID: id})
+	if err != nil {
+		handleError(err, c)
+		return
+	}
+
+	respond(c, http.StatusOK, NewGroupFromApp(g))
+}
+
+// CreateGroup creates a new group.
+// @Summary      Create group
+// @Description  Creates a new group of provisioned users
+// @Tags         SCIM
+// @Accept       json
+// @Produce      json
+// @Security     SCIMToken
+// @Param        request body GroupRequest true "Group resource"
+// @Success      201 {object} Group "Group created successfully"
+// @Failure      400 {object} Error "Bad request - invalid attributes or unknown members"
+// @Failure      401 {object} Error "Unauthorized - invalid or missing SCIM token"
+// @Failure      409 {object} Error "Conflict - displayName already taken"
+// @Failure      500 {object} Error "Internal server error"
+// @Router       /scim/v2/Groups [post]
+// .
+func (h *Handler) CreateGroup(c *gin.Context) {
+	// req holds the deserialized JSON request payload for the group creation.
+	var req GroupRequest
+	if err := util.NewCtxExtractor(c).BindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, scimTypeInvalidSyntax, err.Error())
+		return
+	}
+	members, err := parseMembers(req.Members)
+	if err != nil {
+		respondRequestError(c, err)
+		return
+	}
+
+	g, err := h.s.CreateGroup(c, directory.CreateGroupParams{
+		DisplayName: req.DisplayName,
+		ExternalID:  req.ExternalID,
+		Members:     members,
+	})
+	if err != nil {
+		handleError(err, c)
+		return
+	}
+
+	respond(c, http.StatusCreated, NewGroupFromApp(g))
+}
+
+// ReplaceGroup replaces the attributes and members of a group.
+// @Summary      Replace group
+// @Description  Replaces the attributes and members of a group
+// @Tags         SCIM
+// @Accept       json
+// @Produce      json
+// @Security     SCIMToken
+// @Param        id path string true "Group ID" format(uuid)
+// @Param        request body GroupRequest true "Group resource"
+// @Success      200 {object} Group "Group replaced successfully"
+// @Failure      400 {object} Error "Bad request - invalid attributes or unknown members"
+// @Failure      401 {object} Error "Unauthorized - invalid or missing SCIM token"
+// @Failure      404 {object} Error "Not found - group not found"
+// @Failure      409 {object} Error "Conflict - displayName already taken"
+// @Failure      500 {object} Error "Internal server error"
+// @Router       /scim/v2/Groups/{id} [put]
+// .
+func (h *Handler) ReplaceGroup(c *gin.Context) {
+	id, ok := resourceID(c)
+	if !ok {
+		return
+	}
+	// req holds the deserialized JSON request payload for the group replacement.
+	var req GroupRequest
+	if err := util.NewCtxExtractor(c).BindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, scimTypeInvalidSyntax, err.Error())
+		return
+	}
+	members, err := parseMembers(req.Members)
+	if err != nil {
+		respondRequestError(c, err)
+		return
+	}
+
+	g, err := h.s.UpdateGroup(c, directory.UpdateGroupParams{
+		ID:          id,
+		DisplayName: &req.DisplayName,
+		ExternalID:  &req.ExternalID,
+		MemberOps:   []directory.MemberOp{{Kind: directory.MemberOpReplace, UserIDs: members}},
+	})
+	if err != nil {
+		handleError(err, c)
+		return
+	}
+
+	respond(c, http.StatusOK, NewGroupFromApp(g))
+}
+
+// PatchGroup changes attributes and members of a group.
+// @Summary      Patch group
+// @Description  Applies add, replace and remove operations to the displayName, externalId and members
+// @Description  attributes. Members are removed by value list or by a 'members[value eq "id"]' path.
+// .
+// @Tags         SCIM
+// @Accept       json
+// @Produce      json
+// @Security     SCIMToken
+// @Param        id path string true "Group ID" format(uuid)
+// @Param        request body PatchRequest true "PATCH operations"
+// @Success      200 {object} Group "Group changed successfully"
+// @Failure      400 {object} Error "Bad request - invalid operations or unknown members"
+// @Failure      401 {object} Error "Unauthorized - invalid or missing SCIM token"
+// @Failure      404 {object} Error "Not found - group not found"
+// @Failure      409 {object} Error "Conflict - displayName already taken"
+// @Failure      500 {object} Error "Internal server error"
+// @Router       /scim/v2/Groups/{id} [patch]
+// .
+func (h *Handler) PatchGroup(c *gin.Context) {
+	id, ok := resourceID(c)
+	if !ok {
+		return
+	}
+	// req holds the deserialized JSON request payload for the group changes.
+	var req PatchRequest
+	if err := util.NewCtxExtractor(c).BindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, scimTypeInvalidSyntax, err.Error())
+		return
+	}
+	params, err := newGroupPatch(id, req.Operations)
+	if err != nil {
+		respondRequestError(c, err)
+		return
+	}
+
+	g, err := h.s.UpdateGroup(c, params)
+	if err != nil {
+		handleError(err, c)
+		return
+	}
+
+	respond(c, http.StatusOK, NewGroupFromApp(g))
+}
+
+// DeleteGroup deletes a group.
+// @Summary      Delete group
+// @Description  Deletes a group; its members are kept
+// @Tags         SCIM
+// @Security     SCIMToken
+// @Param        id path string true "Group ID" format(uuid)
+// @Success      204 "Group deleted successfully"
+// @Failure      401 {object} Error "Unauthorized - invalid or missing SCIM token"
+// @Failure      404 {object} Error "Not found - group not found"
+// @Failure      500 {object} Error "Internal server error"
+// @Router       /scim/v2/Groups/{id} [delete]
+// .
+func (h *Handler) DeleteGroup(c *gin.Context) {
+	id, ok := resourceID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.s.DeleteGroup(c, directory.DeleteGroupParams{ID: id}); err != nil {
+		handleError(err, c)
+		return
+	}
+
+	c.Data(http.StatusNoContent, "", nil)
+}
+
+// resourceID parses the resource ID of the path. Malformed IDs cannot name a resource,
+// so they are answered with 404 Not Found.
+func resourceID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusNotFound, "", resourceNotFoundDetail)
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// respondRequestError responds to a malformed request with 400 Bad Request.
+func respondRequestError(c *gin.Context, err error) {
+	var reqErr *requestError
+	if errors.As(err, &reqErr) {
+		respondError(c, http.StatusBadRequest, reqErr.scimType, reqErr.detail)
+		return
+	}
+	respondError(c, http.StatusBadRequest, scimTypeInvalidSyntax, err.Error())
+}
@@ -0,0 +1,795 @@
+package scim
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/directory"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockDirectoryService implements Service for testing.
+type mockDirectoryService struct {
+	createUserFunc  func(ctx context.Context, params directory.CreateUserParams) (*directory.User, error)
+	getUserFunc     func(ctx context.Context, params directory.GetUserParams) (*directory.User, error)
+	listUsersFunc   func(ctx context.Context, params directory.ListUsersParams) (*directory.UserPage, error)
+	updateUserFunc  func(ctx context.Context, params directory.UpdateUserParams) (*directory.User, error)
+	deleteUserFunc  func(ctx context.Context, params directory.DeleteUserParams) error
+	createGroupFunc func(ctx context.Context, params directory.CreateGroupParams) (*directory.Group, error)
+	getGroupFunc    func(ctx context.Context, params directory.GetGroupParams) (*directory.Group, error)
+	listGroupsFunc  func(ctx context.Context, params directory.ListGroupsParams) (*directory.GroupPage, error)
+	updateGroupFunc func(ctx context.Context, params directory.UpdateGroupParams) (*directory.Group, error)
+	deleteGroupFunc func(ctx context.Context, params directory.DeleteGroupParams) error
+}
+
+func (m *mockDirectoryService) CreateUser(
+	ctx context.Context,
+	params directory.CreateUserParams,
+) (*directory.User, error) {
+	if m.createUserFunc != nil {
+		return m.createUserFunc(ctx, params)
+	}
+	return nil, nil
+}
+
+func (m *mockDirectoryService) GetUser(ctx context.Context, params directory.GetUserParams) (*directory.User, error) {
+	if m.getUserFunc != nil {
+		return m.getUserFunc(ctx, params)
+	}
+	return nil, nil
+}
+
+func (m *mockDirectoryService) ListUsers(
+	ctx context.Context,
+	params directory.ListUsersParams,
+) (*directory.UserPage, error) {
+	if m.listUsersFunc != nil {
+		return m.listUsersFunc(ctx, params)
+	}
+	return &directory.UserPage{}, nil
+}
+
+func (m *mockDirectoryService) UpdateUser(
+	ctx context.Context,
+	params directory.UpdateUserParams,
+) (*directory.User, error) {
+	if m.updateUserFunc != nil {
+		return m.updateUserFunc(ctx, params)
+	}
+	return nil, nil
+}
+
+func (m *mockDirectoryService) DeleteUser(ctx context.Context, params directory.DeleteUserParams) error {
+	if m.deleteUserFunc != nil {
+		return m.deleteUserFunc(ctx, params)
+	}
+	return nil
+}
+
+func (m *mockDirectoryService) CreateGroup(
+	ctx context.Context,
+	params directory.CreateGroupParams,
+) (*directory.Group, error) {
+	if m.createGroupFunc != nil {
+		return m.createGroupFunc(ctx, params)
+	}
+	return nil, nil
+}
+
+func (m *mockDirectoryService) GetGroup(
+	ctx context.Context,
+	params directory.GetGroupParams,
+) (*directory.Group, error) {
+	if m.getGroupFunc != nil {
+		return m.getGroupFunc(ctx, params)
+	}
+	return nil, nil
+}
+
+func (m *mockDirectoryService) ListGroups(
+	ctx context.Context,
+	params directory.ListGroupsParams,
+) (*directory.GroupPage, error) {
+	if m.listGroupsFunc != nil {
+		return m.listGroupsFunc(ctx, params)
+	}
+	return &directory.GroupPage{}, nil
+}
+
+func (m *mockDirectoryService) UpdateGroup(
+	ctx context.Context,
+	params directory.UpdateGroupParams,
+) (*directory.Group, error) {
+	if m.updateGroupFunc != nil {
+		return m.updateGroupFunc(ctx, params)
+	}
+	return nil, nil
+}
+
+func (m *mockDirectoryService) DeleteGroup(ctx context.Context, params directory.DeleteGroupParams) error {
+	if m.deleteGroupFunc != nil {
+		return m.deleteGroupFunc(ctx, params)
+	}
+	return nil
+}
+
+// serveSCIM sends the request to a router serving the SCIM routes of the service.
+func serveSCIM(t *testing.T, s Service, method, target, body string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	RegisterRoutes(router.Group("/scim/v2"), NewHandler(s))
+
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/scim+json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// decodeSCIMError decodes a SCIM error response.
+func decodeSCIMError(t *testing.T, w *httptest.ResponseRecorder) Error {
+	t.Helper()
+
+	var got Error
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+	assert.Equal(t, []string{SchemaError}, got.Schemas)
+	return got
+}
+
+func TestHandler_ServiceProviderConfig(t *testing.T) {
+	t.Parallel()
+
+	w := serveSCIM(t, &mockDirectoryService{}, http.MethodGet, "/scim/v2/ServiceProviderConfig", "")
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/scim+json; charset=utf-8", w.Header().Get("Content-Type"))
+	var got ServiceProviderConfig
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+	assert.True(t, got.Patch.Supported)
+	assert.True(t, got.Filter.Supported)
+	assert.Equal(t, maxPageSize, got.Filter.MaxResults)
+	assert.False(t, got.Bulk.Supported)
+}
+
+func TestHandler_ListUsers(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+
+	tests := []struct {
+		mockService    *mockDirectoryService
+		name           string
+		target         string
+		wantScimType   string
+		expectedStatus int
+		wantTotal      int
+	}{
+		{
+			name:   "filtered page",
+			target: `/scim/v2/Users?filter=userName+eq+%22alice%22&startIndex=1&count=10`,
+			mockService: &mockDirectoryService{
+				listUsersFunc: func(_ context.Context, params directory.ListUsersParams) (*directory.UserPage, error) {
+					assert.Equal(t, directory.ListUsersParams{UserName: "alice", Offset: 0, Limit: 10}, params)
+					return &directory.UserPage{
+						Users: []*directory.User{{ID: userID, UserName: "alice", Active: true}},
+						Total: 1,
+					}, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+			wantTotal:      1,
+		},
+		{
+			name:           "unsupported filter",
+			target:         `/scim/v2/Users?filter=userName+sw+%22a%22`,
+			mockService:    &mockDirectoryService{},
+			expectedStatus: http.StatusBadRequest,
+			wantScimType:   scimTypeInvalidFilter,
+		},
+		{
+			name:   "service error",
+			target: "/scim/v2/Users",
+			mockService: &mockDirectoryService{
+				listUsersFunc: func(context.Context, directory.ListUsersParams) (*directory.UserPage, error) {
+					return nil, directory.ErrDirectoryTechError
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			w := serveSCIM(t, tt.mockService, http.MethodGet, tt.target, "")
+
+			require.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus != http.StatusOK {
+				assert.Equal(t, tt.wantScimType, decodeSCIMError(t, w).SCIMType)
+				return
+			}
+			var got struct {
+				Resources    []User `json:"Resources"`
+				TotalResults int    `json:"totalResults"`
+				StartIndex   int    `json:"startIndex"`
+				ItemsPerPage int    `json:"itemsPerPage"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+			assert.Equal(t, tt.wantTotal, got.TotalResults)
+			assert.Equal(t, 1, got.StartIndex)
+			assert.Equal(t, len(got.Resources), got.ItemsPerPage)
+			assert.Equal(t, userID.String(), got.Resources[0].ID)
+		})
+	}
+}
+
+func TestHandler_GetUser(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+
+	tests := []struct {
+		mockService    *mockDirectoryService
+		name           string
+		id             string
+		expectedStatus int
+	}{
+		{
+			name: "success",
+			id:   userID.String(),
+			mockService: &mockDirectoryService{
+				getUserFunc: func(_ context.Context, params directory.GetUserParams) (*directory.User, error) {
+					assert.Equal(t, userID, params.ID)
+					return &directory.User{ID: userID, UserName: "alice", Active: true}, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "not found",
+			id:   userID.String(),
+			mockService: &mockDirectoryService{
+				getUserFunc: func(context.Context, directory.GetUserParams) (*directory.User, error) {
+					return nil, directory.ErrDirectoryUserNotFound
+				},
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "malformed ID",
+			id:             "not-a-uuid",
+			mockService:    &mockDirectoryService{},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			w := serveSCIM(t, tt.mockService, http.MethodGet, "/scim/v2/Users/"+tt.id, "")
+
+			require.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus != http.StatusOK {
+				decodeSCIMError(t, w)
+				return
+			}
+			var got User
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+			assert.Equal(t, []string{SchemaUser}, got.Schemas)
+			assert.Equal(t, "alice", got.UserName)
+			assert.True(t, got.Active)
+		})
+	}
+}
+
+func TestHandler_CreateUser(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		mockService    *mockDirectoryService
+		name           string
+		body           string
+		wantScimType   string
+		expectedStatus int
+	}{
+		{
+			name: "success",
+			body: `{"schemas":["` + SchemaUser + `"],"userName":"alice","externalId":"e-1",` +
+				`"name":{"givenName":"Alice"}}`,
+			mockService: &mockDirectoryService{
+				createUserFunc: func(_ context.Context, params directory.CreateUserParams) (*directory.User, error) {
+					assert.Equal(t, directory.CreateUserParams{UserName: "alice", ExternalID: "e-1", Active: true}, params)
+					return &directory.User{ID: uuid.New(), UserName: "alice", ExternalID: "e-1", Active: true}, nil
+				},
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name: "inactive user",
+			body: `{"userName":"alice","active":false}`,
+			mockService: &mockDirectoryService{
+				createUserFunc: func(_ context.Context, params directory.CreateUserParams) (*directory.User, error) {
+					assert.False(t, params.Active)
+					return &directory.User{ID: uuid.New(), UserName: "alice"}, nil
+				},
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name: "userName taken",
+			body: `{"userName":"alice"}`,
+			mockService: &mockDirectoryService{
+				createUserFunc: func(context.Context, directory.CreateUserParams) (*directory.User, error) {
+					return nil, directory.ErrDirectoryUserAlreadyExists
+				},
+			},
+			expectedStatus: http.StatusConflict,
+			wantScimType:   scimTypeUniqueness,
+		},
+		{
+			name: "invalid userName",
+			body: `{"userName":"al"}`,
+			mockService: &mockDirectoryService{
+				createUserFunc: func(context.Context, directory.CreateUserParams) (*directory.User, error) {
+					return nil, errors.Join(directory.ErrDirectoryAppError, directory.ErrDirectoryIncorrectUserName)
+				},
+			},
+			expectedStatus: http.StatusBadRequest,
+			wantScimType:   scimTypeInvalidValue,
+		},
+		{
+			name:           "malformed body",
+			body:           `{"userName":`,
+			mockService:    &mockDirectoryService{},
+			expectedStatus: http.StatusBadRequest,
+			wantScimType:   scimTypeInvalidSyntax,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			w := serveSCIM(t, tt.mockService, http.MethodPost, "/scim/v2/Users", tt.body)
+
+			require.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus != http.StatusCreated {
+				assert.Equal(t, tt.wantScimType, decodeSCIMError(t, w).SCIMType)
+				return
+			}
+			var got User
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+			assert.Equal(t, "alice", got.UserName)
+		})
+	}
+}
+
+func TestHandler_ReplaceUser(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	called := false
+	service := &mockDirectoryService{
+		updateUserFunc: func(_ context.Context, params directory.UpdateUserParams) (*directory.User, error) {
+			called = true
+			assert.Equal(t, userID, params.ID)
+			require.NotNil(t, params.UserName)
+			assert.Equal(t, "alice", *params.UserName)
+			require.NotNil(t, params.ExternalID)
+			assert.Empty(t, *params.ExternalID)
+			require.NotNil(t, params.Active)
+			assert.True(t, *params.Active)
+			assert.Nil(t, params.Password)
+			return &directory.User{ID: userID, UserName: "alice", Active: true}, nil
+		},
+	}
+
+	w := serveSCIM(t, service, http.MethodPut, "/scim/v2/Users/"+userID.String(), `{"userName":"alice"}`)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, called)
+}
+
+func TestHandler_PatchUser(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+
+	tests := []struct {
+		mockService    *mockDirectoryService
+		name           string
+		body           string
+		wantScimType   string
+		expectedStatus int
+	}{
+		{
+			name: "deactivate",
+			body: `{"schemas":["` + SchemaPatchOp + `"],"Operations":[{"op":"replace","path":"active","value":false}]}`,
+			mockService: &mockDirectoryService{
+				updateUserFunc: func(_ context.Context, params directory.UpdateUserParams) (*directory.User, error) {
+					require.NotNil(t, params.Active)
+					assert.False(t, *params.Active)
+					return &directory.User{ID: userID, UserName: "alice"}, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "unsupported operation",
+			body:           `{"Operations":[{"op":"move","path":"active"}]}`,
+			mockService:    &mockDirectoryService{},
+			expectedStatus: http.StatusBadRequest,
+			wantScimType:   scimTypeInvalidSyntax,
+		},
+		{
+			name: "user not found",
+			body: `{"Operations":[{"op":"replace","path":"active","value":false}]}`,
+			mockService: &mockDirectoryService{
+				updateUserFunc: func(context.Context, directory.UpdateUserParams) (*directory.User, error) {
+					return nil, directory.ErrDirectoryUserNotFound
+				},
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			w := serveSCIM(t, tt.mockService, http.MethodPatch, "/scim/v2/Users/"+userID.String(), tt.body)
+
+			require.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus != http.StatusOK {
+				assert.Equal(t, tt.wantScimType, decodeSCIMError(t, w).SCIMType)
+				return
+			}
+			var got User
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+			assert.False(t, got.Active)
+		})
+	}
+}
+
+func TestHandler_DeleteUser(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+
+	tests := []struct {
+		mockService    *mockDirectoryService
+		name           string
+		expectedStatus int
+	}{
+		{
+			name: "success",
+			mockService: &mockDirectoryService{
+				deleteUserFunc: func(_ context.Context, params directory.DeleteUserParams) error {
+					assert.Equal(t, userID, params.ID)
+					return nil
+				},
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name: "not found",
+			mockService: &mockDirectoryService{
+				deleteUserFunc: func(context.Context, directory.DeleteUserParams) error {
+					return directory.ErrDirectoryUserNotFound
+				},
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			w := serveSCIM(t, tt.mockService, http.MethodDelete, "/scim/v2/Users/"+userID.String(), "")
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestHandler_ListGroups(t *testing.T) {
+	t.Parallel()
+
+	groupID := uuid.New()
+	memberID := uuid.New()
+	service := &mockDirectoryService{
+		listGroupsFunc: func(_ context.Context, params directory.ListGroupsParams) (*directory.GroupPage, error) {
+			assert.Equal(t, directory.ListGroupsParams{DisplayName: "Ops", Offset: 4, Limit: defaultPageSize}, params)
+			return &directory.GroupPage{
+				Groups: []*directory.Group{{ID: groupID, DisplayName: "Ops", Members: []uuid.UUID{memberID}}},
+				Total:  5,
+			}, nil
+		},
+	}
+
+	w := serveSCIM(t, service, http.MethodGet, `/scim/v2/Groups?filter=displayName+eq+%22Ops%22&startIndex=5`, "")
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var got struct {
+		Resources    []Group `json:"Resources"`
+		TotalResults int     `json:"totalResults"`
+		StartIndex   int     `json:"startIndex"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+	assert.Equal(t, 5, got.TotalResults)
+	assert.Equal(t, 5, got.StartIndex)
+	require.Len(t, got.Resources, 1)
+	assert.Equal(t, []MemberRef{{Value: memberID.String()}}, got.Resources[0].Members)
+}
+
+func TestHandler_GetGroup(t *testing.T) {
+	t.Parallel()
+
+	groupID := uuid.New()
+
+	tests := []struct {
+		mockService    *mockDirectoryService
+		name           string
+		expectedStatus int
+	}{
+		{
+			name: "success",
+			mockService: &mockDirectoryService{
+				getGroupFunc: func(_ context.Context, params directory.GetGroupParams) (*directory.Group, error) {
+					assert.Equal(t, groupID, params.ID)
+					return &directory.Group{ID: groupID, DisplayName: "Ops"}, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "not found",
+			mockService: &mockDirectoryService{
+				getGroupFunc: func(context.Context, directory.GetGroupParams) (*directory.Group, error) {
+					return nil, directory.ErrDirectoryGroupNotFound
+				},
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			w := serveSCIM(t, tt.mockService, http.MethodGet, "/scim/v2/Groups/"+groupID.String(), "")
+
+			require.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus != http.StatusOK {
+				decodeSCIMError(t, w)
+				return
+			}
+			var got Group
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+			assert.Equal(t, []string{SchemaGroup}, got.Schemas)
+			assert.Equal(t, "Ops", got.DisplayName)
+			assert.Empty(t, got.Members)
+		})
+	}
+}
+
+func TestHandler_CreateGroup(t *testing.T) {
+	t.Parallel()
+
+	memberID := uuid.New()
+
+	tests := []struct {
+		mockService    *mockDirectoryService
+		name           string
+		body           string
+		wantScimType   string
+		expectedStatus int
+	}{
+		{
+			name: "success",
+			body: `{"displayName":"Ops","members":[{"value":"` + memberID.String() + `"}]}`,
+			mockService: &mockDirectoryService{
+				createGroupFunc: func(_ context.Context, params directory.CreateGroupParams) (*directory.Group, error) {
+					assert.Equal(t, directory.CreateGroupParams{DisplayName: "Ops", Members: []uuid.UUID{memberID}}, params)
+					return &directory.Group{ID: uuid.New(), DisplayName: "Ops", Members: params.Members}, nil
+				},
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name: "unknown member",
+			body: `{"displayName":"Ops","members":[{"value":"` + memberID.String() + `"}]}`,
+			mockService: &mockDirectoryService{
+				createGroupFunc: func(context.Context, directory.CreateGroupParams) (*directory.Group, error) {
+					return nil, directory.ErrDirectoryMemberNotFound
+				},
+			},
+			expectedStatus: http.StatusBadRequest,
+			wantScimType:   scimTypeInvalidValue,
+		},
+		{
+			name:           "member is not a user ID",
+			body:           `{"displayName":"Ops","members":[{"value":"alice"}]}`,
+			mockService:    &mockDirectoryService{},
+			expectedStatus: http.StatusBadRequest,
+			wantScimType:   scimTypeInvalidValue,
+		},
+		{
+			name: "displayName taken",
+			body: `{"displayName":"Ops"}`,
+			mockService: &mockDirectoryService{
+				createGroupFunc: func(context.Context, directory.CreateGroupParams) (*directory.Group, error) {
+					return nil, directory.ErrDirectoryGroupAlreadyExists
+				},
+			},
+			expectedStatus: http.StatusConflict,
+			wantScimType:   scimTypeUniqueness,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			w := serveSCIM(t, tt.mockService, http.MethodPost, "/scim/v2/Groups", tt.body)
+
+			require.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus != http.StatusCreated {
+				assert.Equal(t, tt.wantScimType, decodeSCIMError(t, w).SCIMType)
+				return
+			}
+			var got Group
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+			assert.Equal(t, []MemberRef{{Value: memberID.String()}}, got.Members)
+		})
+	}
+}
+
+func TestHandler_ReplaceGroup(t *testing.T) {
+	t.Parallel()
+
+	groupID := uuid.New()
+	memberID := uuid.New()
+	called := false
+	service := &mockDirectoryService{
+		updateGroupFunc: func(_ context.Context, params directory.UpdateGroupParams) (*directory.Group, error) {
+			called = true
+			assert.Equal(t, groupID, params.ID)
+			require.NotNil(t, params.DisplayName)
+			assert.Equal(t, "Ops", *params.DisplayName)
+			assert.Equal(t, []directory.MemberOp{
+				{Kind: directory.MemberOpReplace, UserIDs: []uuid.UUID{memberID}},
+			}, params.MemberOps)
+			return &directory.Group{ID: groupID, DisplayName: "Ops", Members: []uuid.UUID{memberID}}, nil
+		},
+	}
+
+	w := serveSCIM(t, service, http.MethodPut, "/scim/v2/Groups/"+groupID.String(),
+		`{"displayName":"Ops","members":[{"value":"`+memberID.String()+`"}]}`)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, called)
+}
+
+func TestHandler_PatchGroup(t *testing.T) {
+	t.Parallel()
+
+	groupID := uuid.New()
+	memberID := uuid.New()
+
+	tests := []struct {
+		mockService    *mockDirectoryService
+		name           string
+		body           string
+		wantScimType   string
+		expectedStatus int
+	}{
+		{
+			name: "remove member",
+			body: `{"Operations":[{"op":"remove","path":"members[value eq \"` + memberID.String() + `\"]"}]}`,
+			mockService: &mockDirectoryService{
+				updateGroupFunc: func(_ context.Context, params directory.UpdateGroupParams) (*directory.Group, error) {
+					assert.Equal(t, []directory.MemberOp{
+						{Kind: directory.MemberOpRemove, UserIDs: []uuid.UUID{memberID}},
+					}, params.MemberOps)
+					return &directory.Group{ID: groupID, DisplayName: "Ops"}, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid path",
+			body:           `{"Operations":[{"op":"remove","path":"displayName"}]}`,
+			mockService:    &mockDirectoryService{},
+			expectedStatus: http.StatusBadRequest,
+			wantScimType:   scimTypeInvalidPath,
+		},
+		{
+			name: "group not found",
+			body: `{"Operations":[{"op":"replace","path":"displayName","value":"Ops"}]}`,
+			mockService: &mockDirectoryService{
+				updateGroupFunc: func(context.Context, directory.UpdateGroupParams) (*directory.Group, error) {
+					return nil, directory.ErrDirectoryGroupNotFound
+				},
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			w := serveSCIM(t, tt.mockService, http.MethodPatch, "/scim/v2/Groups/"+groupID.String(), tt.body)
+
+			require.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus != http.StatusOK {
+				assert.Equal(t, tt.wantScimType, decodeSCIMError(t, w).SCIMType)
+			}
+		})
+	}
+}
+
+func TestHandler_DeleteGroup(t *testing.T) {
+	t.Parallel()
+
+	groupID := uuid.New()
+
+	tests := []struct {
+		mockService    *mockDirectoryService
+		name           string
+		id             string
+		expectedStatus int
+	}{
+		{
+			name: "success",
+			id:   groupID.String(),
+			mockService: &mockDirectoryService{
+				deleteGroupFunc: func(_ context.Context, params directory.DeleteGroupParams) error {
+					assert.Equal(t, groupID, params.ID)
+					return nil
+				},
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name: "not found",
+			id:   groupID.String(),
+			mockService: &mockDirectoryService{
+				deleteGroupFunc: func(context.Context, directory.DeleteGroupParams) error {
+					return directory.ErrDirectoryGroupNotFound
+				},
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "malformed ID",
+			id:             "42",
+			mockService:    &mockDirectoryService{},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			w := serveSCIM(t, tt.mockService, http.MethodDelete, "/scim/v2/Groups/"+tt.id, "")
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
@@ -0,0 +1,350 @@
+package scim
+
+import (
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/directory"
+	"github.com/google/uuid"
+)
+
+// Page size limits of list requests.
+const (
+	// defaultPageSize is the number of resources returned when the request sets no count.
+	defaultPageSize = 100
+	// maxPageSize limits the number of resources returned per page.
+	maxPageSize = 200
+)
+
+// Lower-cased names of the supported resource attributes.
+const (
+	// attrUserName names the login of a user.
+	attrUserName = "username"
+	// attrPassword names the password of a user.
+	attrPassword = "password"
+	// attrActive names the activation of a user.
+	attrActive = "active"
+	// attrExternalID names the directory identifier of a user or group.
+	attrExternalID = "externalid"
+	// attrDisplayName names the display name of a group.
+	attrDisplayName = "displayname"
+	// attrMembers names the members of a group.
+	attrMembers = "members"
+)
+
+// eqFilterPattern matches filters of the form `attribute eq "value"`.
+var eqFilterPattern = regexp.MustCompile(`^\s*([A-Za-z][\w.]*)\s+(?i:eq)\s+("(?:[^"\\]|\\.)*")\s*$`)
+
+// requestError reports a malformed SCIM request together with the SCIM error type to respond with.
+type requestError struct {
+	// scimType names the SCIM error type.
+	scimType string
+	// detail describes the problem.
+	detail string
+}
+
+// Error returns the error detail.
+func (e *requestError) Error() string {
+	return e.detail
+}
+
+// listQuery holds the query parameters of list requests.
+type listQuery struct {
+	// Count limits the number of resources returned; nil uses defaultPageSize.
+	Count *int `form:"count"`
+	// Filter restricts the resources returned.
+	Filter string `form:"filter"`
+	// StartIndex contains the 1-based index of the first resource to return.
+	StartIndex int `form:"startIndex"`
+}
+
+// page returns the 1-based start index, the offset and the limit of the requested page.
+func (q listQuery) page() (startIndex, offset, limit int) {
+	startIndex = max(q.StartIndex, 1)
+	limit = defaultPageSize
+	if q.Count != nil {
+		limit = min(max(*q.Count, 0), maxPageSize)
+	}
+	return startIndex, startIndex - 1, limit
+}
+
+// parseEqFilter parses a filter of the form `attribute eq "value"`, the only form supported, and returns
+// the lower-cased attribute name and the value.
+func parseEqFilter(filter string) (string, string, error) {
+	m := eqFilterPattern.FindStringSubmatch(filter)
+	if m == nil {
+		return "", "", &requestError{
+			scimType: scimTypeInvalidFilter,
+			detail:   `Only filters of the form 'attribute eq "value"' are supported`,
+		}
+	}
+	var value string
+	if err := json.Unmarshal([]byte(m[2]), &value); err != nil {
+		return "", "", &requestError{scimType: scimTypeInvalidFilter, detail: "Filter value is not a valid string"}
+	}
+	return strings.ToLower(m[1]), value, nil
+}
+
+// filterUsers converts a user filter into listing parameters; userName and externalId can be filtered on.
+func filterUsers(filter string, params *directory.ListUsersParams) error {
+	if filter == "" {
+		return nil
+	}
+	attr, value, err := parseEqFilter(filter)
+	if err != nil {
+		return err
+	}
+	switch attr {
+	case attrUserName:
+		params.UserName = value
+	case attrExternalID:
+		params.ExternalID = value
+	default:
+		return &requestError{scimType: scimTypeInvalidFilter, detail: "Users can be filtered by userName or externalId"}
+	}
+	return nil
+}
+
+// filterGroups converts a group filter into listing parameters; displayName and externalId can be filtered on.
+func filterGroups(filter string, params *directory.ListGroupsParams) error {
+	if filter == "" {
+		return nil
+	}
+	attr, value, err := parseEqFilter(filter)
+	if err != nil {
+		return err
+	}
+	switch attr {
+	case attrDisplayName:
+		params.DisplayName = value
+	case attrExternalID:
+		params.ExternalID = value
+	default:
+		return &requestError{
+			scimType: scimTypeInvalidFilter,
+			detail:   "Groups can be filtered by displayName or externalId",
+		}
+	}
+	return nil
+}
+
+// parseMembers converts member references into user IDs.
+func parseMembers(refs []MemberRef) ([]uuid.UUID, error) {
+	ids := make([]uuid.UUID, 0, len(refs))
+	for _, ref := range refs {
+		id, err := uuid.Parse(ref.Value)
+		if err != nil {
+			return nil, &requestError{scimType: scimTypeInvalidValue, detail: "Member values must be user IDs"}
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// newUserPatch converts the operations of a PATCH request into changes of the user. Operations on
+// attributes AegisVaultKeeper does not store, such as names and e-mails, are ignored.
+func newUserPatch(id uuid.UUID, ops []PatchOperation) (directory.UpdateUserParams, error) {
+	params := directory.UpdateUserParams{ID: id}
+	for _, op := range ops {
+		switch strings.ToLower(op.Op) {
+		case "add", "replace":
+			if op.Path != "" {
+				if err := setUserAttr(&params, op.Path, op.Value); err != nil {
+					return directory.UpdateUserParams{}, err
+				}
+				continue
+			}
+			attrs, err := decodeObject(op.Value)
+			if err != nil {
+				return directory.UpdateUserParams{}, err
+			}
+			for name, value := range attrs {
+				if err := setUserAttr(&params, name, value); err != nil {
+					return directory.UpdateUserParams{}, err
+				}
+			}
+		case "remove":
+			switch strings.ToLower(op.Path) {
+			case "":
+				return directory.UpdateUserParams{}, &requestError{
+					scimType: scimTypeInvalidPath, detail: "Remove operations require a path",
+				}
+			case attrExternalID:
+				empty := ""
+				params.ExternalID = &empty
+			case attrUserName, attrPassword, attrActive:
+				return directory.UpdateUserParams{}, &requestError{
+					scimType: scimTypeInvalidValue, detail: "Attribute " + op.Path + " cannot be removed",
+				}
+			}
+		default:
+			return directory.UpdateUserParams{}, unsupportedOp(op.Op)
+		}
+	}
+	return params, nil
+}
+
+// setUserAttr sets a user attribute from its JSON value; unsupported attributes are ignored.
+func setUserAttr(params *directory.UpdateUserParams, name string, value json.RawMessage) error {
+	var err error
+	switch strings.ToLower(name) {
+	case attrUserName:
+		params.UserName, err = decodeString(name, value)
+	case attrPassword:
+		params.Password, err = decodeString(name, value)
+	case attrExternalID:
+		params.ExternalID, err = decodeString(name, value)
+	case attrActive:
+		params.Active, err = decodeBool(name, value)
+	}
+	return err
+}
+
+// newGroupPatch converts the operations of a PATCH request into changes of the group.
+func newGroupPatch(id uuid.UUID, ops []PatchOperation) (directory.UpdateGroupParams, error) {
+	params := directory.UpdateGroupParams{ID: id}
+	for _, op := range ops {
+		var err error
+		switch kind := strings.ToLower(op.Op); kind {
+		case "add", "replace":
+			err = addGroupAttrs(&params, kind == "replace", op)
+		case "remove":
+			err = removeGroupAttr(&params, op)
+		default:
+			err = unsupportedOp(op.Op)
+		}
+		if err != nil {
+			return directory.UpdateGroupParams{}, err
+		}
+	}
+	return params, nil
+}
+
+// addGroupAttrs applies an add or replace operation to the group changes.
+func addGroupAttrs(params *directory.UpdateGroupParams, replace bool, op PatchOperation) error {
+	if op.Path != "" {
+		return setGroupAttr(params, replace, op.Path, op.Value)
+	}
+	attrs, err := decodeObject(op.Value)
+	if err != nil {
+		return err
+	}
+	for name, value := range attrs {
+		if err := setGroupAttr(params, replace, name, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setGroupAttr sets a group attribute from its JSON value; members are added, or replaced when replace
+// is set. Unsupported attributes are ignored.
+func setGroupAttr(params *directory.UpdateGroupParams, replace bool, name string, value json.RawMessage) error {
+	var err error
+	switch strings.ToLower(name) {
+	case attrDisplayName:
+		params.DisplayName, err = decodeString(name, value)
+	case attrExternalID:
+		params.ExternalID, err = decodeString(name, value)
+	case attrMembers:
+		var refs []MemberRef
+		if err := json.Unmarshal(value, &refs); err != nil {
+			return &requestError{scimType: scimTypeInvalidValue, detail: "members must be a list of member references"}
+		}
+		ids, err := parseMembers(refs)
+		if err != nil {
+			return err
+		}
+		kind := directory.MemberOpAdd
+		if replace {
+			kind = directory.MemberOpReplace
+		}
+		params.MemberOps = append(params.MemberOps, directory.MemberOp{Kind: kind, UserIDs: ids})
+	}
+	return err
+}
+
+// removeGroupAttr applies a remove operation to the group changes. Members are removed by a list value,
+// by a path of the form `members[value eq "id"]`, or all at once.
+func removeGroupAttr(params *directory.UpdateGroupParams, op PatchOperation) error {
+	path := strings.ToLower(strings.TrimSpace(op.Path))
+	switch {
+	case path == attrExternalID:
+		empty := ""
+		params.ExternalID = &empty
+	case path == attrMembers:
+		var refs []MemberRef
+		if len(op.Value) != 0 && string(op.Value) != "null" {
+			if err := json.Unmarshal(op.Value, &refs); err != nil {
+				return &requestError{
+					scimType: scimTypeInvalidValue, detail: "members must be a list of member references",
+				}
+			}
+		}
+		if len(refs) == 0 {
+			params.MemberOps = append(params.MemberOps, directory.MemberOp{Kind: directory.MemberOpReplace})
+			return nil
+		}
+		ids, err := parseMembers(refs)
+		if err != nil {
+			return err
+		}
+		params.MemberOps = append(params.MemberOps, directory.MemberOp{Kind: directory.MemberOpRemove, UserIDs: ids})
+	case strings.HasPrefix(path, attrMembers+"[") && strings.HasSuffix(path, "]"):
+		attr, value, err := parseEqFilter(op.Path[len(attrMembers)+1 : len(op.Path)-1])
+		if err != nil || attr != "value" {
+			return &requestError{scimType: scimTypeInvalidPath, detail: `Members are selected by 'members[value eq "id"]'`}
+		}
+		ids, err := parseMembers([]MemberRef{{Value: value}})
+		if err != nil {
+			return err
+		}
+		params.MemberOps = append(params.MemberOps, directory.MemberOp{Kind: directory.MemberOpRemove, UserIDs: ids})
+	default:
+		return &requestError{scimType: scimTypeInvalidPath, detail: "Attribute " + op.Path + " cannot be removed"}
+	}
+	return nil
+}
+
+// unsupportedOp reports a PATCH operation other than add, replace and remove.
+func unsupportedOp(op string) error {
+	return &requestError{scimType: scimTypeInvalidSyntax, detail: "Unsupported PATCH operation " + strconv.Quote(op)}
+}
+
+// decodeObject decodes the value of a PATCH operation without a path.
+func decodeObject(value json.RawMessage) (map[string]json.RawMessage, error) {
+	var attrs map[string]json.RawMessage
+	if err := json.Unmarshal(value, &attrs); err != nil {
+		return nil, &requestError{
+			scimType: scimTypeInvalidSyntax, detail: "Operations without a path require an object value",
+		}
+	}
+	return attrs, nil
+}
+
+// decodeString decodes a string attribute value.
+func decodeString(name string, value json.RawMessage) (*string, error) {
+	var s string
+	if err := json.Unmarshal(value, &s); err != nil {
+		return nil, &requestError{scimType: scimTypeInvalidValue, detail: name + " must be a string"}
+	}
+	return &s, nil
+}
+
+// decodeBool decodes a boolean attribute value, also accepting the "True" and "False" strings
+// sent by some identity providers.
+func decodeBool(name string, value json.RawMessage) (*bool, error) {
+	var b bool
+	if err := json.Unmarshal(value, &b); err == nil {
+		return &b, nil
+	}
+	var s string
+	if err := json.Unmarshal(value, &s); err == nil {
+		if b, err := strconv.ParseBool(s); err == nil {
+			return &b, nil
+		}
+	}
+	return nil, &requestError{scimType: scimTypeInvalidValue, detail: name + " must be a boolean"}
+}
@@ -0,0 +1,344 @@
+package scim
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/directory"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListQuery_Page(t *testing.T) {
+	t.Parallel()
+
+	count := func(n int) *int { return &n }
+
+	tests := []struct {
+		query          listQuery
+		name           string
+		wantStartIndex int
+		wantOffset     int
+		wantLimit      int
+	}{
+		{name: "defaults", query: listQuery{}, wantStartIndex: 1, wantOffset: 0, wantLimit: defaultPageSize},
+		{
+			name:           "explicit page",
+			query:          listQuery{StartIndex: 11, Count: count(10)},
+			wantStartIndex: 11, wantOffset: 10, wantLimit: 10,
+		},
+		{
+			name:           "count above maximum",
+			query:          listQuery{Count: count(maxPageSize + 1)},
+			wantStartIndex: 1, wantOffset: 0, wantLimit: maxPageSize,
+		},
+		{
+			name:           "negative count",
+			query:          listQuery{StartIndex: -3, Count: count(-1)},
+			wantStartIndex: 1, wantOffset: 0, wantLimit: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			startIndex, offset, limit := tt.query.page()
+
+			assert.Equal(t, tt.wantStartIndex, startIndex)
+			assert.Equal(t, tt.wantOffset, offset)
+			assert.Equal(t, tt.wantLimit, limit)
+		})
+	}
+}
+
+func TestParseEqFilter(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		filter    string
+		wantAttr  string
+		wantValue string
+		wantErr   bool
+	}{
+		{name: "simple", filter: `userName eq "alice"`, wantAttr: "username", wantValue: "alice"},
+		{name: "operator case", filter: ` externalId EQ "a-1" `, wantAttr: "externalid", wantValue: "a-1"},
+		{name: "escaped quote", filter: `displayName eq "say \"hi\""`, wantAttr: "displayname", wantValue: `say "hi"`},
+		{name: "other operator", filter: `userName co "ali"`, wantErr: true},
+		{name: "compound", filter: `userName eq "a" and active eq true`, wantErr: true},
+		{name: "unquoted value", filter: `active eq true`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			attr, value, err := parseEqFilter(tt.filter)
+
+			if tt.wantErr {
+				var reqErr *requestError
+				require.ErrorAs(t, err, &reqErr)
+				assert.Equal(t, scimTypeInvalidFilter, reqErr.scimType)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantAttr, attr)
+			assert.Equal(t, tt.wantValue, value)
+		})
+	}
+}
+
+func TestFilterUsers(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		filter  string
+		want    directory.ListUsersParams
+		wantErr bool
+	}{
+		{name: "no filter"},
+		{name: "userName", filter: `userName eq "alice"`, want: directory.ListUsersParams{UserName: "alice"}},
+		{name: "externalId", filter: `externalId eq "e-1"`, want: directory.ListUsersParams{ExternalID: "e-1"}},
+		{name: "unsupported attribute", filter: `emails eq "a@example.com"`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var got directory.ListUsersParams
+			err := filterUsers(tt.filter, &got)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFilterGroups(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		filter  string
+		want    directory.ListGroupsParams
+		wantErr bool
+	}{
+		{name: "no filter"},
+		{name: "displayName", filter: `displayName eq "Ops"`, want: directory.ListGroupsParams{DisplayName: "Ops"}},
+		{name: "externalId", filter: `externalId eq "g-1"`, want: directory.ListGroupsParams{ExternalID: "g-1"}},
+		{name: "unsupported attribute", filter: `members eq "x"`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var got directory.ListGroupsParams
+			err := filterGroups(tt.filter, &got)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNewUserPatch(t *testing.T) {
+	t.Parallel()
+
+	id := uuid.New()
+	str := func(s string) *string { return &s }
+	boolean := func(b bool) *bool { return &b }
+
+	tests := []struct {
+		name         string
+		ops          string
+		want         directory.UpdateUserParams
+		wantScimType string
+	}{
+		{
+			name: "replace with paths",
+			ops: `[{"op":"Replace","path":"active","value":false},` +
+				`{"op":"replace","path":"userName","value":"alice.new"}]`,
+			want: directory.UpdateUserParams{ID: id, Active: boolean(false), UserName: str("alice.new")},
+		},
+		{
+			name: "replace without path",
+			ops:  `[{"op":"replace","value":{"active":"False","externalId":"e-2","name":{"givenName":"A"}}}]`,
+			want: directory.UpdateUserParams{ID: id, Active: boolean(false), ExternalID: str("e-2")},
+		},
+		{
+			name: "add password",
+			ops:  `[{"op":"add","path":"password","value":"n3w-passw0rd"}]`,
+			want: directory.UpdateUserParams{ID: id, Password: str("n3w-passw0rd")},
+		},
+		{
+			name: "remove externalId",
+			ops:  `[{"op":"remove","path":"externalId"}]`,
+			want: directory.UpdateUserParams{ID: id, ExternalID: str("")},
+		},
+		{
+			name: "unsupported attribute ignored",
+			ops:  `[{"op":"replace","path":"emails[type eq \"work\"].value","value":"a@example.com"}]`,
+			want: directory.UpdateUserParams{ID: id},
+		},
+		{
+			name:         "remove userName",
+			ops:          `[{"op":"remove","path":"userName"}]`,
+			wantScimType: scimTypeInvalidValue,
+		},
+		{
+			name:         "remove without path",
+			ops:          `[{"op":"remove"}]`,
+			wantScimType: scimTypeInvalidPath,
+		},
+		{
+			name:         "active is not a boolean",
+			ops:          `[{"op":"replace","path":"active","value":"maybe"}]`,
+			wantScimType: scimTypeInvalidValue,
+		},
+		{
+			name:         "value without path is not an object",
+			ops:          `[{"op":"replace","value":"alice"}]`,
+			wantScimType: scimTypeInvalidSyntax,
+		},
+		{
+			name:         "unsupported operation",
+			ops:          `[{"op":"move","path":"userName"}]`,
+			wantScimType: scimTypeInvalidSyntax,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var ops []PatchOperation
+			require.NoError(t, json.Unmarshal([]byte(tt.ops), &ops))
+
+			got, err := newUserPatch(id, ops)
+
+			if tt.wantScimType != "" {
+				var reqErr *requestError
+				require.ErrorAs(t, err, &reqErr)
+				assert.Equal(t, tt.wantScimType, reqErr.scimType)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNewGroupPatch(t *testing.T) {
+	t.Parallel()
+
+	id := uuid.New()
+	member1 := uuid.New()
+	member2 := uuid.New()
+	str := func(s string) *string { return &s }
+
+	tests := []struct {
+		name         string
+		ops          string
+		want         directory.UpdateGroupParams
+		wantScimType string
+	}{
+		{
+			name: "add members",
+			ops:  `[{"op":"add","path":"members","value":[{"value":"` + member1.String() + `"}]}]`,
+			want: directory.UpdateGroupParams{ID: id, MemberOps: []directory.MemberOp{
+				{Kind: directory.MemberOpAdd, UserIDs: []uuid.UUID{member1}},
+			}},
+		},
+		{
+			name: "replace without path",
+			ops: `[{"op":"replace","value":{"displayName":"Ops","members":[{"value":"` +
+				member2.String() + `"}]}}]`,
+			want: directory.UpdateGroupParams{ID: id, DisplayName: str("Ops"), MemberOps: []directory.MemberOp{
+				{Kind: directory.MemberOpReplace, UserIDs: []uuid.UUID{member2}},
+			}},
+		},
+		{
+			name: "remove members by value",
+			ops:  `[{"op":"remove","path":"members","value":[{"value":"` + member1.String() + `"}]}]`,
+			want: directory.UpdateGroupParams{ID: id, MemberOps: []directory.MemberOp{
+				{Kind: directory.MemberOpRemove, UserIDs: []uuid.UUID{member1}},
+			}},
+		},
+		{
+			name: "remove member by filter path",
+			ops:  `[{"op":"remove","path":"members[value eq \"` + member2.String() + `\"]"}]`,
+			want: directory.UpdateGroupParams{ID: id, MemberOps: []directory.MemberOp{
+				{Kind: directory.MemberOpRemove, UserIDs: []uuid.UUID{member2}},
+			}},
+		},
+		{
+			name: "remove all members",
+			ops:  `[{"op":"remove","path":"members"}]`,
+			want: directory.UpdateGroupParams{ID: id, MemberOps: []directory.MemberOp{
+				{Kind: directory.MemberOpReplace},
+			}},
+		},
+		{
+			name: "operations kept in order",
+			ops: `[{"op":"remove","path":"members"},` +
+				`{"op":"add","path":"members","value":[{"value":"` + member1.String() + `"}]}]`,
+			want: directory.UpdateGroupParams{ID: id, MemberOps: []directory.MemberOp{
+				{Kind: directory.MemberOpReplace},
+				{Kind: directory.MemberOpAdd, UserIDs: []uuid.UUID{member1}},
+			}},
+		},
+		{
+			name:         "member is not a user ID",
+			ops:          `[{"op":"add","path":"members","value":[{"value":"alice"}]}]`,
+			wantScimType: scimTypeInvalidValue,
+		},
+		{
+			name:         "remove displayName",
+			ops:          `[{"op":"remove","path":"displayName"}]`,
+			wantScimType: scimTypeInvalidPath,
+		},
+		{
+			name:         "malformed member filter",
+			ops:          `[{"op":"remove","path":"members[display eq \"x\"]"}]`,
+			wantScimType: scimTypeInvalidPath,
+		},
+		{
+			name:         "unsupported operation",
+			ops:          `[{"op":"copy","path":"members"}]`,
+			wantScimType: scimTypeInvalidSyntax,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var ops []PatchOperation
+			require.NoError(t, json.Unmarshal([]byte(tt.ops), &ops))
+
+			got, err := newGroupPatch(id, ops)
+
+			if tt.wantScimType != "" {
+				var reqErr *requestError
+				require.ErrorAs(t, err, &reqErr)
+				assert.Equal(t, tt.wantScimType, reqErr.scimType)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
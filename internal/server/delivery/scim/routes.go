@@ -0,0 +1,25 @@
+package scim
+
+import "github.com/gin-gonic/gin"
+
+// RegisterRoutes registers SCIM provisioning routes with the provided router group.
+// Creates /ServiceProviderConfig, /Users, /Users/:id, /Groups and /Groups/:id with the specified handler.
+func RegisterRoutes(r *gin.RouterGroup, h *Handler) {
+	r.GET("/ServiceProviderConfig", h.ServiceProviderConfig)
+
+	usersGroup := r.Group("/Users")
+	usersGroup.GET("", h.ListUsers)
+	usersGroup.POST("", h.CreateUser)
+	usersGroup.GET("/:id", h.GetUser)
+	usersGroup.PUT("/:id", h.ReplaceUser)
+	usersGroup.PATCH("/:id", h.PatchUser)
+	usersGroup.DELETE("/:id", h.DeleteUser)
+
+	groupsGroup := r.Group("/Groups")
+	groupsGroup.GET("", h.ListGroups)
+	groupsGroup.POST("", h.CreateGroup)
+	groupsGroup.GET("/:id", h.GetGroup)
+	groupsGroup.PUT("/:id", h.ReplaceGroup)
+	groupsGroup.PATCH("/:id", h.PatchGroup)
+	groupsGroup.DELETE("/:id", h.DeleteGroup)
+}
@@ -0,0 +1,32 @@
+package scim
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterRoutes(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	RegisterRoutes(router.Group("/scim/v2"), NewHandler(&mockDirectoryService{}))
+
+	got := make(map[string]string)
+	for _, r := range router.Routes() {
+		got[r.Method+" "+r.Path] = r.Handler
+	}
+	assert.Len(t, got, 13)
+	assert.Contains(t, got, http.MethodGet+" /scim/v2/ServiceProviderConfig")
+	for _, resource := range []string{"/scim/v2/Users", "/scim/v2/Groups"} {
+		assert.Contains(t, got, http.MethodGet+" "+resource)
+		assert.Contains(t, got, http.MethodPost+" "+resource)
+		assert.Contains(t, got, http.MethodGet+" "+resource+"/:id")
+		assert.Contains(t, got, http.MethodPut+" "+resource+"/:id")
+		assert.Contains(t, got, http.MethodPatch+" "+resource+"/:id")
+		assert.Contains(t, got, http.MethodDelete+" "+resource+"/:id")
+	}
+}
@@ -0,0 +1,5 @@
+// Package sdk provides the endpoint reporting the API contract generated clients are built against.
+//
+// Clients generated from the OpenAPI specification embed its version and hash; comparing them with the
+// values reported here tells a client whether it is in lockstep with the server.
+package sdk
@@ -0,0 +1,10 @@
+package sdk
+
+// Info describes the OpenAPI specification the server implements.
+type Info struct {
+	// SpecVersion is the API version declared by the specification.
+	SpecVersion string `json:"spec_version" example:"0.1.1"`
+	// SpecSHA256 is the hex SHA-256 digest of the canonical specification, equal to SPEC_SHA256 of
+	// clients generated from it.
+	SpecSHA256 string `json:"spec_sha256"  example:"eda1f39b6c5919200fddf585819d2cf1a0b3ed25b5c34cab8ded871cbbc24c12"`
+}
@@ -0,0 +1,58 @@
+package sdk
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/sdkgen"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/response"
+	"github.com/gin-gonic/gin"
+)
+
+// Handler handles HTTP requests for the SDK contract endpoint.
+type Handler struct {
+	// info computes the specification info once, on first use.
+	info func() (Info, error)
+}
+
+// NewHandler creates a new SDK handler reporting the specification returned by spec.
+func NewHandler(spec func() (string, error)) *Handler {
+	return &Handler{info: sync.OnceValues(func() (Info, error) {
+		doc, err := spec()
+		if err != nil {
+			return Info{}, fmt.Errorf("failed to read OpenAPI spec: %w", err)
+		}
+		parsed, err := sdkgen.ParseSpec([]byte(doc))
+		if err != nil {
+			return Info{}, err
+		}
+		hash, err := sdkgen.SpecHash([]byte(doc))
+		if err != nil {
+			return Info{}, err
+		}
+		return Info{SpecVersion: parsed.Info.Version, SpecSHA256: hash}, nil
+	})}
+}
+
+// Info returns the version and hash of the OpenAPI specification the server implements.
+// @Summary      Get SDK contract information
+// @Description  Reports the version and SHA-256 hash of the OpenAPI specification served by this server.
+// @Description  Generated clients embed the same values, so a mismatch means the client must be regenerated.
+// .
+// @Tags         System
+// @Accept       json
+// @Produce      json
+// @Success      200 {object} Info "Specification information"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /sdk [get]
+// .
+func (h *Handler) Info(c *gin.Context) {
+	info, err := h.info()
+	if err != nil {
+		_ = c.Error(err)
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+	c.JSON(http.StatusOK, info)
+}
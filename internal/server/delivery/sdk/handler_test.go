@@ -0,0 +1,110 @@
+package sdk
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	_ "github.com/gdyunin/aegis-vault-keeper/docs"
+	"github.com/gdyunin/aegis-vault-keeper/internal/sdkgen"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggo/swag"
+)
+
+func TestHandler_Info(t *testing.T) {
+	t.Parallel()
+
+	spec := `{"swagger":"2.0","info":{"title":"API","version":"0.2.0"},"paths":{}}`
+	hash, err := sdkgen.SpecHash([]byte(spec))
+	require.NoError(t, err)
+
+	tests := []struct {
+		spec       func() (string, error)
+		name       string
+		wantBody   string
+		wantStatus int
+	}{
+		{
+			name:       "success",
+			spec:       func() (string, error) { return spec, nil },
+			wantStatus: http.StatusOK,
+			wantBody:   `{"spec_version":"0.2.0","spec_sha256":"` + hash + `"}`,
+		},
+		{
+			name:       "spec unavailable",
+			spec:       func() (string, error) { return "", errors.New("no swag has yet been registered") },
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   `{"messages":["Internal Server Error"]}`,
+		},
+		{
+			name:       "invalid spec",
+			spec:       func() (string, error) { return "{", nil },
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   `{"messages":["Internal Server Error"]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			RegisterRoutes(router.Group("/api"), NewHandler(tt.spec))
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/sdk", nil))
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.JSONEq(t, tt.wantBody, w.Body.String())
+		})
+	}
+}
+
+func TestNewHandler_ReadsSpecOnce(t *testing.T) {
+	t.Parallel()
+
+	// calls counts the reads of the specification.
+	calls := 0
+	h := NewHandler(func() (string, error) {
+		calls++
+		return `{"info":{"version":"1.0"}}`, nil
+	})
+
+	for range 3 {
+		info, err := h.info()
+		require.NoError(t, err)
+		assert.Equal(t, "1.0", info.SpecVersion)
+	}
+	assert.Equal(t, 1, calls)
+}
+
+// TestHandler_Info_MatchesGeneratedSpec checks that the hash reported for the spec the server renders equals the
+// hash embedded in clients generated from docs/swagger.json.
+func TestHandler_Info_MatchesGeneratedSpec(t *testing.T) {
+	t.Parallel()
+
+	file, err := os.ReadFile(filepath.Join("..", "..", "..", "..", "docs", "swagger.json"))
+	require.NoError(t, err)
+	want, err := sdkgen.SpecHash(file)
+	require.NoError(t, err)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	RegisterRoutes(router.Group("/api"), NewHandler(func() (string, error) { return swag.ReadDoc() }))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/sdk", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	// got holds the decoded response.
+	var got Info
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+	assert.Equal(t, want, got.SpecSHA256)
+}
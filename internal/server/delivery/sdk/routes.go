@@ -0,0 +1,8 @@
+package sdk
+
+import "github.com/gin-gonic/gin"
+
+// RegisterRoutes registers the SDK contract routes with the provided router group.
+func RegisterRoutes(r *gin.RouterGroup, h *Handler) {
+	r.GET("/sdk", h.Info)
+}
@@ -0,0 +1,23 @@
+package sdk
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterRoutes(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	RegisterRoutes(router.Group("/api"), NewHandler(func() (string, error) { return "{}", nil }))
+
+	routes := router.Routes()
+	if assert.Len(t, routes, 1) {
+		assert.Equal(t, http.MethodGet, routes[0].Method)
+		assert.Equal(t, "/api/sdk", routes[0].Path)
+	}
+}
@@ -0,0 +1,5 @@
+// Package signingkey provides HTTP handlers for request signing key endpoints in the AegisVaultKeeper server.
+//
+// This package lets authenticated users issue, list and revoke the per-client HMAC keys
+// their export requests are signed with.
+package signingkey
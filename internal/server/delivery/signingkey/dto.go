@@ -0,0 +1,74 @@
+package signingkey
+
+import (
+	"encoding/base64"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/signingkey"
+	"github.com/google/uuid"
+)
+
+// SigningKey represents a request signing key without its secret.
+type SigningKey struct {
+	// CreatedAt contains the key creation timestamp.
+	CreatedAt time.Time `json:"created_at" example:"2023-12-01T10:00:00Z"`
+	// Name contains the label of the client holding the key.
+	Name string `json:"name"       example:"backup-cli"`
+	// ID contains the key identifier sent in request signatures.
+	ID uuid.UUID `json:"id"         example:"123e4567-e89b-12d3-a456-426614174000"`
+}
+
+// NewSigningKeyFromApp converts an application layer signing key to delivery DTO.
+func NewSigningKeyFromApp(k *signingkey.SigningKey) *SigningKey {
+	if k == nil {
+		return nil
+	}
+	return &SigningKey{
+		ID:        k.ID,
+		Name:      k.Name,
+		CreatedAt: k.CreatedAt,
+	}
+}
+
+// NewSigningKeysFromApp converts application layer signing keys to delivery DTOs.
+func NewSigningKeysFromApp(ks []*signingkey.SigningKey) []*SigningKey {
+	result := make([]*SigningKey, 0, len(ks))
+	for _, k := range ks {
+		result = append(result, NewSigningKeyFromApp(k))
+	}
+	return result
+}
+
+// CreateSigningKeyRequest represents the request to issue a signing key.
+type CreateSigningKeyRequest struct {
+	// Name contains the label of the client the key is issued to (required, up to 64 characters).
+	Name string `json:"name" binding:"required" example:"backup-cli"`
+}
+
+// CreateSigningKeyResponse represents the response after issuing a signing key.
+type CreateSigningKeyResponse struct {
+	// Key contains the issued key.
+	Key *SigningKey `json:"key"`
+	// Secret contains the base64-encoded key material; it is shown only once.
+	Secret string `json:"secret" example:"q83vEjRWeJq83vEjRWeJq83vEjRWeJq83vEjRWeJq80="`
+}
+
+// NewCreateSigningKeyResponseFromApp converts an issued application layer signing key to delivery DTO.
+func NewCreateSigningKeyResponseFromApp(k *signingkey.SigningKey) CreateSigningKeyResponse {
+	return CreateSigningKeyResponse{
+		Key:    NewSigningKeyFromApp(k),
+		Secret: base64.StdEncoding.EncodeToString(k.Secret),
+	}
+}
+
+// ListSigningKeysResponse represents the response containing the signing keys of the user.
+type ListSigningKeysResponse struct {
+	// Keys contains the signing keys ordered by creation time.
+	Keys []*SigningKey `json:"keys"`
+}
+
+// DeleteSigningKeyRequest represents the request to revoke a signing key.
+type DeleteSigningKeyRequest struct {
+	// ID contains the key identifier (required UUID format).
+	ID string `uri:"id" binding:"required" example:"123e4567-e89b-12d3-a456-426614174000"`
+}
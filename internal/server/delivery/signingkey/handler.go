@@ -0,0 +1,160 @@
+package signingkey
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/signingkey"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/response"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/util"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/securebytes"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Service defines the signing key application service interface.
+type Service interface {
+	// Create issues a new signing key and returns it together with its secret.
+	Create(context.Context, signingkey.CreateParams) (*signingkey.SigningKey, error)
+	// List retrieves the signing keys of the user without their secrets.
+	List(context.Context, signingkey.ListParams) ([]*signingkey.SigningKey, error)
+	// Delete revokes a signing key of the user.
+	Delete(context.Context, signingkey.DeleteParams) error
+}
+
+// Handler handles HTTP requests for signing key endpoints.
+type Handler struct {
+	// s is the signing key service used to process operations.
+	s Service
+}
+
+// NewHandler creates a new signing key handler with the provided service.
+func NewHandler(s Service) *Handler {
+	return &Handler{s: s}
+}
+
+// List retrieves the signing keys of the authenticated user.
+// @Summary      List signing keys
+// @Description  Retrieves the request signing keys issued to the clients of the user, without their secrets
+// @Tags         Account
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} ListSigningKeysResponse "Signing keys retrieved successfully"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /account/signing-keys [get]
+// .
+func (h *Handler) List(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	userID, err := extractor.UserID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+
+	keys, err := h.s.List(c, signingkey.ListParams{UserID: userID})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, ListSigningKeysResponse{Keys: NewSigningKeysFromApp(keys)})
+}
+
+// Create issues a signing key to a client of the authenticated user.
+// @Summary      Create signing key
+// @Description  Issues an HMAC key a client signs export requests with. The secret is shown only once.
+// @Description  Once the user has a key, vault exports without a valid signature are rejected.
+// .
+// @Tags         Account
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body CreateSigningKeyRequest true "Signing key data"
+// @Success      201 {object} CreateSigningKeyResponse "Signing key created successfully"
+// @Failure      400 {object} response.Error "Bad request - invalid name"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /account/signing-keys [post]
+// .
+func (h *Handler) Create(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	userID, err := extractor.UserID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+
+	// req holds the deserialized JSON request payload for the key creation.
+	var req CreateSigningKeyRequest
+	if err := extractor.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	key, err := h.s.Create(c, signingkey.CreateParams{Name: req.Name, UserID: userID})
+	if err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+	defer securebytes.Wipe(key.Secret)
+
+	c.JSON(http.StatusCreated, NewCreateSigningKeyResponseFromApp(key))
+}
+
+// Delete revokes a signing key of the authenticated user.
+// @Summary      Delete signing key
+// @Description  Revokes a request signing key. Revoking the last key lifts the signing requirement.
+// @Tags         Account
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Signing key ID" format(uuid)
+// @Success      204 "Signing key deleted successfully"
+// @Failure      400 {object} response.Error "Bad request - invalid ID format"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
+// @Failure      404 {object} response.Error "Not found - signing key not found"
+// @Failure      500 {object} response.Error "Internal server error"
+// @Router       /account/signing-keys/{id} [delete]
+// .
+func (h *Handler) Delete(c *gin.Context) {
+	extractor := util.NewCtxExtractor(c)
+
+	userID, err := extractor.UserID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DefaultInternalServerError)
+		return
+	}
+
+	// req holds the deserialized URI parameters for the delete request.
+	var req DeleteSigningKeyRequest
+	if err := extractor.BindURI(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	keyID, err := uuid.Parse(req.ID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.DefaultBadRequestError)
+		return
+	}
+
+	if err := h.s.Delete(c, signingkey.DeleteParams{ID: keyID, UserID: userID}); err != nil {
+		code, msgs := handleError(err, c)
+		c.JSON(code, response.Error{
+			Messages: msgs,
+		})
+		return
+	}
+
+	c.Data(http.StatusNoContent, "", nil)
+}
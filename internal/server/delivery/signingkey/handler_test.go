@@ -0,0 +1,271 @@
+package signingkey
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/signingkey"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/consts"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockSigningKeyService implements Service for testing.
+type mockSigningKeyService struct {
+	createFunc func(ctx context.Context, params signingkey.CreateParams) (*signingkey.SigningKey, error)
+	listFunc   func(ctx context.Context, params signingkey.ListParams) ([]*signingkey.SigningKey, error)
+	deleteFunc func(ctx context.Context, params signingkey.DeleteParams) error
+}
+
+func (m *mockSigningKeyService) Create(
+	ctx context.Context,
+	params signingkey.CreateParams,
+) (*signingkey.SigningKey, error) {
+	if m.createFunc != nil {
+		return m.createFunc(ctx, params)
+	}
+	return nil, nil
+}
+
+func (m *mockSigningKeyService) List(
+	ctx context.Context,
+	params signingkey.ListParams,
+) ([]*signingkey.SigningKey, error) {
+	if m.listFunc != nil {
+		return m.listFunc(ctx, params)
+	}
+	return nil, nil
+}
+
+func (m *mockSigningKeyService) Delete(ctx context.Context, params signingkey.DeleteParams) error {
+	if m.deleteFunc != nil {
+		return m.deleteFunc(ctx, params)
+	}
+	return nil
+}
+
+func TestHandler_List(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	keyID := uuid.New()
+	createdAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		mockService    *mockSigningKeyService
+		want           *ListSigningKeysResponse
+		name           string
+		setUser        bool
+		expectedStatus int
+	}{
+		{
+			name:    "success",
+			setUser: true,
+			mockService: &mockSigningKeyService{
+				listFunc: func(_ context.Context, params signingkey.ListParams) ([]*signingkey.SigningKey, error) {
+					assert.Equal(t, userID, params.UserID)
+					return []*signingkey.SigningKey{{ID: keyID, Name: "laptop", CreatedAt: createdAt}}, nil
+				},
+			},
+			want: &ListSigningKeysResponse{
+				Keys: []*SigningKey{{ID: keyID, Name: "laptop", CreatedAt: createdAt}},
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "missing user context",
+			mockService:    &mockSigningKeyService{},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:    "service error",
+			setUser: true,
+			mockService: &mockSigningKeyService{
+				listFunc: func(context.Context, signingkey.ListParams) ([]*signingkey.SigningKey, error) {
+					return nil, signingkey.ErrSigningKeyTechError
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/account/signing-keys", nil)
+			if tt.setUser {
+				c.Set(consts.CtxKeyUserID, userID)
+			}
+
+			NewHandler(tt.mockService).List(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.want == nil {
+				return
+			}
+			var got ListSigningKeysResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+			assert.Equal(t, *tt.want, got)
+		})
+	}
+}
+
+func TestHandler_Create(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	keyID := uuid.New()
+	secret := []byte("0123456789abcdef0123456789abcdef")
+	wantSecret := base64.StdEncoding.EncodeToString(secret)
+
+	tests := []struct {
+		mockService    *mockSigningKeyService
+		name           string
+		body           string
+		wantName       string
+		expectedStatus int
+	}{
+		{
+			name: "success",
+			body: `{"name":"backup-cli"}`,
+			mockService: &mockSigningKeyService{
+				createFunc: func(_ context.Context, params signingkey.CreateParams) (*signingkey.SigningKey, error) {
+					assert.Equal(t, userID, params.UserID)
+					assert.Equal(t, "backup-cli", params.Name)
+					return &signingkey.SigningKey{
+						ID:     keyID,
+						Name:   params.Name,
+						Secret: append([]byte(nil), secret...),
+					}, nil
+				},
+			},
+			wantName:       "backup-cli",
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "missing name",
+			body:           `{}`,
+			mockService:    &mockSigningKeyService{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "invalid name",
+			body: `{"name":"` + strings.Repeat("a", 65) + `"}`,
+			mockService: &mockSigningKeyService{
+				createFunc: func(context.Context, signingkey.CreateParams) (*signingkey.SigningKey, error) {
+					return nil, signingkey.ErrSigningKeyIncorrectName
+				},
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "service error",
+			body: `{"name":"backup-cli"}`,
+			mockService: &mockSigningKeyService{
+				createFunc: func(context.Context, signingkey.CreateParams) (*signingkey.SigningKey, error) {
+					return nil, errors.Join(signingkey.ErrSigningKeyTechError, errors.New("db down"))
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/account/signing-keys", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Set(consts.CtxKeyUserID, userID)
+
+			NewHandler(tt.mockService).Create(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.wantName == "" {
+				return
+			}
+			var got CreateSigningKeyResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+			require.NotNil(t, got.Key)
+			assert.Equal(t, keyID, got.Key.ID)
+			assert.Equal(t, tt.wantName, got.Key.Name)
+			assert.Equal(t, wantSecret, got.Secret)
+		})
+	}
+}
+
+func TestHandler_Delete(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	keyID := uuid.New()
+
+	tests := []struct {
+		mockService    *mockSigningKeyService
+		name           string
+		id             string
+		expectedStatus int
+	}{
+		{
+			name: "success",
+			id:   keyID.String(),
+			mockService: &mockSigningKeyService{
+				deleteFunc: func(_ context.Context, params signingkey.DeleteParams) error {
+					assert.Equal(t, signingkey.DeleteParams{ID: keyID, UserID: userID}, params)
+					return nil
+				},
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "invalid id",
+			id:             "not-a-uuid",
+			mockService:    &mockSigningKeyService{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "not found",
+			id:   keyID.String(),
+			mockService: &mockSigningKeyService{
+				deleteFunc: func(context.Context, signingkey.DeleteParams) error {
+					return signingkey.ErrSigningKeyNotFound
+				},
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodDelete, "/account/signing-keys/"+tt.id, nil)
+			c.Params = gin.Params{{Key: "id", Value: tt.id}}
+			c.Set(consts.CtxKeyUserID, userID)
+
+			NewHandler(tt.mockService).Delete(c)
+
+			c.Writer.WriteHeaderNow()
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
@@ -0,0 +1,12 @@
+package signingkey
+
+import "github.com/gin-gonic/gin"
+
+// RegisterRoutes registers signing key routes with the provided router group.
+// Creates /signing-keys and /signing-keys/:id with the specified handler.
+func RegisterRoutes(r *gin.RouterGroup, h *Handler) {
+	keysGroup := r.Group("/signing-keys")
+	keysGroup.GET("", h.List)
+	keysGroup.POST("", h.Create)
+	keysGroup.DELETE("/:id", h.Delete)
+}
@@ -0,0 +1,14 @@
+package util
+
+import (
+	"slices"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Chain returns the handlers of a route running the middleware before the handler. Routes built from the same
+// middleware slice get chains of their own: appending to a slice with spare capacity would write every handler
+// into its shared backing array, so each route would end up running the handler appended last.
+func Chain(middleware []gin.HandlerFunc, handler gin.HandlerFunc) []gin.HandlerFunc {
+	return append(slices.Clip(middleware), handler)
+}
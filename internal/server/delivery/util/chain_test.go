@@ -0,0 +1,30 @@
+package util
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChain(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+	// middleware has spare capacity, so appending to it directly would share its backing array.
+	middleware := make([]gin.HandlerFunc, 1, 4)
+	middleware[0] = func(c *gin.Context) { c.Header("X-Middleware", "run") }
+
+	router := gin.New()
+	router.GET("/first", Chain(middleware, func(c *gin.Context) { c.String(http.StatusOK, "first") })...)
+	router.GET("/second", Chain(middleware, func(c *gin.Context) { c.String(http.StatusOK, "second") })...)
+
+	for _, route := range []string{"first", "second"} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/"+route, http.NoBody))
+		assert.Equal(t, route, w.Body.String())
+		assert.Equal(t, "run", w.Header().Get("X-Middleware"))
+	}
+}
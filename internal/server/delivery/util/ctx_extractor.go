@@ -3,6 +3,8 @@ package util
 import (
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/consts"
 	"github.com/gin-gonic/gin"
@@ -34,6 +36,20 @@ func (e *CtxExtractor) UserID() (uuid.UUID, error) {
 	return userID, nil
 }
 
+// AdminOperator extracts the name of the admin operator authenticated by the admin token from the context.
+// Returns an error if the operator is not present or is not a non-empty string.
+func (e *CtxExtractor) AdminOperator() (string, error) {
+	value, exists := e.c.Get(consts.CtxKeyAdminOperator)
+	if !exists {
+		return "", errors.New("admin operator not found in context")
+	}
+	operator, ok := value.(string)
+	if !ok || operator == "" {
+		return "", errors.New("admin operator in context is not a non-empty string")
+	}
+	return operator, nil
+}
+
 // BindJSON binds the request JSON body to the provided destination pointer.
 // Returns an error if the JSON is malformed or doesn't match the destination type.
 func (e *CtxExtractor) BindJSON(destPtr any) error {
@@ -51,3 +67,65 @@ func (e *CtxExtractor) BindURI(destPtr any) error {
 	}
 	return nil
 }
+
+// BindQuery binds the request query parameters to the provided destination pointer.
+// Returns an error if the query parameters don't match the destination type.
+func (e *CtxExtractor) BindQuery(destPtr any) error {
+	if err := e.c.ShouldBindQuery(destPtr); err != nil {
+		return fmt.Errorf("failed to bind query: %w", err)
+	}
+	return nil
+}
+
+// Fields extracts the comma-separated field names of the "fields" query parameter, keeping their order
+// and dropping duplicates. Returns nil when the parameter is absent or empty, and an error when a name is
+// not one of the allowed names.
+func (e *CtxExtractor) Fields(allowed []string) ([]string, error) {
+	return e.queryNames("fields", "field", allowed)
+}
+
+// Include extracts the comma-separated names of the "include" query parameter, which adds items left out of
+// lists by default, such as archived items. Returns nil when the parameter is absent or empty, and an error
+// when a name is not one of the allowed names.
+func (e *CtxExtractor) Include(allowed []string) ([]string, error) {
+	return e.queryNames("include", "include", allowed)
+}
+
+// Sort extracts the name of the "sort" query parameter, which selects the order of lists, such as the manual
+// order. Returns an empty name when the parameter is absent and an error when it is not one of the allowed names.
+func (e *CtxExtractor) Sort(allowed []string) (string, error) {
+	name := strings.TrimSpace(e.c.Query("sort"))
+	if name != "" && !slices.Contains(allowed, name) {
+		return "", fmt.Errorf("unknown sort %q", name)
+	}
+	return name, nil
+}
+
+// Folder extracts the "folder" query parameter naming the folder whose manual order sorts lists; empty
+// addresses the root.
+func (e *CtxExtractor) Folder() string {
+	return e.c.Query("folder")
+}
+
+// queryNames extracts the comma-separated names of the query parameter, keeping their order and dropping
+// duplicates, and rejects names that are not allowed, calling them kind in the error.
+func (e *CtxExtractor) queryNames(param, kind string, allowed []string) ([]string, error) {
+	raw := e.c.Query(param)
+	if raw == "" {
+		return nil, nil
+	}
+
+	// names holds the validated names in request order.
+	var names []string
+	for name := range strings.SplitSeq(raw, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" || slices.Contains(names, name) {
+			continue
+		}
+		if !slices.Contains(allowed, name) {
+			return nil, fmt.Errorf("unknown %s %q", kind, name)
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/consts"
 	"github.com/gin-gonic/gin"
@@ -133,6 +134,62 @@ func TestCtxExtractor_UserID(t *testing.T) {
 	}
 }
 
+func TestCtxExtractor_AdminOperator(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		value   any
+		name    string
+		want    string
+		set     bool
+		wantErr bool
+	}{
+		{
+			name:  "named operator",
+			value: "alice",
+			set:   true,
+			want:  "alice",
+		},
+		{
+			name:    "operator not found",
+			wantErr: true,
+		},
+		{
+			name:    "empty operator",
+			value:   "",
+			set:     true,
+			wantErr: true,
+		},
+		{
+			name:    "wrong type",
+			value:   42,
+			set:     true,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			if tt.set {
+				c.Set(consts.CtxKeyAdminOperator, tt.value)
+			}
+
+			got, err := NewCtxExtractor(c).AdminOperator()
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Empty(t, got)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
 func TestCtxExtractor_BindJSON(t *testing.T) {
 	t.Parallel()
 
@@ -351,3 +408,207 @@ func TestCtxExtractor_Integration(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, uriParams{ItemID: "123"}, gotURI)
 }
+
+func TestCtxExtractor_BindQuery(t *testing.T) {
+	t.Parallel()
+
+	type queryParams struct {
+		At time.Time `form:"at" binding:"required" time_format:"2006-01-02T15:04:05Z07:00"`
+	}
+
+	tests := []struct {
+		want    *queryParams
+		name    string
+		query   string
+		wantErr bool
+	}{
+		{
+			name:  "valid query",
+			query: "?at=2026-10-15T12:00:00Z",
+			want:  &queryParams{At: time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)},
+		},
+		{
+			name:    "missing required parameter",
+			query:   "",
+			wantErr: true,
+		},
+		{
+			name:    "malformed value",
+			query:   "?at=yesterday",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodGet, "/items"+tt.query, http.NoBody)
+
+			var got queryParams
+			err := NewCtxExtractor(c).BindQuery(&got)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "failed to bind query")
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, tt.want.At.Equal(got.At))
+		})
+	}
+}
+
+func TestCtxExtractor_Fields(t *testing.T) {
+	t.Parallel()
+
+	allowed := []string{"id", "login", "updated_at"}
+
+	tests := []struct {
+		name    string
+		query   string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:  "absent",
+			query: "",
+			want:  nil,
+		},
+		{
+			name:  "empty value",
+			query: "?fields=",
+			want:  nil,
+		},
+		{
+			name:  "selected fields",
+			query: "?fields=login,updated_at",
+			want:  []string{"login", "updated_at"},
+		},
+		{
+			name:  "spaces and duplicates",
+			query: "?fields=login,%20login,,id",
+			want:  []string{"login", "id"},
+		},
+		{
+			name:    "unknown field",
+			query:   "?fields=login,password",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodGet, "/items"+tt.query, http.NoBody)
+
+			got, err := NewCtxExtractor(c).Fields(allowed)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "unknown field")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCtxExtractor_Include(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		query   string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:  "absent",
+			query: "",
+			want:  nil,
+		},
+		{
+			name:  "archived",
+			query: "?include=archived,archived",
+			want:  []string{"archived"},
+		},
+		{
+			name:    "unknown name",
+			query:   "?include=deleted",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodGet, "/items"+tt.query, http.NoBody)
+
+			got, err := NewCtxExtractor(c).Include([]string{"archived"})
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "unknown include")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCtxExtractor_Sort(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		query      string
+		want       string
+		wantFolder string
+		wantErr    bool
+	}{
+		{
+			name:  "absent",
+			query: "",
+		},
+		{
+			name:       "manual with folder",
+			query:      "?sort=manual&folder=prod/db",
+			want:       "manual",
+			wantFolder: "prod/db",
+		},
+		{
+			name:    "unknown name",
+			query:   "?sort=name",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodGet, "/items"+tt.query, http.NoBody)
+			extractor := NewCtxExtractor(c)
+
+			got, err := extractor.Sort([]string{"manual"})
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "unknown sort")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantFolder, extractor.Folder())
+		})
+	}
+}
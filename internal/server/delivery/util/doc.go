@@ -1,5 +1,5 @@
 // Package util provides request processing utilities for the AegisVaultKeeper delivery layer.
 //
 // This package implements common utility functions for HTTP request processing,
-// including parameter extraction and request validation helpers.
+// including parameter extraction, request validation helpers and the handler chains of routes.
 package util
@@ -0,0 +1,5 @@
+// Package version provides the build version endpoint for the AegisVaultKeeper server.
+//
+// This package reports the application version to everyone and the full build details, such as the commit,
+// Go version and key dependency versions, to authenticated users only.
+package version
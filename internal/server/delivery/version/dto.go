@@ -0,0 +1,35 @@
+package version
+
+import "github.com/gdyunin/aegis-vault-keeper/internal/server/buildinfo"
+
+// Info represents the build details of the running server; fields other than the version are omitted for
+// unauthenticated callers.
+type Info struct {
+	// Dependencies maps the key dependency modules to their versions.
+	Dependencies map[string]string `json:"dependencies,omitempty" example:"github.com/gin-gonic/gin:v1.10.1"`
+	// Version contains the application version.
+	Version string `json:"version"                example:"v1.4.2"`
+	// Commit contains the Git commit hash of the build.
+	Commit string `json:"commit,omitempty"       example:"0b712a2"`
+	// Date contains the build timestamp.
+	Date string `json:"date,omitempty"         example:"2026-10-15T12:00:00Z"`
+	// GoVersion contains the version of the Go toolchain that built the binary.
+	GoVersion string `json:"go_version,omitempty"   example:"go1.24.4"`
+	// Modified determines whether the build had uncommitted changes.
+	Modified bool `json:"modified,omitempty"     example:"false"`
+}
+
+// NewInfoFromDetails converts the build details to delivery DTO.
+func NewInfoFromDetails(d *buildinfo.Details) *Info {
+	if d == nil {
+		return nil
+	}
+	return &Info{
+		Dependencies: d.Dependencies,
+		Version:      d.Version,
+		Commit:       d.Commit,
+		Date:         d.Date,
+		GoVersion:    d.GoVersion,
+		Modified:     d.Modified,
+	}
+}
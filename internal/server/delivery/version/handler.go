@@ -0,0 +1,49 @@
+package version
+
+import (
+	"net/http"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/buildinfo"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/util"
+	"github.com/gin-gonic/gin"
+)
+
+// BuildDetailsReader provides access to the details of the running build.
+type BuildDetailsReader interface {
+	// Details returns the details of the running build.
+	Details() *buildinfo.Details
+}
+
+// Handler handles HTTP requests for the build version endpoint.
+type Handler struct {
+	// info provides the build details of the application.
+	info BuildDetailsReader
+}
+
+// NewHandler creates a new version handler with the provided build details reader.
+func NewHandler(info BuildDetailsReader) *Handler {
+	return &Handler{info: info}
+}
+
+// Version returns the version of the application, with the full build details for authenticated users.
+// @Summary      Get application version
+// @Description  Returns the application version. Requests with a valid access token also get the commit,
+// @Description  build date, Go version, whether the build had uncommitted changes and the versions of key
+// @Description  dependencies; requests without one, or with an invalid one, get the version only.
+// .
+// @Tags         System
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} Info "Application version"
+// @Router       /version [get]
+// .
+func (h *Handler) Version(c *gin.Context) {
+	c.Header("Vary", "Authorization")
+	details := h.info.Details()
+	if _, err := util.NewCtxExtractor(c).UserID(); err != nil {
+		c.JSON(http.StatusOK, Info{Version: details.Version})
+		return
+	}
+	c.JSON(http.StatusOK, NewInfoFromDetails(details))
+}
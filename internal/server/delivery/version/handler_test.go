@@ -0,0 +1,74 @@
+package version
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/buildinfo"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/consts"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockDetailsReader implements BuildDetailsReader for testing.
+type mockDetailsReader struct {
+	details *buildinfo.Details
+}
+
+func (m *mockDetailsReader) Details() *buildinfo.Details {
+	return m.details
+}
+
+func TestHandler_Version(t *testing.T) {
+	t.Parallel()
+
+	reader := &mockDetailsReader{details: &buildinfo.Details{
+		Dependencies: map[string]string{"github.com/gin-gonic/gin": "v1.10.1"},
+		Version:      "v1.4.2",
+		Commit:       "0b712a2",
+		Date:         "2026-10-15T12:00:00Z",
+		GoVersion:    "go1.24.4",
+		Modified:     true,
+	}}
+
+	tests := []struct {
+		name     string
+		wantBody string
+		setUser  bool
+	}{
+		{
+			name:     "unauthenticated caller",
+			wantBody: `{"version":"v1.4.2"}`,
+		},
+		{
+			name: "authenticated caller",
+			wantBody: `{
+				"dependencies":{"github.com/gin-gonic/gin":"v1.10.1"},"version":"v1.4.2","commit":"0b712a2",
+				"date":"2026-10-15T12:00:00Z","go_version":"go1.24.4","modified":true
+			}`,
+			setUser: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/version", nil)
+			if tt.setUser {
+				c.Set(consts.CtxKeyUserID, uuid.New())
+			}
+
+			NewHandler(reader).Version(c)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.JSONEq(t, tt.wantBody, w.Body.String())
+			assert.Equal(t, "Authorization", w.Header().Get("Vary"))
+		})
+	}
+}
@@ -0,0 +1,8 @@
+package version
+
+import "github.com/gin-gonic/gin"
+
+// RegisterRoutes registers the build version route with the provided router group.
+func RegisterRoutes(r *gin.RouterGroup, h *Handler) {
+	r.GET("/version", h.Version)
+}
@@ -0,0 +1,6 @@
+// Package websession provides HTTP handlers for cookie sessions of web clients in the AegisVaultKeeper server.
+//
+// This package turns an access token into a session kept in an HttpOnly cookie, so a first-party web client does
+// not store the token where its scripts can read it, and hands out the CSRF token the client repeats with
+// requests modifying data. Ending the session clears the cookies.
+package websession
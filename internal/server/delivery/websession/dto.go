@@ -0,0 +1,15 @@
+package websession
+
+// SessionResponse represents a started cookie session.
+type SessionResponse struct {
+	// CSRFToken contains the token to send in the X-CSRF-Token header of requests modifying data.
+	CSRFToken string `json:"csrf_token" example:"Jb0TqL6Z0d3sQm3oBqSx8pNwM2Kx0o3zq7h5b0Ff9nE"`
+}
+
+// SessionSettings describes how web clients keep their sessions.
+type SessionSettings struct {
+	// CSRFCookieName names the cookie web clients read the CSRF token of their session from.
+	CSRFCookieName string `json:"csrf_cookie_name,omitzero" example:"avk_csrf"`
+	// Enabled reports whether cookie sessions are available; without them clients keep the access token.
+	Enabled bool `json:"enabled"                   example:"true"`
+}
@@ -0,0 +1,111 @@
+package websession
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/middleware"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/response"
+	"github.com/gin-gonic/gin"
+)
+
+// cookiePath limits the cookies to the paths of the server, so the web client and the API both receive them.
+const cookiePath = "/"
+
+// Handler handles HTTP requests for cookie session endpoints.
+type Handler struct {
+	// cfg contains the cookie and CSRF token settings.
+	cfg middleware.SessionConfig
+}
+
+// NewHandler creates a new cookie session handler with the provided settings.
+func NewHandler(cfg middleware.SessionConfig) *Handler {
+	return &Handler{cfg: cfg}
+}
+
+// Settings returns whether cookie sessions are available and the name of the CSRF cookie.
+// @Summary      Get cookie session settings
+// @Description  Reports whether web clients may keep their access token in a session cookie and which cookie
+// @Description  holds the CSRF token to repeat in the X-CSRF-Token header.
+// .
+// @Tags         Auth
+// @Produce      json
+// @Success      200 {object} SessionSettings "Cookie session settings"
+// @Router       /auth/session [get]
+// .
+func (h *Handler) Settings(c *gin.Context) {
+	settings := SessionSettings{Enabled: h.cfg.Enabled}
+	if h.cfg.Enabled {
+		settings.CSRFCookieName = h.cfg.CSRFCookieName
+	}
+	c.JSON(http.StatusOK, settings)
+}
+
+// Start starts a cookie session with the access token the request is authenticated with.
+// @Summary      Start cookie session
+// @Description  Stores the access token of the request in an HttpOnly session cookie and returns the CSRF token,
+// @Description  which is also set in a cookie readable by scripts. Later requests authenticate with the cookie
+// @Description  instead of the Authorization header; those modifying data must repeat the CSRF token in the
+// @Description  X-CSRF-Token header. Calling it with the session cookie renews the CSRF cookie.
+// .
+// @Tags         Auth
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} SessionResponse "Session started"
+// @Failure      401 {object} response.Error "Unauthorized - invalid or missing token"
+// @Failure      403 {object} response.Error "Forbidden - CSRF token missing or invalid"
+// @Failure      404 {object} response.Error "Not found - cookie sessions are disabled"
+// @Router       /auth/session [post]
+// .
+func (h *Handler) Start(c *gin.Context) {
+	if !h.cfg.Enabled {
+		c.JSON(http.StatusNotFound, response.Error{Messages: []string{"Cookie sessions are disabled"}})
+		return
+	}
+
+	accessToken := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+	csrfToken := middleware.CSRFToken(h.cfg.Key, accessToken)
+	h.setCookie(c, h.cfg.CookieName, accessToken, true)
+	h.setCookie(c, h.cfg.CSRFCookieName, csrfToken, false)
+
+	c.JSON(http.StatusOK, SessionResponse{CSRFToken: csrfToken})
+}
+
+// End ends the cookie session by clearing its cookies.
+// @Summary      End cookie session
+// @Description  Clears the session and CSRF cookies. The access token itself stays valid until it expires.
+// .
+// @Tags         Auth
+// @Success      204 "Session ended"
+// @Failure      403 {object} response.Error "Forbidden - CSRF token missing or invalid"
+// @Failure      404 {object} response.Error "Not found - cookie sessions are disabled"
+// @Router       /auth/session [delete]
+// .
+func (h *Handler) End(c *gin.Context) {
+	if !h.cfg.Enabled {
+		c.JSON(http.StatusNotFound, response.Error{Messages: []string{"Cookie sessions are disabled"}})
+		return
+	}
+
+	h.setCookie(c, h.cfg.CookieName, "", true)
+	h.setCookie(c, h.cfg.CSRFCookieName, "", false)
+
+	c.Status(http.StatusNoContent)
+}
+
+// setCookie sets a cookie lasting for the browser session; an empty value deletes the cookie.
+func (h *Handler) setCookie(c *gin.Context, name, value string, httpOnly bool) {
+	cookie := &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     cookiePath,
+		Domain:   h.cfg.Domain,
+		Secure:   h.cfg.Secure,
+		HttpOnly: httpOnly,
+		SameSite: h.cfg.SameSite,
+	}
+	if value == "" {
+		cookie.MaxAge = -1
+	}
+	http.SetCookie(c.Writer, cookie)
+}
@@ -0,0 +1,148 @@
+package websession
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testConfig returns enabled cookie session settings.
+func testConfig() middleware.SessionConfig {
+	return middleware.SessionConfig{
+		Key:            []byte("session-key"),
+		CookieName:     "avk_session",
+		CSRFCookieName: "avk_csrf",
+		SameSite:       http.SameSiteStrictMode,
+		Secure:         true,
+		Enabled:        true,
+	}
+}
+
+func TestHandler_Settings(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		cfg      middleware.SessionConfig
+		name     string
+		wantBody string
+	}{
+		{name: "sessions enabled", cfg: testConfig(), wantBody: `{"csrf_cookie_name":"avk_csrf","enabled":true}`},
+		{
+			name:     "sessions disabled",
+			cfg:      middleware.SessionConfig{CSRFCookieName: "avk_csrf"},
+			wantBody: `{"enabled":false}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			router := gin.New()
+			router.GET("/auth/session", NewHandler(tt.cfg).Settings)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/auth/session", nil))
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.JSONEq(t, tt.wantBody, rec.Body.String())
+		})
+	}
+}
+
+func TestHandler_Start(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		cfg        middleware.SessionConfig
+		name       string
+		wantStatus int
+	}{
+		{name: "session started", cfg: testConfig(), wantStatus: http.StatusOK},
+		{name: "sessions disabled", cfg: middleware.SessionConfig{}, wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			router := gin.New()
+			router.POST("/auth/session", NewHandler(tt.cfg).Start)
+			req := httptest.NewRequest(http.MethodPost, "/auth/session", nil)
+			req.Header.Set("Authorization", "Bearer access-token")
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			require.Equal(t, tt.wantStatus, rec.Code)
+			if tt.wantStatus != http.StatusOK {
+				assert.Empty(t, rec.Result().Cookies())
+				return
+			}
+			// resp holds the decoded session.
+			var resp SessionResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+			assert.Equal(t, middleware.CSRFToken(tt.cfg.Key, "access-token"), resp.CSRFToken)
+
+			cookies := rec.Result().Cookies()
+			require.Len(t, cookies, 2)
+			assert.Equal(t, "avk_session", cookies[0].Name)
+			assert.Equal(t, "access-token", cookies[0].Value)
+			assert.True(t, cookies[0].HttpOnly)
+			assert.Equal(t, "avk_csrf", cookies[1].Name)
+			assert.Equal(t, resp.CSRFToken, cookies[1].Value)
+			assert.False(t, cookies[1].HttpOnly)
+			for _, cookie := range cookies {
+				assert.True(t, cookie.Secure)
+				assert.Equal(t, http.SameSiteStrictMode, cookie.SameSite)
+				assert.Equal(t, "/", cookie.Path)
+				assert.Zero(t, cookie.MaxAge, "cookies last for the browser session")
+			}
+		})
+	}
+}
+
+func TestHandler_End(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		cfg        middleware.SessionConfig
+		name       string
+		wantStatus int
+	}{
+		{name: "session ended", cfg: testConfig(), wantStatus: http.StatusNoContent},
+		{name: "sessions disabled", cfg: middleware.SessionConfig{}, wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			router := gin.New()
+			router.DELETE("/auth/session", NewHandler(tt.cfg).End)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/auth/session", nil))
+
+			require.Equal(t, tt.wantStatus, rec.Code)
+			if tt.wantStatus != http.StatusNoContent {
+				return
+			}
+			cookies := rec.Result().Cookies()
+			require.Len(t, cookies, 2)
+			for _, cookie := range cookies {
+				assert.Empty(t, cookie.Value)
+				assert.Negative(t, cookie.MaxAge)
+			}
+		})
+	}
+}
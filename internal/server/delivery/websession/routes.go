@@ -0,0 +1,15 @@
+package websession
+
+import (
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/util"
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterRoutes registers the cookie session endpoints /auth/session on the provided router group. Starting a
+// session runs the authenticated middleware first; reading the settings and ending a session need no valid token,
+// so web clients can check the settings before logging in and expired sessions end too.
+func RegisterRoutes(r *gin.RouterGroup, h *Handler, authenticated ...gin.HandlerFunc) {
+	r.GET("/auth/session", h.Settings)
+	r.POST("/auth/session", util.Chain(authenticated, h.Start)...)
+	r.DELETE("/auth/session", h.End)
+}
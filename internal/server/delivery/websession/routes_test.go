@@ -0,0 +1,29 @@
+package websession
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterRoutes(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	RegisterRoutes(router.Group("/api"), NewHandler(middleware.SessionConfig{}))
+
+	routes := router.Routes()
+	assert.Len(t, routes, 3)
+	// methods maps the registered paths to their methods.
+	methods := make(map[string]string)
+	for _, r := range routes {
+		methods[r.Method] = r.Path
+	}
+	assert.Equal(t, "/api/auth/session", methods[http.MethodGet])
+	assert.Equal(t, "/api/auth/session", methods[http.MethodPost])
+	assert.Equal(t, "/api/auth/session", methods[http.MethodDelete])
+}
@@ -0,0 +1,477 @@
+"use strict";
+
+// kinds describes the vault item types: their API routes, the response properties holding them and their fields.
+const kinds = {
+  credentials: {
+    path: "/api/items/credentials",
+    one: "credential",
+    many: "credentials",
+    fields: [
+      { name: "login", label: "Login", required: true },
+      { name: "password", label: "Password", required: true, secret: true },
+      { name: "description", label: "Description" },
+    ],
+    // URIs are not edited here, so updates send them back unchanged.
+    keep: ["uris"],
+  },
+  bankcards: {
+    path: "/api/items/bankcards",
+    one: "bankcard",
+    many: "bankcards",
+    fields: [
+      { name: "card_number", label: "Card number", required: true, secret: true },
+      { name: "card_holder", label: "Card holder", required: true },
+      { name: "expiry_month", label: "Expiry month", required: true },
+      { name: "expiry_year", label: "Expiry year", required: true },
+      { name: "cvv", label: "CVV", required: true, secret: true },
+      { name: "description", label: "Description" },
+    ],
+    keep: [],
+  },
+  notes: {
+    path: "/api/items/notes",
+    one: "note",
+    many: "notes",
+    fields: [
+      { name: "note", label: "Note", required: true, multiline: true },
+      { name: "description", label: "Description" },
+    ],
+    keep: [],
+  },
+  files: {
+    path: "/api/items/filedata/",
+    many: "files",
+    fields: [
+      { name: "storage_key", label: "Name" },
+      { name: "folder", label: "Folder" },
+      { name: "description", label: "Description" },
+      { name: "size", label: "Size (bytes)" },
+      { name: "hash_sum", label: "SHA-256" },
+    ],
+  },
+};
+
+// state holds the session of the page.
+const state = {
+  // token is the access token when cookie sessions are unavailable; it is never stored outside the page.
+  token: "",
+  // sessions reports whether the server keeps the access token in a session cookie.
+  sessions: false,
+  // csrfCookie names the cookie holding the CSRF token of the session.
+  csrfCookie: "",
+  // challengeID identifies the sign-in waiting for confirmation.
+  challengeID: "",
+  // kind is the item type shown.
+  kind: "credentials",
+  // items lists the items of the shown type.
+  items: [],
+  // selected is the ID of the shown item.
+  selected: "",
+};
+
+const $ = (id) => document.getElementById(id);
+
+// show reports a message to the user; errors are highlighted.
+function show(message, isError = false) {
+  const status = $("status");
+  status.textContent = message;
+  status.classList.toggle("error", isError);
+  status.hidden = message === "";
+}
+
+// csrfToken returns the CSRF token of the cookie session, or an empty string without one.
+function csrfToken() {
+  if (!state.csrfCookie) {
+    return "";
+  }
+  for (const cookie of document.cookie.split(";")) {
+    const [name, ...value] = cookie.trim().split("=");
+    if (name === state.csrfCookie) {
+      return value.join("=");
+    }
+  }
+  return "";
+}
+
+// request calls the API; JSON bodies are encoded, form data is sent as is.
+async function request(method, path, body) {
+  const headers = {};
+  if (state.token) {
+    headers.Authorization = "Bearer " + state.token;
+  }
+  const csrf = csrfToken();
+  if (csrf) {
+    headers["X-CSRF-Token"] = csrf;
+  }
+  let payload = body;
+  if (body !== undefined && !(body instanceof FormData)) {
+    headers["Content-Type"] = "application/json";
+    payload = JSON.stringify(body);
+  }
+  return fetch(path, { method, headers, body: payload, credentials: "same-origin", cache: "no-store" });
+}
+
+// failure turns an unsuccessful response into an error carrying the messages of the server.
+async function failure(resp) {
+  if (resp.status === 401 && !$("vault-view").hidden) {
+    signedOut();
+    return new Error("Your session has ended. Sign in again.");
+  }
+  try {
+    const body = await resp.json();
+    if (Array.isArray(body.messages) && body.messages.length > 0) {
+      return new Error(body.messages.join(" "));
+    }
+  } catch {
+    // The body is not an error response; the status is reported instead.
+  }
+  return new Error("The request failed with status " + resp.status + ".");
+}
+
+// call calls the API and returns the decoded response; empty responses return null.
+async function call(method, path, body) {
+  const resp = await request(method, path, body);
+  if (!resp.ok) {
+    throw await failure(resp);
+  }
+  return resp.status === 204 ? null : resp.json();
+}
+
+// guarded runs an action started by the user and reports its failure.
+function guarded(action) {
+  return async (event) => {
+    event?.preventDefault();
+    try {
+      await action(event);
+    } catch (err) {
+      show(err.message, true);
+    }
+  };
+}
+
+// signIn keeps the access token of a completed sign-in, in a session cookie when the server offers one.
+async function signIn(accessToken) {
+  state.token = accessToken;
+  if (state.sessions) {
+    await call("POST", "/api/auth/session");
+    // Browsers drop secure cookies received over plain HTTP; the token is then kept in the page instead.
+    if (csrfToken()) {
+      state.token = "";
+    }
+  }
+  $("sign-in-form").reset();
+  $("verify-form").reset();
+  await openVault();
+}
+
+// signedOut forgets the session and shows the sign-in form.
+function signedOut() {
+  state.token = "";
+  state.challengeID = "";
+  $("vault-view").hidden = true;
+  $("sign-out").hidden = true;
+  $("verify-form").hidden = true;
+  $("sign-in-form").hidden = false;
+  $("sign-in-view").hidden = false;
+}
+
+// openVault shows the vault with the items of the current type.
+async function openVault() {
+  $("sign-in-view").hidden = true;
+  $("vault-view").hidden = false;
+  $("sign-out").hidden = false;
+  show("");
+  await selectKind(state.kind);
+}
+
+// selectKind lists the items of a type.
+async function selectKind(kind) {
+  state.kind = kind;
+  state.selected = "";
+  for (const tab of $("tabs").querySelectorAll("button")) {
+    if (tab.dataset.kind === kind) {
+      tab.setAttribute("aria-current", "page");
+    } else {
+      tab.removeAttribute("aria-current");
+    }
+  }
+  $("detail-pane").hidden = true;
+  const body = await call("GET", kinds[kind].path);
+  state.items = body?.[kinds[kind].many] ?? [];
+  renderList();
+}
+
+// label returns the text an item is listed with.
+function label(item) {
+  return item.description || item.storage_key || item.login || item.card_holder || item.id;
+}
+
+// renderList lists the items of the current type.
+function renderList() {
+  const list = $("item-list");
+  list.replaceChildren();
+  if (state.items.length === 0) {
+    const empty = document.createElement("li");
+    empty.className = "empty";
+    empty.textContent = "No items yet.";
+    list.append(empty);
+    return;
+  }
+  for (const item of state.items) {
+    const open = button(label(item), guarded(() => showItem(item.id)));
+    if (item.id === state.selected) {
+      open.setAttribute("aria-current", "true");
+    }
+    const entry = document.createElement("li");
+    entry.append(open);
+    list.append(entry);
+  }
+}
+
+// fetchItem returns an item with its secrets; file contents are not downloaded.
+async function fetchItem(id) {
+  const kind = kinds[state.kind];
+  if (!kind.one) {
+    return state.items.find((item) => item.id === id);
+  }
+  const body = await call("GET", kind.path + "/" + encodeURIComponent(id));
+  return body[kind.one];
+}
+
+// showItem shows an item, with its secrets hidden until revealed.
+async function showItem(id) {
+  const item = await fetchItem(id);
+  state.selected = id;
+  renderList();
+
+  const pane = $("detail-pane");
+  pane.replaceChildren();
+  const title = document.createElement("h2");
+  title.textContent = label(item);
+  const fields = document.createElement("dl");
+  for (const field of kinds[state.kind].fields) {
+    const value = String(item[field.name] ?? "");
+    if (value === "") {
+      continue;
+    }
+    const name = document.createElement("dt");
+    name.textContent = field.label;
+    const content = document.createElement("dd");
+    const text = document.createElement("span");
+    text.textContent = field.secret ? "••••••••" : value;
+    content.append(text);
+    if (field.secret) {
+      content.append(button("Show", () => {
+        text.textContent = text.textContent === value ? "••••••••" : value;
+      }));
+      content.append(button("Copy", guarded(() => navigator.clipboard.writeText(value))));
+    }
+    fields.append(name, content);
+  }
+  if (item.updated_at) {
+    const name = document.createElement("dt");
+    name.textContent = "Updated";
+    const content = document.createElement("dd");
+    content.textContent = new Date(item.updated_at).toLocaleString();
+    fields.append(name, content);
+  }
+
+  const actions = document.createElement("div");
+  actions.className = "actions";
+  if (state.kind === "files") {
+    actions.append(button("Download", guarded(() => download(item))));
+    actions.append(button("Upload new version", () => showUploadForm(item)));
+  } else {
+    actions.append(button("Edit", () => showForm(item)));
+  }
+  pane.append(title, fields, actions);
+  pane.hidden = false;
+}
+
+// button creates a button running the handler on click.
+function button(text, handler) {
+  const b = document.createElement("button");
+  b.type = "button";
+  b.textContent = text;
+  b.addEventListener("click", handler);
+  return b;
+}
+
+// input creates a labeled form field.
+function input(field, value) {
+  const wrapper = document.createElement("label");
+  wrapper.textContent = field.label + " ";
+  const control = document.createElement(field.multiline ? "textarea" : "input");
+  control.name = field.name;
+  control.value = value ?? "";
+  control.required = Boolean(field.required);
+  if (field.secret) {
+    control.autocomplete = "off";
+  }
+  wrapper.append(control);
+  return wrapper;
+}
+
+// showForm shows the form creating an item, or editing the given one.
+function showForm(item) {
+  const kind = kinds[state.kind];
+  const form = document.createElement("form");
+  const title = document.createElement("h2");
+  title.textContent = item ? "Edit " + label(item) : "New item";
+  form.append(title);
+  for (const field of kind.fields) {
+    form.append(input(field, item?.[field.name]));
+  }
+  const save = document.createElement("button");
+  save.type = "submit";
+  save.textContent = "Save";
+  const actions = document.createElement("div");
+  actions.className = "actions";
+  actions.append(save, button("Cancel", guarded(() => (item ? showItem(item.id) : closeDetail()))));
+  form.append(actions);
+
+  form.addEventListener("submit", guarded(async () => {
+    const body = Object.fromEntries(new FormData(form));
+    for (const name of kind.keep) {
+      if (item?.[name] !== undefined) {
+        body[name] = item[name];
+      }
+    }
+    const saved = item
+      ? await call("PUT", kind.path + "/" + encodeURIComponent(item.id), body)
+      : await call("POST", kind.path, body);
+    show("Saved.");
+    await selectKind(state.kind);
+    await showItem(saved.id);
+  }));
+  openDetail(form);
+}
+
+// showUploadForm shows the form uploading a file, or a new version of the given one.
+function showUploadForm(file) {
+  const form = document.createElement("form");
+  const title = document.createElement("h2");
+  title.textContent = file ? "Upload new version of " + label(file) : "Upload file";
+  const chooser = document.createElement("label");
+  chooser.textContent = "File ";
+  const control = document.createElement("input");
+  control.type = "file";
+  control.name = "file";
+  control.required = true;
+  chooser.append(control);
+  form.append(title, chooser);
+  if (!file) {
+    form.append(input({ name: "folder", label: "Folder" }));
+  }
+  form.append(input({ name: "description", label: "Description" }, file?.description));
+  const upload = document.createElement("button");
+  upload.type = "submit";
+  upload.textContent = "Upload";
+  const actions = document.createElement("div");
+  actions.className = "actions";
+  actions.append(upload, button("Cancel", guarded(() => (file ? showItem(file.id) : closeDetail()))));
+  form.append(actions);
+
+  form.addEventListener("submit", guarded(async () => {
+    const data = new FormData(form);
+    // New files are stored under their own name; new versions keep the name of the file they replace.
+    data.set("storage_key", file ? file.storage_key : control.files[0].name);
+    show("Uploading…");
+    const saved = file
+      ? await call("PUT", kinds.files.path + encodeURIComponent(file.id), data)
+      : await call("POST", kinds.files.path, data);
+    show("Uploaded.");
+    await selectKind("files");
+    await showItem(saved.id);
+  }));
+  openDetail(form);
+}
+
+// download saves the decrypted content of a file.
+async function download(file) {
+  const resp = await request("GET", kinds.files.path + encodeURIComponent(file.id));
+  if (!resp.ok) {
+    throw await failure(resp);
+  }
+  // Files are sent as multipart form data, with their metadata before their content.
+  const parts = await resp.formData();
+  const url = URL.createObjectURL(parts.get("file"));
+  const link = document.createElement("a");
+  link.href = url;
+  link.download = file.storage_key || "download";
+  link.click();
+  URL.revokeObjectURL(url);
+}
+
+// openDetail shows content in the detail pane.
+function openDetail(content) {
+  const pane = $("detail-pane");
+  pane.replaceChildren(content);
+  pane.hidden = false;
+  content.querySelector("input, textarea")?.focus();
+}
+
+// closeDetail empties the detail pane.
+function closeDetail() {
+  state.selected = "";
+  $("detail-pane").hidden = true;
+  renderList();
+}
+
+// start reads the session settings and opens the vault when a session is still valid.
+async function start() {
+  const settings = await call("GET", "/api/auth/session");
+  state.sessions = settings.enabled;
+  state.csrfCookie = settings.csrf_cookie_name ?? "";
+
+  $("sign-in-form").addEventListener("submit", guarded(async (event) => {
+    const form = new FormData(event.target);
+    const resp = await request("POST", "/api/auth/login", { login: form.get("login"), password: form.get("password") });
+    if (!resp.ok) {
+      throw await failure(resp);
+    }
+    const body = await resp.json();
+    // Sign-ins from unrecognized devices or locations are held until confirmed with a code.
+    if (resp.status === 202) {
+      state.challengeID = body.challenge_id;
+      $("sign-in-form").hidden = true;
+      $("verify-form").hidden = false;
+      show("");
+      return;
+    }
+    await signIn(body.access_token);
+  }));
+  $("verify-form").addEventListener("submit", guarded(async (event) => {
+    const code = new FormData(event.target).get("code").trim();
+    // Recovery codes are grouped with dashes; verification codes are digits only.
+    const body = code.includes("-")
+      ? { challenge_id: state.challengeID, recovery_code: code }
+      : { challenge_id: state.challengeID, code };
+    const token = await call("POST", "/api/auth/login/verify", body);
+    await signIn(token.access_token);
+  }));
+  $("sign-out").addEventListener("click", guarded(async () => {
+    if (state.sessions) {
+      await call("DELETE", "/api/auth/session");
+    }
+    signedOut();
+    show("Signed out.");
+  }));
+  for (const tab of $("tabs").querySelectorAll("button")) {
+    tab.addEventListener("click", guarded(() => selectKind(tab.dataset.kind)));
+  }
+  $("new-item").addEventListener("click", () => (state.kind === "files" ? showUploadForm() : showForm()));
+
+  if (csrfToken()) {
+    try {
+      await openVault();
+      return;
+    } catch {
+      // The session has expired; the user signs in again.
+      show("");
+    }
+  }
+  signedOut();
+}
+
+document.addEventListener("DOMContentLoaded", guarded(start));
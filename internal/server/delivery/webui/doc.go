@@ -0,0 +1,7 @@
+// Package webui serves the first-party web vault UI embedded in the AegisVaultKeeper server binary.
+//
+// The UI is a set of static files calling the REST API under "/api" from the browser: it signs users in, lists,
+// shows, creates and edits credentials, bank cards and notes, and lists, uploads and downloads files. With cookie
+// sessions enabled it keeps the access token in the HttpOnly session cookie; otherwise the token lives only in
+// the memory of the page, so reloading the page signs the user out.
+package webui
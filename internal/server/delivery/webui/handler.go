@@ -0,0 +1,43 @@
+package webui
+
+import (
+	"embed"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// assetsDir is the directory of the embedded files of the web UI.
+const assetsDir = "assets"
+
+// indexPath is the path the web UI is opened at.
+const indexPath = "/ui/"
+
+// assets holds the files of the web UI.
+//
+//go:embed assets
+var assets embed.FS
+
+// Handler handles HTTP requests for the web UI.
+type Handler struct {
+	// files serves the embedded files of the web UI.
+	files http.Handler
+}
+
+// NewHandler creates a new web UI handler serving the embedded files.
+func NewHandler() *Handler {
+	return &Handler{files: http.FileServerFS(assets)}
+}
+
+// Asset serves the embedded file named by the filepath parameter; directories serve their index.html.
+// The files change only with the binary, so browsers revalidate them instead of caching them.
+func (h *Handler) Asset(c *gin.Context) {
+	c.Header("Cache-Control", "no-cache")
+	c.Request.URL.Path = "/" + assetsDir + c.Param("filepath")
+	h.files.ServeHTTP(c.Writer, c.Request)
+}
+
+// Root redirects the root of the domain to the web UI.
+func (h *Handler) Root(c *gin.Context) {
+	c.Redirect(http.StatusFound, indexPath)
+}
@@ -0,0 +1,84 @@
+package webui
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_Asset(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name            string
+		path            string
+		wantContentType string
+		wantBody        string
+		wantLocation    string
+		wantStatus      int
+	}{
+		{
+			name:            "index",
+			path:            "/ui/",
+			wantStatus:      http.StatusOK,
+			wantContentType: "text/html; charset=utf-8",
+			wantBody:        "<title>AegisVaultKeeper</title>",
+		},
+		{
+			name:            "script",
+			path:            "/ui/app.js",
+			wantStatus:      http.StatusOK,
+			wantContentType: "text/javascript; charset=utf-8",
+			wantBody:        `"/api/auth/session"`,
+		},
+		{
+			name:            "stylesheet",
+			path:            "/ui/app.css",
+			wantStatus:      http.StatusOK,
+			wantContentType: "text/css; charset=utf-8",
+		},
+		{name: "index by name", path: "/ui/index.html", wantStatus: http.StatusMovedPermanently, wantLocation: "./"},
+		{name: "missing file", path: "/ui/missing.js", wantStatus: http.StatusNotFound},
+		{name: "path outside assets", path: "/ui/../handler.go", wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			router := gin.New()
+			router.GET("/ui/*filepath", NewHandler().Asset)
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.URL.Path = tt.path
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			if tt.wantStatus == http.StatusOK {
+				assert.Equal(t, "no-cache", rec.Header().Get("Cache-Control"))
+				assert.Equal(t, tt.wantContentType, rec.Header().Get("Content-Type"))
+			}
+			assert.Contains(t, rec.Body.String(), tt.wantBody)
+			assert.Equal(t, tt.wantLocation, rec.Header().Get("Location"))
+		})
+	}
+}
+
+func TestHandler_Root(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/", NewHandler().Root)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusFound, rec.Code)
+	assert.Equal(t, "/ui/", rec.Header().Get("Location"))
+}
@@ -0,0 +1,11 @@
+package webui
+
+import "github.com/gin-gonic/gin"
+
+// RegisterRoutes registers the web UI under /ui and the redirect of the root path to it on the provided router
+// group. The group must not add a prefix, since the UI calls the API under "/api".
+func RegisterRoutes(r *gin.RouterGroup, h *Handler) {
+	r.GET("/", h.Root)
+	r.GET("/ui/*filepath", h.Asset)
+	r.HEAD("/ui/*filepath", h.Asset)
+}
@@ -0,0 +1,28 @@
+package webui
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterRoutes(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	RegisterRoutes(router.Group(""), NewHandler())
+
+	routes := router.Routes()
+	assert.Len(t, routes, 3)
+	// paths maps the registered methods and paths to whether they are registered.
+	paths := make(map[string]bool)
+	for _, r := range routes {
+		paths[r.Method+" "+r.Path] = true
+	}
+	assert.True(t, paths[http.MethodGet+" /"])
+	assert.True(t, paths[http.MethodGet+" /ui/*filepath"])
+	assert.True(t, paths[http.MethodHead+" /ui/*filepath"])
+}
@@ -0,0 +1,7 @@
+// Package wellknown provides the app association endpoints for the AegisVaultKeeper server.
+//
+// This package serves the Digital Asset Links statement list of Android and the apple-app-site-association
+// file of iOS under /.well-known, generated from configuration, so native autofill services can offer the
+// credentials of a self-hosted instance in the associated apps. It also publishes the public key clients
+// verify signed responses with.
+package wellknown
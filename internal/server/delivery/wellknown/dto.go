@@ -0,0 +1,76 @@
+package wellknown
+
+// AndroidApp describes an Android app associated with the server.
+type AndroidApp struct {
+	// PackageName contains the application ID of the app.
+	PackageName string
+	// Fingerprints lists the SHA-256 fingerprints of the signing certificates of the app.
+	Fingerprints []string
+}
+
+// Associations describes the native apps associated with the server.
+type Associations struct {
+	// AndroidApps lists the associated Android apps.
+	AndroidApps []AndroidApp
+	// IOSApps lists the app IDs, TEAMID.bundle.id, of the associated iOS apps.
+	IOSApps []string
+}
+
+// ResponseSigningKey represents the public key the responses of critical endpoints are signed with.
+type ResponseSigningKey struct {
+	// KeyID contains the identifier of the key sent in the X-Response-Signature header.
+	KeyID string `json:"key_id"                           example:"3f2a9c01b7d4e588"`
+	// Algorithm contains the signature algorithm, always Ed25519.
+	Algorithm string `json:"alg"                              example:"Ed25519"`
+	// PublicKey contains the base64-encoded Ed25519 public key.
+	PublicKey []byte `json:"public_key" swaggertype:"string" example:"11qYAYKxCrfVS/7TyWQHOg7hcvPapiMlrwIaaPcHURo="`
+}
+
+// relationGetLoginCreds is the Digital Asset Links relation allowing an app to use the credentials of a site.
+const relationGetLoginCreds = "delegate_permission/common.get_login_creds"
+
+// AssetLink represents a Digital Asset Links statement of assetlinks.json.
+type AssetLink struct {
+	// Target identifies the associated app.
+	Target AssetLinkTarget `json:"target"`
+	// Relation lists the permissions granted to the app.
+	Relation []string `json:"relation" example:"delegate_permission/common.get_login_creds"`
+}
+
+// AssetLinkTarget represents the app a Digital Asset Links statement is about.
+type AssetLinkTarget struct {
+	// Namespace contains the kind of the target, always android_app.
+	Namespace string `json:"namespace"                example:"android_app"`
+	// PackageName contains the application ID of the app.
+	PackageName string `json:"package_name"             example:"com.example.vault"`
+	// SHA256CertFingerprints lists the fingerprints of the signing certificates of the app.
+	SHA256CertFingerprints []string `json:"sha256_cert_fingerprints"`
+}
+
+// AppSiteAssociation represents the apple-app-site-association file.
+type AppSiteAssociation struct {
+	// WebCredentials lists the apps allowed to use the credentials of the site.
+	WebCredentials WebCredentials `json:"webcredentials"`
+}
+
+// WebCredentials represents the webcredentials service of the apple-app-site-association file.
+type WebCredentials struct {
+	// Apps lists the app IDs of the associated apps.
+	Apps []string `json:"apps" example:"ABCDE12345.com.example.vault"`
+}
+
+// newAssetLinks creates the Digital Asset Links statements of the Android apps.
+func newAssetLinks(apps []AndroidApp) []AssetLink {
+	links := make([]AssetLink, 0, len(apps))
+	for _, app := range apps {
+		links = append(links, AssetLink{
+			Relation: []string{relationGetLoginCreds},
+			Target: AssetLinkTarget{
+				Namespace:              "android_app",
+				PackageName:            app.PackageName,
+				SHA256CertFingerprints: app.Fingerprints,
+			},
+		})
+	}
+	return links
+}
@@ -0,0 +1,100 @@
+package wellknown
+
+import (
+	"crypto/ed25519"
+	"net/http"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/crypto"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/response"
+	"github.com/gin-gonic/gin"
+)
+
+// notConfiguredError is returned for the association files of platforms without associated apps.
+var notConfiguredError = response.Error{Messages: []string{"No apps are associated for this platform"}}
+
+// unsignedError is returned for the response signing key when responses are not signed.
+var unsignedError = response.Error{Messages: []string{"Responses of this server are not signed"}}
+
+// Handler handles HTTP requests for the app association endpoints.
+type Handler struct {
+	// assetLinks holds the Digital Asset Links statements of the associated Android apps.
+	assetLinks []AssetLink
+	// responseKey holds the response signing key; nil when responses are not signed.
+	responseKey *ResponseSigningKey
+	// appSiteAssociation holds the apple-app-site-association file of the associated iOS apps.
+	appSiteAssociation AppSiteAssociation
+}
+
+// NewHandler creates a new well-known handler serving the files of the associated apps and the public key
+// responses are signed with; a nil key reports that responses are not signed.
+func NewHandler(a Associations, responseKey ed25519.PublicKey) *Handler {
+	h := &Handler{
+		assetLinks:         newAssetLinks(a.AndroidApps),
+		appSiteAssociation: AppSiteAssociation{WebCredentials: WebCredentials{Apps: a.IOSApps}},
+	}
+	if responseKey != nil {
+		h.responseKey = &ResponseSigningKey{
+			KeyID:     crypto.Ed25519KeyID(responseKey),
+			Algorithm: "Ed25519",
+			PublicKey: responseKey,
+		}
+	}
+	return h
+}
+
+// AssetLinks returns the Digital Asset Links statements of the associated Android apps.
+// @Summary      Get Android asset links
+// @Description  Returns the Digital Asset Links statements granting the configured Android apps the
+// @Description  get_login_creds relation, so Android autofill offers the credentials of this server in them
+// .
+// @Tags         System
+// @Produce      json
+// @Success      200 {array} AssetLink "Asset links statements"
+// @Failure      404 {object} response.Error "No Android apps are associated"
+// @Router       /.well-known/assetlinks.json [get]
+// .
+func (h *Handler) AssetLinks(c *gin.Context) {
+	if len(h.assetLinks) == 0 {
+		c.JSON(http.StatusNotFound, notConfiguredError)
+		return
+	}
+	c.JSON(http.StatusOK, h.assetLinks)
+}
+
+// AppleAppSiteAssociation returns the apple-app-site-association file of the associated iOS apps.
+// @Summary      Get Apple app site association
+// @Description  Returns the apple-app-site-association file listing the configured iOS apps under
+// @Description  webcredentials, so iOS Password AutoFill offers the credentials of this server in them
+// .
+// @Tags         System
+// @Produce      json
+// @Success      200 {object} AppSiteAssociation "App site association"
+// @Failure      404 {object} response.Error "No iOS apps are associated"
+// @Router       /.well-known/apple-app-site-association [get]
+// .
+func (h *Handler) AppleAppSiteAssociation(c *gin.Context) {
+	if len(h.appSiteAssociation.WebCredentials.Apps) == 0 {
+		c.JSON(http.StatusNotFound, notConfiguredError)
+		return
+	}
+	c.JSON(http.StatusOK, h.appSiteAssociation)
+}
+
+// ResponseSigningKey returns the public key the responses of critical endpoints are signed with.
+// @Summary      Get response signing key
+// @Description  Returns the Ed25519 public key vault exports, sync manifests and recovery codes are signed with,
+// @Description  so clients can verify the X-Response-Signature header of those responses
+// .
+// @Tags         System
+// @Produce      json
+// @Success      200 {object} ResponseSigningKey "Response signing key"
+// @Failure      404 {object} response.Error "Responses are not signed"
+// @Router       /.well-known/response-signing-key [get]
+// .
+func (h *Handler) ResponseSigningKey(c *gin.Context) {
+	if h.responseKey == nil {
+		c.JSON(http.StatusNotFound, unsignedError)
+		return
+	}
+	c.JSON(http.StatusOK, h.responseKey)
+}
@@ -0,0 +1,142 @@
+package wellknown
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/crypto"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_AssetLinks(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		wantBody       string
+		associations   Associations
+		expectedStatus int
+	}{
+		{
+			name: "associated apps",
+			associations: Associations{AndroidApps: []AndroidApp{
+				{PackageName: "com.example.vault", Fingerprints: []string{"AB:CD", "EF:01"}},
+			}},
+			wantBody: `[{"target":{"namespace":"android_app","package_name":"com.example.vault",` +
+				`"sha256_cert_fingerprints":["AB:CD","EF:01"]},` +
+				`"relation":["delegate_permission/common.get_login_creds"]}]`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "no associated apps",
+			associations:   Associations{IOSApps: []string{"ABCDE12345.com.example.vault"}},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/.well-known/assetlinks.json", nil)
+
+			NewHandler(tt.associations, nil).AssetLinks(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.wantBody != "" {
+				assert.JSONEq(t, tt.wantBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandler_AppleAppSiteAssociation(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		associations   Associations
+		expectedStatus int
+	}{
+		{
+			name:           "associated apps",
+			associations:   Associations{IOSApps: []string{"ABCDE12345.com.example.vault"}},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "no associated apps",
+			associations:   Associations{AndroidApps: []AndroidApp{{PackageName: "com.example.vault"}}},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/.well-known/apple-app-site-association", nil)
+
+			NewHandler(tt.associations, nil).AppleAppSiteAssociation(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+			var got AppSiteAssociation
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+			assert.Equal(t, tt.associations.IOSApps, got.WebCredentials.Apps)
+			assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+		})
+	}
+}
+
+func TestHandler_ResponseSigningKey(t *testing.T) {
+	t.Parallel()
+
+	public := ed25519.NewKeyFromSeed(bytes.Repeat([]byte{0x03}, ed25519.SeedSize)).Public().(ed25519.PublicKey)
+
+	tests := []struct {
+		key            ed25519.PublicKey
+		name           string
+		expectedStatus int
+	}{
+		{name: "signed responses", key: public, expectedStatus: http.StatusOK},
+		{name: "unsigned responses", expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/.well-known/response-signing-key", nil)
+
+			NewHandler(Associations{}, tt.key).ResponseSigningKey(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.key == nil {
+				return
+			}
+			// got holds the decoded key.
+			var got ResponseSigningKey
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+			assert.Equal(t, crypto.Ed25519KeyID(public), got.KeyID)
+			assert.Equal(t, "Ed25519", got.Algorithm)
+			assert.Equal(t, []byte(public), got.PublicKey)
+		})
+	}
+}
@@ -0,0 +1,13 @@
+package wellknown
+
+import "github.com/gin-gonic/gin"
+
+// RegisterRoutes registers the app association and response signing key routes under /.well-known with the
+// provided router group. Apple and Google fetch the files from the root of the domain, so the group must not add
+// a prefix.
+func RegisterRoutes(r *gin.RouterGroup, h *Handler) {
+	wellKnownGroup := r.Group("/.well-known")
+	wellKnownGroup.GET("/assetlinks.json", h.AssetLinks)
+	wellKnownGroup.GET("/apple-app-site-association", h.AppleAppSiteAssociation)
+	wellKnownGroup.GET("/response-signing-key", h.ResponseSigningKey)
+}
@@ -0,0 +1,34 @@
+package wellknown
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterRoutes(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	RegisterRoutes(router.Group(""), NewHandler(Associations{}, nil))
+
+	expectedRoutes := []string{
+		"/.well-known/assetlinks.json",
+		"/.well-known/apple-app-site-association",
+		"/.well-known/response-signing-key",
+	}
+	for _, path := range expectedRoutes {
+		found := false
+		for _, route := range router.Routes() {
+			if route.Method == http.MethodGet && route.Path == path {
+				found = true
+				break
+			}
+		}
+		assert.True(t, found, "Expected route GET %s not found", path)
+	}
+	assert.Len(t, router.Routes(), len(expectedRoutes))
+}
@@ -0,0 +1,6 @@
+// Package accesspolicy provides time-based access policy domain entities and business rules
+// for the AegisVaultKeeper server.
+//
+// This package implements core domain logic for vault availability windows, defining the Policy
+// entity, the weekdays and time of day it covers, and how a set of policies admits a moment.
+package accesspolicy
@@ -0,0 +1,21 @@
+package accesspolicy
+
+import "errors"
+
+// Access policy domain error definitions.
+var (
+	// ErrNewPolicyParamsValidation indicates that access policy creation parameters failed validation.
+	ErrNewPolicyParamsValidation = errors.New("new access policy parameters validation failed")
+
+	// ErrIncorrectDays indicates that the policy lists no or unknown weekdays.
+	ErrIncorrectDays = errors.New("incorrect access policy days")
+
+	// ErrIncorrectTime indicates that the window start or end is not a valid HH:MM time of day.
+	ErrIncorrectTime = errors.New("incorrect access policy time")
+
+	// ErrIncorrectTimezone indicates that the policy time zone is not a known IANA time zone.
+	ErrIncorrectTimezone = errors.New("incorrect access policy timezone")
+
+	// ErrIncorrectOwner indicates that a global policy is not managed by an administrator.
+	ErrIncorrectOwner = errors.New("global access policies must be managed by an administrator")
+)
@@ -0,0 +1,250 @@
+package accesspolicy
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	// The time zone database is embedded since the runtime image does not ship one.
+	_ "time/tzdata"
+
+	"github.com/google/uuid"
+)
+
+// minutesPerDay is the number of minutes in a day; a window may end at 24:00.
+const minutesPerDay = 24 * 60
+
+// DaySet is a set of weekdays stored as a bit mask indexed by time.Weekday.
+type DaySet uint8
+
+// allDays has the bit of every weekday set.
+const allDays DaySet = 1<<7 - 1
+
+// dayNames maps the three-letter weekday names used by the API to weekdays.
+var dayNames = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// ParseDays parses three-letter weekday names, such as "mon", into a day set.
+func ParseDays(names []string) (DaySet, error) {
+	var days DaySet
+	for _, name := range names {
+		d, ok := dayNames[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			return 0, fmt.Errorf("unknown weekday %q: %w", name, ErrIncorrectDays)
+		}
+		days |= 1 << d
+	}
+	if days == 0 {
+		return 0, fmt.Errorf("no weekdays listed: %w", ErrIncorrectDays)
+	}
+	return days, nil
+}
+
+// Has reports whether the set contains the weekday.
+func (s DaySet) Has(d time.Weekday) bool {
+	return s&(1<<d) != 0
+}
+
+// Names returns the three-letter names of the weekdays in the set, starting with Monday.
+func (s DaySet) Names() []string {
+	names := make([]string, 0, 7)
+	for i := range 7 {
+		d := (time.Monday + time.Weekday(i)) % 7
+		if s.Has(d) {
+			names = append(names, strings.ToLower(d.String()[:3]))
+		}
+	}
+	return names
+}
+
+// ParseClock parses an HH:MM time of day, including 24:00, into minutes since midnight.
+func ParseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err == nil {
+		return t.Hour()*60 + t.Minute(), nil
+	}
+	if s == "24:00" {
+		return minutesPerDay, nil
+	}
+	return 0, fmt.Errorf("time of day %q is not HH:MM: %w", s, ErrIncorrectTime)
+}
+
+// FormatClock formats minutes since midnight as an HH:MM time of day.
+func FormatClock(minutes int) string {
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
+
+// Policy restricts the vault to a recurring availability window.
+// A window whose end precedes its start runs overnight into the following day.
+// Policies without an owner are global and apply to every user.
+type Policy struct {
+	// CreatedAt contains the timestamp when the policy was created.
+	CreatedAt time.Time
+	// Location contains the time zone the window is evaluated in.
+	Location *time.Location
+	// Days contains the weekdays the window opens on.
+	Days DaySet
+	// Start contains the minute of the day the window opens at.
+	Start int
+	// End contains the minute of the day the window closes at; 1440 closes it at midnight.
+	End int
+	// ID uniquely identifies this policy.
+	ID uuid.UUID
+	// UserID identifies the user the policy applies to, or uuid.Nil for global policies.
+	UserID uuid.UUID
+	// Managed reports whether an administrator defined the policy; users cannot remove managed policies.
+	Managed bool
+}
+
+// NewPolicy creates a new access policy with the provided parameters after validation.
+func NewPolicy(params NewPolicyParams) (*Policy, error) {
+	if err := params.Validate(); err != nil {
+		return nil, errors.Join(ErrNewPolicyParamsValidation, err)
+	}
+
+	days, _ := ParseDays(params.Days)
+	start, _ := ParseClock(params.Start)
+	end, _ := ParseClock(params.End)
+	loc, _ := loadLocation(params.Timezone)
+	return &Policy{
+		ID:        uuid.New(),
+		UserID:    params.UserID,
+		Days:      days,
+		Start:     start,
+		End:       end,
+		Location:  loc,
+		Managed:   params.Managed,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+// Contains reports whether the window is open at the moment.
+func (p *Policy) Contains(t time.Time) bool {
+	local := t.In(p.Location)
+	minute := local.Hour()*60 + local.Minute()
+	day := local.Weekday()
+
+	if p.Start < p.End {
+		return p.Days.Has(day) && minute >= p.Start && minute < p.End
+	}
+	previous := (day + 6) % 7
+	return (p.Days.Has(day) && minute >= p.Start) || (p.Days.Has(previous) && minute < p.End)
+}
+
+// IsGlobal reports whether the policy applies to all users.
+func (p *Policy) IsGlobal() bool {
+	return p.UserID == uuid.Nil
+}
+
+// Allows reports whether the policies admit the moment. Policies managed by administrators and
+// policies users defined for themselves restrict independently: each group that has any policy
+// must have one whose window is open, so users cannot widen windows set by an administrator.
+func Allows(policies []*Policy, t time.Time) bool {
+	var hasManaged, hasOwn, managedOpen, ownOpen bool
+	for _, p := range policies {
+		open := p.Contains(t)
+		if p.Managed {
+			hasManaged, managedOpen = true, managedOpen || open
+		} else {
+			hasOwn, ownOpen = true, ownOpen || open
+		}
+	}
+	return (!hasManaged || managedOpen) && (!hasOwn || ownOpen)
+}
+
+// loadLocation loads the IANA time zone, defaulting to UTC when none is given.
+func loadLocation(name string) (*time.Location, error) {
+	if name == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil || strings.EqualFold(name, "local") {
+		return nil, fmt.Errorf("unknown time zone %q: %w", name, ErrIncorrectTimezone)
+	}
+	return loc, nil
+}
+
+// NewPolicyParams contains parameters for creating a new access policy.
+type NewPolicyParams struct {
+	// Days contains the three-letter names of the weekdays the window opens on (required).
+	Days []string
+	// Start contains the HH:MM time of day the window opens at (required).
+	Start string
+	// End contains the HH:MM time of day the window closes at, 24:00 for midnight (required).
+	End string
+	// Timezone contains the IANA time zone of the window; empty means UTC.
+	Timezone string
+	// UserID identifies the user the policy applies to, or uuid.Nil for a global policy.
+	UserID uuid.UUID
+	// Managed reports whether an administrator defines the policy.
+	Managed bool
+}
+
+// Validate checks that the access policy creation parameters are valid.
+func (p *NewPolicyParams) Validate() error {
+	validations := []func() error{
+		p.validateDays,
+		p.validateWindow,
+		p.validateTimezone,
+		p.validateOwner,
+	}
+
+	// errs collects all validation errors encountered during policy validation.
+	var errs []error
+	for _, fn := range validations {
+		if err := fn(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) != 0 {
+		return errors.Join(errs...)
+	}
+	return nil
+}
+
+// validateDays ensures that at least one known weekday is listed.
+func (p *NewPolicyParams) validateDays() error {
+	_, err := ParseDays(p.Days)
+	return err
+}
+
+// validateWindow ensures that start and end are times of day that bound a non-empty window.
+func (p *NewPolicyParams) validateWindow() error {
+	start, err := ParseClock(p.Start)
+	if err != nil {
+		return err
+	}
+	end, err := ParseClock(p.End)
+	if err != nil {
+		return err
+	}
+	if start == minutesPerDay {
+		return fmt.Errorf("window cannot open at 24:00: %w", ErrIncorrectTime)
+	}
+	if start == end {
+		return fmt.Errorf("window opens and closes at the same time: %w", ErrIncorrectTime)
+	}
+	return nil
+}
+
+// validateTimezone ensures that the time zone is a known IANA time zone.
+func (p *NewPolicyParams) validateTimezone() error {
+	_, err := loadLocation(p.Timezone)
+	return err
+}
+
+// validateOwner ensures that only administrators define global policies.
+func (p *NewPolicyParams) validateOwner() error {
+	if p.UserID == uuid.Nil && !p.Managed {
+		return ErrIncorrectOwner
+	}
+	return nil
+}
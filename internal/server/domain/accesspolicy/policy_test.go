@@ -0,0 +1,263 @@
+package accesspolicy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPolicy(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+
+	tests := []struct {
+		wantErr      error
+		name         string
+		wantLocation string
+		params       NewPolicyParams
+		wantDays     DaySet
+		wantStart    int
+		wantEnd      int
+	}{
+		{
+			name: "work hours",
+			params: NewPolicyParams{
+				Days:     []string{"mon", "tue", "wed", "thu", "fri"},
+				Start:    "09:00",
+				End:      "18:30",
+				Timezone: "Europe/Berlin",
+				UserID:   userID,
+			},
+			wantDays:     0b0111110,
+			wantStart:    9 * 60,
+			wantEnd:      18*60 + 30,
+			wantLocation: "Europe/Berlin",
+		},
+		{
+			name:         "whole day in UTC",
+			params:       NewPolicyParams{Days: []string{"Sat", " sun "}, Start: "00:00", End: "24:00", UserID: userID},
+			wantDays:     0b1000001,
+			wantEnd:      minutesPerDay,
+			wantLocation: "UTC",
+		},
+		{
+			name: "global managed overnight",
+			params: NewPolicyParams{
+				Days:    []string{"fri"},
+				Start:   "22:00",
+				End:     "06:00",
+				Managed: true,
+			},
+			wantDays:     1 << time.Friday,
+			wantStart:    22 * 60,
+			wantEnd:      6 * 60,
+			wantLocation: "UTC",
+		},
+		{
+			name:    "no days",
+			params:  NewPolicyParams{Start: "09:00", End: "18:00", UserID: userID},
+			wantErr: ErrIncorrectDays,
+		},
+		{
+			name:    "unknown day",
+			params:  NewPolicyParams{Days: []string{"monday"}, Start: "09:00", End: "18:00", UserID: userID},
+			wantErr: ErrIncorrectDays,
+		},
+		{
+			name:    "malformed time",
+			params:  NewPolicyParams{Days: []string{"mon"}, Start: "9am", End: "18:00", UserID: userID},
+			wantErr: ErrIncorrectTime,
+		},
+		{
+			name:    "empty window",
+			params:  NewPolicyParams{Days: []string{"mon"}, Start: "09:00", End: "09:00", UserID: userID},
+			wantErr: ErrIncorrectTime,
+		},
+		{
+			name:    "opens at midnight end",
+			params:  NewPolicyParams{Days: []string{"mon"}, Start: "24:00", End: "06:00", UserID: userID},
+			wantErr: ErrIncorrectTime,
+		},
+		{
+			name: "unknown timezone",
+			params: NewPolicyParams{
+				Days:     []string{"mon"},
+				Start:    "09:00",
+				End:      "18:00",
+				Timezone: "Mars/Olympus",
+				UserID:   userID,
+			},
+			wantErr: ErrIncorrectTimezone,
+		},
+		{
+			name: "server local timezone",
+			params: NewPolicyParams{
+				Days:     []string{"mon"},
+				Start:    "09:00",
+				End:      "18:00",
+				Timezone: "Local",
+				UserID:   userID,
+			},
+			wantErr: ErrIncorrectTimezone,
+		},
+		{
+			name:    "global policy of a user",
+			params:  NewPolicyParams{Days: []string{"mon"}, Start: "09:00", End: "18:00"},
+			wantErr: ErrIncorrectOwner,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			p, err := NewPolicy(tt.params)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, ErrNewPolicyParamsValidation)
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, p)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotEqual(t, uuid.Nil, p.ID)
+			assert.Equal(t, tt.params.UserID, p.UserID)
+			assert.Equal(t, tt.wantDays, p.Days)
+			assert.Equal(t, tt.wantStart, p.Start)
+			assert.Equal(t, tt.wantEnd, p.End)
+			assert.Equal(t, tt.wantLocation, p.Location.String())
+			assert.Equal(t, tt.params.Managed, p.Managed)
+			assert.False(t, p.CreatedAt.IsZero())
+		})
+	}
+}
+
+func TestPolicy_Contains(t *testing.T) {
+	t.Parallel()
+
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	workHours := &Policy{Days: 0b0111110, Start: 9 * 60, End: 18 * 60, Location: berlin}
+	night := &Policy{Days: 1 << time.Friday, Start: 22 * 60, End: 6 * 60, Location: time.UTC}
+	untilMidnight := &Policy{Days: 1 << time.Sunday, Start: 20 * 60, End: minutesPerDay, Location: time.UTC}
+
+	tests := []struct {
+		at     time.Time
+		policy *Policy
+		name   string
+		want   bool
+	}{
+		{
+			name:   "inside work hours",
+			policy: workHours,
+			at:     time.Date(2026, 10, 14, 9, 0, 0, 0, berlin),
+			want:   true,
+		},
+		{
+			name:   "end is exclusive",
+			policy: workHours,
+			at:     time.Date(2026, 10, 14, 18, 0, 0, 0, berlin),
+		},
+		{
+			name:   "evaluated in policy timezone",
+			policy: workHours,
+			at:     time.Date(2026, 10, 14, 6, 30, 0, 0, time.UTC),
+		},
+		{
+			name:   "weekend",
+			policy: workHours,
+			at:     time.Date(2026, 10, 17, 12, 0, 0, 0, berlin),
+		},
+		{
+			name:   "overnight before midnight",
+			policy: night,
+			at:     time.Date(2026, 10, 16, 23, 0, 0, 0, time.UTC),
+			want:   true,
+		},
+		{
+			name:   "overnight after midnight",
+			policy: night,
+			at:     time.Date(2026, 10, 17, 5, 59, 0, 0, time.UTC),
+			want:   true,
+		},
+		{
+			name:   "overnight of an unlisted day",
+			policy: night,
+			at:     time.Date(2026, 10, 16, 5, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "until midnight",
+			policy: untilMidnight,
+			at:     time.Date(2026, 10, 18, 23, 59, 0, 0, time.UTC),
+			want:   true,
+		},
+		{
+			name:   "after midnight end",
+			policy: untilMidnight,
+			at:     time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, tt.policy.Contains(tt.at))
+		})
+	}
+}
+
+func TestAllows(t *testing.T) {
+	t.Parallel()
+
+	at := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+	open := func(managed bool) *Policy {
+		return &Policy{Days: allDays, Start: 11 * 60, End: 13 * 60, Location: time.UTC, Managed: managed}
+	}
+	closed := func(managed bool) *Policy {
+		return &Policy{Days: allDays, Start: 14 * 60, End: 15 * 60, Location: time.UTC, Managed: managed}
+	}
+
+	tests := []struct {
+		name     string
+		policies []*Policy
+		want     bool
+	}{
+		{name: "no policies", want: true},
+		{name: "one open window", policies: []*Policy{closed(false), open(false)}, want: true},
+		{name: "all windows closed", policies: []*Policy{closed(false), closed(false)}},
+		{name: "open managed and own", policies: []*Policy{open(true), open(false)}, want: true},
+		{name: "own window cannot widen managed", policies: []*Policy{closed(true), open(false)}},
+		{name: "own window narrows managed", policies: []*Policy{open(true), closed(false)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, Allows(tt.policies, at))
+		})
+	}
+}
+
+func TestDaySet_Names(t *testing.T) {
+	t.Parallel()
+
+	days, err := ParseDays([]string{"sun", "fri", "mon"})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"mon", "fri", "sun"}, days.Names())
+	assert.True(t, days.Has(time.Sunday))
+	assert.False(t, days.Has(time.Tuesday))
+}
+
+func TestFormatClock(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "09:05", FormatClock(9*60+5))
+	assert.Equal(t, "24:00", FormatClock(minutesPerDay))
+}
@@ -0,0 +1,7 @@
+// Package accessrule provides network access rule domain entities and business rules
+// for the AegisVaultKeeper server.
+//
+// This package implements core domain logic for IP allowlists, CIDR denylists and
+// country blocking, defining the Rule entity and how rules match client addresses, and the
+// geofence limiting where restricted items can be revealed from.
+package accessrule
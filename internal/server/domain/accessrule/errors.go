@@ -0,0 +1,21 @@
+package accessrule
+
+import "errors"
+
+// Access rule domain error definitions.
+var (
+	// ErrNewRuleParamsValidation indicates that access rule creation parameters failed validation.
+	ErrNewRuleParamsValidation = errors.New("new access rule parameters validation failed")
+
+	// ErrIncorrectAction indicates that the rule action is neither allow nor deny.
+	ErrIncorrectAction = errors.New("incorrect access rule action")
+
+	// ErrIncorrectNetwork indicates that the rule network is not a valid CIDR or IP address.
+	ErrIncorrectNetwork = errors.New("incorrect access rule network")
+
+	// ErrIncorrectCountry indicates that the rule country is not an ISO 3166-1 alpha-2 code.
+	ErrIncorrectCountry = errors.New("incorrect access rule country")
+
+	// ErrIncorrectTarget indicates that the rule does not specify exactly one of network or country.
+	ErrIncorrectTarget = errors.New("access rule must specify either a network or a country")
+)
@@ -0,0 +1,43 @@
+package accessrule
+
+import (
+	"net/netip"
+	"strings"
+)
+
+// Geofence limits the locations items tagged restricted can be revealed from.
+// A geofence without networks and countries is disabled and admits every location.
+type Geofence struct {
+	// Networks contains the networks restricted items can be revealed from.
+	Networks []netip.Prefix
+	// Countries contains the ISO 3166-1 alpha-2 codes of countries restricted items can be revealed from.
+	Countries []string
+}
+
+// Enabled reports whether the geofence restricts any location.
+func (g Geofence) Enabled() bool {
+	return len(g.Networks) != 0 || len(g.Countries) != 0
+}
+
+// Admits reports whether the client address or country lies inside the geofence.
+// An invalid address and an empty country lie outside every network and country.
+func (g Geofence) Admits(ip netip.Addr, country string) bool {
+	if !g.Enabled() {
+		return true
+	}
+	if ip.IsValid() {
+		for _, n := range g.Networks {
+			if n.Contains(ip.Unmap()) {
+				return true
+			}
+		}
+	}
+	if country != "" {
+		for _, c := range g.Countries {
+			if strings.EqualFold(c, country) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,40 @@
+package accessrule
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGeofence_Admits(t *testing.T) {
+	t.Parallel()
+
+	fence := Geofence{
+		Networks:  []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")},
+		Countries: []string{"DE"},
+	}
+
+	tests := []struct {
+		name    string
+		fence   Geofence
+		ip      netip.Addr
+		country string
+		want    bool
+	}{
+		{name: "disabled admits all", ip: netip.MustParseAddr("203.0.113.7"), want: true},
+		{name: "inside network", fence: fence, ip: netip.MustParseAddr("10.1.2.3"), want: true},
+		{name: "mapped address inside network", fence: fence, ip: netip.MustParseAddr("::ffff:10.1.2.3"), want: true},
+		{name: "inside country", fence: fence, ip: netip.MustParseAddr("203.0.113.7"), country: "de", want: true},
+		{name: "outside", fence: fence, ip: netip.MustParseAddr("203.0.113.7"), country: "FR"},
+		{name: "unknown location", fence: fence},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, tt.fence.Admits(tt.ip, tt.country))
+		})
+	}
+}
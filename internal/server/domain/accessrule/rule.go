@@ -0,0 +1,158 @@
+package accessrule
+
+import (
+	"errors"
+	"net/netip"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Action determines whether a matching rule permits or blocks access.
+type Action string
+
+// Supported rule actions.
+const (
+	// ActionAllow adds the rule target to an allowlist.
+	ActionAllow Action = "allow"
+	// ActionDeny adds the rule target to a denylist.
+	ActionDeny Action = "deny"
+)
+
+// Rule restricts the network locations access is permitted from.
+// Rules without an owner are global and apply to every request.
+type Rule struct {
+	// CreatedAt contains the timestamp when the rule was created.
+	CreatedAt time.Time
+	// Network contains the matched network; it is invalid for country rules.
+	Network netip.Prefix
+	// Country contains the matched ISO 3166-1 alpha-2 country code; it is empty for network rules.
+	Country string
+	// Action determines whether matching requests are allowed or denied.
+	Action Action
+	// ID uniquely identifies this rule.
+	ID uuid.UUID
+	// UserID identifies the user the rule applies to, or uuid.Nil for global rules.
+	UserID uuid.UUID
+}
+
+// NewRule creates a new access rule with the provided parameters after validation.
+func NewRule(params NewRuleParams) (*Rule, error) {
+	if err := params.Validate(); err != nil {
+		return nil, errors.Join(ErrNewRuleParamsValidation, err)
+	}
+
+	r := Rule{
+		ID:        uuid.New(),
+		UserID:    params.UserID,
+		Action:    params.Action,
+		Country:   strings.ToUpper(params.Country),
+		CreatedAt: time.Now(),
+	}
+	if params.Network != "" {
+		r.Network, _ = ParseNetwork(params.Network)
+	}
+	return &r, nil
+}
+
+// Matches reports whether the rule target covers the client address or country.
+func (r *Rule) Matches(ip netip.Addr, country string) bool {
+	if r.Country != "" {
+		return country != "" && strings.EqualFold(r.Country, country)
+	}
+	return ip.IsValid() && r.Network.Contains(ip.Unmap())
+}
+
+// IsGlobal reports whether the rule applies to all users.
+func (r *Rule) IsGlobal() bool {
+	return r.UserID == uuid.Nil
+}
+
+// ParseNetwork parses a CIDR, treating a bare IP address as a single-host network.
+func ParseNetwork(s string) (netip.Prefix, error) {
+	if !strings.Contains(s, "/") {
+		ip, err := netip.ParseAddr(s)
+		if err != nil {
+			return netip.Prefix{}, err
+		}
+		ip = ip.Unmap()
+		return netip.PrefixFrom(ip, ip.BitLen()), nil
+	}
+	p, err := netip.ParsePrefix(s)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	return p.Masked(), nil
+}
+
+// NewRuleParams contains parameters for creating a new access rule.
+type NewRuleParams struct {
+	// Action determines whether matching requests are allowed or denied (required).
+	Action Action
+	// Network contains a CIDR or IP address to match (exclusive with Country).
+	Network string
+	// Country contains an ISO 3166-1 alpha-2 country code to match (exclusive with Network).
+	Country string
+	// UserID identifies the user the rule applies to, or uuid.Nil for a global rule.
+	UserID uuid.UUID
+}
+
+// Validate checks that the access rule creation parameters are valid.
+func (p *NewRuleParams) Validate() error {
+	validations := []func() error{
+		p.validateAction,
+		p.validateTarget,
+	}
+
+	// errs collects all validation errors encountered during rule validation.
+	var errs []error
+	for _, fn := range validations {
+		if err := fn(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) != 0 {
+		return errors.Join(errs...)
+	}
+	return nil
+}
+
+// validateAction ensures that the action is allow or deny.
+func (p *NewRuleParams) validateAction() error {
+	if p.Action != ActionAllow && p.Action != ActionDeny {
+		return ErrIncorrectAction
+	}
+	return nil
+}
+
+// validateTarget ensures that exactly one valid network or country is specified.
+func (p *NewRuleParams) validateTarget() error {
+	switch {
+	case (p.Network == "") == (p.Country == ""):
+		return ErrIncorrectTarget
+	case p.Network != "":
+		if _, err := ParseNetwork(p.Network); err != nil {
+			return ErrIncorrectNetwork
+		}
+	default:
+		if !isCountryCode(p.Country) {
+			return ErrIncorrectCountry
+		}
+	}
+	return nil
+}
+
+// isCountryCode reports whether s consists of exactly two ASCII letters.
+func isCountryCode(s string) bool {
+	if len(s) != 2 {
+		return false
+	}
+	for _, c := range s {
+		if (c < 'A' || c > 'Z') && (c < 'a' || c > 'z') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,123 @@
+package accessrule
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRule(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+
+	tests := []struct {
+		wantErr     error
+		name        string
+		wantCountry string
+		wantNetwork netip.Prefix
+		params      NewRuleParams
+	}{
+		{
+			name:        "global network deny",
+			params:      NewRuleParams{Action: ActionDeny, Network: "10.1.2.3/8"},
+			wantNetwork: netip.MustParsePrefix("10.0.0.0/8"),
+		},
+		{
+			name:        "user address allow",
+			params:      NewRuleParams{Action: ActionAllow, Network: "2001:db8::1", UserID: userID},
+			wantNetwork: netip.MustParsePrefix("2001:db8::1/128"),
+		},
+		{
+			name:        "country deny",
+			params:      NewRuleParams{Action: ActionDeny, Country: "ru"},
+			wantCountry: "RU",
+		},
+		{
+			name:    "unknown action",
+			params:  NewRuleParams{Action: "block", Network: "10.0.0.0/8"},
+			wantErr: ErrIncorrectAction,
+		},
+		{
+			name:    "no target",
+			params:  NewRuleParams{Action: ActionDeny},
+			wantErr: ErrIncorrectTarget,
+		},
+		{
+			name:    "both targets",
+			params:  NewRuleParams{Action: ActionDeny, Network: "10.0.0.0/8", Country: "DE"},
+			wantErr: ErrIncorrectTarget,
+		},
+		{
+			name:    "invalid network",
+			params:  NewRuleParams{Action: ActionAllow, Network: "10.0.0.0/40"},
+			wantErr: ErrIncorrectNetwork,
+		},
+		{
+			name:    "invalid country",
+			params:  NewRuleParams{Action: ActionAllow, Country: "DEU"},
+			wantErr: ErrIncorrectCountry,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r, err := NewRule(tt.params)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, ErrNewRuleParamsValidation)
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, r)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotEqual(t, uuid.Nil, r.ID)
+			assert.Equal(t, tt.params.UserID, r.UserID)
+			assert.Equal(t, tt.params.Action, r.Action)
+			assert.Equal(t, tt.wantNetwork, r.Network)
+			assert.Equal(t, tt.wantCountry, r.Country)
+			assert.False(t, r.CreatedAt.IsZero())
+			assert.Equal(t, tt.params.UserID == uuid.Nil, r.IsGlobal())
+		})
+	}
+}
+
+func TestRule_Matches(t *testing.T) {
+	t.Parallel()
+
+	network := &Rule{Network: netip.MustParsePrefix("203.0.113.0/24")}
+	country := &Rule{Country: "DE"}
+
+	tests := []struct {
+		rule    *Rule
+		name    string
+		ip      string
+		country string
+		want    bool
+	}{
+		{name: "address in network", rule: network, ip: "203.0.113.9", want: true},
+		{name: "mapped address in network", rule: network, ip: "::ffff:203.0.113.9", want: true},
+		{name: "address outside network", rule: network, ip: "198.51.100.1", want: false},
+		{name: "unknown address", rule: network, want: false},
+		{name: "country match", rule: country, ip: "198.51.100.1", country: "de", want: true},
+		{name: "other country", rule: country, ip: "198.51.100.1", country: "FR", want: false},
+		{name: "unknown country", rule: country, ip: "198.51.100.1", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var ip netip.Addr
+			if tt.ip != "" {
+				ip = netip.MustParseAddr(tt.ip)
+			}
+			assert.Equal(t, tt.want, tt.rule.Matches(ip, tt.country))
+		})
+	}
+}
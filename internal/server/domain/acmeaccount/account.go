@@ -0,0 +1,234 @@
+package acmeaccount
+
+import (
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/securebytes"
+	"github.com/google/uuid"
+)
+
+const (
+	// maxURLLen is the maximum length of the directory and hook URLs in bytes.
+	maxURLLen = 512
+	// maxEmailLen is the maximum length of the contact e-mail in bytes.
+	maxEmailLen = 255
+	// maxAccountKeyLen is the maximum size of the PEM-encoded account key in bytes.
+	maxAccountKeyLen = 16 << 10
+	// maxDNSCredentials is the maximum number of DNS credentials of an account.
+	maxDNSCredentials = 32
+	// maxDNSCredentialLen is the maximum length of a DNS credential value in bytes.
+	maxDNSCredentialLen = 4 << 10
+)
+
+var (
+	// providerPattern matches DNS provider names, such as cloudflare or route53.
+	providerPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,31}$`)
+	// credentialNamePattern matches DNS credential names, which follow environment variable conventions.
+	credentialNamePattern = regexp.MustCompile(`^[A-Z][A-Z0-9_]{0,63}$`)
+)
+
+// Account represents an ACME certificate authority account with the DNS credentials of its DNS-01 challenges.
+type Account struct {
+	// UpdatedAt contains the timestamp when the account was last modified.
+	UpdatedAt time.Time
+	// DirectoryURL contains the encrypted directory URL of the certificate authority.
+	DirectoryURL []byte
+	// Email contains the encrypted contact e-mail of the account.
+	Email []byte
+	// AccountKey contains the encrypted PEM-encoded private key of the account.
+	AccountKey []byte
+	// DNSProvider contains the encrypted name of the DNS provider answering DNS-01 challenges.
+	DNSProvider []byte
+	// DNSCredentials contains the encrypted JSON object of the DNS provider credentials by name.
+	DNSCredentials []byte
+	// HookURL contains the encrypted URL of the automation hook told about account changes.
+	HookURL []byte
+	// Description contains the encrypted account description.
+	Description []byte
+	// ID uniquely identifies this account.
+	ID uuid.UUID
+	// UserID identifies the user who owns this account.
+	UserID uuid.UUID
+}
+
+// NewAccount creates a new ACME account with the provided parameters after validation.
+func NewAccount(params NewAccountParams) (*Account, error) {
+	if err := params.Validate(); err != nil {
+		return nil, errors.Join(ErrNewAccountParamsValidation, err)
+	}
+
+	// credentials holds the DNS credentials as stored, with an empty object for accounts without them.
+	credentials := params.DNSCredentials
+	if credentials == nil {
+		credentials = map[string]string{}
+	}
+	encoded, err := json.Marshal(credentials)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode DNS credentials: %w", err)
+	}
+
+	return &Account{
+		ID:             uuid.New(),
+		UserID:         params.UserID,
+		DirectoryURL:   []byte(params.DirectoryURL),
+		Email:          []byte(params.Email),
+		AccountKey:     []byte(params.AccountKey),
+		DNSProvider:    []byte(params.DNSProvider),
+		DNSCredentials: encoded,
+		HookURL:        []byte(params.HookURL),
+		Description:    []byte(params.Description),
+		UpdatedAt:      time.Now(),
+	}, nil
+}
+
+// Credentials decodes the DNS provider credentials of the account by name.
+func (a *Account) Credentials() (map[string]string, error) {
+	credentials := map[string]string{}
+	if len(a.DNSCredentials) == 0 {
+		return credentials, nil
+	}
+	if err := json.Unmarshal(a.DNSCredentials, &credentials); err != nil {
+		return nil, fmt.Errorf("failed to decode DNS credentials: %w", err)
+	}
+	return credentials, nil
+}
+
+// Wipe zeroes the sensitive fields of the account in place once they are no longer needed.
+func (a *Account) Wipe() {
+	if a == nil {
+		return
+	}
+	securebytes.Wipe(a.DirectoryURL, a.Email, a.AccountKey, a.DNSProvider, a.DNSCredentials, a.HookURL, a.Description)
+}
+
+// NewAccountParams contains parameters for creating a new ACME account.
+type NewAccountParams struct {
+	// DNSCredentials contains the DNS provider credentials by name, such as CF_DNS_API_TOKEN (optional).
+	DNSCredentials map[string]string
+	// DirectoryURL contains the HTTPS directory URL of the certificate authority (required).
+	DirectoryURL string
+	// Email contains the contact e-mail of the account (optional).
+	Email string
+	// AccountKey contains the PEM-encoded private key of the account (required).
+	AccountKey string
+	// DNSProvider contains the name of the DNS provider; required when DNS credentials are given.
+	DNSProvider string
+	// HookURL contains the HTTPS URL of the automation hook told about account changes (optional).
+	HookURL string
+	// Description contains an optional description for the account.
+	Description string
+	// UserID identifies the user who will own this account.
+	UserID uuid.UUID
+}
+
+// Validate checks that the ACME account creation parameters are valid.
+func (p *NewAccountParams) Validate() error {
+	validations := []func() error{
+		p.validateDirectoryURL,
+		p.validateEmail,
+		p.validateAccountKey,
+		p.validateDNSProvider,
+		p.validateDNSCredentials,
+		p.validateHookURL,
+	}
+
+	// errs collects all validation errors encountered during account validation.
+	var errs []error
+	for _, fn := range validations {
+		if err := fn(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) != 0 {
+		return errors.Join(errs...)
+	}
+	return nil
+}
+
+// validateDirectoryURL ensures that the directory URL is an HTTPS URL.
+func (p *NewAccountParams) validateDirectoryURL() error {
+	if !httpsURL(p.DirectoryURL) {
+		return ErrIncorrectDirectoryURL
+	}
+	return nil
+}
+
+// validateEmail ensures that the contact e-mail, if any, is a plain e-mail address.
+func (p *NewAccountParams) validateEmail() error {
+	if p.Email == "" {
+		return nil
+	}
+	if len(p.Email) > maxEmailLen {
+		return ErrIncorrectEmail
+	}
+	addr, err := mail.ParseAddress(p.Email)
+	if err != nil || addr.Address != p.Email {
+		return ErrIncorrectEmail
+	}
+	return nil
+}
+
+// validateAccountKey ensures that the account key is a single PEM-encoded private key.
+func (p *NewAccountParams) validateAccountKey() error {
+	if p.AccountKey == "" || len(p.AccountKey) > maxAccountKeyLen {
+		return ErrIncorrectAccountKey
+	}
+	block, rest := pem.Decode([]byte(p.AccountKey))
+	if block == nil || !strings.HasSuffix(block.Type, "PRIVATE KEY") || strings.TrimSpace(string(rest)) != "" {
+		return ErrIncorrectAccountKey
+	}
+	return nil
+}
+
+// validateDNSProvider ensures that the DNS provider name is well-formed and given for DNS credentials.
+func (p *NewAccountParams) validateDNSProvider() error {
+	if p.DNSProvider == "" {
+		if len(p.DNSCredentials) != 0 {
+			return ErrIncorrectDNSProvider
+		}
+		return nil
+	}
+	if !providerPattern.MatchString(p.DNSProvider) {
+		return ErrIncorrectDNSProvider
+	}
+	return nil
+}
+
+// validateDNSCredentials ensures that the DNS credentials have environment variable names and non-empty values.
+func (p *NewAccountParams) validateDNSCredentials() error {
+	if len(p.DNSCredentials) > maxDNSCredentials {
+		return ErrIncorrectDNSCredentials
+	}
+	for name, value := range p.DNSCredentials {
+		if !credentialNamePattern.MatchString(name) || value == "" || len(value) > maxDNSCredentialLen {
+			return ErrIncorrectDNSCredentials
+		}
+	}
+	return nil
+}
+
+// validateHookURL ensures that the automation hook URL, if any, is an HTTPS URL.
+func (p *NewAccountParams) validateHookURL() error {
+	if p.HookURL != "" && !httpsURL(p.HookURL) {
+		return ErrIncorrectHookURL
+	}
+	return nil
+}
+
+// httpsURL reports whether s is an absolute HTTPS URL with a host.
+func httpsURL(s string) bool {
+	if s == "" || len(s) > maxURLLen {
+		return false
+	}
+	u, err := url.Parse(s)
+	return err == nil && u.Scheme == "https" && u.Host != "" && u.User == nil
+}
@@ -0,0 +1,6 @@
+// Package acmeaccount provides ACME account domain entities and business rules for the AegisVaultKeeper server.
+//
+// This package defines the Account entity holding the account key of an ACME certificate authority together with
+// the DNS provider credentials used to answer DNS-01 challenges, so certificate renewal tooling can keep them in
+// the vault.
+package acmeaccount
@@ -0,0 +1,24 @@
+package acmeaccount
+
+import "errors"
+
+// ErrNewAccountParamsValidation indicates that ACME account creation parameters failed validation.
+var ErrNewAccountParamsValidation = errors.New("new ACME account parameters validation failed")
+
+// ErrIncorrectDirectoryURL indicates that the ACME directory URL is missing or not an HTTPS URL.
+var ErrIncorrectDirectoryURL = errors.New("incorrect ACME directory URL")
+
+// ErrIncorrectEmail indicates that the account contact e-mail is not a plain e-mail address.
+var ErrIncorrectEmail = errors.New("incorrect ACME account e-mail")
+
+// ErrIncorrectAccountKey indicates that the account key is missing or not a PEM-encoded private key.
+var ErrIncorrectAccountKey = errors.New("incorrect ACME account key")
+
+// ErrIncorrectDNSProvider indicates that the DNS provider name is malformed or missing for DNS credentials.
+var ErrIncorrectDNSProvider = errors.New("incorrect DNS provider")
+
+// ErrIncorrectDNSCredentials indicates that the DNS credentials are too many or have malformed names or values.
+var ErrIncorrectDNSCredentials = errors.New("incorrect DNS credentials")
+
+// ErrIncorrectHookURL indicates that the automation hook URL is not an HTTPS URL.
+var ErrIncorrectHookURL = errors.New("incorrect automation hook URL")
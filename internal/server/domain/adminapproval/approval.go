@@ -0,0 +1,212 @@
+package adminapproval
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Status names the state of an approval.
+type Status string
+
+// Approval states.
+const (
+	// StatusPending means the request awaits the approval of a second operator.
+	StatusPending Status = "pending"
+	// StatusApproved means a second operator approved the request; the initiator may execute it until it expires.
+	StatusApproved Status = "approved"
+	// StatusRejected means an operator rejected the request, or the initiator withdrew it.
+	StatusRejected Status = "rejected"
+	// StatusExecuted means the initiator executed the approved request; an approval is used only once.
+	StatusExecuted Status = "executed"
+	// StatusExpired means the request was not approved or executed in time.
+	// It is never stored; State derives it from the expiry time.
+	StatusExpired Status = "expired"
+)
+
+// Approval represents a destructive admin request held back until a second operator approves it.
+// The request is identified by its method, path and the SHA-256 digest of its body, so the approved
+// request is exactly the one executed; the body itself is not kept.
+type Approval struct {
+	// CreatedAt contains the timestamp when the request was initiated.
+	CreatedAt time.Time
+	// DecidedAt contains the timestamp of the decision; zero while the request is pending.
+	DecidedAt time.Time
+	// ExpiresAt contains the deadline of both the approval and the execution of the request.
+	ExpiresAt time.Time
+	// Action names the guarded admin action, such as legal_hold.release.
+	Action string
+	// Method contains the HTTP method of the request.
+	Method string
+	// Path contains the path with the query of the request.
+	Path string
+	// Initiator names the operator who initiated the request.
+	Initiator string
+	// Approver names the operator who approved or rejected the request; empty while the request is pending.
+	Approver string
+	// Status contains the stored state of the approval.
+	Status Status
+	// BodyDigest contains the SHA-256 digest of the request body.
+	BodyDigest []byte
+	// ID uniquely identifies this approval.
+	ID uuid.UUID
+}
+
+// NewApproval creates a pending approval with the provided parameters after validation.
+func NewApproval(params NewApprovalParams) (*Approval, error) {
+	if err := params.Validate(); err != nil {
+		return nil, errors.Join(ErrNewApprovalParamsValidation, err)
+	}
+	now := time.Now()
+	return &Approval{
+		ID:         uuid.New(),
+		Action:     params.Action,
+		Method:     params.Method,
+		Path:       params.Path,
+		BodyDigest: params.BodyDigest,
+		Initiator:  params.Initiator,
+		Status:     StatusPending,
+		CreatedAt:  now,
+		ExpiresAt:  now.Add(params.TTL),
+	}, nil
+}
+
+// State returns the state of the approval at the moment, reporting pending and approved requests past
+// their expiry time as expired.
+func (a *Approval) State(at time.Time) Status {
+	if (a.Status == StatusPending || a.Status == StatusApproved) && !at.Before(a.ExpiresAt) {
+		return StatusExpired
+	}
+	return a.Status
+}
+
+// Decide records the decision of an operator at the moment. Only an operator other than the initiator may
+// approve the request; the initiator may reject it to withdraw it.
+func (a *Approval) Decide(operator string, approve bool, at time.Time) error {
+	switch state := a.State(at); {
+	case operator == "":
+		return ErrIncorrectOperator
+	case state == StatusExpired:
+		return ErrApprovalExpired
+	case state != StatusPending:
+		return fmt.Errorf("approval is %s: %w", state, ErrApprovalNotPending)
+	case approve && operator == a.Initiator:
+		return ErrSelfApproval
+	}
+
+	a.Approver = operator
+	a.DecidedAt = at
+	if approve {
+		a.Status = StatusApproved
+	} else {
+		a.Status = StatusRejected
+	}
+	return nil
+}
+
+// Execute marks the approved request as executed by the operator at the moment. The operator must be the
+// initiator and the request must match the approved one.
+func (a *Approval) Execute(params ExecuteParams, at time.Time) error {
+	switch state := a.State(at); {
+	case state == StatusExpired:
+		return ErrApprovalExpired
+	case state != StatusApproved:
+		return fmt.Errorf("approval is %s: %w", state, ErrApprovalNotApproved)
+	case params.Operator != a.Initiator ||
+		params.Method != a.Method ||
+		params.Path != a.Path ||
+		!bytes.Equal(params.BodyDigest, a.BodyDigest):
+		return ErrRequestMismatch
+	}
+
+	a.Status = StatusExecuted
+	return nil
+}
+
+// ExecuteParams describes the request an operator is about to execute under an approval.
+type ExecuteParams struct {
+	// Method contains the HTTP method of the request.
+	Method string
+	// Path contains the path with the query of the request.
+	Path string
+	// Operator names the operator executing the request.
+	Operator string
+	// BodyDigest contains the SHA-256 digest of the request body.
+	BodyDigest []byte
+}
+
+// NewApprovalParams contains parameters for initiating a guarded admin request.
+type NewApprovalParams struct {
+	// Action names the guarded admin action (required).
+	Action string
+	// Method contains the HTTP method of the request (required).
+	Method string
+	// Path contains the path with the query of the request (required).
+	Path string
+	// Initiator names the operator initiating the request (required).
+	Initiator string
+	// BodyDigest contains the SHA-256 digest of the request body (required).
+	BodyDigest []byte
+	// TTL specifies how long the request may await its approval and execution.
+	TTL time.Duration
+}
+
+// Validate checks that the approval parameters are valid.
+func (p *NewApprovalParams) Validate() error {
+	validations := []func() error{
+		p.validateAction,
+		p.validateRequest,
+		p.validateInitiator,
+		p.validateTTL,
+	}
+
+	// errs collects all validation errors encountered during approval validation.
+	var errs []error
+	for _, fn := range validations {
+		if err := fn(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) != 0 {
+		return errors.Join(errs...)
+	}
+	return nil
+}
+
+// validateAction ensures that the guarded action is specified.
+func (p *NewApprovalParams) validateAction() error {
+	if strings.TrimSpace(p.Action) == "" {
+		return ErrIncorrectAction
+	}
+	return nil
+}
+
+// validateRequest ensures that the request is fully described.
+func (p *NewApprovalParams) validateRequest() error {
+	if p.Method == "" || p.Path == "" || len(p.BodyDigest) != sha256.Size {
+		return ErrIncorrectRequest
+	}
+	return nil
+}
+
+// validateInitiator ensures that the initiating operator is specified.
+func (p *NewApprovalParams) validateInitiator() error {
+	if p.Initiator == "" {
+		return ErrIncorrectOperator
+	}
+	return nil
+}
+
+// validateTTL ensures that the approval lifetime is positive.
+func (p *NewApprovalParams) validateTTL() error {
+	if p.TTL <= 0 {
+		return ErrIncorrectTTL
+	}
+	return nil
+}
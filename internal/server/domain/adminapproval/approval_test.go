@@ -0,0 +1,307 @@
+package adminapproval
+
+import (
+	"crypto/sha256"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewApproval(t *testing.T) {
+	t.Parallel()
+
+	digest := sha256.Sum256(nil)
+	valid := NewApprovalParams{
+		Action:     "legal_hold.release",
+		Method:     http.MethodDelete,
+		Path:       "/api/admin/legal-holds/6f1c2a7e-0d5b-4c55-9a3e-1f2b3c4d5e6f",
+		Initiator:  "alice",
+		BodyDigest: digest[:],
+		TTL:        time.Hour,
+	}
+	// with returns the valid parameters changed by fn.
+	with := func(fn func(p *NewApprovalParams)) NewApprovalParams {
+		p := valid
+		fn(&p)
+		return p
+	}
+
+	tests := []struct {
+		wantErr error
+		name    string
+		params  NewApprovalParams
+	}{
+		{name: "valid approval", params: valid},
+		{
+			name:    "missing action",
+			params:  with(func(p *NewApprovalParams) { p.Action = " " }),
+			wantErr: ErrIncorrectAction,
+		},
+		{
+			name:    "missing path",
+			params:  with(func(p *NewApprovalParams) { p.Path = "" }),
+			wantErr: ErrIncorrectRequest,
+		},
+		{
+			name:    "truncated digest",
+			params:  with(func(p *NewApprovalParams) { p.BodyDigest = digest[:16] }),
+			wantErr: ErrIncorrectRequest,
+		},
+		{
+			name:    "missing initiator",
+			params:  with(func(p *NewApprovalParams) { p.Initiator = "" }),
+			wantErr: ErrIncorrectOperator,
+		},
+		{
+			name:    "zero lifetime",
+			params:  with(func(p *NewApprovalParams) { p.TTL = 0 }),
+			wantErr: ErrIncorrectTTL,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := NewApproval(tt.params)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, ErrNewApprovalParamsValidation)
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, got)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotEqual(t, uuid.Nil, got.ID)
+			assert.Equal(t, tt.params.Action, got.Action)
+			assert.Equal(t, tt.params.Method, got.Method)
+			assert.Equal(t, tt.params.Path, got.Path)
+			assert.Equal(t, tt.params.BodyDigest, got.BodyDigest)
+			assert.Equal(t, tt.params.Initiator, got.Initiator)
+			assert.Equal(t, StatusPending, got.Status)
+			assert.Empty(t, got.Approver)
+			assert.Equal(t, tt.params.TTL, got.ExpiresAt.Sub(got.CreatedAt))
+		})
+	}
+}
+
+func TestApproval_State(t *testing.T) {
+	t.Parallel()
+
+	expiresAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		at     time.Time
+		name   string
+		status Status
+		want   Status
+	}{
+		{name: "pending", status: StatusPending, at: expiresAt.Add(-time.Second), want: StatusPending},
+		{name: "pending past expiry", status: StatusPending, at: expiresAt, want: StatusExpired},
+		{name: "approved", status: StatusApproved, at: expiresAt.Add(-time.Second), want: StatusApproved},
+		{name: "approved past expiry", status: StatusApproved, at: expiresAt, want: StatusExpired},
+		{name: "rejected past expiry", status: StatusRejected, at: expiresAt, want: StatusRejected},
+		{name: "executed past expiry", status: StatusExecuted, at: expiresAt, want: StatusExecuted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			a := &Approval{Status: tt.status, ExpiresAt: expiresAt}
+			assert.Equal(t, tt.want, a.State(tt.at))
+		})
+	}
+}
+
+func TestApproval_Decide(t *testing.T) {
+	t.Parallel()
+
+	at := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		wantErr    error
+		expiresAt  time.Time
+		name       string
+		operator   string
+		status     Status
+		wantStatus Status
+		approve    bool
+	}{
+		{
+			name:       "approved by a second operator",
+			operator:   "bob",
+			status:     StatusPending,
+			expiresAt:  at.Add(time.Hour),
+			approve:    true,
+			wantStatus: StatusApproved,
+		},
+		{
+			name:       "rejected by a second operator",
+			operator:   "bob",
+			status:     StatusPending,
+			expiresAt:  at.Add(time.Hour),
+			wantStatus: StatusRejected,
+		},
+		{
+			name:       "withdrawn by the initiator",
+			operator:   "alice",
+			status:     StatusPending,
+			expiresAt:  at.Add(time.Hour),
+			wantStatus: StatusRejected,
+		},
+		{
+			name:      "approved by the initiator",
+			operator:  "alice",
+			status:    StatusPending,
+			expiresAt: at.Add(time.Hour),
+			approve:   true,
+			wantErr:   ErrSelfApproval,
+		},
+		{
+			name:      "already approved",
+			operator:  "carol",
+			status:    StatusApproved,
+			expiresAt: at.Add(time.Hour),
+			approve:   true,
+			wantErr:   ErrApprovalNotPending,
+		},
+		{
+			name:      "expired",
+			operator:  "bob",
+			status:    StatusPending,
+			expiresAt: at,
+			approve:   true,
+			wantErr:   ErrApprovalExpired,
+		},
+		{
+			name:      "missing operator",
+			status:    StatusPending,
+			expiresAt: at.Add(time.Hour),
+			approve:   true,
+			wantErr:   ErrIncorrectOperator,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			a := &Approval{Initiator: "alice", Status: tt.status, ExpiresAt: tt.expiresAt}
+			err := a.Decide(tt.operator, tt.approve, at)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Equal(t, tt.status, a.Status)
+				assert.True(t, a.DecidedAt.IsZero())
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, a.Status)
+			assert.Equal(t, tt.operator, a.Approver)
+			assert.Equal(t, at, a.DecidedAt)
+		})
+	}
+}
+
+func TestApproval_Execute(t *testing.T) {
+	t.Parallel()
+
+	at := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	digest := sha256.Sum256([]byte(`{"reason":"Case closed"}`))
+	other := sha256.Sum256(nil)
+	request := ExecuteParams{
+		Method:     http.MethodDelete,
+		Path:       "/api/admin/recordings/6f1c2a7e-0d5b-4c55-9a3e-1f2b3c4d5e6f",
+		Operator:   "alice",
+		BodyDigest: digest[:],
+	}
+	// with returns the approved request changed by fn.
+	with := func(fn func(p *ExecuteParams)) ExecuteParams {
+		p := request
+		fn(&p)
+		return p
+	}
+
+	tests := []struct {
+		wantErr   error
+		expiresAt time.Time
+		name      string
+		status    Status
+		params    ExecuteParams
+	}{
+		{name: "approved request", status: StatusApproved, expiresAt: at.Add(time.Hour), params: request},
+		{
+			name:      "pending",
+			status:    StatusPending,
+			expiresAt: at.Add(time.Hour),
+			params:    request,
+			wantErr:   ErrApprovalNotApproved,
+		},
+		{
+			name:      "already executed",
+			status:    StatusExecuted,
+			expiresAt: at.Add(time.Hour),
+			params:    request,
+			wantErr:   ErrApprovalNotApproved,
+		},
+		{
+			name:      "rejected",
+			status:    StatusRejected,
+			expiresAt: at.Add(time.Hour),
+			params:    request,
+			wantErr:   ErrApprovalNotApproved,
+		},
+		{name: "expired", status: StatusApproved, expiresAt: at, params: request, wantErr: ErrApprovalExpired},
+		{
+			name:      "executed by the approver",
+			status:    StatusApproved,
+			expiresAt: at.Add(time.Hour),
+			params:    with(func(p *ExecuteParams) { p.Operator = "bob" }),
+			wantErr:   ErrRequestMismatch,
+		},
+		{
+			name:      "other path",
+			status:    StatusApproved,
+			expiresAt: at.Add(time.Hour),
+			params:    with(func(p *ExecuteParams) { p.Path = "/api/admin/recordings/other" }),
+			wantErr:   ErrRequestMismatch,
+		},
+		{
+			name:      "other body",
+			status:    StatusApproved,
+			expiresAt: at.Add(time.Hour),
+			params:    with(func(p *ExecuteParams) { p.BodyDigest = other[:] }),
+			wantErr:   ErrRequestMismatch,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			a := &Approval{
+				Method:     request.Method,
+				Path:       request.Path,
+				Initiator:  "alice",
+				Approver:   "bob",
+				Status:     tt.status,
+				BodyDigest: digest[:],
+				ExpiresAt:  tt.expiresAt,
+			}
+			err := a.Execute(tt.params, at)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Equal(t, tt.status, a.Status)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, StatusExecuted, a.Status)
+		})
+	}
+}
@@ -0,0 +1,5 @@
+// Package adminapproval provides dual-control domain entities and business rules for the AegisVaultKeeper server.
+//
+// This package implements the approvals destructive admin requests wait for: one operator initiates a request,
+// a second operator approves it, and only then may the initiator execute it, once, before the approval expires.
+package adminapproval
@@ -0,0 +1,36 @@
+package adminapproval
+
+import "errors"
+
+// Dual-control domain error definitions.
+var (
+	// ErrNewApprovalParamsValidation indicates that approval creation parameters failed validation.
+	ErrNewApprovalParamsValidation = errors.New("new admin approval parameters validation failed")
+
+	// ErrIncorrectAction indicates that the guarded action is not specified.
+	ErrIncorrectAction = errors.New("guarded admin action is not specified")
+
+	// ErrIncorrectRequest indicates that the method, path or body digest of the request is missing.
+	ErrIncorrectRequest = errors.New("incorrect guarded admin request")
+
+	// ErrIncorrectOperator indicates that the operator is not specified.
+	ErrIncorrectOperator = errors.New("admin operator is not specified")
+
+	// ErrIncorrectTTL indicates that the approval lifetime is not positive.
+	ErrIncorrectTTL = errors.New("incorrect admin approval lifetime")
+
+	// ErrApprovalNotPending indicates that the approval has already been decided.
+	ErrApprovalNotPending = errors.New("admin approval is already decided")
+
+	// ErrApprovalNotApproved indicates that the request is pending, rejected or already executed.
+	ErrApprovalNotApproved = errors.New("admin request is not approved")
+
+	// ErrApprovalExpired indicates that the request was not approved or executed within its lifetime.
+	ErrApprovalExpired = errors.New("admin approval has expired")
+
+	// ErrSelfApproval indicates that an operator attempted to approve their own request.
+	ErrSelfApproval = errors.New("admin requests cannot be approved by the initiator")
+
+	// ErrRequestMismatch indicates that the executed request or its operator differs from the approved one.
+	ErrRequestMismatch = errors.New("admin request does not match the approved request")
+)
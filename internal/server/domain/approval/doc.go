@@ -0,0 +1,5 @@
+// Package approval provides access approval domain entities and business rules for the AegisVaultKeeper server.
+//
+// This package implements the requests users file to reveal items that require approval, and the rules
+// under which a designated approver grants or denies them.
+package approval
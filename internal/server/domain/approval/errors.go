@@ -0,0 +1,27 @@
+package approval
+
+import "errors"
+
+// Access approval domain error definitions.
+var (
+	// ErrNewRequestParamsValidation indicates that access request creation parameters failed validation.
+	ErrNewRequestParamsValidation = errors.New("new access request parameters validation failed")
+
+	// ErrIncorrectItem indicates that the requested item is not specified.
+	ErrIncorrectItem = errors.New("requested item is not specified")
+
+	// ErrIncorrectReason indicates that the request reason is too long.
+	ErrIncorrectReason = errors.New("incorrect access request reason")
+
+	// ErrIncorrectTTL indicates that the request lifetime is not positive.
+	ErrIncorrectTTL = errors.New("incorrect access request lifetime")
+
+	// ErrRequestNotPending indicates that the request has already been decided.
+	ErrRequestNotPending = errors.New("access request is already decided")
+
+	// ErrRequestExpired indicates that the request was not decided within its lifetime.
+	ErrRequestExpired = errors.New("access request has expired")
+
+	// ErrSelfApproval indicates that an approver attempted to decide their own request.
+	ErrSelfApproval = errors.New("access requests cannot be decided by the requester")
+)
@@ -0,0 +1,164 @@
+package approval
+
+import (
+	"errors"
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// maxReasonLen is the maximum length of a request reason in characters.
+const maxReasonLen = 255
+
+// Status names the state of an access request.
+type Status string
+
+// Access request states.
+const (
+	// StatusPending means the request awaits the decision of an approver.
+	StatusPending Status = "pending"
+	// StatusApproved means an approver granted the request; the item can be revealed until it expires.
+	StatusApproved Status = "approved"
+	// StatusDenied means an approver rejected the request.
+	StatusDenied Status = "denied"
+	// StatusExpired means the request was not decided in time, or the access it granted has ended.
+	// It is never stored; State derives it from the expiry time.
+	StatusExpired Status = "expired"
+)
+
+// Request represents the request of a user to reveal one of their items that requires approval.
+type Request struct {
+	// CreatedAt contains the timestamp when the request was filed.
+	CreatedAt time.Time
+	// DecidedAt contains the timestamp of the decision; zero while the request is pending.
+	DecidedAt time.Time
+	// ExpiresAt contains the deadline of the decision while pending and the end of the granted access once approved.
+	ExpiresAt time.Time
+	// Reason contains the explanation given by the requester.
+	Reason string
+	// Status contains the stored state of the request.
+	Status Status
+	// ID uniquely identifies this request.
+	ID uuid.UUID
+	// ItemID identifies the requested item.
+	ItemID uuid.UUID
+	// UserID identifies the requester, who owns the item.
+	UserID uuid.UUID
+	// ApproverID identifies the approver who decided the request; uuid.Nil while the request is pending.
+	ApproverID uuid.UUID
+}
+
+// NewRequest creates a pending access request with the provided parameters after validation.
+func NewRequest(params NewRequestParams) (*Request, error) {
+	if err := params.Validate(); err != nil {
+		return nil, errors.Join(ErrNewRequestParamsValidation, err)
+	}
+	now := time.Now()
+	return &Request{
+		ID:        uuid.New(),
+		ItemID:    params.ItemID,
+		UserID:    params.UserID,
+		Reason:    params.Reason,
+		Status:    StatusPending,
+		CreatedAt: now,
+		ExpiresAt: now.Add(params.TTL),
+	}, nil
+}
+
+// State returns the state of the request at the moment, reporting pending and approved requests past
+// their expiry time as expired.
+func (r *Request) State(at time.Time) Status {
+	if (r.Status == StatusPending || r.Status == StatusApproved) && !at.Before(r.ExpiresAt) {
+		return StatusExpired
+	}
+	return r.Status
+}
+
+// Grants reports whether the request permits revealing its item at the moment.
+func (r *Request) Grants(at time.Time) bool {
+	return r.State(at) == StatusApproved
+}
+
+// Decide records the decision of an approver at the moment. An approved request grants access for the TTL.
+func (r *Request) Decide(approverID uuid.UUID, approve bool, ttl time.Duration, at time.Time) error {
+	switch state := r.State(at); {
+	case state == StatusExpired && r.Status == StatusPending:
+		return ErrRequestExpired
+	case state != StatusPending:
+		return fmt.Errorf("request is %s: %w", state, ErrRequestNotPending)
+	case approverID == r.UserID:
+		return ErrSelfApproval
+	case approve && ttl <= 0:
+		return fmt.Errorf("access lifetime must be positive: %w", ErrIncorrectTTL)
+	}
+
+	r.ApproverID = approverID
+	r.DecidedAt = at
+	if approve {
+		r.Status = StatusApproved
+		r.ExpiresAt = at.Add(ttl)
+	} else {
+		r.Status = StatusDenied
+	}
+	return nil
+}
+
+// NewRequestParams contains parameters for filing an access request.
+type NewRequestParams struct {
+	// Reason contains the explanation shown to approvers, up to 255 characters.
+	Reason string
+	// ItemID identifies the requested item (required).
+	ItemID uuid.UUID
+	// UserID identifies the requester, who owns the item.
+	UserID uuid.UUID
+	// TTL specifies how long the request may await a decision.
+	TTL time.Duration
+}
+
+// Validate checks that the access request parameters are valid.
+func (p *NewRequestParams) Validate() error {
+	validations := []func() error{
+		p.validateItem,
+		p.validateReason,
+		p.validateTTL,
+	}
+
+	// errs collects all validation errors encountered during request validation.
+	var errs []error
+	for _, fn := range validations {
+		if err := fn(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) != 0 {
+		return errors.Join(errs...)
+	}
+	return nil
+}
+
+// validateItem ensures that the requested item is specified.
+func (p *NewRequestParams) validateItem() error {
+	if p.ItemID == uuid.Nil {
+		return ErrIncorrectItem
+	}
+	return nil
+}
+
+// validateReason ensures that the reason is not longer than allowed.
+func (p *NewRequestParams) validateReason() error {
+	if utf8.RuneCountInString(p.Reason) > maxReasonLen {
+		return fmt.Errorf("reason exceeds %d characters: %w", maxReasonLen, ErrIncorrectReason)
+	}
+	return nil
+}
+
+// validateTTL ensures that the request lifetime is positive.
+func (p *NewRequestParams) validateTTL() error {
+	if p.TTL <= 0 {
+		return ErrIncorrectTTL
+	}
+	return nil
+}
@@ -0,0 +1,187 @@
+package approval
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRequest(t *testing.T) {
+	t.Parallel()
+
+	itemID, userID := uuid.New(), uuid.New()
+
+	tests := []struct {
+		wantErr error
+		name    string
+		params  NewRequestParams
+	}{
+		{
+			name:   "valid request",
+			params: NewRequestParams{ItemID: itemID, UserID: userID, Reason: "quarterly audit", TTL: time.Hour},
+		},
+		{
+			name:   "without reason",
+			params: NewRequestParams{ItemID: itemID, UserID: userID, TTL: time.Hour},
+		},
+		{
+			name:    "missing item",
+			params:  NewRequestParams{UserID: userID, TTL: time.Hour},
+			wantErr: ErrIncorrectItem,
+		},
+		{
+			name:    "reason too long",
+			params:  NewRequestParams{ItemID: itemID, UserID: userID, Reason: strings.Repeat("a", 256), TTL: time.Hour},
+			wantErr: ErrIncorrectReason,
+		},
+		{
+			name:    "zero lifetime",
+			params:  NewRequestParams{ItemID: itemID, UserID: userID},
+			wantErr: ErrIncorrectTTL,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := NewRequest(tt.params)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, ErrNewRequestParamsValidation)
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, got)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotEqual(t, uuid.Nil, got.ID)
+			assert.Equal(t, tt.params.ItemID, got.ItemID)
+			assert.Equal(t, tt.params.UserID, got.UserID)
+			assert.Equal(t, tt.params.Reason, got.Reason)
+			assert.Equal(t, StatusPending, got.Status)
+			assert.Equal(t, uuid.Nil, got.ApproverID)
+			assert.Equal(t, tt.params.TTL, got.ExpiresAt.Sub(got.CreatedAt))
+		})
+	}
+}
+
+func TestRequest_State(t *testing.T) {
+	t.Parallel()
+
+	expiresAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		status     Status
+		at         time.Time
+		wantState  Status
+		wantGrants bool
+	}{
+		{name: "pending", status: StatusPending, at: expiresAt.Add(-time.Minute), wantState: StatusPending},
+		{name: "pending expired", status: StatusPending, at: expiresAt, wantState: StatusExpired},
+		{
+			name:       "approved",
+			status:     StatusApproved,
+			at:         expiresAt.Add(-time.Minute),
+			wantState:  StatusApproved,
+			wantGrants: true,
+		},
+		{name: "approved expired", status: StatusApproved, at: expiresAt.Add(time.Minute), wantState: StatusExpired},
+		{name: "denied", status: StatusDenied, at: expiresAt.Add(time.Minute), wantState: StatusDenied},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := &Request{Status: tt.status, ExpiresAt: expiresAt}
+
+			assert.Equal(t, tt.wantState, r.State(tt.at))
+			assert.Equal(t, tt.wantGrants, r.Grants(tt.at))
+		})
+	}
+}
+
+func TestRequest_Decide(t *testing.T) {
+	t.Parallel()
+
+	userID, approverID := uuid.New(), uuid.New()
+	createdAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	decidedAt := createdAt.Add(10 * time.Minute)
+
+	tests := []struct {
+		wantErr       error
+		name          string
+		status        Status
+		wantStatus    Status
+		wantExpiresAt time.Time
+		approverID    uuid.UUID
+		approve       bool
+	}{
+		{
+			name:          "approve",
+			status:        StatusPending,
+			approverID:    approverID,
+			approve:       true,
+			wantStatus:    StatusApproved,
+			wantExpiresAt: decidedAt.Add(15 * time.Minute),
+		},
+		{
+			name:          "deny",
+			status:        StatusPending,
+			approverID:    approverID,
+			wantStatus:    StatusDenied,
+			wantExpiresAt: createdAt.Add(time.Hour),
+		},
+		{name: "own request", status: StatusPending, approverID: userID, approve: true, wantErr: ErrSelfApproval},
+		{
+			name:       "already approved",
+			status:     StatusApproved,
+			approverID: approverID,
+			approve:    true,
+			wantErr:    ErrRequestNotPending,
+		},
+		{name: "already denied", status: StatusDenied, approverID: approverID, wantErr: ErrRequestNotPending},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := &Request{
+				Status:    tt.status,
+				UserID:    userID,
+				CreatedAt: createdAt,
+				ExpiresAt: createdAt.Add(time.Hour),
+			}
+
+			err := r.Decide(tt.approverID, tt.approve, 15*time.Minute, decidedAt)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Equal(t, tt.status, r.Status)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, r.Status)
+			assert.Equal(t, tt.approverID, r.ApproverID)
+			assert.Equal(t, decidedAt, r.DecidedAt)
+			assert.Equal(t, tt.wantExpiresAt, r.ExpiresAt)
+		})
+	}
+
+	t.Run("expired", func(t *testing.T) {
+		t.Parallel()
+
+		r := &Request{Status: StatusPending, UserID: userID, ExpiresAt: createdAt.Add(time.Hour)}
+
+		err := r.Decide(approverID, true, 15*time.Minute, createdAt.Add(2*time.Hour))
+
+		require.ErrorIs(t, err, ErrRequestExpired)
+		assert.Equal(t, StatusPending, r.Status)
+	})
+}
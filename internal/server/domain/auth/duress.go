@@ -0,0 +1,96 @@
+package auth
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// duressLoginPrefix prefixes the logins of decoy vault profiles; profiles are never looked up by login.
+const duressLoginPrefix = "duress:"
+
+// NewDuressVault creates the decoy vault profile of the user and links it to the user. The profile is stored
+// as a user of its own with separate key material, so the items of the decoy vault are kept apart from the
+// items of the real one and are encrypted with a different key.
+func (u *User) NewDuressVault(cryptoKeyGen CryptoKeyGenerator) (*User, error) {
+	if u.IsDuressVault() {
+		return nil, ErrDuressVaultNested
+	}
+
+	cryptoKey, err := cryptoKeyGen.CryptoKeyGenerate(cryptoKeySize)
+	if err != nil {
+		return nil, errors.Join(ErrCryptoKeyGenerate, err)
+	}
+
+	id := uuid.New()
+	u.DuressVaultID = id
+	return &User{
+		ID:        id,
+		Login:     duressLoginPrefix + id.String(),
+		CryptoKey: cryptoKey,
+		ProfileOf: u.ID,
+		Active:    true,
+	}, nil
+}
+
+// SetDuressPassword validates the duress password and stores its hash on the user and as the password of
+// the decoy vault profile, so account changes in duress sessions are confirmed with the duress password.
+// Alert selects whether logins with the duress password raise a silent security alert.
+func (u *User) SetDuressPassword(decoy *User, hasher PasswordHasher, password string, alert bool) error {
+	if decoy.ID != u.DuressVaultID || decoy.ProfileOf != u.ID {
+		return ErrDuressVaultMismatch
+	}
+
+	params := NewUserParams{Password: password}
+	if err := params.validatePassword(); err != nil {
+		return err
+	}
+	passwordHash, err := hasher.PasswordHash(password)
+	if err != nil {
+		return errors.Join(ErrPasswordHash, err)
+	}
+
+	u.DuressPasswordHash = passwordHash
+	u.DuressAlert = alert
+	decoy.PasswordHash = passwordHash
+	return nil
+}
+
+// ClearDuressPassword turns duress logins off and revokes the sessions the decoy vault was given before the
+// specified moment, so access tokens of earlier duress logins stop being accepted. The decoy vault is kept and
+// opens again once a new duress password is set. Decoy is nil when the user has no decoy vault.
+func (u *User) ClearDuressPassword(decoy *User, at time.Time) error {
+	if decoy != nil {
+		if decoy.ID != u.DuressVaultID || decoy.ProfileOf != u.ID {
+			return ErrDuressVaultMismatch
+		}
+		decoy.RevokeSessions(at)
+	}
+	u.DuressPasswordHash = ""
+	u.DuressAlert = false
+	return nil
+}
+
+// HasDuressPassword reports whether logins with the duress password open the decoy vault of the user.
+func (u *User) HasDuressPassword() bool {
+	return u.DuressPasswordHash != "" && u.DuressVaultID != uuid.Nil
+}
+
+// IsDuressVault reports whether the user is the decoy vault profile of another user.
+func (u *User) IsDuressVault() bool {
+	return u.ProfileOf != uuid.Nil
+}
+
+// VerifyDuressPassword verifies if the provided password matches the duress password of the user.
+// Users without a duress password match no password.
+func (u *User) VerifyDuressPassword(verificator PasswordVerificator, password string) (bool, error) {
+	if !u.HasDuressPassword() {
+		return false, nil
+	}
+	verified, err := verificator.PasswordVerify(u.DuressPasswordHash, password)
+	if err != nil {
+		return false, errors.Join(ErrPasswordVerificationFailed, err)
+	}
+	return verified, nil
+}
@@ -0,0 +1,197 @@
+package auth
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUser_NewDuressVault(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		gen     *mockCryptoKeyGenerator
+		wantErr error
+		name    string
+		decoy   bool
+	}{
+		{name: "decoy vault created", gen: &mockCryptoKeyGenerator{}},
+		{name: "decoy of a decoy", gen: &mockCryptoKeyGenerator{}, decoy: true, wantErr: ErrDuressVaultNested},
+		{
+			name: "key generation failure",
+			gen: &mockCryptoKeyGenerator{generateFunc: func(int) ([]byte, error) {
+				return nil, errors.New("no entropy")
+			}},
+			wantErr: ErrCryptoKeyGenerate,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			u := &User{ID: uuid.New(), Login: "alice", CryptoKey: []byte("real-key"), Active: true}
+			if tt.decoy {
+				u.ProfileOf = uuid.New()
+			}
+
+			decoy, err := u.NewDuressVault(tt.gen)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Equal(t, uuid.Nil, u.DuressVaultID)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, decoy.ID, u.DuressVaultID)
+			assert.Equal(t, u.ID, decoy.ProfileOf)
+			assert.True(t, decoy.IsDuressVault())
+			assert.False(t, u.IsDuressVault())
+			assert.True(t, decoy.Active)
+			assert.NotEqual(t, u.Login, decoy.Login)
+			assert.Len(t, decoy.CryptoKey, cryptoKeySize)
+		})
+	}
+}
+
+func TestUser_SetDuressPassword(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		wantErr  error
+		name     string
+		password string
+		foreign  bool
+	}{
+		{name: "valid duress password", password: "duresspassword"},
+		{name: "too short", password: "short", wantErr: ErrIncorrectPassword},
+		{name: "decoy of another user", password: "duresspassword", foreign: true, wantErr: ErrDuressVaultMismatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			u := &User{ID: uuid.New(), PasswordHash: "hashed_password"}
+			decoy, err := u.NewDuressVault(&mockCryptoKeyGenerator{})
+			require.NoError(t, err)
+			if tt.foreign {
+				decoy.ProfileOf = uuid.New()
+			}
+
+			err = u.SetDuressPassword(decoy, &mockPasswordHasher{}, tt.password, true)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.False(t, u.HasDuressPassword())
+				assert.Empty(t, decoy.PasswordHash)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, u.HasDuressPassword())
+			assert.True(t, u.DuressAlert)
+			assert.Equal(t, "hashed_"+tt.password, u.DuressPasswordHash)
+			assert.Equal(t, u.DuressPasswordHash, decoy.PasswordHash)
+			assert.Equal(t, "hashed_password", u.PasswordHash)
+
+			require.NoError(t, u.ClearDuressPassword(decoy, time.Now()))
+			assert.False(t, u.HasDuressPassword())
+			assert.False(t, u.DuressAlert)
+			assert.Equal(t, decoy.ID, u.DuressVaultID)
+		})
+	}
+}
+
+func TestUser_ClearDuressPassword(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		wantErr     error
+		name        string
+		noDecoy     bool
+		foreign     bool
+		wantRevoked bool
+	}{
+		{name: "decoy sessions revoked", wantRevoked: true},
+		{name: "no decoy vault", noDecoy: true},
+		{name: "decoy of another user", foreign: true, wantErr: ErrDuressVaultMismatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			u := &User{ID: uuid.New(), PasswordHash: "hashed_password"}
+			decoy, err := u.NewDuressVault(&mockCryptoKeyGenerator{})
+			require.NoError(t, err)
+			require.NoError(t, u.SetDuressPassword(decoy, &mockPasswordHasher{}, "duresspassword", true))
+			if tt.foreign {
+				decoy.ProfileOf = uuid.New()
+			}
+			// cleared holds the decoy vault passed to ClearDuressPassword.
+			cleared := decoy
+			if tt.noDecoy {
+				cleared = nil
+			}
+			issuedAt := time.Now().Add(-time.Minute)
+
+			err = u.ClearDuressPassword(cleared, time.Now())
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.True(t, u.HasDuressPassword())
+				assert.False(t, decoy.SessionRevoked(issuedAt))
+				return
+			}
+			require.NoError(t, err)
+			assert.False(t, u.HasDuressPassword())
+			assert.Equal(t, tt.wantRevoked, decoy.SessionRevoked(issuedAt))
+		})
+	}
+}
+
+func TestUser_VerifyDuressPassword(t *testing.T) {
+	t.Parallel()
+
+	verificator := &mockPasswordVerificator{verifyFunc: func(hashedData, verifyingData string) (bool, error) {
+		return hashedData == "hashed_"+verifyingData, nil
+	}}
+
+	tests := []struct {
+		user     *User
+		name     string
+		password string
+		want     bool
+	}{
+		{
+			name:     "duress password",
+			user:     &User{DuressPasswordHash: "hashed_duresspassword", DuressVaultID: uuid.New()},
+			password: "duresspassword",
+			want:     true,
+		},
+		{
+			name:     "other password",
+			user:     &User{DuressPasswordHash: "hashed_duresspassword", DuressVaultID: uuid.New()},
+			password: "password",
+		},
+		{
+			name:     "no duress password",
+			user:     &User{PasswordHash: "hashed_password", DuressVaultID: uuid.New()},
+			password: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := tt.user.VerifyDuressPassword(verificator, tt.password)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
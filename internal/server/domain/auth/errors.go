@@ -19,6 +19,39 @@ var (
 	// ErrIncorrectPassword indicates the password format or length is incorrect.
 	ErrIncorrectPassword = errors.New("incorrect password")
 
+	// ErrPasswordTooShort indicates a password shorter than the policy minimum.
+	ErrPasswordTooShort = errors.New("password too short")
+
+	// ErrPasswordTooLong indicates a password longer than the maximum length.
+	ErrPasswordTooLong = errors.New("password too long")
+
+	// ErrPasswordNoLower indicates a password without a lower-case letter required by the policy.
+	ErrPasswordNoLower = errors.New("password lacks a lower-case letter")
+
+	// ErrPasswordNoUpper indicates a password without an upper-case letter required by the policy.
+	ErrPasswordNoUpper = errors.New("password lacks an upper-case letter")
+
+	// ErrPasswordNoDigit indicates a password without a digit required by the policy.
+	ErrPasswordNoDigit = errors.New("password lacks a digit")
+
+	// ErrPasswordNoSymbol indicates a password without a symbol required by the policy.
+	ErrPasswordNoSymbol = errors.New("password lacks a symbol")
+
+	// ErrPasswordCommon indicates a common or banned password.
+	ErrPasswordCommon = errors.New("password too common")
+
+	// ErrPasswordReused indicates a password matching one of the recent passwords of the user.
+	ErrPasswordReused = errors.New("password used recently")
+
 	// ErrPasswordVerificationFailed indicates password verification failed.
 	ErrPasswordVerificationFailed = errors.New("password verification failed")
+
+	// ErrRecoveryCodeGenerate indicates failure to generate recovery codes.
+	ErrRecoveryCodeGenerate = errors.New("failed to generate recovery codes")
+
+	// ErrDuressVaultNested indicates an attempt to give a decoy vault profile a decoy vault of its own.
+	ErrDuressVaultNested = errors.New("decoy vaults cannot have a duress password")
+
+	// ErrDuressVaultMismatch indicates a decoy vault profile that does not belong to the user.
+	ErrDuressVaultMismatch = errors.New("decoy vault belongs to another user")
 )
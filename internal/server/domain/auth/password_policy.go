@@ -0,0 +1,203 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+const (
+	// MinPasswordLength defines the smallest minimum length a password policy may set.
+	MinPasswordLength = passwordMinLen
+
+	// MaxPasswordLength defines the length no password may exceed.
+	MaxPasswordLength = passwordMaxLen
+)
+
+// MaxPasswordHistory defines how many recent passwords a password policy may forbid reusing at most.
+// Every remembered password costs a hash verification on each password change.
+const MaxPasswordHistory = 24
+
+// PasswordClass identifies a character class passwords may be required to contain.
+type PasswordClass string
+
+const (
+	// PasswordClassLower requires a lower-case letter.
+	PasswordClassLower PasswordClass = "lower"
+	// PasswordClassUpper requires an upper-case letter.
+	PasswordClassUpper PasswordClass = "upper"
+	// PasswordClassDigit requires a digit.
+	PasswordClassDigit PasswordClass = "digit"
+	// PasswordClassSymbol requires a character that is neither a letter nor a digit.
+	PasswordClassSymbol PasswordClass = "symbol"
+)
+
+// passwordClasses maps each character class to its membership test and the error of passwords lacking it.
+var passwordClasses = map[PasswordClass]struct {
+	// missing is the violation of passwords without a character of the class.
+	missing error
+	// has reports whether the character belongs to the class.
+	has func(r rune) bool
+}{
+	PasswordClassLower:  {missing: ErrPasswordNoLower, has: unicode.IsLower},
+	PasswordClassUpper:  {missing: ErrPasswordNoUpper, has: unicode.IsUpper},
+	PasswordClassDigit:  {missing: ErrPasswordNoDigit, has: unicode.IsDigit},
+	PasswordClassSymbol: {missing: ErrPasswordNoSymbol, has: isPasswordSymbol},
+}
+
+// ParsePasswordClass parses a character class name: lower, upper, digit or symbol.
+func ParsePasswordClass(name string) (PasswordClass, error) {
+	class := PasswordClass(strings.ToLower(strings.TrimSpace(name)))
+	if _, ok := passwordClasses[class]; !ok {
+		return "", fmt.Errorf("unknown password character class %q", name)
+	}
+	return class, nil
+}
+
+// In reports whether the password contains a character of the class.
+func (c PasswordClass) In(password string) bool {
+	rule, ok := passwordClasses[c]
+	return ok && strings.ContainsFunc(password, rule.has)
+}
+
+// IsCommonPassword reports whether the password, ignoring case, is one of the widely used passwords dictionary
+// attacks try first.
+func IsCommonPassword(password string) bool {
+	return slices.Contains(commonPasswords, strings.ToLower(password))
+}
+
+// PasswordPolicyParams contains parameters for creating a password policy.
+type PasswordPolicyParams struct {
+	// Banned lists passwords rejected in addition to the common ones, matched ignoring case.
+	Banned []string
+	// RequiredClasses lists the character classes every password must contain.
+	RequiredClasses []PasswordClass
+	// MinLength specifies the minimum password length; values below 8 use 8, values above 64 use 64.
+	MinLength int
+	// HistorySize specifies how many recent passwords of the user, the current one included, may not be reused
+	// (0 allows reuse; capped at MaxPasswordHistory).
+	HistorySize int
+	// BanCommon determines whether the built-in list of common passwords is rejected.
+	BanCommon bool
+}
+
+// PasswordPolicy holds the rules user passwords must follow. The zero value only enforces the length limits.
+type PasswordPolicy struct {
+	// banned holds the lower-cased rejected passwords.
+	banned map[string]struct{}
+	// classes lists the character classes every password must contain.
+	classes []PasswordClass
+	// minLength specifies the minimum password length; zero uses the default minimum.
+	minLength int
+	// historySize specifies how many recent passwords may not be reused.
+	historySize int
+}
+
+// NewPasswordPolicy creates a password policy with the provided parameters.
+func NewPasswordPolicy(params PasswordPolicyParams) PasswordPolicy {
+	p := PasswordPolicy{
+		minLength:   min(max(params.MinLength, passwordMinLen), passwordMaxLen),
+		historySize: min(max(params.HistorySize, 0), MaxPasswordHistory),
+	}
+	for _, class := range params.RequiredClasses {
+		if _, ok := passwordClasses[class]; ok && !slices.Contains(p.classes, class) {
+			p.classes = append(p.classes, class)
+		}
+	}
+
+	banned := params.Banned
+	if params.BanCommon {
+		banned = append(slices.Clone(commonPasswords), banned...)
+	}
+	for _, password := range banned {
+		if password = strings.TrimSpace(password); password != "" {
+			if p.banned == nil {
+				p.banned = make(map[string]struct{}, len(banned))
+			}
+			p.banned[strings.ToLower(password)] = struct{}{}
+		}
+	}
+	return p
+}
+
+// HistorySize returns how many recent passwords of the user, the current one included, may not be reused.
+func (p PasswordPolicy) HistorySize() int {
+	return p.historySize
+}
+
+// Check validates the password against the policy. Every violation is reported, joined with
+// ErrIncorrectPassword.
+func (p PasswordPolicy) Check(password string) error {
+	// violations collects the rules the password breaks.
+	var violations []error
+
+	minLength := p.minLength
+	if minLength == 0 {
+		minLength = passwordMinLen
+	}
+	switch {
+	case len(password) < minLength:
+		violations = append(violations, ErrPasswordTooShort)
+	case len(password) > passwordMaxLen:
+		violations = append(violations, ErrPasswordTooLong)
+	}
+
+	for _, class := range p.classes {
+		rule := passwordClasses[class]
+		if !strings.ContainsFunc(password, rule.has) {
+			violations = append(violations, rule.missing)
+		}
+	}
+
+	if _, ok := p.banned[strings.ToLower(password)]; ok {
+		violations = append(violations, ErrPasswordCommon)
+	}
+
+	if len(violations) != 0 {
+		return errors.Join(append([]error{ErrIncorrectPassword}, violations...)...)
+	}
+	return nil
+}
+
+// reused reports whether the password matches the current password of the user or one of the previous ones
+// remembered by the policy.
+func (p PasswordPolicy) reused(u *User, verificator PasswordVerificator, password string) (bool, error) {
+	if p.historySize == 0 {
+		return false, nil
+	}
+	recent := append([]string{u.PasswordHash}, u.PasswordHistory...)
+	for _, hash := range recent[:min(len(recent), p.historySize)] {
+		if hash == "" {
+			continue
+		}
+		ok, err := verificator.PasswordVerify(hash, password)
+		if err != nil {
+			return false, errors.Join(ErrPasswordVerificationFailed, err)
+		}
+		if ok {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// isPasswordSymbol reports whether the character is neither a letter nor a digit, spaces included.
+func isPasswordSymbol(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
+
+// commonPasswords lists widely used passwords long enough to pass the length limits, which dictionary attacks
+// try first.
+var commonPasswords = []string{
+	"00000000", "11111111", "12121212", "12341234", "12344321", "123123123", "12345678", "123456789",
+	"1234567890", "1234qwer", "123qweasd", "1q2w3e4r", "1q2w3e4r5t", "1qaz2wsx", "87654321", "88888888",
+	"987654321", "aa123456", "abc12345", "abcd1234", "access14", "admin123", "administrator", "asdfghjk",
+	"asdfghjkl", "baseball", "basketball", "butterfly", "changeme", "charlie1", "chocolate", "computer",
+	"dragon123", "football", "iloveyou", "iloveyou1", "jennifer", "letmein1", "letmein123", "liverpool",
+	"master123", "michelle", "monkey123", "p@ssw0rd", "p@ssword", "passw0rd", "password", "password!",
+	"password1", "password1!", "password12", "password123", "princess", "q1w2e3r4", "q1w2e3r4t5", "qazwsxedc",
+	"qwer1234", "qwerty12", "qwerty123", "qwertyui", "qwertyuiop", "shadow123", "starwars", "sunshine",
+	"superman", "trustno1", "welcome1", "welcome123", "whatever", "zaq12wsx", "zxcvbnm1", "zxcvbnmasdf",
+}
@@ -0,0 +1,252 @@
+package auth
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePasswordClass(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		input   string
+		want    PasswordClass
+		wantErr bool
+	}{
+		{name: "lower", input: "lower", want: PasswordClassLower},
+		{name: "upper with spaces and case", input: " Upper ", want: PasswordClassUpper},
+		{name: "digit", input: "digit", want: PasswordClassDigit},
+		{name: "symbol", input: "SYMBOL", want: PasswordClassSymbol},
+		{name: "unknown", input: "emoji", wantErr: true},
+		{name: "empty", input: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParsePasswordClass(tt.input)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestPasswordPolicy_Check(t *testing.T) {
+	t.Parallel()
+
+	strict := NewPasswordPolicy(PasswordPolicyParams{
+		Banned: []string{" Company2024! ", ""},
+		RequiredClasses: []PasswordClass{
+			PasswordClassLower, PasswordClassUpper, PasswordClassDigit, PasswordClassSymbol, PasswordClassLower,
+		},
+		MinLength: 12,
+		BanCommon: true,
+	})
+
+	tests := []struct {
+		wantErrs []error
+		policy   PasswordPolicy
+		name     string
+		password string
+	}{
+		{
+			name:     "zero policy accepts minimum length",
+			password: "12345678",
+		},
+		{
+			name:     "zero policy accepts common passwords",
+			password: "password123",
+		},
+		{
+			name:     "zero policy rejects short password",
+			password: "1234567",
+			wantErrs: []error{ErrPasswordTooShort},
+		},
+		{
+			name:     "zero policy rejects long password",
+			password: strings.Repeat("a", 65),
+			wantErrs: []error{ErrPasswordTooLong},
+		},
+		{
+			name:     "strict policy accepts strong password",
+			policy:   strict,
+			password: "Correct-Horse-42",
+		},
+		{
+			name:     "strict policy reports every violation",
+			policy:   strict,
+			password: "short",
+			wantErrs: []error{ErrPasswordTooShort, ErrPasswordNoUpper, ErrPasswordNoDigit, ErrPasswordNoSymbol},
+		},
+		{
+			name:     "common password ignoring case",
+			policy:   NewPasswordPolicy(PasswordPolicyParams{BanCommon: true}),
+			password: "PassWord123",
+			wantErrs: []error{ErrPasswordCommon},
+		},
+		{
+			name:     "strict policy rejects banned password",
+			policy:   strict,
+			password: "company2024!",
+			wantErrs: []error{ErrPasswordNoUpper, ErrPasswordCommon},
+		},
+		{
+			name:     "symbol class counts spaces",
+			policy:   NewPasswordPolicy(PasswordPolicyParams{RequiredClasses: []PasswordClass{PasswordClassSymbol}}),
+			password: "correct horse",
+		},
+		{
+			name:     "minimum length below default uses default",
+			policy:   NewPasswordPolicy(PasswordPolicyParams{MinLength: 4}),
+			password: "1234567",
+			wantErrs: []error{ErrPasswordTooShort},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.policy.Check(tt.password)
+			if len(tt.wantErrs) == 0 {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, ErrIncorrectPassword)
+			for _, want := range tt.wantErrs {
+				assert.ErrorIs(t, err, want)
+			}
+			assert.Len(t, err.(interface{ Unwrap() []error }).Unwrap(), len(tt.wantErrs)+1)
+		})
+	}
+}
+
+func TestNewPasswordPolicy_HistorySize(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, 0, NewPasswordPolicy(PasswordPolicyParams{HistorySize: -1}).HistorySize())
+	assert.Equal(t, 5, NewPasswordPolicy(PasswordPolicyParams{HistorySize: 5}).HistorySize())
+	assert.Equal(t, MaxPasswordHistory, NewPasswordPolicy(PasswordPolicyParams{HistorySize: 100}).HistorySize())
+}
+
+func TestUser_ChangePassword(t *testing.T) {
+	t.Parallel()
+
+	verifyErr := errors.New("verify failed")
+
+	tests := []struct {
+		verificator PasswordVerificator
+		wantErr     error
+		history     []string
+		wantHistory []string
+		name        string
+		password    string
+		wantHash    string
+		historySize int
+	}{
+		{
+			name:        "without history",
+			verificator: &mockPasswordVerificator{},
+			history:     []string{"hashed_older"},
+			password:    "current1",
+			wantHash:    "hashed_current1",
+		},
+		{
+			name:        "remembers replaced hash",
+			verificator: &mockPasswordVerificator{},
+			history:     []string{"hashed_older", "hashed_oldest"},
+			password:    "brand-new",
+			wantHash:    "hashed_brand-new",
+			wantHistory: []string{"hashed_current1", "hashed_older"},
+			historySize: 3,
+		},
+		{
+			name:        "rejects current password",
+			verificator: &mockPasswordVerificator{},
+			password:    "current1",
+			wantHash:    "hashed_current1",
+			wantErr:     ErrPasswordReused,
+			historySize: 1,
+		},
+		{
+			name:        "rejects remembered password",
+			verificator: &mockPasswordVerificator{},
+			password:    "older123",
+			wantHash:    "hashed_current1",
+			wantErr:     ErrPasswordReused,
+			history:     []string{"hashed_older123"},
+			historySize: 2,
+		},
+		{
+			name:        "allows forgotten password",
+			verificator: &mockPasswordVerificator{},
+			history:     []string{"hashed_older123", "hashed_oldest1"},
+			password:    "oldest1x",
+			wantHash:    "hashed_oldest1x",
+			wantHistory: []string{"hashed_current1"},
+			historySize: 2,
+		},
+		{
+			name:        "policy violation",
+			verificator: &mockPasswordVerificator{},
+			password:    "short",
+			wantHash:    "hashed_current1",
+			wantErr:     ErrPasswordTooShort,
+			historySize: 2,
+		},
+		{
+			name: "verification failure",
+			verificator: &mockPasswordVerificator{verifyFunc: func(string, string) (bool, error) {
+				return false, verifyErr
+			}},
+			password:    "brand-new",
+			wantHash:    "hashed_current1",
+			wantErr:     verifyErr,
+			historySize: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			u := &User{PasswordHash: "hashed_current1", PasswordHistory: tt.history}
+			policy := NewPasswordPolicy(PasswordPolicyParams{HistorySize: tt.historySize})
+
+			err := u.ChangePassword(&mockPasswordHasher{}, tt.verificator, policy, tt.password)
+
+			require.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.wantHash, u.PasswordHash)
+			if tt.wantErr == nil {
+				assert.Equal(t, tt.wantHistory, u.PasswordHistory)
+			}
+		})
+	}
+}
+
+func TestPasswordClass_In(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, PasswordClassLower.In("ABCd"))
+	assert.False(t, PasswordClassUpper.In("abcd"))
+	assert.True(t, PasswordClassDigit.In("abc1"))
+	assert.True(t, PasswordClassSymbol.In("abc d"))
+	assert.False(t, PasswordClass("emoji").In("abcd"))
+}
+
+func TestIsCommonPassword(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, IsCommonPassword("Password123"))
+	assert.False(t, IsCommonPassword("Correct-Horse-42"))
+}
@@ -0,0 +1,87 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"encoding/base32"
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/consttime"
+)
+
+const (
+	// RecoveryCodeCount defines how many recovery codes a user gets at a time.
+	RecoveryCodeCount = 10
+
+	// recoveryCodeSize defines the number of random bytes in a recovery code.
+	recoveryCodeSize = 10
+
+	// recoveryCodeGroup defines the number of characters between the dashes of a formatted recovery code.
+	recoveryCodeGroup = 4
+)
+
+// recoveryCodeEncoding renders recovery codes in lower case without ambiguous padding.
+var recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// ResetRecoveryCodes replaces the recovery codes of the user with a new set and returns the codes.
+// Only their digests are kept, so the returned codes cannot be shown again; the previous codes stop working.
+func (u *User) ResetRecoveryCodes(gen CryptoKeyGenerator) ([]string, error) {
+	codes := make([]string, 0, RecoveryCodeCount)
+	digests := make([][]byte, 0, RecoveryCodeCount)
+	for range RecoveryCodeCount {
+		raw, err := gen.CryptoKeyGenerate(recoveryCodeSize)
+		if err != nil {
+			return nil, errors.Join(ErrRecoveryCodeGenerate, err)
+		}
+		code := formatRecoveryCode(strings.ToLower(recoveryCodeEncoding.EncodeToString(raw)))
+		codes = append(codes, code)
+		digests = append(digests, HashRecoveryCode(code))
+	}
+
+	u.RecoveryCodes = digests
+	return codes, nil
+}
+
+// UseRecoveryCode consumes the recovery code and reports whether it was one of the unused codes of the user.
+// Codes are matched ignoring case, dashes and spaces.
+func (u *User) UseRecoveryCode(code string) bool {
+	digest := HashRecoveryCode(code)
+	for i, stored := range u.RecoveryCodes {
+		if consttime.Equal(stored, digest) {
+			u.RecoveryCodes = slices.Delete(slices.Clone(u.RecoveryCodes), i, i+1)
+			return true
+		}
+	}
+	return false
+}
+
+// RecoveryCodesLeft returns the number of unused recovery codes of the user.
+func (u *User) RecoveryCodesLeft() int {
+	return len(u.RecoveryCodes)
+}
+
+// HashRecoveryCode returns the SHA-256 digest recovery codes are stored and matched by.
+// Codes are random, so a fast digest does not make them guessable.
+func HashRecoveryCode(code string) []byte {
+	normalized := strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+		return r
+	}, strings.ToLower(strings.TrimSpace(code)))
+	sum := sha256.Sum256([]byte(normalized))
+	return sum[:]
+}
+
+// formatRecoveryCode splits the code into dash-separated groups for readability.
+func formatRecoveryCode(code string) string {
+	var b strings.Builder
+	for i, r := range code {
+		if i > 0 && i%recoveryCodeGroup == 0 {
+			b.WriteByte('-')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,97 @@
+package auth
+
+import (
+	"crypto/rand"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUser_ResetRecoveryCodes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		gen     *mockCryptoKeyGenerator
+		wantErr error
+		name    string
+	}{
+		{
+			name: "codes generated",
+			gen: &mockCryptoKeyGenerator{generateFunc: func(size int) ([]byte, error) {
+				b := make([]byte, size)
+				_, err := rand.Read(b)
+				return b, err
+			}},
+		},
+		{
+			name: "generator failure",
+			gen: &mockCryptoKeyGenerator{generateFunc: func(int) ([]byte, error) {
+				return nil, errors.New("no entropy")
+			}},
+			wantErr: ErrRecoveryCodeGenerate,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			previous := HashRecoveryCode("old-code")
+			u := &User{RecoveryCodes: [][]byte{previous}}
+
+			codes, err := u.ResetRecoveryCodes(tt.gen)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Equal(t, [][]byte{previous}, u.RecoveryCodes)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, codes, RecoveryCodeCount)
+			assert.Equal(t, RecoveryCodeCount, u.RecoveryCodesLeft())
+			for i, code := range codes {
+				assert.Len(t, code, 19)
+				assert.Equal(t, strings.ToLower(code), code)
+				assert.Equal(t, HashRecoveryCode(code), u.RecoveryCodes[i])
+			}
+			assert.False(t, u.UseRecoveryCode("old-code"))
+		})
+	}
+}
+
+func TestUser_UseRecoveryCode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		code     string
+		wantLeft int
+		want     bool
+	}{
+		{name: "exact code", code: "abcd-efgh-ijkl-mnop", want: true, wantLeft: 1},
+		{name: "upper case without dashes", code: " ABCDEFGHIJKLMNOP ", want: true, wantLeft: 1},
+		{name: "grouped with spaces", code: "abcd efgh ijkl mnop", want: true, wantLeft: 1},
+		{name: "unknown code", code: "abcd-efgh-ijkl-mnoq", want: false, wantLeft: 2},
+		{name: "empty code", code: "", want: false, wantLeft: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			u := &User{RecoveryCodes: [][]byte{
+				HashRecoveryCode("abcd-efgh-ijkl-mnop"),
+				HashRecoveryCode("qrst-uvwx-yz23-4567"),
+			}}
+
+			assert.Equal(t, tt.want, u.UseRecoveryCode(tt.code))
+			assert.Equal(t, tt.wantLeft, u.RecoveryCodesLeft())
+			if tt.want {
+				assert.False(t, u.UseRecoveryCode(tt.code), "recovery codes are single-use")
+			}
+		})
+	}
+}
@@ -2,7 +2,9 @@ package auth
 
 import (
 	"errors"
+	"time"
 
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/securebytes"
 	"github.com/google/uuid"
 )
 
@@ -45,14 +47,39 @@ type (
 
 // User represents a user entity in the authentication domain.
 type User struct {
+	// DeprovisionedAt is the moment the user was removed by directory sync; zero while provisioned.
+	DeprovisionedAt time.Time
+	// SessionsRevokedAt is the moment access tokens issued earlier stopped being accepted; zero if never.
+	SessionsRevokedAt time.Time
 	// Login contains the user's unique login identifier.
 	Login string
+	// ExternalID contains the identifier of the user in the enterprise directory, if provisioned from one.
+	ExternalID string
 	// PasswordHash contains the hashed password.
 	PasswordHash string
-	// CryptoKey contains the user-specific encryption key.
+	// PasswordHistory contains the hashes of the previous passwords, most recent first, as many as the password
+	// policy remembers.
+	PasswordHistory []string
+	// DuressPasswordHash contains the hashed duress password opening the decoy vault; empty while duress
+	// logins are off.
+	DuressPasswordHash string
+	// CryptoKey contains the user-specific encryption key, sealed with the unlock key if the user has
+	// an unlock secret.
 	CryptoKey []byte
+	// UnlockSalt contains the salt clients derive the unlock key with; empty while the user has no unlock secret.
+	UnlockSalt []byte
+	// RecoveryCodes contains the SHA-256 digests of the unused recovery codes of the user.
+	RecoveryCodes [][]byte
 	// ID is the unique identifier of the user.
 	ID uuid.UUID
+	// DuressVaultID identifies the decoy vault profile of the user; uuid.Nil until a duress password is set.
+	DuressVaultID uuid.UUID
+	// ProfileOf identifies the user owning this decoy vault profile; uuid.Nil for users.
+	ProfileOf uuid.UUID
+	// Active reports whether the user may log in.
+	Active bool
+	// DuressAlert reports whether logins with the duress password raise a silent security alert.
+	DuressAlert bool
 }
 
 // NewUser creates a new user entity with the provided parameters and dependencies.
@@ -76,11 +103,116 @@ func NewUser(params NewUserParams, hasher PasswordHasher, cryptoKeyGen CryptoKey
 		Login:        params.Login,
 		PasswordHash: passwordHash,
 		CryptoKey:    cryptoKey,
+		Active:       true,
 	}
 
 	return &u, nil
 }
 
+// Wipe zeroes the user crypto key in place once it is no longer needed.
+func (u *User) Wipe() {
+	if u == nil {
+		return
+	}
+	securebytes.Wipe(u.CryptoKey)
+}
+
+// SetLogin changes the user's login after validating its length constraints.
+func (u *User) SetLogin(login string) error {
+	params := NewUserParams{Login: login}
+	if err := params.validateLogin(); err != nil {
+		return err
+	}
+	u.Login = login
+	return nil
+}
+
+// SetPassword validates the password length and replaces the user's password hash. The password history is
+// left as is; ChangePassword enforces the password policy instead.
+func (u *User) SetPassword(hasher PasswordHasher, password string) error {
+	params := NewUserParams{Password: password}
+	if err := params.validatePassword(); err != nil {
+		return err
+	}
+	passwordHash, err := hasher.PasswordHash(password)
+	if err != nil {
+		return errors.Join(ErrPasswordHash, err)
+	}
+	u.PasswordHash = passwordHash
+	return nil
+}
+
+// ChangePassword validates the password against the policy and replaces the user's password hash. Passwords
+// matching one of the recent passwords the policy remembers fail with ErrPasswordReused; the replaced hash is
+// remembered in the password history.
+func (u *User) ChangePassword(
+	hasher PasswordHasher,
+	verificator PasswordVerificator,
+	policy PasswordPolicy,
+	password string,
+) error {
+	if err := policy.Check(password); err != nil {
+		return err
+	}
+	reused, err := policy.reused(u, verificator, password)
+	if err != nil {
+		return err
+	}
+	if reused {
+		return errors.Join(ErrIncorrectPassword, ErrPasswordReused)
+	}
+
+	passwordHash, err := hasher.PasswordHash(password)
+	if err != nil {
+		return errors.Join(ErrPasswordHash, err)
+	}
+	// history holds the hashes of the previous passwords the policy still remembers after the change.
+	var history []string
+	if keep := policy.HistorySize() - 1; keep > 0 {
+		history = append([]string{u.PasswordHash}, u.PasswordHistory...)
+		history = history[:min(len(history), keep)]
+	}
+	u.PasswordHash = passwordHash
+	u.PasswordHistory = history
+	return nil
+}
+
+// Deprovision deactivates the user at the specified moment. The user's vault is retained.
+func (u *User) Deprovision(at time.Time) {
+	u.Active = false
+	u.DeprovisionedAt = at
+}
+
+// Deprovisioned reports whether the user was removed by directory sync.
+func (u *User) Deprovisioned() bool {
+	return !u.DeprovisionedAt.IsZero()
+}
+
+// RevokeSessions invalidates the access tokens issued to the user before the specified moment.
+// Token issue times have a precision of a second, so the moment is truncated to keep tokens issued
+// right after the revocation valid.
+func (u *User) RevokeSessions(at time.Time) {
+	u.SessionsRevokedAt = at.Truncate(time.Second)
+}
+
+// SessionRevoked reports whether an access token issued at the specified moment was revoked.
+func (u *User) SessionRevoked(issuedAt time.Time) bool {
+	return !u.SessionsRevokedAt.IsZero() && issuedAt.Before(u.SessionsRevokedAt)
+}
+
+// HasUnlockSecret reports whether the crypto key is sealed with an unlock key derived from an unlock secret.
+func (u *User) HasUnlockSecret() bool {
+	return len(u.UnlockSalt) != 0
+}
+
+// SetUnlockSecret replaces the crypto key with its copy sealed with the unlock key derived with the salt.
+// The replaced key is wiped.
+func (u *User) SetUnlockSecret(sealedKey, salt []byte) {
+	securebytes.Wipe(u.CryptoKey)
+	u.CryptoKey = sealedKey
+	u.UnlockSalt = salt
+}
+
 // VerifyPassword verifies if the provided password matches the user's stored password.
 func (u *User) VerifyPassword(verificator PasswordVerificator, password string) (bool, error) {
 	verified, err := verificator.PasswordVerify(u.PasswordHash, password)
@@ -96,6 +228,8 @@ type NewUserParams struct {
 	Login string
 	// Password specifies the user's password.
 	Password string
+	// Policy specifies the rules the password must follow; the zero value only enforces the length limits.
+	Policy PasswordPolicy
 }
 
 // Validate validates the new user parameters and returns any validation errors.
@@ -127,10 +261,7 @@ func (up *NewUserParams) validateLogin() error {
 	return nil
 }
 
-// validatePassword validates the password parameter length constraints.
+// validatePassword validates the password parameter against the password policy.
 func (up *NewUserParams) validatePassword() error {
-	if len(up.Password) < passwordMinLen || len(up.Password) > passwordMaxLen {
-		return ErrIncorrectPassword
-	}
-	return nil
+	return up.Policy.Check(up.Password)
 }
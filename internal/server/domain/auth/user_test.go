@@ -4,6 +4,7 @@ import (
 	"errors"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -534,6 +535,8 @@ func TestUser_FieldValidation(t *testing.T) {
 	assert.Equal(t, "testuser", user.Login, "Login should match input")
 	assert.Equal(t, "hashed_testpassword", user.PasswordHash, "Password should be hashed")
 	assert.Len(t, user.CryptoKey, 32, "CryptoKey should be 32 bytes")
+	assert.True(t, user.Active, "New users should be active")
+	assert.False(t, user.Deprovisioned(), "New users should not be deprovisioned")
 }
 
 func TestConstants(t *testing.T) {
@@ -546,3 +549,174 @@ func TestConstants(t *testing.T) {
 	assert.Equal(t, 8, passwordMinLen, "PasswordMinLen should be 8")
 	assert.Equal(t, 64, passwordMaxLen, "PasswordMaxLen should be 64")
 }
+
+func TestUser_Wipe(t *testing.T) {
+	t.Parallel()
+
+	u := &User{Login: "user", CryptoKey: []byte("12345678901234567890123456789012")}
+
+	u.Wipe()
+
+	assert.Equal(t, make([]byte, 32), u.CryptoKey)
+	assert.NotPanics(t, func() { (*User)(nil).Wipe() })
+}
+
+func TestUser_SetLogin(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		wantErr   error
+		name      string
+		login     string
+		wantLogin string
+	}{
+		{name: "valid login", login: "newlogin", wantLogin: "newlogin"},
+		{name: "too short", login: "abc", wantLogin: "oldlogin", wantErr: ErrIncorrectLogin},
+		{name: "too long", login: strings.Repeat("a", loginMaxLen+1), wantLogin: "oldlogin", wantErr: ErrIncorrectLogin},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			u := &User{Login: "oldlogin"}
+
+			err := u.SetLogin(tt.login)
+
+			require.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.wantLogin, u.Login)
+		})
+	}
+}
+
+func TestUser_SetPassword(t *testing.T) {
+	t.Parallel()
+
+	hashErr := errors.New("hash failed")
+
+	tests := []struct {
+		hasher   PasswordHasher
+		wantErr  error
+		name     string
+		password string
+		wantHash string
+	}{
+		{
+			name:     "valid password",
+			hasher:   &mockPasswordHasher{},
+			password: "newpassword",
+			wantHash: "hashed_newpassword",
+		},
+		{
+			name:     "too short",
+			hasher:   &mockPasswordHasher{},
+			password: "short",
+			wantHash: "oldhash",
+			wantErr:  ErrIncorrectPassword,
+		},
+		{
+			name: "hash failure",
+			hasher: &mockPasswordHasher{hashFunc: func(string) (string, error) {
+				return "", hashErr
+			}},
+			password: "newpassword",
+			wantHash: "oldhash",
+			wantErr:  ErrPasswordHash,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			u := &User{PasswordHash: "oldhash"}
+
+			err := u.SetPassword(tt.hasher, tt.password)
+
+			require.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.wantHash, u.PasswordHash)
+		})
+	}
+}
+
+func TestUser_Deprovision(t *testing.T) {
+	t.Parallel()
+
+	at := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	u := &User{Active: true}
+
+	u.Deprovision(at)
+
+	assert.False(t, u.Active)
+	assert.True(t, u.Deprovisioned())
+	assert.Equal(t, at, u.DeprovisionedAt)
+}
+
+func TestUser_SessionRevoked(t *testing.T) {
+	t.Parallel()
+
+	revokedAt := time.Date(2025, 1, 2, 3, 4, 5, 700_000_000, time.UTC)
+
+	tests := []struct {
+		issuedAt time.Time
+		name     string
+		revoke   bool
+		want     bool
+	}{
+		{name: "never revoked", issuedAt: revokedAt.Add(-time.Hour), want: false},
+		{name: "issued before revocation", revoke: true, issuedAt: revokedAt.Add(-time.Second), want: true},
+		{name: "issued in the revocation second", revoke: true, issuedAt: revokedAt.Truncate(time.Second), want: false},
+		{name: "issued after revocation", revoke: true, issuedAt: revokedAt.Add(time.Minute), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			u := &User{}
+			if tt.revoke {
+				u.RevokeSessions(revokedAt)
+			}
+
+			assert.Equal(t, tt.want, u.SessionRevoked(tt.issuedAt))
+		})
+	}
+}
+
+func TestUser_HasUnlockSecret(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		salt []byte
+		want bool
+	}{
+		{name: "no unlock secret", salt: nil, want: false},
+		{name: "empty salt", salt: []byte{}, want: false},
+		{name: "unlock secret set", salt: []byte("0123456789abcdef"), want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			u := &User{UnlockSalt: tt.salt}
+
+			assert.Equal(t, tt.want, u.HasUnlockSecret())
+		})
+	}
+}
+
+func TestUser_SetUnlockSecret(t *testing.T) {
+	t.Parallel()
+
+	plainKey := []byte("plain_crypto_key")
+	u := &User{CryptoKey: plainKey}
+
+	u.SetUnlockSecret([]byte("sealed_crypto_key"), []byte("0123456789abcdef"))
+
+	assert.Equal(t, []byte("sealed_crypto_key"), u.CryptoKey)
+	assert.Equal(t, []byte("0123456789abcdef"), u.UnlockSalt)
+	assert.True(t, u.HasUnlockSecret())
+	assert.Equal(t, make([]byte, len("plain_crypto_key")), plainKey)
+}
@@ -0,0 +1,6 @@
+// Package authz provides the attribute-based access control policy engine of the AegisVaultKeeper server.
+//
+// This package implements a small rule language deciding whether a subject may perform an action
+// on a resource in the current environment, and the Policy evaluating those rules with deny taking
+// precedence over allow and denial by default.
+package authz
@@ -0,0 +1,12 @@
+package authz
+
+import "errors"
+
+// Authorization policy domain error definitions.
+var (
+	// ErrIncorrectRule indicates that a policy rule does not follow the rule syntax.
+	ErrIncorrectRule = errors.New("incorrect authorization rule")
+
+	// ErrUnknownAttribute indicates that a policy rule refers to an attribute the engine does not provide.
+	ErrUnknownAttribute = errors.New("unknown authorization attribute")
+)
@@ -0,0 +1,362 @@
+package authz
+
+import (
+	"bufio"
+	"fmt"
+	"net/netip"
+	"slices"
+	"strings"
+)
+
+// Actions performed on vault items.
+const (
+	// ActionRead reveals items.
+	ActionRead = "read"
+	// ActionWrite creates, changes or recovers items.
+	ActionWrite = "write"
+)
+
+// Kinds of vault items.
+const (
+	// KindBankCard identifies bank cards.
+	KindBankCard = "bankcard"
+	// KindCredential identifies credentials.
+	KindCredential = "credential"
+	// KindNote identifies notes.
+	KindNote = "note"
+	// KindFile identifies files.
+	KindFile = "file"
+	// KindACME identifies ACME accounts.
+	KindACME = "acme"
+)
+
+// Attributes of an authorization request available to rule conditions.
+const (
+	// AttrAction is the action requested.
+	AttrAction = "action"
+	// AttrSubjectID is the identifier of the user performing the action.
+	AttrSubjectID = "subject.id"
+	// AttrResourceKind is the kind of the item acted on.
+	AttrResourceKind = "resource.kind"
+	// AttrResourceID is the identifier of the item acted on, empty for whole collections.
+	AttrResourceID = "resource.id"
+	// AttrResourceOwner is the identifier of the user owning the item acted on.
+	AttrResourceOwner = "resource.owner"
+	// AttrEnvIP is the client address of the request, empty outside requests.
+	AttrEnvIP = "env.ip"
+	// AttrEnvWeekday is the lowercase three-letter UTC weekday, such as mon.
+	AttrEnvWeekday = "env.weekday"
+	// AttrEnvHour is the two-digit UTC hour of the day, such as 09.
+	AttrEnvHour = "env.hour"
+)
+
+// DefaultPolicy is the rule text of the policy used when no policy is configured:
+// users can read and change their own items only.
+const DefaultPolicy = "allow * * when subject.id == resource.owner"
+
+// knownActions lists the actions rules can apply to.
+var knownActions = []string{ActionRead, ActionWrite}
+
+// knownKinds lists the resource kinds rules can apply to.
+var knownKinds = []string{KindBankCard, KindCredential, KindNote, KindFile, KindACME}
+
+// knownAttributes lists the attributes rule conditions can refer to.
+var knownAttributes = []string{
+	AttrAction, AttrSubjectID, AttrResourceKind, AttrResourceID, AttrResourceOwner,
+	AttrEnvIP, AttrEnvWeekday, AttrEnvHour,
+}
+
+// Attributes holds the attribute values of an authorization request keyed by attribute name.
+type Attributes map[string]string
+
+// Effect is the outcome of a matching rule.
+type Effect string
+
+// Rule effects.
+const (
+	// EffectAllow permits matching requests unless a deny rule matches too.
+	EffectAllow Effect = "allow"
+	// EffectDeny rejects matching requests.
+	EffectDeny Effect = "deny"
+)
+
+// operator compares the attribute of a condition with its operand.
+type operator string
+
+// Condition operators.
+const (
+	// opEqual matches equal values.
+	opEqual operator = "=="
+	// opNotEqual matches different values.
+	opNotEqual operator = "!="
+	// opIn matches values listed by the operand or, for addresses, contained in a listed network.
+	opIn operator = "in"
+)
+
+// Condition compares a request attribute with a literal or another attribute.
+type Condition struct {
+	// attr is the name of the compared attribute.
+	attr string
+	// op is the comparison operator.
+	op operator
+	// ref is the name of the attribute compared with, empty when comparing with literals.
+	ref string
+	// values contains the literals compared with.
+	values []string
+}
+
+// matches reports whether the condition holds for the attributes.
+func (c Condition) matches(attrs Attributes) bool {
+	value := attrs[c.attr]
+	switch c.op {
+	case opEqual:
+		return value == c.operand(attrs)
+	case opNotEqual:
+		return value != c.operand(attrs)
+	default:
+		return c.contains(value)
+	}
+}
+
+// operand returns the value the attribute is compared with.
+func (c Condition) operand(attrs Attributes) string {
+	if c.ref != "" {
+		return attrs[c.ref]
+	}
+	return c.values[0]
+}
+
+// contains reports whether the value is listed by the condition or is an address inside a listed network.
+func (c Condition) contains(value string) bool {
+	if value == "" {
+		return false
+	}
+	if slices.Contains(c.values, value) {
+		return true
+	}
+	ip, err := netip.ParseAddr(value)
+	if err != nil {
+		return false
+	}
+	for _, v := range c.values {
+		if p, err := netip.ParsePrefix(v); err == nil && p.Contains(ip.Unmap()) {
+			return true
+		}
+	}
+	return false
+}
+
+// Rule grants or denies actions on kinds of resources when all of its conditions hold.
+type Rule struct {
+	// Text contains the rule as written in the policy.
+	Text string
+	// Effect contains the outcome of the rule when it matches.
+	Effect Effect
+	// actions contains the actions the rule applies to, nil for every action.
+	actions []string
+	// kinds contains the resource kinds the rule applies to, nil for every kind.
+	kinds []string
+	// conditions contains the conditions that must all hold for the rule to match.
+	conditions []Condition
+}
+
+// matches reports whether the rule applies to the attributes.
+func (r *Rule) matches(attrs Attributes) bool {
+	if r.actions != nil && !slices.Contains(r.actions, attrs[AttrAction]) {
+		return false
+	}
+	if r.kinds != nil && !slices.Contains(r.kinds, attrs[AttrResourceKind]) {
+		return false
+	}
+	for _, c := range r.conditions {
+		if !c.matches(attrs) {
+			return false
+		}
+	}
+	return true
+}
+
+// Decision is the outcome of evaluating a policy.
+type Decision struct {
+	// Rule contains the rule that decided, nil when no rule matched and access was denied by default.
+	Rule *Rule
+	// Allowed reports whether the request is permitted.
+	Allowed bool
+}
+
+// Policy is an ordered set of authorization rules.
+type Policy struct {
+	// rules contains the rules in the order they were written.
+	rules []*Rule
+}
+
+// Evaluate decides the request described by the attributes. The first matching deny rule rejects
+// the request; otherwise the first matching allow rule permits it; otherwise it is denied.
+func (p *Policy) Evaluate(attrs Attributes) Decision {
+	var allow *Rule
+	for _, r := range p.rules {
+		if !r.matches(attrs) {
+			continue
+		}
+		if r.Effect == EffectDeny {
+			return Decision{Rule: r}
+		}
+		if allow == nil {
+			allow = r
+		}
+	}
+	return Decision{Rule: allow, Allowed: allow != nil}
+}
+
+// Rules returns the rules of the policy in the order they were written.
+func (p *Policy) Rules() []*Rule {
+	return p.rules
+}
+
+// Parse parses a policy written one rule per line; blank lines and lines starting with # are ignored.
+// A rule reads
+//
+//	allow|deny <actions> <kinds> [when <attribute> <op> <operand> [and ...]]
+//
+// where actions and kinds are comma-separated lists or *, op is ==, != or in, and the operand is an
+// attribute or a literal, for in a comma-separated list of literals that may include networks.
+func Parse(text string) (*Policy, error) {
+	policy := &Policy{}
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	for line := 1; scanner.Scan(); line++ {
+		raw := strings.TrimSpace(scanner.Text())
+		if raw == "" || strings.HasPrefix(raw, "#") {
+			continue
+		}
+		rule, err := parseRule(raw)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		policy.rules = append(policy.rules, rule)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read policy: %w", err)
+	}
+	return policy, nil
+}
+
+// parseRule parses a single rule line.
+func parseRule(text string) (*Rule, error) {
+	fields := strings.Fields(text)
+	if len(fields) < 3 {
+		return nil, fmt.Errorf("%w: expected effect, actions and kinds in %q", ErrIncorrectRule, text)
+	}
+
+	rule := &Rule{Text: strings.Join(fields, " "), Effect: Effect(fields[0])}
+	if rule.Effect != EffectAllow && rule.Effect != EffectDeny {
+		return nil, fmt.Errorf("%w: effect must be allow or deny, got %q", ErrIncorrectRule, fields[0])
+	}
+	var err error
+	if rule.actions, err = parseNames(fields[1], knownActions, "action"); err != nil {
+		return nil, err
+	}
+	if rule.kinds, err = parseNames(fields[2], knownKinds, "kind"); err != nil {
+		return nil, err
+	}
+
+	rest := fields[3:]
+	if len(rest) == 0 {
+		return rule, nil
+	}
+	if rest[0] != "when" || len(rest) < 4 {
+		return nil, fmt.Errorf("%w: expected when and a condition in %q", ErrIncorrectRule, text)
+	}
+	rest = rest[1:]
+	for {
+		if len(rest) < 3 {
+			return nil, fmt.Errorf("%w: incomplete condition in %q", ErrIncorrectRule, text)
+		}
+		cond, err := parseCondition(rest[0], rest[1], rest[2])
+		if err != nil {
+			return nil, err
+		}
+		rule.conditions = append(rule.conditions, cond)
+		rest = rest[3:]
+		if len(rest) == 0 {
+			return rule, nil
+		}
+		if rest[0] != "and" {
+			return nil, fmt.Errorf("%w: expected and before %q", ErrIncorrectRule, rest[0])
+		}
+		rest = rest[1:]
+	}
+}
+
+// parseCondition parses the attribute, operator and operand of a condition.
+func parseCondition(attr, op, operand string) (Condition, error) {
+	if !slices.Contains(knownAttributes, attr) {
+		return Condition{}, fmt.Errorf("%w: %q", ErrUnknownAttribute, attr)
+	}
+	cond := Condition{attr: attr, op: operator(op)}
+	switch cond.op {
+	case opEqual, opNotEqual:
+		if isAttributeRef(operand) {
+			if !slices.Contains(knownAttributes, operand) {
+				return Condition{}, fmt.Errorf("%w: %q", ErrUnknownAttribute, operand)
+			}
+			cond.ref = operand
+		} else {
+			cond.values = []string{unquote(operand)}
+		}
+	case opIn:
+		cond.values = parseList(operand)
+		if len(cond.values) == 0 {
+			return Condition{}, fmt.Errorf("%w: in requires a list of values", ErrIncorrectRule)
+		}
+	default:
+		return Condition{}, fmt.Errorf("%w: operator must be ==, != or in, got %q", ErrIncorrectRule, op)
+	}
+	return cond, nil
+}
+
+// parseNames parses the actions or kinds of a rule, returning nil for the * wildcard.
+func parseNames(field string, known []string, what string) ([]string, error) {
+	if field == "*" {
+		return nil, nil
+	}
+	names := parseList(field)
+	if len(names) == 0 {
+		return nil, fmt.Errorf("%w: empty %s list", ErrIncorrectRule, what)
+	}
+	for _, name := range names {
+		if !slices.Contains(known, name) {
+			return nil, fmt.Errorf("%w: unknown %s %q", ErrIncorrectRule, what, name)
+		}
+	}
+	return names, nil
+}
+
+// isAttributeRef reports whether the operand names an attribute rather than a literal.
+func isAttributeRef(operand string) bool {
+	return operand == AttrAction ||
+		strings.HasPrefix(operand, "subject.") ||
+		strings.HasPrefix(operand, "resource.") ||
+		strings.HasPrefix(operand, "env.")
+}
+
+// parseList splits a comma-separated list, returning nil for the * wildcard.
+func parseList(field string) []string {
+	if field == "*" {
+		return nil
+	}
+	var items []string
+	for _, item := range strings.Split(field, ",") {
+		if item = unquote(strings.TrimSpace(item)); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// unquote strips the double quotes around a literal.
+func unquote(literal string) string {
+	if len(literal) >= 2 && literal[0] == '"' && literal[len(literal)-1] == '"' {
+		return literal[1 : len(literal)-1]
+	}
+	return literal
+}
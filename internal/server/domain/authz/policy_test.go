@@ -0,0 +1,158 @@
+package authz
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		wantErr   error
+		name      string
+		text      string
+		wantRules int
+	}{
+		{name: "default policy", text: DefaultPolicy, wantRules: 1},
+		{
+			name: "comments and blank lines",
+			text: "# owners only\n\nallow read,write note,file when subject.id == resource.owner\n" +
+				"deny write * when env.ip in 203.0.113.0/24,198.51.100.7 and env.weekday in sat,sun\n",
+			wantRules: 2,
+		},
+		{name: "rule without conditions", text: "deny * bankcard", wantRules: 1},
+		{name: "quoted literal", text: `deny read * when env.hour == "03"`, wantRules: 1},
+		{name: "empty policy", text: "", wantRules: 0},
+		{name: "unknown effect", text: "permit * *", wantErr: ErrIncorrectRule},
+		{name: "missing kinds", text: "allow read", wantErr: ErrIncorrectRule},
+		{name: "unknown action", text: "allow delete *", wantErr: ErrIncorrectRule},
+		{name: "unknown kind", text: "allow * wallet", wantErr: ErrIncorrectRule},
+		{name: "empty action list", text: "allow , *", wantErr: ErrIncorrectRule},
+		{name: "missing when", text: "allow * * if subject.id == resource.owner", wantErr: ErrIncorrectRule},
+		{name: "incomplete condition", text: "allow * * when subject.id ==", wantErr: ErrIncorrectRule},
+		{name: "unknown operator", text: "allow * * when subject.id ~= resource.owner", wantErr: ErrIncorrectRule},
+		{name: "missing and", text: "allow * * when action == read or action == write", wantErr: ErrIncorrectRule},
+		{name: "unknown attribute", text: "allow * * when subject.role == admin", wantErr: ErrUnknownAttribute},
+		{
+			name:    "unknown operand attribute",
+			text:    "allow * * when subject.id == resource.group",
+			wantErr: ErrUnknownAttribute,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			policy, err := Parse(tt.text)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, policy)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, policy.Rules(), tt.wantRules)
+		})
+	}
+}
+
+func TestPolicy_Evaluate(t *testing.T) {
+	t.Parallel()
+
+	policy, err := Parse(`
+allow * * when subject.id == resource.owner
+allow read note when resource.id == 6f1c2f4e-0000-4000-8000-000000000001
+deny write * when env.ip in 203.0.113.0/24
+deny read bankcard when env.weekday in sat,sun and env.hour != 12
+`)
+	require.NoError(t, err)
+
+	owner := Attributes{
+		AttrAction:        ActionRead,
+		AttrSubjectID:     "u1",
+		AttrResourceKind:  KindNote,
+		AttrResourceOwner: "u1",
+		AttrEnvIP:         "192.0.2.1",
+		AttrEnvWeekday:    "mon",
+		AttrEnvHour:       "09",
+	}
+	with := func(overrides Attributes) Attributes {
+		attrs := Attributes{}
+		for k, v := range owner {
+			attrs[k] = v
+		}
+		for k, v := range overrides {
+			attrs[k] = v
+		}
+		return attrs
+	}
+
+	tests := []struct {
+		attrs       Attributes
+		name        string
+		wantRule    string
+		wantAllowed bool
+	}{
+		{
+			name:        "owner reads",
+			attrs:       owner,
+			wantAllowed: true,
+			wantRule:    "allow * * when subject.id == resource.owner",
+		},
+		{name: "other user reads", attrs: with(Attributes{AttrSubjectID: "u2"})},
+		{
+			name:        "literal resource match",
+			attrs:       with(Attributes{AttrSubjectID: "u2", AttrResourceID: "6f1c2f4e-0000-4000-8000-000000000001"}),
+			wantAllowed: true,
+			wantRule:    "allow read note when resource.id == 6f1c2f4e-0000-4000-8000-000000000001",
+		},
+		{
+			name:     "write from denied network",
+			attrs:    with(Attributes{AttrAction: ActionWrite, AttrEnvIP: "203.0.113.9"}),
+			wantRule: "deny write * when env.ip in 203.0.113.0/24",
+		},
+		{
+			name:        "read from denied network",
+			attrs:       with(Attributes{AttrEnvIP: "203.0.113.9"}),
+			wantAllowed: true,
+			wantRule:    "allow * * when subject.id == resource.owner",
+		},
+		{
+			name:        "write without client address",
+			attrs:       with(Attributes{AttrAction: ActionWrite, AttrEnvIP: ""}),
+			wantAllowed: true,
+			wantRule:    "allow * * when subject.id == resource.owner",
+		},
+		{
+			name:     "bank card on weekend",
+			attrs:    with(Attributes{AttrResourceKind: KindBankCard, AttrEnvWeekday: "sun"}),
+			wantRule: "deny read bankcard when env.weekday in sat,sun and env.hour != 12",
+		},
+		{
+			name:        "bank card on weekend at noon",
+			attrs:       with(Attributes{AttrResourceKind: KindBankCard, AttrEnvWeekday: "sun", AttrEnvHour: "12"}),
+			wantAllowed: true,
+			wantRule:    "allow * * when subject.id == resource.owner",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := policy.Evaluate(tt.attrs)
+
+			assert.Equal(t, tt.wantAllowed, got.Allowed)
+			if tt.wantRule == "" {
+				assert.Nil(t, got.Rule)
+				return
+			}
+			require.NotNil(t, got.Rule)
+			assert.Equal(t, tt.wantRule, got.Rule.Text)
+		})
+	}
+}
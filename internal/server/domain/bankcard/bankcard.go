@@ -6,6 +6,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/securebytes"
 	"github.com/google/uuid"
 )
 
@@ -64,6 +65,14 @@ func NewBankCard(params *NewBankCardParams) (*BankCard, error) {
 	}, nil
 }
 
+// Wipe zeroes the sensitive card data in place once it is no longer needed.
+func (b *BankCard) Wipe() {
+	if b == nil {
+		return
+	}
+	securebytes.Wipe(b.CardNumber, b.CardHolder, b.ExpiryMonth, b.ExpiryYear, b.CVV, b.Description)
+}
+
 // NewBankCardParams contains the parameters for creating a new bank card entity.
 type NewBankCardParams struct {
 	// CardNumber contains the card number (13-19 digits, validated with Luhn algorithm).
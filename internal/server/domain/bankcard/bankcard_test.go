@@ -422,3 +422,23 @@ func TestLuhnValid(t *testing.T) {
 		})
 	}
 }
+
+func TestBankCard_Wipe(t *testing.T) {
+	t.Parallel()
+
+	b := &BankCard{
+		CardNumber:  []byte("4111111111111111"),
+		CardHolder:  []byte("JOHN DOE"),
+		ExpiryMonth: []byte("12"),
+		ExpiryYear:  []byte("2030"),
+		CVV:         []byte("123"),
+		Description: []byte("main card"),
+	}
+
+	b.Wipe()
+
+	for _, field := range [][]byte{b.CardNumber, b.CardHolder, b.ExpiryMonth, b.ExpiryYear, b.CVV, b.Description} {
+		assert.Equal(t, make([]byte, len(field)), field)
+	}
+	assert.NotPanics(t, func() { (*BankCard)(nil).Wipe() })
+}
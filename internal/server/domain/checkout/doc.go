@@ -0,0 +1,6 @@
+// Package checkout provides shared credential check-out domain entities and business rules for the
+// AegisVaultKeeper server.
+//
+// This package implements the credentials owners share with a directory group, and the exclusive leases
+// under which one member at a time may reveal a shared credential.
+package checkout
@@ -0,0 +1,24 @@
+package checkout
+
+import "errors"
+
+// Shared credential check-out domain error definitions.
+var (
+	// ErrNewShareParamsValidation indicates that share creation parameters failed validation.
+	ErrNewShareParamsValidation = errors.New("new credential share parameters validation failed")
+
+	// ErrIncorrectCredential indicates that the shared credential is not specified.
+	ErrIncorrectCredential = errors.New("shared credential is not specified")
+
+	// ErrIncorrectGroup indicates that the group the credential is shared with is not specified.
+	ErrIncorrectGroup = errors.New("share group is not specified")
+
+	// ErrIncorrectTTL indicates that the check-out lifetime is not positive.
+	ErrIncorrectTTL = errors.New("incorrect check-out lifetime")
+
+	// ErrCheckedOut indicates that another member holds the credential.
+	ErrCheckedOut = errors.New("credential is checked out by another member")
+
+	// ErrNotCheckedOut indicates that the member does not hold the credential.
+	ErrNotCheckedOut = errors.New("credential is not checked out by the member")
+)
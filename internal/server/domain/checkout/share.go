@@ -0,0 +1,142 @@
+package checkout
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Share represents a credential its owner shares with a directory group, so that one member at a time
+// can check it out and reveal the secret.
+type Share struct {
+	// CreatedAt contains the timestamp when the credential was shared.
+	CreatedAt time.Time
+	// CheckedOutAt contains the timestamp of the current check-out; zero while checked in.
+	CheckedOutAt time.Time
+	// ExpiresAt contains the moment the current check-out ends automatically; zero while checked in.
+	ExpiresAt time.Time
+	// CredentialID identifies the shared credential.
+	CredentialID uuid.UUID
+	// OwnerID identifies the user owning the credential.
+	OwnerID uuid.UUID
+	// GroupID identifies the group whose members may check the credential out.
+	GroupID uuid.UUID
+	// HolderID identifies the member holding the credential; uuid.Nil while checked in.
+	HolderID uuid.UUID
+	// RotateOnCheckIn determines whether the password is rotated whenever the credential is checked in.
+	RotateOnCheckIn bool
+}
+
+// NewShare creates a checked in credential share with the provided parameters after validation.
+func NewShare(params NewShareParams) (*Share, error) {
+	if err := params.Validate(); err != nil {
+		return nil, errors.Join(ErrNewShareParamsValidation, err)
+	}
+	return &Share{
+		CredentialID:    params.CredentialID,
+		OwnerID:         params.OwnerID,
+		GroupID:         params.GroupID,
+		RotateOnCheckIn: params.RotateOnCheckIn,
+		CreatedAt:       time.Now(),
+	}, nil
+}
+
+// Holder returns the member holding the credential at the moment, or uuid.Nil when it is checked in
+// or the check-out has expired.
+func (s *Share) Holder(at time.Time) uuid.UUID {
+	if s.HolderID == uuid.Nil || !at.Before(s.ExpiresAt) {
+		return uuid.Nil
+	}
+	return s.HolderID
+}
+
+// Expired reports whether the credential is still held at the moment although its check-out has ended,
+// so it is due for automatic check-in.
+func (s *Share) Expired(at time.Time) bool {
+	return s.HolderID != uuid.Nil && !at.Before(s.ExpiresAt)
+}
+
+// CheckOut hands the credential to the member at the moment for the TTL. Checking out a credential
+// the member already holds keeps the current check-out.
+func (s *Share) CheckOut(memberID uuid.UUID, ttl time.Duration, at time.Time) error {
+	switch holder := s.Holder(at); {
+	case holder == memberID:
+		return nil
+	case holder != uuid.Nil:
+		return ErrCheckedOut
+	case ttl <= 0:
+		return fmt.Errorf("check-out lifetime must be positive: %w", ErrIncorrectTTL)
+	}
+
+	s.HolderID = memberID
+	s.CheckedOutAt = at
+	s.ExpiresAt = at.Add(ttl)
+	return nil
+}
+
+// CheckIn returns the credential the member holds at the moment.
+func (s *Share) CheckIn(memberID uuid.UUID, at time.Time) error {
+	if memberID == uuid.Nil || s.Holder(at) != memberID {
+		return ErrNotCheckedOut
+	}
+	s.Release()
+	return nil
+}
+
+// Release checks the credential in regardless of its holder, as done when a check-out expires.
+func (s *Share) Release() {
+	s.HolderID = uuid.Nil
+	s.CheckedOutAt = time.Time{}
+	s.ExpiresAt = time.Time{}
+}
+
+// NewShareParams contains parameters for sharing a credential with a group.
+type NewShareParams struct {
+	// CredentialID identifies the shared credential (required).
+	CredentialID uuid.UUID
+	// OwnerID identifies the user owning the credential.
+	OwnerID uuid.UUID
+	// GroupID identifies the group whose members may check the credential out (required).
+	GroupID uuid.UUID
+	// RotateOnCheckIn determines whether the password is rotated whenever the credential is checked in.
+	RotateOnCheckIn bool
+}
+
+// Validate checks that the share parameters are valid.
+func (p *NewShareParams) Validate() error {
+	validations := []func() error{
+		p.validateCredential,
+		p.validateGroup,
+	}
+
+	// errs collects all validation errors encountered during share validation.
+	var errs []error
+	for _, fn := range validations {
+		if err := fn(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) != 0 {
+		return errors.Join(errs...)
+	}
+	return nil
+}
+
+// validateCredential ensures that the shared credential is specified.
+func (p *NewShareParams) validateCredential() error {
+	if p.CredentialID == uuid.Nil {
+		return ErrIncorrectCredential
+	}
+	return nil
+}
+
+// validateGroup ensures that the share group is specified.
+func (p *NewShareParams) validateGroup() error {
+	if p.GroupID == uuid.Nil {
+		return ErrIncorrectGroup
+	}
+	return nil
+}
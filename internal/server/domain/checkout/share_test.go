@@ -0,0 +1,201 @@
+package checkout
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewShare(t *testing.T) {
+	t.Parallel()
+
+	credentialID, ownerID, groupID := uuid.New(), uuid.New(), uuid.New()
+
+	tests := []struct {
+		wantErr error
+		name    string
+		params  NewShareParams
+	}{
+		{
+			name:   "valid share",
+			params: NewShareParams{CredentialID: credentialID, OwnerID: ownerID, GroupID: groupID},
+		},
+		{
+			name: "with rotation",
+			params: NewShareParams{
+				CredentialID:    credentialID,
+				OwnerID:         ownerID,
+				GroupID:         groupID,
+				RotateOnCheckIn: true,
+			},
+		},
+		{
+			name:    "missing credential",
+			params:  NewShareParams{OwnerID: ownerID, GroupID: groupID},
+			wantErr: ErrIncorrectCredential,
+		},
+		{
+			name:    "missing group",
+			params:  NewShareParams{CredentialID: credentialID, OwnerID: ownerID},
+			wantErr: ErrIncorrectGroup,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := NewShare(tt.params)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, ErrNewShareParamsValidation)
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, got)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.params.CredentialID, got.CredentialID)
+			assert.Equal(t, tt.params.OwnerID, got.OwnerID)
+			assert.Equal(t, tt.params.GroupID, got.GroupID)
+			assert.Equal(t, tt.params.RotateOnCheckIn, got.RotateOnCheckIn)
+			assert.Equal(t, uuid.Nil, got.HolderID)
+			assert.False(t, got.CreatedAt.IsZero())
+		})
+	}
+}
+
+func TestShare_Holder(t *testing.T) {
+	t.Parallel()
+
+	holderID := uuid.New()
+	expiresAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		at          time.Time
+		holderID    uuid.UUID
+		wantHolder  uuid.UUID
+		wantExpired bool
+	}{
+		{name: "checked in", at: expiresAt.Add(-time.Minute)},
+		{name: "checked out", holderID: holderID, at: expiresAt.Add(-time.Minute), wantHolder: holderID},
+		{name: "check-out ended", holderID: holderID, at: expiresAt, wantExpired: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := &Share{HolderID: tt.holderID, ExpiresAt: expiresAt}
+
+			assert.Equal(t, tt.wantHolder, s.Holder(tt.at))
+			assert.Equal(t, tt.wantExpired, s.Expired(tt.at))
+		})
+	}
+}
+
+func TestShare_CheckOut(t *testing.T) {
+	t.Parallel()
+
+	memberID, otherID := uuid.New(), uuid.New()
+	at := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		wantErr       error
+		name          string
+		holderID      uuid.UUID
+		expiresAt     time.Time
+		wantExpiresAt time.Time
+		ttl           time.Duration
+	}{
+		{name: "checked in", ttl: time.Hour, wantExpiresAt: at.Add(time.Hour)},
+		{
+			name:          "held by the member",
+			holderID:      memberID,
+			expiresAt:     at.Add(10 * time.Minute),
+			ttl:           time.Hour,
+			wantExpiresAt: at.Add(10 * time.Minute),
+		},
+		{
+			name:      "held by another member",
+			holderID:  otherID,
+			expiresAt: at.Add(10 * time.Minute),
+			ttl:       time.Hour,
+			wantErr:   ErrCheckedOut,
+		},
+		{
+			name:          "check-out of another member ended",
+			holderID:      otherID,
+			expiresAt:     at,
+			ttl:           time.Hour,
+			wantExpiresAt: at.Add(time.Hour),
+		},
+		{name: "zero lifetime", wantErr: ErrIncorrectTTL},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := &Share{HolderID: tt.holderID, ExpiresAt: tt.expiresAt}
+
+			err := s.CheckOut(memberID, tt.ttl, at)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Equal(t, tt.holderID, s.HolderID)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, memberID, s.HolderID)
+			assert.Equal(t, tt.wantExpiresAt, s.ExpiresAt)
+		})
+	}
+}
+
+func TestShare_CheckIn(t *testing.T) {
+	t.Parallel()
+
+	memberID, otherID := uuid.New(), uuid.New()
+	at := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		wantErr   error
+		name      string
+		holderID  uuid.UUID
+		expiresAt time.Time
+	}{
+		{name: "held by the member", holderID: memberID, expiresAt: at.Add(time.Minute)},
+		{name: "checked in", wantErr: ErrNotCheckedOut},
+		{
+			name:      "held by another member",
+			holderID:  otherID,
+			expiresAt: at.Add(time.Minute),
+			wantErr:   ErrNotCheckedOut,
+		},
+		{name: "check-out ended", holderID: memberID, expiresAt: at, wantErr: ErrNotCheckedOut},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := &Share{HolderID: tt.holderID, CheckedOutAt: at.Add(-time.Hour), ExpiresAt: tt.expiresAt}
+
+			err := s.CheckIn(memberID, at)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Equal(t, tt.holderID, s.HolderID)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, uuid.Nil, s.HolderID)
+			assert.True(t, s.CheckedOutAt.IsZero())
+			assert.True(t, s.ExpiresAt.IsZero())
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"time"
 
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/securebytes"
 	"github.com/google/uuid"
 )
 
@@ -17,6 +18,8 @@ type Credential struct {
 	Password []byte
 	// Description contains the encrypted user-provided description (optional, max 255 chars).
 	Description []byte
+	// URIs contains the encrypted JSON list of the credential URIs with their match rules (optional).
+	URIs []byte
 	// ID contains the unique credential identifier.
 	ID uuid.UUID
 	// UserID contains the credential owner identifier.
@@ -29,20 +32,36 @@ func NewCredential(params NewCredentialParams) (*Credential, error) {
 		return nil, errors.Join(ErrNewCredentialParamsValidation, err)
 	}
 
+	uris, err := encodeURIs(params.URIs)
+	if err != nil {
+		return nil, err
+	}
+
 	c := Credential{
 		ID:          uuid.New(),
 		UserID:      params.UserID,
 		Login:       []byte(params.Login),
 		Password:    []byte(params.Password),
 		Description: []byte(params.Description),
+		URIs:        uris,
 		UpdatedAt:   time.Now(),
 	}
 
 	return &c, nil
 }
 
+// Wipe zeroes the login, password, description and URIs in place once they are no longer needed.
+func (c *Credential) Wipe() {
+	if c == nil {
+		return
+	}
+	securebytes.Wipe(c.Login, c.Password, c.Description, c.URIs)
+}
+
 // NewCredentialParams contains the parameters for creating a new credential entity.
 type NewCredentialParams struct {
+	// URIs contains the website and app addresses of the credential with their match rules (optional, max 32).
+	URIs []URI
 	// Login contains the username/login (required, 1-255 chars).
 	Login string
 	// Password contains the password (required, 1-255 chars).
@@ -58,6 +77,7 @@ func (cp *NewCredentialParams) Validate() error {
 	validations := []func() error{
 		cp.validateLogin,
 		cp.validatePassword,
+		cp.validateURIs,
 	}
 
 	// errs collects all validation errors encountered during credential validation.
@@ -88,3 +108,16 @@ func (cp *NewCredentialParams) validatePassword() error {
 	}
 	return nil
 }
+
+// validateURIs validates the number of URIs and that every URI suits its match rule.
+func (cp *NewCredentialParams) validateURIs() error {
+	if len(cp.URIs) > MaxURIs {
+		return ErrTooManyURIs
+	}
+	for _, u := range cp.URIs {
+		if err := u.validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -243,3 +243,16 @@ func TestNewCredentialParams_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestCredential_Wipe(t *testing.T) {
+	t.Parallel()
+
+	c := &Credential{Login: []byte("user"), Password: []byte("secret"), Description: []byte("work")}
+
+	c.Wipe()
+
+	assert.Equal(t, make([]byte, len("user")), c.Login)
+	assert.Equal(t, make([]byte, len("secret")), c.Password)
+	assert.Equal(t, make([]byte, len("work")), c.Description)
+	assert.NotPanics(t, func() { (*Credential)(nil).Wipe() })
+}
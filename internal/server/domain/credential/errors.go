@@ -10,3 +10,12 @@ var ErrIncorrectLogin = errors.New("incorrect login")
 
 // ErrIncorrectPassword indicates the password field is empty or invalid.
 var ErrIncorrectPassword = errors.New("incorrect password")
+
+// ErrIncorrectURI indicates a credential URI is empty, too long or does not suit its match rule.
+var ErrIncorrectURI = errors.New("incorrect URI")
+
+// ErrIncorrectMatchRule indicates a credential URI has an unknown match rule.
+var ErrIncorrectMatchRule = errors.New("incorrect URI match rule")
+
+// ErrTooManyURIs indicates a credential has more URIs than allowed.
+var ErrTooManyURIs = errors.New("too many URIs")
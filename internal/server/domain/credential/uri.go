@@ -0,0 +1,193 @@
+package credential
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/publicsuffix"
+)
+
+const (
+	// MaxURIs is the maximum number of URIs of a credential.
+	MaxURIs = 32
+	// maxURILen is the maximum length of a URI or match pattern in bytes.
+	maxURILen = 2048
+)
+
+const (
+	// SchemeAndroidApp is the URI scheme of Android apps, such as androidapp://com.example.app.
+	SchemeAndroidApp = "androidapp"
+	// SchemeIOSApp is the URI scheme of iOS apps, such as iosapp://com.example.app.
+	SchemeIOSApp = "iosapp"
+)
+
+// MatchRule defines how a credential URI is compared with the URI of a page or app asking for credentials.
+type MatchRule string
+
+const (
+	// MatchBaseDomain matches URIs sharing the registrable domain, such as login.example.com and example.com.
+	MatchBaseDomain MatchRule = "base_domain"
+	// MatchHost matches URIs with the same host name and port.
+	MatchHost MatchRule = "host"
+	// MatchStartsWith matches URIs beginning with the credential URI.
+	MatchStartsWith MatchRule = "starts_with"
+	// MatchRegex matches URIs against the credential URI taken as a regular expression.
+	MatchRegex MatchRule = "regex"
+	// MatchNever keeps the URI for reference only and matches nothing.
+	MatchNever MatchRule = "never"
+)
+
+// URI represents a website or app address of a credential with the rule it is matched by.
+type URI struct {
+	// Value contains the URI, or the regular expression for MatchRegex.
+	Value string `json:"value"`
+	// Match contains the rule the URI is matched by; empty means MatchBaseDomain.
+	Match MatchRule `json:"match,omitempty"`
+}
+
+// validate checks that the URI suits its match rule.
+func (u URI) validate() error {
+	if u.Value == "" || len(u.Value) > maxURILen {
+		return ErrIncorrectURI
+	}
+	switch u.Match {
+	case "", MatchBaseDomain, MatchHost:
+		if _, ok := parseURI(u.Value); !ok {
+			return ErrIncorrectURI
+		}
+	case MatchRegex:
+		if _, err := regexp.Compile(u.Value); err != nil {
+			return ErrIncorrectURI
+		}
+	case MatchStartsWith, MatchNever:
+	default:
+		return ErrIncorrectMatchRule
+	}
+	return nil
+}
+
+// Matches reports whether the target URI matches the credential URI under its match rule.
+func (u URI) Matches(target string) bool {
+	switch u.Match {
+	case "", MatchBaseDomain:
+		want, ok := parseURI(u.Value)
+		got, targetOK := parseURI(target)
+		if !ok || !targetOK {
+			return false
+		}
+		if isAppURI(want) || isAppURI(got) {
+			return sameApp(want, got)
+		}
+		return baseDomain(want.Hostname()) == baseDomain(got.Hostname())
+	case MatchHost:
+		want, ok := parseURI(u.Value)
+		got, targetOK := parseURI(target)
+		if !ok || !targetOK {
+			return false
+		}
+		if isAppURI(want) || isAppURI(got) {
+			return sameApp(want, got)
+		}
+		return want.Host == got.Host
+	case MatchStartsWith:
+		return strings.HasPrefix(target, u.Value)
+	case MatchRegex:
+		re, err := regexp.Compile(u.Value)
+		return err == nil && re.MatchString(target)
+	default:
+		return false
+	}
+}
+
+// ValidTarget reports whether the target URI has a host credential URIs can be matched with.
+func ValidTarget(target string) bool {
+	if len(target) > maxURILen {
+		return false
+	}
+	_, ok := parseURI(target)
+	return ok
+}
+
+// URIList decodes the URIs of the credential.
+func (c *Credential) URIList() ([]URI, error) {
+	if len(c.URIs) == 0 {
+		return nil, nil
+	}
+	// uris holds the decoded URIs of the credential.
+	var uris []URI
+	if err := json.Unmarshal(c.URIs, &uris); err != nil {
+		return nil, fmt.Errorf("failed to decode URIs: %w", err)
+	}
+	return uris, nil
+}
+
+// Matches reports whether any URI of the credential matches the target URI.
+func (c *Credential) Matches(target string) (bool, error) {
+	uris, err := c.URIList()
+	if err != nil {
+		return false, err
+	}
+	for _, u := range uris {
+		if u.Matches(target) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// encodeURIs encodes the URIs for storage with the default match rule spelled out; no URIs encode as empty.
+func encodeURIs(uris []URI) ([]byte, error) {
+	if len(uris) == 0 {
+		return nil, nil
+	}
+	stored := make([]URI, 0, len(uris))
+	for _, u := range uris {
+		if u.Match == "" {
+			u.Match = MatchBaseDomain
+		}
+		stored = append(stored, u)
+	}
+	encoded, err := json.Marshal(stored)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode URIs: %w", err)
+	}
+	return encoded, nil
+}
+
+// parseURI parses an absolute URI with a host; bare host names, such as example.com, are taken as HTTPS URIs.
+// Host names are lowercased.
+func parseURI(s string) (*url.URL, bool) {
+	if !strings.Contains(s, "://") {
+		s = "https://" + s
+	}
+	u, err := url.Parse(s)
+	if err != nil || u.Hostname() == "" {
+		return nil, false
+	}
+	u.Host = strings.ToLower(u.Host)
+	return u, true
+}
+
+// isAppURI reports whether the URI names a mobile app by its package name or bundle ID.
+func isAppURI(u *url.URL) bool {
+	return u.Scheme == SchemeAndroidApp || u.Scheme == SchemeIOSApp
+}
+
+// sameApp reports whether both URIs name the same app of the same platform; app IDs are never compared by
+// domain, as com.example.app is not a host under example.app.
+func sameApp(want, got *url.URL) bool {
+	return want.Scheme == got.Scheme && want.Host == got.Host
+}
+
+// baseDomain returns the registrable domain of the host, or the host itself for IP addresses, single-label
+// names and public suffixes.
+func baseDomain(host string) string {
+	domain, err := publicsuffix.EffectiveTLDPlusOne(host)
+	if err != nil {
+		return host
+	}
+	return domain
+}
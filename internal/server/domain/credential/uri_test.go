@@ -0,0 +1,168 @@
+package credential
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestURI_Matches(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		uri    URI
+		target string
+		want   bool
+	}{
+		{
+			name:   "base domain/subdomain",
+			uri:    URI{Value: "https://example.com"},
+			target: "https://login.example.com/signin",
+			want:   true,
+		},
+		{
+			name:   "base domain/public suffix",
+			uri:    URI{Value: "alice.github.io", Match: MatchBaseDomain},
+			target: "https://bob.github.io",
+		},
+		{name: "base domain/other domain", uri: URI{Value: "example.com"}, target: "https://example.org"},
+		{
+			name:   "host/same host",
+			uri:    URI{Value: "https://Login.example.com", Match: MatchHost},
+			target: "https://login.example.com/reset",
+			want:   true,
+		},
+		{
+			name:   "host/other port",
+			uri:    URI{Value: "https://example.com:8443", Match: MatchHost},
+			target: "https://example.com",
+		},
+		{
+			name:   "starts with/prefix",
+			uri:    URI{Value: "https://example.com/app", Match: MatchStartsWith},
+			target: "https://example.com/app/login",
+			want:   true,
+		},
+		{
+			name:   "starts with/other path",
+			uri:    URI{Value: "https://example.com/app", Match: MatchStartsWith},
+			target: "https://example.com/admin",
+		},
+		{
+			name:   "regex/match",
+			uri:    URI{Value: `^https://(www\.)?example\.com/`, Match: MatchRegex},
+			target: "https://www.example.com/login",
+			want:   true,
+		},
+		{
+			name:   "base domain/same android app",
+			uri:    URI{Value: "androidapp://com.example.app"},
+			target: "androidapp://com.example.app",
+			want:   true,
+		},
+		{
+			name:   "base domain/other android app",
+			uri:    URI{Value: "androidapp://com.example.app"},
+			target: "androidapp://com.example.wallet",
+		},
+		{
+			name:   "base domain/ios app of android package",
+			uri:    URI{Value: "androidapp://com.example.app"},
+			target: "iosapp://com.example.app",
+		},
+		{name: "base domain/website of app", uri: URI{Value: "example.app"}, target: "androidapp://com.example.app"},
+		{
+			name:   "host/same ios app",
+			uri:    URI{Value: "iosapp://com.example.App", Match: MatchHost},
+			target: "iosapp://com.example.app",
+			want:   true,
+		},
+		{
+			name:   "host/website of app",
+			uri:    URI{Value: "iosapp://example.com", Match: MatchHost},
+			target: "https://example.com",
+		},
+		{name: "never", uri: URI{Value: "https://example.com", Match: MatchNever}, target: "https://example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, tt.uri.Matches(tt.target))
+		})
+	}
+}
+
+func TestNewCredential_URIs(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		wantErr error
+		name    string
+		uris    []URI
+	}{
+		{name: "no URIs"},
+		{name: "valid URIs", uris: []URI{{Value: "example.com"}, {Value: "android://app", Match: MatchNever}}},
+		{name: "empty URI", uris: []URI{{}}, wantErr: ErrIncorrectURI},
+		{name: "URI without host", uris: []URI{{Value: "https://", Match: MatchHost}}, wantErr: ErrIncorrectURI},
+		{name: "invalid regex", uris: []URI{{Value: "(", Match: MatchRegex}}, wantErr: ErrIncorrectURI},
+		{name: "unknown rule", uris: []URI{{Value: "example.com", Match: "exact"}}, wantErr: ErrIncorrectMatchRule},
+		{name: "too long URI", uris: []URI{{Value: strings.Repeat("a", 2049)}}, wantErr: ErrIncorrectURI},
+		{name: "too many URIs", uris: make([]URI, MaxURIs+1), wantErr: ErrTooManyURIs},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			c, err := NewCredential(NewCredentialParams{
+				Login:    "admin",
+				Password: "pass",
+				URIs:     tt.uris,
+				UserID:   uuid.New(),
+			})
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				require.ErrorIs(t, err, ErrNewCredentialParamsValidation)
+				return
+			}
+			require.NoError(t, err)
+			got, err := c.URIList()
+			require.NoError(t, err)
+			require.Len(t, got, len(tt.uris))
+			for i, u := range got {
+				assert.Equal(t, tt.uris[i].Value, u.Value)
+				assert.NotEmpty(t, u.Match)
+			}
+		})
+	}
+}
+
+func TestCredential_Matches(t *testing.T) {
+	t.Parallel()
+
+	c, err := NewCredential(NewCredentialParams{
+		Login:    "admin",
+		Password: "pass",
+		URIs:     []URI{{Value: "https://example.com", Match: MatchNever}, {Value: "accounts.example.org"}},
+		UserID:   uuid.New(),
+	})
+	require.NoError(t, err)
+
+	matched, err := c.Matches("https://example.com")
+	require.NoError(t, err)
+	assert.False(t, matched)
+
+	matched, err = c.Matches("https://www.example.org/login")
+	require.NoError(t, err)
+	assert.True(t, matched)
+
+	_, err = (&Credential{URIs: []byte("not json")}).Matches("https://example.com")
+	require.Error(t, err)
+}
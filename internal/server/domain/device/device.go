@@ -0,0 +1,270 @@
+package device
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/netip"
+	"strings"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/consttime"
+	"github.com/google/uuid"
+)
+
+// Anomaly reasons reported for logins from unrecognized fingerprints.
+const (
+	// ReasonNewDevice indicates a login from client software not seen before.
+	ReasonNewDevice = "new_device"
+	// ReasonNewLocation indicates a login from a country or network not seen before.
+	ReasonNewLocation = "new_location"
+)
+
+// MaxChallengeAttempts limits wrong verification codes accepted for a single challenge.
+const MaxChallengeAttempts = 5
+
+// maxUserAgentLen limits the stored user agent length.
+const maxUserAgentLen = 512
+
+// Location prefix lengths used when the country of an address is unknown.
+const (
+	// ipv4LocationBits groups IPv4 addresses by /24 network.
+	ipv4LocationBits = 24
+	// ipv6LocationBits groups IPv6 addresses by /48 network.
+	ipv6LocationBits = 48
+)
+
+// Device represents a login fingerprint of a user: the client software and location a login came from.
+// Untrusted devices carry a pending step-up verification challenge, completed either with the
+// verification code or by an approval followed by a claim from the held client.
+type Device struct {
+	// CreatedAt contains the timestamp of the first login from this device.
+	CreatedAt time.Time
+	// LastSeenAt contains the timestamp of the latest login from this device.
+	LastSeenAt time.Time
+	// ChallengeExpiresAt contains the moment the pending verification challenge expires.
+	ChallengeExpiresAt time.Time
+	// IP contains the client address of the latest login.
+	IP netip.Addr
+	// UserAgent contains the reported client software.
+	UserAgent string
+	// Fingerprint identifies the client software independently of its location.
+	Fingerprint string
+	// Location contains the country code, or the network when the country is unknown.
+	Location string
+	// ChallengeHash contains the SHA-256 digest of the pending verification code.
+	ChallengeHash []byte
+	// ApprovalHash contains the SHA-256 digest of the approval link token of the pending challenge.
+	ApprovalHash []byte
+	// ChallengeAttempts counts wrong verification codes submitted for the pending challenge.
+	ChallengeAttempts int
+	// ID uniquely identifies this device.
+	ID uuid.UUID
+	// UserID identifies the user who signed in from this device.
+	UserID uuid.UUID
+	// Trusted determines whether logins from this device are recognized.
+	Trusted bool
+	// Approved determines whether the pending challenge was approved and awaits the claim.
+	Approved bool
+}
+
+// NewDevice creates a new untrusted device with the provided parameters after validation.
+func NewDevice(params NewDeviceParams) (*Device, error) {
+	if err := params.Validate(); err != nil {
+		return nil, errors.Join(ErrNewDeviceParamsValidation, err)
+	}
+
+	userAgent := strings.TrimSpace(params.UserAgent)
+	if len(userAgent) > maxUserAgentLen {
+		userAgent = userAgent[:maxUserAgentLen]
+	}
+	now := time.Now()
+	return &Device{
+		ID:          uuid.New(),
+		UserID:      params.UserID,
+		IP:          params.IP,
+		UserAgent:   userAgent,
+		Fingerprint: FingerprintOf(params.UserAgent),
+		Location:    LocationOf(params.IP, params.Country),
+		CreatedAt:   now,
+		LastSeenAt:  now,
+	}, nil
+}
+
+// FingerprintOf derives the device fingerprint from the reported user agent.
+func FingerprintOf(userAgent string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(userAgent)))
+	return hex.EncodeToString(sum[:])
+}
+
+// LocationOf derives the login location from the country, falling back to the client network.
+// It returns an empty string when neither is known.
+func LocationOf(ip netip.Addr, country string) string {
+	if country != "" {
+		return strings.ToUpper(country)
+	}
+	if !ip.IsValid() {
+		return ""
+	}
+	ip = ip.Unmap()
+	bits := ipv6LocationBits
+	if ip.Is4() {
+		bits = ipv4LocationBits
+	}
+	p, _ := ip.Prefix(bits)
+	return p.String()
+}
+
+// Anomalies reports why the device is unrecognized among the known devices of the same user.
+// Only trusted devices are considered; when there are none, the first login is not anomalous.
+func (d *Device) Anomalies(known []*Device) []string {
+	var knownDevice, knownLocation, anyTrusted bool
+	for _, k := range known {
+		if !k.Trusted {
+			continue
+		}
+		anyTrusted = true
+		knownDevice = knownDevice || k.Fingerprint == d.Fingerprint
+		knownLocation = knownLocation || d.Location == "" || k.Location == d.Location
+	}
+	if !anyTrusted {
+		return nil
+	}
+
+	var reasons []string
+	if !knownDevice {
+		reasons = append(reasons, ReasonNewDevice)
+	}
+	if !knownLocation {
+		reasons = append(reasons, ReasonNewLocation)
+	}
+	return reasons
+}
+
+// IssueChallenge replaces the pending verification challenge with the code and approval token
+// valid until expiresAt. An empty approval token disables approval by link.
+func (d *Device) IssueChallenge(code, approvalToken string, expiresAt time.Time) {
+	d.ChallengeHash = digest(code)
+	d.ApprovalHash = nil
+	if approvalToken != "" {
+		d.ApprovalHash = digest(approvalToken)
+	}
+	d.ChallengeExpiresAt = expiresAt
+	d.ChallengeAttempts = 0
+	d.Trusted = false
+	d.Approved = false
+}
+
+// Pending reports whether the device has a challenge that can still be completed at now.
+func (d *Device) Pending(now time.Time) bool {
+	return d.checkChallenge(now) == nil
+}
+
+// VerifyChallenge checks the submitted code against the pending challenge and trusts the device on success.
+// Every wrong code counts as an attempt, so the caller must persist the device regardless of the result.
+func (d *Device) VerifyChallenge(code string, now time.Time) error {
+	if err := d.checkChallenge(now); err != nil {
+		return err
+	}
+	if !consttime.Equal(digest(code), d.ChallengeHash) {
+		d.ChallengeAttempts++
+		return ErrChallengeMismatch
+	}
+	d.Trust(now)
+	return nil
+}
+
+// Approve checks the approval link token against the pending challenge and approves it on success.
+// Wrong tokens count as attempts like wrong codes, so the caller must persist the device regardless.
+func (d *Device) Approve(token string, now time.Time) error {
+	if err := d.checkChallenge(now); err != nil {
+		return err
+	}
+	if len(d.ApprovalHash) == 0 {
+		return ErrNoChallenge
+	}
+	if !consttime.Equal(digest(token), d.ApprovalHash) {
+		d.ChallengeAttempts++
+		return ErrChallengeMismatch
+	}
+	d.Approved = true
+	return nil
+}
+
+// ApproveTrusted approves the pending challenge on behalf of an already trusted device of the user.
+func (d *Device) ApproveTrusted(now time.Time) error {
+	if err := d.checkChallenge(now); err != nil {
+		return err
+	}
+	d.Approved = true
+	return nil
+}
+
+// Claim completes an approved challenge and trusts the device.
+// It returns ErrApprovalPending while the challenge still awaits approval.
+func (d *Device) Claim(now time.Time) error {
+	if err := d.checkChallenge(now); err != nil {
+		return err
+	}
+	if !d.Approved {
+		return ErrApprovalPending
+	}
+	d.Trust(now)
+	return nil
+}
+
+// Trust marks the device as recognized and discards any pending challenge.
+func (d *Device) Trust(now time.Time) {
+	d.Trusted = true
+	d.Approved = false
+	d.ChallengeHash = nil
+	d.ApprovalHash = nil
+	d.ChallengeExpiresAt = time.Time{}
+	d.ChallengeAttempts = 0
+	d.LastSeenAt = now
+}
+
+// Seen records a login from the device at the specified address.
+func (d *Device) Seen(ip netip.Addr, now time.Time) {
+	d.IP = ip
+	d.LastSeenAt = now
+}
+
+// checkChallenge reports why the pending challenge cannot be completed at now, if it cannot.
+func (d *Device) checkChallenge(now time.Time) error {
+	switch {
+	case len(d.ChallengeHash) == 0:
+		return ErrNoChallenge
+	case !now.Before(d.ChallengeExpiresAt):
+		return ErrChallengeExpired
+	case d.ChallengeAttempts >= MaxChallengeAttempts:
+		return ErrChallengeAttemptsExceeded
+	}
+	return nil
+}
+
+// digest returns the SHA-256 digest of a challenge secret.
+func digest(secret string) []byte {
+	sum := sha256.Sum256([]byte(secret))
+	return sum[:]
+}
+
+// NewDeviceParams contains parameters for creating a new device.
+type NewDeviceParams struct {
+	// IP contains the client address of the login.
+	IP netip.Addr
+	// UserAgent contains the reported client software.
+	UserAgent string
+	// Country contains the ISO 3166-1 alpha-2 country code of the address, if known.
+	Country string
+	// UserID identifies the user who signed in (required).
+	UserID uuid.UUID
+}
+
+// Validate checks that the device creation parameters are valid.
+func (p *NewDeviceParams) Validate() error {
+	if p.UserID == uuid.Nil {
+		return ErrIncorrectUserID
+	}
+	return nil
+}
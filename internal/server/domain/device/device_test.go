@@ -0,0 +1,310 @@
+package device
+
+import (
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewDevice(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+
+	tests := []struct {
+		name    string
+		params  NewDeviceParams
+		wantLoc string
+		wantErr error
+	}{
+		{
+			name: "country location",
+			params: NewDeviceParams{
+				UserID:    userID,
+				IP:        netip.MustParseAddr("203.0.113.7"),
+				UserAgent: " Firefox ",
+				Country:   "de",
+			},
+			wantLoc: "DE",
+		},
+		{
+			name:    "network location without country",
+			params:  NewDeviceParams{UserID: userID, IP: netip.MustParseAddr("203.0.113.7")},
+			wantLoc: "203.0.113.0/24",
+		},
+		{
+			name:    "missing user",
+			params:  NewDeviceParams{UserAgent: "Firefox"},
+			wantErr: ErrIncorrectUserID,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			d, err := NewDevice(tt.params)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.ErrorIs(t, err, ErrNewDeviceParamsValidation)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotEqual(t, uuid.Nil, d.ID)
+			assert.Equal(t, tt.params.UserID, d.UserID)
+			assert.Equal(t, strings.TrimSpace(tt.params.UserAgent), d.UserAgent)
+			assert.Equal(t, FingerprintOf(tt.params.UserAgent), d.Fingerprint)
+			assert.Equal(t, tt.wantLoc, d.Location)
+			assert.False(t, d.Trusted)
+		})
+	}
+}
+
+func TestLocationOf(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		ip      netip.Addr
+		name    string
+		country string
+		want    string
+	}{
+		{name: "country wins", ip: netip.MustParseAddr("10.1.2.3"), country: "fr", want: "FR"},
+		{name: "ipv4 network", ip: netip.MustParseAddr("10.1.2.3"), want: "10.1.2.0/24"},
+		{name: "mapped ipv4 network", ip: netip.MustParseAddr("::ffff:10.1.2.3"), want: "10.1.2.0/24"},
+		{name: "ipv6 network", ip: netip.MustParseAddr("2001:db8:1:2::1"), want: "2001:db8:1::/48"},
+		{name: "unknown", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, LocationOf(tt.ip, tt.country))
+		})
+	}
+}
+
+func TestDevice_Anomalies(t *testing.T) {
+	t.Parallel()
+
+	trusted := &Device{Fingerprint: FingerprintOf("Firefox"), Location: "DE", Trusted: true}
+	pending := &Device{Fingerprint: FingerprintOf("Chrome"), Location: "FR"}
+
+	tests := []struct {
+		candidate *Device
+		name      string
+		known     []*Device
+		want      []string
+	}{
+		{
+			name:      "first device",
+			candidate: &Device{Fingerprint: FingerprintOf("Chrome"), Location: "FR"},
+			known:     nil,
+		},
+		{
+			name:      "only pending devices",
+			candidate: &Device{Fingerprint: FingerprintOf("Chrome"), Location: "FR"},
+			known:     []*Device{pending},
+		},
+		{
+			name:      "known device and location",
+			candidate: &Device{Fingerprint: FingerprintOf("Firefox"), Location: "DE"},
+			known:     []*Device{trusted},
+		},
+		{
+			name:      "new device",
+			candidate: &Device{Fingerprint: FingerprintOf("Chrome"), Location: "DE"},
+			known:     []*Device{trusted, pending},
+			want:      []string{ReasonNewDevice},
+		},
+		{
+			name:      "new location",
+			candidate: &Device{Fingerprint: FingerprintOf("Firefox"), Location: "FR"},
+			known:     []*Device{trusted, pending},
+			want:      []string{ReasonNewLocation},
+		},
+		{
+			name:      "new device and location",
+			candidate: &Device{Fingerprint: FingerprintOf("Chrome"), Location: "FR"},
+			known:     []*Device{trusted, pending},
+			want:      []string{ReasonNewDevice, ReasonNewLocation},
+		},
+		{
+			name:      "unknown location is not anomalous",
+			candidate: &Device{Fingerprint: FingerprintOf("Firefox")},
+			known:     []*Device{trusted},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, tt.candidate.Anomalies(tt.known))
+		})
+	}
+}
+
+func TestDevice_VerifyChallenge(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, time.March, 15, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		setup        func(d *Device)
+		wantErr      error
+		name         string
+		code         string
+		wantAttempts int
+		wantTrusted  bool
+	}{
+		{
+			name:        "correct code",
+			setup:       func(d *Device) { d.IssueChallenge("123456", "", now.Add(time.Minute)) },
+			code:        "123456",
+			wantTrusted: true,
+		},
+		{
+			name:         "wrong code",
+			setup:        func(d *Device) { d.IssueChallenge("123456", "", now.Add(time.Minute)) },
+			code:         "654321",
+			wantErr:      ErrChallengeMismatch,
+			wantAttempts: 1,
+		},
+		{
+			name:    "expired",
+			setup:   func(d *Device) { d.IssueChallenge("123456", "", now) },
+			code:    "123456",
+			wantErr: ErrChallengeExpired,
+		},
+		{
+			name: "attempts exceeded",
+			setup: func(d *Device) {
+				d.IssueChallenge("123456", "", now.Add(time.Minute))
+				d.ChallengeAttempts = MaxChallengeAttempts
+			},
+			code:         "123456",
+			wantErr:      ErrChallengeAttemptsExceeded,
+			wantAttempts: MaxChallengeAttempts,
+		},
+		{
+			name:    "no challenge",
+			setup:   func(d *Device) {},
+			code:    "123456",
+			wantErr: ErrNoChallenge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			d := &Device{}
+			tt.setup(d)
+
+			err := d.VerifyChallenge(tt.code, now)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+				assert.Empty(t, d.ChallengeHash)
+				assert.Equal(t, now, d.LastSeenAt)
+			}
+			assert.Equal(t, tt.wantTrusted, d.Trusted)
+			assert.Equal(t, tt.wantAttempts, d.ChallengeAttempts)
+		})
+	}
+}
+
+func TestDevice_ApproveAndClaim(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, time.March, 15, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		approve      func(d *Device) error
+		wantApprove  error
+		wantClaim    error
+		name         string
+		token        string
+		wantAttempts int
+		wantTrusted  bool
+	}{
+		{
+			name:        "approved by link",
+			approve:     func(d *Device) error { return d.Approve("approval-token", now) },
+			wantTrusted: true,
+		},
+		{
+			name:        "approved by trusted device",
+			approve:     func(d *Device) error { return d.ApproveTrusted(now) },
+			wantTrusted: true,
+		},
+		{
+			name:         "wrong link token",
+			approve:      func(d *Device) error { return d.Approve("other-token", now) },
+			wantApprove:  ErrChallengeMismatch,
+			wantClaim:    ErrApprovalPending,
+			wantAttempts: 1,
+		},
+		{
+			name:      "not approved",
+			approve:   func(d *Device) error { return nil },
+			wantClaim: ErrApprovalPending,
+		},
+		{
+			name:        "approval after expiry",
+			approve:     func(d *Device) error { return d.ApproveTrusted(now.Add(time.Hour)) },
+			wantApprove: ErrChallengeExpired,
+			wantClaim:   ErrApprovalPending,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			d := &Device{}
+			d.IssueChallenge("123456", "approval-token", now.Add(time.Minute))
+			require.True(t, d.Pending(now))
+
+			err := tt.approve(d)
+			if tt.wantApprove != nil {
+				require.ErrorIs(t, err, tt.wantApprove)
+			} else {
+				require.NoError(t, err)
+			}
+
+			err = d.Claim(now)
+			if tt.wantClaim != nil {
+				require.ErrorIs(t, err, tt.wantClaim)
+			} else {
+				require.NoError(t, err)
+				assert.Empty(t, d.ApprovalHash)
+				assert.False(t, d.Pending(now))
+				require.ErrorIs(t, d.Claim(now), ErrNoChallenge)
+			}
+			assert.Equal(t, tt.wantTrusted, d.Trusted)
+			assert.Equal(t, tt.wantAttempts, d.ChallengeAttempts)
+		})
+	}
+}
+
+func TestDevice_ApproveWithoutLink(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, time.March, 15, 10, 0, 0, 0, time.UTC)
+	d := &Device{}
+	d.IssueChallenge("123456", "", now.Add(time.Minute))
+
+	require.ErrorIs(t, d.Approve("", now), ErrNoChallenge)
+	assert.False(t, d.Approved)
+}
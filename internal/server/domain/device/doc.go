@@ -0,0 +1,7 @@
+// Package device provides login device domain entities and business rules
+// for the AegisVaultKeeper server.
+//
+// This package implements core domain logic for login fingerprints: the client software
+// and network location a user signs in from, how unrecognized ones are detected and
+// how a step-up verification challenge establishes trust in them.
+package device
@@ -0,0 +1,27 @@
+package device
+
+import "errors"
+
+// Device domain error definitions.
+var (
+	// ErrNewDeviceParamsValidation indicates that device creation parameters failed validation.
+	ErrNewDeviceParamsValidation = errors.New("new device parameters validation failed")
+
+	// ErrIncorrectUserID indicates that the device owner is not specified.
+	ErrIncorrectUserID = errors.New("incorrect device user id")
+
+	// ErrNoChallenge indicates that the device has no pending verification challenge.
+	ErrNoChallenge = errors.New("device has no pending verification challenge")
+
+	// ErrChallengeExpired indicates that the verification challenge is no longer valid.
+	ErrChallengeExpired = errors.New("device verification challenge expired")
+
+	// ErrChallengeAttemptsExceeded indicates that too many wrong verification codes were submitted.
+	ErrChallengeAttemptsExceeded = errors.New("device verification attempts exceeded")
+
+	// ErrChallengeMismatch indicates that the submitted verification code is wrong.
+	ErrChallengeMismatch = errors.New("device verification code mismatch")
+
+	// ErrApprovalPending indicates that the held login has not been approved yet.
+	ErrApprovalPending = errors.New("device approval pending")
+)
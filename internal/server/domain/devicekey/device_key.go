@@ -0,0 +1,82 @@
+package devicekey
+
+import (
+	"crypto/ecdh"
+	"crypto/rand"
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// maxNameLen limits the length of device key names in characters.
+const maxNameLen = 64
+
+// DeviceKey is the X25519 public key of a device of the user that sync payloads are encrypted for.
+type DeviceKey struct {
+	// CreatedAt contains the timestamp when the key was registered.
+	CreatedAt time.Time
+	// Name labels the device holding the private key.
+	Name string
+	// PublicKey contains the 32-byte X25519 public key of the device.
+	PublicKey []byte
+	// ID uniquely identifies this key and is sent by the device to request encrypted payloads.
+	ID uuid.UUID
+	// UserID identifies the user who owns this key.
+	UserID uuid.UUID
+}
+
+// NewDeviceKey creates a new device key with the provided parameters after validation.
+func NewDeviceKey(params NewDeviceKeyParams) (*DeviceKey, error) {
+	if err := params.Validate(); err != nil {
+		return nil, errors.Join(ErrNewDeviceKeyParamsValidation, err)
+	}
+
+	return &DeviceKey{
+		ID:        uuid.New(),
+		UserID:    params.UserID,
+		Name:      strings.TrimSpace(params.Name),
+		PublicKey: params.PublicKey,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+// NewDeviceKeyParams contains parameters for registering a new device key.
+type NewDeviceKeyParams struct {
+	// Name labels the device holding the private key (required).
+	Name string
+	// PublicKey contains the X25519 public key of the device (required).
+	PublicKey []byte
+	// UserID identifies the user who owns this key.
+	UserID uuid.UUID
+}
+
+// Validate checks that the device key creation parameters are valid.
+func (p *NewDeviceKeyParams) Validate() error {
+	var errs []error
+	name := strings.TrimSpace(p.Name)
+	if name == "" || utf8.RuneCountInString(name) > maxNameLen {
+		errs = append(errs, ErrIncorrectName)
+	}
+	if !validPublicKey(p.PublicKey) {
+		errs = append(errs, ErrIncorrectPublicKey)
+	}
+	return errors.Join(errs...)
+}
+
+// validPublicKey reports whether the key is an X25519 public key agreeing on a non-zero shared secret.
+// Low-order points agree on the all-zero secret with every private key, so one random agreement tells them apart.
+func validPublicKey(key []byte) bool {
+	pub, err := ecdh.X25519().NewPublicKey(key)
+	if err != nil {
+		return false
+	}
+	probe, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return false
+	}
+	_, err = probe.ECDH(pub)
+	return err == nil
+}
@@ -0,0 +1,80 @@
+package devicekey
+
+import (
+	"crypto/ecdh"
+	"crypto/rand"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewDeviceKey(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	key, err := ecdh.X25519().GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	publicKey := key.PublicKey().Bytes()
+
+	tests := []struct {
+		wantErr  error
+		name     string
+		wantName string
+		params   NewDeviceKeyParams
+	}{
+		{
+			name:     "valid key",
+			params:   NewDeviceKeyParams{Name: "phone", PublicKey: publicKey, UserID: userID},
+			wantName: "phone",
+		},
+		{
+			name:     "name trimmed",
+			params:   NewDeviceKeyParams{Name: "  laptop  ", PublicKey: publicKey, UserID: userID},
+			wantName: "laptop",
+		},
+		{
+			name:    "blank name",
+			params:  NewDeviceKeyParams{Name: "   ", PublicKey: publicKey, UserID: userID},
+			wantErr: ErrIncorrectName,
+		},
+		{
+			name:    "too long name",
+			params:  NewDeviceKeyParams{Name: strings.Repeat("a", 65), PublicKey: publicKey, UserID: userID},
+			wantErr: ErrIncorrectName,
+		},
+		{
+			name:    "short public key",
+			params:  NewDeviceKeyParams{Name: "phone", PublicKey: publicKey[:31], UserID: userID},
+			wantErr: ErrIncorrectPublicKey,
+		},
+		{
+			name:    "low-order public key",
+			params:  NewDeviceKeyParams{Name: "phone", PublicKey: make([]byte, 32), UserID: userID},
+			wantErr: ErrIncorrectPublicKey,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			k, err := NewDeviceKey(tt.params)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, ErrNewDeviceKeyParamsValidation)
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, k)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotEqual(t, uuid.Nil, k.ID)
+			assert.Equal(t, userID, k.UserID)
+			assert.Equal(t, tt.wantName, k.Name)
+			assert.Equal(t, publicKey, k.PublicKey)
+			assert.False(t, k.CreatedAt.IsZero())
+		})
+	}
+}
@@ -0,0 +1,6 @@
+// Package devicekey provides device key domain entities and business rules
+// for the AegisVaultKeeper server.
+//
+// This package implements core domain logic for the X25519 public keys devices of a user register,
+// so sync payloads can be encrypted for a single device on top of TLS.
+package devicekey
@@ -0,0 +1,15 @@
+package devicekey
+
+import "errors"
+
+// Device key domain error definitions.
+var (
+	// ErrNewDeviceKeyParamsValidation indicates that device key creation parameters failed validation.
+	ErrNewDeviceKeyParamsValidation = errors.New("new device key parameters validation failed")
+
+	// ErrIncorrectName indicates that the device key name is empty or too long.
+	ErrIncorrectName = errors.New("incorrect device key name")
+
+	// ErrIncorrectPublicKey indicates that the device public key is not a usable X25519 key.
+	ErrIncorrectPublicKey = errors.New("incorrect device public key")
+)
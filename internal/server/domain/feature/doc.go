@@ -0,0 +1,6 @@
+// Package feature provides feature flag domain entities and business rules
+// for the AegisVaultKeeper server.
+//
+// This package implements core domain logic for stored feature flag overrides that
+// switch experimental endpoints and behaviors on or off per deployment or per user.
+package feature
@@ -0,0 +1,12 @@
+package feature
+
+import "errors"
+
+// Feature flag domain error definitions.
+var (
+	// ErrNewFlagParamsValidation indicates that feature flag creation parameters failed validation.
+	ErrNewFlagParamsValidation = errors.New("new feature flag parameters validation failed")
+
+	// ErrIncorrectKey indicates that the feature flag key is malformed.
+	ErrIncorrectKey = errors.New("incorrect feature flag key")
+)
@@ -0,0 +1,78 @@
+package feature
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// maxKeyLen limits the length of feature flag keys.
+const maxKeyLen = 64
+
+// Flag overrides whether a feature is enabled.
+// Flags without an owner apply to the whole deployment, others only to their user.
+type Flag struct {
+	// UpdatedAt contains the timestamp when the flag was last set.
+	UpdatedAt time.Time
+	// Key identifies the feature.
+	Key string
+	// UserID identifies the user the flag applies to, or uuid.Nil for deployment-wide flags.
+	UserID uuid.UUID
+	// Enabled determines whether the feature is switched on.
+	Enabled bool
+}
+
+// NewFlag creates a new feature flag with the provided parameters after validation.
+func NewFlag(params NewFlagParams) (*Flag, error) {
+	if err := params.Validate(); err != nil {
+		return nil, errors.Join(ErrNewFlagParamsValidation, err)
+	}
+
+	return &Flag{
+		Key:       params.Key,
+		UserID:    params.UserID,
+		Enabled:   params.Enabled,
+		UpdatedAt: time.Now(),
+	}, nil
+}
+
+// IsGlobal reports whether the flag applies to all users.
+func (f *Flag) IsGlobal() bool {
+	return f.UserID == uuid.Nil
+}
+
+// NewFlagParams contains parameters for creating a new feature flag.
+type NewFlagParams struct {
+	// Key identifies the feature (required).
+	Key string
+	// UserID identifies the user the flag applies to, or uuid.Nil for a deployment-wide flag.
+	UserID uuid.UUID
+	// Enabled determines whether the feature is switched on.
+	Enabled bool
+}
+
+// Validate checks that the feature flag creation parameters are valid.
+func (p *NewFlagParams) Validate() error {
+	if !IsValidKey(p.Key) {
+		return ErrIncorrectKey
+	}
+	return nil
+}
+
+// IsValidKey reports whether s is a feature flag key: up to 64 lowercase letters, digits,
+// dots, dashes or underscores, starting with a letter or digit.
+func IsValidKey(s string) bool {
+	if s == "" || len(s) > maxKeyLen {
+		return false
+	}
+	for i, c := range s {
+		switch {
+		case c >= 'a' && c <= 'z', c >= '0' && c <= '9':
+		case i > 0 && (c == '.' || c == '-' || c == '_'):
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,90 @@
+package feature
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewFlag(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+
+	tests := []struct {
+		wantErr error
+		name    string
+		params  NewFlagParams
+	}{
+		{
+			name:   "global flag",
+			params: NewFlagParams{Key: "sync_v2", Enabled: true},
+		},
+		{
+			name:   "user flag",
+			params: NewFlagParams{Key: "crypto.v2", UserID: userID},
+		},
+		{
+			name:    "empty key",
+			params:  NewFlagParams{Enabled: true},
+			wantErr: ErrIncorrectKey,
+		},
+		{
+			name:    "malformed key",
+			params:  NewFlagParams{Key: "Sync V2"},
+			wantErr: ErrIncorrectKey,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			f, err := NewFlag(tt.params)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, ErrNewFlagParamsValidation)
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, f)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.params.Key, f.Key)
+			assert.Equal(t, tt.params.UserID, f.UserID)
+			assert.Equal(t, tt.params.Enabled, f.Enabled)
+			assert.False(t, f.UpdatedAt.IsZero())
+			assert.Equal(t, tt.params.UserID == uuid.Nil, f.IsGlobal())
+		})
+	}
+}
+
+func TestIsValidKey(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		key  string
+		want bool
+	}{
+		{name: "letters and digits", key: "sync2", want: true},
+		{name: "separators", key: "crypto.scheme-v2_beta", want: true},
+		{name: "longest", key: strings.Repeat("a", 64), want: true},
+		{name: "empty", key: "", want: false},
+		{name: "too long", key: strings.Repeat("a", 65), want: false},
+		{name: "uppercase", key: "Sync", want: false},
+		{name: "leading separator", key: "_sync", want: false},
+		{name: "space", key: "sync v2", want: false},
+		{name: "equals sign", key: "sync=true", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, IsValidKey(tt.key))
+		})
+	}
+}
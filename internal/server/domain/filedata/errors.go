@@ -8,6 +8,11 @@ var (
 	// ErrIncorrectHashSum indicates that the provided hash sum is invalid or doesn't match.
 	ErrIncorrectHashSum = errors.New("invalid hash sum")
 
+	// ErrIncorrectFolder indicates that the provided folder path is invalid.
+	ErrIncorrectFolder = errors.New("invalid folder path")
+
 	// ErrNewFileParamsValidation indicates that file creation parameters failed validation.
 	ErrNewFileParamsValidation = errors.New("new file parameters validation failed")
+	// ErrNewFolderParamsValidation indicates that folder creation parameters failed validation.
+	ErrNewFolderParamsValidation = errors.New("new folder parameters validation failed")
 )
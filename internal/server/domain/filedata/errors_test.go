@@ -24,11 +24,21 @@ func TestErrors(t *testing.T) {
 			err:  ErrIncorrectHashSum,
 			want: "invalid hash sum",
 		},
+		{
+			name: "ErrIncorrectFolder",
+			err:  ErrIncorrectFolder,
+			want: "invalid folder path",
+		},
 		{
 			name: "ErrNewFileParamsValidation",
 			err:  ErrNewFileParamsValidation,
 			want: "new file parameters validation failed",
 		},
+		{
+			name: "ErrNewFolderParamsValidation",
+			err:  ErrNewFolderParamsValidation,
+			want: "new folder parameters validation failed",
+		},
 	}
 
 	for _, tt := range tests {
@@ -20,10 +20,16 @@ type FileData struct {
 	StorageKey []byte
 	// HashSum contains the encrypted SHA256 hash of the file content.
 	HashSum []byte
+	// FileKey contains the per-file content key wrapped by the user key; empty for legacy files.
+	FileKey []byte
+	// Folder contains the encrypted path of the folder holding the file; empty for the root folder.
+	Folder []byte
 	// ID uniquely identifies this file.
 	ID uuid.UUID
 	// UserID identifies the user who owns this file.
 	UserID uuid.UUID
+	// Size contains the size of the file content in bytes; zero for files stored before sizes were recorded.
+	Size int64
 }
 
 // NewFileDataParams contains parameters for creating a new file data entry.
@@ -34,8 +40,12 @@ type NewFileDataParams struct {
 	StorageKey string
 	// HashSum contains the SHA256 hash of the file content (required, validated as hex).
 	HashSum string
+	// Folder contains the path of the folder holding the file; empty for the root folder.
+	Folder string
 	// UserID identifies the user who will own this file.
 	UserID uuid.UUID
+	// Size contains the size of the file content in bytes.
+	Size int64
 }
 
 // NewFile creates a new file data entry with the provided parameters after validation.
@@ -49,15 +59,39 @@ func NewFile(p NewFileDataParams) (*FileData, error) {
 		Description: []byte(p.Description),
 		StorageKey:  []byte(normalizeSlash(p.StorageKey)),
 		HashSum:     []byte(strings.ToLower(strings.TrimSpace(p.HashSum))),
+		Folder:      []byte(NormalizeFolder(p.Folder)),
+		Size:        p.Size,
 		UpdatedAt:   time.Now(),
 	}, nil
 }
 
+// Relocate moves the file into the folder and renames it to the storage key after validation.
+// An empty storage key keeps the current one; an empty folder moves the file to the root folder.
+func (fd *FileData) Relocate(folder, storageKey string) error {
+	if storageKey == "" {
+		storageKey = string(fd.StorageKey)
+	}
+	storageKey = normalizeSlash(strings.TrimSpace(storageKey))
+	if !validStorageKey(storageKey) {
+		return ErrIncorrectStorageKey
+	}
+	folder = NormalizeFolder(folder)
+	if folder != "" && !ValidFolder(folder) {
+		return ErrIncorrectFolder
+	}
+
+	fd.StorageKey = []byte(storageKey)
+	fd.Folder = []byte(folder)
+	fd.UpdatedAt = time.Now()
+	return nil
+}
+
 // Validate checks that the file creation parameters are valid and secure.
 func (p *NewFileDataParams) Validate() error {
 	validations := []func() error{
 		p.validateStorageKey,
 		p.validateHashSum,
+		p.validateFolder,
 	}
 
 	// errs collects all validation errors encountered during file data validation.
@@ -82,6 +116,27 @@ func (p *NewFileDataParams) validateStorageKey() error {
 	return nil
 }
 
+// RebaseFolder moves the file from below the from folder to below the to folder, keeping its position
+// relative to from. It reports whether the file lay below from and was changed.
+func (fd *FileData) RebaseFolder(from, to string) bool {
+	folder, ok := RebaseFolder(string(fd.Folder), from, to)
+	if !ok {
+		return false
+	}
+	fd.Folder = []byte(folder)
+	fd.UpdatedAt = time.Now()
+	return true
+}
+
+// validateFolder ensures the folder is empty or a valid folder path.
+func (p *NewFileDataParams) validateFolder() error {
+	folder := NormalizeFolder(p.Folder)
+	if folder != "" && !ValidFolder(folder) {
+		return ErrIncorrectFolder
+	}
+	return nil
+}
+
 // validateHashSum ensures the hash sum is a valid SHA256 hex string.
 func (p *NewFileDataParams) validateHashSum() error {
 	hs := strings.ToLower(strings.TrimSpace(p.HashSum))
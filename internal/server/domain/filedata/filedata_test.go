@@ -33,6 +33,7 @@ func TestNewFile(t *testing.T) {
 					StorageKey:  "documents/file.pdf",
 					HashSum:     validHashSum,
 					UserID:      userID,
+					Size:        2048,
 				},
 			},
 			want: func(t *testing.T, fd *FileData) {
@@ -42,6 +43,7 @@ func TestNewFile(t *testing.T) {
 				assert.Equal(t, []byte("Important document"), fd.Description)
 				assert.Equal(t, []byte("documents/file.pdf"), fd.StorageKey)
 				assert.Equal(t, []byte(validHashSum), fd.HashSum)
+				assert.Equal(t, int64(2048), fd.Size)
 				assert.WithinDuration(t, time.Now(), fd.UpdatedAt, time.Second)
 			},
 		},
@@ -118,6 +120,33 @@ func TestNewFile(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "valid/normalized_folder",
+			args: args{
+				params: NewFileDataParams{
+					StorageKey: "file.txt",
+					HashSum:    validHashSum,
+					Folder:     " /docs/taxes/ ",
+					UserID:     userID,
+				},
+			},
+			want: func(t *testing.T, fd *FileData) {
+				t.Helper()
+				assert.Equal(t, []byte("docs/taxes"), fd.Folder)
+			},
+		},
+		{
+			name: "invalid/folder",
+			args: args{
+				params: NewFileDataParams{
+					StorageKey: "file.txt",
+					HashSum:    validHashSum,
+					Folder:     "docs/../etc",
+					UserID:     userID,
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name: "invalid/invalid_hash_sum",
 			args: args{
@@ -560,3 +589,65 @@ func TestNormalizeSlash(t *testing.T) {
 		})
 	}
 }
+
+func TestFileData_Relocate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		folder     string
+		storageKey string
+		wantKey    string
+		wantFolder string
+		wantErr    error
+	}{
+		{name: "move_keeps_name", folder: "docs/", wantKey: "old.txt", wantFolder: "docs"},
+		{name: "rename", storageKey: `reports\new.txt`, wantKey: "reports/new.txt", wantFolder: ""},
+		{name: "invalid_storage_key", storageKey: "../escape.txt", wantErr: ErrIncorrectStorageKey},
+		{name: "invalid_folder", folder: "a//b", wantErr: ErrIncorrectFolder},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			fd := &FileData{StorageKey: []byte("old.txt"), Folder: []byte("inbox")}
+			err := fd.Relocate(tt.folder, tt.storageKey)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Equal(t, []byte("old.txt"), fd.StorageKey)
+				assert.Equal(t, []byte("inbox"), fd.Folder)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, []byte(tt.wantKey), fd.StorageKey)
+			assert.Equal(t, []byte(tt.wantFolder), fd.Folder)
+		})
+	}
+}
+
+func TestFileData_RebaseFolder(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		folder     string
+		wantFolder string
+		want       bool
+	}{
+		{name: "in_moved_folder", folder: "docs", wantFolder: "archive/docs", want: true},
+		{name: "below_moved_folder", folder: "docs/taxes", wantFolder: "archive/docs/taxes", want: true},
+		{name: "root", folder: "", wantFolder: ""},
+		{name: "other_folder", folder: "docs2", wantFolder: "docs2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			fd := &FileData{Folder: []byte(tt.folder)}
+			assert.Equal(t, tt.want, fd.RebaseFolder("docs", "archive/docs"))
+			assert.Equal(t, tt.wantFolder, string(fd.Folder))
+		})
+	}
+}
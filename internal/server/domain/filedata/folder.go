@@ -0,0 +1,99 @@
+package filedata
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/itempath"
+	"github.com/google/uuid"
+)
+
+// Folder represents a folder organizing the files of a user into a hierarchy.
+type Folder struct {
+	// UpdatedAt contains the timestamp when the folder was last created, renamed or moved.
+	UpdatedAt time.Time
+	// Path contains the encrypted slash-separated path of the folder, such as docs/taxes.
+	Path []byte
+	// ID uniquely identifies this folder.
+	ID uuid.UUID
+	// UserID identifies the user who owns this folder.
+	UserID uuid.UUID
+}
+
+// NewFolderParams contains parameters for creating a new folder.
+type NewFolderParams struct {
+	// Path contains the path of the folder (required, validated with ValidFolder).
+	Path string
+	// UserID identifies the user who will own this folder.
+	UserID uuid.UUID
+}
+
+// NewFolder creates a new folder with the provided parameters after validation.
+func NewFolder(p NewFolderParams) (*Folder, error) {
+	if err := p.Validate(); err != nil {
+		return nil, errors.Join(ErrNewFolderParamsValidation, err)
+	}
+	return &Folder{
+		ID:        uuid.New(),
+		UserID:    p.UserID,
+		Path:      []byte(NormalizeFolder(p.Path)),
+		UpdatedAt: time.Now(),
+	}, nil
+}
+
+// Validate checks that the folder creation parameters are valid.
+func (p *NewFolderParams) Validate() error {
+	if !ValidFolder(NormalizeFolder(p.Path)) {
+		return ErrIncorrectFolder
+	}
+	return nil
+}
+
+// Rebase moves the folder from below the from path to below the to path, keeping its position relative to from.
+// It reports whether the folder lay below from and was changed.
+func (f *Folder) Rebase(from, to string) bool {
+	path, ok := RebaseFolder(string(f.Path), from, to)
+	if !ok {
+		return false
+	}
+	f.Path = []byte(path)
+	f.UpdatedAt = time.Now()
+	return true
+}
+
+// NormalizeFolder trims surrounding spaces and slashes from the folder path; the root folder is empty.
+func NormalizeFolder(path string) string {
+	return itempath.Normalize(path)
+}
+
+// ValidFolder reports whether the normalized folder path follows the item path rules of itempath.Valid.
+func ValidFolder(path string) bool {
+	return itempath.Valid(path)
+}
+
+// ParentFolder returns the path of the folder directly containing the folder path; the root folder is empty.
+func ParentFolder(path string) string {
+	i := strings.LastIndex(path, "/")
+	if i < 0 {
+		return ""
+	}
+	return path[:i]
+}
+
+// InFolder reports whether the path equals the folder or lies below it; every path lies below the root folder.
+func InFolder(path, folder string) bool {
+	return folder == "" || path == folder || strings.HasPrefix(path, folder+"/")
+}
+
+// RebaseFolder replaces the from prefix of the path with to and reports whether the path lay below from.
+func RebaseFolder(path, from, to string) (string, bool) {
+	if from == "" || !InFolder(path, from) {
+		return path, false
+	}
+	rest := strings.TrimPrefix(path, from)
+	if to == "" {
+		return strings.TrimPrefix(rest, "/"), true
+	}
+	return to + rest, true
+}
@@ -0,0 +1,129 @@
+package filedata
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewFolder(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+
+	tests := []struct {
+		name     string
+		path     string
+		wantPath string
+		wantErr  bool
+	}{
+		{name: "valid", path: "docs/taxes", wantPath: "docs/taxes"},
+		{name: "normalized", path: " /docs/ ", wantPath: "docs"},
+		{name: "empty", path: " / ", wantErr: true},
+		{name: "traversal", path: "docs/../etc", wantErr: true},
+		{name: "invalid_characters", path: "docs/my taxes", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := NewFolder(NewFolderParams{Path: tt.path, UserID: userID})
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.ErrorIs(t, err, ErrNewFolderParamsValidation)
+				assert.ErrorIs(t, err, ErrIncorrectFolder)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotEqual(t, uuid.Nil, got.ID)
+			assert.Equal(t, userID, got.UserID)
+			assert.Equal(t, []byte(tt.wantPath), got.Path)
+		})
+	}
+}
+
+func TestFolder_Rebase(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		path     string
+		from     string
+		to       string
+		wantPath string
+		want     bool
+	}{
+		{name: "renamed_itself", path: "docs", from: "docs", to: "papers", wantPath: "papers", want: true},
+		{
+			name:     "descendant",
+			path:     "docs/taxes/2025",
+			from:     "docs",
+			to:       "archive/docs",
+			wantPath: "archive/docs/taxes/2025",
+			want:     true,
+		},
+		{name: "moved_to_root", path: "archive/docs/taxes", from: "archive/docs", to: "", wantPath: "taxes", want: true},
+		{name: "sibling_with_common_prefix", path: "docs2/a", from: "docs", to: "papers", wantPath: "docs2/a"},
+		{name: "unrelated", path: "photos", from: "docs", to: "papers", wantPath: "photos"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			f := &Folder{Path: []byte(tt.path)}
+			assert.Equal(t, tt.want, f.Rebase(tt.from, tt.to))
+			assert.Equal(t, tt.wantPath, string(f.Path))
+		})
+	}
+}
+
+func TestParentFolder(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		path string
+		want string
+	}{
+		{name: "root", path: "", want: ""},
+		{name: "top_level", path: "docs", want: ""},
+		{name: "nested", path: "docs/taxes/2025", want: "docs/taxes"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, ParentFolder(tt.path))
+		})
+	}
+}
+
+func TestInFolder(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		path   string
+		folder string
+		want   bool
+	}{
+		{name: "root_contains_everything", path: "docs/a", folder: "", want: true},
+		{name: "same", path: "docs", folder: "docs", want: true},
+		{name: "below", path: "docs/a/b", folder: "docs", want: true},
+		{name: "common_prefix", path: "docs2", folder: "docs"},
+		{name: "above", path: "docs", folder: "docs/a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, InFolder(tt.path, tt.folder))
+		})
+	}
+}
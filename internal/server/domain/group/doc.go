@@ -0,0 +1,6 @@
+// Package group provides user group domain entities and business rules
+// for the AegisVaultKeeper server.
+//
+// This package implements core domain logic for the groups an enterprise directory
+// assigns users to through SCIM provisioning.
+package group
@@ -0,0 +1,11 @@
+package group
+
+import "errors"
+
+// Group domain error definitions.
+var (
+	// ErrNewGroupParamsValidation indicates that group creation parameters failed validation.
+	ErrNewGroupParamsValidation = errors.New("new group parameters validation failed")
+	// ErrIncorrectDisplayName indicates that the group display name is empty or too long.
+	ErrIncorrectDisplayName = errors.New("incorrect group display name")
+)
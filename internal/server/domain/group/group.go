@@ -0,0 +1,79 @@
+package group
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// maxDisplayNameLen limits the length of group display names in characters.
+const maxDisplayNameLen = 256
+
+// Group is a set of users maintained by an enterprise directory.
+type Group struct {
+	// CreatedAt contains the timestamp when the group was created.
+	CreatedAt time.Time
+	// UpdatedAt contains the timestamp when the group was last changed.
+	UpdatedAt time.Time
+	// DisplayName contains the unique human-readable name of the group.
+	DisplayName string
+	// ExternalID contains the identifier of the group in the enterprise directory.
+	ExternalID string
+	// Members lists the identifiers of the users in the group.
+	Members []uuid.UUID
+	// ID uniquely identifies the group.
+	ID uuid.UUID
+}
+
+// NewGroup creates a new group with the provided parameters after validation.
+func NewGroup(params NewGroupParams) (*Group, error) {
+	if err := params.Validate(); err != nil {
+		return nil, errors.Join(ErrNewGroupParamsValidation, err)
+	}
+
+	now := time.Now()
+	return &Group{
+		ID:          uuid.New(),
+		DisplayName: strings.TrimSpace(params.DisplayName),
+		ExternalID:  params.ExternalID,
+		Members:     params.Members,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}, nil
+}
+
+// Rename changes the display name of the group after validation.
+func (g *Group) Rename(displayName string) error {
+	if err := validateDisplayName(displayName); err != nil {
+		return err
+	}
+	g.DisplayName = strings.TrimSpace(displayName)
+	return nil
+}
+
+// NewGroupParams contains parameters for creating a new group.
+type NewGroupParams struct {
+	// DisplayName contains the unique human-readable name of the group (required).
+	DisplayName string
+	// ExternalID contains the identifier of the group in the enterprise directory.
+	ExternalID string
+	// Members lists the identifiers of the users in the group.
+	Members []uuid.UUID
+}
+
+// Validate checks that the group creation parameters are valid.
+func (p *NewGroupParams) Validate() error {
+	return validateDisplayName(p.DisplayName)
+}
+
+// validateDisplayName checks that the display name is not blank and not longer than maxDisplayNameLen.
+func validateDisplayName(displayName string) error {
+	name := strings.TrimSpace(displayName)
+	if name == "" || utf8.RuneCountInString(name) > maxDisplayNameLen {
+		return ErrIncorrectDisplayName
+	}
+	return nil
+}
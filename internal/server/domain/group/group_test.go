@@ -0,0 +1,98 @@
+package group
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewGroup(t *testing.T) {
+	t.Parallel()
+
+	members := []uuid.UUID{uuid.New(), uuid.New()}
+
+	tests := []struct {
+		wantErr  error
+		name     string
+		wantName string
+		params   NewGroupParams
+	}{
+		{
+			name:     "valid group",
+			params:   NewGroupParams{DisplayName: "Engineering", ExternalID: "00g1", Members: members},
+			wantName: "Engineering",
+		},
+		{
+			name:     "name trimmed",
+			params:   NewGroupParams{DisplayName: "  Finance  "},
+			wantName: "Finance",
+		},
+		{
+			name:     "longest name",
+			params:   NewGroupParams{DisplayName: strings.Repeat("я", maxDisplayNameLen)},
+			wantName: strings.Repeat("я", maxDisplayNameLen),
+		},
+		{
+			name:    "blank name",
+			params:  NewGroupParams{DisplayName: "   "},
+			wantErr: ErrIncorrectDisplayName,
+		},
+		{
+			name:    "too long name",
+			params:  NewGroupParams{DisplayName: strings.Repeat("a", maxDisplayNameLen+1)},
+			wantErr: ErrIncorrectDisplayName,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			g, err := NewGroup(tt.params)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, ErrNewGroupParamsValidation)
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, g)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotEqual(t, uuid.Nil, g.ID)
+			assert.Equal(t, tt.wantName, g.DisplayName)
+			assert.Equal(t, tt.params.ExternalID, g.ExternalID)
+			assert.Equal(t, tt.params.Members, g.Members)
+			assert.False(t, g.CreatedAt.IsZero())
+			assert.Equal(t, g.CreatedAt, g.UpdatedAt)
+		})
+	}
+}
+
+func TestGroup_Rename(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		wantErr  error
+		name     string
+		newName  string
+		wantName string
+	}{
+		{name: "valid name", newName: " Platform ", wantName: "Platform"},
+		{name: "blank name", newName: "", wantName: "Engineering", wantErr: ErrIncorrectDisplayName},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			g := &Group{DisplayName: "Engineering"}
+
+			err := g.Rename(tt.newName)
+
+			require.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.wantName, g.DisplayName)
+		})
+	}
+}
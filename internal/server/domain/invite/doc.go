@@ -0,0 +1,5 @@
+// Package invite provides invite code domain entities and business rules for the AegisVaultKeeper server.
+//
+// This package implements core domain logic for the single-use codes new users register with when
+// registration is invitation-only, including the group memberships and invite quota they grant.
+package invite
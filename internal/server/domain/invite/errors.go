@@ -0,0 +1,27 @@
+package invite
+
+import "errors"
+
+// Invite domain error definitions.
+var (
+	// ErrNewInviteParamsValidation indicates that invite creation parameters failed validation.
+	ErrNewInviteParamsValidation = errors.New("new invite parameters validation failed")
+
+	// ErrIncorrectCode indicates that the invite code is too short.
+	ErrIncorrectCode = errors.New("incorrect invite code")
+
+	// ErrIncorrectNote indicates that the invite note is too long.
+	ErrIncorrectNote = errors.New("incorrect invite note")
+
+	// ErrIncorrectExpiry indicates that the invite expires in the past or too far in the future.
+	ErrIncorrectExpiry = errors.New("incorrect invite expiry")
+
+	// ErrIncorrectPresets indicates that the invite grants too many groups or too large an invite quota.
+	ErrIncorrectPresets = errors.New("incorrect invite presets")
+
+	// ErrInviteUnusable indicates that the invite was already redeemed, was revoked or has expired.
+	ErrInviteUnusable = errors.New("invite is not usable")
+
+	// ErrInviteRedeemed indicates that a redeemed invite cannot be revoked.
+	ErrInviteRedeemed = errors.New("invite already redeemed")
+)
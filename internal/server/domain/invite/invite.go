@@ -0,0 +1,164 @@
+package invite
+
+import (
+	"crypto/sha256"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// Invite limits.
+const (
+	// MinCodeLen defines the minimum length of invite codes.
+	MinCodeLen = 20
+	// maxNoteLen limits the length of invite notes in characters.
+	maxNoteLen = 256
+	// MaxLifetime limits how far in the future invites may expire.
+	MaxLifetime = 90 * 24 * time.Hour
+	// maxGroups limits the number of groups an invite adds the new user to.
+	maxGroups = 20
+	// MaxInviteQuota limits the number of invites an invite allows the new user to create.
+	MaxInviteQuota = 1000
+)
+
+// Status names the state of an invite.
+type Status string
+
+// Invite states.
+const (
+	// StatusPending marks an invite that can still be redeemed.
+	StatusPending Status = "pending"
+	// StatusRedeemed marks an invite a user registered with.
+	StatusRedeemed Status = "redeemed"
+	// StatusRevoked marks an invite revoked before it was redeemed.
+	StatusRevoked Status = "revoked"
+	// StatusExpired marks an invite that expired before it was redeemed.
+	StatusExpired Status = "expired"
+)
+
+// Invite is a single-use code a new user registers with.
+type Invite struct {
+	// CreatedAt contains the timestamp when the invite was created.
+	CreatedAt time.Time
+	// ExpiresAt contains the timestamp after which the invite can no longer be redeemed.
+	ExpiresAt time.Time
+	// RevokedAt contains the timestamp when the invite was revoked; zero if it was not.
+	RevokedAt time.Time
+	// RedeemedAt contains the timestamp when the invite was redeemed; zero if it was not.
+	RedeemedAt time.Time
+	// Note describes whom the invite is meant for.
+	Note string
+	// CodeHash contains the SHA-256 digest of the invite code.
+	CodeHash []byte
+	// Groups lists the groups the new user is added to.
+	Groups []uuid.UUID
+	// InviteQuota specifies how many invites the new user may create.
+	InviteQuota int
+	// ID uniquely identifies this invite.
+	ID uuid.UUID
+	// CreatedBy identifies the user who created the invite; uuid.Nil for administrators.
+	CreatedBy uuid.UUID
+	// RedeemedBy identifies the user registered with the invite; uuid.Nil if it was not redeemed.
+	RedeemedBy uuid.UUID
+}
+
+// NewInvite creates a new invite with the provided parameters after validation.
+func NewInvite(params NewInviteParams) (*Invite, error) {
+	now := time.Now()
+	if err := params.validate(now); err != nil {
+		return nil, errors.Join(ErrNewInviteParamsValidation, err)
+	}
+
+	return &Invite{
+		ID:          uuid.New(),
+		CodeHash:    HashCode(params.Code),
+		Note:        strings.TrimSpace(params.Note),
+		Groups:      slices.Clone(params.Groups),
+		InviteQuota: params.InviteQuota,
+		CreatedBy:   params.CreatedBy,
+		CreatedAt:   now,
+		ExpiresAt:   params.ExpiresAt,
+	}, nil
+}
+
+// HashCode returns the SHA-256 digest invite codes are stored and looked up by.
+func HashCode(code string) []byte {
+	sum := sha256.Sum256([]byte(code))
+	return sum[:]
+}
+
+// Status reports the state of the invite at the specified time.
+func (i *Invite) Status(at time.Time) Status {
+	switch {
+	case !i.RedeemedAt.IsZero():
+		return StatusRedeemed
+	case !i.RevokedAt.IsZero():
+		return StatusRevoked
+	case !at.Before(i.ExpiresAt):
+		return StatusExpired
+	default:
+		return StatusPending
+	}
+}
+
+// Redeem marks the invite as redeemed by the user at the specified time.
+// It fails with ErrInviteUnusable unless the invite is pending.
+func (i *Invite) Redeem(userID uuid.UUID, at time.Time) error {
+	if i.Status(at) != StatusPending {
+		return ErrInviteUnusable
+	}
+	i.RedeemedBy = userID
+	i.RedeemedAt = at
+	return nil
+}
+
+// Revoke prevents the invite from being redeemed. Revoking a revoked or expired invite has no effect;
+// a redeemed invite cannot be revoked.
+func (i *Invite) Revoke(at time.Time) error {
+	if !i.RedeemedAt.IsZero() {
+		return ErrInviteRedeemed
+	}
+	if i.RevokedAt.IsZero() {
+		i.RevokedAt = at
+	}
+	return nil
+}
+
+// NewInviteParams contains parameters for creating a new invite.
+type NewInviteParams struct {
+	// ExpiresAt specifies when the invite can no longer be redeemed (required, at most MaxLifetime ahead).
+	ExpiresAt time.Time
+	// Code contains the invite code of at least MinCodeLen characters (required).
+	Code string
+	// Note describes whom the invite is meant for (optional).
+	Note string
+	// Groups lists the groups the new user is added to (optional).
+	Groups []uuid.UUID
+	// InviteQuota specifies how many invites the new user may create (optional).
+	InviteQuota int
+	// CreatedBy identifies the user creating the invite; uuid.Nil for administrators.
+	CreatedBy uuid.UUID
+}
+
+// validate checks that the invite creation parameters are valid at the specified time.
+func (p *NewInviteParams) validate(now time.Time) error {
+	var errs []error
+	if len(p.Code) < MinCodeLen {
+		errs = append(errs, ErrIncorrectCode)
+	}
+	if utf8.RuneCountInString(strings.TrimSpace(p.Note)) > maxNoteLen {
+		errs = append(errs, ErrIncorrectNote)
+	}
+	if !p.ExpiresAt.After(now) || p.ExpiresAt.Sub(now) > MaxLifetime {
+		errs = append(errs, ErrIncorrectExpiry)
+	}
+	if len(p.Groups) > maxGroups || slices.Contains(p.Groups, uuid.Nil) ||
+		p.InviteQuota < 0 || p.InviteQuota > MaxInviteQuota {
+		errs = append(errs, ErrIncorrectPresets)
+	}
+	return errors.Join(errs...)
+}
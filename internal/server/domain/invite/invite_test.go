@@ -0,0 +1,123 @@
+package invite
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewInvite(t *testing.T) {
+	t.Parallel()
+
+	code := strings.Repeat("c", MinCodeLen)
+	expiresAt := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		wantErr error
+		name    string
+		params  NewInviteParams
+	}{
+		{
+			name:   "valid invite",
+			params: NewInviteParams{Code: code, Note: "  for Alex  ", ExpiresAt: expiresAt},
+		},
+		{
+			name: "with presets",
+			params: NewInviteParams{
+				Code: code, ExpiresAt: expiresAt, Groups: []uuid.UUID{uuid.New()}, InviteQuota: MaxInviteQuota,
+			},
+		},
+		{
+			name:    "short code",
+			params:  NewInviteParams{Code: code[1:], ExpiresAt: expiresAt},
+			wantErr: ErrIncorrectCode,
+		},
+		{
+			name:    "too long note",
+			params:  NewInviteParams{Code: code, Note: strings.Repeat("n", 257), ExpiresAt: expiresAt},
+			wantErr: ErrIncorrectNote,
+		},
+		{
+			name:    "already expired",
+			params:  NewInviteParams{Code: code, ExpiresAt: time.Now().Add(-time.Minute)},
+			wantErr: ErrIncorrectExpiry,
+		},
+		{
+			name:    "too long lifetime",
+			params:  NewInviteParams{Code: code, ExpiresAt: time.Now().Add(MaxLifetime + time.Hour)},
+			wantErr: ErrIncorrectExpiry,
+		},
+		{
+			name:    "negative quota",
+			params:  NewInviteParams{Code: code, ExpiresAt: expiresAt, InviteQuota: -1},
+			wantErr: ErrIncorrectPresets,
+		},
+		{
+			name:    "nil group",
+			params:  NewInviteParams{Code: code, ExpiresAt: expiresAt, Groups: []uuid.UUID{uuid.Nil}},
+			wantErr: ErrIncorrectPresets,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			i, err := NewInvite(tt.params)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, ErrNewInviteParamsValidation)
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, HashCode(code), i.CodeHash)
+			assert.Equal(t, strings.TrimSpace(tt.params.Note), i.Note)
+			assert.Equal(t, tt.params.InviteQuota, i.InviteQuota)
+			assert.Equal(t, StatusPending, i.Status(time.Now()))
+		})
+	}
+}
+
+func TestInvite_Lifecycle(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	newInvite := func() *Invite {
+		return &Invite{ID: uuid.New(), CreatedAt: now, ExpiresAt: now.Add(time.Hour)}
+	}
+
+	t.Run("redeem once", func(t *testing.T) {
+		t.Parallel()
+
+		i := newInvite()
+		userID := uuid.New()
+		require.NoError(t, i.Redeem(userID, now))
+		assert.Equal(t, StatusRedeemed, i.Status(now))
+		assert.Equal(t, userID, i.RedeemedBy)
+		require.ErrorIs(t, i.Redeem(uuid.New(), now), ErrInviteUnusable)
+		require.ErrorIs(t, i.Revoke(now), ErrInviteRedeemed)
+	})
+
+	t.Run("revoked", func(t *testing.T) {
+		t.Parallel()
+
+		i := newInvite()
+		require.NoError(t, i.Revoke(now))
+		require.NoError(t, i.Revoke(now.Add(time.Minute)), "revoking again has no effect")
+		assert.Equal(t, now, i.RevokedAt)
+		assert.Equal(t, StatusRevoked, i.Status(now))
+		require.ErrorIs(t, i.Redeem(uuid.New(), now), ErrInviteUnusable)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		t.Parallel()
+
+		i := newInvite()
+		assert.Equal(t, StatusExpired, i.Status(i.ExpiresAt))
+		require.ErrorIs(t, i.Redeem(uuid.New(), i.ExpiresAt), ErrInviteUnusable)
+	})
+}
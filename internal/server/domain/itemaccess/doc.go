@@ -0,0 +1,5 @@
+// Package itemaccess provides item reveal domain entities for the AegisVaultKeeper server.
+//
+// This package implements the record of each reveal of a vault item and the access history built
+// from them, with reveal counters per device, so users can spot unexpected access to their secrets.
+package itemaccess
@@ -0,0 +1,15 @@
+package itemaccess
+
+import "errors"
+
+// Item access domain error definitions.
+var (
+	// ErrNewRevealParamsValidation indicates that item reveal parameters failed validation.
+	ErrNewRevealParamsValidation = errors.New("new item reveal parameters validation failed")
+
+	// ErrIncorrectItem indicates that the revealed item is not specified.
+	ErrIncorrectItem = errors.New("revealed item is not specified")
+
+	// ErrIncorrectUserID indicates that the user who revealed the item is not specified.
+	ErrIncorrectUserID = errors.New("revealing user is not specified")
+)
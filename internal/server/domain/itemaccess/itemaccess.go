@@ -0,0 +1,129 @@
+package itemaccess
+
+import (
+	"errors"
+	"net/netip"
+	"strings"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/device"
+	"github.com/google/uuid"
+)
+
+// maxUserAgentLen limits the stored user agent length.
+const maxUserAgentLen = 512
+
+// Reveal represents a single successful read of the secret content of a vault item.
+type Reveal struct {
+	// RevealedAt contains the moment the item was revealed.
+	RevealedAt time.Time
+	// IP contains the client address the item was revealed from, if known.
+	IP netip.Addr
+	// UserAgent contains the reported client software.
+	UserAgent string
+	// Fingerprint identifies the client software, grouping reveals by device like login devices.
+	Fingerprint string
+	// ID uniquely identifies this reveal.
+	ID uuid.UUID
+	// ItemID identifies the revealed item of any kind.
+	ItemID uuid.UUID
+	// UserID identifies the user who owns and revealed the item.
+	UserID uuid.UUID
+}
+
+// NewReveal creates a new item reveal happening now with the provided parameters after validation.
+func NewReveal(params NewRevealParams) (*Reveal, error) {
+	if err := params.Validate(); err != nil {
+		return nil, errors.Join(ErrNewRevealParamsValidation, err)
+	}
+
+	userAgent := strings.TrimSpace(params.UserAgent)
+	if len(userAgent) > maxUserAgentLen {
+		userAgent = userAgent[:maxUserAgentLen]
+	}
+	return &Reveal{
+		ID:          uuid.New(),
+		ItemID:      params.ItemID,
+		UserID:      params.UserID,
+		IP:          params.IP,
+		UserAgent:   userAgent,
+		Fingerprint: device.FingerprintOf(params.UserAgent),
+		RevealedAt:  time.Now(),
+	}, nil
+}
+
+// DeviceAccess summarizes the reveals of an item from one device.
+type DeviceAccess struct {
+	// LastRevealedAt contains the moment of the latest reveal from the device.
+	LastRevealedAt time.Time
+	// UserAgent contains the client software reported by the latest reveal from the device.
+	UserAgent string
+	// Fingerprint identifies the device.
+	Fingerprint string
+	// Count is the number of reveals from the device.
+	Count int
+}
+
+// History represents the access history of a vault item.
+type History struct {
+	// LastRevealedAt contains the moment of the latest reveal; zero when the item was never revealed.
+	LastRevealedAt time.Time
+	// Devices summarizes the reveals per device, most recently active first.
+	Devices []*DeviceAccess
+	// Reveals lists the latest reveals, newest first.
+	Reveals []*Reveal
+	// Count is the number of reveals of the item.
+	Count int
+}
+
+// NewHistory builds the access history of an item from its reveals ordered newest first.
+// Counters cover every reveal, while at most limit latest reveals are listed; a non-positive limit lists all.
+func NewHistory(reveals []*Reveal, limit int) *History {
+	h := &History{Count: len(reveals), Devices: []*DeviceAccess{}, Reveals: reveals}
+	if limit > 0 && len(reveals) > limit {
+		h.Reveals = reveals[:limit]
+	}
+	if len(reveals) == 0 {
+		h.Reveals = []*Reveal{}
+		return h
+	}
+	h.LastRevealedAt = reveals[0].RevealedAt
+
+	// devices indexes the device summaries by fingerprint.
+	devices := make(map[string]*DeviceAccess)
+	for _, r := range reveals {
+		d, ok := devices[r.Fingerprint]
+		if !ok {
+			d = &DeviceAccess{Fingerprint: r.Fingerprint, UserAgent: r.UserAgent, LastRevealedAt: r.RevealedAt}
+			devices[r.Fingerprint] = d
+			h.Devices = append(h.Devices, d)
+		}
+		d.Count++
+	}
+	return h
+}
+
+// NewRevealParams contains parameters for recording an item reveal.
+type NewRevealParams struct {
+	// IP contains the client address the item was revealed from, if known.
+	IP netip.Addr
+	// UserAgent contains the reported client software.
+	UserAgent string
+	// ItemID identifies the revealed item (required).
+	ItemID uuid.UUID
+	// UserID identifies the user who revealed the item (required).
+	UserID uuid.UUID
+}
+
+// Validate checks that the item reveal parameters are valid.
+func (p *NewRevealParams) Validate() error {
+	// errs collects all validation errors encountered during reveal validation.
+	var errs []error
+	if p.ItemID == uuid.Nil {
+		errs = append(errs, ErrIncorrectItem)
+	}
+	if p.UserID == uuid.Nil {
+		errs = append(errs, ErrIncorrectUserID)
+	}
+	return errors.Join(errs...)
+}
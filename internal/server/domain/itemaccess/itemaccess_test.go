@@ -0,0 +1,140 @@
+package itemaccess
+
+import (
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/device"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewReveal(t *testing.T) {
+	t.Parallel()
+
+	itemID := uuid.New()
+	userID := uuid.New()
+	ip := netip.MustParseAddr("203.0.113.7")
+
+	tests := []struct {
+		wantErrs      []error
+		name          string
+		params        NewRevealParams
+		wantUserAgent string
+	}{
+		{
+			name:          "valid reveal",
+			params:        NewRevealParams{ItemID: itemID, UserID: userID, IP: ip, UserAgent: " aegis-cli/1.0 "},
+			wantUserAgent: "aegis-cli/1.0",
+		},
+		{
+			name:          "long user agent truncated",
+			params:        NewRevealParams{ItemID: itemID, UserID: userID, UserAgent: strings.Repeat("a", 600)},
+			wantUserAgent: strings.Repeat("a", maxUserAgentLen),
+		},
+		{
+			name:     "missing item and user",
+			params:   NewRevealParams{UserAgent: "aegis-cli/1.0"},
+			wantErrs: []error{ErrNewRevealParamsValidation, ErrIncorrectItem, ErrIncorrectUserID},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r, err := NewReveal(tt.params)
+
+			if tt.wantErrs != nil {
+				for _, want := range tt.wantErrs {
+					require.ErrorIs(t, err, want)
+				}
+				assert.Nil(t, r)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotEqual(t, uuid.Nil, r.ID)
+			assert.Equal(t, itemID, r.ItemID)
+			assert.Equal(t, userID, r.UserID)
+			assert.Equal(t, tt.params.IP, r.IP)
+			assert.Equal(t, tt.wantUserAgent, r.UserAgent)
+			assert.Equal(t, device.FingerprintOf(tt.params.UserAgent), r.Fingerprint)
+			assert.WithinDuration(t, time.Now(), r.RevealedAt, time.Minute)
+		})
+	}
+}
+
+func TestNewHistory(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	reveal := func(userAgent string, ago time.Duration) *Reveal {
+		return &Reveal{UserAgent: userAgent, Fingerprint: device.FingerprintOf(userAgent), RevealedAt: now.Add(-ago)}
+	}
+	reveals := []*Reveal{
+		reveal("phone", time.Minute),
+		reveal("laptop", time.Hour),
+		reveal("phone", 2*time.Hour),
+	}
+
+	tests := []struct {
+		name        string
+		reveals     []*Reveal
+		wantDevices []DeviceAccess
+		limit       int
+		wantListed  int
+		wantCount   int
+	}{
+		{
+			name:      "reveals from two devices",
+			reveals:   reveals,
+			wantCount: 3,
+			wantDevices: []DeviceAccess{
+				{UserAgent: "phone", Fingerprint: device.FingerprintOf("phone"), LastRevealedAt: now.Add(-time.Minute), Count: 2},
+				{UserAgent: "laptop", Fingerprint: device.FingerprintOf("laptop"), LastRevealedAt: now.Add(-time.Hour), Count: 1},
+			},
+			wantListed: 3,
+		},
+		{
+			name:      "listing limited",
+			reveals:   reveals,
+			limit:     1,
+			wantCount: 3,
+			wantDevices: []DeviceAccess{
+				{UserAgent: "phone", Fingerprint: device.FingerprintOf("phone"), LastRevealedAt: now.Add(-time.Minute), Count: 2},
+				{UserAgent: "laptop", Fingerprint: device.FingerprintOf("laptop"), LastRevealedAt: now.Add(-time.Hour), Count: 1},
+			},
+			wantListed: 1,
+		},
+		{
+			name:        "never revealed",
+			wantDevices: []DeviceAccess{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			h := NewHistory(tt.reveals, tt.limit)
+
+			assert.Equal(t, tt.wantCount, h.Count)
+			require.NotNil(t, h.Reveals)
+			assert.Len(t, h.Reveals, tt.wantListed)
+			if tt.wantCount > 0 {
+				assert.Equal(t, tt.reveals[0].RevealedAt, h.LastRevealedAt)
+			} else {
+				assert.True(t, h.LastRevealedAt.IsZero())
+			}
+			// devices holds the device summaries dereferenced for comparison.
+			devices := make([]DeviceAccess, 0, len(h.Devices))
+			for _, d := range h.Devices {
+				devices = append(devices, *d)
+			}
+			assert.Equal(t, tt.wantDevices, devices)
+		})
+	}
+}
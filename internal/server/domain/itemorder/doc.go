@@ -0,0 +1,5 @@
+// Package itemorder provides manual item order domain entities and business rules for the AegisVaultKeeper server.
+//
+// This package implements the per-folder orders users arrange their vault items in, with pinned items
+// kept ahead of the others, which item listings follow when the manual sort is requested.
+package itemorder
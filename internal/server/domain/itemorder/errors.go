@@ -0,0 +1,21 @@
+package itemorder
+
+import "errors"
+
+// Item order domain error definitions.
+var (
+	// ErrNewItemOrderParamsValidation indicates that item order parameters failed validation.
+	ErrNewItemOrderParamsValidation = errors.New("new item order parameters validation failed")
+
+	// ErrIncorrectFolder indicates that a folder is not a sequence of short names separated by slashes.
+	ErrIncorrectFolder = errors.New("incorrect item order folder")
+
+	// ErrIncorrectItem indicates that the moved item is not specified.
+	ErrIncorrectItem = errors.New("moved item is not specified")
+
+	// ErrIncorrectPosition indicates that a position is negative.
+	ErrIncorrectPosition = errors.New("incorrect item position")
+
+	// ErrTooManyItems indicates that the order would hold more items than allowed.
+	ErrTooManyItems = errors.New("too many ordered items")
+)
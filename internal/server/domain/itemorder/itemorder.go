@@ -0,0 +1,134 @@
+package itemorder
+
+import (
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/itempath"
+	"github.com/google/uuid"
+)
+
+// MaxItems is the maximum number of items one order holds.
+const MaxItems = 1000
+
+// Entry represents one item placed in a manual order.
+type Entry struct {
+	// ItemID identifies the placed item of any kind.
+	ItemID uuid.UUID
+	// Pinned indicates whether the item is pinned ahead of the unpinned items.
+	Pinned bool
+}
+
+// ItemOrder represents the manual order a user arranged the items of one folder in.
+type ItemOrder struct {
+	// UpdatedAt contains the timestamp when the order was last changed.
+	UpdatedAt time.Time
+	// Folder contains the normalized folder the order applies to; empty for the root.
+	Folder string
+	// Entries contains the placed items in order, the pinned ones first.
+	Entries []Entry
+	// UserID identifies the user who owns the order.
+	UserID uuid.UUID
+}
+
+// NewItemOrder creates an empty order of a folder with the provided parameters after validation.
+// The folder is normalized with itempath.Normalize; an empty folder addresses the root.
+func NewItemOrder(params NewItemOrderParams) (*ItemOrder, error) {
+	if err := params.Validate(); err != nil {
+		return nil, errors.Join(ErrNewItemOrderParamsValidation, err)
+	}
+
+	return &ItemOrder{
+		UserID:    params.UserID,
+		Folder:    itempath.Normalize(params.Folder),
+		UpdatedAt: time.Now(),
+	}, nil
+}
+
+// Position represents the requested place of an item in an order.
+type Position struct {
+	// ItemID identifies the moved item (required).
+	ItemID uuid.UUID
+	// Position contains the zero-based index of the item among the pinned or among the unpinned items;
+	// indexes past the end place the item last.
+	Position int
+	// Pinned indicates whether the item is placed among the pinned items.
+	Pinned bool
+}
+
+// Move places the items at the positions, applying them one after another, and reports an error without
+// changing the order when a position is invalid or the order would hold more than MaxItems items.
+func (o *ItemOrder) Move(positions []Position) error {
+	// errs collects all validation errors encountered during position validation.
+	var errs []error
+	for _, p := range positions {
+		if p.ItemID == uuid.Nil {
+			errs = append(errs, ErrIncorrectItem)
+		}
+		if p.Position < 0 {
+			errs = append(errs, ErrIncorrectPosition)
+		}
+	}
+	if len(errs) != 0 {
+		return errors.Join(errs...)
+	}
+
+	entries := slices.Clone(o.Entries)
+	for _, p := range positions {
+		entries = slices.DeleteFunc(entries, func(e Entry) bool { return e.ItemID == p.ItemID })
+		pinned := 0
+		for pinned < len(entries) && entries[pinned].Pinned {
+			pinned++
+		}
+		at := min(p.Position, len(entries)-pinned) + pinned
+		if p.Pinned {
+			at = min(p.Position, pinned)
+		}
+		entries = slices.Insert(entries, at, Entry{ItemID: p.ItemID, Pinned: p.Pinned})
+	}
+	if len(entries) > MaxItems {
+		return ErrTooManyItems
+	}
+
+	o.Entries = entries
+	o.UpdatedAt = time.Now()
+	return nil
+}
+
+// Sort reorders the items following the order: the placed items come first in their manual order and the
+// other items keep their relative order after them. A nil order leaves the items unchanged.
+func Sort[T any](o *ItemOrder, items []T, itemID func(T) uuid.UUID) {
+	if o == nil || len(o.Entries) == 0 {
+		return
+	}
+
+	ranks := make(map[uuid.UUID]int, len(o.Entries))
+	for i, e := range o.Entries {
+		ranks[e.ItemID] = i
+	}
+	rank := func(item T) int {
+		if r, ok := ranks[itemID(item)]; ok {
+			return r
+		}
+		return len(o.Entries)
+	}
+	slices.SortStableFunc(items, func(a, b T) int { return rank(a) - rank(b) })
+}
+
+// NewItemOrderParams contains parameters for creating the order of a folder.
+type NewItemOrderParams struct {
+	// Folder contains the folder the order applies to, such as prod/db; empty addresses the root.
+	Folder string
+	// UserID identifies the user who owns the order.
+	UserID uuid.UUID
+}
+
+// Validate checks that the item order parameters are valid.
+func (p *NewItemOrderParams) Validate() error {
+	folder := itempath.Normalize(p.Folder)
+	if folder != "" && !itempath.Valid(folder) {
+		return ErrIncorrectFolder
+	}
+	return nil
+}
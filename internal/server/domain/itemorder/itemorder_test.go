@@ -0,0 +1,158 @@
+package itemorder
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewItemOrder(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		wantErr    error
+		name       string
+		folder     string
+		wantFolder string
+	}{
+		{name: "root", folder: ""},
+		{name: "normalized", folder: " /prod/db/ ", wantFolder: "prod/db"},
+		{name: "incorrect folder", folder: "prod/../db", wantErr: ErrIncorrectFolder},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			userID := uuid.New()
+			got, err := NewItemOrder(NewItemOrderParams{Folder: tt.folder, UserID: userID})
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, ErrNewItemOrderParamsValidation)
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantFolder, got.Folder)
+			assert.Equal(t, userID, got.UserID)
+			assert.Empty(t, got.Entries)
+		})
+	}
+}
+
+func TestItemOrder_Move(t *testing.T) {
+	t.Parallel()
+
+	a, b, c, d := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+
+	tests := []struct {
+		wantErr   error
+		name      string
+		entries   []Entry
+		positions []Position
+		want      []Entry
+	}{
+		{
+			name:      "place into empty order",
+			positions: []Position{{ItemID: a}, {ItemID: b, Position: 5}, {ItemID: c}},
+			want:      []Entry{{ItemID: c}, {ItemID: a}, {ItemID: b}},
+		},
+		{
+			name:      "pin keeps pinned items first",
+			entries:   []Entry{{ItemID: a}, {ItemID: b}, {ItemID: c}},
+			positions: []Position{{ItemID: c, Pinned: true}, {ItemID: b, Position: 3, Pinned: true}},
+			want:      []Entry{{ItemID: c, Pinned: true}, {ItemID: b, Pinned: true}, {ItemID: a}},
+		},
+		{
+			name:      "unpinned positions count after pinned items",
+			entries:   []Entry{{ItemID: a, Pinned: true}, {ItemID: b}, {ItemID: c}},
+			positions: []Position{{ItemID: d, Position: 1}},
+			want:      []Entry{{ItemID: a, Pinned: true}, {ItemID: b}, {ItemID: d}, {ItemID: c}},
+		},
+		{
+			name:      "unpin moves item among unpinned",
+			entries:   []Entry{{ItemID: a, Pinned: true}, {ItemID: b}},
+			positions: []Position{{ItemID: a, Position: 1}},
+			want:      []Entry{{ItemID: b}, {ItemID: a}},
+		},
+		{
+			name:      "missing item",
+			entries:   []Entry{{ItemID: a}},
+			positions: []Position{{Position: 1}},
+			wantErr:   ErrIncorrectItem,
+			want:      []Entry{{ItemID: a}},
+		},
+		{
+			name:      "negative position",
+			entries:   []Entry{{ItemID: a}},
+			positions: []Position{{ItemID: b, Position: -1}},
+			wantErr:   ErrIncorrectPosition,
+			want:      []Entry{{ItemID: a}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			o := &ItemOrder{Entries: tt.entries}
+			err := o.Move(tt.positions)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, o.Entries)
+		})
+	}
+}
+
+func TestItemOrder_MoveTooManyItems(t *testing.T) {
+	t.Parallel()
+
+	o := &ItemOrder{}
+	positions := make([]Position, MaxItems)
+	for i := range positions {
+		positions[i] = Position{ItemID: uuid.New(), Position: i}
+	}
+	require.NoError(t, o.Move(positions))
+
+	err := o.Move([]Position{{ItemID: uuid.New()}})
+
+	require.ErrorIs(t, err, ErrTooManyItems)
+	assert.Len(t, o.Entries, MaxItems)
+}
+
+func TestSort(t *testing.T) {
+	t.Parallel()
+
+	a, b, c, d := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+
+	tests := []struct {
+		order *ItemOrder
+		name  string
+		items []uuid.UUID
+		want  []uuid.UUID
+	}{
+		{name: "nil order", items: []uuid.UUID{a, b, c}, want: []uuid.UUID{a, b, c}},
+		{
+			name:  "placed items first",
+			order: &ItemOrder{Entries: []Entry{{ItemID: c, Pinned: true}, {ItemID: a}, {ItemID: uuid.New()}}},
+			items: []uuid.UUID{a, b, c, d},
+			want:  []uuid.UUID{c, a, b, d},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			Sort(tt.order, tt.items, func(id uuid.UUID) uuid.UUID { return id })
+
+			assert.Equal(t, tt.want, tt.items)
+		})
+	}
+}
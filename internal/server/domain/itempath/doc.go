@@ -0,0 +1,5 @@
+// Package itempath provides item path domain entities and business rules for the AegisVaultKeeper server.
+//
+// This package implements the hierarchical paths, such as prod/db/payments, users assign to their vault
+// items so scripts and operators can address secrets by name instead of identifier.
+package itempath
@@ -0,0 +1,15 @@
+package itempath
+
+import "errors"
+
+// Item path domain error definitions.
+var (
+	// ErrNewItemPathParamsValidation indicates that item path parameters failed validation.
+	ErrNewItemPathParamsValidation = errors.New("new item path parameters validation failed")
+
+	// ErrIncorrectItem indicates that the addressed item is not specified.
+	ErrIncorrectItem = errors.New("addressed item is not specified")
+
+	// ErrIncorrectPath indicates that a path is not a sequence of short names separated by slashes.
+	ErrIncorrectPath = errors.New("incorrect item path")
+)
@@ -0,0 +1,135 @@
+package itempath
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// maxPathLen is the maximum length of a path in bytes.
+	maxPathLen = 256
+	// maxDepth is the maximum number of segments of a path.
+	maxDepth = 16
+	// maxSegmentLen is the maximum length of a path segment in bytes.
+	maxSegmentLen = 64
+)
+
+// ItemPath represents the path a user assigned to one of their vault items.
+type ItemPath struct {
+	// UpdatedAt contains the timestamp when the path was last changed.
+	UpdatedAt time.Time
+	// Path contains the normalized path of the item; empty when the item has no path.
+	Path string
+	// ItemID identifies the addressed item of any kind.
+	ItemID uuid.UUID
+	// UserID identifies the user who owns the item.
+	UserID uuid.UUID
+}
+
+// NewItemPath creates the path of an item with the provided parameters after validation.
+// The path is normalized with Normalize; an empty path removes the stored one.
+func NewItemPath(params NewItemPathParams) (*ItemPath, error) {
+	if err := params.Validate(); err != nil {
+		return nil, errors.Join(ErrNewItemPathParamsValidation, err)
+	}
+
+	return &ItemPath{
+		ItemID:    params.ItemID,
+		UserID:    params.UserID,
+		Path:      Normalize(params.Path),
+		UpdatedAt: time.Now(),
+	}, nil
+}
+
+// Under reports whether the path equals the prefix or lies below it; every path lies below the empty prefix.
+func (p *ItemPath) Under(prefix string) bool {
+	prefix = Normalize(prefix)
+	return prefix == "" || p.Path == prefix || strings.HasPrefix(p.Path, prefix+"/")
+}
+
+// NewItemPathParams contains parameters for setting the path of an item.
+type NewItemPathParams struct {
+	// Path contains the new path, such as prod/db/payments; empty removes the path.
+	Path string
+	// ItemID identifies the addressed item (required).
+	ItemID uuid.UUID
+	// UserID identifies the user who owns the item.
+	UserID uuid.UUID
+}
+
+// Validate checks that the item path parameters are valid.
+func (p *NewItemPathParams) Validate() error {
+	validations := []func() error{
+		p.validateItem,
+		p.validatePath,
+	}
+
+	// errs collects all validation errors encountered during path validation.
+	var errs []error
+	for _, fn := range validations {
+		if err := fn(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) != 0 {
+		return errors.Join(errs...)
+	}
+	return nil
+}
+
+// validateItem ensures that the addressed item is specified.
+func (p *NewItemPathParams) validateItem() error {
+	if p.ItemID == uuid.Nil {
+		return ErrIncorrectItem
+	}
+	return nil
+}
+
+// validatePath ensures that the path is empty or a valid sequence of segments.
+func (p *NewItemPathParams) validatePath() error {
+	path := Normalize(p.Path)
+	if path != "" && !Valid(path) {
+		return ErrIncorrectPath
+	}
+	return nil
+}
+
+// Normalize trims surrounding spaces and slashes from the path.
+func Normalize(path string) string {
+	return strings.Trim(strings.TrimSpace(path), "/")
+}
+
+// Valid reports whether the normalized path consists of 1 to 16 segments separated by single slashes, each
+// made of ASCII letters, digits, '.', '-' and '_' and being neither "." nor "..".
+func Valid(path string) bool {
+	if path == "" || len(path) > maxPathLen {
+		return false
+	}
+	segments := strings.Split(path, "/")
+	if len(segments) > maxDepth {
+		return false
+	}
+	for _, s := range segments {
+		if !isSegment(s) {
+			return false
+		}
+	}
+	return true
+}
+
+// isSegment reports whether s is a valid path segment.
+func isSegment(s string) bool {
+	if s == "" || len(s) > maxSegmentLen || s == "." || s == ".." {
+		return false
+	}
+	for _, c := range s {
+		if (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') && (c < '0' || c > '9') && c != '.' && c != '-' && c != '_' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,100 @@
+package itempath
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewItemPath(t *testing.T) {
+	t.Parallel()
+
+	itemID := uuid.New()
+
+	tests := []struct {
+		wantErr  error
+		name     string
+		params   NewItemPathParams
+		wantPath string
+	}{
+		{
+			name:     "normalized",
+			params:   NewItemPathParams{ItemID: itemID, Path: " /prod/db/payments/ "},
+			wantPath: "prod/db/payments",
+		},
+		{name: "empty path clears path", params: NewItemPathParams{ItemID: itemID}},
+		{name: "missing item", params: NewItemPathParams{Path: "prod/db"}, wantErr: ErrIncorrectItem},
+		{
+			name:    "empty segment",
+			params:  NewItemPathParams{ItemID: itemID, Path: "prod//db"},
+			wantErr: ErrIncorrectPath,
+		},
+		{
+			name:    "parent segment",
+			params:  NewItemPathParams{ItemID: itemID, Path: "prod/../db"},
+			wantErr: ErrIncorrectPath,
+		},
+		{
+			name:    "segment with spaces",
+			params:  NewItemPathParams{ItemID: itemID, Path: "prod/my db"},
+			wantErr: ErrIncorrectPath,
+		},
+		{
+			name:    "too deep",
+			params:  NewItemPathParams{ItemID: itemID, Path: strings.Repeat("a/", maxDepth) + "a"},
+			wantErr: ErrIncorrectPath,
+		},
+		{
+			name:    "too long segment",
+			params:  NewItemPathParams{ItemID: itemID, Path: strings.Repeat("a", maxSegmentLen+1)},
+			wantErr: ErrIncorrectPath,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := NewItemPath(tt.params)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, ErrNewItemPathParamsValidation)
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantPath, got.Path)
+			assert.Equal(t, itemID, got.ItemID)
+		})
+	}
+}
+
+func TestItemPath_Under(t *testing.T) {
+	t.Parallel()
+
+	p := &ItemPath{Path: "prod/db/payments"}
+
+	tests := []struct {
+		name   string
+		prefix string
+		want   bool
+	}{
+		{name: "empty prefix", prefix: "", want: true},
+		{name: "parent", prefix: "prod/db", want: true},
+		{name: "parent with slash", prefix: "prod/db/", want: true},
+		{name: "same path", prefix: "prod/db/payments", want: true},
+		{name: "partial segment", prefix: "prod/d", want: false},
+		{name: "other branch", prefix: "staging", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, p.Under(tt.prefix))
+		})
+	}
+}
@@ -0,0 +1,6 @@
+// Package itempolicy provides vault item policy domain entities and business rules
+// for the AegisVaultKeeper server.
+//
+// This package implements the rules administrators set on the vault items of every user or of the members of a
+// directory group: the item types that may not be stored and the strength credential passwords must have.
+package itempolicy
@@ -0,0 +1,48 @@
+package itempolicy
+
+import "errors"
+
+// Item policy domain error definitions.
+var (
+	// ErrNewPolicyParamsValidation indicates that item policy creation parameters failed validation.
+	ErrNewPolicyParamsValidation = errors.New("new item policy parameters validation failed")
+
+	// ErrIncorrectName indicates that the item policy name is empty or too long.
+	ErrIncorrectName = errors.New("incorrect item policy name")
+
+	// ErrIncorrectKind indicates that the item policy forbids an unknown item type.
+	ErrIncorrectKind = errors.New("incorrect item type")
+
+	// ErrIncorrectMinPasswordLength indicates that the minimum password length is negative or too large.
+	ErrIncorrectMinPasswordLength = errors.New("incorrect minimum password length")
+
+	// ErrIncorrectPasswordClass indicates that the item policy requires an unknown character class.
+	ErrIncorrectPasswordClass = errors.New("incorrect password character class")
+
+	// ErrNoRules indicates that the item policy restricts nothing.
+	ErrNoRules = errors.New("item policy has no rules")
+
+	// ErrKindForbidden indicates that the item policies forbid storing items of the type.
+	ErrKindForbidden = errors.New("item type forbidden by item policy")
+
+	// ErrWeakPassword indicates that a credential password does not meet the item policies.
+	ErrWeakPassword = errors.New("credential password does not meet item policy")
+
+	// ErrPasswordTooShort indicates that a credential password is shorter than the item policies require.
+	ErrPasswordTooShort = errors.New("credential password is too short")
+
+	// ErrPasswordNoLower indicates that a credential password lacks a required lower-case letter.
+	ErrPasswordNoLower = errors.New("credential password has no lower-case letter")
+
+	// ErrPasswordNoUpper indicates that a credential password lacks a required upper-case letter.
+	ErrPasswordNoUpper = errors.New("credential password has no upper-case letter")
+
+	// ErrPasswordNoDigit indicates that a credential password lacks a required digit.
+	ErrPasswordNoDigit = errors.New("credential password has no digit")
+
+	// ErrPasswordNoSymbol indicates that a credential password lacks a required symbol.
+	ErrPasswordNoSymbol = errors.New("credential password has no symbol")
+
+	// ErrPasswordCommon indicates that a credential password is a commonly used one.
+	ErrPasswordCommon = errors.New("credential password is too common")
+)
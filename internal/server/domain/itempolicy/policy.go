@@ -0,0 +1,190 @@
+package itempolicy
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/auth"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/authz"
+	"github.com/google/uuid"
+)
+
+// Limits of the item policies.
+const (
+	// maxNameLength limits the length of the item policy name in characters.
+	maxNameLength = 100
+	// maxMinPasswordLength limits the minimum length of credential passwords in characters.
+	maxMinPasswordLength = 128
+)
+
+// Kinds lists the item types an item policy may forbid.
+var Kinds = []string{authz.KindBankCard, authz.KindCredential, authz.KindFile, authz.KindNote}
+
+// classViolations maps each character class to the violation of passwords lacking it.
+var classViolations = map[auth.PasswordClass]error{
+	auth.PasswordClassLower:  ErrPasswordNoLower,
+	auth.PasswordClassUpper:  ErrPasswordNoUpper,
+	auth.PasswordClassDigit:  ErrPasswordNoDigit,
+	auth.PasswordClassSymbol: ErrPasswordNoSymbol,
+}
+
+// Rules contains the restrictions an item policy puts on vault items; the zero value restricts nothing.
+type Rules struct {
+	// ForbiddenKinds lists the item types that may not be stored, sorted.
+	ForbiddenKinds []string
+	// RequiredClasses lists the character classes every credential password must contain.
+	RequiredClasses []auth.PasswordClass
+	// MinPasswordLength specifies the minimum length of credential passwords in characters; zero sets none.
+	MinPasswordLength int
+	// BanCommonPasswords determines whether commonly used credential passwords are rejected.
+	BanCommonPasswords bool
+}
+
+// IsZero reports whether the rules restrict nothing.
+func (r Rules) IsZero() bool {
+	return len(r.ForbiddenKinds) == 0 && len(r.RequiredClasses) == 0 && r.MinPasswordLength == 0 &&
+		!r.BanCommonPasswords
+}
+
+// Merge returns the rules enforcing the restrictions of both: every forbidden type and required class, the
+// longer minimum length and the ban of common passwords when either sets it. It resolves the rules of users
+// several policies apply to.
+func (r Rules) Merge(other Rules) Rules {
+	return Rules{
+		ForbiddenKinds:     union(r.ForbiddenKinds, other.ForbiddenKinds),
+		RequiredClasses:    union(r.RequiredClasses, other.RequiredClasses),
+		MinPasswordLength:  max(r.MinPasswordLength, other.MinPasswordLength),
+		BanCommonPasswords: r.BanCommonPasswords || other.BanCommonPasswords,
+	}
+}
+
+// CheckKind ensures that the rules allow storing items of the type.
+func (r Rules) CheckKind(kind string) error {
+	if slices.Contains(r.ForbiddenKinds, kind) {
+		return ErrKindForbidden
+	}
+	return nil
+}
+
+// CheckPassword validates a credential password against the rules. Every violation is reported, joined with
+// ErrWeakPassword.
+func (r Rules) CheckPassword(password string) error {
+	// violations collects the rules the password breaks.
+	var violations []error
+	if utf8.RuneCountInString(password) < r.MinPasswordLength {
+		violations = append(violations, ErrPasswordTooShort)
+	}
+	for _, class := range r.RequiredClasses {
+		if !class.In(password) {
+			violations = append(violations, classViolations[class])
+		}
+	}
+	if r.BanCommonPasswords && auth.IsCommonPassword(password) {
+		violations = append(violations, ErrPasswordCommon)
+	}
+
+	if len(violations) != 0 {
+		return errors.Join(append([]error{ErrWeakPassword}, violations...)...)
+	}
+	return nil
+}
+
+// Policy applies item rules to every user or to the members of a directory group.
+type Policy struct {
+	// CreatedAt contains the timestamp when the policy was created.
+	CreatedAt time.Time
+	// UpdatedAt contains the timestamp when the policy was last changed.
+	UpdatedAt time.Time
+	// Name describes the policy.
+	Name string
+	// Rules contains the restrictions the policy puts on vault items.
+	Rules
+	// ID uniquely identifies the policy.
+	ID uuid.UUID
+	// GroupID identifies the group whose members the policy applies to; uuid.Nil applies it to every user.
+	GroupID uuid.UUID
+}
+
+// NewPolicy creates a new item policy with the provided parameters after validation.
+func NewPolicy(params NewPolicyParams) (*Policy, error) {
+	if err := params.Validate(); err != nil {
+		return nil, errors.Join(ErrNewPolicyParamsValidation, err)
+	}
+
+	now := time.Now()
+	rules := params.Rules
+	rules.ForbiddenKinds = union(nil, rules.ForbiddenKinds)
+	rules.RequiredClasses = union(nil, rules.RequiredClasses)
+	return &Policy{
+		ID:        uuid.New(),
+		GroupID:   params.GroupID,
+		Name:      strings.TrimSpace(params.Name),
+		Rules:     rules,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil
+}
+
+// Update replaces the name, group and rules of the policy with the provided ones after validation.
+func (p *Policy) Update(params NewPolicyParams) error {
+	updated, err := NewPolicy(params)
+	if err != nil {
+		return err
+	}
+	p.Name, p.GroupID, p.Rules, p.UpdatedAt = updated.Name, updated.GroupID, updated.Rules, updated.UpdatedAt
+	return nil
+}
+
+// AppliesTo reports whether the policy applies to a member of the groups.
+func (p *Policy) AppliesTo(groupIDs []uuid.UUID) bool {
+	return p.GroupID == uuid.Nil || slices.Contains(groupIDs, p.GroupID)
+}
+
+// NewPolicyParams contains parameters for creating a new item policy.
+type NewPolicyParams struct {
+	// Name describes the policy (required).
+	Name string
+	// Rules contains the restrictions the policy puts on vault items; at least one is required.
+	Rules Rules
+	// GroupID identifies the group whose members the policy applies to; uuid.Nil applies it to every user.
+	GroupID uuid.UUID
+}
+
+// Validate checks that the item policy creation parameters are valid.
+func (p *NewPolicyParams) Validate() error {
+	var errs []error
+	name := strings.TrimSpace(p.Name)
+	if name == "" || utf8.RuneCountInString(name) > maxNameLength {
+		errs = append(errs, ErrIncorrectName)
+	}
+	for _, kind := range p.Rules.ForbiddenKinds {
+		if !slices.Contains(Kinds, kind) {
+			errs = append(errs, ErrIncorrectKind)
+			break
+		}
+	}
+	for _, class := range p.Rules.RequiredClasses {
+		if _, ok := classViolations[class]; !ok {
+			errs = append(errs, ErrIncorrectPasswordClass)
+			break
+		}
+	}
+	if p.Rules.MinPasswordLength < 0 || p.Rules.MinPasswordLength > maxMinPasswordLength {
+		errs = append(errs, ErrIncorrectMinPasswordLength)
+	}
+	if p.Rules.IsZero() {
+		errs = append(errs, ErrNoRules)
+	}
+	return errors.Join(errs...)
+}
+
+// union returns the sorted distinct values of both slices, or nil when both are empty.
+func union[T ~string](a, b []T) []T {
+	if len(a)+len(b) == 0 {
+		return nil
+	}
+	return slices.Compact(slices.Sorted(slices.Values(slices.Concat(a, b))))
+}
@@ -0,0 +1,233 @@
+package itempolicy
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/auth"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/authz"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPolicy(t *testing.T) {
+	t.Parallel()
+
+	groupID := uuid.New()
+
+	tests := []struct {
+		wantErr   error
+		name      string
+		params    NewPolicyParams
+		wantRules Rules
+	}{
+		{
+			name: "valid policy",
+			params: NewPolicyParams{
+				Name:    " Finance ",
+				GroupID: groupID,
+				Rules: Rules{
+					ForbiddenKinds:  []string{authz.KindNote, authz.KindBankCard, authz.KindNote},
+					RequiredClasses: []auth.PasswordClass{auth.PasswordClassUpper, auth.PasswordClassDigit},
+				},
+			},
+			wantRules: Rules{
+				ForbiddenKinds:  []string{authz.KindBankCard, authz.KindNote},
+				RequiredClasses: []auth.PasswordClass{auth.PasswordClassDigit, auth.PasswordClassUpper},
+			},
+		},
+		{
+			name:      "password rules only",
+			params:    NewPolicyParams{Name: "Everyone", Rules: Rules{MinPasswordLength: 16, BanCommonPasswords: true}},
+			wantRules: Rules{MinPasswordLength: 16, BanCommonPasswords: true},
+		},
+		{
+			name:    "missing name",
+			params:  NewPolicyParams{Name: " ", Rules: Rules{MinPasswordLength: 12}},
+			wantErr: ErrIncorrectName,
+		},
+		{
+			name:    "long name",
+			params:  NewPolicyParams{Name: strings.Repeat("a", maxNameLength+1), Rules: Rules{MinPasswordLength: 12}},
+			wantErr: ErrIncorrectName,
+		},
+		{
+			name:    "unknown kind",
+			params:  NewPolicyParams{Name: "Kinds", Rules: Rules{ForbiddenKinds: []string{"acme"}}},
+			wantErr: ErrIncorrectKind,
+		},
+		{
+			name:    "unknown class",
+			params:  NewPolicyParams{Name: "Classes", Rules: Rules{RequiredClasses: []auth.PasswordClass{"emoji"}}},
+			wantErr: ErrIncorrectPasswordClass,
+		},
+		{
+			name:    "negative length",
+			params:  NewPolicyParams{Name: "Length", Rules: Rules{MinPasswordLength: -1, BanCommonPasswords: true}},
+			wantErr: ErrIncorrectMinPasswordLength,
+		},
+		{
+			name:    "too long length",
+			params:  NewPolicyParams{Name: "Length", Rules: Rules{MinPasswordLength: maxMinPasswordLength + 1}},
+			wantErr: ErrIncorrectMinPasswordLength,
+		},
+		{
+			name:    "no rules",
+			params:  NewPolicyParams{Name: "Empty"},
+			wantErr: ErrNoRules,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			p, err := NewPolicy(tt.params)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, ErrNewPolicyParamsValidation)
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, p)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotEqual(t, uuid.Nil, p.ID)
+			assert.Equal(t, strings.TrimSpace(tt.params.Name), p.Name)
+			assert.Equal(t, tt.params.GroupID, p.GroupID)
+			assert.Equal(t, tt.wantRules, p.Rules)
+			assert.Equal(t, p.CreatedAt, p.UpdatedAt)
+		})
+	}
+}
+
+func TestPolicy_Update(t *testing.T) {
+	t.Parallel()
+
+	p, err := NewPolicy(NewPolicyParams{Name: "Everyone", Rules: Rules{MinPasswordLength: 12}})
+	require.NoError(t, err)
+	id, created := p.ID, p.CreatedAt
+
+	err = p.Update(NewPolicyParams{Name: "Everyone"})
+	require.ErrorIs(t, err, ErrNoRules)
+	assert.Equal(t, 12, p.MinPasswordLength)
+
+	groupID := uuid.New()
+	err = p.Update(NewPolicyParams{Name: "Finance", GroupID: groupID, Rules: Rules{BanCommonPasswords: true}})
+	require.NoError(t, err)
+	assert.Equal(t, id, p.ID)
+	assert.Equal(t, created, p.CreatedAt)
+	assert.Equal(t, "Finance", p.Name)
+	assert.Equal(t, groupID, p.GroupID)
+	assert.Equal(t, Rules{BanCommonPasswords: true}, p.Rules)
+}
+
+func TestPolicy_AppliesTo(t *testing.T) {
+	t.Parallel()
+
+	groupID := uuid.New()
+
+	assert.True(t, (&Policy{}).AppliesTo(nil))
+	assert.True(t, (&Policy{GroupID: groupID}).AppliesTo([]uuid.UUID{uuid.New(), groupID}))
+	assert.False(t, (&Policy{GroupID: groupID}).AppliesTo([]uuid.UUID{uuid.New()}))
+}
+
+func TestRules_Merge(t *testing.T) {
+	t.Parallel()
+
+	a := Rules{
+		ForbiddenKinds:    []string{authz.KindNote},
+		RequiredClasses:   []auth.PasswordClass{auth.PasswordClassUpper},
+		MinPasswordLength: 12,
+	}
+	b := Rules{
+		ForbiddenKinds:     []string{authz.KindBankCard, authz.KindNote},
+		RequiredClasses:    []auth.PasswordClass{auth.PasswordClassDigit},
+		MinPasswordLength:  8,
+		BanCommonPasswords: true,
+	}
+	want := Rules{
+		ForbiddenKinds:     []string{authz.KindBankCard, authz.KindNote},
+		RequiredClasses:    []auth.PasswordClass{auth.PasswordClassDigit, auth.PasswordClassUpper},
+		MinPasswordLength:  12,
+		BanCommonPasswords: true,
+	}
+
+	assert.Equal(t, want, a.Merge(b))
+	assert.Equal(t, want, b.Merge(a))
+	assert.Equal(t, Rules{}, Rules{}.Merge(Rules{}))
+	assert.True(t, Rules{}.IsZero())
+}
+
+func TestRules_CheckKind(t *testing.T) {
+	t.Parallel()
+
+	rules := Rules{ForbiddenKinds: []string{authz.KindBankCard}}
+
+	require.ErrorIs(t, rules.CheckKind(authz.KindBankCard), ErrKindForbidden)
+	require.NoError(t, rules.CheckKind(authz.KindCredential))
+	require.NoError(t, Rules{}.CheckKind(authz.KindBankCard))
+}
+
+func TestRules_CheckPassword(t *testing.T) {
+	t.Parallel()
+
+	strict := Rules{
+		RequiredClasses: []auth.PasswordClass{
+			auth.PasswordClassDigit, auth.PasswordClassLower, auth.PasswordClassSymbol, auth.PasswordClassUpper,
+		},
+		MinPasswordLength:  12,
+		BanCommonPasswords: true,
+	}
+
+	tests := []struct {
+		wantErrs []error
+		name     string
+		password string
+		rules    Rules
+	}{
+		{
+			name:     "no rules accept anything",
+			password: "1",
+		},
+		{
+			name:     "strong password",
+			password: "Correct-Horse-42",
+			rules:    strict,
+		},
+		{
+			name:     "every violation reported",
+			password: "short",
+			rules:    strict,
+			wantErrs: []error{ErrPasswordTooShort, ErrPasswordNoDigit, ErrPasswordNoSymbol, ErrPasswordNoUpper},
+		},
+		{
+			name:     "length counts characters",
+			password: "пароль",
+			rules:    Rules{MinPasswordLength: 7},
+			wantErrs: []error{ErrPasswordTooShort},
+		},
+		{
+			name:     "common password",
+			password: "Password123",
+			rules:    Rules{BanCommonPasswords: true},
+			wantErrs: []error{ErrPasswordCommon},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.rules.CheckPassword(tt.password)
+			if len(tt.wantErrs) == 0 {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, ErrWeakPassword)
+			for _, want := range tt.wantErrs {
+				assert.ErrorIs(t, err, want)
+			}
+			assert.Len(t, err.(interface{ Unwrap() []error }).Unwrap(), len(tt.wantErrs)+1)
+		})
+	}
+}
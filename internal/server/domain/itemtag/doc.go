@@ -0,0 +1,6 @@
+// Package itemtag provides item tag domain entities and business rules for the AegisVaultKeeper server.
+//
+// This package implements the labels users attach to their vault items, defining which tags are
+// valid, the restricted tag that limits the locations an item can be revealed from and the approval
+// tag that makes reveals of an item wait for the consent of an approver.
+package itemtag
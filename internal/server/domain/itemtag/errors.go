@@ -0,0 +1,18 @@
+package itemtag
+
+import "errors"
+
+// Item tag domain error definitions.
+var (
+	// ErrNewItemTagsParamsValidation indicates that item tag parameters failed validation.
+	ErrNewItemTagsParamsValidation = errors.New("new item tags parameters validation failed")
+
+	// ErrIncorrectItem indicates that the tagged item is not specified.
+	ErrIncorrectItem = errors.New("tagged item is not specified")
+
+	// ErrIncorrectTag indicates that a tag is not a short lowercase label.
+	ErrIncorrectTag = errors.New("incorrect item tag")
+
+	// ErrTooManyTags indicates that more tags than allowed are attached to an item.
+	ErrTooManyTags = errors.New("too many item tags")
+)
@@ -0,0 +1,144 @@
+package itemtag
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Tags with a meaning to the server.
+const (
+	// Restricted tags items that may only be revealed from the locations configured by the operator.
+	Restricted = "restricted"
+	// RequiresApproval tags items that may only be revealed after a designated approver granted a request.
+	RequiresApproval = "approval"
+	// Archived tags items kept indefinitely but hidden from item lists unless archived items are requested.
+	Archived = "archived"
+)
+
+const (
+	// MaxTags is the maximum number of tags attached to a single item.
+	MaxTags = 16
+	// maxTagLen is the maximum length of a tag in bytes.
+	maxTagLen = 32
+)
+
+// ItemTags represents the tags a user attached to one of their vault items.
+type ItemTags struct {
+	// UpdatedAt contains the timestamp when the tags were last changed.
+	UpdatedAt time.Time
+	// Tags contains the sorted, deduplicated tags of the item.
+	Tags []string
+	// ItemID identifies the tagged item of any kind.
+	ItemID uuid.UUID
+	// UserID identifies the user who owns the item.
+	UserID uuid.UUID
+}
+
+// NewItemTags creates the tag set of an item with the provided parameters after validation.
+// Tags are trimmed, lowercased, deduplicated and sorted; an empty set removes all tags.
+func NewItemTags(params NewItemTagsParams) (*ItemTags, error) {
+	if err := params.Validate(); err != nil {
+		return nil, errors.Join(ErrNewItemTagsParamsValidation, err)
+	}
+
+	return &ItemTags{
+		ItemID:    params.ItemID,
+		UserID:    params.UserID,
+		Tags:      normalize(params.Tags),
+		UpdatedAt: time.Now(),
+	}, nil
+}
+
+// Has reports whether the item carries the tag.
+func (t *ItemTags) Has(tag string) bool {
+	return slices.Contains(t.Tags, tag)
+}
+
+// ItemIDs returns the set of the items the tag sets belong to.
+func ItemIDs(tags []*ItemTags) map[uuid.UUID]bool {
+	ids := make(map[uuid.UUID]bool, len(tags))
+	for _, t := range tags {
+		ids[t.ItemID] = true
+	}
+	return ids
+}
+
+// NewItemTagsParams contains parameters for setting the tags of an item.
+type NewItemTagsParams struct {
+	// Tags contains the labels to attach; letters, digits, '-' and '_' up to 32 characters each.
+	Tags []string
+	// ItemID identifies the tagged item (required).
+	ItemID uuid.UUID
+	// UserID identifies the user who owns the item.
+	UserID uuid.UUID
+}
+
+// Validate checks that the item tag parameters are valid.
+func (p *NewItemTagsParams) Validate() error {
+	validations := []func() error{
+		p.validateItem,
+		p.validateTags,
+	}
+
+	// errs collects all validation errors encountered during tag validation.
+	var errs []error
+	for _, fn := range validations {
+		if err := fn(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) != 0 {
+		return errors.Join(errs...)
+	}
+	return nil
+}
+
+// validateItem ensures that the tagged item is specified.
+func (p *NewItemTagsParams) validateItem() error {
+	if p.ItemID == uuid.Nil {
+		return ErrIncorrectItem
+	}
+	return nil
+}
+
+// validateTags ensures that every tag is a valid label and that the item does not carry too many.
+func (p *NewItemTagsParams) validateTags() error {
+	for _, tag := range p.Tags {
+		if !isTag(strings.ToLower(strings.TrimSpace(tag))) {
+			return ErrIncorrectTag
+		}
+	}
+	if len(normalize(p.Tags)) > MaxTags {
+		return ErrTooManyTags
+	}
+	return nil
+}
+
+// normalize trims and lowercases the tags and returns them sorted without duplicates.
+func normalize(tags []string) []string {
+	out := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		out = append(out, strings.ToLower(strings.TrimSpace(tag)))
+	}
+	slices.Sort(out)
+	return slices.Compact(out)
+}
+
+// isTag reports whether s is a non-empty label of ASCII letters, digits, '-' and '_' starting
+// with a letter or digit.
+func isTag(s string) bool {
+	if s == "" || len(s) > maxTagLen || s[0] == '-' || s[0] == '_' {
+		return false
+	}
+	for _, c := range s {
+		if (c < 'a' || c > 'z') && (c < '0' || c > '9') && c != '-' && c != '_' {
+			return false
+		}
+	}
+	return true
+}
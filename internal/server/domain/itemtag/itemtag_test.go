@@ -0,0 +1,111 @@
+package itemtag
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewItemTags(t *testing.T) {
+	t.Parallel()
+
+	itemID := uuid.New()
+	userID := uuid.New()
+
+	tooMany := make([]string, 0, MaxTags+1)
+	for i := range MaxTags + 1 {
+		tooMany = append(tooMany, fmt.Sprintf("tag-%d", i))
+	}
+
+	tests := []struct {
+		wantErr  error
+		name     string
+		params   NewItemTagsParams
+		wantTags []string
+	}{
+		{
+			name:     "normalized",
+			params:   NewItemTagsParams{ItemID: itemID, Tags: []string{" Work ", "restricted", "work", "tax_2024"}},
+			wantTags: []string{"restricted", "tax_2024", "work"},
+		},
+		{
+			name:     "empty set clears tags",
+			params:   NewItemTagsParams{ItemID: itemID},
+			wantTags: []string{},
+		},
+		{
+			name:    "missing item",
+			params:  NewItemTagsParams{Tags: []string{"work"}},
+			wantErr: ErrIncorrectItem,
+		},
+		{
+			name:    "blank tag",
+			params:  NewItemTagsParams{ItemID: itemID, Tags: []string{" "}},
+			wantErr: ErrIncorrectTag,
+		},
+		{
+			name:    "tag with spaces",
+			params:  NewItemTagsParams{ItemID: itemID, Tags: []string{"top secret"}},
+			wantErr: ErrIncorrectTag,
+		},
+		{
+			name:    "tag starting with dash",
+			params:  NewItemTagsParams{ItemID: itemID, Tags: []string{"-work"}},
+			wantErr: ErrIncorrectTag,
+		},
+		{
+			name:    "too long tag",
+			params:  NewItemTagsParams{ItemID: itemID, Tags: []string{"abcdefghijklmnopqrstuvwxyz0123456"}},
+			wantErr: ErrIncorrectTag,
+		},
+		{
+			name:    "too many tags",
+			params:  NewItemTagsParams{ItemID: itemID, Tags: tooMany},
+			wantErr: ErrTooManyTags,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tt.params.UserID = userID
+			tags, err := NewItemTags(tt.params)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, ErrNewItemTagsParamsValidation)
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, tags)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, itemID, tags.ItemID)
+			assert.Equal(t, userID, tags.UserID)
+			assert.Equal(t, tt.wantTags, tags.Tags)
+			assert.False(t, tags.UpdatedAt.IsZero())
+		})
+	}
+}
+
+func TestItemTags_Has(t *testing.T) {
+	t.Parallel()
+
+	tags := &ItemTags{Tags: []string{Restricted, "work"}}
+
+	assert.True(t, tags.Has(Restricted))
+	assert.False(t, tags.Has("personal"))
+}
+
+func TestItemIDs(t *testing.T) {
+	t.Parallel()
+
+	first, second := uuid.New(), uuid.New()
+
+	ids := ItemIDs([]*ItemTags{{ItemID: first}, {ItemID: second}})
+
+	assert.Equal(t, map[uuid.UUID]bool{first: true, second: true}, ids)
+	assert.Empty(t, ItemIDs(nil))
+}
@@ -0,0 +1,6 @@
+// Package legalhold provides legal hold domain entities and business rules
+// for the AegisVaultKeeper server.
+//
+// This package implements the holds an administrator places on the data of a user or on a single item of a user
+// to keep it from being permanently deleted until the hold is released.
+package legalhold
@@ -0,0 +1,15 @@
+package legalhold
+
+import "errors"
+
+// Legal hold domain error definitions.
+var (
+	// ErrNewHoldParamsValidation indicates that legal hold creation parameters failed validation.
+	ErrNewHoldParamsValidation = errors.New("new legal hold parameters validation failed")
+
+	// ErrIncorrectUserID indicates that the legal hold does not name a user.
+	ErrIncorrectUserID = errors.New("incorrect legal hold user ID")
+
+	// ErrIncorrectReason indicates that the legal hold reason is empty or too long.
+	ErrIncorrectReason = errors.New("incorrect legal hold reason")
+)
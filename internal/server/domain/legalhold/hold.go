@@ -0,0 +1,70 @@
+package legalhold
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// maxReasonLength limits the length of the legal hold reason in characters.
+const maxReasonLength = 1000
+
+// Hold keeps the data of a user, or a single item of the user, from being permanently deleted until released.
+type Hold struct {
+	// PlacedAt contains the timestamp when the hold was placed.
+	PlacedAt time.Time
+	// Reason describes the matter the data is preserved for.
+	Reason string
+	// ID uniquely identifies the hold.
+	ID uuid.UUID
+	// UserID identifies the user whose data is held.
+	UserID uuid.UUID
+	// ItemID identifies the held item; uuid.Nil holds every item of the user.
+	ItemID uuid.UUID
+}
+
+// NewHold creates a new legal hold with the provided parameters after validation.
+func NewHold(params NewHoldParams) (*Hold, error) {
+	if err := params.Validate(); err != nil {
+		return nil, errors.Join(ErrNewHoldParamsValidation, err)
+	}
+
+	return &Hold{
+		ID:       uuid.New(),
+		UserID:   params.UserID,
+		ItemID:   params.ItemID,
+		Reason:   strings.TrimSpace(params.Reason),
+		PlacedAt: time.Now(),
+	}, nil
+}
+
+// Covers reports whether the hold applies to the item of the user.
+func (h *Hold) Covers(userID, itemID uuid.UUID) bool {
+	return h.UserID == userID && (h.ItemID == uuid.Nil || h.ItemID == itemID)
+}
+
+// NewHoldParams contains parameters for creating a new legal hold.
+type NewHoldParams struct {
+	// Reason describes the matter the data is preserved for (required).
+	Reason string
+	// UserID identifies the user whose data is held (required).
+	UserID uuid.UUID
+	// ItemID identifies the held item; uuid.Nil holds every item of the user.
+	ItemID uuid.UUID
+}
+
+// Validate checks that the legal hold creation parameters are valid.
+func (p *NewHoldParams) Validate() error {
+	var errs []error
+	if p.UserID == uuid.Nil {
+		errs = append(errs, ErrIncorrectUserID)
+	}
+	reason := strings.TrimSpace(p.Reason)
+	if reason == "" || utf8.RuneCountInString(reason) > maxReasonLength {
+		errs = append(errs, ErrIncorrectReason)
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,85 @@
+package legalhold
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewHold(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	itemID := uuid.New()
+
+	tests := []struct {
+		wantErr    error
+		name       string
+		wantReason string
+		params     NewHoldParams
+	}{
+		{
+			name:       "hold on a user",
+			params:     NewHoldParams{UserID: userID, Reason: " Case 42 "},
+			wantReason: "Case 42",
+		},
+		{
+			name:       "hold on an item",
+			params:     NewHoldParams{UserID: userID, ItemID: itemID, Reason: "Case 42"},
+			wantReason: "Case 42",
+		},
+		{
+			name:    "missing user",
+			params:  NewHoldParams{Reason: "Case 42"},
+			wantErr: ErrIncorrectUserID,
+		},
+		{
+			name:    "blank reason",
+			params:  NewHoldParams{UserID: userID, Reason: "  "},
+			wantErr: ErrIncorrectReason,
+		},
+		{
+			name:    "reason too long",
+			params:  NewHoldParams{UserID: userID, Reason: strings.Repeat("я", maxReasonLength+1)},
+			wantErr: ErrIncorrectReason,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			h, err := NewHold(tt.params)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, ErrNewHoldParamsValidation)
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, h)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotEqual(t, uuid.Nil, h.ID)
+			assert.Equal(t, tt.params.UserID, h.UserID)
+			assert.Equal(t, tt.params.ItemID, h.ItemID)
+			assert.Equal(t, tt.wantReason, h.Reason)
+			assert.False(t, h.PlacedAt.IsZero())
+		})
+	}
+}
+
+func TestHold_Covers(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	itemID := uuid.New()
+	user := &Hold{UserID: userID}
+	item := &Hold{UserID: userID, ItemID: itemID}
+
+	assert.True(t, user.Covers(userID, uuid.New()))
+	assert.False(t, user.Covers(uuid.New(), itemID))
+	assert.True(t, item.Covers(userID, itemID))
+	assert.False(t, item.Covers(userID, uuid.New()))
+}
@@ -0,0 +1,5 @@
+// Package machine provides machine identity domain entities and business rules for the AegisVaultKeeper server.
+//
+// This package implements core domain logic for the identities CI/CD pipelines and other automation
+// authenticate with to lease specific vault items of their owner, without a user session.
+package machine
@@ -0,0 +1,21 @@
+package machine
+
+import "errors"
+
+// Machine identity domain error definitions.
+var (
+	// ErrNewMachineParamsValidation indicates that machine identity creation parameters failed validation.
+	ErrNewMachineParamsValidation = errors.New("new machine identity parameters validation failed")
+
+	// ErrIncorrectName indicates that the machine identity name is empty or too long.
+	ErrIncorrectName = errors.New("incorrect machine identity name")
+
+	// ErrIncorrectToken indicates that the machine token is too short.
+	ErrIncorrectToken = errors.New("incorrect machine token")
+
+	// ErrIncorrectScope indicates that the scope is empty, too large or names an item of an unsupported kind.
+	ErrIncorrectScope = errors.New("incorrect machine identity scope")
+
+	// ErrItemOutOfScope indicates that a requested item is not in the scope of the machine identity.
+	ErrItemOutOfScope = errors.New("item is out of machine identity scope")
+)
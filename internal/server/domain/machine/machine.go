@@ -0,0 +1,167 @@
+package machine
+
+import (
+	"crypto/sha256"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// Machine identity limits.
+const (
+	// maxNameLen limits the length of machine identity names in characters.
+	maxNameLen = 64
+	// MaxScopeSize limits how many items a machine identity may lease.
+	MaxScopeSize = 100
+	// MinTokenLen defines the minimum length of machine tokens.
+	MinTokenLen = 20
+)
+
+// Kind names the kind of a vault item a machine identity may lease.
+type Kind string
+
+// Supported leasable item kinds.
+const (
+	// KindCredential leases the login and password of a credential.
+	KindCredential Kind = "credential"
+	// KindNote leases the text of a note.
+	KindNote Kind = "note"
+	// KindACME leases the account key and DNS credentials of an ACME account.
+	KindACME Kind = "acme"
+)
+
+// Item addresses a vault item in the scope of a machine identity.
+type Item struct {
+	// Kind names the kind of the item.
+	Kind Kind
+	// ID identifies the item.
+	ID uuid.UUID
+}
+
+// Machine is an identity automation of the user authenticates with to lease the items in its scope.
+type Machine struct {
+	// CreatedAt contains the timestamp when the identity was created.
+	CreatedAt time.Time
+	// LastUsedAt contains the timestamp of the last lease; zero if never used.
+	LastUsedAt time.Time
+	// Name labels the automation holding the token.
+	Name string
+	// TokenHash contains the SHA-256 digest of the machine token.
+	TokenHash []byte
+	// Scope lists the items the identity may lease.
+	Scope []Item
+	// ID uniquely identifies this identity.
+	ID uuid.UUID
+	// UserID identifies the user who owns this identity and the items in its scope.
+	UserID uuid.UUID
+}
+
+// NewMachine creates a new machine identity with the provided parameters after validation.
+// Duplicate scope items are removed.
+func NewMachine(params NewMachineParams) (*Machine, error) {
+	if err := params.Validate(); err != nil {
+		return nil, errors.Join(ErrNewMachineParamsValidation, err)
+	}
+
+	scope := make([]Item, 0, len(params.Scope))
+	for _, item := range params.Scope {
+		if !slices.Contains(scope, item) {
+			scope = append(scope, item)
+		}
+	}
+
+	return &Machine{
+		ID:        uuid.New(),
+		UserID:    params.UserID,
+		Name:      strings.TrimSpace(params.Name),
+		TokenHash: HashToken(params.Token),
+		Scope:     scope,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+// HashToken returns the SHA-256 digest machine tokens are stored and looked up by.
+func HashToken(token string) []byte {
+	sum := sha256.Sum256([]byte(token))
+	return sum[:]
+}
+
+// Resolve returns the scope items with the requested identifiers, or the whole scope when none are requested.
+// Returns ErrItemOutOfScope when an identifier is not in the scope.
+func (m *Machine) Resolve(ids []uuid.UUID) ([]Item, error) {
+	if len(ids) == 0 {
+		return slices.Clone(m.Scope), nil
+	}
+
+	items := make([]Item, 0, len(ids))
+	for _, id := range ids {
+		i := slices.IndexFunc(m.Scope, func(item Item) bool { return item.ID == id })
+		if i < 0 {
+			return nil, ErrItemOutOfScope
+		}
+		if !slices.Contains(items, m.Scope[i]) {
+			items = append(items, m.Scope[i])
+		}
+	}
+	return items, nil
+}
+
+// Used records a lease by the identity at the specified moment.
+func (m *Machine) Used(at time.Time) {
+	m.LastUsedAt = at
+}
+
+// NewMachineParams contains parameters for creating a new machine identity.
+type NewMachineParams struct {
+	// Name labels the automation holding the token (required).
+	Name string
+	// Token contains the machine token of at least MinTokenLen characters (required).
+	Token string
+	// Scope lists the items the identity may lease (required, at most MaxScopeSize).
+	Scope []Item
+	// UserID identifies the user who owns the identity.
+	UserID uuid.UUID
+}
+
+// Validate checks that the machine identity creation parameters are valid.
+func (p *NewMachineParams) Validate() error {
+	var errs []error
+	name := strings.TrimSpace(p.Name)
+	if name == "" || utf8.RuneCountInString(name) > maxNameLen {
+		errs = append(errs, ErrIncorrectName)
+	}
+	if len(p.Token) < MinTokenLen {
+		errs = append(errs, ErrIncorrectToken)
+	}
+	if !validScope(p.Scope) {
+		errs = append(errs, ErrIncorrectScope)
+	}
+	return errors.Join(errs...)
+}
+
+// validScope reports whether the scope is non-empty, within MaxScopeSize and names items of supported kinds.
+func validScope(scope []Item) bool {
+	if len(scope) == 0 || len(scope) > MaxScopeSize {
+		return false
+	}
+	for _, item := range scope {
+		if item.ID == uuid.Nil || !item.Kind.leasable() {
+			return false
+		}
+	}
+	return true
+}
+
+// leasable reports whether items of the kind may be leased.
+func (k Kind) leasable() bool {
+	switch k {
+	case KindCredential, KindNote, KindACME:
+		return true
+	default:
+		return false
+	}
+}
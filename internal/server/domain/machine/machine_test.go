@@ -0,0 +1,153 @@
+package machine
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMachine(t *testing.T) {
+	t.Parallel()
+
+	userID, itemID := uuid.New(), uuid.New()
+	token := strings.Repeat("t", MinTokenLen)
+	item := Item{Kind: KindCredential, ID: itemID}
+
+	tooLarge := make([]Item, MaxScopeSize+1)
+	for i := range tooLarge {
+		tooLarge[i] = Item{Kind: KindNote, ID: uuid.New()}
+	}
+
+	tests := []struct {
+		wantErr   error
+		name      string
+		wantName  string
+		params    NewMachineParams
+		wantScope []Item
+	}{
+		{
+			name:      "valid identity",
+			params:    NewMachineParams{Name: " ci-deploy ", Token: token, Scope: []Item{item, item}, UserID: userID},
+			wantName:  "ci-deploy",
+			wantScope: []Item{item},
+		},
+		{
+			name: "ACME account",
+			params: NewMachineParams{
+				Name: "cert-renewal", Token: token, Scope: []Item{{Kind: KindACME, ID: itemID}}, UserID: userID,
+			},
+			wantName:  "cert-renewal",
+			wantScope: []Item{{Kind: KindACME, ID: itemID}},
+		},
+		{
+			name:    "blank name",
+			params:  NewMachineParams{Name: "  ", Token: token, Scope: []Item{item}, UserID: userID},
+			wantErr: ErrIncorrectName,
+		},
+		{
+			name:    "too long name",
+			params:  NewMachineParams{Name: strings.Repeat("a", 65), Token: token, Scope: []Item{item}, UserID: userID},
+			wantErr: ErrIncorrectName,
+		},
+		{
+			name:    "short token",
+			params:  NewMachineParams{Name: "ci", Token: "short", Scope: []Item{item}, UserID: userID},
+			wantErr: ErrIncorrectToken,
+		},
+		{
+			name:    "empty scope",
+			params:  NewMachineParams{Name: "ci", Token: token, UserID: userID},
+			wantErr: ErrIncorrectScope,
+		},
+		{
+			name:    "too large scope",
+			params:  NewMachineParams{Name: "ci", Token: token, Scope: tooLarge, UserID: userID},
+			wantErr: ErrIncorrectScope,
+		},
+		{
+			name: "unsupported kind",
+			params: NewMachineParams{
+				Name: "ci", Token: token, Scope: []Item{{Kind: "file", ID: itemID}}, UserID: userID,
+			},
+			wantErr: ErrIncorrectScope,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := NewMachine(tt.params)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, ErrNewMachineParamsValidation)
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, got)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotEqual(t, uuid.Nil, got.ID)
+			assert.Equal(t, userID, got.UserID)
+			assert.Equal(t, tt.wantName, got.Name)
+			assert.Equal(t, HashToken(token), got.TokenHash)
+			assert.Equal(t, tt.wantScope, got.Scope)
+		})
+	}
+}
+
+func TestMachine_Resolve(t *testing.T) {
+	t.Parallel()
+
+	credential := Item{Kind: KindCredential, ID: uuid.New()}
+	note := Item{Kind: KindNote, ID: uuid.New()}
+	m := &Machine{Scope: []Item{credential, note}}
+
+	tests := []struct {
+		wantErr error
+		name    string
+		ids     []uuid.UUID
+		want    []Item
+	}{
+		{name: "whole scope", want: []Item{credential, note}},
+		{name: "selected items", ids: []uuid.UUID{note.ID, note.ID}, want: []Item{note}},
+		{name: "item out of scope", ids: []uuid.UUID{note.ID, uuid.New()}, wantErr: ErrItemOutOfScope},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := m.Resolve(tt.ids)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMachine_Used(t *testing.T) {
+	t.Parallel()
+
+	at := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	m := &Machine{}
+
+	m.Used(at)
+
+	assert.Equal(t, at, m.LastUsedAt)
+}
+
+func BenchmarkHashToken(b *testing.B) {
+	token := "avk_" + strings.Repeat("t", 43)
+
+	for range b.N {
+		_ = HashToken(token)
+	}
+}
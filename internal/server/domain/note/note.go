@@ -4,6 +4,7 @@ import (
 	"errors"
 	"time"
 
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/securebytes"
 	"github.com/google/uuid"
 )
 
@@ -37,6 +38,14 @@ func NewNote(params NewNoteParams) (*Note, error) {
 	return &n, nil
 }
 
+// Wipe zeroes the note content and description in place once they are no longer needed.
+func (n *Note) Wipe() {
+	if n == nil {
+		return
+	}
+	securebytes.Wipe(n.Note, n.Description)
+}
+
 // NewNoteParams contains parameters for creating a new note.
 type NewNoteParams struct {
 	// Note contains the text content of the note (required).
@@ -325,3 +325,15 @@ func TestNote_ByteArrayConversion(t *testing.T) {
 		})
 	}
 }
+
+func TestNote_Wipe(t *testing.T) {
+	t.Parallel()
+
+	n := &Note{Note: []byte("secret note"), Description: []byte("secret description")}
+
+	n.Wipe()
+
+	assert.Equal(t, make([]byte, len("secret note")), n.Note)
+	assert.Equal(t, make([]byte, len("secret description")), n.Description)
+	assert.NotPanics(t, func() { (*Note)(nil).Wipe() })
+}
@@ -0,0 +1,111 @@
+package notecrdt
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// wireID is the encoded form of an element ID.
+type wireID struct {
+	// Replica identifies the replica that inserted the element.
+	Replica string `json:"replica"`
+	// Counter is the Lamport timestamp of the insertion.
+	Counter uint64 `json:"counter"`
+}
+
+// wireInsert is the encoded form of an insertion.
+type wireInsert struct {
+	// Value contains the inserted text.
+	Value string `json:"value"`
+	// ID identifies the inserted element.
+	ID wireID `json:"id"`
+	// After identifies the element the text is inserted after.
+	After wireID `json:"after"`
+}
+
+// wireOp is the encoded form of an operation.
+type wireOp struct {
+	// Insert describes the insertion.
+	Insert *wireInsert `json:"insert,omitempty"`
+	// Delete identifies the deleted element.
+	Delete *wireID `json:"delete,omitempty"`
+}
+
+// wireElement is the encoded form of a snapshot element.
+type wireElement struct {
+	// Value contains the text of the element.
+	Value string `json:"value,omitempty"`
+	// ID identifies the element.
+	ID wireID `json:"id"`
+	// Deleted reports whether the element is a tombstone.
+	Deleted bool `json:"deleted,omitempty"`
+}
+
+// wireUpdate is the encoded form of an update.
+type wireUpdate struct {
+	// Snapshot contains the elements of a snapshot.
+	Snapshot []wireElement `json:"snapshot,omitempty"`
+	// Ops lists the operations of an operation batch.
+	Ops []wireOp `json:"ops,omitempty"`
+}
+
+// Encode returns the JSON encoding of the update, the format updates are exchanged with clients and stored in.
+func Encode(u Update) ([]byte, error) {
+	w := wireUpdate{
+		Snapshot: make([]wireElement, 0, len(u.Snapshot)),
+		Ops:      make([]wireOp, 0, len(u.Ops)),
+	}
+	for _, e := range u.Snapshot {
+		w.Snapshot = append(w.Snapshot, wireElement{ID: wireID(e.ID), Value: e.Value, Deleted: e.Deleted})
+	}
+	for _, op := range u.Ops {
+		// o holds the encoded operation.
+		var o wireOp
+		if op.Insert != nil {
+			o.Insert = &wireInsert{ID: wireID(op.Insert.ID), After: wireID(op.Insert.After), Value: op.Insert.Value}
+		}
+		if op.Delete != nil {
+			id := wireID(*op.Delete)
+			o.Delete = &id
+		}
+		w.Ops = append(w.Ops, o)
+	}
+
+	data, err := json.Marshal(w)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode note text update: %w", err)
+	}
+	return data, nil
+}
+
+// Decode parses an update encoded by Encode. Unknown fields are rejected, so malformed updates are not
+// stored with parts of them silently dropped.
+func Decode(data []byte) (Update, error) {
+	// w holds the decoded wire form of the update.
+	var w wireUpdate
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&w); err != nil {
+		return Update{}, fmt.Errorf("%w: %w", ErrMalformedUpdate, err)
+	}
+
+	// u holds the decoded update.
+	var u Update
+	for _, e := range w.Snapshot {
+		u.Snapshot = append(u.Snapshot, Element{ID: ID(e.ID), Value: e.Value, Deleted: e.Deleted})
+	}
+	for _, o := range w.Ops {
+		// op holds the decoded operation.
+		var op Op
+		if o.Insert != nil {
+			op.Insert = &Insert{ID: ID(o.Insert.ID), After: ID(o.Insert.After), Value: o.Insert.Value}
+		}
+		if o.Delete != nil {
+			id := ID(*o.Delete)
+			op.Delete = &id
+		}
+		u.Ops = append(u.Ops, op)
+	}
+	return u, nil
+}
@@ -0,0 +1,6 @@
+// Package notecrdt provides the replicated text of notes edited in merge mode for the AegisVaultKeeper server.
+//
+// This package implements a Replicated Growable Array (RGA): every inserted piece of text is identified by the
+// Lamport counter of the insertion and the replica that made it, deletions leave tombstones, and concurrent
+// edits of several devices converge to the same text whatever order they are merged in.
+package notecrdt
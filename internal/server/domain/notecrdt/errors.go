@@ -0,0 +1,15 @@
+package notecrdt
+
+import "errors"
+
+// ErrInvalidOperation indicates that an operation of an update is malformed.
+var ErrInvalidOperation = errors.New("invalid note text operation")
+
+// ErrUnknownElement indicates that an operation references an element the text does not contain.
+var ErrUnknownElement = errors.New("unknown note text element")
+
+// ErrMisplacedSnapshot indicates that a snapshot is merged into a text that already has elements.
+var ErrMisplacedSnapshot = errors.New("snapshot must be the first update of the note text")
+
+// ErrMalformedUpdate indicates that an encoded update cannot be decoded.
+var ErrMalformedUpdate = errors.New("malformed note text update")
@@ -0,0 +1,33 @@
+package notecrdt
+
+import (
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/securebytes"
+	"github.com/google/uuid"
+)
+
+// Record is an update of the text of a note as stored.
+type Record struct {
+	// CreatedAt contains the timestamp when the update was stored.
+	CreatedAt time.Time
+	// Data contains the update encoded by Encode; encrypted at rest.
+	Data []byte
+	// ID uniquely identifies this record.
+	ID uuid.UUID
+	// NoteID identifies the note whose text the update changes.
+	NoteID uuid.UUID
+	// UserID identifies the user who owns the note.
+	UserID uuid.UUID
+	// Seq orders the updates of all notes; a compacted snapshot takes the sequence number of the last update
+	// it replaces.
+	Seq int64
+}
+
+// Wipe zeroes the update data in place once it is no longer needed.
+func (r *Record) Wipe() {
+	if r == nil {
+		return
+	}
+	securebytes.Wipe(r.Data)
+}
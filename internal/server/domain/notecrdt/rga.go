@@ -0,0 +1,214 @@
+package notecrdt
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// SeedReplica names the replica of the elements the text of a note is seeded with when merge mode is enabled.
+const SeedReplica = "seed"
+
+// ID identifies an element of the text by the Lamport counter of its insertion and the replica that inserted it.
+// The zero ID stands for the start of the text.
+type ID struct {
+	// Replica identifies the replica, such as a device, that inserted the element.
+	Replica string
+	// Counter is the Lamport timestamp of the insertion; it exceeds the counters of all elements the replica
+	// has seen.
+	Counter uint64
+}
+
+// IsZero reports whether the ID stands for the start of the text.
+func (id ID) IsZero() bool {
+	return id == ID{}
+}
+
+// Compare orders IDs by counter and then by replica. Elements inserted at the same place are ordered by
+// descending ID, so concurrent insertions land in the same order on every replica.
+func (id ID) Compare(other ID) int {
+	return cmp.Or(cmp.Compare(id.Counter, other.Counter), cmp.Compare(id.Replica, other.Replica))
+}
+
+// Insert describes the insertion of a piece of text.
+type Insert struct {
+	// Value contains the inserted text.
+	Value string
+	// ID identifies the inserted element.
+	ID ID
+	// After identifies the element the text is inserted after; the zero ID inserts at the start of the text.
+	After ID
+}
+
+// Op is a single operation on the text: either an insertion or a deletion.
+type Op struct {
+	// Insert describes the insertion; nil for deletions.
+	Insert *Insert
+	// Delete identifies the deleted element; nil for insertions.
+	Delete *ID
+}
+
+// Element is a piece of the text. Deleted elements are kept as tombstones without their value, so operations
+// of replicas that have not seen the deletion yet still merge.
+type Element struct {
+	// Value contains the text of the element; empty for tombstones.
+	Value string
+	// ID identifies the element.
+	ID ID
+	// Deleted reports whether the element is a tombstone.
+	Deleted bool
+}
+
+// Update is a batch of operations made by a replica, or a snapshot of the whole text made by compaction.
+type Update struct {
+	// Snapshot contains all elements of the text in order, tombstones included; empty for operation batches.
+	Snapshot []Element
+	// Ops lists the operations in the order they were made.
+	Ops []Op
+}
+
+// IsSnapshot reports whether the update is a snapshot of the whole text.
+func (u *Update) IsSnapshot() bool {
+	return len(u.Snapshot) != 0
+}
+
+// Seed returns the update inserting the text with one element per character, made by SeedReplica.
+func Seed(text string) Update {
+	// u collects the insertions of the characters.
+	var u Update
+	// after holds the ID of the previous character.
+	var after ID
+	for _, r := range text {
+		id := ID{Replica: SeedReplica, Counter: after.Counter + 1}
+		u.Ops = append(u.Ops, Op{Insert: &Insert{ID: id, After: after, Value: string(r)}})
+		after = id
+	}
+	return u
+}
+
+// Doc is the replicated text of a note.
+type Doc struct {
+	// elements holds the elements of the text in order, tombstones included.
+	elements []Element
+	// ids holds the IDs of all elements, so repeated insertions are recognized.
+	ids map[ID]struct{}
+}
+
+// NewDoc creates an empty text.
+func NewDoc() *Doc {
+	return &Doc{ids: make(map[ID]struct{})}
+}
+
+// Merge applies the update to the text. Insertions of elements the text already contains and deletions of
+// tombstones are ignored, so an update may be merged more than once. Operations must follow the operations they
+// depend on; on error the text is left with the operations preceding the failed one applied.
+func (d *Doc) Merge(u Update) error {
+	if u.IsSnapshot() {
+		if len(d.elements) != 0 {
+			return ErrMisplacedSnapshot
+		}
+		for i, e := range u.Snapshot {
+			if err := d.insertAt(i, e); err != nil {
+				return fmt.Errorf("invalid snapshot element %d: %w", i, err)
+			}
+		}
+	}
+	for i, op := range u.Ops {
+		if err := d.apply(op); err != nil {
+			return fmt.Errorf("failed to apply operation %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// Text returns the current text without tombstones.
+func (d *Doc) Text() string {
+	// b accumulates the values of the elements.
+	var b strings.Builder
+	for _, e := range d.elements {
+		b.WriteString(e.Value)
+	}
+	return b.String()
+}
+
+// Snapshot returns the update recreating the whole text, tombstones included.
+func (d *Doc) Snapshot() Update {
+	return Update{Snapshot: slices.Clone(d.elements)}
+}
+
+// apply applies a single operation to the text.
+func (d *Doc) apply(op Op) error {
+	switch {
+	case op.Insert != nil && op.Delete == nil:
+		return d.insert(*op.Insert)
+	case op.Delete != nil && op.Insert == nil:
+		return d.delete(*op.Delete)
+	default:
+		return fmt.Errorf("%w: exactly one of insert and delete must be set", ErrInvalidOperation)
+	}
+}
+
+// insert places the inserted element after its reference, skipping the elements inserted at the same place
+// concurrently with a greater ID together with the elements inserted after them.
+func (d *Doc) insert(ins Insert) error {
+	if ins.ID.Counter <= ins.After.Counter {
+		return fmt.Errorf("%w: counter %d does not exceed the counter of the preceding element",
+			ErrInvalidOperation, ins.ID.Counter)
+	}
+	if _, ok := d.ids[ins.ID]; ok {
+		return nil
+	}
+
+	pos := 0
+	if !ins.After.IsZero() {
+		i := d.index(ins.After)
+		if i < 0 {
+			return fmt.Errorf("%w: %s:%d", ErrUnknownElement, ins.After.Replica, ins.After.Counter)
+		}
+		pos = i + 1
+	}
+	for pos < len(d.elements) && d.elements[pos].ID.Compare(ins.ID) > 0 {
+		pos++
+	}
+	return d.insertAt(pos, Element{ID: ins.ID, Value: ins.Value})
+}
+
+// delete turns the element into a tombstone.
+func (d *Doc) delete(id ID) error {
+	i := d.index(id)
+	if i < 0 {
+		return fmt.Errorf("%w: %s:%d", ErrUnknownElement, id.Replica, id.Counter)
+	}
+	d.elements[i].Value = ""
+	d.elements[i].Deleted = true
+	return nil
+}
+
+// insertAt inserts the element at the position, rejecting elements without a valid ID, with a known one or
+// without text.
+func (d *Doc) insertAt(pos int, e Element) error {
+	if e.ID.Replica == "" || e.ID.Counter == 0 {
+		return fmt.Errorf("%w: element ID must have a replica and a positive counter", ErrInvalidOperation)
+	}
+	if !e.Deleted && e.Value == "" {
+		return fmt.Errorf("%w: element value is empty", ErrInvalidOperation)
+	}
+	if _, ok := d.ids[e.ID]; ok {
+		return fmt.Errorf("%w: duplicate element %s:%d", ErrInvalidOperation, e.ID.Replica, e.ID.Counter)
+	}
+	if e.Deleted {
+		e.Value = ""
+	}
+	d.ids[e.ID] = struct{}{}
+	d.elements = slices.Insert(d.elements, pos, e)
+	return nil
+}
+
+// index returns the position of the element, or -1 when the text does not contain it.
+func (d *Doc) index(id ID) int {
+	if _, ok := d.ids[id]; !ok {
+		return -1
+	}
+	return slices.IndexFunc(d.elements, func(e Element) bool { return e.ID == id })
+}
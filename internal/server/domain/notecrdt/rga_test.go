@@ -0,0 +1,177 @@
+package notecrdt
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ins returns an operation inserting the value with the ID after the referenced element.
+func ins(replica string, counter uint64, after ID, value string) Op {
+	return Op{Insert: &Insert{ID: ID{Replica: replica, Counter: counter}, After: after, Value: value}}
+}
+
+// del returns an operation deleting the element.
+func del(replica string, counter uint64) Op {
+	return Op{Delete: &ID{Replica: replica, Counter: counter}}
+}
+
+func TestDoc_Merge(t *testing.T) {
+	t.Parallel()
+
+	// base holds the seed text both replicas start from: "ac".
+	base := Seed("ac")
+	seedA := ID{Replica: SeedReplica, Counter: 1}
+
+	tests := []struct {
+		wantErr error
+		name    string
+		want    string
+		updates []Update
+	}{
+		{
+			name:    "seed",
+			updates: []Update{base},
+			want:    "ac",
+		},
+		{
+			name:    "insert and delete",
+			updates: []Update{base, {Ops: []Op{ins("phone", 3, seedA, "b"), del(SeedReplica, 2)}}},
+			want:    "ab",
+		},
+		{
+			name: "concurrent inserts at the same place",
+			updates: []Update{
+				base,
+				{Ops: []Op{ins("laptop", 3, seedA, "1")}},
+				{Ops: []Op{ins("phone", 3, seedA, "2")}},
+			},
+			want: "a21c",
+		},
+		{
+			name: "concurrent inserts in the other order",
+			updates: []Update{
+				base,
+				{Ops: []Op{ins("phone", 3, seedA, "2")}},
+				{Ops: []Op{ins("laptop", 3, seedA, "1")}},
+			},
+			want: "a21c",
+		},
+		{
+			name: "later insert skips the subtree of a concurrent one",
+			updates: []Update{
+				base,
+				{Ops: []Op{ins("phone", 4, seedA, "x"), ins("phone", 5, ID{Replica: "phone", Counter: 4}, "y")}},
+				{Ops: []Op{ins("laptop", 3, seedA, "z")}},
+			},
+			want: "axyzc",
+		},
+		{
+			name: "repeated update",
+			updates: []Update{
+				base,
+				{Ops: []Op{ins("phone", 3, seedA, "b"), del(SeedReplica, 2)}},
+				{Ops: []Op{ins("phone", 3, seedA, "b"), del(SeedReplica, 2)}},
+			},
+			want: "ab",
+		},
+		{
+			name:    "unknown reference",
+			updates: []Update{base, {Ops: []Op{ins("phone", 9, ID{Replica: "laptop", Counter: 8}, "b")}}},
+			wantErr: ErrUnknownElement,
+		},
+		{
+			name:    "unknown deletion",
+			updates: []Update{base, {Ops: []Op{del("laptop", 8)}}},
+			wantErr: ErrUnknownElement,
+		},
+		{
+			name:    "counter not exceeding the reference",
+			updates: []Update{base, {Ops: []Op{ins("phone", 1, seedA, "b")}}},
+			wantErr: ErrInvalidOperation,
+		},
+		{
+			name:    "empty value",
+			updates: []Update{base, {Ops: []Op{ins("phone", 3, seedA, "")}}},
+			wantErr: ErrInvalidOperation,
+		},
+		{
+			name:    "insert and delete in one operation",
+			updates: []Update{{Ops: []Op{{Insert: &Insert{}, Delete: &ID{}}}}},
+			wantErr: ErrInvalidOperation,
+		},
+		{
+			name:    "snapshot after operations",
+			updates: []Update{base, {Snapshot: []Element{{ID: ID{Replica: "phone", Counter: 1}, Value: "x"}}}},
+			wantErr: ErrMisplacedSnapshot,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			d := NewDoc()
+			// err holds the error of the last merged update.
+			var err error
+			for _, u := range tt.updates {
+				if err = d.Merge(u); err != nil {
+					break
+				}
+			}
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, d.Text())
+		})
+	}
+}
+
+func TestDoc_Snapshot(t *testing.T) {
+	t.Parallel()
+
+	d := NewDoc()
+	require.NoError(t, d.Merge(Seed("abc")))
+	require.NoError(t, d.Merge(Update{Ops: []Op{del(SeedReplica, 2)}}))
+
+	snapshot := d.Snapshot()
+	require.True(t, snapshot.IsSnapshot())
+	assert.Len(t, snapshot.Snapshot, 3, "tombstones are kept")
+
+	compacted := NewDoc()
+	require.NoError(t, compacted.Merge(snapshot))
+	assert.Equal(t, "ac", compacted.Text())
+
+	// Edits made against the original text still merge into the compacted one.
+	edit := Update{Ops: []Op{ins("phone", 4, ID{Replica: SeedReplica, Counter: 2}, "B")}}
+	require.NoError(t, d.Merge(edit))
+	require.NoError(t, compacted.Merge(edit))
+	assert.Equal(t, d.Text(), compacted.Text())
+	assert.Equal(t, "aBc", compacted.Text())
+}
+
+func TestEncodeDecode(t *testing.T) {
+	t.Parallel()
+
+	d := NewDoc()
+	require.NoError(t, d.Merge(Seed("hi")))
+	require.NoError(t, d.Merge(Update{Ops: []Op{del(SeedReplica, 1)}}))
+
+	for _, u := range []Update{Seed("héllo"), d.Snapshot(), {Ops: []Op{del("phone", 3)}}} {
+		data, err := Encode(u)
+		require.NoError(t, err)
+		got, err := Decode(data)
+		require.NoError(t, err)
+		assert.Equal(t, u, got)
+	}
+
+	data, err := Encode(Update{Ops: []Op{del("phone", 3)}})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"ops":[{"delete":{"replica":"phone","counter":3}}]}`, string(data))
+
+	_, err = Decode([]byte(`{"ops":[{"move":{}}]}`))
+	require.ErrorIs(t, err, ErrMalformedUpdate)
+}
@@ -0,0 +1,196 @@
+package notification
+
+import (
+	"errors"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Kind names the delivery mechanism of a notification channel.
+type Kind string
+
+// Supported channel kinds.
+const (
+	// KindEmail delivers notifications by e-mail; the target is the address.
+	KindEmail Kind = "email"
+	// KindTelegram delivers notifications through the Telegram bot; the target is the chat ID.
+	KindTelegram Kind = "telegram"
+	// KindSlack delivers notifications to a Slack incoming webhook; the target is the webhook URL.
+	KindSlack Kind = "slack"
+	// KindWebPush delivers notifications as web push messages to the push subscriptions of user devices.
+	// Web push is configured per device with push subscriptions rather than as a channel.
+	KindWebPush Kind = "webpush"
+)
+
+// Kinds lists the kinds notification channels can be configured for.
+var Kinds = []Kind{KindEmail, KindTelegram, KindSlack}
+
+// Category names a kind of notification users subscribe channels to.
+type Category string
+
+// Notification categories.
+const (
+	// CategorySecurity covers security alerts such as login verification codes.
+	CategorySecurity Category = "security"
+	// CategorySharing covers invitations to shared items.
+	CategorySharing Category = "sharing"
+	// CategoryReminders covers expiry reminders and vault health reports.
+	CategoryReminders Category = "reminders"
+)
+
+// Categories lists the notification categories.
+var Categories = []Category{CategorySecurity, CategorySharing, CategoryReminders}
+
+// slackWebhookHost is the only host Slack incoming webhooks are accepted for, so user-supplied
+// targets cannot make the server send requests to arbitrary hosts.
+const slackWebhookHost = "hooks.slack.com"
+
+// telegramChatIDPattern matches numeric Telegram chat IDs; group chats have negative IDs.
+var telegramChatIDPattern = regexp.MustCompile(`^-?[0-9]{1,20}$`)
+
+// Channel is a destination notifications of the subscribed categories are delivered to.
+type Channel struct {
+	// CreatedAt contains the timestamp when the channel was created.
+	CreatedAt time.Time
+	// UpdatedAt contains the timestamp when the channel was last changed.
+	UpdatedAt time.Time
+	// Kind names the delivery mechanism.
+	Kind Kind
+	// Target addresses the recipient within the delivery mechanism (sensitive data).
+	Target string
+	// Categories lists the notification categories delivered to this channel.
+	Categories []Category
+	// ID uniquely identifies this channel.
+	ID uuid.UUID
+	// UserID identifies the user who owns this channel.
+	UserID uuid.UUID
+}
+
+// NewChannel creates a new notification channel with the provided parameters after validation.
+func NewChannel(params NewChannelParams) (*Channel, error) {
+	target, err := params.validate()
+	if err != nil {
+		return nil, errors.Join(ErrNewChannelParamsValidation, err)
+	}
+	now := time.Now()
+	return &Channel{
+		ID:         uuid.New(),
+		UserID:     params.UserID,
+		Kind:       params.Kind,
+		Target:     target,
+		Categories: normalizeCategories(params.Categories),
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}, nil
+}
+
+// Reconfigure replaces the target and the categories of the channel after validation.
+func (c *Channel) Reconfigure(target string, categories []Category) error {
+	params := NewChannelParams{Kind: c.Kind, Target: target, Categories: categories, UserID: c.UserID}
+	normalized, err := params.validate()
+	if err != nil {
+		return errors.Join(ErrNewChannelParamsValidation, err)
+	}
+	c.Target = normalized
+	c.Categories = normalizeCategories(categories)
+	c.UpdatedAt = time.Now()
+	return nil
+}
+
+// Subscribed reports whether notifications of the category are delivered to the channel.
+func (c *Channel) Subscribed(category Category) bool {
+	return slices.Contains(c.Categories, category)
+}
+
+// Hint returns a non-sensitive description of the target that lets the owner recognize the channel:
+// the address of e-mail channels, the chat ID of Telegram channels and the host of webhook URLs.
+func (c *Channel) Hint() string {
+	switch c.Kind {
+	case KindSlack:
+		return slackWebhookHost
+	default:
+		return c.Target
+	}
+}
+
+// NewChannelParams contains parameters for creating a new notification channel.
+type NewChannelParams struct {
+	// Kind names the delivery mechanism (required).
+	Kind Kind
+	// Target addresses the recipient within the delivery mechanism (required).
+	Target string
+	// Categories lists the notification categories to deliver (at least one).
+	Categories []Category
+	// UserID identifies the user who owns this channel.
+	UserID uuid.UUID
+}
+
+// Validate checks that the channel creation parameters are valid.
+func (p *NewChannelParams) Validate() error {
+	_, err := p.validate()
+	return err
+}
+
+// validate checks the parameters and returns the target in its normalized form.
+func (p *NewChannelParams) validate() (string, error) {
+	var errs []error
+	target, err := normalizeTarget(p.Kind, p.Target)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	if len(p.Categories) == 0 {
+		errs = append(errs, ErrIncorrectCategories)
+	} else if err := validateCategories(p.Categories); err != nil {
+		errs = append(errs, err)
+	}
+	return target, errors.Join(errs...)
+}
+
+// normalizeTarget validates the target for the channel kind and returns its normalized form.
+func normalizeTarget(kind Kind, target string) (string, error) {
+	target = strings.TrimSpace(target)
+	switch kind {
+	case KindEmail:
+		addr, err := mail.ParseAddress(target)
+		if err != nil {
+			return "", ErrIncorrectTarget
+		}
+		return addr.Address, nil
+	case KindTelegram:
+		if !telegramChatIDPattern.MatchString(target) {
+			return "", ErrIncorrectTarget
+		}
+		return target, nil
+	case KindSlack:
+		u, err := url.Parse(target)
+		if err != nil || u.Scheme != "https" || u.Host != slackWebhookHost || !strings.HasPrefix(u.Path, "/services/") {
+			return "", ErrIncorrectTarget
+		}
+		return u.String(), nil
+	default:
+		return "", ErrIncorrectKind
+	}
+}
+
+// validateCategories checks that every category is a known one.
+func validateCategories(categories []Category) error {
+	for _, c := range categories {
+		if !slices.Contains(Categories, c) {
+			return ErrIncorrectCategories
+		}
+	}
+	return nil
+}
+
+// normalizeCategories returns the categories sorted and without duplicates.
+func normalizeCategories(categories []Category) []Category {
+	result := slices.Clone(categories)
+	slices.Sort(result)
+	return slices.Compact(result)
+}
@@ -0,0 +1,254 @@
+package notification
+
+import (
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testWebPushSubscription returns a JSON push subscription with a fresh user agent key.
+func testWebPushSubscription(t *testing.T, endpoint string) string {
+	t.Helper()
+
+	key, err := ecdh.P256().GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	var sub WebPushSubscription
+	sub.Endpoint = endpoint
+	sub.Keys.P256dh = base64.RawURLEncoding.EncodeToString(key.PublicKey().Bytes())
+	sub.Keys.Auth = base64.RawURLEncoding.EncodeToString(make([]byte, webPushAuthSecretSize))
+	b, err := json.Marshal(sub)
+	require.NoError(t, err)
+	return string(b)
+}
+
+func TestNewChannel(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	security := []Category{CategorySecurity}
+
+	tests := []struct {
+		wantErr        error
+		name           string
+		wantTarget     string
+		params         NewChannelParams
+		wantCategories []Category
+	}{
+		{
+			name:           "email",
+			params:         NewChannelParams{Kind: KindEmail, Target: " Alice <alice@example.com> ", Categories: security},
+			wantTarget:     "alice@example.com",
+			wantCategories: security,
+		},
+		{
+			name: "telegram with duplicate categories",
+			params: NewChannelParams{
+				Kind:       KindTelegram,
+				Target:     "-100123",
+				Categories: []Category{CategorySharing, CategorySecurity, CategorySharing},
+			},
+			wantTarget:     "-100123",
+			wantCategories: []Category{CategorySecurity, CategorySharing},
+		},
+		{
+			name: "slack",
+			params: NewChannelParams{
+				Kind:       KindSlack,
+				Target:     "https://hooks.slack.com/services/T0/B0/x",
+				Categories: security,
+			},
+			wantTarget:     "https://hooks.slack.com/services/T0/B0/x",
+			wantCategories: security,
+		},
+		{
+			name:    "unknown kind",
+			params:  NewChannelParams{Kind: "sms", Target: "+100", Categories: security},
+			wantErr: ErrIncorrectKind,
+		},
+		{
+			name:    "malformed email",
+			params:  NewChannelParams{Kind: KindEmail, Target: "alice", Categories: security},
+			wantErr: ErrIncorrectTarget,
+		},
+		{
+			name:    "telegram username",
+			params:  NewChannelParams{Kind: KindTelegram, Target: "@alice", Categories: security},
+			wantErr: ErrIncorrectTarget,
+		},
+		{
+			name: "slack webhook on other host",
+			params: NewChannelParams{
+				Kind:       KindSlack,
+				Target:     "https://internal.example.com/services/x",
+				Categories: security,
+			},
+			wantErr: ErrIncorrectTarget,
+		},
+		{
+			name: "plain http slack webhook",
+			params: NewChannelParams{
+				Kind:       KindSlack,
+				Target:     "http://hooks.slack.com/services/x",
+				Categories: security,
+			},
+			wantErr: ErrIncorrectTarget,
+		},
+		{
+			name: "web push is configured per device",
+			params: NewChannelParams{
+				Kind:       KindWebPush,
+				Target:     testWebPushSubscription(t, "https://fcm.googleapis.com/fcm/send/abc"),
+				Categories: security,
+			},
+			wantErr: ErrIncorrectKind,
+		},
+		{
+			name:    "no categories",
+			params:  NewChannelParams{Kind: KindTelegram, Target: "42"},
+			wantErr: ErrIncorrectCategories,
+		},
+		{
+			name:    "unknown category",
+			params:  NewChannelParams{Kind: KindTelegram, Target: "42", Categories: []Category{"marketing"}},
+			wantErr: ErrIncorrectCategories,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tt.params.UserID = userID
+			c, err := NewChannel(tt.params)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, ErrNewChannelParamsValidation)
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, c)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotEqual(t, uuid.Nil, c.ID)
+			assert.Equal(t, userID, c.UserID)
+			assert.Equal(t, tt.params.Kind, c.Kind)
+			assert.Equal(t, tt.wantTarget, c.Target)
+			assert.Equal(t, tt.wantCategories, c.Categories)
+			assert.False(t, c.CreatedAt.IsZero())
+		})
+	}
+}
+
+func TestChannel_Reconfigure(t *testing.T) {
+	t.Parallel()
+
+	c, err := NewChannel(NewChannelParams{
+		Kind:       KindTelegram,
+		Target:     "42",
+		Categories: []Category{CategorySecurity},
+		UserID:     uuid.New(),
+	})
+	require.NoError(t, err)
+
+	err = c.Reconfigure("alice", []Category{CategoryReminders})
+	require.ErrorIs(t, err, ErrIncorrectTarget)
+	assert.Equal(t, "42", c.Target)
+	assert.Equal(t, []Category{CategorySecurity}, c.Categories)
+
+	require.NoError(t, c.Reconfigure("43", []Category{CategoryReminders}))
+	assert.Equal(t, "43", c.Target)
+	assert.True(t, c.Subscribed(CategoryReminders))
+	assert.False(t, c.Subscribed(CategorySecurity))
+}
+
+func TestChannel_Hint(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		channel Channel
+		want    string
+	}{
+		{
+			name:    "email shows address",
+			channel: Channel{Kind: KindEmail, Target: "alice@example.com"},
+			want:    "alice@example.com",
+		},
+		{
+			name:    "telegram shows chat ID",
+			channel: Channel{Kind: KindTelegram, Target: "42"},
+			want:    "42",
+		},
+		{
+			name:    "slack hides webhook secret",
+			channel: Channel{Kind: KindSlack, Target: "https://hooks.slack.com/services/T0/B0/secret"},
+			want:    "hooks.slack.com",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, tt.channel.Hint())
+		})
+	}
+}
+
+func TestParseWebPushSubscription(t *testing.T) {
+	t.Parallel()
+
+	valid := testWebPushSubscription(t, "https://push.example.com/abc")
+	padded := func() string {
+		var sub WebPushSubscription
+		require.NoError(t, json.Unmarshal([]byte(valid), &sub))
+		sub.Keys.Auth = base64.URLEncoding.EncodeToString(make([]byte, webPushAuthSecretSize))
+		b, err := json.Marshal(sub)
+		require.NoError(t, err)
+		return string(b)
+	}()
+
+	tests := []struct {
+		name    string
+		target  string
+		wantErr bool
+	}{
+		{name: "valid", target: valid},
+		{name: "padded keys", target: padded},
+		{name: "not JSON", target: "push", wantErr: true},
+		{
+			name:    "http endpoint",
+			target:  testWebPushSubscription(t, "http://push.example.com/abc"),
+			wantErr: true,
+		},
+		{
+			name:    "compressed public key",
+			target:  `{"endpoint":"https://push.example.com","keys":{"p256dh":"AwAA","auth":"AAAAAAAAAAAAAAAAAAAAAA"}}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			sub, err := ParseWebPushSubscription(tt.target)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Nil(t, sub)
+				return
+			}
+			require.NoError(t, err)
+			key, err := sub.PublicKey()
+			require.NoError(t, err)
+			assert.Len(t, key, webPushPublicKeySize)
+			assert.Equal(t, "push.example.com", sub.Host())
+		})
+	}
+}
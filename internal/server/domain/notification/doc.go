@@ -0,0 +1,7 @@
+// Package notification provides notification channel domain entities and business rules
+// for the AegisVaultKeeper server.
+//
+// This package implements core domain logic for the channels users receive notifications on,
+// such as e-mail, Telegram and Slack, the web push subscriptions of their devices, and the
+// notification categories each one is subscribed to.
+package notification
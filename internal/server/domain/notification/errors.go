@@ -0,0 +1,24 @@
+package notification
+
+import "errors"
+
+// Notification domain error definitions.
+var (
+	// ErrNewChannelParamsValidation indicates that channel creation parameters failed validation.
+	ErrNewChannelParamsValidation = errors.New("new notification channel parameters validation failed")
+	// ErrIncorrectKind indicates that the channel kind is not supported.
+	ErrIncorrectKind = errors.New("incorrect notification channel kind")
+	// ErrIncorrectTarget indicates that the channel target is malformed for its kind.
+	ErrIncorrectTarget = errors.New("incorrect notification channel target")
+	// ErrIncorrectCategories indicates that no categories or unknown categories were selected.
+	ErrIncorrectCategories = errors.New("incorrect notification categories")
+	// ErrNewPushSubscriptionParamsValidation indicates that push subscription parameters failed validation.
+	ErrNewPushSubscriptionParamsValidation = errors.New("new push subscription parameters validation failed")
+	// ErrIncorrectDevice indicates that the device name of a push subscription is empty or too long.
+	ErrIncorrectDevice = errors.New("incorrect push subscription device")
+	// ErrIncorrectSubscription indicates that the endpoint or the keys of a push subscription are malformed.
+	ErrIncorrectSubscription = errors.New("incorrect push subscription")
+	// ErrPushSubscriptionGone indicates that the push service no longer accepts messages for the subscription,
+	// because the browser unsubscribed or the subscription expired.
+	ErrPushSubscriptionGone = errors.New("push subscription expired or unsubscribed")
+)
@@ -0,0 +1,120 @@
+package notification
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// maxDeviceLen limits the length of push subscription device names, in characters.
+const maxDeviceLen = 64
+
+// PushMessageType distinguishes the web push messages handled by the service worker of a client.
+type PushMessageType string
+
+// Web push message types.
+const (
+	// PushMessageNotification carries a notification to show to the user.
+	PushMessageNotification PushMessageType = "notification"
+	// PushMessageSync tells the client that the vault changed on another device and should be pulled.
+	PushMessageSync PushMessageType = "sync"
+)
+
+// PushMessage is the JSON payload of a web push message. Sync messages carry no content, so vault
+// changes are never revealed to push services.
+type PushMessage struct {
+	// Type tells the service worker how to handle the message.
+	Type PushMessageType `json:"type"`
+	// Category names the kind of notification; empty for sync messages.
+	Category Category `json:"category,omitempty"`
+	// Title contains the one-line summary of the notification.
+	Title string `json:"title,omitempty"`
+	// Body contains the plain-text content of the notification.
+	Body string `json:"body,omitempty"`
+}
+
+// PushSubscription is a browser push subscription of a user device. It receives sync messages whenever
+// the vault changes and notifications of the subscribed categories.
+type PushSubscription struct {
+	// CreatedAt contains the timestamp when the device subscribed.
+	CreatedAt time.Time
+	// UpdatedAt contains the timestamp when the subscription was last renewed.
+	UpdatedAt time.Time
+	// Device contains the name the user recognizes the device by.
+	Device string
+	// Subscription contains the push service endpoint and the encryption keys (sensitive data).
+	Subscription WebPushSubscription
+	// Categories lists the notification categories delivered to the device; empty for sync only.
+	Categories []Category
+	// ID uniquely identifies this subscription.
+	ID uuid.UUID
+	// UserID identifies the user who owns this subscription.
+	UserID uuid.UUID
+}
+
+// NewPushSubscription creates a new push subscription with the provided parameters after validation.
+func NewPushSubscription(params NewPushSubscriptionParams) (*PushSubscription, error) {
+	if err := params.Validate(); err != nil {
+		return nil, errors.Join(ErrNewPushSubscriptionParamsValidation, err)
+	}
+	now := time.Now()
+	return &PushSubscription{
+		ID:           uuid.New(),
+		UserID:       params.UserID,
+		Device:       strings.TrimSpace(params.Device),
+		Subscription: params.Subscription,
+		Categories:   normalizeCategories(params.Categories),
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}, nil
+}
+
+// Renew replaces the device name, the keys and the categories of the subscription after validation.
+// Browsers rotate the keys of an endpoint, so a device subscribing again renews its subscription.
+func (s *PushSubscription) Renew(params NewPushSubscriptionParams) error {
+	if err := params.Validate(); err != nil {
+		return errors.Join(ErrNewPushSubscriptionParamsValidation, err)
+	}
+	s.Device = strings.TrimSpace(params.Device)
+	s.Subscription = params.Subscription
+	s.Categories = normalizeCategories(params.Categories)
+	s.UpdatedAt = time.Now()
+	return nil
+}
+
+// Subscribed reports whether notifications of the category are delivered to the device.
+func (s *PushSubscription) Subscribed(category Category) bool {
+	return slices.Contains(s.Categories, category)
+}
+
+// NewPushSubscriptionParams contains parameters for creating a new push subscription.
+type NewPushSubscriptionParams struct {
+	// Device contains the name the user recognizes the device by (required).
+	Device string
+	// Subscription contains the push service endpoint and the encryption keys (required).
+	Subscription WebPushSubscription
+	// Categories lists the notification categories to deliver; empty subscribes to sync messages only.
+	Categories []Category
+	// UserID identifies the user who owns this subscription.
+	UserID uuid.UUID
+}
+
+// Validate checks that the push subscription parameters are valid.
+func (p *NewPushSubscriptionParams) Validate() error {
+	var errs []error
+	device := strings.TrimSpace(p.Device)
+	if device == "" || utf8.RuneCountInString(device) > maxDeviceLen {
+		errs = append(errs, ErrIncorrectDevice)
+	}
+	if err := p.Subscription.Validate(); err != nil {
+		errs = append(errs, errors.Join(ErrIncorrectSubscription, err))
+	}
+	if err := validateCategories(p.Categories); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
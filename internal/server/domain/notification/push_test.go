@@ -0,0 +1,134 @@
+package notification
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testPushSubscription returns a push subscription with a fresh user agent key.
+func testPushSubscription(t *testing.T, endpoint string) WebPushSubscription {
+	t.Helper()
+
+	var sub WebPushSubscription
+	require.NoError(t, json.Unmarshal([]byte(testWebPushSubscription(t, endpoint)), &sub))
+	return sub
+}
+
+func TestNewPushSubscription(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	valid := testPushSubscription(t, "https://fcm.googleapis.com/fcm/send/abc")
+	insecure := testPushSubscription(t, "http://fcm.googleapis.com/fcm/send/abc")
+
+	tests := []struct {
+		wantErr        error
+		name           string
+		wantDevice     string
+		params         NewPushSubscriptionParams
+		wantCategories []Category
+	}{
+		{
+			name: "with categories",
+			params: NewPushSubscriptionParams{
+				Device:       " Laptop ",
+				Subscription: valid,
+				Categories:   []Category{CategoryReminders, CategorySecurity, CategoryReminders},
+			},
+			wantDevice:     "Laptop",
+			wantCategories: []Category{CategoryReminders, CategorySecurity},
+		},
+		{
+			name:       "sync only",
+			params:     NewPushSubscriptionParams{Device: "Phone", Subscription: valid},
+			wantDevice: "Phone",
+		},
+		{
+			name:    "empty device",
+			params:  NewPushSubscriptionParams{Device: "  ", Subscription: valid},
+			wantErr: ErrIncorrectDevice,
+		},
+		{
+			name:    "long device",
+			params:  NewPushSubscriptionParams{Device: strings.Repeat("д", maxDeviceLen+1), Subscription: valid},
+			wantErr: ErrIncorrectDevice,
+		},
+		{
+			name:    "http endpoint",
+			params:  NewPushSubscriptionParams{Device: "Laptop", Subscription: insecure},
+			wantErr: ErrIncorrectSubscription,
+		},
+		{
+			name:    "missing keys",
+			params:  NewPushSubscriptionParams{Device: "Laptop", Subscription: WebPushSubscription{Endpoint: valid.Endpoint}},
+			wantErr: ErrIncorrectSubscription,
+		},
+		{
+			name: "unknown category",
+			params: NewPushSubscriptionParams{
+				Device:       "Laptop",
+				Subscription: valid,
+				Categories:   []Category{"marketing"},
+			},
+			wantErr: ErrIncorrectCategories,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tt.params.UserID = userID
+			s, err := NewPushSubscription(tt.params)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, ErrNewPushSubscriptionParamsValidation)
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, s)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotEqual(t, uuid.Nil, s.ID)
+			assert.Equal(t, userID, s.UserID)
+			assert.Equal(t, tt.wantDevice, s.Device)
+			assert.Equal(t, tt.params.Subscription, s.Subscription)
+			assert.Equal(t, tt.wantCategories, s.Categories)
+			assert.False(t, s.CreatedAt.IsZero())
+		})
+	}
+}
+
+func TestPushSubscription_Renew(t *testing.T) {
+	t.Parallel()
+
+	original := testPushSubscription(t, "https://push.example.com/abc")
+	s, err := NewPushSubscription(NewPushSubscriptionParams{
+		Device:       "Laptop",
+		Subscription: original,
+		Categories:   []Category{CategorySecurity},
+		UserID:       uuid.New(),
+	})
+	require.NoError(t, err)
+
+	err = s.Renew(NewPushSubscriptionParams{Device: "", Subscription: original})
+	require.ErrorIs(t, err, ErrIncorrectDevice)
+	assert.Equal(t, "Laptop", s.Device)
+	assert.True(t, s.Subscribed(CategorySecurity))
+
+	rotated := testPushSubscription(t, "https://push.example.com/abc")
+	err = s.Renew(NewPushSubscriptionParams{
+		Device:       "Work laptop",
+		Subscription: rotated,
+		Categories:   []Category{CategoryReminders},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Work laptop", s.Device)
+	assert.Equal(t, rotated, s.Subscription)
+	assert.True(t, s.Subscribed(CategoryReminders))
+	assert.False(t, s.Subscribed(CategorySecurity))
+}
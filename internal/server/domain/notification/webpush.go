@@ -0,0 +1,92 @@
+package notification
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strings"
+)
+
+// Web push subscription key sizes (RFC 8291).
+const (
+	// webPushPublicKeySize is the size of the uncompressed P-256 public key of the user agent.
+	webPushPublicKeySize = 65
+	// webPushAuthSecretSize is the size of the authentication secret of the user agent.
+	webPushAuthSecretSize = 16
+)
+
+// WebPushSubscription holds a push subscription as serialized by PushSubscription.toJSON() in browsers.
+type WebPushSubscription struct {
+	// Endpoint is the push service URL messages are posted to.
+	Endpoint string `json:"endpoint"`
+	// Keys contains the message encryption keys of the user agent.
+	Keys WebPushKeys `json:"keys"`
+}
+
+// WebPushKeys holds the message encryption keys of a push subscription.
+type WebPushKeys struct {
+	// P256dh contains the base64url-encoded P-256 public key of the user agent.
+	P256dh string `json:"p256dh"`
+	// Auth contains the base64url-encoded authentication secret of the user agent.
+	Auth string `json:"auth"`
+}
+
+// PublicKey returns the decoded P-256 public key of the user agent.
+func (s *WebPushSubscription) PublicKey() ([]byte, error) {
+	return decodeWebPushKey(s.Keys.P256dh, webPushPublicKeySize)
+}
+
+// AuthSecret returns the decoded authentication secret of the user agent.
+func (s *WebPushSubscription) AuthSecret() ([]byte, error) {
+	return decodeWebPushKey(s.Keys.Auth, webPushAuthSecretSize)
+}
+
+// Host returns the host of the push service serving the endpoint.
+func (s *WebPushSubscription) Host() string {
+	u, err := url.Parse(s.Endpoint)
+	if err != nil {
+		return ""
+	}
+	return u.Host
+}
+
+// Validate checks that the endpoint is an https URL and the keys are well-formed.
+func (s *WebPushSubscription) Validate() error {
+	u, err := url.Parse(s.Endpoint)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return errors.New("push subscription endpoint must be an https URL")
+	}
+	key, err := s.PublicKey()
+	if err != nil || key[0] != 0x04 {
+		return errors.New("push subscription p256dh key must be an uncompressed P-256 point")
+	}
+	if _, err := s.AuthSecret(); err != nil {
+		return errors.New("push subscription auth secret must be 16 bytes")
+	}
+	return nil
+}
+
+// ParseWebPushSubscription parses a JSON push subscription and checks its endpoint and keys.
+func ParseWebPushSubscription(target string) (*WebPushSubscription, error) {
+	var sub WebPushSubscription
+	if err := json.Unmarshal([]byte(target), &sub); err != nil {
+		return nil, errors.New("malformed push subscription")
+	}
+	if err := sub.Validate(); err != nil {
+		return nil, err
+	}
+	return &sub, nil
+}
+
+// decodeWebPushKey decodes base64url key material, padded or not, of the expected size.
+func decodeWebPushKey(s string, size int) ([]byte, error) {
+	b, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+	if err != nil {
+		return nil, errors.New("invalid base64url key")
+	}
+	if len(b) != size {
+		return nil, errors.New("unexpected key size")
+	}
+	return b, nil
+}
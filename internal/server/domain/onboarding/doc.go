@@ -0,0 +1,6 @@
+// Package onboarding provides onboarding wizard domain entities and business rules
+// for the AegisVaultKeeper server.
+//
+// This package implements the template of the first-run wizard that checks a freshly deployed server and creates
+// its first account: the steps it is made of, their order and the states they end in.
+package onboarding
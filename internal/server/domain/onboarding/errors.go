@@ -0,0 +1,18 @@
+package onboarding
+
+import "errors"
+
+// Onboarding domain error definitions.
+var (
+	// ErrIncorrectTemplate indicates that the onboarding wizard template is invalid.
+	ErrIncorrectTemplate = errors.New("incorrect onboarding template")
+
+	// ErrUnknownStep indicates that the template lists a step the wizard does not know.
+	ErrUnknownStep = errors.New("unknown onboarding step")
+
+	// ErrDuplicateStep indicates that the template lists a step more than once.
+	ErrDuplicateStep = errors.New("duplicate onboarding step")
+
+	// ErrAdminStepNotLast indicates that the template does not end with the admin account step.
+	ErrAdminStepNotLast = errors.New("onboarding must end with the admin step")
+)
@@ -0,0 +1,52 @@
+package onboarding
+
+import "time"
+
+// Result describes the outcome of the last run of a step.
+type Result struct {
+	// CheckedAt contains the timestamp when the step last ran; zero for pending steps.
+	CheckedAt time.Time
+	// Status contains the state the step ended in.
+	Status string
+	// Message explains the state to the operator.
+	Message string
+}
+
+// Progress holds the results of the steps run so far, by step name.
+type Progress map[string]Result
+
+// Result returns the result of the named step; steps that have not run are pending.
+func (p Progress) Result(name string) Result {
+	if r, ok := p[name]; ok {
+		return r
+	}
+	return Result{Status: StatusPending}
+}
+
+// Record stores the result of the named step.
+func (p Progress) Record(name, status, message string) Result {
+	r := Result{Status: status, Message: message, CheckedAt: time.Now()}
+	p[name] = r
+	return r
+}
+
+// Next returns the name of the first step of the template that neither passed nor was skipped; empty when every
+// step is done.
+func (p Progress) Next(t Template) string {
+	for _, d := range t {
+		if s := p.Result(d.Name).Status; s != StatusPassed && s != StatusSkipped {
+			return d.Name
+		}
+	}
+	return ""
+}
+
+// Ready reports whether every required step preceding the named step of the template passed.
+func (p Progress) Ready(t Template, name string) bool {
+	for _, d := range t.Before(name) {
+		if d.Required && p.Result(d.Name).Status != StatusPassed {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,37 @@
+package onboarding
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProgress(t *testing.T) {
+	t.Parallel()
+
+	template, err := NewTemplate(nil)
+	require.NoError(t, err)
+	progress := Progress{}
+
+	assert.Equal(t, StatusPending, progress.Result(StepStorage).Status)
+	assert.True(t, progress.Result(StepStorage).CheckedAt.IsZero())
+	assert.Equal(t, StepMasterKey, progress.Next(template))
+	assert.False(t, progress.Ready(template, StepAdmin))
+	assert.True(t, progress.Ready(template, StepMasterKey))
+
+	r := progress.Record(StepMasterKey, StatusPassed, "ok")
+	assert.False(t, r.CheckedAt.IsZero())
+	progress.Record(StepStorage, StatusFailed, "read-only file system")
+	assert.Equal(t, StepStorage, progress.Next(template))
+	assert.False(t, progress.Ready(template, StepAdmin))
+
+	progress.Record(StepStorage, StatusPassed, "ok")
+	assert.Equal(t, StepSMTP, progress.Next(template))
+	assert.True(t, progress.Ready(template, StepAdmin), "the smtp step is optional")
+
+	progress.Record(StepSMTP, StatusSkipped, "no relay")
+	assert.Equal(t, StepAdmin, progress.Next(template))
+	progress.Record(StepAdmin, StatusPassed, "created")
+	assert.Empty(t, progress.Next(template))
+}
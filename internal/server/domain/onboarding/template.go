@@ -0,0 +1,127 @@
+package onboarding
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// Onboarding step names.
+const (
+	// StepMasterKey checks the master key the stored data is encrypted with.
+	StepMasterKey = "master_key"
+	// StepStorage checks that the file storage accepts, returns and removes files.
+	StepStorage = "storage"
+	// StepSMTP sends a test message through the SMTP relay.
+	StepSMTP = "smtp"
+	// StepAdmin creates the first account of the server and completes the onboarding.
+	StepAdmin = "admin"
+)
+
+// Step states.
+const (
+	// StatusPending marks a step that has not run yet.
+	StatusPending = "pending"
+	// StatusPassed marks a step that ran successfully.
+	StatusPassed = "passed"
+	// StatusFailed marks a step whose check failed; it may be run again.
+	StatusFailed = "failed"
+	// StatusSkipped marks an optional step that does not apply to the server.
+	StatusSkipped = "skipped"
+)
+
+// DefaultSteps lists the steps of the wizard in the default order.
+var DefaultSteps = []string{StepMasterKey, StepStorage, StepSMTP, StepAdmin}
+
+// definitions describes every step the wizard knows.
+var definitions = map[string]StepDefinition{
+	StepMasterKey: {
+		Name:  StepMasterKey,
+		Title: "Master key",
+		Description: "Checks the MASTER_KEY every stored secret is encrypted with. The key is read when the server " +
+			"starts, so a weak key is replaced in the configuration followed by a restart.",
+		Required: true,
+	},
+	StepStorage: {
+		Name:        StepStorage,
+		Title:       "File storage",
+		Description: "Writes, reads back and removes a probe file in FILE_STORAGE_BASE_PATH.",
+		Required:    true,
+	},
+	StepSMTP: {
+		Name:  StepSMTP,
+		Title: "E-mail delivery",
+		Description: "Sends a test message through the SMTP relay to the given address. Skipped when no relay is " +
+			"configured; e-mail features stay disabled then.",
+	},
+	StepAdmin: {
+		Name:  StepAdmin,
+		Title: "Administrator account",
+		Description: "Creates the first account of the server once the required checks passed. The wizard closes " +
+			"afterwards.",
+		Required: true,
+	},
+}
+
+// StepDefinition describes a step of the onboarding wizard.
+type StepDefinition struct {
+	// Name identifies the step.
+	Name string
+	// Title names the step for display.
+	Title string
+	// Description explains what the step does.
+	Description string
+	// Required determines whether the step must pass before the onboarding completes.
+	Required bool
+}
+
+// Template lists the steps of the onboarding wizard in the order they are presented.
+type Template []StepDefinition
+
+// NewTemplate creates the wizard template of the named steps in the given order; no names use DefaultSteps.
+// Every step may appear once, and the template must end with StepAdmin, since creating the first account
+// completes the onboarding.
+func NewTemplate(names []string) (Template, error) {
+	if len(names) == 0 {
+		names = DefaultSteps
+	}
+
+	var errs []error
+	template := make(Template, 0, len(names))
+	for i, name := range names {
+		d, ok := definitions[name]
+		switch {
+		case !ok:
+			errs = append(errs, fmt.Errorf("%w: %q", ErrUnknownStep, name))
+		case slices.Contains(names[:i], name):
+			errs = append(errs, fmt.Errorf("%w: %q", ErrDuplicateStep, name))
+		default:
+			template = append(template, d)
+		}
+	}
+	if names[len(names)-1] != StepAdmin {
+		errs = append(errs, ErrAdminStepNotLast)
+	}
+	if len(errs) != 0 {
+		return nil, errors.Join(append([]error{ErrIncorrectTemplate}, errs...)...)
+	}
+	return template, nil
+}
+
+// Step returns the definition of the named step and whether the template contains it.
+func (t Template) Step(name string) (StepDefinition, bool) {
+	i := slices.IndexFunc(t, func(d StepDefinition) bool { return d.Name == name })
+	if i < 0 {
+		return StepDefinition{}, false
+	}
+	return t[i], true
+}
+
+// Before returns the definitions of the steps preceding the named step.
+func (t Template) Before(name string) Template {
+	i := slices.IndexFunc(t, func(d StepDefinition) bool { return d.Name == name })
+	if i < 0 {
+		return nil
+	}
+	return t[:i]
+}
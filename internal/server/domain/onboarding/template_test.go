@@ -0,0 +1,94 @@
+package onboarding
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTemplate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		wantErr   error
+		name      string
+		names     []string
+		wantSteps []string
+	}{
+		{
+			name:      "default steps",
+			wantSteps: DefaultSteps,
+		},
+		{
+			name:      "custom order without smtp",
+			names:     []string{StepStorage, StepMasterKey, StepAdmin},
+			wantSteps: []string{StepStorage, StepMasterKey, StepAdmin},
+		},
+		{
+			name:      "admin only",
+			names:     []string{StepAdmin},
+			wantSteps: []string{StepAdmin},
+		},
+		{
+			name:    "unknown step",
+			names:   []string{"dns", StepAdmin},
+			wantErr: ErrUnknownStep,
+		},
+		{
+			name:    "duplicate step",
+			names:   []string{StepStorage, StepStorage, StepAdmin},
+			wantErr: ErrDuplicateStep,
+		},
+		{
+			name:    "admin step not last",
+			names:   []string{StepAdmin, StepStorage},
+			wantErr: ErrAdminStepNotLast,
+		},
+		{
+			name:    "admin step missing",
+			names:   []string{StepStorage},
+			wantErr: ErrAdminStepNotLast,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			template, err := NewTemplate(tt.names)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, ErrIncorrectTemplate)
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, template)
+				return
+			}
+			require.NoError(t, err)
+			// names collects the step names of the template in order.
+			var names []string
+			for _, d := range template {
+				names = append(names, d.Name)
+				assert.NotEmpty(t, d.Title)
+				assert.NotEmpty(t, d.Description)
+			}
+			assert.Equal(t, tt.wantSteps, names)
+		})
+	}
+}
+
+func TestTemplate_Step(t *testing.T) {
+	t.Parallel()
+
+	template, err := NewTemplate([]string{StepStorage, StepAdmin})
+	require.NoError(t, err)
+
+	d, ok := template.Step(StepStorage)
+	assert.True(t, ok)
+	assert.True(t, d.Required)
+	_, ok = template.Step(StepSMTP)
+	assert.False(t, ok)
+
+	assert.Equal(t, template[:1], template.Before(StepAdmin))
+	assert.Empty(t, template.Before(StepStorage))
+	assert.Nil(t, template.Before(StepSMTP))
+}
@@ -0,0 +1,6 @@
+// Package plan provides subscription plan domain entities for the AegisVaultKeeper server.
+//
+// This package implements the plans of hosted deployments and the limits each plan puts on vault items,
+// file sizes and credential sharing, and the billing subscriptions assigning plans to users. Self-hosted
+// deployments usually run without plans.
+package plan
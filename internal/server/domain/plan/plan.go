@@ -0,0 +1,90 @@
+package plan
+
+// Names of the built-in plans.
+const (
+	// NameFree names the free plan.
+	NameFree = "free"
+	// NamePremium names the premium plan.
+	NamePremium = "premium"
+)
+
+// Limits describes what users on a plan may store and share. Zero numeric limits mean no limit.
+type Limits struct {
+	// MaxItems limits the number of stored vault items: credentials, notes, bank cards and files.
+	MaxItems int64
+	// MaxFileSize limits the size of a single file in bytes.
+	MaxFileSize int64
+	// MaxOrgSize limits the number of members of a group credentials are shared with.
+	MaxOrgSize int
+	// Sharing allows sharing credentials with groups.
+	Sharing bool
+}
+
+// Plan represents a subscription plan and its limits.
+type Plan struct {
+	// Name identifies the plan, such as NameFree.
+	Name string
+	// Limits contains the limits of the plan.
+	Limits Limits
+}
+
+// Built-in plans.
+var (
+	// Free is the plan of users who do not pay: a small vault of their own without sharing.
+	Free = Plan{
+		Name: NameFree,
+		Limits: Limits{
+			MaxItems:    50,
+			MaxFileSize: 10 << 20,
+		},
+	}
+	// Premium is the plan of paying users: an unlimited vault shared with groups of up to 10 members.
+	Premium = Plan{
+		Name: NamePremium,
+		Limits: Limits{
+			MaxOrgSize: 10,
+			Sharing:    true,
+		},
+	}
+)
+
+// Lookup returns the built-in plan with the name.
+func Lookup(name string) (Plan, bool) {
+	switch name {
+	case NameFree:
+		return Free, true
+	case NamePremium:
+		return Premium, true
+	default:
+		return Plan{}, false
+	}
+}
+
+// AllowsItems reports whether the item limit leaves room for the added items next to the stored ones.
+func (p *Plan) AllowsItems(stored, added int64) bool {
+	return p.Limits.MaxItems <= 0 || stored+added <= p.Limits.MaxItems
+}
+
+// AllowsFileSize reports whether a user may store a file of size bytes.
+func (p *Plan) AllowsFileSize(size int64) bool {
+	return p.Limits.MaxFileSize <= 0 || size <= p.Limits.MaxFileSize
+}
+
+// AllowsOrgSize reports whether a user may share credentials with a group of the given number of members.
+func (p *Plan) AllowsOrgSize(members int) bool {
+	return p.Limits.MaxOrgSize <= 0 || members <= p.Limits.MaxOrgSize
+}
+
+// Covers reports whether the plan allows everything the other plan allows, so moving from the other plan to
+// this one is not a downgrade.
+func (p *Plan) Covers(other *Plan) bool {
+	return coversLimit(p.Limits.MaxItems, other.Limits.MaxItems) &&
+		coversLimit(p.Limits.MaxFileSize, other.Limits.MaxFileSize) &&
+		(!other.Limits.Sharing ||
+			p.Limits.Sharing && coversLimit(int64(p.Limits.MaxOrgSize), int64(other.Limits.MaxOrgSize)))
+}
+
+// coversLimit reports whether the limit is at least the other one, zero meaning no limit.
+func coversLimit(limit, other int64) bool {
+	return limit <= 0 || (other > 0 && limit >= other)
+}
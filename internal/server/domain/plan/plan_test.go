@@ -0,0 +1,111 @@
+package plan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLookup(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		want   Plan
+		name   string
+		wantOK bool
+	}{
+		{name: NameFree, want: Free, wantOK: true},
+		{name: NamePremium, want: Premium, wantOK: true},
+		{name: "enterprise"},
+		{name: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := Lookup(tt.name)
+
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestPlan_Allows(t *testing.T) {
+	t.Parallel()
+
+	limited := Plan{Name: "limited", Limits: Limits{MaxItems: 3, MaxFileSize: 100, MaxOrgSize: 2}}
+	unlimited := Plan{Name: "unlimited"}
+
+	tests := []struct {
+		plan      Plan
+		name      string
+		stored    int64
+		added     int64
+		fileSize  int64
+		members   int
+		wantItems bool
+		wantFile  bool
+		wantOrg   bool
+	}{
+		{
+			name: "within limits", plan: limited,
+			stored: 1, added: 2, fileSize: 100, members: 2,
+			wantItems: true, wantFile: true, wantOrg: true,
+		},
+		{
+			name: "over limits", plan: limited,
+			stored: 3, added: 1, fileSize: 101, members: 3,
+		},
+		{
+			name: "no limits", plan: unlimited,
+			stored: 1 << 20, added: 1, fileSize: 1 << 40, members: 1000,
+			wantItems: true, wantFile: true, wantOrg: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.wantItems, tt.plan.AllowsItems(tt.stored, tt.added))
+			assert.Equal(t, tt.wantFile, tt.plan.AllowsFileSize(tt.fileSize))
+			assert.Equal(t, tt.wantOrg, tt.plan.AllowsOrgSize(tt.members))
+		})
+	}
+}
+
+func TestPlan_Covers(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		plan  Plan
+		other Plan
+		name  string
+		want  bool
+	}{
+		{name: "premium covers free", plan: Premium, other: Free, want: true},
+		{name: "free does not cover premium", plan: Free, other: Premium},
+		{name: "same plan", plan: Free, other: Free, want: true},
+		{name: "unlimited covers everything", plan: Plan{Limits: Limits{Sharing: true}}, other: Premium, want: true},
+		{
+			name:  "smaller item limit",
+			plan:  Plan{Limits: Limits{MaxItems: 10}},
+			other: Plan{Limits: Limits{MaxItems: 20}},
+		},
+		{
+			name:  "limited does not cover unlimited",
+			plan:  Plan{Limits: Limits{MaxFileSize: 1 << 30}},
+			other: Plan{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, tt.plan.Covers(&tt.other))
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package plan
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Statuses of billing subscriptions, named like the Stripe subscription statuses.
+const (
+	// StatusActive marks a paid subscription.
+	StatusActive = "active"
+	// StatusTrialing marks a subscription in its trial period.
+	StatusTrialing = "trialing"
+	// StatusPastDue marks a subscription whose latest payment failed and is being retried.
+	StatusPastDue = "past_due"
+	// StatusCanceled marks an ended subscription.
+	StatusCanceled = "canceled"
+)
+
+// Subscription represents the billing subscription assigning a plan to a user.
+type Subscription struct {
+	// EventAt is the time of the latest billing event applied to the subscription.
+	EventAt time.Time
+	// PeriodEnd is the end of the billing period paid for.
+	PeriodEnd time.Time
+	// GraceUntil is the time the plan kept after a downgrade ends; zero without a pending downgrade.
+	GraceUntil time.Time
+	// UpdatedAt is the time the subscription was last stored.
+	UpdatedAt time.Time
+	// Plan names the plan the subscription grants while it is in good standing.
+	Plan string
+	// Status contains the status of the subscription, such as StatusActive.
+	Status string
+	// CustomerID identifies the customer at the billing provider.
+	CustomerID string
+	// SubscriptionID identifies the subscription at the billing provider.
+	SubscriptionID string
+	// GracePlan names the plan kept until GraceUntil after a downgrade.
+	GracePlan string
+	// UserID identifies the subscribed user.
+	UserID uuid.UUID
+	// OverQuota reports whether the user stores more items than the plan left after a downgrade allows.
+	OverQuota bool
+}
+
+// Grants reports whether the subscription is in good standing and grants its plan. Past due subscriptions keep
+// their plan while the billing provider retries the payment.
+func (s *Subscription) Grants() bool {
+	switch s.Status {
+	case StatusActive, StatusTrialing, StatusPastDue:
+		return true
+	default:
+		return false
+	}
+}
+
+// PlanAt returns the name of the plan the subscription gives at the moment: the plan kept after a downgrade
+// until the paid period ends, then the plan of the subscription while it grants one. It returns an empty name
+// when the subscription gives no plan.
+func (s *Subscription) PlanAt(at time.Time) string {
+	if s.GracePlan != "" && at.Before(s.GraceUntil) {
+		return s.GracePlan
+	}
+	if s.Grants() {
+		return s.Plan
+	}
+	return ""
+}
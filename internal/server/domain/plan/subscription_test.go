@@ -0,0 +1,51 @@
+package plan
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubscription_PlanAt(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		want string
+		sub  Subscription
+	}{
+		{name: "active", sub: Subscription{Plan: NamePremium, Status: StatusActive}, want: NamePremium},
+		{name: "trialing", sub: Subscription{Plan: NamePremium, Status: StatusTrialing}, want: NamePremium},
+		{name: "past due", sub: Subscription{Plan: NamePremium, Status: StatusPastDue}, want: NamePremium},
+		{name: "canceled", sub: Subscription{Plan: NamePremium, Status: StatusCanceled}},
+		{name: "unpaid", sub: Subscription{Plan: NamePremium, Status: "unpaid"}},
+		{
+			name: "downgrade in grace",
+			sub: Subscription{
+				Plan: NameFree, Status: StatusActive, GracePlan: NamePremium, GraceUntil: now.Add(time.Hour),
+			},
+			want: NamePremium,
+		},
+		{
+			name: "cancellation in grace",
+			sub:  Subscription{Status: StatusCanceled, GracePlan: NamePremium, GraceUntil: now.Add(time.Hour)},
+			want: NamePremium,
+		},
+		{
+			name: "grace over",
+			sub:  Subscription{Plan: NameFree, Status: StatusActive, GracePlan: NamePremium, GraceUntil: now},
+			want: NameFree,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, tt.sub.PlanAt(now))
+		})
+	}
+}
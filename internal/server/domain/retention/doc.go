@@ -0,0 +1,6 @@
+// Package retention provides data retention policy domain entities and business rules
+// for the AegisVaultKeeper server.
+//
+// This package implements the rules deciding how many replaced item versions and how many days of item
+// reveal records are kept, and the overrides applying other rules to the members of directory groups.
+package retention
@@ -0,0 +1,18 @@
+package retention
+
+import "errors"
+
+// Retention policy domain error definitions.
+var (
+	// ErrNewOverrideParamsValidation indicates that retention override creation parameters failed validation.
+	ErrNewOverrideParamsValidation = errors.New("new retention override parameters validation failed")
+
+	// ErrIncorrectGroupID indicates that the retention override does not name a group.
+	ErrIncorrectGroupID = errors.New("incorrect retention override group ID")
+
+	// ErrIncorrectKeepVersions indicates that the number of kept versions is negative or too large.
+	ErrIncorrectKeepVersions = errors.New("incorrect number of kept versions")
+
+	// ErrIncorrectKeepAuditDays indicates that the number of days audit records are kept is negative or too large.
+	ErrIncorrectKeepAuditDays = errors.New("incorrect number of days audit records are kept")
+)
@@ -0,0 +1,114 @@
+package retention
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Limits of the retention rules.
+const (
+	// maxKeepVersions limits the number of replaced versions kept per item.
+	maxKeepVersions = 10000
+	// maxKeepAuditDays limits the number of days item reveal records are kept, about a hundred years.
+	maxKeepAuditDays = 36500
+)
+
+// Policy contains the data retention rules; a zero rule keeps the data forever.
+type Policy struct {
+	// KeepVersions specifies how many replaced versions of every item are kept.
+	KeepVersions int
+	// KeepAuditDays specifies for how many days item reveal records are kept.
+	KeepAuditDays int
+}
+
+// IsZero reports whether the policy keeps all data forever.
+func (p Policy) IsZero() bool {
+	return p.KeepVersions == 0 && p.KeepAuditDays == 0
+}
+
+// Merge returns the policy keeping the data either policy keeps: a rule keeping data forever wins, otherwise
+// the longer one does. It resolves the policy of users in several groups with overrides.
+func (p Policy) Merge(other Policy) Policy {
+	return Policy{
+		KeepVersions:  mergeRule(p.KeepVersions, other.KeepVersions),
+		KeepAuditDays: mergeRule(p.KeepAuditDays, other.KeepAuditDays),
+	}
+}
+
+// AuditCutoff returns the moment item reveal records before which are purged at now, or the zero time when the
+// policy keeps them forever.
+func (p Policy) AuditCutoff(now time.Time) time.Time {
+	if p.KeepAuditDays == 0 {
+		return time.Time{}
+	}
+	return now.AddDate(0, 0, -p.KeepAuditDays)
+}
+
+// mergeRule returns the rule keeping more data of the two, where zero keeps everything.
+func mergeRule(a, b int) int {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return max(a, b)
+}
+
+// Override applies a retention policy to the members of a directory group instead of the configured defaults.
+type Override struct {
+	// UpdatedAt contains the timestamp when the override was last set.
+	UpdatedAt time.Time
+	// Policy contains the rules applied to the members of the group.
+	Policy
+	// GroupID identifies the group the override applies to.
+	GroupID uuid.UUID
+}
+
+// NewOverride creates a new retention override with the provided parameters after validation.
+func NewOverride(params NewOverrideParams) (*Override, error) {
+	if err := params.Validate(); err != nil {
+		return nil, errors.Join(ErrNewOverrideParamsValidation, err)
+	}
+
+	return &Override{
+		GroupID:   params.GroupID,
+		Policy:    params.Policy,
+		UpdatedAt: time.Now(),
+	}, nil
+}
+
+// NewOverrideParams contains parameters for creating a new retention override.
+type NewOverrideParams struct {
+	// Policy contains the rules applied to the members of the group.
+	Policy Policy
+	// GroupID identifies the group the override applies to (required).
+	GroupID uuid.UUID
+}
+
+// Validate checks that the retention override creation parameters are valid.
+func (p *NewOverrideParams) Validate() error {
+	var errs []error
+	if p.GroupID == uuid.Nil {
+		errs = append(errs, ErrIncorrectGroupID)
+	}
+	if p.Policy.KeepVersions < 0 || p.Policy.KeepVersions > maxKeepVersions {
+		errs = append(errs, ErrIncorrectKeepVersions)
+	}
+	if p.Policy.KeepAuditDays < 0 || p.Policy.KeepAuditDays > maxKeepAuditDays {
+		errs = append(errs, ErrIncorrectKeepAuditDays)
+	}
+	return errors.Join(errs...)
+}
+
+// Counts contains the amount of data a retention policy purges.
+type Counts struct {
+	// Versions is the number of replaced item versions.
+	Versions int64
+	// AuditRecords is the number of item reveal records.
+	AuditRecords int64
+}
+
+// Add returns the sum of the counts.
+func (c Counts) Add(other Counts) Counts {
+	return Counts{Versions: c.Versions + other.Versions, AuditRecords: c.AuditRecords + other.AuditRecords}
+}
@@ -0,0 +1,117 @@
+package retention
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewOverride(t *testing.T) {
+	t.Parallel()
+
+	groupID := uuid.New()
+
+	tests := []struct {
+		wantErr error
+		name    string
+		params  NewOverrideParams
+	}{
+		{
+			name:   "valid override",
+			params: NewOverrideParams{GroupID: groupID, Policy: Policy{KeepVersions: 5, KeepAuditDays: 30}},
+		},
+		{
+			name:   "keep everything",
+			params: NewOverrideParams{GroupID: groupID},
+		},
+		{
+			name:    "missing group",
+			params:  NewOverrideParams{Policy: Policy{KeepVersions: 5}},
+			wantErr: ErrIncorrectGroupID,
+		},
+		{
+			name:    "negative versions",
+			params:  NewOverrideParams{GroupID: groupID, Policy: Policy{KeepVersions: -1}},
+			wantErr: ErrIncorrectKeepVersions,
+		},
+		{
+			name:    "too many audit days",
+			params:  NewOverrideParams{GroupID: groupID, Policy: Policy{KeepAuditDays: maxKeepAuditDays + 1}},
+			wantErr: ErrIncorrectKeepAuditDays,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			o, err := NewOverride(tt.params)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, ErrNewOverrideParamsValidation)
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, o)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.params.GroupID, o.GroupID)
+			assert.Equal(t, tt.params.Policy, o.Policy)
+			assert.False(t, o.UpdatedAt.IsZero())
+		})
+	}
+}
+
+func TestPolicy_Merge(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		a    Policy
+		b    Policy
+		want Policy
+	}{
+		{
+			name: "longer rules win",
+			a:    Policy{KeepVersions: 5, KeepAuditDays: 90},
+			b:    Policy{KeepVersions: 10, KeepAuditDays: 30},
+			want: Policy{KeepVersions: 10, KeepAuditDays: 90},
+		},
+		{
+			name: "keeping forever wins",
+			a:    Policy{KeepVersions: 5, KeepAuditDays: 90},
+			b:    Policy{KeepAuditDays: 30},
+			want: Policy{KeepAuditDays: 90},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, tt.a.Merge(tt.b))
+			assert.Equal(t, tt.want, tt.b.Merge(tt.a))
+		})
+	}
+}
+
+func TestPolicy_AuditCutoff(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+
+	assert.True(t, Policy{KeepVersions: 5}.AuditCutoff(now).IsZero())
+	assert.Equal(t, time.Date(2026, 9, 15, 12, 0, 0, 0, time.UTC), Policy{KeepAuditDays: 30}.AuditCutoff(now))
+}
+
+func TestCounts_Add(t *testing.T) {
+	t.Parallel()
+
+	got := Counts{Versions: 1, AuditRecords: 2}.Add(Counts{Versions: 3, AuditRecords: 4})
+
+	assert.Equal(t, Counts{Versions: 4, AuditRecords: 6}, got)
+	assert.True(t, Policy{}.IsZero())
+	assert.False(t, Policy{KeepAuditDays: 1}.IsZero())
+}
@@ -0,0 +1,6 @@
+// Package rotation provides credential rotation domain entities and business rules for the AegisVaultKeeper
+// server.
+//
+// This package implements the rotation policies binding a credential to the external system its secret
+// belongs to, and the schedule on which the secret is replaced in that system and in the vault.
+package rotation
@@ -0,0 +1,17 @@
+package rotation
+
+import "errors"
+
+// Rotation domain error definitions.
+var (
+	// ErrNewPolicyParamsValidation indicates that rotation policy parameters failed validation.
+	ErrNewPolicyParamsValidation = errors.New("new rotation policy parameters validation failed")
+	// ErrIncorrectCredential indicates that the credential of a rotation policy is not specified.
+	ErrIncorrectCredential = errors.New("incorrect rotation policy credential")
+	// ErrIncorrectKind indicates that the rotator kind is not supported.
+	ErrIncorrectKind = errors.New("incorrect rotator kind")
+	// ErrIncorrectSettings indicates that the settings are missing or malformed for the rotator kind.
+	ErrIncorrectSettings = errors.New("incorrect rotator settings")
+	// ErrIncorrectInterval indicates that the rotation interval is negative or shorter than the minimum.
+	ErrIncorrectInterval = errors.New("incorrect rotation interval")
+)
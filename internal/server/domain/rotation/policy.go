@@ -0,0 +1,260 @@
+package rotation
+
+import (
+	"errors"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Kind names the external system a rotator replaces secrets in.
+type Kind string
+
+// Supported rotator kinds.
+const (
+	// KindPostgres changes the password of a PostgreSQL role; the credential login is the role name.
+	KindPostgres Kind = "postgres"
+	// KindAWSIAM replaces an AWS IAM access key; the credential login is the access key ID and the password
+	// the secret access key.
+	KindAWSIAM Kind = "aws_iam"
+	// KindWebhook hands a generated password to a webhook that applies it to the target system.
+	KindWebhook Kind = "webhook"
+)
+
+// Kinds lists the supported rotator kinds.
+var Kinds = []Kind{KindPostgres, KindAWSIAM, KindWebhook}
+
+// MinInterval is the shortest interval scheduled rotations may run at.
+const MinInterval = time.Hour
+
+// maxLastErrorLength limits the length of the stored failure reason of the last rotation.
+const maxLastErrorLength = 255
+
+// Default settings applied when they are omitted.
+const (
+	// defaultPostgresPort is the port PostgreSQL servers listen on by default.
+	defaultPostgresPort = 5432
+	// defaultPostgresSSLMode requires TLS without verifying the server certificate.
+	defaultPostgresSSLMode = "require"
+	// defaultAWSRegion is the region AWS IAM requests are signed for.
+	defaultAWSRegion = "us-east-1"
+	// defaultAWSIAMEndpoint is the global AWS IAM endpoint.
+	defaultAWSIAMEndpoint = "https://iam.amazonaws.com"
+)
+
+// postgresSSLModes lists the accepted PostgreSQL TLS modes.
+var postgresSSLModes = []string{"disable", "require", "verify-ca", "verify-full"}
+
+// iamUserNamePattern matches AWS IAM user names.
+var iamUserNamePattern = regexp.MustCompile(`^[\w+=,.@-]{1,64}$`)
+
+// awsRegionPattern matches AWS region names such as eu-central-1.
+var awsRegionPattern = regexp.MustCompile(`^[a-z]{2}(-[a-z]+)+-[0-9]$`)
+
+// Settings addresses the external system the secret of a credential belongs to.
+// Only the settings of the policy kind are kept.
+type Settings struct {
+	// Host contains the PostgreSQL server host (postgres, required).
+	Host string
+	// Database contains the database connected to (postgres, required).
+	Database string
+	// SSLMode contains the TLS mode of the connection (postgres, defaults to require).
+	SSLMode string
+	// UserName contains the IAM user the access key belongs to (aws_iam, required).
+	UserName string
+	// Region contains the region requests are signed for (aws_iam, defaults to us-east-1).
+	Region string
+	// Endpoint contains the IAM API URL (aws_iam, defaults to the global endpoint).
+	Endpoint string
+	// URL contains the HTTPS URL of the webhook (webhook, required).
+	URL string
+	// Port contains the PostgreSQL server port (postgres, defaults to 5432).
+	Port int
+}
+
+// Secret contains the login and password of a credential.
+type Secret struct {
+	// Login contains the credential login/username.
+	Login string
+	// Password contains the credential password.
+	Password string
+}
+
+// Policy binds a credential to the external system its secret is rotated in.
+type Policy struct {
+	// CreatedAt contains the timestamp when the policy was created.
+	CreatedAt time.Time
+	// LastRotatedAt contains the timestamp of the last successful rotation; zero if never rotated.
+	LastRotatedAt time.Time
+	// NextRotationAt contains the moment the next scheduled rotation is due; zero when rotated on demand only.
+	NextRotationAt time.Time
+	// LastError contains the failure reason of the last rotation; empty if it succeeded.
+	LastError string
+	// Kind names the rotator replacing the secret.
+	Kind Kind
+	// Settings addresses the external system.
+	Settings Settings
+	// Interval specifies how often the secret is rotated; zero rotates on demand only.
+	Interval time.Duration
+	// CredentialID identifies the rotated credential.
+	CredentialID uuid.UUID
+	// UserID identifies the owner of the credential.
+	UserID uuid.UUID
+}
+
+// NewPolicy creates a new rotation policy with validation and normalized settings.
+func NewPolicy(params NewPolicyParams) (*Policy, error) {
+	settings, err := params.validate()
+	if err != nil {
+		return nil, errors.Join(ErrNewPolicyParamsValidation, err)
+	}
+
+	now := time.Now()
+	p := Policy{
+		CredentialID: params.CredentialID,
+		UserID:       params.UserID,
+		Kind:         params.Kind,
+		Settings:     settings,
+		Interval:     params.Interval,
+		CreatedAt:    now,
+	}
+	p.schedule(now)
+	return &p, nil
+}
+
+// Due reports whether a scheduled rotation is due at the specified moment.
+func (p *Policy) Due(at time.Time) bool {
+	return p.Interval > 0 && !p.NextRotationAt.After(at)
+}
+
+// Rotated records a successful rotation at the specified moment and schedules the next one.
+func (p *Policy) Rotated(at time.Time) {
+	p.LastRotatedAt = at
+	p.LastError = ""
+	p.schedule(at)
+}
+
+// Failed records a failed rotation at the specified moment and schedules the next attempt
+// after the regular interval.
+func (p *Policy) Failed(at time.Time, reason string) {
+	if len(reason) > maxLastErrorLength {
+		reason = reason[:maxLastErrorLength]
+	}
+	p.LastError = reason
+	p.schedule(at)
+}
+
+// schedule sets the moment of the next scheduled rotation counted from the specified moment.
+func (p *Policy) schedule(from time.Time) {
+	p.NextRotationAt = time.Time{}
+	if p.Interval > 0 {
+		p.NextRotationAt = from.Add(p.Interval)
+	}
+}
+
+// NewPolicyParams contains parameters for creating a new rotation policy.
+type NewPolicyParams struct {
+	// Kind names the rotator replacing the secret (required).
+	Kind Kind
+	// Settings addresses the external system; required settings depend on the kind.
+	Settings Settings
+	// Interval specifies how often the secret is rotated; zero rotates on demand only.
+	Interval time.Duration
+	// CredentialID identifies the rotated credential (required).
+	CredentialID uuid.UUID
+	// UserID identifies the owner of the credential.
+	UserID uuid.UUID
+}
+
+// Validate checks that the rotation policy parameters are valid.
+func (p *NewPolicyParams) Validate() error {
+	_, err := p.validate()
+	return err
+}
+
+// validate checks the parameters and returns the settings of the kind with defaults applied.
+func (p *NewPolicyParams) validate() (Settings, error) {
+	var errs []error
+	if p.CredentialID == uuid.Nil {
+		errs = append(errs, ErrIncorrectCredential)
+	}
+	if p.Interval < 0 || (p.Interval > 0 && p.Interval < MinInterval) {
+		errs = append(errs, ErrIncorrectInterval)
+	}
+	settings, err := normalizeSettings(p.Kind, p.Settings)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	return settings, errors.Join(errs...)
+}
+
+// normalizeSettings validates the settings of the rotator kind and returns them with defaults applied,
+// dropping the settings of other kinds.
+func normalizeSettings(kind Kind, s Settings) (Settings, error) {
+	switch kind {
+	case KindPostgres:
+		return normalizePostgres(s)
+	case KindAWSIAM:
+		return normalizeAWSIAM(s)
+	case KindWebhook:
+		u, err := url.Parse(strings.TrimSpace(s.URL))
+		if err != nil || u.Scheme != "https" || u.Host == "" {
+			return Settings{}, ErrIncorrectSettings
+		}
+		return Settings{URL: u.String()}, nil
+	default:
+		return Settings{}, ErrIncorrectKind
+	}
+}
+
+// normalizePostgres validates and normalizes PostgreSQL connection settings.
+func normalizePostgres(s Settings) (Settings, error) {
+	result := Settings{
+		Host:     strings.TrimSpace(s.Host),
+		Port:     s.Port,
+		Database: strings.TrimSpace(s.Database),
+		SSLMode:  strings.TrimSpace(s.SSLMode),
+	}
+	if result.Port == 0 {
+		result.Port = defaultPostgresPort
+	}
+	if result.SSLMode == "" {
+		result.SSLMode = defaultPostgresSSLMode
+	}
+	switch {
+	case result.Host == "" || strings.ContainsAny(result.Host, " /?#"),
+		result.Port < 1 || result.Port > 65535,
+		result.Database == "",
+		!slices.Contains(postgresSSLModes, result.SSLMode):
+		return Settings{}, ErrIncorrectSettings
+	}
+	return result, nil
+}
+
+// normalizeAWSIAM validates and normalizes AWS IAM API settings.
+func normalizeAWSIAM(s Settings) (Settings, error) {
+	result := Settings{
+		UserName: strings.TrimSpace(s.UserName),
+		Region:   strings.TrimSpace(s.Region),
+		Endpoint: strings.TrimSpace(s.Endpoint),
+	}
+	if result.Region == "" {
+		result.Region = defaultAWSRegion
+	}
+	if result.Endpoint == "" {
+		result.Endpoint = defaultAWSIAMEndpoint
+	}
+	if !iamUserNamePattern.MatchString(result.UserName) || !awsRegionPattern.MatchString(result.Region) {
+		return Settings{}, ErrIncorrectSettings
+	}
+	u, err := url.Parse(result.Endpoint)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return Settings{}, ErrIncorrectSettings
+	}
+	result.Endpoint = strings.TrimRight(u.String(), "/")
+	return result, nil
+}
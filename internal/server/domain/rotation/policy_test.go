@@ -0,0 +1,205 @@
+package rotation
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPolicy(t *testing.T) {
+	t.Parallel()
+
+	credentialID, userID := uuid.New(), uuid.New()
+
+	tests := []struct {
+		wantErr      error
+		name         string
+		params       NewPolicyParams
+		wantSettings Settings
+	}{
+		{
+			name: "postgres with defaults",
+			params: NewPolicyParams{
+				Kind:     KindPostgres,
+				Settings: Settings{Host: " db.internal ", Database: "app", URL: "https://ignored.example.com"},
+				Interval: 24 * time.Hour,
+			},
+			wantSettings: Settings{Host: "db.internal", Port: 5432, Database: "app", SSLMode: "require"},
+		},
+		{
+			name: "postgres with explicit settings",
+			params: NewPolicyParams{
+				Kind:     KindPostgres,
+				Settings: Settings{Host: "10.0.0.5", Port: 6432, Database: "app", SSLMode: "verify-full"},
+			},
+			wantSettings: Settings{Host: "10.0.0.5", Port: 6432, Database: "app", SSLMode: "verify-full"},
+		},
+		{
+			name:         "aws iam with defaults",
+			params:       NewPolicyParams{Kind: KindAWSIAM, Settings: Settings{UserName: "ci-deployer"}},
+			wantSettings: Settings{UserName: "ci-deployer", Region: "us-east-1", Endpoint: "https://iam.amazonaws.com"},
+		},
+		{
+			name: "aws iam with custom endpoint",
+			params: NewPolicyParams{
+				Kind: KindAWSIAM,
+				Settings: Settings{
+					UserName: "ci-deployer",
+					Region:   "cn-north-1",
+					Endpoint: "https://iam.cn-north-1.amazonaws.com.cn/",
+				},
+			},
+			wantSettings: Settings{
+				UserName: "ci-deployer",
+				Region:   "cn-north-1",
+				Endpoint: "https://iam.cn-north-1.amazonaws.com.cn",
+			},
+		},
+		{
+			name:         "webhook",
+			params:       NewPolicyParams{Kind: KindWebhook, Settings: Settings{URL: "https://rotate.example.com/hook"}},
+			wantSettings: Settings{URL: "https://rotate.example.com/hook"},
+		},
+		{
+			name:    "unknown kind",
+			params:  NewPolicyParams{Kind: "ldap"},
+			wantErr: ErrIncorrectKind,
+		},
+		{
+			name:    "postgres without database",
+			params:  NewPolicyParams{Kind: KindPostgres, Settings: Settings{Host: "db.internal"}},
+			wantErr: ErrIncorrectSettings,
+		},
+		{
+			name: "postgres with unknown TLS mode",
+			params: NewPolicyParams{
+				Kind:     KindPostgres,
+				Settings: Settings{Host: "db.internal", Database: "app", SSLMode: "prefer"},
+			},
+			wantErr: ErrIncorrectSettings,
+		},
+		{
+			name:    "aws iam with malformed user",
+			params:  NewPolicyParams{Kind: KindAWSIAM, Settings: Settings{UserName: "ci deployer"}},
+			wantErr: ErrIncorrectSettings,
+		},
+		{
+			name: "aws iam over plain HTTP",
+			params: NewPolicyParams{
+				Kind:     KindAWSIAM,
+				Settings: Settings{UserName: "ci-deployer", Endpoint: "http://iam.amazonaws.com"},
+			},
+			wantErr: ErrIncorrectSettings,
+		},
+		{
+			name:    "webhook over plain HTTP",
+			params:  NewPolicyParams{Kind: KindWebhook, Settings: Settings{URL: "http://rotate.example.com/hook"}},
+			wantErr: ErrIncorrectSettings,
+		},
+		{
+			name: "interval below minimum",
+			params: NewPolicyParams{
+				Kind:     KindWebhook,
+				Settings: Settings{URL: "https://rotate.example.com/hook"},
+				Interval: time.Minute,
+			},
+			wantErr: ErrIncorrectInterval,
+		},
+		{
+			name: "negative interval",
+			params: NewPolicyParams{
+				Kind:     KindWebhook,
+				Settings: Settings{URL: "https://rotate.example.com/hook"},
+				Interval: -time.Hour,
+			},
+			wantErr: ErrIncorrectInterval,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			params := tt.params
+			params.CredentialID, params.UserID = credentialID, userID
+			got, err := NewPolicy(params)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, ErrNewPolicyParamsValidation)
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, got)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, credentialID, got.CredentialID)
+			assert.Equal(t, userID, got.UserID)
+			assert.Equal(t, tt.params.Kind, got.Kind)
+			assert.Equal(t, tt.wantSettings, got.Settings)
+			assert.False(t, got.CreatedAt.IsZero())
+			if tt.params.Interval > 0 {
+				assert.Equal(t, got.CreatedAt.Add(tt.params.Interval), got.NextRotationAt)
+			} else {
+				assert.True(t, got.NextRotationAt.IsZero())
+			}
+		})
+	}
+}
+
+func TestNewPolicy_MissingCredential(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewPolicy(NewPolicyParams{Kind: KindWebhook, Settings: Settings{URL: "https://rotate.example.com"}})
+
+	require.ErrorIs(t, err, ErrIncorrectCredential)
+}
+
+func TestPolicy_Due(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		policy Policy
+		want   bool
+	}{
+		{name: "due", policy: Policy{Interval: time.Hour, NextRotationAt: now}, want: true},
+		{name: "overdue", policy: Policy{Interval: time.Hour, NextRotationAt: now.Add(-time.Minute)}, want: true},
+		{name: "not yet due", policy: Policy{Interval: time.Hour, NextRotationAt: now.Add(time.Minute)}},
+		{name: "on demand only", policy: Policy{NextRotationAt: now.Add(-time.Hour)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, tt.policy.Due(now))
+		})
+	}
+}
+
+func TestPolicy_RotatedAndFailed(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	p := Policy{Interval: 24 * time.Hour}
+
+	p.Failed(now, strings.Repeat("x", 300))
+	assert.Len(t, p.LastError, 255)
+	assert.True(t, p.LastRotatedAt.IsZero())
+	assert.Equal(t, now.Add(24*time.Hour), p.NextRotationAt)
+
+	later := now.Add(time.Hour)
+	p.Rotated(later)
+	assert.Empty(t, p.LastError)
+	assert.Equal(t, later, p.LastRotatedAt)
+	assert.Equal(t, later.Add(24*time.Hour), p.NextRotationAt)
+
+	onDemand := Policy{}
+	onDemand.Rotated(now)
+	assert.True(t, onDemand.NextRotationAt.IsZero())
+}
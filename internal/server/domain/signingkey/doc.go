@@ -0,0 +1,6 @@
+// Package signingkey provides request signing key domain entities and business rules
+// for the AegisVaultKeeper server.
+//
+// This package implements core domain logic for the per-client HMAC keys users sign
+// high-privilege requests with, so a leaked access token alone cannot replay them.
+package signingkey
@@ -0,0 +1,15 @@
+package signingkey
+
+import "errors"
+
+// Signing key domain error definitions.
+var (
+	// ErrNewSigningKeyParamsValidation indicates that signing key creation parameters failed validation.
+	ErrNewSigningKeyParamsValidation = errors.New("new signing key parameters validation failed")
+
+	// ErrIncorrectName indicates that the signing key name is empty or too long.
+	ErrIncorrectName = errors.New("incorrect signing key name")
+
+	// ErrIncorrectSecret indicates that the signing key secret is too short.
+	ErrIncorrectSecret = errors.New("incorrect signing key secret")
+)
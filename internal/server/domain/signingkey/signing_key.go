@@ -0,0 +1,79 @@
+package signingkey
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/securebytes"
+	"github.com/google/uuid"
+)
+
+// Signing key limits.
+const (
+	// maxNameLen limits the length of signing key names in characters.
+	maxNameLen = 64
+	// MinSecretLen defines the minimum length of signing key secrets in bytes.
+	MinSecretLen = 32
+)
+
+// SigningKey is an HMAC key a client of the user signs high-privilege requests with.
+type SigningKey struct {
+	// CreatedAt contains the timestamp when the key was created.
+	CreatedAt time.Time
+	// Name labels the client holding the key.
+	Name string
+	// Secret contains the HMAC key material.
+	Secret []byte
+	// ID uniquely identifies this key and is sent with every signature made with it.
+	ID uuid.UUID
+	// UserID identifies the user who owns this key.
+	UserID uuid.UUID
+}
+
+// NewSigningKey creates a new signing key with the provided parameters after validation.
+func NewSigningKey(params NewSigningKeyParams) (*SigningKey, error) {
+	if err := params.Validate(); err != nil {
+		return nil, errors.Join(ErrNewSigningKeyParamsValidation, err)
+	}
+
+	return &SigningKey{
+		ID:        uuid.New(),
+		UserID:    params.UserID,
+		Name:      strings.TrimSpace(params.Name),
+		Secret:    params.Secret,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+// Wipe zeroes the key material. It is safe to call on a nil key.
+func (k *SigningKey) Wipe() {
+	if k == nil {
+		return
+	}
+	securebytes.Wipe(k.Secret)
+}
+
+// NewSigningKeyParams contains parameters for creating a new signing key.
+type NewSigningKeyParams struct {
+	// Name labels the client holding the key (required).
+	Name string
+	// Secret contains the HMAC key material of at least MinSecretLen bytes (required).
+	Secret []byte
+	// UserID identifies the user who owns this key.
+	UserID uuid.UUID
+}
+
+// Validate checks that the signing key creation parameters are valid.
+func (p *NewSigningKeyParams) Validate() error {
+	var errs []error
+	name := strings.TrimSpace(p.Name)
+	if name == "" || utf8.RuneCountInString(name) > maxNameLen {
+		errs = append(errs, ErrIncorrectName)
+	}
+	if len(p.Secret) < MinSecretLen {
+		errs = append(errs, ErrIncorrectSecret)
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,88 @@
+package signingkey
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSigningKey(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	secret := bytes.Repeat([]byte{0x42}, MinSecretLen)
+
+	tests := []struct {
+		wantErr  error
+		name     string
+		wantName string
+		params   NewSigningKeyParams
+	}{
+		{
+			name:     "valid key",
+			params:   NewSigningKeyParams{Name: "backup-cli", Secret: secret, UserID: userID},
+			wantName: "backup-cli",
+		},
+		{
+			name:     "name trimmed",
+			params:   NewSigningKeyParams{Name: "  laptop  ", Secret: secret, UserID: userID},
+			wantName: "laptop",
+		},
+		{
+			name:     "longest name",
+			params:   NewSigningKeyParams{Name: strings.Repeat("я", 64), Secret: secret, UserID: userID},
+			wantName: strings.Repeat("я", 64),
+		},
+		{
+			name:    "blank name",
+			params:  NewSigningKeyParams{Name: "   ", Secret: secret, UserID: userID},
+			wantErr: ErrIncorrectName,
+		},
+		{
+			name:    "too long name",
+			params:  NewSigningKeyParams{Name: strings.Repeat("a", 65), Secret: secret, UserID: userID},
+			wantErr: ErrIncorrectName,
+		},
+		{
+			name:    "short secret",
+			params:  NewSigningKeyParams{Name: "laptop", Secret: secret[:MinSecretLen-1], UserID: userID},
+			wantErr: ErrIncorrectSecret,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			k, err := NewSigningKey(tt.params)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, ErrNewSigningKeyParamsValidation)
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, k)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotEqual(t, uuid.Nil, k.ID)
+			assert.Equal(t, userID, k.UserID)
+			assert.Equal(t, tt.wantName, k.Name)
+			assert.Equal(t, secret, k.Secret)
+			assert.False(t, k.CreatedAt.IsZero())
+		})
+	}
+}
+
+func TestSigningKey_Wipe(t *testing.T) {
+	t.Parallel()
+
+	k := &SigningKey{Secret: []byte("secret key material")}
+
+	k.Wipe()
+
+	assert.Equal(t, make([]byte, len("secret key material")), k.Secret)
+	assert.NotPanics(t, func() { (*SigningKey)(nil).Wipe() })
+}
@@ -0,0 +1,5 @@
+// Package stats provides usage statistics domain entities for the AegisVaultKeeper server.
+//
+// This package implements the periodic rollups of aggregate instance usage administrators review over time:
+// user activity, item counts, storage usage and request volume. Rollups never identify single users.
+package stats
@@ -0,0 +1,98 @@
+package stats
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Activity windows of the active user counts.
+const (
+	// DailyWindow is the period of the daily active user count.
+	DailyWindow = 24 * time.Hour
+	// MonthlyWindow is the period of the monthly active user count.
+	MonthlyWindow = 30 * 24 * time.Hour
+)
+
+// Item types counted by rollups.
+const (
+	// ItemCredentials counts stored credentials.
+	ItemCredentials = "credentials"
+	// ItemNotes counts stored notes.
+	ItemNotes = "notes"
+	// ItemBankCards counts stored bank cards.
+	ItemBankCards = "bank_cards"
+	// ItemFiles counts stored files.
+	ItemFiles = "files"
+)
+
+// StorageTier groups users by the bytes of file content they store.
+type StorageTier struct {
+	// Name identifies the tier.
+	Name string
+	// Below is the exclusive upper bound of stored bytes of the tier; zero for the unbounded last tier.
+	Below int64
+}
+
+// StorageTiers lists the storage usage tiers in ascending order.
+var StorageTiers = []StorageTier{
+	{Name: "under_1mib", Below: 1 << 20},
+	{Name: "under_10mib", Below: 10 << 20},
+	{Name: "under_100mib", Below: 100 << 20},
+	{Name: "under_1gib", Below: 1 << 30},
+	{Name: "over_1gib"},
+}
+
+// TierOf returns the name of the storage tier of a user storing the bytes.
+func TierOf(bytes int64) string {
+	for _, t := range StorageTiers {
+		if t.Below == 0 || bytes < t.Below {
+			return t.Name
+		}
+	}
+	return StorageTiers[len(StorageTiers)-1].Name
+}
+
+// Distribution counts the users of every storage tier, users storing no files included.
+func Distribution(users int64, usage []int64) map[string]int64 {
+	tiers := make(map[string]int64, len(StorageTiers))
+	for _, t := range StorageTiers {
+		tiers[t.Name] = 0
+	}
+	for _, bytes := range usage {
+		tiers[TierOf(bytes)]++
+	}
+	tiers[StorageTiers[0].Name] += max(users-int64(len(usage)), 0)
+	return tiers
+}
+
+// Rollup is a snapshot of the aggregate usage of the instance. The request counters cover the period since the
+// previous rollup of the same server process.
+type Rollup struct {
+	// RolledUpAt contains the moment the snapshot was taken.
+	RolledUpAt time.Time
+	// Items counts the stored items by item type.
+	Items map[string]int64
+	// StorageTiers counts the users by storage usage tier.
+	StorageTiers map[string]int64
+	// ID uniquely identifies this rollup.
+	ID uuid.UUID
+	// Users is the number of registered users, decoy vault profiles excluded.
+	Users int64
+	// DailyActiveUsers is the number of users who signed in during the last DailyWindow.
+	DailyActiveUsers int64
+	// MonthlyActiveUsers is the number of users who signed in during the last MonthlyWindow.
+	MonthlyActiveUsers int64
+	// StorageBytes is the total size of stored file content.
+	StorageBytes int64
+	// Requests counts the handled API requests.
+	Requests int64
+	// ClientErrors counts the requests answered with a 4xx status.
+	ClientErrors int64
+	// ServerErrors counts the requests answered with a 5xx status.
+	ServerErrors int64
+	// SyncRequests counts the vault synchronization requests.
+	SyncRequests int64
+	// SyncBytes counts the bytes received and sent by vault synchronization requests.
+	SyncBytes int64
+}
@@ -0,0 +1,47 @@
+package stats
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTierOf(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		want  string
+		bytes int64
+	}{
+		{name: "empty", bytes: 0, want: "under_1mib"},
+		{name: "just below 1 MiB", bytes: 1<<20 - 1, want: "under_1mib"},
+		{name: "exactly 1 MiB", bytes: 1 << 20, want: "under_10mib"},
+		{name: "50 MiB", bytes: 50 << 20, want: "under_100mib"},
+		{name: "500 MiB", bytes: 500 << 20, want: "under_1gib"},
+		{name: "exactly 1 GiB", bytes: 1 << 30, want: "over_1gib"},
+		{name: "1 TiB", bytes: 1 << 40, want: "over_1gib"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, TierOf(tt.bytes))
+		})
+	}
+}
+
+func TestDistribution(t *testing.T) {
+	t.Parallel()
+
+	got := Distribution(5, []int64{100, 20 << 20, 2 << 30})
+
+	assert.Equal(t, map[string]int64{
+		"under_1mib":   3,
+		"under_10mib":  0,
+		"under_100mib": 1,
+		"under_1gib":   0,
+		"over_1gib":    1,
+	}, got)
+}
@@ -0,0 +1,6 @@
+// Package dryrun marks request contexts whose changes are rolled back.
+//
+// The delivery layer runs dry-run requests in a transaction that never commits, and
+// application services and recorders read the mark back to skip effects outside the
+// database, which the rollback cannot undo.
+package dryrun
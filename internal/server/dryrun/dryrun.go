@@ -0,0 +1,23 @@
+package dryrun
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrUnsupported indicates that the storage cannot roll back the changes of a dry run.
+var ErrUnsupported = errors.New("dry run not supported by the storage")
+
+// ctxKey is the context key under which the dry-run mark is stored.
+type ctxKey struct{}
+
+// With returns a copy of ctx marked as a dry run.
+func With(ctx context.Context) context.Context {
+	return context.WithValue(ctx, ctxKey{}, true)
+}
+
+// Enabled reports whether ctx belongs to a dry run.
+func Enabled(ctx context.Context) bool {
+	enabled, _ := ctx.Value(ctxKey{}).(bool)
+	return enabled
+}
@@ -0,0 +1,29 @@
+package dryrun
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnabled(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		ctx  context.Context
+		name string
+		want bool
+	}{
+		{name: "marked context", ctx: With(context.Background()), want: true},
+		{name: "plain context", ctx: context.Background()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, Enabled(tt.ctx))
+		})
+	}
+}
@@ -10,12 +10,20 @@ func BuildApp() *fx.App {
 	return fx.New(
 		configModule,
 		loggerModule,
+		observabilityModule,
 		repositoryModule,
+		chaosModule,
 		applicationModule,
 		deliveryModule,
+		jobsModule,
+		systemdModule,
 		fx.Invoke(
 			runDatabaseClient,
+			runFieldRebind,
+			runRowSignatureBackfill,
 			runHTTPServer,
+			runJobs,
+			runSystemdNotifier,
 		),
 	)
 }
@@ -1,25 +1,137 @@
 package fxshow
 
 import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	accesscontrolApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/accesscontrol"
+	accesspolicyApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/accesspolicy"
+	acmeaccountApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/acmeaccount"
+	adminapprovalApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/adminapproval"
+	approvalApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/approval"
+	auditexportApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/auditexport"
 	authApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/auth"
+	authzApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/authz"
 	bankcardApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/bankcard"
+	billingApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/billing"
+	botcheckApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/botcheck"
+	bruteforceApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/bruteforce"
+	checkoutApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/checkout"
 	credentialApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/credential"
 	datasyncApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/datasync"
+	devicekeyApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/devicekey"
+	diagnosticsApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/diagnostics"
+	directoryApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/directory"
+	featureApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/feature"
 	filedataApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/filedata"
+	integrityApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/integrity"
+	inviteApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/invite"
+	itemaccessApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/itemaccess"
+	itemorderApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/itemorder"
+	itempathApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/itempath"
+	itempolicyApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/itempolicy"
+	itemtagApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/itemtag"
+	legalholdApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/legalhold"
+	licenseApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/license"
+	machineApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/machine"
+	maintenanceApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/maintenance"
 	noteApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/note"
+	notificationApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/notification"
+	onboardingApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/onboarding"
+	planApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/plan"
+	purgeApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/purge"
+	recordingApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/recording"
+	reportingApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/reporting"
+	retentionApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/retention"
+	rotationApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/rotation"
+	signingkeyApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/signingkey"
+	statsApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/stats"
+	storagegcApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/storagegc"
+	telemetryApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/telemetry"
+	updatecheckApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/updatecheck"
+	vaulthealthApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/vaulthealth"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/buildinfo"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/captcha"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/config"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/crypto"
+	accesspolicyDelivery "github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/accesspolicy"
+	accountDelivery "github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/account"
+	acmeaccountDelivery "github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/acmeaccount"
+	adminDelivery "github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/admin"
+	adminapprovalDelivery "github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/adminapproval"
+	approvalDelivery "github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/approval"
+	auditexportDelivery "github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/auditexport"
 	authDelivery "github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/auth"
 	bankcardDelivery "github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/bankcard"
+	billingDelivery "github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/billing"
+	botcheckDelivery "github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/botcheck"
+	checkoutDelivery "github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/checkout"
 	credentialDelivery "github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/credential"
 	datasyncDelivery "github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/datasync"
+	devicekeyDelivery "github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/devicekey"
+	featureDelivery "github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/feature"
 	filedataDelivery "github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/filedata"
+	inviteDelivery "github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/invite"
+	itemaccessDelivery "github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/itemaccess"
+	itemorderDelivery "github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/itemorder"
+	itempathDelivery "github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/itempath"
+	itempolicyDelivery "github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/itempolicy"
+	itemtagDelivery "github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/itemtag"
+	machineDelivery "github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/machine"
 	middlewareDelivery "github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/middleware"
 	noteDelivery "github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/note"
+	notificationDelivery "github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/notification"
+	onboardingDelivery "github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/onboarding"
+	planDelivery "github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/plan"
+	purgeDelivery "github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/purge"
+	reportDelivery "github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/report"
+	rotationDelivery "github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/rotation"
+	scimDelivery "github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/scim"
+	signingkeyDelivery "github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/signingkey"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/accessrule"
+	authDomain "github.com/gdyunin/aegis-vault-keeper/internal/server/domain/auth"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/authz"
+	notificationDomain "github.com/gdyunin/aegis-vault-keeper/internal/server/domain/notification"
+	onboardingDomain "github.com/gdyunin/aegis-vault-keeper/internal/server/domain/onboarding"
+	rotationDomain "github.com/gdyunin/aegis-vault-keeper/internal/server/domain/rotation"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/geoip"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/license"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/mailer"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/notifier"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/release"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/rotator"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/security"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/seed"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/telemetry"
 	"go.uber.org/fx"
 )
 
+// notificationSendTimeout bounds a single delivery to a chat or push service.
+const notificationSendTimeout = 10 * time.Second
+
+// rotationRequestTimeout bounds a single request of a rotator to an external system.
+const rotationRequestTimeout = 30 * time.Second
+
+// acmeHookTimeout bounds a single call of an ACME account automation hook.
+const acmeHookTimeout = 10 * time.Second
+
+// challengeVerifyTimeout bounds a single CAPTCHA response verification request.
+const challengeVerifyTimeout = 10 * time.Second
+
+// telemetrySendTimeout bounds a single usage ping to the telemetry endpoint.
+const telemetrySendTimeout = 10 * time.Second
+
+// updateCheckTimeout bounds a single release manifest download.
+const updateCheckTimeout = 30 * time.Second
+
+// authFailureLogPerm is the permission of a created authentication failure log, readable by the log group
+// fail2ban usually runs in.
+const authFailureLogPerm = 0o640
+
 // applicationModule provides all application layer dependencies.
 // Configures security components, business logic services, and their interfaces.
 var applicationModule = fx.Module("application",
@@ -28,45 +140,713 @@ var applicationModule = fx.Module("application",
 			return security.NewPasswordHasherVerificator(crypto.HashBcrypt, crypto.VerifyBcrypt)
 		},
 		new(authApp.PasswordHasherVerificator),
+		new(authDomain.PasswordHasher),
 	),
 	provideWithInterfaces[*security.CryptoKeyGenerator](
 		security.NewCryptoKeyGenerator,
 		new(authApp.CryptoKeyGenerator),
+		new(authDomain.CryptoKeyGenerator),
 	),
 	provideWithInterfaces[*security.TokenGenerateValidator](
-		func(cfg *config.AuthConfig) (*security.TokenGenerateValidator, error) {
-			return security.NewTokenGenerateValidator(cfg.MasterKey, cfg.AccessTokenLifeTime)
-		},
+		newTokenGenerateValidator,
 		new(authApp.TokenGenerateValidator),
+		new(purgeApp.TokenGenerateValidator),
+	),
+	fx.Provide(
+		func(cfg *config.AuthzConfig) (*authz.Policy, error) {
+			text := authz.DefaultPolicy
+			if cfg.PolicyFile != "" {
+				b, err := os.ReadFile(cfg.PolicyFile)
+				if err != nil {
+					return nil, fmt.Errorf("failed to read authorization policy: %w", err)
+				}
+				text = string(b)
+			}
+			return authz.Parse(text)
+		},
+	),
+	provideWithInterfaces[*authzApp.Service](
+		authzApp.NewService,
+		new(bankcardApp.Authorizer),
+		new(credentialApp.Authorizer),
+		new(noteApp.Authorizer),
+		new(filedataApp.Authorizer),
+		new(acmeaccountApp.Authorizer),
+		new(purgeApp.Authorizer),
+	),
+	fx.Provide(func(cfg *config.PlanConfig) *planApp.StaticProvider {
+		return planApp.NewStaticProvider(cfg.Default)
+	}),
+	provideWithInterfaces[*planApp.SubscriptionService](
+		func(
+			r planApp.SubscriptionRepository,
+			items planApp.Repository,
+			fallback *planApp.StaticProvider,
+		) *planApp.SubscriptionService {
+			return planApp.NewSubscriptionService(r, items, fallback)
+		},
+		fx.Self(),
+		new(billingApp.PlanSyncer),
+	),
+	fx.Provide(newPlanProvider),
+	provideWithInterfaces[*licenseApp.Service](
+		func(r licenseApp.Repository, audit licenseApp.AuditRecorder, cfg *config.LicenseConfig) *licenseApp.Service {
+			return licenseApp.NewService(r, audit, cfg.PublicKey, cfg.Key, cfg.GracePeriod)
+		},
+		fx.Self(),
+		new(adminDelivery.LicenseService),
+		new(middlewareDelivery.LicenseChecker),
+		new(checkoutApp.LicenseEnforcer),
+	),
+	provideWithInterfaces[*billingApp.Service](
+		func(plans billingApp.PlanSyncer, cfg *config.BillingConfig) *billingApp.Service {
+			return billingApp.NewService(
+				plans, cfg.StripeWebhookSecret, cfg.StripePricePlans, cfg.StripeWebhookTolerance,
+			)
+		},
+		new(billingDelivery.Service),
+	),
+	provideWithInterfaces[*planApp.Service](
+		planApp.NewService,
+		new(bankcardApp.PlanEnforcer),
+		new(credentialApp.PlanEnforcer),
+		new(noteApp.PlanEnforcer),
+		new(filedataApp.PlanEnforcer),
+		new(checkoutApp.PlanEnforcer),
+		new(planDelivery.Service),
 	),
 	provideWithInterfaces[*bankcardApp.Service](
 		bankcardApp.NewService,
 		new(datasyncApp.BankCardService),
 		new(bankcardDelivery.Service),
+		new(vaulthealthApp.BankCardService),
+		new(reportingApp.BankCardService),
+		new(seed.BankCardService),
 	),
 	provideWithInterfaces[*credentialApp.Service](
 		credentialApp.NewService,
 		new(datasyncApp.CredentialService),
 		new(credentialDelivery.Service),
+		new(vaulthealthApp.CredentialService),
+		new(reportingApp.CredentialService),
+		new(checkoutApp.CredentialReader),
+		new(checkoutApp.Rotator),
+		new(rotationApp.CredentialStore),
+		new(machineApp.CredentialReader),
+		new(seed.CredentialService),
 	),
 	provideWithInterfaces[*noteApp.Service](
 		noteApp.NewService,
+		fx.Self(),
 		new(datasyncApp.NoteService),
 		new(noteDelivery.Service),
+		new(vaulthealthApp.NoteService),
+		new(machineApp.NoteReader),
+		new(seed.NoteService),
 	),
 	provideWithInterfaces[*filedataApp.Service](
-		filedataApp.NewService,
+		func(
+			r filedataApp.Repository,
+			fs filedataApp.FileStorageRepository,
+			folders filedataApp.FolderRepository,
+			uow filedataApp.UnitOfWork,
+			authorizer filedataApp.Authorizer,
+			plans filedataApp.PlanEnforcer,
+			policies filedataApp.PolicyEnforcer,
+			tags filedataApp.TagRepository,
+			orders filedataApp.OrderRepository,
+			cfg *config.FileStorageConfig,
+		) *filedataApp.Service {
+			limiter := filedataApp.NewLimiter(filedataApp.TransferLimits{
+				Workers:      cfg.TransferWorkers,
+				MemoryBudget: cfg.TransferMemory,
+				QueueTimeout: cfg.TransferQueueTimeout,
+			})
+			return filedataApp.NewService(
+				r, fs, folders, uow, authorizer, cfg.Quota, limiter, plans, policies, tags, orders,
+			)
+		},
 		new(datasyncApp.FileDataService),
 		new(filedataDelivery.Service),
+		new(vaulthealthApp.FileDataService),
+		new(seed.FileService),
+	),
+	provideWithInterfaces[*notifier.ACMEHook](
+		func() *notifier.ACMEHook {
+			return notifier.NewACMEHook(&http.Client{Timeout: acmeHookTimeout})
+		},
+		new(acmeaccountApp.Hook),
+	),
+	provideWithInterfaces[*acmeaccountApp.Service](
+		acmeaccountApp.NewService,
+		new(acmeaccountDelivery.Service),
+		new(machineApp.ACMEReader),
 	),
+	fx.Provide(
+		func(
+			cfg *config.LoginProtectionConfig,
+			devices authApp.DeviceRepository,
+			geo accesscontrolApp.CountryResolver,
+			notifier authApp.Notifier,
+			audit authApp.AuditRecorder,
+		) authApp.LoginGuard {
+			if !cfg.AnomalyDetection {
+				return nil
+			}
+			return authApp.NewAnomalyDetector(devices, geo, notifier, audit, cfg.ApprovalURL, cfg.StepUpTTL)
+		},
+	),
+	provideWithInterfaces[*inviteApp.Service](
+		func(
+			r inviteApp.Repository,
+			groups inviteApp.GroupRepository,
+			uow inviteApp.UnitOfWork,
+			audit inviteApp.AuditRecorder,
+			cfg *config.InviteConfig,
+		) *inviteApp.Service {
+			return inviteApp.NewService(r, groups, uow, audit, cfg.Required, cfg.TTL, cfg.UserQuota)
+		},
+		new(authApp.InviteRedeemer),
+		new(inviteDelivery.Service),
+	),
+	fx.Provide(newLoginChangeNotifier),
+	fx.Provide(newPasswordPolicy),
+	fx.Provide(func(audit authApp.AuditRecorder) authApp.DuressAlerter { return authApp.NewDuressAlarm(audit) }),
 	provideWithInterfaces[*authApp.Service](
 		authApp.NewService,
 		new(authDelivery.Service),
 		new(middlewareDelivery.AuthWithJWTService),
+		new(middlewareDelivery.VaultUnlockService),
+		new(reportingApp.PasswordConfirmer),
+		new(seed.UserService),
+		new(onboardingApp.AccountRegistrar),
 	),
 	provideWithInterfaces[*datasyncApp.Service](
 		datasyncApp.NewService,
 		new(datasyncDelivery.Service),
 	),
 	fx.Provide(datasyncApp.NewServicesAggregator),
+	fx.Provide(integrityApp.NewService),
+	fx.Provide(newNotificationSenders),
+	fx.Provide(newPushSender),
+	provideWithInterfaces[*notificationApp.Service](
+		notificationApp.NewService,
+		new(authApp.Notifier),
+		new(vaulthealthApp.Notifier),
+		new(approvalApp.Notifier),
+		new(notificationDelivery.Service),
+		new(middlewareDelivery.SyncTrigger),
+	),
+	provideWithInterfaces[*accesspolicyApp.Service](
+		accesspolicyApp.NewService,
+		new(middlewareDelivery.RevealChecker),
+		new(machineApp.RevealChecker),
+		new(accesspolicyDelivery.Service),
+		new(adminDelivery.AccessPolicyService),
+	),
+	provideWithInterfaces[*vaulthealthApp.Service](
+		vaulthealthApp.NewService,
+		fx.Self(),
+		new(accountDelivery.Service),
+	),
+	fx.Provide(
+		func(cfg *config.GeoIPConfig) (accesscontrolApp.CountryResolver, error) {
+			if cfg.DBPath == "" {
+				return nil, nil
+			}
+			return geoip.Open(cfg.DBPath)
+		},
+	),
+	provideWithInterfaces[*accesscontrolApp.Service](
+		func(
+			r accesscontrolApp.Repository,
+			geo accesscontrolApp.CountryResolver,
+			tags accesscontrolApp.TagRepository,
+			cfg *config.GeofenceConfig,
+			audit accesscontrolApp.AuditRecorder,
+		) *accesscontrolApp.Service {
+			fence := accessrule.Geofence{Networks: cfg.Networks, Countries: cfg.Countries}
+			return accesscontrolApp.NewService(r, geo, tags, fence, audit)
+		},
+		new(middlewareDelivery.AccessChecker),
+		new(middlewareDelivery.RestrictedItemChecker),
+		new(machineApp.AccessChecker),
+		new(adminDelivery.Service),
+	),
+	provideWithInterfaces[*bruteforceApp.Service](
+		newBruteForceService,
+		new(middlewareDelivery.BruteForceGuard),
+	),
+	provideWithInterfaces[*botcheckApp.Service](
+		newBotCheckService,
+		new(middlewareDelivery.HumanChecker),
+		new(botcheckDelivery.Service),
+	),
+	provideWithInterfaces[*itemorderApp.Service](
+		itemorderApp.NewService,
+		new(itemorderDelivery.Service),
+	),
+	provideWithInterfaces[*itempathApp.Service](
+		itempathApp.NewService,
+		new(itempathDelivery.Service),
+		new(seed.PathService),
+	),
+	provideWithInterfaces[*itemaccessApp.Service](
+		itemaccessApp.NewService,
+		new(itemaccessDelivery.Service),
+		new(middlewareDelivery.ItemAccessRecorder),
+	),
+	provideWithInterfaces[*reportingApp.Service](
+		reportingApp.NewService,
+		new(reportDelivery.Service),
+	),
+	provideWithInterfaces[*itemtagApp.Service](
+		itemtagApp.NewService,
+		new(itemtagDelivery.Service),
+		new(seed.TagService),
+	),
+	provideWithInterfaces[*approvalApp.Service](
+		func(
+			r approvalApp.Repository,
+			tags approvalApp.TagRepository,
+			notifier approvalApp.Notifier,
+			audit approvalApp.AuditRecorder,
+			cfg *config.ApprovalConfig,
+		) *approvalApp.Service {
+			return approvalApp.NewService(r, tags, notifier, audit, cfg.Approvers, cfg.RequestTTL, cfg.AccessTTL)
+		},
+		new(middlewareDelivery.ApprovalChecker),
+		new(machineApp.ApprovalChecker),
+		new(approvalDelivery.Service),
+	),
+	provideWithInterfaces[*checkoutApp.Service](
+		func(
+			r checkoutApp.Repository,
+			groups checkoutApp.GroupRepository,
+			credentials checkoutApp.CredentialReader,
+			rotator checkoutApp.Rotator,
+			audit checkoutApp.AuditRecorder,
+			plans checkoutApp.PlanEnforcer,
+			licenses checkoutApp.LicenseEnforcer,
+			cfg *config.CheckoutConfig,
+		) *checkoutApp.Service {
+			return checkoutApp.NewService(r, groups, credentials, rotator, audit, cfg.TTL, plans, licenses)
+		},
+		fx.Self(),
+		new(checkoutDelivery.Service),
+	),
+	fx.Provide(newRotators),
+	provideWithInterfaces[*rotationApp.Service](
+		rotationApp.NewService,
+		fx.Self(),
+		new(rotationDelivery.Service),
+	),
+	provideWithInterfaces[*machineApp.Service](
+		func(
+			r machineApp.Repository,
+			paths machineApp.PathRepository,
+			credentials machineApp.CredentialReader,
+			notes machineApp.NoteReader,
+			acme machineApp.ACMEReader,
+			access machineApp.AccessChecker,
+			reveal machineApp.RevealChecker,
+			approvals machineApp.ApprovalChecker,
+			audit machineApp.AuditRecorder,
+			cfg *config.LeaseConfig,
+		) *machineApp.Service {
+			return machineApp.NewService(r, paths, credentials, notes, acme, access, reveal, approvals, audit, cfg.TTL)
+		},
+		new(machineDelivery.Service),
+	),
+	provideWithInterfaces[*maintenanceApp.Service](
+		func(cfg *config.MaintenanceConfig, audit maintenanceApp.AuditRecorder) *maintenanceApp.Service {
+			return maintenanceApp.NewService(cfg.Enabled, audit)
+		},
+		new(middlewareDelivery.MaintenanceMode),
+		new(adminDelivery.MaintenanceService),
+	),
+	provideWithInterfaces[*featureApp.Service](
+		func(r featureApp.Repository, cfg *config.FeatureConfig, audit featureApp.AuditRecorder) *featureApp.Service {
+			return featureApp.NewService(r, cfg.Defaults, audit)
+		},
+		new(middlewareDelivery.FeatureChecker),
+		new(featureDelivery.Service),
+		new(adminDelivery.FeatureService),
+		new(telemetryApp.FeatureLister),
+	),
+	provideWithInterfaces[*diagnosticsApp.Service](
+		diagnosticsApp.NewService,
+		new(adminDelivery.DiagnosticsService),
+	),
+	provideWithInterfaces[*storagegcApp.Service](
+		func(
+			blobs storagegcApp.BlobStore,
+			metadata storagegcApp.MetadataRepository,
+			users storagegcApp.UserDirectory,
+			metrics storagegcApp.MetricsRecorder,
+			cfg *config.StorageGCConfig,
+		) *storagegcApp.Service {
+			return storagegcApp.NewService(blobs, metadata, users, metrics, cfg.GracePeriod, cfg.Cleanup)
+		},
+		fx.Self(),
+		new(adminDelivery.StorageService),
+	),
+	provideWithInterfaces[*statsApp.Service](
+		func(
+			repository statsApp.Repository,
+			metrics statsApp.MetricsSnapshotter,
+			cfg *config.StatsConfig,
+		) *statsApp.Service {
+			return statsApp.NewService(repository, metrics, cfg.Retention)
+		},
+		fx.Self(),
+		new(adminDelivery.StatsService),
+	),
+	provideWithInterfaces[*telemetryApp.Service](
+		func(
+			usage telemetryApp.UsageCollector,
+			features telemetryApp.FeatureLister,
+			cfg *config.TelemetryConfig,
+		) *telemetryApp.Service {
+			sender := telemetry.NewHTTPSender(&http.Client{Timeout: telemetrySendTimeout}, cfg.Endpoint)
+			settings := telemetryApp.Settings{Endpoint: cfg.Endpoint, Interval: cfg.Interval, Enabled: cfg.Enabled}
+			return telemetryApp.NewService(settings, usage, features, sender)
+		},
+		fx.Self(),
+		new(adminDelivery.TelemetryService),
+	),
+	provideWithInterfaces[*updatecheckApp.Service](
+		func(cfg *config.UpdateCheckConfig) *updatecheckApp.Service {
+			// fetcher stays nil when no release manifest URL is configured.
+			var fetcher updatecheckApp.Fetcher
+			if cfg.URL != "" {
+				fetcher = release.NewHTTPFetcher(&http.Client{Timeout: updateCheckTimeout}, cfg.URL)
+			}
+			settings := updatecheckApp.Settings{
+				PublicKey: cfg.PublicKey,
+				Current:   buildinfo.Version,
+				Interval:  cfg.Interval,
+			}
+			return updatecheckApp.NewService(settings, fetcher)
+		},
+		fx.Self(),
+		new(adminDelivery.UpdateService),
+	),
+	provideWithInterfaces[*recordingApp.Service](
+		func(
+			repository recordingApp.Repository,
+			audit recordingApp.AuditRecorder,
+			cfg *config.RecordingConfig,
+		) *recordingApp.Service {
+			settings := recordingApp.Settings{
+				MaxWindow:   cfg.MaxWindow,
+				Retention:   cfg.Retention,
+				MaxBodySize: cfg.MaxBodySize,
+			}
+			return recordingApp.NewService(repository, audit, settings)
+		},
+		fx.Self(),
+		new(adminDelivery.RecordingService),
+		new(middlewareDelivery.RequestRecorder),
+	),
+	provideWithInterfaces[*retentionApp.Service](
+		func(
+			repository retentionApp.Repository,
+			groups retentionApp.GroupRepository,
+			holds retentionApp.HoldResolver,
+			audit retentionApp.AuditRecorder,
+			cfg *config.RetentionConfig,
+		) *retentionApp.Service {
+			defaults := retentionApp.Policy{KeepVersions: cfg.KeepVersions, KeepAuditDays: cfg.KeepAuditDays}
+			return retentionApp.NewService(repository, groups, holds, audit, defaults)
+		},
+		fx.Self(),
+		new(adminDelivery.RetentionService),
+	),
+	provideWithInterfaces[*auditexportApp.Service](
+		func(
+			repository auditexportApp.Repository,
+			audit auditexportApp.AuditRecorder,
+			signingCfg *config.RequestSigningConfig,
+		) *auditexportApp.Service {
+			return auditexportApp.NewService(repository, signingCfg.ResponseKey, audit)
+		},
+		new(auditexportDelivery.Service),
+	),
+	provideWithInterfaces[*adminapprovalApp.Service](
+		newAdminApprovalService,
+		fx.Self(),
+		new(adminapprovalDelivery.Service),
+	),
+	fx.Provide(newDualControlGate),
+	provideWithInterfaces[*legalholdApp.Service](
+		legalholdApp.NewService,
+		fx.Self(),
+		new(adminDelivery.LegalHoldService),
+		new(retentionApp.HoldResolver),
+		new(purgeApp.HoldResolver),
+	),
+	provideWithInterfaces[*itempolicyApp.Service](
+		itempolicyApp.NewService,
+		fx.Self(),
+		new(adminDelivery.ItemPolicyService),
+		new(itempolicyDelivery.Service),
+		new(credentialApp.PolicyEnforcer),
+		new(bankcardApp.PolicyEnforcer),
+		new(noteApp.PolicyEnforcer),
+		new(filedataApp.PolicyEnforcer),
+	),
+	provideWithInterfaces[*onboardingApp.Service](
+		newOnboardingService,
+		new(onboardingDelivery.Service),
+	),
+	provideWithInterfaces[*purgeApp.Service](
+		func(
+			repository purgeApp.Repository,
+			uow purgeApp.UnitOfWork,
+			tokens purgeApp.TokenGenerateValidator,
+			authorizer purgeApp.Authorizer,
+			holds purgeApp.HoldResolver,
+			audit purgeApp.AuditRecorder,
+			cfg *config.PurgeConfig,
+		) *purgeApp.Service {
+			return purgeApp.NewService(repository, uow, tokens, authorizer, holds, audit, cfg.TokenTTL)
+		},
+		new(purgeDelivery.Service),
+	),
+	provideWithInterfaces[*signingkeyApp.Service](
+		signingkeyApp.NewService,
+		new(middlewareDelivery.SigningKeyResolver),
+		new(signingkeyDelivery.Service),
+	),
+	provideWithInterfaces[*devicekeyApp.Service](
+		devicekeyApp.NewService,
+		new(middlewareDelivery.DeviceKeyResolver),
+		new(devicekeyDelivery.Service),
+	),
+	provideWithInterfaces[*directoryApp.Service](
+		directoryApp.NewService,
+		new(scimDelivery.Service),
+	),
 )
+
+// newTokenGenerateValidator creates the access token generator/validator, accepting tokens of the identity
+// provider as well when a JWKS URL is configured and single sign-on is licensed.
+func newTokenGenerateValidator(
+	cfg *config.AuthConfig,
+	licenses *licenseApp.Service,
+) (*security.TokenGenerateValidator, error) {
+	policy := security.TokenClaimsPolicy{Issuer: cfg.Issuer, Audiences: cfg.Audiences, Leeway: cfg.Leeway}
+	if cfg.JWKSURL == "" {
+		return security.NewTokenGenerateValidator(cfg.MasterKey, cfg.AccessTokenLifeTime, policy, nil)
+	}
+
+	federation, err := security.NewFederatedTokenVerifier(
+		security.NewJWKSKeySet(http.DefaultClient, cfg.JWKSURL, cfg.JWKSCacheTTL),
+		security.FederationPolicy{
+			Issuer:    cfg.JWKSIssuer,
+			UserClaim: cfg.JWKSUserClaim,
+			Audiences: cfg.Audiences,
+			Leeway:    cfg.Leeway,
+			Allowed: func() bool {
+				return licenses.Allows(context.Background(), license.FeatureSSO)
+			},
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create token federation: %w", err)
+	}
+	return security.NewTokenGenerateValidator(cfg.MasterKey, cfg.AccessTokenLifeTime, policy, federation)
+}
+
+// newPlanProvider creates the provider of the plans limiting users. With the Stripe integration configured,
+// users get the plans of their subscriptions and the default plan without one.
+func newPlanProvider(
+	cfg *config.BillingConfig,
+	subscriptions *planApp.SubscriptionService,
+	static *planApp.StaticProvider,
+) planApp.Provider {
+	if cfg.StripeWebhookSecret == "" {
+		return static
+	}
+	return subscriptions
+}
+
+// newNotificationSenders creates the senders of the notification channel kinds configured on the server.
+// E-mail requires an SMTP relay and Telegram a bot token.
+func newNotificationSenders(
+	mail *config.MailConfig,
+	cfg *config.NotificationConfig,
+) map[notificationDomain.Kind]notificationApp.Sender {
+	client := &http.Client{Timeout: notificationSendTimeout}
+	senders := map[notificationDomain.Kind]notificationApp.Sender{
+		notificationDomain.KindSlack: notifier.NewSlackSender(client),
+	}
+	if mail.Host != "" {
+		senders[notificationDomain.KindEmail] = mailer.NewSMTPMailer(
+			mail.Host, mail.Port, mail.Username, mail.Password, mail.From,
+		)
+	}
+	if cfg.TelegramBotToken != "" {
+		senders[notificationDomain.KindTelegram] = notifier.NewTelegramSender(client, cfg.TelegramBotToken)
+	}
+	return senders
+}
+
+// newLoginChangeNotifier creates the deliverer of login change confirmations. Confirmations are e-mailed
+// through the e-mail notification sender, so logins that are e-mail addresses require an SMTP relay.
+func newLoginChangeNotifier(
+	senders map[notificationDomain.Kind]notificationApp.Sender,
+	notifier authApp.Notifier,
+	audit authApp.AuditRecorder,
+) authApp.LoginChangeNotifier {
+	// messenger stays nil when no SMTP relay is configured.
+	var messenger authApp.Messenger
+	if sender, ok := senders[notificationDomain.KindEmail]; ok {
+		messenger = sender
+	}
+	return authApp.NewLoginChangeMailer(messenger, notifier, audit)
+}
+
+// newPasswordPolicy creates the policy of user passwords. The banned password file lists one password per line;
+// blank lines and lines starting with # are ignored.
+func newPasswordPolicy(cfg *config.PasswordPolicyConfig) (authDomain.PasswordPolicy, error) {
+	// banned collects the passwords of the banned password file.
+	var banned []string
+	if cfg.BannedFile != "" {
+		b, err := os.ReadFile(cfg.BannedFile)
+		if err != nil {
+			return authDomain.PasswordPolicy{}, fmt.Errorf("failed to read banned passwords: %w", err)
+		}
+		for _, line := range strings.Split(string(b), "\n") {
+			if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#") {
+				banned = append(banned, line)
+			}
+		}
+	}
+	return authDomain.NewPasswordPolicy(authDomain.PasswordPolicyParams{
+		Banned:          banned,
+		RequiredClasses: cfg.RequiredClasses,
+		MinLength:       cfg.MinLength,
+		HistorySize:     cfg.History,
+		BanCommon:       cfg.BanCommon,
+	}), nil
+}
+
+// newAdminApprovalService creates the dual-control service of destructive admin requests. The operators named
+// in the admin configuration are mailed about requests awaiting approval through the e-mail notification sender,
+// so notifications require an SMTP relay.
+func newAdminApprovalService(
+	r adminapprovalApp.Repository,
+	senders map[notificationDomain.Kind]notificationApp.Sender,
+	audit adminapprovalApp.AuditRecorder,
+	cfg *config.AdminConfig,
+) *adminapprovalApp.Service {
+	// messenger stays nil when no SMTP relay is configured.
+	var messenger adminapprovalApp.Messenger
+	if sender, ok := senders[notificationDomain.KindEmail]; ok {
+		messenger = sender
+	}
+	return adminapprovalApp.NewService(r, messenger, audit, cfg.NotifyEmails, cfg.DualControlTTL)
+}
+
+// newDualControlGate returns the dual-control service as the gate of destructive admin requests, or nil when
+// dual control is disabled.
+func newDualControlGate(s *adminapprovalApp.Service, cfg *config.AdminConfig) middlewareDelivery.DualControlGate {
+	if !cfg.DualControl {
+		return nil
+	}
+	return s
+}
+
+// newOnboardingService creates the first-run wizard of the server. The test message of the smtp step is sent
+// through the e-mail notification sender, so the step is skipped when no SMTP relay is configured.
+func newOnboardingService(
+	users onboardingApp.UserRepository,
+	accounts onboardingApp.AccountRegistrar,
+	storage onboardingApp.StorageProber,
+	senders map[notificationDomain.Kind]notificationApp.Sender,
+	audit onboardingApp.AuditRecorder,
+	cfg *config.OnboardingConfig,
+) (*onboardingApp.Service, error) {
+	template, err := onboardingDomain.NewTemplate(cfg.Steps)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create onboarding template: %w", err)
+	}
+	// mailer stays nil when no SMTP relay is configured.
+	var mailer onboardingApp.Mailer
+	if sender, ok := senders[notificationDomain.KindEmail]; ok {
+		mailer = sender
+	}
+	return onboardingApp.NewService(users, accounts, storage, mailer, audit, template, cfg.MasterKeyLength), nil
+}
+
+// newRotators creates the rotators of the external systems credential secrets can be rotated in.
+func newRotators() map[rotationDomain.Kind]rotationApp.Rotator {
+	client := &http.Client{Timeout: rotationRequestTimeout}
+	return map[rotationDomain.Kind]rotationApp.Rotator{
+		rotationDomain.KindPostgres: rotator.NewPostgresRotator(),
+		rotationDomain.KindAWSIAM:   rotator.NewAWSIAMRotator(client),
+		rotationDomain.KindWebhook:  rotator.NewWebhookRotator(client),
+	}
+}
+
+// newPushSender creates the web push sender of device subscriptions, or nil when no VAPID key is configured.
+func newPushSender(cfg *config.NotificationConfig) (notificationApp.PushSender, error) {
+	if cfg.VAPIDPrivateKey == "" {
+		return nil, nil
+	}
+	client := &http.Client{Timeout: notificationSendTimeout}
+	webPush, err := notifier.NewWebPushSender(client, cfg.VAPIDPrivateKey, cfg.VAPIDSubject)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create web push sender: %w", err)
+	}
+	return webPush, nil
+}
+
+// newBruteForceService creates the brute force protection service. With a log path configured, the failure log
+// is appended to that file, which is closed on shutdown.
+func newBruteForceService(
+	lc fx.Lifecycle,
+	cfg *config.BruteForceConfig,
+	audit bruteforceApp.AuditRecorder,
+) (*bruteforceApp.Service, error) {
+	policy := bruteforceApp.Policy{Threshold: cfg.Threshold, Window: cfg.Window, BanDuration: cfg.BanDuration}
+	if cfg.LogPath == "" {
+		return bruteforceApp.NewService(policy, nil, audit), nil
+	}
+
+	f, err := os.OpenFile(cfg.LogPath, os.O_WRONLY|os.O_APPEND|os.O_CREATE, authFailureLogPerm)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open authentication failure log: %w", err)
+	}
+	lc.Append(fx.Hook{
+		OnStop: func(context.Context) error { return f.Close() },
+	})
+	return bruteforceApp.NewService(policy, f, audit), nil
+}
+
+// newBotCheckService creates the human verification service with the verifier of the configured provider.
+// Proof-of-work puzzles are issued and verified by the same instance; without a provider challenges are disabled.
+func newBotCheckService(cfg *config.ChallengeConfig) *botcheckApp.Service {
+	settings := botcheckApp.Settings{
+		Provider:      cfg.Provider,
+		SiteKey:       cfg.SiteKey,
+		LoginFailures: cfg.LoginFailures,
+		LoginWindow:   cfg.LoginWindow,
+	}
+	client := &http.Client{Timeout: challengeVerifyTimeout}
+	switch cfg.Provider {
+	case botcheckApp.ProviderHCaptcha:
+		verifier := captcha.NewSiteVerifier(client, captcha.HCaptchaVerifyURL, cfg.Secret)
+		return botcheckApp.NewService(settings, verifier, nil)
+	case botcheckApp.ProviderTurnstile:
+		verifier := captcha.NewSiteVerifier(client, captcha.TurnstileVerifyURL, cfg.Secret)
+		return botcheckApp.NewService(settings, verifier, nil)
+	case botcheckApp.ProviderPoW:
+		pow := captcha.NewProofOfWork(cfg.Key, cfg.Difficulty, cfg.TTL)
+		return botcheckApp.NewService(settings, pow, pow)
+	default:
+		return botcheckApp.NewService(settings, nil, nil)
+	}
+}
@@ -0,0 +1,108 @@
+package fxshow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/backup"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/config"
+	repositorySnapshot "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/snapshot"
+	"go.uber.org/fx"
+)
+
+// Supported snapshot store types.
+const (
+	// backupStorageLocal keeps snapshots in a local directory.
+	backupStorageLocal = "local"
+	// backupStorageS3 keeps snapshots in an S3-compatible object storage.
+	backupStorageS3 = "s3"
+)
+
+// backupModule provides backup and restore dependencies.
+// Configures the snapshot store, the table snapshot repository and the backup service.
+var backupModule = fx.Module("backup",
+	fx.Provide(
+		newBackupStore,
+		func(cfg *config.BackupConfig, db backup.Database, store backup.Store) *backup.Service {
+			return backup.NewService(db, store, cfg.Key, cfg.FilesBasePath)
+		},
+	),
+	provideWithInterfaces[*repositorySnapshot.Repository](
+		repositorySnapshot.NewRepository,
+		new(backup.Database),
+	),
+)
+
+// newBackupStore creates the snapshot store selected by the backup configuration.
+func newBackupStore(cfg *config.BackupConfig) (backup.Store, error) {
+	switch cfg.Storage {
+	case "", backupStorageLocal:
+		if cfg.LocalPath == "" {
+			return nil, errors.New("BACKUP_LOCAL_PATH is required for local backup storage")
+		}
+		return backup.NewLocalStore(cfg.LocalPath), nil
+	case backupStorageS3:
+		if cfg.S3Endpoint == "" || cfg.S3Bucket == "" || cfg.S3AccessKeyID == "" || cfg.S3SecretAccessKey == "" {
+			return nil, errors.New("BACKUP_S3_ENDPOINT, BACKUP_S3_BUCKET and S3 credentials are required for s3")
+		}
+		return backup.NewS3Store(http.DefaultClient, backup.S3Config{
+			Endpoint:        cfg.S3Endpoint,
+			Region:          cfg.S3Region,
+			Bucket:          cfg.S3Bucket,
+			AccessKeyID:     cfg.S3AccessKeyID,
+			SecretAccessKey: cfg.S3SecretAccessKey,
+		}), nil
+	default:
+		return nil, fmt.Errorf("unsupported backup storage %q", cfg.Storage)
+	}
+}
+
+// RunBackup creates an encrypted snapshot of the database and the file storage
+// using the server configuration.
+func RunBackup(ctx context.Context) (*backup.Result, error) {
+	var res *backup.Result
+	err := runBackupService(ctx, func(ctx context.Context, s *backup.Service) error {
+		var err error
+		res, err = s.Backup(ctx)
+		return err
+	})
+	return res, err
+}
+
+// RunRestore replaces the database and the file storage content with the named snapshot
+// using the server configuration. The server must be stopped while restoring.
+func RunRestore(ctx context.Context, name string) (*backup.Manifest, error) {
+	var m *backup.Manifest
+	err := runBackupService(ctx, func(ctx context.Context, s *backup.Service) error {
+		var err error
+		m, err = s.Restore(ctx, name)
+		return err
+	})
+	return m, err
+}
+
+// runBackupService starts a minimal application with the backup service, runs fn and stops it.
+func runBackupService(ctx context.Context, fn func(ctx context.Context, s *backup.Service) error) (err error) {
+	var s *backup.Service
+	app := fx.New(
+		configModule,
+		loggerModule,
+		repositoryModule,
+		backupModule,
+		fx.Invoke(runDatabaseClient),
+		fx.Populate(&s),
+	)
+
+	if err := app.Start(ctx); err != nil {
+		return fmt.Errorf("failed to start backup application: %w", err)
+	}
+	defer func() {
+		if stopErr := app.Stop(context.WithoutCancel(ctx)); stopErr != nil {
+			err = errors.Join(err, fmt.Errorf("failed to stop backup application: %w", stopErr))
+		}
+	}()
+
+	return fn(ctx, s)
+}
@@ -0,0 +1,74 @@
+package fxshow
+
+import (
+	"testing"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/backup"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackupModule(t *testing.T) {
+	t.Parallel()
+
+	assert.NotNil(t, backupModule, "backupModule should not be nil")
+}
+
+func TestNewBackupStore(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		cfg         *config.BackupConfig
+		wantType    backup.Store
+		name        string
+		errContains string
+	}{
+		{
+			name:     "default_local",
+			cfg:      &config.BackupConfig{LocalPath: "/app/backups"},
+			wantType: &backup.LocalStore{},
+		},
+		{
+			name:        "local_without_path",
+			cfg:         &config.BackupConfig{Storage: "local"},
+			errContains: "BACKUP_LOCAL_PATH is required",
+		},
+		{
+			name: "s3",
+			cfg: &config.BackupConfig{
+				Storage:           "s3",
+				S3Endpoint:        "https://s3.example.com",
+				S3Bucket:          "backups",
+				S3AccessKeyID:     "AKID",
+				S3SecretAccessKey: "secret",
+			},
+			wantType: &backup.S3Store{},
+		},
+		{
+			name:        "s3_without_credentials",
+			cfg:         &config.BackupConfig{Storage: "s3", S3Endpoint: "https://s3.example.com"},
+			errContains: "credentials are required",
+		},
+		{
+			name:        "unsupported",
+			cfg:         &config.BackupConfig{Storage: "ftp"},
+			errContains: `unsupported backup storage "ftp"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			store, err := newBackupStore(tt.cfg)
+			if tt.errContains != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errContains)
+				return
+			}
+			require.NoError(t, err)
+			assert.IsType(t, tt.wantType, store)
+		})
+	}
+}
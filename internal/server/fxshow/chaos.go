@@ -0,0 +1,29 @@
+package fxshow
+
+import (
+	"math/rand/v2"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/config"
+	repositoryDB "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/db"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+// chaosModule injects the configured repository faults into the database client of the server.
+// It is only part of the serving application, so maintenance commands never see injected faults.
+var chaosModule = fx.Decorate(decorateChaosDBClient)
+
+// decorateChaosDBClient wraps the database client with fault injection when repository faults are configured.
+func decorateChaosDBClient(
+	client repositoryDB.DBClient,
+	cfg *config.ChaosConfig,
+	recorder repositoryDB.ChaosRecorder,
+	logger *zap.SugaredLogger,
+) repositoryDB.DBClient {
+	if len(cfg.RepositoryFaults) == 0 {
+		return client
+	}
+	logger.Warnf("Fault injection is enabled with %d repository faults; never enable it in production",
+		len(cfg.RepositoryFaults))
+	return repositoryDB.NewChaosClient(client, cfg.RepositoryFaults, rand.Float64, recorder)
+}
@@ -0,0 +1,59 @@
+package fxshow
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/chaos"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/config"
+	repositoryDB "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/db"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+	"go.uber.org/zap"
+)
+
+// stubDBClient is a database client provided in place of PostgreSQL.
+type stubDBClient struct {
+	repositoryDB.DBClient
+}
+
+func TestChaosModule(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		faults    []chaos.Fault
+		wantChaos bool
+	}{
+		{name: "no faults"},
+		{
+			name:      "repository faults",
+			faults:    []chaos.Fault{{Kind: chaos.KindLatency, Probability: 1, Latency: time.Millisecond}},
+			wantChaos: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// client receives the decorated database client.
+			var client repositoryDB.DBClient
+			app := fxtest.New(t,
+				fx.Module("repository",
+					fx.Provide(func() repositoryDB.DBClient { return stubDBClient{} }),
+					fx.Invoke(func(c repositoryDB.DBClient) { client = c }),
+				),
+				fx.Supply(&config.ChaosConfig{RepositoryFaults: tt.faults}, zap.NewNop().Sugar()),
+				fx.Provide(func() repositoryDB.ChaosRecorder { return nil }),
+				chaosModule,
+			)
+			app.RequireStart()
+			defer app.RequireStop()
+
+			_, isChaos := client.(*repositoryDB.ChaosClient)
+			assert.Equal(t, tt.wantChaos, isChaos)
+		})
+	}
+}
@@ -12,8 +12,50 @@ var configModule = fx.Module("config",
 		config.LoadConfig,
 		config.ExtractAuthConfig,
 		config.ExtractDBConfig,
+		config.ExtractCryptoConfig,
 		config.ExtractLoggerConfig,
 		config.ExtractDeliveryConfig,
+		config.ExtractCORSConfig,
+		config.ExtractSessionConfig,
+		config.ExtractSecurityHeadersConfig,
+		config.ExtractProxyConfig,
+		config.ExtractAdminConfig,
+		config.ExtractSCIMConfig,
+		config.ExtractRequestSigningConfig,
+		config.ExtractGeoIPConfig,
+		config.ExtractGeofenceConfig,
+		config.ExtractAuthzConfig,
+		config.ExtractApprovalConfig,
+		config.ExtractCheckoutConfig,
+		config.ExtractRotationConfig,
+		config.ExtractStorageGCConfig,
+		config.ExtractNoteMergeConfig,
+		config.ExtractStatsConfig,
+		config.ExtractPlanConfig,
+		config.ExtractBillingConfig,
+		config.ExtractLicenseConfig,
+		config.ExtractTelemetryConfig,
+		config.ExtractUpdateCheckConfig,
+		config.ExtractRecordingConfig,
+		config.ExtractRetentionConfig,
+		config.ExtractAutofillConfig,
+		config.ExtractPurgeConfig,
+		config.ExtractLeaseConfig,
+		config.ExtractLoginProtectionConfig,
+		config.ExtractBruteForceConfig,
+		config.ExtractChallengeConfig,
+		config.ExtractInviteConfig,
+		config.ExtractPasswordPolicyConfig,
+		config.ExtractOnboardingConfig,
+		config.ExtractMaintenanceConfig,
+		config.ExtractFeatureConfig,
+		config.ExtractChaosConfig,
 		config.ExtractFileStorageConfig,
+		config.ExtractIntegrityConfig,
+		config.ExtractHistoryConfig,
+		config.ExtractBackupConfig,
+		config.ExtractMailConfig,
+		config.ExtractNotificationConfig,
+		config.ExtractHealthReportConfig,
 	),
 )
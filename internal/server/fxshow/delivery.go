@@ -1,10 +1,49 @@
 package fxshow
 
 import (
+	"fmt"
+	"math/rand/v2"
+
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/buildinfo"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/common"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/config"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/accesspolicy"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/account"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/acmeaccount"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/admin"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/adminapproval"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/approval"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/auditexport"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/auth"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/bankcard"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/billing"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/botcheck"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/checkout"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/credential"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/datasync"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/devicekey"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/feature"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/filedata"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/invite"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/itemaccess"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/itemorder"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/itempath"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/itempolicy"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/itemtag"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/machine"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/middleware"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/note"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/notification"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/onboarding"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/plan"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/purge"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/report"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/rotation"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/scim"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/signingkey"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/wellknown"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/systemd"
 	"go.uber.org/fx"
 	"go.uber.org/zap"
 )
@@ -19,11 +58,146 @@ var deliveryModule = fx.Module("delivery",
 		new(delivery.BuildInfoOperator),
 	),
 	provideWithInterfaces[*delivery.RouteRegistry](
-		delivery.NewRouteRegistry,
+		func(
+			p routeRegistryParams,
+			deliveryCfg *config.DeliveryConfig,
+			adminCfg *config.AdminConfig,
+			scimCfg *config.SCIMConfig,
+			signingCfg *config.RequestSigningConfig,
+			autofillCfg *config.AutofillConfig,
+			sessionCfg *config.SessionConfig,
+		) *delivery.RouteRegistry {
+			return delivery.NewRouteRegistry(
+				p.AuthService,
+				p.AuthJWTService,
+				p.BuildInfoOperator,
+				p.MetricsSnapshotter,
+				p.BankcardService,
+				p.CredentialService,
+				p.NoteService,
+				p.DatasyncService,
+				p.FiledataService,
+				p.AccountService,
+				p.AccessChecker,
+				p.BruteForceGuard,
+				p.HumanChecker,
+				p.ChallengeService,
+				p.AdminService,
+				p.MaintenanceMode,
+				p.MaintenanceService,
+				p.FeatureService,
+				p.FeatureFlagService,
+				p.DiagnosticsService,
+				p.SigningKeyService,
+				p.DirectoryService,
+				p.NotificationService,
+				p.SyncTrigger,
+				p.AccessPolicyService,
+				p.AccessPolicyAdminService,
+				p.RevealChecker,
+				p.ItemTagService,
+				p.RestrictedChecker,
+				p.ApprovalService,
+				p.ApprovalChecker,
+				p.CheckoutService,
+				p.RotationService,
+				p.MachineService,
+				p.ItemPathService,
+				p.ACMEAccountService,
+				p.StorageService,
+				p.StatsService,
+				p.InviteService,
+				p.VaultUnlocker,
+				p.ItemAccessService,
+				p.RevealRecorder,
+				p.ReportService,
+				p.PlanService,
+				p.BillingService,
+				p.LicenseService,
+				p.LicenseChecker,
+				p.TelemetryService,
+				p.UpdateService,
+				p.RecordingService,
+				p.RetentionService,
+				p.LegalHoldService,
+				p.ItemPolicyService,
+				p.ItemPolicyAdminService,
+				p.OnboardingService,
+				p.AuditExportService,
+				p.AdminApprovalService,
+				p.DualControlGate,
+				p.DryRunner,
+				p.PurgeService,
+				p.ItemOrderService,
+				p.DeviceKeyService,
+				p.DeviceKeyResolver,
+				delivery.RouteTimeouts{
+					Default: deliveryCfg.RequestTimeout,
+					Items:   deliveryCfg.ItemsRequestTimeout,
+					Files:   deliveryCfg.FilesRequestTimeout,
+				},
+				delivery.RequestSigning{
+					Keys:        p.SigningKeyResolver,
+					ResponseKey: signingCfg.ResponseKey,
+					AdminKey:    signingCfg.AdminKey,
+					MaxSkew:     signingCfg.MaxSkew,
+				},
+				middleware.SessionConfig{
+					Key:            sessionCfg.Key,
+					CookieName:     sessionCfg.CookieName,
+					CSRFCookieName: sessionCfg.CSRFCookieName,
+					Domain:         sessionCfg.Domain,
+					SameSite:       sessionCfg.SameSite,
+					Secure:         sessionCfg.Secure,
+					Enabled:        sessionCfg.Enabled,
+				},
+				deliveryCfg.WebUIEnabled,
+				newAutofillAssociations(autofillCfg),
+				p.TimeoutRecorder,
+				adminCfg.Token,
+				adminCfg.Operators,
+				scimCfg.Token,
+			)
+		},
 		new(delivery.RouteConfigurator),
 	),
 	provideWithInterfaces[*delivery.MiddlewareRegistry](
-		delivery.NewMiddlewareRegistry,
+		func(
+			logger *zap.SugaredLogger,
+			corsCfg *config.CORSConfig,
+			headersCfg *config.SecurityHeadersConfig,
+			proxyCfg *config.ProxyConfig,
+			chaosCfg *config.ChaosConfig,
+			chaosRecorder middleware.ChaosRecorder,
+			requestStats middleware.RequestStatsRecorder,
+			recorder middleware.RequestRecorder,
+		) *delivery.MiddlewareRegistry {
+			return delivery.NewMiddlewareRegistry(
+				logger,
+				middleware.CORSConfig{
+					AllowedOrigins:   corsCfg.AllowedOrigins,
+					AllowedMethods:   corsCfg.AllowedMethods,
+					AllowedHeaders:   corsCfg.AllowedHeaders,
+					MaxAge:           corsCfg.MaxAge,
+					AllowCredentials: corsCfg.AllowCredentials,
+				},
+				middleware.SecurityHeadersConfig{
+					ContentSecurityPolicy:     headersCfg.CSP,
+					HTMLContentSecurityPolicy: headersCfg.HTMLCSP,
+					HSTSMaxAge:                headersCfg.HSTSMaxAge,
+					HSTSIncludeSubdomains:     headersCfg.HSTSIncludeSubdomains,
+					TLSEnabled:                headersCfg.TLSEnabled,
+				},
+				proxyCfg.TrustedProxies,
+				middleware.ChaosConfig{
+					Rules:    chaosCfg.Rules,
+					Roll:     rand.Float64,
+					Recorder: chaosRecorder,
+				},
+				requestStats,
+				recorder,
+			)
+		},
 		new(delivery.MiddlewareConfigurator),
 	),
 	fx.Provide(
@@ -32,8 +206,8 @@ var deliveryModule = fx.Module("delivery",
 			logger *zap.SugaredLogger,
 			rc delivery.RouteConfigurator,
 			mc delivery.MiddlewareConfigurator,
-		) *delivery.HTTPServer {
-			return delivery.NewHTTPServer(
+		) (*delivery.HTTPServer, error) {
+			s := delivery.NewHTTPServer(
 				logger.Named("hhtp-server"),
 				rc,
 				mc,
@@ -43,11 +217,192 @@ var deliveryModule = fx.Module("delivery",
 				cfg.TLSEnabled,
 				cfg.TLSCertFile,
 				cfg.TLSKeyFile,
+				acmeConfig(cfg),
+				delivery.ConnectionConfig{
+					ReadTimeout:       cfg.ReadTimeout,
+					ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+					WriteTimeout:      cfg.WriteTimeout,
+					IdleTimeout:       cfg.IdleTimeout,
+					MaxHeaderBytes:    cfg.MaxHeaderBytes,
+					HTTP2Enabled:      cfg.HTTP2Enabled,
+					KeepAlivesEnabled: cfg.KeepAlivesEnabled,
+				},
 			)
+			// Sockets passed by systemd socket activation replace the configured addresses.
+			listeners, err := systemd.Listeners()
+			if err != nil {
+				return nil, fmt.Errorf("failed to take over activated sockets: %w", err)
+			}
+			if err := s.UseListeners(listeners); err != nil {
+				return nil, fmt.Errorf("failed to use activated sockets: %w", err)
+			}
+			return s, nil
 		},
 	),
 )
 
+// routeRegistryParams collects the services exposed through HTTP routes.
+type routeRegistryParams struct {
+	fx.In
+
+	// AuthService handles user authentication operations.
+	AuthService auth.Service
+	// AuthJWTService provides JWT authentication middleware.
+	AuthJWTService middleware.AuthWithJWTService
+	// BuildInfoOperator provides application build information.
+	BuildInfoOperator delivery.BuildInfoOperator
+	// MetricsSnapshotter provides operational metrics.
+	MetricsSnapshotter delivery.MetricsSnapshotter
+	// BankcardService handles bank card operations.
+	BankcardService bankcard.Service
+	// CredentialService handles credential operations.
+	CredentialService credential.Service
+	// NoteService handles note operations.
+	NoteService note.Service
+	// DatasyncService handles data synchronization operations.
+	DatasyncService datasync.Service
+	// FiledataService handles file data operations.
+	FiledataService filedata.Service
+	// AccountService handles account report operations.
+	AccountService account.Service
+	// AccessChecker enforces network access rules.
+	AccessChecker middleware.AccessChecker
+	// BruteForceGuard rejects banned clients and reports failed authentications.
+	BruteForceGuard middleware.BruteForceGuard
+	// HumanChecker requires challenge solutions on registrations and repeated failed logins.
+	HumanChecker middleware.HumanChecker
+	// ChallengeService issues the challenges clients solve for the human check.
+	ChallengeService botcheck.Service
+	// AdminService handles administrative operations.
+	AdminService admin.Service
+	// MaintenanceMode reports read-only maintenance mode.
+	MaintenanceMode middleware.MaintenanceMode
+	// MaintenanceService switches read-only maintenance mode.
+	MaintenanceService admin.MaintenanceService
+	// FeatureService resolves the feature flags of users.
+	FeatureService feature.Service
+	// FeatureFlagService manages stored feature flags.
+	FeatureFlagService admin.FeatureService
+	// DiagnosticsService measures the runtime performance of the instance.
+	DiagnosticsService admin.DiagnosticsService
+	// SigningKeyService manages the request signing keys of users.
+	SigningKeyService signingkey.Service
+	// DirectoryService provisions users and groups from an identity provider.
+	DirectoryService scim.Service
+	// NotificationService manages the notification channels and push subscriptions of users.
+	NotificationService notification.Service
+	// SyncTrigger tells the other devices of a user that the vault changed.
+	SyncTrigger middleware.SyncTrigger
+	// AccessPolicyService manages the access windows users define for themselves.
+	AccessPolicyService accesspolicy.Service
+	// AccessPolicyAdminService manages the access windows set by administrators.
+	AccessPolicyAdminService admin.AccessPolicyService
+	// RevealChecker rejects item reveals outside the access windows of users.
+	RevealChecker middleware.RevealChecker
+	// ItemTagService manages the tags users attach to their vault items.
+	ItemTagService itemtag.Service
+	// RestrictedChecker rejects access to restricted items from outside the geofence.
+	RestrictedChecker middleware.RestrictedItemChecker
+	// ApprovalService runs the approval workflow of items that require approval.
+	ApprovalService approval.Service
+	// ApprovalChecker rejects access to items that require approval without an approved request.
+	ApprovalChecker middleware.ApprovalChecker
+	// CheckoutService shares credentials with groups and checks them out to one member at a time.
+	CheckoutService checkout.Service
+	// RotationService rotates credential secrets in the external systems they belong to.
+	RotationService rotation.Service
+	// MachineService issues machine identities and leases vault items to them.
+	MachineService machine.Service
+	// ItemPathService manages the paths users assign to their vault items.
+	ItemPathService itempath.Service
+	// ACMEAccountService manages the ACME accounts certificate renewal tooling reads from the vault.
+	ACMEAccountService acmeaccount.Service
+	// StorageService reconciles stored file contents with file metadata.
+	StorageService admin.StorageService
+	// StatsService reports aggregate usage statistics.
+	StatsService admin.StatsService
+	// InviteService manages the invite codes new users register with.
+	InviteService invite.Service
+	// VaultUnlocker verifies the unlock keys of vaults sealed with an unlock secret.
+	VaultUnlocker middleware.VaultUnlockService
+	// ItemAccessService reports the access history of vault items.
+	ItemAccessService itemaccess.Service
+	// RevealRecorder records the reveals of vault items in their access history.
+	RevealRecorder middleware.ItemAccessRecorder
+	// ReportService generates CSV reports of vault items.
+	ReportService report.Service
+	// PlanService reports the plans limiting users.
+	PlanService plan.Service
+	// BillingService receives the webhooks of billing integrations.
+	BillingService billing.Service
+	// LicenseService manages the enterprise license.
+	LicenseService admin.LicenseService
+	// LicenseChecker rejects requests to unlicensed enterprise features.
+	LicenseChecker middleware.LicenseChecker
+	// TelemetryService previews the anonymous usage ping.
+	TelemetryService admin.TelemetryService
+	// UpdateService reports available server updates.
+	UpdateService admin.UpdateService
+	// RecordingService manages the request recordings of support debugging.
+	RecordingService admin.RecordingService
+	// RetentionService manages the data retention policies.
+	RetentionService admin.RetentionService
+	// LegalHoldService manages the legal holds.
+	LegalHoldService admin.LegalHoldService
+	// ItemPolicyService reports the item policy applying to the user.
+	ItemPolicyService itempolicy.Service
+	// ItemPolicyAdminService manages the item policies.
+	ItemPolicyAdminService admin.ItemPolicyService
+	// OnboardingService runs the first-run wizard of a server with an empty database.
+	OnboardingService onboarding.Service
+	// AuditExportService exports the audit trail for compliance audits.
+	AuditExportService auditexport.Service
+	// AdminApprovalService lists and decides the destructive admin requests held back for approval.
+	AdminApprovalService adminapproval.Service
+	// DualControlGate holds back destructive admin requests until a second operator approves them.
+	DualControlGate middleware.DualControlGate
+	// DryRunner runs dry-run item changes in transactions that roll back.
+	DryRunner middleware.DryRunner
+	// PurgeService permanently deletes every item of one kind after confirmation.
+	PurgeService purge.Service
+	// ItemOrderService manages the manual orders users arrange the items of their folders in.
+	ItemOrderService itemorder.Service
+	// DeviceKeyService manages the device keys sync responses are encrypted for.
+	DeviceKeyService devicekey.Service
+	// DeviceKeyResolver resolves the device keys sync responses are encrypted for.
+	DeviceKeyResolver middleware.DeviceKeyResolver
+	// SigningKeyResolver resolves the signing keys vault exports are verified with.
+	SigningKeyResolver middleware.SigningKeyResolver
+	// TimeoutRecorder counts timed out and canceled requests.
+	TimeoutRecorder middleware.TimeoutRecorder
+}
+
+// acmeConfig returns the ACME certificate management settings, or nil when certificate files are used.
+func acmeConfig(cfg *config.DeliveryConfig) *delivery.ACMEConfig {
+	if len(cfg.ACMEDomains) == 0 {
+		return nil
+	}
+	return &delivery.ACMEConfig{
+		Domains:      cfg.ACMEDomains,
+		Email:        cfg.ACMEEmail,
+		CacheDir:     cfg.ACMECacheDir,
+		DirectoryURL: cfg.ACMEDirectoryURL,
+		HTTPAddr:     cfg.ACMEHTTPAddr,
+	}
+}
+
+// newAutofillAssociations returns the apps the autofill association files declare.
+func newAutofillAssociations(cfg *config.AutofillConfig) wellknown.Associations {
+	androidApps := make([]wellknown.AndroidApp, 0, len(cfg.AndroidApps))
+	for _, app := range cfg.AndroidApps {
+		androidApps = append(androidApps, wellknown.AndroidApp{
+			PackageName:  app.PackageName,
+			Fingerprints: app.Fingerprints,
+		})
+	}
+	return wellknown.Associations{AndroidApps: androidApps, IOSApps: cfg.IOSApps}
+}
+
 // runHTTPServer registers HTTP server lifecycle hooks with fx.
 func runHTTPServer(lc fx.Lifecycle, s *delivery.HTTPServer) {
 	lc.Append(fx.Hook{
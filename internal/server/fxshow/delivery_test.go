@@ -262,7 +262,7 @@ func TestDeliveryModuleRegistryProviders(t *testing.T) {
 				// Test direct provider pattern for MiddlewareRegistry
 				assert.NotNil(t, deliveryModule)
 
-				// Uses delivery.NewMiddlewareRegistry directly
+				// Wraps delivery.NewMiddlewareRegistry with CORS settings
 				app := fx.New(
 					deliveryModule,
 					fx.NopLogger,
@@ -16,3 +16,9 @@ func provideWithInterfaces[Impl any](constructor any, interfaces ...any) fx.Opti
 		fx.Annotate(constructor, asOptions...),
 	)
 }
+
+// exposeAs declares which interfaces an already provided type implements, so the same instance is
+// available both as the concrete type and through the interfaces.
+func exposeAs[Impl any](interfaces ...any) fx.Option {
+	return provideWithInterfaces[Impl](func(impl Impl) Impl { return impl }, interfaces...)
+}
@@ -0,0 +1,32 @@
+package fxshow
+
+import (
+	"context"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/config"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/health"
+)
+
+// healthcheckTimeout bounds a health check, so a hanging server is reported unhealthy.
+const healthcheckTimeout = 5 * time.Second
+
+// RunHealthcheck requests the health endpoint of the server running in the same container with the server
+// configuration. It starts no application, so the check stays cheap enough to run every few seconds.
+func RunHealthcheck(ctx context.Context) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return err
+	}
+	d := config.ExtractDeliveryConfig(cfg)
+
+	// serverName selects the certificate obtained via ACME; certificate files are served without it.
+	var serverName string
+	if len(d.ACMEDomains) > 0 {
+		serverName = d.ACMEDomains[0]
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, healthcheckTimeout)
+	defer cancel()
+	return health.Probe(ctx, d.Address, serverName, d.TLSEnabled)
+}
@@ -0,0 +1,310 @@
+package fxshow
+
+import (
+	"context"
+	"fmt"
+
+	checkoutApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/checkout"
+	integrityApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/integrity"
+	legalholdApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/legalhold"
+	noteApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/note"
+	recordingApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/recording"
+	retentionApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/retention"
+	rotationApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/rotation"
+	statsApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/stats"
+	storagegcApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/storagegc"
+	telemetryApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/telemetry"
+	updatecheckApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/updatecheck"
+	vaulthealthApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/vaulthealth"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/config"
+	repositoryHistory "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/history"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/scheduler"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+// jobsGroup is the fx value group collecting periodic background jobs.
+const jobsGroup = `group:"jobs"`
+
+// jobsModule provides periodic background jobs.
+// Each job is registered in the jobs value group and started by runJobs.
+var jobsModule = fx.Module("jobs",
+	fx.Provide(
+		fx.Annotate(
+			func(
+				cfg *config.IntegrityConfig,
+				logger *zap.SugaredLogger,
+				s *integrityApp.Service,
+			) *scheduler.PeriodicJob {
+				l := logger.Named("integrity-verification")
+				return scheduler.NewPeriodicJob(l, "integrity-verification", cfg.VerifyInterval,
+					func(ctx context.Context) error {
+						report, err := s.RunVerification(ctx)
+						if err != nil {
+							return fmt.Errorf("integrity verification failed: %w", err)
+						}
+						if len(report.Violations) > 0 {
+							l.Errorf("Integrity verification found %d tampered rows of %d checked",
+								len(report.Violations), report.Checked)
+						}
+						return nil
+					},
+				)
+			},
+			fx.ResultTags(jobsGroup),
+		),
+		fx.Annotate(
+			func(
+				cfg *config.HistoryConfig,
+				logger *zap.SugaredLogger,
+				p *repositoryHistory.Pruner,
+				holds *legalholdApp.Service,
+			) *scheduler.PeriodicJob {
+				l := logger.Named("item-history-pruning")
+				return scheduler.NewPeriodicJob(l, "item-history-pruning", cfg.PruneInterval,
+					func(ctx context.Context) error {
+						held, err := holds.Held(ctx)
+						if err != nil {
+							return fmt.Errorf("item history pruning failed: %w", err)
+						}
+						n, err := p.Prune(ctx, repositoryHistory.PruneParams{
+							Retention:      cfg.Retention,
+							ExcludeUserIDs: held.UserIDs,
+							ExcludeItemIDs: held.ItemIDs,
+						})
+						if err != nil {
+							return fmt.Errorf("item history pruning failed: %w", err)
+						}
+						if n > 0 {
+							l.Infof("Pruned %d expired item versions", n)
+						}
+						return nil
+					},
+				)
+			},
+			fx.ResultTags(jobsGroup),
+		),
+		fx.Annotate(
+			func(
+				cfg *config.HealthReportConfig,
+				logger *zap.SugaredLogger,
+				s *vaulthealthApp.Service,
+			) *scheduler.PeriodicJob {
+				l := logger.Named("vault-health-report")
+				return scheduler.NewPeriodicJob(l, "vault-health-report", cfg.Interval,
+					func(ctx context.Context) error {
+						if err := s.RunScheduled(ctx); err != nil {
+							return fmt.Errorf("vault health reporting failed: %w", err)
+						}
+						return nil
+					},
+				)
+			},
+			fx.ResultTags(jobsGroup),
+		),
+		fx.Annotate(
+			func(
+				cfg *config.CheckoutConfig,
+				logger *zap.SugaredLogger,
+				s *checkoutApp.Service,
+			) *scheduler.PeriodicJob {
+				l := logger.Named("credential-check-in")
+				return scheduler.NewPeriodicJob(l, "credential-check-in", cfg.SweepInterval,
+					func(ctx context.Context) error {
+						n, err := s.CheckInExpired(ctx)
+						if err != nil {
+							return fmt.Errorf("credential check-in failed: %w", err)
+						}
+						if n > 0 {
+							l.Infof("Checked in %d expired credential check-outs", n)
+						}
+						return nil
+					},
+				)
+			},
+			fx.ResultTags(jobsGroup),
+		),
+		fx.Annotate(
+			func(
+				cfg *config.RotationConfig,
+				logger *zap.SugaredLogger,
+				s *rotationApp.Service,
+			) *scheduler.PeriodicJob {
+				l := logger.Named("credential-rotation")
+				return scheduler.NewPeriodicJob(l, "credential-rotation", cfg.CheckInterval,
+					func(ctx context.Context) error {
+						n, err := s.RotateDue(ctx)
+						if n > 0 {
+							l.Infof("Rotated %d credentials", n)
+						}
+						if err != nil {
+							return fmt.Errorf("credential rotation failed: %w", err)
+						}
+						return nil
+					},
+				)
+			},
+			fx.ResultTags(jobsGroup),
+		),
+		fx.Annotate(
+			func(
+				cfg *config.StorageGCConfig,
+				logger *zap.SugaredLogger,
+				s *storagegcApp.Service,
+			) *scheduler.PeriodicJob {
+				l := logger.Named("storage-reconciliation")
+				return scheduler.NewPeriodicJob(l, "storage-reconciliation", cfg.Interval,
+					func(ctx context.Context) error {
+						report, err := s.Reconcile(ctx)
+						if err != nil {
+							return fmt.Errorf("storage reconciliation failed: %w", err)
+						}
+						if len(report.OrphanBlobs) > 0 || len(report.MissingBlobs) > 0 {
+							l.Warnf("Storage reconciliation found %d orphaned contents (%d removed) "+
+								"and %d files without content",
+								len(report.OrphanBlobs), report.Removed, len(report.MissingBlobs))
+						}
+						if len(report.SkippedUsers) > 0 {
+							l.Errorf("Storage reconciliation skipped %d users with unreadable file metadata",
+								len(report.SkippedUsers))
+						}
+						return nil
+					},
+				)
+			},
+			fx.ResultTags(jobsGroup),
+		),
+		fx.Annotate(
+			func(
+				cfg *config.NoteMergeConfig,
+				logger *zap.SugaredLogger,
+				s *noteApp.Service,
+			) *scheduler.PeriodicJob {
+				l := logger.Named("note-update-compaction")
+				return scheduler.NewPeriodicJob(l, "note-update-compaction", cfg.CompactInterval,
+					func(ctx context.Context) error {
+						n, err := s.Compact(ctx, cfg.CompactThreshold)
+						if n > 0 {
+							l.Infof("Compacted the updates of %d notes", n)
+						}
+						if err != nil {
+							return fmt.Errorf("note update compaction failed: %w", err)
+						}
+						return nil
+					},
+				)
+			},
+			fx.ResultTags(jobsGroup),
+		),
+		fx.Annotate(
+			func(
+				cfg *config.StatsConfig,
+				logger *zap.SugaredLogger,
+				s *statsApp.Service,
+			) *scheduler.PeriodicJob {
+				l := logger.Named("stats-rollup")
+				return scheduler.NewPeriodicJob(l, "stats-rollup", cfg.RollupInterval,
+					func(ctx context.Context) error {
+						if _, err := s.Rollup(ctx); err != nil {
+							return fmt.Errorf("usage statistics rollup failed: %w", err)
+						}
+						return nil
+					},
+				)
+			},
+			fx.ResultTags(jobsGroup),
+		),
+		fx.Annotate(
+			func(logger *zap.SugaredLogger, s *telemetryApp.Service) *scheduler.PeriodicJob {
+				l := logger.Named("telemetry-ping")
+				return scheduler.NewPeriodicJob(l, "telemetry-ping", s.Interval(),
+					func(ctx context.Context) error {
+						if err := s.Send(ctx); err != nil {
+							return fmt.Errorf("telemetry ping failed: %w", err)
+						}
+						return nil
+					},
+				)
+			},
+			fx.ResultTags(jobsGroup),
+		),
+		fx.Annotate(
+			func(logger *zap.SugaredLogger, s *updatecheckApp.Service) *scheduler.PeriodicJob {
+				l := logger.Named("update-check")
+				return scheduler.NewPeriodicJob(l, "update-check", s.Interval(),
+					func(ctx context.Context) error {
+						status, err := s.Check(ctx)
+						if err != nil {
+							return fmt.Errorf("update check failed: %w", err)
+						}
+						if status.Available {
+							l.Warnf("Update available: version %s is released, running %s; see %s",
+								status.Latest, status.Current, status.URL)
+						}
+						return nil
+					},
+				)
+			},
+			fx.ResultTags(jobsGroup),
+		),
+		fx.Annotate(
+			func(logger *zap.SugaredLogger, s *recordingApp.Service) *scheduler.PeriodicJob {
+				l := logger.Named("recording-purge")
+				return scheduler.NewPeriodicJob(l, "recording-purge", s.PurgeInterval(),
+					func(ctx context.Context) error {
+						n, err := s.Purge(ctx)
+						if err != nil {
+							return fmt.Errorf("recording purge failed: %w", err)
+						}
+						if n > 0 {
+							l.Infof("Purged %d expired request recordings", n)
+						}
+						return nil
+					},
+				)
+			},
+			fx.ResultTags(jobsGroup),
+		),
+		fx.Annotate(
+			func(
+				cfg *config.RetentionConfig,
+				logger *zap.SugaredLogger,
+				s *retentionApp.Service,
+			) *scheduler.PeriodicJob {
+				l := logger.Named("retention-enforcement")
+				return scheduler.NewPeriodicJob(l, "retention-enforcement", cfg.Interval,
+					func(ctx context.Context) error {
+						report, err := s.Enforce(ctx)
+						if report != nil && (report.Versions > 0 || report.AuditRecords > 0) {
+							l.Infof("Purged %d item versions and %d reveal records past retention",
+								report.Versions, report.AuditRecords)
+						}
+						if err != nil {
+							return fmt.Errorf("retention enforcement failed: %w", err)
+						}
+						return nil
+					},
+				)
+			},
+			fx.ResultTags(jobsGroup),
+		),
+	),
+)
+
+// jobsParams collects all periodic jobs from the jobs value group.
+type jobsParams struct {
+	fx.In
+
+	// Jobs lists the registered periodic jobs.
+	Jobs []*scheduler.PeriodicJob `group:"jobs"`
+}
+
+// runJobs registers lifecycle hooks starting and stopping all periodic jobs with fx.
+func runJobs(lc fx.Lifecycle, p jobsParams) {
+	for _, job := range p.Jobs {
+		lc.Append(fx.Hook{
+			OnStart: job.Start,
+			OnStop:  job.Stop,
+		})
+	}
+}
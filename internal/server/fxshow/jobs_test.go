@@ -0,0 +1,42 @@
+package fxshow
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/scheduler"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+	"go.uber.org/zap"
+)
+
+func TestJobsModule(t *testing.T) {
+	t.Parallel()
+
+	assert.NotNil(t, jobsModule, "jobsModule should not be nil")
+	assert.NotNil(t, observabilityModule, "observabilityModule should not be nil")
+}
+
+func TestRunJobs(t *testing.T) {
+	t.Parallel()
+
+	var runs atomic.Int32
+	job := scheduler.NewPeriodicJob(zap.NewNop().Sugar(), "test", time.Millisecond, func(ctx context.Context) error {
+		runs.Add(1)
+		return nil
+	})
+
+	app := fxtest.New(t,
+		fx.Provide(fx.Annotate(func() *scheduler.PeriodicJob { return job }, fx.ResultTags(jobsGroup))),
+		fx.Invoke(runJobs),
+		fx.NopLogger,
+	)
+
+	app.RequireStart()
+	assert.Eventually(t, func() bool { return runs.Load() > 0 }, time.Second, time.Millisecond,
+		"job should run after application start")
+	app.RequireStop()
+}
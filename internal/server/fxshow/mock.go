@@ -0,0 +1,317 @@
+package fxshow
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	applicationAccesscontrol "github.com/gdyunin/aegis-vault-keeper/internal/server/application/accesscontrol"
+	applicationAccesspolicy "github.com/gdyunin/aegis-vault-keeper/internal/server/application/accesspolicy"
+	applicationAcmeaccount "github.com/gdyunin/aegis-vault-keeper/internal/server/application/acmeaccount"
+	applicationAdminapproval "github.com/gdyunin/aegis-vault-keeper/internal/server/application/adminapproval"
+	applicationApproval "github.com/gdyunin/aegis-vault-keeper/internal/server/application/approval"
+	applicationAuditexport "github.com/gdyunin/aegis-vault-keeper/internal/server/application/auditexport"
+	applicationAuth "github.com/gdyunin/aegis-vault-keeper/internal/server/application/auth"
+	applicationBankcard "github.com/gdyunin/aegis-vault-keeper/internal/server/application/bankcard"
+	applicationCheckout "github.com/gdyunin/aegis-vault-keeper/internal/server/application/checkout"
+	applicationCredential "github.com/gdyunin/aegis-vault-keeper/internal/server/application/credential"
+	applicationDatasync "github.com/gdyunin/aegis-vault-keeper/internal/server/application/datasync"
+	applicationDevicekey "github.com/gdyunin/aegis-vault-keeper/internal/server/application/devicekey"
+	applicationDirectory "github.com/gdyunin/aegis-vault-keeper/internal/server/application/directory"
+	applicationFeature "github.com/gdyunin/aegis-vault-keeper/internal/server/application/feature"
+	applicationFiledata "github.com/gdyunin/aegis-vault-keeper/internal/server/application/filedata"
+	applicationIntegrity "github.com/gdyunin/aegis-vault-keeper/internal/server/application/integrity"
+	applicationInvite "github.com/gdyunin/aegis-vault-keeper/internal/server/application/invite"
+	applicationItemaccess "github.com/gdyunin/aegis-vault-keeper/internal/server/application/itemaccess"
+	applicationItemorder "github.com/gdyunin/aegis-vault-keeper/internal/server/application/itemorder"
+	applicationItempath "github.com/gdyunin/aegis-vault-keeper/internal/server/application/itempath"
+	applicationItempolicy "github.com/gdyunin/aegis-vault-keeper/internal/server/application/itempolicy"
+	applicationItemtag "github.com/gdyunin/aegis-vault-keeper/internal/server/application/itemtag"
+	applicationLegalhold "github.com/gdyunin/aegis-vault-keeper/internal/server/application/legalhold"
+	applicationLicense "github.com/gdyunin/aegis-vault-keeper/internal/server/application/license"
+	applicationMachine "github.com/gdyunin/aegis-vault-keeper/internal/server/application/machine"
+	applicationNote "github.com/gdyunin/aegis-vault-keeper/internal/server/application/note"
+	applicationNotification "github.com/gdyunin/aegis-vault-keeper/internal/server/application/notification"
+	applicationOnboarding "github.com/gdyunin/aegis-vault-keeper/internal/server/application/onboarding"
+	applicationPlan "github.com/gdyunin/aegis-vault-keeper/internal/server/application/plan"
+	applicationPurge "github.com/gdyunin/aegis-vault-keeper/internal/server/application/purge"
+	applicationRecording "github.com/gdyunin/aegis-vault-keeper/internal/server/application/recording"
+	applicationRetention "github.com/gdyunin/aegis-vault-keeper/internal/server/application/retention"
+	applicationRotation "github.com/gdyunin/aegis-vault-keeper/internal/server/application/rotation"
+	applicationSigningkey "github.com/gdyunin/aegis-vault-keeper/internal/server/application/signingkey"
+	applicationStats "github.com/gdyunin/aegis-vault-keeper/internal/server/application/stats"
+	applicationStoragegc "github.com/gdyunin/aegis-vault-keeper/internal/server/application/storagegc"
+	applicationTelemetry "github.com/gdyunin/aegis-vault-keeper/internal/server/application/telemetry"
+	applicationVaulthealth "github.com/gdyunin/aegis-vault-keeper/internal/server/application/vaulthealth"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/audit"
+	middlewareDelivery "github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/middleware"
+	repositoryFilestorage "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/filestorage"
+	repositoryKeyprv "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/keyprv"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/memory"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/security"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/seed"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+// BuildMockApp constructs the application in mock server mode: the full API served from in-memory
+// repositories seeded with deterministic fixture data, without a database or background jobs.
+// File content is kept in a temporary directory removed on shutdown.
+func BuildMockApp() *fx.App {
+	return fx.New(
+		configModule,
+		loggerModule,
+		observabilityModule,
+		mockRepositoryModule,
+		applicationModule,
+		deliveryModule,
+		systemdModule,
+		fx.Invoke(
+			seedMockData,
+			runHTTPServer,
+			runSystemdNotifier,
+		),
+	)
+}
+
+// mockRepositoryModule provides the in-memory repositories of the mock server mode.
+// It declares the same interfaces as repositoryModule, so the application and delivery layers are unchanged.
+var mockRepositoryModule = fx.Module("repository",
+	fx.Provide(
+		memory.NewUserRepository,
+		memory.NewGroupRepository,
+		memory.NewBankCardRepository,
+		memory.NewCredentialRepository,
+		memory.NewNoteRepository,
+		memory.NewFileDataRepository,
+		memory.NewDeviceRepository,
+		memory.NewFolderRepository,
+		memory.NewItemTagRepository,
+		memory.NewItemPathRepository,
+		memory.NewItemRevealRepository,
+		memory.NewNoteUpdateRepository,
+		memory.NewApprovalRepository,
+		memory.NewCheckoutRepository,
+		memory.NewRotationRepository,
+		newMockFileStorage,
+	),
+	exposeAs[*memory.UserRepository](
+		new(applicationAuth.Repository),
+		new(security.UserKeyRepository),
+		new(applicationVaulthealth.UserDirectory),
+		new(applicationStoragegc.UserDirectory),
+		new(applicationDirectory.UserRepository),
+		new(applicationNotification.UserRepository),
+		new(applicationLegalhold.UserRepository),
+		new(applicationOnboarding.UserRepository),
+	),
+	provideWithInterfaces[*security.UserKeyProvider](
+		security.NewUserKeyProvider,
+		new(repositoryKeyprv.UserKeyProvider),
+	),
+	provideWithInterfaces[*memory.Verifier](
+		memory.NewVerifier,
+		new(applicationIntegrity.Verifier),
+	),
+	provideWithInterfaces[*memory.UnitOfWork](
+		memory.NewUnitOfWork,
+		new(applicationDatasync.UnitOfWork),
+		new(applicationDirectory.UnitOfWork),
+		new(applicationFiledata.UnitOfWork),
+		new(applicationNote.UnitOfWork),
+		new(seed.UnitOfWork),
+		new(applicationInvite.UnitOfWork),
+		new(applicationPurge.UnitOfWork),
+		new(middlewareDelivery.DryRunner),
+	),
+	exposeAs[*memory.BankCardRepository](new(applicationBankcard.Repository)),
+	exposeAs[*memory.CredentialRepository](new(applicationCredential.Repository)),
+	exposeAs[*memory.NoteRepository](new(applicationNote.Repository)),
+	exposeAs[*memory.NoteUpdateRepository](
+		new(applicationNote.UpdateRepository),
+	),
+	exposeAs[*memory.FileDataRepository](
+		new(applicationFiledata.Repository),
+		new(applicationStoragegc.MetadataRepository),
+	),
+	exposeAs[*memory.FolderRepository](new(applicationFiledata.FolderRepository)),
+	provideWithInterfaces[*memory.AcmeAccountRepository](
+		memory.NewAcmeAccountRepository,
+		new(applicationAcmeaccount.Repository),
+	),
+	provideWithInterfaces[*memory.AccessRuleRepository](
+		memory.NewAccessRuleRepository,
+		new(applicationAccesscontrol.Repository),
+	),
+	exposeAs[*memory.DeviceRepository](new(applicationAuth.DeviceRepository)),
+	provideWithInterfaces[*memory.FeatureRepository](
+		memory.NewFeatureRepository,
+		new(applicationFeature.Repository),
+	),
+	provideWithInterfaces[*memory.SigningKeyRepository](
+		memory.NewSigningKeyRepository,
+		new(applicationSigningkey.Repository),
+	),
+	provideWithInterfaces[*memory.DeviceKeyRepository](
+		memory.NewDeviceKeyRepository,
+		new(applicationDevicekey.Repository),
+	),
+	provideWithInterfaces[*memory.NotificationRepository](
+		memory.NewNotificationRepository,
+		new(applicationNotification.Repository),
+	),
+	provideWithInterfaces[*memory.PushSubscriptionRepository](
+		memory.NewPushSubscriptionRepository,
+		new(applicationNotification.PushRepository),
+	),
+	provideWithInterfaces[*memory.AccessPolicyRepository](
+		memory.NewAccessPolicyRepository,
+		new(applicationAccesspolicy.Repository),
+	),
+	exposeAs[*memory.ItemTagRepository](
+		new(applicationItemtag.Repository),
+		new(applicationAccesscontrol.TagRepository),
+		new(applicationApproval.TagRepository),
+		new(applicationBankcard.TagRepository),
+		new(applicationCredential.TagRepository),
+		new(applicationNote.TagRepository),
+		new(applicationFiledata.TagRepository),
+	),
+	exposeAs[*memory.ItemRevealRepository](
+		new(applicationItemaccess.Repository),
+	),
+	provideWithInterfaces[*memory.ItemOrderRepository](
+		memory.NewItemOrderRepository,
+		new(applicationItemorder.Repository),
+		new(applicationBankcard.OrderRepository),
+		new(applicationCredential.OrderRepository),
+		new(applicationNote.OrderRepository),
+		new(applicationFiledata.OrderRepository),
+	),
+	exposeAs[*memory.ItemPathRepository](
+		new(applicationItempath.Repository),
+		new(applicationMachine.PathRepository),
+	),
+	exposeAs[*memory.ApprovalRepository](
+		new(applicationApproval.Repository),
+	),
+	exposeAs[*memory.CheckoutRepository](
+		new(applicationCheckout.Repository),
+	),
+	exposeAs[*memory.RotationRepository](
+		new(applicationRotation.Repository),
+	),
+	provideWithInterfaces[*memory.MachineRepository](
+		memory.NewMachineRepository,
+		new(applicationMachine.Repository),
+	),
+	provideWithInterfaces[*memory.PlanRepository](
+		memory.NewPlanRepository,
+		new(applicationPlan.Repository),
+		new(applicationPlan.SubscriptionRepository),
+	),
+	provideWithInterfaces[*memory.LicenseRepository](
+		memory.NewLicenseRepository,
+		new(applicationLicense.Repository),
+	),
+	provideWithInterfaces[*memory.StatsRepository](
+		memory.NewStatsRepository,
+		new(applicationStats.Repository),
+		new(applicationTelemetry.UsageCollector),
+	),
+	provideWithInterfaces[*memory.PurgeRepository](
+		memory.NewPurgeRepository,
+		new(applicationPurge.Repository),
+	),
+	provideWithInterfaces[*memory.RecordingRepository](
+		memory.NewRecordingRepository,
+		new(applicationRecording.Repository),
+	),
+	provideWithInterfaces[*memory.RetentionRepository](
+		memory.NewRetentionRepository,
+		new(applicationRetention.Repository),
+	),
+	provideWithInterfaces[*memory.LegalHoldRepository](
+		memory.NewLegalHoldRepository,
+		new(applicationLegalhold.Repository),
+	),
+	provideWithInterfaces[*memory.ItemPolicyRepository](
+		memory.NewItemPolicyRepository,
+		new(applicationItempolicy.Repository),
+	),
+	provideWithInterfaces[*memory.AdminApprovalRepository](
+		memory.NewAdminApprovalRepository,
+		new(applicationAdminapproval.Repository),
+	),
+	provideWithInterfaces[*memory.AuditLogRepository](
+		memory.NewAuditLogRepository,
+		new(audit.Store),
+		new(applicationAuditexport.Repository),
+	),
+	provideWithInterfaces[*memory.InviteRepository](
+		memory.NewInviteRepository,
+		new(applicationInvite.Repository),
+	),
+	exposeAs[*memory.GroupRepository](
+		new(applicationDirectory.GroupRepository),
+		new(applicationCheckout.GroupRepository),
+		new(applicationInvite.GroupRepository),
+		new(applicationRetention.GroupRepository),
+		new(applicationItempolicy.GroupRepository),
+	),
+	exposeAs[*repositoryFilestorage.Repository](
+		new(applicationFiledata.FileStorageRepository),
+		new(applicationVaulthealth.StorageUsageMeter),
+		new(applicationStoragegc.BlobStore),
+		new(applicationOnboarding.StorageProber),
+		new(memory.FileStore),
+	),
+)
+
+// newMockFileStorage creates a file storage in a new temporary directory that is removed on shutdown.
+func newMockFileStorage(
+	lc fx.Lifecycle,
+	kprv repositoryKeyprv.UserKeyProvider,
+) (*repositoryFilestorage.Repository, error) {
+	dir, err := os.MkdirTemp("", "aegis-vault-keeper-mock-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create mock file storage directory: %w", err)
+	}
+	lc.Append(fx.Hook{
+		OnStop: func(context.Context) error { return os.RemoveAll(dir) },
+	})
+	return repositoryFilestorage.NewRepository(dir, kprv), nil
+}
+
+// seedMockData stores the fixture data in the in-memory repositories before the server starts.
+func seedMockData(
+	hasher applicationAuth.PasswordHasherVerificator,
+	fs memory.FileStore,
+	logger *zap.SugaredLogger,
+	users *memory.UserRepository,
+	credentials *memory.CredentialRepository,
+	cards *memory.BankCardRepository,
+	notes *memory.NoteRepository,
+	files *memory.FileDataRepository,
+	folders *memory.FolderRepository,
+	tags *memory.ItemTagRepository,
+	paths *memory.ItemPathRepository,
+) error {
+	fixtures := &memory.Fixtures{
+		Users:       users,
+		Credentials: credentials,
+		BankCards:   cards,
+		Notes:       notes,
+		Files:       files,
+		Folders:     folders,
+		Tags:        tags,
+		Paths:       paths,
+		FileStore:   fs,
+	}
+	if err := fixtures.Seed(context.Background(), hasher); err != nil {
+		return fmt.Errorf("failed to seed mock data: %w", err)
+	}
+	logger.Warnf("Mock server mode: data is kept in memory only; sign in as %q with password %q",
+		memory.FixtureLogin, memory.FixturePassword)
+	return nil
+}
@@ -0,0 +1,127 @@
+package fxshow
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/config"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/memory"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+// mockTestConfig returns the configuration the mock server tests run with instead of the loaded one.
+func mockTestConfig() *config.Config {
+	return &config.Config{
+		MasterKey:           make([]byte, 32),
+		IntegrityKey:        make([]byte, 32),
+		LoggerLevel:         "info",
+		AccessTokenLifeTime: time.Hour,
+	}
+}
+
+func TestBuildMockApp(t *testing.T) {
+	var app *fx.App
+	assert.NotPanics(t, func() {
+		app = BuildMockApp()
+	}, "BuildMockApp should not panic")
+	assert.NotNil(t, app, "BuildMockApp should return a non-nil fx.App")
+}
+
+func TestMockRepositoryModuleGraph(t *testing.T) {
+	t.Parallel()
+
+	err := fx.ValidateApp(
+		configModule,
+		fx.Replace(mockTestConfig()),
+		loggerModule,
+		observabilityModule,
+		mockRepositoryModule,
+		applicationModule,
+		deliveryModule,
+		fx.Invoke(seedMockData, runHTTPServer),
+	)
+	require.NoError(t, err, "mock server dependency graph should be complete")
+}
+
+func TestMockServerFixtures(t *testing.T) {
+	t.Parallel()
+
+	// routes registers the API routes.
+	var routes delivery.RouteConfigurator
+	// middlewares registers the API middlewares.
+	var middlewares delivery.MiddlewareConfigurator
+	app := fxtest.New(t,
+		configModule,
+		fx.Replace(mockTestConfig()),
+		loggerModule,
+		observabilityModule,
+		mockRepositoryModule,
+		applicationModule,
+		deliveryModule,
+		fx.Invoke(seedMockData),
+		fx.Populate(&routes, &middlewares),
+	)
+	app.RequireStart()
+	defer app.RequireStop()
+
+	engine := gin.New()
+	middlewares.RegisterMiddlewares(engine)
+	routes.RegisterRoutes(engine)
+
+	do := func(method, path, body, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, req)
+		return w
+	}
+
+	login := do(http.MethodPost, "/api/auth/login",
+		`{"login":"`+memory.FixtureLogin+`","password":"`+memory.FixturePassword+`"}`, "")
+	require.Equal(t, http.StatusOK, login.Code, login.Body.String())
+	// token holds the issued access token.
+	var token struct {
+		// AccessToken contains the JWT of the fixture user.
+		AccessToken string `json:"access_token"`
+	}
+	require.NoError(t, json.Unmarshal(login.Body.Bytes(), &token))
+	require.NotEmpty(t, token.AccessToken)
+
+	tests := []struct {
+		name     string
+		path     string
+		contains string
+	}{
+		{name: "credentials", path: "/api/items/credentials", contains: "GitHub"},
+		{name: "bank cards", path: "/api/items/bankcards", contains: "4111"},
+		{name: "notes", path: "/api/items/notes", contains: "Home Wi-Fi"},
+		{name: "files", path: "/api/items/filedata/", contains: "tax-return-2024.txt"},
+		{name: "folders", path: "/api/items/folders/", contains: "documents"},
+		{name: "tags", path: "/api/items/tags", contains: "finance"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := do(http.MethodGet, tt.path, "", token.AccessToken)
+			assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+			assert.Contains(t, w.Body.String(), tt.contains)
+		})
+	}
+
+	created := do(http.MethodPost, "/api/items/notes", `{"note":"created in mock mode"}`, token.AccessToken)
+	assert.Equal(t, http.StatusCreated, created.Code, created.Body.String())
+	notes := do(http.MethodGet, "/api/items/notes", "", token.AccessToken)
+	assert.Contains(t, notes.Body.String(), "created in mock mode")
+}
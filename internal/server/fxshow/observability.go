@@ -0,0 +1,97 @@
+package fxshow
+
+import (
+	accesscontrolApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/accesscontrol"
+	accesspolicyApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/accesspolicy"
+	acmeaccountApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/acmeaccount"
+	adminapprovalApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/adminapproval"
+	approvalApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/approval"
+	auditexportApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/auditexport"
+	authApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/auth"
+	authzApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/authz"
+	bruteforceApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/bruteforce"
+	checkoutApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/checkout"
+	devicekeyApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/devicekey"
+	directoryApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/directory"
+	featureApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/feature"
+	integrityApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/integrity"
+	inviteApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/invite"
+	itemaccessApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/itemaccess"
+	itempolicyApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/itempolicy"
+	itemtagApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/itemtag"
+	legalholdApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/legalhold"
+	licenseApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/license"
+	machineApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/machine"
+	maintenanceApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/maintenance"
+	notificationApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/notification"
+	onboardingApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/onboarding"
+	purgeApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/purge"
+	recordingApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/recording"
+	reportingApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/reporting"
+	retentionApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/retention"
+	rotationApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/rotation"
+	signingkeyApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/signingkey"
+	statsApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/stats"
+	storagegcApp "github.com/gdyunin/aegis-vault-keeper/internal/server/application/storagegc"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/audit"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/middleware"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/metrics"
+	repositoryDB "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/db"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+// observabilityModule provides metrics and audit dependencies.
+// Configures the shared metrics registry and the audit event recorder persisting the audit trail.
+var observabilityModule = fx.Module("observability",
+	provideWithInterfaces[*metrics.Registry](
+		metrics.NewRegistry,
+		new(integrityApp.MetricsRecorder),
+		new(storagegcApp.MetricsRecorder),
+		new(delivery.MetricsSnapshotter),
+		new(middleware.TimeoutRecorder),
+		new(middleware.ChaosRecorder),
+		new(middleware.RequestStatsRecorder),
+		new(statsApp.MetricsSnapshotter),
+		new(repositoryDB.ChaosRecorder),
+	),
+	provideWithInterfaces[*audit.TrailRecorder](
+		func(lc fx.Lifecycle, logger *zap.SugaredLogger, store audit.Store) *audit.TrailRecorder {
+			auditLogger := logger.Named("audit")
+			r := audit.NewTrailRecorder(audit.NewLogRecorder(auditLogger), store, auditLogger)
+			lc.Append(fx.Hook{OnStart: r.Start, OnStop: r.Stop})
+			return r
+		},
+		new(integrityApp.AuditRecorder),
+		new(accesscontrolApp.AuditRecorder),
+		new(authApp.AuditRecorder),
+		new(maintenanceApp.AuditRecorder),
+		new(featureApp.AuditRecorder),
+		new(signingkeyApp.AuditRecorder),
+		new(devicekeyApp.AuditRecorder),
+		new(directoryApp.AuditRecorder),
+		new(notificationApp.AuditRecorder),
+		new(accesspolicyApp.AuditRecorder),
+		new(itemtagApp.AuditRecorder),
+		new(itemaccessApp.AuditRecorder),
+		new(reportingApp.AuditRecorder),
+		new(authzApp.AuditRecorder),
+		new(approvalApp.AuditRecorder),
+		new(checkoutApp.AuditRecorder),
+		new(rotationApp.AuditRecorder),
+		new(machineApp.AuditRecorder),
+		new(inviteApp.AuditRecorder),
+		new(acmeaccountApp.AuditRecorder),
+		new(bruteforceApp.AuditRecorder),
+		new(licenseApp.AuditRecorder),
+		new(recordingApp.AuditRecorder),
+		new(retentionApp.AuditRecorder),
+		new(legalholdApp.AuditRecorder),
+		new(itempolicyApp.AuditRecorder),
+		new(onboardingApp.AuditRecorder),
+		new(purgeApp.AuditRecorder),
+		new(auditexportApp.AuditRecorder),
+		new(adminapprovalApp.AuditRecorder),
+	),
+)
@@ -2,24 +2,97 @@ package fxshow
 
 import (
 	"context"
+	"fmt"
 
+	applicationAccesscontrol "github.com/gdyunin/aegis-vault-keeper/internal/server/application/accesscontrol"
+	applicationAccesspolicy "github.com/gdyunin/aegis-vault-keeper/internal/server/application/accesspolicy"
+	applicationAcmeaccount "github.com/gdyunin/aegis-vault-keeper/internal/server/application/acmeaccount"
+	applicationAdminapproval "github.com/gdyunin/aegis-vault-keeper/internal/server/application/adminapproval"
+	applicationApproval "github.com/gdyunin/aegis-vault-keeper/internal/server/application/approval"
+	applicationAuditexport "github.com/gdyunin/aegis-vault-keeper/internal/server/application/auditexport"
 	applicationAuth "github.com/gdyunin/aegis-vault-keeper/internal/server/application/auth"
 	applicationBankcard "github.com/gdyunin/aegis-vault-keeper/internal/server/application/bankcard"
+	applicationCheckout "github.com/gdyunin/aegis-vault-keeper/internal/server/application/checkout"
 	applicationCredential "github.com/gdyunin/aegis-vault-keeper/internal/server/application/credential"
+	applicationDatasync "github.com/gdyunin/aegis-vault-keeper/internal/server/application/datasync"
+	applicationDevicekey "github.com/gdyunin/aegis-vault-keeper/internal/server/application/devicekey"
+	applicationDirectory "github.com/gdyunin/aegis-vault-keeper/internal/server/application/directory"
+	applicationFeature "github.com/gdyunin/aegis-vault-keeper/internal/server/application/feature"
 	applicationFiledata "github.com/gdyunin/aegis-vault-keeper/internal/server/application/filedata"
+	applicationIntegrity "github.com/gdyunin/aegis-vault-keeper/internal/server/application/integrity"
+	applicationInvite "github.com/gdyunin/aegis-vault-keeper/internal/server/application/invite"
+	applicationItemaccess "github.com/gdyunin/aegis-vault-keeper/internal/server/application/itemaccess"
+	applicationItemorder "github.com/gdyunin/aegis-vault-keeper/internal/server/application/itemorder"
+	applicationItempath "github.com/gdyunin/aegis-vault-keeper/internal/server/application/itempath"
+	applicationItempolicy "github.com/gdyunin/aegis-vault-keeper/internal/server/application/itempolicy"
+	applicationItemtag "github.com/gdyunin/aegis-vault-keeper/internal/server/application/itemtag"
+	applicationLegalhold "github.com/gdyunin/aegis-vault-keeper/internal/server/application/legalhold"
+	applicationLicense "github.com/gdyunin/aegis-vault-keeper/internal/server/application/license"
+	applicationMachine "github.com/gdyunin/aegis-vault-keeper/internal/server/application/machine"
 	applicationNote "github.com/gdyunin/aegis-vault-keeper/internal/server/application/note"
+	applicationNotification "github.com/gdyunin/aegis-vault-keeper/internal/server/application/notification"
+	applicationOnboarding "github.com/gdyunin/aegis-vault-keeper/internal/server/application/onboarding"
+	applicationPlan "github.com/gdyunin/aegis-vault-keeper/internal/server/application/plan"
+	applicationPurge "github.com/gdyunin/aegis-vault-keeper/internal/server/application/purge"
+	applicationRecording "github.com/gdyunin/aegis-vault-keeper/internal/server/application/recording"
+	applicationRetention "github.com/gdyunin/aegis-vault-keeper/internal/server/application/retention"
+	applicationRotation "github.com/gdyunin/aegis-vault-keeper/internal/server/application/rotation"
+	applicationSigningkey "github.com/gdyunin/aegis-vault-keeper/internal/server/application/signingkey"
+	applicationStats "github.com/gdyunin/aegis-vault-keeper/internal/server/application/stats"
+	applicationStoragegc "github.com/gdyunin/aegis-vault-keeper/internal/server/application/storagegc"
+	applicationTelemetry "github.com/gdyunin/aegis-vault-keeper/internal/server/application/telemetry"
+	applicationVaulthealth "github.com/gdyunin/aegis-vault-keeper/internal/server/application/vaulthealth"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/audit"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/config"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/database"
+	middlewareDelivery "github.com/gdyunin/aegis-vault-keeper/internal/server/delivery/middleware"
+	repositoryAccesspolicy "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/accesspolicy"
+	repositoryAccessrule "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/accessrule"
+	repositoryAcmeaccount "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/acmeaccount"
+	repositoryAdminapproval "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/adminapproval"
+	repositoryApproval "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/approval"
+	repositoryAuditlog "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/auditlog"
 	repositoryAuth "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/auth"
 	repositoryBankcard "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/bankcard"
+	repositoryCheckout "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/checkout"
 	repositoryCredential "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/credential"
 	repositoryDB "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/db"
+	repositoryDevice "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/device"
+	repositoryDevicekey "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/devicekey"
+	repositoryFeature "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/feature"
+	repositoryFieldcrypt "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/fieldcrypt"
 	repositoryFiledata "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/filedata"
+	repositoryFilefolder "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/filefolder"
 	repositoryFilestorage "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/filestorage"
+	repositoryGroup "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/group"
+	repositoryHistory "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/history"
+	repositoryInvite "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/invite"
+	repositoryItemaccess "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/itemaccess"
+	repositoryItemorder "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/itemorder"
+	repositoryItempath "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/itempath"
+	repositoryItempolicy "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/itempolicy"
+	repositoryItemtag "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/itemtag"
 	repositoryKeyprv "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/keyprv"
+	repositoryLegalhold "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/legalhold"
+	repositoryLicense "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/license"
+	repositoryMachine "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/machine"
+	repositoryMiddleware "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/middleware"
 	repositoryNote "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/note"
+	repositoryNoteupdate "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/noteupdate"
+	repositoryNotification "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/notification"
+	repositoryPlan "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/plan"
+	repositoryPurge "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/purge"
+	repositoryPushsubscription "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/pushsubscription"
+	repositoryRecording "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/recording"
+	repositoryRetention "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/retention"
+	repositoryRotation "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/rotation"
+	repositoryRowsign "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/rowsign"
+	repositorySigningkey "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/signingkey"
+	repositoryStats "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/stats"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/security"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/seed"
 	"go.uber.org/fx"
+	"go.uber.org/zap"
 )
 
 // repositoryModule provides all repository layer dependencies.
@@ -31,11 +104,64 @@ var repositoryModule = fx.Module("repository",
 		},
 		new(applicationAuth.Repository),
 		new(security.UserKeyRepository),
+		new(applicationVaulthealth.UserDirectory),
+		new(applicationStoragegc.UserDirectory),
+		new(applicationDirectory.UserRepository),
+		new(applicationNotification.UserRepository),
+		new(applicationLegalhold.UserRepository),
+		new(applicationOnboarding.UserRepository),
 	),
 	provideWithInterfaces[*security.UserKeyProvider](
 		security.NewUserKeyProvider,
 		new(repositoryKeyprv.UserKeyProvider),
 	),
+	fx.Provide(
+		func(cfg *config.IntegrityConfig) *repositoryRowsign.Signer {
+			return repositoryRowsign.NewSigner(cfg.Key)
+		},
+	),
+	provideWithInterfaces[*repositoryRowsign.Verifier](
+		func(dbClient repositoryDB.DBClient, signer *repositoryRowsign.Signer) *repositoryRowsign.Verifier {
+			return repositoryRowsign.NewVerifier(dbClient, signer, signedTables...)
+		},
+		new(applicationIntegrity.Verifier),
+	),
+	fx.Provide(
+		func(dbClient repositoryDB.DBClient, signer *repositoryRowsign.Signer) *repositoryRowsign.Backfiller {
+			return repositoryRowsign.NewBackfiller(dbClient, signer, signedTables...)
+		},
+	),
+	fx.Provide(
+		func(dbClient repositoryDB.DBClient, kprv repositoryKeyprv.UserKeyProvider) *repositoryFieldcrypt.Rebinder {
+			return repositoryFieldcrypt.NewRebinder(dbClient, kprv, boundTables...)
+		},
+	),
+	fx.Provide(
+		func(cfg *config.DBConfig) repositoryMiddleware.RetryPolicy {
+			return repositoryMiddleware.RetryPolicy{MaxRetries: cfg.TxRetries, Backoff: cfg.TxRetryBackoff}
+		},
+	),
+	fx.Provide(
+		func(cfg *config.CryptoConfig) *repositoryFieldcrypt.Pipeline {
+			return repositoryFieldcrypt.NewPipeline(cfg.Workers)
+		},
+	),
+	provideWithInterfaces[*repositoryDB.UnitOfWork](
+		repositoryDB.NewUnitOfWork,
+		new(applicationDatasync.UnitOfWork),
+		new(applicationDirectory.UnitOfWork),
+		new(applicationFiledata.UnitOfWork),
+		new(applicationNote.UnitOfWork),
+		new(seed.UnitOfWork),
+		new(applicationInvite.UnitOfWork),
+		new(applicationPurge.UnitOfWork),
+		new(middlewareDelivery.DryRunner),
+	),
+	fx.Provide(
+		func(dbClient repositoryDB.DBClient) *repositoryHistory.Pruner {
+			return repositoryHistory.NewPruner(dbClient, "bank_cards", "credentials", "notes")
+		},
+	),
 	provideWithInterfaces[*repositoryBankcard.Repository](
 		repositoryBankcard.NewRepository,
 		new(applicationBankcard.Repository),
@@ -48,26 +174,177 @@ var repositoryModule = fx.Module("repository",
 		repositoryNote.NewRepository,
 		new(applicationNote.Repository),
 	),
+	provideWithInterfaces[*repositoryNoteupdate.Repository](
+		repositoryNoteupdate.NewRepository,
+		new(applicationNote.UpdateRepository),
+	),
 	provideWithInterfaces[*repositoryFiledata.Repository](
 		repositoryFiledata.NewRepository,
 		new(applicationFiledata.Repository),
+		new(applicationStoragegc.MetadataRepository),
+	),
+	provideWithInterfaces[*repositoryFilefolder.Repository](
+		repositoryFilefolder.NewRepository,
+		new(applicationFiledata.FolderRepository),
+	),
+	provideWithInterfaces[*repositoryAcmeaccount.Repository](
+		repositoryAcmeaccount.NewRepository,
+		new(applicationAcmeaccount.Repository),
+	),
+	provideWithInterfaces[*repositoryAccessrule.Repository](
+		repositoryAccessrule.NewRepository,
+		new(applicationAccesscontrol.Repository),
+	),
+	provideWithInterfaces[*repositoryDevice.Repository](
+		repositoryDevice.NewRepository,
+		new(applicationAuth.DeviceRepository),
+	),
+	provideWithInterfaces[*repositoryFeature.Repository](
+		repositoryFeature.NewRepository,
+		new(applicationFeature.Repository),
+	),
+	provideWithInterfaces[*repositorySigningkey.Repository](
+		repositorySigningkey.NewRepository,
+		new(applicationSigningkey.Repository),
+	),
+	provideWithInterfaces[*repositoryDevicekey.Repository](
+		repositoryDevicekey.NewRepository,
+		new(applicationDevicekey.Repository),
+	),
+	provideWithInterfaces[*repositoryNotification.Repository](
+		repositoryNotification.NewRepository,
+		new(applicationNotification.Repository),
+	),
+	provideWithInterfaces[*repositoryPushsubscription.Repository](
+		repositoryPushsubscription.NewRepository,
+		new(applicationNotification.PushRepository),
+	),
+	provideWithInterfaces[*repositoryAccesspolicy.Repository](
+		repositoryAccesspolicy.NewRepository,
+		new(applicationAccesspolicy.Repository),
+	),
+	provideWithInterfaces[*repositoryItemaccess.Repository](
+		repositoryItemaccess.NewRepository,
+		new(applicationItemaccess.Repository),
+	),
+	provideWithInterfaces[*repositoryPlan.Repository](
+		repositoryPlan.NewRepository,
+		new(applicationPlan.Repository),
+		new(applicationPlan.SubscriptionRepository),
+	),
+	provideWithInterfaces[*repositoryLicense.Repository](
+		repositoryLicense.NewRepository,
+		new(applicationLicense.Repository),
+	),
+	provideWithInterfaces[*repositoryStats.Repository](
+		repositoryStats.NewRepository,
+		new(applicationStats.Repository),
+		new(applicationTelemetry.UsageCollector),
+	),
+	provideWithInterfaces[*repositoryPurge.Repository](
+		repositoryPurge.NewRepository,
+		new(applicationPurge.Repository),
+	),
+	provideWithInterfaces[*repositoryRecording.Repository](
+		func(dbClient repositoryDB.DBClient, cfg *config.RecordingConfig) *repositoryRecording.Repository {
+			return repositoryRecording.NewRepository(dbClient, cfg.Key)
+		},
+		new(applicationRecording.Repository),
+	),
+	provideWithInterfaces[*repositoryRetention.Repository](
+		func(dbClient repositoryDB.DBClient) *repositoryRetention.Repository {
+			return repositoryRetention.NewRepository(dbClient, "bank_cards", "credentials", "notes")
+		},
+		new(applicationRetention.Repository),
+	),
+	provideWithInterfaces[*repositoryLegalhold.Repository](
+		repositoryLegalhold.NewRepository,
+		new(applicationLegalhold.Repository),
+	),
+	provideWithInterfaces[*repositoryItempolicy.Repository](
+		repositoryItempolicy.NewRepository,
+		new(applicationItempolicy.Repository),
+	),
+	provideWithInterfaces[*repositoryAdminapproval.Repository](
+		repositoryAdminapproval.NewRepository,
+		new(applicationAdminapproval.Repository),
+	),
+	provideWithInterfaces[*repositoryAuditlog.Repository](
+		repositoryAuditlog.NewRepository,
+		new(audit.Store),
+		new(applicationAuditexport.Repository),
+	),
+	provideWithInterfaces[*repositoryItemtag.Repository](
+		repositoryItemtag.NewRepository,
+		new(applicationItemtag.Repository),
+		new(applicationAccesscontrol.TagRepository),
+		new(applicationApproval.TagRepository),
+		new(applicationBankcard.TagRepository),
+		new(applicationCredential.TagRepository),
+		new(applicationNote.TagRepository),
+		new(applicationFiledata.TagRepository),
+	),
+	provideWithInterfaces[*repositoryItemorder.Repository](
+		repositoryItemorder.NewRepository,
+		new(applicationItemorder.Repository),
+		new(applicationBankcard.OrderRepository),
+		new(applicationCredential.OrderRepository),
+		new(applicationNote.OrderRepository),
+		new(applicationFiledata.OrderRepository),
+	),
+	provideWithInterfaces[*repositoryItempath.Repository](
+		repositoryItempath.NewRepository,
+		new(applicationItempath.Repository),
+		new(applicationMachine.PathRepository),
+	),
+	provideWithInterfaces[*repositoryApproval.Repository](
+		repositoryApproval.NewRepository,
+		new(applicationApproval.Repository),
+	),
+	provideWithInterfaces[*repositoryCheckout.Repository](
+		repositoryCheckout.NewRepository,
+		new(applicationCheckout.Repository),
+	),
+	provideWithInterfaces[*repositoryRotation.Repository](
+		repositoryRotation.NewRepository,
+		new(applicationRotation.Repository),
+	),
+	provideWithInterfaces[*repositoryMachine.Repository](
+		repositoryMachine.NewRepository,
+		new(applicationMachine.Repository),
+	),
+	provideWithInterfaces[*repositoryInvite.Repository](
+		repositoryInvite.NewRepository,
+		new(applicationInvite.Repository),
+	),
+	provideWithInterfaces[*repositoryGroup.Repository](
+		repositoryGroup.NewRepository,
+		new(applicationDirectory.GroupRepository),
+		new(applicationCheckout.GroupRepository),
+		new(applicationInvite.GroupRepository),
+		new(applicationRetention.GroupRepository),
+		new(applicationItempolicy.GroupRepository),
 	),
 	provideWithInterfaces[*repositoryFilestorage.Repository](
 		func(cfg *config.FileStorageConfig, kprv repositoryKeyprv.UserKeyProvider) *repositoryFilestorage.Repository {
 			return repositoryFilestorage.NewRepository(cfg.BasePath, kprv)
 		},
 		new(applicationFiledata.FileStorageRepository),
+		new(applicationVaulthealth.StorageUsageMeter),
+		new(applicationStoragegc.BlobStore),
+		new(applicationOnboarding.StorageProber),
 	),
 	provideWithInterfaces[*database.Client](
 		func(cfg *config.DBConfig) (*database.Client, error) {
 			return database.NewClient(&database.Config{
-				Host:     cfg.Host,
-				User:     cfg.User,
-				Password: cfg.Password,
-				DBName:   cfg.DBName,
-				SSLMode:  cfg.SSLMode,
-				Port:     cfg.Port,
-				Timeout:  cfg.Timeout,
+				Host:       cfg.Host,
+				User:       cfg.User,
+				Password:   cfg.Password,
+				DBName:     cfg.DBName,
+				SSLMode:    cfg.SSLMode,
+				Port:       cfg.Port,
+				Timeout:    cfg.Timeout,
+				RLSEnabled: cfg.RLSEnabled,
 			})
 		},
 		new(repositoryDB.DBClient),
@@ -75,6 +352,35 @@ var repositoryModule = fx.Module("repository",
 	),
 )
 
+// signedTables lists the tables whose rows carry integrity signatures.
+var signedTables = []repositoryRowsign.Table{
+	repositoryBankcard.SignedTable,
+	repositoryCredential.SignedTable,
+	repositoryNote.SignedTable,
+	repositoryNoteupdate.SignedTable,
+	repositoryFiledata.SignedTable,
+	repositoryFilefolder.SignedTable,
+	repositoryAcmeaccount.SignedTable,
+}
+
+// boundTables lists the tables, with their retained versions, that may hold fields sealed before encrypted fields
+// were bound to their items.
+var boundTables = []repositoryFieldcrypt.BoundTable{
+	repositoryBankcard.BoundTable,
+	historyBoundTable(repositoryBankcard.BoundTable),
+	repositoryCredential.BoundTable,
+	historyBoundTable(repositoryCredential.BoundTable),
+	repositoryNote.BoundTable,
+	historyBoundTable(repositoryNote.BoundTable),
+	repositoryFiledata.BoundTable,
+}
+
+// historyBoundTable describes the history table retaining versions of the specified bound item table.
+func historyBoundTable(t repositoryFieldcrypt.BoundTable) repositoryFieldcrypt.BoundTable {
+	t.Name = repositoryHistory.Table(t.Name)
+	return t
+}
+
 // PingCloser interface for database clients that support connectivity testing and graceful shutdown.
 type PingCloser interface {
 	Ping(context.Context) error
@@ -88,3 +394,38 @@ func runDatabaseClient(lc fx.Lifecycle, pc PingCloser) {
 		OnStop:  pc.Close,
 	})
 }
+
+// runFieldRebind registers a lifecycle hook binding the fields stored before encrypted fields were bound to their
+// items, so they can be opened afterwards. It must be registered after runDatabaseClient and before
+// runRowSignatureBackfill, which signs the rebound rows.
+func runFieldRebind(lc fx.Lifecycle, logger *zap.SugaredLogger, r *repositoryFieldcrypt.Rebinder) {
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			n, err := r.Rebind(ctx)
+			if err != nil {
+				return fmt.Errorf("field rebind failed: %w", err)
+			}
+			if n > 0 {
+				logger.Named("field-rebind").Infof("Bound fields of %d rows stored before field binding", n)
+			}
+			return nil
+		},
+	})
+}
+
+// runRowSignatureBackfill registers a lifecycle hook signing the rows stored before row signatures were introduced,
+// so they pass verification on their first read. It must be registered after runDatabaseClient.
+func runRowSignatureBackfill(lc fx.Lifecycle, logger *zap.SugaredLogger, b *repositoryRowsign.Backfiller) {
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			n, err := b.Backfill(ctx)
+			if err != nil {
+				return fmt.Errorf("row signature backfill failed: %w", err)
+			}
+			if n > 0 {
+				logger.Named("row-signature-backfill").Infof("Signed %d rows stored without a signature", n)
+			}
+			return nil
+		},
+	})
+}
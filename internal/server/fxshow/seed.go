@@ -0,0 +1,47 @@
+package fxshow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/seed"
+	"go.uber.org/fx"
+)
+
+// seedModule provides the fixture loader.
+var seedModule = fx.Module("seed",
+	fx.Provide(seed.NewLoader),
+)
+
+// RunSeed loads the fixture document at path into the database and the file storage
+// using the server configuration.
+func RunSeed(ctx context.Context, path string) (res *seed.Result, err error) {
+	doc, err := seed.ReadDocument(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var l *seed.Loader
+	app := fx.New(
+		configModule,
+		loggerModule,
+		observabilityModule,
+		repositoryModule,
+		applicationModule,
+		seedModule,
+		fx.Invoke(runDatabaseClient),
+		fx.Populate(&l),
+	)
+
+	if err := app.Start(ctx); err != nil {
+		return nil, fmt.Errorf("failed to start seed application: %w", err)
+	}
+	defer func() {
+		if stopErr := app.Stop(context.WithoutCancel(ctx)); stopErr != nil {
+			err = errors.Join(err, fmt.Errorf("failed to stop seed application: %w", stopErr))
+		}
+	}()
+
+	return l.Load(ctx, doc)
+}
@@ -0,0 +1,58 @@
+package fxshow
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/seed"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+func TestSeedModuleGraph(t *testing.T) {
+	t.Parallel()
+
+	// l receives the fixture loader.
+	var l *seed.Loader
+	err := fx.ValidateApp(
+		configModule,
+		fx.Replace(mockTestConfig()),
+		loggerModule,
+		observabilityModule,
+		repositoryModule,
+		applicationModule,
+		seedModule,
+		fx.Invoke(runDatabaseClient),
+		fx.Populate(&l),
+	)
+	require.NoError(t, err, "seed dependency graph should be complete")
+}
+
+func TestSeedModuleLoadsExample(t *testing.T) {
+	t.Parallel()
+
+	doc, err := seed.ReadDocument(filepath.Join("..", "..", "..", "config", "fixtures.example.yml"))
+	require.NoError(t, err)
+
+	// l receives the fixture loader.
+	var l *seed.Loader
+	app := fxtest.New(t,
+		configModule,
+		fx.Replace(mockTestConfig()),
+		loggerModule,
+		observabilityModule,
+		mockRepositoryModule,
+		applicationModule,
+		seedModule,
+		fx.Populate(&l),
+	)
+	app.RequireStart()
+	defer app.RequireStop()
+
+	res, err := l.Load(context.Background(), doc)
+	require.NoError(t, err)
+	assert.Equal(t, &seed.Result{Users: 2, Credentials: 1, BankCards: 1, Notes: 2, Folders: 2, Files: 1}, res)
+}
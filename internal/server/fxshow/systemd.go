@@ -0,0 +1,44 @@
+package fxshow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/scheduler"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/systemd"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+// systemdModule provides the notifier reporting the service state to systemd.
+var systemdModule = fx.Module("systemd",
+	fx.Provide(systemd.NewNotifier),
+)
+
+// runSystemdNotifier registers lifecycle hooks reporting readiness to systemd and pinging its watchdog at half
+// the timeout. It is invoked last, so readiness is reported once the HTTP server listens and the shutdown is
+// reported before any component stops.
+func runSystemdNotifier(lc fx.Lifecycle, n *systemd.Notifier, logger *zap.SugaredLogger) {
+	if !n.Enabled() {
+		return
+	}
+	l := logger.Named("systemd")
+	watchdog := scheduler.NewPeriodicJob(l, "systemd-watchdog", n.WatchdogInterval()/2,
+		func(context.Context) error {
+			return n.Notify(systemd.Watchdog)
+		},
+	)
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			if err := n.Notify(systemd.Ready, "STATUS=Serving requests"); err != nil {
+				return fmt.Errorf("failed to report readiness: %w", err)
+			}
+			return watchdog.Start(ctx)
+		},
+		OnStop: func(ctx context.Context) error {
+			err := n.Notify(systemd.Stopping)
+			return errors.Join(err, watchdog.Stop(ctx))
+		},
+	})
+}
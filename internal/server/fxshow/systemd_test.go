@@ -0,0 +1,53 @@
+package fxshow
+
+import (
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/systemd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+	"go.uber.org/zap"
+)
+
+// The notifier reads the process environment, so the test does not run in parallel.
+func TestRunSystemdNotifier(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notify")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	require.NoError(t, err)
+	defer func() { _ = conn.Close() }()
+	t.Setenv("NOTIFY_SOCKET", path)
+	t.Setenv("WATCHDOG_USEC", "40000")
+	t.Setenv("WATCHDOG_PID", "")
+
+	// next reads the next notification.
+	next := func() string {
+		t.Helper()
+		buf := make([]byte, 128)
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+		n, err := conn.Read(buf)
+		require.NoError(t, err)
+		return string(buf[:n])
+	}
+
+	app := fxtest.New(t,
+		systemdModule,
+		fx.Supply(zap.NewNop().Sugar()),
+		fx.Invoke(runSystemdNotifier),
+	)
+	app.RequireStart()
+	assert.Equal(t, systemd.Ready+"\nSTATUS=Serving requests", next())
+	assert.Equal(t, systemd.Watchdog, next())
+
+	app.RequireStop()
+	// last holds the final notification, skipping watchdog pings sent before the stop.
+	last := next()
+	for last == systemd.Watchdog {
+		last = next()
+	}
+	assert.Equal(t, systemd.Stopping, last)
+}
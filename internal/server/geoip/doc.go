@@ -0,0 +1,5 @@
+// Package geoip resolves IP addresses to countries using MaxMind DB files for the AegisVaultKeeper server.
+//
+// This package reads GeoLite2/GeoIP2 Country and City databases with the maxminddb-golang library
+// and returns the country codes used by country access rules.
+package geoip
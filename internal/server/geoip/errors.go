@@ -0,0 +1,6 @@
+package geoip
+
+import "errors"
+
+// ErrInvalidDatabase indicates that the file is not a valid MaxMind DB.
+var ErrInvalidDatabase = errors.New("invalid MaxMind database")
@@ -0,0 +1,72 @@
+package geoip
+
+import (
+	"fmt"
+	"net/netip"
+	"os"
+
+	"github.com/oschwald/maxminddb-golang"
+)
+
+// Reader looks up countries of IP addresses in an in-memory MaxMind DB.
+type Reader struct {
+	// db is the MaxMind DB reader.
+	db *maxminddb.Reader
+}
+
+// countryRecord contains the fields of a GeoLite2/GeoIP2 Country or City record used for lookups.
+type countryRecord struct {
+	// Country is the country where the address is located.
+	Country struct {
+		// ISOCode is the ISO 3166-1 alpha-2 country code.
+		ISOCode string `maxminddb:"iso_code"`
+	} `maxminddb:"country"`
+	// RegisteredCountry is the country where the ISP registered the network.
+	RegisteredCountry struct {
+		// ISOCode is the ISO 3166-1 alpha-2 country code.
+		ISOCode string `maxminddb:"iso_code"`
+	} `maxminddb:"registered_country"`
+}
+
+// Open reads the MaxMind DB file at path into memory.
+func Open(path string) (*Reader, error) {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read GeoIP database: %w", err)
+	}
+	return NewReader(buf)
+}
+
+// NewReader creates a Reader over the MaxMind DB content in buf.
+func NewReader(buf []byte) (*Reader, error) {
+	db, err := maxminddb.FromBytes(buf)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidDatabase, err)
+	}
+	if v := db.Metadata.IPVersion; v != 4 && v != 6 {
+		return nil, fmt.Errorf("%w: unsupported IP version %d", ErrInvalidDatabase, v)
+	}
+	return &Reader{db: db}, nil
+}
+
+// Country returns the ISO 3166-1 alpha-2 country code of ip,
+// falling back to the registered country when the location country is absent.
+// An empty code is returned when the address is not present in the database.
+func (r *Reader) Country(ip netip.Addr) (string, error) {
+	if !ip.IsValid() {
+		return "", nil
+	}
+	ip = ip.Unmap()
+	if ip.Is6() && r.db.Metadata.IPVersion == 4 {
+		return "", nil
+	}
+
+	var record countryRecord
+	if err := r.db.Lookup(ip.AsSlice(), &record); err != nil {
+		return "", fmt.Errorf("%w: failed to decode record: %w", ErrInvalidDatabase, err)
+	}
+	if record.Country.ISOCode != "" {
+		return record.Country.ISOCode, nil
+	}
+	return record.RegisteredCountry.ISOCode, nil
+}
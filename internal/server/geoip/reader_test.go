@@ -0,0 +1,242 @@
+package geoip
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// metadataMarker precedes the metadata section at the end of a MaxMind DB file.
+var metadataMarker = []byte("\xAB\xCD\xEFMaxMind.com")
+
+// dataSectionSeparatorSize is the number of zero bytes between the search tree and the data section.
+const dataSectionSeparatorSize = 16
+
+// typeUint64 is the extended MaxMind DB field type of unsigned 64-bit integers.
+const typeUint64 = 9
+
+// testNetworks maps networks of the test database to their records.
+var testNetworks = []struct {
+	prefix  netip.Prefix
+	country string
+	// registered stores the code as the registered country only.
+	registered bool
+}{
+	{prefix: netip.MustParsePrefix("203.0.113.0/24"), country: "DE"},
+	{prefix: netip.MustParsePrefix("198.51.100.0/25"), country: "US"},
+	{prefix: netip.MustParsePrefix("2001:db8::/32"), country: "FR"},
+	{prefix: netip.MustParsePrefix("192.0.2.0/24"), country: "JP", registered: true},
+}
+
+// trieNode is a node of the search tree built for the test database.
+type trieNode struct {
+	// children holds node indexes (>= 0), data records (<= -2) or -1 for empty.
+	children [2]int
+}
+
+// buildTestDB encodes a MaxMind DB with the test networks and the specified record size.
+func buildTestDB(t *testing.T, recordSize int) []byte {
+	t.Helper()
+
+	nodes := []trieNode{{children: [2]int{-1, -1}}}
+	var data []byte
+	keyOffsets := map[string]int{}
+
+	for i, n := range testNetworks {
+		recordOffset := len(data)
+		data = append(data, encodeRecord(keyOffsets, len(data), n.country, n.registered, uint64(i))...)
+
+		addr := n.prefix.Addr()
+		bits := n.prefix.Bits()
+		if addr.Is4() {
+			addr = netip.AddrFrom16(addr.As16())
+			b := addr.As16()
+			b[10], b[11] = 0, 0
+			addr = netip.AddrFrom16(b)
+			bits += 96
+		}
+		raw := addr.As16()
+
+		node := 0
+		for j := range bits {
+			bit := int(raw[j/8]>>(7-j%8)) & 1
+			if j == bits-1 {
+				nodes[node].children[bit] = -2 - recordOffset
+				break
+			}
+			if nodes[node].children[bit] < 0 {
+				nodes = append(nodes, trieNode{children: [2]int{-1, -1}})
+				nodes[node].children[bit] = len(nodes) - 1
+			}
+			node = nodes[node].children[bit]
+		}
+	}
+
+	nodeCount := len(nodes)
+	value := func(child int) uint32 {
+		switch {
+		case child == -1:
+			return uint32(nodeCount)
+		case child < -1:
+			return uint32(nodeCount + dataSectionSeparatorSize + (-2 - child))
+		default:
+			return uint32(child)
+		}
+	}
+
+	var tree []byte
+	for _, n := range nodes {
+		l, r := value(n.children[0]), value(n.children[1])
+		switch recordSize {
+		case 24:
+			tree = append(tree, byte(l>>16), byte(l>>8), byte(l), byte(r>>16), byte(r>>8), byte(r))
+		case 28:
+			tree = append(tree, byte(l>>16), byte(l>>8), byte(l),
+				byte((l>>24)<<4)|byte(r>>24&0x0F), byte(r>>16), byte(r>>8), byte(r))
+		default:
+			tree = binary.BigEndian.AppendUint32(tree, l)
+			tree = binary.BigEndian.AppendUint32(tree, r)
+		}
+	}
+
+	out := append(tree, make([]byte, dataSectionSeparatorSize)...)
+	out = append(out, data...)
+	out = append(out, metadataMarker...)
+	out = append(out, 0xE0|3)
+	out = append(out, encodeString("node_count")...)
+	out = append(out, 0xC4)
+	out = binary.BigEndian.AppendUint32(out, uint32(nodeCount))
+	out = append(out, encodeString("record_size")...)
+	out = append(out, 0xA2, 0, byte(recordSize))
+	out = append(out, encodeString("ip_version")...)
+	out = append(out, 0xA2, 0, 6)
+	return out
+}
+
+// encodeRecord encodes {"<country key>": {"iso_code": code}, "geoname_id": id} at offset base.
+// Repeated keys are encoded as pointers to their first occurrence.
+func encodeRecord(keyOffsets map[string]int, base int, code string, registered bool, id uint64) []byte {
+	out := []byte{0xE0 | 2}
+	key := func(k string) {
+		if off, ok := keyOffsets[k]; ok {
+			out = append(out, 0x20|byte(off>>8), byte(off))
+			return
+		}
+		keyOffsets[k] = base + len(out)
+		out = append(out, encodeString(k)...)
+	}
+
+	countryKey := "country"
+	if registered {
+		countryKey = "registered_country"
+	}
+	key(countryKey)
+	out = append(out, 0xE0|1)
+	key("iso_code")
+	out = append(out, encodeString(code)...)
+
+	key("geoname_id")
+	out = append(out, 0x08, typeUint64-7)
+	out = binary.BigEndian.AppendUint64(out, id)
+	return out
+}
+
+// encodeString encodes a short UTF-8 string field.
+func encodeString(s string) []byte {
+	return append([]byte{0x40 | byte(len(s))}, s...)
+}
+
+func TestReader_Country(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		ip   string
+		want string
+	}{
+		{ip: "203.0.113.77", want: "DE"},
+		{ip: "198.51.100.1", want: "US"},
+		{ip: "198.51.100.200", want: ""},
+		{ip: "2001:db8:1::1", want: "FR"},
+		{ip: "::ffff:203.0.113.5", want: "DE"},
+		{ip: "192.0.2.10", want: "JP"},
+		{ip: "8.8.8.8", want: ""},
+		{ip: "2001:db9::1", want: ""},
+	}
+
+	for _, recordSize := range []int{24, 28, 32} {
+		r, err := NewReader(buildTestDB(t, recordSize))
+		require.NoError(t, err)
+
+		for _, tt := range tests {
+			t.Run(fmt.Sprintf("%d-bit/%s", recordSize, tt.ip), func(t *testing.T) {
+				t.Parallel()
+
+				got, err := r.Country(netip.MustParseAddr(tt.ip))
+				require.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			})
+		}
+	}
+}
+
+func TestReader_CountryInvalidAddress(t *testing.T) {
+	t.Parallel()
+
+	r, err := NewReader(buildTestDB(t, 24))
+	require.NoError(t, err)
+
+	got, err := r.Country(netip.Addr{})
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
+
+func TestNewReader_Invalid(t *testing.T) {
+	t.Parallel()
+
+	valid := buildTestDB(t, 24)
+
+	tests := []struct {
+		name string
+		buf  []byte
+	}{
+		{name: "empty", buf: nil},
+		{name: "missing marker", buf: []byte("not a database")},
+		{name: "truncated metadata", buf: append(append([]byte{}, metadataMarker...), 0xE0|3)},
+		{name: "metadata not a map", buf: append(append([]byte{}, metadataMarker...), encodeString("x")...)},
+		{name: "unsupported record size", buf: append(append([]byte{}, metadataMarker...),
+			0xE0|2, 0x4B, 'r', 'e', 'c', 'o', 'r', 'd', '_', 's', 'i', 'z', 'e', 0xA1, 20,
+			0x4A, 'i', 'p', '_', 'v', 'e', 'r', 's', 'i', 'o', 'n', 0xA1, 6)},
+		{name: "tree exceeds file", buf: valid[len(valid)-60:]},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := NewReader(tt.buf)
+			assert.ErrorIs(t, err, ErrInvalidDatabase)
+		})
+	}
+}
+
+func TestOpen(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "country.mmdb")
+	require.NoError(t, os.WriteFile(path, buildTestDB(t, 28), 0o600))
+
+	r, err := Open(path)
+	require.NoError(t, err)
+	got, err := r.Country(netip.MustParseAddr("203.0.113.1"))
+	require.NoError(t, err)
+	assert.Equal(t, "DE", got)
+
+	_, err = Open(filepath.Join(t.TempDir(), "missing.mmdb"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,7 @@
+// Package kdbx provides a writer of KeePass databases for the AegisVaultKeeper server.
+//
+// Databases are written in the KDBX 4 format opened by KeePass 2.35+ and KeePassXC 2.7+: the payload is
+// compressed with gzip, encrypted with AES-256-CBC under a key derived from the password with Argon2id and
+// authenticated with HMAC-SHA256 blocks. Protected fields are additionally masked with the ChaCha20 inner
+// stream, as KeePass itself does for passwords.
+package kdbx
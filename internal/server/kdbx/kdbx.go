@@ -0,0 +1,88 @@
+package kdbx
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Standard entry field keys, shown by KeePass in their own columns.
+const (
+	// KeyTitle is the key of the entry title.
+	KeyTitle = "Title"
+	// KeyUserName is the key of the entry user name.
+	KeyUserName = "UserName"
+	// KeyPassword is the key of the entry password.
+	KeyPassword = "Password"
+	// KeyURL is the key of the entry URL.
+	KeyURL = "URL"
+	// KeyNotes is the key of the entry notes.
+	KeyNotes = "Notes"
+)
+
+// ErrPasswordRequired indicates an attempt to write a database without a master password.
+var ErrPasswordRequired = errors.New("kdbx master password is required")
+
+// Database represents a KeePass database.
+type Database struct {
+	// Root contains the root group holding every group and entry of the database.
+	Root *Group
+	// Name contains the database name shown by KeePass.
+	Name string
+}
+
+// Group represents a group of entries, shown by KeePass as a folder.
+type Group struct {
+	// Groups contains the nested groups.
+	Groups []*Group
+	// Entries contains the entries of the group.
+	Entries []*Entry
+	// Name contains the group name.
+	Name string
+	// ID uniquely identifies the group; a random one is used when zero.
+	ID uuid.UUID
+}
+
+// Entry represents a KeePass entry.
+type Entry struct {
+	// Modified contains the moment the entry was last modified; the moment of writing when zero.
+	Modified time.Time
+	// Fields contains the string fields of the entry, including the standard ones such as KeyTitle.
+	Fields []Field
+	// Attachments contains the files attached to the entry.
+	Attachments []Attachment
+	// ID uniquely identifies the entry; a random one is used when zero.
+	ID uuid.UUID
+}
+
+// Field represents a string field of an entry.
+type Field struct {
+	// Key contains the field name.
+	Key string
+	// Value contains the field value.
+	Value string
+	// Protected marks fields KeePass masks on screen and keeps encrypted in memory.
+	Protected bool
+}
+
+// Attachment represents a file attached to an entry.
+type Attachment struct {
+	// Name contains the file name.
+	Name string
+	// Data contains the file content.
+	Data []byte
+}
+
+// KDF contains the Argon2id parameters deriving the encryption key from the master password.
+type KDF struct {
+	// Memory is the amount of memory used in bytes.
+	Memory uint64
+	// Iterations is the number of passes over the memory.
+	Iterations uint64
+	// Parallelism is the number of threads used.
+	Parallelism uint32
+}
+
+// DefaultKDF contains the second recommended Argon2id parameters of RFC 9106, for memory-constrained servers.
+var DefaultKDF = KDF{Memory: 64 << 20, Iterations: 3, Parallelism: 4}
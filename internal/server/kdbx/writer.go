@@ -0,0 +1,294 @@
+package kdbx
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// File signature and format version.
+const (
+	// signature1 is the first magic number of KeePass files.
+	signature1 = 0x9AA2D903
+	// signature2 is the second magic number, identifying KeePass 2 databases.
+	signature2 = 0xB54BFB67
+	// formatVersion is the KDBX 4.0 version, major in the high half.
+	formatVersion = 0x00040000
+)
+
+// Outer header field identifiers.
+const (
+	// headerEnd terminates the outer header.
+	headerEnd = 0
+	// headerCipherID identifies the payload cipher.
+	headerCipherID = 2
+	// headerCompression identifies the payload compression.
+	headerCompression = 3
+	// headerMasterSeed contains the seed mixed into the encryption and HMAC keys.
+	headerMasterSeed = 4
+	// headerEncryptionIV contains the initialization vector of the payload cipher.
+	headerEncryptionIV = 7
+	// headerKDFParameters contains the key derivation parameters.
+	headerKDFParameters = 11
+)
+
+// Inner header field identifiers.
+const (
+	// innerEnd terminates the inner header.
+	innerEnd = 0
+	// innerStreamID identifies the cipher masking protected fields.
+	innerStreamID = 1
+	// innerStreamKey contains the key of the cipher masking protected fields.
+	innerStreamKey = 2
+	// innerBinary contains an attachment.
+	innerBinary = 3
+)
+
+// Variant dictionary value types.
+const (
+	// variantEnd terminates a variant dictionary.
+	variantEnd = 0x00
+	// variantUInt32 is an unsigned 32-bit integer.
+	variantUInt32 = 0x04
+	// variantUInt64 is an unsigned 64-bit integer.
+	variantUInt64 = 0x05
+	// variantBytes is a byte array.
+	variantBytes = 0x42
+	// variantVersion is the supported variant dictionary version.
+	variantVersion = 0x0100
+)
+
+const (
+	// compressionGzip marks a gzip compressed payload.
+	compressionGzip = 1
+	// streamChaCha20 identifies the ChaCha20 inner stream.
+	streamChaCha20 = 3
+	// argon2Version is the Argon2 version 1.3.
+	argon2Version = 0x13
+	// binaryProtected flags attachments KeePass keeps encrypted in memory.
+	binaryProtected = 0x01
+	// blockSize is the size of the HMAC authenticated payload blocks.
+	blockSize = 1 << 20
+)
+
+var (
+	// cipherAES256 is the UUID of the AES-256-CBC payload cipher.
+	cipherAES256 = []byte{
+		0x31, 0xC1, 0xF2, 0xE6, 0xBF, 0x71, 0x43, 0x50, 0xBE, 0x58, 0x05, 0x21, 0x6A, 0xFC, 0x5A, 0xFF,
+	}
+	// kdfArgon2id is the UUID of the Argon2id key derivation function.
+	kdfArgon2id = []byte{
+		0x9E, 0x29, 0x8B, 0x19, 0x56, 0xDB, 0x47, 0x73, 0xB2, 0x3D, 0xFC, 0x3E, 0xC6, 0xF0, 0xA1, 0xE6,
+	}
+	// headerEndMarker is the content of the field terminating the outer header.
+	headerEndMarker = []byte("\r\n\r\n")
+)
+
+// Write encrypts the database with the master password and writes it to w in the KDBX 4 format.
+// The encryption key is derived from the password with the kdf parameters.
+func Write(w io.Writer, db *Database, password string, kdf KDF) error {
+	if password == "" {
+		return ErrPasswordRequired
+	}
+	return write(w, db, password, kdf)
+}
+
+// WriteWithoutPassword writes the database protected by an empty master password, which KeePass and KeePassXC
+// open with the password field left blank. It is meant for files encrypted as a whole by other means, such as
+// exports encrypted to a public key.
+func WriteWithoutPassword(w io.Writer, db *Database, kdf KDF) error {
+	return write(w, db, "", kdf)
+}
+
+// write encrypts the database with the master password and writes it to w in the KDBX 4 format.
+func write(w io.Writer, db *Database, password string, kdf KDF) error {
+	// masterSeed, iv, salt and streamKey hold the random values of the file.
+	var masterSeed, salt [32]byte
+	var iv [aes.BlockSize]byte
+	var streamKey [64]byte
+	for _, b := range [][]byte{masterSeed[:], salt[:], iv[:], streamKey[:]} {
+		if _, err := rand.Read(b); err != nil {
+			return fmt.Errorf("failed to generate random values: %w", err)
+		}
+	}
+
+	header := outerHeader(masterSeed[:], iv[:], salt[:], kdf)
+	encKey, hmacKey := deriveKeys(password, masterSeed[:], salt[:], kdf)
+
+	payload, err := innerPayload(db, streamKey[:])
+	if err != nil {
+		return err
+	}
+	encrypted, err := encrypt(encKey, iv[:], payload)
+	if err != nil {
+		return err
+	}
+
+	headerHash := sha256.Sum256(header)
+	// out holds the complete file before it is written.
+	var out bytes.Buffer
+	out.Grow(len(header) + len(encrypted) + 128)
+	out.Write(header)
+	out.Write(headerHash[:])
+	out.Write(headerHMAC(hmacKey, header))
+	writeBlocks(&out, hmacKey, encrypted)
+
+	if _, err := w.Write(out.Bytes()); err != nil {
+		return fmt.Errorf("failed to write kdbx file: %w", err)
+	}
+	return nil
+}
+
+// outerHeader builds the unencrypted header describing the cipher, the compression and the key derivation.
+func outerHeader(masterSeed, iv, salt []byte, kdf KDF) []byte {
+	// h accumulates the header bytes.
+	var h bytes.Buffer
+	_ = binary.Write(&h, binary.LittleEndian, []uint32{signature1, signature2, formatVersion})
+	writeField(&h, headerCipherID, cipherAES256)
+	writeField(&h, headerCompression, binary.LittleEndian.AppendUint32(nil, compressionGzip))
+	writeField(&h, headerMasterSeed, masterSeed)
+	writeField(&h, headerEncryptionIV, iv)
+	writeField(&h, headerKDFParameters, kdfParameters(salt, kdf))
+	writeField(&h, headerEnd, headerEndMarker)
+	return h.Bytes()
+}
+
+// kdfParameters encodes the Argon2id parameters as a variant dictionary.
+func kdfParameters(salt []byte, kdf KDF) []byte {
+	// d accumulates the dictionary bytes.
+	var d bytes.Buffer
+	_ = binary.Write(&d, binary.LittleEndian, uint16(variantVersion))
+	writeVariant(&d, variantBytes, "$UUID", kdfArgon2id)
+	writeVariant(&d, variantBytes, "S", salt)
+	writeVariant(&d, variantUInt32, "P", binary.LittleEndian.AppendUint32(nil, kdf.Parallelism))
+	writeVariant(&d, variantUInt64, "M", binary.LittleEndian.AppendUint64(nil, kdf.Memory))
+	writeVariant(&d, variantUInt64, "I", binary.LittleEndian.AppendUint64(nil, kdf.Iterations))
+	writeVariant(&d, variantUInt32, "V", binary.LittleEndian.AppendUint32(nil, argon2Version))
+	d.WriteByte(variantEnd)
+	return d.Bytes()
+}
+
+// deriveKeys derives the payload encryption key and the base key of the block HMACs from the password.
+func deriveKeys(password string, masterSeed, salt []byte, kdf KDF) ([]byte, []byte) {
+	passwordHash := sha256.Sum256([]byte(password))
+	composite := sha256.Sum256(passwordHash[:])
+	transformed := argon2.IDKey(
+		composite[:],
+		salt,
+		uint32(kdf.Iterations),
+		uint32(kdf.Memory/1024),
+		uint8(kdf.Parallelism),
+		32,
+	)
+
+	encKey := sha256.Sum256(append(append([]byte{}, masterSeed...), transformed...))
+	hmacKey := sha512.Sum512(append(append(append([]byte{}, masterSeed...), transformed...), 0x01))
+	return encKey[:], hmacKey[:]
+}
+
+// innerPayload builds the gzip compressed inner header and XML document of the database.
+func innerPayload(db *Database, streamKey []byte) ([]byte, error) {
+	// plain holds the uncompressed inner header followed by the XML document.
+	var plain bytes.Buffer
+	writeField(&plain, innerStreamID, binary.LittleEndian.AppendUint32(nil, streamChaCha20))
+	writeField(&plain, innerStreamKey, streamKey)
+	for _, a := range attachments(db.Root) {
+		writeField(&plain, innerBinary, append([]byte{binaryProtected}, a.Data...))
+	}
+	writeField(&plain, innerEnd, nil)
+
+	if err := writeXML(&plain, db, streamKey); err != nil {
+		return nil, err
+	}
+
+	// compressed holds the gzip compressed payload.
+	var compressed bytes.Buffer
+	zw := gzip.NewWriter(&compressed)
+	if _, err := zw.Write(plain.Bytes()); err != nil {
+		return nil, fmt.Errorf("failed to compress kdbx payload: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress kdbx payload: %w", err)
+	}
+	return compressed.Bytes(), nil
+}
+
+// encrypt encrypts the payload with AES-256-CBC and PKCS #7 padding.
+func encrypt(key, iv, payload []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kdbx cipher: %w", err)
+	}
+	padding := aes.BlockSize - len(payload)%aes.BlockSize
+	padded := append(payload, bytes.Repeat([]byte{byte(padding)}, padding)...)
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(padded, padded)
+	return padded, nil
+}
+
+// writeBlocks writes the encrypted payload as HMAC authenticated blocks followed by the empty final block.
+func writeBlocks(out *bytes.Buffer, hmacKey, encrypted []byte) {
+	// index numbers the blocks as authenticated by their HMACs.
+	var index uint64
+	for {
+		n := min(len(encrypted), blockSize)
+		data := encrypted[:n]
+		encrypted = encrypted[n:]
+
+		sized := binary.LittleEndian.AppendUint32(nil, uint32(n))
+		out.Write(blockHMAC(hmacKey, index, append(sized, data...)))
+		out.Write(sized)
+		out.Write(data)
+		if n == 0 {
+			return
+		}
+		index++
+	}
+}
+
+// blockHMAC computes the HMAC-SHA256 authenticating the block with the index and its length prefixed data.
+func blockHMAC(hmacKey []byte, index uint64, data []byte) []byte {
+	mac := hmac.New(sha256.New, blockKey(hmacKey, index))
+	mac.Write(binary.LittleEndian.AppendUint64(nil, index))
+	mac.Write(data)
+	return mac.Sum(nil)
+}
+
+// headerHMAC computes the HMAC-SHA256 authenticating the outer header with the key of the maximal block index.
+func headerHMAC(hmacKey, header []byte) []byte {
+	mac := hmac.New(sha256.New, blockKey(hmacKey, math.MaxUint64))
+	mac.Write(header)
+	return mac.Sum(nil)
+}
+
+// blockKey derives the HMAC key of the block with the index from the base key.
+func blockKey(hmacKey []byte, index uint64) []byte {
+	key := sha512.Sum512(append(binary.LittleEndian.AppendUint64(nil, index), hmacKey...))
+	return key[:]
+}
+
+// writeField writes a header field as its identifier, little-endian length and data.
+func writeField(b *bytes.Buffer, id byte, data []byte) {
+	b.WriteByte(id)
+	_ = binary.Write(b, binary.LittleEndian, uint32(len(data)))
+	b.Write(data)
+}
+
+// writeVariant writes a variant dictionary entry as its type, name and value, both length prefixed.
+func writeVariant(b *bytes.Buffer, kind byte, name string, value []byte) {
+	b.WriteByte(kind)
+	_ = binary.Write(b, binary.LittleEndian, uint32(len(name)))
+	b.WriteString(name)
+	_ = binary.Write(b, binary.LittleEndian, uint32(len(value)))
+	b.Write(value)
+}
@@ -0,0 +1,351 @@
+package kdbx
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/xml"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/chacha20"
+)
+
+// testKDF keeps the key derivation of tests fast.
+var testKDF = KDF{Memory: 1 << 20, Iterations: 1, Parallelism: 1}
+
+// readDatabase decrypts a KDBX 4 file the way KeePass does, verifying every HMAC, and returns the XML document
+// with protected values unmasked and the attachments of the inner header.
+func readDatabase(t *testing.T, file []byte, password string) (*xmlFile, [][]byte) {
+	t.Helper()
+
+	r := bytes.NewReader(file)
+	// sig holds the signatures and the format version.
+	var sig [3]uint32
+	require.NoError(t, binary.Read(r, binary.LittleEndian, &sig))
+	require.Equal(t, [3]uint32{signature1, signature2, formatVersion}, sig)
+
+	fields := readFields(t, r)
+	headerLen := len(file) - r.Len()
+	header := file[:headerLen]
+	require.Equal(t, cipherAES256, fields[headerCipherID][0])
+	require.Equal(t, []byte{compressionGzip, 0, 0, 0}, fields[headerCompression][0])
+
+	params := readVariants(t, fields[headerKDFParameters][0])
+	require.Equal(t, kdfArgon2id, params["$UUID"])
+	kdf := KDF{
+		Memory:      binary.LittleEndian.Uint64(params["M"]),
+		Iterations:  binary.LittleEndian.Uint64(params["I"]),
+		Parallelism: binary.LittleEndian.Uint32(params["P"]),
+	}
+	encKey, hmacKey := deriveKeys(password, fields[headerMasterSeed][0], params["S"], kdf)
+
+	headerHash := sha256.Sum256(header)
+	require.Equal(t, headerHash[:], next(t, r, sha256.Size))
+	if !hmac.Equal(headerHMAC(hmacKey, header), next(t, r, sha256.Size)) {
+		t.Fatal("header HMAC mismatch: wrong password or corrupted header")
+	}
+
+	// encrypted collects the payload of the authenticated blocks.
+	var encrypted []byte
+	for index := uint64(0); ; index++ {
+		mac := next(t, r, sha256.Size)
+		sized := next(t, r, 4)
+		data := next(t, r, int(binary.LittleEndian.Uint32(sized)))
+		require.True(t, hmac.Equal(blockHMAC(hmacKey, index, append(sized, data...)), mac), "block %d HMAC", index)
+		if len(data) == 0 {
+			break
+		}
+		encrypted = append(encrypted, data...)
+	}
+	require.Zero(t, r.Len())
+
+	block, err := aes.NewCipher(encKey)
+	require.NoError(t, err)
+	cipher.NewCBCDecrypter(block, fields[headerEncryptionIV][0]).CryptBlocks(encrypted, encrypted)
+	padded := encrypted[:len(encrypted)-int(encrypted[len(encrypted)-1])]
+	zr, err := gzip.NewReader(bytes.NewReader(padded))
+	require.NoError(t, err)
+	plain, err := io.ReadAll(zr)
+	require.NoError(t, err)
+
+	inner := bytes.NewReader(plain)
+	innerFields := readFields(t, inner)
+	require.Equal(t, []byte{streamChaCha20, 0, 0, 0}, innerFields[innerStreamID][0])
+	// binaries holds the attachments without their flags.
+	var binaries [][]byte
+	for _, b := range innerFields[innerBinary] {
+		require.Equal(t, byte(binaryProtected), b[0])
+		binaries = append(binaries, b[1:])
+	}
+
+	// doc holds the parsed XML document.
+	var doc xmlFile
+	document, _ := io.ReadAll(inner)
+	require.NoError(t, xml.Unmarshal(document, &doc))
+	keyHash := sha512.Sum512(innerFields[innerStreamKey][0])
+	stream, err := chacha20.NewUnauthenticatedCipher(keyHash[:32], keyHash[32:44])
+	require.NoError(t, err)
+	doc.Root.Group.unmask(t, stream)
+	return &doc, binaries
+}
+
+// readFields reads header fields up to the terminating one, keyed by identifier in order of appearance.
+func readFields(t *testing.T, r *bytes.Reader) map[byte][][]byte {
+	t.Helper()
+	fields := make(map[byte][][]byte)
+	for {
+		id, err := r.ReadByte()
+		require.NoError(t, err)
+		data := next(t, r, int(binary.LittleEndian.Uint32(next(t, r, 4))))
+		if id == headerEnd {
+			return fields
+		}
+		fields[id] = append(fields[id], data)
+	}
+}
+
+// readVariants reads a variant dictionary into its values keyed by name.
+func readVariants(t *testing.T, data []byte) map[string][]byte {
+	t.Helper()
+	r := bytes.NewReader(data)
+	require.Equal(t, []byte{0x00, 0x01}, next(t, r, 2))
+	values := make(map[string][]byte)
+	for {
+		kind, err := r.ReadByte()
+		require.NoError(t, err)
+		if kind == variantEnd {
+			return values
+		}
+		name := next(t, r, int(binary.LittleEndian.Uint32(next(t, r, 4))))
+		values[string(name)] = next(t, r, int(binary.LittleEndian.Uint32(next(t, r, 4))))
+	}
+}
+
+// next reads n bytes from r.
+func next(t *testing.T, r *bytes.Reader, n int) []byte {
+	t.Helper()
+	b := make([]byte, n)
+	_, err := io.ReadFull(r, b)
+	require.NoError(t, err)
+	return b
+}
+
+// xmlFile mirrors the parts of the KeePass XML document the tests check.
+type xmlFile struct {
+	Meta struct {
+		Generator    string `xml:"Generator"`
+		DatabaseName string `xml:"DatabaseName"`
+	} `xml:"Meta"`
+	Root struct {
+		Group xmlGroup `xml:"Group"`
+	} `xml:"Root"`
+}
+
+// xmlGroup mirrors a group of the KeePass XML document.
+type xmlGroup struct {
+	Name    string     `xml:"Name"`
+	UUID    string     `xml:"UUID"`
+	Entries []xmlEntry `xml:"Entry"`
+	Groups  []xmlGroup `xml:"Group"`
+}
+
+// xmlEntry mirrors an entry of the KeePass XML document.
+type xmlEntry struct {
+	UUID  string `xml:"UUID"`
+	Times struct {
+		LastModificationTime string `xml:"LastModificationTime"`
+	} `xml:"Times"`
+	Strings []struct {
+		Key   string `xml:"Key"`
+		Value struct {
+			Text      string `xml:",chardata"`
+			Protected string `xml:"Protected,attr"`
+		} `xml:"Value"`
+	} `xml:"String"`
+	Binaries []struct {
+		Key   string `xml:"Key"`
+		Value struct {
+			Ref int `xml:"Ref,attr"`
+		} `xml:"Value"`
+	} `xml:"Binary"`
+}
+
+// unmask replaces protected values with their plain text, consuming the stream in document order.
+func (g *xmlGroup) unmask(t *testing.T, stream *chacha20.Cipher) {
+	t.Helper()
+	for i := range g.Entries {
+		for j := range g.Entries[i].Strings {
+			v := &g.Entries[i].Strings[j].Value
+			if v.Protected != "True" {
+				continue
+			}
+			masked, err := base64.StdEncoding.DecodeString(v.Text)
+			require.NoError(t, err)
+			stream.XORKeyStream(masked, masked)
+			v.Text = string(masked)
+		}
+	}
+	for i := range g.Groups {
+		g.Groups[i].unmask(t, stream)
+	}
+}
+
+// field returns the value of the entry field with the key.
+func (e *xmlEntry) field(key string) string {
+	for _, s := range e.Strings {
+		if s.Key == key {
+			return s.Value.Text
+		}
+	}
+	return ""
+}
+
+func TestWrite(t *testing.T) {
+	t.Parallel()
+
+	entryID := uuid.New()
+	modified := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	db := &Database{
+		Name: "Vault",
+		Root: &Group{
+			Name: "Vault",
+			Groups: []*Group{
+				{
+					Name: "Credentials",
+					Entries: []*Entry{{
+						ID:       entryID,
+						Modified: modified,
+						Fields: []Field{
+							{Key: KeyTitle, Value: "Mail <work>"},
+							{Key: KeyUserName, Value: "alice"},
+							{Key: KeyPassword, Value: "s3cr3t & more", Protected: true},
+						},
+					}},
+				},
+				{
+					Name: "Files",
+					Groups: []*Group{{
+						Name: "docs",
+						Entries: []*Entry{
+							{
+								Fields:      []Field{{Key: KeyTitle, Value: "passport.pdf"}},
+								Attachments: []Attachment{{Name: "passport.pdf", Data: []byte("%PDF-1.7")}},
+							},
+							{
+								Fields:      []Field{{Key: KeyNotes, Value: "scan", Protected: true}},
+								Attachments: []Attachment{{Name: "back.png", Data: bytes.Repeat([]byte{0x89}, blockSize+1)}},
+							},
+						},
+					}},
+				},
+			},
+		},
+	}
+
+	// file holds the written database.
+	var file bytes.Buffer
+	require.NoError(t, Write(&file, db, "master password", testKDF))
+
+	doc, binaries := readDatabase(t, file.Bytes(), "master password")
+
+	assert.Equal(t, generator, doc.Meta.Generator)
+	assert.Equal(t, "Vault", doc.Meta.DatabaseName)
+	root := doc.Root.Group
+	assert.Equal(t, "Vault", root.Name)
+	require.Len(t, root.Groups, 2)
+
+	credentials := root.Groups[0]
+	assert.Equal(t, "Credentials", credentials.Name)
+	require.Len(t, credentials.Entries, 1)
+	entry := credentials.Entries[0]
+	assert.Equal(t, base64.StdEncoding.EncodeToString(entryID[:]), entry.UUID)
+	assert.Equal(t, encodeTime(modified), entry.Times.LastModificationTime)
+	assert.Equal(t, "Mail <work>", entry.field(KeyTitle))
+	assert.Equal(t, "alice", entry.field(KeyUserName))
+	assert.Equal(t, "s3cr3t & more", entry.field(KeyPassword))
+
+	require.Len(t, root.Groups[1].Groups, 1)
+	docs := root.Groups[1].Groups[0]
+	assert.Equal(t, "docs", docs.Name)
+	require.Len(t, docs.Entries, 2)
+	assert.Equal(t, "scan", docs.Entries[1].field(KeyNotes))
+	require.Len(t, binaries, 2)
+	for i, name := range []string{"passport.pdf", "back.png"} {
+		require.Len(t, docs.Entries[i].Binaries, 1)
+		assert.Equal(t, name, docs.Entries[i].Binaries[0].Key)
+		ref := docs.Entries[i].Binaries[0].Value.Ref
+		assert.Equal(t, db.Root.Groups[1].Groups[0].Entries[i].Attachments[0].Data, binaries[ref])
+	}
+}
+
+func TestWriteWithoutPassword(t *testing.T) {
+	t.Parallel()
+
+	db := &Database{Name: "Vault", Root: &Group{Name: "Vault"}}
+
+	// file holds the written database.
+	var file bytes.Buffer
+	require.NoError(t, WriteWithoutPassword(&file, db, testKDF))
+
+	doc, _ := readDatabase(t, file.Bytes(), "")
+	assert.Equal(t, "Vault", doc.Root.Group.Name)
+}
+
+func TestWrite_Errors(t *testing.T) {
+	t.Parallel()
+
+	db := &Database{Name: "Vault", Root: &Group{Name: "Vault"}}
+
+	t.Run("password required", func(t *testing.T) {
+		t.Parallel()
+
+		err := Write(io.Discard, db, "", testKDF)
+
+		require.ErrorIs(t, err, ErrPasswordRequired)
+	})
+
+	t.Run("write failure", func(t *testing.T) {
+		t.Parallel()
+
+		err := Write(failingWriter{}, db, "master password", testKDF)
+
+		require.ErrorIs(t, err, errWriteFailed)
+	})
+
+	t.Run("wrong password rejected", func(t *testing.T) {
+		t.Parallel()
+
+		// file holds the written database.
+		var file bytes.Buffer
+		require.NoError(t, Write(&file, db, "master password", testKDF))
+
+		// The header HMAC is keyed with the key derived from the password.
+		r := bytes.NewReader(file.Bytes()[12:])
+		fields := readFields(t, r)
+		header := file.Bytes()[:file.Len()-r.Len()]
+		params := readVariants(t, fields[headerKDFParameters][0])
+		_, hmacKey := deriveKeys("wrong password", fields[headerMasterSeed][0], params["S"], testKDF)
+		stored := file.Bytes()[len(header)+sha256.Size : len(header)+2*sha256.Size]
+		assert.False(t, hmac.Equal(headerHMAC(hmacKey, header), stored))
+	})
+}
+
+// errWriteFailed is returned by failingWriter.
+var errWriteFailed = errors.New("disk full")
+
+// failingWriter fails every write.
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errWriteFailed }
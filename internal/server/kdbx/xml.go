@@ -0,0 +1,145 @@
+package kdbx
+
+import (
+	"bytes"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/chacha20"
+)
+
+// generator names the application in the database metadata.
+const generator = "AegisVaultKeeper"
+
+// epochOffset is the number of seconds between 0001-01-01, the epoch of KDBX 4 times, and the Unix epoch.
+const epochOffset = 62135596800
+
+// xmlWriter writes the XML document of a database, masking protected values with the inner stream.
+type xmlWriter struct {
+	// b accumulates the document.
+	b *bytes.Buffer
+	// stream masks protected values in document order.
+	stream *chacha20.Cipher
+	// now is the moment used for entries without a modification time.
+	now time.Time
+	// binaries counts the attachments referenced so far, matching their order in the inner header.
+	binaries int
+}
+
+// writeXML writes the XML document of the database to b; streamKey keys the inner stream.
+func writeXML(b *bytes.Buffer, db *Database, streamKey []byte) error {
+	keyHash := sha512.Sum512(streamKey)
+	stream, err := chacha20.NewUnauthenticatedCipher(keyHash[:32], keyHash[32:44])
+	if err != nil {
+		return fmt.Errorf("failed to create kdbx inner stream: %w", err)
+	}
+	w := &xmlWriter{b: b, stream: stream, now: time.Now()}
+
+	b.WriteString(`<?xml version="1.0" encoding="utf-8" standalone="yes"?>`)
+	b.WriteString("<KeePassFile><Meta>")
+	w.element("Generator", generator)
+	w.element("DatabaseName", db.Name)
+	b.WriteString("<MemoryProtection><ProtectPassword>True</ProtectPassword></MemoryProtection>")
+	b.WriteString("</Meta><Root>")
+	root := db.Root
+	if root == nil {
+		root = &Group{Name: db.Name}
+	}
+	w.group(root)
+	b.WriteString("</Root></KeePassFile>")
+	return nil
+}
+
+// group writes the group with its entries followed by its nested groups.
+func (w *xmlWriter) group(g *Group) {
+	w.b.WriteString("<Group>")
+	w.element("UUID", encodeUUID(g.ID))
+	w.element("Name", g.Name)
+	for _, e := range g.Entries {
+		w.entry(e)
+	}
+	for _, child := range g.Groups {
+		w.group(child)
+	}
+	w.b.WriteString("</Group>")
+}
+
+// entry writes the entry with its fields and attachment references.
+func (w *xmlWriter) entry(e *Entry) {
+	modified := e.Modified
+	if modified.IsZero() {
+		modified = w.now
+	}
+
+	w.b.WriteString("<Entry>")
+	w.element("UUID", encodeUUID(e.ID))
+	w.b.WriteString("<Times>")
+	w.element("CreationTime", encodeTime(modified))
+	w.element("LastModificationTime", encodeTime(modified))
+	w.element("LastAccessTime", encodeTime(modified))
+	w.b.WriteString("</Times>")
+	for _, f := range e.Fields {
+		w.b.WriteString("<String>")
+		w.element("Key", f.Key)
+		if f.Protected {
+			masked := []byte(f.Value)
+			w.stream.XORKeyStream(masked, masked)
+			w.b.WriteString(`<Value Protected="True">`)
+			w.b.WriteString(base64.StdEncoding.EncodeToString(masked))
+			w.b.WriteString("</Value>")
+		} else {
+			w.element("Value", f.Value)
+		}
+		w.b.WriteString("</String>")
+	}
+	for _, a := range e.Attachments {
+		w.b.WriteString("<Binary>")
+		w.element("Key", a.Name)
+		w.b.WriteString(`<Value Ref="` + strconv.Itoa(w.binaries) + `"/>`)
+		w.b.WriteString("</Binary>")
+		w.binaries++
+	}
+	w.b.WriteString("</Entry>")
+}
+
+// element writes an element with the escaped text.
+func (w *xmlWriter) element(name, text string) {
+	w.b.WriteString("<" + name + ">")
+	_ = xml.EscapeText(w.b, []byte(text))
+	w.b.WriteString("</" + name + ">")
+}
+
+// attachments lists the attachments of the group tree in the order the XML document references them.
+func attachments(g *Group) []Attachment {
+	if g == nil {
+		return nil
+	}
+	// list collects the attachments of the group and its nested groups.
+	var list []Attachment
+	for _, e := range g.Entries {
+		list = append(list, e.Attachments...)
+	}
+	for _, child := range g.Groups {
+		list = append(list, attachments(child)...)
+	}
+	return list
+}
+
+// encodeUUID encodes the ID in base64 as KeePass does, generating a random one when it is zero.
+func encodeUUID(id uuid.UUID) string {
+	if id == uuid.Nil {
+		id = uuid.New()
+	}
+	return base64.StdEncoding.EncodeToString(id[:])
+}
+
+// encodeTime encodes the moment as the base64 little-endian count of seconds since 0001-01-01 UTC.
+func encodeTime(t time.Time) string {
+	return base64.StdEncoding.EncodeToString(binary.LittleEndian.AppendUint64(nil, uint64(t.Unix()+epochOffset)))
+}
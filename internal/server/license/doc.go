@@ -0,0 +1,7 @@
+// Package license provides license keys for the enterprise features of self-hosted AegisVaultKeeper servers.
+//
+// A license key carries the licensee, the licensed features and the validity period, signed with the
+// Ed25519 key of the vendor. Servers verify keys with the public key alone, so keys cannot be forged or
+// altered without the private key. Expired licenses keep their features for a grace period before the
+// features are switched off.
+package license
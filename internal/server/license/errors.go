@@ -0,0 +1,11 @@
+package license
+
+import "errors"
+
+// License key error definitions.
+var (
+	// ErrKeyMalformed indicates that the license key is not a well-formed key.
+	ErrKeyMalformed = errors.New("license key malformed")
+	// ErrSignatureInvalid indicates that the license key was not signed with the private key of the vendor.
+	ErrSignatureInvalid = errors.New("license signature invalid")
+)
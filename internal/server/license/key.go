@@ -0,0 +1,105 @@
+package license
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// keyPrefix starts every license key and names the key format version.
+const keyPrefix = "AVK1"
+
+// rawLicense is the JSON representation of the terms signed in a license key.
+type rawLicense struct {
+	// ID identifies the license.
+	ID string `json:"id"`
+	// Licensee names the organization the license is issued to.
+	Licensee string `json:"licensee"`
+	// Features lists the licensed features.
+	Features []string `json:"features"`
+	// IssuedAt contains the Unix time the license was issued.
+	IssuedAt int64 `json:"issued_at"`
+	// ExpiresAt contains the Unix time the license expires; omitted for perpetual licenses.
+	ExpiresAt int64 `json:"expires_at,omitempty"`
+}
+
+// Parse verifies the license key with the public key of the vendor and returns its terms.
+// Keys have the form AVK1.PAYLOAD.SIGNATURE, where PAYLOAD is the base64url JSON of the terms and SIGNATURE
+// the base64url Ed25519 signature of AVK1.PAYLOAD.
+func Parse(key string, publicKey ed25519.PublicKey) (*License, error) {
+	parts := strings.Split(strings.TrimSpace(key), ".")
+	if len(parts) != 3 || parts[0] != keyPrefix {
+		return nil, fmt.Errorf("expected %s.PAYLOAD.SIGNATURE: %w", keyPrefix, ErrKeyMalformed)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature encoding: %w", ErrKeyMalformed)
+	}
+	if len(publicKey) != ed25519.PublicKeySize ||
+		!ed25519.Verify(publicKey, []byte(parts[0]+"."+parts[1]), signature) {
+		return nil, ErrSignatureInvalid
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid payload encoding: %w", ErrKeyMalformed)
+	}
+	// raw holds the decoded license terms.
+	var raw rawLicense
+	if err := json.Unmarshal(payload, &raw); err != nil {
+		return nil, fmt.Errorf("invalid payload: %w", errors.Join(ErrKeyMalformed, err))
+	}
+	if raw.ID == "" {
+		return nil, fmt.Errorf("license ID is missing: %w", ErrKeyMalformed)
+	}
+
+	l := &License{
+		IssuedAt: time.Unix(raw.IssuedAt, 0).UTC(),
+		ID:       raw.ID,
+		Licensee: raw.Licensee,
+		Features: raw.Features,
+	}
+	if raw.ExpiresAt != 0 {
+		l.ExpiresAt = time.Unix(raw.ExpiresAt, 0).UTC()
+	}
+	return l, nil
+}
+
+// Sign issues a license key for the terms, signed with the private key of the vendor.
+func Sign(l *License, privateKey ed25519.PrivateKey) (string, error) {
+	if l.ID == "" {
+		return "", errors.New("license ID is required")
+	}
+	raw := rawLicense{
+		ID:       l.ID,
+		Licensee: l.Licensee,
+		Features: l.Features,
+		IssuedAt: l.IssuedAt.Unix(),
+	}
+	if !l.ExpiresAt.IsZero() {
+		raw.ExpiresAt = l.ExpiresAt.Unix()
+	}
+	payload, err := json.Marshal(raw)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode license: %w", err)
+	}
+	signed := keyPrefix + "." + base64.RawURLEncoding.EncodeToString(payload)
+	signature := ed25519.Sign(privateKey, []byte(signed))
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// ParsePublicKey decodes a base64 Ed25519 public key.
+func ParsePublicKey(s string) (ed25519.PublicKey, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil {
+		return nil, fmt.Errorf("invalid base64 encoding: %w", err)
+	}
+	if len(key) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("expected %d bytes, got %d", ed25519.PublicKeySize, len(key))
+	}
+	return ed25519.PublicKey(key), nil
+}
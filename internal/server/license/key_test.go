@@ -0,0 +1,88 @@
+package license
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	_, otherKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	terms := &License{
+		IssuedAt:  time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		ExpiresAt: time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC),
+		ID:        "lic_1",
+		Licensee:  "Example Corp",
+		Features:  []string{FeatureSSO, FeatureSCIM},
+	}
+	key, err := Sign(terms, privateKey)
+	require.NoError(t, err)
+	forged, err := Sign(terms, otherKey)
+	require.NoError(t, err)
+	perpetualTerms := &License{IssuedAt: terms.IssuedAt, ID: "lic_2", Features: []string{FeatureOrgVaults}}
+	perpetual, err := Sign(perpetualTerms, privateKey)
+	require.NoError(t, err)
+	parts := strings.Split(key, ".")
+	tampered := parts[0] + "." + base64.RawURLEncoding.EncodeToString(
+		[]byte(`{"id":"lic_1","features":["sso","scim","org_vaults"],"issued_at":0}`),
+	) + "." + parts[2]
+
+	tests := []struct {
+		want    *License
+		wantErr error
+		name    string
+		key     string
+	}{
+		{name: "valid key", key: key, want: terms},
+		{name: "surrounding whitespace", key: "  " + key + "\n", want: terms},
+		{name: "perpetual license", key: perpetual, want: perpetualTerms},
+		{name: "other vendor key", key: forged, wantErr: ErrSignatureInvalid},
+		{name: "altered terms", key: tampered, wantErr: ErrSignatureInvalid},
+		{name: "wrong prefix", key: "AVK2" + strings.TrimPrefix(key, "AVK1"), wantErr: ErrKeyMalformed},
+		{name: "missing signature", key: parts[0] + "." + parts[1], wantErr: ErrKeyMalformed},
+		{name: "empty", key: "", wantErr: ErrKeyMalformed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := Parse(tt.key, publicKey)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParsePublicKey(t *testing.T) {
+	t.Parallel()
+
+	publicKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	got, err := ParsePublicKey(base64.StdEncoding.EncodeToString(publicKey))
+	require.NoError(t, err)
+	assert.Equal(t, publicKey, got)
+
+	_, err = ParsePublicKey(base64.StdEncoding.EncodeToString(publicKey[:16]))
+	require.Error(t, err)
+	_, err = ParsePublicKey("not base64!")
+	require.Error(t, err)
+}
@@ -0,0 +1,76 @@
+package license
+
+import (
+	"slices"
+	"time"
+)
+
+// Enterprise features gated by licenses.
+const (
+	// FeatureSSO licenses signing in with the access tokens of a central identity provider.
+	FeatureSSO = "sso"
+	// FeatureSCIM licenses provisioning users and groups through the SCIM API.
+	FeatureSCIM = "scim"
+	// FeatureAuditExport licenses exporting the audit trail.
+	FeatureAuditExport = "audit_export"
+	// FeatureOrgVaults licenses sharing credentials with groups.
+	FeatureOrgVaults = "org_vaults"
+)
+
+// Features lists the enterprise features in display order.
+var Features = []string{FeatureSSO, FeatureSCIM, FeatureAuditExport, FeatureOrgVaults}
+
+// States of licenses.
+const (
+	// StateValid reports a license within its validity period.
+	StateValid = "valid"
+	// StateGrace reports an expired license whose features are kept for the grace period.
+	StateGrace = "grace"
+	// StateExpired reports an expired license past the grace period; its features are off.
+	StateExpired = "expired"
+)
+
+// License represents the terms of a license key.
+type License struct {
+	// IssuedAt is the time the vendor issued the license.
+	IssuedAt time.Time
+	// ExpiresAt is the time the license expires; zero for perpetual licenses.
+	ExpiresAt time.Time
+	// ID identifies the license.
+	ID string
+	// Licensee names the organization the license is issued to.
+	Licensee string
+	// Features lists the licensed features, such as FeatureSCIM.
+	Features []string
+}
+
+// Includes reports whether the license covers the feature, regardless of its validity.
+func (l *License) Includes(feature string) bool {
+	return slices.Contains(l.Features, feature)
+}
+
+// GraceUntil returns the end of the grace period after expiry; zero for perpetual licenses.
+func (l *License) GraceUntil(grace time.Duration) time.Time {
+	if l.ExpiresAt.IsZero() {
+		return time.Time{}
+	}
+	return l.ExpiresAt.Add(grace)
+}
+
+// State returns the state of the license at the moment, given the grace period after expiry.
+func (l *License) State(at time.Time, grace time.Duration) string {
+	switch {
+	case l.ExpiresAt.IsZero() || at.Before(l.ExpiresAt):
+		return StateValid
+	case at.Before(l.GraceUntil(grace)):
+		return StateGrace
+	default:
+		return StateExpired
+	}
+}
+
+// Allows reports whether the license grants the feature at the moment: it includes the feature and is valid
+// or in its grace period.
+func (l *License) Allows(feature string, at time.Time, grace time.Duration) bool {
+	return l.Includes(feature) && l.State(at, grace) != StateExpired
+}
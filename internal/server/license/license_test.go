@@ -0,0 +1,63 @@
+package license
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLicense_State(t *testing.T) {
+	t.Parallel()
+
+	expiresAt := time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC)
+	grace := 14 * 24 * time.Hour
+
+	tests := []struct {
+		license   *License
+		at        time.Time
+		name      string
+		want      string
+		wantSCIM  bool
+		wantOrgVs bool
+	}{
+		{
+			name:     "perpetual",
+			license:  &License{Features: []string{FeatureSCIM}},
+			at:       expiresAt.AddDate(10, 0, 0),
+			want:     StateValid,
+			wantSCIM: true,
+		},
+		{
+			name:      "before expiry",
+			license:   &License{ExpiresAt: expiresAt, Features: []string{FeatureSCIM, FeatureOrgVaults}},
+			at:        expiresAt.Add(-time.Second),
+			want:      StateValid,
+			wantSCIM:  true,
+			wantOrgVs: true,
+		},
+		{
+			name:     "in grace",
+			license:  &License{ExpiresAt: expiresAt, Features: []string{FeatureSCIM}},
+			at:       expiresAt.Add(grace - time.Second),
+			want:     StateGrace,
+			wantSCIM: true,
+		},
+		{
+			name:    "past grace",
+			license: &License{ExpiresAt: expiresAt, Features: []string{FeatureSCIM}},
+			at:      expiresAt.Add(grace),
+			want:    StateExpired,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, tt.license.State(tt.at, grace))
+			assert.Equal(t, tt.wantSCIM, tt.license.Allows(FeatureSCIM, tt.at, grace))
+			assert.Equal(t, tt.wantOrgVs, tt.license.Allows(FeatureOrgVaults, tt.at, grace))
+		})
+	}
+}
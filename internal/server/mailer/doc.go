@@ -0,0 +1,5 @@
+// Package mailer provides outgoing e-mail delivery for the AegisVaultKeeper server.
+//
+// This package sends plain-text messages through an SMTP relay. Connections are upgraded
+// with STARTTLS whenever the relay offers it.
+package mailer
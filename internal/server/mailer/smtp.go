@@ -0,0 +1,83 @@
+package mailer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidHeader indicates that a message header value contains line breaks.
+var ErrInvalidHeader = errors.New("invalid message header value")
+
+// sendFunc defines the signature of the SMTP transmission function.
+type sendFunc func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+
+// SMTPMailer sends plain-text messages through an SMTP relay.
+type SMTPMailer struct {
+	// auth authenticates the relay session; nil sends without authentication.
+	auth smtp.Auth
+	// send transmits a prepared message to the relay.
+	send sendFunc
+	// addr is the host:port address of the relay.
+	addr string
+	// from is the sender address of all messages.
+	from string
+}
+
+// NewSMTPMailer creates a new SMTPMailer for the relay at host:port.
+// Authentication is used only when username is not empty.
+func NewSMTPMailer(host string, port int, username, password, from string) *SMTPMailer {
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+	return &SMTPMailer{
+		auth: auth,
+		send: smtp.SendMail,
+		addr: net.JoinHostPort(host, strconv.Itoa(port)),
+		from: from,
+	}
+}
+
+// Send delivers a plain-text message with the subject and body to the recipient.
+func (m *SMTPMailer) Send(ctx context.Context, to, subject, body string) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("message sending canceled: %w", err)
+	}
+
+	msg, err := buildMessage(m.from, to, subject, body, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to build message: %w", err)
+	}
+	if err := m.send(m.addr, m.auth, m.from, []string{to}, msg); err != nil {
+		return fmt.Errorf("failed to send message: %w", err)
+	}
+	return nil
+}
+
+// buildMessage renders an RFC 5322 plain-text message.
+func buildMessage(from, to, subject, body string, date time.Time) ([]byte, error) {
+	for _, v := range []string{from, to, subject} {
+		if strings.ContainsAny(v, "\r\n") {
+			return nil, ErrInvalidHeader
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("From: " + from + "\r\n")
+	b.WriteString("To: " + to + "\r\n")
+	b.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", subject) + "\r\n")
+	b.WriteString("Date: " + date.Format(time.RFC1123Z) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(strings.ReplaceAll(body, "\r\n", "\n"), "\n", "\r\n"))
+	return []byte(b.String()), nil
+}
@@ -0,0 +1,132 @@
+package mailer
+
+import (
+	"context"
+	"errors"
+	"net/smtp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSMTPMailer(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		username string
+		wantAuth bool
+	}{
+		{name: "with authentication", username: "user", wantAuth: true},
+		{name: "without authentication", username: "", wantAuth: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			m := NewSMTPMailer("smtp.example.com", 587, tt.username, "secret", "vault@example.com")
+
+			assert.Equal(t, "smtp.example.com:587", m.addr)
+			assert.Equal(t, "vault@example.com", m.from)
+			assert.Equal(t, tt.wantAuth, m.auth != nil)
+		})
+	}
+}
+
+func TestSMTPMailer_Send(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		sendErr     error
+		ctx         func() context.Context
+		name        string
+		to          string
+		errContains string
+		wantSent    bool
+	}{
+		{
+			name:     "success",
+			ctx:      context.Background,
+			to:       "user@example.com",
+			wantSent: true,
+		},
+		{
+			name:        "relay error",
+			ctx:         context.Background,
+			to:          "user@example.com",
+			sendErr:     errors.New("connection refused"),
+			errContains: "failed to send message",
+			wantSent:    true,
+		},
+		{
+			name:        "header injection",
+			ctx:         context.Background,
+			to:          "user@example.com\r\nBcc: victim@example.com",
+			errContains: "failed to build message",
+		},
+		{
+			name: "canceled context",
+			ctx: func() context.Context {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+				return ctx
+			},
+			to:          "user@example.com",
+			errContains: "message sending canceled",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				sent  bool
+				gotTo []string
+			)
+			m := NewSMTPMailer("smtp.example.com", 25, "", "", "vault@example.com")
+			m.send = func(addr string, _ smtp.Auth, from string, to []string, msg []byte) error {
+				sent = true
+				gotTo = to
+				assert.Equal(t, "smtp.example.com:25", addr)
+				assert.Equal(t, "vault@example.com", from)
+				assert.Contains(t, string(msg), "\r\n\r\nbody")
+				return tt.sendErr
+			}
+
+			err := m.Send(tt.ctx(), tt.to, "Subject", "body")
+
+			assert.Equal(t, tt.wantSent, sent)
+			if tt.errContains != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errContains)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, []string{tt.to}, gotTo)
+		})
+	}
+}
+
+func TestBuildMessage(t *testing.T) {
+	t.Parallel()
+
+	date := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+
+	msg, err := buildMessage("vault@example.com", "user@example.com", "Отчет", "line 1\nline 2", date)
+	require.NoError(t, err)
+
+	text := string(msg)
+	headers, body, found := strings.Cut(text, "\r\n\r\n")
+	require.True(t, found)
+	assert.Contains(t, headers, "From: vault@example.com\r\n")
+	assert.Contains(t, headers, "To: user@example.com\r\n")
+	assert.Contains(t, headers, "Subject: =?utf-8?q?")
+	assert.Contains(t, headers, "Date: Thu, 15 Oct 2026 12:00:00 +0000")
+	assert.Contains(t, headers, "Content-Type: text/plain; charset=utf-8")
+	assert.Equal(t, "line 1\r\nline 2", body)
+}
@@ -0,0 +1,5 @@
+// Package metrics provides in-process operational counters for the AegisVaultKeeper server.
+//
+// This package implements a lightweight metrics registry that services update
+// and the delivery layer exposes for monitoring systems.
+package metrics
@@ -0,0 +1,40 @@
+package metrics
+
+import (
+	"maps"
+	"sync"
+)
+
+// Registry stores named integer metrics safe for concurrent use.
+type Registry struct {
+	// values holds the current metric values by name.
+	values map[string]int64
+	// mu guards values.
+	mu sync.RWMutex
+}
+
+// NewRegistry creates a new empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{values: make(map[string]int64)}
+}
+
+// Add increments the named counter by delta.
+func (r *Registry) Add(name string, delta int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.values[name] += delta
+}
+
+// Set assigns the named gauge to value.
+func (r *Registry) Set(name string, value int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.values[name] = value
+}
+
+// Snapshot returns a copy of all current metric values.
+func (r *Registry) Snapshot() map[string]int64 {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return maps.Clone(r.values)
+}
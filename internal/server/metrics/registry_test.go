@@ -0,0 +1,79 @@
+package metrics
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistry(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		apply func(r *Registry)
+		want  map[string]int64
+		name  string
+	}{
+		{
+			name:  "empty",
+			apply: func(r *Registry) {},
+			want:  map[string]int64{},
+		},
+		{
+			name: "add_accumulates",
+			apply: func(r *Registry) {
+				r.Add("counter", 2)
+				r.Add("counter", 3)
+			},
+			want: map[string]int64{"counter": 5},
+		},
+		{
+			name: "set_overwrites",
+			apply: func(r *Registry) {
+				r.Set("gauge", 7)
+				r.Set("gauge", 1)
+			},
+			want: map[string]int64{"gauge": 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := NewRegistry()
+			tt.apply(r)
+			assert.Equal(t, tt.want, r.Snapshot())
+		})
+	}
+}
+
+func TestRegistry_Concurrent(t *testing.T) {
+	t.Parallel()
+
+	r := NewRegistry()
+	var wg sync.WaitGroup
+	for range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r.Add("counter", 1)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int64(50), r.Snapshot()["counter"])
+}
+
+func TestRegistry_SnapshotIsCopy(t *testing.T) {
+	t.Parallel()
+
+	r := NewRegistry()
+	r.Set("gauge", 1)
+
+	snapshot := r.Snapshot()
+	snapshot["gauge"] = 100
+
+	assert.Equal(t, int64(1), r.Snapshot()["gauge"])
+}
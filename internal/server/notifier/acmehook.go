@@ -0,0 +1,38 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// acmeAccountUpdated is the event type of the ACME account change notifications.
+const acmeAccountUpdated = "acme.account_updated"
+
+// ACMEHook tells the automation hooks of ACME accounts, such as certificate renewal tooling, about changes.
+type ACMEHook struct {
+	// client performs the HTTP requests.
+	client *http.Client
+}
+
+// NewACMEHook creates a new ACMEHook.
+func NewACMEHook(client *http.Client) *ACMEHook {
+	return &ACMEHook{client: client}
+}
+
+// AccountChanged posts an account change event to the hook URL held in target. The event identifies the
+// account only; the hook fetches the account key and DNS credentials through the lease API.
+func (h *ACMEHook) AccountChanged(ctx context.Context, target string, accountID uuid.UUID, updatedAt time.Time) error {
+	payload := struct {
+		UpdatedAt time.Time `json:"updated_at"`
+		Event     string    `json:"event"`
+		AccountID uuid.UUID `json:"account_id"`
+	}{Event: acmeAccountUpdated, AccountID: accountID, UpdatedAt: updatedAt.UTC()}
+	if err := postJSON(ctx, h.client, target, payload); err != nil {
+		return fmt.Errorf("failed to call ACME automation hook: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestACMEHook_AccountChanged(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		status  int
+		wantErr bool
+	}{
+		{name: "delivered", status: http.StatusNoContent},
+		{name: "hook failing", status: http.StatusInternalServerError, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			accountID := uuid.New()
+			updatedAt := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+			var got map[string]string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/hooks/secret", r.URL.Path)
+				assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+				assert.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+				w.WriteHeader(tt.status)
+			}))
+			t.Cleanup(server.Close)
+
+			err := NewACMEHook(server.Client()).
+				AccountChanged(context.Background(), server.URL+"/hooks/secret", accountID, updatedAt)
+
+			assert.Equal(t, map[string]string{
+				"event":      "acme.account_updated",
+				"account_id": accountID.String(),
+				"updated_at": "2026-10-15T12:00:00Z",
+			}, got)
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.NotContains(t, err.Error(), "secret")
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,9 @@
+// Package notifier provides outgoing notification delivery over channels other than e-mail
+// for the AegisVaultKeeper server.
+//
+// This package sends messages to Telegram chats through the Bot API, to Slack incoming webhooks
+// and to browsers as encrypted web push messages (RFC 8291) authenticated with VAPID (RFC 8292).
+// The chat senders share the Send signature of the SMTP mailer, so the notification service treats
+// all channels alike; web push messages are pushed to the subscriptions of user devices instead.
+// The automation hooks of ACME accounts are told about account changes with secret-free JSON events.
+package notifier
@@ -0,0 +1,61 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// maxErrorBodySize limits how much of an error response is read to release the connection.
+const maxErrorBodySize = 4 << 10
+
+// StatusError reports a non-2xx response of the remote service.
+type StatusError struct {
+	// StatusCode contains the HTTP status code of the response.
+	StatusCode int
+}
+
+// Error implements the error interface.
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected status %d", e.StatusCode)
+}
+
+// post sends the body to the URL and fails on non-2xx responses. Transport errors are stripped of
+// the URL, which embeds secrets such as the bot token or the webhook path.
+func post(ctx context.Context, client *http.Client, target string, body []byte, header http.Header) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return errors.New("failed to build request")
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxErrorBodySize))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return &StatusError{StatusCode: resp.StatusCode}
+	}
+	return nil
+}
+
+// postJSON sends the payload encoded as JSON to the URL.
+func postJSON(ctx context.Context, client *http.Client, target string, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode payload: %w", err)
+	}
+	return post(ctx, client, target, body, http.Header{"Content-Type": {"application/json"}})
+}
@@ -0,0 +1,29 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// SlackSender sends messages to Slack incoming webhooks.
+type SlackSender struct {
+	// client performs the HTTP requests.
+	client *http.Client
+}
+
+// NewSlackSender creates a new SlackSender.
+func NewSlackSender(client *http.Client) *SlackSender {
+	return &SlackSender{client: client}
+}
+
+// Send posts a message with the subject and body to the webhook URL held in to.
+func (s *SlackSender) Send(ctx context.Context, to, subject, body string) error {
+	payload := struct {
+		Text string `json:"text"`
+	}{Text: "*" + subject + "*\n" + body}
+	if err := postJSON(ctx, s.client, to, payload); err != nil {
+		return fmt.Errorf("failed to send Slack message: %w", err)
+	}
+	return nil
+}
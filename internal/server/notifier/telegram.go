@@ -0,0 +1,38 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// telegramAPIURL is the base URL of the Telegram Bot API.
+const telegramAPIURL = "https://api.telegram.org"
+
+// TelegramSender sends messages to Telegram chats through a bot.
+type TelegramSender struct {
+	// client performs the HTTP requests.
+	client *http.Client
+	// baseURL is the base URL of the Bot API.
+	baseURL string
+	// token authenticates the bot.
+	token string
+}
+
+// NewTelegramSender creates a new TelegramSender for the bot with the token.
+// Users must start a conversation with the bot before it can message them.
+func NewTelegramSender(client *http.Client, token string) *TelegramSender {
+	return &TelegramSender{client: client, baseURL: telegramAPIURL, token: token}
+}
+
+// Send delivers a message with the subject and body to the chat with the ID held in to.
+func (s *TelegramSender) Send(ctx context.Context, to, subject, body string) error {
+	payload := struct {
+		ChatID string `json:"chat_id"`
+		Text   string `json:"text"`
+	}{ChatID: to, Text: subject + "\n\n" + body}
+	if err := postJSON(ctx, s.client, s.baseURL+"/bot"+s.token+"/sendMessage", payload); err != nil {
+		return fmt.Errorf("failed to send Telegram message: %w", err)
+	}
+	return nil
+}
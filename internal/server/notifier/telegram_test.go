@@ -0,0 +1,67 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTelegramSender_Send(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		status  int
+		wantErr bool
+	}{
+		{name: "delivered", status: http.StatusOK},
+		{name: "chat not found", status: http.StatusBadRequest, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var got map[string]string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/botsecret-token/sendMessage", r.URL.Path)
+				assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+				assert.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+				w.WriteHeader(tt.status)
+			}))
+			t.Cleanup(server.Close)
+
+			s := NewTelegramSender(server.Client(), "secret-token")
+			s.baseURL = server.URL
+
+			err := s.Send(context.Background(), "-100123", "New login", "Code: 123456")
+
+			assert.Equal(t, map[string]string{"chat_id": "-100123", "text": "New login\n\nCode: 123456"}, got)
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.NotContains(t, err.Error(), "secret-token")
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestTelegramSender_Send_TransportErrorHidesToken(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+	s := NewTelegramSender(server.Client(), "secret-token")
+	s.baseURL = server.URL
+
+	err := s.Send(context.Background(), "42", "subject", "body")
+
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "secret-token")
+}
@@ -0,0 +1,244 @@
+package notifier
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/notification"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Web push message parameters.
+const (
+	// webPushRecordSize is the record size announced in the aes128gcm header; messages fit one record.
+	webPushRecordSize = 4096
+	// webPushSaltSize is the size of the random salt of each message.
+	webPushSaltSize = 16
+	// webPushMaxPayload is the largest plaintext fitting the record with the padding delimiter and tag,
+	// after the 86-byte header push services count against their 4096-byte limit.
+	webPushMaxPayload = webPushRecordSize - 86 - 1 - 16
+	// webPushTTL specifies how long push services keep undelivered messages, in seconds.
+	webPushTTL = 24 * 60 * 60
+	// vapidTokenLifetime specifies how long VAPID tokens are valid; RFC 8292 allows at most 24 hours.
+	vapidTokenLifetime = 12 * time.Hour
+	// webPushSyncTopic collapses undelivered sync messages of a subscription into the latest one.
+	webPushSyncTopic = "sync"
+)
+
+// ErrPayloadTooLarge indicates that a message exceeds the payload limit of push services.
+var ErrPayloadTooLarge = errors.New("push message payload too large")
+
+// WebPushSender sends encrypted web push messages (RFC 8291) to browser push subscriptions,
+// identifying the server to push services with VAPID (RFC 8292).
+type WebPushSender struct {
+	// client performs the HTTP requests.
+	client *http.Client
+	// key signs the VAPID tokens.
+	key *ecdsa.PrivateKey
+	// now returns the current time used for token expiry.
+	now func() time.Time
+	// publicKey contains the uncompressed VAPID public key announced to push services.
+	publicKey string
+	// subject contains the contact URI of the operator (mailto: or https:).
+	subject string
+}
+
+// NewWebPushSender creates a new WebPushSender with the base64url-encoded P-256 VAPID private key
+// and the contact subject of the operator.
+func NewWebPushSender(client *http.Client, vapidPrivateKey, subject string) (*WebPushSender, error) {
+	key, err := ParseVAPIDPrivateKey(vapidPrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	pub, err := key.PublicKey.ECDH()
+	if err != nil {
+		return nil, fmt.Errorf("invalid VAPID key: %w", err)
+	}
+	return &WebPushSender{
+		client:    client,
+		key:       key,
+		now:       time.Now,
+		publicKey: base64.RawURLEncoding.EncodeToString(pub.Bytes()),
+		subject:   subject,
+	}, nil
+}
+
+// PublicKey returns the base64url-encoded VAPID public key browsers pass as applicationServerKey
+// when subscribing.
+func (s *WebPushSender) PublicKey() string {
+	return s.publicKey
+}
+
+// Push encrypts the message and posts it to the push service of the subscription. Notifications are
+// sent with high urgency; sync messages with normal urgency under a topic, so a device that is offline
+// receives only the latest one. Returns notification.ErrPushSubscriptionGone when the subscription
+// no longer exists.
+func (s *WebPushSender) Push(
+	ctx context.Context,
+	sub *notification.WebPushSubscription,
+	msg notification.PushMessage,
+) error {
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to encode push message: %w", err)
+	}
+	if len(payload) > webPushMaxPayload {
+		return ErrPayloadTooLarge
+	}
+
+	uaPublic, err := sub.PublicKey()
+	if err != nil {
+		return fmt.Errorf("invalid push subscription: %w", err)
+	}
+	authSecret, err := sub.AuthSecret()
+	if err != nil {
+		return fmt.Errorf("invalid push subscription: %w", err)
+	}
+	content, err := encryptWebPush(payload, uaPublic, authSecret)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt push message: %w", err)
+	}
+	token, err := s.vapidToken(sub.Endpoint)
+	if err != nil {
+		return fmt.Errorf("failed to sign VAPID token: %w", err)
+	}
+
+	header := http.Header{
+		"Content-Type":     {"application/octet-stream"},
+		"Content-Encoding": {"aes128gcm"},
+		"Ttl":              {strconv.Itoa(webPushTTL)},
+		"Urgency":          {"high"},
+		"Authorization":    {"vapid t=" + token + ", k=" + s.publicKey},
+	}
+	if msg.Type == notification.PushMessageSync {
+		header.Set("Urgency", "normal")
+		header.Set("Topic", webPushSyncTopic)
+	}
+	err = post(ctx, s.client, sub.Endpoint, content, header)
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) &&
+		(statusErr.StatusCode == http.StatusNotFound || statusErr.StatusCode == http.StatusGone) {
+		return notification.ErrPushSubscriptionGone
+	}
+	if err != nil {
+		return fmt.Errorf("failed to send push message: %w", err)
+	}
+	return nil
+}
+
+// vapidToken signs a VAPID token for the push service serving the endpoint.
+func (s *WebPushSender) vapidToken(endpoint string) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", fmt.Errorf("invalid endpoint: %w", err)
+	}
+	claims := jwt.MapClaims{
+		"aud": u.Scheme + "://" + u.Host,
+		"exp": s.now().Add(vapidTokenLifetime).Unix(),
+		"sub": s.subject,
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodES256, claims).SignedString(s.key)
+}
+
+// ParseVAPIDPrivateKey decodes a base64url-encoded 32-byte P-256 private key, padded or not.
+func ParseVAPIDPrivateKey(s string) (*ecdsa.PrivateKey, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+	if err != nil {
+		return nil, errors.New("VAPID private key must be base64url-encoded")
+	}
+	key, err := ecdh.P256().NewPrivateKey(raw)
+	if err != nil {
+		return nil, errors.New("VAPID private key must be a 32-byte P-256 scalar")
+	}
+	pub := key.PublicKey().Bytes()
+	return &ecdsa.PrivateKey{
+		PublicKey: ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     new(big.Int).SetBytes(pub[1:33]),
+			Y:     new(big.Int).SetBytes(pub[33:]),
+		},
+		D: new(big.Int).SetBytes(raw),
+	}, nil
+}
+
+// encryptWebPush encrypts the payload for the user agent with the aes128gcm content coding
+// as specified by RFC 8291, using a fresh ephemeral key and salt.
+func encryptWebPush(payload, uaPublic, authSecret []byte) ([]byte, error) {
+	uaKey, err := ecdh.P256().NewPublicKey(uaPublic)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user agent key: %w", err)
+	}
+	asKey, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate ephemeral key: %w", err)
+	}
+	salt := make([]byte, webPushSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+	return sealWebPush(payload, uaKey, asKey, authSecret, salt)
+}
+
+// sealWebPush encrypts the payload with the given ephemeral key and salt.
+func sealWebPush(
+	payload []byte,
+	uaKey *ecdh.PublicKey,
+	asKey *ecdh.PrivateKey,
+	authSecret, salt []byte,
+) ([]byte, error) {
+	secret, err := asKey.ECDH(uaKey)
+	if err != nil {
+		return nil, fmt.Errorf("key agreement failed: %w", err)
+	}
+	asPublic := asKey.PublicKey().Bytes()
+
+	keyInfo := "WebPush: info\x00" + string(uaKey.Bytes()) + string(asPublic)
+	ikm, err := hkdf.Key(sha256.New, secret, authSecret, keyInfo, 32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive input key: %w", err)
+	}
+	cek, err := hkdf.Key(sha256.New, ikm, salt, "Content-Encoding: aes128gcm\x00", 16)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive content key: %w", err)
+	}
+	nonce, err := hkdf.Key(sha256.New, ikm, salt, "Content-Encoding: nonce\x00", 12)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive nonce: %w", err)
+	}
+
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCM: %w", err)
+	}
+
+	header := make([]byte, 0, webPushSaltSize+4+1+len(asPublic))
+	header = append(header, salt...)
+	header = binary.BigEndian.AppendUint32(header, webPushRecordSize)
+	header = append(header, byte(len(asPublic)))
+	header = append(header, asPublic...)
+
+	plaintext := append(append(make([]byte, 0, len(payload)+1), payload...), 0x02)
+	return gcm.Seal(header, nonce, plaintext, nil), nil
+}
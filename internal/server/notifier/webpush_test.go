@@ -0,0 +1,282 @@
+package notifier
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/notification"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testVAPIDKey is a base64url-encoded P-256 private key used in tests.
+var testVAPIDKey = base64.RawURLEncoding.EncodeToString(
+	[]byte("0123456789abcdef0123456789abcdef"),
+)
+
+// decryptWebPush decrypts an aes128gcm message with the user agent key as a browser would.
+func decryptWebPush(t *testing.T, content []byte, uaKey *ecdh.PrivateKey, authSecret []byte) []byte {
+	t.Helper()
+
+	salt := content[:16]
+	assert.Equal(t, uint32(webPushRecordSize), binary.BigEndian.Uint32(content[16:20]))
+	idLen := int(content[20])
+	asKey, err := ecdh.P256().NewPublicKey(content[21 : 21+idLen])
+	require.NoError(t, err)
+	secret, err := uaKey.ECDH(asKey)
+	require.NoError(t, err)
+
+	keyInfo := "WebPush: info\x00" + string(uaKey.PublicKey().Bytes()) + string(asKey.Bytes())
+	ikm, err := hkdf.Key(sha256.New, secret, authSecret, keyInfo, 32)
+	require.NoError(t, err)
+	cek, err := hkdf.Key(sha256.New, ikm, salt, "Content-Encoding: aes128gcm\x00", 16)
+	require.NoError(t, err)
+	nonce, err := hkdf.Key(sha256.New, ikm, salt, "Content-Encoding: nonce\x00", 12)
+	require.NoError(t, err)
+
+	block, err := aes.NewCipher(cek)
+	require.NoError(t, err)
+	gcm, err := cipher.NewGCM(block)
+	require.NoError(t, err)
+	plaintext, err := gcm.Open(nil, nonce, content[21+idLen:], nil)
+	require.NoError(t, err)
+	require.Equal(t, byte(0x02), plaintext[len(plaintext)-1])
+	return plaintext[:len(plaintext)-1]
+}
+
+func TestSealWebPush_RFC8291Example(t *testing.T) {
+	t.Parallel()
+
+	// Test vector from RFC 8291, Appendix A.
+	decode := func(s string) []byte {
+		b, err := base64.RawURLEncoding.DecodeString(s)
+		require.NoError(t, err)
+		return b
+	}
+	uaKey, err := ecdh.P256().NewPublicKey(decode(
+		"BCVxsr7N_eNgVRqvHtD0zTZsEc6-VV-JvLexhqUzORcxaOzi6-AYWXvTBHm4bjyPjs7Vd8pZGH6SRpkNtoIAiw4"))
+	require.NoError(t, err)
+	asKey, err := ecdh.P256().NewPrivateKey(decode("yfWPiYE-n46HLnH0KqZOF1fJJU3MYrct3AELtAQ-oRw"))
+	require.NoError(t, err)
+
+	got, err := sealWebPush(
+		[]byte("When I grow up, I want to be a watermelon"),
+		uaKey,
+		asKey,
+		decode("BTBZMqHH6r4Tts7J_aSIgg"),
+		decode("DGv6ra1nlYgDCS1FRnbzlw"),
+	)
+
+	require.NoError(t, err)
+	want := "DGv6ra1nlYgDCS1FRnbzlwAAEABBBP4z9KsN6nGRTbVYI_c7VJSPQTBtkgcy27mlmlMoZIIgDll6e3vCYLocInmYWAmS6TlzAC8wEqKK" +
+		"6PBru3jl7A_yl95bQpu6cVPTpK4Mqgkf1CXztLVBSt2Ks3oZwbuwXPXLWyouBWLVWGNWQexSgSxsj_Qulcy4a-fN"
+	assert.Equal(t, want, base64.RawURLEncoding.EncodeToString(got))
+}
+
+// testSubscription returns a push subscription for the endpoint with the user agent keys.
+func testSubscription(
+	endpoint string,
+	uaKey *ecdh.PrivateKey,
+	authSecret []byte,
+) *notification.WebPushSubscription {
+	return &notification.WebPushSubscription{
+		Endpoint: endpoint,
+		Keys: notification.WebPushKeys{
+			P256dh: base64.RawURLEncoding.EncodeToString(uaKey.PublicKey().Bytes()),
+			Auth:   base64.RawURLEncoding.EncodeToString(authSecret),
+		},
+	}
+}
+
+func TestWebPushSender_Push(t *testing.T) {
+	t.Parallel()
+
+	uaKey, err := ecdh.P256().GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	authSecret := make([]byte, 16)
+	_, err = rand.Read(authSecret)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		wantPayload string
+		wantUrgency string
+		wantTopic   string
+		msg         notification.PushMessage
+	}{
+		{
+			name: "notification",
+			msg: notification.PushMessage{
+				Type:     notification.PushMessageNotification,
+				Category: notification.CategorySecurity,
+				Title:    "New login",
+				Body:     "Code: 123456",
+			},
+			wantPayload: `{"type":"notification","category":"security","title":"New login","body":"Code: 123456"}`,
+			wantUrgency: "high",
+		},
+		{
+			name:        "sync",
+			msg:         notification.PushMessage{Type: notification.PushMessageSync},
+			wantPayload: `{"type":"sync"}`,
+			wantUrgency: "normal",
+			wantTopic:   "sync",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				gotHeader http.Header
+				gotBody   []byte
+			)
+			server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotHeader = r.Header.Clone()
+				gotBody, _ = io.ReadAll(r.Body)
+				w.WriteHeader(http.StatusCreated)
+			}))
+			t.Cleanup(server.Close)
+
+			s, err := NewWebPushSender(server.Client(), testVAPIDKey, "mailto:ops@example.com")
+			require.NoError(t, err)
+
+			sub := testSubscription(server.URL+"/push/abc", uaKey, authSecret)
+			require.NoError(t, s.Push(context.Background(), sub, tt.msg))
+
+			assert.Equal(t, "aes128gcm", gotHeader.Get("Content-Encoding"))
+			assert.Equal(t, "86400", gotHeader.Get("TTL"))
+			assert.Equal(t, tt.wantUrgency, gotHeader.Get("Urgency"))
+			assert.Equal(t, tt.wantTopic, gotHeader.Get("Topic"))
+			payload := decryptWebPush(t, gotBody, uaKey, authSecret)
+			assert.JSONEq(t, tt.wantPayload, string(payload))
+
+			auth, ok := strings.CutPrefix(gotHeader.Get("Authorization"), "vapid t=")
+			require.True(t, ok)
+			token, key, ok := strings.Cut(auth, ", k=")
+			require.True(t, ok)
+			assert.Equal(t, s.PublicKey(), key)
+
+			vapid, err := ParseVAPIDPrivateKey(testVAPIDKey)
+			require.NoError(t, err)
+			claims := jwt.MapClaims{}
+			_, err = jwt.ParseWithClaims(token, claims, func(*jwt.Token) (interface{}, error) {
+				return &vapid.PublicKey, nil
+			}, jwt.WithValidMethods([]string{"ES256"}), jwt.WithAudience(server.URL), jwt.WithExpirationRequired())
+			require.NoError(t, err)
+			assert.Equal(t, "mailto:ops@example.com", claims["sub"])
+		})
+	}
+}
+
+func TestWebPushSender_Push_Errors(t *testing.T) {
+	t.Parallel()
+
+	uaKey, err := ecdh.P256().GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	authSecret := make([]byte, 16)
+
+	tests := []struct {
+		wantErr error
+		name    string
+		body    string
+		status  int
+		noKeys  bool
+	}{
+		{name: "malformed subscription", status: http.StatusCreated, body: "body", noKeys: true},
+		{
+			name:    "payload too large",
+			status:  http.StatusCreated,
+			body:    strings.Repeat("a", webPushMaxPayload),
+			wantErr: ErrPayloadTooLarge,
+		},
+		{
+			name:    "subscription gone",
+			status:  http.StatusGone,
+			body:    "body",
+			wantErr: notification.ErrPushSubscriptionGone,
+		},
+		{
+			name:    "subscription not found",
+			status:  http.StatusNotFound,
+			body:    "body",
+			wantErr: notification.ErrPushSubscriptionGone,
+		},
+		{name: "push service failure", status: http.StatusInternalServerError, body: "body"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(tt.status)
+			}))
+			t.Cleanup(server.Close)
+			s, err := NewWebPushSender(server.Client(), testVAPIDKey, "mailto:ops@example.com")
+			require.NoError(t, err)
+
+			sub := testSubscription(server.URL+"/push/abc", uaKey, authSecret)
+			if tt.noKeys {
+				sub.Keys = notification.WebPushKeys{}
+			}
+			msg := notification.PushMessage{Type: notification.PushMessageNotification, Title: "t", Body: tt.body}
+			err = s.Push(context.Background(), sub, msg)
+
+			require.Error(t, err)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NotErrorIs(t, err, notification.ErrPushSubscriptionGone)
+			}
+		})
+	}
+}
+
+func TestParseVAPIDPrivateKey(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		key     string
+		wantErr bool
+	}{
+		{name: "unpadded", key: testVAPIDKey},
+		{name: "padded", key: base64.URLEncoding.EncodeToString([]byte("0123456789abcdef0123456789abcdef"))},
+		{name: "not base64url", key: "not a key!", wantErr: true},
+		{name: "short", key: base64.RawURLEncoding.EncodeToString([]byte("short")), wantErr: true},
+		{name: "zero scalar", key: base64.RawURLEncoding.EncodeToString(make([]byte, 32)), wantErr: true},
+		{name: "hex", key: hex.EncodeToString(make([]byte, 32)), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			key, err := ParseVAPIDPrivateKey(tt.key)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Nil(t, key)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, key.Curve.IsOnCurve(key.X, key.Y))
+		})
+	}
+}
@@ -0,0 +1,8 @@
+// Package otpmigration provides the otpauth-migration format of Google Authenticator exports for the
+// AegisVaultKeeper server.
+//
+// Google Authenticator transfers its one-time password seeds as QR codes of otpauth-migration://offline URIs
+// carrying a base64 encoded protobuf payload. This package decodes such URIs into OTP parameters and encodes
+// OTP parameters into them, split into batches small enough for a QR code each, and renders the parameters
+// as the otpauth:// key URIs understood by authenticator apps.
+package otpmigration
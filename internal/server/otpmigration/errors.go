@@ -0,0 +1,15 @@
+package otpmigration
+
+import "errors"
+
+// otpauth-migration error definitions.
+var (
+	// ErrInvalidURI indicates a URI that is not an otpauth-migration://offline URI with a data parameter.
+	ErrInvalidURI = errors.New("invalid otpauth-migration URI")
+
+	// ErrInvalidPayload indicates a migration payload that is not valid base64 or protobuf.
+	ErrInvalidPayload = errors.New("invalid otpauth-migration payload")
+
+	// ErrSecretRequired indicates OTP parameters without a secret.
+	ErrSecretRequired = errors.New("OTP secret is required")
+)
@@ -0,0 +1,172 @@
+package otpmigration
+
+import (
+	"encoding/base32"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Algorithm identifies the HMAC hash function of one-time passwords.
+type Algorithm int32
+
+// Algorithms of the migration payload.
+const (
+	// AlgorithmUnspecified leaves the algorithm to the default, SHA-1.
+	AlgorithmUnspecified Algorithm = iota
+	// AlgorithmSHA1 is HMAC-SHA1.
+	AlgorithmSHA1
+	// AlgorithmSHA256 is HMAC-SHA256.
+	AlgorithmSHA256
+	// AlgorithmSHA512 is HMAC-SHA512.
+	AlgorithmSHA512
+	// AlgorithmMD5 is HMAC-MD5.
+	AlgorithmMD5
+)
+
+// algorithmNames maps the algorithms to their names in otpauth:// key URIs.
+var algorithmNames = map[Algorithm]string{
+	AlgorithmSHA1:   "SHA1",
+	AlgorithmSHA256: "SHA256",
+	AlgorithmSHA512: "SHA512",
+	AlgorithmMD5:    "MD5",
+}
+
+// Type identifies the kind of one-time passwords.
+type Type int32
+
+// Types of the migration payload.
+const (
+	// TypeUnspecified leaves the type to the default, time-based.
+	TypeUnspecified Type = iota
+	// TypeHOTP is counter-based one-time passwords (RFC 4226).
+	TypeHOTP
+	// TypeTOTP is time-based one-time passwords (RFC 6238).
+	TypeTOTP
+)
+
+const (
+	// scheme is the scheme of migration URIs.
+	scheme = "otpauth-migration"
+	// host is the host of migration URIs.
+	host = "offline"
+	// defaultDigits is the number of digits of passwords unless eight are specified.
+	defaultDigits = 6
+	// period is the validity of time-based passwords in seconds; the migration format does not carry it.
+	period = 30
+	// DefaultBatchSize is the number of OTPs per URI that still fits a readable QR code.
+	DefaultBatchSize = 10
+)
+
+// OTP contains the parameters of a one-time password generator.
+type OTP struct {
+	// Secret contains the shared secret key.
+	Secret []byte
+	// Name contains the account name, often prefixed with the issuer and a colon.
+	Name string
+	// Issuer contains the name of the service the account belongs to.
+	Issuer string
+	// Counter contains the moving factor of counter-based passwords.
+	Counter int64
+	// Algorithm identifies the HMAC hash function.
+	Algorithm Algorithm
+	// Type identifies the kind of passwords.
+	Type Type
+	// Digits is the number of password digits, 6 or 8.
+	Digits int
+}
+
+// Decode decodes an otpauth-migration://offline URI of a Google Authenticator export into the OTPs it carries.
+// An export of many accounts spans several URIs, each of which decodes on its own.
+func Decode(uri string) ([]*OTP, error) {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != scheme || u.Host != host {
+		return nil, ErrInvalidURI
+	}
+	// Scanners that do not escape the parameter leave plus signs that query decoding turns into spaces.
+	data := strings.ReplaceAll(u.Query().Get("data"), " ", "+")
+	if data == "" {
+		return nil, ErrInvalidURI
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		// Some scanners drop the padding of the parameter.
+		if raw, err = base64.RawStdEncoding.DecodeString(strings.TrimRight(data, "=")); err != nil {
+			return nil, errors.Join(ErrInvalidPayload, err)
+		}
+	}
+	otps, err := unmarshalPayload(raw)
+	if err != nil {
+		return nil, errors.Join(ErrInvalidPayload, err)
+	}
+	return otps, nil
+}
+
+// Encode encodes the OTPs into otpauth-migration://offline URIs of at most batchSize OTPs each, which Google
+// Authenticator imports as one batch; a non-positive batchSize uses DefaultBatchSize.
+func Encode(otps []*OTP, batchSize int) ([]string, error) {
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+	for _, o := range otps {
+		if len(o.Secret) == 0 {
+			return nil, ErrSecretRequired
+		}
+	}
+
+	batches := max((len(otps)+batchSize-1)/batchSize, 1)
+	batchID := rand.Int32()
+	uris := make([]string, 0, batches)
+	for i := range batches {
+		chunk := otps[min(i*batchSize, len(otps)):min((i+1)*batchSize, len(otps))]
+		raw := marshalPayload(chunk, batches, i, batchID)
+		query := url.Values{"data": {base64.StdEncoding.EncodeToString(raw)}}
+		uris = append(uris, scheme+"://"+host+"?"+query.Encode())
+	}
+	return uris, nil
+}
+
+// KeyURI renders the OTP as an otpauth:// key URI, as scanned by authenticator apps from single-account QR codes.
+func (o *OTP) KeyURI() string {
+	label := o.Name
+	if o.Issuer != "" && !strings.HasPrefix(o.Name, o.Issuer+":") {
+		label = o.Issuer + ":" + o.Name
+	}
+	query := url.Values{
+		"secret":    {base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(o.Secret)},
+		"algorithm": {o.algorithmName()},
+		"digits":    {strconv.Itoa(o.digits())},
+	}
+	if o.Issuer != "" {
+		query.Set("issuer", o.Issuer)
+	}
+	kind := "totp"
+	if o.Type == TypeHOTP {
+		kind = "hotp"
+		query.Set("counter", strconv.FormatInt(o.Counter, 10))
+	} else {
+		query.Set("period", strconv.Itoa(period))
+	}
+	return fmt.Sprintf("otpauth://%s/%s?%s", kind, url.PathEscape(label), query.Encode())
+}
+
+// algorithmName returns the name of the algorithm in key URIs, SHA1 when it is unspecified.
+func (o *OTP) algorithmName() string {
+	if name, ok := algorithmNames[o.Algorithm]; ok {
+		return name
+	}
+	return algorithmNames[AlgorithmSHA1]
+}
+
+// digits returns the number of password digits, 6 unless eight are specified.
+func (o *OTP) digits() int {
+	if o.Digits == 8 {
+		return 8
+	}
+	return defaultDigits
+}
@@ -0,0 +1,192 @@
+package otpmigration
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// exampleSecret is the key "JBSWY3DPEHPK3PXP" of the Google Authenticator key URI documentation.
+var exampleSecret = []byte("Hello!\xde\xad\xbe\xef")
+
+// examplePayload is the MigrationPayload of a Google Authenticator export of the example account.
+var examplePayload = slices.Concat(
+	[]byte{0x0A, 0x35}, // otp_parameters, 53 bytes
+	[]byte{0x0A, 0x0A}, // secret, 10 bytes
+	exampleSecret,
+	[]byte{0x12, 0x18}, // name, 24 bytes
+	[]byte("Example:alice@google.com"),
+	[]byte{0x1A, 0x07}, // issuer, 7 bytes
+	[]byte("Example"),
+	[]byte{0x20, 0x01},       // algorithm SHA1
+	[]byte{0x28, 0x01},       // digits SIX
+	[]byte{0x30, 0x02},       // type TOTP
+	[]byte{0x10, 0x01},       // version 1
+	[]byte{0x18, 0x01},       // batch_size 1
+	[]byte{0x28, 0xC8, 0x01}, // batch_id 200
+)
+
+// encodeData encodes the payload as the escaped data parameter of a migration URI.
+func encodeData(payload []byte) string {
+	return url.QueryEscape(base64.StdEncoding.EncodeToString(payload))
+}
+
+func TestDecode(t *testing.T) {
+	t.Parallel()
+
+	data := base64.StdEncoding.EncodeToString(examplePayload)
+	want := []*OTP{{
+		Secret:    exampleSecret,
+		Name:      "Example:alice@google.com",
+		Issuer:    "Example",
+		Algorithm: AlgorithmSHA1,
+		Type:      TypeTOTP,
+		Digits:    6,
+	}}
+
+	tests := []struct {
+		wantErr error
+		name    string
+		uri     string
+		want    []*OTP
+	}{
+		{
+			name: "escaped data",
+			uri:  "otpauth-migration://offline?data=" + encodeData(examplePayload),
+			want: want,
+		},
+		{
+			name: "unescaped data",
+			uri:  "otpauth-migration://offline?data=" + data,
+			want: want,
+		},
+		{
+			name: "unpadded data",
+			uri:  "otpauth-migration://offline?data=" + base64.RawStdEncoding.EncodeToString(examplePayload),
+			want: want,
+		},
+		{
+			name:    "key URI",
+			uri:     "otpauth://totp/Example:alice@google.com?secret=JBSWY3DPEHPK3PXP",
+			wantErr: ErrInvalidURI,
+		},
+		{
+			name:    "missing data",
+			uri:     "otpauth-migration://offline",
+			wantErr: ErrInvalidURI,
+		},
+		{
+			name:    "invalid base64",
+			uri:     "otpauth-migration://offline?data=%21%21",
+			wantErr: ErrInvalidPayload,
+		},
+		{
+			name:    "truncated payload",
+			uri:     "otpauth-migration://offline?data=" + encodeData(examplePayload[:20]),
+			wantErr: ErrInvalidPayload,
+		},
+		{
+			name:    "parameters without secret",
+			uri:     "otpauth-migration://offline?data=" + encodeData([]byte{0x0A, 0x02, 0x30, 0x02}),
+			wantErr: ErrSecretRequired,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			otps, err := Decode(tt.uri)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, otps)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, otps)
+		})
+	}
+}
+
+func TestEncode(t *testing.T) {
+	t.Parallel()
+
+	otps := make([]*OTP, 0, 12)
+	for i := range 11 {
+		otps = append(otps, &OTP{Secret: []byte{byte(i + 1)}, Name: fmt.Sprintf("user%d", i), Issuer: "Example"})
+	}
+	otps = append(otps, &OTP{
+		Secret:    exampleSecret,
+		Name:      "counter",
+		Algorithm: AlgorithmSHA512,
+		Type:      TypeHOTP,
+		Digits:    8,
+		Counter:   42,
+	})
+
+	uris, err := Encode(otps, 0)
+	require.NoError(t, err)
+	require.Len(t, uris, 2)
+
+	// decoded collects the OTPs of every batch.
+	var decoded []*OTP
+	for _, uri := range uris {
+		batch, err := Decode(uri)
+		require.NoError(t, err)
+		assert.LessOrEqual(t, len(batch), DefaultBatchSize)
+		decoded = append(decoded, batch...)
+	}
+	require.Len(t, decoded, len(otps))
+	assert.Equal(t, &OTP{Secret: []byte{1}, Name: "user0", Issuer: "Example", Algorithm: AlgorithmSHA1, Type: TypeTOTP,
+		Digits: 6}, decoded[0])
+	assert.Equal(t, otps[11], decoded[11])
+
+	_, err = Encode([]*OTP{{Name: "no secret"}}, 1)
+	require.ErrorIs(t, err, ErrSecretRequired)
+
+	uris, err = Encode(nil, 1)
+	require.NoError(t, err)
+	require.Len(t, uris, 1)
+}
+
+func TestOTP_KeyURI(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		otp  *OTP
+		name string
+		want string
+	}{
+		{
+			name: "issuer prefixed name",
+			otp:  &OTP{Secret: exampleSecret, Name: "Example:alice@google.com", Issuer: "Example", Type: TypeTOTP},
+			want: "otpauth://totp/Example:alice@google.com?algorithm=SHA1&digits=6&issuer=Example&period=30" +
+				"&secret=JBSWY3DPEHPK3PXP",
+		},
+		{
+			name: "issuer added to name",
+			otp:  &OTP{Secret: exampleSecret, Name: "alice", Issuer: "ACME Co", Algorithm: AlgorithmSHA256, Digits: 8},
+			want: "otpauth://totp/ACME%20Co:alice?algorithm=SHA256&digits=8&issuer=ACME+Co&period=30" +
+				"&secret=JBSWY3DPEHPK3PXP",
+		},
+		{
+			name: "counter based",
+			otp:  &OTP{Secret: exampleSecret, Name: "alice", Type: TypeHOTP, Counter: 7},
+			want: "otpauth://hotp/alice?algorithm=SHA1&counter=7&digits=6&secret=JBSWY3DPEHPK3PXP",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, tt.otp.KeyURI())
+		})
+	}
+}
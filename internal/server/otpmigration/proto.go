@@ -0,0 +1,232 @@
+package otpmigration
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// Protobuf wire types used by the migration payload.
+const (
+	// wireVarint is a variable-length integer.
+	wireVarint = 0
+	// wire64 is a fixed 64-bit value.
+	wire64 = 1
+	// wireBytes is a length-delimited value.
+	wireBytes = 2
+	// wire32 is a fixed 32-bit value.
+	wire32 = 5
+)
+
+// Field numbers of the MigrationPayload message.
+const (
+	// payloadOTPParameters is the repeated OtpParameters field.
+	payloadOTPParameters = 1
+	// payloadVersion is the payload format version.
+	payloadVersion = 2
+	// payloadBatchSize is the number of URIs of the export.
+	payloadBatchSize = 3
+	// payloadBatchIndex is the index of the URI within the export.
+	payloadBatchIndex = 4
+	// payloadBatchID identifies the export all its URIs belong to.
+	payloadBatchID = 5
+)
+
+// Field numbers of the OtpParameters message.
+const (
+	// paramSecret is the secret key.
+	paramSecret = 1
+	// paramName is the account name.
+	paramName = 2
+	// paramIssuer is the issuer.
+	paramIssuer = 3
+	// paramAlgorithm is the Algorithm enum.
+	paramAlgorithm = 4
+	// paramDigits is the DigitCount enum: 1 for six digits, 2 for eight.
+	paramDigits = 5
+	// paramType is the OtpType enum.
+	paramType = 6
+	// paramCounter is the HOTP counter.
+	paramCounter = 7
+)
+
+// Values of the DigitCount enum.
+const (
+	// digitCountSix is six digits.
+	digitCountSix = 1
+	// digitCountEight is eight digits.
+	digitCountEight = 2
+)
+
+// payloadFormatVersion is the MigrationPayload version written by Google Authenticator.
+const payloadFormatVersion = 1
+
+// errTruncated indicates a protobuf message ending inside a field.
+var errTruncated = errors.New("truncated protobuf message")
+
+// marshalPayload encodes the OTPs as the MigrationPayload message of the batch with the index.
+func marshalPayload(otps []*OTP, batchSize, batchIndex int, batchID int32) []byte {
+	// b accumulates the message.
+	var b []byte
+	for _, o := range otps {
+		b = appendBytesField(b, payloadOTPParameters, marshalParameters(o))
+	}
+	b = appendVarintField(b, payloadVersion, payloadFormatVersion)
+	b = appendVarintField(b, payloadBatchSize, uint64(batchSize))
+	b = appendVarintField(b, payloadBatchIndex, uint64(batchIndex))
+	return appendVarintField(b, payloadBatchID, uint64(int64(batchID)))
+}
+
+// marshalParameters encodes the OTP as an OtpParameters message.
+func marshalParameters(o *OTP) []byte {
+	digits := uint64(digitCountSix)
+	if o.digits() == 8 {
+		digits = digitCountEight
+	}
+	kind := o.Type
+	if kind == TypeUnspecified {
+		kind = TypeTOTP
+	}
+	algorithm := o.Algorithm
+	if algorithm == AlgorithmUnspecified {
+		algorithm = AlgorithmSHA1
+	}
+
+	// b accumulates the message.
+	var b []byte
+	b = appendBytesField(b, paramSecret, o.Secret)
+	b = appendBytesField(b, paramName, []byte(o.Name))
+	b = appendBytesField(b, paramIssuer, []byte(o.Issuer))
+	b = appendVarintField(b, paramAlgorithm, uint64(algorithm))
+	b = appendVarintField(b, paramDigits, digits)
+	b = appendVarintField(b, paramType, uint64(kind))
+	if kind == TypeHOTP {
+		b = appendVarintField(b, paramCounter, uint64(o.Counter))
+	}
+	return b
+}
+
+// unmarshalPayload decodes the OTPs of a MigrationPayload message; the batch fields are skipped.
+func unmarshalPayload(b []byte) ([]*OTP, error) {
+	// otps collects the decoded OTP parameters.
+	var otps []*OTP
+	err := walkFields(b, func(num int, varint uint64, data []byte) error {
+		if num != payloadOTPParameters || data == nil {
+			return nil
+		}
+		o, err := unmarshalParameters(data)
+		if err != nil {
+			return err
+		}
+		otps = append(otps, o)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return otps, nil
+}
+
+// unmarshalParameters decodes an OtpParameters message.
+func unmarshalParameters(b []byte) (*OTP, error) {
+	o := &OTP{Digits: defaultDigits}
+	err := walkFields(b, func(num int, varint uint64, data []byte) error {
+		switch num {
+		case paramSecret:
+			o.Secret = append([]byte{}, data...)
+		case paramName:
+			o.Name = string(data)
+		case paramIssuer:
+			o.Issuer = string(data)
+		case paramAlgorithm:
+			o.Algorithm = Algorithm(varint)
+		case paramDigits:
+			if varint == digitCountEight {
+				o.Digits = 8
+			}
+		case paramType:
+			o.Type = Type(varint)
+		case paramCounter:
+			o.Counter = int64(varint)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(o.Secret) == 0 {
+		return nil, ErrSecretRequired
+	}
+	return o, nil
+}
+
+// walkFields calls fn for every field of the message with its number and either its varint value or, for
+// length-delimited fields, its non-nil data. Fixed-size fields are skipped.
+func walkFields(b []byte, fn func(num int, varint uint64, data []byte) error) error {
+	for len(b) > 0 {
+		key, n := binary.Uvarint(b)
+		if n <= 0 {
+			return errTruncated
+		}
+		b = b[n:]
+		num := int(key >> 3)
+
+		// varint and data hold the value of the field.
+		var varint uint64
+		var data []byte
+		switch key & 7 {
+		case wireVarint:
+			if varint, n = binary.Uvarint(b); n <= 0 {
+				return errTruncated
+			}
+			b = b[n:]
+		case wireBytes:
+			size, n := binary.Uvarint(b)
+			if n <= 0 || uint64(len(b)-n) < size {
+				return errTruncated
+			}
+			data = b[n : n+int(size) : n+int(size)]
+			if data == nil {
+				data = []byte{}
+			}
+			b = b[n+int(size):]
+		case wire64:
+			if len(b) < 8 {
+				return errTruncated
+			}
+			b = b[8:]
+			continue
+		case wire32:
+			if len(b) < 4 {
+				return errTruncated
+			}
+			b = b[4:]
+			continue
+		default:
+			return fmt.Errorf("unsupported protobuf wire type %d", key&7)
+		}
+		if err := fn(num, varint, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// appendVarintField appends a varint field, omitting zero values as proto3 does.
+func appendVarintField(b []byte, num int, v uint64) []byte {
+	if v == 0 {
+		return b
+	}
+	b = binary.AppendUvarint(b, uint64(num)<<3|wireVarint)
+	return binary.AppendUvarint(b, v)
+}
+
+// appendBytesField appends a length-delimited field, omitting empty values as proto3 does.
+func appendBytesField(b []byte, num int, data []byte) []byte {
+	if len(data) == 0 {
+		return b
+	}
+	b = binary.AppendUvarint(b, uint64(num)<<3|wireBytes)
+	b = binary.AppendUvarint(b, uint64(len(data)))
+	return append(b, data...)
+}
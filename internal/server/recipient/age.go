@@ -0,0 +1,48 @@
+package recipient
+
+import (
+	"bytes"
+	"fmt"
+
+	"filippo.io/age"
+)
+
+// ageRecipientPrefix starts Bech32-encoded age X25519 recipients.
+const ageRecipientPrefix = "age1"
+
+// ageX25519 encrypts files to an age X25519 recipient.
+type ageX25519 struct {
+	// recipient contains the X25519 public key of the recipient.
+	recipient *age.X25519Recipient
+}
+
+// parseAge parses a Bech32-encoded age X25519 recipient.
+func parseAge(s string) (*ageX25519, error) {
+	r, err := age.ParseX25519Recipient(s)
+	if err != nil {
+		return nil, ErrInvalidRecipient
+	}
+	return &ageX25519{recipient: r}, nil
+}
+
+// Extension returns the file name extension of age files.
+func (r *ageX25519) Extension() string {
+	return ".age"
+}
+
+// Encrypt encrypts the plaintext to the recipient as a binary age v1 file.
+func (r *ageX25519) Encrypt(plaintext []byte) ([]byte, error) {
+	// out holds the encrypted file.
+	var out bytes.Buffer
+	w, err := age.Encrypt(&out, r.recipient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt to age recipient: %w", err)
+	}
+	if _, err := w.Write(plaintext); err != nil {
+		return nil, fmt.Errorf("failed to encrypt to age recipient: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encrypt to age recipient: %w", err)
+	}
+	return out.Bytes(), nil
+}
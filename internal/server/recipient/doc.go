@@ -0,0 +1,6 @@
+// Package recipient encrypts vault exports to the public key of their owner for the AegisVaultKeeper server.
+//
+// Exports can be encrypted to an age X25519 recipient ("age1..."), producing a binary age v1 file opened by
+// age and rage, or to an OpenPGP public key, producing a binary OpenPGP message opened by GnuPG. Either way the
+// backup can be stored safely without a passphrase shared between the server and its owner.
+package recipient
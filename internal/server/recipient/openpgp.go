@@ -0,0 +1,73 @@
+package recipient
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
+)
+
+// armoredPublicKeyHeader starts ASCII-armored OpenPGP public keys.
+const armoredPublicKeyHeader = "-----BEGIN PGP PUBLIC KEY BLOCK-----"
+
+// openPGPKey encrypts files to an OpenPGP public key.
+type openPGPKey struct {
+	// entity contains the public key with its encryption subkeys.
+	entity *openpgp.Entity
+}
+
+// parseArmoredOpenPGP parses an ASCII-armored OpenPGP public key.
+func parseArmoredOpenPGP(s string) (*openPGPKey, error) {
+	keyring, err := openpgp.ReadArmoredKeyRing(strings.NewReader(s))
+	if err != nil {
+		return nil, ErrInvalidRecipient
+	}
+	return newOpenPGPKey(keyring)
+}
+
+// parseOpenPGP parses a binary OpenPGP public key.
+func parseOpenPGP(key []byte) (*openPGPKey, error) {
+	keyring, err := openpgp.ReadKeyRing(bytes.NewReader(key))
+	if err != nil {
+		return nil, ErrInvalidRecipient
+	}
+	return newOpenPGPKey(keyring)
+}
+
+// newOpenPGPKey takes the single key of the keyring, which must have a valid encryption key.
+func newOpenPGPKey(keyring openpgp.EntityList) (*openPGPKey, error) {
+	if len(keyring) != 1 {
+		return nil, ErrInvalidRecipient
+	}
+	if _, ok := keyring[0].EncryptionKey(time.Now()); !ok {
+		return nil, ErrInvalidRecipient
+	}
+	return &openPGPKey{entity: keyring[0]}, nil
+}
+
+// Extension returns the file name extension of OpenPGP messages.
+func (k *openPGPKey) Extension() string {
+	return ".gpg"
+}
+
+// Encrypt encrypts the plaintext to the key as a binary OpenPGP message with AES-256.
+func (k *openPGPKey) Encrypt(plaintext []byte) ([]byte, error) {
+	// out holds the encrypted message.
+	var out bytes.Buffer
+	hints := &openpgp.FileHints{IsBinary: true, ModTime: time.Now()}
+	config := &packet.Config{DefaultCipher: packet.CipherAES256, Time: time.Now}
+	w, err := openpgp.Encrypt(&out, []*openpgp.Entity{k.entity}, nil, hints, config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt to OpenPGP key: %w", err)
+	}
+	if _, err := w.Write(plaintext); err != nil {
+		return nil, fmt.Errorf("failed to encrypt to OpenPGP key: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encrypt to OpenPGP key: %w", err)
+	}
+	return out.Bytes(), nil
+}
@@ -0,0 +1,37 @@
+package recipient
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+)
+
+// ErrInvalidRecipient indicates a recipient that is neither an age X25519 recipient nor an OpenPGP public key
+// with an encryption key.
+var ErrInvalidRecipient = errors.New("recipient is not an age recipient or an OpenPGP public key")
+
+// Recipient encrypts files to a public key.
+type Recipient interface {
+	// Encrypt encrypts the plaintext to the public key of the recipient.
+	Encrypt(plaintext []byte) ([]byte, error)
+	// Extension returns the file name extension of encrypted files, such as ".age".
+	Extension() string
+}
+
+// Parse parses an age X25519 recipient ("age1...") or an OpenPGP public key, either ASCII-armored or as the
+// standard base64 encoding of the binary key, as printed by "gpg --export <key> | base64 -w0".
+func Parse(s string) (Recipient, error) {
+	s = strings.TrimSpace(s)
+	switch {
+	case strings.HasPrefix(strings.ToLower(s), ageRecipientPrefix):
+		return parseAge(s)
+	case strings.HasPrefix(s, armoredPublicKeyHeader):
+		return parseArmoredOpenPGP(s)
+	default:
+		key, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			return nil, ErrInvalidRecipient
+		}
+		return parseOpenPGP(key)
+	}
+}
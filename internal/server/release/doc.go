@@ -0,0 +1,6 @@
+// Package release provides the signed release manifests the AegisVaultKeeper server checks for updates.
+//
+// A release manifest announces the latest server release: its version, release date, download page and notes,
+// signed with the Ed25519 release key of the vendor. Servers verify manifests with the public key alone, so a
+// compromised or spoofed manifest host cannot announce releases that were never published.
+package release
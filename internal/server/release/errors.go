@@ -0,0 +1,11 @@
+package release
+
+import "errors"
+
+// Release manifest error definitions.
+var (
+	// ErrManifestMalformed indicates that the release manifest is not a well-formed manifest.
+	ErrManifestMalformed = errors.New("release manifest malformed")
+	// ErrSignatureInvalid indicates that the release manifest was not signed with the release key of the vendor.
+	ErrSignatureInvalid = errors.New("release manifest signature invalid")
+)
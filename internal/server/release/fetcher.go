@@ -0,0 +1,56 @@
+package release
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// maxManifestSize limits the size of release manifests read from the manifest URL.
+const maxManifestSize = 64 << 10
+
+// HTTPFetcher downloads release manifests from the manifest URL.
+type HTTPFetcher struct {
+	// client performs the HTTP requests.
+	client *http.Client
+	// url contains the address the manifest is published at.
+	url string
+}
+
+// NewHTTPFetcher creates a new HTTPFetcher downloading the manifest published at the URL.
+func NewHTTPFetcher(client *http.Client, url string) *HTTPFetcher {
+	return &HTTPFetcher{client: client, url: url}
+}
+
+// Fetch downloads the release manifest and fails on non-2xx responses and oversized manifests.
+func (f *HTTPFetcher) Fetch(ctx context.Context) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, f.url, http.NoBody)
+	if err != nil {
+		return "", fmt.Errorf("failed to build release manifest request: %w", err)
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return "", fmt.Errorf("release manifest request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return "", fmt.Errorf("release manifest host answered status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxManifestSize+1))
+	if err != nil {
+		return "", fmt.Errorf("failed to read release manifest: %w", err)
+	}
+	if len(body) > maxManifestSize {
+		return "", fmt.Errorf("release manifest exceeds %d bytes", maxManifestSize)
+	}
+	return string(body), nil
+}
@@ -0,0 +1,50 @@
+package release
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPFetcher_Fetch(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		body    string
+		want    string
+		status  int
+		wantErr bool
+	}{
+		{name: "published manifest", body: "AVKR1.payload.signature", want: "AVKR1.payload.signature", status: http.StatusOK},
+		{name: "missing manifest", status: http.StatusNotFound, wantErr: true},
+		{name: "oversized manifest", body: strings.Repeat("x", maxManifestSize+1), status: http.StatusOK, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodGet, r.Method)
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			t.Cleanup(server.Close)
+
+			got, err := NewHTTPFetcher(server.Client(), server.URL+"/latest").Fetch(context.Background())
+
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
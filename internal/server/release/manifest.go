@@ -0,0 +1,131 @@
+package release
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/mod/semver"
+)
+
+// manifestPrefix starts every release manifest and names the manifest format version.
+const manifestPrefix = "AVKR1"
+
+// Manifest represents the latest published server release.
+type Manifest struct {
+	// ReleasedAt indicates when the release was published.
+	ReleasedAt time.Time
+	// Version contains the semantic version of the release.
+	Version string
+	// URL contains the page the release is downloaded from.
+	URL string
+	// Notes contains a summary of the changes in the release.
+	Notes string
+}
+
+// rawManifest is the JSON representation of the release signed in a manifest.
+type rawManifest struct {
+	// Version contains the semantic version of the release.
+	Version string `json:"version"`
+	// URL contains the page the release is downloaded from.
+	URL string `json:"url"`
+	// Notes contains a summary of the changes in the release.
+	Notes string `json:"notes,omitempty"`
+	// ReleasedAt contains the Unix time the release was published.
+	ReleasedAt int64 `json:"released_at"`
+}
+
+// Parse verifies the release manifest with the public key of the vendor and returns the release.
+// Manifests have the form AVKR1.PAYLOAD.SIGNATURE, where PAYLOAD is the base64url JSON of the release and
+// SIGNATURE the base64url Ed25519 signature of AVKR1.PAYLOAD.
+func Parse(manifest string, publicKey ed25519.PublicKey) (*Manifest, error) {
+	parts := strings.Split(strings.TrimSpace(manifest), ".")
+	if len(parts) != 3 || parts[0] != manifestPrefix {
+		return nil, fmt.Errorf("expected %s.PAYLOAD.SIGNATURE: %w", manifestPrefix, ErrManifestMalformed)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature encoding: %w", ErrManifestMalformed)
+	}
+	if len(publicKey) != ed25519.PublicKeySize ||
+		!ed25519.Verify(publicKey, []byte(parts[0]+"."+parts[1]), signature) {
+		return nil, ErrSignatureInvalid
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid payload encoding: %w", ErrManifestMalformed)
+	}
+	// raw holds the decoded release.
+	var raw rawManifest
+	if err := json.Unmarshal(payload, &raw); err != nil {
+		return nil, fmt.Errorf("invalid payload: %w", errors.Join(ErrManifestMalformed, err))
+	}
+	if !Valid(raw.Version) {
+		return nil, fmt.Errorf("invalid release version %q: %w", raw.Version, ErrManifestMalformed)
+	}
+
+	return &Manifest{
+		ReleasedAt: time.Unix(raw.ReleasedAt, 0).UTC(),
+		Version:    raw.Version,
+		URL:        raw.URL,
+		Notes:      raw.Notes,
+	}, nil
+}
+
+// Sign issues a release manifest for the release, signed with the private key of the vendor.
+func Sign(m *Manifest, privateKey ed25519.PrivateKey) (string, error) {
+	if !Valid(m.Version) {
+		return "", fmt.Errorf("invalid release version %q", m.Version)
+	}
+	payload, err := json.Marshal(rawManifest{
+		Version:    m.Version,
+		URL:        m.URL,
+		Notes:      m.Notes,
+		ReleasedAt: m.ReleasedAt.Unix(),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode release manifest: %w", err)
+	}
+	signed := manifestPrefix + "." + base64.RawURLEncoding.EncodeToString(payload)
+	signature := ed25519.Sign(privateKey, []byte(signed))
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// ParsePublicKey decodes a base64 Ed25519 release public key.
+func ParsePublicKey(s string) (ed25519.PublicKey, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil {
+		return nil, fmt.Errorf("invalid base64 encoding: %w", err)
+	}
+	if len(key) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("expected %d bytes, got %d", ed25519.PublicKeySize, len(key))
+	}
+	return ed25519.PublicKey(key), nil
+}
+
+// Valid reports whether the version is a semantic version, with or without the leading v.
+func Valid(version string) bool {
+	return semver.IsValid(canonical(version))
+}
+
+// Newer reports whether the latest version is a later release than the current one. Versions that are not
+// semantic versions, such as those of development builds, are never reported as outdated.
+func Newer(latest, current string) bool {
+	if !Valid(latest) || !Valid(current) {
+		return false
+	}
+	return semver.Compare(canonical(latest), canonical(current)) > 0
+}
+
+// canonical prefixes the version with the v semver expects.
+func canonical(version string) string {
+	if strings.HasPrefix(version, "v") {
+		return version
+	}
+	return "v" + version
+}
@@ -0,0 +1,117 @@
+package release
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	_, otherKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	latest := &Manifest{
+		ReleasedAt: time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC),
+		Version:    "v1.5.0",
+		URL:        "https://example.com/releases/v1.5.0",
+		Notes:      "Telemetry and update checks",
+	}
+	manifest, err := Sign(latest, privateKey)
+	require.NoError(t, err)
+	forged, err := Sign(latest, otherKey)
+	require.NoError(t, err)
+	parts := strings.Split(manifest, ".")
+	tampered := parts[0] + "." + base64.RawURLEncoding.EncodeToString(
+		[]byte(`{"version":"v9.0.0","url":"https://evil.example.com","released_at":0}`),
+	) + "." + parts[2]
+	unversioned := parts[0] + "." + base64.RawURLEncoding.EncodeToString([]byte(`{"version":"latest"}`))
+	unversioned += "." + base64.RawURLEncoding.EncodeToString(ed25519.Sign(privateKey, []byte(unversioned)))
+
+	tests := []struct {
+		want     *Manifest
+		wantErr  error
+		name     string
+		manifest string
+	}{
+		{name: "valid manifest", manifest: manifest, want: latest},
+		{name: "surrounding whitespace", manifest: "  " + manifest + "\n", want: latest},
+		{name: "other vendor key", manifest: forged, wantErr: ErrSignatureInvalid},
+		{name: "altered release", manifest: tampered, wantErr: ErrSignatureInvalid},
+		{name: "invalid version", manifest: unversioned, wantErr: ErrManifestMalformed},
+		{
+			name:     "wrong prefix",
+			manifest: "AVKR2" + strings.TrimPrefix(manifest, "AVKR1"),
+			wantErr:  ErrManifestMalformed,
+		},
+		{name: "missing signature", manifest: parts[0] + "." + parts[1], wantErr: ErrManifestMalformed},
+		{name: "empty", manifest: "", wantErr: ErrManifestMalformed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := Parse(tt.manifest, publicKey)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParsePublicKey(t *testing.T) {
+	t.Parallel()
+
+	publicKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	got, err := ParsePublicKey(base64.StdEncoding.EncodeToString(publicKey))
+	require.NoError(t, err)
+	assert.Equal(t, publicKey, got)
+
+	_, err = ParsePublicKey(base64.StdEncoding.EncodeToString(publicKey[:16]))
+	require.Error(t, err)
+	_, err = ParsePublicKey("not base64!")
+	require.Error(t, err)
+}
+
+func TestNewer(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		latest  string
+		current string
+		want    bool
+	}{
+		{name: "newer patch", latest: "v1.4.1", current: "v1.4.0", want: true},
+		{name: "newer minor without prefix", latest: "1.5.0", current: "v1.4.9", want: true},
+		{name: "same version", latest: "v1.4.0", current: "1.4.0"},
+		{name: "older release", latest: "v1.3.0", current: "v1.4.0"},
+		{name: "release after its candidate", latest: "v1.5.0", current: "v1.5.0-rc.1", want: true},
+		{name: "candidate of the running release", latest: "v1.5.0-rc.1", current: "v1.5.0"},
+		{name: "development build", latest: "v1.5.0", current: "N/A"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, Newer(tt.latest, tt.current))
+		})
+	}
+}
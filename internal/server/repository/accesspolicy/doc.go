@@ -0,0 +1,5 @@
+// Package accesspolicy provides time-based access policy persistence for the AegisVaultKeeper server.
+//
+// This package implements storage of global and per-user vault availability windows
+// in PostgreSQL.
+package accesspolicy
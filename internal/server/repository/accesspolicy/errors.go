@@ -0,0 +1,6 @@
+package accesspolicy
+
+import "errors"
+
+// ErrPolicyNotFound indicates that the requested access policy was not found in the repository.
+var ErrPolicyNotFound = errors.New("access policy not found")
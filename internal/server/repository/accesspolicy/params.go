@@ -0,0 +1,29 @@
+package accesspolicy
+
+import (
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/accesspolicy"
+	"github.com/google/uuid"
+)
+
+// SaveParams contains the parameters for saving an access policy to the repository.
+type SaveParams struct {
+	// Entity contains the access policy to be persisted.
+	Entity *accesspolicy.Policy
+}
+
+// LoadParams contains the parameters for loading access policies from the repository.
+// When neither Global nor UserID is set, all policies are loaded.
+type LoadParams struct {
+	// UserID selects the policies of the specified user.
+	UserID uuid.UUID
+	// Global selects the policies applying to all users.
+	Global bool
+}
+
+// DeleteParams contains the parameters for deleting an access policy from the repository.
+type DeleteParams struct {
+	// ID identifies the policy to delete.
+	ID uuid.UUID
+	// UserID restricts the deletion to the policies the user defined; uuid.Nil deletes any policy.
+	UserID uuid.UUID
+}
@@ -0,0 +1,127 @@
+package accesspolicy
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/accesspolicy"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/db"
+	"github.com/google/uuid"
+)
+
+// Repository provides access policy persistence operations.
+type Repository struct {
+	// db is the database client used for policy operations.
+	db db.DBClient
+}
+
+// NewRepository creates a new Repository with the provided database client.
+func NewRepository(dbClient db.DBClient) *Repository {
+	return &Repository{db: dbClient}
+}
+
+// Save stores a new access policy.
+func (r *Repository) Save(ctx context.Context, params SaveParams) error {
+	e := params.Entity
+
+	var userID uuid.NullUUID
+	if !e.IsGlobal() {
+		userID = uuid.NullUUID{UUID: e.UserID, Valid: true}
+	}
+
+	query := `
+		INSERT INTO aegis_vault_keeper.access_policies
+			(id, user_id, days, start_minute, end_minute, timezone, managed, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+	if _, err := r.db.Exec(ctx, query,
+		e.ID, userID, int16(e.Days), int16(e.Start), int16(e.End), e.Location.String(), e.Managed, e.CreatedAt,
+	); err != nil {
+		return fmt.Errorf("failed to save access policy: %w", err)
+	}
+	return nil
+}
+
+// Load retrieves access policies matching the provided parameters ordered by creation time.
+func (r *Repository) Load(ctx context.Context, params LoadParams) ([]*accesspolicy.Policy, error) {
+	var (
+		conditions []string
+		args       []interface{}
+	)
+	if params.Global {
+		conditions = append(conditions, "user_id IS NULL")
+	}
+	if params.UserID != uuid.Nil {
+		args = append(args, params.UserID)
+		conditions = append(conditions, fmt.Sprintf("user_id = $%d", len(args)))
+	}
+
+	query := `
+		SELECT id, user_id, days, start_minute, end_minute, timezone, managed, created_at
+		FROM aegis_vault_keeper.access_policies
+	`
+	if len(conditions) != 0 {
+		query += " WHERE " + strings.Join(conditions, " OR ")
+	}
+	query += " ORDER BY created_at, id"
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load access policies: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var policies []*accesspolicy.Policy
+	for rows.Next() {
+		var (
+			p                accesspolicy.Policy
+			userID           uuid.NullUUID
+			days, start, end int16
+			timezone         string
+		)
+		if err := rows.Scan(
+			&p.ID, &userID, &days, &start, &end, &timezone, &p.Managed, &p.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan access policy: %w", err)
+		}
+		loc, err := time.LoadLocation(timezone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timezone of access policy %s: %w", p.ID, err)
+		}
+		p.UserID = userID.UUID
+		p.Days = accesspolicy.DaySet(days)
+		p.Start, p.End = int(start), int(end)
+		p.Location = loc
+		policies = append(policies, &p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate access policies: %w", err)
+	}
+	return policies, nil
+}
+
+// Delete removes the access policy with the specified ID. When a user is specified, only a policy
+// that user defined is removed; policies managed by administrators are left in place.
+func (r *Repository) Delete(ctx context.Context, params DeleteParams) error {
+	query := "DELETE FROM aegis_vault_keeper.access_policies WHERE id = $1"
+	args := []interface{}{params.ID}
+	if params.UserID != uuid.Nil {
+		query += " AND user_id = $2 AND NOT managed"
+		args = append(args, params.UserID)
+	}
+
+	res, err := r.db.Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to delete access policy: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check deleted access policies: %w", err)
+	}
+	if n == 0 {
+		return ErrPolicyNotFound
+	}
+	return nil
+}
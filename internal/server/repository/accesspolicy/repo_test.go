@@ -0,0 +1,273 @@
+package accesspolicy
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/accesspolicy"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/db"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockResult implements sql.Result for testing.
+type mockResult struct {
+	rowsAffected int64
+}
+
+func (m mockResult) LastInsertId() (int64, error) { return 0, nil }
+func (m mockResult) RowsAffected() (int64, error) { return m.rowsAffected, nil }
+
+// mockDBClient implements db.DBClient for testing.
+type mockDBClient struct {
+	execFunc  func(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	queryFunc func(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+func (m *mockDBClient) Exec(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	if m.execFunc != nil {
+		return m.execFunc(ctx, query, args...)
+	}
+	return mockResult{rowsAffected: 1}, nil
+}
+
+func (m *mockDBClient) Query(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	if m.queryFunc != nil {
+		return m.queryFunc(ctx, query, args...)
+	}
+	return nil, errors.New("mock not configured")
+}
+
+func (m *mockDBClient) QueryRow(context.Context, string, ...interface{}) *sql.Row {
+	return nil
+}
+
+func (m *mockDBClient) BeginTx(context.Context, *sql.TxOptions) (*sql.Tx, error) {
+	return nil, errors.New("mock not configured")
+}
+
+func (m *mockDBClient) CommitTx(*sql.Tx) error {
+	return nil
+}
+
+func (m *mockDBClient) RollbackTx(*sql.Tx) error {
+	return nil
+}
+
+// Ensure mockDBClient implements db.DBClient.
+var _ db.DBClient = (*mockDBClient)(nil)
+
+func TestRepository_Save(t *testing.T) {
+	t.Parallel()
+
+	policyID, userID := uuid.New(), uuid.New()
+	createdAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	tests := []struct {
+		execErr  error
+		policy   *accesspolicy.Policy
+		name     string
+		wantArgs []interface{}
+	}{
+		{
+			name: "global managed policy",
+			policy: &accesspolicy.Policy{
+				ID:        policyID,
+				Days:      0b0111110,
+				Start:     9 * 60,
+				End:       18 * 60,
+				Location:  time.UTC,
+				Managed:   true,
+				CreatedAt: createdAt,
+			},
+			wantArgs: []interface{}{
+				policyID, uuid.NullUUID{}, int16(0b0111110), int16(540), int16(1080), "UTC", true, createdAt,
+			},
+		},
+		{
+			name: "user policy",
+			policy: &accesspolicy.Policy{
+				ID:        policyID,
+				UserID:    userID,
+				Days:      1,
+				Start:     22 * 60,
+				End:       6 * 60,
+				Location:  berlin,
+				CreatedAt: createdAt,
+			},
+			wantArgs: []interface{}{
+				policyID, uuid.NullUUID{UUID: userID, Valid: true}, int16(1), int16(1320), int16(360),
+				"Europe/Berlin", false, createdAt,
+			},
+		},
+		{
+			name:    "exec error",
+			policy:  &accesspolicy.Policy{ID: policyID, Days: 1, End: 60, Location: time.UTC, Managed: true},
+			execErr: errors.New("connection refused"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var gotArgs []interface{}
+			client := &mockDBClient{
+				execFunc: func(_ context.Context, query string, args ...interface{}) (sql.Result, error) {
+					assert.Contains(t, query, "INSERT INTO aegis_vault_keeper.access_policies")
+					gotArgs = args
+					return mockResult{rowsAffected: 1}, tt.execErr
+				},
+			}
+
+			err := NewRepository(client).Save(context.Background(), SaveParams{Entity: tt.policy})
+
+			if tt.execErr != nil {
+				assert.ErrorIs(t, err, tt.execErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantArgs, gotArgs)
+		})
+	}
+}
+
+func TestRepository_LoadQuery(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+
+	tests := []struct {
+		name      string
+		wantWhere string
+		wantArgs  []interface{}
+		params    LoadParams
+	}{
+		{
+			name:   "all policies",
+			params: LoadParams{},
+		},
+		{
+			name:      "global policies",
+			params:    LoadParams{Global: true},
+			wantWhere: "WHERE user_id IS NULL ORDER BY",
+		},
+		{
+			name:      "user policies",
+			params:    LoadParams{UserID: userID},
+			wantWhere: "WHERE user_id = $1 ORDER BY",
+			wantArgs:  []interface{}{userID},
+		},
+		{
+			name:      "global and user policies",
+			params:    LoadParams{Global: true, UserID: userID},
+			wantWhere: "WHERE user_id IS NULL OR user_id = $1 ORDER BY",
+			wantArgs:  []interface{}{userID},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			queryErr := errors.New("query error")
+			var gotQuery string
+			var gotArgs []interface{}
+			client := &mockDBClient{
+				queryFunc: func(_ context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+					gotQuery, gotArgs = query, args
+					return nil, queryErr
+				},
+			}
+
+			_, err := NewRepository(client).Load(context.Background(), tt.params)
+
+			require.ErrorIs(t, err, queryErr)
+			if tt.wantWhere == "" {
+				assert.NotContains(t, gotQuery, "WHERE")
+			} else {
+				assert.Contains(t, gotQuery, tt.wantWhere)
+			}
+			assert.Equal(t, tt.wantArgs, gotArgs)
+		})
+	}
+}
+
+func TestRepository_Delete(t *testing.T) {
+	t.Parallel()
+
+	policyID, userID := uuid.New(), uuid.New()
+
+	tests := []struct {
+		execErr      error
+		wantErr      error
+		name         string
+		wantQuery    string
+		wantArgs     []interface{}
+		params       DeleteParams
+		rowsAffected int64
+	}{
+		{
+			name:         "deleted by administrator",
+			params:       DeleteParams{ID: policyID},
+			wantArgs:     []interface{}{policyID},
+			rowsAffected: 1,
+		},
+		{
+			name:         "deleted by owner",
+			params:       DeleteParams{ID: policyID, UserID: userID},
+			wantQuery:    "AND user_id = $2 AND NOT managed",
+			wantArgs:     []interface{}{policyID, userID},
+			rowsAffected: 1,
+		},
+		{
+			name:         "not found",
+			params:       DeleteParams{ID: policyID},
+			wantArgs:     []interface{}{policyID},
+			rowsAffected: 0,
+			wantErr:      ErrPolicyNotFound,
+		},
+		{
+			name:     "exec error",
+			params:   DeleteParams{ID: policyID},
+			wantArgs: []interface{}{policyID},
+			execErr:  errors.New("connection refused"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := &mockDBClient{
+				execFunc: func(_ context.Context, query string, args ...interface{}) (sql.Result, error) {
+					assert.Contains(t, query, "DELETE FROM aegis_vault_keeper.access_policies")
+					if tt.wantQuery != "" {
+						assert.Contains(t, query, tt.wantQuery)
+					} else {
+						assert.NotContains(t, query, "managed")
+					}
+					assert.Equal(t, tt.wantArgs, args)
+					return mockResult{rowsAffected: tt.rowsAffected}, tt.execErr
+				},
+			}
+
+			err := NewRepository(client).Delete(context.Background(), tt.params)
+
+			switch {
+			case tt.execErr != nil:
+				assert.ErrorIs(t, err, tt.execErr)
+			case tt.wantErr != nil:
+				assert.ErrorIs(t, err, tt.wantErr)
+			default:
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,5 @@
+// Package accessrule provides network access rule persistence for the AegisVaultKeeper server.
+//
+// This package implements storage of global and per-user IP and country access rules
+// in PostgreSQL.
+package accessrule
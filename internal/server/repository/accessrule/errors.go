@@ -0,0 +1,6 @@
+package accessrule
+
+import "errors"
+
+// ErrRuleNotFound indicates that the requested access rule was not found in the repository.
+var ErrRuleNotFound = errors.New("access rule not found")
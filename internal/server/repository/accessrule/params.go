@@ -0,0 +1,27 @@
+package accessrule
+
+import (
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/accessrule"
+	"github.com/google/uuid"
+)
+
+// SaveParams contains the parameters for saving an access rule to the repository.
+type SaveParams struct {
+	// Entity contains the access rule to be persisted.
+	Entity *accessrule.Rule
+}
+
+// LoadParams contains the parameters for loading access rules from the repository.
+// When neither Global nor UserID is set, all rules are loaded.
+type LoadParams struct {
+	// UserID selects the rules of the specified user.
+	UserID uuid.UUID
+	// Global selects the rules applying to all users.
+	Global bool
+}
+
+// DeleteParams contains the parameters for deleting an access rule from the repository.
+type DeleteParams struct {
+	// ID identifies the rule to delete.
+	ID uuid.UUID
+}
@@ -0,0 +1,136 @@
+package accessrule
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/netip"
+	"strings"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/accessrule"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/db"
+	"github.com/google/uuid"
+)
+
+// Repository provides access rule persistence operations.
+type Repository struct {
+	// db is the database client used for rule operations.
+	db db.DBClient
+}
+
+// NewRepository creates a new Repository with the provided database client.
+func NewRepository(dbClient db.DBClient) *Repository {
+	return &Repository{db: dbClient}
+}
+
+// Save stores a new access rule.
+func (r *Repository) Save(ctx context.Context, params SaveParams) error {
+	e := params.Entity
+
+	var network, country sql.NullString
+	if e.Network.IsValid() {
+		network = sql.NullString{String: e.Network.String(), Valid: true}
+	}
+	if e.Country != "" {
+		country = sql.NullString{String: e.Country, Valid: true}
+	}
+	var userID uuid.NullUUID
+	if !e.IsGlobal() {
+		userID = uuid.NullUUID{UUID: e.UserID, Valid: true}
+	}
+
+	query := `
+		INSERT INTO aegis_vault_keeper.access_rules (id, user_id, action, network, country, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	if _, err := r.db.Exec(ctx, query,
+		e.ID, userID, string(e.Action), network, country, e.CreatedAt,
+	); err != nil {
+		return fmt.Errorf("failed to save access rule: %w", err)
+	}
+	return nil
+}
+
+// Load retrieves access rules matching the provided parameters ordered by creation time.
+func (r *Repository) Load(ctx context.Context, params LoadParams) ([]*accessrule.Rule, error) {
+	var (
+		conditions []string
+		args       []interface{}
+	)
+	if params.Global {
+		conditions = append(conditions, "user_id IS NULL")
+	}
+	if params.UserID != uuid.Nil {
+		args = append(args, params.UserID)
+		conditions = append(conditions, fmt.Sprintf("user_id = $%d", len(args)))
+	}
+
+	query := `
+		SELECT id, user_id, action, network::text, country, created_at
+		FROM aegis_vault_keeper.access_rules
+	`
+	if len(conditions) != 0 {
+		query += " WHERE " + strings.Join(conditions, " OR ")
+	}
+	query += " ORDER BY created_at, id"
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load access rules: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var rules []*accessrule.Rule
+	for rows.Next() {
+		rule, err := scanRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate access rules: %w", err)
+	}
+	return rules, nil
+}
+
+// Delete removes the access rule with the specified ID.
+func (r *Repository) Delete(ctx context.Context, params DeleteParams) error {
+	res, err := r.db.Exec(ctx, "DELETE FROM aegis_vault_keeper.access_rules WHERE id = $1", params.ID)
+	if err != nil {
+		return fmt.Errorf("failed to delete access rule: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check deleted access rules: %w", err)
+	}
+	if n == 0 {
+		return ErrRuleNotFound
+	}
+	return nil
+}
+
+// scanRule converts the current row into an access rule entity.
+func scanRule(rows *sql.Rows) (*accessrule.Rule, error) {
+	var (
+		rule             accessrule.Rule
+		userID           uuid.NullUUID
+		action           string
+		network, country sql.NullString
+	)
+	if err := rows.Scan(&rule.ID, &userID, &action, &network, &country, &rule.CreatedAt); err != nil {
+		return nil, fmt.Errorf("failed to scan access rule: %w", err)
+	}
+
+	rule.UserID = userID.UUID
+	rule.Action = accessrule.Action(action)
+	rule.Country = country.String
+	if network.Valid {
+		p, err := netip.ParsePrefix(network.String)
+		if err != nil {
+			return nil, fmt.Errorf("invalid network of access rule %s: %w", rule.ID, err)
+		}
+		rule.Network = p
+	}
+	return &rule, nil
+}
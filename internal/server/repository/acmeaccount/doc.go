@@ -0,0 +1,5 @@
+// Package acmeaccount provides encrypted ACME account data persistence for the AegisVaultKeeper server.
+//
+// This package stores account keys, DNS provider credentials and hook URLs encrypted with the keys of their owners,
+// signs every row for integrity verification and confines queries to the row-level security scope of the owner.
+package acmeaccount
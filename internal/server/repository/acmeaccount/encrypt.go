@@ -0,0 +1,122 @@
+package acmeaccount
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/acmeaccount"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/fieldcrypt"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/keyprv"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/securebytes"
+)
+
+// itemType identifies ACME account items in the ownership binding of encrypted fields.
+const itemType = "acme"
+
+// encryptionMw creates a middleware that encrypts account fields before saving.
+// Every field, including the directory and hook URLs, is encrypted using AES-GCM.
+func encryptionMw(keyProvider keyprv.UserKeyProvider) saveMw {
+	return func(next saveFunc) saveFunc {
+		return func(ctx context.Context, p SaveParams) error {
+			k, err := keyProvider.UserKeyProvide(ctx, p.Entity.UserID)
+			if err != nil {
+				return fmt.Errorf("failed to provide user key: %w", err)
+			}
+			defer securebytes.Wipe(k)
+
+			if p.Entity, err = sealAccount(k, p.Entity); err != nil {
+				return err
+			}
+			return next(ctx, p)
+		}
+	}
+}
+
+// sealAccount returns a copy of the account with all sensitive fields encrypted with the key.
+func sealAccount(k []byte, e *acmeaccount.Account) (*acmeaccount.Account, error) {
+	copyEntity := *e
+	// b holds the ownership binding authenticated with every encrypted field.
+	b := fieldcrypt.Binding{ItemType: itemType, UserID: copyEntity.UserID, ItemID: copyEntity.ID}
+
+	var err error
+	if copyEntity.DirectoryURL, err = b.Seal(k, "directory_url", copyEntity.DirectoryURL); err != nil {
+		return nil, fmt.Errorf("failed to encrypt directory URL: %w", err)
+	}
+	if copyEntity.Email, err = b.Seal(k, "email", copyEntity.Email); err != nil {
+		return nil, fmt.Errorf("failed to encrypt e-mail: %w", err)
+	}
+	if copyEntity.AccountKey, err = b.Seal(k, "account_key", copyEntity.AccountKey); err != nil {
+		return nil, fmt.Errorf("failed to encrypt account key: %w", err)
+	}
+	if copyEntity.DNSProvider, err = b.Seal(k, "dns_provider", copyEntity.DNSProvider); err != nil {
+		return nil, fmt.Errorf("failed to encrypt DNS provider: %w", err)
+	}
+	if copyEntity.DNSCredentials, err = b.Seal(k, "dns_credentials", copyEntity.DNSCredentials); err != nil {
+		return nil, fmt.Errorf("failed to encrypt DNS credentials: %w", err)
+	}
+	if copyEntity.HookURL, err = b.Seal(k, "hook_url", copyEntity.HookURL); err != nil {
+		return nil, fmt.Errorf("failed to encrypt hook URL: %w", err)
+	}
+	if copyEntity.Description, err = b.Seal(k, "description", copyEntity.Description); err != nil {
+		return nil, fmt.Errorf("failed to encrypt description: %w", err)
+	}
+	return &copyEntity, nil
+}
+
+// decryptionMw creates middleware that decrypts account entities after loading from storage.
+func decryptionMw(keyProvider keyprv.UserKeyProvider) loadMw {
+	return func(next loadFunc) loadFunc {
+		return func(ctx context.Context, p LoadParams) ([]*acmeaccount.Account, error) {
+			entities, err := next(ctx, p)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load entities: %w", err)
+			}
+			if len(entities) == 0 {
+				return []*acmeaccount.Account{}, nil
+			}
+
+			k, err := keyProvider.UserKeyProvide(ctx, p.UserID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to provide user key: %w", err)
+			}
+			defer securebytes.Wipe(k)
+
+			for _, entity := range entities {
+				if err := openAccount(k, p, entity); err != nil {
+					return nil, err
+				}
+			}
+			return entities, nil
+		}
+	}
+}
+
+// openAccount decrypts all sensitive fields of the loaded account in place.
+func openAccount(k []byte, p LoadParams, e *acmeaccount.Account) error {
+	// b holds the ownership binding the loaded fields must have been sealed with.
+	b := fieldcrypt.Binding{ItemType: itemType, UserID: p.UserID, ItemID: e.ID}
+
+	var err error
+	if e.DirectoryURL, err = b.Open(k, "directory_url", e.DirectoryURL); err != nil {
+		return fmt.Errorf("failed to decrypt directory URL: %w", err)
+	}
+	if e.Email, err = b.Open(k, "email", e.Email); err != nil {
+		return fmt.Errorf("failed to decrypt e-mail: %w", err)
+	}
+	if e.AccountKey, err = b.Open(k, "account_key", e.AccountKey); err != nil {
+		return fmt.Errorf("failed to decrypt account key: %w", err)
+	}
+	if e.DNSProvider, err = b.Open(k, "dns_provider", e.DNSProvider); err != nil {
+		return fmt.Errorf("failed to decrypt DNS provider: %w", err)
+	}
+	if e.DNSCredentials, err = b.Open(k, "dns_credentials", e.DNSCredentials); err != nil {
+		return fmt.Errorf("failed to decrypt DNS credentials: %w", err)
+	}
+	if e.HookURL, err = b.Open(k, "hook_url", e.HookURL); err != nil {
+		return fmt.Errorf("failed to decrypt hook URL: %w", err)
+	}
+	if e.Description, err = b.Open(k, "description", e.Description); err != nil {
+		return fmt.Errorf("failed to decrypt description: %w", err)
+	}
+	return nil
+}
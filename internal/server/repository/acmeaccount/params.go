@@ -0,0 +1,20 @@
+package acmeaccount
+
+import (
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/acmeaccount"
+	"github.com/google/uuid"
+)
+
+// SaveParams contains the parameters for saving an ACME account entity to the repository.
+type SaveParams struct {
+	// Entity contains the account data to be persisted.
+	Entity *acmeaccount.Account
+}
+
+// LoadParams contains the parameters for loading ACME account entities from the repository.
+type LoadParams struct {
+	// ID contains the specific account identifier for single record lookup (optional).
+	ID uuid.UUID
+	// UserID contains the user identifier for filtering accounts by owner (required).
+	UserID uuid.UUID
+}
@@ -0,0 +1,135 @@
+package acmeaccount
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/acmeaccount"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/db"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/rowsign"
+	"github.com/google/uuid"
+)
+
+// rawSave creates a database save function that persists account data directly to PostgreSQL.
+// Uses INSERT ON CONFLICT DO UPDATE for upsert behavior.
+func rawSave(db db.DBClient, signer *rowsign.Signer) saveFunc {
+	return func(ctx context.Context, p SaveParams) error {
+		e := p.Entity
+
+		signature, err := signer.Sign(SignedTable, rowValues(e)...)
+		if err != nil {
+			return fmt.Errorf("failed to sign ACME account row: %w", err)
+		}
+
+		query := `
+			INSERT INTO aegis_vault_keeper.acme_accounts (
+				id, user_id, directory_url, email, account_key, dns_provider, dns_credentials, hook_url,
+				description, updated_at, signature
+			) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11)
+			ON CONFLICT (id) DO UPDATE SET
+			  directory_url   = EXCLUDED.directory_url,
+			  email           = EXCLUDED.email,
+			  account_key     = EXCLUDED.account_key,
+			  dns_provider    = EXCLUDED.dns_provider,
+			  dns_credentials = EXCLUDED.dns_credentials,
+			  hook_url        = EXCLUDED.hook_url,
+			  description     = EXCLUDED.description,
+			  updated_at      = EXCLUDED.updated_at,
+			  signature       = EXCLUDED.signature
+		`
+
+		if _, err := db.Exec(
+			ctx,
+			query,
+			e.ID,
+			e.UserID,
+			e.DirectoryURL,
+			e.Email,
+			e.AccountKey,
+			e.DNSProvider,
+			e.DNSCredentials,
+			e.HookURL,
+			e.Description,
+			e.UpdatedAt,
+			signature,
+		); err != nil {
+			return fmt.Errorf("failed to save ACME account: %w", err)
+		}
+		return nil
+	}
+}
+
+// rawLoad creates a database load function that retrieves account data from PostgreSQL.
+// Supports filtering by user ID and specific account ID.
+func rawLoad(db db.DBClient, signer *rowsign.Signer) loadFunc {
+	return func(ctx context.Context, p LoadParams) ([]*acmeaccount.Account, error) {
+		var (
+			queryBuilder strings.Builder
+			args         []interface{}
+			conditions   []string
+			argIdx       = 1
+		)
+
+		queryBuilder.WriteString(`SELECT id, user_id, directory_url, email, account_key, dns_provider, dns_credentials,
+			hook_url, description, updated_at, signature FROM aegis_vault_keeper.acme_accounts`)
+
+		if p.ID != uuid.Nil {
+			conditions = append(conditions, fmt.Sprintf("id = $%d", argIdx))
+			args = append(args, p.ID)
+			argIdx++
+		}
+		if p.UserID != uuid.Nil {
+			conditions = append(conditions, fmt.Sprintf("user_id = $%d", argIdx))
+			args = append(args, p.UserID)
+			// argIdx++ // Last usage, no need to increment
+		}
+		if len(conditions) == 0 {
+			return nil, errors.New("at least one of ID or UserID must be provided")
+		}
+
+		queryBuilder.WriteString(" WHERE ")
+		queryBuilder.WriteString(strings.Join(conditions, " AND "))
+		queryBuilder.WriteString(" ORDER BY updated_at")
+
+		rows, err := db.Query(ctx, queryBuilder.String(), args...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to execute query: %w", err)
+		}
+		defer func() { _ = rows.Close() }()
+
+		// accounts collects all account entities retrieved from the database.
+		var accounts []*acmeaccount.Account
+		for rows.Next() {
+			// a holds a single account entity during database row scanning.
+			var a acmeaccount.Account
+			// signature holds the stored row integrity signature.
+			var signature []byte
+			if err := rows.Scan(
+				&a.ID,
+				&a.UserID,
+				&a.DirectoryURL,
+				&a.Email,
+				&a.AccountKey,
+				&a.DNSProvider,
+				&a.DNSCredentials,
+				&a.HookURL,
+				&a.Description,
+				&a.UpdatedAt,
+				&signature,
+			); err != nil {
+				return nil, fmt.Errorf("failed to scan row: %w", err)
+			}
+			if err := signer.Verify(SignedTable, signature, rowValues(&a)...); err != nil {
+				return nil, fmt.Errorf("ACME account row %s failed integrity verification: %w", a.ID, err)
+			}
+			accounts = append(accounts, &a)
+		}
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("rows iteration error: %w", err)
+		}
+
+		return accounts, nil
+	}
+}
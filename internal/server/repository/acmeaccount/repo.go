@@ -0,0 +1,72 @@
+package acmeaccount
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/acmeaccount"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/db"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/keyprv"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/middleware"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/rowsign"
+)
+
+// saveFunc defines the signature for account save operations.
+type saveFunc func(ctx context.Context, params SaveParams) error
+
+// saveMw defines middleware for save operations.
+type saveMw = middleware.Middleware[saveFunc]
+
+// loadFunc defines the signature for account load operations.
+type loadFunc func(ctx context.Context, params LoadParams) ([]*acmeaccount.Account, error)
+
+// loadMw defines middleware for load operations.
+type loadMw = middleware.Middleware[loadFunc]
+
+// Repository provides encrypted ACME account data persistence with middleware support.
+type Repository struct {
+	// save is the function chain for saving account data with encryption middleware.
+	save saveFunc
+	// load is the function chain for loading account data with decryption middleware.
+	load loadFunc
+}
+
+// NewRepository creates a new Repository with encryption middleware and database backend.
+func NewRepository(
+	dbClient db.DBClient,
+	keyProvider keyprv.UserKeyProvider,
+	signer *rowsign.Signer,
+	retry middleware.RetryPolicy,
+) *Repository {
+	return &Repository{
+		save: middleware.Chain(
+			rawSave(dbClient, signer),
+			middleware.RetryExecMw[saveFunc](retry),
+			userScopeSaveMw(dbClient),
+			encryptionMw(keyProvider),
+		),
+		load: middleware.Chain(
+			rawLoad(dbClient, signer),
+			middleware.RetryQueryMw[loadFunc](retry),
+			userScopeLoadMw(dbClient),
+			decryptionMw(keyProvider),
+		),
+	}
+}
+
+// Save persists an ACME account with automatic encryption of sensitive fields.
+func (r *Repository) Save(ctx context.Context, params SaveParams) error {
+	if err := r.save(ctx, params); err != nil {
+		return fmt.Errorf("failed to save ACME account: %w", err)
+	}
+	return nil
+}
+
+// Load retrieves ACME accounts with automatic decryption of sensitive fields.
+func (r *Repository) Load(ctx context.Context, params LoadParams) ([]*acmeaccount.Account, error) {
+	accounts, err := r.load(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load ACME accounts: %w", err)
+	}
+	return accounts, nil
+}
@@ -0,0 +1,162 @@
+package acmeaccount
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/acmeaccount"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/middleware"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/rowsign"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockDBClient implements db.DBClient for testing.
+type mockDBClient struct {
+	execFunc  func(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	queryFunc func(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+func (m *mockDBClient) Exec(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	if m.execFunc != nil {
+		return m.execFunc(ctx, query, args...)
+	}
+	return mockResult{}, nil
+}
+
+func (m *mockDBClient) Query(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	if m.queryFunc != nil {
+		return m.queryFunc(ctx, query, args...)
+	}
+	return nil, errors.New("mock not configured")
+}
+
+func (m *mockDBClient) QueryRow(context.Context, string, ...interface{}) *sql.Row {
+	return nil
+}
+
+func (m *mockDBClient) BeginTx(context.Context, *sql.TxOptions) (*sql.Tx, error) {
+	return nil, errors.New("mock not configured")
+}
+
+func (m *mockDBClient) CommitTx(*sql.Tx) error {
+	return nil
+}
+
+func (m *mockDBClient) RollbackTx(*sql.Tx) error {
+	return nil
+}
+
+// mockResult implements sql.Result for testing.
+type mockResult struct{}
+
+func (m mockResult) LastInsertId() (int64, error) { return 1, nil }
+func (m mockResult) RowsAffected() (int64, error) { return 1, nil }
+
+// mockKeyProvider implements keyprv.UserKeyProvider for testing.
+type mockKeyProvider struct {
+	err error
+}
+
+func (m *mockKeyProvider) UserKeyProvide(context.Context, uuid.UUID) ([]byte, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return []byte("12345678901234567890123456789012"), nil
+}
+
+func TestRepository_Save(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+
+	tests := []struct {
+		execErr       error
+		keyErr        error
+		name          string
+		expectedError string
+	}{
+		{name: "successful save"},
+		{name: "database error", execErr: errors.New("database error"), expectedError: "failed to save ACME account"},
+		{name: "key provider error", keyErr: errors.New("key error"), expectedError: "failed to provide user key"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			entity := &acmeaccount.Account{
+				ID:           uuid.New(),
+				UserID:       userID,
+				DirectoryURL: []byte("https://acme.example.com/directory"),
+				AccountKey:   []byte("key"),
+				UpdatedAt:    time.Now(),
+			}
+			// saved holds the account key column of the executed statement.
+			var saved []byte
+			dbClient := &mockDBClient{
+				execFunc: func(_ context.Context, _ string, args ...interface{}) (sql.Result, error) {
+					saved, _ = args[4].([]byte)
+					return mockResult{}, tt.execErr
+				},
+			}
+			signer := rowsign.NewSigner([]byte("integrity-key"))
+			repo := NewRepository(dbClient, &mockKeyProvider{err: tt.keyErr}, signer, middleware.RetryPolicy{})
+
+			err := repo.Save(context.Background(), SaveParams{Entity: entity})
+
+			if tt.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotEqual(t, []byte("key"), saved)
+			assert.Equal(t, []byte("key"), entity.AccountKey)
+		})
+	}
+}
+
+func TestRepository_Load(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		expectedError string
+		params        LoadParams
+	}{
+		{
+			name:          "database error",
+			params:        LoadParams{UserID: uuid.New()},
+			expectedError: "failed to load ACME accounts",
+		},
+		{
+			name:          "no filter",
+			expectedError: "at least one of ID or UserID must be provided",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			dbClient := &mockDBClient{
+				queryFunc: func(context.Context, string, ...interface{}) (*sql.Rows, error) {
+					return nil, errors.New("database error")
+				},
+			}
+			signer := rowsign.NewSigner([]byte("integrity-key"))
+			repo := NewRepository(dbClient, &mockKeyProvider{}, signer, middleware.RetryPolicy{})
+
+			accounts, err := repo.Load(context.Background(), tt.params)
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expectedError)
+			assert.Nil(t, accounts)
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package acmeaccount
+
+import (
+	"context"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/acmeaccount"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/db"
+)
+
+// userScopeSaveMw creates middleware that runs account saves within the owner's row-level security scope.
+func userScopeSaveMw(dbClient db.DBClient) saveMw {
+	return func(next saveFunc) saveFunc {
+		return func(ctx context.Context, p SaveParams) error {
+			return db.InUserScope(ctx, dbClient, p.Entity.UserID, func(ctx context.Context) error {
+				return next(ctx, p)
+			})
+		}
+	}
+}
+
+// userScopeLoadMw creates middleware that runs account loads within the owner's row-level security scope.
+func userScopeLoadMw(dbClient db.DBClient) loadMw {
+	return func(next loadFunc) loadFunc {
+		return func(ctx context.Context, p LoadParams) ([]*acmeaccount.Account, error) {
+			// entities holds the accounts loaded inside the user scope.
+			var entities []*acmeaccount.Account
+			err := db.InUserScope(ctx, dbClient, p.UserID, func(ctx context.Context) error {
+				var err error
+				entities, err = next(ctx, p)
+				return err
+			})
+			return entities, err
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package acmeaccount
+
+import (
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/acmeaccount"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/rowsign"
+)
+
+// SignedTable describes the acme_accounts table columns covered by row integrity signatures.
+var SignedTable = rowsign.Table{
+	Name: "acme_accounts",
+	Columns: []rowsign.Column{
+		{Name: "id", Kind: rowsign.KindUUID},
+		{Name: "user_id", Kind: rowsign.KindUUID},
+		{Name: "directory_url", Kind: rowsign.KindBytes},
+		{Name: "email", Kind: rowsign.KindBytes},
+		{Name: "account_key", Kind: rowsign.KindBytes},
+		{Name: "dns_provider", Kind: rowsign.KindBytes},
+		{Name: "dns_credentials", Kind: rowsign.KindBytes},
+		{Name: "hook_url", Kind: rowsign.KindBytes},
+		{Name: "description", Kind: rowsign.KindBytes},
+		{Name: "updated_at", Kind: rowsign.KindTime},
+	},
+}
+
+// rowValues returns the stored account column values in SignedTable column order.
+func rowValues(e *acmeaccount.Account) []any {
+	return []any{
+		e.ID,
+		e.UserID,
+		e.DirectoryURL,
+		e.Email,
+		e.AccountKey,
+		e.DNSProvider,
+		e.DNSCredentials,
+		e.HookURL,
+		e.Description,
+		e.UpdatedAt,
+	}
+}
@@ -0,0 +1,5 @@
+// Package adminapproval provides dual-control approval persistence for the AegisVaultKeeper server.
+//
+// This package implements storage of the destructive admin requests awaiting the approval of a second
+// operator, and of their decisions and executions, in PostgreSQL.
+package adminapproval
@@ -0,0 +1,13 @@
+package adminapproval
+
+import "errors"
+
+// Admin approval repository error definitions.
+var (
+	// ErrApprovalNotFound indicates that the requested approval was not found in the repository.
+	ErrApprovalNotFound = errors.New("admin approval not found")
+
+	// ErrApprovalConflict indicates that the stored approval is no longer in the state it was changed from,
+	// because another request decided or executed it first.
+	ErrApprovalConflict = errors.New("admin approval was changed concurrently")
+)
@@ -0,0 +1,24 @@
+package adminapproval
+
+import (
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/adminapproval"
+	"github.com/google/uuid"
+)
+
+// SaveParams contains the parameters for saving an approval to the repository.
+type SaveParams struct {
+	// Entity contains the approval to be persisted.
+	Entity *adminapproval.Approval
+	// From contains the stored state the approval is changed from; empty stores a new approval.
+	// The change is rejected with ErrApprovalConflict unless the stored approval is still in this state.
+	From adminapproval.Status
+}
+
+// LoadParams contains the parameters for loading approvals from the repository.
+// Zero fields do not filter.
+type LoadParams struct {
+	// Status selects approvals in the stored state.
+	Status adminapproval.Status
+	// ID selects a single approval.
+	ID uuid.UUID
+}
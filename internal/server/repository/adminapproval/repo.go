@@ -0,0 +1,125 @@
+package adminapproval
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/adminapproval"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/db"
+	"github.com/google/uuid"
+)
+
+// Repository provides admin approval persistence operations.
+type Repository struct {
+	// db is the database client used for admin approval operations.
+	db db.DBClient
+}
+
+// NewRepository creates a new Repository with the provided database client.
+func NewRepository(dbClient db.DBClient) *Repository {
+	return &Repository{db: dbClient}
+}
+
+// Save stores a new approval or records the decision or execution of an existing one. An existing approval
+// is only changed while it is still in the state it was loaded in, so an approval is decided and executed
+// once even when operators race.
+func (r *Repository) Save(ctx context.Context, params SaveParams) error {
+	e := params.Entity
+
+	if params.From == "" {
+		query := `
+			INSERT INTO aegis_vault_keeper.admin_approvals
+				(id, action, method, path, body_digest, initiator, status, created_at, expires_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		`
+		if _, err := r.db.Exec(ctx, query,
+			e.ID, e.Action, e.Method, e.Path, e.BodyDigest, e.Initiator, string(e.Status), e.CreatedAt, e.ExpiresAt,
+		); err != nil {
+			return fmt.Errorf("failed to save admin approval: %w", err)
+		}
+		return nil
+	}
+
+	var (
+		approver  sql.NullString
+		decidedAt sql.NullTime
+	)
+	if e.Approver != "" {
+		approver = sql.NullString{String: e.Approver, Valid: true}
+	}
+	if !e.DecidedAt.IsZero() {
+		decidedAt = sql.NullTime{Time: e.DecidedAt, Valid: true}
+	}
+
+	query := `
+		UPDATE aegis_vault_keeper.admin_approvals
+		SET status = $2, approver = $3, decided_at = $4
+		WHERE id = $1 AND status = $5
+	`
+	res, err := r.db.Exec(ctx, query, e.ID, string(e.Status), approver, decidedAt, string(params.From))
+	if err != nil {
+		return fmt.Errorf("failed to update admin approval: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check updated admin approvals: %w", err)
+	}
+	if n == 0 {
+		return ErrApprovalConflict
+	}
+	return nil
+}
+
+// Load retrieves approvals matching the provided parameters, most recent first.
+// ErrApprovalNotFound is returned when an approval requested by ID does not exist.
+func (r *Repository) Load(ctx context.Context, params LoadParams) ([]*adminapproval.Approval, error) {
+	query := `
+		SELECT id, action, method, path, body_digest, initiator, approver, status, created_at, decided_at, expires_at
+		FROM aegis_vault_keeper.admin_approvals
+	`
+	// args holds the positional arguments of the filter.
+	var args []interface{}
+	switch {
+	case params.ID != uuid.Nil:
+		args = append(args, params.ID)
+		query += " WHERE id = $1"
+	case params.Status != "":
+		args = append(args, string(params.Status))
+		query += " WHERE status = $1"
+	}
+	query += " ORDER BY created_at DESC, id"
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load admin approvals: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var approvals []*adminapproval.Approval
+	for rows.Next() {
+		var (
+			a         adminapproval.Approval
+			status    string
+			approver  sql.NullString
+			decidedAt sql.NullTime
+		)
+		if err := rows.Scan(
+			&a.ID, &a.Action, &a.Method, &a.Path, &a.BodyDigest, &a.Initiator, &approver, &status,
+			&a.CreatedAt, &decidedAt, &a.ExpiresAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan admin approval: %w", err)
+		}
+		a.Status = adminapproval.Status(status)
+		a.Approver = approver.String
+		a.DecidedAt = decidedAt.Time
+		approvals = append(approvals, &a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate admin approvals: %w", err)
+	}
+	if params.ID != uuid.Nil && len(approvals) == 0 {
+		return nil, ErrApprovalNotFound
+	}
+	return approvals, nil
+}
@@ -0,0 +1,216 @@
+package adminapproval
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/adminapproval"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/db"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockResult implements sql.Result for testing.
+type mockResult struct {
+	rowsAffected int64
+}
+
+func (m mockResult) LastInsertId() (int64, error) { return 0, nil }
+func (m mockResult) RowsAffected() (int64, error) { return m.rowsAffected, nil }
+
+// mockDBClient implements db.DBClient for testing.
+type mockDBClient struct {
+	execFunc  func(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	queryFunc func(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+func (m *mockDBClient) Exec(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	if m.execFunc != nil {
+		return m.execFunc(ctx, query, args...)
+	}
+	return mockResult{rowsAffected: 1}, nil
+}
+
+func (m *mockDBClient) Query(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	if m.queryFunc != nil {
+		return m.queryFunc(ctx, query, args...)
+	}
+	return nil, errors.New("mock not configured")
+}
+
+func (m *mockDBClient) QueryRow(context.Context, string, ...interface{}) *sql.Row {
+	return nil
+}
+
+func (m *mockDBClient) BeginTx(context.Context, *sql.TxOptions) (*sql.Tx, error) {
+	return nil, errors.New("mock not configured")
+}
+
+func (m *mockDBClient) CommitTx(*sql.Tx) error {
+	return nil
+}
+
+func (m *mockDBClient) RollbackTx(*sql.Tx) error {
+	return nil
+}
+
+// Ensure mockDBClient implements db.DBClient.
+var _ db.DBClient = (*mockDBClient)(nil)
+
+func TestRepository_Save(t *testing.T) {
+	t.Parallel()
+
+	id := uuid.New()
+	digest := sha256.Sum256(nil)
+	createdAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	decidedAt := createdAt.Add(10 * time.Minute)
+	expiresAt := createdAt.Add(time.Hour)
+	pending := &adminapproval.Approval{
+		ID: id, Action: "recording.delete", Method: "DELETE", Path: "/api/admin/recordings/1",
+		BodyDigest: digest[:], Initiator: "alice", Status: adminapproval.StatusPending,
+		CreatedAt: createdAt, ExpiresAt: expiresAt,
+	}
+	approved := &adminapproval.Approval{
+		ID: id, Status: adminapproval.StatusApproved, Approver: "bob", DecidedAt: decidedAt,
+	}
+
+	tests := []struct {
+		execErr   error
+		wantErr   error
+		entity    *adminapproval.Approval
+		wantArgs  []interface{}
+		name      string
+		from      adminapproval.Status
+		wantQuery string
+		affected  int64
+	}{
+		{
+			name:      "new approval",
+			entity:    pending,
+			wantQuery: "INSERT INTO aegis_vault_keeper.admin_approvals",
+			wantArgs: []interface{}{
+				id, "recording.delete", "DELETE", "/api/admin/recordings/1", digest[:], "alice", "pending",
+				createdAt, expiresAt,
+			},
+			affected: 1,
+		},
+		{
+			name:      "decision",
+			entity:    approved,
+			from:      adminapproval.StatusPending,
+			wantQuery: "WHERE id = $1 AND status = $5",
+			wantArgs: []interface{}{
+				id, "approved", sql.NullString{String: "bob", Valid: true},
+				sql.NullTime{Time: decidedAt, Valid: true}, "pending",
+			},
+			affected: 1,
+		},
+		{
+			name:      "decided concurrently",
+			entity:    approved,
+			from:      adminapproval.StatusPending,
+			wantQuery: "UPDATE aegis_vault_keeper.admin_approvals",
+			wantArgs: []interface{}{
+				id, "approved", sql.NullString{String: "bob", Valid: true},
+				sql.NullTime{Time: decidedAt, Valid: true}, "pending",
+			},
+			wantErr: ErrApprovalConflict,
+		},
+		{
+			name:      "exec error",
+			entity:    pending,
+			wantQuery: "INSERT INTO aegis_vault_keeper.admin_approvals",
+			execErr:   errors.New("connection refused"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := &mockDBClient{
+				execFunc: func(_ context.Context, query string, args ...interface{}) (sql.Result, error) {
+					assert.Contains(t, query, tt.wantQuery)
+					if tt.execErr != nil {
+						return nil, tt.execErr
+					}
+					assert.Equal(t, tt.wantArgs, args)
+					return mockResult{rowsAffected: tt.affected}, nil
+				},
+			}
+
+			err := NewRepository(client).Save(context.Background(), SaveParams{Entity: tt.entity, From: tt.from})
+
+			switch {
+			case tt.execErr != nil:
+				assert.ErrorIs(t, err, tt.execErr)
+			case tt.wantErr != nil:
+				assert.ErrorIs(t, err, tt.wantErr)
+			default:
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRepository_Load(t *testing.T) {
+	t.Parallel()
+
+	id := uuid.New()
+
+	tests := []struct {
+		wantArgs  []interface{}
+		name      string
+		wantQuery string
+		noQuery   string
+		params    LoadParams
+	}{
+		{
+			name:      "all approvals",
+			noQuery:   "WHERE",
+			wantQuery: "ORDER BY created_at DESC, id",
+		},
+		{
+			name:      "single approval",
+			params:    LoadParams{ID: id},
+			wantQuery: "WHERE id = $1",
+			wantArgs:  []interface{}{id},
+		},
+		{
+			name:      "pending approvals",
+			params:    LoadParams{Status: adminapproval.StatusPending},
+			wantQuery: "WHERE status = $1",
+			wantArgs:  []interface{}{"pending"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			queryErr := errors.New("query error")
+			var gotQuery string
+			var gotArgs []interface{}
+			client := &mockDBClient{
+				queryFunc: func(_ context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+					gotQuery, gotArgs = query, args
+					return nil, queryErr
+				},
+			}
+
+			_, err := NewRepository(client).Load(context.Background(), tt.params)
+
+			require.ErrorIs(t, err, queryErr)
+			assert.Contains(t, gotQuery, tt.wantQuery)
+			if tt.noQuery != "" {
+				assert.NotContains(t, gotQuery, tt.noQuery)
+			}
+			assert.Equal(t, tt.wantArgs, gotArgs)
+		})
+	}
+}
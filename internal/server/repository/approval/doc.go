@@ -0,0 +1,5 @@
+// Package approval provides access request persistence for the AegisVaultKeeper server.
+//
+// This package implements storage of the requests users file to reveal items that require
+// approval, and of the decisions of approvers, in PostgreSQL.
+package approval
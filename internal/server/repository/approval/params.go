@@ -0,0 +1,29 @@
+package approval
+
+import (
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/approval"
+	"github.com/google/uuid"
+)
+
+// SaveParams contains the parameters for saving an access request to the repository.
+type SaveParams struct {
+	// Entity contains the access request to be persisted; an existing request is updated with its decision.
+	Entity *approval.Request
+}
+
+// LoadParams contains the parameters for loading access requests from the repository.
+// Zero fields do not filter.
+type LoadParams struct {
+	// ActiveAt selects requests whose expiry time is after the moment.
+	ActiveAt time.Time
+	// Status selects requests in the stored state.
+	Status approval.Status
+	// ID selects a single request.
+	ID uuid.UUID
+	// UserID selects the requests of the specified requester.
+	UserID uuid.UUID
+	// ItemID selects the requests for the specified item.
+	ItemID uuid.UUID
+}
@@ -0,0 +1,123 @@
+package approval
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/approval"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/db"
+	"github.com/google/uuid"
+)
+
+// Repository provides access request persistence operations.
+type Repository struct {
+	// db is the database client used for access request operations.
+	db db.DBClient
+}
+
+// NewRepository creates a new Repository with the provided database client.
+func NewRepository(dbClient db.DBClient) *Repository {
+	return &Repository{db: dbClient}
+}
+
+// Save stores a new access request or records the decision of an existing one.
+func (r *Repository) Save(ctx context.Context, params SaveParams) error {
+	e := params.Entity
+
+	var (
+		approverID uuid.NullUUID
+		decidedAt  sql.NullTime
+	)
+	if e.ApproverID != uuid.Nil {
+		approverID = uuid.NullUUID{UUID: e.ApproverID, Valid: true}
+	}
+	if !e.DecidedAt.IsZero() {
+		decidedAt = sql.NullTime{Time: e.DecidedAt, Valid: true}
+	}
+
+	query := `
+		INSERT INTO aegis_vault_keeper.access_requests
+			(id, user_id, item_id, reason, status, approver_id, created_at, decided_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (id) DO UPDATE SET
+			status = EXCLUDED.status,
+			approver_id = EXCLUDED.approver_id,
+			decided_at = EXCLUDED.decided_at,
+			expires_at = EXCLUDED.expires_at
+	`
+	if _, err := r.db.Exec(ctx, query,
+		e.ID, e.UserID, e.ItemID, e.Reason, string(e.Status), approverID, e.CreatedAt, decidedAt, e.ExpiresAt,
+	); err != nil {
+		return fmt.Errorf("failed to save access request: %w", err)
+	}
+	return nil
+}
+
+// Load retrieves access requests matching the provided parameters, most recent first.
+func (r *Repository) Load(ctx context.Context, params LoadParams) ([]*approval.Request, error) {
+	var (
+		conditions []string
+		args       []interface{}
+	)
+	// filter adds a condition comparing a column with the next positional argument.
+	filter := func(condition string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	if params.ID != uuid.Nil {
+		filter("id = $%d", params.ID)
+	}
+	if params.UserID != uuid.Nil {
+		filter("user_id = $%d", params.UserID)
+	}
+	if params.ItemID != uuid.Nil {
+		filter("item_id = $%d", params.ItemID)
+	}
+	if params.Status != "" {
+		filter("status = $%d", string(params.Status))
+	}
+	if !params.ActiveAt.IsZero() {
+		filter("expires_at > $%d", params.ActiveAt)
+	}
+
+	query := `
+		SELECT id, user_id, item_id, reason, status, approver_id, created_at, decided_at, expires_at
+		FROM aegis_vault_keeper.access_requests
+	`
+	if len(conditions) != 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY created_at DESC, id"
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load access requests: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var requests []*approval.Request
+	for rows.Next() {
+		var (
+			req        approval.Request
+			status     string
+			approverID uuid.NullUUID
+			decidedAt  sql.NullTime
+		)
+		if err := rows.Scan(
+			&req.ID, &req.UserID, &req.ItemID, &req.Reason, &status, &approverID,
+			&req.CreatedAt, &decidedAt, &req.ExpiresAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan access request: %w", err)
+		}
+		req.Status = approval.Status(status)
+		req.ApproverID = approverID.UUID
+		req.DecidedAt = decidedAt.Time
+		requests = append(requests, &req)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate access requests: %w", err)
+	}
+	return requests, nil
+}
@@ -0,0 +1,194 @@
+package approval
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/approval"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/db"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockResult implements sql.Result for testing.
+type mockResult struct {
+	rowsAffected int64
+}
+
+func (m mockResult) LastInsertId() (int64, error) { return 0, nil }
+func (m mockResult) RowsAffected() (int64, error) { return m.rowsAffected, nil }
+
+// mockDBClient implements db.DBClient for testing.
+type mockDBClient struct {
+	execFunc  func(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	queryFunc func(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+func (m *mockDBClient) Exec(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	if m.execFunc != nil {
+		return m.execFunc(ctx, query, args...)
+	}
+	return mockResult{rowsAffected: 1}, nil
+}
+
+func (m *mockDBClient) Query(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	if m.queryFunc != nil {
+		return m.queryFunc(ctx, query, args...)
+	}
+	return nil, errors.New("mock not configured")
+}
+
+func (m *mockDBClient) QueryRow(context.Context, string, ...interface{}) *sql.Row {
+	return nil
+}
+
+func (m *mockDBClient) BeginTx(context.Context, *sql.TxOptions) (*sql.Tx, error) {
+	return nil, errors.New("mock not configured")
+}
+
+func (m *mockDBClient) CommitTx(*sql.Tx) error {
+	return nil
+}
+
+func (m *mockDBClient) RollbackTx(*sql.Tx) error {
+	return nil
+}
+
+// Ensure mockDBClient implements db.DBClient.
+var _ db.DBClient = (*mockDBClient)(nil)
+
+func TestRepository_Save(t *testing.T) {
+	t.Parallel()
+
+	id, itemID, userID, approverID := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	createdAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	decidedAt := createdAt.Add(10 * time.Minute)
+	expiresAt := createdAt.Add(time.Hour)
+
+	tests := []struct {
+		execErr  error
+		entity   *approval.Request
+		name     string
+		wantArgs []interface{}
+	}{
+		{
+			name: "pending request",
+			entity: &approval.Request{
+				ID: id, UserID: userID, ItemID: itemID, Reason: "audit", Status: approval.StatusPending,
+				CreatedAt: createdAt, ExpiresAt: expiresAt,
+			},
+			wantArgs: []interface{}{
+				id, userID, itemID, "audit", "pending", uuid.NullUUID{}, createdAt, sql.NullTime{}, expiresAt,
+			},
+		},
+		{
+			name: "decided request",
+			entity: &approval.Request{
+				ID: id, UserID: userID, ItemID: itemID, Status: approval.StatusApproved, ApproverID: approverID,
+				CreatedAt: createdAt, DecidedAt: decidedAt, ExpiresAt: expiresAt,
+			},
+			wantArgs: []interface{}{
+				id, userID, itemID, "", "approved", uuid.NullUUID{UUID: approverID, Valid: true}, createdAt,
+				sql.NullTime{Time: decidedAt, Valid: true}, expiresAt,
+			},
+		},
+		{
+			name:    "exec error",
+			entity:  &approval.Request{ID: id, UserID: userID, ItemID: itemID, Status: approval.StatusPending},
+			execErr: errors.New("connection refused"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var gotArgs []interface{}
+			client := &mockDBClient{
+				execFunc: func(_ context.Context, query string, args ...interface{}) (sql.Result, error) {
+					assert.Contains(t, query, "INSERT INTO aegis_vault_keeper.access_requests")
+					assert.Contains(t, query, "ON CONFLICT (id) DO UPDATE")
+					gotArgs = args
+					return mockResult{rowsAffected: 1}, tt.execErr
+				},
+			}
+
+			err := NewRepository(client).Save(context.Background(), SaveParams{Entity: tt.entity})
+
+			if tt.execErr != nil {
+				assert.ErrorIs(t, err, tt.execErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantArgs, gotArgs)
+		})
+	}
+}
+
+func TestRepository_Load(t *testing.T) {
+	t.Parallel()
+
+	id, itemID, userID := uuid.New(), uuid.New(), uuid.New()
+	at := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		params    LoadParams
+		wantQuery string
+		noQuery   string
+		wantArgs  []interface{}
+	}{
+		{
+			name:      "all requests",
+			noQuery:   "WHERE",
+			wantQuery: "ORDER BY created_at DESC, id",
+		},
+		{
+			name:      "single request",
+			params:    LoadParams{ID: id},
+			wantQuery: "WHERE id = $1",
+			wantArgs:  []interface{}{id},
+		},
+		{
+			name:      "active grants of an item",
+			params:    LoadParams{UserID: userID, ItemID: itemID, Status: approval.StatusApproved, ActiveAt: at},
+			wantQuery: "WHERE user_id = $1 AND item_id = $2 AND status = $3 AND expires_at > $4",
+			wantArgs:  []interface{}{userID, itemID, "approved", at},
+		},
+		{
+			name:      "pending requests",
+			params:    LoadParams{Status: approval.StatusPending, ActiveAt: at},
+			wantQuery: "WHERE status = $1 AND expires_at > $2",
+			wantArgs:  []interface{}{"pending", at},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			queryErr := errors.New("query error")
+			var gotQuery string
+			var gotArgs []interface{}
+			client := &mockDBClient{
+				queryFunc: func(_ context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+					gotQuery, gotArgs = query, args
+					return nil, queryErr
+				},
+			}
+
+			_, err := NewRepository(client).Load(context.Background(), tt.params)
+
+			require.ErrorIs(t, err, queryErr)
+			assert.Contains(t, gotQuery, tt.wantQuery)
+			if tt.noQuery != "" {
+				assert.NotContains(t, gotQuery, tt.noQuery)
+			}
+			assert.Equal(t, tt.wantArgs, gotArgs)
+		})
+	}
+}
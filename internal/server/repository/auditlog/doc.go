@@ -0,0 +1,5 @@
+// Package auditlog provides audit trail persistence for the AegisVaultKeeper server.
+//
+// This package implements append-only storage of audit events in PostgreSQL and reading them back page by page
+// for compliance exports.
+package auditlog
@@ -0,0 +1,16 @@
+package auditlog
+
+import "time"
+
+// LoadParams contains the parameters for loading a page of audit entries from the repository.
+type LoadParams struct {
+	// From is the inclusive start of the period the entries occurred in.
+	From time.Time
+	// To is the exclusive end of the period the entries occurred in.
+	To time.Time
+	// After restricts the page to entries stored after the entry with this sequence number; zero starts at the
+	// first entry.
+	After int64
+	// Limit bounds the number of entries loaded.
+	Limit int
+}
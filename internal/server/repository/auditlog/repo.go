@@ -0,0 +1,113 @@
+package auditlog
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/audit"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/db"
+	"github.com/google/uuid"
+)
+
+// appendColumns is the number of columns inserted for every entry.
+const appendColumns = 5
+
+// Repository provides audit trail persistence operations.
+type Repository struct {
+	// db is the database client used for audit trail operations.
+	db db.DBClient
+}
+
+// NewRepository creates a new Repository with the provided database client.
+func NewRepository(dbClient db.DBClient) *Repository {
+	return &Repository{db: dbClient}
+}
+
+// Append stores the entries in the order given with a single statement, so a batch is stored completely or not
+// at all. Sequence numbers are assigned by the database. Timestamps are stored as wall clock time of the server
+// location without a time zone, like the other timestamps of the schema.
+func (r *Repository) Append(ctx context.Context, entries []audit.Entry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	// values and args hold the row placeholders of the statement with their arguments.
+	values := make([]string, 0, len(entries))
+	args := make([]any, 0, appendColumns*len(entries))
+	for _, e := range entries {
+		// details holds the JSON-encoded event attributes; events without attributes store an empty object.
+		details := []byte("{}")
+		if e.Details != nil {
+			encoded, err := json.Marshal(e.Details)
+			if err != nil {
+				return fmt.Errorf("failed to encode audit event details: %w", err)
+			}
+			details = encoded
+		}
+		n := len(args)
+		values = append(values, fmt.Sprintf("($%d, $%d, $%d, $%d, $%d)", n+1, n+2, n+3, n+4, n+5))
+		args = append(args,
+			e.Type,
+			uuid.NullUUID{UUID: e.UserID, Valid: e.UserID != uuid.Nil},
+			sql.NullString{String: e.ClientIP, Valid: e.ClientIP != ""},
+			details,
+			e.OccurredAt.In(time.Local),
+		)
+	}
+
+	query := `
+		INSERT INTO aegis_vault_keeper.audit_events (event_type, user_id, client_ip, details, occurred_at)
+		VALUES ` + strings.Join(values, ", ")
+	if _, err := r.db.Exec(ctx, query, args...); err != nil {
+		return fmt.Errorf("failed to append audit events: %w", err)
+	}
+	return nil
+}
+
+// Load retrieves a page of the entries that occurred in the period ordered by sequence number.
+func (r *Repository) Load(ctx context.Context, params LoadParams) ([]*audit.Entry, error) {
+	query := `
+		SELECT seq, event_type, user_id, host(client_ip), details, occurred_at
+		FROM aegis_vault_keeper.audit_events
+		WHERE occurred_at >= $1 AND occurred_at < $2 AND seq > $3
+		ORDER BY seq
+		LIMIT $4
+	`
+	rows, err := r.db.Query(ctx, query,
+		params.From.In(time.Local), params.To.In(time.Local), params.After, params.Limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load audit events: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var entries []*audit.Entry
+	for rows.Next() {
+		var e audit.Entry
+		var (
+			// userID holds the nullable identifier of the affected user.
+			userID uuid.NullUUID
+			// clientIP holds the nullable client address.
+			clientIP sql.NullString
+			// details holds the JSON-encoded event attributes.
+			details []byte
+		)
+		if err := rows.Scan(&e.Seq, &e.Type, &userID, &clientIP, &details, &e.OccurredAt); err != nil {
+			return nil, fmt.Errorf("failed to scan audit event: %w", err)
+		}
+		if err := json.Unmarshal(details, &e.Details); err != nil {
+			return nil, fmt.Errorf("failed to decode audit event details: %w", err)
+		}
+		e.UserID = userID.UUID
+		e.ClientIP = clientIP.String
+		entries = append(entries, &e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate audit events: %w", err)
+	}
+	return entries, nil
+}
@@ -0,0 +1,178 @@
+package auditlog
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/audit"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/db"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockResult implements sql.Result for testing.
+type mockResult struct {
+	rowsAffected int64
+}
+
+func (m mockResult) LastInsertId() (int64, error) { return 0, nil }
+func (m mockResult) RowsAffected() (int64, error) { return m.rowsAffected, nil }
+
+// mockDBClient implements db.DBClient for testing.
+type mockDBClient struct {
+	execFunc  func(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	queryFunc func(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+func (m *mockDBClient) Exec(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	if m.execFunc != nil {
+		return m.execFunc(ctx, query, args...)
+	}
+	return mockResult{rowsAffected: 1}, nil
+}
+
+func (m *mockDBClient) Query(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	if m.queryFunc != nil {
+		return m.queryFunc(ctx, query, args...)
+	}
+	return nil, errors.New("mock not configured")
+}
+
+func (m *mockDBClient) QueryRow(context.Context, string, ...interface{}) *sql.Row {
+	return nil
+}
+
+func (m *mockDBClient) BeginTx(context.Context, *sql.TxOptions) (*sql.Tx, error) {
+	return nil, errors.New("mock not configured")
+}
+
+func (m *mockDBClient) CommitTx(*sql.Tx) error {
+	return nil
+}
+
+func (m *mockDBClient) RollbackTx(*sql.Tx) error {
+	return nil
+}
+
+// Ensure mockDBClient implements db.DBClient.
+var _ db.DBClient = (*mockDBClient)(nil)
+
+func TestRepository_Append(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	userID := uuid.New()
+
+	tests := []struct {
+		execErr    error
+		name       string
+		wantValues string
+		entries    []audit.Entry
+		wantArgs   []interface{}
+		wantExec   bool
+	}{
+		{
+			name: "no entries",
+		},
+		{
+			name: "entries in one statement",
+			entries: []audit.Entry{
+				{
+					Event: audit.Event{
+						Type:       audit.EventLegalHoldPlaced,
+						UserID:     userID,
+						OccurredAt: now,
+						Details:    map[string]string{"reason": "Case 42"},
+					},
+					ClientIP: "203.0.113.7",
+				},
+				{Event: audit.Event{Type: audit.EventRowTampered, OccurredAt: now}},
+			},
+			wantValues: "VALUES ($1, $2, $3, $4, $5), ($6, $7, $8, $9, $10)",
+			wantArgs: []interface{}{
+				audit.EventLegalHoldPlaced,
+				uuid.NullUUID{UUID: userID, Valid: true},
+				sql.NullString{String: "203.0.113.7", Valid: true},
+				[]byte(`{"reason":"Case 42"}`),
+				now.In(time.Local),
+				audit.EventRowTampered,
+				uuid.NullUUID{},
+				sql.NullString{},
+				[]byte("{}"),
+				now.In(time.Local),
+			},
+			wantExec: true,
+		},
+		{
+			name:       "database error",
+			entries:    []audit.Entry{{Event: audit.Event{Type: audit.EventRowTampered, OccurredAt: now}}},
+			execErr:    errors.New("connection lost"),
+			wantValues: "VALUES ($1, $2, $3, $4, $5)",
+			wantArgs: []interface{}{
+				audit.EventRowTampered, uuid.NullUUID{}, sql.NullString{}, []byte("{}"), now.In(time.Local),
+			},
+			wantExec: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// executed reports whether a statement was sent.
+			var executed bool
+			client := &mockDBClient{
+				execFunc: func(_ context.Context, query string, args ...interface{}) (sql.Result, error) {
+					executed = true
+					assert.Contains(t, query, "INSERT INTO aegis_vault_keeper.audit_events")
+					assert.Contains(t, query, tt.wantValues)
+					assert.Equal(t, tt.wantArgs, args)
+					if tt.execErr != nil {
+						return nil, tt.execErr
+					}
+					return mockResult{rowsAffected: int64(len(tt.entries))}, nil
+				},
+			}
+
+			err := NewRepository(client).Append(context.Background(), tt.entries)
+			assert.Equal(t, tt.wantExec, executed)
+			if tt.execErr != nil {
+				assert.ErrorIs(t, err, tt.execErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestRepository_Load(t *testing.T) {
+	t.Parallel()
+
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+	dbErr := errors.New("connection lost")
+
+	client := &mockDBClient{
+		queryFunc: func(_ context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+			assert.Contains(t, query, "WHERE occurred_at >= $1 AND occurred_at < $2 AND seq > $3")
+			assert.Contains(t, query, "ORDER BY seq")
+			assert.Equal(t, []interface{}{from.In(time.Local), to.In(time.Local), int64(41), 100}, args)
+			return nil, dbErr
+		},
+	}
+
+	entries, err := NewRepository(client).Load(context.Background(), LoadParams{
+		From:  from,
+		To:    to,
+		After: 41,
+		Limit: 100,
+	})
+
+	require.Error(t, err)
+	assert.ErrorIs(t, err, dbErr)
+	assert.Nil(t, entries)
+}
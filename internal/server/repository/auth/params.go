@@ -18,3 +18,15 @@ type LoadParams struct {
 	// ID contains the user's unique identifier for lookup (alternative to Login).
 	ID uuid.UUID
 }
+
+// ListParams contains the parameters for listing provisioned users.
+type ListParams struct {
+	// Login restricts the listing to the user with this login, when set.
+	Login string
+	// ExternalID restricts the listing to users with this directory identifier, when set.
+	ExternalID string
+	// Offset specifies how many matching users to skip.
+	Offset int
+	// Limit specifies the maximum number of users to return.
+	Limit int
+}
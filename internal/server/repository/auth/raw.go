@@ -3,6 +3,7 @@ package auth
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strings"
@@ -18,16 +19,58 @@ func rawSave(db db.DBClient) saveFunc {
 	return func(ctx context.Context, p SaveParams) error {
 		e := p.Entity
 
+		var externalID sql.NullString
+		if e.ExternalID != "" {
+			externalID = sql.NullString{String: e.ExternalID, Valid: true}
+		}
+		var deprovisionedAt sql.NullTime
+		if !e.DeprovisionedAt.IsZero() {
+			deprovisionedAt = sql.NullTime{Time: e.DeprovisionedAt, Valid: true}
+		}
+		var sessionsRevokedAt sql.NullTime
+		if !e.SessionsRevokedAt.IsZero() {
+			sessionsRevokedAt = sql.NullTime{Time: e.SessionsRevokedAt, Valid: true}
+		}
+		recoveryCodes, err := json.Marshal(nonNil(e.RecoveryCodes))
+		if err != nil {
+			return fmt.Errorf("failed to encode recovery codes: %w", err)
+		}
+		passwordHistory, err := json.Marshal(nonNilHistory(e.PasswordHistory))
+		if err != nil {
+			return fmt.Errorf("failed to encode password history: %w", err)
+		}
+
+		duressVaultID := uuid.NullUUID{UUID: e.DuressVaultID, Valid: e.DuressVaultID != uuid.Nil}
+		profileOf := uuid.NullUUID{UUID: e.ProfileOf, Valid: e.ProfileOf != uuid.Nil}
+
 		query := `
-			INSERT INTO aegis_vault_keeper.auth_users (id, login, password_hash, crypto_key)
-			VALUES ($1, $2, $3, $4)
+			INSERT INTO aegis_vault_keeper.auth_users
+				(id, login, password_hash, crypto_key, active, external_id, deprovisioned_at, sessions_revoked_at,
+				 unlock_salt, recovery_codes, duress_password_hash, duress_vault_id, profile_of, duress_alert,
+				 password_history)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
 			ON CONFLICT (id) DO UPDATE SET
 			  login = EXCLUDED.login,
 			  password_hash = EXCLUDED.password_hash,
-			  crypto_key = EXCLUDED.crypto_key
+			  crypto_key = EXCLUDED.crypto_key,
+			  active = EXCLUDED.active,
+			  external_id = EXCLUDED.external_id,
+			  deprovisioned_at = EXCLUDED.deprovisioned_at,
+			  sessions_revoked_at = EXCLUDED.sessions_revoked_at,
+			  unlock_salt = EXCLUDED.unlock_salt,
+			  recovery_codes = EXCLUDED.recovery_codes,
+			  duress_password_hash = EXCLUDED.duress_password_hash,
+			  duress_vault_id = EXCLUDED.duress_vault_id,
+			  profile_of = EXCLUDED.profile_of,
+			  duress_alert = EXCLUDED.duress_alert,
+			  password_history = EXCLUDED.password_history
 		`
 
-		if _, err := db.Exec(ctx, query, e.ID, e.Login, e.PasswordHash, e.CryptoKey); err != nil {
+		if _, err := db.Exec(ctx, query,
+			e.ID, e.Login, e.PasswordHash, e.CryptoKey, e.Active, externalID, deprovisionedAt, sessionsRevokedAt,
+			e.UnlockSalt, recoveryCodes, e.DuressPasswordHash, duressVaultID, profileOf, e.DuressAlert,
+			passwordHistory,
+		); err != nil {
 			// pgErr holds the PostgreSQL error details for constraint violation checking.
 			var pgErr *pgconn.PgError
 			if ok := errors.As(err, &pgErr); ok && pgErr.Code == "23505" {
@@ -40,6 +83,7 @@ func rawSave(db db.DBClient) saveFunc {
 }
 
 // rawLoad creates a function that performs raw database load operations for users.
+// Decoy vault profiles are loaded by ID only, so a login never resolves to one.
 func rawLoad(db db.DBClient) func(ctx context.Context, p LoadParams) (*auth.User, error) {
 	return func(ctx context.Context, p LoadParams) (*auth.User, error) {
 		var (
@@ -50,7 +94,9 @@ func rawLoad(db db.DBClient) func(ctx context.Context, p LoadParams) (*auth.User
 		)
 
 		queryBuilder.WriteString(`
-			SELECT id, login, password_hash, crypto_key
+			SELECT id, login, password_hash, crypto_key, active, external_id, deprovisioned_at, sessions_revoked_at,
+			       unlock_salt, recovery_codes, duress_password_hash, duress_vault_id, profile_of, duress_alert,
+			       password_history
 			FROM aegis_vault_keeper.auth_users
 		`)
 
@@ -60,7 +106,7 @@ func rawLoad(db db.DBClient) func(ctx context.Context, p LoadParams) (*auth.User
 			argIdx++
 		}
 		if p.Login != "" {
-			conditions = append(conditions, fmt.Sprintf("login = $%d", argIdx))
+			conditions = append(conditions, fmt.Sprintf("login = $%d AND profile_of IS NULL", argIdx))
 			args = append(args, p.Login)
 			// argIdx++ // Last usage, no need to increment
 		}
@@ -71,20 +117,151 @@ func rawLoad(db db.DBClient) func(ctx context.Context, p LoadParams) (*auth.User
 		queryBuilder.WriteString(" WHERE ")
 		queryBuilder.WriteString(strings.Join(conditions, " AND "))
 
-		// user holds the retrieved user entity from the database.
-		var user auth.User
+		var (
+			// user holds the retrieved user entity from the database.
+			user              auth.User
+			externalID        sql.NullString
+			deprovisionedAt   sql.NullTime
+			sessionsRevokedAt sql.NullTime
+			recoveryCodes     []byte
+			passwordHistory   []byte
+			duressVaultID     uuid.NullUUID
+			profileOf         uuid.NullUUID
+		)
 		if err := db.QueryRow(ctx, queryBuilder.String(), args...).Scan(
 			&user.ID,
 			&user.Login,
 			&user.PasswordHash,
 			&user.CryptoKey,
+			&user.Active,
+			&externalID,
+			&deprovisionedAt,
+			&sessionsRevokedAt,
+			&user.UnlockSalt,
+			&recoveryCodes,
+			&user.DuressPasswordHash,
+			&duressVaultID,
+			&profileOf,
+			&user.DuressAlert,
+			&passwordHistory,
 		); err != nil {
 			if errors.Is(err, sql.ErrNoRows) {
 				return nil, ErrUserNotFound
 			}
 			return nil, fmt.Errorf("failed to scan user: %w", err)
 		}
+		if err := json.Unmarshal(recoveryCodes, &user.RecoveryCodes); err != nil {
+			return nil, fmt.Errorf("failed to decode recovery codes: %w", err)
+		}
+		if err := json.Unmarshal(passwordHistory, &user.PasswordHistory); err != nil {
+			return nil, fmt.Errorf("failed to decode password history: %w", err)
+		}
+		user.ExternalID = externalID.String
+		user.DeprovisionedAt = deprovisionedAt.Time
+		user.SessionsRevokedAt = sessionsRevokedAt.Time
+		user.DuressVaultID = duressVaultID.UUID
+		user.ProfileOf = profileOf.UUID
 
 		return &user, nil
 	}
 }
+
+// rawListIDs creates a function that lists the identifiers of all registered users.
+func rawListIDs(db db.DBClient) func(ctx context.Context) ([]uuid.UUID, error) {
+	return func(ctx context.Context) ([]uuid.UUID, error) {
+		rows, err := db.Query(ctx, "SELECT id FROM aegis_vault_keeper.auth_users ORDER BY id")
+		if err != nil {
+			return nil, fmt.Errorf("failed to execute query: %w", err)
+		}
+		defer func() { _ = rows.Close() }()
+
+		var ids []uuid.UUID
+		for rows.Next() {
+			// id holds the identifier of the current user row.
+			var id uuid.UUID
+			if err := rows.Scan(&id); err != nil {
+				return nil, fmt.Errorf("failed to scan user id: %w", err)
+			}
+			ids = append(ids, id)
+		}
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("failed to iterate user ids: %w", err)
+		}
+
+		return ids, nil
+	}
+}
+
+// rawList creates a function that lists provisioned users without their crypto keys, ordered by login,
+// and counts all users matching the filter. Decoy vault profiles are not listed.
+func rawList(db db.DBClient) func(ctx context.Context, p ListParams) ([]*auth.User, int, error) {
+	return func(ctx context.Context, p ListParams) ([]*auth.User, int, error) {
+		var (
+			where strings.Builder
+			args  []interface{}
+		)
+		where.WriteString(" WHERE deprovisioned_at IS NULL AND profile_of IS NULL")
+		if p.Login != "" {
+			args = append(args, p.Login)
+			fmt.Fprintf(&where, " AND login = $%d", len(args))
+		}
+		if p.ExternalID != "" {
+			args = append(args, p.ExternalID)
+			fmt.Fprintf(&where, " AND external_id = $%d", len(args))
+		}
+
+		var total int
+		countQuery := "SELECT COUNT(*) FROM aegis_vault_keeper.auth_users" + where.String()
+		if err := db.QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
+			return nil, 0, fmt.Errorf("failed to count users: %w", err)
+		}
+		if total == 0 || p.Limit <= 0 {
+			return nil, total, nil
+		}
+
+		args = append(args, p.Offset, p.Limit)
+		query := "SELECT id, login, active, external_id FROM aegis_vault_keeper.auth_users" + where.String() +
+			fmt.Sprintf(" ORDER BY login OFFSET $%d LIMIT $%d", len(args)-1, len(args))
+
+		rows, err := db.Query(ctx, query, args...)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to execute query: %w", err)
+		}
+		defer func() { _ = rows.Close() }()
+
+		var users []*auth.User
+		for rows.Next() {
+			var (
+				// user holds the current user row.
+				user       auth.User
+				externalID sql.NullString
+			)
+			if err := rows.Scan(&user.ID, &user.Login, &user.Active, &externalID); err != nil {
+				return nil, 0, fmt.Errorf("failed to scan user: %w", err)
+			}
+			user.ExternalID = externalID.String
+			users = append(users, &user)
+		}
+		if err := rows.Err(); err != nil {
+			return nil, 0, fmt.Errorf("failed to iterate users: %w", err)
+		}
+
+		return users, total, nil
+	}
+}
+
+// nonNil substitutes an empty slice for nil, so no recovery codes are stored as an empty JSON array.
+func nonNil(digests [][]byte) [][]byte {
+	if digests == nil {
+		return [][]byte{}
+	}
+	return digests
+}
+
+// nonNilHistory substitutes an empty slice for nil, so no password history is stored as an empty JSON array.
+func nonNilHistory(hashes []string) []string {
+	if hashes == nil {
+		return []string{}
+	}
+	return hashes
+}
@@ -3,8 +3,10 @@ package auth
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/auth"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/db"
@@ -97,6 +99,99 @@ func TestRawSave(t *testing.T) {
 			expectErr:   true,
 			expectedErr: ErrUserAlreadyExists,
 		},
+		{
+			name: "save deprovisioned directory user",
+			params: SaveParams{
+				Entity: &auth.User{
+					ID:              uuid.New(),
+					Login:           "directory_user",
+					PasswordHash:    "hashed_password",
+					CryptoKey:       []byte("crypto_key"),
+					ExternalID:      "00u1a2b3c4",
+					DeprovisionedAt: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+				},
+			},
+		},
+		{
+			name: "save user with revoked sessions",
+			params: SaveParams{
+				Entity: &auth.User{
+					ID:                uuid.New(),
+					Login:             "user@example.com",
+					PasswordHash:      "hashed_password",
+					CryptoKey:         []byte("crypto_key"),
+					Active:            true,
+					SessionsRevokedAt: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+				},
+			},
+		},
+		{
+			name: "save user with unlock secret",
+			params: SaveParams{
+				Entity: &auth.User{
+					ID:           uuid.New(),
+					Login:        "user@example.com",
+					PasswordHash: "hashed_password",
+					CryptoKey:    []byte("sealed_crypto_key"),
+					UnlockSalt:   []byte("0123456789abcdef"),
+					Active:       true,
+				},
+			},
+		},
+		{
+			name: "save user with recovery codes",
+			params: SaveParams{
+				Entity: &auth.User{
+					ID:            uuid.New(),
+					Login:         "user@example.com",
+					PasswordHash:  "hashed_password",
+					CryptoKey:     []byte("crypto_key"),
+					RecoveryCodes: [][]byte{auth.HashRecoveryCode("abcd-efgh"), auth.HashRecoveryCode("ijkl-mnop")},
+					Active:        true,
+				},
+			},
+		},
+		{
+			name: "save user with password history",
+			params: SaveParams{
+				Entity: &auth.User{
+					ID:              uuid.New(),
+					Login:           "user@example.com",
+					PasswordHash:    "hashed_password",
+					PasswordHistory: []string{"hashed_previous", "hashed_older"},
+					CryptoKey:       []byte("crypto_key"),
+					Active:          true,
+				},
+			},
+		},
+		{
+			name: "save user with duress vault",
+			params: SaveParams{
+				Entity: &auth.User{
+					ID:                 uuid.New(),
+					Login:              "user@example.com",
+					PasswordHash:       "hashed_password",
+					DuressPasswordHash: "hashed_duress_password",
+					CryptoKey:          []byte("crypto_key"),
+					DuressVaultID:      uuid.New(),
+					Active:             true,
+					DuressAlert:        true,
+				},
+			},
+		},
+		{
+			name: "save decoy vault profile",
+			params: SaveParams{
+				Entity: &auth.User{
+					ID:           uuid.New(),
+					Login:        "duress:decoy",
+					PasswordHash: "hashed_duress_password",
+					CryptoKey:    []byte("decoy_crypto_key"),
+					ProfileOf:    uuid.New(),
+					Active:       true,
+				},
+			},
+		},
 		{
 			name: "save with generic database error",
 			params: SaveParams{
@@ -123,11 +218,38 @@ func TestRawSave(t *testing.T) {
 					assert.Contains(t, query, "ON CONFLICT (id) DO UPDATE SET")
 
 					// Verify parameters
-					require.Len(t, args, 4)
+					require.Len(t, args, 15)
 					assert.Equal(t, tt.params.Entity.ID, args[0])
 					assert.Equal(t, tt.params.Entity.Login, args[1])
 					assert.Equal(t, tt.params.Entity.PasswordHash, args[2])
 					assert.Equal(t, tt.params.Entity.CryptoKey, args[3])
+					assert.Equal(t, tt.params.Entity.Active, args[4])
+					assert.Equal(t, sql.NullString{
+						String: tt.params.Entity.ExternalID, Valid: tt.params.Entity.ExternalID != "",
+					}, args[5])
+					assert.Equal(t, sql.NullTime{
+						Time: tt.params.Entity.DeprovisionedAt, Valid: !tt.params.Entity.DeprovisionedAt.IsZero(),
+					}, args[6])
+					assert.Equal(t, sql.NullTime{
+						Time: tt.params.Entity.SessionsRevokedAt, Valid: !tt.params.Entity.SessionsRevokedAt.IsZero(),
+					}, args[7])
+					assert.Equal(t, tt.params.Entity.UnlockSalt, args[8])
+					// recoveryCodes holds the decoded recovery code digests.
+					var recoveryCodes [][]byte
+					require.NoError(t, json.Unmarshal(args[9].([]byte), &recoveryCodes))
+					assert.Equal(t, nonNil(tt.params.Entity.RecoveryCodes), recoveryCodes)
+					assert.Equal(t, tt.params.Entity.DuressPasswordHash, args[10])
+					assert.Equal(t, uuid.NullUUID{
+						UUID: tt.params.Entity.DuressVaultID, Valid: tt.params.Entity.DuressVaultID != uuid.Nil,
+					}, args[11])
+					assert.Equal(t, uuid.NullUUID{
+						UUID: tt.params.Entity.ProfileOf, Valid: tt.params.Entity.ProfileOf != uuid.Nil,
+					}, args[12])
+					assert.Equal(t, tt.params.Entity.DuressAlert, args[13])
+					// passwordHistory holds the decoded previous password hashes.
+					var passwordHistory []string
+					require.NoError(t, json.Unmarshal(args[14].([]byte), &passwordHistory))
+					assert.Equal(t, nonNilHistory(tt.params.Entity.PasswordHistory), passwordHistory)
 
 					return nil, tt.execError
 				},
@@ -234,12 +356,19 @@ func TestRawSaveQueryConstruction(t *testing.T) {
 
 					// Verify query components
 					assert.Contains(t, query, "INSERT INTO aegis_vault_keeper.auth_users")
-					assert.Contains(t, query, "(id, login, password_hash, crypto_key)")
-					assert.Contains(t, query, "VALUES ($1, $2, $3, $4)")
+					assert.Contains(t, query,
+						"(id, login, password_hash, crypto_key, active, external_id, deprovisioned_at, sessions_revoked_at,")
+					assert.Contains(t, query, "VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)")
 					assert.Contains(t, query, "ON CONFLICT (id) DO UPDATE SET")
 					assert.Contains(t, query, "login = EXCLUDED.login")
 					assert.Contains(t, query, "password_hash = EXCLUDED.password_hash")
 					assert.Contains(t, query, "crypto_key = EXCLUDED.crypto_key")
+					assert.Contains(t, query, "deprovisioned_at = EXCLUDED.deprovisioned_at")
+					assert.Contains(t, query, "sessions_revoked_at = EXCLUDED.sessions_revoked_at")
+					assert.Contains(t, query, "unlock_salt = EXCLUDED.unlock_salt")
+					assert.Contains(t, query, "recovery_codes = EXCLUDED.recovery_codes")
+					assert.Contains(t, query, "duress_password_hash = EXCLUDED.duress_password_hash")
+					assert.Contains(t, query, "profile_of = EXCLUDED.profile_of")
 
 					return mockResult{}, nil
 				},
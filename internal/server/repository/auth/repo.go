@@ -7,6 +7,7 @@ import (
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/auth"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/db"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/middleware"
+	"github.com/google/uuid"
 )
 
 // saveFunc defines the signature for user persistence operations with middleware support.
@@ -27,13 +28,19 @@ type Repository struct {
 	save saveFunc
 	// load is the middleware chain for user retrieval operations.
 	load loadFunc
+	// listIDs lists the identifiers of all registered users.
+	listIDs func(ctx context.Context) ([]uuid.UUID, error)
+	// list lists provisioned users without their crypto keys.
+	list func(ctx context.Context, params ListParams) ([]*auth.User, int, error)
 }
 
 // NewRepository creates a new user repository with encryption middleware and database client.
 func NewRepository(dbClient db.DBClient, secretKey []byte) *Repository {
 	return &Repository{
-		save: middleware.Chain(rawSave(dbClient), encryptionMw(secretKey)),
-		load: middleware.Chain(rawLoad(dbClient), decryptionMw(secretKey)),
+		save:    middleware.Chain(rawSave(dbClient), encryptionMw(secretKey)),
+		load:    middleware.Chain(rawLoad(dbClient), decryptionMw(secretKey)),
+		listIDs: rawListIDs(dbClient),
+		list:    rawList(dbClient),
 	}
 }
 
@@ -53,3 +60,22 @@ func (r *Repository) Load(ctx context.Context, params LoadParams) (*auth.User, e
 	}
 	return u, nil
 }
+
+// ListIDs returns the identifiers of all registered users.
+func (r *Repository) ListIDs(ctx context.Context) ([]uuid.UUID, error) {
+	ids, err := r.listIDs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+	return ids, nil
+}
+
+// List returns a page of provisioned users, without their crypto keys, and the number of users
+// matching the parameters. Deprovisioned users are excluded.
+func (r *Repository) List(ctx context.Context, params ListParams) ([]*auth.User, int, error) {
+	users, total, err := r.list(ctx, params)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list users: %w", err)
+	}
+	return users, total, nil
+}
@@ -38,6 +38,7 @@ func TestNewRepository(t *testing.T) {
 			assert.NotNil(t, repo)
 			assert.NotNil(t, repo.save)
 			assert.NotNil(t, repo.load)
+			assert.NotNil(t, repo.listIDs)
 		})
 	}
 }
@@ -369,3 +370,15 @@ func TestRepository_SaveLoadIntegration(t *testing.T) {
 		})
 	}
 }
+
+func TestRepository_ListIDs(t *testing.T) {
+	t.Parallel()
+
+	repo := NewRepository(&mockDBClient{}, []byte("test-secret-key-32-chars-long!!"))
+
+	ids, err := repo.ListIDs(context.Background())
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to list users")
+	assert.Nil(t, ids)
+}
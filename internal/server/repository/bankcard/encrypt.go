@@ -4,11 +4,23 @@ import (
 	"context"
 	"fmt"
 
-	"github.com/gdyunin/aegis-vault-keeper/internal/server/crypto"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/bankcard"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/fieldcrypt"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/keyprv"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/securebytes"
 )
 
+// itemType identifies bank card items in the ownership binding of encrypted fields.
+const itemType = "bank_card"
+
+// BoundTable describes the bank_cards table columns that may still hold fields sealed before
+// encrypted fields were bound to their owner and item.
+var BoundTable = fieldcrypt.BoundTable{
+	Name:     "bank_cards",
+	ItemType: itemType,
+	Columns:  []string{"card_number", "card_holder", "expiry_month", "expiry_year", "cvv", "description"},
+}
+
 // encryptionMw creates a middleware that encrypts bank card data before saving.
 // All sensitive fields (card number, holder, expiry, CVV, description) are encrypted using AES-GCM.
 func encryptionMw(keyProvider keyprv.UserKeyProvider) saveMw {
@@ -18,37 +30,72 @@ func encryptionMw(keyProvider keyprv.UserKeyProvider) saveMw {
 			if err != nil {
 				return fmt.Errorf("failed to provide user key: %w", err)
 			}
+			defer securebytes.Wipe(k)
 
-			copyEntity := *p.Entity
-
-			if copyEntity.CardNumber, err = crypto.EncryptAESGCM(k, copyEntity.CardNumber); err != nil {
-				return fmt.Errorf("failed to encrypt card number: %w", err)
-			}
-			if copyEntity.CardHolder, err = crypto.EncryptAESGCM(k, copyEntity.CardHolder); err != nil {
-				return fmt.Errorf("failed to encrypt card holder: %w", err)
-			}
-			if copyEntity.ExpiryMonth, err = crypto.EncryptAESGCM(k, copyEntity.ExpiryMonth); err != nil {
-				return fmt.Errorf("failed to encrypt expiry month: %w", err)
-			}
-			if copyEntity.ExpiryYear, err = crypto.EncryptAESGCM(k, copyEntity.ExpiryYear); err != nil {
-				return fmt.Errorf("failed to encrypt expiry year: %w", err)
+			if p.Entity, err = sealBankCard(k, p.Entity); err != nil {
+				return err
 			}
-			if copyEntity.CVV, err = crypto.EncryptAESGCM(k, copyEntity.CVV); err != nil {
-				return fmt.Errorf("failed to encrypt CVV: %w", err)
+			return next(ctx, p)
+		}
+	}
+}
+
+// batchEncryptionMw creates a middleware that encrypts every bank card of a batch before saving.
+// The user key is provided once for the whole batch and the entities are sealed on the pipeline.
+func batchEncryptionMw(keyProvider keyprv.UserKeyProvider, pipeline *fieldcrypt.Pipeline) saveBatchMw {
+	return func(next saveBatchFunc) saveBatchFunc {
+		return func(ctx context.Context, p SaveBatchParams) error {
+			k, err := keyProvider.UserKeyProvide(ctx, p.UserID)
+			if err != nil {
+				return fmt.Errorf("failed to provide user key: %w", err)
 			}
-			if copyEntity.Description, err = crypto.EncryptAESGCM(k, copyEntity.Description); err != nil {
-				return fmt.Errorf("failed to encrypt description: %w", err)
+			defer securebytes.Wipe(k)
+
+			sealed, err := fieldcrypt.Map(ctx, pipeline, p.Entities, func(e *bankcard.BankCard) (*bankcard.BankCard, error) {
+				return sealBankCard(k, e)
+			})
+			if err != nil {
+				return err
 			}
 
-			p.Entity = &copyEntity
+			p.Entities = sealed
 			return next(ctx, p)
 		}
 	}
 }
 
+// sealBankCard returns a copy of the bank card with all sensitive fields encrypted with the key.
+func sealBankCard(k []byte, e *bankcard.BankCard) (*bankcard.BankCard, error) {
+	copyEntity := *e
+	// b holds the ownership binding authenticated with every encrypted field.
+	b := fieldcrypt.Binding{ItemType: itemType, UserID: copyEntity.UserID, ItemID: copyEntity.ID}
+
+	var err error
+	if copyEntity.CardNumber, err = b.Seal(k, "card_number", copyEntity.CardNumber); err != nil {
+		return nil, fmt.Errorf("failed to encrypt card number: %w", err)
+	}
+	if copyEntity.CardHolder, err = b.Seal(k, "card_holder", copyEntity.CardHolder); err != nil {
+		return nil, fmt.Errorf("failed to encrypt card holder: %w", err)
+	}
+	if copyEntity.ExpiryMonth, err = b.Seal(k, "expiry_month", copyEntity.ExpiryMonth); err != nil {
+		return nil, fmt.Errorf("failed to encrypt expiry month: %w", err)
+	}
+	if copyEntity.ExpiryYear, err = b.Seal(k, "expiry_year", copyEntity.ExpiryYear); err != nil {
+		return nil, fmt.Errorf("failed to encrypt expiry year: %w", err)
+	}
+	if copyEntity.CVV, err = b.Seal(k, "cvv", copyEntity.CVV); err != nil {
+		return nil, fmt.Errorf("failed to encrypt CVV: %w", err)
+	}
+	if copyEntity.Description, err = b.Seal(k, "description", copyEntity.Description); err != nil {
+		return nil, fmt.Errorf("failed to encrypt description: %w", err)
+	}
+	return &copyEntity, nil
+}
+
 // decryptionMw creates a middleware that decrypts bank card data after loading.
-// All sensitive fields are decrypted using AES-GCM with the user's encryption key.
-func decryptionMw(keyProvider keyprv.UserKeyProvider) loadMw {
+// The sensitive fields selected by the load parameters, all by default, are decrypted using AES-GCM
+// with the user's encryption key.
+func decryptionMw(keyProvider keyprv.UserKeyProvider, pipeline *fieldcrypt.Pipeline) loadMw {
 	return func(next loadFunc) loadFunc {
 		return func(ctx context.Context, p LoadParams) ([]*bankcard.BankCard, error) {
 			entities, err := next(ctx, p)
@@ -64,26 +111,35 @@ func decryptionMw(keyProvider keyprv.UserKeyProvider) loadMw {
 			if err != nil {
 				return nil, fmt.Errorf("failed to provide user key: %w", err)
 			}
+			defer securebytes.Wipe(k)
 
-			for _, entity := range entities {
-				if entity.CardNumber, err = crypto.DecryptAESGCM(k, entity.CardNumber); err != nil {
-					return nil, fmt.Errorf("failed to decrypt card number: %w", err)
+			err = pipeline.Run(ctx, len(entities), func(i int) error {
+				entity := entities[i]
+				// b holds the ownership binding the loaded fields must have been sealed with.
+				b := fieldcrypt.Binding{ItemType: itemType, UserID: p.UserID, ItemID: entity.ID}
+				var err error
+				if entity.CardNumber, err = b.OpenSelected(k, p.Fields, "card_number", entity.CardNumber); err != nil {
+					return fmt.Errorf("failed to decrypt card number: %w", err)
 				}
-				if entity.CardHolder, err = crypto.DecryptAESGCM(k, entity.CardHolder); err != nil {
-					return nil, fmt.Errorf("failed to decrypt card holder: %w", err)
+				if entity.CardHolder, err = b.OpenSelected(k, p.Fields, "card_holder", entity.CardHolder); err != nil {
+					return fmt.Errorf("failed to decrypt card holder: %w", err)
 				}
-				if entity.ExpiryMonth, err = crypto.DecryptAESGCM(k, entity.ExpiryMonth); err != nil {
-					return nil, fmt.Errorf("failed to decrypt expiry month: %w", err)
+				if entity.ExpiryMonth, err = b.OpenSelected(k, p.Fields, "expiry_month", entity.ExpiryMonth); err != nil {
+					return fmt.Errorf("failed to decrypt expiry month: %w", err)
 				}
-				if entity.ExpiryYear, err = crypto.DecryptAESGCM(k, entity.ExpiryYear); err != nil {
-					return nil, fmt.Errorf("failed to decrypt expiry year: %w", err)
+				if entity.ExpiryYear, err = b.OpenSelected(k, p.Fields, "expiry_year", entity.ExpiryYear); err != nil {
+					return fmt.Errorf("failed to decrypt expiry year: %w", err)
 				}
-				if entity.CVV, err = crypto.DecryptAESGCM(k, entity.CVV); err != nil {
-					return nil, fmt.Errorf("failed to decrypt CVV: %w", err)
+				if entity.CVV, err = b.OpenSelected(k, p.Fields, "cvv", entity.CVV); err != nil {
+					return fmt.Errorf("failed to decrypt CVV: %w", err)
 				}
-				if entity.Description, err = crypto.DecryptAESGCM(k, entity.Description); err != nil {
-					return nil, fmt.Errorf("failed to decrypt description: %w", err)
+				if entity.Description, err = b.OpenSelected(k, p.Fields, "description", entity.Description); err != nil {
+					return fmt.Errorf("failed to decrypt description: %w", err)
 				}
+				return nil
+			})
+			if err != nil {
+				return nil, err
 			}
 
 			return entities, nil
@@ -1,6 +1,8 @@
 package bankcard
 
 import (
+	"time"
+
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/bankcard"
 	"github.com/google/uuid"
 )
@@ -11,10 +13,28 @@ type SaveParams struct {
 	Entity *bankcard.BankCard
 }
 
+// SaveBatchParams contains the parameters for saving several bank card entities of one user at once.
+type SaveBatchParams struct {
+	// Entities contains the bank card data to be persisted; every entity must belong to UserID.
+	Entities []*bankcard.BankCard
+	// UserID contains the identifier of the user owning all entities.
+	UserID uuid.UUID
+}
+
 // LoadParams contains the parameters for loading bank card entities from the repository.
 type LoadParams struct {
+	// After restricts the load to the bank cards whose ID sorts after it; zero value starts at the first.
+	After uuid.UUID
+	// AsOf selects the versions current at the specified moment; zero value loads current versions.
+	AsOf time.Time
+	// Fields names the sensitive fields to decrypt (optional); unselected fields are returned empty.
+	Fields []string
 	// ID contains the specific bank card identifier for single record lookup (optional).
 	ID uuid.UUID
+	// IDs contains the bank card identifiers to restrict the lookup to (optional).
+	IDs []uuid.UUID
+	// Limit caps the number of loaded bank cards and orders them by ID; zero value loads all.
+	Limit int
 	// UserID contains the user identifier for filtering bank cards by owner (required).
 	UserID uuid.UUID
 }
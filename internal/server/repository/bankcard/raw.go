@@ -4,23 +4,33 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/bankcard"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/db"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/history"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/rowsign"
 	"github.com/google/uuid"
 )
 
 // rawSave creates a database save function that persists bank card data directly to PostgreSQL.
 // Uses INSERT ON CONFLICT DO UPDATE for upsert behavior.
-func rawSave(db db.DBClient) saveFunc {
+func rawSave(db db.DBClient, signer *rowsign.Signer) saveFunc {
 	return func(ctx context.Context, p SaveParams) error {
 		e := p.Entity
 
+		signature, err := signer.Sign(SignedTable, rowValues(e)...)
+		if err != nil {
+			return fmt.Errorf("failed to sign bank card row: %w", err)
+		}
+
 		query := `
 			INSERT INTO aegis_vault_keeper.bank_cards (
-				id, user_id, card_number, card_holder, expiry_month, expiry_year, cvv, description, updated_at
-			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+				id, user_id, card_number, card_holder, expiry_month, expiry_year, cvv, description,
+				updated_at, signature
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 			ON CONFLICT (id) DO UPDATE SET
 			  card_number   = EXCLUDED.card_number,
 			  card_holder   = EXCLUDED.card_holder,
@@ -28,7 +38,8 @@ func rawSave(db db.DBClient) saveFunc {
 			  expiry_year   = EXCLUDED.expiry_year,
 			  cvv           = EXCLUDED.cvv,
 			  description   = EXCLUDED.description,
-			  updated_at    = EXCLUDED.updated_at
+			  updated_at    = EXCLUDED.updated_at,
+			  signature     = EXCLUDED.signature
 		`
 
 		if _, err := db.Exec(
@@ -43,6 +54,7 @@ func rawSave(db db.DBClient) saveFunc {
 			e.CVV,
 			e.Description,
 			e.UpdatedAt,
+			signature,
 		); err != nil {
 			return fmt.Errorf("query execution failed: %w", err)
 		}
@@ -50,9 +62,81 @@ func rawSave(db db.DBClient) saveFunc {
 	}
 }
 
+// batchChunkSize limits the number of rows upserted by a single batch statement.
+const batchChunkSize = 1000
+
+// rawSaveBatch creates a database save function that upserts bank cards in chunks of batchChunkSize rows.
+// Every chunk is a single statement whose text does not depend on the row count, so it is prepared
+// once per connection. Rows of other users are never overwritten.
+func rawSaveBatch(db db.DBClient, signer *rowsign.Signer) saveBatchFunc {
+	return func(ctx context.Context, p SaveBatchParams) error {
+		query := `
+			INSERT INTO aegis_vault_keeper.bank_cards (
+				id, user_id, card_number, card_holder, expiry_month, expiry_year, cvv, description,
+				updated_at, signature
+			)
+			SELECT * FROM unnest(
+				$1::uuid[], $2::uuid[], $3::bytea[], $4::bytea[], $5::bytea[], $6::bytea[], $7::bytea[],
+				$8::bytea[], $9::timestamp[], $10::bytea[]
+			)
+			ON CONFLICT (id) DO UPDATE SET
+			  card_number   = EXCLUDED.card_number,
+			  card_holder   = EXCLUDED.card_holder,
+			  expiry_month  = EXCLUDED.expiry_month,
+			  expiry_year   = EXCLUDED.expiry_year,
+			  cvv           = EXCLUDED.cvv,
+			  description   = EXCLUDED.description,
+			  updated_at    = EXCLUDED.updated_at,
+			  signature     = EXCLUDED.signature
+			WHERE bank_cards.user_id = EXCLUDED.user_id
+		`
+
+		for chunk := range slices.Chunk(p.Entities, batchChunkSize) {
+			var (
+				ids          = make([]uuid.UUID, len(chunk))
+				userIDs      = make([]uuid.UUID, len(chunk))
+				numbers      = make([][]byte, len(chunk))
+				holders      = make([][]byte, len(chunk))
+				months       = make([][]byte, len(chunk))
+				years        = make([][]byte, len(chunk))
+				cvvs         = make([][]byte, len(chunk))
+				descriptions = make([][]byte, len(chunk))
+				updatedAt    = make([]time.Time, len(chunk))
+				signatures   = make([][]byte, len(chunk))
+			)
+			for i, e := range chunk {
+				signature, err := signer.Sign(SignedTable, rowValues(e)...)
+				if err != nil {
+					return fmt.Errorf("failed to sign bank card row %s: %w", e.ID, err)
+				}
+				ids[i], userIDs[i] = e.ID, e.UserID
+				numbers[i], holders[i] = e.CardNumber, e.CardHolder
+				months[i], years[i], cvvs[i] = e.ExpiryMonth, e.ExpiryYear, e.CVV
+				descriptions[i], updatedAt[i], signatures[i] = e.Description, e.UpdatedAt, signature
+			}
+
+			res, err := db.Exec(
+				ctx, query,
+				ids, userIDs, numbers, holders, months, years, cvvs, descriptions, updatedAt, signatures,
+			)
+			if err != nil {
+				return fmt.Errorf("query execution failed: %w", err)
+			}
+			affected, err := res.RowsAffected()
+			if err != nil {
+				return fmt.Errorf("failed to get affected rows: %w", err)
+			}
+			if affected != int64(len(chunk)) {
+				return fmt.Errorf("%d of %d bank cards belong to another user", int64(len(chunk))-affected, len(chunk))
+			}
+		}
+		return nil
+	}
+}
+
 // rawLoad creates a database load function that retrieves bank card data from PostgreSQL.
 // Supports filtering by user ID and specific bank card ID.
-func rawLoad(db db.DBClient) func(ctx context.Context, p LoadParams) ([]*bankcard.BankCard, error) {
+func rawLoad(db db.DBClient, signer *rowsign.Signer) loadFunc {
 	return func(ctx context.Context, p LoadParams) ([]*bankcard.BankCard, error) {
 		var (
 			queryBuilder strings.Builder
@@ -61,28 +145,47 @@ func rawLoad(db db.DBClient) func(ctx context.Context, p LoadParams) ([]*bankcar
 			argIdx       = 1
 		)
 
-		queryBuilder.WriteString(`
-			SELECT id, user_id, card_number, card_holder, expiry_month,
-				   expiry_year, cvv, description, updated_at
-			FROM aegis_vault_keeper.bank_cards
-		`)
+		// columns lists the selected bank card columns.
+		const columns = "id, user_id, card_number, card_holder, expiry_month, " +
+			"expiry_year, cvv, description, updated_at, signature"
+		source := "aegis_vault_keeper.bank_cards"
+		if !p.AsOf.IsZero() {
+			source = history.AsOfSource("bank_cards", columns, argIdx)
+			args = append(args, history.AsOfArg(p.AsOf))
+			argIdx++
+		}
+		queryBuilder.WriteString("SELECT " + columns + " FROM " + source)
 
 		if p.ID != uuid.Nil {
 			conditions = append(conditions, fmt.Sprintf("id = $%d", argIdx))
 			args = append(args, p.ID)
 			argIdx++
 		}
+		if p.IDs != nil {
+			conditions = append(conditions, fmt.Sprintf("id = ANY($%d::uuid[])", argIdx))
+			args = append(args, p.IDs)
+			argIdx++
+		}
 		if p.UserID != uuid.Nil {
 			conditions = append(conditions, fmt.Sprintf("user_id = $%d", argIdx))
 			args = append(args, p.UserID)
-			// argIdx++ // Last usage, no need to increment
+			argIdx++
 		}
 		if len(conditions) == 0 {
 			return nil, errors.New("at least one of ID or UserID must be provided")
 		}
+		if p.After != uuid.Nil {
+			conditions = append(conditions, fmt.Sprintf("id > $%d", argIdx))
+			args = append(args, p.After)
+			argIdx++
+		}
 
 		queryBuilder.WriteString(" WHERE ")
 		queryBuilder.WriteString(strings.Join(conditions, " AND "))
+		if p.Limit > 0 {
+			queryBuilder.WriteString(fmt.Sprintf(" ORDER BY id LIMIT $%d", argIdx))
+			args = append(args, p.Limit)
+		}
 
 		rows, err := db.Query(ctx, queryBuilder.String(), args...)
 		if err != nil {
@@ -95,6 +198,8 @@ func rawLoad(db db.DBClient) func(ctx context.Context, p LoadParams) ([]*bankcar
 		for rows.Next() {
 			// bc holds a single bank card entity during database row scanning.
 			var bc bankcard.BankCard
+			// signature holds the stored row integrity signature.
+			var signature []byte
 			if err := rows.Scan(
 				&bc.ID,
 				&bc.UserID,
@@ -105,9 +210,13 @@ func rawLoad(db db.DBClient) func(ctx context.Context, p LoadParams) ([]*bankcar
 				&bc.CVV,
 				&bc.Description,
 				&bc.UpdatedAt,
+				&signature,
 			); err != nil {
 				return nil, fmt.Errorf("row scan failed: %w", err)
 			}
+			if err := signer.Verify(SignedTable, signature, rowValues(&bc)...); err != nil {
+				return nil, fmt.Errorf("bank card row %s failed integrity verification: %w", bc.ID, err)
+			}
 			cards = append(cards, &bc)
 		}
 		if err := rows.Err(); err != nil {
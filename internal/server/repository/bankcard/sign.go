@@ -0,0 +1,37 @@
+package bankcard
+
+import (
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/bankcard"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/rowsign"
+)
+
+// SignedTable describes the bank_cards table columns covered by row integrity signatures.
+var SignedTable = rowsign.Table{
+	Name: "bank_cards",
+	Columns: []rowsign.Column{
+		{Name: "id", Kind: rowsign.KindUUID},
+		{Name: "user_id", Kind: rowsign.KindUUID},
+		{Name: "card_number", Kind: rowsign.KindBytes},
+		{Name: "card_holder", Kind: rowsign.KindBytes},
+		{Name: "expiry_month", Kind: rowsign.KindBytes},
+		{Name: "expiry_year", Kind: rowsign.KindBytes},
+		{Name: "cvv", Kind: rowsign.KindBytes},
+		{Name: "description", Kind: rowsign.KindBytes},
+		{Name: "updated_at", Kind: rowsign.KindTime},
+	},
+}
+
+// rowValues returns the stored bank card column values in SignedTable column order.
+func rowValues(e *bankcard.BankCard) []any {
+	return []any{
+		e.ID,
+		e.UserID,
+		e.CardNumber,
+		e.CardHolder,
+		e.ExpiryMonth,
+		e.ExpiryYear,
+		e.CVV,
+		e.Description,
+		e.UpdatedAt,
+	}
+}
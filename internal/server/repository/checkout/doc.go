@@ -0,0 +1,5 @@
+// Package checkout provides shared credential persistence for the AegisVaultKeeper server.
+//
+// This package implements storage of the credentials owners share with directory groups, and of the
+// exclusive check-outs of their members, in PostgreSQL.
+package checkout
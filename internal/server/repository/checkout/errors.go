@@ -0,0 +1,11 @@
+package checkout
+
+import "errors"
+
+// Shared credential repository error definitions.
+var (
+	// ErrShareNotFound indicates that the requested credential share was not found in the repository.
+	ErrShareNotFound = errors.New("credential share not found")
+	// ErrLeaseChanged indicates that the check-out of the credential changed since it was loaded.
+	ErrLeaseChanged = errors.New("credential check-out changed concurrently")
+)
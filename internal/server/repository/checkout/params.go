@@ -0,0 +1,45 @@
+package checkout
+
+import (
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/checkout"
+	"github.com/google/uuid"
+)
+
+// SaveParams contains the parameters for saving a credential share to the repository.
+type SaveParams struct {
+	// Entity contains the share to be created, or whose group and rotation setting are updated.
+	Entity *checkout.Share
+}
+
+// LoadParams contains the parameters for loading credential shares from the repository.
+// Zero fields do not filter.
+type LoadParams struct {
+	// ExpiredAt selects shares still held although their check-out ended before the moment.
+	ExpiredAt time.Time
+	// CredentialID selects the share of the specified credential.
+	CredentialID uuid.UUID
+	// OwnerID selects the shares of the specified owner.
+	OwnerID uuid.UUID
+	// MemberID selects the shares with groups the specified user is a member of.
+	MemberID uuid.UUID
+}
+
+// SwapLeaseParams contains the parameters for replacing the check-out of a credential share.
+type SwapLeaseParams struct {
+	// Entity contains the share with its new holder and check-out times.
+	Entity *checkout.Share
+	// CheckedOutAt contains the check-out time loaded with the share; zero when it was checked in.
+	CheckedOutAt time.Time
+	// HolderID identifies the holder loaded with the share; uuid.Nil when it was checked in.
+	HolderID uuid.UUID
+}
+
+// DeleteParams contains the parameters for deleting a credential share from the repository.
+type DeleteParams struct {
+	// CredentialID identifies the shared credential.
+	CredentialID uuid.UUID
+	// OwnerID identifies the owner of the credential.
+	OwnerID uuid.UUID
+}
@@ -0,0 +1,168 @@
+package checkout
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/checkout"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/db"
+	"github.com/google/uuid"
+)
+
+// Repository provides credential share persistence operations.
+type Repository struct {
+	// db is the database client used for credential share operations.
+	db db.DBClient
+}
+
+// NewRepository creates a new Repository with the provided database client.
+func NewRepository(dbClient db.DBClient) *Repository {
+	return &Repository{db: dbClient}
+}
+
+// Save shares a credential or updates the group and rotation setting of an existing share.
+// The check-out of an existing share is kept.
+func (r *Repository) Save(ctx context.Context, params SaveParams) error {
+	e := params.Entity
+
+	query := `
+		INSERT INTO aegis_vault_keeper.credential_shares
+			(credential_id, owner_id, group_id, rotate_on_checkin, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (credential_id) DO UPDATE SET
+			group_id = EXCLUDED.group_id,
+			rotate_on_checkin = EXCLUDED.rotate_on_checkin
+	`
+	if _, err := r.db.Exec(ctx, query, e.CredentialID, e.OwnerID, e.GroupID, e.RotateOnCheckIn, e.CreatedAt); err != nil {
+		return fmt.Errorf("failed to save credential share: %w", err)
+	}
+	return nil
+}
+
+// Load retrieves credential shares matching the provided parameters, ordered by creation time.
+func (r *Repository) Load(ctx context.Context, params LoadParams) ([]*checkout.Share, error) {
+	var (
+		conditions []string
+		args       []interface{}
+	)
+	// filter adds a condition comparing a column with the next positional argument.
+	filter := func(condition string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	if params.CredentialID != uuid.Nil {
+		filter("credential_id = $%d", params.CredentialID)
+	}
+	if params.OwnerID != uuid.Nil {
+		filter("owner_id = $%d", params.OwnerID)
+	}
+	if params.MemberID != uuid.Nil {
+		filter(`group_id IN (
+			SELECT group_id FROM aegis_vault_keeper.user_group_members WHERE user_id = $%d
+		)`, params.MemberID)
+	}
+	if !params.ExpiredAt.IsZero() {
+		filter("holder_id IS NOT NULL AND expires_at <= $%d", params.ExpiredAt)
+	}
+
+	query := `
+		SELECT credential_id, owner_id, group_id, rotate_on_checkin, holder_id, created_at, checked_out_at, expires_at
+		FROM aegis_vault_keeper.credential_shares
+	`
+	if len(conditions) != 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY created_at, credential_id"
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load credential shares: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var shares []*checkout.Share
+	for rows.Next() {
+		var (
+			s            checkout.Share
+			holderID     uuid.NullUUID
+			checkedOutAt sql.NullTime
+			expiresAt    sql.NullTime
+		)
+		if err := rows.Scan(
+			&s.CredentialID, &s.OwnerID, &s.GroupID, &s.RotateOnCheckIn, &holderID,
+			&s.CreatedAt, &checkedOutAt, &expiresAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan credential share: %w", err)
+		}
+		s.HolderID = holderID.UUID
+		s.CheckedOutAt = checkedOutAt.Time
+		s.ExpiresAt = expiresAt.Time
+		shares = append(shares, &s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate credential shares: %w", err)
+	}
+	return shares, nil
+}
+
+// SwapLease stores the check-out of the share only while the check-out loaded with it is still current,
+// so concurrent check-outs cannot both succeed. Returns ErrLeaseChanged when the check-out changed meanwhile.
+func (r *Repository) SwapLease(ctx context.Context, params SwapLeaseParams) error {
+	e := params.Entity
+
+	query := `
+		UPDATE aegis_vault_keeper.credential_shares
+		SET holder_id = $1, checked_out_at = $2, expires_at = $3
+		WHERE credential_id = $4
+			AND holder_id IS NOT DISTINCT FROM $5
+			AND checked_out_at IS NOT DISTINCT FROM $6
+	`
+	res, err := r.db.Exec(ctx, query,
+		nullUUID(e.HolderID), nullTime(e.CheckedOutAt), nullTime(e.ExpiresAt),
+		e.CredentialID, nullUUID(params.HolderID), nullTime(params.CheckedOutAt),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save credential check-out: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check saved credential check-outs: %w", err)
+	}
+	if n == 0 {
+		return ErrLeaseChanged
+	}
+	return nil
+}
+
+// Delete stops sharing the credential of the owner.
+func (r *Repository) Delete(ctx context.Context, params DeleteParams) error {
+	query := `
+		DELETE FROM aegis_vault_keeper.credential_shares
+		WHERE credential_id = $1 AND owner_id = $2
+	`
+	res, err := r.db.Exec(ctx, query, params.CredentialID, params.OwnerID)
+	if err != nil {
+		return fmt.Errorf("failed to delete credential share: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check deleted credential shares: %w", err)
+	}
+	if n == 0 {
+		return ErrShareNotFound
+	}
+	return nil
+}
+
+// nullUUID converts uuid.Nil to SQL NULL.
+func nullUUID(id uuid.UUID) uuid.NullUUID {
+	return uuid.NullUUID{UUID: id, Valid: id != uuid.Nil}
+}
+
+// nullTime converts the zero time to SQL NULL.
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
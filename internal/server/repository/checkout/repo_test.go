@@ -0,0 +1,299 @@
+package checkout
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/checkout"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/db"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockResult implements sql.Result for testing.
+type mockResult struct {
+	rowsAffected int64
+}
+
+func (m mockResult) LastInsertId() (int64, error) { return 0, nil }
+func (m mockResult) RowsAffected() (int64, error) { return m.rowsAffected, nil }
+
+// mockDBClient implements db.DBClient for testing.
+type mockDBClient struct {
+	execFunc  func(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	queryFunc func(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+func (m *mockDBClient) Exec(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	if m.execFunc != nil {
+		return m.execFunc(ctx, query, args...)
+	}
+	return mockResult{rowsAffected: 1}, nil
+}
+
+func (m *mockDBClient) Query(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	if m.queryFunc != nil {
+		return m.queryFunc(ctx, query, args...)
+	}
+	return nil, errors.New("mock not configured")
+}
+
+func (m *mockDBClient) QueryRow(context.Context, string, ...interface{}) *sql.Row {
+	return nil
+}
+
+func (m *mockDBClient) BeginTx(context.Context, *sql.TxOptions) (*sql.Tx, error) {
+	return nil, errors.New("mock not configured")
+}
+
+func (m *mockDBClient) CommitTx(*sql.Tx) error {
+	return nil
+}
+
+func (m *mockDBClient) RollbackTx(*sql.Tx) error {
+	return nil
+}
+
+// Ensure mockDBClient implements db.DBClient.
+var _ db.DBClient = (*mockDBClient)(nil)
+
+func TestRepository_Save(t *testing.T) {
+	t.Parallel()
+
+	credentialID, ownerID, groupID := uuid.New(), uuid.New(), uuid.New()
+	createdAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		execErr error
+		name    string
+	}{
+		{name: "saved"},
+		{name: "exec error", execErr: errors.New("connection refused")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var gotArgs []interface{}
+			client := &mockDBClient{
+				execFunc: func(_ context.Context, query string, args ...interface{}) (sql.Result, error) {
+					assert.Contains(t, query, "INSERT INTO aegis_vault_keeper.credential_shares")
+					assert.Contains(t, query, "ON CONFLICT (credential_id) DO UPDATE")
+					assert.NotContains(t, query, "holder_id")
+					gotArgs = args
+					return mockResult{rowsAffected: 1}, tt.execErr
+				},
+			}
+
+			err := NewRepository(client).Save(context.Background(), SaveParams{Entity: &checkout.Share{
+				CredentialID:    credentialID,
+				OwnerID:         ownerID,
+				GroupID:         groupID,
+				RotateOnCheckIn: true,
+				CreatedAt:       createdAt,
+			}})
+
+			if tt.execErr != nil {
+				assert.ErrorIs(t, err, tt.execErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, []interface{}{credentialID, ownerID, groupID, true, createdAt}, gotArgs)
+		})
+	}
+}
+
+func TestRepository_Load(t *testing.T) {
+	t.Parallel()
+
+	credentialID, ownerID, memberID := uuid.New(), uuid.New(), uuid.New()
+	at := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		params    LoadParams
+		wantQuery string
+		noQuery   string
+		wantArgs  []interface{}
+	}{
+		{
+			name:      "all shares",
+			noQuery:   "WHERE",
+			wantQuery: "ORDER BY created_at, credential_id",
+		},
+		{
+			name:      "share of a credential of an owner",
+			params:    LoadParams{CredentialID: credentialID, OwnerID: ownerID},
+			wantQuery: "WHERE credential_id = $1 AND owner_id = $2",
+			wantArgs:  []interface{}{credentialID, ownerID},
+		},
+		{
+			name:      "shares of a member",
+			params:    LoadParams{MemberID: memberID},
+			wantQuery: "FROM aegis_vault_keeper.user_group_members WHERE user_id = $1",
+			wantArgs:  []interface{}{memberID},
+		},
+		{
+			name:      "expired check-outs",
+			params:    LoadParams{ExpiredAt: at},
+			wantQuery: "WHERE holder_id IS NOT NULL AND expires_at <= $1",
+			wantArgs:  []interface{}{at},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			queryErr := errors.New("query error")
+			var gotQuery string
+			var gotArgs []interface{}
+			client := &mockDBClient{
+				queryFunc: func(_ context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+					gotQuery, gotArgs = query, args
+					return nil, queryErr
+				},
+			}
+
+			_, err := NewRepository(client).Load(context.Background(), tt.params)
+
+			require.ErrorIs(t, err, queryErr)
+			assert.Contains(t, gotQuery, tt.wantQuery)
+			if tt.noQuery != "" {
+				assert.NotContains(t, gotQuery, tt.noQuery)
+			}
+			assert.Equal(t, tt.wantArgs, gotArgs)
+		})
+	}
+}
+
+func TestRepository_SwapLease(t *testing.T) {
+	t.Parallel()
+
+	credentialID, holderID := uuid.New(), uuid.New()
+	checkedOutAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	expiresAt := checkedOutAt.Add(time.Hour)
+
+	tests := []struct {
+		execErr  error
+		wantErr  error
+		name     string
+		params   SwapLeaseParams
+		wantArgs []interface{}
+		affected int64
+	}{
+		{
+			name: "check out",
+			params: SwapLeaseParams{Entity: &checkout.Share{
+				CredentialID: credentialID, HolderID: holderID, CheckedOutAt: checkedOutAt, ExpiresAt: expiresAt,
+			}},
+			affected: 1,
+			wantArgs: []interface{}{
+				uuid.NullUUID{UUID: holderID, Valid: true},
+				sql.NullTime{Time: checkedOutAt, Valid: true},
+				sql.NullTime{Time: expiresAt, Valid: true},
+				credentialID, uuid.NullUUID{}, sql.NullTime{},
+			},
+		},
+		{
+			name: "check in",
+			params: SwapLeaseParams{
+				Entity:       &checkout.Share{CredentialID: credentialID},
+				HolderID:     holderID,
+				CheckedOutAt: checkedOutAt,
+			},
+			affected: 1,
+			wantArgs: []interface{}{
+				uuid.NullUUID{}, sql.NullTime{}, sql.NullTime{},
+				credentialID, uuid.NullUUID{UUID: holderID, Valid: true}, sql.NullTime{Time: checkedOutAt, Valid: true},
+			},
+		},
+		{
+			name:    "changed concurrently",
+			params:  SwapLeaseParams{Entity: &checkout.Share{CredentialID: credentialID, HolderID: holderID}},
+			wantErr: ErrLeaseChanged,
+		},
+		{
+			name:    "exec error",
+			params:  SwapLeaseParams{Entity: &checkout.Share{CredentialID: credentialID}},
+			execErr: errors.New("connection refused"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var gotArgs []interface{}
+			client := &mockDBClient{
+				execFunc: func(_ context.Context, query string, args ...interface{}) (sql.Result, error) {
+					assert.Contains(t, query, "holder_id IS NOT DISTINCT FROM $5")
+					gotArgs = args
+					return mockResult{rowsAffected: tt.affected}, tt.execErr
+				},
+			}
+
+			err := NewRepository(client).SwapLease(context.Background(), tt.params)
+
+			switch {
+			case tt.execErr != nil:
+				assert.ErrorIs(t, err, tt.execErr)
+			case tt.wantErr != nil:
+				assert.ErrorIs(t, err, tt.wantErr)
+			default:
+				require.NoError(t, err)
+				assert.Equal(t, tt.wantArgs, gotArgs)
+			}
+		})
+	}
+}
+
+func TestRepository_Delete(t *testing.T) {
+	t.Parallel()
+
+	credentialID, ownerID := uuid.New(), uuid.New()
+
+	tests := []struct {
+		execErr  error
+		wantErr  error
+		name     string
+		affected int64
+	}{
+		{name: "deleted", affected: 1},
+		{name: "not found", wantErr: ErrShareNotFound},
+		{name: "exec error", execErr: errors.New("connection refused")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := &mockDBClient{
+				execFunc: func(_ context.Context, query string, args ...interface{}) (sql.Result, error) {
+					assert.Contains(t, query, "DELETE FROM aegis_vault_keeper.credential_shares")
+					assert.Equal(t, []interface{}{credentialID, ownerID}, args)
+					return mockResult{rowsAffected: tt.affected}, tt.execErr
+				},
+			}
+
+			err := NewRepository(client).Delete(context.Background(), DeleteParams{
+				CredentialID: credentialID,
+				OwnerID:      ownerID,
+			})
+
+			switch {
+			case tt.execErr != nil:
+				assert.ErrorIs(t, err, tt.execErr)
+			case tt.wantErr != nil:
+				assert.ErrorIs(t, err, tt.wantErr)
+			default:
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -4,11 +4,23 @@ import (
 	"context"
 	"fmt"
 
-	"github.com/gdyunin/aegis-vault-keeper/internal/server/crypto"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/credential"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/fieldcrypt"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/keyprv"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/securebytes"
 )
 
+// itemType identifies credential items in the ownership binding of encrypted fields.
+const itemType = "credential"
+
+// BoundTable describes the credentials table columns that may still hold fields sealed before
+// encrypted fields were bound to their owner and item.
+var BoundTable = fieldcrypt.BoundTable{
+	Name:     "credentials",
+	ItemType: itemType,
+	Columns:  []string{"login", "password", "description"},
+}
+
 // encryptionMw creates middleware that encrypts credential fields before saving to the database.
 func encryptionMw(keyProvider keyprv.UserKeyProvider) saveMw {
 	return func(next saveFunc) saveFunc {
@@ -17,28 +29,67 @@ func encryptionMw(keyProvider keyprv.UserKeyProvider) saveMw {
 			if err != nil {
 				return fmt.Errorf("failed to provide user key: %w", err)
 			}
+			defer securebytes.Wipe(k)
 
-			copyEntity := *p.Entity
-
-			if copyEntity.Login, err = crypto.EncryptAESGCM(k, copyEntity.Login); err != nil {
-				return fmt.Errorf("failed to encrypt login: %w", err)
+			if p.Entity, err = sealCredential(k, p.Entity); err != nil {
+				return err
 			}
-			if copyEntity.Password, err = crypto.EncryptAESGCM(k, copyEntity.Password); err != nil {
-				return fmt.Errorf("failed to encrypt password: %w", err)
+			return next(ctx, p)
+		}
+	}
+}
+
+// batchEncryptionMw creates a middleware that encrypts every credential of a batch before saving.
+// The user key is provided once for the whole batch and the entities are sealed on the pipeline.
+func batchEncryptionMw(keyProvider keyprv.UserKeyProvider, pipeline *fieldcrypt.Pipeline) saveBatchMw {
+	return func(next saveBatchFunc) saveBatchFunc {
+		return func(ctx context.Context, p SaveBatchParams) error {
+			k, err := keyProvider.UserKeyProvide(ctx, p.UserID)
+			if err != nil {
+				return fmt.Errorf("failed to provide user key: %w", err)
 			}
-			if copyEntity.Description, err = crypto.EncryptAESGCM(k, copyEntity.Description); err != nil {
-				return fmt.Errorf("failed to encrypt description: %w", err)
+			defer securebytes.Wipe(k)
+
+			sealed, err := fieldcrypt.Map(
+				ctx, pipeline, p.Entities,
+				func(e *credential.Credential) (*credential.Credential, error) { return sealCredential(k, e) },
+			)
+			if err != nil {
+				return err
 			}
 
-			p.Entity = &copyEntity
+			p.Entities = sealed
 			return next(ctx, p)
 		}
 	}
 }
 
+// sealCredential returns a copy of the credential with all sensitive fields encrypted with the key.
+func sealCredential(k []byte, e *credential.Credential) (*credential.Credential, error) {
+	copyEntity := *e
+	// b holds the ownership binding authenticated with every encrypted field.
+	b := fieldcrypt.Binding{ItemType: itemType, UserID: copyEntity.UserID, ItemID: copyEntity.ID}
+
+	var err error
+	if copyEntity.Login, err = b.Seal(k, "login", copyEntity.Login); err != nil {
+		return nil, fmt.Errorf("failed to encrypt login: %w", err)
+	}
+	if copyEntity.Password, err = b.Seal(k, "password", copyEntity.Password); err != nil {
+		return nil, fmt.Errorf("failed to encrypt password: %w", err)
+	}
+	if copyEntity.Description, err = b.Seal(k, "description", copyEntity.Description); err != nil {
+		return nil, fmt.Errorf("failed to encrypt description: %w", err)
+	}
+	if copyEntity.URIs, err = b.Seal(k, "uris", copyEntity.URIs); err != nil {
+		return nil, fmt.Errorf("failed to encrypt URIs: %w", err)
+	}
+	return &copyEntity, nil
+}
+
 // DecryptionMw creates middleware that decrypts credential fields after loading from the database.
-// All sensitive fields (login, password, description) are decrypted using AES-GCM with the user's encryption key.
-func decryptionMw(keyProvider keyprv.UserKeyProvider) loadMw {
+// The selected sensitive fields (login, password, description, uris; all by default) are decrypted using AES-GCM
+// with the user's encryption key.
+func decryptionMw(keyProvider keyprv.UserKeyProvider, pipeline *fieldcrypt.Pipeline) loadMw {
 	return func(next loadFunc) loadFunc {
 		return func(ctx context.Context, p LoadParams) ([]*credential.Credential, error) {
 			entities, err := next(ctx, p)
@@ -53,17 +104,33 @@ func decryptionMw(keyProvider keyprv.UserKeyProvider) loadMw {
 			if err != nil {
 				return nil, fmt.Errorf("failed to provide user key: %w", err)
 			}
+			defer securebytes.Wipe(k)
 
-			for _, entity := range entities {
-				if entity.Login, err = crypto.DecryptAESGCM(k, entity.Login); err != nil {
-					return nil, fmt.Errorf("failed to decrypt login: %w", err)
+			err = pipeline.Run(ctx, len(entities), func(i int) error {
+				entity := entities[i]
+				// b holds the ownership binding the loaded fields must have been sealed with.
+				b := fieldcrypt.Binding{ItemType: itemType, UserID: p.UserID, ItemID: entity.ID}
+				var err error
+				if entity.Login, err = b.OpenSelected(k, p.Fields, "login", entity.Login); err != nil {
+					return fmt.Errorf("failed to decrypt login: %w", err)
 				}
-				if entity.Password, err = crypto.DecryptAESGCM(k, entity.Password); err != nil {
-					return nil, fmt.Errorf("failed to decrypt password: %w", err)
+				if entity.Password, err = b.OpenSelected(k, p.Fields, "password", entity.Password); err != nil {
+					return fmt.Errorf("failed to decrypt password: %w", err)
 				}
-				if entity.Description, err = crypto.DecryptAESGCM(k, entity.Description); err != nil {
-					return nil, fmt.Errorf("failed to decrypt description: %w", err)
+				if entity.Description, err = b.OpenSelected(k, p.Fields, "description", entity.Description); err != nil {
+					return fmt.Errorf("failed to decrypt description: %w", err)
 				}
+				// Credentials saved before URIs were introduced have none stored.
+				if len(entity.URIs) == 0 {
+					return nil
+				}
+				if entity.URIs, err = b.OpenSelected(k, p.Fields, "uris", entity.URIs); err != nil {
+					return fmt.Errorf("failed to decrypt URIs: %w", err)
+				}
+				return nil
+			})
+			if err != nil {
+				return nil, err
 			}
 
 			return entities, nil
@@ -1,6 +1,7 @@
 package credential
 
 import (
+	"bytes"
 	"context"
 	"testing"
 
@@ -10,6 +11,7 @@ import (
 
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/crypto"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/credential"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/fieldcrypt"
 )
 
 // Mock key provider for testing encryption middleware.
@@ -22,7 +24,7 @@ func (m *mockEncryptKeyProvider) UserKeyProvide(ctx context.Context, userID uuid
 	if m.shouldErr {
 		return nil, assert.AnError
 	}
-	return m.key, nil
+	return bytes.Clone(m.key), nil
 }
 
 func TestEncryptionMw(t *testing.T) {
@@ -50,6 +52,7 @@ func TestEncryptionMw(t *testing.T) {
 				Login:       []byte("testuser"),
 				Password:    []byte("testpass"),
 				Description: []byte("test desc"),
+				URIs:        []byte(`[{"value":"example.com","match":"base_domain"}]`),
 			},
 			expectError: false,
 		},
@@ -124,6 +127,9 @@ func TestEncryptionMw(t *testing.T) {
 				if len(tt.entity.Description) > 0 {
 					assert.NotEqual(t, tt.entity.Description, receivedParams.Entity.Description, "Description should be encrypted")
 				}
+				if len(tt.entity.URIs) > 0 {
+					assert.NotEqual(t, tt.entity.URIs, receivedParams.Entity.URIs, "URIs should be encrypted")
+				}
 
 				// Verify entity structure is preserved
 				assert.Equal(t, tt.entity.ID, receivedParams.Entity.ID, "ID should be preserved")
@@ -157,10 +163,12 @@ func TestDecryptionMw(t *testing.T) {
 				id := uuid.New()
 				userID := uuid.New()
 
-				// Use crypto package to create real encrypted values
-				loginEncrypted, _ := crypto.EncryptAESGCM(validKey, []byte("test_login"))
-				passwordEncrypted, _ := crypto.EncryptAESGCM(validKey, []byte("test_password"))
-				descEncrypted, _ := crypto.EncryptAESGCM(validKey, []byte("test_description"))
+				// b holds the ownership binding the loader expects for this entity
+				b := fieldcrypt.Binding{ItemType: itemType, UserID: userID, ItemID: id}
+				loginEncrypted, _ := b.Seal(validKey, "login", []byte("test_login"))
+				passwordEncrypted, _ := b.Seal(validKey, "password", []byte("test_password"))
+				descEncrypted, _ := b.Seal(validKey, "description", []byte("test_description"))
+				urisEncrypted, _ := b.Seal(validKey, "uris", []byte(`[{"value":"example.com"}]`))
 
 				return []*credential.Credential{
 					{
@@ -169,11 +177,31 @@ func TestDecryptionMw(t *testing.T) {
 						Login:       loginEncrypted,
 						Password:    passwordEncrypted,
 						Description: descEncrypted,
+						URIs:        urisEncrypted,
 					},
 				}
 			}(),
 			expectError: false,
 		},
+		{
+			name: "ciphertext swapped from another item",
+			keyProvider: &mockEncryptKeyProvider{
+				key:       validKey,
+				shouldErr: false,
+			},
+			entities: func() []*credential.Credential {
+				userID := uuid.New()
+				// other holds the binding of a different item owned by the same user
+				other := fieldcrypt.Binding{ItemType: itemType, UserID: userID, ItemID: uuid.New()}
+				swapped, _ := other.Seal(validKey, "login", []byte("foreign"))
+
+				return []*credential.Credential{
+					{ID: uuid.New(), UserID: userID, Login: swapped},
+				}
+			}(),
+			expectError: true,
+			errorMsg:    "integrity violation",
+		},
 		{
 			name: "empty entities list",
 			keyProvider: &mockEncryptKeyProvider{
@@ -229,7 +257,7 @@ func TestDecryptionMw(t *testing.T) {
 			t.Parallel()
 
 			// Create middleware
-			mw := decryptionMw(tt.keyProvider)
+			mw := decryptionMw(tt.keyProvider, nil)
 
 			// Mock next function that simulates database load
 			nextFunc := func(ctx context.Context, p LoadParams) ([]*credential.Credential, error) {
@@ -244,6 +272,9 @@ func TestDecryptionMw(t *testing.T) {
 
 			// Execute
 			params := LoadParams{UserID: uuid.New()}
+			if len(tt.entities) > 0 {
+				params.UserID = tt.entities[0].UserID
+			}
 			result, err := wrappedFunc(context.Background(), params)
 
 			if tt.expectError {
@@ -269,6 +300,93 @@ func TestDecryptionMw(t *testing.T) {
 	}
 }
 
+func TestDecryptionMw_Legacy(t *testing.T) {
+	t.Parallel()
+
+	key := []byte("12345678901234567890123456789012")
+	// sealed is a field encrypted the way credentials were stored before their fields were bound to them.
+	sealed, err := crypto.EncryptAESGCM(key, []byte("test_login"))
+	require.NoError(t, err)
+	legacy := &credential.Credential{ID: uuid.New(), UserID: uuid.New(), Login: sealed}
+
+	load := decryptionMw(&mockEncryptKeyProvider{key: key}, nil)(
+		func(context.Context, LoadParams) ([]*credential.Credential, error) {
+			return []*credential.Credential{legacy}, nil
+		},
+	)
+	_, err = load(context.Background(), LoadParams{UserID: legacy.UserID})
+
+	require.ErrorIs(t, err, fieldcrypt.ErrIntegrityViolation)
+}
+
+func TestDecryptionMw_SelectedFields(t *testing.T) {
+	t.Parallel()
+
+	validKey := []byte("12345678901234567890123456789012")
+	id := uuid.New()
+	userID := uuid.New()
+
+	// b holds the ownership binding the loader expects for the entity
+	b := fieldcrypt.Binding{ItemType: itemType, UserID: userID, ItemID: id}
+	loginEncrypted, err := b.Seal(validKey, "login", []byte("test_login"))
+	require.NoError(t, err)
+	passwordEncrypted, err := b.Seal(validKey, "password", []byte("test_password"))
+	require.NoError(t, err)
+	descEncrypted, err := b.Seal(validKey, "description", []byte("test_description"))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		fields       []string
+		wantLogin    []byte
+		wantPassword []byte
+	}{
+		{
+			name:         "all fields by default",
+			fields:       nil,
+			wantLogin:    []byte("test_login"),
+			wantPassword: []byte("test_password"),
+		},
+		{
+			name:         "login only",
+			fields:       []string{"login"},
+			wantLogin:    []byte("test_login"),
+			wantPassword: nil,
+		},
+		{
+			name:         "no sensitive field",
+			fields:       []string{"updated_at"},
+			wantLogin:    nil,
+			wantPassword: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mw := decryptionMw(&mockEncryptKeyProvider{key: validKey}, nil)
+			load := mw(func(ctx context.Context, p LoadParams) ([]*credential.Credential, error) {
+				return []*credential.Credential{
+					{
+						ID:          id,
+						UserID:      userID,
+						Login:       loginEncrypted,
+						Password:    passwordEncrypted,
+						Description: descEncrypted,
+					},
+				}, nil
+			})
+
+			result, err := load(context.Background(), LoadParams{Fields: tt.fields, UserID: userID})
+			require.NoError(t, err)
+			require.Len(t, result, 1)
+			assert.Equal(t, tt.wantLogin, result[0].Login)
+			assert.Equal(t, tt.wantPassword, result[0].Password)
+		})
+	}
+}
+
 func TestMiddlewareChaining(t *testing.T) {
 	t.Parallel()
 
@@ -1,6 +1,8 @@
 package credential
 
 import (
+	"time"
+
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/credential"
 	"github.com/google/uuid"
 )
@@ -11,10 +13,28 @@ type SaveParams struct {
 	Entity *credential.Credential
 }
 
+// SaveBatchParams contains the parameters for saving several credential entities of one user at once.
+type SaveBatchParams struct {
+	// Entities contains the credential data to be persisted; every entity must belong to UserID.
+	Entities []*credential.Credential
+	// UserID contains the identifier of the user owning all entities.
+	UserID uuid.UUID
+}
+
 // LoadParams contains parameters for loading credential entities from the repository.
 type LoadParams struct {
+	// After restricts the load to the credentials whose ID sorts after it; zero value starts at the first.
+	After uuid.UUID
+	// AsOf selects the versions current at the specified moment; zero value loads current versions.
+	AsOf time.Time
+	// Fields names the encrypted fields to decrypt; other fields are left empty. Empty decrypts all fields.
+	Fields []string
 	// ID specifies the credential ID to load; zero value loads all user credentials.
 	ID uuid.UUID
+	// IDs contains the credential identifiers to restrict the lookup to (optional).
+	IDs []uuid.UUID
+	// Limit caps the number of loaded credentials and orders them by ID; zero value loads all.
+	Limit int
 	// UserID identifies the user whose credentials to load.
 	UserID uuid.UUID
 }
@@ -4,38 +4,129 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/credential"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/db"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/history"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/rowsign"
 	"github.com/google/uuid"
 )
 
 // rawSave creates a function that performs raw database save operations for credentials.
-func rawSave(db db.DBClient) saveFunc {
+func rawSave(db db.DBClient, signer *rowsign.Signer) saveFunc {
 	return func(ctx context.Context, p SaveParams) error {
 		e := p.Entity
 
+		signature, err := signer.Sign(SignedTable, rowValues(e)...)
+		if err != nil {
+			return fmt.Errorf("failed to sign credential row: %w", err)
+		}
+
 		query := `
-			INSERT INTO aegis_vault_keeper.credentials (id, user_id, login, password, description, updated_at)
-			VALUES ($1, $2, $3, $4, $5, $6)
+			INSERT INTO aegis_vault_keeper.credentials (
+				id, user_id, login, password, description, updated_at, signature, uris
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 			ON CONFLICT (id) DO UPDATE SET
 			  login        = EXCLUDED.login,
 			  password     = EXCLUDED.password,
 			  description  = EXCLUDED.description,
-			  updated_at   = EXCLUDED.updated_at
+			  updated_at   = EXCLUDED.updated_at,
+			  signature    = EXCLUDED.signature,
+			  uris         = EXCLUDED.uris
 		`
 
-		if _, err := db.Exec(ctx, query, e.ID, e.UserID, e.Login, e.Password, e.Description, e.UpdatedAt); err != nil {
+		if _, err := db.Exec(
+			ctx,
+			query,
+			e.ID,
+			e.UserID,
+			e.Login,
+			e.Password,
+			e.Description,
+			e.UpdatedAt,
+			signature,
+			e.URIs,
+		); err != nil {
 			return fmt.Errorf("failed to save credential: %w", err)
 		}
 		return nil
 	}
 }
 
+// batchChunkSize limits the number of rows upserted by a single batch statement.
+const batchChunkSize = 1000
+
+// rawSaveBatch creates a database save function that upserts credentials in chunks of batchChunkSize rows.
+// Every chunk is a single statement whose text does not depend on the row count, so it is prepared
+// once per connection. Rows of other users are never overwritten.
+func rawSaveBatch(db db.DBClient, signer *rowsign.Signer) saveBatchFunc {
+	return func(ctx context.Context, p SaveBatchParams) error {
+		query := `
+			INSERT INTO aegis_vault_keeper.credentials (
+				id, user_id, login, password, description, updated_at, signature, uris
+			)
+			SELECT * FROM unnest(
+				$1::uuid[], $2::uuid[], $3::bytea[], $4::bytea[], $5::bytea[], $6::timestamp[], $7::bytea[],
+				$8::bytea[]
+			)
+			ON CONFLICT (id) DO UPDATE SET
+			  login       = EXCLUDED.login,
+			  password    = EXCLUDED.password,
+			  description = EXCLUDED.description,
+			  updated_at  = EXCLUDED.updated_at,
+			  signature   = EXCLUDED.signature,
+			  uris        = EXCLUDED.uris
+			WHERE credentials.user_id = EXCLUDED.user_id
+		`
+
+		for chunk := range slices.Chunk(p.Entities, batchChunkSize) {
+			var (
+				ids          = make([]uuid.UUID, len(chunk))
+				userIDs      = make([]uuid.UUID, len(chunk))
+				logins       = make([][]byte, len(chunk))
+				passwords    = make([][]byte, len(chunk))
+				descriptions = make([][]byte, len(chunk))
+				updatedAt    = make([]time.Time, len(chunk))
+				signatures   = make([][]byte, len(chunk))
+				uris         = make([][]byte, len(chunk))
+			)
+			for i, e := range chunk {
+				signature, err := signer.Sign(SignedTable, rowValues(e)...)
+				if err != nil {
+					return fmt.Errorf("failed to sign credential row %s: %w", e.ID, err)
+				}
+				ids[i], userIDs[i] = e.ID, e.UserID
+				logins[i] = e.Login
+				passwords[i] = e.Password
+				descriptions[i] = e.Description
+				updatedAt[i], signatures[i] = e.UpdatedAt, signature
+				uris[i] = e.URIs
+			}
+
+			res, err := db.Exec(
+				ctx, query, ids, userIDs, logins, passwords, descriptions, updatedAt, signatures, uris,
+			)
+			if err != nil {
+				return fmt.Errorf("query execution failed: %w", err)
+			}
+			affected, err := res.RowsAffected()
+			if err != nil {
+				return fmt.Errorf("failed to get affected rows: %w", err)
+			}
+			if affected != int64(len(chunk)) {
+				return fmt.Errorf("%d of %d credentials belong to another user", int64(len(chunk))-affected, len(chunk))
+			}
+		}
+		return nil
+	}
+}
+
 // RawLoad creates a function that performs raw database load operations for credentials.
 // Supports filtering by user ID and specific credential ID.
-func rawLoad(db db.DBClient) func(ctx context.Context, p LoadParams) ([]*credential.Credential, error) {
+func rawLoad(db db.DBClient, signer *rowsign.Signer) loadFunc {
 	return func(ctx context.Context, p LoadParams) ([]*credential.Credential, error) {
 		var (
 			queryBuilder strings.Builder
@@ -44,27 +135,46 @@ func rawLoad(db db.DBClient) func(ctx context.Context, p LoadParams) ([]*credent
 			argIdx       = 1
 		)
 
-		queryBuilder.WriteString(`
-			SELECT id, user_id, login, password, description, updated_at
-			FROM aegis_vault_keeper.credentials
-		`)
+		// columns lists the selected credential columns.
+		const columns = "id, user_id, login, password, description, updated_at, signature, uris"
+		source := "aegis_vault_keeper.credentials"
+		if !p.AsOf.IsZero() {
+			source = history.AsOfSource("credentials", columns, argIdx)
+			args = append(args, history.AsOfArg(p.AsOf))
+			argIdx++
+		}
+		queryBuilder.WriteString("SELECT " + columns + " FROM " + source)
 
 		if p.ID != uuid.Nil {
 			conditions = append(conditions, fmt.Sprintf("id = $%d", argIdx))
 			args = append(args, p.ID)
 			argIdx++
 		}
+		if p.IDs != nil {
+			conditions = append(conditions, fmt.Sprintf("id = ANY($%d::uuid[])", argIdx))
+			args = append(args, p.IDs)
+			argIdx++
+		}
 		if p.UserID != uuid.Nil {
 			conditions = append(conditions, fmt.Sprintf("user_id = $%d", argIdx))
 			args = append(args, p.UserID)
-			// argIdx++ // Last usage, no need to increment
+			argIdx++
 		}
 		if len(conditions) == 0 {
 			return nil, errors.New("at least one of ID or UserID must be provided")
 		}
+		if p.After != uuid.Nil {
+			conditions = append(conditions, fmt.Sprintf("id > $%d", argIdx))
+			args = append(args, p.After)
+			argIdx++
+		}
 
 		queryBuilder.WriteString(" WHERE ")
 		queryBuilder.WriteString(strings.Join(conditions, " AND "))
+		if p.Limit > 0 {
+			queryBuilder.WriteString(fmt.Sprintf(" ORDER BY id LIMIT $%d", argIdx))
+			args = append(args, p.Limit)
+		}
 
 		rows, err := db.Query(ctx, queryBuilder.String(), args...)
 		if err != nil {
@@ -77,6 +187,8 @@ func rawLoad(db db.DBClient) func(ctx context.Context, p LoadParams) ([]*credent
 		for rows.Next() {
 			// c holds a single credential entity during database row scanning.
 			var c credential.Credential
+			// signature holds the stored row integrity signature.
+			var signature []byte
 			if err := rows.Scan(
 				&c.ID,
 				&c.UserID,
@@ -84,9 +196,14 @@ func rawLoad(db db.DBClient) func(ctx context.Context, p LoadParams) ([]*credent
 				&c.Password,
 				&c.Description,
 				&c.UpdatedAt,
+				&signature,
+				&c.URIs,
 			); err != nil {
 				return nil, fmt.Errorf("failed to scan row: %w", err)
 			}
+			if err := signer.Verify(SignedTable, signature, rowValues(&c)...); err != nil {
+				return nil, fmt.Errorf("credential row %s failed integrity verification: %w", c.ID, err)
+			}
 			creds = append(creds, &c)
 		}
 		if err := rows.Err(); err != nil {
@@ -6,8 +6,10 @@ import (
 
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/credential"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/db"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/fieldcrypt"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/keyprv"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/middleware"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/rowsign"
 )
 
 // saveFunc defines the signature for credential save operations.
@@ -16,6 +18,12 @@ type saveFunc func(ctx context.Context, params SaveParams) error
 // saveMw is middleware for credential save operations.
 type saveMw = middleware.Middleware[saveFunc]
 
+// saveBatchFunc defines the signature for batch credential save operations.
+type saveBatchFunc func(ctx context.Context, params SaveBatchParams) error
+
+// saveBatchMw defines middleware for batch save operations.
+type saveBatchMw = middleware.Middleware[saveBatchFunc]
+
 // loadFunc defines the signature for credential load operations.
 type loadFunc func(ctx context.Context, params LoadParams) ([]*credential.Credential, error)
 
@@ -26,15 +34,40 @@ type loadMw = middleware.Middleware[loadFunc]
 type Repository struct {
 	// save is the function chain for saving credential data with encryption middleware.
 	save saveFunc
+	// saveBatch is the function chain for saving several credentials at once with encryption middleware.
+	saveBatch saveBatchFunc
 	// load is the function chain for loading credential data with decryption middleware.
 	load loadFunc
 }
 
 // NewRepository creates a new Repository with encryption/decryption middleware.
-func NewRepository(dbClient db.DBClient, keyProvider keyprv.UserKeyProvider) *Repository {
+// The items of bulk loads and saves are encrypted and decrypted concurrently on the pipeline.
+func NewRepository(
+	dbClient db.DBClient,
+	keyProvider keyprv.UserKeyProvider,
+	signer *rowsign.Signer,
+	retry middleware.RetryPolicy,
+	pipeline *fieldcrypt.Pipeline,
+) *Repository {
 	return &Repository{
-		save: middleware.Chain(rawSave(dbClient), encryptionMw(keyProvider)),
-		load: middleware.Chain(rawLoad(dbClient), decryptionMw(keyProvider)),
+		save: middleware.Chain(
+			rawSave(dbClient, signer),
+			middleware.RetryExecMw[saveFunc](retry),
+			userScopeSaveMw(dbClient),
+			encryptionMw(keyProvider),
+		),
+		saveBatch: middleware.Chain(
+			rawSaveBatch(dbClient, signer),
+			middleware.RetryExecMw[saveBatchFunc](retry),
+			userScopeSaveBatchMw(dbClient),
+			batchEncryptionMw(keyProvider, pipeline),
+		),
+		load: middleware.Chain(
+			rawLoad(dbClient, signer),
+			middleware.RetryQueryMw[loadFunc](retry),
+			userScopeLoadMw(dbClient),
+			decryptionMw(keyProvider, pipeline),
+		),
 	}
 }
 
@@ -46,6 +79,23 @@ func (r *Repository) Save(ctx context.Context, params SaveParams) error {
 	return nil
 }
 
+// SaveBatch persists several credentials of one user with automatic encryption of sensitive fields.
+// Large batches are written with a few multi-row statements instead of one statement per credential.
+func (r *Repository) SaveBatch(ctx context.Context, params SaveBatchParams) error {
+	if len(params.Entities) == 0 {
+		return nil
+	}
+	for _, e := range params.Entities {
+		if e.UserID != params.UserID {
+			return fmt.Errorf("credential %s does not belong to user %s", e.ID, params.UserID)
+		}
+	}
+	if err := r.saveBatch(ctx, params); err != nil {
+		return fmt.Errorf("failed to save credentials: %w", err)
+	}
+	return nil
+}
+
 // Load retrieves credentials with automatic decryption.
 func (r *Repository) Load(ctx context.Context, params LoadParams) ([]*credential.Credential, error) {
 	creds, err := r.load(ctx, params)
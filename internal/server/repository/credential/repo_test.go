@@ -8,6 +8,9 @@ import (
 	"time"
 
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/credential"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/fieldcrypt"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/middleware"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/rowsign"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -98,7 +101,7 @@ func TestNewRepository(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			repo := NewRepository(nil, nil)
+			repo := NewRepository(nil, nil, nil, middleware.RetryPolicy{}, nil)
 
 			assert.NotNil(t, repo)
 			assert.NotNil(t, repo.save)
@@ -182,7 +185,8 @@ func TestRepository_Save(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			repo := NewRepository(tt.dbClient, tt.keyProvider)
+			signer := rowsign.NewSigner([]byte("integrity-key"))
+			repo := NewRepository(tt.dbClient, tt.keyProvider, signer, middleware.RetryPolicy{}, nil)
 			err := repo.Save(context.Background(), tt.params)
 
 			if tt.expectedError != "" {
@@ -246,7 +250,8 @@ func TestRepository_Load(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			repo := NewRepository(tt.dbClient, tt.keyProvider)
+			signer := rowsign.NewSigner([]byte("integrity-key"))
+			repo := NewRepository(tt.dbClient, tt.keyProvider, signer, middleware.RetryPolicy{}, nil)
 			creds, err := repo.Load(context.Background(), tt.params)
 
 			if tt.expectedError != "" {
@@ -260,3 +265,185 @@ func TestRepository_Load(t *testing.T) {
 		})
 	}
 }
+
+func TestRepository_Load_AsOfQuery(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	itemID := uuid.New()
+	asOf := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		wantContains []string
+		wantArgs     []interface{}
+		params       LoadParams
+	}{
+		{
+			name:         "current versions",
+			params:       LoadParams{UserID: userID},
+			wantContains: []string{"FROM aegis_vault_keeper.credentials WHERE user_id = $1"},
+			wantArgs:     []interface{}{userID},
+		},
+		{
+			name:   "versions as of moment",
+			params: LoadParams{AsOf: asOf, ID: itemID, UserID: userID},
+			wantContains: []string{
+				"aegis_vault_keeper.credentials_history",
+				"WHERE updated_at <= $1",
+				"WHERE id = $2 AND user_id = $3",
+			},
+			wantArgs: []interface{}{asOf.In(time.Local), itemID, userID},
+		},
+		{
+			name:         "page after an item",
+			params:       LoadParams{After: itemID, Limit: 500, UserID: userID},
+			wantContains: []string{"WHERE user_id = $1 AND id > $2 ORDER BY id LIMIT $3"},
+			wantArgs:     []interface{}{userID, itemID, 500},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var gotQuery string
+			var gotArgs []interface{}
+			client := &mockDBClient{
+				queryFunc: func(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+					gotQuery, gotArgs = query, args
+					return nil, errors.New("query error")
+				},
+			}
+
+			_, err := rawLoad(client, rowsign.NewSigner([]byte("integrity-key")))(context.Background(), tt.params)
+			require.Error(t, err)
+			for _, want := range tt.wantContains {
+				assert.Contains(t, gotQuery, want)
+			}
+			assert.Equal(t, tt.wantArgs, gotArgs)
+		})
+	}
+}
+
+// affectedResult implements sql.Result reporting a fixed number of affected rows.
+type affectedResult int64
+
+func (r affectedResult) LastInsertId() (int64, error) { return 0, nil }
+func (r affectedResult) RowsAffected() (int64, error) { return int64(r), nil }
+
+func TestRepository_SaveBatch(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	makeItems := func(n int, owner uuid.UUID) []*credential.Credential {
+		items := make([]*credential.Credential, n)
+		for i := range items {
+			items[i] = &credential.Credential{ID: uuid.New(), UserID: owner, Login: []byte("user@example.com")}
+		}
+		return items
+	}
+
+	tests := []struct {
+		name          string
+		expectedError string
+		entities      []*credential.Credential
+		foreignRows   int64
+		wantExecs     int
+	}{
+		{name: "empty batch", wantExecs: 0},
+		{name: "single chunk", entities: makeItems(3, userID), wantExecs: 1},
+		{name: "several chunks", entities: makeItems(batchChunkSize+1, userID), wantExecs: 2},
+		{
+			name:          "rows of another user",
+			entities:      makeItems(2, userID),
+			foreignRows:   1,
+			wantExecs:     1,
+			expectedError: "1 of 2 credentials belong to another user",
+		},
+		{
+			name:          "entity of another user",
+			entities:      append(makeItems(1, userID), makeItems(1, uuid.New())...),
+			expectedError: "does not belong to user",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			execs := 0
+			dbClient := &mockDBClient{
+				execFunc: func(_ context.Context, _ string, args ...interface{}) (sql.Result, error) {
+					execs++
+					ids, ok := args[0].([]uuid.UUID)
+					require.True(t, ok)
+					assert.LessOrEqual(t, len(ids), batchChunkSize)
+					for _, arg := range args[2:] {
+						if column, ok := arg.([][]byte); ok {
+							assert.Len(t, column, len(ids))
+						}
+					}
+					return affectedResult(int64(len(ids)) - tt.foreignRows), nil
+				},
+			}
+			keyCalls := 0
+			keyProvider := &mockKeyProvider{
+				keyFunc: func(context.Context, uuid.UUID) ([]byte, error) {
+					keyCalls++
+					return []byte("12345678901234567890123456789012"), nil
+				},
+			}
+			signer := rowsign.NewSigner([]byte("integrity-key"))
+			repo := NewRepository(dbClient, keyProvider, signer, middleware.RetryPolicy{}, nil)
+
+			err := repo.SaveBatch(context.Background(), SaveBatchParams{Entities: tt.entities, UserID: userID})
+
+			assert.Equal(t, tt.wantExecs, execs)
+			if tt.wantExecs > 0 {
+				assert.Equal(t, 1, keyCalls)
+			}
+			if tt.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func BenchmarkRepository_SaveBatch(b *testing.B) {
+	userID := uuid.New()
+	creds := make([]*credential.Credential, 10_000)
+	for i := range creds {
+		creds[i] = &credential.Credential{
+			ID:          uuid.New(),
+			UserID:      userID,
+			Login:       []byte("user@example.com"),
+			Password:    []byte("correct horse battery staple"),
+			Description: []byte("Credential"),
+			UpdatedAt:   time.Now(),
+		}
+	}
+	dbClient := &mockDBClient{
+		execFunc: func(_ context.Context, _ string, args ...interface{}) (sql.Result, error) {
+			ids, _ := args[0].([]uuid.UUID)
+			return affectedResult(len(ids)), nil
+		},
+	}
+	keyProvider := &mockKeyProvider{
+		keyFunc: func(context.Context, uuid.UUID) ([]byte, error) {
+			return []byte("12345678901234567890123456789012"), nil
+		},
+	}
+	signer := rowsign.NewSigner([]byte("integrity-key"))
+	repo := NewRepository(dbClient, keyProvider, signer, middleware.RetryPolicy{}, fieldcrypt.NewPipeline(0))
+
+	b.ResetTimer()
+	for range b.N {
+		if err := repo.SaveBatch(context.Background(), SaveBatchParams{Entities: creds, UserID: userID}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
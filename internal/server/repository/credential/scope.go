@@ -0,0 +1,47 @@
+package credential
+
+import (
+	"context"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/credential"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/db"
+)
+
+// userScopeSaveMw creates middleware that runs credential saves within the owner's row-level security scope.
+func userScopeSaveMw(dbClient db.DBClient) saveMw {
+	return func(next saveFunc) saveFunc {
+		return func(ctx context.Context, p SaveParams) error {
+			return db.InUserScope(ctx, dbClient, p.Entity.UserID, func(ctx context.Context) error {
+				return next(ctx, p)
+			})
+		}
+	}
+}
+
+// userScopeSaveBatchMw creates middleware that runs batch credential saves within the owner's row-level
+// security scope.
+func userScopeSaveBatchMw(dbClient db.DBClient) saveBatchMw {
+	return func(next saveBatchFunc) saveBatchFunc {
+		return func(ctx context.Context, p SaveBatchParams) error {
+			return db.InUserScope(ctx, dbClient, p.UserID, func(ctx context.Context) error {
+				return next(ctx, p)
+			})
+		}
+	}
+}
+
+// userScopeLoadMw creates middleware that runs credential loads within the owner's row-level security scope.
+func userScopeLoadMw(dbClient db.DBClient) loadMw {
+	return func(next loadFunc) loadFunc {
+		return func(ctx context.Context, p LoadParams) ([]*credential.Credential, error) {
+			// entities holds the credentials loaded inside the user scope.
+			var entities []*credential.Credential
+			err := db.InUserScope(ctx, dbClient, p.UserID, func(ctx context.Context) error {
+				var err error
+				entities, err = next(ctx, p)
+				return err
+			})
+			return entities, err
+		}
+	}
+}
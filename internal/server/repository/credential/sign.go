@@ -0,0 +1,32 @@
+package credential
+
+import (
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/credential"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/rowsign"
+)
+
+// SignedTable describes the credentials table columns covered by row integrity signatures.
+// The uris column is left out: the sealed URI list is authenticated by its own AEAD binding to user and credential.
+var SignedTable = rowsign.Table{
+	Name: "credentials",
+	Columns: []rowsign.Column{
+		{Name: "id", Kind: rowsign.KindUUID},
+		{Name: "user_id", Kind: rowsign.KindUUID},
+		{Name: "login", Kind: rowsign.KindBytes},
+		{Name: "password", Kind: rowsign.KindBytes},
+		{Name: "description", Kind: rowsign.KindBytes},
+		{Name: "updated_at", Kind: rowsign.KindTime},
+	},
+}
+
+// rowValues returns the stored credential column values in SignedTable column order.
+func rowValues(e *credential.Credential) []any {
+	return []any{
+		e.ID,
+		e.UserID,
+		e.Login,
+		e.Password,
+		e.Description,
+		e.UpdatedAt,
+	}
+}
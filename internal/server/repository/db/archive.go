@@ -0,0 +1,27 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// skipArchiveQuery makes the item version trigger skip archiving for the rest of the current transaction.
+const skipArchiveQuery = "SELECT set_config('aegis_vault_keeper.skip_archive', 'on', true)"
+
+// WithoutArchiving runs fn within a transaction in which updated items are not archived as item versions, for
+// maintenance rewriting how items are stored without changing them. Clients without transaction support run fn
+// directly and archive as usual.
+func WithoutArchiving(ctx context.Context, client DBClient, fn func(ctx context.Context) error) error {
+	runner, ok := client.(TxRunner)
+	if !ok {
+		return fn(ctx)
+	}
+	return runner.RunInTx(ctx, uuid.Nil, func(ctx context.Context) error {
+		if _, err := client.Exec(ctx, skipArchiveQuery); err != nil {
+			return fmt.Errorf("failed to turn off item archiving: %w", err)
+		}
+		return fn(ctx)
+	})
+}
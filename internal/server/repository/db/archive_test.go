@@ -0,0 +1,80 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// archiveClient implements DBClient and TxRunner, recording the statements run inside transactions.
+type archiveClient struct {
+	plainClient
+	execErr error
+	queries []string
+	inTx    bool
+	txs     int
+}
+
+func (c *archiveClient) Exec(_ context.Context, query string, _ ...interface{}) (sql.Result, error) {
+	if c.inTx {
+		c.queries = append(c.queries, query)
+	}
+	return nil, c.execErr
+}
+
+func (c *archiveClient) RunInTx(ctx context.Context, _ uuid.UUID, fn func(ctx context.Context) error) error {
+	c.inTx = true
+	c.txs++
+	defer func() { c.inTx = false }()
+	return fn(ctx)
+}
+
+func TestWithoutArchiving(t *testing.T) {
+	t.Parallel()
+
+	t.Run("client_with_transactions", func(t *testing.T) {
+		t.Parallel()
+
+		client := &archiveClient{}
+		err := WithoutArchiving(context.Background(), client, func(ctx context.Context) error {
+			_, err := client.Exec(ctx, "UPDATE aegis_vault_keeper.notes SET note = $1")
+			return err
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, client.txs)
+		assert.Equal(t, []string{skipArchiveQuery, "UPDATE aegis_vault_keeper.notes SET note = $1"}, client.queries)
+	})
+
+	t.Run("setting_fails", func(t *testing.T) {
+		t.Parallel()
+
+		client := &archiveClient{execErr: errors.New("connection lost")}
+		called := false
+		err := WithoutArchiving(context.Background(), client, func(context.Context) error {
+			called = true
+			return nil
+		})
+
+		require.ErrorContains(t, err, "failed to turn off item archiving")
+		assert.False(t, called)
+	})
+
+	t.Run("client_without_transactions", func(t *testing.T) {
+		t.Parallel()
+
+		called := false
+		err := WithoutArchiving(context.Background(), plainClient{}, func(context.Context) error {
+			called = true
+			return nil
+		})
+
+		require.NoError(t, err)
+		assert.True(t, called)
+	})
+}
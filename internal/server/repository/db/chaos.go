@@ -0,0 +1,136 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/chaos"
+	"github.com/google/uuid"
+)
+
+// MetricChaosFaults counts the faults injected into database operations.
+const MetricChaosFaults = "chaos_repository_faults_injected_total"
+
+// ChaosRecorder defines the interface for counting injected faults.
+type ChaosRecorder interface {
+	// Add increments the named counter by delta.
+	Add(name string, delta int64)
+}
+
+// ChaosClient decorates a database client with fault injection, so every query, statement and
+// transaction start may be delayed or failed with chaos.ErrInjected.
+// Failed QueryRow calls report context.Canceled when scanned, because a *sql.Row cannot carry other errors.
+type ChaosClient struct {
+	// client runs the operations.
+	client DBClient
+	// recorder counts injected faults (nil disables counting).
+	recorder ChaosRecorder
+	// roll draws the numbers faults fire by (nil uses a pseudo-random source).
+	roll func() float64
+	// faults lists the latency and error faults injected into operations.
+	faults []chaos.Fault
+}
+
+// NewChaosClient creates a new ChaosClient injecting the faults into the operations of the client.
+func NewChaosClient(client DBClient, faults []chaos.Fault, roll func() float64, recorder ChaosRecorder) *ChaosClient {
+	return &ChaosClient{client: client, faults: faults, roll: roll, recorder: recorder}
+}
+
+// Exec executes a query that doesn't return rows after injecting faults.
+func (c *ChaosClient) Exec(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	if err := c.inject(ctx); err != nil {
+		return nil, err
+	}
+	return c.client.Exec(ctx, query, args...) // nolint:wrapcheck // Transparent client decorator
+}
+
+// QueryRow executes a query that returns at most one row after injecting faults.
+func (c *ChaosClient) QueryRow(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	if err := c.inject(ctx); err != nil {
+		canceled, cancel := context.WithCancelCause(ctx)
+		cancel(err)
+		return c.client.QueryRow(canceled, query, args...)
+	}
+	return c.client.QueryRow(ctx, query, args...)
+}
+
+// Query executes a query that returns multiple rows after injecting faults.
+func (c *ChaosClient) Query(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	if err := c.inject(ctx); err != nil {
+		return nil, err
+	}
+	return c.client.Query(ctx, query, args...) // nolint:wrapcheck // Transparent client decorator
+}
+
+// BeginTx starts a new database transaction after injecting faults.
+func (c *ChaosClient) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	if err := c.inject(ctx); err != nil {
+		return nil, err
+	}
+	return c.client.BeginTx(ctx, opts) // nolint:wrapcheck // Transparent client decorator
+}
+
+// CommitTx commits the specified transaction.
+func (c *ChaosClient) CommitTx(tx *sql.Tx) error {
+	return c.client.CommitTx(tx) // nolint:wrapcheck // Transparent client decorator
+}
+
+// RollbackTx rolls back the specified transaction.
+func (c *ChaosClient) RollbackTx(tx *sql.Tx) error {
+	return c.client.RollbackTx(tx) // nolint:wrapcheck // Transparent client decorator
+}
+
+// RunInTx executes fn inside a single transaction of the client after injecting faults, or directly
+// when the client has no transaction support.
+func (c *ChaosClient) RunInTx(ctx context.Context, userID uuid.UUID, fn func(ctx context.Context) error) error {
+	runner, ok := c.client.(TxRunner)
+	if !ok {
+		return fn(ctx)
+	}
+	if err := c.inject(ctx); err != nil {
+		return err
+	}
+	return runner.RunInTx(ctx, userID, fn) // nolint:wrapcheck // Transparent client decorator
+}
+
+// RunInUserScope executes fn within the user scope of the client.
+func (c *ChaosClient) RunInUserScope(
+	ctx context.Context,
+	userID uuid.UUID,
+	fn func(ctx context.Context) error,
+) error {
+	return InUserScope(ctx, c.client, userID, fn)
+}
+
+// RunInAdminScope executes fn within the admin scope of the client.
+func (c *ChaosClient) RunInAdminScope(ctx context.Context, fn func(ctx context.Context) error) error {
+	return InAdminScope(ctx, c.client, fn)
+}
+
+// inject applies the faults that fire to the current operation. Latency faults delay it until the
+// context ends; the first error fault fails it.
+func (c *ChaosClient) inject(ctx context.Context) error {
+	for _, f := range c.faults {
+		if !f.Fires(c.roll) {
+			continue
+		}
+		if c.recorder != nil {
+			c.recorder.Add(MetricChaosFaults, 1)
+		}
+		switch f.Kind {
+		case chaos.KindLatency:
+			t := time.NewTimer(f.Latency)
+			select {
+			case <-t.C:
+			case <-ctx.Done():
+				t.Stop()
+				return fmt.Errorf("injected latency interrupted: %w", ctx.Err())
+			}
+		case chaos.KindError:
+			return fmt.Errorf("database operation failed: %w", chaos.ErrInjected)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/chaos"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// chaosCounter counts metric increments by name.
+type chaosCounter struct {
+	counts map[string]int64
+	mu     sync.Mutex
+}
+
+func (r *chaosCounter) Add(name string, delta int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.counts == nil {
+		r.counts = make(map[string]int64)
+	}
+	r.counts[name] += delta
+}
+
+func TestChaosClient_Exec(t *testing.T) {
+	t.Parallel()
+
+	errorFault := chaos.Fault{Kind: chaos.KindError, Probability: 0.5}
+	latencyFault := chaos.Fault{Kind: chaos.KindLatency, Probability: 1, Latency: 20 * time.Millisecond}
+
+	tests := []struct {
+		wantErr     error
+		wantCounts  map[string]int64
+		name        string
+		faults      []chaos.Fault
+		roll        float64
+		cancel      bool
+		wantLatency time.Duration
+	}{
+		{
+			name: "no faults",
+		},
+		{
+			name:       "error fired",
+			faults:     []chaos.Fault{errorFault},
+			roll:       0.2,
+			wantErr:    chaos.ErrInjected,
+			wantCounts: map[string]int64{MetricChaosFaults: 1},
+		},
+		{
+			name:   "error not fired",
+			faults: []chaos.Fault{errorFault},
+			roll:   0.8,
+		},
+		{
+			name:        "latency",
+			faults:      []chaos.Fault{latencyFault},
+			wantLatency: 20 * time.Millisecond,
+			wantCounts:  map[string]int64{MetricChaosFaults: 1},
+		},
+		{
+			name:       "latency interrupted",
+			faults:     []chaos.Fault{latencyFault},
+			cancel:     true,
+			wantErr:    context.Canceled,
+			wantCounts: map[string]int64{MetricChaosFaults: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			recorder := &chaosCounter{}
+			c := NewChaosClient(plainClient{}, tt.faults, func() float64 { return tt.roll }, recorder)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tt.cancel {
+				cancel()
+			}
+
+			start := time.Now()
+			_, err := c.Exec(ctx, "SELECT 1")
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.GreaterOrEqual(t, time.Since(start), tt.wantLatency)
+			assert.Equal(t, tt.wantCounts, recorder.counts)
+		})
+	}
+}
+
+func TestChaosClient_RunInTx(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	fault := chaos.Fault{Kind: chaos.KindError, Probability: 1}
+
+	client := &txClient{}
+	c := NewChaosClient(client, []chaos.Fault{fault}, nil, nil)
+	err := c.RunInTx(context.Background(), userID, func(context.Context) error { return nil })
+	require.ErrorIs(t, err, chaos.ErrInjected)
+	assert.Zero(t, client.txs, "a failed transaction start should not run the transaction")
+
+	c = NewChaosClient(client, nil, nil, nil)
+	require.NoError(t, c.RunInTx(context.Background(), userID, func(context.Context) error { return nil }))
+	assert.Equal(t, 1, client.txs)
+	assert.Equal(t, userID, client.txUser)
+
+	fnErr := errors.New("fn failed")
+	c = NewChaosClient(plainClient{}, []chaos.Fault{fault}, nil, nil)
+	err = c.RunInTx(context.Background(), userID, func(context.Context) error { return fnErr })
+	assert.ErrorIs(t, err, fnErr, "clients without transactions should run fn directly")
+}
+
+func TestChaosClient_RunInUserScope(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	client := &scopedClient{}
+	c := NewChaosClient(client, nil, nil, nil)
+
+	require.NoError(t, c.RunInUserScope(context.Background(), userID, func(context.Context) error { return nil }))
+	assert.Equal(t, userID, client.scopedUser)
+}
+
+func TestChaosClient_RunInAdminScope(t *testing.T) {
+	t.Parallel()
+
+	client := &scopedClient{}
+	c := NewChaosClient(client, nil, nil, nil)
+
+	require.NoError(t, c.RunInAdminScope(context.Background(), func(context.Context) error { return nil }))
+	assert.True(t, client.adminScope)
+}
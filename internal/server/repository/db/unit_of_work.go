@@ -0,0 +1,166 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/dryrun"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/middleware"
+	"github.com/google/uuid"
+)
+
+// TxRunner defines database clients able to run several operations in one transaction.
+type TxRunner interface {
+	// RunInTx executes fn inside a single transaction scoped to the specified user.
+	RunInTx(ctx context.Context, userID uuid.UUID, fn func(ctx context.Context) error) error
+}
+
+// errDryRunRollback makes the transaction of a dry run roll back after fn succeeds.
+var errDryRunRollback = errors.New("dry run rolled back")
+
+// uowCtxKey is the context key under which the state of the active unit of work is stored.
+type uowCtxKey struct{}
+
+// uowState collects the deferred actions of an active unit of work.
+type uowState struct {
+	// onRollback holds the actions registered by OnRollback in registration order.
+	onRollback []func(ctx context.Context) error
+	// onCommit holds the actions registered by OnCommit in registration order.
+	onCommit []func(ctx context.Context) error
+	// mu guards onRollback and onCommit.
+	mu sync.Mutex
+}
+
+// UnitOfWork composes the operations of several repositories into a single transaction.
+// Repository operations run within Do join its transaction instead of opening their own, so they
+// commit together or roll back together.
+type UnitOfWork struct {
+	// client runs the transactions of units of work.
+	client DBClient
+	// retry defines how units of work failing on serialization conflicts or deadlocks are re-run.
+	retry middleware.RetryPolicy
+}
+
+// NewUnitOfWork creates a new UnitOfWork over the database client with the retry policy.
+func NewUnitOfWork(client DBClient, retry middleware.RetryPolicy) *UnitOfWork {
+	return &UnitOfWork{client: client, retry: retry}
+}
+
+// Do executes fn as one transaction scoped to userID. The transaction commits when fn succeeds and
+// rolls back otherwise, running the actions registered by OnRollback in reverse order. The whole
+// unit is re-run per the retry policy on serialization conflicts or deadlocks. Once the transaction
+// has committed, the actions registered by OnCommit run in registration order; their errors are
+// returned, but the transaction stays committed. Nested calls join the outer unit; clients without
+// transaction support run fn directly.
+func (u *UnitOfWork) Do(ctx context.Context, userID uuid.UUID, fn func(ctx context.Context) error) error {
+	runner, ok := u.client.(TxRunner)
+	if !ok || ctx.Value(uowCtxKey{}) != nil {
+		return fn(ctx)
+	}
+
+	// state holds the deferred actions of the attempt that committed.
+	var state *uowState
+	err := middleware.Retry(ctx, u.retry, func(ctx context.Context) error {
+		state = &uowState{}
+		err := runner.RunInTx(context.WithValue(ctx, uowCtxKey{}, state), userID, fn)
+		if err != nil {
+			if rbErr := state.rollback(context.WithoutCancel(ctx)); rbErr != nil {
+				return errors.Join(err, rbErr)
+			}
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return state.commit(context.WithoutCancel(ctx))
+}
+
+// DryRun executes fn as one transaction scoped to userID that always rolls back, running the actions
+// registered by OnRollback in reverse order, so fn is checked against the database without taking
+// effect. Repository operations and units of work within fn join its transaction, and the context of
+// fn is marked for dryrun.Enabled. Clients without transaction support get dryrun.ErrUnsupported
+// and fn does not run.
+func (u *UnitOfWork) DryRun(ctx context.Context, userID uuid.UUID, fn func(ctx context.Context) error) error {
+	runner, ok := u.client.(TxRunner)
+	if !ok {
+		return dryrun.ErrUnsupported
+	}
+
+	state := &uowState{}
+	ctx = context.WithValue(dryrun.With(ctx), uowCtxKey{}, state)
+	err := runner.RunInTx(ctx, userID, func(ctx context.Context) error {
+		if err := fn(ctx); err != nil {
+			return err
+		}
+		return errDryRunRollback
+	})
+	if errors.Is(err, errDryRunRollback) {
+		err = nil
+	}
+	if rbErr := state.rollback(context.WithoutCancel(ctx)); rbErr != nil {
+		return errors.Join(err, rbErr)
+	}
+	return err
+}
+
+// OnRollback registers fn to undo effects outside the database, such as stored files, when the
+// unit of work in ctx rolls back. It reports false when ctx belongs to no unit of work.
+func OnRollback(ctx context.Context, fn func(ctx context.Context) error) bool {
+	state, ok := ctx.Value(uowCtxKey{}).(*uowState)
+	if !ok {
+		return false
+	}
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	state.onRollback = append(state.onRollback, fn)
+	return true
+}
+
+// OnCommit registers fn to finish effects outside the database, such as removing replaced files,
+// once the unit of work in ctx has committed. The actions never run when the unit rolls back or is a
+// dry run. It reports false when ctx belongs to no unit of work.
+func OnCommit(ctx context.Context, fn func(ctx context.Context) error) bool {
+	state, ok := ctx.Value(uowCtxKey{}).(*uowState)
+	if !ok {
+		return false
+	}
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	state.onCommit = append(state.onCommit, fn)
+	return true
+}
+
+// commit runs the actions registered by OnCommit in registration order and joins their errors.
+func (s *uowState) commit(ctx context.Context) error {
+	s.mu.Lock()
+	actions := slices.Clone(s.onCommit)
+	s.mu.Unlock()
+
+	var errs []error
+	for _, fn := range actions {
+		if err := fn(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("commit action failed: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// rollback runs the registered actions in reverse order and joins their errors.
+func (s *uowState) rollback(ctx context.Context) error {
+	s.mu.Lock()
+	actions := slices.Clone(s.onRollback)
+	s.mu.Unlock()
+
+	var errs []error
+	for _, fn := range slices.Backward(actions) {
+		if err := fn(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("rollback action failed: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,260 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/dryrun"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/middleware"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// txClient implements DBClient and TxRunner, counting the transactions it runs.
+type txClient struct {
+	plainClient
+	txUser uuid.UUID
+	txs    int
+}
+
+func (c *txClient) RunInTx(ctx context.Context, userID uuid.UUID, fn func(ctx context.Context) error) error {
+	c.txs++
+	c.txUser = userID
+	return fn(ctx)
+}
+
+func TestUnitOfWork_Do(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	fnErr := errors.New("fn failed")
+	rollbackErr := errors.New("rollback failed")
+
+	tests := []struct {
+		fnErr        error
+		rollbackErr  error
+		name         string
+		wantRollback []string
+		wantTxs      int
+	}{
+		{name: "commit", wantTxs: 1},
+		{name: "rollback runs actions in reverse order", fnErr: fnErr, wantRollback: []string{"second", "first"}, wantTxs: 1},
+		{
+			name: "rollback action error joined", fnErr: fnErr, rollbackErr: rollbackErr,
+			wantRollback: []string{"second", "first"}, wantTxs: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := &txClient{}
+			var rolledBack []string
+			err := NewUnitOfWork(client, middleware.RetryPolicy{}).Do(
+				context.Background(),
+				userID,
+				func(ctx context.Context) error {
+					for _, name := range []string{"first", "second"} {
+						registered := OnRollback(ctx, func(context.Context) error {
+							rolledBack = append(rolledBack, name)
+							return tt.rollbackErr
+						})
+						assert.True(t, registered)
+					}
+					return tt.fnErr
+				},
+			)
+
+			assert.Equal(t, tt.wantTxs, client.txs)
+			assert.Equal(t, userID, client.txUser)
+			assert.Equal(t, tt.wantRollback, rolledBack)
+			if tt.fnErr == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, tt.fnErr)
+			if tt.rollbackErr != nil {
+				require.ErrorIs(t, err, tt.rollbackErr)
+			}
+		})
+	}
+}
+
+func TestUnitOfWork_DoRetry(t *testing.T) {
+	t.Parallel()
+
+	client := &txClient{}
+	attempts, rollbacks := 0, 0
+	err := NewUnitOfWork(client, middleware.RetryPolicy{MaxRetries: 2}).Do(
+		context.Background(),
+		uuid.New(),
+		func(ctx context.Context) error {
+			attempts++
+			OnRollback(ctx, func(context.Context) error {
+				rollbacks++
+				return nil
+			})
+			if attempts == 1 {
+				return &pgconn.PgError{Code: "40P01"}
+			}
+			return nil
+		},
+	)
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, client.txs)
+	assert.Equal(t, 1, rollbacks)
+}
+
+func TestUnitOfWork_DoOnCommit(t *testing.T) {
+	t.Parallel()
+
+	fnErr := errors.New("fn failed")
+	commitErr := errors.New("commit action failed")
+
+	tests := []struct {
+		fnErr      error
+		commitErr  error
+		name       string
+		wantCommit []string
+		failFirst  bool
+	}{
+		{name: "commit runs actions in order", wantCommit: []string{"first", "second"}},
+		{name: "rollback skips actions", fnErr: fnErr},
+		{name: "retried unit runs actions of committed attempt", failFirst: true, wantCommit: []string{"first", "second"}},
+		{name: "action error returned", commitErr: commitErr, wantCommit: []string{"first", "second"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			attempts := 0
+			var committed []string
+			err := NewUnitOfWork(&txClient{}, middleware.RetryPolicy{MaxRetries: 1}).Do(
+				context.Background(),
+				uuid.New(),
+				func(ctx context.Context) error {
+					attempts++
+					for _, name := range []string{"first", "second"} {
+						assert.True(t, OnCommit(ctx, func(context.Context) error {
+							committed = append(committed, name)
+							return tt.commitErr
+						}))
+					}
+					if tt.failFirst && attempts == 1 {
+						return &pgconn.PgError{Code: "40P01"}
+					}
+					return tt.fnErr
+				},
+			)
+
+			assert.Equal(t, tt.wantCommit, committed)
+			switch {
+			case tt.fnErr != nil:
+				require.ErrorIs(t, err, tt.fnErr)
+			case tt.commitErr != nil:
+				require.ErrorIs(t, err, tt.commitErr)
+			default:
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestUnitOfWork_DoNested(t *testing.T) {
+	t.Parallel()
+
+	client := &txClient{}
+	uow := NewUnitOfWork(client, middleware.RetryPolicy{})
+	err := uow.Do(context.Background(), uuid.New(), func(ctx context.Context) error {
+		return uow.Do(ctx, uuid.New(), func(context.Context) error { return nil })
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, client.txs)
+}
+
+func TestUnitOfWork_DoWithoutTxSupport(t *testing.T) {
+	t.Parallel()
+
+	called := false
+	err := NewUnitOfWork(plainClient{}, middleware.RetryPolicy{}).Do(
+		context.Background(),
+		uuid.New(),
+		func(ctx context.Context) error {
+			called = true
+			assert.False(t, OnRollback(ctx, func(context.Context) error { return nil }))
+			assert.False(t, OnCommit(ctx, func(context.Context) error { return nil }))
+			return nil
+		},
+	)
+
+	require.NoError(t, err)
+	assert.True(t, called)
+}
+
+func TestUnitOfWork_DryRun(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	fnErr := errors.New("fn failed")
+
+	tests := []struct {
+		fnErr   error
+		client  DBClient
+		wantErr error
+		name    string
+		wantTxs int
+		wantRan bool
+	}{
+		{name: "success rolls back", client: &txClient{}, wantTxs: 1, wantRan: true},
+		{name: "failure rolls back", client: &txClient{}, fnErr: fnErr, wantErr: fnErr, wantTxs: 1, wantRan: true},
+		{name: "no transaction support", client: plainClient{}, wantErr: dryrun.ErrUnsupported},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			uow := NewUnitOfWork(tt.client, middleware.RetryPolicy{})
+			ran, rollbacks, commits := false, 0, 0
+			err := uow.DryRun(context.Background(), userID, func(ctx context.Context) error {
+				ran = true
+				assert.True(t, dryrun.Enabled(ctx))
+				// Units of work within the dry run join its transaction and its rollback.
+				require.NoError(t, uow.Do(ctx, userID, func(ctx context.Context) error {
+					OnRollback(ctx, func(context.Context) error {
+						rollbacks++
+						return nil
+					})
+					OnCommit(ctx, func(context.Context) error {
+						commits++
+						return nil
+					})
+					return nil
+				}))
+				return tt.fnErr
+			})
+
+			assert.Equal(t, tt.wantRan, ran)
+			if tt.wantRan {
+				assert.Equal(t, 1, rollbacks)
+				assert.Zero(t, commits, "commit actions never run in a dry run")
+			}
+			if client, ok := tt.client.(*txClient); ok {
+				assert.Equal(t, tt.wantTxs, client.txs)
+				assert.Equal(t, userID, client.txUser)
+			}
+			if tt.wantErr == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package db
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// UserScopeRunner defines database clients able to bind a unit of work to a single user.
+// Implementations set the row-level security user scope so policies restrict visible rows.
+type UserScopeRunner interface {
+	// RunInUserScope executes fn inside a transaction scoped to the specified user.
+	RunInUserScope(ctx context.Context, userID uuid.UUID, fn func(ctx context.Context) error) error
+}
+
+// InUserScope runs fn within the user scope when the client supports it, or directly otherwise.
+func InUserScope(
+	ctx context.Context,
+	client DBClient,
+	userID uuid.UUID,
+	fn func(ctx context.Context) error,
+) error {
+	if runner, ok := client.(UserScopeRunner); ok {
+		return runner.RunInUserScope(ctx, userID, fn)
+	}
+	return fn(ctx)
+}
+
+// AdminScopeRunner defines database clients able to run instance-wide work across all users.
+// Implementations set the row-level security admin scope so policies expose the rows of every user.
+type AdminScopeRunner interface {
+	// RunInAdminScope executes fn inside a transaction exposing the rows of every user.
+	RunInAdminScope(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// InAdminScope runs fn within the admin scope when the client supports it, or directly otherwise.
+func InAdminScope(ctx context.Context, client DBClient, fn func(ctx context.Context) error) error {
+	if runner, ok := client.(AdminScopeRunner); ok {
+		return runner.RunInAdminScope(ctx, fn)
+	}
+	return fn(ctx)
+}
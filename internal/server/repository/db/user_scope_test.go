@@ -0,0 +1,137 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// plainClient implements DBClient without user scope support.
+type plainClient struct{}
+
+func (plainClient) Exec(context.Context, string, ...interface{}) (sql.Result, error) { return nil, nil }
+func (plainClient) QueryRow(context.Context, string, ...interface{}) *sql.Row        { return nil }
+func (plainClient) Query(context.Context, string, ...interface{}) (*sql.Rows, error) { return nil, nil }
+func (plainClient) BeginTx(context.Context, *sql.TxOptions) (*sql.Tx, error)         { return nil, nil }
+func (plainClient) CommitTx(*sql.Tx) error                                           { return nil }
+func (plainClient) RollbackTx(*sql.Tx) error                                         { return nil }
+
+// scopedClient implements DBClient, UserScopeRunner and AdminScopeRunner, recording the scope used.
+type scopedClient struct {
+	plainClient
+	scopedUser uuid.UUID
+	adminScope bool
+}
+
+func (s *scopedClient) RunInUserScope(
+	ctx context.Context,
+	userID uuid.UUID,
+	fn func(ctx context.Context) error,
+) error {
+	s.scopedUser = userID
+	return fn(ctx)
+}
+
+func (s *scopedClient) RunInAdminScope(ctx context.Context, fn func(ctx context.Context) error) error {
+	s.adminScope = true
+	return fn(ctx)
+}
+
+func TestInUserScope(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	fnErr := errors.New("fn failed")
+
+	tests := []struct {
+		client      DBClient
+		fnErr       error
+		name        string
+		expectScope bool
+	}{
+		{
+			name:        "client_with_scope_support",
+			client:      &scopedClient{},
+			expectScope: true,
+		},
+		{
+			name:   "client_without_scope_support",
+			client: plainClient{},
+		},
+		{
+			name:   "nil_client",
+			client: nil,
+		},
+		{
+			name:        "fn_error_propagated",
+			client:      &scopedClient{},
+			fnErr:       fnErr,
+			expectScope: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			called := false
+			err := InUserScope(context.Background(), tt.client, userID, func(ctx context.Context) error {
+				called = true
+				return tt.fnErr
+			})
+
+			assert.True(t, called)
+			if tt.fnErr != nil {
+				require.ErrorIs(t, err, tt.fnErr)
+			} else {
+				require.NoError(t, err)
+			}
+			if tt.expectScope {
+				sc, ok := tt.client.(*scopedClient)
+				require.True(t, ok)
+				assert.Equal(t, userID, sc.scopedUser)
+			}
+		})
+	}
+}
+
+func TestInAdminScope(t *testing.T) {
+	t.Parallel()
+
+	fnErr := errors.New("fn failed")
+
+	tests := []struct {
+		client      DBClient
+		name        string
+		expectScope bool
+	}{
+		{name: "client_with_scope_support", client: &scopedClient{}, expectScope: true},
+		{name: "client_without_scope_support", client: plainClient{}},
+		{name: "nil_client", client: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			called := false
+			err := InAdminScope(context.Background(), tt.client, func(ctx context.Context) error {
+				called = true
+				return fnErr
+			})
+
+			assert.True(t, called)
+			require.ErrorIs(t, err, fnErr)
+			if tt.expectScope {
+				sc, ok := tt.client.(*scopedClient)
+				require.True(t, ok)
+				assert.True(t, sc.adminScope)
+			}
+		})
+	}
+}
@@ -0,0 +1,5 @@
+// Package device provides login device persistence for the AegisVaultKeeper server.
+//
+// This package implements storage of user login fingerprints and their pending
+// step-up verification challenges in PostgreSQL.
+package device
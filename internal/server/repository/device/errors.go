@@ -0,0 +1,6 @@
+package device
+
+import "errors"
+
+// ErrDeviceNotFound indicates that the requested device was not found in the repository.
+var ErrDeviceNotFound = errors.New("device not found")
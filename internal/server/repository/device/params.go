@@ -0,0 +1,21 @@
+package device
+
+import (
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/device"
+	"github.com/google/uuid"
+)
+
+// SaveParams contains the parameters for saving a device to the repository.
+type SaveParams struct {
+	// Entity contains the device to be created or updated.
+	Entity *device.Device
+}
+
+// LoadParams contains the parameters for loading devices from the repository.
+// ID takes precedence over UserID.
+type LoadParams struct {
+	// ID selects a single device.
+	ID uuid.UUID
+	// UserID selects all devices of the specified user.
+	UserID uuid.UUID
+}
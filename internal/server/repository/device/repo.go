@@ -0,0 +1,130 @@
+package device
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/netip"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/device"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/db"
+	"github.com/google/uuid"
+)
+
+// Repository provides login device persistence operations.
+type Repository struct {
+	// db is the database client used for device operations.
+	db db.DBClient
+}
+
+// NewRepository creates a new Repository with the provided database client.
+func NewRepository(dbClient db.DBClient) *Repository {
+	return &Repository{db: dbClient}
+}
+
+// Save creates the device or updates its trust state, latest login, pending challenge and approval.
+func (r *Repository) Save(ctx context.Context, params SaveParams) error {
+	e := params.Entity
+
+	var ip sql.NullString
+	if e.IP.IsValid() {
+		ip = sql.NullString{String: e.IP.String(), Valid: true}
+	}
+	var expiresAt sql.NullTime
+	if !e.ChallengeExpiresAt.IsZero() {
+		expiresAt = sql.NullTime{Time: e.ChallengeExpiresAt, Valid: true}
+	}
+
+	query := `
+		INSERT INTO aegis_vault_keeper.auth_devices (
+			id, user_id, fingerprint, user_agent, ip, location, trusted,
+			challenge_hash, challenge_expires_at, challenge_attempts, approval_hash, approved,
+			created_at, last_seen_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		ON CONFLICT (id) DO UPDATE SET
+			ip = EXCLUDED.ip,
+			trusted = EXCLUDED.trusted,
+			challenge_hash = EXCLUDED.challenge_hash,
+			challenge_expires_at = EXCLUDED.challenge_expires_at,
+			challenge_attempts = EXCLUDED.challenge_attempts,
+			approval_hash = EXCLUDED.approval_hash,
+			approved = EXCLUDED.approved,
+			last_seen_at = EXCLUDED.last_seen_at
+	`
+	if _, err := r.db.Exec(ctx, query,
+		e.ID, e.UserID, e.Fingerprint, e.UserAgent, ip, e.Location, e.Trusted,
+		e.ChallengeHash, expiresAt, e.ChallengeAttempts, e.ApprovalHash, e.Approved, e.CreatedAt, e.LastSeenAt,
+	); err != nil {
+		return fmt.Errorf("failed to save device: %w", err)
+	}
+	return nil
+}
+
+// Load retrieves the devices matching the provided parameters ordered by creation time.
+// Loading by ID returns ErrDeviceNotFound when the device does not exist.
+func (r *Repository) Load(ctx context.Context, params LoadParams) ([]*device.Device, error) {
+	query := `
+		SELECT id, user_id, fingerprint, user_agent, host(ip), location, trusted,
+			challenge_hash, challenge_expires_at, challenge_attempts, approval_hash, approved,
+			created_at, last_seen_at
+		FROM aegis_vault_keeper.auth_devices
+	`
+	arg := any(params.UserID)
+	if params.ID != uuid.Nil {
+		query += " WHERE id = $1"
+		arg = params.ID
+	} else {
+		query += " WHERE user_id = $1"
+	}
+	query += " ORDER BY created_at, id"
+
+	rows, err := r.db.Query(ctx, query, arg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load devices: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var devices []*device.Device
+	for rows.Next() {
+		d, err := scanDevice(rows)
+		if err != nil {
+			return nil, err
+		}
+		devices = append(devices, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate devices: %w", err)
+	}
+	if params.ID != uuid.Nil && len(devices) == 0 {
+		return nil, ErrDeviceNotFound
+	}
+	return devices, nil
+}
+
+// scanDevice converts the current row into a device entity.
+func scanDevice(rows *sql.Rows) (*device.Device, error) {
+	var (
+		d         device.Device
+		ip        sql.NullString
+		expiresAt sql.NullTime
+	)
+	if err := rows.Scan(
+		&d.ID, &d.UserID, &d.Fingerprint, &d.UserAgent, &ip, &d.Location, &d.Trusted,
+		&d.ChallengeHash, &expiresAt, &d.ChallengeAttempts, &d.ApprovalHash, &d.Approved, &d.CreatedAt, &d.LastSeenAt,
+	); err != nil {
+		return nil, fmt.Errorf("failed to scan device: %w", err)
+	}
+
+	if ip.Valid {
+		addr, err := netip.ParseAddr(ip.String)
+		if err != nil {
+			return nil, fmt.Errorf("invalid address of device %s: %w", d.ID, err)
+		}
+		d.IP = addr
+	}
+	if expiresAt.Valid {
+		d.ChallengeExpiresAt = expiresAt.Time
+	}
+	return &d, nil
+}
@@ -0,0 +1,178 @@
+package device
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/device"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/db"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockResult implements sql.Result for testing.
+type mockResult struct{}
+
+func (m mockResult) LastInsertId() (int64, error) { return 0, nil }
+func (m mockResult) RowsAffected() (int64, error) { return 1, nil }
+
+// mockDBClient implements db.DBClient for testing.
+type mockDBClient struct {
+	execFunc  func(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	queryFunc func(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+func (m *mockDBClient) Exec(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	if m.execFunc != nil {
+		return m.execFunc(ctx, query, args...)
+	}
+	return mockResult{}, nil
+}
+
+func (m *mockDBClient) Query(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	if m.queryFunc != nil {
+		return m.queryFunc(ctx, query, args...)
+	}
+	return nil, errors.New("mock not configured")
+}
+
+func (m *mockDBClient) QueryRow(context.Context, string, ...interface{}) *sql.Row {
+	return nil
+}
+
+func (m *mockDBClient) BeginTx(context.Context, *sql.TxOptions) (*sql.Tx, error) {
+	return nil, errors.New("mock not configured")
+}
+
+func (m *mockDBClient) CommitTx(*sql.Tx) error {
+	return nil
+}
+
+func (m *mockDBClient) RollbackTx(*sql.Tx) error {
+	return nil
+}
+
+// Ensure mockDBClient implements db.DBClient.
+var _ db.DBClient = (*mockDBClient)(nil)
+
+func TestRepository_Save(t *testing.T) {
+	t.Parallel()
+
+	deviceID, userID := uuid.New(), uuid.New()
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	expiresAt := now.Add(10 * time.Minute)
+
+	tests := []struct {
+		execErr  error
+		device   *device.Device
+		name     string
+		wantArgs []interface{}
+	}{
+		{
+			name: "trusted device",
+			device: &device.Device{
+				ID: deviceID, UserID: userID, Fingerprint: "fp", UserAgent: "Firefox",
+				IP: netip.MustParseAddr("203.0.113.7"), Location: "DE", Trusted: true,
+				CreatedAt: now, LastSeenAt: now,
+			},
+			wantArgs: []interface{}{
+				deviceID, userID, "fp", "Firefox", sql.NullString{String: "203.0.113.7", Valid: true}, "DE", true,
+				[]byte(nil), sql.NullTime{}, 0, []byte(nil), false, now, now,
+			},
+		},
+		{
+			name: "pending device without address",
+			device: &device.Device{
+				ID: deviceID, UserID: userID, Fingerprint: "fp", ChallengeHash: []byte{1}, ApprovalHash: []byte{2},
+				ChallengeExpiresAt: expiresAt, ChallengeAttempts: 2, Approved: true, CreatedAt: now, LastSeenAt: now,
+			},
+			wantArgs: []interface{}{
+				deviceID, userID, "fp", "", sql.NullString{}, "", false,
+				[]byte{1}, sql.NullTime{Time: expiresAt, Valid: true}, 2, []byte{2}, true, now, now,
+			},
+		},
+		{
+			name:    "exec error",
+			device:  &device.Device{ID: deviceID, UserID: userID},
+			execErr: errors.New("connection refused"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var gotArgs []interface{}
+			client := &mockDBClient{
+				execFunc: func(_ context.Context, query string, args ...interface{}) (sql.Result, error) {
+					assert.Contains(t, query, "INSERT INTO aegis_vault_keeper.auth_devices")
+					assert.Contains(t, query, "ON CONFLICT (id) DO UPDATE")
+					gotArgs = args
+					return mockResult{}, tt.execErr
+				},
+			}
+
+			err := NewRepository(client).Save(context.Background(), SaveParams{Entity: tt.device})
+
+			if tt.execErr != nil {
+				assert.ErrorIs(t, err, tt.execErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantArgs, gotArgs)
+		})
+	}
+}
+
+func TestRepository_LoadQuery(t *testing.T) {
+	t.Parallel()
+
+	deviceID, userID := uuid.New(), uuid.New()
+
+	tests := []struct {
+		name      string
+		wantWhere string
+		wantArg   uuid.UUID
+		params    LoadParams
+	}{
+		{
+			name:      "by user",
+			params:    LoadParams{UserID: userID},
+			wantWhere: "WHERE user_id = $1",
+			wantArg:   userID,
+		},
+		{
+			name:      "by id",
+			params:    LoadParams{ID: deviceID, UserID: userID},
+			wantWhere: "WHERE id = $1",
+			wantArg:   deviceID,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			queryErr := errors.New("query error")
+			var gotQuery string
+			var gotArgs []interface{}
+			client := &mockDBClient{
+				queryFunc: func(_ context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+					gotQuery, gotArgs = query, args
+					return nil, queryErr
+				},
+			}
+
+			_, err := NewRepository(client).Load(context.Background(), tt.params)
+
+			require.ErrorIs(t, err, queryErr)
+			assert.Contains(t, gotQuery, tt.wantWhere)
+			assert.Equal(t, []interface{}{tt.wantArg}, gotArgs)
+		})
+	}
+}
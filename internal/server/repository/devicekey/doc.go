@@ -0,0 +1,5 @@
+// Package devicekey provides device key persistence for the AegisVaultKeeper server.
+//
+// This package implements storage of the X25519 public keys of user devices in PostgreSQL,
+// sealed under the key of their owner, so a key replaced in the database fails to open.
+package devicekey
@@ -0,0 +1,6 @@
+package devicekey
+
+import "errors"
+
+// ErrDeviceKeyNotFound indicates that the requested device key was not found in the repository.
+var ErrDeviceKeyNotFound = errors.New("device key not found")
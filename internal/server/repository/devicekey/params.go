@@ -0,0 +1,28 @@
+package devicekey
+
+import (
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/devicekey"
+	"github.com/google/uuid"
+)
+
+// SaveParams contains the parameters for saving a device key to the repository.
+type SaveParams struct {
+	// Entity contains the device key to be created.
+	Entity *devicekey.DeviceKey
+}
+
+// LoadParams contains the parameters for loading device keys from the repository.
+type LoadParams struct {
+	// ID selects a single key of the user; uuid.Nil loads all of them.
+	ID uuid.UUID
+	// UserID identifies the owner of the keys.
+	UserID uuid.UUID
+}
+
+// DeleteParams contains the parameters for deleting a device key from the repository.
+type DeleteParams struct {
+	// ID identifies the key to delete.
+	ID uuid.UUID
+	// UserID identifies the owner of the key.
+	UserID uuid.UUID
+}
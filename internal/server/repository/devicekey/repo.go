@@ -0,0 +1,138 @@
+package devicekey
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/devicekey"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/db"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/fieldcrypt"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/keyprv"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/securebytes"
+	"github.com/google/uuid"
+)
+
+// itemType identifies device keys in the ownership binding of their sealed public keys.
+const itemType = "device_key"
+
+// Repository provides device key persistence operations.
+type Repository struct {
+	// db is the database client used for device key operations.
+	db db.DBClient
+	// keyProvider provides the user keys the public keys are sealed with.
+	keyProvider keyprv.UserKeyProvider
+}
+
+// NewRepository creates a new Repository with the provided database client and user key provider.
+func NewRepository(dbClient db.DBClient, keyProvider keyprv.UserKeyProvider) *Repository {
+	return &Repository{db: dbClient, keyProvider: keyProvider}
+}
+
+// Save creates the device key, sealing its public key with the key of the owner. The public key is not secret,
+// but sealing authenticates it, so payloads are never encrypted for a key replaced in the database.
+func (r *Repository) Save(ctx context.Context, params SaveParams) error {
+	e := params.Entity
+
+	k, err := r.keyProvider.UserKeyProvide(ctx, e.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to provide user key: %w", err)
+	}
+	defer securebytes.Wipe(k)
+
+	b := fieldcrypt.Binding{ItemType: itemType, UserID: e.UserID, ItemID: e.ID}
+	publicKey, err := b.Seal(k, "public_key", e.PublicKey)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt public key: %w", err)
+	}
+
+	query := `
+		INSERT INTO aegis_vault_keeper.device_keys (id, user_id, name, public_key, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+	if _, err := r.db.Exec(ctx, query, e.ID, e.UserID, e.Name, publicKey, e.CreatedAt); err != nil {
+		return fmt.Errorf("failed to save device key: %w", err)
+	}
+	return nil
+}
+
+// Load retrieves the device keys of the user with opened public keys ordered by registration time.
+// Loading by ID returns ErrDeviceKeyNotFound when the user has no such key.
+func (r *Repository) Load(ctx context.Context, params LoadParams) ([]*devicekey.DeviceKey, error) {
+	query := `
+		SELECT id, user_id, name, public_key, created_at
+		FROM aegis_vault_keeper.device_keys
+		WHERE user_id = $1
+	`
+	args := []any{params.UserID}
+	if params.ID != uuid.Nil {
+		query += " AND id = $2"
+		args = append(args, params.ID)
+	}
+	query += " ORDER BY created_at, id"
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load device keys: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var keys []*devicekey.DeviceKey
+	for rows.Next() {
+		var key devicekey.DeviceKey
+		if err := rows.Scan(&key.ID, &key.UserID, &key.Name, &key.PublicKey, &key.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan device key: %w", err)
+		}
+		keys = append(keys, &key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate device keys: %w", err)
+	}
+	if params.ID != uuid.Nil && len(keys) == 0 {
+		return nil, ErrDeviceKeyNotFound
+	}
+	if len(keys) == 0 {
+		return keys, nil
+	}
+
+	if err := r.open(ctx, params.UserID, keys); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// Delete removes the device key of the user.
+func (r *Repository) Delete(ctx context.Context, params DeleteParams) error {
+	query := `
+		DELETE FROM aegis_vault_keeper.device_keys
+		WHERE id = $1 AND user_id = $2
+	`
+	res, err := r.db.Exec(ctx, query, params.ID, params.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to delete device key: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check deleted device keys: %w", err)
+	}
+	if n == 0 {
+		return ErrDeviceKeyNotFound
+	}
+	return nil
+}
+
+// open replaces the sealed public keys of the keys with their plaintext.
+func (r *Repository) open(ctx context.Context, userID uuid.UUID, keys []*devicekey.DeviceKey) error {
+	k, err := r.keyProvider.UserKeyProvide(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to provide user key: %w", err)
+	}
+	defer securebytes.Wipe(k)
+
+	for _, key := range keys {
+		b := fieldcrypt.Binding{ItemType: itemType, UserID: userID, ItemID: key.ID}
+		if key.PublicKey, err = b.Open(k, "public_key", key.PublicKey); err != nil {
+			return fmt.Errorf("failed to decrypt public key: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,5 @@
+// Package feature provides feature flag persistence for the AegisVaultKeeper server.
+//
+// This package implements storage of deployment-wide and per-user feature flag
+// overrides in PostgreSQL.
+package feature
@@ -0,0 +1,6 @@
+package feature
+
+import "errors"
+
+// ErrFlagNotFound indicates that the requested feature flag was not found in the repository.
+var ErrFlagNotFound = errors.New("feature flag not found")
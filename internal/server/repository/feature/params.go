@@ -0,0 +1,29 @@
+package feature
+
+import (
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/feature"
+	"github.com/google/uuid"
+)
+
+// SaveParams contains the parameters for saving a feature flag to the repository.
+type SaveParams struct {
+	// Entity contains the feature flag to be persisted.
+	Entity *feature.Flag
+}
+
+// LoadParams contains the parameters for loading feature flags from the repository.
+// When neither Global nor UserID is set, all flags are loaded.
+type LoadParams struct {
+	// UserID selects the flags of the specified user.
+	UserID uuid.UUID
+	// Global selects the flags applying to all users.
+	Global bool
+}
+
+// DeleteParams contains the parameters for deleting a feature flag from the repository.
+type DeleteParams struct {
+	// Key identifies the feature of the flag to delete.
+	Key string
+	// UserID identifies the user of the flag to delete, or uuid.Nil for the deployment-wide flag.
+	UserID uuid.UUID
+}
@@ -0,0 +1,111 @@
+package feature
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/feature"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/db"
+	"github.com/google/uuid"
+)
+
+// Repository provides feature flag persistence operations.
+type Repository struct {
+	// db is the database client used for flag operations.
+	db db.DBClient
+}
+
+// NewRepository creates a new Repository with the provided database client.
+func NewRepository(dbClient db.DBClient) *Repository {
+	return &Repository{db: dbClient}
+}
+
+// Save creates the feature flag or updates the existing flag of the same feature and user.
+func (r *Repository) Save(ctx context.Context, params SaveParams) error {
+	e := params.Entity
+
+	query := `
+		INSERT INTO aegis_vault_keeper.feature_flags (key, user_id, enabled, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (key, user_id) DO UPDATE SET
+			enabled = EXCLUDED.enabled,
+			updated_at = EXCLUDED.updated_at
+	`
+	if _, err := r.db.Exec(ctx, query, e.Key, nullUserID(e.UserID), e.Enabled, e.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to save feature flag: %w", err)
+	}
+	return nil
+}
+
+// Load retrieves feature flags matching the provided parameters ordered by key.
+func (r *Repository) Load(ctx context.Context, params LoadParams) ([]*feature.Flag, error) {
+	var (
+		conditions []string
+		args       []interface{}
+	)
+	if params.Global {
+		conditions = append(conditions, "user_id IS NULL")
+	}
+	if params.UserID != uuid.Nil {
+		args = append(args, params.UserID)
+		conditions = append(conditions, fmt.Sprintf("user_id = $%d", len(args)))
+	}
+
+	query := `
+		SELECT key, user_id, enabled, updated_at
+		FROM aegis_vault_keeper.feature_flags
+	`
+	if len(conditions) != 0 {
+		query += " WHERE " + strings.Join(conditions, " OR ")
+	}
+	query += " ORDER BY key, user_id NULLS FIRST"
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load feature flags: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var flags []*feature.Flag
+	for rows.Next() {
+		var (
+			flag   feature.Flag
+			userID uuid.NullUUID
+		)
+		if err := rows.Scan(&flag.Key, &userID, &flag.Enabled, &flag.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan feature flag: %w", err)
+		}
+		flag.UserID = userID.UUID
+		flags = append(flags, &flag)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate feature flags: %w", err)
+	}
+	return flags, nil
+}
+
+// Delete removes the feature flag of the specified feature and user.
+func (r *Repository) Delete(ctx context.Context, params DeleteParams) error {
+	query := `
+		DELETE FROM aegis_vault_keeper.feature_flags
+		WHERE key = $1 AND user_id IS NOT DISTINCT FROM $2
+	`
+	res, err := r.db.Exec(ctx, query, params.Key, nullUserID(params.UserID))
+	if err != nil {
+		return fmt.Errorf("failed to delete feature flag: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check deleted feature flags: %w", err)
+	}
+	if n == 0 {
+		return ErrFlagNotFound
+	}
+	return nil
+}
+
+// nullUserID converts uuid.Nil, meaning a deployment-wide flag, to SQL NULL.
+func nullUserID(id uuid.UUID) uuid.NullUUID {
+	return uuid.NullUUID{UUID: id, Valid: id != uuid.Nil}
+}
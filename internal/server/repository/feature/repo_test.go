@@ -0,0 +1,242 @@
+package feature
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/feature"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/db"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockResult implements sql.Result for testing.
+type mockResult struct {
+	rowsAffected int64
+}
+
+func (m mockResult) LastInsertId() (int64, error) { return 0, nil }
+func (m mockResult) RowsAffected() (int64, error) { return m.rowsAffected, nil }
+
+// mockDBClient implements db.DBClient for testing.
+type mockDBClient struct {
+	execFunc  func(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	queryFunc func(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+func (m *mockDBClient) Exec(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	if m.execFunc != nil {
+		return m.execFunc(ctx, query, args...)
+	}
+	return mockResult{rowsAffected: 1}, nil
+}
+
+func (m *mockDBClient) Query(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	if m.queryFunc != nil {
+		return m.queryFunc(ctx, query, args...)
+	}
+	return nil, errors.New("mock not configured")
+}
+
+func (m *mockDBClient) QueryRow(context.Context, string, ...interface{}) *sql.Row {
+	return nil
+}
+
+func (m *mockDBClient) BeginTx(context.Context, *sql.TxOptions) (*sql.Tx, error) {
+	return nil, errors.New("mock not configured")
+}
+
+func (m *mockDBClient) CommitTx(*sql.Tx) error {
+	return nil
+}
+
+func (m *mockDBClient) RollbackTx(*sql.Tx) error {
+	return nil
+}
+
+// Ensure mockDBClient implements db.DBClient.
+var _ db.DBClient = (*mockDBClient)(nil)
+
+func TestRepository_Save(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	updatedAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		execErr  error
+		flag     *feature.Flag
+		name     string
+		wantArgs []interface{}
+	}{
+		{
+			name:     "global flag",
+			flag:     &feature.Flag{Key: "sync_v2", Enabled: true, UpdatedAt: updatedAt},
+			wantArgs: []interface{}{"sync_v2", uuid.NullUUID{}, true, updatedAt},
+		},
+		{
+			name: "user flag",
+			flag: &feature.Flag{Key: "sync_v2", UserID: userID, UpdatedAt: updatedAt},
+			wantArgs: []interface{}{
+				"sync_v2", uuid.NullUUID{UUID: userID, Valid: true}, false, updatedAt,
+			},
+		},
+		{
+			name:    "exec error",
+			flag:    &feature.Flag{Key: "sync_v2"},
+			execErr: errors.New("connection refused"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var gotArgs []interface{}
+			client := &mockDBClient{
+				execFunc: func(_ context.Context, query string, args ...interface{}) (sql.Result, error) {
+					assert.Contains(t, query, "INSERT INTO aegis_vault_keeper.feature_flags")
+					assert.Contains(t, query, "ON CONFLICT (key, user_id) DO UPDATE")
+					gotArgs = args
+					return mockResult{rowsAffected: 1}, tt.execErr
+				},
+			}
+
+			err := NewRepository(client).Save(context.Background(), SaveParams{Entity: tt.flag})
+
+			if tt.execErr != nil {
+				assert.ErrorIs(t, err, tt.execErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantArgs, gotArgs)
+		})
+	}
+}
+
+func TestRepository_LoadQuery(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+
+	tests := []struct {
+		name      string
+		wantWhere string
+		wantArgs  []interface{}
+		params    LoadParams
+	}{
+		{
+			name:   "all flags",
+			params: LoadParams{},
+		},
+		{
+			name:      "global flags",
+			params:    LoadParams{Global: true},
+			wantWhere: "WHERE user_id IS NULL ORDER BY",
+		},
+		{
+			name:      "user flags",
+			params:    LoadParams{UserID: userID},
+			wantWhere: "WHERE user_id = $1 ORDER BY",
+			wantArgs:  []interface{}{userID},
+		},
+		{
+			name:      "global and user flags",
+			params:    LoadParams{Global: true, UserID: userID},
+			wantWhere: "WHERE user_id IS NULL OR user_id = $1 ORDER BY",
+			wantArgs:  []interface{}{userID},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			queryErr := errors.New("query error")
+			var gotQuery string
+			var gotArgs []interface{}
+			client := &mockDBClient{
+				queryFunc: func(_ context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+					gotQuery, gotArgs = query, args
+					return nil, queryErr
+				},
+			}
+
+			_, err := NewRepository(client).Load(context.Background(), tt.params)
+
+			require.ErrorIs(t, err, queryErr)
+			if tt.wantWhere == "" {
+				assert.NotContains(t, gotQuery, "WHERE")
+			} else {
+				assert.Contains(t, gotQuery, tt.wantWhere)
+			}
+			assert.Equal(t, tt.wantArgs, gotArgs)
+		})
+	}
+}
+
+func TestRepository_Delete(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+
+	tests := []struct {
+		execErr      error
+		wantErr      error
+		name         string
+		wantUserID   uuid.NullUUID
+		params       DeleteParams
+		rowsAffected int64
+	}{
+		{
+			name:         "global flag",
+			params:       DeleteParams{Key: "sync_v2"},
+			rowsAffected: 1,
+		},
+		{
+			name:         "user flag",
+			params:       DeleteParams{Key: "sync_v2", UserID: userID},
+			wantUserID:   uuid.NullUUID{UUID: userID, Valid: true},
+			rowsAffected: 1,
+		},
+		{
+			name:    "not found",
+			params:  DeleteParams{Key: "sync_v2"},
+			wantErr: ErrFlagNotFound,
+		},
+		{
+			name:    "exec error",
+			params:  DeleteParams{Key: "sync_v2"},
+			execErr: errors.New("connection refused"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := &mockDBClient{
+				execFunc: func(_ context.Context, query string, args ...interface{}) (sql.Result, error) {
+					assert.Contains(t, query, "DELETE FROM aegis_vault_keeper.feature_flags")
+					assert.Equal(t, []interface{}{tt.params.Key, tt.wantUserID}, args)
+					return mockResult{rowsAffected: tt.rowsAffected}, tt.execErr
+				},
+			}
+
+			err := NewRepository(client).Delete(context.Background(), tt.params)
+
+			switch {
+			case tt.execErr != nil:
+				assert.ErrorIs(t, err, tt.execErr)
+			case tt.wantErr != nil:
+				assert.ErrorIs(t, err, tt.wantErr)
+			default:
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package fieldcrypt
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/crypto"
+	"github.com/google/uuid"
+)
+
+// ErrIntegrityViolation indicates that an encrypted field does not belong to the item it was loaded for.
+var ErrIntegrityViolation = errors.New("encrypted field integrity violation")
+
+// Binding describes the ownership context authenticated together with every encrypted item field.
+type Binding struct {
+	// ItemType identifies the kind of item the field belongs to.
+	ItemType string
+	// UserID identifies the owner of the item.
+	UserID uuid.UUID
+	// ItemID identifies the item the field belongs to.
+	ItemID uuid.UUID
+}
+
+// Seal encrypts field data with the key, binding the ciphertext to the item and field name.
+func (b Binding) Seal(key []byte, field string, data []byte) ([]byte, error) {
+	ciphertext, err := crypto.Seal(key, data, b.aad(field))
+	if err != nil {
+		return nil, fmt.Errorf("failed to seal %s field %s: %w", b.ItemType, field, err)
+	}
+	return ciphertext, nil
+}
+
+// Open decrypts field data with the key, verifying it was sealed for the same item and field name.
+// Returns ErrIntegrityViolation when the ciphertext was tampered with or moved from another item.
+func (b Binding) Open(key []byte, field string, data []byte) ([]byte, error) {
+	plaintext, err := crypto.Open(key, data, b.aad(field))
+	if err != nil {
+		if errors.Is(err, crypto.ErrIntegrityCheckFailed) {
+			err = errors.Join(ErrIntegrityViolation, err)
+		}
+		return nil, fmt.Errorf("failed to open %s %s field %s: %w", b.ItemType, b.ItemID, field, err)
+	}
+	return plaintext, nil
+}
+
+// OpenSelected decrypts field data like Open when the field is named in fields and returns nil without
+// decrypting otherwise. An empty field list selects every field.
+func (b Binding) OpenSelected(key []byte, fields []string, field string, data []byte) ([]byte, error) {
+	if len(fields) != 0 && !slices.Contains(fields, field) {
+		return nil, nil
+	}
+	return b.Open(key, field, data)
+}
+
+// aad builds the additional authenticated data for the named field.
+func (b Binding) aad(field string) []byte {
+	return crypto.BuildAAD(b.UserID.String(), b.ItemType, b.ItemID.String(), field)
+}
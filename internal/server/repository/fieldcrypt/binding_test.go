@@ -0,0 +1,148 @@
+package fieldcrypt
+
+import (
+	"testing"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/crypto"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBinding_SealOpen(t *testing.T) {
+	t.Parallel()
+
+	key := []byte("12345678901234567890123456789012")
+	sealed := Binding{ItemType: "note", UserID: uuid.New(), ItemID: uuid.New()}
+
+	ciphertext, err := sealed.Seal(key, "note", []byte("secret"))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		binding     Binding
+		field       string
+		expectError bool
+	}{
+		{
+			name:        "same binding",
+			binding:     sealed,
+			field:       "note",
+			expectError: false,
+		},
+		{
+			name:        "swapped item id",
+			binding:     Binding{ItemType: sealed.ItemType, UserID: sealed.UserID, ItemID: uuid.New()},
+			field:       "note",
+			expectError: true,
+		},
+		{
+			name:        "swapped user id",
+			binding:     Binding{ItemType: sealed.ItemType, UserID: uuid.New(), ItemID: sealed.ItemID},
+			field:       "note",
+			expectError: true,
+		},
+		{
+			name:        "swapped item type",
+			binding:     Binding{ItemType: "credential", UserID: sealed.UserID, ItemID: sealed.ItemID},
+			field:       "note",
+			expectError: true,
+		},
+		{
+			name:        "swapped field",
+			binding:     sealed,
+			field:       "description",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			plaintext, err := tt.binding.Open(key, tt.field, ciphertext)
+			if tt.expectError {
+				require.ErrorIs(t, err, ErrIntegrityViolation)
+				assert.Nil(t, plaintext)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, []byte("secret"), plaintext)
+		})
+	}
+}
+
+func TestBinding_OpenLegacy(t *testing.T) {
+	t.Parallel()
+
+	key := []byte("12345678901234567890123456789012")
+	b := Binding{ItemType: "note", UserID: uuid.New(), ItemID: uuid.New()}
+
+	// legacy is a field sealed before ciphertexts were bound to their items.
+	legacy, err := crypto.EncryptAESGCM(key, []byte("secret"))
+	require.NoError(t, err)
+
+	plaintext, err := b.Open(key, "note", legacy)
+	require.ErrorIs(t, err, ErrIntegrityViolation, "an unbound field must not be accepted for any item")
+	assert.Nil(t, plaintext)
+}
+
+func TestBinding_Errors(t *testing.T) {
+	t.Parallel()
+
+	b := Binding{ItemType: "note", UserID: uuid.New(), ItemID: uuid.New()}
+
+	_, err := b.Seal([]byte("short"), "note", []byte("data"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to seal")
+
+	_, err = b.Open([]byte("short"), "note", []byte("data"))
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, ErrIntegrityViolation)
+
+	_, err = b.Open([]byte("12345678901234567890123456789012"), "note", []byte("short"))
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, ErrIntegrityViolation)
+}
+
+func TestBinding_OpenSelected(t *testing.T) {
+	t.Parallel()
+
+	key := []byte("12345678901234567890123456789012")
+	b := Binding{ItemType: "note", UserID: uuid.New(), ItemID: uuid.New()}
+
+	ciphertext, err := b.Seal(key, "note", []byte("secret"))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		fields []string
+		want   []byte
+	}{
+		{
+			name:   "all fields",
+			fields: nil,
+			want:   []byte("secret"),
+		},
+		{
+			name:   "field selected",
+			fields: []string{"description", "note"},
+			want:   []byte("secret"),
+		},
+		{
+			name:   "field not selected",
+			fields: []string{"description"},
+			want:   nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			plaintext, err := b.OpenSelected(key, tt.fields, "note", ciphertext)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, plaintext)
+		})
+	}
+}
@@ -0,0 +1,6 @@
+// Package fieldcrypt provides ownership-bound field encryption for the AegisVaultKeeper repository layer.
+//
+// This package binds every encrypted column value to its owner, item type, item ID and field name
+// through AEAD additional authenticated data, so swapped or relocated ciphertexts are rejected.
+// Its Pipeline spreads the encryption of bulk operation items over a bounded pool of workers.
+package fieldcrypt
@@ -0,0 +1,81 @@
+package fieldcrypt
+
+import (
+	"context"
+	"runtime"
+	"sync/atomic"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// Pipeline encrypts or decrypts the items of bulk operations on a bounded pool of workers.
+// A nil Pipeline processes items serially.
+type Pipeline struct {
+	// workers limits the number of items processed concurrently.
+	workers int
+}
+
+// NewPipeline creates a Pipeline running at most workers items concurrently.
+// A non-positive number of workers uses one worker per available CPU.
+func NewPipeline(workers int) *Pipeline {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	return &Pipeline{workers: workers}
+}
+
+// Run calls fn for every index in [0, n) on the worker pool. fn must only touch the item at its index,
+// so results assembled by index keep the input order. The first error stops the remaining items and is
+// returned; Run also stops once ctx is done.
+func (p *Pipeline) Run(ctx context.Context, n int, fn func(i int) error) error {
+	workers := 1
+	if p != nil {
+		workers = min(p.workers, n)
+	}
+	if workers <= 1 {
+		for i := range n {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if err := fn(i); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	g, ctx := errgroup.WithContext(ctx)
+	// next holds the index of the next unclaimed item.
+	var next atomic.Int64
+	for range workers {
+		g.Go(func() error {
+			for {
+				i := int(next.Add(1) - 1)
+				if i >= n {
+					return nil
+				}
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+				if err := fn(i); err != nil {
+					return err
+				}
+			}
+		})
+	}
+	return g.Wait()
+}
+
+// Map applies fn to every item on the pipeline's worker pool and returns the results in input order.
+func Map[T, R any](ctx context.Context, p *Pipeline, items []T, fn func(item T) (R, error)) ([]R, error) {
+	results := make([]R, len(items))
+	err := p.Run(ctx, len(items), func(i int) error {
+		var err error
+		results[i], err = fn(items[i])
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
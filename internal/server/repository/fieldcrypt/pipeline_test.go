@@ -0,0 +1,120 @@
+package fieldcrypt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPipeline_Map(t *testing.T) {
+	t.Parallel()
+
+	items := make([]int, 1000)
+	for i := range items {
+		items[i] = i
+	}
+
+	tests := []struct {
+		pipeline *Pipeline
+		name     string
+	}{
+		{name: "nil pipeline", pipeline: nil},
+		{name: "single worker", pipeline: NewPipeline(1)},
+		{name: "several workers", pipeline: NewPipeline(8)},
+		{name: "default workers", pipeline: NewPipeline(0)},
+		{name: "more workers than items", pipeline: NewPipeline(5000)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			results, err := Map(context.Background(), tt.pipeline, items, func(item int) (string, error) {
+				return fmt.Sprint(item), nil
+			})
+
+			require.NoError(t, err)
+			require.Len(t, results, len(items))
+			for i, r := range results {
+				assert.Equal(t, fmt.Sprint(i), r)
+			}
+		})
+	}
+}
+
+func TestPipeline_RunError(t *testing.T) {
+	t.Parallel()
+
+	errItem := errors.New("item failed")
+
+	tests := []struct {
+		pipeline *Pipeline
+		name     string
+	}{
+		{name: "serial", pipeline: NewPipeline(1)},
+		{name: "parallel", pipeline: NewPipeline(4)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var processed atomic.Int64
+			err := tt.pipeline.Run(context.Background(), 10_000, func(i int) error {
+				processed.Add(1)
+				if i == 10 {
+					return errItem
+				}
+				return nil
+			})
+
+			require.ErrorIs(t, err, errItem)
+			assert.Less(t, processed.Load(), int64(10_000))
+		})
+	}
+}
+
+func TestPipeline_RunCanceled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	calls := 0
+	err := NewPipeline(1).Run(ctx, 10, func(int) error {
+		calls++
+		return nil
+	})
+
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Zero(t, calls)
+}
+
+func BenchmarkPipeline_Seal(b *testing.B) {
+	key := []byte("12345678901234567890123456789012")
+	items := make([]Binding, 10_000)
+	for i := range items {
+		items[i] = Binding{ItemType: "note", UserID: uuid.New(), ItemID: uuid.New()}
+	}
+	data := make([]byte, 1024)
+
+	for _, workers := range []int{1, 0} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			p := NewPipeline(workers)
+			for range b.N {
+				_, err := Map(context.Background(), p, items, func(bd Binding) ([]byte, error) {
+					return bd.Seal(key, "note", data)
+				})
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,217 @@
+package fieldcrypt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/crypto"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/db"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/keyprv"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/securebytes"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/vaultunlock"
+	"github.com/google/uuid"
+)
+
+// BoundTable describes a table whose encrypted columns are bound to their owner and item.
+type BoundTable struct {
+	// Name is the table name within the aegis_vault_keeper schema.
+	Name string
+	// ItemType identifies the kind of item the rows hold, as bound by Binding.
+	ItemType string
+	// Columns lists the encrypted columns; each is bound under its column name.
+	Columns []string
+}
+
+// storedRow holds the encrypted column values of a row that may hold unbound fields.
+type storedRow struct {
+	// location is the physical row location, identifying versions that share an item ID.
+	location string
+	// values lists the encrypted column values in BoundTable column order.
+	values [][]byte
+	// id identifies the item the row holds.
+	id uuid.UUID
+	// userID identifies the owner of the item.
+	userID uuid.UUID
+}
+
+// Rebinder binds the encrypted fields stored before ciphertexts were bound to their items.
+type Rebinder struct {
+	// db is the database client used to scan and update tables.
+	db db.DBClient
+	// keys provides the user keys the fields are sealed with.
+	keys keyprv.UserKeyProvider
+	// tables lists the tables to rebind.
+	tables []BoundTable
+}
+
+// NewRebinder creates a new Rebinder for the given tables.
+func NewRebinder(dbClient db.DBClient, keys keyprv.UserKeyProvider, tables ...BoundTable) *Rebinder {
+	return &Rebinder{
+		db:     dbClient,
+		keys:   keys,
+		tables: tables,
+	}
+}
+
+// Rebind seals the unbound fields of unsigned rows again with their binding in every table not rebound before and
+// returns the number of rows rebound. Each table is rebound once and recorded in the field_binding_backfills table,
+// so an unbound ciphertext placed later is rejected as an integrity violation instead of being bound. Signed rows
+// were sealed after the binding was introduced and are left untouched, and rebound items are not archived as new
+// versions. The rows of all users are rebound within the admin scope; the fields of users whose vault is locked
+// cannot be opened and stay unbound.
+func (r *Rebinder) Rebind(ctx context.Context) (int, error) {
+	// rebound counts the rows rebound across all tables.
+	var rebound int
+	err := db.InAdminScope(ctx, r.db, func(ctx context.Context) error {
+		for _, t := range r.tables {
+			err := db.WithoutArchiving(ctx, r.db, func(ctx context.Context) error {
+				n, err := r.rebindTable(ctx, t)
+				rebound += n
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("failed to rebind table %s: %w", t.Name, err)
+			}
+		}
+		return nil
+	})
+	return rebound, err
+}
+
+// rebindTable binds the unbound fields of a single table unless it was rebound before.
+func (r *Rebinder) rebindTable(ctx context.Context, t BoundTable) (int, error) {
+	var done bool
+	if err := r.db.QueryRow(ctx,
+		"SELECT EXISTS (SELECT 1 FROM aegis_vault_keeper.field_binding_backfills WHERE table_name = $1)",
+		t.Name,
+	).Scan(&done); err != nil {
+		return 0, fmt.Errorf("failed to check rebind state: %w", err)
+	}
+	if done {
+		return 0, nil
+	}
+
+	rows, err := r.unsignedRows(ctx, t)
+	if err != nil {
+		return 0, err
+	}
+
+	// keys caches the user keys provided for the table; nil marks a locked vault.
+	keys := make(map[uuid.UUID][]byte)
+	defer func() {
+		for _, k := range keys {
+			securebytes.Wipe(k)
+		}
+	}()
+
+	assignments := make([]string, 0, len(t.Columns))
+	for i, col := range t.Columns {
+		assignments = append(assignments, fmt.Sprintf("%s = $%d", col, i+2))
+	}
+	update := fmt.Sprintf(
+		"UPDATE aegis_vault_keeper.%s SET %s WHERE ctid = $1::tid", t.Name, strings.Join(assignments, ", "),
+	)
+
+	var rebound int
+	for _, row := range rows {
+		k, ok := keys[row.userID]
+		if !ok {
+			k, err = r.keys.UserKeyProvide(ctx, row.userID)
+			if err != nil && !errors.Is(err, vaultunlock.ErrLocked) {
+				return rebound, fmt.Errorf("failed to provide user key: %w", err)
+			}
+			keys[row.userID] = k
+		}
+		if k == nil {
+			continue
+		}
+
+		changed, err := rebindRow(k, Binding{ItemType: t.ItemType, UserID: row.userID, ItemID: row.id}, t, row)
+		if err != nil {
+			return rebound, err
+		}
+		if !changed {
+			continue
+		}
+
+		args := make([]any, 0, len(row.values)+1)
+		args = append(args, row.location)
+		for _, v := range row.values {
+			args = append(args, v)
+		}
+		if _, err := r.db.Exec(ctx, update, args...); err != nil {
+			return rebound, fmt.Errorf("failed to store rebound fields of item %s: %w", row.id, err)
+		}
+		rebound++
+	}
+
+	if _, err := r.db.Exec(ctx,
+		"INSERT INTO aegis_vault_keeper.field_binding_backfills (table_name, completed_at) VALUES ($1, NOW()) "+
+			"ON CONFLICT (table_name) DO NOTHING",
+		t.Name,
+	); err != nil {
+		return rebound, fmt.Errorf("failed to record rebind: %w", err)
+	}
+	return rebound, nil
+}
+
+// rebindRow seals the unbound fields of the row again with the binding and reports whether any field changed.
+// Fields that are empty, already bound or no ciphertext of the key are kept as they are.
+func rebindRow(k []byte, b Binding, t BoundTable, row storedRow) (bool, error) {
+	var changed bool
+	for i, col := range t.Columns {
+		data := row.values[i]
+		if len(data) == 0 {
+			continue
+		}
+		if plaintext, err := crypto.Open(k, data, b.aad(col)); err == nil {
+			securebytes.Wipe(plaintext)
+			continue
+		}
+		plaintext, err := crypto.DecryptAESGCM(k, data)
+		if err != nil {
+			continue
+		}
+		sealed, err := b.Seal(k, col, plaintext)
+		securebytes.Wipe(plaintext)
+		if err != nil {
+			return false, err
+		}
+		row.values[i] = sealed
+		changed = true
+	}
+	return changed, nil
+}
+
+// unsignedRows loads the encrypted column values of the rows of the table without a row signature.
+func (r *Rebinder) unsignedRows(ctx context.Context, t BoundTable) ([]storedRow, error) {
+	query := fmt.Sprintf(
+		"SELECT ctid::text, id, user_id, %s FROM aegis_vault_keeper.%s WHERE signature IS NULL",
+		strings.Join(t.Columns, ", "), t.Name,
+	)
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var stored []storedRow
+	for rows.Next() {
+		row := storedRow{values: make([][]byte, len(t.Columns))}
+		dest := make([]any, 0, len(t.Columns)+3)
+		dest = append(dest, &row.location, &row.id, &row.userID)
+		for i := range row.values {
+			dest = append(dest, &row.values[i])
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		stored = append(stored, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+	return stored, nil
+}
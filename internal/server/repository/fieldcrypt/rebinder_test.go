@@ -0,0 +1,309 @@
+package fieldcrypt
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/crypto"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/vaultunlock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// noteTable describes the bound columns of the notes table for testing.
+var noteTable = BoundTable{Name: "notes", ItemType: "note", Columns: []string{"note", "description"}}
+
+// fakeNote is a row of the in-memory notes table.
+type fakeNote struct {
+	location    string
+	note        []byte
+	description []byte
+	signature   []byte
+	id          uuid.UUID
+	userID      uuid.UUID
+}
+
+// fakeStore is an in-memory notes table and rebind record served through database/sql.
+type fakeStore struct {
+	// rebound holds the names of the tables recorded as rebound.
+	rebound map[string]bool
+	// notes holds the rows of the notes table.
+	notes []*fakeNote
+	// mu guards the store fields.
+	mu sync.Mutex
+	// rls reports whether row-level security hides the rows of the notes table outside the admin scope.
+	rls bool
+	// adminScope reports whether the running transaction is in the admin scope.
+	adminScope bool
+}
+
+// hidden reports whether row-level security hides the rows of the notes table from the running statement.
+func (s *fakeStore) hidden() bool {
+	return s.rls && !s.adminScope
+}
+
+func (s *fakeStore) Connect(context.Context) (driver.Conn, error) { return fakeConn{s}, nil }
+func (s *fakeStore) Driver() driver.Driver                        { return nil }
+
+// fakeConn answers the queries of the Rebinder on the notes table.
+type fakeConn struct {
+	s *fakeStore
+}
+
+func (fakeConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("prepare not supported") }
+func (fakeConn) Close() error                        { return nil }
+func (fakeConn) Begin() (driver.Tx, error)           { return nil, errors.New("transactions not supported") }
+
+func (c fakeConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.s.mu.Lock()
+	defer c.s.mu.Unlock()
+
+	if strings.HasPrefix(query, "SELECT EXISTS") {
+		name, _ := args[0].Value.(string)
+		return &fakeRows{columns: []string{"exists"}, values: [][]driver.Value{{c.s.rebound[name]}}}, nil
+	}
+	if query != "SELECT ctid::text, id, user_id, note, description FROM aegis_vault_keeper.notes "+
+		"WHERE signature IS NULL" {
+		return nil, errors.New("unexpected query: " + query)
+	}
+
+	rows := &fakeRows{columns: []string{"ctid", "id", "user_id", "note", "description"}}
+	for _, n := range c.s.notes {
+		if c.s.hidden() || n.signature != nil {
+			continue
+		}
+		rows.values = append(rows.values,
+			[]driver.Value{n.location, n.id.String(), n.userID.String(), n.note, n.description})
+	}
+	return rows, nil
+}
+
+func (c fakeConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.s.mu.Lock()
+	defer c.s.mu.Unlock()
+
+	switch query {
+	case "UPDATE aegis_vault_keeper.notes SET note = $2, description = $3 WHERE ctid = $1::tid":
+		location, _ := args[0].Value.(string)
+		for _, n := range c.s.notes {
+			if n.location == location && !c.s.hidden() {
+				n.note, _ = args[1].Value.([]byte)
+				n.description, _ = args[2].Value.([]byte)
+			}
+		}
+	case "INSERT INTO aegis_vault_keeper.field_binding_backfills (table_name, completed_at) VALUES ($1, NOW()) " +
+		"ON CONFLICT (table_name) DO NOTHING":
+		name, _ := args[0].Value.(string)
+		c.s.rebound[name] = true
+	default:
+		return nil, errors.New("unexpected statement: " + query)
+	}
+	return driver.RowsAffected(1), nil
+}
+
+// fakeRows iterates over rows of driver values.
+type fakeRows struct {
+	columns []string
+	values  [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string { return r.columns }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
+// fakeDBClient implements db.DBClient over a database/sql handle.
+type fakeDBClient struct {
+	db *sql.DB
+}
+
+func (c fakeDBClient) Exec(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return c.db.ExecContext(ctx, query, args...)
+}
+
+func (c fakeDBClient) QueryRow(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return c.db.QueryRowContext(ctx, query, args...)
+}
+
+func (c fakeDBClient) Query(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return c.db.QueryContext(ctx, query, args...)
+}
+
+func (c fakeDBClient) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	return c.db.BeginTx(ctx, opts)
+}
+
+func (fakeDBClient) CommitTx(*sql.Tx) error   { return nil }
+func (fakeDBClient) RollbackTx(*sql.Tx) error { return nil }
+
+// fakeAdminDBClient extends fakeDBClient with the admin scope of row-level security.
+type fakeAdminDBClient struct {
+	fakeDBClient
+	store *fakeStore
+}
+
+func (c fakeAdminDBClient) RunInAdminScope(ctx context.Context, fn func(ctx context.Context) error) error {
+	c.store.mu.Lock()
+	c.store.adminScope = true
+	c.store.mu.Unlock()
+	defer func() {
+		c.store.mu.Lock()
+		c.store.adminScope = false
+		c.store.mu.Unlock()
+	}()
+	return fn(ctx)
+}
+
+// fakeKeyProvider provides the keys of users; users without a key have a locked vault.
+type fakeKeyProvider struct {
+	err  error
+	keys map[uuid.UUID][]byte
+}
+
+func (p *fakeKeyProvider) UserKeyProvide(_ context.Context, userID uuid.UUID) ([]byte, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	k, ok := p.keys[userID]
+	if !ok {
+		return nil, fmt.Errorf("user %s: %w", userID, vaultunlock.ErrLocked)
+	}
+	return append([]byte(nil), k...), nil
+}
+
+func TestRebinder_Rebind(t *testing.T) {
+	t.Parallel()
+
+	key := []byte("12345678901234567890123456789012")
+	userID, lockedID := uuid.New(), uuid.New()
+	// legacySeal encrypts data the way fields were stored before they were bound to their items.
+	legacySeal := func(data string) []byte {
+		ciphertext, err := crypto.EncryptAESGCM(key, []byte(data))
+		require.NoError(t, err)
+		return ciphertext
+	}
+
+	itemID := uuid.New()
+	current := &fakeNote{
+		location: "(0,1)", id: itemID, userID: userID,
+		note: legacySeal("current"), description: legacySeal("description"),
+	}
+	version := &fakeNote{
+		location: "(0,2)", id: itemID, userID: userID,
+		note: legacySeal("version"), description: []byte{},
+	}
+	boundItem := Binding{ItemType: "note", UserID: userID, ItemID: uuid.New()}
+	boundNote, err := boundItem.Seal(key, "note", []byte("bound"))
+	require.NoError(t, err)
+	bound := &fakeNote{location: "(0,3)", id: boundItem.ItemID, userID: userID, note: boundNote, description: []byte{}}
+	signed := &fakeNote{
+		location: "(0,4)", id: uuid.New(), userID: userID,
+		note: legacySeal("signed"), description: []byte{}, signature: []byte("signature"),
+	}
+	locked := &fakeNote{
+		location: "(0,5)", id: uuid.New(), userID: lockedID, note: legacySeal("locked"), description: []byte{},
+	}
+	signedNote, lockedNote := signed.note, locked.note
+
+	store := &fakeStore{rebound: make(map[string]bool), notes: []*fakeNote{current, version, bound, signed, locked}}
+	client := fakeDBClient{db: sql.OpenDB(store)}
+	t.Cleanup(func() { _ = client.db.Close() })
+	rebinder := NewRebinder(client, &fakeKeyProvider{keys: map[uuid.UUID][]byte{userID: key}}, noteTable)
+
+	b := Binding{ItemType: "note", UserID: userID, ItemID: itemID}
+	_, err = b.Open(key, "note", current.note)
+	require.ErrorIs(t, err, ErrIntegrityViolation, "an unbound field is rejected before the rebind")
+
+	n, err := rebinder.Rebind(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.True(t, store.rebound["notes"])
+
+	for _, tt := range []struct {
+		row   *fakeNote
+		field string
+		data  []byte
+		want  string
+	}{
+		{row: current, field: "note", data: current.note, want: "current"},
+		{row: current, field: "description", data: current.description, want: "description"},
+		{row: version, field: "note", data: version.note, want: "version"},
+	} {
+		plaintext, err := b.Open(key, tt.field, tt.data)
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, string(plaintext))
+	}
+	assert.Equal(t, []byte{}, version.description, "an empty field stays empty")
+	assert.Equal(t, boundNote, bound.note, "a bound field is kept")
+	assert.Equal(t, signedNote, signed.note, "a signed row is not rebound")
+	assert.Equal(t, lockedNote, locked.note, "the fields of a locked vault are not rebound")
+
+	// An unbound ciphertext placed after the rebind is not bound again.
+	current.note = legacySeal("swapped")
+	n, err = rebinder.Rebind(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, n)
+	_, err = b.Open(key, "note", current.note)
+	require.ErrorIs(t, err, ErrIntegrityViolation)
+}
+
+func TestRebinder_Rebind_RowLevelSecurity(t *testing.T) {
+	t.Parallel()
+
+	key := []byte("12345678901234567890123456789012")
+	legacy, err := crypto.EncryptAESGCM(key, []byte("note"))
+	require.NoError(t, err)
+	row := &fakeNote{location: "(0,1)", id: uuid.New(), userID: uuid.New(), note: legacy, description: []byte{}}
+
+	store := &fakeStore{rebound: make(map[string]bool), notes: []*fakeNote{row}, rls: true}
+	client := fakeAdminDBClient{fakeDBClient: fakeDBClient{db: sql.OpenDB(store)}, store: store}
+	t.Cleanup(func() { _ = client.db.Close() })
+	keys := &fakeKeyProvider{keys: map[uuid.UUID][]byte{row.userID: key}}
+
+	n, err := NewRebinder(client, keys, noteTable).Rebind(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, n, "the rows of every user are rebound within the admin scope")
+	b := Binding{ItemType: "note", UserID: row.userID, ItemID: row.id}
+	plaintext, err := b.Open(key, "note", row.note)
+	require.NoError(t, err)
+	assert.Equal(t, "note", string(plaintext))
+}
+
+func TestRebinder_Rebind_KeyError(t *testing.T) {
+	t.Parallel()
+
+	key := []byte("12345678901234567890123456789012")
+	legacy, err := crypto.EncryptAESGCM(key, []byte("note"))
+	require.NoError(t, err)
+
+	store := &fakeStore{
+		rebound: make(map[string]bool),
+		notes: []*fakeNote{
+			{location: "(0,1)", id: uuid.New(), userID: uuid.New(), note: legacy, description: []byte{}},
+		},
+	}
+	client := fakeDBClient{db: sql.OpenDB(store)}
+	t.Cleanup(func() { _ = client.db.Close() })
+	rebinder := NewRebinder(client, &fakeKeyProvider{err: errors.New("user not found")}, noteTable)
+
+	_, err = rebinder.Rebind(context.Background())
+
+	require.ErrorContains(t, err, "failed to rebind table notes")
+	assert.False(t, store.rebound["notes"], "a failed rebind is retried on the next start")
+}
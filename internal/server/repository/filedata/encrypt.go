@@ -4,11 +4,23 @@ import (
 	"context"
 	"fmt"
 
-	"github.com/gdyunin/aegis-vault-keeper/internal/server/crypto"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/filedata"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/fieldcrypt"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/keyprv"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/securebytes"
 )
 
+// itemType identifies file items in the ownership binding of encrypted fields.
+const itemType = "file"
+
+// BoundTable describes the files table columns that may still hold fields sealed before
+// encrypted fields were bound to their owner and item.
+var BoundTable = fieldcrypt.BoundTable{
+	Name:     "files",
+	ItemType: itemType,
+	Columns:  []string{"storage_key", "hash_sum", "description"},
+}
+
 // encryptionMw creates middleware that encrypts file data fields before saving to the database.
 func encryptionMw(keyProvider keyprv.UserKeyProvider) saveMw {
 	return func(next saveFunc) saveFunc {
@@ -17,18 +29,24 @@ func encryptionMw(keyProvider keyprv.UserKeyProvider) saveMw {
 			if err != nil {
 				return fmt.Errorf("failed to provide user key: %w", err)
 			}
+			defer securebytes.Wipe(k)
 
 			copyEntity := *p.Entity
+			// b holds the ownership binding authenticated with every encrypted field.
+			b := fieldcrypt.Binding{ItemType: itemType, UserID: copyEntity.UserID, ItemID: copyEntity.ID}
 
-			if copyEntity.StorageKey, err = crypto.EncryptAESGCM(k, copyEntity.StorageKey); err != nil {
+			if copyEntity.StorageKey, err = b.Seal(k, "storage_key", copyEntity.StorageKey); err != nil {
 				return fmt.Errorf("failed to encrypt storage key: %w", err)
 			}
-			if copyEntity.HashSum, err = crypto.EncryptAESGCM(k, copyEntity.HashSum); err != nil {
+			if copyEntity.HashSum, err = b.Seal(k, "hash_sum", copyEntity.HashSum); err != nil {
 				return fmt.Errorf("failed to encrypt hash sum: %w", err)
 			}
-			if copyEntity.Description, err = crypto.EncryptAESGCM(k, copyEntity.Description); err != nil {
+			if copyEntity.Description, err = b.Seal(k, "description", copyEntity.Description); err != nil {
 				return fmt.Errorf("failed to encrypt description: %w", err)
 			}
+			if copyEntity.Folder, err = b.Seal(k, "folder", copyEntity.Folder); err != nil {
+				return fmt.Errorf("failed to encrypt folder: %w", err)
+			}
 
 			p.Entity = &copyEntity
 			return next(ctx, p)
@@ -37,7 +55,7 @@ func encryptionMw(keyProvider keyprv.UserKeyProvider) saveMw {
 }
 
 // decryptionMw creates middleware that decrypts file data fields after loading from the database.
-func decryptionMw(keyProvider keyprv.UserKeyProvider) loadMw {
+func decryptionMw(keyProvider keyprv.UserKeyProvider, pipeline *fieldcrypt.Pipeline) loadMw {
 	return func(next loadFunc) loadFunc {
 		return func(ctx context.Context, p LoadParams) ([]*filedata.FileData, error) {
 			entities, err := next(ctx, p)
@@ -52,17 +70,33 @@ func decryptionMw(keyProvider keyprv.UserKeyProvider) loadMw {
 			if err != nil {
 				return nil, fmt.Errorf("failed to provide user key: %w", err)
 			}
+			defer securebytes.Wipe(k)
 
-			for _, entity := range entities {
-				if entity.StorageKey, err = crypto.DecryptAESGCM(k, entity.StorageKey); err != nil {
-					return nil, fmt.Errorf("failed to decrypt storage key: %w", err)
+			err = pipeline.Run(ctx, len(entities), func(i int) error {
+				entity := entities[i]
+				// b holds the ownership binding the loaded fields must have been sealed with.
+				b := fieldcrypt.Binding{ItemType: itemType, UserID: p.UserID, ItemID: entity.ID}
+				var err error
+				if entity.StorageKey, err = b.Open(k, "storage_key", entity.StorageKey); err != nil {
+					return fmt.Errorf("failed to decrypt storage key: %w", err)
 				}
-				if entity.HashSum, err = crypto.DecryptAESGCM(k, entity.HashSum); err != nil {
-					return nil, fmt.Errorf("failed to decrypt hash sum: %w", err)
+				if entity.HashSum, err = b.Open(k, "hash_sum", entity.HashSum); err != nil {
+					return fmt.Errorf("failed to decrypt hash sum: %w", err)
 				}
-				if entity.Description, err = crypto.DecryptAESGCM(k, entity.Description); err != nil {
-					return nil, fmt.Errorf("failed to decrypt description: %w", err)
+				if entity.Description, err = b.Open(k, "description", entity.Description); err != nil {
+					return fmt.Errorf("failed to decrypt description: %w", err)
 				}
+				// Files stored before folders were introduced have no folder and lie in the root folder.
+				if len(entity.Folder) == 0 {
+					return nil
+				}
+				if entity.Folder, err = b.Open(k, "folder", entity.Folder); err != nil {
+					return fmt.Errorf("failed to decrypt folder: %w", err)
+				}
+				return nil
+			})
+			if err != nil {
+				return nil, err
 			}
 
 			return entities, nil
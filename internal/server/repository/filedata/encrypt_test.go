@@ -1,6 +1,7 @@
 package filedata
 
 import (
+	"bytes"
 	"context"
 	"testing"
 
@@ -8,8 +9,8 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
-	"github.com/gdyunin/aegis-vault-keeper/internal/server/crypto"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/filedata"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/fieldcrypt"
 )
 
 // Mock key provider for testing encryption middleware.
@@ -22,7 +23,7 @@ func (m *mockFileDataKeyProvider) UserKeyProvide(ctx context.Context, userID uui
 	if m.shouldErr {
 		return nil, assert.AnError
 	}
-	return m.key, nil
+	return bytes.Clone(m.key), nil
 }
 
 func TestEncryptionMw(t *testing.T) {
@@ -157,10 +158,11 @@ func TestDecryptionMw(t *testing.T) {
 				id := uuid.New()
 				userID := uuid.New()
 
-				// Use crypto package to create real encrypted values
-				storageKeyEncrypted, _ := crypto.EncryptAESGCM(validKey, []byte("storage/path/file.txt"))
-				hashSumEncrypted, _ := crypto.EncryptAESGCM(validKey, []byte("sha256hashvalue"))
-				descEncrypted, _ := crypto.EncryptAESGCM(validKey, []byte("test file description"))
+				// b holds the ownership binding the loader expects for this entity
+				b := fieldcrypt.Binding{ItemType: itemType, UserID: userID, ItemID: id}
+				storageKeyEncrypted, _ := b.Seal(validKey, "storage_key", []byte("storage/path/file.txt"))
+				hashSumEncrypted, _ := b.Seal(validKey, "hash_sum", []byte("sha256hashvalue"))
+				descEncrypted, _ := b.Seal(validKey, "description", []byte("test file description"))
 
 				return []*filedata.FileData{
 					{
@@ -174,6 +176,25 @@ func TestDecryptionMw(t *testing.T) {
 			}(),
 			expectError: false,
 		},
+		{
+			name: "ciphertext swapped from another item",
+			keyProvider: &mockFileDataKeyProvider{
+				key:       validKey,
+				shouldErr: false,
+			},
+			entities: func() []*filedata.FileData {
+				userID := uuid.New()
+				// other holds the binding of a different item owned by the same user
+				other := fieldcrypt.Binding{ItemType: itemType, UserID: userID, ItemID: uuid.New()}
+				swapped, _ := other.Seal(validKey, "storage_key", []byte("foreign"))
+
+				return []*filedata.FileData{
+					{ID: uuid.New(), UserID: userID, StorageKey: swapped},
+				}
+			}(),
+			expectError: true,
+			errorMsg:    "integrity violation",
+		},
 		{
 			name: "empty entities list",
 			keyProvider: &mockFileDataKeyProvider{
@@ -229,7 +250,7 @@ func TestDecryptionMw(t *testing.T) {
 			t.Parallel()
 
 			// Create middleware
-			mw := decryptionMw(tt.keyProvider)
+			mw := decryptionMw(tt.keyProvider, nil)
 
 			// Mock next function that simulates database load
 			nextFunc := func(ctx context.Context, p LoadParams) ([]*filedata.FileData, error) {
@@ -244,6 +265,9 @@ func TestDecryptionMw(t *testing.T) {
 
 			// Execute
 			params := LoadParams{UserID: uuid.New()}
+			if len(tt.entities) > 0 {
+				params.UserID = tt.entities[0].UserID
+			}
 			result, err := wrappedFunc(context.Background(), params)
 
 			if tt.expectError {
@@ -320,3 +344,54 @@ func TestMiddlewareChaining(t *testing.T) {
 		) // mw1 (encryption) was applied
 	})
 }
+
+func TestFolderRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	keyProvider := &mockFileDataKeyProvider{key: []byte("12345678901234567890123456789012")}
+
+	tests := []struct {
+		name   string
+		folder []byte
+	}{
+		{name: "nested folder", folder: []byte("docs/taxes")},
+		{name: "root folder", folder: []byte("")},
+		{name: "legacy row without folder", folder: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			entity := &filedata.FileData{
+				ID:          uuid.New(),
+				UserID:      uuid.New(),
+				StorageKey:  []byte("file.txt"),
+				HashSum:     []byte("sha256hashvalue"),
+				Description: []byte("description"),
+				Folder:      tt.folder,
+			}
+
+			// stored holds the entity as passed to the database.
+			var stored filedata.FileData
+			save := encryptionMw(keyProvider)(func(_ context.Context, p SaveParams) error {
+				stored = *p.Entity
+				return nil
+			})
+			require.NoError(t, save(context.Background(), SaveParams{Entity: entity}))
+			if tt.folder == nil {
+				stored.Folder = nil
+			} else {
+				assert.NotEqual(t, tt.folder, stored.Folder)
+			}
+
+			load := decryptionMw(keyProvider, nil)(func(context.Context, LoadParams) ([]*filedata.FileData, error) {
+				return []*filedata.FileData{&stored}, nil
+			})
+			got, err := load(context.Background(), LoadParams{UserID: entity.UserID})
+			require.NoError(t, err)
+			require.Len(t, got, 1)
+			assert.Equal(t, string(tt.folder), string(got[0].Folder))
+		})
+	}
+}
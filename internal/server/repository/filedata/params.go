@@ -13,8 +13,12 @@ type SaveParams struct {
 
 // LoadParams contains parameters for loading file data entities from the repository.
 type LoadParams struct {
+	// After restricts the load to the file data whose ID sorts after it; zero value starts at the first.
+	After uuid.UUID
 	// ID specifies the file data ID to load; zero value loads all user file data.
 	ID uuid.UUID
+	// Limit caps the number of loaded file data and orders them by ID; zero value loads all.
+	Limit int
 	// UserID identifies the user whose file data to load.
 	UserID uuid.UUID
 }
@@ -8,25 +8,49 @@ import (
 
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/filedata"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/db"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/rowsign"
 	"github.com/google/uuid"
 )
 
 // rawSave creates a function that performs raw database save operations for file data.
-func rawSave(db db.DBClient) saveFunc {
+func rawSave(db db.DBClient, signer *rowsign.Signer) saveFunc {
 	return func(ctx context.Context, p SaveParams) error {
 		e := p.Entity
 
+		signature, err := signer.Sign(SignedTable, rowValues(e)...)
+		if err != nil {
+			return fmt.Errorf("failed to sign file row: %w", err)
+		}
+
 		query := `
-			INSERT INTO aegis_vault_keeper.files (id, user_id, storage_key, hash_sum, description, updated_at)
-			VALUES ($1, $2, $3, $4, $5, $6)
+			INSERT INTO aegis_vault_keeper.files (
+				id, user_id, storage_key, hash_sum, description, updated_at, signature, file_key, folder, size
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 			ON CONFLICT (id) DO UPDATE SET
 			  storage_key        = EXCLUDED.storage_key,
 			  hash_sum     = EXCLUDED.hash_sum,
 			  description  = EXCLUDED.description,
-			  updated_at   = EXCLUDED.updated_at
+			  updated_at   = EXCLUDED.updated_at,
+			  signature    = EXCLUDED.signature,
+			  file_key     = EXCLUDED.file_key,
+			  folder       = EXCLUDED.folder,
+			  size         = EXCLUDED.size
 		`
 
-		if _, err := db.Exec(ctx, query, e.ID, e.UserID, e.StorageKey, e.HashSum, e.Description, e.UpdatedAt); err != nil {
+		if _, err := db.Exec(
+			ctx,
+			query,
+			e.ID,
+			e.UserID,
+			e.StorageKey,
+			e.HashSum,
+			e.Description,
+			e.UpdatedAt,
+			signature,
+			e.FileKey,
+			e.Folder,
+			e.Size,
+		); err != nil {
 			return fmt.Errorf("failed to save file: %w", err)
 		}
 		return nil
@@ -34,7 +58,7 @@ func rawSave(db db.DBClient) saveFunc {
 }
 
 // rawLoad creates a function that performs raw database load operations for file data.
-func rawLoad(db db.DBClient) func(ctx context.Context, p LoadParams) ([]*filedata.FileData, error) {
+func rawLoad(db db.DBClient, signer *rowsign.Signer) loadFunc {
 	return func(ctx context.Context, p LoadParams) ([]*filedata.FileData, error) {
 		var (
 			queryBuilder strings.Builder
@@ -44,7 +68,8 @@ func rawLoad(db db.DBClient) func(ctx context.Context, p LoadParams) ([]*filedat
 		)
 
 		queryBuilder.WriteString(`
-			SELECT id, user_id, storage_key, hash_sum, description, updated_at
+			SELECT id, user_id, storage_key, hash_sum, description, updated_at, signature, file_key, folder,
+			       COALESCE(size, 0)
 			FROM aegis_vault_keeper.files
 		`)
 
@@ -56,14 +81,23 @@ func rawLoad(db db.DBClient) func(ctx context.Context, p LoadParams) ([]*filedat
 		if p.UserID != uuid.Nil {
 			conditions = append(conditions, fmt.Sprintf("user_id = $%d", argIdx))
 			args = append(args, p.UserID)
-			// argIdx++ // Last usage, no need to increment
+			argIdx++
 		}
 		if len(conditions) == 0 {
 			return nil, errors.New("at least one of ID or UserID must be provided")
 		}
+		if p.After != uuid.Nil {
+			conditions = append(conditions, fmt.Sprintf("id > $%d", argIdx))
+			args = append(args, p.After)
+			argIdx++
+		}
 
 		queryBuilder.WriteString(" WHERE ")
 		queryBuilder.WriteString(strings.Join(conditions, " AND "))
+		if p.Limit > 0 {
+			queryBuilder.WriteString(fmt.Sprintf(" ORDER BY id LIMIT $%d", argIdx))
+			args = append(args, p.Limit)
+		}
 
 		rows, err := db.Query(ctx, queryBuilder.String(), args...)
 		if err != nil {
@@ -76,6 +110,8 @@ func rawLoad(db db.DBClient) func(ctx context.Context, p LoadParams) ([]*filedat
 		for rows.Next() {
 			// c holds a single file data entity during database row scanning.
 			var c filedata.FileData
+			// signature holds the stored row integrity signature.
+			var signature []byte
 			if err := rows.Scan(
 				&c.ID,
 				&c.UserID,
@@ -83,9 +119,16 @@ func rawLoad(db db.DBClient) func(ctx context.Context, p LoadParams) ([]*filedat
 				&c.HashSum,
 				&c.Description,
 				&c.UpdatedAt,
+				&signature,
+				&c.FileKey,
+				&c.Folder,
+				&c.Size,
 			); err != nil {
 				return nil, fmt.Errorf("failed to scan row: %w", err)
 			}
+			if err := signer.Verify(SignedTable, signature, rowValues(&c)...); err != nil {
+				return nil, fmt.Errorf("file row %s failed integrity verification: %w", c.ID, err)
+			}
 			fds = append(fds, &c)
 		}
 		if err := rows.Err(); err != nil {
@@ -6,8 +6,10 @@ import (
 
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/filedata"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/db"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/fieldcrypt"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/keyprv"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/middleware"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/rowsign"
 )
 
 // saveFunc defines the signature for file data save operations.
@@ -31,10 +33,27 @@ type Repository struct {
 }
 
 // NewRepository creates a new Repository with encryption/decryption middleware.
-func NewRepository(dbClient db.DBClient, keyProvider keyprv.UserKeyProvider) *Repository {
+// The items of bulk loads and saves are encrypted and decrypted concurrently on the pipeline.
+func NewRepository(
+	dbClient db.DBClient,
+	keyProvider keyprv.UserKeyProvider,
+	signer *rowsign.Signer,
+	retry middleware.RetryPolicy,
+	pipeline *fieldcrypt.Pipeline,
+) *Repository {
 	return &Repository{
-		save: middleware.Chain(rawSave(dbClient), encryptionMw(keyProvider)),
-		load: middleware.Chain(rawLoad(dbClient), decryptionMw(keyProvider)),
+		save: middleware.Chain(
+			rawSave(dbClient, signer),
+			middleware.RetryExecMw[saveFunc](retry),
+			userScopeSaveMw(dbClient),
+			encryptionMw(keyProvider),
+		),
+		load: middleware.Chain(
+			rawLoad(dbClient, signer),
+			middleware.RetryQueryMw[loadFunc](retry),
+			userScopeLoadMw(dbClient),
+			decryptionMw(keyProvider, pipeline),
+		),
 	}
 }
 
@@ -8,6 +8,8 @@ import (
 	"time"
 
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/filedata"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/middleware"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/rowsign"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -98,7 +100,7 @@ func TestNewRepository(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			repo := NewRepository(nil, nil)
+			repo := NewRepository(nil, nil, nil, middleware.RetryPolicy{}, nil)
 
 			assert.NotNil(t, repo)
 			assert.NotNil(t, repo.save)
@@ -182,7 +184,8 @@ func TestRepository_Save(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			repo := NewRepository(tt.dbClient, tt.keyProvider)
+			signer := rowsign.NewSigner([]byte("integrity-key"))
+			repo := NewRepository(tt.dbClient, tt.keyProvider, signer, middleware.RetryPolicy{}, nil)
 			err := repo.Save(context.Background(), tt.params)
 
 			if tt.expectedError != "" {
@@ -246,7 +249,8 @@ func TestRepository_Load(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			repo := NewRepository(tt.dbClient, tt.keyProvider)
+			signer := rowsign.NewSigner([]byte("integrity-key"))
+			repo := NewRepository(tt.dbClient, tt.keyProvider, signer, middleware.RetryPolicy{}, nil)
 			files, err := repo.Load(context.Background(), tt.params)
 
 			if tt.expectedError != "" {
@@ -0,0 +1,35 @@
+package filedata
+
+import (
+	"context"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/filedata"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/db"
+)
+
+// userScopeSaveMw creates middleware that runs file metadata saves within the owner RLS scope.
+func userScopeSaveMw(dbClient db.DBClient) saveMw {
+	return func(next saveFunc) saveFunc {
+		return func(ctx context.Context, p SaveParams) error {
+			return db.InUserScope(ctx, dbClient, p.Entity.UserID, func(ctx context.Context) error {
+				return next(ctx, p)
+			})
+		}
+	}
+}
+
+// userScopeLoadMw creates middleware that runs file metadata loads within the owner RLS scope.
+func userScopeLoadMw(dbClient db.DBClient) loadMw {
+	return func(next loadFunc) loadFunc {
+		return func(ctx context.Context, p LoadParams) ([]*filedata.FileData, error) {
+			// entities holds the file metadata records loaded inside the user scope.
+			var entities []*filedata.FileData
+			err := db.InUserScope(ctx, dbClient, p.UserID, func(ctx context.Context) error {
+				var err error
+				entities, err = next(ctx, p)
+				return err
+			})
+			return entities, err
+		}
+	}
+}
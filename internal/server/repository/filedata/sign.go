@@ -0,0 +1,34 @@
+package filedata
+
+import (
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/filedata"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/rowsign"
+)
+
+// SignedTable describes the files table columns covered by row integrity signatures.
+// The file_key and folder columns are left out: the wrapped key and the sealed folder path are authenticated
+// by their own AEAD bindings to user and file. The size column is informational only; downloads take their
+// length from the decrypted content.
+var SignedTable = rowsign.Table{
+	Name: "files",
+	Columns: []rowsign.Column{
+		{Name: "id", Kind: rowsign.KindUUID},
+		{Name: "user_id", Kind: rowsign.KindUUID},
+		{Name: "storage_key", Kind: rowsign.KindBytes},
+		{Name: "hash_sum", Kind: rowsign.KindBytes},
+		{Name: "description", Kind: rowsign.KindBytes},
+		{Name: "updated_at", Kind: rowsign.KindTime},
+	},
+}
+
+// rowValues returns the stored file column values in SignedTable column order.
+func rowValues(e *filedata.FileData) []any {
+	return []any{
+		e.ID,
+		e.UserID,
+		e.StorageKey,
+		e.HashSum,
+		e.Description,
+		e.UpdatedAt,
+	}
+}
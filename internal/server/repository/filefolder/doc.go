@@ -0,0 +1,5 @@
+// Package filefolder provides encrypted file folder persistence for the AegisVaultKeeper server.
+//
+// This package stores folder paths encrypted with the keys of their owners, signs every row for integrity
+// verification and confines queries to the row-level security scope of the owner.
+package filefolder
@@ -0,0 +1,67 @@
+package filefolder
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/filedata"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/fieldcrypt"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/keyprv"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/securebytes"
+)
+
+// itemType identifies file folders in the ownership binding of encrypted fields.
+const itemType = "folder"
+
+// encryptionMw creates a middleware that encrypts the folder path before saving.
+func encryptionMw(keyProvider keyprv.UserKeyProvider) saveMw {
+	return func(next saveFunc) saveFunc {
+		return func(ctx context.Context, p SaveParams) error {
+			k, err := keyProvider.UserKeyProvide(ctx, p.Entity.UserID)
+			if err != nil {
+				return fmt.Errorf("failed to provide user key: %w", err)
+			}
+			defer securebytes.Wipe(k)
+
+			copyEntity := *p.Entity
+			// b holds the ownership binding authenticated with the encrypted path.
+			b := fieldcrypt.Binding{ItemType: itemType, UserID: copyEntity.UserID, ItemID: copyEntity.ID}
+			if copyEntity.Path, err = b.Seal(k, "path", copyEntity.Path); err != nil {
+				return fmt.Errorf("failed to encrypt folder path: %w", err)
+			}
+
+			p.Entity = &copyEntity
+			return next(ctx, p)
+		}
+	}
+}
+
+// decryptionMw creates middleware that decrypts folder entities after loading from storage.
+func decryptionMw(keyProvider keyprv.UserKeyProvider) loadMw {
+	return func(next loadFunc) loadFunc {
+		return func(ctx context.Context, p LoadParams) ([]*filedata.Folder, error) {
+			entities, err := next(ctx, p)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load entities: %w", err)
+			}
+			if len(entities) == 0 {
+				return []*filedata.Folder{}, nil
+			}
+
+			k, err := keyProvider.UserKeyProvide(ctx, p.UserID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to provide user key: %w", err)
+			}
+			defer securebytes.Wipe(k)
+
+			for _, entity := range entities {
+				// b holds the ownership binding the loaded path must have been sealed with.
+				b := fieldcrypt.Binding{ItemType: itemType, UserID: p.UserID, ItemID: entity.ID}
+				if entity.Path, err = b.Open(k, "path", entity.Path); err != nil {
+					return nil, fmt.Errorf("failed to decrypt folder path: %w", err)
+				}
+			}
+			return entities, nil
+		}
+	}
+}
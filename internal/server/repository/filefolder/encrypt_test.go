@@ -0,0 +1,56 @@
+package filefolder
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/filedata"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncryptionRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	plain := filedata.Folder{
+		ID:     uuid.New(),
+		UserID: userID,
+		Path:   []byte("docs/taxes"),
+	}
+	keyProvider := &mockKeyProvider{}
+
+	// stored holds the folder as passed to the database.
+	var stored *filedata.Folder
+	save := encryptionMw(keyProvider)(func(_ context.Context, p SaveParams) error {
+		stored = p.Entity
+		return nil
+	})
+	require.NoError(t, save(context.Background(), SaveParams{Entity: &plain}))
+	assert.NotEqual(t, plain.Path, stored.Path)
+
+	t.Run("same owner", func(t *testing.T) {
+		t.Parallel()
+
+		sealed := *stored
+		load := decryptionMw(keyProvider)(func(context.Context, LoadParams) ([]*filedata.Folder, error) {
+			return []*filedata.Folder{&sealed}, nil
+		})
+		got, err := load(context.Background(), LoadParams{UserID: userID})
+		require.NoError(t, err)
+		require.Len(t, got, 1)
+		assert.Equal(t, plain, *got[0])
+	})
+
+	t.Run("other owner", func(t *testing.T) {
+		t.Parallel()
+
+		sealed := *stored
+		load := decryptionMw(keyProvider)(func(context.Context, LoadParams) ([]*filedata.Folder, error) {
+			return []*filedata.Folder{&sealed}, nil
+		})
+		_, err := load(context.Background(), LoadParams{UserID: uuid.New()})
+		require.Error(t, err)
+	})
+}
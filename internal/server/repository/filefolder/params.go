@@ -0,0 +1,20 @@
+package filefolder
+
+import (
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/filedata"
+	"github.com/google/uuid"
+)
+
+// SaveParams contains the parameters for saving a folder entity to the repository.
+type SaveParams struct {
+	// Entity contains the folder data to be persisted.
+	Entity *filedata.Folder
+}
+
+// LoadParams contains the parameters for loading folder entities from the repository.
+type LoadParams struct {
+	// ID contains the specific folder identifier for single record lookup (optional).
+	ID uuid.UUID
+	// UserID contains the user identifier for filtering folders by owner (required).
+	UserID uuid.UUID
+}
@@ -0,0 +1,98 @@
+package filefolder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/filedata"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/db"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/rowsign"
+	"github.com/google/uuid"
+)
+
+// rawSave creates a database save function that persists folder data directly to PostgreSQL.
+// Uses INSERT ON CONFLICT DO UPDATE for upsert behavior.
+func rawSave(db db.DBClient, signer *rowsign.Signer) saveFunc {
+	return func(ctx context.Context, p SaveParams) error {
+		e := p.Entity
+
+		signature, err := signer.Sign(SignedTable, rowValues(e)...)
+		if err != nil {
+			return fmt.Errorf("failed to sign folder row: %w", err)
+		}
+
+		query := `
+			INSERT INTO aegis_vault_keeper.file_folders (id, user_id, path, updated_at, signature)
+			VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (id) DO UPDATE SET
+			  path       = EXCLUDED.path,
+			  updated_at = EXCLUDED.updated_at,
+			  signature  = EXCLUDED.signature
+		`
+
+		if _, err := db.Exec(ctx, query, e.ID, e.UserID, e.Path, e.UpdatedAt, signature); err != nil {
+			return fmt.Errorf("failed to save folder: %w", err)
+		}
+		return nil
+	}
+}
+
+// rawLoad creates a database load function that retrieves folder data from PostgreSQL.
+// Supports filtering by user ID and specific folder ID.
+func rawLoad(db db.DBClient, signer *rowsign.Signer) loadFunc {
+	return func(ctx context.Context, p LoadParams) ([]*filedata.Folder, error) {
+		var (
+			queryBuilder strings.Builder
+			args         []any
+			conditions   []string
+			argIdx       = 1
+		)
+
+		queryBuilder.WriteString(`SELECT id, user_id, path, updated_at, signature FROM aegis_vault_keeper.file_folders`)
+
+		if p.ID != uuid.Nil {
+			conditions = append(conditions, fmt.Sprintf("id = $%d", argIdx))
+			args = append(args, p.ID)
+			argIdx++
+		}
+		if p.UserID != uuid.Nil {
+			conditions = append(conditions, fmt.Sprintf("user_id = $%d", argIdx))
+			args = append(args, p.UserID)
+			// argIdx++ // Last usage, no need to increment
+		}
+		if len(conditions) == 0 {
+			return nil, errors.New("at least one of ID or UserID must be provided")
+		}
+
+		queryBuilder.WriteString(" WHERE ")
+		queryBuilder.WriteString(strings.Join(conditions, " AND "))
+
+		rows, err := db.Query(ctx, queryBuilder.String(), args...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to execute query: %w", err)
+		}
+		defer func() { _ = rows.Close() }()
+
+		// folders collects all folder entities retrieved from the database.
+		var folders []*filedata.Folder
+		for rows.Next() {
+			// f holds a single folder entity during database row scanning.
+			var f filedata.Folder
+			// signature holds the stored row integrity signature.
+			var signature []byte
+			if err := rows.Scan(&f.ID, &f.UserID, &f.Path, &f.UpdatedAt, &signature); err != nil {
+				return nil, fmt.Errorf("failed to scan row: %w", err)
+			}
+			if err := signer.Verify(SignedTable, signature, rowValues(&f)...); err != nil {
+				return nil, fmt.Errorf("folder row %s failed integrity verification: %w", f.ID, err)
+			}
+			folders = append(folders, &f)
+		}
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("rows iteration error: %w", err)
+		}
+		return folders, nil
+	}
+}
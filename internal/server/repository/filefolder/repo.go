@@ -0,0 +1,72 @@
+package filefolder
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/filedata"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/db"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/keyprv"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/middleware"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/rowsign"
+)
+
+// saveFunc defines the signature for folder save operations.
+type saveFunc func(ctx context.Context, params SaveParams) error
+
+// saveMw defines middleware for save operations.
+type saveMw = middleware.Middleware[saveFunc]
+
+// loadFunc defines the signature for folder load operations.
+type loadFunc func(ctx context.Context, params LoadParams) ([]*filedata.Folder, error)
+
+// loadMw defines middleware for load operations.
+type loadMw = middleware.Middleware[loadFunc]
+
+// Repository provides encrypted file folder persistence with middleware support.
+type Repository struct {
+	// save is the function chain for saving folders with encryption middleware.
+	save saveFunc
+	// load is the function chain for loading folders with decryption middleware.
+	load loadFunc
+}
+
+// NewRepository creates a new Repository with encryption middleware and database backend.
+func NewRepository(
+	dbClient db.DBClient,
+	keyProvider keyprv.UserKeyProvider,
+	signer *rowsign.Signer,
+	retry middleware.RetryPolicy,
+) *Repository {
+	return &Repository{
+		save: middleware.Chain(
+			rawSave(dbClient, signer),
+			middleware.RetryExecMw[saveFunc](retry),
+			userScopeSaveMw(dbClient),
+			encryptionMw(keyProvider),
+		),
+		load: middleware.Chain(
+			rawLoad(dbClient, signer),
+			middleware.RetryQueryMw[loadFunc](retry),
+			userScopeLoadMw(dbClient),
+			decryptionMw(keyProvider),
+		),
+	}
+}
+
+// Save persists a folder with automatic encryption of its path.
+func (r *Repository) Save(ctx context.Context, params SaveParams) error {
+	if err := r.save(ctx, params); err != nil {
+		return fmt.Errorf("failed to save folder: %w", err)
+	}
+	return nil
+}
+
+// Load retrieves folders with automatic decryption of their paths.
+func (r *Repository) Load(ctx context.Context, params LoadParams) ([]*filedata.Folder, error) {
+	folders, err := r.load(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load folders: %w", err)
+	}
+	return folders, nil
+}
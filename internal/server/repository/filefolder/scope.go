@@ -0,0 +1,35 @@
+package filefolder
+
+import (
+	"context"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/filedata"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/db"
+)
+
+// userScopeSaveMw creates middleware that runs folder saves within the owner's row-level security scope.
+func userScopeSaveMw(dbClient db.DBClient) saveMw {
+	return func(next saveFunc) saveFunc {
+		return func(ctx context.Context, p SaveParams) error {
+			return db.InUserScope(ctx, dbClient, p.Entity.UserID, func(ctx context.Context) error {
+				return next(ctx, p)
+			})
+		}
+	}
+}
+
+// userScopeLoadMw creates middleware that runs folder loads within the owner's row-level security scope.
+func userScopeLoadMw(dbClient db.DBClient) loadMw {
+	return func(next loadFunc) loadFunc {
+		return func(ctx context.Context, p LoadParams) ([]*filedata.Folder, error) {
+			// entities holds the folders loaded inside the user scope.
+			var entities []*filedata.Folder
+			err := db.InUserScope(ctx, dbClient, p.UserID, func(ctx context.Context) error {
+				var err error
+				entities, err = next(ctx, p)
+				return err
+			})
+			return entities, err
+		}
+	}
+}
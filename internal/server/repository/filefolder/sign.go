@@ -0,0 +1,27 @@
+package filefolder
+
+import (
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/filedata"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/rowsign"
+)
+
+// SignedTable describes the file_folders table columns covered by row integrity signatures.
+var SignedTable = rowsign.Table{
+	Name: "file_folders",
+	Columns: []rowsign.Column{
+		{Name: "id", Kind: rowsign.KindUUID},
+		{Name: "user_id", Kind: rowsign.KindUUID},
+		{Name: "path", Kind: rowsign.KindBytes},
+		{Name: "updated_at", Kind: rowsign.KindTime},
+	},
+}
+
+// rowValues returns the stored folder column values in SignedTable column order.
+func rowValues(e *filedata.Folder) []any {
+	return []any{
+		e.ID,
+		e.UserID,
+		e.Path,
+		e.UpdatedAt,
+	}
+}
@@ -1,5 +1,6 @@
 // Package filestorage provides encrypted file content storage for the AegisVaultKeeper server.
 //
 // This package implements the repository pattern for actual file content storage,
-// handling encrypted file persistence and retrieval operations.
+// handling encrypted file persistence and retrieval operations. Every file is sealed
+// with its own random key, which is wrapped by the user key and kept in the file metadata.
 package filestorage
@@ -6,18 +6,21 @@ import (
 
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/crypto"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/keyprv"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/securebytes"
+	"github.com/google/uuid"
 )
 
 // encryptionMw creates middleware that encrypts file data before saving to storage.
 func encryptionMw(keyProvider keyprv.UserKeyProvider) saveMw {
 	return func(next saveFunc) saveFunc {
 		return func(ctx context.Context, p SaveParams) error {
-			k, err := keyProvider.UserKeyProvide(ctx, p.UserID)
+			k, err := dataKey(ctx, keyProvider, p.UserID, p.FileID, p.FileKey)
 			if err != nil {
-				return fmt.Errorf("failed to get user key: %w", err)
+				return err
 			}
+			defer securebytes.Wipe(k)
 
-			encryptedData, err := crypto.EncryptAESGCM(k, p.Data)
+			encryptedData, err := crypto.Seal(k, p.Data, nil)
 			if err != nil {
 				return fmt.Errorf("failed to encrypt file data: %w", err)
 			}
@@ -26,6 +29,8 @@ func encryptionMw(keyProvider keyprv.UserKeyProvider) saveMw {
 				UserID:     p.UserID,
 				StorageKey: p.StorageKey,
 				Data:       encryptedData,
+				FileKey:    p.FileKey,
+				FileID:     p.FileID,
 			}
 
 			return next(ctx, encryptedParams)
@@ -42,12 +47,13 @@ func decryptionMw(keyProvider keyprv.UserKeyProvider) loadMw {
 				return nil, err
 			}
 
-			k, err := keyProvider.UserKeyProvide(ctx, p.UserID)
+			k, err := dataKey(ctx, keyProvider, p.UserID, p.FileID, p.FileKey)
 			if err != nil {
-				return nil, fmt.Errorf("failed to get user key: %w", err)
+				return nil, err
 			}
+			defer securebytes.Wipe(k)
 
-			decryptedData, err := crypto.DecryptAESGCM(k, encryptedData)
+			decryptedData, err := crypto.Open(k, encryptedData, nil)
 			if err != nil {
 				return nil, fmt.Errorf("failed to decrypt file data: %w", err)
 			}
@@ -56,3 +62,23 @@ func decryptionMw(keyProvider keyprv.UserKeyProvider) loadMw {
 		}
 	}
 }
+
+// dataKey returns the key that encrypts the file content: the unwrapped per-file key when one is given,
+// or the user key itself for files stored before per-file keys were introduced.
+func dataKey(
+	ctx context.Context,
+	keyProvider keyprv.UserKeyProvider,
+	userID, fileID uuid.UUID,
+	wrapped []byte,
+) ([]byte, error) {
+	k, err := keyProvider.UserKeyProvide(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user key: %w", err)
+	}
+	if len(wrapped) == 0 {
+		return k, nil
+	}
+	defer securebytes.Wipe(k)
+
+	return unwrapFileKey(k, wrapped, userID, fileID)
+}
@@ -0,0 +1,81 @@
+package filestorage
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/crypto"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/securebytes"
+	"github.com/google/uuid"
+)
+
+// fileKeySize is the length in bytes of a per-file data encryption key.
+const fileKeySize = 32
+
+// ErrFileKeyRequired indicates that a rewrap was requested for a file stored without a per-file key.
+var ErrFileKeyRequired = errors.New("file key is required")
+
+// fileKeyAAD binds a wrapped file key to the owning user and the file it encrypts.
+func fileKeyAAD(userID, fileID uuid.UUID) []byte {
+	return crypto.BuildAAD("file_key", userID.String(), fileID.String())
+}
+
+// wrapFileKey seals a plain file key with the user key.
+func wrapFileKey(userKey, fileKey []byte, userID, fileID uuid.UUID) ([]byte, error) {
+	wrapped, err := crypto.Seal(userKey, fileKey, fileKeyAAD(userID, fileID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to wrap file key: %w", err)
+	}
+	return wrapped, nil
+}
+
+// unwrapFileKey opens a wrapped file key with the user key.
+func unwrapFileKey(userKey, wrapped []byte, userID, fileID uuid.UUID) ([]byte, error) {
+	fileKey, err := crypto.Open(userKey, wrapped, fileKeyAAD(userID, fileID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap file key: %w", err)
+	}
+	return fileKey, nil
+}
+
+// NewFileKey generates a unique file key and returns it wrapped by the current user key.
+// The wrapped key is meant to be stored in the file metadata and passed back on Save and Load.
+func (r *Repository) NewFileKey(ctx context.Context, params FileKeyParams) ([]byte, error) {
+	k, err := r.keyProvider.UserKeyProvide(ctx, params.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user key: %w", err)
+	}
+	defer securebytes.Wipe(k)
+
+	fileKey := make([]byte, fileKeySize)
+	defer securebytes.Wipe(fileKey)
+	if _, err := rand.Read(fileKey); err != nil {
+		return nil, fmt.Errorf("failed to generate file key: %w", err)
+	}
+
+	return wrapFileKey(k, fileKey, params.UserID, params.FileID)
+}
+
+// Rewrap unwraps a file key with the previous user key and wraps it again with the current one.
+// The stored file content is left untouched, so rotating the user key costs one small seal per file.
+func (r *Repository) Rewrap(ctx context.Context, params RewrapParams) ([]byte, error) {
+	if len(params.FileKey) == 0 {
+		return nil, ErrFileKeyRequired
+	}
+
+	fileKey, err := unwrapFileKey(params.OldUserKey, params.FileKey, params.UserID, params.FileID)
+	if err != nil {
+		return nil, err
+	}
+	defer securebytes.Wipe(fileKey)
+
+	k, err := r.keyProvider.UserKeyProvide(ctx, params.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user key: %w", err)
+	}
+	defer securebytes.Wipe(k)
+
+	return wrapFileKey(k, fileKey, params.UserID, params.FileID)
+}
@@ -0,0 +1,147 @@
+package filestorage
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepository_NewFileKey(t *testing.T) {
+	t.Parallel()
+
+	userKey := []byte("test-key-32-bytes-for-encryption")
+
+	tests := []struct {
+		keyProvider *mockKeyProvider
+		name        string
+		errContains string
+		wantErr     bool
+	}{
+		{
+			name:        "wraps a fresh key",
+			keyProvider: &mockKeyProvider{key: userKey},
+		},
+		{
+			name:        "key provider error",
+			keyProvider: &mockKeyProvider{err: errors.New("key provider error")},
+			wantErr:     true,
+			errContains: "failed to get user key",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := NewRepository(t.TempDir(), tt.keyProvider)
+			params := FileKeyParams{UserID: uuid.New(), FileID: uuid.New()}
+
+			wrapped, err := r.NewFileKey(context.Background(), params)
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errContains)
+				return
+			}
+			require.NoError(t, err)
+
+			fileKey, err := unwrapFileKey(userKey, wrapped, params.UserID, params.FileID)
+			require.NoError(t, err)
+			assert.Len(t, fileKey, fileKeySize)
+
+			other, err := r.NewFileKey(context.Background(), params)
+			require.NoError(t, err)
+			otherKey, err := unwrapFileKey(userKey, other, params.UserID, params.FileID)
+			require.NoError(t, err)
+			assert.NotEqual(t, fileKey, otherKey, "every file must get its own key")
+
+			_, err = unwrapFileKey(userKey, wrapped, params.UserID, uuid.New())
+			assert.Error(t, err, "wrapped key must be bound to its file")
+		})
+	}
+}
+
+func TestRepository_PerFileKeyRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	r := NewRepository(t.TempDir(), &mockKeyProvider{key: []byte("test-key-32-bytes-for-encryption")})
+	userID, fileID := uuid.New(), uuid.New()
+
+	wrapped, err := r.NewFileKey(ctx, FileKeyParams{UserID: userID, FileID: fileID})
+	require.NoError(t, err)
+
+	require.NoError(t, r.Save(ctx, SaveParams{
+		UserID:     userID,
+		FileID:     fileID,
+		FileKey:    wrapped,
+		StorageKey: "doc.txt",
+		Data:       []byte("secret content"),
+	}))
+
+	data, err := r.Load(ctx, LoadParams{UserID: userID, FileID: fileID, FileKey: wrapped, StorageKey: "doc.txt"})
+	require.NoError(t, err)
+	assert.Equal(t, []byte("secret content"), data)
+
+	_, err = r.Load(ctx, LoadParams{UserID: userID, FileID: fileID, StorageKey: "doc.txt"})
+	assert.Error(t, err, "the user key alone must not decrypt a file stored under a file key")
+}
+
+func TestRepository_Rewrap(t *testing.T) {
+	t.Parallel()
+
+	oldUserKey := []byte("old-key-32-bytes-for-encryption!")
+	newUserKey := []byte("new-key-32-bytes-for-encryption!")
+	userID, fileID := uuid.New(), uuid.New()
+
+	fileKey := make([]byte, fileKeySize)
+	wrapped, err := wrapFileKey(oldUserKey, fileKey, userID, fileID)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		errContains string
+		params      RewrapParams
+		wantErr     bool
+	}{
+		{
+			name:   "rewraps with the current user key",
+			params: RewrapParams{UserID: userID, FileID: fileID, FileKey: wrapped, OldUserKey: oldUserKey},
+		},
+		{
+			name:        "missing file key",
+			params:      RewrapParams{UserID: userID, FileID: fileID, OldUserKey: oldUserKey},
+			wantErr:     true,
+			errContains: ErrFileKeyRequired.Error(),
+		},
+		{
+			name:        "wrong old user key",
+			params:      RewrapParams{UserID: userID, FileID: fileID, FileKey: wrapped, OldUserKey: newUserKey},
+			wantErr:     true,
+			errContains: "failed to unwrap file key",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := NewRepository(t.TempDir(), &mockKeyProvider{key: newUserKey})
+
+			rewrapped, err := r.Rewrap(context.Background(), tt.params)
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errContains)
+				return
+			}
+			require.NoError(t, err)
+
+			got, err := unwrapFileKey(newUserKey, rewrapped, userID, fileID)
+			require.NoError(t, err)
+			assert.Equal(t, fileKey, got)
+		})
+	}
+}
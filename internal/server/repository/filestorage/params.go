@@ -1,6 +1,10 @@
 package filestorage
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 // SaveParams contains parameters for saving file data to storage.
 type SaveParams struct {
@@ -8,16 +12,24 @@ type SaveParams struct {
 	StorageKey string
 	// Data contains the file content to be saved.
 	Data []byte
+	// FileKey is the wrapped per-file key; empty for files encrypted directly with the user key.
+	FileKey []byte
 	// UserID identifies the user who owns the file.
 	UserID uuid.UUID
+	// FileID identifies the file metadata the wrapped key is bound to.
+	FileID uuid.UUID
 }
 
 // LoadParams contains parameters for loading file data from storage.
 type LoadParams struct {
 	// StorageKey identifies the file in storage.
 	StorageKey string
+	// FileKey is the wrapped per-file key; empty for files encrypted directly with the user key.
+	FileKey []byte
 	// UserID identifies the user who owns the file.
 	UserID uuid.UUID
+	// FileID identifies the file metadata the wrapped key is bound to.
+	FileID uuid.UUID
 }
 
 // DeleteParams contains parameters for deleting file data from storage.
@@ -27,3 +39,53 @@ type DeleteParams struct {
 	// UserID identifies the user who owns the file.
 	UserID uuid.UUID
 }
+
+// MoveParams contains parameters for moving file data to another storage key.
+type MoveParams struct {
+	// FromStorageKey identifies the file in storage.
+	FromStorageKey string
+	// ToStorageKey is the storage key the file is moved to; it must not be occupied.
+	ToStorageKey string
+	// UserID identifies the user who owns the file.
+	UserID uuid.UUID
+}
+
+// UsageParams contains parameters for calculating the storage space used by a user.
+type UsageParams struct {
+	// Exclude identifies a stored file left out of the measurement, such as one about to be replaced; optional.
+	Exclude string
+	// UserID identifies the user whose files are measured.
+	UserID uuid.UUID
+}
+
+// FileKeyParams contains parameters for generating a new per-file key.
+type FileKeyParams struct {
+	// UserID identifies the user whose key wraps the file key.
+	UserID uuid.UUID
+	// FileID identifies the file metadata the wrapped key is bound to.
+	FileID uuid.UUID
+}
+
+// RewrapParams contains parameters for re-wrapping a file key after a user key rotation.
+type RewrapParams struct {
+	// FileKey is the file key wrapped by the previous user key.
+	FileKey []byte
+	// OldUserKey is the previous user key the file key is currently wrapped with.
+	OldUserKey []byte
+	// UserID identifies the user who owns the file.
+	UserID uuid.UUID
+	// FileID identifies the file metadata the wrapped key is bound to.
+	FileID uuid.UUID
+}
+
+// Blob describes a stored file found by a storage scan.
+type Blob struct {
+	// ModTime is the time the file was last written.
+	ModTime time.Time
+	// StorageKey is the slash-separated path of the file within the user directory.
+	StorageKey string
+	// Size is the stored (encrypted) size of the file in bytes.
+	Size int64
+	// UserID identifies the user directory the file is stored in.
+	UserID uuid.UUID
+}
@@ -4,9 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
+	"syscall"
+
+	"github.com/google/uuid"
 )
 
 const (
@@ -14,8 +19,15 @@ const (
 	DirectoryPermission = 0o750
 	// FilePermission is the permission for creating files.
 	FilePermission = 0o600
+	// probeFileName names the file written by storage probes; it is skipped by scans like temporary files.
+	probeFileName = ".storage-probe.tmp"
+	// ioChunkSize is the amount of file data transferred between context cancellation checks.
+	ioChunkSize = 256 << 10
 )
 
+// ErrStorageKeyTaken indicates that a file is already stored under the target storage key of a move.
+var ErrStorageKeyTaken = errors.New("storage key is already taken")
+
 // rawSave creates a function that performs raw filesystem save operations.
 func rawSave(basePath string) saveFunc {
 	return func(ctx context.Context, p SaveParams) error {
@@ -36,7 +48,7 @@ func rawSave(basePath string) saveFunc {
 			return fmt.Errorf("failed to create file directory: %w", err)
 		}
 
-		if err := os.WriteFile(fullPath, p.Data, FilePermission); err != nil {
+		if err := writeFileContext(ctx, fullPath, p.Data); err != nil {
 			return fmt.Errorf("failed to write file: %w", err)
 		}
 
@@ -55,7 +67,7 @@ func rawLoad(basePath string) func(ctx context.Context, p LoadParams) ([]byte, e
 			return nil, errors.New("invalid storage key: path traversal detected")
 		}
 
-		data, err := os.ReadFile(fullPath)
+		data, err := readFileContext(ctx, fullPath)
 		if err != nil {
 			if os.IsNotExist(err) {
 				return nil, fmt.Errorf("file not found: %w", err)
@@ -85,19 +97,252 @@ func rawDelete(basePath string) func(ctx context.Context, p DeleteParams) error
 			return fmt.Errorf("failed to delete file: %w", err)
 		}
 
-		// Try to remove empty directories (best effort)
-		dir := filepath.Dir(fullPath)
-		for dir != userDir && dir != "." {
-			if err := os.Remove(dir); err != nil {
-				// Directory is not empty or other error, stop cleanup but don't fail
-				// This is expected behavior for non-empty directories
-				break // nolint:nilerr // Ignoring error in cleanup is intentional
+		removeEmptyDirs(filepath.Dir(fullPath), userDir)
+		return nil
+	}
+}
+
+// rawMove creates a function that moves a stored file to another storage key without overwriting.
+// The file is hard-linked under the new key before the old key is removed, so an occupied key is never replaced.
+func rawMove(basePath string) moveFunc {
+	return func(ctx context.Context, p MoveParams) error {
+		userDir := filepath.Join(basePath, p.UserID.String())
+		fromPath := filepath.Join(userDir, normalizeStorageKey(p.FromStorageKey))
+		toPath := filepath.Join(userDir, normalizeStorageKey(p.ToStorageKey))
+
+		if !strings.HasPrefix(fromPath, userDir) || !strings.HasPrefix(toPath, userDir) {
+			return errors.New("invalid storage key: path traversal detected")
+		}
+		if fromPath == toPath {
+			return nil
+		}
+
+		if err := os.MkdirAll(filepath.Dir(toPath), DirectoryPermission); err != nil {
+			return fmt.Errorf("failed to create file directory: %w", err)
+		}
+		if err := os.Link(fromPath, toPath); err != nil {
+			if errors.Is(err, fs.ErrExist) {
+				return ErrStorageKeyTaken
+			}
+			if errors.Is(err, fs.ErrNotExist) {
+				return fmt.Errorf("file not found: %w", err)
+			}
+			return fmt.Errorf("failed to link file: %w", err)
+		}
+		if err := os.Remove(fromPath); err != nil {
+			return fmt.Errorf("failed to remove moved file: %w", err)
+		}
+
+		removeEmptyDirs(filepath.Dir(fromPath), userDir)
+		return nil
+	}
+}
+
+// removeEmptyDirs removes dir and its empty parents up to, but excluding, userDir (best effort).
+func removeEmptyDirs(dir, userDir string) {
+	for dir != userDir && dir != "." {
+		if err := os.Remove(dir); err != nil {
+			// Directory is not empty or other error, stop cleanup but don't fail
+			// This is expected behavior for non-empty directories
+			break
+		}
+		dir = filepath.Dir(dir)
+	}
+}
+
+// rawUsage creates a function that sums the sizes of the stored files of a user, except the excluded one.
+func rawUsage(basePath string) usageFunc {
+	return func(ctx context.Context, p UsageParams) (int64, error) {
+		userDir := filepath.Join(basePath, p.UserID.String())
+		// excludePath is the path of the file left out of the measurement; empty when none is.
+		var excludePath string
+		if p.Exclude != "" {
+			excludePath = filepath.Join(userDir, normalizeStorageKey(p.Exclude))
+		}
+
+		var total int64
+		err := filepath.WalkDir(userDir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.Type().IsRegular() || path == excludePath {
+				return nil
+			}
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			total += info.Size()
+			return nil
+		})
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return 0, nil
+			}
+			return 0, fmt.Errorf("failed to measure user directory: %w", err)
+		}
+
+		return total, nil
+	}
+}
+
+// rawScan creates a function that lists the stored files of all users.
+// Entries outside user directories and temporary files of writes in progress are skipped.
+func rawScan(basePath string) scanFunc {
+	return func(ctx context.Context) ([]Blob, error) {
+		entries, err := os.ReadDir(basePath)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("failed to read storage directory: %w", err)
+		}
+
+		// blobs collects the stored files found in all user directories.
+		var blobs []Blob
+		for _, e := range entries {
+			userID, err := uuid.Parse(e.Name())
+			if err != nil || !e.IsDir() {
+				continue
+			}
+			userDir := filepath.Join(basePath, e.Name())
+			err = filepath.WalkDir(userDir, func(path string, d fs.DirEntry, err error) error {
+				if err != nil {
+					return err
+				}
+				if err := ctx.Err(); err != nil {
+					return fmt.Errorf("scan aborted: %w", err)
+				}
+				if !d.Type().IsRegular() || isTempFile(d.Name()) {
+					return nil
+				}
+				info, err := d.Info()
+				if err != nil {
+					return err
+				}
+				rel, err := filepath.Rel(userDir, path)
+				if err != nil {
+					return err
+				}
+				blobs = append(blobs, Blob{
+					UserID:     userID,
+					StorageKey: filepath.ToSlash(rel),
+					Size:       info.Size(),
+					ModTime:    info.ModTime(),
+				})
+				return nil
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to scan user directory: %w", err)
 			}
-			dir = filepath.Dir(dir)
 		}
 
-		return nil // nolint:nilerr // Ignoring error in cleanup is intentional
+		return blobs, nil
+	}
+}
+
+// rawProbe creates a function that writes, reads back and removes a probe file in the storage directory.
+func rawProbe(basePath string) probeFunc {
+	return func(ctx context.Context) error {
+		if err := os.MkdirAll(basePath, DirectoryPermission); err != nil {
+			return fmt.Errorf("failed to create storage directory: %w", err)
+		}
+
+		path := filepath.Join(basePath, probeFileName)
+		data := []byte(uuid.NewString())
+		if err := writeFileContext(ctx, path, data); err != nil {
+			return fmt.Errorf("failed to write probe file: %w", err)
+		}
+		got, err := readFileContext(ctx, path)
+		if err != nil {
+			return fmt.Errorf("failed to read probe file: %w", err)
+		}
+		if err := os.Remove(path); err != nil {
+			return fmt.Errorf("failed to remove probe file: %w", err)
+		}
+		if string(got) != string(data) {
+			return errors.New("probe file read back differs from the written one")
+		}
+		return nil
+	}
+}
+
+// isTempFile reports whether name is a temporary file created by writeFileContext.
+func isTempFile(name string) bool {
+	return strings.HasPrefix(name, ".") && strings.HasSuffix(name, ".tmp")
+}
+
+// writeFileContext writes data in chunks to a temporary file next to path and renames it into place.
+// Writing stops once ctx is done, leaving any previous file at path untouched.
+func writeFileContext(ctx context.Context, path string, data []byte) (err error) {
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		return &fs.PathError{Op: "open", Path: path, Err: syscall.EISDIR}
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tmp.Close()
+			_ = os.Remove(tmp.Name())
+		}
+	}()
+
+	for off := 0; off < len(data); off += ioChunkSize {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("write aborted: %w", err)
+		}
+		if _, err := tmp.Write(data[off:min(off+ioChunkSize, len(data))]); err != nil {
+			return fmt.Errorf("failed to write temporary file: %w", err)
+		}
+	}
+
+	if err := tmp.Chmod(FilePermission); err != nil {
+		return fmt.Errorf("failed to set file permission: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close temporary file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace file: %w", err)
+	}
+	return nil
+}
+
+// readFileContext reads the file at path in chunks, stopping once ctx is done.
+func readFileContext(ctx context.Context, path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	var size int64
+	if info, err := f.Stat(); err == nil {
+		size = info.Size()
 	}
+	data := make([]byte, 0, size)
+	buf := make([]byte, ioChunkSize)
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("read aborted: %w", err)
+		}
+		n, err := f.Read(buf)
+		data = append(data, buf[:n]...)
+		if errors.Is(err, io.EOF) {
+			return data, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// BlobKey returns the key a file stored under storageKey is reported with by Scan.
+func BlobKey(storageKey string) string {
+	return filepath.ToSlash(normalizeStorageKey(storageKey))
 }
 
 // normalizeStorageKey sanitizes storage keys to prevent path traversal attacks.
@@ -2,6 +2,7 @@ package filestorage
 
 import (
 	"context"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"testing"
@@ -393,3 +394,279 @@ func TestRawDelete(t *testing.T) {
 		})
 	}
 }
+
+func TestRawUsage(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.MustParse("550e8400-e29b-41d4-a716-446655440000")
+
+	tests := []struct {
+		files   map[string][]byte
+		name    string
+		exclude string
+		want    int64
+	}{
+		{
+			name: "sums nested files",
+			files: map[string][]byte{
+				"a.txt":            []byte("12345"),
+				"folder/b.txt":     []byte("123"),
+				"folder/sub/c.bin": make([]byte, 100),
+			},
+			want: 108,
+		},
+		{
+			name: "excluded file left out",
+			files: map[string][]byte{
+				"a.txt":            []byte("12345"),
+				"folder/sub/c.bin": make([]byte, 100),
+			},
+			exclude: "/folder/sub/c.bin",
+			want:    5,
+		},
+		{
+			name: "missing user directory",
+			want: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			basePath := t.TempDir()
+			for key, data := range tt.files {
+				fullPath := filepath.Join(basePath, userID.String(), key)
+				require.NoError(t, os.MkdirAll(filepath.Dir(fullPath), DirectoryPermission))
+				require.NoError(t, os.WriteFile(fullPath, data, FilePermission))
+			}
+			// Files of other users must not be counted.
+			otherDir := filepath.Join(basePath, uuid.NewString())
+			require.NoError(t, os.MkdirAll(otherDir, DirectoryPermission))
+			require.NoError(t, os.WriteFile(filepath.Join(otherDir, "x"), []byte("other"), FilePermission))
+
+			got, err := rawUsage(basePath)(context.Background(), UsageParams{UserID: userID, Exclude: tt.exclude})
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRawMove(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.MustParse("550e8400-e29b-41d4-a716-446655440000")
+
+	tests := []struct {
+		files       map[string][]byte
+		wantFiles   map[string][]byte
+		wantErrIs   error
+		name        string
+		errContains string
+		params      MoveParams
+		wantErr     bool
+	}{
+		{
+			name:      "moves into new directory and removes empty source directories",
+			files:     map[string][]byte{"inbox/sub/a.txt": []byte("data")},
+			params:    MoveParams{UserID: userID, FromStorageKey: "inbox/sub/a.txt", ToStorageKey: "docs/b.txt"},
+			wantFiles: map[string][]byte{"docs/b.txt": []byte("data")},
+		},
+		{
+			name:      "same key is a no-op",
+			files:     map[string][]byte{"a.txt": []byte("data")},
+			params:    MoveParams{UserID: userID, FromStorageKey: "a.txt", ToStorageKey: "./a.txt"},
+			wantFiles: map[string][]byte{"a.txt": []byte("data")},
+		},
+		{
+			name:      "occupied target is not overwritten",
+			files:     map[string][]byte{"a.txt": []byte("data"), "b.txt": []byte("other")},
+			params:    MoveParams{UserID: userID, FromStorageKey: "a.txt", ToStorageKey: "b.txt"},
+			wantFiles: map[string][]byte{"a.txt": []byte("data"), "b.txt": []byte("other")},
+			wantErr:   true,
+			wantErrIs: ErrStorageKeyTaken,
+		},
+		{
+			name:        "missing source",
+			params:      MoveParams{UserID: userID, FromStorageKey: "a.txt", ToStorageKey: "b.txt"},
+			wantErr:     true,
+			errContains: "file not found",
+		},
+		{
+			name:        "path traversal attack",
+			files:       map[string][]byte{"a.txt": []byte("data")},
+			params:      MoveParams{UserID: userID, FromStorageKey: "a.txt", ToStorageKey: "../../../etc/passwd"},
+			wantFiles:   map[string][]byte{"a.txt": []byte("data")},
+			wantErr:     true,
+			errContains: "path traversal detected",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			basePath := t.TempDir()
+			userDir := filepath.Join(basePath, userID.String())
+			require.NoError(t, os.MkdirAll(userDir, DirectoryPermission))
+			for key, data := range tt.files {
+				fullPath := filepath.Join(userDir, key)
+				require.NoError(t, os.MkdirAll(filepath.Dir(fullPath), DirectoryPermission))
+				require.NoError(t, os.WriteFile(fullPath, data, FilePermission))
+			}
+
+			err := rawMove(basePath)(context.Background(), tt.params)
+			if tt.wantErr {
+				require.Error(t, err)
+				if tt.wantErrIs != nil {
+					assert.ErrorIs(t, err, tt.wantErrIs)
+				}
+				if tt.errContains != "" {
+					assert.Contains(t, err.Error(), tt.errContains)
+				}
+			} else {
+				require.NoError(t, err)
+			}
+
+			// got collects the files left in the user directory.
+			got := map[string][]byte{}
+			require.NoError(t, filepath.WalkDir(userDir, func(path string, d fs.DirEntry, err error) error {
+				if err != nil || d.IsDir() {
+					return err
+				}
+				rel, err := filepath.Rel(userDir, path)
+				if err != nil {
+					return err
+				}
+				data, err := os.ReadFile(path)
+				got[filepath.ToSlash(rel)] = data
+				return err
+			}))
+			if len(tt.wantFiles) == 0 {
+				assert.Empty(t, got)
+			} else {
+				assert.Equal(t, tt.wantFiles, got)
+			}
+			assert.NoDirExists(t, filepath.Join(userDir, "inbox"))
+		})
+	}
+}
+
+func TestRawScan(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.MustParse("550e8400-e29b-41d4-a716-446655440000")
+
+	tests := []struct {
+		files map[string][]byte
+		name  string
+		want  []string
+	}{
+		{
+			name: "lists nested files of user directories",
+			files: map[string][]byte{
+				userID.String() + "/a.txt":            []byte("12345"),
+				userID.String() + "/folder/sub/c.bin": make([]byte, 10),
+			},
+			want: []string{"a.txt", "folder/sub/c.bin"},
+		},
+		{
+			name: "skips temporary files and foreign entries",
+			files: map[string][]byte{
+				userID.String() + "/a.txt":          []byte("12345"),
+				userID.String() + "/.a.txt.123.tmp": []byte("partial"),
+				"not-a-user/b.txt":                  []byte("x"),
+				"stray.txt":                         []byte("x"),
+			},
+			want: []string{"a.txt"},
+		},
+		{
+			name: "empty storage",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			basePath := t.TempDir()
+			for key, data := range tt.files {
+				fullPath := filepath.Join(basePath, key)
+				require.NoError(t, os.MkdirAll(filepath.Dir(fullPath), DirectoryPermission))
+				require.NoError(t, os.WriteFile(fullPath, data, FilePermission))
+			}
+
+			blobs, err := rawScan(basePath)(context.Background())
+			require.NoError(t, err)
+
+			// got holds the storage keys of the scanned files.
+			var got []string
+			for _, b := range blobs {
+				assert.Equal(t, userID, b.UserID)
+				got = append(got, b.StorageKey)
+			}
+			assert.ElementsMatch(t, tt.want, got)
+		})
+	}
+}
+
+func TestRawScan_MissingBasePath(t *testing.T) {
+	t.Parallel()
+
+	blobs, err := rawScan(filepath.Join(t.TempDir(), "missing"))(context.Background())
+
+	require.NoError(t, err)
+	assert.Empty(t, blobs)
+}
+
+func TestRawProbe(t *testing.T) {
+	t.Parallel()
+
+	basePath := filepath.Join(t.TempDir(), "storage")
+	require.NoError(t, rawProbe(basePath)(context.Background()))
+	entries, err := os.ReadDir(basePath)
+	require.NoError(t, err)
+	assert.Empty(t, entries, "the probe file is removed")
+
+	notDir := filepath.Join(t.TempDir(), "file")
+	require.NoError(t, os.WriteFile(notDir, []byte("x"), FilePermission))
+	assert.Error(t, rawProbe(notDir)(context.Background()))
+}
+
+func TestBlobKey(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "folder/file.txt", BlobKey("./folder//file.txt"))
+	assert.Equal(t, "file.txt", BlobKey("/file.txt"))
+}
+
+func TestRawSaveLoad_CanceledContext(t *testing.T) {
+	t.Parallel()
+
+	basePath := t.TempDir()
+	userID := uuid.MustParse("550e8400-e29b-41d4-a716-446655440000")
+	original := []byte("original content")
+	require.NoError(t, rawSave(basePath)(context.Background(), SaveParams{
+		UserID: userID, StorageKey: "file.dat", Data: original,
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := rawSave(basePath)(ctx, SaveParams{
+		UserID: userID, StorageKey: "file.dat", Data: make([]byte, 2*ioChunkSize),
+	})
+	require.ErrorIs(t, err, context.Canceled)
+
+	_, err = rawLoad(basePath)(ctx, LoadParams{UserID: userID, StorageKey: "file.dat"})
+	require.ErrorIs(t, err, context.Canceled)
+
+	entries, err := os.ReadDir(filepath.Join(basePath, userID.String()))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "aborted write must not leave temporary files")
+
+	data, err := rawLoad(basePath)(context.Background(), LoadParams{UserID: userID, StorageKey: "file.dat"})
+	require.NoError(t, err)
+	assert.Equal(t, original, data)
+}
@@ -23,6 +23,18 @@ type loadMw = middleware.Middleware[loadFunc]
 // deleteFunc defines the signature for file storage delete operations.
 type deleteFunc func(ctx context.Context, params DeleteParams) error
 
+// moveFunc defines the signature for file storage move operations.
+type moveFunc func(ctx context.Context, params MoveParams) error
+
+// usageFunc defines the signature for file storage usage calculations.
+type usageFunc func(ctx context.Context, params UsageParams) (int64, error)
+
+// scanFunc defines the signature for listing all stored files.
+type scanFunc func(ctx context.Context) ([]Blob, error)
+
+// probeFunc defines the signature for checking that the storage accepts, returns and removes files.
+type probeFunc func(ctx context.Context) error
+
 // Repository provides encrypted filesystem storage operations using middleware pattern.
 type Repository struct {
 	// save is the function chain for saving file data with encryption middleware.
@@ -31,14 +43,29 @@ type Repository struct {
 	load loadFunc
 	// delete is the function for removing files from the filesystem.
 	delete deleteFunc
+	// move is the function for moving files to another storage key.
+	move moveFunc
+	// usage is the function for measuring the stored files of a user.
+	usage usageFunc
+	// scan is the function for listing the stored files of all users.
+	scan scanFunc
+	// probe is the function for checking that the storage directory is usable.
+	probe probeFunc
+	// keyProvider supplies the user keys that wrap per-file keys.
+	keyProvider keyprv.UserKeyProvider
 }
 
 // NewRepository creates a new Repository with encryption/decryption middleware for filesystem storage.
 func NewRepository(basePath string, keyProvider keyprv.UserKeyProvider) *Repository {
 	return &Repository{
-		save:   middleware.Chain(rawSave(basePath), encryptionMw(keyProvider)),
-		load:   middleware.Chain(rawLoad(basePath), decryptionMw(keyProvider)),
-		delete: rawDelete(basePath),
+		save:        middleware.Chain(rawSave(basePath), encryptionMw(keyProvider)),
+		load:        middleware.Chain(rawLoad(basePath), decryptionMw(keyProvider)),
+		delete:      rawDelete(basePath),
+		move:        rawMove(basePath),
+		usage:       rawUsage(basePath),
+		scan:        rawScan(basePath),
+		probe:       rawProbe(basePath),
+		keyProvider: keyProvider,
 	}
 }
 
@@ -66,3 +93,39 @@ func (r *Repository) Delete(ctx context.Context, params DeleteParams) error {
 	}
 	return nil
 }
+
+// Move moves file data to another storage key of the same user without re-encrypting it.
+// It returns ErrStorageKeyTaken when the target storage key is occupied.
+func (r *Repository) Move(ctx context.Context, params MoveParams) error {
+	if err := r.move(ctx, params); err != nil {
+		return fmt.Errorf("failed to move file in storage: %w", err)
+	}
+	return nil
+}
+
+// Usage returns the number of bytes the stored (encrypted) files of a user occupy.
+func (r *Repository) Usage(ctx context.Context, params UsageParams) (int64, error) {
+	n, err := r.usage(ctx, params)
+	if err != nil {
+		return 0, fmt.Errorf("failed to calculate storage usage: %w", err)
+	}
+	return n, nil
+}
+
+// Scan lists the stored files of all users without reading or decrypting their content.
+func (r *Repository) Scan(ctx context.Context) ([]Blob, error) {
+	blobs, err := r.scan(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan storage: %w", err)
+	}
+	return blobs, nil
+}
+
+// Probe checks that the storage directory accepts, returns and removes files by writing, reading back and
+// deleting a probe file. The probe is named like a temporary file, so scans never report it.
+func (r *Repository) Probe(ctx context.Context) error {
+	if err := r.probe(ctx); err != nil {
+		return fmt.Errorf("storage probe failed: %w", err)
+	}
+	return nil
+}
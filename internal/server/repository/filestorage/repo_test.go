@@ -1,6 +1,7 @@
 package filestorage
 
 import (
+	"bytes"
 	"context"
 	"testing"
 
@@ -21,7 +22,7 @@ func (m *mockKeyProvider) UserKeyProvide(ctx context.Context, userID uuid.UUID)
 	if m.err != nil {
 		return nil, m.err
 	}
-	return m.key, nil
+	return bytes.Clone(m.key), nil
 }
 
 func TestNewRepository(t *testing.T) {
@@ -54,6 +55,8 @@ func TestNewRepository(t *testing.T) {
 			assert.NotNil(t, repo.save)
 			assert.NotNil(t, repo.load)
 			assert.NotNil(t, repo.delete)
+			assert.NotNil(t, repo.usage)
+			assert.NotNil(t, repo.probe)
 		})
 	}
 }
@@ -0,0 +1,5 @@
+// Package group provides user group persistence for the AegisVaultKeeper server.
+//
+// This package implements storage of the groups an enterprise directory provisions
+// through SCIM and of their memberships in PostgreSQL.
+package group
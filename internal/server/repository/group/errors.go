@@ -0,0 +1,12 @@
+package group
+
+import "errors"
+
+var (
+	// ErrGroupNotFound indicates that the requested group was not found in the repository.
+	ErrGroupNotFound = errors.New("group not found")
+	// ErrGroupAlreadyExists indicates that a group with the same display name already exists.
+	ErrGroupAlreadyExists = errors.New("group already exists")
+	// ErrGroupMemberNotFound indicates that a group member references an unknown user.
+	ErrGroupMemberNotFound = errors.New("group member not found")
+)
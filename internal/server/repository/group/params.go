@@ -0,0 +1,40 @@
+package group
+
+import (
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/group"
+	"github.com/google/uuid"
+)
+
+// SaveParams contains the parameters for saving a group to the repository.
+type SaveParams struct {
+	// Entity contains the group to be created or updated; its members are not saved.
+	Entity *group.Group
+}
+
+// LoadParams contains the parameters for loading groups from the repository.
+type LoadParams struct {
+	// DisplayName restricts the listing to the group with this display name, when set.
+	DisplayName string
+	// ExternalID restricts the listing to groups with this directory identifier, when set.
+	ExternalID string
+	// Offset specifies how many matching groups to skip.
+	Offset int
+	// Limit specifies the maximum number of groups to return.
+	Limit int
+	// ID selects a single group; uuid.Nil lists groups by the other parameters.
+	ID uuid.UUID
+}
+
+// MembersParams contains the parameters for changing the members of a group.
+type MembersParams struct {
+	// UserIDs lists the users to add, remove or set as the members.
+	UserIDs []uuid.UUID
+	// GroupID identifies the group.
+	GroupID uuid.UUID
+}
+
+// DeleteParams contains the parameters for deleting a group from the repository.
+type DeleteParams struct {
+	// ID identifies the group to delete.
+	ID uuid.UUID
+}
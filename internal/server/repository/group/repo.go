@@ -0,0 +1,236 @@
+package group
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/group"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/db"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// PostgreSQL error codes of the constraint violations mapped to repository errors.
+const (
+	// uniqueViolation is raised when a group with the same display name exists.
+	uniqueViolation = "23505"
+	// foreignKeyViolation is raised when a member references an unknown user.
+	foreignKeyViolation = "23503"
+)
+
+// Repository provides user group persistence operations.
+type Repository struct {
+	// db is the database client used for group operations.
+	db db.DBClient
+}
+
+// NewRepository creates a new Repository with the provided database client.
+func NewRepository(dbClient db.DBClient) *Repository {
+	return &Repository{db: dbClient}
+}
+
+// Save creates the group or updates its display name and external ID. Members are changed with
+// ReplaceMembers, AddMembers and RemoveMembers.
+func (r *Repository) Save(ctx context.Context, params SaveParams) error {
+	e := params.Entity
+
+	var externalID sql.NullString
+	if e.ExternalID != "" {
+		externalID = sql.NullString{String: e.ExternalID, Valid: true}
+	}
+
+	query := `
+		INSERT INTO aegis_vault_keeper.user_groups (id, display_name, external_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (id) DO UPDATE SET
+			display_name = EXCLUDED.display_name,
+			external_id = EXCLUDED.external_id,
+			updated_at = EXCLUDED.updated_at
+	`
+	if _, err := r.db.Exec(ctx, query, e.ID, e.DisplayName, externalID, e.CreatedAt, e.UpdatedAt); err != nil {
+		return mapConstraintError("failed to save group", err)
+	}
+	return nil
+}
+
+// Load retrieves a page of groups with their members ordered by display name, and the number of groups
+// matching the parameters. Members deprovisioned from the directory are omitted.
+// Loading by ID returns ErrGroupNotFound when the group does not exist.
+func (r *Repository) Load(ctx context.Context, params LoadParams) ([]*group.Group, int, error) {
+	var (
+		where strings.Builder
+		args  []any
+	)
+	where.WriteString(" WHERE TRUE")
+	if params.ID != uuid.Nil {
+		args = append(args, params.ID)
+		fmt.Fprintf(&where, " AND id = $%d", len(args))
+	}
+	if params.DisplayName != "" {
+		args = append(args, params.DisplayName)
+		fmt.Fprintf(&where, " AND display_name = $%d", len(args))
+	}
+	if params.ExternalID != "" {
+		args = append(args, params.ExternalID)
+		fmt.Fprintf(&where, " AND external_id = $%d", len(args))
+	}
+
+	total := 1
+	if params.ID == uuid.Nil {
+		countQuery := "SELECT COUNT(*) FROM aegis_vault_keeper.user_groups" + where.String()
+		if err := r.db.QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
+			return nil, 0, fmt.Errorf("failed to count groups: %w", err)
+		}
+		if total == 0 || params.Limit <= 0 {
+			return nil, total, nil
+		}
+		args = append(args, params.Offset, params.Limit)
+		fmt.Fprintf(&where, " ORDER BY display_name, id OFFSET $%d LIMIT $%d", len(args)-1, len(args))
+	}
+
+	query := "SELECT id, display_name, external_id, created_at, updated_at FROM aegis_vault_keeper.user_groups" +
+		where.String()
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to load groups: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var groups []*group.Group
+	for rows.Next() {
+		var (
+			g          group.Group
+			externalID sql.NullString
+		)
+		if err := rows.Scan(&g.ID, &g.DisplayName, &externalID, &g.CreatedAt, &g.UpdatedAt); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan group: %w", err)
+		}
+		g.ExternalID = externalID.String
+		groups = append(groups, &g)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to iterate groups: %w", err)
+	}
+	if params.ID != uuid.Nil && len(groups) == 0 {
+		return nil, 0, ErrGroupNotFound
+	}
+	if len(groups) == 0 {
+		return groups, total, nil
+	}
+
+	if err := r.loadMembers(ctx, groups); err != nil {
+		return nil, 0, err
+	}
+	return groups, total, nil
+}
+
+// ReplaceMembers sets the members of the group to exactly the listed users.
+func (r *Repository) ReplaceMembers(ctx context.Context, params MembersParams) error {
+	query := `DELETE FROM aegis_vault_keeper.user_group_members WHERE group_id = $1`
+	if _, err := r.db.Exec(ctx, query, params.GroupID); err != nil {
+		return fmt.Errorf("failed to clear group members: %w", err)
+	}
+	return r.AddMembers(ctx, params)
+}
+
+// AddMembers adds the listed users to the group; users already in the group are skipped.
+func (r *Repository) AddMembers(ctx context.Context, params MembersParams) error {
+	if len(params.UserIDs) == 0 {
+		return nil
+	}
+	query := `
+		INSERT INTO aegis_vault_keeper.user_group_members (group_id, user_id)
+		SELECT $1, unnest($2::uuid[])
+		ON CONFLICT DO NOTHING
+	`
+	if _, err := r.db.Exec(ctx, query, params.GroupID, params.UserIDs); err != nil {
+		return mapConstraintError("failed to add group members", err)
+	}
+	return nil
+}
+
+// RemoveMembers removes the listed users from the group.
+func (r *Repository) RemoveMembers(ctx context.Context, params MembersParams) error {
+	if len(params.UserIDs) == 0 {
+		return nil
+	}
+	query := `
+		DELETE FROM aegis_vault_keeper.user_group_members
+		WHERE group_id = $1 AND user_id = ANY($2::uuid[])
+	`
+	if _, err := r.db.Exec(ctx, query, params.GroupID, params.UserIDs); err != nil {
+		return fmt.Errorf("failed to remove group members: %w", err)
+	}
+	return nil
+}
+
+// Delete removes the group together with its memberships.
+func (r *Repository) Delete(ctx context.Context, params DeleteParams) error {
+	query := `DELETE FROM aegis_vault_keeper.user_groups WHERE id = $1`
+	res, err := r.db.Exec(ctx, query, params.ID)
+	if err != nil {
+		return fmt.Errorf("failed to delete group: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check deleted groups: %w", err)
+	}
+	if n == 0 {
+		return ErrGroupNotFound
+	}
+	return nil
+}
+
+// loadMembers fills in the members of the groups that are still provisioned.
+func (r *Repository) loadMembers(ctx context.Context, groups []*group.Group) error {
+	ids := make([]uuid.UUID, 0, len(groups))
+	byID := make(map[uuid.UUID]*group.Group, len(groups))
+	for _, g := range groups {
+		ids = append(ids, g.ID)
+		byID[g.ID] = g
+	}
+
+	query := `
+		SELECT m.group_id, m.user_id
+		FROM aegis_vault_keeper.user_group_members m
+		JOIN aegis_vault_keeper.auth_users u ON u.id = m.user_id
+		WHERE m.group_id = ANY($1::uuid[]) AND u.deprovisioned_at IS NULL
+		ORDER BY u.login
+	`
+	rows, err := r.db.Query(ctx, query, ids)
+	if err != nil {
+		return fmt.Errorf("failed to load group members: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var groupID, userID uuid.UUID
+		if err := rows.Scan(&groupID, &userID); err != nil {
+			return fmt.Errorf("failed to scan group member: %w", err)
+		}
+		if g, ok := byID[groupID]; ok {
+			g.Members = append(g.Members, userID)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate group members: %w", err)
+	}
+	return nil
+}
+
+// mapConstraintError maps constraint violations to repository errors and wraps other errors with msg.
+func mapConstraintError(msg string, err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case uniqueViolation:
+			return ErrGroupAlreadyExists
+		case foreignKeyViolation:
+			return ErrGroupMemberNotFound
+		}
+	}
+	return fmt.Errorf("%s: %w", msg, err)
+}
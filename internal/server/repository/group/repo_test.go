@@ -0,0 +1,291 @@
+package group
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/group"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/db"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockResult implements sql.Result for testing.
+type mockResult struct {
+	rowsAffected int64
+}
+
+func (m mockResult) LastInsertId() (int64, error) { return 0, nil }
+func (m mockResult) RowsAffected() (int64, error) { return m.rowsAffected, nil }
+
+// mockDBClient implements db.DBClient for testing.
+type mockDBClient struct {
+	execFunc  func(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	queryFunc func(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+func (m *mockDBClient) Exec(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	if m.execFunc != nil {
+		return m.execFunc(ctx, query, args...)
+	}
+	return mockResult{rowsAffected: 1}, nil
+}
+
+func (m *mockDBClient) Query(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	if m.queryFunc != nil {
+		return m.queryFunc(ctx, query, args...)
+	}
+	return nil, errors.New("mock not configured")
+}
+
+func (m *mockDBClient) QueryRow(context.Context, string, ...interface{}) *sql.Row {
+	return nil
+}
+
+func (m *mockDBClient) BeginTx(context.Context, *sql.TxOptions) (*sql.Tx, error) {
+	return nil, errors.New("mock not configured")
+}
+
+func (m *mockDBClient) CommitTx(*sql.Tx) error {
+	return nil
+}
+
+func (m *mockDBClient) RollbackTx(*sql.Tx) error {
+	return nil
+}
+
+// Ensure mockDBClient implements db.DBClient.
+var _ db.DBClient = (*mockDBClient)(nil)
+
+func TestRepository_Save(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		execErr    error
+		wantErr    error
+		entity     *group.Group
+		name       string
+		wantExtID  sql.NullString
+		wantErrAny bool
+	}{
+		{
+			name:      "with external ID",
+			entity:    &group.Group{ID: uuid.New(), DisplayName: "Engineering", ExternalID: "00g1", CreatedAt: now},
+			wantExtID: sql.NullString{String: "00g1", Valid: true},
+		},
+		{
+			name:   "without external ID",
+			entity: &group.Group{ID: uuid.New(), DisplayName: "Finance", CreatedAt: now},
+		},
+		{
+			name:    "duplicate display name",
+			entity:  &group.Group{ID: uuid.New(), DisplayName: "Engineering", CreatedAt: now},
+			execErr: &pgconn.PgError{Code: "23505"},
+			wantErr: ErrGroupAlreadyExists,
+		},
+		{
+			name:       "exec error",
+			entity:     &group.Group{ID: uuid.New(), DisplayName: "Engineering", CreatedAt: now},
+			execErr:    errors.New("connection refused"),
+			wantErrAny: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var gotArgs []interface{}
+			client := &mockDBClient{
+				execFunc: func(_ context.Context, query string, args ...interface{}) (sql.Result, error) {
+					assert.Contains(t, query, "INSERT INTO aegis_vault_keeper.user_groups")
+					assert.Contains(t, query, "ON CONFLICT (id) DO UPDATE SET")
+					gotArgs = args
+					return mockResult{rowsAffected: 1}, tt.execErr
+				},
+			}
+
+			err := NewRepository(client).Save(context.Background(), SaveParams{Entity: tt.entity})
+
+			switch {
+			case tt.wantErr != nil:
+				assert.ErrorIs(t, err, tt.wantErr)
+			case tt.wantErrAny:
+				assert.ErrorIs(t, err, tt.execErr)
+			default:
+				require.NoError(t, err)
+				assert.Equal(t, []interface{}{
+					tt.entity.ID, tt.entity.DisplayName, tt.wantExtID, tt.entity.CreatedAt, tt.entity.UpdatedAt,
+				}, gotArgs)
+			}
+		})
+	}
+}
+
+func TestRepository_LoadByID(t *testing.T) {
+	t.Parallel()
+
+	id := uuid.New()
+	queryErr := errors.New("query error")
+	client := &mockDBClient{
+		queryFunc: func(_ context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+			assert.Contains(t, query, "FROM aegis_vault_keeper.user_groups WHERE TRUE AND id = $1")
+			assert.NotContains(t, query, "LIMIT")
+			assert.Equal(t, []interface{}{id}, args)
+			return nil, queryErr
+		},
+	}
+
+	_, _, err := NewRepository(client).Load(context.Background(), LoadParams{ID: id, Limit: 10})
+
+	assert.ErrorIs(t, err, queryErr)
+}
+
+func TestRepository_Members(t *testing.T) {
+	t.Parallel()
+
+	groupID := uuid.New()
+	userIDs := []uuid.UUID{uuid.New(), uuid.New()}
+
+	tests := []struct {
+		call      func(*Repository, MembersParams) error
+		execErr   error
+		wantErr   error
+		name      string
+		wantQuery []string
+	}{
+		{
+			name: "add members",
+			call: func(r *Repository, p MembersParams) error {
+				return r.AddMembers(context.Background(), p)
+			},
+			wantQuery: []string{"INSERT INTO aegis_vault_keeper.user_group_members"},
+		},
+		{
+			name: "add unknown member",
+			call: func(r *Repository, p MembersParams) error {
+				return r.AddMembers(context.Background(), p)
+			},
+			execErr:   &pgconn.PgError{Code: "23503"},
+			wantErr:   ErrGroupMemberNotFound,
+			wantQuery: []string{"INSERT INTO aegis_vault_keeper.user_group_members"},
+		},
+		{
+			name: "remove members",
+			call: func(r *Repository, p MembersParams) error {
+				return r.RemoveMembers(context.Background(), p)
+			},
+			wantQuery: []string{"DELETE FROM aegis_vault_keeper.user_group_members"},
+		},
+		{
+			name: "replace members",
+			call: func(r *Repository, p MembersParams) error {
+				return r.ReplaceMembers(context.Background(), p)
+			},
+			wantQuery: []string{
+				"DELETE FROM aegis_vault_keeper.user_group_members WHERE group_id = $1",
+				"INSERT INTO aegis_vault_keeper.user_group_members",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var gotQueries []string
+			client := &mockDBClient{
+				execFunc: func(_ context.Context, query string, args ...interface{}) (sql.Result, error) {
+					gotQueries = append(gotQueries, query)
+					assert.Equal(t, groupID, args[0])
+					if len(args) > 1 {
+						assert.Equal(t, userIDs, args[1])
+					}
+					return mockResult{rowsAffected: 1}, tt.execErr
+				},
+			}
+
+			err := tt.call(NewRepository(client), MembersParams{GroupID: groupID, UserIDs: userIDs})
+
+			require.ErrorIs(t, err, tt.wantErr)
+			require.Len(t, gotQueries, len(tt.wantQuery))
+			for i, want := range tt.wantQuery {
+				assert.Contains(t, gotQueries[i], want)
+			}
+		})
+	}
+}
+
+func TestRepository_MembersEmpty(t *testing.T) {
+	t.Parallel()
+
+	client := &mockDBClient{
+		execFunc: func(context.Context, string, ...interface{}) (sql.Result, error) {
+			t.Fatal("no query expected for an empty member list")
+			return nil, nil
+		},
+	}
+	repo := NewRepository(client)
+	params := MembersParams{GroupID: uuid.New()}
+
+	require.NoError(t, repo.AddMembers(context.Background(), params))
+	require.NoError(t, repo.RemoveMembers(context.Background(), params))
+}
+
+func TestRepository_Delete(t *testing.T) {
+	t.Parallel()
+
+	params := DeleteParams{ID: uuid.New()}
+
+	tests := []struct {
+		execErr      error
+		wantErr      error
+		name         string
+		rowsAffected int64
+	}{
+		{
+			name:         "deleted",
+			rowsAffected: 1,
+		},
+		{
+			name:    "not found",
+			wantErr: ErrGroupNotFound,
+		},
+		{
+			name:    "exec error",
+			execErr: errors.New("connection refused"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := &mockDBClient{
+				execFunc: func(_ context.Context, query string, args ...interface{}) (sql.Result, error) {
+					assert.Contains(t, query, "DELETE FROM aegis_vault_keeper.user_groups")
+					assert.Equal(t, []interface{}{params.ID}, args)
+					return mockResult{rowsAffected: tt.rowsAffected}, tt.execErr
+				},
+			}
+
+			err := NewRepository(client).Delete(context.Background(), params)
+
+			switch {
+			case tt.execErr != nil:
+				assert.ErrorIs(t, err, tt.execErr)
+			case tt.wantErr != nil:
+				assert.ErrorIs(t, err, tt.wantErr)
+			default:
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,6 @@
+// Package history provides access to retained item versions for the AegisVaultKeeper server.
+//
+// Every update of a versioned item table archives the previous row into its history table.
+// This package builds point-in-time queries over current and archived rows and prunes
+// versions that exceeded the retention period.
+package history
@@ -0,0 +1,179 @@
+package history
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockDBClient implements db.DBClient for testing.
+type mockDBClient struct {
+	execFunc func(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+func (m *mockDBClient) Exec(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return m.execFunc(ctx, query, args...)
+}
+
+func (m *mockDBClient) Query(context.Context, string, ...interface{}) (*sql.Rows, error) {
+	return nil, errors.New("mock not configured")
+}
+
+func (m *mockDBClient) QueryRow(context.Context, string, ...interface{}) *sql.Row {
+	return nil
+}
+
+func (m *mockDBClient) BeginTx(context.Context, *sql.TxOptions) (*sql.Tx, error) {
+	return nil, errors.New("mock not configured")
+}
+
+func (m *mockDBClient) CommitTx(*sql.Tx) error {
+	return nil
+}
+
+func (m *mockDBClient) RollbackTx(*sql.Tx) error {
+	return nil
+}
+
+// mockResult implements sql.Result for testing.
+type mockResult struct {
+	affected int64
+}
+
+func (m mockResult) LastInsertId() (int64, error) { return 0, nil }
+func (m mockResult) RowsAffected() (int64, error) { return m.affected, nil }
+
+func TestAsOfSource(t *testing.T) {
+	t.Parallel()
+
+	source := AsOfSource("notes", "id, updated_at", 3)
+
+	assert.Contains(t, source, "SELECT DISTINCT ON (id) id, updated_at")
+	assert.Contains(t, source, "SELECT id, updated_at FROM aegis_vault_keeper.notes\n")
+	assert.Contains(t, source, "SELECT id, updated_at FROM aegis_vault_keeper.notes_history")
+	assert.Contains(t, source, "WHERE updated_at <= $3")
+	assert.Contains(t, source, "ORDER BY id, updated_at DESC")
+	assert.Contains(t, source, ") AS notes")
+}
+
+func TestAsOfArg(t *testing.T) {
+	t.Parallel()
+
+	moment := time.Date(2026, 10, 15, 12, 0, 0, 0, time.FixedZone("UTC+3", 3*60*60))
+	got := AsOfArg(moment)
+
+	assert.True(t, moment.Equal(got))
+	assert.Equal(t, time.Local, got.Location())
+}
+
+func TestPruner_Prune(t *testing.T) {
+	t.Parallel()
+
+	const pruneNotes = "DELETE FROM aegis_vault_keeper.notes_history " +
+		"WHERE archived_at < now() - make_interval(secs => $1)"
+	const pruneCredentials = "DELETE FROM aegis_vault_keeper.credentials_history " +
+		"WHERE archived_at < now() - make_interval(secs => $1)"
+
+	users := []uuid.UUID{uuid.New()}
+	items := []uuid.UUID{uuid.New()}
+	const exclusions = " AND NOT (user_id = ANY($2::uuid[])) AND NOT (id = ANY($3::uuid[]))"
+
+	tests := []struct {
+		execErr     error
+		name        string
+		errContains string
+		wantQueries []string
+		wantArgs    []interface{}
+		params      PruneParams
+		wantTotal   int64
+	}{
+		{
+			name:        "prunes every history table",
+			params:      PruneParams{Retention: time.Hour},
+			wantQueries: []string{pruneNotes, pruneCredentials},
+			wantArgs:    []interface{}{time.Hour.Seconds()},
+			wantTotal:   4,
+		},
+		{
+			name:        "keeps the versions of excluded users and items",
+			params:      PruneParams{Retention: time.Hour, ExcludeUserIDs: users, ExcludeItemIDs: items},
+			wantQueries: []string{pruneNotes + exclusions, pruneCredentials + exclusions},
+			wantArgs:    []interface{}{time.Hour.Seconds(), users, items},
+			wantTotal:   4,
+		},
+		{
+			name:   "non-positive retention keeps all versions",
+			params: PruneParams{ExcludeUserIDs: users},
+		},
+		{
+			name:        "database error",
+			params:      PruneParams{Retention: time.Hour},
+			wantArgs:    []interface{}{time.Hour.Seconds()},
+			execErr:     errors.New("connection lost"),
+			wantQueries: []string{pruneNotes},
+			errContains: "failed to prune notes versions",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var queries []string
+			client := &mockDBClient{
+				execFunc: func(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+					queries = append(queries, query)
+					assert.Equal(t, tt.wantArgs, args)
+					if tt.execErr != nil {
+						return nil, tt.execErr
+					}
+					return mockResult{affected: 2}, nil
+				},
+			}
+
+			total, err := NewPruner(client, "notes", "credentials").Prune(context.Background(), tt.params)
+			if tt.errContains != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errContains)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.wantTotal, total)
+			}
+			assert.Equal(t, tt.wantQueries, queries)
+		})
+	}
+}
+
+// adminScopedClient wraps mockDBClient and records whether queries ran within the admin scope.
+type adminScopedClient struct {
+	mockDBClient
+	inScope bool
+}
+
+func (a *adminScopedClient) RunInAdminScope(ctx context.Context, fn func(ctx context.Context) error) error {
+	a.inScope = true
+	defer func() { a.inScope = false }()
+	return fn(ctx)
+}
+
+func TestPruner_Prune_AdminScope(t *testing.T) {
+	t.Parallel()
+
+	client := &adminScopedClient{}
+	var scoped []bool
+	client.execFunc = func(context.Context, string, ...interface{}) (sql.Result, error) {
+		scoped = append(scoped, client.inScope)
+		return mockResult{affected: 1}, nil
+	}
+
+	total, err := NewPruner(client, "notes", "credentials").Prune(context.Background(), PruneParams{Retention: time.Hour})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Equal(t, []bool{true, true}, scoped)
+}
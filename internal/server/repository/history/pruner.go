@@ -0,0 +1,79 @@
+package history
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/db"
+	"github.com/google/uuid"
+)
+
+// Pruner removes retained item versions that exceeded the retention period.
+type Pruner struct {
+	// db is the database client used for deletions.
+	db db.DBClient
+	// itemTables lists the versioned item tables whose history is pruned.
+	itemTables []string
+}
+
+// NewPruner creates a new Pruner for the history of the specified item tables.
+func NewPruner(dbClient db.DBClient, itemTables ...string) *Pruner {
+	return &Pruner{db: dbClient, itemTables: itemTables}
+}
+
+// PruneParams contains the parameters selecting the item versions to prune.
+type PruneParams struct {
+	// ExcludeUserIDs lists the users whose versions are kept regardless of their age.
+	ExcludeUserIDs []uuid.UUID
+	// ExcludeItemIDs lists the items whose versions are kept regardless of their age.
+	ExcludeItemIDs []uuid.UUID
+	// Retention specifies how long archived versions are kept; a non-positive retention keeps all versions.
+	Retention time.Duration
+}
+
+// Prune deletes versions archived longer than the retention ago, except the versions of the excluded users and
+// items, and returns the number of deleted versions. The versions of all users are pruned within the admin scope.
+func (p *Pruner) Prune(ctx context.Context, params PruneParams) (int64, error) {
+	if params.Retention <= 0 {
+		return 0, nil
+	}
+
+	// total holds the number of versions pruned inside the admin scope.
+	var total int64
+	err := db.InAdminScope(ctx, p.db, func(ctx context.Context) error {
+		var err error
+		total, err = p.prune(ctx, params)
+		return err
+	})
+	return total, err
+}
+
+// prune deletes the versions selected by params from the history of every item table.
+func (p *Pruner) prune(ctx context.Context, params PruneParams) (int64, error) {
+	condition := "archived_at < now() - make_interval(secs => $1)"
+	args := []any{params.Retention.Seconds()}
+	if len(params.ExcludeUserIDs) > 0 {
+		args = append(args, params.ExcludeUserIDs)
+		condition += fmt.Sprintf(" AND NOT (user_id = ANY($%d::uuid[]))", len(args))
+	}
+	if len(params.ExcludeItemIDs) > 0 {
+		args = append(args, params.ExcludeItemIDs)
+		condition += fmt.Sprintf(" AND NOT (id = ANY($%d::uuid[]))", len(args))
+	}
+
+	var total int64
+	for _, t := range p.itemTables {
+		query := fmt.Sprintf("DELETE FROM aegis_vault_keeper.%s WHERE %s", Table(t), condition)
+		res, err := p.db.Exec(ctx, query, args...)
+		if err != nil {
+			return total, fmt.Errorf("failed to prune %s versions: %w", t, err)
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return total, fmt.Errorf("failed to count pruned %s versions: %w", t, err)
+		}
+		total += n
+	}
+	return total, nil
+}
@@ -0,0 +1,34 @@
+package history
+
+import (
+	"fmt"
+	"time"
+)
+
+// Table returns the name of the history table retaining versions of the specified item table.
+func Table(itemTable string) string {
+	return itemTable + "_history"
+}
+
+// AsOfSource builds a FROM clause source exposing, for every item, the version that was current
+// at the moment bound to the query argument with index asOfArg. Items created after that moment
+// are absent. The source is aliased with the item table name, so filters can be applied as usual.
+func AsOfSource(itemTable, columns string, asOfArg int) string {
+	return fmt.Sprintf(`(
+			SELECT DISTINCT ON (id) %[2]s
+			FROM (
+				SELECT %[2]s FROM aegis_vault_keeper.%[1]s
+				UNION ALL
+				SELECT %[2]s FROM aegis_vault_keeper.%[3]s
+			) AS versions
+			WHERE updated_at <= $%[4]d
+			ORDER BY id, updated_at DESC
+		) AS %[1]s`, itemTable, columns, Table(itemTable), asOfArg)
+}
+
+// AsOfArg converts a point in time into a query argument comparable with stored update timestamps.
+// Item timestamps are stored as wall clock time of the server location without a time zone,
+// so the moment is expressed in the same location.
+func AsOfArg(t time.Time) time.Time {
+	return t.In(time.Local)
+}
@@ -0,0 +1,5 @@
+// Package invite provides invite code persistence for the AegisVaultKeeper server.
+//
+// This package implements storage of registration invites in PostgreSQL. Only the digests of invite
+// codes are stored, so invites are looked up by the digest of the presented code.
+package invite
@@ -0,0 +1,7 @@
+package invite
+
+import "errors"
+
+// ErrInviteNotFound indicates that the requested invite was not found in the repository, or that the invite
+// to redeem is no longer pending.
+var ErrInviteNotFound = errors.New("invite not found")
@@ -0,0 +1,37 @@
+package invite
+
+import (
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/invite"
+	"github.com/google/uuid"
+)
+
+// SaveParams contains the parameters for saving an invite to the repository.
+type SaveParams struct {
+	// Entity contains the invite to be created or revoked.
+	Entity *invite.Invite
+}
+
+// LoadParams contains the parameters for loading invites from the repository.
+// Zero fields do not filter.
+type LoadParams struct {
+	// CodeHash selects the invite holding the code with the specified digest.
+	CodeHash []byte
+	// ID selects a single invite.
+	ID uuid.UUID
+	// CreatedBy selects the invites created by the specified user.
+	CreatedBy uuid.UUID
+	// RedeemedBy selects the invite the specified user registered with.
+	RedeemedBy uuid.UUID
+}
+
+// RedeemParams contains the parameters for redeeming an invite.
+type RedeemParams struct {
+	// At specifies the redemption time; the invite must not have expired by then.
+	At time.Time
+	// ID identifies the invite to redeem.
+	ID uuid.UUID
+	// UserID identifies the user registered with the invite.
+	UserID uuid.UUID
+}
@@ -0,0 +1,153 @@
+package invite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/invite"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/db"
+	"github.com/google/uuid"
+)
+
+// Repository provides invite persistence operations.
+type Repository struct {
+	// db is the database client used for invite operations.
+	db db.DBClient
+}
+
+// NewRepository creates a new Repository with the provided database client.
+func NewRepository(dbClient db.DBClient) *Repository {
+	return &Repository{db: dbClient}
+}
+
+// Save creates the invite or updates the revocation of the existing one.
+func (r *Repository) Save(ctx context.Context, params SaveParams) error {
+	e := params.Entity
+
+	groups, err := json.Marshal(e.Groups)
+	if err != nil {
+		return fmt.Errorf("failed to encode invite groups: %w", err)
+	}
+
+	query := `
+		INSERT INTO aegis_vault_keeper.invites
+			(id, code_hash, note, group_ids, invite_quota, created_by, created_at, expires_at, revoked_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (id) DO UPDATE SET
+			revoked_at = EXCLUDED.revoked_at
+	`
+	if _, err := r.db.Exec(ctx, query,
+		e.ID, e.CodeHash, e.Note, groups, e.InviteQuota, nullUUID(e.CreatedBy), e.CreatedAt, e.ExpiresAt,
+		nullTime(e.RevokedAt),
+	); err != nil {
+		return fmt.Errorf("failed to save invite: %w", err)
+	}
+	return nil
+}
+
+// Load retrieves invites matching the provided parameters, ordered by creation time.
+// Loading by ID or code digest returns ErrInviteNotFound when no invite matches.
+func (r *Repository) Load(ctx context.Context, params LoadParams) ([]*invite.Invite, error) {
+	var (
+		conditions []string
+		args       []interface{}
+	)
+	// filter adds a condition comparing a column with the next positional argument.
+	filter := func(condition string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	if params.ID != uuid.Nil {
+		filter("id = $%d", params.ID)
+	}
+	if params.CodeHash != nil {
+		filter("code_hash = $%d", params.CodeHash)
+	}
+	if params.CreatedBy != uuid.Nil {
+		filter("created_by = $%d", params.CreatedBy)
+	}
+	if params.RedeemedBy != uuid.Nil {
+		filter("redeemed_by = $%d", params.RedeemedBy)
+	}
+
+	query := `
+		SELECT id, code_hash, note, group_ids, invite_quota, created_by, created_at, expires_at,
+			revoked_at, redeemed_by, redeemed_at
+		FROM aegis_vault_keeper.invites
+	`
+	if len(conditions) != 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY created_at, id"
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load invites: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var invites []*invite.Invite
+	for rows.Next() {
+		var (
+			i                     invite.Invite
+			groups                []byte
+			createdBy, redeemedBy uuid.NullUUID
+			revokedAt, redeemedAt sql.NullTime
+		)
+		if err := rows.Scan(
+			&i.ID, &i.CodeHash, &i.Note, &groups, &i.InviteQuota, &createdBy, &i.CreatedAt, &i.ExpiresAt,
+			&revokedAt, &redeemedBy, &redeemedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan invite: %w", err)
+		}
+		if err := json.Unmarshal(groups, &i.Groups); err != nil {
+			return nil, fmt.Errorf("failed to decode invite groups: %w", err)
+		}
+		i.CreatedBy, i.RedeemedBy = createdBy.UUID, redeemedBy.UUID
+		i.RevokedAt, i.RedeemedAt = revokedAt.Time, redeemedAt.Time
+		invites = append(invites, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate invites: %w", err)
+	}
+	if (params.ID != uuid.Nil || params.CodeHash != nil) && len(invites) == 0 {
+		return nil, ErrInviteNotFound
+	}
+	return invites, nil
+}
+
+// Redeem marks the pending invite as redeemed by the user. It returns ErrInviteNotFound when the invite was
+// redeemed or revoked in the meantime or has expired, so concurrent registrations cannot share an invite.
+func (r *Repository) Redeem(ctx context.Context, params RedeemParams) error {
+	query := `
+		UPDATE aegis_vault_keeper.invites
+		SET redeemed_by = $2, redeemed_at = $3
+		WHERE id = $1 AND redeemed_at IS NULL AND revoked_at IS NULL AND expires_at > $3
+	`
+	res, err := r.db.Exec(ctx, query, params.ID, params.UserID, params.At)
+	if err != nil {
+		return fmt.Errorf("failed to redeem invite: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check redeemed invites: %w", err)
+	}
+	if n == 0 {
+		return ErrInviteNotFound
+	}
+	return nil
+}
+
+// nullUUID converts uuid.Nil to SQL NULL.
+func nullUUID(id uuid.UUID) uuid.NullUUID {
+	return uuid.NullUUID{UUID: id, Valid: id != uuid.Nil}
+}
+
+// nullTime converts the zero time to SQL NULL.
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
@@ -0,0 +1,221 @@
+package invite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/invite"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/db"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockResult implements sql.Result for testing.
+type mockResult struct {
+	rowsAffected int64
+}
+
+func (m mockResult) LastInsertId() (int64, error) { return 0, nil }
+func (m mockResult) RowsAffected() (int64, error) { return m.rowsAffected, nil }
+
+// mockDBClient implements db.DBClient for testing.
+type mockDBClient struct {
+	execFunc  func(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	queryFunc func(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+func (m *mockDBClient) Exec(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	if m.execFunc != nil {
+		return m.execFunc(ctx, query, args...)
+	}
+	return mockResult{rowsAffected: 1}, nil
+}
+
+func (m *mockDBClient) Query(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	if m.queryFunc != nil {
+		return m.queryFunc(ctx, query, args...)
+	}
+	return nil, errors.New("mock not configured")
+}
+
+func (m *mockDBClient) QueryRow(context.Context, string, ...interface{}) *sql.Row {
+	return nil
+}
+
+func (m *mockDBClient) BeginTx(context.Context, *sql.TxOptions) (*sql.Tx, error) {
+	return nil, errors.New("mock not configured")
+}
+
+func (m *mockDBClient) CommitTx(*sql.Tx) error {
+	return nil
+}
+
+func (m *mockDBClient) RollbackTx(*sql.Tx) error {
+	return nil
+}
+
+// Ensure mockDBClient implements db.DBClient.
+var _ db.DBClient = (*mockDBClient)(nil)
+
+func TestRepository_Save(t *testing.T) {
+	t.Parallel()
+
+	id, groupID := uuid.New(), uuid.New()
+	createdAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	codeHash := invite.HashCode("avki_0123456789ABCDEFGHIJ")
+
+	tests := []struct {
+		execErr error
+		name    string
+	}{
+		{name: "saved"},
+		{name: "exec error", execErr: errors.New("connection refused")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var gotArgs []interface{}
+			client := &mockDBClient{
+				execFunc: func(_ context.Context, query string, args ...interface{}) (sql.Result, error) {
+					assert.Contains(t, query, "INSERT INTO aegis_vault_keeper.invites")
+					assert.Contains(t, query, "ON CONFLICT (id) DO UPDATE")
+					gotArgs = args
+					return mockResult{rowsAffected: 1}, tt.execErr
+				},
+			}
+
+			err := NewRepository(client).Save(context.Background(), SaveParams{Entity: &invite.Invite{
+				ID:          id,
+				CodeHash:    codeHash,
+				Note:        "for Alex",
+				Groups:      []uuid.UUID{groupID},
+				InviteQuota: 2,
+				CreatedAt:   createdAt,
+				ExpiresAt:   createdAt.Add(time.Hour),
+			}})
+
+			if tt.execErr != nil {
+				assert.ErrorIs(t, err, tt.execErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, gotArgs, 9)
+			assert.Equal(t, []interface{}{id, codeHash, "for Alex"}, gotArgs[:3])
+			assert.JSONEq(t, `["`+groupID.String()+`"]`, string(gotArgs[3].([]byte)))
+			assert.Equal(t, []interface{}{
+				2, uuid.NullUUID{}, createdAt, createdAt.Add(time.Hour), sql.NullTime{},
+			}, gotArgs[4:], "invites of administrators have no creator")
+		})
+	}
+}
+
+func TestRepository_Load(t *testing.T) {
+	t.Parallel()
+
+	id, userID := uuid.New(), uuid.New()
+	codeHash := invite.HashCode("avki_0123456789ABCDEFGHIJ")
+
+	tests := []struct {
+		name      string
+		params    LoadParams
+		wantQuery string
+		wantArgs  []interface{}
+	}{
+		{
+			name:      "invites of a creator",
+			params:    LoadParams{CreatedBy: userID},
+			wantQuery: "WHERE created_by = $1",
+			wantArgs:  []interface{}{userID},
+		},
+		{
+			name:      "invite of a creator",
+			params:    LoadParams{ID: id, CreatedBy: userID},
+			wantQuery: "WHERE id = $1 AND created_by = $2",
+			wantArgs:  []interface{}{id, userID},
+		},
+		{
+			name:      "invite holding a code",
+			params:    LoadParams{CodeHash: codeHash},
+			wantQuery: "WHERE code_hash = $1",
+			wantArgs:  []interface{}{codeHash},
+		},
+		{
+			name:      "invite of a registered user",
+			params:    LoadParams{RedeemedBy: userID},
+			wantQuery: "WHERE redeemed_by = $1",
+			wantArgs:  []interface{}{userID},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			queryErr := errors.New("query error")
+			var gotQuery string
+			var gotArgs []interface{}
+			client := &mockDBClient{
+				queryFunc: func(_ context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+					gotQuery, gotArgs = query, args
+					return nil, queryErr
+				},
+			}
+
+			_, err := NewRepository(client).Load(context.Background(), tt.params)
+
+			require.ErrorIs(t, err, queryErr)
+			assert.Contains(t, gotQuery, tt.wantQuery)
+			assert.Contains(t, gotQuery, "ORDER BY created_at, id")
+			assert.Equal(t, tt.wantArgs, gotArgs)
+		})
+	}
+}
+
+func TestRepository_Redeem(t *testing.T) {
+	t.Parallel()
+
+	id, userID := uuid.New(), uuid.New()
+	at := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		execErr  error
+		wantErr  error
+		name     string
+		affected int64
+	}{
+		{name: "redeemed", affected: 1},
+		{name: "no longer pending", wantErr: ErrInviteNotFound},
+		{name: "exec error", execErr: errors.New("connection refused")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := &mockDBClient{
+				execFunc: func(_ context.Context, query string, args ...interface{}) (sql.Result, error) {
+					assert.Contains(t, query, "redeemed_at IS NULL AND revoked_at IS NULL AND expires_at > $3")
+					assert.Equal(t, []interface{}{id, userID, at}, args)
+					return mockResult{rowsAffected: tt.affected}, tt.execErr
+				},
+			}
+
+			err := NewRepository(client).Redeem(context.Background(), RedeemParams{ID: id, UserID: userID, At: at})
+
+			switch {
+			case tt.execErr != nil:
+				assert.ErrorIs(t, err, tt.execErr)
+			case tt.wantErr != nil:
+				assert.ErrorIs(t, err, tt.wantErr)
+			default:
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
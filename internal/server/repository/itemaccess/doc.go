@@ -0,0 +1,5 @@
+// Package itemaccess provides item reveal persistence for the AegisVaultKeeper server.
+//
+// This package implements storage of the reveals of vault items users review in their access history
+// in PostgreSQL.
+package itemaccess
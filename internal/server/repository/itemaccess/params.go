@@ -0,0 +1,20 @@
+package itemaccess
+
+import (
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/itemaccess"
+	"github.com/google/uuid"
+)
+
+// SaveParams contains the parameters for saving an item reveal to the repository.
+type SaveParams struct {
+	// Entity contains the item reveal to be persisted.
+	Entity *itemaccess.Reveal
+}
+
+// LoadParams contains the parameters for loading the reveals of an item from the repository.
+type LoadParams struct {
+	// UserID selects the reveals of the specified user.
+	UserID uuid.UUID
+	// ItemID selects the revealed item.
+	ItemID uuid.UUID
+}
@@ -0,0 +1,81 @@
+package itemaccess
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/netip"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/itemaccess"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/db"
+)
+
+// Repository provides item reveal persistence operations.
+type Repository struct {
+	// db is the database client used for reveal operations.
+	db db.DBClient
+}
+
+// NewRepository creates a new Repository with the provided database client.
+func NewRepository(dbClient db.DBClient) *Repository {
+	return &Repository{db: dbClient}
+}
+
+// Save stores the item reveal.
+func (r *Repository) Save(ctx context.Context, params SaveParams) error {
+	e := params.Entity
+
+	var ip sql.NullString
+	if e.IP.IsValid() {
+		ip = sql.NullString{String: e.IP.String(), Valid: true}
+	}
+
+	query := `
+		INSERT INTO aegis_vault_keeper.item_reveals (id, user_id, item_id, fingerprint, user_agent, ip, revealed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+	if _, err := r.db.Exec(ctx, query,
+		e.ID, e.UserID, e.ItemID, e.Fingerprint, e.UserAgent, ip, e.RevealedAt,
+	); err != nil {
+		return fmt.Errorf("failed to save item reveal: %w", err)
+	}
+	return nil
+}
+
+// Load retrieves the reveals of the item, newest first.
+func (r *Repository) Load(ctx context.Context, params LoadParams) ([]*itemaccess.Reveal, error) {
+	query := `
+		SELECT id, user_id, item_id, fingerprint, user_agent, host(ip), revealed_at
+		FROM aegis_vault_keeper.item_reveals
+		WHERE user_id = $1 AND item_id = $2
+		ORDER BY revealed_at DESC, id
+	`
+	rows, err := r.db.Query(ctx, query, params.UserID, params.ItemID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load item reveals: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var reveals []*itemaccess.Reveal
+	for rows.Next() {
+		var (
+			e  itemaccess.Reveal
+			ip sql.NullString
+		)
+		if err := rows.Scan(&e.ID, &e.UserID, &e.ItemID, &e.Fingerprint, &e.UserAgent, &ip, &e.RevealedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan item reveal: %w", err)
+		}
+		if ip.Valid {
+			addr, err := netip.ParseAddr(ip.String)
+			if err != nil {
+				return nil, fmt.Errorf("invalid address of item reveal %s: %w", e.ID, err)
+			}
+			e.IP = addr
+		}
+		reveals = append(reveals, &e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate item reveals: %w", err)
+	}
+	return reveals, nil
+}
@@ -0,0 +1,149 @@
+package itemaccess
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/itemaccess"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/db"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockResult implements sql.Result for testing.
+type mockResult struct {
+	rowsAffected int64
+}
+
+func (m mockResult) LastInsertId() (int64, error) { return 0, nil }
+func (m mockResult) RowsAffected() (int64, error) { return m.rowsAffected, nil }
+
+// mockDBClient implements db.DBClient for testing.
+type mockDBClient struct {
+	execFunc  func(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	queryFunc func(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+func (m *mockDBClient) Exec(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	if m.execFunc != nil {
+		return m.execFunc(ctx, query, args...)
+	}
+	return mockResult{rowsAffected: 1}, nil
+}
+
+func (m *mockDBClient) Query(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	if m.queryFunc != nil {
+		return m.queryFunc(ctx, query, args...)
+	}
+	return nil, errors.New("mock not configured")
+}
+
+func (m *mockDBClient) QueryRow(context.Context, string, ...interface{}) *sql.Row {
+	return nil
+}
+
+func (m *mockDBClient) BeginTx(context.Context, *sql.TxOptions) (*sql.Tx, error) {
+	return nil, errors.New("mock not configured")
+}
+
+func (m *mockDBClient) CommitTx(*sql.Tx) error {
+	return nil
+}
+
+func (m *mockDBClient) RollbackTx(*sql.Tx) error {
+	return nil
+}
+
+// Ensure mockDBClient implements db.DBClient.
+var _ db.DBClient = (*mockDBClient)(nil)
+
+func TestRepository_Save(t *testing.T) {
+	t.Parallel()
+
+	id, itemID, userID := uuid.New(), uuid.New(), uuid.New()
+	revealedAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		execErr  error
+		name     string
+		wantArgs []interface{}
+		ip       netip.Addr
+	}{
+		{
+			name: "reveal with address",
+			ip:   netip.MustParseAddr("203.0.113.7"),
+			wantArgs: []interface{}{
+				id, userID, itemID, "fp", "aegis-cli/1.0",
+				sql.NullString{String: "203.0.113.7", Valid: true}, revealedAt,
+			},
+		},
+		{
+			name:     "reveal without address",
+			wantArgs: []interface{}{id, userID, itemID, "fp", "aegis-cli/1.0", sql.NullString{}, revealedAt},
+		},
+		{
+			name:    "exec error",
+			execErr: errors.New("connection refused"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var gotArgs []interface{}
+			client := &mockDBClient{
+				execFunc: func(_ context.Context, query string, args ...interface{}) (sql.Result, error) {
+					assert.Contains(t, query, "INSERT INTO aegis_vault_keeper.item_reveals")
+					gotArgs = args
+					return mockResult{rowsAffected: 1}, tt.execErr
+				},
+			}
+
+			entity := &itemaccess.Reveal{
+				ID:          id,
+				ItemID:      itemID,
+				UserID:      userID,
+				Fingerprint: "fp",
+				UserAgent:   "aegis-cli/1.0",
+				IP:          tt.ip,
+				RevealedAt:  revealedAt,
+			}
+			err := NewRepository(client).Save(context.Background(), SaveParams{Entity: entity})
+
+			if tt.execErr != nil {
+				assert.ErrorIs(t, err, tt.execErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantArgs, gotArgs)
+		})
+	}
+}
+
+func TestRepository_Load(t *testing.T) {
+	t.Parallel()
+
+	itemID, userID := uuid.New(), uuid.New()
+	queryErr := errors.New("query error")
+	var gotQuery string
+	var gotArgs []interface{}
+	client := &mockDBClient{
+		queryFunc: func(_ context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+			gotQuery, gotArgs = query, args
+			return nil, queryErr
+		},
+	}
+
+	_, err := NewRepository(client).Load(context.Background(), LoadParams{UserID: userID, ItemID: itemID})
+
+	require.ErrorIs(t, err, queryErr)
+	assert.Contains(t, gotQuery, "WHERE user_id = $1 AND item_id = $2")
+	assert.Contains(t, gotQuery, "ORDER BY revealed_at DESC")
+	assert.Equal(t, []interface{}{userID, itemID}, gotArgs)
+}
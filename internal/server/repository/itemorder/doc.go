@@ -0,0 +1,4 @@
+// Package itemorder provides manual item order persistence for the AegisVaultKeeper server.
+//
+// This package implements storage of the per-folder orders users arrange their vault items in, in PostgreSQL.
+package itemorder
@@ -0,0 +1,9 @@
+package itemorder
+
+import "errors"
+
+// Item order repository error definitions.
+var (
+	// ErrOrderNotFound indicates that the user has not arranged the items of the folder.
+	ErrOrderNotFound = errors.New("item order not found")
+)
@@ -0,0 +1,20 @@
+package itemorder
+
+import (
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/itemorder"
+	"github.com/google/uuid"
+)
+
+// SaveParams contains the parameters for saving the order of a folder to the repository.
+type SaveParams struct {
+	// Entity contains the order to be persisted; an order without entries removes the stored one.
+	Entity *itemorder.ItemOrder
+}
+
+// LoadParams contains the parameters for loading the order of a folder from the repository.
+type LoadParams struct {
+	// Folder selects the normalized folder; empty selects the root.
+	Folder string
+	// UserID selects the order of the specified user.
+	UserID uuid.UUID
+}
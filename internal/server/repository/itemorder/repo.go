@@ -0,0 +1,96 @@
+package itemorder
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/itemorder"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/db"
+	"github.com/google/uuid"
+)
+
+// Repository provides item order persistence operations.
+type Repository struct {
+	// db is the database client used for order operations.
+	db db.DBClient
+}
+
+// NewRepository creates a new Repository with the provided database client.
+func NewRepository(dbClient db.DBClient) *Repository {
+	return &Repository{db: dbClient}
+}
+
+// Save replaces the stored order of the folder, removing it when the order has no entries.
+// Items are stored in order with the number of leading pinned items.
+func (r *Repository) Save(ctx context.Context, params SaveParams) error {
+	e := params.Entity
+
+	if len(e.Entries) == 0 {
+		query := "DELETE FROM aegis_vault_keeper.item_orders WHERE user_id = $1 AND folder = $2"
+		if _, err := r.db.Exec(ctx, query, e.UserID, e.Folder); err != nil {
+			return fmt.Errorf("failed to delete item order: %w", err)
+		}
+		return nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(e.Entries))
+	pinned := 0
+	for _, entry := range e.Entries {
+		ids = append(ids, entry.ItemID)
+		if entry.Pinned {
+			pinned++
+		}
+	}
+
+	query := `
+		INSERT INTO aegis_vault_keeper.item_orders (user_id, folder, item_ids, pinned, updated_at)
+		VALUES ($1, $2, $3::uuid[], $4, $5)
+		ON CONFLICT (user_id, folder) DO UPDATE SET
+			item_ids = EXCLUDED.item_ids,
+			pinned = EXCLUDED.pinned,
+			updated_at = EXCLUDED.updated_at
+	`
+	if _, err := r.db.Exec(ctx, query, e.UserID, e.Folder, ids, pinned, e.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to save item order: %w", err)
+	}
+	return nil
+}
+
+// Load retrieves the order of the folder.
+// Returns ErrOrderNotFound when the user has not arranged the items of the folder.
+func (r *Repository) Load(ctx context.Context, params LoadParams) (*itemorder.ItemOrder, error) {
+	query := `
+		SELECT user_id, folder, array_to_string(item_ids, ','), pinned, updated_at
+		FROM aegis_vault_keeper.item_orders
+		WHERE user_id = $1 AND folder = $2
+	`
+	rows, err := r.db.Query(ctx, query, params.UserID, params.Folder)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load item order: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("failed to load item order: %w", err)
+		}
+		return nil, ErrOrderNotFound
+	}
+	var (
+		o      itemorder.ItemOrder
+		ids    string
+		pinned int
+	)
+	if err := rows.Scan(&o.UserID, &o.Folder, &ids, &pinned, &o.UpdatedAt); err != nil {
+		return nil, fmt.Errorf("failed to scan item order: %w", err)
+	}
+	for i, id := range strings.Split(ids, ",") {
+		itemID, err := uuid.Parse(id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse ordered item: %w", err)
+		}
+		o.Entries = append(o.Entries, itemorder.Entry{ItemID: itemID, Pinned: i < pinned})
+	}
+	return &o, nil
+}
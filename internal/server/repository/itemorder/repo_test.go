@@ -0,0 +1,141 @@
+package itemorder
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/itemorder"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/db"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockResult implements sql.Result for testing.
+type mockResult struct {
+	rowsAffected int64
+}
+
+func (m mockResult) LastInsertId() (int64, error) { return 0, nil }
+func (m mockResult) RowsAffected() (int64, error) { return m.rowsAffected, nil }
+
+// mockDBClient implements db.DBClient for testing.
+type mockDBClient struct {
+	execFunc  func(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	queryFunc func(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+func (m *mockDBClient) Exec(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	if m.execFunc != nil {
+		return m.execFunc(ctx, query, args...)
+	}
+	return mockResult{rowsAffected: 1}, nil
+}
+
+func (m *mockDBClient) Query(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	if m.queryFunc != nil {
+		return m.queryFunc(ctx, query, args...)
+	}
+	return nil, errors.New("mock not configured")
+}
+
+func (m *mockDBClient) QueryRow(context.Context, string, ...interface{}) *sql.Row {
+	return nil
+}
+
+func (m *mockDBClient) BeginTx(context.Context, *sql.TxOptions) (*sql.Tx, error) {
+	return nil, errors.New("mock not configured")
+}
+
+func (m *mockDBClient) CommitTx(*sql.Tx) error {
+	return nil
+}
+
+func (m *mockDBClient) RollbackTx(*sql.Tx) error {
+	return nil
+}
+
+// Ensure mockDBClient implements db.DBClient.
+var _ db.DBClient = (*mockDBClient)(nil)
+
+func TestRepository_Save(t *testing.T) {
+	t.Parallel()
+
+	itemA, itemB, userID := uuid.New(), uuid.New(), uuid.New()
+	updatedAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		execErr   error
+		name      string
+		wantQuery string
+		wantArgs  []interface{}
+		entries   []itemorder.Entry
+	}{
+		{
+			name:      "upsert order",
+			entries:   []itemorder.Entry{{ItemID: itemA, Pinned: true}, {ItemID: itemB}},
+			wantQuery: "INSERT INTO aegis_vault_keeper.item_orders",
+			wantArgs:  []interface{}{userID, "prod", []uuid.UUID{itemA, itemB}, 1, updatedAt},
+		},
+		{
+			name:      "empty order deletes order",
+			wantQuery: "DELETE FROM aegis_vault_keeper.item_orders",
+			wantArgs:  []interface{}{userID, "prod"},
+		},
+		{
+			name:      "exec error",
+			entries:   []itemorder.Entry{{ItemID: itemA}},
+			wantQuery: "INSERT INTO aegis_vault_keeper.item_orders",
+			execErr:   errors.New("connection refused"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var gotArgs []interface{}
+			client := &mockDBClient{
+				execFunc: func(_ context.Context, query string, args ...interface{}) (sql.Result, error) {
+					assert.Contains(t, query, tt.wantQuery)
+					gotArgs = args
+					return mockResult{rowsAffected: 1}, tt.execErr
+				},
+			}
+
+			entity := &itemorder.ItemOrder{UserID: userID, Folder: "prod", Entries: tt.entries, UpdatedAt: updatedAt}
+			err := NewRepository(client).Save(context.Background(), SaveParams{Entity: entity})
+
+			if tt.execErr != nil {
+				assert.ErrorIs(t, err, tt.execErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantArgs, gotArgs)
+		})
+	}
+}
+
+func TestRepository_Load(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	queryErr := errors.New("query error")
+	var gotQuery string
+	var gotArgs []interface{}
+	client := &mockDBClient{
+		queryFunc: func(_ context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+			gotQuery, gotArgs = query, args
+			return nil, queryErr
+		},
+	}
+
+	_, err := NewRepository(client).Load(context.Background(), LoadParams{UserID: userID, Folder: "prod"})
+
+	require.ErrorIs(t, err, queryErr)
+	assert.Contains(t, gotQuery, "WHERE user_id = $1 AND folder = $2")
+	assert.Equal(t, []interface{}{userID, "prod"}, gotArgs)
+}
@@ -0,0 +1,4 @@
+// Package itempath provides item path persistence for the AegisVaultKeeper server.
+//
+// This package implements storage of the hierarchical paths users assign to their vault items in PostgreSQL.
+package itempath
@@ -0,0 +1,9 @@
+package itempath
+
+import "errors"
+
+// Item path repository error definitions.
+var (
+	// ErrPathTaken indicates that another item of the user already has the path.
+	ErrPathTaken = errors.New("item path already taken")
+)
@@ -0,0 +1,24 @@
+package itempath
+
+import (
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/itempath"
+	"github.com/google/uuid"
+)
+
+// SaveParams contains the parameters for saving the path of an item to the repository.
+type SaveParams struct {
+	// Entity contains the item path to be persisted; an empty path removes the stored one.
+	Entity *itempath.ItemPath
+}
+
+// LoadParams contains the parameters for loading item paths from the repository.
+type LoadParams struct {
+	// Path selects the item with exactly this normalized path; empty does not filter.
+	Path string
+	// Prefix selects the items whose paths equal or lie below this normalized path; empty does not filter.
+	Prefix string
+	// UserID selects the items of the specified user.
+	UserID uuid.UUID
+	// ItemID selects a single item; uuid.Nil does not filter.
+	ItemID uuid.UUID
+}
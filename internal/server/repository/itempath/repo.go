@@ -0,0 +1,100 @@
+package itempath
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/itempath"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/db"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// uniqueViolation is the PostgreSQL error code of unique constraint violations.
+const uniqueViolation = "23505"
+
+// Repository provides item path persistence operations.
+type Repository struct {
+	// db is the database client used for path operations.
+	db db.DBClient
+}
+
+// NewRepository creates a new Repository with the provided database client.
+func NewRepository(dbClient db.DBClient) *Repository {
+	return &Repository{db: dbClient}
+}
+
+// Save replaces the stored path of the item, removing it when the path is empty.
+// Returns ErrPathTaken when another item of the user already has the path.
+func (r *Repository) Save(ctx context.Context, params SaveParams) error {
+	e := params.Entity
+
+	if e.Path == "" {
+		query := "DELETE FROM aegis_vault_keeper.item_paths WHERE user_id = $1 AND item_id = $2"
+		if _, err := r.db.Exec(ctx, query, e.UserID, e.ItemID); err != nil {
+			return fmt.Errorf("failed to delete item path: %w", err)
+		}
+		return nil
+	}
+
+	query := `
+		INSERT INTO aegis_vault_keeper.item_paths (user_id, item_id, path, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id, item_id) DO UPDATE SET
+			path = EXCLUDED.path,
+			updated_at = EXCLUDED.updated_at
+	`
+	if _, err := r.db.Exec(ctx, query, e.UserID, e.ItemID, e.Path, e.UpdatedAt); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+			return ErrPathTaken
+		}
+		return fmt.Errorf("failed to save item path: %w", err)
+	}
+	return nil
+}
+
+// Load retrieves the item paths of the user matching the parameters ordered by path.
+func (r *Repository) Load(ctx context.Context, params LoadParams) ([]*itempath.ItemPath, error) {
+	query := `
+		SELECT user_id, item_id, path, updated_at
+		FROM aegis_vault_keeper.item_paths
+		WHERE user_id = $1
+	`
+	args := []any{params.UserID}
+	if params.ItemID != uuid.Nil {
+		args = append(args, params.ItemID)
+		query += " AND item_id = $" + strconv.Itoa(len(args))
+	}
+	if params.Path != "" {
+		args = append(args, params.Path)
+		query += " AND path = $" + strconv.Itoa(len(args))
+	}
+	if params.Prefix != "" {
+		args = append(args, params.Prefix)
+		n := strconv.Itoa(len(args))
+		query += " AND (path = $" + n + " OR starts_with(path, $" + n + " || '/'))"
+	}
+	query += " ORDER BY path"
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load item paths: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var result []*itempath.ItemPath
+	for rows.Next() {
+		var p itempath.ItemPath
+		if err := rows.Scan(&p.UserID, &p.ItemID, &p.Path, &p.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan item path: %w", err)
+		}
+		result = append(result, &p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate item paths: %w", err)
+	}
+	return result, nil
+}
@@ -0,0 +1,197 @@
+package itempath
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/itempath"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/db"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockResult implements sql.Result for testing.
+type mockResult struct {
+	rowsAffected int64
+}
+
+func (m mockResult) LastInsertId() (int64, error) { return 0, nil }
+func (m mockResult) RowsAffected() (int64, error) { return m.rowsAffected, nil }
+
+// mockDBClient implements db.DBClient for testing.
+type mockDBClient struct {
+	execFunc  func(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	queryFunc func(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+func (m *mockDBClient) Exec(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	if m.execFunc != nil {
+		return m.execFunc(ctx, query, args...)
+	}
+	return mockResult{rowsAffected: 1}, nil
+}
+
+func (m *mockDBClient) Query(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	if m.queryFunc != nil {
+		return m.queryFunc(ctx, query, args...)
+	}
+	return nil, errors.New("mock not configured")
+}
+
+func (m *mockDBClient) QueryRow(context.Context, string, ...interface{}) *sql.Row {
+	return nil
+}
+
+func (m *mockDBClient) BeginTx(context.Context, *sql.TxOptions) (*sql.Tx, error) {
+	return nil, errors.New("mock not configured")
+}
+
+func (m *mockDBClient) CommitTx(*sql.Tx) error {
+	return nil
+}
+
+func (m *mockDBClient) RollbackTx(*sql.Tx) error {
+	return nil
+}
+
+// Ensure mockDBClient implements db.DBClient.
+var _ db.DBClient = (*mockDBClient)(nil)
+
+func TestRepository_Save(t *testing.T) {
+	t.Parallel()
+
+	itemID, userID := uuid.New(), uuid.New()
+	updatedAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		execErr   error
+		wantErr   error
+		name      string
+		path      string
+		wantQuery string
+		wantArgs  []interface{}
+	}{
+		{
+			name:      "upsert path",
+			path:      "prod/db/payments",
+			wantQuery: "INSERT INTO aegis_vault_keeper.item_paths",
+			wantArgs:  []interface{}{userID, itemID, "prod/db/payments", updatedAt},
+		},
+		{
+			name:      "empty path deletes path",
+			wantQuery: "DELETE FROM aegis_vault_keeper.item_paths",
+			wantArgs:  []interface{}{userID, itemID},
+		},
+		{
+			name:      "path taken",
+			path:      "prod/db/payments",
+			wantQuery: "INSERT INTO aegis_vault_keeper.item_paths",
+			execErr:   &pgconn.PgError{Code: uniqueViolation},
+			wantErr:   ErrPathTaken,
+		},
+		{
+			name:      "exec error",
+			path:      "prod/db/payments",
+			wantQuery: "INSERT INTO aegis_vault_keeper.item_paths",
+			execErr:   errors.New("connection refused"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var gotArgs []interface{}
+			client := &mockDBClient{
+				execFunc: func(_ context.Context, query string, args ...interface{}) (sql.Result, error) {
+					assert.Contains(t, query, tt.wantQuery)
+					gotArgs = args
+					return mockResult{rowsAffected: 1}, tt.execErr
+				},
+			}
+
+			entity := &itempath.ItemPath{ItemID: itemID, UserID: userID, Path: tt.path, UpdatedAt: updatedAt}
+			err := NewRepository(client).Save(context.Background(), SaveParams{Entity: entity})
+
+			switch {
+			case tt.wantErr != nil:
+				assert.ErrorIs(t, err, tt.wantErr)
+			case tt.execErr != nil:
+				assert.ErrorIs(t, err, tt.execErr)
+			default:
+				require.NoError(t, err)
+				assert.Equal(t, tt.wantArgs, gotArgs)
+			}
+		})
+	}
+}
+
+func TestRepository_Load(t *testing.T) {
+	t.Parallel()
+
+	itemID, userID := uuid.New(), uuid.New()
+
+	tests := []struct {
+		name      string
+		params    LoadParams
+		wantQuery string
+		noQuery   string
+		wantArgs  []interface{}
+	}{
+		{
+			name:      "all paths",
+			params:    LoadParams{UserID: userID},
+			wantQuery: "WHERE user_id = $1",
+			noQuery:   "$2",
+			wantArgs:  []interface{}{userID},
+		},
+		{
+			name:      "single item",
+			params:    LoadParams{UserID: userID, ItemID: itemID},
+			wantQuery: "AND item_id = $2",
+			wantArgs:  []interface{}{userID, itemID},
+		},
+		{
+			name:      "exact path",
+			params:    LoadParams{UserID: userID, Path: "prod/db"},
+			wantQuery: "AND path = $2",
+			wantArgs:  []interface{}{userID, "prod/db"},
+		},
+		{
+			name:      "prefix",
+			params:    LoadParams{UserID: userID, Prefix: "prod"},
+			wantQuery: "AND (path = $2 OR starts_with(path, $2 || '/'))",
+			wantArgs:  []interface{}{userID, "prod"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			queryErr := errors.New("query error")
+			var gotQuery string
+			var gotArgs []interface{}
+			client := &mockDBClient{
+				queryFunc: func(_ context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+					gotQuery, gotArgs = query, args
+					return nil, queryErr
+				},
+			}
+
+			_, err := NewRepository(client).Load(context.Background(), tt.params)
+
+			require.ErrorIs(t, err, queryErr)
+			assert.Contains(t, gotQuery, tt.wantQuery)
+			if tt.noQuery != "" {
+				assert.NotContains(t, gotQuery, tt.noQuery)
+			}
+			assert.Equal(t, tt.wantArgs, gotArgs)
+		})
+	}
+}
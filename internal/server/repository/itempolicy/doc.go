@@ -0,0 +1,5 @@
+// Package itempolicy provides vault item policy persistence for the AegisVaultKeeper server.
+//
+// This package implements storage of the item policies administrators set on the vault items of every user or
+// of the members of a directory group in PostgreSQL.
+package itempolicy
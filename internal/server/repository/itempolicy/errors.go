@@ -0,0 +1,6 @@
+package itempolicy
+
+import "errors"
+
+// ErrPolicyNotFound indicates that the requested item policy was not found in the repository.
+var ErrPolicyNotFound = errors.New("item policy not found")
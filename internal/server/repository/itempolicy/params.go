@@ -0,0 +1,24 @@
+package itempolicy
+
+import (
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/itempolicy"
+	"github.com/google/uuid"
+)
+
+// SaveParams contains the parameters for saving an item policy to the repository.
+type SaveParams struct {
+	// Entity contains the item policy to be created or updated.
+	Entity *itempolicy.Policy
+}
+
+// LoadParams contains the parameters for loading item policies from the repository.
+type LoadParams struct {
+	// ID restricts the result to the policy with this identifier when set; otherwise every policy is loaded.
+	ID uuid.UUID
+}
+
+// DeleteParams contains the parameters for deleting an item policy from the repository.
+type DeleteParams struct {
+	// ID identifies the policy to delete.
+	ID uuid.UUID
+}
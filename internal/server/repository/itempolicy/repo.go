@@ -0,0 +1,125 @@
+package itempolicy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/auth"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/itempolicy"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/db"
+	"github.com/google/uuid"
+)
+
+// Repository provides item policy persistence operations.
+type Repository struct {
+	// db is the database client used for item policy operations.
+	db db.DBClient
+}
+
+// NewRepository creates a new Repository with the provided database client.
+func NewRepository(dbClient db.DBClient) *Repository {
+	return &Repository{db: dbClient}
+}
+
+// rulesRecord is the stored JSON form of item policy rules.
+type rulesRecord struct {
+	// ForbiddenKinds lists the item types that may not be stored.
+	ForbiddenKinds []string `json:"forbidden_kinds,omitempty"`
+	// RequiredClasses lists the character classes every credential password must contain.
+	RequiredClasses []auth.PasswordClass `json:"required_classes,omitempty"`
+	// MinPasswordLength specifies the minimum length of credential passwords.
+	MinPasswordLength int `json:"min_password_length,omitzero"`
+	// BanCommonPasswords determines whether commonly used credential passwords are rejected.
+	BanCommonPasswords bool `json:"ban_common_passwords,omitzero"`
+}
+
+// Save creates the item policy or updates the existing policy with the same ID.
+func (r *Repository) Save(ctx context.Context, params SaveParams) error {
+	e := params.Entity
+	groupID := uuid.NullUUID{UUID: e.GroupID, Valid: e.GroupID != uuid.Nil}
+
+	rules, err := json.Marshal(rulesRecord(e.Rules))
+	if err != nil {
+		return fmt.Errorf("failed to encode item policy rules: %w", err)
+	}
+
+	query := `
+		INSERT INTO aegis_vault_keeper.item_policies (id, group_id, name, rules, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (id) DO UPDATE SET
+			group_id = EXCLUDED.group_id,
+			name = EXCLUDED.name,
+			rules = EXCLUDED.rules,
+			updated_at = EXCLUDED.updated_at
+	`
+	if _, err := r.db.Exec(ctx, query, e.ID, groupID, e.Name, rules, e.CreatedAt, e.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to save item policy: %w", err)
+	}
+	return nil
+}
+
+// Load retrieves the item policies matching the parameters ordered by creation time.
+// ErrPolicyNotFound is returned when a policy requested by ID does not exist.
+func (r *Repository) Load(ctx context.Context, params LoadParams) ([]*itempolicy.Policy, error) {
+	query := `
+		SELECT id, group_id, name, rules, created_at, updated_at
+		FROM aegis_vault_keeper.item_policies
+	`
+	// args holds the arguments of the query.
+	var args []any
+	if params.ID != uuid.Nil {
+		query += " WHERE id = $1"
+		args = append(args, params.ID)
+	}
+	query += " ORDER BY created_at, id"
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load item policies: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var policies []*itempolicy.Policy
+	for rows.Next() {
+		var (
+			p       itempolicy.Policy
+			groupID uuid.NullUUID
+			rules   []byte
+		)
+		if err := rows.Scan(&p.ID, &groupID, &p.Name, &rules, &p.CreatedAt, &p.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan item policy: %w", err)
+		}
+		var record rulesRecord
+		if err := json.Unmarshal(rules, &record); err != nil {
+			return nil, fmt.Errorf("failed to decode item policy rules: %w", err)
+		}
+		p.GroupID = groupID.UUID
+		p.Rules = itempolicy.Rules(record)
+		policies = append(policies, &p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate item policies: %w", err)
+	}
+	if params.ID != uuid.Nil && len(policies) == 0 {
+		return nil, ErrPolicyNotFound
+	}
+	return policies, nil
+}
+
+// Delete removes the item policy.
+func (r *Repository) Delete(ctx context.Context, params DeleteParams) error {
+	query := `DELETE FROM aegis_vault_keeper.item_policies WHERE id = $1`
+	res, err := r.db.Exec(ctx, query, params.ID)
+	if err != nil {
+		return fmt.Errorf("failed to delete item policy: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check deleted item policies: %w", err)
+	}
+	if n == 0 {
+		return ErrPolicyNotFound
+	}
+	return nil
+}
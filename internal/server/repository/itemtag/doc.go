@@ -0,0 +1,4 @@
+// Package itemtag provides item tag persistence for the AegisVaultKeeper server.
+//
+// This package implements storage of the tags users attach to their vault items in PostgreSQL.
+package itemtag
@@ -0,0 +1,32 @@
+package itemtag
+
+import (
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/itemtag"
+	"github.com/google/uuid"
+)
+
+// SaveParams contains the parameters for saving the tags of an item to the repository.
+type SaveParams struct {
+	// Entity contains the item tags to be persisted; an empty tag set removes the stored tags.
+	Entity *itemtag.ItemTags
+}
+
+// LoadParams contains the parameters for loading item tags from the repository.
+type LoadParams struct {
+	// Tag selects only the items carrying the tag; empty loads items with any tags.
+	Tag string
+	// UserID selects the items of the specified user.
+	UserID uuid.UUID
+	// ItemID selects a single item; uuid.Nil loads the tags of every item of the user.
+	ItemID uuid.UUID
+}
+
+// ExistsParams contains the parameters for checking whether items carry a tag.
+type ExistsParams struct {
+	// Tag contains the tag to look for.
+	Tag string
+	// UserID selects the items of the specified user.
+	UserID uuid.UUID
+	// ItemID selects a single item; uuid.Nil checks every item of the user.
+	ItemID uuid.UUID
+}
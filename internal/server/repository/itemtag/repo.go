@@ -0,0 +1,121 @@
+package itemtag
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/itemtag"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/db"
+	"github.com/google/uuid"
+)
+
+// Repository provides item tag persistence operations.
+type Repository struct {
+	// db is the database client used for tag operations.
+	db db.DBClient
+}
+
+// NewRepository creates a new Repository with the provided database client.
+func NewRepository(dbClient db.DBClient) *Repository {
+	return &Repository{db: dbClient}
+}
+
+// Save replaces the stored tags of the item, removing them when the tag set is empty.
+func (r *Repository) Save(ctx context.Context, params SaveParams) error {
+	e := params.Entity
+
+	if len(e.Tags) == 0 {
+		query := "DELETE FROM aegis_vault_keeper.item_tags WHERE user_id = $1 AND item_id = $2"
+		if _, err := r.db.Exec(ctx, query, e.UserID, e.ItemID); err != nil {
+			return fmt.Errorf("failed to delete item tags: %w", err)
+		}
+		return nil
+	}
+
+	query := `
+		INSERT INTO aegis_vault_keeper.item_tags (user_id, item_id, tags, updated_at)
+		VALUES ($1, $2, $3::text[], $4)
+		ON CONFLICT (user_id, item_id) DO UPDATE SET
+			tags = EXCLUDED.tags,
+			updated_at = EXCLUDED.updated_at
+	`
+	if _, err := r.db.Exec(ctx, query, e.UserID, e.ItemID, e.Tags, e.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to save item tags: %w", err)
+	}
+	return nil
+}
+
+// Load retrieves the tagged items of the user, most recently tagged first.
+func (r *Repository) Load(ctx context.Context, params LoadParams) ([]*itemtag.ItemTags, error) {
+	query := `
+		SELECT user_id, item_id, array_to_string(tags, ','), updated_at
+		FROM aegis_vault_keeper.item_tags
+		WHERE user_id = $1
+	`
+	args := []any{params.UserID}
+	if params.ItemID != uuid.Nil {
+		args = append(args, params.ItemID)
+		query += fmt.Sprintf(" AND item_id = $%d", len(args))
+	}
+	if params.Tag != "" {
+		args = append(args, params.Tag)
+		query += fmt.Sprintf(" AND $%d = ANY (tags)", len(args))
+	}
+	query += " ORDER BY updated_at DESC, item_id"
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load item tags: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var result []*itemtag.ItemTags
+	for rows.Next() {
+		var (
+			t    itemtag.ItemTags
+			tags string
+		)
+		if err := rows.Scan(&t.UserID, &t.ItemID, &tags, &t.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan item tags: %w", err)
+		}
+		t.Tags = strings.Split(tags, ",")
+		result = append(result, &t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate item tags: %w", err)
+	}
+	return result, nil
+}
+
+// Exists reports whether the item, or any item of the user when no item is specified, carries the tag.
+func (r *Repository) Exists(ctx context.Context, params ExistsParams) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM aegis_vault_keeper.item_tags
+			WHERE user_id = $1 AND $2 = ANY (tags)
+	`
+	args := []any{params.UserID, params.Tag}
+	if params.ItemID != uuid.Nil {
+		query += " AND item_id = $3"
+		args = append(args, params.ItemID)
+	}
+	query += ")"
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return false, fmt.Errorf("failed to check item tags: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var exists bool
+	if rows.Next() {
+		if err := rows.Scan(&exists); err != nil {
+			return false, fmt.Errorf("failed to scan item tag check: %w", err)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return false, fmt.Errorf("failed to iterate item tag check: %w", err)
+	}
+	return exists, nil
+}
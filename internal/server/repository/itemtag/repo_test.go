@@ -0,0 +1,213 @@
+package itemtag
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/itemtag"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/db"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockResult implements sql.Result for testing.
+type mockResult struct {
+	rowsAffected int64
+}
+
+func (m mockResult) LastInsertId() (int64, error) { return 0, nil }
+func (m mockResult) RowsAffected() (int64, error) { return m.rowsAffected, nil }
+
+// mockDBClient implements db.DBClient for testing.
+type mockDBClient struct {
+	execFunc  func(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	queryFunc func(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+func (m *mockDBClient) Exec(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	if m.execFunc != nil {
+		return m.execFunc(ctx, query, args...)
+	}
+	return mockResult{rowsAffected: 1}, nil
+}
+
+func (m *mockDBClient) Query(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	if m.queryFunc != nil {
+		return m.queryFunc(ctx, query, args...)
+	}
+	return nil, errors.New("mock not configured")
+}
+
+func (m *mockDBClient) QueryRow(context.Context, string, ...interface{}) *sql.Row {
+	return nil
+}
+
+func (m *mockDBClient) BeginTx(context.Context, *sql.TxOptions) (*sql.Tx, error) {
+	return nil, errors.New("mock not configured")
+}
+
+func (m *mockDBClient) CommitTx(*sql.Tx) error {
+	return nil
+}
+
+func (m *mockDBClient) RollbackTx(*sql.Tx) error {
+	return nil
+}
+
+// Ensure mockDBClient implements db.DBClient.
+var _ db.DBClient = (*mockDBClient)(nil)
+
+func TestRepository_Save(t *testing.T) {
+	t.Parallel()
+
+	itemID, userID := uuid.New(), uuid.New()
+	updatedAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		execErr   error
+		name      string
+		wantQuery string
+		wantArgs  []interface{}
+		tags      []string
+	}{
+		{
+			name:      "upsert tags",
+			tags:      []string{"restricted", "work"},
+			wantQuery: "INSERT INTO aegis_vault_keeper.item_tags",
+			wantArgs:  []interface{}{userID, itemID, []string{"restricted", "work"}, updatedAt},
+		},
+		{
+			name:      "empty set deletes tags",
+			tags:      []string{},
+			wantQuery: "DELETE FROM aegis_vault_keeper.item_tags",
+			wantArgs:  []interface{}{userID, itemID},
+		},
+		{
+			name:      "exec error",
+			tags:      []string{"work"},
+			wantQuery: "INSERT INTO aegis_vault_keeper.item_tags",
+			execErr:   errors.New("connection refused"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var gotArgs []interface{}
+			client := &mockDBClient{
+				execFunc: func(_ context.Context, query string, args ...interface{}) (sql.Result, error) {
+					assert.Contains(t, query, tt.wantQuery)
+					gotArgs = args
+					return mockResult{rowsAffected: 1}, tt.execErr
+				},
+			}
+
+			entity := &itemtag.ItemTags{ItemID: itemID, UserID: userID, Tags: tt.tags, UpdatedAt: updatedAt}
+			err := NewRepository(client).Save(context.Background(), SaveParams{Entity: entity})
+
+			if tt.execErr != nil {
+				assert.ErrorIs(t, err, tt.execErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantArgs, gotArgs)
+		})
+	}
+}
+
+func TestRepository_Queries(t *testing.T) {
+	t.Parallel()
+
+	itemID, userID := uuid.New(), uuid.New()
+
+	tests := []struct {
+		run       func(r *Repository) error
+		name      string
+		wantQuery string
+		noQuery   string
+		wantArgs  []interface{}
+	}{
+		{
+			name: "load all items",
+			run: func(r *Repository) error {
+				_, err := r.Load(context.Background(), LoadParams{UserID: userID})
+				return err
+			},
+			wantQuery: "WHERE user_id = $1",
+			noQuery:   "item_id = $2",
+			wantArgs:  []interface{}{userID},
+		},
+		{
+			name: "load single item",
+			run: func(r *Repository) error {
+				_, err := r.Load(context.Background(), LoadParams{UserID: userID, ItemID: itemID})
+				return err
+			},
+			wantQuery: "AND item_id = $2",
+			wantArgs:  []interface{}{userID, itemID},
+		},
+		{
+			name: "load items with tag",
+			run: func(r *Repository) error {
+				_, err := r.Load(context.Background(), LoadParams{UserID: userID, Tag: itemtag.Archived})
+				return err
+			},
+			wantQuery: "AND $2 = ANY (tags)",
+			noQuery:   "item_id = $",
+			wantArgs:  []interface{}{userID, itemtag.Archived},
+		},
+		{
+			name: "any item tagged",
+			run: func(r *Repository) error {
+				_, err := r.Exists(context.Background(), ExistsParams{UserID: userID, Tag: itemtag.Restricted})
+				return err
+			},
+			wantQuery: "$2 = ANY (tags)",
+			noQuery:   "item_id = $3",
+			wantArgs:  []interface{}{userID, itemtag.Restricted},
+		},
+		{
+			name: "single item tagged",
+			run: func(r *Repository) error {
+				_, err := r.Exists(context.Background(), ExistsParams{
+					UserID: userID,
+					ItemID: itemID,
+					Tag:    itemtag.Restricted,
+				})
+				return err
+			},
+			wantQuery: "AND item_id = $3)",
+			wantArgs:  []interface{}{userID, itemtag.Restricted, itemID},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			queryErr := errors.New("query error")
+			var gotQuery string
+			var gotArgs []interface{}
+			client := &mockDBClient{
+				queryFunc: func(_ context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+					gotQuery, gotArgs = query, args
+					return nil, queryErr
+				},
+			}
+
+			err := tt.run(NewRepository(client))
+
+			require.ErrorIs(t, err, queryErr)
+			assert.Contains(t, gotQuery, tt.wantQuery)
+			if tt.noQuery != "" {
+				assert.NotContains(t, gotQuery, tt.noQuery)
+			}
+			assert.Equal(t, tt.wantArgs, gotArgs)
+		})
+	}
+}
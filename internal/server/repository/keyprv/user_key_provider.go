@@ -9,5 +9,6 @@ import (
 // UserKeyProvider interface for retrieving user-specific encryption keys.
 // Implementations must provide secure key derivation and storage.
 type UserKeyProvider interface {
+	// UserKeyProvide returns a fresh copy of the user key; the caller wipes it once done.
 	UserKeyProvide(ctx context.Context, userID uuid.UUID) ([]byte, error)
 }
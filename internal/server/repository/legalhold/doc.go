@@ -0,0 +1,5 @@
+// Package legalhold provides legal hold persistence for the AegisVaultKeeper server.
+//
+// This package implements storage of the legal holds placed on the data of users and on single items in
+// PostgreSQL.
+package legalhold
@@ -0,0 +1,6 @@
+package legalhold
+
+import "errors"
+
+// ErrHoldNotFound indicates that the requested legal hold was not found in the repository.
+var ErrHoldNotFound = errors.New("legal hold not found")
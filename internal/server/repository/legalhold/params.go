@@ -0,0 +1,27 @@
+package legalhold
+
+import (
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/legalhold"
+	"github.com/google/uuid"
+)
+
+// SaveParams contains the parameters for saving a legal hold to the repository.
+type SaveParams struct {
+	// Entity contains the legal hold to be persisted.
+	Entity *legalhold.Hold
+}
+
+// LoadParams contains the parameters for loading legal holds from the repository.
+// When neither field is set, every hold is loaded.
+type LoadParams struct {
+	// ID restricts the result to the hold with this identifier when set.
+	ID uuid.UUID
+	// UserID restricts the result to the holds on the data of this user when set.
+	UserID uuid.UUID
+}
+
+// DeleteParams contains the parameters for deleting a legal hold from the repository.
+type DeleteParams struct {
+	// ID identifies the hold to delete.
+	ID uuid.UUID
+}
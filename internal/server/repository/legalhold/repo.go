@@ -0,0 +1,105 @@
+package legalhold
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/legalhold"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/db"
+	"github.com/google/uuid"
+)
+
+// Repository provides legal hold persistence operations.
+type Repository struct {
+	// db is the database client used for legal hold operations.
+	db db.DBClient
+}
+
+// NewRepository creates a new Repository with the provided database client.
+func NewRepository(dbClient db.DBClient) *Repository {
+	return &Repository{db: dbClient}
+}
+
+// Save stores a new legal hold.
+func (r *Repository) Save(ctx context.Context, params SaveParams) error {
+	e := params.Entity
+	itemID := uuid.NullUUID{UUID: e.ItemID, Valid: e.ItemID != uuid.Nil}
+
+	query := `
+		INSERT INTO aegis_vault_keeper.legal_holds (id, user_id, item_id, reason, placed_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+	if _, err := r.db.Exec(ctx, query, e.ID, e.UserID, itemID, e.Reason, e.PlacedAt); err != nil {
+		return fmt.Errorf("failed to save legal hold: %w", err)
+	}
+	return nil
+}
+
+// Load retrieves the legal holds matching the parameters ordered by placement time.
+// ErrHoldNotFound is returned when a hold requested by ID does not exist.
+func (r *Repository) Load(ctx context.Context, params LoadParams) ([]*legalhold.Hold, error) {
+	// conditions and args hold the filters of the query with their arguments.
+	var conditions []string
+	var args []any
+	if params.ID != uuid.Nil {
+		args = append(args, params.ID)
+		conditions = append(conditions, fmt.Sprintf("id = $%d", len(args)))
+	}
+	if params.UserID != uuid.Nil {
+		args = append(args, params.UserID)
+		conditions = append(conditions, fmt.Sprintf("user_id = $%d", len(args)))
+	}
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, user_id, item_id, reason, placed_at
+		FROM aegis_vault_keeper.legal_holds
+		%s
+		ORDER BY placed_at, id
+	`, where)
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load legal holds: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var holds []*legalhold.Hold
+	for rows.Next() {
+		var h legalhold.Hold
+		// itemID holds the nullable item identifier.
+		var itemID uuid.NullUUID
+		if err := rows.Scan(&h.ID, &h.UserID, &itemID, &h.Reason, &h.PlacedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan legal hold: %w", err)
+		}
+		h.ItemID = itemID.UUID
+		holds = append(holds, &h)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate legal holds: %w", err)
+	}
+	if params.ID != uuid.Nil && len(holds) == 0 {
+		return nil, ErrHoldNotFound
+	}
+	return holds, nil
+}
+
+// Delete removes the legal hold.
+func (r *Repository) Delete(ctx context.Context, params DeleteParams) error {
+	query := `DELETE FROM aegis_vault_keeper.legal_holds WHERE id = $1`
+	res, err := r.db.Exec(ctx, query, params.ID)
+	if err != nil {
+		return fmt.Errorf("failed to delete legal hold: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check deleted legal holds: %w", err)
+	}
+	if n == 0 {
+		return ErrHoldNotFound
+	}
+	return nil
+}
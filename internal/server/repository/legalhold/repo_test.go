@@ -0,0 +1,215 @@
+package legalhold
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/legalhold"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/db"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockResult implements sql.Result for testing.
+type mockResult struct {
+	rowsAffected int64
+}
+
+func (m mockResult) LastInsertId() (int64, error) { return 0, nil }
+func (m mockResult) RowsAffected() (int64, error) { return m.rowsAffected, nil }
+
+// mockDBClient implements db.DBClient for testing.
+type mockDBClient struct {
+	execFunc  func(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	queryFunc func(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+func (m *mockDBClient) Exec(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	if m.execFunc != nil {
+		return m.execFunc(ctx, query, args...)
+	}
+	return mockResult{rowsAffected: 1}, nil
+}
+
+func (m *mockDBClient) Query(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	if m.queryFunc != nil {
+		return m.queryFunc(ctx, query, args...)
+	}
+	return nil, errors.New("mock not configured")
+}
+
+func (m *mockDBClient) QueryRow(context.Context, string, ...interface{}) *sql.Row {
+	return nil
+}
+
+func (m *mockDBClient) BeginTx(context.Context, *sql.TxOptions) (*sql.Tx, error) {
+	return nil, errors.New("mock not configured")
+}
+
+func (m *mockDBClient) CommitTx(*sql.Tx) error {
+	return nil
+}
+
+func (m *mockDBClient) RollbackTx(*sql.Tx) error {
+	return nil
+}
+
+// Ensure mockDBClient implements db.DBClient.
+var _ db.DBClient = (*mockDBClient)(nil)
+
+func TestRepository_Save(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	userID := uuid.New()
+	itemID := uuid.New()
+
+	tests := []struct {
+		execErr    error
+		entity     *legalhold.Hold
+		name       string
+		wantItemID uuid.NullUUID
+		wantErr    bool
+	}{
+		{
+			name:   "hold on a user",
+			entity: &legalhold.Hold{ID: uuid.New(), UserID: userID, Reason: "Case 42", PlacedAt: now},
+		},
+		{
+			name:       "hold on an item",
+			entity:     &legalhold.Hold{ID: uuid.New(), UserID: userID, ItemID: itemID, Reason: "Case 42", PlacedAt: now},
+			wantItemID: uuid.NullUUID{UUID: itemID, Valid: true},
+		},
+		{
+			name:    "database error",
+			entity:  &legalhold.Hold{ID: uuid.New(), UserID: userID, Reason: "Case 42", PlacedAt: now},
+			execErr: errors.New("connection lost"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := &mockDBClient{
+				execFunc: func(_ context.Context, query string, args ...interface{}) (sql.Result, error) {
+					assert.Contains(t, query, "INSERT INTO aegis_vault_keeper.legal_holds")
+					e := tt.entity
+					assert.Equal(t, []interface{}{e.ID, e.UserID, tt.wantItemID, "Case 42", now}, args)
+					if tt.execErr != nil {
+						return nil, tt.execErr
+					}
+					return mockResult{rowsAffected: 1}, nil
+				},
+			}
+
+			err := NewRepository(client).Save(context.Background(), SaveParams{Entity: tt.entity})
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.ErrorIs(t, err, tt.execErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestRepository_Load(t *testing.T) {
+	t.Parallel()
+
+	id := uuid.New()
+	userID := uuid.New()
+	dbErr := errors.New("connection lost")
+
+	tests := []struct {
+		wantArgs  []interface{}
+		name      string
+		wantWhere string
+		params    LoadParams
+	}{
+		{name: "every hold"},
+		{
+			name:      "holds of a user",
+			params:    LoadParams{UserID: userID},
+			wantWhere: "WHERE user_id = $1",
+			wantArgs:  []interface{}{userID},
+		},
+		{
+			name:      "hold by ID",
+			params:    LoadParams{ID: id, UserID: userID},
+			wantWhere: "WHERE id = $1 AND user_id = $2",
+			wantArgs:  []interface{}{id, userID},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := &mockDBClient{
+				queryFunc: func(_ context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+					if tt.wantWhere == "" {
+						assert.NotContains(t, query, "WHERE")
+					} else {
+						assert.Contains(t, query, tt.wantWhere)
+					}
+					assert.Equal(t, tt.wantArgs, args)
+					return nil, dbErr
+				},
+			}
+
+			holds, err := NewRepository(client).Load(context.Background(), tt.params)
+
+			require.Error(t, err)
+			assert.ErrorIs(t, err, dbErr)
+			assert.Nil(t, holds)
+		})
+	}
+}
+
+func TestRepository_Delete(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		execErr  error
+		wantErr  error
+		name     string
+		affected int64
+	}{
+		{name: "deleted", affected: 1},
+		{name: "not found", affected: 0, wantErr: ErrHoldNotFound},
+		{name: "database error", execErr: errors.New("connection lost")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			id := uuid.New()
+			client := &mockDBClient{
+				execFunc: func(_ context.Context, _ string, args ...interface{}) (sql.Result, error) {
+					assert.Equal(t, []interface{}{id}, args)
+					if tt.execErr != nil {
+						return nil, tt.execErr
+					}
+					return mockResult{rowsAffected: tt.affected}, nil
+				},
+			}
+
+			err := NewRepository(client).Delete(context.Background(), DeleteParams{ID: id})
+			switch {
+			case tt.execErr != nil:
+				assert.ErrorIs(t, err, tt.execErr)
+			case tt.wantErr != nil:
+				assert.ErrorIs(t, err, tt.wantErr)
+			default:
+				require.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,5 @@
+// Package license provides license key persistence for the AegisVaultKeeper server.
+//
+// This package stores the license keys installed by administrators in PostgreSQL, so every server instance
+// shares the latest installed license. Keys are stored as issued and verified again when loaded.
+package license
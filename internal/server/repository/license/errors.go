@@ -0,0 +1,9 @@
+package license
+
+import "errors"
+
+// License repository error definitions.
+var (
+	// ErrLicenseNotFound indicates that no license key is installed.
+	ErrLicenseNotFound = errors.New("license not found")
+)
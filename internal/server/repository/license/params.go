@@ -0,0 +1,13 @@
+package license
+
+import "time"
+
+// SaveParams contains the parameters for saving an installed license key to the repository.
+type SaveParams struct {
+	// InstalledAt is the time the license key was installed.
+	InstalledAt time.Time
+	// ID identifies the license; installing it again replaces the stored key.
+	ID string
+	// Key contains the license key as issued.
+	Key string
+}
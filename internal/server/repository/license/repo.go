@@ -0,0 +1,61 @@
+package license
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/db"
+)
+
+// Repository provides license key persistence operations.
+type Repository struct {
+	// db is the database client used for license operations.
+	db db.DBClient
+}
+
+// NewRepository creates a new Repository with the provided database client.
+func NewRepository(dbClient db.DBClient) *Repository {
+	return &Repository{db: dbClient}
+}
+
+// Save stores the installed license key. Installing a license again replaces its key and installation time.
+func (r *Repository) Save(ctx context.Context, params SaveParams) error {
+	query := `
+		INSERT INTO aegis_vault_keeper.licenses (id, key, installed_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (id) DO UPDATE SET key = EXCLUDED.key, installed_at = EXCLUDED.installed_at
+	`
+	if _, err := r.db.Exec(ctx, query, params.ID, params.Key, params.InstalledAt); err != nil {
+		return fmt.Errorf("failed to save license: %w", err)
+	}
+	return nil
+}
+
+// Load retrieves the most recently installed license key.
+// Returns ErrLicenseNotFound when no license key is installed.
+func (r *Repository) Load(ctx context.Context) (string, error) {
+	query := `
+		SELECT key
+		FROM aegis_vault_keeper.licenses
+		ORDER BY installed_at DESC, id
+		LIMIT 1
+	`
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return "", fmt.Errorf("failed to load license: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return "", fmt.Errorf("failed to load license: %w", err)
+		}
+		return "", ErrLicenseNotFound
+	}
+	// key holds the installed license key.
+	var key string
+	if err := rows.Scan(&key); err != nil {
+		return "", fmt.Errorf("failed to scan license: %w", err)
+	}
+	return key, nil
+}
@@ -0,0 +1,127 @@
+package license
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockResult implements sql.Result for testing.
+type mockResult struct {
+	rowsAffected int64
+}
+
+func (m mockResult) LastInsertId() (int64, error) { return 0, nil }
+func (m mockResult) RowsAffected() (int64, error) { return m.rowsAffected, nil }
+
+// mockDBClient implements db.DBClient for testing.
+type mockDBClient struct {
+	execFunc  func(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	queryFunc func(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+func (m *mockDBClient) Exec(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	if m.execFunc != nil {
+		return m.execFunc(ctx, query, args...)
+	}
+	return mockResult{rowsAffected: 1}, nil
+}
+
+func (m *mockDBClient) Query(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	if m.queryFunc != nil {
+		return m.queryFunc(ctx, query, args...)
+	}
+	return nil, errors.New("mock not configured")
+}
+
+func (m *mockDBClient) QueryRow(context.Context, string, ...interface{}) *sql.Row {
+	return nil
+}
+
+func (m *mockDBClient) BeginTx(context.Context, *sql.TxOptions) (*sql.Tx, error) {
+	return nil, errors.New("mock not configured")
+}
+
+func (m *mockDBClient) CommitTx(*sql.Tx) error {
+	return nil
+}
+
+func (m *mockDBClient) RollbackTx(*sql.Tx) error {
+	return nil
+}
+
+// Ensure mockDBClient implements db.DBClient.
+var _ db.DBClient = (*mockDBClient)(nil)
+
+func TestRepository_Save(t *testing.T) {
+	t.Parallel()
+
+	installedAt := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	dbErr := errors.New("database error")
+
+	tests := []struct {
+		execErr  error
+		wantErr  error
+		name     string
+		wantArgs []interface{}
+	}{
+		{
+			name:     "saved",
+			wantArgs: []interface{}{"lic_1", "AVK1.payload.signature", installedAt},
+		},
+		{
+			name:    "database failure",
+			execErr: dbErr,
+			wantErr: dbErr,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// gotArgs holds the arguments of the executed statement.
+			var gotArgs []interface{}
+			repo := NewRepository(&mockDBClient{
+				execFunc: func(_ context.Context, _ string, args ...interface{}) (sql.Result, error) {
+					gotArgs = args
+					return mockResult{rowsAffected: 1}, tt.execErr
+				},
+			})
+
+			err := repo.Save(context.Background(), SaveParams{
+				InstalledAt: installedAt,
+				ID:          "lic_1",
+				Key:         "AVK1.payload.signature",
+			})
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantArgs, gotArgs)
+		})
+	}
+}
+
+func TestRepository_Load(t *testing.T) {
+	t.Parallel()
+
+	dbErr := errors.New("database error")
+	repo := NewRepository(&mockDBClient{
+		queryFunc: func(context.Context, string, ...interface{}) (*sql.Rows, error) {
+			return nil, dbErr
+		},
+	})
+
+	_, err := repo.Load(context.Background())
+
+	require.ErrorIs(t, err, dbErr)
+}
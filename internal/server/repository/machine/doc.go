@@ -0,0 +1,5 @@
+// Package machine provides machine identity persistence for the AegisVaultKeeper server.
+//
+// This package implements storage of machine identities in PostgreSQL. Only the digests of machine
+// tokens are stored, so identities are looked up by the digest of the presented token.
+package machine
@@ -0,0 +1,6 @@
+package machine
+
+import "errors"
+
+// ErrMachineNotFound indicates that the requested machine identity was not found in the repository.
+var ErrMachineNotFound = errors.New("machine identity not found")
@@ -0,0 +1,31 @@
+package machine
+
+import (
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/machine"
+	"github.com/google/uuid"
+)
+
+// SaveParams contains the parameters for saving a machine identity to the repository.
+type SaveParams struct {
+	// Entity contains the machine identity to be created or updated.
+	Entity *machine.Machine
+}
+
+// LoadParams contains the parameters for loading machine identities from the repository.
+// Zero fields do not filter.
+type LoadParams struct {
+	// TokenHash selects the identity holding the token with the specified digest.
+	TokenHash []byte
+	// ID selects a single identity.
+	ID uuid.UUID
+	// UserID selects the identities of the specified owner.
+	UserID uuid.UUID
+}
+
+// DeleteParams contains the parameters for deleting a machine identity from the repository.
+type DeleteParams struct {
+	// ID identifies the identity to delete.
+	ID uuid.UUID
+	// UserID identifies the owner of the identity.
+	UserID uuid.UUID
+}
@@ -0,0 +1,153 @@
+package machine
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/machine"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/db"
+	"github.com/google/uuid"
+)
+
+// Repository provides machine identity persistence operations.
+type Repository struct {
+	// db is the database client used for machine identity operations.
+	db db.DBClient
+}
+
+// NewRepository creates a new Repository with the provided database client.
+func NewRepository(dbClient db.DBClient) *Repository {
+	return &Repository{db: dbClient}
+}
+
+// itemRecord is the stored JSON form of a scope item.
+type itemRecord struct {
+	// Kind names the kind of the item.
+	Kind string `json:"kind"`
+	// ID identifies the item.
+	ID uuid.UUID `json:"id"`
+}
+
+// Save creates the machine identity or updates the name, scope and last use of the existing one.
+func (r *Repository) Save(ctx context.Context, params SaveParams) error {
+	e := params.Entity
+
+	records := make([]itemRecord, 0, len(e.Scope))
+	for _, item := range e.Scope {
+		records = append(records, itemRecord{Kind: string(item.Kind), ID: item.ID})
+	}
+	scope, err := json.Marshal(records)
+	if err != nil {
+		return fmt.Errorf("failed to encode machine identity scope: %w", err)
+	}
+
+	query := `
+		INSERT INTO aegis_vault_keeper.machines (id, user_id, name, token_hash, scope, created_at, last_used_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (id) DO UPDATE SET
+			name = EXCLUDED.name,
+			scope = EXCLUDED.scope,
+			last_used_at = EXCLUDED.last_used_at
+	`
+	if _, err := r.db.Exec(ctx, query,
+		e.ID, e.UserID, e.Name, e.TokenHash, scope, e.CreatedAt, nullTime(e.LastUsedAt),
+	); err != nil {
+		return fmt.Errorf("failed to save machine identity: %w", err)
+	}
+	return nil
+}
+
+// Load retrieves machine identities matching the provided parameters, ordered by creation time.
+// Loading by ID or token digest returns ErrMachineNotFound when no identity matches.
+func (r *Repository) Load(ctx context.Context, params LoadParams) ([]*machine.Machine, error) {
+	var (
+		conditions []string
+		args       []interface{}
+	)
+	// filter adds a condition comparing a column with the next positional argument.
+	filter := func(condition string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	if params.ID != uuid.Nil {
+		filter("id = $%d", params.ID)
+	}
+	if params.UserID != uuid.Nil {
+		filter("user_id = $%d", params.UserID)
+	}
+	if params.TokenHash != nil {
+		filter("token_hash = $%d", params.TokenHash)
+	}
+
+	query := `
+		SELECT id, user_id, name, token_hash, scope, created_at, last_used_at
+		FROM aegis_vault_keeper.machines
+	`
+	if len(conditions) != 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY created_at, id"
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load machine identities: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var machines []*machine.Machine
+	for rows.Next() {
+		var (
+			m          machine.Machine
+			scope      []byte
+			lastUsedAt sql.NullTime
+		)
+		if err := rows.Scan(&m.ID, &m.UserID, &m.Name, &m.TokenHash, &scope, &m.CreatedAt, &lastUsedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan machine identity: %w", err)
+		}
+		var records []itemRecord
+		if err := json.Unmarshal(scope, &records); err != nil {
+			return nil, fmt.Errorf("failed to decode machine identity scope: %w", err)
+		}
+		for _, record := range records {
+			m.Scope = append(m.Scope, machine.Item{Kind: machine.Kind(record.Kind), ID: record.ID})
+		}
+		m.LastUsedAt = lastUsedAt.Time
+		machines = append(machines, &m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate machine identities: %w", err)
+	}
+	if (params.ID != uuid.Nil || params.TokenHash != nil) && len(machines) == 0 {
+		return nil, ErrMachineNotFound
+	}
+	return machines, nil
+}
+
+// Delete removes the machine identity of the user.
+func (r *Repository) Delete(ctx context.Context, params DeleteParams) error {
+	query := `
+		DELETE FROM aegis_vault_keeper.machines
+		WHERE id = $1 AND user_id = $2
+	`
+	res, err := r.db.Exec(ctx, query, params.ID, params.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to delete machine identity: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check deleted machine identities: %w", err)
+	}
+	if n == 0 {
+		return ErrMachineNotFound
+	}
+	return nil
+}
+
+// nullTime converts the zero time to SQL NULL.
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
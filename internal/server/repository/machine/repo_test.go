@@ -0,0 +1,211 @@
+package machine
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/machine"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/db"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockResult implements sql.Result for testing.
+type mockResult struct {
+	rowsAffected int64
+}
+
+func (m mockResult) LastInsertId() (int64, error) { return 0, nil }
+func (m mockResult) RowsAffected() (int64, error) { return m.rowsAffected, nil }
+
+// mockDBClient implements db.DBClient for testing.
+type mockDBClient struct {
+	execFunc  func(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	queryFunc func(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+func (m *mockDBClient) Exec(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	if m.execFunc != nil {
+		return m.execFunc(ctx, query, args...)
+	}
+	return mockResult{rowsAffected: 1}, nil
+}
+
+func (m *mockDBClient) Query(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	if m.queryFunc != nil {
+		return m.queryFunc(ctx, query, args...)
+	}
+	return nil, errors.New("mock not configured")
+}
+
+func (m *mockDBClient) QueryRow(context.Context, string, ...interface{}) *sql.Row {
+	return nil
+}
+
+func (m *mockDBClient) BeginTx(context.Context, *sql.TxOptions) (*sql.Tx, error) {
+	return nil, errors.New("mock not configured")
+}
+
+func (m *mockDBClient) CommitTx(*sql.Tx) error {
+	return nil
+}
+
+func (m *mockDBClient) RollbackTx(*sql.Tx) error {
+	return nil
+}
+
+// Ensure mockDBClient implements db.DBClient.
+var _ db.DBClient = (*mockDBClient)(nil)
+
+func TestRepository_Save(t *testing.T) {
+	t.Parallel()
+
+	id, userID, itemID := uuid.New(), uuid.New(), uuid.New()
+	createdAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	tokenHash := machine.HashToken("ci-deploy-token-0123456789")
+
+	tests := []struct {
+		execErr error
+		name    string
+	}{
+		{name: "saved"},
+		{name: "exec error", execErr: errors.New("connection refused")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var gotArgs []interface{}
+			client := &mockDBClient{
+				execFunc: func(_ context.Context, query string, args ...interface{}) (sql.Result, error) {
+					assert.Contains(t, query, "INSERT INTO aegis_vault_keeper.machines")
+					assert.Contains(t, query, "ON CONFLICT (id) DO UPDATE")
+					gotArgs = args
+					return mockResult{rowsAffected: 1}, tt.execErr
+				},
+			}
+
+			err := NewRepository(client).Save(context.Background(), SaveParams{Entity: &machine.Machine{
+				ID:        id,
+				UserID:    userID,
+				Name:      "ci-deploy",
+				TokenHash: tokenHash,
+				Scope:     []machine.Item{{Kind: machine.KindCredential, ID: itemID}},
+				CreatedAt: createdAt,
+			}})
+
+			if tt.execErr != nil {
+				assert.ErrorIs(t, err, tt.execErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, gotArgs, 7)
+			assert.Equal(t, []interface{}{id, userID, "ci-deploy", tokenHash}, gotArgs[:4])
+			assert.JSONEq(t, `[{"kind":"credential","id":"`+itemID.String()+`"}]`, string(gotArgs[4].([]byte)))
+			assert.Equal(t, []interface{}{createdAt, sql.NullTime{}}, gotArgs[5:])
+		})
+	}
+}
+
+func TestRepository_Load(t *testing.T) {
+	t.Parallel()
+
+	id, userID := uuid.New(), uuid.New()
+	tokenHash := machine.HashToken("ci-deploy-token-0123456789")
+
+	tests := []struct {
+		name      string
+		params    LoadParams
+		wantQuery string
+		wantArgs  []interface{}
+	}{
+		{
+			name:      "identities of an owner",
+			params:    LoadParams{UserID: userID},
+			wantQuery: "WHERE user_id = $1",
+			wantArgs:  []interface{}{userID},
+		},
+		{
+			name:      "identity of an owner",
+			params:    LoadParams{ID: id, UserID: userID},
+			wantQuery: "WHERE id = $1 AND user_id = $2",
+			wantArgs:  []interface{}{id, userID},
+		},
+		{
+			name:      "identity holding a token",
+			params:    LoadParams{TokenHash: tokenHash},
+			wantQuery: "WHERE token_hash = $1",
+			wantArgs:  []interface{}{tokenHash},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			queryErr := errors.New("query error")
+			var gotQuery string
+			var gotArgs []interface{}
+			client := &mockDBClient{
+				queryFunc: func(_ context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+					gotQuery, gotArgs = query, args
+					return nil, queryErr
+				},
+			}
+
+			_, err := NewRepository(client).Load(context.Background(), tt.params)
+
+			require.ErrorIs(t, err, queryErr)
+			assert.Contains(t, gotQuery, tt.wantQuery)
+			assert.Contains(t, gotQuery, "ORDER BY created_at, id")
+			assert.Equal(t, tt.wantArgs, gotArgs)
+		})
+	}
+}
+
+func TestRepository_Delete(t *testing.T) {
+	t.Parallel()
+
+	id, userID := uuid.New(), uuid.New()
+
+	tests := []struct {
+		execErr  error
+		wantErr  error
+		name     string
+		affected int64
+	}{
+		{name: "deleted", affected: 1},
+		{name: "not found", wantErr: ErrMachineNotFound},
+		{name: "exec error", execErr: errors.New("connection refused")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := &mockDBClient{
+				execFunc: func(_ context.Context, query string, args ...interface{}) (sql.Result, error) {
+					assert.Contains(t, query, "DELETE FROM aegis_vault_keeper.machines")
+					assert.Equal(t, []interface{}{id, userID}, args)
+					return mockResult{rowsAffected: tt.affected}, tt.execErr
+				},
+			}
+
+			err := NewRepository(client).Delete(context.Background(), DeleteParams{ID: id, UserID: userID})
+
+			switch {
+			case tt.execErr != nil:
+				assert.ErrorIs(t, err, tt.execErr)
+			case tt.wantErr != nil:
+				assert.ErrorIs(t, err, tt.wantErr)
+			default:
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
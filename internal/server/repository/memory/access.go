@@ -0,0 +1,102 @@
+package memory
+
+import (
+	"context"
+	"slices"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/accesspolicy"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/accessrule"
+	repositoryAccesspolicy "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/accesspolicy"
+	repositoryAccessrule "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/accessrule"
+	"github.com/google/uuid"
+)
+
+// AccessRuleRepository keeps network access rules in memory.
+type AccessRuleRepository struct {
+	// rules holds the stored rules.
+	rules table[accessrule.Rule]
+}
+
+// NewAccessRuleRepository creates a new empty AccessRuleRepository.
+func NewAccessRuleRepository() *AccessRuleRepository {
+	return &AccessRuleRepository{}
+}
+
+// Save stores a new access rule.
+func (r *AccessRuleRepository) Save(_ context.Context, params repositoryAccessrule.SaveParams) error {
+	r.rules.add(params.Entity)
+	return nil
+}
+
+// Load retrieves the global rules, the rules of the user, or both, ordered by creation time;
+// all rules when neither is requested.
+func (r *AccessRuleRepository) Load(
+	_ context.Context,
+	params repositoryAccessrule.LoadParams,
+) ([]*accessrule.Rule, error) {
+	rules := r.rules.filter(func(e *accessrule.Rule) bool {
+		return ownedBy(e.UserID, params.UserID, params.Global)
+	})
+	slices.SortFunc(rules, func(a, b *accessrule.Rule) int { return compareCreated(a.CreatedAt, b.CreatedAt, a.ID, b.ID) })
+	return rules, nil
+}
+
+// Delete removes the access rule with the specified ID.
+func (r *AccessRuleRepository) Delete(_ context.Context, params repositoryAccessrule.DeleteParams) error {
+	if r.rules.remove(func(e *accessrule.Rule) bool { return e.ID == params.ID }) == 0 {
+		return repositoryAccessrule.ErrRuleNotFound
+	}
+	return nil
+}
+
+// AccessPolicyRepository keeps access time policies in memory.
+type AccessPolicyRepository struct {
+	// policies holds the stored policies.
+	policies table[accesspolicy.Policy]
+}
+
+// NewAccessPolicyRepository creates a new empty AccessPolicyRepository.
+func NewAccessPolicyRepository() *AccessPolicyRepository {
+	return &AccessPolicyRepository{}
+}
+
+// Save stores a new access policy.
+func (r *AccessPolicyRepository) Save(_ context.Context, params repositoryAccesspolicy.SaveParams) error {
+	r.policies.add(params.Entity)
+	return nil
+}
+
+// Load retrieves the global policies, the policies of the user, or both, ordered by creation time;
+// all policies when neither is requested.
+func (r *AccessPolicyRepository) Load(
+	_ context.Context,
+	params repositoryAccesspolicy.LoadParams,
+) ([]*accesspolicy.Policy, error) {
+	policies := r.policies.filter(func(e *accesspolicy.Policy) bool {
+		return ownedBy(e.UserID, params.UserID, params.Global)
+	})
+	slices.SortFunc(policies, func(a, b *accesspolicy.Policy) int {
+		return compareCreated(a.CreatedAt, b.CreatedAt, a.ID, b.ID)
+	})
+	return policies, nil
+}
+
+// Delete removes the access policy with the specified ID. When a user is specified, only a policy
+// that user defined is removed; policies managed by administrators are left in place.
+func (r *AccessPolicyRepository) Delete(_ context.Context, params repositoryAccesspolicy.DeleteParams) error {
+	if r.policies.remove(func(e *accesspolicy.Policy) bool {
+		return e.ID == params.ID && (params.UserID == uuid.Nil || (e.UserID == params.UserID && !e.Managed))
+	}) == 0 {
+		return repositoryAccesspolicy.ErrPolicyNotFound
+	}
+	return nil
+}
+
+// ownedBy reports whether an entity with the owner matches a load of the global entities, the entities
+// of the user, or both; every entity matches when neither is requested.
+func ownedBy(owner, userID uuid.UUID, global bool) bool {
+	if !global && userID == uuid.Nil {
+		return true
+	}
+	return (global && owner == uuid.Nil) || (userID != uuid.Nil && owner == userID)
+}
@@ -0,0 +1,41 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/acmeaccount"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/acmeaccount"
+	"github.com/google/uuid"
+)
+
+// AcmeAccountRepository keeps ACME accounts in memory.
+type AcmeAccountRepository struct {
+	// accounts holds the stored accounts.
+	accounts table[acmeaccount.Account]
+}
+
+// NewAcmeAccountRepository creates a new empty AcmeAccountRepository.
+func NewAcmeAccountRepository() *AcmeAccountRepository {
+	return &AcmeAccountRepository{}
+}
+
+// Save creates or updates an ACME account.
+func (r *AcmeAccountRepository) Save(_ context.Context, params repository.SaveParams) error {
+	e := params.Entity
+	r.accounts.put(e, func(a *acmeaccount.Account) bool { return a.ID == e.ID }, nil)
+	return nil
+}
+
+// Load retrieves the accounts matching the ID and the user, whichever are set, ordered by update time.
+func (r *AcmeAccountRepository) Load(_ context.Context, params repository.LoadParams) ([]*acmeaccount.Account, error) {
+	if params.ID == uuid.Nil && params.UserID == uuid.Nil {
+		return nil, errors.New("at least one of ID or UserID must be provided")
+	}
+	accounts := r.accounts.filter(func(a *acmeaccount.Account) bool {
+		return (params.ID == uuid.Nil || a.ID == params.ID) && (params.UserID == uuid.Nil || a.UserID == params.UserID)
+	})
+	slices.SortStableFunc(accounts, func(a, b *acmeaccount.Account) int { return a.UpdatedAt.Compare(b.UpdatedAt) })
+	return accounts, nil
+}
@@ -0,0 +1,66 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/adminapproval"
+	repositoryAdminapproval "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/adminapproval"
+	"github.com/google/uuid"
+)
+
+// AdminApprovalRepository keeps the approvals of destructive admin requests in memory.
+type AdminApprovalRepository struct {
+	// approvals holds the stored approvals.
+	approvals table[adminapproval.Approval]
+}
+
+// NewAdminApprovalRepository creates a new empty AdminApprovalRepository.
+func NewAdminApprovalRepository() *AdminApprovalRepository {
+	return &AdminApprovalRepository{}
+}
+
+// Save stores a new approval or records the decision or execution of an existing one, provided it is still
+// in the state it was loaded in.
+func (r *AdminApprovalRepository) Save(_ context.Context, params repositoryAdminapproval.SaveParams) error {
+	e := params.Entity
+	if params.From == "" {
+		r.approvals.add(e)
+		return nil
+	}
+
+	changed := r.approvals.update(
+		func(stored *adminapproval.Approval) bool { return stored.ID == e.ID && stored.Status == params.From },
+		func(stored *adminapproval.Approval) {
+			stored.Status = e.Status
+			stored.Approver = e.Approver
+			stored.DecidedAt = e.DecidedAt
+		},
+	)
+	if changed == 0 {
+		return repositoryAdminapproval.ErrApprovalConflict
+	}
+	return nil
+}
+
+// Load retrieves approvals matching the provided parameters, most recent first.
+// ErrApprovalNotFound is returned when an approval requested by ID does not exist.
+func (r *AdminApprovalRepository) Load(
+	_ context.Context,
+	params repositoryAdminapproval.LoadParams,
+) ([]*adminapproval.Approval, error) {
+	approvals := r.approvals.filter(func(a *adminapproval.Approval) bool {
+		if params.ID != uuid.Nil {
+			return a.ID == params.ID
+		}
+		return params.Status == "" || a.Status == params.Status
+	})
+	if params.ID != uuid.Nil && len(approvals) == 0 {
+		return nil, repositoryAdminapproval.ErrApprovalNotFound
+	}
+	slices.SortFunc(approvals, func(a, b *adminapproval.Approval) int {
+		return cmp.Or(b.CreatedAt.Compare(a.CreatedAt), compareIDs(a.ID, b.ID))
+	})
+	return approvals, nil
+}
@@ -0,0 +1,59 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/adminapproval"
+	repositoryAdminapproval "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/adminapproval"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdminApprovalRepository_SaveLoad(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	t0 := time.Date(2026, time.October, 1, 12, 0, 0, 0, time.UTC)
+	repo := NewAdminApprovalRepository()
+
+	earlier := &adminapproval.Approval{
+		ID: uuid.New(), Action: "recording.delete", Initiator: "alice", Status: adminapproval.StatusPending,
+		BodyDigest: []byte{1, 2, 3}, CreatedAt: t0, ExpiresAt: t0.Add(time.Hour),
+	}
+	later := &adminapproval.Approval{
+		ID: uuid.New(), Action: "legal_hold.release", Initiator: "bob", Status: adminapproval.StatusPending,
+		CreatedAt: t0.Add(time.Minute), ExpiresAt: t0.Add(time.Hour),
+	}
+	for _, a := range []*adminapproval.Approval{earlier, later} {
+		require.NoError(t, repo.Save(ctx, repositoryAdminapproval.SaveParams{Entity: a}))
+	}
+
+	approvals, err := repo.Load(ctx, repositoryAdminapproval.LoadParams{})
+	require.NoError(t, err)
+	require.Len(t, approvals, 2)
+	assert.Equal(t, later, approvals[0])
+	assert.Equal(t, earlier, approvals[1])
+
+	decided := *earlier
+	decided.Status, decided.Approver, decided.DecidedAt = adminapproval.StatusApproved, "bob", t0.Add(time.Minute)
+	saveParams := repositoryAdminapproval.SaveParams{Entity: &decided, From: adminapproval.StatusPending}
+	require.NoError(t, repo.Save(ctx, saveParams))
+	err = repo.Save(ctx, saveParams)
+	require.ErrorIs(t, err, repositoryAdminapproval.ErrApprovalConflict)
+
+	approvals, err = repo.Load(ctx, repositoryAdminapproval.LoadParams{ID: earlier.ID})
+	require.NoError(t, err)
+	require.Len(t, approvals, 1)
+	assert.Equal(t, &decided, approvals[0])
+
+	approvals, err = repo.Load(ctx, repositoryAdminapproval.LoadParams{Status: adminapproval.StatusPending})
+	require.NoError(t, err)
+	require.Len(t, approvals, 1)
+	assert.Equal(t, later.ID, approvals[0].ID)
+
+	_, err = repo.Load(ctx, repositoryAdminapproval.LoadParams{ID: uuid.New()})
+	assert.ErrorIs(t, err, repositoryAdminapproval.ErrApprovalNotFound)
+}
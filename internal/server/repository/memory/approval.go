@@ -0,0 +1,44 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/approval"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/approval"
+	"github.com/google/uuid"
+)
+
+// ApprovalRepository keeps access requests in memory.
+type ApprovalRepository struct {
+	// requests holds the stored requests.
+	requests table[approval.Request]
+}
+
+// NewApprovalRepository creates a new empty ApprovalRepository.
+func NewApprovalRepository() *ApprovalRepository {
+	return &ApprovalRepository{}
+}
+
+// Save creates or updates an access request.
+func (r *ApprovalRepository) Save(_ context.Context, params repository.SaveParams) error {
+	e := params.Entity
+	r.requests.put(e, func(q *approval.Request) bool { return q.ID == e.ID }, nil)
+	return nil
+}
+
+// Load retrieves access requests matching the provided parameters, most recent first.
+func (r *ApprovalRepository) Load(_ context.Context, params repository.LoadParams) ([]*approval.Request, error) {
+	requests := r.requests.filter(func(q *approval.Request) bool {
+		return (params.ID == uuid.Nil || q.ID == params.ID) &&
+			(params.UserID == uuid.Nil || q.UserID == params.UserID) &&
+			(params.ItemID == uuid.Nil || q.ItemID == params.ItemID) &&
+			(params.Status == "" || q.Status == params.Status) &&
+			(params.ActiveAt.IsZero() || q.ExpiresAt.After(params.ActiveAt))
+	})
+	slices.SortFunc(requests, func(a, b *approval.Request) int {
+		return cmp.Or(b.CreatedAt.Compare(a.CreatedAt), compareIDs(a.ID, b.ID))
+	})
+	return requests, nil
+}
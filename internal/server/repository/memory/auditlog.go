@@ -0,0 +1,49 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/audit"
+	repositoryAuditlog "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/auditlog"
+)
+
+// AuditLogRepository keeps the audit trail in memory.
+type AuditLogRepository struct {
+	// entries holds the stored audit entries.
+	entries table[audit.Entry]
+	// seq holds the sequence number of the last stored entry; it is guarded by the mutex of entries.
+	seq int64
+}
+
+// NewAuditLogRepository creates a new empty AuditLogRepository.
+func NewAuditLogRepository() *AuditLogRepository {
+	return &AuditLogRepository{}
+}
+
+// Append stores the entries in the order given and assigns their sequence numbers.
+func (r *AuditLogRepository) Append(_ context.Context, entries []audit.Entry) error {
+	r.entries.mu.Lock()
+	defer r.entries.mu.Unlock()
+
+	for _, e := range entries {
+		r.seq++
+		e.Seq = r.seq
+		r.entries.rows = append(r.entries.rows, clone(&e))
+	}
+	return nil
+}
+
+// Load retrieves a page of the entries that occurred in the period ordered by sequence number. Entries are
+// stored in sequence order, so insertion order is kept.
+func (r *AuditLogRepository) Load(
+	_ context.Context,
+	params repositoryAuditlog.LoadParams,
+) ([]*audit.Entry, error) {
+	entries := r.entries.filter(func(e *audit.Entry) bool {
+		return !e.OccurredAt.Before(params.From) && e.OccurredAt.Before(params.To) && e.Seq > params.After
+	})
+	if len(entries) > params.Limit {
+		entries = entries[:params.Limit]
+	}
+	return entries, nil
+}
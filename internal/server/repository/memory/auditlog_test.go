@@ -0,0 +1,48 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/audit"
+	repositoryAuditlog "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/auditlog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditLogRepository_AppendLoad(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	t0 := time.Date(2026, time.October, 1, 12, 0, 0, 0, time.UTC)
+	repo := NewAuditLogRepository()
+
+	require.NoError(t, repo.Append(ctx, []audit.Entry{
+		{Event: audit.Event{Type: audit.EventRowTampered, OccurredAt: t0.Add(-time.Hour)}},
+		{Event: audit.Event{Type: audit.EventLegalHoldPlaced, OccurredAt: t0}},
+	}))
+	require.NoError(t, repo.Append(ctx, []audit.Entry{
+		{Event: audit.Event{Type: audit.EventLegalHoldReleased, OccurredAt: t0.Add(time.Minute)}},
+		{Event: audit.Event{Type: audit.EventRowTampered, OccurredAt: t0.Add(time.Hour)}},
+	}))
+
+	params := repositoryAuditlog.LoadParams{From: t0, To: t0.Add(time.Hour), Limit: 1}
+	page, err := repo.Load(ctx, params)
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	assert.Equal(t, audit.EventLegalHoldPlaced, page[0].Type)
+	assert.Equal(t, int64(2), page[0].Seq)
+
+	params.After = page[0].Seq
+	page, err = repo.Load(ctx, params)
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	assert.Equal(t, audit.EventLegalHoldReleased, page[0].Type)
+	assert.Equal(t, int64(3), page[0].Seq)
+
+	params.After = page[0].Seq
+	page, err = repo.Load(ctx, params)
+	require.NoError(t, err)
+	assert.Empty(t, page)
+}
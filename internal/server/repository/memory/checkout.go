@@ -0,0 +1,83 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/checkout"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/checkout"
+	"github.com/google/uuid"
+)
+
+// CheckoutRepository keeps credential shares and their check-outs in memory.
+type CheckoutRepository struct {
+	// groups resolves the members of the groups credentials are shared with.
+	groups *GroupRepository
+	// shares holds the stored shares.
+	shares table[checkout.Share]
+}
+
+// NewCheckoutRepository creates a new empty CheckoutRepository resolving share members with the group repository.
+func NewCheckoutRepository(groups *GroupRepository) *CheckoutRepository {
+	return &CheckoutRepository{groups: groups}
+}
+
+// Save shares a credential or updates the group and rotation setting of an existing share.
+// The check-out of an existing share is kept.
+func (r *CheckoutRepository) Save(_ context.Context, params repository.SaveParams) error {
+	e := params.Entity
+	same := func(s *checkout.Share) bool { return s.CredentialID == e.CredentialID }
+	if r.shares.update(same, func(s *checkout.Share) {
+		s.GroupID, s.RotateOnCheckIn = e.GroupID, e.RotateOnCheckIn
+	}) == 0 {
+		share := *e
+		share.HolderID, share.CheckedOutAt, share.ExpiresAt = uuid.Nil, time.Time{}, time.Time{}
+		r.shares.add(&share)
+	}
+	return nil
+}
+
+// Load retrieves the shares matching the provided parameters ordered by creation time.
+func (r *CheckoutRepository) Load(_ context.Context, params repository.LoadParams) ([]*checkout.Share, error) {
+	shares := r.shares.filter(func(s *checkout.Share) bool {
+		return (params.CredentialID == uuid.Nil || s.CredentialID == params.CredentialID) &&
+			(params.OwnerID == uuid.Nil || s.OwnerID == params.OwnerID) &&
+			(params.ExpiredAt.IsZero() || (s.HolderID != uuid.Nil && !s.ExpiresAt.After(params.ExpiredAt)))
+	})
+	if params.MemberID != uuid.Nil {
+		shares = slices.DeleteFunc(shares, func(s *checkout.Share) bool {
+			return !r.groups.isMember(s.GroupID, params.MemberID)
+		})
+	}
+	slices.SortFunc(shares, func(a, b *checkout.Share) int {
+		return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), compareIDs(a.CredentialID, b.CredentialID))
+	})
+	return shares, nil
+}
+
+// SwapLease stores the check-out of the share only while the check-out loaded with it is still current,
+// so concurrent check-outs cannot both succeed. Returns ErrLeaseChanged when the check-out changed meanwhile.
+func (r *CheckoutRepository) SwapLease(_ context.Context, params repository.SwapLeaseParams) error {
+	e := params.Entity
+	if r.shares.update(func(s *checkout.Share) bool {
+		return s.CredentialID == e.CredentialID && s.HolderID == params.HolderID &&
+			s.CheckedOutAt.Equal(params.CheckedOutAt)
+	}, func(s *checkout.Share) {
+		s.HolderID, s.CheckedOutAt, s.ExpiresAt = e.HolderID, e.CheckedOutAt, e.ExpiresAt
+	}) == 0 {
+		return repository.ErrLeaseChanged
+	}
+	return nil
+}
+
+// Delete removes the share of the credential of the owner.
+func (r *CheckoutRepository) Delete(_ context.Context, params repository.DeleteParams) error {
+	if r.shares.remove(func(s *checkout.Share) bool {
+		return s.CredentialID == params.CredentialID && s.OwnerID == params.OwnerID
+	}) == 0 {
+		return repository.ErrShareNotFound
+	}
+	return nil
+}
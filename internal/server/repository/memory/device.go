@@ -0,0 +1,44 @@
+package memory
+
+import (
+	"context"
+	"slices"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/device"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/device"
+	"github.com/google/uuid"
+)
+
+// DeviceRepository keeps the devices users sign in from in memory.
+type DeviceRepository struct {
+	// devices holds the stored devices.
+	devices table[device.Device]
+}
+
+// NewDeviceRepository creates a new empty DeviceRepository.
+func NewDeviceRepository() *DeviceRepository {
+	return &DeviceRepository{}
+}
+
+// Save creates or updates a device.
+func (r *DeviceRepository) Save(_ context.Context, params repository.SaveParams) error {
+	e := params.Entity
+	r.devices.put(e, func(d *device.Device) bool { return d.ID == e.ID }, nil)
+	return nil
+}
+
+// Load retrieves the device with the ID or, when no ID is set, the devices of the user ordered by creation time.
+// Loading by ID returns ErrDeviceNotFound when the device does not exist.
+func (r *DeviceRepository) Load(_ context.Context, params repository.LoadParams) ([]*device.Device, error) {
+	devices := r.devices.filter(func(d *device.Device) bool {
+		if params.ID != uuid.Nil {
+			return d.ID == params.ID
+		}
+		return d.UserID == params.UserID
+	})
+	if params.ID != uuid.Nil && len(devices) == 0 {
+		return nil, repository.ErrDeviceNotFound
+	}
+	slices.SortFunc(devices, func(a, b *device.Device) int { return compareCreated(a.CreatedAt, b.CreatedAt, a.ID, b.ID) })
+	return devices, nil
+}
@@ -0,0 +1,52 @@
+package memory
+
+import (
+	"context"
+	"slices"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/devicekey"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/devicekey"
+	"github.com/google/uuid"
+)
+
+// DeviceKeyRepository keeps device keys in memory. Public keys are stored as is, without sealing.
+type DeviceKeyRepository struct {
+	// keys holds the stored device keys.
+	keys table[devicekey.DeviceKey]
+}
+
+// NewDeviceKeyRepository creates a new empty DeviceKeyRepository.
+func NewDeviceKeyRepository() *DeviceKeyRepository {
+	return &DeviceKeyRepository{}
+}
+
+// Save stores a new device key.
+func (r *DeviceKeyRepository) Save(_ context.Context, params repository.SaveParams) error {
+	r.keys.add(params.Entity)
+	return nil
+}
+
+// Load retrieves the device keys of the user, or only the one with the ID when set, ordered by registration time.
+// Loading by ID returns ErrDeviceKeyNotFound when the key does not exist.
+func (r *DeviceKeyRepository) Load(_ context.Context, params repository.LoadParams) ([]*devicekey.DeviceKey, error) {
+	keys := r.keys.filter(func(k *devicekey.DeviceKey) bool {
+		return k.UserID == params.UserID && (params.ID == uuid.Nil || k.ID == params.ID)
+	})
+	if params.ID != uuid.Nil && len(keys) == 0 {
+		return nil, repository.ErrDeviceKeyNotFound
+	}
+	slices.SortFunc(keys, func(a, b *devicekey.DeviceKey) int {
+		return compareCreated(a.CreatedAt, b.CreatedAt, a.ID, b.ID)
+	})
+	return keys, nil
+}
+
+// Delete removes the device key with the ID of the user.
+func (r *DeviceKeyRepository) Delete(_ context.Context, params repository.DeleteParams) error {
+	if r.keys.remove(func(k *devicekey.DeviceKey) bool {
+		return k.ID == params.ID && k.UserID == params.UserID
+	}) == 0 {
+		return repository.ErrDeviceKeyNotFound
+	}
+	return nil
+}
@@ -0,0 +1,6 @@
+// Package memory provides in-memory repositories for the AegisVaultKeeper mock server mode.
+//
+// The repositories implement the same contracts as their PostgreSQL counterparts and reuse their parameter
+// types and errors, so the application layer runs against them unchanged. Entities are kept unencrypted in
+// process memory and are lost on restart; the package is meant for client development, not for real data.
+package memory
@@ -0,0 +1,47 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/feature"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/feature"
+)
+
+// FeatureRepository keeps feature flags in memory.
+type FeatureRepository struct {
+	// flags holds the stored flags.
+	flags table[feature.Flag]
+}
+
+// NewFeatureRepository creates a new empty FeatureRepository.
+func NewFeatureRepository() *FeatureRepository {
+	return &FeatureRepository{}
+}
+
+// Save creates or updates the flag with the key for the user, or the global flag when no user is set.
+func (r *FeatureRepository) Save(_ context.Context, params repository.SaveParams) error {
+	e := params.Entity
+	r.flags.put(e, func(f *feature.Flag) bool { return f.Key == e.Key && f.UserID == e.UserID }, nil)
+	return nil
+}
+
+// Load retrieves the global flags, the flags of the user, or both, ordered by key with global flags first;
+// all flags when neither is requested.
+func (r *FeatureRepository) Load(_ context.Context, params repository.LoadParams) ([]*feature.Flag, error) {
+	flags := r.flags.filter(func(f *feature.Flag) bool { return ownedBy(f.UserID, params.UserID, params.Global) })
+	slices.SortFunc(flags, func(a, b *feature.Flag) int {
+		return cmp.Or(strings.Compare(a.Key, b.Key), compareIDs(a.UserID, b.UserID))
+	})
+	return flags, nil
+}
+
+// Delete removes the flag with the key for the user, or the global flag when no user is set.
+func (r *FeatureRepository) Delete(_ context.Context, params repository.DeleteParams) error {
+	if r.flags.remove(func(f *feature.Flag) bool { return f.Key == params.Key && f.UserID == params.UserID }) == 0 {
+		return repository.ErrFlagNotFound
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/filedata"
+	repositoryFiledata "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/filedata"
+	repositoryFilefolder "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/filefolder"
+	"github.com/google/uuid"
+)
+
+// FileDataRepository keeps file metadata in memory; the file content is kept by the file storage.
+type FileDataRepository struct {
+	// files holds the stored file metadata.
+	files table[filedata.FileData]
+}
+
+// NewFileDataRepository creates a new empty FileDataRepository.
+func NewFileDataRepository() *FileDataRepository {
+	return &FileDataRepository{}
+}
+
+// Save creates or updates the metadata of a file.
+func (r *FileDataRepository) Save(_ context.Context, params repositoryFiledata.SaveParams) error {
+	e := params.Entity
+	r.files.put(e, func(f *filedata.FileData) bool { return f.ID == e.ID }, nil)
+	return nil
+}
+
+// Load retrieves the metadata of the files matching the ID and the user, whichever are set.
+func (r *FileDataRepository) Load(
+	_ context.Context,
+	params repositoryFiledata.LoadParams,
+) ([]*filedata.FileData, error) {
+	if params.ID == uuid.Nil && params.UserID == uuid.Nil {
+		return nil, errors.New("at least one of ID or UserID must be provided")
+	}
+	files := r.files.filter(func(f *filedata.FileData) bool {
+		return (params.ID == uuid.Nil || f.ID == params.ID) &&
+			(params.UserID == uuid.Nil || f.UserID == params.UserID) &&
+			(params.After == uuid.Nil || compareIDs(f.ID, params.After) > 0)
+	})
+	if params.Limit > 0 {
+		slices.SortFunc(files, func(a, b *filedata.FileData) int { return compareIDs(a.ID, b.ID) })
+		files = files[:min(params.Limit, len(files))]
+	}
+	return files, nil
+}
+
+// FolderRepository keeps file folders in memory.
+type FolderRepository struct {
+	// folders holds the stored folders.
+	folders table[filedata.Folder]
+}
+
+// NewFolderRepository creates a new empty FolderRepository.
+func NewFolderRepository() *FolderRepository {
+	return &FolderRepository{}
+}
+
+// Save creates or updates a folder.
+func (r *FolderRepository) Save(_ context.Context, params repositoryFilefolder.SaveParams) error {
+	e := params.Entity
+	r.folders.put(e, func(f *filedata.Folder) bool { return f.ID == e.ID }, nil)
+	return nil
+}
+
+// Load retrieves the folders matching the ID and the user, whichever are set.
+func (r *FolderRepository) Load(_ context.Context, params repositoryFilefolder.LoadParams) ([]*filedata.Folder, error) {
+	if params.ID == uuid.Nil && params.UserID == uuid.Nil {
+		return nil, errors.New("at least one of ID or UserID must be provided")
+	}
+	return r.folders.filter(func(f *filedata.Folder) bool {
+		return (params.ID == uuid.Nil || f.ID == params.ID) && (params.UserID == uuid.Nil || f.UserID == params.UserID)
+	}), nil
+}
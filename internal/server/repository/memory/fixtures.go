@@ -0,0 +1,289 @@
+package memory
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/auth"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/bankcard"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/credential"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/filedata"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/itempath"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/itemtag"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/note"
+	repositoryAuth "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/auth"
+	repositoryBankcard "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/bankcard"
+	repositoryCredential "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/credential"
+	repositoryFiledata "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/filedata"
+	repositoryFilefolder "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/filefolder"
+	repositoryFilestorage "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/filestorage"
+	repositoryItempath "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/itempath"
+	repositoryItemtag "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/itemtag"
+	repositoryNote "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/note"
+	"github.com/google/uuid"
+)
+
+// Credentials of the fixture user every mock server starts with.
+const (
+	// FixtureLogin is the login of the fixture user.
+	FixtureLogin = "demo-user"
+	// FixturePassword is the password of the fixture user.
+	FixturePassword = "demo-password"
+)
+
+// FixtureUserID is the identifier of the fixture user.
+var FixtureUserID = fixtureID(1)
+
+// fixtureTime is the moment the fixture items were last updated.
+var fixtureTime = time.Date(2025, time.January, 15, 9, 0, 0, 0, time.UTC)
+
+// FileStore stores the content of the fixture files.
+type FileStore interface {
+	// NewFileKey generates a file key wrapped with the key of the file owner.
+	NewFileKey(ctx context.Context, params repositoryFilestorage.FileKeyParams) ([]byte, error)
+	// Save stores the content of a file.
+	Save(ctx context.Context, params repositoryFilestorage.SaveParams) error
+}
+
+// Fixtures seeds the deterministic fixture data of the mock server: the fixture user and a vault with
+// credentials, bank cards, notes and a file, organized with folders, item paths and tags. Identifiers,
+// contents and timestamps are the same on every start, so clients can rely on them.
+type Fixtures struct {
+	// Users stores the fixture user.
+	Users *UserRepository
+	// Credentials stores the fixture credentials.
+	Credentials *CredentialRepository
+	// BankCards stores the fixture bank cards.
+	BankCards *BankCardRepository
+	// Notes stores the fixture notes.
+	Notes *NoteRepository
+	// Files stores the metadata of the fixture files.
+	Files *FileDataRepository
+	// Folders stores the fixture folders.
+	Folders *FolderRepository
+	// Tags stores the tags of the fixture items.
+	Tags *ItemTagRepository
+	// Paths stores the paths of the fixture items.
+	Paths *ItemPathRepository
+	// FileStore stores the content of the fixture files.
+	FileStore FileStore
+}
+
+// fixtureItem describes the organization of a fixture item.
+type fixtureItem struct {
+	// path contains the item path; empty for items without a path.
+	path string
+	// tags lists the item tags.
+	tags []string
+	// id identifies the item.
+	id uuid.UUID
+}
+
+// Seed stores the fixture data, hashing the fixture password with the hasher.
+func (f *Fixtures) Seed(ctx context.Context, hasher auth.PasswordHasher) error {
+	if err := f.seedUser(ctx, hasher); err != nil {
+		return fmt.Errorf("failed to seed fixture user: %w", err)
+	}
+
+	items, err := f.seedVault(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to seed fixture vault: %w", err)
+	}
+	files, err := f.seedFiles(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to seed fixture files: %w", err)
+	}
+
+	for i, item := range append(items, files...) {
+		// at spreads the organization updates of the items over distinct moments.
+		at := fixtureTime.Add(time.Duration(i) * time.Minute)
+		if item.path != "" {
+			p, err := itempath.NewItemPath(itempath.NewItemPathParams{
+				Path: item.path, ItemID: item.id, UserID: FixtureUserID,
+			})
+			if err != nil {
+				return fmt.Errorf("invalid fixture item path: %w", err)
+			}
+			p.UpdatedAt = at
+			if err := f.Paths.Save(ctx, repositoryItempath.SaveParams{Entity: p}); err != nil {
+				return fmt.Errorf("failed to seed fixture item path: %w", err)
+			}
+		}
+		t, err := itemtag.NewItemTags(itemtag.NewItemTagsParams{Tags: item.tags, ItemID: item.id, UserID: FixtureUserID})
+		if err != nil {
+			return fmt.Errorf("invalid fixture item tags: %w", err)
+		}
+		t.UpdatedAt = at
+		if err := f.Tags.Save(ctx, repositoryItemtag.SaveParams{Entity: t}); err != nil {
+			return fmt.Errorf("failed to seed fixture item tags: %w", err)
+		}
+	}
+	return nil
+}
+
+// seedUser stores the fixture user with a crypto key derived from its identifier.
+func (f *Fixtures) seedUser(ctx context.Context, hasher auth.PasswordHasher) error {
+	u, err := auth.NewUser(auth.NewUserParams{Login: FixtureLogin, Password: FixturePassword}, hasher, fixtureKeyGen{})
+	if err != nil {
+		return err
+	}
+	u.ID = FixtureUserID
+	if err := f.Users.Save(ctx, repositoryAuth.SaveParams{Entity: u}); err != nil {
+		return fmt.Errorf("failed to save user: %w", err)
+	}
+	return nil
+}
+
+// seedVault stores the fixture credentials, bank cards and notes and returns their organization.
+func (f *Fixtures) seedVault(ctx context.Context) ([]fixtureItem, error) {
+	// items collects the organization of the stored items.
+	var items []fixtureItem
+
+	for i, fc := range []struct {
+		params credential.NewCredentialParams
+		item   fixtureItem
+	}{
+		{
+			params: credential.NewCredentialParams{
+				Login: "demo-user", Password: "correct-horse-battery-staple", Description: "GitHub",
+				URIs: []credential.URI{{Value: "https://github.com"}},
+			},
+			item: fixtureItem{path: "work/github", tags: []string{"work"}},
+		},
+		{
+			params: credential.NewCredentialParams{
+				Login: "demo@example.com", Password: "mail-password-42", Description: "Personal mail",
+			},
+			item: fixtureItem{path: "personal/mail", tags: []string{"personal"}},
+		},
+	} {
+		fc.params.UserID = FixtureUserID
+		c, err := credential.NewCredential(fc.params)
+		if err != nil {
+			return nil, fmt.Errorf("invalid fixture credential: %w", err)
+		}
+		c.ID, c.UpdatedAt = fixtureID(10+i), fixtureTime
+		if err := f.Credentials.Save(ctx, repositoryCredential.SaveParams{Entity: c}); err != nil {
+			return nil, fmt.Errorf("failed to save credential: %w", err)
+		}
+		fc.item.id = c.ID
+		items = append(items, fc.item)
+	}
+
+	for i, params := range []bankcard.NewBankCardParams{
+		{
+			CardNumber: "4111111111111111", CardHolder: "DEMO USER", ExpiryMonth: "12", ExpiryYear: "2031",
+			CVV: "123", Description: "Visa debit",
+		},
+		{
+			CardNumber: "5555555555554444", CardHolder: "DEMO USER", ExpiryMonth: "06", ExpiryYear: "2033",
+			CVV: "456", Description: "Mastercard credit",
+		},
+	} {
+		params.UserID = FixtureUserID
+		c, err := bankcard.NewBankCard(&params)
+		if err != nil {
+			return nil, fmt.Errorf("invalid fixture bank card: %w", err)
+		}
+		c.ID, c.UpdatedAt = fixtureID(20+i), fixtureTime
+		if err := f.BankCards.Save(ctx, repositoryBankcard.SaveParams{Entity: c}); err != nil {
+			return nil, fmt.Errorf("failed to save bank card: %w", err)
+		}
+		items = append(items, fixtureItem{id: c.ID, tags: []string{"finance"}})
+	}
+
+	for i, fn := range []struct {
+		params note.NewNoteParams
+		item   fixtureItem
+	}{
+		{
+			params: note.NewNoteParams{Note: "Network: aegis-home\nPassword: welcome-home-2025", Description: "Home Wi-Fi"},
+			item:   fixtureItem{path: "personal/wifi", tags: []string{"personal"}},
+		},
+		{
+			params: note.NewNoteParams{Note: "1234-5678\n9012-3456\n7890-1234", Description: "GitHub recovery codes"},
+			item:   fixtureItem{path: "work/github-recovery", tags: []string{"work"}},
+		},
+	} {
+		fn.params.UserID = FixtureUserID
+		n, err := note.NewNote(fn.params)
+		if err != nil {
+			return nil, fmt.Errorf("invalid fixture note: %w", err)
+		}
+		n.ID, n.UpdatedAt = fixtureID(30+i), fixtureTime
+		if err := f.Notes.Save(ctx, repositoryNote.SaveParams{Entity: n}); err != nil {
+			return nil, fmt.Errorf("failed to save note: %w", err)
+		}
+		fn.item.id = n.ID
+		items = append(items, fn.item)
+	}
+	return items, nil
+}
+
+// seedFiles stores the fixture folders and files and returns the organization of the files.
+func (f *Fixtures) seedFiles(ctx context.Context) ([]fixtureItem, error) {
+	for i, path := range []string{"documents", "documents/taxes"} {
+		folder, err := filedata.NewFolder(filedata.NewFolderParams{Path: path, UserID: FixtureUserID})
+		if err != nil {
+			return nil, fmt.Errorf("invalid fixture folder: %w", err)
+		}
+		folder.ID, folder.UpdatedAt = fixtureID(40+i), fixtureTime
+		if err := f.Folders.Save(ctx, repositoryFilefolder.SaveParams{Entity: folder}); err != nil {
+			return nil, fmt.Errorf("failed to save folder: %w", err)
+		}
+	}
+
+	content := []byte("Tax return 2024\nIncome: 85000\nTax paid: 17000\n")
+	sum := sha256.Sum256(content)
+	fd, err := filedata.NewFile(filedata.NewFileDataParams{
+		StorageKey:  "tax-return-2024.txt",
+		HashSum:     hex.EncodeToString(sum[:]),
+		Description: "Tax return for 2024",
+		Folder:      "documents/taxes",
+		UserID:      FixtureUserID,
+		Size:        int64(len(content)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid fixture file: %w", err)
+	}
+	fd.ID, fd.UpdatedAt = fixtureID(50), fixtureTime
+	if fd.FileKey, err = f.FileStore.NewFileKey(ctx, repositoryFilestorage.FileKeyParams{
+		UserID: fd.UserID, FileID: fd.ID,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to create file key: %w", err)
+	}
+	if err := f.FileStore.Save(ctx, repositoryFilestorage.SaveParams{
+		StorageKey: string(fd.StorageKey),
+		Data:       content,
+		FileKey:    fd.FileKey,
+		UserID:     fd.UserID,
+		FileID:     fd.ID,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to save file content: %w", err)
+	}
+	if err := f.Files.Save(ctx, repositoryFiledata.SaveParams{Entity: fd}); err != nil {
+		return nil, fmt.Errorf("failed to save file metadata: %w", err)
+	}
+	return []fixtureItem{{id: fd.ID, tags: []string{"finance"}}}, nil
+}
+
+// fixtureID returns the fixed identifier with the sequence number n.
+func fixtureID(n int) uuid.UUID {
+	return uuid.MustParse(fmt.Sprintf("00000000-0000-4000-8000-%012d", n))
+}
+
+// fixtureKeyGen generates the crypto key of the fixture user deterministically.
+type fixtureKeyGen struct{}
+
+// CryptoKeyGenerate returns a fixed key of the requested size.
+func (fixtureKeyGen) CryptoKeyGenerate(size int) ([]byte, error) {
+	sum := sha256.Sum256([]byte("aegis-vault-keeper mock fixture key"))
+	if size > len(sum) {
+		return nil, errors.New("fixture key size exceeds 32 bytes")
+	}
+	return sum[:size], nil
+}
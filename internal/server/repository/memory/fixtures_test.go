@@ -0,0 +1,105 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	repositoryAuth "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/auth"
+	repositoryBankcard "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/bankcard"
+	repositoryCredential "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/credential"
+	repositoryFilestorage "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/filestorage"
+	repositoryItemtag "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/itemtag"
+	repositoryNote "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/note"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// plainHasher is a password hasher that keeps passwords as is.
+type plainHasher struct{}
+
+// PasswordHash returns the password unchanged.
+func (plainHasher) PasswordHash(password string) (string, error) {
+	return password, nil
+}
+
+// fileStoreStub records the stored file contents.
+type fileStoreStub struct {
+	// saved maps the storage keys to the stored contents.
+	saved map[string][]byte
+}
+
+// NewFileKey returns a fixed file key.
+func (s *fileStoreStub) NewFileKey(context.Context, repositoryFilestorage.FileKeyParams) ([]byte, error) {
+	return []byte("file-key"), nil
+}
+
+// Save records the file content.
+func (s *fileStoreStub) Save(_ context.Context, params repositoryFilestorage.SaveParams) error {
+	s.saved[params.StorageKey] = params.Data
+	return nil
+}
+
+// newTestFixtures returns fixtures backed by new empty repositories.
+func newTestFixtures() *Fixtures {
+	return &Fixtures{
+		Users:       NewUserRepository(),
+		Credentials: NewCredentialRepository(),
+		BankCards:   NewBankCardRepository(),
+		Notes:       NewNoteRepository(),
+		Files:       NewFileDataRepository(),
+		Folders:     NewFolderRepository(),
+		Tags:        NewItemTagRepository(),
+		Paths:       NewItemPathRepository(),
+		FileStore:   &fileStoreStub{saved: make(map[string][]byte)},
+	}
+}
+
+func TestFixtures_Seed(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	f := newTestFixtures()
+	require.NoError(t, f.Seed(ctx, plainHasher{}))
+
+	user, err := f.Users.Load(ctx, repositoryAuth.LoadParams{Login: FixtureLogin})
+	require.NoError(t, err)
+	assert.Equal(t, FixtureUserID, user.ID)
+	assert.Equal(t, FixturePassword, user.PasswordHash)
+	assert.Len(t, user.CryptoKey, 32)
+
+	credentials, err := f.Credentials.Load(ctx, repositoryCredential.LoadParams{UserID: FixtureUserID})
+	require.NoError(t, err)
+	assert.Len(t, credentials, 2)
+	cards, err := f.BankCards.Load(ctx, repositoryBankcard.LoadParams{UserID: FixtureUserID})
+	require.NoError(t, err)
+	assert.Len(t, cards, 2)
+	notes, err := f.Notes.Load(ctx, repositoryNote.LoadParams{UserID: FixtureUserID})
+	require.NoError(t, err)
+	assert.Len(t, notes, 2)
+	tags, err := f.Tags.Load(ctx, repositoryItemtag.LoadParams{UserID: FixtureUserID})
+	require.NoError(t, err)
+	assert.Len(t, tags, 7)
+	assert.Contains(t, f.FileStore.(*fileStoreStub).saved, "tax-return-2024.txt")
+}
+
+func TestFixtures_SeedIsDeterministic(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	first, second := newTestFixtures(), newTestFixtures()
+	require.NoError(t, first.Seed(ctx, plainHasher{}))
+	require.NoError(t, second.Seed(ctx, plainHasher{}))
+
+	params := repositoryNote.LoadParams{UserID: FixtureUserID}
+	firstNotes, err := first.Notes.Load(ctx, params)
+	require.NoError(t, err)
+	secondNotes, err := second.Notes.Load(ctx, params)
+	require.NoError(t, err)
+	assert.Equal(t, firstNotes, secondNotes)
+
+	firstUser, err := first.Users.Load(ctx, repositoryAuth.LoadParams{ID: FixtureUserID})
+	require.NoError(t, err)
+	secondUser, err := second.Users.Load(ctx, repositoryAuth.LoadParams{ID: FixtureUserID})
+	require.NoError(t, err)
+	assert.Equal(t, firstUser.CryptoKey, secondUser.CryptoKey)
+}
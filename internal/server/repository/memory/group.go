@@ -0,0 +1,157 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/auth"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/group"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/group"
+	"github.com/google/uuid"
+)
+
+// GroupRepository keeps user groups and their members in memory.
+type GroupRepository struct {
+	// users resolves the members of the groups.
+	users *UserRepository
+	// groups holds the stored groups; Members holds the identifiers of all member users.
+	groups table[group.Group]
+}
+
+// NewGroupRepository creates a new empty GroupRepository resolving members with the user repository.
+func NewGroupRepository(users *UserRepository) *GroupRepository {
+	return &GroupRepository{users: users}
+}
+
+// Save creates the group or updates its display name and external ID; display names are unique.
+// Members are changed with ReplaceMembers, AddMembers and RemoveMembers.
+func (r *GroupRepository) Save(_ context.Context, params repository.SaveParams) error {
+	e := clone(params.Entity)
+
+	r.groups.mu.Lock()
+	defer r.groups.mu.Unlock()
+
+	// existing holds the stored group with the identifier of the saved one; nil for new groups.
+	var existing *group.Group
+	for _, stored := range r.groups.rows {
+		switch {
+		case stored.ID == e.ID:
+			existing = stored
+		case stored.DisplayName == e.DisplayName:
+			return repository.ErrGroupAlreadyExists
+		}
+	}
+	if existing == nil {
+		e.Members = nil
+		r.groups.rows = append(r.groups.rows, e)
+		return nil
+	}
+	existing.DisplayName, existing.ExternalID, existing.UpdatedAt = e.DisplayName, e.ExternalID, e.UpdatedAt
+	return nil
+}
+
+// Load retrieves a page of groups with their members ordered by display name, and the number of groups
+// matching the parameters. Members deprovisioned from the directory are omitted.
+// Loading by ID returns ErrGroupNotFound when the group does not exist.
+func (r *GroupRepository) Load(_ context.Context, params repository.LoadParams) ([]*group.Group, int, error) {
+	groups := r.groups.filter(func(g *group.Group) bool {
+		return (params.ID == uuid.Nil || g.ID == params.ID) &&
+			(params.DisplayName == "" || g.DisplayName == params.DisplayName) &&
+			(params.ExternalID == "" || g.ExternalID == params.ExternalID)
+	})
+	total := len(groups)
+	if params.ID != uuid.Nil {
+		if total == 0 {
+			return nil, 0, repository.ErrGroupNotFound
+		}
+	} else {
+		if total == 0 || params.Limit <= 0 {
+			return nil, total, nil
+		}
+		slices.SortFunc(groups, func(a, b *group.Group) int {
+			return cmp.Or(strings.Compare(a.DisplayName, b.DisplayName), compareIDs(a.ID, b.ID))
+		})
+		groups = page(groups, params.Offset, params.Limit)
+	}
+
+	// members maps the identifiers of the provisioned users to the users.
+	members := make(map[uuid.UUID]*auth.User)
+	for _, u := range r.users.users.filter(func(u *auth.User) bool { return u.DeprovisionedAt.IsZero() }) {
+		members[u.ID] = u
+	}
+	for _, g := range groups {
+		g.Members = slices.DeleteFunc(g.Members, func(id uuid.UUID) bool { return members[id] == nil })
+		slices.SortFunc(g.Members, func(a, b uuid.UUID) int { return strings.Compare(members[a].Login, members[b].Login) })
+		if len(g.Members) == 0 {
+			g.Members = nil
+		}
+	}
+	return groups, total, nil
+}
+
+// ReplaceMembers sets the members of the group to exactly the listed users.
+func (r *GroupRepository) ReplaceMembers(_ context.Context, params repository.MembersParams) error {
+	if err := r.requireUsers(params.UserIDs); err != nil {
+		return err
+	}
+	r.groups.update(func(g *group.Group) bool { return g.ID == params.GroupID }, func(g *group.Group) {
+		g.Members = nil
+		addMembers(g, params.UserIDs)
+	})
+	return nil
+}
+
+// AddMembers adds the listed users to the group; existing members are kept.
+func (r *GroupRepository) AddMembers(_ context.Context, params repository.MembersParams) error {
+	if err := r.requireUsers(params.UserIDs); err != nil {
+		return err
+	}
+	r.groups.update(func(g *group.Group) bool { return g.ID == params.GroupID }, func(g *group.Group) {
+		addMembers(g, params.UserIDs)
+	})
+	return nil
+}
+
+// RemoveMembers removes the listed users from the group.
+func (r *GroupRepository) RemoveMembers(_ context.Context, params repository.MembersParams) error {
+	r.groups.update(func(g *group.Group) bool { return g.ID == params.GroupID }, func(g *group.Group) {
+		g.Members = slices.DeleteFunc(g.Members, func(id uuid.UUID) bool { return slices.Contains(params.UserIDs, id) })
+	})
+	return nil
+}
+
+// Delete removes the group with the specified ID together with its memberships.
+func (r *GroupRepository) Delete(_ context.Context, params repository.DeleteParams) error {
+	if r.groups.remove(func(g *group.Group) bool { return g.ID == params.ID }) == 0 {
+		return repository.ErrGroupNotFound
+	}
+	return nil
+}
+
+// isMember reports whether the user is a member of the group.
+func (r *GroupRepository) isMember(groupID, userID uuid.UUID) bool {
+	return len(r.groups.filter(func(g *group.Group) bool {
+		return g.ID == groupID && slices.Contains(g.Members, userID)
+	})) > 0
+}
+
+// requireUsers returns ErrGroupMemberNotFound when any of the users does not exist.
+func (r *GroupRepository) requireUsers(ids []uuid.UUID) error {
+	for _, id := range ids {
+		if len(r.users.users.filter(func(u *auth.User) bool { return u.ID == id })) == 0 {
+			return repository.ErrGroupMemberNotFound
+		}
+	}
+	return nil
+}
+
+// addMembers adds the users that are not members yet to the group.
+func addMembers(g *group.Group, ids []uuid.UUID) {
+	for _, id := range ids {
+		if !slices.Contains(g.Members, id) {
+			g.Members = append(g.Members, id)
+		}
+	}
+}
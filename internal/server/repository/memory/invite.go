@@ -0,0 +1,63 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"slices"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/invite"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/invite"
+	"github.com/google/uuid"
+)
+
+// InviteRepository keeps registration invites in memory.
+type InviteRepository struct {
+	// invites holds the stored invites.
+	invites table[invite.Invite]
+}
+
+// NewInviteRepository creates a new empty InviteRepository.
+func NewInviteRepository() *InviteRepository {
+	return &InviteRepository{}
+}
+
+// Save creates an invite or updates its revocation.
+func (r *InviteRepository) Save(_ context.Context, params repository.SaveParams) error {
+	e := params.Entity
+	if r.invites.update(func(i *invite.Invite) bool { return i.ID == e.ID }, func(i *invite.Invite) {
+		i.RevokedAt = e.RevokedAt
+	}) == 0 {
+		r.invites.add(e)
+	}
+	return nil
+}
+
+// Load retrieves invites matching the provided parameters, ordered by creation time.
+// Loading by ID or code digest returns ErrInviteNotFound when no invite matches.
+func (r *InviteRepository) Load(_ context.Context, params repository.LoadParams) ([]*invite.Invite, error) {
+	invites := r.invites.filter(func(i *invite.Invite) bool {
+		return (params.ID == uuid.Nil || i.ID == params.ID) &&
+			(params.CodeHash == nil || bytes.Equal(i.CodeHash, params.CodeHash)) &&
+			(params.CreatedBy == uuid.Nil || i.CreatedBy == params.CreatedBy) &&
+			(params.RedeemedBy == uuid.Nil || i.RedeemedBy == params.RedeemedBy)
+	})
+	if (params.ID != uuid.Nil || params.CodeHash != nil) && len(invites) == 0 {
+		return nil, repository.ErrInviteNotFound
+	}
+	slices.SortFunc(invites, func(a, b *invite.Invite) int {
+		return compareCreated(a.CreatedAt, b.CreatedAt, a.ID, b.ID)
+	})
+	return invites, nil
+}
+
+// Redeem marks the pending invite as redeemed by the user, returning ErrInviteNotFound when it is not pending.
+func (r *InviteRepository) Redeem(_ context.Context, params repository.RedeemParams) error {
+	if r.invites.update(func(i *invite.Invite) bool {
+		return i.ID == params.ID && i.Status(params.At) == invite.StatusPending
+	}, func(i *invite.Invite) {
+		i.RedeemedBy, i.RedeemedAt = params.UserID, params.At
+	}) == 0 {
+		return repository.ErrInviteNotFound
+	}
+	return nil
+}
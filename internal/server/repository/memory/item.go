@@ -0,0 +1,262 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/bankcard"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/credential"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/note"
+	repositoryBankcard "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/bankcard"
+	repositoryCredential "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/credential"
+	repositoryNote "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/note"
+	"github.com/google/uuid"
+)
+
+// itemKeys exposes the identity and the revision time of a vault item.
+type itemKeys[T any] struct {
+	// id returns the item identifier.
+	id func(e *T) uuid.UUID
+	// userID returns the identifier of the item owner.
+	userID func(e *T) uuid.UUID
+	// updatedAt returns the time of the item revision.
+	updatedAt func(e *T) time.Time
+}
+
+// itemLoadParams holds the load parameters shared by the vault item repositories.
+type itemLoadParams struct {
+	// after restricts the load to the items whose ID sorts after it when set.
+	after uuid.UUID
+	// asOf selects the revisions current at this moment; zero selects the latest revisions.
+	asOf time.Time
+	// ids restricts the load to these items when non-nil.
+	ids []uuid.UUID
+	// id restricts the load to this item when set.
+	id uuid.UUID
+	// limit caps the number of loaded items and orders them by ID when positive.
+	limit int
+	// userID restricts the load to the items of this user when set.
+	userID uuid.UUID
+}
+
+// items keeps every saved revision of the vault items of one kind, so loads can look back in time
+// like the item history tables do.
+type items[T any] struct {
+	// keys exposes the identity and the revision time of the items.
+	keys itemKeys[T]
+	// revisions holds the saved revisions in save order.
+	revisions table[T]
+}
+
+// save stores a new revision of every entity; no entity may belong to another user than its stored revisions.
+func (s *items[T]) save(entities ...*T) error {
+	s.revisions.mu.Lock()
+	defer s.revisions.mu.Unlock()
+
+	for _, e := range entities {
+		for _, stored := range s.revisions.rows {
+			if s.keys.id(stored) == s.keys.id(e) && s.keys.userID(stored) != s.keys.userID(e) {
+				return fmt.Errorf("item %s belongs to another user", s.keys.id(e))
+			}
+		}
+	}
+	for _, e := range entities {
+		s.revisions.rows = append(s.revisions.rows, clone(e))
+	}
+	return nil
+}
+
+// load returns the current revisions of the matching items in the order the items were created,
+// or in ID order when the load is limited.
+func (s *items[T]) load(p itemLoadParams) ([]*T, error) {
+	if p.id == uuid.Nil && p.ids == nil && p.userID == uuid.Nil {
+		return nil, errors.New("at least one of ID or UserID must be provided")
+	}
+
+	s.revisions.mu.Lock()
+	defer s.revisions.mu.Unlock()
+
+	// order lists the item identifiers in creation order.
+	var order []uuid.UUID
+	current := make(map[uuid.UUID]*T)
+	for _, e := range s.revisions.rows {
+		id, at := s.keys.id(e), s.keys.updatedAt(e)
+		switch {
+		case p.id != uuid.Nil && id != p.id,
+			p.ids != nil && !slices.Contains(p.ids, id),
+			p.userID != uuid.Nil && s.keys.userID(e) != p.userID,
+			p.after != uuid.Nil && compareIDs(id, p.after) <= 0,
+			!p.asOf.IsZero() && at.After(p.asOf):
+			continue
+		}
+		prev, ok := current[id]
+		if !ok {
+			order = append(order, id)
+		}
+		if !ok || !at.Before(s.keys.updatedAt(prev)) {
+			current[id] = e
+		}
+	}
+
+	if p.limit > 0 {
+		slices.SortFunc(order, compareIDs)
+		order = order[:min(p.limit, len(order))]
+	}
+
+	// loaded collects copies of the current revisions.
+	var loaded []*T
+	for _, id := range order {
+		loaded = append(loaded, clone(current[id]))
+	}
+	return loaded, nil
+}
+
+// count returns the number of stored items of the user, or of every user when userID is unset, counting
+// every item once whatever its number of revisions.
+func (s *items[T]) count(userID uuid.UUID) int64 {
+	s.revisions.mu.Lock()
+	defer s.revisions.mu.Unlock()
+
+	ids := make(map[uuid.UUID]struct{})
+	for _, e := range s.revisions.rows {
+		if userID == uuid.Nil || s.keys.userID(e) == userID {
+			ids[s.keys.id(e)] = struct{}{}
+		}
+	}
+	return int64(len(ids))
+}
+
+// BankCardRepository keeps bank cards and their revisions in memory.
+type BankCardRepository struct {
+	// cards holds the bank card revisions.
+	cards items[bankcard.BankCard]
+}
+
+// NewBankCardRepository creates a new empty BankCardRepository.
+func NewBankCardRepository() *BankCardRepository {
+	return &BankCardRepository{cards: items[bankcard.BankCard]{keys: itemKeys[bankcard.BankCard]{
+		id:        func(e *bankcard.BankCard) uuid.UUID { return e.ID },
+		userID:    func(e *bankcard.BankCard) uuid.UUID { return e.UserID },
+		updatedAt: func(e *bankcard.BankCard) time.Time { return e.UpdatedAt },
+	}}}
+}
+
+// Save stores a new revision of a bank card.
+func (r *BankCardRepository) Save(_ context.Context, params repositoryBankcard.SaveParams) error {
+	return r.cards.save(params.Entity)
+}
+
+// SaveBatch stores new revisions of several bank cards of one user.
+func (r *BankCardRepository) SaveBatch(_ context.Context, params repositoryBankcard.SaveBatchParams) error {
+	for _, e := range params.Entities {
+		if e.UserID != params.UserID {
+			return fmt.Errorf("bank card %s does not belong to user %s", e.ID, params.UserID)
+		}
+	}
+	return r.cards.save(params.Entities...)
+}
+
+// Load retrieves bank cards; all fields are loaded regardless of the requested ones.
+func (r *BankCardRepository) Load(
+	_ context.Context,
+	params repositoryBankcard.LoadParams,
+) ([]*bankcard.BankCard, error) {
+	return r.cards.load(itemLoadParams{
+		after:  params.After,
+		asOf:   params.AsOf,
+		ids:    params.IDs,
+		id:     params.ID,
+		limit:  params.Limit,
+		userID: params.UserID,
+	})
+}
+
+// CredentialRepository keeps credentials and their revisions in memory.
+type CredentialRepository struct {
+	// credentials holds the credential revisions.
+	credentials items[credential.Credential]
+}
+
+// NewCredentialRepository creates a new empty CredentialRepository.
+func NewCredentialRepository() *CredentialRepository {
+	return &CredentialRepository{credentials: items[credential.Credential]{keys: itemKeys[credential.Credential]{
+		id:        func(e *credential.Credential) uuid.UUID { return e.ID },
+		userID:    func(e *credential.Credential) uuid.UUID { return e.UserID },
+		updatedAt: func(e *credential.Credential) time.Time { return e.UpdatedAt },
+	}}}
+}
+
+// Save stores a new revision of a credential.
+func (r *CredentialRepository) Save(_ context.Context, params repositoryCredential.SaveParams) error {
+	return r.credentials.save(params.Entity)
+}
+
+// SaveBatch stores new revisions of several credentials of one user.
+func (r *CredentialRepository) SaveBatch(_ context.Context, params repositoryCredential.SaveBatchParams) error {
+	for _, e := range params.Entities {
+		if e.UserID != params.UserID {
+			return fmt.Errorf("credential %s does not belong to user %s", e.ID, params.UserID)
+		}
+	}
+	return r.credentials.save(params.Entities...)
+}
+
+// Load retrieves credentials; all fields are loaded regardless of the requested ones.
+func (r *CredentialRepository) Load(
+	_ context.Context,
+	params repositoryCredential.LoadParams,
+) ([]*credential.Credential, error) {
+	return r.credentials.load(itemLoadParams{
+		after:  params.After,
+		asOf:   params.AsOf,
+		ids:    params.IDs,
+		id:     params.ID,
+		limit:  params.Limit,
+		userID: params.UserID,
+	})
+}
+
+// NoteRepository keeps notes and their revisions in memory.
+type NoteRepository struct {
+	// notes holds the note revisions.
+	notes items[note.Note]
+}
+
+// NewNoteRepository creates a new empty NoteRepository.
+func NewNoteRepository() *NoteRepository {
+	return &NoteRepository{notes: items[note.Note]{keys: itemKeys[note.Note]{
+		id:        func(e *note.Note) uuid.UUID { return e.ID },
+		userID:    func(e *note.Note) uuid.UUID { return e.UserID },
+		updatedAt: func(e *note.Note) time.Time { return e.UpdatedAt },
+	}}}
+}
+
+// Save stores a new revision of a note.
+func (r *NoteRepository) Save(_ context.Context, params repositoryNote.SaveParams) error {
+	return r.notes.save(params.Entity)
+}
+
+// SaveBatch stores new revisions of several notes of one user.
+func (r *NoteRepository) SaveBatch(_ context.Context, params repositoryNote.SaveBatchParams) error {
+	for _, e := range params.Entities {
+		if e.UserID != params.UserID {
+			return fmt.Errorf("note %s does not belong to user %s", e.ID, params.UserID)
+		}
+	}
+	return r.notes.save(params.Entities...)
+}
+
+// Load retrieves notes; all fields are loaded regardless of the requested ones.
+func (r *NoteRepository) Load(_ context.Context, params repositoryNote.LoadParams) ([]*note.Note, error) {
+	return r.notes.load(itemLoadParams{
+		after:  params.After,
+		asOf:   params.AsOf,
+		ids:    params.IDs,
+		id:     params.ID,
+		limit:  params.Limit,
+		userID: params.UserID,
+	})
+}
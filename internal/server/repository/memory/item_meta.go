@@ -0,0 +1,171 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/itemaccess"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/itemorder"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/itempath"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/itemtag"
+	repositoryItemaccess "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/itemaccess"
+	repositoryItemorder "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/itemorder"
+	repositoryItempath "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/itempath"
+	repositoryItemtag "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/itemtag"
+	"github.com/google/uuid"
+)
+
+// ItemTagRepository keeps the tags of vault items in memory.
+type ItemTagRepository struct {
+	// tags holds the stored tag sets.
+	tags table[itemtag.ItemTags]
+}
+
+// NewItemTagRepository creates a new empty ItemTagRepository.
+func NewItemTagRepository() *ItemTagRepository {
+	return &ItemTagRepository{}
+}
+
+// Save replaces the stored tags of the item, removing them when the tag set is empty.
+func (r *ItemTagRepository) Save(_ context.Context, params repositoryItemtag.SaveParams) error {
+	e := params.Entity
+	same := func(t *itemtag.ItemTags) bool { return t.UserID == e.UserID && t.ItemID == e.ItemID }
+	if len(e.Tags) == 0 {
+		r.tags.remove(same)
+		return nil
+	}
+	r.tags.put(e, same, nil)
+	return nil
+}
+
+// Load retrieves the tagged items of the user, most recently tagged first.
+func (r *ItemTagRepository) Load(_ context.Context, params repositoryItemtag.LoadParams) ([]*itemtag.ItemTags, error) {
+	tags := r.tags.filter(func(t *itemtag.ItemTags) bool {
+		return t.UserID == params.UserID && (params.ItemID == uuid.Nil || t.ItemID == params.ItemID) &&
+			(params.Tag == "" || slices.Contains(t.Tags, params.Tag))
+	})
+	slices.SortFunc(tags, func(a, b *itemtag.ItemTags) int {
+		return cmp.Or(b.UpdatedAt.Compare(a.UpdatedAt), compareIDs(a.ItemID, b.ItemID))
+	})
+	return tags, nil
+}
+
+// Exists reports whether the item, or any item of the user when no item is specified, carries the tag.
+func (r *ItemTagRepository) Exists(_ context.Context, params repositoryItemtag.ExistsParams) (bool, error) {
+	return len(r.tags.filter(func(t *itemtag.ItemTags) bool {
+		return t.UserID == params.UserID && (params.ItemID == uuid.Nil || t.ItemID == params.ItemID) &&
+			slices.Contains(t.Tags, params.Tag)
+	})) > 0, nil
+}
+
+// ItemPathRepository keeps the paths of vault items in memory.
+type ItemPathRepository struct {
+	// paths holds the stored item paths.
+	paths table[itempath.ItemPath]
+}
+
+// NewItemPathRepository creates a new empty ItemPathRepository.
+func NewItemPathRepository() *ItemPathRepository {
+	return &ItemPathRepository{}
+}
+
+// Save assigns the path to the item, removing the path of the item when it is empty.
+// Paths are unique per user.
+func (r *ItemPathRepository) Save(_ context.Context, params repositoryItempath.SaveParams) error {
+	e := params.Entity
+	same := func(p *itempath.ItemPath) bool { return p.UserID == e.UserID && p.ItemID == e.ItemID }
+	if e.Path == "" {
+		r.paths.remove(same)
+		return nil
+	}
+	if !r.paths.put(e, same, func(p *itempath.ItemPath) bool { return p.UserID == e.UserID && p.Path == e.Path }) {
+		return repositoryItempath.ErrPathTaken
+	}
+	return nil
+}
+
+// Load retrieves the item paths of the user matching the parameters ordered by path.
+func (r *ItemPathRepository) Load(
+	_ context.Context,
+	params repositoryItempath.LoadParams,
+) ([]*itempath.ItemPath, error) {
+	paths := r.paths.filter(func(p *itempath.ItemPath) bool {
+		return p.UserID == params.UserID &&
+			(params.ItemID == uuid.Nil || p.ItemID == params.ItemID) &&
+			(params.Path == "" || p.Path == params.Path) &&
+			(params.Prefix == "" || p.Path == params.Prefix || strings.HasPrefix(p.Path, params.Prefix+"/"))
+	})
+	slices.SortFunc(paths, func(a, b *itempath.ItemPath) int { return strings.Compare(a.Path, b.Path) })
+	return paths, nil
+}
+
+// ItemRevealRepository keeps the reveals of vault items in memory.
+type ItemRevealRepository struct {
+	// reveals holds the stored item reveals.
+	reveals table[itemaccess.Reveal]
+}
+
+// NewItemRevealRepository creates a new empty ItemRevealRepository.
+func NewItemRevealRepository() *ItemRevealRepository {
+	return &ItemRevealRepository{}
+}
+
+// Save stores the item reveal.
+func (r *ItemRevealRepository) Save(_ context.Context, params repositoryItemaccess.SaveParams) error {
+	r.reveals.add(params.Entity)
+	return nil
+}
+
+// Load retrieves the reveals of the item, newest first.
+func (r *ItemRevealRepository) Load(
+	_ context.Context,
+	params repositoryItemaccess.LoadParams,
+) ([]*itemaccess.Reveal, error) {
+	reveals := r.reveals.filter(func(e *itemaccess.Reveal) bool {
+		return e.UserID == params.UserID && e.ItemID == params.ItemID
+	})
+	slices.SortFunc(reveals, func(a, b *itemaccess.Reveal) int {
+		return cmp.Or(b.RevealedAt.Compare(a.RevealedAt), compareIDs(a.ID, b.ID))
+	})
+	return reveals, nil
+}
+
+// ItemOrderRepository keeps the manual orders of folders in memory.
+type ItemOrderRepository struct {
+	// orders holds the stored orders, one per user and folder.
+	orders table[itemorder.ItemOrder]
+}
+
+// NewItemOrderRepository creates a new empty ItemOrderRepository.
+func NewItemOrderRepository() *ItemOrderRepository {
+	return &ItemOrderRepository{}
+}
+
+// Save replaces the stored order of the folder, removing it when the order has no entries.
+func (r *ItemOrderRepository) Save(_ context.Context, params repositoryItemorder.SaveParams) error {
+	e := params.Entity
+	same := func(o *itemorder.ItemOrder) bool { return o.UserID == e.UserID && o.Folder == e.Folder }
+	if len(e.Entries) == 0 {
+		r.orders.remove(same)
+		return nil
+	}
+	r.orders.put(e, same, nil)
+	return nil
+}
+
+// Load retrieves the order of the folder.
+// Returns ErrOrderNotFound when the user has not arranged the items of the folder.
+func (r *ItemOrderRepository) Load(
+	_ context.Context,
+	params repositoryItemorder.LoadParams,
+) (*itemorder.ItemOrder, error) {
+	orders := r.orders.filter(func(o *itemorder.ItemOrder) bool {
+		return o.UserID == params.UserID && o.Folder == params.Folder
+	})
+	if len(orders) == 0 {
+		return nil, repositoryItemorder.ErrOrderNotFound
+	}
+	return orders[0], nil
+}
@@ -0,0 +1,113 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/credential"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/credential"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCredentialRepository_Load(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	userID, otherID := uuid.New(), uuid.New()
+	firstID := uuid.MustParse("00000000-0000-0000-0000-000000000002")
+	secondID := uuid.MustParse("00000000-0000-0000-0000-000000000001")
+	t0 := time.Date(2025, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+	repo := NewCredentialRepository()
+	for _, c := range []*credential.Credential{
+		{ID: firstID, UserID: userID, Login: []byte("v1"), UpdatedAt: t0},
+		{ID: secondID, UserID: userID, Login: []byte("other"), UpdatedAt: t0.Add(time.Minute)},
+		{ID: firstID, UserID: userID, Login: []byte("v2"), UpdatedAt: t0.Add(time.Hour)},
+	} {
+		require.NoError(t, repo.Save(ctx, repository.SaveParams{Entity: c}))
+	}
+
+	tests := []struct {
+		name      string
+		wantLogin []string
+		params    repository.LoadParams
+		wantErr   bool
+	}{
+		{
+			name:      "latest revisions in creation order",
+			params:    repository.LoadParams{UserID: userID},
+			wantLogin: []string{"v2", "other"},
+		},
+		{
+			name:      "revision as of a moment",
+			params:    repository.LoadParams{UserID: userID, AsOf: t0.Add(30 * time.Minute)},
+			wantLogin: []string{"v1", "other"},
+		},
+		{
+			name:      "single item",
+			params:    repository.LoadParams{ID: secondID, UserID: userID},
+			wantLogin: []string{"other"},
+		},
+		{
+			name:      "first page in ID order",
+			params:    repository.LoadParams{Limit: 1, UserID: userID},
+			wantLogin: []string{"other"},
+		},
+		{
+			name:      "page after an item",
+			params:    repository.LoadParams{After: secondID, Limit: 10, UserID: userID},
+			wantLogin: []string{"v2"},
+		},
+		{
+			name:   "another user",
+			params: repository.LoadParams{UserID: otherID},
+		},
+		{
+			name:    "no filter",
+			params:  repository.LoadParams{},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			loaded, err := repo.Load(ctx, tt.params)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			// logins collects the loaded logins.
+			var logins []string
+			for _, c := range loaded {
+				logins = append(logins, string(c.Login))
+			}
+			assert.Equal(t, tt.wantLogin, logins)
+		})
+	}
+}
+
+func TestCredentialRepository_SaveForeignItem(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	id := uuid.New()
+	repo := NewCredentialRepository()
+	require.NoError(t, repo.Save(ctx, repository.SaveParams{Entity: &credential.Credential{ID: id, UserID: uuid.New()}}))
+
+	err := repo.Save(ctx, repository.SaveParams{Entity: &credential.Credential{ID: id, UserID: uuid.New()}})
+	assert.Error(t, err, "an item of another user must not be overwritten")
+
+	otherUser := uuid.New()
+	err = repo.SaveBatch(ctx, repository.SaveBatchParams{
+		UserID:   uuid.New(),
+		Entities: []*credential.Credential{{ID: uuid.New(), UserID: otherUser}},
+	})
+	assert.Error(t, err, "a batch must only contain items of its user")
+}
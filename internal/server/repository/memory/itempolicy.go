@@ -0,0 +1,66 @@
+package memory
+
+import (
+	"context"
+	"slices"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/itempolicy"
+	repositoryItempolicy "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/itempolicy"
+	"github.com/google/uuid"
+)
+
+// ItemPolicyRepository keeps item policies in memory.
+type ItemPolicyRepository struct {
+	// policies holds the stored item policies.
+	policies table[itempolicy.Policy]
+}
+
+// NewItemPolicyRepository creates a new empty ItemPolicyRepository.
+func NewItemPolicyRepository() *ItemPolicyRepository {
+	return &ItemPolicyRepository{}
+}
+
+// Save creates the item policy or updates the existing policy with the same ID.
+func (r *ItemPolicyRepository) Save(_ context.Context, params repositoryItempolicy.SaveParams) error {
+	e := detachRules(params.Entity)
+	r.policies.put(e, func(p *itempolicy.Policy) bool { return p.ID == e.ID }, nil)
+	return nil
+}
+
+// Load retrieves the item policies matching the parameters ordered by creation time.
+// ErrPolicyNotFound is returned when a policy requested by ID does not exist.
+func (r *ItemPolicyRepository) Load(
+	_ context.Context,
+	params repositoryItempolicy.LoadParams,
+) ([]*itempolicy.Policy, error) {
+	policies := r.policies.filter(func(p *itempolicy.Policy) bool {
+		return params.ID == uuid.Nil || p.ID == params.ID
+	})
+	if params.ID != uuid.Nil && len(policies) == 0 {
+		return nil, repositoryItempolicy.ErrPolicyNotFound
+	}
+	for i, p := range policies {
+		policies[i] = detachRules(p)
+	}
+	slices.SortFunc(policies, func(a, b *itempolicy.Policy) int {
+		return compareCreated(a.CreatedAt, b.CreatedAt, a.ID, b.ID)
+	})
+	return policies, nil
+}
+
+// Delete removes the item policy.
+func (r *ItemPolicyRepository) Delete(_ context.Context, params repositoryItempolicy.DeleteParams) error {
+	if r.policies.remove(func(p *itempolicy.Policy) bool { return p.ID == params.ID }) == 0 {
+		return repositoryItempolicy.ErrPolicyNotFound
+	}
+	return nil
+}
+
+// detachRules returns a copy of the policy whose rules do not share memory with the original, which the table
+// copying only exported slice fields leaves shared.
+func detachRules(p *itempolicy.Policy) *itempolicy.Policy {
+	c := *p
+	c.ForbiddenKinds = slices.Clone(p.ForbiddenKinds)
+	c.RequiredClasses = slices.Clone(p.RequiredClasses)
+	return &c
+}
@@ -0,0 +1,59 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/itempolicy"
+	repositoryItempolicy "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/itempolicy"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestItemPolicyRepository_SaveLoadDelete(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	t0 := time.Date(2026, time.October, 1, 12, 0, 0, 0, time.UTC)
+	repo := NewItemPolicyRepository()
+
+	later := &itempolicy.Policy{
+		ID:        uuid.New(),
+		Name:      "Everyone",
+		Rules:     itempolicy.Rules{ForbiddenKinds: []string{"bankcard"}},
+		CreatedAt: t0.Add(time.Hour),
+	}
+	earlier := &itempolicy.Policy{
+		ID:        uuid.New(),
+		GroupID:   uuid.New(),
+		Name:      "Finance",
+		Rules:     itempolicy.Rules{MinPasswordLength: 12},
+		CreatedAt: t0,
+	}
+	for _, p := range []*itempolicy.Policy{later, earlier} {
+		require.NoError(t, repo.Save(ctx, repositoryItempolicy.SaveParams{Entity: p}))
+	}
+	later.ForbiddenKinds[0] = "note"
+
+	policies, err := repo.Load(ctx, repositoryItempolicy.LoadParams{})
+	require.NoError(t, err)
+	require.Len(t, policies, 2)
+	assert.Equal(t, earlier, policies[0])
+	assert.Equal(t, []string{"bankcard"}, policies[1].ForbiddenKinds)
+
+	updated := *earlier
+	updated.Name = "Accounting"
+	require.NoError(t, repo.Save(ctx, repositoryItempolicy.SaveParams{Entity: &updated}))
+	policies, err = repo.Load(ctx, repositoryItempolicy.LoadParams{ID: earlier.ID})
+	require.NoError(t, err)
+	require.Len(t, policies, 1)
+	assert.Equal(t, "Accounting", policies[0].Name)
+
+	require.NoError(t, repo.Delete(ctx, repositoryItempolicy.DeleteParams{ID: later.ID}))
+	_, err = repo.Load(ctx, repositoryItempolicy.LoadParams{ID: later.ID})
+	require.ErrorIs(t, err, repositoryItempolicy.ErrPolicyNotFound)
+	err = repo.Delete(ctx, repositoryItempolicy.DeleteParams{ID: later.ID})
+	assert.ErrorIs(t, err, repositoryItempolicy.ErrPolicyNotFound)
+}
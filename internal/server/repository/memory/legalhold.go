@@ -0,0 +1,51 @@
+package memory
+
+import (
+	"context"
+	"slices"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/legalhold"
+	repositoryLegalhold "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/legalhold"
+	"github.com/google/uuid"
+)
+
+// LegalHoldRepository keeps legal holds in memory.
+type LegalHoldRepository struct {
+	// holds holds the stored legal holds.
+	holds table[legalhold.Hold]
+}
+
+// NewLegalHoldRepository creates a new empty LegalHoldRepository.
+func NewLegalHoldRepository() *LegalHoldRepository {
+	return &LegalHoldRepository{}
+}
+
+// Save stores a new legal hold.
+func (r *LegalHoldRepository) Save(_ context.Context, params repositoryLegalhold.SaveParams) error {
+	r.holds.add(params.Entity)
+	return nil
+}
+
+// Load retrieves the legal holds matching the parameters ordered by placement time.
+// ErrHoldNotFound is returned when a hold requested by ID does not exist.
+func (r *LegalHoldRepository) Load(
+	_ context.Context,
+	params repositoryLegalhold.LoadParams,
+) ([]*legalhold.Hold, error) {
+	holds := r.holds.filter(func(h *legalhold.Hold) bool {
+		return (params.ID == uuid.Nil || h.ID == params.ID) && (params.UserID == uuid.Nil || h.UserID == params.UserID)
+	})
+	if params.ID != uuid.Nil && len(holds) == 0 {
+		return nil, repositoryLegalhold.ErrHoldNotFound
+	}
+	slices.SortFunc(holds, func(a, b *legalhold.Hold) int { return compareCreated(a.PlacedAt, b.PlacedAt, a.ID, b.ID) })
+	return holds, nil
+}
+
+// Delete removes the legal hold.
+func (r *LegalHoldRepository) Delete(_ context.Context, params repositoryLegalhold.DeleteParams) error {
+	if r.holds.remove(func(h *legalhold.Hold) bool { return h.ID == params.ID }) == 0 {
+		return repositoryLegalhold.ErrHoldNotFound
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/legalhold"
+	repositoryLegalhold "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/legalhold"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLegalHoldRepository_SaveLoadDelete(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	userID, otherID := uuid.New(), uuid.New()
+	t0 := time.Date(2026, time.October, 1, 12, 0, 0, 0, time.UTC)
+	repo := NewLegalHoldRepository()
+
+	later := &legalhold.Hold{ID: uuid.New(), UserID: userID, Reason: "Case 42", PlacedAt: t0.Add(time.Hour)}
+	earlier := &legalhold.Hold{ID: uuid.New(), UserID: userID, ItemID: uuid.New(), Reason: "Case 7", PlacedAt: t0}
+	other := &legalhold.Hold{ID: uuid.New(), UserID: otherID, Reason: "Case 42", PlacedAt: t0}
+	for _, h := range []*legalhold.Hold{later, earlier, other} {
+		require.NoError(t, repo.Save(ctx, repositoryLegalhold.SaveParams{Entity: h}))
+	}
+
+	holds, err := repo.Load(ctx, repositoryLegalhold.LoadParams{UserID: userID})
+	require.NoError(t, err)
+	require.Len(t, holds, 2)
+	assert.Equal(t, earlier, holds[0])
+	assert.Equal(t, later, holds[1])
+
+	holds, err = repo.Load(ctx, repositoryLegalhold.LoadParams{})
+	require.NoError(t, err)
+	assert.Len(t, holds, 3)
+
+	require.NoError(t, repo.Delete(ctx, repositoryLegalhold.DeleteParams{ID: later.ID}))
+	_, err = repo.Load(ctx, repositoryLegalhold.LoadParams{ID: later.ID})
+	require.ErrorIs(t, err, repositoryLegalhold.ErrHoldNotFound)
+	err = repo.Delete(ctx, repositoryLegalhold.DeleteParams{ID: later.ID})
+	assert.ErrorIs(t, err, repositoryLegalhold.ErrHoldNotFound)
+}
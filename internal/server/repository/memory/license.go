@@ -0,0 +1,55 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/license"
+)
+
+// installedLicense is an installed license key.
+type installedLicense struct {
+	// InstalledAt is the time the key was installed.
+	InstalledAt time.Time
+	// ID identifies the license.
+	ID string
+	// Key contains the license key as issued.
+	Key string
+}
+
+// LicenseRepository keeps installed license keys in memory.
+type LicenseRepository struct {
+	// licenses holds the installed license keys, one per license.
+	licenses table[installedLicense]
+}
+
+// NewLicenseRepository creates a new empty LicenseRepository.
+func NewLicenseRepository() *LicenseRepository {
+	return &LicenseRepository{}
+}
+
+// Save stores the installed license key. Installing a license again replaces its key and installation time.
+func (r *LicenseRepository) Save(_ context.Context, params repository.SaveParams) error {
+	r.licenses.put(
+		&installedLicense{InstalledAt: params.InstalledAt, ID: params.ID, Key: params.Key},
+		func(stored *installedLicense) bool { return stored.ID == params.ID },
+		nil,
+	)
+	return nil
+}
+
+// Load retrieves the most recently installed license key.
+// Returns ErrLicenseNotFound when no license key is installed.
+func (r *LicenseRepository) Load(_ context.Context) (string, error) {
+	licenses := r.licenses.filter(func(*installedLicense) bool { return true })
+	if len(licenses) == 0 {
+		return "", repository.ErrLicenseNotFound
+	}
+	latest := slices.MaxFunc(licenses, func(a, b *installedLicense) int {
+		return cmp.Or(a.InstalledAt.Compare(b.InstalledAt), strings.Compare(b.ID, a.ID))
+	})
+	return latest.Key, nil
+}
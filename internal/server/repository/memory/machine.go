@@ -0,0 +1,60 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"slices"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/machine"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/machine"
+	"github.com/google/uuid"
+)
+
+// MachineRepository keeps machine identities in memory.
+type MachineRepository struct {
+	// machines holds the stored machine identities.
+	machines table[machine.Machine]
+}
+
+// NewMachineRepository creates a new empty MachineRepository.
+func NewMachineRepository() *MachineRepository {
+	return &MachineRepository{}
+}
+
+// Save creates a machine identity or updates its name, scope and last use.
+func (r *MachineRepository) Save(_ context.Context, params repository.SaveParams) error {
+	e := params.Entity
+	if r.machines.update(func(m *machine.Machine) bool { return m.ID == e.ID }, func(m *machine.Machine) {
+		m.Name, m.Scope, m.LastUsedAt = e.Name, slices.Clone(e.Scope), e.LastUsedAt
+	}) == 0 {
+		r.machines.add(e)
+	}
+	return nil
+}
+
+// Load retrieves machine identities matching the provided parameters, ordered by creation time.
+// Loading by ID or token digest returns ErrMachineNotFound when no identity matches.
+func (r *MachineRepository) Load(_ context.Context, params repository.LoadParams) ([]*machine.Machine, error) {
+	machines := r.machines.filter(func(m *machine.Machine) bool {
+		return (params.ID == uuid.Nil || m.ID == params.ID) &&
+			(params.UserID == uuid.Nil || m.UserID == params.UserID) &&
+			(params.TokenHash == nil || bytes.Equal(m.TokenHash, params.TokenHash))
+	})
+	if (params.ID != uuid.Nil || params.TokenHash != nil) && len(machines) == 0 {
+		return nil, repository.ErrMachineNotFound
+	}
+	slices.SortFunc(machines, func(a, b *machine.Machine) int {
+		return compareCreated(a.CreatedAt, b.CreatedAt, a.ID, b.ID)
+	})
+	return machines, nil
+}
+
+// Delete removes the machine identity with the ID of the user.
+func (r *MachineRepository) Delete(_ context.Context, params repository.DeleteParams) error {
+	if r.machines.remove(func(m *machine.Machine) bool {
+		return m.ID == params.ID && m.UserID == params.UserID
+	}) == 0 {
+		return repository.ErrMachineNotFound
+	}
+	return nil
+}
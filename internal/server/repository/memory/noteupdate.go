@@ -0,0 +1,109 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/notecrdt"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/noteupdate"
+	"github.com/google/uuid"
+)
+
+// NoteUpdateRepository keeps the pending collaborative updates of notes in memory.
+type NoteUpdateRepository struct {
+	// updates holds the stored updates.
+	updates table[notecrdt.Record]
+	// seq holds the last assigned sequence number.
+	seq int64
+}
+
+// NewNoteUpdateRepository creates a new empty NoteUpdateRepository.
+func NewNoteUpdateRepository() *NoteUpdateRepository {
+	return &NoteUpdateRepository{}
+}
+
+// Save stores a note update and returns its sequence number. A nonzero Seq is stored as is.
+func (r *NoteUpdateRepository) Save(_ context.Context, params repository.SaveParams) (int64, error) {
+	e := clone(params.Entity)
+
+	r.updates.mu.Lock()
+	defer r.updates.mu.Unlock()
+
+	if e.Seq == 0 {
+		r.seq++
+		e.Seq = r.seq
+	}
+	r.seq = max(r.seq, e.Seq)
+	r.updates.rows = append(r.updates.rows, e)
+	return e.Seq, nil
+}
+
+// Load retrieves the updates of a note after the sequence number in sequence order.
+// Updates are not locked; Lock is accepted for compatibility.
+func (r *NoteUpdateRepository) Load(_ context.Context, params repository.LoadParams) ([]*notecrdt.Record, error) {
+	if params.NoteID == uuid.Nil || params.UserID == uuid.Nil {
+		return nil, errors.New("both NoteID and UserID must be provided")
+	}
+	updates := r.updates.filter(func(u *notecrdt.Record) bool {
+		return u.NoteID == params.NoteID && u.UserID == params.UserID && u.Seq > params.AfterSeq
+	})
+	slices.SortFunc(updates, func(a, b *notecrdt.Record) int { return cmp.Compare(a.Seq, b.Seq) })
+	return updates, nil
+}
+
+// Delete removes the updates of a note up to the sequence number; a zero UpToSeq removes all of them.
+func (r *NoteUpdateRepository) Delete(_ context.Context, params repository.DeleteParams) error {
+	if params.NoteID == uuid.Nil || params.UserID == uuid.Nil {
+		return errors.New("both NoteID and UserID must be provided")
+	}
+	r.updates.remove(func(u *notecrdt.Record) bool {
+		return u.NoteID == params.NoteID && u.UserID == params.UserID &&
+			(params.UpToSeq == 0 || u.Seq <= params.UpToSeq)
+	})
+	return nil
+}
+
+// Merging returns which of the notes have stored updates.
+func (r *NoteUpdateRepository) Merging(_ context.Context, params repository.MergingParams) ([]uuid.UUID, error) {
+	if params.UserID == uuid.Nil {
+		return nil, errors.New("UserID must be provided")
+	}
+	// ids collects the notes with stored updates.
+	var ids []uuid.UUID
+	for _, u := range r.updates.filter(func(u *notecrdt.Record) bool {
+		return u.UserID == params.UserID && slices.Contains(params.NoteIDs, u.NoteID)
+	}) {
+		if !slices.Contains(ids, u.NoteID) {
+			ids = append(ids, u.NoteID)
+		}
+	}
+	return ids, nil
+}
+
+// Compactable returns the notes of all users with at least the minimum number of stored updates.
+func (r *NoteUpdateRepository) Compactable(
+	_ context.Context,
+	params repository.CompactableParams,
+) ([]repository.NoteRef, error) {
+	// counts holds the number of updates per note.
+	counts := make(map[repository.NoteRef]int)
+	// order lists the notes in the order of their first update.
+	var order []repository.NoteRef
+	for _, u := range r.updates.filter(func(*notecrdt.Record) bool { return true }) {
+		ref := repository.NoteRef{NoteID: u.NoteID, UserID: u.UserID}
+		if counts[ref] == 0 {
+			order = append(order, ref)
+		}
+		counts[ref]++
+	}
+	// refs collects the notes worth compacting.
+	var refs []repository.NoteRef
+	for _, ref := range order {
+		if counts[ref] >= params.MinUpdates {
+			refs = append(refs, ref)
+		}
+	}
+	return refs, nil
+}
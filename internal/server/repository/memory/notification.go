@@ -0,0 +1,118 @@
+package memory
+
+import (
+	"context"
+	"crypto/sha256"
+	"slices"
+	"strings"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/notification"
+	repositoryNotification "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/notification"
+	repositoryPushsubscription "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/pushsubscription"
+	"github.com/google/uuid"
+)
+
+// NotificationRepository keeps notification channels in memory. Targets are stored as is, without encryption.
+type NotificationRepository struct {
+	// channels holds the stored channels.
+	channels table[notification.Channel]
+}
+
+// NewNotificationRepository creates a new empty NotificationRepository.
+func NewNotificationRepository() *NotificationRepository {
+	return &NotificationRepository{}
+}
+
+// Save creates or updates a channel; every user has at most one channel of a kind.
+func (r *NotificationRepository) Save(_ context.Context, params repositoryNotification.SaveParams) error {
+	e := params.Entity
+	if !r.channels.put(e,
+		func(c *notification.Channel) bool { return c.ID == e.ID },
+		func(c *notification.Channel) bool { return c.UserID == e.UserID && c.Kind == e.Kind },
+	) {
+		return repositoryNotification.ErrChannelAlreadyExists
+	}
+	return nil
+}
+
+// Load retrieves the channels of the user, or only the one of the kind when set, ordered by kind.
+// Loading by kind returns ErrChannelNotFound when the user has no such channel.
+func (r *NotificationRepository) Load(
+	_ context.Context,
+	params repositoryNotification.LoadParams,
+) ([]*notification.Channel, error) {
+	channels := r.channels.filter(func(c *notification.Channel) bool {
+		return c.UserID == params.UserID && (params.Kind == "" || c.Kind == params.Kind)
+	})
+	if params.Kind != "" && len(channels) == 0 {
+		return nil, repositoryNotification.ErrChannelNotFound
+	}
+	slices.SortFunc(channels, func(a, b *notification.Channel) int {
+		return strings.Compare(string(a.Kind), string(b.Kind))
+	})
+	return channels, nil
+}
+
+// Delete removes the channel of the kind of the user.
+func (r *NotificationRepository) Delete(_ context.Context, params repositoryNotification.DeleteParams) error {
+	if r.channels.remove(func(c *notification.Channel) bool {
+		return c.UserID == params.UserID && c.Kind == params.Kind
+	}) == 0 {
+		return repositoryNotification.ErrChannelNotFound
+	}
+	return nil
+}
+
+// PushSubscriptionRepository keeps web push subscriptions in memory.
+type PushSubscriptionRepository struct {
+	// subscriptions holds the stored subscriptions.
+	subscriptions table[notification.PushSubscription]
+}
+
+// NewPushSubscriptionRepository creates a new empty PushSubscriptionRepository.
+func NewPushSubscriptionRepository() *PushSubscriptionRepository {
+	return &PushSubscriptionRepository{}
+}
+
+// Save creates or updates a subscription; every user subscribes a push endpoint at most once.
+func (r *PushSubscriptionRepository) Save(_ context.Context, params repositoryPushsubscription.SaveParams) error {
+	e := params.Entity
+	digest := sha256.Sum256([]byte(e.Subscription.Endpoint))
+	if !r.subscriptions.put(e,
+		func(s *notification.PushSubscription) bool { return s.ID == e.ID },
+		func(s *notification.PushSubscription) bool {
+			return s.UserID == e.UserID && sha256.Sum256([]byte(s.Subscription.Endpoint)) == digest
+		},
+	) {
+		return repositoryPushsubscription.ErrSubscriptionAlreadyExists
+	}
+	return nil
+}
+
+// Load retrieves the subscriptions of the user, or only the one with the ID when set, ordered by creation time.
+// Loading by ID returns ErrSubscriptionNotFound when the subscription does not exist.
+func (r *PushSubscriptionRepository) Load(
+	_ context.Context,
+	params repositoryPushsubscription.LoadParams,
+) ([]*notification.PushSubscription, error) {
+	subscriptions := r.subscriptions.filter(func(s *notification.PushSubscription) bool {
+		return s.UserID == params.UserID && (params.ID == uuid.Nil || s.ID == params.ID)
+	})
+	if params.ID != uuid.Nil && len(subscriptions) == 0 {
+		return nil, repositoryPushsubscription.ErrSubscriptionNotFound
+	}
+	slices.SortFunc(subscriptions, func(a, b *notification.PushSubscription) int {
+		return compareCreated(a.CreatedAt, b.CreatedAt, a.ID, b.ID)
+	})
+	return subscriptions, nil
+}
+
+// Delete removes the subscription with the ID of the user.
+func (r *PushSubscriptionRepository) Delete(_ context.Context, params repositoryPushsubscription.DeleteParams) error {
+	if r.subscriptions.remove(func(s *notification.PushSubscription) bool {
+		return s.UserID == params.UserID && s.ID == params.ID
+	}) == 0 {
+		return repositoryPushsubscription.ErrSubscriptionNotFound
+	}
+	return nil
+}
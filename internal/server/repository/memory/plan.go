@@ -0,0 +1,104 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/auth"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/filedata"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/plan"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/plan"
+)
+
+// billingEvent is an applied billing event.
+type billingEvent struct {
+	// ID identifies the event at the billing provider.
+	ID string
+}
+
+// PlanRepository counts the vault items of the in-memory repositories for plan enforcement and keeps the
+// billing subscriptions of users in memory.
+type PlanRepository struct {
+	// users resolves the subscribed users.
+	users *UserRepository
+	// credentials provides the stored credentials.
+	credentials *CredentialRepository
+	// notes provides the stored notes.
+	notes *NoteRepository
+	// cards provides the stored bank cards.
+	cards *BankCardRepository
+	// files provides the stored file metadata.
+	files *FileDataRepository
+	// subscriptions holds the stored subscriptions, one per user.
+	subscriptions table[plan.Subscription]
+	// events holds the applied billing events.
+	events table[billingEvent]
+}
+
+// NewPlanRepository creates a new PlanRepository counting the items of the provided repositories and
+// resolving subscribed users with the user repository.
+func NewPlanRepository(
+	users *UserRepository,
+	credentials *CredentialRepository,
+	notes *NoteRepository,
+	cards *BankCardRepository,
+	files *FileDataRepository,
+) *PlanRepository {
+	return &PlanRepository{users: users, credentials: credentials, notes: notes, cards: cards, files: files}
+}
+
+// CountItems returns the number of credentials, notes, bank cards and files stored by the user.
+func (r *PlanRepository) CountItems(_ context.Context, params repository.CountItemsParams) (int64, error) {
+	files := r.files.files.filter(func(f *filedata.FileData) bool { return f.UserID == params.UserID })
+	return r.credentials.credentials.count(params.UserID) +
+		r.notes.notes.count(params.UserID) +
+		r.cards.cards.count(params.UserID) +
+		int64(len(files)), nil
+}
+
+// LoadSubscription retrieves the subscription of the user.
+// Returns ErrSubscriptionNotFound when the user has no subscription.
+func (r *PlanRepository) LoadSubscription(
+	_ context.Context,
+	params repository.LoadSubscriptionParams,
+) (*plan.Subscription, error) {
+	subs := r.subscriptions.filter(func(s *plan.Subscription) bool { return s.UserID == params.UserID })
+	if len(subs) == 0 {
+		return nil, repository.ErrSubscriptionNotFound
+	}
+	return subs[0], nil
+}
+
+// SaveSubscription stores the subscription of the user unless a subscription from a later billing event is
+// stored. Returns ErrSubscriptionUserNotFound when the user does not exist.
+func (r *PlanRepository) SaveSubscription(_ context.Context, params repository.SaveSubscriptionParams) error {
+	e := params.Entity
+	if len(r.users.users.filter(func(u *auth.User) bool { return u.ID == e.UserID })) == 0 {
+		return repository.ErrSubscriptionUserNotFound
+	}
+
+	r.subscriptions.mu.Lock()
+	defer r.subscriptions.mu.Unlock()
+
+	for i, stored := range r.subscriptions.rows {
+		if stored.UserID != e.UserID {
+			continue
+		}
+		if !stored.EventAt.After(e.EventAt) {
+			r.subscriptions.rows[i] = clone(e)
+		}
+		return nil
+	}
+	r.subscriptions.rows = append(r.subscriptions.rows, clone(e))
+	return nil
+}
+
+// HasEvent reports whether the billing event was already applied.
+func (r *PlanRepository) HasEvent(_ context.Context, params repository.EventParams) (bool, error) {
+	return len(r.events.filter(func(e *billingEvent) bool { return e.ID == params.ID })) > 0, nil
+}
+
+// SaveEvent records the billing event as applied; recording it again has no effect.
+func (r *PlanRepository) SaveEvent(_ context.Context, params repository.EventParams) error {
+	r.events.put(&billingEvent{ID: params.ID}, func(stored *billingEvent) bool { return stored.ID == params.ID }, nil)
+	return nil
+}
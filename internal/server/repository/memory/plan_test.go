@@ -0,0 +1,120 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/auth"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/credential"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/filedata"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/plan"
+	repositoryAuth "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/auth"
+	repositoryCredential "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/credential"
+	repositoryFiledata "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/filedata"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/plan"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanRepository_CountItems(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	credentials, files := NewCredentialRepository(), NewFileDataRepository()
+	repo := NewPlanRepository(NewUserRepository(), credentials, NewNoteRepository(), NewBankCardRepository(), files)
+
+	alice, bob := uuid.New(), uuid.New()
+	credentialID := uuid.New()
+	for _, c := range []*credential.Credential{
+		{ID: credentialID, UserID: alice, UpdatedAt: now.Add(-time.Hour)},
+		{ID: credentialID, UserID: alice, UpdatedAt: now},
+		{ID: uuid.New(), UserID: bob, UpdatedAt: now},
+	} {
+		require.NoError(t, credentials.Save(ctx, repositoryCredential.SaveParams{Entity: c}))
+	}
+	require.NoError(t, files.Save(ctx, repositoryFiledata.SaveParams{
+		Entity: &filedata.FileData{ID: uuid.New(), UserID: alice},
+	}))
+
+	tests := []struct {
+		name   string
+		userID uuid.UUID
+		want   int64
+	}{
+		{name: "revisions counted once", userID: alice, want: 2},
+		{name: "other user", userID: bob, want: 1},
+		{name: "no items", userID: uuid.New()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := repo.CountItems(ctx, repository.CountItemsParams{UserID: tt.userID})
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestPlanRepository_SaveSubscription(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	users := NewUserRepository()
+	repo := NewPlanRepository(
+		users, NewCredentialRepository(), NewNoteRepository(), NewBankCardRepository(), NewFileDataRepository(),
+	)
+	userID := uuid.New()
+	require.NoError(t, users.Save(ctx, repositoryAuth.SaveParams{Entity: &auth.User{ID: userID, Login: "alice"}}))
+
+	_, err := repo.LoadSubscription(ctx, repository.LoadSubscriptionParams{UserID: userID})
+	require.ErrorIs(t, err, repository.ErrSubscriptionNotFound)
+
+	save := func(eventAt time.Time, name string) error {
+		return repo.SaveSubscription(ctx, repository.SaveSubscriptionParams{Entity: &plan.Subscription{
+			EventAt: eventAt, Plan: name, Status: plan.StatusActive, UserID: userID,
+		}})
+	}
+	require.NoError(t, save(now, plan.NamePremium))
+	require.NoError(t, save(now.Add(-time.Minute), plan.NameFree), "stale subscriptions are ignored")
+
+	got, err := repo.LoadSubscription(ctx, repository.LoadSubscriptionParams{UserID: userID})
+	require.NoError(t, err)
+	assert.Equal(t, plan.NamePremium, got.Plan)
+
+	require.NoError(t, save(now.Add(time.Minute), plan.NameFree))
+	got, err = repo.LoadSubscription(ctx, repository.LoadSubscriptionParams{UserID: userID})
+	require.NoError(t, err)
+	assert.Equal(t, plan.NameFree, got.Plan)
+
+	err = repo.SaveSubscription(ctx, repository.SaveSubscriptionParams{Entity: &plan.Subscription{UserID: uuid.New()}})
+	require.ErrorIs(t, err, repository.ErrSubscriptionUserNotFound)
+}
+
+func TestPlanRepository_Events(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	repo := NewPlanRepository(
+		NewUserRepository(), NewCredentialRepository(), NewNoteRepository(), NewBankCardRepository(),
+		NewFileDataRepository(),
+	)
+
+	seen, err := repo.HasEvent(ctx, repository.EventParams{ID: "evt_1"})
+	require.NoError(t, err)
+	assert.False(t, seen)
+
+	require.NoError(t, repo.SaveEvent(ctx, repository.EventParams{ID: "evt_1"}))
+	require.NoError(t, repo.SaveEvent(ctx, repository.EventParams{ID: "evt_1"}))
+
+	seen, err = repo.HasEvent(ctx, repository.EventParams{ID: "evt_1"})
+	require.NoError(t, err)
+	assert.True(t, seen)
+	assert.Len(t, repo.events.rows, 1)
+}
@@ -0,0 +1,138 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/approval"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/authz"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/checkout"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/itemaccess"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/itempath"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/itemtag"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/notecrdt"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/rotation"
+	repositoryPurge "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/purge"
+	"github.com/google/uuid"
+)
+
+// purgeable lists the current items of one kind of a user and removes them with every revision.
+type purgeable interface {
+	// summarize lists the identities and revision times of the current items of the user in ID order.
+	summarize(userID uuid.UUID) []*repositoryPurge.Item
+	// purge removes every revision of the listed items of the user and returns the number of removed items.
+	purge(userID uuid.UUID, ids []uuid.UUID) int64
+}
+
+// summarize lists the identities and revision times of the current items of the user in ID order.
+func (s *items[T]) summarize(userID uuid.UUID) []*repositoryPurge.Item {
+	current, _ := s.load(itemLoadParams{userID: userID})
+	summary := make([]*repositoryPurge.Item, 0, len(current))
+	for _, e := range current {
+		summary = append(summary, &repositoryPurge.Item{ID: s.keys.id(e), UpdatedAt: s.keys.updatedAt(e)})
+	}
+	slices.SortFunc(summary, func(a, b *repositoryPurge.Item) int { return compareIDs(a.ID, b.ID) })
+	return summary
+}
+
+// purge removes every revision of the listed items of the user and returns the number of removed items.
+func (s *items[T]) purge(userID uuid.UUID, ids []uuid.UUID) int64 {
+	before := s.count(userID)
+	s.revisions.remove(func(e *T) bool {
+		return s.keys.userID(e) == userID && slices.Contains(ids, s.keys.id(e))
+	})
+	return before - s.count(userID)
+}
+
+// PurgeRepository permanently deletes vault items kept in memory together with the rows belonging to them.
+type PurgeRepository struct {
+	// kinds maps the kinds of items that can be purged to their storage.
+	kinds map[string]purgeable
+	// tags holds the tags of the items.
+	tags *ItemTagRepository
+	// paths holds the paths of the items.
+	paths *ItemPathRepository
+	// reveals holds the reveals of the items.
+	reveals *ItemRevealRepository
+	// approvals holds the access requests for the items.
+	approvals *ApprovalRepository
+	// shares holds the shares of credentials.
+	shares *CheckoutRepository
+	// rotations holds the rotation policies of credentials.
+	rotations *RotationRepository
+	// updates holds the updates of notes.
+	updates *NoteUpdateRepository
+}
+
+// NewPurgeRepository creates a new PurgeRepository over the in-memory repositories holding the items and
+// the rows belonging to them.
+func NewPurgeRepository(
+	cards *BankCardRepository,
+	credentials *CredentialRepository,
+	notes *NoteRepository,
+	tags *ItemTagRepository,
+	paths *ItemPathRepository,
+	reveals *ItemRevealRepository,
+	approvals *ApprovalRepository,
+	shares *CheckoutRepository,
+	rotations *RotationRepository,
+	updates *NoteUpdateRepository,
+) *PurgeRepository {
+	return &PurgeRepository{
+		kinds: map[string]purgeable{
+			authz.KindBankCard:   &cards.cards,
+			authz.KindCredential: &credentials.credentials,
+			authz.KindNote:       &notes.notes,
+		},
+		tags:      tags,
+		paths:     paths,
+		reveals:   reveals,
+		approvals: approvals,
+		shares:    shares,
+		rotations: rotations,
+		updates:   updates,
+	}
+}
+
+// Load lists the items of the kind of the user in ID order.
+func (r *PurgeRepository) Load(_ context.Context, params repositoryPurge.LoadParams) ([]*repositoryPurge.Item, error) {
+	kind, ok := r.kinds[params.Kind]
+	if !ok {
+		return nil, fmt.Errorf("failed to load %q items: %w", params.Kind, repositoryPurge.ErrUnknownKind)
+	}
+	return kind.summarize(params.UserID), nil
+}
+
+// Delete permanently removes the listed items of the kind of the user together with their revisions and
+// every row belonging to them, and returns the number of deleted items.
+func (r *PurgeRepository) Delete(_ context.Context, params repositoryPurge.DeleteParams) (int64, error) {
+	kind, ok := r.kinds[params.Kind]
+	if !ok {
+		return 0, fmt.Errorf("failed to delete %q items: %w", params.Kind, repositoryPurge.ErrUnknownKind)
+	}
+
+	userID, ids := params.UserID, params.IDs
+	r.tags.tags.remove(func(e *itemtag.ItemTags) bool {
+		return e.UserID == userID && slices.Contains(ids, e.ItemID)
+	})
+	r.paths.paths.remove(func(e *itempath.ItemPath) bool {
+		return e.UserID == userID && slices.Contains(ids, e.ItemID)
+	})
+	r.reveals.reveals.remove(func(e *itemaccess.Reveal) bool {
+		return e.UserID == userID && slices.Contains(ids, e.ItemID)
+	})
+	r.approvals.requests.remove(func(e *approval.Request) bool {
+		return e.UserID == userID && slices.Contains(ids, e.ItemID)
+	})
+	r.shares.shares.remove(func(e *checkout.Share) bool {
+		return e.OwnerID == userID && slices.Contains(ids, e.CredentialID)
+	})
+	r.rotations.policies.remove(func(e *rotation.Policy) bool {
+		return e.UserID == userID && slices.Contains(ids, e.CredentialID)
+	})
+	r.updates.updates.remove(func(e *notecrdt.Record) bool {
+		return e.UserID == userID && slices.Contains(ids, e.NoteID)
+	})
+	return kind.purge(userID, ids), nil
+}
@@ -0,0 +1,84 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/credential"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/itemtag"
+	repositoryCredential "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/credential"
+	repositoryItemtag "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/itemtag"
+	repositoryPurge "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/purge"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPurgeRepository(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	userID, otherID := uuid.New(), uuid.New()
+	firstID := uuid.MustParse("00000000-0000-0000-0000-000000000002")
+	secondID := uuid.MustParse("00000000-0000-0000-0000-000000000001")
+	otherItemID := uuid.New()
+	t0 := time.Date(2025, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+	credentials := NewCredentialRepository()
+	for _, c := range []*credential.Credential{
+		{ID: firstID, UserID: userID, UpdatedAt: t0},
+		{ID: secondID, UserID: userID, UpdatedAt: t0.Add(time.Minute)},
+		{ID: firstID, UserID: userID, UpdatedAt: t0.Add(time.Hour)},
+		{ID: otherItemID, UserID: otherID, UpdatedAt: t0},
+	} {
+		require.NoError(t, credentials.Save(ctx, repositoryCredential.SaveParams{Entity: c}))
+	}
+	tags := NewItemTagRepository()
+	for _, tag := range []*itemtag.ItemTags{
+		{ItemID: firstID, UserID: userID, Tags: []string{"work"}},
+		{ItemID: otherItemID, UserID: otherID, Tags: []string{"work"}},
+	} {
+		require.NoError(t, tags.Save(ctx, repositoryItemtag.SaveParams{Entity: tag}))
+	}
+
+	shares := NewCheckoutRepository(NewGroupRepository(NewUserRepository()))
+	repo := NewPurgeRepository(NewBankCardRepository(), credentials, NewNoteRepository(), tags,
+		NewItemPathRepository(), NewItemRevealRepository(), NewApprovalRepository(), shares,
+		NewRotationRepository(), NewNoteUpdateRepository())
+
+	items, err := repo.Load(ctx, repositoryPurge.LoadParams{Kind: "credential", UserID: userID})
+	require.NoError(t, err)
+	assert.Equal(t, []*repositoryPurge.Item{
+		{ID: secondID, UpdatedAt: t0.Add(time.Minute)},
+		{ID: firstID, UpdatedAt: t0.Add(time.Hour)},
+	}, items)
+
+	_, err = repo.Load(ctx, repositoryPurge.LoadParams{Kind: "file", UserID: userID})
+	require.ErrorIs(t, err, repositoryPurge.ErrUnknownKind)
+
+	n, err := repo.Delete(ctx, repositoryPurge.DeleteParams{
+		IDs:    []uuid.UUID{firstID, secondID},
+		Kind:   "credential",
+		UserID: userID,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+
+	items, err = repo.Load(ctx, repositoryPurge.LoadParams{Kind: "credential", UserID: userID})
+	require.NoError(t, err)
+	assert.Empty(t, items)
+	left, err := credentials.Load(ctx, repositoryCredential.LoadParams{UserID: userID, AsOf: t0.Add(time.Second)})
+	require.NoError(t, err)
+	assert.Empty(t, left, "retained revisions are purged")
+
+	userTags, err := tags.Load(ctx, repositoryItemtag.LoadParams{UserID: userID})
+	require.NoError(t, err)
+	assert.Empty(t, userTags)
+	otherTags, err := tags.Load(ctx, repositoryItemtag.LoadParams{UserID: otherID})
+	require.NoError(t, err)
+	assert.Len(t, otherTags, 1)
+	otherItems, err := repo.Load(ctx, repositoryPurge.LoadParams{Kind: "credential", UserID: otherID})
+	require.NoError(t, err)
+	assert.Len(t, otherItems, 1)
+}
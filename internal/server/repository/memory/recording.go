@@ -0,0 +1,106 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/recording"
+	"github.com/google/uuid"
+)
+
+// recordedExchange is a captured request/response pair of a recording session.
+type recordedExchange struct {
+	// Exchange contains the captured pair.
+	Exchange repository.Exchange
+	// SessionID identifies the session the pair was captured for.
+	SessionID uuid.UUID
+}
+
+// RecordingRepository keeps recording sessions and their captured pairs in memory, without encryption.
+type RecordingRepository struct {
+	// sessions holds the recording sessions.
+	sessions table[repository.Session]
+	// exchanges holds the captured pairs of every session.
+	exchanges table[recordedExchange]
+}
+
+// NewRecordingRepository creates a new empty RecordingRepository.
+func NewRecordingRepository() *RecordingRepository {
+	return &RecordingRepository{}
+}
+
+// SaveSession stores a new recording session or updates the expiry time of an existing one.
+func (r *RecordingRepository) SaveSession(_ context.Context, params repository.SaveSessionParams) error {
+	s := params.Session
+	if r.sessions.update(
+		func(stored *repository.Session) bool { return stored.ID == s.ID },
+		func(stored *repository.Session) { stored.ExpiresAt = s.ExpiresAt },
+	) == 0 {
+		s.Exchanges = 0
+		r.sessions.add(&s)
+	}
+	return nil
+}
+
+// LoadSessions retrieves recording sessions matching the provided parameters with the number of pairs captured
+// for them, most recent first.
+func (r *RecordingRepository) LoadSessions(
+	_ context.Context,
+	params repository.LoadSessionsParams,
+) ([]*repository.Session, error) {
+	sessions := r.sessions.filter(func(s *repository.Session) bool {
+		if params.ID != uuid.Nil && s.ID != params.ID {
+			return false
+		}
+		return params.ActiveAt.IsZero() || !s.StartedAt.After(params.ActiveAt) && s.ExpiresAt.After(params.ActiveAt)
+	})
+	for _, s := range sessions {
+		s.Exchanges = len(r.exchanges.filter(func(e *recordedExchange) bool { return e.SessionID == s.ID }))
+	}
+	slices.SortFunc(sessions, func(a, b *repository.Session) int {
+		return cmp.Or(b.StartedAt.Compare(a.StartedAt), compareIDs(a.ID, b.ID))
+	})
+	return sessions, nil
+}
+
+// DeleteSession removes the recording session together with the pairs captured for it.
+// Returns ErrSessionNotFound when no such session exists.
+func (r *RecordingRepository) DeleteSession(_ context.Context, id uuid.UUID) error {
+	if r.sessions.remove(func(s *repository.Session) bool { return s.ID == id }) == 0 {
+		return repository.ErrSessionNotFound
+	}
+	r.exchanges.remove(func(e *recordedExchange) bool { return e.SessionID == id })
+	return nil
+}
+
+// DeleteExpired removes the recording sessions that expired before the moment together with their pairs and
+// returns the number of removed sessions.
+func (r *RecordingRepository) DeleteExpired(_ context.Context, before time.Time) (int64, error) {
+	expired := r.sessions.filter(func(s *repository.Session) bool { return s.ExpiresAt.Before(before) })
+	for _, s := range expired {
+		r.sessions.remove(func(stored *repository.Session) bool { return stored.ID == s.ID })
+		r.exchanges.remove(func(e *recordedExchange) bool { return e.SessionID == s.ID })
+	}
+	return int64(len(expired)), nil
+}
+
+// SaveExchange stores a captured request/response pair.
+func (r *RecordingRepository) SaveExchange(_ context.Context, params repository.SaveExchangeParams) error {
+	r.exchanges.add(&recordedExchange{Exchange: params.Exchange, SessionID: params.SessionID})
+	return nil
+}
+
+// LoadExchanges retrieves the pairs captured for the recording session in the order they were captured.
+func (r *RecordingRepository) LoadExchanges(_ context.Context, sessionID uuid.UUID) ([]*repository.Exchange, error) {
+	stored := r.exchanges.filter(func(e *recordedExchange) bool { return e.SessionID == sessionID })
+	exchanges := make([]*repository.Exchange, 0, len(stored))
+	for _, e := range stored {
+		exchanges = append(exchanges, &e.Exchange)
+	}
+	slices.SortStableFunc(exchanges, func(a, b *repository.Exchange) int {
+		return compareCreated(a.RecordedAt, b.RecordedAt, a.ID, b.ID)
+	})
+	return exchanges, nil
+}
@@ -0,0 +1,153 @@
+package memory
+
+import (
+	"context"
+	"slices"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/itemaccess"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/retention"
+	repositoryRetention "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/retention"
+	"github.com/google/uuid"
+)
+
+// expirable counts and removes the replaced revisions of the items of one kind the retention rules do not keep.
+type expirable interface {
+	// expire counts or, when purge is set, removes the revisions of the items in scope beyond the
+	// current revision and the latest keep replaced ones, and returns their number.
+	expire(keep int, inScope func(userID, itemID uuid.UUID) bool, purge bool) int64
+}
+
+// expire counts or, when purge is set, removes the revisions of the items in scope beyond the
+// current revision and the latest keep replaced ones, and returns their number.
+func (s *items[T]) expire(keep int, inScope func(userID, itemID uuid.UUID) bool, purge bool) int64 {
+	s.revisions.mu.Lock()
+	defer s.revisions.mu.Unlock()
+
+	// newer counts the revisions of every item seen so far walking from the newest.
+	newer := make(map[uuid.UUID]int)
+	// expired marks the revisions past the kept ones.
+	expired := make([]bool, len(s.revisions.rows))
+	var n int64
+	for i := len(s.revisions.rows) - 1; i >= 0; i-- {
+		e := s.revisions.rows[i]
+		id := s.keys.id(e)
+		if !inScope(s.keys.userID(e), id) {
+			continue
+		}
+		newer[id]++
+		if newer[id] > keep+1 {
+			expired[i] = true
+			n++
+		}
+	}
+	if purge {
+		kept := s.revisions.rows[:0]
+		for i, e := range s.revisions.rows {
+			if !expired[i] {
+				kept = append(kept, e)
+			}
+		}
+		clear(s.revisions.rows[len(kept):])
+		s.revisions.rows = kept
+	}
+	return n
+}
+
+// RetentionRepository keeps retention overrides in memory and enforces retention on the in-memory items and
+// item reveals.
+type RetentionRepository struct {
+	// overrides holds the stored overrides.
+	overrides table[retention.Override]
+	// versioned lists the storage of the items whose replaced revisions are subject to retention.
+	versioned []expirable
+	// reveals holds the item reveals subject to retention.
+	reveals *ItemRevealRepository
+}
+
+// NewRetentionRepository creates a new RetentionRepository without overrides over the in-memory repositories
+// holding the items and their reveals.
+func NewRetentionRepository(
+	cards *BankCardRepository,
+	credentials *CredentialRepository,
+	notes *NoteRepository,
+	reveals *ItemRevealRepository,
+) *RetentionRepository {
+	return &RetentionRepository{
+		versioned: []expirable{&cards.cards, &credentials.credentials, &notes.notes},
+		reveals:   reveals,
+	}
+}
+
+// Save creates the retention override or updates the existing override of the same group.
+func (r *RetentionRepository) Save(_ context.Context, params repositoryRetention.SaveParams) error {
+	e := params.Entity
+	r.overrides.put(e, func(o *retention.Override) bool { return o.GroupID == e.GroupID }, nil)
+	return nil
+}
+
+// Load retrieves every retention override ordered by group ID.
+func (r *RetentionRepository) Load(context.Context) ([]*retention.Override, error) {
+	overrides := r.overrides.filter(func(*retention.Override) bool { return true })
+	slices.SortFunc(overrides, func(a, b *retention.Override) int { return compareIDs(a.GroupID, b.GroupID) })
+	return overrides, nil
+}
+
+// Delete removes the retention override of the group.
+func (r *RetentionRepository) Delete(_ context.Context, params repositoryRetention.DeleteParams) error {
+	if r.overrides.remove(func(o *retention.Override) bool { return o.GroupID == params.GroupID }) == 0 {
+		return repositoryRetention.ErrOverrideNotFound
+	}
+	return nil
+}
+
+// Count returns the amount of data in the scope the retention rules do not keep, without deleting it.
+func (r *RetentionRepository) Count(
+	_ context.Context,
+	params repositoryRetention.ScopeParams,
+) (retention.Counts, error) {
+	return r.apply(params, false), nil
+}
+
+// Purge deletes the data in the scope the retention rules do not keep and returns the amount deleted.
+func (r *RetentionRepository) Purge(
+	_ context.Context,
+	params repositoryRetention.ScopeParams,
+) (retention.Counts, error) {
+	return r.apply(params, true), nil
+}
+
+// apply counts or, when purge is set, deletes the revisions and item reveals the retention rules do not keep.
+func (r *RetentionRepository) apply(params repositoryRetention.ScopeParams, purge bool) retention.Counts {
+	inScope := func(userID, itemID uuid.UUID) bool {
+		if slices.Contains(params.ExcludeItemIDs, itemID) {
+			return false
+		}
+		switch {
+		case params.UserIDs != nil:
+			return slices.Contains(params.UserIDs, userID)
+		case params.ExcludeUserIDs != nil:
+			return !slices.Contains(params.ExcludeUserIDs, userID)
+		default:
+			return true
+		}
+	}
+
+	// counts accumulates the affected data.
+	var counts retention.Counts
+	if params.KeepVersions > 0 {
+		for _, v := range r.versioned {
+			counts.Versions += v.expire(params.KeepVersions, inScope, purge)
+		}
+	}
+	if !params.AuditBefore.IsZero() {
+		expired := func(e *itemaccess.Reveal) bool {
+			return inScope(e.UserID, e.ItemID) && e.RevealedAt.Before(params.AuditBefore)
+		}
+		if purge {
+			counts.AuditRecords = int64(r.reveals.reveals.remove(expired))
+		} else {
+			counts.AuditRecords = int64(len(r.reveals.reveals.filter(expired)))
+		}
+	}
+	return counts
+}
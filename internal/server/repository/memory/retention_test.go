@@ -0,0 +1,103 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/itemaccess"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/note"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/retention"
+	repositoryItemaccess "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/itemaccess"
+	repositoryNote "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/note"
+	repositoryRetention "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/retention"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetentionRepository_Overrides(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	groupID := uuid.New()
+	repo := NewRetentionRepository(NewBankCardRepository(), NewCredentialRepository(), NewNoteRepository(),
+		NewItemRevealRepository())
+
+	for _, days := range []int{30, 90} {
+		err := repo.Save(ctx, repositoryRetention.SaveParams{Entity: &retention.Override{
+			GroupID: groupID,
+			Policy:  retention.Policy{KeepAuditDays: days},
+		}})
+		require.NoError(t, err)
+	}
+	overrides, err := repo.Load(ctx)
+	require.NoError(t, err)
+	require.Len(t, overrides, 1)
+	assert.Equal(t, 90, overrides[0].KeepAuditDays)
+
+	require.NoError(t, repo.Delete(ctx, repositoryRetention.DeleteParams{GroupID: groupID}))
+	err = repo.Delete(ctx, repositoryRetention.DeleteParams{GroupID: groupID})
+	assert.ErrorIs(t, err, repositoryRetention.ErrOverrideNotFound)
+}
+
+func TestRetentionRepository_Purge(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	userID, otherID := uuid.New(), uuid.New()
+	noteID, otherNoteID := uuid.New(), uuid.New()
+	t0 := time.Date(2026, time.October, 1, 12, 0, 0, 0, time.UTC)
+
+	notes := NewNoteRepository()
+	for i := range 4 {
+		for _, n := range []*note.Note{
+			{ID: noteID, UserID: userID, UpdatedAt: t0.Add(time.Duration(i) * time.Hour)},
+			{ID: otherNoteID, UserID: otherID, UpdatedAt: t0.Add(time.Duration(i) * time.Hour)},
+		} {
+			require.NoError(t, notes.Save(ctx, repositoryNote.SaveParams{Entity: n}))
+		}
+	}
+	reveals := NewItemRevealRepository()
+	for _, r := range []*itemaccess.Reveal{
+		{ID: uuid.New(), ItemID: noteID, UserID: userID, RevealedAt: t0},
+		{ID: uuid.New(), ItemID: noteID, UserID: userID, RevealedAt: t0.AddDate(0, 0, 10)},
+		{ID: uuid.New(), ItemID: otherNoteID, UserID: otherID, RevealedAt: t0},
+	} {
+		require.NoError(t, reveals.Save(ctx, repositoryItemaccess.SaveParams{Entity: r}))
+	}
+	repo := NewRetentionRepository(NewBankCardRepository(), NewCredentialRepository(), notes, reveals)
+	params := repositoryRetention.ScopeParams{
+		AuditBefore:    t0.AddDate(0, 0, 1),
+		ExcludeUserIDs: []uuid.UUID{otherID},
+		KeepVersions:   1,
+	}
+
+	held := params
+	held.ExcludeItemIDs = []uuid.UUID{noteID}
+	counts, err := repo.Count(ctx, held)
+	require.NoError(t, err)
+	assert.Equal(t, retention.Counts{}, counts, "excluded items are kept")
+
+	counts, err = repo.Count(ctx, params)
+	require.NoError(t, err)
+	assert.Equal(t, retention.Counts{Versions: 2, AuditRecords: 1}, counts)
+
+	counts, err = repo.Purge(ctx, params)
+	require.NoError(t, err)
+	assert.Equal(t, retention.Counts{Versions: 2, AuditRecords: 1}, counts)
+
+	counts, err = repo.Count(ctx, params)
+	require.NoError(t, err)
+	assert.Equal(t, retention.Counts{}, counts)
+	current, err := notes.Load(ctx, repositoryNote.LoadParams{ID: noteID, UserID: userID})
+	require.NoError(t, err)
+	require.Len(t, current, 1)
+	assert.Equal(t, t0.Add(3*time.Hour), current[0].UpdatedAt)
+	old, err := notes.Load(ctx, repositoryNote.LoadParams{ID: noteID, UserID: userID, AsOf: t0.Add(time.Hour)})
+	require.NoError(t, err)
+	assert.Empty(t, old, "expired revisions are purged")
+	other, err := notes.Load(ctx, repositoryNote.LoadParams{ID: otherNoteID, UserID: otherID, AsOf: t0})
+	require.NoError(t, err)
+	assert.Len(t, other, 1, "revisions out of scope are kept")
+}
@@ -0,0 +1,52 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/rotation"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/rotation"
+	"github.com/google/uuid"
+)
+
+// RotationRepository keeps credential rotation policies in memory.
+type RotationRepository struct {
+	// policies holds the stored policies.
+	policies table[rotation.Policy]
+}
+
+// NewRotationRepository creates a new empty RotationRepository.
+func NewRotationRepository() *RotationRepository {
+	return &RotationRepository{}
+}
+
+// Save creates the rotation policy of a credential or replaces the existing one.
+func (r *RotationRepository) Save(_ context.Context, params repository.SaveParams) error {
+	e := params.Entity
+	r.policies.put(e, func(p *rotation.Policy) bool { return p.CredentialID == e.CredentialID }, nil)
+	return nil
+}
+
+// Load retrieves the rotation policies matching the provided parameters ordered by creation time.
+func (r *RotationRepository) Load(_ context.Context, params repository.LoadParams) ([]*rotation.Policy, error) {
+	policies := r.policies.filter(func(p *rotation.Policy) bool {
+		return (params.CredentialID == uuid.Nil || p.CredentialID == params.CredentialID) &&
+			(params.UserID == uuid.Nil || p.UserID == params.UserID) &&
+			(params.DueAt.IsZero() || (p.Interval > 0 && !p.NextRotationAt.After(params.DueAt)))
+	})
+	slices.SortFunc(policies, func(a, b *rotation.Policy) int {
+		return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), compareIDs(a.CredentialID, b.CredentialID))
+	})
+	return policies, nil
+}
+
+// Delete removes the rotation policy of the credential of the owner.
+func (r *RotationRepository) Delete(_ context.Context, params repository.DeleteParams) error {
+	if r.policies.remove(func(p *rotation.Policy) bool {
+		return p.CredentialID == params.CredentialID && p.UserID == params.UserID
+	}) == 0 {
+		return repository.ErrPolicyNotFound
+	}
+	return nil
+}
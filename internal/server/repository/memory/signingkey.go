@@ -0,0 +1,57 @@
+package memory
+
+import (
+	"context"
+	"slices"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/signingkey"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/signingkey"
+	"github.com/google/uuid"
+)
+
+// SigningKeyRepository keeps request signing keys in memory. Secrets are stored as is, without encryption.
+type SigningKeyRepository struct {
+	// keys holds the stored signing keys.
+	keys table[signingkey.SigningKey]
+}
+
+// NewSigningKeyRepository creates a new empty SigningKeyRepository.
+func NewSigningKeyRepository() *SigningKeyRepository {
+	return &SigningKeyRepository{}
+}
+
+// Save stores a new signing key.
+func (r *SigningKeyRepository) Save(_ context.Context, params repository.SaveParams) error {
+	r.keys.add(params.Entity)
+	return nil
+}
+
+// Load retrieves the signing keys of the user, or only the one with the ID when set, ordered by creation time.
+// Loading by ID returns ErrSigningKeyNotFound when the key does not exist.
+func (r *SigningKeyRepository) Load(_ context.Context, params repository.LoadParams) ([]*signingkey.SigningKey, error) {
+	keys := r.keys.filter(func(k *signingkey.SigningKey) bool {
+		return k.UserID == params.UserID && (params.ID == uuid.Nil || k.ID == params.ID)
+	})
+	if params.ID != uuid.Nil && len(keys) == 0 {
+		return nil, repository.ErrSigningKeyNotFound
+	}
+	slices.SortFunc(keys, func(a, b *signingkey.SigningKey) int {
+		return compareCreated(a.CreatedAt, b.CreatedAt, a.ID, b.ID)
+	})
+	return keys, nil
+}
+
+// Exists reports whether the user has any signing key.
+func (r *SigningKeyRepository) Exists(_ context.Context, params repository.ExistsParams) (bool, error) {
+	return len(r.keys.filter(func(k *signingkey.SigningKey) bool { return k.UserID == params.UserID })) > 0, nil
+}
+
+// Delete removes the signing key with the ID of the user.
+func (r *SigningKeyRepository) Delete(_ context.Context, params repository.DeleteParams) error {
+	if r.keys.remove(func(k *signingkey.SigningKey) bool {
+		return k.ID == params.ID && k.UserID == params.UserID
+	}) == 0 {
+		return repository.ErrSigningKeyNotFound
+	}
+	return nil
+}
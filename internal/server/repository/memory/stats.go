@@ -0,0 +1,113 @@
+package memory
+
+import (
+	"context"
+	"maps"
+	"slices"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/auth"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/device"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/filedata"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/stats"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/stats"
+	"github.com/google/uuid"
+)
+
+// StatsRepository aggregates the usage of the in-memory repositories and keeps usage rollups in memory.
+type StatsRepository struct {
+	// users provides the registered users.
+	users *UserRepository
+	// devices provides the sign-in times of the users.
+	devices *DeviceRepository
+	// credentials provides the stored credentials.
+	credentials *CredentialRepository
+	// notes provides the stored notes.
+	notes *NoteRepository
+	// cards provides the stored bank cards.
+	cards *BankCardRepository
+	// files provides the stored file metadata.
+	files *FileDataRepository
+	// rollups holds the stored rollups.
+	rollups table[stats.Rollup]
+}
+
+// NewStatsRepository creates a new StatsRepository aggregating the usage of the provided repositories.
+func NewStatsRepository(
+	users *UserRepository,
+	devices *DeviceRepository,
+	credentials *CredentialRepository,
+	notes *NoteRepository,
+	cards *BankCardRepository,
+	files *FileDataRepository,
+) *StatsRepository {
+	return &StatsRepository{
+		users:       users,
+		devices:     devices,
+		credentials: credentials,
+		notes:       notes,
+		cards:       cards,
+		files:       files,
+	}
+}
+
+// Collect returns a rollup holding the aggregate usage of the repositories at the moment. Request counters are
+// left zero.
+func (r *StatsRepository) Collect(_ context.Context, params repository.CollectParams) (*stats.Rollup, error) {
+	e := stats.Rollup{RolledUpAt: params.At}
+	e.Users = int64(len(r.users.users.filter(func(u *auth.User) bool { return u.ProfileOf == uuid.Nil })))
+
+	dayStart, monthStart := params.At.Add(-stats.DailyWindow), params.At.Add(-stats.MonthlyWindow)
+	daily, monthly := make(map[uuid.UUID]struct{}), make(map[uuid.UUID]struct{})
+	for _, d := range r.devices.devices.filter(func(d *device.Device) bool { return !d.LastSeenAt.Before(monthStart) }) {
+		monthly[d.UserID] = struct{}{}
+		if !d.LastSeenAt.Before(dayStart) {
+			daily[d.UserID] = struct{}{}
+		}
+	}
+	e.DailyActiveUsers, e.MonthlyActiveUsers = int64(len(daily)), int64(len(monthly))
+
+	usage := make(map[uuid.UUID]int64)
+	files := r.files.files.filter(func(*filedata.FileData) bool { return true })
+	for _, f := range files {
+		usage[f.UserID] += f.Size
+		e.StorageBytes += f.Size
+	}
+
+	e.Items = map[string]int64{
+		stats.ItemCredentials: r.credentials.credentials.count(uuid.Nil),
+		stats.ItemNotes:       r.notes.notes.count(uuid.Nil),
+		stats.ItemBankCards:   r.cards.cards.count(uuid.Nil),
+		stats.ItemFiles:       int64(len(files)),
+	}
+	e.StorageTiers = stats.Distribution(e.Users, slices.Collect(maps.Values(usage)))
+	return &e, nil
+}
+
+// Save stores the rollup.
+func (r *StatsRepository) Save(_ context.Context, params repository.SaveParams) error {
+	e := clone(params.Entity)
+	e.Items = maps.Clone(e.Items)
+	e.StorageTiers = maps.Clone(e.StorageTiers)
+	r.rollups.add(e)
+	return nil
+}
+
+// Load retrieves the rollups taken in the period, oldest first.
+func (r *StatsRepository) Load(_ context.Context, params repository.LoadParams) ([]*stats.Rollup, error) {
+	rollups := r.rollups.filter(func(e *stats.Rollup) bool {
+		return !e.RolledUpAt.Before(params.From) && e.RolledUpAt.Before(params.To)
+	})
+	for _, e := range rollups {
+		e.Items = maps.Clone(e.Items)
+		e.StorageTiers = maps.Clone(e.StorageTiers)
+	}
+	slices.SortFunc(rollups, func(a, b *stats.Rollup) int {
+		return compareCreated(a.RolledUpAt, b.RolledUpAt, a.ID, b.ID)
+	})
+	return rollups, nil
+}
+
+// Prune deletes the rollups taken before the moment and returns the number of deleted rollups.
+func (r *StatsRepository) Prune(_ context.Context, params repository.PruneParams) (int64, error) {
+	return int64(r.rollups.remove(func(e *stats.Rollup) bool { return e.RolledUpAt.Before(params.Before) })), nil
+}
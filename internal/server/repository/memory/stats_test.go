@@ -0,0 +1,100 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/auth"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/device"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/filedata"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/note"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/stats"
+	repositoryAuth "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/auth"
+	repositoryDevice "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/device"
+	repositoryFiledata "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/filedata"
+	repositoryNote "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/note"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/stats"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatsRepository_Collect(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	users, devices := NewUserRepository(), NewDeviceRepository()
+	notes, files := NewNoteRepository(), NewFileDataRepository()
+	repo := NewStatsRepository(users, devices, NewCredentialRepository(), notes, NewBankCardRepository(), files)
+
+	alice, bob := uuid.New(), uuid.New()
+	for _, u := range []*auth.User{
+		{ID: alice, Login: "alice"},
+		{ID: bob, Login: "bob"},
+		{ID: uuid.New(), Login: "alice-decoy", ProfileOf: alice},
+	} {
+		require.NoError(t, users.Save(ctx, repositoryAuth.SaveParams{Entity: u}))
+	}
+	for _, d := range []*device.Device{
+		{ID: uuid.New(), UserID: alice, LastSeenAt: now.Add(-time.Hour)},
+		{ID: uuid.New(), UserID: alice, LastSeenAt: now.Add(-10 * 24 * time.Hour)},
+		{ID: uuid.New(), UserID: bob, LastSeenAt: now.Add(-10 * 24 * time.Hour)},
+	} {
+		require.NoError(t, devices.Save(ctx, repositoryDevice.SaveParams{Entity: d}))
+	}
+	noteID := uuid.New()
+	for _, n := range []*note.Note{
+		{ID: noteID, UserID: alice, UpdatedAt: now.Add(-time.Hour)},
+		{ID: noteID, UserID: alice, UpdatedAt: now},
+	} {
+		require.NoError(t, notes.Save(ctx, repositoryNote.SaveParams{Entity: n}))
+	}
+	require.NoError(t, files.Save(ctx, repositoryFiledata.SaveParams{
+		Entity: &filedata.FileData{ID: uuid.New(), UserID: bob, Size: 2 << 20},
+	}))
+
+	got, err := repo.Collect(ctx, repository.CollectParams{At: now})
+
+	require.NoError(t, err)
+	assert.Equal(t, now, got.RolledUpAt)
+	assert.Equal(t, int64(2), got.Users)
+	assert.Equal(t, int64(1), got.DailyActiveUsers)
+	assert.Equal(t, int64(2), got.MonthlyActiveUsers)
+	assert.Equal(t, int64(2<<20), got.StorageBytes)
+	assert.Equal(t, map[string]int64{
+		stats.ItemCredentials: 0,
+		stats.ItemNotes:       1,
+		stats.ItemBankCards:   0,
+		stats.ItemFiles:       1,
+	}, got.Items)
+	assert.Equal(t, int64(1), got.StorageTiers["under_1mib"])
+	assert.Equal(t, int64(1), got.StorageTiers["under_10mib"])
+}
+
+func TestStatsRepository_SaveLoadPrune(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	repo := NewStatsRepository(nil, nil, nil, nil, nil, nil)
+	for _, at := range []time.Time{now, now.Add(-2 * time.Hour), now.Add(-time.Hour)} {
+		require.NoError(t, repo.Save(ctx, repository.SaveParams{Entity: &stats.Rollup{ID: uuid.New(), RolledUpAt: at}}))
+	}
+
+	loaded, err := repo.Load(ctx, repository.LoadParams{From: now.Add(-2 * time.Hour), To: now})
+	require.NoError(t, err)
+	require.Len(t, loaded, 2)
+	assert.Equal(t, now.Add(-2*time.Hour), loaded[0].RolledUpAt)
+	assert.Equal(t, now.Add(-time.Hour), loaded[1].RolledUpAt)
+
+	pruned, err := repo.Prune(ctx, repository.PruneParams{Before: now})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), pruned)
+
+	loaded, err = repo.Load(ctx, repository.LoadParams{From: now.Add(-time.Hour), To: now.Add(time.Hour)})
+	require.NoError(t, err)
+	require.Len(t, loaded, 1)
+	assert.Equal(t, now, loaded[0].RolledUpAt)
+}
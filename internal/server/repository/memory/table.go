@@ -0,0 +1,125 @@
+package memory
+
+import (
+	"bytes"
+	"cmp"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// table keeps the entities of one repository in insertion order.
+// Entities are copied on the way in and out, so callers wiping their copies leave the stored ones intact.
+type table[T any] struct {
+	// rows holds the stored entities.
+	rows []*T
+	// mu guards rows.
+	mu sync.Mutex
+}
+
+// filter returns copies of the stored entities matching keep in insertion order; nil when none match.
+func (t *table[T]) filter(keep func(e *T) bool) []*T {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// found collects the copies of the matching entities.
+	var found []*T
+	for _, e := range t.rows {
+		if keep(e) {
+			found = append(found, clone(e))
+		}
+	}
+	return found
+}
+
+// add appends a copy of e.
+func (t *table[T]) add(e *T) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.rows = append(t.rows, clone(e))
+}
+
+// put stores a copy of e in place of the entity matching same, or appends it when none matches.
+// It reports false without storing anything when another entity matches clash; clash may be nil.
+func (t *table[T]) put(e *T, same, clash func(stored *T) bool) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	idx := -1
+	for i, stored := range t.rows {
+		switch {
+		case same(stored):
+			idx = i
+		case clash != nil && clash(stored):
+			return false
+		}
+	}
+	if idx < 0 {
+		t.rows = append(t.rows, clone(e))
+		return true
+	}
+	t.rows[idx] = clone(e)
+	return true
+}
+
+// update applies fn to the stored entities matching match and returns how many matched.
+func (t *table[T]) update(match func(stored *T) bool, fn func(stored *T)) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// n counts the matching entities.
+	var n int
+	for _, stored := range t.rows {
+		if match(stored) {
+			fn(stored)
+			n++
+		}
+	}
+	return n
+}
+
+// remove deletes the stored entities matching match and returns how many were deleted.
+func (t *table[T]) remove(match func(stored *T) bool) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	kept := t.rows[:0]
+	for _, stored := range t.rows {
+		if !match(stored) {
+			kept = append(kept, stored)
+		}
+	}
+	n := len(t.rows) - len(kept)
+	clear(t.rows[len(kept):])
+	t.rows = kept
+	return n
+}
+
+// clone returns a copy of an entity whose slice fields do not share memory with the original.
+// Entity slices hold values only, so copying them one level deep detaches the copy completely.
+// Only exported fields are copied this way, which covers the domain entities.
+func clone[T any](e *T) *T {
+	c := *e
+	v := reflect.ValueOf(&c).Elem()
+	for i := range v.NumField() {
+		f := v.Field(i)
+		if f.Kind() == reflect.Slice && !f.IsNil() && f.CanSet() {
+			f.Set(reflect.AppendSlice(reflect.MakeSlice(f.Type(), 0, f.Len()), f))
+		}
+	}
+	return &c
+}
+
+// compareCreated orders entities by creation time and then by identifier, like the "ORDER BY created_at, id"
+// clause of the PostgreSQL repositories.
+func compareCreated(at, bt time.Time, aID, bID uuid.UUID) int {
+	return cmp.Or(at.Compare(bt), compareIDs(aID, bID))
+}
+
+// compareIDs orders identifiers bytewise, like PostgreSQL orders uuid values.
+func compareIDs(a, b uuid.UUID) int {
+	return bytes.Compare(a[:], b[:])
+}
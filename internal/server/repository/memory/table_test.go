@@ -0,0 +1,112 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// row is a test entity of the table; like the domain entities, it exports its fields.
+type row struct {
+	// Key identifies the row.
+	Key string
+	// Data holds the row content.
+	Data []byte
+	// Group groups rows for clash checks.
+	Group int
+}
+
+func TestTable_CopiesEntities(t *testing.T) {
+	t.Parallel()
+
+	// tbl holds the rows under test.
+	var tbl table[row]
+	stored := &row{Key: "a", Data: []byte("secret")}
+	tbl.add(stored)
+	clear(stored.Data)
+
+	loaded := tbl.filter(func(*row) bool { return true })
+	assert.Equal(t, []byte("secret"), loaded[0].Data, "wiping the saved entity must not touch the stored copy")
+
+	clear(loaded[0].Data)
+	assert.Equal(t, []byte("secret"), tbl.filter(func(*row) bool { return true })[0].Data,
+		"wiping a loaded entity must not touch the stored copy")
+}
+
+func TestTable_Put(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		entity   *row
+		name     string
+		wantKeys []string
+		wantOK   bool
+	}{
+		{name: "insert", entity: &row{Key: "c", Group: 3}, wantOK: true, wantKeys: []string{"a", "b", "c"}},
+		{name: "replace", entity: &row{Key: "a", Group: 1}, wantOK: true, wantKeys: []string{"a", "b"}},
+		{name: "clash", entity: &row{Key: "c", Group: 2}, wantOK: false, wantKeys: []string{"a", "b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// tbl holds the rows under test.
+			var tbl table[row]
+			tbl.add(&row{Key: "a", Group: 1})
+			tbl.add(&row{Key: "b", Group: 2})
+
+			ok := tbl.put(tt.entity,
+				func(r *row) bool { return r.Key == tt.entity.Key },
+				func(r *row) bool { return r.Group == tt.entity.Group },
+			)
+			assert.Equal(t, tt.wantOK, ok)
+
+			// keys collects the stored keys.
+			var keys []string
+			for _, r := range tbl.filter(func(*row) bool { return true }) {
+				keys = append(keys, r.Key)
+			}
+			assert.Equal(t, tt.wantKeys, keys)
+		})
+	}
+}
+
+func TestTable_Remove(t *testing.T) {
+	t.Parallel()
+
+	// tbl holds the rows under test.
+	var tbl table[row]
+	for _, key := range []string{"a", "b", "c"} {
+		tbl.add(&row{Key: key, Group: len(key)})
+	}
+
+	assert.Equal(t, 1, tbl.remove(func(r *row) bool { return r.Key == "b" }))
+	assert.Equal(t, 0, tbl.remove(func(r *row) bool { return r.Key == "b" }))
+	assert.Len(t, tbl.filter(func(*row) bool { return true }), 2)
+	assert.Nil(t, tbl.filter(func(r *row) bool { return r.Key == "b" }))
+}
+
+func TestPage(t *testing.T) {
+	t.Parallel()
+
+	items := []int{1, 2, 3, 4, 5}
+	tests := []struct {
+		name   string
+		want   []int
+		offset int
+		limit  int
+	}{
+		{name: "first page", offset: 0, limit: 2, want: []int{1, 2}},
+		{name: "last page", offset: 4, limit: 2, want: []int{5}},
+		{name: "past the end", offset: 10, limit: 2, want: nil},
+		{name: "negative offset", offset: -1, limit: 1, want: []int{1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, page(items, tt.offset, tt.limit))
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/dryrun"
+	"github.com/google/uuid"
+)
+
+// UnitOfWork runs units of work against the in-memory repositories. Memory has no transactions, so the
+// operations of a failed unit are not rolled back.
+type UnitOfWork struct{}
+
+// NewUnitOfWork creates a new UnitOfWork.
+func NewUnitOfWork() *UnitOfWork {
+	return &UnitOfWork{}
+}
+
+// Do executes fn directly.
+func (u *UnitOfWork) Do(ctx context.Context, _ uuid.UUID, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// DryRun reports dryrun.ErrUnsupported, since memory cannot roll back the changes of fn.
+func (u *UnitOfWork) DryRun(context.Context, uuid.UUID, func(ctx context.Context) error) error {
+	return dryrun.ErrUnsupported
+}
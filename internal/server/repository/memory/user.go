@@ -0,0 +1,90 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/auth"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/auth"
+	"github.com/google/uuid"
+)
+
+// UserRepository keeps users in memory. Crypto keys are stored as is, without master key encryption.
+type UserRepository struct {
+	// users holds the stored users.
+	users table[auth.User]
+}
+
+// NewUserRepository creates a new empty UserRepository.
+func NewUserRepository() *UserRepository {
+	return &UserRepository{}
+}
+
+// Save creates or updates a user; logins are unique.
+func (r *UserRepository) Save(_ context.Context, params repository.SaveParams) error {
+	e := params.Entity
+	if !r.users.put(e,
+		func(u *auth.User) bool { return u.ID == e.ID },
+		func(u *auth.User) bool { return u.Login == e.Login },
+	) {
+		return repository.ErrUserAlreadyExists
+	}
+	return nil
+}
+
+// Load retrieves the user matching the ID and the login, whichever are set. Decoy vault profiles are
+// loaded by ID only.
+func (r *UserRepository) Load(_ context.Context, params repository.LoadParams) (*auth.User, error) {
+	if params.ID == uuid.Nil && params.Login == "" {
+		return nil, errors.New("at least one of ID or Login must be provided")
+	}
+	users := r.users.filter(func(u *auth.User) bool {
+		return (params.ID == uuid.Nil || u.ID == params.ID) &&
+			(params.Login == "" || u.Login == params.Login && !u.IsDuressVault())
+	})
+	if len(users) == 0 {
+		return nil, repository.ErrUserNotFound
+	}
+	return users[0], nil
+}
+
+// ListIDs returns the identifiers of all registered users.
+func (r *UserRepository) ListIDs(_ context.Context) ([]uuid.UUID, error) {
+	// ids collects the user identifiers.
+	var ids []uuid.UUID
+	for _, u := range r.users.filter(func(*auth.User) bool { return true }) {
+		ids = append(ids, u.ID)
+	}
+	slices.SortFunc(ids, compareIDs)
+	return ids, nil
+}
+
+// List returns a page of provisioned users ordered by login, without their crypto keys, and the number
+// of users matching the parameters. Deprovisioned users and decoy vault profiles are excluded.
+func (r *UserRepository) List(_ context.Context, params repository.ListParams) ([]*auth.User, int, error) {
+	users := r.users.filter(func(u *auth.User) bool {
+		return u.DeprovisionedAt.IsZero() && !u.IsDuressVault() &&
+			(params.Login == "" || u.Login == params.Login) &&
+			(params.ExternalID == "" || u.ExternalID == params.ExternalID)
+	})
+	total := len(users)
+	if total == 0 || params.Limit <= 0 {
+		return nil, total, nil
+	}
+	slices.SortFunc(users, func(a, b *auth.User) int { return strings.Compare(a.Login, b.Login) })
+	for _, u := range users {
+		u.CryptoKey = nil
+	}
+	return page(users, params.Offset, params.Limit), total, nil
+}
+
+// page returns the window of items starting at offset with at most limit items; nil when the window is empty.
+func page[T any](items []T, offset, limit int) []T {
+	offset = min(max(offset, 0), len(items))
+	if window := items[offset:min(offset+limit, len(items))]; len(window) > 0 {
+		return window
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/auth"
+	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/auth"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserRepository_Save(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	repo := NewUserRepository()
+	id := uuid.New()
+	require.NoError(t, repo.Save(ctx, repository.SaveParams{Entity: &auth.User{ID: id, Login: "alice"}}))
+
+	tests := []struct {
+		wantErr error
+		user    *auth.User
+		name    string
+	}{
+		{name: "update", user: &auth.User{ID: id, Login: "alice", Active: true}},
+		{name: "rename", user: &auth.User{ID: id, Login: "alice2"}},
+		{name: "login taken", user: &auth.User{ID: uuid.New(), Login: "alice2"}, wantErr: repository.ErrUserAlreadyExists},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := repo.Save(ctx, repository.SaveParams{Entity: tt.user})
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+
+	ids, err := repo.ListIDs(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{id}, ids)
+}
+
+func TestUserRepository_List(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	repo := NewUserRepository()
+	for _, u := range []*auth.User{
+		{ID: uuid.New(), Login: "carol", CryptoKey: []byte("key")},
+		{ID: uuid.New(), Login: "alice", CryptoKey: []byte("key")},
+		{ID: uuid.New(), Login: "bob", DeprovisionedAt: time.Now()},
+		{ID: uuid.New(), Login: "duress:decoy", ProfileOf: uuid.New()},
+	} {
+		require.NoError(t, repo.Save(ctx, repository.SaveParams{Entity: u}))
+	}
+
+	users, total, err := repo.List(ctx, repository.ListParams{Limit: 1, Offset: 1})
+	require.NoError(t, err)
+	assert.Equal(t, 2, total)
+	require.Len(t, users, 1)
+	assert.Equal(t, "carol", users[0].Login)
+	assert.Nil(t, users[0].CryptoKey)
+
+	_, err = repo.Load(ctx, repository.LoadParams{Login: "dave"})
+	assert.ErrorIs(t, err, repository.ErrUserNotFound)
+	_, err = repo.Load(ctx, repository.LoadParams{Login: "duress:decoy"})
+	assert.ErrorIs(t, err, repository.ErrUserNotFound, "decoy vault profiles are not loaded by login")
+}
@@ -0,0 +1,122 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// PostgreSQL error codes of transaction failures that succeed when the transaction is re-run.
+const (
+	// sqlStateSerializationFailure reports a transaction that conflicted with a concurrent one.
+	sqlStateSerializationFailure = "40001"
+	// sqlStateDeadlockDetected reports a transaction aborted to break a deadlock.
+	sqlStateDeadlockDetected = "40P01"
+)
+
+// RetryPolicy defines how operations failing with transient transaction errors are re-run.
+type RetryPolicy struct {
+	// Backoff specifies the delay before the first retry, doubled for every further retry.
+	Backoff time.Duration
+	// MaxRetries specifies how often a failed operation is re-run (0 disables retries).
+	MaxRetries int
+}
+
+// RetriesExhaustedError reports an operation that still failed with a transient transaction error
+// after all retries allowed by the policy.
+type RetriesExhaustedError struct {
+	// Err is the error of the last attempt.
+	Err error
+	// Attempts is the number of times the operation was run.
+	Attempts int
+}
+
+// Error returns the error message including the number of attempts.
+func (e *RetriesExhaustedError) Error() string {
+	return fmt.Sprintf("transaction retries exhausted after %d attempts: %v", e.Attempts, e.Err)
+}
+
+// Unwrap returns the error of the last attempt.
+func (e *RetriesExhaustedError) Unwrap() error {
+	return e.Err
+}
+
+// retryCtxKey marks contexts of operations already run under a retry loop.
+type retryCtxKey struct{}
+
+// IsRetryable reports whether err is a PostgreSQL serialization failure or deadlock.
+func IsRetryable(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+	return pgErr.Code == sqlStateSerializationFailure || pgErr.Code == sqlStateDeadlockDetected
+}
+
+// Retry runs op and re-runs it with exponential backoff while it fails with a retryable error.
+// op must run a whole transaction, so every attempt starts afresh. Nested calls run op once and leave
+// retrying to the outermost call, even one with retries disabled, since a transaction cannot be
+// resumed after a failed statement.
+// When the retries run out, the last error is returned wrapped in a RetriesExhaustedError.
+func Retry(ctx context.Context, policy RetryPolicy, op func(ctx context.Context) error) error {
+	if ctx.Value(retryCtxKey{}) != nil {
+		return op(ctx)
+	}
+	ctx = context.WithValue(ctx, retryCtxKey{}, struct{}{})
+	if policy.MaxRetries <= 0 {
+		return op(ctx)
+	}
+
+	delay := policy.Backoff
+	for attempt := 1; ; attempt++ {
+		err := op(ctx)
+		if err == nil || !IsRetryable(err) {
+			return err
+		}
+		if attempt > policy.MaxRetries {
+			return &RetriesExhaustedError{Err: err, Attempts: attempt}
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return errors.Join(err, ctx.Err())
+		case <-timer.C:
+		}
+		delay *= 2
+	}
+}
+
+// RetryExecMw creates middleware that re-runs failed operations returning only an error per the policy.
+// Repositories apply it first in their chains, so it wraps the transaction of the row-level security scope and an
+// operation failing on a serialization conflict or deadlock is re-run with a fresh transaction.
+func RetryExecMw[F ~func(context.Context, P) error, P any](policy RetryPolicy) Middleware[F] {
+	return func(next F) F {
+		return func(ctx context.Context, p P) error {
+			return Retry(ctx, policy, func(ctx context.Context) error {
+				return next(ctx, p)
+			})
+		}
+	}
+}
+
+// RetryQueryMw creates middleware that re-runs failed operations returning a result per the policy.
+// It is applied like RetryExecMw.
+func RetryQueryMw[F ~func(context.Context, P) (R, error), P, R any](policy RetryPolicy) Middleware[F] {
+	return func(next F) F {
+		return func(ctx context.Context, p P) (R, error) {
+			// result holds the outcome of the last attempt.
+			var result R
+			err := Retry(ctx, policy, func(ctx context.Context) error {
+				var err error
+				result, err = next(ctx, p)
+				return err
+			})
+			return result, err
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errTestPermanent = errors.New("permanent failure")
+
+func TestIsRetryable(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		err  error
+		name string
+		want bool
+	}{
+		{name: "serialization failure", err: &pgconn.PgError{Code: "40001"}, want: true},
+		{name: "deadlock", err: fmt.Errorf("save failed: %w", &pgconn.PgError{Code: "40P01"}), want: true},
+		{name: "unique violation", err: &pgconn.PgError{Code: "23505"}, want: false},
+		{name: "other error", err: errTestPermanent, want: false},
+		{name: "nil error", err: nil, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, IsRetryable(tt.err))
+		})
+	}
+}
+
+func TestRetry(t *testing.T) {
+	t.Parallel()
+
+	serialization := &pgconn.PgError{Code: "40001"}
+
+	tests := []struct {
+		errs         []error
+		wantErr      error
+		name         string
+		policy       RetryPolicy
+		wantAttempts int
+		wantExhaust  bool
+	}{
+		{
+			name:         "success on first attempt",
+			policy:       RetryPolicy{MaxRetries: 3},
+			errs:         []error{nil},
+			wantAttempts: 1,
+		},
+		{
+			name:         "success after retries",
+			policy:       RetryPolicy{MaxRetries: 3, Backoff: time.Millisecond},
+			errs:         []error{serialization, &pgconn.PgError{Code: "40P01"}, nil},
+			wantAttempts: 3,
+		},
+		{
+			name:         "permanent error not retried",
+			policy:       RetryPolicy{MaxRetries: 3},
+			errs:         []error{errTestPermanent},
+			wantErr:      errTestPermanent,
+			wantAttempts: 1,
+		},
+		{
+			name:         "retries exhausted",
+			policy:       RetryPolicy{MaxRetries: 2},
+			errs:         []error{serialization, serialization, serialization},
+			wantErr:      serialization,
+			wantAttempts: 3,
+			wantExhaust:  true,
+		},
+		{
+			name:         "retries disabled",
+			policy:       RetryPolicy{},
+			errs:         []error{serialization},
+			wantErr:      serialization,
+			wantAttempts: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			attempts := 0
+			err := Retry(context.Background(), tt.policy, func(context.Context) error {
+				err := tt.errs[attempts]
+				attempts++
+				return err
+			})
+
+			assert.Equal(t, tt.wantAttempts, attempts)
+			if tt.wantErr == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, tt.wantErr)
+			var exhausted *RetriesExhaustedError
+			assert.Equal(t, tt.wantExhaust, errors.As(err, &exhausted))
+			if tt.wantExhaust {
+				assert.Equal(t, tt.wantAttempts, exhausted.Attempts)
+			}
+		})
+	}
+}
+
+func TestRetry_Nested(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		outer       RetryPolicy
+		wantRuns    int
+		wantExhaust bool
+	}{
+		{name: "outer retries", outer: RetryPolicy{MaxRetries: 2}, wantRuns: 3, wantExhaust: true},
+		{name: "outer retries disabled", outer: RetryPolicy{}, wantRuns: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			outer, inner := 0, 0
+			err := Retry(context.Background(), tt.outer, func(ctx context.Context) error {
+				outer++
+				return Retry(ctx, RetryPolicy{MaxRetries: 2}, func(context.Context) error {
+					inner++
+					return &pgconn.PgError{Code: "40001"}
+				})
+			})
+
+			var exhausted *RetriesExhaustedError
+			assert.Equal(t, tt.wantExhaust, errors.As(err, &exhausted))
+			assert.Equal(t, tt.wantRuns, outer)
+			assert.Equal(t, tt.wantRuns, inner)
+		})
+	}
+}
+
+func TestRetry_ContextCanceled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	attempts := 0
+	err := Retry(ctx, RetryPolicy{MaxRetries: 3, Backoff: time.Hour}, func(context.Context) error {
+		attempts++
+		cancel()
+		return &pgconn.PgError{Code: "40P01"}
+	})
+
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, attempts)
+}
+
+func TestRetryMw(t *testing.T) {
+	t.Parallel()
+
+	type execFunc func(ctx context.Context, p string) error
+	type queryFunc func(ctx context.Context, p string) (int, error)
+
+	policy := RetryPolicy{MaxRetries: 1}
+
+	execCalls := 0
+	exec := Chain(execFunc(func(_ context.Context, p string) error {
+		execCalls++
+		if execCalls == 1 {
+			return &pgconn.PgError{Code: "40001"}
+		}
+		assert.Equal(t, "params", p)
+		return nil
+	}), RetryExecMw[execFunc](policy))
+	require.NoError(t, exec(context.Background(), "params"))
+	assert.Equal(t, 2, execCalls)
+
+	queryCalls := 0
+	query := Chain(queryFunc(func(_ context.Context, p string) (int, error) {
+		queryCalls++
+		if queryCalls == 1 {
+			return 0, &pgconn.PgError{Code: "40P01"}
+		}
+		return len(p), nil
+	}), RetryQueryMw[queryFunc](policy))
+	n, err := query(context.Background(), "params")
+	require.NoError(t, err)
+	assert.Equal(t, 6, n)
+	assert.Equal(t, 2, queryCalls)
+}
@@ -4,11 +4,23 @@ import (
 	"context"
 	"fmt"
 
-	"github.com/gdyunin/aegis-vault-keeper/internal/server/crypto"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/note"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/fieldcrypt"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/keyprv"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/securebytes"
 )
 
+// itemType identifies note items in the ownership binding of encrypted fields.
+const itemType = "note"
+
+// BoundTable describes the notes table columns that may still hold fields sealed before
+// encrypted fields were bound to their owner and item.
+var BoundTable = fieldcrypt.BoundTable{
+	Name:     "notes",
+	ItemType: itemType,
+	Columns:  []string{"note", "description"},
+}
+
 // encryptionMw creates a middleware that encrypts note content before saving.
 // Both note content and description fields are encrypted using AES-GCM.
 func encryptionMw(keyProvider keyprv.UserKeyProvider) saveMw {
@@ -18,25 +30,59 @@ func encryptionMw(keyProvider keyprv.UserKeyProvider) saveMw {
 			if err != nil {
 				return fmt.Errorf("failed to provide user key: %w", err)
 			}
+			defer securebytes.Wipe(k)
 
-			copyEntity := *p.Entity
+			if p.Entity, err = sealNote(k, p.Entity); err != nil {
+				return err
+			}
+			return next(ctx, p)
+		}
+	}
+}
 
-			if copyEntity.Note, err = crypto.EncryptAESGCM(k, copyEntity.Note); err != nil {
-				return fmt.Errorf("failed to encrypt note: %w", err)
+// batchEncryptionMw creates a middleware that encrypts every note of a batch before saving.
+// The user key is provided once for the whole batch and the entities are sealed on the pipeline.
+func batchEncryptionMw(keyProvider keyprv.UserKeyProvider, pipeline *fieldcrypt.Pipeline) saveBatchMw {
+	return func(next saveBatchFunc) saveBatchFunc {
+		return func(ctx context.Context, p SaveBatchParams) error {
+			k, err := keyProvider.UserKeyProvide(ctx, p.UserID)
+			if err != nil {
+				return fmt.Errorf("failed to provide user key: %w", err)
 			}
+			defer securebytes.Wipe(k)
 
-			if copyEntity.Description, err = crypto.EncryptAESGCM(k, copyEntity.Description); err != nil {
-				return fmt.Errorf("failed to encrypt description: %w", err)
+			sealed, err := fieldcrypt.Map(ctx, pipeline, p.Entities, func(e *note.Note) (*note.Note, error) {
+				return sealNote(k, e)
+			})
+			if err != nil {
+				return err
 			}
 
-			p.Entity = &copyEntity
+			p.Entities = sealed
 			return next(ctx, p)
 		}
 	}
 }
 
+// sealNote returns a copy of the note with all sensitive fields encrypted with the key.
+func sealNote(k []byte, e *note.Note) (*note.Note, error) {
+	copyEntity := *e
+	// b holds the ownership binding authenticated with every encrypted field.
+	b := fieldcrypt.Binding{ItemType: itemType, UserID: copyEntity.UserID, ItemID: copyEntity.ID}
+
+	var err error
+	if copyEntity.Note, err = b.Seal(k, "note", copyEntity.Note); err != nil {
+		return nil, fmt.Errorf("failed to encrypt note: %w", err)
+	}
+
+	if copyEntity.Description, err = b.Seal(k, "description", copyEntity.Description); err != nil {
+		return nil, fmt.Errorf("failed to encrypt description: %w", err)
+	}
+	return &copyEntity, nil
+}
+
 // decryptionMw creates middleware that decrypts note entities after loading from storage.
-func decryptionMw(keyProvider keyprv.UserKeyProvider) loadMw {
+func decryptionMw(keyProvider keyprv.UserKeyProvider, pipeline *fieldcrypt.Pipeline) loadMw {
 	return func(next loadFunc) loadFunc {
 		return func(ctx context.Context, p LoadParams) ([]*note.Note, error) {
 			entities, err := next(ctx, p)
@@ -51,14 +97,23 @@ func decryptionMw(keyProvider keyprv.UserKeyProvider) loadMw {
 			if err != nil {
 				return nil, fmt.Errorf("failed to provide user key: %w", err)
 			}
+			defer securebytes.Wipe(k)
 
-			for _, entity := range entities {
-				if entity.Note, err = crypto.DecryptAESGCM(k, entity.Note); err != nil {
-					return nil, fmt.Errorf("failed to decrypt note: %w", err)
+			err = pipeline.Run(ctx, len(entities), func(i int) error {
+				entity := entities[i]
+				// b holds the ownership binding the loaded fields must have been sealed with.
+				b := fieldcrypt.Binding{ItemType: itemType, UserID: p.UserID, ItemID: entity.ID}
+				var err error
+				if entity.Note, err = b.OpenSelected(k, p.Fields, "note", entity.Note); err != nil {
+					return fmt.Errorf("failed to decrypt note: %w", err)
 				}
-				if entity.Description, err = crypto.DecryptAESGCM(k, entity.Description); err != nil {
-					return nil, fmt.Errorf("failed to decrypt description: %w", err)
+				if entity.Description, err = b.OpenSelected(k, p.Fields, "description", entity.Description); err != nil {
+					return fmt.Errorf("failed to decrypt description: %w", err)
 				}
+				return nil
+			})
+			if err != nil {
+				return nil, err
 			}
 
 			return entities, nil
@@ -1,6 +1,7 @@
 package note
 
 import (
+	"bytes"
 	"context"
 	"testing"
 
@@ -8,8 +9,8 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
-	"github.com/gdyunin/aegis-vault-keeper/internal/server/crypto"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/note"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/fieldcrypt"
 )
 
 // Mock key provider for testing encryption middleware.
@@ -22,7 +23,7 @@ func (m *mockNoteKeyProvider) UserKeyProvide(ctx context.Context, userID uuid.UU
 	if m.shouldErr {
 		return nil, assert.AnError
 	}
-	return m.key, nil
+	return bytes.Clone(m.key), nil
 }
 
 func TestEncryptionMw(t *testing.T) {
@@ -152,9 +153,10 @@ func TestDecryptionMw(t *testing.T) {
 				id := uuid.New()
 				userID := uuid.New()
 
-				// Use crypto package to create real encrypted values
-				noteEncrypted, _ := crypto.EncryptAESGCM(validKey, []byte("test_note"))
-				descEncrypted, _ := crypto.EncryptAESGCM(validKey, []byte("test_description"))
+				// b holds the ownership binding the loader expects for this entity
+				b := fieldcrypt.Binding{ItemType: itemType, UserID: userID, ItemID: id}
+				noteEncrypted, _ := b.Seal(validKey, "note", []byte("test_note"))
+				descEncrypted, _ := b.Seal(validKey, "description", []byte("test_description"))
 
 				return []*note.Note{
 					{
@@ -167,6 +169,25 @@ func TestDecryptionMw(t *testing.T) {
 			}(),
 			expectError: false,
 		},
+		{
+			name: "ciphertext swapped from another item",
+			keyProvider: &mockNoteKeyProvider{
+				key:       validKey,
+				shouldErr: false,
+			},
+			entities: func() []*note.Note {
+				userID := uuid.New()
+				// other holds the binding of a different item owned by the same user
+				other := fieldcrypt.Binding{ItemType: itemType, UserID: userID, ItemID: uuid.New()}
+				swapped, _ := other.Seal(validKey, "note", []byte("foreign"))
+
+				return []*note.Note{
+					{ID: uuid.New(), UserID: userID, Note: swapped},
+				}
+			}(),
+			expectError: true,
+			errorMsg:    "integrity violation",
+		},
 		{
 			name: "empty entities list",
 			keyProvider: &mockNoteKeyProvider{
@@ -221,7 +242,7 @@ func TestDecryptionMw(t *testing.T) {
 			t.Parallel()
 
 			// Create middleware
-			mw := decryptionMw(tt.keyProvider)
+			mw := decryptionMw(tt.keyProvider, nil)
 
 			// Mock next function that simulates database load
 			nextFunc := func(ctx context.Context, p LoadParams) ([]*note.Note, error) {
@@ -236,6 +257,9 @@ func TestDecryptionMw(t *testing.T) {
 
 			// Execute
 			params := LoadParams{UserID: uuid.New()}
+			if len(tt.entities) > 0 {
+				params.UserID = tt.entities[0].UserID
+			}
 			result, err := wrappedFunc(context.Background(), params)
 
 			if tt.expectError {
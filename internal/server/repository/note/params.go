@@ -1,6 +1,8 @@
 package note
 
 import (
+	"time"
+
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/note"
 	"github.com/google/uuid"
 )
@@ -11,10 +13,28 @@ type SaveParams struct {
 	Entity *note.Note
 }
 
+// SaveBatchParams contains the parameters for saving several note entities of one user at once.
+type SaveBatchParams struct {
+	// Entities contains the note data to be persisted; every entity must belong to UserID.
+	Entities []*note.Note
+	// UserID contains the identifier of the user owning all entities.
+	UserID uuid.UUID
+}
+
 // LoadParams contains the parameters for loading note entities from the repository.
 type LoadParams struct {
+	// After restricts the load to the notes whose ID sorts after it; zero value starts at the first.
+	After uuid.UUID
+	// AsOf selects the versions current at the specified moment; zero value loads current versions.
+	AsOf time.Time
+	// Fields restricts decryption to the named fields, leaving the others empty (optional).
+	Fields []string
 	// ID contains the specific note identifier for single record lookup (optional).
 	ID uuid.UUID
+	// IDs contains the note identifiers to restrict the lookup to (optional).
+	IDs []uuid.UUID
+	// Limit caps the number of loaded notes and orders them by ID; zero value loads all.
+	Limit int
 	// UserID contains the user identifier for filtering notes by owner (required).
 	UserID uuid.UUID
 }
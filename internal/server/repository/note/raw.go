@@ -4,38 +4,115 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/note"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/db"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/history"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/rowsign"
 	"github.com/google/uuid"
 )
 
 // rawSave creates a database save function that persists note data directly to PostgreSQL.
 // Uses INSERT ON CONFLICT DO UPDATE for upsert behavior.
-func rawSave(db db.DBClient) saveFunc {
+func rawSave(db db.DBClient, signer *rowsign.Signer) saveFunc {
 	return func(ctx context.Context, p SaveParams) error {
 		e := p.Entity
 
+		signature, err := signer.Sign(SignedTable, rowValues(e)...)
+		if err != nil {
+			return fmt.Errorf("failed to sign note row: %w", err)
+		}
+
 		query := `
-			INSERT INTO aegis_vault_keeper.notes (id, user_id, note, description, updated_at)
-			VALUES ($1,$2,$3,$4,$5)
+			INSERT INTO aegis_vault_keeper.notes (
+				id, user_id, note, description, updated_at, signature
+			) VALUES ($1,$2,$3,$4,$5,$6)
 			ON CONFLICT (id) DO UPDATE SET
 			  note        = EXCLUDED.note,
 			  description = EXCLUDED.description,
-			  updated_at  = EXCLUDED.updated_at
+			  updated_at  = EXCLUDED.updated_at,
+			  signature   = EXCLUDED.signature
 		`
 
-		if _, err := db.Exec(ctx, query, e.ID, e.UserID, e.Note, e.Description, e.UpdatedAt); err != nil {
+		if _, err := db.Exec(
+			ctx,
+			query,
+			e.ID,
+			e.UserID,
+			e.Note,
+			e.Description,
+			e.UpdatedAt,
+			signature,
+		); err != nil {
 			return fmt.Errorf("failed to save note: %w", err)
 		}
 		return nil
 	}
 }
 
+// batchChunkSize limits the number of rows upserted by a single batch statement.
+const batchChunkSize = 1000
+
+// rawSaveBatch creates a database save function that upserts notes in chunks of batchChunkSize rows.
+// Every chunk is a single statement whose text does not depend on the row count, so it is prepared
+// once per connection. Rows of other users are never overwritten.
+func rawSaveBatch(db db.DBClient, signer *rowsign.Signer) saveBatchFunc {
+	return func(ctx context.Context, p SaveBatchParams) error {
+		query := `
+			INSERT INTO aegis_vault_keeper.notes (
+				id, user_id, note, description, updated_at, signature
+			)
+			SELECT * FROM unnest($1::uuid[], $2::uuid[], $3::bytea[], $4::bytea[], $5::timestamp[], $6::bytea[])
+			ON CONFLICT (id) DO UPDATE SET
+			  note        = EXCLUDED.note,
+			  description = EXCLUDED.description,
+			  updated_at  = EXCLUDED.updated_at,
+			  signature   = EXCLUDED.signature
+			WHERE notes.user_id = EXCLUDED.user_id
+		`
+
+		for chunk := range slices.Chunk(p.Entities, batchChunkSize) {
+			var (
+				ids          = make([]uuid.UUID, len(chunk))
+				userIDs      = make([]uuid.UUID, len(chunk))
+				contents     = make([][]byte, len(chunk))
+				descriptions = make([][]byte, len(chunk))
+				updatedAt    = make([]time.Time, len(chunk))
+				signatures   = make([][]byte, len(chunk))
+			)
+			for i, e := range chunk {
+				signature, err := signer.Sign(SignedTable, rowValues(e)...)
+				if err != nil {
+					return fmt.Errorf("failed to sign note row %s: %w", e.ID, err)
+				}
+				ids[i], userIDs[i] = e.ID, e.UserID
+				contents[i] = e.Note
+				descriptions[i] = e.Description
+				updatedAt[i], signatures[i] = e.UpdatedAt, signature
+			}
+
+			res, err := db.Exec(ctx, query, ids, userIDs, contents, descriptions, updatedAt, signatures)
+			if err != nil {
+				return fmt.Errorf("query execution failed: %w", err)
+			}
+			affected, err := res.RowsAffected()
+			if err != nil {
+				return fmt.Errorf("failed to get affected rows: %w", err)
+			}
+			if affected != int64(len(chunk)) {
+				return fmt.Errorf("%d of %d notes belong to another user", int64(len(chunk))-affected, len(chunk))
+			}
+		}
+		return nil
+	}
+}
+
 // rawLoad creates a database load function that retrieves note data from PostgreSQL.
 // Supports filtering by user ID and specific note ID.
-func rawLoad(db db.DBClient) func(ctx context.Context, p LoadParams) ([]*note.Note, error) {
+func rawLoad(db db.DBClient, signer *rowsign.Signer) loadFunc {
 	return func(ctx context.Context, p LoadParams) ([]*note.Note, error) {
 		var (
 			queryBuilder strings.Builder
@@ -44,27 +121,46 @@ func rawLoad(db db.DBClient) func(ctx context.Context, p LoadParams) ([]*note.No
 			argIdx       = 1
 		)
 
-		queryBuilder.WriteString(`
-			SELECT id, user_id, note, description, updated_at
-			FROM aegis_vault_keeper.notes
-		`)
+		// columns lists the selected note columns.
+		const columns = "id, user_id, note, description, updated_at, signature"
+		source := "aegis_vault_keeper.notes"
+		if !p.AsOf.IsZero() {
+			source = history.AsOfSource("notes", columns, argIdx)
+			args = append(args, history.AsOfArg(p.AsOf))
+			argIdx++
+		}
+		queryBuilder.WriteString("SELECT " + columns + " FROM " + source)
 
 		if p.ID != uuid.Nil {
 			conditions = append(conditions, fmt.Sprintf("id = $%d", argIdx))
 			args = append(args, p.ID)
 			argIdx++
 		}
+		if p.IDs != nil {
+			conditions = append(conditions, fmt.Sprintf("id = ANY($%d::uuid[])", argIdx))
+			args = append(args, p.IDs)
+			argIdx++
+		}
 		if p.UserID != uuid.Nil {
 			conditions = append(conditions, fmt.Sprintf("user_id = $%d", argIdx))
 			args = append(args, p.UserID)
-			// argIdx++ // Last usage, no need to increment
+			argIdx++
 		}
 		if len(conditions) == 0 {
 			return nil, errors.New("at least one of ID or UserID must be provided")
 		}
+		if p.After != uuid.Nil {
+			conditions = append(conditions, fmt.Sprintf("id > $%d", argIdx))
+			args = append(args, p.After)
+			argIdx++
+		}
 
 		queryBuilder.WriteString(" WHERE ")
 		queryBuilder.WriteString(strings.Join(conditions, " AND "))
+		if p.Limit > 0 {
+			queryBuilder.WriteString(fmt.Sprintf(" ORDER BY id LIMIT $%d", argIdx))
+			args = append(args, p.Limit)
+		}
 
 		rows, err := db.Query(ctx, queryBuilder.String(), args...)
 		if err != nil {
@@ -77,15 +173,21 @@ func rawLoad(db db.DBClient) func(ctx context.Context, p LoadParams) ([]*note.No
 		for rows.Next() {
 			// n holds a single note entity during database row scanning.
 			var n note.Note
+			// signature holds the stored row integrity signature.
+			var signature []byte
 			if err := rows.Scan(
 				&n.ID,
 				&n.UserID,
 				&n.Note,
 				&n.Description,
 				&n.UpdatedAt,
+				&signature,
 			); err != nil {
 				return nil, fmt.Errorf("failed to scan row: %w", err)
 			}
+			if err := signer.Verify(SignedTable, signature, rowValues(&n)...); err != nil {
+				return nil, fmt.Errorf("note row %s failed integrity verification: %w", n.ID, err)
+			}
 			notes = append(notes, &n)
 		}
 		if err := rows.Err(); err != nil {
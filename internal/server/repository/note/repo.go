@@ -6,8 +6,10 @@ import (
 
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/note"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/db"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/fieldcrypt"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/keyprv"
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/middleware"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/rowsign"
 )
 
 // saveFunc defines the signature for note save operations.
@@ -16,6 +18,12 @@ type saveFunc func(ctx context.Context, params SaveParams) error
 // saveMw defines middleware for save operations.
 type saveMw = middleware.Middleware[saveFunc]
 
+// saveBatchFunc defines the signature for batch note save operations.
+type saveBatchFunc func(ctx context.Context, params SaveBatchParams) error
+
+// saveBatchMw defines middleware for batch save operations.
+type saveBatchMw = middleware.Middleware[saveBatchFunc]
+
 // loadFunc defines the signature for note load operations.
 type loadFunc func(ctx context.Context, params LoadParams) ([]*note.Note, error)
 
@@ -26,15 +34,40 @@ type loadMw = middleware.Middleware[loadFunc]
 type Repository struct {
 	// save is the function chain for saving note data with encryption middleware.
 	save saveFunc
+	// saveBatch is the function chain for saving several notes at once with encryption middleware.
+	saveBatch saveBatchFunc
 	// load is the function chain for loading note data with decryption middleware.
 	load loadFunc
 }
 
 // NewRepository creates a new Repository with encryption middleware and database backend.
-func NewRepository(dbClient db.DBClient, keyProvider keyprv.UserKeyProvider) *Repository {
+// The items of bulk loads and saves are encrypted and decrypted concurrently on the pipeline.
+func NewRepository(
+	dbClient db.DBClient,
+	keyProvider keyprv.UserKeyProvider,
+	signer *rowsign.Signer,
+	retry middleware.RetryPolicy,
+	pipeline *fieldcrypt.Pipeline,
+) *Repository {
 	return &Repository{
-		save: middleware.Chain(rawSave(dbClient), encryptionMw(keyProvider)),
-		load: middleware.Chain(rawLoad(dbClient), decryptionMw(keyProvider)),
+		save: middleware.Chain(
+			rawSave(dbClient, signer),
+			middleware.RetryExecMw[saveFunc](retry),
+			userScopeSaveMw(dbClient),
+			encryptionMw(keyProvider),
+		),
+		saveBatch: middleware.Chain(
+			rawSaveBatch(dbClient, signer),
+			middleware.RetryExecMw[saveBatchFunc](retry),
+			userScopeSaveBatchMw(dbClient),
+			batchEncryptionMw(keyProvider, pipeline),
+		),
+		load: middleware.Chain(
+			rawLoad(dbClient, signer),
+			middleware.RetryQueryMw[loadFunc](retry),
+			userScopeLoadMw(dbClient),
+			decryptionMw(keyProvider, pipeline),
+		),
 	}
 }
 
@@ -46,6 +79,23 @@ func (r *Repository) Save(ctx context.Context, params SaveParams) error {
 	return nil
 }
 
+// SaveBatch persists several notes of one user with automatic encryption of sensitive fields.
+// Large batches are written with a few multi-row statements instead of one statement per note.
+func (r *Repository) SaveBatch(ctx context.Context, params SaveBatchParams) error {
+	if len(params.Entities) == 0 {
+		return nil
+	}
+	for _, e := range params.Entities {
+		if e.UserID != params.UserID {
+			return fmt.Errorf("note %s does not belong to user %s", e.ID, params.UserID)
+		}
+	}
+	if err := r.saveBatch(ctx, params); err != nil {
+		return fmt.Errorf("failed to save notes: %w", err)
+	}
+	return nil
+}
+
 // Load retrieves notes with automatic decryption of sensitive fields.
 func (r *Repository) Load(ctx context.Context, params LoadParams) ([]*note.Note, error) {
 	notes, err := r.load(ctx, params)
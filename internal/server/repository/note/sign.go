@@ -0,0 +1,29 @@
+package note
+
+import (
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/note"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/rowsign"
+)
+
+// SignedTable describes the notes table columns covered by row integrity signatures.
+var SignedTable = rowsign.Table{
+	Name: "notes",
+	Columns: []rowsign.Column{
+		{Name: "id", Kind: rowsign.KindUUID},
+		{Name: "user_id", Kind: rowsign.KindUUID},
+		{Name: "note", Kind: rowsign.KindBytes},
+		{Name: "description", Kind: rowsign.KindBytes},
+		{Name: "updated_at", Kind: rowsign.KindTime},
+	},
+}
+
+// rowValues returns the stored note column values in SignedTable column order.
+func rowValues(e *note.Note) []any {
+	return []any{
+		e.ID,
+		e.UserID,
+		e.Note,
+		e.Description,
+		e.UpdatedAt,
+	}
+}
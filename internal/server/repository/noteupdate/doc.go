@@ -0,0 +1,5 @@
+// Package noteupdate provides encrypted persistence of note text updates for the AegisVaultKeeper server.
+//
+// This package stores the updates of notes edited in merge mode encrypted with the keys of their owners, signs
+// every row for integrity verification and confines queries to the row-level security scope of the owner.
+package noteupdate
@@ -0,0 +1,71 @@
+package noteupdate
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/notecrdt"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/fieldcrypt"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/keyprv"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/securebytes"
+)
+
+// itemType identifies note items in the ownership binding of encrypted fields; updates are bound to their note,
+// so they cannot be moved to another one.
+const itemType = "note"
+
+// dataField names the encrypted update data in the ownership binding.
+const dataField = "update"
+
+// encryptionMw creates a middleware that encrypts the update data before saving.
+func encryptionMw(keyProvider keyprv.UserKeyProvider) saveMw {
+	return func(next saveFunc) saveFunc {
+		return func(ctx context.Context, p SaveParams) (int64, error) {
+			k, err := keyProvider.UserKeyProvide(ctx, p.Entity.UserID)
+			if err != nil {
+				return 0, fmt.Errorf("failed to provide user key: %w", err)
+			}
+			defer securebytes.Wipe(k)
+
+			copyEntity := *p.Entity
+			// b holds the ownership binding authenticated with the encrypted data.
+			b := fieldcrypt.Binding{ItemType: itemType, UserID: copyEntity.UserID, ItemID: copyEntity.NoteID}
+			if copyEntity.Data, err = b.Seal(k, dataField, copyEntity.Data); err != nil {
+				return 0, fmt.Errorf("failed to encrypt note update: %w", err)
+			}
+
+			p.Entity = &copyEntity
+			return next(ctx, p)
+		}
+	}
+}
+
+// decryptionMw creates middleware that decrypts note updates after loading from storage.
+func decryptionMw(keyProvider keyprv.UserKeyProvider) loadMw {
+	return func(next loadFunc) loadFunc {
+		return func(ctx context.Context, p LoadParams) ([]*notecrdt.Record, error) {
+			entities, err := next(ctx, p)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load entities: %w", err)
+			}
+			if len(entities) == 0 {
+				return []*notecrdt.Record{}, nil
+			}
+
+			k, err := keyProvider.UserKeyProvide(ctx, p.UserID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to provide user key: %w", err)
+			}
+			defer securebytes.Wipe(k)
+
+			for _, entity := range entities {
+				// b holds the ownership binding the loaded data must have been sealed with.
+				b := fieldcrypt.Binding{ItemType: itemType, UserID: p.UserID, ItemID: p.NoteID}
+				if entity.Data, err = b.Open(k, dataField, entity.Data); err != nil {
+					return nil, fmt.Errorf("failed to decrypt note update: %w", err)
+				}
+			}
+			return entities, nil
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package noteupdate
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/notecrdt"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockKeyProvider implements keyprv.UserKeyProvider for testing.
+type mockKeyProvider struct{}
+
+func (mockKeyProvider) UserKeyProvide(context.Context, uuid.UUID) ([]byte, error) {
+	return []byte("12345678901234567890123456789012"), nil
+}
+
+func TestEncryptionRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	noteID := uuid.New()
+	plain := notecrdt.Record{
+		ID:     uuid.New(),
+		UserID: userID,
+		NoteID: noteID,
+		Data:   []byte(`{"ops":[{"delete":{"replica":"phone","counter":3}}]}`),
+	}
+
+	// stored holds the update as passed to the database.
+	var stored *notecrdt.Record
+	save := encryptionMw(mockKeyProvider{})(func(_ context.Context, p SaveParams) (int64, error) {
+		stored = p.Entity
+		return 1, nil
+	})
+	_, err := save(context.Background(), SaveParams{Entity: &plain})
+	require.NoError(t, err)
+	assert.NotEqual(t, plain.Data, stored.Data)
+
+	tests := []struct {
+		name    string
+		params  LoadParams
+		wantErr bool
+	}{
+		{name: "same note", params: LoadParams{NoteID: noteID, UserID: userID}},
+		{name: "other note", params: LoadParams{NoteID: uuid.New(), UserID: userID}, wantErr: true},
+		{name: "other owner", params: LoadParams{NoteID: noteID, UserID: uuid.New()}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			sealed := *stored
+			load := decryptionMw(mockKeyProvider{})(func(context.Context, LoadParams) ([]*notecrdt.Record, error) {
+				return []*notecrdt.Record{&sealed}, nil
+			})
+			got, err := load(context.Background(), tt.params)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, got, 1)
+			assert.Equal(t, plain, *got[0])
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package noteupdate
+
+import (
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/notecrdt"
+	"github.com/google/uuid"
+)
+
+// SaveParams contains the parameters for saving a note update to the repository.
+type SaveParams struct {
+	// Entity contains the update to be persisted; a zero Seq is assigned by the database.
+	Entity *notecrdt.Record
+}
+
+// LoadParams contains the parameters for loading the updates of a note from the repository.
+type LoadParams struct {
+	// NoteID identifies the note whose updates are loaded (required).
+	NoteID uuid.UUID
+	// UserID identifies the owner of the note (required).
+	UserID uuid.UUID
+	// AfterSeq limits the load to the updates stored after the sequence number; zero loads all of them.
+	AfterSeq int64
+	// Lock locks the note until the end of the surrounding transaction, so concurrent merges of its updates
+	// run one after another.
+	Lock bool
+}
+
+// DeleteParams contains the parameters for deleting the updates of a note from the repository.
+type DeleteParams struct {
+	// NoteID identifies the note whose updates are deleted (required).
+	NoteID uuid.UUID
+	// UserID identifies the owner of the note (required).
+	UserID uuid.UUID
+	// UpToSeq limits the deletion to the updates with a sequence number up to it; zero deletes all of them.
+	UpToSeq int64
+}
+
+// MergingParams contains the parameters for finding the notes edited in merge mode.
+type MergingParams struct {
+	// NoteIDs lists the notes to check.
+	NoteIDs []uuid.UUID
+	// UserID identifies the owner of the notes (required).
+	UserID uuid.UUID
+}
+
+// CompactableParams contains the parameters for finding the notes whose updates are worth compacting.
+type CompactableParams struct {
+	// MinUpdates is the number of stored updates a note must have to be compacted.
+	MinUpdates int
+}
+
+// NoteRef identifies a note of a user.
+type NoteRef struct {
+	// NoteID identifies the note.
+	NoteID uuid.UUID
+	// UserID identifies the owner of the note.
+	UserID uuid.UUID
+}
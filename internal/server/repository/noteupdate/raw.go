@@ -0,0 +1,181 @@
+package noteupdate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/notecrdt"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/db"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/rowsign"
+	"github.com/google/uuid"
+)
+
+// rawSave creates a database save function that inserts a note update into PostgreSQL and returns its sequence
+// number. Updates are never changed once stored; a nonzero Seq is stored as is, so compacted snapshots keep the
+// sequence number of the updates they replace.
+func rawSave(db db.DBClient, signer *rowsign.Signer) saveFunc {
+	return func(ctx context.Context, p SaveParams) (int64, error) {
+		e := p.Entity
+
+		signature, err := signer.Sign(SignedTable, rowValues(e)...)
+		if err != nil {
+			return 0, fmt.Errorf("failed to sign note update row: %w", err)
+		}
+
+		query := `
+			INSERT INTO aegis_vault_keeper.note_updates (id, user_id, note_id, data, created_at, signature)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING seq
+		`
+		args := []any{e.ID, e.UserID, e.NoteID, e.Data, e.CreatedAt, signature}
+		if e.Seq != 0 {
+			query = `
+				INSERT INTO aegis_vault_keeper.note_updates (id, user_id, note_id, data, created_at, signature, seq)
+				VALUES ($1, $2, $3, $4, $5, $6, $7)
+				RETURNING seq
+			`
+			args = append(args, e.Seq)
+		}
+
+		// seq holds the sequence number of the stored update.
+		var seq int64
+		if err := db.QueryRow(ctx, query, args...).Scan(&seq); err != nil {
+			return 0, fmt.Errorf("failed to save note update: %w", err)
+		}
+		return seq, nil
+	}
+}
+
+// rawLoad creates a database load function that retrieves the updates of a note from PostgreSQL in sequence
+// order, locking the note first when requested.
+func rawLoad(db db.DBClient, signer *rowsign.Signer) loadFunc {
+	return func(ctx context.Context, p LoadParams) ([]*notecrdt.Record, error) {
+		if p.NoteID == uuid.Nil || p.UserID == uuid.Nil {
+			return nil, errors.New("both NoteID and UserID must be provided")
+		}
+
+		if p.Lock {
+			lockQuery := `SELECT id FROM aegis_vault_keeper.notes WHERE id = $1 AND user_id = $2 FOR UPDATE`
+			if _, err := db.Exec(ctx, lockQuery, p.NoteID, p.UserID); err != nil {
+				return nil, fmt.Errorf("failed to lock note: %w", err)
+			}
+		}
+
+		query := `
+			SELECT id, seq, user_id, note_id, data, created_at, signature
+			FROM aegis_vault_keeper.note_updates
+			WHERE note_id = $1 AND user_id = $2 AND seq > $3
+			ORDER BY seq
+		`
+		rows, err := db.Query(ctx, query, p.NoteID, p.UserID, p.AfterSeq)
+		if err != nil {
+			return nil, fmt.Errorf("failed to execute query: %w", err)
+		}
+		defer func() { _ = rows.Close() }()
+
+		// records collects all note updates retrieved from the database.
+		var records []*notecrdt.Record
+		for rows.Next() {
+			// r holds a single note update during database row scanning.
+			var r notecrdt.Record
+			// signature holds the stored row integrity signature.
+			var signature []byte
+			if err := rows.Scan(&r.ID, &r.Seq, &r.UserID, &r.NoteID, &r.Data, &r.CreatedAt, &signature); err != nil {
+				return nil, fmt.Errorf("failed to scan row: %w", err)
+			}
+			if err := signer.Verify(SignedTable, signature, rowValues(&r)...); err != nil {
+				return nil, fmt.Errorf("note update row %s failed integrity verification: %w", r.ID, err)
+			}
+			records = append(records, &r)
+		}
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("rows iteration error: %w", err)
+		}
+		return records, nil
+	}
+}
+
+// rawDelete creates a database delete function that removes the updates of a note from PostgreSQL.
+func rawDelete(db db.DBClient) deleteFunc {
+	return func(ctx context.Context, p DeleteParams) error {
+		if p.NoteID == uuid.Nil || p.UserID == uuid.Nil {
+			return errors.New("both NoteID and UserID must be provided")
+		}
+
+		query := `
+			DELETE FROM aegis_vault_keeper.note_updates
+			WHERE note_id = $1 AND user_id = $2 AND ($3 = 0 OR seq <= $3)
+		`
+		if _, err := db.Exec(ctx, query, p.NoteID, p.UserID, p.UpToSeq); err != nil {
+			return fmt.Errorf("failed to delete note updates: %w", err)
+		}
+		return nil
+	}
+}
+
+// rawMerging creates a database function that returns which of the notes have stored updates.
+func rawMerging(db db.DBClient) mergingFunc {
+	return func(ctx context.Context, p MergingParams) ([]uuid.UUID, error) {
+		if p.UserID == uuid.Nil {
+			return nil, errors.New("UserID must be provided")
+		}
+
+		query := `
+			SELECT DISTINCT note_id FROM aegis_vault_keeper.note_updates
+			WHERE user_id = $1 AND note_id = ANY($2::uuid[])
+		`
+		rows, err := db.Query(ctx, query, p.UserID, p.NoteIDs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to execute query: %w", err)
+		}
+		defer func() { _ = rows.Close() }()
+
+		// ids collects the notes with stored updates.
+		var ids []uuid.UUID
+		for rows.Next() {
+			// id holds a single note ID during database row scanning.
+			var id uuid.UUID
+			if err := rows.Scan(&id); err != nil {
+				return nil, fmt.Errorf("failed to scan row: %w", err)
+			}
+			ids = append(ids, id)
+		}
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("rows iteration error: %w", err)
+		}
+		return ids, nil
+	}
+}
+
+// rawCompactable creates a database function that returns the notes of all users with at least the minimum
+// number of stored updates.
+func rawCompactable(db db.DBClient) compactableFunc {
+	return func(ctx context.Context, p CompactableParams) ([]NoteRef, error) {
+		query := `
+			SELECT note_id, user_id FROM aegis_vault_keeper.note_updates
+			GROUP BY note_id, user_id
+			HAVING COUNT(*) >= $1
+		`
+		rows, err := db.Query(ctx, query, p.MinUpdates)
+		if err != nil {
+			return nil, fmt.Errorf("failed to execute query: %w", err)
+		}
+		defer func() { _ = rows.Close() }()
+
+		// refs collects the notes worth compacting.
+		var refs []NoteRef
+		for rows.Next() {
+			// ref holds a single note reference during database row scanning.
+			var ref NoteRef
+			if err := rows.Scan(&ref.NoteID, &ref.UserID); err != nil {
+				return nil, fmt.Errorf("failed to scan row: %w", err)
+			}
+			refs = append(refs, ref)
+		}
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("rows iteration error: %w", err)
+		}
+		return refs, nil
+	}
+}
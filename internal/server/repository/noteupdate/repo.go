@@ -0,0 +1,143 @@
+package noteupdate
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/notecrdt"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/db"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/keyprv"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/middleware"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/rowsign"
+	"github.com/google/uuid"
+)
+
+// saveFunc defines the signature for note update save operations.
+type saveFunc func(ctx context.Context, params SaveParams) (int64, error)
+
+// saveMw defines middleware for save operations.
+type saveMw = middleware.Middleware[saveFunc]
+
+// loadFunc defines the signature for note update load operations.
+type loadFunc func(ctx context.Context, params LoadParams) ([]*notecrdt.Record, error)
+
+// loadMw defines middleware for load operations.
+type loadMw = middleware.Middleware[loadFunc]
+
+// deleteFunc defines the signature for note update delete operations.
+type deleteFunc func(ctx context.Context, params DeleteParams) error
+
+// deleteMw defines middleware for delete operations.
+type deleteMw = middleware.Middleware[deleteFunc]
+
+// mergingFunc defines the signature for merge mode lookups.
+type mergingFunc func(ctx context.Context, params MergingParams) ([]uuid.UUID, error)
+
+// mergingMw defines middleware for merge mode lookups.
+type mergingMw = middleware.Middleware[mergingFunc]
+
+// compactableFunc defines the signature for lookups of notes worth compacting.
+type compactableFunc func(ctx context.Context, params CompactableParams) ([]NoteRef, error)
+
+// compactableMw defines middleware for lookups of notes worth compacting.
+type compactableMw = middleware.Middleware[compactableFunc]
+
+// Repository provides encrypted note update persistence with middleware support.
+type Repository struct {
+	// save is the function chain for saving updates with encryption middleware.
+	save saveFunc
+	// load is the function chain for loading updates with decryption middleware.
+	load loadFunc
+	// del is the function chain for deleting updates.
+	del deleteFunc
+	// merging is the function chain for finding the notes edited in merge mode.
+	merging mergingFunc
+	// compactable is the function chain for finding the notes of all users worth compacting.
+	compactable compactableFunc
+}
+
+// NewRepository creates a new Repository with encryption middleware and database backend.
+func NewRepository(
+	dbClient db.DBClient,
+	keyProvider keyprv.UserKeyProvider,
+	signer *rowsign.Signer,
+	retry middleware.RetryPolicy,
+) *Repository {
+	return &Repository{
+		save: middleware.Chain(
+			rawSave(dbClient, signer),
+			middleware.RetryQueryMw[saveFunc](retry),
+			userScopeSaveMw(dbClient),
+			encryptionMw(keyProvider),
+		),
+		load: middleware.Chain(
+			rawLoad(dbClient, signer),
+			middleware.RetryQueryMw[loadFunc](retry),
+			userScopeLoadMw(dbClient),
+			decryptionMw(keyProvider),
+		),
+		del: middleware.Chain(
+			rawDelete(dbClient),
+			middleware.RetryExecMw[deleteFunc](retry),
+			userScopeDeleteMw(dbClient),
+		),
+		merging: middleware.Chain(
+			rawMerging(dbClient),
+			middleware.RetryQueryMw[mergingFunc](retry),
+			userScopeMergingMw(dbClient),
+		),
+		compactable: middleware.Chain(
+			rawCompactable(dbClient),
+			middleware.RetryQueryMw[compactableFunc](retry),
+			adminScopeCompactableMw(dbClient),
+		),
+	}
+}
+
+// Save persists a note update with automatic encryption of its data and returns its sequence number.
+func (r *Repository) Save(ctx context.Context, params SaveParams) (int64, error) {
+	seq, err := r.save(ctx, params)
+	if err != nil {
+		return 0, fmt.Errorf("failed to save note update: %w", err)
+	}
+	return seq, nil
+}
+
+// Load retrieves the updates of a note in sequence order with automatic decryption of their data.
+func (r *Repository) Load(ctx context.Context, params LoadParams) ([]*notecrdt.Record, error) {
+	records, err := r.load(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load note updates: %w", err)
+	}
+	return records, nil
+}
+
+// Delete removes the updates of a note.
+func (r *Repository) Delete(ctx context.Context, params DeleteParams) error {
+	if err := r.del(ctx, params); err != nil {
+		return fmt.Errorf("failed to delete note updates: %w", err)
+	}
+	return nil
+}
+
+// Merging returns which of the notes are edited in merge mode, that is have stored updates.
+func (r *Repository) Merging(ctx context.Context, params MergingParams) ([]uuid.UUID, error) {
+	if len(params.NoteIDs) == 0 {
+		return nil, nil
+	}
+	ids, err := r.merging(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find notes in merge mode: %w", err)
+	}
+	return ids, nil
+}
+
+// Compactable returns the notes of all users with at least the minimum number of stored updates. The notes of all
+// users are looked up within the admin scope.
+func (r *Repository) Compactable(ctx context.Context, params CompactableParams) ([]NoteRef, error) {
+	refs, err := r.compactable(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find compactable notes: %w", err)
+	}
+	return refs, nil
+}
@@ -0,0 +1,86 @@
+package noteupdate
+
+import (
+	"context"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/notecrdt"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/db"
+	"github.com/google/uuid"
+)
+
+// userScopeSaveMw creates middleware that runs note update saves within the owner's row-level security scope.
+func userScopeSaveMw(dbClient db.DBClient) saveMw {
+	return func(next saveFunc) saveFunc {
+		return func(ctx context.Context, p SaveParams) (int64, error) {
+			// seq holds the sequence number assigned inside the user scope.
+			var seq int64
+			err := db.InUserScope(ctx, dbClient, p.Entity.UserID, func(ctx context.Context) error {
+				var err error
+				seq, err = next(ctx, p)
+				return err
+			})
+			return seq, err
+		}
+	}
+}
+
+// userScopeLoadMw creates middleware that runs note update loads within the owner's row-level security scope.
+func userScopeLoadMw(dbClient db.DBClient) loadMw {
+	return func(next loadFunc) loadFunc {
+		return func(ctx context.Context, p LoadParams) ([]*notecrdt.Record, error) {
+			// entities holds the updates loaded inside the user scope.
+			var entities []*notecrdt.Record
+			err := db.InUserScope(ctx, dbClient, p.UserID, func(ctx context.Context) error {
+				var err error
+				entities, err = next(ctx, p)
+				return err
+			})
+			return entities, err
+		}
+	}
+}
+
+// userScopeDeleteMw creates middleware that runs note update deletions within the owner's row-level security
+// scope.
+func userScopeDeleteMw(dbClient db.DBClient) deleteMw {
+	return func(next deleteFunc) deleteFunc {
+		return func(ctx context.Context, p DeleteParams) error {
+			return db.InUserScope(ctx, dbClient, p.UserID, func(ctx context.Context) error {
+				return next(ctx, p)
+			})
+		}
+	}
+}
+
+// userScopeMergingMw creates middleware that runs merge mode lookups within the owner's row-level security scope.
+func userScopeMergingMw(dbClient db.DBClient) mergingMw {
+	return func(next mergingFunc) mergingFunc {
+		return func(ctx context.Context, p MergingParams) ([]uuid.UUID, error) {
+			// ids holds the notes found inside the user scope.
+			var ids []uuid.UUID
+			err := db.InUserScope(ctx, dbClient, p.UserID, func(ctx context.Context) error {
+				var err error
+				ids, err = next(ctx, p)
+				return err
+			})
+			return ids, err
+		}
+	}
+}
+
+// adminScopeCompactableMw creates middleware that runs compaction lookups within the admin row-level security
+// scope, so the notes of every user are found.
+func adminScopeCompactableMw(dbClient db.DBClient) compactableMw {
+	return func(next compactableFunc) compactableFunc {
+		return func(ctx context.Context, p CompactableParams) ([]NoteRef, error) {
+			// refs holds the notes found inside the admin scope.
+			var refs []NoteRef
+			err := db.InAdminScope(ctx, dbClient, func(ctx context.Context) error {
+				var err error
+				refs, err = next(ctx, p)
+				return err
+			})
+			return refs, err
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package noteupdate
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/notecrdt"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// plainClient implements db.DBClient without scope support.
+type plainClient struct{}
+
+func (plainClient) Exec(context.Context, string, ...interface{}) (sql.Result, error) { return nil, nil }
+func (plainClient) QueryRow(context.Context, string, ...interface{}) *sql.Row        { return nil }
+func (plainClient) Query(context.Context, string, ...interface{}) (*sql.Rows, error) { return nil, nil }
+func (plainClient) BeginTx(context.Context, *sql.TxOptions) (*sql.Tx, error)         { return nil, nil }
+func (plainClient) CommitTx(*sql.Tx) error                                           { return nil }
+func (plainClient) RollbackTx(*sql.Tx) error                                         { return nil }
+
+// scopedClient implements db.DBClient with row-level security enabled, recording the scope of the running
+// operation.
+type scopedClient struct {
+	plainClient
+	scopedUser uuid.UUID
+	adminScope bool
+}
+
+func (s *scopedClient) RunInUserScope(
+	ctx context.Context,
+	userID uuid.UUID,
+	fn func(ctx context.Context) error,
+) error {
+	s.scopedUser = userID
+	defer func() { s.scopedUser = uuid.Nil }()
+	return fn(ctx)
+}
+
+func (s *scopedClient) RunInAdminScope(ctx context.Context, fn func(ctx context.Context) error) error {
+	s.adminScope = true
+	defer func() { s.adminScope = false }()
+	return fn(ctx)
+}
+
+func TestScopeMw(t *testing.T) {
+	t.Parallel()
+
+	userID, noteID := uuid.New(), uuid.New()
+	client := &scopedClient{}
+
+	// scopes collects the user scope and admin scope seen by every operation.
+	var scopes []string
+	record := func() {
+		switch {
+		case client.adminScope:
+			scopes = append(scopes, "admin")
+		case client.scopedUser != uuid.Nil:
+			scopes = append(scopes, client.scopedUser.String())
+		default:
+			scopes = append(scopes, "none")
+		}
+	}
+
+	save := userScopeSaveMw(client)(func(context.Context, SaveParams) (int64, error) {
+		record()
+		return 1, nil
+	})
+	load := userScopeLoadMw(client)(func(context.Context, LoadParams) ([]*notecrdt.Record, error) {
+		record()
+		return nil, nil
+	})
+	del := userScopeDeleteMw(client)(func(context.Context, DeleteParams) error {
+		record()
+		return nil
+	})
+	merging := userScopeMergingMw(client)(func(context.Context, MergingParams) ([]uuid.UUID, error) {
+		record()
+		return nil, nil
+	})
+	compactable := adminScopeCompactableMw(client)(func(context.Context, CompactableParams) ([]NoteRef, error) {
+		record()
+		return []NoteRef{{NoteID: noteID, UserID: userID}}, nil
+	})
+
+	ctx := context.Background()
+	_, err := save(ctx, SaveParams{Entity: &notecrdt.Record{UserID: userID, NoteID: noteID}})
+	require.NoError(t, err)
+	_, err = load(ctx, LoadParams{NoteID: noteID, UserID: userID})
+	require.NoError(t, err)
+	require.NoError(t, del(ctx, DeleteParams{NoteID: noteID, UserID: userID}))
+	_, err = merging(ctx, MergingParams{NoteIDs: []uuid.UUID{noteID}, UserID: userID})
+	require.NoError(t, err)
+	refs, err := compactable(ctx, CompactableParams{MinUpdates: 2})
+	require.NoError(t, err)
+
+	owner := userID.String()
+	assert.Equal(t, []string{owner, owner, owner, owner, "admin"}, scopes)
+	assert.Equal(t, []NoteRef{{NoteID: noteID, UserID: userID}}, refs,
+		"the notes of every user are found within the admin scope")
+}
@@ -0,0 +1,29 @@
+package noteupdate
+
+import (
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/notecrdt"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/rowsign"
+)
+
+// SignedTable describes the note_updates table columns covered by row integrity signatures.
+var SignedTable = rowsign.Table{
+	Name: "note_updates",
+	Columns: []rowsign.Column{
+		{Name: "id", Kind: rowsign.KindUUID},
+		{Name: "user_id", Kind: rowsign.KindUUID},
+		{Name: "note_id", Kind: rowsign.KindUUID},
+		{Name: "data", Kind: rowsign.KindBytes},
+		{Name: "created_at", Kind: rowsign.KindTime},
+	},
+}
+
+// rowValues returns the stored update column values in SignedTable column order.
+func rowValues(e *notecrdt.Record) []any {
+	return []any{
+		e.ID,
+		e.UserID,
+		e.NoteID,
+		e.Data,
+		e.CreatedAt,
+	}
+}
@@ -0,0 +1,5 @@
+// Package notification provides notification channel persistence for the AegisVaultKeeper server.
+//
+// This package implements storage of the per-user notification channels and their category
+// preferences in PostgreSQL, with the channel targets encrypted under the key of their owner.
+package notification
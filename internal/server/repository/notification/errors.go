@@ -0,0 +1,11 @@
+package notification
+
+import "errors"
+
+// Notification repository error definitions.
+var (
+	// ErrChannelNotFound indicates that the requested notification channel was not found in the repository.
+	ErrChannelNotFound = errors.New("notification channel not found")
+	// ErrChannelAlreadyExists indicates that the user already has a channel of the same kind.
+	ErrChannelAlreadyExists = errors.New("notification channel already exists")
+)
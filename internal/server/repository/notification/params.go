@@ -0,0 +1,28 @@
+package notification
+
+import (
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/notification"
+	"github.com/google/uuid"
+)
+
+// SaveParams contains the parameters for saving a notification channel to the repository.
+type SaveParams struct {
+	// Entity contains the channel to be created or updated.
+	Entity *notification.Channel
+}
+
+// LoadParams contains the parameters for loading notification channels from the repository.
+type LoadParams struct {
+	// Kind selects the channel of a single kind; empty loads all channels of the user.
+	Kind notification.Kind
+	// UserID identifies the owner of the channels.
+	UserID uuid.UUID
+}
+
+// DeleteParams contains the parameters for deleting a notification channel from the repository.
+type DeleteParams struct {
+	// Kind identifies the channel to delete.
+	Kind notification.Kind
+	// UserID identifies the owner of the channel.
+	UserID uuid.UUID
+}
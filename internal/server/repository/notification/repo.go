@@ -0,0 +1,181 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/notification"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/db"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/fieldcrypt"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/keyprv"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/securebytes"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// itemType identifies notification channels in the ownership binding of their encrypted targets.
+const itemType = "notification_channel"
+
+// uniqueViolation is the PostgreSQL error code raised when the user already has a channel of the kind.
+const uniqueViolation = "23505"
+
+// Repository provides notification channel persistence operations.
+type Repository struct {
+	// db is the database client used for channel operations.
+	db db.DBClient
+	// keyProvider provides the user keys the targets are encrypted with.
+	keyProvider keyprv.UserKeyProvider
+}
+
+// NewRepository creates a new Repository with the provided database client and user key provider.
+func NewRepository(dbClient db.DBClient, keyProvider keyprv.UserKeyProvider) *Repository {
+	return &Repository{db: dbClient, keyProvider: keyProvider}
+}
+
+// Save creates the channel or updates its target and categories, encrypting the target with the key
+// of the owner. Creating a second channel of the same kind returns ErrChannelAlreadyExists.
+func (r *Repository) Save(ctx context.Context, params SaveParams) error {
+	e := params.Entity
+
+	k, err := r.keyProvider.UserKeyProvide(ctx, e.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to provide user key: %w", err)
+	}
+	defer securebytes.Wipe(k)
+
+	b := fieldcrypt.Binding{ItemType: itemType, UserID: e.UserID, ItemID: e.ID}
+	target, err := b.Seal(k, "target", []byte(e.Target))
+	if err != nil {
+		return fmt.Errorf("failed to encrypt target: %w", err)
+	}
+
+	categories := make([]string, len(e.Categories))
+	for i, c := range e.Categories {
+		categories[i] = string(c)
+	}
+
+	query := `
+		INSERT INTO aegis_vault_keeper.notification_channels
+			(id, user_id, kind, target, categories, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5::text[], $6, $7)
+		ON CONFLICT (id) DO UPDATE SET
+			target = EXCLUDED.target,
+			categories = EXCLUDED.categories,
+			updated_at = EXCLUDED.updated_at
+	`
+	_, err = r.db.Exec(ctx, query, e.ID, e.UserID, string(e.Kind), target, categories, e.CreatedAt, e.UpdatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+			return ErrChannelAlreadyExists
+		}
+		return fmt.Errorf("failed to save notification channel: %w", err)
+	}
+	return nil
+}
+
+// Load retrieves the channels of the user with decrypted targets ordered by kind.
+// Loading by kind returns ErrChannelNotFound when the user has no such channel.
+func (r *Repository) Load(ctx context.Context, params LoadParams) ([]*notification.Channel, error) {
+	query := `
+		SELECT id, user_id, kind, target, array_to_string(categories, ','), created_at, updated_at
+		FROM aegis_vault_keeper.notification_channels
+		WHERE user_id = $1
+	`
+	args := []any{params.UserID}
+	if params.Kind != "" {
+		query += " AND kind = $2"
+		args = append(args, string(params.Kind))
+	}
+	query += " ORDER BY kind"
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load notification channels: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var (
+		channels []*notification.Channel
+		targets  [][]byte
+	)
+	for rows.Next() {
+		var (
+			c          notification.Channel
+			kind       string
+			target     []byte
+			categories string
+		)
+		if err := rows.Scan(&c.ID, &c.UserID, &kind, &target, &categories, &c.CreatedAt, &c.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan notification channel: %w", err)
+		}
+		c.Kind = notification.Kind(kind)
+		for _, category := range strings.Split(categories, ",") {
+			if category != "" {
+				c.Categories = append(c.Categories, notification.Category(category))
+			}
+		}
+		channels = append(channels, &c)
+		targets = append(targets, target)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate notification channels: %w", err)
+	}
+	if params.Kind != "" && len(channels) == 0 {
+		return nil, ErrChannelNotFound
+	}
+	if len(channels) == 0 {
+		return channels, nil
+	}
+
+	if err := r.decrypt(ctx, params.UserID, channels, targets); err != nil {
+		return nil, err
+	}
+	return channels, nil
+}
+
+// Delete removes the channel of the kind from the user.
+func (r *Repository) Delete(ctx context.Context, params DeleteParams) error {
+	query := `
+		DELETE FROM aegis_vault_keeper.notification_channels
+		WHERE user_id = $1 AND kind = $2
+	`
+	res, err := r.db.Exec(ctx, query, params.UserID, string(params.Kind))
+	if err != nil {
+		return fmt.Errorf("failed to delete notification channel: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check deleted notification channels: %w", err)
+	}
+	if n == 0 {
+		return ErrChannelNotFound
+	}
+	return nil
+}
+
+// decrypt sets the targets of the channels from their sealed form.
+func (r *Repository) decrypt(
+	ctx context.Context,
+	userID uuid.UUID,
+	channels []*notification.Channel,
+	targets [][]byte,
+) error {
+	k, err := r.keyProvider.UserKeyProvide(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to provide user key: %w", err)
+	}
+	defer securebytes.Wipe(k)
+
+	for i, c := range channels {
+		b := fieldcrypt.Binding{ItemType: itemType, UserID: userID, ItemID: c.ID}
+		target, err := b.Open(k, "target", targets[i])
+		if err != nil {
+			return fmt.Errorf("failed to decrypt target: %w", err)
+		}
+		c.Target = string(target)
+	}
+	return nil
+}
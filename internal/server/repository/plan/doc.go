@@ -0,0 +1,6 @@
+// Package plan provides plan usage queries and subscription persistence for the AegisVaultKeeper server.
+//
+// This package implements counting the vault items of a user in PostgreSQL, so plan limits can be checked
+// before new items are stored, and stores the billing subscriptions assigning plans to users together with
+// the identifiers of the billing events already applied.
+package plan
@@ -0,0 +1,11 @@
+package plan
+
+import "errors"
+
+// Plan repository error definitions.
+var (
+	// ErrSubscriptionNotFound indicates that the user has no stored subscription.
+	ErrSubscriptionNotFound = errors.New("subscription not found")
+	// ErrSubscriptionUserNotFound indicates that the subscription references an unknown user.
+	ErrSubscriptionUserNotFound = errors.New("subscribed user not found")
+)
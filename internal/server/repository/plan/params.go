@@ -0,0 +1,30 @@
+package plan
+
+import (
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/plan"
+	"github.com/google/uuid"
+)
+
+// CountItemsParams contains the parameters for counting the vault items of a user.
+type CountItemsParams struct {
+	// UserID identifies the user whose items are counted.
+	UserID uuid.UUID
+}
+
+// LoadSubscriptionParams contains the parameters for loading the subscription of a user.
+type LoadSubscriptionParams struct {
+	// UserID identifies the subscribed user.
+	UserID uuid.UUID
+}
+
+// SaveSubscriptionParams contains the parameters for storing a subscription.
+type SaveSubscriptionParams struct {
+	// Entity is the subscription to store.
+	Entity *plan.Subscription
+}
+
+// EventParams contains the parameters identifying a billing event.
+type EventParams struct {
+	// ID identifies the event at the billing provider.
+	ID string
+}
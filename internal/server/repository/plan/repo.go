@@ -0,0 +1,182 @@
+package plan
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/plan"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/db"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// foreignKeyViolation is the PostgreSQL error code raised when a subscription references an unknown user.
+const foreignKeyViolation = "23503"
+
+// Repository provides plan usage queries.
+type Repository struct {
+	// db is the database client used for usage queries.
+	db db.DBClient
+}
+
+// NewRepository creates a new Repository with the provided database client.
+func NewRepository(dbClient db.DBClient) *Repository {
+	return &Repository{db: dbClient}
+}
+
+// CountItems returns the number of credentials, notes, bank cards and files the user stores.
+// The items are counted within the user scope.
+func (r *Repository) CountItems(ctx context.Context, params CountItemsParams) (int64, error) {
+	// n holds the number of items counted inside the user scope.
+	var n int64
+	err := db.InUserScope(ctx, r.db, params.UserID, func(ctx context.Context) error {
+		var err error
+		n, err = r.countItems(ctx, params)
+		return err
+	})
+	return n, err
+}
+
+// countItems counts the items the user stores.
+func (r *Repository) countItems(ctx context.Context, params CountItemsParams) (int64, error) {
+	query := `
+		SELECT
+			(SELECT count(*) FROM aegis_vault_keeper.credentials WHERE user_id = $1) +
+			(SELECT count(*) FROM aegis_vault_keeper.notes WHERE user_id = $1) +
+			(SELECT count(*) FROM aegis_vault_keeper.bank_cards WHERE user_id = $1) +
+			(SELECT count(*) FROM aegis_vault_keeper.files WHERE user_id = $1)
+	`
+	rows, err := r.db.Query(ctx, query, params.UserID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count items: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return 0, fmt.Errorf("failed to count items: %w", err)
+		}
+		return 0, errors.New("failed to count items: no result")
+	}
+	// n holds the number of stored items.
+	var n int64
+	if err := rows.Scan(&n); err != nil {
+		return 0, fmt.Errorf("failed to scan item count: %w", err)
+	}
+	return n, nil
+}
+
+// LoadSubscription retrieves the subscription of the user.
+func (r *Repository) LoadSubscription(ctx context.Context, params LoadSubscriptionParams) (*plan.Subscription, error) {
+	query := `
+		SELECT user_id, plan, status, customer_id, subscription_id, period_end, grace_plan, grace_until,
+			over_quota, event_at, updated_at
+		FROM aegis_vault_keeper.plan_subscriptions
+		WHERE user_id = $1
+	`
+	rows, err := r.db.Query(ctx, query, params.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load subscription: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("failed to load subscription: %w", err)
+		}
+		return nil, ErrSubscriptionNotFound
+	}
+	var (
+		s                     plan.Subscription
+		periodEnd, graceUntil sql.NullTime
+	)
+	if err := rows.Scan(
+		&s.UserID, &s.Plan, &s.Status, &s.CustomerID, &s.SubscriptionID, &periodEnd, &s.GracePlan, &graceUntil,
+		&s.OverQuota, &s.EventAt, &s.UpdatedAt,
+	); err != nil {
+		return nil, fmt.Errorf("failed to scan subscription: %w", err)
+	}
+	s.PeriodEnd = periodEnd.Time
+	s.GraceUntil = graceUntil.Time
+	return &s, nil
+}
+
+// SaveSubscription stores the subscription of the user. A stored subscription reflecting a later billing event
+// is kept, so events delivered out of order never roll a subscription back.
+func (r *Repository) SaveSubscription(ctx context.Context, params SaveSubscriptionParams) error {
+	e := params.Entity
+	query := `
+		INSERT INTO aegis_vault_keeper.plan_subscriptions (
+			user_id, plan, status, customer_id, subscription_id, period_end, grace_plan, grace_until,
+			over_quota, event_at, updated_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		ON CONFLICT (user_id) DO UPDATE SET
+			plan = EXCLUDED.plan,
+			status = EXCLUDED.status,
+			customer_id = EXCLUDED.customer_id,
+			subscription_id = EXCLUDED.subscription_id,
+			period_end = EXCLUDED.period_end,
+			grace_plan = EXCLUDED.grace_plan,
+			grace_until = EXCLUDED.grace_until,
+			over_quota = EXCLUDED.over_quota,
+			event_at = EXCLUDED.event_at,
+			updated_at = EXCLUDED.updated_at
+		WHERE plan_subscriptions.event_at <= EXCLUDED.event_at
+	`
+	_, err := r.db.Exec(ctx, query,
+		e.UserID, e.Plan, e.Status, e.CustomerID, e.SubscriptionID, nullTime(e.PeriodEnd), e.GracePlan,
+		nullTime(e.GraceUntil), e.OverQuota, e.EventAt, e.UpdatedAt,
+	)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == foreignKeyViolation {
+			return ErrSubscriptionUserNotFound
+		}
+		return fmt.Errorf("failed to save subscription: %w", err)
+	}
+	return nil
+}
+
+// HasEvent reports whether the billing event was already applied.
+func (r *Repository) HasEvent(ctx context.Context, params EventParams) (bool, error) {
+	rows, err := r.db.Query(ctx,
+		"SELECT EXISTS (SELECT 1 FROM aegis_vault_keeper.billing_events WHERE id = $1)", params.ID,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to look up billing event: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	// exists reports whether the event is stored.
+	var exists bool
+	if rows.Next() {
+		if err := rows.Scan(&exists); err != nil {
+			return false, fmt.Errorf("failed to scan billing event: %w", err)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return false, fmt.Errorf("failed to look up billing event: %w", err)
+	}
+	return exists, nil
+}
+
+// SaveEvent records the billing event as applied.
+func (r *Repository) SaveEvent(ctx context.Context, params EventParams) error {
+	query := `
+		INSERT INTO aegis_vault_keeper.billing_events (id, applied_at)
+		VALUES ($1, now())
+		ON CONFLICT (id) DO NOTHING
+	`
+	if _, err := r.db.Exec(ctx, query, params.ID); err != nil {
+		return fmt.Errorf("failed to save billing event: %w", err)
+	}
+	return nil
+}
+
+// nullTime converts the zero time to SQL NULL.
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
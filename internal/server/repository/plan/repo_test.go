@@ -0,0 +1,171 @@
+package plan
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/plan"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/db"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockResult implements sql.Result for testing.
+type mockResult struct {
+	rowsAffected int64
+}
+
+func (m mockResult) LastInsertId() (int64, error) { return 0, nil }
+func (m mockResult) RowsAffected() (int64, error) { return m.rowsAffected, nil }
+
+// mockDBClient implements db.DBClient for testing.
+type mockDBClient struct {
+	execFunc  func(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	queryFunc func(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+func (m *mockDBClient) Exec(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	if m.execFunc != nil {
+		return m.execFunc(ctx, query, args...)
+	}
+	return mockResult{rowsAffected: 1}, nil
+}
+
+func (m *mockDBClient) Query(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	if m.queryFunc != nil {
+		return m.queryFunc(ctx, query, args...)
+	}
+	return nil, errors.New("mock not configured")
+}
+
+func (m *mockDBClient) QueryRow(context.Context, string, ...interface{}) *sql.Row {
+	return nil
+}
+
+func (m *mockDBClient) BeginTx(context.Context, *sql.TxOptions) (*sql.Tx, error) {
+	return nil, errors.New("mock not configured")
+}
+
+func (m *mockDBClient) CommitTx(*sql.Tx) error {
+	return nil
+}
+
+func (m *mockDBClient) RollbackTx(*sql.Tx) error {
+	return nil
+}
+
+// Ensure mockDBClient implements db.DBClient.
+var _ db.DBClient = (*mockDBClient)(nil)
+
+func TestRepository_CountItems(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	queryErr := errors.New("query error")
+	var (
+		gotQuery string
+		gotArgs  []interface{}
+	)
+	client := &mockDBClient{
+		queryFunc: func(_ context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+			gotQuery, gotArgs = query, args
+			return nil, queryErr
+		},
+	}
+
+	_, err := NewRepository(client).CountItems(context.Background(), CountItemsParams{UserID: userID})
+
+	require.ErrorIs(t, err, queryErr)
+	assert.Equal(t, []interface{}{userID}, gotArgs)
+	for _, table := range []string{"credentials", "notes", "bank_cards", "files"} {
+		assert.Contains(t, gotQuery, "aegis_vault_keeper."+table+" WHERE user_id = $1")
+	}
+}
+
+func TestRepository_SaveSubscription(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	execErr := errors.New("exec error")
+	sub := &plan.Subscription{
+		UserID:         uuid.New(),
+		Plan:           plan.NamePremium,
+		Status:         plan.StatusActive,
+		CustomerID:     "cus_123",
+		SubscriptionID: "sub_123",
+		PeriodEnd:      now.Add(30 * 24 * time.Hour),
+		EventAt:        now,
+		UpdatedAt:      now,
+	}
+
+	tests := []struct {
+		execErr error
+		wantErr error
+		name    string
+	}{
+		{name: "success"},
+		{name: "unknown user", execErr: &pgconn.PgError{Code: foreignKeyViolation}, wantErr: ErrSubscriptionUserNotFound},
+		{name: "database error", execErr: execErr, wantErr: execErr},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				gotQuery string
+				gotArgs  []interface{}
+			)
+			client := &mockDBClient{
+				execFunc: func(_ context.Context, query string, args ...interface{}) (sql.Result, error) {
+					gotQuery, gotArgs = query, args
+					return mockResult{rowsAffected: 1}, tt.execErr
+				},
+			}
+
+			err := NewRepository(client).SaveSubscription(context.Background(), SaveSubscriptionParams{Entity: sub})
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Contains(t, gotQuery, "WHERE plan_subscriptions.event_at <= EXCLUDED.event_at")
+			require.Len(t, gotArgs, 11)
+			assert.Equal(t, sub.UserID, gotArgs[0])
+			assert.Equal(t, sql.NullTime{Time: sub.PeriodEnd, Valid: true}, gotArgs[5])
+			assert.Equal(t, sql.NullTime{}, gotArgs[7], "no grace period is stored as NULL")
+		})
+	}
+}
+
+func TestRepository_Events(t *testing.T) {
+	t.Parallel()
+
+	dbErr := errors.New("database error")
+	var gotArgs [][]interface{}
+	client := &mockDBClient{
+		execFunc: func(_ context.Context, _ string, args ...interface{}) (sql.Result, error) {
+			gotArgs = append(gotArgs, args)
+			return mockResult{}, nil
+		},
+		queryFunc: func(context.Context, string, ...interface{}) (*sql.Rows, error) {
+			return nil, dbErr
+		},
+	}
+	repo := NewRepository(client)
+
+	require.NoError(t, repo.SaveEvent(context.Background(), EventParams{ID: "evt_123"}))
+	assert.Equal(t, [][]interface{}{{"evt_123"}}, gotArgs)
+
+	_, err := repo.HasEvent(context.Background(), EventParams{ID: "evt_123"})
+	require.ErrorIs(t, err, dbErr)
+
+	_, err = repo.LoadSubscription(context.Background(), LoadSubscriptionParams{UserID: uuid.New()})
+	require.ErrorIs(t, err, dbErr)
+}
@@ -0,0 +1,5 @@
+// Package purge provides permanent deletion of vault items for the AegisVaultKeeper server.
+//
+// This package removes every item of one kind of a user from PostgreSQL together with the retained
+// versions, tags, paths, reveals, access requests, shares and rotations of the items.
+package purge
@@ -0,0 +1,9 @@
+package purge
+
+import "errors"
+
+// Purge repository error definitions.
+var (
+	// ErrUnknownKind indicates that items of the specified kind cannot be purged.
+	ErrUnknownKind = errors.New("unknown item kind")
+)
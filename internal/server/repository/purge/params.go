@@ -0,0 +1,33 @@
+package purge
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Item identifies a stored vault item and its revision.
+type Item struct {
+	// UpdatedAt is the time of the current revision of the item.
+	UpdatedAt time.Time
+	// ID identifies the item.
+	ID uuid.UUID
+}
+
+// LoadParams contains parameters for listing the items of one kind of a user.
+type LoadParams struct {
+	// Kind selects the kind of the items.
+	Kind string
+	// UserID identifies the owner of the items.
+	UserID uuid.UUID
+}
+
+// DeleteParams contains parameters for permanently deleting items of one kind of a user.
+type DeleteParams struct {
+	// IDs lists the items to delete.
+	IDs []uuid.UUID
+	// Kind selects the kind of the items.
+	Kind string
+	// UserID identifies the owner of the items.
+	UserID uuid.UUID
+}
@@ -0,0 +1,152 @@
+package purge
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/authz"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/db"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/history"
+)
+
+// linkedTable describes a table holding rows that belong to vault items.
+type linkedTable struct {
+	// name is the name of the table.
+	name string
+	// userColumn is the column identifying the owner of the item.
+	userColumn string
+	// itemColumn is the column identifying the item.
+	itemColumn string
+}
+
+// itemTables maps the kinds of items that can be purged to their tables.
+var itemTables = map[string]string{
+	authz.KindBankCard:   "bank_cards",
+	authz.KindCredential: "credentials",
+	authz.KindNote:       "notes",
+}
+
+// sharedLinks lists the tables holding rows of items of every kind.
+var sharedLinks = []linkedTable{
+	{name: "item_tags", userColumn: "user_id", itemColumn: "item_id"},
+	{name: "item_paths", userColumn: "user_id", itemColumn: "item_id"},
+	{name: "item_reveals", userColumn: "user_id", itemColumn: "item_id"},
+	{name: "access_requests", userColumn: "user_id", itemColumn: "item_id"},
+}
+
+// kindLinks lists the tables holding rows of items of a single kind. Note updates reference their notes
+// and are removed by the database together with them.
+var kindLinks = map[string][]linkedTable{
+	authz.KindCredential: {
+		{name: "credential_shares", userColumn: "owner_id", itemColumn: "credential_id"},
+		{name: "credential_rotations", userColumn: "user_id", itemColumn: "credential_id"},
+	},
+}
+
+// Repository provides permanent deletion of vault items.
+type Repository struct {
+	// db is the database client used for purge operations.
+	db db.DBClient
+}
+
+// NewRepository creates a new Repository with the provided database client.
+func NewRepository(dbClient db.DBClient) *Repository {
+	return &Repository{db: dbClient}
+}
+
+// Load lists the items of the kind of the user in ID order within the user scope.
+// Returns ErrUnknownKind when items of the kind cannot be purged.
+func (r *Repository) Load(ctx context.Context, params LoadParams) ([]*Item, error) {
+	// items holds the items loaded inside the user scope.
+	var items []*Item
+	err := db.InUserScope(ctx, r.db, params.UserID, func(ctx context.Context) error {
+		var err error
+		items, err = r.load(ctx, params)
+		return err
+	})
+	return items, err
+}
+
+// load lists the items of the kind of the user in ID order.
+func (r *Repository) load(ctx context.Context, params LoadParams) ([]*Item, error) {
+	table, ok := itemTables[params.Kind]
+	if !ok {
+		return nil, fmt.Errorf("failed to load %q items: %w", params.Kind, ErrUnknownKind)
+	}
+
+	query := fmt.Sprintf(
+		"SELECT id, updated_at FROM aegis_vault_keeper.%s WHERE user_id = $1 ORDER BY id",
+		table,
+	)
+	rows, err := r.db.Query(ctx, query, params.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s: %w", table, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var items []*Item
+	for rows.Next() {
+		var item Item
+		if err := rows.Scan(&item.ID, &item.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan %s: %w", table, err)
+		}
+		items = append(items, &item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate %s: %w", table, err)
+	}
+	return items, nil
+}
+
+// Delete permanently removes the listed items of the kind of the user together with their retained versions
+// and every row belonging to them, and returns the number of deleted items. The deletions are not atomic on
+// their own, so callers run them in a unit of work. The deletions run within the user scope.
+// Returns ErrUnknownKind when items of the kind cannot be purged.
+func (r *Repository) Delete(ctx context.Context, params DeleteParams) (int64, error) {
+	// n holds the number of items deleted inside the user scope.
+	var n int64
+	err := db.InUserScope(ctx, r.db, params.UserID, func(ctx context.Context) error {
+		var err error
+		n, err = r.delete(ctx, params)
+		return err
+	})
+	return n, err
+}
+
+// delete removes the listed items of the kind of the user and every row belonging to them.
+func (r *Repository) delete(ctx context.Context, params DeleteParams) (int64, error) {
+	table, ok := itemTables[params.Kind]
+	if !ok {
+		return 0, fmt.Errorf("failed to delete %q items: %w", params.Kind, ErrUnknownKind)
+	}
+	if len(params.IDs) == 0 {
+		return 0, nil
+	}
+
+	links := append([]linkedTable{{name: history.Table(table), userColumn: "user_id", itemColumn: "id"}},
+		sharedLinks...)
+	links = append(links, kindLinks[params.Kind]...)
+	for _, l := range links {
+		query := fmt.Sprintf(
+			"DELETE FROM aegis_vault_keeper.%s WHERE %s = $1 AND %s = ANY($2::uuid[])",
+			l.name, l.userColumn, l.itemColumn,
+		)
+		if _, err := r.db.Exec(ctx, query, params.UserID, params.IDs); err != nil {
+			return 0, fmt.Errorf("failed to delete %s of purged %s: %w", l.name, table, err)
+		}
+	}
+
+	query := fmt.Sprintf(
+		"DELETE FROM aegis_vault_keeper.%s WHERE user_id = $1 AND id = ANY($2::uuid[])",
+		table,
+	)
+	res, err := r.db.Exec(ctx, query, params.UserID, params.IDs)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete %s: %w", table, err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to count deleted %s: %w", table, err)
+	}
+	return n, nil
+}
@@ -0,0 +1,193 @@
+package purge
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/db"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockResult implements sql.Result for testing.
+type mockResult struct {
+	rowsAffected int64
+}
+
+func (m mockResult) LastInsertId() (int64, error) { return 0, nil }
+func (m mockResult) RowsAffected() (int64, error) { return m.rowsAffected, nil }
+
+// mockDBClient implements db.DBClient for testing.
+type mockDBClient struct {
+	execFunc  func(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	queryFunc func(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+func (m *mockDBClient) Exec(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	if m.execFunc != nil {
+		return m.execFunc(ctx, query, args...)
+	}
+	return mockResult{rowsAffected: 1}, nil
+}
+
+func (m *mockDBClient) Query(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	if m.queryFunc != nil {
+		return m.queryFunc(ctx, query, args...)
+	}
+	return nil, errors.New("mock not configured")
+}
+
+func (m *mockDBClient) QueryRow(context.Context, string, ...interface{}) *sql.Row {
+	return nil
+}
+
+func (m *mockDBClient) BeginTx(context.Context, *sql.TxOptions) (*sql.Tx, error) {
+	return nil, errors.New("mock not configured")
+}
+
+func (m *mockDBClient) CommitTx(*sql.Tx) error {
+	return nil
+}
+
+func (m *mockDBClient) RollbackTx(*sql.Tx) error {
+	return nil
+}
+
+// Ensure mockDBClient implements db.DBClient.
+var _ db.DBClient = (*mockDBClient)(nil)
+
+func TestRepository_Load(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	queryErr := errors.New("connection refused")
+
+	tests := []struct {
+		wantErr   error
+		name      string
+		kind      string
+		wantTable string
+	}{
+		{
+			name:      "query error",
+			kind:      "credential",
+			wantTable: "aegis_vault_keeper.credentials",
+			wantErr:   queryErr,
+		},
+		{
+			name:    "unknown kind",
+			kind:    "file",
+			wantErr: ErrUnknownKind,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := &mockDBClient{
+				queryFunc: func(_ context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+					assert.Contains(t, query, "FROM "+tt.wantTable+" WHERE user_id = $1")
+					assert.Equal(t, []interface{}{userID}, args)
+					return nil, queryErr
+				},
+			}
+
+			items, err := NewRepository(client).Load(context.Background(), LoadParams{Kind: tt.kind, UserID: userID})
+
+			require.ErrorIs(t, err, tt.wantErr)
+			assert.Nil(t, items)
+		})
+	}
+}
+
+func TestRepository_Delete(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	ids := []uuid.UUID{uuid.New(), uuid.New()}
+
+	tests := []struct {
+		execErr    error
+		wantErr    error
+		name       string
+		kind       string
+		wantTables []string
+		ids        []uuid.UUID
+		want       int64
+	}{
+		{
+			name: "credentials with shares and rotations",
+			kind: "credential",
+			ids:  ids,
+			wantTables: []string{
+				"credentials_history", "item_tags", "item_paths", "item_reveals", "access_requests",
+				"credential_shares", "credential_rotations", "credentials",
+			},
+			want: 2,
+		},
+		{
+			name: "notes",
+			kind: "note",
+			ids:  ids,
+			wantTables: []string{
+				"notes_history", "item_tags", "item_paths", "item_reveals", "access_requests", "notes",
+			},
+			want: 2,
+		},
+		{
+			name: "no items",
+			kind: "bankcard",
+		},
+		{
+			name:    "unknown kind",
+			kind:    "file",
+			ids:     ids,
+			wantErr: ErrUnknownKind,
+		},
+		{
+			name:       "exec error",
+			kind:       "bankcard",
+			ids:        ids,
+			execErr:    errors.New("connection refused"),
+			wantTables: []string{"bank_cards_history"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var tables []string
+			client := &mockDBClient{
+				execFunc: func(_ context.Context, query string, args ...interface{}) (sql.Result, error) {
+					table, _, _ := strings.Cut(strings.TrimPrefix(query, "DELETE FROM aegis_vault_keeper."), " ")
+					tables = append(tables, table)
+					assert.Equal(t, []interface{}{userID, tt.ids}, args)
+					return mockResult{rowsAffected: int64(len(tt.ids))}, tt.execErr
+				},
+			}
+
+			n, err := NewRepository(client).Delete(context.Background(), DeleteParams{
+				IDs:    tt.ids,
+				Kind:   tt.kind,
+				UserID: userID,
+			})
+
+			switch {
+			case tt.execErr != nil:
+				require.ErrorIs(t, err, tt.execErr)
+			case tt.wantErr != nil:
+				require.ErrorIs(t, err, tt.wantErr)
+			default:
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, n)
+			assert.Equal(t, tt.wantTables, tables)
+		})
+	}
+}
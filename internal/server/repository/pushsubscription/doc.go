@@ -0,0 +1,6 @@
+// Package pushsubscription provides web push subscription persistence for the AegisVaultKeeper server.
+//
+// This package implements storage of the push subscriptions of user devices in PostgreSQL. The
+// endpoints and keys are encrypted under the key of their owner; a digest of the endpoint keeps
+// a device from being subscribed twice.
+package pushsubscription
@@ -0,0 +1,11 @@
+package pushsubscription
+
+import "errors"
+
+// Push subscription repository error definitions.
+var (
+	// ErrSubscriptionNotFound indicates that the requested push subscription was not found in the repository.
+	ErrSubscriptionNotFound = errors.New("push subscription not found")
+	// ErrSubscriptionAlreadyExists indicates that the user already has a subscription for the same endpoint.
+	ErrSubscriptionAlreadyExists = errors.New("push subscription already exists")
+)
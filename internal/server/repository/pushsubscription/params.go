@@ -0,0 +1,28 @@
+package pushsubscription
+
+import (
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/notification"
+	"github.com/google/uuid"
+)
+
+// SaveParams contains the parameters for saving a push subscription to the repository.
+type SaveParams struct {
+	// Entity contains the subscription to be created or updated.
+	Entity *notification.PushSubscription
+}
+
+// LoadParams contains the parameters for loading push subscriptions from the repository.
+type LoadParams struct {
+	// ID selects a single subscription; uuid.Nil loads all subscriptions of the user.
+	ID uuid.UUID
+	// UserID identifies the owner of the subscriptions.
+	UserID uuid.UUID
+}
+
+// DeleteParams contains the parameters for deleting a push subscription from the repository.
+type DeleteParams struct {
+	// ID identifies the subscription to delete.
+	ID uuid.UUID
+	// UserID identifies the owner of the subscription.
+	UserID uuid.UUID
+}
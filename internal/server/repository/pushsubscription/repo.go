@@ -0,0 +1,196 @@
+package pushsubscription
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/notification"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/db"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/fieldcrypt"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/keyprv"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/securebytes"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// itemType identifies push subscriptions in the ownership binding of their encrypted endpoints and keys.
+const itemType = "push_subscription"
+
+// uniqueViolation is the PostgreSQL error code raised when the user already subscribed the endpoint.
+const uniqueViolation = "23505"
+
+// Repository provides push subscription persistence operations.
+type Repository struct {
+	// db is the database client used for subscription operations.
+	db db.DBClient
+	// keyProvider provides the user keys the subscriptions are encrypted with.
+	keyProvider keyprv.UserKeyProvider
+}
+
+// NewRepository creates a new Repository with the provided database client and user key provider.
+func NewRepository(dbClient db.DBClient, keyProvider keyprv.UserKeyProvider) *Repository {
+	return &Repository{db: dbClient, keyProvider: keyProvider}
+}
+
+// Save creates the subscription or updates its device name, keys and categories, encrypting the endpoint
+// and the keys with the key of the owner. Subscribing an endpoint twice returns ErrSubscriptionAlreadyExists.
+func (r *Repository) Save(ctx context.Context, params SaveParams) error {
+	e := params.Entity
+
+	plain, err := json.Marshal(e.Subscription)
+	if err != nil {
+		return fmt.Errorf("failed to encode push subscription: %w", err)
+	}
+	defer securebytes.Wipe(plain)
+
+	k, err := r.keyProvider.UserKeyProvide(ctx, e.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to provide user key: %w", err)
+	}
+	defer securebytes.Wipe(k)
+
+	b := fieldcrypt.Binding{ItemType: itemType, UserID: e.UserID, ItemID: e.ID}
+	subscription, err := b.Seal(k, "subscription", plain)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt push subscription: %w", err)
+	}
+	digest := sha256.Sum256([]byte(e.Subscription.Endpoint))
+
+	categories := make([]string, len(e.Categories))
+	for i, c := range e.Categories {
+		categories[i] = string(c)
+	}
+
+	query := `
+		INSERT INTO aegis_vault_keeper.push_subscriptions
+			(id, user_id, device, endpoint_digest, subscription, categories, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6::text[], $7, $8)
+		ON CONFLICT (id) DO UPDATE SET
+			device = EXCLUDED.device,
+			subscription = EXCLUDED.subscription,
+			categories = EXCLUDED.categories,
+			updated_at = EXCLUDED.updated_at
+	`
+	_, err = r.db.Exec(
+		ctx, query, e.ID, e.UserID, e.Device, digest[:], subscription, categories, e.CreatedAt, e.UpdatedAt,
+	)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+			return ErrSubscriptionAlreadyExists
+		}
+		return fmt.Errorf("failed to save push subscription: %w", err)
+	}
+	return nil
+}
+
+// Load retrieves the push subscriptions of the user with decrypted endpoints and keys, oldest first.
+// Loading by ID returns ErrSubscriptionNotFound when the user has no such subscription.
+func (r *Repository) Load(ctx context.Context, params LoadParams) ([]*notification.PushSubscription, error) {
+	query := `
+		SELECT id, user_id, device, subscription, array_to_string(categories, ','), created_at, updated_at
+		FROM aegis_vault_keeper.push_subscriptions
+		WHERE user_id = $1
+	`
+	args := []any{params.UserID}
+	if params.ID != uuid.Nil {
+		query += " AND id = $2"
+		args = append(args, params.ID)
+	}
+	query += " ORDER BY created_at, id"
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load push subscriptions: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var (
+		subscriptions []*notification.PushSubscription
+		sealed        [][]byte
+	)
+	for rows.Next() {
+		var (
+			s            notification.PushSubscription
+			subscription []byte
+			categories   string
+		)
+		err := rows.Scan(&s.ID, &s.UserID, &s.Device, &subscription, &categories, &s.CreatedAt, &s.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan push subscription: %w", err)
+		}
+		for _, category := range strings.Split(categories, ",") {
+			if category != "" {
+				s.Categories = append(s.Categories, notification.Category(category))
+			}
+		}
+		subscriptions = append(subscriptions, &s)
+		sealed = append(sealed, subscription)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate push subscriptions: %w", err)
+	}
+	if params.ID != uuid.Nil && len(subscriptions) == 0 {
+		return nil, ErrSubscriptionNotFound
+	}
+	if len(subscriptions) == 0 {
+		return subscriptions, nil
+	}
+
+	if err := r.decrypt(ctx, params.UserID, subscriptions, sealed); err != nil {
+		return nil, err
+	}
+	return subscriptions, nil
+}
+
+// Delete removes the push subscription from the user.
+func (r *Repository) Delete(ctx context.Context, params DeleteParams) error {
+	query := `
+		DELETE FROM aegis_vault_keeper.push_subscriptions
+		WHERE user_id = $1 AND id = $2
+	`
+	res, err := r.db.Exec(ctx, query, params.UserID, params.ID)
+	if err != nil {
+		return fmt.Errorf("failed to delete push subscription: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check deleted push subscriptions: %w", err)
+	}
+	if n == 0 {
+		return ErrSubscriptionNotFound
+	}
+	return nil
+}
+
+// decrypt sets the endpoints and keys of the subscriptions from their sealed form.
+func (r *Repository) decrypt(
+	ctx context.Context,
+	userID uuid.UUID,
+	subscriptions []*notification.PushSubscription,
+	sealed [][]byte,
+) error {
+	k, err := r.keyProvider.UserKeyProvide(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to provide user key: %w", err)
+	}
+	defer securebytes.Wipe(k)
+
+	for i, s := range subscriptions {
+		b := fieldcrypt.Binding{ItemType: itemType, UserID: userID, ItemID: s.ID}
+		plain, err := b.Open(k, "subscription", sealed[i])
+		if err != nil {
+			return fmt.Errorf("failed to decrypt push subscription: %w", err)
+		}
+		err = json.Unmarshal(plain, &s.Subscription)
+		securebytes.Wipe(plain)
+		if err != nil {
+			return fmt.Errorf("failed to decode push subscription: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,271 @@
+package pushsubscription
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/notification"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/db"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/fieldcrypt"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockResult implements sql.Result for testing.
+type mockResult struct {
+	rowsAffected int64
+}
+
+func (m mockResult) LastInsertId() (int64, error) { return 0, nil }
+func (m mockResult) RowsAffected() (int64, error) { return m.rowsAffected, nil }
+
+// mockDBClient implements db.DBClient for testing.
+type mockDBClient struct {
+	execFunc  func(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	queryFunc func(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+func (m *mockDBClient) Exec(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	if m.execFunc != nil {
+		return m.execFunc(ctx, query, args...)
+	}
+	return mockResult{rowsAffected: 1}, nil
+}
+
+func (m *mockDBClient) Query(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	if m.queryFunc != nil {
+		return m.queryFunc(ctx, query, args...)
+	}
+	return nil, errors.New("mock not configured")
+}
+
+func (m *mockDBClient) QueryRow(context.Context, string, ...interface{}) *sql.Row {
+	return nil
+}
+
+func (m *mockDBClient) BeginTx(context.Context, *sql.TxOptions) (*sql.Tx, error) {
+	return nil, errors.New("mock not configured")
+}
+
+func (m *mockDBClient) CommitTx(*sql.Tx) error {
+	return nil
+}
+
+func (m *mockDBClient) RollbackTx(*sql.Tx) error {
+	return nil
+}
+
+// Ensure mockDBClient implements db.DBClient.
+var _ db.DBClient = (*mockDBClient)(nil)
+
+// mockKeyProvider implements keyprv.UserKeyProvider for testing.
+type mockKeyProvider struct {
+	err error
+	key []byte
+}
+
+func (m *mockKeyProvider) UserKeyProvide(context.Context, uuid.UUID) ([]byte, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return bytes.Clone(m.key), nil
+}
+
+func TestRepository_Save(t *testing.T) {
+	t.Parallel()
+
+	userKey := bytes.Repeat([]byte{0x01}, 32)
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	subscription := &notification.PushSubscription{
+		ID:     uuid.New(),
+		UserID: uuid.New(),
+		Device: "Laptop",
+		Subscription: notification.WebPushSubscription{
+			Endpoint: "https://push.example.com/send/secret-token",
+			Keys:     notification.WebPushKeys{P256dh: "BPublicKey", Auth: "AuthSecret"},
+		},
+		Categories: []notification.Category{notification.CategorySecurity},
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+
+	tests := []struct {
+		keyErr  error
+		execErr error
+		wantErr error
+		name    string
+	}{
+		{
+			name: "subscription encrypted",
+		},
+		{
+			name:   "key provider error",
+			keyErr: errors.New("user not found"),
+		},
+		{
+			name:    "endpoint already subscribed",
+			execErr: &pgconn.PgError{Code: uniqueViolation},
+			wantErr: ErrSubscriptionAlreadyExists,
+		},
+		{
+			name:    "exec error",
+			execErr: errors.New("connection refused"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var gotArgs []interface{}
+			client := &mockDBClient{
+				execFunc: func(_ context.Context, query string, args ...interface{}) (sql.Result, error) {
+					assert.Contains(t, query, "INSERT INTO aegis_vault_keeper.push_subscriptions")
+					gotArgs = args
+					return mockResult{rowsAffected: 1}, tt.execErr
+				},
+			}
+			repo := NewRepository(client, &mockKeyProvider{key: userKey, err: tt.keyErr})
+
+			err := repo.Save(context.Background(), SaveParams{Entity: subscription})
+
+			switch {
+			case tt.keyErr != nil:
+				require.ErrorIs(t, err, tt.keyErr)
+				assert.Nil(t, gotArgs)
+				return
+			case tt.wantErr != nil:
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			case tt.execErr != nil:
+				require.ErrorIs(t, err, tt.execErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, gotArgs, 8)
+			assert.Equal(t, []interface{}{subscription.ID, subscription.UserID, "Laptop"}, gotArgs[:3])
+			digest := sha256.Sum256([]byte(subscription.Subscription.Endpoint))
+			assert.Equal(t, digest[:], gotArgs[3])
+			assert.Equal(t, []string{"security"}, gotArgs[5])
+			assert.Equal(t, []interface{}{now, now}, gotArgs[6:])
+
+			sealed, ok := gotArgs[4].([]byte)
+			require.True(t, ok)
+			assert.NotContains(t, string(sealed), "secret-token")
+			b := fieldcrypt.Binding{ItemType: itemType, UserID: subscription.UserID, ItemID: subscription.ID}
+			plain, err := b.Open(userKey, "subscription", sealed)
+			require.NoError(t, err)
+			var got notification.WebPushSubscription
+			require.NoError(t, json.Unmarshal(plain, &got))
+			assert.Equal(t, subscription.Subscription, got)
+		})
+	}
+}
+
+func TestRepository_LoadQuery(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	id := uuid.New()
+
+	tests := []struct {
+		name      string
+		wantWhere string
+		wantArgs  []interface{}
+		params    LoadParams
+	}{
+		{
+			name:      "all subscriptions of user",
+			params:    LoadParams{UserID: userID},
+			wantWhere: "WHERE user_id = $1\n\t ORDER BY",
+			wantArgs:  []interface{}{userID},
+		},
+		{
+			name:      "single subscription",
+			params:    LoadParams{ID: id, UserID: userID},
+			wantWhere: "AND id = $2 ORDER BY",
+			wantArgs:  []interface{}{userID, id},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			queryErr := errors.New("query error")
+			var gotQuery string
+			var gotArgs []interface{}
+			client := &mockDBClient{
+				queryFunc: func(_ context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+					gotQuery, gotArgs = query, args
+					return nil, queryErr
+				},
+			}
+
+			_, err := NewRepository(client, &mockKeyProvider{}).Load(context.Background(), tt.params)
+
+			require.ErrorIs(t, err, queryErr)
+			assert.Contains(t, gotQuery, tt.wantWhere)
+			assert.Equal(t, tt.wantArgs, gotArgs)
+		})
+	}
+}
+
+func TestRepository_Delete(t *testing.T) {
+	t.Parallel()
+
+	params := DeleteParams{ID: uuid.New(), UserID: uuid.New()}
+
+	tests := []struct {
+		execErr      error
+		wantErr      error
+		name         string
+		rowsAffected int64
+	}{
+		{
+			name:         "deleted",
+			rowsAffected: 1,
+		},
+		{
+			name:    "not found",
+			wantErr: ErrSubscriptionNotFound,
+		},
+		{
+			name:    "exec error",
+			execErr: errors.New("connection refused"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := &mockDBClient{
+				execFunc: func(_ context.Context, query string, args ...interface{}) (sql.Result, error) {
+					assert.Contains(t, query, "DELETE FROM aegis_vault_keeper.push_subscriptions")
+					assert.Equal(t, []interface{}{params.UserID, params.ID}, args)
+					return mockResult{rowsAffected: tt.rowsAffected}, tt.execErr
+				},
+			}
+
+			err := NewRepository(client, &mockKeyProvider{}).Delete(context.Background(), params)
+
+			switch {
+			case tt.execErr != nil:
+				assert.ErrorIs(t, err, tt.execErr)
+			case tt.wantErr != nil:
+				assert.ErrorIs(t, err, tt.wantErr)
+			default:
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
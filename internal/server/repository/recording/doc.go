@@ -0,0 +1,6 @@
+// Package recording provides persistence of debugging recordings for the AegisVaultKeeper server.
+//
+// This package stores the recording sessions started by administrators and the sanitized request/response
+// pairs captured for them in PostgreSQL. Captured pairs are encrypted with a key derived from the master key
+// and bound to their session, so they cannot be read from the database or moved between sessions.
+package recording
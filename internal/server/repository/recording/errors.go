@@ -0,0 +1,9 @@
+package recording
+
+import "errors"
+
+// Recording repository error definitions.
+var (
+	// ErrSessionNotFound indicates that no recording session with the specified ID exists.
+	ErrSessionNotFound = errors.New("recording session not found")
+)
@@ -0,0 +1,80 @@
+package recording
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Session is a stored recording session.
+type Session struct {
+	// StartedAt is the time the recording started.
+	StartedAt time.Time
+	// ExpiresAt is the time the recording stops capturing requests.
+	ExpiresAt time.Time
+	// ID identifies the session.
+	ID uuid.UUID
+	// UserID selects the requests of a single user; uuid.Nil records the requests of every user.
+	UserID uuid.UUID
+	// Route selects the requests whose path starts with the prefix; empty records every path.
+	Route string
+	// Reason explains why the recording was started, such as a support case reference.
+	Reason string
+	// Exchanges is the number of request/response pairs captured for the session.
+	Exchanges int
+}
+
+// Exchange is a stored request/response pair, sanitized by the application layer.
+type Exchange struct {
+	// RecordedAt is the time the request was received.
+	RecordedAt time.Time
+	// RequestHeaders contains the request headers.
+	RequestHeaders map[string]string
+	// ResponseHeaders contains the response headers.
+	ResponseHeaders map[string]string
+	// ID identifies the pair.
+	ID uuid.UUID
+	// UserID identifies the authenticated user; uuid.Nil for anonymous requests.
+	UserID uuid.UUID
+	// RequestID identifies the request in the logs.
+	RequestID string
+	// Method is the HTTP method of the request.
+	Method string
+	// Path is the URL path of the request.
+	Path string
+	// Route is the route pattern that handled the request.
+	Route string
+	// Query is the query string of the request.
+	Query string
+	// RequestBody is the request body.
+	RequestBody string
+	// ResponseBody is the response body.
+	ResponseBody string
+	// Duration is how long the request took to handle.
+	Duration time.Duration
+	// Status is the HTTP status code of the response.
+	Status int
+}
+
+// SaveSessionParams contains the parameters for saving a recording session to the repository.
+type SaveSessionParams struct {
+	// Session contains the session to be persisted; an existing session is updated with its expiry time.
+	Session Session
+}
+
+// LoadSessionsParams contains the parameters for loading recording sessions from the repository.
+// Zero fields do not filter.
+type LoadSessionsParams struct {
+	// ActiveAt selects sessions that started before and expire after the moment.
+	ActiveAt time.Time
+	// ID selects a single session.
+	ID uuid.UUID
+}
+
+// SaveExchangeParams contains the parameters for saving a captured request/response pair to the repository.
+type SaveExchangeParams struct {
+	// Exchange contains the pair to be persisted; everything but its ID and time is encrypted.
+	Exchange Exchange
+	// SessionID identifies the session the pair was captured for.
+	SessionID uuid.UUID
+}
@@ -0,0 +1,73 @@
+package recording
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// exchangeRecord is the encrypted part of a stored request/response pair.
+type exchangeRecord struct {
+	// RequestHeaders contains the request headers.
+	RequestHeaders map[string]string `json:"request_headers,omitempty"`
+	// ResponseHeaders contains the response headers.
+	ResponseHeaders map[string]string `json:"response_headers,omitempty"`
+	// UserID identifies the authenticated user; uuid.Nil for anonymous requests.
+	UserID uuid.UUID `json:"user_id"`
+	// RequestID identifies the request in the logs.
+	RequestID string `json:"request_id,omitempty"`
+	// Method is the HTTP method of the request.
+	Method string `json:"method"`
+	// Path is the URL path of the request.
+	Path string `json:"path"`
+	// Route is the route pattern that handled the request.
+	Route string `json:"route,omitempty"`
+	// Query is the query string of the request.
+	Query string `json:"query,omitempty"`
+	// RequestBody is the request body.
+	RequestBody string `json:"request_body,omitempty"`
+	// ResponseBody is the response body.
+	ResponseBody string `json:"response_body,omitempty"`
+	// Duration is how long the request took to handle.
+	Duration time.Duration `json:"duration"`
+	// Status is the HTTP status code of the response.
+	Status int `json:"status"`
+}
+
+// newExchangeRecord builds the encrypted part of the pair.
+func newExchangeRecord(e *Exchange) *exchangeRecord {
+	return &exchangeRecord{
+		RequestHeaders:  e.RequestHeaders,
+		ResponseHeaders: e.ResponseHeaders,
+		UserID:          e.UserID,
+		RequestID:       e.RequestID,
+		Method:          e.Method,
+		Path:            e.Path,
+		Route:           e.Route,
+		Query:           e.Query,
+		RequestBody:     e.RequestBody,
+		ResponseBody:    e.ResponseBody,
+		Duration:        e.Duration,
+		Status:          e.Status,
+	}
+}
+
+// exchange restores the pair stored with the ID at the time.
+func (r *exchangeRecord) exchange(id uuid.UUID, recordedAt time.Time) *Exchange {
+	return &Exchange{
+		RecordedAt:      recordedAt,
+		RequestHeaders:  r.RequestHeaders,
+		ResponseHeaders: r.ResponseHeaders,
+		ID:              id,
+		UserID:          r.UserID,
+		RequestID:       r.RequestID,
+		Method:          r.Method,
+		Path:            r.Path,
+		Route:           r.Route,
+		Query:           r.Query,
+		RequestBody:     r.RequestBody,
+		ResponseBody:    r.ResponseBody,
+		Duration:        r.Duration,
+		Status:          r.Status,
+	}
+}
@@ -0,0 +1,206 @@
+package recording
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/crypto"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/db"
+	"github.com/google/uuid"
+)
+
+// exchangeAADDomain separates the additional data of captured pairs from other ciphertexts sealed with the key.
+const exchangeAADDomain = "recorded-exchange"
+
+// Repository provides recording session and captured pair persistence operations.
+type Repository struct {
+	// db is the database client used for recording operations.
+	db db.DBClient
+	// key encrypts the captured pairs.
+	key []byte
+}
+
+// NewRepository creates a new Repository with the provided database client and the key captured pairs are
+// encrypted with.
+func NewRepository(dbClient db.DBClient, key []byte) *Repository {
+	return &Repository{db: dbClient, key: key}
+}
+
+// SaveSession stores a new recording session or updates the expiry time of an existing one.
+func (r *Repository) SaveSession(ctx context.Context, params SaveSessionParams) error {
+	s := params.Session
+
+	// userID stays NULL for sessions recording the requests of every user.
+	var userID uuid.NullUUID
+	if s.UserID != uuid.Nil {
+		userID = uuid.NullUUID{UUID: s.UserID, Valid: true}
+	}
+
+	query := `
+		INSERT INTO aegis_vault_keeper.recording_sessions (id, user_id, route, reason, started_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (id) DO UPDATE SET expires_at = EXCLUDED.expires_at
+	`
+	if _, err := r.db.Exec(ctx, query, s.ID, userID, s.Route, s.Reason, s.StartedAt, s.ExpiresAt); err != nil {
+		return fmt.Errorf("failed to save recording session: %w", err)
+	}
+	return nil
+}
+
+// LoadSessions retrieves recording sessions matching the provided parameters with the number of pairs captured
+// for them, most recent first.
+func (r *Repository) LoadSessions(ctx context.Context, params LoadSessionsParams) ([]*Session, error) {
+	var (
+		conditions []string
+		args       []interface{}
+	)
+	// filter adds a condition comparing a column with the next positional argument.
+	filter := func(condition string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	if params.ID != uuid.Nil {
+		filter("s.id = $%d", params.ID)
+	}
+	if !params.ActiveAt.IsZero() {
+		filter("s.started_at <= $%[1]d AND s.expires_at > $%[1]d", params.ActiveAt)
+	}
+
+	query := `
+		SELECT s.id, s.user_id, s.route, s.reason, s.started_at, s.expires_at,
+			(SELECT count(*) FROM aegis_vault_keeper.recorded_exchanges e WHERE e.session_id = s.id)
+		FROM aegis_vault_keeper.recording_sessions s
+	`
+	if len(conditions) != 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY s.started_at DESC, s.id"
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load recording sessions: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var sessions []*Session
+	for rows.Next() {
+		var (
+			s      Session
+			userID uuid.NullUUID
+		)
+		if err := rows.Scan(&s.ID, &userID, &s.Route, &s.Reason, &s.StartedAt, &s.ExpiresAt, &s.Exchanges); err != nil {
+			return nil, fmt.Errorf("failed to scan recording session: %w", err)
+		}
+		s.UserID = userID.UUID
+		sessions = append(sessions, &s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate recording sessions: %w", err)
+	}
+	return sessions, nil
+}
+
+// DeleteSession removes the recording session together with the pairs captured for it.
+// Returns ErrSessionNotFound when no such session exists.
+func (r *Repository) DeleteSession(ctx context.Context, id uuid.UUID) error {
+	query := `DELETE FROM aegis_vault_keeper.recording_sessions WHERE id = $1`
+	res, err := r.db.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete recording session: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check deleted recording sessions: %w", err)
+	}
+	if n == 0 {
+		return ErrSessionNotFound
+	}
+	return nil
+}
+
+// DeleteExpired removes the recording sessions that expired before the moment together with their pairs and
+// returns the number of removed sessions.
+func (r *Repository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	query := `DELETE FROM aegis_vault_keeper.recording_sessions WHERE expires_at < $1`
+	res, err := r.db.Exec(ctx, query, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired recording sessions: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to check deleted recording sessions: %w", err)
+	}
+	return n, nil
+}
+
+// SaveExchange encrypts and stores a captured request/response pair.
+func (r *Repository) SaveExchange(ctx context.Context, params SaveExchangeParams) error {
+	e := params.Exchange
+
+	plaintext, err := json.Marshal(newExchangeRecord(&e))
+	if err != nil {
+		return fmt.Errorf("failed to encode recorded exchange: %w", err)
+	}
+	data, err := crypto.Seal(r.key, plaintext, exchangeAAD(params.SessionID, e.ID))
+	if err != nil {
+		return fmt.Errorf("failed to encrypt recorded exchange: %w", err)
+	}
+
+	query := `
+		INSERT INTO aegis_vault_keeper.recorded_exchanges (id, session_id, recorded_at, data)
+		VALUES ($1, $2, $3, $4)
+	`
+	if _, err := r.db.Exec(ctx, query, e.ID, params.SessionID, e.RecordedAt, data); err != nil {
+		return fmt.Errorf("failed to save recorded exchange: %w", err)
+	}
+	return nil
+}
+
+// LoadExchanges retrieves and decrypts the pairs captured for the recording session in the order they were
+// captured.
+func (r *Repository) LoadExchanges(ctx context.Context, sessionID uuid.UUID) ([]*Exchange, error) {
+	query := `
+		SELECT id, recorded_at, data
+		FROM aegis_vault_keeper.recorded_exchanges
+		WHERE session_id = $1
+		ORDER BY recorded_at, id
+	`
+	rows, err := r.db.Query(ctx, query, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load recorded exchanges: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var exchanges []*Exchange
+	for rows.Next() {
+		var (
+			id         uuid.UUID
+			recordedAt time.Time
+			data       []byte
+		)
+		if err := rows.Scan(&id, &recordedAt, &data); err != nil {
+			return nil, fmt.Errorf("failed to scan recorded exchange: %w", err)
+		}
+		plaintext, err := crypto.Open(r.key, data, exchangeAAD(sessionID, id))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt recorded exchange %s: %w", id, err)
+		}
+		var record exchangeRecord
+		if err := json.Unmarshal(plaintext, &record); err != nil {
+			return nil, fmt.Errorf("failed to decode recorded exchange %s: %w", id, err)
+		}
+		exchanges = append(exchanges, record.exchange(id, recordedAt))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate recorded exchanges: %w", err)
+	}
+	return exchanges, nil
+}
+
+// exchangeAAD builds the additional authenticated data binding a captured pair to its session.
+func exchangeAAD(sessionID, id uuid.UUID) []byte {
+	return crypto.BuildAAD(exchangeAADDomain, sessionID.String(), id.String())
+}
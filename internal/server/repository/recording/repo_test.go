@@ -0,0 +1,282 @@
+package recording
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/crypto"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/db"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testKey is the key captured pairs are encrypted with in tests.
+var testKey = []byte("12345678901234567890123456789012")
+
+// mockResult implements sql.Result for testing.
+type mockResult struct {
+	rowsAffected int64
+}
+
+func (m mockResult) LastInsertId() (int64, error) { return 0, nil }
+func (m mockResult) RowsAffected() (int64, error) { return m.rowsAffected, nil }
+
+// mockDBClient implements db.DBClient for testing.
+type mockDBClient struct {
+	execFunc  func(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	queryFunc func(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+func (m *mockDBClient) Exec(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	if m.execFunc != nil {
+		return m.execFunc(ctx, query, args...)
+	}
+	return mockResult{rowsAffected: 1}, nil
+}
+
+func (m *mockDBClient) Query(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	if m.queryFunc != nil {
+		return m.queryFunc(ctx, query, args...)
+	}
+	return nil, errors.New("mock not configured")
+}
+
+func (m *mockDBClient) QueryRow(context.Context, string, ...interface{}) *sql.Row {
+	return nil
+}
+
+func (m *mockDBClient) BeginTx(context.Context, *sql.TxOptions) (*sql.Tx, error) {
+	return nil, errors.New("mock not configured")
+}
+
+func (m *mockDBClient) CommitTx(*sql.Tx) error {
+	return nil
+}
+
+func (m *mockDBClient) RollbackTx(*sql.Tx) error {
+	return nil
+}
+
+// Ensure mockDBClient implements db.DBClient.
+var _ db.DBClient = (*mockDBClient)(nil)
+
+func TestRepository_SaveSession(t *testing.T) {
+	t.Parallel()
+
+	startedAt := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	expiresAt := startedAt.Add(time.Hour)
+	id := uuid.New()
+	userID := uuid.New()
+
+	tests := []struct {
+		name     string
+		wantArgs []interface{}
+		session  Session
+	}{
+		{
+			name: "session of a user",
+			session: Session{
+				StartedAt: startedAt, ExpiresAt: expiresAt, ID: id, UserID: userID, Reason: "case 42",
+			},
+			wantArgs: []interface{}{
+				id, uuid.NullUUID{UUID: userID, Valid: true}, "", "case 42", startedAt, expiresAt,
+			},
+		},
+		{
+			name:     "session of a route",
+			session:  Session{StartedAt: startedAt, ExpiresAt: expiresAt, ID: id, Route: "/api/items"},
+			wantArgs: []interface{}{id, uuid.NullUUID{}, "/api/items", "", startedAt, expiresAt},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// gotArgs holds the arguments of the executed statement.
+			var gotArgs []interface{}
+			repo := NewRepository(&mockDBClient{
+				execFunc: func(_ context.Context, _ string, args ...interface{}) (sql.Result, error) {
+					gotArgs = args
+					return mockResult{rowsAffected: 1}, nil
+				},
+			}, testKey)
+
+			err := repo.SaveSession(context.Background(), SaveSessionParams{Session: tt.session})
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantArgs, gotArgs)
+		})
+	}
+}
+
+func TestRepository_LoadSessionsQuery(t *testing.T) {
+	t.Parallel()
+
+	id := uuid.New()
+	at := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		wantWhere string
+		wantArgs  []interface{}
+		params    LoadSessionsParams
+	}{
+		{
+			name:      "all sessions",
+			wantWhere: "recording_sessions s\n\t ORDER BY",
+		},
+		{
+			name:      "active sessions",
+			params:    LoadSessionsParams{ActiveAt: at},
+			wantWhere: "WHERE s.started_at <= $1 AND s.expires_at > $1 ORDER BY",
+			wantArgs:  []interface{}{at},
+		},
+		{
+			name:      "single active session",
+			params:    LoadSessionsParams{ActiveAt: at, ID: id},
+			wantWhere: "WHERE s.id = $1 AND s.started_at <= $2 AND s.expires_at > $2 ORDER BY",
+			wantArgs:  []interface{}{id, at},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			queryErr := errors.New("query error")
+			// gotQuery holds the executed query.
+			var gotQuery string
+			// gotArgs holds the arguments of the executed query.
+			var gotArgs []interface{}
+			repo := NewRepository(&mockDBClient{
+				queryFunc: func(_ context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+					gotQuery, gotArgs = query, args
+					return nil, queryErr
+				},
+			}, testKey)
+
+			_, err := repo.LoadSessions(context.Background(), tt.params)
+
+			require.ErrorIs(t, err, queryErr)
+			assert.Contains(t, gotQuery, tt.wantWhere)
+			assert.Equal(t, tt.wantArgs, gotArgs)
+		})
+	}
+}
+
+func TestRepository_DeleteSession(t *testing.T) {
+	t.Parallel()
+
+	id := uuid.New()
+
+	tests := []struct {
+		execErr      error
+		wantErr      error
+		name         string
+		rowsAffected int64
+	}{
+		{
+			name:         "deleted",
+			rowsAffected: 1,
+		},
+		{
+			name:    "not found",
+			wantErr: ErrSessionNotFound,
+		},
+		{
+			name:    "exec error",
+			execErr: errors.New("connection refused"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := NewRepository(&mockDBClient{
+				execFunc: func(_ context.Context, query string, args ...interface{}) (sql.Result, error) {
+					assert.Contains(t, query, "DELETE FROM aegis_vault_keeper.recording_sessions")
+					assert.Equal(t, []interface{}{id}, args)
+					return mockResult{rowsAffected: tt.rowsAffected}, tt.execErr
+				},
+			}, testKey)
+
+			err := repo.DeleteSession(context.Background(), id)
+
+			switch {
+			case tt.execErr != nil:
+				assert.ErrorIs(t, err, tt.execErr)
+			case tt.wantErr != nil:
+				assert.ErrorIs(t, err, tt.wantErr)
+			default:
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRepository_DeleteExpired(t *testing.T) {
+	t.Parallel()
+
+	before := time.Date(2026, 10, 8, 12, 0, 0, 0, time.UTC)
+	repo := NewRepository(&mockDBClient{
+		execFunc: func(_ context.Context, query string, args ...interface{}) (sql.Result, error) {
+			assert.Contains(t, query, "WHERE expires_at < $1")
+			assert.Equal(t, []interface{}{before}, args)
+			return mockResult{rowsAffected: 3}, nil
+		},
+	}, testKey)
+
+	n, err := repo.DeleteExpired(context.Background(), before)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), n)
+}
+
+func TestRepository_SaveExchange(t *testing.T) {
+	t.Parallel()
+
+	recordedAt := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	params := SaveExchangeParams{
+		Exchange: Exchange{
+			RecordedAt: recordedAt,
+			ID:         uuid.New(),
+			Method:     "GET",
+			Path:       "/api/items/notes",
+			Status:     200,
+		},
+		SessionID: uuid.New(),
+	}
+	// gotArgs holds the arguments of the executed statement.
+	var gotArgs []interface{}
+	repo := NewRepository(&mockDBClient{
+		execFunc: func(_ context.Context, _ string, args ...interface{}) (sql.Result, error) {
+			gotArgs = args
+			return mockResult{rowsAffected: 1}, nil
+		},
+	}, testKey)
+
+	err := repo.SaveExchange(context.Background(), params)
+
+	require.NoError(t, err)
+	require.Len(t, gotArgs, 4)
+	assert.Equal(t, []interface{}{params.Exchange.ID, params.SessionID, recordedAt}, gotArgs[:3])
+	stored, ok := gotArgs[3].([]byte)
+	require.True(t, ok)
+	assert.NotContains(t, string(stored), "/api/items/notes")
+
+	plaintext, err := crypto.Open(testKey, stored, exchangeAAD(params.SessionID, params.Exchange.ID))
+	require.NoError(t, err)
+	var record exchangeRecord
+	require.NoError(t, json.Unmarshal(plaintext, &record))
+	assert.Equal(t, &params.Exchange, record.exchange(params.Exchange.ID, recordedAt))
+
+	_, err = crypto.Open(testKey, stored, exchangeAAD(uuid.New(), params.Exchange.ID))
+	assert.Error(t, err, "pairs must not open under another session")
+}
@@ -0,0 +1,5 @@
+// Package retention provides data retention persistence for the AegisVaultKeeper server.
+//
+// This package implements storage of the retention overrides of directory groups in PostgreSQL, and counts
+// and deletes the replaced item versions and item reveal records a retention policy does not keep.
+package retention
@@ -0,0 +1,6 @@
+package retention
+
+import "errors"
+
+// ErrOverrideNotFound indicates that the requested retention override was not found in the repository.
+var ErrOverrideNotFound = errors.New("retention override not found")
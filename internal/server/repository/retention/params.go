@@ -0,0 +1,36 @@
+package retention
+
+import (
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/retention"
+	"github.com/google/uuid"
+)
+
+// SaveParams contains the parameters for saving a retention override to the repository.
+type SaveParams struct {
+	// Entity contains the retention override to be persisted.
+	Entity *retention.Override
+}
+
+// DeleteParams contains the parameters for deleting a retention override from the repository.
+type DeleteParams struct {
+	// GroupID identifies the group of the override to delete.
+	GroupID uuid.UUID
+}
+
+// ScopeParams contains the parameters selecting the data a retention policy does not keep.
+// UserIDs and ExcludeUserIDs are exclusive; when neither is set, the data of every user is selected.
+type ScopeParams struct {
+	// AuditBefore selects the item reveal records older than this moment; zero selects none.
+	AuditBefore time.Time
+	// UserIDs restricts the selection to the data of these users when non-nil.
+	UserIDs []uuid.UUID
+	// ExcludeUserIDs leaves the data of these users out of the selection when non-nil.
+	ExcludeUserIDs []uuid.UUID
+	// ExcludeItemIDs leaves the versions and reveal records of these items out of the selection when non-nil.
+	ExcludeItemIDs []uuid.UUID
+	// KeepVersions selects the replaced versions of every item older than the latest KeepVersions; zero selects
+	// none.
+	KeepVersions int
+}
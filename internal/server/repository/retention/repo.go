@@ -0,0 +1,194 @@
+package retention
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/retention"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/db"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/history"
+)
+
+// Repository provides retention override persistence and retention enforcement operations.
+type Repository struct {
+	// db is the database client used for retention operations.
+	db db.DBClient
+	// itemTables lists the versioned item tables whose history is subject to retention.
+	itemTables []string
+}
+
+// NewRepository creates a new Repository with the provided database client enforcing retention on the history
+// of the specified item tables.
+func NewRepository(dbClient db.DBClient, itemTables ...string) *Repository {
+	return &Repository{db: dbClient, itemTables: itemTables}
+}
+
+// Save creates the retention override or updates the existing override of the same group.
+func (r *Repository) Save(ctx context.Context, params SaveParams) error {
+	e := params.Entity
+
+	query := `
+		INSERT INTO aegis_vault_keeper.retention_overrides (group_id, keep_versions, keep_audit_days, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (group_id) DO UPDATE SET
+			keep_versions = EXCLUDED.keep_versions,
+			keep_audit_days = EXCLUDED.keep_audit_days,
+			updated_at = EXCLUDED.updated_at
+	`
+	if _, err := r.db.Exec(ctx, query, e.GroupID, e.KeepVersions, e.KeepAuditDays, e.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to save retention override: %w", err)
+	}
+	return nil
+}
+
+// Load retrieves every retention override ordered by group ID.
+func (r *Repository) Load(ctx context.Context) ([]*retention.Override, error) {
+	query := `
+		SELECT group_id, keep_versions, keep_audit_days, updated_at
+		FROM aegis_vault_keeper.retention_overrides
+		ORDER BY group_id
+	`
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load retention overrides: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var overrides []*retention.Override
+	for rows.Next() {
+		var o retention.Override
+		if err := rows.Scan(&o.GroupID, &o.KeepVersions, &o.KeepAuditDays, &o.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan retention override: %w", err)
+		}
+		overrides = append(overrides, &o)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate retention overrides: %w", err)
+	}
+	return overrides, nil
+}
+
+// Delete removes the retention override of the group.
+func (r *Repository) Delete(ctx context.Context, params DeleteParams) error {
+	query := `DELETE FROM aegis_vault_keeper.retention_overrides WHERE group_id = $1`
+	res, err := r.db.Exec(ctx, query, params.GroupID)
+	if err != nil {
+		return fmt.Errorf("failed to delete retention override: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check deleted retention overrides: %w", err)
+	}
+	if n == 0 {
+		return ErrOverrideNotFound
+	}
+	return nil
+}
+
+// Count returns the amount of data in the scope the retention rules do not keep, without deleting it.
+func (r *Repository) Count(ctx context.Context, params ScopeParams) (retention.Counts, error) {
+	return r.apply(ctx, params, false)
+}
+
+// Purge deletes the data in the scope the retention rules do not keep and returns the amount deleted.
+// The deletions are not atomic; data left by a failure is purged by the next run.
+func (r *Repository) Purge(ctx context.Context, params ScopeParams) (retention.Counts, error) {
+	return r.apply(ctx, params, true)
+}
+
+// apply counts or, when purge is set, deletes the data the retention rules do not keep within the admin scope, so
+// the data of every user in the scope is affected, and returns the affected rows of each kind.
+func (r *Repository) apply(ctx context.Context, params ScopeParams, purge bool) (retention.Counts, error) {
+	// counts holds the rows affected inside the admin scope.
+	var counts retention.Counts
+	err := db.InAdminScope(ctx, r.db, func(ctx context.Context) error {
+		var err error
+		counts, err = r.enforce(ctx, params, purge)
+		return err
+	})
+	return counts, err
+}
+
+// enforce counts or, when purge is set, deletes the replaced versions of every item table and the item reveal
+// records the retention rules do not keep, and returns the affected rows of each kind.
+func (r *Repository) enforce(ctx context.Context, params ScopeParams, purge bool) (retention.Counts, error) {
+	// counts accumulates the affected rows.
+	var counts retention.Counts
+	if params.KeepVersions > 0 {
+		scope, scopeArgs := dataScope(params, 2, "id")
+		for _, t := range r.itemTables {
+			table := history.Table(t)
+			condition := fmt.Sprintf(`ctid IN (
+				SELECT ctid FROM (
+					SELECT ctid, row_number() OVER (PARTITION BY id ORDER BY archived_at DESC) AS rank
+					FROM aegis_vault_keeper.%s
+					WHERE %s
+				) ranked
+				WHERE rank > $1
+			)`, table, scope)
+			n, err := r.affected(ctx, purge, table, condition, append([]any{params.KeepVersions}, scopeArgs...)...)
+			if err != nil {
+				return counts, fmt.Errorf("failed to apply retention to %s: %w", table, err)
+			}
+			counts.Versions += n
+		}
+	}
+	if !params.AuditBefore.IsZero() {
+		scope, scopeArgs := dataScope(params, 2, "item_id")
+		condition := "revealed_at < $1 AND " + scope
+		n, err := r.affected(ctx, purge, "item_reveals", condition, append([]any{params.AuditBefore}, scopeArgs...)...)
+		if err != nil {
+			return counts, fmt.Errorf("failed to apply retention to item_reveals: %w", err)
+		}
+		counts.AuditRecords = n
+	}
+	return counts, nil
+}
+
+// affected counts or, when purge is set, deletes the rows of the table matching the condition and returns their
+// number.
+func (r *Repository) affected(ctx context.Context, purge bool, table, condition string, args ...any) (int64, error) {
+	if !purge {
+		query := fmt.Sprintf("SELECT count(*) FROM aegis_vault_keeper.%s WHERE %s", table, condition)
+		// n holds the number of matching rows.
+		var n int64
+		if err := r.db.QueryRow(ctx, query, args...).Scan(&n); err != nil {
+			return 0, fmt.Errorf("failed to count rows: %w", err)
+		}
+		return n, nil
+	}
+	query := fmt.Sprintf("DELETE FROM aegis_vault_keeper.%s WHERE %s", table, condition)
+	res, err := r.db.Exec(ctx, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete rows: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to count deleted rows: %w", err)
+	}
+	return n, nil
+}
+
+// dataScope returns the condition restricting rows to the users of the scope and leaving out the excluded items
+// identified by the item column, with its arguments numbered from argNum.
+func dataScope(params ScopeParams, argNum int, itemColumn string) (string, []any) {
+	condition, args := userScope(params, argNum)
+	if params.ExcludeItemIDs != nil {
+		condition += fmt.Sprintf(" AND NOT (%s = ANY($%d::uuid[]))", itemColumn, argNum+len(args))
+		args = append(args, params.ExcludeItemIDs)
+	}
+	return condition, args
+}
+
+// userScope returns the condition restricting rows to the users of the scope, with its argument numbered from
+// argNum, or an always true condition without arguments for every user.
+func userScope(params ScopeParams, argNum int) (string, []any) {
+	switch {
+	case params.UserIDs != nil:
+		return fmt.Sprintf("user_id = ANY($%d::uuid[])", argNum), []any{params.UserIDs}
+	case params.ExcludeUserIDs != nil:
+		return fmt.Sprintf("NOT (user_id = ANY($%d::uuid[]))", argNum), []any{params.ExcludeUserIDs}
+	default:
+		return "TRUE", nil
+	}
+}
@@ -0,0 +1,294 @@
+package retention
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/retention"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/db"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockResult implements sql.Result for testing.
+type mockResult struct {
+	rowsAffected int64
+}
+
+func (m mockResult) LastInsertId() (int64, error) { return 0, nil }
+func (m mockResult) RowsAffected() (int64, error) { return m.rowsAffected, nil }
+
+// mockDBClient implements db.DBClient for testing.
+type mockDBClient struct {
+	execFunc  func(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	queryFunc func(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+func (m *mockDBClient) Exec(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	if m.execFunc != nil {
+		return m.execFunc(ctx, query, args...)
+	}
+	return mockResult{rowsAffected: 1}, nil
+}
+
+func (m *mockDBClient) Query(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	if m.queryFunc != nil {
+		return m.queryFunc(ctx, query, args...)
+	}
+	return nil, errors.New("mock not configured")
+}
+
+func (m *mockDBClient) QueryRow(context.Context, string, ...interface{}) *sql.Row {
+	return nil
+}
+
+func (m *mockDBClient) BeginTx(context.Context, *sql.TxOptions) (*sql.Tx, error) {
+	return nil, errors.New("mock not configured")
+}
+
+func (m *mockDBClient) CommitTx(*sql.Tx) error {
+	return nil
+}
+
+func (m *mockDBClient) RollbackTx(*sql.Tx) error {
+	return nil
+}
+
+// Ensure mockDBClient implements db.DBClient.
+var _ db.DBClient = (*mockDBClient)(nil)
+
+func TestRepository_Save(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	entity := &retention.Override{
+		Policy:    retention.Policy{KeepVersions: 5, KeepAuditDays: 30},
+		GroupID:   uuid.New(),
+		UpdatedAt: now,
+	}
+
+	tests := []struct {
+		execErr error
+		name    string
+		wantErr bool
+	}{
+		{name: "upserts the override"},
+		{name: "database error", execErr: errors.New("connection lost"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := &mockDBClient{
+				execFunc: func(_ context.Context, query string, args ...interface{}) (sql.Result, error) {
+					assert.Contains(t, query, "ON CONFLICT (group_id) DO UPDATE")
+					assert.Equal(t, []interface{}{entity.GroupID, 5, 30, now}, args)
+					if tt.execErr != nil {
+						return nil, tt.execErr
+					}
+					return mockResult{rowsAffected: 1}, nil
+				},
+			}
+
+			err := NewRepository(client).Save(context.Background(), SaveParams{Entity: entity})
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.ErrorIs(t, err, tt.execErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestRepository_Load(t *testing.T) {
+	t.Parallel()
+
+	dbErr := errors.New("connection lost")
+	client := &mockDBClient{
+		queryFunc: func(context.Context, string, ...interface{}) (*sql.Rows, error) {
+			return nil, dbErr
+		},
+	}
+
+	overrides, err := NewRepository(client).Load(context.Background())
+
+	require.Error(t, err)
+	assert.ErrorIs(t, err, dbErr)
+	assert.Nil(t, overrides)
+}
+
+func TestRepository_Delete(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		execErr  error
+		wantErr  error
+		name     string
+		affected int64
+	}{
+		{name: "deleted", affected: 1},
+		{name: "not found", affected: 0, wantErr: ErrOverrideNotFound},
+		{name: "database error", execErr: errors.New("connection lost")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			groupID := uuid.New()
+			client := &mockDBClient{
+				execFunc: func(_ context.Context, _ string, args ...interface{}) (sql.Result, error) {
+					assert.Equal(t, []interface{}{groupID}, args)
+					if tt.execErr != nil {
+						return nil, tt.execErr
+					}
+					return mockResult{rowsAffected: tt.affected}, nil
+				},
+			}
+
+			err := NewRepository(client).Delete(context.Background(), DeleteParams{GroupID: groupID})
+			switch {
+			case tt.execErr != nil:
+				assert.ErrorIs(t, err, tt.execErr)
+			case tt.wantErr != nil:
+				assert.ErrorIs(t, err, tt.wantErr)
+			default:
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRepository_Purge(t *testing.T) {
+	t.Parallel()
+
+	users := []uuid.UUID{uuid.New()}
+	items := []uuid.UUID{uuid.New()}
+	cutoff := time.Date(2026, 9, 15, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		execErr     error
+		wantArgs    [][]interface{}
+		params      ScopeParams
+		name        string
+		wantScope   string
+		wantTables  []string
+		wantCounts  retention.Counts
+		errContains string
+	}{
+		{
+			name:       "versions and reveals of every user",
+			params:     ScopeParams{KeepVersions: 3, AuditBefore: cutoff},
+			wantScope:  "TRUE",
+			wantTables: []string{"notes_history", "credentials_history", "item_reveals"},
+			wantArgs:   [][]interface{}{{3}, {3}, {cutoff}},
+			wantCounts: retention.Counts{Versions: 4, AuditRecords: 2},
+		},
+		{
+			name:       "versions of the users",
+			params:     ScopeParams{KeepVersions: 1, UserIDs: users},
+			wantScope:  "user_id = ANY($2::uuid[])",
+			wantTables: []string{"notes_history", "credentials_history"},
+			wantArgs:   [][]interface{}{{1, users}, {1, users}},
+			wantCounts: retention.Counts{Versions: 4},
+		},
+		{
+			name:       "reveals of the other users",
+			params:     ScopeParams{AuditBefore: cutoff, ExcludeUserIDs: users},
+			wantScope:  "NOT (user_id = ANY($2::uuid[]))",
+			wantTables: []string{"item_reveals"},
+			wantArgs:   [][]interface{}{{cutoff, users}},
+			wantCounts: retention.Counts{AuditRecords: 2},
+		},
+		{
+			name:       "versions and reveals of items not on hold",
+			params:     ScopeParams{KeepVersions: 2, AuditBefore: cutoff, ExcludeUserIDs: users, ExcludeItemIDs: items},
+			wantScope:  "NOT (user_id = ANY($2::uuid[])) AND NOT (",
+			wantTables: []string{"notes_history", "credentials_history", "item_reveals"},
+			wantArgs:   [][]interface{}{{2, users, items}, {2, users, items}, {cutoff, users, items}},
+			wantCounts: retention.Counts{Versions: 4, AuditRecords: 2},
+		},
+		{
+			name:   "keep everything",
+			params: ScopeParams{UserIDs: users},
+		},
+		{
+			name:        "database error",
+			params:      ScopeParams{KeepVersions: 3},
+			execErr:     errors.New("connection lost"),
+			wantScope:   "TRUE",
+			wantTables:  []string{"notes_history"},
+			wantArgs:    [][]interface{}{{3}},
+			errContains: "failed to apply retention to notes_history",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// tables and args collect the target table and arguments of every statement.
+			var tables []string
+			var args [][]interface{}
+			client := &mockDBClient{
+				execFunc: func(_ context.Context, query string, a ...interface{}) (sql.Result, error) {
+					table, _, _ := strings.Cut(strings.TrimPrefix(query, "DELETE FROM aegis_vault_keeper."), " ")
+					tables = append(tables, table)
+					args = append(args, a)
+					assert.Contains(t, query, tt.wantScope)
+					if tt.execErr != nil {
+						return nil, tt.execErr
+					}
+					return mockResult{rowsAffected: 2}, nil
+				},
+			}
+
+			counts, err := NewRepository(client, "notes", "credentials").Purge(context.Background(), tt.params)
+			assert.Equal(t, tt.wantTables, tables)
+			assert.Equal(t, tt.wantArgs, args)
+			if tt.errContains != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errContains)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantCounts, counts)
+		})
+	}
+}
+
+// adminScopedClient wraps mockDBClient and records whether statements ran within the admin scope.
+type adminScopedClient struct {
+	mockDBClient
+	inScope bool
+}
+
+func (a *adminScopedClient) RunInAdminScope(ctx context.Context, fn func(ctx context.Context) error) error {
+	a.inScope = true
+	defer func() { a.inScope = false }()
+	return fn(ctx)
+}
+
+func TestRepository_Purge_AdminScope(t *testing.T) {
+	t.Parallel()
+
+	client := &adminScopedClient{}
+	var scoped []bool
+	client.execFunc = func(context.Context, string, ...interface{}) (sql.Result, error) {
+		scoped = append(scoped, client.inScope)
+		return mockResult{rowsAffected: 1}, nil
+	}
+
+	params := ScopeParams{KeepVersions: 1, AuditBefore: time.Now()}
+	counts, err := NewRepository(client, "notes", "credentials").Purge(context.Background(), params)
+	require.NoError(t, err)
+	assert.Equal(t, retention.Counts{Versions: 2, AuditRecords: 1}, counts)
+	assert.Equal(t, []bool{true, true, true}, scoped)
+}
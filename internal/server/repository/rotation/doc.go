@@ -0,0 +1,5 @@
+// Package rotation provides credential rotation policy persistence for the AegisVaultKeeper server.
+//
+// This package implements storage of the policies binding credentials to the external systems their
+// secrets are rotated in, together with the rotation schedule and outcome, in PostgreSQL.
+package rotation
@@ -0,0 +1,9 @@
+package rotation
+
+import "errors"
+
+// Rotation repository error definitions.
+var (
+	// ErrPolicyNotFound indicates that the requested rotation policy was not found in the repository.
+	ErrPolicyNotFound = errors.New("rotation policy not found")
+)
@@ -0,0 +1,33 @@
+package rotation
+
+import (
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/rotation"
+	"github.com/google/uuid"
+)
+
+// SaveParams contains the parameters for saving a rotation policy to the repository.
+type SaveParams struct {
+	// Entity contains the policy to be created or replaced.
+	Entity *rotation.Policy
+}
+
+// LoadParams contains the parameters for loading rotation policies from the repository.
+// Zero fields do not filter.
+type LoadParams struct {
+	// DueAt selects scheduled policies whose next rotation is due at the moment.
+	DueAt time.Time
+	// CredentialID selects the policy of the specified credential.
+	CredentialID uuid.UUID
+	// UserID selects the policies of the specified owner.
+	UserID uuid.UUID
+}
+
+// DeleteParams contains the parameters for deleting a rotation policy from the repository.
+type DeleteParams struct {
+	// CredentialID identifies the rotated credential.
+	CredentialID uuid.UUID
+	// UserID identifies the owner of the credential.
+	UserID uuid.UUID
+}
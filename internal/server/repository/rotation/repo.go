@@ -0,0 +1,171 @@
+package rotation
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/rotation"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/db"
+	"github.com/google/uuid"
+)
+
+// Repository provides rotation policy persistence operations.
+type Repository struct {
+	// db is the database client used for rotation policy operations.
+	db db.DBClient
+}
+
+// NewRepository creates a new Repository with the provided database client.
+func NewRepository(dbClient db.DBClient) *Repository {
+	return &Repository{db: dbClient}
+}
+
+// settingsRecord is the stored JSON form of rotator settings.
+type settingsRecord struct {
+	// Host contains the PostgreSQL server host.
+	Host string `json:"host,omitzero"`
+	// Database contains the PostgreSQL database.
+	Database string `json:"database,omitzero"`
+	// SSLMode contains the PostgreSQL TLS mode.
+	SSLMode string `json:"sslmode,omitzero"`
+	// UserName contains the AWS IAM user.
+	UserName string `json:"user_name,omitzero"`
+	// Region contains the AWS signing region.
+	Region string `json:"region,omitzero"`
+	// Endpoint contains the AWS IAM API URL.
+	Endpoint string `json:"endpoint,omitzero"`
+	// URL contains the webhook URL.
+	URL string `json:"url,omitzero"`
+	// Port contains the PostgreSQL server port.
+	Port int `json:"port,omitzero"`
+}
+
+// Save creates the rotation policy of a credential or replaces the existing one.
+func (r *Repository) Save(ctx context.Context, params SaveParams) error {
+	e := params.Entity
+
+	settings, err := json.Marshal(settingsRecord(e.Settings))
+	if err != nil {
+		return fmt.Errorf("failed to encode rotator settings: %w", err)
+	}
+
+	query := `
+		INSERT INTO aegis_vault_keeper.credential_rotations
+			(credential_id, user_id, kind, settings, interval_seconds, last_error, created_at,
+			 last_rotated_at, next_rotation_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (credential_id) DO UPDATE SET
+			kind = EXCLUDED.kind,
+			settings = EXCLUDED.settings,
+			interval_seconds = EXCLUDED.interval_seconds,
+			last_error = EXCLUDED.last_error,
+			last_rotated_at = EXCLUDED.last_rotated_at,
+			next_rotation_at = EXCLUDED.next_rotation_at
+	`
+	if _, err := r.db.Exec(ctx, query,
+		e.CredentialID, e.UserID, string(e.Kind), settings, int64(e.Interval/time.Second), e.LastError,
+		e.CreatedAt, nullTime(e.LastRotatedAt), nullTime(e.NextRotationAt),
+	); err != nil {
+		return fmt.Errorf("failed to save rotation policy: %w", err)
+	}
+	return nil
+}
+
+// Load retrieves rotation policies matching the provided parameters, ordered by creation time.
+func (r *Repository) Load(ctx context.Context, params LoadParams) ([]*rotation.Policy, error) {
+	var (
+		conditions []string
+		args       []interface{}
+	)
+	// filter adds a condition comparing a column with the next positional argument.
+	filter := func(condition string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	if params.CredentialID != uuid.Nil {
+		filter("credential_id = $%d", params.CredentialID)
+	}
+	if params.UserID != uuid.Nil {
+		filter("user_id = $%d", params.UserID)
+	}
+	if !params.DueAt.IsZero() {
+		filter("interval_seconds > 0 AND next_rotation_at <= $%d", params.DueAt)
+	}
+
+	query := `
+		SELECT credential_id, user_id, kind, settings, interval_seconds, last_error, created_at,
+			last_rotated_at, next_rotation_at
+		FROM aegis_vault_keeper.credential_rotations
+	`
+	if len(conditions) != 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY created_at, credential_id"
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load rotation policies: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var policies []*rotation.Policy
+	for rows.Next() {
+		var (
+			p              rotation.Policy
+			kind           string
+			settings       []byte
+			interval       int64
+			lastRotatedAt  sql.NullTime
+			nextRotationAt sql.NullTime
+		)
+		if err := rows.Scan(
+			&p.CredentialID, &p.UserID, &kind, &settings, &interval, &p.LastError, &p.CreatedAt,
+			&lastRotatedAt, &nextRotationAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan rotation policy: %w", err)
+		}
+		var record settingsRecord
+		if err := json.Unmarshal(settings, &record); err != nil {
+			return nil, fmt.Errorf("failed to decode rotator settings: %w", err)
+		}
+		p.Kind = rotation.Kind(kind)
+		p.Settings = rotation.Settings(record)
+		p.Interval = time.Duration(interval) * time.Second
+		p.LastRotatedAt = lastRotatedAt.Time
+		p.NextRotationAt = nextRotationAt.Time
+		policies = append(policies, &p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate rotation policies: %w", err)
+	}
+	return policies, nil
+}
+
+// Delete removes the rotation policy of the credential of the owner.
+func (r *Repository) Delete(ctx context.Context, params DeleteParams) error {
+	query := `
+		DELETE FROM aegis_vault_keeper.credential_rotations
+		WHERE credential_id = $1 AND user_id = $2
+	`
+	res, err := r.db.Exec(ctx, query, params.CredentialID, params.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to delete rotation policy: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check deleted rotation policies: %w", err)
+	}
+	if n == 0 {
+		return ErrPolicyNotFound
+	}
+	return nil
+}
+
+// nullTime converts the zero time to SQL NULL.
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
@@ -0,0 +1,222 @@
+package rotation
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/rotation"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/db"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockResult implements sql.Result for testing.
+type mockResult struct {
+	rowsAffected int64
+}
+
+func (m mockResult) LastInsertId() (int64, error) { return 0, nil }
+func (m mockResult) RowsAffected() (int64, error) { return m.rowsAffected, nil }
+
+// mockDBClient implements db.DBClient for testing.
+type mockDBClient struct {
+	execFunc  func(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	queryFunc func(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+func (m *mockDBClient) Exec(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	if m.execFunc != nil {
+		return m.execFunc(ctx, query, args...)
+	}
+	return mockResult{rowsAffected: 1}, nil
+}
+
+func (m *mockDBClient) Query(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	if m.queryFunc != nil {
+		return m.queryFunc(ctx, query, args...)
+	}
+	return nil, errors.New("mock not configured")
+}
+
+func (m *mockDBClient) QueryRow(context.Context, string, ...interface{}) *sql.Row {
+	return nil
+}
+
+func (m *mockDBClient) BeginTx(context.Context, *sql.TxOptions) (*sql.Tx, error) {
+	return nil, errors.New("mock not configured")
+}
+
+func (m *mockDBClient) CommitTx(*sql.Tx) error {
+	return nil
+}
+
+func (m *mockDBClient) RollbackTx(*sql.Tx) error {
+	return nil
+}
+
+// Ensure mockDBClient implements db.DBClient.
+var _ db.DBClient = (*mockDBClient)(nil)
+
+func TestRepository_Save(t *testing.T) {
+	t.Parallel()
+
+	credentialID, userID := uuid.New(), uuid.New()
+	createdAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	nextRotationAt := createdAt.Add(24 * time.Hour)
+
+	tests := []struct {
+		execErr error
+		name    string
+	}{
+		{name: "saved"},
+		{name: "exec error", execErr: errors.New("connection refused")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var gotArgs []interface{}
+			client := &mockDBClient{
+				execFunc: func(_ context.Context, query string, args ...interface{}) (sql.Result, error) {
+					assert.Contains(t, query, "INSERT INTO aegis_vault_keeper.credential_rotations")
+					assert.Contains(t, query, "ON CONFLICT (credential_id) DO UPDATE")
+					gotArgs = args
+					return mockResult{rowsAffected: 1}, tt.execErr
+				},
+			}
+
+			err := NewRepository(client).Save(context.Background(), SaveParams{Entity: &rotation.Policy{
+				CredentialID:   credentialID,
+				UserID:         userID,
+				Kind:           rotation.KindPostgres,
+				Settings:       rotation.Settings{Host: "db.internal", Port: 5432, Database: "app", SSLMode: "require"},
+				Interval:       24 * time.Hour,
+				CreatedAt:      createdAt,
+				NextRotationAt: nextRotationAt,
+			}})
+
+			if tt.execErr != nil {
+				assert.ErrorIs(t, err, tt.execErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, gotArgs, 9)
+			assert.Equal(t, []interface{}{credentialID, userID, "postgres"}, gotArgs[:3])
+			assert.JSONEq(t,
+				`{"host":"db.internal","port":5432,"database":"app","sslmode":"require"}`,
+				string(gotArgs[3].([]byte)),
+			)
+			assert.Equal(t, []interface{}{
+				int64(86400), "", createdAt, sql.NullTime{}, sql.NullTime{Time: nextRotationAt, Valid: true},
+			}, gotArgs[4:])
+		})
+	}
+}
+
+func TestRepository_Load(t *testing.T) {
+	t.Parallel()
+
+	credentialID, userID := uuid.New(), uuid.New()
+	at := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		params    LoadParams
+		wantQuery string
+		noQuery   string
+		wantArgs  []interface{}
+	}{
+		{
+			name:      "all policies",
+			noQuery:   "WHERE",
+			wantQuery: "ORDER BY created_at, credential_id",
+		},
+		{
+			name:      "policy of a credential of an owner",
+			params:    LoadParams{CredentialID: credentialID, UserID: userID},
+			wantQuery: "WHERE credential_id = $1 AND user_id = $2",
+			wantArgs:  []interface{}{credentialID, userID},
+		},
+		{
+			name:      "due policies",
+			params:    LoadParams{DueAt: at},
+			wantQuery: "WHERE interval_seconds > 0 AND next_rotation_at <= $1",
+			wantArgs:  []interface{}{at},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			queryErr := errors.New("query error")
+			var gotQuery string
+			var gotArgs []interface{}
+			client := &mockDBClient{
+				queryFunc: func(_ context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+					gotQuery, gotArgs = query, args
+					return nil, queryErr
+				},
+			}
+
+			_, err := NewRepository(client).Load(context.Background(), tt.params)
+
+			require.ErrorIs(t, err, queryErr)
+			assert.Contains(t, gotQuery, tt.wantQuery)
+			if tt.noQuery != "" {
+				assert.NotContains(t, gotQuery, tt.noQuery)
+			}
+			assert.Equal(t, tt.wantArgs, gotArgs)
+		})
+	}
+}
+
+func TestRepository_Delete(t *testing.T) {
+	t.Parallel()
+
+	credentialID, userID := uuid.New(), uuid.New()
+
+	tests := []struct {
+		execErr  error
+		wantErr  error
+		name     string
+		affected int64
+	}{
+		{name: "deleted", affected: 1},
+		{name: "not found", wantErr: ErrPolicyNotFound},
+		{name: "exec error", execErr: errors.New("connection refused")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := &mockDBClient{
+				execFunc: func(_ context.Context, query string, args ...interface{}) (sql.Result, error) {
+					assert.Contains(t, query, "DELETE FROM aegis_vault_keeper.credential_rotations")
+					assert.Equal(t, []interface{}{credentialID, userID}, args)
+					return mockResult{rowsAffected: tt.affected}, tt.execErr
+				},
+			}
+
+			err := NewRepository(client).Delete(context.Background(), DeleteParams{
+				CredentialID: credentialID,
+				UserID:       userID,
+			})
+
+			switch {
+			case tt.execErr != nil:
+				assert.ErrorIs(t, err, tt.execErr)
+			case tt.wantErr != nil:
+				assert.ErrorIs(t, err, tt.wantErr)
+			default:
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package rowsign
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/db"
+	"github.com/google/uuid"
+)
+
+// unsignedRow holds the signed column values of a row stored without a signature.
+type unsignedRow struct {
+	// values lists the signed column values in table column order.
+	values []any
+	// id identifies the row.
+	id uuid.UUID
+}
+
+// Backfiller signs the rows of signed tables stored before row signatures were introduced.
+type Backfiller struct {
+	// db is the database client used to scan and update tables.
+	db db.DBClient
+	// signer computes row signatures.
+	signer *Signer
+	// tables lists the signed tables to backfill.
+	tables []Table
+}
+
+// NewBackfiller creates a new Backfiller for the given signed tables.
+func NewBackfiller(dbClient db.DBClient, signer *Signer, tables ...Table) *Backfiller {
+	return &Backfiller{
+		db:     dbClient,
+		signer: signer,
+		tables: tables,
+	}
+}
+
+// Backfill signs the rows without a signature in every signed table not backfilled before and returns the number
+// of rows signed. Each table is backfilled once and recorded in the row_signature_backfills table, so a signature
+// removed later is reported as a violation instead of being signed again. The rows of all users are signed within
+// the admin scope, and signed items are not archived as new versions.
+func (b *Backfiller) Backfill(ctx context.Context) (int, error) {
+	// signed counts the rows signed across all tables.
+	var signed int
+	err := db.InAdminScope(ctx, b.db, func(ctx context.Context) error {
+		for _, t := range b.tables {
+			err := db.WithoutArchiving(ctx, b.db, func(ctx context.Context) error {
+				n, err := b.backfillTable(ctx, t)
+				signed += n
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("failed to backfill table %s: %w", t.Name, err)
+			}
+		}
+		return nil
+	})
+	return signed, err
+}
+
+// backfillTable signs the rows without a signature in a single table unless it was backfilled before.
+func (b *Backfiller) backfillTable(ctx context.Context, t Table) (int, error) {
+	if len(t.Columns) < 2 {
+		return 0, errors.New("table must declare at least id and user_id columns")
+	}
+
+	var done bool
+	if err := b.db.QueryRow(ctx,
+		"SELECT EXISTS (SELECT 1 FROM aegis_vault_keeper.row_signature_backfills WHERE table_name = $1)",
+		t.Name,
+	).Scan(&done); err != nil {
+		return 0, fmt.Errorf("failed to check backfill state: %w", err)
+	}
+	if done {
+		return 0, nil
+	}
+
+	rows, err := b.unsignedRows(ctx, t)
+	if err != nil {
+		return 0, err
+	}
+	update := fmt.Sprintf(
+		"UPDATE aegis_vault_keeper.%s SET signature = $1 WHERE %s = $2 AND signature IS NULL",
+		t.Name, t.Columns[0].Name,
+	)
+	for _, row := range rows {
+		signature, err := b.signer.Sign(t, row.values...)
+		if err != nil {
+			return 0, fmt.Errorf("failed to sign row %s: %w", row.id, err)
+		}
+		if _, err := b.db.Exec(ctx, update, signature, row.id); err != nil {
+			return 0, fmt.Errorf("failed to store signature of row %s: %w", row.id, err)
+		}
+	}
+
+	if _, err := b.db.Exec(ctx,
+		"INSERT INTO aegis_vault_keeper.row_signature_backfills (table_name, completed_at) VALUES ($1, NOW()) "+
+			"ON CONFLICT (table_name) DO NOTHING",
+		t.Name,
+	); err != nil {
+		return 0, fmt.Errorf("failed to record backfill: %w", err)
+	}
+	return len(rows), nil
+}
+
+// unsignedRows loads the signed column values of the rows of the table without a signature.
+func (b *Backfiller) unsignedRows(ctx context.Context, t Table) ([]unsignedRow, error) {
+	names := make([]string, 0, len(t.Columns))
+	for _, col := range t.Columns {
+		names = append(names, col.Name)
+	}
+
+	query := fmt.Sprintf(
+		"SELECT %s FROM aegis_vault_keeper.%s WHERE signature IS NULL", strings.Join(names, ", "), t.Name,
+	)
+	rows, err := b.db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var unsigned []unsignedRow
+	for rows.Next() {
+		// dest holds scan destinations for the signed columns; the trailing signature target is not scanned.
+		dest := scanDestinations(t)
+		if err := rows.Scan(dest[:len(t.Columns)]...); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		values := dereference(dest[:len(t.Columns)])
+		id, _ := values[0].(uuid.UUID)
+		unsigned = append(unsigned, unsignedRow{values: values, id: id})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+	return unsigned, nil
+}
@@ -0,0 +1,263 @@
+package rowsign
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeNote is a row of the in-memory notes table.
+type fakeNote struct {
+	updatedAt time.Time
+	note      []byte
+	signature []byte
+	id        uuid.UUID
+	userID    uuid.UUID
+}
+
+// fakeStore is an in-memory notes table and backfill record served through database/sql.
+type fakeStore struct {
+	// backfilled holds the names of the tables recorded as backfilled.
+	backfilled map[string]bool
+	// notes holds the rows of the notes table.
+	notes []*fakeNote
+	// archived counts the rows archived as item versions on update.
+	archived int
+	// mu guards the store fields.
+	mu sync.Mutex
+	// rls reports whether row-level security hides the rows of the notes table outside the admin scope.
+	rls bool
+	// adminScope reports whether the running transaction is in the admin scope.
+	adminScope bool
+	// skipArchive reports whether the running transaction turned off item archiving.
+	skipArchive bool
+}
+
+// hidden reports whether row-level security hides the rows of the notes table from the running statement.
+func (s *fakeStore) hidden() bool {
+	return s.rls && !s.adminScope
+}
+
+func (s *fakeStore) Connect(context.Context) (driver.Conn, error) { return fakeConn{s}, nil }
+func (s *fakeStore) Driver() driver.Driver                        { return nil }
+
+// fakeConn answers the queries of the Backfiller and the Verifier on the notes table.
+type fakeConn struct {
+	s *fakeStore
+}
+
+func (fakeConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("prepare not supported") }
+func (fakeConn) Close() error                        { return nil }
+func (fakeConn) Begin() (driver.Tx, error)           { return nil, errors.New("transactions not supported") }
+
+func (c fakeConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.s.mu.Lock()
+	defer c.s.mu.Unlock()
+
+	if strings.HasPrefix(query, "SELECT EXISTS") {
+		name, _ := args[0].Value.(string)
+		return &fakeRows{columns: []string{"exists"}, values: [][]driver.Value{{c.s.backfilled[name]}}}, nil
+	}
+
+	withSignature := strings.Contains(query, "signature FROM")
+	onlyUnsigned := strings.HasSuffix(query, "WHERE signature IS NULL")
+	rows := &fakeRows{columns: []string{"id", "user_id", "note", "updated_at"}}
+	if withSignature {
+		rows.columns = append(rows.columns, "signature")
+	}
+	for _, n := range c.s.notes {
+		if c.s.hidden() || onlyUnsigned && n.signature != nil {
+			continue
+		}
+		row := []driver.Value{n.id.String(), n.userID.String(), n.note, n.updatedAt}
+		if withSignature {
+			row = append(row, n.signature)
+		}
+		rows.values = append(rows.values, row)
+	}
+	return rows, nil
+}
+
+func (c fakeConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.s.mu.Lock()
+	defer c.s.mu.Unlock()
+
+	switch {
+	case strings.HasPrefix(query, "SELECT set_config('aegis_vault_keeper.skip_archive'"):
+		c.s.skipArchive = true
+	case strings.HasPrefix(query, "UPDATE aegis_vault_keeper.notes"):
+		signature, _ := args[0].Value.([]byte)
+		id, _ := args[1].Value.(string)
+		for _, n := range c.s.notes {
+			if n.id.String() == id && n.signature == nil && !c.s.hidden() {
+				n.signature = signature
+				if !c.s.skipArchive {
+					c.s.archived++
+				}
+			}
+		}
+	case strings.HasPrefix(query, "INSERT INTO aegis_vault_keeper.row_signature_backfills"):
+		name, _ := args[0].Value.(string)
+		c.s.backfilled[name] = true
+	default:
+		return nil, errors.New("unexpected statement: " + query)
+	}
+	return driver.RowsAffected(1), nil
+}
+
+// fakeRows iterates over rows of driver values.
+type fakeRows struct {
+	columns []string
+	values  [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string { return r.columns }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
+// fakeDBClient implements db.DBClient over a database/sql handle.
+type fakeDBClient struct {
+	db *sql.DB
+}
+
+func (c fakeDBClient) Exec(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return c.db.ExecContext(ctx, query, args...)
+}
+
+func (c fakeDBClient) QueryRow(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return c.db.QueryRowContext(ctx, query, args...)
+}
+
+func (c fakeDBClient) Query(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return c.db.QueryContext(ctx, query, args...)
+}
+
+func (c fakeDBClient) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	return c.db.BeginTx(ctx, opts)
+}
+
+func (fakeDBClient) CommitTx(*sql.Tx) error   { return nil }
+func (fakeDBClient) RollbackTx(*sql.Tx) error { return nil }
+
+// fakeTxDBClient extends fakeDBClient with transactions and the admin scope, whose settings end with them.
+type fakeTxDBClient struct {
+	fakeDBClient
+	store *fakeStore
+}
+
+func (c fakeTxDBClient) RunInAdminScope(ctx context.Context, fn func(ctx context.Context) error) error {
+	c.store.mu.Lock()
+	c.store.adminScope = true
+	c.store.mu.Unlock()
+	defer func() {
+		c.store.mu.Lock()
+		c.store.adminScope = false
+		c.store.mu.Unlock()
+	}()
+	return fn(ctx)
+}
+
+func (c fakeTxDBClient) RunInTx(ctx context.Context, _ uuid.UUID, fn func(ctx context.Context) error) error {
+	defer func() {
+		c.store.mu.Lock()
+		c.store.skipArchive = false
+		c.store.mu.Unlock()
+	}()
+	return fn(ctx)
+}
+
+func TestBackfiller_Backfill(t *testing.T) {
+	t.Parallel()
+
+	signer := NewSigner([]byte("integrity-key"))
+	updatedAt := time.Date(2025, 5, 6, 7, 8, 9, 123456000, time.UTC)
+
+	signed := &fakeNote{id: uuid.New(), userID: uuid.New(), note: []byte("signed"), updatedAt: updatedAt}
+	var err error
+	signed.signature, err = signer.Sign(testTable, signed.id, signed.userID, signed.note, signed.updatedAt)
+	require.NoError(t, err)
+	signature := signed.signature
+	legacy := &fakeNote{id: uuid.New(), userID: uuid.New(), note: []byte("legacy"), updatedAt: updatedAt}
+
+	store := &fakeStore{backfilled: make(map[string]bool), notes: []*fakeNote{signed, legacy}}
+	client := fakeDBClient{db: sql.OpenDB(store)}
+	t.Cleanup(func() { _ = client.db.Close() })
+	verifier := NewVerifier(client, signer, testTable)
+	backfiller := NewBackfiller(client, signer, testTable)
+
+	reports, err := verifier.Verify(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []Violation{{Table: "notes", ID: legacy.id, UserID: legacy.userID}}, reports[0].Violations,
+		"a row stored without a signature fails verification before the backfill")
+
+	n, err := backfiller.Backfill(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, signature, signed.signature, "a signed row keeps its signature")
+	require.NoError(t, signer.Verify(testTable, legacy.signature, legacy.id, legacy.userID, legacy.note, legacy.updatedAt))
+	assert.True(t, store.backfilled["notes"])
+
+	reports, err = verifier.Verify(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, reports[0].Violations)
+	assert.Equal(t, 2, reports[0].Checked)
+
+	// A signature removed after the backfill is not signed again.
+	signed.signature = nil
+	n, err = backfiller.Backfill(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, n)
+	assert.Nil(t, signed.signature)
+}
+
+func TestBackfiller_Backfill_RowLevelSecurity(t *testing.T) {
+	t.Parallel()
+
+	signer := NewSigner([]byte("integrity-key"))
+	legacy := &fakeNote{id: uuid.New(), userID: uuid.New(), note: []byte("legacy"), updatedAt: time.Now()}
+	store := &fakeStore{backfilled: make(map[string]bool), notes: []*fakeNote{legacy}, rls: true}
+	client := fakeTxDBClient{fakeDBClient: fakeDBClient{db: sql.OpenDB(store)}, store: store}
+	t.Cleanup(func() { _ = client.db.Close() })
+
+	n, err := NewBackfiller(client, signer, testTable).Backfill(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, n, "the rows of every user are signed within the admin scope")
+	assert.NotNil(t, legacy.signature)
+	assert.Zero(t, store.archived, "signing a row does not archive it as a new item version")
+
+	reports, err := NewVerifier(client, signer, testTable).Verify(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, reports[0].Checked, "the rows of every user are verified within the admin scope")
+	assert.Empty(t, reports[0].Violations)
+}
+
+func TestBackfiller_Backfill_Errors(t *testing.T) {
+	t.Parallel()
+
+	b := NewBackfiller(&mockDBClient{}, NewSigner([]byte("key")),
+		Table{Name: "broken", Columns: []Column{{Name: "id", Kind: KindUUID}}})
+
+	_, err := b.Backfill(context.Background())
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "at least id and user_id")
+}
@@ -0,0 +1,6 @@
+// Package rowsign provides HMAC integrity signatures over stored rows for the AegisVaultKeeper repository layer.
+//
+// Every signed row carries an HMAC over all of its columns computed with a server integrity key.
+// Signatures are verified on each read and by the periodic verification job, so rows modified
+// directly in the database are detected.
+package rowsign
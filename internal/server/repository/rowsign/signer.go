@@ -0,0 +1,90 @@
+package rowsign
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/crypto"
+	"github.com/google/uuid"
+)
+
+// ErrSignatureMismatch indicates that a stored row does not match its integrity signature.
+var ErrSignatureMismatch = errors.New("row signature mismatch")
+
+// Signer computes and verifies row signatures under the server integrity key.
+type Signer struct {
+	// key is the HMAC key used for row signatures.
+	key []byte
+}
+
+// NewSigner creates a new Signer using the provided integrity key.
+func NewSigner(key []byte) *Signer {
+	return &Signer{key: key}
+}
+
+// Sign computes the signature of a row given its column values in table column order.
+func (s *Signer) Sign(t Table, values ...any) ([]byte, error) {
+	data, err := canonicalize(t, values)
+	if err != nil {
+		return nil, fmt.Errorf("failed to canonicalize %s row: %w", t.Name, err)
+	}
+	return crypto.SignHMACSHA256(s.key, data), nil
+}
+
+// Verify checks the signature of a row given its column values in table column order.
+// Returns ErrSignatureMismatch when the row was modified after signing or the signature is missing.
+func (s *Signer) Verify(t Table, signature []byte, values ...any) error {
+	data, err := canonicalize(t, values)
+	if err != nil {
+		return fmt.Errorf("failed to canonicalize %s row: %w", t.Name, err)
+	}
+	if !crypto.VerifyHMACSHA256(s.key, data, signature) {
+		return fmt.Errorf("%w: table %s", ErrSignatureMismatch, t.Name)
+	}
+	return nil
+}
+
+// canonicalize encodes the table name, column names and values into unambiguous signing input.
+func canonicalize(t Table, values []any) ([]byte, error) {
+	if len(values) != len(t.Columns) {
+		return nil, fmt.Errorf("expected %d values, got %d", len(t.Columns), len(values))
+	}
+
+	parts := make([]string, 0, 1+2*len(values))
+	parts = append(parts, t.Name)
+	for i, col := range t.Columns {
+		enc, err := encodeValue(col, values[i])
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, col.Name, enc)
+	}
+	return crypto.BuildAAD(parts...), nil
+}
+
+// encodeValue converts a column value into its canonical string form.
+func encodeValue(col Column, v any) (string, error) {
+	switch col.Kind {
+	case KindUUID:
+		if id, ok := v.(uuid.UUID); ok {
+			return id.String(), nil
+		}
+	case KindBytes:
+		if b, ok := v.([]byte); ok {
+			return string(b), nil
+		}
+	case KindTime:
+		if ts, ok := v.(time.Time); ok {
+			return canonicalTime(ts).Format(time.RFC3339Nano), nil
+		}
+	}
+	return "", fmt.Errorf("unexpected value type %T for column %s", v, col.Name)
+}
+
+// canonicalTime normalizes a timestamp the way it round-trips through a TIMESTAMP column:
+// the wall clock is kept, the location is discarded and precision is truncated to microseconds.
+func canonicalTime(t time.Time) time.Time {
+	wall := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+	return wall.Truncate(time.Microsecond)
+}
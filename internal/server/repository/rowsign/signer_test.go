@@ -0,0 +1,158 @@
+package rowsign
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testTable describes a signed table used in tests.
+var testTable = Table{
+	Name: "notes",
+	Columns: []Column{
+		{Name: "id", Kind: KindUUID},
+		{Name: "user_id", Kind: KindUUID},
+		{Name: "note", Kind: KindBytes},
+		{Name: "updated_at", Kind: KindTime},
+	},
+}
+
+func TestSigner_SignVerify(t *testing.T) {
+	t.Parallel()
+
+	signer := NewSigner([]byte("integrity-key"))
+	id, userID := uuid.New(), uuid.New()
+	updatedAt := time.Date(2025, 5, 6, 7, 8, 9, 123456789, time.FixedZone("UTC+3", 3*60*60))
+
+	signature, err := signer.Sign(testTable, id, userID, []byte("ciphertext"), updatedAt)
+	require.NoError(t, err)
+
+	// roundTripped mirrors how a TIMESTAMP column returns the value: wall clock in UTC, microsecond precision.
+	roundTripped := time.Date(2025, 5, 6, 7, 8, 9, 123456000, time.UTC)
+
+	tests := []struct {
+		signer    *Signer
+		name      string
+		signature []byte
+		values    []any
+		wantErr   bool
+	}{
+		{
+			name:      "valid_row",
+			signer:    signer,
+			signature: signature,
+			values:    []any{id, userID, []byte("ciphertext"), roundTripped},
+		},
+		{
+			name:      "modified_column",
+			signer:    signer,
+			signature: signature,
+			values:    []any{id, userID, []byte("ciphertexT"), roundTripped},
+			wantErr:   true,
+		},
+		{
+			name:      "moved_to_other_user",
+			signer:    signer,
+			signature: signature,
+			values:    []any{id, uuid.New(), []byte("ciphertext"), roundTripped},
+			wantErr:   true,
+		},
+		{
+			name:      "modified_timestamp",
+			signer:    signer,
+			signature: signature,
+			values:    []any{id, userID, []byte("ciphertext"), roundTripped.Add(time.Second)},
+			wantErr:   true,
+		},
+		{
+			name:      "missing_signature",
+			signer:    signer,
+			signature: nil,
+			values:    []any{id, userID, []byte("ciphertext"), roundTripped},
+			wantErr:   true,
+		},
+		{
+			name:      "different_key",
+			signer:    NewSigner([]byte("other-key")),
+			signature: signature,
+			values:    []any{id, userID, []byte("ciphertext"), roundTripped},
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.signer.Verify(testTable, tt.signature, tt.values...)
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrSignatureMismatch)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestSigner_InvalidValues(t *testing.T) {
+	t.Parallel()
+
+	signer := NewSigner([]byte("integrity-key"))
+
+	tests := []struct {
+		name   string
+		values []any
+	}{
+		{
+			name:   "wrong_value_count",
+			values: []any{uuid.New()},
+		},
+		{
+			name:   "wrong_value_type",
+			values: []any{"not-a-uuid", uuid.New(), []byte("x"), time.Now()},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := signer.Sign(testTable, tt.values...)
+			require.Error(t, err)
+			assert.NotErrorIs(t, err, ErrSignatureMismatch)
+
+			err = signer.Verify(testTable, []byte("sig"), tt.values...)
+			require.Error(t, err)
+			assert.NotErrorIs(t, err, ErrSignatureMismatch)
+		})
+	}
+}
+
+func BenchmarkSigner(b *testing.B) {
+	signer := NewSigner([]byte("integrity-key"))
+	id, userID := uuid.New(), uuid.New()
+	ciphertext := make([]byte, 1024)
+	updatedAt := time.Now()
+	signature, err := signer.Sign(testTable, id, userID, ciphertext, updatedAt)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.Run("sign", func(b *testing.B) {
+		for range b.N {
+			if _, err := signer.Sign(testTable, id, userID, ciphertext, updatedAt); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("verify", func(b *testing.B) {
+		for range b.N {
+			if err := signer.Verify(testTable, signature, id, userID, ciphertext, updatedAt); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,30 @@
+package rowsign
+
+// ColumnKind describes how a signed column value is scanned from the database.
+type ColumnKind int
+
+const (
+	// KindUUID marks a UUID column.
+	KindUUID ColumnKind = iota
+	// KindBytes marks a BYTEA column.
+	KindBytes
+	// KindTime marks a TIMESTAMP column.
+	KindTime
+)
+
+// Column describes a single column covered by row signatures.
+type Column struct {
+	// Name is the database column name.
+	Name string
+	// Kind determines how the column value is scanned and canonicalized.
+	Kind ColumnKind
+}
+
+// Table describes a signed table and the ordered columns covered by its row signatures.
+// The first column must hold the row identifier and the second the owner identifier.
+type Table struct {
+	// Name is the table name within the aegis_vault_keeper schema.
+	Name string
+	// Columns lists the signed columns in signing order.
+	Columns []Column
+}
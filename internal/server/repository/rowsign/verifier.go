@@ -0,0 +1,146 @@
+package rowsign
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/db"
+	"github.com/google/uuid"
+)
+
+// Violation describes a stored row whose signature failed verification.
+type Violation struct {
+	// Table is the name of the table containing the row.
+	Table string
+	// ID identifies the tampered row.
+	ID uuid.UUID
+	// UserID identifies the owner recorded in the tampered row.
+	UserID uuid.UUID
+}
+
+// Report summarizes a verification pass over a single table.
+type Report struct {
+	// Table is the name of the verified table.
+	Table string
+	// Violations lists rows whose signatures failed verification.
+	Violations []Violation
+	// Checked is the number of rows verified.
+	Checked int
+}
+
+// Verifier scans signed tables and verifies every row signature.
+type Verifier struct {
+	// db is the database client used to scan tables.
+	db db.DBClient
+	// signer verifies row signatures.
+	signer *Signer
+	// tables lists the signed tables to verify.
+	tables []Table
+}
+
+// NewVerifier creates a new Verifier for the given signed tables.
+func NewVerifier(dbClient db.DBClient, signer *Signer, tables ...Table) *Verifier {
+	return &Verifier{
+		db:     dbClient,
+		signer: signer,
+		tables: tables,
+	}
+}
+
+// Verify scans all signed tables and returns a report per table. The rows of all users are scanned
+// within the admin scope.
+func (v *Verifier) Verify(ctx context.Context) ([]Report, error) {
+	reports := make([]Report, 0, len(v.tables))
+	err := db.InAdminScope(ctx, v.db, func(ctx context.Context) error {
+		for _, t := range v.tables {
+			report, err := v.verifyTable(ctx, t)
+			if err != nil {
+				return fmt.Errorf("failed to verify table %s: %w", t.Name, err)
+			}
+			reports = append(reports, report)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reports, nil
+}
+
+// verifyTable scans a single table and verifies each row signature.
+func (v *Verifier) verifyTable(ctx context.Context, t Table) (Report, error) {
+	report := Report{Table: t.Name}
+	if len(t.Columns) < 2 {
+		return report, errors.New("table must declare at least id and user_id columns")
+	}
+
+	names := make([]string, 0, len(t.Columns)+1)
+	for _, col := range t.Columns {
+		names = append(names, col.Name)
+	}
+	names = append(names, "signature")
+
+	query := fmt.Sprintf("SELECT %s FROM aegis_vault_keeper.%s", strings.Join(names, ", "), t.Name)
+	rows, err := v.db.Query(ctx, query)
+	if err != nil {
+		return report, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		// dest holds scan destinations for the signed columns followed by the signature.
+		dest := scanDestinations(t)
+		if err := rows.Scan(dest...); err != nil {
+			return report, fmt.Errorf("failed to scan row: %w", err)
+		}
+
+		values := dereference(dest[:len(t.Columns)])
+		signature := *dest[len(t.Columns)].(*[]byte)
+
+		report.Checked++
+		if err := v.signer.Verify(t, signature, values...); err != nil {
+			id, _ := values[0].(uuid.UUID)
+			userID, _ := values[1].(uuid.UUID)
+			report.Violations = append(report.Violations, Violation{Table: t.Name, ID: id, UserID: userID})
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return report, fmt.Errorf("rows iteration error: %w", err)
+	}
+	return report, nil
+}
+
+// scanDestinations allocates typed scan targets for the table columns and the signature.
+func scanDestinations(t Table) []any {
+	dest := make([]any, 0, len(t.Columns)+1)
+	for _, col := range t.Columns {
+		switch col.Kind {
+		case KindUUID:
+			dest = append(dest, new(uuid.UUID))
+		case KindTime:
+			dest = append(dest, new(time.Time))
+		default:
+			dest = append(dest, new([]byte))
+		}
+	}
+	return append(dest, new([]byte))
+}
+
+// dereference converts scan targets back into plain values.
+func dereference(dest []any) []any {
+	values := make([]any, len(dest))
+	for i, d := range dest {
+		switch p := d.(type) {
+		case *uuid.UUID:
+			values[i] = *p
+		case *time.Time:
+			values[i] = *p
+		case *[]byte:
+			values[i] = *p
+		}
+	}
+	return values
+}
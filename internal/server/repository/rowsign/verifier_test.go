@@ -0,0 +1,102 @@
+package rowsign
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockDBClient implements db.DBClient for testing.
+type mockDBClient struct {
+	queryFunc func(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+func (m *mockDBClient) Exec(context.Context, string, ...interface{}) (sql.Result, error) {
+	return nil, errors.New("mock not configured")
+}
+
+func (m *mockDBClient) Query(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return m.queryFunc(ctx, query, args...)
+}
+
+func (m *mockDBClient) QueryRow(context.Context, string, ...interface{}) *sql.Row { return nil }
+
+func (m *mockDBClient) BeginTx(context.Context, *sql.TxOptions) (*sql.Tx, error) {
+	return nil, errors.New("mock not configured")
+}
+
+func (m *mockDBClient) CommitTx(*sql.Tx) error   { return nil }
+func (m *mockDBClient) RollbackTx(*sql.Tx) error { return nil }
+
+func TestVerifier_Verify_Errors(t *testing.T) {
+	t.Parallel()
+
+	queryErr := errors.New("query failed")
+
+	tests := []struct {
+		table    Table
+		name     string
+		errorMsg string
+	}{
+		{
+			name:     "query_error",
+			table:    testTable,
+			errorMsg: "failed to execute query",
+		},
+		{
+			name:     "table_without_owner_column",
+			table:    Table{Name: "broken", Columns: []Column{{Name: "id", Kind: KindUUID}}},
+			errorMsg: "at least id and user_id",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var gotQuery string
+			dbClient := &mockDBClient{
+				queryFunc: func(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+					gotQuery = query
+					return nil, queryErr
+				},
+			}
+
+			v := NewVerifier(dbClient, NewSigner([]byte("key")), tt.table)
+			reports, err := v.Verify(context.Background())
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errorMsg)
+			assert.Nil(t, reports)
+			if tt.name == "query_error" {
+				assert.Equal(t,
+					"SELECT id, user_id, note, updated_at, signature FROM aegis_vault_keeper.notes",
+					gotQuery,
+				)
+			}
+		})
+	}
+}
+
+func TestScanDestinationsRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	dest := scanDestinations(testTable)
+	require.Len(t, dest, len(testTable.Columns)+1)
+
+	id, userID := uuid.New(), uuid.New()
+	now := time.Now()
+	*dest[0].(*uuid.UUID) = id
+	*dest[1].(*uuid.UUID) = userID
+	*dest[2].(*[]byte) = []byte("data")
+	*dest[3].(*time.Time) = now
+
+	values := dereference(dest[:len(testTable.Columns)])
+	assert.Equal(t, []any{id, userID, []byte("data"), now}, values)
+}
@@ -0,0 +1,5 @@
+// Package signingkey provides request signing key persistence for the AegisVaultKeeper server.
+//
+// This package implements storage of per-client request signing keys in PostgreSQL,
+// with the key material encrypted under the key of its owner.
+package signingkey
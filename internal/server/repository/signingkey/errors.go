@@ -0,0 +1,6 @@
+package signingkey
+
+import "errors"
+
+// ErrSigningKeyNotFound indicates that the requested signing key was not found in the repository.
+var ErrSigningKeyNotFound = errors.New("signing key not found")
@@ -0,0 +1,34 @@
+package signingkey
+
+import (
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/signingkey"
+	"github.com/google/uuid"
+)
+
+// SaveParams contains the parameters for saving a signing key to the repository.
+type SaveParams struct {
+	// Entity contains the signing key to be created.
+	Entity *signingkey.SigningKey
+}
+
+// LoadParams contains the parameters for loading signing keys from the repository.
+type LoadParams struct {
+	// ID selects a single key of the user; uuid.Nil loads all of them.
+	ID uuid.UUID
+	// UserID identifies the owner of the keys.
+	UserID uuid.UUID
+}
+
+// ExistsParams contains the parameters for checking whether a user has signing keys.
+type ExistsParams struct {
+	// UserID identifies the owner of the keys.
+	UserID uuid.UUID
+}
+
+// DeleteParams contains the parameters for deleting a signing key from the repository.
+type DeleteParams struct {
+	// ID identifies the key to delete.
+	ID uuid.UUID
+	// UserID identifies the owner of the key.
+	UserID uuid.UUID
+}
@@ -0,0 +1,160 @@
+package signingkey
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/signingkey"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/db"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/fieldcrypt"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/keyprv"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/securebytes"
+	"github.com/google/uuid"
+)
+
+// itemType identifies signing keys in the ownership binding of their encrypted secrets.
+const itemType = "signing_key"
+
+// Repository provides signing key persistence operations.
+type Repository struct {
+	// db is the database client used for signing key operations.
+	db db.DBClient
+	// keyProvider provides the user keys the secrets are encrypted with.
+	keyProvider keyprv.UserKeyProvider
+}
+
+// NewRepository creates a new Repository with the provided database client and user key provider.
+func NewRepository(dbClient db.DBClient, keyProvider keyprv.UserKeyProvider) *Repository {
+	return &Repository{db: dbClient, keyProvider: keyProvider}
+}
+
+// Save creates the signing key, encrypting its secret with the key of the owner.
+func (r *Repository) Save(ctx context.Context, params SaveParams) error {
+	e := params.Entity
+
+	k, err := r.keyProvider.UserKeyProvide(ctx, e.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to provide user key: %w", err)
+	}
+	defer securebytes.Wipe(k)
+
+	b := fieldcrypt.Binding{ItemType: itemType, UserID: e.UserID, ItemID: e.ID}
+	secret, err := b.Seal(k, "secret", e.Secret)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt secret: %w", err)
+	}
+
+	query := `
+		INSERT INTO aegis_vault_keeper.signing_keys (id, user_id, name, secret, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+	if _, err := r.db.Exec(ctx, query, e.ID, e.UserID, e.Name, secret, e.CreatedAt); err != nil {
+		return fmt.Errorf("failed to save signing key: %w", err)
+	}
+	return nil
+}
+
+// Load retrieves the signing keys of the user with decrypted secrets ordered by creation time.
+// Loading by ID returns ErrSigningKeyNotFound when the user has no such key.
+func (r *Repository) Load(ctx context.Context, params LoadParams) ([]*signingkey.SigningKey, error) {
+	query := `
+		SELECT id, user_id, name, secret, created_at
+		FROM aegis_vault_keeper.signing_keys
+		WHERE user_id = $1
+	`
+	args := []any{params.UserID}
+	if params.ID != uuid.Nil {
+		query += " AND id = $2"
+		args = append(args, params.ID)
+	}
+	query += " ORDER BY created_at, id"
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load signing keys: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var keys []*signingkey.SigningKey
+	for rows.Next() {
+		var key signingkey.SigningKey
+		if err := rows.Scan(&key.ID, &key.UserID, &key.Name, &key.Secret, &key.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan signing key: %w", err)
+		}
+		keys = append(keys, &key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate signing keys: %w", err)
+	}
+	if params.ID != uuid.Nil && len(keys) == 0 {
+		return nil, ErrSigningKeyNotFound
+	}
+	if len(keys) == 0 {
+		return keys, nil
+	}
+
+	if err := r.decrypt(ctx, params.UserID, keys); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// Exists reports whether the user has at least one signing key.
+func (r *Repository) Exists(ctx context.Context, params ExistsParams) (bool, error) {
+	query := `
+		SELECT EXISTS (SELECT 1 FROM aegis_vault_keeper.signing_keys WHERE user_id = $1)
+	`
+	rows, err := r.db.Query(ctx, query, params.UserID)
+	if err != nil {
+		return false, fmt.Errorf("failed to check signing keys: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var exists bool
+	if rows.Next() {
+		if err := rows.Scan(&exists); err != nil {
+			return false, fmt.Errorf("failed to scan signing key existence: %w", err)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return false, fmt.Errorf("failed to iterate signing key existence: %w", err)
+	}
+	return exists, nil
+}
+
+// Delete removes the signing key of the user.
+func (r *Repository) Delete(ctx context.Context, params DeleteParams) error {
+	query := `
+		DELETE FROM aegis_vault_keeper.signing_keys
+		WHERE id = $1 AND user_id = $2
+	`
+	res, err := r.db.Exec(ctx, query, params.ID, params.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to delete signing key: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check deleted signing keys: %w", err)
+	}
+	if n == 0 {
+		return ErrSigningKeyNotFound
+	}
+	return nil
+}
+
+// decrypt replaces the sealed secrets of the keys with their plaintext.
+func (r *Repository) decrypt(ctx context.Context, userID uuid.UUID, keys []*signingkey.SigningKey) error {
+	k, err := r.keyProvider.UserKeyProvide(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to provide user key: %w", err)
+	}
+	defer securebytes.Wipe(k)
+
+	for _, key := range keys {
+		b := fieldcrypt.Binding{ItemType: itemType, UserID: userID, ItemID: key.ID}
+		if key.Secret, err = b.Open(k, "secret", key.Secret); err != nil {
+			return fmt.Errorf("failed to decrypt secret: %w", err)
+		}
+	}
+	return nil
+}
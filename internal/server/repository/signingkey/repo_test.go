@@ -0,0 +1,266 @@
+package signingkey
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/signingkey"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/db"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/fieldcrypt"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockResult implements sql.Result for testing.
+type mockResult struct {
+	rowsAffected int64
+}
+
+func (m mockResult) LastInsertId() (int64, error) { return 0, nil }
+func (m mockResult) RowsAffected() (int64, error) { return m.rowsAffected, nil }
+
+// mockDBClient implements db.DBClient for testing.
+type mockDBClient struct {
+	execFunc  func(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	queryFunc func(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+func (m *mockDBClient) Exec(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	if m.execFunc != nil {
+		return m.execFunc(ctx, query, args...)
+	}
+	return mockResult{rowsAffected: 1}, nil
+}
+
+func (m *mockDBClient) Query(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	if m.queryFunc != nil {
+		return m.queryFunc(ctx, query, args...)
+	}
+	return nil, errors.New("mock not configured")
+}
+
+func (m *mockDBClient) QueryRow(context.Context, string, ...interface{}) *sql.Row {
+	return nil
+}
+
+func (m *mockDBClient) BeginTx(context.Context, *sql.TxOptions) (*sql.Tx, error) {
+	return nil, errors.New("mock not configured")
+}
+
+func (m *mockDBClient) CommitTx(*sql.Tx) error {
+	return nil
+}
+
+func (m *mockDBClient) RollbackTx(*sql.Tx) error {
+	return nil
+}
+
+// Ensure mockDBClient implements db.DBClient.
+var _ db.DBClient = (*mockDBClient)(nil)
+
+// mockKeyProvider implements keyprv.UserKeyProvider for testing.
+type mockKeyProvider struct {
+	err error
+	key []byte
+}
+
+func (m *mockKeyProvider) UserKeyProvide(context.Context, uuid.UUID) ([]byte, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return bytes.Clone(m.key), nil
+}
+
+func TestRepository_Save(t *testing.T) {
+	t.Parallel()
+
+	userKey := bytes.Repeat([]byte{0x01}, 32)
+	key := &signingkey.SigningKey{
+		ID:        uuid.New(),
+		UserID:    uuid.New(),
+		Name:      "backup-cli",
+		Secret:    bytes.Repeat([]byte{0x42}, signingkey.MinSecretLen),
+		CreatedAt: time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC),
+	}
+
+	tests := []struct {
+		keyErr  error
+		execErr error
+		name    string
+	}{
+		{
+			name: "secret encrypted",
+		},
+		{
+			name:   "key provider error",
+			keyErr: errors.New("user not found"),
+		},
+		{
+			name:    "exec error",
+			execErr: errors.New("connection refused"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var gotArgs []interface{}
+			client := &mockDBClient{
+				execFunc: func(_ context.Context, query string, args ...interface{}) (sql.Result, error) {
+					assert.Contains(t, query, "INSERT INTO aegis_vault_keeper.signing_keys")
+					gotArgs = args
+					return mockResult{rowsAffected: 1}, tt.execErr
+				},
+			}
+			repo := NewRepository(client, &mockKeyProvider{key: userKey, err: tt.keyErr})
+
+			err := repo.Save(context.Background(), SaveParams{Entity: key})
+
+			switch {
+			case tt.keyErr != nil:
+				require.ErrorIs(t, err, tt.keyErr)
+				assert.Nil(t, gotArgs)
+				return
+			case tt.execErr != nil:
+				require.ErrorIs(t, err, tt.execErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, gotArgs, 5)
+			assert.Equal(t, []interface{}{key.ID, key.UserID, key.Name}, gotArgs[:3])
+			assert.Equal(t, key.CreatedAt, gotArgs[4])
+
+			sealed, ok := gotArgs[3].([]byte)
+			require.True(t, ok)
+			assert.NotContains(t, string(sealed), string(key.Secret))
+			b := fieldcrypt.Binding{ItemType: itemType, UserID: key.UserID, ItemID: key.ID}
+			secret, err := b.Open(userKey, "secret", sealed)
+			require.NoError(t, err)
+			assert.Equal(t, key.Secret, secret)
+		})
+	}
+}
+
+func TestRepository_LoadQuery(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	keyID := uuid.New()
+
+	tests := []struct {
+		name      string
+		wantWhere string
+		wantArgs  []interface{}
+		params    LoadParams
+	}{
+		{
+			name:      "all keys of user",
+			params:    LoadParams{UserID: userID},
+			wantWhere: "WHERE user_id = $1\n\t ORDER BY",
+			wantArgs:  []interface{}{userID},
+		},
+		{
+			name:      "single key",
+			params:    LoadParams{ID: keyID, UserID: userID},
+			wantWhere: "AND id = $2 ORDER BY",
+			wantArgs:  []interface{}{userID, keyID},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			queryErr := errors.New("query error")
+			var gotQuery string
+			var gotArgs []interface{}
+			client := &mockDBClient{
+				queryFunc: func(_ context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+					gotQuery, gotArgs = query, args
+					return nil, queryErr
+				},
+			}
+
+			_, err := NewRepository(client, &mockKeyProvider{}).Load(context.Background(), tt.params)
+
+			require.ErrorIs(t, err, queryErr)
+			assert.Contains(t, gotQuery, tt.wantWhere)
+			assert.Equal(t, tt.wantArgs, gotArgs)
+		})
+	}
+}
+
+func TestRepository_Exists(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	queryErr := errors.New("query error")
+	client := &mockDBClient{
+		queryFunc: func(_ context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+			assert.Contains(t, query, "SELECT EXISTS")
+			assert.Equal(t, []interface{}{userID}, args)
+			return nil, queryErr
+		},
+	}
+
+	_, err := NewRepository(client, &mockKeyProvider{}).Exists(context.Background(), ExistsParams{UserID: userID})
+
+	assert.ErrorIs(t, err, queryErr)
+}
+
+func TestRepository_Delete(t *testing.T) {
+	t.Parallel()
+
+	params := DeleteParams{ID: uuid.New(), UserID: uuid.New()}
+
+	tests := []struct {
+		execErr      error
+		wantErr      error
+		name         string
+		rowsAffected int64
+	}{
+		{
+			name:         "deleted",
+			rowsAffected: 1,
+		},
+		{
+			name:    "not found",
+			wantErr: ErrSigningKeyNotFound,
+		},
+		{
+			name:    "exec error",
+			execErr: errors.New("connection refused"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := &mockDBClient{
+				execFunc: func(_ context.Context, query string, args ...interface{}) (sql.Result, error) {
+					assert.Contains(t, query, "DELETE FROM aegis_vault_keeper.signing_keys")
+					assert.Equal(t, []interface{}{params.ID, params.UserID}, args)
+					return mockResult{rowsAffected: tt.rowsAffected}, tt.execErr
+				},
+			}
+
+			err := NewRepository(client, &mockKeyProvider{}).Delete(context.Background(), params)
+
+			switch {
+			case tt.execErr != nil:
+				assert.ErrorIs(t, err, tt.execErr)
+			case tt.wantErr != nil:
+				assert.ErrorIs(t, err, tt.wantErr)
+			default:
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
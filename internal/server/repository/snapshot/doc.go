@@ -0,0 +1,5 @@
+// Package snapshot provides database table dumps and restores for the AegisVaultKeeper server.
+//
+// This package exports the application tables as JSON documents from a consistent
+// read snapshot and replaces their content from such documents in a single transaction.
+package snapshot
@@ -0,0 +1,197 @@
+package snapshot
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/backup"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/db"
+)
+
+// tables lists the application tables included in snapshots.
+var tables = []string{
+	"auth_users",
+	"credentials",
+	"notes",
+	"bank_cards",
+	"files",
+	"credentials_history",
+	"notes_history",
+	"bank_cards_history",
+}
+
+// adminScopeQuery sets the row-level security admin scope for the current transaction, so the rows of all
+// users are dumped and restored.
+const adminScopeQuery = "SELECT set_config('aegis_vault_keeper.admin_scope', 'on', true)"
+
+// Repository provides dump and restore operations over the application tables.
+type Repository struct {
+	// db is the database client used for table operations.
+	db db.DBClient
+}
+
+// NewRepository creates a new Repository with the provided database client.
+func NewRepository(dbClient db.DBClient) *Repository {
+	return &Repository{db: dbClient}
+}
+
+// Tables returns the names of the application tables included in snapshots.
+func (r *Repository) Tables() []string {
+	return slices.Clone(tables)
+}
+
+// Dump streams every row of the application tables as a JSON document to fn.
+// All tables are read within a single repeatable read transaction, so the dump is consistent.
+func (r *Repository) Dump(ctx context.Context, fn func(table string, row []byte) error) (err error) {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return fmt.Errorf("failed to start dump transaction: %w", err)
+	}
+	defer func() {
+		if rbErr := r.db.RollbackTx(tx); rbErr != nil && !errors.Is(rbErr, sql.ErrTxDone) {
+			err = errors.Join(err, rbErr)
+		}
+	}()
+
+	if _, err := tx.ExecContext(ctx, adminScopeQuery); err != nil {
+		return fmt.Errorf("failed to set dump scope: %w", err)
+	}
+	for _, table := range tables {
+		if err := dumpTable(ctx, tx, table, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Replace replaces the content of all application tables with the rows provided by source.
+// The row count of every table is checked against its entry before the transaction is committed.
+func (r *Repository) Replace(
+	ctx context.Context,
+	entries []backup.TableEntry,
+	source backup.RowSource,
+) (err error) {
+	if err := validateEntries(entries); err != nil {
+		return err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to start restore transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := r.db.RollbackTx(tx); rbErr != nil {
+				err = errors.Join(err, rbErr)
+			}
+		}
+	}()
+
+	if _, err := tx.ExecContext(ctx, adminScopeQuery); err != nil {
+		return fmt.Errorf("failed to set restore scope: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, truncateQuery()); err != nil {
+		return fmt.Errorf("failed to truncate tables: %w", err)
+	}
+
+	for _, e := range entries {
+		if err := restoreTable(ctx, tx, e, source); err != nil {
+			return err
+		}
+	}
+
+	if err := r.db.CommitTx(tx); err != nil {
+		return fmt.Errorf("failed to commit restore transaction: %w", err)
+	}
+	return nil
+}
+
+// dumpTable streams the rows of a single table to fn.
+func dumpTable(ctx context.Context, tx *sql.Tx, table string, fn func(table string, row []byte) error) error {
+	query := fmt.Sprintf("SELECT row_to_json(t)::text FROM aegis_vault_keeper.%s t ORDER BY t.id", table)
+	rows, err := tx.QueryContext(ctx, query)
+	if err != nil {
+		return fmt.Errorf("failed to query table %s: %w", table, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var row string
+		if err := rows.Scan(&row); err != nil {
+			return fmt.Errorf("failed to scan table %s row: %w", table, err)
+		}
+		if err := fn(table, []byte(row)); err != nil {
+			return fmt.Errorf("failed to process table %s row: %w", table, err)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate table %s rows: %w", table, err)
+	}
+	return nil
+}
+
+// restoreTable inserts the rows of a single table and verifies the resulting row count.
+func restoreTable(
+	ctx context.Context,
+	tx *sql.Tx,
+	e backup.TableEntry,
+	source backup.RowSource,
+) error {
+	insert := fmt.Sprintf(
+		"INSERT INTO aegis_vault_keeper.%[1]s "+
+			"SELECT * FROM json_populate_record(NULL::aegis_vault_keeper.%[1]s, $1::json)",
+		e.Name,
+	)
+	err := source(e.Name, func(row []byte) error {
+		if _, err := tx.ExecContext(ctx, insert, string(row)); err != nil {
+			return fmt.Errorf("failed to insert row: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to restore table %s: %w", e.Name, err)
+	}
+
+	var count int
+	query := fmt.Sprintf("SELECT count(*) FROM aegis_vault_keeper.%s", e.Name)
+	if err := tx.QueryRowContext(ctx, query).Scan(&count); err != nil {
+		return fmt.Errorf("failed to count table %s rows: %w", e.Name, err)
+	}
+	if count != e.Rows {
+		return fmt.Errorf("%w: table %s has %d rows, expected %d",
+			backup.ErrInconsistentSnapshot, e.Name, count, e.Rows)
+	}
+	return nil
+}
+
+// validateEntries checks that entries cover exactly the application tables.
+func validateEntries(entries []backup.TableEntry) error {
+	if len(entries) != len(tables) {
+		return fmt.Errorf("%w: expected %d tables, got %d",
+			backup.ErrInconsistentSnapshot, len(tables), len(entries))
+	}
+	seen := make(map[string]struct{}, len(entries))
+	for _, e := range entries {
+		if !slices.Contains(tables, e.Name) {
+			return fmt.Errorf("%w: unknown table %q", backup.ErrInconsistentSnapshot, e.Name)
+		}
+		if _, ok := seen[e.Name]; ok {
+			return fmt.Errorf("%w: duplicate table %q", backup.ErrInconsistentSnapshot, e.Name)
+		}
+		seen[e.Name] = struct{}{}
+	}
+	return nil
+}
+
+// truncateQuery builds the statement removing the content of all application tables.
+func truncateQuery() string {
+	qualified := make([]string, len(tables))
+	for i, t := range tables {
+		qualified[i] = "aegis_vault_keeper." + t
+	}
+	return "TRUNCATE " + strings.Join(qualified, ", ")
+}
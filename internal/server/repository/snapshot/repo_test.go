@@ -0,0 +1,138 @@
+package snapshot
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/backup"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockDBClient implements db.DBClient for testing.
+type mockDBClient struct {
+	beginTxErr   error
+	beginTxCalls int
+}
+
+func (m *mockDBClient) Exec(context.Context, string, ...interface{}) (sql.Result, error) {
+	return nil, errors.New("mock not configured")
+}
+
+func (m *mockDBClient) Query(context.Context, string, ...interface{}) (*sql.Rows, error) {
+	return nil, errors.New("mock not configured")
+}
+
+func (m *mockDBClient) QueryRow(context.Context, string, ...interface{}) *sql.Row {
+	return nil
+}
+
+func (m *mockDBClient) BeginTx(context.Context, *sql.TxOptions) (*sql.Tx, error) {
+	m.beginTxCalls++
+	return nil, m.beginTxErr
+}
+
+func (m *mockDBClient) CommitTx(*sql.Tx) error {
+	return nil
+}
+
+func (m *mockDBClient) RollbackTx(*sql.Tx) error {
+	return nil
+}
+
+func allEntries() []backup.TableEntry {
+	entries := make([]backup.TableEntry, len(tables))
+	for i, t := range tables {
+		entries[i] = backup.TableEntry{Name: t}
+	}
+	return entries
+}
+
+func TestRepository_Tables(t *testing.T) {
+	t.Parallel()
+
+	r := NewRepository(nil)
+	got := r.Tables()
+	assert.Equal(t, []string{
+		"auth_users", "credentials", "notes", "bank_cards", "files",
+		"credentials_history", "notes_history", "bank_cards_history",
+	}, got)
+
+	got[0] = "changed"
+	assert.Equal(t, "auth_users", r.Tables()[0], "returned slice must be a copy")
+}
+
+func TestRepository_Dump_BeginTxError(t *testing.T) {
+	t.Parallel()
+
+	client := &mockDBClient{beginTxErr: errors.New("connection refused")}
+	err := NewRepository(client).Dump(context.Background(), func(string, []byte) error { return nil })
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to start dump transaction")
+}
+
+func TestRepository_Replace_Validation(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		errContains string
+		entries     []backup.TableEntry
+		wantBegin   bool
+	}{
+		{
+			name:        "missing_tables",
+			entries:     allEntries()[:2],
+			errContains: "expected 8 tables, got 2",
+		},
+		{
+			name: "unknown_table",
+			entries: append(allEntries()[:len(tables)-1], backup.TableEntry{
+				Name: "pg_catalog.pg_authid",
+			}),
+			errContains: "unknown table",
+		},
+		{
+			name:        "duplicate_table",
+			entries:     append(allEntries()[:len(tables)-1], backup.TableEntry{Name: "notes"}),
+			errContains: "duplicate table",
+		},
+		{
+			name:        "valid_entries_reach_database",
+			entries:     allEntries(),
+			errContains: "failed to start restore transaction",
+			wantBegin:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := &mockDBClient{beginTxErr: errors.New("connection refused")}
+			err := NewRepository(client).Replace(context.Background(), tt.entries,
+				func(string, func([]byte) error) error { return nil })
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errContains)
+			assert.Equal(t, tt.wantBegin, client.beginTxCalls == 1)
+			if !tt.wantBegin {
+				assert.ErrorIs(t, err, backup.ErrInconsistentSnapshot)
+			}
+		})
+	}
+}
+
+func TestTruncateQuery(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t,
+		"TRUNCATE aegis_vault_keeper.auth_users, aegis_vault_keeper.credentials, aegis_vault_keeper.notes, "+
+			"aegis_vault_keeper.bank_cards, aegis_vault_keeper.files, aegis_vault_keeper.credentials_history, "+
+			"aegis_vault_keeper.notes_history, aegis_vault_keeper.bank_cards_history",
+		truncateQuery(),
+	)
+}
@@ -0,0 +1,196 @@
+package snapshot
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/backup"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rlsStore is an in-memory set of application tables whose rows, except those of auth_users, are hidden by
+// row-level security outside the admin scope.
+type rlsStore struct {
+	// rows holds the JSON documents stored per table.
+	rows map[string][]string
+	// mu guards rows and adminScope.
+	mu sync.Mutex
+	// adminScope reports whether the running transaction is in the admin scope.
+	adminScope bool
+}
+
+func (s *rlsStore) Connect(context.Context) (driver.Conn, error) { return rlsConn{s}, nil }
+func (s *rlsStore) Driver() driver.Driver                        { return nil }
+
+// hidden reports whether row-level security hides the rows of the table from the running statement.
+func (s *rlsStore) hidden(table string) bool {
+	return table != "auth_users" && !s.adminScope
+}
+
+// rlsConn answers the statements of the snapshot repository.
+type rlsConn struct {
+	s *rlsStore
+}
+
+func (rlsConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("prepare not supported") }
+func (rlsConn) Close() error                        { return nil }
+func (c rlsConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c rlsConn) BeginTx(context.Context, driver.TxOptions) (driver.Tx, error) { return rlsTx(c), nil }
+
+// rlsTx ends the admin scope together with the transaction.
+type rlsTx struct {
+	s *rlsStore
+}
+
+func (t rlsTx) Commit() error   { return t.end() }
+func (t rlsTx) Rollback() error { return t.end() }
+
+func (t rlsTx) end() error {
+	t.s.mu.Lock()
+	defer t.s.mu.Unlock()
+	t.s.adminScope = false
+	return nil
+}
+
+// tableOf returns the application table named in the query.
+func tableOf(query string) string {
+	_, rest, _ := strings.Cut(query, "aegis_vault_keeper.")
+	return strings.Fields(rest)[0]
+}
+
+func (c rlsConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.s.mu.Lock()
+	defer c.s.mu.Unlock()
+
+	switch {
+	case query == adminScopeQuery:
+		c.s.adminScope = true
+	case strings.HasPrefix(query, "TRUNCATE"):
+		c.s.rows = make(map[string][]string)
+	case strings.HasPrefix(query, "INSERT INTO"):
+		table := tableOf(query)
+		if c.s.hidden(table) {
+			return nil, errors.New("new row violates row-level security policy for table " + table)
+		}
+		row, _ := args[0].Value.(string)
+		c.s.rows[table] = append(c.s.rows[table], row)
+	default:
+		return nil, errors.New("unexpected statement: " + query)
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (c rlsConn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	c.s.mu.Lock()
+	defer c.s.mu.Unlock()
+
+	table := tableOf(query)
+	var visible []string
+	if !c.s.hidden(table) {
+		visible = c.s.rows[table]
+	}
+	switch {
+	case strings.HasPrefix(query, "SELECT row_to_json"):
+		rows := &rlsRows{columns: []string{"row_to_json"}}
+		for _, row := range visible {
+			rows.values = append(rows.values, []driver.Value{row})
+		}
+		return rows, nil
+	case strings.HasPrefix(query, "SELECT count(*)"):
+		return &rlsRows{columns: []string{"count"}, values: [][]driver.Value{{int64(len(visible))}}}, nil
+	default:
+		return nil, errors.New("unexpected query: " + query)
+	}
+}
+
+// rlsRows iterates over rows of driver values.
+type rlsRows struct {
+	columns []string
+	values  [][]driver.Value
+}
+
+func (r *rlsRows) Columns() []string { return r.columns }
+func (r *rlsRows) Close() error      { return nil }
+
+func (r *rlsRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
+// sqlClient implements db.DBClient over a database/sql handle.
+type sqlClient struct {
+	db *sql.DB
+}
+
+func (c sqlClient) Exec(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return c.db.ExecContext(ctx, query, args...)
+}
+
+func (c sqlClient) QueryRow(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return c.db.QueryRowContext(ctx, query, args...)
+}
+
+func (c sqlClient) Query(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return c.db.QueryContext(ctx, query, args...)
+}
+
+func (c sqlClient) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	return c.db.BeginTx(ctx, opts)
+}
+
+func (sqlClient) CommitTx(tx *sql.Tx) error   { return tx.Commit() }
+func (sqlClient) RollbackTx(tx *sql.Tx) error { return tx.Rollback() }
+
+func TestRepository_DumpReplace_RowLevelSecurity(t *testing.T) {
+	t.Parallel()
+
+	store := &rlsStore{rows: make(map[string][]string)}
+	for _, table := range tables {
+		store.rows[table] = []string{`{"table":"` + table + `"}`}
+	}
+	client := sqlClient{db: sql.OpenDB(store)}
+	t.Cleanup(func() { _ = client.db.Close() })
+	r := NewRepository(client)
+
+	// dumped collects the dumped rows per table.
+	dumped := make(map[string][]string)
+	err := r.Dump(context.Background(), func(table string, row []byte) error {
+		dumped[table] = append(dumped[table], string(row))
+		return nil
+	})
+	require.NoError(t, err)
+	for _, table := range tables {
+		assert.Equal(t, []string{`{"table":"` + table + `"}`}, dumped[table],
+			"the rows of every user of %s are dumped within the admin scope", table)
+	}
+
+	entries := make([]backup.TableEntry, 0, len(tables))
+	for _, table := range tables {
+		entries = append(entries, backup.TableEntry{Name: table, Rows: len(dumped[table])})
+	}
+	err = r.Replace(context.Background(), entries, func(table string, fn func(row []byte) error) error {
+		for _, row := range dumped[table] {
+			if err := fn([]byte(row)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	require.NoError(t, err, "the rows of every user are restored and counted within the admin scope")
+	assert.Equal(t, dumped, store.rows)
+	assert.False(t, store.adminScope, "the admin scope ends with the transaction")
+}
@@ -0,0 +1,6 @@
+// Package stats provides usage statistics persistence for the AegisVaultKeeper server.
+//
+// This package implements the aggregate queries over users, devices and items the usage rollups are taken
+// from, and storage of the rollups in PostgreSQL. The queries run without a user scope, so row-level security
+// lets them count the rows of every user.
+package stats
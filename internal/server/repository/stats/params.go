@@ -0,0 +1,33 @@
+package stats
+
+import (
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/stats"
+)
+
+// CollectParams contains the parameters for collecting the aggregate usage of the instance.
+type CollectParams struct {
+	// At is the moment the active user windows end at.
+	At time.Time
+}
+
+// SaveParams contains the parameters for saving a rollup to the repository.
+type SaveParams struct {
+	// Entity contains the rollup to be persisted.
+	Entity *stats.Rollup
+}
+
+// LoadParams contains the parameters for loading rollups from the repository.
+type LoadParams struct {
+	// From selects the rollups taken at or after this moment.
+	From time.Time
+	// To selects the rollups taken before this moment.
+	To time.Time
+}
+
+// PruneParams contains the parameters for removing old rollups from the repository.
+type PruneParams struct {
+	// Before selects the rollups taken before this moment.
+	Before time.Time
+}
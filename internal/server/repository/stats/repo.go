@@ -0,0 +1,197 @@
+package stats
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/stats"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/db"
+)
+
+// Repository provides usage statistics persistence operations.
+type Repository struct {
+	// db is the database client used for statistics operations.
+	db db.DBClient
+}
+
+// NewRepository creates a new Repository with the provided database client.
+func NewRepository(dbClient db.DBClient) *Repository {
+	return &Repository{db: dbClient}
+}
+
+// Collect returns a rollup holding the aggregate usage of the instance at the moment. Request counters are
+// not stored in the database and are left zero. The items of all users are counted within the admin scope.
+func (r *Repository) Collect(ctx context.Context, params CollectParams) (*stats.Rollup, error) {
+	// rollup holds the rollup collected inside the admin scope.
+	var rollup *stats.Rollup
+	err := db.InAdminScope(ctx, r.db, func(ctx context.Context) error {
+		var err error
+		rollup, err = r.collect(ctx, params)
+		return err
+	})
+	return rollup, err
+}
+
+// collect counts the aggregate usage of the instance.
+func (r *Repository) collect(ctx context.Context, params CollectParams) (*stats.Rollup, error) {
+	usage, err := r.usage(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT
+			(SELECT count(*) FROM aegis_vault_keeper.auth_users WHERE profile_of IS NULL),
+			(SELECT count(DISTINCT user_id) FROM aegis_vault_keeper.auth_devices WHERE last_seen_at >= $1),
+			(SELECT count(DISTINCT user_id) FROM aegis_vault_keeper.auth_devices WHERE last_seen_at >= $2),
+			(SELECT count(*) FROM aegis_vault_keeper.credentials),
+			(SELECT count(*) FROM aegis_vault_keeper.notes),
+			(SELECT count(*) FROM aegis_vault_keeper.bank_cards),
+			(SELECT count(*) FROM aegis_vault_keeper.files),
+			(SELECT COALESCE(sum(size), 0) FROM aegis_vault_keeper.files)
+	`
+	rows, err := r.db.Query(ctx, query,
+		params.At.Add(-stats.DailyWindow), params.At.Add(-stats.MonthlyWindow),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count usage: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("failed to count usage: %w", err)
+		}
+		return nil, errors.New("failed to count usage: no result")
+	}
+	// credentials, notes, cards and files hold the item counts by type.
+	var credentials, notes, cards, files int64
+	e := stats.Rollup{RolledUpAt: params.At}
+	if err := rows.Scan(
+		&e.Users, &e.DailyActiveUsers, &e.MonthlyActiveUsers,
+		&credentials, &notes, &cards, &files, &e.StorageBytes,
+	); err != nil {
+		return nil, fmt.Errorf("failed to scan usage: %w", err)
+	}
+	e.Items = map[string]int64{
+		stats.ItemCredentials: credentials,
+		stats.ItemNotes:       notes,
+		stats.ItemBankCards:   cards,
+		stats.ItemFiles:       files,
+	}
+	e.StorageTiers = stats.Distribution(e.Users, usage)
+	return &e, nil
+}
+
+// usage returns the bytes of file content stored by every user storing files.
+func (r *Repository) usage(ctx context.Context) ([]int64, error) {
+	query := `
+		SELECT COALESCE(sum(size), 0)
+		FROM aegis_vault_keeper.files
+		GROUP BY user_id
+	`
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load storage usage: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var usage []int64
+	for rows.Next() {
+		// bytes holds the stored bytes of the current user.
+		var bytes int64
+		if err := rows.Scan(&bytes); err != nil {
+			return nil, fmt.Errorf("failed to scan storage usage: %w", err)
+		}
+		usage = append(usage, bytes)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate storage usage: %w", err)
+	}
+	return usage, nil
+}
+
+// Save stores the rollup.
+func (r *Repository) Save(ctx context.Context, params SaveParams) error {
+	e := params.Entity
+
+	items, err := json.Marshal(e.Items)
+	if err != nil {
+		return fmt.Errorf("failed to encode rollup item counts: %w", err)
+	}
+	tiers, err := json.Marshal(e.StorageTiers)
+	if err != nil {
+		return fmt.Errorf("failed to encode rollup storage tiers: %w", err)
+	}
+
+	query := `
+		INSERT INTO aegis_vault_keeper.stats_rollups (
+			id, rolled_up_at, users, daily_active_users, monthly_active_users, items, storage_bytes, storage_tiers,
+			requests, client_errors, server_errors, sync_requests, sync_bytes
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+	`
+	if _, err := r.db.Exec(ctx, query,
+		e.ID, e.RolledUpAt, e.Users, e.DailyActiveUsers, e.MonthlyActiveUsers, items, e.StorageBytes, tiers,
+		e.Requests, e.ClientErrors, e.ServerErrors, e.SyncRequests, e.SyncBytes,
+	); err != nil {
+		return fmt.Errorf("failed to save rollup: %w", err)
+	}
+	return nil
+}
+
+// Load retrieves the rollups taken in the period, oldest first.
+func (r *Repository) Load(ctx context.Context, params LoadParams) ([]*stats.Rollup, error) {
+	query := `
+		SELECT id, rolled_up_at, users, daily_active_users, monthly_active_users, items, storage_bytes,
+			storage_tiers, requests, client_errors, server_errors, sync_requests, sync_bytes
+		FROM aegis_vault_keeper.stats_rollups
+		WHERE rolled_up_at >= $1 AND rolled_up_at < $2
+		ORDER BY rolled_up_at, id
+	`
+	rows, err := r.db.Query(ctx, query, params.From, params.To)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load rollups: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var rollups []*stats.Rollup
+	for rows.Next() {
+		var (
+			e            stats.Rollup
+			items, tiers []byte
+		)
+		if err := rows.Scan(
+			&e.ID, &e.RolledUpAt, &e.Users, &e.DailyActiveUsers, &e.MonthlyActiveUsers, &items, &e.StorageBytes,
+			&tiers, &e.Requests, &e.ClientErrors, &e.ServerErrors, &e.SyncRequests, &e.SyncBytes,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan rollup: %w", err)
+		}
+		if err := json.Unmarshal(items, &e.Items); err != nil {
+			return nil, fmt.Errorf("invalid item counts of rollup %s: %w", e.ID, err)
+		}
+		if err := json.Unmarshal(tiers, &e.StorageTiers); err != nil {
+			return nil, fmt.Errorf("invalid storage tiers of rollup %s: %w", e.ID, err)
+		}
+		rollups = append(rollups, &e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate rollups: %w", err)
+	}
+	return rollups, nil
+}
+
+// Prune deletes the rollups taken before the moment and returns the number of deleted rollups.
+func (r *Repository) Prune(ctx context.Context, params PruneParams) (int64, error) {
+	res, err := r.db.Exec(ctx, "DELETE FROM aegis_vault_keeper.stats_rollups WHERE rolled_up_at < $1", params.Before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune rollups: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to count pruned rollups: %w", err)
+	}
+	return n, nil
+}
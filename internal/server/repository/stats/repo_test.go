@@ -0,0 +1,210 @@
+package stats
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/stats"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/repository/db"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockResult implements sql.Result for testing.
+type mockResult struct {
+	rowsAffected int64
+}
+
+func (m mockResult) LastInsertId() (int64, error) { return 0, nil }
+func (m mockResult) RowsAffected() (int64, error) { return m.rowsAffected, nil }
+
+// mockDBClient implements db.DBClient for testing.
+type mockDBClient struct {
+	execFunc  func(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	queryFunc func(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+func (m *mockDBClient) Exec(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	if m.execFunc != nil {
+		return m.execFunc(ctx, query, args...)
+	}
+	return mockResult{rowsAffected: 1}, nil
+}
+
+func (m *mockDBClient) Query(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	if m.queryFunc != nil {
+		return m.queryFunc(ctx, query, args...)
+	}
+	return nil, errors.New("mock not configured")
+}
+
+func (m *mockDBClient) QueryRow(context.Context, string, ...interface{}) *sql.Row {
+	return nil
+}
+
+func (m *mockDBClient) BeginTx(context.Context, *sql.TxOptions) (*sql.Tx, error) {
+	return nil, errors.New("mock not configured")
+}
+
+func (m *mockDBClient) CommitTx(*sql.Tx) error {
+	return nil
+}
+
+func (m *mockDBClient) RollbackTx(*sql.Tx) error {
+	return nil
+}
+
+// Ensure mockDBClient implements db.DBClient.
+var _ db.DBClient = (*mockDBClient)(nil)
+
+func TestRepository_Collect(t *testing.T) {
+	t.Parallel()
+
+	queryErr := errors.New("query error")
+	var gotQuery string
+	client := &mockDBClient{
+		queryFunc: func(_ context.Context, query string, _ ...interface{}) (*sql.Rows, error) {
+			gotQuery = query
+			return nil, queryErr
+		},
+	}
+
+	_, err := NewRepository(client).Collect(context.Background(), CollectParams{At: time.Now()})
+
+	require.ErrorIs(t, err, queryErr)
+	assert.Contains(t, gotQuery, "GROUP BY user_id")
+}
+
+// adminScopedClient wraps mockDBClient and records whether queries ran within the admin scope.
+type adminScopedClient struct {
+	mockDBClient
+	inScope bool
+}
+
+func (a *adminScopedClient) RunInAdminScope(ctx context.Context, fn func(ctx context.Context) error) error {
+	a.inScope = true
+	defer func() { a.inScope = false }()
+	return fn(ctx)
+}
+
+func TestRepository_Collect_AdminScope(t *testing.T) {
+	t.Parallel()
+
+	queryErr := errors.New("query error")
+	client := &adminScopedClient{}
+	var scoped []bool
+	client.queryFunc = func(context.Context, string, ...interface{}) (*sql.Rows, error) {
+		scoped = append(scoped, client.inScope)
+		return nil, queryErr
+	}
+
+	_, err := NewRepository(client).Collect(context.Background(), CollectParams{At: time.Now()})
+
+	require.ErrorIs(t, err, queryErr)
+	assert.Equal(t, []bool{true}, scoped)
+}
+
+func TestRepository_Save(t *testing.T) {
+	t.Parallel()
+
+	id := uuid.New()
+	rolledUpAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	execErr := errors.New("connection refused")
+
+	tests := []struct {
+		execErr error
+		name    string
+	}{
+		{name: "success"},
+		{name: "exec error", execErr: execErr},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var gotArgs []interface{}
+			client := &mockDBClient{
+				execFunc: func(_ context.Context, query string, args ...interface{}) (sql.Result, error) {
+					assert.Contains(t, query, "INSERT INTO aegis_vault_keeper.stats_rollups")
+					gotArgs = args
+					return mockResult{rowsAffected: 1}, tt.execErr
+				},
+			}
+
+			entity := &stats.Rollup{
+				ID:                 id,
+				RolledUpAt:         rolledUpAt,
+				Users:              10,
+				DailyActiveUsers:   3,
+				MonthlyActiveUsers: 7,
+				Items:              map[string]int64{stats.ItemNotes: 4},
+				StorageBytes:       2048,
+				StorageTiers:       map[string]int64{"under_1mib": 10},
+				Requests:           100,
+				ClientErrors:       5,
+				ServerErrors:       1,
+				SyncRequests:       20,
+				SyncBytes:          4096,
+			}
+			err := NewRepository(client).Save(context.Background(), SaveParams{Entity: entity})
+
+			if tt.execErr != nil {
+				assert.ErrorIs(t, err, tt.execErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, []interface{}{
+				id, rolledUpAt, int64(10), int64(3), int64(7), []byte(`{"notes":4}`), int64(2048),
+				[]byte(`{"under_1mib":10}`), int64(100), int64(5), int64(1), int64(20), int64(4096),
+			}, gotArgs)
+		})
+	}
+}
+
+func TestRepository_Load(t *testing.T) {
+	t.Parallel()
+
+	from := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	queryErr := errors.New("query error")
+	var gotQuery string
+	var gotArgs []interface{}
+	client := &mockDBClient{
+		queryFunc: func(_ context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+			gotQuery, gotArgs = query, args
+			return nil, queryErr
+		},
+	}
+
+	_, err := NewRepository(client).Load(context.Background(), LoadParams{From: from, To: to})
+
+	require.ErrorIs(t, err, queryErr)
+	assert.Contains(t, gotQuery, "WHERE rolled_up_at >= $1 AND rolled_up_at < $2")
+	assert.Contains(t, gotQuery, "ORDER BY rolled_up_at")
+	assert.Equal(t, []interface{}{from, to}, gotArgs)
+}
+
+func TestRepository_Prune(t *testing.T) {
+	t.Parallel()
+
+	before := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+	var gotArgs []interface{}
+	client := &mockDBClient{
+		execFunc: func(_ context.Context, query string, args ...interface{}) (sql.Result, error) {
+			assert.Contains(t, query, "DELETE FROM aegis_vault_keeper.stats_rollups WHERE rolled_up_at < $1")
+			gotArgs = args
+			return mockResult{rowsAffected: 3}, nil
+		},
+	}
+
+	n, err := NewRepository(client).Prune(context.Background(), PruneParams{Before: before})
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), n)
+	assert.Equal(t, []interface{}{before}, gotArgs)
+}
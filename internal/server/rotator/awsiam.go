@@ -0,0 +1,124 @@
+package rotator
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/rotation"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/sigv4"
+)
+
+// iamAPIVersion is the version of the AWS IAM query API.
+const iamAPIVersion = "2010-05-08"
+
+// maxResponseSize limits how much of a response is read.
+const maxResponseSize = 64 << 10
+
+// AWSIAMRotator replaces an AWS IAM access key: it creates a new key for the IAM user and deletes the
+// replaced one, signing both requests with the replaced key. The user may hold at most one other key,
+// since IAM allows two access keys per user.
+type AWSIAMRotator struct {
+	// client performs the HTTP requests.
+	client *http.Client
+	// now returns the current time used for request signing.
+	now func() time.Time
+}
+
+// NewAWSIAMRotator creates a new AWSIAMRotator.
+func NewAWSIAMRotator(client *http.Client) *AWSIAMRotator {
+	return &AWSIAMRotator{client: client, now: time.Now}
+}
+
+// iamCreateAccessKeyResponse is the CreateAccessKey response of the IAM query API.
+type iamCreateAccessKeyResponse struct {
+	// AccessKeyID contains the identifier of the created key.
+	AccessKeyID string `xml:"CreateAccessKeyResult>AccessKey>AccessKeyId"`
+	// SecretAccessKey contains the secret of the created key.
+	SecretAccessKey string `xml:"CreateAccessKeyResult>AccessKey>SecretAccessKey"`
+}
+
+// iamErrorResponse is the error response of the IAM query API.
+type iamErrorResponse struct {
+	// Code contains the error code, e.g. InvalidClientTokenId.
+	Code string `xml:"Error>Code"`
+}
+
+// Rotate creates a new access key for the IAM user and deletes the key held by the credential.
+// When the replaced key cannot be deleted, the new key is returned with the error.
+func (r *AWSIAMRotator) Rotate(
+	ctx context.Context,
+	p *rotation.Policy,
+	current rotation.Secret,
+) (rotation.Secret, error) {
+	body, err := r.call(ctx, p.Settings, current, url.Values{
+		"Action":   {"CreateAccessKey"},
+		"UserName": {p.Settings.UserName},
+	})
+	if err != nil {
+		return rotation.Secret{}, fmt.Errorf("failed to create AWS access key: %w", err)
+	}
+	var created iamCreateAccessKeyResponse
+	if err := xml.Unmarshal(body, &created); err != nil || created.AccessKeyID == "" || created.SecretAccessKey == "" {
+		return rotation.Secret{}, errors.New("failed to create AWS access key: malformed response")
+	}
+	secret := rotation.Secret{Login: created.AccessKeyID, Password: created.SecretAccessKey}
+
+	if _, err := r.call(ctx, p.Settings, current, url.Values{
+		"Action":      {"DeleteAccessKey"},
+		"UserName":    {p.Settings.UserName},
+		"AccessKeyId": {current.Login},
+	}); err != nil {
+		return secret, fmt.Errorf("failed to delete replaced AWS access key: %w", err)
+	}
+	return secret, nil
+}
+
+// call sends the signed IAM query API action and returns the response body.
+func (r *AWSIAMRotator) call(
+	ctx context.Context,
+	s rotation.Settings,
+	key rotation.Secret,
+	params url.Values,
+) ([]byte, error) {
+	params.Set("Version", iamAPIVersion)
+	payload := params.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.Endpoint+"/", strings.NewReader(payload))
+	if err != nil {
+		return nil, errors.New("failed to build request")
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+	signer := sigv4.NewSigner("iam", s.Region, sigv4.Credentials{
+		AccessKeyID:     key.Login,
+		SecretAccessKey: key.Password,
+	}, "content-type")
+	signer.Sign(req, sigv4.PayloadHash([]byte(payload)), r.now())
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		var apiErr iamErrorResponse
+		if xml.Unmarshal(body, &apiErr) == nil && apiErr.Code != "" {
+			return nil, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, apiErr.Code)
+		}
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return body, nil
+}
@@ -0,0 +1,127 @@
+package rotator
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/rotation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createAccessKeyResponse is a successful CreateAccessKey response of the IAM query API.
+const createAccessKeyResponse = `<CreateAccessKeyResponse xmlns="https://iam.amazonaws.com/doc/2010-05-08/">
+  <CreateAccessKeyResult>
+    <AccessKey>
+      <UserName>ci-deployer</UserName>
+      <AccessKeyId>AKIANEWKEY</AccessKeyId>
+      <Status>Active</Status>
+      <SecretAccessKey>new-secret-key</SecretAccessKey>
+    </AccessKey>
+  </CreateAccessKeyResult>
+</CreateAccessKeyResponse>`
+
+// iamErrorBody is an error response of the IAM query API.
+const iamErrorBody = `<ErrorResponse><Error><Type>Sender</Type><Code>LimitExceeded</Code>` +
+	`<Message>Cannot exceed quota for AccessKeysPerUser: 2</Message></Error></ErrorResponse>`
+
+func TestAWSIAMRotator_Rotate(t *testing.T) {
+	t.Parallel()
+
+	current := rotation.Secret{Login: "AKIAOLDKEY", Password: "old-secret-key"}
+
+	tests := []struct {
+		name         string
+		createStatus int
+		createBody   string
+		deleteStatus int
+		wantErr      string
+		wantSecret   rotation.Secret
+	}{
+		{
+			name:         "key replaced",
+			createStatus: http.StatusOK,
+			createBody:   createAccessKeyResponse,
+			deleteStatus: http.StatusOK,
+			wantSecret:   rotation.Secret{Login: "AKIANEWKEY", Password: "new-secret-key"},
+		},
+		{
+			name:         "key quota exceeded",
+			createStatus: http.StatusConflict,
+			createBody:   iamErrorBody,
+			wantErr:      "unexpected status 409: LimitExceeded",
+		},
+		{
+			name:         "malformed response",
+			createStatus: http.StatusOK,
+			createBody:   "<CreateAccessKeyResponse/>",
+			wantErr:      "malformed response",
+		},
+		{
+			name:         "replaced key kept",
+			createStatus: http.StatusOK,
+			createBody:   createAccessKeyResponse,
+			deleteStatus: http.StatusForbidden,
+			wantErr:      "failed to delete replaced AWS access key",
+			wantSecret:   rotation.Secret{Login: "AKIANEWKEY", Password: "new-secret-key"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				mu      sync.Mutex
+				actions []string
+			)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				require.NoError(t, r.ParseForm())
+				assert.Equal(t, "2010-05-08", r.PostForm.Get("Version"))
+				assert.Equal(t, "ci-deployer", r.PostForm.Get("UserName"))
+				assert.Equal(t, "20261001T120000Z", r.Header.Get("X-Amz-Date"))
+				assert.True(t, strings.HasPrefix(r.Header.Get("Authorization"),
+					"AWS4-HMAC-SHA256 Credential=AKIAOLDKEY/20261001/eu-central-1/iam/aws4_request, "+
+						"SignedHeaders=content-type;host;x-amz-date, Signature="))
+
+				mu.Lock()
+				actions = append(actions, r.PostForm.Get("Action"))
+				mu.Unlock()
+				switch r.PostForm.Get("Action") {
+				case "CreateAccessKey":
+					w.WriteHeader(tt.createStatus)
+					_, _ = w.Write([]byte(tt.createBody))
+				case "DeleteAccessKey":
+					assert.Equal(t, "AKIAOLDKEY", r.PostForm.Get("AccessKeyId"))
+					w.WriteHeader(tt.deleteStatus)
+				}
+			}))
+			t.Cleanup(server.Close)
+
+			r := NewAWSIAMRotator(server.Client())
+			r.now = func() time.Time { return time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC) }
+			got, err := r.Rotate(context.Background(), &rotation.Policy{
+				Kind: rotation.KindAWSIAM,
+				Settings: rotation.Settings{
+					UserName: "ci-deployer",
+					Region:   "eu-central-1",
+					Endpoint: server.URL,
+				},
+			}, current)
+
+			assert.Equal(t, tt.wantSecret, got)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, []string{"CreateAccessKey", "DeleteAccessKey"}, actions)
+		})
+	}
+}
@@ -0,0 +1,6 @@
+// Package rotator provides credential secret rotation in external systems for the AegisVaultKeeper server.
+//
+// This package changes the passwords of PostgreSQL roles, replaces AWS IAM access keys through the IAM
+// query API signed with AWS Signature Version 4, and hands generated passwords to webhooks applying them
+// to other systems. Every rotator shares the Rotate signature, so the rotation service treats them alike.
+package rotator
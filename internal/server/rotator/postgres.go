@@ -0,0 +1,117 @@
+package rotator
+
+import (
+	"context"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/crypto"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/rotation"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// SCRAM-SHA-256 verifier parameters matching the defaults of PostgreSQL.
+const (
+	// scramIterations is the PBKDF2 iteration count of the verifier.
+	scramIterations = 4096
+	// scramSaltSize is the size of the random verifier salt in bytes.
+	scramSaltSize = 16
+)
+
+// connValueEscaper escapes values quoted in PostgreSQL connection strings.
+var connValueEscaper = strings.NewReplacer(`\`, `\\`, `'`, `\'`)
+
+// postgresConnectTimeout limits how long connecting to the PostgreSQL server may take.
+const postgresConnectTimeout = 10 * time.Second
+
+// pgConn is the subset of a PostgreSQL connection used for password changes.
+type pgConn interface {
+	// Exec executes the SQL statement.
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	// Close closes the connection.
+	Close(ctx context.Context) error
+}
+
+// PostgresRotator changes the password of a PostgreSQL role. It connects as the role with its current
+// password and sends a SCRAM-SHA-256 verifier, so the new password never reaches the server or its logs.
+type PostgresRotator struct {
+	// connect opens a connection with the provided configuration.
+	connect func(ctx context.Context, cfg *pgx.ConnConfig) (pgConn, error)
+}
+
+// NewPostgresRotator creates a new PostgresRotator.
+func NewPostgresRotator() *PostgresRotator {
+	return &PostgresRotator{
+		connect: func(ctx context.Context, cfg *pgx.ConnConfig) (pgConn, error) {
+			return pgx.ConnectConfig(ctx, cfg)
+		},
+	}
+}
+
+// Rotate sets a generated password for the role named by the credential login.
+func (r *PostgresRotator) Rotate(
+	ctx context.Context,
+	p *rotation.Policy,
+	current rotation.Secret,
+) (rotation.Secret, error) {
+	cfg, err := pgx.ParseConfig(fmt.Sprintf("host='%s' port=%d dbname='%s' sslmode=%s",
+		connValueEscaper.Replace(p.Settings.Host), p.Settings.Port,
+		connValueEscaper.Replace(p.Settings.Database), p.Settings.SSLMode))
+	if err != nil {
+		return rotation.Secret{}, fmt.Errorf("invalid PostgreSQL connection settings: %w", err)
+	}
+	cfg.User = current.Login
+	cfg.Password = current.Password
+	cfg.ConnectTimeout = postgresConnectTimeout
+
+	conn, err := r.connect(ctx, cfg)
+	if err != nil {
+		return rotation.Secret{}, fmt.Errorf("failed to connect to PostgreSQL: %w", redactPgError(err))
+	}
+	defer func() { _ = conn.Close(context.WithoutCancel(ctx)) }()
+
+	password := rand.Text()
+	verifier, err := scramVerifier(password)
+	if err != nil {
+		return rotation.Secret{}, err
+	}
+	statement := "ALTER ROLE " + pgx.Identifier{current.Login}.Sanitize() + " PASSWORD '" + verifier + "'"
+	if _, err := conn.Exec(ctx, statement); err != nil {
+		return rotation.Secret{}, fmt.Errorf("failed to change PostgreSQL password: %w", redactPgError(err))
+	}
+	return rotation.Secret{Login: current.Login, Password: password}, nil
+}
+
+// scramVerifier builds the SCRAM-SHA-256 verifier PostgreSQL stores for the password.
+func scramVerifier(password string) (string, error) {
+	salt := make([]byte, scramSaltSize)
+	_, _ = rand.Read(salt)
+	salted, err := pbkdf2.Key(sha256.New, password, salt, scramIterations, sha256.Size)
+	if err != nil {
+		return "", fmt.Errorf("failed to derive SCRAM key: %w", err)
+	}
+	clientKey := crypto.SignHMACSHA256(salted, []byte("Client Key"))
+	storedKey := sha256.Sum256(clientKey)
+	serverKey := crypto.SignHMACSHA256(salted, []byte("Server Key"))
+
+	enc := base64.StdEncoding
+	return fmt.Sprintf("SCRAM-SHA-256$%d:%s$%s:%s", scramIterations,
+		enc.EncodeToString(salt), enc.EncodeToString(storedKey[:]), enc.EncodeToString(serverKey)), nil
+}
+
+// redactPgError keeps only the SQLSTATE and message of server errors, dropping the statement details
+// that may echo the sent verifier.
+func redactPgError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return fmt.Errorf("%s (SQLSTATE %s)", pgErr.Message, pgErr.Code)
+	}
+	return err
+}
@@ -0,0 +1,114 @@
+package rotator
+
+import (
+	"context"
+	"crypto/pbkdf2"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/crypto"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/rotation"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePgConn records the statements executed on it.
+type fakePgConn struct {
+	execErr    error
+	statements []string
+	closed     bool
+}
+
+func (c *fakePgConn) Exec(_ context.Context, sql string, _ ...any) (pgconn.CommandTag, error) {
+	c.statements = append(c.statements, sql)
+	return pgconn.CommandTag{}, c.execErr
+}
+
+func (c *fakePgConn) Close(context.Context) error {
+	c.closed = true
+	return nil
+}
+
+// alterRolePattern captures the role and verifier of the sent password change.
+var alterRolePattern = regexp.MustCompile(
+	`^ALTER ROLE "(.+)" PASSWORD 'SCRAM-SHA-256\$4096:([^$]+)\$([^:]+):([^']+)'$`,
+)
+
+func TestPostgresRotator_Rotate(t *testing.T) {
+	t.Parallel()
+
+	policy := &rotation.Policy{
+		Kind:     rotation.KindPostgres,
+		Settings: rotation.Settings{Host: "db.internal", Port: 6432, Database: "o'app", SSLMode: "disable"},
+	}
+	current := rotation.Secret{Login: `app"user`, Password: "old-password"}
+
+	tests := []struct {
+		connectErr error
+		execErr    error
+		name       string
+		wantErr    string
+	}{
+		{name: "password changed"},
+		{name: "connection refused", connectErr: errors.New("dial tcp: connection refused"), wantErr: "connect"},
+		{
+			name:    "permission denied",
+			execErr: &pgconn.PgError{Code: "42501", Message: "permission denied", Detail: "PASSWORD 'SCRAM'"},
+			wantErr: "permission denied (SQLSTATE 42501)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			conn := &fakePgConn{execErr: tt.execErr}
+			r := NewPostgresRotator()
+			r.connect = func(_ context.Context, cfg *pgx.ConnConfig) (pgConn, error) {
+				assert.Equal(t, "db.internal", cfg.Host)
+				assert.Equal(t, uint16(6432), cfg.Port)
+				assert.Equal(t, "o'app", cfg.Database)
+				assert.Equal(t, current.Login, cfg.User)
+				assert.Equal(t, current.Password, cfg.Password)
+				if tt.connectErr != nil {
+					return nil, tt.connectErr
+				}
+				return conn, nil
+			}
+
+			got, err := r.Rotate(context.Background(), policy, current)
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				assert.NotContains(t, err.Error(), "SCRAM")
+				assert.Empty(t, got.Password)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, conn.closed)
+			assert.Equal(t, current.Login, got.Login)
+			assert.Len(t, got.Password, 26)
+
+			require.Len(t, conn.statements, 1)
+			m := alterRolePattern.FindStringSubmatch(conn.statements[0])
+			require.NotNil(t, m, conn.statements[0])
+			assert.Equal(t, `app""user`, m[1])
+			assert.NotContains(t, conn.statements[0], got.Password)
+
+			salt, err := base64.StdEncoding.DecodeString(m[2])
+			require.NoError(t, err)
+			salted, err := pbkdf2.Key(sha256.New, got.Password, salt, 4096, sha256.Size)
+			require.NoError(t, err)
+			storedKey := sha256.Sum256(crypto.SignHMACSHA256(salted, []byte("Client Key")))
+			assert.Equal(t, base64.StdEncoding.EncodeToString(storedKey[:]), m[3])
+			serverKey := crypto.SignHMACSHA256(salted, []byte("Server Key"))
+			assert.Equal(t, base64.StdEncoding.EncodeToString(serverKey), m[4])
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package rotator
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/crypto"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/rotation"
+	"github.com/google/uuid"
+)
+
+// SignatureHeader carries the HMAC-SHA256 signature of webhook requests, keyed with the current password.
+const SignatureHeader = "X-Aegis-Signature"
+
+// WebhookRotator hands a generated password to a webhook that applies it to the target system.
+// Requests are signed with the current password, so the webhook can verify them before applying
+// the new one; any 2xx response confirms the change.
+type WebhookRotator struct {
+	// client performs the HTTP requests.
+	client *http.Client
+}
+
+// NewWebhookRotator creates a new WebhookRotator.
+func NewWebhookRotator(client *http.Client) *WebhookRotator {
+	return &WebhookRotator{client: client}
+}
+
+// webhookPayload is the JSON body posted to rotation webhooks.
+type webhookPayload struct {
+	// Login contains the credential login.
+	Login string `json:"login"`
+	// Password contains the new password to apply.
+	Password string `json:"password"`
+	// CredentialID identifies the rotated credential.
+	CredentialID uuid.UUID `json:"credential_id"`
+}
+
+// Rotate posts a generated password for the credential login to the webhook URL.
+func (r *WebhookRotator) Rotate(
+	ctx context.Context,
+	p *rotation.Policy,
+	current rotation.Secret,
+) (rotation.Secret, error) {
+	secret := rotation.Secret{Login: current.Login, Password: rand.Text()}
+	body, err := json.Marshal(webhookPayload{
+		CredentialID: p.CredentialID,
+		Login:        secret.Login,
+		Password:     secret.Password,
+	})
+	if err != nil {
+		return rotation.Secret{}, fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.Settings.URL, bytes.NewReader(body))
+	if err != nil {
+		return rotation.Secret{}, errors.New("failed to build webhook request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	mac := crypto.SignHMACSHA256([]byte(current.Password), body)
+	req.Header.Set(SignatureHeader, "sha256="+hex.EncodeToString(mac))
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return rotation.Secret{}, fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseSize))
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return rotation.Secret{}, fmt.Errorf("webhook rejected the rotation: unexpected status %d", resp.StatusCode)
+	}
+	return secret, nil
+}
@@ -0,0 +1,76 @@
+package rotator
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/crypto"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/rotation"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookRotator_Rotate(t *testing.T) {
+	t.Parallel()
+
+	credentialID := uuid.New()
+	current := rotation.Secret{Login: "svc-backup", Password: "old-password"}
+
+	tests := []struct {
+		name    string
+		status  int
+		wantErr bool
+	}{
+		{name: "applied", status: http.StatusNoContent},
+		{name: "rejected", status: http.StatusUnauthorized, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var got webhookPayload
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, err := io.ReadAll(r.Body)
+				assert.NoError(t, err)
+				assert.Equal(t, "/rotate", r.URL.Path)
+				assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+				signature, ok := strings.CutPrefix(r.Header.Get(SignatureHeader), "sha256=")
+				assert.True(t, ok)
+				mac, err := hex.DecodeString(signature)
+				assert.NoError(t, err)
+				assert.True(t, crypto.VerifyHMACSHA256([]byte(current.Password), body, mac))
+
+				assert.NoError(t, json.Unmarshal(body, &got))
+				w.WriteHeader(tt.status)
+			}))
+			t.Cleanup(server.Close)
+
+			secret, err := NewWebhookRotator(server.Client()).Rotate(context.Background(), &rotation.Policy{
+				CredentialID: credentialID,
+				Kind:         rotation.KindWebhook,
+				Settings:     rotation.Settings{URL: server.URL + "/rotate"},
+			}, current)
+
+			assert.Equal(t, credentialID, got.CredentialID)
+			assert.Equal(t, current.Login, got.Login)
+			assert.NotEqual(t, current.Password, got.Password)
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "unexpected status 401")
+				assert.Empty(t, secret.Password)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, rotation.Secret{Login: current.Login, Password: got.Password}, secret)
+		})
+	}
+}
@@ -0,0 +1,5 @@
+// Package scheduler provides periodic background jobs for the AegisVaultKeeper server.
+//
+// This package runs maintenance tasks such as integrity verification on a fixed interval
+// and ties their lifetime to the application lifecycle.
+package scheduler
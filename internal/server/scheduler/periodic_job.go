@@ -0,0 +1,100 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Task is a unit of periodic background work.
+type Task func(ctx context.Context) error
+
+// PeriodicJob runs a task on a fixed interval until it is stopped.
+type PeriodicJob struct {
+	// logger records task failures.
+	logger *zap.SugaredLogger
+	// task is the work executed on every tick.
+	task Task
+	// cancel stops the running job loop.
+	cancel context.CancelFunc
+	// done is closed when the job loop exits.
+	done chan struct{}
+	// name identifies the job in logs.
+	name string
+	// interval specifies the delay between task runs; non-positive values disable the job.
+	interval time.Duration
+}
+
+// NewPeriodicJob creates a new PeriodicJob running the task every interval.
+func NewPeriodicJob(logger *zap.SugaredLogger, name string, interval time.Duration, task Task) *PeriodicJob {
+	return &PeriodicJob{
+		logger:   logger,
+		name:     name,
+		interval: interval,
+		task:     task,
+	}
+}
+
+// Name returns the job name.
+func (j *PeriodicJob) Name() string {
+	return j.name
+}
+
+// Start launches the job loop in the background. Jobs with a non-positive interval are not started.
+func (j *PeriodicJob) Start(_ context.Context) error {
+	if j.interval <= 0 {
+		j.logger.Infof("Periodic job %q is disabled", j.name)
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	j.cancel = cancel
+	j.done = make(chan struct{})
+
+	go j.loop(ctx)
+	j.logger.Infof("Periodic job %q started with interval %s", j.name, j.interval)
+	return nil
+}
+
+// Stop cancels the job loop and waits for the running task to finish or the context to expire.
+func (j *PeriodicJob) Stop(ctx context.Context) error {
+	if j.cancel == nil {
+		return nil
+	}
+	j.cancel()
+
+	select {
+	case <-j.done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("periodic job %q stop interrupted: %w", j.name, ctx.Err())
+	}
+}
+
+// RunOnce executes the task a single time, logging and returning any failure.
+func (j *PeriodicJob) RunOnce(ctx context.Context) error {
+	if err := j.task(ctx); err != nil {
+		j.logger.Errorf("Periodic job %q failed: %v", j.name, err)
+		return fmt.Errorf("periodic job %q failed: %w", j.name, err)
+	}
+	return nil
+}
+
+// loop runs the task on every tick until the context is cancelled.
+func (j *PeriodicJob) loop(ctx context.Context) {
+	defer close(j.done)
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_ = j.RunOnce(ctx)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestPeriodicJob_StartStop(t *testing.T) {
+	t.Parallel()
+
+	var runs atomic.Int32
+	job := NewPeriodicJob(zap.NewNop().Sugar(), "test", 5*time.Millisecond, func(ctx context.Context) error {
+		runs.Add(1)
+		return nil
+	})
+
+	require.NoError(t, job.Start(context.Background()))
+	assert.Eventually(t, func() bool { return runs.Load() >= 2 }, time.Second, 5*time.Millisecond)
+	require.NoError(t, job.Stop(context.Background()))
+
+	stopped := runs.Load()
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, stopped, runs.Load(), "job should not run after stop")
+}
+
+func TestPeriodicJob_Disabled(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		interval time.Duration
+	}{
+		{name: "zero_interval", interval: 0},
+		{name: "negative_interval", interval: -time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var runs atomic.Int32
+			job := NewPeriodicJob(zap.NewNop().Sugar(), "disabled", tt.interval, func(ctx context.Context) error {
+				runs.Add(1)
+				return nil
+			})
+
+			require.NoError(t, job.Start(context.Background()))
+			require.NoError(t, job.Stop(context.Background()))
+			assert.Zero(t, runs.Load())
+		})
+	}
+}
+
+func TestPeriodicJob_RunOnce(t *testing.T) {
+	t.Parallel()
+
+	taskErr := errors.New("task failed")
+
+	tests := []struct {
+		taskErr error
+		name    string
+	}{
+		{name: "success", taskErr: nil},
+		{name: "failure", taskErr: taskErr},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			job := NewPeriodicJob(zap.NewNop().Sugar(), "once", time.Hour, func(ctx context.Context) error {
+				return tt.taskErr
+			})
+
+			err := job.RunOnce(context.Background())
+			if tt.taskErr != nil {
+				require.ErrorIs(t, err, tt.taskErr)
+				assert.Contains(t, err.Error(), `"once"`)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "once", job.Name())
+		})
+	}
+}
@@ -0,0 +1,6 @@
+// Package securebytes provides handling of secret byte buffers for the AegisVaultKeeper server.
+//
+// Key material and decrypted item fields are kept in byte slices rather than strings, so they can be
+// zeroed as soon as they are no longer needed. Key material can additionally be locked in memory to keep
+// it out of swap, which reduces the exposure of secrets in core dumps and swap files.
+package securebytes
@@ -0,0 +1,25 @@
+//go:build !unix
+
+package securebytes
+
+import "errors"
+
+// errLockUnsupported indicates that the platform cannot pin memory.
+var errLockUnsupported = errors.New("memory locking is not supported on this platform")
+
+// Lock pins the buffer in physical memory, so it is never written to swap.
+// It always fails on platforms without mlock.
+func Lock(b []byte) error {
+	if len(b) == 0 {
+		return nil
+	}
+	return errLockUnsupported
+}
+
+// Unlock releases a buffer pinned with Lock.
+func Unlock(b []byte) error {
+	if len(b) == 0 {
+		return nil
+	}
+	return errLockUnsupported
+}
@@ -0,0 +1,32 @@
+//go:build unix
+
+package securebytes
+
+import (
+	"fmt"
+
+	"golang.org/x/sys/unix"
+)
+
+// Lock pins the buffer in physical memory, so it is never written to swap.
+// Locking may fail when the process exceeds its RLIMIT_MEMLOCK limit.
+func Lock(b []byte) error {
+	if len(b) == 0 {
+		return nil
+	}
+	if err := unix.Mlock(b); err != nil {
+		return fmt.Errorf("mlock: %w", err)
+	}
+	return nil
+}
+
+// Unlock releases a buffer pinned with Lock.
+func Unlock(b []byte) error {
+	if len(b) == 0 {
+		return nil
+	}
+	if err := unix.Munlock(b); err != nil {
+		return fmt.Errorf("munlock: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package securebytes
+
+// redacted replaces the contents of Bytes whenever they are formatted.
+const redacted = "[REDACTED]"
+
+// Bytes holds secret data. Formatting never reveals the contents, so secrets do not end up in logs.
+type Bytes []byte
+
+// Wipe zeroes the contents in place.
+func (b Bytes) Wipe() {
+	clear(b)
+}
+
+// String returns a placeholder instead of the contents.
+func (b Bytes) String() string {
+	return redacted
+}
+
+// GoString returns a placeholder instead of the contents.
+func (b Bytes) GoString() string {
+	return redacted
+}
+
+// Wiper is implemented by values holding secrets that can be zeroed in place.
+type Wiper interface {
+	// Wipe zeroes the secrets held by the value.
+	Wipe()
+}
+
+// Wipe zeroes every buffer in place. Nil buffers are skipped.
+func Wipe(bufs ...[]byte) {
+	for _, b := range bufs {
+		clear(b)
+	}
+}
+
+// WipeAll wipes every item of the slice.
+func WipeAll[T Wiper](items []T) {
+	for _, item := range items {
+		item.Wipe()
+	}
+}
@@ -0,0 +1,115 @@
+package securebytes
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// wipeCounter implements Wiper, counting its wipes.
+type wipeCounter struct {
+	wipes int
+}
+
+func (w *wipeCounter) Wipe() {
+	w.wipes++
+}
+
+func TestWipe(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		bufs [][]byte
+	}{
+		{name: "single buffer", bufs: [][]byte{[]byte("secret")}},
+		{name: "several buffers", bufs: [][]byte{[]byte("secret"), []byte("another secret")}},
+		{name: "nil and empty buffers", bufs: [][]byte{nil, {}, []byte("secret")}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			Wipe(tt.bufs...)
+
+			for _, b := range tt.bufs {
+				assert.Empty(t, bytes.Trim(b, "\x00"))
+			}
+		})
+	}
+}
+
+func TestBytes_Wipe(t *testing.T) {
+	t.Parallel()
+
+	b := Bytes("secret")
+	// view shares the memory of b, like a caller holding the buffer.
+	view := []byte(b)
+
+	b.Wipe()
+
+	assert.Equal(t, make([]byte, 6), view)
+}
+
+func TestBytes_Format(t *testing.T) {
+	t.Parallel()
+
+	b := Bytes("secret")
+
+	tests := []struct {
+		name   string
+		format string
+	}{
+		{name: "value", format: "%v"},
+		{name: "string", format: "%s"},
+		{name: "hex", format: "%x"},
+		{name: "go syntax", format: "%#v"},
+		{name: "struct field", format: "%+v"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := fmt.Sprintf(tt.format, struct{ Key Bytes }{Key: b})
+
+			assert.NotContains(t, got, "secret")
+			assert.NotContains(t, got, fmt.Sprintf("%x", "secret"))
+		})
+	}
+}
+
+func TestBytes_String(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "{Key:[REDACTED]}", fmt.Sprintf("%+v", struct{ Key Bytes }{Key: Bytes("secret")}))
+}
+
+func TestWipeAll(t *testing.T) {
+	t.Parallel()
+
+	items := []*wipeCounter{{}, {}, {}}
+
+	WipeAll(items)
+
+	for _, item := range items {
+		assert.Equal(t, 1, item.wipes)
+	}
+}
+
+func TestLockUnlock(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, Lock(nil))
+	require.NoError(t, Unlock(nil))
+
+	b := make([]byte, 32)
+	if err := Lock(b); err != nil {
+		t.Skipf("memory locking unavailable: %v", err)
+	}
+	require.NoError(t, Unlock(b))
+}
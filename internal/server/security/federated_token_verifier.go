@@ -0,0 +1,114 @@
+package security
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// DefaultFederationUserClaim is the claim holding the user ID in identity provider tokens when none is configured.
+const DefaultFederationUserClaim = "sub"
+
+// jwksFetchTimeout bounds fetching the key set while a token is verified.
+const jwksFetchTimeout = 10 * time.Second
+
+// federationSigningMethods lists the asymmetric signing methods accepted for identity provider tokens.
+var federationSigningMethods = []string{
+	"RS256", "RS384", "RS512",
+	"PS256", "PS384", "PS512",
+	"ES256", "ES384", "ES512",
+	"EdDSA",
+}
+
+// FederationPolicy defines the claims required of access tokens issued by a central identity provider.
+type FederationPolicy struct {
+	// Issuer names the identity provider tokens must be issued by.
+	Issuer string
+	// UserClaim names the claim holding the AegisVaultKeeper user ID (empty uses DefaultFederationUserClaim).
+	UserClaim string
+	// Audiences lists the audiences accepted in tokens; a token must name at least one of them.
+	Audiences []string
+	// Leeway tolerates clock skew between services when checking the exp, nbf and iat claims.
+	Leeway time.Duration
+	// Allowed reports whether identity provider tokens are accepted at the moment, such as while single sign-on
+	// is licensed; nil always accepts them.
+	Allowed func() bool
+}
+
+// FederatedTokenVerifier verifies access tokens issued by a central identity provider against the
+// signing keys it publishes, so AegisVaultKeeper can sit behind an existing single sign-on.
+type FederatedTokenVerifier struct {
+	// keys provides the signing keys of the identity provider.
+	keys *JWKSKeySet
+	// issuer names the identity provider tokens must be issued by.
+	issuer string
+	// userClaim names the claim holding the user ID.
+	userClaim string
+	// audiences lists the audiences accepted in tokens.
+	audiences []string
+	// allowed reports whether identity provider tokens are accepted at the moment; nil always accepts them.
+	allowed func() bool
+	// leeway tolerates clock skew when checking time-based claims.
+	leeway time.Duration
+}
+
+// NewFederatedTokenVerifier creates a new FederatedTokenVerifier. The issuer and at least one audience are
+// required, so tokens the identity provider issued for other services are not accepted.
+func NewFederatedTokenVerifier(keys *JWKSKeySet, policy FederationPolicy) (*FederatedTokenVerifier, error) {
+	if policy.Issuer == "" {
+		return nil, errors.New("JWT error: identity provider issuer is required")
+	}
+	if len(policy.Audiences) == 0 {
+		return nil, errors.New("JWT error: at least one accepted audience is required for identity provider tokens")
+	}
+	userClaim := policy.UserClaim
+	if userClaim == "" {
+		userClaim = DefaultFederationUserClaim
+	}
+	return &FederatedTokenVerifier{
+		keys:      keys,
+		issuer:    policy.Issuer,
+		userClaim: userClaim,
+		audiences: policy.Audiences,
+		allowed:   policy.Allowed,
+		leeway:    policy.Leeway,
+	}, nil
+}
+
+// VerifyAccessToken verifies an identity provider token and returns the user ID held in the user claim
+// and the moment the token was issued at, zero when the token does not tell.
+func (v *FederatedTokenVerifier) VerifyAccessToken(tokenString string) (uuid.UUID, time.Time, error) {
+	if v.allowed != nil && !v.allowed() {
+		return uuid.Nil, time.Time{}, errors.New("JWT error: identity provider tokens are not accepted")
+	}
+	claims := jwt.MapClaims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		ctx, cancel := context.WithTimeout(context.Background(), jwksFetchTimeout)
+		defer cancel()
+		return v.keys.Key(ctx, kid)
+	},
+		jwt.WithValidMethods(federationSigningMethods),
+		jwt.WithIssuer(v.issuer),
+		jwt.WithAudience(v.audiences...),
+		jwt.WithLeeway(v.leeway),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return uuid.Nil, time.Time{}, fmt.Errorf("JWT error: invalid identity provider token: %w", err)
+	}
+	if !token.Valid {
+		return uuid.Nil, time.Time{}, errors.New("JWT error: identity provider token is not valid or has expired")
+	}
+
+	raw, _ := claims[v.userClaim].(string)
+	userID, err := uuid.Parse(raw)
+	if err != nil {
+		return uuid.Nil, time.Time{}, fmt.Errorf("JWT error: claim %q does not hold a user ID", v.userClaim)
+	}
+	return userID, issuedAt(claims), nil
+}
@@ -0,0 +1,258 @@
+package security
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testIdPIssuer is the issuer of identity provider tokens in tests.
+const testIdPIssuer = "https://idp.example.com"
+
+// signIdPToken signs identity provider claims with the key, naming the key ID in the header.
+func signIdPToken(t *testing.T, key *ecdsa.PrivateKey, kid string, claims jwt.MapClaims) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+	return signed
+}
+
+func TestNewFederatedTokenVerifier(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		policy        FederationPolicy
+		wantUserClaim string
+		wantErr       bool
+	}{
+		{
+			name:          "default user claim",
+			policy:        FederationPolicy{Issuer: testIdPIssuer, Audiences: []string{"vault"}},
+			wantUserClaim: DefaultFederationUserClaim,
+		},
+		{
+			name:          "custom user claim",
+			policy:        FederationPolicy{Issuer: testIdPIssuer, Audiences: []string{"vault"}, UserClaim: "vault_uid"},
+			wantUserClaim: "vault_uid",
+		},
+		{
+			name:    "missing issuer",
+			policy:  FederationPolicy{Audiences: []string{"vault"}},
+			wantErr: true,
+		},
+		{
+			name:    "missing audiences",
+			policy:  FederationPolicy{Issuer: testIdPIssuer},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := NewFederatedTokenVerifier(nil, tt.policy)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Nil(t, got)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantUserClaim, got.userClaim)
+		})
+	}
+}
+
+func TestFederatedTokenVerifier_VerifyAccessToken(t *testing.T) {
+	t.Parallel()
+
+	idpKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	server := newJWKSServer(t, ecJWK("idp-1", &idpKey.PublicKey))
+	verifier, err := NewFederatedTokenVerifier(
+		NewJWKSKeySet(server.Client(), server.URL, time.Hour),
+		FederationPolicy{Issuer: testIdPIssuer, Audiences: []string{"vault", "cli"}},
+	)
+	require.NoError(t, err)
+
+	userID := uuid.New()
+	now := time.Now()
+	// claims builds valid identity provider claims with the overrides applied.
+	claims := func(overrides jwt.MapClaims) jwt.MapClaims {
+		c := jwt.MapClaims{
+			"iss": testIdPIssuer,
+			"aud": []string{"cli"},
+			"sub": userID.String(),
+			"iat": now.Unix(),
+			"nbf": now.Unix(),
+			"exp": now.Add(time.Hour).Unix(),
+		}
+		for k, v := range overrides {
+			if v == nil {
+				delete(c, k)
+				continue
+			}
+			c[k] = v
+		}
+		return c
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr bool
+	}{
+		{
+			name:  "valid token",
+			token: signIdPToken(t, idpKey, "idp-1", claims(nil)),
+		},
+		{
+			name:    "other issuer",
+			token:   signIdPToken(t, idpKey, "idp-1", claims(jwt.MapClaims{"iss": "https://evil.example.com"})),
+			wantErr: true,
+		},
+		{
+			name:    "other audience",
+			token:   signIdPToken(t, idpKey, "idp-1", claims(jwt.MapClaims{"aud": "billing"})),
+			wantErr: true,
+		},
+		{
+			name:    "expired",
+			token:   signIdPToken(t, idpKey, "idp-1", claims(jwt.MapClaims{"exp": now.Add(-time.Minute).Unix()})),
+			wantErr: true,
+		},
+		{
+			name:    "not yet valid",
+			token:   signIdPToken(t, idpKey, "idp-1", claims(jwt.MapClaims{"nbf": now.Add(time.Hour).Unix()})),
+			wantErr: true,
+		},
+		{
+			name:    "no expiry",
+			token:   signIdPToken(t, idpKey, "idp-1", claims(jwt.MapClaims{"exp": nil})),
+			wantErr: true,
+		},
+		{
+			name:    "subject is not a user ID",
+			token:   signIdPToken(t, idpKey, "idp-1", claims(jwt.MapClaims{"sub": "alice@example.com"})),
+			wantErr: true,
+		},
+		{
+			name:    "signed with unknown key",
+			token:   signIdPToken(t, otherKey, "idp-1", claims(nil)),
+			wantErr: true,
+		},
+		{
+			name:    "unknown key ID",
+			token:   signIdPToken(t, idpKey, "idp-2", claims(nil)),
+			wantErr: true,
+		},
+		{
+			name: "HMAC signed",
+			token: func() string {
+				token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims(nil)).
+					SignedString(make([]byte, MinSecretKeyLength))
+				require.NoError(t, err)
+				return token
+			}(),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, _, err := verifier.VerifyAccessToken(tt.token)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Equal(t, uuid.Nil, got)
+				assert.Contains(t, err.Error(), "JWT error")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, userID, got)
+		})
+	}
+}
+
+func TestTokenGenerateValidator_Federation(t *testing.T) {
+	t.Parallel()
+
+	idpKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	server := newJWKSServer(t, ecJWK("idp-1", &idpKey.PublicKey))
+	federation, err := NewFederatedTokenVerifier(
+		NewJWKSKeySet(server.Client(), server.URL, time.Hour),
+		FederationPolicy{Issuer: testIdPIssuer, Audiences: []string{"vault"}},
+	)
+	require.NoError(t, err)
+
+	policy := TokenClaimsPolicy{Audiences: []string{"vault"}}
+	secretKey := make([]byte, MinSecretKeyLength)
+	federated, err := NewTokenGenerateValidator(secretKey, time.Hour, policy, federation)
+	require.NoError(t, err)
+	local, err := NewTokenGenerateValidator(secretKey, time.Hour, policy, nil)
+	require.NoError(t, err)
+	unlicensedFederation, err := NewFederatedTokenVerifier(
+		NewJWKSKeySet(server.Client(), server.URL, time.Hour),
+		FederationPolicy{Issuer: testIdPIssuer, Audiences: []string{"vault"}, Allowed: func() bool { return false }},
+	)
+	require.NoError(t, err)
+	unlicensed, err := NewTokenGenerateValidator(secretKey, time.Hour, policy, unlicensedFederation)
+	require.NoError(t, err)
+
+	userID := uuid.New()
+	idpToken := signIdPToken(t, idpKey, "idp-1", jwt.MapClaims{
+		"iss": testIdPIssuer,
+		"aud": "vault",
+		"sub": userID.String(),
+		"exp": time.Now().Add(time.Hour).Unix(),
+	})
+	ownToken, _, _, err := federated.GenerateAccessToken(userID)
+	require.NoError(t, err)
+
+	tests := []struct {
+		validator *TokenGenerateValidator
+		name      string
+		token     string
+		wantErr   bool
+	}{
+		{name: "own token with federation", validator: federated, token: ownToken},
+		{name: "identity provider token with federation", validator: federated, token: idpToken},
+		{name: "identity provider token without federation", validator: local, token: idpToken, wantErr: true},
+		{name: "own token with federation not allowed", validator: unlicensed, token: ownToken},
+		{name: "identity provider token with federation not allowed", validator: unlicensed, token: idpToken, wantErr: true},
+		{name: "malformed token with federation", validator: federated, token: "not-a-token", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, _, err := tt.validator.ValidateAccessToken(tt.token)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, userID, got)
+		})
+	}
+}
@@ -0,0 +1,231 @@
+package security
+
+import (
+	"context"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// maxJWKSSize limits the size of key set documents read from the identity provider.
+const maxJWKSSize = 1 << 20
+
+// jwksRefreshCooldown limits how often the key set is fetched again for tokens naming unknown keys,
+// so forged key IDs cannot flood the identity provider.
+const jwksRefreshCooldown = 30 * time.Second
+
+// errJWKSKeyNotFound indicates that the key set holds no key with the requested ID.
+var errJWKSKeyNotFound = errors.New("signing key not found in key set")
+
+// jsonWebKey holds the members of a JSON Web Key (RFC 7517) used for signature verification.
+type jsonWebKey struct {
+	// Kty names the key type (RSA, EC, OKP).
+	Kty string `json:"kty"`
+	// Kid identifies the key.
+	Kid string `json:"kid"`
+	// Use names the intended key use; keys for other uses than "sig" are skipped.
+	Use string `json:"use"`
+	// N contains the RSA modulus.
+	N string `json:"n"`
+	// E contains the RSA public exponent.
+	E string `json:"e"`
+	// Crv names the curve of EC and OKP keys.
+	Crv string `json:"crv"`
+	// X contains the x coordinate of EC keys or the public key of OKP keys.
+	X string `json:"x"`
+	// Y contains the y coordinate of EC keys.
+	Y string `json:"y"`
+}
+
+// JWKSKeySet provides the token signing keys of an identity provider, fetched from its JWKS endpoint
+// and cached for a configured time.
+type JWKSKeySet struct {
+	// fetchedAt is the moment keys were last fetched successfully.
+	fetchedAt time.Time
+	// attemptedAt is the moment keys were last requested from the endpoint.
+	attemptedAt time.Time
+	// client performs the HTTP requests.
+	client *http.Client
+	// now returns the current time used for cache expiry.
+	now func() time.Time
+	// keys maps key IDs to the cached public keys.
+	keys map[string]any
+	// url is the address of the JWKS endpoint.
+	url string
+	// ttl specifies how long fetched keys are used before they are fetched again.
+	ttl time.Duration
+	// mu guards the cache.
+	mu sync.Mutex
+}
+
+// NewJWKSKeySet creates a new JWKSKeySet fetching keys from the endpoint URL and caching them for ttl.
+func NewJWKSKeySet(client *http.Client, url string, ttl time.Duration) *JWKSKeySet {
+	return &JWKSKeySet{client: client, url: url, ttl: ttl, now: time.Now}
+}
+
+// Key returns the public key with the specified ID. The key set is fetched when the cache has expired,
+// or when the key is unknown and the set was not requested within the refresh cooldown, so rotated keys
+// are picked up. While the endpoint is unreachable, cached keys stay in use.
+// An empty ID selects the only key of a set holding a single key.
+func (s *JWKSKeySet) Key(ctx context.Context, kid string) (any, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	expired := s.keys == nil || now.Sub(s.fetchedAt) >= s.ttl
+	key, ok := s.lookup(kid)
+	if ok && !expired {
+		return key, nil
+	}
+
+	if expired || now.Sub(s.attemptedAt) >= jwksRefreshCooldown {
+		s.attemptedAt = now
+		if err := s.refresh(ctx, now); err != nil && !ok {
+			return nil, err
+		}
+		key, ok = s.lookup(kid)
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", errJWKSKeyNotFound, kid)
+	}
+	return key, nil
+}
+
+// lookup returns the cached key with the specified ID, or the only cached key for an empty ID.
+func (s *JWKSKeySet) lookup(kid string) (any, bool) {
+	if kid == "" && len(s.keys) == 1 {
+		for _, key := range s.keys {
+			return key, true
+		}
+	}
+	key, ok := s.keys[kid]
+	return key, ok
+}
+
+// refresh fetches the key set and replaces the cached keys. Keys of unsupported types are skipped.
+func (s *JWKSKeySet) refresh(ctx context.Context, now time.Time) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, http.NoBody)
+	if err != nil {
+		return fmt.Errorf("failed to build key set request: %w", err)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to fetch key set: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to fetch key set: unexpected status %d", resp.StatusCode)
+	}
+
+	var doc struct {
+		// Keys lists the keys of the set.
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxJWKSSize)).Decode(&doc); err != nil {
+		return fmt.Errorf("failed to decode key set: %w", err)
+	}
+
+	keys := make(map[string]any, len(doc.Keys))
+	for _, jwk := range doc.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := parseJSONWebKey(jwk)
+		if err != nil {
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+	if len(keys) == 0 {
+		return errors.New("key set contains no supported signing keys")
+	}
+
+	s.keys = keys
+	s.fetchedAt = now
+	return nil
+}
+
+// parseJSONWebKey converts an RSA, EC (P-256, P-384, P-521) or Ed25519 JSON Web Key into a public key.
+func parseJSONWebKey(jwk jsonWebKey) (any, error) {
+	switch jwk.Kty {
+	case "RSA":
+		n, err := decodeBigInt(jwk.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(jwk.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() < 3 || e.Int64() > 1<<31-1 {
+			return nil, errors.New("invalid RSA exponent")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		return parseECKey(jwk)
+	case "OKP":
+		if jwk.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported OKP curve %q", jwk.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid Ed25519 key")
+		}
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", jwk.Kty)
+	}
+}
+
+// parseECKey converts an EC JSON Web Key into an ECDSA public key, rejecting points off the curve.
+func parseECKey(jwk jsonWebKey) (*ecdsa.PublicKey, error) {
+	var (
+		curve elliptic.Curve
+		check ecdh.Curve
+	)
+	switch jwk.Crv {
+	case "P-256":
+		curve, check = elliptic.P256(), ecdh.P256()
+	case "P-384":
+		curve, check = elliptic.P384(), ecdh.P384()
+	case "P-521":
+		curve, check = elliptic.P521(), ecdh.P521()
+	default:
+		return nil, fmt.Errorf("unsupported EC curve %q", jwk.Crv)
+	}
+
+	size := (curve.Params().BitSize + 7) / 8
+	x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+	if err != nil || len(x) != size {
+		return nil, errors.New("invalid EC x coordinate")
+	}
+	y, err := base64.RawURLEncoding.DecodeString(jwk.Y)
+	if err != nil || len(y) != size {
+		return nil, errors.New("invalid EC y coordinate")
+	}
+	point := append(append([]byte{4}, x...), y...)
+	if _, err := check.NewPublicKey(point); err != nil {
+		return nil, fmt.Errorf("invalid EC point: %w", err)
+	}
+	return &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+}
+
+// decodeBigInt decodes a base64url-encoded unsigned big-endian integer.
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || len(b) == 0 {
+		return nil, errors.New("invalid base64url integer")
+	}
+	return new(big.Int).SetBytes(b), nil
+}
@@ -0,0 +1,208 @@
+package security
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// jwksServer serves a replaceable key set and counts the requests.
+type jwksServer struct {
+	*httptest.Server
+	keys     []jsonWebKey
+	requests atomic.Int32
+	status   int
+	mu       sync.Mutex
+}
+
+// newJWKSServer starts a JWKS endpoint serving the keys.
+func newJWKSServer(t *testing.T, keys ...jsonWebKey) *jwksServer {
+	t.Helper()
+
+	s := &jwksServer{keys: keys, status: http.StatusOK}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		s.requests.Add(1)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		w.WriteHeader(s.status)
+		_ = json.NewEncoder(w).Encode(map[string]any{"keys": s.keys})
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// set replaces the served keys and response status.
+func (s *jwksServer) set(status int, keys ...jsonWebKey) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.status, s.keys = status, keys
+}
+
+// rsaJWK encodes an RSA public key as a JSON Web Key.
+func rsaJWK(kid string, key *rsa.PublicKey) jsonWebKey {
+	return jsonWebKey{
+		Kty: "RSA",
+		Kid: kid,
+		Use: "sig",
+		N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}
+}
+
+// ecJWK encodes a P-256 public key as a JSON Web Key.
+func ecJWK(kid string, key *ecdsa.PublicKey) jsonWebKey {
+	x, y := make([]byte, 32), make([]byte, 32)
+	return jsonWebKey{
+		Kty: "EC",
+		Kid: kid,
+		Crv: "P-256",
+		X:   base64.RawURLEncoding.EncodeToString(key.X.FillBytes(x)),
+		Y:   base64.RawURLEncoding.EncodeToString(key.Y.FillBytes(y)),
+	}
+}
+
+func TestJWKSKeySet_Key(t *testing.T) {
+	t.Parallel()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	edKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	keys := []jsonWebKey{
+		rsaJWK("rsa", &rsaKey.PublicKey),
+		ecJWK("ec", &ecKey.PublicKey),
+		{Kty: "OKP", Kid: "ed", Crv: "Ed25519", X: base64.RawURLEncoding.EncodeToString(edKey)},
+		{Kty: "RSA", Kid: "enc", Use: "enc", N: "AQAB", E: "AQAB"},
+		{Kty: "oct", Kid: "hmac"},
+	}
+	server := newJWKSServer(t, keys...)
+	set := NewJWKSKeySet(server.Client(), server.URL, time.Hour)
+
+	tests := []struct {
+		want    any
+		name    string
+		kid     string
+		wantErr bool
+	}{
+		{name: "RSA key", kid: "rsa", want: &rsaKey.PublicKey},
+		{name: "EC key", kid: "ec", want: &ecKey.PublicKey},
+		{name: "Ed25519 key", kid: "ed", want: edKey},
+		{name: "encryption key skipped", kid: "enc", wantErr: true},
+		{name: "symmetric key skipped", kid: "hmac", wantErr: true},
+		{name: "unknown key", kid: "other", wantErr: true},
+		{name: "no key ID with several keys", kid: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := set.Key(context.Background(), tt.kid)
+
+			if tt.wantErr {
+				require.ErrorIs(t, err, errJWKSKeyNotFound)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestJWKSKeySet_Caching(t *testing.T) {
+	t.Parallel()
+
+	oldKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	newKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	server := newJWKSServer(t, ecJWK("old", &oldKey.PublicKey))
+	now := time.Now()
+	set := NewJWKSKeySet(server.Client(), server.URL, 10*time.Minute)
+	set.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	// The key set is fetched once and served from the cache.
+	for range 3 {
+		_, err = set.Key(ctx, "old")
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int32(1), server.requests.Load())
+
+	// A rotated key is picked up once the refresh cooldown has passed.
+	server.set(http.StatusOK, ecJWK("new", &newKey.PublicKey))
+	_, err = set.Key(ctx, "new")
+	require.ErrorIs(t, err, errJWKSKeyNotFound)
+	assert.Equal(t, int32(1), server.requests.Load())
+
+	now = now.Add(jwksRefreshCooldown)
+	got, err := set.Key(ctx, "new")
+	require.NoError(t, err)
+	assert.Equal(t, &newKey.PublicKey, got)
+	assert.Equal(t, int32(2), server.requests.Load())
+
+	// An empty key ID selects the only key of the set.
+	got, err = set.Key(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, &newKey.PublicKey, got)
+
+	// Cached keys stay in use while the endpoint fails after the cache expired.
+	server.set(http.StatusServiceUnavailable)
+	now = now.Add(time.Hour)
+	got, err = set.Key(ctx, "new")
+	require.NoError(t, err)
+	assert.Equal(t, &newKey.PublicKey, got)
+	assert.Equal(t, int32(3), server.requests.Load())
+}
+
+func TestJWKSKeySet_FetchErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		status int
+		keys   []jsonWebKey
+	}{
+		{name: "error status", status: http.StatusInternalServerError},
+		{name: "no supported keys", status: http.StatusOK, keys: []jsonWebKey{{Kty: "oct", Kid: "hmac"}}},
+		{name: "invalid EC point", status: http.StatusOK, keys: []jsonWebKey{{
+			Kty: "EC", Kid: "ec", Crv: "P-256",
+			X: base64.RawURLEncoding.EncodeToString(make([]byte, 32)),
+			Y: base64.RawURLEncoding.EncodeToString(make([]byte, 32)),
+		}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			server := newJWKSServer(t)
+			server.set(tt.status, tt.keys...)
+			set := NewJWKSKeySet(server.Client(), server.URL, time.Hour)
+
+			_, err := set.Key(context.Background(), "ec")
+
+			require.Error(t, err)
+			assert.NotErrorIs(t, err, errJWKSKeyNotFound)
+		})
+	}
+}
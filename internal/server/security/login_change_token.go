@@ -0,0 +1,128 @@
+package security
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// Token types of the confirmation tokens of a login change, one sent to the old and one to the new login.
+// Each type is signed with its own key derived from the secret key, so a confirmation token is never
+// accepted as an access token or as a token of the other type.
+const (
+	// TokenTypeLoginChangeOld is the type of the token confirming the change from the old login.
+	TokenTypeLoginChangeOld = "login_change_old"
+	// TokenTypeLoginChangeNew is the type of the token confirming the change from the new login.
+	TokenTypeLoginChangeNew = "login_change_new"
+)
+
+// LoginChangeClaims represents the JWT claims of a login change confirmation token.
+type LoginChangeClaims struct {
+	jwt.RegisteredClaims
+	// OldLogin contains the login the user changes from.
+	OldLogin string `json:"old_login"`
+	// NewLogin contains the login the user changes to.
+	NewLogin string `json:"new_login"`
+	// UserID contains the unique identifier of the user changing the login.
+	UserID uuid.UUID `json:"user_id"`
+}
+
+// GenerateLoginChangeTokens creates the pair of confirmation tokens of a login change valid for ttl.
+// Both tokens share the identifier of the change, so only tokens issued together confirm it.
+func (t *TokenGenerateValidator) GenerateLoginChangeTokens(
+	userID uuid.UUID,
+	oldLogin string,
+	newLogin string,
+	ttl time.Duration,
+) (oldToken string, newToken string, expiresAt time.Time, err error) {
+	issuedAt := time.Now()
+	expiresAt = issuedAt.Add(ttl)
+	changeID := uuid.NewString()
+
+	sign := func(tokenType string) (string, error) {
+		claims := &LoginChangeClaims{
+			UserID:   userID,
+			OldLogin: oldLogin,
+			NewLogin: newLogin,
+			RegisteredClaims: jwt.RegisteredClaims{
+				ID:        changeID,
+				IssuedAt:  jwt.NewNumericDate(issuedAt),
+				NotBefore: jwt.NewNumericDate(issuedAt),
+				ExpiresAt: jwt.NewNumericDate(expiresAt),
+				Issuer:    t.issuer,
+				Audience:  jwt.ClaimStrings{tokenType},
+			},
+		}
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(t.typeKey(tokenType))
+		if err != nil {
+			return "", fmt.Errorf("JWT error: failed to sign %s token: %w", tokenType, err)
+		}
+		return token, nil
+	}
+
+	if oldToken, err = sign(TokenTypeLoginChangeOld); err != nil {
+		return "", "", time.Time{}, err
+	}
+	if newToken, err = sign(TokenTypeLoginChangeNew); err != nil {
+		return "", "", time.Time{}, err
+	}
+	return oldToken, newToken, expiresAt, nil
+}
+
+// ValidateLoginChangeTokens validates the pair of confirmation tokens of a login change and returns
+// the user changing the login, the old and the new login. The tokens must be of their types, unexpired
+// and issued together.
+func (t *TokenGenerateValidator) ValidateLoginChangeTokens(
+	oldToken string,
+	newToken string,
+) (userID uuid.UUID, oldLogin string, newLogin string, err error) {
+	oldClaims, err := t.parseLoginChangeToken(TokenTypeLoginChangeOld, oldToken)
+	if err != nil {
+		return uuid.Nil, "", "", err
+	}
+	newClaims, err := t.parseLoginChangeToken(TokenTypeLoginChangeNew, newToken)
+	if err != nil {
+		return uuid.Nil, "", "", err
+	}
+	if oldClaims.ID == "" || oldClaims.ID != newClaims.ID || oldClaims.UserID != newClaims.UserID ||
+		oldClaims.OldLogin != newClaims.OldLogin || oldClaims.NewLogin != newClaims.NewLogin {
+		return uuid.Nil, "", "", errors.New("JWT error: login change tokens were not issued together")
+	}
+	return oldClaims.UserID, oldClaims.OldLogin, oldClaims.NewLogin, nil
+}
+
+// parseLoginChangeToken validates a login change confirmation token of the specified type.
+func (t *TokenGenerateValidator) parseLoginChangeToken(tokenType, tokenString string) (*LoginChangeClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &LoginChangeClaims{}, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("JWT error: unexpected signing method: %v", token.Header["alg"])
+		}
+		return t.typeKey(tokenType), nil
+	},
+		jwt.WithIssuer(t.issuer),
+		jwt.WithAudience(tokenType),
+		jwt.WithLeeway(t.leeway),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("JWT error: invalid %s token: %w", tokenType, err)
+	}
+
+	claims, ok := token.Claims.(*LoginChangeClaims)
+	if !ok || !token.Valid || claims == nil {
+		return nil, fmt.Errorf("JWT error: %s token is not valid or has expired", tokenType)
+	}
+	return claims, nil
+}
+
+// typeKey derives the key signing the tokens of the specified type from the secret key.
+func (t *TokenGenerateValidator) typeKey(tokenType string) []byte {
+	mac := hmac.New(sha256.New, t.secretKey)
+	mac.Write([]byte(tokenType))
+	return mac.Sum(nil)
+}
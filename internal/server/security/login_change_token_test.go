@@ -0,0 +1,77 @@
+package security
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenGenerateValidator_LoginChangeTokens(t *testing.T) {
+	t.Parallel()
+
+	tgv, err := NewTokenGenerateValidator(make([]byte, MinSecretKeyLength), time.Hour, TokenClaimsPolicy{}, nil)
+	require.NoError(t, err)
+
+	userID := uuid.New()
+	oldToken, newToken, expiresAt, err := tgv.GenerateLoginChangeTokens(userID, "alice", "alice@example.com", time.Hour)
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), expiresAt, time.Minute)
+
+	otherOld, otherNew, _, err := tgv.GenerateLoginChangeTokens(userID, "alice", "alice@example.com", time.Hour)
+	require.NoError(t, err)
+	_, expiredNew, _, err := tgv.GenerateLoginChangeTokens(userID, "alice", "alice@example.com", -time.Hour)
+	require.NoError(t, err)
+	accessToken, _, _, err := tgv.GenerateAccessToken(userID)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		oldToken string
+		newToken string
+		wantErr  bool
+	}{
+		{name: "tokens issued together", oldToken: oldToken, newToken: newToken},
+		{name: "swapped tokens", oldToken: newToken, newToken: oldToken, wantErr: true},
+		{name: "tokens of different changes", oldToken: oldToken, newToken: otherNew, wantErr: true},
+		{name: "other change pair", oldToken: otherOld, newToken: otherNew},
+		{name: "expired token", oldToken: oldToken, newToken: expiredNew, wantErr: true},
+		{name: "access token", oldToken: oldToken, newToken: accessToken, wantErr: true},
+		{name: "malformed token", oldToken: "not.a.token", newToken: newToken, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gotUserID, gotOld, gotNew, err := tgv.ValidateLoginChangeTokens(tt.oldToken, tt.newToken)
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "JWT error")
+				assert.Equal(t, uuid.Nil, gotUserID)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, userID, gotUserID)
+			assert.Equal(t, "alice", gotOld)
+			assert.Equal(t, "alice@example.com", gotNew)
+		})
+	}
+}
+
+func TestTokenGenerateValidator_LoginChangeTokenNotAccessToken(t *testing.T) {
+	t.Parallel()
+
+	tgv, err := NewTokenGenerateValidator(make([]byte, MinSecretKeyLength), time.Hour, TokenClaimsPolicy{}, nil)
+	require.NoError(t, err)
+
+	oldToken, newToken, _, err := tgv.GenerateLoginChangeTokens(uuid.New(), "alice", "bob_new", time.Hour)
+	require.NoError(t, err)
+
+	for _, token := range []string{oldToken, newToken} {
+		_, _, err := tgv.ValidateAccessToken(token)
+		require.Error(t, err)
+	}
+}
@@ -0,0 +1,83 @@
+package security
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// TokenTypeItemPurge is the type of the token confirming the purge of every item of one kind.
+// The type is signed with its own key derived from the secret key, so a purge token is never accepted
+// as an access token or as a login change token.
+const TokenTypeItemPurge = "item_purge"
+
+// PurgeClaims represents the JWT claims of an item purge confirmation token.
+type PurgeClaims struct {
+	jwt.RegisteredClaims
+	// Kind contains the kind of the items to purge.
+	Kind string `json:"kind"`
+	// Digest contains the digest of the items the purge was requested for.
+	Digest string `json:"digest"`
+	// UserID contains the unique identifier of the user purging the items.
+	UserID uuid.UUID `json:"user_id"`
+}
+
+// GeneratePurgeToken creates the confirmation token of a purge of the items of the kind valid for ttl.
+// The token binds the digest of the affected items, so it confirms the purge of exactly those items.
+func (t *TokenGenerateValidator) GeneratePurgeToken(
+	userID uuid.UUID,
+	kind string,
+	digest string,
+	ttl time.Duration,
+) (token string, expiresAt time.Time, err error) {
+	issuedAt := time.Now()
+	expiresAt = issuedAt.Add(ttl)
+
+	claims := &PurgeClaims{
+		UserID: userID,
+		Kind:   kind,
+		Digest: digest,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			IssuedAt:  jwt.NewNumericDate(issuedAt),
+			NotBefore: jwt.NewNumericDate(issuedAt),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			Issuer:    t.issuer,
+			Audience:  jwt.ClaimStrings{TokenTypeItemPurge},
+		},
+	}
+	token, err = jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(t.typeKey(TokenTypeItemPurge))
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("JWT error: failed to sign %s token: %w", TokenTypeItemPurge, err)
+	}
+	return token, expiresAt, nil
+}
+
+// ValidatePurgeToken validates the confirmation token of an item purge and returns the user purging
+// the items, the kind of the items and the digest of the items the purge was requested for.
+func (t *TokenGenerateValidator) ValidatePurgeToken(
+	tokenString string,
+) (userID uuid.UUID, kind string, digest string, err error) {
+	token, err := jwt.ParseWithClaims(tokenString, &PurgeClaims{}, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("JWT error: unexpected signing method: %v", token.Header["alg"])
+		}
+		return t.typeKey(TokenTypeItemPurge), nil
+	},
+		jwt.WithIssuer(t.issuer),
+		jwt.WithAudience(TokenTypeItemPurge),
+		jwt.WithLeeway(t.leeway),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return uuid.Nil, "", "", fmt.Errorf("JWT error: invalid %s token: %w", TokenTypeItemPurge, err)
+	}
+
+	claims, ok := token.Claims.(*PurgeClaims)
+	if !ok || !token.Valid || claims == nil {
+		return uuid.Nil, "", "", fmt.Errorf("JWT error: %s token is not valid or has expired", TokenTypeItemPurge)
+	}
+	return claims.UserID, claims.Kind, claims.Digest, nil
+}
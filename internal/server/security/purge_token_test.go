@@ -0,0 +1,79 @@
+package security
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenGenerateValidator_PurgeToken(t *testing.T) {
+	t.Parallel()
+
+	tgv, err := NewTokenGenerateValidator(make([]byte, MinSecretKeyLength), time.Hour, TokenClaimsPolicy{}, nil)
+	require.NoError(t, err)
+	otherKey := bytes.Repeat([]byte{1}, MinSecretKeyLength)
+	other, err := NewTokenGenerateValidator(otherKey, time.Hour, TokenClaimsPolicy{}, nil)
+	require.NoError(t, err)
+
+	userID := uuid.New()
+	token, expiresAt, err := tgv.GeneratePurgeToken(userID, "credential", "digest", 5*time.Minute)
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(5*time.Minute), expiresAt, time.Minute)
+
+	expired, _, err := tgv.GeneratePurgeToken(userID, "credential", "digest", -time.Hour)
+	require.NoError(t, err)
+	foreign, _, err := other.GeneratePurgeToken(userID, "credential", "digest", time.Hour)
+	require.NoError(t, err)
+	accessToken, _, _, err := tgv.GenerateAccessToken(userID)
+	require.NoError(t, err)
+	oldToken, _, _, err := tgv.GenerateLoginChangeTokens(userID, "alice", "bob_new", time.Hour)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr bool
+	}{
+		{name: "valid token", token: token},
+		{name: "expired token", token: expired, wantErr: true},
+		{name: "token of another key", token: foreign, wantErr: true},
+		{name: "access token", token: accessToken, wantErr: true},
+		{name: "login change token", token: oldToken, wantErr: true},
+		{name: "malformed token", token: "not.a.token", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gotUserID, gotKind, gotDigest, err := tgv.ValidatePurgeToken(tt.token)
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "JWT error")
+				assert.Equal(t, uuid.Nil, gotUserID)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, userID, gotUserID)
+			assert.Equal(t, "credential", gotKind)
+			assert.Equal(t, "digest", gotDigest)
+		})
+	}
+}
+
+func TestTokenGenerateValidator_PurgeTokenNotAccessToken(t *testing.T) {
+	t.Parallel()
+
+	tgv, err := NewTokenGenerateValidator(make([]byte, MinSecretKeyLength), time.Hour, TokenClaimsPolicy{}, nil)
+	require.NoError(t, err)
+
+	token, _, err := tgv.GeneratePurgeToken(uuid.New(), "note", "digest", time.Hour)
+	require.NoError(t, err)
+
+	_, _, err = tgv.ValidateAccessToken(token)
+	require.Error(t, err)
+}
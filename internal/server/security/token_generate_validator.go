@@ -14,6 +14,9 @@ const (
 	TokenTypeBearer = "Bearer"
 )
 
+// DefaultTokenIssuer is the issuer of access tokens used when none is configured.
+const DefaultTokenIssuer = "aegis_vault_keeper"
+
 // Claims represents the JWT token claims including user identification.
 type Claims struct {
 	jwt.RegisteredClaims
@@ -21,12 +24,31 @@ type Claims struct {
 	UserID uuid.UUID `json:"user_id"`
 }
 
+// TokenClaimsPolicy defines the registered claims set on issued access tokens and required of presented ones.
+type TokenClaimsPolicy struct {
+	// Issuer names the issuer of the tokens (empty uses DefaultTokenIssuer).
+	Issuer string
+	// Audiences lists the audiences of issued tokens; presented tokens must name at least one of them
+	// (empty skips the audience check).
+	Audiences []string
+	// Leeway tolerates clock skew between services when checking the exp, nbf and iat claims.
+	Leeway time.Duration
+}
+
 // TokenGenerateValidator provides JWT token generation and validation functionality.
 type TokenGenerateValidator struct {
+	// federation verifies tokens issued by a central identity provider; nil accepts only own tokens.
+	federation *FederatedTokenVerifier
+	// issuer names the issuer of the tokens.
+	issuer string
 	// secretKey contains the HMAC secret for signing and validating tokens.
 	secretKey []byte
+	// audiences lists the audiences of issued tokens, one of which presented tokens must name.
+	audiences []string
 	// accessTokenExpireDuration defines how long access tokens remain valid.
 	accessTokenExpireDuration time.Duration
+	// leeway tolerates clock skew when checking time-based claims.
+	leeway time.Duration
 }
 
 const (
@@ -35,9 +57,13 @@ const (
 )
 
 // NewTokenGenerateValidator creates a new JWT token generator/validator with security validation.
+// When federation is not nil, tokens signed with asymmetric keys are verified by it, so users signed in
+// through a central identity provider are accepted as well.
 func NewTokenGenerateValidator(
 	secretKey []byte,
 	accessTokenExpireDuration time.Duration,
+	policy TokenClaimsPolicy,
+	federation *FederatedTokenVerifier,
 ) (*TokenGenerateValidator, error) {
 	if len(secretKey) < MinSecretKeyLength {
 		return nil, fmt.Errorf(
@@ -45,9 +71,17 @@ func NewTokenGenerateValidator(
 			MinSecretKeyLength,
 		)
 	}
+	issuer := policy.Issuer
+	if issuer == "" {
+		issuer = DefaultTokenIssuer
+	}
 	return &TokenGenerateValidator{
 		secretKey:                 secretKey,
 		accessTokenExpireDuration: accessTokenExpireDuration,
+		issuer:                    issuer,
+		audiences:                 policy.Audiences,
+		leeway:                    policy.Leeway,
+		federation:                federation,
 	}, nil
 }
 
@@ -60,8 +94,10 @@ func (t *TokenGenerateValidator) GenerateAccessToken(userID uuid.UUID) (string,
 		UserID: userID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			IssuedAt:  jwt.NewNumericDate(issuedAt),
+			NotBefore: jwt.NewNumericDate(issuedAt),
 			ExpiresAt: jwt.NewNumericDate(expiresAt),
-			Issuer:    "aegis_vault_keeper",
+			Issuer:    t.issuer,
+			Audience:  t.audiences,
 		},
 	}
 
@@ -74,22 +110,58 @@ func (t *TokenGenerateValidator) GenerateAccessToken(userID uuid.UUID) (string,
 	return tokenString, TokenTypeBearer, expiresAt, nil
 }
 
-// ValidateAccessToken validates a JWT token and returns the associated user ID.
-func (t *TokenGenerateValidator) ValidateAccessToken(tokenString string) (uuid.UUID, error) {
+// ValidateAccessToken validates a JWT token and returns the associated user ID and the moment the token
+// was issued at, zero when the token does not tell. Tokens must be issued by the configured issuer for one
+// of the configured audiences and be within their validity period. Tokens signed with asymmetric keys are
+// passed to the federation verifier.
+func (t *TokenGenerateValidator) ValidateAccessToken(tokenString string) (uuid.UUID, time.Time, error) {
+	if t.federation != nil && !signedWithHMAC(tokenString) {
+		return t.federation.VerifyAccessToken(tokenString)
+	}
+
+	opts := []jwt.ParserOption{
+		jwt.WithIssuer(t.issuer),
+		jwt.WithLeeway(t.leeway),
+		jwt.WithExpirationRequired(),
+	}
+	if len(t.audiences) > 0 {
+		opts = append(opts, jwt.WithAudience(t.audiences...))
+	}
+
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, fmt.Errorf("JWT error: unexpected signing method: %v", token.Header["alg"])
 		}
 		return t.secretKey, nil
-	})
+	}, opts...)
 	if err != nil {
-		return uuid.Nil, fmt.Errorf("JWT error: invalid token: %w", err)
+		return uuid.Nil, time.Time{}, fmt.Errorf("JWT error: invalid token: %w", err)
 	}
 
 	claims, ok := token.Claims.(*Claims)
 	if !ok || !token.Valid || claims == nil {
-		return uuid.Nil, errors.New("JWT error: token is not valid or has expired")
+		return uuid.Nil, time.Time{}, errors.New("JWT error: token is not valid or has expired")
+	}
+
+	return claims.UserID, issuedAt(claims), nil
+}
+
+// issuedAt returns the moment held in the iat claim, or zero when the claim is missing.
+func issuedAt(claims jwt.Claims) time.Time {
+	iat, err := claims.GetIssuedAt()
+	if err != nil || iat == nil {
+		return time.Time{}
 	}
+	return iat.Time
+}
 
-	return claims.UserID, nil
+// signedWithHMAC reports whether the token header names an HMAC signing method. Tokens that cannot be
+// parsed are reported as HMAC-signed, so the local validation rejects them.
+func signedWithHMAC(tokenString string) bool {
+	token, _, err := jwt.NewParser().ParseUnverified(tokenString, jwt.MapClaims{})
+	if err != nil {
+		return true
+	}
+	_, ok := token.Method.(*jwt.SigningMethodHMAC)
+	return ok
 }
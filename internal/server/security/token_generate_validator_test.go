@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -98,7 +99,12 @@ func TestNewTokenGenerateValidator(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			got, err := NewTokenGenerateValidator(tt.args.secretKey, tt.args.accessTokenExpireDuration)
+			got, err := NewTokenGenerateValidator(
+				tt.args.secretKey,
+				tt.args.accessTokenExpireDuration,
+				TokenClaimsPolicy{},
+				nil,
+			)
 			if tt.wantErr {
 				require.Error(t, err)
 				assert.Nil(t, got)
@@ -123,7 +129,7 @@ func TestTokenGenerateValidator_GenerateAccessToken(t *testing.T) {
 	}
 	duration := time.Hour
 
-	tgv, err := NewTokenGenerateValidator(secretKey, duration)
+	tgv, err := NewTokenGenerateValidator(secretKey, duration, TokenClaimsPolicy{}, nil)
 	require.NoError(t, err)
 
 	type args struct {
@@ -182,9 +188,10 @@ func TestTokenGenerateValidator_GenerateAccessToken(t *testing.T) {
 				assert.True(t, expiresAt.Before(afterGeneration.Add(duration).Add(time.Second)))
 
 				// Validate that the token can be parsed back
-				userID, err := tgv.ValidateAccessToken(token)
+				userID, issuedAt, err := tgv.ValidateAccessToken(token)
 				require.NoError(t, err)
 				assert.Equal(t, tt.args.userID, userID)
+				assert.WithinDuration(t, time.Now(), issuedAt, time.Minute)
 			}
 		})
 	}
@@ -199,7 +206,7 @@ func TestTokenGenerateValidator_ValidateAccessToken(t *testing.T) {
 	}
 	duration := time.Hour
 
-	tgv, err := NewTokenGenerateValidator(secretKey, duration)
+	tgv, err := NewTokenGenerateValidator(secretKey, duration, TokenClaimsPolicy{}, nil)
 	require.NoError(t, err)
 
 	// Generate a valid token for testing
@@ -208,7 +215,7 @@ func TestTokenGenerateValidator_ValidateAccessToken(t *testing.T) {
 	require.NoError(t, err)
 
 	// Create expired token generator for testing
-	expiredTGV, err := NewTokenGenerateValidator(secretKey, -time.Hour) // Already expired
+	expiredTGV, err := NewTokenGenerateValidator(secretKey, -time.Hour, TokenClaimsPolicy{}, nil) // Already expired
 	require.NoError(t, err)
 	expiredToken, _, _, err := expiredTGV.GenerateAccessToken(userID)
 	require.NoError(t, err)
@@ -218,7 +225,7 @@ func TestTokenGenerateValidator_ValidateAccessToken(t *testing.T) {
 	for i := range differentSecretKey {
 		differentSecretKey[i] = byte((i + 1) % 256)
 	}
-	differentTGV, err := NewTokenGenerateValidator(differentSecretKey, duration)
+	differentTGV, err := NewTokenGenerateValidator(differentSecretKey, duration, TokenClaimsPolicy{}, nil)
 	require.NoError(t, err)
 	differentSecretToken, _, _, err := differentTGV.GenerateAccessToken(userID)
 	require.NoError(t, err)
@@ -295,7 +302,7 @@ func TestTokenGenerateValidator_ValidateAccessToken(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			got, err := tgv.ValidateAccessToken(tt.args.tokenString)
+			got, _, err := tgv.ValidateAccessToken(tt.args.tokenString)
 			if tt.wantErr {
 				require.Error(t, err)
 				assert.Equal(t, uuid.Nil, got)
@@ -317,7 +324,7 @@ func TestTokenGenerateValidator_RoundTrip(t *testing.T) {
 	}
 	duration := time.Hour
 
-	tgv, err := NewTokenGenerateValidator(secretKey, duration)
+	tgv, err := NewTokenGenerateValidator(secretKey, duration, TokenClaimsPolicy{}, nil)
 	require.NoError(t, err)
 
 	// Test multiple user IDs
@@ -340,13 +347,149 @@ func TestTokenGenerateValidator_RoundTrip(t *testing.T) {
 			assert.True(t, expiresAt.After(time.Now()))
 
 			// Validate token
-			gotUserID, err := tgv.ValidateAccessToken(token)
+			gotUserID, _, err := tgv.ValidateAccessToken(token)
 			require.NoError(t, err)
 			assert.Equal(t, userID, gotUserID)
 		})
 	}
 }
 
+func TestTokenGenerateValidator_ClaimsPolicy(t *testing.T) {
+	t.Parallel()
+
+	secretKey := make([]byte, MinSecretKeyLength)
+	userID := uuid.New()
+	policy := TokenClaimsPolicy{Issuer: "https://vault.example.com", Audiences: []string{"vault", "cli"}}
+
+	tgv, err := NewTokenGenerateValidator(secretKey, time.Hour, policy, nil)
+	require.NoError(t, err)
+
+	// sign creates a token with the claims, signed with the shared secret.
+	sign := func(claims jwt.RegisteredClaims) string {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, &Claims{RegisteredClaims: claims, UserID: userID}).
+			SignedString(secretKey)
+		require.NoError(t, err)
+		return token
+	}
+	now := time.Now()
+	exp := jwt.NewNumericDate(now.Add(time.Hour))
+
+	tests := []struct {
+		name    string
+		token   string
+		leeway  time.Duration
+		wantErr bool
+	}{
+		{
+			name:  "own token",
+			token: func() string { token, _, _, _ := tgv.GenerateAccessToken(userID); return token }(),
+		},
+		{
+			name:  "any accepted audience",
+			token: sign(jwt.RegisteredClaims{Issuer: policy.Issuer, Audience: []string{"cli"}, ExpiresAt: exp}),
+		},
+		{
+			name:    "other issuer",
+			token:   sign(jwt.RegisteredClaims{Issuer: DefaultTokenIssuer, Audience: []string{"vault"}, ExpiresAt: exp}),
+			wantErr: true,
+		},
+		{
+			name:    "other audience",
+			token:   sign(jwt.RegisteredClaims{Issuer: policy.Issuer, Audience: []string{"billing"}, ExpiresAt: exp}),
+			wantErr: true,
+		},
+		{
+			name:    "no audience",
+			token:   sign(jwt.RegisteredClaims{Issuer: policy.Issuer, ExpiresAt: exp}),
+			wantErr: true,
+		},
+		{
+			name:    "no expiry",
+			token:   sign(jwt.RegisteredClaims{Issuer: policy.Issuer, Audience: []string{"vault"}}),
+			wantErr: true,
+		},
+		{
+			name: "not yet valid",
+			token: sign(jwt.RegisteredClaims{
+				Issuer: policy.Issuer, Audience: []string{"vault"}, ExpiresAt: exp,
+				NotBefore: jwt.NewNumericDate(now.Add(30 * time.Second)),
+			}),
+			wantErr: true,
+		},
+		{
+			name: "not yet valid within leeway",
+			token: sign(jwt.RegisteredClaims{
+				Issuer: policy.Issuer, Audience: []string{"vault"}, ExpiresAt: exp,
+				NotBefore: jwt.NewNumericDate(now.Add(30 * time.Second)),
+			}),
+			leeway: time.Minute,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			p := policy
+			p.Leeway = tt.leeway
+			validator, err := NewTokenGenerateValidator(secretKey, time.Hour, p, nil)
+			require.NoError(t, err)
+
+			got, _, err := validator.ValidateAccessToken(tt.token)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "JWT error")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, userID, got)
+		})
+	}
+}
+
+func TestTokenGenerateValidator_GenerateAccessTokenClaims(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		policy     TokenClaimsPolicy
+		wantIssuer string
+		wantAud    jwt.ClaimStrings
+	}{
+		{
+			name:       "default issuer",
+			wantIssuer: DefaultTokenIssuer,
+		},
+		{
+			name:       "configured issuer and audiences",
+			policy:     TokenClaimsPolicy{Issuer: "https://vault.example.com", Audiences: []string{"vault", "cli"}},
+			wantIssuer: "https://vault.example.com",
+			wantAud:    jwt.ClaimStrings{"vault", "cli"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tgv, err := NewTokenGenerateValidator(make([]byte, MinSecretKeyLength), time.Hour, tt.policy, nil)
+			require.NoError(t, err)
+
+			token, _, _, err := tgv.GenerateAccessToken(uuid.New())
+			require.NoError(t, err)
+
+			claims := &Claims{}
+			_, _, err = jwt.NewParser().ParseUnverified(token, claims)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantIssuer, claims.Issuer)
+			assert.Equal(t, tt.wantAud, claims.Audience)
+			require.NotNil(t, claims.NotBefore)
+			assert.Equal(t, claims.IssuedAt, claims.NotBefore)
+		})
+	}
+}
+
 func TestClaims(t *testing.T) {
 	t.Parallel()
 
@@ -368,7 +511,7 @@ func TestConstants(t *testing.T) {
 // Benchmark token generation and validation.
 func BenchmarkTokenGenerateValidator_GenerateAccessToken(b *testing.B) {
 	secretKey := make([]byte, MinSecretKeyLength)
-	tgv, _ := NewTokenGenerateValidator(secretKey, time.Hour)
+	tgv, _ := NewTokenGenerateValidator(secretKey, time.Hour, TokenClaimsPolicy{}, nil)
 	userID := uuid.New()
 
 	b.ResetTimer()
@@ -382,13 +525,13 @@ func BenchmarkTokenGenerateValidator_GenerateAccessToken(b *testing.B) {
 
 func BenchmarkTokenGenerateValidator_ValidateAccessToken(b *testing.B) {
 	secretKey := make([]byte, MinSecretKeyLength)
-	tgv, _ := NewTokenGenerateValidator(secretKey, time.Hour)
+	tgv, _ := NewTokenGenerateValidator(secretKey, time.Hour, TokenClaimsPolicy{}, nil)
 	userID := uuid.New()
 	token, _, _, _ := tgv.GenerateAccessToken(userID)
 
 	b.ResetTimer()
 	for range b.N {
-		_, err := tgv.ValidateAccessToken(token)
+		_, _, err := tgv.ValidateAccessToken(token)
 		if err != nil {
 			b.Fatal(err)
 		}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/auth"
 	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/auth"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/vaultunlock"
 	"github.com/google/uuid"
 )
 
@@ -29,10 +30,25 @@ func NewUserKeyProvider(r UserKeyRepository) *UserKeyProvider {
 }
 
 // UserKeyProvide retrieves the cryptographic key for the specified user ID.
+// The key is decrypted for every call, so the caller owns it and wipes it once done.
+// Keys of users with an unlock secret are opened with the unlock key carried by ctx and fail with
+// vaultunlock.ErrLocked without one.
 func (p *UserKeyProvider) UserKeyProvide(ctx context.Context, userID uuid.UUID) ([]byte, error) {
 	u, err := p.r.Load(ctx, repository.LoadParams{ID: userID})
 	if err != nil {
 		return nil, fmt.Errorf("failed to load user with ID %s: %w", userID, err)
 	}
-	return u.CryptoKey, nil
+	if !u.HasUnlockSecret() {
+		return u.CryptoKey, nil
+	}
+
+	unlockKey, ok := vaultunlock.Key(ctx)
+	if !ok {
+		return nil, fmt.Errorf("failed to open key of user with ID %s: %w", userID, vaultunlock.ErrLocked)
+	}
+	key, err := vaultunlock.Open(unlockKey, u.CryptoKey, u.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open key of user with ID %s: %w", userID, err)
+	}
+	return key, nil
 }
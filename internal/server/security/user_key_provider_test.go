@@ -1,12 +1,14 @@
 package security
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"testing"
 
 	"github.com/gdyunin/aegis-vault-keeper/internal/server/domain/auth"
 	repository "github.com/gdyunin/aegis-vault-keeper/internal/server/repository/auth"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/vaultunlock"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -213,6 +215,51 @@ func TestUserKeyProvider_UserKeyProvide(t *testing.T) {
 	}
 }
 
+func TestUserKeyProvider_UserKeyProvide_UnlockSecret(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	cryptoKey := []byte("test_crypto_key_123456789012345678")
+	unlockKey := bytes.Repeat([]byte{1}, vaultunlock.KeySize)
+	sealed, err := vaultunlock.Seal(unlockKey, cryptoKey, userID)
+	require.NoError(t, err)
+
+	tests := []struct {
+		ctx     context.Context
+		wantErr error
+		name    string
+	}{
+		{name: "unlocked", ctx: vaultunlock.WithKey(context.Background(), unlockKey)},
+		{name: "locked", ctx: context.Background(), wantErr: vaultunlock.ErrLocked},
+		{
+			name:    "wrong unlock key",
+			ctx:     vaultunlock.WithKey(context.Background(), bytes.Repeat([]byte{2}, vaultunlock.KeySize)),
+			wantErr: vaultunlock.ErrInvalidKey,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			p := NewUserKeyProvider(&mockUserKeyRepository{
+				users: map[uuid.UUID]*auth.User{
+					userID: {ID: userID, CryptoKey: sealed, UnlockSalt: []byte("0123456789abcdef")},
+				},
+			})
+
+			got, err := p.UserKeyProvide(tt.ctx, userID)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, got)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, cryptoKey, got)
+		})
+	}
+}
+
 func TestUserKeyProvider_UserKeyProvide_ContextHandling(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,6 @@
+// Package seed loads fixture documents describing users and their vaults into an AegisVaultKeeper server.
+//
+// Fixture documents are written in YAML or JSON. Items are stored through the application services, so
+// they are validated, encrypted and signed exactly like data pushed through the API, which makes the
+// loaded environments suitable for staging and for reproducing bug reports.
+package seed
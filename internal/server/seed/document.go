@@ -0,0 +1,178 @@
+package seed
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Document describes the users and vault items to load.
+type Document struct {
+	// Users lists the users to register together with their vaults.
+	Users []User `yaml:"users"`
+}
+
+// User describes a user and the content of the user's vault.
+type User struct {
+	// Login contains the login of the user.
+	Login string `yaml:"login"`
+	// Password contains the password of the user.
+	Password string `yaml:"password"`
+	// Folders lists the file folders to create in order; parents must precede their subfolders.
+	Folders []string `yaml:"folders"`
+	// Credentials lists the credentials of the user.
+	Credentials []Credential `yaml:"credentials"`
+	// BankCards lists the bank cards of the user.
+	BankCards []BankCard `yaml:"bank_cards"`
+	// Notes lists the notes of the user.
+	Notes []Note `yaml:"notes"`
+	// Files lists the files of the user.
+	Files []File `yaml:"files"`
+}
+
+// Organization holds the organization shared by all vault items.
+type Organization struct {
+	// Path contains the item path; empty leaves the item without a path.
+	Path string `yaml:"path"`
+	// Tags lists the item tags.
+	Tags []string `yaml:"tags"`
+}
+
+// Credential describes a credential.
+type Credential struct {
+	// Login contains the stored login.
+	Login string `yaml:"login"`
+	// Password contains the stored password.
+	Password string `yaml:"password"`
+	// Description contains the credential description.
+	Description string `yaml:"description"`
+	// URIs lists the website and app addresses of the credential.
+	URIs []URI `yaml:"uris"`
+	// Organization holds the path and the tags of the credential.
+	Organization `yaml:",inline"`
+}
+
+// URI describes a credential URI.
+type URI struct {
+	// Value contains the URI, or the regular expression for the regex rule.
+	Value string `yaml:"value"`
+	// Match contains the match rule; empty means base_domain.
+	Match string `yaml:"match"`
+}
+
+// BankCard describes a bank card.
+type BankCard struct {
+	// CardNumber contains the card number.
+	CardNumber string `yaml:"card_number"`
+	// CardHolder contains the name of the card holder.
+	CardHolder string `yaml:"card_holder"`
+	// ExpiryMonth contains the two-digit expiry month.
+	ExpiryMonth string `yaml:"expiry_month"`
+	// ExpiryYear contains the four-digit expiry year.
+	ExpiryYear string `yaml:"expiry_year"`
+	// CVV contains the card verification value.
+	CVV string `yaml:"cvv"`
+	// Description contains the card description.
+	Description string `yaml:"description"`
+	// Organization holds the path and the tags of the bank card.
+	Organization `yaml:",inline"`
+}
+
+// Note describes a note.
+type Note struct {
+	// Note contains the note text.
+	Note string `yaml:"note"`
+	// Description contains the note description.
+	Description string `yaml:"description"`
+	// Organization holds the path and the tags of the note.
+	Organization `yaml:",inline"`
+}
+
+// File describes a file. Its content is given inline or read from a source file.
+type File struct {
+	// Name contains the storage key of the file.
+	Name string `yaml:"name"`
+	// Folder contains the folder of the file; it must be listed in the folders of the user.
+	Folder string `yaml:"folder"`
+	// Description contains the file description.
+	Description string `yaml:"description"`
+	// Content contains the text content of the file.
+	Content string `yaml:"content"`
+	// Source contains the path of the file holding the content, relative to the fixture document.
+	Source string `yaml:"source"`
+	// Organization holds the path and the tags of the file.
+	Organization `yaml:",inline"`
+}
+
+// Decode parses a fixture document written in YAML or JSON. Unknown fields are rejected, so typos do not
+// silently drop data.
+func Decode(r io.Reader) (*Document, error) {
+	dec := yaml.NewDecoder(r)
+	dec.KnownFields(true)
+
+	// doc holds the decoded document.
+	var doc Document
+	if err := dec.Decode(&doc); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to decode fixture document: %w", err)
+	}
+	if err := doc.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid fixture document: %w", err)
+	}
+	return &doc, nil
+}
+
+// ReadDocument reads and decodes the fixture document at path, resolving file sources relative to it.
+func ReadDocument(path string) (*Document, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixture document: %w", err)
+	}
+	doc, err := Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	dir := filepath.Dir(path)
+	for _, u := range doc.Users {
+		for i, f := range u.Files {
+			if f.Source != "" && !filepath.IsAbs(f.Source) {
+				u.Files[i].Source = filepath.Join(dir, f.Source)
+			}
+		}
+	}
+	return doc, nil
+}
+
+// Validate checks the parts of the document the application services cannot: logins are present and
+// unique and every file has exactly one content origin. Item fields are validated when items are stored.
+func (d *Document) Validate() error {
+	if len(d.Users) == 0 {
+		return errors.New("no users")
+	}
+
+	// errs collects the problems found.
+	var errs []error
+	logins := make(map[string]bool, len(d.Users))
+	for i, u := range d.Users {
+		switch {
+		case u.Login == "":
+			errs = append(errs, fmt.Errorf("user %d: login is required", i+1))
+		case logins[u.Login]:
+			errs = append(errs, fmt.Errorf("user %q: duplicate login", u.Login))
+		}
+		logins[u.Login] = true
+
+		for j, f := range u.Files {
+			if (f.Content == "") == (f.Source == "") {
+				errs = append(errs, fmt.Errorf("user %q: file %d: exactly one of content or source is required",
+					u.Login, j+1))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
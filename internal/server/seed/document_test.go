@@ -0,0 +1,128 @@
+package seed
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		want    *Document
+		name    string
+		input   string
+		wantErr string
+	}{
+		{
+			name: "yaml",
+			input: `
+users:
+  - login: alice
+    password: alice-password
+    folders: [docs]
+    credentials:
+      - login: alice@example.com
+        password: secret
+        path: personal/mail
+        tags: [personal]
+    files:
+      - name: a.txt
+        folder: docs
+        content: hello
+`,
+			want: &Document{Users: []User{{
+				Login:    "alice",
+				Password: "alice-password",
+				Folders:  []string{"docs"},
+				Credentials: []Credential{{
+					Login:        "alice@example.com",
+					Password:     "secret",
+					Organization: Organization{Path: "personal/mail", Tags: []string{"personal"}},
+				}},
+				Files: []File{{Name: "a.txt", Folder: "docs", Content: "hello"}},
+			}}},
+		},
+		{
+			name:  "json",
+			input: `{"users":[{"login":"bob","password":"bob-password","bank_cards":[{"card_number":"4111"}]}]}`,
+			want: &Document{Users: []User{{
+				Login:     "bob",
+				Password:  "bob-password",
+				BankCards: []BankCard{{CardNumber: "4111"}},
+			}}},
+		},
+		{
+			name:    "unknown field",
+			input:   `{"users":[{"login":"bob","pasword":"typo"}]}`,
+			wantErr: "pasword",
+		},
+		{
+			name:    "empty document",
+			input:   ``,
+			wantErr: "no users",
+		},
+		{
+			name:    "duplicate login",
+			input:   `{"users":[{"login":"bob"},{"login":"bob"}]}`,
+			wantErr: "duplicate login",
+		},
+		{
+			name:    "file without content",
+			input:   `{"users":[{"login":"bob","files":[{"name":"a.txt"}]}]}`,
+			wantErr: "exactly one of content or source",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			doc, err := Decode(strings.NewReader(tt.input))
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, doc)
+		})
+	}
+}
+
+func TestReadDocument(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "fixtures.yml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+users:
+  - login: alice
+    files:
+      - name: relative.pdf
+        source: files/relative.pdf
+      - name: absolute.pdf
+        source: /srv/absolute.pdf
+`), 0o600))
+
+	doc, err := ReadDocument(path)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "files", "relative.pdf"), doc.Users[0].Files[0].Source)
+	assert.Equal(t, "/srv/absolute.pdf", doc.Users[0].Files[1].Source)
+
+	_, err = ReadDocument(filepath.Join(dir, "missing.yml"))
+	assert.Error(t, err)
+}
+
+func TestReadDocument_Example(t *testing.T) {
+	t.Parallel()
+
+	doc, err := ReadDocument(filepath.Join("..", "..", "..", "config", "fixtures.example.yml"))
+	require.NoError(t, err)
+	assert.Len(t, doc.Users, 2)
+}
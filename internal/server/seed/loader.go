@@ -0,0 +1,262 @@
+package seed
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/auth"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/bankcard"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/credential"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/filedata"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/itempath"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/itemtag"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/note"
+	"github.com/google/uuid"
+)
+
+// UserService defines the user registration required by the loader.
+type UserService interface {
+	// Register creates a user and returns its identifier and recovery codes.
+	Register(ctx context.Context, params auth.RegisterParams) (auth.Registration, error)
+}
+
+// CredentialService defines the credential operations required by the loader.
+type CredentialService interface {
+	// Push creates a credential and returns its identifier.
+	Push(ctx context.Context, params *credential.PushParams) (uuid.UUID, error)
+}
+
+// BankCardService defines the bank card operations required by the loader.
+type BankCardService interface {
+	// Push creates a bank card and returns its identifier.
+	Push(ctx context.Context, params *bankcard.PushParams) (uuid.UUID, error)
+}
+
+// NoteService defines the note operations required by the loader.
+type NoteService interface {
+	// Push creates a note and returns its identifier.
+	Push(ctx context.Context, params *note.PushParams) (uuid.UUID, error)
+}
+
+// FileService defines the file and folder operations required by the loader.
+type FileService interface {
+	// CreateFolder creates a folder below an existing parent folder.
+	CreateFolder(ctx context.Context, params filedata.CreateFolderParams) (*filedata.Folder, error)
+	// Push stores a file and returns its identifier.
+	Push(ctx context.Context, params *filedata.PushParams) (uuid.UUID, error)
+}
+
+// TagService defines the item tag operations required by the loader.
+type TagService interface {
+	// SetTags replaces the tags of an item.
+	SetTags(ctx context.Context, params itemtag.SetParams) (*itemtag.ItemTags, error)
+}
+
+// PathService defines the item path operations required by the loader.
+type PathService interface {
+	// SetPath replaces the path of an item.
+	SetPath(ctx context.Context, params itempath.SetParams) (*itempath.ItemPath, error)
+}
+
+// UnitOfWork defines the interface for running several repository writes as one transaction.
+type UnitOfWork interface {
+	// Do executes fn in a single transaction scoped to the user, rolling back every write on error.
+	Do(ctx context.Context, userID uuid.UUID, fn func(ctx context.Context) error) error
+}
+
+// Result counts the loaded entities.
+type Result struct {
+	// Users is the number of registered users.
+	Users int
+	// Credentials is the number of stored credentials.
+	Credentials int
+	// BankCards is the number of stored bank cards.
+	BankCards int
+	// Notes is the number of stored notes.
+	Notes int
+	// Folders is the number of created folders.
+	Folders int
+	// Files is the number of stored files.
+	Files int
+}
+
+// Loader stores fixture documents through the application services.
+type Loader struct {
+	// users registers the users.
+	users UserService
+	// credentials stores the credentials.
+	credentials CredentialService
+	// cards stores the bank cards.
+	cards BankCardService
+	// notes stores the notes.
+	notes NoteService
+	// files stores the folders and files.
+	files FileService
+	// tags stores the item tags.
+	tags TagService
+	// paths stores the item paths.
+	paths PathService
+	// uow groups the writes of a vault into a single transaction.
+	uow UnitOfWork
+}
+
+// NewLoader creates a new Loader with the provided services.
+func NewLoader(
+	users UserService,
+	credentials CredentialService,
+	cards BankCardService,
+	notes NoteService,
+	files FileService,
+	tags TagService,
+	paths PathService,
+	uow UnitOfWork,
+) *Loader {
+	return &Loader{
+		users:       users,
+		credentials: credentials,
+		cards:       cards,
+		notes:       notes,
+		files:       files,
+		tags:        tags,
+		paths:       paths,
+		uow:         uow,
+	}
+}
+
+// Load registers the users of the document and stores their vaults. The vault of each user is stored in
+// a single transaction. Loading stops at the first error; users loaded before it are kept, and the failing
+// user stays registered with an empty vault.
+func (l *Loader) Load(ctx context.Context, doc *Document) (*Result, error) {
+	res := &Result{}
+	for _, u := range doc.Users {
+		registration, err := l.users.Register(ctx, auth.RegisterParams{Login: u.Login, Password: u.Password})
+		if err != nil {
+			return res, fmt.Errorf("failed to register user %q: %w", u.Login, err)
+		}
+		res.Users++
+		userID := registration.UserID
+
+		// vault counts the entities of the user until they are committed.
+		vault := &Result{}
+		if err := l.uow.Do(ctx, userID, func(ctx context.Context) error {
+			*vault = Result{}
+			return l.loadVault(ctx, userID, &u, vault)
+		}); err != nil {
+			return res, fmt.Errorf("failed to load the vault of user %q: %w", u.Login, err)
+		}
+		res.Credentials += vault.Credentials
+		res.BankCards += vault.BankCards
+		res.Notes += vault.Notes
+		res.Folders += vault.Folders
+		res.Files += vault.Files
+	}
+	return res, nil
+}
+
+// loadVault stores the vault items of a user and counts them in res.
+func (l *Loader) loadVault(ctx context.Context, userID uuid.UUID, u *User, res *Result) error {
+	for i, c := range u.Credentials {
+		id, err := l.credentials.Push(ctx, &credential.PushParams{
+			Login:       c.Login,
+			Password:    c.Password,
+			Description: c.Description,
+			URIs:        credentialURIs(c.URIs),
+			UserID:      userID,
+		})
+		if err != nil {
+			return fmt.Errorf("credential %d: %w", i+1, err)
+		}
+		if err := l.organize(ctx, userID, id, c.Organization); err != nil {
+			return fmt.Errorf("credential %d: %w", i+1, err)
+		}
+		res.Credentials++
+	}
+
+	for i, c := range u.BankCards {
+		id, err := l.cards.Push(ctx, &bankcard.PushParams{
+			CardNumber:  c.CardNumber,
+			CardHolder:  c.CardHolder,
+			ExpiryMonth: c.ExpiryMonth,
+			ExpiryYear:  c.ExpiryYear,
+			CVV:         c.CVV,
+			Description: c.Description,
+			UserID:      userID,
+		})
+		if err != nil {
+			return fmt.Errorf("bank card %d: %w", i+1, err)
+		}
+		if err := l.organize(ctx, userID, id, c.Organization); err != nil {
+			return fmt.Errorf("bank card %d: %w", i+1, err)
+		}
+		res.BankCards++
+	}
+
+	for i, n := range u.Notes {
+		id, err := l.notes.Push(ctx, &note.PushParams{Note: n.Note, Description: n.Description, UserID: userID})
+		if err != nil {
+			return fmt.Errorf("note %d: %w", i+1, err)
+		}
+		if err := l.organize(ctx, userID, id, n.Organization); err != nil {
+			return fmt.Errorf("note %d: %w", i+1, err)
+		}
+		res.Notes++
+	}
+
+	for _, path := range u.Folders {
+		if _, err := l.files.CreateFolder(ctx, filedata.CreateFolderParams{Path: path, UserID: userID}); err != nil {
+			return fmt.Errorf("folder %q: %w", path, err)
+		}
+		res.Folders++
+	}
+
+	for _, f := range u.Files {
+		data := []byte(f.Content)
+		if f.Source != "" {
+			var err error
+			if data, err = os.ReadFile(f.Source); err != nil {
+				return fmt.Errorf("file %q: failed to read source: %w", f.Name, err)
+			}
+		}
+		id, err := l.files.Push(ctx, &filedata.PushParams{
+			StorageKey:  f.Name,
+			Description: f.Description,
+			Folder:      f.Folder,
+			Data:        data,
+			UserID:      userID,
+			AllowEmpty:  true,
+		})
+		if err != nil {
+			return fmt.Errorf("file %q: %w", f.Name, err)
+		}
+		if err := l.organize(ctx, userID, id, f.Organization); err != nil {
+			return fmt.Errorf("file %q: %w", f.Name, err)
+		}
+		res.Files++
+	}
+	return nil
+}
+
+// organize stores the path and the tags of an item.
+func (l *Loader) organize(ctx context.Context, userID, itemID uuid.UUID, o Organization) error {
+	if o.Path != "" {
+		if _, err := l.paths.SetPath(ctx, itempath.SetParams{Path: o.Path, ItemID: itemID, UserID: userID}); err != nil {
+			return fmt.Errorf("failed to set path: %w", err)
+		}
+	}
+	if len(o.Tags) > 0 {
+		if _, err := l.tags.SetTags(ctx, itemtag.SetParams{Tags: o.Tags, ItemID: itemID, UserID: userID}); err != nil {
+			return fmt.Errorf("failed to set tags: %w", err)
+		}
+	}
+	return nil
+}
+
+// credentialURIs converts the credential URIs of a fixture document to application DTOs.
+func credentialURIs(uris []URI) []credential.URI {
+	result := make([]credential.URI, 0, len(uris))
+	for _, u := range uris {
+		result = append(result, credential.URI{Value: u.Value, Match: u.Match})
+	}
+	return result
+}
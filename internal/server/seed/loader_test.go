@@ -0,0 +1,176 @@
+package seed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/auth"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/bankcard"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/credential"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/filedata"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/itempath"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/itemtag"
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/application/note"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recorder records the calls made by the loader and fails the call named fail.
+type recorder struct {
+	// fail names the call to fail.
+	fail string
+	// calls lists the calls in order.
+	calls []string
+}
+
+// record records a call and returns an error when it is the one to fail.
+func (r *recorder) record(format string, args ...any) error {
+	call := fmt.Sprintf(format, args...)
+	r.calls = append(r.calls, call)
+	if call == r.fail {
+		return errors.New("boom")
+	}
+	return nil
+}
+
+// fakeUsers registers users through the recorder.
+type fakeUsers struct{ *recorder }
+
+func (f fakeUsers) Register(_ context.Context, params auth.RegisterParams) (auth.Registration, error) {
+	return auth.Registration{UserID: uuid.New()}, f.record("register %s", params.Login)
+}
+
+// fakeCredentials stores credentials through the recorder.
+type fakeCredentials struct{ *recorder }
+
+func (f fakeCredentials) Push(_ context.Context, params *credential.PushParams) (uuid.UUID, error) {
+	return uuid.New(), f.record("credential %s", params.Login)
+}
+
+// fakeCards stores bank cards through the recorder.
+type fakeCards struct{ *recorder }
+
+func (f fakeCards) Push(_ context.Context, params *bankcard.PushParams) (uuid.UUID, error) {
+	return uuid.New(), f.record("card %s", params.CardNumber)
+}
+
+// fakeNotes stores notes through the recorder.
+type fakeNotes struct{ *recorder }
+
+func (f fakeNotes) Push(_ context.Context, params *note.PushParams) (uuid.UUID, error) {
+	return uuid.New(), f.record("note %s", params.Description)
+}
+
+// fakeFiles stores folders and files through the recorder.
+type fakeFiles struct{ *recorder }
+
+func (f fakeFiles) CreateFolder(_ context.Context, params filedata.CreateFolderParams) (*filedata.Folder, error) {
+	return &filedata.Folder{}, f.record("folder %s", params.Path)
+}
+
+func (f fakeFiles) Push(_ context.Context, params *filedata.PushParams) (uuid.UUID, error) {
+	return uuid.New(), f.record("file %s/%s %s", params.Folder, params.StorageKey, params.Data)
+}
+
+// fakeTags stores item tags through the recorder.
+type fakeTags struct{ *recorder }
+
+func (f fakeTags) SetTags(_ context.Context, params itemtag.SetParams) (*itemtag.ItemTags, error) {
+	return &itemtag.ItemTags{}, f.record("tags %v", params.Tags)
+}
+
+// fakePaths stores item paths through the recorder.
+type fakePaths struct{ *recorder }
+
+func (f fakePaths) SetPath(_ context.Context, params itempath.SetParams) (*itempath.ItemPath, error) {
+	return &itempath.ItemPath{}, f.record("path %s", params.Path)
+}
+
+// fakeUnitOfWork runs units of work directly through the recorder.
+type fakeUnitOfWork struct{ *recorder }
+
+func (f fakeUnitOfWork) Do(ctx context.Context, _ uuid.UUID, fn func(ctx context.Context) error) error {
+	if err := f.record("begin"); err != nil {
+		return err
+	}
+	return fn(ctx)
+}
+
+// newTestLoader returns a loader over fakes recording into r.
+func newTestLoader(r *recorder) *Loader {
+	return NewLoader(fakeUsers{r}, fakeCredentials{r}, fakeCards{r}, fakeNotes{r}, fakeFiles{r}, fakeTags{r},
+		fakePaths{r}, fakeUnitOfWork{r})
+}
+
+func TestLoader_Load(t *testing.T) {
+	t.Parallel()
+
+	doc := &Document{Users: []User{
+		{
+			Login:   "alice",
+			Folders: []string{"docs"},
+			Credentials: []Credential{
+				{Login: "alice@example.com", Organization: Organization{Path: "mail", Tags: []string{"personal"}}},
+			},
+			BankCards: []BankCard{{CardNumber: "4111111111111111"}},
+			Notes:     []Note{{Description: "Wi-Fi", Organization: Organization{Tags: []string{"home"}}}},
+			Files:     []File{{Name: "a.txt", Folder: "docs", Content: "hello"}},
+		},
+		{Login: "bob", Notes: []Note{{Description: "Todo"}}},
+	}}
+
+	tests := []struct {
+		wantRes   *Result
+		name      string
+		fail      string
+		wantCalls []string
+	}{
+		{
+			name: "everything loaded",
+			wantCalls: []string{
+				"register alice", "begin", "credential alice@example.com", "path mail", "tags [personal]",
+				"card 4111111111111111", "note Wi-Fi", "tags [home]", "folder docs", "file docs/a.txt hello",
+				"register bob", "begin", "note Todo",
+			},
+			wantRes: &Result{Users: 2, Credentials: 1, BankCards: 1, Notes: 2, Folders: 1, Files: 1},
+		},
+		{
+			name: "item failure stops loading",
+			fail: "note Wi-Fi",
+			wantCalls: []string{
+				"register alice", "begin", "credential alice@example.com", "path mail", "tags [personal]",
+				"card 4111111111111111", "note Wi-Fi",
+			},
+			wantRes: &Result{Users: 1},
+		},
+		{
+			name: "registration failure stops loading",
+			fail: "register bob",
+			wantCalls: []string{
+				"register alice", "begin", "credential alice@example.com", "path mail", "tags [personal]",
+				"card 4111111111111111", "note Wi-Fi", "tags [home]", "folder docs", "file docs/a.txt hello",
+				"register bob",
+			},
+			wantRes: &Result{Users: 1, Credentials: 1, BankCards: 1, Notes: 1, Folders: 1, Files: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := &recorder{fail: tt.fail}
+			res, err := newTestLoader(r).Load(context.Background(), doc)
+			if tt.fail != "" {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantCalls, r.calls)
+			assert.Equal(t, tt.wantRes, res)
+		})
+	}
+}
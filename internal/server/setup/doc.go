@@ -0,0 +1,6 @@
+// Package setup prepares a new AegisVaultKeeper installation.
+//
+// This package asks for the deployment settings, generates the secrets of the server, checks the resulting
+// configuration the way the server loads it, writes it to an environment file and creates the database schema
+// from the migrations, recording the version like the migrate tool does so later migrations apply on top.
+package setup
@@ -0,0 +1,109 @@
+package setup
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// prompter asks questions on a terminal and reads the answers line by line. After the first failure to read an
+// answer it asks nothing more and keeps the current values; the failure is reported by err.
+type prompter struct {
+	// err holds the first failure to read an answer.
+	err error
+	// in reads the answers.
+	in *bufio.Reader
+	// out receives the questions.
+	out io.Writer
+}
+
+// newPrompter creates a prompter asking on out and reading answers from in.
+func newPrompter(in io.Reader, out io.Writer) *prompter {
+	return &prompter{in: bufio.NewReader(in), out: out}
+}
+
+// ask asks for a text value; an empty answer keeps the current value shown in brackets.
+func (p *prompter) ask(question, current string) string {
+	if p.err != nil {
+		return current
+	}
+	if current != "" {
+		fmt.Fprintf(p.out, "%s [%s]: ", question, current)
+	} else {
+		fmt.Fprintf(p.out, "%s: ", question)
+	}
+	line, err := p.in.ReadString('\n')
+	switch {
+	case errors.Is(err, io.EOF) && line == "":
+		fmt.Fprintln(p.out)
+		p.err = fmt.Errorf("no answer to %q: %w", question, io.ErrUnexpectedEOF)
+		return current
+	case err != nil && !errors.Is(err, io.EOF):
+		p.err = fmt.Errorf("failed to read answer: %w", err)
+		return current
+	}
+	if answer := strings.TrimSpace(line); answer != "" {
+		return answer
+	}
+	return current
+}
+
+// askInt asks for a number until the answer is one.
+func (p *prompter) askInt(question string, current int) int {
+	for p.err == nil {
+		answer := p.ask(question, strconv.Itoa(current))
+		if n, err := strconv.Atoi(answer); err == nil {
+			return n
+		}
+		fmt.Fprintf(p.out, "%q is not a number\n", answer)
+	}
+	return current
+}
+
+// askBool asks a yes or no question until the answer is one.
+func (p *prompter) askBool(question string, current bool) bool {
+	def := "n"
+	if current {
+		def = "y"
+	}
+	for p.err == nil {
+		switch strings.ToLower(p.ask(question+" (y/n)", def)) {
+		case "y", "yes":
+			return true
+		case "n", "no":
+			return false
+		}
+		fmt.Fprintln(p.out, "Answer y or n")
+	}
+	return current
+}
+
+// askSettings asks for every setting, offering the current values as defaults. The settings of the TLS mode
+// are asked only when they apply.
+func (p *prompter) askSettings(s Settings) (Settings, error) {
+	s.DBHost = p.ask("PostgreSQL host", s.DBHost)
+	s.DBPort = p.askInt("PostgreSQL port", s.DBPort)
+	s.DBUser = p.ask("PostgreSQL user", s.DBUser)
+	s.DBPassword = p.ask("PostgreSQL password", s.DBPassword)
+	s.DBName = p.ask("PostgreSQL database", s.DBName)
+	s.DBSSLMode = p.ask("PostgreSQL SSL mode", s.DBSSLMode)
+	s.AppPort = p.askInt("Application port", s.AppPort)
+	s.FileStoragePath = p.ask("File storage directory", s.FileStoragePath)
+	s.TLSMode = p.ask("TLS mode (files, acme, none)", s.TLSMode)
+	switch s.TLSMode {
+	case TLSModeFiles:
+		s.TLSCertFile = p.ask("TLS certificate file", s.TLSCertFile)
+		s.TLSKeyFile = p.ask("TLS private key file", s.TLSKeyFile)
+	case TLSModeACME:
+		s.ACMEDomains = p.ask("ACME domains (comma-separated)", s.ACMEDomains)
+		s.ACMEEmail = p.ask("ACME contact e-mail", s.ACMEEmail)
+	}
+	s.AdminAPI = p.askBool("Enable the admin API", s.AdminAPI)
+	if p.err != nil {
+		return Settings{}, p.err
+	}
+	return s, nil
+}
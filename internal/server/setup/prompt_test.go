@@ -0,0 +1,67 @@
+package setup
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrompter_AskSettings(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		wantErr error
+		modify  func(s *Settings)
+		name    string
+		answers string
+	}{
+		{
+			name:    "defaults kept",
+			answers: strings.Repeat("\n", 13),
+			modify:  func(*Settings) {},
+		},
+		{
+			name: "answers replace defaults",
+			answers: "db.internal\n6432\navk\nsecret\nvault\nrequire\n8443\n/srv/files\n" +
+				"acme\nvault.example.com\nops@example.com\nn\n",
+			modify: func(s *Settings) {
+				s.DBHost, s.DBPort, s.DBUser, s.DBPassword = "db.internal", 6432, "avk", "secret"
+				s.DBName, s.DBSSLMode, s.AppPort, s.FileStoragePath = "vault", "require", 8443, "/srv/files"
+				s.TLSMode, s.ACMEDomains, s.ACMEEmail, s.AdminAPI = TLSModeACME, "vault.example.com",
+					"ops@example.com", false
+			},
+		},
+		{
+			name:    "invalid answers asked again",
+			answers: "\nfive\n5433\n\n\n\n\n\n\nnone\nmaybe\nyes\n",
+			modify:  func(s *Settings) { s.DBPort, s.TLSMode = 5433, TLSModeNone },
+		},
+		{
+			name:    "input ends early",
+			answers: "db.internal\n",
+			wantErr: io.ErrUnexpectedEOF,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// out collects the questions.
+			var out strings.Builder
+			got, err := newPrompter(strings.NewReader(tt.answers), &out).askSettings(validSettings())
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			want := validSettings()
+			tt.modify(&want)
+			assert.Equal(t, want, got)
+			assert.Contains(t, out.String(), "PostgreSQL host [localhost]: ")
+		})
+	}
+}
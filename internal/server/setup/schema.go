@@ -0,0 +1,149 @@
+package setup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// migrationSuffix ends the names of the files migrating the schema up.
+const migrationSuffix = ".up.sql"
+
+// ErrDirtySchema is returned when a previous migration failed halfway and the schema needs manual repair.
+var ErrDirtySchema = errors.New("database schema is dirty")
+
+// migration is a file migrating the schema up to its version.
+type migration struct {
+	// name is the name of the SQL file.
+	name string
+	// version is the number the file name starts with.
+	version uint64
+}
+
+// SchemaResult describes the migrations applied to the database.
+type SchemaResult struct {
+	// Applied lists the names of the applied migration files in order.
+	Applied []string
+	// From is the schema version before the migrations; zero for an empty database.
+	From uint64
+	// To is the schema version after the migrations.
+	To uint64
+}
+
+// listMigrations returns the up migrations at the root of the file system ordered by version.
+func listMigrations(fsys fs.FS) ([]migration, error) {
+	names, err := fs.Glob(fsys, "*"+migrationSuffix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list migrations: %w", err)
+	}
+	if len(names) == 0 {
+		return nil, errors.New("no migrations found")
+	}
+
+	migrations := make([]migration, 0, len(names))
+	// seen maps versions to the files declaring them.
+	seen := make(map[uint64]string, len(names))
+	for _, name := range names {
+		prefix, _, _ := strings.Cut(name, "_")
+		version, err := strconv.ParseUint(prefix, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("migration %s does not start with a version number", name)
+		}
+		if other, ok := seen[version]; ok {
+			return nil, fmt.Errorf("migrations %s and %s share version %d", other, name, version)
+		}
+		seen[version] = name
+		migrations = append(migrations, migration{name: name, version: version})
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].version < migrations[j].version })
+	return migrations, nil
+}
+
+// MigrateSchema applies the up migrations of the file system newer than the schema version of the database.
+// The version is kept in the schema_migrations table of the migrate tool and marked dirty while a migration
+// runs, so the tool and this function can take turns on the same database.
+func MigrateSchema(ctx context.Context, dsn string, fsys fs.FS) (*SchemaResult, error) {
+	migrations, err := listMigrations(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := pgx.Connect(ctx, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer func() { _ = conn.Close(context.WithoutCancel(ctx)) }()
+
+	if _, err := conn.Exec(ctx,
+		`CREATE TABLE IF NOT EXISTS schema_migrations (version bigint NOT NULL PRIMARY KEY, dirty boolean NOT NULL)`,
+	); err != nil {
+		return nil, fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+	// current holds the schema version of the database.
+	var current int64
+	// dirty reports whether the last migration failed halfway.
+	var dirty bool
+	err = conn.QueryRow(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&current, &dirty)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	if dirty {
+		return nil, fmt.Errorf("%w at version %d: repair it and mark the version clean", ErrDirtySchema, current)
+	}
+
+	res := &SchemaResult{From: uint64(current), To: uint64(current)}
+	for _, m := range migrations {
+		if m.version <= res.To {
+			continue
+		}
+		if err := applyMigration(ctx, conn, fsys, m); err != nil {
+			return res, err
+		}
+		res.Applied = append(res.Applied, m.name)
+		res.To = m.version
+	}
+	return res, nil
+}
+
+// applyMigration runs the migration between marking its version dirty and clean.
+func applyMigration(ctx context.Context, conn *pgx.Conn, fsys fs.FS, m migration) error {
+	sql, err := fs.ReadFile(fsys, m.name)
+	if err != nil {
+		return fmt.Errorf("failed to read migration: %w", err)
+	}
+	if err := setSchemaVersion(ctx, conn, m.version, true); err != nil {
+		return err
+	}
+	if _, err := conn.Exec(ctx, string(sql)); err != nil {
+		return fmt.Errorf("failed to apply %s: %w", m.name, err)
+	}
+	return setSchemaVersion(ctx, conn, m.version, false)
+}
+
+// setSchemaVersion replaces the recorded schema version.
+func setSchemaVersion(ctx context.Context, conn *pgx.Conn, version uint64, dirty bool) error {
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin schema version update: %w", err)
+	}
+	defer func() { _ = tx.Rollback(context.WithoutCancel(ctx)) }()
+
+	if _, err := tx.Exec(ctx, `TRUNCATE schema_migrations`); err != nil {
+		return fmt.Errorf("failed to clear schema version: %w", err)
+	}
+	if _, err := tx.Exec(ctx,
+		`INSERT INTO schema_migrations (version, dirty) VALUES ($1, $2)`, int64(version), dirty,
+	); err != nil {
+		return fmt.Errorf("failed to record schema version: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit schema version: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package setup
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListMigrations(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		files        []string
+		wantVersions []uint64
+		wantErr      bool
+	}{
+		{
+			name:         "ordered by version",
+			files:        []string{"000010_b.up.sql", "000002_a.up.sql", "000002_a.down.sql", "README.md"},
+			wantVersions: []uint64{2, 10},
+		},
+		{name: "no migrations", files: []string{"000001_a.down.sql"}, wantErr: true},
+		{name: "no version", files: []string{"init.up.sql"}, wantErr: true},
+		{name: "shared version", files: []string{"000001_a.up.sql", "1_b.up.sql"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			fsys := fstest.MapFS{}
+			for _, f := range tt.files {
+				fsys[f] = &fstest.MapFile{Data: []byte("SELECT 1;")}
+			}
+
+			migrations, err := listMigrations(fsys)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			// versions collects the versions of the listed migrations.
+			versions := make([]uint64, 0, len(migrations))
+			for _, m := range migrations {
+				versions = append(versions, m.version)
+			}
+			assert.Equal(t, tt.wantVersions, versions)
+		})
+	}
+}
+
+func TestListMigrations_Embedded(t *testing.T) {
+	t.Parallel()
+
+	migrations, err := listMigrations(Options{}.migrations())
+	require.NoError(t, err)
+	for i, m := range migrations {
+		assert.Equal(t, uint64(i+1), m.version, "migration versions have no gaps")
+	}
+}
@@ -0,0 +1,46 @@
+package setup
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+)
+
+const (
+	// masterKeyBytes specifies the random bytes of the master key; its base64url form has 64 characters.
+	masterKeyBytes = 48
+	// adminTokenBytes specifies the random bytes of the admin API token; its base64url form has 43 characters.
+	adminTokenBytes = 32
+)
+
+// GenerateSecrets generates the secrets of a new server from the system random source. The admin API token is
+// generated only when the admin API is enabled.
+func GenerateSecrets(adminAPI bool) (Secrets, error) {
+	// secrets collects the generated secrets.
+	var secrets Secrets
+	var err error
+	if secrets.MasterKey, err = randomToken(masterKeyBytes); err != nil {
+		return Secrets{}, fmt.Errorf("failed to generate master key: %w", err)
+	}
+	if adminAPI {
+		if secrets.AdminAPIToken, err = randomToken(adminTokenBytes); err != nil {
+			return Secrets{}, fmt.Errorf("failed to generate admin API token: %w", err)
+		}
+	}
+	seed := make([]byte, ed25519.SeedSize)
+	if _, err := rand.Read(seed); err != nil {
+		return Secrets{}, fmt.Errorf("failed to generate response signing key: %w", err)
+	}
+	secrets.ResponseSigningKey = base64.StdEncoding.EncodeToString(seed)
+	return secrets, nil
+}
+
+// randomToken returns n random bytes encoded as unpadded base64url, which is safe in environment files.
+func randomToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
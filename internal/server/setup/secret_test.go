@@ -0,0 +1,44 @@
+package setup
+
+import (
+	"testing"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateSecrets(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		adminAPI  bool
+		wantToken bool
+	}{
+		{name: "admin api enabled", adminAPI: true, wantToken: true},
+		{name: "admin api disabled"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			secrets, err := GenerateSecrets(tt.adminAPI)
+			require.NoError(t, err)
+			assert.Len(t, secrets.MasterKey, 64)
+			if tt.wantToken {
+				assert.Len(t, secrets.AdminAPIToken, 43)
+			} else {
+				assert.Empty(t, secrets.AdminAPIToken)
+			}
+			_, err = crypto.ParseEd25519Seed(secrets.ResponseSigningKey)
+			require.NoError(t, err)
+
+			other, err := GenerateSecrets(tt.adminAPI)
+			require.NoError(t, err)
+			assert.NotEqual(t, secrets.MasterKey, other.MasterKey)
+			assert.NotEqual(t, secrets.ResponseSigningKey, other.ResponseSigningKey)
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package setup
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// TLS modes of the server.
+const (
+	// TLSModeFiles serves HTTPS with the certificate and key files.
+	TLSModeFiles = "files"
+	// TLSModeACME serves HTTPS with certificates obtained via ACME for the domains.
+	TLSModeACME = "acme"
+	// TLSModeNone serves plain HTTP, for servers behind a TLS-terminating reverse proxy.
+	TLSModeNone = "none"
+)
+
+// ErrInvalidSettings is returned when a deployment setting would be rejected by the server.
+var ErrInvalidSettings = errors.New("invalid settings")
+
+// Settings holds the deployment settings written to the environment file.
+type Settings struct {
+	// DBHost specifies the PostgreSQL host.
+	DBHost string
+	// DBUser specifies the PostgreSQL user.
+	DBUser string
+	// DBPassword specifies the password of the PostgreSQL user.
+	DBPassword string
+	// DBName specifies the PostgreSQL database.
+	DBName string
+	// DBSSLMode specifies the PostgreSQL SSL mode.
+	DBSSLMode string
+	// FileStoragePath specifies the directory of uploaded files.
+	FileStoragePath string
+	// TLSMode selects how the server serves HTTPS: files, acme or none.
+	TLSMode string
+	// TLSCertFile specifies the certificate file of the files TLS mode.
+	TLSCertFile string
+	// TLSKeyFile specifies the private key file of the files TLS mode.
+	TLSKeyFile string
+	// ACMEDomains lists the comma-separated host names of the acme TLS mode.
+	ACMEDomains string
+	// ACMEEmail specifies the ACME account contact address.
+	ACMEEmail string
+	// DBPort specifies the PostgreSQL port.
+	DBPort int
+	// AppPort specifies the port the server listens on.
+	AppPort int
+	// AdminAPI determines whether an admin API token is generated, enabling the admin API.
+	AdminAPI bool
+}
+
+// DefaultSettings returns the settings of a server on the local host.
+func DefaultSettings() Settings {
+	return Settings{
+		DBHost:          "localhost",
+		DBPort:          5432,
+		DBUser:          "postgres",
+		DBName:          "aegis_vault_keeper",
+		DBSSLMode:       "disable",
+		AppPort:         8080,
+		FileStoragePath: "/app/filestorage",
+		TLSMode:         TLSModeFiles,
+		TLSCertFile:     "/app/certs/server.pem",
+		TLSKeyFile:      "/app/certs/server-key.pem",
+		AdminAPI:        true,
+	}
+}
+
+// Secrets holds the generated secrets of the server.
+type Secrets struct {
+	// MasterKey is the key all encryption and token signing keys of the server are derived from.
+	MasterKey string
+	// AdminAPIToken authorizes administrative requests; empty when the admin API stays disabled.
+	AdminAPIToken string
+	// ResponseSigningKey is the base64-encoded Ed25519 seed signing responses of critical endpoints.
+	ResponseSigningKey string
+}
+
+// Variable is an environment variable of the server.
+type Variable struct {
+	// Name is the name of the variable.
+	Name string
+	// Value is the value of the variable.
+	Value string
+}
+
+// check reports the first setting the server would reject.
+func (s Settings) check() error {
+	switch {
+	case s.DBHost == "":
+		return fmt.Errorf("%w: database host is required", ErrInvalidSettings)
+	case s.DBPort < 1 || s.DBPort > 65535:
+		return fmt.Errorf("%w: database port must be between 1 and 65535", ErrInvalidSettings)
+	case s.DBUser == "":
+		return fmt.Errorf("%w: database user is required", ErrInvalidSettings)
+	case s.DBPassword == "":
+		return fmt.Errorf("%w: database password is required", ErrInvalidSettings)
+	case s.DBName == "":
+		return fmt.Errorf("%w: database name is required", ErrInvalidSettings)
+	case s.AppPort < 1 || s.AppPort > 65535:
+		return fmt.Errorf("%w: application port must be between 1 and 65535", ErrInvalidSettings)
+	case s.FileStoragePath == "":
+		return fmt.Errorf("%w: file storage directory is required", ErrInvalidSettings)
+	}
+	switch s.TLSMode {
+	case TLSModeFiles, TLSModeNone:
+		return nil
+	case TLSModeACME:
+		if strings.TrimSpace(s.ACMEDomains) == "" {
+			return fmt.Errorf("%w: ACME domains are required in the acme TLS mode", ErrInvalidSettings)
+		}
+		return nil
+	default:
+		return fmt.Errorf("%w: unknown TLS mode %q, expected files, acme or none", ErrInvalidSettings, s.TLSMode)
+	}
+}
+
+// Env returns the environment variables of the settings and secrets in the order they are written.
+func (s Settings) Env(secrets Secrets) []Variable {
+	vars := []Variable{
+		{Name: "POSTGRES_USER", Value: s.DBUser},
+		{Name: "POSTGRES_PASSWORD", Value: s.DBPassword},
+		{Name: "POSTGRES_DB_NAME", Value: s.DBName},
+		{Name: "POSTGRES_HOST", Value: s.DBHost},
+		{Name: "POSTGRES_PORT", Value: strconv.Itoa(s.DBPort)},
+		{Name: "POSTGRES_SSL_MODE", Value: s.DBSSLMode},
+		{Name: "APPLICATION_PORT", Value: strconv.Itoa(s.AppPort)},
+		{Name: "MASTER_KEY", Value: secrets.MasterKey},
+		{Name: "FILE_STORAGE_BASE_PATH", Value: s.FileStoragePath},
+		{Name: "TLS_ENABLED", Value: strconv.FormatBool(s.TLSMode != TLSModeNone)},
+	}
+	switch s.TLSMode {
+	case TLSModeFiles:
+		vars = append(vars,
+			Variable{Name: "TLS_CERT_FILE", Value: s.TLSCertFile},
+			Variable{Name: "TLS_KEY_FILE", Value: s.TLSKeyFile},
+		)
+	case TLSModeACME:
+		vars = append(vars,
+			Variable{Name: "TLS_ACME_DOMAINS", Value: s.ACMEDomains},
+			Variable{Name: "TLS_ACME_EMAIL", Value: s.ACMEEmail},
+		)
+	}
+	return append(vars,
+		Variable{Name: "ADMIN_API_TOKEN", Value: secrets.AdminAPIToken},
+		Variable{Name: "RESPONSE_SIGNING_KEY", Value: secrets.ResponseSigningKey},
+	)
+}
+
+// DSN returns the PostgreSQL connection URL of the settings.
+func (s Settings) DSN() string {
+	u := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(s.DBUser, s.DBPassword),
+		Host:     net.JoinHostPort(s.DBHost, strconv.Itoa(s.DBPort)),
+		Path:     "/" + s.DBName,
+		RawQuery: url.Values{"sslmode": {s.DBSSLMode}}.Encode(),
+	}
+	return u.String()
+}
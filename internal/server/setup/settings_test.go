@@ -0,0 +1,136 @@
+package setup
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func validSettings() Settings {
+	s := DefaultSettings()
+	s.DBPassword = "postgres-password"
+	return s
+}
+
+func TestSettings_Check(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		modify  func(s *Settings)
+		name    string
+		wantErr bool
+	}{
+		{name: "defaults with password", modify: func(*Settings) {}},
+		{name: "no database password", modify: func(s *Settings) { s.DBPassword = "" }, wantErr: true},
+		{name: "no database host", modify: func(s *Settings) { s.DBHost = "" }, wantErr: true},
+		{name: "database port out of range", modify: func(s *Settings) { s.DBPort = 70000 }, wantErr: true},
+		{name: "application port zero", modify: func(s *Settings) { s.AppPort = 0 }, wantErr: true},
+		{name: "no file storage", modify: func(s *Settings) { s.FileStoragePath = "" }, wantErr: true},
+		{name: "plain http", modify: func(s *Settings) { s.TLSMode = TLSModeNone }},
+		{
+			name:   "acme with domains",
+			modify: func(s *Settings) { s.TLSMode, s.ACMEDomains = TLSModeACME, "vault.example.com" },
+		},
+		{name: "acme without domains", modify: func(s *Settings) { s.TLSMode = TLSModeACME }, wantErr: true},
+		{name: "unknown tls mode", modify: func(s *Settings) { s.TLSMode = "self-signed" }, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := validSettings()
+			tt.modify(&s)
+			err := s.check()
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidSettings)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestSettings_Env(t *testing.T) {
+	t.Parallel()
+
+	secrets := Secrets{MasterKey: "master", AdminAPIToken: "admin", ResponseSigningKey: "seed"}
+
+	tests := []struct {
+		modify   func(s *Settings)
+		want     map[string]string
+		absent   []string
+		name     string
+		wantVars int
+	}{
+		{
+			name:   "certificate files",
+			modify: func(*Settings) {},
+			want: map[string]string{
+				"TLS_ENABLED":          "true",
+				"TLS_CERT_FILE":        "/app/certs/server.pem",
+				"MASTER_KEY":           "master",
+				"ADMIN_API_TOKEN":      "admin",
+				"RESPONSE_SIGNING_KEY": "seed",
+				"POSTGRES_PORT":        "5432",
+			},
+			absent:   []string{"TLS_ACME_DOMAINS"},
+			wantVars: 14,
+		},
+		{
+			name:     "acme",
+			modify:   func(s *Settings) { s.TLSMode, s.ACMEDomains = TLSModeACME, "vault.example.com" },
+			want:     map[string]string{"TLS_ENABLED": "true", "TLS_ACME_DOMAINS": "vault.example.com"},
+			absent:   []string{"TLS_CERT_FILE"},
+			wantVars: 14,
+		},
+		{
+			name:     "plain http",
+			modify:   func(s *Settings) { s.TLSMode = TLSModeNone },
+			want:     map[string]string{"TLS_ENABLED": "false"},
+			absent:   []string{"TLS_CERT_FILE", "TLS_ACME_DOMAINS"},
+			wantVars: 12,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := validSettings()
+			tt.modify(&s)
+			vars := s.Env(secrets)
+			assert.Len(t, vars, tt.wantVars)
+			// got maps the variable names to their values.
+			got := make(map[string]string, len(vars))
+			for _, v := range vars {
+				got[v.Name] = v.Value
+			}
+			for name, value := range tt.want {
+				assert.Equal(t, value, got[name], name)
+			}
+			for _, name := range tt.absent {
+				assert.NotContains(t, got, name)
+			}
+		})
+	}
+}
+
+func TestSettings_DSN(t *testing.T) {
+	t.Parallel()
+
+	s := validSettings()
+	s.DBPassword = "p@ss word/#"
+	s.DBHost = "::1"
+
+	u, err := url.Parse(s.DSN())
+	require.NoError(t, err)
+	password, _ := u.User.Password()
+	assert.Equal(t, "p@ss word/#", password)
+	assert.Equal(t, "postgres", u.User.Username())
+	assert.Equal(t, "[::1]:5432", u.Host)
+	assert.Equal(t, "/aegis_vault_keeper", u.Path)
+	assert.Equal(t, "disable", u.Query().Get("sslmode"))
+}
@@ -0,0 +1,207 @@
+package setup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/config"
+	"github.com/gdyunin/aegis-vault-keeper/migrations"
+	"github.com/spf13/viper"
+)
+
+// envFileMode restricts the environment file to its owner, because it holds the secrets of the server.
+const envFileMode = 0o600
+
+// ErrEnvFileExists is returned when the environment file exists and may not be replaced.
+var ErrEnvFileExists = errors.New("environment file already exists")
+
+// Options configures a setup run.
+type Options struct {
+	// In reads the answers to the questions; nil uses the settings as they are without asking.
+	In io.Reader
+	// Out receives the questions.
+	Out io.Writer
+	// Migrations holds the database migrations; nil uses the migrations embedded in the binary.
+	Migrations fs.FS
+	// Settings holds the initial settings, offered as defaults when asking.
+	Settings Settings
+	// EnvFile specifies the environment file to write.
+	EnvFile string
+	// ConfigDir specifies the directory of the server.yml file the configuration is checked with.
+	ConfigDir string
+	// Force determines whether an existing environment file is replaced.
+	Force bool
+	// SkipSchema determines whether creating the database schema is skipped.
+	SkipSchema bool
+}
+
+// Result describes a completed setup run.
+type Result struct {
+	// Schema describes the applied migrations; nil when creating the schema was skipped.
+	Schema *SchemaResult
+	// EnvFile is the written environment file.
+	EnvFile string
+	// Settings holds the settings written to the environment file.
+	Settings Settings
+}
+
+// migrations returns the database migrations the run applies.
+func (o Options) migrations() fs.FS {
+	if o.Migrations != nil {
+		return o.Migrations
+	}
+	return migrations.FS
+}
+
+// Run prepares a new installation: it asks for the settings, generates the secrets, checks the configuration
+// the way the server loads it, creates the database schema and writes the environment file. The file is written
+// last, so a failed run leaves no configuration behind and can be repeated.
+func Run(ctx context.Context, opts Options) (*Result, error) {
+	if !opts.Force {
+		if _, err := os.Stat(opts.EnvFile); err == nil {
+			return nil, fmt.Errorf("%w: %s", ErrEnvFileExists, opts.EnvFile)
+		}
+	}
+
+	settings := opts.Settings
+	if opts.In != nil {
+		var err error
+		if settings, err = newPrompter(opts.In, opts.Out).askSettings(settings); err != nil {
+			return nil, err
+		}
+	}
+	if err := settings.check(); err != nil {
+		return nil, err
+	}
+
+	secrets, err := GenerateSecrets(settings.AdminAPI)
+	if err != nil {
+		return nil, err
+	}
+	vars := settings.Env(secrets)
+	if err := validateConfig(opts.ConfigDir, vars); err != nil {
+		return nil, err
+	}
+
+	res := &Result{EnvFile: opts.EnvFile, Settings: settings}
+	if !opts.SkipSchema {
+		if res.Schema, err = MigrateSchema(ctx, settings.DSN(), opts.migrations()); err != nil {
+			return nil, fmt.Errorf("failed to create database schema: %w", err)
+		}
+	}
+	if err := writeEnvFile(opts.EnvFile, vars, opts.Force); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// validateConfig loads the server configuration from server.yml of the directory and the variables, so the
+// settings are checked by the same rules as on startup.
+func validateConfig(configDir string, vars []Variable) error {
+	viper.Reset()
+	defer viper.Reset()
+	viper.AddConfigPath(configDir)
+
+	for _, v := range vars {
+		if err := os.Setenv(v.Name, v.Value); err != nil {
+			return fmt.Errorf("failed to set %s: %w", v.Name, err)
+		}
+	}
+	if _, err := config.LoadConfig(); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidSettings, err)
+	}
+	return nil
+}
+
+// writeEnvFile writes the variables to the environment file readable by its owner only. An existing file is
+// replaced only when forced.
+func writeEnvFile(path string, vars []Variable, force bool) error {
+	// b collects the content of the file.
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Generated by aegis_vault_keeper init on %s.\n", time.Now().UTC().Format(time.DateOnly))
+	b.WriteString("# Keep this file secret and backed up: data stored by the server cannot be decrypted without\n")
+	b.WriteString("# its MASTER_KEY, and the key cannot be changed once data is stored.\n")
+	for _, v := range vars {
+		if strings.ContainsAny(v.Value, "\r\n") {
+			return fmt.Errorf("%w: %s must not contain line breaks", ErrInvalidSettings, v.Name)
+		}
+		fmt.Fprintf(&b, "%s=%s\n", v.Name, v.Value)
+	}
+
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if !force {
+		flags |= os.O_EXCL
+	}
+	f, err := os.OpenFile(path, flags, envFileMode)
+	if errors.Is(err, os.ErrExist) {
+		return fmt.Errorf("%w: %s", ErrEnvFileExists, path)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create environment file: %w", err)
+	}
+	if _, err := f.WriteString(b.String()); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write environment file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to close environment file: %w", err)
+	}
+	return nil
+}
+
+// Write prints what the run did and the next steps of the installation.
+func (r *Result) Write(w io.Writer) error {
+	// b collects the report.
+	var b strings.Builder
+	fmt.Fprintf(&b, "Configuration written to %s\n", r.EnvFile)
+	switch {
+	case r.Schema == nil:
+		b.WriteString("Database schema creation skipped\n")
+	case len(r.Schema.Applied) == 0:
+		fmt.Fprintf(&b, "Database schema is up to date at version %d\n", r.Schema.To)
+	default:
+		fmt.Fprintf(&b, "Database schema migrated from version %d to %d (%d migrations)\n",
+			r.Schema.From, r.Schema.To, len(r.Schema.Applied))
+	}
+	if r.Schema != nil && r.Schema.From != 0 {
+		b.WriteString("The database had a schema already: if it holds data stored with another MASTER_KEY, " +
+			"restore the previous configuration\n")
+	}
+
+	b.WriteString("\nNext steps:\n")
+	fmt.Fprintf(&b, "  1. Back up %s in a safe place; its MASTER_KEY cannot be recovered.\n", r.EnvFile)
+	fmt.Fprintf(&b, "  2. Start the server with the variables of %s, e.g. EnvironmentFile= of a systemd unit "+
+		"or env_file of docker compose.\n", r.EnvFile)
+	fmt.Fprintf(&b, "  3. Open %s/api/onboarding to check the setup and create the first account.\n", r.baseURL())
+	if r.Settings.AdminAPI {
+		fmt.Fprintf(&b, "  4. Send ADMIN_API_TOKEN of %s in the X-Admin-Token header of admin requests.\n",
+			r.EnvFile)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// baseURL returns the address the server answers on once started.
+func (r *Result) baseURL() string {
+	s := r.Settings
+	scheme, host := "https", "localhost"
+	switch s.TLSMode {
+	case TLSModeNone:
+		scheme = "http"
+	case TLSModeACME:
+		domain, _, _ := strings.Cut(s.ACMEDomains, ",")
+		host = strings.TrimSpace(domain)
+	}
+	if s.AppPort == 443 && scheme == "https" || s.AppPort == 80 && scheme == "http" {
+		return scheme + "://" + host
+	}
+	return scheme + "://" + net.JoinHostPort(host, strconv.Itoa(s.AppPort))
+}
@@ -0,0 +1,140 @@
+package setup
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// repositoryConfigDir holds the server.yml of the repository.
+const repositoryConfigDir = "../../../config"
+
+func TestWriteEnvFile(t *testing.T) {
+	t.Parallel()
+
+	vars := []Variable{{Name: "MASTER_KEY", Value: "key"}, {Name: "ADMIN_API_TOKEN", Value: ""}}
+
+	t.Run("owner only", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), ".env")
+		require.NoError(t, writeEnvFile(path, vars, false))
+
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(envFileMode), info.Mode().Perm())
+		b, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(string(b), "# Generated by aegis_vault_keeper init"))
+		assert.Contains(t, string(b), "\nMASTER_KEY=key\nADMIN_API_TOKEN=\n")
+	})
+
+	t.Run("existing file kept unless forced", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), ".env")
+		require.NoError(t, os.WriteFile(path, []byte("MASTER_KEY=old\n"), 0o600))
+
+		require.ErrorIs(t, writeEnvFile(path, vars, false), ErrEnvFileExists)
+		require.NoError(t, writeEnvFile(path, vars, true))
+		b, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.NotContains(t, string(b), "old")
+	})
+
+	t.Run("line break rejected", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), ".env")
+		err := writeEnvFile(path, []Variable{{Name: "POSTGRES_PASSWORD", Value: "a\nMASTER_KEY=b"}}, false)
+		require.ErrorIs(t, err, ErrInvalidSettings)
+		assert.NoFileExists(t, path)
+	})
+}
+
+// Run checks the configuration through the process environment and the global viper instance, so its tests
+// do not run in parallel.
+func TestRun(t *testing.T) {
+	tests := []struct {
+		wantErr   error
+		modify    func(opts *Options)
+		name      string
+		wantLines []string
+	}{
+		{
+			name:   "plain http without asking",
+			modify: func(*Options) {},
+			wantLines: []string{
+				"Database schema creation skipped",
+				"Open http://localhost:8080/api/onboarding",
+				"Send ADMIN_API_TOKEN",
+			},
+		},
+		{
+			name: "answers asked",
+			modify: func(opts *Options) {
+				opts.In = strings.NewReader(strings.Repeat("\n", 8) + "acme\nvault.example.com\n\nn\n")
+				opts.Out = &strings.Builder{}
+			},
+			wantLines: []string{"Open https://vault.example.com:8080/api/onboarding"},
+		},
+		{
+			name:    "missing certificate files",
+			modify:  func(opts *Options) { opts.Settings.TLSMode = TLSModeFiles },
+			wantErr: ErrInvalidSettings,
+		},
+		{
+			name:    "missing password",
+			modify:  func(opts *Options) { opts.Settings.DBPassword = "" },
+			wantErr: ErrInvalidSettings,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Restore the variables Run sets once the test ends.
+			for _, v := range validSettings().Env(Secrets{}) {
+				t.Setenv(v.Name, os.Getenv(v.Name))
+			}
+			t.Setenv("TLS_ACME_DOMAINS", "")
+			t.Setenv("TLS_ACME_EMAIL", "")
+
+			settings := validSettings()
+			settings.TLSMode = TLSModeNone
+			settings.TLSCertFile = filepath.Join(t.TempDir(), "missing.pem")
+			opts := Options{
+				Settings:   settings,
+				EnvFile:    filepath.Join(t.TempDir(), ".env"),
+				ConfigDir:  repositoryConfigDir,
+				SkipSchema: true,
+			}
+			tt.modify(&opts)
+
+			res, err := Run(context.Background(), opts)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.NoFileExists(t, opts.EnvFile)
+				return
+			}
+			require.NoError(t, err)
+			b, err := os.ReadFile(opts.EnvFile)
+			require.NoError(t, err)
+			assert.Regexp(t, `\nMASTER_KEY=[A-Za-z0-9_-]{64}\n`, string(b))
+
+			// report collects the summary of the run.
+			var report strings.Builder
+			require.NoError(t, res.Write(&report))
+			for _, line := range tt.wantLines {
+				assert.Contains(t, report.String(), line)
+			}
+
+			_, err = Run(context.Background(), opts)
+			require.ErrorIs(t, err, ErrEnvFileExists, "a second run keeps the generated secrets")
+		})
+	}
+}
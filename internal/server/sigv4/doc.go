@@ -0,0 +1,5 @@
+// Package sigv4 signs HTTP requests to AWS and S3-compatible services for the AegisVaultKeeper server.
+//
+// This package implements AWS Signature Version 4: the canonical request is built from the method, the path
+// and query of the request URL and the signed headers, and the signature is added as the Authorization header.
+package sigv4
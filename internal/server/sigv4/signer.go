@@ -0,0 +1,159 @@
+package sigv4
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/crypto"
+)
+
+// EmptyPayloadHash is the SHA-256 checksum of an empty request body.
+const EmptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// algorithm is the signing algorithm name used in the string to sign and the Authorization header.
+const algorithm = "AWS4-HMAC-SHA256"
+
+// s3Service is the name of the S3 service, whose canonical URI is not encoded twice.
+const s3Service = "s3"
+
+// Credentials contains the access key used for request signing.
+type Credentials struct {
+	// AccessKeyID is the access key identifier.
+	AccessKeyID string
+	// SecretAccessKey is the secret key (sensitive data).
+	SecretAccessKey string
+}
+
+// Signer adds AWS Signature Version 4 authorization headers to requests of one service in one region.
+type Signer struct {
+	// creds contains the signing access key.
+	creds Credentials
+	// service is the signing service name, e.g. s3 or iam.
+	service string
+	// region is the signing region, e.g. us-east-1.
+	region string
+	// signedHeaders lists the lowercase names of the signed headers in sorted order.
+	signedHeaders []string
+}
+
+// NewSigner creates a new Signer for the service and region.
+// The host and x-amz-date headers are always signed in addition to the listed headers.
+func NewSigner(service, region string, creds Credentials, signedHeaders ...string) *Signer {
+	headers := []string{"host", "x-amz-date"}
+	for _, h := range signedHeaders {
+		headers = append(headers, strings.ToLower(h))
+	}
+	slices.Sort(headers)
+	return &Signer{
+		creds:         creds,
+		service:       service,
+		region:        region,
+		signedHeaders: slices.Compact(headers),
+	}
+}
+
+// PayloadHash returns the hex-encoded SHA-256 checksum of the request body.
+func PayloadHash(payload []byte) string {
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:])
+}
+
+// Sign sets the X-Amz-Date and Authorization headers of the request signed at the time t.
+// The signed headers other than host and x-amz-date must be set on the request beforehand.
+func (s *Signer) Sign(req *http.Request, payloadHash string, t time.Time) {
+	t = t.UTC()
+	amzDate := t.Format("20060102T150405Z")
+	date := t.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+
+	signedHeaders := strings.Join(s.signedHeaders, ";")
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		s.canonicalURI(req.URL),
+		canonicalQuery(req.URL),
+		s.canonicalHeaders(req),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + s.region + "/" + s.service + "/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := algorithm + "\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := crypto.SignHMACSHA256([]byte("AWS4"+s.creds.SecretAccessKey), []byte(date))
+	for _, part := range []string{s.region, s.service, "aws4_request"} {
+		key = crypto.SignHMACSHA256(key, []byte(part))
+	}
+	signature := hex.EncodeToString(crypto.SignHMACSHA256(key, []byte(stringToSign)))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		algorithm, s.creds.AccessKeyID, scope, signedHeaders, signature,
+	))
+}
+
+// canonicalURI returns the URI-encoded path of the request URL.
+// Services other than S3 expect every path segment to be encoded twice.
+func (s *Signer) canonicalURI(u *url.URL) string {
+	path := u.EscapedPath()
+	if path == "" {
+		return "/"
+	}
+	if s.service == s3Service {
+		return path
+	}
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		segments[i] = uriEncode(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// canonicalHeaders returns the signed headers of the request as lowercase name:value lines.
+func (s *Signer) canonicalHeaders(req *http.Request) string {
+	var b strings.Builder
+	for _, name := range s.signedHeaders {
+		value := req.Header.Get(name)
+		if name == "host" {
+			value = req.Host
+			if value == "" {
+				value = req.URL.Host
+			}
+		}
+		b.WriteString(name + ":" + strings.Join(strings.Fields(value), " ") + "\n")
+	}
+	return b.String()
+}
+
+// canonicalQuery returns the query parameters of the request URL sorted by name and value.
+func canonicalQuery(u *url.URL) string {
+	query := u.Query()
+	params := make([][2]string, 0, len(query))
+	for name, values := range query {
+		for _, value := range values {
+			params = append(params, [2]string{uriEncode(name), uriEncode(value)})
+		}
+	}
+	slices.SortFunc(params, func(a, b [2]string) int {
+		if c := strings.Compare(a[0], b[0]); c != 0 {
+			return c
+		}
+		return strings.Compare(a[1], b[1])
+	})
+	pairs := make([]string, len(params))
+	for i, p := range params {
+		pairs[i] = p[0] + "=" + p[1]
+	}
+	return strings.Join(pairs, "&")
+}
+
+// uriEncode percent-encodes every byte of s except the unreserved characters A-Z, a-z, 0-9, '-', '.', '_' and '~'.
+func uriEncode(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
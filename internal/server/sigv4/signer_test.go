@@ -0,0 +1,133 @@
+package sigv4
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// exampleCredentials is the access key of the AWS Signature Version 4 test suite.
+var exampleCredentials = Credentials{
+	AccessKeyID:     "AKIDEXAMPLE",
+	SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+}
+
+func TestSigner_Sign(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		headers       map[string]string
+		signedHeaders []string
+		name          string
+		service       string
+		url           string
+		wantAuth      string
+	}{
+		{
+			name:    "get vanilla",
+			service: "service",
+			url:     "https://example.amazonaws.com/",
+			wantAuth: "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, " +
+				"SignedHeaders=host;x-amz-date, " +
+				"Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31",
+		},
+		{
+			name:    "query parameters sorted by name",
+			service: "service",
+			url:     "https://example.amazonaws.com/?Param2=value2&Param1=value1",
+			wantAuth: "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, " +
+				"SignedHeaders=host;x-amz-date, " +
+				"Signature=b97d918cfa904a5beff61c982a1b6f458b799221646efd99d3219ec94cdf2500",
+		},
+		{
+			name:          "iam query with content type",
+			service:       "iam",
+			url:           "https://iam.amazonaws.com/?Action=ListUsers&Version=2010-05-08",
+			headers:       map[string]string{"Content-Type": "application/x-www-form-urlencoded; charset=utf-8"},
+			signedHeaders: []string{"Content-Type"},
+			wantAuth: "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/iam/aws4_request, " +
+				"SignedHeaders=content-type;host;x-amz-date, " +
+				"Signature=5d672d79c15b13162d9279b0855cfba6789a8edb4c82c400e06b5924a6f2b5d7",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req, err := http.NewRequest(http.MethodGet, tt.url, http.NoBody)
+			require.NoError(t, err)
+			for name, value := range tt.headers {
+				req.Header.Set(name, value)
+			}
+
+			s := NewSigner(tt.service, "us-east-1", exampleCredentials, tt.signedHeaders...)
+			s.Sign(req, EmptyPayloadHash, time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC))
+
+			assert.Equal(t, "20150830T123600Z", req.Header.Get("X-Amz-Date"))
+			assert.Equal(t, tt.wantAuth, req.Header.Get("Authorization"))
+		})
+	}
+}
+
+func TestSigner_canonicalURI(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		service string
+		url     string
+		want    string
+	}{
+		{name: "empty path", service: "iam", url: "https://iam.amazonaws.com", want: "/"},
+		{name: "root path", service: "iam", url: "https://iam.amazonaws.com/", want: "/"},
+		{name: "s3 encoded once", service: "s3", url: "https://s3.test/bucket/a%20b", want: "/bucket/a%20b"},
+		{name: "other services encoded twice", service: "iam", url: "https://iam.test/a%20b/c", want: "/a%2520b/c"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			u, err := url.Parse(tt.url)
+			require.NoError(t, err)
+			s := NewSigner(tt.service, "us-east-1", exampleCredentials)
+			assert.Equal(t, tt.want, s.canonicalURI(u))
+		})
+	}
+}
+
+func TestCanonicalQuery(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		url  string
+		want string
+	}{
+		{name: "no query", url: "https://example.test/", want: ""},
+		{name: "sorted by name then value", url: "https://example.test/?b=2&a=2&a=1", want: "a=1&a=2&b=2"},
+		{name: "name prefix sorts first", url: "https://example.test/?a-b=1&a=2", want: "a=2&a-b=1"},
+		{name: "reserved characters encoded", url: "https://example.test/?k=a+b%2A~", want: "k=a%20b%2A~"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			u, err := url.Parse(tt.url)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, canonicalQuery(u))
+		})
+	}
+}
+
+func TestPayloadHash(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, EmptyPayloadHash, PayloadHash(nil))
+}
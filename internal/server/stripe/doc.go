@@ -0,0 +1,6 @@
+// Package stripe decodes Stripe webhooks for the AegisVaultKeeper server.
+//
+// This package verifies the Stripe-Signature header of webhook requests with the signing secret of the
+// endpoint and decodes the subscription events the billing integration applies to the plans of users.
+// It needs no Stripe SDK and makes no API calls.
+package stripe
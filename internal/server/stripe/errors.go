@@ -0,0 +1,11 @@
+package stripe
+
+import "errors"
+
+// Webhook error definitions.
+var (
+	// ErrSignatureInvalid indicates that the webhook signature is missing, malformed, expired or wrong.
+	ErrSignatureInvalid = errors.New("stripe webhook signature invalid")
+	// ErrEventInvalid indicates that the webhook payload is not a valid Stripe event.
+	ErrEventInvalid = errors.New("stripe event invalid")
+)
@@ -0,0 +1,132 @@
+package stripe
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Types of the subscription events applied to plans.
+const (
+	// EventSubscriptionCreated is sent when a customer subscribes.
+	EventSubscriptionCreated = "customer.subscription.created"
+	// EventSubscriptionUpdated is sent when a subscription changes its price, status or billing period.
+	EventSubscriptionUpdated = "customer.subscription.updated"
+	// EventSubscriptionDeleted is sent when a subscription ends.
+	EventSubscriptionDeleted = "customer.subscription.deleted"
+)
+
+// Event represents a Stripe webhook event.
+type Event struct {
+	// Created is the time Stripe created the event.
+	Created time.Time
+	// Subscription contains the subscription of subscription events; nil for other events.
+	Subscription *Subscription
+	// ID identifies the event; Stripe may deliver an event more than once.
+	ID string
+	// Type contains the event type, such as EventSubscriptionUpdated.
+	Type string
+}
+
+// Subscription represents the Stripe subscription carried by a subscription event.
+type Subscription struct {
+	// CurrentPeriodEnd is the end of the billing period paid for.
+	CurrentPeriodEnd time.Time
+	// Metadata contains the metadata set on the subscription, such as the user it belongs to.
+	Metadata map[string]string
+	// ID identifies the subscription.
+	ID string
+	// CustomerID identifies the customer.
+	CustomerID string
+	// Status contains the subscription status, such as active or past_due.
+	Status string
+	// PriceIDs lists the prices of the subscription items.
+	PriceIDs []string
+}
+
+// rawEvent is the JSON representation of a Stripe event.
+type rawEvent struct {
+	// Data holds the object the event is about.
+	Data struct {
+		// Object contains the JSON of the object.
+		Object json.RawMessage `json:"object"`
+	} `json:"data"`
+	// ID identifies the event.
+	ID string `json:"id"`
+	// Type contains the event type.
+	Type string `json:"type"`
+	// Object names the kind of the object, "event" for events.
+	Object string `json:"object"`
+	// Created contains the Unix time of the event.
+	Created int64 `json:"created"`
+}
+
+// rawSubscription is the JSON representation of a Stripe subscription.
+type rawSubscription struct {
+	// Metadata contains the metadata set on the subscription.
+	Metadata map[string]string `json:"metadata"`
+	// ID identifies the subscription.
+	ID string `json:"id"`
+	// Customer identifies the customer.
+	Customer string `json:"customer"`
+	// Status contains the subscription status.
+	Status string `json:"status"`
+	// Items holds the subscription items.
+	Items struct {
+		// Data lists the subscription items.
+		Data []struct {
+			// Price holds the price of the item.
+			Price struct {
+				// ID identifies the price.
+				ID string `json:"id"`
+			} `json:"price"`
+			// CurrentPeriodEnd contains the Unix end of the billing period of the item, set by API versions
+			// since 2025-03-31.
+			CurrentPeriodEnd int64 `json:"current_period_end"`
+		} `json:"data"`
+	} `json:"items"`
+	// CurrentPeriodEnd contains the Unix end of the billing period, set by API versions before 2025-03-31.
+	CurrentPeriodEnd int64 `json:"current_period_end"`
+}
+
+// decodeEvent decodes a Stripe event, with its subscription for subscription events.
+func decodeEvent(payload []byte) (*Event, error) {
+	// raw holds the decoded event JSON.
+	var raw rawEvent
+	if err := json.Unmarshal(payload, &raw); err != nil {
+		return nil, fmt.Errorf("failed to decode event: %w: %w", err, ErrEventInvalid)
+	}
+	if raw.Object != "event" || raw.ID == "" || raw.Type == "" {
+		return nil, fmt.Errorf("payload is not an event: %w", ErrEventInvalid)
+	}
+	e := &Event{ID: raw.ID, Type: raw.Type, Created: time.Unix(raw.Created, 0).UTC()}
+
+	switch raw.Type {
+	case EventSubscriptionCreated, EventSubscriptionUpdated, EventSubscriptionDeleted:
+	default:
+		return e, nil
+	}
+	// sub holds the decoded subscription JSON.
+	var sub rawSubscription
+	if err := json.Unmarshal(raw.Data.Object, &sub); err != nil {
+		return nil, fmt.Errorf("failed to decode subscription of event %s: %w: %w", raw.ID, err, ErrEventInvalid)
+	}
+	if sub.ID == "" {
+		return nil, fmt.Errorf("event %s carries no subscription: %w", raw.ID, ErrEventInvalid)
+	}
+	e.Subscription = &Subscription{
+		Metadata:   sub.Metadata,
+		ID:         sub.ID,
+		CustomerID: sub.Customer,
+		Status:     sub.Status,
+	}
+	periodEnd := sub.CurrentPeriodEnd
+	for _, item := range sub.Items.Data {
+		e.Subscription.PriceIDs = append(e.Subscription.PriceIDs, item.Price.ID)
+		periodEnd = max(periodEnd, item.CurrentPeriodEnd)
+	}
+	if periodEnd > 0 {
+		e.Subscription.CurrentPeriodEnd = time.Unix(periodEnd, 0).UTC()
+	}
+	return e, nil
+}
@@ -0,0 +1,78 @@
+package stripe
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/crypto"
+)
+
+// DefaultTolerance is the age of webhook signatures Stripe libraries accept by default.
+const DefaultTolerance = 5 * time.Minute
+
+// signatureScheme names the Stripe-Signature entries holding HMAC-SHA256 signatures.
+const signatureScheme = "v1"
+
+// ConstructEvent verifies the Stripe-Signature header of a webhook payload with the signing secret of the
+// endpoint and decodes the event. Signatures made more than tolerance before or after now are rejected,
+// so captured requests cannot be replayed later; a non-positive tolerance uses DefaultTolerance.
+func ConstructEvent(payload []byte, header, secret string, tolerance time.Duration, now time.Time) (*Event, error) {
+	if tolerance <= 0 {
+		tolerance = DefaultTolerance
+	}
+	timestamp, signatures, err := parseSignatureHeader(header)
+	if err != nil {
+		return nil, err
+	}
+	signedAt := time.Unix(timestamp, 0)
+	if signedAt.Before(now.Add(-tolerance)) || signedAt.After(now.Add(tolerance)) {
+		return nil, fmt.Errorf("signature timestamp outside the tolerance: %w", ErrSignatureInvalid)
+	}
+
+	signed := make([]byte, 0, len(payload)+21)
+	signed = strconv.AppendInt(signed, timestamp, 10)
+	signed = append(signed, '.')
+	signed = append(signed, payload...)
+	for _, sig := range signatures {
+		if crypto.VerifyHMACSHA256([]byte(secret), signed, sig) {
+			return decodeEvent(payload)
+		}
+	}
+	return nil, fmt.Errorf("no matching signature: %w", ErrSignatureInvalid)
+}
+
+// parseSignatureHeader parses a Stripe-Signature header of the form t=TIMESTAMP,v1=HEX[,v1=HEX...].
+// Several v1 signatures are sent while the signing secret is being rolled; other schemes are ignored.
+func parseSignatureHeader(header string) (int64, [][]byte, error) {
+	var (
+		timestamp  int64
+		signatures [][]byte
+	)
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "t":
+			t, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return 0, nil, fmt.Errorf("invalid signature timestamp: %w", ErrSignatureInvalid)
+			}
+			timestamp = t
+		case signatureScheme:
+			sig, err := hex.DecodeString(value)
+			if err != nil {
+				continue
+			}
+			signatures = append(signatures, sig)
+		}
+	}
+	if timestamp == 0 || len(signatures) == 0 {
+		return 0, nil, fmt.Errorf("missing signature timestamp or %s signature: %w", signatureScheme, ErrSignatureInvalid)
+	}
+	return timestamp, signatures, nil
+}
@@ -0,0 +1,182 @@
+package stripe
+
+import (
+	"encoding/hex"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testSecret is the endpoint signing secret of the tests.
+const testSecret = "whsec_test"
+
+// subscriptionEvent is a customer.subscription.updated event of the 2025-03-31 API version.
+const subscriptionEvent = `{
+	"id": "evt_1", "object": "event", "type": "customer.subscription.updated", "created": 1791979200,
+	"data": {"object": {
+		"id": "sub_1", "object": "subscription", "customer": "cus_1", "status": "active",
+		"metadata": {"user_id": "5f0c9a3e-8f6b-4d1e-9c7a-2b3d4e5f6a7b"},
+		"items": {"data": [{"price": {"id": "price_premium"}, "current_period_end": 1794657600}]}
+	}}
+}`
+
+// signature returns the hex v1 signature of the payload signed at the time with the secret.
+func signature(payload, secret string, at time.Time) string {
+	signed := strconv.FormatInt(at.Unix(), 10) + "." + payload
+	return hex.EncodeToString(crypto.SignHMACSHA256([]byte(secret), []byte(signed)))
+}
+
+// sign returns a Stripe-Signature header signing the payload at the time with the secret.
+func sign(payload, secret string, at time.Time) string {
+	return "t=" + strconv.FormatInt(at.Unix(), 10) + ",v1=" + signature(payload, secret, at)
+}
+
+func TestConstructEvent(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1791979200, 0).UTC()
+
+	tests := []struct {
+		wantErr error
+		name    string
+		payload string
+		header  string
+	}{
+		{name: "valid signature", payload: subscriptionEvent, header: sign(subscriptionEvent, testSecret, now)},
+		{
+			name:    "rolled secret",
+			payload: subscriptionEvent,
+			header:  sign(subscriptionEvent, "whsec_old", now) + ",v1=" + signature(subscriptionEvent, testSecret, now),
+		},
+		{
+			name:    "signature within tolerance",
+			payload: subscriptionEvent,
+			header:  sign(subscriptionEvent, testSecret, now.Add(-4*time.Minute)),
+		},
+		{
+			name:    "wrong secret",
+			payload: subscriptionEvent,
+			header:  sign(subscriptionEvent, "whsec_other", now),
+			wantErr: ErrSignatureInvalid,
+		},
+		{
+			name:    "tampered payload",
+			payload: subscriptionEvent + " ",
+			header:  sign(subscriptionEvent, testSecret, now),
+			wantErr: ErrSignatureInvalid,
+		},
+		{
+			name:    "expired signature",
+			payload: subscriptionEvent,
+			header:  sign(subscriptionEvent, testSecret, now.Add(-6*time.Minute)),
+			wantErr: ErrSignatureInvalid,
+		},
+		{
+			name:    "future signature",
+			payload: subscriptionEvent,
+			header:  sign(subscriptionEvent, testSecret, now.Add(6*time.Minute)),
+			wantErr: ErrSignatureInvalid,
+		},
+		{name: "missing header", payload: subscriptionEvent, wantErr: ErrSignatureInvalid},
+		{name: "missing timestamp", payload: subscriptionEvent, header: "v1=00", wantErr: ErrSignatureInvalid},
+		{name: "invalid timestamp", payload: subscriptionEvent, header: "t=x,v1=00", wantErr: ErrSignatureInvalid},
+		{name: "invalid JSON", payload: "{", header: sign("{", testSecret, now), wantErr: ErrEventInvalid},
+		{
+			name:    "not an event",
+			payload: `{"id": "sub_1", "object": "subscription"}`,
+			header:  sign(`{"id": "sub_1", "object": "subscription"}`, testSecret, now),
+			wantErr: ErrEventInvalid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			e, err := ConstructEvent([]byte(tt.payload), tt.header, testSecret, 0, now)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "evt_1", e.ID)
+		})
+	}
+}
+
+func TestDecodeEvent(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		want    *Event
+		name    string
+		payload string
+		wantErr bool
+	}{
+		{
+			name:    "subscription with item periods",
+			payload: subscriptionEvent,
+			want: &Event{
+				Created: time.Unix(1791979200, 0).UTC(),
+				Subscription: &Subscription{
+					CurrentPeriodEnd: time.Unix(1794657600, 0).UTC(),
+					Metadata:         map[string]string{"user_id": "5f0c9a3e-8f6b-4d1e-9c7a-2b3d4e5f6a7b"},
+					ID:               "sub_1",
+					CustomerID:       "cus_1",
+					Status:           "active",
+					PriceIDs:         []string{"price_premium"},
+				},
+				ID:   "evt_1",
+				Type: EventSubscriptionUpdated,
+			},
+		},
+		{
+			name: "subscription with legacy period",
+			payload: `{"id": "evt_2", "object": "event", "type": "customer.subscription.deleted", "created": 10,
+				"data": {"object": {"id": "sub_2", "customer": "cus_2", "status": "canceled",
+				"current_period_end": 20, "items": {"data": []}}}}`,
+			want: &Event{
+				Created: time.Unix(10, 0).UTC(),
+				Subscription: &Subscription{
+					CurrentPeriodEnd: time.Unix(20, 0).UTC(),
+					ID:               "sub_2",
+					CustomerID:       "cus_2",
+					Status:           "canceled",
+				},
+				ID:   "evt_2",
+				Type: EventSubscriptionDeleted,
+			},
+		},
+		{
+			name:    "other event",
+			payload: `{"id": "evt_3", "object": "event", "type": "invoice.paid", "created": 10, "data": {"object": {}}}`,
+			want:    &Event{Created: time.Unix(10, 0).UTC(), ID: "evt_3", Type: "invoice.paid"},
+		},
+		{
+			name: "subscription event without subscription",
+			payload: `{"id": "evt_4", "object": "event", "type": "customer.subscription.created", "created": 10,
+				"data": {"object": {}}}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := decodeEvent([]byte(tt.payload))
+
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrEventInvalid)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
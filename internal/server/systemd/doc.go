@@ -0,0 +1,7 @@
+// Package systemd integrates the server with the systemd service manager.
+//
+// This package takes over the listening sockets passed by socket activation (LISTEN_FDS), so systemd can
+// open privileged ports and queue connections while the server restarts, and sends state notifications
+// (sd_notify) reporting readiness, shutdown and watchdog keep-alives to the socket named by NOTIFY_SOCKET.
+// Outside systemd both are no-ops.
+package systemd
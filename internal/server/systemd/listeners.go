@@ -0,0 +1,26 @@
+package systemd
+
+import (
+	"errors"
+	"os"
+)
+
+// Environment variables of socket activation.
+const (
+	// envListenPID holds the process the sockets are passed to.
+	envListenPID = "LISTEN_PID"
+	// envListenFDs holds the number of passed sockets.
+	envListenFDs = "LISTEN_FDS"
+	// envListenFDNames holds the names of the passed sockets.
+	envListenFDNames = "LISTEN_FDNAMES"
+)
+
+// ErrInvalidActivation is returned when the socket activation variables do not describe listening sockets.
+var ErrInvalidActivation = errors.New("invalid socket activation")
+
+// unsetActivationEnv removes the socket activation variables, so child processes do not take the sockets over.
+func unsetActivationEnv() {
+	for _, name := range []string{envListenPID, envListenFDs, envListenFDNames} {
+		_ = os.Unsetenv(name)
+	}
+}
@@ -0,0 +1,11 @@
+//go:build !unix
+
+package systemd
+
+import "net"
+
+// Listeners returns the sockets passed by socket activation, which never happens on this platform.
+func Listeners() ([]net.Listener, error) {
+	unsetActivationEnv()
+	return nil, nil
+}
@@ -0,0 +1,95 @@
+//go:build unix
+
+package systemd
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
+)
+
+// passSocket duplicates the descriptor of a new listening socket as systemd would pass it and returns the
+// descriptor with the address the socket listens on. The caller owns the descriptor.
+func passSocket(t *testing.T) (int, string) {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer func() { _ = l.Close() }()
+	tl, ok := l.(*net.TCPListener)
+	require.True(t, ok)
+	f, err := tl.File()
+	require.NoError(t, err)
+	defer func() { _ = f.Close() }()
+	fd, err := unix.Dup(int(f.Fd()))
+	require.NoError(t, err)
+	return fd, l.Addr().String()
+}
+
+func TestListeners(t *testing.T) {
+	t.Parallel()
+
+	const pid = 4242
+
+	tests := []struct {
+		env       map[string]string
+		name      string
+		wantErr   bool
+		wantCount int
+	}{
+		{name: "not activated", env: map[string]string{}},
+		{name: "other process", env: map[string]string{envListenPID: "1", envListenFDs: "1"}},
+		{name: "missing count", env: map[string]string{envListenPID: strconv.Itoa(pid)}, wantErr: true},
+		{
+			name:      "activated",
+			env:       map[string]string{envListenPID: strconv.Itoa(pid), envListenFDs: "1"},
+			wantCount: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			fd, addr := passSocket(t)
+			ls, err := listeners(func(k string) string { return tt.env[k] }, pid, fd)
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrInvalidActivation)
+				_ = unix.Close(fd)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, ls, tt.wantCount)
+			if len(ls) == 0 {
+				_ = unix.Close(fd)
+			}
+			for _, l := range ls {
+				assert.Equal(t, addr, l.Addr().String())
+				conn, err := net.Dial("tcp", addr)
+				require.NoError(t, err)
+				_ = conn.Close()
+				_ = l.Close()
+			}
+		})
+	}
+
+	t.Run("not a socket", func(t *testing.T) {
+		t.Parallel()
+
+		f, err := os.Create(filepath.Join(t.TempDir(), "file"))
+		require.NoError(t, err)
+		defer func() { _ = f.Close() }()
+		fd, err := unix.Dup(int(f.Fd()))
+		require.NoError(t, err)
+
+		env := map[string]string{envListenPID: strconv.Itoa(pid), envListenFDs: "1"}
+		_, err = listeners(func(k string) string { return env[k] }, pid, fd)
+		require.ErrorIs(t, err, ErrInvalidActivation)
+	})
+}
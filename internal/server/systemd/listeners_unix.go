@@ -0,0 +1,54 @@
+//go:build unix
+
+package systemd
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+
+	"golang.org/x/sys/unix"
+)
+
+// listenFDsStart is the first passed file descriptor; the standard streams come before it.
+const listenFDsStart = 3
+
+// Listeners returns the sockets passed by socket activation in the order of the ListenStream= lines of the
+// socket unit, or nil when the process was not started by socket activation. It takes the sockets over, so
+// it is called once.
+func Listeners() ([]net.Listener, error) {
+	defer unsetActivationEnv()
+	return listeners(os.Getenv, os.Getpid(), listenFDsStart)
+}
+
+// listeners takes over the sockets described by the activation variables read with getenv, starting at the
+// first file descriptor. Sockets passed to another process than pid are ignored.
+func listeners(getenv func(string) string, pid, first int) ([]net.Listener, error) {
+	listenPID, err := strconv.Atoi(getenv(envListenPID))
+	if err != nil || listenPID != pid {
+		return nil, nil
+	}
+	n, err := strconv.Atoi(getenv(envListenFDs))
+	if err != nil || n <= 0 {
+		return nil, fmt.Errorf("%w: %s=%q", ErrInvalidActivation, envListenFDs, getenv(envListenFDs))
+	}
+
+	ls := make([]net.Listener, 0, n)
+	for fd := first; fd < first+n; fd++ {
+		unix.CloseOnExec(fd)
+		f := os.NewFile(uintptr(fd), "listen-fd-"+strconv.Itoa(fd))
+		l, err := net.FileListener(f)
+		_ = f.Close()
+		if err != nil {
+			for _, l := range ls {
+				_ = l.Close()
+			}
+			return nil, errors.Join(
+				fmt.Errorf("%w: file descriptor %d is not a listening socket", ErrInvalidActivation, fd), err)
+		}
+		ls = append(ls, l)
+	}
+	return ls, nil
+}
@@ -0,0 +1,90 @@
+package systemd
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Service states sent to systemd.
+const (
+	// Ready reports that the service finished starting up.
+	Ready = "READY=1"
+	// Stopping reports that the service is shutting down.
+	Stopping = "STOPPING=1"
+	// Watchdog keeps the watchdog of the service from restarting it.
+	Watchdog = "WATCHDOG=1"
+)
+
+// Environment variables of state notifications.
+const (
+	// envNotifySocket holds the address of the notification socket.
+	envNotifySocket = "NOTIFY_SOCKET"
+	// envWatchdogUSec holds the watchdog timeout in microseconds.
+	envWatchdogUSec = "WATCHDOG_USEC"
+	// envWatchdogPID holds the process the watchdog applies to.
+	envWatchdogPID = "WATCHDOG_PID"
+)
+
+// Notifier sends state notifications to systemd.
+type Notifier struct {
+	// socket is the address of the notification socket; empty outside a Type=notify service.
+	socket string
+	// watchdog is the WatchdogSec= timeout of the service; zero when the watchdog is disabled.
+	watchdog time.Duration
+}
+
+// NewNotifier creates a Notifier from the environment systemd starts the service with.
+func NewNotifier() *Notifier {
+	return newNotifier(os.Getenv, os.Getpid())
+}
+
+// newNotifier creates a Notifier from the variables read with getenv for the process pid.
+func newNotifier(getenv func(string) string, pid int) *Notifier {
+	n := &Notifier{socket: getenv(envNotifySocket)}
+	if n.socket == "" {
+		return n
+	}
+	if p := getenv(envWatchdogPID); p != "" && p != strconv.Itoa(pid) {
+		return n
+	}
+	if usec, err := strconv.ParseInt(getenv(envWatchdogUSec), 10, 64); err == nil && usec > 0 {
+		n.watchdog = time.Duration(usec) * time.Microsecond
+	}
+	return n
+}
+
+// Enabled reports whether the service runs under systemd expecting notifications.
+func (n *Notifier) Enabled() bool {
+	return n.socket != ""
+}
+
+// WatchdogInterval returns the watchdog timeout of the service, within which Watchdog is sent repeatedly;
+// zero when the watchdog is disabled.
+func (n *Notifier) WatchdogInterval() time.Duration {
+	return n.watchdog
+}
+
+// Notify sends the states in a single notification. It does nothing outside systemd.
+func (n *Notifier) Notify(states ...string) error {
+	if n.socket == "" {
+		return nil
+	}
+	name := n.socket
+	if strings.HasPrefix(name, "@") {
+		name = "\x00" + name[1:]
+	}
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: name, Net: "unixgram"})
+	if err != nil {
+		return fmt.Errorf("failed to connect to notification socket: %w", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	if _, err := conn.Write([]byte(strings.Join(states, "\n"))); err != nil {
+		return fmt.Errorf("failed to send notification: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package systemd
+
+import (
+	"net"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewNotifier(t *testing.T) {
+	t.Parallel()
+
+	const pid = 4242
+
+	tests := []struct {
+		env          map[string]string
+		name         string
+		wantWatchdog time.Duration
+		wantEnabled  bool
+	}{
+		{name: "outside systemd", env: map[string]string{envWatchdogUSec: "1000000"}},
+		{name: "without watchdog", env: map[string]string{envNotifySocket: "/run/notify"}, wantEnabled: true},
+		{
+			name:         "with watchdog",
+			env:          map[string]string{envNotifySocket: "/run/notify", envWatchdogUSec: "30000000"},
+			wantEnabled:  true,
+			wantWatchdog: 30 * time.Second,
+		},
+		{
+			name: "watchdog of own process",
+			env: map[string]string{
+				envNotifySocket: "/run/notify", envWatchdogUSec: "5000000", envWatchdogPID: strconv.Itoa(pid),
+			},
+			wantEnabled:  true,
+			wantWatchdog: 5 * time.Second,
+		},
+		{
+			name: "watchdog of other process",
+			env: map[string]string{
+				envNotifySocket: "/run/notify", envWatchdogUSec: "5000000", envWatchdogPID: "1",
+			},
+			wantEnabled: true,
+		},
+		{
+			name:        "invalid watchdog",
+			env:         map[string]string{envNotifySocket: "/run/notify", envWatchdogUSec: "soon"},
+			wantEnabled: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			n := newNotifier(func(k string) string { return tt.env[k] }, pid)
+			assert.Equal(t, tt.wantEnabled, n.Enabled())
+			assert.Equal(t, tt.wantWatchdog, n.WatchdogInterval())
+		})
+	}
+}
+
+func TestNotifier_Notify(t *testing.T) {
+	t.Parallel()
+
+	t.Run("outside systemd", func(t *testing.T) {
+		t.Parallel()
+
+		require.NoError(t, (&Notifier{}).Notify(Ready))
+	})
+
+	t.Run("states sent together", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "notify")
+		conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+		require.NoError(t, err)
+		defer func() { _ = conn.Close() }()
+
+		require.NoError(t, (&Notifier{socket: path}).Notify(Ready, "STATUS=Serving"))
+
+		buf := make([]byte, 64)
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+		n, err := conn.Read(buf)
+		require.NoError(t, err)
+		assert.Equal(t, "READY=1\nSTATUS=Serving", string(buf[:n]))
+	})
+
+	t.Run("missing socket", func(t *testing.T) {
+		t.Parallel()
+
+		err := (&Notifier{socket: filepath.Join(t.TempDir(), "missing")}).Notify(Stopping)
+		assert.Error(t, err)
+	})
+}
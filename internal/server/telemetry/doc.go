@@ -0,0 +1,6 @@
+// Package telemetry provides the anonymous usage pings of the AegisVaultKeeper server.
+//
+// This package defines the payload opted-in deployments send to the telemetry endpoint and posts it there.
+// Payloads carry no identifiers: the server version and platform, item and user counts rounded to buckets,
+// and the names of the feature flags switched on for the deployment.
+package telemetry
@@ -0,0 +1,51 @@
+package telemetry
+
+import (
+	"encoding/json"
+	"strconv"
+)
+
+// SchemaVersion is the version of the payload format, raised whenever fields change meaning.
+const SchemaVersion = 1
+
+// bucketBounds lists the inclusive upper bounds of the count buckets.
+var bucketBounds = []int64{10, 100, 1_000, 10_000, 100_000, 1_000_000}
+
+// Payload represents an anonymous usage ping.
+type Payload struct {
+	// Items contains the bucketed number of stored items by item type.
+	Items map[string]string `json:"items"`
+	// Version contains the server version.
+	Version string `json:"version"`
+	// OS contains the operating system the server runs on.
+	OS string `json:"os"`
+	// Arch contains the processor architecture the server runs on.
+	Arch string `json:"arch"`
+	// Users contains the bucketed number of registered users.
+	Users string `json:"users"`
+	// Features lists the feature flags switched on for the deployment, ordered by key.
+	Features []string `json:"features"`
+	// Schema contains the payload format version.
+	Schema int `json:"schema"`
+}
+
+// Encode returns the JSON encoding of the payload as sent to the telemetry endpoint.
+func (p *Payload) Encode() ([]byte, error) {
+	return json.Marshal(p)
+}
+
+// Bucket rounds a count up to its order of magnitude, such as "11-100", so pings never reveal exact sizes.
+func Bucket(n int64) string {
+	if n <= 0 {
+		return "0"
+	}
+	// low is the inclusive lower bound of the current bucket.
+	low := int64(1)
+	for _, high := range bucketBounds {
+		if n <= high {
+			return strconv.FormatInt(low, 10) + "-" + strconv.FormatInt(high, 10)
+		}
+		low = high + 1
+	}
+	return strconv.FormatInt(low, 10) + "+"
+}
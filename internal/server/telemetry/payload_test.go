@@ -0,0 +1,57 @@
+package telemetry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBucket(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		want string
+		n    int64
+	}{
+		{name: "none", n: 0, want: "0"},
+		{name: "negative", n: -3, want: "0"},
+		{name: "one", n: 1, want: "1-10"},
+		{name: "upper bound", n: 10, want: "1-10"},
+		{name: "lower bound", n: 11, want: "11-100"},
+		{name: "thousands", n: 4_321, want: "1001-10000"},
+		{name: "million", n: 1_000_000, want: "100001-1000000"},
+		{name: "beyond the last bucket", n: 7_000_000, want: "1000001+"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, Bucket(tt.n))
+		})
+	}
+}
+
+func TestPayload_Encode(t *testing.T) {
+	t.Parallel()
+
+	p := &Payload{
+		Items:    map[string]string{"notes": "11-100"},
+		Version:  "1.4.0",
+		OS:       "linux",
+		Arch:     "amd64",
+		Users:    "1-10",
+		Features: []string{"sync_v2"},
+		Schema:   SchemaVersion,
+	}
+
+	got, err := p.Encode()
+
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"items":{"notes":"11-100"},"version":"1.4.0","os":"linux","arch":"amd64",
+		"users":"1-10","features":["sync_v2"],"schema":1
+	}`, string(got))
+}
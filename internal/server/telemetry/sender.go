@@ -0,0 +1,51 @@
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// maxResponseSize limits how much of a response is read to release the connection.
+const maxResponseSize = 4 << 10
+
+// HTTPSender posts usage pings to the telemetry endpoint.
+type HTTPSender struct {
+	// client performs the HTTP requests.
+	client *http.Client
+	// endpoint contains the URL pings are posted to.
+	endpoint string
+}
+
+// NewHTTPSender creates a new HTTPSender posting to the endpoint.
+func NewHTTPSender(client *http.Client, endpoint string) *HTTPSender {
+	return &HTTPSender{client: client, endpoint: endpoint}
+}
+
+// Send posts the encoded payload and fails on non-2xx responses.
+func (s *HTTPSender) Send(ctx context.Context, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build telemetry request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("telemetry request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseSize))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("telemetry endpoint answered status %d", resp.StatusCode)
+	}
+	return nil
+}
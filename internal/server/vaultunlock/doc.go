@@ -0,0 +1,7 @@
+// Package vaultunlock seals user keys with unlock keys and carries unlock keys through request contexts.
+//
+// Users may protect their vault with an unlock secret kept apart from the login password. Clients derive the
+// unlock key from the secret with Argon2id and send only the derived key; the server seals the user key with it,
+// so the login password alone cannot decrypt the vault. The delivery layer verifies the unlock key once per
+// request, and the key provider reads it back to open the user key.
+package vaultunlock
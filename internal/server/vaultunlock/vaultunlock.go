@@ -0,0 +1,72 @@
+package vaultunlock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/crypto"
+	"github.com/google/uuid"
+)
+
+// Parameters of the key derivation clients run over the unlock secret and the salt of the user.
+const (
+	// KDF names the key derivation function.
+	KDF = "argon2id"
+	// KDFTime is the number of passes over the memory.
+	KDFTime uint32 = 3
+	// KDFMemoryKiB is the memory used by the derivation in KiB.
+	KDFMemoryKiB uint32 = 64 * 1024
+	// KDFThreads is the number of lanes of the derivation.
+	KDFThreads uint8 = 4
+	// KeySize is the size in bytes of unlock keys.
+	KeySize = 32
+	// MinSaltSize is the minimum size in bytes of the salt unlock keys are derived with.
+	MinSaltSize = 16
+)
+
+var (
+	// ErrLocked indicates that the user key is sealed and no unlock key was supplied.
+	ErrLocked = errors.New("vault is locked")
+	// ErrInvalidKey indicates an unlock key that does not open the user key.
+	ErrInvalidKey = errors.New("invalid unlock key")
+)
+
+// keyCtxKey is the context key under which the unlock key is stored.
+type keyCtxKey struct{}
+
+// WithKey returns a copy of ctx carrying the unlock key.
+func WithKey(ctx context.Context, key []byte) context.Context {
+	return context.WithValue(ctx, keyCtxKey{}, key)
+}
+
+// Key returns the unlock key stored in ctx, if any.
+func Key(ctx context.Context) ([]byte, bool) {
+	key, ok := ctx.Value(keyCtxKey{}).([]byte)
+	return key, ok && len(key) != 0
+}
+
+// Seal encrypts the user key with the unlock key. The ciphertext is bound to the user, so it cannot be
+// moved to another account.
+func Seal(unlockKey, userKey []byte, userID uuid.UUID) ([]byte, error) {
+	if len(unlockKey) != KeySize {
+		return nil, fmt.Errorf("unlock key must be %d bytes: %w", KeySize, ErrInvalidKey)
+	}
+	sealed, err := crypto.EncryptAESGCMWithAAD(unlockKey, userKey, userID[:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to seal user key: %w", err)
+	}
+	return sealed, nil
+}
+
+// Open decrypts a user key sealed with Seal. It fails with ErrInvalidKey when the unlock key does not match.
+func Open(unlockKey, sealed []byte, userID uuid.UUID) ([]byte, error) {
+	if len(unlockKey) != KeySize {
+		return nil, fmt.Errorf("unlock key must be %d bytes: %w", KeySize, ErrInvalidKey)
+	}
+	userKey, err := crypto.DecryptAESGCMWithAAD(unlockKey, sealed, userID[:])
+	if err != nil {
+		return nil, errors.Join(ErrInvalidKey, err)
+	}
+	return userKey, nil
+}
@@ -0,0 +1,81 @@
+package vaultunlock
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKey(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		ctx    context.Context
+		name   string
+		want   []byte
+		wantOK bool
+	}{
+		{
+			name:   "stored key",
+			ctx:    WithKey(context.Background(), []byte("unlock-key")),
+			want:   []byte("unlock-key"),
+			wantOK: true,
+		},
+		{name: "empty key", ctx: WithKey(context.Background(), nil)},
+		{name: "no key", ctx: context.Background()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := Key(tt.ctx)
+			assert.Equal(t, tt.wantOK, ok)
+			if tt.wantOK {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestSealOpen(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	unlockKey := bytes.Repeat([]byte{1}, KeySize)
+	userKey := bytes.Repeat([]byte{7}, 32)
+
+	sealed, err := Seal(unlockKey, userKey, userID)
+	require.NoError(t, err)
+	assert.NotContains(t, string(sealed), string(userKey))
+
+	tests := []struct {
+		name      string
+		unlockKey []byte
+		userID    uuid.UUID
+		wantErr   bool
+	}{
+		{name: "matching key", unlockKey: unlockKey, userID: userID},
+		{name: "wrong key", unlockKey: bytes.Repeat([]byte{2}, KeySize), userID: userID, wantErr: true},
+		{name: "short key", unlockKey: []byte("short"), userID: userID, wantErr: true},
+		{name: "other user", unlockKey: unlockKey, userID: uuid.New(), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := Open(tt.unlockKey, sealed, tt.userID)
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrInvalidKey)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, userKey, got)
+		})
+	}
+}
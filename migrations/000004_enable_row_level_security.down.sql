@@ -0,0 +1,17 @@
+DROP POLICY IF EXISTS files_user_isolation ON aegis_vault_keeper.files;
+ALTER TABLE aegis_vault_keeper.files NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE aegis_vault_keeper.files DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS bank_cards_user_isolation ON aegis_vault_keeper.bank_cards;
+ALTER TABLE aegis_vault_keeper.bank_cards NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE aegis_vault_keeper.bank_cards DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS notes_user_isolation ON aegis_vault_keeper.notes;
+ALTER TABLE aegis_vault_keeper.notes NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE aegis_vault_keeper.notes DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS credentials_user_isolation ON aegis_vault_keeper.credentials;
+ALTER TABLE aegis_vault_keeper.credentials NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE aegis_vault_keeper.credentials DISABLE ROW LEVEL SECURITY;
+
+DROP FUNCTION IF EXISTS aegis_vault_keeper.rls_user_id();
@@ -0,0 +1,29 @@
+CREATE OR REPLACE FUNCTION aegis_vault_keeper.rls_user_id() RETURNS UUID
+    LANGUAGE sql STABLE AS
+$$
+SELECT NULLIF(current_setting('aegis_vault_keeper.user_id', true), '')::UUID
+$$;
+
+ALTER TABLE aegis_vault_keeper.credentials ENABLE ROW LEVEL SECURITY;
+ALTER TABLE aegis_vault_keeper.credentials FORCE ROW LEVEL SECURITY;
+CREATE POLICY credentials_user_isolation ON aegis_vault_keeper.credentials
+    USING (aegis_vault_keeper.rls_user_id() IS NULL OR user_id = aegis_vault_keeper.rls_user_id())
+    WITH CHECK (aegis_vault_keeper.rls_user_id() IS NULL OR user_id = aegis_vault_keeper.rls_user_id());
+
+ALTER TABLE aegis_vault_keeper.notes ENABLE ROW LEVEL SECURITY;
+ALTER TABLE aegis_vault_keeper.notes FORCE ROW LEVEL SECURITY;
+CREATE POLICY notes_user_isolation ON aegis_vault_keeper.notes
+    USING (aegis_vault_keeper.rls_user_id() IS NULL OR user_id = aegis_vault_keeper.rls_user_id())
+    WITH CHECK (aegis_vault_keeper.rls_user_id() IS NULL OR user_id = aegis_vault_keeper.rls_user_id());
+
+ALTER TABLE aegis_vault_keeper.bank_cards ENABLE ROW LEVEL SECURITY;
+ALTER TABLE aegis_vault_keeper.bank_cards FORCE ROW LEVEL SECURITY;
+CREATE POLICY bank_cards_user_isolation ON aegis_vault_keeper.bank_cards
+    USING (aegis_vault_keeper.rls_user_id() IS NULL OR user_id = aegis_vault_keeper.rls_user_id())
+    WITH CHECK (aegis_vault_keeper.rls_user_id() IS NULL OR user_id = aegis_vault_keeper.rls_user_id());
+
+ALTER TABLE aegis_vault_keeper.files ENABLE ROW LEVEL SECURITY;
+ALTER TABLE aegis_vault_keeper.files FORCE ROW LEVEL SECURITY;
+CREATE POLICY files_user_isolation ON aegis_vault_keeper.files
+    USING (aegis_vault_keeper.rls_user_id() IS NULL OR user_id = aegis_vault_keeper.rls_user_id())
+    WITH CHECK (aegis_vault_keeper.rls_user_id() IS NULL OR user_id = aegis_vault_keeper.rls_user_id());
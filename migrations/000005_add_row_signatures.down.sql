@@ -0,0 +1,4 @@
+ALTER TABLE aegis_vault_keeper.files DROP COLUMN IF EXISTS signature;
+ALTER TABLE aegis_vault_keeper.bank_cards DROP COLUMN IF EXISTS signature;
+ALTER TABLE aegis_vault_keeper.notes DROP COLUMN IF EXISTS signature;
+ALTER TABLE aegis_vault_keeper.credentials DROP COLUMN IF EXISTS signature;
@@ -0,0 +1,4 @@
+ALTER TABLE aegis_vault_keeper.credentials ADD COLUMN IF NOT EXISTS signature BYTEA;
+ALTER TABLE aegis_vault_keeper.notes ADD COLUMN IF NOT EXISTS signature BYTEA;
+ALTER TABLE aegis_vault_keeper.bank_cards ADD COLUMN IF NOT EXISTS signature BYTEA;
+ALTER TABLE aegis_vault_keeper.files ADD COLUMN IF NOT EXISTS signature BYTEA;
@@ -0,0 +1,10 @@
+DROP TRIGGER IF EXISTS bank_cards_archive_version ON aegis_vault_keeper.bank_cards;
+DROP TABLE IF EXISTS aegis_vault_keeper.bank_cards_history;
+
+DROP TRIGGER IF EXISTS notes_archive_version ON aegis_vault_keeper.notes;
+DROP TABLE IF EXISTS aegis_vault_keeper.notes_history;
+
+DROP TRIGGER IF EXISTS credentials_archive_version ON aegis_vault_keeper.credentials;
+DROP TABLE IF EXISTS aegis_vault_keeper.credentials_history;
+
+DROP FUNCTION IF EXISTS aegis_vault_keeper.archive_item_version();
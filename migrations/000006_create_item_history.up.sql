@@ -0,0 +1,72 @@
+CREATE OR REPLACE FUNCTION aegis_vault_keeper.archive_item_version() RETURNS TRIGGER
+    LANGUAGE plpgsql AS
+$$
+BEGIN
+    EXECUTE format('INSERT INTO aegis_vault_keeper.%I SELECT ($1).*, now()', TG_TABLE_NAME || '_history')
+        USING OLD;
+    RETURN NEW;
+END
+$$;
+
+CREATE TABLE IF NOT EXISTS aegis_vault_keeper.credentials_history
+(
+    LIKE aegis_vault_keeper.credentials,
+    archived_at TIMESTAMP NOT NULL
+);
+CREATE INDEX IF NOT EXISTS credentials_history_id_idx
+    ON aegis_vault_keeper.credentials_history (id, updated_at);
+CREATE INDEX IF NOT EXISTS credentials_history_user_id_idx
+    ON aegis_vault_keeper.credentials_history (user_id, updated_at);
+CREATE INDEX IF NOT EXISTS credentials_history_archived_at_idx
+    ON aegis_vault_keeper.credentials_history (archived_at);
+CREATE TRIGGER credentials_archive_version
+    AFTER UPDATE ON aegis_vault_keeper.credentials
+    FOR EACH ROW EXECUTE FUNCTION aegis_vault_keeper.archive_item_version();
+
+CREATE TABLE IF NOT EXISTS aegis_vault_keeper.notes_history
+(
+    LIKE aegis_vault_keeper.notes,
+    archived_at TIMESTAMP NOT NULL
+);
+CREATE INDEX IF NOT EXISTS notes_history_id_idx
+    ON aegis_vault_keeper.notes_history (id, updated_at);
+CREATE INDEX IF NOT EXISTS notes_history_user_id_idx
+    ON aegis_vault_keeper.notes_history (user_id, updated_at);
+CREATE INDEX IF NOT EXISTS notes_history_archived_at_idx
+    ON aegis_vault_keeper.notes_history (archived_at);
+CREATE TRIGGER notes_archive_version
+    AFTER UPDATE ON aegis_vault_keeper.notes
+    FOR EACH ROW EXECUTE FUNCTION aegis_vault_keeper.archive_item_version();
+
+CREATE TABLE IF NOT EXISTS aegis_vault_keeper.bank_cards_history
+(
+    LIKE aegis_vault_keeper.bank_cards,
+    archived_at TIMESTAMP NOT NULL
+);
+CREATE INDEX IF NOT EXISTS bank_cards_history_id_idx
+    ON aegis_vault_keeper.bank_cards_history (id, updated_at);
+CREATE INDEX IF NOT EXISTS bank_cards_history_user_id_idx
+    ON aegis_vault_keeper.bank_cards_history (user_id, updated_at);
+CREATE INDEX IF NOT EXISTS bank_cards_history_archived_at_idx
+    ON aegis_vault_keeper.bank_cards_history (archived_at);
+CREATE TRIGGER bank_cards_archive_version
+    AFTER UPDATE ON aegis_vault_keeper.bank_cards
+    FOR EACH ROW EXECUTE FUNCTION aegis_vault_keeper.archive_item_version();
+
+ALTER TABLE aegis_vault_keeper.credentials_history ENABLE ROW LEVEL SECURITY;
+ALTER TABLE aegis_vault_keeper.credentials_history FORCE ROW LEVEL SECURITY;
+CREATE POLICY credentials_history_user_isolation ON aegis_vault_keeper.credentials_history
+    USING (aegis_vault_keeper.rls_user_id() IS NULL OR user_id = aegis_vault_keeper.rls_user_id())
+    WITH CHECK (aegis_vault_keeper.rls_user_id() IS NULL OR user_id = aegis_vault_keeper.rls_user_id());
+
+ALTER TABLE aegis_vault_keeper.notes_history ENABLE ROW LEVEL SECURITY;
+ALTER TABLE aegis_vault_keeper.notes_history FORCE ROW LEVEL SECURITY;
+CREATE POLICY notes_history_user_isolation ON aegis_vault_keeper.notes_history
+    USING (aegis_vault_keeper.rls_user_id() IS NULL OR user_id = aegis_vault_keeper.rls_user_id())
+    WITH CHECK (aegis_vault_keeper.rls_user_id() IS NULL OR user_id = aegis_vault_keeper.rls_user_id());
+
+ALTER TABLE aegis_vault_keeper.bank_cards_history ENABLE ROW LEVEL SECURITY;
+ALTER TABLE aegis_vault_keeper.bank_cards_history FORCE ROW LEVEL SECURITY;
+CREATE POLICY bank_cards_history_user_isolation ON aegis_vault_keeper.bank_cards_history
+    USING (aegis_vault_keeper.rls_user_id() IS NULL OR user_id = aegis_vault_keeper.rls_user_id())
+    WITH CHECK (aegis_vault_keeper.rls_user_id() IS NULL OR user_id = aegis_vault_keeper.rls_user_id());
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS aegis_vault_keeper.access_rules
+(
+    id         UUID      PRIMARY KEY,
+    user_id    UUID      REFERENCES aegis_vault_keeper.auth_users (id) ON DELETE CASCADE,
+    action     TEXT      NOT NULL CHECK (action IN ('allow', 'deny')),
+    network    CIDR,
+    country    CHAR(2),
+    created_at TIMESTAMP NOT NULL,
+    CHECK ((network IS NULL) <> (country IS NULL))
+);
+CREATE INDEX IF NOT EXISTS access_rules_user_id_idx
+    ON aegis_vault_keeper.access_rules (user_id);
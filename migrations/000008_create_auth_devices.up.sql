@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS aegis_vault_keeper.auth_devices
+(
+    id                   UUID      PRIMARY KEY,
+    user_id              UUID      NOT NULL REFERENCES aegis_vault_keeper.auth_users (id) ON DELETE CASCADE,
+    fingerprint          TEXT      NOT NULL,
+    user_agent           TEXT      NOT NULL,
+    ip                   INET,
+    location             TEXT      NOT NULL,
+    trusted              BOOLEAN   NOT NULL,
+    challenge_hash       BYTEA,
+    challenge_expires_at TIMESTAMP,
+    challenge_attempts   INTEGER   NOT NULL DEFAULT 0,
+    created_at           TIMESTAMP NOT NULL,
+    last_seen_at         TIMESTAMP NOT NULL
+);
+CREATE INDEX IF NOT EXISTS auth_devices_user_id_idx
+    ON aegis_vault_keeper.auth_devices (user_id);
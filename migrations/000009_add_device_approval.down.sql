@@ -0,0 +1,2 @@
+ALTER TABLE aegis_vault_keeper.auth_devices DROP COLUMN IF EXISTS approved;
+ALTER TABLE aegis_vault_keeper.auth_devices DROP COLUMN IF EXISTS approval_hash;
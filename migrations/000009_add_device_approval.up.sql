@@ -0,0 +1,2 @@
+ALTER TABLE aegis_vault_keeper.auth_devices ADD COLUMN IF NOT EXISTS approval_hash BYTEA;
+ALTER TABLE aegis_vault_keeper.auth_devices ADD COLUMN IF NOT EXISTS approved BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS aegis_vault_keeper.feature_flags
+(
+    key        TEXT      NOT NULL,
+    user_id    UUID      REFERENCES aegis_vault_keeper.auth_users (id) ON DELETE CASCADE,
+    enabled    BOOLEAN   NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    UNIQUE NULLS NOT DISTINCT (key, user_id)
+);
+CREATE INDEX IF NOT EXISTS feature_flags_user_id_idx
+    ON aegis_vault_keeper.feature_flags (user_id);
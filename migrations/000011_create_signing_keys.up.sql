@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS aegis_vault_keeper.signing_keys
+(
+    id         UUID      PRIMARY KEY,
+    user_id    UUID      NOT NULL REFERENCES aegis_vault_keeper.auth_users (id) ON DELETE CASCADE,
+    name       TEXT      NOT NULL,
+    secret     BYTEA     NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
+CREATE INDEX IF NOT EXISTS signing_keys_user_id_idx
+    ON aegis_vault_keeper.signing_keys (user_id);
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS aegis_vault_keeper.user_group_members;
+DROP TABLE IF EXISTS aegis_vault_keeper.user_groups;
+
+DROP INDEX IF EXISTS aegis_vault_keeper.auth_users_external_id_idx;
+ALTER TABLE aegis_vault_keeper.auth_users DROP COLUMN IF EXISTS deprovisioned_at;
+ALTER TABLE aegis_vault_keeper.auth_users DROP COLUMN IF EXISTS external_id;
+ALTER TABLE aegis_vault_keeper.auth_users DROP COLUMN IF EXISTS active;
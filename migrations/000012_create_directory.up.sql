@@ -0,0 +1,25 @@
+ALTER TABLE aegis_vault_keeper.auth_users ADD COLUMN IF NOT EXISTS active BOOLEAN NOT NULL DEFAULT TRUE;
+ALTER TABLE aegis_vault_keeper.auth_users ADD COLUMN IF NOT EXISTS external_id TEXT;
+ALTER TABLE aegis_vault_keeper.auth_users ADD COLUMN IF NOT EXISTS deprovisioned_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS auth_users_external_id_idx
+    ON aegis_vault_keeper.auth_users (external_id);
+
+CREATE TABLE IF NOT EXISTS aegis_vault_keeper.user_groups
+(
+    id           UUID PRIMARY KEY,
+    display_name TEXT      NOT NULL UNIQUE,
+    external_id  TEXT,
+    created_at   TIMESTAMP NOT NULL,
+    updated_at   TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS aegis_vault_keeper.user_group_members
+(
+    group_id UUID NOT NULL REFERENCES aegis_vault_keeper.user_groups (id) ON DELETE CASCADE,
+    user_id  UUID NOT NULL REFERENCES aegis_vault_keeper.auth_users (id) ON DELETE CASCADE,
+    PRIMARY KEY (group_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS user_group_members_user_id_idx
+    ON aegis_vault_keeper.user_group_members (user_id);
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS aegis_vault_keeper.notification_channels
+(
+    id         UUID      PRIMARY KEY,
+    user_id    UUID      NOT NULL REFERENCES aegis_vault_keeper.auth_users (id) ON DELETE CASCADE,
+    kind       TEXT      NOT NULL,
+    target     BYTEA     NOT NULL,
+    categories TEXT[]    NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    UNIQUE (user_id, kind)
+);
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS aegis_vault_keeper.push_subscriptions
+(
+    id              UUID      PRIMARY KEY,
+    user_id         UUID      NOT NULL REFERENCES aegis_vault_keeper.auth_users (id) ON DELETE CASCADE,
+    device          TEXT      NOT NULL,
+    endpoint_digest BYTEA     NOT NULL,
+    subscription    BYTEA     NOT NULL,
+    categories      TEXT[]    NOT NULL,
+    created_at      TIMESTAMP NOT NULL,
+    updated_at      TIMESTAMP NOT NULL,
+    UNIQUE (user_id, endpoint_digest)
+);
+
+-- Web push moved from a single channel per user to a subscription per device.
+DELETE FROM aegis_vault_keeper.notification_channels WHERE kind = 'webpush';
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS aegis_vault_keeper.access_policies
+(
+    id           UUID      PRIMARY KEY,
+    user_id      UUID      REFERENCES aegis_vault_keeper.auth_users (id) ON DELETE CASCADE,
+    days         SMALLINT  NOT NULL CHECK (days BETWEEN 1 AND 127),
+    start_minute SMALLINT  NOT NULL CHECK (start_minute BETWEEN 0 AND 1439),
+    end_minute   SMALLINT  NOT NULL CHECK (end_minute BETWEEN 1 AND 1440),
+    timezone     TEXT      NOT NULL,
+    managed      BOOLEAN   NOT NULL,
+    created_at   TIMESTAMP NOT NULL,
+    CHECK (start_minute <> end_minute),
+    CHECK (user_id IS NOT NULL OR managed)
+);
+CREATE INDEX IF NOT EXISTS access_policies_user_id_idx
+    ON aegis_vault_keeper.access_policies (user_id);
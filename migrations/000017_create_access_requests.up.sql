@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS aegis_vault_keeper.access_requests
+(
+    id          UUID      PRIMARY KEY,
+    user_id     UUID      NOT NULL REFERENCES aegis_vault_keeper.auth_users (id) ON DELETE CASCADE,
+    item_id     UUID      NOT NULL,
+    reason      TEXT      NOT NULL,
+    status      TEXT      NOT NULL CHECK (status IN ('pending', 'approved', 'denied')),
+    approver_id UUID      REFERENCES aegis_vault_keeper.auth_users (id) ON DELETE SET NULL,
+    created_at  TIMESTAMP NOT NULL,
+    decided_at  TIMESTAMP,
+    expires_at  TIMESTAMP NOT NULL
+);
+CREATE INDEX IF NOT EXISTS access_requests_user_id_item_id_idx
+    ON aegis_vault_keeper.access_requests (user_id, item_id);
+CREATE INDEX IF NOT EXISTS access_requests_pending_idx
+    ON aegis_vault_keeper.access_requests (expires_at)
+    WHERE status = 'pending';
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS aegis_vault_keeper.credential_shares
+(
+    credential_id     UUID      PRIMARY KEY,
+    owner_id          UUID      NOT NULL REFERENCES aegis_vault_keeper.auth_users (id) ON DELETE CASCADE,
+    group_id          UUID      NOT NULL REFERENCES aegis_vault_keeper.user_groups (id) ON DELETE CASCADE,
+    rotate_on_checkin BOOLEAN   NOT NULL,
+    holder_id         UUID      REFERENCES aegis_vault_keeper.auth_users (id) ON DELETE SET NULL,
+    created_at        TIMESTAMP NOT NULL,
+    checked_out_at    TIMESTAMP,
+    expires_at        TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS credential_shares_owner_id_idx
+    ON aegis_vault_keeper.credential_shares (owner_id);
+CREATE INDEX IF NOT EXISTS credential_shares_group_id_idx
+    ON aegis_vault_keeper.credential_shares (group_id);
+CREATE INDEX IF NOT EXISTS credential_shares_checked_out_idx
+    ON aegis_vault_keeper.credential_shares (expires_at)
+    WHERE holder_id IS NOT NULL;
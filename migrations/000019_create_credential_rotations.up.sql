@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS aegis_vault_keeper.credential_rotations
+(
+    credential_id    UUID      PRIMARY KEY,
+    user_id          UUID      NOT NULL REFERENCES aegis_vault_keeper.auth_users (id) ON DELETE CASCADE,
+    kind             TEXT      NOT NULL CHECK (kind IN ('postgres', 'aws_iam', 'webhook')),
+    settings         JSONB     NOT NULL,
+    interval_seconds BIGINT    NOT NULL CHECK (interval_seconds >= 0),
+    last_error       TEXT      NOT NULL DEFAULT '',
+    created_at       TIMESTAMP NOT NULL,
+    last_rotated_at  TIMESTAMP,
+    next_rotation_at TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS credential_rotations_user_id_idx
+    ON aegis_vault_keeper.credential_rotations (user_id);
+CREATE INDEX IF NOT EXISTS credential_rotations_scheduled_idx
+    ON aegis_vault_keeper.credential_rotations (next_rotation_at)
+    WHERE interval_seconds > 0;
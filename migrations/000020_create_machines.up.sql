@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS aegis_vault_keeper.machines
+(
+    id           UUID      PRIMARY KEY,
+    user_id      UUID      NOT NULL REFERENCES aegis_vault_keeper.auth_users (id) ON DELETE CASCADE,
+    name         TEXT      NOT NULL,
+    token_hash   BYTEA     NOT NULL UNIQUE,
+    scope        JSONB     NOT NULL,
+    created_at   TIMESTAMP NOT NULL,
+    last_used_at TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS machines_user_id_idx
+    ON aegis_vault_keeper.machines (user_id);
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS aegis_vault_keeper.item_paths
+(
+    user_id    UUID      NOT NULL REFERENCES aegis_vault_keeper.auth_users (id) ON DELETE CASCADE,
+    item_id    UUID      NOT NULL,
+    path       TEXT      NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (user_id, item_id),
+    UNIQUE (user_id, path)
+);
@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS aegis_vault_keeper.acme_accounts
+(
+    id              UUID      PRIMARY KEY,
+    user_id         UUID      NOT NULL REFERENCES aegis_vault_keeper.auth_users (id) ON DELETE CASCADE,
+    directory_url   BYTEA     NOT NULL,
+    email           BYTEA     NOT NULL,
+    account_key     BYTEA     NOT NULL,
+    dns_provider    BYTEA     NOT NULL,
+    dns_credentials BYTEA     NOT NULL,
+    hook_url        BYTEA     NOT NULL,
+    description     BYTEA     NOT NULL,
+    updated_at      TIMESTAMP NOT NULL,
+    signature       BYTEA
+);
+CREATE INDEX IF NOT EXISTS acme_accounts_user_id_idx
+    ON aegis_vault_keeper.acme_accounts (user_id);
+
+ALTER TABLE aegis_vault_keeper.acme_accounts ENABLE ROW LEVEL SECURITY;
+ALTER TABLE aegis_vault_keeper.acme_accounts FORCE ROW LEVEL SECURITY;
+CREATE POLICY acme_accounts_user_isolation ON aegis_vault_keeper.acme_accounts
+    USING (aegis_vault_keeper.rls_user_id() IS NULL OR user_id = aegis_vault_keeper.rls_user_id())
+    WITH CHECK (aegis_vault_keeper.rls_user_id() IS NULL OR user_id = aegis_vault_keeper.rls_user_id());
@@ -0,0 +1 @@
+ALTER TABLE aegis_vault_keeper.files ADD COLUMN IF NOT EXISTS file_key BYTEA;
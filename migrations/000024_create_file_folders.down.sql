@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS aegis_vault_keeper.file_folders;
+ALTER TABLE aegis_vault_keeper.files DROP COLUMN IF EXISTS folder;
@@ -0,0 +1,19 @@
+ALTER TABLE aegis_vault_keeper.files ADD COLUMN IF NOT EXISTS folder BYTEA;
+
+CREATE TABLE IF NOT EXISTS aegis_vault_keeper.file_folders
+(
+    id         UUID      PRIMARY KEY,
+    user_id    UUID      NOT NULL REFERENCES aegis_vault_keeper.auth_users (id) ON DELETE CASCADE,
+    path       BYTEA     NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    signature  BYTEA
+);
+
+CREATE INDEX IF NOT EXISTS file_folders_user_id_idx
+    ON aegis_vault_keeper.file_folders (user_id);
+
+ALTER TABLE aegis_vault_keeper.file_folders ENABLE ROW LEVEL SECURITY;
+ALTER TABLE aegis_vault_keeper.file_folders FORCE ROW LEVEL SECURITY;
+CREATE POLICY file_folders_user_isolation ON aegis_vault_keeper.file_folders
+    USING (aegis_vault_keeper.rls_user_id() IS NULL OR user_id = aegis_vault_keeper.rls_user_id())
+    WITH CHECK (aegis_vault_keeper.rls_user_id() IS NULL OR user_id = aegis_vault_keeper.rls_user_id());
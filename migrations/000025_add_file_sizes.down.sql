@@ -0,0 +1 @@
+ALTER TABLE aegis_vault_keeper.files DROP COLUMN IF EXISTS size;
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS aegis_vault_keeper.note_updates
+(
+    id         UUID      PRIMARY KEY,
+    seq        BIGINT    GENERATED BY DEFAULT AS IDENTITY UNIQUE,
+    user_id    UUID      NOT NULL REFERENCES aegis_vault_keeper.auth_users (id) ON DELETE CASCADE,
+    note_id    UUID      NOT NULL REFERENCES aegis_vault_keeper.notes (id) ON DELETE CASCADE,
+    data       BYTEA     NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    signature  BYTEA
+);
+
+CREATE INDEX IF NOT EXISTS note_updates_note_id_seq_idx
+    ON aegis_vault_keeper.note_updates (note_id, seq);
+
+ALTER TABLE aegis_vault_keeper.note_updates ENABLE ROW LEVEL SECURITY;
+ALTER TABLE aegis_vault_keeper.note_updates FORCE ROW LEVEL SECURITY;
+CREATE POLICY note_updates_user_isolation ON aegis_vault_keeper.note_updates
+    USING (aegis_vault_keeper.rls_user_id() IS NULL OR user_id = aegis_vault_keeper.rls_user_id())
+    WITH CHECK (aegis_vault_keeper.rls_user_id() IS NULL OR user_id = aegis_vault_keeper.rls_user_id());
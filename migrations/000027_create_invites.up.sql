@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS aegis_vault_keeper.invites
+(
+    id           UUID      PRIMARY KEY,
+    code_hash    BYTEA     NOT NULL UNIQUE,
+    note         TEXT      NOT NULL,
+    group_ids    JSONB     NOT NULL,
+    invite_quota INTEGER   NOT NULL,
+    created_by   UUID      REFERENCES aegis_vault_keeper.auth_users (id) ON DELETE CASCADE,
+    created_at   TIMESTAMP NOT NULL,
+    expires_at   TIMESTAMP NOT NULL,
+    revoked_at   TIMESTAMP,
+    redeemed_by  UUID      REFERENCES aegis_vault_keeper.auth_users (id) ON DELETE SET NULL,
+    redeemed_at  TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS invites_created_by_idx
+    ON aegis_vault_keeper.invites (created_by);
+CREATE INDEX IF NOT EXISTS invites_redeemed_by_idx
+    ON aegis_vault_keeper.invites (redeemed_by);
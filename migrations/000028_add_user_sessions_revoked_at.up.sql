@@ -0,0 +1 @@
+ALTER TABLE aegis_vault_keeper.auth_users ADD COLUMN IF NOT EXISTS sessions_revoked_at TIMESTAMP;
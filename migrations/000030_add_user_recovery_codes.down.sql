@@ -0,0 +1 @@
+ALTER TABLE aegis_vault_keeper.auth_users DROP COLUMN IF EXISTS recovery_codes;
@@ -0,0 +1,7 @@
+DELETE FROM aegis_vault_keeper.auth_users WHERE profile_of IS NOT NULL;
+
+ALTER TABLE aegis_vault_keeper.auth_users
+    DROP COLUMN IF EXISTS duress_alert,
+    DROP COLUMN IF EXISTS profile_of,
+    DROP COLUMN IF EXISTS duress_vault_id,
+    DROP COLUMN IF EXISTS duress_password_hash;
@@ -0,0 +1,5 @@
+ALTER TABLE aegis_vault_keeper.auth_users
+    ADD COLUMN IF NOT EXISTS duress_password_hash TEXT    NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS duress_vault_id      UUID    REFERENCES aegis_vault_keeper.auth_users (id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS profile_of           UUID    REFERENCES aegis_vault_keeper.auth_users (id) ON DELETE CASCADE,
+    ADD COLUMN IF NOT EXISTS duress_alert         BOOLEAN NOT NULL DEFAULT FALSE;
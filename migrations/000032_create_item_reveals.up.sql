@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS aegis_vault_keeper.item_reveals
+(
+    id          UUID      PRIMARY KEY,
+    user_id     UUID      NOT NULL REFERENCES aegis_vault_keeper.auth_users (id) ON DELETE CASCADE,
+    item_id     UUID      NOT NULL,
+    fingerprint TEXT      NOT NULL,
+    user_agent  TEXT      NOT NULL,
+    ip          INET,
+    revealed_at TIMESTAMP NOT NULL
+);
+CREATE INDEX IF NOT EXISTS item_reveals_user_id_item_id_idx
+    ON aegis_vault_keeper.item_reveals (user_id, item_id, revealed_at DESC);
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS aegis_vault_keeper.stats_rollups
+(
+    id                   UUID      PRIMARY KEY,
+    rolled_up_at         TIMESTAMP NOT NULL,
+    users                BIGINT    NOT NULL,
+    daily_active_users   BIGINT    NOT NULL,
+    monthly_active_users BIGINT    NOT NULL,
+    items                JSONB     NOT NULL,
+    storage_bytes        BIGINT    NOT NULL,
+    storage_tiers        JSONB     NOT NULL,
+    requests             BIGINT    NOT NULL,
+    client_errors        BIGINT    NOT NULL,
+    server_errors        BIGINT    NOT NULL,
+    sync_requests        BIGINT    NOT NULL,
+    sync_bytes           BIGINT    NOT NULL
+);
+CREATE INDEX IF NOT EXISTS stats_rollups_rolled_up_at_idx
+    ON aegis_vault_keeper.stats_rollups (rolled_up_at);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS aegis_vault_keeper.billing_events;
+DROP TABLE IF EXISTS aegis_vault_keeper.plan_subscriptions;
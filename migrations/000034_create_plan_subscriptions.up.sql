@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS aegis_vault_keeper.plan_subscriptions
+(
+    user_id         UUID      PRIMARY KEY REFERENCES aegis_vault_keeper.auth_users (id) ON DELETE CASCADE,
+    plan            TEXT      NOT NULL,
+    status          TEXT      NOT NULL,
+    customer_id     TEXT      NOT NULL,
+    subscription_id TEXT      NOT NULL,
+    period_end      TIMESTAMP,
+    grace_plan      TEXT      NOT NULL,
+    grace_until     TIMESTAMP,
+    over_quota      BOOLEAN   NOT NULL,
+    event_at        TIMESTAMP NOT NULL,
+    updated_at      TIMESTAMP NOT NULL
+);
+CREATE INDEX IF NOT EXISTS plan_subscriptions_over_quota_idx
+    ON aegis_vault_keeper.plan_subscriptions (user_id)
+    WHERE over_quota;
+
+CREATE TABLE IF NOT EXISTS aegis_vault_keeper.billing_events
+(
+    id         TEXT      PRIMARY KEY,
+    applied_at TIMESTAMP NOT NULL
+);
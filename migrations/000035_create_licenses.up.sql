@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS aegis_vault_keeper.licenses
+(
+    id           TEXT      PRIMARY KEY,
+    key          TEXT      NOT NULL,
+    installed_at TIMESTAMP NOT NULL
+);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS aegis_vault_keeper.recorded_exchanges;
+DROP TABLE IF EXISTS aegis_vault_keeper.recording_sessions;
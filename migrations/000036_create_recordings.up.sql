@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS aegis_vault_keeper.recording_sessions
+(
+    id         UUID      PRIMARY KEY,
+    user_id    UUID      REFERENCES aegis_vault_keeper.auth_users (id) ON DELETE CASCADE,
+    route      TEXT      NOT NULL,
+    reason     TEXT      NOT NULL,
+    started_at TIMESTAMP NOT NULL,
+    expires_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS recording_sessions_expires_at_idx
+    ON aegis_vault_keeper.recording_sessions (expires_at);
+
+CREATE TABLE IF NOT EXISTS aegis_vault_keeper.recorded_exchanges
+(
+    id          UUID      PRIMARY KEY,
+    session_id  UUID      NOT NULL REFERENCES aegis_vault_keeper.recording_sessions (id) ON DELETE CASCADE,
+    recorded_at TIMESTAMP NOT NULL,
+    data        BYTEA     NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS recorded_exchanges_session_id_idx
+    ON aegis_vault_keeper.recorded_exchanges (session_id, recorded_at);
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS aegis_vault_keeper.item_orders
+(
+    user_id    UUID      NOT NULL REFERENCES aegis_vault_keeper.auth_users (id) ON DELETE CASCADE,
+    folder     TEXT      NOT NULL,
+    item_ids   UUID[]    NOT NULL,
+    pinned     INTEGER   NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (user_id, folder)
+);
@@ -0,0 +1,2 @@
+ALTER TABLE aegis_vault_keeper.credentials_history DROP COLUMN IF EXISTS uris;
+ALTER TABLE aegis_vault_keeper.credentials DROP COLUMN IF EXISTS uris;
@@ -0,0 +1,11 @@
+ALTER TABLE aegis_vault_keeper.credentials ADD COLUMN IF NOT EXISTS uris BYTEA;
+
+-- Versions are archived positionally, so archived_at has to stay the last column of the history table.
+ALTER TABLE aegis_vault_keeper.credentials_history ADD COLUMN IF NOT EXISTS uris BYTEA;
+ALTER TABLE aegis_vault_keeper.credentials_history RENAME COLUMN archived_at TO archived_at_before_uris;
+ALTER TABLE aegis_vault_keeper.credentials_history ADD COLUMN archived_at TIMESTAMP;
+UPDATE aegis_vault_keeper.credentials_history SET archived_at = archived_at_before_uris;
+ALTER TABLE aegis_vault_keeper.credentials_history ALTER COLUMN archived_at SET NOT NULL;
+ALTER TABLE aegis_vault_keeper.credentials_history DROP COLUMN archived_at_before_uris;
+CREATE INDEX IF NOT EXISTS credentials_history_archived_at_idx
+    ON aegis_vault_keeper.credentials_history (archived_at);
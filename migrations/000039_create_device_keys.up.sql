@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS aegis_vault_keeper.device_keys
+(
+    id         UUID      PRIMARY KEY,
+    user_id    UUID      NOT NULL REFERENCES aegis_vault_keeper.auth_users (id) ON DELETE CASCADE,
+    name       TEXT      NOT NULL,
+    public_key BYTEA     NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
+CREATE INDEX IF NOT EXISTS device_keys_user_id_idx
+    ON aegis_vault_keeper.device_keys (user_id);
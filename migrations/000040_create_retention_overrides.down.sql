@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS aegis_vault_keeper.item_reveals_revealed_at_idx;
+DROP TABLE IF EXISTS aegis_vault_keeper.retention_overrides;
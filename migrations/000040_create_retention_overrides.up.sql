@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS aegis_vault_keeper.retention_overrides
+(
+    group_id        UUID      PRIMARY KEY REFERENCES aegis_vault_keeper.user_groups (id) ON DELETE CASCADE,
+    keep_versions   INTEGER   NOT NULL,
+    keep_audit_days INTEGER   NOT NULL,
+    updated_at      TIMESTAMP NOT NULL
+);
+CREATE INDEX IF NOT EXISTS item_reveals_revealed_at_idx
+    ON aegis_vault_keeper.item_reveals (revealed_at);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS aegis_vault_keeper.legal_holds;
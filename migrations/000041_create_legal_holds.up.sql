@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS aegis_vault_keeper.legal_holds
+(
+    id        UUID      PRIMARY KEY,
+    user_id   UUID      NOT NULL REFERENCES aegis_vault_keeper.auth_users (id) ON DELETE CASCADE,
+    item_id   UUID,
+    reason    TEXT      NOT NULL,
+    placed_at TIMESTAMP NOT NULL
+);
+CREATE INDEX IF NOT EXISTS legal_holds_user_id_idx
+    ON aegis_vault_keeper.legal_holds (user_id);
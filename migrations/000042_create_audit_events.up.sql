@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS aegis_vault_keeper.audit_events
+(
+    seq         BIGSERIAL PRIMARY KEY,
+    event_type  TEXT      NOT NULL,
+    user_id     UUID,
+    client_ip   INET,
+    details     JSONB     NOT NULL,
+    occurred_at TIMESTAMP NOT NULL
+);
+CREATE INDEX IF NOT EXISTS audit_events_occurred_at_idx
+    ON aegis_vault_keeper.audit_events (occurred_at);
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS aegis_vault_keeper.admin_approvals
+(
+    id          UUID      PRIMARY KEY,
+    action      TEXT      NOT NULL,
+    method      TEXT      NOT NULL,
+    path        TEXT      NOT NULL,
+    body_digest BYTEA     NOT NULL,
+    initiator   TEXT      NOT NULL,
+    approver    TEXT,
+    status      TEXT      NOT NULL CHECK (status IN ('pending', 'approved', 'rejected', 'executed')),
+    created_at  TIMESTAMP NOT NULL,
+    decided_at  TIMESTAMP,
+    expires_at  TIMESTAMP NOT NULL
+);
+CREATE INDEX IF NOT EXISTS admin_approvals_status_idx
+    ON aegis_vault_keeper.admin_approvals (status, created_at);
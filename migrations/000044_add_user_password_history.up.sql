@@ -0,0 +1 @@
+ALTER TABLE aegis_vault_keeper.auth_users ADD COLUMN IF NOT EXISTS password_history JSONB NOT NULL DEFAULT '[]';
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS aegis_vault_keeper.item_policies
+(
+    id         UUID      PRIMARY KEY,
+    group_id   UUID      REFERENCES aegis_vault_keeper.user_groups (id) ON DELETE CASCADE,
+    name       TEXT      NOT NULL,
+    rules      JSONB     NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
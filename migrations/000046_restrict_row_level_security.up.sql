@@ -0,0 +1,55 @@
+CREATE OR REPLACE FUNCTION aegis_vault_keeper.rls_admin_scope() RETURNS BOOLEAN
+    LANGUAGE sql STABLE AS
+$$
+SELECT COALESCE(current_setting('aegis_vault_keeper.admin_scope', true), '') = 'on'
+$$;
+
+DROP POLICY IF EXISTS credentials_user_isolation ON aegis_vault_keeper.credentials;
+CREATE POLICY credentials_user_isolation ON aegis_vault_keeper.credentials
+    USING (aegis_vault_keeper.rls_admin_scope() OR user_id = aegis_vault_keeper.rls_user_id())
+    WITH CHECK (aegis_vault_keeper.rls_admin_scope() OR user_id = aegis_vault_keeper.rls_user_id());
+
+DROP POLICY IF EXISTS notes_user_isolation ON aegis_vault_keeper.notes;
+CREATE POLICY notes_user_isolation ON aegis_vault_keeper.notes
+    USING (aegis_vault_keeper.rls_admin_scope() OR user_id = aegis_vault_keeper.rls_user_id())
+    WITH CHECK (aegis_vault_keeper.rls_admin_scope() OR user_id = aegis_vault_keeper.rls_user_id());
+
+DROP POLICY IF EXISTS bank_cards_user_isolation ON aegis_vault_keeper.bank_cards;
+CREATE POLICY bank_cards_user_isolation ON aegis_vault_keeper.bank_cards
+    USING (aegis_vault_keeper.rls_admin_scope() OR user_id = aegis_vault_keeper.rls_user_id())
+    WITH CHECK (aegis_vault_keeper.rls_admin_scope() OR user_id = aegis_vault_keeper.rls_user_id());
+
+DROP POLICY IF EXISTS files_user_isolation ON aegis_vault_keeper.files;
+CREATE POLICY files_user_isolation ON aegis_vault_keeper.files
+    USING (aegis_vault_keeper.rls_admin_scope() OR user_id = aegis_vault_keeper.rls_user_id())
+    WITH CHECK (aegis_vault_keeper.rls_admin_scope() OR user_id = aegis_vault_keeper.rls_user_id());
+
+DROP POLICY IF EXISTS credentials_history_user_isolation ON aegis_vault_keeper.credentials_history;
+CREATE POLICY credentials_history_user_isolation ON aegis_vault_keeper.credentials_history
+    USING (aegis_vault_keeper.rls_admin_scope() OR user_id = aegis_vault_keeper.rls_user_id())
+    WITH CHECK (aegis_vault_keeper.rls_admin_scope() OR user_id = aegis_vault_keeper.rls_user_id());
+
+DROP POLICY IF EXISTS notes_history_user_isolation ON aegis_vault_keeper.notes_history;
+CREATE POLICY notes_history_user_isolation ON aegis_vault_keeper.notes_history
+    USING (aegis_vault_keeper.rls_admin_scope() OR user_id = aegis_vault_keeper.rls_user_id())
+    WITH CHECK (aegis_vault_keeper.rls_admin_scope() OR user_id = aegis_vault_keeper.rls_user_id());
+
+DROP POLICY IF EXISTS bank_cards_history_user_isolation ON aegis_vault_keeper.bank_cards_history;
+CREATE POLICY bank_cards_history_user_isolation ON aegis_vault_keeper.bank_cards_history
+    USING (aegis_vault_keeper.rls_admin_scope() OR user_id = aegis_vault_keeper.rls_user_id())
+    WITH CHECK (aegis_vault_keeper.rls_admin_scope() OR user_id = aegis_vault_keeper.rls_user_id());
+
+DROP POLICY IF EXISTS acme_accounts_user_isolation ON aegis_vault_keeper.acme_accounts;
+CREATE POLICY acme_accounts_user_isolation ON aegis_vault_keeper.acme_accounts
+    USING (aegis_vault_keeper.rls_admin_scope() OR user_id = aegis_vault_keeper.rls_user_id())
+    WITH CHECK (aegis_vault_keeper.rls_admin_scope() OR user_id = aegis_vault_keeper.rls_user_id());
+
+DROP POLICY IF EXISTS file_folders_user_isolation ON aegis_vault_keeper.file_folders;
+CREATE POLICY file_folders_user_isolation ON aegis_vault_keeper.file_folders
+    USING (aegis_vault_keeper.rls_admin_scope() OR user_id = aegis_vault_keeper.rls_user_id())
+    WITH CHECK (aegis_vault_keeper.rls_admin_scope() OR user_id = aegis_vault_keeper.rls_user_id());
+
+DROP POLICY IF EXISTS note_updates_user_isolation ON aegis_vault_keeper.note_updates;
+CREATE POLICY note_updates_user_isolation ON aegis_vault_keeper.note_updates
+    USING (aegis_vault_keeper.rls_admin_scope() OR user_id = aegis_vault_keeper.rls_user_id())
+    WITH CHECK (aegis_vault_keeper.rls_admin_scope() OR user_id = aegis_vault_keeper.rls_user_id());
@@ -0,0 +1,5 @@
+CREATE TABLE IF NOT EXISTS aegis_vault_keeper.row_signature_backfills
+(
+    table_name   TEXT      PRIMARY KEY,
+    completed_at TIMESTAMP NOT NULL
+);
@@ -0,0 +1,11 @@
+CREATE OR REPLACE FUNCTION aegis_vault_keeper.archive_item_version() RETURNS TRIGGER
+    LANGUAGE plpgsql AS
+$$
+BEGIN
+    EXECUTE format('INSERT INTO aegis_vault_keeper.%I SELECT ($1).*, now()', TG_TABLE_NAME || '_history')
+        USING OLD;
+    RETURN NEW;
+END
+$$;
+
+DROP TABLE IF EXISTS aegis_vault_keeper.field_binding_backfills;
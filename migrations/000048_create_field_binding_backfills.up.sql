@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS aegis_vault_keeper.field_binding_backfills
+(
+    table_name   TEXT      PRIMARY KEY,
+    completed_at TIMESTAMP NOT NULL
+);
+
+CREATE OR REPLACE FUNCTION aegis_vault_keeper.archive_item_version() RETURNS TRIGGER
+    LANGUAGE plpgsql AS
+$$
+BEGIN
+    IF COALESCE(current_setting('aegis_vault_keeper.skip_archive', true), '') = 'on' THEN
+        RETURN NEW;
+    END IF;
+    EXECUTE format('INSERT INTO aegis_vault_keeper.%I SELECT ($1).*, now()', TG_TABLE_NAME || '_history')
+        USING OLD;
+    RETURN NEW;
+END
+$$;
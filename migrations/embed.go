@@ -0,0 +1,10 @@
+// Package migrations embeds the database migrations into the binary, so the init command creates the schema
+// without the migration files on disk.
+package migrations
+
+import "embed"
+
+// FS holds the up and down migrations.
+//
+//go:embed *.sql
+var FS embed.FS
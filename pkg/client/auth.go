@@ -0,0 +1,110 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// credentialsRequest is the request body of registrations and logins.
+type credentialsRequest struct {
+	// Login contains the login of the user.
+	Login string `json:"login"`
+	// Password contains the password of the user.
+	Password string `json:"password"`
+}
+
+// challengeRequest is the request body confirming a held login.
+type challengeRequest struct {
+	// Code contains the verification code; empty for claims of approved logins.
+	Code string `json:"code,omitzero"`
+	// RecoveryCode contains a recovery code confirming the login in place of the verification code.
+	RecoveryCode string `json:"recovery_code,omitzero"`
+	// ChallengeID identifies the held login.
+	ChallengeID uuid.UUID `json:"challenge_id"`
+}
+
+// Register creates a user and returns its ID together with its recovery codes, which are shown only once.
+func (c *Client) Register(ctx context.Context, login, password string) (*Registration, error) {
+	// registration holds the decoded registration response.
+	var registration Registration
+	r := &request{method: http.MethodPost, path: "/api/auth/register", public: true}
+	if _, err := c.callJSON(ctx, r, credentialsRequest{Login: login, Password: password}, &registration); err != nil {
+		return nil, fmt.Errorf("failed to register: %w", err)
+	}
+	return &registration, nil
+}
+
+// Login authenticates the user and stores the issued access token in the client. Logins from new devices or
+// locations may be held: the result then carries the step-up challenge to confirm with VerifyLogin or ClaimLogin.
+func (c *Client) Login(ctx context.Context, login, password string) (*LoginResult, error) {
+	// resp holds the undecoded token or step-up challenge, told apart by the status code.
+	var resp json.RawMessage
+	r := &request{method: http.MethodPost, path: "/api/auth/login", public: true}
+	status, err := c.callJSON(ctx, r, credentialsRequest{Login: login, Password: password}, &resp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to log in: %w", err)
+	}
+
+	if status == http.StatusAccepted {
+		// stepUp holds the decoded challenge of the held login.
+		var stepUp StepUp
+		if err := json.Unmarshal(resp, &stepUp); err != nil {
+			return nil, fmt.Errorf("failed to decode step-up challenge: %w", err)
+		}
+		return &LoginResult{StepUp: &stepUp}, nil
+	}
+
+	// token holds the decoded access token.
+	var token Token
+	if err := json.Unmarshal(resp, &token); err != nil {
+		return nil, fmt.Errorf("failed to decode access token: %w", err)
+	}
+	c.setToken(token)
+	return &LoginResult{Token: &token}, nil
+}
+
+// VerifyLogin completes a held login with the verification code sent to the user and stores the issued
+// access token in the client.
+func (c *Client) VerifyLogin(ctx context.Context, challengeID uuid.UUID, code string) (*Token, error) {
+	token, err := c.completeLogin(ctx, "/api/auth/login/verify", challengeRequest{ChallengeID: challengeID, Code: code})
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify login: %w", err)
+	}
+	return token, nil
+}
+
+// VerifyLoginWithRecoveryCode completes a held login with one of the recovery codes of the user in place of the
+// verification code and stores the issued access token in the client. The recovery code is used up.
+func (c *Client) VerifyLoginWithRecoveryCode(ctx context.Context, challengeID uuid.UUID, code string) (*Token, error) {
+	body := challengeRequest{ChallengeID: challengeID, RecoveryCode: code}
+	token, err := c.completeLogin(ctx, "/api/auth/login/verify", body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify login with recovery code: %w", err)
+	}
+	return token, nil
+}
+
+// ClaimLogin completes a held login approved from another device and stores the issued access token in the
+// client. It fails with 409 Conflict while the login is not approved yet.
+func (c *Client) ClaimLogin(ctx context.Context, challengeID uuid.UUID) (*Token, error) {
+	token, err := c.completeLogin(ctx, "/api/auth/login/claim", challengeRequest{ChallengeID: challengeID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim login: %w", err)
+	}
+	return token, nil
+}
+
+// completeLogin sends the confirmation of a held login and stores the issued access token.
+func (c *Client) completeLogin(ctx context.Context, path string, body challengeRequest) (*Token, error) {
+	// token holds the decoded access token.
+	var token Token
+	if _, err := c.callJSON(ctx, &request{method: http.MethodPost, path: path, public: true}, body, &token); err != nil {
+		return nil, err
+	}
+	c.setToken(token)
+	return &token, nil
+}
@@ -0,0 +1,112 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_Login(t *testing.T) {
+	t.Parallel()
+
+	challengeID := uuid.New()
+	expiresAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		response   any
+		name       string
+		status     int
+		wantStepUp bool
+	}{
+		{
+			name:     "token",
+			status:   http.StatusOK,
+			response: Token{AccessToken: "token", ExpiresAt: expiresAt, TokenType: "Bearer"},
+		},
+		{
+			name:       "held login",
+			status:     http.StatusAccepted,
+			response:   StepUp{ChallengeID: challengeID, ExpiresAt: expiresAt, Reasons: []string{"new_location"}},
+			wantStepUp: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/api/auth/login", r.URL.Path)
+				assert.Empty(t, r.Header.Get("Authorization"))
+				// body holds the decoded login request.
+				var body credentialsRequest
+				assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+				assert.Equal(t, credentialsRequest{Login: "alice", Password: "secret"}, body)
+				writeJSON(t, w, tt.status, tt.response)
+			})
+
+			res, err := c.Login(context.Background(), "alice", "secret")
+			require.NoError(t, err)
+			if tt.wantStepUp {
+				require.NotNil(t, res.StepUp)
+				assert.Nil(t, res.Token)
+				assert.Equal(t, challengeID, res.StepUp.ChallengeID)
+				assert.Equal(t, expiresAt, res.StepUp.ExpiresAt)
+				assert.Empty(t, c.Token().AccessToken)
+				return
+			}
+			require.NotNil(t, res.Token)
+			assert.Equal(t, expiresAt, res.Token.ExpiresAt)
+			assert.Equal(t, "token", c.Token().AccessToken)
+		})
+	}
+}
+
+func TestClient_CompleteLogin(t *testing.T) {
+	t.Parallel()
+
+	challengeID := uuid.New()
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		// body holds the decoded confirmation.
+		var body map[string]string
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, challengeID.String(), body["challenge_id"])
+		switch r.URL.Path {
+		case "/api/auth/login/verify":
+			assert.Equal(t, "123456", body["code"])
+			writeJSON(t, w, http.StatusOK, Token{AccessToken: "verified"})
+		case "/api/auth/login/claim":
+			assert.NotContains(t, body, "code")
+			writeJSON(t, w, http.StatusConflict, APIError{Messages: []string{"Login is not approved yet"}})
+		}
+	})
+
+	_, err := c.ClaimLogin(context.Background(), challengeID)
+	require.True(t, IsStatus(err, http.StatusConflict))
+
+	token, err := c.VerifyLogin(context.Background(), challengeID, "123456")
+	require.NoError(t, err)
+	assert.Equal(t, "verified", token.AccessToken)
+	assert.Equal(t, "verified", c.Token().AccessToken)
+}
+
+func TestClient_Register(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/auth/register", r.URL.Path)
+		writeJSON(t, w, http.StatusCreated, map[string]any{"id": userID, "recovery_codes": []string{"abcd-efgh"}})
+	})
+
+	registration, err := c.Register(context.Background(), "alice", "secret")
+	require.NoError(t, err)
+	assert.Equal(t, userID, registration.ID)
+	assert.Equal(t, []string{"abcd-efgh"}, registration.RecoveryCodes)
+}
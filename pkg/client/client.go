@@ -0,0 +1,465 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdh"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// refreshMargin is how long before its expiry an access token is renewed.
+const refreshMargin = 30 * time.Second
+
+// headerSignature names the header carrying the HMAC signature of vault exports.
+const headerSignature = "X-Signature"
+
+// headerVaultUnlock names the header carrying the unlock key of vaults sealed with an unlock secret.
+const headerVaultUnlock = "X-Vault-Unlock"
+
+// signatureVersion names the request signing scheme supported by the server.
+const signatureVersion = "v1"
+
+// DefaultRetryPolicy is the retry policy used when none is configured.
+var DefaultRetryPolicy = RetryPolicy{MaxAttempts: 3, BaseDelay: 200 * time.Millisecond, MaxDelay: 5 * time.Second}
+
+// RetryPolicy configures retries of idempotent requests failing with transport errors or with
+// 429, 502, 503 or 504 responses.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts of a request; values below 2 disable retries.
+	MaxAttempts int
+	// BaseDelay is the delay before the first retry; it doubles on every further retry.
+	BaseDelay time.Duration
+	// MaxDelay caps the delay between attempts, including delays requested with Retry-After.
+	MaxDelay time.Duration
+}
+
+// delay returns the wait before the attempt following the given one, with up to 20% of jitter.
+// A positive server delay requested with Retry-After takes precedence over the backoff.
+func (p RetryPolicy) delay(attempt int, requested time.Duration) time.Duration {
+	d := requested
+	if d <= 0 {
+		d = p.BaseDelay << min(attempt-1, 16)
+		d += time.Duration(rand.Int64N(int64(d)/5 + 1))
+	}
+	if p.MaxDelay > 0 && d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	return d
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithHTTPClient sets the HTTP client sending the requests; http.DefaultClient is used by default.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = hc
+	}
+}
+
+// WithCredentials sets the login and password the client logs in with whenever it has no valid access token,
+// so expired or revoked tokens are renewed automatically.
+func WithCredentials(login, password string) Option {
+	return func(c *Client) {
+		c.login, c.password = login, password
+	}
+}
+
+// WithToken sets the access token of the client, for example one obtained earlier with Login.
+func WithToken(t Token) Option {
+	return func(c *Client) {
+		c.token = t
+	}
+}
+
+// WithSigningKey sets the request signing key the client signs vault exports with. Users holding signing keys
+// must sign them, so a leaked access token alone cannot export the vault.
+func WithSigningKey(keyID string, secret []byte) Option {
+	return func(c *Client) {
+		c.signingKeyID = keyID
+		c.signingSecret = bytes.Clone(secret)
+	}
+}
+
+// WithUnlockKey sets the unlock key the client sends with authenticated requests, for example one derived earlier
+// with DeriveUnlockKey. Vaults sealed with an unlock secret reject requests without it.
+func WithUnlockKey(key []byte) Option {
+	return func(c *Client) {
+		c.unlockKey = bytes.Clone(key)
+	}
+}
+
+// WithDeviceKey sets the registered device key the client asks the server to encrypt sync responses for, so
+// they can only be read with the private key of the device even where TLS is terminated by a proxy.
+// Sync responses that are not encrypted for the key are rejected.
+func WithDeviceKey(keyID string, key *ecdh.PrivateKey) Option {
+	return func(c *Client) {
+		c.deviceKeyID = keyID
+		c.deviceKey = key
+	}
+}
+
+// WithResponseKey sets the Ed25519 public key of the server, as published by ResponseSigningKey, that vault
+// exports, the sync manifest and recovery codes must be signed with. Responses without a valid, fresh signature
+// are rejected with ErrInvalidResponseSignature, so a compromised proxy cannot modify them unnoticed.
+func WithResponseKey(key ed25519.PublicKey) Option {
+	return func(c *Client) {
+		c.responseKey = key
+	}
+}
+
+// WithRetryPolicy sets the retry policy of idempotent requests; DefaultRetryPolicy is used by default.
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = p
+	}
+}
+
+// Client is a typed client of the AegisVaultKeeper HTTP API. It is safe for concurrent use.
+type Client struct {
+	// token contains the current access token; empty until a login.
+	token Token
+	// baseURL is the server address the API paths are resolved against.
+	baseURL *url.URL
+	// httpClient sends the requests.
+	httpClient *http.Client
+	// login contains the login used to renew access tokens; empty disables renewal.
+	login string
+	// password contains the password used to renew access tokens.
+	password string
+	// signingKeyID identifies the request signing key; empty disables signing.
+	signingKeyID string
+	// signingSecret contains the key material of the request signing key.
+	signingSecret []byte
+	// responseKey contains the public key signed responses are verified with; nil disables verification.
+	responseKey ed25519.PublicKey
+	// deviceKey contains the private key of the device key; nil disables encryption of sync responses.
+	deviceKey *ecdh.PrivateKey
+	// deviceKeyID identifies the device key sync responses are encrypted for.
+	deviceKeyID string
+	// unlockKey contains the unlock key of the vault; empty for vaults without an unlock secret.
+	unlockKey []byte
+	// retry configures retries of idempotent requests.
+	retry RetryPolicy
+	// mu guards token and unlockKey.
+	mu sync.Mutex
+	// loginMu serializes token renewals, so concurrent requests log in once.
+	loginMu sync.Mutex
+}
+
+// New creates a client of the server at baseURL, such as "https://vault.example.com".
+func New(baseURL string, opts ...Option) (*Client, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse base URL: %w", err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("base URL %q must be an absolute http or https URL", baseURL)
+	}
+
+	c := &Client{
+		baseURL:    u,
+		httpClient: http.DefaultClient,
+		retry:      DefaultRetryPolicy,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// Token returns the current access token of the client.
+func (c *Client) Token() Token {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.token
+}
+
+// setToken replaces the access token of the client.
+func (c *Client) setToken(t Token) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.token = t
+}
+
+// vaultUnlockKey returns the unlock key of the client.
+func (c *Client) vaultUnlockKey() []byte {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.unlockKey
+}
+
+// setUnlockKey replaces the unlock key of the client.
+func (c *Client) setUnlockKey(key []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.unlockKey = key
+}
+
+// request describes an API request.
+type request struct {
+	// query contains the query parameters of the request.
+	query url.Values
+	// header contains additional headers of the request.
+	header http.Header
+	// method is the HTTP method of the request.
+	method string
+	// path is the API path of the request, such as "/api/items/notes".
+	path string
+	// contentType is the media type of the body.
+	contentType string
+	// body contains the request body; it is kept in memory so retries and signatures can reuse it.
+	body []byte
+	// public determines whether the request is sent without an access token.
+	public bool
+	// signed determines whether the request is signed with the signing key, if one is configured.
+	signed bool
+	// sealed determines whether the response is encrypted for the device key, if one is configured.
+	sealed bool
+	// verified determines whether the response must be signed with the response key, if one is configured.
+	verified bool
+	// idempotent marks a request retried even though its method is not idempotent.
+	idempotent bool
+}
+
+// retryable reports whether the request may be sent again after the given attempt.
+func (c *Client) retryable(r *request, attempt int) bool {
+	if attempt >= c.retry.MaxAttempts {
+		return false
+	}
+	switch r.method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return r.idempotent
+	}
+}
+
+// do sends the request and returns the successful response; the caller closes its body.
+// Error responses are returned as *APIError, signed responses are verified and responses encrypted for the device
+// key are returned decrypted.
+// A request rejected with 401 is sent once more after renewing the access token, and idempotent requests failing
+// with transient errors are retried with backoff.
+func (c *Client) do(ctx context.Context, r *request) (*http.Response, error) {
+	renewed := false
+	for attempt := 1; ; attempt++ {
+		token, err := c.validToken(ctx, r.public)
+		if err != nil {
+			return nil, err
+		}
+
+		req, err := c.newHTTPRequest(ctx, r, token.AccessToken)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			if ctx.Err() != nil || !c.retryable(r, attempt) {
+				return nil, fmt.Errorf("%s %s failed: %w", r.method, r.path, err)
+			}
+			if err := sleep(ctx, c.retry.delay(attempt, 0)); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if resp.StatusCode == http.StatusUnauthorized && !r.public && !renewed && c.login != "" {
+			discard(resp)
+			renewed = true
+			if err := c.renewToken(ctx, token); err != nil {
+				return nil, err
+			}
+			// A renewal is not a retry of the request.
+			attempt--
+			continue
+		}
+
+		if isTransient(resp.StatusCode) && c.retryable(r, attempt) {
+			requested := retryAfter(resp)
+			discard(resp)
+			if err := sleep(ctx, c.retry.delay(attempt, requested)); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if resp.StatusCode >= http.StatusBadRequest {
+			defer discard(resp)
+			return nil, newAPIError(resp)
+		}
+		if r.verified && c.responseKey != nil {
+			if err := c.verifyResponse(resp); err != nil {
+				return nil, fmt.Errorf("%s %s failed: %w", r.method, r.path, err)
+			}
+		}
+		if r.sealed && c.deviceKey != nil {
+			if err := c.openSealed(resp); err != nil {
+				return nil, fmt.Errorf("%s %s failed: %w", r.method, r.path, err)
+			}
+		}
+		return resp, nil
+	}
+}
+
+// newHTTPRequest builds an attempt of the request authenticated with the access token.
+func (c *Client) newHTTPRequest(ctx context.Context, r *request, accessToken string) (*http.Request, error) {
+	u := c.baseURL.JoinPath(r.path)
+	u.RawQuery = r.query.Encode()
+
+	// body holds the request body of the attempt; nil for requests without one.
+	var body io.Reader
+	if r.body != nil {
+		body = bytes.NewReader(r.body)
+	}
+	req, err := http.NewRequestWithContext(ctx, r.method, u.String(), body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	for name, values := range r.header {
+		req.Header[name] = values
+	}
+	if r.contentType != "" {
+		req.Header.Set("Content-Type", r.contentType)
+	}
+	if !r.public && accessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+	}
+	if key := c.vaultUnlockKey(); !r.public && len(key) != 0 {
+		req.Header.Set(headerVaultUnlock, base64.StdEncoding.EncodeToString(key))
+	}
+	if r.signed && c.signingKeyID != "" {
+		req.Header.Set(headerSignature, c.sign(r.method, req.URL.RequestURI(), time.Now().Unix(), r.body))
+	}
+	if r.sealed && c.deviceKey != nil {
+		req.Header.Set(headerDeviceKey, c.deviceKeyID)
+	}
+	return req, nil
+}
+
+// sign returns the signature header of a request: the HMAC-SHA256 of the signing scheme, method, path with
+// query, timestamp and hex SHA-256 digest of the body, separated by newlines.
+func (c *Client) sign(method, requestURI string, timestamp int64, body []byte) string {
+	digest := sha256.Sum256(body)
+	ts := strconv.FormatInt(timestamp, 10)
+	canonical := strings.Join([]string{signatureVersion, method, requestURI, ts, hex.EncodeToString(digest[:])}, "\n")
+
+	mac := hmac.New(sha256.New, c.signingSecret)
+	mac.Write([]byte(canonical))
+	return "t=" + ts + ",key=" + c.signingKeyID + "," + signatureVersion + "=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// validToken returns the access token to send, logging in first when the client has credentials and no token
+// valid for at least refreshMargin. Public requests need no token.
+func (c *Client) validToken(ctx context.Context, public bool) (Token, error) {
+	if public {
+		return Token{}, nil
+	}
+	token := c.Token()
+	if c.login == "" || token.valid(time.Now().Add(refreshMargin)) {
+		return token, nil
+	}
+	if err := c.renewToken(ctx, token); err != nil {
+		return Token{}, err
+	}
+	return c.Token(), nil
+}
+
+// renewToken logs in with the credentials of the client unless another request already replaced the stale token.
+func (c *Client) renewToken(ctx context.Context, stale Token) error {
+	c.loginMu.Lock()
+	defer c.loginMu.Unlock()
+
+	if current := c.Token(); current.AccessToken != stale.AccessToken && current.valid(time.Now()) {
+		return nil
+	}
+	res, err := c.Login(ctx, c.login, c.password)
+	if err != nil {
+		return fmt.Errorf("failed to renew access token: %w", err)
+	}
+	if res.Token == nil {
+		return fmt.Errorf("failed to renew access token: %w", ErrStepUpRequired)
+	}
+	return nil
+}
+
+// callJSON sends the request with the JSON encoding of in, unless nil, and decodes the JSON response into out,
+// unless nil or the response has no content. It returns the status code of the response.
+func (c *Client) callJSON(ctx context.Context, r *request, in, out any) (int, error) {
+	if in != nil {
+		body, err := json.Marshal(in)
+		if err != nil {
+			return 0, fmt.Errorf("failed to encode request: %w", err)
+		}
+		r.body, r.contentType = body, "application/json"
+	}
+
+	resp, err := c.do(ctx, r)
+	if err != nil {
+		return 0, err
+	}
+	defer discard(resp)
+
+	if out != nil && resp.StatusCode != http.StatusNoContent {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return 0, fmt.Errorf("failed to decode %s %s response: %w", r.method, r.path, err)
+		}
+	}
+	return resp.StatusCode, nil
+}
+
+// isTransient reports whether a response status signals a failure worth retrying.
+func isTransient(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// retryAfter returns the delay requested with the Retry-After header of the response in seconds, or zero.
+func retryAfter(resp *http.Response) time.Duration {
+	seconds, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || seconds <= 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// sleep waits for the duration or until the context is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("retry aborted: %w", ctx.Err())
+	case <-t.C:
+		return nil
+	}
+}
+
+// discard drains and closes the body of the response, so the connection can be reused.
+func discard(resp *http.Response) {
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+	_ = resp.Body.Close()
+}
+
+// errNoContent indicates a response without the expected content.
+var errNoContent = errors.New("response has no content")
@@ -0,0 +1,284 @@
+package client
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fastRetries retries quickly, so tests do not wait for the default backoff.
+var fastRetries = RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond}
+
+// newTestClient starts a server with the handler and returns a client of it.
+func newTestClient(t *testing.T, h http.HandlerFunc, opts ...Option) *Client {
+	t.Helper()
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+
+	c, err := New(srv.URL, append([]Option{WithHTTPClient(srv.Client()), WithRetryPolicy(fastRetries)}, opts...)...)
+	require.NoError(t, err)
+	return c
+}
+
+// writeJSON writes the value as a JSON response with the status code.
+func writeJSON(t *testing.T, w http.ResponseWriter, status int, v any) {
+	t.Helper()
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	require.NoError(t, json.NewEncoder(w).Encode(v))
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		baseURL string
+		wantErr bool
+	}{
+		{name: "https", baseURL: "https://vault.example.com"},
+		{name: "http with path", baseURL: "http://localhost:8080/vault"},
+		{name: "relative", baseURL: "/api", wantErr: true},
+		{name: "unsupported scheme", baseURL: "ftp://vault.example.com", wantErr: true},
+		{name: "malformed", baseURL: "http://[::1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			c, err := New(tt.baseURL)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotNil(t, c)
+		})
+	}
+}
+
+func TestClient_RenewsToken(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		initial    Token
+		name       string
+		wantLogins int32
+	}{
+		{name: "no token", wantLogins: 1},
+		{
+			name:       "expiring token",
+			initial:    Token{AccessToken: "token-0", ExpiresAt: time.Now().Add(time.Second)},
+			wantLogins: 1,
+		},
+		{
+			name:       "revoked token",
+			initial:    Token{AccessToken: "revoked", ExpiresAt: time.Now().Add(time.Hour)},
+			wantLogins: 1,
+		},
+		{
+			name:       "valid token",
+			initial:    Token{AccessToken: "token-0", ExpiresAt: time.Now().Add(time.Hour)},
+			wantLogins: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// logins counts the logins received by the server.
+			var logins atomic.Int32
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/api/auth/login":
+					n := logins.Add(1)
+					writeJSON(t, w, http.StatusOK, Token{
+						AccessToken: "token-" + strconv.Itoa(int(n)),
+						ExpiresAt:   time.Now().Add(time.Hour),
+						TokenType:   "Bearer",
+					})
+				case "/api/items/notes":
+					if r.Header.Get("Authorization") == "Bearer revoked" {
+						writeJSON(t, w, http.StatusUnauthorized, APIError{Messages: []string{"Unauthorized"}})
+						return
+					}
+					writeJSON(t, w, http.StatusOK, map[string]any{"notes": []*Note{{Note: "hello"}}})
+				}
+			}, WithCredentials("alice", "secret"), WithToken(tt.initial))
+
+			notes, err := c.ListNotes(context.Background())
+			require.NoError(t, err)
+			require.Len(t, notes, 1)
+			assert.Equal(t, tt.wantLogins, logins.Load())
+			assert.NotEqual(t, "revoked", c.Token().AccessToken)
+		})
+	}
+}
+
+func TestClient_RenewsTokenOnce(t *testing.T) {
+	t.Parallel()
+
+	// logins counts the logins received by the server.
+	var logins atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/auth/login" {
+			logins.Add(1)
+			writeJSON(t, w, http.StatusOK, Token{AccessToken: "token", ExpiresAt: time.Now().Add(time.Hour)})
+			return
+		}
+		writeJSON(t, w, http.StatusUnauthorized, APIError{Messages: []string{"Unauthorized"}})
+	}, WithCredentials("alice", "secret"))
+
+	_, err := c.ListNotes(context.Background())
+	require.Error(t, err)
+	assert.True(t, IsStatus(err, http.StatusUnauthorized))
+	assert.Equal(t, int32(2), logins.Load(), "one login for the missing token and one renewal after the rejection")
+}
+
+func TestClient_StepUpBlocksRenewal(t *testing.T) {
+	t.Parallel()
+
+	c := newTestClient(t, func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(t, w, http.StatusAccepted, StepUp{Reasons: []string{"new_device"}})
+	}, WithCredentials("alice", "secret"))
+
+	_, err := c.ListNotes(context.Background())
+	require.ErrorIs(t, err, ErrStepUpRequired)
+}
+
+func TestClient_Retry(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		method       string
+		status       int
+		failures     int32
+		wantAttempts int32
+		wantErr      bool
+	}{
+		{name: "transient failures", method: http.MethodGet, status: http.StatusServiceUnavailable, failures: 2,
+			wantAttempts: 3},
+		{name: "rate limited", method: http.MethodPut, status: http.StatusTooManyRequests, failures: 1,
+			wantAttempts: 2},
+		{name: "attempts exhausted", method: http.MethodGet, status: http.StatusBadGateway, failures: 5,
+			wantAttempts: 3, wantErr: true},
+		{name: "client error", method: http.MethodGet, status: http.StatusNotFound, failures: 1,
+			wantAttempts: 1, wantErr: true},
+		{name: "non-idempotent request", method: http.MethodPost, status: http.StatusServiceUnavailable, failures: 1,
+			wantAttempts: 1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// attempts counts the requests received by the server.
+			var attempts atomic.Int32
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				body, err := io.ReadAll(r.Body)
+				assert.NoError(t, err)
+				assert.Equal(t, `{"note":"hello"}`, string(body), "every attempt carries the body")
+				if attempts.Add(1) <= tt.failures {
+					w.Header().Set("Retry-After", "1")
+					writeJSON(t, w, tt.status, APIError{Messages: []string{"try later"}})
+					return
+				}
+				writeJSON(t, w, http.StatusOK, map[string]string{"status": "ok"})
+			}, WithToken(Token{AccessToken: "token"}))
+
+			_, err := c.callJSON(context.Background(), &request{method: tt.method, path: "/api/items/notes"},
+				Note{Note: "hello"}, nil)
+			assert.Equal(t, tt.wantAttempts, attempts.Load())
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.True(t, IsStatus(err, tt.status))
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestClient_RetryHonorsContext(t *testing.T) {
+	t.Parallel()
+
+	c := newTestClient(t, func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}, WithRetryPolicy(RetryPolicy{MaxAttempts: 5, BaseDelay: time.Hour}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := c.ListNotes(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestClient_SignsExports(t *testing.T) {
+	t.Parallel()
+
+	secret := []byte("signing-secret")
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		header := r.Header.Get(headerSignature)
+		if r.URL.Path == "/api/items/sync/manifest" {
+			assert.Empty(t, header, "only exports are signed")
+			writeJSON(t, w, http.StatusOK, map[string]any{"items": []*ManifestItem{}})
+			return
+		}
+
+		// parts holds the fields of the signature header.
+		parts := map[string]string{}
+		for _, kv := range strings.Split(header, ",") {
+			k, v, _ := strings.Cut(kv, "=")
+			parts[k] = v
+		}
+		assert.Equal(t, "key-1", parts["key"])
+		digest := sha256.Sum256(nil)
+		canonical := strings.Join([]string{"v1", r.Method, r.URL.RequestURI(), parts["t"],
+			hex.EncodeToString(digest[:])}, "\n")
+		mac := hmac.New(sha256.New, secret)
+		mac.Write([]byte(canonical))
+		assert.Equal(t, hex.EncodeToString(mac.Sum(nil)), parts["v1"])
+
+		writeJSON(t, w, http.StatusOK, Vault{Notes: []*Note{{Note: "hello"}}})
+	}, WithToken(Token{AccessToken: "token"}), WithSigningKey("key-1", secret))
+
+	v, err := c.PullVault(context.Background())
+	require.NoError(t, err)
+	require.Len(t, v.Notes, 1)
+	_, err = c.PullVaultAsOf(context.Background(), time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC))
+	require.NoError(t, err)
+	_, err = c.Manifest(context.Background())
+	require.NoError(t, err)
+}
+
+func TestAPIError(t *testing.T) {
+	t.Parallel()
+
+	c := newTestClient(t, func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(t, w, http.StatusForbidden, APIError{Code: "outside_access_window", Messages: []string{"Denied"}})
+	})
+
+	_, err := c.ListCredentials(context.Background())
+	// apiErr holds the error response returned by the client.
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusForbidden, apiErr.StatusCode)
+	assert.Equal(t, "outside_access_window", apiErr.Code)
+	assert.Equal(t, []string{"Denied"}, apiErr.Messages)
+	assert.Contains(t, err.Error(), "403: Denied")
+}
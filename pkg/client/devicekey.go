@@ -0,0 +1,181 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/hkdf"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// headerDeviceKey names the header carrying the ID of the device key sync responses are encrypted for.
+const headerDeviceKey = "X-Device-Key"
+
+// deviceKeysPath is the API path of the device keys of the user.
+const deviceKeysPath = "/api/account/device-keys"
+
+// Parameters of the scheme the server encrypts sync responses for device keys with.
+const (
+	// devicePayloadAlgorithm names the scheme in sealed responses.
+	devicePayloadAlgorithm = "X25519-HKDF-SHA256-A256GCM"
+	// deviceWrapKeyInfo binds the key wrapping keys derived from the agreed secrets to the scheme.
+	deviceWrapKeyInfo = "aegis-vault-keeper device key wrap v1"
+	// deviceWrapKeySize is the size in bytes of the AES-256 key wrapping keys.
+	deviceWrapKeySize = 32
+)
+
+// errSealedPayloadMalformed indicates that a sealed response cannot be decrypted.
+var errSealedPayloadMalformed = errors.New("malformed sealed response")
+
+// sealedPayload is the response body of sync responses encrypted for a device key.
+type sealedPayload struct {
+	// KeyID identifies the device key the payload is sealed for.
+	KeyID string `json:"key_id"`
+	// Algorithm names the encryption scheme.
+	Algorithm string `json:"alg"`
+	// EphemeralKey contains the X25519 public key the key wrapping key was agreed with.
+	EphemeralKey []byte `json:"ephemeral_key"`
+	// WrappedKey contains the session key encrypted with the key wrapping key, the nonce prepended.
+	WrappedKey []byte `json:"wrapped_key"`
+	// Ciphertext contains the response body encrypted with the session key, the nonce prepended.
+	Ciphertext []byte `json:"ciphertext"`
+	// ContentType is the content type of the response body.
+	ContentType string `json:"content_type"`
+}
+
+// registerDeviceKeyRequest is the request body registering a device key.
+type registerDeviceKeyRequest struct {
+	// Name contains the label of the device.
+	Name string `json:"name"`
+	// PublicKey contains the X25519 public key of the device.
+	PublicKey []byte `json:"public_key"`
+}
+
+// RegisterDeviceKey registers the X25519 public key of a device under the name and returns the registered key.
+// Its ID and the private key are passed to WithDeviceKey to have sync responses encrypted for the device.
+func (c *Client) RegisterDeviceKey(ctx context.Context, name string, key *ecdh.PublicKey) (*DeviceKey, error) {
+	// k holds the decoded device key.
+	var k DeviceKey
+	r := &request{method: http.MethodPost, path: deviceKeysPath}
+	body := registerDeviceKeyRequest{Name: name, PublicKey: key.Bytes()}
+	if _, err := c.callJSON(ctx, r, body, &k); err != nil {
+		return nil, fmt.Errorf("failed to register device key: %w", err)
+	}
+	return &k, nil
+}
+
+// ListDeviceKeys returns the device keys of the user ordered by registration time.
+func (c *Client) ListDeviceKeys(ctx context.Context) ([]*DeviceKey, error) {
+	// resp holds the decoded device keys.
+	var resp struct {
+		// Keys contains the device keys.
+		Keys []*DeviceKey `json:"keys"`
+	}
+	if _, err := c.callJSON(ctx, &request{method: http.MethodGet, path: deviceKeysPath}, nil, &resp); err != nil {
+		return nil, fmt.Errorf("failed to list device keys: %w", err)
+	}
+	return resp.Keys, nil
+}
+
+// DeleteDeviceKey revokes a device key; requests naming it are rejected afterwards.
+func (c *Client) DeleteDeviceKey(ctx context.Context, id uuid.UUID) error {
+	r := &request{method: http.MethodDelete, path: deviceKeysPath + "/" + id.String()}
+	if _, err := c.callJSON(ctx, r, nil, nil); err != nil {
+		return fmt.Errorf("failed to delete device key %s: %w", id, err)
+	}
+	return nil
+}
+
+// openSealed replaces the body of a successful sync response with the payload decrypted with the device key.
+// Responses without a body carry nothing to protect; any other response must be sealed for the device key.
+func (c *Client) openSealed(resp *http.Response) error {
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return fmt.Errorf("failed to read sealed response: %w", err)
+	}
+	if len(body) == 0 {
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		return nil
+	}
+	if resp.Header.Get(headerDeviceKey) != c.deviceKeyID {
+		return ErrUnsealedResponse
+	}
+
+	// p holds the decoded sealed payload.
+	var p sealedPayload
+	if err := json.Unmarshal(body, &p); err != nil {
+		return fmt.Errorf("failed to decode sealed response: %w", err)
+	}
+	if p.KeyID != c.deviceKeyID || p.Algorithm != devicePayloadAlgorithm {
+		return ErrUnsealedResponse
+	}
+	plaintext, err := c.openPayload(&p)
+	if err != nil {
+		return err
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(plaintext))
+	resp.ContentLength = int64(len(plaintext))
+	resp.Header.Set("Content-Type", p.ContentType)
+	resp.Header.Del("Content-Length")
+	return nil
+}
+
+// openPayload unwraps the session key with the key agreed between the ephemeral key of the payload and the device
+// key, then decrypts the payload with it. The key ID is authenticated by both decryptions.
+func (c *Client) openPayload(p *sealedPayload) ([]byte, error) {
+	ephemeral, err := ecdh.X25519().NewPublicKey(p.EphemeralKey)
+	if err != nil {
+		return nil, errSealedPayloadMalformed
+	}
+	shared, err := c.deviceKey.ECDH(ephemeral)
+	if err != nil {
+		return nil, errSealedPayloadMalformed
+	}
+	salt := append(bytes.Clone(p.EphemeralKey), c.deviceKey.PublicKey().Bytes()...)
+	wrapKey, err := hkdf.Key(sha256.New, shared, salt, deviceWrapKeyInfo, deviceWrapKeySize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive key wrapping key: %w", err)
+	}
+
+	aad := []byte(p.KeyID)
+	sessionKey, err := openAESGCM(wrapKey, p.WrappedKey, aad)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap session key: %w", err)
+	}
+	plaintext, err := openAESGCM(sessionKey, p.Ciphertext, aad)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt sealed response: %w", err)
+	}
+	return plaintext, nil
+}
+
+// openAESGCM decrypts AES-GCM ciphertext prefixed with its nonce.
+func openAESGCM(key, data, aad []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCM: %w", err)
+	}
+	if len(data) < gcm.NonceSize() {
+		return nil, errSealedPayloadMalformed
+	}
+	plaintext, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], aad)
+	if err != nil {
+		return nil, errSealedPayloadMalformed
+	}
+	return plaintext, nil
+}
@@ -0,0 +1,132 @@
+package client
+
+import (
+	"context"
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gdyunin/aegis-vault-keeper/internal/server/crypto"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeSealed writes the value as a JSON response sealed for the device key the way the server does.
+func writeSealed(t *testing.T, w http.ResponseWriter, keyID string, public []byte, v any) {
+	t.Helper()
+	body, err := json.Marshal(v)
+	require.NoError(t, err)
+	envelope, err := crypto.SealForX25519(public, body, []byte(keyID))
+	require.NoError(t, err)
+	w.Header().Set(headerDeviceKey, keyID)
+	writeJSON(t, w, http.StatusOK, sealedPayload{
+		KeyID:        keyID,
+		Algorithm:    devicePayloadAlgorithm,
+		EphemeralKey: envelope.EphemeralKey,
+		WrappedKey:   envelope.WrappedKey,
+		Ciphertext:   envelope.Ciphertext,
+		ContentType:  "application/json; charset=utf-8",
+	})
+}
+
+func TestClient_DeviceKeys(t *testing.T) {
+	t.Parallel()
+
+	id := uuid.New()
+	key, err := ecdh.X25519().GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	stored := &DeviceKey{ID: id, Name: "phone", PublicKey: key.PublicKey().Bytes(), CreatedAt: time.Now().UTC()}
+
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == deviceKeysPath:
+			body, err := io.ReadAll(r.Body)
+			assert.NoError(t, err)
+			// req holds the decoded registration.
+			var req registerDeviceKeyRequest
+			assert.NoError(t, json.Unmarshal(body, &req))
+			assert.Equal(t, "phone", req.Name)
+			assert.Equal(t, key.PublicKey().Bytes(), req.PublicKey)
+			writeJSON(t, w, http.StatusCreated, stored)
+		case r.Method == http.MethodGet && r.URL.Path == deviceKeysPath:
+			writeJSON(t, w, http.StatusOK, map[string]any{"keys": []*DeviceKey{stored}})
+		case r.Method == http.MethodDelete && r.URL.Path == deviceKeysPath+"/"+id.String():
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}, WithToken(Token{AccessToken: "token"}))
+
+	registered, err := c.RegisterDeviceKey(context.Background(), "phone", key.PublicKey())
+	require.NoError(t, err)
+	assert.Equal(t, id, registered.ID)
+
+	keys, err := c.ListDeviceKeys(context.Background())
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	assert.Equal(t, stored.PublicKey, keys[0].PublicKey)
+
+	require.NoError(t, c.DeleteDeviceKey(context.Background(), id))
+}
+
+func TestClient_OpensSealedResponses(t *testing.T) {
+	t.Parallel()
+
+	keyID := uuid.NewString()
+	key, err := ecdh.X25519().GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	other, err := ecdh.X25519().GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	tests := []struct {
+		handler func(t *testing.T, w http.ResponseWriter)
+		name    string
+		wantErr bool
+	}{
+		{
+			name: "sealed for the device key",
+			handler: func(t *testing.T, w http.ResponseWriter) {
+				writeSealed(t, w, keyID, key.PublicKey().Bytes(), Vault{Notes: []*Note{{Note: "hello"}}})
+			},
+		},
+		{
+			name: "plaintext",
+			handler: func(t *testing.T, w http.ResponseWriter) {
+				writeJSON(t, w, http.StatusOK, Vault{Notes: []*Note{{Note: "hello"}}})
+			},
+			wantErr: true,
+		},
+		{
+			name: "sealed for another key",
+			handler: func(t *testing.T, w http.ResponseWriter) {
+				writeSealed(t, w, keyID, other.PublicKey().Bytes(), Vault{Notes: []*Note{{Note: "hello"}}})
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, keyID, r.Header.Get(headerDeviceKey))
+				tt.handler(t, w)
+			}, WithToken(Token{AccessToken: "token"}), WithDeviceKey(keyID, key))
+
+			v, err := c.PullVault(context.Background())
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, v.Notes, 1)
+			assert.Equal(t, "hello", v.Notes[0].Note)
+		})
+	}
+}
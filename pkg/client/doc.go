@@ -0,0 +1,9 @@
+// Package client provides a typed Go client of the AegisVaultKeeper HTTP API.
+//
+// The client covers authentication, recovery codes and duress passwords, vault items, files, synchronization and
+// device keys.
+// It renews access tokens by logging in again with the configured credentials, retries idempotent requests failing
+// with transient errors with exponential backoff, signs vault exports with a request signing key, verifies
+// responses signed by the server, decrypts sync responses encrypted for a device key, unlocks vaults sealed with an
+// unlock secret and honors the context of every call.
+package client
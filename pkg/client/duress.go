@@ -0,0 +1,40 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// duressPasswordRequest is the request body setting or clearing the duress password.
+type duressPasswordRequest struct {
+	// Password contains the password confirming the change.
+	Password string `json:"password"`
+	// DuressPassword contains the new duress password; empty to turn duress logins off.
+	DuressPassword string `json:"duress_password,omitempty"`
+	// Alert requests a silent alert on logins with the duress password.
+	Alert bool `json:"alert"`
+}
+
+// DuressSettings returns whether the user has a duress password and whether its logins raise a silent alert.
+func (c *Client) DuressSettings(ctx context.Context) (*DuressSettings, error) {
+	// settings holds the decoded duress settings.
+	var settings DuressSettings
+	r := &request{method: http.MethodGet, path: "/api/auth/duress"}
+	if _, err := c.callJSON(ctx, r, nil, &settings); err != nil {
+		return nil, fmt.Errorf("failed to get duress settings: %w", err)
+	}
+	return &settings, nil
+}
+
+// SetDuressPassword sets the duress password of the user confirmed with the password. Logins with the duress
+// password open a decoy vault instead of the real one and raise a silent alert when alert is set.
+// An empty duress password turns duress logins off; the decoy vault is kept.
+func (c *Client) SetDuressPassword(ctx context.Context, password, duressPassword string, alert bool) error {
+	r := &request{method: http.MethodPut, path: "/api/auth/duress"}
+	body := duressPasswordRequest{Password: password, DuressPassword: duressPassword, Alert: alert}
+	if _, err := c.callJSON(ctx, r, body, nil); err != nil {
+		return fmt.Errorf("failed to set duress password: %w", err)
+	}
+	return nil
+}
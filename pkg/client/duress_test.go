@@ -0,0 +1,49 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_DuressPassword(t *testing.T) {
+	t.Parallel()
+
+	// stored holds the duress settings applied by the fake server.
+	var stored DuressSettings
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/auth/duress", r.URL.Path)
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		if r.Method == http.MethodGet {
+			writeJSON(t, w, http.StatusOK, stored)
+			return
+		}
+		assert.Equal(t, http.MethodPut, r.Method)
+		// body holds the decoded duress password request.
+		var body duressPasswordRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		if body.Password != "secret" {
+			writeJSON(t, w, http.StatusUnauthorized, APIError{Messages: []string{"Password mismatch"}})
+			return
+		}
+		stored = DuressSettings{Enabled: body.DuressPassword != "", Alert: body.Alert}
+		w.WriteHeader(http.StatusNoContent)
+	}, WithToken(Token{AccessToken: "token"}))
+
+	err := c.SetDuressPassword(context.Background(), "wrong", "decoy", true)
+	require.True(t, IsStatus(err, http.StatusUnauthorized))
+
+	require.NoError(t, c.SetDuressPassword(context.Background(), "secret", "decoy", true))
+	settings, err := c.DuressSettings(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, DuressSettings{Enabled: true, Alert: true}, *settings)
+
+	require.NoError(t, c.SetDuressPassword(context.Background(), "secret", "", false))
+	settings, err = c.DuressSettings(context.Background())
+	require.NoError(t, err)
+	assert.False(t, settings.Enabled)
+}
@@ -0,0 +1,55 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// ErrStepUpRequired indicates that a login is held until it is confirmed with a verification code
+// or approved from another device, so the client cannot renew its access token on its own.
+var ErrStepUpRequired = errors.New("login requires verification")
+
+// ErrInvalidResponseSignature indicates that a response lacks a valid, fresh signature of the response key.
+var ErrInvalidResponseSignature = errors.New("response signature is missing or invalid")
+
+// ErrUnsealedResponse indicates that a sync response was not encrypted for the device key of the client.
+var ErrUnsealedResponse = errors.New("response is not encrypted for the device key")
+
+// APIError is an error response of the server.
+type APIError struct {
+	// Code contains the stable machine-readable identifier of the error; empty for most errors.
+	Code string `json:"code,omitzero"`
+	// Messages contains the error descriptions returned by the server.
+	Messages []string `json:"messages"`
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int `json:"-"`
+}
+
+// Error returns the status code and the messages of the response.
+func (e *APIError) Error() string {
+	if len(e.Messages) == 0 {
+		return fmt.Sprintf("server responded with %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	}
+	return fmt.Sprintf("server responded with %d: %s", e.StatusCode, strings.Join(e.Messages, "; "))
+}
+
+// IsStatus reports whether err is an *APIError with the status code.
+func IsStatus(err error, status int) bool {
+	// apiErr holds the error response found in the chain of err.
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == status
+}
+
+// newAPIError decodes the error response; bodies that are not JSON errors keep only the status code.
+func newAPIError(resp *http.Response) *APIError {
+	apiErr := &APIError{}
+	if body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<16)); err == nil {
+		_ = json.Unmarshal(body, apiErr)
+	}
+	apiErr.StatusCode = resp.StatusCode
+	return apiErr
+}
@@ -0,0 +1,241 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/google/uuid"
+)
+
+// Paths of the file vault endpoints.
+const (
+	// filesPath is the API path of the file collection.
+	filesPath = "/api/items/filedata/"
+	// foldersPath is the API path of the folder collection.
+	foldersPath = "/api/items/folders/"
+)
+
+// FileUpload is the content and metadata of an uploaded file.
+type FileUpload struct {
+	// StorageKey contains the name of the file; empty keeps the name of a replaced file.
+	StorageKey string
+	// Description contains optional notes about the file.
+	Description string
+	// Folder contains the path of an existing folder; empty keeps the folder of a replaced file.
+	Folder string
+	// Content contains the file content.
+	Content []byte
+}
+
+// FolderQuery selects a page of the contents of a folder.
+type FolderQuery struct {
+	// Path contains the path of the listed folder; empty lists the root folder.
+	Path string
+	// Offset is the number of files skipped.
+	Offset int
+	// Limit is the maximum number of files returned; 0 uses the default page size of the server.
+	Limit int
+}
+
+// UploadFile stores a new file and returns its ID.
+func (c *Client) UploadFile(ctx context.Context, upload *FileUpload) (uuid.UUID, error) {
+	id, err := c.pushFile(ctx, http.MethodPost, filesPath, upload)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to upload file: %w", err)
+	}
+	return id, nil
+}
+
+// ReplaceFile replaces the content and metadata of the file with the ID.
+func (c *Client) ReplaceFile(ctx context.Context, id uuid.UUID, upload *FileUpload) error {
+	if _, err := c.pushFile(ctx, http.MethodPut, filesPath+id.String(), upload); err != nil {
+		return fmt.Errorf("failed to replace file %s: %w", id, err)
+	}
+	return nil
+}
+
+// pushFile sends the file as a multipart form and returns the ID of the stored file.
+func (c *Client) pushFile(ctx context.Context, method, path string, upload *FileUpload) (uuid.UUID, error) {
+	// buf holds the encoded multipart form.
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	fields := [][2]string{
+		{"storage_key", upload.StorageKey},
+		{"description", upload.Description},
+		{"folder", upload.Folder},
+	}
+	for _, f := range fields {
+		if f[1] == "" {
+			continue
+		}
+		if err := w.WriteField(f[0], f[1]); err != nil {
+			return uuid.Nil, fmt.Errorf("failed to encode form field %s: %w", f[0], err)
+		}
+	}
+	name := upload.StorageKey
+	if name == "" {
+		name = "file"
+	}
+	part, err := w.CreateFormFile("file", name)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to encode file: %w", err)
+	}
+	if _, err := part.Write(upload.Content); err != nil {
+		return uuid.Nil, fmt.Errorf("failed to encode file: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return uuid.Nil, fmt.Errorf("failed to encode form: %w", err)
+	}
+
+	// resp holds the decoded ID of the stored file.
+	var resp pushResponse
+	r := &request{method: method, path: path, body: buf.Bytes(), contentType: w.FormDataContentType()}
+	if _, err := c.callJSON(ctx, r, nil, &resp); err != nil {
+		return uuid.Nil, err
+	}
+	return resp.ID, nil
+}
+
+// DownloadFile retrieves the file with the ID together with its content.
+func (c *Client) DownloadFile(ctx context.Context, id uuid.UUID) (*File, error) {
+	resp, err := c.do(ctx, &request{method: http.MethodGet, path: filesPath + id.String()})
+	if err != nil {
+		return nil, fmt.Errorf("failed to download file %s: %w", id, err)
+	}
+	defer discard(resp)
+
+	f, err := readDownload(resp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %s: %w", id, err)
+	}
+	return f, nil
+}
+
+// readDownload decodes a file download: a multipart form with the JSON metadata and the file content.
+func readDownload(resp *http.Response) (*File, error) {
+	_, params, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if err != nil || params["boundary"] == "" {
+		return nil, errors.New("response is not a multipart form")
+	}
+
+	// f holds the decoded file metadata and content.
+	var f *File
+	// content holds the file content read from the form.
+	var content []byte
+	r := multipart.NewReader(resp.Body, params["boundary"])
+	for {
+		part, err := r.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read form part: %w", err)
+		}
+		switch part.FormName() {
+		case "metadata":
+			if err := json.NewDecoder(part).Decode(&f); err != nil {
+				return nil, fmt.Errorf("failed to decode metadata: %w", err)
+			}
+		case "file":
+			if content, err = io.ReadAll(part); err != nil {
+				return nil, fmt.Errorf("failed to read content: %w", err)
+			}
+		}
+	}
+	if f == nil {
+		return nil, errors.New("response has no file metadata")
+	}
+	f.Data = content
+	return f, nil
+}
+
+// ListFiles retrieves the metadata of all files; an empty vault returns no files.
+func (c *Client) ListFiles(ctx context.Context) ([]*File, error) {
+	// resp holds the decoded file list.
+	var resp struct {
+		// Files contains the metadata of the files.
+		Files []*File `json:"files"`
+	}
+	if _, err := c.callJSON(ctx, &request{method: http.MethodGet, path: filesPath}, nil, &resp); err != nil {
+		return nil, fmt.Errorf("failed to list files: %w", err)
+	}
+	return resp.Files, nil
+}
+
+// MoveFile moves the file with the ID to an existing folder, the root folder when empty, and renames it
+// unless storageKey is empty.
+func (c *Client) MoveFile(ctx context.Context, id uuid.UUID, folder, storageKey string) (*File, error) {
+	// body holds the target folder and storage key.
+	body := struct {
+		// Folder contains the target folder.
+		Folder string `json:"folder"`
+		// StorageKey contains the new name of the file.
+		StorageKey string `json:"storage_key"`
+	}{Folder: folder, StorageKey: storageKey}
+	// f holds the decoded metadata of the moved file.
+	var f File
+	r := &request{method: http.MethodPost, path: filesPath + id.String() + "/move"}
+	if _, err := c.callJSON(ctx, r, body, &f); err != nil {
+		return nil, fmt.Errorf("failed to move file %s: %w", id, err)
+	}
+	return &f, nil
+}
+
+// ListFolder retrieves the subfolders and a page of the files of a folder.
+func (c *Client) ListFolder(ctx context.Context, q FolderQuery) (*FolderListing, error) {
+	query := url.Values{}
+	if q.Path != "" {
+		query.Set("path", q.Path)
+	}
+	if q.Offset > 0 {
+		query.Set("offset", strconv.Itoa(q.Offset))
+	}
+	if q.Limit > 0 {
+		query.Set("limit", strconv.Itoa(q.Limit))
+	}
+
+	// listing holds the decoded folder contents.
+	var listing FolderListing
+	r := &request{method: http.MethodGet, path: foldersPath, query: query}
+	if _, err := c.callJSON(ctx, r, nil, &listing); err != nil {
+		return nil, fmt.Errorf("failed to list folder %q: %w", q.Path, err)
+	}
+	return &listing, nil
+}
+
+// folderRequest is the request body of folder creations and moves.
+type folderRequest struct {
+	// Path contains the path of the folder.
+	Path string `json:"path"`
+}
+
+// CreateFolder creates a folder at the path; its parent folder must exist.
+func (c *Client) CreateFolder(ctx context.Context, path string) (*Folder, error) {
+	// f holds the decoded created folder.
+	var f Folder
+	r := &request{method: http.MethodPost, path: foldersPath}
+	if _, err := c.callJSON(ctx, r, folderRequest{Path: path}, &f); err != nil {
+		return nil, fmt.Errorf("failed to create folder %q: %w", path, err)
+	}
+	return &f, nil
+}
+
+// MoveFolder renames or moves the folder with the ID to the path, together with its contents.
+func (c *Client) MoveFolder(ctx context.Context, id uuid.UUID, path string) (*Folder, error) {
+	// f holds the decoded moved folder.
+	var f Folder
+	r := &request{method: http.MethodPut, path: foldersPath + id.String()}
+	if _, err := c.callJSON(ctx, r, folderRequest{Path: path}, &f); err != nil {
+		return nil, fmt.Errorf("failed to move folder %s: %w", id, err)
+	}
+	return &f, nil
+}
@@ -0,0 +1,148 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_UploadFile(t *testing.T) {
+	t.Parallel()
+
+	id := uuid.New()
+
+	tests := []struct {
+		upload   *FileUpload
+		call     func(c *Client, upload *FileUpload) error
+		name     string
+		method   string
+		path     string
+		wantName string
+	}{
+		{
+			name:     "upload",
+			method:   http.MethodPost,
+			path:     "/api/items/filedata/",
+			upload:   &FileUpload{StorageKey: "tax.pdf", Description: "2025", Folder: "docs", Content: []byte("%PDF")},
+			wantName: "tax.pdf",
+			call: func(c *Client, upload *FileUpload) error {
+				got, err := c.UploadFile(context.Background(), upload)
+				assert.Equal(t, id, got)
+				return err
+			},
+		},
+		{
+			name:     "replace keeping the name",
+			method:   http.MethodPut,
+			path:     "/api/items/filedata/" + id.String(),
+			upload:   &FileUpload{Content: []byte("%PDF-2")},
+			wantName: "file",
+			call: func(c *Client, upload *FileUpload) error {
+				return c.ReplaceFile(context.Background(), id, upload)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, tt.method, r.Method)
+				assert.Equal(t, tt.path, r.URL.Path)
+				assert.NoError(t, r.ParseMultipartForm(1<<20))
+				assert.Equal(t, tt.upload.StorageKey, r.FormValue("storage_key"))
+				assert.Equal(t, tt.upload.Description, r.FormValue("description"))
+				assert.Equal(t, tt.upload.Folder, r.FormValue("folder"))
+				file, header, err := r.FormFile("file")
+				if assert.NoError(t, err) {
+					content, _ := io.ReadAll(file)
+					assert.Equal(t, tt.upload.Content, content)
+					assert.Equal(t, tt.wantName, header.Filename)
+				}
+				writeJSON(t, w, http.StatusCreated, map[string]uuid.UUID{"id": id})
+			}, WithToken(Token{AccessToken: "token"}))
+
+			require.NoError(t, tt.call(c, tt.upload))
+		})
+	}
+}
+
+func TestClient_DownloadFile(t *testing.T) {
+	t.Parallel()
+
+	id := uuid.New()
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/items/filedata/"+id.String(), r.URL.Path)
+
+		// buf holds the encoded download form.
+		var buf bytes.Buffer
+		mw := multipart.NewWriter(&buf)
+		metadata, err := json.Marshal(File{ID: id, StorageKey: "tax.pdf", Folder: "docs", Size: 4})
+		assert.NoError(t, err)
+		assert.NoError(t, mw.WriteField("metadata", string(metadata)))
+		part, err := mw.CreateFormFile("file", "tax.pdf")
+		assert.NoError(t, err)
+		_, _ = part.Write([]byte("%PDF"))
+		assert.NoError(t, mw.Close())
+
+		w.Header().Set("Content-Type", mw.FormDataContentType())
+		_, _ = w.Write(buf.Bytes())
+	}, WithToken(Token{AccessToken: "token"}))
+
+	f, err := c.DownloadFile(context.Background(), id)
+	require.NoError(t, err)
+	assert.Equal(t, id, f.ID)
+	assert.Equal(t, "tax.pdf", f.StorageKey)
+	assert.Equal(t, []byte("%PDF"), f.Data)
+}
+
+func TestClient_Folders(t *testing.T) {
+	t.Parallel()
+
+	id := uuid.New()
+	ctx := context.Background()
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/items/folders/":
+			assert.Equal(t, "limit=10&offset=20&path=docs", r.URL.RawQuery)
+			writeJSON(t, w, http.StatusOK, FolderListing{Folder: "docs", Total: 25, Offset: 20, Limit: 10})
+		case "POST /api/items/folders/":
+			writeJSON(t, w, http.StatusCreated, Folder{ID: id, Path: "docs/taxes"})
+		case "PUT /api/items/folders/" + id.String():
+			writeJSON(t, w, http.StatusOK, Folder{ID: id, Path: "archive/taxes"})
+		case "POST /api/items/filedata/" + id.String() + "/move":
+			// body holds the decoded move request.
+			var body map[string]string
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, map[string]string{"folder": "docs", "storage_key": "new.pdf"}, body)
+			writeJSON(t, w, http.StatusOK, File{ID: id, Folder: "docs", StorageKey: "new.pdf"})
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}, WithToken(Token{AccessToken: "token"}))
+
+	listing, err := c.ListFolder(ctx, FolderQuery{Path: "docs", Offset: 20, Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, 25, listing.Total)
+
+	folder, err := c.CreateFolder(ctx, "docs/taxes")
+	require.NoError(t, err)
+	assert.Equal(t, id, folder.ID)
+
+	folder, err = c.MoveFolder(ctx, id, "archive/taxes")
+	require.NoError(t, err)
+	assert.Equal(t, "archive/taxes", folder.Path)
+
+	f, err := c.MoveFile(ctx, id, "docs", "new.pdf")
+	require.NoError(t, err)
+	assert.Equal(t, "new.pdf", f.StorageKey)
+}
@@ -0,0 +1,299 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// itemEndpoint addresses the endpoints of an item type.
+type itemEndpoint struct {
+	// path is the API path of the item collection.
+	path string
+	// one is the response key of a single item.
+	one string
+	// many is the response key of item lists.
+	many string
+}
+
+// Endpoints of the item types.
+var (
+	// credentialsEndpoint addresses the credential endpoints.
+	credentialsEndpoint = itemEndpoint{path: "/api/items/credentials", one: "credential", many: "credentials"}
+	// bankCardsEndpoint addresses the bank card endpoints.
+	bankCardsEndpoint = itemEndpoint{path: "/api/items/bankcards", one: "bankcard", many: "bankcards"}
+	// notesEndpoint addresses the note endpoints.
+	notesEndpoint = itemEndpoint{path: "/api/items/notes", one: "note", many: "notes"}
+)
+
+// itemPath returns the API path of the item, followed by the suffix.
+func (e itemEndpoint) itemPath(id uuid.UUID, suffix string) string {
+	return e.path + "/" + id.String() + suffix
+}
+
+// pushResponse is the response of item creations and updates.
+type pushResponse struct {
+	// ID identifies the stored item.
+	ID uuid.UUID `json:"id"`
+}
+
+// fieldsQuery returns the query selecting the fields of read items; no fields select all of them.
+func fieldsQuery(fields []string) url.Values {
+	if len(fields) == 0 {
+		return nil
+	}
+	return url.Values{"fields": {strings.Join(fields, ",")}}
+}
+
+// timestampQuery returns the query addressing the state of an item at the moment.
+func timestampQuery(at time.Time) url.Values {
+	return url.Values{"timestamp": {at.UTC().Format(time.RFC3339Nano)}}
+}
+
+// createItem stores a new item and returns its ID.
+func createItem[T any](ctx context.Context, c *Client, e itemEndpoint, item *T) (uuid.UUID, error) {
+	// resp holds the decoded ID of the created item.
+	var resp pushResponse
+	if _, err := c.callJSON(ctx, &request{method: http.MethodPost, path: e.path}, item, &resp); err != nil {
+		return uuid.Nil, fmt.Errorf("failed to create %s: %w", e.one, err)
+	}
+	return resp.ID, nil
+}
+
+// updateItem replaces the item with the ID.
+func updateItem[T any](ctx context.Context, c *Client, e itemEndpoint, id uuid.UUID, item *T) error {
+	if _, err := c.callJSON(ctx, &request{method: http.MethodPut, path: e.itemPath(id, "")}, item, nil); err != nil {
+		return fmt.Errorf("failed to update %s %s: %w", e.one, id, err)
+	}
+	return nil
+}
+
+// getItem retrieves the item from the path, keyed by the singular item name in the response.
+func getItem[T any](ctx context.Context, c *Client, e itemEndpoint, r *request) (*T, error) {
+	// resp holds the decoded response keyed by the item name.
+	var resp map[string]*T
+	if _, err := c.callJSON(ctx, r, nil, &resp); err != nil {
+		return nil, err
+	}
+	item := resp[e.one]
+	if item == nil {
+		return nil, fmt.Errorf("%s %s: %w", r.method, r.path, errNoContent)
+	}
+	return item, nil
+}
+
+// listItems retrieves all items of the type; an empty vault returns no items.
+func listItems[T any](ctx context.Context, c *Client, e itemEndpoint, fields []string) ([]*T, error) {
+	// resp holds the decoded response keyed by the plural item name.
+	var resp map[string][]*T
+	r := &request{method: http.MethodGet, path: e.path, query: fieldsQuery(fields)}
+	if _, err := c.callJSON(ctx, r, nil, &resp); err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", e.many, err)
+	}
+	return resp[e.many], nil
+}
+
+// CreateCredential stores a new credential and returns its ID.
+func (c *Client) CreateCredential(ctx context.Context, cred *Credential) (uuid.UUID, error) {
+	return createItem(ctx, c, credentialsEndpoint, cred)
+}
+
+// UpdateCredential replaces the credential with the ID.
+func (c *Client) UpdateCredential(ctx context.Context, id uuid.UUID, cred *Credential) error {
+	return updateItem(ctx, c, credentialsEndpoint, id, cred)
+}
+
+// GetCredential retrieves the credential with the ID; fields, if any, select the returned fields.
+func (c *Client) GetCredential(ctx context.Context, id uuid.UUID, fields ...string) (*Credential, error) {
+	r := &request{method: http.MethodGet, path: credentialsEndpoint.itemPath(id, ""), query: fieldsQuery(fields)}
+	cred, err := getItem[Credential](ctx, c, credentialsEndpoint, r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get credential %s: %w", id, err)
+	}
+	return cred, nil
+}
+
+// ListCredentials retrieves all credentials; fields, if any, select the returned fields.
+func (c *Client) ListCredentials(ctx context.Context, fields ...string) ([]*Credential, error) {
+	return listItems[Credential](ctx, c, credentialsEndpoint, fields)
+}
+
+// GetCredentialAsOf retrieves the version of the credential current at the moment.
+func (c *Client) GetCredentialAsOf(ctx context.Context, id uuid.UUID, at time.Time) (*Credential, error) {
+	r := &request{method: http.MethodGet, path: credentialsEndpoint.itemPath(id, "/as-of"), query: timestampQuery(at)}
+	cred, err := getItem[Credential](ctx, c, credentialsEndpoint, r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get credential %s as of %s: %w", id, at, err)
+	}
+	return cred, nil
+}
+
+// RecoverCredential restores the version of the credential current at the moment and returns the result.
+func (c *Client) RecoverCredential(ctx context.Context, id uuid.UUID, at time.Time) (*Credential, error) {
+	r := &request{method: http.MethodPost, path: credentialsEndpoint.itemPath(id, "/recover"), query: timestampQuery(at)}
+	cred, err := getItem[Credential](ctx, c, credentialsEndpoint, r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to recover credential %s: %w", id, err)
+	}
+	return cred, nil
+}
+
+// CreateBankCard stores a new bank card and returns its ID.
+func (c *Client) CreateBankCard(ctx context.Context, card *BankCard) (uuid.UUID, error) {
+	return createItem(ctx, c, bankCardsEndpoint, card)
+}
+
+// UpdateBankCard replaces the bank card with the ID.
+func (c *Client) UpdateBankCard(ctx context.Context, id uuid.UUID, card *BankCard) error {
+	return updateItem(ctx, c, bankCardsEndpoint, id, card)
+}
+
+// GetBankCard retrieves the bank card with the ID; fields, if any, select the returned fields.
+func (c *Client) GetBankCard(ctx context.Context, id uuid.UUID, fields ...string) (*BankCard, error) {
+	r := &request{method: http.MethodGet, path: bankCardsEndpoint.itemPath(id, ""), query: fieldsQuery(fields)}
+	card, err := getItem[BankCard](ctx, c, bankCardsEndpoint, r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bank card %s: %w", id, err)
+	}
+	return card, nil
+}
+
+// ListBankCards retrieves all bank cards; fields, if any, select the returned fields.
+func (c *Client) ListBankCards(ctx context.Context, fields ...string) ([]*BankCard, error) {
+	return listItems[BankCard](ctx, c, bankCardsEndpoint, fields)
+}
+
+// GetBankCardAsOf retrieves the version of the bank card current at the moment.
+func (c *Client) GetBankCardAsOf(ctx context.Context, id uuid.UUID, at time.Time) (*BankCard, error) {
+	r := &request{method: http.MethodGet, path: bankCardsEndpoint.itemPath(id, "/as-of"), query: timestampQuery(at)}
+	card, err := getItem[BankCard](ctx, c, bankCardsEndpoint, r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bank card %s as of %s: %w", id, at, err)
+	}
+	return card, nil
+}
+
+// RecoverBankCard restores the version of the bank card current at the moment and returns the result.
+func (c *Client) RecoverBankCard(ctx context.Context, id uuid.UUID, at time.Time) (*BankCard, error) {
+	r := &request{method: http.MethodPost, path: bankCardsEndpoint.itemPath(id, "/recover"), query: timestampQuery(at)}
+	card, err := getItem[BankCard](ctx, c, bankCardsEndpoint, r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to recover bank card %s: %w", id, err)
+	}
+	return card, nil
+}
+
+// CreateNote stores a new note and returns its ID.
+func (c *Client) CreateNote(ctx context.Context, n *Note) (uuid.UUID, error) {
+	return createItem(ctx, c, notesEndpoint, n)
+}
+
+// UpdateNote replaces the note with the ID. Notes edited in merge mode only accept changes of the description;
+// push text updates with PushNoteUpdate instead.
+func (c *Client) UpdateNote(ctx context.Context, id uuid.UUID, n *Note) error {
+	return updateItem(ctx, c, notesEndpoint, id, n)
+}
+
+// GetNote retrieves the note with the ID; fields, if any, select the returned fields.
+func (c *Client) GetNote(ctx context.Context, id uuid.UUID, fields ...string) (*Note, error) {
+	r := &request{method: http.MethodGet, path: notesEndpoint.itemPath(id, ""), query: fieldsQuery(fields)}
+	n, err := getItem[Note](ctx, c, notesEndpoint, r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get note %s: %w", id, err)
+	}
+	return n, nil
+}
+
+// ListNotes retrieves all notes; fields, if any, select the returned fields.
+func (c *Client) ListNotes(ctx context.Context, fields ...string) ([]*Note, error) {
+	return listItems[Note](ctx, c, notesEndpoint, fields)
+}
+
+// GetNoteAsOf retrieves the version of the note current at the moment.
+func (c *Client) GetNoteAsOf(ctx context.Context, id uuid.UUID, at time.Time) (*Note, error) {
+	r := &request{method: http.MethodGet, path: notesEndpoint.itemPath(id, "/as-of"), query: timestampQuery(at)}
+	n, err := getItem[Note](ctx, c, notesEndpoint, r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get note %s as of %s: %w", id, at, err)
+	}
+	return n, nil
+}
+
+// RecoverNote restores the version of the note current at the moment and returns the result.
+func (c *Client) RecoverNote(ctx context.Context, id uuid.UUID, at time.Time) (*Note, error) {
+	r := &request{method: http.MethodPost, path: notesEndpoint.itemPath(id, "/recover"), query: timestampQuery(at)}
+	n, err := getItem[Note](ctx, c, notesEndpoint, r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to recover note %s: %w", id, err)
+	}
+	return n, nil
+}
+
+// noteUpdatesResponse is the response listing the text updates of a note.
+type noteUpdatesResponse struct {
+	// Updates contains the text updates in sequence order.
+	Updates []*NoteUpdate `json:"updates"`
+}
+
+// EnableNoteMerge switches the note to merge mode and returns its stored text updates.
+func (c *Client) EnableNoteMerge(ctx context.Context, id uuid.UUID) ([]*NoteUpdate, error) {
+	// resp holds the decoded text updates.
+	var resp noteUpdatesResponse
+	r := &request{method: http.MethodPost, path: notesEndpoint.itemPath(id, "/merge"), idempotent: true}
+	if _, err := c.callJSON(ctx, r, nil, &resp); err != nil {
+		return nil, fmt.Errorf("failed to enable merge mode of note %s: %w", id, err)
+	}
+	return resp.Updates, nil
+}
+
+// DisableNoteMerge switches the note back to plain edits, keeping the merged text.
+func (c *Client) DisableNoteMerge(ctx context.Context, id uuid.UUID) error {
+	r := &request{method: http.MethodDelete, path: notesEndpoint.itemPath(id, "/merge")}
+	if _, err := c.callJSON(ctx, r, nil, nil); err != nil {
+		return fmt.Errorf("failed to disable merge mode of note %s: %w", id, err)
+	}
+	return nil
+}
+
+// NoteUpdates retrieves the text updates of the note stored after the sequence number; 0 retrieves all of them.
+func (c *Client) NoteUpdates(ctx context.Context, id uuid.UUID, afterSeq int64) ([]*NoteUpdate, error) {
+	// resp holds the decoded text updates.
+	var resp noteUpdatesResponse
+	r := &request{method: http.MethodGet, path: notesEndpoint.itemPath(id, "/updates")}
+	if afterSeq > 0 {
+		r.query = url.Values{"after": {strconv.FormatInt(afterSeq, 10)}}
+	}
+	if _, err := c.callJSON(ctx, r, nil, &resp); err != nil {
+		return nil, fmt.Errorf("failed to get text updates of note %s: %w", id, err)
+	}
+	return resp.Updates, nil
+}
+
+// PushNoteUpdate merges the JSON encoded text update into the note and returns the note with the merged text
+// and the sequence number of the stored update. Updates merge idempotently, so failed pushes are retried.
+func (c *Client) PushNoteUpdate(ctx context.Context, id uuid.UUID, update json.RawMessage) (*Note, int64, error) {
+	// resp holds the decoded merged note and sequence number.
+	var resp struct {
+		// Note contains the note with the merged text.
+		Note *Note `json:"note"`
+		// Seq is the sequence number of the stored update.
+		Seq int64 `json:"seq"`
+	}
+	// body holds the request payload carrying the update.
+	body := struct {
+		// Update contains the text update.
+		Update json.RawMessage `json:"update"`
+	}{Update: update}
+	r := &request{method: http.MethodPost, path: notesEndpoint.itemPath(id, "/updates"), idempotent: true}
+	if _, err := c.callJSON(ctx, r, body, &resp); err != nil {
+		return nil, 0, fmt.Errorf("failed to push text update of note %s: %w", id, err)
+	}
+	return resp.Note, resp.Seq, nil
+}
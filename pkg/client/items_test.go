@@ -0,0 +1,207 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_Items(t *testing.T) {
+	t.Parallel()
+
+	id := uuid.New()
+	at := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	ctx := context.Background()
+
+	tests := []struct {
+		response  any
+		call      func(t *testing.T, c *Client)
+		name      string
+		method    string
+		path      string
+		query     string
+		body      string
+		status    int
+		wantRetry bool
+	}{
+		{
+			name:     "create credential",
+			method:   http.MethodPost,
+			path:     "/api/items/credentials",
+			body:     `{"login":"alice","password":"secret"}`,
+			status:   http.StatusCreated,
+			response: map[string]uuid.UUID{"id": id},
+			call: func(t *testing.T, c *Client) {
+				got, err := c.CreateCredential(ctx, &Credential{Login: "alice", Password: "secret"})
+				require.NoError(t, err)
+				assert.Equal(t, id, got)
+			},
+		},
+		{
+			name:     "update bank card",
+			method:   http.MethodPut,
+			path:     "/api/items/bankcards/" + id.String(),
+			body:     `{"card_number":"4242424242424242","expiry_month":"12","expiry_year":"2030","cvv":"123"}`,
+			status:   http.StatusCreated,
+			response: map[string]uuid.UUID{"id": id},
+			call: func(t *testing.T, c *Client) {
+				card := &BankCard{CardNumber: "4242424242424242", ExpiryMonth: "12", ExpiryYear: "2030", CVV: "123"}
+				require.NoError(t, c.UpdateBankCard(ctx, id, card))
+			},
+		},
+		{
+			name:     "get note with fields",
+			method:   http.MethodGet,
+			path:     "/api/items/notes/" + id.String(),
+			query:    "fields=description%2Cupdated_at",
+			status:   http.StatusOK,
+			response: map[string]*Note{"note": {ID: id, Description: "todo"}},
+			call: func(t *testing.T, c *Client) {
+				n, err := c.GetNote(ctx, id, "description", "updated_at")
+				require.NoError(t, err)
+				assert.Equal(t, "todo", n.Description)
+			},
+		},
+		{
+			name:     "list credentials",
+			method:   http.MethodGet,
+			path:     "/api/items/credentials",
+			status:   http.StatusOK,
+			response: map[string][]*Credential{"credentials": {{ID: id, Login: "alice"}}},
+			call: func(t *testing.T, c *Client) {
+				creds, err := c.ListCredentials(ctx)
+				require.NoError(t, err)
+				require.Len(t, creds, 1)
+				assert.Equal(t, "alice", creds[0].Login)
+			},
+		},
+		{
+			name:   "list empty bank cards",
+			method: http.MethodGet,
+			path:   "/api/items/bankcards",
+			status: http.StatusNoContent,
+			call: func(t *testing.T, c *Client) {
+				cards, err := c.ListBankCards(ctx)
+				require.NoError(t, err)
+				assert.Empty(t, cards)
+			},
+		},
+		{
+			name:     "credential as of",
+			method:   http.MethodGet,
+			path:     "/api/items/credentials/" + id.String() + "/as-of",
+			query:    "timestamp=2025-03-01T12%3A00%3A00Z",
+			status:   http.StatusOK,
+			response: map[string]*Credential{"credential": {ID: id, Login: "old"}},
+			call: func(t *testing.T, c *Client) {
+				cred, err := c.GetCredentialAsOf(ctx, id, at)
+				require.NoError(t, err)
+				assert.Equal(t, "old", cred.Login)
+			},
+		},
+		{
+			name:     "recover note",
+			method:   http.MethodPost,
+			path:     "/api/items/notes/" + id.String() + "/recover",
+			query:    "timestamp=2025-03-01T12%3A00%3A00Z",
+			status:   http.StatusOK,
+			response: map[string]*Note{"note": {ID: id, Note: "old"}},
+			call: func(t *testing.T, c *Client) {
+				n, err := c.RecoverNote(ctx, id, at)
+				require.NoError(t, err)
+				assert.Equal(t, "old", n.Note)
+			},
+		},
+		{
+			name:   "missing item in response",
+			method: http.MethodGet,
+			path:   "/api/items/bankcards/" + id.String(),
+			status: http.StatusOK,
+			call: func(t *testing.T, c *Client) {
+				_, err := c.GetBankCard(ctx, id)
+				require.ErrorIs(t, err, errNoContent)
+			},
+		},
+		{
+			name:     "note updates",
+			method:   http.MethodGet,
+			path:     "/api/items/notes/" + id.String() + "/updates",
+			query:    "after=41",
+			status:   http.StatusOK,
+			response: map[string][]*NoteUpdate{"updates": {{Update: json.RawMessage(`{"ops":[]}`), Seq: 42}}},
+			call: func(t *testing.T, c *Client) {
+				updates, err := c.NoteUpdates(ctx, id, 41)
+				require.NoError(t, err)
+				require.Len(t, updates, 1)
+				assert.Equal(t, int64(42), updates[0].Seq)
+			},
+		},
+		{
+			name:      "push note update",
+			method:    http.MethodPost,
+			path:      "/api/items/notes/" + id.String() + "/updates",
+			body:      `{"update":{"ops":[]}}`,
+			status:    http.StatusCreated,
+			response:  map[string]any{"note": Note{ID: id, Note: "merged"}, "seq": 43},
+			wantRetry: true,
+			call: func(t *testing.T, c *Client) {
+				n, seq, err := c.PushNoteUpdate(ctx, id, json.RawMessage(`{"ops":[]}`))
+				require.NoError(t, err)
+				assert.Equal(t, "merged", n.Note)
+				assert.Equal(t, int64(43), seq)
+			},
+		},
+		{
+			name:   "disable note merge",
+			method: http.MethodDelete,
+			path:   "/api/items/notes/" + id.String() + "/merge",
+			status: http.StatusNoContent,
+			call: func(t *testing.T, c *Client) {
+				require.NoError(t, c.DisableNoteMerge(ctx, id))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// failed determines whether the server already failed the request once.
+			failed := false
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, tt.method, r.Method)
+				assert.Equal(t, tt.path, r.URL.Path)
+				assert.Equal(t, tt.query, r.URL.RawQuery)
+				assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+				body, err := io.ReadAll(r.Body)
+				assert.NoError(t, err)
+				if tt.body != "" {
+					assert.JSONEq(t, tt.body, string(body))
+				}
+				if tt.wantRetry && !failed {
+					failed = true
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+				if tt.response == nil {
+					w.WriteHeader(tt.status)
+					if tt.status != http.StatusNoContent {
+						_, _ = w.Write([]byte(`{}`))
+					}
+					return
+				}
+				writeJSON(t, w, tt.status, tt.response)
+			}, WithToken(Token{AccessToken: "token"}))
+
+			tt.call(t, c)
+			assert.Equal(t, tt.wantRetry, failed)
+		})
+	}
+}
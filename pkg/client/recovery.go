@@ -0,0 +1,69 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// recoveryCodesResponse is the response body listing the recovery codes of the user.
+type recoveryCodesResponse struct {
+	// RecoveryCodes lists the newly generated recovery codes; empty when only the count is returned.
+	RecoveryCodes []string `json:"recovery_codes"`
+	// Remaining is the number of unused recovery codes.
+	Remaining int `json:"remaining"`
+}
+
+// regenerateRecoveryCodesRequest is the request body replacing the recovery codes.
+type regenerateRecoveryCodesRequest struct {
+	// Password contains the password confirming the change.
+	Password string `json:"password"`
+}
+
+// recoverAccountRequest is the request body resetting the password with a recovery code.
+type recoverAccountRequest struct {
+	// Login contains the login of the user.
+	Login string `json:"login"`
+	// RecoveryCode contains one of the unused recovery codes of the user.
+	RecoveryCode string `json:"recovery_code"`
+	// NewPassword contains the password replacing the forgotten one.
+	NewPassword string `json:"new_password"`
+}
+
+// RecoveryCodesLeft returns the number of unused recovery codes of the user.
+func (c *Client) RecoveryCodesLeft(ctx context.Context) (int, error) {
+	// resp holds the decoded recovery code count.
+	var resp recoveryCodesResponse
+	r := &request{method: http.MethodGet, path: "/api/auth/recovery-codes", verified: true}
+	if _, err := c.callJSON(ctx, r, nil, &resp); err != nil {
+		return 0, fmt.Errorf("failed to get recovery codes: %w", err)
+	}
+	return resp.Remaining, nil
+}
+
+// RegenerateRecoveryCodes replaces the recovery codes of the user with a new set confirmed with the password
+// and returns the new codes; the previous codes stop working.
+func (c *Client) RegenerateRecoveryCodes(ctx context.Context, password string) ([]string, error) {
+	// resp holds the decoded recovery codes.
+	var resp recoveryCodesResponse
+	r := &request{method: http.MethodPost, path: "/api/auth/recovery-codes", verified: true}
+	if _, err := c.callJSON(ctx, r, regenerateRecoveryCodesRequest{Password: password}, &resp); err != nil {
+		return nil, fmt.Errorf("failed to regenerate recovery codes: %w", err)
+	}
+	return resp.RecoveryCodes, nil
+}
+
+// RecoverAccount resets the forgotten password of the user with a recovery code and stores the issued access
+// token in the client. All other sessions of the user are signed out and the recovery code is used up.
+// The client keeps renewing tokens with its configured credentials, so they should carry the new password.
+func (c *Client) RecoverAccount(ctx context.Context, login, recoveryCode, newPassword string) (*Token, error) {
+	// token holds the decoded access token.
+	var token Token
+	r := &request{method: http.MethodPost, path: "/api/auth/recover", public: true}
+	body := recoverAccountRequest{Login: login, RecoveryCode: recoveryCode, NewPassword: newPassword}
+	if _, err := c.callJSON(ctx, r, body, &token); err != nil {
+		return nil, fmt.Errorf("failed to recover account: %w", err)
+	}
+	c.setToken(token)
+	return &token, nil
+}
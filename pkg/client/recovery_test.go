@@ -0,0 +1,83 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_RecoveryCodes(t *testing.T) {
+	t.Parallel()
+
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/auth/recovery-codes", r.URL.Path)
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		if r.Method == http.MethodGet {
+			writeJSON(t, w, http.StatusOK, recoveryCodesResponse{Remaining: 3})
+			return
+		}
+		// body holds the decoded regeneration request.
+		var body regenerateRecoveryCodesRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		if body.Password != "secret" {
+			writeJSON(t, w, http.StatusUnauthorized, APIError{Messages: []string{"Password mismatch"}})
+			return
+		}
+		writeJSON(t, w, http.StatusCreated, recoveryCodesResponse{RecoveryCodes: []string{"abcd-efgh"}, Remaining: 1})
+	}, WithToken(Token{AccessToken: "token"}))
+
+	left, err := c.RecoveryCodesLeft(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 3, left)
+
+	_, err = c.RegenerateRecoveryCodes(context.Background(), "wrong")
+	require.True(t, IsStatus(err, http.StatusUnauthorized))
+
+	codes, err := c.RegenerateRecoveryCodes(context.Background(), "secret")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"abcd-efgh"}, codes)
+}
+
+func TestClient_RecoverAccount(t *testing.T) {
+	t.Parallel()
+
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/auth/recover", r.URL.Path)
+		assert.Empty(t, r.Header.Get("Authorization"))
+		// body holds the decoded recovery request.
+		var body recoverAccountRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, recoverAccountRequest{Login: "alice", RecoveryCode: "abcd-efgh", NewPassword: "new"}, body)
+		writeJSON(t, w, http.StatusOK, Token{AccessToken: "recovered"})
+	})
+
+	token, err := c.RecoverAccount(context.Background(), "alice", "abcd-efgh", "new")
+	require.NoError(t, err)
+	assert.Equal(t, "recovered", token.AccessToken)
+	assert.Equal(t, "recovered", c.Token().AccessToken)
+}
+
+func TestClient_VerifyLoginWithRecoveryCode(t *testing.T) {
+	t.Parallel()
+
+	challengeID := uuid.New()
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/auth/login/verify", r.URL.Path)
+		// body holds the decoded confirmation.
+		var body map[string]string
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, challengeID.String(), body["challenge_id"])
+		assert.Equal(t, "abcd-efgh", body["recovery_code"])
+		assert.NotContains(t, body, "code")
+		writeJSON(t, w, http.StatusOK, Token{AccessToken: "verified"})
+	})
+
+	token, err := c.VerifyLoginWithRecoveryCode(context.Background(), challengeID, "abcd-efgh")
+	require.NoError(t, err)
+	assert.Equal(t, "verified", token.AccessToken)
+}
@@ -0,0 +1,56 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Reports available as CSV files.
+const (
+	// ReportBankCards lists the bank cards of the vault.
+	ReportBankCards = "bankcards"
+	// ReportCredentials lists the credentials of the vault.
+	ReportCredentials = "credentials"
+)
+
+// ReportOptions selects the columns of a CSV report.
+type ReportOptions struct {
+	// Fields lists the report columns in output order; empty selects every column but the secret ones.
+	Fields []string
+	// Password contains the account password confirming a report including secrets.
+	Password string
+	// IncludeSecrets allows secret columns such as passwords, card numbers and CVVs; it requires Password.
+	IncludeSecrets bool
+}
+
+// Report downloads the CSV report of the bank cards or credentials of the vault, one of ReportBankCards and
+// ReportCredentials.
+func (c *Client) Report(ctx context.Context, report string, opts ReportOptions) ([]byte, error) {
+	r := &request{
+		method: http.MethodGet,
+		path:   "/api/reports/" + url.PathEscape(report) + ".csv",
+		query:  url.Values{},
+	}
+	if len(opts.Fields) > 0 {
+		r.query.Set("fields", strings.Join(opts.Fields, ","))
+	}
+	if opts.IncludeSecrets {
+		r.query.Set("include_secrets", "true")
+		r.header = http.Header{"X-Confirm-Password": {opts.Password}}
+	}
+	resp, err := c.do(ctx, r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s report: %w", report, err)
+	}
+	defer discard(resp)
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s report: %w", report, err)
+	}
+	return data, nil
+}
@@ -0,0 +1,57 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_Report(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		opts         ReportOptions
+		name         string
+		report       string
+		wantPath     string
+		wantQuery    string
+		wantPassword string
+	}{
+		{
+			name:      "default columns",
+			report:    ReportCredentials,
+			wantPath:  "/api/reports/credentials.csv",
+			wantQuery: "",
+		},
+		{
+			name:         "selected columns with secrets",
+			report:       ReportBankCards,
+			opts:         ReportOptions{Fields: []string{"card_number", "cvv"}, Password: "secret", IncludeSecrets: true},
+			wantPath:     "/api/reports/bankcards.csv",
+			wantQuery:    "fields=card_number%2Ccvv&include_secrets=true",
+			wantPassword: "secret",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodGet, r.Method)
+				assert.Equal(t, tt.wantPath, r.URL.Path)
+				assert.Equal(t, tt.wantQuery, r.URL.RawQuery)
+				assert.Equal(t, tt.wantPassword, r.Header.Get("X-Confirm-Password"))
+				w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+				_, _ = w.Write([]byte("id\n1\n"))
+			}, WithToken(Token{AccessToken: "token"}))
+
+			data, err := c.Report(context.Background(), tt.report, tt.opts)
+			require.NoError(t, err)
+			assert.Equal(t, []byte("id\n1\n"), data)
+		})
+	}
+}
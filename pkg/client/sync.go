@@ -0,0 +1,166 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Paths of the synchronization endpoints.
+const (
+	// syncPath is the API path of the vault synchronization.
+	syncPath = "/api/items/sync"
+	// capturePath is the API path of item captures.
+	capturePath = "/api/items/capture"
+)
+
+// PullVault retrieves the whole vault with file contents; an empty vault returns an empty Vault.
+// The request is signed when the client has a signing key.
+func (c *Client) PullVault(ctx context.Context) (*Vault, error) {
+	// v holds the decoded vault.
+	var v Vault
+	r := &request{method: http.MethodGet, path: syncPath, signed: true, sealed: true}
+	if _, err := c.callJSON(ctx, r, nil, &v); err != nil {
+		return nil, fmt.Errorf("failed to pull vault: %w", err)
+	}
+	return &v, nil
+}
+
+// PullVaultAsOf retrieves the state of the whole vault at the moment.
+// The request is signed when the client has a signing key.
+func (c *Client) PullVaultAsOf(ctx context.Context, at time.Time) (*Vault, error) {
+	// v holds the decoded vault state.
+	var v Vault
+	r := &request{
+		method: http.MethodGet,
+		path:   "/api/items/vault/as-of",
+		query:  timestampQuery(at),
+		signed: true,
+		sealed: true,
+	}
+	if _, err := c.callJSON(ctx, r, nil, &v); err != nil {
+		return nil, fmt.Errorf("failed to pull vault as of %s: %w", at, err)
+	}
+	return &v, nil
+}
+
+// ExportKDBX exports the whole vault with file contents to a KeePass (KDBX 4) database encrypted with the
+// master password. The request is signed when the client has a signing key.
+func (c *Client) ExportKDBX(ctx context.Context, password string) ([]byte, error) {
+	return c.exportKDBX(ctx, http.Header{"X-Export-Password": {password}})
+}
+
+// ExportKDBXToRecipient exports the whole vault like ExportKDBX, but encrypts the database as a whole to an age
+// X25519 recipient ("age1...") or a base64-encoded OpenPGP public key instead of a master password; the database
+// inside has an empty master password. The result is an age file or a binary OpenPGP message respectively.
+func (c *Client) ExportKDBXToRecipient(ctx context.Context, recipient string) ([]byte, error) {
+	return c.exportKDBX(ctx, http.Header{"X-Export-Recipient": {recipient}})
+}
+
+// exportKDBX requests a KDBX export with the headers choosing its encryption.
+func (c *Client) exportKDBX(ctx context.Context, header http.Header) ([]byte, error) {
+	r := &request{
+		method:   http.MethodGet,
+		path:     "/api/items/export",
+		query:    url.Values{"format": {"kdbx"}},
+		header:   header,
+		signed:   true,
+		sealed:   true,
+		verified: true,
+	}
+	resp, err := c.do(ctx, r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export vault: %w", err)
+	}
+	defer discard(resp)
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read vault export: %w", err)
+	}
+	return data, nil
+}
+
+// PushVault stores all items of the vault at once; items with an ID replace the stored ones.
+func (c *Client) PushVault(ctx context.Context, v *Vault) error {
+	if _, err := c.callJSON(ctx, &request{method: http.MethodPost, path: syncPath, sealed: true}, v, nil); err != nil {
+		return fmt.Errorf("failed to push vault: %w", err)
+	}
+	return nil
+}
+
+// Manifest retrieves the versions and content digests of all items without their content, so clients can
+// find the items changed since their last synchronization.
+func (c *Client) Manifest(ctx context.Context) ([]*ManifestItem, error) {
+	// resp holds the decoded manifest.
+	var resp struct {
+		// Items contains the manifest entries.
+		Items []*ManifestItem `json:"items"`
+	}
+	r := &request{method: http.MethodGet, path: syncPath + "/manifest", sealed: true, verified: true}
+	if _, err := c.callJSON(ctx, r, nil, &resp); err != nil {
+		return nil, fmt.Errorf("failed to get manifest: %w", err)
+	}
+	return resp.Items, nil
+}
+
+// Replay applies the operations queued while offline in order and returns the outcome of every operation.
+// Conflicting, skipped and rejected operations are reported in the outcomes, not as errors.
+func (c *Client) Replay(ctx context.Context, ops []*ReplayOp) ([]*ReplayOutcome, error) {
+	// body holds the operations to replay.
+	body := struct {
+		// Ops contains the operations.
+		Ops []*ReplayOp `json:"ops"`
+	}{Ops: ops}
+	// resp holds the decoded outcomes.
+	var resp struct {
+		// Outcomes contains the outcomes in the order of the operations.
+		Outcomes []*ReplayOutcome `json:"outcomes"`
+	}
+	r := &request{method: http.MethodPost, path: syncPath + "/replay", sealed: true}
+	if _, err := c.callJSON(ctx, r, body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to replay operations: %w", err)
+	}
+	return resp.Outcomes, nil
+}
+
+// Capture stores an item together with its files atomically and returns their IDs.
+func (c *Client) Capture(ctx context.Context, capture *Capture) (*CaptureResult, error) {
+	metadata, err := json.Marshal(capture)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode capture metadata: %w", err)
+	}
+
+	// buf holds the encoded multipart form.
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	if err := w.WriteField("metadata", string(metadata)); err != nil {
+		return nil, fmt.Errorf("failed to encode capture metadata: %w", err)
+	}
+	for _, f := range capture.Files {
+		part, err := w.CreateFormFile("files", f.StorageKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode file %q: %w", f.StorageKey, err)
+		}
+		if _, err := part.Write(f.Content); err != nil {
+			return nil, fmt.Errorf("failed to encode file %q: %w", f.StorageKey, err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode capture: %w", err)
+	}
+
+	// result holds the decoded IDs of the stored item and files.
+	var result CaptureResult
+	r := &request{method: http.MethodPost, path: capturePath, body: buf.Bytes(), contentType: w.FormDataContentType()}
+	if _, err := c.callJSON(ctx, r, nil, &result); err != nil {
+		return nil, fmt.Errorf("failed to capture item: %w", err)
+	}
+	return &result, nil
+}
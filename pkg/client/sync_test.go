@@ -0,0 +1,135 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_Replay(t *testing.T) {
+	t.Parallel()
+
+	id := uuid.New()
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/api/items/sync/replay", r.URL.Path)
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"ops":[
+			{"op_id":"op-1","note":{"note":"Draft"}},
+			{"op_id":"op-2","parent_version":7,"credential":{"id":"`+id.String()+`","login":"alice"}}
+		]}`, string(body))
+		writeJSON(t, w, http.StatusOK, map[string]any{"outcomes": []*ReplayOutcome{
+			{OpID: "op-1", Status: "applied", ID: uuid.New(), Version: 8},
+			{OpID: "op-2", Status: "conflict", ID: id, Version: 9},
+		}})
+	}, WithToken(Token{AccessToken: "token"}))
+
+	outcomes, err := c.Replay(context.Background(), []*ReplayOp{
+		{OpID: "op-1", Note: &Note{Note: "Draft"}},
+		{OpID: "op-2", ParentVersion: 7, Credential: &Credential{ID: id, Login: "alice"}},
+	})
+	require.NoError(t, err)
+	require.Len(t, outcomes, 2)
+	assert.Equal(t, "conflict", outcomes[1].Status)
+	assert.Equal(t, int64(9), outcomes[1].Version)
+}
+
+func TestClient_ExportKDBX(t *testing.T) {
+	t.Parallel()
+
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "/api/items/export", r.URL.Path)
+		assert.Equal(t, "kdbx", r.URL.Query().Get("format"))
+		assert.Equal(t, "master password", r.Header.Get("X-Export-Password"))
+		w.Header().Set("Content-Type", "application/octet-stream")
+		_, _ = w.Write([]byte("kdbx file"))
+	}, WithToken(Token{AccessToken: "token"}))
+
+	data, err := c.ExportKDBX(context.Background(), "master password")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("kdbx file"), data)
+}
+
+func TestClient_ExportKDBXToRecipient(t *testing.T) {
+	t.Parallel()
+
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/items/export", r.URL.Path)
+		assert.Equal(t, "age1recipient", r.Header.Get("X-Export-Recipient"))
+		assert.Empty(t, r.Header.Get("X-Export-Password"))
+		w.Header().Set("Content-Type", "application/octet-stream")
+		_, _ = w.Write([]byte("age file"))
+	}, WithToken(Token{AccessToken: "token"}))
+
+	data, err := c.ExportKDBXToRecipient(context.Background(), "age1recipient")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("age file"), data)
+}
+
+func TestClient_PushVault(t *testing.T) {
+	t.Parallel()
+
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/api/items/sync", r.URL.Path)
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"notes":[{"note":"hello"}]}`, string(body))
+		w.WriteHeader(http.StatusNoContent)
+	}, WithToken(Token{AccessToken: "token"}))
+
+	require.NoError(t, c.PushVault(context.Background(), &Vault{Notes: []*Note{{Note: "hello"}}}))
+}
+
+func TestClient_Capture(t *testing.T) {
+	t.Parallel()
+
+	itemID, fileID := uuid.New(), uuid.New()
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/items/capture", r.URL.Path)
+		assert.NoError(t, r.ParseMultipartForm(1<<20))
+		assert.JSONEq(t,
+			`{"note":{"note":"Passport"},"files":[{"storage_key":"front.jpg","description":"","folder":"ids"}]}`,
+			r.FormValue("metadata"))
+		files := r.MultipartForm.File["files"]
+		if assert.Len(t, files, 1) {
+			f, err := files[0].Open()
+			assert.NoError(t, err)
+			content, _ := io.ReadAll(f)
+			assert.Equal(t, "jpeg", string(content))
+		}
+		writeJSON(t, w, http.StatusCreated, CaptureResult{ItemID: itemID, FileIDs: []uuid.UUID{fileID}})
+	}, WithToken(Token{AccessToken: "token"}))
+
+	res, err := c.Capture(context.Background(), &Capture{
+		Note:  &Note{Note: "Passport"},
+		Files: []*CaptureFile{{StorageKey: "front.jpg", Folder: "ids", Content: []byte("jpeg")}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, itemID, res.ItemID)
+	assert.Equal(t, []uuid.UUID{fileID}, res.FileIDs)
+}
+
+func TestClient_Manifest(t *testing.T) {
+	t.Parallel()
+
+	id := uuid.New()
+	c := newTestClient(t, func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(t, w, http.StatusOK, json.RawMessage(`{"items":[{"id":"`+id.String()+
+			`","type":"note","version":5,"digest":"ab","updated_at":"2025-01-01T00:00:00Z"}]}`))
+	}, WithToken(Token{AccessToken: "token"}))
+
+	items, err := c.Manifest(context.Background())
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, ManifestItem{ID: id, Type: "note", Version: 5, Digest: "ab",
+		UpdatedAt: items[0].UpdatedAt}, *items[0])
+}
@@ -0,0 +1,281 @@
+package client
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Registration is the outcome of a registration.
+type Registration struct {
+	// RecoveryCodes lists the single-use recovery codes of the user; they cannot be retrieved again.
+	RecoveryCodes []string `json:"recovery_codes"`
+	// ID identifies the created user.
+	ID uuid.UUID `json:"id"`
+}
+
+// Token is an access token issued by a login.
+type Token struct {
+	// ExpiresAt specifies when the token becomes invalid.
+	ExpiresAt time.Time `json:"expires_at"`
+	// AccessToken contains the JWT authenticating requests.
+	AccessToken string `json:"access_token"`
+	// TokenType contains the token type, always "Bearer".
+	TokenType string `json:"token_type"`
+}
+
+// valid reports whether the token is set and still valid at the moment.
+func (t Token) valid(at time.Time) bool {
+	return t.AccessToken != "" && (t.ExpiresAt.IsZero() || at.Before(t.ExpiresAt))
+}
+
+// StepUp describes a login held until it is confirmed with a verification code or approved from another device.
+type StepUp struct {
+	// ExpiresAt specifies when the verification code sent to the user expires.
+	ExpiresAt time.Time `json:"expires_at"`
+	// Reasons lists why the login was flagged ("new_device", "new_location").
+	Reasons []string `json:"reasons"`
+	// ChallengeID identifies the challenge to confirm with VerifyLogin or ClaimLogin.
+	ChallengeID uuid.UUID `json:"challenge_id"`
+}
+
+// LoginResult is the outcome of a login: an access token, or a step-up challenge for held logins.
+type LoginResult struct {
+	// Token contains the issued access token; nil for held logins.
+	Token *Token
+	// StepUp contains the challenge of a held login; nil when a token was issued.
+	StepUp *StepUp
+}
+
+// UnlockSettings describes how the unlock key of a vault is derived from its unlock secret.
+type UnlockSettings struct {
+	// KDF names the key derivation function, "argon2id".
+	KDF string `json:"kdf"`
+	// Salt contains the salt of the derivation; empty while the vault has no unlock secret.
+	Salt []byte `json:"salt,omitzero"`
+	// KeySize is the size in bytes of the derived unlock key.
+	KeySize uint32 `json:"key_size"`
+	// Time is the number of passes of the derivation over its memory.
+	Time uint32 `json:"time"`
+	// MemoryKiB is the memory used by the derivation in KiB.
+	MemoryKiB uint32 `json:"memory_kib"`
+	// Threads is the number of lanes of the derivation.
+	Threads uint8 `json:"threads"`
+	// Enabled reports whether the vault is sealed with an unlock secret.
+	Enabled bool `json:"enabled"`
+}
+
+// DuressSettings describes the duress password of a user.
+type DuressSettings struct {
+	// Enabled reports whether logins with the duress password open the decoy vault.
+	Enabled bool `json:"enabled"`
+	// Alert reports whether logins with the duress password raise a silent alert.
+	Alert bool `json:"alert"`
+}
+
+// Credential is a login and password pair stored in the vault.
+type Credential struct {
+	// UpdatedAt contains the time of the last change; set by the server.
+	UpdatedAt time.Time `json:"updated_at,omitzero"`
+	// Login contains the username, email or account identifier.
+	Login string `json:"login,omitzero"`
+	// Password contains the password.
+	Password string `json:"password,omitzero"`
+	// Description contains optional notes about the credential.
+	Description string `json:"description,omitzero"`
+	// ID identifies the credential; set by the server.
+	ID uuid.UUID `json:"id,omitzero"`
+}
+
+// BankCard is a payment card stored in the vault.
+type BankCard struct {
+	// UpdatedAt contains the time of the last change; set by the server.
+	UpdatedAt time.Time `json:"updated_at,omitzero"`
+	// CardNumber contains the card number.
+	CardNumber string `json:"card_number,omitzero"`
+	// CardHolder contains the name of the card holder.
+	CardHolder string `json:"card_holder,omitzero"`
+	// ExpiryMonth contains the expiry month ("01"-"12").
+	ExpiryMonth string `json:"expiry_month,omitzero"`
+	// ExpiryYear contains the expiry year.
+	ExpiryYear string `json:"expiry_year,omitzero"`
+	// CVV contains the card verification value.
+	CVV string `json:"cvv,omitzero"`
+	// Description contains optional notes about the card.
+	Description string `json:"description,omitzero"`
+	// ID identifies the card; set by the server.
+	ID uuid.UUID `json:"id,omitzero"`
+}
+
+// Note is a text note stored in the vault.
+type Note struct {
+	// UpdatedAt contains the time of the last change; set by the server.
+	UpdatedAt time.Time `json:"updated_at,omitzero"`
+	// Note contains the text of the note.
+	Note string `json:"note,omitzero"`
+	// Description contains optional notes about the note.
+	Description string `json:"description,omitzero"`
+	// ID identifies the note; set by the server.
+	ID uuid.UUID `json:"id,omitzero"`
+}
+
+// NoteUpdate is a text update of a note edited in merge mode.
+type NoteUpdate struct {
+	// Update contains the JSON encoded list of insert and delete operations, or a snapshot of the text.
+	Update json.RawMessage `json:"update"`
+	// Seq is the sequence number of the update.
+	Seq int64 `json:"seq"`
+	// Snapshot determines whether the update replaces all updates up to its sequence number.
+	Snapshot bool `json:"snapshot,omitzero"`
+}
+
+// File is the metadata of a file stored in the vault.
+type File struct {
+	// UpdatedAt contains the time of the last change.
+	UpdatedAt time.Time `json:"updated_at"`
+	// StorageKey contains the name of the file.
+	StorageKey string `json:"storage_key"`
+	// HashSum contains the hash of the file content.
+	HashSum string `json:"hash_sum"`
+	// Description contains optional notes about the file.
+	Description string `json:"description"`
+	// Folder contains the path of the folder holding the file; empty for the root folder.
+	Folder string `json:"folder"`
+	// Data contains the file content in vault exports; empty in file listings.
+	Data []byte `json:"data,omitempty"`
+	// ID identifies the file.
+	ID uuid.UUID `json:"id"`
+	// UserID identifies the owner of the file.
+	UserID uuid.UUID `json:"user_id"`
+	// Size contains the size of the file content in bytes.
+	Size int64 `json:"size"`
+}
+
+// Folder is a folder of the file vault.
+type Folder struct {
+	// UpdatedAt contains the time of the last change.
+	UpdatedAt time.Time `json:"updated_at"`
+	// Path contains the path of the folder.
+	Path string `json:"path"`
+	// ID identifies the folder.
+	ID uuid.UUID `json:"id"`
+}
+
+// FolderListing is a page of the contents of a folder.
+type FolderListing struct {
+	// Folder contains the path of the listed folder.
+	Folder string `json:"folder"`
+	// Folders contains the direct subfolders.
+	Folders []*Folder `json:"folders"`
+	// Files contains the page of files of the folder.
+	Files []*File `json:"files"`
+	// Total contains the number of files in the folder.
+	Total int `json:"total"`
+	// Offset contains the number of skipped files.
+	Offset int `json:"offset"`
+	// Limit contains the maximum number of files of the page.
+	Limit int `json:"limit"`
+}
+
+// Vault is the content of a vault exchanged by synchronization.
+type Vault struct {
+	// BankCards contains the bank cards of the vault.
+	BankCards []*BankCard `json:"bankcards,omitzero"`
+	// Credentials contains the credentials of the vault.
+	Credentials []*Credential `json:"credentials,omitzero"`
+	// Notes contains the notes of the vault.
+	Notes []*Note `json:"notes,omitzero"`
+	// Files contains the files of the vault with their content.
+	Files []*File `json:"files,omitzero"`
+}
+
+// ManifestItem describes an item of the vault without its content.
+type ManifestItem struct {
+	// UpdatedAt contains the time of the last change of the item.
+	UpdatedAt time.Time `json:"updated_at"`
+	// Type contains the item type ("bankcard", "credential", "note" or "file").
+	Type string `json:"type"`
+	// Digest contains the hex SHA-256 digest of the item content.
+	Digest string `json:"digest"`
+	// ID identifies the item.
+	ID uuid.UUID `json:"id"`
+	// Version is the version of the item to send as the parent version of offline changes.
+	Version int64 `json:"version"`
+}
+
+// ReplayOp is an operation queued by an offline client; exactly one item is set.
+type ReplayOp struct {
+	// BankCard contains the bank card to store.
+	BankCard *BankCard `json:"bankcard,omitempty"`
+	// Credential contains the credential to store.
+	Credential *Credential `json:"credential,omitempty"`
+	// Note contains the note to store.
+	Note *Note `json:"note,omitempty"`
+	// OpID identifies the operation within the batch.
+	OpID string `json:"op_id"`
+	// AfterOp names an earlier operation of the batch whose stored item this operation updates.
+	AfterOp string `json:"after_op,omitempty"`
+	// ParentVersion is the version of the item the change is based on; required for updates of items by ID.
+	ParentVersion int64 `json:"parent_version,omitempty"`
+}
+
+// ReplayOutcome is the outcome of a replayed operation.
+type ReplayOutcome struct {
+	// OpID identifies the operation.
+	OpID string `json:"op_id"`
+	// Status contains "applied", "conflict", "skipped" or "rejected".
+	Status string `json:"status"`
+	// Messages contains the errors of rejected operations.
+	Messages []string `json:"errors,omitempty"`
+	// ID identifies the stored item of applied operations.
+	ID uuid.UUID `json:"id,omitzero"`
+	// Version is the version of the stored item, or the current version of a conflicting one.
+	Version int64 `json:"version,omitzero"`
+	// Code is the status code of rejected operations.
+	Code int `json:"code,omitzero"`
+}
+
+// CaptureFile is the metadata of a file captured together with an item.
+type CaptureFile struct {
+	// StorageKey contains the name of the file.
+	StorageKey string `json:"storage_key"`
+	// Description contains optional notes about the file.
+	Description string `json:"description"`
+	// Folder contains the path of an existing folder; empty for the root folder.
+	Folder string `json:"folder"`
+	// Content contains the file content.
+	Content []byte `json:"-"`
+}
+
+// Capture is an item stored atomically together with its files; exactly one item is set.
+type Capture struct {
+	// BankCard contains the bank card to store.
+	BankCard *BankCard `json:"bankcard,omitempty"`
+	// Credential contains the credential to store.
+	Credential *Credential `json:"credential,omitempty"`
+	// Note contains the note to store.
+	Note *Note `json:"note,omitempty"`
+	// Files contains the files of the item.
+	Files []*CaptureFile `json:"files,omitempty"`
+}
+
+// CaptureResult identifies the stored item and files of a capture.
+type CaptureResult struct {
+	// FileIDs identifies the stored files in the order of the capture.
+	FileIDs []uuid.UUID `json:"file_ids"`
+	// ItemID identifies the stored item.
+	ItemID uuid.UUID `json:"item_id"`
+}
+
+// DeviceKey is the registered X25519 public key of a device that sync responses can be encrypted for.
+type DeviceKey struct {
+	// CreatedAt contains the time the key was registered.
+	CreatedAt time.Time `json:"created_at"`
+	// Name contains the label of the device holding the private key.
+	Name string `json:"name"`
+	// PublicKey contains the X25519 public key of the device.
+	PublicKey []byte `json:"public_key"`
+	// ID identifies the key; it is passed to WithDeviceKey.
+	ID uuid.UUID `json:"id"`
+}
@@ -0,0 +1,104 @@
+package client
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// unlockSaltSize is the size in bytes of the salts generated for new unlock secrets.
+const unlockSaltSize = 16
+
+// ErrVaultNotUnlocked indicates that the unlock secret of a sealed vault is changed before the client was unlocked.
+var ErrVaultNotUnlocked = errors.New("vault is not unlocked")
+
+// unlockSecretRequest is the request body setting or changing the unlock secret.
+type unlockSecretRequest struct {
+	// Salt contains the salt the new unlock key was derived with.
+	Salt []byte `json:"salt"`
+	// UnlockKey contains the unlock key derived from the new unlock secret.
+	UnlockKey []byte `json:"unlock_key"`
+	// CurrentUnlockKey contains the current unlock key; set when the unlock secret is changed.
+	CurrentUnlockKey []byte `json:"current_unlock_key,omitzero"`
+	// Password contains the password; set when the first unlock secret is set.
+	Password string `json:"password,omitzero"`
+}
+
+// DeriveUnlockKey derives the unlock key of a vault from its unlock secret with the settings of the vault.
+// Only the derived key is sent to the server, never the unlock secret.
+func DeriveUnlockKey(secret string, s UnlockSettings) ([]byte, error) {
+	if s.KDF != "argon2id" {
+		return nil, fmt.Errorf("unsupported unlock key derivation %q", s.KDF)
+	}
+	if len(s.Salt) == 0 {
+		return nil, errors.New("unlock key derivation requires a salt")
+	}
+	return argon2.IDKey([]byte(secret), s.Salt, s.Time, s.MemoryKiB, s.Threads, s.KeySize), nil
+}
+
+// UnlockSettings returns whether the vault is sealed with an unlock secret and how its unlock key is derived.
+func (c *Client) UnlockSettings(ctx context.Context) (*UnlockSettings, error) {
+	// settings holds the decoded unlock secret settings.
+	var settings UnlockSettings
+	r := &request{method: http.MethodGet, path: "/api/auth/unlock-secret"}
+	if _, err := c.callJSON(ctx, r, nil, &settings); err != nil {
+		return nil, fmt.Errorf("failed to get unlock settings: %w", err)
+	}
+	return &settings, nil
+}
+
+// Unlock derives the unlock key of the vault from the unlock secret and sends it with the following requests.
+// Vaults without an unlock secret need no unlocking.
+func (c *Client) Unlock(ctx context.Context, secret string) error {
+	settings, err := c.UnlockSettings(ctx)
+	if err != nil {
+		return err
+	}
+	if !settings.Enabled {
+		return nil
+	}
+	key, err := DeriveUnlockKey(secret, *settings)
+	if err != nil {
+		return fmt.Errorf("failed to unlock vault: %w", err)
+	}
+	c.setUnlockKey(key)
+	return nil
+}
+
+// SetUnlockSecret seals the vault with a new unlock secret kept apart from the password and unlocks the client
+// with it. The first unlock secret is confirmed with the password; changing it requires the client to be unlocked
+// with the current one, and the password is then ignored.
+func (c *Client) SetUnlockSecret(ctx context.Context, secret, password string) error {
+	settings, err := c.UnlockSettings(ctx)
+	if err != nil {
+		return err
+	}
+
+	body := unlockSecretRequest{Password: password}
+	if settings.Enabled {
+		if body.CurrentUnlockKey = c.vaultUnlockKey(); len(body.CurrentUnlockKey) == 0 {
+			return fmt.Errorf("failed to change unlock secret: %w", ErrVaultNotUnlocked)
+		}
+		body.Password = ""
+	}
+
+	settings.Salt = make([]byte, unlockSaltSize)
+	if _, err := rand.Read(settings.Salt); err != nil {
+		return fmt.Errorf("failed to generate unlock salt: %w", err)
+	}
+	if body.UnlockKey, err = DeriveUnlockKey(secret, *settings); err != nil {
+		return fmt.Errorf("failed to set unlock secret: %w", err)
+	}
+	body.Salt = settings.Salt
+
+	r := &request{method: http.MethodPut, path: "/api/auth/unlock-secret"}
+	if _, err := c.callJSON(ctx, r, body, nil); err != nil {
+		return fmt.Errorf("failed to set unlock secret: %w", err)
+	}
+	c.setUnlockKey(body.UnlockKey)
+	return nil
+}
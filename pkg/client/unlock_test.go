@@ -0,0 +1,136 @@
+package client
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testUnlockSettings are cheap derivation settings keeping the tests fast.
+var testUnlockSettings = UnlockSettings{KDF: "argon2id", KeySize: 32, Time: 1, MemoryKiB: 64, Threads: 1}
+
+func TestDeriveUnlockKey(t *testing.T) {
+	t.Parallel()
+
+	withSalt := testUnlockSettings
+	withSalt.Salt = []byte("0123456789abcdef")
+	otherKDF := withSalt
+	otherKDF.KDF = "scrypt"
+
+	tests := []struct {
+		name     string
+		settings UnlockSettings
+		wantErr  bool
+	}{
+		{name: "argon2id", settings: withSalt},
+		{name: "unsupported derivation", settings: otherKDF, wantErr: true},
+		{name: "missing salt", settings: testUnlockSettings, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			key, err := DeriveUnlockKey("unlock secret", tt.settings)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, key, 32)
+			again, err := DeriveUnlockKey("unlock secret", tt.settings)
+			require.NoError(t, err)
+			assert.Equal(t, key, again)
+			other, err := DeriveUnlockKey("other secret", tt.settings)
+			require.NoError(t, err)
+			assert.NotEqual(t, key, other)
+		})
+	}
+}
+
+func TestClient_SetUnlockSecret(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		wantErr      error
+		unlockKey    []byte
+		name         string
+		wantPassword string
+		enabled      bool
+		wantCurrent  bool
+	}{
+		{name: "first unlock secret", wantPassword: "password"},
+		{name: "changed unlock secret", enabled: true, unlockKey: []byte("current-key"), wantCurrent: true},
+		{name: "change while locked", enabled: true, wantErr: ErrVaultNotUnlocked},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// sent holds the decoded unlock secret request.
+			var sent unlockSecretRequest
+			// header holds the unlock key header of the last request.
+			var header string
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/api/auth/unlock-secret", r.URL.Path)
+				header = r.Header.Get(headerVaultUnlock)
+				if r.Method == http.MethodGet {
+					settings := testUnlockSettings
+					settings.Enabled = tt.enabled
+					writeJSON(t, w, http.StatusOK, settings)
+					return
+				}
+				assert.NoError(t, json.NewDecoder(r.Body).Decode(&sent))
+				w.WriteHeader(http.StatusNoContent)
+			}, WithToken(Token{AccessToken: "token"}), WithUnlockKey(tt.unlockKey))
+
+			err := c.SetUnlockSecret(context.Background(), "unlock secret", "password")
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, sent.Salt, unlockSaltSize)
+			assert.Len(t, sent.UnlockKey, 32)
+			assert.Equal(t, tt.wantPassword, sent.Password)
+			if tt.wantCurrent {
+				assert.Equal(t, tt.unlockKey, sent.CurrentUnlockKey)
+			} else {
+				assert.Empty(t, sent.CurrentUnlockKey)
+			}
+
+			_, err = c.UnlockSettings(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, base64.StdEncoding.EncodeToString(sent.UnlockKey), header)
+		})
+	}
+}
+
+func TestClient_Unlock(t *testing.T) {
+	t.Parallel()
+
+	// headers collects the unlock key headers of the requests.
+	var headers []string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		headers = append(headers, r.Header.Get(headerVaultUnlock))
+		settings := testUnlockSettings
+		settings.Salt, settings.Enabled = []byte("0123456789abcdef"), true
+		writeJSON(t, w, http.StatusOK, settings)
+	}, WithToken(Token{AccessToken: "token"}))
+
+	require.NoError(t, c.Unlock(context.Background(), "unlock secret"))
+	_, err := c.UnlockSettings(context.Background())
+	require.NoError(t, err)
+
+	settings := testUnlockSettings
+	settings.Salt = []byte("0123456789abcdef")
+	key, err := DeriveUnlockKey("unlock secret", settings)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"", base64.StdEncoding.EncodeToString(key)}, headers)
+}
@@ -0,0 +1,106 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// signResponse builds the signature header of a response the way the server does.
+func signResponse(key ed25519.PrivateKey, r *http.Request, status int, ts time.Time, body []byte) string {
+	digest := sha256.Sum256(body)
+	t := strconv.FormatInt(ts.Unix(), 10)
+	canonical := strings.Join([]string{"v1", r.Method, r.URL.RequestURI(), strconv.Itoa(status), t,
+		hex.EncodeToString(digest[:])}, "\n")
+	return "t=" + t + ",key=abc,v1=" + hex.EncodeToString(ed25519.Sign(key, []byte(canonical)))
+}
+
+func TestClient_VerifiesResponses(t *testing.T) {
+	t.Parallel()
+
+	key := ed25519.NewKeyFromSeed(bytes.Repeat([]byte{0x09}, ed25519.SeedSize))
+	other := ed25519.NewKeyFromSeed(bytes.Repeat([]byte{0x0a}, ed25519.SeedSize))
+	body := []byte(`{"items":[]}`)
+
+	tests := []struct {
+		sign    func(r *http.Request) string
+		name    string
+		wantErr bool
+	}{
+		{
+			name: "valid signature",
+			sign: func(r *http.Request) string { return signResponse(key, r, http.StatusOK, time.Now(), body) },
+		},
+		{
+			name:    "missing signature",
+			sign:    func(*http.Request) string { return "" },
+			wantErr: true,
+		},
+		{
+			name:    "other key",
+			sign:    func(r *http.Request) string { return signResponse(other, r, http.StatusOK, time.Now(), body) },
+			wantErr: true,
+		},
+		{
+			name: "stale signature",
+			sign: func(r *http.Request) string {
+				return signResponse(key, r, http.StatusOK, time.Now().Add(-time.Hour), body)
+			},
+			wantErr: true,
+		},
+		{
+			name: "modified body",
+			sign: func(r *http.Request) string {
+				return signResponse(key, r, http.StatusOK, time.Now(), []byte(`{"items":null}`))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if header := tt.sign(r); header != "" {
+					w.Header().Set(headerResponseSignature, header)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write(body)
+			}, WithToken(Token{AccessToken: "token"}), WithResponseKey(key.Public().(ed25519.PublicKey)))
+
+			items, err := c.Manifest(context.Background())
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrInvalidResponseSignature)
+				return
+			}
+			require.NoError(t, err)
+			assert.Empty(t, items)
+		})
+	}
+}
+
+func TestClient_ResponseSigningKey(t *testing.T) {
+	t.Parallel()
+
+	public := ed25519.NewKeyFromSeed(bytes.Repeat([]byte{0x09}, ed25519.SeedSize)).Public().(ed25519.PublicKey)
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/.well-known/response-signing-key", r.URL.Path)
+		assert.Empty(t, r.Header.Get("Authorization"))
+		writeJSON(t, w, http.StatusOK, responseSigningKey{KeyID: "abc", Algorithm: "Ed25519", PublicKey: public})
+	}, WithToken(Token{AccessToken: "token"}))
+
+	key, err := c.ResponseSigningKey(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, public, key)
+}
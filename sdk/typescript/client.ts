@@ -0,0 +1,390 @@
+// Code generated by sdkgen from the OpenAPI specification. DO NOT EDIT.
+
+/** Version of the API the client was generated from. */
+export const SPEC_VERSION = "0.1.1";
+/** SHA-256 digest of the specification, as reported by GET /sdk. */
+export const SPEC_SHA256 = "eda1f39b6c5919200fddf585819d2cf1a0b3ed25b5c34cab8ded871cbbc24c12";
+
+export interface AboutBuildInfo {
+  /** Commit is the Git commit hash from which the application was built. */
+  commit?: string;
+  /** Date is the timestamp when the application was built. */
+  date?: string;
+  /** Version is the semantic version string of the application build. */
+  version?: string;
+}
+
+export interface AuthAccessToken {
+  /** AccessToken contains the JWT token for authenticating subsequent requests. */
+  access_token?: string;
+  /** ExpiresAt specifies when the token becomes invalid and must be refreshed. */
+  expires_at?: string;
+  /** TokenType specifies the token type, always "Bearer" for OAuth 2.0 compliance. */
+  token_type?: string;
+}
+
+export interface AuthLoginRequest {
+  /** Login contains the user's email address or username (required, must exist in system). */
+  login: string;
+  /** Password contains the user's plaintext password (required, verified against stored hash). */
+  password: string;
+}
+
+export interface AuthRegisterRequest {
+  /** Login contains the user's email address or username (required, unique across system). */
+  login: string;
+  /** Password contains the user's plaintext password (required, min 8 chars, will be hashed). */
+  password: string;
+}
+
+export interface AuthRegisterResponse {
+  /** ID contains the newly created user's unique identifier. */
+  id?: string;
+}
+
+export interface BankcardBankCard {
+  /** CardHolder contains the name printed on the card (may differ from account holder). */
+  card_holder?: string;
+  /** CardNumber contains the 13-19 digit payment card number (PCI DSS sensitive data). */
+  card_number?: string;
+  /** CVV contains the 3-4 digit card verification value (PCI DSS sensitive data). */
+  cvv?: string;
+  /** Description contains optional user-provided notes about this card. */
+  description?: string;
+  /** ExpiryMonth contains the two-digit expiration month (01-12). */
+  expiry_month?: string;
+  /** ExpiryYear contains the two-digit expiration year (YY format). */
+  expiry_year?: string;
+  /** ID contains the unique identifier for this bank card record. */
+  id?: string;
+  /** UpdatedAt contains the timestamp when this card was last modified. */
+  updated_at?: string;
+}
+
+export interface BankcardListResponse {
+  /** BankCards contains the list of all bank cards belonging to the user. */
+  bankcards?: BankcardBankCard[];
+}
+
+export interface BankcardPullResponse {
+  /** BankCard contains the requested bank card data. */
+  bankcard?: BankcardBankCard;
+}
+
+export interface BankcardPushRequest {
+  /** CardHolder contains the name as printed on the card (required, max 255 chars). */
+  card_holder: string;
+  /** CardNumber contains the 13-19 digit payment card number (required, PCI DSS sensitive). */
+  card_number: string;
+  /** CVV contains the 3-4 digit card verification value (required, PCI DSS sensitive). */
+  cvv: string;
+  /** Description contains optional user notes about this card (max 500 chars). */
+  description?: string;
+  /** ExpiryMonth contains the two-digit expiration month 01-12 (required). */
+  expiry_month: string;
+  /** ExpiryYear contains the two-digit expiration year YY format (required). */
+  expiry_year: string;
+}
+
+export interface BankcardPushResponse {
+  /** ID contains the UUID of the created or updated bank card. */
+  id?: string;
+}
+
+export interface CredentialCredential {
+  /** Description contains optional user notes about where this credential is used. */
+  description?: string;
+  /** ID contains the unique identifier for this credential record. */
+  id?: string;
+  /** Login contains the username, email, or account identifier (sensitive data). */
+  login?: string;
+  /** Password contains the plaintext password (highly sensitive, transmitted encrypted). */
+  password?: string;
+  /** UpdatedAt contains the timestamp when this credential was last modified. */
+  updated_at?: string;
+}
+
+export interface CredentialListResponse {
+  /** List of credentials */
+  credentials?: CredentialCredential[];
+}
+
+export interface CredentialPullResponse {
+  /** Credential data */
+  credential?: CredentialCredential;
+}
+
+export interface CredentialPushRequest {
+  /** Optional description */
+  description?: string;
+  /** Login username or email (required) */
+  login: string;
+  /** Password (required) */
+  password: string;
+}
+
+export interface CredentialPushResponse {
+  /** Created or updated credential ID */
+  id?: string;
+}
+
+export interface DatasyncSyncPayload {
+  /** BankCards contains the user's bank card data for synchronization. */
+  bankcards?: BankcardBankCard[];
+  /** Credentials contains the user's credential data for synchronization. */
+  credentials?: CredentialCredential[];
+  /** Files contains the user's file data for synchronization. */
+  files?: FiledataFileData[];
+  /** Notes contains the user's note data for synchronization. */
+  notes?: NoteNote[];
+}
+
+export interface FiledataFileData {
+  /** Data contains the file content bytes (omitted in list responses). */
+  data?: number[];
+  /** Description is the user-provided description of the file content. */
+  description?: string;
+  /** HashSum is the MD5 hash of the file content for integrity verification. */
+  hash_sum?: string;
+  /** ID is the unique file identifier. */
+  id?: string;
+  /** StorageKey is the filename or key used for storing the file. */
+  storage_key?: string;
+  /** UpdatedAt indicates when the file was last modified. */
+  updated_at?: string;
+  /** UserID identifies the file owner. */
+  user_id?: string;
+}
+
+export interface FiledataListResponse {
+  /** Files contains metadata for all files belonging to the user. */
+  files?: FiledataFileData[];
+}
+
+export interface FiledataPushResponse {
+  /** ID is the unique identifier assigned to the uploaded file. */
+  id?: string;
+}
+
+export interface NoteListResponse {
+  /** Notes contains all notes belonging to the authenticated user. */
+  notes?: NoteNote[];
+}
+
+export interface NoteNote {
+  /** Description contains optional metadata description (max 255 chars). */
+  description?: string;
+  /** ID contains the unique note identifier. */
+  id?: string;
+  /** Note contains the text content (required, max 1000 chars). */
+  note?: string;
+  /** UpdatedAt contains the last modification timestamp. */
+  updated_at?: string;
+}
+
+export interface NotePullResponse {
+  /** Note contains the requested note data. */
+  note?: NoteNote;
+}
+
+export interface NotePushRequest {
+  /** Description contains optional metadata description (max 255 chars). */
+  description?: string;
+  /** Note contains the text content (required, max 1000 chars). */
+  note: string;
+}
+
+export interface NotePushResponse {
+  /** ID contains the created or updated note identifier. */
+  id?: string;
+}
+
+export interface ResponseError {
+  /** Messages contains one or more error descriptions for the client. */
+  messages?: string[];
+}
+
+export class ApiError extends Error {
+  constructor(
+    readonly status: number,
+    readonly body: unknown,
+  ) {
+    super(`API request failed with status ${status}`);
+  }
+}
+
+export interface ClientOptions {
+  /** Base URL of the API including the base path, such as "https://vault.example.com/api". */
+  baseUrl: string;
+  /** Returns the access token sent to protected operations. */
+  token?: () => string | undefined | Promise<string | undefined>;
+  /** Fetch implementation; the global fetch by default. */
+  fetch?: typeof fetch;
+}
+
+type Query = Record<string, string | number | boolean | undefined>;
+
+export class Client {
+  constructor(private readonly options: ClientOptions) {}
+
+  private async request<T>(
+    method: string,
+    path: string,
+    init: { query?: Query; body?: unknown; form?: FormData; secure: boolean; blob?: boolean },
+  ): Promise<T> {
+    const url = new URL(this.options.baseUrl.replace(/\/$/, "") + path);
+    for (const [key, value] of Object.entries(init.query ?? {})) {
+      if (value !== undefined) url.searchParams.set(key, String(value));
+    }
+    const headers: Record<string, string> = { Accept: "application/json" };
+    let body: BodyInit | undefined = init.form;
+    if (init.body !== undefined) {
+      headers["Content-Type"] = "application/json";
+      body = JSON.stringify(init.body);
+    }
+    if (init.secure && this.options.token) {
+      const token = await this.options.token();
+      if (token) headers.Authorization = `Bearer ${token}`;
+    }
+    const res = await (this.options.fetch ?? fetch)(url, { method, headers, body });
+    if (!res.ok) {
+      const text = await res.text();
+      let detail: unknown = text;
+      try {
+        detail = JSON.parse(text);
+      } catch {
+        // Keep the raw text of non-JSON error bodies.
+      }
+      throw new ApiError(res.status, detail);
+    }
+    if (res.status === 204) return undefined as T;
+    if (init.blob) return (await res.blob()) as T;
+    return (await res.json()) as T;
+  }
+
+  /** Get application build information */
+  getAbout(): Promise<AboutBuildInfo> {
+    return this.request("GET", `/about`, { secure: false });
+  }
+
+  /** Authenticate user */
+  postAuthLogin(body: AuthLoginRequest): Promise<AuthAccessToken> {
+    return this.request("POST", `/auth/login`, { secure: false, body });
+  }
+
+  /** Register a new user */
+  postAuthRegister(body: AuthRegisterRequest): Promise<AuthRegisterResponse> {
+    return this.request("POST", `/auth/register`, { secure: false, body });
+  }
+
+  /** Health check */
+  getHealth(): Promise<unknown> {
+    return this.request("GET", `/health`, { secure: false });
+  }
+
+  /** List all bank cards */
+  getItemsBankcards(): Promise<BankcardListResponse | undefined> {
+    return this.request("GET", `/items/bankcards`, { secure: true });
+  }
+
+  /** Create or update bank card */
+  postItemsBankcards(body: BankcardPushRequest): Promise<BankcardPushResponse> {
+    return this.request("POST", `/items/bankcards`, { secure: true, body });
+  }
+
+  /** Get bank card by ID */
+  getItemsBankcardsById(id: string): Promise<BankcardPullResponse> {
+    return this.request("GET", `/items/bankcards/${encodeURIComponent(id)}`, { secure: true });
+  }
+
+  /** Create or update bank card */
+  putItemsBankcardsById(id: string, body: BankcardPushRequest): Promise<BankcardPushResponse> {
+    return this.request("PUT", `/items/bankcards/${encodeURIComponent(id)}`, { secure: true, body });
+  }
+
+  /** List all credentials */
+  getItemsCredentials(): Promise<CredentialListResponse | undefined> {
+    return this.request("GET", `/items/credentials`, { secure: true });
+  }
+
+  /** Create or update credential */
+  postItemsCredentials(body: CredentialPushRequest): Promise<CredentialPushResponse> {
+    return this.request("POST", `/items/credentials`, { secure: true, body });
+  }
+
+  /** Get credential by ID */
+  getItemsCredentialsById(id: string): Promise<CredentialPullResponse> {
+    return this.request("GET", `/items/credentials/${encodeURIComponent(id)}`, { secure: true });
+  }
+
+  /** Create or update credential */
+  putItemsCredentialsById(id: string, body: CredentialPushRequest): Promise<CredentialPushResponse> {
+    return this.request("PUT", `/items/credentials/${encodeURIComponent(id)}`, { secure: true, body });
+  }
+
+  /** List all files */
+  getItemsFiledata(): Promise<FiledataListResponse | undefined> {
+    return this.request("GET", `/items/filedata`, { secure: true });
+  }
+
+  /**
+   * Upload or update file
+   *
+   * Form field "file" (Blob): File to upload
+   *
+   * Form field "storage_key" (string): Custom storage key (filename)
+   *
+   * Form field "description" (string): File description
+   */
+  postItemsFiledata(form: FormData): Promise<FiledataPushResponse> {
+    return this.request("POST", `/items/filedata`, { secure: true, form });
+  }
+
+  /** Get file by ID */
+  getItemsFiledataById(id: string): Promise<Blob> {
+    return this.request("GET", `/items/filedata/${encodeURIComponent(id)}`, { secure: true, blob: true });
+  }
+
+  /**
+   * Upload or update file
+   *
+   * Form field "file" (Blob): File to upload
+   *
+   * Form field "storage_key" (string): Custom storage key (filename)
+   *
+   * Form field "description" (string): File description
+   */
+  putItemsFiledataById(id: string, form: FormData): Promise<FiledataPushResponse> {
+    return this.request("PUT", `/items/filedata/${encodeURIComponent(id)}`, { secure: true, form });
+  }
+
+  /** List all notes */
+  getItemsNotes(): Promise<NoteListResponse | undefined> {
+    return this.request("GET", `/items/notes`, { secure: true });
+  }
+
+  /** Create or update note */
+  postItemsNotes(body: NotePushRequest): Promise<NotePushResponse> {
+    return this.request("POST", `/items/notes`, { secure: true, body });
+  }
+
+  /** Get note by ID */
+  getItemsNotesById(id: string): Promise<NotePullResponse> {
+    return this.request("GET", `/items/notes/${encodeURIComponent(id)}`, { secure: true });
+  }
+
+  /** Create or update note */
+  putItemsNotesById(id: string, body: NotePushRequest): Promise<NotePushResponse> {
+    return this.request("PUT", `/items/notes/${encodeURIComponent(id)}`, { secure: true, body });
+  }
+
+  /** Pull all user data */
+  getItemsSync(): Promise<DatasyncSyncPayload | undefined> {
+    return this.request("GET", `/items/sync`, { secure: true });
+  }
+
+  /** Push user data for synchronization */
+  postItemsSync(body: DatasyncSyncPayload): Promise<undefined> {
+    return this.request("POST", `/items/sync`, { secure: true, body });
+  }
+}